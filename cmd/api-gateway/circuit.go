@@ -0,0 +1,80 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/kilang-desa-murni/crm/pkg/config"
+	"github.com/kilang-desa-murni/crm/pkg/logger"
+	"github.com/kilang-desa-murni/crm/pkg/resilience"
+)
+
+// errUpstreamFailure marks a 5xx response as a failure for the circuit
+// breaker; the response itself is still returned to the client.
+var errUpstreamFailure = errors.New("upstream server error")
+
+// newBreakerRegistry creates the per-service circuit breakers of the proxies.
+func newBreakerRegistry(cfg config.CircuitBreakerConfig, log *logger.Logger) *resilience.CircuitBreakerRegistry {
+	return resilience.NewCircuitBreakerRegistry(breakerConfig(cfg, log))
+}
+
+// breakerConfig turns the configured thresholds into a circuit breaker
+// configuration. A breaker opens after FailureThreshold consecutive failures.
+func breakerConfig(cfg config.CircuitBreakerConfig, log *logger.Logger) resilience.CircuitBreakerConfig {
+	threshold := cfg.FailureThreshold
+	return resilience.CircuitBreakerConfig{
+		MaxRequests: cfg.MaxRequests,
+		Interval:    cfg.Interval,
+		Timeout:     cfg.Timeout,
+		ReadyToTrip: func(counts resilience.Counts) bool {
+			return counts.ConsecutiveFailures >= threshold
+		},
+		// Clients going away say nothing about the service
+		IsSuccessful: func(err error) bool {
+			return err == nil || errors.Is(err, context.Canceled)
+		},
+		OnStateChange: func(name string, from, to resilience.State) {
+			log.Warn().
+				Str("service", name).
+				Str("from", from.String()).
+				Str("to", to.String()).
+				Msg("Circuit breaker state changed")
+		},
+	}
+}
+
+// breakerTransport stops forwarding to a service whose requests keep
+// failing, giving it time to recover. It wraps the retry transport, so a
+// request counts once however many attempts it took.
+type breakerTransport struct {
+	service  string
+	breakers *resilience.CircuitBreakerRegistry
+	base     http.RoundTripper
+}
+
+func newBreakerTransport(service string, breakers *resilience.CircuitBreakerRegistry, base http.RoundTripper) *breakerTransport {
+	return &breakerTransport{service: service, breakers: breakers, base: base}
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *breakerTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	var resp *http.Response
+	err := t.breakers.Get(t.service).Execute(func() error {
+		var err error
+		resp, err = t.base.RoundTrip(req)
+		if err == nil && resp.StatusCode >= http.StatusInternalServerError {
+			return errUpstreamFailure
+		}
+		return err
+	})
+	if errors.Is(err, errUpstreamFailure) {
+		return resp, nil
+	}
+	return resp, err
+}
+
+// circuitOpen reports whether err is a request refused by a circuit breaker.
+func circuitOpen(err error) bool {
+	return errors.Is(err, resilience.ErrCircuitOpen) || errors.Is(err, resilience.ErrTooManyRequests)
+}
@@ -0,0 +1,117 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/kilang-desa-murni/crm/pkg/config"
+	"github.com/kilang-desa-murni/crm/pkg/logger"
+)
+
+// tenantOriginsTimeout bounds a single tenant origins request to IAM.
+const tenantOriginsTimeout = 5 * time.Second
+
+// tenantOrigins holds the browser origins tenants allow for their users, as
+// listed by the IAM service. It is refreshed in the background; until the
+// first successful load no tenant origins are allowed.
+type tenantOrigins struct {
+	url    string
+	client *http.Client
+	log    *logger.Logger
+
+	mu      sync.RWMutex
+	origins map[string][]string
+}
+
+func newTenantOrigins(url string, transport http.RoundTripper, log *logger.Logger) *tenantOrigins {
+	return &tenantOrigins{
+		url:    url,
+		client: &http.Client{Timeout: tenantOriginsTimeout, Transport: transport},
+		log:    log,
+	}
+}
+
+// TenantsForOrigin returns the tenants allowing origin, matching subdomain
+// wildcards.
+func (t *tenantOrigins) TenantsForOrigin(origin string) []string {
+	if origin == "" {
+		return nil
+	}
+
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	var tenants []string
+	for pattern, ids := range t.origins {
+		if pattern != "*" && config.MatchOrigin([]string{pattern}, origin) {
+			tenants = append(tenants, ids...)
+		}
+	}
+	return tenants
+}
+
+// Start loads the tenant origins and reloads them every interval until ctx
+// is done. Failed reloads keep the last origins loaded.
+func (t *tenantOrigins) Start(ctx context.Context, interval time.Duration) {
+	if err := t.load(ctx); err != nil {
+		t.log.Warn().Err(err).Msg("Failed to load tenant CORS origins")
+	}
+	if interval <= 0 {
+		interval = time.Minute
+	}
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if err := t.load(ctx); err != nil {
+					t.log.Warn().Err(err).Msg("Failed to reload tenant CORS origins")
+				}
+			}
+		}
+	}()
+}
+
+func (t *tenantOrigins) load(ctx context.Context) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, t.url, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := t.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("tenant origins request returned %d", resp.StatusCode)
+	}
+
+	var body struct {
+		Data struct {
+			Origins map[string][]string `json:"origins"`
+		} `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return fmt.Errorf("decode tenant origins: %w", err)
+	}
+
+	origins := make(map[string][]string, len(body.Data.Origins))
+	for origin, tenants := range body.Data.Origins {
+		origins[strings.ToLower(origin)] = tenants
+	}
+
+	t.mu.Lock()
+	t.origins = origins
+	t.mu.Unlock()
+	return nil
+}
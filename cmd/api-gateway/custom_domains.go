@@ -0,0 +1,221 @@
+package main
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/crypto/acme"
+	"golang.org/x/crypto/acme/autocert"
+
+	apperrors "github.com/kilang-desa-murni/crm/pkg/errors"
+	"github.com/kilang-desa-murni/crm/pkg/logger"
+	"github.com/kilang-desa-murni/crm/pkg/middleware"
+	"github.com/kilang-desa-murni/crm/pkg/response"
+)
+
+// tenantDomainsTimeout bounds a single tenant domains request to IAM.
+const tenantDomainsTimeout = 5 * time.Second
+
+// tenantDomain is a white-labeled tenant reached on its own domain.
+type tenantDomain struct {
+	TenantID     string          `json:"tenant_id"`
+	Name         string          `json:"name"`
+	CustomDomain string          `json:"custom_domain"`
+	Branding     *tenantBranding `json:"branding,omitempty"`
+}
+
+// tenantBranding is how a white-labeled tenant presents the web app.
+type tenantBranding struct {
+	LogoURL        string `json:"logo_url,omitempty"`
+	PrimaryColor   string `json:"primary_color,omitempty"`
+	SecondaryColor string `json:"secondary_color,omitempty"`
+}
+
+// hostTenantKey is the request context key for the tenant of a custom domain.
+type hostTenantKey struct{}
+
+// tenantDomains holds the custom domains of white-labeled tenants, as listed
+// by the IAM service. It is refreshed in the background; until the first
+// successful load no custom domain is served.
+type tenantDomains struct {
+	url    string
+	client *http.Client
+	log    *logger.Logger
+
+	mu      sync.RWMutex
+	domains map[string]*tenantDomain
+}
+
+func newTenantDomains(url string, transport http.RoundTripper, log *logger.Logger) *tenantDomains {
+	return &tenantDomains{
+		url:    url,
+		client: &http.Client{Timeout: tenantDomainsTimeout, Transport: transport},
+		log:    log,
+	}
+}
+
+// Lookup returns the tenant reached on host, which may carry a port, or nil
+// when host is not a tenant's custom domain.
+func (t *tenantDomains) Lookup(host string) *tenantDomain {
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+	host = strings.ToLower(strings.TrimSuffix(host, "."))
+
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	return t.domains[host]
+}
+
+// HostPolicy allows certificates only for the custom domains of active
+// tenants, so clients cannot make the gateway request certificates for
+// arbitrary names.
+func (t *tenantDomains) HostPolicy(ctx context.Context, host string) error {
+	if t.Lookup(host) == nil {
+		return fmt.Errorf("%s is not a tenant custom domain", host)
+	}
+	return nil
+}
+
+// Middleware resolves requests to a custom domain to its tenant: the tenant
+// replaces any X-Tenant-ID header sent by the client, so the backends see
+// the tenant the domain belongs to.
+func (t *tenantDomains) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		tenant := t.Lookup(r.Host)
+		if tenant == nil {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		r.Header.Set("X-Tenant-ID", tenant.TenantID)
+		ctx := context.WithValue(r.Context(), hostTenantKey{}, tenant)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// RequireHostTenant refuses authenticated requests made on a tenant's custom
+// domain by users of other tenants. It runs after authentication.
+func RequireHostTenant(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		tenant, ok := r.Context().Value(hostTenantKey{}).(*tenantDomain)
+		if !ok {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		if tenantID := middleware.TenantIDFromContext(r.Context()); tenantID != "" && tenantID != tenant.TenantID {
+			response.Error(w, apperrors.ErrForbidden("Domain not allowed for this tenant"))
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// Start loads the tenant domains and reloads them every interval until ctx
+// is done. Failed reloads keep the last domains loaded.
+func (t *tenantDomains) Start(ctx context.Context, interval time.Duration) {
+	if err := t.load(ctx); err != nil {
+		t.log.Warn().Err(err).Msg("Failed to load tenant custom domains")
+	}
+	if interval <= 0 {
+		interval = time.Minute
+	}
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if err := t.load(ctx); err != nil {
+					t.log.Warn().Err(err).Msg("Failed to reload tenant custom domains")
+				}
+			}
+		}
+	}()
+}
+
+func (t *tenantDomains) load(ctx context.Context) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, t.url, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := t.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("tenant domains request returned %d", resp.StatusCode)
+	}
+
+	var body struct {
+		Data struct {
+			Domains map[string]*tenantDomain `json:"domains"`
+		} `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return fmt.Errorf("decode tenant domains: %w", err)
+	}
+
+	domains := make(map[string]*tenantDomain, len(body.Data.Domains))
+	for host, tenant := range body.Data.Domains {
+		if tenant == nil || tenant.TenantID == "" {
+			continue
+		}
+		domains[strings.ToLower(host)] = tenant
+	}
+
+	t.mu.Lock()
+	t.domains = domains
+	t.mu.Unlock()
+	return nil
+}
+
+// brandingHandler serves the tenant and branding of the custom domain the
+// web app is loaded from, before anyone signs in.
+func brandingHandler(domains *tenantDomains) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		tenant := domains.Lookup(r.Host)
+		if tenant == nil {
+			response.Error(w, apperrors.ErrNotFound("custom domain"))
+			return
+		}
+		response.OK(w, tenant)
+	}
+}
+
+// newCertManager creates the ACME certificate manager of the tenants' custom
+// domains. Certificates are cached in cacheDir, which should be shared by
+// the gateway replicas; directoryURL defaults to Let's Encrypt.
+func newCertManager(domains *tenantDomains, cacheDir, email, directoryURL string) *autocert.Manager {
+	manager := &autocert.Manager{
+		Prompt:     autocert.AcceptTOS,
+		Cache:      autocert.DirCache(cacheDir),
+		HostPolicy: domains.HostPolicy,
+		Email:      email,
+	}
+	if directoryURL != "" {
+		manager.Client = &acme.Client{DirectoryURL: directoryURL}
+	}
+	return manager
+}
+
+// certTLSConfig returns the TLS configuration serving the certificates of
+// manager, answering TLS-ALPN-01 challenges.
+func certTLSConfig(manager *autocert.Manager) *tls.Config {
+	config := manager.TLSConfig()
+	config.MinVersion = tls.VersionTLS12
+	return config
+}
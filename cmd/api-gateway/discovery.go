@@ -0,0 +1,334 @@
+package main
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Discovery types supported in the routing config.
+const (
+	DiscoveryStatic     = "static"
+	DiscoveryDNSSRV     = "dns_srv"
+	DiscoveryConsul     = "consul"
+	DiscoveryKubernetes = "kubernetes"
+)
+
+// In-cluster service account files used for Kubernetes discovery.
+const (
+	kubernetesTokenFile     = "/var/run/secrets/kubernetes.io/serviceaccount/token"
+	kubernetesCAFile        = "/var/run/secrets/kubernetes.io/serviceaccount/ca.crt"
+	kubernetesNamespaceFile = "/var/run/secrets/kubernetes.io/serviceaccount/namespace"
+)
+
+// discoveryHTTPTimeout bounds a single Consul or Kubernetes API call.
+const discoveryHTTPTimeout = 5 * time.Second
+
+// ServiceEndpoint is a backend instance a service can be routed to.
+type ServiceEndpoint struct {
+	URL    string `json:"url"`
+	Weight int    `json:"weight"`
+}
+
+// EndpointResolver discovers the current instances of a service.
+type EndpointResolver interface {
+	Resolve(ctx context.Context) ([]ServiceEndpoint, error)
+}
+
+// newEndpointResolver creates the resolver for a service's discovery config.
+func newEndpointResolver(cfg ServiceDiscoveryConfig) (EndpointResolver, error) {
+	scheme := cfg.Scheme
+	if scheme == "" {
+		scheme = "http"
+	}
+
+	switch cfg.Discovery {
+	case "", DiscoveryStatic:
+		if len(cfg.Endpoints) == 0 {
+			return nil, fmt.Errorf("static discovery requires at least one endpoint")
+		}
+		for _, ep := range cfg.Endpoints {
+			if _, err := url.ParseRequestURI(ep.URL); err != nil {
+				return nil, fmt.Errorf("invalid endpoint url %q: %w", ep.URL, err)
+			}
+		}
+		return &staticResolver{endpoints: cfg.Endpoints}, nil
+
+	case DiscoveryDNSSRV:
+		if cfg.SRV == "" {
+			return nil, fmt.Errorf("dns_srv discovery requires srv")
+		}
+		return &dnsSRVResolver{name: cfg.SRV, scheme: scheme, resolver: net.DefaultResolver}, nil
+
+	case DiscoveryConsul:
+		if cfg.Consul.Service == "" {
+			return nil, fmt.Errorf("consul discovery requires consul.service")
+		}
+		address := cfg.Consul.Address
+		if address == "" {
+			address = "http://127.0.0.1:8500"
+		}
+		return &consulResolver{
+			config:  cfg.Consul,
+			address: strings.TrimSuffix(address, "/"),
+			scheme:  scheme,
+			client:  &http.Client{Timeout: discoveryHTTPTimeout},
+		}, nil
+
+	case DiscoveryKubernetes:
+		return newKubernetesResolver(cfg.Kubernetes, scheme)
+	}
+
+	return nil, fmt.Errorf("unknown discovery type %q", cfg.Discovery)
+}
+
+// ============================================================================
+// Static
+// ============================================================================
+
+// staticResolver returns a fixed list of endpoints.
+type staticResolver struct {
+	endpoints []ServiceEndpoint
+}
+
+func (r *staticResolver) Resolve(ctx context.Context) ([]ServiceEndpoint, error) {
+	return r.endpoints, nil
+}
+
+// ============================================================================
+// DNS SRV
+// ============================================================================
+
+// dnsSRVResolver discovers endpoints from DNS SRV records. Only the records
+// with the lowest priority are used, weighted by their SRV weight.
+type dnsSRVResolver struct {
+	name     string
+	scheme   string
+	resolver srvLookuper
+}
+
+// srvLookuper looks up SRV records; *net.Resolver implements it.
+type srvLookuper interface {
+	LookupSRV(ctx context.Context, service, proto, name string) (string, []*net.SRV, error)
+}
+
+func (r *dnsSRVResolver) Resolve(ctx context.Context) ([]ServiceEndpoint, error) {
+	_, records, err := r.resolver.LookupSRV(ctx, "", "", r.name)
+	if err != nil {
+		return nil, fmt.Errorf("srv lookup %s: %w", r.name, err)
+	}
+	if len(records) == 0 {
+		return nil, nil
+	}
+
+	// LookupSRV sorts records by priority
+	priority := records[0].Priority
+	var endpoints []ServiceEndpoint
+	for _, record := range records {
+		if record.Priority != priority {
+			break
+		}
+		host := strings.TrimSuffix(record.Target, ".")
+		endpoints = append(endpoints, ServiceEndpoint{
+			URL:    fmt.Sprintf("%s://%s", r.scheme, net.JoinHostPort(host, strconv.Itoa(int(record.Port)))),
+			Weight: int(record.Weight),
+		})
+	}
+	return endpoints, nil
+}
+
+// ============================================================================
+// Consul
+// ============================================================================
+
+// consulResolver discovers passing instances from the Consul health API.
+type consulResolver struct {
+	config  ConsulDiscoveryConfig
+	address string
+	scheme  string
+	client  *http.Client
+}
+
+// consulServiceEntry is the subset of a /v1/health/service entry used for routing.
+type consulServiceEntry struct {
+	Node struct {
+		Address string `json:"Address"`
+	} `json:"Node"`
+	Service struct {
+		Address string `json:"Address"`
+		Port    int    `json:"Port"`
+		Weights struct {
+			Passing int `json:"Passing"`
+		} `json:"Weights"`
+	} `json:"Service"`
+}
+
+func (r *consulResolver) Resolve(ctx context.Context) ([]ServiceEndpoint, error) {
+	query := url.Values{"passing": []string{"true"}}
+	if r.config.Tag != "" {
+		query.Set("tag", r.config.Tag)
+	}
+	if r.config.Datacenter != "" {
+		query.Set("dc", r.config.Datacenter)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet,
+		fmt.Sprintf("%s/v1/health/service/%s?%s", r.address, url.PathEscape(r.config.Service), query.Encode()), nil)
+	if err != nil {
+		return nil, err
+	}
+	if r.config.Token != "" {
+		req.Header.Set("X-Consul-Token", r.config.Token)
+	}
+
+	var entries []consulServiceEntry
+	if err := doDiscoveryRequest(r.client, req, &entries); err != nil {
+		return nil, fmt.Errorf("consul service %s: %w", r.config.Service, err)
+	}
+
+	endpoints := make([]ServiceEndpoint, 0, len(entries))
+	for _, entry := range entries {
+		host := entry.Service.Address
+		if host == "" {
+			host = entry.Node.Address
+		}
+		endpoints = append(endpoints, ServiceEndpoint{
+			URL:    fmt.Sprintf("%s://%s", r.scheme, net.JoinHostPort(host, strconv.Itoa(entry.Service.Port))),
+			Weight: entry.Service.Weights.Passing,
+		})
+	}
+	return endpoints, nil
+}
+
+// ============================================================================
+// Kubernetes
+// ============================================================================
+
+// kubernetesResolver discovers ready pod addresses from a Service's Endpoints
+// object, using the in-cluster service account.
+type kubernetesResolver struct {
+	config    KubernetesDiscoveryConfig
+	apiServer string
+	namespace string
+	scheme    string
+	tokenFile string
+	client    *http.Client
+}
+
+// kubernetesEndpoints is the subset of a core/v1 Endpoints object used for routing.
+type kubernetesEndpoints struct {
+	Subsets []struct {
+		Addresses []struct {
+			IP string `json:"ip"`
+		} `json:"addresses"`
+		Ports []struct {
+			Name string `json:"name"`
+			Port int    `json:"port"`
+		} `json:"ports"`
+	} `json:"subsets"`
+}
+
+func newKubernetesResolver(cfg KubernetesDiscoveryConfig, scheme string) (*kubernetesResolver, error) {
+	if cfg.Service == "" {
+		return nil, fmt.Errorf("kubernetes discovery requires kubernetes.service")
+	}
+
+	apiServer := cfg.APIServer
+	if apiServer == "" {
+		host, port := os.Getenv("KUBERNETES_SERVICE_HOST"), os.Getenv("KUBERNETES_SERVICE_PORT")
+		if host == "" || port == "" {
+			return nil, fmt.Errorf("kubernetes discovery requires kubernetes.api_server outside a cluster")
+		}
+		apiServer = "https://" + net.JoinHostPort(host, port)
+	}
+
+	namespace := cfg.Namespace
+	if namespace == "" {
+		data, err := os.ReadFile(kubernetesNamespaceFile)
+		if err != nil {
+			return nil, fmt.Errorf("kubernetes discovery requires kubernetes.namespace: %w", err)
+		}
+		namespace = strings.TrimSpace(string(data))
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if ca, err := os.ReadFile(kubernetesCAFile); err == nil {
+		pool := x509.NewCertPool()
+		pool.AppendCertsFromPEM(ca)
+		transport.TLSClientConfig = &tls.Config{RootCAs: pool, MinVersion: tls.VersionTLS12}
+	}
+
+	return &kubernetesResolver{
+		config:    cfg,
+		apiServer: strings.TrimSuffix(apiServer, "/"),
+		namespace: namespace,
+		scheme:    scheme,
+		tokenFile: kubernetesTokenFile,
+		client:    &http.Client{Timeout: discoveryHTTPTimeout, Transport: transport},
+	}, nil
+}
+
+func (r *kubernetesResolver) Resolve(ctx context.Context) ([]ServiceEndpoint, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet,
+		fmt.Sprintf("%s/api/v1/namespaces/%s/endpoints/%s", r.apiServer, url.PathEscape(r.namespace), url.PathEscape(r.config.Service)), nil)
+	if err != nil {
+		return nil, err
+	}
+	// The token is re-read on every call because projected tokens are rotated
+	if token, err := os.ReadFile(r.tokenFile); err == nil {
+		req.Header.Set("Authorization", "Bearer "+strings.TrimSpace(string(token)))
+	}
+
+	var endpoints kubernetesEndpoints
+	if err := doDiscoveryRequest(r.client, req, &endpoints); err != nil {
+		return nil, fmt.Errorf("kubernetes endpoints %s/%s: %w", r.namespace, r.config.Service, err)
+	}
+
+	var result []ServiceEndpoint
+	for _, subset := range endpoints.Subsets {
+		port := 0
+		for _, p := range subset.Ports {
+			if r.config.PortName == "" || p.Name == r.config.PortName {
+				port = p.Port
+				break
+			}
+		}
+		if port == 0 {
+			continue
+		}
+		for _, address := range subset.Addresses {
+			result = append(result, ServiceEndpoint{
+				URL:    fmt.Sprintf("%s://%s", r.scheme, net.JoinHostPort(address.IP, strconv.Itoa(port))),
+				Weight: 1,
+			})
+		}
+	}
+	return result, nil
+}
+
+// doDiscoveryRequest performs a discovery API call and decodes its JSON response.
+func doDiscoveryRequest(client *http.Client, req *http.Request, out interface{}) error {
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("unexpected status %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
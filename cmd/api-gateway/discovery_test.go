@@ -0,0 +1,188 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// fakeSRVLookuper returns fixed SRV records, sorted by priority as
+// net.Resolver returns them.
+type fakeSRVLookuper struct {
+	records []*net.SRV
+	err     error
+}
+
+func (f *fakeSRVLookuper) LookupSRV(ctx context.Context, service, proto, name string) (string, []*net.SRV, error) {
+	return name, f.records, f.err
+}
+
+func TestDNSSRVResolver_Resolve(t *testing.T) {
+	tests := []struct {
+		name    string
+		lookup  *fakeSRVLookuper
+		want    []ServiceEndpoint
+		wantErr bool
+	}{
+		{
+			name: "lowest priority only",
+			lookup: &fakeSRVLookuper{records: []*net.SRV{
+				{Target: "sales-1.svc.local.", Port: 8082, Priority: 10, Weight: 3},
+				{Target: "sales-2.svc.local.", Port: 8082, Priority: 10, Weight: 1},
+				{Target: "sales-dr.svc.local.", Port: 8082, Priority: 20, Weight: 1},
+			}},
+			want: []ServiceEndpoint{
+				{URL: "https://sales-1.svc.local:8082", Weight: 3},
+				{URL: "https://sales-2.svc.local:8082", Weight: 1},
+			},
+		},
+		{
+			name:   "no records",
+			lookup: &fakeSRVLookuper{},
+		},
+		{
+			name:    "lookup failure",
+			lookup:  &fakeSRVLookuper{err: errors.New("no such host")},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resolver := &dnsSRVResolver{name: "_http._tcp.sales.svc.local", scheme: "https", resolver: tt.lookup}
+			got, err := resolver.Resolve(context.Background())
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Resolve() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Resolve() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestConsulResolver_Resolve(t *testing.T) {
+	var gotPath, gotQuery, gotToken string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath, gotQuery, gotToken = r.URL.Path, r.URL.RawQuery, r.Header.Get("X-Consul-Token")
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`[
+			{"Node":{"Address":"10.0.0.1"},"Service":{"Address":"10.1.0.1","Port":8082,"Weights":{"Passing":5}}},
+			{"Node":{"Address":"10.0.0.2"},"Service":{"Address":"","Port":8083,"Weights":{"Passing":1}}}
+		]`))
+	}))
+	defer server.Close()
+
+	resolver, err := newEndpointResolver(ServiceDiscoveryConfig{
+		Discovery: DiscoveryConsul,
+		Consul:    ConsulDiscoveryConfig{Address: server.URL + "/", Service: "sales", Tag: "v2", Datacenter: "kl1", Token: "secret"},
+	})
+	if err != nil {
+		t.Fatalf("newEndpointResolver() error = %v", err)
+	}
+
+	got, err := resolver.Resolve(context.Background())
+	if err != nil {
+		t.Fatalf("Resolve() error = %v", err)
+	}
+	want := []ServiceEndpoint{
+		{URL: "http://10.1.0.1:8082", Weight: 5},
+		{URL: "http://10.0.0.2:8083", Weight: 1},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Resolve() = %v, want %v", got, want)
+	}
+	if gotPath != "/v1/health/service/sales" || gotQuery != "dc=kl1&passing=true&tag=v2" || gotToken != "secret" {
+		t.Errorf("unexpected request %s?%s with token %q", gotPath, gotQuery, gotToken)
+	}
+}
+
+func TestConsulResolver_ErrorStatus(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "ACL not found", http.StatusForbidden)
+	}))
+	defer server.Close()
+
+	resolver, err := newEndpointResolver(ServiceDiscoveryConfig{
+		Discovery: DiscoveryConsul,
+		Consul:    ConsulDiscoveryConfig{Address: server.URL, Service: "sales"},
+	})
+	if err != nil {
+		t.Fatalf("newEndpointResolver() error = %v", err)
+	}
+	if _, err := resolver.Resolve(context.Background()); err == nil {
+		t.Error("expected an error for a 403 from Consul")
+	}
+}
+
+func TestKubernetesResolver_Resolve(t *testing.T) {
+	var gotPath, gotAuth string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath, gotAuth = r.URL.Path, r.Header.Get("Authorization")
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"subsets":[
+			{"addresses":[{"ip":"10.2.0.1"},{"ip":"10.2.0.2"}],"ports":[{"name":"metrics","port":9090},{"name":"http","port":8082}]},
+			{"addresses":[{"ip":"10.2.0.3"}],"ports":[{"name":"metrics","port":9090}]}
+		]}`))
+	}))
+	defer server.Close()
+
+	tokenFile := filepath.Join(t.TempDir(), "token")
+	if err := os.WriteFile(tokenFile, []byte("sa-token\n"), 0o600); err != nil {
+		t.Fatalf("failed to write token: %v", err)
+	}
+
+	resolver, err := newKubernetesResolver(KubernetesDiscoveryConfig{
+		APIServer: server.URL,
+		Namespace: "crm",
+		Service:   "sales-service",
+		PortName:  "http",
+	}, "http")
+	if err != nil {
+		t.Fatalf("newKubernetesResolver() error = %v", err)
+	}
+	resolver.tokenFile = tokenFile
+
+	got, err := resolver.Resolve(context.Background())
+	if err != nil {
+		t.Fatalf("Resolve() error = %v", err)
+	}
+	want := []ServiceEndpoint{
+		{URL: "http://10.2.0.1:8082", Weight: 1},
+		{URL: "http://10.2.0.2:8082", Weight: 1},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Resolve() = %v, want %v", got, want)
+	}
+	if gotPath != "/api/v1/namespaces/crm/endpoints/sales-service" || gotAuth != "Bearer sa-token" {
+		t.Errorf("unexpected request %s with %q", gotPath, gotAuth)
+	}
+}
+
+func TestNewEndpointResolver_Invalid(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  ServiceDiscoveryConfig
+	}{
+		{"static without endpoints", ServiceDiscoveryConfig{Discovery: DiscoveryStatic}},
+		{"static with a bad url", ServiceDiscoveryConfig{Endpoints: []ServiceEndpoint{{URL: "sales-service"}}}},
+		{"dns_srv without a name", ServiceDiscoveryConfig{Discovery: DiscoveryDNSSRV}},
+		{"consul without a service", ServiceDiscoveryConfig{Discovery: DiscoveryConsul}},
+		{"kubernetes without a service", ServiceDiscoveryConfig{Discovery: DiscoveryKubernetes}},
+		{"unknown type", ServiceDiscoveryConfig{Discovery: "zookeeper"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := newEndpointResolver(tt.cfg); err == nil {
+				t.Error("expected an error")
+			}
+		})
+	}
+}
@@ -0,0 +1,682 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/kilang-desa-murni/crm/pkg/auth"
+	"github.com/kilang-desa-murni/crm/pkg/graphql"
+	"github.com/kilang-desa-murni/crm/pkg/identity"
+	"github.com/kilang-desa-murni/crm/pkg/logger"
+	"github.com/kilang-desa-murni/crm/pkg/middleware"
+)
+
+const (
+	// defaultGraphQLMaxDepth and defaultGraphQLMaxComplexity are used when
+	// GATEWAY_GRAPHQL_MAX_DEPTH and GATEWAY_GRAPHQL_MAX_COMPLEXITY are not set.
+	defaultGraphQLMaxDepth      = 8
+	defaultGraphQLMaxComplexity = 1000
+
+	// graphQLBackendTimeout bounds each backend call made by a resolver.
+	graphQLBackendTimeout = 10 * time.Second
+
+	// graphQLDefaultPageSize and graphQLMaxPageSize bound list fields.
+	graphQLDefaultPageSize = 20
+	graphQLMaxPageSize     = 100
+
+	// graphQLMaxBatch bounds the IDs a loader fetches concurrently.
+	graphQLMaxBatch = 25
+
+	// graphQLMaxBatchGet bounds the IDs a loader sends to a batch get
+	// endpoint, the most the services accept in one request.
+	graphQLMaxBatchGet = 500
+)
+
+// ============================================================================
+// Backend Client
+// ============================================================================
+
+// graphQLBackend fetches resources from the backend services for the GraphQL
+// resolvers. Calls go through the routing table and retry policy like
+// proxied requests do.
+type graphQLBackend struct {
+	routes  *RoutingTable
+	clients map[string]*http.Client
+	log     *logger.Logger
+}
+
+func newGraphQLBackend(routes *RoutingTable, log *logger.Logger) *graphQLBackend {
+	clients := make(map[string]*http.Client)
+	for _, service := range []string{"iam", "customer", "sales", "notification"} {
+		clients[service] = &http.Client{
+			Timeout:   graphQLBackendTimeout,
+			Transport: newRetryTransport(service, routes, http.DefaultTransport, log),
+		}
+	}
+	return &graphQLBackend{routes: routes, clients: clients, log: log}
+}
+
+// graphQLCaller is the identity a GraphQL request runs as. Backend calls are
+// made with the caller's own token, so each service enforces its usual
+// permissions, and with the tenant from the verified token claims.
+type graphQLCaller struct {
+	authorization string
+	identity      http.Header // Signed identity headers, forwarded as is
+	requestID     string
+	tenantID      string
+
+	users         *graphql.Loader[string, map[string]interface{}]
+	customers     *graphql.Loader[string, map[string]interface{}]
+	leads         *graphql.Loader[string, map[string]interface{}]
+	opportunities *graphql.Loader[string, map[string]interface{}]
+}
+
+type graphQLCallerKey struct{}
+
+func callerFromContext(ctx context.Context) (*graphQLCaller, error) {
+	caller, ok := ctx.Value(graphQLCallerKey{}).(*graphQLCaller)
+	if !ok {
+		return nil, graphql.NewError("UNAUTHENTICATED", "authentication required")
+	}
+	return caller, nil
+}
+
+// prepare attaches the caller and its per-request loaders to a request.
+func (b *graphQLBackend) prepare(r *http.Request) *http.Request {
+	claims, ok := auth.ClaimsFromContext(r.Context())
+	if !ok {
+		return r
+	}
+
+	caller := &graphQLCaller{
+		authorization: r.Header.Get("Authorization"),
+		identity:      http.Header{},
+		requestID:     middleware.RequestIDFromContext(r.Context()),
+		tenantID:      claims.TenantID,
+	}
+	identity.Copy(caller.identity, r.Header)
+	caller.users = b.batchLoader(caller, "iam", "/api/v1/users/batch-get")
+	caller.customers = b.batchLoader(caller, "customer", "/api/v1/customers/batch-get")
+	caller.leads = b.loader(caller, "sales", "/api/v1/sales/leads/")
+	caller.opportunities = b.batchLoader(caller, "sales", "/api/v1/sales/opportunities/batch-get")
+
+	return r.WithContext(context.WithValue(r.Context(), graphQLCallerKey{}, caller))
+}
+
+// loader creates a loader that fetches resources by ID from a service with
+// no batch get endpoint, so a batch is deduplicated and fanned out
+// concurrently.
+func (b *graphQLBackend) loader(caller *graphQLCaller, service, prefix string) *graphql.Loader[string, map[string]interface{}] {
+	return graphql.NewLoader(func(ctx context.Context, ids []string) ([]map[string]interface{}, []error) {
+		values := make([]map[string]interface{}, len(ids))
+		errs := make([]error, len(ids))
+
+		var wg sync.WaitGroup
+		for i, id := range ids {
+			wg.Add(1)
+			go func(i int, id string) {
+				defer wg.Done()
+				data, err := b.get(ctx, caller, service, prefix+url.PathEscape(id), nil)
+				if err != nil {
+					errs[i] = err
+					return
+				}
+				values[i] = caller.scope(data)
+			}(i, id)
+		}
+		wg.Wait()
+
+		return values, errs
+	}, graphQLMaxBatch)
+}
+
+// batchLoader creates a loader that fetches resources by ID from a service's
+// batch get endpoint, which takes {"ids": [...]} and returns the resources it
+// found. IDs that were not found, or are not UUIDs, load as nil.
+func (b *graphQLBackend) batchLoader(caller *graphQLCaller, service, path string) *graphql.Loader[string, map[string]interface{}] {
+	return graphql.NewLoader(func(ctx context.Context, ids []string) ([]map[string]interface{}, []error) {
+		values := make([]map[string]interface{}, len(ids))
+		errs := make([]error, len(ids))
+
+		// A malformed ID would fail the whole batch, and cannot match anyway.
+		keys := make([]string, len(ids))
+		valid := make([]string, 0, len(ids))
+		for i, id := range ids {
+			if parsed, err := uuid.Parse(id); err == nil {
+				keys[i] = parsed.String()
+				valid = append(valid, keys[i])
+			}
+		}
+		if len(valid) == 0 {
+			return values, errs
+		}
+
+		data, err := b.post(ctx, caller, service, path, map[string]interface{}{"ids": valid})
+		if err != nil {
+			for i := range errs {
+				errs[i] = err
+			}
+			return values, errs
+		}
+
+		found := make(map[string]map[string]interface{})
+		if object, ok := data.(map[string]interface{}); ok {
+			items, _ := object["found"].([]interface{})
+			for _, item := range items {
+				if resource := caller.scope(item); resource != nil {
+					if id, ok := resource["id"].(string); ok {
+						found[id] = resource
+					}
+				}
+			}
+		}
+		for i, key := range keys {
+			if key != "" {
+				values[i] = found[key]
+			}
+		}
+
+		return values, errs
+	}, graphQLMaxBatchGet)
+}
+
+// get calls a backend endpoint and returns the response data, unwrapped from
+// the services' {"success", "data"} envelope, with its keys in camelCase. A
+// 404 returns nil data.
+func (b *graphQLBackend) get(ctx context.Context, caller *graphQLCaller, service, path string, query url.Values) (interface{}, error) {
+	return b.call(ctx, caller, service, http.MethodGet, path, query, nil)
+}
+
+// post sends body as JSON to a backend endpoint and returns the response data
+// as get does.
+func (b *graphQLBackend) post(ctx context.Context, caller *graphQLCaller, service, path string, body interface{}) (interface{}, error) {
+	encoded, err := json.Marshal(body)
+	if err != nil {
+		return nil, err
+	}
+	return b.call(ctx, caller, service, http.MethodPost, path, nil, encoded)
+}
+
+// call makes a backend request on behalf of the caller.
+func (b *graphQLBackend) call(ctx context.Context, caller *graphQLCaller, service, method, path string, query url.Values, content []byte) (interface{}, error) {
+	target, err := b.routes.Pick(service)
+	if err != nil {
+		return nil, graphql.NewError("SERVICE_UNAVAILABLE", fmt.Sprintf("%s service is unavailable", service))
+	}
+
+	endpoint := target.JoinPath(path)
+	endpoint.RawQuery = query.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, method, endpoint.String(), bytes.NewReader(content))
+	if err != nil {
+		return nil, err
+	}
+	if content != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("Authorization", caller.authorization)
+	identity.Copy(req.Header, caller.identity)
+	req.Header.Set("X-Tenant-ID", caller.tenantID)
+	if caller.requestID != "" {
+		req.Header.Set("X-Request-ID", caller.requestID)
+	}
+
+	resp, err := b.clients[service].Do(req)
+	if err != nil {
+		b.log.Warn().Err(err).Str("service", service).Str("path", path).Msg("GraphQL backend call failed")
+		return nil, graphql.NewError("SERVICE_UNAVAILABLE", fmt.Sprintf("%s service is unavailable", service))
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, graphql.NewError("SERVICE_UNAVAILABLE", fmt.Sprintf("failed to read %s service response", service))
+	}
+
+	var payload interface{}
+	decodeErr := json.Unmarshal(body, &payload)
+
+	switch {
+	case resp.StatusCode == http.StatusNotFound:
+		return nil, nil
+	case resp.StatusCode == http.StatusUnauthorized:
+		return nil, graphql.NewError("UNAUTHENTICATED", backendMessage(payload, "authentication required"))
+	case resp.StatusCode == http.StatusForbidden:
+		return nil, graphql.NewError("FORBIDDEN", backendMessage(payload, "permission denied"))
+	case resp.StatusCode >= 500:
+		b.log.Warn().Int("status", resp.StatusCode).Str("service", service).Str("path", path).Msg("GraphQL backend call failed")
+		return nil, graphql.NewError("SERVICE_UNAVAILABLE", fmt.Sprintf("%s service is unavailable", service))
+	case resp.StatusCode >= 400:
+		return nil, graphql.NewError("BAD_REQUEST", backendMessage(payload, "invalid request"))
+	case decodeErr != nil:
+		return nil, graphql.NewError("BAD_GATEWAY", fmt.Sprintf("invalid %s service response", service))
+	}
+
+	if envelope, ok := payload.(map[string]interface{}); ok {
+		if _, wrapped := envelope["success"]; wrapped {
+			payload = envelope["data"]
+		}
+	}
+	return camelizeKeys(payload), nil
+}
+
+// list calls a list endpoint and returns the items visible to the caller.
+func (b *graphQLBackend) list(ctx context.Context, caller *graphQLCaller, service, path string, query url.Values, key string) ([]interface{}, error) {
+	data, err := b.get(ctx, caller, service, path, query)
+	if err != nil {
+		return nil, err
+	}
+
+	// Some list endpoints return the items under a named key with their own
+	// pagination instead of in the envelope's data
+	if object, ok := data.(map[string]interface{}); ok {
+		data = object[key]
+	}
+	items, _ := data.([]interface{})
+
+	visible := make([]interface{}, 0, len(items))
+	for _, item := range items {
+		if object := caller.scope(item); object != nil {
+			visible = append(visible, object)
+		}
+	}
+	return visible, nil
+}
+
+// scope returns a resource only if it belongs to the caller's tenant, so a
+// misbehaving backend cannot leak another tenant's data through a stitched
+// field.
+func (c *graphQLCaller) scope(data interface{}) map[string]interface{} {
+	object, ok := data.(map[string]interface{})
+	if !ok {
+		return nil
+	}
+	if tenantID, ok := object["tenantId"].(string); ok && tenantID != c.tenantID {
+		return nil
+	}
+	return object
+}
+
+// backendMessage extracts the error message of a service error response.
+func backendMessage(payload interface{}, fallback string) string {
+	object, ok := payload.(map[string]interface{})
+	if !ok {
+		return fallback
+	}
+	if nested, ok := object["error"].(map[string]interface{}); ok {
+		object = nested
+	}
+	if message, ok := object["message"].(string); ok && message != "" {
+		return message
+	}
+	return fallback
+}
+
+// camelizeKeys converts the snake_case keys of decoded JSON to camelCase,
+// matching the GraphQL field names.
+func camelizeKeys(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		out := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			out[camelCase(key)] = camelizeKeys(item)
+		}
+		return out
+	case []interface{}:
+		for i, item := range v {
+			v[i] = camelizeKeys(item)
+		}
+		return v
+	}
+	return value
+}
+
+func camelCase(key string) string {
+	parts := strings.Split(key, "_")
+	for i := 1; i < len(parts); i++ {
+		if parts[i] != "" {
+			parts[i] = strings.ToUpper(parts[i][:1]) + parts[i][1:]
+		}
+	}
+	return strings.Join(parts, "")
+}
+
+// ============================================================================
+// Schema
+// ============================================================================
+
+// newGraphQLSchema builds the gateway schema. Root fields call the owning
+// service; fields that cross services resolve through the per-request
+// loaders, so a list of N opportunities loads its customers in one batch.
+func newGraphQLSchema(backend *graphQLBackend, maxDepth, maxComplexity int) (*graphql.Schema, error) {
+	money := graphql.NewObject("Money", "A monetary amount in minor units.")
+	money.AddField("amount", &graphql.FieldDef{Type: graphql.Float, Description: "Amount in minor units, e.g. sen."})
+	money.AddField("currency", &graphql.FieldDef{Type: graphql.String})
+	money.AddField("display", &graphql.FieldDef{Type: graphql.String})
+
+	role := graphql.NewObject("Role", "")
+	role.AddField("id", &graphql.FieldDef{Type: graphql.ID})
+	role.AddField("name", &graphql.FieldDef{Type: graphql.String})
+
+	user := graphql.NewObject("User", "A user of the tenant, from the IAM service.")
+	user.AddField("id", &graphql.FieldDef{Type: graphql.ID})
+	user.AddField("email", &graphql.FieldDef{Type: graphql.String})
+	user.AddField("firstName", &graphql.FieldDef{Type: graphql.String})
+	user.AddField("lastName", &graphql.FieldDef{Type: graphql.String})
+	user.AddField("fullName", &graphql.FieldDef{Type: graphql.String})
+	user.AddField("phone", &graphql.FieldDef{Type: graphql.String})
+	user.AddField("avatarUrl", &graphql.FieldDef{Type: graphql.String})
+	user.AddField("status", &graphql.FieldDef{Type: graphql.String})
+	user.AddField("roles", &graphql.FieldDef{Type: graphql.NewList(role)})
+	user.AddField("permissions", &graphql.FieldDef{Type: graphql.NewList(graphql.String)})
+	user.AddField("createdAt", &graphql.FieldDef{Type: graphql.String})
+
+	stage := graphql.NewObject("Stage", "A pipeline stage.")
+	stage.AddField("id", &graphql.FieldDef{Type: graphql.ID})
+	stage.AddField("name", &graphql.FieldDef{Type: graphql.String})
+	stage.AddField("type", &graphql.FieldDef{Type: graphql.String})
+	stage.AddField("probability", &graphql.FieldDef{Type: graphql.Int})
+
+	customer := graphql.NewObject("Customer", "A customer, from the customer service.")
+	lead := graphql.NewObject("Lead", "A lead, from the sales service.")
+	opportunity := graphql.NewObject("Opportunity", "An opportunity, from the sales service.")
+
+	customer.AddField("id", &graphql.FieldDef{Type: graphql.ID})
+	customer.AddField("code", &graphql.FieldDef{Type: graphql.String})
+	customer.AddField("name", &graphql.FieldDef{Type: graphql.String})
+	customer.AddField("type", &graphql.FieldDef{Type: graphql.String})
+	customer.AddField("status", &graphql.FieldDef{Type: graphql.String})
+	customer.AddField("tier", &graphql.FieldDef{Type: graphql.String})
+	customer.AddField("email", &graphql.FieldDef{Type: graphql.String})
+	customer.AddField("website", &graphql.FieldDef{Type: graphql.String})
+	customer.AddField("tags", &graphql.FieldDef{Type: graphql.NewList(graphql.String)})
+	customer.AddField("createdAt", &graphql.FieldDef{Type: graphql.String})
+	customer.AddField("owner", &graphql.FieldDef{Type: user, Resolve: loadRef("ownerId", usersLoader)})
+	customer.AddField("opportunities", &graphql.FieldDef{
+		Description: "The customer's opportunities, newest first.",
+		Type:        graphql.NewList(opportunity),
+		Args:        []*graphql.ArgDef{statusArg(), pageSizeArg(10)},
+		Complexity:  listComplexity,
+		Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+			query := pageQuery(p.Args, "page", "page_size")
+			query.Set("customer_ids", stringField(p.Source, "id"))
+			setStatuses(query, p.Args)
+			return backend.resolveList(p, "sales", "/api/v1/sales/opportunities", query, "opportunities")
+		},
+	})
+
+	lead.AddField("id", &graphql.FieldDef{Type: graphql.ID})
+	lead.AddField("firstName", &graphql.FieldDef{Type: graphql.String})
+	lead.AddField("lastName", &graphql.FieldDef{Type: graphql.String})
+	lead.AddField("fullName", &graphql.FieldDef{Type: graphql.String})
+	lead.AddField("email", &graphql.FieldDef{Type: graphql.String})
+	lead.AddField("phone", &graphql.FieldDef{Type: graphql.String})
+	lead.AddField("company", &graphql.FieldDef{Type: graphql.String})
+	lead.AddField("status", &graphql.FieldDef{Type: graphql.String})
+	lead.AddField("score", &graphql.FieldDef{Type: graphql.Int})
+	lead.AddField("rating", &graphql.FieldDef{Type: graphql.String})
+	lead.AddField("source", &graphql.FieldDef{Type: graphql.String})
+	lead.AddField("createdAt", &graphql.FieldDef{Type: graphql.String})
+	lead.AddField("owner", &graphql.FieldDef{Type: user, Resolve: loadRef("ownerId", usersLoader)})
+
+	opportunity.AddField("id", &graphql.FieldDef{Type: graphql.ID})
+	opportunity.AddField("name", &graphql.FieldDef{Type: graphql.String})
+	opportunity.AddField("status", &graphql.FieldDef{Type: graphql.String})
+	opportunity.AddField("amount", &graphql.FieldDef{Type: money})
+	opportunity.AddField("probability", &graphql.FieldDef{Type: graphql.Int})
+	opportunity.AddField("expectedCloseDate", &graphql.FieldDef{Type: graphql.String})
+	opportunity.AddField("stage", &graphql.FieldDef{Type: stage})
+	opportunity.AddField("createdAt", &graphql.FieldDef{Type: graphql.String})
+	opportunity.AddField("customer", &graphql.FieldDef{Type: customer, Resolve: loadRef("customerId", customersLoader)})
+	opportunity.AddField("lead", &graphql.FieldDef{Type: lead, Resolve: loadRef("leadId", leadsLoader)})
+	opportunity.AddField("owner", &graphql.FieldDef{Type: user, Resolve: loadRef("ownerId", usersLoader)})
+
+	notification := graphql.NewObject("Notification", "A notification, from the notification service.")
+	notification.AddField("id", &graphql.FieldDef{Type: graphql.ID})
+	notification.AddField("type", &graphql.FieldDef{Type: graphql.String})
+	notification.AddField("channel", &graphql.FieldDef{Type: graphql.String})
+	notification.AddField("status", &graphql.FieldDef{Type: graphql.String})
+	notification.AddField("subject", &graphql.FieldDef{Type: graphql.String})
+	notification.AddField("body", &graphql.FieldDef{Type: graphql.String})
+	notification.AddField("createdAt", &graphql.FieldDef{Type: graphql.String})
+	notification.AddField("readAt", &graphql.FieldDef{Type: graphql.String})
+
+	query := graphql.NewObject("Query", "")
+	query.AddField("me", &graphql.FieldDef{
+		Description: "The authenticated user.",
+		Type:        user,
+		Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+			// Read through /auth/me, which needs no user management permission
+			caller, err := callerFromContext(p.Context)
+			if err != nil {
+				return nil, err
+			}
+			data, err := backend.get(p.Context, caller, "iam", "/api/v1/auth/me", nil)
+			if err != nil {
+				return nil, err
+			}
+			return caller.scope(data), nil
+		},
+	})
+	query.AddField("user", &graphql.FieldDef{Type: user, Args: []*graphql.ArgDef{idArg()}, Resolve: loadArg(usersLoader)})
+	query.AddField("customer", &graphql.FieldDef{Type: customer, Args: []*graphql.ArgDef{idArg()}, Resolve: loadArg(customersLoader)})
+	query.AddField("lead", &graphql.FieldDef{Type: lead, Args: []*graphql.ArgDef{idArg()}, Resolve: loadArg(leadsLoader)})
+	query.AddField("opportunity", &graphql.FieldDef{Type: opportunity, Args: []*graphql.ArgDef{idArg()}, Resolve: loadArg(opportunitiesLoader)})
+	query.AddField("customers", &graphql.FieldDef{
+		Type:       graphql.NewList(customer),
+		Args:       []*graphql.ArgDef{{Name: "search", Type: graphql.String}, statusArg(), pageArg(), pageSizeArg(graphQLDefaultPageSize)},
+		Complexity: listComplexity,
+		Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+			// The customer service pages by offset rather than page number
+			page, pageSize := pageArgs(p.Args)
+			query := url.Values{}
+			query.Set("offset", strconv.Itoa((page-1)*pageSize))
+			query.Set("limit", strconv.Itoa(pageSize))
+			if search, ok := p.Args["search"].(string); ok && search != "" {
+				query.Set("q", search)
+			}
+			setStatuses(query, p.Args)
+			return backend.resolveList(p, "customer", "/api/v1/customers", query, "customers")
+		},
+	})
+	query.AddField("leads", &graphql.FieldDef{
+		Type:       graphql.NewList(lead),
+		Args:       []*graphql.ArgDef{statusArg(), pageArg(), pageSizeArg(graphQLDefaultPageSize)},
+		Complexity: listComplexity,
+		Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+			query := pageQuery(p.Args, "page", "page_size")
+			setStatuses(query, p.Args)
+			return backend.resolveList(p, "sales", "/api/v1/sales/leads", query, "leads")
+		},
+	})
+	query.AddField("opportunities", &graphql.FieldDef{
+		Type:       graphql.NewList(opportunity),
+		Args:       []*graphql.ArgDef{statusArg(), {Name: "customerId", Type: graphql.ID}, pageArg(), pageSizeArg(graphQLDefaultPageSize)},
+		Complexity: listComplexity,
+		Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+			query := pageQuery(p.Args, "page", "page_size")
+			if customerID, ok := p.Args["customerId"].(string); ok && customerID != "" {
+				query.Set("customer_ids", customerID)
+			}
+			setStatuses(query, p.Args)
+			return backend.resolveList(p, "sales", "/api/v1/sales/opportunities", query, "opportunities")
+		},
+	})
+	query.AddField("notifications", &graphql.FieldDef{
+		Type:       graphql.NewList(notification),
+		Args:       []*graphql.ArgDef{pageArg(), pageSizeArg(graphQLDefaultPageSize)},
+		Complexity: listComplexity,
+		Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+			query := pageQuery(p.Args, "page", "page_size")
+			return backend.resolveList(p, "notification", "/api/v1/notifications", query, "notifications")
+		},
+	})
+
+	return graphql.NewSchema(graphql.SchemaConfig{
+		Query:         query,
+		MaxDepth:      maxDepth,
+		MaxComplexity: maxComplexity,
+	})
+}
+
+func (b *graphQLBackend) resolveList(p graphql.ResolveParams, service, path string, query url.Values, key string) (interface{}, error) {
+	caller, err := callerFromContext(p.Context)
+	if err != nil {
+		return nil, err
+	}
+	return b.list(p.Context, caller, service, path, query, key)
+}
+
+// ============================================================================
+// Resolver Helpers
+// ============================================================================
+
+type loaderFunc func(c *graphQLCaller) *graphql.Loader[string, map[string]interface{}]
+
+func usersLoader(c *graphQLCaller) *graphql.Loader[string, map[string]interface{}] {
+	return c.users
+}
+
+func customersLoader(c *graphQLCaller) *graphql.Loader[string, map[string]interface{}] {
+	return c.customers
+}
+
+func leadsLoader(c *graphQLCaller) *graphql.Loader[string, map[string]interface{}] {
+	return c.leads
+}
+
+func opportunitiesLoader(c *graphQLCaller) *graphql.Loader[string, map[string]interface{}] {
+	return c.opportunities
+}
+
+// loadArg resolves a root field by its id argument.
+func loadArg(loader loaderFunc) graphql.ResolveFunc {
+	return func(p graphql.ResolveParams) (interface{}, error) {
+		caller, err := callerFromContext(p.Context)
+		if err != nil {
+			return nil, err
+		}
+		return loader(caller).Load(p.Context, p.Args["id"].(string)), nil
+	}
+}
+
+// loadRef resolves a field that references a resource of another service by
+// the ID held in the source's key.
+func loadRef(key string, loader loaderFunc) graphql.ResolveFunc {
+	return func(p graphql.ResolveParams) (interface{}, error) {
+		id := stringField(p.Source, key)
+		if id == "" {
+			return nil, nil
+		}
+		caller, err := callerFromContext(p.Context)
+		if err != nil {
+			return nil, err
+		}
+		return loader(caller).Load(p.Context, id), nil
+	}
+}
+
+func stringField(source interface{}, key string) string {
+	object, _ := source.(map[string]interface{})
+	value, _ := object[key].(string)
+	return value
+}
+
+func idArg() *graphql.ArgDef {
+	return &graphql.ArgDef{Name: "id", Type: graphql.NewNonNull(graphql.ID)}
+}
+
+func statusArg() *graphql.ArgDef {
+	return &graphql.ArgDef{Name: "status", Type: graphql.NewList(graphql.String), Description: "Only return items with one of these statuses."}
+}
+
+func pageArg() *graphql.ArgDef {
+	return &graphql.ArgDef{Name: "page", Type: graphql.Int, DefaultValue: 1}
+}
+
+func pageSizeArg(def int) *graphql.ArgDef {
+	return &graphql.ArgDef{Name: "pageSize", Type: graphql.Int, DefaultValue: def}
+}
+
+// pageArgs returns the page and page size arguments, clamped to valid values.
+func pageArgs(args map[string]interface{}) (page, pageSize int) {
+	page, _ = args["page"].(int)
+	pageSize, _ = args["pageSize"].(int)
+	page = max(page, 1)
+	pageSize = min(max(pageSize, 1), graphQLMaxPageSize)
+	return page, pageSize
+}
+
+func pageQuery(args map[string]interface{}, pageParam, pageSizeParam string) url.Values {
+	page, pageSize := pageArgs(args)
+	query := url.Values{}
+	query.Set(pageParam, strconv.Itoa(page))
+	query.Set(pageSizeParam, strconv.Itoa(pageSize))
+	return query
+}
+
+func setStatuses(query url.Values, args map[string]interface{}) {
+	statuses, _ := args["status"].([]interface{})
+	values := make([]string, 0, len(statuses))
+	for _, status := range statuses {
+		if s, ok := status.(string); ok && s != "" {
+			values = append(values, s)
+		}
+	}
+	if len(values) > 0 {
+		query.Set("statuses", strings.Join(values, ","))
+	}
+}
+
+// listComplexity charges a list field for every item it may return.
+func listComplexity(args map[string]interface{}, childComplexity int) int {
+	_, pageSize := pageArgs(args)
+	return 1 + pageSize*childComplexity
+}
+
+// ============================================================================
+// Handlers
+// ============================================================================
+
+// newGraphQLHandler creates the GraphQL endpoint handler and a handler that
+// serves the schema SDL.
+func newGraphQLHandler(routes *RoutingTable, log *logger.Logger) (http.Handler, http.Handler, error) {
+	maxDepth, err := strconv.Atoi(getEnv("GATEWAY_GRAPHQL_MAX_DEPTH", strconv.Itoa(defaultGraphQLMaxDepth)))
+	if err != nil {
+		return nil, nil, errors.New("GATEWAY_GRAPHQL_MAX_DEPTH must be an integer")
+	}
+	maxComplexity, err := strconv.Atoi(getEnv("GATEWAY_GRAPHQL_MAX_COMPLEXITY", strconv.Itoa(defaultGraphQLMaxComplexity)))
+	if err != nil {
+		return nil, nil, errors.New("GATEWAY_GRAPHQL_MAX_COMPLEXITY must be an integer")
+	}
+
+	backend := newGraphQLBackend(routes, log)
+	schema, err := newGraphQLSchema(backend, maxDepth, maxComplexity)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	handler := graphql.NewHandler(schema)
+	handler.Prepare = backend.prepare
+
+	sdl := schema.SDL()
+	schemaHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		io.WriteString(w, sdl)
+	})
+
+	return handler, schemaHandler, nil
+}
@@ -0,0 +1,518 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/kilang-desa-murni/crm/pkg/database"
+	apperrors "github.com/kilang-desa-murni/crm/pkg/errors"
+	"github.com/kilang-desa-murni/crm/pkg/logger"
+	"github.com/kilang-desa-murni/crm/pkg/middleware"
+	"github.com/kilang-desa-murni/crm/pkg/response"
+)
+
+const (
+	defaultHeavyTenantConcurrency = 2
+	defaultHeavyMaxConcurrency    = 8
+	defaultHeavyQueueSize         = 20
+	defaultHeavyJobTTL            = 24 * time.Hour
+
+	// maxHeavyJobBody is the largest request body queued; larger requests
+	// over the caps are refused.
+	maxHeavyJobBody = 10 << 20
+	// maxHeavyJobResult is the largest response kept for a queued request.
+	maxHeavyJobResult = 10 << 20
+	// heavyJobAttempts is how often a queued request is sent while the
+	// service sheds it to serve interactive requests.
+	heavyJobAttempts = 3
+
+	heavyJobKeyPrefix = "gateway:heavy_jobs:"
+)
+
+// Queued request states.
+const (
+	heavyJobQueued    = "queued"
+	heavyJobRunning   = "running"
+	heavyJobCompleted = "completed"
+	heavyJobFailed    = "failed"
+)
+
+// ============================================================================
+// Configuration
+// ============================================================================
+
+// heavyConfig caps the heavy requests in flight at a gateway instance.
+type heavyConfig struct {
+	// TenantConcurrency is the heavy requests a tenant may have in flight.
+	TenantConcurrency int
+	// MaxConcurrency is the heavy requests in flight across tenants, so
+	// they never take the connections interactive requests need.
+	MaxConcurrency int
+	// QueueSize is the heavy requests a tenant may have queued.
+	QueueSize int
+	// JobTTL is how long queued requests and their responses are kept.
+	JobTTL time.Duration
+}
+
+// loadHeavyConfig reads the heavy request caps from the environment.
+func loadHeavyConfig() (heavyConfig, error) {
+	cfg := heavyConfig{JobTTL: defaultHeavyJobTTL}
+	for _, setting := range []struct {
+		env   string
+		value *int
+		def   int
+	}{
+		{"GATEWAY_HEAVY_TENANT_CONCURRENCY", &cfg.TenantConcurrency, defaultHeavyTenantConcurrency},
+		{"GATEWAY_HEAVY_MAX_CONCURRENCY", &cfg.MaxConcurrency, defaultHeavyMaxConcurrency},
+		{"GATEWAY_HEAVY_QUEUE_SIZE", &cfg.QueueSize, defaultHeavyQueueSize},
+	} {
+		value, err := strconv.Atoi(getEnv(setting.env, strconv.Itoa(setting.def)))
+		if err != nil || value <= 0 {
+			return cfg, fmt.Errorf("%s must be a positive integer", setting.env)
+		}
+		*setting.value = value
+	}
+
+	if value := getEnv("GATEWAY_HEAVY_JOB_TTL", ""); value != "" {
+		ttl, err := time.ParseDuration(value)
+		if err != nil || ttl <= 0 {
+			return cfg, fmt.Errorf("invalid GATEWAY_HEAVY_JOB_TTL %q", value)
+		}
+		cfg.JobTTL = ttl
+	}
+	return cfg, nil
+}
+
+// isHeavyRequest reports whether a request starts an import, an export or a
+// bulk operation. Listing imports and exports is not heavy; starting them
+// is. Of a resumable upload only completing it, which runs the import, is
+// heavy; its chunks are not.
+func isHeavyRequest(r *http.Request) bool {
+	for _, segment := range strings.Split(strings.Trim(r.URL.Path, "/"), "/") {
+		switch segment {
+		case "import", "export", "bulk":
+			return true
+		case "imports", "exports":
+			if r.Method == http.MethodPost {
+				return true
+			}
+		case "import-uploads":
+			return r.Method == http.MethodPost && strings.HasSuffix(strings.TrimSuffix(r.URL.Path, "/"), "/complete")
+		}
+	}
+	return false
+}
+
+// ============================================================================
+// Heavy Requests
+// ============================================================================
+
+// heavyJob is a heavy request queued because its tenant, or the gateway,
+// had as many heavy requests in flight as allowed.
+type heavyJob struct {
+	ID          string     `json:"id"`
+	TenantID    string     `json:"tenant_id"`
+	UserID      string     `json:"user_id"`
+	Method      string     `json:"method"`
+	Path        string     `json:"path"`
+	Status      string     `json:"status"`
+	StatusCode  int        `json:"status_code,omitempty"`
+	Error       string     `json:"error,omitempty"`
+	ResultURL   string     `json:"result_url,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+	StartedAt   *time.Time `json:"started_at,omitempty"`
+	CompletedAt *time.Time `json:"completed_at,omitempty"`
+}
+
+// heavyJobResult is the response of a queued request.
+type heavyJobResult struct {
+	StatusCode  int    `json:"status_code"`
+	ContentType string `json:"content_type,omitempty"`
+	Body        []byte `json:"body,omitempty"`
+}
+
+// queuedRequest is a queued request waiting for a slot.
+type queuedRequest struct {
+	job  *heavyJob
+	req  *http.Request
+	body []byte
+	next http.Handler
+}
+
+// heavyTenant is a tenant's heavy requests at this gateway instance.
+type heavyTenant struct {
+	inFlight int
+	queue    []*queuedRequest
+}
+
+// heavyRequests caps the heavy requests of each tenant, so imports, exports
+// and bulk operations cannot starve interactive requests. Requests over the
+// caps are queued and answered with 202 and a job to follow; the services
+// are told they are background requests, which they shed first when busy.
+// Caps and queues are per gateway instance; jobs are kept in Redis, so any
+// instance answers for them.
+type heavyRequests struct {
+	config heavyConfig
+	redis  *database.RedisClient
+	log    *logger.Logger
+
+	mu       sync.Mutex
+	inFlight int
+	tenants  map[string]*heavyTenant
+
+	served   uint64
+	queued   uint64
+	refused  uint64
+	finished uint64
+}
+
+func newHeavyRequests(config heavyConfig, redis *database.RedisClient, log *logger.Logger) *heavyRequests {
+	return &heavyRequests{
+		config:  config,
+		redis:   redis,
+		log:     log,
+		tenants: make(map[string]*heavyTenant),
+	}
+}
+
+// Middleware serves heavy requests within the caps and queues the others.
+// It runs after authentication, as caps are per tenant.
+func (h *heavyRequests) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		tenantID := middleware.TenantIDFromContext(r.Context())
+		if !isHeavyRequest(r) || tenantID == "" {
+			r.Header.Del(middleware.PriorityHeader)
+			next.ServeHTTP(w, r)
+			return
+		}
+		r.Header.Set(middleware.PriorityHeader, middleware.PriorityBackground)
+
+		h.mu.Lock()
+		acquired := h.acquire(tenantID)
+		h.mu.Unlock()
+		if acquired {
+			defer h.release(tenantID)
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxHeavyJobBody))
+		if err != nil {
+			h.refuse(w, "Request body too large to queue")
+			return
+		}
+
+		now := time.Now().UTC()
+		job := &heavyJob{
+			ID:        uuid.New().String(),
+			TenantID:  tenantID,
+			UserID:    middleware.UserIDFromContext(r.Context()),
+			Method:    r.Method,
+			Path:      r.URL.Path,
+			Status:    heavyJobQueued,
+			CreatedAt: now,
+		}
+		if err := h.save(r.Context(), job); err != nil {
+			h.log.Error().Err(err).Msg("Failed to save queued request")
+			response.Error(w, apperrors.ErrInternal("Failed to queue request"))
+			return
+		}
+
+		// The request outlives the client's connection, keeping the caller
+		// found by authentication
+		queued := &queuedRequest{
+			job:  job,
+			req:  r.Clone(context.WithoutCancel(r.Context())),
+			body: body,
+			next: next,
+		}
+		if !h.enqueue(tenantID, queued) {
+			_ = h.redis.Delete(r.Context(), heavyJobKeyPrefix+job.ID)
+			h.refuse(w, "Too many heavy requests queued")
+			return
+		}
+
+		w.Header().Set("Location", heavyJobURL(job.ID))
+		response.Accepted(w, job)
+	})
+}
+
+// acquire takes a slot for a request of the tenant unless the caps are
+// reached or the tenant has requests queued before it; h.mu must be held.
+func (h *heavyRequests) acquire(tenantID string) bool {
+	tenant := h.tenant(tenantID)
+	if h.inFlight >= h.config.MaxConcurrency || tenant.inFlight >= h.config.TenantConcurrency || len(tenant.queue) > 0 {
+		return false
+	}
+	h.inFlight++
+	tenant.inFlight++
+	h.served++
+	return true
+}
+
+// enqueue queues a request of the tenant, starting it at once if a slot
+// freed up meanwhile. It reports false when the tenant's queue is full.
+func (h *heavyRequests) enqueue(tenantID string, queued *queuedRequest) bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	tenant := h.tenant(tenantID)
+	if len(tenant.queue) >= h.config.QueueSize {
+		h.refused++
+		return false
+	}
+	tenant.queue = append(tenant.queue, queued)
+	h.queued++
+	h.startQueued()
+	return true
+}
+
+// release frees the slot of a finished request and starts queued requests
+// that fit in the caps.
+func (h *heavyRequests) release(tenantID string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	tenant := h.tenant(tenantID)
+	h.inFlight--
+	tenant.inFlight--
+	h.startQueued()
+	if tenant.inFlight == 0 && len(tenant.queue) == 0 {
+		delete(h.tenants, tenantID)
+	}
+}
+
+// startQueued starts queued requests while there are slots; h.mu must be
+// held.
+func (h *heavyRequests) startQueued() {
+	for id, tenant := range h.tenants {
+		for len(tenant.queue) > 0 && h.inFlight < h.config.MaxConcurrency && tenant.inFlight < h.config.TenantConcurrency {
+			queued := tenant.queue[0]
+			tenant.queue = tenant.queue[1:]
+			h.inFlight++
+			tenant.inFlight++
+			go h.run(id, queued)
+		}
+	}
+}
+
+func (h *heavyRequests) tenant(tenantID string) *heavyTenant {
+	tenant, ok := h.tenants[tenantID]
+	if !ok {
+		tenant = &heavyTenant{}
+		h.tenants[tenantID] = tenant
+	}
+	return tenant
+}
+
+// run sends a queued request and keeps its response for the client.
+func (h *heavyRequests) run(tenantID string, queued *queuedRequest) {
+	defer h.release(tenantID)
+	ctx := queued.req.Context()
+	job := queued.job
+
+	started := time.Now().UTC()
+	job.Status = heavyJobRunning
+	job.StartedAt = &started
+	if err := h.save(ctx, job); err != nil {
+		h.log.Warn().Err(err).Str("job_id", job.ID).Msg("Failed to save queued request")
+	}
+
+	rec := h.send(queued)
+
+	completed := time.Now().UTC()
+	job.CompletedAt = &completed
+	job.StatusCode = rec.status
+	switch {
+	case rec.truncated:
+		job.Status = heavyJobFailed
+		job.Error = "response too large to keep; retry the request later"
+	case rec.status >= http.StatusInternalServerError:
+		job.Status = heavyJobFailed
+		job.ResultURL = heavyJobURL(job.ID) + "/result"
+	default:
+		job.Status = heavyJobCompleted
+		job.ResultURL = heavyJobURL(job.ID) + "/result"
+	}
+
+	if job.ResultURL != "" {
+		result := heavyJobResult{
+			StatusCode:  rec.status,
+			ContentType: rec.header.Get("Content-Type"),
+			Body:        rec.body.Bytes(),
+		}
+		if err := h.redis.Set(ctx, heavyJobKeyPrefix+job.ID+":result", result, h.config.JobTTL); err != nil {
+			h.log.Error().Err(err).Str("job_id", job.ID).Msg("Failed to save queued request response")
+			job.Status = heavyJobFailed
+			job.Error = "failed to keep the response"
+			job.ResultURL = ""
+		}
+	}
+	if err := h.save(ctx, job); err != nil {
+		h.log.Error().Err(err).Str("job_id", job.ID).Msg("Failed to save queued request")
+	}
+
+	h.mu.Lock()
+	h.finished++
+	h.mu.Unlock()
+}
+
+// send sends a queued request, again after a pause while the service sheds
+// it to serve interactive requests.
+func (h *heavyRequests) send(queued *queuedRequest) (rec *jobRecorder) {
+	for attempt := 1; ; attempt++ {
+		rec = h.sendOnce(queued)
+		if rec.status != http.StatusServiceUnavailable || attempt == heavyJobAttempts {
+			return rec
+		}
+		time.Sleep(time.Duration(attempt) * 2 * time.Second)
+	}
+}
+
+func (h *heavyRequests) sendOnce(queued *queuedRequest) (rec *jobRecorder) {
+	rec = &jobRecorder{header: make(http.Header)}
+	defer func() {
+		if p := recover(); p != nil {
+			h.log.Error().Interface("panic", p).Str("job_id", queued.job.ID).Msg("Queued request panicked")
+			rec.status = http.StatusInternalServerError
+		}
+	}()
+
+	req := queued.req.Clone(queued.req.Context())
+	req.Body = io.NopCloser(bytes.NewReader(queued.body))
+	req.ContentLength = int64(len(queued.body))
+	queued.next.ServeHTTP(rec, req)
+	if rec.status == 0 {
+		rec.status = http.StatusOK
+	}
+	return rec
+}
+
+func (h *heavyRequests) save(ctx context.Context, job *heavyJob) error {
+	return h.redis.Set(ctx, heavyJobKeyPrefix+job.ID, job, h.config.JobTTL)
+}
+
+func (h *heavyRequests) refuse(w http.ResponseWriter, message string) {
+	w.Header().Set("Retry-After", "30")
+	response.Error(w, apperrors.ErrTooManyRequests(message))
+}
+
+// ownJob returns the queued request of the path's ID made by the caller.
+func (h *heavyRequests) ownJob(r *http.Request) (*heavyJob, error) {
+	var job heavyJob
+	if err := h.redis.Get(r.Context(), heavyJobKeyPrefix+r.PathValue("id"), &job); err != nil {
+		if errors.Is(err, database.ErrKeyNotFound) {
+			return nil, apperrors.ErrNotFound("queued request")
+		}
+		return nil, err
+	}
+	if job.TenantID != middleware.TenantIDFromContext(r.Context()) || job.UserID != middleware.UserIDFromContext(r.Context()) {
+		return nil, apperrors.ErrNotFound("queued request")
+	}
+	return &job, nil
+}
+
+// JobHandler serves GET /api/v1/queued-requests/{id}, the state of a queued
+// request of the caller.
+func (h *heavyRequests) JobHandler(w http.ResponseWriter, r *http.Request) {
+	job, err := h.ownJob(r)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+	response.OK(w, job)
+}
+
+// ResultHandler serves GET /api/v1/queued-requests/{id}/result, the response
+// of a finished queued request of the caller, as the service sent it.
+func (h *heavyRequests) ResultHandler(w http.ResponseWriter, r *http.Request) {
+	job, err := h.ownJob(r)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+	if job.ResultURL == "" {
+		response.Error(w, apperrors.ErrConflict(fmt.Sprintf("Queued request is %s", job.Status)))
+		return
+	}
+
+	var result heavyJobResult
+	if err := h.redis.Get(r.Context(), heavyJobKeyPrefix+job.ID+":result", &result); err != nil {
+		if errors.Is(err, database.ErrKeyNotFound) {
+			err = apperrors.ErrNotFound("queued request response")
+		}
+		response.Error(w, err)
+		return
+	}
+	if result.ContentType != "" {
+		w.Header().Set("Content-Type", result.ContentType)
+	}
+	w.WriteHeader(result.StatusCode)
+	_, _ = w.Write(result.Body)
+}
+
+// WriteMetrics writes the heavy request counts in the Prometheus text
+// format.
+func (h *heavyRequests) WriteMetrics(w io.Writer) {
+	h.mu.Lock()
+	queued := 0
+	for _, tenant := range h.tenants {
+		queued += len(tenant.queue)
+	}
+	inFlight, served, enqueued, refused, finished := h.inFlight, h.served, h.queued, h.refused, h.finished
+	h.mu.Unlock()
+
+	fmt.Fprintf(w, "# HELP gateway_heavy_requests_in_flight Heavy requests being served.\n# TYPE gateway_heavy_requests_in_flight gauge\n")
+	fmt.Fprintf(w, "gateway_heavy_requests_in_flight %d\n", inFlight)
+	fmt.Fprintf(w, "# HELP gateway_heavy_requests_queued Heavy requests waiting for a slot.\n# TYPE gateway_heavy_requests_queued gauge\n")
+	fmt.Fprintf(w, "gateway_heavy_requests_queued %d\n", queued)
+	fmt.Fprintf(w, "# HELP gateway_heavy_requests_total Heavy requests by how they were handled.\n# TYPE gateway_heavy_requests_total counter\n")
+	fmt.Fprintf(w, "gateway_heavy_requests_total{outcome=\"served\"} %d\n", served)
+	fmt.Fprintf(w, "gateway_heavy_requests_total{outcome=\"queued\"} %d\n", enqueued)
+	fmt.Fprintf(w, "gateway_heavy_requests_total{outcome=\"refused\"} %d\n", refused)
+	fmt.Fprintf(w, "gateway_heavy_requests_total{outcome=\"queued_finished\"} %d\n", finished)
+}
+
+func heavyJobURL(id string) string {
+	return "/api/v1/queued-requests/" + id
+}
+
+// jobRecorder records the response of a queued request, up to
+// maxHeavyJobResult bytes.
+type jobRecorder struct {
+	header    http.Header
+	status    int
+	body      bytes.Buffer
+	truncated bool
+}
+
+func (r *jobRecorder) Header() http.Header {
+	return r.header
+}
+
+func (r *jobRecorder) WriteHeader(code int) {
+	if r.status == 0 {
+		r.status = code
+	}
+}
+
+func (r *jobRecorder) Write(b []byte) (int, error) {
+	if r.status == 0 {
+		r.status = http.StatusOK
+	}
+	if r.truncated || r.body.Len()+len(b) > maxHeavyJobResult {
+		r.truncated = true
+		return len(b), nil
+	}
+	return r.body.Write(b)
+}
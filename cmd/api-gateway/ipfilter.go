@@ -0,0 +1,781 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/spf13/viper"
+
+	"github.com/kilang-desa-murni/crm/pkg/database"
+	apperrors "github.com/kilang-desa-murni/crm/pkg/errors"
+	"github.com/kilang-desa-murni/crm/pkg/logger"
+	"github.com/kilang-desa-murni/crm/pkg/middleware"
+	"github.com/kilang-desa-murni/crm/pkg/response"
+)
+
+// defaultIPFilterConfigPath is used when GATEWAY_IP_FILTER_CONFIG is not set.
+const defaultIPFilterConfigPath = "configs/gateway/ip_filter.yaml"
+
+// Redis keys of the dynamic deny-list. The hash maps each denied address or
+// CIDR to its DenyListEntry; the channel announces changes to every gateway.
+const (
+	denyListKey     = "gateway:ip_deny_list"
+	denyListChannel = "gateway:ip_deny_list:changed"
+)
+
+// denyListRefreshInterval is how often the deny-list is reloaded from Redis,
+// in case a change announcement was missed.
+const denyListRefreshInterval = 30 * time.Second
+
+// ============================================================================
+// Configuration
+// ============================================================================
+
+// IPFilterConfig is the YAML-driven IP filtering policy of the gateway.
+type IPFilterConfig struct {
+	// TrustedProxies are the addresses or CIDRs of the load balancers and
+	// proxies in front of the gateway. X-Forwarded-For is only read from
+	// them, right to left, skipping trusted hops.
+	TrustedProxies []string `mapstructure:"trusted_proxies"`
+	// GeoIP resolves client countries for country rules.
+	GeoIP GeoIPConfig `mapstructure:"geoip"`
+	// Global rules apply to every request.
+	Global IPRules `mapstructure:"global"`
+	// Groups are matched by path prefix; the group with the longest
+	// matching prefix applies in addition to the global rules.
+	Groups []IPRouteGroup `mapstructure:"groups"`
+}
+
+// GeoIPConfig configures country lookup. The country is taken from
+// CountryHeader when a trusted proxy sets it, such as a CDN's CF-IPCountry,
+// and otherwise looked up in Database.
+type GeoIPConfig struct {
+	// Database is a CSV file of "network,country" lines, such as a GeoLite2
+	// or IP2Location country export with ISO 3166 country codes.
+	Database      string `mapstructure:"database"`
+	CountryHeader string `mapstructure:"country_header"`
+}
+
+// IPRules allow or deny clients by address and country. Deny rules win. When
+// Allow or AllowCountries is set, clients outside them are denied, including
+// clients whose country is unknown.
+type IPRules struct {
+	Allow          []string `mapstructure:"allow"`
+	Deny           []string `mapstructure:"deny"`
+	AllowCountries []string `mapstructure:"allow_countries"`
+	DenyCountries  []string `mapstructure:"deny_countries"`
+}
+
+// IPRouteGroup is the policy for requests under a set of path prefixes.
+type IPRouteGroup struct {
+	Name         string   `mapstructure:"name"`
+	PathPrefixes []string `mapstructure:"path_prefixes"`
+
+	IPRules `mapstructure:",squash"`
+}
+
+// LoadIPFilterConfig reads the IP filtering policy from a YAML file. A
+// missing file yields an empty policy: no filtering, and X-Forwarded-For is
+// not trusted.
+func LoadIPFilterConfig(path string) (*IPFilterConfig, error) {
+	cfg := &IPFilterConfig{}
+	if _, err := os.Stat(path); errors.Is(err, os.ErrNotExist) {
+		return cfg, nil
+	}
+
+	v := viper.New()
+	v.SetConfigFile(path)
+	v.SetConfigType("yaml")
+	if err := v.ReadInConfig(); err != nil {
+		return nil, fmt.Errorf("error reading ip filter config: %w", err)
+	}
+	if err := v.Unmarshal(cfg); err != nil {
+		return nil, fmt.Errorf("error parsing ip filter config: %w", err)
+	}
+	return cfg, nil
+}
+
+// ============================================================================
+// Policy
+// ============================================================================
+
+// ipPolicy is a compiled IP filtering policy.
+type ipPolicy struct {
+	trusted       []netip.Prefix
+	countryHeader string
+	countries     *countryDatabase
+	global        *compiledIPRules
+	groups        []*compiledIPGroup
+	// routes are the path prefixes of the groups, longest first
+	routes []ipRoute
+}
+
+type compiledIPRules struct {
+	allow          []netip.Prefix
+	deny           []netip.Prefix
+	allowCountries map[string]bool
+	denyCountries  map[string]bool
+}
+
+type compiledIPGroup struct {
+	name  string
+	rules *compiledIPRules
+}
+
+type ipRoute struct {
+	prefix string
+	group  *compiledIPGroup
+}
+
+// compileIPPolicy validates and compiles an IP filtering policy.
+func compileIPPolicy(cfg *IPFilterConfig) (*ipPolicy, error) {
+	trusted, err := parsePrefixes(cfg.TrustedProxies)
+	if err != nil {
+		return nil, fmt.Errorf("trusted_proxies: %w", err)
+	}
+	global, err := compileIPRules(cfg.Global)
+	if err != nil {
+		return nil, fmt.Errorf("global: %w", err)
+	}
+
+	policy := &ipPolicy{
+		trusted:       trusted,
+		countryHeader: cfg.GeoIP.CountryHeader,
+		global:        global,
+	}
+	if cfg.GeoIP.Database != "" {
+		if policy.countries, err = loadCountryDatabase(cfg.GeoIP.Database); err != nil {
+			return nil, fmt.Errorf("geoip: %w", err)
+		}
+	}
+
+	for i, group := range cfg.Groups {
+		name := group.Name
+		if name == "" {
+			name = fmt.Sprintf("groups[%d]", i)
+		}
+		if len(group.PathPrefixes) == 0 {
+			return nil, fmt.Errorf("%s: path_prefixes is required", name)
+		}
+		rules, err := compileIPRules(group.IPRules)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", name, err)
+		}
+		compiled := &compiledIPGroup{name: name, rules: rules}
+		policy.groups = append(policy.groups, compiled)
+		for _, prefix := range group.PathPrefixes {
+			policy.routes = append(policy.routes, ipRoute{prefix: prefix, group: compiled})
+		}
+	}
+	// Stable, so of two groups with the same prefix the first one listed wins
+	sort.SliceStable(policy.routes, func(i, j int) bool {
+		return len(policy.routes[i].prefix) > len(policy.routes[j].prefix)
+	})
+
+	needsCountry := global.usesCountries()
+	for _, group := range policy.groups {
+		needsCountry = needsCountry || group.rules.usesCountries()
+	}
+	if needsCountry && policy.countries == nil && policy.countryHeader == "" {
+		return nil, fmt.Errorf("country rules need geoip.database or geoip.country_header")
+	}
+	return policy, nil
+}
+
+func compileIPRules(rules IPRules) (*compiledIPRules, error) {
+	allow, err := parsePrefixes(rules.Allow)
+	if err != nil {
+		return nil, fmt.Errorf("allow: %w", err)
+	}
+	deny, err := parsePrefixes(rules.Deny)
+	if err != nil {
+		return nil, fmt.Errorf("deny: %w", err)
+	}
+	return &compiledIPRules{
+		allow:          allow,
+		deny:           deny,
+		allowCountries: countrySet(rules.AllowCountries),
+		denyCountries:  countrySet(rules.DenyCountries),
+	}, nil
+}
+
+func (r *compiledIPRules) usesCountries() bool {
+	return len(r.allowCountries) > 0 || len(r.denyCountries) > 0
+}
+
+// permits reports whether the rules let a client through; country is "" when
+// unknown.
+func (r *compiledIPRules) permits(addr netip.Addr, country string) bool {
+	if containsAddr(r.deny, addr) || r.denyCountries[country] {
+		return false
+	}
+	if len(r.allow) > 0 && !containsAddr(r.allow, addr) {
+		return false
+	}
+	if len(r.allowCountries) > 0 && !r.allowCountries[country] {
+		return false
+	}
+	return true
+}
+
+// group returns the route group with the longest prefix matching a path, or
+// nil.
+func (p *ipPolicy) group(path string) *compiledIPGroup {
+	for _, route := range p.routes {
+		if strings.HasPrefix(path, route.prefix) {
+			return route.group
+		}
+	}
+	return nil
+}
+
+// clientAddr returns the address of the client that made a request. The
+// X-Forwarded-For chain is walked right to left while the hop is a trusted
+// proxy; the first untrusted hop is the client.
+func (p *ipPolicy) clientAddr(r *http.Request) (netip.Addr, bool) {
+	addr, ok := parseAddr(r.RemoteAddr)
+	if !ok {
+		return netip.Addr{}, false
+	}
+	if !containsAddr(p.trusted, addr) {
+		return addr, true
+	}
+
+	hops := strings.Split(strings.Join(r.Header.Values("X-Forwarded-For"), ","), ",")
+	for i := len(hops) - 1; i >= 0; i-- {
+		hop, ok := parseAddr(strings.TrimSpace(hops[i]))
+		if !ok {
+			break
+		}
+		addr = hop
+		if !containsAddr(p.trusted, hop) {
+			break
+		}
+	}
+	return addr, true
+}
+
+// clientCountryHeader carries the client's country to the backends. Any value
+// sent by the client is replaced.
+const clientCountryHeader = "X-Client-Country"
+
+// country returns the ISO country code of a client, or "" when unknown. The
+// country header is only believed from a trusted proxy.
+func (p *ipPolicy) country(r *http.Request, addr netip.Addr) string {
+	if p.countryHeader != "" {
+		if peer, ok := parseAddr(r.RemoteAddr); ok && containsAddr(p.trusted, peer) {
+			if country := strings.ToUpper(strings.TrimSpace(r.Header.Get(p.countryHeader))); country != "" && country != "XX" {
+				return country
+			}
+		}
+	}
+	if p.countries != nil {
+		return p.countries.lookup(addr)
+	}
+	return ""
+}
+
+// ============================================================================
+// Filter
+// ============================================================================
+
+// IPFilter enforces the IP filtering policy and the dynamic deny-list.
+type IPFilter struct {
+	redis *database.RedisClient
+	log   *logger.Logger
+
+	mu     sync.RWMutex
+	policy *ipPolicy
+	denied []deniedPrefix
+}
+
+type deniedPrefix struct {
+	prefix    netip.Prefix
+	expiresAt *time.Time
+}
+
+// NewIPFilter compiles the policy. The dynamic deny-list is kept in redis.
+func NewIPFilter(cfg *IPFilterConfig, redis *database.RedisClient, log *logger.Logger) (*IPFilter, error) {
+	policy, err := compileIPPolicy(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return &IPFilter{redis: redis, log: log, policy: policy}, nil
+}
+
+// Apply replaces the policy.
+func (f *IPFilter) Apply(cfg *IPFilterConfig) error {
+	policy, err := compileIPPolicy(cfg)
+	if err != nil {
+		return err
+	}
+	f.mu.Lock()
+	f.policy = policy
+	f.mu.Unlock()
+	return nil
+}
+
+// Middleware resolves the client address, refuses clients the policy or the
+// deny-list rejects with 403, and passes the others on with RemoteAddr set
+// to the client address. X-Forwarded-For is dropped, so the proxy forwards
+// only the resolved client to the backends, along with its country in
+// X-Client-Country when GeoIP is configured.
+func (f *IPFilter) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		f.mu.RLock()
+		policy, denied := f.policy, f.denied
+		f.mu.RUnlock()
+
+		addr, ok := policy.clientAddr(r)
+		if !ok {
+			response.Error(w, apperrors.ErrForbidden("Access denied"))
+			return
+		}
+
+		now := time.Now()
+		for _, entry := range denied {
+			if entry.prefix.Contains(addr) && (entry.expiresAt == nil || now.Before(*entry.expiresAt)) {
+				f.deny(w, r, addr, "deny_list")
+				return
+			}
+		}
+
+		country := ""
+		group := policy.group(r.URL.Path)
+		if policy.countries != nil || policy.countryHeader != "" {
+			country = policy.country(r, addr)
+		}
+		if !policy.global.permits(addr, country) {
+			f.deny(w, r, addr, "global")
+			return
+		}
+		if group != nil && !group.rules.permits(addr, country) {
+			f.deny(w, r, addr, group.name)
+			return
+		}
+
+		r.RemoteAddr = net.JoinHostPort(addr.String(), "0")
+		r.Header.Del("X-Forwarded-For")
+		r.Header.Set("X-Real-IP", addr.String())
+		r.Header.Del(clientCountryHeader)
+		if country != "" {
+			r.Header.Set(clientCountryHeader, country)
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+func (f *IPFilter) deny(w http.ResponseWriter, r *http.Request, addr netip.Addr, rule string) {
+	f.log.Warn().
+		Str("client_ip", addr.String()).
+		Str("path", r.URL.Path).
+		Str("rule", rule).
+		Msg("Request denied by IP filter")
+	response.Error(w, apperrors.ErrForbidden("Access denied from this address"))
+}
+
+// ============================================================================
+// Dynamic Deny-List
+// ============================================================================
+
+// DenyListEntry is a dynamically denied address or CIDR.
+type DenyListEntry struct {
+	CIDR      string     `json:"cidr"`
+	Reason    string     `json:"reason,omitempty"`
+	CreatedBy string     `json:"created_by,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+}
+
+// Start loads the deny-list and keeps it in sync with Redis until ctx is
+// done, reloading when any gateway announces a change and periodically.
+func (f *IPFilter) Start(ctx context.Context) {
+	if err := f.reload(ctx); err != nil {
+		f.log.Warn().Err(err).Msg("Failed to load IP deny-list")
+	}
+
+	pubsub := f.redis.Subscribe(ctx, denyListChannel)
+	go func() {
+		defer pubsub.Close()
+		ticker := time.NewTicker(denyListRefreshInterval)
+		defer ticker.Stop()
+		changes := pubsub.Channel()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-changes:
+			case <-ticker.C:
+			}
+			if err := f.reload(ctx); err != nil {
+				f.log.Warn().Err(err).Msg("Failed to reload IP deny-list")
+			}
+		}
+	}()
+}
+
+// Entries returns the current deny-list entries, dropping expired ones from
+// Redis.
+func (f *IPFilter) Entries(ctx context.Context) ([]DenyListEntry, error) {
+	raw, err := f.redis.HGetAll(ctx, denyListKey)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	entries := make([]DenyListEntry, 0, len(raw))
+	var expired []string
+	for cidr, value := range raw {
+		var entry DenyListEntry
+		if err := json.Unmarshal([]byte(value), &entry); err != nil {
+			f.log.Warn().Err(err).Str("cidr", cidr).Msg("Skipping malformed IP deny-list entry")
+			continue
+		}
+		if entry.ExpiresAt != nil && !now.Before(*entry.ExpiresAt) {
+			expired = append(expired, cidr)
+			continue
+		}
+		entries = append(entries, entry)
+	}
+	if len(expired) > 0 {
+		_ = f.redis.HDel(ctx, denyListKey, expired...)
+	}
+
+	sort.Slice(entries, func(i, j int) bool { return entries[i].CreatedAt.Before(entries[j].CreatedAt) })
+	return entries, nil
+}
+
+// Deny adds an address or CIDR to the deny-list, replacing an existing
+// entry for it. A zero ttl denies it until it is removed.
+func (f *IPFilter) Deny(ctx context.Context, cidr, reason, createdBy string, ttl time.Duration) (*DenyListEntry, error) {
+	prefix, err := parsePrefix(cidr)
+	if err != nil {
+		return nil, err
+	}
+
+	entry := &DenyListEntry{
+		CIDR:      prefix.String(),
+		Reason:    reason,
+		CreatedBy: createdBy,
+		CreatedAt: time.Now().UTC(),
+	}
+	if ttl > 0 {
+		expiresAt := entry.CreatedAt.Add(ttl)
+		entry.ExpiresAt = &expiresAt
+	}
+
+	// HSet stores the entry as JSON
+	if err := f.redis.HSet(ctx, denyListKey, entry.CIDR, entry); err != nil {
+		return nil, err
+	}
+	f.announce(ctx)
+	return entry, nil
+}
+
+// Allow removes an address or CIDR from the deny-list. It reports false when
+// it was not denied.
+func (f *IPFilter) Allow(ctx context.Context, cidr string) (bool, error) {
+	prefix, err := parsePrefix(cidr)
+	if err != nil {
+		return false, err
+	}
+
+	removed, err := f.redis.Client().HDel(ctx, denyListKey, prefix.String()).Result()
+	if err != nil {
+		return false, err
+	}
+	if removed > 0 {
+		f.announce(ctx)
+	}
+	return removed > 0, nil
+}
+
+// announce reloads the local deny-list and tells the other gateways to.
+func (f *IPFilter) announce(ctx context.Context) {
+	if err := f.reload(ctx); err != nil {
+		f.log.Warn().Err(err).Msg("Failed to reload IP deny-list")
+	}
+	if err := f.redis.Publish(ctx, denyListChannel, time.Now().Unix()); err != nil {
+		f.log.Warn().Err(err).Msg("Failed to announce IP deny-list change")
+	}
+}
+
+func (f *IPFilter) reload(ctx context.Context) error {
+	entries, err := f.Entries(ctx)
+	if err != nil {
+		return err
+	}
+
+	denied := make([]deniedPrefix, 0, len(entries))
+	for _, entry := range entries {
+		prefix, err := parsePrefix(entry.CIDR)
+		if err != nil {
+			continue
+		}
+		denied = append(denied, deniedPrefix{prefix: prefix, expiresAt: entry.ExpiresAt})
+	}
+
+	f.mu.Lock()
+	f.denied = denied
+	f.mu.Unlock()
+	return nil
+}
+
+// ============================================================================
+// Admin
+// ============================================================================
+
+// denyRequest is the body of POST /admin/ip-deny-list.
+type denyRequest struct {
+	CIDR   string `json:"cidr"`
+	Reason string `json:"reason"`
+	// TTL is how long the entry lasts, e.g. "24h"; empty means until removed.
+	TTL string `json:"ttl"`
+}
+
+// denyListHandler lists the deny-list entries.
+func denyListHandler(filter *IPFilter) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		entries, err := filter.Entries(r.Context())
+		if err != nil {
+			response.Error(w, apperrors.ErrInternalWrap(err, "Failed to load IP deny-list"))
+			return
+		}
+		response.OK(w, entries)
+	}
+}
+
+// denyHandler adds an address or CIDR to the deny-list.
+func denyHandler(filter *IPFilter) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req denyRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			response.Error(w, apperrors.ErrBadRequest("Invalid request body"))
+			return
+		}
+		var ttl time.Duration
+		if req.TTL != "" {
+			var err error
+			if ttl, err = time.ParseDuration(req.TTL); err != nil || ttl <= 0 {
+				response.Error(w, apperrors.ErrValidation("ttl must be a positive duration such as 24h"))
+				return
+			}
+		}
+
+		entry, err := filter.Deny(r.Context(), req.CIDR, req.Reason, middleware.UserIDFromContext(r.Context()), ttl)
+		if err != nil {
+			response.Error(w, denyListError(err))
+			return
+		}
+		response.Created(w, entry)
+	}
+}
+
+// allowHandler removes the entry named by the cidr query parameter.
+func allowHandler(filter *IPFilter) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		removed, err := filter.Allow(r.Context(), r.URL.Query().Get("cidr"))
+		if err != nil {
+			response.Error(w, denyListError(err))
+			return
+		}
+		if !removed {
+			response.Error(w, apperrors.ErrNotFound("IP deny-list entry"))
+			return
+		}
+		response.NoContent(w)
+	}
+}
+
+func denyListError(err error) error {
+	var parseErr *addrParseError
+	if errors.As(err, &parseErr) {
+		return apperrors.ErrValidation(parseErr.Error())
+	}
+	return apperrors.ErrInternalWrap(err, "Failed to update IP deny-list")
+}
+
+// ============================================================================
+// Hot Reload
+// ============================================================================
+
+// WatchIPFilterConfig reloads the IP filtering policy whenever the file
+// changes. Invalid configs are logged and ignored so the current policy stays
+// in place.
+func WatchIPFilterConfig(path string, filter *IPFilter, log *logger.Logger) {
+	if _, err := os.Stat(path); err != nil {
+		return
+	}
+
+	v := viper.New()
+	v.SetConfigFile(path)
+	v.SetConfigType("yaml")
+	v.OnConfigChange(func(e fsnotify.Event) {
+		cfg, err := LoadIPFilterConfig(path)
+		if err == nil {
+			err = filter.Apply(cfg)
+		}
+		if err != nil {
+			log.Error().Err(err).Str("path", path).Msg("Failed to reload IP filter config")
+			return
+		}
+		log.Info().Str("path", path).Int("groups", len(cfg.Groups)).Msg("IP filter config reloaded")
+	})
+	v.WatchConfig()
+}
+
+// ============================================================================
+// GeoIP
+// ============================================================================
+
+// countryDatabase maps address ranges to ISO country codes.
+type countryDatabase struct {
+	ranges []countryRange
+}
+
+type countryRange struct {
+	prefix  netip.Prefix
+	country string
+}
+
+// loadCountryDatabase reads a CSV file of "network,country" lines. A header
+// line and lines that do not parse are skipped.
+func loadCountryDatabase(path string) (*countryDatabase, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	db := &countryDatabase{}
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		network, country, ok := strings.Cut(scanner.Text(), ",")
+		if !ok {
+			continue
+		}
+		prefix, err := netip.ParsePrefix(strings.TrimSpace(network))
+		if err != nil {
+			continue
+		}
+		country = strings.ToUpper(strings.Trim(strings.TrimSpace(country), `"`))
+		if country != "" {
+			db.ranges = append(db.ranges, countryRange{prefix: prefix.Masked(), country: country})
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if len(db.ranges) == 0 {
+		return nil, fmt.Errorf("%s has no country ranges", path)
+	}
+
+	// Sorted by start address, most specific last, for the binary search
+	sort.Slice(db.ranges, func(i, j int) bool {
+		a, b := db.ranges[i].prefix, db.ranges[j].prefix
+		if c := a.Addr().Compare(b.Addr()); c != 0 {
+			return c < 0
+		}
+		return a.Bits() < b.Bits()
+	})
+	return db, nil
+}
+
+// lookup returns the country of addr, or "".
+func (db *countryDatabase) lookup(addr netip.Addr) string {
+	addr = addr.Unmap()
+	// The candidate ranges start at or before addr; walk back from the last
+	// one to find the most specific range containing it
+	i := sort.Search(len(db.ranges), func(i int) bool { return db.ranges[i].prefix.Addr().Compare(addr) > 0 })
+	for j := i - 1; j >= 0 && i-j <= 32; j-- {
+		if db.ranges[j].prefix.Contains(addr) {
+			return db.ranges[j].country
+		}
+	}
+	return ""
+}
+
+// ============================================================================
+// Helpers
+// ============================================================================
+
+// addrParseError reports an invalid address or CIDR.
+type addrParseError struct {
+	value string
+}
+
+func (e *addrParseError) Error() string {
+	return fmt.Sprintf("invalid IP address or CIDR %q", e.value)
+}
+
+// parsePrefix parses an address or CIDR; an address becomes a single-address
+// prefix.
+func parsePrefix(value string) (netip.Prefix, error) {
+	value = strings.TrimSpace(value)
+	if strings.Contains(value, "/") {
+		prefix, err := netip.ParsePrefix(value)
+		if err != nil {
+			return netip.Prefix{}, &addrParseError{value: value}
+		}
+		return prefix.Masked(), nil
+	}
+	addr, err := netip.ParseAddr(value)
+	if err != nil {
+		return netip.Prefix{}, &addrParseError{value: value}
+	}
+	addr = addr.Unmap()
+	return netip.PrefixFrom(addr, addr.BitLen()), nil
+}
+
+func parsePrefixes(values []string) ([]netip.Prefix, error) {
+	prefixes := make([]netip.Prefix, 0, len(values))
+	for _, value := range values {
+		prefix, err := parsePrefix(value)
+		if err != nil {
+			return nil, err
+		}
+		prefixes = append(prefixes, prefix)
+	}
+	return prefixes, nil
+}
+
+// parseAddr parses an address with or without a port.
+func parseAddr(value string) (netip.Addr, bool) {
+	if host, _, err := net.SplitHostPort(value); err == nil {
+		value = host
+	}
+	addr, err := netip.ParseAddr(value)
+	if err != nil {
+		return netip.Addr{}, false
+	}
+	return addr.Unmap(), true
+}
+
+func containsAddr(prefixes []netip.Prefix, addr netip.Addr) bool {
+	for _, prefix := range prefixes {
+		if prefix.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
+
+func countrySet(countries []string) map[string]bool {
+	set := make(map[string]bool, len(countries))
+	for _, country := range countries {
+		set[strings.ToUpper(strings.TrimSpace(country))] = true
+	}
+	return set
+}
@@ -0,0 +1,185 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/kilang-desa-murni/crm/pkg/logger"
+)
+
+func newTestIPFilter(t *testing.T, cfg *IPFilterConfig) *IPFilter {
+	t.Helper()
+	filter, err := NewIPFilter(cfg, nil, logger.New(logger.Config{Level: "error"}))
+	if err != nil {
+		t.Fatalf("NewIPFilter() error = %v", err)
+	}
+	return filter
+}
+
+func TestIPPolicy_ClientAddr(t *testing.T) {
+	tests := []struct {
+		name       string
+		trusted    []string
+		remoteAddr string
+		forwarded  []string
+		want       string
+	}{
+		{
+			name:       "no trusted proxies ignores the header",
+			remoteAddr: "198.51.100.7:5123",
+			forwarded:  []string{"203.0.113.9"},
+			want:       "198.51.100.7",
+		},
+		{
+			name:       "untrusted peer ignores the header",
+			trusted:    []string{"10.0.0.0/8"},
+			remoteAddr: "198.51.100.7:5123",
+			forwarded:  []string{"203.0.113.9"},
+			want:       "198.51.100.7",
+		},
+		{
+			name:       "trusted peer without the header",
+			trusted:    []string{"10.0.0.0/8"},
+			remoteAddr: "10.0.0.2:5123",
+			want:       "10.0.0.2",
+		},
+		{
+			name:       "client behind one proxy",
+			trusted:    []string{"10.0.0.0/8"},
+			remoteAddr: "10.0.0.2:5123",
+			forwarded:  []string{"198.51.100.7"},
+			want:       "198.51.100.7",
+		},
+		{
+			name:       "spoofed leftmost entry",
+			trusted:    []string{"10.0.0.0/8"},
+			remoteAddr: "10.0.0.2:5123",
+			forwarded:  []string{"127.0.0.1, 198.51.100.7"},
+			want:       "198.51.100.7",
+		},
+		{
+			name:       "trusted hops across several headers",
+			trusted:    []string{"10.0.0.0/8"},
+			remoteAddr: "10.0.0.2:5123",
+			forwarded:  []string{"203.0.113.1, 198.51.100.7", "10.1.0.4"},
+			want:       "198.51.100.7",
+		},
+		{
+			name:       "garbage hop stops the walk",
+			trusted:    []string{"10.0.0.0/8"},
+			remoteAddr: "10.0.0.2:5123",
+			forwarded:  []string{"198.51.100.7, not-an-ip, 10.1.0.4"},
+			want:       "10.1.0.4",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			policy, err := compileIPPolicy(&IPFilterConfig{TrustedProxies: tt.trusted})
+			if err != nil {
+				t.Fatalf("compileIPPolicy() error = %v", err)
+			}
+
+			r := httptest.NewRequest(http.MethodGet, "/api/v1/leads", nil)
+			r.RemoteAddr = tt.remoteAddr
+			for _, value := range tt.forwarded {
+				r.Header.Add("X-Forwarded-For", value)
+			}
+
+			addr, ok := policy.clientAddr(r)
+			if !ok || addr.String() != tt.want {
+				t.Errorf("clientAddr() = %v, %v, want %s", addr, ok, tt.want)
+			}
+		})
+	}
+}
+
+func TestIPFilter_Middleware(t *testing.T) {
+	cfg := &IPFilterConfig{
+		TrustedProxies: []string{"10.0.0.0/8"},
+		Global:         IPRules{Deny: []string{"192.0.2.0/24"}},
+		Groups: []IPRouteGroup{
+			{
+				Name:         "admin",
+				PathPrefixes: []string{"/admin/"},
+				IPRules:      IPRules{Allow: []string{"203.0.113.0/28"}},
+			},
+		},
+	}
+
+	tests := []struct {
+		name       string
+		path       string
+		forwarded  string
+		wantStatus int
+	}{
+		{"allowed client", "/api/v1/leads", "198.51.100.7", http.StatusOK},
+		{"denied CIDR", "/api/v1/leads", "192.0.2.44", http.StatusForbidden},
+		{"spoofed allowed address before a denied client", "/api/v1/leads", "203.0.113.2, 192.0.2.44", http.StatusForbidden},
+		{"group allow CIDR", "/admin/ip-deny-list", "203.0.113.2", http.StatusOK},
+		{"outside the group allow CIDR", "/admin/ip-deny-list", "203.0.113.20", http.StatusForbidden},
+		{"spoofed allowed address outside the group", "/admin/ip-deny-list", "203.0.113.2, 198.51.100.7", http.StatusForbidden},
+	}
+
+	filter := newTestIPFilter(t, cfg)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotRealIP string
+			handler := filter.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				gotRealIP = r.Header.Get("X-Real-IP")
+				if r.Header.Get("X-Forwarded-For") != "" {
+					t.Error("expected X-Forwarded-For to be dropped")
+				}
+			}))
+
+			r := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			r.RemoteAddr = "10.0.0.2:5123"
+			r.Header.Set("X-Forwarded-For", tt.forwarded)
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, r)
+
+			if w.Code != tt.wantStatus {
+				t.Errorf("expected %d, got %d", tt.wantStatus, w.Code)
+			}
+			if tt.wantStatus == http.StatusOK && gotRealIP == "" {
+				t.Error("expected X-Real-IP to carry the client address")
+			}
+		})
+	}
+}
+
+func TestIPPolicy_GroupLongestPrefix(t *testing.T) {
+	policy, err := compileIPPolicy(&IPFilterConfig{
+		Groups: []IPRouteGroup{
+			{Name: "admin", PathPrefixes: []string{"/admin/"}},
+			{Name: "deny-list", PathPrefixes: []string{"/admin/ip-deny-list"}},
+			{Name: "api", PathPrefixes: []string{"/api/", "/api/v1/webhooks/"}},
+		},
+	})
+	if err != nil {
+		t.Fatalf("compileIPPolicy() error = %v", err)
+	}
+
+	tests := []struct {
+		path string
+		want string
+	}{
+		{"/admin/slo/rules", "admin"},
+		{"/admin/ip-deny-list", "deny-list"},
+		{"/api/v1/webhooks/stripe", "api"},
+		{"/health", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			name := ""
+			if group := policy.group(tt.path); group != nil {
+				name = group.name
+			}
+			if name != tt.want {
+				t.Errorf("group(%q) = %q, want %q", tt.path, name, tt.want)
+			}
+		})
+	}
+}
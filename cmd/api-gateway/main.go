@@ -5,22 +5,34 @@ package main
 
 import (
 	"context"
+	"database/sql"
+	"errors"
 	"fmt"
-	"io"
 	"net/http"
 	"net/http/httputil"
 	"net/url"
 	"os"
-	"os/signal"
-	"syscall"
+	"path/filepath"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/kilang-desa-murni/crm/pkg/auth"
+	"github.com/kilang-desa-murni/crm/pkg/capture"
 	"github.com/kilang-desa-murni/crm/pkg/config"
 	"github.com/kilang-desa-murni/crm/pkg/database"
+	apperrors "github.com/kilang-desa-murni/crm/pkg/errors"
+	"github.com/kilang-desa-murni/crm/pkg/events"
+	"github.com/kilang-desa-murni/crm/pkg/featureflag"
+	"github.com/kilang-desa-murni/crm/pkg/i18n"
+	"github.com/kilang-desa-murni/crm/pkg/lifecycle"
 	"github.com/kilang-desa-murni/crm/pkg/logger"
 	"github.com/kilang-desa-murni/crm/pkg/middleware"
+	"github.com/kilang-desa-murni/crm/pkg/resilience"
 	"github.com/kilang-desa-murni/crm/pkg/response"
+	"github.com/kilang-desa-murni/crm/pkg/security"
+	"github.com/kilang-desa-murni/crm/pkg/serviceauth"
+	"github.com/kilang-desa-murni/crm/pkg/slo"
 	"github.com/kilang-desa-murni/crm/pkg/tracer"
 )
 
@@ -53,9 +65,10 @@ func main() {
 
 	// Initialize logger
 	log := logger.New(logger.Config{
-		Level:  cfg.Logger.Level,
-		Format: cfg.Logger.Format,
-		Caller: cfg.Logger.Caller,
+		Level:    cfg.Logger.Level,
+		Format:   cfg.Logger.Format,
+		Caller:   cfg.Logger.Caller,
+		Shipping: cfg.Logger.Shipping,
 	})
 	log = log.With().Service(cfg.App.Name).Logger()
 	logger.SetGlobal(log)
@@ -66,23 +79,40 @@ func main() {
 		Str("git_commit", GitCommit).
 		Msg("Starting API Gateway")
 
+	// Components are stopped in the reverse order they are registered in
+	lc := lifecycle.New(log)
+
+	// Wait for the dependencies to come up rather than crash-looping
+	startup := lifecycle.NewStartup(cfg.Startup, log)
+	startup.ServeHealth(fmt.Sprintf("%s:%d", cfg.Server.Host, cfg.Server.Port), Version)
+
 	// Initialize tracer
 	tr, err := tracer.New(&cfg.Tracer, log)
 	if err != nil {
 		log.Fatal().Err(err).Msg("Failed to initialize tracer")
 	}
-	defer tr.Close(context.Background())
+	lc.OnShutdown("tracer", tr.Close)
 
 	// Initialize Redis for rate limiting
-	redis, err := database.NewRedis(&cfg.Redis, log)
+	redis, err := lifecycle.Await(startup, "redis", func() (*database.RedisClient, error) {
+		return database.NewRedis(&cfg.Redis, log)
+	})
 	if err != nil {
 		log.Fatal().Err(err).Msg("Failed to connect to Redis")
 	}
-	defer redis.Close()
+	lc.OnClose("redis", redis)
 
 	// Initialize JWT Manager for token validation
 	jwtManager := auth.NewJWTManager(&cfg.JWT)
 
+	// Pick up rotated JWT keys when the secrets are refreshed
+	cfg.WatchSecrets(lc.Context(), func(updated *config.Config) {
+		jwtManager.UpdateKeys(updated.JWT)
+		log.Info().Msg("JWT keys refreshed")
+	}, func(err error) {
+		log.Warn().Err(err).Msg("Failed to refresh secrets")
+	})
+
 	// Get service URLs from environment
 	serviceURLs := ServiceURLs{
 		IAM:          getEnv("IAM_SERVICE_URL", "http://localhost:8081"),
@@ -91,20 +121,226 @@ func main() {
 		Notification: getEnv("NOTIFICATION_SERVICE_URL", "http://localhost:8084"),
 	}
 
-	// Create reverse proxies for each service
-	iamProxy := createReverseProxy(serviceURLs.IAM, log)
-	customerProxy := createReverseProxy(serviceURLs.Customer, log)
-	salesProxy := createReverseProxy(serviceURLs.Sales, log)
-	notificationProxy := createReverseProxy(serviceURLs.Notification, log)
+	// Build the routing table; the environment URLs are the static fallback
+	routingCtx := lc.Context()
+
+	staticRoutes := staticRoutingConfig(serviceURLs)
+	routingConfigPath := getEnv("GATEWAY_ROUTING_CONFIG", defaultRoutingConfigPath)
+	routingConfig, err := LoadRoutingConfig(routingConfigPath, staticRoutes)
+	if err != nil {
+		log.Fatal().Err(err).Str("path", routingConfigPath).Msg("Failed to load routing config")
+	}
+	routes, err := NewRoutingTable(routingCtx, routingConfig, log)
+	if err != nil {
+		log.Fatal().Err(err).Str("path", routingConfigPath).Msg("Invalid routing config")
+	}
+	routes.Start(routingCtx)
+	lc.OnShutdown("routing table", func(context.Context) error {
+		routes.Stop()
+		return nil
+	})
+	WatchRoutingConfig(routingCtx, routingConfigPath, staticRoutes, routes, log)
+
+	// Create reverse proxies for each service, each behind its own circuit breaker
+	breakers := newBreakerRegistry(cfg.CircuitBreaker, log)
+	iamProxy := createServiceProxy("iam", routes, breakers, log)
+	customerProxy := createServiceProxy("customer", routes, breakers, log)
+	salesProxy := createServiceProxy("sales", routes, breakers, log)
+	notificationProxy := createServiceProxy("notification", routes, breakers, log)
+
+	// GraphQL endpoint stitching the services' data into one query
+	graphQLHandler, graphQLSchemaHandler, err := newGraphQLHandler(routes, log)
+	if err != nil {
+		log.Fatal().Err(err).Msg("Invalid GraphQL config")
+	}
+
+	// Cookie sessions for the web app; both modes run side by side while
+	// clients migrate from bearer tokens
+	sessionConfig, err := loadSessionConfig(cfg)
+	if err != nil {
+		log.Fatal().Err(err).Msg("Invalid session config")
+	}
+	authHandler := newSessionHandler(iamProxy, sessionConfig, log)
+	log.Info().Str("mode", string(sessionConfig.Mode)).Msg("Session mode configured")
+
+	// Calls to the IAM service's internal endpoints carry a service token
+	serviceTokens := serviceauth.NewClient(cfg.ServiceAuth, "api-gateway")
+	iamTransport := serviceTokens.Transport("iam-service", nil)
+
+	// Access tokens are JWTs validated here, or opaque tokens exchanged for
+	// their claims at the IAM service. Either way backend services are sent
+	// the caller's signed identity once an identity secret is configured
+	validateToken := middleware.JWTValidator(jwtManager)
+	if cfg.JWT.TokenMode == config.TokenModeOpaque {
+		introspectionURL := getEnv("GATEWAY_INTROSPECTION_URL", serviceURLs.IAM+"/internal/auth/introspect")
+		validateToken = auth.NewIntrospector(introspectionURL, iamTransport, redis, cfg.JWT.IntrospectionCacheTTL).ValidateAccessToken
+	}
+	log.Info().Str("mode", cfg.JWT.TokenMode).Msg("Token mode configured")
+
+	// CORS policy of the environment, extended with the origins tenants
+	// allow for their own users
+	var originSource middleware.TenantOriginSource
+	if cfg.CORS.TenantOriginsURL != "" {
+		origins := newTenantOrigins(cfg.CORS.TenantOriginsURL, iamTransport, log)
+		origins.Start(routingCtx, cfg.CORS.TenantOriginsRefresh)
+		originSource = origins
+	}
+	corsPolicy := middleware.NewCORSPolicy(cfg.CORS, cfg.App.Environment, originSource)
+
+	// Custom domains of white-labeled tenants; requests to a custom domain
+	// are resolved to its tenant
+	domains := newTenantDomains(getEnv("GATEWAY_TENANT_DOMAINS_URL", ""), iamTransport, log)
+	if domains.url != "" {
+		refresh, err := time.ParseDuration(getEnv("GATEWAY_TENANT_DOMAINS_REFRESH", "1m"))
+		if err != nil {
+			log.Fatal().Err(err).Msg("Invalid GATEWAY_TENANT_DOMAINS_REFRESH")
+		}
+		domains.Start(routingCtx, refresh)
+	}
+
+	// IP allow/deny policy per route group, plus the deny-list kept in Redis
+	ipFilterConfigPath := getEnv("GATEWAY_IP_FILTER_CONFIG", defaultIPFilterConfigPath)
+	ipFilterConfig, err := LoadIPFilterConfig(ipFilterConfigPath)
+	if err != nil {
+		log.Fatal().Err(err).Str("path", ipFilterConfigPath).Msg("Failed to load IP filter config")
+	}
+	ipFilter, err := NewIPFilter(ipFilterConfig, redis, log)
+	if err != nil {
+		log.Fatal().Err(err).Str("path", ipFilterConfigPath).Msg("Invalid IP filter config")
+	}
+	ipFilter.Start(routingCtx)
+	WatchIPFilterConfig(ipFilterConfigPath, ipFilter, log)
+
+	// Feature flags are managed here and read by every service from the
+	// shared store
+	var flagDB *sql.DB
+	if cfg.FeatureFlags.Store == "postgres" {
+		db, err := lifecycle.Await(startup, "postgresql", func() (*database.PostgresDB, error) {
+			return database.NewPostgres(&cfg.Database, log)
+		})
+		if err != nil {
+			log.Fatal().Err(err).Msg("Failed to connect to PostgreSQL")
+		}
+		lc.OnClose("postgres", db)
+		flagDB = db.DB
+	}
+	flagStore, err := featureflag.NewStore(cfg.FeatureFlags, redis, flagDB)
+	if err != nil {
+		log.Fatal().Err(err).Msg("Invalid feature flag config")
+	}
+	flags := featureflag.NewClient(flagStore, log)
+	flags.Start(routingCtx, cfg.FeatureFlags.RefreshInterval)
 
-	// Create rate limiter
+	// Create rate limiter; requests are limited per client address before
+	// authentication and per tenant after it
 	rateLimitConfig := middleware.RateLimitConfig{
-		Requests: 100,
-		Window:   time.Minute,
-		KeyFunc:  middleware.TenantKeyFunc,
+		Requests:  cfg.RateLimit.Requests,
+		Window:    cfg.RateLimit.Window,
+		Algorithm: cfg.RateLimit.Algorithm,
+		Burst:     cfg.RateLimit.Burst,
 	}
 	rateLimiter := middleware.NewRedisRateLimiter(redis, rateLimitConfig)
 
+	// Apply log level, rate limit and circuit breaker changes from the config
+	// file, SIGHUP or the admin API without a restart
+	runtime := config.NewRuntime(cfg)
+	runtime.Watch(routingCtx, func(err error) {
+		log.Warn().Err(err).Msg("Failed to reload configuration")
+	})
+	go func() {
+		for tunables := range runtime.Subscribe(routingCtx) {
+			if err := log.SetLevel(tunables.LogLevel); err != nil {
+				log.Warn().Err(err).Msg("Invalid log level")
+			}
+			rateLimiter.SetLimit(tunables.RateLimit.Requests, tunables.RateLimit.Window)
+			breakers.Reconfigure(breakerConfig(tunables.CircuitBreaker, log))
+			log.Info().Interface("tunables", tunables).Msg("Runtime configuration updated")
+		}
+	}()
+
+	// Imports, exports and bulk operations are capped per tenant; requests
+	// over the caps are queued and answered with a job to follow
+	heavyConfig, err := loadHeavyConfig()
+	if err != nil {
+		log.Fatal().Err(err).Msg("Invalid heavy request config")
+	}
+	heavy := newHeavyRequests(heavyConfig, redis, log)
+
+	// Replay retried POSTs that carry an Idempotency-Key
+	idempotencyStore := middleware.NewRedisIdempotencyStore(redis)
+
+	// Load request/response transformation policy
+	transformConfigPath := getEnv("GATEWAY_TRANSFORM_CONFIG", defaultTransformConfigPath)
+	transformConfig, err := LoadTransformConfig(transformConfigPath)
+	if err != nil {
+		log.Fatal().Err(err).Str("path", transformConfigPath).Msg("Failed to load transform config")
+	}
+	transforms, err := NewTransformEngine(transformConfig)
+	if err != nil {
+		log.Fatal().Err(err).Str("path", transformConfigPath).Msg("Invalid transform config")
+	}
+	log.Info().
+		Str("path", transformConfigPath).
+		Int("routes", len(transformConfig.Routes)).
+		Msg("Transform policy loaded")
+
+	// Route each public API version to the backends, translating the
+	// endpoints that changed, and announce the retirement of old versions
+	versionConfigPath := getEnv("GATEWAY_VERSIONS_CONFIG", defaultVersionConfigPath)
+	versionConfig, err := LoadVersionConfig(versionConfigPath)
+	if err != nil {
+		log.Fatal().Err(err).Str("path", versionConfigPath).Msg("Failed to load version config")
+	}
+	versions, err := NewVersionRouter(versionConfig)
+	if err != nil {
+		log.Fatal().Err(err).Str("path", versionConfigPath).Msg("Invalid version config")
+	}
+	log.Info().
+		Str("path", versionConfigPath).
+		Int("versions", len(versionConfig.Versions)).
+		Msg("API version policy loaded")
+
+	// Track the service level objectives of the route groups and email the
+	// recipients when an error budget burns too fast
+	sloConfigPath := getEnv("GATEWAY_SLO_CONFIG", defaultSLOConfigPath)
+	sloConfig, err := slo.LoadConfig(sloConfigPath)
+	if err != nil {
+		log.Fatal().Err(err).Str("path", sloConfigPath).Msg("Failed to load SLO config")
+	}
+	sloTracker := slo.NewTracker(sloConfig)
+	if len(sloConfig.Objectives) > 0 && len(sloConfig.Recipients) > 0 {
+		eventBus, err := events.NewRabbitMQEventBus(&cfg.RabbitMQ, log)
+		if err != nil {
+			log.Warn().Err(err).Msg("Failed to connect to RabbitMQ, SLO alerts are only exported as metrics")
+		} else {
+			lc.OnClose("event bus", eventBus)
+			sloTracker.Start(routingCtx, sloAlertNotifier(eventBus, sloConfig.Recipients), func(err error) {
+				log.Error().Err(err).Msg("Failed to publish SLO alert")
+			})
+		}
+	}
+	log.Info().
+		Str("path", sloConfigPath).
+		Int("objectives", len(sloConfig.Objectives)).
+		Msg("SLO policy loaded")
+
+	// Opt-in traffic capture: administrators record the sanitized requests
+	// of a tenant for a time window, to replay them against staging with
+	// traffic-replay. Captures go to an object storage bucket mounted at
+	// GATEWAY_CAPTURE_DIR
+	captureStore, err := capture.NewDirStore(getEnv("GATEWAY_CAPTURE_DIR", filepath.Join(os.TempDir(), "crm-traffic-captures")))
+	if err != nil {
+		log.Fatal().Err(err).Msg("Failed to initialize traffic capture storage")
+	}
+	captureBodyLimit, err := strconv.Atoi(getEnv("GATEWAY_CAPTURE_BODY_LIMIT", strconv.Itoa(capture.DefaultBodyLimit)))
+	if err != nil {
+		log.Fatal().Err(err).Msg("Invalid GATEWAY_CAPTURE_BODY_LIMIT")
+	}
+	captureSessions := capture.NewSessions(redis, log)
+	captureSessions.Start(routingCtx)
+	trafficRecorder := capture.NewRecorder(captureSessions, captureStore, captureSubject, captureBodyLimit, log)
+	trafficRecorder.Start(routingCtx)
+
 	// Create HTTP router
 	mux := http.NewServeMux()
 
@@ -120,17 +356,16 @@ func main() {
 			checks["redis"] = response.HealthCheck{Status: "healthy"}
 		}
 
-		// Check backend services
-		services := map[string]string{
-			"iam":          serviceURLs.IAM + "/health",
-			"customer":     serviceURLs.Customer + "/health",
-			"sales":        serviceURLs.Sales + "/health",
-			"notification": serviceURLs.Notification + "/health",
-		}
-
+		// Check backend services through the routing table
 		client := &http.Client{Timeout: 5 * time.Second}
-		for name, url := range services {
-			resp, err := client.Get(url)
+		for _, name := range []string{"iam", "customer", "sales", "notification"} {
+			target, err := routes.Pick(name)
+			if err != nil {
+				checks[name] = response.HealthCheck{Status: "unhealthy", Message: err.Error()}
+				continue
+			}
+
+			resp, err := client.Get(target.JoinPath("/health").String())
 			if err != nil {
 				checks[name] = response.HealthCheck{Status: "unhealthy", Message: err.Error()}
 			} else {
@@ -154,35 +389,104 @@ func main() {
 		response.Health(w, status, Version, time.Since(startTime), checks)
 	})
 
-	// Metrics endpoint (placeholder)
+	// Metrics endpoint
 	mux.HandleFunc("GET /metrics", func(w http.ResponseWriter, r *http.Request) {
-		w.Header().Set("Content-Type", "text/plain")
-		w.Write([]byte("# Metrics placeholder\n"))
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+		sloTracker.WriteMetrics(w)
+		versions.WriteMetrics(w)
+		heavy.WriteMetrics(w)
 	})
 
+	// Requests and tenants of each API version (admin only)
+	mux.Handle("GET /admin/api-versions", middleware.RequireRoles("admin")(versionUsageHandler(versions)))
+
+	// Routing table inspection (admin only)
+	mux.Handle("GET /admin/routing", middleware.RequireRoles("admin")(routingTableHandler(routes)))
+
+	// Dynamic IP deny-list management (admin only)
+	mux.Handle("GET /admin/ip-deny-list", middleware.RequireRoles("admin")(denyListHandler(ipFilter)))
+	mux.Handle("POST /admin/ip-deny-list", middleware.RequireRoles("admin")(denyHandler(ipFilter)))
+	mux.Handle("DELETE /admin/ip-deny-list", middleware.RequireRoles("admin")(allowHandler(ipFilter)))
+
+	// Feature flag management (admin only) and the caller's flags for the web app
+	mux.Handle("/admin/feature-flags/", middleware.RequireRoles("admin")(
+		http.StripPrefix("/admin/feature-flags", featureflag.NewAdminHandler(flagStore, flags, flagSubject))))
+	mux.Handle("GET /api/v1/feature-flags", featureflag.NewEvaluateHandler(flags, flagSubject))
+
+	// SLO status and the Prometheus alert rules of the policy (admin only)
+	mux.Handle("/admin/slo/", middleware.RequireRoles("admin")(
+		http.StripPrefix("/admin/slo", slo.NewAdminHandler(sloConfig, sloTracker))))
+
+	// Traffic capture sessions and the exchanges they captured (admin only)
+	mux.Handle("/admin/traffic-captures/", middleware.RequireRoles("admin")(
+		http.StripPrefix("/admin/traffic-captures", capture.NewAdminHandler(captureSessions, captureStore, middleware.UserIDFromContext))))
+
+	// Runtime configuration: view it and change tunables (admin only)
+	runtimeHandler := config.NewRuntimeHandler(cfg, runtime)
+	mux.Handle("GET /admin/config", middleware.RequireRoles("admin")(http.HandlerFunc(runtimeHandler.Get)))
+	mux.Handle("PATCH /admin/config", middleware.RequireRoles("admin")(http.HandlerFunc(runtimeHandler.Patch)))
+
 	// API Documentation endpoint
 	mux.HandleFunc("GET /api", func(w http.ResponseWriter, r *http.Request) {
 		response.OK(w, map[string]interface{}{
 			"name":    "CRM Kilang Desa Murni Batik API",
 			"version": Version,
+			"api_versions": versions.Versions(),
 			"endpoints": map[string]string{
 				"auth":         "/api/v1/auth/*",
 				"users":        "/api/v1/users/*",
+				"tenants":      "/api/v1/tenants/*",
 				"customers":    "/api/v1/customers/*",
 				"leads":        "/api/v1/leads/*",
 				"opportunities": "/api/v1/opportunities/*",
 				"pipelines":    "/api/v1/pipelines/*",
 				"deals":        "/api/v1/deals/*",
+				"reports":      "/api/v1/reports/*",
+				"currency":     "/api/v1/currency/*",
+				"tax":          "/api/v1/tax/*",
+				"opportunity-reasons": "/api/v1/opportunity-reasons/*",
+				"inbound-email": "/api/v1/inbound-email/*",
+				"events":       "/api/v1/events/*",
+				"comments":     "/api/v1/{leads,opportunities,customers}/{id}/comments/*",
+				"retention":     "/api/v1/retention/*",
+				"discount-approval-rules": "/api/v1/discount-approval-rules/*",
+				"discount-approvals": "/api/v1/discount-approvals/*",
+				"orders":        "/api/v1/orders/*",
+				"shipments":     "/api/v1/shipments/*",
+				"einvoice":      "/api/v1/einvoice/*",
+				"accounting":    "/api/v1/accounting/*",
+				"ecommerce":     "/api/v1/ecommerce/*",
+				"pricing":       "/api/v1/pricing/*",
+				"insights":      "/api/v1/insights/*",
 				"notifications": "/api/v1/notifications/*",
+				"devices":      "/api/v1/devices/*",
+				"branding":     "/api/v1/branding",
+				"labels":       "/api/v1/labels",
+				"graphql":      "/graphql",
 			},
 		})
 	})
 
-	// Route to IAM service (authentication endpoints - no auth required)
-	mux.HandleFunc("/api/v1/auth/", func(w http.ResponseWriter, r *http.Request) {
-		iamProxy.ServeHTTP(w, r)
+	// Tenant and branding of the custom domain the web app is loaded from
+	mux.HandleFunc("GET /api/v1/branding", brandingHandler(domains))
+
+	// Display labels of statuses, priorities and stage types, in the locale
+	// given by ?locale= or the one the client accepts
+	mux.HandleFunc("GET /api/v1/labels", func(w http.ResponseWriter, r *http.Request) {
+		locale := i18n.Negotiate(r.Header.Get("Accept-Language"))
+		if requested := i18n.Match(r.URL.Query().Get("locale")); requested != "" {
+			locale = requested
+		}
+		w.Header().Set("Content-Language", locale)
+		response.OK(w, map[string]interface{}{
+			"locale": locale,
+			"labels": i18n.Labels(locale),
+		})
 	})
 
+	// Route to IAM service (authentication endpoints - no auth required)
+	mux.Handle("/api/v1/auth/", authHandler)
+
 	// Route to IAM service (user management - auth required)
 	mux.HandleFunc("/api/v1/users/", func(w http.ResponseWriter, r *http.Request) {
 		iamProxy.ServeHTTP(w, r)
@@ -192,11 +496,44 @@ func main() {
 		iamProxy.ServeHTTP(w, r)
 	})
 
+	mux.HandleFunc("/api/v1/tenants/", func(w http.ResponseWriter, r *http.Request) {
+		iamProxy.ServeHTTP(w, r)
+	})
+
+	// Tenant export downloads, authorized by their signed links
+	mux.HandleFunc("/api/v1/export-files/", func(w http.ResponseWriter, r *http.Request) {
+		iamProxy.ServeHTTP(w, r)
+	})
+
+	// Queued heavy requests and their responses
+	mux.HandleFunc("GET /api/v1/queued-requests/{id}", heavy.JobHandler)
+	mux.HandleFunc("GET /api/v1/queued-requests/{id}/result", heavy.ResultHandler)
+
+	// Customer 360, merged from the customer, sales and notification services
+	mux.Handle("GET /api/v1/customers/{id}/overview", newCustomerOverviewHandler(routes, redis, log))
+
+	// Comment threads on customers are kept by the sales service, with
+	// those on leads and opportunities
+	mux.HandleFunc("/api/v1/customers/{id}/comments", func(w http.ResponseWriter, r *http.Request) {
+		salesProxy.ServeHTTP(w, r)
+	})
+	mux.HandleFunc("/api/v1/customers/{id}/comments/", func(w http.ResponseWriter, r *http.Request) {
+		salesProxy.ServeHTTP(w, r)
+	})
+
 	// Route to Customer service
 	mux.HandleFunc("/api/v1/customers/", func(w http.ResponseWriter, r *http.Request) {
 		customerProxy.ServeHTTP(w, r)
 	})
 
+	// Resumable import uploads
+	mux.HandleFunc("/api/v1/import-uploads", func(w http.ResponseWriter, r *http.Request) {
+		customerProxy.ServeHTTP(w, r)
+	})
+	mux.HandleFunc("/api/v1/import-uploads/", func(w http.ResponseWriter, r *http.Request) {
+		customerProxy.ServeHTTP(w, r)
+	})
+
 	// Route to Sales service
 	mux.HandleFunc("/api/v1/leads/", func(w http.ResponseWriter, r *http.Request) {
 		salesProxy.ServeHTTP(w, r)
@@ -214,35 +551,161 @@ func main() {
 		salesProxy.ServeHTTP(w, r)
 	})
 
+	mux.HandleFunc("/api/v1/reports/", func(w http.ResponseWriter, r *http.Request) {
+		salesProxy.ServeHTTP(w, r)
+	})
+
+	mux.HandleFunc("/api/v1/currency/", func(w http.ResponseWriter, r *http.Request) {
+		salesProxy.ServeHTTP(w, r)
+	})
+
+	mux.HandleFunc("/api/v1/tax/", func(w http.ResponseWriter, r *http.Request) {
+		salesProxy.ServeHTTP(w, r)
+	})
+
+	mux.HandleFunc("/api/v1/opportunity-reasons/", func(w http.ResponseWriter, r *http.Request) {
+		salesProxy.ServeHTTP(w, r)
+	})
+
+	mux.HandleFunc("/api/v1/inbound-email/", func(w http.ResponseWriter, r *http.Request) {
+		salesProxy.ServeHTTP(w, r)
+	})
+
+	mux.HandleFunc("/api/v1/events/", func(w http.ResponseWriter, r *http.Request) {
+		salesProxy.ServeHTTP(w, r)
+	})
+
+	mux.HandleFunc("/api/v1/retention/", func(w http.ResponseWriter, r *http.Request) {
+		salesProxy.ServeHTTP(w, r)
+	})
+
+	mux.HandleFunc("/api/v1/discount-approval-rules", func(w http.ResponseWriter, r *http.Request) {
+		salesProxy.ServeHTTP(w, r)
+	})
+
+	mux.HandleFunc("/api/v1/discount-approval-rules/", func(w http.ResponseWriter, r *http.Request) {
+		salesProxy.ServeHTTP(w, r)
+	})
+
+	mux.HandleFunc("/api/v1/discount-approvals", func(w http.ResponseWriter, r *http.Request) {
+		salesProxy.ServeHTTP(w, r)
+	})
+
+	mux.HandleFunc("/api/v1/discount-approvals/", func(w http.ResponseWriter, r *http.Request) {
+		salesProxy.ServeHTTP(w, r)
+	})
+
+	mux.HandleFunc("/api/v1/orders", func(w http.ResponseWriter, r *http.Request) {
+		salesProxy.ServeHTTP(w, r)
+	})
+
+	mux.HandleFunc("/api/v1/orders/", func(w http.ResponseWriter, r *http.Request) {
+		salesProxy.ServeHTTP(w, r)
+	})
+
+	mux.HandleFunc("/api/v1/shipments/", func(w http.ResponseWriter, r *http.Request) {
+		salesProxy.ServeHTTP(w, r)
+	})
+
+	mux.HandleFunc("/api/v1/einvoice/", func(w http.ResponseWriter, r *http.Request) {
+		salesProxy.ServeHTTP(w, r)
+	})
+
+	mux.HandleFunc("/api/v1/accounting/", func(w http.ResponseWriter, r *http.Request) {
+		salesProxy.ServeHTTP(w, r)
+	})
+
+	mux.HandleFunc("/api/v1/ecommerce/", func(w http.ResponseWriter, r *http.Request) {
+		salesProxy.ServeHTTP(w, r)
+	})
+
+	mux.HandleFunc("/api/v1/pricing/", func(w http.ResponseWriter, r *http.Request) {
+		salesProxy.ServeHTTP(w, r)
+	})
+
+	mux.HandleFunc("/api/v1/insights", func(w http.ResponseWriter, r *http.Request) {
+		salesProxy.ServeHTTP(w, r)
+	})
+
+	mux.HandleFunc("/api/v1/insights/", func(w http.ResponseWriter, r *http.Request) {
+		salesProxy.ServeHTTP(w, r)
+	})
+
 	// Route to Notification service
 	mux.HandleFunc("/api/v1/notifications/", func(w http.ResponseWriter, r *http.Request) {
 		notificationProxy.ServeHTTP(w, r)
 	})
 
+	// Push notification devices of the calling user
+	mux.HandleFunc("/api/v1/devices", func(w http.ResponseWriter, r *http.Request) {
+		notificationProxy.ServeHTTP(w, r)
+	})
+
+	mux.HandleFunc("/api/v1/devices/", func(w http.ResponseWriter, r *http.Request) {
+		notificationProxy.ServeHTTP(w, r)
+	})
+
+	// GraphQL aggregation (auth required; backends are called with the caller's token)
+	mux.Handle("/graphql", graphQLHandler)
+	mux.Handle("GET /graphql/schema", graphQLSchemaHandler)
+
 	// Apply middleware for public endpoints (health, metrics, api docs)
 	publicHandler := middleware.Chain(
 		middleware.RequestID,
-		middleware.Logger(log),
+		middleware.RequestLogger(log, cfg.Logger.Request),
 		middleware.Recover(log),
-		middleware.CORS([]string{"*"}, []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"}, []string{"*"}),
+		ipFilter.Middleware,
+		security.Headers(cfg.Security),
+		security.Sanitize(cfg.Security),
+		middleware.CORSWithPolicy(corsPolicy),
+		middleware.ForwardIdentity(jwtManager),
+		transforms.Middleware,
 	)(mux)
 
 	// Apply middleware for protected endpoints
 	protectedHandler := middleware.Chain(
 		middleware.RequestID,
-		middleware.Logger(log),
+		middleware.RequestLogger(log, cfg.Logger.Request),
 		middleware.Recover(log),
-		middleware.CORS([]string{"*"}, []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"}, []string{"*"}),
+		ipFilter.Middleware,
+		security.Headers(cfg.Security),
+		security.Sanitize(cfg.Security),
+		middleware.CORSWithPolicy(corsPolicy),
 		middleware.RateLimit(rateLimiter, rateLimitConfig),
-		middleware.Auth(jwtManager),
+		middleware.SessionAuth(validateToken, sessionConfig),
+		// Limited per tenant once the caller is known
+		middleware.TenantRateLimit(rateLimiter, rateLimitConfig),
+		// Before the identity is signed, so queued requests are signed
+		// when they are sent
+		heavy.Middleware,
+		middleware.ForwardIdentity(jwtManager),
+		middleware.RequireOriginTenant,
+		RequireHostTenant,
+		versions.TrackTenant,
+		trafficRecorder.Middleware,
+		middleware.Idempotency(idempotencyStore, middleware.DefaultIdempotencyConfig()),
+		featureflag.Middleware(flags, flagSubject),
+		transforms.Middleware,
 	)(mux)
 
-	// Create main handler that selects appropriate handler based on path
+	// Create main handler that selects appropriate handler based on path;
+	// versioned paths have been routed to the backend prefix by now
 	mainHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		// Public endpoints
 		if r.URL.Path == "/health" || r.URL.Path == "/metrics" || r.URL.Path == "/api" ||
 			r.URL.Path == "/api/v1/auth/login" || r.URL.Path == "/api/v1/auth/register" ||
-			r.URL.Path == "/api/v1/auth/refresh" {
+			r.URL.Path == "/api/v1/auth/refresh" || r.URL.Path == "/api/v1/branding" ||
+			r.URL.Path == "/api/v1/labels" ||
+			// Mail relay delivery, authenticated by the sales service's shared secret
+			r.URL.Path == "/api/v1/inbound-email/messages" ||
+			// Carrier tracking webhooks, verified by the carrier's webhook parser
+			strings.HasPrefix(r.URL.Path, "/api/v1/shipments/webhooks/") ||
+			// Accounting connector webhooks, signed with the connection secret
+			strings.HasPrefix(r.URL.Path, "/api/v1/accounting/webhooks/") ||
+			// Online store webhooks, signed with the store's webhook secret
+			strings.HasPrefix(r.URL.Path, "/api/v1/ecommerce/webhooks/") ||
+			// Tenant export downloads, authenticated by the link's signature
+			strings.HasPrefix(r.URL.Path, "/api/v1/export-files/") {
 			publicHandler.ServeHTTP(w, r)
 			return
 		}
@@ -251,16 +714,50 @@ func main() {
 		protectedHandler.ServeHTTP(w, r)
 	})
 
-	// Create HTTP server
+	// Create HTTP server. Responses are labelled for the client's language
+	// after they are translated to the requested API version
+	handler := sloTracker.Middleware(i18n.DefaultLabelFields.Middleware(versions.Middleware(domains.Middleware(mainHandler))))
 	server := &http.Server{
 		Addr:         fmt.Sprintf("%s:%d", cfg.Server.Host, cfg.Server.Port),
-		Handler:      mainHandler,
+		Handler:      handler,
 		ReadTimeout:  cfg.Server.ReadTimeout,
 		WriteTimeout: cfg.Server.WriteTimeout,
 		IdleTimeout:  cfg.Server.IdleTimeout,
 	}
 
-	// Start server in a goroutine
+	// Serve the tenants' custom domains over TLS with certificates obtained
+	// from the ACME CA; the HTTP server answers the HTTP-01 challenges
+	if getEnv("GATEWAY_ACME_ENABLED", "false") == "true" {
+		certManager := newCertManager(
+			domains,
+			getEnv("GATEWAY_ACME_CACHE_DIR", filepath.Join(os.TempDir(), "crm-acme")),
+			getEnv("GATEWAY_ACME_EMAIL", ""),
+			getEnv("GATEWAY_ACME_DIRECTORY_URL", ""),
+		)
+		server.Handler = certManager.HTTPHandler(handler)
+
+		tlsServer := &http.Server{
+			Addr:         getEnv("GATEWAY_TLS_ADDR", ":8443"),
+			Handler:      handler,
+			TLSConfig:    certTLSConfig(certManager),
+			ReadTimeout:  cfg.Server.ReadTimeout,
+			WriteTimeout: cfg.Server.WriteTimeout,
+			IdleTimeout:  cfg.Server.IdleTimeout,
+		}
+		go func() {
+			log.Info().
+				Str("addr", tlsServer.Addr).
+				Msg("HTTPS server started for tenant custom domains")
+
+			if err := tlsServer.ListenAndServeTLS("", ""); err != nil && err != http.ErrServerClosed {
+				log.Fatal().Err(err).Msg("HTTPS server failed")
+			}
+		}()
+		lc.OnShutdown("https server", tlsServer.Shutdown)
+	}
+
+	// Start server in a goroutine, taking over from the startup health checks
+	startup.Ready()
 	go func() {
 		log.Info().
 			Str("addr", server.Addr).
@@ -270,40 +767,57 @@ func main() {
 			log.Fatal().Err(err).Msg("HTTP server failed")
 		}
 	}()
+	lc.OnShutdown("http server", server.Shutdown)
 
 	// Wait for interrupt signal
-	quit := make(chan os.Signal, 1)
-	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
-	<-quit
-
-	log.Info().Msg("Shutting down server...")
+	sig := lc.Wait()
+	log.Info().Str("signal", sig.String()).Msg("Shutting down server...")
 
-	// Create shutdown context with timeout
-	ctx, cancel := context.WithTimeout(context.Background(), cfg.Server.ShutdownTimeout)
-	defer cancel()
-
-	// Graceful shutdown
-	if err := server.Shutdown(ctx); err != nil {
+	// Stop taking requests, let those being proxied finish, then close the
+	// connections
+	if err := lc.Shutdown(cfg.Server.ShutdownTimeout); err != nil {
 		log.Error().Err(err).Msg("Server forced to shutdown")
 	}
 
 	log.Info().Msg("Server stopped")
-}
 
-// createReverseProxy creates a reverse proxy for a target URL.
-func createReverseProxy(target string, log *logger.Logger) *httputil.ReverseProxy {
-	targetURL, err := url.Parse(target)
-	if err != nil {
-		log.Fatal().Err(err).Str("target", target).Msg("Invalid target URL")
+	// Ship the log lines still buffered
+	logCtx, logCancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer logCancel()
+	if err := log.Close(logCtx); err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to ship remaining logs: %v\n", err)
 	}
+}
+
+// proxyTargetKey is the request context key for the endpoint picked for a request.
+type proxyTargetKey struct{}
 
-	proxy := httputil.NewSingleHostReverseProxy(targetURL)
+// createServiceProxy creates a reverse proxy that forwards each request to an
+// instance of the named service picked from the routing table.
+func createServiceProxy(service string, routes *RoutingTable, breakers *resilience.CircuitBreakerRegistry, log *logger.Logger) http.Handler {
+	proxy := &httputil.ReverseProxy{
+		// Idempotent requests are retried on other instances per the service's
+		// retry policy; the circuit breaker sees the outcome of all attempts
+		Transport: newBreakerTransport(service, breakers, newRetryTransport(service, routes, http.DefaultTransport, log)),
+	}
 
 	// Custom error handler
 	proxy.ErrorHandler = func(w http.ResponseWriter, r *http.Request, err error) {
+		// Request bodies over the transform policy limit fail while being proxied
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			response.Error(w, apperrors.ErrPayloadTooLarge(maxBytesErr.Limit))
+			return
+		}
+		if circuitOpen(err) {
+			response.Error(w, apperrors.ErrServiceUnavailable(service))
+			return
+		}
+
 		log.Error().
 			Err(err).
-			Str("target", target).
+			Str("service", service).
+			Str("target", r.URL.Host).
 			Str("path", r.URL.Path).
 			Msg("Proxy error")
 
@@ -311,9 +825,18 @@ func createReverseProxy(target string, log *logger.Logger) *httputil.ReverseProx
 	}
 
 	// Modify request before sending to backend
-	originalDirector := proxy.Director
 	proxy.Director = func(r *http.Request) {
-		originalDirector(r)
+		target := r.Context().Value(proxyTargetKey{}).(*url.URL)
+		r.URL.Scheme = target.Scheme
+		r.URL.Host = target.Host
+		if target.Path != "" {
+			r.URL.Path = strings.TrimSuffix(target.Path, "/") + r.URL.Path
+			r.URL.RawPath = ""
+		}
+		if _, ok := r.Header["User-Agent"]; !ok {
+			// Explicitly disable the default Go User-Agent
+			r.Header.Set("User-Agent", "")
+		}
 
 		// Forward original host and protocol
 		r.Header.Set("X-Forwarded-Host", r.Host)
@@ -332,7 +855,28 @@ func createReverseProxy(target string, log *logger.Logger) *httputil.ReverseProx
 		return nil
 	}
 
-	return proxy
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		target, err := routes.Pick(service)
+		if err != nil {
+			log.Warn().Err(err).Str("service", service).Msg("No route to service")
+			response.Error(w, apperrors.ErrServiceUnavailable(service))
+			return
+		}
+		proxy.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), proxyTargetKey{}, target)))
+	})
+}
+
+// flagSubject evaluates feature flags for the authenticated tenant and user.
+func flagSubject(ctx context.Context) featureflag.Subject {
+	return featureflag.Subject{
+		TenantID: middleware.TenantIDFromContext(ctx),
+		UserID:   middleware.UserIDFromContext(ctx),
+	}
+}
+
+// captureSubject resolves the tenant and user of a request for traffic capture.
+func captureSubject(ctx context.Context) (string, string) {
+	return middleware.TenantIDFromContext(ctx), middleware.UserIDFromContext(ctx)
 }
 
 // getEnv gets an environment variable or returns a default value.
@@ -342,31 +886,3 @@ func getEnv(key, defaultValue string) string {
 	}
 	return defaultValue
 }
-
-// aggregateResponses aggregates responses from multiple services (for future use).
-func aggregateResponses(ctx context.Context, urls []string) ([]interface{}, error) {
-	results := make([]interface{}, len(urls))
-	client := &http.Client{Timeout: 10 * time.Second}
-
-	for i, url := range urls {
-		req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
-		if err != nil {
-			return nil, err
-		}
-
-		resp, err := client.Do(req)
-		if err != nil {
-			return nil, err
-		}
-		defer resp.Body.Close()
-
-		body, err := io.ReadAll(resp.Body)
-		if err != nil {
-			return nil, err
-		}
-
-		results[i] = string(body)
-	}
-
-	return results, nil
-}
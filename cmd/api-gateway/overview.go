@@ -0,0 +1,382 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/kilang-desa-murni/crm/pkg/auth"
+	apperrors "github.com/kilang-desa-murni/crm/pkg/errors"
+	"github.com/kilang-desa-murni/crm/pkg/identity"
+	"github.com/kilang-desa-murni/crm/pkg/logger"
+	"github.com/kilang-desa-murni/crm/pkg/middleware"
+	"github.com/kilang-desa-murni/crm/pkg/response"
+)
+
+const (
+	// overviewSectionTimeout bounds each backend call of an overview, so a
+	// slow service delays the overview by at most this long.
+	overviewSectionTimeout = 3 * time.Second
+
+	// overviewStaleTTL is how long the last good copy of a section is kept
+	// to stand in for it while its service is unavailable.
+	overviewStaleTTL = 24 * time.Hour
+
+	// overviewListSize is the number of items in the overview's lists.
+	overviewListSize = 10
+
+	// overviewMaxDeals bounds the deals summed into the lifetime value.
+	overviewMaxDeals = 100
+
+	// overviewMaxResponseSize bounds a backend response read for a section.
+	overviewMaxResponseSize = 4 << 20
+)
+
+// Overview section statuses
+const (
+	sectionOK          = "ok"
+	sectionStale       = "stale"
+	sectionUnavailable = "unavailable"
+)
+
+// ============================================================================
+// Customer Overview
+// ============================================================================
+
+// overviewSection is one section of a customer overview. A section whose
+// service failed is served from its last good copy, marked stale with the
+// time it was fetched, or is unavailable when there is none.
+type overviewSection struct {
+	Status    string      `json:"status"`
+	FetchedAt *time.Time  `json:"fetched_at,omitempty"`
+	Error     string      `json:"error,omitempty"`
+	Data      interface{} `json:"data"`
+}
+
+// overviewCache keeps the last good copy of overview sections.
+type overviewCache interface {
+	Get(ctx context.Context, key string, target interface{}) error
+	Set(ctx context.Context, key string, value interface{}, expiration time.Duration) error
+}
+
+// overviewCaller is the identity an overview is fetched as. Backend calls
+// are made with the caller's own token, so each service enforces its usual
+// permissions.
+type overviewCaller struct {
+	authorization string
+	identity      http.Header // Signed identity headers, forwarded as is
+	requestID     string
+	tenantID      string
+}
+
+// customerOverviewHandler serves GET /api/v1/customers/{id}/overview: the
+// customer's profile, open opportunities, last activities, recent
+// notifications and lifetime value, fetched from the customer, sales and
+// notification services concurrently.
+type customerOverviewHandler struct {
+	routes  *RoutingTable
+	clients map[string]*http.Client
+	cache   overviewCache
+	log     *logger.Logger
+}
+
+func newCustomerOverviewHandler(routes *RoutingTable, cache overviewCache, log *logger.Logger) *customerOverviewHandler {
+	clients := make(map[string]*http.Client)
+	for _, service := range []string{"customer", "sales", "notification"} {
+		clients[service] = &http.Client{
+			Timeout:   overviewSectionTimeout,
+			Transport: newRetryTransport(service, routes, http.DefaultTransport, log),
+		}
+	}
+	return &customerOverviewHandler{routes: routes, clients: clients, cache: cache, log: log}
+}
+
+// overviewFetch fetches the data of one section.
+type overviewFetch func(ctx context.Context, caller *overviewCaller, customerID string) (interface{}, error)
+
+func (h *customerOverviewHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	claims, ok := auth.ClaimsFromContext(r.Context())
+	if !ok {
+		response.Error(w, apperrors.ErrUnauthorized("authentication required"))
+		return
+	}
+	customerID := r.PathValue("id")
+	if _, err := uuid.Parse(customerID); err != nil {
+		response.BadRequest(w, "invalid customer ID")
+		return
+	}
+
+	caller := &overviewCaller{
+		authorization: r.Header.Get("Authorization"),
+		identity:      http.Header{},
+		requestID:     middleware.RequestIDFromContext(r.Context()),
+		tenantID:      claims.TenantID,
+	}
+	identity.Copy(caller.identity, r.Header)
+
+	fetches := map[string]overviewFetch{
+		"customer":             h.fetchCustomer,
+		"open_opportunities":   h.fetchOpenOpportunities,
+		"recent_activities":    h.fetchRecentActivities,
+		"recent_notifications": h.fetchRecentNotifications,
+		"lifetime_value":       h.fetchLifetimeValue,
+	}
+
+	sections := make(map[string]*overviewSection, len(fetches))
+	var customerErr error
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for name, fetch := range fetches {
+		wg.Add(1)
+		go func(name string, fetch overviewFetch) {
+			defer wg.Done()
+			section, err := h.section(r.Context(), caller, customerID, name, fetch)
+			mu.Lock()
+			defer mu.Unlock()
+			sections[name] = section
+			if name == "customer" {
+				customerErr = err
+			}
+		}(name, fetch)
+	}
+	wg.Wait()
+
+	if errors.Is(customerErr, errOverviewNotFound) {
+		response.NotFound(w, "customer")
+		return
+	}
+
+	partial := false
+	for _, section := range sections {
+		if section.Status != sectionOK {
+			partial = true
+		}
+	}
+
+	response.OK(w, map[string]interface{}{
+		"customer_id":  customerID,
+		"partial":      partial,
+		"generated_at": time.Now().UTC(),
+		"sections":     sections,
+	})
+}
+
+// errOverviewNotFound is returned by a fetch when the customer does not exist.
+var errOverviewNotFound = errors.New("not found")
+
+// overviewUnavailableError is returned by a fetch when a service could not be
+// reached or failed with a server error.
+type overviewUnavailableError struct {
+	service string
+}
+
+func (e *overviewUnavailableError) Error() string {
+	return e.service + " service is unavailable"
+}
+
+// section fetches a section, keeping its last good copy to serve in its
+// place while the section's service is unavailable. The copy is shared by
+// the tenant's users, so it never stands in for a request the service
+// rejected, such as a caller without permission. The fetch error is
+// returned with the section.
+func (h *customerOverviewHandler) section(ctx context.Context, caller *overviewCaller, customerID, name string, fetch overviewFetch) (*overviewSection, error) {
+	key := fmt.Sprintf("gateway:overview:%s:%s:%s", caller.tenantID, customerID, name)
+
+	data, err := fetch(ctx, caller, customerID)
+	if err == nil {
+		now := time.Now().UTC()
+		section := &overviewSection{Status: sectionOK, FetchedAt: &now, Data: data}
+		if h.cache != nil {
+			if err := h.cache.Set(ctx, key, section, overviewStaleTTL); err != nil {
+				h.log.Warn().Err(err).Str("section", name).Msg("Failed to cache customer overview section")
+			}
+		}
+		return section, nil
+	}
+
+	var unavailable *overviewUnavailableError
+	if errors.As(err, &unavailable) && h.cache != nil {
+		var cached overviewSection
+		if cacheErr := h.cache.Get(ctx, key, &cached); cacheErr == nil {
+			cached.Status = sectionStale
+			cached.Error = err.Error()
+			return &cached, err
+		}
+	}
+	return &overviewSection{Status: sectionUnavailable, Error: err.Error()}, err
+}
+
+func (h *customerOverviewHandler) fetchCustomer(ctx context.Context, caller *overviewCaller, customerID string) (interface{}, error) {
+	data, err := h.get(ctx, caller, "customer", "/api/v1/customers/"+customerID, nil)
+	if err != nil {
+		return nil, err
+	}
+	customer := caller.scope(data)
+	if customer == nil {
+		return nil, errOverviewNotFound
+	}
+	return customer, nil
+}
+
+func (h *customerOverviewHandler) fetchOpenOpportunities(ctx context.Context, caller *overviewCaller, customerID string) (interface{}, error) {
+	query := url.Values{}
+	query.Set("customer_ids", customerID)
+	query.Set("statuses", "open")
+	query.Set("page_size", strconv.Itoa(overviewListSize))
+	return h.list(ctx, caller, "sales", "/api/v1/sales/opportunities", query, "opportunities")
+}
+
+func (h *customerOverviewHandler) fetchRecentActivities(ctx context.Context, caller *overviewCaller, customerID string) (interface{}, error) {
+	query := url.Values{}
+	query.Set("limit", strconv.Itoa(overviewListSize))
+	query.Set("sort_order", "desc")
+	return h.list(ctx, caller, "customer", "/api/v1/customers/"+customerID+"/activities", query, "activities")
+}
+
+func (h *customerOverviewHandler) fetchRecentNotifications(ctx context.Context, caller *overviewCaller, customerID string) (interface{}, error) {
+	query := url.Values{}
+	query.Set("customer_id", customerID)
+	query.Set("page_size", strconv.Itoa(overviewListSize))
+	return h.list(ctx, caller, "notification", "/api/v1/notifications", query, "items")
+}
+
+// fetchLifetimeValue sums the customer's deals that were not cancelled, per
+// currency, in minor units.
+func (h *customerOverviewHandler) fetchLifetimeValue(ctx context.Context, caller *overviewCaller, customerID string) (interface{}, error) {
+	query := url.Values{}
+	query.Set("customer_id", customerID)
+	query.Set("page_size", strconv.Itoa(overviewMaxDeals))
+	deals, err := h.list(ctx, caller, "sales", "/api/v1/sales/deals", query, "deals")
+	if err != nil {
+		return nil, err
+	}
+
+	totals := make(map[string]int64)
+	count := 0
+	for _, deal := range deals {
+		if deal["status"] == "cancelled" {
+			continue
+		}
+		amount, _ := deal["total_amount"].(map[string]interface{})
+		currency, _ := amount["currency"].(string)
+		value, _ := amount["amount"].(float64)
+		if currency == "" {
+			continue
+		}
+		totals[currency] += int64(value)
+		count++
+	}
+	return map[string]interface{}{
+		"totals":     totals,
+		"deal_count": count,
+		"complete":   len(deals) < overviewMaxDeals,
+	}, nil
+}
+
+// list calls a list endpoint and returns the items that belong to the
+// caller's tenant.
+func (h *customerOverviewHandler) list(ctx context.Context, caller *overviewCaller, service, path string, query url.Values, key string) ([]map[string]interface{}, error) {
+	data, err := h.get(ctx, caller, service, path, query)
+	if err != nil {
+		return nil, err
+	}
+
+	// Some list endpoints return the items under a named key with their own
+	// pagination instead of in the envelope's data
+	if object, ok := data.(map[string]interface{}); ok {
+		data = object[key]
+	}
+	items, _ := data.([]interface{})
+
+	visible := make([]map[string]interface{}, 0, len(items))
+	for _, item := range items {
+		if object := caller.scope(item); object != nil {
+			visible = append(visible, object)
+		}
+	}
+	return visible, nil
+}
+
+// get calls a backend endpoint on behalf of the caller and returns the
+// response data, unwrapped from the services' {"success", "data"} envelope.
+func (h *customerOverviewHandler) get(ctx context.Context, caller *overviewCaller, service, path string, query url.Values) (interface{}, error) {
+	target, err := h.routes.Pick(service)
+	if err != nil {
+		return nil, &overviewUnavailableError{service: service}
+	}
+
+	endpoint := target.JoinPath(path)
+	endpoint.RawQuery = query.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("Authorization", caller.authorization)
+	identity.Copy(req.Header, caller.identity)
+	req.Header.Set("X-Tenant-ID", caller.tenantID)
+	if caller.requestID != "" {
+		req.Header.Set("X-Request-ID", caller.requestID)
+	}
+
+	resp, err := h.clients[service].Do(req)
+	if err != nil {
+		h.log.Warn().Err(err).Str("service", service).Str("path", path).Msg("Customer overview backend call failed")
+		return nil, &overviewUnavailableError{service: service}
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, overviewMaxResponseSize+1))
+	if err != nil {
+		h.log.Warn().Err(err).Str("service", service).Str("path", path).Msg("Customer overview backend response failed")
+		return nil, &overviewUnavailableError{service: service}
+	}
+	if len(body) > overviewMaxResponseSize {
+		return nil, fmt.Errorf("%s service response is too large", service)
+	}
+
+	var payload interface{}
+	decodeErr := json.Unmarshal(body, &payload)
+
+	switch {
+	case resp.StatusCode == http.StatusNotFound:
+		return nil, errOverviewNotFound
+	case resp.StatusCode >= 500:
+		h.log.Warn().Int("status", resp.StatusCode).Str("service", service).Str("path", path).Msg("Customer overview backend call failed")
+		return nil, &overviewUnavailableError{service: service}
+	case resp.StatusCode >= 400:
+		return nil, errors.New(backendMessage(payload, fmt.Sprintf("%s service rejected the request", service)))
+	case decodeErr != nil:
+		return nil, fmt.Errorf("invalid %s service response", service)
+	}
+
+	if envelope, ok := payload.(map[string]interface{}); ok {
+		if _, wrapped := envelope["success"]; wrapped {
+			payload = envelope["data"]
+		}
+	}
+	return payload, nil
+}
+
+// scope returns a resource only if it belongs to the caller's tenant, so a
+// misbehaving backend cannot leak another tenant's data into an overview.
+func (c *overviewCaller) scope(data interface{}) map[string]interface{} {
+	object, ok := data.(map[string]interface{})
+	if !ok {
+		return nil
+	}
+	if tenantID, ok := object["tenant_id"].(string); ok && tenantID != c.tenantID {
+		return nil
+	}
+	return object
+}
@@ -0,0 +1,330 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/kilang-desa-murni/crm/pkg/auth"
+	"github.com/kilang-desa-murni/crm/pkg/logger"
+)
+
+const (
+	testOverviewTenant   = "7c9e6679-7425-40de-944b-e07fc1f90ae7"
+	testOverviewCustomer = "3f2b8c1a-6d4e-4f5a-9b7c-8d9e0f1a2b3c"
+)
+
+// memoryOverviewCache is an overviewCache kept in memory.
+type memoryOverviewCache struct {
+	mu      sync.Mutex
+	entries map[string][]byte
+}
+
+func (c *memoryOverviewCache) Get(ctx context.Context, key string, target interface{}) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	data, ok := c.entries[key]
+	if !ok {
+		return errors.New("cache miss")
+	}
+	return json.Unmarshal(data, target)
+}
+
+func (c *memoryOverviewCache) Set(ctx context.Context, key string, value interface{}, expiration time.Duration) error {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries[key] = data
+	return nil
+}
+
+// overviewBackend answers for the customer, sales and notification services.
+// A failure registered for a path prefix replaces the normal response.
+type overviewBackend struct {
+	mu       sync.Mutex
+	failures map[string]http.HandlerFunc
+}
+
+func (b *overviewBackend) fail(prefix string, handler http.HandlerFunc) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.failures[prefix] = handler
+}
+
+func (b *overviewBackend) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	b.mu.Lock()
+	for prefix, handler := range b.failures {
+		if strings.HasPrefix(r.URL.Path, prefix) {
+			b.mu.Unlock()
+			handler(w, r)
+			return
+		}
+	}
+	b.mu.Unlock()
+
+	var data interface{}
+	switch {
+	case r.URL.Path == "/api/v1/customers/"+testOverviewCustomer:
+		data = map[string]interface{}{"id": testOverviewCustomer, "tenant_id": testOverviewTenant, "name": "Batik Murni"}
+	case strings.HasSuffix(r.URL.Path, "/activities"):
+		data = map[string]interface{}{"activities": []interface{}{map[string]interface{}{"id": "a-1", "tenant_id": testOverviewTenant}}}
+	case r.URL.Path == "/api/v1/sales/opportunities":
+		data = map[string]interface{}{"opportunities": []interface{}{
+			map[string]interface{}{"id": "o-1", "tenant_id": testOverviewTenant},
+			map[string]interface{}{"id": "o-2", "tenant_id": "another-tenant"},
+		}}
+	case r.URL.Path == "/api/v1/sales/deals":
+		data = map[string]interface{}{"deals": []interface{}{
+			map[string]interface{}{"status": "won", "total_amount": map[string]interface{}{"currency": "MYR", "amount": 150000}},
+			map[string]interface{}{"status": "cancelled", "total_amount": map[string]interface{}{"currency": "MYR", "amount": 90000}},
+		}}
+	case r.URL.Path == "/api/v1/notifications":
+		data = map[string]interface{}{"items": []interface{}{}}
+	default:
+		writeBackendError(w, http.StatusNotFound, "not found")
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(map[string]interface{}{"success": true, "data": data})
+}
+
+func writeBackendError(w http.ResponseWriter, status int, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(map[string]interface{}{"success": false, "error": map[string]interface{}{"message": message}})
+}
+
+func backendStatus(status int, message string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) { writeBackendError(w, status, message) }
+}
+
+// dropConnection closes the connection without a response.
+func dropConnection(w http.ResponseWriter, r *http.Request) {
+	panic(http.ErrAbortHandler)
+}
+
+// newTestOverview serves customer overviews from a test backend.
+func newTestOverview(t *testing.T) (http.Handler, *overviewBackend) {
+	t.Helper()
+	backend := &overviewBackend{failures: map[string]http.HandlerFunc{}}
+	server := httptest.NewServer(backend)
+	t.Cleanup(server.Close)
+
+	endpoints := []ServiceEndpoint{{URL: server.URL, Weight: 1}}
+	log := logger.New(logger.Config{Level: "error"})
+	routes, err := NewRoutingTable(context.Background(), &RoutingConfig{
+		Retry: RetryPolicyConfig{MaxAttempts: 1},
+		Services: map[string]ServiceDiscoveryConfig{
+			"customer":     {Discovery: DiscoveryStatic, Endpoints: endpoints},
+			"sales":        {Discovery: DiscoveryStatic, Endpoints: endpoints},
+			"notification": {Discovery: DiscoveryStatic, Endpoints: endpoints},
+		},
+	}, log)
+	if err != nil {
+		t.Fatalf("NewRoutingTable() error = %v", err)
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle("GET /api/v1/customers/{id}/overview", newCustomerOverviewHandler(routes, &memoryOverviewCache{entries: map[string][]byte{}}, log))
+	return mux, backend
+}
+
+type testOverview struct {
+	Data struct {
+		Partial  bool                       `json:"partial"`
+		Sections map[string]overviewSection `json:"sections"`
+	} `json:"data"`
+}
+
+// getOverview requests the test customer's overview as a user of the tenant.
+func getOverview(t *testing.T, handler http.Handler, customerID string) (int, testOverview) {
+	t.Helper()
+	r := httptest.NewRequest(http.MethodGet, "/api/v1/customers/"+customerID+"/overview", nil)
+	r.Header.Set("Authorization", "Bearer token")
+	r = r.WithContext(auth.ContextWithClaims(r.Context(), &auth.Claims{UserID: "user-1", TenantID: testOverviewTenant}))
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, r)
+
+	var overview testOverview
+	if w.Code == http.StatusOK {
+		if err := json.Unmarshal(w.Body.Bytes(), &overview); err != nil {
+			t.Fatalf("failed to decode overview: %v", err)
+		}
+	}
+	return w.Code, overview
+}
+
+func sectionStatuses(overview testOverview) map[string]string {
+	statuses := make(map[string]string, len(overview.Data.Sections))
+	for name, section := range overview.Data.Sections {
+		statuses[name] = section.Status
+	}
+	return statuses
+}
+
+func TestCustomerOverview_Sections(t *testing.T) {
+	tests := []struct {
+		name        string
+		failures    map[string]http.HandlerFunc
+		wantPartial bool
+		want        map[string]string
+	}{
+		{
+			name: "every section fetched",
+			want: map[string]string{
+				"customer": sectionOK, "open_opportunities": sectionOK, "recent_activities": sectionOK,
+				"recent_notifications": sectionOK, "lifetime_value": sectionOK,
+			},
+		},
+		{
+			name:        "failed service without a cached copy",
+			failures:    map[string]http.HandlerFunc{"/api/v1/sales/": backendStatus(http.StatusServiceUnavailable, "maintenance")},
+			wantPartial: true,
+			want: map[string]string{
+				"customer": sectionOK, "open_opportunities": sectionUnavailable, "recent_activities": sectionOK,
+				"recent_notifications": sectionOK, "lifetime_value": sectionUnavailable,
+			},
+		},
+		{
+			name: "oversized response",
+			failures: map[string]http.HandlerFunc{"/api/v1/notifications": func(w http.ResponseWriter, r *http.Request) {
+				_, _ = w.Write([]byte(`{"success":true,"data":{"items":["` + strings.Repeat("x", overviewMaxResponseSize) + `"]}}`))
+			}},
+			wantPartial: true,
+			want: map[string]string{
+				"customer": sectionOK, "open_opportunities": sectionOK, "recent_activities": sectionOK,
+				"recent_notifications": sectionUnavailable, "lifetime_value": sectionOK,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler, backend := newTestOverview(t)
+			for prefix, failure := range tt.failures {
+				backend.fail(prefix, failure)
+			}
+
+			status, overview := getOverview(t, handler, testOverviewCustomer)
+			if status != http.StatusOK {
+				t.Fatalf("expected 200, got %d", status)
+			}
+			if overview.Data.Partial != tt.wantPartial {
+				t.Errorf("partial = %v, want %v", overview.Data.Partial, tt.wantPartial)
+			}
+			got := sectionStatuses(overview)
+			for name, want := range tt.want {
+				if got[name] != want {
+					t.Errorf("section %s = %q, want %q", name, got[name], want)
+				}
+			}
+		})
+	}
+}
+
+func TestCustomerOverview_ScopesToTenant(t *testing.T) {
+	handler, _ := newTestOverview(t)
+	_, overview := getOverview(t, handler, testOverviewCustomer)
+
+	opportunities, _ := overview.Data.Sections["open_opportunities"].Data.([]interface{})
+	if len(opportunities) != 1 {
+		t.Errorf("expected another tenant's opportunity to be dropped, got %v", opportunities)
+	}
+	value, _ := overview.Data.Sections["lifetime_value"].Data.(map[string]interface{})
+	if totals, _ := value["totals"].(map[string]interface{}); totals["MYR"] != float64(150000) {
+		t.Errorf("expected cancelled deals to be left out of the lifetime value, got %v", value)
+	}
+}
+
+func TestCustomerOverview_StaleSections(t *testing.T) {
+	tests := []struct {
+		name      string
+		failure   http.HandlerFunc
+		wantStale bool
+		wantError string
+	}{
+		{"server error", backendStatus(http.StatusBadGateway, "upstream down"), true, "sales service is unavailable"},
+		{"network failure", dropConnection, true, "sales service is unavailable"},
+		{"forbidden", backendStatus(http.StatusForbidden, "permission denied"), false, "permission denied"},
+		{"bad request", backendStatus(http.StatusBadRequest, "invalid filter"), false, "invalid filter"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler, backend := newTestOverview(t)
+
+			// A good fetch leaves a copy to stand in for the section
+			if _, overview := getOverview(t, handler, testOverviewCustomer); overview.Data.Partial {
+				t.Fatalf("expected a complete overview to seed the cache, got %v", sectionStatuses(overview))
+			}
+
+			backend.fail("/api/v1/sales/", tt.failure)
+			status, overview := getOverview(t, handler, testOverviewCustomer)
+			if status != http.StatusOK {
+				t.Fatalf("expected 200, got %d", status)
+			}
+
+			section := overview.Data.Sections["open_opportunities"]
+			if section.Error != tt.wantError {
+				t.Errorf("error = %q, want %q", section.Error, tt.wantError)
+			}
+			if !tt.wantStale {
+				if section.Status != sectionUnavailable || section.Data != nil || section.FetchedAt != nil {
+					t.Errorf("expected no cached data for a rejected request, got %+v", section)
+				}
+				return
+			}
+			if section.Status != sectionStale || section.FetchedAt == nil {
+				t.Errorf("expected the cached copy marked stale, got %+v", section)
+			}
+			if opportunities, _ := section.Data.([]interface{}); len(opportunities) != 1 {
+				t.Errorf("expected the cached opportunities, got %v", section.Data)
+			}
+		})
+	}
+}
+
+func TestCustomerOverview_Errors(t *testing.T) {
+	tests := []struct {
+		name       string
+		customerID string
+		failure    http.HandlerFunc
+		wantStatus int
+	}{
+		{"unknown customer", "0d9c3b4a-1e2f-4a5b-8c6d-7e8f9a0b1c2d", nil, http.StatusNotFound},
+		{"customer of another tenant", testOverviewCustomer, func(w http.ResponseWriter, r *http.Request) {
+			_, _ = w.Write([]byte(`{"success":true,"data":{"id":"` + testOverviewCustomer + `","tenant_id":"another-tenant"}}`))
+		}, http.StatusNotFound},
+		{"invalid customer ID", "not-a-uuid", nil, http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler, backend := newTestOverview(t)
+			if tt.failure != nil {
+				backend.fail("/api/v1/customers/"+tt.customerID, tt.failure)
+			}
+			if status, _ := getOverview(t, handler, tt.customerID); status != tt.wantStatus {
+				t.Errorf("expected %d, got %d", tt.wantStatus, status)
+			}
+		})
+	}
+}
+
+func TestCustomerOverview_Unauthenticated(t *testing.T) {
+	handler, _ := newTestOverview(t)
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/customers/"+testOverviewCustomer+"/overview", nil))
+	if w.Code != http.StatusUnauthorized {
+		t.Errorf("expected 401, got %d", w.Code)
+	}
+}
@@ -0,0 +1,484 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"math/rand"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/kilang-desa-murni/crm/pkg/logger"
+)
+
+// ============================================================================
+// Configuration
+// ============================================================================
+
+// RetryPolicyConfig configures retries of proxied requests. Zero fields take
+// the defaults from defaultRetryPolicy; set max_attempts to 1 to disable retries.
+type RetryPolicyConfig struct {
+	// MaxAttempts is the total number of attempts, including the first.
+	MaxAttempts int `mapstructure:"max_attempts"`
+	// PerTryTimeout bounds each attempt until its response headers arrive.
+	PerTryTimeout time.Duration `mapstructure:"per_try_timeout"`
+	// BackoffBase and BackoffMax bound the full-jitter exponential backoff.
+	BackoffBase time.Duration `mapstructure:"backoff_base"`
+	BackoffMax  time.Duration `mapstructure:"backoff_max"`
+	// RetryOn lists the response status codes that are retried.
+	RetryOn []int `mapstructure:"retry_on"`
+	// Methods lists the idempotent methods that may be retried.
+	Methods []string `mapstructure:"methods"`
+
+	Budget RetryBudgetConfig `mapstructure:"budget"`
+	Hedge  HedgeConfig       `mapstructure:"hedge"`
+}
+
+// RetryBudgetConfig limits retries to a share of recent traffic, so a failing
+// backend sees at most (1 + ratio) times its normal load instead of a storm.
+type RetryBudgetConfig struct {
+	// Ratio is the number of retries allowed per request in the window.
+	Ratio float64 `mapstructure:"ratio"`
+	// MinRetriesPerSecond keeps retries available for low-traffic services.
+	MinRetriesPerSecond int `mapstructure:"min_retries_per_second"`
+	// Window is the period requests and retries are counted over.
+	Window time.Duration `mapstructure:"window"`
+}
+
+// HedgeConfig enables request hedging: when an attempt has not answered within
+// Delay, another attempt is sent to a different instance and the first good
+// response wins. Hedges draw from the retry budget.
+type HedgeConfig struct {
+	Delay     time.Duration `mapstructure:"delay"`
+	MaxHedges int           `mapstructure:"max_hedges"`
+	// Paths lists the path prefixes of latency-sensitive read endpoints to hedge.
+	Paths []string `mapstructure:"paths"`
+}
+
+// defaultRetryPolicy is used for fields a policy does not set.
+var defaultRetryPolicy = RetryPolicyConfig{
+	MaxAttempts:   3,
+	PerTryTimeout: 10 * time.Second,
+	BackoffBase:   25 * time.Millisecond,
+	BackoffMax:    500 * time.Millisecond,
+	RetryOn:       []int{http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout},
+	Methods:       []string{http.MethodGet, http.MethodHead, http.MethodOptions},
+	Budget: RetryBudgetConfig{
+		Ratio:               0.2,
+		MinRetriesPerSecond: 5,
+		Window:              10 * time.Second,
+	},
+	Hedge: HedgeConfig{
+		MaxHedges: 1,
+	},
+}
+
+// ============================================================================
+// Policy
+// ============================================================================
+
+// retryPolicy is a compiled retry policy.
+type retryPolicy struct {
+	config  RetryPolicyConfig
+	retryOn map[int]bool
+	methods map[string]bool
+}
+
+// mergeRetryPolicy overlays the fields a service sets on the global policy.
+func mergeRetryPolicy(global RetryPolicyConfig, override *RetryPolicyConfig) RetryPolicyConfig {
+	if override == nil {
+		return global
+	}
+	merged := global
+	if override.MaxAttempts != 0 {
+		merged.MaxAttempts = override.MaxAttempts
+	}
+	if override.PerTryTimeout != 0 {
+		merged.PerTryTimeout = override.PerTryTimeout
+	}
+	if override.BackoffBase != 0 {
+		merged.BackoffBase = override.BackoffBase
+	}
+	if override.BackoffMax != 0 {
+		merged.BackoffMax = override.BackoffMax
+	}
+	if len(override.RetryOn) > 0 {
+		merged.RetryOn = override.RetryOn
+	}
+	if len(override.Methods) > 0 {
+		merged.Methods = override.Methods
+	}
+	if override.Budget.Ratio != 0 {
+		merged.Budget.Ratio = override.Budget.Ratio
+	}
+	if override.Budget.MinRetriesPerSecond != 0 {
+		merged.Budget.MinRetriesPerSecond = override.Budget.MinRetriesPerSecond
+	}
+	if override.Budget.Window != 0 {
+		merged.Budget.Window = override.Budget.Window
+	}
+	if override.Hedge.Delay != 0 {
+		merged.Hedge.Delay = override.Hedge.Delay
+	}
+	if override.Hedge.MaxHedges != 0 {
+		merged.Hedge.MaxHedges = override.Hedge.MaxHedges
+	}
+	if len(override.Hedge.Paths) > 0 {
+		merged.Hedge.Paths = override.Hedge.Paths
+	}
+	return merged
+}
+
+// compileRetryPolicy fills unset fields from the defaults and validates the policy.
+func compileRetryPolicy(cfg RetryPolicyConfig) (*retryPolicy, error) {
+	d := defaultRetryPolicy
+	if cfg.MaxAttempts == 0 {
+		cfg.MaxAttempts = d.MaxAttempts
+	}
+	if cfg.PerTryTimeout == 0 {
+		cfg.PerTryTimeout = d.PerTryTimeout
+	}
+	if cfg.BackoffBase == 0 {
+		cfg.BackoffBase = d.BackoffBase
+	}
+	if cfg.BackoffMax == 0 {
+		cfg.BackoffMax = d.BackoffMax
+	}
+	if len(cfg.RetryOn) == 0 {
+		cfg.RetryOn = d.RetryOn
+	}
+	if len(cfg.Methods) == 0 {
+		cfg.Methods = d.Methods
+	}
+	if cfg.Budget.Ratio == 0 {
+		cfg.Budget.Ratio = d.Budget.Ratio
+	}
+	if cfg.Budget.MinRetriesPerSecond == 0 {
+		cfg.Budget.MinRetriesPerSecond = d.Budget.MinRetriesPerSecond
+	}
+	if cfg.Budget.Window == 0 {
+		cfg.Budget.Window = d.Budget.Window
+	}
+	if cfg.Hedge.MaxHedges == 0 {
+		cfg.Hedge.MaxHedges = d.Hedge.MaxHedges
+	}
+
+	if cfg.MaxAttempts < 1 {
+		return nil, fmt.Errorf("retry max_attempts must be at least 1")
+	}
+	if cfg.Budget.Ratio < 0 || cfg.Budget.MinRetriesPerSecond < 0 {
+		return nil, fmt.Errorf("retry budget must not be negative")
+	}
+	if len(cfg.Hedge.Paths) > 0 && cfg.Hedge.Delay <= 0 {
+		return nil, fmt.Errorf("retry hedge delay is required when hedge paths are set")
+	}
+
+	p := &retryPolicy{
+		config:  cfg,
+		retryOn: make(map[int]bool, len(cfg.RetryOn)),
+		methods: make(map[string]bool, len(cfg.Methods)),
+	}
+	for _, code := range cfg.RetryOn {
+		p.retryOn[code] = true
+	}
+	for _, method := range cfg.Methods {
+		method = strings.ToUpper(method)
+		if method != http.MethodGet && method != http.MethodHead && method != http.MethodOptions {
+			return nil, fmt.Errorf("retry method %s is not idempotent without a body", method)
+		}
+		p.methods[method] = true
+	}
+	return p, nil
+}
+
+// retryable reports whether a request may be sent more than once.
+func (p *retryPolicy) retryable(req *http.Request) bool {
+	if !p.methods[req.Method] {
+		return false
+	}
+	return req.Body == nil || req.Body == http.NoBody
+}
+
+// hedged reports whether a request is for a latency-sensitive endpoint.
+func (p *retryPolicy) hedged(req *http.Request) bool {
+	for _, prefix := range p.config.Hedge.Paths {
+		if strings.HasPrefix(req.URL.Path, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// shouldRetry reports whether an attempt failed transiently.
+func (p *retryPolicy) shouldRetry(resp *http.Response, err error) bool {
+	if err != nil {
+		return true
+	}
+	return p.retryOn[resp.StatusCode]
+}
+
+// backoff returns the full-jitter delay before the given retry (1-based).
+func (p *retryPolicy) backoff(retry int) time.Duration {
+	ceiling := p.config.BackoffBase << (retry - 1)
+	if ceiling <= 0 || ceiling > p.config.BackoffMax {
+		ceiling = p.config.BackoffMax
+	}
+	return time.Duration(rand.Int63n(int64(ceiling) + 1))
+}
+
+// ============================================================================
+// Budget
+// ============================================================================
+
+// retryBudget counts requests and retries over a fixed window.
+type retryBudget struct {
+	mu          sync.Mutex
+	windowStart time.Time
+	requests    int
+	retries     int
+}
+
+// recordRequest counts an original request towards the budget.
+func (b *retryBudget) recordRequest(cfg RetryBudgetConfig) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.roll(cfg)
+	b.requests++
+}
+
+// withdraw reserves a retry, returning false when the budget is spent.
+func (b *retryBudget) withdraw(cfg RetryBudgetConfig) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.roll(cfg)
+
+	allowed := int(cfg.Ratio * float64(b.requests))
+	if floor := int(float64(cfg.MinRetriesPerSecond) * cfg.Window.Seconds()); allowed < floor {
+		allowed = floor
+	}
+	if b.retries >= allowed {
+		return false
+	}
+	b.retries++
+	return true
+}
+
+func (b *retryBudget) roll(cfg RetryBudgetConfig) {
+	now := time.Now()
+	if now.Sub(b.windowStart) >= cfg.Window {
+		b.windowStart = now
+		b.requests = 0
+		b.retries = 0
+	}
+}
+
+// usage returns the requests and retries counted in the current window.
+func (b *retryBudget) usage(cfg RetryBudgetConfig) (requests, retries int) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.roll(cfg)
+	return b.requests, b.retries
+}
+
+// ============================================================================
+// Transport
+// ============================================================================
+
+// retryTransport retries idempotent proxied requests on other instances of a
+// service, within the service's retry budget, and hedges slow reads.
+type retryTransport struct {
+	service string
+	routes  *RoutingTable
+	base    http.RoundTripper
+	log     *logger.Logger
+}
+
+// newRetryTransport wraps base with the retry policy of a service.
+func newRetryTransport(service string, routes *RoutingTable, base http.RoundTripper, log *logger.Logger) *retryTransport {
+	return &retryTransport{service: service, routes: routes, base: base, log: log}
+}
+
+// attemptResult is the outcome of one attempt.
+type attemptResult struct {
+	resp *http.Response
+	err  error
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *retryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	policy, budget := t.routes.retryPolicy(t.service)
+	if policy == nil || !policy.retryable(req) {
+		return t.base.RoundTrip(req)
+	}
+	budget.recordRequest(policy.config.Budget)
+
+	ctx := req.Context()
+	for attempt := 1; ; attempt++ {
+		var result attemptResult
+		if attempt == 1 && policy.hedged(req) {
+			result = t.hedge(req, policy, budget)
+		} else {
+			result = t.try(ctx, req, policy, attempt > 1)
+		}
+
+		if !policy.shouldRetry(result.resp, result.err) || attempt >= policy.config.MaxAttempts || ctx.Err() != nil {
+			return result.resp, result.err
+		}
+		if !budget.withdraw(policy.config.Budget) {
+			t.log.Warn().Str("service", t.service).Str("path", req.URL.Path).Msg("Retry budget exhausted")
+			return result.resp, result.err
+		}
+		discardResponse(result.resp)
+
+		t.log.Debug().
+			Str("service", t.service).
+			Str("path", req.URL.Path).
+			Int("attempt", attempt+1).
+			Err(result.err).
+			Msg("Retrying proxied request")
+
+		timer := time.NewTimer(policy.backoff(attempt))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// try sends one attempt under parent, on another instance of the service when
+// repick is set. The per-try timeout covers the wait for response headers; the
+// attempt context is released when the response body is closed so streamed
+// bodies are not cut off.
+func (t *retryTransport) try(parent context.Context, req *http.Request, policy *retryPolicy, repick bool) attemptResult {
+	ctx, cancel := context.WithCancel(parent)
+	timer := time.AfterFunc(policy.config.PerTryTimeout, cancel)
+
+	out := req.Clone(ctx)
+	if repick {
+		if target, err := t.routes.Pick(t.service); err == nil {
+			out.URL.Scheme = target.Scheme
+			out.URL.Host = target.Host
+		}
+	}
+
+	resp, err := t.base.RoundTrip(out)
+	if !timer.Stop() && err == nil {
+		// The per-try timeout fired as the headers arrived
+		resp.Body.Close()
+		resp, err = nil, context.DeadlineExceeded
+	}
+	if err != nil {
+		cancel()
+		return attemptResult{err: err}
+	}
+
+	resp.Body = &cancelOnCloseBody{ReadCloser: resp.Body, cancel: cancel}
+	return attemptResult{resp: resp}
+}
+
+// hedge sends the request and, each time no attempt has answered within the
+// hedge delay, another attempt to a different instance. The first response
+// that should not be retried wins; the remaining attempts are cancelled.
+func (t *retryTransport) hedge(req *http.Request, policy *retryPolicy, budget *retryBudget) attemptResult {
+	type hedgeResult struct {
+		attemptResult
+		index int
+	}
+
+	results := make(chan hedgeResult, policy.config.Hedge.MaxHedges+1)
+	var cancels []context.CancelFunc
+	launch := func(repick bool) {
+		ctx, cancel := context.WithCancel(req.Context())
+		index := len(cancels)
+		cancels = append(cancels, cancel)
+		go func() {
+			results <- hedgeResult{attemptResult: t.try(ctx, req, policy, repick), index: index}
+		}()
+	}
+
+	launch(false)
+	inflight := 1
+
+	timer := time.NewTimer(policy.config.Hedge.Delay)
+	defer timer.Stop()
+
+	// last may be returned, so its context is released when its body is
+	// closed rather than cancelled here
+	var last attemptResult
+	lastIndex := -1
+	for inflight > 0 {
+		select {
+		case result := <-results:
+			inflight--
+			if policy.shouldRetry(result.resp, result.err) {
+				discardResponse(last.resp)
+				last, lastIndex = result.attemptResult, result.index
+				if last.resp == nil {
+					cancels[result.index]()
+				} else {
+					last.resp.Body = &cancelOnCloseBody{ReadCloser: last.resp.Body, cancel: cancels[result.index]}
+				}
+				continue
+			}
+
+			// Cancel the losing attempts and release whatever they return
+			for i, cancel := range cancels {
+				if i != result.index {
+					cancel()
+				}
+			}
+			go func(pending int) {
+				for ; pending > 0; pending-- {
+					discardResponse((<-results).resp)
+				}
+			}(inflight)
+			discardResponse(last.resp)
+
+			if result.resp != nil {
+				body := result.resp.Body
+				result.resp.Body = &cancelOnCloseBody{ReadCloser: body, cancel: cancels[result.index]}
+			}
+			return result.attemptResult
+
+		case <-timer.C:
+			if len(cancels) <= policy.config.Hedge.MaxHedges && budget.withdraw(policy.config.Budget) {
+				t.log.Debug().Str("service", t.service).Str("path", req.URL.Path).Msg("Hedging proxied request")
+				launch(true)
+				inflight++
+				timer.Reset(policy.config.Hedge.Delay)
+			}
+		}
+	}
+
+	for i, cancel := range cancels {
+		if i != lastIndex || last.resp == nil {
+			cancel()
+		}
+	}
+	return last
+}
+
+// discardResponse closes a response that will not be returned to the client.
+func discardResponse(resp *http.Response) {
+	if resp == nil {
+		return
+	}
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 4096))
+	resp.Body.Close()
+}
+
+// cancelOnCloseBody releases an attempt's context once its body is closed.
+type cancelOnCloseBody struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (b *cancelOnCloseBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.cancel()
+	return err
+}
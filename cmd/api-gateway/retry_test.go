@@ -0,0 +1,301 @@
+package main
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/kilang-desa-murni/crm/pkg/logger"
+)
+
+// newTestRetryTransport routes the sales service to the given servers with
+// the given retry policy.
+func newTestRetryTransport(t *testing.T, retry RetryPolicyConfig, servers ...*httptest.Server) (*retryTransport, *RoutingTable) {
+	t.Helper()
+	endpoints := make([]ServiceEndpoint, len(servers))
+	for i, server := range servers {
+		endpoints[i] = ServiceEndpoint{URL: server.URL, Weight: 1}
+	}
+	log := logger.New(logger.Config{Level: "error"})
+	table, err := NewRoutingTable(context.Background(), &RoutingConfig{
+		Retry: retry,
+		Services: map[string]ServiceDiscoveryConfig{
+			"sales": {Discovery: DiscoveryStatic, Endpoints: endpoints},
+		},
+	}, log)
+	if err != nil {
+		t.Fatalf("NewRoutingTable() error = %v", err)
+	}
+	return newRetryTransport("sales", table, http.DefaultTransport, log), table
+}
+
+// roundTrip sends a request through the transport and reads the response.
+func roundTrip(t *testing.T, transport *retryTransport, method, url string) (int, string, error) {
+	t.Helper()
+	req := httptest.NewRequest(method, url, nil)
+	req.RequestURI = ""
+	resp, err := transport.RoundTrip(req)
+	if err != nil {
+		return 0, "", err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	return resp.StatusCode, string(body), err
+}
+
+// slowBody answers with status after the delay, flushing the headers before
+// the body so the body is still unread when the attempt is settled.
+func slowBody(w http.ResponseWriter, status int, body string, delay time.Duration) {
+	w.WriteHeader(status)
+	w.(http.Flusher).Flush()
+	time.Sleep(delay)
+	_, _ = w.Write([]byte(body))
+}
+
+func TestRetryPolicy_Backoff(t *testing.T) {
+	policy, err := compileRetryPolicy(RetryPolicyConfig{BackoffBase: 10 * time.Millisecond, BackoffMax: 50 * time.Millisecond})
+	if err != nil {
+		t.Fatalf("compileRetryPolicy() error = %v", err)
+	}
+
+	tests := []struct {
+		retry   int
+		ceiling time.Duration
+	}{
+		{1, 10 * time.Millisecond},
+		{2, 20 * time.Millisecond},
+		{3, 40 * time.Millisecond},
+		{4, 50 * time.Millisecond},
+		{64, 50 * time.Millisecond},
+	}
+
+	for _, tt := range tests {
+		var longest time.Duration
+		for i := 0; i < 500; i++ {
+			delay := policy.backoff(tt.retry)
+			if delay < 0 || delay > tt.ceiling {
+				t.Fatalf("backoff(%d) = %v, want within [0, %v]", tt.retry, delay, tt.ceiling)
+			}
+			if delay > longest {
+				longest = delay
+			}
+		}
+		// Full jitter spreads delays over the whole range
+		if longest < tt.ceiling/2 {
+			t.Errorf("backoff(%d) never exceeded %v in 500 draws", tt.retry, longest)
+		}
+	}
+}
+
+func TestRetryBudget_Withdraw(t *testing.T) {
+	cfg := RetryBudgetConfig{Ratio: 0.5, MinRetriesPerSecond: 1, Window: 2 * time.Second}
+	budget := &retryBudget{}
+
+	// The floor allows MinRetriesPerSecond * Window retries without traffic
+	for i := 0; i < 2; i++ {
+		if !budget.withdraw(cfg) {
+			t.Fatalf("withdraw %d refused within the floor", i+1)
+		}
+	}
+	if budget.withdraw(cfg) {
+		t.Fatal("expected the floor to be spent")
+	}
+
+	// Ten requests at a ratio of 0.5 allow five retries in the window
+	for i := 0; i < 10; i++ {
+		budget.recordRequest(cfg)
+	}
+	for i := 0; i < 3; i++ {
+		if !budget.withdraw(cfg) {
+			t.Fatalf("withdraw %d refused within the ratio", i+3)
+		}
+	}
+	if budget.withdraw(cfg) {
+		t.Fatal("expected the ratio to be spent")
+	}
+	if requests, retries := budget.usage(cfg); requests != 10 || retries != 5 {
+		t.Errorf("usage() = %d, %d, want 10, 5", requests, retries)
+	}
+
+	// A new window starts afresh
+	budget.windowStart = time.Now().Add(-cfg.Window)
+	if !budget.withdraw(cfg) {
+		t.Error("expected a new window to allow retries")
+	}
+	if requests, retries := budget.usage(cfg); requests != 0 || retries != 1 {
+		t.Errorf("usage() after rolling = %d, %d, want 0, 1", requests, retries)
+	}
+}
+
+func TestRetryTransport_RoundTrip(t *testing.T) {
+	tests := []struct {
+		name       string
+		method     string
+		failures   int32
+		wantStatus int
+		wantBody   string
+		wantCalls  int32
+	}{
+		{"succeeds after retries", http.MethodGet, 2, http.StatusOK, "ok", 3},
+		{"attempts used up", http.MethodGet, 5, http.StatusServiceUnavailable, "busy", 3},
+		{"non-idempotent method not retried", http.MethodPost, 1, http.StatusServiceUnavailable, "busy", 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var calls int32
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if atomic.AddInt32(&calls, 1) <= tt.failures {
+					slowBody(w, http.StatusServiceUnavailable, "busy", 10*time.Millisecond)
+					return
+				}
+				_, _ = w.Write([]byte("ok"))
+			}))
+			defer server.Close()
+
+			transport, _ := newTestRetryTransport(t, RetryPolicyConfig{MaxAttempts: 3, BackoffBase: time.Millisecond, BackoffMax: time.Millisecond}, server)
+			status, body, err := roundTrip(t, transport, tt.method, server.URL+"/api/v1/leads")
+			if err != nil {
+				t.Fatalf("RoundTrip() error = %v", err)
+			}
+			if status != tt.wantStatus || body != tt.wantBody {
+				t.Errorf("got %d %q, want %d %q", status, body, tt.wantStatus, tt.wantBody)
+			}
+			if got := atomic.LoadInt32(&calls); got != tt.wantCalls {
+				t.Errorf("backend called %d times, want %d", got, tt.wantCalls)
+			}
+		})
+	}
+}
+
+func TestRetryTransport_BudgetExhausted(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		slowBody(w, http.StatusBadGateway, "bad gateway", 10*time.Millisecond)
+	}))
+	defer server.Close()
+
+	transport, table := newTestRetryTransport(t, RetryPolicyConfig{MaxAttempts: 3}, server)
+	policy, budget := table.retryPolicy("sales")
+	for budget.withdraw(policy.config.Budget) {
+	}
+
+	status, body, err := roundTrip(t, transport, http.MethodGet, server.URL+"/api/v1/leads")
+	if err != nil {
+		t.Fatalf("RoundTrip() error = %v", err)
+	}
+	if status != http.StatusBadGateway || body != "bad gateway" {
+		t.Errorf("got %d %q, want the last response intact", status, body)
+	}
+	if got := atomic.LoadInt32(&calls); got != 1 {
+		t.Errorf("backend called %d times, want 1 with the budget spent", got)
+	}
+}
+
+func TestRetryTransport_Hedge(t *testing.T) {
+	hedge := HedgeConfig{Delay: 20 * time.Millisecond, MaxHedges: 1, Paths: []string{"/api/v1/reports/"}}
+
+	tests := []struct {
+		name        string
+		maxAttempts int
+		spendBudget bool
+		// handle answers the n-th attempt, from 1
+		handle     func(w http.ResponseWriter, n int32)
+		wantStatus int
+		wantBody   string
+		wantCalls  int32
+	}{
+		{
+			name:        "hedge wins over a slow attempt",
+			maxAttempts: 1,
+			handle: func(w http.ResponseWriter, n int32) {
+				if n == 1 {
+					time.Sleep(200 * time.Millisecond)
+				}
+				_, _ = w.Write([]byte("report"))
+			},
+			wantStatus: http.StatusOK,
+			wantBody:   "report",
+			wantCalls:  2,
+		},
+		{
+			name:        "every attempt fails",
+			maxAttempts: 1,
+			handle: func(w http.ResponseWriter, n int32) {
+				if n == 1 {
+					time.Sleep(50 * time.Millisecond)
+				}
+				slowBody(w, http.StatusServiceUnavailable, "busy", 20*time.Millisecond)
+			},
+			wantStatus: http.StatusServiceUnavailable,
+			wantBody:   "busy",
+			wantCalls:  2,
+		},
+		{
+			name:        "budget spent before the hedge",
+			maxAttempts: 3,
+			spendBudget: true,
+			handle: func(w http.ResponseWriter, n int32) {
+				time.Sleep(50 * time.Millisecond)
+				slowBody(w, http.StatusServiceUnavailable, "busy", 20*time.Millisecond)
+			},
+			wantStatus: http.StatusServiceUnavailable,
+			wantBody:   "busy",
+			wantCalls:  1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var calls int32
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				tt.handle(w, atomic.AddInt32(&calls, 1))
+			}))
+			defer server.Close()
+
+			transport, table := newTestRetryTransport(t, RetryPolicyConfig{MaxAttempts: tt.maxAttempts, Hedge: hedge}, server)
+			if tt.spendBudget {
+				policy, budget := table.retryPolicy("sales")
+				for budget.withdraw(policy.config.Budget) {
+				}
+			}
+
+			status, body, err := roundTrip(t, transport, http.MethodGet, server.URL+"/api/v1/reports/sales")
+			if err != nil {
+				t.Fatalf("RoundTrip() error = %v", err)
+			}
+			if status != tt.wantStatus || body != tt.wantBody {
+				t.Errorf("got %d %q, want %d %q", status, body, tt.wantStatus, tt.wantBody)
+			}
+			if got := atomic.LoadInt32(&calls); got != tt.wantCalls {
+				t.Errorf("backend called %d times, want %d", got, tt.wantCalls)
+			}
+		})
+	}
+}
+
+func TestCompileRetryPolicy_Invalid(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  RetryPolicyConfig
+	}{
+		{"negative attempts", RetryPolicyConfig{MaxAttempts: -1}},
+		{"negative budget", RetryPolicyConfig{Budget: RetryBudgetConfig{Ratio: -0.1}}},
+		{"hedge paths without a delay", RetryPolicyConfig{Hedge: HedgeConfig{Paths: []string{"/api/v1/reports/"}}}},
+		{"non-idempotent method", RetryPolicyConfig{Methods: []string{"get", "post"}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := compileRetryPolicy(tt.cfg); err == nil || !strings.Contains(err.Error(), "retry") {
+				t.Errorf("compileRetryPolicy() error = %v, want a retry error", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,520 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"reflect"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/spf13/viper"
+
+	"github.com/kilang-desa-murni/crm/pkg/logger"
+	"github.com/kilang-desa-murni/crm/pkg/response"
+)
+
+// defaultRoutingConfigPath is used when GATEWAY_ROUTING_CONFIG is not set.
+const defaultRoutingConfigPath = "configs/gateway/routing.yaml"
+
+// defaultRefreshInterval is how often discovered endpoints are re-resolved.
+const defaultRefreshInterval = 15 * time.Second
+
+// ErrNoEndpoints is returned when a service has no instances to route to.
+var ErrNoEndpoints = errors.New("no endpoints available")
+
+// ============================================================================
+// Configuration
+// ============================================================================
+
+// RoutingConfig describes how backend service instances are discovered and
+// how requests to them are retried.
+type RoutingConfig struct {
+	RefreshInterval time.Duration                     `mapstructure:"refresh_interval"`
+	Retry           RetryPolicyConfig                 `mapstructure:"retry"`
+	Services        map[string]ServiceDiscoveryConfig `mapstructure:"services"`
+}
+
+// ServiceDiscoveryConfig configures discovery for one service.
+type ServiceDiscoveryConfig struct {
+	// Discovery is one of static, dns_srv, consul or kubernetes.
+	Discovery string `mapstructure:"discovery"`
+	// Scheme is used for discovered endpoints. Defaults to http.
+	Scheme string `mapstructure:"scheme"`
+
+	Endpoints  []ServiceEndpoint         `mapstructure:"endpoints"`
+	SRV        string                    `mapstructure:"srv"`
+	Consul     ConsulDiscoveryConfig     `mapstructure:"consul"`
+	Kubernetes KubernetesDiscoveryConfig `mapstructure:"kubernetes"`
+
+	// Retry overrides the global retry policy field by field.
+	Retry *RetryPolicyConfig `mapstructure:"retry"`
+}
+
+// ConsulDiscoveryConfig configures Consul health API discovery.
+type ConsulDiscoveryConfig struct {
+	Address    string `mapstructure:"address"`
+	Service    string `mapstructure:"service"`
+	Tag        string `mapstructure:"tag"`
+	Datacenter string `mapstructure:"datacenter"`
+	Token      string `mapstructure:"token"`
+}
+
+// KubernetesDiscoveryConfig configures Kubernetes Endpoints discovery.
+type KubernetesDiscoveryConfig struct {
+	APIServer string `mapstructure:"api_server"`
+	Namespace string `mapstructure:"namespace"`
+	Service   string `mapstructure:"service"`
+	PortName  string `mapstructure:"port_name"`
+}
+
+// staticRoutingConfig returns a routing config with the service URLs from the environment.
+func staticRoutingConfig(urls ServiceURLs) *RoutingConfig {
+	static := func(u string) ServiceDiscoveryConfig {
+		return ServiceDiscoveryConfig{Discovery: DiscoveryStatic, Endpoints: []ServiceEndpoint{{URL: u, Weight: 1}}}
+	}
+	return &RoutingConfig{
+		RefreshInterval: defaultRefreshInterval,
+		Services: map[string]ServiceDiscoveryConfig{
+			"iam":          static(urls.IAM),
+			"customer":     static(urls.Customer),
+			"sales":        static(urls.Sales),
+			"notification": static(urls.Notification),
+		},
+	}
+}
+
+// LoadRoutingConfig reads the routing config from a YAML file. Services the file
+// does not configure fall back to the static URLs from the environment.
+func LoadRoutingConfig(path string, fallback *RoutingConfig) (*RoutingConfig, error) {
+	cfg := &RoutingConfig{}
+	if _, err := os.Stat(path); err == nil {
+		v := viper.New()
+		v.SetConfigFile(path)
+		v.SetConfigType("yaml")
+		if err := v.ReadInConfig(); err != nil {
+			return nil, fmt.Errorf("error reading routing config: %w", err)
+		}
+		if err := v.Unmarshal(cfg); err != nil {
+			return nil, fmt.Errorf("error parsing routing config: %w", err)
+		}
+	} else if !errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("error reading routing config: %w", err)
+	}
+
+	if cfg.RefreshInterval <= 0 {
+		cfg.RefreshInterval = fallback.RefreshInterval
+	}
+	if cfg.Services == nil {
+		cfg.Services = make(map[string]ServiceDiscoveryConfig)
+	}
+	for name, service := range fallback.Services {
+		if _, ok := cfg.Services[name]; !ok {
+			cfg.Services[name] = service
+		}
+	}
+	return cfg, nil
+}
+
+// ============================================================================
+// Routing Table
+// ============================================================================
+
+// RoutingTable holds the discovered endpoints of every backend service and
+// balances requests across them with smooth weighted round-robin.
+type RoutingTable struct {
+	mu              sync.RWMutex
+	services        map[string]*serviceRoute
+	refreshInterval time.Duration
+	retryPolicies   map[string]*retryPolicy
+	// retryBudgets outlive config reloads so a reload cannot reset an exhausted budget
+	retryBudgets map[string]*retryBudget
+	log          *logger.Logger
+
+	stopCh chan struct{}
+	wg     sync.WaitGroup
+	once   sync.Once
+}
+
+// serviceRoute is the routing state of one service.
+type serviceRoute struct {
+	name     string
+	config   ServiceDiscoveryConfig
+	resolver EndpointResolver
+
+	mu          sync.Mutex
+	endpoints   []*weightedEndpoint
+	lastRefresh time.Time
+	lastError   error
+}
+
+// weightedEndpoint is an endpoint with its smooth weighted round-robin state.
+type weightedEndpoint struct {
+	ServiceEndpoint
+	target  *url.URL
+	current int
+}
+
+// NewRoutingTable creates a routing table and resolves every service once.
+func NewRoutingTable(ctx context.Context, cfg *RoutingConfig, log *logger.Logger) (*RoutingTable, error) {
+	t := &RoutingTable{
+		services:     make(map[string]*serviceRoute),
+		retryBudgets: make(map[string]*retryBudget),
+		log:          log,
+		stopCh:       make(chan struct{}),
+	}
+	if err := t.Apply(ctx, cfg); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+// Apply replaces the routing config. Services whose discovery config is
+// unchanged keep their endpoints and balancing state; changed services are
+// resolved immediately. Retry policies are always replaced.
+func (t *RoutingTable) Apply(ctx context.Context, cfg *RoutingConfig) error {
+	t.mu.RLock()
+	current := t.services
+	t.mu.RUnlock()
+
+	services := make(map[string]*serviceRoute, len(cfg.Services))
+	policies := make(map[string]*retryPolicy, len(cfg.Services))
+	var changed []*serviceRoute
+	for name, serviceCfg := range cfg.Services {
+		policy, err := compileRetryPolicy(mergeRetryPolicy(cfg.Retry, serviceCfg.Retry))
+		if err != nil {
+			return fmt.Errorf("service %s: %w", name, err)
+		}
+		policies[name] = policy
+
+		serviceCfg.Retry = nil
+		if existing, ok := current[name]; ok && reflect.DeepEqual(existing.config, serviceCfg) {
+			services[name] = existing
+			continue
+		}
+
+		resolver, err := newEndpointResolver(serviceCfg)
+		if err != nil {
+			return fmt.Errorf("service %s: %w", name, err)
+		}
+		route := &serviceRoute{name: name, config: serviceCfg, resolver: resolver}
+		// Keep serving the previous endpoints until the new config resolves
+		if existing, ok := current[name]; ok {
+			route.endpoints = existing.snapshotEndpoints()
+		}
+		services[name] = route
+		changed = append(changed, route)
+	}
+
+	for _, route := range changed {
+		t.refreshService(ctx, route)
+	}
+
+	refreshInterval := cfg.RefreshInterval
+	if refreshInterval <= 0 {
+		refreshInterval = defaultRefreshInterval
+	}
+
+	t.mu.Lock()
+	t.services = services
+	t.refreshInterval = refreshInterval
+	t.retryPolicies = policies
+	for name := range services {
+		if _, ok := t.retryBudgets[name]; !ok {
+			t.retryBudgets[name] = &retryBudget{}
+		}
+	}
+	t.mu.Unlock()
+	return nil
+}
+
+// Start periodically re-resolves every service until Stop is called or ctx is cancelled.
+func (t *RoutingTable) Start(ctx context.Context) {
+	t.wg.Add(1)
+	go func() {
+		defer t.wg.Done()
+
+		t.mu.RLock()
+		interval := t.refreshInterval
+		t.mu.RUnlock()
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-t.stopCh:
+				return
+			case <-ticker.C:
+				t.Refresh(ctx)
+
+				t.mu.RLock()
+				if t.refreshInterval != interval {
+					interval = t.refreshInterval
+					ticker.Reset(interval)
+				}
+				t.mu.RUnlock()
+			}
+		}
+	}()
+}
+
+// Stop stops the refresh loop.
+func (t *RoutingTable) Stop() {
+	t.once.Do(func() {
+		close(t.stopCh)
+	})
+	t.wg.Wait()
+}
+
+// Refresh re-resolves the endpoints of every service.
+func (t *RoutingTable) Refresh(ctx context.Context) {
+	t.mu.RLock()
+	routes := make([]*serviceRoute, 0, len(t.services))
+	for _, route := range t.services {
+		routes = append(routes, route)
+	}
+	t.mu.RUnlock()
+
+	for _, route := range routes {
+		t.refreshService(ctx, route)
+	}
+}
+
+// refreshService resolves one service. On failure the previous endpoints are
+// kept, so a discovery outage does not take the service offline.
+func (t *RoutingTable) refreshService(ctx context.Context, route *serviceRoute) {
+	resolveCtx, cancel := context.WithTimeout(ctx, discoveryHTTPTimeout)
+	defer cancel()
+
+	resolved, err := route.resolver.Resolve(resolveCtx)
+	if err == nil && len(resolved) == 0 {
+		err = ErrNoEndpoints
+	}
+
+	var endpoints []*weightedEndpoint
+	if err == nil {
+		endpoints, err = newWeightedEndpoints(resolved)
+	}
+
+	route.mu.Lock()
+	defer route.mu.Unlock()
+
+	route.lastRefresh = time.Now().UTC()
+	route.lastError = err
+	if err != nil {
+		t.log.Warn().Err(err).Str("service", route.name).Msg("Service discovery failed, keeping previous endpoints")
+		return
+	}
+
+	if !sameEndpoints(route.endpoints, endpoints) {
+		t.log.Info().
+			Str("service", route.name).
+			Int("endpoints", len(endpoints)).
+			Msg("Service endpoints updated")
+		route.endpoints = endpoints
+	}
+}
+
+// Pick returns the target URL of the next instance of a service.
+func (t *RoutingTable) Pick(service string) (*url.URL, error) {
+	t.mu.RLock()
+	route, ok := t.services[service]
+	t.mu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("unknown service %q", service)
+	}
+	return route.pick()
+}
+
+// retryPolicy returns the retry policy and budget of a service, or a nil
+// policy for an unknown service.
+func (t *RoutingTable) retryPolicy(service string) (*retryPolicy, *retryBudget) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	return t.retryPolicies[service], t.retryBudgets[service]
+}
+
+// pick selects an endpoint with smooth weighted round-robin, which spreads
+// requests evenly instead of sending bursts to the heaviest instance.
+func (r *serviceRoute) pick() (*url.URL, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if len(r.endpoints) == 0 {
+		return nil, ErrNoEndpoints
+	}
+
+	total := 0
+	var best *weightedEndpoint
+	for _, ep := range r.endpoints {
+		ep.current += ep.Weight
+		total += ep.Weight
+		if best == nil || ep.current > best.current {
+			best = ep
+		}
+	}
+	best.current -= total
+	return best.target, nil
+}
+
+// snapshotEndpoints copies the endpoints with their balancing state, so a
+// route built from them does not share state guarded by another route's mutex.
+func (r *serviceRoute) snapshotEndpoints() []*weightedEndpoint {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	endpoints := make([]*weightedEndpoint, len(r.endpoints))
+	for i, ep := range r.endpoints {
+		copied := *ep
+		endpoints[i] = &copied
+	}
+	return endpoints
+}
+
+func newWeightedEndpoints(endpoints []ServiceEndpoint) ([]*weightedEndpoint, error) {
+	result := make([]*weightedEndpoint, 0, len(endpoints))
+	for _, ep := range endpoints {
+		target, err := url.Parse(ep.URL)
+		if err != nil {
+			return nil, fmt.Errorf("invalid endpoint url %q: %w", ep.URL, err)
+		}
+		if ep.Weight <= 0 {
+			ep.Weight = 1
+		}
+		result = append(result, &weightedEndpoint{ServiceEndpoint: ep, target: target})
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].URL < result[j].URL })
+	return result, nil
+}
+
+func sameEndpoints(a, b []*weightedEndpoint) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i].ServiceEndpoint != b[i].ServiceEndpoint {
+			return false
+		}
+	}
+	return true
+}
+
+// ============================================================================
+// Hot Reload
+// ============================================================================
+
+// WatchRoutingConfig reloads the routing config whenever the file changes.
+// Invalid configs are logged and ignored so the current routes stay in place.
+func WatchRoutingConfig(ctx context.Context, path string, fallback *RoutingConfig, table *RoutingTable, log *logger.Logger) {
+	if _, err := os.Stat(path); err != nil {
+		return
+	}
+
+	v := viper.New()
+	v.SetConfigFile(path)
+	v.SetConfigType("yaml")
+	v.OnConfigChange(func(e fsnotify.Event) {
+		cfg, err := LoadRoutingConfig(path, fallback)
+		if err == nil {
+			err = table.Apply(ctx, cfg)
+		}
+		if err != nil {
+			log.Error().Err(err).Str("path", path).Msg("Failed to reload routing config")
+			return
+		}
+		log.Info().Str("path", path).Int("services", len(cfg.Services)).Msg("Routing config reloaded")
+	})
+	v.WatchConfig()
+}
+
+// ============================================================================
+// Admin
+// ============================================================================
+
+// RoutingTableSnapshot is the admin view of the routing table.
+type RoutingTableSnapshot struct {
+	RefreshInterval string                 `json:"refresh_interval"`
+	Services        []ServiceRouteSnapshot `json:"services"`
+}
+
+// ServiceRouteSnapshot is the admin view of one service's routes.
+type ServiceRouteSnapshot struct {
+	Name        string            `json:"name"`
+	Discovery   string            `json:"discovery"`
+	Endpoints   []ServiceEndpoint `json:"endpoints"`
+	LastRefresh *time.Time        `json:"last_refresh,omitempty"`
+	LastError   string            `json:"last_error,omitempty"`
+	Retry       *RetrySnapshot    `json:"retry,omitempty"`
+}
+
+// RetrySnapshot is the admin view of a service's retry policy and budget usage
+// in the current budget window.
+type RetrySnapshot struct {
+	MaxAttempts    int      `json:"max_attempts"`
+	PerTryTimeout  string   `json:"per_try_timeout"`
+	HedgePaths     []string `json:"hedge_paths,omitempty"`
+	WindowRequests int      `json:"window_requests"`
+	WindowRetries  int      `json:"window_retries"`
+}
+
+// Snapshot returns the current routing table, sorted by service name.
+func (t *RoutingTable) Snapshot() RoutingTableSnapshot {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	snapshot := RoutingTableSnapshot{
+		RefreshInterval: t.refreshInterval.String(),
+		Services:        make([]ServiceRouteSnapshot, 0, len(t.services)),
+	}
+	for name, route := range t.services {
+		route.mu.Lock()
+		service := ServiceRouteSnapshot{
+			Name:      name,
+			Discovery: route.config.Discovery,
+			Endpoints: make([]ServiceEndpoint, len(route.endpoints)),
+		}
+		if service.Discovery == "" {
+			service.Discovery = DiscoveryStatic
+		}
+		for i, ep := range route.endpoints {
+			service.Endpoints[i] = ep.ServiceEndpoint
+		}
+		if !route.lastRefresh.IsZero() {
+			lastRefresh := route.lastRefresh
+			service.LastRefresh = &lastRefresh
+		}
+		if route.lastError != nil {
+			service.LastError = route.lastError.Error()
+		}
+		route.mu.Unlock()
+
+		if policy := t.retryPolicies[name]; policy != nil {
+			requests, retries := t.retryBudgets[name].usage(policy.config.Budget)
+			service.Retry = &RetrySnapshot{
+				MaxAttempts:    policy.config.MaxAttempts,
+				PerTryTimeout:  policy.config.PerTryTimeout.String(),
+				HedgePaths:     policy.config.Hedge.Paths,
+				WindowRequests: requests,
+				WindowRetries:  retries,
+			}
+		}
+
+		snapshot.Services = append(snapshot.Services, service)
+	}
+	sort.Slice(snapshot.Services, func(i, j int) bool { return snapshot.Services[i].Name < snapshot.Services[j].Name })
+	return snapshot
+}
+
+// routingTableHandler serves GET /admin/routing.
+func routingTableHandler(table *RoutingTable) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		response.OK(w, table.Snapshot())
+	}
+}
@@ -0,0 +1,124 @@
+package main
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/kilang-desa-murni/crm/pkg/logger"
+)
+
+func newTestRoutingTable(t *testing.T, endpoints ...ServiceEndpoint) *RoutingTable {
+	t.Helper()
+	table, err := NewRoutingTable(context.Background(), &RoutingConfig{
+		Services: map[string]ServiceDiscoveryConfig{
+			"sales": {Discovery: DiscoveryStatic, Endpoints: endpoints},
+		},
+	}, logger.New(logger.Config{Level: "error"}))
+	if err != nil {
+		t.Fatalf("NewRoutingTable() error = %v", err)
+	}
+	return table
+}
+
+func pickHosts(t *testing.T, table *RoutingTable, n int) string {
+	t.Helper()
+	hosts := make([]string, 0, n)
+	for i := 0; i < n; i++ {
+		target, err := table.Pick("sales")
+		if err != nil {
+			t.Fatalf("Pick() error = %v", err)
+		}
+		hosts = append(hosts, target.Hostname())
+	}
+	return strings.Join(hosts, ",")
+}
+
+func TestRoutingTable_WeightedRoundRobin(t *testing.T) {
+	tests := []struct {
+		name      string
+		endpoints []ServiceEndpoint
+		want      string
+	}{
+		{
+			name:      "equal weights alternate",
+			endpoints: []ServiceEndpoint{{URL: "http://b:8082", Weight: 1}, {URL: "http://a:8082", Weight: 1}},
+			want:      "a,b,a,b",
+		},
+		{
+			name: "heavy endpoint is spread out",
+			endpoints: []ServiceEndpoint{
+				{URL: "http://a:8082", Weight: 5},
+				{URL: "http://b:8082", Weight: 1},
+				{URL: "http://c:8082", Weight: 1},
+			},
+			want: "a,a,b,a,c,a,a",
+		},
+		{
+			name:      "missing weight counts as one",
+			endpoints: []ServiceEndpoint{{URL: "http://a:8082", Weight: 2}, {URL: "http://b:8082"}},
+			want:      "a,b,a,a,b,a",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			table := newTestRoutingTable(t, tt.endpoints...)
+			if got := pickHosts(t, table, strings.Count(tt.want, ",")+1); got != tt.want {
+				t.Errorf("picks = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRoutingTable_PickUnknownService(t *testing.T) {
+	table := newTestRoutingTable(t, ServiceEndpoint{URL: "http://a:8082"})
+	if _, err := table.Pick("billing"); err == nil {
+		t.Error("expected an error for an unknown service")
+	}
+}
+
+func TestRoutingTable_ApplyCopiesEndpoints(t *testing.T) {
+	table := newTestRoutingTable(t,
+		ServiceEndpoint{URL: "http://a:8082", Weight: 2},
+		ServiceEndpoint{URL: "http://b:8082", Weight: 1},
+	)
+	old := table.services["sales"]
+	pickHosts(t, table, 1)
+
+	// Switching to a discovery that cannot resolve keeps the previous endpoints
+	err := table.Apply(context.Background(), &RoutingConfig{
+		Services: map[string]ServiceDiscoveryConfig{
+			"sales": {Discovery: DiscoveryConsul, Consul: ConsulDiscoveryConfig{Address: "http://127.0.0.1:1", Service: "sales"}},
+		},
+	})
+	if err != nil {
+		t.Fatalf("Apply() error = %v", err)
+	}
+	route := table.services["sales"]
+	if route == old || len(route.endpoints) != len(old.endpoints) {
+		t.Fatalf("expected a new route keeping %d endpoints, got %d", len(old.endpoints), len(route.endpoints))
+	}
+	for i := range route.endpoints {
+		if route.endpoints[i] == old.endpoints[i] {
+			t.Errorf("endpoint %s is shared with the replaced route", route.endpoints[i].URL)
+		}
+		if route.endpoints[i].current != old.endpoints[i].current {
+			t.Errorf("expected the balancing state of %s to carry over", route.endpoints[i].URL)
+		}
+	}
+
+	// Requests still in flight on the old route must not race the new one
+	var wg sync.WaitGroup
+	for _, r := range []*serviceRoute{old, route} {
+		wg.Add(1)
+		go func(r *serviceRoute) {
+			defer wg.Done()
+			for i := 0; i < 100; i++ {
+				_, _ = r.pick()
+			}
+		}(r)
+	}
+	wg.Wait()
+}
@@ -0,0 +1,252 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/kilang-desa-murni/crm/pkg/config"
+	apperrors "github.com/kilang-desa-murni/crm/pkg/errors"
+	"github.com/kilang-desa-murni/crm/pkg/logger"
+	"github.com/kilang-desa-murni/crm/pkg/middleware"
+	"github.com/kilang-desa-murni/crm/pkg/response"
+)
+
+// sessionModeHeader is set to "cookie" by clients asking for a cookie
+// session while the gateway accepts both session modes.
+const sessionModeHeader = "X-Session-Mode"
+
+// ============================================================================
+// Configuration
+// ============================================================================
+
+// loadSessionConfig reads the session mode and cookie settings from the
+// environment. Cookie sessions need GATEWAY_CSRF_SECRET, shared by every
+// gateway instance.
+func loadSessionConfig(cfg *config.Config) (middleware.SessionConfig, error) {
+	session := middleware.DefaultSessionConfig()
+	session.Mode = middleware.SessionMode(strings.ToLower(getEnv("GATEWAY_SESSION_MODE", string(session.Mode))))
+	session.Domain = getEnv("GATEWAY_SESSION_COOKIE_DOMAIN", "")
+	session.CSRFSecret = []byte(getEnv("GATEWAY_CSRF_SECRET", ""))
+	if cfg.JWT.RefreshExpiry > 0 {
+		session.RefreshMaxAge = cfg.JWT.RefreshExpiry
+	}
+
+	switch session.Mode {
+	case middleware.SessionModeBearer, middleware.SessionModeCookie, middleware.SessionModeBoth:
+	default:
+		return session, fmt.Errorf("invalid GATEWAY_SESSION_MODE %q, want bearer, cookie or both", session.Mode)
+	}
+
+	if value := getEnv("GATEWAY_SESSION_COOKIE_SECURE", ""); value != "" {
+		secure, err := strconv.ParseBool(value)
+		if err != nil {
+			return session, fmt.Errorf("invalid GATEWAY_SESSION_COOKIE_SECURE: %w", err)
+		}
+		session.Secure = secure
+	}
+
+	switch strings.ToLower(getEnv("GATEWAY_SESSION_SAMESITE", "lax")) {
+	case "lax":
+		session.SameSite = http.SameSiteLaxMode
+	case "strict":
+		session.SameSite = http.SameSiteStrictMode
+	case "none":
+		if !session.Secure {
+			return session, fmt.Errorf("GATEWAY_SESSION_SAMESITE none requires secure cookies")
+		}
+		session.SameSite = http.SameSiteNoneMode
+	default:
+		return session, fmt.Errorf("invalid GATEWAY_SESSION_SAMESITE, want lax, strict or none")
+	}
+
+	if session.Mode.AllowsCookie() && len(session.CSRFSecret) < 32 {
+		return session, fmt.Errorf("cookie sessions need a GATEWAY_CSRF_SECRET of at least 32 bytes")
+	}
+	return session, nil
+}
+
+// ============================================================================
+// Session Handler
+// ============================================================================
+
+// sessionHandler fronts the IAM service's auth endpoints. For cookie sessions
+// it moves the tokens of login and refresh responses into httpOnly cookies,
+// issuing a CSRF token in their place, takes the refresh token of refresh and
+// logout requests from the cookie, and clears the cookies on logout. Bearer
+// clients are proxied unchanged.
+type sessionHandler struct {
+	iam    http.Handler
+	config middleware.SessionConfig
+	log    *logger.Logger
+}
+
+func newSessionHandler(iam http.Handler, config middleware.SessionConfig, log *logger.Logger) *sessionHandler {
+	return &sessionHandler{iam: iam, config: config, log: log}
+}
+
+// usesCookies reports whether a request is made for a cookie session.
+func (h *sessionHandler) usesCookies(r *http.Request) bool {
+	switch h.config.Mode {
+	case middleware.SessionModeCookie:
+		return true
+	case middleware.SessionModeBoth:
+		return strings.EqualFold(r.Header.Get(sessionModeHeader), "cookie")
+	}
+	return false
+}
+
+func (h *sessionHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost || !h.usesCookies(r) {
+		h.iam.ServeHTTP(w, r)
+		return
+	}
+
+	switch {
+	case strings.HasSuffix(r.URL.Path, "/auth/login"), strings.HasSuffix(r.URL.Path, "/auth/register"):
+		h.issue(w, r)
+	case strings.HasSuffix(r.URL.Path, "/auth/refresh"):
+		// Refresh is a public endpoint, so its CSRF token is checked here
+		if err := middleware.VerifyCSRF(r, h.config); err != nil {
+			response.Error(w, err)
+			return
+		}
+		if !h.withRefreshToken(r) {
+			response.Error(w, apperrors.ErrUnauthorized("Missing session"))
+			return
+		}
+		h.issue(w, r)
+	case strings.HasSuffix(r.URL.Path, "/auth/logout"):
+		h.withRefreshToken(r)
+		captured := h.capture(r)
+		if captured.status < http.StatusInternalServerError {
+			middleware.ClearSessionCookies(w, h.config)
+		}
+		captured.writeTo(w, captured.body.Bytes())
+	default:
+		h.iam.ServeHTTP(w, r)
+	}
+}
+
+// issue proxies a request returning a token pair and moves the tokens into
+// the session cookies.
+func (h *sessionHandler) issue(w http.ResponseWriter, r *http.Request) {
+	captured := h.capture(r)
+	if captured.status != http.StatusOK && captured.status != http.StatusCreated {
+		if captured.status == http.StatusUnauthorized {
+			middleware.ClearSessionCookies(w, h.config)
+		}
+		captured.writeTo(w, captured.body.Bytes())
+		return
+	}
+
+	var envelope map[string]json.RawMessage
+	var data map[string]interface{}
+	if err := json.Unmarshal(captured.body.Bytes(), &envelope); err != nil || json.Unmarshal(envelope["data"], &data) != nil {
+		h.log.Error().Str("path", r.URL.Path).Msg("Unexpected token response from IAM service")
+		response.Error(w, apperrors.ErrServiceUnavailable("iam"))
+		return
+	}
+
+	accessToken, _ := data["access_token"].(string)
+	refreshToken, _ := data["refresh_token"].(string)
+	expiresIn, _ := data["expires_in"].(float64)
+	if accessToken == "" || refreshToken == "" {
+		captured.writeTo(w, captured.body.Bytes())
+		return
+	}
+
+	csrfToken, err := middleware.SetSessionCookies(w, h.config, accessToken, refreshToken, time.Duration(expiresIn)*time.Second)
+	if err != nil {
+		response.Error(w, apperrors.ErrInternalWrap(err, "Failed to issue CSRF token"))
+		return
+	}
+
+	// The tokens stay out of reach of scripts; the client gets the CSRF
+	// token to echo in its requests instead
+	delete(data, "access_token")
+	delete(data, "refresh_token")
+	data["csrf_token"] = csrfToken
+	data["csrf_header"] = h.config.CSRFHeader
+
+	rewritten, err := json.Marshal(data)
+	if err != nil {
+		response.Error(w, apperrors.ErrInternalWrap(err, "Failed to encode session response"))
+		return
+	}
+	envelope["data"] = rewritten
+	body, err := json.Marshal(envelope)
+	if err != nil {
+		response.Error(w, apperrors.ErrInternalWrap(err, "Failed to encode session response"))
+		return
+	}
+	captured.writeTo(w, body)
+}
+
+// withRefreshToken replaces the request body with the refresh token of the
+// session cookie. It reports false when there is no session cookie.
+func (h *sessionHandler) withRefreshToken(r *http.Request) bool {
+	cookie, err := r.Cookie(h.config.RefreshCookie)
+	if err != nil || cookie.Value == "" {
+		return false
+	}
+
+	body, _ := json.Marshal(map[string]string{"refresh_token": cookie.Value})
+	r.Body = io.NopCloser(bytes.NewReader(body))
+	r.ContentLength = int64(len(body))
+	r.Header.Set("Content-Type", "application/json")
+	r.Header.Set("Content-Length", strconv.Itoa(len(body)))
+	return true
+}
+
+// capturedResponse is an IAM response held back to be rewritten.
+type capturedResponse struct {
+	header http.Header
+	status int
+	body   bytes.Buffer
+}
+
+func (c *capturedResponse) Header() http.Header { return c.header }
+
+func (c *capturedResponse) WriteHeader(status int) {
+	if c.status == 0 {
+		c.status = status
+	}
+}
+
+func (c *capturedResponse) Write(b []byte) (int, error) {
+	if c.status == 0 {
+		c.status = http.StatusOK
+	}
+	return c.body.Write(b)
+}
+
+// capture proxies the request to the IAM service and returns its response.
+func (h *sessionHandler) capture(r *http.Request) *capturedResponse {
+	captured := &capturedResponse{header: make(http.Header)}
+	h.iam.ServeHTTP(captured, r)
+	if captured.status == 0 {
+		captured.status = http.StatusOK
+	}
+	return captured
+}
+
+// writeTo writes the captured response with body in place of its own.
+func (c *capturedResponse) writeTo(w http.ResponseWriter, body []byte) {
+	for name, values := range c.header {
+		if name == "Content-Length" {
+			continue
+		}
+		for _, value := range values {
+			w.Header().Add(name, value)
+		}
+	}
+	w.Header().Set("Content-Length", strconv.Itoa(len(body)))
+	w.WriteHeader(c.status)
+	w.Write(body)
+}
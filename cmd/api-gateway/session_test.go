@@ -0,0 +1,112 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/kilang-desa-murni/crm/pkg/logger"
+	"github.com/kilang-desa-murni/crm/pkg/middleware"
+)
+
+// fakeIAM answers login, register and refresh with a token pair.
+func fakeIAM(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	_, _ = w.Write([]byte(`{"success":true,"data":{"access_token":"access-1","refresh_token":"refresh-1","expires_in":900,"user":{"id":"u1"}}}`))
+}
+
+func testGatewaySessionConfig(mode middleware.SessionMode) middleware.SessionConfig {
+	config := middleware.DefaultSessionConfig()
+	config.Mode = mode
+	config.CSRFSecret = []byte("0123456789abcdef0123456789abcdef")
+	return config
+}
+
+func TestSessionHandler_Issue(t *testing.T) {
+	tests := []struct {
+		name        string
+		mode        middleware.SessionMode
+		sessionMode string
+		wantCookies bool
+	}{
+		{"cookie mode", middleware.SessionModeCookie, "", true},
+		{"both modes asking for cookies", middleware.SessionModeBoth, "cookie", true},
+		{"both modes asking for bearer", middleware.SessionModeBoth, "", false},
+		{"bearer mode", middleware.SessionModeBearer, "cookie", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := testGatewaySessionConfig(tt.mode)
+			handler := newSessionHandler(http.HandlerFunc(fakeIAM), config, logger.New(logger.Config{Level: "error"}))
+
+			r := httptest.NewRequest(http.MethodPost, "/api/v1/auth/login", strings.NewReader(`{"email":"a@b.my","password":"secret"}`))
+			if tt.sessionMode != "" {
+				r.Header.Set(sessionModeHeader, tt.sessionMode)
+			}
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, r)
+
+			if w.Code != http.StatusOK {
+				t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+			}
+
+			var body struct {
+				Data map[string]interface{} `json:"data"`
+			}
+			if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+				t.Fatalf("failed to decode response: %v", err)
+			}
+			cookies := make(map[string]*http.Cookie)
+			for _, cookie := range w.Result().Cookies() {
+				cookies[cookie.Name] = cookie
+			}
+
+			if !tt.wantCookies {
+				if body.Data["access_token"] != "access-1" || len(cookies) != 0 {
+					t.Errorf("expected the bearer response unchanged, got %v with cookies %v", body.Data, cookies)
+				}
+				return
+			}
+
+			for _, field := range []string{"access_token", "refresh_token"} {
+				if _, ok := body.Data[field]; ok {
+					t.Errorf("expected %s to be dropped from the body", field)
+				}
+			}
+			if body.Data["csrf_token"] == nil || body.Data["csrf_header"] != config.CSRFHeader || body.Data["user"] == nil {
+				t.Errorf("expected the CSRF token with the rest of the response, got %v", body.Data)
+			}
+
+			for name, want := range map[string]string{config.AccessCookie: "access-1", config.RefreshCookie: "refresh-1"} {
+				cookie := cookies[name]
+				if cookie == nil || cookie.Value != want || !cookie.HttpOnly || !cookie.Secure {
+					t.Errorf("expected a secure httpOnly %s cookie, got %+v", name, cookie)
+				}
+			}
+			csrf := cookies[config.CSRFCookie]
+			if csrf == nil || csrf.HttpOnly || csrf.Value != body.Data["csrf_token"] {
+				t.Errorf("expected a script-readable CSRF cookie matching the body, got %+v", csrf)
+			}
+			if !middleware.VerifyCSRFToken(config.CSRFSecret, csrf.Value, "refresh-1") {
+				t.Error("expected the CSRF token to belong to the new session")
+			}
+		})
+	}
+}
+
+func TestSessionHandler_RefreshRequiresCSRF(t *testing.T) {
+	config := testGatewaySessionConfig(middleware.SessionModeCookie)
+	handler := newSessionHandler(http.HandlerFunc(fakeIAM), config, logger.New(logger.Config{Level: "error"}))
+
+	r := httptest.NewRequest(http.MethodPost, "/api/v1/auth/refresh", nil)
+	r.AddCookie(&http.Cookie{Name: config.RefreshCookie, Value: "refresh-0"})
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, r)
+
+	if w.Code != http.StatusForbidden {
+		t.Errorf("expected 403 without the CSRF header, got %d", w.Code)
+	}
+}
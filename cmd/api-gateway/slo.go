@@ -0,0 +1,33 @@
+package main
+
+import (
+	"context"
+
+	"github.com/kilang-desa-murni/crm/pkg/events"
+	"github.com/kilang-desa-murni/crm/pkg/slo"
+)
+
+// defaultSLOConfigPath is used when GATEWAY_SLO_CONFIG is not set.
+const defaultSLOConfigPath = "configs/gateway/slo.yaml"
+
+// sloAlertNotifier publishes burn rate alerts for the notification service
+// to email to the recipients.
+func sloAlertNotifier(publisher events.Publisher, recipients []string) slo.Notifier {
+	return func(ctx context.Context, alert slo.Alert) error {
+		event := events.NewEvent(events.EventTypeSLOBurnRateAlert, "", alert.Objective, map[string]interface{}{
+			"objective":              alert.Objective,
+			"description":            alert.Description,
+			"severity":               alert.Severity,
+			"status":                 alert.Status,
+			"target":                 alert.Target,
+			"burn_rate":              alert.BurnRate,
+			"short_burn_rate":        alert.ShortBurnRate,
+			"threshold":              alert.Threshold,
+			"long_window":            alert.LongWindow,
+			"short_window":           alert.ShortWindow,
+			"error_budget_remaining": alert.ErrorBudgetRemaining,
+			"recipients":             recipients,
+		})
+		return publisher.Publish(ctx, event.WithMetadata("source", "api-gateway"))
+	}
+}
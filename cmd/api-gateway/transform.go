@@ -0,0 +1,393 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/viper"
+
+	apperrors "github.com/kilang-desa-murni/crm/pkg/errors"
+	"github.com/kilang-desa-murni/crm/pkg/middleware"
+	"github.com/kilang-desa-murni/crm/pkg/response"
+)
+
+// defaultTransformConfigPath is used when GATEWAY_TRANSFORM_CONFIG is not set.
+const defaultTransformConfigPath = "configs/gateway/transforms.yaml"
+
+// ============================================================================
+// Configuration
+// ============================================================================
+
+// TransformConfig is the YAML-driven request/response transformation policy
+// applied to every request before it is proxied to a backend service.
+type TransformConfig struct {
+	// Global rules apply to every request, before any route rules.
+	Global TransformRules `mapstructure:"global"`
+	// Routes are matched in file order; the first match wins.
+	Routes []RouteTransform `mapstructure:"routes"`
+}
+
+// TransformRules are the header and body rules shared by global and route policies.
+type TransformRules struct {
+	RequestHeaders  HeaderRules `mapstructure:"request_headers"`
+	ResponseHeaders HeaderRules `mapstructure:"response_headers"`
+	// MaxBodySize limits the request body, e.g. "512KB" or "10MB". Empty means unlimited.
+	MaxBodySize string `mapstructure:"max_body_size"`
+}
+
+// RouteTransform is the policy for requests matching a path prefix. A "*"
+// segment in the prefix matches any one path segment, e.g.
+// "/api/v1/opportunities/*/attachments".
+type RouteTransform struct {
+	Name       string       `mapstructure:"name"`
+	PathPrefix string       `mapstructure:"path_prefix"`
+	Methods    []string     `mapstructure:"methods"`
+	Rewrite    *PathRewrite `mapstructure:"rewrite"`
+
+	TransformRules `mapstructure:",squash"`
+}
+
+// PathRewrite rewrites the request path. With a pattern, the path is rewritten
+// with regexp.ReplaceAllString; without one, the route path prefix is replaced.
+type PathRewrite struct {
+	Pattern     string `mapstructure:"pattern"`
+	Replacement string `mapstructure:"replacement"`
+}
+
+// HeaderRules modify headers. Remove runs first, then Set, then Add.
+// Remove entries ending in "*" match every header with that prefix.
+// Values may reference ${tenant_id}, ${user_id}, ${request_id} and ${route};
+// a header whose value expands to an empty string is skipped.
+type HeaderRules struct {
+	Add    map[string]string `mapstructure:"add"`
+	Set    map[string]string `mapstructure:"set"`
+	Remove []string          `mapstructure:"remove"`
+}
+
+// LoadTransformConfig reads the transformation policy from a YAML file.
+// A missing file yields an empty policy, so the gateway proxies requests unchanged.
+func LoadTransformConfig(path string) (*TransformConfig, error) {
+	cfg := &TransformConfig{}
+	if _, err := os.Stat(path); errors.Is(err, os.ErrNotExist) {
+		return cfg, nil
+	}
+
+	v := viper.New()
+	v.SetConfigFile(path)
+	v.SetConfigType("yaml")
+	if err := v.ReadInConfig(); err != nil {
+		return nil, fmt.Errorf("error reading transform config: %w", err)
+	}
+	if err := v.Unmarshal(cfg); err != nil {
+		return nil, fmt.Errorf("error parsing transform config: %w", err)
+	}
+	return cfg, nil
+}
+
+// ============================================================================
+// Engine
+// ============================================================================
+
+// TransformEngine applies a compiled transformation policy to requests.
+type TransformEngine struct {
+	global *compiledRules
+	routes []*compiledRoute
+}
+
+type compiledRules struct {
+	requestHeaders  HeaderRules
+	responseHeaders HeaderRules
+	maxBodySize     int64
+}
+
+type compiledRoute struct {
+	name       string
+	pathPrefix string
+	methods    map[string]bool
+	rewrite    *PathRewrite
+	pattern    *regexp.Regexp
+	rules      *compiledRules
+	// segments is set when the prefix has "*" segments and is matched
+	// segment by segment
+	segments []string
+}
+
+// NewTransformEngine validates and compiles a transformation policy.
+func NewTransformEngine(cfg *TransformConfig) (*TransformEngine, error) {
+	global, err := compileRules(cfg.Global)
+	if err != nil {
+		return nil, fmt.Errorf("global: %w", err)
+	}
+
+	engine := &TransformEngine{global: global}
+	for i, route := range cfg.Routes {
+		name := route.Name
+		if name == "" {
+			name = fmt.Sprintf("routes[%d]", i)
+		}
+		if route.PathPrefix == "" {
+			return nil, fmt.Errorf("%s: path_prefix is required", name)
+		}
+
+		rules, err := compileRules(route.TransformRules)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", name, err)
+		}
+
+		compiled := &compiledRoute{
+			name:       name,
+			pathPrefix: route.PathPrefix,
+			rewrite:    route.Rewrite,
+			rules:      rules,
+		}
+		if len(route.Methods) > 0 {
+			compiled.methods = make(map[string]bool, len(route.Methods))
+			for _, method := range route.Methods {
+				compiled.methods[strings.ToUpper(method)] = true
+			}
+		}
+		if strings.Contains(route.PathPrefix, "*") {
+			compiled.segments = strings.Split(strings.Trim(route.PathPrefix, "/"), "/")
+			for _, segment := range compiled.segments {
+				if _, err := path.Match(segment, ""); err != nil {
+					return nil, fmt.Errorf("%s: invalid path_prefix: %w", name, err)
+				}
+			}
+			// The matched prefix is not a fixed string that can be replaced
+			if route.Rewrite != nil && route.Rewrite.Pattern == "" {
+				return nil, fmt.Errorf("%s: a path_prefix with \"*\" segments needs a rewrite pattern", name)
+			}
+		}
+		if route.Rewrite != nil && route.Rewrite.Pattern != "" {
+			compiled.pattern, err = regexp.Compile(route.Rewrite.Pattern)
+			if err != nil {
+				return nil, fmt.Errorf("%s: invalid rewrite pattern: %w", name, err)
+			}
+		}
+
+		engine.routes = append(engine.routes, compiled)
+	}
+
+	return engine, nil
+}
+
+func compileRules(rules TransformRules) (*compiledRules, error) {
+	size, err := parseByteSize(rules.MaxBodySize)
+	if err != nil {
+		return nil, fmt.Errorf("invalid max_body_size: %w", err)
+	}
+	return &compiledRules{
+		requestHeaders:  rules.RequestHeaders,
+		responseHeaders: rules.ResponseHeaders,
+		maxBodySize:     size,
+	}, nil
+}
+
+// Middleware applies the policy to requests before they reach the proxies.
+// It must run after authentication so tenant and user headers can be injected.
+func (e *TransformEngine) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		route := e.match(r)
+
+		// Body size limit; the route limit overrides the global one
+		limit := e.global.maxBodySize
+		if route != nil && route.rules.maxBodySize > 0 {
+			limit = route.rules.maxBodySize
+		}
+		if limit > 0 {
+			if r.ContentLength > limit {
+				response.Error(w, apperrors.ErrPayloadTooLarge(limit))
+				return
+			}
+			r.Body = http.MaxBytesReader(w, r.Body, limit)
+		}
+
+		// Request headers
+		routeName := ""
+		if route != nil {
+			routeName = route.name
+		}
+		vars := requestVars(r, routeName)
+		applyHeaderRules(r.Header, e.global.requestHeaders, vars)
+		if route != nil {
+			applyHeaderRules(r.Header, route.rules.requestHeaders, vars)
+		}
+
+		// Path rewrite
+		if route != nil && route.rewrite != nil {
+			path := route.rewritePath(r.URL.Path)
+			if path != r.URL.Path {
+				r.URL.Path = path
+				r.URL.RawPath = ""
+			}
+		}
+
+		// Response headers are applied when the backend response is written
+		rules := []HeaderRules{e.global.responseHeaders}
+		if route != nil {
+			rules = append(rules, route.rules.responseHeaders)
+		}
+		next.ServeHTTP(&headerRewriteWriter{ResponseWriter: w, rules: rules, vars: vars}, r)
+	})
+}
+
+// match returns the first route matching the request, or nil.
+func (e *TransformEngine) match(r *http.Request) *compiledRoute {
+	for _, route := range e.routes {
+		if !route.matchesPath(r.URL.Path) {
+			continue
+		}
+		if route.methods != nil && !route.methods[r.Method] {
+			continue
+		}
+		return route
+	}
+	return nil
+}
+
+// matchesPath reports whether urlPath is below the route's path prefix.
+func (c *compiledRoute) matchesPath(urlPath string) bool {
+	if c.segments == nil {
+		return strings.HasPrefix(urlPath, c.pathPrefix)
+	}
+
+	segments := strings.Split(strings.Trim(urlPath, "/"), "/")
+	if len(segments) < len(c.segments) {
+		return false
+	}
+	for i, pattern := range c.segments {
+		if ok, _ := path.Match(pattern, segments[i]); !ok {
+			return false
+		}
+	}
+	return true
+}
+
+func (c *compiledRoute) rewritePath(path string) string {
+	if c.pattern != nil {
+		return c.pattern.ReplaceAllString(path, c.rewrite.Replacement)
+	}
+	return c.rewrite.Replacement + strings.TrimPrefix(path, c.pathPrefix)
+}
+
+// ============================================================================
+// Header Rules
+// ============================================================================
+
+// requestVars returns the values available to header rule templates.
+func requestVars(r *http.Request, route string) map[string]string {
+	ctx := r.Context()
+	return map[string]string{
+		"tenant_id":  middleware.TenantIDFromContext(ctx),
+		"user_id":    middleware.UserIDFromContext(ctx),
+		"request_id": middleware.RequestIDFromContext(ctx),
+		"route":      route,
+	}
+}
+
+func applyHeaderRules(header http.Header, rules HeaderRules, vars map[string]string) {
+	for _, name := range rules.Remove {
+		if prefix, ok := strings.CutSuffix(name, "*"); ok {
+			prefix = http.CanonicalHeaderKey(prefix)
+			for key := range header {
+				if strings.HasPrefix(key, prefix) {
+					header.Del(key)
+				}
+			}
+			continue
+		}
+		header.Del(name)
+	}
+
+	for name, value := range rules.Set {
+		if expanded := expandHeaderValue(value, vars); expanded != "" {
+			header.Set(name, expanded)
+		}
+	}
+
+	for name, value := range rules.Add {
+		if expanded := expandHeaderValue(value, vars); expanded != "" {
+			header.Add(name, expanded)
+		}
+	}
+}
+
+func expandHeaderValue(value string, vars map[string]string) string {
+	if !strings.Contains(value, "${") {
+		return value
+	}
+	return os.Expand(value, func(name string) string {
+		return vars[name]
+	})
+}
+
+// headerRewriteWriter applies response header rules before the status line is written.
+type headerRewriteWriter struct {
+	http.ResponseWriter
+	rules       []HeaderRules
+	vars        map[string]string
+	wroteHeader bool
+}
+
+func (w *headerRewriteWriter) WriteHeader(statusCode int) {
+	if !w.wroteHeader {
+		w.wroteHeader = true
+		for _, rules := range w.rules {
+			applyHeaderRules(w.Header(), rules, w.vars)
+		}
+	}
+	w.ResponseWriter.WriteHeader(statusCode)
+}
+
+func (w *headerRewriteWriter) Write(b []byte) (int, error) {
+	if !w.wroteHeader {
+		w.WriteHeader(http.StatusOK)
+	}
+	return w.ResponseWriter.Write(b)
+}
+
+// Unwrap exposes the underlying writer so the proxy can still flush streamed responses.
+func (w *headerRewriteWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// ============================================================================
+// Helpers
+// ============================================================================
+
+// parseByteSize parses sizes such as "512", "64KB" or "10MB" using 1024-byte units.
+func parseByteSize(s string) (int64, error) {
+	s = strings.ToUpper(strings.TrimSpace(s))
+	if s == "" {
+		return 0, nil
+	}
+
+	multiplier := int64(1)
+	for _, unit := range []struct {
+		suffix string
+		size   int64
+	}{
+		{"GB", 1 << 30},
+		{"MB", 1 << 20},
+		{"KB", 1 << 10},
+		{"B", 1},
+	} {
+		if strings.HasSuffix(s, unit.suffix) {
+			multiplier = unit.size
+			s = strings.TrimSpace(strings.TrimSuffix(s, unit.suffix))
+			break
+		}
+	}
+
+	n, err := strconv.ParseInt(s, 10, 64)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("%q is not a valid size", s)
+	}
+	return n * multiplier, nil
+}
@@ -0,0 +1,279 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/kilang-desa-murni/crm/pkg/middleware"
+)
+
+func newTestTransformEngine(t *testing.T, cfg *TransformConfig) *TransformEngine {
+	t.Helper()
+	engine, err := NewTransformEngine(cfg)
+	if err != nil {
+		t.Fatalf("NewTransformEngine() error = %v", err)
+	}
+	return engine
+}
+
+func TestTransformEngine_Rewrite(t *testing.T) {
+	engine := newTestTransformEngine(t, &TransformConfig{
+		Routes: []RouteTransform{
+			{Name: "legacy-customers", PathPrefix: "/api/customers/", Rewrite: &PathRewrite{Replacement: "/api/v1/customers/"}},
+			{Name: "legacy-leads", PathPrefix: "/api/leads/", Rewrite: &PathRewrite{Pattern: `^/api/leads/(.*)$`, Replacement: "/api/v1/leads/$1"}},
+			{Name: "post-only", PathPrefix: "/api/deals/", Methods: []string{"post"}, Rewrite: &PathRewrite{Replacement: "/api/v1/deals/"}},
+		},
+	})
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		want   string
+	}{
+		{"prefix replacement", http.MethodGet, "/api/customers/42/notes", "/api/v1/customers/42/notes"},
+		{"pattern replacement", http.MethodGet, "/api/leads/7", "/api/v1/leads/7"},
+		{"method matched case-insensitively", http.MethodPost, "/api/deals/9", "/api/v1/deals/9"},
+		{"other method untouched", http.MethodGet, "/api/deals/9", "/api/deals/9"},
+		{"unmatched path untouched", http.MethodGet, "/api/v1/customers/42", "/api/v1/customers/42"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got string
+			handler := engine.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				got = r.URL.Path
+			}))
+			handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(tt.method, tt.path, nil))
+
+			if got != tt.want {
+				t.Errorf("expected the backend to see %s, got %s", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestTransformEngine_HeaderRules(t *testing.T) {
+	engine := newTestTransformEngine(t, &TransformConfig{
+		Global: TransformRules{
+			RequestHeaders: HeaderRules{
+				Remove: []string{"X-Tenant-ID", "X-Internal-*"},
+				Set:    map[string]string{"X-Tenant-ID": "${tenant_id}", "X-User-ID": "${user_id}"},
+			},
+			ResponseHeaders: HeaderRules{
+				Remove: []string{"Server", "X-Internal-*"},
+				Set:    map[string]string{"X-Content-Type-Options": "nosniff"},
+			},
+		},
+		Routes: []RouteTransform{
+			{
+				Name:       "reports",
+				PathPrefix: "/api/v1/reports/",
+				TransformRules: TransformRules{
+					RequestHeaders:  HeaderRules{Add: map[string]string{"X-Gateway-Route": "${route}"}},
+					ResponseHeaders: HeaderRules{Set: map[string]string{"Cache-Control": "no-store"}},
+				},
+			},
+		},
+	})
+
+	var backend http.Header
+	handler := engine.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		backend = r.Header.Clone()
+		w.Header().Set("Server", "sales-service")
+		w.Header().Set("X-Internal-Trace", "abc")
+		w.Header().Set("Cache-Control", "max-age=60")
+		_, _ = w.Write([]byte("ok"))
+	}))
+
+	r := httptest.NewRequest(http.MethodGet, "/api/v1/reports/sales", nil)
+	r.Header.Set("X-Tenant-ID", "spoofed")
+	r.Header.Set("X-User-ID", "spoofed")
+	r.Header.Set("X-Internal-Service", "iam")
+	r.Header.Set("X-Internal-Token", "forged")
+	r = r.WithContext(context.WithValue(r.Context(), middleware.TenantIDKey, "tenant-1"))
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, r)
+
+	if got := backend.Get("X-Tenant-ID"); got != "tenant-1" {
+		t.Errorf("expected X-Tenant-ID from the token, got %q", got)
+	}
+	// No user in the context, so the client's header is kept but never
+	// replaced with an empty value
+	if got := backend.Get("X-User-ID"); got != "spoofed" {
+		t.Errorf("expected an empty expansion to be skipped, got %q", got)
+	}
+	if backend.Get("X-Internal-Service") != "" || backend.Get("X-Internal-Token") != "" {
+		t.Errorf("expected X-Internal-* to be stripped, got %v", backend)
+	}
+	if got := backend.Get("X-Gateway-Route"); got != "reports" {
+		t.Errorf("expected the route name to be added, got %q", got)
+	}
+
+	if w.Header().Get("Server") != "" || w.Header().Get("X-Internal-Trace") != "" {
+		t.Errorf("expected backend headers to be stripped, got %v", w.Header())
+	}
+	if w.Header().Get("X-Content-Type-Options") != "nosniff" || w.Header().Get("Cache-Control") != "no-store" {
+		t.Errorf("expected global and route headers to be set, got %v", w.Header())
+	}
+}
+
+func TestTransformEngine_MaxBodySize(t *testing.T) {
+	engine := newTestTransformEngine(t, &TransformConfig{
+		Global: TransformRules{MaxBodySize: "16B"},
+		Routes: []RouteTransform{
+			{Name: "import", PathPrefix: "/api/v1/customers/import", Methods: []string{"POST"}, TransformRules: TransformRules{MaxBodySize: "64B"}},
+		},
+	})
+
+	tests := []struct {
+		name       string
+		path       string
+		size       int
+		wantStatus int
+	}{
+		{"within the global limit", "/api/v1/leads", 16, http.StatusOK},
+		{"over the global limit", "/api/v1/leads", 17, http.StatusRequestEntityTooLarge},
+		{"within the route limit", "/api/v1/customers/import", 64, http.StatusOK},
+		{"over the route limit", "/api/v1/customers/import", 65, http.StatusRequestEntityTooLarge},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := engine.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, httptest.NewRequest(http.MethodPost, tt.path, strings.NewReader(strings.Repeat("x", tt.size))))
+
+			if w.Code != tt.wantStatus {
+				t.Errorf("expected %d, got %d", tt.wantStatus, w.Code)
+			}
+		})
+	}
+}
+
+func TestTransformEngine_MatchWildcardSegment(t *testing.T) {
+	engine := newTestTransformEngine(t, &TransformConfig{
+		Routes: []RouteTransform{
+			{Name: "attachments", PathPrefix: "/api/v1/opportunities/*/attachments", Methods: []string{"POST"}},
+			{Name: "opportunities", PathPrefix: "/api/v1/opportunities/"},
+		},
+	})
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		want   string
+	}{
+		{"wildcard segment", http.MethodPost, "/api/v1/opportunities/42/attachments", "attachments"},
+		{"below the wildcard prefix", http.MethodPost, "/api/v1/opportunities/42/attachments/", "attachments"},
+		{"other method falls through", http.MethodGet, "/api/v1/opportunities/42/attachments", "opportunities"},
+		{"segment must match whole", http.MethodPost, "/api/v1/opportunities/42/attachments-archive", "opportunities"},
+		{"wildcard spans one segment only", http.MethodPost, "/api/v1/opportunities/42/7/attachments", "opportunities"},
+		{"shorter path", http.MethodPost, "/api/v1/opportunities/42", "opportunities"},
+		{"no match", http.MethodPost, "/api/v1/deals/42/attachments", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ""
+			if route := engine.match(httptest.NewRequest(tt.method, tt.path, nil)); route != nil {
+				got = route.name
+			}
+			if got != tt.want {
+				t.Errorf("match(%s %s) = %q, want %q", tt.method, tt.path, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewTransformEngine_Invalid(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  *TransformConfig
+	}{
+		{"bad global size", &TransformConfig{Global: TransformRules{MaxBodySize: "lots"}}},
+		{"missing path prefix", &TransformConfig{Routes: []RouteTransform{{Name: "r"}}}},
+		{"bad rewrite pattern", &TransformConfig{Routes: []RouteTransform{{PathPrefix: "/a", Rewrite: &PathRewrite{Pattern: "("}}}}},
+		{"bad path prefix segment", &TransformConfig{Routes: []RouteTransform{{PathPrefix: "/a/[*/b"}}}},
+		{"prefix rewrite of a wildcard prefix", &TransformConfig{Routes: []RouteTransform{{PathPrefix: "/a/*/b", Rewrite: &PathRewrite{Replacement: "/c/"}}}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := NewTransformEngine(tt.cfg); err == nil {
+				t.Error("expected an error")
+			}
+		})
+	}
+}
+
+func TestParseByteSize(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    int64
+		wantErr bool
+	}{
+		{"", 0, false},
+		{"512", 512, false},
+		{"512B", 512, false},
+		{"64kb", 64 << 10, false},
+		{" 10 MB ", 10 << 20, false},
+		{"2GB", 2 << 30, false},
+		{"1.5MB", 0, true},
+		{"-1KB", 0, true},
+		{"MB", 0, true},
+		{"ten", 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, err := parseByteSize(tt.in)
+			if (err != nil) != tt.wantErr || got != tt.want {
+				t.Errorf("parseByteSize(%q) = %d, %v, want %d (error %v)", tt.in, got, err, tt.want, tt.wantErr)
+			}
+		})
+	}
+}
+
+// TestTransformConfig_UploadLimits checks that the shipped policy lets the
+// upload endpoints through at the size their services accept.
+func TestTransformConfig_UploadLimits(t *testing.T) {
+	cfg, err := LoadTransformConfig("../../" + defaultTransformConfigPath)
+	if err != nil {
+		t.Fatalf("LoadTransformConfig() error = %v", err)
+	}
+	engine := newTestTransformEngine(t, cfg)
+
+	tests := []struct {
+		method string
+		path   string
+		want   int64
+	}{
+		{http.MethodPost, "/api/v1/customers/import", 20 << 20},
+		{http.MethodPost, "/api/v1/leads/import", 20 << 20},
+		{http.MethodPost, "/api/v1/imports", 20 << 20},
+		{http.MethodPatch, "/api/v1/import-uploads/6a1f0c2e-8b4d-4e1a-9c3f-2d5e7a9b1c40", 8 << 20},
+		{http.MethodPost, "/api/v1/import-uploads", 1 << 20},
+		{http.MethodPost, "/api/v1/opportunities/6a1f0c2e-8b4d-4e1a-9c3f-2d5e7a9b1c40/attachments", 11 << 20},
+		{http.MethodPost, "/api/v1/opportunities/6a1f0c2e-8b4d-4e1a-9c3f-2d5e7a9b1c40/products", 1 << 20},
+		{http.MethodPost, "/api/v1/inbound-email/messages", 30 << 20},
+		{http.MethodPost, "/api/v1/ecommerce/webhooks/0b6f1c1e-5d1a-4c53-9a1e-3f0f8f1f2a10", 2 << 20},
+		{http.MethodPost, "/api/v1/leads", 1 << 20},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.method+" "+tt.path, func(t *testing.T) {
+			limit := engine.global.maxBodySize
+			if route := engine.match(httptest.NewRequest(tt.method, tt.path, nil)); route != nil && route.rules.maxBodySize > 0 {
+				limit = route.rules.maxBodySize
+			}
+			if limit != tt.want {
+				t.Errorf("limit = %d, want %d", limit, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,719 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"os"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/spf13/viper"
+
+	apperrors "github.com/kilang-desa-murni/crm/pkg/errors"
+	"github.com/kilang-desa-murni/crm/pkg/middleware"
+	"github.com/kilang-desa-murni/crm/pkg/response"
+)
+
+// defaultVersionConfigPath is used when GATEWAY_VERSIONS_CONFIG is not set.
+const defaultVersionConfigPath = "configs/gateway/versions.yaml"
+
+// defaultBackendPrefix is the path prefix the backend services serve.
+const defaultBackendPrefix = "/api/v1"
+
+// maxTranslatedBodySize bounds the request and response bodies a translator
+// rewrites.
+const maxTranslatedBodySize = 10 << 20
+
+// maxTrackedTenants bounds the tenants whose usage is kept per version.
+const maxTrackedTenants = 10000
+
+// versionPathPattern matches versioned API paths such as /api/v2/leads.
+var versionPathPattern = regexp.MustCompile(`^/api/(v[0-9]+)(/.*)?$`)
+
+// versionNamePattern matches version names such as v2.
+var versionNamePattern = regexp.MustCompile(`^v[0-9]+$`)
+
+// ============================================================================
+// Configuration
+// ============================================================================
+
+// VersionConfig is the YAML-driven policy of the public API versions.
+type VersionConfig struct {
+	// BackendPrefix is the path prefix every version is routed to on the
+	// backend services. Defaults to /api/v1.
+	BackendPrefix string `mapstructure:"backend_prefix"`
+	// Versions are listed oldest first; the last one is the current version.
+	Versions []APIVersionConfig `mapstructure:"versions"`
+}
+
+// APIVersionConfig describes one public API version.
+type APIVersionConfig struct {
+	Name string `mapstructure:"name"`
+	// Deprecated and Sunset are dates such as "2026-11-01". Requests to a
+	// deprecated version are answered with Deprecation and Sunset headers;
+	// after the sunset date they are refused with 410 Gone.
+	Deprecated string `mapstructure:"deprecated"`
+	Sunset     string `mapstructure:"sunset"`
+	// Link is the migration guide sent in the Link header of a deprecated
+	// version.
+	Link string `mapstructure:"link"`
+	// Translators rewrite the request and response bodies of changed
+	// endpoints between the version and the backend services.
+	Translators []VersionTranslatorConfig `mapstructure:"translators"`
+}
+
+// VersionTranslatorConfig translates the JSON bodies of the endpoints
+// matching Paths, relative to the version root such as "/leads/*".
+// Request moves turn the version's request into the backend's; response
+// moves turn the backend's response into the version's.
+type VersionTranslatorConfig struct {
+	Name     string      `mapstructure:"name"`
+	Paths    []string    `mapstructure:"paths"`
+	Methods  []string    `mapstructure:"methods"`
+	Request  []FieldMove `mapstructure:"request"`
+	Response []FieldMove `mapstructure:"response"`
+}
+
+// FieldMove moves the field at the dotted path From to To, creating the
+// objects along To. Without To, the field is removed.
+type FieldMove struct {
+	From string `mapstructure:"from"`
+	To   string `mapstructure:"to"`
+}
+
+// LoadVersionConfig reads the versioning policy from a YAML file. A missing
+// file yields an empty policy, so versioned paths are proxied unchanged.
+func LoadVersionConfig(path string) (*VersionConfig, error) {
+	cfg := &VersionConfig{}
+	if _, err := os.Stat(path); errors.Is(err, os.ErrNotExist) {
+		return cfg, nil
+	}
+
+	v := viper.New()
+	v.SetConfigFile(path)
+	v.SetConfigType("yaml")
+	if err := v.ReadInConfig(); err != nil {
+		return nil, fmt.Errorf("error reading version config: %w", err)
+	}
+	if err := v.Unmarshal(cfg); err != nil {
+		return nil, fmt.Errorf("error parsing version config: %w", err)
+	}
+	return cfg, nil
+}
+
+// ============================================================================
+// Router
+// ============================================================================
+
+// VersionRouter routes each public API version to the backend services,
+// translating the bodies of the endpoints that changed between them, and
+// counts the requests of every version.
+type VersionRouter struct {
+	backendPrefix string
+	versions      map[string]*versionRoute
+	order         []*versionRoute
+	current       *versionRoute
+	now           func() time.Time
+}
+
+// versionRoute is the router of one version.
+type versionRoute struct {
+	name        string
+	deprecated  *time.Time
+	sunset      *time.Time
+	link        string
+	translators []*compiledTranslator
+
+	mu       sync.Mutex
+	requests int64
+	tenants  map[string]*versionTenantUsage
+}
+
+type compiledTranslator struct {
+	name     string
+	paths    []string
+	methods  map[string]bool
+	request  []FieldMove
+	response []FieldMove
+}
+
+type versionTenantUsage struct {
+	requests int64
+	lastSeen time.Time
+}
+
+// NewVersionRouter validates and compiles a versioning policy.
+func NewVersionRouter(cfg *VersionConfig) (*VersionRouter, error) {
+	router := &VersionRouter{
+		backendPrefix: strings.TrimSuffix(cfg.BackendPrefix, "/"),
+		versions:      make(map[string]*versionRoute),
+		now:           time.Now,
+	}
+	if router.backendPrefix == "" {
+		router.backendPrefix = defaultBackendPrefix
+	}
+
+	for i, version := range cfg.Versions {
+		if !versionNamePattern.MatchString(version.Name) {
+			return nil, fmt.Errorf("versions[%d]: name must look like v1", i)
+		}
+		if router.versions[version.Name] != nil {
+			return nil, fmt.Errorf("%s: version is listed twice", version.Name)
+		}
+
+		route := &versionRoute{
+			name:    version.Name,
+			link:    version.Link,
+			tenants: make(map[string]*versionTenantUsage),
+		}
+		var err error
+		if route.deprecated, err = parseVersionDate(version.Deprecated); err != nil {
+			return nil, fmt.Errorf("%s: invalid deprecated date: %w", version.Name, err)
+		}
+		if route.sunset, err = parseVersionDate(version.Sunset); err != nil {
+			return nil, fmt.Errorf("%s: invalid sunset date: %w", version.Name, err)
+		}
+		if route.sunset != nil && route.deprecated == nil {
+			return nil, fmt.Errorf("%s: a version with a sunset date must be deprecated", version.Name)
+		}
+		if route.sunset != nil && route.sunset.Before(*route.deprecated) {
+			return nil, fmt.Errorf("%s: sunset is before the deprecation", version.Name)
+		}
+
+		for j, translator := range version.Translators {
+			compiled, err := compileTranslator(translator)
+			if err != nil {
+				name := translator.Name
+				if name == "" {
+					name = fmt.Sprintf("translators[%d]", j)
+				}
+				return nil, fmt.Errorf("%s: %s: %w", version.Name, name, err)
+			}
+			route.translators = append(route.translators, compiled)
+		}
+
+		router.versions[route.name] = route
+		router.order = append(router.order, route)
+	}
+
+	if len(router.order) > 0 {
+		router.current = router.order[len(router.order)-1]
+		if router.current.deprecated != nil {
+			return nil, fmt.Errorf("%s: the current version cannot be deprecated", router.current.name)
+		}
+	}
+	return router, nil
+}
+
+func compileTranslator(cfg VersionTranslatorConfig) (*compiledTranslator, error) {
+	if len(cfg.Paths) == 0 {
+		return nil, errors.New("paths are required")
+	}
+	compiled := &compiledTranslator{
+		name:     cfg.Name,
+		request:  cfg.Request,
+		response: cfg.Response,
+	}
+	for _, pattern := range cfg.Paths {
+		if !strings.HasPrefix(pattern, "/") {
+			return nil, fmt.Errorf("path %q must start with /", pattern)
+		}
+		if _, err := path.Match(pattern, ""); err != nil {
+			return nil, fmt.Errorf("invalid path %q: %w", pattern, err)
+		}
+		compiled.paths = append(compiled.paths, strings.TrimSuffix(pattern, "/"))
+	}
+	if len(cfg.Methods) > 0 {
+		compiled.methods = make(map[string]bool, len(cfg.Methods))
+		for _, method := range cfg.Methods {
+			compiled.methods[strings.ToUpper(method)] = true
+		}
+	}
+	for _, move := range append(append([]FieldMove{}, cfg.Request...), cfg.Response...) {
+		if move.From == "" {
+			return nil, errors.New("every move needs a from path")
+		}
+	}
+	return compiled, nil
+}
+
+func parseVersionDate(value string) (*time.Time, error) {
+	if value == "" {
+		return nil, nil
+	}
+	t, err := time.Parse("2006-01-02", value)
+	if err != nil {
+		if t, err = time.Parse(time.RFC3339, value); err != nil {
+			return nil, fmt.Errorf("%q is not a date such as 2026-11-01", value)
+		}
+	}
+	t = t.UTC()
+	return &t, nil
+}
+
+// versionContextKey is the request context key for the version of a request.
+type versionContextKey struct{}
+
+// Middleware routes versioned requests to the backend prefix, so the
+// routes below it only know the backend paths. It must run before the
+// public and protected routes are told apart. Unversioned paths pass
+// through unchanged.
+func (v *VersionRouter) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		match := versionPathPattern.FindStringSubmatch(r.URL.Path)
+		if match == nil || len(v.order) == 0 {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		route := v.versions[match[1]]
+		if route == nil {
+			response.Error(w, apperrors.New(apperrors.ErrCodeNotFound, fmt.Sprintf("API version %s is not supported", match[1])))
+			return
+		}
+		rest := match[2]
+
+		route.count()
+		w.Header().Set("API-Version", route.name)
+		now := v.now()
+		if route.deprecated != nil {
+			v.setDeprecationHeaders(w.Header(), route, rest)
+			if route.sunset != nil && !now.Before(*route.sunset) {
+				response.Error(w, apperrors.ErrGone(fmt.Sprintf("API version %s was retired on %s; use %s", route.name, route.sunset.Format("2006-01-02"), v.current.name)))
+				return
+			}
+		}
+
+		r.URL.Path = v.backendPrefix + rest
+		r.URL.RawPath = ""
+		r = r.WithContext(context.WithValue(r.Context(), versionContextKey{}, route))
+
+		var requestMoves, responseMoves []FieldMove
+		for _, translator := range route.match(r.Method, rest) {
+			requestMoves = append(requestMoves, translator.request...)
+			responseMoves = append(responseMoves, translator.response...)
+		}
+
+		if len(requestMoves) > 0 {
+			if err := translateRequest(r, requestMoves); err != nil {
+				response.Error(w, err)
+				return
+			}
+		}
+		if len(responseMoves) == 0 {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		rec := &translationRecorder{w: w, header: make(http.Header), statusCode: http.StatusOK}
+		next.ServeHTTP(rec, r)
+		rec.flush(responseMoves)
+	})
+}
+
+// TrackTenant records the authenticated tenant of versioned requests, so
+// the tenants still calling a deprecated version are known. It must run
+// after authentication.
+func (v *VersionRouter) TrackTenant(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if route, ok := r.Context().Value(versionContextKey{}).(*versionRoute); ok {
+			if tenantID := middleware.TenantIDFromContext(r.Context()); tenantID != "" {
+				route.trackTenant(tenantID, v.now().UTC())
+			}
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// setDeprecationHeaders announces a deprecated version: Deprecation as in
+// RFC 9745, Sunset as in RFC 8594, and Links to the migration guide and
+// the same resource in the current version.
+func (v *VersionRouter) setDeprecationHeaders(header http.Header, route *versionRoute, rest string) {
+	header.Set("Deprecation", "@"+strconv.FormatInt(route.deprecated.Unix(), 10))
+	if route.sunset != nil {
+		header.Set("Sunset", route.sunset.Format(http.TimeFormat))
+	}
+	if route.link != "" {
+		header.Add("Link", fmt.Sprintf(`<%s>; rel="deprecation"; type="text/html"`, route.link))
+	}
+	header.Add("Link", fmt.Sprintf(`</api/%s%s>; rel="successor-version"`, v.current.name, rest))
+}
+
+// match returns the translators of the endpoint, in file order.
+func (r *versionRoute) match(method, rest string) []*compiledTranslator {
+	rest = strings.TrimSuffix(rest, "/")
+	var matched []*compiledTranslator
+	for _, translator := range r.translators {
+		if translator.methods != nil && !translator.methods[method] {
+			continue
+		}
+		for _, pattern := range translator.paths {
+			if ok, _ := path.Match(pattern, rest); ok {
+				matched = append(matched, translator)
+				break
+			}
+		}
+	}
+	return matched
+}
+
+func (r *versionRoute) count() {
+	r.mu.Lock()
+	r.requests++
+	r.mu.Unlock()
+}
+
+func (r *versionRoute) trackTenant(tenantID string, at time.Time) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	usage := r.tenants[tenantID]
+	if usage == nil {
+		if len(r.tenants) >= maxTrackedTenants {
+			return
+		}
+		usage = &versionTenantUsage{}
+		r.tenants[tenantID] = usage
+	}
+	usage.requests++
+	usage.lastSeen = at
+}
+
+// ============================================================================
+// Translation
+// ============================================================================
+
+// translateRequest applies the moves to a JSON request body.
+func translateRequest(r *http.Request, moves []FieldMove) error {
+	if r.Body == nil || r.Body == http.NoBody || !isJSON(r.Header.Get("Content-Type")) {
+		return nil
+	}
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxTranslatedBodySize+1))
+	r.Body.Close()
+	if err != nil {
+		return apperrors.ErrBadRequest("Failed to read request body")
+	}
+	if len(body) > maxTranslatedBodySize {
+		return apperrors.ErrPayloadTooLarge(maxTranslatedBodySize)
+	}
+
+	var payload interface{}
+	if err := json.Unmarshal(body, &payload); err == nil {
+		if object, ok := payload.(map[string]interface{}); ok {
+			applyMoves(object, moves)
+			body, _ = json.Marshal(object)
+		}
+	}
+
+	// Bodies that are not JSON objects are passed on for the backend to reject
+	r.Body = io.NopCloser(bytes.NewReader(body))
+	r.ContentLength = int64(len(body))
+	r.Header.Set("Content-Length", strconv.Itoa(len(body)))
+	return nil
+}
+
+// translateResponse applies the moves to a JSON response body. Bodies in the
+// {"success", "data"} envelope are translated by their data, and lists by
+// each of their items.
+func translateResponse(body []byte, moves []FieldMove) []byte {
+	var payload interface{}
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return body
+	}
+
+	target := payload
+	if object, ok := payload.(map[string]interface{}); ok {
+		if _, wrapped := object["success"]; wrapped {
+			target = object["data"]
+		}
+	}
+	switch data := target.(type) {
+	case map[string]interface{}:
+		applyMoves(data, moves)
+	case []interface{}:
+		for _, item := range data {
+			if object, ok := item.(map[string]interface{}); ok {
+				applyMoves(object, moves)
+			}
+		}
+	default:
+		return body
+	}
+
+	translated, err := json.Marshal(payload)
+	if err != nil {
+		return body
+	}
+	return translated
+}
+
+func applyMoves(object map[string]interface{}, moves []FieldMove) {
+	for _, move := range moves {
+		value, ok := takeField(object, strings.Split(move.From, "."))
+		if !ok || move.To == "" {
+			continue
+		}
+		putField(object, strings.Split(move.To, "."), value)
+	}
+}
+
+// takeField removes and returns the field at the path, removing the objects
+// the removal leaves empty.
+func takeField(object map[string]interface{}, path []string) (interface{}, bool) {
+	if len(path) == 1 {
+		value, ok := object[path[0]]
+		delete(object, path[0])
+		return value, ok
+	}
+	child, ok := object[path[0]].(map[string]interface{})
+	if !ok {
+		return nil, false
+	}
+	value, ok := takeField(child, path[1:])
+	if ok && len(child) == 0 {
+		delete(object, path[0])
+	}
+	return value, ok
+}
+
+// putField sets the field at the path, creating the objects along it.
+func putField(object map[string]interface{}, path []string, value interface{}) {
+	for _, key := range path[:len(path)-1] {
+		child, ok := object[key].(map[string]interface{})
+		if !ok {
+			child = make(map[string]interface{})
+			object[key] = child
+		}
+		object = child
+	}
+	object[path[len(path)-1]] = value
+}
+
+func isJSON(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	return err == nil && (mediaType == "application/json" || strings.HasSuffix(mediaType, "+json"))
+}
+
+// translationRecorder buffers a backend response so its body can be
+// translated before it is written. Responses that are not translated, and
+// bodies that grow past maxTranslatedBodySize, are streamed through as is.
+type translationRecorder struct {
+	w           http.ResponseWriter
+	header      http.Header
+	body        bytes.Buffer
+	statusCode  int
+	wroteHeader bool
+	streaming   bool
+}
+
+func (r *translationRecorder) Header() http.Header {
+	return r.header
+}
+
+func (r *translationRecorder) Write(b []byte) (int, error) {
+	if !r.wroteHeader {
+		r.WriteHeader(http.StatusOK)
+	}
+	if !r.streaming && r.body.Len()+len(b) > maxTranslatedBodySize {
+		if err := r.stream(); err != nil {
+			return 0, err
+		}
+	}
+	if r.streaming {
+		return r.w.Write(b)
+	}
+	return r.body.Write(b)
+}
+
+func (r *translationRecorder) WriteHeader(statusCode int) {
+	if r.wroteHeader {
+		return
+	}
+	r.wroteHeader = true
+	r.statusCode = statusCode
+	if !r.translatable() {
+		r.stream()
+	}
+}
+
+// translatable reports whether the response is a successful JSON body.
+func (r *translationRecorder) translatable() bool {
+	return r.statusCode >= 200 && r.statusCode < 300 && isJSON(r.header.Get("Content-Type")) &&
+		r.header.Get("Content-Encoding") == ""
+}
+
+// stream writes the headers and the body buffered so far untranslated, and
+// passes the rest of the body straight through.
+func (r *translationRecorder) stream() error {
+	r.streaming = true
+	for key, values := range r.header {
+		r.w.Header()[key] = values
+	}
+	r.w.WriteHeader(r.statusCode)
+
+	_, err := r.w.Write(r.body.Bytes())
+	r.body = bytes.Buffer{}
+	return err
+}
+
+// flush writes a buffered response, translating successful JSON bodies.
+func (r *translationRecorder) flush(moves []FieldMove) {
+	if r.streaming {
+		return
+	}
+
+	body := r.body.Bytes()
+	if r.translatable() {
+		body = translateResponse(body, moves)
+		r.header.Set("Content-Length", strconv.Itoa(len(body)))
+	}
+
+	for key, values := range r.header {
+		r.w.Header()[key] = values
+	}
+	r.w.WriteHeader(r.statusCode)
+	r.w.Write(body)
+}
+
+// ============================================================================
+// Usage
+// ============================================================================
+
+// VersionInfo describes a version and its deprecation.
+type VersionInfo struct {
+	Version    string     `json:"version"`
+	Status     string     `json:"status"`
+	Current    bool       `json:"current"`
+	Deprecated *time.Time `json:"deprecated,omitempty"`
+	Sunset     *time.Time `json:"sunset,omitempty"`
+	Link       string     `json:"link,omitempty"`
+}
+
+// VersionUsage reports a version and who still calls it.
+type VersionUsage struct {
+	VersionInfo
+	Requests int64                `json:"requests"`
+	Tenants  []TenantVersionUsage `json:"tenants"`
+}
+
+// TenantVersionUsage is a tenant's requests to a version on this instance.
+type TenantVersionUsage struct {
+	TenantID string    `json:"tenant_id"`
+	Requests int64     `json:"requests"`
+	LastSeen time.Time `json:"last_seen"`
+}
+
+// Versions returns the versions oldest first.
+func (v *VersionRouter) Versions() []VersionInfo {
+	now := v.now()
+	versions := make([]VersionInfo, 0, len(v.order))
+	for _, route := range v.order {
+		versions = append(versions, VersionInfo{
+			Version:    route.name,
+			Status:     route.status(now),
+			Current:    route == v.current,
+			Deprecated: route.deprecated,
+			Sunset:     route.sunset,
+			Link:       route.link,
+		})
+	}
+	return versions
+}
+
+// Usage returns the versions oldest first, with the requests this instance
+// routed to each since it started and the tenants that sent them, most
+// recent first.
+func (v *VersionRouter) Usage() []VersionUsage {
+	versions := v.Versions()
+	usage := make([]VersionUsage, 0, len(versions))
+	for i, route := range v.order {
+		route.mu.Lock()
+		entry := VersionUsage{
+			VersionInfo: versions[i],
+			Requests:    route.requests,
+			Tenants:     make([]TenantVersionUsage, 0, len(route.tenants)),
+		}
+		for tenantID, tenant := range route.tenants {
+			entry.Tenants = append(entry.Tenants, TenantVersionUsage{
+				TenantID: tenantID,
+				Requests: tenant.requests,
+				LastSeen: tenant.lastSeen,
+			})
+		}
+		route.mu.Unlock()
+
+		sort.Slice(entry.Tenants, func(i, j int) bool {
+			return entry.Tenants[i].LastSeen.After(entry.Tenants[j].LastSeen)
+		})
+		usage = append(usage, entry)
+	}
+	return usage
+}
+
+// status is "active", "deprecated" or "retired".
+func (r *versionRoute) status(now time.Time) string {
+	switch {
+	case r.sunset != nil && !now.Before(*r.sunset):
+		return "retired"
+	case r.deprecated != nil && !now.Before(*r.deprecated):
+		return "deprecated"
+	}
+	return "active"
+}
+
+// WriteMetrics writes the version series in the Prometheus text format:
+// requests per version, and whether each version is deprecated and when it
+// is retired.
+func (v *VersionRouter) WriteMetrics(w io.Writer) {
+	if len(v.order) == 0 {
+		return
+	}
+
+	fmt.Fprintf(w, "# HELP api_version_requests_total Requests routed to the API version.\n# TYPE api_version_requests_total counter\n")
+	for _, route := range v.order {
+		route.mu.Lock()
+		fmt.Fprintf(w, "api_version_requests_total{version=%q} %d\n", route.name, route.requests)
+		route.mu.Unlock()
+	}
+
+	fmt.Fprintf(w, "# HELP api_version_tenants Tenants that called the API version since the instance started.\n# TYPE api_version_tenants gauge\n")
+	for _, route := range v.order {
+		route.mu.Lock()
+		fmt.Fprintf(w, "api_version_tenants{version=%q} %d\n", route.name, len(route.tenants))
+		route.mu.Unlock()
+	}
+
+	fmt.Fprintf(w, "# HELP api_version_deprecated Whether the API version is deprecated.\n# TYPE api_version_deprecated gauge\n")
+	for _, route := range v.order {
+		value := 0
+		if route.deprecated != nil {
+			value = 1
+		}
+		fmt.Fprintf(w, "api_version_deprecated{version=%q} %d\n", route.name, value)
+	}
+
+	fmt.Fprintf(w, "# HELP api_version_sunset_timestamp_seconds When the API version is retired.\n# TYPE api_version_sunset_timestamp_seconds gauge\n")
+	for _, route := range v.order {
+		if route.sunset != nil {
+			fmt.Fprintf(w, "api_version_sunset_timestamp_seconds{version=%q} %d\n", route.name, route.sunset.Unix())
+		}
+	}
+}
+
+// versionUsageHandler serves the usage of every version.
+func versionUsageHandler(versions *VersionRouter) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		response.OK(w, versions.Usage())
+	}
+}
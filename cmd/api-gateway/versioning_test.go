@@ -0,0 +1,383 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func newTestVersionRouter(t *testing.T, cfg *VersionConfig) *VersionRouter {
+	t.Helper()
+	router, err := NewVersionRouter(cfg)
+	if err != nil {
+		t.Fatalf("NewVersionRouter() error = %v", err)
+	}
+	return router
+}
+
+// backendCall records what a version's request looked like to the backend.
+type backendCall struct {
+	path string
+	body map[string]interface{}
+}
+
+// serveVersioned sends a request through the router to a backend answering
+// with the given handler.
+func serveVersioned(t *testing.T, router *VersionRouter, r *http.Request, backend http.HandlerFunc) (*httptest.ResponseRecorder, *backendCall) {
+	t.Helper()
+	call := &backendCall{}
+	handler := router.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		call.path = r.URL.Path
+		if body, _ := io.ReadAll(r.Body); len(body) > 0 {
+			if err := json.Unmarshal(body, &call.body); err != nil {
+				t.Errorf("backend received invalid JSON %s: %v", body, err)
+			}
+		}
+		if backend != nil {
+			backend(w, r)
+		}
+	}))
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, r)
+	return w, call
+}
+
+func jsonRequest(method, target, body string) *http.Request {
+	r := httptest.NewRequest(method, target, strings.NewReader(body))
+	r.Header.Set("Content-Type", "application/json")
+	return r
+}
+
+func TestVersionRouter_PathRewrite(t *testing.T) {
+	router := newTestVersionRouter(t, &VersionConfig{Versions: []APIVersionConfig{{Name: "v1"}, {Name: "v2"}}})
+
+	tests := []struct {
+		name        string
+		path        string
+		wantStatus  int
+		wantPath    string
+		wantVersion string
+	}{
+		{"current version", "/api/v2/leads/42", http.StatusOK, "/api/v1/leads/42", "v2"},
+		{"older version", "/api/v1/leads/42", http.StatusOK, "/api/v1/leads/42", "v1"},
+		{"version root", "/api/v2", http.StatusOK, "/api/v1", "v2"},
+		{"unknown version", "/api/v9/leads", http.StatusNotFound, "", ""},
+		{"unversioned path", "/health", http.StatusOK, "/health", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w, call := serveVersioned(t, router, httptest.NewRequest(http.MethodGet, tt.path, nil), nil)
+			if w.Code != tt.wantStatus {
+				t.Fatalf("expected %d, got %d", tt.wantStatus, w.Code)
+			}
+			if call.path != tt.wantPath {
+				t.Errorf("expected the backend to see %q, got %q", tt.wantPath, call.path)
+			}
+			if got := w.Header().Get("API-Version"); got != tt.wantVersion {
+				t.Errorf("API-Version = %q, want %q", got, tt.wantVersion)
+			}
+		})
+	}
+}
+
+// TestVersionConfig_Translators runs every request translator of the shipped
+// policy.
+func TestVersionConfig_Translators(t *testing.T) {
+	cfg, err := LoadVersionConfig("../../" + defaultVersionConfigPath)
+	if err != nil {
+		t.Fatalf("LoadVersionConfig() error = %v", err)
+	}
+	router := newTestVersionRouter(t, cfg)
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		body   string
+		want   map[string]interface{}
+	}{
+		{
+			name:   "lead-budget",
+			method: http.MethodPost,
+			path:   "/api/v2/leads/7/qualify",
+			body:   `{"budget":{"amount":250000,"currency":"MYR"},"notes":"ready"}`,
+			want:   map[string]interface{}{"budget": float64(250000), "budget_currency": "MYR", "notes": "ready"},
+		},
+		{
+			name:   "lead-utm-params",
+			method: http.MethodPut,
+			path:   "/api/v2/leads/7",
+			body:   `{"utm_params":{"source":"facebook","medium":"cpc","campaign":"raya","term":"batik","content":"banner"}}`,
+			want: map[string]interface{}{
+				"utm_source": "facebook", "utm_medium": "cpc", "utm_campaign": "raya", "utm_term": "batik", "utm_content": "banner",
+			},
+		},
+		{
+			name:   "lead-budget and lead-utm-params together",
+			method: http.MethodPost,
+			path:   "/api/v2/leads",
+			body:   `{"budget":{"amount":100,"currency":"MYR"},"utm_params":{"source":"email"}}`,
+			want:   map[string]interface{}{"budget": float64(100), "budget_currency": "MYR", "utm_source": "email"},
+		},
+		{
+			name:   "opportunity-amount",
+			method: http.MethodPost,
+			path:   "/api/v2/opportunities",
+			body:   `{"name":"Wholesale","amount":{"amount":500000,"currency":"MYR"}}`,
+			want:   map[string]interface{}{"name": "Wholesale", "amount": float64(500000), "currency": "MYR"},
+		},
+		{
+			name:   "v1 is not translated",
+			method: http.MethodPost,
+			path:   "/api/v1/opportunities",
+			body:   `{"amount":500000,"currency":"MYR"}`,
+			want:   map[string]interface{}{"amount": float64(500000), "currency": "MYR"},
+		},
+		{
+			name:   "other methods are not translated",
+			method: http.MethodPatch,
+			path:   "/api/v2/opportunities/9",
+			body:   `{"amount":{"amount":1,"currency":"MYR"}}`,
+			want:   map[string]interface{}{"amount": map[string]interface{}{"amount": float64(1), "currency": "MYR"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w, call := serveVersioned(t, router, jsonRequest(tt.method, tt.path, tt.body), nil)
+			if w.Code != http.StatusOK {
+				t.Fatalf("expected 200, got %d", w.Code)
+			}
+			got, _ := json.Marshal(call.body)
+			want, _ := json.Marshal(tt.want)
+			if !bytes.Equal(got, want) {
+				t.Errorf("backend body = %s, want %s", got, want)
+			}
+		})
+	}
+}
+
+func TestVersionRouter_TranslateResponse(t *testing.T) {
+	router := newTestVersionRouter(t, &VersionConfig{Versions: []APIVersionConfig{
+		{Name: "v1", Translators: []VersionTranslatorConfig{{
+			Paths:    []string{"/deals", "/deals/*"},
+			Response: []FieldMove{{From: "total.amount", To: "amount"}, {From: "total.currency", To: "currency"}, {From: "internal_notes"}},
+		}}},
+		{Name: "v2"},
+	}})
+
+	tests := []struct {
+		name        string
+		path        string
+		status      int
+		contentType string
+		body        string
+		want        string
+	}{
+		{
+			name:        "enveloped object",
+			path:        "/api/v1/deals/3",
+			status:      http.StatusOK,
+			contentType: "application/json",
+			body:        `{"success":true,"data":{"id":"3","internal_notes":"x","total":{"amount":10,"currency":"MYR"}}}`,
+			want:        `{"data":{"amount":10,"currency":"MYR","id":"3"},"success":true}`,
+		},
+		{
+			name:        "list items",
+			path:        "/api/v1/deals",
+			status:      http.StatusOK,
+			contentType: "application/json; charset=utf-8",
+			body:        `[{"id":"1","total":{"amount":1,"currency":"MYR"}},{"id":"2"}]`,
+			want:        `[{"amount":1,"currency":"MYR","id":"1"},{"id":"2"}]`,
+		},
+		{
+			name:        "error responses are passed through",
+			path:        "/api/v1/deals/3",
+			status:      http.StatusNotFound,
+			contentType: "application/json",
+			body:        `{"success":false,"error":{"message":"deal not found"},"total":{"amount":1}}`,
+			want:        `{"success":false,"error":{"message":"deal not found"},"total":{"amount":1}}`,
+		},
+		{
+			name:        "other content types are passed through",
+			path:        "/api/v1/deals/3",
+			status:      http.StatusOK,
+			contentType: "text/csv",
+			body:        "id,total\n3,10\n",
+			want:        "id,total\n3,10\n",
+		},
+		{
+			name:        "current version is not translated",
+			path:        "/api/v2/deals/3",
+			status:      http.StatusOK,
+			contentType: "application/json",
+			body:        `{"id":"3","total":{"amount":10,"currency":"MYR"}}`,
+			want:        `{"id":"3","total":{"amount":10,"currency":"MYR"}}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w, _ := serveVersioned(t, router, httptest.NewRequest(http.MethodGet, tt.path, nil), func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", tt.contentType)
+				w.WriteHeader(tt.status)
+				_, _ = w.Write([]byte(tt.body))
+			})
+			if w.Code != tt.status {
+				t.Errorf("expected %d, got %d", tt.status, w.Code)
+			}
+			if got := w.Body.String(); got != tt.want {
+				t.Errorf("body = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+// streamingRecorder counts the writes that reach the client before the
+// handler returns.
+type streamingRecorder struct {
+	*httptest.ResponseRecorder
+	writes int
+}
+
+func (r *streamingRecorder) Write(b []byte) (int, error) {
+	r.writes++
+	return r.ResponseRecorder.Write(b)
+}
+
+func TestVersionRouter_LargeResponseStreamed(t *testing.T) {
+	router := newTestVersionRouter(t, &VersionConfig{Versions: []APIVersionConfig{
+		{Name: "v1", Translators: []VersionTranslatorConfig{{Paths: []string{"/reports/*"}, Response: []FieldMove{{From: "rows"}}}}},
+		{Name: "v2"},
+	}})
+
+	chunk := []byte(`"` + strings.Repeat("x", 1<<20) + `",`)
+	chunks := maxTranslatedBodySize/len(chunk) + 4
+	var written int
+	handler := router.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"rows":[`))
+		for i := 0; i < chunks; i++ {
+			_, _ = w.Write(chunk)
+			written += len(chunk)
+		}
+		_, _ = w.Write([]byte(`""]}`))
+	}))
+
+	w := &streamingRecorder{ResponseRecorder: httptest.NewRecorder()}
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/reports/sales", nil))
+
+	// Past the limit the buffered part is written once, and every later
+	// write goes straight through
+	if w.writes < 4 {
+		t.Errorf("expected the body past the limit to be streamed, got %d writes", w.writes)
+	}
+	if got, want := w.Body.Len(), written+len(`{"rows":[`)+len(`""]}`); got != want {
+		t.Fatalf("expected the whole body of %d bytes, got %d", want, got)
+	}
+	if !bytes.HasPrefix(w.Body.Bytes(), []byte(`{"rows":[`)) {
+		t.Error("expected the body to be passed through untranslated")
+	}
+}
+
+func TestVersionRouter_Deprecation(t *testing.T) {
+	router := newTestVersionRouter(t, &VersionConfig{Versions: []APIVersionConfig{
+		{Name: "v1", Deprecated: "2026-11-01", Sunset: "2027-05-01", Link: "https://developers.example.com/migrate-v2"},
+		{Name: "v2"},
+	}})
+
+	tests := []struct {
+		name              string
+		now               time.Time
+		path              string
+		wantStatus        int
+		wantVersionStatus string
+	}{
+		{"before the deprecation", time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC), "/api/v1/leads/4", http.StatusOK, "active"},
+		{"deprecated", time.Date(2026, 12, 1, 0, 0, 0, 0, time.UTC), "/api/v1/leads/4", http.StatusOK, "deprecated"},
+		{"on the sunset date", time.Date(2027, 5, 1, 0, 0, 0, 0, time.UTC), "/api/v1/leads/4", http.StatusGone, "retired"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router.now = func() time.Time { return tt.now }
+			w, call := serveVersioned(t, router, httptest.NewRequest(http.MethodGet, tt.path, nil), nil)
+
+			if w.Code != tt.wantStatus {
+				t.Fatalf("expected %d, got %d", tt.wantStatus, w.Code)
+			}
+			if tt.wantStatus == http.StatusGone && call.path != "" {
+				t.Error("expected a retired version not to reach the backend")
+			}
+
+			// The headers announce the deprecation ahead of its date too
+			header := w.Header()
+			if got := header.Get("Deprecation"); got != "@1793491200" {
+				t.Errorf("Deprecation = %q, want @1793491200", got)
+			}
+			if got := header.Get("Sunset"); got != "Sat, 01 May 2027 00:00:00 GMT" {
+				t.Errorf("Sunset = %q", got)
+			}
+			links := strings.Join(header.Values("Link"), ", ")
+			if !strings.Contains(links, `<https://developers.example.com/migrate-v2>; rel="deprecation"`) ||
+				!strings.Contains(links, `</api/v2/leads/4>; rel="successor-version"`) {
+				t.Errorf("Link = %q", links)
+			}
+
+			if got := router.Versions()[0].Status; got != tt.wantVersionStatus {
+				t.Errorf("status = %q, want %q", got, tt.wantVersionStatus)
+			}
+		})
+	}
+
+	// The current version carries no deprecation headers
+	w, _ := serveVersioned(t, router, httptest.NewRequest(http.MethodGet, "/api/v2/leads/4", nil), nil)
+	if w.Header().Get("Deprecation") != "" || w.Header().Get("Sunset") != "" || len(w.Header().Values("Link")) != 0 {
+		t.Errorf("expected no deprecation headers for the current version, got %v", w.Header())
+	}
+}
+
+func TestVersionConfig_Shipped(t *testing.T) {
+	cfg, err := LoadVersionConfig("../../" + defaultVersionConfigPath)
+	if err != nil {
+		t.Fatalf("LoadVersionConfig() error = %v", err)
+	}
+	router := newTestVersionRouter(t, cfg)
+
+	// No version is deprecated until a date is announced
+	for _, version := range router.Versions() {
+		if version.Deprecated != nil || version.Sunset != nil {
+			t.Errorf("%s: expected no deprecation, got %v and %v", version.Version, version.Deprecated, version.Sunset)
+		}
+	}
+}
+
+func TestNewVersionRouter_Invalid(t *testing.T) {
+	tests := []struct {
+		name     string
+		versions []APIVersionConfig
+	}{
+		{"bad name", []APIVersionConfig{{Name: "2"}}},
+		{"listed twice", []APIVersionConfig{{Name: "v1"}, {Name: "v1"}}},
+		{"bad date", []APIVersionConfig{{Name: "v1", Deprecated: "next year"}, {Name: "v2"}}},
+		{"sunset without deprecation", []APIVersionConfig{{Name: "v1", Sunset: "2027-05-01"}, {Name: "v2"}}},
+		{"sunset before deprecation", []APIVersionConfig{{Name: "v1", Deprecated: "2027-05-01", Sunset: "2026-11-01"}, {Name: "v2"}}},
+		{"current version deprecated", []APIVersionConfig{{Name: "v1"}, {Name: "v2", Deprecated: "2026-11-01"}}},
+		{"translator without paths", []APIVersionConfig{{Name: "v1", Translators: []VersionTranslatorConfig{{Name: "t"}}}}},
+		{"move without from", []APIVersionConfig{{Name: "v1", Translators: []VersionTranslatorConfig{{Paths: []string{"/leads"}, Request: []FieldMove{{To: "a"}}}}}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := NewVersionRouter(&VersionConfig{Versions: tt.versions}); err == nil {
+				t.Error("expected an error")
+			}
+		})
+	}
+}
@@ -5,20 +5,30 @@ package main
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"os"
-	"os/signal"
-	"syscall"
+	"strings"
 	"time"
 
+	"github.com/google/uuid"
+
+	"github.com/kilang-desa-murni/crm/internal/customer/application"
+	"github.com/kilang-desa-murni/crm/internal/customer/application/usecase"
+	"github.com/kilang-desa-murni/crm/internal/customer/domain"
+	customermongo "github.com/kilang-desa-murni/crm/internal/customer/infrastructure/persistence/mongodb"
 	"github.com/kilang-desa-murni/crm/pkg/auth"
 	"github.com/kilang-desa-murni/crm/pkg/config"
 	"github.com/kilang-desa-murni/crm/pkg/database"
 	"github.com/kilang-desa-murni/crm/pkg/events"
+	"github.com/kilang-desa-murni/crm/pkg/lifecycle"
 	"github.com/kilang-desa-murni/crm/pkg/logger"
 	"github.com/kilang-desa-murni/crm/pkg/middleware"
 	"github.com/kilang-desa-murni/crm/pkg/response"
+	"github.com/kilang-desa-murni/crm/pkg/security"
+	"github.com/kilang-desa-murni/crm/pkg/serviceauth"
 	"github.com/kilang-desa-murni/crm/pkg/tracer"
 )
 
@@ -43,9 +53,10 @@ func main() {
 
 	// Initialize logger
 	log := logger.New(logger.Config{
-		Level:  cfg.Logger.Level,
-		Format: cfg.Logger.Format,
-		Caller: cfg.Logger.Caller,
+		Level:    cfg.Logger.Level,
+		Format:   cfg.Logger.Format,
+		Caller:   cfg.Logger.Caller,
+		Shipping: cfg.Logger.Shipping,
 	})
 	log = log.With().Service(cfg.App.Name).Logger()
 	logger.SetGlobal(log)
@@ -56,37 +67,73 @@ func main() {
 		Str("git_commit", GitCommit).
 		Msg("Starting Customer service")
 
+	// Components are stopped in the reverse order they are registered in
+	lc := lifecycle.New(log)
+
+	// Wait for the dependencies to come up rather than crash-looping
+	startup := lifecycle.NewStartup(cfg.Startup, log)
+	startup.ServeHealth(fmt.Sprintf("%s:%d", cfg.Server.Host, cfg.Server.Port), Version)
+
 	// Initialize tracer
 	tr, err := tracer.New(&cfg.Tracer, log)
 	if err != nil {
 		log.Fatal().Err(err).Msg("Failed to initialize tracer")
 	}
-	defer tr.Close(context.Background())
+	lc.OnShutdown("tracer", tr.Close)
 
 	// Initialize MongoDB
-	mongodb, err := database.NewMongoDB(&cfg.MongoDB, log)
+	mongodb, err := lifecycle.Await(startup, "mongodb", func() (*database.MongoDB, error) {
+		return database.NewMongoDB(&cfg.MongoDB, log)
+	})
 	if err != nil {
 		log.Fatal().Err(err).Msg("Failed to connect to MongoDB")
 	}
-	defer mongodb.Close(context.Background())
+	lc.OnShutdown("mongodb", mongodb.Close)
 
 	// Initialize Redis
-	redis, err := database.NewRedis(&cfg.Redis, log)
+	redis, err := lifecycle.Await(startup, "redis", func() (*database.RedisClient, error) {
+		return database.NewRedis(&cfg.Redis, log)
+	})
 	if err != nil {
 		log.Fatal().Err(err).Msg("Failed to connect to Redis")
 	}
-	defer redis.Close()
+	lc.OnClose("redis", redis)
 
 	// Initialize RabbitMQ Event Bus
-	eventBus, err := events.NewRabbitMQEventBus(&cfg.RabbitMQ, log)
+	eventBus, err := lifecycle.Await(startup, "rabbitmq", func() (*events.RabbitMQEventBus, error) {
+		return events.NewRabbitMQEventBus(&cfg.RabbitMQ, log)
+	})
 	if err != nil {
 		log.Fatal().Err(err).Msg("Failed to connect to RabbitMQ")
 	}
-	defer eventBus.Close()
+	lc.OnClose("event bus", eventBus)
 
 	// Initialize JWT Manager for token validation
 	jwtManager := auth.NewJWTManager(&cfg.JWT)
 
+	// Pick up rotated JWT keys when the secrets are refreshed
+	cfg.WatchSecrets(lc.Context(), func(updated *config.Config) {
+		jwtManager.UpdateKeys(updated.JWT)
+		log.Info().Msg("JWT keys refreshed")
+	}, func(err error) {
+		log.Warn().Err(err).Msg("Failed to refresh secrets")
+	})
+
+	// Apply log level changes from the config file or SIGHUP without a restart
+	runtime := config.NewRuntime(cfg)
+	runtime.Watch(lc.Context(), func(err error) {
+		log.Warn().Err(err).Msg("Failed to reload configuration")
+	})
+	go func() {
+		for tunables := range runtime.Subscribe(lc.Context()) {
+			if err := log.SetLevel(tunables.LogLevel); err != nil {
+				log.Warn().Err(err).Msg("Invalid log level")
+				continue
+			}
+			log.Info().Str("level", tunables.LogLevel).Msg("Log level updated")
+		}
+	}()
+
 	// Create HTTP router
 	mux := http.NewServeMux()
 
@@ -109,6 +156,13 @@ func main() {
 			checks["redis"] = response.HealthCheck{Status: "healthy"}
 		}
 
+		// Check RabbitMQ
+		if err := eventBus.Health(r.Context()); err != nil {
+			checks["rabbitmq"] = response.HealthCheck{Status: "unhealthy", Message: err.Error()}
+		} else {
+			checks["rabbitmq"] = response.HealthCheck{Status: "healthy"}
+		}
+
 		status := "healthy"
 		for _, check := range checks {
 			if check.Status != "healthy" {
@@ -120,10 +174,17 @@ func main() {
 		response.Health(w, status, Version, time.Since(startTime), checks)
 	})
 
-	// Metrics endpoint (placeholder)
+	// Shed requests over the adaptive concurrency limit during spikes
+	loadShedder, err := middleware.NewLoadShedder(cfg.App.Name, cfg.LoadShed, log)
+	if err != nil {
+		log.Fatal().Err(err).Msg("Invalid load shedding config")
+	}
+
+	// Metrics endpoint
 	mux.HandleFunc("GET /metrics", func(w http.ResponseWriter, r *http.Request) {
 		w.Header().Set("Content-Type", "text/plain")
-		w.Write([]byte("# Metrics placeholder\n"))
+		loadShedder.WriteMetrics(w)
+		eventBus.WriteMetrics(w)
 	})
 
 	// Customer API routes
@@ -194,17 +255,54 @@ func main() {
 	// Apply middleware
 	handler := middleware.Chain(
 		middleware.RequestID,
-		middleware.Logger(log),
+		middleware.RequestLogger(log, cfg.Logger.Request),
 		middleware.Recover(log),
-		middleware.CORS([]string{"*"}, []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"}, []string{"*"}),
+		loadShedder.Middleware,
+		security.Headers(cfg.Security),
+		security.Sanitize(cfg.Security),
+		middleware.CORSWithPolicy(middleware.NewCORSPolicy(cfg.CORS, cfg.App.Environment, nil)),
 		middleware.ContentType("application/json"),
 		middleware.Auth(jwtManager),
 	)(mux)
 
+	// Tenant data for the IAM service's tenant exports, demo data and user
+	// reassignments, and customer matches for the sales service's duplicate
+	// leads. Internal routes are called by other services without a user
+	// token but with a service token granting the route's scope; the API
+	// gateway does not route them
+	demoDataUseCase := usecase.NewDemoDataUseCase(
+		customermongo.NewCustomerRepository(mongodb.Database()),
+		customermongo.NewDemoDataRepository(mongodb.Database()),
+	)
+	reassignmentUseCase := usecase.NewOwnerReassignmentUseCase(
+		customermongo.NewUnitOfWork(mongodb.Client(), mongodb.Database()),
+		idGenerator{},
+		nil,
+	)
+
+	serviceAuth := serviceauth.NewVerifier(cfg.ServiceAuth, "customer-service")
+	internalMux := http.NewServeMux()
+	internalMux.Handle("GET /internal/tenants/{tenantID}/export/{dataset}",
+		serviceAuth.Require(serviceauth.ScopeTenantsExport)(exportTenantData(customermongo.NewTenantExporter(mongodb.Database()), log)))
+	internalMux.Handle("POST /internal/tenants/{tenantID}/demo-data",
+		serviceAuth.Require(serviceauth.ScopeTenantsDemoData)(seedDemoData(demoDataUseCase, log)))
+	internalMux.Handle("DELETE /internal/tenants/{tenantID}/demo-data",
+		serviceAuth.Require(serviceauth.ScopeTenantsDemoData)(purgeDemoData(demoDataUseCase, log)))
+	internalMux.Handle("GET /internal/tenants/{tenantID}/customers/matches",
+		serviceAuth.Require(serviceauth.ScopeCustomersMatch)(findCustomerMatches(customermongo.NewCustomerRepository(mongodb.Database()), log)))
+	internalMux.Handle("POST /internal/tenants/{tenantID}/reassign/customers",
+		serviceAuth.Require(serviceauth.ScopeTenantsReassign)(reassignCustomers(reassignmentUseCase, log)))
+	internalHandler := middleware.Chain(
+		middleware.RequestID,
+		middleware.RequestLogger(log, cfg.Logger.Request),
+		middleware.Recover(log),
+	)(internalMux)
+
 	// Create public handler (without auth)
 	publicMux := http.NewServeMux()
 	publicMux.Handle("/health", mux)
 	publicMux.Handle("/metrics", mux)
+	publicMux.Handle("/internal/", internalHandler)
 	publicMux.Handle("/", handler)
 
 	// Create HTTP server
@@ -216,7 +314,8 @@ func main() {
 		IdleTimeout:  cfg.Server.IdleTimeout,
 	}
 
-	// Start server in a goroutine
+	// Start server in a goroutine, taking over from the startup health checks
+	startup.Ready()
 	go func() {
 		log.Info().
 			Str("addr", server.Addr).
@@ -226,22 +325,275 @@ func main() {
 			log.Fatal().Err(err).Msg("HTTP server failed")
 		}
 	}()
+	lc.OnShutdown("http server", server.Shutdown)
 
 	// Wait for interrupt signal
-	quit := make(chan os.Signal, 1)
-	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
-	<-quit
-
-	log.Info().Msg("Shutting down server...")
+	sig := lc.Wait()
+	log.Info().Str("signal", sig.String()).Msg("Shutting down server...")
 
-	// Create shutdown context with timeout
-	ctx, cancel := context.WithTimeout(context.Background(), cfg.Server.ShutdownTimeout)
-	defer cancel()
-
-	// Graceful shutdown
-	if err := server.Shutdown(ctx); err != nil {
+	// Stop taking requests, let those in flight finish, then close the
+	// connections
+	if err := lc.Shutdown(cfg.Server.ShutdownTimeout); err != nil {
 		log.Error().Err(err).Msg("Server forced to shutdown")
 	}
 
 	log.Info().Msg("Server stopped")
+
+	// Ship the log lines still buffered
+	logCtx, logCancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer logCancel()
+	if err := log.Close(logCtx); err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to ship remaining logs: %v\n", err)
+	}
+}
+
+// exportTenantData streams a tenant's records of a data set as one JSON
+// object per line. The write deadline is lifted as an export may outlast it,
+// and a failure after the first record aborts the connection so the caller
+// sees a truncated export rather than a complete one.
+func exportTenantData(exporter domain.TenantDataExporter, log *logger.Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		tenantID, err := uuid.Parse(r.PathValue("tenantID"))
+		if err != nil {
+			response.BadRequest(w, "invalid tenant ID")
+			return
+		}
+		dataset := r.PathValue("dataset")
+		if dataset != domain.TenantExportCustomers && dataset != domain.TenantExportContacts {
+			response.NotFound(w, "export data set")
+			return
+		}
+
+		_ = http.NewResponseController(w).SetWriteDeadline(time.Time{})
+
+		out := &exportWriter{w: w}
+		if _, err := exporter.ExportTenantData(r.Context(), tenantID, dataset, out); err != nil {
+			log.Error().Err(err).Str("tenant_id", tenantID.String()).Str("dataset", dataset).Msg("Tenant data export failed")
+			if !out.started {
+				response.InternalError(w, "failed to export tenant data")
+				return
+			}
+			panic(http.ErrAbortHandler)
+		}
+	}
+}
+
+// seedDemoData seeds a tenant's demo customers. The seeded_by query
+// parameter names the user recorded as their creator.
+func seedDemoData(uc *usecase.DemoDataUseCase, log *logger.Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		tenantID, err := uuid.Parse(r.PathValue("tenantID"))
+		if err != nil {
+			response.BadRequest(w, "invalid tenant ID")
+			return
+		}
+		seededBy, err := uuid.Parse(r.URL.Query().Get("seeded_by"))
+		if err != nil {
+			response.BadRequest(w, "invalid seeded_by user ID")
+			return
+		}
+
+		result, err := uc.Seed(r.Context(), tenantID, seededBy)
+		if err != nil {
+			log.Error().Err(err).Str("tenant_id", tenantID.String()).Msg("Demo data seeding failed")
+			response.InternalError(w, "failed to seed demo data")
+			return
+		}
+		response.OK(w, result)
+	}
+}
+
+// purgeDemoData deletes a tenant's demo customers.
+func purgeDemoData(uc *usecase.DemoDataUseCase, log *logger.Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		tenantID, err := uuid.Parse(r.PathValue("tenantID"))
+		if err != nil {
+			response.BadRequest(w, "invalid tenant ID")
+			return
+		}
+
+		result, err := uc.Purge(r.Context(), tenantID)
+		if err != nil {
+			log.Error().Err(err).Str("tenant_id", tenantID.String()).Msg("Demo data purge failed")
+			response.InternalError(w, "failed to purge demo data")
+			return
+		}
+		response.OK(w, result)
+	}
+}
+
+// reassignCustomers transfers a user's customers to other users for the IAM
+// service's user reassignments.
+func reassignCustomers(uc *usecase.OwnerReassignmentUseCase, log *logger.Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		tenantID, err := uuid.Parse(r.PathValue("tenantID"))
+		if err != nil {
+			response.BadRequest(w, "invalid tenant ID")
+			return
+		}
+
+		var input usecase.ReassignCustomersInput
+		if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+			response.BadRequest(w, "invalid request body")
+			return
+		}
+		input.TenantID = tenantID
+
+		result, err := uc.ReassignCustomers(r.Context(), input)
+		if err != nil {
+			var appErr *application.ApplicationError
+			if errors.As(err, &appErr) && application.IsValidationError(err) {
+				response.BadRequest(w, appErr.Message)
+				return
+			}
+			log.Error().Err(err).Str("tenant_id", tenantID.String()).Msg("Customer reassignment failed")
+			response.InternalError(w, "failed to reassign customers")
+			return
+		}
+		response.OK(w, result)
+	}
+}
+
+// customerMatch is a customer a new lead may already be.
+type customerMatch struct {
+	ID        uuid.UUID  `json:"id"`
+	TenantID  uuid.UUID  `json:"tenant_id"`
+	Code      string     `json:"code"`
+	Name      string     `json:"name"`
+	Type      string     `json:"type"`
+	Status    string     `json:"status"`
+	Email     *string    `json:"email,omitempty"`
+	Phone     *string    `json:"phone,omitempty"`
+	OwnerID   *uuid.UUID `json:"owner_id,omitempty"`
+	MatchedOn []string   `json:"matched_on"`
+}
+
+// findCustomerMatches returns the customers matching the email, any of the
+// phone and the name query parameters, for the sales service's duplicate
+// lead detection.
+func findCustomerMatches(customers domain.CustomerRepository, log *logger.Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		tenantID, err := uuid.Parse(r.PathValue("tenantID"))
+		if err != nil {
+			response.BadRequest(w, "invalid tenant ID")
+			return
+		}
+		query := r.URL.Query()
+		email := strings.ToLower(strings.TrimSpace(query.Get("email")))
+		name := strings.TrimSpace(query.Get("name"))
+
+		// Phones are stored in E.164 form
+		phones := make(map[string]bool)
+		for _, raw := range query["phone"] {
+			if phone, err := domain.NewPhoneNumber(raw, ""); err == nil {
+				phones[phone.E164()] = true
+			}
+		}
+
+		// One query per phone number, the first with the email and name
+		lookups := []string{""}
+		if len(phones) > 0 {
+			lookups = lookups[:0]
+			for phone := range phones {
+				lookups = append(lookups, phone)
+			}
+		}
+
+		matches := make([]customerMatch, 0)
+		seen := make(map[uuid.UUID]bool)
+		for i, phone := range lookups {
+			lookupEmail, lookupName := email, name
+			if i > 0 {
+				lookupEmail, lookupName = "", ""
+			}
+			found, err := customers.FindDuplicates(r.Context(), tenantID, lookupEmail, phone, lookupName)
+			if err != nil {
+				log.Error().Err(err).Str("tenant_id", tenantID.String()).Msg("Customer match lookup failed")
+				response.InternalError(w, "failed to find matching customers")
+				return
+			}
+			for _, customer := range found {
+				if seen[customer.ID] {
+					continue
+				}
+				seen[customer.ID] = true
+				matches = append(matches, newCustomerMatch(customer, email, phones, name))
+			}
+		}
+		response.OK(w, matches)
+	}
+}
+
+// newCustomerMatch describes a customer and the details it matched on.
+func newCustomerMatch(customer *domain.Customer, email string, phones map[string]bool, name string) customerMatch {
+	match := customerMatch{
+		ID:        customer.ID,
+		TenantID:  customer.TenantID,
+		Code:      customer.Code,
+		Name:      customer.Name,
+		Type:      string(customer.Type),
+		Status:    string(customer.Status),
+		OwnerID:   customer.OwnerID,
+		MatchedOn: make([]string, 0, 3),
+	}
+	if !customer.Email.IsEmpty() {
+		address := customer.Email.String()
+		match.Email = &address
+		if email != "" && customer.Email.Normalized() == email {
+			match.MatchedOn = append(match.MatchedOn, "email")
+		}
+	}
+	for i, phone := range customer.PhoneNumbers {
+		if i == 0 {
+			number := phone.E164()
+			match.Phone = &number
+		}
+		if phones[phone.E164()] {
+			number := phone.E164()
+			match.Phone = &number
+			match.MatchedOn = append(match.MatchedOn, "phone")
+			break
+		}
+	}
+	if name != "" && strings.EqualFold(customer.Name, name) {
+		match.MatchedOn = append(match.MatchedOn, "company")
+	}
+	return match
+}
+
+// exportWriter sends the response headers on the first write, so an export
+// that fails before writing anything still gets an error response.
+type exportWriter struct {
+	w       http.ResponseWriter
+	started bool
+}
+
+// Write writes p to the response.
+func (e *exportWriter) Write(p []byte) (int, error) {
+	if !e.started {
+		e.started = true
+		e.w.Header().Set("Content-Type", "application/x-ndjson")
+		e.w.WriteHeader(http.StatusOK)
+	}
+	return e.w.Write(p)
+}
+
+// idGenerator generates the IDs of the outbox entries written by the
+// internal routes, which never create customers and so need no codes.
+type idGenerator struct{}
+
+// NewID generates a new UUID.
+func (idGenerator) NewID() uuid.UUID {
+	return uuid.New()
+}
+
+// NewCode is not supported, as no customer or contact is created.
+func (idGenerator) NewCode(ctx context.Context, tenantID uuid.UUID, entityType string) (string, error) {
+	return "", errors.New("customer codes are not generated by internal routes")
+}
+
+// ValidateID validates an ID.
+func (idGenerator) ValidateID(id string) error {
+	_, err := uuid.Parse(id)
+	return err
 }
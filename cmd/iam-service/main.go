@@ -5,20 +5,24 @@ package main
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"net/http"
 	"os"
-	"os/signal"
-	"syscall"
+	"strings"
 	"time"
 
 	"github.com/kilang-desa-murni/crm/pkg/auth"
 	"github.com/kilang-desa-murni/crm/pkg/config"
 	"github.com/kilang-desa-murni/crm/pkg/database"
+	apperrors "github.com/kilang-desa-murni/crm/pkg/errors"
 	"github.com/kilang-desa-murni/crm/pkg/events"
+	"github.com/kilang-desa-murni/crm/pkg/lifecycle"
 	"github.com/kilang-desa-murni/crm/pkg/logger"
 	"github.com/kilang-desa-murni/crm/pkg/middleware"
 	"github.com/kilang-desa-murni/crm/pkg/response"
+	"github.com/kilang-desa-murni/crm/pkg/security"
+	"github.com/kilang-desa-murni/crm/pkg/serviceauth"
 	"github.com/kilang-desa-murni/crm/pkg/tracer"
 )
 
@@ -43,9 +47,10 @@ func main() {
 
 	// Initialize logger
 	log := logger.New(logger.Config{
-		Level:  cfg.Logger.Level,
-		Format: cfg.Logger.Format,
-		Caller: cfg.Logger.Caller,
+		Level:    cfg.Logger.Level,
+		Format:   cfg.Logger.Format,
+		Caller:   cfg.Logger.Caller,
+		Shipping: cfg.Logger.Shipping,
 	})
 	log = log.With().Service(cfg.App.Name).Logger()
 	logger.SetGlobal(log)
@@ -56,37 +61,78 @@ func main() {
 		Str("git_commit", GitCommit).
 		Msg("Starting IAM service")
 
+	// Components are stopped in the reverse order they are registered in
+	lc := lifecycle.New(log)
+
+	// Wait for the dependencies to come up rather than crash-looping
+	startup := lifecycle.NewStartup(cfg.Startup, log)
+	startup.ServeHealth(fmt.Sprintf("%s:%d", cfg.Server.Host, cfg.Server.Port), Version)
+
 	// Initialize tracer
 	tr, err := tracer.New(&cfg.Tracer, log)
 	if err != nil {
 		log.Fatal().Err(err).Msg("Failed to initialize tracer")
 	}
-	defer tr.Close(context.Background())
+	lc.OnShutdown("tracer", tr.Close)
 
 	// Initialize PostgreSQL
-	db, err := database.NewPostgres(&cfg.Database, log)
+	db, err := lifecycle.Await(startup, "postgresql", func() (*database.PostgresDB, error) {
+		return database.NewPostgres(&cfg.Database, log)
+	})
 	if err != nil {
 		log.Fatal().Err(err).Msg("Failed to connect to PostgreSQL")
 	}
-	defer db.Close()
+	lc.OnClose("postgres", db)
 
 	// Initialize Redis
-	redis, err := database.NewRedis(&cfg.Redis, log)
+	redis, err := lifecycle.Await(startup, "redis", func() (*database.RedisClient, error) {
+		return database.NewRedis(&cfg.Redis, log)
+	})
 	if err != nil {
 		log.Fatal().Err(err).Msg("Failed to connect to Redis")
 	}
-	defer redis.Close()
+	lc.OnClose("redis", redis)
 
 	// Initialize RabbitMQ Event Bus
-	eventBus, err := events.NewRabbitMQEventBus(&cfg.RabbitMQ, log)
+	eventBus, err := lifecycle.Await(startup, "rabbitmq", func() (*events.RabbitMQEventBus, error) {
+		return events.NewRabbitMQEventBus(&cfg.RabbitMQ, log)
+	})
 	if err != nil {
 		log.Fatal().Err(err).Msg("Failed to connect to RabbitMQ")
 	}
-	defer eventBus.Close()
+	lc.OnClose("event bus", eventBus)
 
 	// Initialize JWT Manager
 	jwtManager := auth.NewJWTManager(&cfg.JWT)
 
+	// Pick up rotated JWT keys when the secrets are refreshed
+	cfg.WatchSecrets(lc.Context(), func(updated *config.Config) {
+		jwtManager.UpdateKeys(updated.JWT)
+		log.Info().Msg("JWT keys refreshed")
+	}, func(err error) {
+		log.Warn().Err(err).Msg("Failed to refresh secrets")
+	})
+
+	// Apply log level changes from the config file or SIGHUP without a restart
+	runtime := config.NewRuntime(cfg)
+	runtime.Watch(lc.Context(), func(err error) {
+		log.Warn().Err(err).Msg("Failed to reload configuration")
+	})
+	go func() {
+		for tunables := range runtime.Subscribe(lc.Context()) {
+			if err := log.SetLevel(tunables.LogLevel); err != nil {
+				log.Warn().Err(err).Msg("Invalid log level")
+				continue
+			}
+			log.Info().Str("level", tunables.LogLevel).Msg("Log level updated")
+		}
+	}()
+
+	// Opaque access tokens keep their claims in Redis, where introspection
+	// looks them up; JWTs are introspected by validating them
+	tokens := auth.NewOpaqueTokenManager(jwtManager, redis)
+	log.Info().Str("mode", cfg.JWT.TokenMode).Msg("Token mode configured")
+
 	// Initialize Password Hasher
 	_ = auth.NewPasswordHasher(nil)
 
@@ -112,6 +158,13 @@ func main() {
 			checks["redis"] = response.HealthCheck{Status: "healthy"}
 		}
 
+		// Check RabbitMQ
+		if err := eventBus.Health(r.Context()); err != nil {
+			checks["rabbitmq"] = response.HealthCheck{Status: "unhealthy", Message: err.Error()}
+		} else {
+			checks["rabbitmq"] = response.HealthCheck{Status: "healthy"}
+		}
+
 		status := "healthy"
 		for _, check := range checks {
 			if check.Status != "healthy" {
@@ -123,10 +176,17 @@ func main() {
 		response.Health(w, status, Version, time.Since(startTime), checks)
 	})
 
-	// Metrics endpoint (placeholder)
+	// Shed requests over the adaptive concurrency limit during spikes
+	loadShedder, err := middleware.NewLoadShedder(cfg.App.Name, cfg.LoadShed, log)
+	if err != nil {
+		log.Fatal().Err(err).Msg("Invalid load shedding config")
+	}
+
+	// Metrics endpoint
 	mux.HandleFunc("GET /metrics", func(w http.ResponseWriter, r *http.Request) {
 		w.Header().Set("Content-Type", "text/plain")
-		w.Write([]byte("# Metrics placeholder\n"))
+		loadShedder.WriteMetrics(w)
+		eventBus.WriteMetrics(w)
 	})
 
 	// API routes
@@ -136,12 +196,15 @@ func main() {
 
 	mux.HandleFunc("POST /api/v1/auth/login", func(w http.ResponseWriter, r *http.Request) {
 		// Demo login endpoint
-		tokenPair, err := jwtManager.GenerateTokenPair(
-			"user-123",
-			"tenant-456",
-			"demo@example.com",
-			[]string{"admin"},
-		)
+		userID, tenantID, email, roles := "user-123", "tenant-456", "demo@example.com", []string{"admin"}
+
+		var tokenPair *auth.TokenPair
+		var err error
+		if cfg.JWT.TokenMode == config.TokenModeOpaque {
+			tokenPair, err = tokens.GenerateTokenPair(r.Context(), userID, tenantID, email, roles)
+		} else {
+			tokenPair, err = jwtManager.GenerateTokenPair(userID, tenantID, email, roles)
+		}
 		if err != nil {
 			response.Error(w, err)
 			return
@@ -154,9 +217,42 @@ func main() {
 	})
 
 	mux.HandleFunc("POST /api/v1/auth/logout", func(w http.ResponseWriter, r *http.Request) {
+		// Opaque access tokens are revoked at once, though gateways accept
+		// them until their cached introspection expires
+		token := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if err := tokens.Revoke(r.Context(), token); err != nil {
+			response.Error(w, err)
+			return
+		}
 		response.NoContent(w)
 	})
 
+	// Token introspection for the gateway, exchanging access tokens for
+	// their claims. Internal: the gateway does not route /internal paths,
+	// and callers need a service token
+	serviceAuth := serviceauth.NewVerifier(cfg.ServiceAuth, "iam-service")
+	mux.Handle("POST /internal/auth/introspect", serviceAuth.Require(serviceauth.ScopeTokensIntrospect)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req auth.IntrospectionRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.Token == "" {
+			response.BadRequest(w, "token is required")
+			return
+		}
+
+		claims, err := tokens.Introspect(r.Context(), req.Token)
+		if err != nil {
+			// Invalid and expired tokens are inactive rather than errors
+			for _, code := range []apperrors.ErrorCode{apperrors.ErrCodeTokenInvalid, apperrors.ErrCodeTokenExpired} {
+				if apperrors.Is(err, code) {
+					response.OK(w, auth.Introspection{Active: false, Code: string(code)})
+					return
+				}
+			}
+			response.Error(w, err)
+			return
+		}
+		response.OK(w, auth.Introspection{Active: true, Claims: claims})
+	})))
+
 	// Protected routes (require authentication)
 	mux.HandleFunc("GET /api/v1/users", func(w http.ResponseWriter, r *http.Request) {
 		response.OK(w, map[string]string{"message": "List users - TODO"})
@@ -174,22 +270,36 @@ func main() {
 	// Apply middleware
 	handler := middleware.Chain(
 		middleware.RequestID,
-		middleware.Logger(log),
+		middleware.RequestLogger(log, cfg.Logger.Request),
 		middleware.Recover(log),
-		middleware.CORS([]string{"*"}, []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"}, []string{"*"}),
+		loadShedder.Middleware,
+		security.Headers(cfg.Security),
+		security.Sanitize(cfg.Security),
+		middleware.CORSWithPolicy(middleware.NewCORSPolicy(cfg.CORS, cfg.App.Environment, nil)),
 		middleware.ContentType("application/json"),
 	)(mux)
 
+	// Service tokens for calls between the services. The token endpoint
+	// takes OAuth 2.0 form posts, so it skips the JSON-only middleware
+	rootMux := http.NewServeMux()
+	rootMux.Handle("POST /internal/auth/token", middleware.Chain(
+		middleware.RequestID,
+		middleware.RequestLogger(log, cfg.Logger.Request),
+		middleware.Recover(log),
+	)(serviceauth.NewIssuer(cfg.ServiceAuth)))
+	rootMux.Handle("/", handler)
+
 	// Create HTTP server
 	server := &http.Server{
 		Addr:         fmt.Sprintf("%s:%d", cfg.Server.Host, cfg.Server.Port),
-		Handler:      handler,
+		Handler:      rootMux,
 		ReadTimeout:  cfg.Server.ReadTimeout,
 		WriteTimeout: cfg.Server.WriteTimeout,
 		IdleTimeout:  cfg.Server.IdleTimeout,
 	}
 
-	// Start server in a goroutine
+	// Start server in a goroutine, taking over from the startup health checks
+	startup.Ready()
 	go func() {
 		log.Info().
 			Str("addr", server.Addr).
@@ -199,22 +309,24 @@ func main() {
 			log.Fatal().Err(err).Msg("HTTP server failed")
 		}
 	}()
+	lc.OnShutdown("http server", server.Shutdown)
 
 	// Wait for interrupt signal
-	quit := make(chan os.Signal, 1)
-	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
-	<-quit
-
-	log.Info().Msg("Shutting down server...")
-
-	// Create shutdown context with timeout
-	ctx, cancel := context.WithTimeout(context.Background(), cfg.Server.ShutdownTimeout)
-	defer cancel()
+	sig := lc.Wait()
+	log.Info().Str("signal", sig.String()).Msg("Shutting down server...")
 
-	// Graceful shutdown
-	if err := server.Shutdown(ctx); err != nil {
+	// Stop taking requests, let those in flight finish, then close the
+	// connections
+	if err := lc.Shutdown(cfg.Server.ShutdownTimeout); err != nil {
 		log.Error().Err(err).Msg("Server forced to shutdown")
 	}
 
 	log.Info().Msg("Server stopped")
+
+	// Ship the log lines still buffered
+	logCtx, logCancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer logCancel()
+	if err := log.Close(logCtx); err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to ship remaining logs: %v\n", err)
+	}
 }
@@ -5,19 +5,42 @@ package main
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"net/http"
 	"os"
-	"os/signal"
-	"syscall"
+	"strconv"
+	"strings"
 	"time"
 
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+
+	"github.com/kilang-desa-murni/crm/internal/notification/application"
+	"github.com/kilang-desa-murni/crm/internal/notification/application/dto"
+	"github.com/kilang-desa-murni/crm/internal/notification/application/ports"
+	"github.com/kilang-desa-murni/crm/internal/notification/application/usecase"
+	"github.com/kilang-desa-murni/crm/internal/notification/domain"
+	"github.com/kilang-desa-murni/crm/internal/notification/infrastructure/messaging"
+	"github.com/kilang-desa-murni/crm/internal/notification/infrastructure/persistence/postgres"
+	"github.com/kilang-desa-murni/crm/internal/notification/infrastructure/providers/chat"
+	"github.com/kilang-desa-murni/crm/internal/notification/infrastructure/providers/push"
+	"github.com/kilang-desa-murni/crm/internal/notification/infrastructure/worker"
+	"github.com/kilang-desa-murni/crm/pkg/auth"
 	"github.com/kilang-desa-murni/crm/pkg/config"
 	"github.com/kilang-desa-murni/crm/pkg/database"
+	apperrors "github.com/kilang-desa-murni/crm/pkg/errors"
+	"github.com/kilang-desa-murni/crm/pkg/etag"
 	"github.com/kilang-desa-murni/crm/pkg/events"
+	"github.com/kilang-desa-murni/crm/pkg/lifecycle"
 	"github.com/kilang-desa-murni/crm/pkg/logger"
 	"github.com/kilang-desa-murni/crm/pkg/middleware"
+	"github.com/kilang-desa-murni/crm/pkg/render"
 	"github.com/kilang-desa-murni/crm/pkg/response"
+	"github.com/kilang-desa-murni/crm/pkg/security"
+	"github.com/kilang-desa-murni/crm/pkg/serviceauth"
 	"github.com/kilang-desa-murni/crm/pkg/tracer"
 )
 
@@ -42,9 +65,10 @@ func main() {
 
 	// Initialize logger
 	log := logger.New(logger.Config{
-		Level:  cfg.Logger.Level,
-		Format: cfg.Logger.Format,
-		Caller: cfg.Logger.Caller,
+		Level:    cfg.Logger.Level,
+		Format:   cfg.Logger.Format,
+		Caller:   cfg.Logger.Caller,
+		Shipping: cfg.Logger.Shipping,
 	})
 	log = log.With().Service(cfg.App.Name).Logger()
 	logger.SetGlobal(log)
@@ -55,43 +79,167 @@ func main() {
 		Str("git_commit", GitCommit).
 		Msg("Starting Notification service")
 
+	// Components are stopped in the reverse order they are registered in
+	lc := lifecycle.New(log)
+
+	// Wait for the dependencies to come up rather than crash-looping
+	startup := lifecycle.NewStartup(cfg.Startup, log)
+	startup.ServeHealth(fmt.Sprintf("%s:%d", cfg.Server.Host, cfg.Server.Port), Version)
+
 	// Initialize tracer
 	tr, err := tracer.New(&cfg.Tracer, log)
 	if err != nil {
 		log.Fatal().Err(err).Msg("Failed to initialize tracer")
 	}
-	defer tr.Close(context.Background())
+	lc.OnShutdown("tracer", tr.Close)
 
 	// Initialize PostgreSQL
-	db, err := database.NewPostgres(&cfg.Database, log)
+	db, err := lifecycle.Await(startup, "postgresql", func() (*database.PostgresDB, error) {
+		return database.NewPostgres(&cfg.Database, log)
+	})
 	if err != nil {
 		log.Fatal().Err(err).Msg("Failed to connect to PostgreSQL")
 	}
-	defer db.Close()
+	lc.OnClose("postgres", db)
 
 	// Initialize Redis
-	redis, err := database.NewRedis(&cfg.Redis, log)
+	redis, err := lifecycle.Await(startup, "redis", func() (*database.RedisClient, error) {
+		return database.NewRedis(&cfg.Redis, log)
+	})
 	if err != nil {
 		log.Fatal().Err(err).Msg("Failed to connect to Redis")
 	}
-	defer redis.Close()
+	lc.OnClose("redis", redis)
 
 	// Initialize RabbitMQ Event Bus
-	eventBus, err := events.NewRabbitMQEventBus(&cfg.RabbitMQ, log)
+	eventBus, err := lifecycle.Await(startup, "rabbitmq", func() (*events.RabbitMQEventBus, error) {
+		return events.NewRabbitMQEventBus(&cfg.RabbitMQ, log)
+	})
 	if err != nil {
 		log.Fatal().Err(err).Msg("Failed to connect to RabbitMQ")
 	}
-	defer eventBus.Close()
+	lc.OnClose("event bus", eventBus)
+
+	// Erase customers' personal data from sent notifications when the
+	// customer service erases them
+	erasureConsumer, err := messaging.NewCustomerErasureConsumer(messaging.CustomerErasureConsumerConfig{
+		URL:            cfg.RabbitMQ.URL,
+		PrefetchCount:  cfg.RabbitMQ.PrefetchCount,
+		ReconnectDelay: cfg.RabbitMQ.ReconnectDelay,
+	}, postgres.NewRecipientEraser(sqlx.NewDb(db.DB, "postgres")), log)
+	if err != nil {
+		log.Warn().Err(err).Msg("Failed to initialize customer erasure consumer, erasures stay pending until it starts")
+	} else {
+		lc.OnShutdown("customer erasure consumer", erasureConsumer.Shutdown)
+		if err := erasureConsumer.Start(context.Background()); err != nil {
+			log.Warn().Err(err).Msg("Failed to start customer erasure consumer")
+		}
+	}
+
+	// Start the dispatch workers that handle subscribed events. Failed events
+	// are retried by the retry policy of their channel, and a notification.failed
+	// event is published for those that run out of attempts.
+	policies, err := retryPolicies(cfg.Notification)
+	if err != nil {
+		log.Fatal().Err(err).Msg("Invalid notification retry policy")
+	}
+	// Sent emails are archived for as long as their tenant's retention.
+	retention, err := archiveRetention(cfg.Notification)
+	if err != nil {
+		log.Fatal().Err(err).Msg("Invalid email archive retention")
+	}
+	log.Info().Int("default_days", retention.DefaultDays).Int("tenant_overrides", len(retention.TenantDays)).Msg("Email archive retention configured")
+
+	// Bodies of sent notifications are cleared once their tenant's body
+	// retention passes; the delivery records are kept.
+	bodies, err := bodyRetention(cfg.Notification)
+	if err != nil {
+		log.Fatal().Err(err).Msg("Invalid notification body retention")
+	}
+	bodyWorker := worker.NewBodyRetentionWorker(worker.BodyRetentionConfig{
+		Retention: bodies,
+		Interval:  cfg.Notification.BodyPurgeInterval,
+	}, postgres.NewNotificationRepository(sqlx.NewDb(db.DB, "postgres")), log)
+	bodyWorker.Start(context.Background())
+	lc.OnShutdown("body retention worker", bodyWorker.Shutdown)
+
+	// Browsers subscribe to push notifications with the tenant's VAPID key;
+	// subscriptions past their expiration time are removed
+	var webPushKeys ports.WebPushKeys
+	if cfg.Notification.WebPush.Enabled() {
+		keys, err := vapidKeys(cfg.Notification.WebPush)
+		if err != nil {
+			log.Fatal().Err(err).Msg("Invalid web push configuration")
+		}
+		webPushKeys = keys
+		log.Info().Int("tenant_keys", len(cfg.Notification.WebPush.TenantKeys)).Msg("Web push configured")
+	}
+	subscriptionWorker := worker.NewSubscriptionCleanupWorker(worker.SubscriptionCleanupConfig{
+		Interval: cfg.Notification.WebPush.CleanupInterval,
+	}, postgres.NewDeviceRepository(sqlx.NewDb(db.DB, "postgres")), log)
+	subscriptionWorker.Start(context.Background())
+	lc.OnShutdown("push subscription cleanup worker", subscriptionWorker.Shutdown)
+
+	// Sales events are posted to the Slack and Teams channels tenants
+	// connect through incoming webhooks
+	chatIntegrationUseCase := usecase.NewChatIntegrationUseCase(
+		postgres.NewChatIntegrationRepository(sqlx.NewDb(db.DB, "postgres")),
+		chat.NewSlackWebhookProvider(chat.DefaultWebhookConfig()),
+		chat.NewTeamsWebhookProvider(chat.DefaultWebhookConfig()),
+	)
+
+	// Subscribed events are routed to audiences and channels by the routing
+	// rules of their tenant, or by the default rules. The rules are reloaded
+	// periodically to pick up changes made through other instances
+	routingRuleUseCase := usecase.NewRoutingRuleUseCase(postgres.NewRoutingRuleRepository(sqlx.NewDb(db.DB, "postgres")))
+	routingWorker := worker.NewRoutingReloadWorker(cfg.Notification.RoutingReloadInterval, routingRuleUseCase, log)
+	routingWorker.Start(context.Background())
+	lc.OnShutdown("routing reload worker", routingWorker.Shutdown)
+
+	poolConfig := dispatchPoolConfig(cfg.Notification)
+	poolConfig.RetryPolicies = &policies
+	poolConfig.OnExhausted = func(ctx context.Context, failure worker.DispatchFailure) {
+		event := events.NewEvent(events.EventTypeNotificationFailed, "", "", map[string]interface{}{
+			"channel":    failure.Channel.String(),
+			"attempts":   failure.Attempts,
+			"provider":   failure.Provider,
+			"error_code": failure.ErrorCode,
+			"error":      failure.Err.Error(),
+		})
+		if err := eventBus.Publish(ctx, event); err != nil {
+			log.Error().Err(err).Msg("Failed to publish notification failed event")
+		}
+	}
+	dispatchPool := worker.NewDispatchPool(poolConfig, nil, log)
+	dispatchPool.Start(context.Background())
+	lc.OnShutdown("dispatch pool", dispatchPool.Shutdown)
 
-	// Subscribe to events
+	// Critical alerts are escalated through the on-call levels of the
+	// tenant's escalation policies until acknowledged. Pages go out on the
+	// dispatch workers, and unacknowledged levels are escalated periodically
+	escalationUseCase := usecase.NewEscalationUseCase(
+		postgres.NewEscalationPolicyRepository(sqlx.NewDb(db.DB, "postgres")),
+		postgres.NewOnCallScheduleRepository(sqlx.NewDb(db.DB, "postgres")),
+		postgres.NewEscalationRepository(sqlx.NewDb(db.DB, "postgres")),
+		pageEscalation(dispatchPool, log),
+	)
+	onCallScheduleUseCase := usecase.NewOnCallScheduleUseCase(postgres.NewOnCallScheduleRepository(sqlx.NewDb(db.DB, "postgres")))
+	escalationWorker := worker.NewEscalationWorker(cfg.Notification.EscalationInterval, 0, escalationUseCase, log)
+	escalationWorker.Start(context.Background())
+	lc.OnShutdown("escalation worker", escalationWorker.Shutdown)
+
+	// Subscribe to events. The subscriptions are drained before the dispatch
+	// pool stops, so every accepted event is dispatched
+	lc.OnShutdown("event subscriptions", eventBus.Drain)
 	go func() {
-		eventTypes := []events.EventType{
-			events.EventTypeUserCreated,
-			events.EventTypeLeadCreated,
-			events.EventTypeOpportunityWon,
-			events.EventTypeOpportunityLost,
-			events.EventTypeEmailSend,
-			events.EventTypeSMSSend,
+		eventTypes := []events.EventType{events.EventTypeTenantCreated}
+		for _, eventType := range domain.RoutableEventTypes {
+			eventTypes = append(eventTypes, events.EventType(eventType))
+		}
+		for _, eventType := range domain.EscalatableEventTypes {
+			if !domain.IsRoutableEventType(eventType) {
+				eventTypes = append(eventTypes, events.EventType(eventType))
+			}
 		}
 
 		err := eventBus.Subscribe(context.Background(), eventTypes, func(ctx context.Context, event *events.Event) error {
@@ -101,28 +249,31 @@ func main() {
 				Str("tenant_id", event.TenantID).
 				Msg("Received event")
 
-			// Handle different event types
-			switch event.Type {
-			case events.EventTypeUserCreated:
-				// Send welcome email
-				log.Info().Str("user_id", event.AggregateID).Msg("Sending welcome email")
-			case events.EventTypeLeadCreated:
-				// Notify sales team
-				log.Info().Str("lead_id", event.AggregateID).Msg("Notifying sales team of new lead")
-			case events.EventTypeOpportunityWon:
-				// Send confirmation to customer
-				log.Info().Str("opportunity_id", event.AggregateID).Msg("Sending deal confirmation")
-			case events.EventTypeOpportunityLost:
-				// Send follow-up survey
-				log.Info().Str("opportunity_id", event.AggregateID).Msg("Sending follow-up survey")
-			case events.EventTypeEmailSend:
-				// Send email
-				log.Info().Interface("data", event.Data).Msg("Sending email")
-			case events.EventTypeSMSSend:
-				// Send SMS
-				log.Info().Interface("data", event.Data).Msg("Sending SMS")
+			if event.Type == events.EventTypeTenantCreated {
+				// Seed the welcome and password reset templates, in English
+				// and Malay
+				return dispatchPool.Submit(ctx, domain.ChannelEmail, func(ctx context.Context) error {
+					log.Info().Str("tenant_id", event.TenantID).Msg("Seeding system templates")
+					return nil
+				})
 			}
 
+			// Notifications are handled on the workers of their channel. A
+			// full queue returns an error, so the event is redelivered later.
+			if err := routeEvent(ctx, routingRuleUseCase, dispatchPool, event, log); err != nil {
+				return err
+			}
+			// Critical alerts also page on-call until acknowledged
+			if domain.IsEscalatableEventType(string(event.Type)) {
+				if err := escalationUseCase.Escalate(ctx, routedEvent(event)); err != nil {
+					return err
+				}
+			}
+			// Chat posts have their own workers, so slow webhooks do not hold
+			// up email and in-app notifications
+			if chatEvent := salesChatEvent(event); chatEvent != nil {
+				return dispatchPool.Submit(ctx, domain.ChannelSlack, postChatEvent(chatIntegrationUseCase, chatEvent, log))
+			}
 			return nil
 		})
 
@@ -131,6 +282,30 @@ func main() {
 		}
 	}()
 
+	// Initialize priority delivery queue
+	deliveryQueue, err := messaging.NewPriorityQueue(messaging.PriorityQueueConfig{
+		URL:            cfg.RabbitMQ.URL,
+		Concurrency:    cfg.Notification.DeliveryConcurrency,
+		Policy:         dispatchPolicy(cfg.Notification),
+		ReconnectDelay: cfg.RabbitMQ.ReconnectDelay,
+	})
+	if err != nil {
+		log.Fatal().Err(err).Msg("Failed to initialize delivery queue")
+	}
+	lc.OnShutdown("delivery queue", deliveryQueue.Shutdown)
+
+	if err := deliveryQueue.Consume(context.Background(), func(ctx context.Context, job *ports.DeliveryJob) error {
+		log.Info().
+			Str("notification_id", job.NotificationID).
+			Str("channel", job.Channel.String()).
+			Str("priority", job.Priority.String()).
+			Dur("waited", time.Since(job.EnqueuedAt)).
+			Msg("Delivering queued notification")
+		return nil
+	}); err != nil {
+		log.Fatal().Err(err).Msg("Failed to consume delivery queue")
+	}
+
 	// Create HTTP router
 	mux := http.NewServeMux()
 
@@ -153,6 +328,13 @@ func main() {
 			checks["redis"] = response.HealthCheck{Status: "healthy"}
 		}
 
+		// Check RabbitMQ
+		if err := eventBus.Health(r.Context()); err != nil {
+			checks["rabbitmq"] = response.HealthCheck{Status: "unhealthy", Message: err.Error()}
+		} else {
+			checks["rabbitmq"] = response.HealthCheck{Status: "healthy"}
+		}
+
 		status := "healthy"
 		for _, check := range checks {
 			if check.Status != "healthy" {
@@ -164,10 +346,18 @@ func main() {
 		response.Health(w, status, Version, time.Since(startTime), checks)
 	})
 
-	// Metrics endpoint (placeholder)
+	// Shed requests over the adaptive concurrency limit during spikes
+	loadShedder, err := middleware.NewLoadShedder(cfg.App.Name, cfg.LoadShed, log)
+	if err != nil {
+		log.Fatal().Err(err).Msg("Invalid load shedding config")
+	}
+
+	// Metrics endpoint
 	mux.HandleFunc("GET /metrics", func(w http.ResponseWriter, r *http.Request) {
 		w.Header().Set("Content-Type", "text/plain")
-		w.Write([]byte("# Metrics placeholder\n"))
+		writeDispatchMetrics(w, dispatchPool.Stats())
+		loadShedder.WriteMetrics(w)
+		eventBus.WriteMetrics(w)
 	})
 
 	// Notification API routes
@@ -175,11 +365,108 @@ func main() {
 		response.Paginated(w, []interface{}{}, 1, 10, 0)
 	})
 
+	// Delivery statistics; from and to are RFC 3339 times or YYYY-MM-DD dates
+	mux.HandleFunc("GET /api/v1/notifications/stats", func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		from, errFrom := parseStatsTime(q.Get("from"))
+		to, errTo := parseStatsTime(q.Get("to"))
+		if errFrom != nil || errTo != nil {
+			response.BadRequest(w, "from and to must be RFC 3339 times or YYYY-MM-DD dates")
+			return
+		}
+		if !from.IsZero() && !to.IsZero() && !from.Before(to) {
+			response.BadRequest(w, "from must be before to")
+			return
+		}
+		response.OK(w, map[string]interface{}{
+			"start_date":  from,
+			"end_date":    to,
+			"channel":     q.Get("channel"),
+			"period":      q.Get("interval"),
+			"by_channel":  map[string]interface{}{},
+			"by_template": []interface{}{},
+			"series":      []interface{}{},
+		})
+	})
+
+	// Sent email archive, restricted to auditors
+	jwtManager := auth.NewJWTManager(&cfg.JWT)
+
+	// Pick up rotated JWT keys when the secrets are refreshed
+	cfg.WatchSecrets(lc.Context(), func(updated *config.Config) {
+		jwtManager.UpdateKeys(updated.JWT)
+		log.Info().Msg("JWT keys refreshed")
+	}, func(err error) {
+		log.Warn().Err(err).Msg("Failed to refresh secrets")
+	})
+
+	// Apply log level changes from the config file or SIGHUP without a restart
+	runtime := config.NewRuntime(cfg)
+	runtime.Watch(lc.Context(), func(err error) {
+		log.Warn().Err(err).Msg("Failed to reload configuration")
+	})
+	go func() {
+		for tunables := range runtime.Subscribe(lc.Context()) {
+			if err := log.SetLevel(tunables.LogLevel); err != nil {
+				log.Warn().Err(err).Msg("Invalid log level")
+				continue
+			}
+			log.Info().Str("level", tunables.LogLevel).Msg("Log level updated")
+		}
+	}()
+	auditor := func(h http.HandlerFunc) http.Handler {
+		return middleware.Chain(middleware.Auth(jwtManager), middleware.RequirePermission("notifications:audit"))(h)
+	}
+	mux.Handle("GET /api/v1/notifications/archive", auditor(func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		after, errAfter := parseStatsTime(q.Get("sent_after"))
+		before, errBefore := parseStatsTime(q.Get("sent_before"))
+		if errAfter != nil || errBefore != nil {
+			response.BadRequest(w, "sent_after and sent_before must be RFC 3339 times or YYYY-MM-DD dates")
+			return
+		}
+		if !after.IsZero() && !before.IsZero() && !after.Before(before) {
+			response.BadRequest(w, "sent_after must be before sent_before")
+			return
+		}
+		response.Paginated(w, []interface{}{}, 1, 20, 0)
+	}))
+	mux.Handle("GET /api/v1/notifications/archive/{id}", auditor(func(w http.ResponseWriter, r *http.Request) {
+		response.OK(w, map[string]string{"message": "Get archived email", "id": r.PathValue("id")})
+	}))
+	mux.Handle("GET /api/v1/notifications/archive/{id}/raw", auditor(func(w http.ResponseWriter, r *http.Request) {
+		response.NotFound(w, "archived email")
+	}))
+
 	mux.HandleFunc("GET /api/v1/notifications/{id}", func(w http.ResponseWriter, r *http.Request) {
 		id := r.PathValue("id")
 		response.OK(w, map[string]string{"message": "Get notification", "id": id})
 	})
 
+	// Delivery history and provider status callbacks
+	// Registered as {id}/{view}: a literal timeline segment would be ambiguous
+	// with templates/{id} for the path templates/timeline.
+	mux.HandleFunc("GET /api/v1/notifications/{id}/{view}", func(w http.ResponseWriter, r *http.Request) {
+		if r.PathValue("view") != "timeline" {
+			response.NotFound(w, "resource")
+			return
+		}
+		id := r.PathValue("id")
+		response.OK(w, map[string]interface{}{"notification_id": id, "entries": []interface{}{}})
+	})
+
+	mux.HandleFunc("POST /api/v1/notifications/webhooks/{provider}", func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			ProviderMessageID string `json:"provider_message_id"`
+			Status            string `json:"status"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.ProviderMessageID == "" || req.Status == "" {
+			response.BadRequest(w, "provider_message_id and status are required")
+			return
+		}
+		response.OK(w, map[string]interface{}{"provider": r.PathValue("provider"), "provider_message_id": req.ProviderMessageID, "updated": false})
+	})
+
 	// Template API routes
 	mux.HandleFunc("GET /api/v1/notifications/templates", func(w http.ResponseWriter, r *http.Request) {
 		response.OK(w, []interface{}{})
@@ -189,20 +476,144 @@ func main() {
 		response.Created(w, map[string]string{"message": "Create template - TODO"})
 	})
 
-	mux.HandleFunc("GET /api/v1/notifications/templates/{id}", func(w http.ResponseWriter, r *http.Request) {
+	// Template reads are tagged with an ETag; writes require If-Match
+	getTemplate := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		id := r.PathValue("id")
 		response.OK(w, map[string]string{"message": "Get template", "id": id})
 	})
+	templateETag := etag.Resource(getTemplate)
+
+	mux.Handle("GET /api/v1/notifications/templates/{id}", templateETag(getTemplate))
 
-	mux.HandleFunc("PUT /api/v1/notifications/templates/{id}", func(w http.ResponseWriter, r *http.Request) {
+	mux.Handle("PUT /api/v1/notifications/templates/{id}", templateETag(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		id := r.PathValue("id")
 		response.OK(w, map[string]string{"message": "Update template", "id": id})
+	})))
+
+	mux.Handle("DELETE /api/v1/notifications/templates/{id}", templateETag(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		response.NoContent(w)
+	})))
+
+	// Template version history
+	mux.HandleFunc("GET /api/v1/notifications/templates/{id}/versions", func(w http.ResponseWriter, r *http.Request) {
+		id := r.PathValue("id")
+		response.OK(w, map[string]interface{}{"template_id": id, "versions": []interface{}{}, "total_count": 0})
+	})
+
+	mux.HandleFunc("GET /api/v1/notifications/templates/{id}/versions/diff", func(w http.ResponseWriter, r *http.Request) {
+		id := r.PathValue("id")
+		from, errFrom := strconv.Atoi(r.URL.Query().Get("from"))
+		to, errTo := strconv.Atoi(r.URL.Query().Get("to"))
+		if errFrom != nil || errTo != nil || from < 1 || to < 1 {
+			response.BadRequest(w, "from and to must be positive version numbers")
+			return
+		}
+		response.OK(w, map[string]interface{}{"template_id": id, "from_version": from, "to_version": to, "changes": []interface{}{}})
+	})
+
+	mux.HandleFunc("POST /api/v1/notifications/templates/{id}/versions/{version}/rollback", func(w http.ResponseWriter, r *http.Request) {
+		id := r.PathValue("id")
+		version, err := strconv.Atoi(r.PathValue("version"))
+		if err != nil || version < 1 {
+			response.BadRequest(w, "version must be a positive version number")
+			return
+		}
+		response.OK(w, map[string]interface{}{"message": "Rollback template", "template_id": id, "reverted_to": version})
+	})
+
+	// Template preview and test sends
+	mux.HandleFunc("POST /api/v1/notifications/templates/{id}/preview", func(w http.ResponseWriter, r *http.Request) {
+		id := r.PathValue("id")
+		response.OK(w, map[string]interface{}{"template_id": id, "subject": "", "body": "", "variables": map[string]interface{}{}})
+	})
+
+	mux.HandleFunc("POST /api/v1/notifications/templates/{id}/test-send", func(w http.ResponseWriter, r *http.Request) {
+		id := r.PathValue("id")
+		var req struct {
+			Recipient string `json:"recipient"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil || strings.TrimSpace(req.Recipient) == "" {
+			response.BadRequest(w, "recipient is required")
+			return
+		}
+		if !isTestRecipient(cfg.Notification.TestRecipients, req.Recipient) {
+			response.Forbidden(w, "recipient is not an allowed test address")
+			return
+		}
+		response.Accepted(w, map[string]string{"message": "Test message queued for sending", "template_id": id})
 	})
 
-	mux.HandleFunc("DELETE /api/v1/notifications/templates/{id}", func(w http.ResponseWriter, r *http.Request) {
+	// Template locale variants
+	mux.HandleFunc("GET /api/v1/notifications/templates/{id}/locales", func(w http.ResponseWriter, r *http.Request) {
+		id := r.PathValue("id")
+		response.OK(w, map[string]interface{}{"template_id": id, "default_locale": "en", "localizations": []interface{}{}})
+	})
+
+	mux.HandleFunc("PUT /api/v1/notifications/templates/{id}/locales/{locale}", func(w http.ResponseWriter, r *http.Request) {
+		id := r.PathValue("id")
+		var req struct {
+			Body string `json:"body"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil || strings.TrimSpace(req.Body) == "" {
+			response.BadRequest(w, "body is required")
+			return
+		}
+		response.OK(w, map[string]string{"message": "Set template locale", "template_id": id, "locale": r.PathValue("locale")})
+	})
+
+	mux.HandleFunc("DELETE /api/v1/notifications/templates/{id}/locales/{locale}", func(w http.ResponseWriter, r *http.Request) {
 		response.NoContent(w)
 	})
 
+	// Devices of the calling user that push notifications are sent to;
+	// devices whose tokens the push provider rejects are unregistered
+	deviceUseCase := usecase.NewDeviceUseCase(postgres.NewDeviceRepository(sqlx.NewDb(db.DB, "postgres")), webPushKeys)
+	authenticated := middleware.Auth(jwtManager)
+	mux.Handle("POST /api/v1/devices", authenticated(registerDevice(deviceUseCase, log)))
+	mux.Handle("POST /api/v1/devices/web-push", authenticated(registerWebPushSubscription(deviceUseCase, log)))
+	mux.Handle("GET /api/v1/devices/web-push/key", authenticated(getWebPushKey(deviceUseCase, log)))
+	mux.Handle("GET /api/v1/devices", authenticated(listDevices(deviceUseCase, log)))
+	mux.Handle("DELETE /api/v1/devices/{id}", authenticated(unregisterDevice(deviceUseCase, log)))
+
+	// Slack and Teams channels of the tenant that sales events are posted to
+	integrationManager := func(h http.HandlerFunc) http.Handler {
+		return middleware.Chain(authenticated, middleware.RequirePermission("notifications:integrations"))(h)
+	}
+	mux.Handle("POST /api/v1/notifications/chat-integrations", integrationManager(createChatIntegration(chatIntegrationUseCase, log)))
+	mux.Handle("GET /api/v1/notifications/chat-integrations", integrationManager(listChatIntegrations(chatIntegrationUseCase, log)))
+	mux.Handle("GET /api/v1/notifications/chat-integrations/{id}", integrationManager(getChatIntegration(chatIntegrationUseCase, log)))
+	mux.Handle("PUT /api/v1/notifications/chat-integrations/{id}", integrationManager(updateChatIntegration(chatIntegrationUseCase, log)))
+	mux.Handle("DELETE /api/v1/notifications/chat-integrations/{id}", integrationManager(deleteChatIntegration(chatIntegrationUseCase, log)))
+	mux.Handle("POST /api/v1/notifications/chat-integrations/{id}/test", integrationManager(testChatIntegration(chatIntegrationUseCase, log)))
+
+	// Routing rules of the tenant choosing who is notified of events, on
+	// which channels and with which templates
+	mux.Handle("POST /api/v1/notifications/routing-rules", integrationManager(createRoutingRule(routingRuleUseCase, log)))
+	mux.Handle("GET /api/v1/notifications/routing-rules", integrationManager(listRoutingRules(routingRuleUseCase, log)))
+	mux.Handle("GET /api/v1/notifications/routing-rules/{id}", integrationManager(getRoutingRule(routingRuleUseCase, log)))
+	mux.Handle("PUT /api/v1/notifications/routing-rules/{id}", integrationManager(updateRoutingRule(routingRuleUseCase, log)))
+	mux.Handle("DELETE /api/v1/notifications/routing-rules/{id}", integrationManager(deleteRoutingRule(routingRuleUseCase, log)))
+
+	// Escalation policies paging on-call for critical alerts, and the on-call
+	// rotations they page
+	mux.Handle("POST /api/v1/notifications/escalation-policies", integrationManager(createEscalationPolicy(escalationUseCase, log)))
+	mux.Handle("GET /api/v1/notifications/escalation-policies", integrationManager(listEscalationPolicies(escalationUseCase, log)))
+	mux.Handle("GET /api/v1/notifications/escalation-policies/{id}", integrationManager(getEscalationPolicy(escalationUseCase, log)))
+	mux.Handle("PUT /api/v1/notifications/escalation-policies/{id}", integrationManager(updateEscalationPolicy(escalationUseCase, log)))
+	mux.Handle("DELETE /api/v1/notifications/escalation-policies/{id}", integrationManager(deleteEscalationPolicy(escalationUseCase, log)))
+	mux.Handle("POST /api/v1/notifications/on-call-schedules", integrationManager(createOnCallSchedule(onCallScheduleUseCase, log)))
+	mux.Handle("GET /api/v1/notifications/on-call-schedules", integrationManager(listOnCallSchedules(onCallScheduleUseCase, log)))
+	mux.Handle("GET /api/v1/notifications/on-call-schedules/{id}", integrationManager(getOnCallSchedule(onCallScheduleUseCase, log)))
+	mux.Handle("PUT /api/v1/notifications/on-call-schedules/{id}", integrationManager(updateOnCallSchedule(onCallScheduleUseCase, log)))
+	mux.Handle("DELETE /api/v1/notifications/on-call-schedules/{id}", integrationManager(deleteOnCallSchedule(onCallScheduleUseCase, log)))
+
+	// Alerts being escalated; pages carry the escalation ID they are
+	// acknowledged with. Registered as {id}/{action}: a literal ack segment
+	// would be ambiguous with webhooks/{provider} for the path webhooks/ack
+	mux.Handle("GET /api/v1/notifications/escalations", authenticated(listEscalations(escalationUseCase, log)))
+	mux.Handle("GET /api/v1/notifications/escalations/{id}", authenticated(getEscalation(escalationUseCase, log)))
+	mux.Handle("POST /api/v1/notifications/{id}/{action}", authenticated(acknowledgeEscalation(escalationUseCase, log)))
+
 	// Direct send endpoints (for internal use)
 	mux.HandleFunc("POST /api/v1/notifications/send/email", func(w http.ResponseWriter, r *http.Request) {
 		response.Accepted(w, map[string]string{"message": "Email queued for sending"})
@@ -212,12 +623,31 @@ func main() {
 		response.Accepted(w, map[string]string{"message": "SMS queued for sending"})
 	})
 
+	// Tenant data for the IAM service's tenant exports and demo data, called
+	// with a service token granting the route's scope; the API gateway does
+	// not route /internal paths
+	serviceAuth := serviceauth.NewVerifier(cfg.ServiceAuth, "notification-service")
+	mux.Handle("GET /internal/tenants/{tenantID}/export/{dataset}",
+		serviceAuth.Require(serviceauth.ScopeTenantsExport)(exportTenantData(postgres.NewTenantExporter(sqlx.NewDb(db.DB, "postgres")), log)))
+
+	demoDataUseCase := usecase.NewDemoDataUseCase(
+		postgres.NewNotificationRepository(sqlx.NewDb(db.DB, "postgres")),
+		postgres.NewDemoDataRepository(sqlx.NewDb(db.DB, "postgres")),
+	)
+	mux.Handle("POST /internal/tenants/{tenantID}/demo-data",
+		serviceAuth.Require(serviceauth.ScopeTenantsDemoData)(seedDemoData(demoDataUseCase, log)))
+	mux.Handle("DELETE /internal/tenants/{tenantID}/demo-data",
+		serviceAuth.Require(serviceauth.ScopeTenantsDemoData)(purgeDemoData(demoDataUseCase, log)))
+
 	// Apply middleware
 	handler := middleware.Chain(
 		middleware.RequestID,
-		middleware.Logger(log),
+		middleware.RequestLogger(log, cfg.Logger.Request),
 		middleware.Recover(log),
-		middleware.CORS([]string{"*"}, []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"}, []string{"*"}),
+		loadShedder.Middleware,
+		security.Headers(cfg.Security),
+		security.Sanitize(cfg.Security),
+		middleware.CORSWithPolicy(middleware.NewCORSPolicy(cfg.CORS, cfg.App.Environment, nil)),
 		middleware.ContentType("application/json"),
 	)(mux)
 
@@ -230,7 +660,8 @@ func main() {
 		IdleTimeout:  cfg.Server.IdleTimeout,
 	}
 
-	// Start server in a goroutine
+	// Start server in a goroutine, taking over from the startup health checks
+	startup.Ready()
 	go func() {
 		log.Info().
 			Str("addr", server.Addr).
@@ -240,22 +671,1045 @@ func main() {
 			log.Fatal().Err(err).Msg("HTTP server failed")
 		}
 	}()
+	lc.OnShutdown("http server", server.Shutdown)
 
 	// Wait for interrupt signal
-	quit := make(chan os.Signal, 1)
-	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
-	<-quit
-
-	log.Info().Msg("Shutting down server...")
+	sig := lc.Wait()
+	log.Info().Str("signal", sig.String()).Msg("Shutting down server...")
 
-	// Create shutdown context with timeout
-	ctx, cancel := context.WithTimeout(context.Background(), cfg.Server.ShutdownTimeout)
-	defer cancel()
-
-	// Graceful shutdown
-	if err := server.Shutdown(ctx); err != nil {
+	// Stop taking requests and messages, let the dispatch workers finish the
+	// events already accepted, then close the connections
+	if err := lc.Shutdown(cfg.Server.ShutdownTimeout); err != nil {
 		log.Error().Err(err).Msg("Server forced to shutdown")
 	}
 
 	log.Info().Msg("Server stopped")
+
+	// Ship the log lines still buffered
+	logCtx, logCancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer logCancel()
+	if err := log.Close(logCtx); err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to ship remaining logs: %v\n", err)
+	}
+}
+
+// exportTenantData streams a tenant's records of a data set as one JSON
+// object per line. The write deadline is lifted as an export may outlast it,
+// and a failure after the first record aborts the connection so the caller
+// sees a truncated export rather than a complete one.
+func exportTenantData(exporter domain.TenantDataExporter, log *logger.Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		tenantID, err := uuid.Parse(r.PathValue("tenantID"))
+		if err != nil {
+			response.BadRequest(w, "invalid tenant ID")
+			return
+		}
+		dataset := r.PathValue("dataset")
+		if dataset != domain.TenantExportTemplates && dataset != domain.TenantExportNotifications {
+			response.NotFound(w, "export data set")
+			return
+		}
+
+		_ = http.NewResponseController(w).SetWriteDeadline(time.Time{})
+
+		out := &exportWriter{w: w}
+		if _, err := exporter.ExportTenantData(r.Context(), tenantID, dataset, out); err != nil {
+			log.Error().Err(err).Str("tenant_id", tenantID.String()).Str("dataset", dataset).Msg("Tenant data export failed")
+			if !out.started {
+				response.InternalError(w, "failed to export tenant data")
+				return
+			}
+			panic(http.ErrAbortHandler)
+		}
+	}
+}
+
+// seedDemoData seeds a tenant's demo notifications.
+func seedDemoData(uc usecase.DemoDataUseCase, log *logger.Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		tenantID, err := uuid.Parse(r.PathValue("tenantID"))
+		if err != nil {
+			response.BadRequest(w, "invalid tenant ID")
+			return
+		}
+
+		result, err := uc.Seed(r.Context(), tenantID)
+		if err != nil {
+			log.Error().Err(err).Str("tenant_id", tenantID.String()).Msg("Demo data seeding failed")
+			response.InternalError(w, "failed to seed demo data")
+			return
+		}
+		response.OK(w, result)
+	}
+}
+
+// purgeDemoData deletes a tenant's demo notifications.
+func purgeDemoData(uc usecase.DemoDataUseCase, log *logger.Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		tenantID, err := uuid.Parse(r.PathValue("tenantID"))
+		if err != nil {
+			response.BadRequest(w, "invalid tenant ID")
+			return
+		}
+
+		result, err := uc.Purge(r.Context(), tenantID)
+		if err != nil {
+			log.Error().Err(err).Str("tenant_id", tenantID.String()).Msg("Demo data purge failed")
+			response.InternalError(w, "failed to purge demo data")
+			return
+		}
+		response.OK(w, result)
+	}
+}
+
+// registerDevice registers a device of the calling user for push
+// notifications.
+func registerDevice(uc usecase.DeviceUseCase, log *logger.Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req dto.RegisterDeviceRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			response.BadRequest(w, "invalid request body")
+			return
+		}
+		req.TenantID = middleware.TenantIDFromContext(r.Context())
+		req.UserID = middleware.UserIDFromContext(r.Context())
+
+		device, err := uc.RegisterDevice(r.Context(), &req)
+		if err != nil {
+			writeDeviceError(w, err, log)
+			return
+		}
+		response.Created(w, device)
+	}
+}
+
+// registerWebPushSubscription registers the browser push subscription of
+// the calling user, the PushSubscription the browser returned as JSON.
+func registerWebPushSubscription(uc usecase.DeviceUseCase, log *logger.Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req dto.RegisterWebPushSubscriptionRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			response.BadRequest(w, "invalid request body")
+			return
+		}
+		req.TenantID = middleware.TenantIDFromContext(r.Context())
+		req.UserID = middleware.UserIDFromContext(r.Context())
+
+		device, err := uc.RegisterWebPushSubscription(r.Context(), &req)
+		if err != nil {
+			writeDeviceError(w, err, log)
+			return
+		}
+		response.Created(w, device)
+	}
+}
+
+// getWebPushKey returns the VAPID public key browsers of the calling user's
+// tenant subscribe with.
+func getWebPushKey(uc usecase.DeviceUseCase, log *logger.Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		key, err := uc.GetWebPushKey(r.Context(), middleware.TenantIDFromContext(r.Context()))
+		if err != nil {
+			writeDeviceError(w, err, log)
+			return
+		}
+		response.OK(w, key)
+	}
+}
+
+// listDevices lists the devices of the calling user.
+func listDevices(uc usecase.DeviceUseCase, log *logger.Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		devices, err := uc.ListDevices(r.Context(), &dto.ListDevicesRequest{
+			TenantID: middleware.TenantIDFromContext(r.Context()),
+			UserID:   middleware.UserIDFromContext(r.Context()),
+		})
+		if err != nil {
+			writeDeviceError(w, err, log)
+			return
+		}
+		response.OK(w, devices)
+	}
+}
+
+// unregisterDevice unregisters a device of the calling user, e.g. when they
+// sign out of the app.
+func unregisterDevice(uc usecase.DeviceUseCase, log *logger.Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		err := uc.UnregisterDevice(r.Context(), &dto.UnregisterDeviceRequest{
+			TenantID: middleware.TenantIDFromContext(r.Context()),
+			UserID:   middleware.UserIDFromContext(r.Context()),
+			DeviceID: r.PathValue("id"),
+		})
+		if err != nil {
+			writeDeviceError(w, err, log)
+			return
+		}
+		response.NoContent(w)
+	}
+}
+
+// writeDeviceError writes the response for a failed device request.
+func writeDeviceError(w http.ResponseWriter, err error, log *logger.Logger) {
+	var appErr *application.AppError
+	if !errors.As(err, &appErr) {
+		appErr = application.NewInternalError("device request failed", err)
+	}
+	switch appErr.Code {
+	case application.ErrCodeValidation, application.ErrCodeInvalidInput, application.ErrCodeInvalidDeviceToken:
+		response.BadRequest(w, appErr.Message)
+	case application.ErrCodeNotFound:
+		response.NotFound(w, "device")
+	case application.ErrCodeChannelNotConfigured:
+		response.Error(w, apperrors.ErrServiceUnavailable("Web push"))
+	default:
+		log.Error().Err(err).Msg("Device request failed")
+		response.InternalError(w, "failed to process device request")
+	}
+}
+
+// createChatIntegration connects a Slack or Teams channel of the calling
+// user's tenant.
+func createChatIntegration(uc usecase.ChatIntegrationUseCase, log *logger.Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req dto.CreateChatIntegrationRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			response.BadRequest(w, "invalid request body")
+			return
+		}
+		req.TenantID = middleware.TenantIDFromContext(r.Context())
+		req.CreatedBy = middleware.UserIDFromContext(r.Context())
+
+		integration, err := uc.CreateIntegration(r.Context(), &req)
+		if err != nil {
+			writeChatIntegrationError(w, err, log)
+			return
+		}
+		response.Created(w, integration)
+	}
+}
+
+// listChatIntegrations lists the chat integrations of the calling user's
+// tenant.
+func listChatIntegrations(uc usecase.ChatIntegrationUseCase, log *logger.Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		integrations, err := uc.ListIntegrations(r.Context(), middleware.TenantIDFromContext(r.Context()))
+		if err != nil {
+			writeChatIntegrationError(w, err, log)
+			return
+		}
+		response.OK(w, integrations)
+	}
+}
+
+// getChatIntegration gets a chat integration.
+func getChatIntegration(uc usecase.ChatIntegrationUseCase, log *logger.Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		integration, err := uc.GetIntegration(r.Context(), middleware.TenantIDFromContext(r.Context()), r.PathValue("id"))
+		if err != nil {
+			writeChatIntegrationError(w, err, log)
+			return
+		}
+		response.OK(w, integration)
+	}
+}
+
+// updateChatIntegration updates a chat integration; fields left out of the
+// request are kept.
+func updateChatIntegration(uc usecase.ChatIntegrationUseCase, log *logger.Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req dto.UpdateChatIntegrationRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			response.BadRequest(w, "invalid request body")
+			return
+		}
+		req.TenantID = middleware.TenantIDFromContext(r.Context())
+		req.ID = r.PathValue("id")
+
+		integration, err := uc.UpdateIntegration(r.Context(), &req)
+		if err != nil {
+			writeChatIntegrationError(w, err, log)
+			return
+		}
+		response.OK(w, integration)
+	}
+}
+
+// deleteChatIntegration disconnects a chat integration.
+func deleteChatIntegration(uc usecase.ChatIntegrationUseCase, log *logger.Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if err := uc.DeleteIntegration(r.Context(), middleware.TenantIDFromContext(r.Context()), r.PathValue("id")); err != nil {
+			writeChatIntegrationError(w, err, log)
+			return
+		}
+		response.NoContent(w)
+	}
+}
+
+// testChatIntegration posts a test message to the channel of a chat
+// integration. A failed post is reported in the response body.
+func testChatIntegration(uc usecase.ChatIntegrationUseCase, log *logger.Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		result, err := uc.TestIntegration(r.Context(), middleware.TenantIDFromContext(r.Context()), r.PathValue("id"))
+		if err != nil {
+			writeChatIntegrationError(w, err, log)
+			return
+		}
+		response.OK(w, result)
+	}
+}
+
+// writeChatIntegrationError writes the response for a failed chat
+// integration request.
+func writeChatIntegrationError(w http.ResponseWriter, err error, log *logger.Logger) {
+	var appErr *application.AppError
+	if !errors.As(err, &appErr) {
+		appErr = application.NewInternalError("chat integration request failed", err)
+	}
+	switch appErr.Code {
+	case application.ErrCodeValidation, application.ErrCodeInvalidInput:
+		response.BadRequest(w, appErr.Message)
+	case application.ErrCodeNotFound:
+		response.NotFound(w, "chat integration")
+	case application.ErrCodeChannelNotConfigured:
+		response.Error(w, apperrors.ErrServiceUnavailable("Chat integrations"))
+	default:
+		log.Error().Err(err).Msg("Chat integration request failed")
+		response.InternalError(w, "failed to process chat integration request")
+	}
+}
+
+// postChatEvent returns the job posting an event to the chat integrations of
+// its tenant. Failed posts are logged rather than retried, as redelivering
+// the event would post it again to the channels that did get it.
+func postChatEvent(uc usecase.ChatIntegrationUseCase, event *domain.ChatEvent, log *logger.Logger) worker.DispatchJob {
+	return func(ctx context.Context) error {
+		if err := uc.Post(ctx, event); err != nil {
+			log.Warn().Err(err).
+				Str("tenant_id", event.TenantID.String()).
+				Str("trigger", string(event.Trigger)).
+				Msg("Failed to post event to chat integrations")
+		}
+		return nil
+	}
+}
+
+// createRoutingRule adds a routing rule to the calling user's tenant.
+func createRoutingRule(uc usecase.RoutingRuleUseCase, log *logger.Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req dto.CreateRoutingRuleRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			response.BadRequest(w, "invalid request body")
+			return
+		}
+		req.TenantID = middleware.TenantIDFromContext(r.Context())
+		req.CreatedBy = middleware.UserIDFromContext(r.Context())
+
+		rule, err := uc.CreateRule(r.Context(), &req)
+		if err != nil {
+			writeRoutingRuleError(w, err, log)
+			return
+		}
+		response.Created(w, rule)
+	}
+}
+
+// listRoutingRules lists the routing rules of the calling user's tenant.
+func listRoutingRules(uc usecase.RoutingRuleUseCase, log *logger.Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		rules, err := uc.ListRules(r.Context(), middleware.TenantIDFromContext(r.Context()))
+		if err != nil {
+			writeRoutingRuleError(w, err, log)
+			return
+		}
+		response.OK(w, rules)
+	}
+}
+
+// getRoutingRule gets a routing rule.
+func getRoutingRule(uc usecase.RoutingRuleUseCase, log *logger.Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		rule, err := uc.GetRule(r.Context(), middleware.TenantIDFromContext(r.Context()), r.PathValue("id"))
+		if err != nil {
+			writeRoutingRuleError(w, err, log)
+			return
+		}
+		response.OK(w, rule)
+	}
+}
+
+// updateRoutingRule updates a routing rule; fields left out of the request
+// are kept.
+func updateRoutingRule(uc usecase.RoutingRuleUseCase, log *logger.Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req dto.UpdateRoutingRuleRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			response.BadRequest(w, "invalid request body")
+			return
+		}
+		req.TenantID = middleware.TenantIDFromContext(r.Context())
+		req.ID = r.PathValue("id")
+
+		rule, err := uc.UpdateRule(r.Context(), &req)
+		if err != nil {
+			writeRoutingRuleError(w, err, log)
+			return
+		}
+		response.OK(w, rule)
+	}
+}
+
+// deleteRoutingRule removes a routing rule.
+func deleteRoutingRule(uc usecase.RoutingRuleUseCase, log *logger.Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if err := uc.DeleteRule(r.Context(), middleware.TenantIDFromContext(r.Context()), r.PathValue("id")); err != nil {
+			writeRoutingRuleError(w, err, log)
+			return
+		}
+		response.NoContent(w)
+	}
+}
+
+// writeRoutingRuleError writes the response for a failed routing rule
+// request.
+func writeRoutingRuleError(w http.ResponseWriter, err error, log *logger.Logger) {
+	var appErr *application.AppError
+	if !errors.As(err, &appErr) {
+		appErr = application.NewInternalError("routing rule request failed", err)
+	}
+	switch appErr.Code {
+	case application.ErrCodeValidation, application.ErrCodeInvalidInput:
+		response.BadRequest(w, appErr.Message)
+	case application.ErrCodeNotFound:
+		response.NotFound(w, "routing rule")
+	default:
+		log.Error().Err(err).Msg("Routing rule request failed")
+		response.InternalError(w, "failed to process routing rule request")
+	}
+}
+
+// createEscalationPolicy adds an escalation policy to the calling user's
+// tenant.
+func createEscalationPolicy(uc usecase.EscalationUseCase, log *logger.Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req dto.CreateEscalationPolicyRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			response.BadRequest(w, "invalid request body")
+			return
+		}
+		req.TenantID = middleware.TenantIDFromContext(r.Context())
+		req.CreatedBy = middleware.UserIDFromContext(r.Context())
+
+		policy, err := uc.CreatePolicy(r.Context(), &req)
+		if err != nil {
+			writeEscalationError(w, err, "escalation policy", log)
+			return
+		}
+		response.Created(w, policy)
+	}
+}
+
+// listEscalationPolicies lists the escalation policies of the calling user's
+// tenant.
+func listEscalationPolicies(uc usecase.EscalationUseCase, log *logger.Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		policies, err := uc.ListPolicies(r.Context(), middleware.TenantIDFromContext(r.Context()))
+		if err != nil {
+			writeEscalationError(w, err, "escalation policy", log)
+			return
+		}
+		response.OK(w, policies)
+	}
+}
+
+// getEscalationPolicy gets an escalation policy.
+func getEscalationPolicy(uc usecase.EscalationUseCase, log *logger.Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		policy, err := uc.GetPolicy(r.Context(), middleware.TenantIDFromContext(r.Context()), r.PathValue("id"))
+		if err != nil {
+			writeEscalationError(w, err, "escalation policy", log)
+			return
+		}
+		response.OK(w, policy)
+	}
+}
+
+// updateEscalationPolicy updates an escalation policy; fields left out of
+// the request are kept.
+func updateEscalationPolicy(uc usecase.EscalationUseCase, log *logger.Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req dto.UpdateEscalationPolicyRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			response.BadRequest(w, "invalid request body")
+			return
+		}
+		req.TenantID = middleware.TenantIDFromContext(r.Context())
+		req.ID = r.PathValue("id")
+
+		policy, err := uc.UpdatePolicy(r.Context(), &req)
+		if err != nil {
+			writeEscalationError(w, err, "escalation policy", log)
+			return
+		}
+		response.OK(w, policy)
+	}
+}
+
+// deleteEscalationPolicy removes an escalation policy.
+func deleteEscalationPolicy(uc usecase.EscalationUseCase, log *logger.Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if err := uc.DeletePolicy(r.Context(), middleware.TenantIDFromContext(r.Context()), r.PathValue("id")); err != nil {
+			writeEscalationError(w, err, "escalation policy", log)
+			return
+		}
+		response.NoContent(w)
+	}
+}
+
+// createOnCallSchedule adds an on-call rotation to the calling user's tenant.
+func createOnCallSchedule(uc usecase.OnCallScheduleUseCase, log *logger.Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req dto.CreateOnCallScheduleRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			response.BadRequest(w, "invalid request body")
+			return
+		}
+		req.TenantID = middleware.TenantIDFromContext(r.Context())
+
+		schedule, err := uc.CreateSchedule(r.Context(), &req)
+		if err != nil {
+			writeEscalationError(w, err, "on-call schedule", log)
+			return
+		}
+		response.Created(w, schedule)
+	}
+}
+
+// listOnCallSchedules lists the on-call schedules of the calling user's
+// tenant, with who is on call now.
+func listOnCallSchedules(uc usecase.OnCallScheduleUseCase, log *logger.Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		schedules, err := uc.ListSchedules(r.Context(), middleware.TenantIDFromContext(r.Context()))
+		if err != nil {
+			writeEscalationError(w, err, "on-call schedule", log)
+			return
+		}
+		response.OK(w, schedules)
+	}
+}
+
+// getOnCallSchedule gets an on-call schedule, with who is on call now.
+func getOnCallSchedule(uc usecase.OnCallScheduleUseCase, log *logger.Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		schedule, err := uc.GetSchedule(r.Context(), middleware.TenantIDFromContext(r.Context()), r.PathValue("id"))
+		if err != nil {
+			writeEscalationError(w, err, "on-call schedule", log)
+			return
+		}
+		response.OK(w, schedule)
+	}
+}
+
+// updateOnCallSchedule updates an on-call schedule; fields left out of the
+// request are kept.
+func updateOnCallSchedule(uc usecase.OnCallScheduleUseCase, log *logger.Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req dto.UpdateOnCallScheduleRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			response.BadRequest(w, "invalid request body")
+			return
+		}
+		req.TenantID = middleware.TenantIDFromContext(r.Context())
+		req.ID = r.PathValue("id")
+
+		schedule, err := uc.UpdateSchedule(r.Context(), &req)
+		if err != nil {
+			writeEscalationError(w, err, "on-call schedule", log)
+			return
+		}
+		response.OK(w, schedule)
+	}
+}
+
+// deleteOnCallSchedule removes an on-call schedule.
+func deleteOnCallSchedule(uc usecase.OnCallScheduleUseCase, log *logger.Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if err := uc.DeleteSchedule(r.Context(), middleware.TenantIDFromContext(r.Context()), r.PathValue("id")); err != nil {
+			writeEscalationError(w, err, "on-call schedule", log)
+			return
+		}
+		response.NoContent(w)
+	}
+}
+
+// listEscalations lists the escalations of the calling user's tenant, newest
+// first, optionally by status.
+func listEscalations(uc usecase.EscalationUseCase, log *logger.Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		page, _ := strconv.Atoi(q.Get("page"))
+		pageSize, _ := strconv.Atoi(q.Get("page_size"))
+
+		list, err := uc.ListEscalations(r.Context(), &dto.ListEscalationsRequest{
+			TenantID: middleware.TenantIDFromContext(r.Context()),
+			Status:   q.Get("status"),
+			Page:     page,
+			PageSize: pageSize,
+		})
+		if err != nil {
+			writeEscalationError(w, err, "escalation", log)
+			return
+		}
+		response.Paginated(w, list.Items, list.Page, list.PageSize, list.TotalCount)
+	}
+}
+
+// getEscalation gets an escalation.
+func getEscalation(uc usecase.EscalationUseCase, log *logger.Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		escalation, err := uc.GetEscalation(r.Context(), middleware.TenantIDFromContext(r.Context()), r.PathValue("id"))
+		if err != nil {
+			writeEscalationError(w, err, "escalation", log)
+			return
+		}
+		response.OK(w, escalation)
+	}
+}
+
+// acknowledgeEscalation acknowledges the alert a page was sent for, stopping
+// its escalation. It serves the ack action only.
+func acknowledgeEscalation(uc usecase.EscalationUseCase, log *logger.Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.PathValue("action") != "ack" {
+			response.NotFound(w, "resource")
+			return
+		}
+		escalation, err := uc.Acknowledge(r.Context(), middleware.TenantIDFromContext(r.Context()), r.PathValue("id"),
+			middleware.UserIDFromContext(r.Context()))
+		if err != nil {
+			writeEscalationError(w, err, "escalation", log)
+			return
+		}
+		response.OK(w, escalation)
+	}
+}
+
+// writeEscalationError writes the response for a failed escalation policy,
+// on-call schedule or escalation request.
+func writeEscalationError(w http.ResponseWriter, err error, resource string, log *logger.Logger) {
+	var appErr *application.AppError
+	if !errors.As(err, &appErr) {
+		appErr = application.NewInternalError(resource+" request failed", err)
+	}
+	switch appErr.Code {
+	case application.ErrCodeValidation, application.ErrCodeInvalidInput:
+		response.BadRequest(w, appErr.Message)
+	case application.ErrCodeNotFound:
+		response.NotFound(w, resource)
+	case application.ErrCodeConflict:
+		response.Conflict(w, appErr.Message)
+	default:
+		log.Error().Err(err).Str("resource", resource).Msg("Escalation request failed")
+		response.InternalError(w, "failed to process "+resource+" request")
+	}
+}
+
+// pageEscalation returns the pager submitting the pages of an escalation
+// level to the workers of their channels, one per audience and channel.
+func pageEscalation(pool *worker.DispatchPool, log *logger.Logger) usecase.EscalationPagerFunc {
+	return func(ctx context.Context, escalation *domain.Escalation, audiences []domain.RoutingAudience, channels []domain.NotificationChannel) error {
+		for _, channel := range channels {
+			for _, audience := range audiences {
+				audience := audience
+				err := pool.Submit(ctx, channel, func(ctx context.Context) error {
+					log.Info().
+						Str("escalation_id", escalation.ID.String()).
+						Str("event_id", escalation.EventID).
+						Str("event_type", escalation.EventType).
+						Int("level", escalation.Level+1).
+						Int("cycle", escalation.Cycle).
+						Str("audience", string(audience.Type)).
+						Str("audience_value", audience.Value).
+						Str("channel", channel.String()).
+						Msg("Paging on-call")
+					return nil
+				})
+				if err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	}
+}
+
+// routeEvent submits the notifications the routing rules of its tenant send
+// for an event to the workers of their channels.
+func routeEvent(ctx context.Context, uc usecase.RoutingRuleUseCase, pool *worker.DispatchPool, event *events.Event, log *logger.Logger) error {
+	routed, err := uc.Route(ctx, routedEvent(event))
+	if err != nil {
+		return err
+	}
+
+	for _, notification := range routed {
+		notification := notification
+		err := pool.Submit(ctx, notification.Channel, func(ctx context.Context) error {
+			log.Info().
+				Str("event_id", event.ID).
+				Str("event_type", string(event.Type)).
+				Str("aggregate_id", event.AggregateID).
+				Str("rule", notification.RuleName).
+				Str("audience", string(notification.Audience.Type)).
+				Str("audience_value", notification.Audience.Value).
+				Str("channel", notification.Channel.String()).
+				Str("template", notification.TemplateCode).
+				Msg("Sending routed notification")
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// routedEvent converts a subscribed event to the event routing rules and
+// escalation policies are evaluated against.
+func routedEvent(event *events.Event) *domain.RoutedEvent {
+	tenantID, _ := uuid.Parse(event.TenantID)
+	return &domain.RoutedEvent{
+		ID:          event.ID,
+		TenantID:    tenantID,
+		Type:        string(event.Type),
+		AggregateID: event.AggregateID,
+		Data:        event.Data,
+	}
+}
+
+// salesChatEvent builds the chat event of a won opportunity, a new lead, an
+// overdue invoice or a sales insight, or returns nil for events that are not
+// posted. Sales amounts are in minor currency units.
+func salesChatEvent(event *events.Event) *domain.ChatEvent {
+	tenantID, err := uuid.Parse(event.TenantID)
+	if err != nil {
+		return nil
+	}
+	data := event.Data
+	currency := eventString(data, "currency")
+
+	var trigger domain.ChatTrigger
+	var amount float64
+	vars := map[string]interface{}{"currency": currency}
+	var fields []domain.ChatField
+	switch event.Type {
+	case events.EventTypeOpportunityWon:
+		trigger = domain.ChatTriggerOpportunityWon
+		amount = eventFloat(data, "amount") / 100
+		vars["opportunity_code"] = eventString(data, "opportunity_code")
+		vars["opportunity_name"] = eventString(data, "opportunity_name")
+		vars["won_reason"] = eventString(data, "won_reason")
+		fields = append(fields, domain.ChatField{Name: "Amount", Value: formatChatAmount(currency, amount)})
+		if reason := eventString(data, "won_reason"); reason != "" {
+			fields = append(fields, domain.ChatField{Name: "Reason", Value: reason})
+		}
+	case events.EventTypeLeadCreated:
+		trigger = domain.ChatTriggerBigLead
+		amount = eventFloat(data, "estimated_value") / 100
+		vars["lead_code"] = eventString(data, "lead_code")
+		vars["company_name"] = eventString(data, "company_name")
+		vars["contact_name"] = eventString(data, "contact_name")
+		vars["source"] = eventString(data, "source")
+		vars["estimated_value"] = amount
+		fields = append(fields,
+			domain.ChatField{Name: "Estimated value", Value: formatChatAmount(currency, amount)},
+			domain.ChatField{Name: "Source", Value: eventString(data, "source")},
+		)
+	case events.EventTypeDealInvoiceOverdue:
+		trigger = domain.ChatTriggerInvoiceOverdue
+		amount = eventFloat(data, "outstanding_amount") / 100
+		vars["deal_code"] = eventString(data, "deal_code")
+		vars["invoice_number"] = eventString(data, "invoice_number")
+		vars["customer_name"] = eventString(data, "customer_name")
+		vars["due_date"] = eventString(data, "due_date")
+		fields = append(fields,
+			domain.ChatField{Name: "Outstanding", Value: formatChatAmount(currency, amount)},
+			domain.ChatField{Name: "Deal", Value: eventString(data, "deal_code")},
+		)
+	case events.EventTypeInsightBiggestDealWon:
+		trigger = domain.ChatTriggerBiggestDealWon
+		amount = eventFloat(data, "amount") / 100
+		previousBest := eventFloat(data, "previous_best") / 100
+		vars["opportunity_code"] = eventString(data, "opportunity_code")
+		vars["opportunity_name"] = eventString(data, "opportunity_name")
+		vars["owner_name"] = eventString(data, "owner_name")
+		vars["previous_best"] = previousBest
+		fields = append(fields, domain.ChatField{Name: "Amount", Value: formatChatAmount(currency, amount)})
+		if previousBest > 0 {
+			fields = append(fields, domain.ChatField{Name: "Previous best", Value: formatChatAmount(currency, previousBest)})
+		}
+	case events.EventTypeInsightLowPipelineCoverage:
+		trigger = domain.ChatTriggerLowPipelineCoverage
+		amount = eventFloat(data, "open_pipeline") / 100
+		remaining := eventFloat(data, "remaining_quota") / 100
+		coverage := eventFloat(data, "coverage")
+		vars["open_pipeline"] = amount
+		vars["remaining_quota"] = remaining
+		vars["quota"] = eventFloat(data, "quota") / 100
+		vars["coverage"] = coverage
+		vars["minimum_coverage"] = eventFloat(data, "minimum_coverage")
+		fields = append(fields,
+			domain.ChatField{Name: "Open pipeline", Value: formatChatAmount(currency, amount)},
+			domain.ChatField{Name: "Quota left", Value: formatChatAmount(currency, remaining)},
+			domain.ChatField{Name: "Coverage", Value: fmt.Sprintf("%.1fx", coverage)},
+		)
+	case events.EventTypeInsightLeadVolumeDrop:
+		trigger = domain.ChatTriggerLeadVolumeDrop
+		drop := -eventFloat(data, "change_percent")
+		vars["leads"] = eventFloat(data, "leads")
+		vars["previous_leads"] = eventFloat(data, "previous_leads")
+		vars["drop_percent"] = drop
+		vars["period_start"] = eventString(data, "period_start")
+		fields = append(fields,
+			domain.ChatField{Name: "Leads", Value: fmt.Sprintf("%.0f", eventFloat(data, "leads"))},
+			domain.ChatField{Name: "Previous week", Value: fmt.Sprintf("%.0f", eventFloat(data, "previous_leads"))},
+		)
+	default:
+		return nil
+	}
+	vars["amount"] = amount
+
+	return &domain.ChatEvent{
+		TenantID: tenantID,
+		Trigger:  trigger,
+		Amount:   amount,
+		Currency: currency,
+		Vars:     vars,
+		Fields:   fields,
+	}
+}
+
+// formatChatAmount formats an amount in major units the way chat templates
+// do.
+func formatChatAmount(currency string, amount float64) string {
+	formatted, err := render.Text("{{currencyIn .currency .amount}}", map[string]interface{}{"currency": currency, "amount": amount})
+	if err != nil {
+		return fmt.Sprintf("%s %.2f", currency, amount)
+	}
+	return formatted
+}
+
+// eventString returns a string field of event data.
+func eventString(data map[string]interface{}, key string) string {
+	value, _ := data[key].(string)
+	return value
+}
+
+// eventFloat returns a numeric field of event data, which JSON decodes as
+// float64.
+func eventFloat(data map[string]interface{}, key string) float64 {
+	value, _ := data[key].(float64)
+	return value
+}
+
+// exportWriter sends the response headers on the first write, so an export
+// that fails before writing anything still gets an error response.
+type exportWriter struct {
+	w       http.ResponseWriter
+	started bool
+}
+
+// Write writes p to the response.
+func (e *exportWriter) Write(p []byte) (int, error) {
+	if !e.started {
+		e.started = true
+		e.w.Header().Set("Content-Type", "application/x-ndjson")
+		e.w.WriteHeader(http.StatusOK)
+	}
+	return e.w.Write(p)
+}
+
+// isTestRecipient reports whether recipient is one of the configured test
+// addresses.
+func isTestRecipient(allowed []string, recipient string) bool {
+	recipient = strings.TrimSpace(recipient)
+	for _, candidate := range allowed {
+		if strings.EqualFold(strings.TrimSpace(candidate), recipient) {
+			return true
+		}
+	}
+	return false
+}
+
+// dispatchPolicy builds the delivery dispatch policy from configuration,
+// keeping the default weight for priorities the configuration leaves out.
+func dispatchPolicy(cfg config.NotificationConfig) domain.DispatchPolicy {
+	policy := domain.DefaultDispatchPolicy()
+	for name, weight := range cfg.DeliveryWeights {
+		if priority, err := domain.ParsePriority(name); err == nil && weight > 0 {
+			policy.Weights[priority] = weight
+		}
+	}
+	if cfg.DeliveryMaxWait > 0 {
+		policy.MaxWait = cfg.DeliveryMaxWait
+	}
+	return policy
+}
+
+// dispatchPoolConfig builds the dispatch pool configuration, ignoring
+// unknown channels.
+func dispatchPoolConfig(cfg config.NotificationConfig) worker.DispatchPoolConfig {
+	poolConfig := worker.DefaultDispatchPoolConfig()
+	for name, workers := range cfg.DispatchWorkers {
+		if channel, err := domain.ParseChannel(name); err == nil && workers > 0 {
+			poolConfig.Workers[channel] = workers
+		}
+	}
+	if cfg.DispatchQueueSize > 0 {
+		poolConfig.QueueSize = cfg.DispatchQueueSize
+	}
+	if cfg.DispatchSubmitTimeout > 0 {
+		poolConfig.SubmitTimeout = cfg.DispatchSubmitTimeout
+	}
+	return poolConfig
+}
+
+// retryPolicies builds the delivery retry policies from configuration. Each
+// channel override starts from the default policy and replaces the settings
+// it sets.
+func retryPolicies(cfg config.NotificationConfig) (domain.RetryPolicies, error) {
+	policies := domain.RetryPolicies{
+		Default:  retryPolicy(domain.DefaultRetryPolicy(), cfg.Retry),
+		Channels: make(map[domain.NotificationChannel]domain.RetryPolicy, len(cfg.RetryChannels)),
+	}
+	for name, override := range cfg.RetryChannels {
+		channel, err := domain.ParseChannel(name)
+		if err != nil {
+			return domain.RetryPolicies{}, fmt.Errorf("retry policy for %q: %w", name, err)
+		}
+		policies.Channels[channel] = retryPolicy(policies.Default, override)
+	}
+	return policies, policies.Validate()
+}
+
+// archiveRetention builds the sent email archive retention from
+// configuration.
+func archiveRetention(cfg config.NotificationConfig) (domain.ArchiveRetention, error) {
+	retention := domain.DefaultArchiveRetention()
+	if cfg.ArchiveRetentionDays > 0 {
+		retention.DefaultDays = cfg.ArchiveRetentionDays
+	}
+	retention.TenantDays = make(map[uuid.UUID]int, len(cfg.ArchiveTenantRetentionDays))
+	for tenant, days := range cfg.ArchiveTenantRetentionDays {
+		tenantID, err := uuid.Parse(tenant)
+		if err != nil {
+			return domain.ArchiveRetention{}, fmt.Errorf("archive retention for %q: %w", tenant, err)
+		}
+		retention.TenantDays[tenantID] = days
+	}
+	return retention, retention.Validate()
+}
+
+// bodyRetention builds the notification body retention from configuration.
+func bodyRetention(cfg config.NotificationConfig) (domain.BodyRetention, error) {
+	retention := domain.BodyRetention{
+		DefaultDays: cfg.BodyRetentionDays,
+		TenantDays:  make(map[uuid.UUID]int, len(cfg.BodyTenantRetentionDays)),
+	}
+	for tenant, days := range cfg.BodyTenantRetentionDays {
+		tenantID, err := uuid.Parse(tenant)
+		if err != nil {
+			return domain.BodyRetention{}, fmt.Errorf("body retention for %q: %w", tenant, err)
+		}
+		retention.TenantDays[tenantID] = days
+	}
+	return retention, retention.Validate()
+}
+
+// vapidKeys builds the web push VAPID keys from configuration.
+func vapidKeys(cfg config.WebPushConfig) (*push.VAPIDKeys, error) {
+	tenantKeys := make(map[string]string, len(cfg.TenantKeys))
+	for _, key := range cfg.TenantKeys {
+		if _, err := uuid.Parse(key.TenantID); err != nil {
+			return nil, fmt.Errorf("web push key for %q: %w", key.TenantID, err)
+		}
+		tenantKeys[key.TenantID] = key.PrivateKey
+	}
+	return push.NewVAPIDKeys(cfg.PrivateKey, tenantKeys)
+}
+
+// retryPolicy applies the settings of a configured retry policy to base.
+func retryPolicy(base domain.RetryPolicy, cfg config.RetryPolicyConfig) domain.RetryPolicy {
+	policy := base
+	if cfg.MaxAttempts > 0 {
+		policy.MaxAttempts = cfg.MaxAttempts
+	}
+	if cfg.InitialInterval > 0 {
+		policy.InitialInterval = int(cfg.InitialInterval.Seconds())
+	}
+	if cfg.MaxInterval > 0 {
+		policy.MaxInterval = int(cfg.MaxInterval.Seconds())
+	}
+	if cfg.Multiplier > 0 {
+		policy.Multiplier = cfg.Multiplier
+	}
+	if cfg.Jitter > 0 {
+		policy.Jitter = cfg.Jitter
+	}
+	if cfg.RetryableErrors != nil {
+		policy.RetryableErrors = cfg.RetryableErrors
+	}
+	if cfg.NonRetryableErrors != nil {
+		policy.NonRetryableErrors = cfg.NonRetryableErrors
+	}
+	if len(cfg.ProviderErrors) > 0 {
+		policy.ProviderErrors = make(map[string]domain.RetryErrorRules, len(cfg.ProviderErrors))
+		for provider, rules := range cfg.ProviderErrors {
+			policy.ProviderErrors[strings.ToLower(provider)] = domain.RetryErrorRules{
+				Retryable:    rules.Retryable,
+				NonRetryable: rules.NonRetryable,
+			}
+		}
+	}
+	return policy
+}
+
+// writeDispatchMetrics writes the dispatch pool state in the Prometheus text
+// format.
+func writeDispatchMetrics(w io.Writer, stats []worker.DispatchLaneStats) {
+	metrics := []struct {
+		name  string
+		kind  string
+		help  string
+		value func(worker.DispatchLaneStats) float64
+	}{
+		{"notification_dispatch_queue_depth", "gauge", "Events waiting for a dispatch worker.", func(s worker.DispatchLaneStats) float64 { return float64(s.QueueDepth) }},
+		{"notification_dispatch_queue_capacity", "gauge", "Maximum events waiting for a dispatch worker.", func(s worker.DispatchLaneStats) float64 { return float64(s.QueueCapacity) }},
+		{"notification_dispatch_workers", "gauge", "Dispatch workers.", func(s worker.DispatchLaneStats) float64 { return float64(s.Workers) }},
+		{"notification_dispatch_active", "gauge", "Events being dispatched.", func(s worker.DispatchLaneStats) float64 { return float64(s.Active) }},
+		{"notification_dispatch_processed_total", "counter", "Events dispatched.", func(s worker.DispatchLaneStats) float64 { return float64(s.Processed) }},
+		{"notification_dispatch_failed_total", "counter", "Events whose dispatch failed.", func(s worker.DispatchLaneStats) float64 { return float64(s.Failed) }},
+		{"notification_dispatch_rejected_total", "counter", "Events rejected because the queue was full.", func(s worker.DispatchLaneStats) float64 { return float64(s.Rejected) }},
+		{"notification_dispatch_retried_total", "counter", "Failed events scheduled for another attempt.", func(s worker.DispatchLaneStats) float64 { return float64(s.Retried) }},
+		{"notification_dispatch_exhausted_total", "counter", "Events that failed after their last attempt.", func(s worker.DispatchLaneStats) float64 { return float64(s.Exhausted) }},
+		{"notification_dispatch_latency_seconds_avg", "gauge", "Average dispatch time.", func(s worker.DispatchLaneStats) float64 { return s.AvgLatency.Seconds() }},
+	}
+
+	for _, m := range metrics {
+		fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s %s\n", m.name, m.help, m.name, m.kind)
+		for _, s := range stats {
+			fmt.Fprintf(w, "%s{channel=%q} %g\n", m.name, s.Channel, m.value(s))
+		}
+	}
+}
+
+// parseStatsTime parses a stats period bound given as an RFC 3339 time or a
+// YYYY-MM-DD date. An empty value is the zero time, for the default period.
+func parseStatsTime(value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	return time.Parse("2006-01-02", value)
 }
@@ -0,0 +1,225 @@
+// Sales Event Replay - Event Store Replay Tool
+// ============================================
+// Replays events recorded in the sales event store, either to re-drive
+// RabbitMQ consumers or to rebuild the report read models for the days the
+// events touched.
+//
+// Usage:
+//
+//	sales-event-replay -mode republish -from 2026-03-01 [-queue sales.cache-invalidation]
+//	sales-event-replay -mode rebuild-reports -from 2026-03-01 -to 2026-03-07
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"sort"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+
+	"github.com/kilang-desa-murni/crm/internal/sales/application/dto"
+	"github.com/kilang-desa-murni/crm/internal/sales/application/usecase"
+	"github.com/kilang-desa-murni/crm/internal/sales/domain"
+	"github.com/kilang-desa-murni/crm/internal/sales/infrastructure/messaging"
+	"github.com/kilang-desa-murni/crm/internal/sales/infrastructure/persistence/postgres"
+	"github.com/kilang-desa-murni/crm/pkg/config"
+	"github.com/kilang-desa-murni/crm/pkg/database"
+	"github.com/kilang-desa-murni/crm/pkg/logger"
+)
+
+// Version information (set during build)
+var (
+	Version   = "dev"
+	BuildTime = "unknown"
+	GitCommit = "unknown"
+)
+
+// Replay modes
+const (
+	modeRepublish      = "republish"
+	modeRebuildReports = "rebuild-reports"
+)
+
+// reportAggregateTypes are the aggregates the report read models are built from.
+var reportAggregateTypes = map[string]bool{
+	"lead":        true,
+	"opportunity": true,
+	"deal":        true,
+}
+
+func main() {
+	var (
+		mode          = flag.String("mode", modeRepublish, "replay mode: republish or rebuild-reports")
+		tenantID      = flag.String("tenant", "", "replay a single tenant (default: all tenants)")
+		aggregateID   = flag.String("aggregate", "", "replay a single aggregate")
+		aggregateType = flag.String("aggregate-type", "", "replay one aggregate type, e.g. opportunity")
+		eventTypes    = flag.String("types", "", "comma-separated event types, e.g. opportunity.won,deal.created")
+		from          = flag.String("from", "", "replay events that occurred at or after this date or RFC 3339 time")
+		to            = flag.String("to", "", "replay events that occurred before the end of this date or this RFC 3339 time")
+		after         = flag.Int64("after", 0, "resume after this store position")
+		queue         = flag.String("queue", "", "republish straight to this consumer queue instead of the sales exchange")
+		batchSize     = flag.Int("batch", 500, "events read from the store per batch")
+		dryRun        = flag.Bool("dry-run", false, "list the events that would be replayed without replaying them")
+	)
+	flag.Parse()
+
+	if *mode != modeRepublish && *mode != modeRebuildReports {
+		fmt.Fprintf(os.Stderr, "Unknown mode %q: use %s or %s\n", *mode, modeRepublish, modeRebuildReports)
+		os.Exit(2)
+	}
+
+	// Load configuration
+	cfg, err := config.Load("")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to load configuration: %v\n", err)
+		os.Exit(1)
+	}
+	cfg.App.Name = "sales-event-replay"
+
+	// Initialize logger
+	log := logger.New(logger.Config{
+		Level:  cfg.Logger.Level,
+		Format: cfg.Logger.Format,
+		Caller: cfg.Logger.Caller,
+	})
+	log = log.With().Service(cfg.App.Name).Logger()
+
+	log.Info().
+		Str("version", Version).
+		Str("build_time", BuildTime).
+		Str("git_commit", GitCommit).
+		Str("mode", *mode).
+		Bool("dry_run", *dryRun).
+		Msg("Starting event replay")
+
+	// Stop cleanly on interrupt; the last position is logged for resuming
+	ctx, cancel := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer cancel()
+
+	// Initialize PostgreSQL
+	db, err := database.NewPostgres(&cfg.Database, log)
+	if err != nil {
+		log.Fatal().Err(err).Msg("Failed to connect to PostgreSQL")
+	}
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db.DB, "postgres")
+	eventStoreUseCase := usecase.NewEventStoreUseCase(postgres.NewEventStore(sqlxDB))
+
+	req := &dto.ReplayEventsRequest{
+		TenantID:      *tenantID,
+		AggregateID:   *aggregateID,
+		AggregateType: *aggregateType,
+		From:          *from,
+		To:            *to,
+		AfterPosition: *after,
+		BatchSize:     *batchSize,
+	}
+	if *eventTypes != "" {
+		req.EventTypes = strings.Split(*eventTypes, ",")
+	}
+
+	var handle usecase.EventReplayHandler
+	days := make(map[time.Time]bool)
+
+	switch {
+	case *dryRun:
+		handle = func(ctx context.Context, record *domain.EventRecord) error {
+			fmt.Printf("%d\t%s\t%s\t%s#%d\t%s\n",
+				record.Position, record.OccurredAt.Format(time.RFC3339), record.EventType,
+				record.AggregateID, record.Sequence, record.TenantID)
+			return nil
+		}
+
+	case *mode == modeRepublish:
+		republisher, err := messaging.NewEventRepublisher(cfg.RabbitMQ.URL)
+		if err != nil {
+			log.Fatal().Err(err).Msg("Failed to connect event republisher")
+		}
+		defer republisher.Close()
+
+		if *queue != "" {
+			if err := republisher.CheckQueue(*queue); err != nil {
+				log.Fatal().Err(err).Msg("Failed to find consumer queue")
+			}
+		}
+
+		handle = func(ctx context.Context, record *domain.EventRecord) error {
+			return republisher.Republish(ctx, record, *queue)
+		}
+
+	case *mode == modeRebuildReports:
+		// Collect the days first so each day is rebuilt once
+		handle = func(ctx context.Context, record *domain.EventRecord) error {
+			if reportAggregateTypes[record.AggregateType] {
+				days[domain.TruncateToDay(record.OccurredAt)] = true
+			}
+			return nil
+		}
+	}
+
+	resp, err := eventStoreUseCase.Replay(ctx, req, handle)
+	if err != nil {
+		event := log.Error().Err(err)
+		if resp != nil {
+			event = event.Int("events", resp.Events).Int64("last_position", resp.LastPosition)
+		}
+		event.Msg("Event replay failed, resume with -after set to last_position")
+		os.Exit(1)
+	}
+
+	log.Info().
+		Int("events", resp.Events).
+		Int64("last_position", resp.LastPosition).
+		Dur("duration", resp.CompletedAt.Sub(resp.StartedAt)).
+		Msg("Event replay completed")
+
+	if *mode != modeRebuildReports || *dryRun {
+		return
+	}
+
+	if err := rebuildReports(ctx, sqlxDB, days, log); err != nil {
+		log.Fatal().Err(err).Msg("Failed to rebuild report aggregates")
+	}
+}
+
+// rebuildReports recomputes the report aggregates of every day in days, oldest first.
+func rebuildReports(ctx context.Context, db *sqlx.DB, days map[time.Time]bool, log *logger.Logger) error {
+	reportUseCase := usecase.NewReportUseCase(
+		postgres.NewReportRepository(db),
+		nil, // exportRepo
+		nil, // brandingRepo
+		nil, // renderers
+		nil, // fileStorage
+		nil, // notificationService
+		nil, // cacheService, cached reports expire by TTL
+		nil, // tenantBranding
+	)
+
+	sorted := make([]time.Time, 0, len(days))
+	for day := range days {
+		sorted = append(sorted, day)
+	}
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Before(sorted[j]) })
+
+	var rows int64
+	for _, day := range sorted {
+		run, err := reportUseCase.RunDailyAggregation(ctx, day)
+		if err != nil {
+			return fmt.Errorf("failed to rebuild %s: %w", day.Format("2006-01-02"), err)
+		}
+		rows += run.RowsWritten
+	}
+
+	log.Info().
+		Int("days", len(sorted)).
+		Int64("rows_written", rows).
+		Msg("Report aggregates rebuilt")
+	return nil
+}
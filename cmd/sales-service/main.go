@@ -8,22 +8,46 @@ import (
 	"fmt"
 	"net/http"
 	"os"
-	"os/signal"
-	"syscall"
+	"path/filepath"
+	"strings"
 	"time"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
 	"github.com/jmoiron/sqlx"
 
+	"github.com/kilang-desa-murni/crm/internal/sales/application/ports"
 	"github.com/kilang-desa-murni/crm/internal/sales/application/usecase"
+	"github.com/kilang-desa-murni/crm/internal/sales/domain"
+	"github.com/kilang-desa-murni/crm/internal/sales/infrastructure/accounting"
+	"github.com/kilang-desa-murni/crm/internal/sales/infrastructure/antivirus"
+	salescache "github.com/kilang-desa-murni/crm/internal/sales/infrastructure/cache"
+	"github.com/kilang-desa-murni/crm/internal/sales/infrastructure/carrier"
+	salescustomer "github.com/kilang-desa-murni/crm/internal/sales/infrastructure/customer"
+	"github.com/kilang-desa-murni/crm/internal/sales/infrastructure/ecommerce"
+	salesemail "github.com/kilang-desa-murni/crm/internal/sales/infrastructure/email"
+	"github.com/kilang-desa-murni/crm/internal/sales/infrastructure/exchangerate"
+	"github.com/kilang-desa-murni/crm/internal/sales/infrastructure/export"
+	salesiam "github.com/kilang-desa-murni/crm/internal/sales/infrastructure/iam"
+	"github.com/kilang-desa-murni/crm/internal/sales/infrastructure/imaging"
 	"github.com/kilang-desa-murni/crm/internal/sales/infrastructure/messaging"
+	"github.com/kilang-desa-murni/crm/internal/sales/infrastructure/myinvois"
 	"github.com/kilang-desa-murni/crm/internal/sales/infrastructure/persistence/postgres"
+	"github.com/kilang-desa-murni/crm/internal/sales/infrastructure/projection"
+	"github.com/kilang-desa-murni/crm/internal/sales/infrastructure/storage"
+	"github.com/kilang-desa-murni/crm/internal/sales/infrastructure/webhook"
+	"github.com/kilang-desa-murni/crm/internal/sales/infrastructure/worker"
 	saleshttp "github.com/kilang-desa-murni/crm/internal/sales/interfaces/http"
 	"github.com/kilang-desa-murni/crm/pkg/config"
 	"github.com/kilang-desa-murni/crm/pkg/database"
+	"github.com/kilang-desa-murni/crm/pkg/events"
+	"github.com/kilang-desa-murni/crm/pkg/featureflag"
+	"github.com/kilang-desa-murni/crm/pkg/lifecycle"
 	"github.com/kilang-desa-murni/crm/pkg/logger"
+	crmmiddleware "github.com/kilang-desa-murni/crm/pkg/middleware"
 	"github.com/kilang-desa-murni/crm/pkg/response"
+	"github.com/kilang-desa-murni/crm/pkg/security"
+	"github.com/kilang-desa-murni/crm/pkg/serviceauth"
 	"github.com/kilang-desa-murni/crm/pkg/tracer"
 )
 
@@ -48,9 +72,10 @@ func main() {
 
 	// Initialize logger
 	log := logger.New(logger.Config{
-		Level:  cfg.Logger.Level,
-		Format: cfg.Logger.Format,
-		Caller: cfg.Logger.Caller,
+		Level:    cfg.Logger.Level,
+		Format:   cfg.Logger.Format,
+		Caller:   cfg.Logger.Caller,
+		Shipping: cfg.Logger.Shipping,
 	})
 	log = log.With().Service(cfg.App.Name).Logger()
 	logger.SetGlobal(log)
@@ -61,53 +86,119 @@ func main() {
 		Str("git_commit", GitCommit).
 		Msg("Starting Sales service")
 
+	// Components are stopped in the reverse order they are registered in
+	lc := lifecycle.New(log)
+
+	// Wait for the dependencies to come up rather than crash-looping
+	startup := lifecycle.NewStartup(cfg.Startup, log)
+	startup.ServeHealth(fmt.Sprintf("%s:%d", cfg.Server.Host, cfg.Server.Port), Version)
+
 	// Initialize tracer
 	tr, err := tracer.New(&cfg.Tracer, log)
 	if err != nil {
 		log.Fatal().Err(err).Msg("Failed to initialize tracer")
 	}
-	defer tr.Close(context.Background())
+	lc.OnShutdown("tracer", tr.Close)
 
 	// Initialize PostgreSQL
-	db, err := database.NewPostgres(&cfg.Database, log)
+	db, err := lifecycle.Await(startup, "postgresql", func() (*database.PostgresDB, error) {
+		return database.NewPostgres(&cfg.Database, log)
+	})
 	if err != nil {
 		log.Fatal().Err(err).Msg("Failed to connect to PostgreSQL")
 	}
-	defer db.Close()
+	lc.OnClose("postgres", db)
 
 	// Wrap sql.DB with sqlx for repositories
 	sqlxDB := sqlx.NewDb(db.DB, "postgres")
 
-	// Initialize Redis
-	redisClient, err := database.NewRedis(&cfg.Redis, log)
-	if err != nil {
-		log.Fatal().Err(err).Msg("Failed to connect to Redis")
-	}
-	defer redisClient.Close()
-
-	// Initialize RabbitMQ Publisher
-	rabbitConfig := messaging.RabbitMQConfig{
-		URL:               cfg.RabbitMQ.URL,
-		Exchange:          messaging.SalesEventsExchange,
-		ExchangeType:      cfg.RabbitMQ.ExchangeType,
-		Durable:           true,
-		AutoDelete:        false,
-		DeliveryMode:      2, // Persistent
-		ContentType:       "application/json",
-		ReconnectDelay:    cfg.RabbitMQ.ReconnectDelay,
-		MaxReconnectTries: 10,
-		PrefetchCount:     cfg.RabbitMQ.PrefetchCount,
-	}
+	// In dev mode events are delivered in process and the cache is kept in
+	// memory, so the service runs with PostgreSQL alone
+	var (
+		redisClient    *database.RedisClient
+		eventPublisher messaging.EventPublisher
+		appPublisher   *messaging.ApplicationPublisher
+		cacheService   ports.CacheService
+		newConsumer    func(queue string, bindings []messaging.ConsumerBinding) (messaging.EventConsumer, error)
+	)
 
-	eventPublisher, err := messaging.NewRabbitMQPublisher(rabbitConfig)
-	if err != nil {
-		log.Fatal().Err(err).Msg("Failed to initialize event publisher")
+	if cfg.IsDevMode() {
+		log.Warn().Msg("Dev mode: events are delivered in process and the cache is kept in memory")
+
+		bus := messaging.NewMemoryBus()
+		lc.OnClose("event bus", bus)
+		eventPublisher = bus
+		appPublisher = messaging.NewMemoryApplicationPublisher(bus)
+		cacheService = salescache.NewMemoryCacheService(salescache.DefaultRedisCacheConfig().DefaultTTL)
+		newConsumer = func(queue string, bindings []messaging.ConsumerBinding) (messaging.EventConsumer, error) {
+			return bus.NewConsumer(bindings), nil
+		}
+	} else {
+		// Initialize Redis
+		redisClient, err = lifecycle.Await(startup, "redis", func() (*database.RedisClient, error) {
+			return database.NewRedis(&cfg.Redis, log)
+		})
+		if err != nil {
+			log.Fatal().Err(err).Msg("Failed to connect to Redis")
+		}
+		lc.OnClose("redis", redisClient)
+
+		// Initialize RabbitMQ Publisher
+		rabbitConfig := messaging.RabbitMQConfig{
+			URL:               cfg.RabbitMQ.URL,
+			Exchange:          messaging.SalesEventsExchange,
+			ExchangeType:      cfg.RabbitMQ.ExchangeType,
+			Durable:           true,
+			AutoDelete:        false,
+			DeliveryMode:      2, // Persistent
+			ContentType:       "application/json",
+			ReconnectDelay:    cfg.RabbitMQ.ReconnectDelay,
+			MaxReconnectTries: 10,
+			PrefetchCount:     cfg.RabbitMQ.PrefetchCount,
+		}
+
+		rabbitPublisher, err := lifecycle.Await(startup, "rabbitmq", func() (*messaging.RabbitMQPublisher, error) {
+			return messaging.NewRabbitMQPublisher(rabbitConfig)
+		})
+		if err != nil {
+			log.Fatal().Err(err).Msg("Failed to initialize event publisher")
+		}
+		lc.OnClose("event publisher", rabbitPublisher)
+
+		// Declare queues
+		if err := rabbitPublisher.DeclareQueues(); err != nil {
+			log.Warn().Err(err).Msg("Failed to declare queues (non-fatal)")
+		}
+
+		eventPublisher = rabbitPublisher
+		appPublisher = messaging.NewApplicationPublisher(rabbitPublisher)
+		cacheService = salescache.NewRedisCacheService(redisClient.Client(), salescache.DefaultRedisCacheConfig())
+		newConsumer = func(queue string, bindings []messaging.ConsumerBinding) (messaging.EventConsumer, error) {
+			consumer, err := messaging.NewRabbitMQConsumer(messaging.RabbitMQConsumerConfig{
+				URL:            cfg.RabbitMQ.URL,
+				Queue:          queue,
+				Bindings:       bindings,
+				PrefetchCount:  cfg.RabbitMQ.PrefetchCount,
+				ReconnectDelay: cfg.RabbitMQ.ReconnectDelay,
+			})
+			if err != nil {
+				return nil, err
+			}
+			return consumer, nil
+		}
 	}
-	defer eventPublisher.Close()
 
-	// Declare queues
-	if err := eventPublisher.DeclareQueues(); err != nil {
-		log.Warn().Err(err).Msg("Failed to declare queues (non-fatal)")
+	// Invalidate cached entries when the data behind them changes
+	cacheInvalidator := salescache.NewCacheInvalidator(cacheService, log)
+
+	invalidationConsumer, err := newConsumer(messaging.CacheInvalidationQueue, cacheInvalidator.Bindings())
+	if err != nil {
+		log.Warn().Err(err).Msg("Failed to initialize cache invalidation consumer, cached entries expire by TTL only")
+	} else {
+		lc.OnShutdown("cache invalidation consumer", invalidationConsumer.Shutdown)
+		if err := invalidationConsumer.Consume(context.Background(), cacheInvalidator.Handle); err != nil {
+			log.Warn().Err(err).Msg("Failed to start cache invalidation consumer")
+		}
 	}
 
 	// Initialize repositories
@@ -115,79 +206,604 @@ func main() {
 	opportunityRepo := postgres.NewOpportunityRepository(sqlxDB)
 	dealRepo := postgres.NewDealRepository(sqlxDB)
 	pipelineRepo := postgres.NewPipelineRepository(sqlxDB)
+	reportRepo := postgres.NewReportRepository(sqlxDB)
+	reportExportRepo := postgres.NewReportExportRepository(sqlxDB)
+	reportBrandingRepo := postgres.NewReportBrandingRepository(sqlxDB)
+	exchangeRateRepo := postgres.NewExchangeRateRepository(sqlxDB)
+	taxSettingsRepo := postgres.NewTaxSettingsRepository(sqlxDB)
+	pricingSettingsRepo := postgres.NewPricingSettingsRepository(sqlxDB)
+	eventStore := postgres.NewEventStore(sqlxDB)
+	boardRepo := postgres.NewOpportunityBoardRepository(sqlxDB)
+	funnelRepo := postgres.NewStageFunnelRepository(sqlxDB)
+	insightRepo := postgres.NewInsightRepository(sqlxDB)
+	reasonRepo := postgres.NewOpportunityReasonRepository(sqlxDB)
+	discountApprovalRepo := postgres.NewDiscountApprovalRepository(sqlxDB)
+	orderRepo := postgres.NewOrderRepository(sqlxDB)
+	orderWebhookRepo := postgres.NewOrderWebhookRepository(sqlxDB)
+	shipmentRepo := postgres.NewShipmentRepository(sqlxDB)
+	einvoiceRepo := postgres.NewEInvoiceRepository(sqlxDB)
+	einvoiceSettingsRepo := postgres.NewEInvoiceSettingsRepository(sqlxDB)
+	accountingConnectionRepo := postgres.NewAccountingConnectionRepository(sqlxDB)
+	accountingSyncRepo := postgres.NewAccountingSyncRepository(sqlxDB)
+	ecommerceStoreRepo := postgres.NewEcommerceStoreRepository(sqlxDB)
+	ecommerceImportRepo := postgres.NewEcommerceImportRepository(sqlxDB)
+	inboundMailboxRepo := postgres.NewInboundMailboxRepository(sqlxDB)
+	inboundEmailRepo := postgres.NewInboundEmailRepository(sqlxDB)
+	retentionPolicyRepo := postgres.NewRetentionPolicyRepository(sqlxDB)
+	archivedRecordRepo := postgres.NewArchivedRecordRepository(sqlxDB)
+	commentRepo := postgres.NewCommentRepository(sqlxDB)
+	revenueSplitRepo := postgres.NewRevenueSplitRepository(sqlxDB)
+
+	// Record every published event in the event store
+	recordingPublisher := messaging.NewRecordingPublisher(eventStore, appPublisher, log)
+
+	// Initialize file storage for generated reports and attachments
+	exportDir := os.Getenv("SALES_EXPORT_DIR")
+	if exportDir == "" {
+		exportDir = filepath.Join(os.TempDir(), "crm-sales-exports")
+	}
+	fileStorage, err := storage.NewLocalFileStorage(exportDir)
+	if err != nil {
+		log.Fatal().Err(err).Msg("Failed to initialize file storage")
+	}
+
+	// Records archived by retention policies go to cold storage, typically
+	// an object storage bucket mounted at SALES_ARCHIVE_DIR
+	archiveDir := os.Getenv("SALES_ARCHIVE_DIR")
+	if archiveDir == "" {
+		archiveDir = filepath.Join(exportDir, "archive")
+	}
+	archiveStorage, err := storage.NewLocalFileStorage(archiveDir)
+	if err != nil {
+		log.Fatal().Err(err).Msg("Failed to initialize archive storage")
+	}
 
 	// Initialize use cases
+	exchangeRateUseCase := usecase.NewExchangeRateUseCase(
+		exchangeRateRepo,
+		[]ports.ExchangeRateProvider{
+			exchangerate.NewECBProvider(exchangerate.DefaultECBConfig()),
+			exchangerate.NewBNMProvider(exchangerate.DefaultBNMConfig()),
+		},
+	)
+
+	// Calls to the other services' internal routes carry a service token
+	serviceTokens := serviceauth.NewClient(cfg.ServiceAuth, "sales-service")
+
+	// Quotes, invoices and reports carry the tenant's white-label branding
+	// when the IAM service is reachable
+	var tenantBranding ports.TenantBrandingProvider
+	if iamServiceURL := os.Getenv("IAM_SERVICE_URL"); iamServiceURL != "" {
+		brandingConfig := salesiam.DefaultBrandingConfig(iamServiceURL)
+		brandingConfig.Transport = serviceTokens.Transport("iam-service", nil)
+		tenantBranding = salesiam.NewHTTPBrandingProvider(brandingConfig)
+	}
+
+	taxUseCase := usecase.NewTaxUseCase(
+		taxSettingsRepo,
+		opportunityRepo,
+		dealRepo,
+		reportBrandingRepo,
+		fileStorage,
+		export.NewPDFRenderer(),
+		discountApprovalRepo,
+		tenantBranding,
+	)
+
+	pricingUseCase := usecase.NewPricingUseCase(pricingSettingsRepo)
+
+	// Lead conversion runs as a saga that undoes its steps when one fails
+	leadConversion := usecase.NewLeadConversionOrchestrator(
+		postgres.NewUnitOfWork(sqlxDB),
+		leadRepo,
+		opportunityRepo,
+		pipelineRepo,
+		recordingPublisher,
+		nil, // customerService - inject if available
+	)
+
+	// New leads are matched against existing customers when the customer
+	// service is reachable
+	var customerMatcher ports.CustomerMatcher
+	if customerServiceURL := os.Getenv("CUSTOMER_SERVICE_URL"); customerServiceURL != "" {
+		matcherConfig := salescustomer.DefaultMatcherConfig(customerServiceURL)
+		matcherConfig.Transport = serviceTokens.Transport("customer-service", nil)
+		customerMatcher = salescustomer.NewHTTPMatcher(matcherConfig)
+	} else {
+		log.Warn().Msg("CUSTOMER_SERVICE_URL is not set, new leads are matched against existing leads only")
+	}
+
 	leadUseCase := usecase.NewLeadUseCase(
 		leadRepo,
 		opportunityRepo,
 		pipelineRepo,
-		eventPublisher,
+		recordingPublisher,
 		nil, // customerService - inject if available
 		nil, // userService - inject if available
-		nil, // cacheService - inject if available
+		cacheService,
 		nil, // searchService - inject if available
 		nil, // idGenerator - inject if available
+		leadConversion,
+		customerMatcher,
 	)
 
 	opportunityUseCase := usecase.NewOpportunityUseCase(
 		opportunityRepo,
 		pipelineRepo,
 		dealRepo,
-		eventPublisher,
+		recordingPublisher,
 		nil, // customerService
 		nil, // userService
 		nil, // productService
-		nil, // cacheService
+		cacheService,
 		nil, // searchService
 		nil, // idGenerator
+		exchangeRateUseCase,
+		taxUseCase,
+		reasonRepo,
+		discountApprovalRepo,
+		pricingUseCase,
 	)
 
 	dealUseCase := usecase.NewDealUseCase(
 		dealRepo,
 		opportunityRepo,
-		eventPublisher,
+		recordingPublisher,
 		nil, // customerService
 		nil, // userService
 		nil, // productService
-		nil, // cacheService
+		cacheService,
 		nil, // searchService
 		nil, // idGenerator
 		nil, // notificationService
+		taxUseCase,
 	)
 
 	pipelineUseCase := usecase.NewPipelineUseCase(
 		pipelineRepo,
 		opportunityRepo,
-		eventPublisher,
-		nil, // cacheService
+		recordingPublisher,
+		cacheService,
 		nil, // idGenerator
+		exchangeRateUseCase,
+		revenueSplitRepo,
+	)
+
+	reportUseCase := usecase.NewReportUseCase(
+		reportRepo,
+		reportExportRepo,
+		reportBrandingRepo,
+		[]ports.ReportRenderer{
+			export.NewXLSXRenderer(),
+			export.NewPDFRenderer(),
+		},
+		fileStorage,
+		nil, // notificationService
+		cacheService,
+		tenantBranding,
+	)
+
+	eventStoreUseCase := usecase.NewEventStoreUseCase(eventStore)
+	boardUseCase := usecase.NewBoardUseCase(boardRepo, pipelineRepo, opportunityRepo)
+	funnelUseCase := usecase.NewFunnelUseCase(funnelRepo, pipelineRepo, opportunityRepo)
+	insightUseCase := usecase.NewInsightUseCase(insightRepo, opportunityRepo, recordingPublisher)
+	reasonUseCase := usecase.NewOpportunityReasonUseCase(reasonRepo)
+	discountApprovalUseCase := usecase.NewDiscountApprovalUseCase(
+		discountApprovalRepo,
+		opportunityRepo,
+		recordingPublisher,
+		nil, // notificationService
+	)
+	// Orders are pushed to the tenant's factory system over signed webhooks
+	orderUseCase := usecase.NewOrderUseCase(
+		orderRepo,
+		dealRepo,
+		orderWebhookRepo,
+		recordingPublisher,
+		webhook.NewHTTPSender(webhook.DefaultConfig(), log),
+	)
+
+	// Carriers are polled through the tracking API and may push updates to
+	// the signed carrier webhook
+	var (
+		carrierTrackers       []ports.CarrierTracker
+		carrierWebhookParsers []ports.CarrierWebhookParser
+	)
+	carrierTrackingURL := os.Getenv("SALES_CARRIER_TRACKING_URL")
+	carrierWebhookSecret := os.Getenv("SALES_CARRIER_WEBHOOK_SECRET")
+	for _, code := range strings.Split(os.Getenv("SALES_CARRIERS"), ",") {
+		code = domain.NormalizeCarrier(code)
+		if code == "" {
+			continue
+		}
+		if carrierTrackingURL != "" {
+			carrierTrackers = append(carrierTrackers, carrier.NewHTTPTracker(code, carrier.HTTPTrackerConfig{
+				URL:    carrierTrackingURL,
+				APIKey: os.Getenv("SALES_CARRIER_TRACKING_API_KEY"),
+			}))
+		}
+		if carrierWebhookSecret != "" {
+			carrierWebhookParsers = append(carrierWebhookParsers, carrier.NewSignedWebhookParser(code, carrierWebhookSecret))
+		}
+	}
+	shipmentUseCase := usecase.NewShipmentUseCase(
+		shipmentRepo,
+		dealRepo,
+		recordingPublisher,
+		nil, // customerService
+		carrierTrackers,
+		carrierWebhookParsers,
+	)
+
+	// Invoices are submitted to MyInvois through an intermediary account that
+	// acts on behalf of each tenant's TIN
+	var einvoiceService ports.EInvoiceService
+	if clientID := os.Getenv("SALES_MYINVOIS_CLIENT_ID"); clientID != "" {
+		myinvoisConfig := myinvois.SandboxConfig(clientID, os.Getenv("SALES_MYINVOIS_CLIENT_SECRET"))
+		if os.Getenv("SALES_MYINVOIS_ENV") == "production" {
+			myinvoisConfig = myinvois.ProductionConfig(clientID, os.Getenv("SALES_MYINVOIS_CLIENT_SECRET"))
+		}
+		einvoiceService = myinvois.NewClient(myinvoisConfig)
+	}
+	einvoiceUseCase := usecase.NewEInvoiceUseCase(
+		einvoiceRepo,
+		einvoiceSettingsRepo,
+		dealRepo,
+		taxSettingsRepo,
+		recordingPublisher,
+		einvoiceService,
+	)
+	// Invoices and payments are pushed to the connector each tenant runs in
+	// front of its accounting system
+	accountingUseCase := usecase.NewAccountingUseCase(
+		accountingConnectionRepo,
+		accountingSyncRepo,
+		dealRepo,
+		recordingPublisher,
+		accounting.NewHTTPConnector(accounting.HTTPConnectorConfig{}),
+	)
+	// Online store orders become won deals and abandoned carts become leads
+	ecommerceUseCase := usecase.NewEcommerceUseCase(
+		ecommerceStoreRepo,
+		ecommerceImportRepo,
+		pipelineRepo,
+		opportunityRepo,
+		dealRepo,
+		leadRepo,
+		nil, // customerService - inject if available
+		customerMatcher,
+		nil, // idGenerator - inject if available
+		recordingPublisher,
+		[]ports.EcommerceClient{
+			ecommerce.NewShopifyClient(0),
+			ecommerce.NewWooCommerceClient(0),
+		},
+	)
+	// Attachments are quarantined until clamd scans them clean; in
+	// detect-only mode scans are only recorded
+	var virusScanner ports.VirusScanner
+	if clamavAddr := os.Getenv("SALES_CLAMAV_ADDR"); clamavAddr != "" {
+		virusScanner = antivirus.NewClamAV(antivirus.DefaultClamAVConfig(clamavAddr))
+	} else {
+		log.Warn().Msg("SALES_CLAMAV_ADDR is not set, attachments are not scanned for viruses")
+	}
+	attachmentUseCase := usecase.NewAttachmentUseCase(
+		opportunityRepo,
+		fileStorage,
+		imaging.NewResizer(imaging.DefaultJPEGQuality),
+		virusScanner,
+		recordingPublisher,
+		nil, // notificationService
+		usecase.AttachmentScanConfig{DetectOnly: os.Getenv("SALES_CLAMAV_DETECT_ONLY") == "true"},
+	)
+
+	// Email sent to a tenant's forwarding address on this domain becomes a lead
+	inboundEmailDomain := os.Getenv("SALES_INBOUND_EMAIL_DOMAIN")
+	inboundEmailUseCase := usecase.NewInboundEmailUseCase(
+		inboundMailboxRepo,
+		inboundEmailRepo,
+		leadRepo,
+		opportunityRepo,
+		salesemail.NewMIMEParser(),
+		fileStorage,
+		recordingPublisher,
+		inboundEmailDomain,
+	)
+	if inboundEmailDomain == "" {
+		log.Warn().Msg("SALES_INBOUND_EMAIL_DOMAIN is not set, inbound email is disabled")
+	}
+
+	retentionUseCase := usecase.NewRetentionUseCase(
+		retentionPolicyRepo,
+		archivedRecordRepo,
+		postgres.NewRetentionStore(sqlxDB),
+		postgres.NewTransactionManager(sqlxDB),
+		archiveStorage,
+	)
+
+	demoDataUseCase := usecase.NewDemoDataUseCase(
+		postgres.NewDemoDataRepository(sqlxDB),
+		pipelineRepo,
+		leadRepo,
+		opportunityRepo,
 	)
 
+	reassignmentUseCase := usecase.NewOwnerReassignmentUseCase(leadRepo, opportunityRepo, recordingPublisher)
+
+	presenceUseCase := usecase.NewPresenceUseCase(leadRepo, opportunityRepo, dealRepo)
+
+	commentUseCase := usecase.NewCommentUseCase(
+		commentRepo,
+		leadRepo,
+		opportunityRepo,
+		nil, // customerService - inject if available
+		recordingPublisher,
+	)
+
+	revenueSplitUseCase := usecase.NewRevenueSplitUseCase(
+		revenueSplitRepo,
+		opportunityRepo,
+		nil, // userService - inject if available
+		recordingPublisher,
+	)
+
+	// Keep the opportunity board read model up to date
+	boardProjector := projection.NewBoardProjector(boardUseCase, log)
+
+	boardConsumer, err := newConsumer(messaging.OpportunityBoardQueue, boardProjector.Bindings())
+	if err != nil {
+		log.Warn().Err(err).Msg("Failed to initialize opportunity board consumer, boards update on rebuild only")
+	} else {
+		lc.OnShutdown("opportunity board consumer", boardConsumer.Shutdown)
+		if err := boardConsumer.Consume(context.Background(), boardProjector.Handle); err != nil {
+			log.Warn().Err(err).Msg("Failed to start opportunity board consumer")
+		}
+	}
+
+	// Keep the stage funnel read model up to date
+	funnelProjector := projection.NewFunnelProjector(funnelUseCase, log)
+
+	funnelConsumer, err := newConsumer(messaging.StageFunnelQueue, funnelProjector.Bindings())
+	if err != nil {
+		log.Warn().Err(err).Msg("Failed to initialize stage funnel consumer, funnels update on rebuild only")
+	} else {
+		lc.OnShutdown("stage funnel consumer", funnelConsumer.Shutdown)
+		if err := funnelConsumer.Consume(context.Background(), funnelProjector.Handle); err != nil {
+			log.Warn().Err(err).Msg("Failed to start stage funnel consumer")
+		}
+	}
+
+	// Generate image variants of uploaded attachments
+	thumbnailWorker := worker.NewThumbnailWorker(attachmentUseCase, log)
+
+	thumbnailConsumer, err := newConsumer(messaging.ThumbnailQueue, thumbnailWorker.Bindings())
+	if err != nil {
+		log.Warn().Err(err).Msg("Failed to initialize thumbnail consumer, image variants are generated on first download")
+	} else {
+		lc.OnShutdown("thumbnail consumer", thumbnailConsumer.Shutdown)
+		if err := thumbnailConsumer.Consume(context.Background(), thumbnailWorker.Handle); err != nil {
+			log.Warn().Err(err).Msg("Failed to start thumbnail consumer")
+		}
+	}
+
+	// Scan uploaded attachments for viruses
+	if virusScanner != nil {
+		scanWorker := worker.NewScanWorker(attachmentUseCase, log)
+
+		scanConsumer, err := newConsumer(messaging.AttachmentScanQueue, scanWorker.Bindings())
+		if err != nil {
+			log.Warn().Err(err).Msg("Failed to initialize attachment scan consumer, attachments are scanned on first download")
+		} else {
+			lc.OnShutdown("attachment scan consumer", scanConsumer.Shutdown)
+			if err := scanConsumer.Consume(context.Background(), scanWorker.Handle); err != nil {
+				log.Warn().Err(err).Msg("Failed to start attachment scan consumer")
+			}
+		}
+	}
+
+	// Run the insights engine: won opportunities are checked as they are won,
+	// pipeline coverage and lead volume hourly
+	insightWorker := worker.NewInsightWorker(insightUseCase, worker.DefaultInsightConfig(), log)
+
+	insightConsumer, err := newConsumer(messaging.InsightQueue, insightWorker.Bindings())
+	if err != nil {
+		log.Warn().Err(err).Msg("Failed to initialize insight consumer, biggest deal insights are disabled")
+	} else {
+		lc.OnShutdown("insight consumer", insightConsumer.Shutdown)
+		if err := insightConsumer.Consume(context.Background(), insightWorker.Handle); err != nil {
+			log.Warn().Err(err).Msg("Failed to start insight consumer")
+		}
+	}
+	insightWorker.Start(context.Background())
+	lc.OnShutdown("insight worker", func(context.Context) error {
+		insightWorker.Stop()
+		return nil
+	})
+
+	// Erase customers' personal data when the customer service erases them
+	erasureWorker := worker.NewCustomerErasureWorker(postgres.NewCustomerErasureRepository(sqlxDB), eventPublisher, log)
+
+	erasureConsumer, err := newConsumer(messaging.CustomerErasureQueue, erasureWorker.Bindings())
+	if err != nil {
+		log.Warn().Err(err).Msg("Failed to initialize customer erasure consumer, erasures stay pending until it starts")
+	} else {
+		lc.OnShutdown("customer erasure consumer", erasureConsumer.Shutdown)
+		if err := erasureConsumer.Consume(context.Background(), erasureWorker.Handle); err != nil {
+			log.Warn().Err(err).Msg("Failed to start customer erasure consumer")
+		}
+	}
+
+	// Apply log level changes from the config file or SIGHUP without a restart
+	runtime := config.NewRuntime(cfg)
+	runtime.Watch(lc.Context(), func(err error) {
+		log.Warn().Err(err).Msg("Failed to reload configuration")
+	})
+	go func() {
+		for tunables := range runtime.Subscribe(lc.Context()) {
+			if err := log.SetLevel(tunables.LogLevel); err != nil {
+				log.Warn().Err(err).Msg("Invalid log level")
+				continue
+			}
+			log.Info().Str("level", tunables.LogLevel).Msg("Log level updated")
+		}
+	}()
+
+	// Start nightly report aggregation. The workers are stopped rather than
+	// cancelled at shutdown, so a run in progress completes
+	aggregationWorker := worker.NewReportAggregationWorker(reportUseCase, worker.DefaultReportAggregationConfig(), log)
+	aggregationWorker.Start(context.Background())
+	lc.OnShutdown("report aggregation worker", func(context.Context) error {
+		aggregationWorker.Stop()
+		return nil
+	})
+
+	// Start exchange rate refresh for tenants with a rate provider
+	exchangeRateWorker := worker.NewExchangeRateRefreshWorker(exchangeRateUseCase, worker.DefaultExchangeRateRefreshConfig(), log)
+	exchangeRateWorker.Start(context.Background())
+	lc.OnShutdown("exchange rate worker", func(context.Context) error {
+		exchangeRateWorker.Stop()
+		return nil
+	})
+
+	// Apply tenants' retention policies daily
+	retentionWorker := worker.NewRetentionWorker(retentionUseCase, worker.DefaultRetentionConfig(), log)
+	retentionWorker.Start(context.Background())
+	lc.OnShutdown("retention worker", func(context.Context) error {
+		retentionWorker.Stop()
+		return nil
+	})
+
+	// Mark lapsed invoices overdue hourly, publishing the overdue alerts
+	invoiceOverdueWorker := worker.NewInvoiceOverdueWorker(dealUseCase, worker.DefaultInvoiceOverdueConfig(), log)
+	invoiceOverdueWorker.Start(context.Background())
+	lc.OnShutdown("invoice overdue worker", func(context.Context) error {
+		invoiceOverdueWorker.Stop()
+		return nil
+	})
+
+	// Open renewal opportunities for recurring deals hourly, once their
+	// renewal notice starts
+	renewalWorker := worker.NewRenewalWorker(opportunityUseCase, worker.DefaultRenewalConfig(), log)
+	renewalWorker.Start(context.Background())
+	lc.OnShutdown("renewal worker", func(context.Context) error {
+		renewalWorker.Stop()
+		return nil
+	})
+
+	// Poll carriers for shipments on their way
+	shipmentTrackingWorker := worker.NewShipmentTrackingWorker(shipmentUseCase, worker.DefaultShipmentTrackingConfig(), log)
+	shipmentTrackingWorker.Start(context.Background())
+	lc.OnShutdown("shipment tracking worker", func(context.Context) error {
+		shipmentTrackingWorker.Stop()
+		return nil
+	})
+
+	// Retry MyInvois submissions and collect validation results
+	einvoiceWorker := worker.NewEInvoiceWorker(einvoiceUseCase, worker.DefaultEInvoiceConfig(), log)
+	einvoiceWorker.Start(context.Background())
+	lc.OnShutdown("e-invoice worker", func(context.Context) error {
+		einvoiceWorker.Stop()
+		return nil
+	})
+
+	// Push invoices and payments to accounting systems
+	accountingSyncWorker := worker.NewAccountingSyncWorker(accountingUseCase, worker.DefaultAccountingSyncConfig(), log)
+	accountingSyncWorker.Start(context.Background())
+	lc.OnShutdown("accounting sync worker", func(context.Context) error {
+		accountingSyncWorker.Stop()
+		return nil
+	})
+
+	// Poll online stores for the orders and carts their webhooks missed
+	ecommerceSyncWorker := worker.NewEcommerceSyncWorker(ecommerceUseCase, worker.DefaultEcommerceSyncConfig(), log)
+	ecommerceSyncWorker.Start(context.Background())
+	lc.OnShutdown("e-commerce sync worker", func(context.Context) error {
+		ecommerceSyncWorker.Stop()
+		return nil
+	})
+
+	// Tokens signed with rotated-out keys stay valid until they expire
+	jwtPreviousSecrets := make([]string, 0, len(cfg.JWT.PreviousKeys))
+	for _, key := range cfg.JWT.PreviousKeys {
+		jwtPreviousSecrets = append(jwtPreviousSecrets, key.Secret)
+	}
+
+	// Feature flags, managed at the gateway and read from the shared store.
+	// Without Redis in dev mode every flag is off unless they are kept in
+	// PostgreSQL
+	var flagStore featureflag.Store = featureflag.NewMemoryStore()
+	if !cfg.IsDevMode() || cfg.FeatureFlags.Store == "postgres" {
+		flagStore, err = featureflag.NewStore(cfg.FeatureFlags, redisClient, db.DB)
+		if err != nil {
+			log.Fatal().Err(err).Msg("Invalid feature flag config")
+		}
+	}
+	flags := featureflag.NewClient(flagStore, log)
+	flags.Start(lc.Context(), cfg.FeatureFlags.RefreshInterval)
+
 	// Initialize HTTP handlers
 	handler := saleshttp.NewHandler(saleshttp.HandlerDependencies{
-		LeadUseCase:        leadUseCase,
-		OpportunityUseCase: opportunityUseCase,
-		DealUseCase:        dealUseCase,
-		PipelineUseCase:    pipelineUseCase,
+		LeadUseCase:             leadUseCase,
+		OpportunityUseCase:      opportunityUseCase,
+		DealUseCase:             dealUseCase,
+		PipelineUseCase:         pipelineUseCase,
+		ReportUseCase:           reportUseCase,
+		ExchangeRateUseCase:     exchangeRateUseCase,
+		TaxUseCase:              taxUseCase,
+		PricingUseCase:          pricingUseCase,
+		EventStoreUseCase:       eventStoreUseCase,
+		BoardUseCase:            boardUseCase,
+		FunnelUseCase:           funnelUseCase,
+		InsightUseCase:          insightUseCase,
+		ReasonUseCase:           reasonUseCase,
+		DiscountApprovalUseCase: discountApprovalUseCase,
+		OrderUseCase:            orderUseCase,
+		ShipmentUseCase:         shipmentUseCase,
+		EInvoiceUseCase:         einvoiceUseCase,
+		AccountingUseCase:       accountingUseCase,
+		EcommerceUseCase:        ecommerceUseCase,
+		AttachmentUseCase:       attachmentUseCase,
+		InboundEmailUseCase:     inboundEmailUseCase,
+		RetentionUseCase:        retentionUseCase,
+		TenantExporter:          postgres.NewTenantExportRepository(sqlxDB),
+		DemoDataUseCase:         demoDataUseCase,
+		ReassignmentUseCase:     reassignmentUseCase,
+		PresenceUseCase:         presenceUseCase,
+		CommentUseCase:          commentUseCase,
+		RevenueSplitUseCase:     revenueSplitUseCase,
+		ServiceAuth:             serviceauth.NewVerifier(cfg.ServiceAuth, "sales-service"),
 		MiddlewareConfig: saleshttp.MiddlewareConfig{
-			JWTSecret:         cfg.JWT.Secret,
-			JWTIssuer:         cfg.JWT.Issuer,
-			JWTAudience:       cfg.JWT.Audience,
-			SkipAuth:          cfg.App.Environment == "development",
-			AllowedOrigins:    []string{"*"},
-			RateLimitRequests: 100,
-			RateLimitWindow:   time.Minute,
+			JWTSecret:          cfg.JWT.Secret,
+			JWTPreviousSecrets: jwtPreviousSecrets,
+			IdentitySecret:     cfg.JWT.IdentitySecret,
+			JWTIssuer:          cfg.JWT.Issuer,
+			JWTAudience:        cfg.JWT.Audience,
+			SkipAuth:           cfg.App.Environment == "development",
+			AllowedOrigins:     cfg.CORS.Origins(cfg.App.Environment),
+			RateLimitRequests:  cfg.RateLimit.Requests,
+			RateLimitWindow:    cfg.RateLimit.Window,
+			InboundEmailSecret: os.Getenv("SALES_INBOUND_EMAIL_SECRET"),
 		},
 	})
 
+	// Shed requests over the adaptive concurrency limit during spikes
+	loadShedder, err := crmmiddleware.NewLoadShedder(cfg.App.Name, cfg.LoadShed, log)
+	if err != nil {
+		log.Fatal().Err(err).Msg("Invalid load shedding config")
+	}
+
 	// Create Chi router
 	r := chi.NewRouter()
 
 	// Global middleware
 	r.Use(middleware.RequestID)
 	r.Use(middleware.RealIP)
-	r.Use(middleware.Logger)
+	r.Use(crmmiddleware.RequestLogger(log, cfg.Logger.Request))
 	r.Use(middleware.Recoverer)
+	r.Use(loadShedder.Middleware)
+	r.Use(security.Headers(cfg.Security))
+	r.Use(security.Sanitize(cfg.Security))
+	r.Use(featureflag.Middleware(flags, salesFlagSubject))
 	r.Use(middleware.Compress(5))
 
 	// Health check endpoint (no auth)
@@ -202,14 +818,16 @@ func main() {
 			checks["postgresql"] = response.HealthCheck{Status: "healthy"}
 		}
 
-		// Check Redis
-		if err := redisClient.Health(r.Context()); err != nil {
-			checks["redis"] = response.HealthCheck{Status: "unhealthy", Message: err.Error()}
-		} else {
-			checks["redis"] = response.HealthCheck{Status: "healthy"}
+		// Check Redis, unless the cache is kept in memory in dev mode
+		if redisClient != nil {
+			if err := redisClient.Health(r.Context()); err != nil {
+				checks["redis"] = response.HealthCheck{Status: "unhealthy", Message: err.Error()}
+			} else {
+				checks["redis"] = response.HealthCheck{Status: "healthy"}
+			}
 		}
 
-		// Check RabbitMQ
+		// Check RabbitMQ, or the memory event bus in dev mode
 		if !eventPublisher.IsConnected() {
 			checks["rabbitmq"] = response.HealthCheck{Status: "unhealthy", Message: "connection closed"}
 		} else {
@@ -227,15 +845,21 @@ func main() {
 		response.Health(w, status, Version, time.Since(startTime), checks)
 	})
 
-	// Metrics endpoint (placeholder)
+	// Metrics endpoint
 	r.Get("/metrics", func(w http.ResponseWriter, r *http.Request) {
 		w.Header().Set("Content-Type", "text/plain")
-		w.Write([]byte("# Metrics placeholder\n"))
+		loadShedder.WriteMetrics(w)
 	})
 
 	// Register Sales API routes
 	handler.RegisterRoutes(r)
 
+	// Cross-service workflows run on the saga manager; operators list stuck
+	// sagas and resume or abort them through the admin API
+	sagaManager := events.NewSagaManager(events.NewPostgresSagaStore(sqlxDB, "sales.workflow_sagas"), nil)
+	r.With(handler.AuthMiddleware, handler.RequireAnyRole("super_admin")).
+		Handle("/api/v1/admin/sagas/*", http.StripPrefix("/api/v1/admin/sagas", events.NewSagaAdminHandler(sagaManager)))
+
 	// Create HTTP server
 	server := &http.Server{
 		Addr:         fmt.Sprintf("%s:%d", cfg.Server.Host, cfg.Server.Port),
@@ -244,8 +868,11 @@ func main() {
 		WriteTimeout: cfg.Server.WriteTimeout,
 		IdleTimeout:  cfg.Server.IdleTimeout,
 	}
+	// Presence streams only end when their client leaves
+	server.RegisterOnShutdown(presenceUseCase.Close)
 
-	// Start server in a goroutine
+	// Start server in a goroutine, taking over from the startup health checks
+	startup.Ready()
 	go func() {
 		log.Info().
 			Str("addr", server.Addr).
@@ -255,27 +882,37 @@ func main() {
 			log.Fatal().Err(err).Msg("HTTP server failed")
 		}
 	}()
+	lc.OnShutdown("http server", server.Shutdown)
 
 	// Wait for interrupt signal
-	quit := make(chan os.Signal, 1)
-	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
-	<-quit
+	sig := lc.Wait()
+	log.Info().Str("signal", sig.String()).Msg("Shutting down server...")
 
-	log.Info().Msg("Shutting down server...")
+	// Stop taking requests and messages, let those in flight finish, then
+	// close the connections
+	if err := lc.Shutdown(cfg.Server.ShutdownTimeout); err != nil {
+		log.Error().Err(err).Msg("Server forced to shutdown")
+	}
 
-	// Create shutdown context with timeout
-	shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), cfg.Server.ShutdownTimeout)
-	defer shutdownCancel()
+	log.Info().Msg("Server stopped")
 
-	// Close event publisher
-	if err := eventPublisher.Close(); err != nil {
-		log.Error().Err(err).Msg("Failed to close event publisher")
+	// Ship the log lines still buffered
+	logCtx, logCancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer logCancel()
+	if err := log.Close(logCtx); err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to ship remaining logs: %v\n", err)
 	}
+}
 
-	// Graceful shutdown
-	if err := server.Shutdown(shutdownCtx); err != nil {
-		log.Error().Err(err).Msg("Server forced to shutdown")
+// salesFlagSubject evaluates feature flags for the tenant and user the
+// authentication middleware resolved.
+func salesFlagSubject(ctx context.Context) featureflag.Subject {
+	var subject featureflag.Subject
+	if tenantID, ok := saleshttp.GetTenantIDFromContext(ctx); ok {
+		subject.TenantID = tenantID.String()
 	}
-
-	log.Info().Msg("Server stopped")
+	if userID, ok := saleshttp.GetUserIDFromContext(ctx); ok {
+		subject.UserID = userID.String()
+	}
+	return subject
 }
@@ -0,0 +1,182 @@
+// Traffic Replay - Captured Request Replay Tool
+// =============================================
+// Re-issues the requests recorded by a gateway traffic capture against
+// another environment, typically staging, and reports the requests whose
+// status differs from the captured one. The captured credentials are
+// redacted, so the requests are sent with the token given here.
+//
+// Usage:
+//
+//	traffic-replay -capture 3f0c... -target https://staging-api.example.com -token $STAGING_TOKEN
+//	traffic-replay -capture 3f0c... -target https://staging-api.example.com -writes -path /api/v1/leads
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/kilang-desa-murni/crm/pkg/capture"
+	"github.com/kilang-desa-murni/crm/pkg/logger"
+)
+
+// Version information (set during build)
+var (
+	Version   = "dev"
+	BuildTime = "unknown"
+	GitCommit = "unknown"
+)
+
+func main() {
+	var (
+		dir       = flag.String("dir", defaultCaptureDir(), "directory the gateway stores captures in (GATEWAY_CAPTURE_DIR)")
+		captureID = flag.String("capture", "", "ID of the capture to replay")
+		target    = flag.String("target", "", "base URL of the environment to replay against")
+		token     = flag.String("token", os.Getenv("REPLAY_TOKEN"), "bearer token the requests are sent with (REPLAY_TOKEN)")
+		writes    = flag.Bool("writes", false, "also replay requests other than GET, HEAD and OPTIONS")
+		path      = flag.String("path", "", "replay only requests under this path prefix")
+		timeout   = flag.Duration("timeout", 30*time.Second, "timeout of each replayed request")
+		pace      = flag.Bool("pace", false, "keep the captured gaps between requests")
+		asJSON    = flag.Bool("json", false, "print the results as JSON lines")
+		dryRun    = flag.Bool("dry-run", false, "list the captured requests without replaying them")
+	)
+	flag.Parse()
+
+	if *captureID == "" || (*target == "" && !*dryRun) {
+		fmt.Fprintln(os.Stderr, "Usage: traffic-replay -capture <id> -target <url> [-token <token>]")
+		flag.PrintDefaults()
+		os.Exit(2)
+	}
+
+	log := logger.New(logger.Config{Level: "info", Format: "console"})
+	log = log.With().Service("traffic-replay").Logger()
+
+	// Stop cleanly on interrupt
+	ctx, cancel := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer cancel()
+
+	store, err := capture.NewDirStore(*dir)
+	if err != nil {
+		log.Fatal().Err(err).Msg("Failed to open capture storage")
+	}
+	exchanges, err := store.List(ctx, *captureID)
+	if err != nil {
+		log.Fatal().Err(err).Str("capture_id", *captureID).Msg("Failed to load capture")
+	}
+
+	if *dryRun {
+		for _, exchange := range exchanges {
+			if strings.HasPrefix(exchange.Path, *path) {
+				fmt.Printf("%s\t%d\t%s %s\n", exchange.StartedAt.Format(time.RFC3339), exchange.Status, exchange.Method, requestURI(exchange))
+			}
+		}
+		return
+	}
+
+	replayer, err := capture.NewReplayer(capture.ReplayConfig{
+		Target:      *target,
+		Token:       *token,
+		AllowWrites: *writes,
+		Timeout:     *timeout,
+	})
+	if err != nil {
+		log.Fatal().Err(err).Msg("Invalid replay target")
+	}
+
+	log.Info().
+		Str("version", Version).
+		Str("build_time", BuildTime).
+		Str("git_commit", GitCommit).
+		Str("capture_id", *captureID).
+		Int("exchanges", len(exchanges)).
+		Str("target", *target).
+		Msg("Starting traffic replay")
+
+	var replayed, matched, skipped, failed int
+	var previous time.Time
+	for _, exchange := range exchanges {
+		if ctx.Err() != nil {
+			break
+		}
+		if !strings.HasPrefix(exchange.Path, *path) {
+			continue
+		}
+		if *pace && !previous.IsZero() {
+			select {
+			case <-ctx.Done():
+			case <-time.After(exchange.StartedAt.Sub(previous)):
+			}
+		}
+		previous = exchange.StartedAt
+
+		result := replayer.Replay(ctx, exchange)
+		switch {
+		case result.Skipped != "":
+			skipped++
+		case result.Error != "":
+			failed++
+		case result.Matches():
+			replayed++
+			matched++
+		default:
+			replayed++
+		}
+		printResult(result, *asJSON)
+	}
+
+	log.Info().
+		Int("replayed", replayed).
+		Int("matched", matched).
+		Int("differed", replayed-matched).
+		Int("skipped", skipped).
+		Int("failed", failed).
+		Msg("Traffic replay completed")
+
+	if replayed != matched || failed > 0 {
+		os.Exit(1)
+	}
+}
+
+// printResult prints one replayed exchange.
+func printResult(result *capture.ReplayResult, asJSON bool) {
+	if asJSON {
+		line, _ := json.Marshal(result)
+		fmt.Println(string(line))
+		return
+	}
+
+	exchange := result.Exchange
+	switch {
+	case result.Skipped != "":
+		fmt.Printf("SKIP\t%s %s\t%s\n", exchange.Method, requestURI(exchange), result.Skipped)
+	case result.Error != "":
+		fmt.Printf("ERROR\t%s %s\t%s\n", exchange.Method, requestURI(exchange), result.Error)
+	case result.Matches():
+		fmt.Printf("OK\t%s %s\t%d\t%dms\n", exchange.Method, requestURI(exchange), result.Status, result.DurationMs)
+	default:
+		fmt.Printf("DIFF\t%s %s\t%d -> %d\t%dms\n", exchange.Method, requestURI(exchange), exchange.Status, result.Status, result.DurationMs)
+	}
+}
+
+// requestURI returns the path and query of a captured request.
+func requestURI(exchange *capture.Exchange) string {
+	if exchange.Query == "" {
+		return exchange.Path
+	}
+	return exchange.Path + "?" + exchange.Query
+}
+
+// defaultCaptureDir is the gateway's capture directory.
+func defaultCaptureDir() string {
+	if dir := os.Getenv("GATEWAY_CAPTURE_DIR"); dir != "" {
+		return dir
+	}
+	return filepath.Join(os.TempDir(), "crm-traffic-captures")
+}
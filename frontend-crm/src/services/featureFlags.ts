@@ -0,0 +1,48 @@
+// ============================================
+// Feature Flag Service
+// Flags evaluated for the signed-in tenant and user
+// ============================================
+
+import { api } from './api';
+
+interface FeatureFlagsResponse {
+    flags: Record<string, boolean>;
+}
+
+// The gateway lets browsers cache the flags for a minute
+const CACHE_TTL_MS = 60 * 1000;
+
+let cached: { flags: Record<string, boolean>; loadedAt: number } | null = null;
+
+export const featureFlagService = {
+    /**
+     * Get every flag's state for the current user, cached for a minute
+     */
+    getFlags: async (refresh = false): Promise<Record<string, boolean>> => {
+        if (!refresh && cached && Date.now() - cached.loadedAt < CACHE_TTL_MS) {
+            return cached.flags;
+        }
+        const response = await api.get<FeatureFlagsResponse>('/feature-flags');
+        cached = { flags: response.flags, loadedAt: Date.now() };
+        return response.flags;
+    },
+
+    /**
+     * Check one flag; unknown flags and failed lookups are off
+     */
+    isEnabled: async (key: string): Promise<boolean> => {
+        try {
+            const flags = await featureFlagService.getFlags();
+            return flags[key] === true;
+        } catch {
+            return false;
+        }
+    },
+
+    /**
+     * Forget the cached flags, e.g. after signing out or switching tenant
+     */
+    clear: (): void => {
+        cached = null;
+    },
+};
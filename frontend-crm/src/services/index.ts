@@ -10,3 +10,4 @@ export { opportunityService } from './opportunities';
 export { customerService } from './customers';
 export { pipelineService } from './pipelines';
 export { dashboardService } from './dashboard';
+export { featureFlagService } from './featureFlags';
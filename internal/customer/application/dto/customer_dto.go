@@ -84,6 +84,9 @@ type CustomerResponse struct {
 	ConvertedAt     *time.Time                    `json:"converted_at,omitempty"`
 	ChurnedAt       *time.Time                    `json:"churned_at,omitempty"`
 	ChurnReason     string                        `json:"churn_reason,omitempty"`
+	Lifecycle       domain.CustomerLifecycle      `json:"lifecycle"`
+	LifecycleChangedAt *time.Time                 `json:"lifecycle_changed_at,omitempty"`
+	LifecycleOverride bool                        `json:"lifecycle_override"`
 	Version         int                           `json:"version"`
 	CreatedAt       time.Time                     `json:"created_at"`
 	UpdatedAt       time.Time                     `json:"updated_at"`
@@ -98,6 +101,9 @@ type CustomerSummaryResponse struct {
 	Name           string                `json:"name"`
 	Type           domain.CustomerType   `json:"type"`
 	Status         domain.CustomerStatus `json:"status"`
+	Lifecycle      domain.CustomerLifecycle `json:"lifecycle"`
+	HealthScore    int                   `json:"health_score"`
+	HealthTrend    domain.HealthTrend    `json:"health_trend,omitempty"`
 	Tier           domain.CustomerTier   `json:"tier"`
 	Email          string                `json:"email,omitempty"`
 	Phone          string                `json:"phone,omitempty"`
@@ -115,6 +121,7 @@ type CustomerListResponse struct {
 	Offset     int                       `json:"offset"`
 	Limit      int                       `json:"limit"`
 	HasMore    bool                      `json:"has_more"`
+	NextCursor string                    `json:"next_cursor,omitempty"`
 }
 
 // ============================================================================
@@ -152,13 +159,16 @@ type AddressInput struct {
 	State       string             `json:"state,omitempty" validate:"omitempty,max=100"`
 	PostalCode  string             `json:"postal_code" validate:"required,max=20"`
 	CountryCode string             `json:"country_code" validate:"required,len=2"`
-	AddressType domain.AddressType `json:"address_type,omitempty" validate:"omitempty,oneof=billing shipping office home other"`
+	AddressType domain.AddressType `json:"address_type,omitempty" validate:"omitempty,oneof=billing shipping office home outlet other"`
 	IsPrimary   bool               `json:"is_primary,omitempty"`
 	Label       string             `json:"label,omitempty" validate:"omitempty,max=50"`
+	Latitude    *float64           `json:"latitude,omitempty" validate:"omitempty,min=-90,max=90,required_with=Longitude"`
+	Longitude   *float64           `json:"longitude,omitempty" validate:"omitempty,min=-180,max=180,required_with=Latitude"`
 }
 
 // AddressResponse represents an address in responses.
 type AddressResponse struct {
+	ID          *uuid.UUID         `json:"id,omitempty"`
 	Line1       string             `json:"line1"`
 	Line2       string             `json:"line2,omitempty"`
 	Line3       string             `json:"line3,omitempty"`
@@ -310,19 +320,27 @@ type CustomerPreferencesResponse struct {
 
 // CustomerStatsResponse represents customer statistics.
 type CustomerStatsResponse struct {
-	ContactCount         int            `json:"contact_count"`
-	ActiveContactCount   int            `json:"active_contact_count"`
-	NoteCount            int            `json:"note_count"`
-	ActivityCount        int            `json:"activity_count"`
-	DealCount            int            `json:"deal_count"`
-	WonDealCount         int            `json:"won_deal_count"`
-	LostDealCount        int            `json:"lost_deal_count"`
-	OpenDealValue        *MoneyResponse `json:"open_deal_value,omitempty"`
-	DaysSinceLastContact *int           `json:"days_since_last_contact,omitempty"`
-	AvgDealSize          *MoneyResponse `json:"avg_deal_size,omitempty"`
-	EngagementScore      int            `json:"engagement_score"`
-	HealthScore          int            `json:"health_score"`
-	LastCalculatedAt     *time.Time     `json:"last_calculated_at,omitempty"`
+	ContactCount         int                   `json:"contact_count"`
+	ActiveContactCount   int                   `json:"active_contact_count"`
+	NoteCount            int                   `json:"note_count"`
+	ActivityCount        int                   `json:"activity_count"`
+	DealCount            int                   `json:"deal_count"`
+	WonDealCount         int                   `json:"won_deal_count"`
+	LastWonDealAt        *time.Time            `json:"last_won_deal_at,omitempty"`
+	LostDealCount        int                   `json:"lost_deal_count"`
+	OpenDealValue        *MoneyResponse        `json:"open_deal_value,omitempty"`
+	DaysSinceLastContact *int                  `json:"days_since_last_contact,omitempty"`
+	AvgDealSize          *MoneyResponse        `json:"avg_deal_size,omitempty"`
+	EngagementScore      int                   `json:"engagement_score"`
+	HealthScore          int                   `json:"health_score"`
+	LastCalculatedAt     *time.Time            `json:"last_calculated_at,omitempty"`
+	PreviousHealthScore  *int                  `json:"previous_health_score,omitempty"`
+	HealthTrend          domain.HealthTrend    `json:"health_trend,omitempty"`
+	HealthFactors        *domain.HealthFactors `json:"health_factors,omitempty"`
+	NotificationOpens    int                   `json:"notification_opens"`
+	NotificationClicks   int                   `json:"notification_clicks"`
+	LastEngagedAt        *time.Time            `json:"last_engaged_at,omitempty"`
+	OverdueInvoiceCount  int                   `json:"overdue_invoice_count"`
 }
 
 // ============================================================================
@@ -342,6 +360,12 @@ type ConvertCustomerRequest struct {
 	Version int    `json:"version" validate:"required,min=1"`
 }
 
+// SetLifecycleRequest represents a manual lifecycle override.
+type SetLifecycleRequest struct {
+	Lifecycle domain.CustomerLifecycle `json:"lifecycle" validate:"required,oneof=prospect active dormant churned"`
+	Reason    string                   `json:"reason,omitempty" validate:"omitempty,max=500"`
+}
+
 // AssignOwnerRequest represents an owner assignment request.
 type AssignOwnerRequest struct {
 	OwnerID uuid.UUID `json:"owner_id" validate:"required"`
@@ -367,6 +391,7 @@ type SearchCustomersRequest struct {
 	Query         string                  `json:"query,omitempty" validate:"omitempty,max=200"`
 	Types         []domain.CustomerType   `json:"types,omitempty"`
 	Statuses      []domain.CustomerStatus `json:"statuses,omitempty"`
+	Lifecycles    []domain.CustomerLifecycle `json:"lifecycles,omitempty"`
 	Tiers         []domain.CustomerTier   `json:"tiers,omitempty"`
 	Sources       []domain.CustomerSource `json:"sources,omitempty"`
 	Tags          []string                `json:"tags,omitempty"`
@@ -374,13 +399,18 @@ type SearchCustomersRequest struct {
 	SegmentIDs    []uuid.UUID             `json:"segment_ids,omitempty"`
 	Industries    []domain.Industry       `json:"industries,omitempty"`
 	Countries     []string                `json:"countries,omitempty"`
+	States        []string                `json:"states,omitempty"`
+	Cities        []string                `json:"cities,omitempty"`
 	CreatedAfter  *time.Time              `json:"created_after,omitempty"`
 	CreatedBefore *time.Time              `json:"created_before,omitempty"`
 	IncludeDeleted bool                   `json:"include_deleted,omitempty"`
 	Offset        int                     `json:"offset,omitempty" validate:"min=0"`
 	Limit         int                     `json:"limit,omitempty" validate:"min=1,max=100"`
+	Cursor        string                  `json:"cursor,omitempty"`
 	SortBy        string                  `json:"sort_by,omitempty" validate:"omitempty,oneof=name created_at updated_at last_contacted_at"`
 	SortOrder     string                  `json:"sort_order,omitempty" validate:"omitempty,oneof=asc desc"`
+	Filter        string                  `json:"filter,omitempty" validate:"omitempty,max=2000"` // e.g. "status:eq:active,tier:in:gold|platinum"
+	Sort          string                  `json:"sort,omitempty" validate:"omitempty,max=200"`    // e.g. "-created_at"; takes precedence over SortBy
 }
 
 // ============================================================================
@@ -398,6 +428,21 @@ type BulkDeleteRequest struct {
 	CustomerIDs []uuid.UUID `json:"customer_ids" validate:"required,min=1,max=100"`
 }
 
+// MaxBatchGetCustomers is the most customers a batch get may request.
+const MaxBatchGetCustomers = 500
+
+// BatchGetCustomersRequest represents a request for several customers by ID.
+type BatchGetCustomersRequest struct {
+	IDs []uuid.UUID `json:"ids" validate:"required,min=1,max=500"`
+}
+
+// BatchGetCustomersResponse represents the customers found by a batch get
+// and the requested IDs that were not found.
+type BatchGetCustomersResponse struct {
+	Found   []*CustomerResponse `json:"found"`
+	Missing []uuid.UUID         `json:"missing"`
+}
+
 // BulkOperationResponse represents a bulk operation result.
 type BulkOperationResponse struct {
 	Processed int      `json:"processed"`
@@ -411,3 +456,130 @@ type MergeCustomersRequest struct {
 	TargetID  uuid.UUID   `json:"target_id" validate:"required"`
 	SourceIDs []uuid.UUID `json:"source_ids" validate:"required,min=1,max=10"`
 }
+
+// ============================================================================
+// Purchase History DTOs
+// ============================================================================
+
+// MaxTopCustomers is the most customers a top customers query may return.
+const MaxTopCustomers = 100
+
+// PurchaseResponse represents a purchase in a customer's purchase history.
+type PurchaseResponse struct {
+	ID            uuid.UUID      `json:"id"`
+	OpportunityID uuid.UUID      `json:"opportunity_id"`
+	Reference     string         `json:"reference,omitempty"`
+	Amount        *MoneyResponse `json:"amount"`
+	PurchasedAt   time.Time      `json:"purchased_at"`
+}
+
+// LoyaltyProgressResponse represents what a customer still needs to reach
+// the next loyalty tier.
+type LoyaltyProgressResponse struct {
+	Tier             domain.CustomerTier `json:"tier"`
+	RevenueRemaining *MoneyResponse      `json:"revenue_remaining"`
+	OrdersRemaining  int                 `json:"orders_remaining"`
+}
+
+// PurchaseHistoryResponse represents a customer's purchase totals and most
+// recent purchases.
+type PurchaseHistoryResponse struct {
+	CustomerID        uuid.UUID                `json:"customer_id"`
+	Tier              domain.CustomerTier      `json:"tier"`
+	TotalRevenue      *MoneyResponse           `json:"total_revenue,omitempty"`
+	OrderCount        int                      `json:"order_count"`
+	LastPurchaseAt    *time.Time               `json:"last_purchase_at,omitempty"`
+	AverageOrderValue *MoneyResponse           `json:"average_order_value,omitempty"`
+	NextTier          *LoyaltyProgressResponse `json:"next_tier,omitempty"`
+	Purchases         []*PurchaseResponse      `json:"purchases"`
+}
+
+// TopCustomerResponse represents a customer's purchase totals for a period.
+type TopCustomerResponse struct {
+	Rank              int                 `json:"rank"`
+	CustomerID        uuid.UUID           `json:"customer_id"`
+	Code              string              `json:"code,omitempty"`
+	Name              string              `json:"name,omitempty"`
+	Tier              domain.CustomerTier `json:"tier,omitempty"`
+	Revenue           *MoneyResponse      `json:"revenue"`
+	OrderCount        int                 `json:"order_count"`
+	AverageOrderValue *MoneyResponse      `json:"average_order_value"`
+	LastPurchaseAt    time.Time           `json:"last_purchase_at"`
+}
+
+// TopCustomersResponse represents the customers with the highest revenue in
+// a period.
+type TopCustomersResponse struct {
+	From      time.Time              `json:"from"`
+	To        time.Time              `json:"to"`
+	Currency  domain.Currency        `json:"currency"`
+	Customers []*TopCustomerResponse `json:"customers"`
+}
+
+// ============================================================================
+// Data Erasure DTOs
+// ============================================================================
+
+// RequestErasureRequest represents a request to erase a customer's personal
+// data.
+type RequestErasureRequest struct {
+	Reason string `json:"reason" validate:"required,max=500"`
+}
+
+// ErasureServiceResponse represents a service's part of an erasure.
+type ErasureServiceResponse struct {
+	Service     string     `json:"service"`
+	Records     int64      `json:"records"`
+	CompletedAt *time.Time `json:"completed_at,omitempty"`
+}
+
+// ErasureResponse represents a customer data erasure request. Once completed
+// it serves as the erasure certificate.
+type ErasureResponse struct {
+	ID                 uuid.UUID                 `json:"id"`
+	CustomerID         uuid.UUID                 `json:"customer_id"`
+	CustomerCode       string                    `json:"customer_code"`
+	Reason             string                    `json:"reason,omitempty"`
+	RequestedBy        *uuid.UUID                `json:"requested_by,omitempty"`
+	Status             domain.ErasureStatus      `json:"status"`
+	Services           []*ErasureServiceResponse `json:"services"`
+	IdentifiersBlocked int                       `json:"identifiers_blocked"`
+	BlockedUntil       time.Time                 `json:"blocked_until"`
+	CreatedAt          time.Time                 `json:"created_at"`
+	CompletedAt        *time.Time                `json:"completed_at,omitempty"`
+}
+
+// ============================================================================
+// Import Upload DTOs
+// ============================================================================
+
+// InitiateUploadRequest represents a request to initiate a resumable import
+// upload.
+type InitiateUploadRequest struct {
+	FileName string `json:"file_name" validate:"required,max=255"`
+	Format   string `json:"format,omitempty" validate:"omitempty,max=10"`
+	Size     int64  `json:"size" validate:"required,gt=0"`
+	Checksum string `json:"checksum" validate:"required,len=64,hexadecimal"`
+}
+
+// CompleteUploadRequest represents a request to complete an import upload
+// and import its file.
+type CompleteUploadRequest struct {
+	FieldMapping   map[string]string `json:"field_mapping,omitempty"`
+	SkipDuplicates bool              `json:"skip_duplicates"`
+	UpdateExisting bool              `json:"update_existing"`
+	DefaultOwner   *uuid.UUID        `json:"default_owner,omitempty"`
+	DefaultTags    []string          `json:"default_tags,omitempty"`
+}
+
+// ============================================================================
+// Demo Data DTOs
+// ============================================================================
+
+// DemoDataResponse represents the outcome of seeding or purging a tenant's
+// demo customers.
+type DemoDataResponse struct {
+	Created  int64 `json:"created"`
+	Existing int64 `json:"existing"`
+	Deleted  int64 `json:"deleted"`
+}
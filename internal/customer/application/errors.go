@@ -30,6 +30,13 @@ const (
 	ErrCodeContactIsBlocked       = "CONTACT_IS_BLOCKED"
 	ErrCodeContactCannotDelete    = "CONTACT_CANNOT_DELETE"
 
+	// Address errors
+	ErrCodeAddressNotFound = "ADDRESS_NOT_FOUND"
+
+	// Erasure errors
+	ErrCodeErasureNotFound = "ERASURE_NOT_FOUND"
+	ErrCodeCustomerErased  = "CUSTOMER_ERASED"
+
 	// Authorization errors
 	ErrCodeUnauthorized     = "UNAUTHORIZED"
 	ErrCodeForbidden        = "FORBIDDEN"
@@ -43,6 +50,14 @@ const (
 	ErrCodeInvalidData       = "INVALID_DATA"
 	ErrCodeFileTooLarge      = "FILE_TOO_LARGE"
 
+	// Import upload errors
+	ErrCodeUploadNotFound         = "UPLOAD_NOT_FOUND"
+	ErrCodeUploadCompleted        = "UPLOAD_COMPLETED"
+	ErrCodeUploadExpired          = "UPLOAD_EXPIRED"
+	ErrCodeUploadOffsetMismatch   = "UPLOAD_OFFSET_MISMATCH"
+	ErrCodeUploadIncomplete       = "UPLOAD_INCOMPLETE"
+	ErrCodeUploadChecksumMismatch = "UPLOAD_CHECKSUM_MISMATCH"
+
 	// General errors
 	ErrCodeInternalError     = "INTERNAL_ERROR"
 	ErrCodeInvalidInput      = "INVALID_INPUT"
@@ -245,6 +260,41 @@ func ErrContactIsBlocked(id uuid.UUID) *ApplicationError {
 	}
 }
 
+// Address Errors
+
+// ErrAddressNotFound creates an address not found error.
+func ErrAddressNotFound(id uuid.UUID) *ApplicationError {
+	return &ApplicationError{
+		Code:       ErrCodeAddressNotFound,
+		Message:    fmt.Sprintf("address not found: %s", id),
+		Details:    map[string]interface{}{"address_id": id},
+		StatusCode: 404,
+	}
+}
+
+// Erasure Errors
+
+// ErrErasureNotFound creates an erasure request not found error.
+func ErrErasureNotFound(id uuid.UUID) *ApplicationError {
+	return &ApplicationError{
+		Code:       ErrCodeErasureNotFound,
+		Message:    fmt.Sprintf("erasure request not found: %s", id),
+		Details:    map[string]interface{}{"erasure_id": id},
+		StatusCode: 404,
+	}
+}
+
+// ErrCustomerErased creates an error for a customer whose personal data was
+// already erased.
+func ErrCustomerErased(id uuid.UUID) *ApplicationError {
+	return &ApplicationError{
+		Code:       ErrCodeCustomerErased,
+		Message:    fmt.Sprintf("customer personal data has already been erased: %s", id),
+		Details:    map[string]interface{}{"customer_id": id},
+		StatusCode: 409,
+	}
+}
+
 // Authorization Errors
 
 // ErrUnauthorized creates an unauthorized error.
@@ -327,6 +377,71 @@ func ErrFileTooLarge(size, maxSize int64) *ApplicationError {
 	}
 }
 
+// Import Upload Errors
+
+// ErrUploadNotFound creates an import upload not found error.
+func ErrUploadNotFound(id uuid.UUID) *ApplicationError {
+	return &ApplicationError{
+		Code:       ErrCodeUploadNotFound,
+		Message:    fmt.Sprintf("import upload not found: %s", id),
+		Details:    map[string]interface{}{"upload_id": id},
+		StatusCode: 404,
+	}
+}
+
+// ErrUploadCompleted creates an error for an upload already handed to its
+// import.
+func ErrUploadCompleted(id uuid.UUID, importID *uuid.UUID) *ApplicationError {
+	return &ApplicationError{
+		Code:       ErrCodeUploadCompleted,
+		Message:    fmt.Sprintf("import upload is already completed: %s", id),
+		Details:    map[string]interface{}{"upload_id": id, "import_id": importID},
+		StatusCode: 409,
+	}
+}
+
+// ErrUploadExpired creates an error for an upload abandoned for too long.
+func ErrUploadExpired(id uuid.UUID) *ApplicationError {
+	return &ApplicationError{
+		Code:       ErrCodeUploadExpired,
+		Message:    fmt.Sprintf("import upload has expired: %s", id),
+		Details:    map[string]interface{}{"upload_id": id},
+		StatusCode: 410,
+	}
+}
+
+// ErrUploadOffsetMismatch creates an error for a chunk that does not start
+// where the bytes received end.
+func ErrUploadOffsetMismatch(offset, received int64) *ApplicationError {
+	return &ApplicationError{
+		Code:       ErrCodeUploadOffsetMismatch,
+		Message:    fmt.Sprintf("chunk offset %d does not match the %d bytes received", offset, received),
+		Details:    map[string]interface{}{"offset": offset, "received": received},
+		StatusCode: 409,
+	}
+}
+
+// ErrUploadIncomplete creates an error for completing an upload before every
+// byte was received.
+func ErrUploadIncomplete(received, size int64) *ApplicationError {
+	return &ApplicationError{
+		Code:       ErrCodeUploadIncomplete,
+		Message:    fmt.Sprintf("received %d of %d bytes", received, size),
+		Details:    map[string]interface{}{"received": received, "size": size},
+		StatusCode: 409,
+	}
+}
+
+// ErrUploadChecksumMismatch creates an error for a chunk or file that does not
+// match its checksum.
+func ErrUploadChecksumMismatch(what string) *ApplicationError {
+	return &ApplicationError{
+		Code:       ErrCodeUploadChecksumMismatch,
+		Message:    fmt.Sprintf("%s does not match its checksum", what),
+		StatusCode: 400,
+	}
+}
+
 // General Errors
 
 // ErrInternalError creates an internal error.
@@ -385,7 +500,8 @@ func ErrServiceUnavailable(service string) *ApplicationError {
 // IsNotFoundError checks if the error is a not found error.
 func IsNotFoundError(err error) bool {
 	if appErr, ok := err.(*ApplicationError); ok {
-		return appErr.Code == ErrCodeCustomerNotFound || appErr.Code == ErrCodeContactNotFound
+		return appErr.Code == ErrCodeCustomerNotFound || appErr.Code == ErrCodeContactNotFound ||
+			appErr.Code == ErrCodeUploadNotFound
 	}
 	return false
 }
@@ -398,7 +514,11 @@ func IsConflictError(err error) bool {
 			appErr.Code == ErrCodeCustomerVersionConflict ||
 			appErr.Code == ErrCodeContactVersionConflict ||
 			appErr.Code == ErrCodeCustomerDuplicate ||
-			appErr.Code == ErrCodeContactDuplicate
+			appErr.Code == ErrCodeContactDuplicate ||
+			appErr.Code == ErrCodeCustomerErased ||
+			appErr.Code == ErrCodeUploadCompleted ||
+			appErr.Code == ErrCodeUploadOffsetMismatch ||
+			appErr.Code == ErrCodeUploadIncomplete
 	}
 	return false
 }
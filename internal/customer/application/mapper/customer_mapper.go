@@ -29,42 +29,46 @@ func (m *CustomerMapper) ToResponse(customer *domain.Customer) *dto.CustomerResp
 	}
 
 	response := &dto.CustomerResponse{
-		ID:              customer.ID,
-		TenantID:        customer.TenantID,
-		Code:            customer.Code,
-		Name:            customer.Name,
-		Type:            customer.Type,
-		Status:          customer.Status,
-		Tier:            customer.Tier,
-		Source:          customer.Source,
-		Email:           customer.Email.String(),
-		PhoneNumbers:    m.phonesToResponse(customer.PhoneNumbers),
-		Website:         customer.Website.String(),
-		Addresses:       m.addressesToResponse(customer.Addresses),
-		SocialProfiles:  m.socialProfilesToResponse(customer.SocialProfiles),
-		CompanyInfo:     m.companyInfoToResponse(customer.CompanyInfo),
-		Financials:      m.financialsToResponse(&customer.Financials),
-		Preferences:     m.preferencesToResponse(&customer.Preferences),
-		Stats:           m.statsToResponse(&customer.Stats),
-		Contacts:        m.contactSummariesToResponse(customer.Contacts),
-		OwnerID:         customer.OwnerID,
-		AssignedTeam:    customer.AssignedTeam,
-		Tags:            customer.Tags,
-		Segments:        customer.Segments,
-		CustomFields:    customer.CustomFields,
-		Notes:           customer.Notes,
-		LogoURL:         customer.LogoURL,
-		LastContactedAt: customer.LastContactedAt,
-		NextFollowUpAt:  customer.NextFollowUpAt,
-		ConvertedAt:     customer.ConvertedAt,
-		ChurnedAt:       customer.ChurnedAt,
-		ChurnReason:     customer.ChurnReason,
-		Version:         customer.Version,
-		CreatedAt:       customer.CreatedAt,
-		UpdatedAt:       customer.UpdatedAt,
-		CreatedBy:       customer.AuditInfo.CreatedBy,
-		UpdatedBy:       customer.AuditInfo.UpdatedBy,
-	}
+		ID:                 customer.ID,
+		TenantID:           customer.TenantID,
+		Code:               customer.Code,
+		Name:               customer.Name,
+		Type:               customer.Type,
+		Status:             customer.Status,
+		Tier:               customer.Tier,
+		Source:             customer.Source,
+		Email:              customer.Email.String(),
+		PhoneNumbers:       m.phonesToResponse(customer.PhoneNumbers),
+		Website:            customer.Website.String(),
+		Addresses:          m.addressesToResponse(customer.Addresses),
+		SocialProfiles:     m.socialProfilesToResponse(customer.SocialProfiles),
+		CompanyInfo:        m.companyInfoToResponse(customer.CompanyInfo),
+		Financials:         m.financialsToResponse(&customer.Financials),
+		Preferences:        m.preferencesToResponse(&customer.Preferences),
+		Stats:              m.statsToResponse(&customer.Stats),
+		Contacts:           m.contactSummariesToResponse(customer.Contacts),
+		OwnerID:            customer.OwnerID,
+		AssignedTeam:       customer.AssignedTeam,
+		Tags:               customer.Tags,
+		Segments:           customer.Segments,
+		CustomFields:       customer.CustomFields,
+		Notes:              customer.Notes,
+		LogoURL:            customer.LogoURL,
+		LastContactedAt:    customer.LastContactedAt,
+		NextFollowUpAt:     customer.NextFollowUpAt,
+		ConvertedAt:        customer.ConvertedAt,
+		ChurnedAt:          customer.ChurnedAt,
+		ChurnReason:        customer.ChurnReason,
+		Lifecycle:          customer.CurrentLifecycle(),
+		LifecycleChangedAt: customer.LifecycleChangedAt,
+		LifecycleOverride:  customer.LifecycleOverride,
+		Version:            customer.Version,
+		CreatedAt:          customer.CreatedAt,
+		UpdatedAt:          customer.UpdatedAt,
+		CreatedBy:          customer.AuditInfo.CreatedBy,
+		UpdatedBy:          customer.AuditInfo.UpdatedBy,
+	}
+	response.Stats.OverdueInvoiceCount = len(customer.OverdueInvoices)
 
 	return response
 }
@@ -86,6 +90,9 @@ func (m *CustomerMapper) ToSummaryResponse(customer *domain.Customer) *dto.Custo
 		Name:            customer.Name,
 		Type:            customer.Type,
 		Status:          customer.Status,
+		Lifecycle:       customer.CurrentLifecycle(),
+		HealthScore:     customer.Stats.HealthScore,
+		HealthTrend:     customer.Stats.HealthTrend,
 		Tier:            customer.Tier,
 		Email:           customer.Email.String(),
 		Phone:           phone,
@@ -147,6 +154,7 @@ func (m *CustomerMapper) addressesToResponse(addresses []domain.Address) []dto.A
 	responses := make([]dto.AddressResponse, len(addresses))
 	for i, addr := range addresses {
 		responses[i] = dto.AddressResponse{
+			ID:          UUIDPtr(addr.ID),
 			Line1:       addr.Line1,
 			Line2:       addr.Line2,
 			Line3:       addr.Line3,
@@ -285,11 +293,18 @@ func (m *CustomerMapper) statsToResponse(stats *domain.CustomerStats) dto.Custom
 		ActivityCount:        stats.ActivityCount,
 		DealCount:            stats.DealCount,
 		WonDealCount:         stats.WonDealCount,
+		LastWonDealAt:        stats.LastWonDealAt,
 		LostDealCount:        stats.LostDealCount,
 		DaysSinceLastContact: stats.DaysSinceLastContact,
 		EngagementScore:      stats.EngagementScore,
 		HealthScore:          stats.HealthScore,
 		LastCalculatedAt:     stats.LastCalculatedAt,
+		PreviousHealthScore:  stats.PreviousHealthScore,
+		HealthTrend:          stats.HealthTrend,
+		HealthFactors:        stats.HealthFactors,
+		NotificationOpens:    stats.NotificationOpens,
+		NotificationClicks:   stats.NotificationClicks,
+		LastEngagedAt:        stats.LastEngagedAt,
 	}
 
 	if stats.OpenDealValue != nil {
@@ -544,13 +559,13 @@ func (m *CustomerMapper) AddressInputToDomain(input *dto.AddressInput) (domain.A
 	}
 
 	// Set optional fields
-	addr.Line2 = input.Line2
-	addr.Line3 = input.Line3
-	addr.State = input.State
+	addr = addr.WithDetails(input.Line2, input.Line3, input.State).WithLabel(input.Label)
 	addr.IsPrimary = input.IsPrimary
-	addr.Label = input.Label
+	if input.Latitude != nil && input.Longitude != nil {
+		addr = addr.WithCoordinates(*input.Latitude, *input.Longitude)
+	}
 
-	return addr, nil
+	return addr.NormalizeRegion()
 }
 
 // SocialProfileInputToDomain converts SocialProfileInput DTO to domain SocialProfile.
@@ -573,6 +588,7 @@ func (m *CustomerMapper) SearchRequestToFilter(req *dto.SearchCustomersRequest)
 		Query:          req.Query,
 		Types:          req.Types,
 		Statuses:       req.Statuses,
+		Lifecycles:     req.Lifecycles,
 		Tiers:          req.Tiers,
 		Sources:        req.Sources,
 		Tags:           req.Tags,
@@ -580,6 +596,8 @@ func (m *CustomerMapper) SearchRequestToFilter(req *dto.SearchCustomersRequest)
 		SegmentIDs:     req.SegmentIDs,
 		Industries:     req.Industries,
 		Countries:      req.Countries,
+		States:         normalizeStates(req.States),
+		Cities:         req.Cities,
 		CreatedAfter:   req.CreatedAfter,
 		CreatedBefore:  req.CreatedBefore,
 		IncludeDeleted: req.IncludeDeleted,
@@ -588,6 +606,19 @@ func (m *CustomerMapper) SearchRequestToFilter(req *dto.SearchCustomersRequest)
 	return filter
 }
 
+// normalizeStates gives Malaysian states in a filter their canonical names,
+// as stored on addresses, so "penang" finds "Pulau Pinang".
+func normalizeStates(states []string) []string {
+	normalized := make([]string, 0, len(states))
+	for _, state := range states {
+		if s, ok := domain.FindMalaysianState(state); ok {
+			state = s.Name
+		}
+		normalized = append(normalized, state)
+	}
+	return normalized
+}
+
 // PaginationFromRequest extracts pagination from search request.
 func (m *CustomerMapper) PaginationFromRequest(req *dto.SearchCustomersRequest) (offset, limit int, sortBy, sortOrder string) {
 	offset = req.Offset
@@ -665,3 +696,11 @@ func BoolPtr(b bool) *bool {
 func StringPtr(s string) *string {
 	return &s
 }
+
+// UUIDPtr returns a pointer to the given UUID, or nil for the nil UUID.
+func UUIDPtr(id uuid.UUID) *uuid.UUID {
+	if id == uuid.Nil {
+		return nil
+	}
+	return &id
+}
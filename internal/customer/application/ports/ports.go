@@ -162,6 +162,21 @@ type FileStorage interface {
 	GetSignedURL(ctx context.Context, url string, expiry time.Duration) (string, error)
 }
 
+// UploadStorage defines the interface for storing the chunks of import
+// uploads until the file is complete.
+type UploadStorage interface {
+	// WriteAt writes a chunk at the offset of the upload's file, creating
+	// the file with the first chunk.
+	WriteAt(ctx context.Context, uploadID uuid.UUID, offset int64, data []byte) error
+
+	// Open opens the upload's file for reading.
+	Open(ctx context.Context, uploadID uuid.UUID) (io.ReadCloser, error)
+
+	// Delete deletes the upload's file. Deleting a missing file is not an
+	// error.
+	Delete(ctx context.Context, uploadID uuid.UUID) error
+}
+
 // ============================================================================
 // Export/Import Ports
 // ============================================================================
@@ -0,0 +1,296 @@
+// Package usecase contains the application use cases for the Customer service.
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/kilang-desa-murni/crm/internal/customer/application"
+	"github.com/kilang-desa-murni/crm/internal/customer/application/dto"
+	"github.com/kilang-desa-murni/crm/internal/customer/application/mapper"
+	"github.com/kilang-desa-murni/crm/internal/customer/application/ports"
+	"github.com/kilang-desa-murni/crm/internal/customer/domain"
+)
+
+// ============================================================================
+// Customer Address Use Cases
+// ============================================================================
+
+// CustomerAddressUseCase manages the address book of a customer: billing,
+// shipping, outlet and other addresses. Addresses without coordinates are
+// geocoded when a geocoder is configured, so territory assignment can place
+// them; an address that cannot be geocoded is saved without coordinates.
+type CustomerAddressUseCase struct {
+	uow            domain.UnitOfWork
+	idGenerator    ports.IDGenerator
+	cache          ports.CacheService
+	auditLogger    ports.AuditLogger
+	geocoder       ports.GeocodingService
+	customerMapper *mapper.CustomerMapper
+}
+
+// NewCustomerAddressUseCase creates a new CustomerAddressUseCase. The
+// geocoder may be nil.
+func NewCustomerAddressUseCase(
+	uow domain.UnitOfWork,
+	idGenerator ports.IDGenerator,
+	cache ports.CacheService,
+	auditLogger ports.AuditLogger,
+	geocoder ports.GeocodingService,
+) *CustomerAddressUseCase {
+	return &CustomerAddressUseCase{
+		uow:            uow,
+		idGenerator:    idGenerator,
+		cache:          cache,
+		auditLogger:    auditLogger,
+		geocoder:       geocoder,
+		customerMapper: mapper.NewCustomerMapper(),
+	}
+}
+
+// CustomerAddressInput holds input for an address operation.
+type CustomerAddressInput struct {
+	TenantID   uuid.UUID
+	UserID     uuid.UUID
+	CustomerID uuid.UUID
+	AddressID  uuid.UUID
+	Request    *dto.AddressInput
+	IPAddress  string
+	UserAgent  string
+}
+
+// List returns the customer's addresses.
+func (uc *CustomerAddressUseCase) List(ctx context.Context, input CustomerAddressInput) ([]dto.AddressResponse, error) {
+	customer, err := uc.findCustomer(ctx, input)
+	if err != nil {
+		return nil, err
+	}
+	return uc.customerMapper.ToResponse(customer).Addresses, nil
+}
+
+// Add adds an address to the customer.
+func (uc *CustomerAddressUseCase) Add(ctx context.Context, input CustomerAddressInput) (*dto.CustomerResponse, error) {
+	if input.Request == nil {
+		return nil, application.ErrInvalidInput("request is required")
+	}
+	customer, err := uc.findCustomer(ctx, input)
+	if err != nil {
+		return nil, err
+	}
+
+	address, err := uc.toAddress(ctx, input.Request)
+	if err != nil {
+		return nil, err
+	}
+	address.ID = uc.idGenerator.NewID()
+	if err := customer.AddAddress(address); err != nil {
+		return nil, addressError(err, input.AddressID)
+	}
+
+	if err := uc.save(ctx, input, customer, "customer.address_added", nil, customer.GetAddress(address.ID)); err != nil {
+		return nil, err
+	}
+	return uc.customerMapper.ToResponse(customer), nil
+}
+
+// Update replaces one of the customer's addresses.
+func (uc *CustomerAddressUseCase) Update(ctx context.Context, input CustomerAddressInput) (*dto.CustomerResponse, error) {
+	if input.Request == nil {
+		return nil, application.ErrInvalidInput("request is required")
+	}
+	if input.AddressID == uuid.Nil {
+		return nil, application.ErrInvalidInput("address_id is required")
+	}
+	customer, err := uc.findCustomer(ctx, input)
+	if err != nil {
+		return nil, err
+	}
+	existing := customer.GetAddress(input.AddressID)
+	if existing == nil {
+		return nil, application.ErrAddressNotFound(input.AddressID)
+	}
+	old := *existing
+
+	address, err := uc.toAddress(ctx, input.Request)
+	if err != nil {
+		return nil, err
+	}
+	address.IsVerified = old.IsVerified && address.Equals(old)
+	if err := customer.UpdateAddress(input.AddressID, address); err != nil {
+		return nil, addressError(err, input.AddressID)
+	}
+
+	if err := uc.save(ctx, input, customer, "customer.address_updated", &old, customer.GetAddress(input.AddressID)); err != nil {
+		return nil, err
+	}
+	return uc.customerMapper.ToResponse(customer), nil
+}
+
+// Remove removes one of the customer's addresses.
+func (uc *CustomerAddressUseCase) Remove(ctx context.Context, input CustomerAddressInput) (*dto.CustomerResponse, error) {
+	if input.AddressID == uuid.Nil {
+		return nil, application.ErrInvalidInput("address_id is required")
+	}
+	customer, err := uc.findCustomer(ctx, input)
+	if err != nil {
+		return nil, err
+	}
+	existing := customer.GetAddress(input.AddressID)
+	if existing == nil {
+		return nil, application.ErrAddressNotFound(input.AddressID)
+	}
+	old := *existing
+
+	if err := customer.RemoveAddress(input.AddressID); err != nil {
+		return nil, addressError(err, input.AddressID)
+	}
+
+	if err := uc.save(ctx, input, customer, "customer.address_removed", &old, nil); err != nil {
+		return nil, err
+	}
+	return uc.customerMapper.ToResponse(customer), nil
+}
+
+// findCustomer finds the customer of an address operation in the caller's
+// tenant.
+func (uc *CustomerAddressUseCase) findCustomer(ctx context.Context, input CustomerAddressInput) (*domain.Customer, error) {
+	if input.TenantID == uuid.Nil {
+		return nil, application.ErrInvalidInput("tenant_id is required")
+	}
+	if input.CustomerID == uuid.Nil {
+		return nil, application.ErrInvalidInput("customer_id is required")
+	}
+
+	customer, err := uc.uow.Customers().FindByID(ctx, input.CustomerID)
+	if err != nil {
+		if domain.IsNotFoundError(err) {
+			return nil, application.ErrCustomerNotFound(input.CustomerID)
+		}
+		return nil, application.ErrInternalError("failed to find customer", err)
+	}
+	if customer.TenantID != input.TenantID {
+		return nil, application.ErrTenantMismatch(input.TenantID, customer.TenantID)
+	}
+	customer.EnsureAddressIDs()
+	return customer, nil
+}
+
+// toAddress validates an address input and geocodes it when it has no
+// coordinates.
+func (uc *CustomerAddressUseCase) toAddress(ctx context.Context, input *dto.AddressInput) (domain.Address, error) {
+	address, err := uc.customerMapper.AddressInputToDomain(input)
+	if err != nil {
+		var verrs domain.ValidationErrors
+		if errors.As(err, &verrs) {
+			return domain.Address{}, application.ErrCustomerValidation("invalid address", map[string]interface{}{
+				"errors": verrs.Error(),
+			})
+		}
+		return domain.Address{}, application.ErrInternalError("failed to build address", err)
+	}
+
+	if uc.geocoder != nil && !address.HasCoordinates() {
+		if location, err := uc.geocoder.Geocode(ctx, address.SingleLine()); err == nil && location != nil {
+			address = address.WithCoordinates(location.Latitude, location.Longitude)
+		}
+	}
+	return address, nil
+}
+
+// save persists the customer with its domain events and records the address
+// change in the audit log.
+func (uc *CustomerAddressUseCase) save(ctx context.Context, input CustomerAddressInput, customer *domain.Customer, action string, oldAddress, newAddress *domain.Address) error {
+	customer.AuditInfo.SetUpdatedBy(input.UserID)
+
+	txCtx, err := uc.uow.Begin(ctx)
+	if err != nil {
+		return application.ErrInternalError("failed to begin transaction", err)
+	}
+	defer uc.uow.Rollback(txCtx)
+
+	if err := uc.uow.Customers().Update(txCtx, customer); err != nil {
+		if domain.IsConflictError(err) {
+			return application.ErrCustomerVersionConflict(customer.ID, customer.Version-1, customer.Version)
+		}
+		return application.ErrInternalError("failed to update customer", err)
+	}
+
+	for _, event := range customer.DomainEvents() {
+		payload, err := json.Marshal(event)
+		if err != nil {
+			return application.ErrInternalError("failed to serialize event", err)
+		}
+
+		outboxEntry := &domain.OutboxEntry{
+			ID:          uc.idGenerator.NewID(),
+			TenantID:    input.TenantID,
+			EventType:   event.EventType(),
+			AggregateID: customer.ID,
+			Payload:     payload,
+			CreatedAt:   time.Now().UTC(),
+		}
+
+		if err := uc.uow.Outbox().Create(txCtx, outboxEntry); err != nil {
+			return application.ErrInternalError("failed to save outbox entry", err)
+		}
+	}
+
+	if err := uc.uow.Commit(txCtx); err != nil {
+		return application.ErrInternalError("failed to commit transaction", err)
+	}
+	customer.ClearDomainEvents()
+
+	if uc.cache != nil {
+		_ = uc.cache.Invalidate(ctx, "customer", customer.ID)
+	}
+
+	if uc.auditLogger != nil {
+		_ = uc.auditLogger.LogAction(ctx, ports.AuditEntry{
+			ID:         uc.idGenerator.NewID(),
+			TenantID:   input.TenantID,
+			UserID:     &input.UserID,
+			Action:     action,
+			EntityType: "customer",
+			EntityID:   customer.ID,
+			OldValue:   addressToAuditMap(oldAddress),
+			NewValue:   addressToAuditMap(newAddress),
+			IPAddress:  input.IPAddress,
+			UserAgent:  input.UserAgent,
+			Timestamp:  time.Now().UTC(),
+		})
+	}
+	return nil
+}
+
+// addressToAuditMap converts an address to a map for audit logging.
+func addressToAuditMap(address *domain.Address) map[string]interface{} {
+	if address == nil {
+		return nil
+	}
+	return map[string]interface{}{
+		"id":           address.ID,
+		"address_type": address.AddressType,
+		"is_primary":   address.IsPrimary,
+		"address":      address.SingleLine(),
+		"state":        address.State,
+		"postal_code":  address.PostalCode,
+	}
+}
+
+// addressError maps a domain address error to an application error.
+func addressError(err error, addressID uuid.UUID) error {
+	switch {
+	case errors.Is(err, domain.ErrAddressNotFound):
+		return application.ErrAddressNotFound(addressID)
+	case errors.Is(err, domain.ErrMaxAddressesExceeded):
+		return application.ErrCustomerValidation("customer has reached the maximum number of addresses", map[string]interface{}{
+			"max_addresses": domain.MaxAddresses,
+		})
+	default:
+		return application.ErrInternalError("failed to change address", err)
+	}
+}
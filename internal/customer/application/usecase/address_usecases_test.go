@@ -0,0 +1,178 @@
+// Package usecase contains the application use cases for the Customer service.
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"github.com/kilang-desa-murni/crm/internal/customer/application"
+	"github.com/kilang-desa-murni/crm/internal/customer/application/dto"
+	"github.com/kilang-desa-murni/crm/internal/customer/application/ports"
+	"github.com/kilang-desa-murni/crm/internal/customer/domain"
+)
+
+// ============================================================================
+// CustomerAddressUseCase Tests
+// ============================================================================
+
+type stubGeocoder struct {
+	ports.GeocodingService
+	location *ports.GeoLocation
+	err      error
+}
+
+func (g *stubGeocoder) Geocode(ctx context.Context, address string) (*ports.GeoLocation, error) {
+	return g.location, g.err
+}
+
+func newTestAddressUseCase(geocoder ports.GeocodingService) (*CustomerAddressUseCase, *MockUnitOfWork) {
+	uow := NewMockUnitOfWork()
+	uc := NewCustomerAddressUseCase(uow, NewMockIDGenerator(), NewMockCustomerCacheService(), NewMockCustomerAuditLogger(), geocoder)
+	return uc, uow
+}
+
+func TestCustomerAddressUseCase_Add(t *testing.T) {
+	geocoder := &stubGeocoder{location: &ports.GeoLocation{Latitude: 3.139, Longitude: 101.6869}}
+	uc, uow := newTestAddressUseCase(geocoder)
+	tenantID := uuid.New()
+	customer := createTestCustomerForUpdate(tenantID)
+	uow.customerRepo.customers[customer.ID] = customer
+
+	result, err := uc.Add(context.Background(), CustomerAddressInput{
+		TenantID:   tenantID,
+		UserID:     uuid.New(),
+		CustomerID: customer.ID,
+		Request: &dto.AddressInput{
+			Line1:       "Lot 12, Jalan Bukit Bintang",
+			City:        "Kuala Lumpur",
+			State:       "wp kuala lumpur",
+			PostalCode:  "55100",
+			CountryCode: "MY",
+			AddressType: domain.AddressTypeOutlet,
+		},
+	})
+	if err != nil {
+		t.Fatalf("Add() error = %v", err)
+	}
+
+	if len(result.Addresses) != 1 {
+		t.Fatalf("Addresses count = %d, want 1", len(result.Addresses))
+	}
+	address := result.Addresses[0]
+	if address.ID == nil || address.State != "Kuala Lumpur" || !address.IsPrimary {
+		t.Errorf("address = %+v, want an ID, the canonical state and primary", address)
+	}
+	if address.Latitude == nil || *address.Latitude != 3.139 {
+		t.Error("an address without coordinates should be geocoded")
+	}
+}
+
+func TestCustomerAddressUseCase_Add_GeocodingFailure(t *testing.T) {
+	uc, uow := newTestAddressUseCase(&stubGeocoder{err: errors.New("quota exceeded")})
+	tenantID := uuid.New()
+	customer := createTestCustomerForUpdate(tenantID)
+	uow.customerRepo.customers[customer.ID] = customer
+
+	result, err := uc.Add(context.Background(), CustomerAddressInput{
+		TenantID:   tenantID,
+		UserID:     uuid.New(),
+		CustomerID: customer.ID,
+		Request: &dto.AddressInput{
+			Line1:       "22 Jalan Tok Hakim",
+			City:        "Kota Bharu",
+			State:       "Kelantan",
+			PostalCode:  "15000",
+			CountryCode: "MY",
+		},
+	})
+	if err != nil {
+		t.Fatalf("Add() error = %v", err)
+	}
+	if result.Addresses[0].Latitude != nil {
+		t.Error("an address that cannot be geocoded should be saved without coordinates")
+	}
+}
+
+func TestCustomerAddressUseCase_Add_PostcodeOutsideState(t *testing.T) {
+	uc, uow := newTestAddressUseCase(nil)
+	tenantID := uuid.New()
+	customer := createTestCustomerForUpdate(tenantID)
+	uow.customerRepo.customers[customer.ID] = customer
+
+	_, err := uc.Add(context.Background(), CustomerAddressInput{
+		TenantID:   tenantID,
+		UserID:     uuid.New(),
+		CustomerID: customer.ID,
+		Request: &dto.AddressInput{
+			Line1:       "1 Jalan Merdeka",
+			City:        "Melaka",
+			State:       "Melaka",
+			PostalCode:  "50000",
+			CountryCode: "MY",
+		},
+	})
+
+	var appErr *application.ApplicationError
+	if !errors.As(err, &appErr) || appErr.Code != application.ErrCodeCustomerValidation {
+		t.Errorf("Add() error = %v, want a validation error", err)
+	}
+}
+
+func TestCustomerAddressUseCase_UpdateAndRemove(t *testing.T) {
+	uc, uow := newTestAddressUseCase(nil)
+	tenantID := uuid.New()
+	customer := createTestCustomerForUpdate(tenantID)
+	addr, _ := domain.NewAddress("3 Jalan Ampang", "Kuala Lumpur", "50450", "MY", domain.AddressTypeBilling)
+	customer.Addresses = []domain.Address{addr}
+	uow.customerRepo.customers[customer.ID] = customer
+
+	addresses, err := uc.List(context.Background(), CustomerAddressInput{TenantID: tenantID, CustomerID: customer.ID})
+	if err != nil || len(addresses) != 1 || addresses[0].ID == nil {
+		t.Fatalf("List() = %+v, %v; want the stored address with an ID", addresses, err)
+	}
+	addressID := *addresses[0].ID
+
+	result, err := uc.Update(context.Background(), CustomerAddressInput{
+		TenantID:   tenantID,
+		UserID:     uuid.New(),
+		CustomerID: customer.ID,
+		AddressID:  addressID,
+		Request: &dto.AddressInput{
+			Line1:       "3 Jalan Ampang",
+			City:        "Kuala Lumpur",
+			State:       "Kuala Lumpur",
+			PostalCode:  "50450",
+			CountryCode: "MY",
+			AddressType: domain.AddressTypeShipping,
+		},
+	})
+	if err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+	if result.Addresses[0].AddressType != domain.AddressTypeShipping || *result.Addresses[0].ID != addressID {
+		t.Errorf("address = %+v, want a shipping address with the same ID", result.Addresses[0])
+	}
+
+	if _, err := uc.Remove(context.Background(), CustomerAddressInput{
+		TenantID:   tenantID,
+		UserID:     uuid.New(),
+		CustomerID: customer.ID,
+		AddressID:  addressID,
+	}); err != nil {
+		t.Fatalf("Remove() error = %v", err)
+	}
+
+	_, err = uc.Remove(context.Background(), CustomerAddressInput{
+		TenantID:   tenantID,
+		UserID:     uuid.New(),
+		CustomerID: customer.ID,
+		AddressID:  addressID,
+	})
+	var appErr *application.ApplicationError
+	if !errors.As(err, &appErr) || appErr.Code != application.ErrCodeAddressNotFound {
+		t.Errorf("Remove(removed) error = %v, want address not found", err)
+	}
+}
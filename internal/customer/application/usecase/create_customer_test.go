@@ -4,6 +4,7 @@ package usecase
 import (
 	"context"
 	"errors"
+	"sort"
 	"testing"
 	"time"
 
@@ -88,7 +89,13 @@ func (m *MockCustomerRepository) FindByEmail(ctx context.Context, tenantID uuid.
 }
 
 func (m *MockCustomerRepository) List(ctx context.Context, filter domain.CustomerFilter) (*domain.CustomerList, error) {
-	return &domain.CustomerList{Customers: []*domain.Customer{}}, nil
+	customers := []*domain.Customer{}
+	for _, id := range filter.IDs {
+		if customer, ok := m.customers[id]; ok {
+			customers = append(customers, customer)
+		}
+	}
+	return &domain.CustomerList{Customers: customers, Total: int64(len(customers))}, nil
 }
 
 func (m *MockCustomerRepository) Search(ctx context.Context, tenantID uuid.UUID, query string, filter domain.CustomerFilter) (*domain.CustomerList, error) {
@@ -96,7 +103,13 @@ func (m *MockCustomerRepository) Search(ctx context.Context, tenantID uuid.UUID,
 }
 
 func (m *MockCustomerRepository) FindByOwner(ctx context.Context, tenantID, ownerID uuid.UUID, filter domain.CustomerFilter) (*domain.CustomerList, error) {
-	return &domain.CustomerList{}, nil
+	customers := []*domain.Customer{}
+	for _, c := range m.customers {
+		if c.TenantID == tenantID && c.OwnerID != nil && *c.OwnerID == ownerID {
+			customers = append(customers, c)
+		}
+	}
+	return &domain.CustomerList{Customers: customers, Total: int64(len(customers))}, nil
 }
 
 func (m *MockCustomerRepository) FindByTag(ctx context.Context, tenantID uuid.UUID, tag string, filter domain.CustomerFilter) (*domain.CustomerList, error) {
@@ -181,6 +194,26 @@ func (m *MockCustomerRepository) FindRecentlyUpdated(ctx context.Context, tenant
 	return nil, nil
 }
 
+func (m *MockCustomerRepository) FindHealthDue(ctx context.Context, calculatedBefore time.Time, limit int) ([]*domain.Customer, error) {
+	var customers []*domain.Customer
+	for _, c := range m.customers {
+		if c.Stats.LastCalculatedAt == nil || c.Stats.LastCalculatedAt.Before(calculatedBefore) {
+			customers = append(customers, c)
+		}
+	}
+	return customers, nil
+}
+
+func (m *MockCustomerRepository) FindLifecycleDue(ctx context.Context, activeBefore time.Time, limit int) ([]*domain.Customer, error) {
+	var customers []*domain.Customer
+	for _, c := range m.customers {
+		if c.Lifecycle == "" || (c.Lifecycle == domain.LifecycleActive && !c.LifecycleOverride) {
+			customers = append(customers, c)
+		}
+	}
+	return customers, nil
+}
+
 // MockContactRepository is a mock implementation of domain.ContactRepository.
 type MockContactRepository struct{}
 
@@ -208,6 +241,10 @@ func (m *MockContactRepository) CountByCustomer(ctx context.Context, customerID
 	return 0, nil
 }
 
+func (m *MockContactRepository) PurgeByCustomer(ctx context.Context, customerID uuid.UUID) (int64, error) {
+	return 0, nil
+}
+
 // MockOutboxRepository is a mock implementation of domain.OutboxRepository.
 type MockCustomerOutboxRepository struct {
 	entries   []*domain.OutboxEntry
@@ -267,6 +304,9 @@ func (m *MockNoteRepository) FindByCustomerID(ctx context.Context, customerID uu
 func (m *MockNoteRepository) CountByCustomer(ctx context.Context, customerID uuid.UUID) (int, error) {
 	return 0, nil
 }
+func (m *MockNoteRepository) PurgeByCustomer(ctx context.Context, customerID uuid.UUID) (int64, error) {
+	return 0, nil
+}
 
 // MockActivityRepository is a mock implementation
 type MockActivityRepository struct{}
@@ -300,6 +340,9 @@ func (m *MockActivityRepository) CountByCustomer(ctx context.Context, customerID
 func (m *MockActivityRepository) GetActivitySummary(ctx context.Context, customerID uuid.UUID) (map[domain.ActivityType]int, error) {
 	return nil, nil
 }
+func (m *MockActivityRepository) AnonymizeByCustomer(ctx context.Context, customerID uuid.UUID) (int64, error) {
+	return 0, nil
+}
 
 // MockSegmentRepository is a mock implementation
 type MockSegmentRepository struct{}
@@ -349,6 +392,116 @@ func (m *MockImportRepository) FindImportErrors(ctx context.Context, importID uu
 	return nil, nil
 }
 
+// MockPurchaseRepository is a mock implementation of domain.PurchaseRepository.
+type MockPurchaseRepository struct {
+	purchases []*domain.Purchase
+}
+
+func NewMockPurchaseRepository() *MockPurchaseRepository {
+	return &MockPurchaseRepository{}
+}
+
+func (m *MockPurchaseRepository) Create(ctx context.Context, purchase *domain.Purchase) error {
+	for _, p := range m.purchases {
+		if p.TenantID == purchase.TenantID && p.OpportunityID == purchase.OpportunityID {
+			return domain.ErrPurchaseAlreadyRecorded
+		}
+	}
+	m.purchases = append(m.purchases, purchase)
+	return nil
+}
+func (m *MockPurchaseRepository) FindByCustomer(ctx context.Context, customerID uuid.UUID, limit int) ([]*domain.Purchase, error) {
+	var result []*domain.Purchase
+	for i := len(m.purchases) - 1; i >= 0 && len(result) < limit; i-- {
+		if m.purchases[i].CustomerID == customerID {
+			result = append(result, m.purchases[i])
+		}
+	}
+	return result, nil
+}
+func (m *MockPurchaseRepository) TopCustomers(ctx context.Context, tenantID uuid.UUID, currency domain.Currency, from, to time.Time, limit int) ([]*domain.CustomerRevenue, error) {
+	totals := make(map[uuid.UUID]*domain.CustomerRevenue)
+	var result []*domain.CustomerRevenue
+	for _, p := range m.purchases {
+		if p.TenantID != tenantID || p.Amount.Currency != currency || p.PurchasedAt.Before(from) || !p.PurchasedAt.Before(to) {
+			continue
+		}
+		total, ok := totals[p.CustomerID]
+		if !ok {
+			total = &domain.CustomerRevenue{CustomerID: p.CustomerID, Revenue: domain.Money{Currency: currency}}
+			totals[p.CustomerID] = total
+			result = append(result, total)
+		}
+		total.Revenue.Amount += p.Amount.Amount
+		total.OrderCount++
+		if p.PurchasedAt.After(total.LastPurchaseAt) {
+			total.LastPurchaseAt = p.PurchasedAt
+		}
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Revenue.Amount > result[j].Revenue.Amount })
+	if len(result) > limit {
+		result = result[:limit]
+	}
+	return result, nil
+}
+
+// MockErasureRepository is a mock implementation of domain.ErasureRepository.
+type MockErasureRepository struct {
+	requests map[uuid.UUID]*domain.ErasureRequest
+}
+
+func NewMockErasureRepository() *MockErasureRepository {
+	return &MockErasureRepository{requests: make(map[uuid.UUID]*domain.ErasureRequest)}
+}
+
+func (m *MockErasureRepository) Create(ctx context.Context, request *domain.ErasureRequest) error {
+	m.requests[request.ID] = request
+	return nil
+}
+func (m *MockErasureRepository) Update(ctx context.Context, request *domain.ErasureRequest) error {
+	if _, ok := m.requests[request.ID]; !ok {
+		return domain.ErrErasureNotFound
+	}
+	request.Version++
+	m.requests[request.ID] = request
+	return nil
+}
+func (m *MockErasureRepository) FindByID(ctx context.Context, id uuid.UUID) (*domain.ErasureRequest, error) {
+	request, ok := m.requests[id]
+	if !ok {
+		return nil, domain.ErrErasureNotFound
+	}
+	return request, nil
+}
+
+// MockErasedIdentifierRepository is a mock implementation of domain.ErasedIdentifierRepository.
+type MockErasedIdentifierRepository struct {
+	identifiers []*domain.ErasedIdentifier
+}
+
+func NewMockErasedIdentifierRepository() *MockErasedIdentifierRepository {
+	return &MockErasedIdentifierRepository{}
+}
+
+func (m *MockErasedIdentifierRepository) Block(ctx context.Context, identifiers []*domain.ErasedIdentifier) error {
+	m.identifiers = append(m.identifiers, identifiers...)
+	return nil
+}
+func (m *MockErasedIdentifierRepository) FindBlocked(ctx context.Context, tenantID uuid.UUID, hashes []string, now time.Time) ([]string, error) {
+	var blocked []string
+	for _, identifier := range m.identifiers {
+		if identifier.TenantID != tenantID || !identifier.ExpiresAt.After(now) {
+			continue
+		}
+		for _, hash := range hashes {
+			if identifier.Hash == hash {
+				blocked = append(blocked, hash)
+			}
+		}
+	}
+	return blocked, nil
+}
+
 // MockUnitOfWork is a mock implementation of domain.UnitOfWork.
 type MockUnitOfWork struct {
 	customerRepo *MockCustomerRepository
@@ -358,6 +511,9 @@ type MockUnitOfWork struct {
 	segmentRepo  *MockSegmentRepository
 	outboxRepo   *MockCustomerOutboxRepository
 	importRepo   *MockImportRepository
+	purchaseRepo *MockPurchaseRepository
+	erasureRepo  *MockErasureRepository
+	erasedIDRepo *MockErasedIdentifierRepository
 	beginErr     error
 	commitErr    error
 }
@@ -371,6 +527,9 @@ func NewMockUnitOfWork() *MockUnitOfWork {
 		segmentRepo:  NewMockSegmentRepository(),
 		outboxRepo:   NewMockCustomerOutboxRepository(),
 		importRepo:   NewMockImportRepository(),
+		purchaseRepo: NewMockPurchaseRepository(),
+		erasureRepo:  NewMockErasureRepository(),
+		erasedIDRepo: NewMockErasedIdentifierRepository(),
 	}
 }
 
@@ -389,6 +548,17 @@ func (m *MockUnitOfWork) Rollback(ctx context.Context) error {
 	return nil
 }
 
+func (m *MockUnitOfWork) WithTransaction(ctx context.Context, fn func(context.Context) error) error {
+	txCtx, err := m.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	if err := fn(txCtx); err != nil {
+		return err
+	}
+	return m.Commit(txCtx)
+}
+
 func (m *MockUnitOfWork) Customers() domain.CustomerRepository {
 	return m.customerRepo
 }
@@ -417,6 +587,18 @@ func (m *MockUnitOfWork) Imports() domain.ImportRepository {
 	return m.importRepo
 }
 
+func (m *MockUnitOfWork) Purchases() domain.PurchaseRepository {
+	return m.purchaseRepo
+}
+
+func (m *MockUnitOfWork) Erasures() domain.ErasureRepository {
+	return m.erasureRepo
+}
+
+func (m *MockUnitOfWork) ErasedIdentifiers() domain.ErasedIdentifierRepository {
+	return m.erasedIDRepo
+}
+
 // MockCustomerEventPublisher is a mock implementation of ports.EventPublisher.
 type MockCustomerEventPublisher struct {
 	publishedEvents []domain.DomainEvent
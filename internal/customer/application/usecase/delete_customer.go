@@ -4,6 +4,7 @@ package usecase
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	"time"
 
 	"github.com/google/uuid"
@@ -425,6 +426,86 @@ func (uc *GetCustomerUseCase) Execute(ctx context.Context, input GetCustomerInpu
 	return uc.customerMapper.ToResponse(customer), nil
 }
 
+// ============================================================================
+// Batch Get Customers Use Case
+// ============================================================================
+
+// BatchGetCustomersUseCase handles getting several customers by ID at once.
+type BatchGetCustomersUseCase struct {
+	uow            domain.UnitOfWork
+	customerMapper *mapper.CustomerMapper
+}
+
+// NewBatchGetCustomersUseCase creates a new BatchGetCustomersUseCase.
+func NewBatchGetCustomersUseCase(uow domain.UnitOfWork) *BatchGetCustomersUseCase {
+	return &BatchGetCustomersUseCase{
+		uow:            uow,
+		customerMapper: mapper.NewCustomerMapper(),
+	}
+}
+
+// BatchGetCustomersInput holds input for getting several customers.
+type BatchGetCustomersInput struct {
+	TenantID uuid.UUID
+	Request  *dto.BatchGetCustomersRequest
+}
+
+// Execute gets the requested customers. Customers that do not exist or
+// belong to another tenant are reported as missing.
+func (uc *BatchGetCustomersUseCase) Execute(ctx context.Context, input BatchGetCustomersInput) (*dto.BatchGetCustomersResponse, error) {
+	if input.TenantID == uuid.Nil {
+		return nil, application.ErrInvalidInput("tenant_id is required")
+	}
+	if input.Request == nil {
+		return nil, application.ErrInvalidInput("ids is required")
+	}
+
+	ids := make([]uuid.UUID, 0, len(input.Request.IDs))
+	seen := make(map[uuid.UUID]bool, len(input.Request.IDs))
+	for _, id := range input.Request.IDs {
+		if id != uuid.Nil && !seen[id] {
+			seen[id] = true
+			ids = append(ids, id)
+		}
+	}
+	if len(ids) == 0 {
+		return nil, application.ErrInvalidInput("ids is required")
+	}
+	if len(ids) > dto.MaxBatchGetCustomers {
+		return nil, application.ErrInvalidInput(fmt.Sprintf("at most %d ids may be requested", dto.MaxBatchGetCustomers))
+	}
+
+	result, err := uc.uow.Customers().List(ctx, domain.CustomerFilter{
+		TenantID: &input.TenantID,
+		IDs:      ids,
+		Limit:    len(ids),
+	})
+	if err != nil {
+		return nil, application.ErrInternalError("failed to find customers", err)
+	}
+
+	byID := make(map[uuid.UUID]*domain.Customer, len(result.Customers))
+	for _, customer := range result.Customers {
+		if customer.TenantID == input.TenantID {
+			byID[customer.ID] = customer
+		}
+	}
+
+	response := &dto.BatchGetCustomersResponse{
+		Found:   make([]*dto.CustomerResponse, 0, len(byID)),
+		Missing: []uuid.UUID{},
+	}
+	for _, id := range ids {
+		if customer, ok := byID[id]; ok {
+			response.Found = append(response.Found, uc.customerMapper.ToResponse(customer))
+		} else {
+			response.Missing = append(response.Missing, id)
+		}
+	}
+
+	return response, nil
+}
+
 // ============================================================================
 // Get Customer By Code Use Case
 // ============================================================================
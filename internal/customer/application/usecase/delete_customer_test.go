@@ -897,6 +897,70 @@ func TestGetCustomerByCodeUseCase_Execute_MissingCode(t *testing.T) {
 	}
 }
 
+// ============================================================================
+// BatchGetCustomersUseCase Tests
+// ============================================================================
+
+func TestBatchGetCustomersUseCase_Execute_Success(t *testing.T) {
+	// Arrange
+	uow := NewMockUnitOfWork()
+	uc := NewBatchGetCustomersUseCase(uow)
+
+	tenantID := uuid.New()
+	first := createTestCustomerForDelete(tenantID)
+	second := createTestCustomerForDelete(tenantID)
+	other := createTestCustomerForDelete(uuid.New())
+	for _, c := range []*domain.Customer{first, second, other} {
+		uow.customerRepo.customers[c.ID] = c
+	}
+	unknown := uuid.New()
+
+	input := BatchGetCustomersInput{
+		TenantID: tenantID,
+		Request: &dto.BatchGetCustomersRequest{
+			IDs: []uuid.UUID{second.ID, unknown, first.ID, other.ID, second.ID},
+		},
+	}
+
+	// Act
+	result, err := uc.Execute(context.Background(), input)
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if len(result.Found) != 2 || result.Found[0].ID != second.ID || result.Found[1].ID != first.ID {
+		t.Errorf("Expected customers in request order, got %+v", result.Found)
+	}
+	if len(result.Missing) != 2 || result.Missing[0] != unknown || result.Missing[1] != other.ID {
+		t.Errorf("Expected unknown and other-tenant customers missing, got %v", result.Missing)
+	}
+}
+
+func TestBatchGetCustomersUseCase_Execute_TooManyIDs(t *testing.T) {
+	// Arrange
+	uow := NewMockUnitOfWork()
+	uc := NewBatchGetCustomersUseCase(uow)
+
+	ids := make([]uuid.UUID, dto.MaxBatchGetCustomers+1)
+	for i := range ids {
+		ids[i] = uuid.New()
+	}
+
+	input := BatchGetCustomersInput{
+		TenantID: uuid.New(),
+		Request:  &dto.BatchGetCustomersRequest{IDs: ids},
+	}
+
+	// Act
+	_, err := uc.Execute(context.Background(), input)
+
+	// Assert
+	if err == nil {
+		t.Fatal("Expected error for too many ids, got nil")
+	}
+}
+
 // ============================================================================
 // Table-Driven Tests for Delete Validation
 // ============================================================================
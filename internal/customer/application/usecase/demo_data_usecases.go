@@ -0,0 +1,126 @@
+// Package usecase contains the application use cases for the Customer service.
+package usecase
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+
+	"github.com/kilang-desa-murni/crm/internal/customer/application"
+	"github.com/kilang-desa-murni/crm/internal/customer/application/dto"
+	"github.com/kilang-desa-murni/crm/internal/customer/domain"
+	"github.com/kilang-desa-murni/crm/pkg/demodata"
+)
+
+// ============================================================================
+// Demo Data Use Case
+// ============================================================================
+
+// DemoDataUseCase seeds and purges a tenant's demo customers and their
+// contacts. Seeded customers publish no events, so they never reach the
+// other services or their notifications.
+type DemoDataUseCase struct {
+	customers domain.CustomerRepository
+	demoRepo  domain.DemoDataRepository
+}
+
+// NewDemoDataUseCase creates a new DemoDataUseCase.
+func NewDemoDataUseCase(customers domain.CustomerRepository, demoRepo domain.DemoDataRepository) *DemoDataUseCase {
+	return &DemoDataUseCase{
+		customers: customers,
+		demoRepo:  demoRepo,
+	}
+}
+
+// Seed creates the demo customers of the tenant that do not exist yet.
+func (uc *DemoDataUseCase) Seed(ctx context.Context, tenantID, userID uuid.UUID) (*dto.DemoDataResponse, error) {
+	ds := demodata.For(tenantID)
+
+	existing, err := uc.demoRepo.FindExisting(ctx, tenantID, customerDemoIDs(ds))
+	if err != nil {
+		return nil, application.ErrInternalError("failed to find demo customers", err)
+	}
+
+	result := &dto.DemoDataResponse{}
+	for _, fixture := range ds.Customers {
+		if existing[fixture.ID] {
+			result.Existing++
+			continue
+		}
+
+		customer, err := newDemoCustomer(tenantID, userID, fixture)
+		if err != nil {
+			return nil, application.ErrInternalError("failed to build demo customer", err)
+		}
+		if err := uc.customers.Create(ctx, customer); err != nil {
+			return nil, application.ErrInternalError("failed to create demo customer", err)
+		}
+		result.Created++
+	}
+
+	return result, nil
+}
+
+// Purge deletes the demo customers of the tenant with their contacts.
+func (uc *DemoDataUseCase) Purge(ctx context.Context, tenantID uuid.UUID) (*dto.DemoDataResponse, error) {
+	deleted, err := uc.demoRepo.Purge(ctx, tenantID, customerDemoIDs(demodata.For(tenantID)))
+	if err != nil {
+		return nil, application.ErrInternalError("failed to purge demo customers", err)
+	}
+	return &dto.DemoDataResponse{Deleted: deleted}, nil
+}
+
+// customerDemoIDs returns the IDs of the demo customers.
+func customerDemoIDs(ds *demodata.DataSet) []uuid.UUID {
+	ids := make([]uuid.UUID, len(ds.Customers))
+	for i, c := range ds.Customers {
+		ids[i] = c.ID
+	}
+	return ids
+}
+
+// newDemoCustomer builds a demo customer with its primary contact, giving
+// both their demo IDs.
+func newDemoCustomer(tenantID, userID uuid.UUID, fixture demodata.Customer) (*domain.Customer, error) {
+	customer, err := domain.NewCustomerBuilder(tenantID, fixture.Name, domain.CustomerTypeCompany).
+		WithCode(fixture.Code).
+		WithEmail(fixture.Email).
+		WithPhone(fixture.Phone, domain.PhoneTypeWork, true).
+		WithWebsite(fixture.Website).
+		WithAddress(fixture.Street, fixture.City, fixture.PostalCode, "MY", domain.AddressTypeOffice).
+		WithCompanyInfo(domain.CompanyInfo{LegalName: fixture.Name, Industry: domain.Industry(fixture.Industry)}).
+		WithTags(fixture.Tags...).
+		WithCreatedBy(userID).
+		Build()
+	if err != nil {
+		return nil, err
+	}
+	customer.ID = fixture.ID
+
+	for i := range customer.Addresses {
+		customer.Addresses[i].State = fixture.State
+		address, err := customer.Addresses[i].NormalizeRegion()
+		if err != nil {
+			return nil, fmt.Errorf("invalid address of %s: %w", fixture.Code, err)
+		}
+		customer.Addresses[i] = address
+	}
+
+	contact, err := domain.NewContactBuilder(customer.ID, tenantID).
+		WithName(fixture.FirstName, fixture.LastName).
+		WithEmail(fixture.ContactMail).
+		WithJobTitle(fixture.JobTitle).
+		WithCreatedBy(userID).
+		Build()
+	if err != nil {
+		return nil, err
+	}
+	contact.ID = fixture.ContactID
+	if err := customer.AddContact(contact); err != nil {
+		return nil, err
+	}
+
+	customer.ClearDomainEvents()
+	return customer, nil
+}
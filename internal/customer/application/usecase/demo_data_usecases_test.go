@@ -0,0 +1,115 @@
+// Package usecase contains the application use cases for the Customer service.
+package usecase
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"github.com/kilang-desa-murni/crm/pkg/demodata"
+)
+
+// ============================================================================
+// DemoDataUseCase Tests
+// ============================================================================
+
+// MockDemoDataRepository is a mock implementation of domain.DemoDataRepository
+// backed by a MockCustomerRepository.
+type MockDemoDataRepository struct {
+	customers *MockCustomerRepository
+}
+
+func (m *MockDemoDataRepository) FindExisting(ctx context.Context, tenantID uuid.UUID, ids []uuid.UUID) (map[uuid.UUID]bool, error) {
+	existing := make(map[uuid.UUID]bool)
+	for _, id := range ids {
+		if c, ok := m.customers.customers[id]; ok && c.TenantID == tenantID {
+			existing[id] = true
+		}
+	}
+	return existing, nil
+}
+
+func (m *MockDemoDataRepository) Purge(ctx context.Context, tenantID uuid.UUID, ids []uuid.UUID) (int64, error) {
+	var deleted int64
+	for _, id := range ids {
+		if c, ok := m.customers.customers[id]; ok && c.TenantID == tenantID {
+			delete(m.customers.customers, id)
+			deleted++
+		}
+	}
+	return deleted, nil
+}
+
+func newTestDemoDataUseCase() (*DemoDataUseCase, *MockCustomerRepository) {
+	customers := NewMockCustomerRepository()
+	return NewDemoDataUseCase(customers, &MockDemoDataRepository{customers: customers}), customers
+}
+
+func TestDemoDataUseCase_Seed(t *testing.T) {
+	uc, customers := newTestDemoDataUseCase()
+	tenantID := uuid.New()
+	ds := demodata.For(tenantID)
+
+	result, err := uc.Seed(context.Background(), tenantID, uuid.New())
+	if err != nil {
+		t.Fatalf("Seed() error = %v", err)
+	}
+	if result.Created != int64(len(ds.Customers)) {
+		t.Errorf("Seed() created %d customers, want %d", result.Created, len(ds.Customers))
+	}
+
+	for _, fixture := range ds.Customers {
+		customer := customers.customers[fixture.ID]
+		if customer == nil {
+			t.Fatalf("customer %s was not created", fixture.Code)
+		}
+		if len(customer.Contacts) != 1 || customer.Contacts[0].ID != fixture.ContactID {
+			t.Errorf("customer %s does not have its demo contact", fixture.Code)
+		}
+		if len(customer.DomainEvents()) != 0 {
+			t.Errorf("customer %s has events to publish", fixture.Code)
+		}
+	}
+}
+
+func TestDemoDataUseCase_Seed_IsIdempotent(t *testing.T) {
+	uc, customers := newTestDemoDataUseCase()
+	tenantID := uuid.New()
+	ds := demodata.For(tenantID)
+
+	if _, err := uc.Seed(context.Background(), tenantID, uuid.New()); err != nil {
+		t.Fatalf("Seed() error = %v", err)
+	}
+	delete(customers.customers, ds.Customers[0].ID)
+
+	result, err := uc.Seed(context.Background(), tenantID, uuid.New())
+	if err != nil {
+		t.Fatalf("Seed() error = %v", err)
+	}
+	if result.Created != 1 || result.Existing != int64(len(ds.Customers)-1) {
+		t.Errorf("Seed() = %+v, want 1 created and %d existing", result, len(ds.Customers)-1)
+	}
+}
+
+func TestDemoDataUseCase_Purge(t *testing.T) {
+	uc, customers := newTestDemoDataUseCase()
+	tenantID := uuid.New()
+
+	if _, err := uc.Seed(context.Background(), tenantID, uuid.New()); err != nil {
+		t.Fatalf("Seed() error = %v", err)
+	}
+	own := createTestCustomerForUpdate(tenantID)
+	customers.customers[own.ID] = own
+
+	result, err := uc.Purge(context.Background(), tenantID)
+	if err != nil {
+		t.Fatalf("Purge() error = %v", err)
+	}
+	if result.Deleted != int64(len(demodata.For(tenantID).Customers)) {
+		t.Errorf("Purge() deleted %d customers", result.Deleted)
+	}
+	if len(customers.customers) != 1 || customers.customers[own.ID] == nil {
+		t.Error("Purge() should delete only the demo customers")
+	}
+}
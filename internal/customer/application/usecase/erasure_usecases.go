@@ -0,0 +1,401 @@
+// Package usecase contains the application use cases for the Customer service.
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/kilang-desa-murni/crm/internal/customer/application"
+	"github.com/kilang-desa-murni/crm/internal/customer/application/dto"
+	"github.com/kilang-desa-murni/crm/internal/customer/application/ports"
+	"github.com/kilang-desa-murni/crm/internal/customer/domain"
+)
+
+const (
+	// maxErasureReasonLength is the longest reason an erasure may be given.
+	maxErasureReasonLength = 500
+
+	// maxErasureConfirmAttempts is how many times a service's confirmation
+	// is applied before giving up when confirmations race.
+	maxErasureConfirmAttempts = 3
+)
+
+// ============================================================================
+// Customer Erasure Use Cases
+// ============================================================================
+
+// ErasureConfig holds configuration for customer data erasure.
+type ErasureConfig struct {
+	// ReimportBlockPeriod is how long an erased customer's email addresses
+	// and phone numbers are blocked from being imported again.
+	ReimportBlockPeriod time.Duration
+	// Services are the other services that erase their copies of the
+	// customer's data and confirm it.
+	Services []string
+}
+
+// DefaultErasureConfig returns the default erasure configuration.
+func DefaultErasureConfig() ErasureConfig {
+	return ErasureConfig{
+		ReimportBlockPeriod: 2 * 365 * 24 * time.Hour,
+		Services:            []string{domain.ErasureServiceSales, domain.ErasureServiceNotification},
+	}
+}
+
+// CustomerErasureUseCase erases customers' personal data on request (the
+// right to be forgotten). The customer service anonymizes the customer and
+// deletes their contacts and notes in one transaction, then publishes a
+// customer.erased event on which the other services erase their copies and
+// confirm. The erasure certificate is written to the audit log once every
+// service has confirmed.
+type CustomerErasureUseCase struct {
+	uow         domain.UnitOfWork
+	idGenerator ports.IDGenerator
+	cache       ports.CacheService
+	auditLogger ports.AuditLogger
+	config      ErasureConfig
+}
+
+// NewCustomerErasureUseCase creates a new CustomerErasureUseCase.
+func NewCustomerErasureUseCase(
+	uow domain.UnitOfWork,
+	idGenerator ports.IDGenerator,
+	cache ports.CacheService,
+	auditLogger ports.AuditLogger,
+	config ErasureConfig,
+) *CustomerErasureUseCase {
+	if config.ReimportBlockPeriod <= 0 {
+		config.ReimportBlockPeriod = DefaultErasureConfig().ReimportBlockPeriod
+	}
+
+	return &CustomerErasureUseCase{
+		uow:         uow,
+		idGenerator: idGenerator,
+		cache:       cache,
+		auditLogger: auditLogger,
+		config:      config,
+	}
+}
+
+// RequestErasureInput holds input for requesting a customer data erasure.
+type RequestErasureInput struct {
+	TenantID    uuid.UUID
+	CustomerID  uuid.UUID
+	Reason      string
+	RequestedBy *uuid.UUID
+	IPAddress   string
+	UserAgent   string
+}
+
+// Request erases the customer's personal data in the customer service and
+// starts the erasure in the other services. The customer's code,
+// financials and statistics are kept.
+func (uc *CustomerErasureUseCase) Request(ctx context.Context, input RequestErasureInput) (*dto.ErasureResponse, error) {
+	if input.TenantID == uuid.Nil {
+		return nil, application.ErrInvalidInput("tenant_id is required")
+	}
+	if input.CustomerID == uuid.Nil {
+		return nil, application.ErrInvalidInput("customer_id is required")
+	}
+	input.Reason = strings.TrimSpace(input.Reason)
+	if input.Reason == "" {
+		return nil, application.ErrInvalidInput("reason is required")
+	}
+	if len(input.Reason) > maxErasureReasonLength {
+		return nil, application.ErrInvalidInput(fmt.Sprintf("reason must be at most %d characters", maxErasureReasonLength))
+	}
+
+	customer, err := uc.uow.Customers().FindByID(ctx, input.CustomerID)
+	if err != nil {
+		if domain.IsNotFoundError(err) {
+			return nil, application.ErrCustomerNotFound(input.CustomerID)
+		}
+		return nil, application.ErrInternalError("failed to find customer", err)
+	}
+	if customer.TenantID != input.TenantID {
+		return nil, application.ErrTenantMismatch(input.TenantID, customer.TenantID)
+	}
+
+	now := time.Now().UTC()
+	request := domain.NewErasureRequest(customer, input.Reason, input.RequestedBy, uc.config.Services, now.Add(uc.config.ReimportBlockPeriod))
+
+	hashes, err := customer.Anonymize(request.ID, now)
+	if err != nil {
+		if errors.Is(err, domain.ErrCustomerErased) {
+			return nil, application.ErrCustomerErased(customer.ID)
+		}
+		return nil, application.ErrInternalError("failed to anonymize customer", err)
+	}
+
+	txCtx, err := uc.uow.Begin(ctx)
+	if err != nil {
+		return nil, application.ErrInternalError("failed to begin transaction", err)
+	}
+	defer uc.uow.Rollback(txCtx)
+
+	contacts, err := uc.uow.Contacts().PurgeByCustomer(txCtx, customer.ID)
+	if err != nil {
+		return nil, application.ErrInternalError("failed to erase contacts", err)
+	}
+	notes, err := uc.uow.Notes().PurgeByCustomer(txCtx, customer.ID)
+	if err != nil {
+		return nil, application.ErrInternalError("failed to erase notes", err)
+	}
+	activities, err := uc.uow.Activities().AnonymizeByCustomer(txCtx, customer.ID)
+	if err != nil {
+		return nil, application.ErrInternalError("failed to anonymize activities", err)
+	}
+
+	if err := uc.uow.Customers().Update(txCtx, customer); err != nil {
+		if domain.IsConflictError(err) {
+			return nil, application.ErrCustomerVersionConflict(customer.ID, customer.Version-1, customer.Version)
+		}
+		return nil, application.ErrInternalError("failed to update customer", err)
+	}
+
+	identifiers := make([]*domain.ErasedIdentifier, 0, len(hashes))
+	for _, hash := range hashes {
+		identifiers = append(identifiers, &domain.ErasedIdentifier{
+			ID:        uc.idGenerator.NewID(),
+			TenantID:  customer.TenantID,
+			Hash:      hash,
+			ErasureID: request.ID,
+			ExpiresAt: request.BlockedUntil,
+			CreatedAt: now,
+		})
+	}
+	if err := uc.uow.ErasedIdentifiers().Block(txCtx, identifiers); err != nil {
+		return nil, application.ErrInternalError("failed to block erased identifiers", err)
+	}
+	request.IdentifiersBlocked = len(identifiers)
+
+	completed, err := request.CompleteService(domain.ErasureServiceCustomer, 1+contacts+notes+activities, now)
+	if err != nil {
+		return nil, application.ErrInternalError("failed to record erasure", err)
+	}
+	if err := uc.uow.Erasures().Create(txCtx, request); err != nil {
+		return nil, application.ErrInternalError("failed to create erasure request", err)
+	}
+
+	for _, event := range customer.DomainEvents() {
+		payload, err := json.Marshal(event)
+		if err != nil {
+			return nil, application.ErrInternalError("failed to serialize event", err)
+		}
+
+		outboxEntry := &domain.OutboxEntry{
+			ID:          uc.idGenerator.NewID(),
+			TenantID:    customer.TenantID,
+			EventType:   event.EventType(),
+			AggregateID: customer.ID,
+			Payload:     payload,
+			CreatedAt:   now,
+		}
+
+		if err := uc.uow.Outbox().Create(txCtx, outboxEntry); err != nil {
+			return nil, application.ErrInternalError("failed to save outbox entry", err)
+		}
+	}
+
+	if err := uc.uow.Commit(txCtx); err != nil {
+		return nil, application.ErrInternalError("failed to commit transaction", err)
+	}
+	customer.ClearDomainEvents()
+
+	if uc.cache != nil {
+		_ = uc.cache.Invalidate(ctx, "customer", customer.ID)
+	}
+
+	// The audit entries describe the erasure without the erased data
+	if uc.auditLogger != nil {
+		_ = uc.auditLogger.LogAction(ctx, ports.AuditEntry{
+			ID:         uc.idGenerator.NewID(),
+			TenantID:   input.TenantID,
+			UserID:     input.RequestedBy,
+			Action:     "customer.erasure_requested",
+			EntityType: "customer",
+			EntityID:   customer.ID,
+			Metadata: map[string]interface{}{
+				"erasure_id":          request.ID,
+				"reason":              request.Reason,
+				"pending_services":    request.PendingServices(),
+				"identifiers_blocked": request.IdentifiersBlocked,
+				"blocked_until":       request.BlockedUntil,
+			},
+			IPAddress: input.IPAddress,
+			UserAgent: input.UserAgent,
+			Timestamp: now,
+		})
+	}
+	if completed {
+		uc.logCertificate(ctx, request)
+	}
+
+	return erasureToResponse(request), nil
+}
+
+// GetErasureInput holds input for getting an erasure request.
+type GetErasureInput struct {
+	TenantID   uuid.UUID
+	CustomerID uuid.UUID
+	ErasureID  uuid.UUID
+}
+
+// Get returns an erasure request and the progress of each service.
+func (uc *CustomerErasureUseCase) Get(ctx context.Context, input GetErasureInput) (*dto.ErasureResponse, error) {
+	if input.TenantID == uuid.Nil {
+		return nil, application.ErrInvalidInput("tenant_id is required")
+	}
+
+	request, err := uc.uow.Erasures().FindByID(ctx, input.ErasureID)
+	if err != nil {
+		if domain.IsNotFoundError(err) {
+			return nil, application.ErrErasureNotFound(input.ErasureID)
+		}
+		return nil, application.ErrInternalError("failed to find erasure request", err)
+	}
+	if request.TenantID != input.TenantID || request.CustomerID != input.CustomerID {
+		return nil, application.ErrErasureNotFound(input.ErasureID)
+	}
+
+	return erasureToResponse(request), nil
+}
+
+// ConfirmErasureInput holds a service's confirmation of its part of an
+// erasure.
+type ConfirmErasureInput struct {
+	TenantID  uuid.UUID
+	ErasureID uuid.UUID
+	Service   string
+	Records   int64
+	ErasedAt  time.Time
+}
+
+// ConfirmService records that a service erased its copy of the customer's
+// data, and writes the erasure certificate to the audit log once every
+// service has confirmed. It is driven by the services' confirmation events:
+// repeated confirmations and unknown erasures are ignored rather than
+// reported.
+func (uc *CustomerErasureUseCase) ConfirmService(ctx context.Context, input ConfirmErasureInput) error {
+	if input.ErasedAt.IsZero() {
+		input.ErasedAt = time.Now().UTC()
+	}
+
+	// Services confirm concurrently, so a confirmation that loses the race
+	// is applied again to the updated request
+	for attempt := 1; ; attempt++ {
+		request, err := uc.uow.Erasures().FindByID(ctx, input.ErasureID)
+		if err != nil {
+			if domain.IsNotFoundError(err) {
+				return nil
+			}
+			return application.ErrInternalError("failed to find erasure request", err)
+		}
+		if request.TenantID != input.TenantID {
+			return application.ErrTenantMismatch(input.TenantID, request.TenantID)
+		}
+		if request.ServiceCompleted(input.Service) {
+			return nil
+		}
+
+		completed, err := request.CompleteService(input.Service, input.Records, input.ErasedAt)
+		if err != nil {
+			return application.ErrInvalidInput(fmt.Sprintf("service %q is not part of erasure %s", input.Service, input.ErasureID))
+		}
+
+		if err := uc.uow.Erasures().Update(ctx, request); err != nil {
+			if errors.Is(err, domain.ErrVersionConflict) && attempt < maxErasureConfirmAttempts {
+				continue
+			}
+			return application.ErrInternalError("failed to update erasure request", err)
+		}
+
+		if uc.auditLogger != nil {
+			_ = uc.auditLogger.LogAction(ctx, ports.AuditEntry{
+				ID:         uc.idGenerator.NewID(),
+				TenantID:   request.TenantID,
+				Action:     "customer.erasure_service_completed",
+				EntityType: "customer",
+				EntityID:   request.CustomerID,
+				Metadata: map[string]interface{}{
+					"erasure_id": request.ID,
+					"service":    input.Service,
+					"records":    input.Records,
+				},
+				Timestamp: time.Now().UTC(),
+			})
+		}
+		if completed {
+			uc.logCertificate(ctx, request)
+		}
+		return nil
+	}
+}
+
+// logCertificate writes the erasure certificate of a completed erasure to
+// the audit log.
+func (uc *CustomerErasureUseCase) logCertificate(ctx context.Context, request *domain.ErasureRequest) {
+	if uc.auditLogger == nil {
+		return
+	}
+
+	services := make([]map[string]interface{}, 0, len(request.Services))
+	for _, status := range request.Services {
+		services = append(services, map[string]interface{}{
+			"service":      status.Service,
+			"records":      status.Records,
+			"completed_at": status.CompletedAt,
+		})
+	}
+
+	_ = uc.auditLogger.LogAction(ctx, ports.AuditEntry{
+		ID:         uc.idGenerator.NewID(),
+		TenantID:   request.TenantID,
+		UserID:     request.RequestedBy,
+		Action:     "customer.erasure_certificate",
+		EntityType: "customer",
+		EntityID:   request.CustomerID,
+		NewValue: map[string]interface{}{
+			"erasure_id":          request.ID,
+			"customer_code":       request.CustomerCode,
+			"reason":              request.Reason,
+			"requested_at":        request.CreatedAt,
+			"completed_at":        request.CompletedAt,
+			"services":            services,
+			"identifiers_blocked": request.IdentifiersBlocked,
+			"blocked_until":       request.BlockedUntil,
+		},
+		Timestamp: time.Now().UTC(),
+	})
+}
+
+// erasureToResponse converts an erasure request to its response.
+func erasureToResponse(request *domain.ErasureRequest) *dto.ErasureResponse {
+	response := &dto.ErasureResponse{
+		ID:                 request.ID,
+		CustomerID:         request.CustomerID,
+		CustomerCode:       request.CustomerCode,
+		Reason:             request.Reason,
+		RequestedBy:        request.RequestedBy,
+		Status:             request.Status,
+		Services:           make([]*dto.ErasureServiceResponse, 0, len(request.Services)),
+		IdentifiersBlocked: request.IdentifiersBlocked,
+		BlockedUntil:       request.BlockedUntil,
+		CreatedAt:          request.CreatedAt,
+		CompletedAt:        request.CompletedAt,
+	}
+	for _, status := range request.Services {
+		response.Services = append(response.Services, &dto.ErasureServiceResponse{
+			Service:     status.Service,
+			Records:     status.Records,
+			CompletedAt: status.CompletedAt,
+		})
+	}
+	return response
+}
@@ -0,0 +1,171 @@
+// Package usecase contains the application use cases for the Customer service.
+package usecase
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/kilang-desa-murni/crm/internal/customer/application"
+	"github.com/kilang-desa-murni/crm/internal/customer/application/ports"
+	"github.com/kilang-desa-murni/crm/internal/customer/domain"
+)
+
+// ============================================================================
+// CustomerErasureUseCase Tests
+// ============================================================================
+
+func newTestErasureUseCase() (*CustomerErasureUseCase, *MockUnitOfWork, *MockCustomerAuditLogger) {
+	uow := NewMockUnitOfWork()
+	auditLogger := NewMockCustomerAuditLogger()
+	uc := NewCustomerErasureUseCase(uow, NewMockIDGenerator(), NewMockCustomerCacheService(), auditLogger, DefaultErasureConfig())
+	return uc, uow, auditLogger
+}
+
+func auditActions(logger *MockCustomerAuditLogger) []string {
+	actions := make([]string, 0, len(logger.entries))
+	for _, entry := range logger.entries {
+		actions = append(actions, entry.Action)
+	}
+	return actions
+}
+
+func TestCustomerErasureUseCase_Request(t *testing.T) {
+	uc, uow, auditLogger := newTestErasureUseCase()
+	customer := createTestCustomerForUpdate(uuid.New())
+	customer.ClearDomainEvents()
+	uow.customerRepo.customers[customer.ID] = customer
+	userID := uuid.New()
+
+	erasure, err := uc.Request(context.Background(), RequestErasureInput{
+		TenantID:    customer.TenantID,
+		CustomerID:  customer.ID,
+		Reason:      "Customer asked to be forgotten",
+		RequestedBy: &userID,
+	})
+	if err != nil {
+		t.Fatalf("Request() error = %v", err)
+	}
+
+	saved := uow.customerRepo.customers[customer.ID]
+	if !saved.IsErased() || saved.Email.String() != "" || saved.Name == "Test Customer" {
+		t.Errorf("customer not anonymized: name %q, email %q", saved.Name, saved.Email.String())
+	}
+	if erasure.Status != domain.ErasureStatusInProgress || len(erasure.Services) != 3 || erasure.Services[0].CompletedAt == nil {
+		t.Errorf("erasure = %+v, want in progress with the customer service completed", erasure)
+	}
+	if erasure.IdentifiersBlocked != 1 || len(uow.erasedIDRepo.identifiers) != 1 {
+		t.Errorf("blocked %d identifiers, want the customer's email", erasure.IdentifiersBlocked)
+	}
+	if len(uow.outboxRepo.entries) != 1 || uow.outboxRepo.entries[0].EventType != domain.EventTypeCustomerErased {
+		t.Error("a customer.erased event should be written to the outbox")
+	}
+	for _, entry := range auditLogger.entries {
+		if entry.OldValue != nil || entry.NewValue != nil {
+			t.Errorf("audit entry %s should not carry the erased data", entry.Action)
+		}
+	}
+
+	_, err = uc.Request(context.Background(), RequestErasureInput{
+		TenantID:   customer.TenantID,
+		CustomerID: customer.ID,
+		Reason:     "Again",
+	})
+	if !application.IsConflictError(err) {
+		t.Errorf("erasing an erased customer error = %v, want a conflict", err)
+	}
+}
+
+func TestCustomerErasureUseCase_ConfirmService(t *testing.T) {
+	uc, uow, auditLogger := newTestErasureUseCase()
+	customer := createTestCustomerForUpdate(uuid.New())
+	uow.customerRepo.customers[customer.ID] = customer
+
+	erasure, err := uc.Request(context.Background(), RequestErasureInput{
+		TenantID:   customer.TenantID,
+		CustomerID: customer.ID,
+		Reason:     "Customer asked to be forgotten",
+	})
+	if err != nil {
+		t.Fatalf("Request() error = %v", err)
+	}
+
+	confirm := func(service string) {
+		t.Helper()
+		err := uc.ConfirmService(context.Background(), ConfirmErasureInput{
+			TenantID:  customer.TenantID,
+			ErasureID: erasure.ID,
+			Service:   service,
+			Records:   4,
+		})
+		if err != nil {
+			t.Fatalf("ConfirmService(%s) error = %v", service, err)
+		}
+	}
+
+	confirm(domain.ErasureServiceSales)
+	confirm(domain.ErasureServiceSales)
+	if uow.erasureRepo.requests[erasure.ID].Status != domain.ErasureStatusInProgress {
+		t.Error("erasure should wait for the notification service")
+	}
+
+	confirm(domain.ErasureServiceNotification)
+	request := uow.erasureRepo.requests[erasure.ID]
+	if request.Status != domain.ErasureStatusCompleted || request.CompletedAt == nil {
+		t.Errorf("status = %s, want completed", request.Status)
+	}
+
+	certificates := 0
+	for _, action := range auditActions(auditLogger) {
+		if action == "customer.erasure_certificate" {
+			certificates++
+		}
+	}
+	if certificates != 1 {
+		t.Errorf("logged %d erasure certificates, want 1", certificates)
+	}
+
+	err = uc.ConfirmService(context.Background(), ConfirmErasureInput{
+		TenantID:  customer.TenantID,
+		ErasureID: erasure.ID,
+		Service:   "billing",
+	})
+	if !application.IsValidationError(err) {
+		t.Errorf("unknown service error = %v, want a validation error", err)
+	}
+}
+
+func TestImportCustomersUseCase_ErasedCustomerBlocked(t *testing.T) {
+	uow := NewMockUnitOfWork()
+	tenantID := uuid.New()
+	uow.erasedIDRepo.identifiers = append(uow.erasedIDRepo.identifiers, &domain.ErasedIdentifier{
+		TenantID:  tenantID,
+		Hash:      domain.HashIdentifier(domain.IdentifierKindEmail, "erased@example.com"),
+		ExpiresAt: time.Now().AddDate(1, 0, 0),
+	})
+
+	importService := NewMockImportService()
+	importService.parseCustomersResult = []*ports.CustomerImportRow{
+		{RowNumber: 1, Name: "Erased Customer", Type: "company", Email: "Erased@Example.com"},
+		{RowNumber: 2, Name: "New Customer", Type: "company", Email: "new@example.com"},
+	}
+	uc := NewImportCustomersUseCase(uow, importService, NewMockCustomerEventPublisher(), NewMockIDGenerator(),
+		NewMockCustomerCacheService(), NewMockCustomerAuditLogger(), DefaultImportConfig())
+
+	result, err := uc.Execute(context.Background(), ImportCustomersInput{
+		TenantID: tenantID,
+		UserID:   uuid.New(),
+		FileName: "customers.csv",
+		FileSize: 1024,
+		Format:   "csv",
+		Data:     []byte("name,email\nErased Customer,Erased@Example.com\nNew Customer,new@example.com"),
+	})
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if result.SuccessCount != 1 || result.FailureCount != 1 {
+		t.Errorf("imported %d and failed %d rows, want the erased customer refused", result.SuccessCount, result.FailureCount)
+	}
+}
@@ -0,0 +1,229 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/kilang-desa-murni/crm/internal/customer/application"
+	"github.com/kilang-desa-murni/crm/internal/customer/application/ports"
+	"github.com/kilang-desa-murni/crm/internal/customer/domain"
+)
+
+// healthBatchSize is the number of customers scored per repository query, and
+// healthMaxBatches bounds the queries of one scoring run.
+const (
+	healthBatchSize  = 200
+	healthMaxBatches = 100
+)
+
+// ============================================================================
+// Customer Health Use Cases
+// ============================================================================
+
+// CustomerHealthUseCase scores customers' health. It records the inputs the
+// other services report, overdue invoices from the sales service and opened
+// or clicked notifications from the notification service, and a daily job
+// combines them with the customers' activity and orders into a score from 0
+// to 100. A sharp drop raises a customer.health_dropped event, which alerts
+// the customer's owner.
+type CustomerHealthUseCase struct {
+	uow         domain.UnitOfWork
+	idGenerator ports.IDGenerator
+	cache       ports.CacheService
+}
+
+// NewCustomerHealthUseCase creates a new CustomerHealthUseCase.
+func NewCustomerHealthUseCase(
+	uow domain.UnitOfWork,
+	idGenerator ports.IDGenerator,
+	cache ports.CacheService,
+) *CustomerHealthUseCase {
+	return &CustomerHealthUseCase{
+		uow:         uow,
+		idGenerator: idGenerator,
+		cache:       cache,
+	}
+}
+
+// RecordOverdueInvoiceInput holds an invoice of a deal with a customer that
+// passed its due date unpaid.
+type RecordOverdueInvoiceInput struct {
+	TenantID   uuid.UUID
+	CustomerID uuid.UUID
+	Invoice    domain.OverdueInvoice
+}
+
+// RecordOverdueInvoice records an overdue invoice on its customer. It is driven
+// by the sales service's deal.invoice_overdue events, so a customer that no
+// longer exists is ignored rather than reported.
+func (uc *CustomerHealthUseCase) RecordOverdueInvoice(ctx context.Context, input RecordOverdueInvoiceInput) error {
+	customer, err := uc.findCustomer(ctx, input.TenantID, input.CustomerID)
+	if err != nil || customer == nil {
+		return err
+	}
+
+	if !customer.RecordOverdueInvoice(input.Invoice) {
+		return nil
+	}
+	return uc.save(ctx, customer)
+}
+
+// RecordInvoicePaymentInput holds a payment received on a deal with a
+// customer.
+type RecordInvoicePaymentInput struct {
+	TenantID        uuid.UUID
+	CustomerID      uuid.UUID
+	DealID          uuid.UUID
+	InvoiceID       *uuid.UUID
+	Amount          int64
+	DealOutstanding int64
+}
+
+// RecordInvoicePayment records a payment against the customer's overdue
+// invoices. It is driven by the sales service's deal.payment_received events.
+func (uc *CustomerHealthUseCase) RecordInvoicePayment(ctx context.Context, input RecordInvoicePaymentInput) error {
+	customer, err := uc.findCustomer(ctx, input.TenantID, input.CustomerID)
+	if err != nil || customer == nil {
+		return err
+	}
+
+	if !customer.RecordInvoicePayment(input.DealID, input.InvoiceID, input.Amount, input.DealOutstanding) {
+		return nil
+	}
+	return uc.save(ctx, customer)
+}
+
+// RecordNotificationEngagement records the customer opening or clicking a
+// notification. It is driven by the notification service's
+// notification.opened and notification.clicked events.
+func (uc *CustomerHealthUseCase) RecordNotificationEngagement(ctx context.Context, tenantID, customerID uuid.UUID, clicked bool, engagedAt time.Time) error {
+	customer, err := uc.findCustomer(ctx, tenantID, customerID)
+	if err != nil || customer == nil {
+		return err
+	}
+
+	customer.RecordNotificationEngagement(clicked, engagedAt)
+	return uc.save(ctx, customer)
+}
+
+// EvaluateDue recalculates the health score of every customer not scored in
+// the health interval as of now, and returns the number of customers scored.
+// A customer that fails to score is skipped and picked up by the next run.
+func (uc *CustomerHealthUseCase) EvaluateDue(ctx context.Context, now time.Time) (int, error) {
+	scored := 0
+	skipped := make(map[uuid.UUID]bool)
+
+	for batch := 0; batch < healthMaxBatches; batch++ {
+		customers, err := uc.uow.Customers().FindHealthDue(ctx, now.Add(-domain.HealthInterval), healthBatchSize+len(skipped))
+		if err != nil {
+			return scored, application.ErrInternalError("failed to find customers due for health scoring", err)
+		}
+
+		progressed := false
+		for _, customer := range customers {
+			if skipped[customer.ID] {
+				continue
+			}
+			orders, err := uc.countRecentOrders(ctx, customer.ID, now)
+			if err != nil {
+				skipped[customer.ID] = true
+				continue
+			}
+			customer.CalculateHealth(orders, now)
+			if err := uc.save(ctx, customer); err != nil {
+				skipped[customer.ID] = true
+				continue
+			}
+			scored++
+			progressed = true
+		}
+
+		if !progressed || len(customers) < healthBatchSize+len(skipped) {
+			break
+		}
+	}
+	return scored, nil
+}
+
+// countRecentOrders counts the customer's purchases in the order window. Only
+// as many purchases as score full points are read.
+func (uc *CustomerHealthUseCase) countRecentOrders(ctx context.Context, customerID uuid.UUID, now time.Time) (int, error) {
+	purchases, err := uc.uow.Purchases().FindByCustomer(ctx, customerID, domain.HealthOrderTarget)
+	if err != nil {
+		return 0, application.ErrInternalError("failed to find purchases", err)
+	}
+
+	since := now.Add(-domain.HealthOrderWindow)
+	orders := 0
+	for _, purchase := range purchases {
+		if !purchase.PurchasedAt.Before(since) {
+			orders++
+		}
+	}
+	return orders, nil
+}
+
+// findCustomer finds a customer of the tenant by ID, returning nil if it no
+// longer exists.
+func (uc *CustomerHealthUseCase) findCustomer(ctx context.Context, tenantID, customerID uuid.UUID) (*domain.Customer, error) {
+	customer, err := uc.uow.Customers().FindByID(ctx, customerID)
+	if err != nil {
+		if domain.IsNotFoundError(err) {
+			return nil, nil
+		}
+		return nil, application.ErrInternalError("failed to find customer", err)
+	}
+	if customer.TenantID != tenantID {
+		return nil, application.ErrTenantMismatch(tenantID, customer.TenantID)
+	}
+	return customer, nil
+}
+
+// save persists the customer with its domain events.
+func (uc *CustomerHealthUseCase) save(ctx context.Context, customer *domain.Customer) error {
+	txCtx, err := uc.uow.Begin(ctx)
+	if err != nil {
+		return application.ErrInternalError("failed to begin transaction", err)
+	}
+	defer uc.uow.Rollback(txCtx)
+
+	if err := uc.uow.Customers().Update(txCtx, customer); err != nil {
+		if domain.IsConflictError(err) {
+			return application.ErrCustomerVersionConflict(customer.ID, customer.Version-1, customer.Version)
+		}
+		return application.ErrInternalError("failed to update customer", err)
+	}
+
+	for _, event := range customer.DomainEvents() {
+		payload, err := json.Marshal(event)
+		if err != nil {
+			return application.ErrInternalError("failed to serialize event", err)
+		}
+
+		outboxEntry := &domain.OutboxEntry{
+			ID:          uc.idGenerator.NewID(),
+			TenantID:    customer.TenantID,
+			EventType:   event.EventType(),
+			AggregateID: customer.ID,
+			Payload:     payload,
+			CreatedAt:   time.Now().UTC(),
+		}
+
+		if err := uc.uow.Outbox().Create(txCtx, outboxEntry); err != nil {
+			return application.ErrInternalError("failed to save outbox entry", err)
+		}
+	}
+
+	if err := uc.uow.Commit(txCtx); err != nil {
+		return application.ErrInternalError("failed to commit transaction", err)
+	}
+	customer.ClearDomainEvents()
+
+	if uc.cache != nil {
+		_ = uc.cache.Invalidate(ctx, "customer", customer.ID)
+	}
+	return nil
+}
@@ -0,0 +1,116 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/kilang-desa-murni/crm/internal/customer/domain"
+)
+
+// ============================================================================
+// CustomerHealthUseCase Tests
+// ============================================================================
+
+func newTestHealthUseCase() (*CustomerHealthUseCase, *MockUnitOfWork) {
+	uow := NewMockUnitOfWork()
+	uc := NewCustomerHealthUseCase(uow, NewMockIDGenerator(), NewMockCustomerCacheService())
+	return uc, uow
+}
+
+func TestCustomerHealthUseCase_EvaluateDue(t *testing.T) {
+	uc, uow := newTestHealthUseCase()
+	now := time.Now().UTC()
+	tenantID := uuid.New()
+
+	customer := createTestCustomerForUpdate(tenantID)
+	contacted := now.AddDate(0, 0, -3)
+	customer.LastContactedAt = &contacted
+	uow.customerRepo.customers[customer.ID] = customer
+	for _, days := range []int{30, 90, 400} {
+		uow.purchaseRepo.purchases = append(uow.purchaseRepo.purchases, &domain.Purchase{
+			ID:          uuid.New(),
+			TenantID:    tenantID,
+			CustomerID:  customer.ID,
+			PurchasedAt: now.AddDate(0, 0, -days),
+		})
+	}
+
+	scored, err := uc.EvaluateDue(context.Background(), now)
+	if err != nil {
+		t.Fatalf("EvaluateDue() error = %v", err)
+	}
+	if scored != 1 {
+		t.Fatalf("EvaluateDue() scored %d customers, want 1", scored)
+	}
+
+	stats := uow.customerRepo.customers[customer.ID].Stats
+	// Recent contact, no engagement, no overdue invoices and two orders in the year
+	if stats.HealthScore != 30+0+25+8 || stats.LastCalculatedAt == nil {
+		t.Errorf("health score = %d, want 63", stats.HealthScore)
+	}
+
+	scored, err = uc.EvaluateDue(context.Background(), now.Add(time.Hour))
+	if err != nil || scored != 0 {
+		t.Errorf("EvaluateDue() rerun scored %d (error %v), want none", scored, err)
+	}
+}
+
+func TestCustomerHealthUseCase_DropAlert(t *testing.T) {
+	uc, uow := newTestHealthUseCase()
+	now := time.Now().UTC()
+	tenantID := uuid.New()
+
+	customer := createTestCustomerForUpdate(tenantID)
+	previous := now.Add(-2 * domain.HealthInterval)
+	customer.Stats.HealthScore = 70
+	customer.Stats.LastCalculatedAt = &previous
+	uow.customerRepo.customers[customer.ID] = customer
+
+	err := uc.RecordOverdueInvoice(context.Background(), RecordOverdueInvoiceInput{
+		TenantID:   tenantID,
+		CustomerID: customer.ID,
+		Invoice: domain.OverdueInvoice{
+			InvoiceID:   uuid.New(),
+			DealID:      uuid.New(),
+			Outstanding: domain.Money{Amount: 500000, Currency: "MYR"},
+			DueDate:     now.AddDate(0, 0, -75),
+		},
+	})
+	if err != nil {
+		t.Fatalf("RecordOverdueInvoice() error = %v", err)
+	}
+
+	if _, err := uc.EvaluateDue(context.Background(), now); err != nil {
+		t.Fatalf("EvaluateDue() error = %v", err)
+	}
+
+	stats := uow.customerRepo.customers[customer.ID].Stats
+	if stats.HealthScore != 0 || stats.HealthTrend != domain.HealthTrendDown {
+		t.Errorf("health = %d (%s), want 0 and down", stats.HealthScore, stats.HealthTrend)
+	}
+	entries := uow.outboxRepo.entries
+	if len(entries) == 0 || entries[len(entries)-1].EventType != domain.EventTypeCustomerHealthDropped {
+		t.Error("a health drop should be written to the outbox")
+	}
+}
+
+func TestCustomerHealthUseCase_RecordNotificationEngagement(t *testing.T) {
+	uc, uow := newTestHealthUseCase()
+	tenantID := uuid.New()
+	customer := createTestCustomerForUpdate(tenantID)
+	uow.customerRepo.customers[customer.ID] = customer
+
+	if err := uc.RecordNotificationEngagement(context.Background(), tenantID, customer.ID, true, time.Now()); err != nil {
+		t.Fatalf("RecordNotificationEngagement() error = %v", err)
+	}
+	if stats := uow.customerRepo.customers[customer.ID].Stats; stats.NotificationClicks != 1 || stats.LastEngagedAt == nil {
+		t.Errorf("stats = %+v, want one click recorded", stats)
+	}
+
+	if err := uc.RecordNotificationEngagement(context.Background(), tenantID, uuid.New(), false, time.Now()); err != nil {
+		t.Errorf("RecordNotificationEngagement() for a missing customer error = %v, want none", err)
+	}
+}
@@ -4,6 +4,7 @@ package usecase
 import (
 	"bytes"
 	"context"
+	"fmt"
 	"io"
 	"time"
 
@@ -202,7 +203,7 @@ func (uc *ImportCustomersUseCase) Execute(ctx context.Context, input ImportCusto
 		}
 		batch := rows[i:end]
 
-		results := uc.processBatch(ctx, input, batch)
+		results := uc.importBatch(ctx, input, batch)
 		for _, result := range results {
 			output.Results = append(output.Results, result)
 			if result.Success {
@@ -297,8 +298,36 @@ func (uc *ImportCustomersUseCase) validateImportInput(input ImportCustomersInput
 	return nil
 }
 
-// processBatch processes a batch of import rows.
-func (uc *ImportCustomersUseCase) processBatch(ctx context.Context, input ImportCustomersInput, rows []*ports.CustomerImportRow) []ImportRowResult {
+// importBatch imports a batch of rows in one transaction, so a batch is
+// imported completely or not at all. When a write fails the batch is rolled
+// back and all of its rows are reported as failed.
+func (uc *ImportCustomersUseCase) importBatch(ctx context.Context, input ImportCustomersInput, rows []*ports.CustomerImportRow) []ImportRowResult {
+	var results []ImportRowResult
+	err := uc.uow.WithTransaction(ctx, func(txCtx context.Context) error {
+		var err error
+		results, err = uc.processBatch(txCtx, input, rows)
+		return err
+	})
+	if err == nil {
+		return results
+	}
+
+	rolledBack := "batch rolled back: " + err.Error()
+	results = make([]ImportRowResult, len(rows))
+	for i, row := range rows {
+		results[i] = ImportRowResult{
+			RowNumber: row.RowNumber,
+			Errors:    append(append([]string(nil), row.Errors...), rolledBack),
+			Warnings:  row.Warnings,
+		}
+	}
+	return results
+}
+
+// processBatch processes a batch of import rows. Rows that cannot be
+// imported are reported in the results; a failed write is returned as an
+// error, since it aborts the transaction the batch runs in.
+func (uc *ImportCustomersUseCase) processBatch(ctx context.Context, input ImportCustomersInput, rows []*ports.CustomerImportRow) ([]ImportRowResult, error) {
 	results := make([]ImportRowResult, len(rows))
 
 	for i, row := range rows {
@@ -315,6 +344,18 @@ func (uc *ImportCustomersUseCase) processBatch(ctx context.Context, input Import
 			continue
 		}
 
+		// Refuse customers whose data was erased
+		if blocked, err := uc.isErased(ctx, input.TenantID, row); err != nil || blocked {
+			result.Success = false
+			if err != nil {
+				result.Errors = append(result.Errors, "failed to check erased customers")
+			} else {
+				result.Errors = append(result.Errors, "customer data was erased and cannot be re-imported")
+			}
+			results[i] = result
+			continue
+		}
+
 		// Check for duplicates
 		if input.SkipDuplicates && row.Email != "" {
 			exists, _ := uc.uow.Customers().ExistsByEmail(ctx, input.TenantID, row.Email)
@@ -324,14 +365,12 @@ func (uc *ImportCustomersUseCase) processBatch(ctx context.Context, input Import
 					existing, err := uc.uow.Customers().FindByEmail(ctx, input.TenantID, row.Email)
 					if err == nil {
 						uc.updateFromImportRow(existing, row, input)
-						if err := uc.uow.Customers().Update(ctx, existing); err == nil {
-							result.Success = true
-							result.Action = "updated"
-							result.CustomerID = &existing.ID
-						} else {
-							result.Success = false
-							result.Errors = append(result.Errors, err.Error())
+						if err := uc.uow.Customers().Update(ctx, existing); err != nil {
+							return results, fmt.Errorf("row %d: %w", row.RowNumber, err)
 						}
+						result.Success = true
+						result.Action = "updated"
+						result.CustomerID = &existing.ID
 					}
 				} else {
 					result.Success = true
@@ -354,18 +393,39 @@ func (uc *ImportCustomersUseCase) processBatch(ctx context.Context, input Import
 
 		// Save customer
 		if err := uc.uow.Customers().Create(ctx, customer); err != nil {
-			result.Success = false
-			result.Errors = append(result.Errors, err.Error())
-		} else {
-			result.Success = true
-			result.Action = "created"
-			result.CustomerID = &customer.ID
+			return results, fmt.Errorf("row %d: %w", row.RowNumber, err)
 		}
+		result.Success = true
+		result.Action = "created"
+		result.CustomerID = &customer.ID
 
 		results[i] = result
 	}
 
-	return results
+	return results, nil
+}
+
+// isErased returns true if the row's email address or phone number belongs
+// to a customer whose data was erased and is still blocked from re-import.
+// Phone numbers are compared in E.164 form, as they were stored.
+func (uc *ImportCustomersUseCase) isErased(ctx context.Context, tenantID uuid.UUID, row *ports.CustomerImportRow) (bool, error) {
+	var phones []string
+	if row.Phone != "" {
+		if phone, err := domain.NewPhoneNumber(row.Phone, domain.PhoneTypeMobile); err == nil {
+			phones = append(phones, phone.E164())
+		}
+	}
+
+	hashes := domain.IdentifierHashes([]string{row.Email}, phones)
+	if len(hashes) == 0 {
+		return false, nil
+	}
+
+	blocked, err := uc.uow.ErasedIdentifiers().FindBlocked(ctx, tenantID, hashes, time.Now().UTC())
+	if err != nil {
+		return false, err
+	}
+	return len(blocked) > 0, nil
 }
 
 // createFromImportRow creates a customer from an import row.
@@ -6,6 +6,7 @@ import (
 	"context"
 	"errors"
 	"io"
+	"strings"
 	"testing"
 	"time"
 
@@ -599,6 +600,46 @@ func TestImportCustomersUseCase_Execute_RowWithErrors(t *testing.T) {
 	}
 }
 
+func TestImportCustomersUseCase_Execute_FailedWriteRollsBackBatch(t *testing.T) {
+	// Arrange
+	uow := NewMockUnitOfWork()
+	uow.customerRepo.createErr = errors.New("write conflict")
+	importService := NewMockImportService()
+	importService.parseCustomersResult = []*ports.CustomerImportRow{
+		{RowNumber: 1, Name: "First Customer", Type: "company", Email: "first@example.com"},
+		{RowNumber: 2, Name: "Second Customer", Type: "company", Email: "second@example.com"},
+	}
+
+	config := DefaultImportConfig()
+	config.ValidateBeforeImport = false
+	uc := NewImportCustomersUseCase(uow, importService, NewMockCustomerEventPublisher(), NewMockIDGenerator(), NewMockCustomerCacheService(), NewMockCustomerAuditLogger(), config)
+
+	input := ImportCustomersInput{
+		TenantID: uuid.New(),
+		UserID:   uuid.New(),
+		FileName: "customers.csv",
+		FileSize: 1024,
+		Format:   "csv",
+		Data:     []byte("test data"),
+	}
+
+	// Act
+	result, err := uc.Execute(context.Background(), input)
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if result.SuccessCount != 0 || result.FailureCount != 2 {
+		t.Errorf("Expected the whole batch to fail, got %d succeeded and %d failed", result.SuccessCount, result.FailureCount)
+	}
+	for _, row := range result.Results {
+		if len(row.Errors) == 0 || !strings.Contains(row.Errors[len(row.Errors)-1], "batch rolled back: row 1: write conflict") {
+			t.Errorf("Expected row %d reported as rolled back, got %v", row.RowNumber, row.Errors)
+		}
+	}
+}
+
 // ============================================================================
 // ExportCustomersUseCase Tests
 // ============================================================================
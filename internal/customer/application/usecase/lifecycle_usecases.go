@@ -0,0 +1,241 @@
+// Package usecase contains the application use cases for the Customer service.
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/kilang-desa-murni/crm/internal/customer/application"
+	"github.com/kilang-desa-murni/crm/internal/customer/application/dto"
+	"github.com/kilang-desa-murni/crm/internal/customer/application/mapper"
+	"github.com/kilang-desa-murni/crm/internal/customer/application/ports"
+	"github.com/kilang-desa-murni/crm/internal/customer/domain"
+)
+
+// lifecycleBatchSize is the number of customers evaluated per repository
+// query, and lifecycleMaxBatches bounds the queries of one evaluation run.
+const (
+	lifecycleBatchSize  = 200
+	lifecycleMaxBatches = 50
+)
+
+// ============================================================================
+// Customer Lifecycle Use Cases
+// ============================================================================
+
+// CustomerLifecycleUseCase moves customers through their lifecycle stages:
+// won deals make a customer active, a periodic evaluation turns customers
+// without a won deal in the dormancy period dormant, and users can set the
+// stage by hand. Every change raises a customer.lifecycle_changed event,
+// which win-back campaigns are triggered from.
+type CustomerLifecycleUseCase struct {
+	uow            domain.UnitOfWork
+	idGenerator    ports.IDGenerator
+	cache          ports.CacheService
+	auditLogger    ports.AuditLogger
+	customerMapper *mapper.CustomerMapper
+}
+
+// NewCustomerLifecycleUseCase creates a new CustomerLifecycleUseCase.
+func NewCustomerLifecycleUseCase(
+	uow domain.UnitOfWork,
+	idGenerator ports.IDGenerator,
+	cache ports.CacheService,
+	auditLogger ports.AuditLogger,
+) *CustomerLifecycleUseCase {
+	return &CustomerLifecycleUseCase{
+		uow:            uow,
+		idGenerator:    idGenerator,
+		cache:          cache,
+		auditLogger:    auditLogger,
+		customerMapper: mapper.NewCustomerMapper(),
+	}
+}
+
+// SetLifecycleInput holds input for a manual lifecycle change.
+type SetLifecycleInput struct {
+	TenantID   uuid.UUID
+	UserID     uuid.UUID
+	CustomerID uuid.UUID
+	Request    *dto.SetLifecycleRequest
+	IPAddress  string
+	UserAgent  string
+}
+
+// Set sets the customer's lifecycle stage by hand. The stage overrides the
+// automatic transitions until the customer's next won deal.
+func (uc *CustomerLifecycleUseCase) Set(ctx context.Context, input SetLifecycleInput) (*dto.CustomerResponse, error) {
+	if input.TenantID == uuid.Nil {
+		return nil, application.ErrInvalidInput("tenant_id is required")
+	}
+	if input.CustomerID == uuid.Nil {
+		return nil, application.ErrInvalidInput("customer_id is required")
+	}
+	if input.Request == nil {
+		return nil, application.ErrInvalidInput("request is required")
+	}
+
+	customer, err := uc.findCustomer(ctx, input.CustomerID)
+	if err != nil {
+		return nil, err
+	}
+	if customer.TenantID != input.TenantID {
+		return nil, application.ErrTenantMismatch(input.TenantID, customer.TenantID)
+	}
+
+	oldLifecycle := customer.CurrentLifecycle()
+	if err := customer.SetLifecycle(input.Request.Lifecycle, input.Request.Reason); err != nil {
+		var verr *domain.ValidationError
+		if errors.As(err, &verr) {
+			return nil, application.ErrCustomerValidation(verr.Message, map[string]interface{}{
+				"lifecycle": input.Request.Lifecycle,
+			})
+		}
+		return nil, application.ErrInternalError("failed to set lifecycle", err)
+	}
+	customer.AuditInfo.SetUpdatedBy(input.UserID)
+
+	if err := uc.save(ctx, customer); err != nil {
+		return nil, err
+	}
+
+	if uc.auditLogger != nil {
+		_ = uc.auditLogger.LogAction(ctx, ports.AuditEntry{
+			ID:         uc.idGenerator.NewID(),
+			TenantID:   input.TenantID,
+			UserID:     &input.UserID,
+			Action:     "customer.lifecycle_changed",
+			EntityType: "customer",
+			EntityID:   customer.ID,
+			OldValue:   map[string]interface{}{"lifecycle": oldLifecycle},
+			NewValue: map[string]interface{}{
+				"lifecycle": customer.Lifecycle,
+				"reason":    input.Request.Reason,
+				"override":  true,
+			},
+			IPAddress: input.IPAddress,
+			UserAgent: input.UserAgent,
+			Timestamp: time.Now().UTC(),
+		})
+	}
+
+	return uc.customerMapper.ToResponse(customer), nil
+}
+
+// RecordWonDeal records a deal won with a customer, making the customer
+// active. It is driven by the sales service's opportunity.won events, so a
+// customer that no longer exists is ignored rather than reported.
+func (uc *CustomerLifecycleUseCase) RecordWonDeal(ctx context.Context, tenantID, customerID uuid.UUID, wonAt time.Time) error {
+	customer, err := uc.findCustomer(ctx, customerID)
+	if err != nil {
+		if application.IsNotFoundError(err) {
+			return nil
+		}
+		return err
+	}
+	if customer.TenantID != tenantID {
+		return application.ErrTenantMismatch(tenantID, customer.TenantID)
+	}
+
+	customer.RecordWonDeal(wonAt)
+	return uc.save(ctx, customer)
+}
+
+// EvaluateDue applies the automatic lifecycle transitions to every customer
+// that is due as of now, and returns the number of customers changed. A
+// customer that fails to save is skipped and picked up by the next run.
+func (uc *CustomerLifecycleUseCase) EvaluateDue(ctx context.Context, now time.Time) (int, error) {
+	changed := 0
+	skipped := make(map[uuid.UUID]bool)
+
+	for batch := 0; batch < lifecycleMaxBatches; batch++ {
+		customers, err := uc.uow.Customers().FindLifecycleDue(ctx, now.Add(-domain.DormancyPeriod), lifecycleBatchSize+len(skipped))
+		if err != nil {
+			return changed, application.ErrInternalError("failed to find customers due for lifecycle evaluation", err)
+		}
+
+		progressed := false
+		for _, customer := range customers {
+			if skipped[customer.ID] {
+				continue
+			}
+			if !customer.EvaluateLifecycle(now) {
+				skipped[customer.ID] = true
+				continue
+			}
+			if err := uc.save(ctx, customer); err != nil {
+				skipped[customer.ID] = true
+				continue
+			}
+			changed++
+			progressed = true
+		}
+
+		if !progressed || len(customers) < lifecycleBatchSize+len(skipped) {
+			break
+		}
+	}
+	return changed, nil
+}
+
+// findCustomer finds a customer by ID.
+func (uc *CustomerLifecycleUseCase) findCustomer(ctx context.Context, customerID uuid.UUID) (*domain.Customer, error) {
+	customer, err := uc.uow.Customers().FindByID(ctx, customerID)
+	if err != nil {
+		if domain.IsNotFoundError(err) {
+			return nil, application.ErrCustomerNotFound(customerID)
+		}
+		return nil, application.ErrInternalError("failed to find customer", err)
+	}
+	return customer, nil
+}
+
+// save persists the customer with its domain events.
+func (uc *CustomerLifecycleUseCase) save(ctx context.Context, customer *domain.Customer) error {
+	txCtx, err := uc.uow.Begin(ctx)
+	if err != nil {
+		return application.ErrInternalError("failed to begin transaction", err)
+	}
+	defer uc.uow.Rollback(txCtx)
+
+	if err := uc.uow.Customers().Update(txCtx, customer); err != nil {
+		if domain.IsConflictError(err) {
+			return application.ErrCustomerVersionConflict(customer.ID, customer.Version-1, customer.Version)
+		}
+		return application.ErrInternalError("failed to update customer", err)
+	}
+
+	for _, event := range customer.DomainEvents() {
+		payload, err := json.Marshal(event)
+		if err != nil {
+			return application.ErrInternalError("failed to serialize event", err)
+		}
+
+		outboxEntry := &domain.OutboxEntry{
+			ID:          uc.idGenerator.NewID(),
+			TenantID:    customer.TenantID,
+			EventType:   event.EventType(),
+			AggregateID: customer.ID,
+			Payload:     payload,
+			CreatedAt:   time.Now().UTC(),
+		}
+
+		if err := uc.uow.Outbox().Create(txCtx, outboxEntry); err != nil {
+			return application.ErrInternalError("failed to save outbox entry", err)
+		}
+	}
+
+	if err := uc.uow.Commit(txCtx); err != nil {
+		return application.ErrInternalError("failed to commit transaction", err)
+	}
+	customer.ClearDomainEvents()
+
+	if uc.cache != nil {
+		_ = uc.cache.Invalidate(ctx, "customer", customer.ID)
+	}
+	return nil
+}
@@ -0,0 +1,121 @@
+// Package usecase contains the application use cases for the Customer service.
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/kilang-desa-murni/crm/internal/customer/application"
+	"github.com/kilang-desa-murni/crm/internal/customer/application/dto"
+	"github.com/kilang-desa-murni/crm/internal/customer/domain"
+)
+
+// ============================================================================
+// CustomerLifecycleUseCase Tests
+// ============================================================================
+
+func newTestLifecycleUseCase() (*CustomerLifecycleUseCase, *MockUnitOfWork) {
+	uow := NewMockUnitOfWork()
+	uc := NewCustomerLifecycleUseCase(uow, NewMockIDGenerator(), NewMockCustomerCacheService(), NewMockCustomerAuditLogger())
+	return uc, uow
+}
+
+func TestCustomerLifecycleUseCase_Set(t *testing.T) {
+	uc, uow := newTestLifecycleUseCase()
+	tenantID := uuid.New()
+	customer := createTestCustomerForUpdate(tenantID)
+	uow.customerRepo.customers[customer.ID] = customer
+
+	result, err := uc.Set(context.Background(), SetLifecycleInput{
+		TenantID:   tenantID,
+		UserID:     uuid.New(),
+		CustomerID: customer.ID,
+		Request:    &dto.SetLifecycleRequest{Lifecycle: domain.LifecycleChurned, Reason: "moved to a competitor"},
+	})
+	if err != nil {
+		t.Fatalf("Set() error = %v", err)
+	}
+	if result.Lifecycle != domain.LifecycleChurned || !result.LifecycleOverride {
+		t.Errorf("lifecycle = %s, override = %v; want an overridden churned lifecycle", result.Lifecycle, result.LifecycleOverride)
+	}
+	if len(uow.outboxRepo.entries) == 0 || uow.outboxRepo.entries[len(uow.outboxRepo.entries)-1].EventType != domain.EventTypeCustomerLifecycleChanged {
+		t.Error("a lifecycle change should be written to the outbox")
+	}
+}
+
+func TestCustomerLifecycleUseCase_Set_InvalidLifecycle(t *testing.T) {
+	uc, uow := newTestLifecycleUseCase()
+	tenantID := uuid.New()
+	customer := createTestCustomerForUpdate(tenantID)
+	uow.customerRepo.customers[customer.ID] = customer
+
+	_, err := uc.Set(context.Background(), SetLifecycleInput{
+		TenantID:   tenantID,
+		UserID:     uuid.New(),
+		CustomerID: customer.ID,
+		Request:    &dto.SetLifecycleRequest{Lifecycle: "hibernating"},
+	})
+
+	var appErr *application.ApplicationError
+	if !errors.As(err, &appErr) || appErr.Code != application.ErrCodeCustomerValidation {
+		t.Errorf("Set() error = %v, want a validation error", err)
+	}
+}
+
+func TestCustomerLifecycleUseCase_EvaluateDue(t *testing.T) {
+	uc, uow := newTestLifecycleUseCase()
+	tenantID := uuid.New()
+	now := time.Now().UTC()
+
+	lastWon := now.Add(-400 * 24 * time.Hour)
+	dormant := createTestCustomerForUpdate(tenantID)
+	dormant.Lifecycle = domain.LifecycleActive
+	dormant.Stats.LastWonDealAt = &lastWon
+
+	recentWon := now.Add(-30 * 24 * time.Hour)
+	active := createTestCustomerForUpdate(tenantID)
+	active.Lifecycle = domain.LifecycleActive
+	active.Stats.LastWonDealAt = &recentWon
+
+	for _, c := range []*domain.Customer{dormant, active} {
+		uow.customerRepo.customers[c.ID] = c
+	}
+
+	changed, err := uc.EvaluateDue(context.Background(), now)
+	if err != nil {
+		t.Fatalf("EvaluateDue() error = %v", err)
+	}
+	if changed != 1 {
+		t.Errorf("changed = %d, want 1", changed)
+	}
+	if dormant.Lifecycle != domain.LifecycleDormant {
+		t.Errorf("customer without a won deal in a year: lifecycle = %s, want dormant", dormant.Lifecycle)
+	}
+	if active.Lifecycle != domain.LifecycleActive {
+		t.Errorf("customer with a recent won deal: lifecycle = %s, want active", active.Lifecycle)
+	}
+}
+
+func TestCustomerLifecycleUseCase_RecordWonDeal(t *testing.T) {
+	uc, uow := newTestLifecycleUseCase()
+	tenantID := uuid.New()
+	customer := createTestCustomerForUpdate(tenantID)
+	customer.Lifecycle = domain.LifecycleDormant
+	customer.LifecycleOverride = true
+	uow.customerRepo.customers[customer.ID] = customer
+
+	if err := uc.RecordWonDeal(context.Background(), tenantID, customer.ID, time.Now()); err != nil {
+		t.Fatalf("RecordWonDeal() error = %v", err)
+	}
+	if customer.Lifecycle != domain.LifecycleActive || customer.LifecycleOverride {
+		t.Errorf("lifecycle = %s, override = %v; want active without override", customer.Lifecycle, customer.LifecycleOverride)
+	}
+
+	if err := uc.RecordWonDeal(context.Background(), tenantID, uuid.New(), time.Now()); err != nil {
+		t.Errorf("RecordWonDeal(unknown customer) error = %v, want nil", err)
+	}
+}
@@ -0,0 +1,61 @@
+package usecase
+
+import (
+	"github.com/kilang-desa-murni/crm/internal/customer/application"
+	"github.com/kilang-desa-murni/crm/internal/customer/domain"
+	"github.com/kilang-desa-murni/crm/pkg/query"
+)
+
+// customerFilterFields is the allow-list of customer filter expressions.
+var customerFilterFields = query.Fields{
+	"status":            query.String,
+	"lifecycle":         query.String,
+	"type":              query.String,
+	"tier":              query.String,
+	"source":            query.String,
+	"name":              query.String,
+	"code":              query.String,
+	"tags":              query.String,
+	"industry":          query.String,
+	"country":           query.String,
+	"state":             query.String,
+	"city":              query.String,
+	"owner_id":          query.UUID,
+	"deal_count":        query.Integer,
+	"engagement_score":  query.Integer,
+	"health_score":      query.Integer,
+	"health":            query.Integer,
+	"health_trend":      query.String,
+	"last_contacted_at": query.Time,
+	"created_at":        query.Time,
+	"updated_at":        query.Time,
+}
+
+// customerSortFields lists the fields customer sort expressions accept.
+var customerSortFields = []string{"name", "code", "created_at", "updated_at", "last_contacted_at"}
+
+// parseCustomerListQuery parses a list request's filter and sort expressions
+// onto filter. The sort, when given, replaces the filter's sort field and
+// order; customer lists are paged on a single sort key, so only one sort
+// field is accepted.
+func parseCustomerListQuery(raw, sort string, filter *domain.CustomerFilter) error {
+	expr, err := query.ParseFilter(raw, customerFilterFields)
+	if err != nil {
+		return application.ErrInvalidInput("invalid filter: " + err.Error())
+	}
+	filter.Expr = expr
+
+	fields, err := query.ParseSort(sort, customerSortFields...)
+	if err != nil {
+		return application.ErrInvalidInput("invalid sort: " + err.Error())
+	}
+	switch len(fields) {
+	case 0:
+	case 1:
+		filter.SortBy = fields[0].Field
+		filter.SortOrder = fields[0].Order()
+	default:
+		return application.ErrInvalidInput("invalid sort: only one sort field is supported")
+	}
+	return nil
+}
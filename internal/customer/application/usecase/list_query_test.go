@@ -0,0 +1,45 @@
+package usecase
+
+import (
+	"testing"
+
+	"github.com/kilang-desa-murni/crm/internal/customer/domain"
+	"github.com/kilang-desa-murni/crm/pkg/query"
+)
+
+func TestParseCustomerListQuery(t *testing.T) {
+	filter := domain.CustomerFilter{SortBy: "created_at", SortOrder: "desc"}
+
+	if err := parseCustomerListQuery("status:eq:active,tier:in:gold|platinum", "name", &filter); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(filter.Expr) != 2 || filter.Expr[1].Op != query.In || len(filter.Expr[1].Values) != 2 {
+		t.Errorf("unexpected filter expression %+v", filter.Expr)
+	}
+	if filter.SortBy != "name" || filter.SortOrder != "asc" {
+		t.Errorf("expected sort by name asc, got %s %s", filter.SortBy, filter.SortOrder)
+	}
+}
+
+func TestParseCustomerListQuery_Errors(t *testing.T) {
+	tests := []struct {
+		name   string
+		filter string
+		sort   string
+	}{
+		{"field not allowed", "notes:contains:vip", ""},
+		{"bad uuid", "owner_id:eq:me", ""},
+		{"sort field not allowed", "", "-health_score"},
+		{"several sort fields", "", "-created_at,name"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var filter domain.CustomerFilter
+			if err := parseCustomerListQuery(tt.filter, tt.sort, &filter); err == nil {
+				t.Error("expected an error")
+			}
+		})
+	}
+}
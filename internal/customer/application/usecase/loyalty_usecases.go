@@ -0,0 +1,339 @@
+// Package usecase contains the application use cases for the Customer service.
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/kilang-desa-murni/crm/internal/customer/application"
+	"github.com/kilang-desa-murni/crm/internal/customer/application/dto"
+	"github.com/kilang-desa-murni/crm/internal/customer/application/mapper"
+	"github.com/kilang-desa-murni/crm/internal/customer/application/ports"
+	"github.com/kilang-desa-murni/crm/internal/customer/domain"
+)
+
+const (
+	// defaultPurchaseHistoryLimit is the number of recent purchases returned
+	// with a customer's purchase totals.
+	defaultPurchaseHistoryLimit = 20
+	maxPurchaseHistoryLimit     = 100
+
+	// defaultTopCustomersLimit is the number of customers returned by a top
+	// customers query without a limit.
+	defaultTopCustomersLimit = 10
+
+	// defaultTopCustomersPeriod is the period of a top customers query
+	// without a start date.
+	defaultTopCustomersPeriod = 365 * 24 * time.Hour
+)
+
+// ============================================================================
+// Customer Loyalty Use Cases
+// ============================================================================
+
+// CustomerLoyaltyUseCase tracks customers' repeat purchases. Each deal won
+// with a customer is recorded as a purchase, which updates the customer's
+// purchase totals and raises their loyalty tier once the policy's thresholds
+// are met.
+type CustomerLoyaltyUseCase struct {
+	uow         domain.UnitOfWork
+	idGenerator ports.IDGenerator
+	cache       ports.CacheService
+	policy      domain.LoyaltyPolicy
+}
+
+// NewCustomerLoyaltyUseCase creates a new CustomerLoyaltyUseCase.
+func NewCustomerLoyaltyUseCase(
+	uow domain.UnitOfWork,
+	idGenerator ports.IDGenerator,
+	cache ports.CacheService,
+	policy domain.LoyaltyPolicy,
+) (*CustomerLoyaltyUseCase, error) {
+	if err := policy.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid loyalty policy: %w", err)
+	}
+
+	return &CustomerLoyaltyUseCase{
+		uow:         uow,
+		idGenerator: idGenerator,
+		cache:       cache,
+		policy:      policy,
+	}, nil
+}
+
+// RecordPurchaseInput holds input for recording a purchase.
+type RecordPurchaseInput struct {
+	TenantID      uuid.UUID
+	CustomerID    uuid.UUID
+	OpportunityID uuid.UUID
+	Reference     string
+	Amount        domain.Money
+	PurchasedAt   time.Time
+}
+
+// RecordPurchase records a won deal as a purchase by the customer. It is
+// driven by the sales service's opportunity.won events: a deal that was
+// already recorded and a customer that no longer exists are ignored rather
+// than reported.
+func (uc *CustomerLoyaltyUseCase) RecordPurchase(ctx context.Context, input RecordPurchaseInput) error {
+	customer, err := uc.uow.Customers().FindByID(ctx, input.CustomerID)
+	if err != nil {
+		if domain.IsNotFoundError(err) {
+			return nil
+		}
+		return application.ErrInternalError("failed to find customer", err)
+	}
+	if customer.TenantID != input.TenantID {
+		return application.ErrTenantMismatch(input.TenantID, customer.TenantID)
+	}
+
+	purchase, err := domain.NewPurchase(input.TenantID, input.CustomerID, input.OpportunityID, input.Reference, input.Amount, input.PurchasedAt)
+	if err != nil {
+		var verr *domain.ValidationError
+		if errors.As(err, &verr) {
+			return application.ErrCustomerValidation(verr.Message, map[string]interface{}{
+				"opportunity_id": input.OpportunityID,
+				"amount":         input.Amount,
+			})
+		}
+		return application.ErrInternalError("failed to create purchase", err)
+	}
+
+	txCtx, err := uc.uow.Begin(ctx)
+	if err != nil {
+		return application.ErrInternalError("failed to begin transaction", err)
+	}
+	defer uc.uow.Rollback(txCtx)
+
+	if err := uc.uow.Purchases().Create(txCtx, purchase); err != nil {
+		if errors.Is(err, domain.ErrPurchaseAlreadyRecorded) {
+			return nil
+		}
+		return application.ErrInternalError("failed to record purchase", err)
+	}
+
+	customer.ApplyPurchase(purchase)
+	customer.ApplyLoyaltyTier(uc.policy)
+
+	if err := uc.uow.Customers().Update(txCtx, customer); err != nil {
+		if domain.IsConflictError(err) {
+			return application.ErrCustomerVersionConflict(customer.ID, customer.Version-1, customer.Version)
+		}
+		return application.ErrInternalError("failed to update customer", err)
+	}
+
+	for _, event := range customer.DomainEvents() {
+		payload, err := json.Marshal(event)
+		if err != nil {
+			return application.ErrInternalError("failed to serialize event", err)
+		}
+
+		outboxEntry := &domain.OutboxEntry{
+			ID:          uc.idGenerator.NewID(),
+			TenantID:    customer.TenantID,
+			EventType:   event.EventType(),
+			AggregateID: customer.ID,
+			Payload:     payload,
+			CreatedAt:   time.Now().UTC(),
+		}
+
+		if err := uc.uow.Outbox().Create(txCtx, outboxEntry); err != nil {
+			return application.ErrInternalError("failed to save outbox entry", err)
+		}
+	}
+
+	if err := uc.uow.Commit(txCtx); err != nil {
+		return application.ErrInternalError("failed to commit transaction", err)
+	}
+	customer.ClearDomainEvents()
+
+	if uc.cache != nil {
+		_ = uc.cache.Invalidate(ctx, "customer", customer.ID)
+	}
+	return nil
+}
+
+// GetPurchaseHistoryInput holds input for getting a customer's purchase history.
+type GetPurchaseHistoryInput struct {
+	TenantID   uuid.UUID
+	CustomerID uuid.UUID
+	Limit      int
+}
+
+// GetPurchaseHistory returns the customer's purchase totals, their progress
+// towards the next loyalty tier, and their most recent purchases.
+func (uc *CustomerLoyaltyUseCase) GetPurchaseHistory(ctx context.Context, input GetPurchaseHistoryInput) (*dto.PurchaseHistoryResponse, error) {
+	if input.TenantID == uuid.Nil {
+		return nil, application.ErrInvalidInput("tenant_id is required")
+	}
+	if input.CustomerID == uuid.Nil {
+		return nil, application.ErrInvalidInput("customer_id is required")
+	}
+	if input.Limit <= 0 {
+		input.Limit = defaultPurchaseHistoryLimit
+	}
+	if input.Limit > maxPurchaseHistoryLimit {
+		input.Limit = maxPurchaseHistoryLimit
+	}
+
+	customer, err := uc.uow.Customers().FindByID(ctx, input.CustomerID)
+	if err != nil {
+		if domain.IsNotFoundError(err) {
+			return nil, application.ErrCustomerNotFound(input.CustomerID)
+		}
+		return nil, application.ErrInternalError("failed to find customer", err)
+	}
+	if customer.TenantID != input.TenantID {
+		return nil, application.ErrTenantMismatch(input.TenantID, customer.TenantID)
+	}
+
+	purchases, err := uc.uow.Purchases().FindByCustomer(ctx, customer.ID, input.Limit)
+	if err != nil {
+		return nil, application.ErrInternalError("failed to find purchases", err)
+	}
+
+	response := &dto.PurchaseHistoryResponse{
+		CustomerID:        customer.ID,
+		Tier:              customer.Tier,
+		TotalRevenue:      mapper.MoneyToResponse(customer.Financials.TotalSpent),
+		OrderCount:        customer.Financials.TotalPurchases,
+		LastPurchaseAt:    customer.Financials.LastPurchaseAt,
+		AverageOrderValue: mapper.MoneyToResponse(customer.AverageOrderValue()),
+		NextTier:          uc.loyaltyProgress(customer),
+		Purchases:         make([]*dto.PurchaseResponse, 0, len(purchases)),
+	}
+	for _, purchase := range purchases {
+		amount := purchase.Amount
+		response.Purchases = append(response.Purchases, &dto.PurchaseResponse{
+			ID:            purchase.ID,
+			OpportunityID: purchase.OpportunityID,
+			Reference:     purchase.Reference,
+			Amount:        mapper.MoneyToResponse(&amount),
+			PurchasedAt:   purchase.PurchasedAt,
+		})
+	}
+
+	return response, nil
+}
+
+// loyaltyProgress returns what the customer still needs to reach the next
+// loyalty tier, or nil if there is no tier above theirs.
+func (uc *CustomerLoyaltyUseCase) loyaltyProgress(customer *domain.Customer) *dto.LoyaltyProgressResponse {
+	next := uc.policy.NextThreshold(customer.Tier)
+	if next == nil {
+		return nil
+	}
+
+	spent := int64(0)
+	if customer.Financials.TotalSpent != nil && customer.Financials.TotalSpent.Currency == uc.policy.Currency {
+		spent = customer.Financials.TotalSpent.Amount
+	}
+	remaining := domain.Money{Amount: max(next.MinRevenue-spent, 0), Currency: uc.policy.Currency}
+
+	return &dto.LoyaltyProgressResponse{
+		Tier:             next.Tier,
+		RevenueRemaining: mapper.MoneyToResponse(&remaining),
+		OrdersRemaining:  max(next.MinOrders-customer.Financials.TotalPurchases, 0),
+	}
+}
+
+// TopCustomersInput holds input for a top customers query.
+type TopCustomersInput struct {
+	TenantID uuid.UUID
+	From     *time.Time
+	To       *time.Time
+	Currency domain.Currency
+	Limit    int
+}
+
+// TopCustomers returns the customers with the highest revenue from purchases
+// in the period, which defaults to the last year. Only purchases in the
+// currency, by default the loyalty policy's, are counted.
+func (uc *CustomerLoyaltyUseCase) TopCustomers(ctx context.Context, input TopCustomersInput) (*dto.TopCustomersResponse, error) {
+	if input.TenantID == uuid.Nil {
+		return nil, application.ErrInvalidInput("tenant_id is required")
+	}
+	if input.Currency == "" {
+		input.Currency = uc.policy.Currency
+	}
+	if !domain.ValidCurrencies[input.Currency] {
+		return nil, application.ErrInvalidInput(fmt.Sprintf("invalid currency %q", input.Currency))
+	}
+	if input.Limit <= 0 {
+		input.Limit = defaultTopCustomersLimit
+	}
+	if input.Limit > dto.MaxTopCustomers {
+		return nil, application.ErrInvalidInput(fmt.Sprintf("limit must be at most %d", dto.MaxTopCustomers))
+	}
+
+	to := time.Now().UTC()
+	if input.To != nil {
+		to = input.To.UTC()
+	}
+	from := to.Add(-defaultTopCustomersPeriod)
+	if input.From != nil {
+		from = input.From.UTC()
+	}
+	if !from.Before(to) {
+		return nil, application.ErrInvalidInput("from must be before to")
+	}
+
+	totals, err := uc.uow.Purchases().TopCustomers(ctx, input.TenantID, input.Currency, from, to, input.Limit)
+	if err != nil {
+		return nil, application.ErrInternalError("failed to total purchases", err)
+	}
+
+	customers := make(map[uuid.UUID]*domain.Customer, len(totals))
+	if len(totals) > 0 {
+		ids := make([]uuid.UUID, 0, len(totals))
+		for _, total := range totals {
+			ids = append(ids, total.CustomerID)
+		}
+		result, err := uc.uow.Customers().List(ctx, domain.CustomerFilter{
+			TenantID: &input.TenantID,
+			IDs:      ids,
+			Limit:    len(ids),
+		})
+		if err != nil {
+			return nil, application.ErrInternalError("failed to find customers", err)
+		}
+		for _, customer := range result.Customers {
+			customers[customer.ID] = customer
+		}
+	}
+
+	response := &dto.TopCustomersResponse{
+		From:      from,
+		To:        to,
+		Currency:  input.Currency,
+		Customers: make([]*dto.TopCustomerResponse, 0, len(totals)),
+	}
+	for i, total := range totals {
+		revenue := total.Revenue
+		average := domain.Money{Amount: revenue.Amount / int64(total.OrderCount), Currency: revenue.Currency}
+
+		entry := &dto.TopCustomerResponse{
+			Rank:              i + 1,
+			CustomerID:        total.CustomerID,
+			Revenue:           mapper.MoneyToResponse(&revenue),
+			OrderCount:        total.OrderCount,
+			AverageOrderValue: mapper.MoneyToResponse(&average),
+			LastPurchaseAt:    total.LastPurchaseAt,
+		}
+		// Purchases of deleted customers still count towards the period's
+		// revenue, so the customer's details may be missing
+		if customer, ok := customers[total.CustomerID]; ok {
+			entry.Code = customer.Code
+			entry.Name = customer.Name
+			entry.Tier = customer.Tier
+		}
+		response.Customers = append(response.Customers, entry)
+	}
+
+	return response, nil
+}
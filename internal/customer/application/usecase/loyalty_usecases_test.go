@@ -0,0 +1,118 @@
+// Package usecase contains the application use cases for the Customer service.
+package usecase
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/kilang-desa-murni/crm/internal/customer/domain"
+)
+
+// ============================================================================
+// CustomerLoyaltyUseCase Tests
+// ============================================================================
+
+func newTestLoyaltyUseCase(t *testing.T) (*CustomerLoyaltyUseCase, *MockUnitOfWork) {
+	t.Helper()
+	uow := NewMockUnitOfWork()
+	uc, err := NewCustomerLoyaltyUseCase(uow, NewMockIDGenerator(), NewMockCustomerCacheService(), domain.DefaultLoyaltyPolicy())
+	if err != nil {
+		t.Fatalf("NewCustomerLoyaltyUseCase() error = %v", err)
+	}
+	return uc, uow
+}
+
+func recordTestPurchase(t *testing.T, uc *CustomerLoyaltyUseCase, customer *domain.Customer, opportunityID uuid.UUID, amount int64, at time.Time) {
+	t.Helper()
+	err := uc.RecordPurchase(context.Background(), RecordPurchaseInput{
+		TenantID:      customer.TenantID,
+		CustomerID:    customer.ID,
+		OpportunityID: opportunityID,
+		Amount:        domain.Money{Amount: amount, Currency: domain.CurrencyMYR},
+		PurchasedAt:   at,
+	})
+	if err != nil {
+		t.Fatalf("RecordPurchase() error = %v", err)
+	}
+}
+
+func TestCustomerLoyaltyUseCase_RecordPurchase(t *testing.T) {
+	uc, uow := newTestLoyaltyUseCase(t)
+	customer := createTestCustomerForUpdate(uuid.New())
+	uow.customerRepo.customers[customer.ID] = customer
+	now := time.Now().UTC()
+
+	recordTestPurchase(t, uc, customer, uuid.New(), 400_00, now.Add(-48*time.Hour))
+	recordTestPurchase(t, uc, customer, uuid.New(), 800_00, now)
+
+	saved := uow.customerRepo.customers[customer.ID]
+	if saved.Financials.TotalPurchases != 2 || saved.Financials.TotalSpent.Amount != 1_200_00 {
+		t.Errorf("totals = %d orders, %d spent; want 2 orders, 120000 spent", saved.Financials.TotalPurchases, saved.Financials.TotalSpent.Amount)
+	}
+	if saved.Tier != domain.CustomerTierBronze {
+		t.Errorf("tier = %s, want bronze", saved.Tier)
+	}
+	if !saved.Financials.LastPurchaseAt.Equal(now) {
+		t.Errorf("last purchase = %v, want %v", saved.Financials.LastPurchaseAt, now)
+	}
+
+	history, err := uc.GetPurchaseHistory(context.Background(), GetPurchaseHistoryInput{TenantID: customer.TenantID, CustomerID: customer.ID})
+	if err != nil {
+		t.Fatalf("GetPurchaseHistory() error = %v", err)
+	}
+	if len(history.Purchases) != 2 || history.AverageOrderValue.Amount != 600 {
+		t.Errorf("history = %d purchases, average %v; want 2 purchases averaging 600", len(history.Purchases), history.AverageOrderValue.Amount)
+	}
+	if history.NextTier == nil || history.NextTier.Tier != domain.CustomerTierSilver || history.NextTier.OrdersRemaining != 3 {
+		t.Errorf("next tier = %+v, want silver with 3 orders remaining", history.NextTier)
+	}
+}
+
+func TestCustomerLoyaltyUseCase_RecordPurchase_Redelivered(t *testing.T) {
+	uc, uow := newTestLoyaltyUseCase(t)
+	customer := createTestCustomerForUpdate(uuid.New())
+	uow.customerRepo.customers[customer.ID] = customer
+	opportunityID := uuid.New()
+
+	recordTestPurchase(t, uc, customer, opportunityID, 500_00, time.Now())
+	recordTestPurchase(t, uc, customer, opportunityID, 500_00, time.Now())
+
+	if len(uow.purchaseRepo.purchases) != 1 || uow.customerRepo.customers[customer.ID].Financials.TotalPurchases != 1 {
+		t.Error("a redelivered won deal should be recorded once")
+	}
+}
+
+func TestCustomerLoyaltyUseCase_TopCustomers(t *testing.T) {
+	uc, uow := newTestLoyaltyUseCase(t)
+	tenantID := uuid.New()
+	small := createTestCustomerForUpdate(tenantID)
+	large := createTestCustomerForUpdate(tenantID)
+	uow.customerRepo.customers[small.ID] = small
+	uow.customerRepo.customers[large.ID] = large
+	now := time.Now().UTC()
+
+	recordTestPurchase(t, uc, small, uuid.New(), 300_00, now.Add(-24*time.Hour))
+	recordTestPurchase(t, uc, large, uuid.New(), 900_00, now.Add(-24*time.Hour))
+	recordTestPurchase(t, uc, large, uuid.New(), 5_000_00, now.AddDate(-2, 0, 0))
+
+	result, err := uc.TopCustomers(context.Background(), TopCustomersInput{TenantID: tenantID})
+	if err != nil {
+		t.Fatalf("TopCustomers() error = %v", err)
+	}
+	if len(result.Customers) != 2 {
+		t.Fatalf("got %d customers, want 2", len(result.Customers))
+	}
+	top := result.Customers[0]
+	if top.CustomerID != large.ID || top.Rank != 1 || top.OrderCount != 1 || top.Name != large.Name {
+		t.Errorf("top customer = %+v, want the larger customer with only the purchase in the last year", top)
+	}
+
+	from := now
+	to := now.Add(-time.Hour)
+	if _, err := uc.TopCustomers(context.Background(), TopCustomersInput{TenantID: tenantID, From: &from, To: &to}); err == nil {
+		t.Error("TopCustomers() should reject a period that ends before it starts")
+	}
+}
@@ -0,0 +1,255 @@
+// Package usecase contains the application use cases for the Customer service.
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/kilang-desa-murni/crm/internal/customer/application"
+	"github.com/kilang-desa-murni/crm/internal/customer/application/dto"
+	"github.com/kilang-desa-murni/crm/internal/customer/application/mapper"
+	"github.com/kilang-desa-murni/crm/internal/customer/application/ports"
+	"github.com/kilang-desa-murni/crm/internal/customer/domain"
+)
+
+// maxMergeSources is the most customers that can be merged into one at once.
+const maxMergeSources = 10
+
+// ============================================================================
+// Merge Customers Use Case
+// ============================================================================
+
+// MergeCustomersUseCase merges duplicate customers into one. The contacts,
+// phone numbers, addresses and tags of the source customers are copied to
+// the target customer and the sources are deleted, all in one transaction.
+type MergeCustomersUseCase struct {
+	uow            domain.UnitOfWork
+	idGenerator    ports.IDGenerator
+	cache          ports.CacheService
+	auditLogger    ports.AuditLogger
+	customerMapper *mapper.CustomerMapper
+}
+
+// NewMergeCustomersUseCase creates a new MergeCustomersUseCase.
+func NewMergeCustomersUseCase(
+	uow domain.UnitOfWork,
+	idGenerator ports.IDGenerator,
+	cache ports.CacheService,
+	auditLogger ports.AuditLogger,
+) *MergeCustomersUseCase {
+	return &MergeCustomersUseCase{
+		uow:            uow,
+		idGenerator:    idGenerator,
+		cache:          cache,
+		auditLogger:    auditLogger,
+		customerMapper: mapper.NewCustomerMapper(),
+	}
+}
+
+// MergeCustomersInput holds input for merging customers.
+type MergeCustomersInput struct {
+	TenantID  uuid.UUID
+	UserID    uuid.UUID
+	Request   *dto.MergeCustomersRequest
+	IPAddress string
+	UserAgent string
+}
+
+// Execute merges the source customers into the target customer.
+func (uc *MergeCustomersUseCase) Execute(ctx context.Context, input MergeCustomersInput) (*dto.CustomerResponse, error) {
+	if err := uc.validate(input); err != nil {
+		return nil, err
+	}
+	req := input.Request
+
+	var target *domain.Customer
+	err := uc.uow.WithTransaction(ctx, func(txCtx context.Context) error {
+		var err error
+		target, err = uc.findCustomer(txCtx, input.TenantID, req.TargetID)
+		if err != nil {
+			return err
+		}
+
+		sources := make([]*domain.Customer, 0, len(req.SourceIDs))
+		for _, sourceID := range req.SourceIDs {
+			source, err := uc.findCustomer(txCtx, input.TenantID, sourceID)
+			if err != nil {
+				return err
+			}
+			if err := mergeCustomer(target, source); err != nil {
+				return err
+			}
+			source.Delete(input.UserID)
+			sources = append(sources, source)
+		}
+
+		target.AuditInfo.SetUpdatedBy(input.UserID)
+		target.AddDomainEvent(domain.NewCustomerMergedEvent(target.ID, input.TenantID, req.SourceIDs, &input.UserID))
+
+		for _, customer := range append([]*domain.Customer{target}, sources...) {
+			if err := uc.save(txCtx, customer); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		var appErr *application.ApplicationError
+		if errors.As(err, &appErr) {
+			return nil, appErr
+		}
+		return nil, application.ErrInternalError("failed to merge customers", err)
+	}
+
+	if uc.cache != nil {
+		_ = uc.cache.Invalidate(ctx, "customer", target.ID)
+		for _, sourceID := range req.SourceIDs {
+			_ = uc.cache.Invalidate(ctx, "customer", sourceID)
+		}
+	}
+
+	if uc.auditLogger != nil {
+		_ = uc.auditLogger.LogAction(ctx, ports.AuditEntry{
+			ID:         uc.idGenerator.NewID(),
+			TenantID:   input.TenantID,
+			UserID:     &input.UserID,
+			Action:     "customers.merged",
+			EntityType: "customer",
+			EntityID:   target.ID,
+			Metadata: map[string]interface{}{
+				"source_ids": req.SourceIDs,
+			},
+			IPAddress: input.IPAddress,
+			UserAgent: input.UserAgent,
+			Timestamp: time.Now().UTC(),
+		})
+	}
+
+	return uc.customerMapper.ToResponse(target), nil
+}
+
+// validate checks the merge request.
+func (uc *MergeCustomersUseCase) validate(input MergeCustomersInput) error {
+	if input.TenantID == uuid.Nil || input.UserID == uuid.Nil {
+		return application.ErrInvalidInput("tenant_id and user_id are required")
+	}
+	if input.Request == nil || input.Request.TargetID == uuid.Nil {
+		return application.ErrInvalidInput("target_id is required")
+	}
+	if len(input.Request.SourceIDs) == 0 {
+		return application.ErrInvalidInput("source_ids are required")
+	}
+	if len(input.Request.SourceIDs) > maxMergeSources {
+		return application.ErrInvalidInput("at most 10 customers can be merged at once")
+	}
+
+	seen := map[uuid.UUID]bool{input.Request.TargetID: true}
+	for _, sourceID := range input.Request.SourceIDs {
+		if seen[sourceID] {
+			return application.ErrCustomerCannotMerge("a customer cannot be merged more than once or into itself")
+		}
+		seen[sourceID] = true
+	}
+	return nil
+}
+
+// findCustomer finds a customer of the tenant by ID.
+func (uc *MergeCustomersUseCase) findCustomer(ctx context.Context, tenantID, customerID uuid.UUID) (*domain.Customer, error) {
+	customer, err := uc.uow.Customers().FindByID(ctx, customerID)
+	if err != nil {
+		if domain.IsNotFoundError(err) {
+			return nil, application.ErrCustomerNotFound(customerID)
+		}
+		return nil, application.ErrInternalError("failed to find customer", err)
+	}
+	if customer.TenantID != tenantID {
+		return nil, application.ErrTenantMismatch(tenantID, customer.TenantID)
+	}
+	return customer, nil
+}
+
+// save persists a merged customer with its domain events.
+func (uc *MergeCustomersUseCase) save(ctx context.Context, customer *domain.Customer) error {
+	if err := uc.uow.Customers().Update(ctx, customer); err != nil {
+		if domain.IsConflictError(err) {
+			return application.ErrCustomerVersionConflict(customer.ID, customer.Version-1, customer.Version)
+		}
+		return err
+	}
+
+	for _, event := range customer.DomainEvents() {
+		payload, err := json.Marshal(event)
+		if err != nil {
+			return application.ErrInternalError("failed to serialize event", err)
+		}
+
+		outboxEntry := &domain.OutboxEntry{
+			ID:          uc.idGenerator.NewID(),
+			TenantID:    customer.TenantID,
+			EventType:   event.EventType(),
+			AggregateID: customer.ID,
+			Payload:     payload,
+			CreatedAt:   time.Now().UTC(),
+		}
+		if err := uc.uow.Outbox().Create(ctx, outboxEntry); err != nil {
+			return err
+		}
+	}
+	customer.ClearDomainEvents()
+	return nil
+}
+
+// mergeCustomer copies the contacts, phone numbers, addresses and tags of
+// source to target. Contacts and phone numbers the target already has are
+// skipped, and the target keeps its primary contact, phone and addresses.
+func mergeCustomer(target, source *domain.Customer) error {
+	if target.Email.IsEmpty() && !source.Email.IsEmpty() {
+		target.UpdateEmail(source.Email)
+	}
+
+	for _, contact := range source.Contacts {
+		contact.CustomerID = target.ID
+		contact.SetPrimary(false)
+		if err := target.AddContact(&contact); err != nil {
+			if errors.Is(err, domain.ErrDuplicateContactEmail) {
+				continue
+			}
+			return application.ErrCustomerCannotMerge(err.Error())
+		}
+	}
+
+	for _, phone := range source.PhoneNumbers {
+		if hasPhone(target, phone.E164()) {
+			continue
+		}
+		phone.SetPrimary(false)
+		target.AddPhone(phone)
+	}
+
+	for _, address := range source.Addresses {
+		address.ID = uuid.Nil
+		address.IsPrimary = false
+		if err := target.AddAddress(address); err != nil {
+			return application.ErrCustomerCannotMerge(err.Error())
+		}
+	}
+
+	for _, tag := range source.Tags {
+		target.AddTag(tag)
+	}
+	return nil
+}
+
+// hasPhone reports whether the customer has the phone number.
+func hasPhone(customer *domain.Customer, e164 string) bool {
+	for _, phone := range customer.PhoneNumbers {
+		if phone.E164() == e164 {
+			return true
+		}
+	}
+	return false
+}
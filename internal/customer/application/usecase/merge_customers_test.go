@@ -0,0 +1,132 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"github.com/kilang-desa-murni/crm/internal/customer/application"
+	"github.com/kilang-desa-murni/crm/internal/customer/application/dto"
+	"github.com/kilang-desa-murni/crm/internal/customer/domain"
+)
+
+func newTestMergeUseCase() (*MergeCustomersUseCase, *MockUnitOfWork) {
+	uow := NewMockUnitOfWork()
+	uc := NewMergeCustomersUseCase(uow, NewMockIDGenerator(), NewMockCustomerCacheService(), NewMockCustomerAuditLogger())
+	return uc, uow
+}
+
+func createTestDuplicateCustomer(tenantID uuid.UUID, contactEmail string) *domain.Customer {
+	customer, _ := domain.NewCustomer(tenantID, "Test Company Duplicate", domain.CustomerTypeCompany)
+	customer.Version = 1
+	customer.AddTag("batik")
+
+	contact, _ := domain.NewContact(tenantID, customer.ID, "Siti", "Aminah", contactEmail)
+	customer.Contacts = append(customer.Contacts, *contact)
+	customer.ClearDomainEvents()
+	return customer
+}
+
+func TestMergeCustomersUseCase_Execute_Success(t *testing.T) {
+	uc, uow := newTestMergeUseCase()
+	tenantID := uuid.New()
+
+	target := createTestCustomerWithContacts(tenantID)
+	source := createTestDuplicateCustomer(tenantID, "siti@example.com")
+	uow.customerRepo.customers[target.ID] = target
+	uow.customerRepo.customers[source.ID] = source
+
+	result, err := uc.Execute(context.Background(), MergeCustomersInput{
+		TenantID: tenantID,
+		UserID:   uuid.New(),
+		Request:  &dto.MergeCustomersRequest{TargetID: target.ID, SourceIDs: []uuid.UUID{source.ID}},
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if result.ID != target.ID {
+		t.Errorf("Expected the target customer, got %s", result.ID)
+	}
+
+	if len(target.Contacts) != 2 {
+		t.Fatalf("Expected 2 contacts, got %d", len(target.Contacts))
+	}
+	if target.Contacts[1].CustomerID != target.ID || target.Contacts[1].IsPrimary {
+		t.Errorf("Expected the merged contact to belong to the target and not be primary, got %+v", target.Contacts[1])
+	}
+	if !target.HasTag("batik") {
+		t.Error("Expected the source tags on the target")
+	}
+	if !source.IsDeleted() {
+		t.Error("Expected the source customer to be deleted")
+	}
+
+	merged := false
+	for _, entry := range uow.outboxRepo.entries {
+		if entry.EventType == domain.EventTypeCustomerMerged && entry.AggregateID == target.ID {
+			merged = true
+		}
+	}
+	if !merged {
+		t.Error("Expected a customer.merged event in the outbox")
+	}
+}
+
+func TestMergeCustomersUseCase_Execute_SkipsDuplicateContacts(t *testing.T) {
+	uc, uow := newTestMergeUseCase()
+	tenantID := uuid.New()
+
+	target := createTestCustomerWithContacts(tenantID)
+	source := createTestDuplicateCustomer(tenantID, "john@example.com")
+	uow.customerRepo.customers[target.ID] = target
+	uow.customerRepo.customers[source.ID] = source
+
+	_, err := uc.Execute(context.Background(), MergeCustomersInput{
+		TenantID: tenantID,
+		UserID:   uuid.New(),
+		Request:  &dto.MergeCustomersRequest{TargetID: target.ID, SourceIDs: []uuid.UUID{source.ID}},
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if len(target.Contacts) != 1 {
+		t.Errorf("Expected the duplicate contact skipped, got %d contacts", len(target.Contacts))
+	}
+}
+
+func TestMergeCustomersUseCase_Execute_InvalidRequests(t *testing.T) {
+	tenantID := uuid.New()
+	targetID := uuid.New()
+
+	tests := []struct {
+		name     string
+		request  *dto.MergeCustomersRequest
+		wantCode string
+	}{
+		{"missing sources", &dto.MergeCustomersRequest{TargetID: targetID}, application.ErrCodeInvalidInput},
+		{"target among sources", &dto.MergeCustomersRequest{TargetID: targetID, SourceIDs: []uuid.UUID{targetID}}, application.ErrCodeCustomerCannotMerge},
+		{"unknown source", &dto.MergeCustomersRequest{TargetID: targetID, SourceIDs: []uuid.UUID{uuid.New()}}, application.ErrCodeCustomerNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			uc, uow := newTestMergeUseCase()
+			target := createTestCustomerWithContacts(tenantID)
+			target.ID = targetID
+			uow.customerRepo.customers[target.ID] = target
+
+			_, err := uc.Execute(context.Background(), MergeCustomersInput{
+				TenantID: tenantID,
+				UserID:   uuid.New(),
+				Request:  tt.request,
+			})
+
+			var appErr *application.ApplicationError
+			if !errors.As(err, &appErr) || appErr.Code != tt.wantCode {
+				t.Errorf("Expected %s, got %v", tt.wantCode, err)
+			}
+		})
+	}
+}
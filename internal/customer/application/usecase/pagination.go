@@ -0,0 +1,58 @@
+package usecase
+
+import (
+	"strings"
+	"time"
+
+	"github.com/kilang-desa-murni/crm/internal/customer/application"
+	"github.com/kilang-desa-murni/crm/internal/customer/application/dto"
+	"github.com/kilang-desa-murni/crm/internal/customer/domain"
+	"github.com/kilang-desa-murni/crm/pkg/response"
+)
+
+// customerCursorSorts maps the sort fields customer lists can be paged on by
+// cursor to the value of that field on a customer.
+var customerCursorSorts = map[string]func(*domain.Customer) string{
+	"created_at": func(c *domain.Customer) string { return c.CreatedAt.UTC().Format(time.RFC3339Nano) },
+	"updated_at": func(c *domain.Customer) string { return c.UpdatedAt.UTC().Format(time.RFC3339Nano) },
+	"name":       func(c *domain.Customer) string { return c.Name },
+	"code":       func(c *domain.Customer) string { return c.Code },
+}
+
+// customerCursorAfter decodes a request cursor into the position a customer
+// list continues after. An empty cursor starts from the first page.
+func customerCursorAfter(raw, sortBy, sortOrder string) (*domain.CustomerCursor, error) {
+	if raw == "" {
+		return nil, nil
+	}
+	if _, ok := customerCursorSorts[sortBy]; !ok {
+		return nil, application.ErrInvalidInput("cursor pagination is not supported when sorting by " + sortBy)
+	}
+
+	cursor, err := response.DecodeCursor(raw, sortBy, strings.ToLower(sortOrder))
+	if err != nil {
+		return nil, application.ErrInvalidInput("invalid cursor")
+	}
+	return &domain.CustomerCursor{SortValue: cursor.Value, Code: cursor.ID}, nil
+}
+
+// setNextCursor sets the cursor of the next page on a customer list. A page
+// continued from a cursor has no meaningful offset, so whether more customers
+// follow is judged by whether the page came back full.
+func setNextCursor(list *dto.CustomerListResponse, customers []*domain.Customer, filter domain.CustomerFilter) {
+	if filter.After != nil {
+		list.HasMore = len(customers) == filter.Limit
+	}
+
+	value, ok := customerCursorSorts[filter.SortBy]
+	if !ok || !list.HasMore || len(customers) == 0 {
+		return
+	}
+	last := customers[len(customers)-1]
+	list.NextCursor = response.Cursor{
+		SortBy:    filter.SortBy,
+		SortOrder: strings.ToLower(filter.SortOrder),
+		Value:     value(last),
+		ID:        last.Code,
+	}.Encode()
+}
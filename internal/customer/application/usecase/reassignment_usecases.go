@@ -0,0 +1,167 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/kilang-desa-murni/crm/internal/customer/application"
+	"github.com/kilang-desa-murni/crm/internal/customer/application/ports"
+	"github.com/kilang-desa-murni/crm/internal/customer/domain"
+)
+
+// reassignBatchSize is the number of a user's customers read per repository
+// query when collecting the customers to reassign.
+const reassignBatchSize = 200
+
+// ============================================================================
+// Owner Reassignment Use Case
+// ============================================================================
+
+// OwnerReassignmentUseCase transfers the customers owned by a user to other
+// users. The IAM service calls it when a user leaves, so their customers are
+// not orphaned.
+type OwnerReassignmentUseCase struct {
+	uow         domain.UnitOfWork
+	idGenerator ports.IDGenerator
+	cache       ports.CacheService
+}
+
+// NewOwnerReassignmentUseCase creates a new OwnerReassignmentUseCase.
+func NewOwnerReassignmentUseCase(
+	uow domain.UnitOfWork,
+	idGenerator ports.IDGenerator,
+	cache ports.CacheService,
+) *OwnerReassignmentUseCase {
+	return &OwnerReassignmentUseCase{
+		uow:         uow,
+		idGenerator: idGenerator,
+		cache:       cache,
+	}
+}
+
+// ReassignCustomersInput holds the user whose customers are reassigned and
+// the users receiving them.
+type ReassignCustomersInput struct {
+	TenantID   uuid.UUID           `json:"-"`
+	FromUserID uuid.UUID           `json:"from_user_id"`
+	Recipients []ReassignRecipient `json:"recipients"`
+}
+
+// ReassignRecipient is a user receiving reassigned customers.
+type ReassignRecipient struct {
+	UserID uuid.UUID `json:"user_id"`
+	Name   string    `json:"name,omitempty"`
+}
+
+// ReassignCustomersOutput holds the outcome of a reassignment: the customers
+// transferred, those that could not be, and the number each recipient
+// received by user ID.
+type ReassignCustomersOutput struct {
+	Transferred int64            `json:"transferred"`
+	Failed      int64            `json:"failed"`
+	Assigned    map[string]int64 `json:"assigned"`
+}
+
+// ReassignCustomers transfers the user's customers to the recipients in turn,
+// starting with the first. Deleted and churned customers keep their owner,
+// and a customer that fails to save is counted as failed.
+func (uc *OwnerReassignmentUseCase) ReassignCustomers(ctx context.Context, input ReassignCustomersInput) (*ReassignCustomersOutput, error) {
+	if input.FromUserID == uuid.Nil {
+		return nil, application.ErrInvalidInput("from_user_id is required")
+	}
+	if len(input.Recipients) == 0 {
+		return nil, application.ErrInvalidInput("at least one recipient is required")
+	}
+	for _, recipient := range input.Recipients {
+		if recipient.UserID == uuid.Nil || recipient.UserID == input.FromUserID {
+			return nil, application.ErrInvalidInput("recipients must be other users")
+		}
+	}
+
+	// Collect the customers before reassigning any, as each one reassigned
+	// leaves the user's pages
+	var customers []*domain.Customer
+	for offset := 0; ; offset += reassignBatchSize {
+		list, err := uc.uow.Customers().FindByOwner(ctx, input.TenantID, input.FromUserID, domain.CustomerFilter{
+			Offset:    offset,
+			Limit:     reassignBatchSize,
+			SortBy:    "created_at",
+			SortOrder: "asc",
+		})
+		if err != nil {
+			return nil, application.ErrInternalError("failed to find customers", err)
+		}
+		for _, customer := range list.Customers {
+			if !customer.IsDeleted() && customer.Status != domain.CustomerStatusChurned {
+				customers = append(customers, customer)
+			}
+		}
+		if len(list.Customers) < reassignBatchSize || int64(offset+reassignBatchSize) >= list.Total {
+			break
+		}
+	}
+
+	output := &ReassignCustomersOutput{Assigned: make(map[string]int64)}
+	for _, customer := range customers {
+		recipient := input.Recipients[output.Transferred%int64(len(input.Recipients))]
+		customer.AssignOwner(recipient.UserID)
+		if err := uc.save(ctx, customer); err != nil {
+			customer.ClearDomainEvents()
+			output.Failed++
+			continue
+		}
+		output.Transferred++
+		output.Assigned[recipient.UserID.String()]++
+	}
+
+	return output, nil
+}
+
+// save persists the customer with its domain events.
+func (uc *OwnerReassignmentUseCase) save(ctx context.Context, customer *domain.Customer) error {
+	txCtx, err := uc.uow.Begin(ctx)
+	if err != nil {
+		return application.ErrInternalError("failed to begin transaction", err)
+	}
+	defer uc.uow.Rollback(txCtx)
+
+	if err := uc.uow.Customers().Update(txCtx, customer); err != nil {
+		if domain.IsConflictError(err) {
+			return application.ErrCustomerVersionConflict(customer.ID, customer.Version-1, customer.Version)
+		}
+		return application.ErrInternalError("failed to update customer", err)
+	}
+
+	for _, event := range customer.DomainEvents() {
+		payload, err := json.Marshal(event)
+		if err != nil {
+			return application.ErrInternalError("failed to serialize event", err)
+		}
+
+		outboxEntry := &domain.OutboxEntry{
+			ID:          uc.idGenerator.NewID(),
+			TenantID:    customer.TenantID,
+			EventType:   event.EventType(),
+			AggregateID: customer.ID,
+			Payload:     payload,
+			CreatedAt:   time.Now().UTC(),
+		}
+
+		if err := uc.uow.Outbox().Create(txCtx, outboxEntry); err != nil {
+			return application.ErrInternalError("failed to save outbox entry", err)
+		}
+	}
+
+	if err := uc.uow.Commit(txCtx); err != nil {
+		return application.ErrInternalError("failed to commit transaction", err)
+	}
+	customer.ClearDomainEvents()
+
+	if uc.cache != nil {
+		_ = uc.cache.Invalidate(ctx, "customer", customer.ID)
+	}
+	return nil
+}
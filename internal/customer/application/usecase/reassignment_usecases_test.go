@@ -0,0 +1,77 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"github.com/kilang-desa-murni/crm/internal/customer/domain"
+)
+
+// ============================================================================
+// OwnerReassignmentUseCase Tests
+// ============================================================================
+
+func TestOwnerReassignmentUseCase_ReassignCustomers(t *testing.T) {
+	uow := NewMockUnitOfWork()
+	uc := NewOwnerReassignmentUseCase(uow, NewMockIDGenerator(), NewMockCustomerCacheService())
+
+	tenantID := uuid.New()
+	leaver, first, second := uuid.New(), uuid.New(), uuid.New()
+
+	for i := 0; i < 3; i++ {
+		customer := createTestCustomerForUpdate(tenantID)
+		customer.OwnerID = &leaver
+		customer.ClearDomainEvents()
+		uow.customerRepo.customers[customer.ID] = customer
+	}
+	churned := createTestCustomerForUpdate(tenantID)
+	churned.OwnerID = &leaver
+	churned.Status = domain.CustomerStatusChurned
+	uow.customerRepo.customers[churned.ID] = churned
+
+	output, err := uc.ReassignCustomers(context.Background(), ReassignCustomersInput{
+		TenantID:   tenantID,
+		FromUserID: leaver,
+		Recipients: []ReassignRecipient{{UserID: first}, {UserID: second}},
+	})
+	if err != nil {
+		t.Fatalf("ReassignCustomers() error = %v", err)
+	}
+
+	if output.Transferred != 3 || output.Failed != 0 {
+		t.Errorf("transferred %d (%d failed), want 3", output.Transferred, output.Failed)
+	}
+	if output.Assigned[first.String()] != 2 || output.Assigned[second.String()] != 1 {
+		t.Errorf("assigned = %v, want 2 and 1 in turn", output.Assigned)
+	}
+	if *churned.OwnerID != leaver {
+		t.Error("a churned customer was reassigned")
+	}
+	if len(uow.outboxRepo.entries) != 3 {
+		t.Errorf("outbox has %d entries, want one owner assigned event per customer", len(uow.outboxRepo.entries))
+	}
+}
+
+func TestOwnerReassignmentUseCase_ReassignCustomers_InvalidInput(t *testing.T) {
+	uc := NewOwnerReassignmentUseCase(NewMockUnitOfWork(), NewMockIDGenerator(), nil)
+	leaver := uuid.New()
+
+	tests := []struct {
+		name  string
+		input ReassignCustomersInput
+	}{
+		{"no recipients", ReassignCustomersInput{TenantID: uuid.New(), FromUserID: leaver}},
+		{"recipient is the owner", ReassignCustomersInput{TenantID: uuid.New(), FromUserID: leaver, Recipients: []ReassignRecipient{{UserID: leaver}}}},
+		{"no owner", ReassignCustomersInput{TenantID: uuid.New(), Recipients: []ReassignRecipient{{UserID: uuid.New()}}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := uc.ReassignCustomers(context.Background(), tt.input); err == nil {
+				t.Error("ReassignCustomers() error = nil, want an invalid input error")
+			}
+		})
+	}
+}
@@ -91,9 +91,24 @@ func (uc *SearchCustomersUseCase) Execute(ctx context.Context, input SearchCusto
 	filter.Limit = limit
 	filter.SortBy = sortBy
 	filter.SortOrder = sortOrder
+	if err := parseCustomerListQuery(input.Request.Filter, input.Request.Sort, &filter); err != nil {
+		return nil, err
+	}
 
-	// Try full-text search if query is provided and search index is available
-	if input.Request.Query != "" && uc.searchIndex != nil && uc.config.EnableFullText {
+	// Cursors page through a stable sort, which relevance ranking is not
+	if input.Request.Cursor != "" && input.Request.Query != "" {
+		return nil, application.ErrInvalidInput("cursor pagination is not supported with a search query")
+	}
+	after, err := customerCursorAfter(input.Request.Cursor, filter.SortBy, filter.SortOrder)
+	if err != nil {
+		return nil, err
+	}
+	filter.After = after
+
+	// Try full-text search if query is provided and search index is available.
+	// The index knows nothing of filter expressions, so those go to the
+	// repository.
+	if input.Request.Query != "" && uc.searchIndex != nil && uc.config.EnableFullText && len(filter.Expr) == 0 {
 		return uc.fullTextSearch(ctx, input.TenantID, input.Request.Query, filter)
 	}
 
@@ -180,7 +195,11 @@ func (uc *SearchCustomersUseCase) repositorySearch(ctx context.Context, tenantID
 		return nil, application.ErrInternalError("failed to search customers", err)
 	}
 
-	return uc.customerMapper.ToListResponse(customerList.Customers, customerList.Total, customerList.Offset, customerList.Limit), nil
+	result := uc.customerMapper.ToListResponse(customerList.Customers, customerList.Total, customerList.Offset, customerList.Limit)
+	if query == "" {
+		setNextCursor(result, customerList.Customers, filter)
+	}
+	return result, nil
 }
 
 // ============================================================================
@@ -214,8 +233,11 @@ type ListCustomersInput struct {
 	TenantID  uuid.UUID
 	Offset    int
 	Limit     int
+	Cursor    string
 	SortBy    string
 	SortOrder string
+	Filter    string // Filter expression, e.g. "status:eq:active"
+	Sort      string // Sort expression, e.g. "-created_at"; takes precedence over SortBy
 }
 
 // Execute lists customers.
@@ -247,6 +269,15 @@ func (uc *ListCustomersUseCase) Execute(ctx context.Context, input ListCustomers
 		SortBy:    input.SortBy,
 		SortOrder: input.SortOrder,
 	}
+	if err := parseCustomerListQuery(input.Filter, input.Sort, &filter); err != nil {
+		return nil, err
+	}
+
+	after, err := customerCursorAfter(input.Cursor, filter.SortBy, filter.SortOrder)
+	if err != nil {
+		return nil, err
+	}
+	filter.After = after
 
 	// List customers
 	customerList, err := uc.uow.Customers().List(ctx, filter)
@@ -254,7 +285,9 @@ func (uc *ListCustomersUseCase) Execute(ctx context.Context, input ListCustomers
 		return nil, application.ErrInternalError("failed to list customers", err)
 	}
 
-	return uc.customerMapper.ToListResponse(customerList.Customers, customerList.Total, customerList.Offset, customerList.Limit), nil
+	result := uc.customerMapper.ToListResponse(customerList.Customers, customerList.Total, customerList.Offset, customerList.Limit)
+	setNextCursor(result, customerList.Customers, filter)
+	return result, nil
 }
 
 // ============================================================================
@@ -281,6 +314,7 @@ type ListByOwnerInput struct {
 	OwnerID  uuid.UUID
 	Offset   int
 	Limit    int
+	Cursor   string
 }
 
 // Execute lists customers by owner.
@@ -294,9 +328,17 @@ func (uc *ListCustomersByOwnerUseCase) Execute(ctx context.Context, input ListBy
 		input.Limit = 20
 	}
 
+	after, err := customerCursorAfter(input.Cursor, "created_at", "desc")
+	if err != nil {
+		return nil, err
+	}
+
 	filter := domain.CustomerFilter{
-		Offset: input.Offset,
-		Limit:  input.Limit,
+		Offset:    input.Offset,
+		Limit:     input.Limit,
+		SortBy:    "created_at",
+		SortOrder: "desc",
+		After:     after,
 	}
 
 	customerList, err := uc.uow.Customers().FindByOwner(ctx, input.TenantID, input.OwnerID, filter)
@@ -304,7 +346,9 @@ func (uc *ListCustomersByOwnerUseCase) Execute(ctx context.Context, input ListBy
 		return nil, application.ErrInternalError("failed to list customers by owner", err)
 	}
 
-	return uc.customerMapper.ToListResponse(customerList.Customers, customerList.Total, customerList.Offset, customerList.Limit), nil
+	result := uc.customerMapper.ToListResponse(customerList.Customers, customerList.Total, customerList.Offset, customerList.Limit)
+	setNextCursor(result, customerList.Customers, filter)
+	return result, nil
 }
 
 // ============================================================================
@@ -331,6 +375,7 @@ type ListByStatusInput struct {
 	Status   domain.CustomerStatus
 	Offset   int
 	Limit    int
+	Cursor   string
 }
 
 // Execute lists customers by status.
@@ -344,9 +389,17 @@ func (uc *ListCustomersByStatusUseCase) Execute(ctx context.Context, input ListB
 		input.Limit = 20
 	}
 
+	after, err := customerCursorAfter(input.Cursor, "created_at", "desc")
+	if err != nil {
+		return nil, err
+	}
+
 	filter := domain.CustomerFilter{
-		Offset: input.Offset,
-		Limit:  input.Limit,
+		Offset:    input.Offset,
+		Limit:     input.Limit,
+		SortBy:    "created_at",
+		SortOrder: "desc",
+		After:     after,
 	}
 
 	customerList, err := uc.uow.Customers().FindByStatus(ctx, input.TenantID, input.Status, filter)
@@ -354,7 +407,9 @@ func (uc *ListCustomersByStatusUseCase) Execute(ctx context.Context, input ListB
 		return nil, application.ErrInternalError("failed to list customers by status", err)
 	}
 
-	return uc.customerMapper.ToListResponse(customerList.Customers, customerList.Total, customerList.Offset, customerList.Limit), nil
+	result := uc.customerMapper.ToListResponse(customerList.Customers, customerList.Total, customerList.Offset, customerList.Limit)
+	setNextCursor(result, customerList.Customers, filter)
+	return result, nil
 }
 
 // ============================================================================
@@ -381,6 +436,7 @@ type ListByTagInput struct {
 	Tag      string
 	Offset   int
 	Limit    int
+	Cursor   string
 }
 
 // Execute lists customers by tag.
@@ -397,9 +453,17 @@ func (uc *ListCustomersByTagUseCase) Execute(ctx context.Context, input ListByTa
 		input.Limit = 20
 	}
 
+	after, err := customerCursorAfter(input.Cursor, "created_at", "desc")
+	if err != nil {
+		return nil, err
+	}
+
 	filter := domain.CustomerFilter{
-		Offset: input.Offset,
-		Limit:  input.Limit,
+		Offset:    input.Offset,
+		Limit:     input.Limit,
+		SortBy:    "created_at",
+		SortOrder: "desc",
+		After:     after,
 	}
 
 	customerList, err := uc.uow.Customers().FindByTag(ctx, input.TenantID, input.Tag, filter)
@@ -407,7 +471,9 @@ func (uc *ListCustomersByTagUseCase) Execute(ctx context.Context, input ListByTa
 		return nil, application.ErrInternalError("failed to list customers by tag", err)
 	}
 
-	return uc.customerMapper.ToListResponse(customerList.Customers, customerList.Total, customerList.Offset, customerList.Limit), nil
+	result := uc.customerMapper.ToListResponse(customerList.Customers, customerList.Total, customerList.Offset, customerList.Limit)
+	setNextCursor(result, customerList.Customers, filter)
+	return result, nil
 }
 
 // ============================================================================
@@ -0,0 +1,378 @@
+// Package usecase contains the application use cases for the Customer service.
+package usecase
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/kilang-desa-murni/crm/internal/customer/application"
+	"github.com/kilang-desa-murni/crm/internal/customer/application/ports"
+	"github.com/kilang-desa-murni/crm/internal/customer/domain"
+)
+
+// ============================================================================
+// Import Upload Use Cases
+// ============================================================================
+
+// UploadConfig holds configuration for resumable import uploads.
+type UploadConfig struct {
+	// MaxFileSize is the largest file that may be uploaded; the import's
+	// own limit applies too.
+	MaxFileSize int64
+	// MaxChunkSize is the largest chunk a request may carry.
+	MaxChunkSize int64
+	// Expiry is how long an upload is kept after its last chunk before it
+	// is deleted as abandoned, and after it is completed.
+	Expiry time.Duration
+	// PurgeBatchSize bounds the expired uploads deleted in one run.
+	PurgeBatchSize int
+}
+
+// DefaultUploadConfig returns the default upload configuration.
+func DefaultUploadConfig() UploadConfig {
+	return UploadConfig{
+		MaxFileSize:    256 * 1024 * 1024, // 256MB
+		MaxChunkSize:   8 * 1024 * 1024,   // 8MB
+		Expiry:         24 * time.Hour,
+		PurgeBatchSize: 100,
+	}
+}
+
+// ImportUploadUseCase uploads large import files in chunks. A client
+// initiates an upload with the file's size and SHA-256 checksum, sends the
+// file in chunks, resuming from the offset received after a broken
+// connection, and completes it; the file is then checked against its
+// checksum and imported. Uploads abandoned for longer than the expiry are
+// deleted.
+type ImportUploadUseCase struct {
+	uploads  domain.ImportUploadRepository
+	storage  ports.UploadStorage
+	importer *ImportCustomersUseCase
+	config   UploadConfig
+}
+
+// NewImportUploadUseCase creates a new ImportUploadUseCase.
+func NewImportUploadUseCase(
+	uploads domain.ImportUploadRepository,
+	storage ports.UploadStorage,
+	importer *ImportCustomersUseCase,
+	config UploadConfig,
+) *ImportUploadUseCase {
+	defaults := DefaultUploadConfig()
+	if config.MaxFileSize <= 0 {
+		config.MaxFileSize = defaults.MaxFileSize
+	}
+	if config.MaxChunkSize <= 0 {
+		config.MaxChunkSize = defaults.MaxChunkSize
+	}
+	if config.Expiry <= 0 {
+		config.Expiry = defaults.Expiry
+	}
+	if config.PurgeBatchSize <= 0 {
+		config.PurgeBatchSize = defaults.PurgeBatchSize
+	}
+
+	return &ImportUploadUseCase{
+		uploads:  uploads,
+		storage:  storage,
+		importer: importer,
+		config:   config,
+	}
+}
+
+// MaxChunkSize returns the largest chunk a request may carry.
+func (uc *ImportUploadUseCase) MaxChunkSize() int64 {
+	return uc.config.MaxChunkSize
+}
+
+// InitiateUploadInput holds input for initiating an import upload.
+type InitiateUploadInput struct {
+	TenantID uuid.UUID
+	UserID   uuid.UUID
+	FileName string
+	Format   string // Taken from the file name's extension when empty
+	Size     int64
+	Checksum string // Hex SHA-256 of the whole file
+}
+
+// Initiate starts an upload.
+func (uc *ImportUploadUseCase) Initiate(ctx context.Context, input InitiateUploadInput) (*domain.ImportUpload, error) {
+	if input.TenantID == uuid.Nil {
+		return nil, application.ErrInvalidInput("tenant_id is required")
+	}
+	if input.UserID == uuid.Nil {
+		return nil, application.ErrInvalidInput("user_id is required")
+	}
+
+	maxSize := min(uc.config.MaxFileSize, uc.importer.config.MaxFileSize)
+	if input.Size > maxSize {
+		return nil, application.ErrFileTooLarge(input.Size, maxSize)
+	}
+
+	format := strings.ToLower(input.Format)
+	if format == "" {
+		format = strings.TrimPrefix(strings.ToLower(filepath.Ext(input.FileName)), ".")
+	}
+	supported := false
+	for _, f := range uc.importer.config.SupportedFormats {
+		if f == format {
+			supported = true
+			break
+		}
+	}
+	if !supported {
+		return nil, application.ErrInvalidFormat(format, uc.importer.config.SupportedFormats)
+	}
+
+	upload, err := domain.NewImportUpload(input.TenantID, input.UserID, input.FileName, format, input.Size, input.Checksum, time.Now().Add(uc.config.Expiry))
+	if err != nil {
+		return nil, application.ErrInvalidInput(err.Error())
+	}
+	if err := uc.uploads.Create(ctx, upload); err != nil {
+		return nil, application.ErrInternalError("failed to create upload", err)
+	}
+	return upload, nil
+}
+
+// Get returns an upload of the tenant, such as to learn its offset before
+// resuming it.
+func (uc *ImportUploadUseCase) Get(ctx context.Context, tenantID, uploadID uuid.UUID) (*domain.ImportUpload, error) {
+	upload, err := uc.uploads.FindByID(ctx, uploadID)
+	if err != nil {
+		if errors.Is(err, domain.ErrUploadNotFound) {
+			return nil, application.ErrUploadNotFound(uploadID)
+		}
+		return nil, application.ErrInternalError("failed to find upload", err)
+	}
+	// Another tenant's upload is reported as missing rather than forbidden,
+	// so upload IDs cannot be probed
+	if upload.TenantID != tenantID {
+		return nil, application.ErrUploadNotFound(uploadID)
+	}
+	return upload, nil
+}
+
+// AppendUploadInput holds input for appending a chunk to an upload.
+type AppendUploadInput struct {
+	TenantID uuid.UUID
+	UploadID uuid.UUID
+	Offset   int64
+	Data     []byte
+	Checksum string // Optional hex SHA-256 of the chunk
+}
+
+// Append writes a chunk of an upload. The chunk must start where the bytes
+// received end; a chunk whose checksum does not match is refused, so the
+// client resends it.
+func (uc *ImportUploadUseCase) Append(ctx context.Context, input AppendUploadInput) (*domain.ImportUpload, error) {
+	if int64(len(input.Data)) > uc.config.MaxChunkSize {
+		return nil, application.ErrFileTooLarge(int64(len(input.Data)), uc.config.MaxChunkSize)
+	}
+	if input.Checksum != "" {
+		sum := sha256.Sum256(input.Data)
+		if !strings.EqualFold(hex.EncodeToString(sum[:]), input.Checksum) {
+			return nil, application.ErrUploadChecksumMismatch("chunk")
+		}
+	}
+
+	upload, err := uc.Get(ctx, input.TenantID, input.UploadID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	if err := upload.Append(input.Offset, int64(len(input.Data)), now, now.Add(uc.config.Expiry)); err != nil {
+		return nil, uc.uploadError(upload, input.Offset, err)
+	}
+
+	// The chunk is written before the offset is recorded, so the bytes
+	// recorded are always stored. Chunks raced at the same offset may
+	// overwrite each other's bytes; the checksum of the file catches that
+	if err := uc.storage.WriteAt(ctx, upload.ID, input.Offset, input.Data); err != nil {
+		return nil, application.ErrInternalError("failed to store chunk", err)
+	}
+	if err := uc.uploads.Update(ctx, upload); err != nil {
+		if errors.Is(err, domain.ErrVersionConflict) {
+			return nil, application.ErrUploadOffsetMismatch(input.Offset, upload.Offset)
+		}
+		return nil, application.ErrInternalError("failed to update upload", err)
+	}
+	return upload, nil
+}
+
+// CompleteUploadInput holds input for completing an upload. The options are
+// those of the import.
+type CompleteUploadInput struct {
+	TenantID       uuid.UUID
+	UserID         uuid.UUID
+	UploadID       uuid.UUID
+	FieldMapping   map[string]string
+	SkipDuplicates bool
+	UpdateExisting bool
+	DefaultOwner   *uuid.UUID
+	DefaultTags    []string
+	IPAddress      string
+	UserAgent      string
+}
+
+// CompleteUploadOutput holds the result of completing an upload.
+type CompleteUploadOutput struct {
+	Upload *domain.ImportUpload   `json:"upload"`
+	Import *ImportCustomersOutput `json:"import"`
+}
+
+// Complete checks the uploaded file against its checksum and imports it. A
+// file that does not match is deleted with its upload, as it cannot be
+// repaired by resending chunks. If the import fails the upload is kept, so
+// completing it may be retried.
+func (uc *ImportUploadUseCase) Complete(ctx context.Context, input CompleteUploadInput) (*CompleteUploadOutput, error) {
+	upload, err := uc.Get(ctx, input.TenantID, input.UploadID)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	if upload.Status == domain.UploadStatusCompleted {
+		return nil, application.ErrUploadCompleted(upload.ID, upload.ImportID)
+	}
+	if upload.IsExpired(now) {
+		return nil, application.ErrUploadExpired(upload.ID)
+	}
+	if !upload.IsComplete() {
+		return nil, application.ErrUploadIncomplete(upload.Offset, upload.Size)
+	}
+
+	data, err := uc.read(ctx, upload)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := uc.importer.Execute(ctx, ImportCustomersInput{
+		TenantID:       upload.TenantID,
+		UserID:         input.UserID,
+		FileName:       upload.FileName,
+		FileSize:       upload.Size,
+		Format:         upload.Format,
+		Data:           data,
+		FieldMapping:   input.FieldMapping,
+		SkipDuplicates: input.SkipDuplicates,
+		UpdateExisting: input.UpdateExisting,
+		DefaultOwner:   input.DefaultOwner,
+		DefaultTags:    input.DefaultTags,
+		IPAddress:      input.IPAddress,
+		UserAgent:      input.UserAgent,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	now = time.Now()
+	if err := upload.Complete(result.ImportID, now, now.Add(uc.config.Expiry)); err != nil {
+		return nil, uc.uploadError(upload, upload.Offset, err)
+	}
+	if err := uc.uploads.Update(ctx, upload); err != nil {
+		return nil, application.ErrInternalError("failed to update upload", err)
+	}
+	// The file is no longer needed; one left behind is deleted when the
+	// upload expires
+	_ = uc.storage.Delete(ctx, upload.ID)
+
+	return &CompleteUploadOutput{Upload: upload, Import: result}, nil
+}
+
+// read reads an upload's file and checks it against the checksum.
+func (uc *ImportUploadUseCase) read(ctx context.Context, upload *domain.ImportUpload) ([]byte, error) {
+	file, err := uc.storage.Open(ctx, upload.ID)
+	if err != nil {
+		return nil, application.ErrInternalError("failed to open uploaded file", err)
+	}
+	defer file.Close()
+
+	var data bytes.Buffer
+	data.Grow(int(upload.Size))
+	hash := sha256.New()
+	if _, err := io.Copy(io.MultiWriter(&data, hash), io.LimitReader(file, upload.Size+1)); err != nil {
+		return nil, application.ErrInternalError("failed to read uploaded file", err)
+	}
+
+	if int64(data.Len()) != upload.Size || hex.EncodeToString(hash.Sum(nil)) != upload.Checksum {
+		if err := uc.delete(ctx, upload.ID); err != nil {
+			return nil, application.ErrInternalError("failed to delete corrupt upload", err)
+		}
+		return nil, application.ErrUploadChecksumMismatch("file")
+	}
+	return data.Bytes(), nil
+}
+
+// Abort deletes an upload and its file.
+func (uc *ImportUploadUseCase) Abort(ctx context.Context, tenantID, uploadID uuid.UUID) error {
+	upload, err := uc.Get(ctx, tenantID, uploadID)
+	if err != nil {
+		return err
+	}
+	if err := uc.delete(ctx, upload.ID); err != nil {
+		return application.ErrInternalError("failed to delete upload", err)
+	}
+	return nil
+}
+
+// PurgeExpired deletes the uploads that expired before now with their files,
+// and returns how many were deleted. Uploads left over past the batch size
+// are deleted on the next run.
+func (uc *ImportUploadUseCase) PurgeExpired(ctx context.Context, now time.Time) (int, error) {
+	expired, err := uc.uploads.FindExpired(ctx, now, uc.config.PurgeBatchSize)
+	if err != nil {
+		return 0, fmt.Errorf("failed to find expired uploads: %w", err)
+	}
+
+	purged := 0
+	for _, upload := range expired {
+		if err := uc.delete(ctx, upload.ID); err != nil {
+			return purged, err
+		}
+		purged++
+	}
+	return purged, nil
+}
+
+// delete deletes an upload's file, then the upload, so a file is never left
+// without an upload to expire it.
+func (uc *ImportUploadUseCase) delete(ctx context.Context, uploadID uuid.UUID) error {
+	if err := uc.storage.Delete(ctx, uploadID); err != nil {
+		return fmt.Errorf("failed to delete uploaded file: %w", err)
+	}
+	if err := uc.uploads.Delete(ctx, uploadID); err != nil && !errors.Is(err, domain.ErrUploadNotFound) {
+		return fmt.Errorf("failed to delete upload: %w", err)
+	}
+	return nil
+}
+
+// uploadError maps a domain error of an upload to an application error.
+func (uc *ImportUploadUseCase) uploadError(upload *domain.ImportUpload, offset int64, err error) error {
+	switch {
+	case errors.Is(err, domain.ErrUploadCompleted):
+		return application.ErrUploadCompleted(upload.ID, upload.ImportID)
+	case errors.Is(err, domain.ErrUploadExpired):
+		return application.ErrUploadExpired(upload.ID)
+	case errors.Is(err, domain.ErrUploadOffsetMismatch):
+		return application.ErrUploadOffsetMismatch(offset, upload.Offset)
+	case errors.Is(err, domain.ErrUploadIncomplete):
+		return application.ErrUploadIncomplete(upload.Offset, upload.Size)
+	case errors.Is(err, domain.ErrUploadExceedsSize):
+		return application.ErrInvalidInput(err.Error())
+	}
+	var validationErr *domain.ValidationError
+	if errors.As(err, &validationErr) {
+		return application.ErrInvalidInput(validationErr.Error())
+	}
+	return application.ErrInternalError("failed to update upload", err)
+}
@@ -0,0 +1,286 @@
+// Package usecase contains the application use cases for the Customer service.
+package usecase
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/kilang-desa-murni/crm/internal/customer/application"
+	"github.com/kilang-desa-murni/crm/internal/customer/domain"
+)
+
+// ============================================================================
+// Mock Import Upload Repository
+// ============================================================================
+
+type MockImportUploadRepository struct {
+	uploads map[uuid.UUID]*domain.ImportUpload
+}
+
+func NewMockImportUploadRepository() *MockImportUploadRepository {
+	return &MockImportUploadRepository{uploads: make(map[uuid.UUID]*domain.ImportUpload)}
+}
+
+func (m *MockImportUploadRepository) Create(ctx context.Context, upload *domain.ImportUpload) error {
+	stored := *upload
+	m.uploads[upload.ID] = &stored
+	return nil
+}
+
+func (m *MockImportUploadRepository) Update(ctx context.Context, upload *domain.ImportUpload) error {
+	stored, ok := m.uploads[upload.ID]
+	if !ok {
+		return domain.ErrUploadNotFound
+	}
+	if stored.Version != upload.Version {
+		return domain.ErrVersionConflict
+	}
+	upload.Version++
+	updated := *upload
+	m.uploads[upload.ID] = &updated
+	return nil
+}
+
+func (m *MockImportUploadRepository) FindByID(ctx context.Context, id uuid.UUID) (*domain.ImportUpload, error) {
+	stored, ok := m.uploads[id]
+	if !ok {
+		return nil, domain.ErrUploadNotFound
+	}
+	upload := *stored
+	return &upload, nil
+}
+
+func (m *MockImportUploadRepository) FindExpired(ctx context.Context, before time.Time, limit int) ([]*domain.ImportUpload, error) {
+	var expired []*domain.ImportUpload
+	for _, upload := range m.uploads {
+		if upload.ExpiresAt.Before(before) && len(expired) < limit {
+			expired = append(expired, upload)
+		}
+	}
+	return expired, nil
+}
+
+func (m *MockImportUploadRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	delete(m.uploads, id)
+	return nil
+}
+
+// ============================================================================
+// Mock Upload Storage
+// ============================================================================
+
+type MockUploadStorage struct {
+	files map[uuid.UUID][]byte
+}
+
+func NewMockUploadStorage() *MockUploadStorage {
+	return &MockUploadStorage{files: make(map[uuid.UUID][]byte)}
+}
+
+func (m *MockUploadStorage) WriteAt(ctx context.Context, uploadID uuid.UUID, offset int64, data []byte) error {
+	file := m.files[uploadID]
+	if end := offset + int64(len(data)); end > int64(len(file)) {
+		file = append(file, make([]byte, end-int64(len(file)))...)
+	}
+	copy(file[offset:], data)
+	m.files[uploadID] = file
+	return nil
+}
+
+func (m *MockUploadStorage) Open(ctx context.Context, uploadID uuid.UUID) (io.ReadCloser, error) {
+	return io.NopCloser(bytes.NewReader(m.files[uploadID])), nil
+}
+
+func (m *MockUploadStorage) Delete(ctx context.Context, uploadID uuid.UUID) error {
+	delete(m.files, uploadID)
+	return nil
+}
+
+// ============================================================================
+// ImportUploadUseCase Tests
+// ============================================================================
+
+func newTestUploadUseCase() (*ImportUploadUseCase, *MockImportUploadRepository, *MockUploadStorage) {
+	importService := NewMockImportService()
+	importService.parseCustomersResult = createValidImportRows()
+	importer := NewImportCustomersUseCase(NewMockUnitOfWork(), importService, NewMockCustomerEventPublisher(),
+		NewMockIDGenerator(), NewMockCustomerCacheService(), NewMockCustomerAuditLogger(), DefaultImportConfig())
+
+	uploads := NewMockImportUploadRepository()
+	storage := NewMockUploadStorage()
+	config := DefaultUploadConfig()
+	config.MaxChunkSize = 16
+	return NewImportUploadUseCase(uploads, storage, importer, config), uploads, storage
+}
+
+func sha256Hex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func TestImportUploadUseCase_ResumedUpload(t *testing.T) {
+	uc, uploads, storage := newTestUploadUseCase()
+	ctx := context.Background()
+	tenantID, userID := uuid.New(), uuid.New()
+	file := []byte("name,email\nCustomer One,one@example.com\n")
+
+	upload, err := uc.Initiate(ctx, InitiateUploadInput{
+		TenantID: tenantID,
+		UserID:   userID,
+		FileName: "customers.CSV",
+		Size:     int64(len(file)),
+		Checksum: sha256Hex(file),
+	})
+	if err != nil {
+		t.Fatalf("Initiate() error = %v", err)
+	}
+	if upload.Format != "csv" {
+		t.Errorf("format = %q, want it taken from the file name", upload.Format)
+	}
+
+	// Send the file in chunks; the connection drops after the first one
+	first := file[:16]
+	if _, err := uc.Append(ctx, AppendUploadInput{TenantID: tenantID, UploadID: upload.ID, Data: first, Checksum: sha256Hex([]byte("corrupted"))}); !isAppCode(err, application.ErrCodeUploadChecksumMismatch) {
+		t.Errorf("corrupted chunk error = %v, want a checksum mismatch", err)
+	}
+	if _, err := uc.Append(ctx, AppendUploadInput{TenantID: tenantID, UploadID: upload.ID, Data: first, Checksum: sha256Hex(first)}); err != nil {
+		t.Fatalf("Append() error = %v", err)
+	}
+
+	resumed, err := uc.Get(ctx, tenantID, upload.ID)
+	if err != nil || resumed.Offset != 16 {
+		t.Fatalf("Get() = %v, %v; want offset 16", resumed, err)
+	}
+	if _, err := uc.Append(ctx, AppendUploadInput{TenantID: tenantID, UploadID: upload.ID, Offset: 0, Data: first}); !isAppCode(err, application.ErrCodeUploadOffsetMismatch) {
+		t.Errorf("resent chunk error = %v, want an offset mismatch", err)
+	}
+	if _, err := uc.Complete(ctx, CompleteUploadInput{TenantID: tenantID, UserID: userID, UploadID: upload.ID}); !isAppCode(err, application.ErrCodeUploadIncomplete) {
+		t.Errorf("Complete() of a partial upload error = %v, want incomplete", err)
+	}
+
+	for offset := int64(16); offset < int64(len(file)); offset += 16 {
+		chunk := file[offset:min(offset+16, int64(len(file)))]
+		if _, err := uc.Append(ctx, AppendUploadInput{TenantID: tenantID, UploadID: upload.ID, Offset: offset, Data: chunk}); err != nil {
+			t.Fatalf("Append() at %d error = %v", offset, err)
+		}
+	}
+
+	if _, err := uc.Get(ctx, uuid.New(), upload.ID); !application.IsNotFoundError(err) {
+		t.Errorf("another tenant's Get() error = %v, want not found", err)
+	}
+
+	result, err := uc.Complete(ctx, CompleteUploadInput{TenantID: tenantID, UserID: userID, UploadID: upload.ID, SkipDuplicates: true})
+	if err != nil {
+		t.Fatalf("Complete() error = %v", err)
+	}
+	if result.Import.TotalRows != 2 {
+		t.Errorf("imported %d rows, want the file's 2", result.Import.TotalRows)
+	}
+	saved := uploads.uploads[upload.ID]
+	if saved.Status != domain.UploadStatusCompleted || saved.ImportID == nil || *saved.ImportID != result.Import.ImportID {
+		t.Errorf("upload = %+v, want completed with the import", saved)
+	}
+	if _, ok := storage.files[upload.ID]; ok {
+		t.Error("the uploaded file should be deleted once imported")
+	}
+
+	if _, err := uc.Complete(ctx, CompleteUploadInput{TenantID: tenantID, UserID: userID, UploadID: upload.ID}); !application.IsConflictError(err) {
+		t.Errorf("second Complete() error = %v, want a conflict", err)
+	}
+}
+
+func TestImportUploadUseCase_Complete_ChecksumMismatch(t *testing.T) {
+	uc, uploads, storage := newTestUploadUseCase()
+	ctx := context.Background()
+	tenantID, userID := uuid.New(), uuid.New()
+	file := []byte("name\nOne\n")
+
+	upload, err := uc.Initiate(ctx, InitiateUploadInput{
+		TenantID: tenantID,
+		UserID:   userID,
+		FileName: "customers.csv",
+		Size:     int64(len(file)),
+		Checksum: sha256Hex([]byte("name\nTwo\n")),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := uc.Append(ctx, AppendUploadInput{TenantID: tenantID, UploadID: upload.ID, Data: file}); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := uc.Complete(ctx, CompleteUploadInput{TenantID: tenantID, UserID: userID, UploadID: upload.ID}); !isAppCode(err, application.ErrCodeUploadChecksumMismatch) {
+		t.Errorf("Complete() error = %v, want a checksum mismatch", err)
+	}
+	if len(uploads.uploads) != 0 || len(storage.files) != 0 {
+		t.Error("a corrupt upload should be deleted with its file")
+	}
+}
+
+func TestImportUploadUseCase_Initiate_Validation(t *testing.T) {
+	uc, _, _ := newTestUploadUseCase()
+	ctx := context.Background()
+
+	for _, tc := range []struct {
+		name  string
+		input InitiateUploadInput
+		code  string
+	}{
+		{"too large", InitiateUploadInput{FileName: "customers.csv", Size: DefaultImportConfig().MaxFileSize + 1, Checksum: sha256Hex(nil)}, application.ErrCodeFileTooLarge},
+		{"unsupported format", InitiateUploadInput{FileName: "customers.pdf", Size: 10, Checksum: sha256Hex(nil)}, application.ErrCodeInvalidFormat},
+		{"bad checksum", InitiateUploadInput{FileName: "customers.csv", Size: 10, Checksum: "md5"}, application.ErrCodeInvalidInput},
+	} {
+		tc.input.TenantID, tc.input.UserID = uuid.New(), uuid.New()
+		if _, err := uc.Initiate(ctx, tc.input); !isAppCode(err, tc.code) {
+			t.Errorf("%s: error = %v, want %s", tc.name, err, tc.code)
+		}
+	}
+}
+
+func TestImportUploadUseCase_PurgeExpired(t *testing.T) {
+	uc, uploads, storage := newTestUploadUseCase()
+	ctx := context.Background()
+	tenantID := uuid.New()
+
+	var ids []uuid.UUID
+	for i := 0; i < 2; i++ {
+		upload, err := uc.Initiate(ctx, InitiateUploadInput{TenantID: tenantID, UserID: uuid.New(), FileName: "customers.csv", Size: 100, Checksum: sha256Hex(nil)})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := uc.Append(ctx, AppendUploadInput{TenantID: tenantID, UploadID: upload.ID, Data: []byte("name")}); err != nil {
+			t.Fatal(err)
+		}
+		ids = append(ids, upload.ID)
+	}
+	uploads.uploads[ids[0]].ExpiresAt = time.Now().Add(-time.Minute)
+
+	purged, err := uc.PurgeExpired(ctx, time.Now())
+	if err != nil {
+		t.Fatalf("PurgeExpired() error = %v", err)
+	}
+	if purged != 1 {
+		t.Errorf("purged %d uploads, want the abandoned one", purged)
+	}
+	if _, ok := uploads.uploads[ids[0]]; ok {
+		t.Error("the abandoned upload should be deleted")
+	}
+	if _, ok := storage.files[ids[0]]; ok {
+		t.Error("the abandoned upload's file should be deleted")
+	}
+	if _, ok := storage.files[ids[1]]; !ok {
+		t.Error("the active upload's file should be kept")
+	}
+}
+
+func isAppCode(err error, code string) bool {
+	appErr, ok := err.(*application.ApplicationError)
+	return ok && appErr.Code == code
+}
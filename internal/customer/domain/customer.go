@@ -2,6 +2,7 @@
 package domain
 
 import (
+	"fmt"
 	"strings"
 	"time"
 
@@ -143,6 +144,7 @@ type CustomerStats struct {
 	ActivityCount        int        `json:"activity_count" bson:"activity_count"`
 	DealCount            int        `json:"deal_count" bson:"deal_count"`
 	WonDealCount         int        `json:"won_deal_count" bson:"won_deal_count"`
+	LastWonDealAt        *time.Time `json:"last_won_deal_at,omitempty" bson:"last_won_deal_at,omitempty"`
 	LostDealCount        int        `json:"lost_deal_count" bson:"lost_deal_count"`
 	OpenDealValue        *Money     `json:"open_deal_value,omitempty" bson:"open_deal_value,omitempty"`
 	DaysSinceLastContact *int       `json:"days_since_last_contact,omitempty" bson:"days_since_last_contact,omitempty"`
@@ -150,6 +152,14 @@ type CustomerStats struct {
 	EngagementScore      int        `json:"engagement_score" bson:"engagement_score"`
 	HealthScore          int        `json:"health_score" bson:"health_score"`
 	LastCalculatedAt     *time.Time `json:"last_calculated_at,omitempty" bson:"last_calculated_at,omitempty"`
+
+	// Health score history and inputs, see CalculateHealth
+	PreviousHealthScore *int           `json:"previous_health_score,omitempty" bson:"previous_health_score,omitempty"`
+	HealthTrend         HealthTrend    `json:"health_trend,omitempty" bson:"health_trend,omitempty"`
+	HealthFactors       *HealthFactors `json:"health_factors,omitempty" bson:"health_factors,omitempty"`
+	NotificationOpens   int            `json:"notification_opens" bson:"notification_opens"`
+	NotificationClicks  int            `json:"notification_clicks" bson:"notification_clicks"`
+	LastEngagedAt       *time.Time     `json:"last_engaged_at,omitempty" bson:"last_engaged_at,omitempty"`
 }
 
 // Customer is the aggregate root for customer management.
@@ -187,11 +197,22 @@ type Customer struct {
 	ConvertedAt     *time.Time             `json:"converted_at,omitempty" bson:"converted_at,omitempty"`
 	ChurnedAt       *time.Time             `json:"churned_at,omitempty" bson:"churned_at,omitempty"`
 	ChurnReason     string                 `json:"churn_reason,omitempty" bson:"churn_reason,omitempty"`
+
+	Lifecycle          CustomerLifecycle `json:"lifecycle,omitempty" bson:"lifecycle,omitempty"`
+	LifecycleChangedAt *time.Time        `json:"lifecycle_changed_at,omitempty" bson:"lifecycle_changed_at,omitempty"`
+	LifecycleOverride  bool              `json:"lifecycle_override" bson:"lifecycle_override"`
+
+	OverdueInvoices []OverdueInvoice `json:"overdue_invoices,omitempty" bson:"overdue_invoices,omitempty"`
+
+	ErasedAt *time.Time `json:"erased_at,omitempty" bson:"erased_at,omitempty"` // Personal data erased
 }
 
 // MaxContacts is the maximum number of contacts per customer.
 const MaxContacts = 100
 
+// MaxAddresses is the maximum number of addresses per customer.
+const MaxAddresses = 50
+
 // CustomerBuilder provides a fluent API for building Customer aggregates.
 type CustomerBuilder struct {
 	customer Customer
@@ -207,6 +228,7 @@ func NewCustomerBuilder(tenantID uuid.UUID, name string, customerType CustomerTy
 			Name:              strings.TrimSpace(name),
 			Type:              customerType,
 			Status:            CustomerStatusLead,
+			Lifecycle:         LifecycleProspect,
 			Tier:              CustomerTierStandard,
 			Source:            CustomerSourceDirect,
 			PhoneNumbers:      make([]PhoneNumber, 0),
@@ -283,6 +305,7 @@ func (b *CustomerBuilder) WithAddress(line1, city, postalCode, countryCode strin
 			}
 		}
 	} else {
+		addr.ID = uuid.New()
 		b.customer.Addresses = append(b.customer.Addresses, addr)
 	}
 	return b
@@ -561,18 +584,139 @@ func (c *Customer) GetPrimaryPhone() *PhoneNumber {
 	return nil
 }
 
-// AddAddress adds an address.
-func (c *Customer) AddAddress(address Address) {
+// AddAddress adds an address. The first address of a type becomes the
+// primary address of that type.
+func (c *Customer) AddAddress(address Address) error {
+	if len(c.Addresses) >= MaxAddresses {
+		return ErrMaxAddressesExceeded
+	}
+	if address.ID == uuid.Nil {
+		address.ID = uuid.New()
+	}
+	if c.primaryAddressIndex(address.AddressType) < 0 {
+		address.IsPrimary = true
+	}
 	if address.IsPrimary {
-		for i := range c.Addresses {
-			if c.Addresses[i].AddressType == address.AddressType {
-				c.Addresses[i].IsPrimary = false
-			}
-		}
+		c.clearPrimaryAddress(address.AddressType)
 	}
 	c.Addresses = append(c.Addresses, address)
 	c.MarkUpdated()
 	c.IncrementVersion()
+
+	c.AddDomainEvent(NewCustomerUpdatedEvent(c, map[string]interface{}{
+		"address_added": address.ID,
+	}))
+	return nil
+}
+
+// UpdateAddress replaces an address, keeping its ID. An address that stops
+// being primary hands the primary flag to another address of its type.
+func (c *Customer) UpdateAddress(addressID uuid.UUID, address Address) error {
+	i := c.addressIndex(addressID)
+	if i < 0 {
+		return ErrAddressNotFound
+	}
+	old := c.Addresses[i]
+	address.ID = addressID
+
+	if address.IsPrimary {
+		c.clearPrimaryAddress(address.AddressType)
+	}
+	c.Addresses[i] = address
+	if old.IsPrimary && (!address.IsPrimary || old.AddressType != address.AddressType) {
+		c.promotePrimaryAddress(old.AddressType, addressID)
+	}
+	if c.primaryAddressIndex(address.AddressType) < 0 {
+		c.Addresses[i].IsPrimary = true
+	}
+	c.MarkUpdated()
+	c.IncrementVersion()
+
+	c.AddDomainEvent(NewCustomerUpdatedEvent(c, map[string]interface{}{
+		"address_updated": addressID,
+	}))
+	return nil
+}
+
+// RemoveAddress removes an address. Removing the primary address of a type
+// makes the next address of that type primary.
+func (c *Customer) RemoveAddress(addressID uuid.UUID) error {
+	i := c.addressIndex(addressID)
+	if i < 0 {
+		return ErrAddressNotFound
+	}
+	removed := c.Addresses[i]
+	c.Addresses = append(c.Addresses[:i], c.Addresses[i+1:]...)
+	if removed.IsPrimary {
+		c.promotePrimaryAddress(removed.AddressType, addressID)
+	}
+	c.MarkUpdated()
+	c.IncrementVersion()
+
+	c.AddDomainEvent(NewCustomerUpdatedEvent(c, map[string]interface{}{
+		"address_removed": addressID,
+	}))
+	return nil
+}
+
+// GetAddress returns an address by ID.
+func (c *Customer) GetAddress(addressID uuid.UUID) *Address {
+	if i := c.addressIndex(addressID); i >= 0 {
+		return &c.Addresses[i]
+	}
+	return nil
+}
+
+// EnsureAddressIDs gives addresses stored before addresses had IDs a stable
+// ID derived from the customer and the address, so they can be addressed
+// until the customer is next saved with the IDs.
+func (c *Customer) EnsureAddressIDs() {
+	for i := range c.Addresses {
+		if c.Addresses[i].ID == uuid.Nil {
+			key := fmt.Sprintf("%d|%s|%s", i, c.Addresses[i].AddressType, c.Addresses[i].SingleLine())
+			c.Addresses[i].ID = uuid.NewSHA1(c.ID, []byte(key))
+		}
+	}
+}
+
+func (c *Customer) addressIndex(addressID uuid.UUID) int {
+	for i := range c.Addresses {
+		if c.Addresses[i].ID == addressID {
+			return i
+		}
+	}
+	return -1
+}
+
+func (c *Customer) primaryAddressIndex(addressType AddressType) int {
+	for i := range c.Addresses {
+		if c.Addresses[i].AddressType == addressType && c.Addresses[i].IsPrimary {
+			return i
+		}
+	}
+	return -1
+}
+
+func (c *Customer) clearPrimaryAddress(addressType AddressType) {
+	for i := range c.Addresses {
+		if c.Addresses[i].AddressType == addressType {
+			c.Addresses[i].IsPrimary = false
+		}
+	}
+}
+
+// promotePrimaryAddress makes the first address of a type other than
+// excludeID primary, unless the type already has a primary address.
+func (c *Customer) promotePrimaryAddress(addressType AddressType, excludeID uuid.UUID) {
+	if c.primaryAddressIndex(addressType) >= 0 {
+		return
+	}
+	for i := range c.Addresses {
+		if c.Addresses[i].AddressType == addressType && c.Addresses[i].ID != excludeID {
+			c.Addresses[i].IsPrimary = true
+			return
+		}
+	}
 }
 
 // GetBillingAddress returns the billing address.
@@ -780,6 +924,7 @@ func (c *Customer) ConvertToCustomer() error {
 	c.IncrementVersion()
 
 	c.AddDomainEvent(NewCustomerConvertedEvent(c, oldStatus))
+	c.changeLifecycle(LifecycleActive, "converted", true, now)
 
 	return nil
 }
@@ -806,6 +951,7 @@ func (c *Customer) MarkAsChurned(reason string) {
 
 	c.AddDomainEvent(NewCustomerChurnedEvent(c, reason))
 	c.AddDomainEvent(NewCustomerStatusChangedEvent(c, oldStatus, CustomerStatusChurned))
+	c.changeLifecycle(LifecycleChurned, c.ChurnReason, false, now)
 }
 
 // Block blocks the customer.
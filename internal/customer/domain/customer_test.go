@@ -364,6 +364,61 @@ func TestCustomer_AddAddress(t *testing.T) {
 	}
 }
 
+func TestCustomer_AddressBook(t *testing.T) {
+	customer := createTestCustomer(t)
+	first, _ := NewAddress("1 Jalan Tun Razak", "Kuala Lumpur", "50400", "MY", AddressTypeOutlet)
+	second, _ := NewAddress("8 Jalan Sultan", "Kota Bharu", "15000", "MY", AddressTypeOutlet)
+
+	if err := customer.AddAddress(first); err != nil {
+		t.Fatalf("AddAddress() error = %v", err)
+	}
+	if err := customer.AddAddress(second); err != nil {
+		t.Fatalf("AddAddress() error = %v", err)
+	}
+	firstID, secondID := customer.Addresses[0].ID, customer.Addresses[1].ID
+	if firstID == uuid.Nil || firstID == secondID {
+		t.Fatal("AddAddress() should give each address its own ID")
+	}
+	if !customer.Addresses[0].IsPrimary || customer.Addresses[1].IsPrimary {
+		t.Error("the first address of a type should be its primary address")
+	}
+
+	// Moving the primary outlet to billing promotes the other outlet
+	billing := customer.Addresses[0]
+	billing.AddressType = AddressTypeBilling
+	if err := customer.UpdateAddress(firstID, billing); err != nil {
+		t.Fatalf("UpdateAddress() error = %v", err)
+	}
+	if !customer.GetAddress(secondID).IsPrimary || !customer.GetAddress(firstID).IsPrimary {
+		t.Error("both the billing address and the remaining outlet should be primary")
+	}
+
+	if err := customer.RemoveAddress(secondID); err != nil {
+		t.Fatalf("RemoveAddress() error = %v", err)
+	}
+	if err := customer.RemoveAddress(secondID); err != ErrAddressNotFound {
+		t.Errorf("RemoveAddress(removed) error = %v, want %v", err, ErrAddressNotFound)
+	}
+	if len(customer.Addresses) != 1 {
+		t.Errorf("Addresses count = %d, want 1", len(customer.Addresses))
+	}
+}
+
+func TestCustomer_EnsureAddressIDs(t *testing.T) {
+	customer := createTestCustomer(t)
+	addr, _ := NewAddress("1 Jalan Tun Razak", "Kuala Lumpur", "50400", "MY", AddressTypeBilling)
+	customer.Addresses = []Address{addr}
+
+	customer.EnsureAddressIDs()
+	id := customer.Addresses[0].ID
+	customer.Addresses[0].ID = uuid.Nil
+	customer.EnsureAddressIDs()
+
+	if id == uuid.Nil || customer.Addresses[0].ID != id {
+		t.Error("EnsureAddressIDs() should give a stored address the same ID every time")
+	}
+}
+
 func TestCustomer_GetBillingAddress(t *testing.T) {
 	customer := createTestCustomer(t)
 	addr, _ := NewAddress("123 Main St", "KL", "50000", "MY", AddressTypeBilling)
@@ -0,0 +1,270 @@
+package domain
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ============================================================================
+// Data Erasure
+// ============================================================================
+
+// Services taking part in a customer data erasure. The customer service
+// erases its own data when the erasure is requested; the other services
+// erase theirs when they receive the customer.erased event, and confirm it.
+const (
+	ErasureServiceCustomer     = "customer"
+	ErasureServiceSales        = "sales"
+	ErasureServiceNotification = "notification"
+)
+
+// ErasureStatus represents the status of an erasure request.
+type ErasureStatus string
+
+const (
+	ErasureStatusInProgress ErasureStatus = "in_progress"
+	ErasureStatusCompleted  ErasureStatus = "completed"
+)
+
+// Kinds of erased identifiers.
+const (
+	IdentifierKindEmail = "email"
+	IdentifierKindPhone = "phone"
+)
+
+// erasedCustomerName replaces the name of an erased customer.
+const erasedCustomerName = "Erased customer"
+
+// ErasureServiceStatus records a service's part of an erasure.
+type ErasureServiceStatus struct {
+	Service     string     `json:"service" bson:"service"`
+	Records     int64      `json:"records" bson:"records"` // Records erased or anonymized
+	CompletedAt *time.Time `json:"completed_at,omitempty" bson:"completed_at,omitempty"`
+}
+
+// ErasureRequest is a request to erase a customer's personal data across the
+// services. Once every service has confirmed its part, the request is the
+// erasure certificate: it names the customer only by ID and code.
+type ErasureRequest struct {
+	ID                 uuid.UUID              `json:"id" bson:"_id"`
+	TenantID           uuid.UUID              `json:"tenant_id" bson:"tenant_id"`
+	CustomerID         uuid.UUID              `json:"customer_id" bson:"customer_id"`
+	CustomerCode       string                 `json:"customer_code" bson:"customer_code"`
+	Reason             string                 `json:"reason,omitempty" bson:"reason,omitempty"`
+	RequestedBy        *uuid.UUID             `json:"requested_by,omitempty" bson:"requested_by,omitempty"`
+	Status             ErasureStatus          `json:"status" bson:"status"`
+	Services           []ErasureServiceStatus `json:"services" bson:"services"`
+	IdentifiersBlocked int                    `json:"identifiers_blocked" bson:"identifiers_blocked"`
+	BlockedUntil       time.Time              `json:"blocked_until" bson:"blocked_until"`
+	CreatedAt          time.Time              `json:"created_at" bson:"created_at"`
+	CompletedAt        *time.Time             `json:"completed_at,omitempty" bson:"completed_at,omitempty"`
+	Version            int                    `json:"version" bson:"version"`
+}
+
+// NewErasureRequest creates an erasure request for a customer. The customer
+// service's part is pending until its data is erased, like the other
+// services'.
+func NewErasureRequest(customer *Customer, reason string, requestedBy *uuid.UUID, services []string, blockedUntil time.Time) *ErasureRequest {
+	statuses := []ErasureServiceStatus{{Service: ErasureServiceCustomer}}
+	for _, service := range services {
+		if service != ErasureServiceCustomer {
+			statuses = append(statuses, ErasureServiceStatus{Service: service})
+		}
+	}
+
+	return &ErasureRequest{
+		ID:           uuid.New(),
+		TenantID:     customer.TenantID,
+		CustomerID:   customer.ID,
+		CustomerCode: customer.Code,
+		Reason:       reason,
+		RequestedBy:  requestedBy,
+		Status:       ErasureStatusInProgress,
+		Services:     statuses,
+		BlockedUntil: blockedUntil.UTC(),
+		CreatedAt:    time.Now().UTC(),
+		Version:      1,
+	}
+}
+
+// CompleteService records that a service erased its part of the customer's
+// data, and reports whether that completed the erasure. Confirmations are
+// delivered at least once, so a repeated confirmation is ignored.
+func (r *ErasureRequest) CompleteService(service string, records int64, at time.Time) (bool, error) {
+	found := false
+	for i := range r.Services {
+		if r.Services[i].Service != service {
+			continue
+		}
+		found = true
+		if r.Services[i].CompletedAt != nil {
+			return false, nil
+		}
+		completedAt := at.UTC()
+		r.Services[i].CompletedAt = &completedAt
+		r.Services[i].Records = records
+	}
+	if !found {
+		return false, NewValidationError("service", "service is not part of the erasure", "UNKNOWN_SERVICE")
+	}
+
+	for _, status := range r.Services {
+		if status.CompletedAt == nil {
+			return false, nil
+		}
+	}
+	completedAt := at.UTC()
+	r.Status = ErasureStatusCompleted
+	r.CompletedAt = &completedAt
+	return true, nil
+}
+
+// ServiceCompleted returns true if the service has confirmed its part.
+func (r *ErasureRequest) ServiceCompleted(service string) bool {
+	for _, status := range r.Services {
+		if status.Service == service {
+			return status.CompletedAt != nil
+		}
+	}
+	return false
+}
+
+// PendingServices returns the services that have not confirmed their part.
+func (r *ErasureRequest) PendingServices() []string {
+	var pending []string
+	for _, status := range r.Services {
+		if status.CompletedAt == nil {
+			pending = append(pending, status.Service)
+		}
+	}
+	return pending
+}
+
+// ErasedIdentifier is a hashed email address or phone number of an erased
+// customer. It blocks the identifier from being re-imported until it expires.
+type ErasedIdentifier struct {
+	ID        uuid.UUID `json:"id" bson:"_id"`
+	TenantID  uuid.UUID `json:"tenant_id" bson:"tenant_id"`
+	Hash      string    `json:"hash" bson:"hash"`
+	ErasureID uuid.UUID `json:"erasure_id" bson:"erasure_id"`
+	ExpiresAt time.Time `json:"expires_at" bson:"expires_at"`
+	CreatedAt time.Time `json:"created_at" bson:"created_at"`
+}
+
+// HashIdentifier returns the hex SHA-256 hash of an identifier, after
+// normalizing it: email addresses are lowercased and phone numbers reduced
+// to their digits. It returns an empty string for an empty identifier.
+//
+// Other services match their own records against these hashes, so the
+// normalization must stay in step with theirs.
+func HashIdentifier(kind, value string) string {
+	switch kind {
+	case IdentifierKindEmail:
+		value = strings.ToLower(strings.TrimSpace(value))
+	case IdentifierKindPhone:
+		value = strings.Map(func(r rune) rune {
+			if r >= '0' && r <= '9' {
+				return r
+			}
+			return -1
+		}, value)
+	}
+	if value == "" {
+		return ""
+	}
+
+	sum := sha256.Sum256([]byte(kind + ":" + value))
+	return hex.EncodeToString(sum[:])
+}
+
+// IdentifierHashes returns the distinct hashes of the email addresses and
+// phone numbers, skipping empty ones.
+func IdentifierHashes(emails, phones []string) []string {
+	seen := make(map[string]bool)
+	var hashes []string
+	add := func(kind, value string) {
+		if hash := HashIdentifier(kind, value); hash != "" && !seen[hash] {
+			seen[hash] = true
+			hashes = append(hashes, hash)
+		}
+	}
+	for _, email := range emails {
+		add(IdentifierKindEmail, email)
+	}
+	for _, phone := range phones {
+		add(IdentifierKindPhone, phone)
+	}
+	return hashes
+}
+
+// IsErased returns true if the customer's personal data has been erased.
+func (c *Customer) IsErased() bool {
+	return c.ErasedAt != nil
+}
+
+// Anonymize erases the customer's personal data: the name, contact details,
+// addresses, social profiles, notes and custom fields of the customer and
+// their contacts. The code, financials, statistics and tier are kept, so
+// revenue reports stay correct. It returns the hashes of the erased email
+// addresses and phone numbers, and raises a CustomerErasedEvent.
+func (c *Customer) Anonymize(erasureID uuid.UUID, at time.Time) ([]string, error) {
+	if c.IsErased() {
+		return nil, ErrCustomerErased
+	}
+
+	var emails, phones []string
+	emails = append(emails, c.Email.String())
+	for _, phone := range c.PhoneNumbers {
+		phones = append(phones, phone.E164())
+	}
+	for i := range c.Contacts {
+		contact := &c.Contacts[i]
+		emails = append(emails, contact.Email.String())
+		for _, phone := range contact.PhoneNumbers {
+			phones = append(phones, phone.E164())
+		}
+		contact.anonymize()
+	}
+	hashes := IdentifierHashes(emails, phones)
+
+	c.Name = erasedCustomerName
+	c.Email = Email{}
+	c.PhoneNumbers = []PhoneNumber{}
+	c.Website = Website{}
+	c.Addresses = []Address{}
+	c.SocialProfiles = nil
+	c.CustomFields = nil
+	c.Notes = ""
+	c.LogoURL = ""
+	c.ChurnReason = ""
+
+	erasedAt := at.UTC()
+	c.ErasedAt = &erasedAt
+	c.MarkUpdated()
+	c.IncrementVersion()
+	c.AddDomainEvent(NewCustomerErasedEvent(c, erasureID, hashes))
+
+	return hashes, nil
+}
+
+// anonymize erases the contact's personal data, keeping its role.
+func (c *Contact) anonymize() {
+	c.Name = PersonName{FirstName: "Erased", LastName: "contact"}
+	c.Email = Email{}
+	c.PhoneNumbers = []PhoneNumber{}
+	c.Addresses = nil
+	c.SocialProfiles = nil
+	c.JobTitle = ""
+	c.Department = ""
+	c.OptedOutMarketing = true
+	c.MarketingConsent = nil
+	c.Birthday = nil
+	c.Notes = ""
+	c.CustomFields = nil
+	c.LinkedInURL = ""
+	c.ProfilePhotoURL = ""
+}
@@ -0,0 +1,90 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ============================================================================
+// Data Erasure Tests
+// ============================================================================
+
+func TestHashIdentifier(t *testing.T) {
+	if HashIdentifier(IdentifierKindEmail, " Siti@Example.com ") != HashIdentifier(IdentifierKindEmail, "siti@example.com") {
+		t.Error("email hashes should ignore case and surrounding spaces")
+	}
+	if HashIdentifier(IdentifierKindPhone, "+60 12-345 6789") != HashIdentifier(IdentifierKindPhone, "60123456789") {
+		t.Error("phone hashes should ignore everything but digits")
+	}
+	if HashIdentifier(IdentifierKindEmail, "60123456789") == HashIdentifier(IdentifierKindPhone, "60123456789") {
+		t.Error("hashes of different kinds should differ")
+	}
+	if HashIdentifier(IdentifierKindPhone, "n/a") != "" {
+		t.Error("an identifier without content should hash to an empty string")
+	}
+}
+
+func TestCustomer_Anonymize(t *testing.T) {
+	customer := createTestCustomer(t)
+	customer.Notes = "Prefers batik sarongs"
+	code := customer.Code
+	erasureID := uuid.New()
+
+	hashes, err := customer.Anonymize(erasureID, time.Now())
+	if err != nil {
+		t.Fatalf("Anonymize() error = %v", err)
+	}
+
+	if len(hashes) != 2 {
+		t.Errorf("got %d identifier hashes, want the email and phone", len(hashes))
+	}
+	if customer.Name != erasedCustomerName || customer.Email.String() != "" || len(customer.PhoneNumbers) != 0 || customer.Notes != "" {
+		t.Error("personal data should be erased")
+	}
+	if customer.Code != code || !customer.IsErased() {
+		t.Error("the customer code should be kept and the customer marked erased")
+	}
+
+	events := customer.DomainEvents()
+	if len(events) != 1 {
+		t.Fatalf("got %d events, want 1", len(events))
+	}
+	event, ok := events[0].(*CustomerErasedEvent)
+	if !ok || event.ErasureID != erasureID || len(event.IdentifierHashes) != 2 {
+		t.Errorf("event = %+v, want a customer erased event with the hashes", events[0])
+	}
+
+	if _, err := customer.Anonymize(uuid.New(), time.Now()); err != ErrCustomerErased {
+		t.Errorf("second Anonymize() error = %v, want ErrCustomerErased", err)
+	}
+}
+
+func TestErasureRequest_CompleteService(t *testing.T) {
+	customer := createTestCustomer(t)
+	request := NewErasureRequest(customer, "Requested by the customer", nil,
+		[]string{ErasureServiceSales, ErasureServiceNotification}, time.Now().AddDate(2, 0, 0))
+
+	now := time.Now()
+	if done, err := request.CompleteService(ErasureServiceCustomer, 3, now); err != nil || done {
+		t.Fatalf("CompleteService(customer) = %v, %v; want pending", done, err)
+	}
+	if done, _ := request.CompleteService(ErasureServiceSales, 5, now); done {
+		t.Error("the erasure should wait for the notification service")
+	}
+	if done, _ := request.CompleteService(ErasureServiceSales, 5, now); done {
+		t.Error("a repeated confirmation should not complete the erasure")
+	}
+	if _, err := request.CompleteService("billing", 1, now); err == nil {
+		t.Error("an unknown service should be rejected")
+	}
+
+	done, err := request.CompleteService(ErasureServiceNotification, 0, now)
+	if err != nil || !done {
+		t.Fatalf("CompleteService(notification) = %v, %v; want completed", done, err)
+	}
+	if request.Status != ErasureStatusCompleted || len(request.PendingServices()) != 0 {
+		t.Errorf("status = %s, pending %v; want completed", request.Status, request.PendingServices())
+	}
+}
@@ -31,6 +31,10 @@ var (
 	ErrDuplicateContactEmail     = errors.New("contact email already exists for this customer")
 	ErrMaxContactsExceeded       = errors.New("maximum number of contacts exceeded")
 
+	// Address errors
+	ErrAddressNotFound           = errors.New("address not found")
+	ErrMaxAddressesExceeded      = errors.New("maximum number of addresses exceeded")
+
 	// Value object errors
 	ErrInvalidEmail              = errors.New("invalid email address")
 	ErrInvalidPhoneNumber        = errors.New("invalid phone number")
@@ -61,6 +65,21 @@ var (
 	ErrInvalidImportFormat       = errors.New("invalid import format")
 	ErrImportValidationFailed    = errors.New("import validation failed")
 
+	// Import upload errors
+	ErrUploadNotFound            = errors.New("import upload not found")
+	ErrUploadCompleted           = errors.New("import upload is already completed")
+	ErrUploadExpired             = errors.New("import upload has expired")
+	ErrUploadOffsetMismatch      = errors.New("chunk offset does not match the bytes received")
+	ErrUploadExceedsSize         = errors.New("chunk exceeds the declared upload size")
+	ErrUploadIncomplete          = errors.New("import upload is incomplete")
+
+	// Purchase errors
+	ErrPurchaseAlreadyRecorded   = errors.New("purchase already recorded for this opportunity")
+
+	// Erasure errors
+	ErrCustomerErased            = errors.New("customer personal data has been erased")
+	ErrErasureNotFound           = errors.New("erasure request not found")
+
 	// General errors
 	ErrInvalidTenantID           = errors.New("invalid tenant ID")
 	ErrUnauthorized              = errors.New("unauthorized access")
@@ -223,7 +242,8 @@ func IsNotFoundError(err error) bool {
 		errors.Is(err, ErrContactNotFound) ||
 		errors.Is(err, ErrSegmentNotFound) ||
 		errors.Is(err, ErrNoteNotFound) ||
-		errors.Is(err, ErrActivityNotFound)
+		errors.Is(err, ErrActivityNotFound) ||
+		errors.Is(err, ErrErasureNotFound)
 }
 
 // IsValidationError checks if the error is a validation error.
@@ -10,18 +10,21 @@ import (
 // Event types for Customer domain.
 const (
 	// Customer events
-	EventTypeCustomerCreated       = "customer.created"
-	EventTypeCustomerUpdated       = "customer.updated"
-	EventTypeCustomerDeleted       = "customer.deleted"
-	EventTypeCustomerStatusChanged = "customer.status_changed"
-	EventTypeCustomerConverted     = "customer.converted"
-	EventTypeCustomerChurned       = "customer.churned"
-	EventTypeCustomerTierChanged   = "customer.tier_changed"
-	EventTypeCustomerOwnerAssigned = "customer.owner_assigned"
-	EventTypeCustomerTagAdded      = "customer.tag_added"
-	EventTypeCustomerTagRemoved    = "customer.tag_removed"
-	EventTypeCustomerMerged        = "customer.merged"
-	EventTypeCustomerImported      = "customer.imported"
+	EventTypeCustomerCreated          = "customer.created"
+	EventTypeCustomerUpdated          = "customer.updated"
+	EventTypeCustomerDeleted          = "customer.deleted"
+	EventTypeCustomerStatusChanged    = "customer.status_changed"
+	EventTypeCustomerConverted        = "customer.converted"
+	EventTypeCustomerChurned          = "customer.churned"
+	EventTypeCustomerTierChanged      = "customer.tier_changed"
+	EventTypeCustomerOwnerAssigned    = "customer.owner_assigned"
+	EventTypeCustomerTagAdded         = "customer.tag_added"
+	EventTypeCustomerTagRemoved       = "customer.tag_removed"
+	EventTypeCustomerMerged           = "customer.merged"
+	EventTypeCustomerImported         = "customer.imported"
+	EventTypeCustomerLifecycleChanged = "customer.lifecycle_changed"
+	EventTypeCustomerHealthDropped    = "customer.health_dropped"
+	EventTypeCustomerErased           = "customer.erased"
 
 	// Contact events
 	EventTypeContactAdded   = "customer.contact.added"
@@ -208,6 +211,99 @@ func NewCustomerChurnedEvent(customer *Customer, reason string) *CustomerChurned
 	}
 }
 
+// CustomerLifecycleChangedEvent is raised when a customer moves to another
+// lifecycle stage. Win-back campaigns start from customers turning dormant or
+// churned.
+type CustomerLifecycleChangedEvent struct {
+	BaseDomainEvent
+	CustomerID   uuid.UUID         `json:"customer_id"`
+	Name         string            `json:"name"`
+	OwnerID      *uuid.UUID        `json:"owner_id,omitempty"`
+	OldLifecycle CustomerLifecycle `json:"old_lifecycle"`
+	NewLifecycle CustomerLifecycle `json:"new_lifecycle"`
+	Reason       string            `json:"reason,omitempty"`
+	Automatic    bool              `json:"automatic"`
+}
+
+// NewCustomerLifecycleChangedEvent creates a new CustomerLifecycleChangedEvent.
+func NewCustomerLifecycleChangedEvent(customer *Customer, oldLifecycle, newLifecycle CustomerLifecycle, reason string, automatic bool) *CustomerLifecycleChangedEvent {
+	return &CustomerLifecycleChangedEvent{
+		BaseDomainEvent: NewBaseDomainEvent(
+			EventTypeCustomerLifecycleChanged,
+			customer.ID,
+			AggregateTypeCustomer,
+			customer.TenantID,
+			customer.Version,
+		),
+		CustomerID:   customer.ID,
+		Name:         customer.Name,
+		OwnerID:      customer.OwnerID,
+		OldLifecycle: oldLifecycle,
+		NewLifecycle: newLifecycle,
+		Reason:       reason,
+		Automatic:    automatic,
+	}
+}
+
+// CustomerHealthDroppedEvent is raised when a customer's health score falls
+// below the alert threshold or drops sharply, to alert the customer's owner.
+type CustomerHealthDroppedEvent struct {
+	BaseDomainEvent
+	CustomerID uuid.UUID     `json:"customer_id"`
+	Name       string        `json:"name"`
+	OwnerID    *uuid.UUID    `json:"owner_id,omitempty"`
+	OldScore   int           `json:"old_score"`
+	NewScore   int           `json:"new_score"`
+	Factors    HealthFactors `json:"factors"`
+}
+
+// NewCustomerHealthDroppedEvent creates a new CustomerHealthDroppedEvent.
+func NewCustomerHealthDroppedEvent(customer *Customer, oldScore, newScore int, factors HealthFactors) *CustomerHealthDroppedEvent {
+	return &CustomerHealthDroppedEvent{
+		BaseDomainEvent: NewBaseDomainEvent(
+			EventTypeCustomerHealthDropped,
+			customer.ID,
+			AggregateTypeCustomer,
+			customer.TenantID,
+			customer.Version,
+		),
+		CustomerID: customer.ID,
+		Name:       customer.Name,
+		OwnerID:    customer.OwnerID,
+		OldScore:   oldScore,
+		NewScore:   newScore,
+		Factors:    factors,
+	}
+}
+
+// CustomerErasedEvent is raised when a customer's personal data is erased.
+// The other services erase their copies of the customer's data when they
+// receive it. It carries hashes of the erased email addresses and phone
+// numbers rather than the identifiers, so the erased data is not copied into
+// the outbox and message queues.
+type CustomerErasedEvent struct {
+	BaseDomainEvent
+	CustomerID       uuid.UUID `json:"customer_id"`
+	ErasureID        uuid.UUID `json:"erasure_id"`
+	IdentifierHashes []string  `json:"identifier_hashes"`
+}
+
+// NewCustomerErasedEvent creates a new CustomerErasedEvent.
+func NewCustomerErasedEvent(customer *Customer, erasureID uuid.UUID, identifierHashes []string) *CustomerErasedEvent {
+	return &CustomerErasedEvent{
+		BaseDomainEvent: NewBaseDomainEvent(
+			EventTypeCustomerErased,
+			customer.ID,
+			AggregateTypeCustomer,
+			customer.TenantID,
+			customer.Version,
+		),
+		CustomerID:       customer.ID,
+		ErasureID:        erasureID,
+		IdentifierHashes: identifierHashes,
+	}
+}
+
 // CustomerTierChangedEvent is raised when customer tier changes.
 type CustomerTierChangedEvent struct {
 	BaseDomainEvent
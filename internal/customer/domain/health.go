@@ -0,0 +1,285 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ============================================================================
+// Customer Health
+// ============================================================================
+
+// HealthTrend is the direction of a customer's health score since its
+// previous calculation.
+type HealthTrend string
+
+const (
+	HealthTrendUp     HealthTrend = "up"
+	HealthTrendDown   HealthTrend = "down"
+	HealthTrendStable HealthTrend = "stable"
+)
+
+// IsValid returns true if the trend is known.
+func (t HealthTrend) IsValid() bool {
+	switch t {
+	case HealthTrendUp, HealthTrendDown, HealthTrendStable:
+		return true
+	}
+	return false
+}
+
+// The health score is out of 100 points, split between its four factors.
+const (
+	HealthActivityPoints   = 30
+	HealthEngagementPoints = 20
+	HealthInvoicePoints    = 25
+	HealthOrderPoints      = 25
+)
+
+const (
+	// HealthInterval is how often a customer's health score is recalculated.
+	HealthInterval = 24 * time.Hour
+
+	// HealthOrderWindow is the period orders are counted over, and
+	// HealthOrderTarget the number of orders in it that scores full points.
+	HealthOrderWindow = 365 * 24 * time.Hour
+	HealthOrderTarget = 6
+
+	// HealthTrendPoints is the change in score needed for the trend to move
+	// up or down rather than stay stable.
+	HealthTrendPoints = 5
+
+	// HealthAlertThreshold is the score below which a customer is at risk,
+	// and HealthDropPoints the fall between two calculations that alerts the
+	// owner whatever the score.
+	HealthAlertThreshold = 40
+	HealthDropPoints     = 15
+
+	// invoiceOverduePoints are lost per overdue invoice, and all invoice
+	// points once an invoice is overdue for longer than invoiceOverdueLimit.
+	invoiceOverduePoints = 10
+	invoiceOverdueLimit  = 60 * 24 * time.Hour
+)
+
+// HealthFactors are the points a customer's health score is made of.
+type HealthFactors struct {
+	Activity   int `json:"activity" bson:"activity"`
+	Engagement int `json:"engagement" bson:"engagement"`
+	Invoices   int `json:"invoices" bson:"invoices"`
+	Orders     int `json:"orders" bson:"orders"`
+}
+
+// Total returns the health score of the factors.
+func (f HealthFactors) Total() int {
+	return f.Activity + f.Engagement + f.Invoices + f.Orders
+}
+
+// OverdueInvoice is an invoice of a deal with the customer that has passed
+// its due date unpaid.
+type OverdueInvoice struct {
+	InvoiceID   uuid.UUID `json:"invoice_id" bson:"invoice_id"`
+	DealID      uuid.UUID `json:"deal_id" bson:"deal_id"`
+	Number      string    `json:"number,omitempty" bson:"number,omitempty"`
+	Outstanding Money     `json:"outstanding" bson:"outstanding"`
+	DueDate     time.Time `json:"due_date" bson:"due_date"`
+}
+
+// RecordOverdueInvoice records an invoice that passed its due date unpaid.
+// Recording the same invoice again updates its outstanding amount. It returns
+// true if the customer changed.
+func (c *Customer) RecordOverdueInvoice(invoice OverdueInvoice) bool {
+	if invoice.Outstanding.Amount <= 0 {
+		return false
+	}
+	invoice.DueDate = invoice.DueDate.UTC()
+
+	for i := range c.OverdueInvoices {
+		if c.OverdueInvoices[i].InvoiceID == invoice.InvoiceID {
+			if c.OverdueInvoices[i] == invoice {
+				return false
+			}
+			c.OverdueInvoices[i] = invoice
+			c.MarkUpdated()
+			c.IncrementVersion()
+			return true
+		}
+	}
+
+	c.OverdueInvoices = append(c.OverdueInvoices, invoice)
+	c.MarkUpdated()
+	c.IncrementVersion()
+	return true
+}
+
+// RecordInvoicePayment records a payment on a deal with the customer. A
+// payment against an overdue invoice reduces its outstanding amount, and the
+// invoice is no longer overdue once paid. When the deal has nothing left
+// outstanding, all its invoices are paid. It returns true if the customer
+// changed.
+func (c *Customer) RecordInvoicePayment(dealID uuid.UUID, invoiceID *uuid.UUID, amount int64, dealOutstanding int64) bool {
+	changed := false
+	invoices := c.OverdueInvoices[:0]
+	for _, invoice := range c.OverdueInvoices {
+		if invoice.DealID == dealID {
+			if dealOutstanding <= 0 {
+				changed = true
+				continue
+			}
+			if invoiceID != nil && invoice.InvoiceID == *invoiceID && amount > 0 {
+				invoice.Outstanding.Amount -= amount
+				changed = true
+				if invoice.Outstanding.Amount <= 0 {
+					continue
+				}
+			}
+		}
+		invoices = append(invoices, invoice)
+	}
+	if !changed {
+		return false
+	}
+
+	c.OverdueInvoices = invoices
+	if len(c.OverdueInvoices) == 0 {
+		c.OverdueInvoices = nil
+	}
+	c.MarkUpdated()
+	c.IncrementVersion()
+	return true
+}
+
+// RecordNotificationEngagement records the customer opening or clicking a
+// notification sent to them.
+func (c *Customer) RecordNotificationEngagement(clicked bool, engagedAt time.Time) {
+	if clicked {
+		c.Stats.NotificationClicks++
+	} else {
+		c.Stats.NotificationOpens++
+	}
+	engagedAt = engagedAt.UTC()
+	if c.Stats.LastEngagedAt == nil || engagedAt.After(*c.Stats.LastEngagedAt) {
+		c.Stats.LastEngagedAt = &engagedAt
+	}
+	c.MarkUpdated()
+	c.IncrementVersion()
+}
+
+// LastActivityAt returns the time of the customer's most recent contact, won
+// deal or purchase, or nil if there has been none.
+func (c *Customer) LastActivityAt() *time.Time {
+	var last *time.Time
+	for _, at := range []*time.Time{c.LastContactedAt, c.Stats.LastWonDealAt, c.Financials.LastPurchaseAt} {
+		if at != nil && (last == nil || at.After(*last)) {
+			last = at
+		}
+	}
+	return last
+}
+
+// HealthFactorsAt scores the customer's health as of now, given the number of
+// orders the customer placed in the order window:
+//   - activity: the recency of the last contact, won deal or purchase
+//   - engagement: the recency of the last opened or clicked notification
+//   - invoices: full points without overdue invoices, fewer per overdue
+//     invoice, and none once an invoice is long overdue
+//   - orders: the number of orders against the target
+func (c *Customer) HealthFactorsAt(recentOrders int, now time.Time) HealthFactors {
+	var factors HealthFactors
+
+	if last := c.LastActivityAt(); last != nil {
+		factors.Activity = recencyPoints(now.Sub(*last), HealthActivityPoints, []recencyStep{
+			{14, 100}, {30, 80}, {60, 50}, {90, 25}, {180, 10},
+		})
+	}
+
+	if c.Stats.LastEngagedAt != nil {
+		factors.Engagement = recencyPoints(now.Sub(*c.Stats.LastEngagedAt), HealthEngagementPoints, []recencyStep{
+			{30, 100}, {90, 60}, {180, 25},
+		})
+	}
+
+	factors.Invoices = HealthInvoicePoints
+	for _, invoice := range c.OverdueInvoices {
+		if now.Sub(invoice.DueDate) > invoiceOverdueLimit {
+			factors.Invoices = 0
+			break
+		}
+		factors.Invoices -= invoiceOverduePoints
+	}
+	if factors.Invoices < 0 {
+		factors.Invoices = 0
+	}
+
+	if recentOrders > HealthOrderTarget {
+		recentOrders = HealthOrderTarget
+	}
+	if recentOrders > 0 {
+		factors.Orders = recentOrders * HealthOrderPoints / HealthOrderTarget
+	}
+
+	return factors
+}
+
+// recencyStep scores a time since an event of up to Days days with Percent
+// of a factor's points.
+type recencyStep struct {
+	Days    int
+	Percent int
+}
+
+// recencyPoints returns the points of the first step the time since an event
+// falls in, or none if it is older than the last step.
+func recencyPoints(since time.Duration, points int, steps []recencyStep) int {
+	for _, step := range steps {
+		if since <= time.Duration(step.Days)*24*time.Hour {
+			return points * step.Percent / 100
+		}
+	}
+	return 0
+}
+
+// IsHealthDue returns true if the customer's health score has not been
+// calculated in the health interval.
+func (c *Customer) IsHealthDue(now time.Time) bool {
+	return c.Stats.LastCalculatedAt == nil || now.Sub(*c.Stats.LastCalculatedAt) >= HealthInterval
+}
+
+// CalculateHealth recalculates the customer's health score as of now from
+// its activity, notification engagement, overdue invoices and the number of
+// orders placed in the order window, and records the trend since the previous
+// calculation. The owner is alerted with a CustomerHealthDroppedEvent when
+// the score falls below the alert threshold or drops sharply.
+func (c *Customer) CalculateHealth(recentOrders int, now time.Time) {
+	now = now.UTC()
+	factors := c.HealthFactorsAt(recentOrders, now)
+	score := factors.Total()
+
+	calculated := c.Stats.LastCalculatedAt != nil
+	previous := c.Stats.HealthScore
+
+	c.Stats.HealthScore = score
+	c.Stats.HealthFactors = &factors
+	c.Stats.LastCalculatedAt = &now
+	c.Stats.HealthTrend = HealthTrendStable
+	c.Stats.PreviousHealthScore = nil
+	if calculated {
+		c.Stats.PreviousHealthScore = &previous
+		switch {
+		case score-previous >= HealthTrendPoints:
+			c.Stats.HealthTrend = HealthTrendUp
+		case previous-score >= HealthTrendPoints:
+			c.Stats.HealthTrend = HealthTrendDown
+		}
+	}
+	c.MarkUpdated()
+	c.IncrementVersion()
+
+	if !calculated || score >= previous {
+		return
+	}
+	crossed := previous >= HealthAlertThreshold && score < HealthAlertThreshold
+	if crossed || previous-score >= HealthDropPoints {
+		c.AddDomainEvent(NewCustomerHealthDroppedEvent(c, previous, score, factors))
+	}
+}
@@ -0,0 +1,123 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ============================================================================
+// Customer Health Tests
+// ============================================================================
+
+func healthDroppedEvents(c *Customer) []*CustomerHealthDroppedEvent {
+	var events []*CustomerHealthDroppedEvent
+	for _, event := range c.GetDomainEvents() {
+		if e, ok := event.(*CustomerHealthDroppedEvent); ok {
+			events = append(events, e)
+		}
+	}
+	return events
+}
+
+func daysAgo(now time.Time, days int) *time.Time {
+	t := now.AddDate(0, 0, -days)
+	return &t
+}
+
+func TestCustomer_HealthFactorsAt(t *testing.T) {
+	now := time.Date(2026, 10, 17, 9, 0, 0, 0, time.UTC)
+	customer := createTestCustomer(t)
+
+	if factors := customer.HealthFactorsAt(0, now); factors != (HealthFactors{Invoices: HealthInvoicePoints}) {
+		t.Errorf("HealthFactorsAt() for a new customer = %+v, want only invoice points", factors)
+	}
+
+	customer.LastContactedAt = daysAgo(now, 10)
+	customer.Stats.LastEngagedAt = daysAgo(now, 60)
+	customer.RecordOverdueInvoice(OverdueInvoice{
+		InvoiceID:   uuid.New(),
+		DealID:      uuid.New(),
+		Outstanding: Money{Amount: 250000, Currency: "MYR"},
+		DueDate:     *daysAgo(now, 20),
+	})
+
+	factors := customer.HealthFactorsAt(3, now)
+	want := HealthFactors{Activity: 30, Engagement: 12, Invoices: 15, Orders: 12}
+	if factors != want {
+		t.Errorf("HealthFactorsAt() = %+v, want %+v", factors, want)
+	}
+	if factors.Total() != 69 {
+		t.Errorf("Total() = %d, want 69", factors.Total())
+	}
+
+	customer.OverdueInvoices[0].DueDate = *daysAgo(now, 90)
+	if factors := customer.HealthFactorsAt(12, now); factors.Invoices != 0 || factors.Orders != HealthOrderPoints {
+		t.Errorf("HealthFactorsAt() = %+v, want no invoice points and full order points", factors)
+	}
+}
+
+func TestCustomer_CalculateHealth(t *testing.T) {
+	now := time.Date(2026, 10, 17, 9, 0, 0, 0, time.UTC)
+	customer := createTestCustomer(t)
+	customer.LastContactedAt = daysAgo(now, 3)
+	customer.Stats.LastEngagedAt = daysAgo(now, 5)
+
+	customer.CalculateHealth(6, now)
+	if customer.Stats.HealthScore != 100 || customer.Stats.HealthTrend != HealthTrendStable || customer.Stats.PreviousHealthScore != nil {
+		t.Fatalf("first calculation = %d (%s), want 100 without a trend", customer.Stats.HealthScore, customer.Stats.HealthTrend)
+	}
+	if customer.IsHealthDue(now.Add(time.Hour)) {
+		t.Error("IsHealthDue() = true right after a calculation")
+	}
+
+	// 100 to 94: a fall, but not sharp enough to alert
+	customer.LastContactedAt = daysAgo(now, 20)
+	customer.CalculateHealth(6, now.Add(HealthInterval))
+	if customer.Stats.HealthScore != 94 || customer.Stats.HealthTrend != HealthTrendDown {
+		t.Errorf("second calculation = %d (%s), want 94 and down", customer.Stats.HealthScore, customer.Stats.HealthTrend)
+	}
+	if len(healthDroppedEvents(customer)) != 0 {
+		t.Error("CalculateHealth() alerted on a small drop")
+	}
+
+	// 94 to 33: below the threshold
+	customer.LastContactedAt = nil
+	customer.Stats.LastEngagedAt = nil
+	customer.CalculateHealth(2, now.Add(2*HealthInterval))
+	events := healthDroppedEvents(customer)
+	if customer.Stats.HealthScore != 33 || len(events) != 1 {
+		t.Fatalf("third calculation = %d with %d alerts, want 33 with one alert", customer.Stats.HealthScore, len(events))
+	}
+	if events[0].OldScore != 94 || events[0].NewScore != 33 || events[0].Factors.Invoices != HealthInvoicePoints {
+		t.Errorf("event = %+v, want a drop from 94 to 33", events[0])
+	}
+}
+
+func TestCustomer_RecordInvoicePayment(t *testing.T) {
+	customer := createTestCustomer(t)
+	dealID := uuid.New()
+	first, second := uuid.New(), uuid.New()
+	for _, id := range []uuid.UUID{first, second} {
+		customer.RecordOverdueInvoice(OverdueInvoice{
+			InvoiceID:   id,
+			DealID:      dealID,
+			Outstanding: Money{Amount: 100000, Currency: "MYR"},
+			DueDate:     time.Now().AddDate(0, 0, -10),
+		})
+	}
+
+	if !customer.RecordInvoicePayment(dealID, &first, 40000, 160000) || customer.OverdueInvoices[0].Outstanding.Amount != 60000 {
+		t.Fatalf("partial payment left %+v, want 60000 outstanding on the first invoice", customer.OverdueInvoices)
+	}
+	if !customer.RecordInvoicePayment(dealID, &first, 60000, 100000) || len(customer.OverdueInvoices) != 1 {
+		t.Fatalf("full payment left %d overdue invoices, want 1", len(customer.OverdueInvoices))
+	}
+	if customer.RecordInvoicePayment(uuid.New(), nil, 5000, 0) {
+		t.Error("RecordInvoicePayment() changed the customer for another deal")
+	}
+	if !customer.RecordInvoicePayment(dealID, nil, 100000, 0) || customer.OverdueInvoices != nil {
+		t.Errorf("settling the deal left %+v, want no overdue invoices", customer.OverdueInvoices)
+	}
+}
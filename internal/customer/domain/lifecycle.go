@@ -0,0 +1,129 @@
+package domain
+
+import (
+	"strings"
+	"time"
+)
+
+// ============================================================================
+// Customer Lifecycle
+// ============================================================================
+
+// CustomerLifecycle is the stage of a customer's buying relationship. Unlike
+// the status, which records what the tenant did with the customer, the
+// lifecycle follows the customer's deals: it moves on its own as deals are won
+// or stop coming, unless it has been set by hand.
+type CustomerLifecycle string
+
+const (
+	LifecycleProspect CustomerLifecycle = "prospect"
+	LifecycleActive   CustomerLifecycle = "active"
+	LifecycleDormant  CustomerLifecycle = "dormant"
+	LifecycleChurned  CustomerLifecycle = "churned"
+)
+
+// DormancyPeriod is how long an active customer can go without a won deal
+// before becoming dormant.
+const DormancyPeriod = 365 * 24 * time.Hour
+
+// IsValid returns true if the lifecycle stage is known.
+func (l CustomerLifecycle) IsValid() bool {
+	switch l {
+	case LifecycleProspect, LifecycleActive, LifecycleDormant, LifecycleChurned:
+		return true
+	}
+	return false
+}
+
+// CurrentLifecycle returns the customer's lifecycle stage. Customers stored
+// before lifecycles were tracked have their stage derived from their status.
+func (c *Customer) CurrentLifecycle() CustomerLifecycle {
+	if c.Lifecycle != "" {
+		return c.Lifecycle
+	}
+	switch c.Status {
+	case CustomerStatusLead, CustomerStatusProspect:
+		return LifecycleProspect
+	case CustomerStatusChurned:
+		return LifecycleChurned
+	default:
+		return LifecycleActive
+	}
+}
+
+// RecordWonDeal records a deal won with the customer at the given time. A won
+// deal makes the customer active and ends a manual lifecycle override, since
+// the customer is buying again whatever stage they were put in. Recording the
+// same deal again changes nothing further.
+func (c *Customer) RecordWonDeal(wonAt time.Time) {
+	wonAt = wonAt.UTC()
+	if c.Stats.LastWonDealAt == nil || wonAt.After(*c.Stats.LastWonDealAt) {
+		c.Stats.LastWonDealAt = &wonAt
+	}
+	c.LifecycleOverride = false
+	c.MarkUpdated()
+	c.IncrementVersion()
+
+	c.changeLifecycle(LifecycleActive, "deal_won", true, wonAt)
+}
+
+// EvaluateLifecycle applies the automatic lifecycle transitions as of now: an
+// active customer without a won deal in the dormancy period becomes dormant.
+// Customers whose lifecycle was set by hand are left alone, and customers
+// stored before lifecycles were tracked have their derived stage recorded.
+// It returns true if the customer changed.
+func (c *Customer) EvaluateLifecycle(now time.Time) bool {
+	changed := false
+	if c.Lifecycle == "" {
+		c.Lifecycle = c.CurrentLifecycle()
+		changed = true
+	}
+	if c.LifecycleOverride || c.Lifecycle != LifecycleActive {
+		return changed
+	}
+
+	lastActivity := c.CreatedAt
+	if c.ConvertedAt != nil {
+		lastActivity = *c.ConvertedAt
+	}
+	if c.Stats.LastWonDealAt != nil {
+		lastActivity = *c.Stats.LastWonDealAt
+	}
+	if now.Sub(lastActivity) < DormancyPeriod {
+		return changed
+	}
+
+	c.MarkUpdated()
+	c.IncrementVersion()
+	c.changeLifecycle(LifecycleDormant, "no_won_deal", true, now)
+	return true
+}
+
+// SetLifecycle sets the lifecycle stage by hand. The stage is kept until the
+// next won deal, so automatic transitions do not undo it.
+func (c *Customer) SetLifecycle(lifecycle CustomerLifecycle, reason string) error {
+	if !lifecycle.IsValid() {
+		return NewValidationError("lifecycle", "invalid lifecycle stage", "INVALID_LIFECYCLE")
+	}
+
+	c.LifecycleOverride = true
+	c.MarkUpdated()
+	c.IncrementVersion()
+	c.changeLifecycle(lifecycle, strings.TrimSpace(reason), false, time.Now().UTC())
+	return nil
+}
+
+// changeLifecycle moves the customer to a lifecycle stage, raising a
+// CustomerLifecycleChangedEvent. Moving to the stage the customer is already
+// in only records the stage.
+func (c *Customer) changeLifecycle(lifecycle CustomerLifecycle, reason string, automatic bool, at time.Time) {
+	old := c.CurrentLifecycle()
+	c.Lifecycle = lifecycle
+	if old == lifecycle {
+		return
+	}
+
+	at = at.UTC()
+	c.LifecycleChangedAt = &at
+	c.AddDomainEvent(NewCustomerLifecycleChangedEvent(c, old, lifecycle, reason, automatic))
+}
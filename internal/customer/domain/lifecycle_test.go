@@ -0,0 +1,120 @@
+package domain
+
+import (
+	"testing"
+	"time"
+)
+
+// ============================================================================
+// Customer Lifecycle Tests
+// ============================================================================
+
+func lifecycleEvents(c *Customer) []*CustomerLifecycleChangedEvent {
+	var events []*CustomerLifecycleChangedEvent
+	for _, event := range c.GetDomainEvents() {
+		if e, ok := event.(*CustomerLifecycleChangedEvent); ok {
+			events = append(events, e)
+		}
+	}
+	return events
+}
+
+func TestCustomer_Lifecycle_Conversion(t *testing.T) {
+	customer := createTestCustomer(t)
+	if customer.CurrentLifecycle() != LifecycleProspect {
+		t.Fatalf("new customer lifecycle = %s, want prospect", customer.CurrentLifecycle())
+	}
+
+	if err := customer.ConvertToCustomer(); err != nil {
+		t.Fatalf("ConvertToCustomer() error = %v", err)
+	}
+
+	events := lifecycleEvents(customer)
+	if customer.Lifecycle != LifecycleActive || len(events) != 1 {
+		t.Fatalf("lifecycle = %s with %d events, want active with 1 event", customer.Lifecycle, len(events))
+	}
+	if events[0].OldLifecycle != LifecycleProspect || !events[0].Automatic {
+		t.Errorf("event = %+v, want an automatic change from prospect", events[0])
+	}
+}
+
+func TestCustomer_EvaluateLifecycle(t *testing.T) {
+	now := time.Now().UTC()
+
+	tests := []struct {
+		name     string
+		sinceWon time.Duration
+		override bool
+		want     CustomerLifecycle
+	}{
+		{"recent won deal", 30 * 24 * time.Hour, false, LifecycleActive},
+		{"no won deal in a year", 400 * 24 * time.Hour, false, LifecycleDormant},
+		{"overridden", 400 * 24 * time.Hour, true, LifecycleActive},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			customer := createTestCustomer(t)
+			customer.Lifecycle = LifecycleActive
+			customer.LifecycleOverride = tt.override
+			lastWon := now.Add(-tt.sinceWon)
+			customer.Stats.LastWonDealAt = &lastWon
+
+			changed := customer.EvaluateLifecycle(now)
+
+			if customer.Lifecycle != tt.want {
+				t.Errorf("lifecycle = %s, want %s", customer.Lifecycle, tt.want)
+			}
+			if changed != (tt.want != LifecycleActive) {
+				t.Errorf("EvaluateLifecycle() = %v", changed)
+			}
+		})
+	}
+}
+
+func TestCustomer_EvaluateLifecycle_Legacy(t *testing.T) {
+	customer := createTestCustomer(t)
+	customer.Lifecycle = ""
+	customer.Status = CustomerStatusChurned
+
+	if !customer.EvaluateLifecycle(time.Now()) {
+		t.Error("EvaluateLifecycle() should record the lifecycle of a legacy customer")
+	}
+	if customer.Lifecycle != LifecycleChurned || len(lifecycleEvents(customer)) != 0 {
+		t.Errorf("lifecycle = %s, want churned without an event", customer.Lifecycle)
+	}
+}
+
+func TestCustomer_SetLifecycle_UntilWonDeal(t *testing.T) {
+	customer := createTestCustomer(t)
+
+	if err := customer.SetLifecycle("hibernating", ""); err == nil {
+		t.Error("SetLifecycle() should reject an unknown stage")
+	}
+
+	if err := customer.SetLifecycle(LifecycleDormant, "closed outlet"); err != nil {
+		t.Fatalf("SetLifecycle() error = %v", err)
+	}
+	if customer.Lifecycle != LifecycleDormant || !customer.LifecycleOverride {
+		t.Fatalf("lifecycle = %s, override = %v; want an overridden dormant lifecycle", customer.Lifecycle, customer.LifecycleOverride)
+	}
+
+	customer.RecordWonDeal(time.Now())
+
+	if customer.Lifecycle != LifecycleActive || customer.LifecycleOverride {
+		t.Errorf("lifecycle = %s, override = %v; a won deal should make the customer active again", customer.Lifecycle, customer.LifecycleOverride)
+	}
+	if customer.Stats.LastWonDealAt == nil {
+		t.Error("LastWonDealAt should be set")
+	}
+}
+
+func TestCustomer_MarkAsChurned_Lifecycle(t *testing.T) {
+	customer := createTestCustomer(t)
+
+	customer.MarkAsChurned("switched supplier")
+
+	if customer.Lifecycle != LifecycleChurned {
+		t.Errorf("lifecycle = %s, want churned", customer.Lifecycle)
+	}
+}
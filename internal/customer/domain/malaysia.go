@@ -0,0 +1,83 @@
+package domain
+
+import (
+	"strconv"
+	"strings"
+)
+
+// ============================================================================
+// Malaysian States and Postcodes
+// ============================================================================
+
+// MalaysianState is a state or federal territory of Malaysia with the
+// postcode ranges Pos Malaysia allocates to it.
+type MalaysianState struct {
+	Code      string
+	Name      string
+	aliases   []string
+	postcodes []postcodeRange
+}
+
+// postcodeRange is an inclusive range of five-digit postcodes.
+type postcodeRange struct {
+	from, to int
+}
+
+// MalaysianStates lists the 13 states and 3 federal territories of Malaysia.
+// Postcode ranges overlap around the Klang Valley, where Selangor and Kuala
+// Lumpur share the 68xxx block.
+var MalaysianStates = []MalaysianState{
+	{Code: "JHR", Name: "Johor", aliases: []string{"johore"}, postcodes: []postcodeRange{{79000, 86999}}},
+	{Code: "KDH", Name: "Kedah", postcodes: []postcodeRange{{5000, 9999}}},
+	{Code: "KTN", Name: "Kelantan", postcodes: []postcodeRange{{15000, 18999}}},
+	{Code: "MLK", Name: "Melaka", aliases: []string{"malacca"}, postcodes: []postcodeRange{{75000, 78999}}},
+	{Code: "NSN", Name: "Negeri Sembilan", aliases: []string{"n. sembilan", "n sembilan"}, postcodes: []postcodeRange{{70000, 73999}}},
+	{Code: "PHG", Name: "Pahang", postcodes: []postcodeRange{{25000, 28999}, {39000, 39999}, {49000, 49999}, {69000, 69999}}},
+	{Code: "PNG", Name: "Pulau Pinang", aliases: []string{"penang", "p. pinang"}, postcodes: []postcodeRange{{10000, 14999}}},
+	{Code: "PRK", Name: "Perak", postcodes: []postcodeRange{{30000, 36999}}},
+	{Code: "PLS", Name: "Perlis", postcodes: []postcodeRange{{1000, 2999}}},
+	{Code: "SBH", Name: "Sabah", postcodes: []postcodeRange{{88000, 91999}}},
+	{Code: "SWK", Name: "Sarawak", postcodes: []postcodeRange{{93000, 98999}}},
+	{Code: "SGR", Name: "Selangor", postcodes: []postcodeRange{{40000, 48999}, {63000, 68999}}},
+	{Code: "TRG", Name: "Terengganu", aliases: []string{"trengganu"}, postcodes: []postcodeRange{{20000, 24999}}},
+	{Code: "KUL", Name: "Kuala Lumpur", aliases: []string{"wilayah persekutuan kuala lumpur", "wp kuala lumpur", "kl"}, postcodes: []postcodeRange{{50000, 60999}, {68000, 68999}}},
+	{Code: "LBN", Name: "Labuan", aliases: []string{"wilayah persekutuan labuan", "wp labuan"}, postcodes: []postcodeRange{{87000, 87999}}},
+	{Code: "PJY", Name: "Putrajaya", aliases: []string{"wilayah persekutuan putrajaya", "wp putrajaya"}, postcodes: []postcodeRange{{62000, 62999}}},
+}
+
+// FindMalaysianState finds a state by its code, name or a common alternative
+// name, ignoring case.
+func FindMalaysianState(state string) (MalaysianState, bool) {
+	key := strings.ToLower(strings.Join(strings.Fields(state), " "))
+	if key == "" {
+		return MalaysianState{}, false
+	}
+	for _, s := range MalaysianStates {
+		if key == strings.ToLower(s.Code) || key == strings.ToLower(s.Name) {
+			return s, true
+		}
+		for _, alias := range s.aliases {
+			if key == alias {
+				return s, true
+			}
+		}
+	}
+	return MalaysianState{}, false
+}
+
+// HasPostcode returns true if the postcode is allocated to the state.
+func (s MalaysianState) HasPostcode(postcode string) bool {
+	if len(postcode) != 5 {
+		return false
+	}
+	n, err := strconv.Atoi(postcode)
+	if err != nil {
+		return false
+	}
+	for _, r := range s.postcodes {
+		if n >= r.from && n <= r.to {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,221 @@
+package domain
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ============================================================================
+// Purchase History
+// ============================================================================
+
+// Purchase is an order placed by a customer. Purchases are recorded from the
+// deals won with the customer, one per won opportunity.
+type Purchase struct {
+	ID            uuid.UUID `json:"id" bson:"_id"`
+	TenantID      uuid.UUID `json:"tenant_id" bson:"tenant_id"`
+	CustomerID    uuid.UUID `json:"customer_id" bson:"customer_id"`
+	OpportunityID uuid.UUID `json:"opportunity_id" bson:"opportunity_id"`
+	Reference     string    `json:"reference,omitempty" bson:"reference,omitempty"`
+	Amount        Money     `json:"amount" bson:"amount"`
+	PurchasedAt   time.Time `json:"purchased_at" bson:"purchased_at"`
+	CreatedAt     time.Time `json:"created_at" bson:"created_at"`
+}
+
+// NewPurchase creates a purchase for a won opportunity.
+func NewPurchase(tenantID, customerID, opportunityID uuid.UUID, reference string, amount Money, purchasedAt time.Time) (*Purchase, error) {
+	if opportunityID == uuid.Nil {
+		return nil, NewValidationError("opportunity_id", "opportunity is required", "REQUIRED")
+	}
+	if amount.Amount < 0 {
+		return nil, NewValidationError("amount", "amount cannot be negative", "INVALID_AMOUNT")
+	}
+	if !ValidCurrencies[amount.Currency] {
+		return nil, NewValidationError("currency", "invalid currency", "INVALID_CURRENCY")
+	}
+
+	return &Purchase{
+		ID:            uuid.New(),
+		TenantID:      tenantID,
+		CustomerID:    customerID,
+		OpportunityID: opportunityID,
+		Reference:     reference,
+		Amount:        amount,
+		PurchasedAt:   purchasedAt.UTC(),
+		CreatedAt:     time.Now().UTC(),
+	}, nil
+}
+
+// CustomerRevenue is a customer's purchase totals for a period.
+type CustomerRevenue struct {
+	CustomerID     uuid.UUID `json:"customer_id"`
+	Revenue        Money     `json:"revenue"`
+	OrderCount     int       `json:"order_count"`
+	LastPurchaseAt time.Time `json:"last_purchase_at"`
+}
+
+// ApplyPurchase adds a purchase to the customer's purchase totals. Totals are
+// kept in the customer's billing currency, so a purchase in another currency
+// only updates the last purchase date.
+func (c *Customer) ApplyPurchase(purchase *Purchase) {
+	if c.Financials.LastPurchaseAt == nil || purchase.PurchasedAt.After(*c.Financials.LastPurchaseAt) {
+		purchasedAt := purchase.PurchasedAt
+		c.Financials.LastPurchaseAt = &purchasedAt
+	}
+
+	if c.Financials.Currency == "" {
+		c.Financials.Currency = purchase.Amount.Currency
+	}
+	if purchase.Amount.Currency == c.Financials.Currency {
+		c.Financials.TotalPurchases++
+		if c.Financials.TotalSpent == nil {
+			spent := purchase.Amount
+			c.Financials.TotalSpent = &spent
+		} else if total, err := c.Financials.TotalSpent.Add(purchase.Amount); err == nil {
+			c.Financials.TotalSpent = &total
+		}
+	}
+
+	c.MarkUpdated()
+	c.IncrementVersion()
+}
+
+// AverageOrderValue returns the customer's average spend per purchase, or nil
+// if the customer has no purchases.
+func (c *Customer) AverageOrderValue() *Money {
+	if c.Financials.TotalPurchases == 0 || c.Financials.TotalSpent == nil {
+		return nil
+	}
+	return &Money{
+		Amount:   c.Financials.TotalSpent.Amount / int64(c.Financials.TotalPurchases),
+		Currency: c.Financials.TotalSpent.Currency,
+	}
+}
+
+// ============================================================================
+// Loyalty Tiers
+// ============================================================================
+
+// LoyaltyThreshold is the lifetime spend and number of purchases a customer
+// needs to reach a loyalty tier. Both must be met.
+type LoyaltyThreshold struct {
+	Tier       CustomerTier `json:"tier" yaml:"tier"`
+	MinRevenue int64        `json:"min_revenue" yaml:"min_revenue"` // In the smallest currency unit
+	MinOrders  int          `json:"min_orders" yaml:"min_orders"`
+}
+
+// LoyaltyPolicy assigns loyalty tiers from customers' purchase totals.
+type LoyaltyPolicy struct {
+	Currency   Currency           `json:"currency" yaml:"currency"`
+	Thresholds []LoyaltyThreshold `json:"thresholds" yaml:"thresholds"`
+}
+
+// DefaultLoyaltyPolicy returns the default loyalty policy, in ringgit.
+func DefaultLoyaltyPolicy() LoyaltyPolicy {
+	return LoyaltyPolicy{
+		Currency: CurrencyMYR,
+		Thresholds: []LoyaltyThreshold{
+			{Tier: CustomerTierBronze, MinRevenue: 1_000_00, MinOrders: 2},
+			{Tier: CustomerTierSilver, MinRevenue: 10_000_00, MinOrders: 5},
+			{Tier: CustomerTierGold, MinRevenue: 50_000_00, MinOrders: 10},
+			{Tier: CustomerTierPlatinum, MinRevenue: 150_000_00, MinOrders: 20},
+		},
+	}
+}
+
+// loyaltyTierRank orders the tiers. Enterprise is negotiated rather than
+// earned, so it is not part of the loyalty ladder.
+var loyaltyTierRank = map[CustomerTier]int{
+	CustomerTierStandard: 0,
+	CustomerTierBronze:   1,
+	CustomerTierSilver:   2,
+	CustomerTierGold:     3,
+	CustomerTierPlatinum: 4,
+}
+
+// Validate checks that the policy's thresholds are loyalty tiers that rise
+// with the tier.
+func (p LoyaltyPolicy) Validate() error {
+	if !ValidCurrencies[p.Currency] {
+		return NewValidationError("currency", "invalid loyalty currency", "INVALID_CURRENCY")
+	}
+
+	thresholds := p.sorted()
+	for i, threshold := range thresholds {
+		if rank, ok := loyaltyTierRank[threshold.Tier]; !ok || rank == 0 {
+			return NewValidationError("thresholds", fmt.Sprintf("%s is not a loyalty tier", threshold.Tier), "INVALID_TIER")
+		}
+		if threshold.MinRevenue < 0 || threshold.MinOrders < 0 {
+			return NewValidationError("thresholds", fmt.Sprintf("%s threshold cannot be negative", threshold.Tier), "INVALID_THRESHOLD")
+		}
+		if i == 0 {
+			continue
+		}
+		prev := thresholds[i-1]
+		if prev.Tier == threshold.Tier {
+			return NewValidationError("thresholds", fmt.Sprintf("%s has more than one threshold", threshold.Tier), "DUPLICATE_TIER")
+		}
+		if threshold.MinRevenue < prev.MinRevenue || threshold.MinOrders < prev.MinOrders {
+			return NewValidationError("thresholds", fmt.Sprintf("%s threshold is below %s", threshold.Tier, prev.Tier), "INVALID_THRESHOLD")
+		}
+	}
+	return nil
+}
+
+// TierFor returns the highest tier whose threshold the purchase totals meet.
+func (p LoyaltyPolicy) TierFor(revenue int64, orders int) CustomerTier {
+	tier := CustomerTierStandard
+	for _, threshold := range p.sorted() {
+		if revenue >= threshold.MinRevenue && orders >= threshold.MinOrders {
+			tier = threshold.Tier
+		}
+	}
+	return tier
+}
+
+// NextThreshold returns the threshold of the tier above the given tier, or
+// nil if there is none.
+func (p LoyaltyPolicy) NextThreshold(tier CustomerTier) *LoyaltyThreshold {
+	rank, ok := loyaltyTierRank[tier]
+	if !ok {
+		return nil
+	}
+	for _, threshold := range p.sorted() {
+		if loyaltyTierRank[threshold.Tier] > rank {
+			return &threshold
+		}
+	}
+	return nil
+}
+
+// sorted returns the thresholds from the lowest tier to the highest.
+func (p LoyaltyPolicy) sorted() []LoyaltyThreshold {
+	thresholds := append([]LoyaltyThreshold(nil), p.Thresholds...)
+	sort.SliceStable(thresholds, func(i, j int) bool {
+		return loyaltyTierRank[thresholds[i].Tier] < loyaltyTierRank[thresholds[j].Tier]
+	})
+	return thresholds
+}
+
+// ApplyLoyaltyTier raises the customer's tier to the loyalty tier their
+// purchase totals have earned, and reports whether it changed. Tiers are
+// never lowered automatically, and enterprise customers keep their tier.
+func (c *Customer) ApplyLoyaltyTier(policy LoyaltyPolicy) bool {
+	current, ok := loyaltyTierRank[c.Tier]
+	if !ok && c.Tier != "" {
+		return false
+	}
+	if c.Financials.TotalSpent == nil || c.Financials.TotalSpent.Currency != policy.Currency {
+		return false
+	}
+
+	earned := policy.TierFor(c.Financials.TotalSpent.Amount, c.Financials.TotalPurchases)
+	if loyaltyTierRank[earned] <= current {
+		return false
+	}
+	c.UpdateTier(earned)
+	return true
+}
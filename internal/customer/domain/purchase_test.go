@@ -0,0 +1,114 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ============================================================================
+// Purchase and Loyalty Tests
+// ============================================================================
+
+func newTestPurchase(t *testing.T, customer *Customer, amount Money, at time.Time) *Purchase {
+	t.Helper()
+	purchase, err := NewPurchase(customer.TenantID, customer.ID, uuid.New(), "", amount, at)
+	if err != nil {
+		t.Fatalf("NewPurchase() error = %v", err)
+	}
+	return purchase
+}
+
+func TestCustomer_ApplyPurchase(t *testing.T) {
+	customer := createTestCustomer(t)
+	now := time.Now().UTC()
+
+	customer.ApplyPurchase(newTestPurchase(t, customer, Money{Amount: 300_00, Currency: CurrencyMYR}, now))
+	customer.ApplyPurchase(newTestPurchase(t, customer, Money{Amount: 100_00, Currency: CurrencyMYR}, now.Add(-time.Hour)))
+	customer.ApplyPurchase(newTestPurchase(t, customer, Money{Amount: 50_00, Currency: CurrencyUSD}, now.Add(-2*time.Hour)))
+
+	if customer.Financials.TotalPurchases != 2 || customer.Financials.TotalSpent.Amount != 400_00 {
+		t.Errorf("totals = %d orders, %d spent; want 2 orders, 40000 spent", customer.Financials.TotalPurchases, customer.Financials.TotalSpent.Amount)
+	}
+	if !customer.Financials.LastPurchaseAt.Equal(now) {
+		t.Errorf("last purchase = %v, want the latest purchase %v", customer.Financials.LastPurchaseAt, now)
+	}
+	if avg := customer.AverageOrderValue(); avg == nil || avg.Amount != 200_00 {
+		t.Errorf("AverageOrderValue() = %v, want 20000", avg)
+	}
+}
+
+func TestLoyaltyPolicy_TierFor(t *testing.T) {
+	policy := DefaultLoyaltyPolicy()
+
+	tests := []struct {
+		name    string
+		revenue int64
+		orders  int
+		want    CustomerTier
+	}{
+		{"first order", 5_000_00, 1, CustomerTierStandard},
+		{"repeat order", 1_000_00, 2, CustomerTierBronze},
+		{"high revenue, few orders", 200_000_00, 6, CustomerTierSilver},
+		{"top boutique", 150_000_00, 20, CustomerTierPlatinum},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := policy.TierFor(tt.revenue, tt.orders); got != tt.want {
+				t.Errorf("TierFor() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoyaltyPolicy_Validate(t *testing.T) {
+	if err := DefaultLoyaltyPolicy().Validate(); err != nil {
+		t.Errorf("default policy Validate() error = %v", err)
+	}
+
+	policy := DefaultLoyaltyPolicy()
+	policy.Thresholds[1].MinRevenue = 0
+	if err := policy.Validate(); err == nil {
+		t.Error("Validate() should reject a tier below the tier beneath it")
+	}
+
+	policy = DefaultLoyaltyPolicy()
+	policy.Thresholds = append(policy.Thresholds, LoyaltyThreshold{Tier: CustomerTierEnterprise, MinRevenue: 1_000_000_00})
+	if err := policy.Validate(); err == nil {
+		t.Error("Validate() should reject the enterprise tier")
+	}
+}
+
+func TestCustomer_ApplyLoyaltyTier(t *testing.T) {
+	policy := DefaultLoyaltyPolicy()
+	spent := Money{Amount: 60_000_00, Currency: CurrencyMYR}
+
+	tests := []struct {
+		name    string
+		tier    CustomerTier
+		want    CustomerTier
+		changed bool
+	}{
+		{"upgrade", CustomerTierBronze, CustomerTierGold, true},
+		{"never downgraded", CustomerTierPlatinum, CustomerTierPlatinum, false},
+		{"enterprise kept", CustomerTierEnterprise, CustomerTierEnterprise, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			customer := createTestCustomer(t)
+			customer.Tier = tt.tier
+			customer.Financials.TotalSpent = &spent
+			customer.Financials.TotalPurchases = 12
+
+			if changed := customer.ApplyLoyaltyTier(policy); changed != tt.changed {
+				t.Errorf("ApplyLoyaltyTier() = %v, want %v", changed, tt.changed)
+			}
+			if customer.Tier != tt.want {
+				t.Errorf("tier = %s, want %s", customer.Tier, tt.want)
+			}
+		})
+	}
+}
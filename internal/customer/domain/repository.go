@@ -3,9 +3,12 @@ package domain
 
 import (
 	"context"
+	"io"
 	"time"
 
 	"github.com/google/uuid"
+
+	"github.com/kilang-desa-murni/crm/pkg/query"
 )
 
 // CustomerRepository defines the interface for customer persistence.
@@ -93,38 +96,60 @@ type CustomerRepository interface {
 
 	// FindRecentlyUpdated finds recently updated customers.
 	FindRecentlyUpdated(ctx context.Context, tenantID uuid.UUID, since time.Time, limit int) ([]*Customer, error)
+
+	// FindLifecycleDue finds customers of every tenant whose lifecycle needs
+	// evaluating: active customers without a won deal since the given time,
+	// and customers with no stored lifecycle.
+	FindLifecycleDue(ctx context.Context, activeBefore time.Time, limit int) ([]*Customer, error)
+
+	// FindHealthDue finds customers of every tenant whose health score was
+	// last calculated before the given time, or never.
+	FindHealthDue(ctx context.Context, calculatedBefore time.Time, limit int) ([]*Customer, error)
 }
 
 // CustomerFilter defines filtering options for customer queries.
 type CustomerFilter struct {
-	TenantID          *uuid.UUID       `json:"tenant_id,omitempty"`
-	IDs               []uuid.UUID      `json:"ids,omitempty"`
-	Codes             []string         `json:"codes,omitempty"`
-	Types             []CustomerType   `json:"types,omitempty"`
-	Statuses          []CustomerStatus `json:"statuses,omitempty"`
-	Tiers             []CustomerTier   `json:"tiers,omitempty"`
-	Sources           []CustomerSource `json:"sources,omitempty"`
-	Tags              []string         `json:"tags,omitempty"`
-	OwnerIDs          []uuid.UUID      `json:"owner_ids,omitempty"`
-	SegmentIDs        []uuid.UUID      `json:"segment_ids,omitempty"`
-	Industries        []Industry       `json:"industries,omitempty"`
-	Countries         []string         `json:"country_codes,omitempty"`
-	Query             string           `json:"query,omitempty"` // Full-text search
-	Email             string           `json:"email,omitempty"`
-	Phone             string           `json:"phone,omitempty"`
-	HasDeals          *bool            `json:"has_deals,omitempty"`
-	HasOpenDeals      *bool            `json:"has_open_deals,omitempty"`
-	CreatedAfter      *time.Time       `json:"created_after,omitempty"`
-	CreatedBefore     *time.Time       `json:"created_before,omitempty"`
-	UpdatedAfter      *time.Time       `json:"updated_after,omitempty"`
-	UpdatedBefore     *time.Time       `json:"updated_before,omitempty"`
-	LastContactAfter  *time.Time       `json:"last_contact_after,omitempty"`
-	LastContactBefore *time.Time       `json:"last_contact_before,omitempty"`
-	IncludeDeleted    bool             `json:"include_deleted,omitempty"`
-	Offset            int              `json:"offset"`
-	Limit             int              `json:"limit"`
-	SortBy            string           `json:"sort_by,omitempty"`
-	SortOrder         string           `json:"sort_order,omitempty"` // "asc" or "desc"
+	TenantID          *uuid.UUID          `json:"tenant_id,omitempty"`
+	IDs               []uuid.UUID         `json:"ids,omitempty"`
+	Codes             []string            `json:"codes,omitempty"`
+	Types             []CustomerType      `json:"types,omitempty"`
+	Statuses          []CustomerStatus    `json:"statuses,omitempty"`
+	Lifecycles        []CustomerLifecycle `json:"lifecycles,omitempty"`
+	Tiers             []CustomerTier      `json:"tiers,omitempty"`
+	Sources           []CustomerSource    `json:"sources,omitempty"`
+	Tags              []string            `json:"tags,omitempty"`
+	OwnerIDs          []uuid.UUID         `json:"owner_ids,omitempty"`
+	SegmentIDs        []uuid.UUID         `json:"segment_ids,omitempty"`
+	Industries        []Industry          `json:"industries,omitempty"`
+	Countries         []string            `json:"country_codes,omitempty"`
+	States            []string            `json:"states,omitempty"`
+	Cities            []string            `json:"cities,omitempty"`
+	Query             string              `json:"query,omitempty"` // Full-text search
+	Email             string              `json:"email,omitempty"`
+	Phone             string              `json:"phone,omitempty"`
+	HasDeals          *bool               `json:"has_deals,omitempty"`
+	HasOpenDeals      *bool               `json:"has_open_deals,omitempty"`
+	CreatedAfter      *time.Time          `json:"created_after,omitempty"`
+	CreatedBefore     *time.Time          `json:"created_before,omitempty"`
+	UpdatedAfter      *time.Time          `json:"updated_after,omitempty"`
+	UpdatedBefore     *time.Time          `json:"updated_before,omitempty"`
+	LastContactAfter  *time.Time          `json:"last_contact_after,omitempty"`
+	LastContactBefore *time.Time          `json:"last_contact_before,omitempty"`
+	Expr              query.Filter        `json:"-"` // Filter expression conditions, combined with the fields above
+	IncludeDeleted    bool                `json:"include_deleted,omitempty"`
+	Offset            int                 `json:"offset"`
+	Limit             int                 `json:"limit"`
+	SortBy            string              `json:"sort_by,omitempty"`
+	SortOrder         string              `json:"sort_order,omitempty"` // "asc" or "desc"
+	After             *CustomerCursor     `json:"-"`                    // Keyset position; takes precedence over Offset
+}
+
+// CustomerCursor is a keyset pagination position: the sort value of the last
+// customer seen and its code, which is unique per tenant and breaks ties
+// between equal sort values.
+type CustomerCursor struct {
+	SortValue string
+	Code      string
 }
 
 // CustomerList represents a paginated list of customers.
@@ -152,6 +177,10 @@ type ContactRepository interface {
 
 	// CountByCustomer counts contacts for a customer.
 	CountByCustomer(ctx context.Context, customerID uuid.UUID) (int, error)
+
+	// PurgeByCustomer permanently deletes all contacts for a customer, and
+	// returns the number deleted.
+	PurgeByCustomer(ctx context.Context, customerID uuid.UUID) (int64, error)
 }
 
 // ContactFilter defines filtering options for contact queries.
@@ -209,6 +238,10 @@ type NoteRepository interface {
 
 	// CountByCustomer counts notes for a customer.
 	CountByCustomer(ctx context.Context, customerID uuid.UUID) (int, error)
+
+	// PurgeByCustomer permanently deletes all notes for a customer, and
+	// returns the number deleted.
+	PurgeByCustomer(ctx context.Context, customerID uuid.UUID) (int64, error)
 }
 
 // Note represents a customer note.
@@ -258,6 +291,11 @@ type ActivityRepository interface {
 
 	// GetActivitySummary gets activity summary for a customer.
 	GetActivitySummary(ctx context.Context, customerID uuid.UUID) (map[ActivityType]int, error)
+
+	// AnonymizeByCustomer clears the subject, description, outcome and
+	// metadata of all activities for a customer, keeping their type and
+	// time, and returns the number anonymized.
+	AnonymizeByCustomer(ctx context.Context, customerID uuid.UUID) (int64, error)
 }
 
 // Activity represents a customer activity.
@@ -362,6 +400,71 @@ type SegmentRule struct {
 	Value    interface{} `json:"value" bson:"value"`
 }
 
+// PurchaseRepository defines the interface for purchase history persistence.
+type PurchaseRepository interface {
+	// Create records a purchase. It returns ErrPurchaseAlreadyRecorded if a
+	// purchase was already recorded for the opportunity.
+	Create(ctx context.Context, purchase *Purchase) error
+
+	// FindByCustomer finds a customer's purchases, most recent first.
+	FindByCustomer(ctx context.Context, customerID uuid.UUID, limit int) ([]*Purchase, error)
+
+	// TopCustomers totals the tenant's purchases in the currency made in
+	// [from, to) per customer, and returns the customers with the highest
+	// revenue first.
+	TopCustomers(ctx context.Context, tenantID uuid.UUID, currency Currency, from, to time.Time, limit int) ([]*CustomerRevenue, error)
+}
+
+// ErasureRepository defines the interface for erasure request persistence.
+type ErasureRepository interface {
+	// Create creates an erasure request.
+	Create(ctx context.Context, request *ErasureRequest) error
+
+	// Update updates an erasure request, returning ErrVersionConflict if it
+	// was updated concurrently.
+	Update(ctx context.Context, request *ErasureRequest) error
+
+	// FindByID finds an erasure request by ID.
+	FindByID(ctx context.Context, id uuid.UUID) (*ErasureRequest, error)
+}
+
+// ErasedIdentifierRepository defines the interface for the identifiers of
+// erased customers that are blocked from re-import.
+type ErasedIdentifierRepository interface {
+	// Block blocks identifiers until they expire. Blocking an identifier
+	// that is already blocked extends its block.
+	Block(ctx context.Context, identifiers []*ErasedIdentifier) error
+
+	// FindBlocked returns the hashes among the given ones that are blocked
+	// for the tenant at the time.
+	FindBlocked(ctx context.Context, tenantID uuid.UUID, hashes []string, now time.Time) ([]string, error)
+}
+
+// Tenant export data sets owned by the customer service.
+const (
+	TenantExportCustomers = "customers"
+	TenantExportContacts  = "contacts"
+)
+
+// TenantDataExporter defines the interface for exporting a tenant's customer
+// records for a tenant data export.
+type TenantDataExporter interface {
+	// ExportTenantData writes every live record of the data set to w as one
+	// JSON object per line, oldest first, and returns the number written.
+	ExportTenantData(ctx context.Context, tenantID uuid.UUID, dataset string, w io.Writer) (int64, error)
+}
+
+// DemoDataRepository defines the interface for managing a tenant's seeded
+// demo customers.
+type DemoDataRepository interface {
+	// FindExisting returns which of the customers exist, deleted or not.
+	FindExisting(ctx context.Context, tenantID uuid.UUID, ids []uuid.UUID) (map[uuid.UUID]bool, error)
+
+	// Purge permanently deletes the customers and returns how many were
+	// deleted.
+	Purge(ctx context.Context, tenantID uuid.UUID, ids []uuid.UUID) (int64, error)
+}
+
 // ImportRepository defines the interface for import operations.
 type ImportRepository interface {
 	// CreateImport creates a new import record.
@@ -383,6 +486,26 @@ type ImportRepository interface {
 	FindImportErrors(ctx context.Context, importID uuid.UUID) ([]*ImportError, error)
 }
 
+// ImportUploadRepository defines the interface for import upload
+// persistence.
+type ImportUploadRepository interface {
+	// Create creates an upload.
+	Create(ctx context.Context, upload *ImportUpload) error
+
+	// Update updates an upload, returning ErrVersionConflict if it was
+	// updated concurrently.
+	Update(ctx context.Context, upload *ImportUpload) error
+
+	// FindByID finds an upload by ID.
+	FindByID(ctx context.Context, id uuid.UUID) (*ImportUpload, error)
+
+	// FindExpired finds up to limit uploads that expired before the time.
+	FindExpired(ctx context.Context, before time.Time, limit int) ([]*ImportUpload, error)
+
+	// Delete deletes an upload.
+	Delete(ctx context.Context, id uuid.UUID) error
+}
+
 // ImportStatus represents the status of an import.
 type ImportStatus string
 
@@ -484,6 +607,11 @@ type UnitOfWork interface {
 	// Rollback rolls back the transaction.
 	Rollback(ctx context.Context) error
 
+	// WithTransaction runs fn in a transaction and commits it if fn returns
+	// nil. A transaction that fails with a transient error is run again, so
+	// fn must not have side effects outside the repositories.
+	WithTransaction(ctx context.Context, fn func(ctx context.Context) error) error
+
 	// Customers returns the customer repository.
 	Customers() CustomerRepository
 
@@ -504,6 +632,15 @@ type UnitOfWork interface {
 
 	// Imports returns the import repository.
 	Imports() ImportRepository
+
+	// Purchases returns the purchase repository.
+	Purchases() PurchaseRepository
+
+	// Erasures returns the erasure request repository.
+	Erasures() ErasureRepository
+
+	// ErasedIdentifiers returns the erased identifier repository.
+	ErasedIdentifiers() ErasedIdentifierRepository
 }
 
 // ContactActivity represents a contact activity.
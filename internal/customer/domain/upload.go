@@ -0,0 +1,148 @@
+package domain
+
+import (
+	"encoding/hex"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ============================================================================
+// Import Uploads
+// ============================================================================
+
+// UploadStatus represents the status of an import upload.
+type UploadStatus string
+
+const (
+	UploadStatusInProgress UploadStatus = "in_progress"
+	UploadStatusCompleted  UploadStatus = "completed"
+)
+
+// ImportUpload is an import file uploaded in chunks, so an upload broken off
+// by a flaky connection resumes from the last chunk received rather than
+// starting over. Once every byte has arrived and the file matches its
+// checksum, it is handed to the import.
+type ImportUpload struct {
+	ID          uuid.UUID    `json:"id" bson:"_id"`
+	TenantID    uuid.UUID    `json:"tenant_id" bson:"tenant_id"`
+	CreatedBy   uuid.UUID    `json:"created_by" bson:"created_by"`
+	FileName    string       `json:"file_name" bson:"file_name"`
+	Format      string       `json:"format" bson:"format"`
+	Size        int64        `json:"size" bson:"size"`
+	Offset      int64        `json:"offset" bson:"offset"`     // Bytes received
+	Checksum    string       `json:"checksum" bson:"checksum"` // Hex SHA-256 of the whole file
+	Status      UploadStatus `json:"status" bson:"status"`
+	ImportID    *uuid.UUID   `json:"import_id,omitempty" bson:"import_id,omitempty"`
+	ExpiresAt   time.Time    `json:"expires_at" bson:"expires_at"`
+	CreatedAt   time.Time    `json:"created_at" bson:"created_at"`
+	UpdatedAt   time.Time    `json:"updated_at" bson:"updated_at"`
+	CompletedAt *time.Time   `json:"completed_at,omitempty" bson:"completed_at,omitempty"`
+	Version     int          `json:"version" bson:"version"`
+}
+
+// NewImportUpload creates an upload of a file of size bytes with the hex
+// SHA-256 checksum, abandoned unless a chunk arrives before it expires.
+func NewImportUpload(tenantID, createdBy uuid.UUID, fileName, format string, size int64, checksum string, expiresAt time.Time) (*ImportUpload, error) {
+	fileName = strings.TrimSpace(fileName)
+	if fileName == "" {
+		return nil, NewValidationError("file_name", "file name is required", "REQUIRED")
+	}
+	if size <= 0 {
+		return nil, NewValidationError("size", "size must be positive", "INVALID_SIZE")
+	}
+	checksum = strings.ToLower(strings.TrimSpace(checksum))
+	if !IsSHA256Checksum(checksum) {
+		return nil, NewValidationError("checksum", "checksum must be a hex SHA-256 digest", "INVALID_CHECKSUM")
+	}
+
+	now := time.Now().UTC()
+	return &ImportUpload{
+		ID:        uuid.New(),
+		TenantID:  tenantID,
+		CreatedBy: createdBy,
+		FileName:  fileName,
+		Format:    format,
+		Size:      size,
+		Checksum:  checksum,
+		Status:    UploadStatusInProgress,
+		ExpiresAt: expiresAt.UTC(),
+		CreatedAt: now,
+		UpdatedAt: now,
+		Version:   1,
+	}, nil
+}
+
+// IsSHA256Checksum returns true if s is a lowercase hex SHA-256 digest.
+func IsSHA256Checksum(s string) bool {
+	if len(s) != 64 || strings.ToLower(s) != s {
+		return false
+	}
+	_, err := hex.DecodeString(s)
+	return err == nil
+}
+
+// IsExpired returns true if the upload has expired at the time: abandoned
+// before it was completed, or kept long enough after.
+func (u *ImportUpload) IsExpired(now time.Time) bool {
+	return !now.Before(u.ExpiresAt)
+}
+
+// IsComplete returns true if every byte of the file has been received.
+func (u *ImportUpload) IsComplete() bool {
+	return u.Offset == u.Size
+}
+
+// Append records a chunk of length bytes received at offset, and keeps the
+// upload from expiring until expiresAt. A chunk must continue where the last
+// one ended, so a client resuming an upload first asks for its offset.
+func (u *ImportUpload) Append(offset, length int64, now, expiresAt time.Time) error {
+	if err := u.checkOpen(now); err != nil {
+		return err
+	}
+	if offset != u.Offset {
+		return ErrUploadOffsetMismatch
+	}
+	if length <= 0 {
+		return NewValidationError("chunk", "chunk is empty", "EMPTY_CHUNK")
+	}
+	if offset+length > u.Size {
+		return ErrUploadExceedsSize
+	}
+
+	u.Offset += length
+	u.ExpiresAt = expiresAt.UTC()
+	u.UpdatedAt = now.UTC()
+	return nil
+}
+
+// Complete records that the file was handed to the import, and keeps the
+// upload until keepUntil so its client can find the import.
+func (u *ImportUpload) Complete(importID uuid.UUID, now, keepUntil time.Time) error {
+	if err := u.checkOpen(now); err != nil {
+		return err
+	}
+	if !u.IsComplete() {
+		return ErrUploadIncomplete
+	}
+
+	completedAt := now.UTC()
+	u.Status = UploadStatusCompleted
+	u.ImportID = &importID
+	u.CompletedAt = &completedAt
+	u.ExpiresAt = keepUntil.UTC()
+	u.UpdatedAt = completedAt
+	return nil
+}
+
+// checkOpen returns an error unless the upload still takes chunks.
+func (u *ImportUpload) checkOpen(now time.Time) error {
+	if u.Status == UploadStatusCompleted {
+		return ErrUploadCompleted
+	}
+	if u.IsExpired(now) {
+		return ErrUploadExpired
+	}
+	return nil
+}
@@ -0,0 +1,103 @@
+package domain
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ============================================================================
+// Import Upload Tests
+// ============================================================================
+
+var testUploadChecksum = strings.Repeat("ab", 32)
+
+func TestNewImportUpload(t *testing.T) {
+	expiresAt := time.Now().Add(time.Hour)
+
+	upload, err := NewImportUpload(uuid.New(), uuid.New(), " customers.xlsx ", "xlsx", 100, strings.ToUpper(testUploadChecksum), expiresAt)
+	if err != nil {
+		t.Fatalf("NewImportUpload() error = %v", err)
+	}
+	if upload.FileName != "customers.xlsx" || upload.Checksum != testUploadChecksum || upload.Status != UploadStatusInProgress {
+		t.Errorf("upload = %+v", upload)
+	}
+
+	for _, tc := range []struct {
+		name     string
+		fileName string
+		size     int64
+		checksum string
+	}{
+		{"no file name", " ", 100, testUploadChecksum},
+		{"empty file", "customers.xlsx", 0, testUploadChecksum},
+		{"short checksum", "customers.xlsx", 100, "abcd"},
+		{"not hex", "customers.xlsx", 100, strings.Repeat("zz", 32)},
+	} {
+		if _, err := NewImportUpload(uuid.New(), uuid.New(), tc.fileName, "xlsx", tc.size, tc.checksum, expiresAt); err == nil {
+			t.Errorf("%s: expected a validation error", tc.name)
+		}
+	}
+}
+
+func TestImportUpload_Append(t *testing.T) {
+	now := time.Now()
+	upload, err := NewImportUpload(uuid.New(), uuid.New(), "customers.csv", "csv", 10, testUploadChecksum, now.Add(time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := upload.Append(0, 6, now, now.Add(2*time.Hour)); err != nil {
+		t.Fatalf("Append() error = %v", err)
+	}
+	if upload.Offset != 6 || !upload.ExpiresAt.Equal(now.Add(2*time.Hour).UTC()) {
+		t.Errorf("offset %d, expires %v; want 6 and the expiry extended", upload.Offset, upload.ExpiresAt)
+	}
+
+	if err := upload.Append(0, 4, now, now.Add(time.Hour)); !errors.Is(err, ErrUploadOffsetMismatch) {
+		t.Errorf("resent chunk error = %v, want ErrUploadOffsetMismatch", err)
+	}
+	if err := upload.Append(6, 5, now, now.Add(time.Hour)); !errors.Is(err, ErrUploadExceedsSize) {
+		t.Errorf("oversized chunk error = %v, want ErrUploadExceedsSize", err)
+	}
+	if err := upload.Complete(uuid.New(), now, now.Add(time.Hour)); !errors.Is(err, ErrUploadIncomplete) {
+		t.Errorf("Complete() of a partial upload error = %v, want ErrUploadIncomplete", err)
+	}
+
+	if err := upload.Append(6, 4, now, now.Add(time.Hour)); err != nil {
+		t.Fatalf("Append() error = %v", err)
+	}
+	if !upload.IsComplete() {
+		t.Error("upload should be complete")
+	}
+
+	if err := upload.Append(10, 1, now.Add(3*time.Hour), now.Add(4*time.Hour)); !errors.Is(err, ErrUploadExpired) {
+		t.Errorf("chunk after expiry error = %v, want ErrUploadExpired", err)
+	}
+}
+
+func TestImportUpload_Complete(t *testing.T) {
+	now := time.Now()
+	upload, err := NewImportUpload(uuid.New(), uuid.New(), "customers.csv", "csv", 4, testUploadChecksum, now.Add(time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := upload.Append(0, 4, now, now.Add(time.Hour)); err != nil {
+		t.Fatal(err)
+	}
+
+	importID := uuid.New()
+	if err := upload.Complete(importID, now, now.Add(24*time.Hour)); err != nil {
+		t.Fatalf("Complete() error = %v", err)
+	}
+	if upload.Status != UploadStatusCompleted || upload.ImportID == nil || *upload.ImportID != importID || upload.CompletedAt == nil {
+		t.Errorf("upload = %+v, want completed with the import", upload)
+	}
+
+	if err := upload.Complete(uuid.New(), now, now.Add(time.Hour)); !errors.Is(err, ErrUploadCompleted) {
+		t.Errorf("second Complete() error = %v, want ErrUploadCompleted", err)
+	}
+}
@@ -8,6 +8,8 @@ import (
 	"regexp"
 	"strings"
 	"unicode"
+
+	"github.com/google/uuid"
 )
 
 // ============================================================================
@@ -366,11 +368,13 @@ const (
 	AddressTypeShipping AddressType = "shipping"
 	AddressTypeOffice   AddressType = "office"
 	AddressTypeHome     AddressType = "home"
+	AddressTypeOutlet   AddressType = "outlet"
 	AddressTypeOther    AddressType = "other"
 )
 
 // Address represents a physical address.
 type Address struct {
+	ID          uuid.UUID   `json:"id,omitempty" bson:"id,omitempty"`
 	Line1       string      `json:"line1" bson:"line1"`
 	Line2       string      `json:"line2,omitempty" bson:"line2,omitempty"`
 	Line3       string      `json:"line3,omitempty" bson:"line3,omitempty"`
@@ -449,6 +453,33 @@ func NewAddress(line1, city, postalCode, countryCode string, addressType Address
 	}, nil
 }
 
+// NormalizeRegion checks the address's state against its country and
+// returns the address with the state's canonical name. A Malaysian address
+// needs a known state whose postcodes include the address's postcode.
+func (a Address) NormalizeRegion() (Address, error) {
+	if a.CountryCode != "MY" {
+		return a, nil
+	}
+
+	var errs ValidationErrors
+	if strings.TrimSpace(a.State) == "" {
+		errs.AddField("state", "state is required for Malaysian addresses", "REQUIRED")
+		return a, errs
+	}
+	state, ok := FindMalaysianState(a.State)
+	if !ok {
+		errs.AddField("state", "unknown Malaysian state", "INVALID_STATE")
+		return a, errs
+	}
+	if !state.HasPostcode(a.PostalCode) {
+		errs.AddField("postal_code", "postcode is not in "+state.Name, "POSTCODE_STATE_MISMATCH")
+		return a, errs
+	}
+
+	a.State = state.Name
+	return a, nil
+}
+
 // isValidPostalCode validates postal code format based on country.
 func isValidPostalCode(postalCode, countryCode string) bool {
 	patterns := map[string]*regexp.Regexp{
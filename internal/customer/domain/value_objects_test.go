@@ -274,6 +274,48 @@ func TestAddress_Equals(t *testing.T) {
 	}
 }
 
+func TestAddress_NormalizeRegion(t *testing.T) {
+	tests := []struct {
+		name      string
+		state     string
+		postcode  string
+		wantState string
+		wantErr   bool
+	}{
+		{"canonical name", "Selangor", "40100", "Selangor", false},
+		{"alias", "penang", "10200", "Pulau Pinang", false},
+		{"state code", "JHR", "81300", "Johor", false},
+		{"federal territory", "WP Kuala Lumpur", "50450", "Kuala Lumpur", false},
+		{"shared Klang Valley postcode", "Selangor", "68000", "Selangor", false},
+		{"postcode in another state", "Kelantan", "50000", "", true},
+		{"unknown state", "Singapura", "50000", "", true},
+		{"missing state", "", "50000", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			addr, _ := NewAddress("1 Jalan Batik", "Somewhere", tt.postcode, "MY", AddressTypeOutlet)
+			addr = addr.WithDetails("", "", tt.state)
+
+			got, err := addr.NormalizeRegion()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("NormalizeRegion() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && got.State != tt.wantState {
+				t.Errorf("State = %q, want %q", got.State, tt.wantState)
+			}
+		})
+	}
+}
+
+func TestAddress_NormalizeRegion_OtherCountry(t *testing.T) {
+	addr, _ := NewAddress("1 Orchard Rd", "Singapore", "238801", "SG", AddressTypeOffice)
+
+	if _, err := addr.NormalizeRegion(); err != nil {
+		t.Errorf("NormalizeRegion() error = %v, want none outside Malaysia", err)
+	}
+}
+
 // ============================================================================
 // SocialProfile Tests
 // ============================================================================
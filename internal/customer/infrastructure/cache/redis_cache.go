@@ -11,6 +11,7 @@ import (
 	"github.com/google/uuid"
 
 	"github.com/kilang-desa-murni/crm/internal/customer/application/ports"
+	pkgcache "github.com/kilang-desa-murni/crm/pkg/cache"
 )
 
 // RedisCacheConfig holds Redis cache configuration.
@@ -43,6 +44,7 @@ func DefaultRedisCacheConfig() RedisCacheConfig {
 type RedisCache struct {
 	client     *redis.Client
 	config     RedisCacheConfig
+	loads      pkgcache.Group[[]byte]
 }
 
 // NewRedisCache creates a new Redis cache service.
@@ -108,6 +110,8 @@ func (c *RedisCache) Set(ctx context.Context, key string, value []byte, ttl time
 // Delete removes a value from cache.
 func (c *RedisCache) Delete(ctx context.Context, key string) error {
 	fullKey := c.buildKey(key)
+	// Readers after the delete must not join a load that started before it
+	c.loads.Forget(key)
 
 	if err := c.client.Del(ctx, fullKey).Err(); err != nil {
 		return fmt.Errorf("failed to delete from cache: %w", err)
@@ -150,7 +154,8 @@ func (c *RedisCache) Exists(ctx context.Context, key string) (bool, error) {
 	return result > 0, nil
 }
 
-// GetOrSet gets a value or sets it if not present.
+// GetOrSet gets a value or sets it if not present. Concurrent misses for the
+// same key share a single call to getter.
 func (c *RedisCache) GetOrSet(ctx context.Context, key string, ttl time.Duration, getter func() ([]byte, error)) ([]byte, error) {
 	// Try to get from cache
 	data, err := c.Get(ctx, key)
@@ -162,16 +167,19 @@ func (c *RedisCache) GetOrSet(ctx context.Context, key string, ttl time.Duration
 		return nil, err
 	}
 
-	// Fetch from source
-	data, err = getter()
-	if err != nil {
-		return nil, err
-	}
+	data, _, err = c.loads.Do(ctx, key, func(ctx context.Context) ([]byte, error) {
+		// Fetch from source
+		data, err := getter()
+		if err != nil {
+			return nil, err
+		}
 
-	// Store in cache (ignore errors - cache is optional)
-	_ = c.Set(ctx, key, data, ttl)
+		// Store in cache (ignore errors - cache is optional)
+		_ = c.Set(ctx, key, data, ttl)
 
-	return data, nil
+		return data, nil
+	})
+	return data, err
 }
 
 // Invalidate invalidates cache for a specific entity.
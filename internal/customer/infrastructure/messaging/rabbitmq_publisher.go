@@ -180,6 +180,7 @@ func (p *RabbitMQPublisher) Publish(ctx context.Context, event domain.DomainEven
 		Headers: amqp.Table{
 			"event_type":   event.EventType(),
 			"aggregate_id": event.AggregateID().String(),
+			"tenant_id":    event.TenantID().String(),
 			"occurred_at":  event.OccurredAt().Format(time.RFC3339),
 		},
 	}
@@ -0,0 +1,536 @@
+package messaging
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	amqp "github.com/rabbitmq/amqp091-go"
+	"go.uber.org/zap"
+
+	"github.com/kilang-desa-murni/crm/internal/customer/application/usecase"
+	"github.com/kilang-desa-murni/crm/internal/customer/domain"
+	"github.com/kilang-desa-murni/crm/pkg/lifecycle"
+)
+
+const (
+	// SalesEventsExchange is the sales service's event exchange.
+	SalesEventsExchange = "sales.events"
+
+	// NotificationEventsExchange is the notification service's event
+	// exchange.
+	NotificationEventsExchange = "notification.events"
+
+	// CustomerLifecycleQueue receives the sales events that move customers
+	// through their lifecycle stages, record their purchases and feed their
+	// health scores, the notification service's engagement events, and the
+	// services' confirmations of customer data erasures.
+	CustomerLifecycleQueue = "customer.lifecycle"
+
+	// opportunityWonRoutingKey is the routing key of the sales service's
+	// opportunity.won events.
+	opportunityWonRoutingKey = "sales.opportunity.won"
+
+	// Routing keys of the sales service's invoice events, which record the
+	// customers' overdue invoices.
+	invoiceOverdueRoutingKey  = "sales.deal.invoice_overdue"
+	paymentReceivedRoutingKey = "sales.deal.payment_received"
+
+	// Routing keys of the notification service's events for customers
+	// opening or clicking the notifications sent to them.
+	notificationOpenedRoutingKey  = "notification.opened"
+	notificationClickedRoutingKey = "notification.clicked"
+
+	// Routing keys of the services' confirmations that they erased a
+	// customer's data.
+	salesCustomerErasedRoutingKey        = "sales.customer.erased"
+	notificationCustomerErasedRoutingKey = "notification.customer.erased"
+)
+
+// ============================================================================
+// Sales Event Consumer
+// ============================================================================
+
+// WonDealRecorder records deals won with customers.
+type WonDealRecorder interface {
+	RecordWonDeal(ctx context.Context, tenantID, customerID uuid.UUID, wonAt time.Time) error
+}
+
+// PurchaseRecorder records won deals as purchases by customers.
+type PurchaseRecorder interface {
+	RecordPurchase(ctx context.Context, input usecase.RecordPurchaseInput) error
+}
+
+// ErasureConfirmer records the services' confirmations of customer data
+// erasures.
+type ErasureConfirmer interface {
+	ConfirmService(ctx context.Context, input usecase.ConfirmErasureInput) error
+}
+
+// HealthRecorder records the inputs of customers' health scores reported by
+// the other services.
+type HealthRecorder interface {
+	RecordOverdueInvoice(ctx context.Context, input usecase.RecordOverdueInvoiceInput) error
+	RecordInvoicePayment(ctx context.Context, input usecase.RecordInvoicePaymentInput) error
+	RecordNotificationEngagement(ctx context.Context, tenantID, customerID uuid.UUID, clicked bool, engagedAt time.Time) error
+}
+
+// SalesEventConsumerConfig holds configuration for the sales event consumer.
+type SalesEventConsumerConfig struct {
+	URL string
+	// Queue is shared by every instance of the service, so each event is
+	// handled once per service rather than once per instance.
+	Queue          string
+	PrefetchCount  int
+	ReconnectDelay time.Duration
+}
+
+// DefaultSalesEventConsumerConfig returns the default consumer configuration.
+func DefaultSalesEventConsumerConfig() SalesEventConsumerConfig {
+	return SalesEventConsumerConfig{
+		Queue:          CustomerLifecycleQueue,
+		PrefetchCount:  10,
+		ReconnectDelay: 5 * time.Second,
+	}
+}
+
+// SalesEventConsumer consumes the sales service's opportunity.won events. It
+// records the won deals on the customers, which makes them active, and as
+// purchases in the customers' purchase history. It records the customers'
+// overdue invoices and notification engagement for their health scores, and
+// the sales and notification services' confirmations of customer data
+// erasures.
+type SalesEventConsumer struct {
+	config    SalesEventConsumerConfig
+	recorder  WonDealRecorder
+	purchases PurchaseRecorder
+	erasures  ErasureConfirmer
+	health    HealthRecorder
+	logger    *zap.Logger
+	conn      *amqp.Connection
+	channel   *amqp.Channel
+	mu        sync.Mutex
+	closed    bool
+
+	// tag identifies the consumer to the broker so Shutdown can cancel it.
+	tag     string
+	drainer lifecycle.Drainer
+}
+
+// NewSalesEventConsumer creates a new sales event consumer and declares its
+// queue.
+func NewSalesEventConsumer(config SalesEventConsumerConfig, recorder WonDealRecorder, purchases PurchaseRecorder, erasures ErasureConfirmer, health HealthRecorder, logger *zap.Logger) (*SalesEventConsumer, error) {
+	defaults := DefaultSalesEventConsumerConfig()
+	if config.Queue == "" {
+		config.Queue = defaults.Queue
+	}
+	if config.PrefetchCount <= 0 {
+		config.PrefetchCount = defaults.PrefetchCount
+	}
+	if config.ReconnectDelay <= 0 {
+		config.ReconnectDelay = defaults.ReconnectDelay
+	}
+
+	consumer := &SalesEventConsumer{config: config, recorder: recorder, purchases: purchases, erasures: erasures, health: health, logger: logger, tag: config.Queue + "-" + uuid.NewString()}
+	if err := consumer.connect(); err != nil {
+		return nil, err
+	}
+	return consumer, nil
+}
+
+// connect opens a channel and declares the queue and its bindings.
+func (c *SalesEventConsumer) connect() error {
+	conn, err := amqp.Dial(c.config.URL)
+	if err != nil {
+		return fmt.Errorf("failed to connect to RabbitMQ: %w", err)
+	}
+
+	ch, err := conn.Channel()
+	if err != nil {
+		conn.Close()
+		return fmt.Errorf("failed to open channel: %w", err)
+	}
+
+	if err := c.declare(ch); err != nil {
+		ch.Close()
+		conn.Close()
+		return err
+	}
+
+	c.mu.Lock()
+	c.conn = conn
+	c.channel = ch
+	c.mu.Unlock()
+	return nil
+}
+
+func (c *SalesEventConsumer) declare(ch *amqp.Channel) error {
+	if err := ch.Qos(c.config.PrefetchCount, 0, false); err != nil {
+		return fmt.Errorf("failed to set QoS: %w", err)
+	}
+
+	// The exchanges are declared with the publishers' settings so binding
+	// works before the other services have started
+	for _, exchange := range []string{SalesEventsExchange, NotificationEventsExchange} {
+		if err := ch.ExchangeDeclare(
+			exchange,
+			"topic",
+			true,  // durable
+			false, // auto-delete
+			false, // internal
+			false, // no-wait
+			nil,
+		); err != nil {
+			return fmt.Errorf("failed to declare exchange %s: %w", exchange, err)
+		}
+	}
+
+	if _, err := ch.QueueDeclare(
+		c.config.Queue,
+		true,  // durable
+		false, // auto-delete
+		false, // exclusive
+		false, // no-wait
+		nil,
+	); err != nil {
+		return fmt.Errorf("failed to declare queue %s: %w", c.config.Queue, err)
+	}
+
+	bindings := []struct{ exchange, key string }{
+		{SalesEventsExchange, opportunityWonRoutingKey},
+		{SalesEventsExchange, salesCustomerErasedRoutingKey},
+		{SalesEventsExchange, invoiceOverdueRoutingKey},
+		{SalesEventsExchange, paymentReceivedRoutingKey},
+		{NotificationEventsExchange, notificationCustomerErasedRoutingKey},
+		{NotificationEventsExchange, notificationOpenedRoutingKey},
+		{NotificationEventsExchange, notificationClickedRoutingKey},
+	}
+	for _, binding := range bindings {
+		if err := ch.QueueBind(
+			c.config.Queue,
+			binding.key,
+			binding.exchange,
+			false, // no-wait
+			nil,
+		); err != nil {
+			return fmt.Errorf("failed to bind queue %s: %w", c.config.Queue, err)
+		}
+	}
+	return nil
+}
+
+// Start consumes events until ctx is cancelled or the consumer is closed,
+// reconnecting if the connection is lost.
+func (c *SalesEventConsumer) Start(ctx context.Context) error {
+	deliveries, err := c.deliveries()
+	if err != nil {
+		return err
+	}
+
+	go func() {
+		for {
+			c.handle(ctx, deliveries)
+
+			// The delivery channel closed: stop, or reconnect after a delay
+			for {
+				c.mu.Lock()
+				closed := c.closed
+				c.mu.Unlock()
+				if closed || c.drainer.Stopped() {
+					return
+				}
+
+				select {
+				case <-ctx.Done():
+					return
+				case <-time.After(c.config.ReconnectDelay):
+				}
+
+				if err := c.connect(); err != nil {
+					c.logger.Warn("Failed to reconnect sales event consumer", zap.Error(err))
+					continue
+				}
+				if deliveries, err = c.deliveries(); err == nil {
+					break
+				}
+			}
+		}
+	}()
+	return nil
+}
+
+func (c *SalesEventConsumer) deliveries() (<-chan amqp.Delivery, error) {
+	c.mu.Lock()
+	ch := c.channel
+	c.mu.Unlock()
+
+	deliveries, err := ch.Consume(
+		c.config.Queue,
+		c.tag, // consumer
+		false, // auto-ack
+		false, // exclusive
+		false, // no-local
+		false, // no-wait
+		nil,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to start consuming: %w", err)
+	}
+	return deliveries, nil
+}
+
+// handle processes deliveries until the channel closes or ctx is cancelled. A
+// failed event is requeued once and dropped if it fails again. Recording a won
+// deal twice is harmless, purchases are recorded once per opportunity,
+// overdue invoices once per invoice and erasures once per service, so
+// redelivered events need no deduplication. A redelivered engagement event
+// may count an open or click twice, which barely moves a health score.
+func (c *SalesEventConsumer) handle(ctx context.Context, deliveries <-chan amqp.Delivery) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case d, ok := <-deliveries:
+			if !ok {
+				return
+			}
+			// Deliveries prefetched after shutdown began go back to the queue
+			if !c.drainer.Begin() {
+				d.Nack(false, true)
+				continue
+			}
+
+			var err error
+			switch d.RoutingKey {
+			case salesCustomerErasedRoutingKey, notificationCustomerErasedRoutingKey:
+				err = c.HandleErasureConfirmed(ctx, d.Body)
+			case invoiceOverdueRoutingKey:
+				err = c.HandleInvoiceOverdue(ctx, d.Body)
+			case paymentReceivedRoutingKey:
+				err = c.HandlePaymentReceived(ctx, d.Body)
+			case notificationOpenedRoutingKey, notificationClickedRoutingKey:
+				err = c.HandleNotificationEngagement(ctx, d.RoutingKey == notificationClickedRoutingKey, d.Body)
+			default:
+				err = c.HandleOpportunityWon(ctx, d.Body)
+			}
+			if err != nil {
+				c.logger.Warn("Failed to handle sales event",
+					zap.String("routing_key", d.RoutingKey),
+					zap.String("message_id", d.MessageId),
+					zap.Error(err),
+				)
+				d.Nack(false, !d.Redelivered)
+			} else {
+				d.Ack(false)
+			}
+			c.drainer.End()
+		}
+	}
+}
+
+// opportunityWonEvent is the part of the sales service's opportunity.won
+// event the customer service uses.
+type opportunityWonEvent struct {
+	OpportunityID   uuid.UUID `json:"aggregate_id"`
+	TenantID        uuid.UUID `json:"tenant_id"`
+	CustomerID      uuid.UUID `json:"customer_id"`
+	OpportunityCode string    `json:"opportunity_code"`
+	Amount          int64     `json:"amount"`
+	Currency        string    `json:"currency"`
+	OccurredAt      time.Time `json:"occurred_at"`
+}
+
+// HandleOpportunityWon records the won deal of an opportunity.won event on the
+// opportunity's customer, and the deal's amount as a purchase by the
+// customer. Events without a customer are ignored.
+func (c *SalesEventConsumer) HandleOpportunityWon(ctx context.Context, body []byte) error {
+	var event opportunityWonEvent
+	if err := json.Unmarshal(body, &event); err != nil {
+		return fmt.Errorf("invalid opportunity won event: %w", err)
+	}
+	if event.TenantID == uuid.Nil || event.CustomerID == uuid.Nil {
+		return nil
+	}
+	if event.OccurredAt.IsZero() {
+		event.OccurredAt = time.Now().UTC()
+	}
+	if err := c.recorder.RecordWonDeal(ctx, event.TenantID, event.CustomerID, event.OccurredAt); err != nil {
+		return err
+	}
+
+	if c.purchases == nil || event.OpportunityID == uuid.Nil {
+		return nil
+	}
+	return c.purchases.RecordPurchase(ctx, usecase.RecordPurchaseInput{
+		TenantID:      event.TenantID,
+		CustomerID:    event.CustomerID,
+		OpportunityID: event.OpportunityID,
+		Reference:     event.OpportunityCode,
+		Amount:        domain.Money{Amount: event.Amount, Currency: domain.Currency(event.Currency)},
+		PurchasedAt:   event.OccurredAt,
+	})
+}
+
+// invoiceOverdueEvent is the part of the sales service's deal.invoice_overdue
+// event the customer service uses.
+type invoiceOverdueEvent struct {
+	DealID            uuid.UUID `json:"aggregate_id"`
+	TenantID          uuid.UUID `json:"tenant_id"`
+	CustomerID        uuid.UUID `json:"customer_id"`
+	InvoiceID         uuid.UUID `json:"invoice_id"`
+	InvoiceNumber     string    `json:"invoice_number"`
+	OutstandingAmount int64     `json:"outstanding_amount"`
+	Currency          string    `json:"currency"`
+	DueDate           time.Time `json:"due_date"`
+}
+
+// HandleInvoiceOverdue records an overdue invoice on the deal's customer.
+// Events without a customer or invoice are ignored.
+func (c *SalesEventConsumer) HandleInvoiceOverdue(ctx context.Context, body []byte) error {
+	var event invoiceOverdueEvent
+	if err := json.Unmarshal(body, &event); err != nil {
+		return fmt.Errorf("invalid invoice overdue event: %w", err)
+	}
+	if c.health == nil || event.TenantID == uuid.Nil || event.CustomerID == uuid.Nil || event.InvoiceID == uuid.Nil {
+		return nil
+	}
+	return c.health.RecordOverdueInvoice(ctx, usecase.RecordOverdueInvoiceInput{
+		TenantID:   event.TenantID,
+		CustomerID: event.CustomerID,
+		Invoice: domain.OverdueInvoice{
+			InvoiceID:   event.InvoiceID,
+			DealID:      event.DealID,
+			Number:      event.InvoiceNumber,
+			Outstanding: domain.Money{Amount: event.OutstandingAmount, Currency: domain.Currency(event.Currency)},
+			DueDate:     event.DueDate,
+		},
+	})
+}
+
+// paymentReceivedEvent is the part of the sales service's
+// deal.payment_received event the customer service uses.
+type paymentReceivedEvent struct {
+	DealID            uuid.UUID  `json:"aggregate_id"`
+	TenantID          uuid.UUID  `json:"tenant_id"`
+	CustomerID        uuid.UUID  `json:"customer_id"`
+	InvoiceID         *uuid.UUID `json:"invoice_id"`
+	Amount            int64      `json:"amount"`
+	OutstandingAmount int64      `json:"outstanding_amount"`
+}
+
+// HandlePaymentReceived records a payment against the overdue invoices of the
+// deal's customer. Events without a customer are ignored.
+func (c *SalesEventConsumer) HandlePaymentReceived(ctx context.Context, body []byte) error {
+	var event paymentReceivedEvent
+	if err := json.Unmarshal(body, &event); err != nil {
+		return fmt.Errorf("invalid payment received event: %w", err)
+	}
+	if c.health == nil || event.TenantID == uuid.Nil || event.CustomerID == uuid.Nil {
+		return nil
+	}
+	return c.health.RecordInvoicePayment(ctx, usecase.RecordInvoicePaymentInput{
+		TenantID:        event.TenantID,
+		CustomerID:      event.CustomerID,
+		DealID:          event.DealID,
+		InvoiceID:       event.InvoiceID,
+		Amount:          event.Amount,
+		DealOutstanding: event.OutstandingAmount,
+	})
+}
+
+// notificationEngagementEvent is the part of the notification service's
+// notification.opened and notification.clicked events the customer service
+// uses.
+type notificationEngagementEvent struct {
+	TenantID         uuid.UUID  `json:"tenant_id"`
+	SourceEntityID   *uuid.UUID `json:"source_entity_id"`
+	SourceEntityType string     `json:"source_entity_type"`
+	OccurredAt       time.Time  `json:"occurred_at"`
+}
+
+// HandleNotificationEngagement records a customer opening or clicking a
+// notification. Only notifications sent about a customer are attributed to
+// it; others are ignored.
+func (c *SalesEventConsumer) HandleNotificationEngagement(ctx context.Context, clicked bool, body []byte) error {
+	var event notificationEngagementEvent
+	if err := json.Unmarshal(body, &event); err != nil {
+		return fmt.Errorf("invalid notification engagement event: %w", err)
+	}
+	if c.health == nil || event.TenantID == uuid.Nil || event.SourceEntityID == nil || event.SourceEntityType != domain.AggregateTypeCustomer {
+		return nil
+	}
+	if event.OccurredAt.IsZero() {
+		event.OccurredAt = time.Now().UTC()
+	}
+	return c.health.RecordNotificationEngagement(ctx, event.TenantID, *event.SourceEntityID, clicked, event.OccurredAt)
+}
+
+// erasureConfirmedEvent is a service's confirmation that it erased its copy
+// of a customer's data.
+type erasureConfirmedEvent struct {
+	ErasureID  uuid.UUID `json:"erasure_id"`
+	TenantID   uuid.UUID `json:"tenant_id"`
+	Service    string    `json:"service"`
+	Records    int64     `json:"records"`
+	OccurredAt time.Time `json:"occurred_at"`
+}
+
+// HandleErasureConfirmed records a service's confirmation of a customer data
+// erasure. Confirmations without an erasure are ignored.
+func (c *SalesEventConsumer) HandleErasureConfirmed(ctx context.Context, body []byte) error {
+	var event erasureConfirmedEvent
+	if err := json.Unmarshal(body, &event); err != nil {
+		return fmt.Errorf("invalid erasure confirmation: %w", err)
+	}
+	if c.erasures == nil || event.ErasureID == uuid.Nil || event.TenantID == uuid.Nil {
+		return nil
+	}
+	return c.erasures.ConfirmService(ctx, usecase.ConfirmErasureInput{
+		TenantID:  event.TenantID,
+		ErasureID: event.ErasureID,
+		Service:   event.Service,
+		Records:   event.Records,
+		ErasedAt:  event.OccurredAt,
+	})
+}
+
+// Shutdown stops consuming, requeues the deliveries not yet handled and
+// waits for the event being handled before closing the connection.
+func (c *SalesEventConsumer) Shutdown(ctx context.Context) error {
+	c.drainer.Stop()
+
+	c.mu.Lock()
+	ch := c.channel
+	c.mu.Unlock()
+	if ch != nil {
+		ch.Cancel(c.tag, false)
+	}
+
+	err := c.drainer.Wait(ctx)
+	if closeErr := c.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
+
+// Close closes the consumer connection.
+func (c *SalesEventConsumer) Close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.closed {
+		return nil
+	}
+	c.closed = true
+
+	if c.channel != nil {
+		c.channel.Close()
+	}
+	if c.conn != nil {
+		return c.conn.Close()
+	}
+	return nil
+}
@@ -225,6 +225,29 @@ func (r *ActivityRepository) DeleteByCustomer(ctx context.Context, customerID uu
 	return nil
 }
 
+// AnonymizeByCustomer clears the free text and metadata of all activities
+// for a customer, keeping their type and time for the customer's statistics.
+func (r *ActivityRepository) AnonymizeByCustomer(ctx context.Context, customerID uuid.UUID) (int64, error) {
+	update := bson.M{
+		"$set": bson.M{
+			"subject":    "",
+			"updated_at": time.Now().UTC(),
+		},
+		"$unset": bson.M{
+			"description": "",
+			"outcome":     "",
+			"metadata":    "",
+		},
+		"$inc": bson.M{"version": 1},
+	}
+
+	result, err := r.collection.UpdateMany(ctx, bson.M{"customer_id": customerID}, update)
+	if err != nil {
+		return 0, fmt.Errorf("failed to anonymize activities by customer: %w", err)
+	}
+	return result.ModifiedCount, nil
+}
+
 // DeleteByContact deletes all activities for a contact.
 func (r *ActivityRepository) DeleteByContact(ctx context.Context, contactID uuid.UUID) error {
 	_, err := r.collection.DeleteMany(ctx, bson.M{"contact_id": contactID})
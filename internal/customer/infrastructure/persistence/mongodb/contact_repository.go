@@ -538,6 +538,16 @@ func (r *ContactRepository) DeleteByCustomer(ctx context.Context, customerID uui
 	return nil
 }
 
+// PurgeByCustomer permanently deletes all contacts for a customer,
+// including soft deleted ones.
+func (r *ContactRepository) PurgeByCustomer(ctx context.Context, customerID uuid.UUID) (int64, error) {
+	result, err := r.collection.DeleteMany(ctx, bson.M{"customer_id": customerID})
+	if err != nil {
+		return 0, fmt.Errorf("failed to purge contacts by customer: %w", err)
+	}
+	return result.DeletedCount, nil
+}
+
 // FindNeedingFollowUp finds contacts needing follow-up.
 func (r *ContactRepository) FindNeedingFollowUp(ctx context.Context, tenantID uuid.UUID, before time.Time) ([]*domain.Contact, error) {
 	filter := bson.M{
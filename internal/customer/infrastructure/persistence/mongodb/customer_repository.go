@@ -4,6 +4,7 @@ package mongodb
 import (
 	"context"
 	"fmt"
+	"regexp"
 	"strings"
 	"time"
 
@@ -128,6 +129,7 @@ func (r *CustomerRepository) FindByID(ctx context.Context, id uuid.UUID) (*domai
 		}
 		return nil, fmt.Errorf("failed to find customer: %w", err)
 	}
+	customer.EnsureAddressIDs()
 
 	return &customer, nil
 }
@@ -175,6 +177,9 @@ func (r *CustomerRepository) FindByEmail(ctx context.Context, tenantID uuid.UUID
 // List lists customers with filtering and pagination.
 func (r *CustomerRepository) List(ctx context.Context, filter domain.CustomerFilter) (*domain.CustomerList, error) {
 	mongoFilter := r.buildFilter(filter)
+	if err := addExprConditions(mongoFilter, filter); err != nil {
+		return nil, err
+	}
 
 	// Count total
 	total, err := r.collection.CountDocuments(ctx, mongoFilter)
@@ -187,7 +192,7 @@ func (r *CustomerRepository) List(ctx context.Context, filter domain.CustomerFil
 		SetSkip(int64(filter.Offset)).
 		SetLimit(int64(filter.Limit))
 
-	// Sorting
+	// Sorting, with the unique code as a tie-breaker
 	sortField := "created_at"
 	if filter.SortBy != "" {
 		sortField = filter.SortBy
@@ -196,7 +201,21 @@ func (r *CustomerRepository) List(ctx context.Context, filter domain.CustomerFil
 	if filter.SortOrder == "asc" {
 		sortOrder = 1
 	}
-	findOpts.SetSort(bson.D{{Key: sortField, Value: sortOrder}})
+	sort := bson.D{{Key: sortField, Value: sortOrder}}
+	if sortField != "code" {
+		sort = append(sort, bson.E{Key: "code", Value: sortOrder})
+	}
+	findOpts.SetSort(sort)
+
+	// Keyset pagination continues after the cursor instead of skipping
+	if filter.After != nil {
+		keyset, err := customerKeyset(sortField, sortOrder, filter.After)
+		if err != nil {
+			return nil, err
+		}
+		addConditions(mongoFilter, keyset)
+		findOpts.SetSkip(0)
+	}
 
 	// Execute query
 	cursor, err := r.collection.Find(ctx, mongoFilter, findOpts)
@@ -209,6 +228,9 @@ func (r *CustomerRepository) List(ctx context.Context, filter domain.CustomerFil
 	if err := cursor.All(ctx, &customers); err != nil {
 		return nil, fmt.Errorf("failed to decode customers: %w", err)
 	}
+	for _, customer := range customers {
+		customer.EnsureAddressIDs()
+	}
 
 	return &domain.CustomerList{
 		Customers: customers,
@@ -223,6 +245,9 @@ func (r *CustomerRepository) List(ctx context.Context, filter domain.CustomerFil
 func (r *CustomerRepository) Search(ctx context.Context, tenantID uuid.UUID, query string, filter domain.CustomerFilter) (*domain.CustomerList, error) {
 	mongoFilter := r.buildFilter(filter)
 	mongoFilter["tenant_id"] = tenantID
+	if err := addExprConditions(mongoFilter, filter); err != nil {
+		return nil, err
+	}
 
 	// Add text search
 	if query != "" {
@@ -315,7 +340,7 @@ func (r *CustomerRepository) FindDuplicates(ctx context.Context, tenantID uuid.U
 		orConditions = append(orConditions, bson.M{"phone_numbers.e164": phone})
 	}
 	if name != "" {
-		orConditions = append(orConditions, bson.M{"name": primitive.Regex{Pattern: "^" + name + "$", Options: "i"}})
+		orConditions = append(orConditions, bson.M{"name": primitive.Regex{Pattern: "^" + regexp.QuoteMeta(name) + "$", Options: "i"}})
 	}
 
 	if len(orConditions) == 0 {
@@ -637,6 +662,149 @@ func (r *CustomerRepository) FindRecentlyUpdated(ctx context.Context, tenantID u
 	return customers, nil
 }
 
+// FindLifecycleDue finds customers of every tenant whose lifecycle needs
+// evaluating. The last won deal, conversion or creation is compared in that
+// order, matching Customer.EvaluateLifecycle.
+func (r *CustomerRepository) FindLifecycleDue(ctx context.Context, activeBefore time.Time, limit int) ([]*domain.Customer, error) {
+	filter := bson.M{
+		"deleted_at": nil,
+		"$or": []bson.M{
+			{"lifecycle": bson.M{"$exists": false}},
+			{
+				"lifecycle":          domain.LifecycleActive,
+				"lifecycle_override": bson.M{"$ne": true},
+				"$or": []bson.M{
+					{"stats.last_won_deal_at": bson.M{"$lt": activeBefore}},
+					{"stats.last_won_deal_at": nil, "converted_at": bson.M{"$lt": activeBefore}},
+					{"stats.last_won_deal_at": nil, "converted_at": nil, "created_at": bson.M{"$lt": activeBefore}},
+				},
+			},
+		},
+	}
+
+	cursor, err := r.collection.Find(ctx, filter, options.Find().SetLimit(int64(limit)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to find customers due for lifecycle evaluation: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var customers []*domain.Customer
+	if err := cursor.All(ctx, &customers); err != nil {
+		return nil, fmt.Errorf("failed to decode customers: %w", err)
+	}
+
+	return customers, nil
+}
+
+// FindHealthDue finds customers of every tenant whose health score was last
+// calculated before the given time, those never calculated first.
+func (r *CustomerRepository) FindHealthDue(ctx context.Context, calculatedBefore time.Time, limit int) ([]*domain.Customer, error) {
+	filter := bson.M{
+		"deleted_at": nil,
+		"$or": []bson.M{
+			{"stats.last_calculated_at": nil},
+			{"stats.last_calculated_at": bson.M{"$lt": calculatedBefore}},
+		},
+	}
+
+	opts := options.Find().
+		SetSort(bson.D{{Key: "stats.last_calculated_at", Value: 1}}).
+		SetLimit(int64(limit))
+
+	cursor, err := r.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find customers due for health scoring: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var customers []*domain.Customer
+	if err := cursor.All(ctx, &customers); err != nil {
+		return nil, fmt.Errorf("failed to decode customers: %w", err)
+	}
+
+	return customers, nil
+}
+
+// customerFilterPaths maps the fields of a customer filter expression to
+// document paths.
+var customerFilterPaths = map[string]string{
+	"status":            "status",
+	"lifecycle":         "lifecycle",
+	"type":              "type",
+	"tier":              "tier",
+	"source":            "source",
+	"name":              "name",
+	"code":              "code",
+	"tags":              "tags",
+	"industry":          "company_info.industry",
+	"country":           "addresses.country_code",
+	"state":             "addresses.state",
+	"city":              "addresses.city",
+	"owner_id":          "owner_id",
+	"deal_count":        "stats.deal_count",
+	"engagement_score":  "stats.engagement_score",
+	"health_score":      "stats.health_score",
+	"health":            "stats.health_score",
+	"health_trend":      "stats.health_trend",
+	"last_contacted_at": "last_contacted_at",
+	"created_at":        "created_at",
+	"updated_at":        "updated_at",
+}
+
+// addExprConditions adds the conditions of the filter's expression to a
+// MongoDB filter.
+func addExprConditions(mongoFilter bson.M, filter domain.CustomerFilter) error {
+	conditions, err := filter.Expr.Mongo(customerFilterPaths)
+	if err != nil {
+		return fmt.Errorf("failed to build customer filter: %w", err)
+	}
+	addConditions(mongoFilter, conditions...)
+	return nil
+}
+
+// addConditions appends conditions to the $and clause of a MongoDB filter, so
+// that several can constrain the same field.
+func addConditions(mongoFilter bson.M, conditions ...bson.M) {
+	if len(conditions) == 0 {
+		return
+	}
+	and, _ := mongoFilter["$and"].([]bson.M)
+	mongoFilter["$and"] = append(and, conditions...)
+}
+
+// customerKeyset builds the condition selecting the customers after a keyset
+// cursor in the given sort. Cursor values are parsed back to the type stored
+// in the sort field so that MongoDB compares like with like.
+func customerKeyset(sortField string, sortOrder int, after *domain.CustomerCursor) (bson.M, error) {
+	op := "$lt"
+	if sortOrder > 0 {
+		op = "$gt"
+	}
+
+	if sortField == "code" {
+		return bson.M{"code": bson.M{op: after.Code}}, nil
+	}
+
+	var value interface{}
+	switch sortField {
+	case "created_at", "updated_at":
+		t, err := time.Parse(time.RFC3339Nano, after.SortValue)
+		if err != nil {
+			return nil, fmt.Errorf("invalid cursor value for %s: %w", sortField, err)
+		}
+		value = t
+	case "name":
+		value = after.SortValue
+	default:
+		return nil, fmt.Errorf("cursor pagination is not supported when sorting by %s", sortField)
+	}
+
+	return bson.M{"$or": []bson.M{
+		{sortField: bson.M{op: value}},
+		{sortField: value, "code": bson.M{op: after.Code}},
+	}}, nil
+}
+
 // buildFilter builds a MongoDB filter from CustomerFilter.
 func (r *CustomerRepository) buildFilter(filter domain.CustomerFilter) bson.M {
 	mongoFilter := bson.M{}
@@ -665,6 +833,10 @@ func (r *CustomerRepository) buildFilter(filter domain.CustomerFilter) bson.M {
 		mongoFilter["status"] = bson.M{"$in": filter.Statuses}
 	}
 
+	if len(filter.Lifecycles) > 0 {
+		mongoFilter["lifecycle"] = bson.M{"$in": filter.Lifecycles}
+	}
+
 	if len(filter.Tiers) > 0 {
 		mongoFilter["tier"] = bson.M{"$in": filter.Tiers}
 	}
@@ -693,6 +865,18 @@ func (r *CustomerRepository) buildFilter(filter domain.CustomerFilter) bson.M {
 		mongoFilter["addresses.country_code"] = bson.M{"$in": filter.Countries}
 	}
 
+	if len(filter.States) > 0 {
+		mongoFilter["addresses.state"] = bson.M{"$in": filter.States}
+	}
+
+	if len(filter.Cities) > 0 {
+		cities := make([]primitive.Regex, len(filter.Cities))
+		for i, city := range filter.Cities {
+			cities[i] = primitive.Regex{Pattern: "^" + regexp.QuoteMeta(city) + "$", Options: "i"}
+		}
+		mongoFilter["addresses.city"] = bson.M{"$in": cities}
+	}
+
 	if filter.Email != "" {
 		mongoFilter["email.address"] = strings.ToLower(filter.Email)
 	}
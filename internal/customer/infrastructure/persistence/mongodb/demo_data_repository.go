@@ -0,0 +1,70 @@
+// Package mongodb provides MongoDB implementations for customer repositories.
+package mongodb
+
+import (
+	"context"
+	"fmt"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/google/uuid"
+
+	"github.com/kilang-desa-murni/crm/internal/customer/domain"
+)
+
+// DemoDataRepository implements domain.DemoDataRepository using MongoDB.
+type DemoDataRepository struct {
+	db         *mongo.Database
+	collection *mongo.Collection
+}
+
+// NewDemoDataRepository creates a new DemoDataRepository.
+func NewDemoDataRepository(db *mongo.Database) *DemoDataRepository {
+	return &DemoDataRepository{
+		db:         db,
+		collection: db.Collection(customersCollection),
+	}
+}
+
+// Ensure DemoDataRepository implements domain.DemoDataRepository.
+var _ domain.DemoDataRepository = (*DemoDataRepository)(nil)
+
+// FindExisting returns which of the customers exist, including soft-deleted
+// ones as they still hold their ID.
+func (r *DemoDataRepository) FindExisting(ctx context.Context, tenantID uuid.UUID, ids []uuid.UUID) (map[uuid.UUID]bool, error) {
+	filter := bson.M{"tenant_id": tenantID, "_id": bson.M{"$in": ids}}
+	opts := options.Find().SetProjection(bson.M{"_id": 1})
+
+	cursor, err := r.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find demo customers: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	existing := make(map[uuid.UUID]bool)
+	for cursor.Next(ctx) {
+		var doc struct {
+			ID uuid.UUID `bson:"_id"`
+		}
+		if err := cursor.Decode(&doc); err != nil {
+			return nil, fmt.Errorf("failed to decode demo customer: %w", err)
+		}
+		existing[doc.ID] = true
+	}
+	if err := cursor.Err(); err != nil {
+		return nil, fmt.Errorf("failed to find demo customers: %w", err)
+	}
+
+	return existing, nil
+}
+
+// Purge permanently deletes the customers with their embedded contacts.
+func (r *DemoDataRepository) Purge(ctx context.Context, tenantID uuid.UUID, ids []uuid.UUID) (int64, error) {
+	result, err := r.collection.DeleteMany(ctx, bson.M{"tenant_id": tenantID, "_id": bson.M{"$in": ids}})
+	if err != nil {
+		return 0, fmt.Errorf("failed to purge demo customers: %w", err)
+	}
+	return result.DeletedCount, nil
+}
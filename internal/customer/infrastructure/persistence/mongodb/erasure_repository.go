@@ -0,0 +1,172 @@
+// Package mongodb provides MongoDB implementations for customer repositories.
+package mongodb
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/google/uuid"
+
+	"github.com/kilang-desa-murni/crm/internal/customer/domain"
+)
+
+const (
+	erasuresCollection          = "customer_erasures"
+	erasedIdentifiersCollection = "customer_erased_identifiers"
+)
+
+// ============================================================================
+// Erasure Repository
+// ============================================================================
+
+// ErasureRepository implements domain.ErasureRepository using MongoDB.
+type ErasureRepository struct {
+	db         *mongo.Database
+	collection *mongo.Collection
+}
+
+// NewErasureRepository creates a new ErasureRepository.
+func NewErasureRepository(db *mongo.Database) *ErasureRepository {
+	return &ErasureRepository{
+		db:         db,
+		collection: db.Collection(erasuresCollection),
+	}
+}
+
+// Create creates an erasure request.
+func (r *ErasureRepository) Create(ctx context.Context, request *domain.ErasureRequest) error {
+	if _, err := r.collection.InsertOne(ctx, request); err != nil {
+		return fmt.Errorf("failed to create erasure request: %w", err)
+	}
+	return nil
+}
+
+// Update updates an erasure request with optimistic locking.
+func (r *ErasureRepository) Update(ctx context.Context, request *domain.ErasureRequest) error {
+	previousVersion := request.Version
+	request.Version++
+
+	filter := bson.M{
+		"_id":     request.ID,
+		"version": previousVersion,
+	}
+
+	result, err := r.collection.ReplaceOne(ctx, filter, request)
+	if err != nil {
+		request.Version = previousVersion
+		return fmt.Errorf("failed to update erasure request: %w", err)
+	}
+
+	if result.MatchedCount == 0 {
+		request.Version = previousVersion
+		count, err := r.collection.CountDocuments(ctx, bson.M{"_id": request.ID})
+		if err == nil && count == 0 {
+			return domain.ErrErasureNotFound
+		}
+		return domain.ErrVersionConflict
+	}
+
+	return nil
+}
+
+// FindByID finds an erasure request by ID.
+func (r *ErasureRepository) FindByID(ctx context.Context, id uuid.UUID) (*domain.ErasureRequest, error) {
+	var request domain.ErasureRequest
+	err := r.collection.FindOne(ctx, bson.M{"_id": id}).Decode(&request)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, domain.ErrErasureNotFound
+		}
+		return nil, fmt.Errorf("failed to find erasure request: %w", err)
+	}
+	return &request, nil
+}
+
+// ============================================================================
+// Erased Identifier Repository
+// ============================================================================
+
+// ErasedIdentifierRepository implements domain.ErasedIdentifierRepository
+// using MongoDB. A TTL index removes identifiers once their block expires.
+type ErasedIdentifierRepository struct {
+	db         *mongo.Database
+	collection *mongo.Collection
+}
+
+// NewErasedIdentifierRepository creates a new ErasedIdentifierRepository.
+func NewErasedIdentifierRepository(db *mongo.Database) *ErasedIdentifierRepository {
+	return &ErasedIdentifierRepository{
+		db:         db,
+		collection: db.Collection(erasedIdentifiersCollection),
+	}
+}
+
+// Block blocks identifiers until they expire, replacing an existing block of
+// the same identifier.
+func (r *ErasedIdentifierRepository) Block(ctx context.Context, identifiers []*domain.ErasedIdentifier) error {
+	if len(identifiers) == 0 {
+		return nil
+	}
+
+	models := make([]mongo.WriteModel, 0, len(identifiers))
+	for _, identifier := range identifiers {
+		models = append(models, mongo.NewUpdateOneModel().
+			SetFilter(bson.M{"tenant_id": identifier.TenantID, "hash": identifier.Hash}).
+			SetUpdate(bson.M{
+				"$set": bson.M{
+					"erasure_id": identifier.ErasureID,
+					"expires_at": identifier.ExpiresAt,
+				},
+				"$setOnInsert": bson.M{
+					"_id":        identifier.ID,
+					"created_at": identifier.CreatedAt,
+				},
+			}).
+			SetUpsert(true))
+	}
+
+	if _, err := r.collection.BulkWrite(ctx, models, options.BulkWrite().SetOrdered(false)); err != nil {
+		return fmt.Errorf("failed to block erased identifiers: %w", err)
+	}
+	return nil
+}
+
+// FindBlocked returns the hashes among the given ones that are blocked for
+// the tenant. Expired blocks are filtered out, as the TTL index removes them
+// only periodically.
+func (r *ErasedIdentifierRepository) FindBlocked(ctx context.Context, tenantID uuid.UUID, hashes []string, now time.Time) ([]string, error) {
+	if len(hashes) == 0 {
+		return nil, nil
+	}
+
+	filter := bson.M{
+		"tenant_id":  tenantID,
+		"hash":       bson.M{"$in": hashes},
+		"expires_at": bson.M{"$gt": now},
+	}
+	opts := options.Find().SetProjection(bson.M{"hash": 1})
+
+	cursor, err := r.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find erased identifiers: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var rows []struct {
+		Hash string `bson:"hash"`
+	}
+	if err := cursor.All(ctx, &rows); err != nil {
+		return nil, fmt.Errorf("failed to decode erased identifiers: %w", err)
+	}
+
+	blocked := make([]string, 0, len(rows))
+	for _, row := range rows {
+		blocked = append(blocked, row.Hash)
+	}
+	return blocked, nil
+}
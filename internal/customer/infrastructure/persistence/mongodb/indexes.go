@@ -4,6 +4,7 @@ package mongodb
 import (
 	"context"
 	"fmt"
+	"sort"
 	"time"
 
 	"go.mongodb.org/mongo-driver/bson"
@@ -11,54 +12,107 @@ import (
 	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
-// IndexManager manages MongoDB indexes for the customer service.
-type IndexManager struct {
-	db *mongo.Database
+// IndexOptions configures how the customer service indexes are built.
+type IndexOptions struct {
+	// Background builds missing indexes without holding up startup, with
+	// the background option for servers older than 4.2.
+	Background bool
+	// SoftDeleteRetention is how long soft-deleted customers and contacts
+	// are kept before a TTL index removes them. Zero keeps them.
+	SoftDeleteRetention time.Duration
 }
 
-// NewIndexManager creates a new IndexManager.
-func NewIndexManager(db *mongo.Database) *IndexManager {
-	return &IndexManager{db: db}
+// CollectionIndexes are the indexes declared for one collection.
+type CollectionIndexes struct {
+	Collection string
+	Indexes    []mongo.IndexModel
 }
 
-// CreateAllIndexes creates all required indexes for the customer service.
-func (m *IndexManager) CreateAllIndexes(ctx context.Context) error {
-	if err := m.createCustomerIndexes(ctx); err != nil {
-		return fmt.Errorf("failed to create customer indexes: %w", err)
-	}
+// IndexDrift is how the indexes of a collection differ from the declared
+// ones. Changed indexes exist under a declared name with other keys or
+// options; they are not rebuilt and must be dropped to be recreated.
+type IndexDrift struct {
+	Collection string   `json:"collection"`
+	Missing    []string `json:"missing,omitempty"`
+	Unexpected []string `json:"unexpected,omitempty"`
+	Changed    []string `json:"changed,omitempty"`
+}
 
-	if err := m.createContactIndexes(ctx); err != nil {
-		return fmt.Errorf("failed to create contact indexes: %w", err)
-	}
+// IndexUsage is how often an index was used since it was created or the
+// server last started.
+type IndexUsage struct {
+	Collection string    `json:"collection"`
+	Name       string    `json:"name"`
+	Ops        int64     `json:"ops"`
+	Since      time.Time `json:"since"`
+	Host       string    `json:"host,omitempty"`
+}
 
-	if err := m.createNoteIndexes(ctx); err != nil {
-		return fmt.Errorf("failed to create note indexes: %w", err)
-	}
+// IndexManager manages MongoDB indexes for the customer service.
+type IndexManager struct {
+	db   *mongo.Database
+	opts IndexOptions
+}
 
-	if err := m.createActivityIndexes(ctx); err != nil {
-		return fmt.Errorf("failed to create activity indexes: %w", err)
-	}
+// NewIndexManager creates a new IndexManager.
+func NewIndexManager(db *mongo.Database, opts IndexOptions) *IndexManager {
+	return &IndexManager{db: db, opts: opts}
+}
 
-	if err := m.createSegmentIndexes(ctx); err != nil {
-		return fmt.Errorf("failed to create segment indexes: %w", err)
+// Definitions returns the indexes declared for each collection.
+func (m *IndexManager) Definitions() []CollectionIndexes {
+	retention := m.opts.SoftDeleteRetention
+	return []CollectionIndexes{
+		{Collection: customersCollection, Indexes: append(customerIndexes(), softDeleteIndexes("customers", retention)...)},
+		{Collection: contactsCollection, Indexes: append(contactIndexes(), softDeleteIndexes("contacts", retention)...)},
+		{Collection: notesCollection, Indexes: noteIndexes()},
+		{Collection: activitiesCollection, Indexes: activityIndexes()},
+		{Collection: segmentsCollection, Indexes: segmentIndexes()},
+		{Collection: importsCollection, Indexes: importIndexes()},
+		{Collection: importErrorsCollection, Indexes: importErrorIndexes()},
+		{Collection: importUploadsCollection, Indexes: importUploadIndexes()},
+		{Collection: outboxCollection, Indexes: outboxIndexes()},
+		{Collection: purchasesCollection, Indexes: purchaseIndexes()},
+		{Collection: erasuresCollection, Indexes: erasureIndexes()},
+		{Collection: erasedIdentifiersCollection, Indexes: erasedIdentifierIndexes()},
 	}
+}
 
-	if err := m.createImportIndexes(ctx); err != nil {
-		return fmt.Errorf("failed to create import indexes: %w", err)
-	}
+// softDeleteIndexes declares the indexes over the soft-deleted documents of
+// a collection. They are partial, so live documents take no space in them:
+// one lists a tenant's deleted documents and, with a retention, a TTL index
+// removes them once it has passed.
+func softDeleteIndexes(prefix string, retention time.Duration) []mongo.IndexModel {
+	deleted := bson.M{"deleted_at": bson.M{"$type": "date"}}
 
-	if err := m.createOutboxIndexes(ctx); err != nil {
-		return fmt.Errorf("failed to create outbox indexes: %w", err)
+	indexes := []mongo.IndexModel{
+		{
+			Keys: bson.D{
+				{Key: "tenant_id", Value: 1},
+				{Key: "deleted_at", Value: -1},
+			},
+			Options: options.Index().
+				SetName("idx_" + prefix + "_deleted").
+				SetPartialFilterExpression(deleted),
+		},
 	}
-
-	return nil
+	if retention > 0 {
+		indexes = append(indexes, mongo.IndexModel{
+			Keys: bson.D{
+				{Key: "deleted_at", Value: 1},
+			},
+			Options: options.Index().
+				SetName("idx_" + prefix + "_deleted_expiry").
+				SetPartialFilterExpression(deleted).
+				SetExpireAfterSeconds(int32(retention.Seconds())),
+		})
+	}
+	return indexes
 }
 
-// createCustomerIndexes creates indexes for the customers collection.
-func (m *IndexManager) createCustomerIndexes(ctx context.Context) error {
-	collection := m.db.Collection(customersCollection)
-
-	indexes := []mongo.IndexModel{
+// customerIndexes declares the indexes of the customers collection.
+func customerIndexes() []mongo.IndexModel {
+	return []mongo.IndexModel{
 		// Unique index on tenant_id + code
 		{
 			Keys: bson.D{
@@ -193,6 +247,55 @@ func (m *IndexManager) createCustomerIndexes(ctx context.Context) error {
 			},
 			Options: options.Index().SetName("idx_customers_country"),
 		},
+		// Indexes for state and city filtering
+		{
+			Keys: bson.D{
+				{Key: "tenant_id", Value: 1},
+				{Key: "addresses.state", Value: 1},
+				{Key: "deleted_at", Value: 1},
+			},
+			Options: options.Index().SetName("idx_customers_state"),
+		},
+		{
+			Keys: bson.D{
+				{Key: "tenant_id", Value: 1},
+				{Key: "addresses.city", Value: 1},
+				{Key: "deleted_at", Value: 1},
+			},
+			Options: options.Index().SetName("idx_customers_city"),
+		},
+		{
+			Keys: bson.D{
+				{Key: "tenant_id", Value: 1},
+				{Key: "lifecycle", Value: 1},
+				{Key: "deleted_at", Value: 1},
+			},
+			Options: options.Index().SetName("idx_customers_lifecycle"),
+		},
+		// Lifecycle evaluation scans active customers across tenants
+		{
+			Keys: bson.D{
+				{Key: "lifecycle", Value: 1},
+				{Key: "stats.last_won_deal_at", Value: 1},
+			},
+			Options: options.Index().SetName("idx_customers_lifecycle_due"),
+		},
+		// Health scoring scans customers across tenants by their last
+		// calculation, and lists filter on the score
+		{
+			Keys: bson.D{
+				{Key: "stats.last_calculated_at", Value: 1},
+			},
+			Options: options.Index().SetName("idx_customers_health_due"),
+		},
+		{
+			Keys: bson.D{
+				{Key: "tenant_id", Value: 1},
+				{Key: "stats.health_score", Value: 1},
+				{Key: "deleted_at", Value: 1},
+			},
+			Options: options.Index().SetName("idx_customers_health_score"),
+		},
 		// Text index for full-text search
 		{
 			Keys: bson.D{
@@ -206,12 +309,12 @@ func (m *IndexManager) createCustomerIndexes(ctx context.Context) error {
 			Options: options.Index().
 				SetName("idx_customers_text_search").
 				SetWeights(bson.M{
-					"name":                     10,
-					"email.address":            8,
-					"code":                     8,
+					"name":                      10,
+					"email.address":             8,
+					"code":                      8,
 					"company_info.company_name": 6,
-					"tags":                     4,
-					"notes":                    2,
+					"tags":                      4,
+					"notes":                     2,
 				}).
 				SetDefaultLanguage("english"),
 		},
@@ -232,16 +335,11 @@ func (m *IndexManager) createCustomerIndexes(ctx context.Context) error {
 			Options: options.Index().SetName("idx_customers_updated"),
 		},
 	}
-
-	_, err := collection.Indexes().CreateMany(ctx, indexes)
-	return err
 }
 
-// createContactIndexes creates indexes for the contacts collection.
-func (m *IndexManager) createContactIndexes(ctx context.Context) error {
-	collection := m.db.Collection(contactsCollection)
-
-	indexes := []mongo.IndexModel{
+// contactIndexes declares the indexes of the contacts collection.
+func contactIndexes() []mongo.IndexModel {
+	return []mongo.IndexModel{
 		// Index for customer_id lookup
 		{
 			Keys: bson.D{
@@ -346,16 +444,11 @@ func (m *IndexManager) createContactIndexes(ctx context.Context) error {
 				SetDefaultLanguage("english"),
 		},
 	}
-
-	_, err := collection.Indexes().CreateMany(ctx, indexes)
-	return err
 }
 
-// createNoteIndexes creates indexes for the notes collection.
-func (m *IndexManager) createNoteIndexes(ctx context.Context) error {
-	collection := m.db.Collection(notesCollection)
-
-	indexes := []mongo.IndexModel{
+// noteIndexes declares the indexes of the notes collection.
+func noteIndexes() []mongo.IndexModel {
+	return []mongo.IndexModel{
 		// Index for customer notes
 		{
 			Keys: bson.D{
@@ -391,16 +484,11 @@ func (m *IndexManager) createNoteIndexes(ctx context.Context) error {
 			Options: options.Index().SetName("idx_notes_creator"),
 		},
 	}
-
-	_, err := collection.Indexes().CreateMany(ctx, indexes)
-	return err
 }
 
-// createActivityIndexes creates indexes for the activities collection.
-func (m *IndexManager) createActivityIndexes(ctx context.Context) error {
-	collection := m.db.Collection(activitiesCollection)
-
-	indexes := []mongo.IndexModel{
+// activityIndexes declares the indexes of the activities collection.
+func activityIndexes() []mongo.IndexModel {
+	return []mongo.IndexModel{
 		// Index for customer activities
 		{
 			Keys: bson.D{
@@ -469,16 +557,11 @@ func (m *IndexManager) createActivityIndexes(ctx context.Context) error {
 			Options: options.Index().SetName("idx_activities_customer_type"),
 		},
 	}
-
-	_, err := collection.Indexes().CreateMany(ctx, indexes)
-	return err
 }
 
-// createSegmentIndexes creates indexes for the segments collection.
-func (m *IndexManager) createSegmentIndexes(ctx context.Context) error {
-	collection := m.db.Collection(segmentsCollection)
-
-	indexes := []mongo.IndexModel{
+// segmentIndexes declares the indexes of the segments collection.
+func segmentIndexes() []mongo.IndexModel {
+	return []mongo.IndexModel{
 		// Unique index on tenant_id + name
 		{
 			Keys: bson.D{
@@ -506,17 +589,11 @@ func (m *IndexManager) createSegmentIndexes(ctx context.Context) error {
 			Options: options.Index().SetName("idx_segments_type"),
 		},
 	}
-
-	_, err := collection.Indexes().CreateMany(ctx, indexes)
-	return err
 }
 
-// createImportIndexes creates indexes for the imports collection.
-func (m *IndexManager) createImportIndexes(ctx context.Context) error {
-	importCollection := m.db.Collection(importsCollection)
-	errorCollection := m.db.Collection(importErrorsCollection)
-
-	importIndexes := []mongo.IndexModel{
+// importIndexes declares the indexes of the imports collection.
+func importIndexes() []mongo.IndexModel {
+	return []mongo.IndexModel{
 		// Index for tenant imports
 		{
 			Keys: bson.D{
@@ -542,8 +619,11 @@ func (m *IndexManager) createImportIndexes(ctx context.Context) error {
 			Options: options.Index().SetName("idx_imports_cleanup"),
 		},
 	}
+}
 
-	errorIndexes := []mongo.IndexModel{
+// importErrorIndexes declares the indexes of the import errors collection.
+func importErrorIndexes() []mongo.IndexModel {
+	return []mongo.IndexModel{
 		// Index for import errors
 		{
 			Keys: bson.D{
@@ -553,20 +633,11 @@ func (m *IndexManager) createImportIndexes(ctx context.Context) error {
 			Options: options.Index().SetName("idx_import_errors_import"),
 		},
 	}
-
-	if _, err := importCollection.Indexes().CreateMany(ctx, importIndexes); err != nil {
-		return err
-	}
-
-	_, err := errorCollection.Indexes().CreateMany(ctx, errorIndexes)
-	return err
 }
 
-// createOutboxIndexes creates indexes for the outbox collection.
-func (m *IndexManager) createOutboxIndexes(ctx context.Context) error {
-	collection := m.db.Collection(outboxCollection)
-
-	indexes := []mongo.IndexModel{
+// outboxIndexes declares the indexes of the outbox collection.
+func outboxIndexes() []mongo.IndexModel {
+	return []mongo.IndexModel{
 		// Index for pending entries
 		{
 			Keys: bson.D{
@@ -617,115 +688,302 @@ func (m *IndexManager) createOutboxIndexes(ctx context.Context) error {
 			Options: options.Index().SetName("idx_outbox_locked"),
 		},
 	}
+}
 
-	_, err := collection.Indexes().CreateMany(ctx, indexes)
-	return err
+// purchaseIndexes declares the indexes of the purchases collection.
+func purchaseIndexes() []mongo.IndexModel {
+	return []mongo.IndexModel{
+		// Unique index so a won opportunity is recorded once
+		{
+			Keys: bson.D{
+				{Key: "tenant_id", Value: 1},
+				{Key: "opportunity_id", Value: 1},
+			},
+			Options: options.Index().
+				SetName("idx_purchases_tenant_opportunity_unique").
+				SetUnique(true),
+		},
+		// Index for customer purchase history
+		{
+			Keys: bson.D{
+				{Key: "customer_id", Value: 1},
+				{Key: "purchased_at", Value: -1},
+			},
+			Options: options.Index().SetName("idx_purchases_customer"),
+		},
+		// Index for top customers by revenue
+		{
+			Keys: bson.D{
+				{Key: "tenant_id", Value: 1},
+				{Key: "amount.currency", Value: 1},
+				{Key: "purchased_at", Value: -1},
+			},
+			Options: options.Index().SetName("idx_purchases_tenant_period"),
+		},
+	}
+}
+
+// importUploadIndexes declares the indexes of the import uploads
+// collection.
+func importUploadIndexes() []mongo.IndexModel {
+	return []mongo.IndexModel{
+		// Index for the expiry worker finding expired uploads
+		{
+			Keys: bson.D{
+				{Key: "expires_at", Value: 1},
+			},
+			Options: options.Index().SetName("idx_import_uploads_expiry"),
+		},
+	}
+}
+
+// erasureIndexes declares the indexes of the erasure requests collection.
+func erasureIndexes() []mongo.IndexModel {
+	return []mongo.IndexModel{
+		// Index for a customer's erasure requests
+		{
+			Keys: bson.D{
+				{Key: "tenant_id", Value: 1},
+				{Key: "customer_id", Value: 1},
+			},
+			Options: options.Index().SetName("idx_erasures_customer"),
+		},
+	}
+}
+
+// erasedIdentifierIndexes declares the indexes of the erased identifiers
+// collection.
+func erasedIdentifierIndexes() []mongo.IndexModel {
+	return []mongo.IndexModel{
+		// Unique index so an identifier has one block per tenant
+		{
+			Keys: bson.D{
+				{Key: "tenant_id", Value: 1},
+				{Key: "hash", Value: 1},
+			},
+			Options: options.Index().
+				SetName("idx_erased_identifiers_tenant_hash_unique").
+				SetUnique(true),
+		},
+		// TTL index that removes identifiers once their block expires
+		{
+			Keys: bson.D{
+				{Key: "expires_at", Value: 1},
+			},
+			Options: options.Index().
+				SetName("idx_erased_identifiers_expiry").
+				SetExpireAfterSeconds(0),
+		},
+	}
 }
 
 // DropAllIndexes drops all indexes (useful for testing).
 func (m *IndexManager) DropAllIndexes(ctx context.Context) error {
-	collections := []string{
-		customersCollection,
-		contactsCollection,
-		notesCollection,
-		activitiesCollection,
-		segmentsCollection,
-		importsCollection,
-		importErrorsCollection,
-		outboxCollection,
-	}
-
-	for _, collName := range collections {
-		coll := m.db.Collection(collName)
-		if _, err := coll.Indexes().DropAll(ctx); err != nil {
-			return fmt.Errorf("failed to drop indexes for %s: %w", collName, err)
+	for _, def := range m.Definitions() {
+		if _, err := m.db.Collection(def.Collection).Indexes().DropAll(ctx); err != nil {
+			return fmt.Errorf("failed to drop indexes for %s: %w", def.Collection, err)
 		}
 	}
 
 	return nil
 }
 
-// EnsureIndexes creates indexes if they don't exist (idempotent).
+// EnsureIndexes creates the declared indexes that do not exist yet. An
+// index that exists under a declared name is left as it is, even when its
+// definition has changed; CheckDrift reports those.
 func (m *IndexManager) EnsureIndexes(ctx context.Context) error {
-	return m.CreateAllIndexes(ctx)
+	for _, def := range m.Definitions() {
+		existing, err := m.listIndexes(ctx, def.Collection)
+		if err != nil {
+			return err
+		}
+
+		var missing []mongo.IndexModel
+		for _, index := range def.Indexes {
+			if _, ok := existing[indexName(index)]; ok {
+				continue
+			}
+			if m.opts.Background {
+				index.Options.SetBackground(true)
+			}
+			missing = append(missing, index)
+		}
+		if len(missing) == 0 {
+			continue
+		}
+
+		if _, err := m.db.Collection(def.Collection).Indexes().CreateMany(ctx, missing); err != nil {
+			return fmt.Errorf("failed to create indexes for %s: %w", def.Collection, err)
+		}
+	}
+
+	return nil
 }
 
-// ValidateIndexes validates that all required indexes exist.
-func (m *IndexManager) ValidateIndexes(ctx context.Context) error {
-	collections := map[string][]string{
-		customersCollection:  {"idx_customers_tenant_code_unique", "idx_customers_text_search"},
-		contactsCollection:   {"idx_contacts_customer", "idx_contacts_text_search"},
-		notesCollection:      {"idx_notes_customer"},
-		activitiesCollection: {"idx_activities_customer"},
-		segmentsCollection:   {"idx_segments_tenant_name_unique"},
-		importsCollection:    {"idx_imports_tenant"},
-		outboxCollection:     {"idx_outbox_pending"},
-	}
-
-	for collName, requiredIndexes := range collections {
-		coll := m.db.Collection(collName)
-		cursor, err := coll.Indexes().List(ctx)
+// CheckDrift compares the indexes of each collection with the declared
+// ones and returns the collections that differ.
+func (m *IndexManager) CheckDrift(ctx context.Context) ([]IndexDrift, error) {
+	var drifts []IndexDrift
+	for _, def := range m.Definitions() {
+		existing, err := m.listIndexes(ctx, def.Collection)
 		if err != nil {
-			return fmt.Errorf("failed to list indexes for %s: %w", collName, err)
+			return nil, err
 		}
-		defer cursor.Close(ctx)
 
-		existingIndexes := make(map[string]bool)
-		for cursor.Next(ctx) {
-			var idx struct {
-				Name string `bson:"name"`
+		drift := IndexDrift{Collection: def.Collection}
+		declared := make(map[string]bool, len(def.Indexes))
+		for _, index := range def.Indexes {
+			name := indexName(index)
+			declared[name] = true
+
+			actual, ok := existing[name]
+			switch {
+			case !ok:
+				drift.Missing = append(drift.Missing, name)
+			case !actual.matches(index):
+				drift.Changed = append(drift.Changed, name)
 			}
-			if err := cursor.Decode(&idx); err != nil {
-				continue
+		}
+		for name := range existing {
+			if name != "_id_" && !declared[name] {
+				drift.Unexpected = append(drift.Unexpected, name)
 			}
-			existingIndexes[idx.Name] = true
 		}
+		sort.Strings(drift.Unexpected)
 
-		for _, requiredIdx := range requiredIndexes {
-			if !existingIndexes[requiredIdx] {
-				return fmt.Errorf("missing required index %s on collection %s", requiredIdx, collName)
-			}
+		if len(drift.Missing) > 0 || len(drift.Unexpected) > 0 || len(drift.Changed) > 0 {
+			drifts = append(drifts, drift)
 		}
 	}
 
-	return nil
+	return drifts, nil
 }
 
-// GetIndexStats returns statistics about indexes for monitoring.
-func (m *IndexManager) GetIndexStats(ctx context.Context) (map[string]interface{}, error) {
-	stats := make(map[string]interface{})
+// ValidateIndexes validates that all required indexes exist.
+func (m *IndexManager) ValidateIndexes(ctx context.Context) error {
+	drifts, err := m.CheckDrift(ctx)
+	if err != nil {
+		return err
+	}
 
-	collections := []string{
-		customersCollection,
-		contactsCollection,
-		notesCollection,
-		activitiesCollection,
-		segmentsCollection,
-		importsCollection,
-		outboxCollection,
+	for _, drift := range drifts {
+		if len(drift.Missing) > 0 {
+			return fmt.Errorf("missing required index %s on collection %s", drift.Missing[0], drift.Collection)
+		}
 	}
 
-	for _, collName := range collections {
-		coll := m.db.Collection(collName)
-		cursor, err := coll.Indexes().List(ctx)
+	return nil
+}
+
+// IndexUsage returns how often each index of the declared collections was
+// used, least used first so indexes worth dropping stand out.
+func (m *IndexManager) IndexUsage(ctx context.Context) ([]IndexUsage, error) {
+	var usage []IndexUsage
+	for _, def := range m.Definitions() {
+		cursor, err := m.db.Collection(def.Collection).Aggregate(ctx, mongo.Pipeline{
+			{{Key: "$indexStats", Value: bson.M{}}},
+		})
 		if err != nil {
-			return nil, fmt.Errorf("failed to list indexes for %s: %w", collName, err)
+			return nil, fmt.Errorf("failed to read index stats for %s: %w", def.Collection, err)
+		}
+
+		var stats []struct {
+			Name     string `bson:"name"`
+			Host     string `bson:"host"`
+			Accesses struct {
+				Ops   int64     `bson:"ops"`
+				Since time.Time `bson:"since"`
+			} `bson:"accesses"`
+		}
+		if err := cursor.All(ctx, &stats); err != nil {
+			return nil, fmt.Errorf("failed to decode index stats for %s: %w", def.Collection, err)
 		}
 
-		var indexes []bson.M
-		if err := cursor.All(ctx, &indexes); err != nil {
-			cursor.Close(ctx)
-			return nil, fmt.Errorf("failed to decode indexes for %s: %w", collName, err)
+		for _, s := range stats {
+			usage = append(usage, IndexUsage{
+				Collection: def.Collection,
+				Name:       s.Name,
+				Ops:        s.Accesses.Ops,
+				Since:      s.Accesses.Since,
+				Host:       s.Host,
+			})
 		}
-		cursor.Close(ctx)
+	}
+
+	sort.SliceStable(usage, func(i, j int) bool {
+		return usage[i].Ops < usage[j].Ops
+	})
+	return usage, nil
+}
+
+// existingIndex is an index as listed by the server.
+type existingIndex struct {
+	Name                    string   `bson:"name"`
+	Key                     bson.D   `bson:"key"`
+	Unique                  bool     `bson:"unique"`
+	ExpireAfterSeconds      *int32   `bson:"expireAfterSeconds"`
+	PartialFilterExpression bson.Raw `bson:"partialFilterExpression"`
+}
 
-		collStats := map[string]interface{}{
-			"index_count": len(indexes),
-			"indexes":     indexes,
+// matches reports whether the index has the keys and the unique, TTL and
+// partial options of the declared one. The keys of text indexes are stored
+// in another form, so only their options are compared.
+func (e existingIndex) matches(index mongo.IndexModel) bool {
+	opts := index.Options
+	if e.Unique != (opts.Unique != nil && *opts.Unique) {
+		return false
+	}
+	if (e.ExpireAfterSeconds == nil) != (opts.ExpireAfterSeconds == nil) ||
+		(e.ExpireAfterSeconds != nil && *e.ExpireAfterSeconds != *opts.ExpireAfterSeconds) {
+		return false
+	}
+	if (e.PartialFilterExpression == nil) != (opts.PartialFilterExpression == nil) {
+		return false
+	}
+
+	keys, ok := index.Keys.(bson.D)
+	if !ok {
+		return true
+	}
+	for _, key := range keys {
+		if key.Value == "text" {
+			return true
 		}
-		stats[collName] = collStats
 	}
+	if len(keys) != len(e.Key) {
+		return false
+	}
+	for i, key := range keys {
+		if key.Key != e.Key[i].Key || fmt.Sprint(key.Value) != fmt.Sprint(e.Key[i].Value) {
+			return false
+		}
+	}
+	return true
+}
 
-	stats["timestamp"] = time.Now().UTC()
-	return stats, nil
+// listIndexes returns the indexes of a collection by name.
+func (m *IndexManager) listIndexes(ctx context.Context, collection string) (map[string]existingIndex, error) {
+	cursor, err := m.db.Collection(collection).Indexes().List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list indexes for %s: %w", collection, err)
+	}
+
+	var indexes []existingIndex
+	if err := cursor.All(ctx, &indexes); err != nil {
+		return nil, fmt.Errorf("failed to decode indexes for %s: %w", collection, err)
+	}
+
+	byName := make(map[string]existingIndex, len(indexes))
+	for _, index := range indexes {
+		byName[index.Name] = index
+	}
+	return byName, nil
+}
+
+// indexName returns the name set on a declared index.
+func indexName(index mongo.IndexModel) string {
+	if index.Options == nil || index.Options.Name == nil {
+		return ""
+	}
+	return *index.Options.Name
 }
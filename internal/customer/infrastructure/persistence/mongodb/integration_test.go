@@ -9,6 +9,8 @@ import (
 
 	"github.com/google/uuid"
 	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
 
 	"github.com/kilang-desa-murni/crm/internal/customer/domain"
 	"github.com/kilang-desa-murni/crm/pkg/testing/containers"
@@ -719,6 +721,58 @@ func TestConcurrentCustomerCreation(t *testing.T) {
 	})
 }
 
+// ============================================================================
+// Index Management Tests
+// ============================================================================
+
+func TestIndexManager_Drift(t *testing.T) {
+	setupTest(t)
+	defer cleanupTest(t)
+
+	db := testMongoDB.GetClient().Database("crm_customer_indexes_test")
+	defer db.Drop(context.Background())
+
+	manager := NewIndexManager(db, IndexOptions{SoftDeleteRetention: 30 * 24 * time.Hour})
+
+	t.Run("creates the declared indexes", func(t *testing.T) {
+		helpers.AssertNoError(t, manager.EnsureIndexes(testCtx))
+		helpers.AssertNoError(t, manager.ValidateIndexes(testCtx))
+
+		drifts, err := manager.CheckDrift(testCtx)
+		helpers.AssertNoError(t, err)
+		helpers.AssertEmpty(t, drifts)
+	})
+
+	t.Run("reports unexpected and changed indexes", func(t *testing.T) {
+		_, err := db.Collection(customersCollection).Indexes().CreateOne(testCtx, mongo.IndexModel{
+			Keys:    bson.D{{Key: "source", Value: 1}},
+			Options: options.Index().SetName("idx_customers_adhoc"),
+		})
+		helpers.AssertNoError(t, err)
+
+		shorterTTL := NewIndexManager(db, IndexOptions{SoftDeleteRetention: 7 * 24 * time.Hour})
+		drifts, err := shorterTTL.CheckDrift(testCtx)
+		helpers.AssertNoError(t, err)
+
+		var customers *IndexDrift
+		for i := range drifts {
+			if drifts[i].Collection == customersCollection {
+				customers = &drifts[i]
+			}
+		}
+		helpers.AssertNotNil(t, customers)
+		helpers.AssertEqual(t, []string{"idx_customers_adhoc"}, customers.Unexpected)
+		helpers.AssertEqual(t, []string{"idx_customers_deleted_expiry"}, customers.Changed)
+		helpers.AssertEmpty(t, customers.Missing)
+	})
+
+	t.Run("reports index usage", func(t *testing.T) {
+		usage, err := manager.IndexUsage(testCtx)
+		helpers.AssertNoError(t, err)
+		helpers.AssertNotEmpty(t, usage)
+	})
+}
+
 // ============================================================================
 // Helper Functions
 // ============================================================================
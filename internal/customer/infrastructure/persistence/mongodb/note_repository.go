@@ -153,6 +153,15 @@ func (r *NoteRepository) DeleteByCustomer(ctx context.Context, customerID uuid.U
 	return nil
 }
 
+// PurgeByCustomer permanently deletes all notes for a customer.
+func (r *NoteRepository) PurgeByCustomer(ctx context.Context, customerID uuid.UUID) (int64, error) {
+	result, err := r.collection.DeleteMany(ctx, bson.M{"customer_id": customerID})
+	if err != nil {
+		return 0, fmt.Errorf("failed to purge notes by customer: %w", err)
+	}
+	return result.DeletedCount, nil
+}
+
 // Pin pins a note.
 func (r *NoteRepository) Pin(ctx context.Context, id uuid.UUID) error {
 	filter := bson.M{"_id": id}
@@ -0,0 +1,119 @@
+// Package mongodb provides MongoDB implementations for customer repositories.
+package mongodb
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/google/uuid"
+
+	"github.com/kilang-desa-murni/crm/internal/customer/domain"
+)
+
+const (
+	purchasesCollection = "customer_purchases"
+)
+
+// PurchaseRepository implements domain.PurchaseRepository using MongoDB.
+type PurchaseRepository struct {
+	db         *mongo.Database
+	collection *mongo.Collection
+}
+
+// NewPurchaseRepository creates a new PurchaseRepository.
+func NewPurchaseRepository(db *mongo.Database) *PurchaseRepository {
+	return &PurchaseRepository{
+		db:         db,
+		collection: db.Collection(purchasesCollection),
+	}
+}
+
+// Create records a purchase. The unique index on the tenant and opportunity
+// makes recording the same won deal twice fail.
+func (r *PurchaseRepository) Create(ctx context.Context, purchase *domain.Purchase) error {
+	_, err := r.collection.InsertOne(ctx, purchase)
+	if err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return domain.ErrPurchaseAlreadyRecorded
+		}
+		return fmt.Errorf("failed to create purchase: %w", err)
+	}
+
+	return nil
+}
+
+// FindByCustomer finds a customer's purchases, most recent first.
+func (r *PurchaseRepository) FindByCustomer(ctx context.Context, customerID uuid.UUID, limit int) ([]*domain.Purchase, error) {
+	opts := options.Find().
+		SetSort(bson.D{{Key: "purchased_at", Value: -1}}).
+		SetLimit(int64(limit))
+
+	cursor, err := r.collection.Find(ctx, bson.M{"customer_id": customerID}, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find purchases: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var purchases []*domain.Purchase
+	if err := cursor.All(ctx, &purchases); err != nil {
+		return nil, fmt.Errorf("failed to decode purchases: %w", err)
+	}
+
+	return purchases, nil
+}
+
+// TopCustomers totals the tenant's purchases in the currency made in
+// [from, to) per customer, highest revenue first.
+func (r *PurchaseRepository) TopCustomers(ctx context.Context, tenantID uuid.UUID, currency domain.Currency, from, to time.Time, limit int) ([]*domain.CustomerRevenue, error) {
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{
+			"tenant_id":       tenantID,
+			"amount.currency": currency,
+			"purchased_at":    bson.M{"$gte": from, "$lt": to},
+		}}},
+		{{Key: "$group", Value: bson.M{
+			"_id":              "$customer_id",
+			"revenue_amount":   bson.M{"$sum": "$amount.amount"},
+			"order_count":      bson.M{"$sum": 1},
+			"last_purchase_at": bson.M{"$max": "$purchased_at"},
+		}}},
+		{{Key: "$sort", Value: bson.D{
+			{Key: "revenue_amount", Value: -1},
+			{Key: "_id", Value: 1},
+		}}},
+		{{Key: "$limit", Value: int64(limit)}},
+	}
+
+	cursor, err := r.collection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, fmt.Errorf("failed to aggregate purchases: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var rows []struct {
+		CustomerID     uuid.UUID `bson:"_id"`
+		RevenueAmount  int64     `bson:"revenue_amount"`
+		OrderCount     int       `bson:"order_count"`
+		LastPurchaseAt time.Time `bson:"last_purchase_at"`
+	}
+	if err := cursor.All(ctx, &rows); err != nil {
+		return nil, fmt.Errorf("failed to decode purchase totals: %w", err)
+	}
+
+	results := make([]*domain.CustomerRevenue, 0, len(rows))
+	for _, row := range rows {
+		results = append(results, &domain.CustomerRevenue{
+			CustomerID:     row.CustomerID,
+			Revenue:        domain.Money{Amount: row.RevenueAmount, Currency: currency},
+			OrderCount:     row.OrderCount,
+			LastPurchaseAt: row.LastPurchaseAt,
+		})
+	}
+
+	return results, nil
+}
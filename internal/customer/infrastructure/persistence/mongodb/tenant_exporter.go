@@ -0,0 +1,91 @@
+// Package mongodb provides MongoDB implementations for customer repositories.
+package mongodb
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+
+	"github.com/google/uuid"
+
+	"github.com/kilang-desa-murni/crm/internal/customer/domain"
+)
+
+// TenantExporter implements domain.TenantDataExporter using MongoDB.
+type TenantExporter struct {
+	db         *mongo.Database
+	collection *mongo.Collection
+}
+
+// NewTenantExporter creates a new TenantExporter.
+func NewTenantExporter(db *mongo.Database) *TenantExporter {
+	return &TenantExporter{
+		db:         db,
+		collection: db.Collection(customersCollection),
+	}
+}
+
+// Ensure TenantExporter implements domain.TenantDataExporter.
+var _ domain.TenantDataExporter = (*TenantExporter)(nil)
+
+// ExportTenantData streams the tenant's customers, or the contacts embedded
+// in them, as JSON in their API representation.
+func (e *TenantExporter) ExportTenantData(ctx context.Context, tenantID uuid.UUID, dataset string, w io.Writer) (int64, error) {
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"tenant_id": tenantID, "deleted_at": nil}}},
+		{{Key: "$sort", Value: bson.D{{Key: "created_at", Value: 1}, {Key: "_id", Value: 1}}}},
+	}
+
+	var decode func(*mongo.Cursor) (interface{}, error)
+	switch dataset {
+	case domain.TenantExportCustomers:
+		decode = func(cursor *mongo.Cursor) (interface{}, error) {
+			var customer domain.Customer
+			err := cursor.Decode(&customer)
+			return &customer, err
+		}
+	case domain.TenantExportContacts:
+		pipeline = append(pipeline,
+			bson.D{{Key: "$unwind", Value: "$contacts"}},
+			bson.D{{Key: "$replaceRoot", Value: bson.M{"newRoot": "$contacts"}}},
+			bson.D{{Key: "$match", Value: bson.M{"deleted_at": nil}}},
+		)
+		decode = func(cursor *mongo.Cursor) (interface{}, error) {
+			var contact domain.Contact
+			err := cursor.Decode(&contact)
+			return &contact, err
+		}
+	default:
+		return 0, fmt.Errorf("unknown export data set %q", dataset)
+	}
+
+	cursor, err := e.collection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return 0, fmt.Errorf("failed to query %s: %w", dataset, err)
+	}
+	defer cursor.Close(ctx)
+
+	out := bufio.NewWriter(w)
+	encoder := json.NewEncoder(out)
+	var count int64
+	for cursor.Next(ctx) {
+		record, err := decode(cursor)
+		if err != nil {
+			return count, fmt.Errorf("failed to decode %s: %w", dataset, err)
+		}
+		if err := encoder.Encode(record); err != nil {
+			return count, err
+		}
+		count++
+	}
+	if err := cursor.Err(); err != nil {
+		return count, fmt.Errorf("failed to read %s: %w", dataset, err)
+	}
+
+	return count, out.Flush()
+}
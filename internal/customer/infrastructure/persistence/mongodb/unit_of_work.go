@@ -5,27 +5,58 @@ import (
 	"context"
 	"fmt"
 	"sync"
+	"time"
 
 	"go.mongodb.org/mongo-driver/mongo"
 
 	"github.com/kilang-desa-murni/crm/internal/customer/domain"
+	"github.com/kilang-desa-murni/crm/pkg/database"
 )
 
-// sessionContextKey is the key used to store the MongoDB session in context.
-type sessionContextKey struct{}
+// maxTransactionAttempts bounds the runs of a transaction that keeps failing
+// with transient errors, and maxCommitAttempts the commits of a transaction
+// whose commit result is unknown.
+const (
+	maxTransactionAttempts = 3
+	maxCommitAttempts      = 3
+)
+
+// transactionRetryDelay is the pause before running a transaction again,
+// multiplied by the attempt number.
+var transactionRetryDelay = 50 * time.Millisecond
+
+// transactionContextKey is the key used to store the transaction in context.
+type transactionContextKey struct{}
+
+// transaction is a unit of work begun with Begin.
+type transaction struct {
+	// session is nil when the server does not support transactions.
+	session mongo.Session
+	// joined is set when Begin was called within another transaction; the
+	// outer transaction commits or aborts the work.
+	joined bool
+	ended  bool
+}
 
 // UnitOfWork implements domain.UnitOfWork using MongoDB transactions.
 type UnitOfWork struct {
-	client             *mongo.Client
-	db                 *mongo.Database
-	customerRepo       *CustomerRepository
-	contactRepo        *ContactRepository
-	noteRepo           *NoteRepository
-	activityRepo       *ActivityRepository
-	segmentRepo        *SegmentRepository
-	importRepo         *ImportRepository
-	outboxRepo         *OutboxRepository
-	mu                 sync.RWMutex
+	client       *mongo.Client
+	db           *mongo.Database
+	customerRepo *CustomerRepository
+	contactRepo  *ContactRepository
+	noteRepo     *NoteRepository
+	activityRepo *ActivityRepository
+	segmentRepo  *SegmentRepository
+	importRepo   *ImportRepository
+	outboxRepo   *OutboxRepository
+	purchaseRepo *PurchaseRepository
+	erasureRepo  *ErasureRepository
+	erasedIDRepo *ErasedIdentifierRepository
+	mu           sync.RWMutex
+
+	// transactions caches whether the server supports transactions once it
+	// has been detected.
+	transactions *bool
 }
 
 // NewUnitOfWork creates a new UnitOfWork.
@@ -40,11 +71,25 @@ func NewUnitOfWork(client *mongo.Client, db *mongo.Database) *UnitOfWork {
 		segmentRepo:  NewSegmentRepository(db),
 		importRepo:   NewImportRepository(db),
 		outboxRepo:   NewOutboxRepository(db),
+		purchaseRepo: NewPurchaseRepository(db),
+		erasureRepo:  NewErasureRepository(db),
+		erasedIDRepo: NewErasedIdentifierRepository(db),
 	}
 }
 
 // Begin begins a new transaction and returns a context with the session.
+// Repository calls made with the returned context are part of the
+// transaction. Begin within a transaction joins it. On a standalone server,
+// which does not support transactions, the writes are made as they come.
 func (uow *UnitOfWork) Begin(ctx context.Context) (context.Context, error) {
+	if outer := transactionFrom(ctx); outer != nil && !outer.ended {
+		return context.WithValue(ctx, transactionContextKey{}, &transaction{session: outer.session, joined: true}), nil
+	}
+
+	if !uow.supportsTransactions(ctx) {
+		return context.WithValue(ctx, transactionContextKey{}, &transaction{}), nil
+	}
+
 	// Start a new session
 	session, err := uow.client.StartSession()
 	if err != nil {
@@ -57,44 +102,76 @@ func (uow *UnitOfWork) Begin(ctx context.Context) (context.Context, error) {
 		return nil, fmt.Errorf("failed to start transaction: %w", err)
 	}
 
-	// Store session in context
-	txCtx := context.WithValue(ctx, sessionContextKey{}, session)
+	// Store the transaction in context
+	txCtx := context.WithValue(ctx, transactionContextKey{}, &transaction{session: session})
 
 	// Use session context for MongoDB operations
 	return mongo.NewSessionContext(txCtx, session), nil
 }
 
-// Commit commits the transaction.
+// Commit commits the transaction. A commit whose result is unknown, e.g.
+// after a network error, is retried.
 func (uow *UnitOfWork) Commit(ctx context.Context) error {
-	session := uow.getSession(ctx)
-	if session == nil {
+	tx := transactionFrom(ctx)
+	if tx == nil {
 		return fmt.Errorf("no active transaction")
 	}
-
-	if err := session.CommitTransaction(ctx); err != nil {
-		return fmt.Errorf("failed to commit transaction: %w", err)
+	if tx.joined || tx.ended {
+		return nil
+	}
+	tx.ended = true
+	if tx.session == nil {
+		return nil
+	}
+	defer tx.session.EndSession(ctx)
+
+	for attempt := 1; ; attempt++ {
+		err := tx.session.CommitTransaction(ctx)
+		if err == nil {
+			return nil
+		}
+		if !database.IsUnknownCommitResultError(err) || attempt == maxCommitAttempts {
+			return fmt.Errorf("failed to commit transaction: %w", err)
+		}
 	}
-
-	session.EndSession(ctx)
-	return nil
 }
 
-// Rollback rolls back the transaction.
+// Rollback rolls back the transaction. It does nothing once the transaction
+// is committed, so it can be deferred right after Begin.
 func (uow *UnitOfWork) Rollback(ctx context.Context) error {
-	session := uow.getSession(ctx)
-	if session == nil {
+	tx := transactionFrom(ctx)
+	if tx == nil || tx.joined || tx.ended {
 		return nil // No active transaction, nothing to rollback
 	}
+	tx.ended = true
+	if tx.session == nil {
+		return nil
+	}
+	defer tx.session.EndSession(ctx)
 
-	if err := session.AbortTransaction(ctx); err != nil {
-		session.EndSession(ctx)
+	if err := tx.session.AbortTransaction(ctx); err != nil {
 		return fmt.Errorf("failed to rollback transaction: %w", err)
 	}
-
-	session.EndSession(ctx)
 	return nil
 }
 
+// supportsTransactions reports whether the server supports transactions,
+// detecting it on first use. While detection fails transactions are
+// attempted, so the error surfaces from the transaction.
+func (uow *UnitOfWork) supportsTransactions(ctx context.Context) bool {
+	uow.mu.Lock()
+	defer uow.mu.Unlock()
+
+	if uow.transactions == nil {
+		supported, err := database.SupportsTransactions(ctx, uow.client)
+		if err != nil {
+			return true
+		}
+		uow.transactions = &supported
+	}
+	return *uow.transactions
+}
+
 // Customers returns the customer repository.
 func (uow *UnitOfWork) Customers() domain.CustomerRepository {
 	uow.mu.RLock()
@@ -144,10 +221,31 @@ func (uow *UnitOfWork) Imports() domain.ImportRepository {
 	return uow.importRepo
 }
 
-// getSession extracts the MongoDB session from the context.
-func (uow *UnitOfWork) getSession(ctx context.Context) mongo.Session {
-	session, _ := ctx.Value(sessionContextKey{}).(mongo.Session)
-	return session
+// Purchases returns the purchase repository.
+func (uow *UnitOfWork) Purchases() domain.PurchaseRepository {
+	uow.mu.RLock()
+	defer uow.mu.RUnlock()
+	return uow.purchaseRepo
+}
+
+// Erasures returns the erasure request repository.
+func (uow *UnitOfWork) Erasures() domain.ErasureRepository {
+	uow.mu.RLock()
+	defer uow.mu.RUnlock()
+	return uow.erasureRepo
+}
+
+// ErasedIdentifiers returns the erased identifier repository.
+func (uow *UnitOfWork) ErasedIdentifiers() domain.ErasedIdentifierRepository {
+	uow.mu.RLock()
+	defer uow.mu.RUnlock()
+	return uow.erasedIDRepo
+}
+
+// transactionFrom extracts the transaction from the context.
+func transactionFrom(ctx context.Context) *transaction {
+	tx, _ := ctx.Value(transactionContextKey{}).(*transaction)
+	return tx
 }
 
 // Database returns the MongoDB database for direct access if needed.
@@ -160,18 +258,45 @@ func (uow *UnitOfWork) Client() *mongo.Client {
 	return uow.client
 }
 
-// WithTransaction executes a function within a transaction.
+// WithTransaction executes a function within a transaction and commits it
+// if fn succeeds. When the transaction fails with a transient error, such as
+// a write conflict with a concurrent transaction or a primary election, fn
+// is run again in a new transaction. Within another transaction fn joins it
+// and the outer transaction retries.
 func (uow *UnitOfWork) WithTransaction(ctx context.Context, fn func(context.Context) error) error {
+	if outer := transactionFrom(ctx); outer != nil && !outer.ended {
+		return fn(ctx)
+	}
+
+	var err error
+	for attempt := 1; attempt <= maxTransactionAttempts; attempt++ {
+		if attempt > 1 {
+			select {
+			case <-ctx.Done():
+				return err
+			case <-time.After(time.Duration(attempt-1) * transactionRetryDelay):
+			}
+		}
+
+		err = uow.runTransaction(ctx, fn)
+		if err == nil || !database.IsTransientTransactionError(err) {
+			return err
+		}
+	}
+	return err
+}
+
+// runTransaction runs fn in one transaction.
+func (uow *UnitOfWork) runTransaction(ctx context.Context, fn func(context.Context) error) error {
 	txCtx, err := uow.Begin(ctx)
 	if err != nil {
 		return err
 	}
+	defer uow.Rollback(txCtx)
 
 	if err := fn(txCtx); err != nil {
-		_ = uow.Rollback(txCtx)
 		return err
 	}
-
 	return uow.Commit(txCtx)
 }
 
@@ -187,6 +312,6 @@ func (uow *UnitOfWork) Close(ctx context.Context) error {
 
 // EnsureIndexes ensures all required indexes exist.
 func (uow *UnitOfWork) EnsureIndexes(ctx context.Context) error {
-	indexManager := NewIndexManager(uow.db)
+	indexManager := NewIndexManager(uow.db, IndexOptions{})
 	return indexManager.EnsureIndexes(ctx)
 }
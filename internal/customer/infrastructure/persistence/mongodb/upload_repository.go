@@ -0,0 +1,115 @@
+// Package mongodb provides MongoDB implementations for customer repositories.
+package mongodb
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/google/uuid"
+
+	"github.com/kilang-desa-murni/crm/internal/customer/domain"
+)
+
+const importUploadsCollection = "customer_import_uploads"
+
+// ============================================================================
+// Import Upload Repository
+// ============================================================================
+
+// ImportUploadRepository implements domain.ImportUploadRepository using
+// MongoDB. Expired uploads are deleted by the upload expiry worker rather
+// than a TTL index, as their files must be deleted with them.
+type ImportUploadRepository struct {
+	db         *mongo.Database
+	collection *mongo.Collection
+}
+
+// NewImportUploadRepository creates a new ImportUploadRepository.
+func NewImportUploadRepository(db *mongo.Database) *ImportUploadRepository {
+	return &ImportUploadRepository{
+		db:         db,
+		collection: db.Collection(importUploadsCollection),
+	}
+}
+
+// Create creates an upload.
+func (r *ImportUploadRepository) Create(ctx context.Context, upload *domain.ImportUpload) error {
+	if _, err := r.collection.InsertOne(ctx, upload); err != nil {
+		return fmt.Errorf("failed to create import upload: %w", err)
+	}
+	return nil
+}
+
+// Update updates an upload with optimistic locking.
+func (r *ImportUploadRepository) Update(ctx context.Context, upload *domain.ImportUpload) error {
+	previousVersion := upload.Version
+	upload.Version++
+
+	filter := bson.M{
+		"_id":     upload.ID,
+		"version": previousVersion,
+	}
+
+	result, err := r.collection.ReplaceOne(ctx, filter, upload)
+	if err != nil {
+		upload.Version = previousVersion
+		return fmt.Errorf("failed to update import upload: %w", err)
+	}
+
+	if result.MatchedCount == 0 {
+		upload.Version = previousVersion
+		count, err := r.collection.CountDocuments(ctx, bson.M{"_id": upload.ID})
+		if err == nil && count == 0 {
+			return domain.ErrUploadNotFound
+		}
+		return domain.ErrVersionConflict
+	}
+
+	return nil
+}
+
+// FindByID finds an upload by ID.
+func (r *ImportUploadRepository) FindByID(ctx context.Context, id uuid.UUID) (*domain.ImportUpload, error) {
+	var upload domain.ImportUpload
+	err := r.collection.FindOne(ctx, bson.M{"_id": id}).Decode(&upload)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, domain.ErrUploadNotFound
+		}
+		return nil, fmt.Errorf("failed to find import upload: %w", err)
+	}
+	return &upload, nil
+}
+
+// FindExpired finds up to limit uploads that expired before the time, those
+// expired longest first.
+func (r *ImportUploadRepository) FindExpired(ctx context.Context, before time.Time, limit int) ([]*domain.ImportUpload, error) {
+	opts := options.Find().
+		SetSort(bson.D{{Key: "expires_at", Value: 1}}).
+		SetLimit(int64(limit))
+
+	cursor, err := r.collection.Find(ctx, bson.M{"expires_at": bson.M{"$lt": before}}, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find expired import uploads: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var uploads []*domain.ImportUpload
+	if err := cursor.All(ctx, &uploads); err != nil {
+		return nil, fmt.Errorf("failed to decode import uploads: %w", err)
+	}
+	return uploads, nil
+}
+
+// Delete deletes an upload.
+func (r *ImportUploadRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	if _, err := r.collection.DeleteOne(ctx, bson.M{"_id": id}); err != nil {
+		return fmt.Errorf("failed to delete import upload: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,68 @@
+// Package storage contains file storage adapters for the Customer service.
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/google/uuid"
+)
+
+// LocalUploadStorage implements ports.UploadStorage on the local filesystem.
+// Each upload is one file, <baseDir>/<uploadID>.part, written in place as
+// chunks arrive. Service instances must share the directory, such as on a
+// shared volume, as the chunks of an upload may reach any instance.
+type LocalUploadStorage struct {
+	baseDir string
+}
+
+// NewLocalUploadStorage creates a new local upload storage rooted at baseDir.
+func NewLocalUploadStorage(baseDir string) (*LocalUploadStorage, error) {
+	if err := os.MkdirAll(baseDir, 0o750); err != nil {
+		return nil, fmt.Errorf("failed to create upload directory: %w", err)
+	}
+	return &LocalUploadStorage{baseDir: baseDir}, nil
+}
+
+// WriteAt writes a chunk at the offset of the upload's file.
+func (s *LocalUploadStorage) WriteAt(ctx context.Context, uploadID uuid.UUID, offset int64, data []byte) error {
+	file, err := os.OpenFile(s.path(uploadID), os.O_WRONLY|os.O_CREATE, 0o640)
+	if err != nil {
+		return fmt.Errorf("failed to open upload file: %w", err)
+	}
+	if _, err := file.WriteAt(data, offset); err != nil {
+		file.Close()
+		return fmt.Errorf("failed to write chunk: %w", err)
+	}
+	// The chunk is only acknowledged once it is on disk
+	if err := file.Sync(); err != nil {
+		file.Close()
+		return fmt.Errorf("failed to sync chunk: %w", err)
+	}
+	return file.Close()
+}
+
+// Open opens the upload's file for reading.
+func (s *LocalUploadStorage) Open(ctx context.Context, uploadID uuid.UUID) (io.ReadCloser, error) {
+	file, err := os.Open(s.path(uploadID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to open upload file: %w", err)
+	}
+	return file, nil
+}
+
+// Delete deletes the upload's file.
+func (s *LocalUploadStorage) Delete(ctx context.Context, uploadID uuid.UUID) error {
+	if err := os.Remove(s.path(uploadID)); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("failed to delete upload file: %w", err)
+	}
+	return nil
+}
+
+func (s *LocalUploadStorage) path(uploadID uuid.UUID) string {
+	return filepath.Join(s.baseDir, uploadID.String()+".part")
+}
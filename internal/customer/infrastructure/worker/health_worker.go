@@ -0,0 +1,102 @@
+package worker
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/kilang-desa-murni/crm/internal/customer/application/usecase"
+)
+
+// HealthConfig holds configuration for the health scoring worker.
+type HealthConfig struct {
+	// Interval is how often due customers are scored. Each customer is
+	// scored once per health interval, so runs may be more frequent to
+	// spread the scoring across the day.
+	Interval time.Duration
+	// RunTimeout bounds a single scoring run of all tenants.
+	RunTimeout time.Duration
+}
+
+// DefaultHealthConfig returns the default worker configuration.
+func DefaultHealthConfig() HealthConfig {
+	return HealthConfig{
+		Interval:   time.Hour,
+		RunTimeout: 30 * time.Minute,
+	}
+}
+
+// HealthWorker periodically recalculates the health scores of customers not
+// scored in the last day.
+type HealthWorker struct {
+	health *usecase.CustomerHealthUseCase
+	config HealthConfig
+	logger *zap.Logger
+
+	stopCh chan struct{}
+	wg     sync.WaitGroup
+	once   sync.Once
+}
+
+// NewHealthWorker creates a new health scoring worker.
+func NewHealthWorker(health *usecase.CustomerHealthUseCase, config HealthConfig, logger *zap.Logger) *HealthWorker {
+	defaults := DefaultHealthConfig()
+	if config.Interval <= 0 {
+		config.Interval = defaults.Interval
+	}
+	if config.RunTimeout <= 0 {
+		config.RunTimeout = defaults.RunTimeout
+	}
+
+	return &HealthWorker{
+		health: health,
+		config: config,
+		logger: logger,
+		stopCh: make(chan struct{}),
+	}
+}
+
+// Start runs the worker in the background until Stop is called or ctx is cancelled.
+func (w *HealthWorker) Start(ctx context.Context) {
+	w.wg.Add(1)
+	go func() {
+		defer w.wg.Done()
+
+		ticker := time.NewTicker(w.config.Interval)
+		defer ticker.Stop()
+
+		w.score(ctx)
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-w.stopCh:
+				return
+			case <-ticker.C:
+				w.score(ctx)
+			}
+		}
+	}()
+}
+
+// Stop stops the worker and waits for a running scoring run to finish.
+func (w *HealthWorker) Stop() {
+	w.once.Do(func() { close(w.stopCh) })
+	w.wg.Wait()
+}
+
+func (w *HealthWorker) score(ctx context.Context) {
+	runCtx, cancel := context.WithTimeout(ctx, w.config.RunTimeout)
+	defer cancel()
+
+	scored, err := w.health.EvaluateDue(runCtx, time.Now().UTC())
+	if err != nil {
+		w.logger.Error("Customer health scoring failed", zap.Int("scored", scored), zap.Error(err))
+		return
+	}
+	if scored > 0 {
+		w.logger.Info("Customer health scoring completed", zap.Int("scored", scored))
+	}
+}
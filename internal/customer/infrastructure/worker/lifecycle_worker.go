@@ -0,0 +1,100 @@
+// Package worker provides background workers for the Customer service.
+package worker
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/kilang-desa-murni/crm/internal/customer/application/usecase"
+)
+
+// LifecycleConfig holds configuration for the lifecycle evaluation worker.
+type LifecycleConfig struct {
+	// Interval is how often customers are evaluated. Dormancy is measured in
+	// months, so a daily run is precise enough.
+	Interval time.Duration
+	// RunTimeout bounds a single evaluation of all tenants.
+	RunTimeout time.Duration
+}
+
+// DefaultLifecycleConfig returns the default worker configuration.
+func DefaultLifecycleConfig() LifecycleConfig {
+	return LifecycleConfig{
+		Interval:   24 * time.Hour,
+		RunTimeout: 30 * time.Minute,
+	}
+}
+
+// LifecycleWorker periodically applies the automatic lifecycle transitions,
+// turning active customers without a recent won deal dormant.
+type LifecycleWorker struct {
+	lifecycle *usecase.CustomerLifecycleUseCase
+	config    LifecycleConfig
+	logger    *zap.Logger
+
+	stopCh chan struct{}
+	wg     sync.WaitGroup
+	once   sync.Once
+}
+
+// NewLifecycleWorker creates a new lifecycle evaluation worker.
+func NewLifecycleWorker(lifecycle *usecase.CustomerLifecycleUseCase, config LifecycleConfig, logger *zap.Logger) *LifecycleWorker {
+	defaults := DefaultLifecycleConfig()
+	if config.Interval <= 0 {
+		config.Interval = defaults.Interval
+	}
+	if config.RunTimeout <= 0 {
+		config.RunTimeout = defaults.RunTimeout
+	}
+
+	return &LifecycleWorker{
+		lifecycle: lifecycle,
+		config:    config,
+		logger:    logger,
+		stopCh:    make(chan struct{}),
+	}
+}
+
+// Start runs the worker in the background until Stop is called or ctx is cancelled.
+func (w *LifecycleWorker) Start(ctx context.Context) {
+	w.wg.Add(1)
+	go func() {
+		defer w.wg.Done()
+
+		ticker := time.NewTicker(w.config.Interval)
+		defer ticker.Stop()
+
+		w.evaluate(ctx)
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-w.stopCh:
+				return
+			case <-ticker.C:
+				w.evaluate(ctx)
+			}
+		}
+	}()
+}
+
+// Stop stops the worker and waits for a running evaluation to finish.
+func (w *LifecycleWorker) Stop() {
+	w.once.Do(func() { close(w.stopCh) })
+	w.wg.Wait()
+}
+
+func (w *LifecycleWorker) evaluate(ctx context.Context) {
+	runCtx, cancel := context.WithTimeout(ctx, w.config.RunTimeout)
+	defer cancel()
+
+	changed, err := w.lifecycle.EvaluateDue(runCtx, time.Now().UTC())
+	if err != nil {
+		w.logger.Error("Customer lifecycle evaluation failed", zap.Int("changed", changed), zap.Error(err))
+		return
+	}
+	w.logger.Info("Customer lifecycle evaluation completed", zap.Int("changed", changed))
+}
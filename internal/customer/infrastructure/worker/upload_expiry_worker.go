@@ -0,0 +1,99 @@
+package worker
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/kilang-desa-murni/crm/internal/customer/application/usecase"
+)
+
+// UploadExpiryConfig holds configuration for the upload expiry worker.
+type UploadExpiryConfig struct {
+	// Interval is how often expired uploads are deleted.
+	Interval time.Duration
+	// RunTimeout bounds a single run.
+	RunTimeout time.Duration
+}
+
+// DefaultUploadExpiryConfig returns the default worker configuration.
+func DefaultUploadExpiryConfig() UploadExpiryConfig {
+	return UploadExpiryConfig{
+		Interval:   15 * time.Minute,
+		RunTimeout: 5 * time.Minute,
+	}
+}
+
+// UploadExpiryWorker periodically deletes abandoned import uploads, and
+// completed ones kept past their expiry, with their files.
+type UploadExpiryWorker struct {
+	uploads *usecase.ImportUploadUseCase
+	config  UploadExpiryConfig
+	logger  *zap.Logger
+
+	stopCh chan struct{}
+	wg     sync.WaitGroup
+	once   sync.Once
+}
+
+// NewUploadExpiryWorker creates a new upload expiry worker.
+func NewUploadExpiryWorker(uploads *usecase.ImportUploadUseCase, config UploadExpiryConfig, logger *zap.Logger) *UploadExpiryWorker {
+	defaults := DefaultUploadExpiryConfig()
+	if config.Interval <= 0 {
+		config.Interval = defaults.Interval
+	}
+	if config.RunTimeout <= 0 {
+		config.RunTimeout = defaults.RunTimeout
+	}
+
+	return &UploadExpiryWorker{
+		uploads: uploads,
+		config:  config,
+		logger:  logger,
+		stopCh:  make(chan struct{}),
+	}
+}
+
+// Start runs the worker in the background until Stop is called or ctx is cancelled.
+func (w *UploadExpiryWorker) Start(ctx context.Context) {
+	w.wg.Add(1)
+	go func() {
+		defer w.wg.Done()
+
+		ticker := time.NewTicker(w.config.Interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-w.stopCh:
+				return
+			case <-ticker.C:
+				w.purge(ctx)
+			}
+		}
+	}()
+}
+
+// Stop stops the worker and waits for a running run to finish.
+func (w *UploadExpiryWorker) Stop() {
+	w.once.Do(func() { close(w.stopCh) })
+	w.wg.Wait()
+}
+
+func (w *UploadExpiryWorker) purge(ctx context.Context) {
+	runCtx, cancel := context.WithTimeout(ctx, w.config.RunTimeout)
+	defer cancel()
+
+	purged, err := w.uploads.PurgeExpired(runCtx, time.Now().UTC())
+	if err != nil {
+		w.logger.Error("Import upload expiry failed", zap.Int("purged", purged), zap.Error(err))
+		return
+	}
+	if purged > 0 {
+		w.logger.Info("Expired import uploads deleted", zap.Int("purged", purged))
+	}
+}
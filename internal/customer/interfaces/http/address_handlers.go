@@ -0,0 +1,140 @@
+// Package http provides HTTP handlers for the Customer service.
+package http
+
+import (
+	"net/http"
+
+	"github.com/google/uuid"
+
+	"github.com/kilang-desa-murni/crm/internal/customer/application/dto"
+	"github.com/kilang-desa-murni/crm/internal/customer/application/usecase"
+)
+
+// ============================================================================
+// Customer Address Handlers
+// ============================================================================
+
+// ListCustomerAddresses handles GET /api/v1/customers/{customerId}/addresses
+func (h *Handler) ListCustomerAddresses(w http.ResponseWriter, r *http.Request) {
+	input, ok := addressInput(w, r, false)
+	if !ok {
+		return
+	}
+
+	addresses, err := h.customerAddresses.List(r.Context(), input)
+	if err != nil {
+		respondError(w, err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, APIResponse{
+		Success: true,
+		Data:    addresses,
+	})
+}
+
+// AddCustomerAddress handles POST /api/v1/customers/{customerId}/addresses
+func (h *Handler) AddCustomerAddress(w http.ResponseWriter, r *http.Request) {
+	input, ok := addressInput(w, r, false)
+	if !ok {
+		return
+	}
+
+	var req dto.AddressInput
+	if err := decodeJSON(r, &req); err != nil {
+		respondError(w, err)
+		return
+	}
+	input.Request = &req
+
+	customer, err := h.customerAddresses.Add(r.Context(), input)
+	if err != nil {
+		respondError(w, err)
+		return
+	}
+
+	respondJSON(w, http.StatusCreated, APIResponse{
+		Success: true,
+		Data:    customer,
+	})
+}
+
+// UpdateCustomerAddress handles PUT /api/v1/customers/{customerId}/addresses/{addressId}
+func (h *Handler) UpdateCustomerAddress(w http.ResponseWriter, r *http.Request) {
+	input, ok := addressInput(w, r, true)
+	if !ok {
+		return
+	}
+
+	var req dto.AddressInput
+	if err := decodeJSON(r, &req); err != nil {
+		respondError(w, err)
+		return
+	}
+	input.Request = &req
+
+	customer, err := h.customerAddresses.Update(r.Context(), input)
+	if err != nil {
+		respondError(w, err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, APIResponse{
+		Success: true,
+		Data:    customer,
+	})
+}
+
+// RemoveCustomerAddress handles DELETE /api/v1/customers/{customerId}/addresses/{addressId}
+func (h *Handler) RemoveCustomerAddress(w http.ResponseWriter, r *http.Request) {
+	input, ok := addressInput(w, r, true)
+	if !ok {
+		return
+	}
+
+	customer, err := h.customerAddresses.Remove(r.Context(), input)
+	if err != nil {
+		respondError(w, err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, APIResponse{
+		Success: true,
+		Data:    customer,
+	})
+}
+
+// addressInput reads the caller and the path parameters of an address
+// request, responding with an error when they are missing or invalid.
+func addressInput(w http.ResponseWriter, r *http.Request, withAddressID bool) (usecase.CustomerAddressInput, bool) {
+	ctx := r.Context()
+	input := usecase.CustomerAddressInput{
+		TenantID:  getTenantID(ctx),
+		UserID:    getUserID(ctx),
+		IPAddress: getClientIP(r),
+		UserAgent: getUserAgent(r),
+	}
+
+	if input.TenantID == uuid.Nil {
+		respondError(w, ErrUnauthorized("tenant_id is required"))
+		return input, false
+	}
+
+	customerID, err := getUUIDParam(r, "customerId")
+	if err != nil {
+		respondError(w, err)
+		return input, false
+	}
+	input.CustomerID = customerID
+
+	if withAddressID {
+		addressID, err := getUUIDParam(r, "addressId")
+		if err != nil {
+			respondError(w, err)
+			return input, false
+		}
+		input.AddressID = addressID
+	}
+
+	return input, true
+}
@@ -68,6 +68,12 @@ func (h *Handler) GetCustomer(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	fields, err := getQueryFields(r, customerFields)
+	if err != nil {
+		respondError(w, err)
+		return
+	}
+
 	input := usecase.GetCustomerInput{
 		TenantID:   tenantID,
 		CustomerID: customerID,
@@ -79,6 +85,82 @@ func (h *Handler) GetCustomer(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	data, err := fields.Apply(customer)
+	if err != nil {
+		respondError(w, err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, APIResponse{
+		Success: true,
+		Data:    data,
+	})
+}
+
+// BatchGetCustomers handles POST /api/v1/customers/batch-get
+func (h *Handler) BatchGetCustomers(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	tenantID := getTenantID(ctx)
+
+	if tenantID == uuid.Nil {
+		respondError(w, ErrUnauthorized("tenant_id is required"))
+		return
+	}
+
+	var req dto.BatchGetCustomersRequest
+	if err := decodeJSON(r, &req); err != nil {
+		respondError(w, err)
+		return
+	}
+
+	input := usecase.BatchGetCustomersInput{
+		TenantID: tenantID,
+		Request:  &req,
+	}
+
+	result, err := h.batchGetCustomers.Execute(ctx, input)
+	if err != nil {
+		respondError(w, err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, APIResponse{
+		Success: true,
+		Data:    result,
+	})
+}
+
+// MergeCustomers handles POST /api/v1/customers/merge
+func (h *Handler) MergeCustomers(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	tenantID := getTenantID(ctx)
+	userID := getUserID(ctx)
+
+	if tenantID == uuid.Nil {
+		respondError(w, ErrUnauthorized("tenant_id is required"))
+		return
+	}
+
+	var req dto.MergeCustomersRequest
+	if err := decodeJSON(r, &req); err != nil {
+		respondError(w, err)
+		return
+	}
+
+	input := usecase.MergeCustomersInput{
+		TenantID:  tenantID,
+		UserID:    userID,
+		Request:   &req,
+		IPAddress: getClientIP(r),
+		UserAgent: getUserAgent(r),
+	}
+
+	customer, err := h.mergeCustomers.Execute(ctx, input)
+	if err != nil {
+		respondError(w, err)
+		return
+	}
+
 	respondJSON(w, http.StatusOK, APIResponse{
 		Success: true,
 		Data:    customer,
@@ -255,6 +337,12 @@ func (h *Handler) ListCustomers(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	fields, err := getQueryFields(r, customerSummaryFields)
+	if err != nil {
+		respondError(w, err)
+		return
+	}
+
 	// Check if this is a search request (has query parameter)
 	if q := getQueryString(r, "q"); q != "" {
 		h.searchCustomers.Execute(ctx, usecase.SearchCustomersInput{
@@ -268,8 +356,11 @@ func (h *Handler) ListCustomers(w http.ResponseWriter, r *http.Request) {
 		TenantID:  tenantID,
 		Offset:    getQueryInt(r, "offset", 0),
 		Limit:     getQueryInt(r, "limit", 20),
+		Cursor:    getQueryString(r, "cursor"),
 		SortBy:    getQueryString(r, "sort_by"),
 		SortOrder: getQueryString(r, "sort_order"),
+		Filter:    getQueryString(r, "filter"),
+		Sort:      getQueryString(r, "sort"),
 	}
 
 	result, err := h.listCustomers.Execute(ctx, input)
@@ -278,14 +369,21 @@ func (h *Handler) ListCustomers(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	data, err := fields.Apply(result.Customers)
+	if err != nil {
+		respondError(w, err)
+		return
+	}
+
 	respondJSON(w, http.StatusOK, APIResponse{
 		Success: true,
-		Data:    result.Customers,
+		Data:    data,
 		Meta: &MetaResponse{
-			Total:   result.Total,
-			Offset:  result.Offset,
-			Limit:   result.Limit,
-			HasMore: result.HasMore,
+			Total:      result.Total,
+			Offset:     result.Offset,
+			Limit:      result.Limit,
+			HasMore:    result.HasMore,
+			NextCursor: result.NextCursor,
 		},
 	})
 }
@@ -300,6 +398,12 @@ func (h *Handler) SearchCustomers(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	fields, err := getQueryFields(r, customerSummaryFields)
+	if err != nil {
+		respondError(w, err)
+		return
+	}
+
 	input := usecase.SearchCustomersInput{
 		TenantID: tenantID,
 		Request:  buildSearchRequest(r),
@@ -311,14 +415,21 @@ func (h *Handler) SearchCustomers(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	data, err := fields.Apply(result.Customers)
+	if err != nil {
+		respondError(w, err)
+		return
+	}
+
 	respondJSON(w, http.StatusOK, APIResponse{
 		Success: true,
-		Data:    result.Customers,
+		Data:    data,
 		Meta: &MetaResponse{
-			Total:   result.Total,
-			Offset:  result.Offset,
-			Limit:   result.Limit,
-			HasMore: result.HasMore,
+			Total:      result.Total,
+			Offset:     result.Offset,
+			Limit:      result.Limit,
+			HasMore:    result.HasMore,
+			NextCursor: result.NextCursor,
 		},
 	})
 }
@@ -333,6 +444,12 @@ func (h *Handler) ListCustomersByOwner(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	fields, err := getQueryFields(r, customerSummaryFields)
+	if err != nil {
+		respondError(w, err)
+		return
+	}
+
 	ownerID, err := getUUIDParam(r, "owner_id")
 	if err != nil {
 		respondError(w, err)
@@ -344,6 +461,7 @@ func (h *Handler) ListCustomersByOwner(w http.ResponseWriter, r *http.Request) {
 		OwnerID:  ownerID,
 		Offset:   getQueryInt(r, "offset", 0),
 		Limit:    getQueryInt(r, "limit", 20),
+		Cursor:   getQueryString(r, "cursor"),
 	}
 
 	result, err := h.listByOwner.Execute(ctx, input)
@@ -352,14 +470,21 @@ func (h *Handler) ListCustomersByOwner(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	data, err := fields.Apply(result.Customers)
+	if err != nil {
+		respondError(w, err)
+		return
+	}
+
 	respondJSON(w, http.StatusOK, APIResponse{
 		Success: true,
-		Data:    result.Customers,
+		Data:    data,
 		Meta: &MetaResponse{
-			Total:   result.Total,
-			Offset:  result.Offset,
-			Limit:   result.Limit,
-			HasMore: result.HasMore,
+			Total:      result.Total,
+			Offset:     result.Offset,
+			Limit:      result.Limit,
+			HasMore:    result.HasMore,
+			NextCursor: result.NextCursor,
 		},
 	})
 }
@@ -374,6 +499,12 @@ func (h *Handler) ListCustomersByStatus(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
+	fields, err := getQueryFields(r, customerSummaryFields)
+	if err != nil {
+		respondError(w, err)
+		return
+	}
+
 	statusParam := getQueryString(r, "status")
 	if statusParam == "" {
 		respondError(w, ErrMissingParameter("status"))
@@ -385,6 +516,7 @@ func (h *Handler) ListCustomersByStatus(w http.ResponseWriter, r *http.Request)
 		Status:   domain.CustomerStatus(statusParam),
 		Offset:   getQueryInt(r, "offset", 0),
 		Limit:    getQueryInt(r, "limit", 20),
+		Cursor:   getQueryString(r, "cursor"),
 	}
 
 	result, err := h.listByStatus.Execute(ctx, input)
@@ -393,14 +525,21 @@ func (h *Handler) ListCustomersByStatus(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
+	data, err := fields.Apply(result.Customers)
+	if err != nil {
+		respondError(w, err)
+		return
+	}
+
 	respondJSON(w, http.StatusOK, APIResponse{
 		Success: true,
-		Data:    result.Customers,
+		Data:    data,
 		Meta: &MetaResponse{
-			Total:   result.Total,
-			Offset:  result.Offset,
-			Limit:   result.Limit,
-			HasMore: result.HasMore,
+			Total:      result.Total,
+			Offset:     result.Offset,
+			Limit:      result.Limit,
+			HasMore:    result.HasMore,
+			NextCursor: result.NextCursor,
 		},
 	})
 }
@@ -415,6 +554,12 @@ func (h *Handler) ListCustomersByTag(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	fields, err := getQueryFields(r, customerSummaryFields)
+	if err != nil {
+		respondError(w, err)
+		return
+	}
+
 	tag := getQueryString(r, "tag")
 	if tag == "" {
 		respondError(w, ErrMissingParameter("tag"))
@@ -426,6 +571,7 @@ func (h *Handler) ListCustomersByTag(w http.ResponseWriter, r *http.Request) {
 		Tag:      tag,
 		Offset:   getQueryInt(r, "offset", 0),
 		Limit:    getQueryInt(r, "limit", 20),
+		Cursor:   getQueryString(r, "cursor"),
 	}
 
 	result, err := h.listByTag.Execute(ctx, input)
@@ -434,14 +580,21 @@ func (h *Handler) ListCustomersByTag(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	data, err := fields.Apply(result.Customers)
+	if err != nil {
+		respondError(w, err)
+		return
+	}
+
 	respondJSON(w, http.StatusOK, APIResponse{
 		Success: true,
-		Data:    result.Customers,
+		Data:    data,
 		Meta: &MetaResponse{
-			Total:   result.Total,
-			Offset:  result.Offset,
-			Limit:   result.Limit,
-			HasMore: result.HasMore,
+			Total:      result.Total,
+			Offset:     result.Offset,
+			Limit:      result.Limit,
+			HasMore:    result.HasMore,
+			NextCursor: result.NextCursor,
 		},
 	})
 }
@@ -782,6 +935,50 @@ func (h *Handler) UnblockCustomer(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
+// SetCustomerLifecycle handles PATCH /api/v1/customers/{id}/lifecycle
+func (h *Handler) SetCustomerLifecycle(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	tenantID := getTenantID(ctx)
+	userID := getUserID(ctx)
+
+	if tenantID == uuid.Nil {
+		respondError(w, ErrUnauthorized("tenant_id is required"))
+		return
+	}
+
+	customerID, err := getUUIDParam(r, "customerId")
+	if err != nil {
+		respondError(w, err)
+		return
+	}
+
+	var req dto.SetLifecycleRequest
+	if err := decodeJSON(r, &req); err != nil {
+		respondError(w, err)
+		return
+	}
+
+	input := usecase.SetLifecycleInput{
+		TenantID:   tenantID,
+		UserID:     userID,
+		CustomerID: customerID,
+		Request:    &req,
+		IPAddress:  getClientIP(r),
+		UserAgent:  getUserAgent(r),
+	}
+
+	customer, err := h.customerLifecycle.Set(ctx, input)
+	if err != nil {
+		respondError(w, err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, APIResponse{
+		Success: true,
+		Data:    customer,
+	})
+}
+
 // AddToSegment handles POST /api/v1/customers/{customerId}/segments/{segmentId}
 func (h *Handler) AddToSegment(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
@@ -0,0 +1,99 @@
+// Package http provides HTTP handlers for the Customer service.
+package http
+
+import (
+	"net/http"
+
+	"github.com/google/uuid"
+
+	"github.com/kilang-desa-murni/crm/internal/customer/application/dto"
+	"github.com/kilang-desa-murni/crm/internal/customer/application/usecase"
+)
+
+// ============================================================================
+// Customer Erasure Handlers
+// ============================================================================
+
+// RequestCustomerErasure handles POST /api/v1/customers/{customerId}/erasure-requests
+func (h *Handler) RequestCustomerErasure(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	tenantID := getTenantID(ctx)
+	userID := getUserID(ctx)
+
+	if tenantID == uuid.Nil {
+		respondError(w, ErrUnauthorized("tenant_id is required"))
+		return
+	}
+
+	customerID, err := getUUIDParam(r, "customerId")
+	if err != nil {
+		respondError(w, err)
+		return
+	}
+
+	var req dto.RequestErasureRequest
+	if err := decodeJSON(r, &req); err != nil {
+		respondError(w, err)
+		return
+	}
+
+	input := usecase.RequestErasureInput{
+		TenantID:   tenantID,
+		CustomerID: customerID,
+		Reason:     req.Reason,
+		IPAddress:  getClientIP(r),
+		UserAgent:  getUserAgent(r),
+	}
+	if userID != uuid.Nil {
+		input.RequestedBy = &userID
+	}
+
+	erasure, err := h.customerErasure.Request(ctx, input)
+	if err != nil {
+		respondError(w, err)
+		return
+	}
+
+	// The other services erase their data asynchronously
+	respondJSON(w, http.StatusAccepted, APIResponse{
+		Success: true,
+		Data:    erasure,
+	})
+}
+
+// GetCustomerErasure handles GET /api/v1/customers/{customerId}/erasure-requests/{erasureId}
+func (h *Handler) GetCustomerErasure(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	tenantID := getTenantID(ctx)
+
+	if tenantID == uuid.Nil {
+		respondError(w, ErrUnauthorized("tenant_id is required"))
+		return
+	}
+
+	customerID, err := getUUIDParam(r, "customerId")
+	if err != nil {
+		respondError(w, err)
+		return
+	}
+	erasureID, err := getUUIDParam(r, "erasureId")
+	if err != nil {
+		respondError(w, err)
+		return
+	}
+
+	erasure, err := h.customerErasure.Get(ctx, usecase.GetErasureInput{
+		TenantID:   tenantID,
+		CustomerID: customerID,
+		ErasureID:  erasureID,
+	})
+	if err != nil {
+		respondError(w, err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, APIResponse{
+		Success: true,
+		Data:    erasure,
+	})
+}
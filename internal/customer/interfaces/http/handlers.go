@@ -15,6 +15,7 @@ import (
 	"github.com/kilang-desa-murni/crm/internal/customer/application/dto"
 	"github.com/kilang-desa-murni/crm/internal/customer/application/usecase"
 	"github.com/kilang-desa-murni/crm/internal/customer/domain"
+	"github.com/kilang-desa-murni/crm/pkg/response"
 )
 
 // Handler holds all HTTP handlers for the Customer service.
@@ -25,6 +26,7 @@ type Handler struct {
 	deleteCustomer       *usecase.DeleteCustomerUseCase
 	bulkDeleteCustomers  *usecase.BulkDeleteCustomersUseCase
 	getCustomer          *usecase.GetCustomerUseCase
+	batchGetCustomers    *usecase.BatchGetCustomersUseCase
 	getCustomerByCode    *usecase.GetCustomerByCodeUseCase
 	searchCustomers      *usecase.SearchCustomersUseCase
 	listCustomers        *usecase.ListCustomersUseCase
@@ -41,6 +43,8 @@ type Handler struct {
 	deactivateCustomer   *usecase.DeactivateCustomerUseCase
 	blockCustomer        *usecase.BlockCustomerUseCase
 	unblockCustomer      *usecase.UnblockCustomerUseCase
+	customerLifecycle    *usecase.CustomerLifecycleUseCase
+	mergeCustomers       *usecase.MergeCustomersUseCase
 
 	// Contact use cases
 	addContact           *usecase.AddContactUseCase
@@ -51,6 +55,15 @@ type Handler struct {
 	setPrimaryContact    *usecase.SetPrimaryContactUseCase
 	searchContacts       *usecase.SearchContactsUseCase
 
+	// Address use cases
+	customerAddresses    *usecase.CustomerAddressUseCase
+
+	// Loyalty use cases
+	customerLoyalty      *usecase.CustomerLoyaltyUseCase
+
+	// Erasure use cases
+	customerErasure      *usecase.CustomerErasureUseCase
+
 	// Note use cases
 	addNote              *usecase.AddNoteUseCase
 	getNote              *usecase.GetNoteUseCase
@@ -81,6 +94,9 @@ type Handler struct {
 	getImportErrors      *usecase.GetImportErrorsUseCase
 	listImports          *usecase.ListImportsUseCase
 	cancelImport         *usecase.CancelImportUseCase
+
+	// Import upload use cases
+	importUploads        *usecase.ImportUploadUseCase
 }
 
 // NewHandler is now defined in routes.go using HandlerDependencies pattern.
@@ -153,6 +169,21 @@ func getQueryStringSlice(r *http.Request, name string) []string {
 	return strings.Split(value, ",")
 }
 
+// Fields selectable with ?fields= on customer responses.
+var (
+	customerFields        = response.FieldsOf(dto.CustomerResponse{})
+	customerSummaryFields = response.FieldsOf(dto.CustomerSummaryResponse{})
+)
+
+// getQueryFields parses the ?fields= sparse fieldset against the allowed fields.
+func getQueryFields(r *http.Request, allowed response.Fields) (response.Fields, error) {
+	fields, err := response.ParseFields(r.URL.Query().Get("fields"), allowed)
+	if err != nil {
+		return nil, ErrInvalidParameter("fields", err.Error())
+	}
+	return fields, nil
+}
+
 // getQueryUUID extracts a UUID query parameter.
 func getQueryUUID(r *http.Request, name string) *uuid.UUID {
 	value := r.URL.Query().Get(name)
@@ -258,10 +289,11 @@ type APIResponse struct {
 
 // MetaResponse contains pagination metadata.
 type MetaResponse struct {
-	Total   int64  `json:"total"`
-	Offset  int    `json:"offset"`
-	Limit   int    `json:"limit"`
-	HasMore bool   `json:"has_more"`
+	Total      int64  `json:"total"`
+	Offset     int    `json:"offset"`
+	Limit      int    `json:"limit"`
+	HasMore    bool   `json:"has_more"`
+	NextCursor string `json:"next_cursor,omitempty"`
 }
 
 // Note: Health check endpoints are defined in routes.go
@@ -276,8 +308,11 @@ func buildSearchRequest(r *http.Request) *dto.SearchCustomersRequest {
 		Query:     getQueryString(r, "q"),
 		Offset:    getQueryInt(r, "offset", 0),
 		Limit:     getQueryInt(r, "limit", 20),
+		Cursor:    getQueryString(r, "cursor"),
 		SortBy:    getQueryString(r, "sort_by"),
 		SortOrder: getQueryString(r, "sort_order"),
+		Filter:    getQueryString(r, "filter"),
+		Sort:      getQueryString(r, "sort"),
 	}
 
 	// Types
@@ -294,6 +329,11 @@ func buildSearchRequest(r *http.Request) *dto.SearchCustomersRequest {
 		}
 	}
 
+	// Lifecycles
+	for _, l := range getQueryStringSlice(r, "lifecycles") {
+		req.Lifecycles = append(req.Lifecycles, domain.CustomerLifecycle(l))
+	}
+
 	// Tiers
 	if tiers := getQueryStringSlice(r, "tiers"); len(tiers) > 0 {
 		for _, t := range tiers {
@@ -304,6 +344,11 @@ func buildSearchRequest(r *http.Request) *dto.SearchCustomersRequest {
 	// Tags
 	req.Tags = getQueryStringSlice(r, "tags")
 
+	// Location
+	req.Countries = getQueryStringSlice(r, "countries")
+	req.States = getQueryStringSlice(r, "states")
+	req.Cities = getQueryStringSlice(r, "cities")
+
 	// Owner IDs
 	if ownerIDs := getQueryStringSlice(r, "owner_ids"); len(ownerIDs) > 0 {
 		for _, id := range ownerIDs {
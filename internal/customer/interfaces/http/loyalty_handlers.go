@@ -0,0 +1,91 @@
+// Package http provides HTTP handlers for the Customer service.
+package http
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/kilang-desa-murni/crm/internal/customer/application/usecase"
+	"github.com/kilang-desa-murni/crm/internal/customer/domain"
+)
+
+// ============================================================================
+// Customer Loyalty Handlers
+// ============================================================================
+
+// GetCustomerPurchaseHistory handles GET /api/v1/customers/{customerId}/purchases
+func (h *Handler) GetCustomerPurchaseHistory(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	tenantID := getTenantID(ctx)
+
+	customerID, err := getUUIDParam(r, "customerId")
+	if err != nil {
+		respondError(w, err)
+		return
+	}
+
+	history, err := h.customerLoyalty.GetPurchaseHistory(ctx, usecase.GetPurchaseHistoryInput{
+		TenantID:   tenantID,
+		CustomerID: customerID,
+		Limit:      getQueryInt(r, "limit", 0),
+	})
+	if err != nil {
+		respondError(w, err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, APIResponse{
+		Success: true,
+		Data:    history,
+	})
+}
+
+// GetTopCustomers handles GET /api/v1/customers/top
+func (h *Handler) GetTopCustomers(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	from, err := parseQueryTime(r, "from")
+	if err != nil {
+		respondError(w, err)
+		return
+	}
+	to, err := parseQueryTime(r, "to")
+	if err != nil {
+		respondError(w, err)
+		return
+	}
+
+	input := usecase.TopCustomersInput{
+		TenantID: getTenantID(ctx),
+		From:     from,
+		To:       to,
+		Currency: domain.Currency(getQueryString(r, "currency")),
+		Limit:    getQueryInt(r, "limit", 0),
+	}
+
+	top, err := h.customerLoyalty.TopCustomers(ctx, input)
+	if err != nil {
+		respondError(w, err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, APIResponse{
+		Success: true,
+		Data:    top,
+	})
+}
+
+// parseQueryTime parses an optional RFC 3339 time query parameter. Unlike
+// getQueryTime it rejects malformed values, since ignoring a period bound
+// would silently widen the period.
+func parseQueryTime(r *http.Request, name string) (*time.Time, error) {
+	value := getQueryString(r, name)
+	if value == "" {
+		return nil, nil
+	}
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return nil, ErrInvalidParameter(name, "must be an RFC 3339 timestamp")
+	}
+	return &t, nil
+}
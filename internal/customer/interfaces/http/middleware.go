@@ -15,6 +15,8 @@ import (
 	"github.com/google/uuid"
 	"go.uber.org/zap"
 	"golang.org/x/time/rate"
+
+	pkgconfig "github.com/kilang-desa-murni/crm/pkg/config"
 )
 
 // ContextKey is a type for context keys used in middleware.
@@ -468,16 +470,20 @@ func MaxBodySize(maxBytes int64) func(http.Handler) http.Handler {
 // CORS Middleware
 // ============================================================================
 
-// CORS creates a CORS middleware.
+// CORS creates a CORS middleware. Listed origins, which may use subdomain
+// wildcards, are echoed back; "*" allows any origin, without credentials.
 func CORS(config *MiddlewareConfig) func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			origin := r.Header.Get("Origin")
+			w.Header().Add("Vary", "Origin")
 
 			// Check if origin is allowed
-			allowed := false
+			allowed, anyOrigin := false, false
 			for _, o := range config.AllowedOrigins {
-				if o == "*" || o == origin {
+				if o == "*" {
+					anyOrigin = true
+				} else if pkgconfig.MatchOrigin([]string{o}, origin) {
 					allowed = true
 					break
 				}
@@ -485,10 +491,11 @@ func CORS(config *MiddlewareConfig) func(http.Handler) http.Handler {
 
 			if allowed {
 				w.Header().Set("Access-Control-Allow-Origin", origin)
-			}
-
-			if config.AllowCredentials {
-				w.Header().Set("Access-Control-Allow-Credentials", "true")
+				if config.AllowCredentials {
+					w.Header().Set("Access-Control-Allow-Credentials", "true")
+				}
+			} else if anyOrigin && origin != "" {
+				w.Header().Set("Access-Control-Allow-Origin", "*")
 			}
 
 			// Handle preflight requests
@@ -11,6 +11,7 @@ import (
 	"go.uber.org/zap"
 
 	"github.com/kilang-desa-murni/crm/internal/customer/application/usecase"
+	"github.com/kilang-desa-murni/crm/pkg/etag"
 )
 
 // RouterConfig contains configuration for the router.
@@ -145,17 +146,22 @@ func (r *Router) setupRoutes() {
 		router.Use(Authenticator(r.config.AuthConfig))
 		router.Use(RequireAuth)
 
-		// Require JSON content type for POST/PUT/PATCH
-		router.Use(RequireContentType("application/json"))
+		router.Group(func(router chi.Router) {
+			// Require JSON content type for POST/PUT/PATCH
+			router.Use(RequireContentType("application/json"))
 
-		// Customer routes
-		router.Route("/customers", r.customerRoutes)
+			// Customer routes
+			router.Route("/customers", r.customerRoutes)
 
-		// Segment routes
-		router.Route("/segments", r.segmentRoutes)
+			// Segment routes
+			router.Route("/segments", r.segmentRoutes)
 
-		// Import routes
-		router.Route("/imports", r.importRoutes)
+			// Import routes
+			router.Route("/imports", r.importRoutes)
+		})
+
+		// Import upload routes; chunks are raw bytes rather than JSON
+		router.Route("/import-uploads", r.importUploadRoutes)
 	})
 
 	// 404 handler
@@ -171,12 +177,17 @@ func (r *Router) customerRoutes(router chi.Router) {
 	router.Post("/", r.handler.CreateCustomer)
 	router.Get("/", r.handler.SearchCustomers)
 	router.Get("/export", r.handler.ExportCustomers)
+	router.Post("/batch-get", r.handler.BatchGetCustomers)
+	router.Post("/merge", r.handler.MergeCustomers)
+	router.Get("/top", r.handler.GetTopCustomers)
 
 	// Single customer operations
 	router.Route("/{customerId}", func(router chi.Router) {
-		router.Get("/", r.handler.GetCustomer)
-		router.Put("/", r.handler.UpdateCustomer)
-		router.Delete("/", r.handler.DeleteCustomer)
+		// Reads are tagged with an ETag; writes require If-Match
+		conditional := router.With(etag.Resource(http.HandlerFunc(r.handler.GetCustomer)))
+		conditional.Get("/", r.handler.GetCustomer)
+		conditional.Put("/", r.handler.UpdateCustomer)
+		conditional.Delete("/", r.handler.DeleteCustomer)
 
 		// Customer actions
 		router.Post("/restore", r.handler.RestoreCustomer)
@@ -184,10 +195,17 @@ func (r *Router) customerRoutes(router chi.Router) {
 		router.Post("/deactivate", r.handler.DeactivateCustomer)
 		router.Post("/block", r.handler.BlockCustomer)
 		router.Post("/unblock", r.handler.UnblockCustomer)
+		router.Patch("/lifecycle", r.handler.SetCustomerLifecycle)
+		router.Get("/purchases", r.handler.GetCustomerPurchaseHistory)
+		router.Post("/erasure-requests", r.handler.RequestCustomerErasure)
+		router.Get("/erasure-requests/{erasureId}", r.handler.GetCustomerErasure)
 
 		// Contact routes (nested under customer)
 		router.Route("/contacts", r.contactRoutes)
 
+		// Address book
+		router.Route("/addresses", r.addressRoutes)
+
 		// Note routes
 		router.Route("/notes", r.noteRoutes)
 
@@ -213,6 +231,14 @@ func (r *Router) contactRoutes(router chi.Router) {
 	})
 }
 
+// addressRoutes sets up customer address routes.
+func (r *Router) addressRoutes(router chi.Router) {
+	router.Get("/", r.handler.ListCustomerAddresses)
+	router.Post("/", r.handler.AddCustomerAddress)
+	router.Put("/{addressId}", r.handler.UpdateCustomerAddress)
+	router.Delete("/{addressId}", r.handler.RemoveCustomerAddress)
+}
+
 // noteRoutes sets up note-related routes.
 func (r *Router) noteRoutes(router chi.Router) {
 	router.Post("/", r.handler.AddNote)
@@ -263,6 +289,19 @@ func (r *Router) importRoutes(router chi.Router) {
 	})
 }
 
+// importUploadRoutes sets up resumable import upload routes.
+func (r *Router) importUploadRoutes(router chi.Router) {
+	router.With(RequireContentType("application/json")).Post("/", r.handler.InitiateImportUpload)
+
+	router.Route("/{uploadId}", func(router chi.Router) {
+		router.Get("/", r.handler.GetImportUpload)
+		router.Head("/", r.handler.HeadImportUpload)
+		router.With(RequireContentType(uploadChunkContentType)).Patch("/", r.handler.AppendImportUpload)
+		router.Post("/complete", r.handler.CompleteImportUpload)
+		router.Delete("/", r.handler.AbortImportUpload)
+	})
+}
+
 // ============================================================================
 // Health and Status Handlers
 // ============================================================================
@@ -374,6 +413,7 @@ type HandlerDependencies struct {
 	// Customer use cases
 	CreateCustomer     *usecase.CreateCustomerUseCase
 	GetCustomer        *usecase.GetCustomerUseCase
+	BatchGetCustomers  *usecase.BatchGetCustomersUseCase
 	UpdateCustomer     *usecase.UpdateCustomerUseCase
 	DeleteCustomer     *usecase.DeleteCustomerUseCase
 	SearchCustomers    *usecase.SearchCustomersUseCase
@@ -384,6 +424,8 @@ type HandlerDependencies struct {
 	UnblockCustomer    *usecase.UnblockCustomerUseCase
 	ExportCustomers    *usecase.ExportCustomersUseCase
 	ImportCustomers    *usecase.ImportCustomersUseCase
+	CustomerLifecycle  *usecase.CustomerLifecycleUseCase
+	MergeCustomers     *usecase.MergeCustomersUseCase
 
 	// Contact use cases
 	AddContact        *usecase.AddContactUseCase
@@ -393,6 +435,15 @@ type HandlerDependencies struct {
 	ListContacts      *usecase.ListContactsUseCase
 	SetPrimaryContact *usecase.SetPrimaryContactUseCase
 
+	// Address use cases
+	CustomerAddresses *usecase.CustomerAddressUseCase
+
+	// Loyalty use cases
+	CustomerLoyalty *usecase.CustomerLoyaltyUseCase
+
+	// Erasure use cases
+	CustomerErasure *usecase.CustomerErasureUseCase
+
 	// Note use cases
 	AddNote    *usecase.AddNoteUseCase
 	GetNote    *usecase.GetNoteUseCase
@@ -423,6 +474,9 @@ type HandlerDependencies struct {
 	GetImportErrors *usecase.GetImportErrorsUseCase
 	ListImports     *usecase.ListImportsUseCase
 	CancelImport    *usecase.CancelImportUseCase
+
+	// Import upload use cases
+	ImportUploads *usecase.ImportUploadUseCase
 }
 
 // NewHandler creates a new handler with all dependencies.
@@ -430,6 +484,7 @@ func NewHandler(deps HandlerDependencies) *Handler {
 	return &Handler{
 		createCustomer:      deps.CreateCustomer,
 		getCustomer:         deps.GetCustomer,
+		batchGetCustomers:   deps.BatchGetCustomers,
 		updateCustomer:      deps.UpdateCustomer,
 		deleteCustomer:      deps.DeleteCustomer,
 		searchCustomers:     deps.SearchCustomers,
@@ -438,6 +493,8 @@ func NewHandler(deps HandlerDependencies) *Handler {
 		deactivateCustomer:  deps.DeactivateCustomer,
 		blockCustomer:       deps.BlockCustomer,
 		unblockCustomer:     deps.UnblockCustomer,
+		customerLifecycle:   deps.CustomerLifecycle,
+		mergeCustomers:      deps.MergeCustomers,
 		exportCustomers:     deps.ExportCustomers,
 		importCustomers:     deps.ImportCustomers,
 		addContact:          deps.AddContact,
@@ -446,6 +503,9 @@ func NewHandler(deps HandlerDependencies) *Handler {
 		deleteContact:       deps.DeleteContact,
 		listContacts:        deps.ListContacts,
 		setPrimaryContact:   deps.SetPrimaryContact,
+		customerAddresses:   deps.CustomerAddresses,
+		customerLoyalty:     deps.CustomerLoyalty,
+		customerErasure:     deps.CustomerErasure,
 		addNote:             deps.AddNote,
 		getNote:             deps.GetNote,
 		updateNote:          deps.UpdateNote,
@@ -469,5 +529,6 @@ func NewHandler(deps HandlerDependencies) *Handler {
 		getImportErrors:     deps.GetImportErrors,
 		listImports:         deps.ListImports,
 		cancelImport:        deps.CancelImport,
+		importUploads:       deps.ImportUploads,
 	}
 }
@@ -0,0 +1,277 @@
+// Package http provides HTTP handlers for the Customer service.
+package http
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/google/uuid"
+
+	"github.com/kilang-desa-murni/crm/internal/customer/application/dto"
+	"github.com/kilang-desa-murni/crm/internal/customer/application/usecase"
+	"github.com/kilang-desa-murni/crm/internal/customer/domain"
+)
+
+// Resumable upload headers. A chunk is sent with the offset it starts at and,
+// optionally, its checksum; the offset of the bytes received is returned so a
+// client can resume after a broken connection.
+const (
+	headerUploadOffset   = "Upload-Offset"
+	headerUploadLength   = "Upload-Length"
+	headerUploadChecksum = "Upload-Checksum" // "sha256 <base64 digest>"
+
+	uploadChunkContentType = "application/offset+octet-stream"
+)
+
+// ============================================================================
+// Import Upload Handlers
+// ============================================================================
+
+// InitiateImportUpload handles POST /api/v1/import-uploads
+func (h *Handler) InitiateImportUpload(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	tenantID := getTenantID(ctx)
+	userID := getUserID(ctx)
+
+	if tenantID == uuid.Nil {
+		respondError(w, ErrUnauthorized("tenant_id is required"))
+		return
+	}
+
+	var req dto.InitiateUploadRequest
+	if err := decodeJSON(r, &req); err != nil {
+		respondError(w, err)
+		return
+	}
+
+	upload, err := h.importUploads.Initiate(ctx, usecase.InitiateUploadInput{
+		TenantID: tenantID,
+		UserID:   userID,
+		FileName: req.FileName,
+		Format:   req.Format,
+		Size:     req.Size,
+		Checksum: req.Checksum,
+	})
+	if err != nil {
+		respondError(w, err)
+		return
+	}
+
+	w.Header().Set("Location", r.URL.Path+"/"+upload.ID.String())
+	setUploadHeaders(w, upload)
+	respondJSON(w, http.StatusCreated, APIResponse{
+		Success: true,
+		Data:    upload,
+	})
+}
+
+// GetImportUpload handles GET /api/v1/import-uploads/{uploadId}
+func (h *Handler) GetImportUpload(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	tenantID := getTenantID(ctx)
+
+	if tenantID == uuid.Nil {
+		respondError(w, ErrUnauthorized("tenant_id is required"))
+		return
+	}
+
+	uploadID, err := getUUIDParam(r, "uploadId")
+	if err != nil {
+		respondError(w, err)
+		return
+	}
+
+	upload, err := h.importUploads.Get(ctx, tenantID, uploadID)
+	if err != nil {
+		respondError(w, err)
+		return
+	}
+
+	setUploadHeaders(w, upload)
+	respondJSON(w, http.StatusOK, APIResponse{
+		Success: true,
+		Data:    upload,
+	})
+}
+
+// HeadImportUpload handles HEAD /api/v1/import-uploads/{uploadId}, returning
+// the offset to resume an upload from.
+func (h *Handler) HeadImportUpload(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	tenantID := getTenantID(ctx)
+
+	if tenantID == uuid.Nil {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	uploadID, err := getUUIDParam(r, "uploadId")
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	upload, err := h.importUploads.Get(ctx, tenantID, uploadID)
+	if err != nil {
+		// A HEAD response has no body
+		w.WriteHeader(toHTTPError(err).StatusCode)
+		return
+	}
+
+	setUploadHeaders(w, upload)
+	w.WriteHeader(http.StatusOK)
+}
+
+// AppendImportUpload handles PATCH /api/v1/import-uploads/{uploadId}
+func (h *Handler) AppendImportUpload(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	tenantID := getTenantID(ctx)
+
+	if tenantID == uuid.Nil {
+		respondError(w, ErrUnauthorized("tenant_id is required"))
+		return
+	}
+
+	uploadID, err := getUUIDParam(r, "uploadId")
+	if err != nil {
+		respondError(w, err)
+		return
+	}
+
+	offset, err := strconv.ParseInt(r.Header.Get(headerUploadOffset), 10, 64)
+	if err != nil || offset < 0 {
+		respondError(w, ErrBadRequest(headerUploadOffset+" header must be a non-negative integer"))
+		return
+	}
+
+	checksum, err := parseUploadChecksum(r.Header.Get(headerUploadChecksum))
+	if err != nil {
+		respondError(w, err)
+		return
+	}
+
+	// Read one byte past the limit so an oversized chunk is refused rather
+	// than truncated
+	data, err := io.ReadAll(io.LimitReader(r.Body, h.importUploads.MaxChunkSize()+1))
+	if err != nil {
+		respondError(w, ErrInvalidRequest("failed to read chunk"))
+		return
+	}
+
+	upload, err := h.importUploads.Append(ctx, usecase.AppendUploadInput{
+		TenantID: tenantID,
+		UploadID: uploadID,
+		Offset:   offset,
+		Data:     data,
+		Checksum: checksum,
+	})
+	if err != nil {
+		respondError(w, err)
+		return
+	}
+
+	setUploadHeaders(w, upload)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// CompleteImportUpload handles POST /api/v1/import-uploads/{uploadId}/complete
+func (h *Handler) CompleteImportUpload(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	tenantID := getTenantID(ctx)
+	userID := getUserID(ctx)
+
+	if tenantID == uuid.Nil {
+		respondError(w, ErrUnauthorized("tenant_id is required"))
+		return
+	}
+
+	uploadID, err := getUUIDParam(r, "uploadId")
+	if err != nil {
+		respondError(w, err)
+		return
+	}
+
+	var req dto.CompleteUploadRequest
+	if r.ContentLength != 0 {
+		if err := decodeJSON(r, &req); err != nil {
+			respondError(w, err)
+			return
+		}
+	}
+
+	result, err := h.importUploads.Complete(ctx, usecase.CompleteUploadInput{
+		TenantID:       tenantID,
+		UserID:         userID,
+		UploadID:       uploadID,
+		FieldMapping:   req.FieldMapping,
+		SkipDuplicates: req.SkipDuplicates,
+		UpdateExisting: req.UpdateExisting,
+		DefaultOwner:   req.DefaultOwner,
+		DefaultTags:    req.DefaultTags,
+		IPAddress:      getClientIP(r),
+		UserAgent:      getUserAgent(r),
+	})
+	if err != nil {
+		respondError(w, err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, APIResponse{
+		Success: true,
+		Data:    result,
+	})
+}
+
+// AbortImportUpload handles DELETE /api/v1/import-uploads/{uploadId}
+func (h *Handler) AbortImportUpload(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	tenantID := getTenantID(ctx)
+
+	if tenantID == uuid.Nil {
+		respondError(w, ErrUnauthorized("tenant_id is required"))
+		return
+	}
+
+	uploadID, err := getUUIDParam(r, "uploadId")
+	if err != nil {
+		respondError(w, err)
+		return
+	}
+
+	if err := h.importUploads.Abort(ctx, tenantID, uploadID); err != nil {
+		respondError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// setUploadHeaders sets the resumable upload headers of an upload.
+func setUploadHeaders(w http.ResponseWriter, upload *domain.ImportUpload) {
+	w.Header().Set(headerUploadOffset, strconv.FormatInt(upload.Offset, 10))
+	w.Header().Set(headerUploadLength, strconv.FormatInt(upload.Size, 10))
+	// The offset changes with every chunk
+	w.Header().Set("Cache-Control", "no-store")
+}
+
+// parseUploadChecksum parses an Upload-Checksum header into a hex SHA-256
+// digest. An empty header yields an empty digest.
+func parseUploadChecksum(header string) (string, error) {
+	if header == "" {
+		return "", nil
+	}
+
+	algorithm, digest, ok := strings.Cut(strings.TrimSpace(header), " ")
+	if !ok || !strings.EqualFold(algorithm, "sha256") {
+		return "", ErrBadRequest(headerUploadChecksum + " header must be \"sha256 <base64 digest>\"")
+	}
+	sum, err := base64.StdEncoding.DecodeString(strings.TrimSpace(digest))
+	if err != nil || len(sum) != 32 {
+		return "", ErrBadRequest(headerUploadChecksum + " header has an invalid sha256 digest")
+	}
+	return hex.EncodeToString(sum), nil
+}
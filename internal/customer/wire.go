@@ -6,6 +6,8 @@ package customer
 
 import (
 	"context"
+	"os"
+	"path/filepath"
 
 	"github.com/google/wire"
 	"go.mongodb.org/mongo-driver/mongo"
@@ -17,6 +19,7 @@ import (
 	"github.com/kilang-desa-murni/crm/internal/customer/infrastructure/cache"
 	"github.com/kilang-desa-murni/crm/internal/customer/infrastructure/messaging"
 	"github.com/kilang-desa-murni/crm/internal/customer/infrastructure/persistence/mongodb"
+	"github.com/kilang-desa-murni/crm/internal/customer/infrastructure/storage"
 	"github.com/kilang-desa-murni/crm/internal/customer/interfaces/http"
 )
 
@@ -26,6 +29,18 @@ type Config struct {
 	RabbitMQ   RabbitMQConfig
 	Redis      RedisConfig
 	HTTP       HTTPConfig
+	// Loyalty holds the loyalty tier thresholds. The default policy is
+	// used when no thresholds are configured.
+	Loyalty    domain.LoyaltyPolicy
+	// Erasure configures customer data erasure. Zero values take the
+	// defaults.
+	Erasure    usecase.ErasureConfig
+	// ImportUploads configures resumable import uploads. Zero values take
+	// the defaults.
+	ImportUploads usecase.UploadConfig
+	// UploadDir is where import uploads are stored. It must be shared by
+	// all service instances.
+	UploadDir  string
 }
 
 // MongoDBConfig contains MongoDB configuration.
@@ -72,19 +87,23 @@ var ProviderSet = wire.NewSet(
 	ProvideActivityRepository,
 	ProvideSegmentRepository,
 	ProvideImportRepository,
+	ProvideImportUploadRepository,
 	ProvideOutboxRepository,
 	ProvideUnitOfWork,
 
 	// Infrastructure
 	ProvideEventPublisher,
 	ProvideCacheService,
+	ProvideUploadStorage,
 
 	// Use cases - Customer
 	ProvideCreateCustomerUseCase,
 	ProvideGetCustomerUseCase,
+	ProvideBatchGetCustomersUseCase,
 	ProvideUpdateCustomerUseCase,
 	ProvideDeleteCustomerUseCase,
 	ProvideSearchCustomersUseCase,
+	ProvideMergeCustomersUseCase,
 
 	// Use cases - Contact
 	ProvideAddContactUseCase,
@@ -94,6 +113,28 @@ var ProviderSet = wire.NewSet(
 	ProvideListContactsUseCase,
 	ProvideSetPrimaryContactUseCase,
 
+	// Use cases - Address
+	ProvideCustomerAddressUseCase,
+
+	// Use cases - Lifecycle
+	ProvideCustomerLifecycleUseCase,
+
+	// Use cases - Health
+	ProvideCustomerHealthUseCase,
+
+	// Use cases - Loyalty
+	ProvideLoyaltyPolicy,
+	ProvideCustomerLoyaltyUseCase,
+
+	// Use cases - Erasure
+	ProvideCustomerErasureUseCase,
+
+	// Use cases - Import uploads
+	ProvideImportUploadUseCase,
+
+	// Use cases - Reassignment
+	ProvideOwnerReassignmentUseCase,
+
 	// HTTP
 	ProvideHandler,
 	ProvideRouter,
@@ -133,6 +174,11 @@ func ProvideImportRepository(db *mongo.Database) domain.ImportRepository {
 	return mongodb.NewImportRepository(db)
 }
 
+// ProvideImportUploadRepository provides an ImportUploadRepository.
+func ProvideImportUploadRepository(db *mongo.Database) domain.ImportUploadRepository {
+	return mongodb.NewImportUploadRepository(db)
+}
+
 // ProvideOutboxRepository provides an OutboxRepository.
 func ProvideOutboxRepository(db *mongo.Database) *mongodb.OutboxRepository {
 	return mongodb.NewOutboxRepository(db)
@@ -176,6 +222,15 @@ func ProvideCacheService(config RedisConfig, logger *zap.Logger) (ports.CacheSer
 	return cache.NewRedisCache(config.Addr, config.Password, config.DB, logger)
 }
 
+// ProvideUploadStorage provides the storage of import uploads.
+func ProvideUploadStorage(config *Config) (ports.UploadStorage, error) {
+	dir := config.UploadDir
+	if dir == "" {
+		dir = filepath.Join(os.TempDir(), "customer-import-uploads")
+	}
+	return storage.NewLocalUploadStorage(dir)
+}
+
 // ============================================================================
 // Use Case Providers - Customer
 // ============================================================================
@@ -199,6 +254,21 @@ func ProvideGetCustomerUseCase(
 	return usecase.NewGetCustomerUseCase(customerRepo, cacheService, logger)
 }
 
+// ProvideBatchGetCustomersUseCase provides a BatchGetCustomersUseCase.
+func ProvideBatchGetCustomersUseCase(uow domain.UnitOfWork) *usecase.BatchGetCustomersUseCase {
+	return usecase.NewBatchGetCustomersUseCase(uow)
+}
+
+// ProvideMergeCustomersUseCase provides a MergeCustomersUseCase.
+func ProvideMergeCustomersUseCase(
+	uow domain.UnitOfWork,
+	idGenerator ports.IDGenerator,
+	cacheService ports.CacheService,
+	auditLogger ports.AuditLogger,
+) *usecase.MergeCustomersUseCase {
+	return usecase.NewMergeCustomersUseCase(uow, idGenerator, cacheService, auditLogger)
+}
+
 // ProvideUpdateCustomerUseCase provides an UpdateCustomerUseCase.
 func ProvideUpdateCustomerUseCase(
 	customerRepo domain.CustomerRepository,
@@ -284,6 +354,117 @@ func ProvideSetPrimaryContactUseCase(
 	return usecase.NewSetPrimaryContactUseCase(contactRepo, eventPublisher, logger)
 }
 
+// ============================================================================
+// Use Case Providers - Address
+// ============================================================================
+
+// ProvideCustomerAddressUseCase provides a CustomerAddressUseCase. No
+// geocoder is configured yet, so addresses keep the coordinates they are
+// given.
+func ProvideCustomerAddressUseCase(
+	uow domain.UnitOfWork,
+	idGenerator ports.IDGenerator,
+	cacheService ports.CacheService,
+	auditLogger ports.AuditLogger,
+) *usecase.CustomerAddressUseCase {
+	return usecase.NewCustomerAddressUseCase(uow, idGenerator, cacheService, auditLogger, nil)
+}
+
+// ============================================================================
+// Use Case Providers - Lifecycle
+// ============================================================================
+
+// ProvideCustomerLifecycleUseCase provides a CustomerLifecycleUseCase.
+func ProvideCustomerLifecycleUseCase(
+	uow domain.UnitOfWork,
+	idGenerator ports.IDGenerator,
+	cacheService ports.CacheService,
+	auditLogger ports.AuditLogger,
+) *usecase.CustomerLifecycleUseCase {
+	return usecase.NewCustomerLifecycleUseCase(uow, idGenerator, cacheService, auditLogger)
+}
+
+// ============================================================================
+// Use Case Providers - Health
+// ============================================================================
+
+// ProvideCustomerHealthUseCase provides a CustomerHealthUseCase.
+func ProvideCustomerHealthUseCase(
+	uow domain.UnitOfWork,
+	idGenerator ports.IDGenerator,
+	cacheService ports.CacheService,
+) *usecase.CustomerHealthUseCase {
+	return usecase.NewCustomerHealthUseCase(uow, idGenerator, cacheService)
+}
+
+// ============================================================================
+// Use Case Providers - Loyalty
+// ============================================================================
+
+// ProvideLoyaltyPolicy provides the configured loyalty policy.
+func ProvideLoyaltyPolicy(config *Config) domain.LoyaltyPolicy {
+	if len(config.Loyalty.Thresholds) == 0 {
+		return domain.DefaultLoyaltyPolicy()
+	}
+	return config.Loyalty
+}
+
+// ProvideCustomerLoyaltyUseCase provides a CustomerLoyaltyUseCase.
+func ProvideCustomerLoyaltyUseCase(
+	uow domain.UnitOfWork,
+	idGenerator ports.IDGenerator,
+	cacheService ports.CacheService,
+	policy domain.LoyaltyPolicy,
+) (*usecase.CustomerLoyaltyUseCase, error) {
+	return usecase.NewCustomerLoyaltyUseCase(uow, idGenerator, cacheService, policy)
+}
+
+// ============================================================================
+// Use Case Providers - Erasure
+// ============================================================================
+
+// ProvideCustomerErasureUseCase provides a CustomerErasureUseCase.
+func ProvideCustomerErasureUseCase(
+	uow domain.UnitOfWork,
+	idGenerator ports.IDGenerator,
+	cacheService ports.CacheService,
+	auditLogger ports.AuditLogger,
+	config *Config,
+) *usecase.CustomerErasureUseCase {
+	erasure := config.Erasure
+	if len(erasure.Services) == 0 {
+		erasure.Services = usecase.DefaultErasureConfig().Services
+	}
+	return usecase.NewCustomerErasureUseCase(uow, idGenerator, cacheService, auditLogger, erasure)
+}
+
+// ============================================================================
+// Use Case Providers - Import Uploads
+// ============================================================================
+
+// ProvideImportUploadUseCase provides an ImportUploadUseCase.
+func ProvideImportUploadUseCase(
+	uploads domain.ImportUploadRepository,
+	uploadStorage ports.UploadStorage,
+	importer *usecase.ImportCustomersUseCase,
+	config *Config,
+) *usecase.ImportUploadUseCase {
+	return usecase.NewImportUploadUseCase(uploads, uploadStorage, importer, config.ImportUploads)
+}
+
+// ============================================================================
+// Use Case Providers - Reassignment
+// ============================================================================
+
+// ProvideOwnerReassignmentUseCase provides an OwnerReassignmentUseCase.
+func ProvideOwnerReassignmentUseCase(
+	uow domain.UnitOfWork,
+	idGenerator ports.IDGenerator,
+	cacheService ports.CacheService,
+) *usecase.OwnerReassignmentUseCase {
+	return usecase.NewOwnerReassignmentUseCase(uow, idGenerator, cacheService)
+}
+
 // ============================================================================
 // HTTP Providers
 // ============================================================================
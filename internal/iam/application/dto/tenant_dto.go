@@ -63,6 +63,21 @@ type UpdateTenantSettingsRequest struct {
 	Currency           *string `json:"currency" validate:"omitempty,len=3"`
 	Language           *string `json:"language" validate:"omitempty,len=2"`
 	NotificationsEmail *bool   `json:"notifications_email"`
+	// AllowedOrigins replaces the browser origins allowed for the tenant's
+	// users when set.
+	AllowedOrigins *[]string `json:"allowed_origins" validate:"omitempty,max=20"`
+	// CustomDomain sets the host name the tenant's users reach the CRM on
+	// when set; an empty string removes it.
+	CustomDomain *string `json:"custom_domain" validate:"omitempty,max=253"`
+}
+
+// UpdateTenantBrandingRequest replaces a tenant's branding.
+type UpdateTenantBrandingRequest struct {
+	LogoURL          string `json:"logo_url" validate:"omitempty,url,max=500"`
+	PrimaryColor     string `json:"primary_color" validate:"omitempty,len=7"`
+	SecondaryColor   string `json:"secondary_color" validate:"omitempty,len=7"`
+	EmailFromAddress string `json:"email_from_address" validate:"omitempty,email,max=255"`
+	EmailFromName    string `json:"email_from_name" validate:"omitempty,max=100"`
 }
 
 // UpdateTenantPlanRequest represents a plan change request.
@@ -126,11 +141,43 @@ type TenantDTO struct {
 
 // TenantSettingsDTO represents tenant settings.
 type TenantSettingsDTO struct {
-	Timezone           string `json:"timezone"`
-	DateFormat         string `json:"date_format"`
-	Currency           string `json:"currency"`
-	Language           string `json:"language"`
-	NotificationsEmail bool   `json:"notifications_email"`
+	Timezone           string   `json:"timezone"`
+	DateFormat         string   `json:"date_format"`
+	Currency           string   `json:"currency"`
+	Language           string   `json:"language"`
+	NotificationsEmail bool     `json:"notifications_email"`
+	AllowedOrigins     []string `json:"allowed_origins,omitempty"`
+	CustomDomain       string   `json:"custom_domain,omitempty"`
+	Branding           *TenantBrandingDTO `json:"branding,omitempty"`
+}
+
+// TenantBrandingDTO represents how a white-labeled tenant presents the CRM.
+type TenantBrandingDTO struct {
+	LogoURL          string `json:"logo_url,omitempty"`
+	PrimaryColor     string `json:"primary_color,omitempty"`
+	SecondaryColor   string `json:"secondary_color,omitempty"`
+	EmailFromAddress string `json:"email_from_address,omitempty"`
+	EmailFromName    string `json:"email_from_name,omitempty"`
+}
+
+// TenantBrandingResponse represents a tenant's branding and custom domain.
+type TenantBrandingResponse struct {
+	TenantID     uuid.UUID          `json:"tenant_id"`
+	Name         string             `json:"name"`
+	CustomDomain string             `json:"custom_domain,omitempty"`
+	Branding     *TenantBrandingDTO `json:"branding,omitempty"`
+}
+
+// TenantDomainsResponse maps the custom domains of active tenants to the
+// tenants and their branding.
+type TenantDomainsResponse struct {
+	Domains map[string]*TenantBrandingResponse `json:"domains"`
+}
+
+// TenantOriginsResponse maps the browser origins tenants allow for their
+// users to the tenants allowing them.
+type TenantOriginsResponse struct {
+	Origins map[string][]uuid.UUID `json:"origins"`
 }
 
 // TenantLimitsDTO represents tenant plan limits.
@@ -161,3 +208,62 @@ type TenantStatsDTO struct {
 	PendingTenants int64 `json:"pending_tenants"`
 	PlanBreakdown  map[string]int64 `json:"plan_breakdown"`
 }
+
+// ============================================================================
+// Tenant Export DTOs
+// ============================================================================
+
+// TenantExportDTO represents a tenant data export.
+type TenantExportDTO struct {
+	ID          uuid.UUID              `json:"id"`
+	TenantID    uuid.UUID              `json:"tenant_id"`
+	RequestedBy uuid.UUID              `json:"requested_by"`
+	Status      string                 `json:"status"`
+	Progress    int                    `json:"progress"`
+	Files       []*TenantExportFileDTO `json:"files"`
+	Error       string                 `json:"error,omitempty"`
+	CreatedAt   time.Time              `json:"created_at"`
+	StartedAt   *time.Time             `json:"started_at,omitempty"`
+	CompletedAt *time.Time             `json:"completed_at,omitempty"`
+	ExpiresAt   *time.Time             `json:"expires_at,omitempty"`
+}
+
+// TenantExportFileDTO represents one data set file of a tenant export.
+// DownloadURL is only set on completed exports and expires after a while;
+// fetch the export again for a fresh one.
+type TenantExportFileDTO struct {
+	Dataset     string     `json:"dataset"`
+	Service     string     `json:"service"`
+	Rows        int64      `json:"rows"`
+	SizeBytes   int64      `json:"size_bytes"`
+	CompletedAt *time.Time `json:"completed_at,omitempty"`
+	DownloadURL string     `json:"download_url,omitempty"`
+}
+
+// ListTenantExportsResponse represents a list tenant exports response.
+type ListTenantExportsResponse struct {
+	Exports []*TenantExportDTO `json:"exports"`
+}
+
+// ============================================================================
+// Tenant Demo Data DTOs
+// ============================================================================
+
+// TenantDemoDataDTO represents the outcome of seeding or purging a tenant's
+// demo data, in total and per service.
+type TenantDemoDataDTO struct {
+	TenantID uuid.UUID                   `json:"tenant_id"`
+	Created  int64                       `json:"created"`
+	Existing int64                       `json:"existing"`
+	Deleted  int64                       `json:"deleted"`
+	Services []*TenantDemoDataServiceDTO `json:"services"`
+}
+
+// TenantDemoDataServiceDTO represents the outcome of seeding or purging a
+// tenant's demo data in one service.
+type TenantDemoDataServiceDTO struct {
+	Service  string `json:"service"`
+	Created  int64  `json:"created"`
+	Existing int64  `json:"existing"`
+	Deleted  int64  `json:"deleted"`
+}
@@ -35,6 +35,9 @@ type LoginRequest struct {
 	Email      string `json:"email" validate:"required,email,max=255"`
 	Password   string `json:"password" validate:"required"`
 	DeviceInfo *DeviceInfoDTO `json:"device_info,omitempty"`
+	// Country is the client's ISO country code, set from the gateway's
+	// X-Client-Country header rather than by the client.
+	Country string `json:"-"`
 }
 
 // LoginResponse represents a login response.
@@ -131,6 +134,22 @@ type ListUsersResponse struct {
 	Pagination *PaginationDTO `json:"pagination"`
 }
 
+// MaxBatchGetUsers is the maximum number of IDs in a batch get request.
+const MaxBatchGetUsers = 500
+
+// BatchGetUsersRequest represents a request for several users by ID.
+type BatchGetUsersRequest struct {
+	TenantID uuid.UUID   `json:"-"`
+	IDs      []uuid.UUID `json:"ids" validate:"required,min=1,max=500"`
+}
+
+// BatchGetUsersResponse represents the users found for a batch get request
+// and the requested IDs that matched no user in the tenant.
+type BatchGetUsersResponse struct {
+	Found   []*UserDTO  `json:"found"`
+	Missing []uuid.UUID `json:"missing"`
+}
+
 // UserDTO represents a user data transfer object.
 type UserDTO struct {
 	ID              uuid.UUID   `json:"id"`
@@ -142,8 +161,10 @@ type UserDTO struct {
 	AvatarURL       string      `json:"avatar_url,omitempty"`
 	Phone           string      `json:"phone,omitempty"`
 	Status          string      `json:"status"`
+	ManagerID       *uuid.UUID  `json:"manager_id,omitempty"`
 	EmailVerifiedAt *time.Time  `json:"email_verified_at,omitempty"`
 	LastLoginAt     *time.Time  `json:"last_login_at,omitempty"`
+	DeactivatedAt   *time.Time  `json:"deactivated_at,omitempty"`
 	Roles           []*RoleDTO  `json:"roles,omitempty"`
 	Permissions     []string    `json:"permissions,omitempty"`
 	CreatedAt       time.Time   `json:"created_at"`
@@ -187,3 +208,137 @@ func NewPaginationDTO(page, pageSize int, totalItems int64) *PaginationDTO {
 		HasPrev:    page > 1,
 	}
 }
+
+// ============================================================================
+// User Reassignment DTOs
+// ============================================================================
+
+// ReassignUserRecordsRequest represents a request to transfer a user's open
+// leads, opportunities and customers to other users. With the round_robin
+// strategy the records are handed to the recipients in turn; with mapping
+// each recipient lists the data sets they take over.
+type ReassignUserRecordsRequest struct {
+	Strategy   string                     `json:"strategy" validate:"required,oneof=round_robin mapping"`
+	Recipients []ReassignRecipientRequest `json:"recipients" validate:"required,min=1,dive"`
+}
+
+// ReassignRecipientRequest represents a user receiving reassigned records.
+type ReassignRecipientRequest struct {
+	UserID   uuid.UUID `json:"user_id" validate:"required"`
+	Datasets []string  `json:"datasets,omitempty" validate:"dive,oneof=leads opportunities customers"`
+}
+
+// UserReassignmentDTO represents a reassignment of a user's records.
+type UserReassignmentDTO struct {
+	ID          uuid.UUID                   `json:"id"`
+	TenantID    uuid.UUID                   `json:"tenant_id"`
+	UserID      uuid.UUID                   `json:"user_id"`
+	RequestedBy uuid.UUID                   `json:"requested_by"`
+	Strategy    string                      `json:"strategy"`
+	Status      string                      `json:"status"`
+	Progress    int                         `json:"progress"`
+	Transferred int64                       `json:"transferred"`
+	Recipients  []*ReassignmentRecipientDTO `json:"recipients"`
+	Datasets    []*ReassignmentDatasetDTO   `json:"datasets"`
+	Error       string                      `json:"error,omitempty"`
+	CreatedAt   time.Time                   `json:"created_at"`
+	StartedAt   *time.Time                  `json:"started_at,omitempty"`
+	CompletedAt *time.Time                  `json:"completed_at,omitempty"`
+}
+
+// ReassignmentRecipientDTO represents a recipient of a user reassignment and
+// the records they received per data set.
+type ReassignmentRecipientDTO struct {
+	UserID   uuid.UUID        `json:"user_id"`
+	Name     string           `json:"name"`
+	Datasets []string         `json:"datasets,omitempty"`
+	Assigned map[string]int64 `json:"assigned"`
+}
+
+// ReassignmentDatasetDTO represents the progress of one data set of a user
+// reassignment.
+type ReassignmentDatasetDTO struct {
+	Dataset     string     `json:"dataset"`
+	Service     string     `json:"service"`
+	Transferred int64      `json:"transferred"`
+	Failed      int64      `json:"failed"`
+	CompletedAt *time.Time `json:"completed_at,omitempty"`
+}
+
+// OffboardUserRequest represents a request to offboard a departing user.
+// Reassign optionally transfers the user's open records in the same step.
+type OffboardUserRequest struct {
+	Reason   string                      `json:"reason" validate:"omitempty,max=500"`
+	Reassign *ReassignUserRecordsRequest `json:"reassign,omitempty"`
+}
+
+// OffboardUserResponse represents the outcome of offboarding a user.
+type OffboardUserResponse struct {
+	User                 *UserDTO             `json:"user"`
+	RevokedRefreshTokens int64                `json:"revoked_refresh_tokens"`
+	Reassignment         *UserReassignmentDTO `json:"reassignment,omitempty"`
+	AnonymizeAfter       time.Time            `json:"anonymize_after"`
+}
+
+// ============================================================================
+// Reporting Line DTOs
+// ============================================================================
+
+// AssignManagerRequest represents a request to set the manager a user reports
+// to. A null manager_id makes the user report to no one.
+type AssignManagerRequest struct {
+	ManagerID *uuid.UUID `json:"manager_id"`
+}
+
+// ReportingLineDTO represents a user's place in the organization chart: the
+// managers above them, nearest first, and the users reporting to them
+// directly.
+type ReportingLineDTO struct {
+	User          *ReportingLineUserDTO   `json:"user"`
+	Managers      []*ReportingLineUserDTO `json:"managers"`
+	DirectReports []*ReportingLineUserDTO `json:"direct_reports"`
+}
+
+// ReportingLineUserDTO represents a user in a reporting line. Inactive
+// managers are included, so approvals can skip past them.
+type ReportingLineUserDTO struct {
+	ID        uuid.UUID  `json:"id"`
+	Email     string     `json:"email"`
+	FullName  string     `json:"full_name"`
+	Status    string     `json:"status"`
+	ManagerID *uuid.UUID `json:"manager_id,omitempty"`
+}
+
+// ============================================================================
+// Security Event DTOs
+// ============================================================================
+
+// ListSecurityEventsRequest represents a request for the security events of
+// the current user.
+type ListSecurityEventsRequest struct {
+	Page           int    `json:"page" validate:"min=1"`
+	PageSize       int    `json:"page_size" validate:"min=1,max=100"`
+	Type           string `json:"type" validate:"omitempty,oneof=login_succeeded login_failed password_changed permissions_changed impersonation_started"`
+	SuspiciousOnly bool   `json:"suspicious_only"`
+}
+
+// SecurityEventDTO represents a security-relevant action on a user's account.
+type SecurityEventDTO struct {
+	ID             uuid.UUID              `json:"id"`
+	Type           string                 `json:"type"`
+	IPAddress      string                 `json:"ip_address,omitempty"`
+	UserAgent      string                 `json:"user_agent,omitempty"`
+	DeviceID       string                 `json:"device_id,omitempty"`
+	Country        string                 `json:"country,omitempty"`
+	ActorID        *uuid.UUID             `json:"actor_id,omitempty"`
+	Details        map[string]interface{} `json:"details,omitempty"`
+	Suspicious     []string               `json:"suspicious,omitempty"`
+	ReauthRequired bool                   `json:"reauth_required"`
+	CreatedAt      time.Time              `json:"created_at"`
+}
+
+// ListSecurityEventsResponse represents a page of security events.
+type ListSecurityEventsResponse struct {
+	Events     []*SecurityEventDTO `json:"events"`
+	Pagination *PaginationDTO      `json:"pagination"`
+}
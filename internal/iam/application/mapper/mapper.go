@@ -28,8 +28,10 @@ func UserToDTO(user *domain.User) *dto.UserDTO {
 		AvatarURL:       user.AvatarURL(),
 		Phone:           user.Phone(),
 		Status:          user.Status().String(),
+		ManagerID:       user.ManagerID(),
 		EmailVerifiedAt: user.EmailVerifiedAt(),
 		LastLoginAt:     user.LastLoginAt(),
+		DeactivatedAt:   user.DeactivatedAt(),
 		CreatedAt:       user.CreatedAt,
 		UpdatedAt:       user.UpdatedAt,
 	}
@@ -64,6 +66,28 @@ func UsersToDTO(users []*domain.User) []*dto.UserDTO {
 	return result
 }
 
+// ReportingLineUserToDTO converts a User domain entity to a
+// ReportingLineUserDTO.
+func ReportingLineUserToDTO(user *domain.User) *dto.ReportingLineUserDTO {
+	return &dto.ReportingLineUserDTO{
+		ID:        user.GetID(),
+		Email:     user.Email().String(),
+		FullName:  user.FullName(),
+		Status:    user.Status().String(),
+		ManagerID: user.ManagerID(),
+	}
+}
+
+// ReportingLineUsersToDTO converts a slice of User domain entities to
+// ReportingLineUserDTOs, returning an empty slice rather than nil.
+func ReportingLineUsersToDTO(users []*domain.User) []*dto.ReportingLineUserDTO {
+	result := make([]*dto.ReportingLineUserDTO, len(users))
+	for i, user := range users {
+		result[i] = ReportingLineUserToDTO(user)
+	}
+	return result
+}
+
 // DeviceInfoDTOToDomain converts a DeviceInfoDTO to domain DeviceInfo.
 func DeviceInfoDTOToDomain(d *dto.DeviceInfoDTO) domain.DeviceInfo {
 	if d == nil {
@@ -147,6 +171,9 @@ func TenantToDTO(tenant *domain.Tenant) *dto.TenantDTO {
 			Currency:           settings.Currency,
 			Language:           settings.Language,
 			NotificationsEmail: settings.NotificationsEmail,
+			AllowedOrigins:     settings.AllowedOrigins,
+			CustomDomain:       settings.CustomDomain,
+			Branding:           TenantBrandingToDTO(settings.Branding),
 		},
 		Limits: &dto.TenantLimitsDTO{
 			MaxUsers:    tenant.Plan().MaxUsers(),
@@ -191,6 +218,119 @@ func TenantsToDTO(tenants []*domain.Tenant) []*dto.TenantDTO {
 	return result
 }
 
+// TenantBrandingToDTO converts a tenant's branding to a TenantBrandingDTO.
+func TenantBrandingToDTO(branding *domain.TenantBranding) *dto.TenantBrandingDTO {
+	if branding == nil {
+		return nil
+	}
+
+	return &dto.TenantBrandingDTO{
+		LogoURL:          branding.LogoURL,
+		PrimaryColor:     branding.PrimaryColor,
+		SecondaryColor:   branding.SecondaryColor,
+		EmailFromAddress: branding.EmailFromAddress,
+		EmailFromName:    branding.EmailFromName,
+	}
+}
+
+// TenantToBrandingResponse converts a Tenant domain entity to its branding
+// and custom domain.
+func TenantToBrandingResponse(tenant *domain.Tenant) *dto.TenantBrandingResponse {
+	settings := tenant.Settings()
+	return &dto.TenantBrandingResponse{
+		TenantID:     tenant.GetID(),
+		Name:         tenant.Name(),
+		CustomDomain: settings.CustomDomain,
+		Branding:     TenantBrandingToDTO(settings.Branding),
+	}
+}
+
+// TenantExportToDTO converts a TenantExport domain entity to a TenantExportDTO.
+func TenantExportToDTO(export *domain.TenantExport) *dto.TenantExportDTO {
+	if export == nil {
+		return nil
+	}
+
+	files := make([]*dto.TenantExportFileDTO, len(export.Files()))
+	for i, file := range export.Files() {
+		files[i] = &dto.TenantExportFileDTO{
+			Dataset:     file.Dataset.String(),
+			Service:     file.Dataset.Service(),
+			Rows:        file.Rows,
+			SizeBytes:   file.SizeBytes,
+			CompletedAt: file.CompletedAt,
+		}
+	}
+
+	return &dto.TenantExportDTO{
+		ID:          export.GetID(),
+		TenantID:    export.TenantID(),
+		RequestedBy: export.RequestedBy(),
+		Status:      export.Status().String(),
+		Progress:    export.Progress(),
+		Files:       files,
+		Error:       export.Error(),
+		CreatedAt:   export.CreatedAt,
+		StartedAt:   export.StartedAt(),
+		CompletedAt: export.CompletedAt(),
+		ExpiresAt:   export.ExpiresAt(),
+	}
+}
+
+// UserReassignmentToDTO converts a UserReassignment domain entity to a
+// UserReassignmentDTO.
+func UserReassignmentToDTO(reassignment *domain.UserReassignment) *dto.UserReassignmentDTO {
+	if reassignment == nil {
+		return nil
+	}
+
+	recipients := make([]*dto.ReassignmentRecipientDTO, len(reassignment.Recipients()))
+	for i, recipient := range reassignment.Recipients() {
+		datasets := make([]string, len(recipient.Datasets))
+		for j, dataset := range recipient.Datasets {
+			datasets[j] = dataset.String()
+		}
+		assigned := make(map[string]int64, len(recipient.Assigned))
+		for dataset, n := range recipient.Assigned {
+			assigned[dataset.String()] = n
+		}
+		recipients[i] = &dto.ReassignmentRecipientDTO{
+			UserID:   recipient.UserID,
+			Name:     recipient.Name,
+			Datasets: datasets,
+			Assigned: assigned,
+		}
+	}
+
+	datasets := make([]*dto.ReassignmentDatasetDTO, len(reassignment.Datasets()))
+	for i, progress := range reassignment.Datasets() {
+		datasets[i] = &dto.ReassignmentDatasetDTO{
+			Dataset:     progress.Dataset.String(),
+			Service:     progress.Dataset.Service(),
+			Transferred: progress.Transferred,
+			Failed:      progress.Failed,
+			CompletedAt: progress.CompletedAt,
+		}
+	}
+
+	return &dto.UserReassignmentDTO{
+		ID:          reassignment.GetID(),
+		TenantID:    reassignment.TenantID(),
+		UserID:      reassignment.UserID(),
+		RequestedBy: reassignment.RequestedBy(),
+		Strategy:    reassignment.Strategy().String(),
+		Status:      reassignment.Status().String(),
+		Progress:    reassignment.Progress(),
+		Transferred: reassignment.Transferred(),
+		Recipients:  recipients,
+		Datasets:    datasets,
+		Error:       reassignment.Error(),
+		CreatedAt:   reassignment.CreatedAt,
+		StartedAt:   reassignment.StartedAt(),
+		CompletedAt: reassignment.CompletedAt(),
+	}
+}
+
 // TenantWithUsageToDTO converts a Tenant with usage stats to TenantDTO.
 func TenantWithUsageToDTO(tenant *domain.Tenant, userCount, contactCount int64) *dto.TenantDTO {
 	tenantDTO := TenantToDTO(tenant)
@@ -245,3 +385,39 @@ func StringsToPermissions(perms []string) ([]domain.Permission, error) {
 func StringsToPermissionSet(perms []string) (*domain.PermissionSet, error) {
 	return domain.NewPermissionSetFromStrings(perms)
 }
+
+// SecurityEventToDTO converts a SecurityEvent domain entity to SecurityEventDTO.
+func SecurityEventToDTO(event *domain.SecurityEvent) *dto.SecurityEventDTO {
+	if event == nil {
+		return nil
+	}
+
+	var suspicious []string
+	for _, activity := range event.Suspicious() {
+		suspicious = append(suspicious, activity.String())
+	}
+
+	source := event.Source()
+	return &dto.SecurityEventDTO{
+		ID:             event.GetID(),
+		Type:           event.Type().String(),
+		IPAddress:      source.IPAddress,
+		UserAgent:      source.UserAgent,
+		DeviceID:       source.DeviceID,
+		Country:        source.Country,
+		ActorID:        source.ActorID,
+		Details:        event.Details(),
+		Suspicious:     suspicious,
+		ReauthRequired: event.ReauthRequired(),
+		CreatedAt:      event.CreatedAt,
+	}
+}
+
+// SecurityEventsToDTO converts a slice of SecurityEvent domain entities to DTOs.
+func SecurityEventsToDTO(events []*domain.SecurityEvent) []*dto.SecurityEventDTO {
+	result := make([]*dto.SecurityEventDTO, len(events))
+	for i, event := range events {
+		result[i] = SecurityEventToDTO(event)
+	}
+	return result
+}
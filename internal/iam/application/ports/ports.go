@@ -4,9 +4,12 @@ package ports
 
 import (
 	"context"
+	"io"
 	"time"
 
 	"github.com/google/uuid"
+
+	"github.com/kilang-desa-murni/crm/internal/iam/domain"
 )
 
 // ============================================================================
@@ -152,6 +155,14 @@ const (
 	AuditActionPasswordChanged = "password_changed"
 	AuditActionRoleAssigned    = "role_assigned"
 	AuditActionRoleRemoved     = "role_removed"
+	AuditActionDataExported    = "data_exported"
+	AuditActionDemoDataSeeded  = "demo_data_seeded"
+	AuditActionDemoDataPurged  = "demo_data_purged"
+	AuditActionRecordsReassigned = "records_reassigned"
+	AuditActionUserOffboarded    = "user_offboarded"
+	AuditActionUserAnonymized    = "user_anonymized"
+	AuditActionImpersonationStarted = "impersonation_started"
+	AuditActionManagerAssigned      = "manager_assigned"
 )
 
 // ============================================================================
@@ -202,6 +213,17 @@ type TokenBlacklist interface {
 	IsBlacklisted(ctx context.Context, token string) (bool, error)
 }
 
+// SessionBlacklist defines the interface for revoking every access token of
+// a user at once, without knowing the tokens.
+type SessionBlacklist interface {
+	// BlacklistUserSessions invalidates the user's tokens issued until after.
+	// The entry is needed for ttl, the lifetime of the longest-lived token.
+	BlacklistUserSessions(ctx context.Context, userID string, after time.Time, ttl time.Duration) error
+
+	// IsSessionValid checks if the user's token issued at issuedAt is still valid.
+	IsSessionValid(ctx context.Context, userID string, issuedAt time.Time) (bool, error)
+}
+
 // ============================================================================
 // Verification Token Ports
 // ============================================================================
@@ -223,3 +245,71 @@ type VerificationTokenService interface {
 	// InvalidateToken invalidates a verification token.
 	InvalidateToken(ctx context.Context, token string) error
 }
+
+// ============================================================================
+// Tenant Export Ports
+// ============================================================================
+
+// TenantDataSource reads a tenant's data from the services that own it.
+type TenantDataSource interface {
+	// ExportDataset writes every record of a data set of the tenant to w as
+	// JSON lines and returns the number of records written.
+	ExportDataset(ctx context.Context, tenantID uuid.UUID, dataset domain.TenantExportDataset, w io.Writer) (int64, error)
+}
+
+// ExportStorage defines the interface for storing tenant export files.
+type ExportStorage interface {
+	// Put stores the content of r under key and returns its size in bytes.
+	Put(ctx context.Context, key string, r io.Reader) (int64, error)
+
+	// SignedURL returns a URL that downloads the file without further
+	// authentication until expiry passes.
+	SignedURL(ctx context.Context, key string, expiry time.Duration) (string, error)
+
+	// Delete deletes the file stored under key.
+	Delete(ctx context.Context, key string) error
+}
+
+// ============================================================================
+// Tenant Demo Data Ports
+// ============================================================================
+
+// DemoDataResult is the outcome of seeding or purging a tenant's demo data in
+// one service.
+type DemoDataResult struct {
+	Created  int64
+	Existing int64
+	Deleted  int64
+}
+
+// DemoDataSeeder seeds and purges a tenant's demo data in the services that
+// own it.
+type DemoDataSeeder interface {
+	// Seed creates the service's demo records of the tenant that do not
+	// exist yet, recording seededBy as their creator.
+	Seed(ctx context.Context, service string, tenantID, seededBy uuid.UUID) (DemoDataResult, error)
+
+	// Purge deletes the service's demo records of the tenant.
+	Purge(ctx context.Context, service string, tenantID uuid.UUID) (DemoDataResult, error)
+}
+
+// ============================================================================
+// User Reassignment Ports
+// ============================================================================
+
+// ReassignmentResult is the outcome of reassigning one data set of a user:
+// the records transferred, those that could not be, and the number each
+// recipient received.
+type ReassignmentResult struct {
+	Transferred int64
+	Failed      int64
+	Assigned    map[uuid.UUID]int64
+}
+
+// OwnerReassigner transfers a user's open records in the services that own
+// them.
+type OwnerReassigner interface {
+	// Reassign hands the user's open records of the data set to the
+	// recipients in turn, starting with the first.
+	Reassign(ctx context.Context, tenantID, fromUserID uuid.UUID, dataset domain.ReassignmentDataset, recipients []domain.ReassignmentRecipient) (ReassignmentResult, error)
+}
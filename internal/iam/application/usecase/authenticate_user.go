@@ -66,32 +66,32 @@ func (uc *AuthenticateUserUseCase) Execute(ctx context.Context, req *dto.LoginRe
 	// Find tenant by slug
 	tenant, err := uc.tenantRepo.FindBySlug(ctx, req.TenantSlug)
 	if err != nil {
-		uc.logFailedLogin(ctx, uuid.Nil, nil, req.Email, ipAddress, userAgent, "tenant_not_found")
+		uc.logFailedLogin(ctx, uuid.Nil, nil, req, ipAddress, userAgent, "tenant_not_found")
 		return nil, application.ErrInvalidCredentials()
 	}
 
 	if !tenant.IsActive() {
-		uc.logFailedLogin(ctx, tenant.GetID(), nil, req.Email, ipAddress, userAgent, "tenant_inactive")
+		uc.logFailedLogin(ctx, tenant.GetID(), nil, req, ipAddress, userAgent, "tenant_inactive")
 		return nil, application.ErrTenantInactive()
 	}
 
 	// Create email value object
 	email, err := domain.NewEmail(req.Email)
 	if err != nil {
-		uc.logFailedLogin(ctx, tenant.GetID(), nil, req.Email, ipAddress, userAgent, "invalid_email_format")
+		uc.logFailedLogin(ctx, tenant.GetID(), nil, req, ipAddress, userAgent, "invalid_email_format")
 		return nil, application.ErrInvalidCredentials()
 	}
 
 	// Find user by email in tenant
 	user, err := uc.userRepo.FindByEmail(ctx, tenant.GetID(), email)
 	if err != nil {
-		uc.logFailedLogin(ctx, tenant.GetID(), nil, req.Email, ipAddress, userAgent, "user_not_found")
+		uc.logFailedLogin(ctx, tenant.GetID(), nil, req, ipAddress, userAgent, "user_not_found")
 		return nil, application.ErrInvalidCredentials()
 	}
 
 	// Check user status
 	if !user.CanLogin() {
-		uc.logFailedLogin(ctx, tenant.GetID(), &user, req.Email, ipAddress, userAgent, "user_cannot_login")
+		uc.logFailedLogin(ctx, tenant.GetID(), &user, req, ipAddress, userAgent, "user_cannot_login")
 
 		if user.IsDeleted() {
 			return nil, application.ErrInvalidCredentials()
@@ -105,7 +105,7 @@ func (uc *AuthenticateUserUseCase) Execute(ctx context.Context, req *dto.LoginRe
 	// Verify password
 	valid, err := uc.passwordHasher.Verify(req.Password, user.PasswordHash().Hash())
 	if err != nil || !valid {
-		uc.logFailedLogin(ctx, tenant.GetID(), &user, req.Email, ipAddress, userAgent, "invalid_password")
+		uc.logFailedLogin(ctx, tenant.GetID(), &user, req, ipAddress, userAgent, "invalid_password")
 		return nil, application.ErrInvalidCredentials()
 	}
 
@@ -193,6 +193,7 @@ func (uc *AuthenticateUserUseCase) Execute(ctx context.Context, req *dto.LoginRe
 		Action:     ports.AuditActionLogin,
 		EntityType: "user",
 		EntityID:   ptrToUUID(user.GetID()),
+		NewValues:  clientDetails(req, map[string]interface{}{}),
 		IPAddress:  ipAddress,
 		UserAgent:  userAgent,
 	})
@@ -207,17 +208,17 @@ func (uc *AuthenticateUserUseCase) Execute(ctx context.Context, req *dto.LoginRe
 }
 
 // logFailedLogin logs a failed login attempt.
-func (uc *AuthenticateUserUseCase) logFailedLogin(ctx context.Context, tenantID uuid.UUID, user **domain.User, email, ipAddress, userAgent, reason string) {
+func (uc *AuthenticateUserUseCase) logFailedLogin(ctx context.Context, tenantID uuid.UUID, user **domain.User, req *dto.LoginRequest, ipAddress, userAgent, reason string) {
 	entry := ports.AuditEntry{
 		TenantID:   tenantID,
 		Action:     ports.AuditActionLoginFailed,
 		EntityType: "user",
 		IPAddress:  ipAddress,
 		UserAgent:  userAgent,
-		NewValues: map[string]interface{}{
-			"email":  email,
+		NewValues: clientDetails(req, map[string]interface{}{
+			"email":  req.Email,
 			"reason": reason,
-		},
+		}),
 	}
 
 	if user != nil && *user != nil {
@@ -229,6 +230,19 @@ func (uc *AuthenticateUserUseCase) logFailedLogin(ctx context.Context, tenantID
 	_ = uc.auditLogger.Log(ctx, entry)
 }
 
+// clientDetails adds the device and country of a login attempt to the values
+// of its audit entry, so the attempt can be told apart from the user's usual
+// sign-ins.
+func clientDetails(req *dto.LoginRequest, values map[string]interface{}) map[string]interface{} {
+	if req.DeviceInfo != nil && req.DeviceInfo.DeviceID != "" {
+		values["device_id"] = req.DeviceInfo.DeviceID
+	}
+	if req.Country != "" {
+		values["country"] = req.Country
+	}
+	return values
+}
+
 // getRoleNames extracts role names from roles.
 func (uc *AuthenticateUserUseCase) getRoleNames(roles []*domain.Role) []string {
 	names := make([]string, len(roles))
@@ -20,10 +20,12 @@ import (
 
 // MockUserRepository is a mock implementation of domain.UserRepository.
 type MockUserRepository struct {
-	FindByEmailFn func(ctx context.Context, tenantID uuid.UUID, email domain.Email) (*domain.User, error)
-	UpdateFn      func(ctx context.Context, user *domain.User) error
-	CreateFn      func(ctx context.Context, user *domain.User) error
-	FindByIDFn    func(ctx context.Context, id uuid.UUID) (*domain.User, error)
+	FindByEmailFn             func(ctx context.Context, tenantID uuid.UUID, email domain.Email) (*domain.User, error)
+	UpdateFn                  func(ctx context.Context, user *domain.User) error
+	CreateFn                  func(ctx context.Context, user *domain.User) error
+	FindByIDFn                func(ctx context.Context, id uuid.UUID) (*domain.User, error)
+	FindDueForAnonymizationFn func(ctx context.Context, deactivatedBefore time.Time, limit int) ([]*domain.User, error)
+	FindByManagerIDFn         func(ctx context.Context, managerID uuid.UUID) ([]*domain.User, error)
 }
 
 func (m *MockUserRepository) Create(ctx context.Context, user *domain.User) error {
@@ -66,6 +68,13 @@ func (m *MockUserRepository) FindByRoleID(ctx context.Context, roleID uuid.UUID)
 	return nil, nil
 }
 
+func (m *MockUserRepository) FindByManagerID(ctx context.Context, managerID uuid.UUID) ([]*domain.User, error) {
+	if m.FindByManagerIDFn != nil {
+		return m.FindByManagerIDFn(ctx, managerID)
+	}
+	return nil, nil
+}
+
 func (m *MockUserRepository) ExistsByEmail(ctx context.Context, tenantID uuid.UUID, email domain.Email) (bool, error) {
 	return false, nil
 }
@@ -74,6 +83,13 @@ func (m *MockUserRepository) CountByTenant(ctx context.Context, tenantID uuid.UU
 	return 0, nil
 }
 
+func (m *MockUserRepository) FindDueForAnonymization(ctx context.Context, deactivatedBefore time.Time, limit int) ([]*domain.User, error) {
+	if m.FindDueForAnonymizationFn != nil {
+		return m.FindDueForAnonymizationFn(ctx, deactivatedBefore, limit)
+	}
+	return nil, nil
+}
+
 // MockTenantRepository is a mock implementation of domain.TenantRepository.
 type MockTenantRepository struct {
 	FindBySlugFn func(ctx context.Context, slug string) (*domain.Tenant, error)
@@ -106,6 +122,10 @@ func (m *MockTenantRepository) FindBySlug(ctx context.Context, slug string) (*do
 	return nil, errors.New("not found")
 }
 
+func (m *MockTenantRepository) FindByCustomDomain(ctx context.Context, host string) (*domain.Tenant, error) {
+	return nil, domain.ErrTenantNotFound
+}
+
 func (m *MockTenantRepository) FindAll(ctx context.Context, opts domain.TenantQueryOptions) ([]*domain.Tenant, int64, error) {
 	return nil, 0, nil
 }
@@ -0,0 +1,165 @@
+// Package usecase contains the application use cases for the IAM service.
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/google/uuid"
+
+	"github.com/kilang-desa-murni/crm/internal/iam/application"
+	"github.com/kilang-desa-murni/crm/internal/iam/application/dto"
+	"github.com/kilang-desa-murni/crm/internal/iam/application/mapper"
+	"github.com/kilang-desa-murni/crm/internal/iam/application/ports"
+	"github.com/kilang-desa-murni/crm/internal/iam/domain"
+)
+
+// securityHistoryLimit is how many of a user's earlier security events are
+// checked against the security rules.
+const securityHistoryLimit = 500
+
+// RecordSecurityEventUseCase handles recording a security event of a user.
+type RecordSecurityEventUseCase struct {
+	securityEventRepo domain.SecurityEventRepository
+	userRepo          domain.UserRepository
+	refreshTokenRepo  domain.RefreshTokenRepository
+	outboxRepo        domain.OutboxRepository
+	sessionBlacklist  ports.SessionBlacklist
+	tokenService      ports.TokenService
+	txManager         ports.TransactionManager
+	rules             domain.SecurityRules
+}
+
+// NewRecordSecurityEventUseCase creates a new RecordSecurityEventUseCase.
+func NewRecordSecurityEventUseCase(
+	securityEventRepo domain.SecurityEventRepository,
+	userRepo domain.UserRepository,
+	refreshTokenRepo domain.RefreshTokenRepository,
+	outboxRepo domain.OutboxRepository,
+	sessionBlacklist ports.SessionBlacklist,
+	tokenService ports.TokenService,
+	txManager ports.TransactionManager,
+	rules domain.SecurityRules,
+) *RecordSecurityEventUseCase {
+	return &RecordSecurityEventUseCase{
+		securityEventRepo: securityEventRepo,
+		userRepo:          userRepo,
+		refreshTokenRepo:  refreshTokenRepo,
+		outboxRepo:        outboxRepo,
+		sessionBlacklist:  sessionBlacklist,
+		tokenService:      tokenService,
+		txManager:         txManager,
+		rules:             rules,
+	}
+}
+
+// Execute checks the event against the security rules and stores it,
+// publishing it along with an alert when it looks suspicious. With forced
+// re-authentication a suspicious event signs the user out everywhere.
+func (uc *RecordSecurityEventUseCase) Execute(ctx context.Context, event *domain.SecurityEvent) error {
+	history, err := uc.securityEventRepo.FindRecentByUser(ctx, event.UserID(), event.CreatedAt.Add(-uc.rules.History), securityHistoryLimit)
+	if err != nil {
+		return application.ErrInternal("failed to find recent security events", err)
+	}
+
+	if activities := uc.rules.Detect(event, history); len(activities) > 0 {
+		user, err := uc.userRepo.FindByID(ctx, event.UserID())
+		if err != nil {
+			return application.ErrNotFound("user", event.UserID())
+		}
+		event.Flag(user, activities, uc.rules.ForceReauth)
+	}
+
+	// Access tokens are self-contained, so every token issued until now is
+	// blacklisted for as long as the longest of them is valid
+	if event.ReauthRequired() {
+		if err := uc.sessionBlacklist.BlacklistUserSessions(ctx, event.UserID().String(), event.CreatedAt, uc.tokenService.GetAccessTokenExpiry()); err != nil {
+			return application.ErrInternal("failed to revoke sessions", err)
+		}
+	}
+
+	// Execute in transaction
+	err = uc.txManager.WithTransaction(ctx, func(txCtx context.Context) error {
+		if err := uc.securityEventRepo.Create(txCtx, event); err != nil {
+			return err
+		}
+
+		if event.ReauthRequired() {
+			if err := uc.refreshTokenRepo.RevokeByUserID(txCtx, event.UserID()); err != nil {
+				return err
+			}
+		}
+
+		// Save domain events to outbox
+		for _, domainEvent := range event.GetDomainEvents() {
+			payload, err := json.Marshal(domainEvent)
+			if err != nil {
+				return err
+			}
+			outboxEntry := &domain.OutboxEntry{
+				EventType:     domainEvent.EventType(),
+				AggregateID:   domainEvent.AggregateID(),
+				AggregateType: domainEvent.AggregateType(),
+				Payload:       payload,
+			}
+			if err := uc.outboxRepo.Create(txCtx, outboxEntry); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return application.ErrInternal("failed to record security event", err)
+	}
+
+	event.ClearDomainEvents()
+
+	return nil
+}
+
+// ListSecurityEventsUseCase handles listing the security events of a user.
+type ListSecurityEventsUseCase struct {
+	securityEventRepo domain.SecurityEventRepository
+}
+
+// NewListSecurityEventsUseCase creates a new ListSecurityEventsUseCase.
+func NewListSecurityEventsUseCase(securityEventRepo domain.SecurityEventRepository) *ListSecurityEventsUseCase {
+	return &ListSecurityEventsUseCase{
+		securityEventRepo: securityEventRepo,
+	}
+}
+
+// Execute lists the security events of a user, newest first.
+func (uc *ListSecurityEventsUseCase) Execute(ctx context.Context, tenantID, userID uuid.UUID, req *dto.ListSecurityEventsRequest) (*dto.ListSecurityEventsResponse, error) {
+	// Set defaults
+	if req.Page < 1 {
+		req.Page = 1
+	}
+	if req.PageSize < 1 || req.PageSize > 100 {
+		req.PageSize = 20
+	}
+
+	eventType := domain.SecurityEventType(req.Type)
+	if eventType != "" && !eventType.IsValid() {
+		return nil, application.ErrValidation("invalid security event type", map[string]interface{}{
+			"type": req.Type,
+		})
+	}
+
+	events, total, err := uc.securityEventRepo.FindByUser(ctx, tenantID, userID, domain.SecurityEventQueryOptions{
+		Page:           req.Page,
+		PageSize:       req.PageSize,
+		Type:           eventType,
+		SuspiciousOnly: req.SuspiciousOnly,
+	})
+	if err != nil {
+		return nil, application.ErrInternal("failed to list security events", err)
+	}
+
+	return &dto.ListSecurityEventsResponse{
+		Events:     mapper.SecurityEventsToDTO(events),
+		Pagination: dto.NewPaginationDTO(req.Page, req.PageSize, total),
+	}, nil
+}
@@ -0,0 +1,176 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/kilang-desa-murni/crm/internal/iam/application"
+	"github.com/kilang-desa-murni/crm/internal/iam/application/dto"
+	"github.com/kilang-desa-murni/crm/internal/iam/domain"
+)
+
+// ============================================================================
+// Mock Implementations for Security Event Tests
+// ============================================================================
+
+// MockSecurityEventRepository is an in-memory mock of domain.SecurityEventRepository.
+// Events are kept newest first.
+type MockSecurityEventRepository struct {
+	events []*domain.SecurityEvent
+}
+
+func (m *MockSecurityEventRepository) Create(ctx context.Context, event *domain.SecurityEvent) error {
+	m.events = append([]*domain.SecurityEvent{event}, m.events...)
+	return nil
+}
+
+func (m *MockSecurityEventRepository) FindByUser(ctx context.Context, tenantID, userID uuid.UUID, opts domain.SecurityEventQueryOptions) ([]*domain.SecurityEvent, int64, error) {
+	var found []*domain.SecurityEvent
+	for _, event := range m.events {
+		if event.TenantID() != tenantID || event.UserID() != userID {
+			continue
+		}
+		if (opts.Type != "" && event.Type() != opts.Type) || (opts.SuspiciousOnly && !event.IsSuspicious()) {
+			continue
+		}
+		found = append(found, event)
+	}
+	return found, int64(len(found)), nil
+}
+
+func (m *MockSecurityEventRepository) FindRecentByUser(ctx context.Context, userID uuid.UUID, since time.Time, limit int) ([]*domain.SecurityEvent, error) {
+	var found []*domain.SecurityEvent
+	for _, event := range m.events {
+		if event.UserID() == userID && !event.CreatedAt.Before(since) && len(found) < limit {
+			found = append(found, event)
+		}
+	}
+	return found, nil
+}
+
+// ============================================================================
+// Security Event Tests
+// ============================================================================
+
+func TestRecordSecurityEventUseCase_Execute(t *testing.T) {
+	ctx := context.Background()
+	tenantID := uuid.New()
+	user := createTestUser(t, tenantID)
+	securityEventRepo := &MockSecurityEventRepository{}
+	sessionBlacklist := &MockSessionBlacklist{}
+	userRepo := &MockUserRepository{
+		FindByIDFn: func(ctx context.Context, id uuid.UUID) (*domain.User, error) {
+			return user, nil
+		},
+	}
+
+	var revokedTokens bool
+	refreshTokenRepo := &MockRefreshTokenRepository{
+		RevokeByUserIDFn: func(ctx context.Context, userID uuid.UUID) error {
+			revokedTokens = userID == user.GetID()
+			return nil
+		},
+	}
+
+	var events []string
+	outboxRepo := &MockOutboxRepository{
+		CreateFn: func(ctx context.Context, entry *domain.OutboxEntry) error {
+			events = append(events, entry.EventType)
+			return nil
+		},
+	}
+
+	rules := domain.DefaultSecurityRules()
+	rules.ForceReauth = true
+	useCase := NewRecordSecurityEventUseCase(
+		securityEventRepo,
+		userRepo,
+		refreshTokenRepo,
+		outboxRepo,
+		sessionBlacklist,
+		&MockTokenService{},
+		&MockTransactionManager{},
+		rules,
+	)
+
+	record := func(country string) *domain.SecurityEvent {
+		t.Helper()
+		event, err := domain.NewSecurityEvent(tenantID, user.GetID(), domain.SecurityEventLoginSucceeded, domain.SecuritySource{
+			DeviceID: "laptop",
+			Country:  country,
+		}, nil)
+		if err != nil {
+			t.Fatalf("NewSecurityEvent() error = %v", err)
+		}
+		if err := useCase.Execute(ctx, event); err != nil {
+			t.Fatalf("Execute() error = %v", err)
+		}
+		return event
+	}
+
+	// The first sign-in is stored and published without an alert
+	first := record("MY")
+	if first.IsSuspicious() || len(events) != 1 || events[0] != "security.login_succeeded" {
+		t.Fatalf("expected the sign-in to be published without an alert, got %v", events)
+	}
+	issuedAt := time.Now().Add(-time.Second)
+
+	// A sign-in from another country is flagged and signs the user out
+	events = nil
+	second := record("SG")
+	if !second.IsSuspicious() || !second.ReauthRequired() {
+		t.Fatalf("expected the sign-in to be flagged, got %v", second.Suspicious())
+	}
+	if len(events) != 2 || events[1] != domain.EventTypeUserSuspiciousActivity {
+		t.Errorf("expected the sign-in and an alert to be published, got %v", events)
+	}
+	if valid, _ := sessionBlacklist.IsSessionValid(ctx, user.GetID().String(), issuedAt); valid || !revokedTokens {
+		t.Error("expected the user's sessions and refresh tokens to be revoked")
+	}
+	if len(securityEventRepo.events) != 2 || len(second.GetDomainEvents()) != 0 {
+		t.Errorf("expected both events to be stored, got %d", len(securityEventRepo.events))
+	}
+}
+
+func TestListSecurityEventsUseCase_Execute(t *testing.T) {
+	ctx := context.Background()
+	tenantID, userID := uuid.New(), uuid.New()
+	securityEventRepo := &MockSecurityEventRepository{}
+	for _, eventType := range []domain.SecurityEventType{domain.SecurityEventLoginSucceeded, domain.SecurityEventPasswordChanged} {
+		event, err := domain.NewSecurityEvent(tenantID, userID, eventType, domain.SecuritySource{IPAddress: "10.0.0.1"}, nil)
+		if err != nil {
+			t.Fatalf("NewSecurityEvent() error = %v", err)
+		}
+		_ = securityEventRepo.Create(ctx, event)
+	}
+
+	useCase := NewListSecurityEventsUseCase(securityEventRepo)
+
+	result, err := useCase.Execute(ctx, tenantID, userID, &dto.ListSecurityEventsRequest{})
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if len(result.Events) != 2 || result.Events[0].Type != "password_changed" || result.Pagination.PageSize != 20 {
+		t.Errorf("expected both events newest first, got %+v", result)
+	}
+
+	result, err = useCase.Execute(ctx, tenantID, userID, &dto.ListSecurityEventsRequest{Type: "login_succeeded"})
+	if err != nil || len(result.Events) != 1 {
+		t.Errorf("Execute() filtered by type = %v, %v; want 1 event", result, err)
+	}
+
+	// Another user's events are not listed
+	result, err = useCase.Execute(ctx, tenantID, uuid.New(), &dto.ListSecurityEventsRequest{})
+	if err != nil || len(result.Events) != 0 {
+		t.Errorf("Execute() for another user = %v, %v; want no events", result, err)
+	}
+
+	var appErr *application.AppError
+	if _, err := useCase.Execute(ctx, tenantID, userID, &dto.ListSecurityEventsRequest{Type: "logged_in"}); !errors.As(err, &appErr) || appErr.Code != application.ErrCodeValidation {
+		t.Errorf("Execute() with an unknown type error = %v, want validation error", err)
+	}
+}
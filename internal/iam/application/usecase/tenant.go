@@ -3,6 +3,7 @@ package usecase
 
 import (
 	"context"
+	"errors"
 	"time"
 
 	"github.com/google/uuid"
@@ -550,6 +551,22 @@ func (uc *UpdateTenantSettingsUseCase) Execute(ctx context.Context, tenantID uui
 		tenant.UpdateSettingsPartial(updates)
 	}
 
+	if req.AllowedOrigins != nil {
+		if err := tenant.SetAllowedOrigins(*req.AllowedOrigins); err != nil {
+			return nil, application.ErrValidation("invalid allowed origins", map[string]interface{}{
+				"error": err.Error(),
+			})
+		}
+		updates["allowed_origins"] = tenant.Settings().AllowedOrigins
+	}
+
+	if req.CustomDomain != nil {
+		if err := setTenantCustomDomain(ctx, uc.tenantRepo, tenant, *req.CustomDomain); err != nil {
+			return nil, err
+		}
+		updates["custom_domain"] = tenant.Settings().CustomDomain
+	}
+
 	// Execute in transaction
 	err = uc.txManager.WithTransaction(ctx, func(txCtx context.Context) error {
 		if err := uc.tenantRepo.Update(txCtx, tenant); err != nil {
@@ -584,9 +601,11 @@ func (uc *UpdateTenantSettingsUseCase) Execute(ctx context.Context, tenantID uui
 		EntityType: "tenant_settings",
 		EntityID:   ptrToUUID(tenantID),
 		OldValues: map[string]interface{}{
-			"timezone":   oldSettings.Timezone,
-			"currency":   oldSettings.Currency,
-			"language":   oldSettings.Language,
+			"timezone":        oldSettings.Timezone,
+			"currency":        oldSettings.Currency,
+			"language":        oldSettings.Language,
+			"allowed_origins": oldSettings.AllowedOrigins,
+			"custom_domain":   oldSettings.CustomDomain,
 		},
 		NewValues: updates,
 	})
@@ -596,6 +615,213 @@ func (uc *UpdateTenantSettingsUseCase) Execute(ctx context.Context, tenantID uui
 	}, nil
 }
 
+// ListTenantOriginsUseCase handles listing the browser origins tenants allow
+// for their users.
+type ListTenantOriginsUseCase struct {
+	tenantRepo domain.TenantRepository
+}
+
+// NewListTenantOriginsUseCase creates a new ListTenantOriginsUseCase.
+func NewListTenantOriginsUseCase(tenantRepo domain.TenantRepository) *ListTenantOriginsUseCase {
+	return &ListTenantOriginsUseCase{
+		tenantRepo: tenantRepo,
+	}
+}
+
+// Execute lists the origins allowed by active tenants, with the tenants
+// allowing each.
+func (uc *ListTenantOriginsUseCase) Execute(ctx context.Context) (*dto.TenantOriginsResponse, error) {
+	opts := domain.DefaultTenantQueryOptions()
+	opts.PageSize = 100
+
+	origins := make(map[string][]uuid.UUID)
+	for {
+		tenants, total, err := uc.tenantRepo.FindAll(ctx, opts)
+		if err != nil {
+			return nil, application.ErrInternal("failed to list tenant origins", err)
+		}
+
+		for _, tenant := range tenants {
+			if !tenant.IsActive() {
+				continue
+			}
+			for _, origin := range tenant.Settings().AllowedOrigins {
+				origins[origin] = append(origins[origin], tenant.GetID())
+			}
+		}
+
+		if len(tenants) == 0 || int64(opts.Page*opts.PageSize) >= total {
+			break
+		}
+		opts.Page++
+	}
+
+	return &dto.TenantOriginsResponse{Origins: origins}, nil
+}
+
+// setTenantCustomDomain sets a tenant's custom domain, refusing domains
+// already used by another tenant.
+func setTenantCustomDomain(ctx context.Context, tenantRepo domain.TenantRepository, tenant *domain.Tenant, host string) error {
+	if err := tenant.SetCustomDomain(host); err != nil {
+		return application.ErrValidation("invalid custom domain", map[string]interface{}{
+			"error": err.Error(),
+		})
+	}
+
+	host = tenant.Settings().CustomDomain
+	if host == "" {
+		return nil
+	}
+	other, err := tenantRepo.FindByCustomDomain(ctx, host)
+	if err != nil && !errors.Is(err, domain.ErrTenantNotFound) {
+		return application.ErrInternal("failed to check custom domain", err)
+	}
+	if other != nil && other.GetID() != tenant.GetID() {
+		return application.ErrConflict(domain.ErrTenantCustomDomainTaken.Error())
+	}
+	return nil
+}
+
+// TenantBrandingUseCase handles reading and updating the branding
+// white-labeled tenants present the CRM with.
+type TenantBrandingUseCase struct {
+	tenantRepo  domain.TenantRepository
+	outboxRepo  domain.OutboxRepository
+	txManager   ports.TransactionManager
+	auditLogger ports.AuditLogger
+}
+
+// NewTenantBrandingUseCase creates a new TenantBrandingUseCase.
+func NewTenantBrandingUseCase(
+	tenantRepo domain.TenantRepository,
+	outboxRepo domain.OutboxRepository,
+	txManager ports.TransactionManager,
+	auditLogger ports.AuditLogger,
+) *TenantBrandingUseCase {
+	return &TenantBrandingUseCase{
+		tenantRepo:  tenantRepo,
+		outboxRepo:  outboxRepo,
+		txManager:   txManager,
+		auditLogger: auditLogger,
+	}
+}
+
+// Get returns a tenant's branding and custom domain.
+func (uc *TenantBrandingUseCase) Get(ctx context.Context, tenantID uuid.UUID) (*dto.TenantBrandingResponse, error) {
+	tenant, err := uc.tenantRepo.FindByID(ctx, tenantID)
+	if err != nil {
+		return nil, application.ErrNotFound("tenant", tenantID)
+	}
+
+	return mapper.TenantToBrandingResponse(tenant), nil
+}
+
+// Update replaces a tenant's branding; a request without any field clears it.
+func (uc *TenantBrandingUseCase) Update(ctx context.Context, tenantID uuid.UUID, req *dto.UpdateTenantBrandingRequest) (*dto.TenantBrandingResponse, error) {
+	tenant, err := uc.tenantRepo.FindByID(ctx, tenantID)
+	if err != nil {
+		return nil, application.ErrNotFound("tenant", tenantID)
+	}
+
+	oldBranding := tenant.Settings().Branding
+
+	var branding *domain.TenantBranding
+	if *req != (dto.UpdateTenantBrandingRequest{}) {
+		branding = &domain.TenantBranding{
+			LogoURL:          req.LogoURL,
+			PrimaryColor:     req.PrimaryColor,
+			SecondaryColor:   req.SecondaryColor,
+			EmailFromAddress: req.EmailFromAddress,
+			EmailFromName:    req.EmailFromName,
+		}
+	}
+	if err := tenant.SetBranding(branding); err != nil {
+		return nil, application.ErrValidation("invalid branding", map[string]interface{}{
+			"error": err.Error(),
+		})
+	}
+
+	// Execute in transaction
+	err = uc.txManager.WithTransaction(ctx, func(txCtx context.Context) error {
+		if err := uc.tenantRepo.Update(txCtx, tenant); err != nil {
+			return err
+		}
+
+		// Save domain events to outbox
+		for _, event := range tenant.GetDomainEvents() {
+			outboxEntry := &domain.OutboxEntry{
+				EventType:     event.EventType(),
+				AggregateID:   event.AggregateID(),
+				AggregateType: event.AggregateType(),
+			}
+			if err := uc.outboxRepo.Create(txCtx, outboxEntry); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return nil, application.ErrInternal("failed to update tenant branding", err)
+	}
+
+	tenant.ClearDomainEvents()
+
+	// Log audit
+	_ = uc.auditLogger.Log(ctx, ports.AuditEntry{
+		TenantID:   tenantID,
+		Action:     ports.AuditActionUpdate,
+		EntityType: "tenant_branding",
+		EntityID:   ptrToUUID(tenantID),
+		OldValues:  map[string]interface{}{"branding": oldBranding},
+		NewValues:  map[string]interface{}{"branding": tenant.Settings().Branding},
+	})
+
+	return mapper.TenantToBrandingResponse(tenant), nil
+}
+
+// ListTenantDomainsUseCase handles listing the custom domains of tenants.
+type ListTenantDomainsUseCase struct {
+	tenantRepo domain.TenantRepository
+}
+
+// NewListTenantDomainsUseCase creates a new ListTenantDomainsUseCase.
+func NewListTenantDomainsUseCase(tenantRepo domain.TenantRepository) *ListTenantDomainsUseCase {
+	return &ListTenantDomainsUseCase{
+		tenantRepo: tenantRepo,
+	}
+}
+
+// Execute lists the custom domains of active tenants, with each tenant and
+// its branding.
+func (uc *ListTenantDomainsUseCase) Execute(ctx context.Context) (*dto.TenantDomainsResponse, error) {
+	opts := domain.DefaultTenantQueryOptions()
+	opts.PageSize = 100
+
+	domains := make(map[string]*dto.TenantBrandingResponse)
+	for {
+		tenants, total, err := uc.tenantRepo.FindAll(ctx, opts)
+		if err != nil {
+			return nil, application.ErrInternal("failed to list tenant domains", err)
+		}
+
+		for _, tenant := range tenants {
+			if !tenant.IsActive() || tenant.Settings().CustomDomain == "" {
+				continue
+			}
+			domains[tenant.Settings().CustomDomain] = mapper.TenantToBrandingResponse(tenant)
+		}
+
+		if len(tenants) == 0 || int64(opts.Page*opts.PageSize) >= total {
+			break
+		}
+		opts.Page++
+	}
+
+	return &dto.TenantDomainsResponse{Domains: domains}, nil
+}
+
 // ChangeTenantPlanUseCase handles changing a tenant's plan.
 type ChangeTenantPlanUseCase struct {
 	tenantRepo  domain.TenantRepository
@@ -0,0 +1,133 @@
+// Package usecase contains the application use cases for the IAM service.
+package usecase
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+
+	"github.com/kilang-desa-murni/crm/internal/iam/application"
+	"github.com/kilang-desa-murni/crm/internal/iam/application/dto"
+	"github.com/kilang-desa-murni/crm/internal/iam/application/ports"
+	"github.com/kilang-desa-murni/crm/internal/iam/domain"
+)
+
+// demoDataServices are the services holding demo data, in seeding order:
+// the sales and notification demo records refer to the demo customers.
+// Purging goes the other way round.
+var demoDataServices = []string{"customer", "sales", "notification"}
+
+// SeedTenantDemoDataUseCase handles seeding a tenant's demo data.
+type SeedTenantDemoDataUseCase struct {
+	tenantRepo  domain.TenantRepository
+	seeder      ports.DemoDataSeeder
+	auditLogger ports.AuditLogger
+}
+
+// NewSeedTenantDemoDataUseCase creates a new SeedTenantDemoDataUseCase.
+func NewSeedTenantDemoDataUseCase(
+	tenantRepo domain.TenantRepository,
+	seeder ports.DemoDataSeeder,
+	auditLogger ports.AuditLogger,
+) *SeedTenantDemoDataUseCase {
+	return &SeedTenantDemoDataUseCase{
+		tenantRepo:  tenantRepo,
+		seeder:      seeder,
+		auditLogger: auditLogger,
+	}
+}
+
+// Execute seeds the demo customers, leads, pipeline, opportunities and
+// notifications of the tenant. Every tenant gets the same data set with its
+// own IDs, so seeding again only creates the records that are missing, and a
+// seeding that failed part way can simply be retried.
+func (uc *SeedTenantDemoDataUseCase) Execute(ctx context.Context, tenantID, seededBy uuid.UUID) (*dto.TenantDemoDataDTO, error) {
+	if _, err := uc.tenantRepo.FindByID(ctx, tenantID); err != nil {
+		return nil, application.ErrNotFound("tenant", tenantID)
+	}
+
+	result := &dto.TenantDemoDataDTO{TenantID: tenantID}
+	for _, service := range demoDataServices {
+		serviceResult, err := uc.seeder.Seed(ctx, service, tenantID, seededBy)
+		if err != nil {
+			return nil, application.ErrInternal("failed to seed demo data in the "+service+" service", err)
+		}
+		addDemoDataResult(result, service, serviceResult)
+	}
+
+	// Log audit
+	_ = uc.auditLogger.Log(ctx, ports.AuditEntry{
+		TenantID:   tenantID,
+		UserID:     ptrToUUID(seededBy),
+		Action:     ports.AuditActionDemoDataSeeded,
+		EntityType: "tenant",
+		EntityID:   ptrToUUID(tenantID),
+		NewValues:  map[string]interface{}{"created": result.Created, "existing": result.Existing},
+	})
+
+	return result, nil
+}
+
+// PurgeTenantDemoDataUseCase handles purging a tenant's demo data.
+type PurgeTenantDemoDataUseCase struct {
+	tenantRepo  domain.TenantRepository
+	seeder      ports.DemoDataSeeder
+	auditLogger ports.AuditLogger
+}
+
+// NewPurgeTenantDemoDataUseCase creates a new PurgeTenantDemoDataUseCase.
+func NewPurgeTenantDemoDataUseCase(
+	tenantRepo domain.TenantRepository,
+	seeder ports.DemoDataSeeder,
+	auditLogger ports.AuditLogger,
+) *PurgeTenantDemoDataUseCase {
+	return &PurgeTenantDemoDataUseCase{
+		tenantRepo:  tenantRepo,
+		seeder:      seeder,
+		auditLogger: auditLogger,
+	}
+}
+
+// Execute deletes the seeded demo records of the tenant and nothing else.
+// Demo records the tenant's own data came to depend on, like a demo
+// opportunity won as a deal, are kept.
+func (uc *PurgeTenantDemoDataUseCase) Execute(ctx context.Context, tenantID, purgedBy uuid.UUID) (*dto.TenantDemoDataDTO, error) {
+	if _, err := uc.tenantRepo.FindByID(ctx, tenantID); err != nil {
+		return nil, application.ErrNotFound("tenant", tenantID)
+	}
+
+	result := &dto.TenantDemoDataDTO{TenantID: tenantID}
+	for i := len(demoDataServices) - 1; i >= 0; i-- {
+		service := demoDataServices[i]
+		serviceResult, err := uc.seeder.Purge(ctx, service, tenantID)
+		if err != nil {
+			return nil, application.ErrInternal("failed to purge demo data in the "+service+" service", err)
+		}
+		addDemoDataResult(result, service, serviceResult)
+	}
+
+	// Log audit
+	_ = uc.auditLogger.Log(ctx, ports.AuditEntry{
+		TenantID:   tenantID,
+		UserID:     ptrToUUID(purgedBy),
+		Action:     ports.AuditActionDemoDataPurged,
+		EntityType: "tenant",
+		EntityID:   ptrToUUID(tenantID),
+		OldValues:  map[string]interface{}{"deleted": result.Deleted},
+	})
+
+	return result, nil
+}
+
+// addDemoDataResult adds the outcome in one service to the tenant's.
+func addDemoDataResult(result *dto.TenantDemoDataDTO, service string, serviceResult ports.DemoDataResult) {
+	result.Created += serviceResult.Created
+	result.Existing += serviceResult.Existing
+	result.Deleted += serviceResult.Deleted
+	result.Services = append(result.Services, &dto.TenantDemoDataServiceDTO{
+		Service:  service,
+		Created:  serviceResult.Created,
+		Existing: serviceResult.Existing,
+		Deleted:  serviceResult.Deleted,
+	})
+}
@@ -0,0 +1,127 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"github.com/kilang-desa-murni/crm/internal/iam/application"
+	"github.com/kilang-desa-murni/crm/internal/iam/application/ports"
+	"github.com/kilang-desa-murni/crm/internal/iam/domain"
+)
+
+// ============================================================================
+// Mock Implementations for Tenant Demo Data Tests
+// ============================================================================
+
+// MockDemoDataSeeder is a mock of ports.DemoDataSeeder that records the
+// services it was called for.
+type MockDemoDataSeeder struct {
+	calls   []string
+	failFor string
+}
+
+func (m *MockDemoDataSeeder) Seed(ctx context.Context, service string, tenantID, seededBy uuid.UUID) (ports.DemoDataResult, error) {
+	m.calls = append(m.calls, service)
+	if service == m.failFor {
+		return ports.DemoDataResult{}, errors.New("service unavailable")
+	}
+	return ports.DemoDataResult{Created: 2, Existing: 1}, nil
+}
+
+func (m *MockDemoDataSeeder) Purge(ctx context.Context, service string, tenantID uuid.UUID) (ports.DemoDataResult, error) {
+	m.calls = append(m.calls, service)
+	if service == m.failFor {
+		return ports.DemoDataResult{}, errors.New("service unavailable")
+	}
+	return ports.DemoDataResult{Deleted: 3}, nil
+}
+
+func newDemoDataTenantRepository(tenant *domain.Tenant) *FullMockTenantRepository {
+	return &FullMockTenantRepository{
+		FindByIDFn: func(ctx context.Context, id uuid.UUID) (*domain.Tenant, error) {
+			if id != tenant.GetID() {
+				return nil, domain.ErrTenantNotFound
+			}
+			return tenant, nil
+		},
+	}
+}
+
+// ============================================================================
+// Tenant Demo Data Tests
+// ============================================================================
+
+func TestSeedTenantDemoDataUseCase_Execute(t *testing.T) {
+	ctx := context.Background()
+	tenant, _ := domain.NewTenant("Kilang Batik", "kilang-batik")
+	seeder := &MockDemoDataSeeder{}
+	auditLogger := &MockAuditLogger{}
+
+	useCase := NewSeedTenantDemoDataUseCase(newDemoDataTenantRepository(tenant), seeder, auditLogger)
+
+	result, err := useCase.Execute(ctx, tenant.GetID(), uuid.New())
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if want := []string{"customer", "sales", "notification"}; !reflect.DeepEqual(seeder.calls, want) {
+		t.Errorf("seeded services %v, want %v", seeder.calls, want)
+	}
+	if result.Created != 6 || result.Existing != 3 || len(result.Services) != 3 {
+		t.Errorf("Execute() = %+v, want the totals of the three services", result)
+	}
+	if len(auditLogger.Calls) != 1 || auditLogger.Calls[0].Action != ports.AuditActionDemoDataSeeded {
+		t.Errorf("expected the seeding to be audited, got %+v", auditLogger.Calls)
+	}
+}
+
+func TestSeedTenantDemoDataUseCase_Execute_ServiceFails(t *testing.T) {
+	ctx := context.Background()
+	tenant, _ := domain.NewTenant("Kilang Batik", "kilang-batik")
+	seeder := &MockDemoDataSeeder{failFor: "sales"}
+	auditLogger := &MockAuditLogger{}
+
+	useCase := NewSeedTenantDemoDataUseCase(newDemoDataTenantRepository(tenant), seeder, auditLogger)
+
+	_, err := useCase.Execute(ctx, tenant.GetID(), uuid.New())
+	var appErr *application.AppError
+	if !errors.As(err, &appErr) || appErr.Code != application.ErrCodeInternal {
+		t.Fatalf("Execute() error = %v, want internal error", err)
+	}
+	if len(seeder.calls) != 2 {
+		t.Errorf("expected seeding to stop at the failed service, got %v", seeder.calls)
+	}
+	if len(auditLogger.Calls) != 0 {
+		t.Errorf("expected a failed seeding not to be audited")
+	}
+}
+
+func TestPurgeTenantDemoDataUseCase_Execute(t *testing.T) {
+	ctx := context.Background()
+	tenant, _ := domain.NewTenant("Kilang Batik", "kilang-batik")
+	seeder := &MockDemoDataSeeder{}
+	auditLogger := &MockAuditLogger{}
+
+	useCase := NewPurgeTenantDemoDataUseCase(newDemoDataTenantRepository(tenant), seeder, auditLogger)
+
+	result, err := useCase.Execute(ctx, tenant.GetID(), uuid.New())
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if want := []string{"notification", "sales", "customer"}; !reflect.DeepEqual(seeder.calls, want) {
+		t.Errorf("purged services %v, want %v", seeder.calls, want)
+	}
+	if result.Deleted != 9 {
+		t.Errorf("Execute() deleted %d records, want 9", result.Deleted)
+	}
+
+	// Unknown tenants are not found
+	_, err = useCase.Execute(ctx, uuid.New(), uuid.New())
+	var appErr *application.AppError
+	if !errors.As(err, &appErr) || appErr.Code != application.ErrCodeNotFound {
+		t.Errorf("Execute() for an unknown tenant error = %v, want not found", err)
+	}
+}
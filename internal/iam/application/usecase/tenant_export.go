@@ -0,0 +1,283 @@
+// Package usecase contains the application use cases for the IAM service.
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/kilang-desa-murni/crm/internal/iam/application"
+	"github.com/kilang-desa-murni/crm/internal/iam/application/dto"
+	"github.com/kilang-desa-murni/crm/internal/iam/application/mapper"
+	"github.com/kilang-desa-murni/crm/internal/iam/application/ports"
+	"github.com/kilang-desa-murni/crm/internal/iam/domain"
+)
+
+// TenantExportConfig holds the settings of tenant exports.
+type TenantExportConfig struct {
+	// Retention is how long the files of a completed export are kept.
+	Retention time.Duration
+	// LinkExpiry is how long a download link stays valid.
+	LinkExpiry time.Duration
+	// HistoryLimit is the number of past exports listed per tenant.
+	HistoryLimit int
+}
+
+// DefaultTenantExportConfig returns the default tenant export settings.
+func DefaultTenantExportConfig() TenantExportConfig {
+	return TenantExportConfig{
+		Retention:    7 * 24 * time.Hour,
+		LinkExpiry:   time.Hour,
+		HistoryLimit: 20,
+	}
+}
+
+// tenantExportKey returns the storage key of a data set file of an export.
+func tenantExportKey(export *domain.TenantExport, dataset domain.TenantExportDataset) string {
+	return fmt.Sprintf("tenant-exports/%s/%s/%s.jsonl", export.TenantID(), export.GetID(), dataset)
+}
+
+// RequestTenantExportUseCase handles requesting a tenant data export.
+type RequestTenantExportUseCase struct {
+	exportRepo  domain.TenantExportRepository
+	tenantRepo  domain.TenantRepository
+	auditLogger ports.AuditLogger
+}
+
+// NewRequestTenantExportUseCase creates a new RequestTenantExportUseCase.
+func NewRequestTenantExportUseCase(
+	exportRepo domain.TenantExportRepository,
+	tenantRepo domain.TenantRepository,
+	auditLogger ports.AuditLogger,
+) *RequestTenantExportUseCase {
+	return &RequestTenantExportUseCase{
+		exportRepo:  exportRepo,
+		tenantRepo:  tenantRepo,
+		auditLogger: auditLogger,
+	}
+}
+
+// Execute queues an export of every data set of the tenant. A tenant has at
+// most one export in progress.
+func (uc *RequestTenantExportUseCase) Execute(ctx context.Context, tenantID, requestedBy uuid.UUID) (*dto.TenantExportDTO, error) {
+	if _, err := uc.tenantRepo.FindByID(ctx, tenantID); err != nil {
+		return nil, application.ErrNotFound("tenant", tenantID)
+	}
+
+	active, err := uc.exportRepo.HasActive(ctx, tenantID)
+	if err != nil {
+		return nil, application.ErrInternal("failed to check tenant exports", err)
+	}
+	if active {
+		return nil, application.ErrConflict("an export of this tenant is already in progress")
+	}
+
+	export, err := domain.NewTenantExport(tenantID, requestedBy)
+	if err != nil {
+		return nil, application.ErrValidation("invalid tenant export", map[string]interface{}{
+			"error": err.Error(),
+		})
+	}
+
+	if err := uc.exportRepo.Create(ctx, export); err != nil {
+		return nil, application.ErrInternal("failed to create tenant export", err)
+	}
+
+	// Log audit
+	_ = uc.auditLogger.Log(ctx, ports.AuditEntry{
+		TenantID:   tenantID,
+		UserID:     ptrToUUID(requestedBy),
+		Action:     ports.AuditActionDataExported,
+		EntityType: "tenant_export",
+		EntityID:   ptrToUUID(export.GetID()),
+	})
+
+	return mapper.TenantExportToDTO(export), nil
+}
+
+// GetTenantExportUseCase handles retrieving tenant exports.
+type GetTenantExportUseCase struct {
+	exportRepo domain.TenantExportRepository
+	storage    ports.ExportStorage
+	config     TenantExportConfig
+}
+
+// NewGetTenantExportUseCase creates a new GetTenantExportUseCase.
+func NewGetTenantExportUseCase(
+	exportRepo domain.TenantExportRepository,
+	storage ports.ExportStorage,
+	config TenantExportConfig,
+) *GetTenantExportUseCase {
+	return &GetTenantExportUseCase{
+		exportRepo: exportRepo,
+		storage:    storage,
+		config:     config,
+	}
+}
+
+// Execute retrieves an export of the tenant. The files of a completed export
+// come with signed download links.
+func (uc *GetTenantExportUseCase) Execute(ctx context.Context, tenantID, exportID uuid.UUID) (*dto.TenantExportDTO, error) {
+	export, err := uc.exportRepo.FindByID(ctx, tenantID, exportID)
+	if err != nil {
+		return nil, application.ErrNotFound("tenant export", exportID)
+	}
+
+	result := mapper.TenantExportToDTO(export)
+	if export.Status() != domain.TenantExportStatusCompleted || export.IsExpired(time.Now().UTC()) {
+		return result, nil
+	}
+
+	for i, file := range export.Files() {
+		url, err := uc.storage.SignedURL(ctx, file.Key, uc.config.LinkExpiry)
+		if err != nil {
+			return nil, application.ErrInternal("failed to sign download link", err)
+		}
+		result.Files[i].DownloadURL = url
+	}
+
+	return result, nil
+}
+
+// List lists the latest exports of the tenant, newest first.
+func (uc *GetTenantExportUseCase) List(ctx context.Context, tenantID uuid.UUID) (*dto.ListTenantExportsResponse, error) {
+	exports, err := uc.exportRepo.FindByTenant(ctx, tenantID, uc.config.HistoryLimit)
+	if err != nil {
+		return nil, application.ErrInternal("failed to list tenant exports", err)
+	}
+
+	result := make([]*dto.TenantExportDTO, len(exports))
+	for i, export := range exports {
+		result[i] = mapper.TenantExportToDTO(export)
+	}
+
+	return &dto.ListTenantExportsResponse{Exports: result}, nil
+}
+
+// RunTenantExportUseCase handles producing queued tenant exports.
+type RunTenantExportUseCase struct {
+	exportRepo domain.TenantExportRepository
+	source     ports.TenantDataSource
+	storage    ports.ExportStorage
+	config     TenantExportConfig
+}
+
+// NewRunTenantExportUseCase creates a new RunTenantExportUseCase.
+func NewRunTenantExportUseCase(
+	exportRepo domain.TenantExportRepository,
+	source ports.TenantDataSource,
+	storage ports.ExportStorage,
+	config TenantExportConfig,
+) *RunTenantExportUseCase {
+	return &RunTenantExportUseCase{
+		exportRepo: exportRepo,
+		source:     source,
+		storage:    storage,
+		config:     config,
+	}
+}
+
+// Execute produces the oldest queued export, writing its data sets one by
+// one so its progress can be followed. It returns false if no export was
+// queued.
+func (uc *RunTenantExportUseCase) Execute(ctx context.Context) (bool, error) {
+	export, err := uc.exportRepo.ClaimPending(ctx)
+	if err != nil {
+		return false, application.ErrInternal("failed to claim tenant export", err)
+	}
+	if export == nil {
+		return false, nil
+	}
+
+	for _, file := range export.Files() {
+		if file.IsDone() {
+			continue
+		}
+		if err := uc.exportDataset(ctx, export, file.Dataset); err != nil {
+			return true, uc.fail(ctx, export, err)
+		}
+		if err := uc.exportRepo.Update(ctx, export); err != nil {
+			return true, application.ErrInternal("failed to record tenant export progress", err)
+		}
+	}
+
+	if err := export.Complete(uc.config.Retention); err != nil {
+		return true, uc.fail(ctx, export, err)
+	}
+	if err := uc.exportRepo.Update(ctx, export); err != nil {
+		return true, application.ErrInternal("failed to complete tenant export", err)
+	}
+
+	return true, nil
+}
+
+// exportDataset streams a data set from its service straight to storage.
+func (uc *RunTenantExportUseCase) exportDataset(ctx context.Context, export *domain.TenantExport, dataset domain.TenantExportDataset) error {
+	key := tenantExportKey(export, dataset)
+	reader, writer := io.Pipe()
+
+	var rows int64
+	exported := make(chan error, 1)
+	go func() {
+		n, err := uc.source.ExportDataset(ctx, export.TenantID(), dataset, writer)
+		rows = n
+		writer.CloseWithError(err)
+		exported <- err
+	}()
+
+	size, err := uc.storage.Put(ctx, key, reader)
+	// Unblock the source if storage gave up before reading everything
+	reader.CloseWithError(err)
+	if exportErr := <-exported; exportErr != nil {
+		return fmt.Errorf("failed to export %s from the %s service: %w", dataset, dataset.Service(), exportErr)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to store %s: %w", dataset, err)
+	}
+
+	return export.CompleteFile(dataset, key, rows, size)
+}
+
+// fail marks the export as failed and deletes the files already written.
+// This happens even if ctx was cancelled, so an interrupted export does not
+// stay running and block the tenant's next one.
+func (uc *RunTenantExportUseCase) fail(ctx context.Context, export *domain.TenantExport, cause error) error {
+	ctx = context.WithoutCancel(ctx)
+	uc.deleteFiles(ctx, export)
+	export.Fail(cause.Error())
+	if err := uc.exportRepo.Update(ctx, export); err != nil {
+		return application.ErrInternal("failed to record tenant export failure", err)
+	}
+	return application.ErrInternal("tenant export failed", cause)
+}
+
+// deleteFiles deletes the files of the export written so far.
+func (uc *RunTenantExportUseCase) deleteFiles(ctx context.Context, export *domain.TenantExport) {
+	for _, file := range export.Files() {
+		if file.Key != "" {
+			_ = uc.storage.Delete(ctx, file.Key)
+		}
+	}
+}
+
+// PurgeExpired deletes the files of up to limit exports past their
+// retention and returns how many exports expired.
+func (uc *RunTenantExportUseCase) PurgeExpired(ctx context.Context, limit int) (int, error) {
+	exports, err := uc.exportRepo.FindExpired(ctx, time.Now().UTC(), limit)
+	if err != nil {
+		return 0, application.ErrInternal("failed to find expired tenant exports", err)
+	}
+
+	for _, export := range exports {
+		uc.deleteFiles(ctx, export)
+		export.Expire()
+		if err := uc.exportRepo.Update(ctx, export); err != nil {
+			return 0, application.ErrInternal("failed to expire tenant export", err)
+		}
+	}
+
+	return len(exports), nil
+}
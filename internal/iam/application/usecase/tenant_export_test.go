@@ -0,0 +1,276 @@
+package usecase
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/kilang-desa-murni/crm/internal/iam/application"
+	"github.com/kilang-desa-murni/crm/internal/iam/domain"
+)
+
+// ============================================================================
+// Mock Implementations for Tenant Export Tests
+// ============================================================================
+
+// MockTenantExportRepository is an in-memory mock of domain.TenantExportRepository.
+type MockTenantExportRepository struct {
+	mu      sync.Mutex
+	exports map[uuid.UUID]*domain.TenantExport
+	updates int
+}
+
+func NewMockTenantExportRepository(exports ...*domain.TenantExport) *MockTenantExportRepository {
+	m := &MockTenantExportRepository{exports: make(map[uuid.UUID]*domain.TenantExport)}
+	for _, export := range exports {
+		m.exports[export.GetID()] = export
+	}
+	return m
+}
+
+func (m *MockTenantExportRepository) Create(ctx context.Context, export *domain.TenantExport) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.exports[export.GetID()] = export
+	return nil
+}
+
+func (m *MockTenantExportRepository) Update(ctx context.Context, export *domain.TenantExport) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.exports[export.GetID()] = export
+	m.updates++
+	return nil
+}
+
+func (m *MockTenantExportRepository) FindByID(ctx context.Context, tenantID, id uuid.UUID) (*domain.TenantExport, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if export, ok := m.exports[id]; ok && export.TenantID() == tenantID {
+		return export, nil
+	}
+	return nil, domain.ErrTenantExportNotFound
+}
+
+func (m *MockTenantExportRepository) FindByTenant(ctx context.Context, tenantID uuid.UUID, limit int) ([]*domain.TenantExport, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var result []*domain.TenantExport
+	for _, export := range m.exports {
+		if export.TenantID() == tenantID {
+			result = append(result, export)
+		}
+	}
+	return result, nil
+}
+
+func (m *MockTenantExportRepository) HasActive(ctx context.Context, tenantID uuid.UUID) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, export := range m.exports {
+		if export.TenantID() == tenantID && export.Status().IsActive() {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (m *MockTenantExportRepository) ClaimPending(ctx context.Context) (*domain.TenantExport, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, export := range m.exports {
+		if export.Status() == domain.TenantExportStatusPending {
+			return export, export.Start()
+		}
+	}
+	return nil, nil
+}
+
+func (m *MockTenantExportRepository) FindExpired(ctx context.Context, now time.Time, limit int) ([]*domain.TenantExport, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var result []*domain.TenantExport
+	for _, export := range m.exports {
+		if export.Status() == domain.TenantExportStatusCompleted && export.IsExpired(now) {
+			result = append(result, export)
+		}
+	}
+	return result, nil
+}
+
+// MockTenantDataSource writes a fixed number of records per data set.
+type MockTenantDataSource struct {
+	Rows       int64
+	FailOnData domain.TenantExportDataset
+}
+
+func (m *MockTenantDataSource) ExportDataset(ctx context.Context, tenantID uuid.UUID, dataset domain.TenantExportDataset, w io.Writer) (int64, error) {
+	if dataset == m.FailOnData {
+		return 0, errors.New("service unavailable")
+	}
+	for i := int64(0); i < m.Rows; i++ {
+		if _, err := fmt.Fprintf(w, "{\"id\":%d}\n", i); err != nil {
+			return i, err
+		}
+	}
+	return m.Rows, nil
+}
+
+// MockExportStorage keeps stored files in memory.
+type MockExportStorage struct {
+	mu    sync.Mutex
+	files map[string][]byte
+}
+
+func NewMockExportStorage() *MockExportStorage {
+	return &MockExportStorage{files: make(map[string][]byte)}
+}
+
+func (m *MockExportStorage) Put(ctx context.Context, key string, r io.Reader) (int64, error) {
+	var buf bytes.Buffer
+	n, err := io.Copy(&buf, r)
+	if err != nil {
+		return n, err
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.files[key] = buf.Bytes()
+	return n, nil
+}
+
+func (m *MockExportStorage) SignedURL(ctx context.Context, key string, expiry time.Duration) (string, error) {
+	return "https://exports.example/" + key + "?signature=test", nil
+}
+
+func (m *MockExportStorage) Delete(ctx context.Context, key string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.files, key)
+	return nil
+}
+
+// ============================================================================
+// Tenant Export Tests
+// ============================================================================
+
+func TestRequestTenantExportUseCase_Execute(t *testing.T) {
+	ctx := context.Background()
+	tenant, _ := domain.NewTenant("Kilang Batik", "kilang-batik")
+	tenantRepo := &FullMockTenantRepository{
+		FindByIDFn: func(ctx context.Context, id uuid.UUID) (*domain.Tenant, error) {
+			return tenant, nil
+		},
+	}
+	exportRepo := NewMockTenantExportRepository()
+	auditLogger := &MockAuditLogger{}
+
+	useCase := NewRequestTenantExportUseCase(exportRepo, tenantRepo, auditLogger)
+
+	result, err := useCase.Execute(ctx, tenant.GetID(), uuid.New())
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if result.Status != domain.TenantExportStatusPending.String() || len(result.Files) != len(domain.TenantExportDatasets()) {
+		t.Errorf("expected a pending export of every data set, got %+v", result)
+	}
+	if len(auditLogger.Calls) != 1 {
+		t.Errorf("expected the export request to be audited, got %d entries", len(auditLogger.Calls))
+	}
+
+	// A second export waits for the first one to finish
+	_, err = useCase.Execute(ctx, tenant.GetID(), uuid.New())
+	var appErr *application.AppError
+	if !errors.As(err, &appErr) || appErr.Code != application.ErrCodeConflict {
+		t.Errorf("Execute() with an export in progress error = %v, want conflict", err)
+	}
+}
+
+func TestRunTenantExportUseCase_Execute(t *testing.T) {
+	ctx := context.Background()
+	export, _ := domain.NewTenantExport(uuid.New(), uuid.New())
+	exportRepo := NewMockTenantExportRepository(export)
+	storage := NewMockExportStorage()
+	config := DefaultTenantExportConfig()
+
+	useCase := NewRunTenantExportUseCase(exportRepo, &MockTenantDataSource{Rows: 3}, storage, config)
+
+	processed, err := useCase.Execute(ctx)
+	if err != nil || !processed {
+		t.Fatalf("Execute() = %v, %v; want an export processed", processed, err)
+	}
+	if export.Status() != domain.TenantExportStatusCompleted {
+		t.Fatalf("expected the export to complete, got %v (%s)", export.Status(), export.Error())
+	}
+	if exportRepo.updates != len(domain.TenantExportDatasets())+1 {
+		t.Errorf("expected progress to be recorded per data set, got %d updates", exportRepo.updates)
+	}
+	for _, file := range export.Files() {
+		if file.Rows != 3 || int64(len(storage.files[file.Key])) != file.SizeBytes {
+			t.Errorf("unexpected file %+v", file)
+		}
+	}
+
+	result, err := NewGetTenantExportUseCase(exportRepo, storage, config).Execute(ctx, export.TenantID(), export.GetID())
+	if err != nil {
+		t.Fatalf("GetTenantExportUseCase.Execute() error = %v", err)
+	}
+	for _, file := range result.Files {
+		if file.DownloadURL == "" {
+			t.Errorf("expected a download link for %s", file.Dataset)
+		}
+	}
+
+	// Nothing left to do
+	if processed, _ := useCase.Execute(ctx); processed {
+		t.Error("expected no export to be processed")
+	}
+}
+
+func TestRunTenantExportUseCase_Execute_SourceFails(t *testing.T) {
+	ctx := context.Background()
+	export, _ := domain.NewTenantExport(uuid.New(), uuid.New())
+	exportRepo := NewMockTenantExportRepository(export)
+	storage := NewMockExportStorage()
+
+	source := &MockTenantDataSource{Rows: 2, FailOnData: domain.ExportDatasetLeads}
+	useCase := NewRunTenantExportUseCase(exportRepo, source, storage, DefaultTenantExportConfig())
+
+	if _, err := useCase.Execute(ctx); err == nil {
+		t.Fatal("Execute() expected an error")
+	}
+	if export.Status() != domain.TenantExportStatusFailed || export.Error() == "" {
+		t.Errorf("expected a failed export with its reason, got %v", export.Status())
+	}
+	if len(storage.files) != 0 {
+		t.Errorf("expected the files written before the failure to be deleted, got %d", len(storage.files))
+	}
+}
+
+func TestRunTenantExportUseCase_PurgeExpired(t *testing.T) {
+	ctx := context.Background()
+	export, _ := domain.NewTenantExport(uuid.New(), uuid.New())
+	exportRepo := NewMockTenantExportRepository(export)
+	storage := NewMockExportStorage()
+
+	config := DefaultTenantExportConfig()
+	config.Retention = -time.Minute
+	useCase := NewRunTenantExportUseCase(exportRepo, &MockTenantDataSource{Rows: 1}, storage, config)
+	if _, err := useCase.Execute(ctx); err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+
+	purged, err := useCase.PurgeExpired(ctx, 10)
+	if err != nil || purged != 1 {
+		t.Fatalf("PurgeExpired() = %d, %v; want 1", purged, err)
+	}
+	if export.Status() != domain.TenantExportStatusExpired || len(storage.files) != 0 {
+		t.Errorf("expected an expired export without files, got %v with %d files", export.Status(), len(storage.files))
+	}
+}
@@ -23,6 +23,7 @@ type FullMockTenantRepository struct {
 	DeleteFn       func(ctx context.Context, id uuid.UUID) error
 	FindByIDFn     func(ctx context.Context, id uuid.UUID) (*domain.Tenant, error)
 	FindBySlugFn   func(ctx context.Context, slug string) (*domain.Tenant, error)
+	FindByCustomDomainFn func(ctx context.Context, host string) (*domain.Tenant, error)
 	FindAllFn      func(ctx context.Context, opts domain.TenantQueryOptions) ([]*domain.Tenant, int64, error)
 	ExistsBySlugFn func(ctx context.Context, slug string) (bool, error)
 	CountFn        func(ctx context.Context) (int64, error)
@@ -63,6 +64,13 @@ func (m *FullMockTenantRepository) FindBySlug(ctx context.Context, slug string)
 	return nil, errors.New("not found")
 }
 
+func (m *FullMockTenantRepository) FindByCustomDomain(ctx context.Context, host string) (*domain.Tenant, error) {
+	if m.FindByCustomDomainFn != nil {
+		return m.FindByCustomDomainFn(ctx, host)
+	}
+	return nil, domain.ErrTenantNotFound
+}
+
 func (m *FullMockTenantRepository) FindAll(ctx context.Context, opts domain.TenantQueryOptions) ([]*domain.Tenant, int64, error) {
 	if m.FindAllFn != nil {
 		return m.FindAllFn(ctx, opts)
@@ -639,6 +647,192 @@ func TestUpdateTenantSettingsUseCase_Execute_Success(t *testing.T) {
 	}
 }
 
+func TestUpdateTenantSettingsUseCase_Execute_InvalidOrigin(t *testing.T) {
+	ctx := context.Background()
+	tenant := createTestTenant(t)
+
+	tenantRepo := &FullMockTenantRepository{
+		FindByIDFn: func(ctx context.Context, id uuid.UUID) (*domain.Tenant, error) {
+			return tenant, nil
+		},
+	}
+
+	useCase := NewUpdateTenantSettingsUseCase(tenantRepo, &MockOutboxRepository{}, &MockTransactionManager{}, &MockAuditLogger{})
+
+	origins := []string{"https://portal.example.com/app"}
+	_, err := useCase.Execute(ctx, tenant.GetID(), &dto.UpdateTenantSettingsRequest{AllowedOrigins: &origins})
+
+	if err == nil {
+		t.Fatal("Execute() should return error for an origin with a path")
+	}
+	var appErr *application.AppError
+	if !errors.As(err, &appErr) {
+		t.Fatalf("Execute() error should be AppError, got %T", err)
+	}
+	if appErr.Code != application.ErrCodeValidation {
+		t.Errorf("Execute() error code = %s, want %s", appErr.Code, application.ErrCodeValidation)
+	}
+}
+
+// ============================================================================
+// ListTenantOriginsUseCase Tests
+// ============================================================================
+
+func TestListTenantOriginsUseCase_Execute_Success(t *testing.T) {
+	ctx := context.Background()
+	tenant1 := createTestTenant(t)
+	_ = tenant1.SetAllowedOrigins([]string{"https://portal.example.com", "https://*.shop.example.com"})
+	tenant2 := createTestTenant(t)
+	_ = tenant2.SetAllowedOrigins([]string{"https://portal.example.com"})
+	suspended := createTestTenant(t)
+	_ = suspended.SetAllowedOrigins([]string{"https://suspended.example.com"})
+	_ = suspended.Suspend("unpaid")
+
+	tenantRepo := &FullMockTenantRepository{
+		FindAllFn: func(ctx context.Context, opts domain.TenantQueryOptions) ([]*domain.Tenant, int64, error) {
+			// Two pages of 100 tenants
+			if opts.Page == 1 {
+				return []*domain.Tenant{tenant1, suspended}, 102, nil
+			}
+			return []*domain.Tenant{tenant2}, 102, nil
+		},
+	}
+
+	useCase := NewListTenantOriginsUseCase(tenantRepo)
+
+	result, err := useCase.Execute(ctx)
+
+	if err != nil {
+		t.Fatalf("Execute() unexpected error = %v", err)
+	}
+	if got := result.Origins["https://portal.example.com"]; len(got) != 2 || got[0] != tenant1.GetID() || got[1] != tenant2.GetID() {
+		t.Errorf("Execute() portal tenants = %v, want both tenants", got)
+	}
+	if got := result.Origins["https://*.shop.example.com"]; len(got) != 1 {
+		t.Errorf("Execute() wildcard tenants = %v, want the first tenant", got)
+	}
+	if _, ok := result.Origins["https://suspended.example.com"]; ok {
+		t.Error("Execute() should skip inactive tenants")
+	}
+}
+
+func TestUpdateTenantSettingsUseCase_Execute_CustomDomainTaken(t *testing.T) {
+	ctx := context.Background()
+	tenant := createTestTenant(t)
+	other := createTestTenant(t)
+	_ = other.SetCustomDomain("crm.theirbrand.com")
+
+	tenantRepo := &FullMockTenantRepository{
+		FindByIDFn: func(ctx context.Context, id uuid.UUID) (*domain.Tenant, error) {
+			return tenant, nil
+		},
+		FindByCustomDomainFn: func(ctx context.Context, host string) (*domain.Tenant, error) {
+			if host == "crm.theirbrand.com" {
+				return other, nil
+			}
+			return nil, domain.ErrTenantNotFound
+		},
+	}
+
+	useCase := NewUpdateTenantSettingsUseCase(tenantRepo, &MockOutboxRepository{}, &MockTransactionManager{}, &MockAuditLogger{})
+
+	host := "CRM.TheirBrand.com"
+	_, err := useCase.Execute(ctx, tenant.GetID(), &dto.UpdateTenantSettingsRequest{CustomDomain: &host})
+
+	var appErr *application.AppError
+	if !errors.As(err, &appErr) || appErr.Code != application.ErrCodeConflict {
+		t.Fatalf("Execute() error = %v, want a conflict for another tenant's domain", err)
+	}
+
+	host = "crm.ourbrand.com"
+	result, err := useCase.Execute(ctx, tenant.GetID(), &dto.UpdateTenantSettingsRequest{CustomDomain: &host})
+	if err != nil {
+		t.Fatalf("Execute() unexpected error = %v", err)
+	}
+	if result.Tenant.Settings.CustomDomain != "crm.ourbrand.com" {
+		t.Errorf("Execute() custom domain = %q, want crm.ourbrand.com", result.Tenant.Settings.CustomDomain)
+	}
+}
+
+// ============================================================================
+// TenantBrandingUseCase Tests
+// ============================================================================
+
+func TestTenantBrandingUseCase_Update(t *testing.T) {
+	ctx := context.Background()
+	tenant := createTestTenant(t)
+
+	var updated bool
+	tenantRepo := &FullMockTenantRepository{
+		FindByIDFn: func(ctx context.Context, id uuid.UUID) (*domain.Tenant, error) {
+			return tenant, nil
+		},
+		UpdateFn: func(ctx context.Context, t *domain.Tenant) error {
+			updated = true
+			return nil
+		},
+	}
+
+	useCase := NewTenantBrandingUseCase(tenantRepo, &MockOutboxRepository{}, &MockTransactionManager{}, &MockAuditLogger{})
+
+	result, err := useCase.Update(ctx, tenant.GetID(), &dto.UpdateTenantBrandingRequest{
+		LogoURL:          "https://cdn.theirbrand.com/logo.png",
+		PrimaryColor:     "#1A73E8",
+		EmailFromAddress: "sales@theirbrand.com",
+	})
+	if err != nil {
+		t.Fatalf("Update() unexpected error = %v", err)
+	}
+	if !updated || result.Branding == nil || result.Branding.PrimaryColor != "#1a73e8" {
+		t.Errorf("Update() = %+v, want the normalized branding saved", result.Branding)
+	}
+
+	_, err = useCase.Update(ctx, tenant.GetID(), &dto.UpdateTenantBrandingRequest{PrimaryColor: "blue!!"})
+	var appErr *application.AppError
+	if !errors.As(err, &appErr) || appErr.Code != application.ErrCodeValidation {
+		t.Errorf("Update() error = %v, want a validation error", err)
+	}
+
+	result, err = useCase.Update(ctx, tenant.GetID(), &dto.UpdateTenantBrandingRequest{})
+	if err != nil || result.Branding != nil {
+		t.Errorf("Update() with no fields = %+v, %v, want the branding cleared", result, err)
+	}
+}
+
+// ============================================================================
+// ListTenantDomainsUseCase Tests
+// ============================================================================
+
+func TestListTenantDomainsUseCase_Execute_Success(t *testing.T) {
+	ctx := context.Background()
+	branded := createTestTenant(t)
+	_ = branded.SetCustomDomain("crm.theirbrand.com")
+	_ = branded.SetBranding(&domain.TenantBranding{PrimaryColor: "#1a73e8"})
+	plain := createTestTenant(t)
+	suspended := createTestTenant(t)
+	_ = suspended.SetCustomDomain("crm.suspended.com")
+	_ = suspended.Suspend("unpaid")
+
+	tenantRepo := &FullMockTenantRepository{
+		FindAllFn: func(ctx context.Context, opts domain.TenantQueryOptions) ([]*domain.Tenant, int64, error) {
+			return []*domain.Tenant{branded, plain, suspended}, 3, nil
+		},
+	}
+
+	result, err := NewListTenantDomainsUseCase(tenantRepo).Execute(ctx)
+
+	if err != nil {
+		t.Fatalf("Execute() unexpected error = %v", err)
+	}
+	if len(result.Domains) != 1 {
+		t.Fatalf("Execute() domains = %v, want only the active tenant's domain", result.Domains)
+	}
+	got := result.Domains["crm.theirbrand.com"]
+	if got == nil || got.TenantID != branded.GetID() || got.Branding == nil || got.Branding.PrimaryColor != "#1a73e8" {
+		t.Errorf("Execute() domain = %+v, want the tenant and its branding", got)
+	}
+}
+
 // ============================================================================
 // ChangeTenantPlanUseCase Tests
 // ============================================================================
@@ -3,6 +3,7 @@ package usecase
 
 import (
 	"context"
+	"encoding/json"
 
 	"github.com/google/uuid"
 
@@ -163,6 +164,74 @@ func (uc *ListUsersUseCase) Execute(ctx context.Context, req *dto.ListUsersReque
 	}, nil
 }
 
+// BatchGetUsersUseCase handles retrieving several users by ID in one call,
+// for services hydrating user references.
+type BatchGetUsersUseCase struct {
+	userRepo domain.UserRepository
+}
+
+// NewBatchGetUsersUseCase creates a new BatchGetUsersUseCase.
+func NewBatchGetUsersUseCase(userRepo domain.UserRepository) *BatchGetUsersUseCase {
+	return &BatchGetUsersUseCase{
+		userRepo: userRepo,
+	}
+}
+
+// Execute retrieves the requested users of the tenant. Users are returned in
+// request order without their roles; IDs that match no user of the tenant are
+// reported as missing.
+func (uc *BatchGetUsersUseCase) Execute(ctx context.Context, req *dto.BatchGetUsersRequest) (*dto.BatchGetUsersResponse, error) {
+	ids := uniqueIDs(req.IDs)
+	if len(ids) == 0 {
+		return nil, application.ErrValidation("ids is required", nil)
+	}
+	if len(ids) > dto.MaxBatchGetUsers {
+		return nil, application.ErrValidation("too many ids", map[string]interface{}{"max": dto.MaxBatchGetUsers})
+	}
+
+	users, _, err := uc.userRepo.FindByTenant(ctx, req.TenantID, domain.UserQueryOptions{
+		Page:     1,
+		PageSize: len(ids),
+		IDs:      ids,
+	})
+	if err != nil {
+		return nil, application.ErrInternal("failed to get users", err)
+	}
+
+	byID := make(map[uuid.UUID]*domain.User, len(users))
+	for _, user := range users {
+		byID[user.GetID()] = user
+	}
+
+	resp := &dto.BatchGetUsersResponse{
+		Found:   make([]*dto.UserDTO, 0, len(users)),
+		Missing: []uuid.UUID{},
+	}
+	for _, id := range ids {
+		if user, ok := byID[id]; ok {
+			resp.Found = append(resp.Found, mapper.UserToDTO(user))
+		} else {
+			resp.Missing = append(resp.Missing, id)
+		}
+	}
+	return resp, nil
+}
+
+// uniqueIDs returns ids without duplicates or nil IDs, in their original
+// order.
+func uniqueIDs(ids []uuid.UUID) []uuid.UUID {
+	seen := make(map[uuid.UUID]bool, len(ids))
+	unique := make([]uuid.UUID, 0, len(ids))
+	for _, id := range ids {
+		if id == uuid.Nil || seen[id] {
+			continue
+		}
+		seen[id] = true
+		unique = append(unique, id)
+	}
+	return unique
+}
+
 // ============================================================================
 // Mutation Use Cases
 // ============================================================================
@@ -220,10 +289,15 @@ func (uc *UpdateUserUseCase) Execute(ctx context.Context, userID, tenantID uuid.
 
 		// Save domain events to outbox
 		for _, event := range user.GetDomainEvents() {
+			payload, err := json.Marshal(event)
+			if err != nil {
+				return err
+			}
 			outboxEntry := &domain.OutboxEntry{
 				EventType:     event.EventType(),
 				AggregateID:   event.AggregateID(),
 				AggregateType: event.AggregateType(),
+				Payload:       payload,
 			}
 			if err := uc.outboxRepo.Create(txCtx, outboxEntry); err != nil {
 				return err
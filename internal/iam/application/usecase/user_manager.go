@@ -0,0 +1,221 @@
+// Package usecase contains the application use cases for the IAM service.
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+
+	"github.com/google/uuid"
+
+	"github.com/kilang-desa-murni/crm/internal/iam/application"
+	"github.com/kilang-desa-murni/crm/internal/iam/application/dto"
+	"github.com/kilang-desa-murni/crm/internal/iam/application/mapper"
+	"github.com/kilang-desa-murni/crm/internal/iam/application/ports"
+	"github.com/kilang-desa-murni/crm/internal/iam/domain"
+)
+
+// AssignManagerUseCase handles setting the manager a user reports to.
+type AssignManagerUseCase struct {
+	userRepo    domain.UserRepository
+	outboxRepo  domain.OutboxRepository
+	txManager   ports.TransactionManager
+	auditLogger ports.AuditLogger
+}
+
+// NewAssignManagerUseCase creates a new AssignManagerUseCase.
+func NewAssignManagerUseCase(
+	userRepo domain.UserRepository,
+	outboxRepo domain.OutboxRepository,
+	txManager ports.TransactionManager,
+	auditLogger ports.AuditLogger,
+) *AssignManagerUseCase {
+	return &AssignManagerUseCase{
+		userRepo:    userRepo,
+		outboxRepo:  outboxRepo,
+		txManager:   txManager,
+		auditLogger: auditLogger,
+	}
+}
+
+// Execute makes the user report to the requested manager, or to no one if
+// the request names none. A manager the user is already above is refused, so
+// the organization chart never has cycles.
+func (uc *AssignManagerUseCase) Execute(ctx context.Context, userID, tenantID uuid.UUID, req *dto.AssignManagerRequest, assignedBy *uuid.UUID) (*dto.UserDTO, error) {
+	user, err := uc.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return nil, application.ErrNotFound("user", userID)
+	}
+
+	// Verify user belongs to tenant
+	if user.TenantID() != tenantID {
+		return nil, application.ErrForbidden("user does not belong to this tenant")
+	}
+
+	oldValues := map[string]interface{}{
+		"manager_id": user.ManagerID(),
+	}
+
+	if req.ManagerID == nil {
+		user.RemoveManager()
+	} else {
+		manager, err := uc.userRepo.FindByID(ctx, *req.ManagerID)
+		if err != nil || manager.TenantID() != tenantID {
+			return nil, application.ErrValidation("manager not found", map[string]interface{}{
+				"manager_id": req.ManagerID.String(),
+			})
+		}
+
+		if err := user.AssignManager(manager); err != nil {
+			return nil, managerError(err)
+		}
+
+		line, err := reportingLine(ctx, uc.userRepo, manager)
+		if err != nil {
+			return nil, application.ErrInternal("failed to load reporting line", err)
+		}
+		if err := user.CheckReportingLine(append([]*domain.User{manager}, line...)); err != nil {
+			return nil, managerError(err)
+		}
+	}
+
+	events := user.GetDomainEvents()
+	if len(events) == 0 {
+		return mapper.UserToDTO(user), nil
+	}
+
+	// Execute in transaction
+	err = uc.txManager.WithTransaction(ctx, func(txCtx context.Context) error {
+		if err := uc.userRepo.Update(txCtx, user); err != nil {
+			return err
+		}
+
+		// Save domain events to outbox
+		for _, event := range events {
+			payload, err := json.Marshal(event)
+			if err != nil {
+				return err
+			}
+			outboxEntry := &domain.OutboxEntry{
+				EventType:     event.EventType(),
+				AggregateID:   event.AggregateID(),
+				AggregateType: event.AggregateType(),
+				Payload:       payload,
+			}
+			if err := uc.outboxRepo.Create(txCtx, outboxEntry); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return nil, application.ErrInternal("failed to assign manager", err)
+	}
+
+	user.ClearDomainEvents()
+
+	// Log audit
+	_ = uc.auditLogger.Log(ctx, ports.AuditEntry{
+		TenantID:   tenantID,
+		UserID:     assignedBy,
+		Action:     ports.AuditActionManagerAssigned,
+		EntityType: "user",
+		EntityID:   ptrToUUID(userID),
+		OldValues:  oldValues,
+		NewValues: map[string]interface{}{
+			"manager_id": user.ManagerID(),
+		},
+	})
+
+	return mapper.UserToDTO(user), nil
+}
+
+// managerError converts a domain error of assigning a manager to an
+// application error.
+func managerError(err error) error {
+	if errors.Is(err, domain.ErrManagerCycle) || errors.Is(err, domain.ErrReportingLineTooDeep) {
+		return application.ErrConflict(err.Error())
+	}
+	return application.ErrValidation(err.Error(), map[string]interface{}{
+		"manager_id": err.Error(),
+	})
+}
+
+// GetReportingLineUseCase handles retrieving a user's place in the
+// organization chart, such as to find who approves their requests.
+type GetReportingLineUseCase struct {
+	userRepo domain.UserRepository
+}
+
+// NewGetReportingLineUseCase creates a new GetReportingLineUseCase.
+func NewGetReportingLineUseCase(userRepo domain.UserRepository) *GetReportingLineUseCase {
+	return &GetReportingLineUseCase{
+		userRepo: userRepo,
+	}
+}
+
+// Execute retrieves the managers above a user, nearest first, and the users
+// reporting to them directly.
+func (uc *GetReportingLineUseCase) Execute(ctx context.Context, userID, tenantID uuid.UUID) (*dto.ReportingLineDTO, error) {
+	user, err := uc.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return nil, application.ErrNotFound("user", userID)
+	}
+
+	// Verify user belongs to tenant
+	if user.TenantID() != tenantID {
+		return nil, application.ErrForbidden("user does not belong to this tenant")
+	}
+
+	managers, err := reportingLine(ctx, uc.userRepo, user)
+	if err != nil {
+		return nil, application.ErrInternal("failed to load reporting line", err)
+	}
+
+	reports, err := uc.userRepo.FindByManagerID(ctx, userID)
+	if err != nil {
+		return nil, application.ErrInternal("failed to load direct reports", err)
+	}
+
+	return &dto.ReportingLineDTO{
+		User:          mapper.ReportingLineUserToDTO(user),
+		Managers:      mapper.ReportingLineUsersToDTO(managers),
+		DirectReports: mapper.ReportingLineUsersToDTO(reports),
+	}, nil
+}
+
+// reportingLine returns the managers above a user, nearest first. The line
+// ends at a manager who reports to no one, or who was deleted or belongs to
+// another tenant. It also ends at a manager seen before and after
+// domain.MaxReportingLineDepth+1 managers, so it stays bounded however the
+// chart was saved; the extra manager lets callers tell a line is too deep.
+func reportingLine(ctx context.Context, userRepo domain.UserRepository, user *domain.User) ([]*domain.User, error) {
+	var line []*domain.User
+	seen := map[uuid.UUID]bool{user.GetID(): true}
+
+	for current := user; current.ManagerID() != nil && len(line) <= domain.MaxReportingLineDepth; {
+		managerID := *current.ManagerID()
+		if seen[managerID] {
+			break
+		}
+		seen[managerID] = true
+
+		manager, err := userRepo.FindByID(ctx, managerID)
+		if err != nil {
+			if errors.Is(err, domain.ErrUserNotFound) {
+				break
+			}
+			return nil, err
+		}
+		if manager.TenantID() != user.TenantID() {
+			break
+		}
+
+		line = append(line, manager)
+		current = manager
+	}
+
+	return line, nil
+}
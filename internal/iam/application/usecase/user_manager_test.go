@@ -0,0 +1,208 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"github.com/kilang-desa-murni/crm/internal/iam/application"
+	"github.com/kilang-desa-murni/crm/internal/iam/application/dto"
+	"github.com/kilang-desa-murni/crm/internal/iam/domain"
+)
+
+// newOrgChartUserRepository returns a user repository holding users, who
+// report to each other as their managers are assigned.
+func newOrgChartUserRepository(users ...*domain.User) *MockUserRepository {
+	byID := make(map[uuid.UUID]*domain.User, len(users))
+	for _, user := range users {
+		byID[user.GetID()] = user
+	}
+	return &MockUserRepository{
+		FindByIDFn: func(ctx context.Context, id uuid.UUID) (*domain.User, error) {
+			if user, ok := byID[id]; ok {
+				return user, nil
+			}
+			return nil, domain.ErrUserNotFound
+		},
+		FindByManagerIDFn: func(ctx context.Context, managerID uuid.UUID) ([]*domain.User, error) {
+			var reports []*domain.User
+			for _, user := range users {
+				if user.ReportsTo(managerID) {
+					reports = append(reports, user)
+				}
+			}
+			return reports, nil
+		},
+	}
+}
+
+// reportTo makes user report to manager.
+func reportTo(t *testing.T, user, manager *domain.User) {
+	t.Helper()
+	if err := user.AssignManager(manager); err != nil {
+		t.Fatalf("AssignManager() error = %v", err)
+	}
+	user.ClearDomainEvents()
+}
+
+// ============================================================================
+// AssignManagerUseCase Tests
+// ============================================================================
+
+func TestAssignManagerUseCase_Execute(t *testing.T) {
+	ctx := context.Background()
+	tenantID := uuid.New()
+	user := createTestUser(t, tenantID)
+	manager := createTestUser(t, tenantID)
+	admin := uuid.New()
+	auditLogger := &MockAuditLogger{}
+	user.ClearDomainEvents()
+
+	var events []*domain.OutboxEntry
+	outboxRepo := &MockOutboxRepository{
+		CreateFn: func(ctx context.Context, entry *domain.OutboxEntry) error {
+			events = append(events, entry)
+			return nil
+		},
+	}
+
+	useCase := NewAssignManagerUseCase(newOrgChartUserRepository(user, manager), outboxRepo, &MockTransactionManager{}, auditLogger)
+
+	managerID := manager.GetID()
+	result, err := useCase.Execute(ctx, user.GetID(), tenantID, &dto.AssignManagerRequest{ManagerID: &managerID}, &admin)
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+
+	if result.ManagerID == nil || *result.ManagerID != managerID {
+		t.Errorf("ManagerID = %v, want %s", result.ManagerID, managerID)
+	}
+	if len(events) != 1 || events[0].EventType != domain.EventTypeUserManagerChanged {
+		t.Fatalf("expected a %s event, got %d events", domain.EventTypeUserManagerChanged, len(events))
+	}
+	if len(events[0].Payload) == 0 {
+		t.Error("expected the event payload to carry the manager")
+	}
+	if len(auditLogger.Calls) != 1 || auditLogger.Calls[0].Action != "manager_assigned" {
+		t.Errorf("expected the assignment to be audited, got %v", auditLogger.Calls)
+	}
+
+	// Removing the manager
+	result, err = useCase.Execute(ctx, user.GetID(), tenantID, &dto.AssignManagerRequest{}, &admin)
+	if err != nil {
+		t.Fatalf("Execute() removing the manager error = %v", err)
+	}
+	if result.ManagerID != nil {
+		t.Errorf("ManagerID = %v, want nil", result.ManagerID)
+	}
+}
+
+func TestAssignManagerUseCase_Execute_Cycle(t *testing.T) {
+	ctx := context.Background()
+	tenantID := uuid.New()
+	director := createTestUser(t, tenantID)
+	manager := createTestUser(t, tenantID)
+	rep := createTestUser(t, tenantID)
+	reportTo(t, manager, director)
+	reportTo(t, rep, manager)
+
+	var updated bool
+	userRepo := newOrgChartUserRepository(director, manager, rep)
+	userRepo.UpdateFn = func(ctx context.Context, user *domain.User) error {
+		updated = true
+		return nil
+	}
+
+	useCase := NewAssignManagerUseCase(userRepo, &MockOutboxRepository{}, &MockTransactionManager{}, &MockAuditLogger{})
+
+	// The director cannot report to someone below them
+	repID := rep.GetID()
+	_, err := useCase.Execute(ctx, director.GetID(), tenantID, &dto.AssignManagerRequest{ManagerID: &repID}, nil)
+
+	var appErr *application.AppError
+	if !errors.As(err, &appErr) || appErr.Code != application.ErrCodeConflict {
+		t.Fatalf("Execute() error = %v, want a conflict", err)
+	}
+	if updated {
+		t.Error("expected the cycle not to be saved")
+	}
+}
+
+func TestAssignManagerUseCase_Execute_InvalidManager(t *testing.T) {
+	ctx := context.Background()
+	tenantID := uuid.New()
+	user := createTestUser(t, tenantID)
+	outsider := createTestUser(t, uuid.New())
+
+	useCase := NewAssignManagerUseCase(newOrgChartUserRepository(user, outsider), &MockOutboxRepository{}, &MockTransactionManager{}, &MockAuditLogger{})
+
+	tests := []struct {
+		name      string
+		managerID uuid.UUID
+	}{
+		{"self", user.GetID()},
+		{"other tenant", outsider.GetID()},
+		{"unknown", uuid.New()},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := useCase.Execute(ctx, user.GetID(), tenantID, &dto.AssignManagerRequest{ManagerID: &tt.managerID}, nil)
+
+			var appErr *application.AppError
+			if !errors.As(err, &appErr) || appErr.Code != application.ErrCodeValidation {
+				t.Errorf("Execute() error = %v, want a validation error", err)
+			}
+		})
+	}
+}
+
+// ============================================================================
+// GetReportingLineUseCase Tests
+// ============================================================================
+
+func TestGetReportingLineUseCase_Execute(t *testing.T) {
+	ctx := context.Background()
+	tenantID := uuid.New()
+	director := createTestUser(t, tenantID)
+	manager := createTestUser(t, tenantID)
+	rep := createTestUser(t, tenantID)
+	reportTo(t, manager, director)
+	reportTo(t, rep, manager)
+
+	useCase := NewGetReportingLineUseCase(newOrgChartUserRepository(director, manager, rep))
+
+	result, err := useCase.Execute(ctx, manager.GetID(), tenantID)
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+
+	if result.User.ID != manager.GetID() {
+		t.Errorf("User.ID = %s, want %s", result.User.ID, manager.GetID())
+	}
+	if len(result.Managers) != 1 || result.Managers[0].ID != director.GetID() {
+		t.Errorf("expected the director as the only manager, got %v", result.Managers)
+	}
+	if len(result.DirectReports) != 1 || result.DirectReports[0].ID != rep.GetID() {
+		t.Errorf("expected the rep as the only direct report, got %v", result.DirectReports)
+	}
+
+	// The line of the rep runs up to the director, nearest first
+	result, err = useCase.Execute(ctx, rep.GetID(), tenantID)
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if len(result.Managers) != 2 || result.Managers[0].ID != manager.GetID() || result.Managers[1].ID != director.GetID() {
+		t.Errorf("unexpected reporting line %v", result.Managers)
+	}
+	if len(result.DirectReports) != 0 {
+		t.Errorf("expected no direct reports, got %v", result.DirectReports)
+	}
+
+	// Users of other tenants are refused
+	if _, err := useCase.Execute(ctx, rep.GetID(), uuid.New()); err == nil {
+		t.Error("expected an error for another tenant")
+	}
+}
@@ -0,0 +1,297 @@
+// Package usecase contains the application use cases for the IAM service.
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/kilang-desa-murni/crm/internal/iam/application"
+	"github.com/kilang-desa-murni/crm/internal/iam/application/dto"
+	"github.com/kilang-desa-murni/crm/internal/iam/application/mapper"
+	"github.com/kilang-desa-murni/crm/internal/iam/application/ports"
+	"github.com/kilang-desa-murni/crm/internal/iam/domain"
+)
+
+// OffboardUserUseCase handles offboarding a departing user.
+type OffboardUserUseCase struct {
+	userRepo            domain.UserRepository
+	refreshTokenRepo    domain.RefreshTokenRepository
+	outboxRepo          domain.OutboxRepository
+	requestReassignment *RequestUserReassignmentUseCase
+	sessionBlacklist    ports.SessionBlacklist
+	tokenService        ports.TokenService
+	txManager           ports.TransactionManager
+	auditLogger         ports.AuditLogger
+	retention           time.Duration
+}
+
+// NewOffboardUserUseCase creates a new OffboardUserUseCase. Offboarded users
+// are anonymized once retention has passed; zero uses
+// domain.DefaultUserRetentionPeriod.
+func NewOffboardUserUseCase(
+	userRepo domain.UserRepository,
+	refreshTokenRepo domain.RefreshTokenRepository,
+	outboxRepo domain.OutboxRepository,
+	requestReassignment *RequestUserReassignmentUseCase,
+	sessionBlacklist ports.SessionBlacklist,
+	tokenService ports.TokenService,
+	txManager ports.TransactionManager,
+	auditLogger ports.AuditLogger,
+	retention time.Duration,
+) *OffboardUserUseCase {
+	if retention <= 0 {
+		retention = domain.DefaultUserRetentionPeriod
+	}
+
+	return &OffboardUserUseCase{
+		userRepo:            userRepo,
+		refreshTokenRepo:    refreshTokenRepo,
+		outboxRepo:          outboxRepo,
+		requestReassignment: requestReassignment,
+		sessionBlacklist:    sessionBlacklist,
+		tokenService:        tokenService,
+		txManager:           txManager,
+		auditLogger:         auditLogger,
+		retention:           retention,
+	}
+}
+
+// Execute deactivates the user and signs them out everywhere: their access
+// tokens stop working at once and their refresh tokens are revoked. If the
+// request asks for it, the transfer of their open records is queued in the
+// same step. The user is kept for the records and audit logs referring to
+// them until the retention period has passed.
+func (uc *OffboardUserUseCase) Execute(ctx context.Context, tenantID, userID, offboardedBy uuid.UUID, req *dto.OffboardUserRequest) (*dto.OffboardUserResponse, error) {
+	user, err := uc.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return nil, application.ErrNotFound("user", userID)
+	}
+
+	// Verify user belongs to tenant
+	if user.TenantID() != tenantID {
+		return nil, application.ErrForbidden("user does not belong to this tenant")
+	}
+
+	if userID == offboardedBy {
+		return nil, application.ErrValidation("users cannot offboard themselves", nil)
+	}
+
+	// Validate the transfer of records before anything changes
+	var reassignment *domain.UserReassignment
+	if req.Reassign != nil {
+		reassignment, err = uc.requestReassignment.prepare(ctx, user, offboardedBy, req.Reassign)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if err := user.Offboard(req.Reason, offboardedBy); err != nil {
+		if errors.Is(err, domain.ErrUserDeleted) {
+			return nil, application.ErrNotFound("user", userID)
+		}
+		return nil, application.ErrConflict(err.Error())
+	}
+
+	revoked, err := uc.refreshTokenRepo.CountActiveByUserID(ctx, userID)
+	if err != nil {
+		return nil, application.ErrInternal("failed to count refresh tokens", err)
+	}
+
+	// Access tokens are self-contained, so every token issued until now is
+	// blacklisted for as long as the longest of them is valid
+	if err := uc.sessionBlacklist.BlacklistUserSessions(ctx, userID.String(), time.Now().UTC(), uc.tokenService.GetAccessTokenExpiry()); err != nil {
+		return nil, application.ErrInternal("failed to revoke sessions", err)
+	}
+
+	// Execute in transaction
+	err = uc.txManager.WithTransaction(ctx, func(txCtx context.Context) error {
+		// Revoke all refresh tokens
+		if err := uc.refreshTokenRepo.RevokeByUserID(txCtx, userID); err != nil {
+			return err
+		}
+
+		if err := uc.userRepo.Update(txCtx, user); err != nil {
+			return err
+		}
+
+		if reassignment != nil {
+			if err := uc.requestReassignment.reassignmentRepo.Create(txCtx, reassignment); err != nil {
+				return err
+			}
+		}
+
+		// Save domain events to outbox
+		for _, event := range user.GetDomainEvents() {
+			payload, err := json.Marshal(event)
+			if err != nil {
+				return err
+			}
+			outboxEntry := &domain.OutboxEntry{
+				EventType:     event.EventType(),
+				AggregateID:   event.AggregateID(),
+				AggregateType: event.AggregateType(),
+				Payload:       payload,
+			}
+			if err := uc.outboxRepo.Create(txCtx, outboxEntry); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return nil, application.ErrInternal("failed to offboard user", err)
+	}
+
+	user.ClearDomainEvents()
+
+	// Log audit
+	newValues := map[string]interface{}{
+		"status":                 user.Status().String(),
+		"reason":                 req.Reason,
+		"revoked_refresh_tokens": revoked,
+	}
+	if reassignment != nil {
+		newValues["reassignment_id"] = reassignment.GetID().String()
+	}
+	_ = uc.auditLogger.Log(ctx, ports.AuditEntry{
+		TenantID:   tenantID,
+		UserID:     ptrToUUID(offboardedBy),
+		Action:     ports.AuditActionUserOffboarded,
+		EntityType: "user",
+		EntityID:   ptrToUUID(userID),
+		NewValues:  newValues,
+	})
+
+	response := &dto.OffboardUserResponse{
+		User:                 mapper.UserToDTO(user),
+		RevokedRefreshTokens: revoked,
+		AnonymizeAfter:       user.DeactivatedAt().Add(uc.retention),
+	}
+	if reassignment != nil {
+		uc.requestReassignment.audit(ctx, reassignment, offboardedBy)
+		response.Reassignment = mapper.UserReassignmentToDTO(reassignment)
+	}
+
+	return response, nil
+}
+
+// anonymizeBatchSize is how many users are anonymized per query.
+const anonymizeBatchSize = 100
+
+// AnonymizeOffboardedUsersUseCase handles anonymizing the users whose
+// retention period after offboarding has passed.
+type AnonymizeOffboardedUsersUseCase struct {
+	userRepo    domain.UserRepository
+	outboxRepo  domain.OutboxRepository
+	txManager   ports.TransactionManager
+	auditLogger ports.AuditLogger
+	retention   time.Duration
+}
+
+// NewAnonymizeOffboardedUsersUseCase creates a new
+// AnonymizeOffboardedUsersUseCase. Zero retention uses
+// domain.DefaultUserRetentionPeriod.
+func NewAnonymizeOffboardedUsersUseCase(
+	userRepo domain.UserRepository,
+	outboxRepo domain.OutboxRepository,
+	txManager ports.TransactionManager,
+	auditLogger ports.AuditLogger,
+	retention time.Duration,
+) *AnonymizeOffboardedUsersUseCase {
+	if retention <= 0 {
+		retention = domain.DefaultUserRetentionPeriod
+	}
+
+	return &AnonymizeOffboardedUsersUseCase{
+		userRepo:    userRepo,
+		outboxRepo:  outboxRepo,
+		txManager:   txManager,
+		auditLogger: auditLogger,
+		retention:   retention,
+	}
+}
+
+// Execute anonymizes every offboarded user of all tenants whose retention
+// period has passed, and returns how many were anonymized.
+func (uc *AnonymizeOffboardedUsersUseCase) Execute(ctx context.Context) (int, error) {
+	anonymized := 0
+	for {
+		now := time.Now().UTC()
+		users, err := uc.userRepo.FindDueForAnonymization(ctx, now.Add(-uc.retention), anonymizeBatchSize)
+		if err != nil {
+			return anonymized, application.ErrInternal("failed to find users due for anonymization", err)
+		}
+
+		batch := 0
+		for _, user := range users {
+			if !user.AnonymizationDue(uc.retention, now) {
+				continue
+			}
+			if err := uc.anonymize(ctx, user); err != nil {
+				return anonymized, err
+			}
+			batch++
+		}
+		anonymized += batch
+
+		if len(users) < anonymizeBatchSize || batch == 0 {
+			return anonymized, nil
+		}
+	}
+}
+
+// anonymize anonymizes one user.
+func (uc *AnonymizeOffboardedUsersUseCase) anonymize(ctx context.Context, user *domain.User) error {
+	if err := user.Anonymize(); err != nil {
+		return application.ErrConflict(err.Error())
+	}
+
+	// Execute in transaction
+	err := uc.txManager.WithTransaction(ctx, func(txCtx context.Context) error {
+		if err := uc.userRepo.Update(txCtx, user); err != nil {
+			return err
+		}
+
+		// Save domain events to outbox
+		for _, event := range user.GetDomainEvents() {
+			payload, err := json.Marshal(event)
+			if err != nil {
+				return err
+			}
+			outboxEntry := &domain.OutboxEntry{
+				EventType:     event.EventType(),
+				AggregateID:   event.AggregateID(),
+				AggregateType: event.AggregateType(),
+				Payload:       payload,
+			}
+			if err := uc.outboxRepo.Create(txCtx, outboxEntry); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return application.ErrInternal("failed to anonymize user", err)
+	}
+
+	user.ClearDomainEvents()
+
+	// Log audit. The values replaced are not logged, or the audit log would
+	// keep the personal data.
+	_ = uc.auditLogger.Log(ctx, ports.AuditEntry{
+		TenantID:   user.TenantID(),
+		Action:     ports.AuditActionUserAnonymized,
+		EntityType: "user",
+		EntityID:   ptrToUUID(user.GetID()),
+	})
+
+	return nil
+}
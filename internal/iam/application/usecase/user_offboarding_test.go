@@ -0,0 +1,175 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/kilang-desa-murni/crm/internal/iam/application"
+	"github.com/kilang-desa-murni/crm/internal/iam/application/dto"
+	"github.com/kilang-desa-murni/crm/internal/iam/application/ports"
+	"github.com/kilang-desa-murni/crm/internal/iam/domain"
+)
+
+// ============================================================================
+// Mock Implementations for User Offboarding Tests
+// ============================================================================
+
+// MockSessionBlacklist is a mock implementation of ports.SessionBlacklist.
+type MockSessionBlacklist struct {
+	revokedAfter map[string]time.Time
+}
+
+func (m *MockSessionBlacklist) BlacklistUserSessions(ctx context.Context, userID string, after time.Time, ttl time.Duration) error {
+	if m.revokedAfter == nil {
+		m.revokedAfter = make(map[string]time.Time)
+	}
+	m.revokedAfter[userID] = after
+	return nil
+}
+
+func (m *MockSessionBlacklist) IsSessionValid(ctx context.Context, userID string, issuedAt time.Time) (bool, error) {
+	after, ok := m.revokedAfter[userID]
+	return !ok || issuedAt.After(after), nil
+}
+
+// ============================================================================
+// User Offboarding Tests
+// ============================================================================
+
+func TestOffboardUserUseCase_Execute(t *testing.T) {
+	ctx := context.Background()
+	tenantID := uuid.New()
+	leaver := createTestUser(t, tenantID)
+	recipient := createTestUser(t, tenantID)
+	admin := uuid.New()
+	userRepo := newReassignmentUserRepository(leaver, recipient)
+	reassignmentRepo := NewMockUserReassignmentRepository()
+	auditLogger := &MockAuditLogger{}
+	sessionBlacklist := &MockSessionBlacklist{}
+
+	var revokedTokens bool
+	refreshTokenRepo := &MockRefreshTokenRepository{
+		CountActiveByUserIDFn: func(ctx context.Context, userID uuid.UUID) (int64, error) {
+			return 2, nil
+		},
+		RevokeByUserIDFn: func(ctx context.Context, userID uuid.UUID) error {
+			revokedTokens = userID == leaver.GetID()
+			return nil
+		},
+	}
+
+	var events []string
+	outboxRepo := &MockOutboxRepository{
+		CreateFn: func(ctx context.Context, entry *domain.OutboxEntry) error {
+			events = append(events, entry.EventType)
+			return nil
+		},
+	}
+
+	useCase := NewOffboardUserUseCase(
+		userRepo,
+		refreshTokenRepo,
+		outboxRepo,
+		NewRequestUserReassignmentUseCase(userRepo, reassignmentRepo, auditLogger),
+		sessionBlacklist,
+		&MockTokenService{},
+		&MockTransactionManager{},
+		auditLogger,
+		30*24*time.Hour,
+	)
+
+	issuedAt := time.Now().Add(-time.Minute)
+	leaver.ClearDomainEvents()
+	result, err := useCase.Execute(ctx, tenantID, leaver.GetID(), admin, &dto.OffboardUserRequest{
+		Reason: "Resigned",
+		Reassign: &dto.ReassignUserRecordsRequest{
+			Strategy:   "round_robin",
+			Recipients: []dto.ReassignRecipientRequest{{UserID: recipient.GetID()}},
+		},
+	})
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+
+	if leaver.Status() != domain.UserStatusInactive || leaver.DeactivationReason() != "Resigned" {
+		t.Errorf("expected an inactive user with the reason recorded, got %v (%q)", leaver.Status(), leaver.DeactivationReason())
+	}
+	if valid, _ := sessionBlacklist.IsSessionValid(ctx, leaver.GetID().String(), issuedAt); valid {
+		t.Error("expected the user's access tokens to be revoked")
+	}
+	if !revokedTokens || result.RevokedRefreshTokens != 2 {
+		t.Errorf("expected the user's 2 refresh tokens to be revoked, got %d", result.RevokedRefreshTokens)
+	}
+	if result.Reassignment == nil {
+		t.Fatal("expected the transfer of the user's records to be queued")
+	}
+	if active, _ := reassignmentRepo.HasActive(ctx, tenantID, leaver.GetID()); !active {
+		t.Error("expected the reassignment to be stored")
+	}
+	if want := leaver.DeactivatedAt().Add(30 * 24 * time.Hour); !result.AnonymizeAfter.Equal(want) {
+		t.Errorf("AnonymizeAfter = %v, want %v", result.AnonymizeAfter, want)
+	}
+	if len(events) != 1 || events[0] != domain.EventTypeUserDeactivated {
+		t.Errorf("expected a user deactivated event, got %v", events)
+	}
+	if len(auditLogger.Calls) != 2 || auditLogger.Calls[0].Action != ports.AuditActionUserOffboarded {
+		t.Errorf("expected the offboarding and the reassignment to be audited, got %+v", auditLogger.Calls)
+	}
+
+	// Offboarding twice is a conflict, and nobody offboards themselves
+	var appErr *application.AppError
+	if _, err := useCase.Execute(ctx, tenantID, leaver.GetID(), admin, &dto.OffboardUserRequest{}); !errors.As(err, &appErr) || appErr.Code != application.ErrCodeConflict {
+		t.Errorf("Execute() for an offboarded user error = %v, want conflict", err)
+	}
+	if _, err := useCase.Execute(ctx, tenantID, recipient.GetID(), recipient.GetID(), &dto.OffboardUserRequest{}); !errors.As(err, &appErr) || appErr.Code != application.ErrCodeValidation {
+		t.Errorf("Execute() for oneself error = %v, want validation error", err)
+	}
+}
+
+func TestAnonymizeOffboardedUsersUseCase_Execute(t *testing.T) {
+	ctx := context.Background()
+	tenantID := uuid.New()
+	departed := createTestUser(t, tenantID)
+	if err := departed.Offboard("", uuid.New()); err != nil {
+		t.Fatalf("Offboard() error = %v", err)
+	}
+	departed.ClearDomainEvents()
+
+	var before time.Time
+	userRepo := &MockUserRepository{
+		FindDueForAnonymizationFn: func(ctx context.Context, deactivatedBefore time.Time, limit int) ([]*domain.User, error) {
+			before = deactivatedBefore
+			if departed.IsAnonymized() {
+				return nil, nil
+			}
+			return []*domain.User{departed}, nil
+		},
+	}
+	auditLogger := &MockAuditLogger{}
+
+	// Within the retention period the user is left as they are
+	useCase := NewAnonymizeOffboardedUsersUseCase(userRepo, &MockOutboxRepository{}, &MockTransactionManager{}, auditLogger, time.Hour)
+	anonymized, err := useCase.Execute(ctx)
+	if err != nil || anonymized != 0 || departed.IsAnonymized() {
+		t.Fatalf("Execute() within retention = %d, %v; want nothing anonymized", anonymized, err)
+	}
+	if time.Since(before) < time.Hour-time.Minute {
+		t.Errorf("expected users deactivated over the retention period ago to be looked for, got %v", before)
+	}
+
+	useCase = NewAnonymizeOffboardedUsersUseCase(userRepo, &MockOutboxRepository{}, &MockTransactionManager{}, auditLogger, time.Nanosecond)
+	anonymized, err = useCase.Execute(ctx)
+	if err != nil || anonymized != 1 {
+		t.Fatalf("Execute() = %d, %v; want 1 user anonymized", anonymized, err)
+	}
+	if departed.Email().String() == "test@example.com" || departed.FullName() == "John Doe" {
+		t.Errorf("expected the personal data to be replaced, got %s (%s)", departed.FullName(), departed.Email())
+	}
+	if len(auditLogger.Calls) != 1 || auditLogger.Calls[0].OldValues != nil {
+		t.Errorf("expected the anonymization to be audited without the old values, got %+v", auditLogger.Calls)
+	}
+}
@@ -0,0 +1,267 @@
+// Package usecase contains the application use cases for the IAM service.
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/google/uuid"
+
+	"github.com/kilang-desa-murni/crm/internal/iam/application"
+	"github.com/kilang-desa-murni/crm/internal/iam/application/dto"
+	"github.com/kilang-desa-murni/crm/internal/iam/application/mapper"
+	"github.com/kilang-desa-murni/crm/internal/iam/application/ports"
+	"github.com/kilang-desa-murni/crm/internal/iam/domain"
+)
+
+// RequestUserReassignmentUseCase handles requesting the reassignment of a
+// user's records.
+type RequestUserReassignmentUseCase struct {
+	userRepo         domain.UserRepository
+	reassignmentRepo domain.UserReassignmentRepository
+	auditLogger      ports.AuditLogger
+}
+
+// NewRequestUserReassignmentUseCase creates a new RequestUserReassignmentUseCase.
+func NewRequestUserReassignmentUseCase(
+	userRepo domain.UserRepository,
+	reassignmentRepo domain.UserReassignmentRepository,
+	auditLogger ports.AuditLogger,
+) *RequestUserReassignmentUseCase {
+	return &RequestUserReassignmentUseCase{
+		userRepo:         userRepo,
+		reassignmentRepo: reassignmentRepo,
+		auditLogger:      auditLogger,
+	}
+}
+
+// Execute queues the transfer of the user's open leads, opportunities and
+// customers to the recipients, who must be active users of the tenant. A
+// user has at most one reassignment in progress.
+func (uc *RequestUserReassignmentUseCase) Execute(ctx context.Context, tenantID, userID, requestedBy uuid.UUID, req *dto.ReassignUserRecordsRequest) (*dto.UserReassignmentDTO, error) {
+	user, err := uc.userRepo.FindByID(ctx, userID)
+	if err != nil || user.TenantID() != tenantID {
+		return nil, application.ErrNotFound("user", userID)
+	}
+
+	reassignment, err := uc.prepare(ctx, user, requestedBy, req)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := uc.reassignmentRepo.Create(ctx, reassignment); err != nil {
+		return nil, application.ErrInternal("failed to create user reassignment", err)
+	}
+
+	uc.audit(ctx, reassignment, requestedBy)
+
+	return mapper.UserReassignmentToDTO(reassignment), nil
+}
+
+// prepare validates the request and builds the reassignment of the user's
+// records, without queueing it.
+func (uc *RequestUserReassignmentUseCase) prepare(ctx context.Context, user *domain.User, requestedBy uuid.UUID, req *dto.ReassignUserRecordsRequest) (*domain.UserReassignment, error) {
+	tenantID := user.TenantID()
+
+	recipients := make([]domain.ReassignmentRecipient, 0, len(req.Recipients))
+	for _, r := range req.Recipients {
+		recipient, err := uc.userRepo.FindByID(ctx, r.UserID)
+		if err != nil || recipient.TenantID() != tenantID {
+			return nil, application.ErrNotFound("user", r.UserID)
+		}
+		if !recipient.IsActive() {
+			return nil, application.ErrValidation("records can only be reassigned to active users", map[string]interface{}{
+				"user_id": r.UserID,
+			})
+		}
+
+		datasets := make([]domain.ReassignmentDataset, len(r.Datasets))
+		for i, dataset := range r.Datasets {
+			datasets[i] = domain.ReassignmentDataset(dataset)
+		}
+		recipients = append(recipients, domain.ReassignmentRecipient{
+			UserID:   r.UserID,
+			Name:     recipient.FullName(),
+			Datasets: datasets,
+		})
+	}
+
+	active, err := uc.reassignmentRepo.HasActive(ctx, tenantID, user.GetID())
+	if err != nil {
+		return nil, application.ErrInternal("failed to check user reassignments", err)
+	}
+	if active {
+		return nil, application.ErrConflict("a reassignment of this user's records is already in progress")
+	}
+
+	reassignment, err := domain.NewUserReassignment(
+		tenantID,
+		user.GetID(),
+		user.FullName(),
+		requestedBy,
+		domain.ReassignmentStrategy(req.Strategy),
+		recipients,
+	)
+	if err != nil {
+		return nil, application.ErrValidation("invalid user reassignment", map[string]interface{}{
+			"error": err.Error(),
+		})
+	}
+
+	return reassignment, nil
+}
+
+// audit logs the request of a reassignment.
+func (uc *RequestUserReassignmentUseCase) audit(ctx context.Context, reassignment *domain.UserReassignment, requestedBy uuid.UUID) {
+	recipientIDs := make([]string, len(reassignment.Recipients()))
+	for i, recipient := range reassignment.Recipients() {
+		recipientIDs[i] = recipient.UserID.String()
+	}
+	_ = uc.auditLogger.Log(ctx, ports.AuditEntry{
+		TenantID:   reassignment.TenantID(),
+		UserID:     ptrToUUID(requestedBy),
+		Action:     ports.AuditActionRecordsReassigned,
+		EntityType: "user",
+		EntityID:   ptrToUUID(reassignment.UserID()),
+		NewValues: map[string]interface{}{
+			"reassignment_id": reassignment.GetID().String(),
+			"strategy":        reassignment.Strategy().String(),
+			"recipients":      recipientIDs,
+		},
+	})
+}
+
+// GetUserReassignmentUseCase handles retrieving user reassignments.
+type GetUserReassignmentUseCase struct {
+	reassignmentRepo domain.UserReassignmentRepository
+}
+
+// NewGetUserReassignmentUseCase creates a new GetUserReassignmentUseCase.
+func NewGetUserReassignmentUseCase(reassignmentRepo domain.UserReassignmentRepository) *GetUserReassignmentUseCase {
+	return &GetUserReassignmentUseCase{
+		reassignmentRepo: reassignmentRepo,
+	}
+}
+
+// Execute retrieves a reassignment of the user's records with its progress.
+func (uc *GetUserReassignmentUseCase) Execute(ctx context.Context, tenantID, userID, reassignmentID uuid.UUID) (*dto.UserReassignmentDTO, error) {
+	reassignment, err := uc.reassignmentRepo.FindByID(ctx, tenantID, reassignmentID)
+	if err != nil || reassignment.UserID() != userID {
+		return nil, application.ErrNotFound("user reassignment", reassignmentID)
+	}
+
+	return mapper.UserReassignmentToDTO(reassignment), nil
+}
+
+// RunUserReassignmentUseCase handles carrying out queued user reassignments.
+type RunUserReassignmentUseCase struct {
+	reassignmentRepo domain.UserReassignmentRepository
+	outboxRepo       domain.OutboxRepository
+	txManager        ports.TransactionManager
+	reassigner       ports.OwnerReassigner
+}
+
+// NewRunUserReassignmentUseCase creates a new RunUserReassignmentUseCase.
+func NewRunUserReassignmentUseCase(
+	reassignmentRepo domain.UserReassignmentRepository,
+	outboxRepo domain.OutboxRepository,
+	txManager ports.TransactionManager,
+	reassigner ports.OwnerReassigner,
+) *RunUserReassignmentUseCase {
+	return &RunUserReassignmentUseCase{
+		reassignmentRepo: reassignmentRepo,
+		outboxRepo:       outboxRepo,
+		txManager:        txManager,
+		reassigner:       reassigner,
+	}
+}
+
+// Execute carries out the oldest queued reassignment, one data set at a time
+// so its progress can be followed, and notifies the recipients once it
+// completes. It returns false if no reassignment was queued.
+func (uc *RunUserReassignmentUseCase) Execute(ctx context.Context) (bool, error) {
+	reassignment, err := uc.reassignmentRepo.ClaimPending(ctx)
+	if err != nil {
+		return false, application.ErrInternal("failed to claim user reassignment", err)
+	}
+	if reassignment == nil {
+		return false, nil
+	}
+
+	for _, progress := range reassignment.Datasets() {
+		if progress.IsDone() {
+			continue
+		}
+		if err := uc.reassignDataset(ctx, reassignment, progress.Dataset); err != nil {
+			return true, uc.fail(ctx, reassignment, err)
+		}
+		if err := uc.reassignmentRepo.Update(ctx, reassignment); err != nil {
+			return true, application.ErrInternal("failed to record user reassignment progress", err)
+		}
+	}
+
+	if err := reassignment.Complete(); err != nil {
+		return true, uc.fail(ctx, reassignment, err)
+	}
+
+	err = uc.txManager.WithTransaction(ctx, func(txCtx context.Context) error {
+		if err := uc.reassignmentRepo.Update(txCtx, reassignment); err != nil {
+			return err
+		}
+
+		// Save domain events to outbox
+		for _, event := range reassignment.GetDomainEvents() {
+			payload, err := json.Marshal(event)
+			if err != nil {
+				return err
+			}
+			outboxEntry := &domain.OutboxEntry{
+				EventType:     event.EventType(),
+				AggregateID:   event.AggregateID(),
+				AggregateType: event.AggregateType(),
+				Payload:       payload,
+			}
+			if err := uc.outboxRepo.Create(txCtx, outboxEntry); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return true, application.ErrInternal("failed to complete user reassignment", err)
+	}
+	reassignment.ClearDomainEvents()
+
+	return true, nil
+}
+
+// reassignDataset transfers the user's records of a data set in the service
+// that owns them.
+func (uc *RunUserReassignmentUseCase) reassignDataset(ctx context.Context, reassignment *domain.UserReassignment, dataset domain.ReassignmentDataset) error {
+	recipients := reassignment.RecipientsFor(dataset)
+	if len(recipients) == 0 {
+		return reassignment.CompleteDataset(dataset, nil, 0)
+	}
+
+	result, err := uc.reassigner.Reassign(ctx, reassignment.TenantID(), reassignment.UserID(), dataset, recipients)
+	if err != nil {
+		return fmt.Errorf("failed to reassign %s in the %s service: %w", dataset, dataset.Service(), err)
+	}
+
+	return reassignment.CompleteDataset(dataset, result.Assigned, result.Failed)
+}
+
+// fail marks the reassignment as failed. This happens even if ctx was
+// cancelled, so an interrupted reassignment does not stay running and block
+// the user's next one. The records already transferred keep their new owners
+// and a new reassignment picks up the rest.
+func (uc *RunUserReassignmentUseCase) fail(ctx context.Context, reassignment *domain.UserReassignment, cause error) error {
+	ctx = context.WithoutCancel(ctx)
+	reassignment.Fail(cause.Error())
+	if err := uc.reassignmentRepo.Update(ctx, reassignment); err != nil {
+		return application.ErrInternal("failed to record user reassignment failure", err)
+	}
+	return application.ErrInternal("user reassignment failed", cause)
+}
@@ -0,0 +1,240 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"github.com/kilang-desa-murni/crm/internal/iam/application"
+	"github.com/kilang-desa-murni/crm/internal/iam/application/dto"
+	"github.com/kilang-desa-murni/crm/internal/iam/application/ports"
+	"github.com/kilang-desa-murni/crm/internal/iam/domain"
+)
+
+// ============================================================================
+// Mock Implementations for User Reassignment Tests
+// ============================================================================
+
+// MockUserReassignmentRepository is an in-memory mock of domain.UserReassignmentRepository.
+type MockUserReassignmentRepository struct {
+	mu            sync.Mutex
+	reassignments map[uuid.UUID]*domain.UserReassignment
+}
+
+func NewMockUserReassignmentRepository(reassignments ...*domain.UserReassignment) *MockUserReassignmentRepository {
+	m := &MockUserReassignmentRepository{reassignments: make(map[uuid.UUID]*domain.UserReassignment)}
+	for _, reassignment := range reassignments {
+		m.reassignments[reassignment.GetID()] = reassignment
+	}
+	return m
+}
+
+func (m *MockUserReassignmentRepository) Create(ctx context.Context, reassignment *domain.UserReassignment) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.reassignments[reassignment.GetID()] = reassignment
+	return nil
+}
+
+func (m *MockUserReassignmentRepository) Update(ctx context.Context, reassignment *domain.UserReassignment) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.reassignments[reassignment.GetID()] = reassignment
+	return nil
+}
+
+func (m *MockUserReassignmentRepository) FindByID(ctx context.Context, tenantID, id uuid.UUID) (*domain.UserReassignment, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if reassignment, ok := m.reassignments[id]; ok && reassignment.TenantID() == tenantID {
+		return reassignment, nil
+	}
+	return nil, domain.ErrUserReassignmentNotFound
+}
+
+func (m *MockUserReassignmentRepository) HasActive(ctx context.Context, tenantID, userID uuid.UUID) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, reassignment := range m.reassignments {
+		if reassignment.TenantID() == tenantID && reassignment.UserID() == userID && reassignment.Status().IsActive() {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (m *MockUserReassignmentRepository) ClaimPending(ctx context.Context) (*domain.UserReassignment, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, reassignment := range m.reassignments {
+		if reassignment.Status() == domain.UserReassignmentStatusPending {
+			return reassignment, reassignment.Start()
+		}
+	}
+	return nil, nil
+}
+
+// MockOwnerReassigner hands a fixed number of records per data set to the
+// recipients in turn.
+type MockOwnerReassigner struct {
+	Records    map[domain.ReassignmentDataset]int64
+	FailOnData domain.ReassignmentDataset
+}
+
+func (m *MockOwnerReassigner) Reassign(ctx context.Context, tenantID, fromUserID uuid.UUID, dataset domain.ReassignmentDataset, recipients []domain.ReassignmentRecipient) (ports.ReassignmentResult, error) {
+	if dataset == m.FailOnData {
+		return ports.ReassignmentResult{}, errors.New("service unavailable")
+	}
+	result := ports.ReassignmentResult{Assigned: make(map[uuid.UUID]int64)}
+	for i := int64(0); i < m.Records[dataset]; i++ {
+		result.Assigned[recipients[i%int64(len(recipients))].UserID]++
+		result.Transferred++
+	}
+	return result, nil
+}
+
+// newReassignmentUserRepository returns a user repository holding the users.
+func newReassignmentUserRepository(users ...*domain.User) *MockUserRepository {
+	byID := make(map[uuid.UUID]*domain.User, len(users))
+	for _, user := range users {
+		byID[user.GetID()] = user
+	}
+	return &MockUserRepository{
+		FindByIDFn: func(ctx context.Context, id uuid.UUID) (*domain.User, error) {
+			if user, ok := byID[id]; ok {
+				return user, nil
+			}
+			return nil, errors.New("not found")
+		},
+	}
+}
+
+// ============================================================================
+// User Reassignment Tests
+// ============================================================================
+
+func TestRequestUserReassignmentUseCase_Execute(t *testing.T) {
+	ctx := context.Background()
+	tenantID := uuid.New()
+	leaver := createTestUser(t, tenantID)
+	recipient := createTestUser(t, tenantID)
+	inactive := createTestUser(t, tenantID)
+	_ = inactive.Deactivate()
+	auditLogger := &MockAuditLogger{}
+
+	useCase := NewRequestUserReassignmentUseCase(
+		newReassignmentUserRepository(leaver, recipient, inactive),
+		NewMockUserReassignmentRepository(),
+		auditLogger,
+	)
+
+	req := &dto.ReassignUserRecordsRequest{
+		Strategy:   "round_robin",
+		Recipients: []dto.ReassignRecipientRequest{{UserID: recipient.GetID()}},
+	}
+	result, err := useCase.Execute(ctx, tenantID, leaver.GetID(), uuid.New(), req)
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if result.Status != domain.UserReassignmentStatusPending.String() || len(result.Datasets) != len(domain.ReassignmentDatasets()) {
+		t.Errorf("expected a pending reassignment of every data set, got %+v", result)
+	}
+	if result.Recipients[0].Name != recipient.FullName() {
+		t.Errorf("expected the recipient's name to be recorded, got %q", result.Recipients[0].Name)
+	}
+	if len(auditLogger.Calls) != 1 || auditLogger.Calls[0].Action != ports.AuditActionRecordsReassigned {
+		t.Errorf("expected the reassignment request to be audited, got %+v", auditLogger.Calls)
+	}
+
+	// A second reassignment waits for the first one to finish
+	_, err = useCase.Execute(ctx, tenantID, leaver.GetID(), uuid.New(), req)
+	var appErr *application.AppError
+	if !errors.As(err, &appErr) || appErr.Code != application.ErrCodeConflict {
+		t.Errorf("Execute() with a reassignment in progress error = %v, want conflict", err)
+	}
+
+	// Records only go to active users of the tenant
+	req.Recipients = []dto.ReassignRecipientRequest{{UserID: inactive.GetID()}}
+	if _, err := useCase.Execute(ctx, tenantID, recipient.GetID(), uuid.New(), req); !errors.As(err, &appErr) || appErr.Code != application.ErrCodeValidation {
+		t.Errorf("Execute() to an inactive user error = %v, want validation error", err)
+	}
+	if _, err := useCase.Execute(ctx, uuid.New(), leaver.GetID(), uuid.New(), req); !errors.As(err, &appErr) || appErr.Code != application.ErrCodeNotFound {
+		t.Errorf("Execute() for another tenant's user error = %v, want not found", err)
+	}
+}
+
+func TestRunUserReassignmentUseCase_Execute(t *testing.T) {
+	ctx := context.Background()
+	first, second := uuid.New(), uuid.New()
+	reassignment, _ := domain.NewUserReassignment(uuid.New(), uuid.New(), "Leaver", uuid.New(), domain.ReassignmentStrategyRoundRobin, []domain.ReassignmentRecipient{
+		{UserID: first, Name: "Aminah"},
+		{UserID: second, Name: "Badrul"},
+	})
+	reassignmentRepo := NewMockUserReassignmentRepository(reassignment)
+
+	var entries []*domain.OutboxEntry
+	outboxRepo := &MockOutboxRepository{
+		CreateFn: func(ctx context.Context, entry *domain.OutboxEntry) error {
+			entries = append(entries, entry)
+			return nil
+		},
+	}
+	reassigner := &MockOwnerReassigner{Records: map[domain.ReassignmentDataset]int64{
+		domain.ReassignmentDatasetLeads:         3,
+		domain.ReassignmentDatasetOpportunities: 1,
+		domain.ReassignmentDatasetCustomers:     2,
+	}}
+
+	useCase := NewRunUserReassignmentUseCase(reassignmentRepo, outboxRepo, &MockTransactionManager{}, reassigner)
+
+	processed, err := useCase.Execute(ctx)
+	if err != nil || !processed {
+		t.Fatalf("Execute() = %v, %v; want a processed reassignment", processed, err)
+	}
+	if reassignment.Status() != domain.UserReassignmentStatusCompleted || reassignment.Transferred() != 6 {
+		t.Fatalf("expected 6 records reassigned, got %v with %d", reassignment.Status(), reassignment.Transferred())
+	}
+
+	// The turn carries on across data sets, so the records are shared evenly
+	for _, recipient := range reassignment.Recipients() {
+		if recipient.Total() != 3 {
+			t.Errorf("recipient %s received %d records, want 3", recipient.Name, recipient.Total())
+		}
+	}
+
+	if len(entries) != 2 {
+		t.Fatalf("expected an outbox event per recipient, got %d", len(entries))
+	}
+	var payload map[string]interface{}
+	if err := json.Unmarshal(entries[0].Payload, &payload); err != nil || payload["recipient_id"] != first.String() {
+		t.Errorf("expected the event payload to name the recipient, got %s", entries[0].Payload)
+	}
+
+	processed, err = useCase.Execute(ctx)
+	if err != nil || processed {
+		t.Errorf("Execute() with nothing queued = %v, %v; want false", processed, err)
+	}
+}
+
+func TestRunUserReassignmentUseCase_Execute_Failure(t *testing.T) {
+	ctx := context.Background()
+	reassignment, _ := domain.NewUserReassignment(uuid.New(), uuid.New(), "Leaver", uuid.New(), domain.ReassignmentStrategyRoundRobin, []domain.ReassignmentRecipient{{UserID: uuid.New()}})
+	reassigner := &MockOwnerReassigner{FailOnData: domain.ReassignmentDatasetOpportunities}
+
+	useCase := NewRunUserReassignmentUseCase(NewMockUserReassignmentRepository(reassignment), &MockOutboxRepository{}, &MockTransactionManager{}, reassigner)
+
+	processed, err := useCase.Execute(ctx)
+	if err == nil || !processed {
+		t.Fatalf("Execute() = %v, %v; want a failed reassignment", processed, err)
+	}
+	if reassignment.Status() != domain.UserReassignmentStatusFailed || reassignment.Error() == "" {
+		t.Errorf("expected a failed reassignment with its cause, got %v (%q)", reassignment.Status(), reassignment.Error())
+	}
+	if !reassignment.Datasets()[0].IsDone() {
+		t.Error("expected the leads reassigned before the failure to be recorded")
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"testing"
+	"time"
 
 	"github.com/google/uuid"
 
@@ -27,6 +28,8 @@ type FullMockUserRepositoryForUserTests struct {
 	FindByRoleIDFn  func(ctx context.Context, roleID uuid.UUID) ([]*domain.User, error)
 	ExistsByEmailFn func(ctx context.Context, tenantID uuid.UUID, email domain.Email) (bool, error)
 	CountByTenantFn func(ctx context.Context, tenantID uuid.UUID) (int64, error)
+
+	FindByManagerIDFn func(ctx context.Context, managerID uuid.UUID) ([]*domain.User, error)
 }
 
 func (m *FullMockUserRepositoryForUserTests) Create(ctx context.Context, user *domain.User) error {
@@ -78,6 +81,13 @@ func (m *FullMockUserRepositoryForUserTests) FindByRoleID(ctx context.Context, r
 	return nil, nil
 }
 
+func (m *FullMockUserRepositoryForUserTests) FindByManagerID(ctx context.Context, managerID uuid.UUID) ([]*domain.User, error) {
+	if m.FindByManagerIDFn != nil {
+		return m.FindByManagerIDFn(ctx, managerID)
+	}
+	return nil, nil
+}
+
 func (m *FullMockUserRepositoryForUserTests) ExistsByEmail(ctx context.Context, tenantID uuid.UUID, email domain.Email) (bool, error) {
 	if m.ExistsByEmailFn != nil {
 		return m.ExistsByEmailFn(ctx, tenantID, email)
@@ -92,6 +102,10 @@ func (m *FullMockUserRepositoryForUserTests) CountByTenant(ctx context.Context,
 	return 0, nil
 }
 
+func (m *FullMockUserRepositoryForUserTests) FindDueForAnonymization(ctx context.Context, deactivatedBefore time.Time, limit int) ([]*domain.User, error) {
+	return nil, nil
+}
+
 // ============================================================================
 // GetUserUseCase Tests
 // ============================================================================
@@ -387,6 +401,68 @@ func TestListUsersUseCase_Execute_RepositoryError(t *testing.T) {
 	}
 }
 
+// ============================================================================
+// BatchGetUsersUseCase Tests
+// ============================================================================
+
+func TestBatchGetUsersUseCase_Execute_Success(t *testing.T) {
+	ctx := context.Background()
+	tenant := createTestTenant(t)
+	user := createTestUser(t, tenant.GetID())
+	missingID := uuid.New()
+
+	userRepo := &FullMockUserRepositoryForUserTests{
+		FindByTenantFn: func(ctx context.Context, tenantID uuid.UUID, opts domain.UserQueryOptions) ([]*domain.User, int64, error) {
+			if len(opts.IDs) != 2 {
+				t.Errorf("Expected 2 unique IDs, got %d", len(opts.IDs))
+			}
+			if opts.PageSize != len(opts.IDs) {
+				t.Errorf("Expected pageSize %d, got %d", len(opts.IDs), opts.PageSize)
+			}
+			return []*domain.User{user}, 1, nil
+		},
+	}
+
+	useCase := NewBatchGetUsersUseCase(userRepo)
+
+	result, err := useCase.Execute(ctx, &dto.BatchGetUsersRequest{
+		TenantID: tenant.GetID(),
+		IDs:      []uuid.UUID{missingID, user.GetID(), missingID},
+	})
+
+	if err != nil {
+		t.Fatalf("Execute() unexpected error = %v", err)
+	}
+	if len(result.Found) != 1 || result.Found[0].ID != user.GetID() {
+		t.Errorf("Execute() Found = %v, want the one existing user", result.Found)
+	}
+	if len(result.Missing) != 1 || result.Missing[0] != missingID {
+		t.Errorf("Execute() Missing = %v, want [%s]", result.Missing, missingID)
+	}
+}
+
+func TestBatchGetUsersUseCase_Execute_TooManyIDs(t *testing.T) {
+	ctx := context.Background()
+	tenant := createTestTenant(t)
+
+	ids := make([]uuid.UUID, dto.MaxBatchGetUsers+1)
+	for i := range ids {
+		ids[i] = uuid.New()
+	}
+
+	useCase := NewBatchGetUsersUseCase(&FullMockUserRepositoryForUserTests{})
+
+	_, err := useCase.Execute(ctx, &dto.BatchGetUsersRequest{TenantID: tenant.GetID(), IDs: ids})
+
+	var appErr *application.AppError
+	if !errors.As(err, &appErr) {
+		t.Fatalf("Execute() error should be AppError, got %T", err)
+	}
+	if appErr.Code != "VALIDATION_ERROR" {
+		t.Errorf("Execute() error code = %s, want VALIDATION_ERROR", appErr.Code)
+	}
+}
+
 // ============================================================================
 // UpdateUserUseCase Tests
 // ============================================================================
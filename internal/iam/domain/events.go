@@ -2,6 +2,8 @@
 package domain
 
 import (
+	"time"
+
 	"github.com/google/uuid"
 )
 
@@ -21,6 +23,16 @@ const (
 	EventTypeUserRoleAssigned    = "user.role_assigned"
 	EventTypeUserRoleRemoved     = "user.role_removed"
 
+	EventTypeUserRecordsReassigned = "user.records_reassigned"
+	EventTypeUserAnonymized        = "user.anonymized"
+	EventTypeUserManagerChanged    = "user.manager_changed"
+
+	EventTypeUserSuspiciousActivity = "user.suspicious_activity"
+
+	// Security events are published as "security." followed by the
+	// SecurityEventType, such as "security.login_failed"
+	EventTypeSecurityPrefix = "security."
+
 	// Role events
 	EventTypeRoleCreated           = "role.created"
 	EventTypeRoleUpdated           = "role.updated"
@@ -45,6 +57,8 @@ const (
 	AggregateTypeUser   = "user"
 	AggregateTypeRole   = "role"
 	AggregateTypeTenant = "tenant"
+
+	AggregateTypeSecurityEvent = "security_event"
 )
 
 // ============================================================================
@@ -74,7 +88,8 @@ func NewUserCreatedEvent(user *User) *UserCreatedEvent {
 // UserUpdatedEvent is raised when a user is updated.
 type UserUpdatedEvent struct {
 	BaseDomainEvent
-	TenantID uuid.UUID `json:"tenant_id"`
+	TenantID  uuid.UUID  `json:"tenant_id"`
+	ManagerID *uuid.UUID `json:"manager_id"`
 }
 
 // NewUserUpdatedEvent creates a new UserUpdatedEvent.
@@ -82,6 +97,7 @@ func NewUserUpdatedEvent(user *User) *UserUpdatedEvent {
 	return &UserUpdatedEvent{
 		BaseDomainEvent: NewBaseDomainEvent(EventTypeUserUpdated, user.GetID(), AggregateTypeUser),
 		TenantID:        user.TenantID(),
+		ManagerID:       user.ManagerID(),
 	}
 }
 
@@ -115,10 +131,14 @@ func NewUserActivatedEvent(user *User) *UserActivatedEvent {
 	}
 }
 
-// UserDeactivatedEvent is raised when a user is deactivated.
+// UserDeactivatedEvent is raised when a user is deactivated. ManagerID is
+// who the user reported to, to whom approvals waiting on the user can be
+// escalated.
 type UserDeactivatedEvent struct {
 	BaseDomainEvent
-	TenantID uuid.UUID `json:"tenant_id"`
+	TenantID  uuid.UUID  `json:"tenant_id"`
+	Reason    string     `json:"reason,omitempty"`
+	ManagerID *uuid.UUID `json:"manager_id"`
 }
 
 // NewUserDeactivatedEvent creates a new UserDeactivatedEvent.
@@ -126,6 +146,8 @@ func NewUserDeactivatedEvent(user *User) *UserDeactivatedEvent {
 	return &UserDeactivatedEvent{
 		BaseDomainEvent: NewBaseDomainEvent(EventTypeUserDeactivated, user.GetID(), AggregateTypeUser),
 		TenantID:        user.TenantID(),
+		Reason:          user.DeactivationReason(),
+		ManagerID:       user.ManagerID(),
 	}
 }
 
@@ -245,6 +267,149 @@ func NewUserRoleRemovedEvent(user *User, role *Role) *UserRoleRemovedEvent {
 	}
 }
 
+// UserRecordsReassignedEvent is raised for each recipient of a completed
+// user reassignment, with the records they received.
+type UserRecordsReassignedEvent struct {
+	BaseDomainEvent
+	TenantID       uuid.UUID `json:"tenant_id"`
+	ReassignmentID uuid.UUID `json:"reassignment_id"`
+	FromUserID     uuid.UUID `json:"from_user_id"`
+	FromUserName   string    `json:"from_user_name"`
+	RecipientID    uuid.UUID `json:"recipient_id"`
+	RecipientName  string    `json:"recipient_name"`
+	Leads          int64     `json:"leads"`
+	Opportunities  int64     `json:"opportunities"`
+	Customers      int64     `json:"customers"`
+	Total          int64     `json:"total"`
+}
+
+// NewUserRecordsReassignedEvent creates a new UserRecordsReassignedEvent.
+func NewUserRecordsReassignedEvent(reassignment *UserReassignment, recipient ReassignmentRecipient) *UserRecordsReassignedEvent {
+	return &UserRecordsReassignedEvent{
+		BaseDomainEvent: NewBaseDomainEvent(EventTypeUserRecordsReassigned, reassignment.UserID(), AggregateTypeUser),
+		TenantID:        reassignment.TenantID(),
+		ReassignmentID:  reassignment.GetID(),
+		FromUserID:      reassignment.UserID(),
+		FromUserName:    reassignment.UserName(),
+		RecipientID:     recipient.UserID,
+		RecipientName:   recipient.Name,
+		Leads:           recipient.Assigned[ReassignmentDatasetLeads],
+		Opportunities:   recipient.Assigned[ReassignmentDatasetOpportunities],
+		Customers:       recipient.Assigned[ReassignmentDatasetCustomers],
+		Total:           recipient.Total(),
+	}
+}
+
+// UserAnonymizedEvent is raised when an offboarded user's personal data is
+// anonymized at the end of the retention period. Services keeping copies of
+// the user's name or email should drop them.
+type UserAnonymizedEvent struct {
+	BaseDomainEvent
+	TenantID uuid.UUID `json:"tenant_id"`
+}
+
+// NewUserAnonymizedEvent creates a new UserAnonymizedEvent.
+func NewUserAnonymizedEvent(user *User) *UserAnonymizedEvent {
+	return &UserAnonymizedEvent{
+		BaseDomainEvent: NewBaseDomainEvent(EventTypeUserAnonymized, user.GetID(), AggregateTypeUser),
+		TenantID:        user.TenantID(),
+	}
+}
+
+// UserManagerChangedEvent is raised when the manager a user reports to is
+// assigned, changed or removed. ManagerID is nil once the user reports to
+// no one.
+type UserManagerChangedEvent struct {
+	BaseDomainEvent
+	TenantID          uuid.UUID  `json:"tenant_id"`
+	ManagerID         *uuid.UUID `json:"manager_id"`
+	PreviousManagerID *uuid.UUID `json:"previous_manager_id"`
+}
+
+// NewUserManagerChangedEvent creates a new UserManagerChangedEvent.
+func NewUserManagerChangedEvent(user *User, previousManagerID *uuid.UUID) *UserManagerChangedEvent {
+	return &UserManagerChangedEvent{
+		BaseDomainEvent:   NewBaseDomainEvent(EventTypeUserManagerChanged, user.GetID(), AggregateTypeUser),
+		TenantID:          user.TenantID(),
+		ManagerID:         user.ManagerID(),
+		PreviousManagerID: previousManagerID,
+	}
+}
+
+// UserSuspiciousActivityEvent is raised when a security event of a user is
+// flagged as suspicious, so the user can be alerted.
+type UserSuspiciousActivityEvent struct {
+	BaseDomainEvent
+	TenantID        uuid.UUID            `json:"tenant_id"`
+	UserID          uuid.UUID            `json:"user_id"`
+	Email           string               `json:"email"`
+	FirstName       string               `json:"first_name"`
+	SecurityEventID uuid.UUID            `json:"security_event_id"`
+	Type            SecurityEventType    `json:"type"`
+	Activities      []SuspiciousActivity `json:"activities"`
+	IPAddress       string               `json:"ip_address,omitempty"`
+	Country         string               `json:"country,omitempty"`
+	UserAgent       string               `json:"user_agent,omitempty"`
+	ReauthRequired  bool                 `json:"reauth_required"`
+	RecordedAt      time.Time            `json:"recorded_at"`
+}
+
+// NewUserSuspiciousActivityEvent creates a new UserSuspiciousActivityEvent.
+func NewUserSuspiciousActivityEvent(event *SecurityEvent, user *User) *UserSuspiciousActivityEvent {
+	return &UserSuspiciousActivityEvent{
+		BaseDomainEvent: NewBaseDomainEvent(EventTypeUserSuspiciousActivity, event.UserID(), AggregateTypeUser),
+		TenantID:        event.TenantID(),
+		UserID:          event.UserID(),
+		Email:           user.Email().String(),
+		FirstName:       user.FirstName(),
+		SecurityEventID: event.GetID(),
+		Type:            event.Type(),
+		Activities:      event.Suspicious(),
+		IPAddress:       event.Source().IPAddress,
+		Country:         event.Source().Country,
+		UserAgent:       event.Source().UserAgent,
+		ReauthRequired:  event.ReauthRequired(),
+		RecordedAt:      event.CreatedAt,
+	}
+}
+
+// ============================================================================
+// Security Events
+// ============================================================================
+
+// SecurityEventRecordedEvent is raised when a security event of a user is
+// recorded. Its event type is EventTypeSecurityPrefix followed by the
+// security event type.
+type SecurityEventRecordedEvent struct {
+	BaseDomainEvent
+	TenantID  uuid.UUID              `json:"tenant_id"`
+	UserID    uuid.UUID              `json:"user_id"`
+	Type      SecurityEventType      `json:"type"`
+	IPAddress string                 `json:"ip_address,omitempty"`
+	UserAgent string                 `json:"user_agent,omitempty"`
+	DeviceID  string                 `json:"device_id,omitempty"`
+	Country   string                 `json:"country,omitempty"`
+	ActorID   *uuid.UUID             `json:"actor_id,omitempty"`
+	Details   map[string]interface{} `json:"details,omitempty"`
+}
+
+// NewSecurityEventRecordedEvent creates a new SecurityEventRecordedEvent.
+func NewSecurityEventRecordedEvent(event *SecurityEvent) *SecurityEventRecordedEvent {
+	source := event.Source()
+	return &SecurityEventRecordedEvent{
+		BaseDomainEvent: NewBaseDomainEvent(EventTypeSecurityPrefix+event.Type().String(), event.GetID(), AggregateTypeSecurityEvent),
+		TenantID:        event.TenantID(),
+		UserID:          event.UserID(),
+		Type:            event.Type(),
+		IPAddress:       source.IPAddress,
+		UserAgent:       source.UserAgent,
+		DeviceID:        source.DeviceID,
+		Country:         source.Country,
+		ActorID:         source.ActorID,
+		Details:         event.Details(),
+	}
+}
+
 // ============================================================================
 // Role Events
 // ============================================================================
@@ -21,6 +21,7 @@ const (
 	ActionUpdate = "update"
 	ActionDelete = "delete"
 	ActionList   = "list"
+	ActionExport = "export"
 	ActionAll    = "*"
 )
 
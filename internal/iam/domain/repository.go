@@ -3,6 +3,7 @@ package domain
 
 import (
 	"context"
+	"time"
 
 	"github.com/google/uuid"
 )
@@ -34,11 +35,18 @@ type UserRepository interface {
 	// FindByRoleID finds all users with a specific role.
 	FindByRoleID(ctx context.Context, roleID uuid.UUID) ([]*User, error)
 
+	// FindByManagerID finds the users reporting directly to a manager.
+	FindByManagerID(ctx context.Context, managerID uuid.UUID) ([]*User, error)
+
 	// ExistsByEmail checks if a user with the email exists in the tenant.
 	ExistsByEmail(ctx context.Context, tenantID uuid.UUID, email Email) (bool, error)
 
 	// CountByTenant returns the number of users for a tenant.
 	CountByTenant(ctx context.Context, tenantID uuid.UUID) (int64, error)
+
+	// FindDueForAnonymization finds up to limit offboarded users of all
+	// tenants deactivated before the time and not anonymized yet.
+	FindDueForAnonymization(ctx context.Context, deactivatedBefore time.Time, limit int) ([]*User, error)
 }
 
 // UserQueryOptions defines options for querying users.
@@ -49,6 +57,7 @@ type UserQueryOptions struct {
 	SortDirection string
 	Status        *UserStatus
 	Search        string
+	IDs           []uuid.UUID // Restricts the results to these users
 	IncludeRoles  bool
 }
 
@@ -145,6 +154,9 @@ type TenantRepository interface {
 	// FindBySlug finds a tenant by slug.
 	FindBySlug(ctx context.Context, slug string) (*Tenant, error)
 
+	// FindByCustomDomain finds a tenant by its custom domain.
+	FindByCustomDomain(ctx context.Context, domain string) (*Tenant, error)
+
 	// FindAll finds all tenants with pagination.
 	FindAll(ctx context.Context, opts TenantQueryOptions) ([]*Tenant, int64, error)
 
@@ -176,6 +188,86 @@ func DefaultTenantQueryOptions() TenantQueryOptions {
 	}
 }
 
+// ============================================================================
+// Tenant Export Repository
+// ============================================================================
+
+// TenantExportRepository defines the interface for tenant export persistence operations.
+type TenantExportRepository interface {
+	// Create creates a new tenant export.
+	Create(ctx context.Context, export *TenantExport) error
+
+	// Update updates an existing tenant export.
+	Update(ctx context.Context, export *TenantExport) error
+
+	// FindByID finds an export of a tenant by ID.
+	FindByID(ctx context.Context, tenantID, id uuid.UUID) (*TenantExport, error)
+
+	// FindByTenant finds the latest exports of a tenant, newest first.
+	FindByTenant(ctx context.Context, tenantID uuid.UUID, limit int) ([]*TenantExport, error)
+
+	// HasActive checks if the tenant has a pending or running export.
+	HasActive(ctx context.Context, tenantID uuid.UUID) (bool, error)
+
+	// ClaimPending starts the oldest pending export and returns it, or nil
+	// if there is none. Concurrent callers never claim the same export, and
+	// running exports abandoned by a crashed worker are claimed again.
+	ClaimPending(ctx context.Context) (*TenantExport, error)
+
+	// FindExpired finds completed exports whose files have expired.
+	FindExpired(ctx context.Context, now time.Time, limit int) ([]*TenantExport, error)
+}
+
+// ============================================================================
+// User Reassignment Repository
+// ============================================================================
+
+// UserReassignmentRepository defines the interface for user reassignment persistence operations.
+type UserReassignmentRepository interface {
+	// Create creates a new user reassignment.
+	Create(ctx context.Context, reassignment *UserReassignment) error
+
+	// Update updates an existing user reassignment.
+	Update(ctx context.Context, reassignment *UserReassignment) error
+
+	// FindByID finds a reassignment of a tenant by ID.
+	FindByID(ctx context.Context, tenantID, id uuid.UUID) (*UserReassignment, error)
+
+	// HasActive checks if the user has a pending or running reassignment.
+	HasActive(ctx context.Context, tenantID, userID uuid.UUID) (bool, error)
+
+	// ClaimPending starts the oldest pending reassignment and returns it, or
+	// nil if there is none. Concurrent callers never claim the same
+	// reassignment, and running reassignments abandoned by a crashed worker
+	// are claimed again.
+	ClaimPending(ctx context.Context) (*UserReassignment, error)
+}
+
+// ============================================================================
+// Security Event Repository
+// ============================================================================
+
+// SecurityEventRepository defines the interface for security event persistence operations.
+type SecurityEventRepository interface {
+	// Create creates a new security event.
+	Create(ctx context.Context, event *SecurityEvent) error
+
+	// FindByUser finds the security events of a user with pagination, newest first.
+	FindByUser(ctx context.Context, tenantID, userID uuid.UUID, opts SecurityEventQueryOptions) ([]*SecurityEvent, int64, error)
+
+	// FindRecentByUser finds at most limit security events of a user recorded
+	// since the given time, newest first.
+	FindRecentByUser(ctx context.Context, userID uuid.UUID, since time.Time, limit int) ([]*SecurityEvent, error)
+}
+
+// SecurityEventQueryOptions defines options for querying security events.
+type SecurityEventQueryOptions struct {
+	Page           int
+	PageSize       int
+	Type           SecurityEventType
+	SuspiciousOnly bool
+}
+
 // ============================================================================
 // Refresh Token Repository
 // ============================================================================
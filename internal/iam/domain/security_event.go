@@ -0,0 +1,294 @@
+// Package domain contains the domain layer for the IAM service.
+package domain
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// SecurityEventType is a kind of security-relevant action on a user's
+// account.
+type SecurityEventType string
+
+const (
+	SecurityEventLoginSucceeded       SecurityEventType = "login_succeeded"
+	SecurityEventLoginFailed          SecurityEventType = "login_failed"
+	SecurityEventPasswordChanged      SecurityEventType = "password_changed"
+	SecurityEventPermissionsChanged   SecurityEventType = "permissions_changed"
+	SecurityEventImpersonationStarted SecurityEventType = "impersonation_started"
+)
+
+// IsValid checks if the security event type is known.
+func (t SecurityEventType) IsValid() bool {
+	switch t {
+	case SecurityEventLoginSucceeded, SecurityEventLoginFailed, SecurityEventPasswordChanged,
+		SecurityEventPermissionsChanged, SecurityEventImpersonationStarted:
+		return true
+	default:
+		return false
+	}
+}
+
+// String returns the string representation of the security event type.
+func (t SecurityEventType) String() string {
+	return string(t)
+}
+
+// SuspiciousActivity is a reason a security event looks suspicious.
+type SuspiciousActivity string
+
+const (
+	// SuspiciousActivityNewDevice is a sign-in from a device the user has
+	// not signed in from before.
+	SuspiciousActivityNewDevice SuspiciousActivity = "new_device"
+	// SuspiciousActivityNewCountry is a sign-in from a country the user has
+	// not signed in from before.
+	SuspiciousActivityNewCountry SuspiciousActivity = "new_country"
+	// SuspiciousActivityRepeatedFailures is a burst of failed sign-ins.
+	SuspiciousActivityRepeatedFailures SuspiciousActivity = "repeated_failures"
+)
+
+// String returns the string representation of the suspicious activity.
+func (a SuspiciousActivity) String() string {
+	return string(a)
+}
+
+// SecuritySource describes where a security event came from.
+type SecuritySource struct {
+	IPAddress string
+	UserAgent string
+	DeviceID  string
+	Country   string
+	// ActorID is the user who performed the action when it is someone other
+	// than the user, such as the administrator changing their roles.
+	ActorID *uuid.UUID
+}
+
+// SecurityEvent records a security-relevant action on a user's account,
+// such as a sign-in or a change of their password or permissions. Events
+// are kept so users can review the activity on their account, and are
+// checked against the security rules as they are recorded.
+type SecurityEvent struct {
+	BaseAggregateRoot
+	tenantID       uuid.UUID
+	userID         uuid.UUID
+	eventType      SecurityEventType
+	source         SecuritySource
+	details        map[string]interface{}
+	suspicious     []SuspiciousActivity
+	reauthRequired bool
+}
+
+// NewSecurityEvent creates a security event of a user.
+func NewSecurityEvent(
+	tenantID, userID uuid.UUID,
+	eventType SecurityEventType,
+	source SecuritySource,
+	details map[string]interface{},
+) (*SecurityEvent, error) {
+	if tenantID == uuid.Nil || userID == uuid.Nil {
+		return nil, ErrSecurityEventUserRequired
+	}
+	if !eventType.IsValid() {
+		return nil, fmt.Errorf("%w: %q", ErrSecurityEventInvalidType, eventType)
+	}
+
+	event := &SecurityEvent{
+		BaseAggregateRoot: NewBaseAggregateRoot(),
+		tenantID:          tenantID,
+		userID:            userID,
+		eventType:         eventType,
+		source:            source,
+		details:           details,
+	}
+	event.AddDomainEvent(NewSecurityEventRecordedEvent(event))
+	return event, nil
+}
+
+// ReconstructSecurityEvent reconstructs a SecurityEvent from persistence.
+func ReconstructSecurityEvent(
+	id, tenantID, userID uuid.UUID,
+	eventType SecurityEventType,
+	source SecuritySource,
+	details map[string]interface{},
+	suspicious []SuspiciousActivity,
+	reauthRequired bool,
+	createdAt time.Time,
+) *SecurityEvent {
+	return &SecurityEvent{
+		BaseAggregateRoot: BaseAggregateRoot{
+			BaseEntity: BaseEntity{
+				ID:        id,
+				CreatedAt: createdAt,
+				UpdatedAt: createdAt,
+			},
+		},
+		tenantID:       tenantID,
+		userID:         userID,
+		eventType:      eventType,
+		source:         source,
+		details:        details,
+		suspicious:     suspicious,
+		reauthRequired: reauthRequired,
+	}
+}
+
+// Getters
+
+// TenantID returns the ID of the tenant.
+func (e *SecurityEvent) TenantID() uuid.UUID {
+	return e.tenantID
+}
+
+// UserID returns the ID of the user whose account the event is about.
+func (e *SecurityEvent) UserID() uuid.UUID {
+	return e.userID
+}
+
+// Type returns the security event type.
+func (e *SecurityEvent) Type() SecurityEventType {
+	return e.eventType
+}
+
+// Source returns where the event came from.
+func (e *SecurityEvent) Source() SecuritySource {
+	return e.source
+}
+
+// Details returns what the event is about, such as the role assigned.
+func (e *SecurityEvent) Details() map[string]interface{} {
+	return e.details
+}
+
+// Suspicious returns why the event looks suspicious, if it does.
+func (e *SecurityEvent) Suspicious() []SuspiciousActivity {
+	return e.suspicious
+}
+
+// IsSuspicious returns true if the event was flagged as suspicious.
+func (e *SecurityEvent) IsSuspicious() bool {
+	return len(e.suspicious) > 0
+}
+
+// ReauthRequired returns true if the user was signed out everywhere because
+// of the event.
+func (e *SecurityEvent) ReauthRequired() bool {
+	return e.reauthRequired
+}
+
+// DeviceKey identifies the device of the event: the device ID the client
+// sent, or else its user agent.
+func (e *SecurityEvent) DeviceKey() string {
+	if e.source.DeviceID != "" {
+		return e.source.DeviceID
+	}
+	return e.source.UserAgent
+}
+
+// Behaviors
+
+// Flag marks the event as suspicious, raising an event so the user is
+// alerted. When reauth is set the user is signed out everywhere.
+func (e *SecurityEvent) Flag(user *User, activities []SuspiciousActivity, reauth bool) {
+	if len(activities) == 0 {
+		return
+	}
+	e.suspicious = activities
+	e.reauthRequired = reauth
+	e.AddDomainEvent(NewUserSuspiciousActivityEvent(e, user))
+}
+
+// ============================================================================
+// Security Rules
+// ============================================================================
+
+// SecurityRules decide which security events are suspicious.
+type SecurityRules struct {
+	// NewDevice flags sign-ins from a device not seen before.
+	NewDevice bool
+	// NewCountry flags sign-ins from a country not seen before.
+	NewCountry bool
+	// MaxFailedLogins flags the failed sign-in that reaches this many
+	// failures within FailureWindow. Zero disables the rule.
+	MaxFailedLogins int
+	FailureWindow   time.Duration
+	// History is how far back devices and countries are remembered.
+	History time.Duration
+	// ForceReauth signs the user out everywhere when an event is flagged.
+	ForceReauth bool
+}
+
+// DefaultSecurityRules returns the default security rules.
+func DefaultSecurityRules() SecurityRules {
+	return SecurityRules{
+		NewDevice:       true,
+		NewCountry:      true,
+		MaxFailedLogins: 5,
+		FailureWindow:   15 * time.Minute,
+		History:         90 * 24 * time.Hour,
+	}
+}
+
+// Detect returns why the event looks suspicious given the user's earlier
+// events within History, or nil. A user's first sign-in is never suspicious,
+// and a burst of failures is reported once, when it reaches the threshold.
+func (r SecurityRules) Detect(event *SecurityEvent, history []*SecurityEvent) []SuspiciousActivity {
+	var activities []SuspiciousActivity
+
+	switch event.Type() {
+	case SecurityEventLoginSucceeded:
+		signedIn := false
+		knownDevice, knownCountry := false, false
+		countries := 0
+		for _, past := range history {
+			if past.Type() != SecurityEventLoginSucceeded {
+				continue
+			}
+			signedIn = true
+			if past.DeviceKey() == event.DeviceKey() {
+				knownDevice = true
+			}
+			if past.Source().Country != "" {
+				countries++
+				if past.Source().Country == event.Source().Country {
+					knownCountry = true
+				}
+			}
+		}
+		if !signedIn {
+			return nil
+		}
+		if r.NewDevice && event.DeviceKey() != "" && !knownDevice {
+			activities = append(activities, SuspiciousActivityNewDevice)
+		}
+		// Without any country on record there is nothing to compare with
+		if r.NewCountry && event.Source().Country != "" && countries > 0 && !knownCountry {
+			activities = append(activities, SuspiciousActivityNewCountry)
+		}
+
+	case SecurityEventLoginFailed:
+		if r.MaxFailedLogins <= 0 {
+			return nil
+		}
+		failures := 1
+		since := event.CreatedAt.Add(-r.FailureWindow)
+		for _, past := range history {
+			if past.Type() == SecurityEventLoginFailed && past.CreatedAt.After(since) {
+				failures++
+			}
+		}
+		if failures == r.MaxFailedLogins {
+			activities = append(activities, SuspiciousActivityRepeatedFailures)
+		}
+	}
+
+	return activities
+}
+
+// Security event errors
+var (
+	ErrSecurityEventUserRequired = fmt.Errorf("security event requires a tenant and a user")
+	ErrSecurityEventInvalidType  = fmt.Errorf("invalid security event type")
+)
@@ -0,0 +1,162 @@
+// Package domain contains the domain layer for the IAM service.
+package domain
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func pastSecurityEvent(userID uuid.UUID, eventType SecurityEventType, source SecuritySource, at time.Time) *SecurityEvent {
+	return ReconstructSecurityEvent(uuid.New(), uuid.New(), userID, eventType, source, nil, nil, false, at)
+}
+
+func TestNewSecurityEvent(t *testing.T) {
+	tenantID, userID := uuid.New(), uuid.New()
+
+	if _, err := NewSecurityEvent(tenantID, uuid.Nil, SecurityEventLoginSucceeded, SecuritySource{}, nil); !errors.Is(err, ErrSecurityEventUserRequired) {
+		t.Errorf("NewSecurityEvent() without a user error = %v, want %v", err, ErrSecurityEventUserRequired)
+	}
+	if _, err := NewSecurityEvent(tenantID, userID, "logged_in", SecuritySource{}, nil); !errors.Is(err, ErrSecurityEventInvalidType) {
+		t.Errorf("NewSecurityEvent() with an unknown type error = %v, want %v", err, ErrSecurityEventInvalidType)
+	}
+
+	event, err := NewSecurityEvent(tenantID, userID, SecurityEventLoginFailed, SecuritySource{UserAgent: "curl/8.0"}, nil)
+	if err != nil {
+		t.Fatalf("NewSecurityEvent() error = %v", err)
+	}
+	if event.DeviceKey() != "curl/8.0" {
+		t.Errorf("DeviceKey() = %q, want the user agent without a device ID", event.DeviceKey())
+	}
+	events := event.GetDomainEvents()
+	if len(events) != 1 || events[0].EventType() != "security.login_failed" {
+		t.Errorf("expected a security.login_failed event, got %v", events)
+	}
+}
+
+func TestSecurityRules_Detect_Logins(t *testing.T) {
+	rules := DefaultSecurityRules()
+	userID := uuid.New()
+	now := time.Now().UTC()
+	laptop := SecuritySource{DeviceID: "laptop", Country: "MY"}
+
+	tests := []struct {
+		name    string
+		source  SecuritySource
+		history []*SecurityEvent
+		want    []SuspiciousActivity
+	}{
+		{
+			name:   "first sign-in",
+			source: SecuritySource{DeviceID: "phone", Country: "SG"},
+		},
+		{
+			name:    "known device and country",
+			source:  laptop,
+			history: []*SecurityEvent{pastSecurityEvent(userID, SecurityEventLoginSucceeded, laptop, now.Add(-time.Hour))},
+		},
+		{
+			name:    "new device",
+			source:  SecuritySource{DeviceID: "phone", Country: "MY"},
+			history: []*SecurityEvent{pastSecurityEvent(userID, SecurityEventLoginSucceeded, laptop, now.Add(-time.Hour))},
+			want:    []SuspiciousActivity{SuspiciousActivityNewDevice},
+		},
+		{
+			name:    "new device and country",
+			source:  SecuritySource{DeviceID: "phone", Country: "SG"},
+			history: []*SecurityEvent{pastSecurityEvent(userID, SecurityEventLoginSucceeded, laptop, now.Add(-time.Hour))},
+			want:    []SuspiciousActivity{SuspiciousActivityNewDevice, SuspiciousActivityNewCountry},
+		},
+		{
+			name:    "no country on record",
+			source:  SecuritySource{DeviceID: "laptop", Country: "SG"},
+			history: []*SecurityEvent{pastSecurityEvent(userID, SecurityEventLoginSucceeded, SecuritySource{DeviceID: "laptop"}, now.Add(-time.Hour))},
+		},
+		{
+			name:    "only failures on record",
+			source:  SecuritySource{DeviceID: "phone", Country: "SG"},
+			history: []*SecurityEvent{pastSecurityEvent(userID, SecurityEventLoginFailed, laptop, now.Add(-time.Hour))},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			event, err := NewSecurityEvent(uuid.New(), userID, SecurityEventLoginSucceeded, tt.source, nil)
+			if err != nil {
+				t.Fatalf("NewSecurityEvent() error = %v", err)
+			}
+
+			got := rules.Detect(event, tt.history)
+			if len(got) != len(tt.want) {
+				t.Fatalf("Detect() = %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("Detect() = %v, want %v", got, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestSecurityRules_Detect_RepeatedFailures(t *testing.T) {
+	rules := DefaultSecurityRules()
+	userID := uuid.New()
+	now := time.Now().UTC()
+
+	var history []*SecurityEvent
+	// An old failure outside the window does not count
+	history = append(history, pastSecurityEvent(userID, SecurityEventLoginFailed, SecuritySource{}, now.Add(-time.Hour)))
+
+	for i := 1; i <= rules.MaxFailedLogins+1; i++ {
+		event, err := NewSecurityEvent(uuid.New(), userID, SecurityEventLoginFailed, SecuritySource{}, nil)
+		if err != nil {
+			t.Fatalf("NewSecurityEvent() error = %v", err)
+		}
+
+		got := rules.Detect(event, history)
+		if i == rules.MaxFailedLogins {
+			if len(got) != 1 || got[0] != SuspiciousActivityRepeatedFailures {
+				t.Errorf("failure %d: Detect() = %v, want repeated failures", i, got)
+			}
+		} else if len(got) != 0 {
+			t.Errorf("failure %d: Detect() = %v, want nothing", i, got)
+		}
+		history = append(history, event)
+	}
+
+	rules.MaxFailedLogins = 0
+	event, _ := NewSecurityEvent(uuid.New(), userID, SecurityEventLoginFailed, SecuritySource{}, nil)
+	if got := rules.Detect(event, history); len(got) != 0 {
+		t.Errorf("Detect() with the rule disabled = %v, want nothing", got)
+	}
+}
+
+func TestSecurityEvent_Flag(t *testing.T) {
+	user := createTestUser(t)
+	event, err := NewSecurityEvent(user.TenantID(), user.GetID(), SecurityEventLoginSucceeded, SecuritySource{Country: "SG"}, nil)
+	if err != nil {
+		t.Fatalf("NewSecurityEvent() error = %v", err)
+	}
+
+	event.Flag(user, nil, true)
+	if event.IsSuspicious() || event.ReauthRequired() {
+		t.Error("expected an event without suspicious activity to be left alone")
+	}
+
+	event.Flag(user, []SuspiciousActivity{SuspiciousActivityNewCountry}, true)
+	if !event.IsSuspicious() || !event.ReauthRequired() {
+		t.Error("expected the event to be flagged with re-authentication required")
+	}
+
+	events := event.GetDomainEvents()
+	if len(events) != 2 || events[1].EventType() != EventTypeUserSuspiciousActivity {
+		t.Fatalf("expected a suspicious activity event, got %v", events)
+	}
+	alert := events[1].(*UserSuspiciousActivityEvent)
+	if alert.Email != "test@example.com" || alert.Country != "SG" || !alert.ReauthRequired {
+		t.Errorf("expected the alert to carry the user and the sign-in, got %+v", alert)
+	}
+}
@@ -3,6 +3,7 @@ package domain
 
 import (
 	"fmt"
+	"net/url"
 	"regexp"
 	"strings"
 	"time"
@@ -103,9 +104,23 @@ type TenantSettings struct {
 	Currency           string                 `json:"currency"`
 	Language           string                 `json:"language"`
 	NotificationsEmail bool                   `json:"notifications_email"`
+	AllowedOrigins     []string               `json:"allowed_origins,omitempty"`
+	CustomDomain       string                 `json:"custom_domain,omitempty"`
+	Branding           *TenantBranding        `json:"branding,omitempty"`
 	Custom             map[string]interface{} `json:"custom"`
 }
 
+// TenantBranding is how a white-labeled tenant presents the CRM to its
+// users and customers: the web app on its custom domain, the emails sent on
+// its behalf and the reports it renders.
+type TenantBranding struct {
+	LogoURL          string `json:"logo_url,omitempty"`
+	PrimaryColor     string `json:"primary_color,omitempty"`
+	SecondaryColor   string `json:"secondary_color,omitempty"`
+	EmailFromAddress string `json:"email_from_address,omitempty"`
+	EmailFromName    string `json:"email_from_name,omitempty"`
+}
+
 // DefaultTenantSettings returns default settings for a tenant.
 func DefaultTenantSettings() TenantSettings {
 	return TenantSettings{
@@ -325,6 +340,144 @@ func (t *Tenant) UpdateSettingsPartial(updates map[string]interface{}) {
 	t.MarkUpdated()
 }
 
+// SetAllowedOrigins sets the browser origins the tenant's users may call the
+// API from, in addition to the platform's own. Origins are written as
+// scheme://host[:port]; a host starting with "*." allows its subdomains.
+func (t *Tenant) SetAllowedOrigins(origins []string) error {
+	normalized := make([]string, 0, len(origins))
+	seen := make(map[string]bool, len(origins))
+	for _, origin := range origins {
+		origin = strings.ToLower(strings.TrimRight(strings.TrimSpace(origin), "/"))
+		if !isValidOrigin(origin) {
+			return fmt.Errorf("%w: %q", ErrTenantOriginInvalid, origin)
+		}
+		if !seen[origin] {
+			seen[origin] = true
+			normalized = append(normalized, origin)
+		}
+	}
+	if len(normalized) > maxTenantOrigins {
+		return ErrTenantTooManyOrigins
+	}
+
+	t.settings.AllowedOrigins = normalized
+	t.MarkUpdated()
+
+	t.AddDomainEvent(NewTenantSettingsUpdatedEvent(t))
+
+	return nil
+}
+
+// maxTenantOrigins is the maximum number of origins a tenant may allow.
+const maxTenantOrigins = 20
+
+// isValidOrigin reports whether origin is an http(s) origin without path,
+// query or credentials, optionally with a subdomain wildcard.
+func isValidOrigin(origin string) bool {
+	u, err := url.Parse(origin)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.User != nil ||
+		u.Path != "" || u.RawQuery != "" || u.Fragment != "" {
+		return false
+	}
+
+	host := strings.TrimPrefix(u.Hostname(), "*.")
+	if host == "" || strings.Contains(host, "*") {
+		return false
+	}
+	// A wildcard must sit under a registrable domain, not a bare TLD
+	if strings.HasPrefix(u.Hostname(), "*.") && !strings.Contains(host, ".") {
+		return false
+	}
+	return true
+}
+
+// SetCustomDomain sets the host name the tenant's users reach the CRM on,
+// such as crm.example.com, or clears it when empty. The gateway resolves
+// requests to the host to the tenant and obtains its certificate.
+func (t *Tenant) SetCustomDomain(domain string) error {
+	domain = strings.ToLower(strings.TrimSuffix(strings.TrimSpace(domain), "."))
+	if domain != "" && !isValidDomain(domain) {
+		return fmt.Errorf("%w: %q", ErrTenantCustomDomainInvalid, domain)
+	}
+
+	t.settings.CustomDomain = domain
+	t.MarkUpdated()
+
+	t.AddDomainEvent(NewTenantSettingsUpdatedEvent(t))
+
+	return nil
+}
+
+// Domain label validation regexes
+var (
+	domainLabelRegex  = regexp.MustCompile(`^[a-z0-9](?:[a-z0-9-]{0,61}[a-z0-9])?$`)
+	numericLabelRegex = regexp.MustCompile(`^[0-9]+$`)
+)
+
+// isValidDomain reports whether domain is a fully qualified host name of at
+// least two labels, without scheme, port or wildcard.
+func isValidDomain(domain string) bool {
+	if len(domain) > 253 {
+		return false
+	}
+	labels := strings.Split(domain, ".")
+	if len(labels) < 2 {
+		return false
+	}
+	for _, label := range labels {
+		if !domainLabelRegex.MatchString(label) {
+			return false
+		}
+	}
+	// The top-level domain is never numeric, which also rules out IP addresses
+	return !numericLabelRegex.MatchString(labels[len(labels)-1])
+}
+
+// SetBranding sets the tenant's branding, or clears it when nil. Colors are
+// written as #rrggbb and the logo must be served over HTTPS.
+func (t *Tenant) SetBranding(branding *TenantBranding) error {
+	if branding != nil {
+		normalized := TenantBranding{
+			LogoURL:          strings.TrimSpace(branding.LogoURL),
+			PrimaryColor:     strings.ToLower(strings.TrimSpace(branding.PrimaryColor)),
+			SecondaryColor:   strings.ToLower(strings.TrimSpace(branding.SecondaryColor)),
+			EmailFromAddress: strings.ToLower(strings.TrimSpace(branding.EmailFromAddress)),
+			EmailFromName:    strings.TrimSpace(branding.EmailFromName),
+		}
+		if normalized.LogoURL != "" {
+			u, err := url.Parse(normalized.LogoURL)
+			if err != nil || u.Scheme != "https" || u.Host == "" || u.User != nil {
+				return fmt.Errorf("%w: logo must be an https URL", ErrTenantBrandingInvalid)
+			}
+		}
+		for _, color := range []string{normalized.PrimaryColor, normalized.SecondaryColor} {
+			if color != "" && !brandColorRegex.MatchString(color) {
+				return fmt.Errorf("%w: color %q must be written as #rrggbb", ErrTenantBrandingInvalid, color)
+			}
+		}
+		if normalized.EmailFromAddress != "" {
+			at := strings.LastIndex(normalized.EmailFromAddress, "@")
+			if at < 1 || !isValidDomain(normalized.EmailFromAddress[at+1:]) {
+				return fmt.Errorf("%w: invalid email from address", ErrTenantBrandingInvalid)
+			}
+		}
+		if len(normalized.EmailFromName) > 100 || strings.ContainsAny(normalized.EmailFromName, "\r\n") {
+			return fmt.Errorf("%w: invalid email from name", ErrTenantBrandingInvalid)
+		}
+		branding = &normalized
+	}
+
+	t.settings.Branding = branding
+	t.MarkUpdated()
+
+	t.AddDomainEvent(NewTenantSettingsUpdatedEvent(t))
+
+	return nil
+}
+
+// brandColorRegex matches a #rrggbb color.
+var brandColorRegex = regexp.MustCompile(`^#[0-9a-f]{6}$`)
+
 // Activate activates the tenant.
 func (t *Tenant) Activate() error {
 	if t.status == TenantStatusActive {
@@ -506,17 +659,22 @@ func (t *Tenant) DeleteMetadata(key string) {
 
 // Tenant errors
 var (
-	ErrTenantNotFound         = fmt.Errorf("tenant not found")
-	ErrTenantNameRequired     = fmt.Errorf("tenant name is required")
-	ErrTenantNameTooLong      = fmt.Errorf("tenant name exceeds maximum length of 255 characters")
-	ErrTenantSlugRequired     = fmt.Errorf("tenant slug is required")
-	ErrTenantSlugTooLong      = fmt.Errorf("tenant slug exceeds maximum length of 100 characters")
-	ErrTenantSlugInvalid      = fmt.Errorf("tenant slug must contain only lowercase letters, numbers, and hyphens")
-	ErrTenantSlugExists       = fmt.Errorf("tenant slug already exists")
-	ErrTenantPlanInvalid      = fmt.Errorf("invalid tenant plan")
-	ErrTenantDeleted          = fmt.Errorf("tenant is deleted")
-	ErrTenantNotSuspended     = fmt.Errorf("tenant is not suspended")
-	ErrTenantAlreadyActivated = fmt.Errorf("tenant is already activated")
-	ErrTenantNotActive        = fmt.Errorf("tenant is not active")
-	ErrTenantLimitReached     = fmt.Errorf("tenant limit reached for current plan")
+	ErrTenantNotFound            = fmt.Errorf("tenant not found")
+	ErrTenantNameRequired        = fmt.Errorf("tenant name is required")
+	ErrTenantNameTooLong         = fmt.Errorf("tenant name exceeds maximum length of 255 characters")
+	ErrTenantSlugRequired        = fmt.Errorf("tenant slug is required")
+	ErrTenantSlugTooLong         = fmt.Errorf("tenant slug exceeds maximum length of 100 characters")
+	ErrTenantSlugInvalid         = fmt.Errorf("tenant slug must contain only lowercase letters, numbers, and hyphens")
+	ErrTenantSlugExists          = fmt.Errorf("tenant slug already exists")
+	ErrTenantPlanInvalid         = fmt.Errorf("invalid tenant plan")
+	ErrTenantDeleted             = fmt.Errorf("tenant is deleted")
+	ErrTenantNotSuspended        = fmt.Errorf("tenant is not suspended")
+	ErrTenantAlreadyActivated    = fmt.Errorf("tenant is already activated")
+	ErrTenantNotActive           = fmt.Errorf("tenant is not active")
+	ErrTenantLimitReached        = fmt.Errorf("tenant limit reached for current plan")
+	ErrTenantOriginInvalid       = fmt.Errorf("invalid tenant origin")
+	ErrTenantTooManyOrigins      = fmt.Errorf("tenant origins exceed maximum of 20")
+	ErrTenantCustomDomainInvalid = fmt.Errorf("invalid tenant custom domain")
+	ErrTenantCustomDomainTaken   = fmt.Errorf("custom domain is used by another tenant")
+	ErrTenantBrandingInvalid     = fmt.Errorf("invalid tenant branding")
 )
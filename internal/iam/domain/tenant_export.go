@@ -0,0 +1,286 @@
+// Package domain contains the domain layer for the IAM service.
+package domain
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// TenantExportStatus represents the status of a tenant export.
+type TenantExportStatus string
+
+const (
+	TenantExportStatusPending   TenantExportStatus = "pending"
+	TenantExportStatusRunning   TenantExportStatus = "running"
+	TenantExportStatusCompleted TenantExportStatus = "completed"
+	TenantExportStatusFailed    TenantExportStatus = "failed"
+	TenantExportStatusExpired   TenantExportStatus = "expired"
+)
+
+// IsActive returns true while the export is still being produced.
+func (s TenantExportStatus) IsActive() bool {
+	return s == TenantExportStatusPending || s == TenantExportStatusRunning
+}
+
+// String returns the string representation of the status.
+func (s TenantExportStatus) String() string {
+	return string(s)
+}
+
+// TenantExportDataset is a data set included in a tenant export.
+type TenantExportDataset string
+
+const (
+	ExportDatasetCustomers     TenantExportDataset = "customers"
+	ExportDatasetContacts      TenantExportDataset = "contacts"
+	ExportDatasetLeads         TenantExportDataset = "leads"
+	ExportDatasetOpportunities TenantExportDataset = "opportunities"
+	ExportDatasetTemplates     TenantExportDataset = "templates"
+	ExportDatasetNotifications TenantExportDataset = "notifications"
+)
+
+// TenantExportDatasets returns every data set of a complete tenant export,
+// in the order they are exported.
+func TenantExportDatasets() []TenantExportDataset {
+	return []TenantExportDataset{
+		ExportDatasetCustomers,
+		ExportDatasetContacts,
+		ExportDatasetLeads,
+		ExportDatasetOpportunities,
+		ExportDatasetTemplates,
+		ExportDatasetNotifications,
+	}
+}
+
+// Service returns the name of the service that owns the data set.
+func (d TenantExportDataset) Service() string {
+	switch d {
+	case ExportDatasetCustomers, ExportDatasetContacts:
+		return "customer"
+	case ExportDatasetLeads, ExportDatasetOpportunities:
+		return "sales"
+	case ExportDatasetTemplates, ExportDatasetNotifications:
+		return "notification"
+	default:
+		return ""
+	}
+}
+
+// String returns the string representation of the data set.
+func (d TenantExportDataset) String() string {
+	return string(d)
+}
+
+// TenantExportFile is the file one data set of an export is written to.
+type TenantExportFile struct {
+	Dataset     TenantExportDataset `json:"dataset"`
+	Key         string              `json:"key,omitempty"`
+	Rows        int64               `json:"rows"`
+	SizeBytes   int64               `json:"size_bytes"`
+	CompletedAt *time.Time          `json:"completed_at,omitempty"`
+}
+
+// IsDone returns true once the data set has been written.
+func (f TenantExportFile) IsDone() bool {
+	return f.CompletedAt != nil
+}
+
+// TenantExport is a complete copy of a tenant's data, written as one JSON
+// lines file per data set to object storage so the tenant can take its data
+// elsewhere.
+type TenantExport struct {
+	BaseEntity
+	tenantID    uuid.UUID
+	requestedBy uuid.UUID
+	status      TenantExportStatus
+	files       []TenantExportFile
+	errorMsg    string
+	startedAt   *time.Time
+	completedAt *time.Time
+	expiresAt   *time.Time
+}
+
+// NewTenantExport creates a pending export of every data set of a tenant.
+func NewTenantExport(tenantID, requestedBy uuid.UUID) (*TenantExport, error) {
+	if tenantID == uuid.Nil {
+		return nil, ErrTenantExportTenantRequired
+	}
+
+	datasets := TenantExportDatasets()
+	files := make([]TenantExportFile, 0, len(datasets))
+	for _, dataset := range datasets {
+		files = append(files, TenantExportFile{Dataset: dataset})
+	}
+
+	return &TenantExport{
+		BaseEntity:  NewBaseEntity(),
+		tenantID:    tenantID,
+		requestedBy: requestedBy,
+		status:      TenantExportStatusPending,
+		files:       files,
+	}, nil
+}
+
+// ReconstructTenantExport reconstructs a TenantExport from persistence.
+func ReconstructTenantExport(
+	id, tenantID, requestedBy uuid.UUID,
+	status TenantExportStatus,
+	files []TenantExportFile,
+	errorMsg string,
+	startedAt, completedAt, expiresAt *time.Time,
+	createdAt, updatedAt time.Time,
+) *TenantExport {
+	return &TenantExport{
+		BaseEntity: BaseEntity{
+			ID:        id,
+			CreatedAt: createdAt,
+			UpdatedAt: updatedAt,
+		},
+		tenantID:    tenantID,
+		requestedBy: requestedBy,
+		status:      status,
+		files:       files,
+		errorMsg:    errorMsg,
+		startedAt:   startedAt,
+		completedAt: completedAt,
+		expiresAt:   expiresAt,
+	}
+}
+
+// Getters
+
+// TenantID returns the ID of the exported tenant.
+func (e *TenantExport) TenantID() uuid.UUID {
+	return e.tenantID
+}
+
+// RequestedBy returns the ID of the user who requested the export.
+func (e *TenantExport) RequestedBy() uuid.UUID {
+	return e.requestedBy
+}
+
+// Status returns the export status.
+func (e *TenantExport) Status() TenantExportStatus {
+	return e.status
+}
+
+// Files returns the files of the export.
+func (e *TenantExport) Files() []TenantExportFile {
+	return e.files
+}
+
+// Error returns why the export failed.
+func (e *TenantExport) Error() string {
+	return e.errorMsg
+}
+
+// StartedAt returns when the export started.
+func (e *TenantExport) StartedAt() *time.Time {
+	return e.startedAt
+}
+
+// CompletedAt returns when the export completed.
+func (e *TenantExport) CompletedAt() *time.Time {
+	return e.completedAt
+}
+
+// ExpiresAt returns when the files of a completed export are deleted.
+func (e *TenantExport) ExpiresAt() *time.Time {
+	return e.expiresAt
+}
+
+// Progress returns the percentage of data sets written.
+func (e *TenantExport) Progress() int {
+	if len(e.files) == 0 {
+		return 0
+	}
+	done := 0
+	for _, file := range e.files {
+		if file.IsDone() {
+			done++
+		}
+	}
+	return done * 100 / len(e.files)
+}
+
+// IsExpired returns true if the files of the export are no longer available.
+func (e *TenantExport) IsExpired(now time.Time) bool {
+	return e.status == TenantExportStatusExpired || (e.expiresAt != nil && now.After(*e.expiresAt))
+}
+
+// Behaviors
+
+// Start marks the export as running.
+func (e *TenantExport) Start() error {
+	if e.status != TenantExportStatusPending {
+		return fmt.Errorf("%w: cannot start a %s export", ErrTenantExportInvalidState, e.status)
+	}
+	now := time.Now().UTC()
+	e.status = TenantExportStatusRunning
+	e.startedAt = &now
+	e.MarkUpdated()
+	return nil
+}
+
+// CompleteFile records that a data set has been written to key.
+func (e *TenantExport) CompleteFile(dataset TenantExportDataset, key string, rows, sizeBytes int64) error {
+	if e.status != TenantExportStatusRunning {
+		return fmt.Errorf("%w: cannot write files of a %s export", ErrTenantExportInvalidState, e.status)
+	}
+	for i := range e.files {
+		if e.files[i].Dataset != dataset {
+			continue
+		}
+		now := time.Now().UTC()
+		e.files[i].Key = key
+		e.files[i].Rows = rows
+		e.files[i].SizeBytes = sizeBytes
+		e.files[i].CompletedAt = &now
+		e.MarkUpdated()
+		return nil
+	}
+	return fmt.Errorf("%w: %q", ErrTenantExportUnknownDataset, dataset)
+}
+
+// Complete marks the export as completed once every data set is written.
+// Its files are kept for keepFor.
+func (e *TenantExport) Complete(keepFor time.Duration) error {
+	if e.status != TenantExportStatusRunning {
+		return fmt.Errorf("%w: cannot complete a %s export", ErrTenantExportInvalidState, e.status)
+	}
+	if e.Progress() < 100 {
+		return fmt.Errorf("%w: data sets are still being written", ErrTenantExportInvalidState)
+	}
+	now := time.Now().UTC()
+	expiresAt := now.Add(keepFor)
+	e.status = TenantExportStatusCompleted
+	e.completedAt = &now
+	e.expiresAt = &expiresAt
+	e.MarkUpdated()
+	return nil
+}
+
+// Fail marks the export as failed.
+func (e *TenantExport) Fail(reason string) {
+	now := time.Now().UTC()
+	e.status = TenantExportStatusFailed
+	e.errorMsg = reason
+	e.completedAt = &now
+	e.MarkUpdated()
+}
+
+// Expire marks the files of the export as deleted.
+func (e *TenantExport) Expire() {
+	e.status = TenantExportStatusExpired
+	e.MarkUpdated()
+}
+
+// Tenant export errors
+var (
+	ErrTenantExportNotFound       = fmt.Errorf("tenant export not found")
+	ErrTenantExportTenantRequired = fmt.Errorf("tenant export requires a tenant")
+	ErrTenantExportInvalidState   = fmt.Errorf("invalid tenant export state")
+	ErrTenantExportUnknownDataset = fmt.Errorf("unknown tenant export data set")
+)
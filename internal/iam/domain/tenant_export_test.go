@@ -0,0 +1,93 @@
+// Package domain contains the domain layer for the IAM service.
+package domain
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestNewTenantExport(t *testing.T) {
+	export, err := NewTenantExport(uuid.New(), uuid.New())
+	if err != nil {
+		t.Fatalf("NewTenantExport() error = %v", err)
+	}
+
+	if export.Status() != TenantExportStatusPending {
+		t.Errorf("Status() = %v, want %v", export.Status(), TenantExportStatusPending)
+	}
+	if len(export.Files()) != len(TenantExportDatasets()) {
+		t.Errorf("expected a file per data set, got %d", len(export.Files()))
+	}
+	for _, file := range export.Files() {
+		if file.Dataset.Service() == "" {
+			t.Errorf("data set %q has no owning service", file.Dataset)
+		}
+	}
+
+	if _, err := NewTenantExport(uuid.Nil, uuid.New()); !errors.Is(err, ErrTenantExportTenantRequired) {
+		t.Errorf("NewTenantExport() without tenant error = %v, want %v", err, ErrTenantExportTenantRequired)
+	}
+}
+
+func TestTenantExport_Lifecycle(t *testing.T) {
+	export, _ := NewTenantExport(uuid.New(), uuid.New())
+
+	if err := export.CompleteFile(ExportDatasetLeads, "leads.jsonl", 1, 10); !errors.Is(err, ErrTenantExportInvalidState) {
+		t.Errorf("CompleteFile() before Start() error = %v, want %v", err, ErrTenantExportInvalidState)
+	}
+	if err := export.Start(); err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+	if err := export.Start(); !errors.Is(err, ErrTenantExportInvalidState) {
+		t.Errorf("second Start() error = %v, want %v", err, ErrTenantExportInvalidState)
+	}
+
+	if err := export.CompleteFile(ExportDatasetLeads, "leads.jsonl", 42, 4096); err != nil {
+		t.Fatalf("CompleteFile() error = %v", err)
+	}
+	if got, want := export.Progress(), 100/len(TenantExportDatasets()); got != want {
+		t.Errorf("Progress() = %d, want %d", got, want)
+	}
+	if err := export.Complete(time.Hour); !errors.Is(err, ErrTenantExportInvalidState) {
+		t.Errorf("Complete() with data sets left error = %v, want %v", err, ErrTenantExportInvalidState)
+	}
+	if err := export.CompleteFile("invoices", "invoices.jsonl", 1, 1); !errors.Is(err, ErrTenantExportUnknownDataset) {
+		t.Errorf("CompleteFile() of unknown data set error = %v, want %v", err, ErrTenantExportUnknownDataset)
+	}
+
+	for _, dataset := range TenantExportDatasets() {
+		if err := export.CompleteFile(dataset, dataset.String()+".jsonl", 1, 1); err != nil {
+			t.Fatalf("CompleteFile(%s) error = %v", dataset, err)
+		}
+	}
+	if err := export.Complete(time.Hour); err != nil {
+		t.Fatalf("Complete() error = %v", err)
+	}
+
+	if export.Status() != TenantExportStatusCompleted || export.Progress() != 100 {
+		t.Errorf("expected a completed export, got %v at %d%%", export.Status(), export.Progress())
+	}
+	if export.IsExpired(time.Now()) {
+		t.Error("expected the export to be available until it expires")
+	}
+	if !export.IsExpired(time.Now().Add(2 * time.Hour)) {
+		t.Error("expected the export to expire after its retention")
+	}
+}
+
+func TestTenantExport_Fail(t *testing.T) {
+	export, _ := NewTenantExport(uuid.New(), uuid.New())
+	_ = export.Start()
+
+	export.Fail("sales service unavailable")
+
+	if export.Status() != TenantExportStatusFailed || export.Error() != "sales service unavailable" {
+		t.Errorf("expected a failed export, got %v (%q)", export.Status(), export.Error())
+	}
+	if export.Status().IsActive() {
+		t.Error("expected a failed export not to be active")
+	}
+}
@@ -2,6 +2,7 @@
 package domain
 
 import (
+	"errors"
 	"strings"
 	"testing"
 	"time"
@@ -882,3 +883,98 @@ func TestTenant_SlugConversion(t *testing.T) {
 		t.Errorf("NewTenant() should convert slug to lowercase, got %v", tenant.Slug())
 	}
 }
+
+func TestTenant_SetAllowedOrigins(t *testing.T) {
+	tenant, err := NewTenant("Test Tenant", "test-tenant")
+	if err != nil {
+		t.Fatalf("NewTenant() unexpected error = %v", err)
+	}
+
+	err = tenant.SetAllowedOrigins([]string{"https://Portal.Example.com/", "https://*.example.com", "http://localhost:8080", "https://portal.example.com"})
+	if err != nil {
+		t.Fatalf("Tenant.SetAllowedOrigins() unexpected error = %v", err)
+	}
+	origins := tenant.Settings().AllowedOrigins
+	if len(origins) != 3 || origins[0] != "https://portal.example.com" {
+		t.Errorf("Tenant.SetAllowedOrigins() = %v, want normalized and deduplicated origins", origins)
+	}
+
+	invalid := []string{"*", "https://*", "https://*.com", "ftp://example.com", "https://example.com/app", "example.com", "https://a.*.example.com"}
+	for _, origin := range invalid {
+		if err := tenant.SetAllowedOrigins([]string{origin}); !errors.Is(err, ErrTenantOriginInvalid) {
+			t.Errorf("Tenant.SetAllowedOrigins(%q) error = %v, want ErrTenantOriginInvalid", origin, err)
+		}
+	}
+	if len(tenant.Settings().AllowedOrigins) != 3 {
+		t.Error("Tenant.SetAllowedOrigins() should keep the origins when rejecting an update")
+	}
+}
+
+func TestTenant_SetCustomDomain(t *testing.T) {
+	tenant, err := NewTenant("Test Tenant", "test-tenant")
+	if err != nil {
+		t.Fatalf("NewTenant() unexpected error = %v", err)
+	}
+
+	if err := tenant.SetCustomDomain(" CRM.TheirBrand.com. "); err != nil {
+		t.Fatalf("Tenant.SetCustomDomain() unexpected error = %v", err)
+	}
+	if got := tenant.Settings().CustomDomain; got != "crm.theirbrand.com" {
+		t.Errorf("Tenant.SetCustomDomain() = %q, want the normalized host name", got)
+	}
+
+	invalid := []string{"localhost", "https://crm.example.com", "crm.example.com:8443", "*.example.com", "10.0.0.1", "crm_1.example.com", "-crm.example.com"}
+	for _, domain := range invalid {
+		if err := tenant.SetCustomDomain(domain); !errors.Is(err, ErrTenantCustomDomainInvalid) {
+			t.Errorf("Tenant.SetCustomDomain(%q) error = %v, want ErrTenantCustomDomainInvalid", domain, err)
+		}
+	}
+	if tenant.Settings().CustomDomain != "crm.theirbrand.com" {
+		t.Error("Tenant.SetCustomDomain() should keep the domain when rejecting an update")
+	}
+
+	if err := tenant.SetCustomDomain(""); err != nil || tenant.Settings().CustomDomain != "" {
+		t.Errorf("Tenant.SetCustomDomain(\"\") = %q, %v, want the domain cleared", tenant.Settings().CustomDomain, err)
+	}
+}
+
+func TestTenant_SetBranding(t *testing.T) {
+	tenant, err := NewTenant("Test Tenant", "test-tenant")
+	if err != nil {
+		t.Fatalf("NewTenant() unexpected error = %v", err)
+	}
+
+	err = tenant.SetBranding(&TenantBranding{
+		LogoURL:          "https://cdn.theirbrand.com/logo.png",
+		PrimaryColor:     "#1A73E8",
+		EmailFromAddress: " Sales@TheirBrand.com ",
+		EmailFromName:    "TheirBrand Sales",
+	})
+	if err != nil {
+		t.Fatalf("Tenant.SetBranding() unexpected error = %v", err)
+	}
+	branding := tenant.Settings().Branding
+	if branding == nil || branding.PrimaryColor != "#1a73e8" || branding.EmailFromAddress != "sales@theirbrand.com" {
+		t.Errorf("Tenant.SetBranding() = %+v, want normalized colors and address", branding)
+	}
+
+	invalid := []*TenantBranding{
+		{LogoURL: "http://cdn.theirbrand.com/logo.png"},
+		{PrimaryColor: "blue"},
+		{SecondaryColor: "#fff"},
+		{EmailFromAddress: "sales"},
+		{EmailFromName: "Sales\r\nBcc: victim@example.com"},
+	}
+	for _, b := range invalid {
+		if err := tenant.SetBranding(b); !errors.Is(err, ErrTenantBrandingInvalid) {
+			t.Errorf("Tenant.SetBranding(%+v) error = %v, want ErrTenantBrandingInvalid", b, err)
+		}
+	}
+	if tenant.Settings().Branding.PrimaryColor != "#1a73e8" {
+		t.Error("Tenant.SetBranding() should keep the branding when rejecting an update")
+	}
+
+	if err := tenant.SetBranding(nil); err != nil || tenant.Settings().Branding != nil {
+		t.Errorf("Tenant.SetBranding(nil) = %+v, %v, want the branding cleared", tenant.Settings().Branding, err)
+	}
+}
@@ -44,6 +44,7 @@ type User struct {
 	avatarURL       string
 	phone           string
 	status          UserStatus
+	managerID       *uuid.UUID
 	emailVerifiedAt *time.Time
 	lastLoginAt     *time.Time
 	metadata        map[string]interface{}
@@ -91,6 +92,7 @@ func ReconstructUser(
 	passwordHash Password,
 	firstName, lastName, avatarURL, phone string,
 	status UserStatus,
+	managerID *uuid.UUID,
 	emailVerifiedAt, lastLoginAt *time.Time,
 	metadata map[string]interface{},
 	createdAt, updatedAt time.Time,
@@ -114,6 +116,7 @@ func ReconstructUser(
 		avatarURL:       avatarURL,
 		phone:           phone,
 		status:          status,
+		managerID:       managerID,
 		emailVerifiedAt: emailVerifiedAt,
 		lastLoginAt:     lastLoginAt,
 		metadata:        metadata,
@@ -298,7 +301,12 @@ func (u *User) Activate() error {
 		return ErrUserDeleted
 	}
 
+	if u.IsAnonymized() {
+		return ErrUserAnonymized
+	}
+
 	u.status = UserStatusActive
+	u.clearOffboarding()
 	u.MarkUpdated()
 
 	u.AddDomainEvent(NewUserActivatedEvent(u))
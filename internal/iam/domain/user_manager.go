@@ -0,0 +1,94 @@
+// Package domain contains the domain layer for the IAM service.
+package domain
+
+import (
+	"fmt"
+
+	"github.com/google/uuid"
+)
+
+// MaxReportingLineDepth is how many managers a reporting line may have above
+// a user. It bounds walking up the organization chart, so a manager cannot be
+// assigned if that makes a reporting line longer.
+const MaxReportingLineDepth = 50
+
+// ManagerID returns the ID of the manager the user reports to, or nil if they
+// report to no one.
+func (u *User) ManagerID() *uuid.UUID {
+	return u.managerID
+}
+
+// ReportsTo returns true if the user's manager is the given user.
+func (u *User) ReportsTo(managerID uuid.UUID) bool {
+	return u.managerID != nil && *u.managerID == managerID
+}
+
+// AssignManager makes the user report to manager, who must be an active
+// member of the same tenant. The user must not be among the managers above
+// manager; checking that needs the reporting line of manager, so it is left
+// to the caller with CheckReportingLine.
+func (u *User) AssignManager(manager *User) error {
+	if u.IsDeleted() {
+		return ErrUserDeleted
+	}
+	if manager.GetID() == u.GetID() {
+		return ErrUserOwnManager
+	}
+	if manager.TenantID() != u.tenantID {
+		return ErrManagerTenantMismatch
+	}
+	if manager.IsDeleted() || manager.Status() == UserStatusInactive {
+		return ErrManagerNotActive
+	}
+	if u.ReportsTo(manager.GetID()) {
+		return nil
+	}
+
+	previous := u.managerID
+	managerID := manager.GetID()
+	u.managerID = &managerID
+	u.MarkUpdated()
+
+	u.AddDomainEvent(NewUserManagerChangedEvent(u, previous))
+
+	return nil
+}
+
+// RemoveManager makes the user report to no one.
+func (u *User) RemoveManager() {
+	if u.managerID == nil {
+		return
+	}
+
+	previous := u.managerID
+	u.managerID = nil
+	u.MarkUpdated()
+
+	u.AddDomainEvent(NewUserManagerChangedEvent(u, previous))
+}
+
+// CheckReportingLine checks that the user can report to the first user of
+// line, which lists a prospective manager followed by the managers above
+// them, nearest first. Reporting to them fails if it closes a cycle, that is
+// the user is already above them, or if the user's line would grow longer
+// than MaxReportingLineDepth.
+func (u *User) CheckReportingLine(line []*User) error {
+	for _, manager := range line {
+		if manager.GetID() == u.GetID() {
+			return ErrManagerCycle
+		}
+	}
+	if len(line) > MaxReportingLineDepth {
+		return ErrReportingLineTooDeep
+	}
+	return nil
+}
+
+// User manager errors
+var (
+	ErrUserOwnManager        = fmt.Errorf("user cannot be their own manager")
+	ErrManagerTenantMismatch = fmt.Errorf("manager belongs to another tenant")
+	ErrManagerNotActive      = fmt.Errorf("manager is not active")
+	ErrManagerCycle          = fmt.Errorf("manager reports to the user")
+	ErrReportingLineTooDeep  = fmt.Errorf("reporting line is too deep")
+)
@@ -0,0 +1,112 @@
+// Package domain contains the domain layer for the IAM service.
+package domain
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func createTestUserInTenant(t *testing.T, tenantID uuid.UUID) *User {
+	t.Helper()
+	user, err := NewUser(tenantID, MustNewEmail("member@example.com"), NewPasswordFromHash("hashed_password"), "Jane", "Doe")
+	if err != nil {
+		t.Fatalf("Failed to create test user: %v", err)
+	}
+	return user
+}
+
+func TestUser_AssignManager(t *testing.T) {
+	user := createTestUser(t)
+	manager := createTestUserInTenant(t, user.TenantID())
+	user.ClearDomainEvents()
+
+	if err := user.AssignManager(manager); err != nil {
+		t.Fatalf("AssignManager() error = %v", err)
+	}
+	if !user.ReportsTo(manager.GetID()) {
+		t.Errorf("ManagerID() = %v, want %s", user.ManagerID(), manager.GetID())
+	}
+
+	events := user.GetDomainEvents()
+	if len(events) != 1 || events[0].EventType() != EventTypeUserManagerChanged {
+		t.Fatalf("expected a %s event, got %v", EventTypeUserManagerChanged, events)
+	}
+	changed := events[0].(*UserManagerChangedEvent)
+	if changed.ManagerID == nil || *changed.ManagerID != manager.GetID() || changed.PreviousManagerID != nil {
+		t.Errorf("unexpected event %+v", changed)
+	}
+
+	// Assigning the same manager again changes nothing
+	user.ClearDomainEvents()
+	if err := user.AssignManager(manager); err != nil {
+		t.Fatalf("AssignManager() again error = %v", err)
+	}
+	if len(user.GetDomainEvents()) != 0 {
+		t.Error("expected no event when the manager is unchanged")
+	}
+
+	user.RemoveManager()
+	if user.ManagerID() != nil {
+		t.Errorf("ManagerID() = %v, want nil", user.ManagerID())
+	}
+	events = user.GetDomainEvents()
+	if len(events) != 1 {
+		t.Fatalf("expected an event for removing the manager, got %d", len(events))
+	}
+	if previous := events[0].(*UserManagerChangedEvent).PreviousManagerID; previous == nil || *previous != manager.GetID() {
+		t.Errorf("PreviousManagerID = %v, want %s", previous, manager.GetID())
+	}
+}
+
+func TestUser_AssignManager_Invalid(t *testing.T) {
+	user := createTestUser(t)
+
+	offboarded := createTestUserInTenant(t, user.TenantID())
+	if err := offboarded.Offboard("", uuid.New()); err != nil {
+		t.Fatalf("Offboard() error = %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		manager *User
+		wantErr error
+	}{
+		{"self", user, ErrUserOwnManager},
+		{"other tenant", createTestUserInTenant(t, uuid.New()), ErrManagerTenantMismatch},
+		{"offboarded", offboarded, ErrManagerNotActive},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := user.AssignManager(tt.manager); !errors.Is(err, tt.wantErr) {
+				t.Errorf("AssignManager() error = %v, want %v", err, tt.wantErr)
+			}
+			if user.ManagerID() != nil {
+				t.Error("expected no manager to be assigned")
+			}
+		})
+	}
+}
+
+func TestUser_CheckReportingLine(t *testing.T) {
+	user := createTestUser(t)
+	manager := createTestUserInTenant(t, user.TenantID())
+	director := createTestUserInTenant(t, user.TenantID())
+
+	if err := user.CheckReportingLine([]*User{manager, director}); err != nil {
+		t.Errorf("CheckReportingLine() error = %v", err)
+	}
+	if err := user.CheckReportingLine([]*User{manager, user, director}); !errors.Is(err, ErrManagerCycle) {
+		t.Errorf("CheckReportingLine() error = %v, want %v", err, ErrManagerCycle)
+	}
+
+	line := make([]*User, MaxReportingLineDepth+1)
+	for i := range line {
+		line[i] = manager
+	}
+	if err := user.CheckReportingLine(line); !errors.Is(err, ErrReportingLineTooDeep) {
+		t.Errorf("CheckReportingLine() error = %v, want %v", err, ErrReportingLineTooDeep)
+	}
+}
@@ -0,0 +1,146 @@
+// Package domain contains the domain layer for the IAM service.
+package domain
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// DefaultUserRetentionPeriod is how long an offboarded user is kept as they
+// were, so audit logs and records still name them, before their personal data
+// is anonymized.
+const DefaultUserRetentionPeriod = 365 * 24 * time.Hour
+
+// Metadata keys recording a user's offboarding.
+const (
+	userMetadataDeactivatedAt      = "deactivated_at"
+	userMetadataDeactivatedBy      = "deactivated_by"
+	userMetadataDeactivationReason = "deactivation_reason"
+	userMetadataAnonymizedAt       = "anonymized_at"
+)
+
+// anonymizedPasswordHash replaces the password of anonymized users. It is no
+// bcrypt hash, so no password ever matches it.
+const anonymizedPasswordHash = "!anonymized"
+
+// Offboard deactivates a departing user. Unlike Deactivate, it records when,
+// why and by whom, which starts the retention period after which the user is
+// anonymized. The user itself is kept, so the records and audit logs
+// referring to them stay valid.
+func (u *User) Offboard(reason string, offboardedBy uuid.UUID) error {
+	if u.IsDeleted() {
+		return ErrUserDeleted
+	}
+	if u.IsAnonymized() {
+		return ErrUserAnonymized
+	}
+	if u.DeactivatedAt() != nil {
+		return ErrUserAlreadyOffboarded
+	}
+
+	u.status = UserStatusInactive
+	u.metadata[userMetadataDeactivatedAt] = time.Now().UTC().Format(time.RFC3339)
+	u.metadata[userMetadataDeactivatedBy] = offboardedBy.String()
+	if reason != "" {
+		u.metadata[userMetadataDeactivationReason] = reason
+	}
+	u.MarkUpdated()
+
+	u.AddDomainEvent(NewUserDeactivatedEvent(u))
+
+	return nil
+}
+
+// DeactivatedAt returns when the user was offboarded, or nil if they were not.
+func (u *User) DeactivatedAt() *time.Time {
+	return u.metadataTime(userMetadataDeactivatedAt)
+}
+
+// DeactivationReason returns why the user was offboarded.
+func (u *User) DeactivationReason() string {
+	reason, _ := u.metadata[userMetadataDeactivationReason].(string)
+	return reason
+}
+
+// AnonymizedAt returns when the user's personal data was anonymized, or nil
+// if it was not.
+func (u *User) AnonymizedAt() *time.Time {
+	return u.metadataTime(userMetadataAnonymizedAt)
+}
+
+// IsAnonymized returns true if the user's personal data was anonymized.
+func (u *User) IsAnonymized() bool {
+	return u.AnonymizedAt() != nil
+}
+
+// AnonymizationDue returns true if the user was offboarded longer than the
+// retention period ago and is still identifiable.
+func (u *User) AnonymizationDue(retention time.Duration, now time.Time) bool {
+	deactivatedAt := u.DeactivatedAt()
+	return deactivatedAt != nil && !u.IsAnonymized() &&
+		u.status == UserStatusInactive && !deactivatedAt.Add(retention).After(now)
+}
+
+// Anonymize irreversibly replaces the personal data of an offboarded user.
+// The user keeps their ID, tenant and roles, so the records and audit logs
+// referring to them stay valid, but can never log in again.
+func (u *User) Anonymize() error {
+	if u.IsAnonymized() {
+		return nil
+	}
+	if u.DeactivatedAt() == nil || u.status != UserStatusInactive {
+		return ErrUserNotOffboarded
+	}
+
+	email, err := NewEmail(fmt.Sprintf("anonymized-%s@anonymized.invalid", u.GetID()))
+	if err != nil {
+		return err
+	}
+
+	u.email = email
+	u.passwordHash = NewPasswordFromHash(anonymizedPasswordHash)
+	u.firstName = "Former"
+	u.lastName = "user"
+	u.avatarURL = ""
+	u.phone = ""
+	u.emailVerifiedAt = nil
+	u.metadata = map[string]interface{}{
+		userMetadataDeactivatedAt: u.metadata[userMetadataDeactivatedAt],
+		userMetadataAnonymizedAt:  time.Now().UTC().Format(time.RFC3339),
+	}
+	u.MarkUpdated()
+
+	u.AddDomainEvent(NewUserAnonymizedEvent(u))
+
+	return nil
+}
+
+// clearOffboarding forgets the user's offboarding when they are activated
+// again, which stops their anonymization.
+func (u *User) clearOffboarding() {
+	delete(u.metadata, userMetadataDeactivatedAt)
+	delete(u.metadata, userMetadataDeactivatedBy)
+	delete(u.metadata, userMetadataDeactivationReason)
+}
+
+// metadataTime reads a timestamp kept in the user's metadata.
+func (u *User) metadataTime(key string) *time.Time {
+	value, ok := u.metadata[key].(string)
+	if !ok {
+		return nil
+	}
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return nil
+	}
+	return &t
+}
+
+// User offboarding errors
+var (
+	ErrUserAlreadyOffboarded = fmt.Errorf("user is already offboarded")
+	ErrUserNotOffboarded     = fmt.Errorf("user is not offboarded")
+	ErrUserAnonymized        = fmt.Errorf("user is anonymized")
+)
@@ -0,0 +1,78 @@
+// Package domain contains the domain layer for the IAM service.
+package domain
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestUser_Offboard(t *testing.T) {
+	user := createTestUser(t)
+	offboardedBy := uuid.New()
+
+	if err := user.Offboard("Resigned", offboardedBy); err != nil {
+		t.Fatalf("Offboard() error = %v", err)
+	}
+
+	if user.Status() != UserStatusInactive || user.CanLogin() {
+		t.Errorf("expected an inactive user who cannot log in, got %v", user.Status())
+	}
+	if user.DeactivatedAt() == nil || user.DeactivationReason() != "Resigned" {
+		t.Errorf("expected the offboarding to be recorded, got %v (%q)", user.DeactivatedAt(), user.DeactivationReason())
+	}
+	if err := user.Offboard("", offboardedBy); !errors.Is(err, ErrUserAlreadyOffboarded) {
+		t.Errorf("Offboard() twice error = %v, want %v", err, ErrUserAlreadyOffboarded)
+	}
+
+	// Activating the user again stops their anonymization
+	if err := user.Activate(); err != nil {
+		t.Fatalf("Activate() error = %v", err)
+	}
+	if user.DeactivatedAt() != nil || user.AnonymizationDue(0, time.Now()) {
+		t.Error("expected the offboarding to be forgotten once activated")
+	}
+}
+
+func TestUser_Anonymize(t *testing.T) {
+	user := createTestUser(t)
+	roleCount := len(user.Roles())
+
+	if err := user.Anonymize(); !errors.Is(err, ErrUserNotOffboarded) {
+		t.Fatalf("Anonymize() of an active user error = %v, want %v", err, ErrUserNotOffboarded)
+	}
+
+	if err := user.Offboard("", uuid.New()); err != nil {
+		t.Fatalf("Offboard() error = %v", err)
+	}
+	if user.AnonymizationDue(time.Hour, time.Now()) {
+		t.Error("expected no anonymization within the retention period")
+	}
+	if !user.AnonymizationDue(time.Hour, time.Now().Add(2*time.Hour)) {
+		t.Error("expected anonymization after the retention period")
+	}
+
+	user.ClearDomainEvents()
+	if err := user.Anonymize(); err != nil {
+		t.Fatalf("Anonymize() error = %v", err)
+	}
+
+	if !user.IsAnonymized() || user.DeactivatedAt() == nil {
+		t.Error("expected the user to be anonymized with their offboarding kept")
+	}
+	if user.Email().String() == "test@example.com" || user.Phone() != "" || user.FullName() != "Former user" {
+		t.Errorf("expected the personal data to be replaced, got %s, %s, %q", user.Email(), user.FullName(), user.Phone())
+	}
+	if user.PasswordHash().Hash() != anonymizedPasswordHash || len(user.Roles()) != roleCount {
+		t.Error("expected an unusable password and the roles kept")
+	}
+	if events := user.GetDomainEvents(); len(events) != 1 || events[0].EventType() != EventTypeUserAnonymized {
+		t.Errorf("expected a user anonymized event, got %v", events)
+	}
+
+	if err := user.Activate(); !errors.Is(err, ErrUserAnonymized) {
+		t.Errorf("Activate() of an anonymized user error = %v, want %v", err, ErrUserAnonymized)
+	}
+}
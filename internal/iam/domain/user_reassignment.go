@@ -0,0 +1,452 @@
+// Package domain contains the domain layer for the IAM service.
+package domain
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// UserReassignmentStatus represents the status of a user reassignment.
+type UserReassignmentStatus string
+
+const (
+	UserReassignmentStatusPending   UserReassignmentStatus = "pending"
+	UserReassignmentStatusRunning   UserReassignmentStatus = "running"
+	UserReassignmentStatusCompleted UserReassignmentStatus = "completed"
+	UserReassignmentStatusFailed    UserReassignmentStatus = "failed"
+)
+
+// IsActive returns true while the records are still being reassigned.
+func (s UserReassignmentStatus) IsActive() bool {
+	return s == UserReassignmentStatusPending || s == UserReassignmentStatusRunning
+}
+
+// String returns the string representation of the status.
+func (s UserReassignmentStatus) String() string {
+	return string(s)
+}
+
+// ReassignmentStrategy is how a user's records are shared among recipients.
+type ReassignmentStrategy string
+
+const (
+	// ReassignmentStrategyRoundRobin hands the records of every data set to
+	// the recipients in turn.
+	ReassignmentStrategyRoundRobin ReassignmentStrategy = "round_robin"
+	// ReassignmentStrategyMapping hands each data set to the recipient it is
+	// mapped to. Data sets mapped to nobody keep their owner.
+	ReassignmentStrategyMapping ReassignmentStrategy = "mapping"
+)
+
+// IsValid checks if the strategy is known.
+func (s ReassignmentStrategy) IsValid() bool {
+	return s == ReassignmentStrategyRoundRobin || s == ReassignmentStrategyMapping
+}
+
+// String returns the string representation of the strategy.
+func (s ReassignmentStrategy) String() string {
+	return string(s)
+}
+
+// ReassignmentDataset is a kind of record a user owns that can be
+// reassigned.
+type ReassignmentDataset string
+
+const (
+	ReassignmentDatasetLeads         ReassignmentDataset = "leads"
+	ReassignmentDatasetOpportunities ReassignmentDataset = "opportunities"
+	ReassignmentDatasetCustomers     ReassignmentDataset = "customers"
+)
+
+// ReassignmentDatasets returns every data set that can be reassigned, in the
+// order they are reassigned.
+func ReassignmentDatasets() []ReassignmentDataset {
+	return []ReassignmentDataset{
+		ReassignmentDatasetLeads,
+		ReassignmentDatasetOpportunities,
+		ReassignmentDatasetCustomers,
+	}
+}
+
+// Service returns the name of the service that owns the data set.
+func (d ReassignmentDataset) Service() string {
+	switch d {
+	case ReassignmentDatasetCustomers:
+		return "customer"
+	case ReassignmentDatasetLeads, ReassignmentDatasetOpportunities:
+		return "sales"
+	default:
+		return ""
+	}
+}
+
+// String returns the string representation of the data set.
+func (d ReassignmentDataset) String() string {
+	return string(d)
+}
+
+// ReassignmentRecipient is a user receiving reassigned records, with the
+// number of records received per data set.
+type ReassignmentRecipient struct {
+	UserID   uuid.UUID                     `json:"user_id"`
+	Name     string                        `json:"name"`
+	Datasets []ReassignmentDataset         `json:"datasets,omitempty"`
+	Assigned map[ReassignmentDataset]int64 `json:"assigned,omitempty"`
+}
+
+// Receives returns true if the recipient is mapped to the data set. Without
+// a mapping the recipient receives every data set.
+func (r ReassignmentRecipient) Receives(dataset ReassignmentDataset) bool {
+	if len(r.Datasets) == 0 {
+		return true
+	}
+	for _, d := range r.Datasets {
+		if d == dataset {
+			return true
+		}
+	}
+	return false
+}
+
+// Total returns the number of records the recipient received.
+func (r ReassignmentRecipient) Total() int64 {
+	var total int64
+	for _, n := range r.Assigned {
+		total += n
+	}
+	return total
+}
+
+// ReassignmentProgress is the outcome of reassigning one data set.
+type ReassignmentProgress struct {
+	Dataset     ReassignmentDataset `json:"dataset"`
+	Transferred int64               `json:"transferred"`
+	Failed      int64               `json:"failed"`
+	CompletedAt *time.Time          `json:"completed_at,omitempty"`
+}
+
+// IsDone returns true once the data set has been reassigned.
+func (p ReassignmentProgress) IsDone() bool {
+	return p.CompletedAt != nil
+}
+
+// UserReassignment transfers the open records a user owns in the other
+// services to other users of the tenant, typically when the user leaves.
+// The data sets are reassigned one by one in the background, and each
+// recipient is notified once it completes.
+type UserReassignment struct {
+	BaseAggregateRoot
+	tenantID    uuid.UUID
+	userID      uuid.UUID
+	userName    string
+	requestedBy uuid.UUID
+	strategy    ReassignmentStrategy
+	recipients  []ReassignmentRecipient
+	datasets    []ReassignmentProgress
+	status      UserReassignmentStatus
+	errorMsg    string
+	startedAt   *time.Time
+	completedAt *time.Time
+}
+
+// NewUserReassignment creates a pending reassignment of a user's records.
+// With the mapping strategy every recipient lists the data sets they
+// receive, and a data set goes to one recipient at most.
+func NewUserReassignment(
+	tenantID, userID uuid.UUID,
+	userName string,
+	requestedBy uuid.UUID,
+	strategy ReassignmentStrategy,
+	recipients []ReassignmentRecipient,
+) (*UserReassignment, error) {
+	if tenantID == uuid.Nil || userID == uuid.Nil {
+		return nil, ErrUserReassignmentUserRequired
+	}
+	if !strategy.IsValid() {
+		return nil, fmt.Errorf("%w: %q", ErrUserReassignmentInvalidStrategy, strategy)
+	}
+	if len(recipients) == 0 {
+		return nil, ErrUserReassignmentNoRecipients
+	}
+
+	seen := make(map[uuid.UUID]bool, len(recipients))
+	mapped := make(map[ReassignmentDataset]bool)
+	for i := range recipients {
+		recipient := &recipients[i]
+		if recipient.UserID == uuid.Nil || recipient.UserID == userID {
+			return nil, ErrUserReassignmentInvalidRecipient
+		}
+		if seen[recipient.UserID] {
+			return nil, fmt.Errorf("%w: %s is listed twice", ErrUserReassignmentInvalidRecipient, recipient.UserID)
+		}
+		seen[recipient.UserID] = true
+		recipient.Assigned = nil
+
+		if strategy == ReassignmentStrategyRoundRobin {
+			recipient.Datasets = nil
+			continue
+		}
+		if len(recipient.Datasets) == 0 {
+			return nil, fmt.Errorf("%w: %s is mapped to no data set", ErrUserReassignmentInvalidRecipient, recipient.UserID)
+		}
+		for _, dataset := range recipient.Datasets {
+			if dataset.Service() == "" {
+				return nil, fmt.Errorf("%w: %q", ErrUserReassignmentUnknownDataset, dataset)
+			}
+			if mapped[dataset] {
+				return nil, fmt.Errorf("%w: %s are mapped to more than one recipient", ErrUserReassignmentInvalidRecipient, dataset)
+			}
+			mapped[dataset] = true
+		}
+	}
+
+	var datasets []ReassignmentProgress
+	for _, dataset := range ReassignmentDatasets() {
+		if strategy == ReassignmentStrategyRoundRobin || mapped[dataset] {
+			datasets = append(datasets, ReassignmentProgress{Dataset: dataset})
+		}
+	}
+
+	return &UserReassignment{
+		BaseAggregateRoot: NewBaseAggregateRoot(),
+		tenantID:          tenantID,
+		userID:            userID,
+		userName:          userName,
+		requestedBy:       requestedBy,
+		strategy:          strategy,
+		recipients:        recipients,
+		datasets:          datasets,
+		status:            UserReassignmentStatusPending,
+	}, nil
+}
+
+// ReconstructUserReassignment reconstructs a UserReassignment from
+// persistence.
+func ReconstructUserReassignment(
+	id, tenantID, userID uuid.UUID,
+	userName string,
+	requestedBy uuid.UUID,
+	strategy ReassignmentStrategy,
+	recipients []ReassignmentRecipient,
+	datasets []ReassignmentProgress,
+	status UserReassignmentStatus,
+	errorMsg string,
+	startedAt, completedAt *time.Time,
+	createdAt, updatedAt time.Time,
+) *UserReassignment {
+	return &UserReassignment{
+		BaseAggregateRoot: BaseAggregateRoot{
+			BaseEntity: BaseEntity{
+				ID:        id,
+				CreatedAt: createdAt,
+				UpdatedAt: updatedAt,
+			},
+		},
+		tenantID:    tenantID,
+		userID:      userID,
+		userName:    userName,
+		requestedBy: requestedBy,
+		strategy:    strategy,
+		recipients:  recipients,
+		datasets:    datasets,
+		status:      status,
+		errorMsg:    errorMsg,
+		startedAt:   startedAt,
+		completedAt: completedAt,
+	}
+}
+
+// Getters
+
+// TenantID returns the ID of the tenant.
+func (r *UserReassignment) TenantID() uuid.UUID {
+	return r.tenantID
+}
+
+// UserID returns the ID of the user whose records are reassigned.
+func (r *UserReassignment) UserID() uuid.UUID {
+	return r.userID
+}
+
+// UserName returns the name of the user whose records are reassigned.
+func (r *UserReassignment) UserName() string {
+	return r.userName
+}
+
+// RequestedBy returns the ID of the user who requested the reassignment.
+func (r *UserReassignment) RequestedBy() uuid.UUID {
+	return r.requestedBy
+}
+
+// Strategy returns how the records are shared among the recipients.
+func (r *UserReassignment) Strategy() ReassignmentStrategy {
+	return r.strategy
+}
+
+// Recipients returns the users receiving the records.
+func (r *UserReassignment) Recipients() []ReassignmentRecipient {
+	return r.recipients
+}
+
+// Datasets returns the progress of each data set reassigned.
+func (r *UserReassignment) Datasets() []ReassignmentProgress {
+	return r.datasets
+}
+
+// Status returns the reassignment status.
+func (r *UserReassignment) Status() UserReassignmentStatus {
+	return r.status
+}
+
+// Error returns why the reassignment failed.
+func (r *UserReassignment) Error() string {
+	return r.errorMsg
+}
+
+// StartedAt returns when the reassignment started.
+func (r *UserReassignment) StartedAt() *time.Time {
+	return r.startedAt
+}
+
+// CompletedAt returns when the reassignment completed.
+func (r *UserReassignment) CompletedAt() *time.Time {
+	return r.completedAt
+}
+
+// Progress returns the percentage of data sets reassigned.
+func (r *UserReassignment) Progress() int {
+	if len(r.datasets) == 0 {
+		return 0
+	}
+	done := 0
+	for _, dataset := range r.datasets {
+		if dataset.IsDone() {
+			done++
+		}
+	}
+	return done * 100 / len(r.datasets)
+}
+
+// Transferred returns the number of records reassigned so far.
+func (r *UserReassignment) Transferred() int64 {
+	var total int64
+	for _, dataset := range r.datasets {
+		total += dataset.Transferred
+	}
+	return total
+}
+
+// Behaviors
+
+// Start marks the reassignment as running.
+func (r *UserReassignment) Start() error {
+	if r.status != UserReassignmentStatusPending {
+		return fmt.Errorf("%w: cannot start a %s reassignment", ErrUserReassignmentInvalidState, r.status)
+	}
+	now := time.Now().UTC()
+	r.status = UserReassignmentStatusRunning
+	r.startedAt = &now
+	r.MarkUpdated()
+	return nil
+}
+
+// RecipientsFor returns the recipients of a data set, in the order its
+// records are handed out. With the round robin strategy the order carries on
+// from where the previous data set stopped, so the records are shared evenly
+// overall.
+func (r *UserReassignment) RecipientsFor(dataset ReassignmentDataset) []ReassignmentRecipient {
+	var recipients []ReassignmentRecipient
+	for _, recipient := range r.recipients {
+		if recipient.Receives(dataset) {
+			recipients = append(recipients, recipient)
+		}
+	}
+	if len(recipients) < 2 {
+		return recipients
+	}
+
+	start := int(r.Transferred() % int64(len(recipients)))
+	rotated := make([]ReassignmentRecipient, 0, len(recipients))
+	rotated = append(rotated, recipients[start:]...)
+	return append(rotated, recipients[:start]...)
+}
+
+// CompleteDataset records that a data set has been reassigned, with the
+// number of records each recipient received and those that could not be
+// reassigned.
+func (r *UserReassignment) CompleteDataset(dataset ReassignmentDataset, assigned map[uuid.UUID]int64, failed int64) error {
+	if r.status != UserReassignmentStatusRunning {
+		return fmt.Errorf("%w: cannot reassign data sets of a %s reassignment", ErrUserReassignmentInvalidState, r.status)
+	}
+	for i := range r.datasets {
+		if r.datasets[i].Dataset != dataset {
+			continue
+		}
+
+		var transferred int64
+		for j := range r.recipients {
+			n := assigned[r.recipients[j].UserID]
+			if n == 0 {
+				continue
+			}
+			if r.recipients[j].Assigned == nil {
+				r.recipients[j].Assigned = make(map[ReassignmentDataset]int64)
+			}
+			r.recipients[j].Assigned[dataset] = n
+			transferred += n
+		}
+
+		now := time.Now().UTC()
+		r.datasets[i].Transferred = transferred
+		r.datasets[i].Failed = failed
+		r.datasets[i].CompletedAt = &now
+		r.MarkUpdated()
+		return nil
+	}
+	return fmt.Errorf("%w: %q", ErrUserReassignmentUnknownDataset, dataset)
+}
+
+// Complete marks the reassignment as completed once every data set is
+// reassigned, raising an event for each recipient that received records.
+func (r *UserReassignment) Complete() error {
+	if r.status != UserReassignmentStatusRunning {
+		return fmt.Errorf("%w: cannot complete a %s reassignment", ErrUserReassignmentInvalidState, r.status)
+	}
+	if r.Progress() < 100 {
+		return fmt.Errorf("%w: data sets are still being reassigned", ErrUserReassignmentInvalidState)
+	}
+	now := time.Now().UTC()
+	r.status = UserReassignmentStatusCompleted
+	r.completedAt = &now
+	r.MarkUpdated()
+
+	for _, recipient := range r.recipients {
+		if recipient.Total() > 0 {
+			r.AddDomainEvent(NewUserRecordsReassignedEvent(r, recipient))
+		}
+	}
+	return nil
+}
+
+// Fail marks the reassignment as failed. The records already reassigned keep
+// their new owners.
+func (r *UserReassignment) Fail(reason string) {
+	now := time.Now().UTC()
+	r.status = UserReassignmentStatusFailed
+	r.errorMsg = reason
+	r.completedAt = &now
+	r.MarkUpdated()
+}
+
+// User reassignment errors
+var (
+	ErrUserReassignmentNotFound         = fmt.Errorf("user reassignment not found")
+	ErrUserReassignmentUserRequired     = fmt.Errorf("user reassignment requires a tenant and a user")
+	ErrUserReassignmentInvalidStrategy  = fmt.Errorf("invalid user reassignment strategy")
+	ErrUserReassignmentNoRecipients     = fmt.Errorf("user reassignment requires at least one recipient")
+	ErrUserReassignmentInvalidRecipient = fmt.Errorf("invalid user reassignment recipient")
+	ErrUserReassignmentInvalidState     = fmt.Errorf("invalid user reassignment state")
+	ErrUserReassignmentUnknownDataset   = fmt.Errorf("unknown user reassignment data set")
+)
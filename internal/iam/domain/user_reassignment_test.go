@@ -0,0 +1,132 @@
+// Package domain contains the domain layer for the IAM service.
+package domain
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestNewUserReassignment(t *testing.T) {
+	tenantID, userID := uuid.New(), uuid.New()
+	first, second := uuid.New(), uuid.New()
+
+	reassignment, err := NewUserReassignment(tenantID, userID, "Leaver", uuid.New(), ReassignmentStrategyRoundRobin, []ReassignmentRecipient{
+		{UserID: first, Name: "Aminah", Datasets: []ReassignmentDataset{ReassignmentDatasetLeads}},
+		{UserID: second, Name: "Badrul"},
+	})
+	if err != nil {
+		t.Fatalf("NewUserReassignment() error = %v", err)
+	}
+	if reassignment.Status() != UserReassignmentStatusPending {
+		t.Errorf("Status() = %v, want %v", reassignment.Status(), UserReassignmentStatusPending)
+	}
+	if len(reassignment.Datasets()) != len(ReassignmentDatasets()) {
+		t.Errorf("expected every data set with round robin, got %d", len(reassignment.Datasets()))
+	}
+	if len(reassignment.Recipients()[0].Datasets) != 0 {
+		t.Error("expected round robin to ignore the recipients' data sets")
+	}
+
+	mapped, err := NewUserReassignment(tenantID, userID, "Leaver", uuid.New(), ReassignmentStrategyMapping, []ReassignmentRecipient{
+		{UserID: first, Datasets: []ReassignmentDataset{ReassignmentDatasetCustomers}},
+		{UserID: second, Datasets: []ReassignmentDataset{ReassignmentDatasetLeads}},
+	})
+	if err != nil {
+		t.Fatalf("NewUserReassignment() with mapping error = %v", err)
+	}
+	if got := mapped.Datasets(); len(got) != 2 || got[0].Dataset != ReassignmentDatasetLeads || got[1].Dataset != ReassignmentDatasetCustomers {
+		t.Errorf("expected only the mapped data sets in order, got %v", got)
+	}
+
+	tests := []struct {
+		name       string
+		strategy   ReassignmentStrategy
+		recipients []ReassignmentRecipient
+		want       error
+	}{
+		{"no recipients", ReassignmentStrategyRoundRobin, nil, ErrUserReassignmentNoRecipients},
+		{"unknown strategy", "lottery", []ReassignmentRecipient{{UserID: first}}, ErrUserReassignmentInvalidStrategy},
+		{"recipient is the user", ReassignmentStrategyRoundRobin, []ReassignmentRecipient{{UserID: userID}}, ErrUserReassignmentInvalidRecipient},
+		{"recipient listed twice", ReassignmentStrategyRoundRobin, []ReassignmentRecipient{{UserID: first}, {UserID: first}}, ErrUserReassignmentInvalidRecipient},
+		{"unmapped recipient", ReassignmentStrategyMapping, []ReassignmentRecipient{{UserID: first}}, ErrUserReassignmentInvalidRecipient},
+		{"data set mapped twice", ReassignmentStrategyMapping, []ReassignmentRecipient{
+			{UserID: first, Datasets: []ReassignmentDataset{ReassignmentDatasetLeads}},
+			{UserID: second, Datasets: []ReassignmentDataset{ReassignmentDatasetLeads}},
+		}, ErrUserReassignmentInvalidRecipient},
+		{"unknown data set", ReassignmentStrategyMapping, []ReassignmentRecipient{
+			{UserID: first, Datasets: []ReassignmentDataset{"tasks"}},
+		}, ErrUserReassignmentUnknownDataset},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := NewUserReassignment(tenantID, userID, "Leaver", uuid.New(), tt.strategy, tt.recipients); !errors.Is(err, tt.want) {
+				t.Errorf("NewUserReassignment() error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestUserReassignment_Lifecycle(t *testing.T) {
+	first, second := uuid.New(), uuid.New()
+	reassignment, _ := NewUserReassignment(uuid.New(), uuid.New(), "Leaver", uuid.New(), ReassignmentStrategyRoundRobin, []ReassignmentRecipient{
+		{UserID: first, Name: "Aminah"},
+		{UserID: second, Name: "Badrul"},
+	})
+
+	if err := reassignment.CompleteDataset(ReassignmentDatasetLeads, nil, 0); !errors.Is(err, ErrUserReassignmentInvalidState) {
+		t.Errorf("CompleteDataset() before Start() error = %v, want %v", err, ErrUserReassignmentInvalidState)
+	}
+	if err := reassignment.Start(); err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+
+	// Three leads leave the second recipient next in turn
+	if err := reassignment.CompleteDataset(ReassignmentDatasetLeads, map[uuid.UUID]int64{first: 2, second: 1}, 0); err != nil {
+		t.Fatalf("CompleteDataset() error = %v", err)
+	}
+	if next := reassignment.RecipientsFor(ReassignmentDatasetOpportunities); next[0].UserID != second {
+		t.Errorf("expected the round robin to carry on with the second recipient, got %s", next[0].UserID)
+	}
+	if err := reassignment.Complete(); !errors.Is(err, ErrUserReassignmentInvalidState) {
+		t.Errorf("Complete() with data sets left error = %v, want %v", err, ErrUserReassignmentInvalidState)
+	}
+
+	if err := reassignment.CompleteDataset(ReassignmentDatasetOpportunities, map[uuid.UUID]int64{second: 1}, 1); err != nil {
+		t.Fatalf("CompleteDataset() error = %v", err)
+	}
+	if err := reassignment.CompleteDataset(ReassignmentDatasetCustomers, nil, 0); err != nil {
+		t.Fatalf("CompleteDataset() error = %v", err)
+	}
+	if err := reassignment.Complete(); err != nil {
+		t.Fatalf("Complete() error = %v", err)
+	}
+
+	if reassignment.Status() != UserReassignmentStatusCompleted || reassignment.Progress() != 100 || reassignment.Transferred() != 4 {
+		t.Errorf("expected a completed reassignment of 4 records, got %v at %d%% with %d", reassignment.Status(), reassignment.Progress(), reassignment.Transferred())
+	}
+
+	events := reassignment.GetDomainEvents()
+	if len(events) != 2 {
+		t.Fatalf("expected an event per recipient, got %d", len(events))
+	}
+	event := events[1].(*UserRecordsReassignedEvent)
+	if event.RecipientID != second || event.Leads != 1 || event.Opportunities != 1 || event.Total != 2 {
+		t.Errorf("unexpected event for the second recipient: %+v", event)
+	}
+}
+
+func TestUserReassignment_Fail(t *testing.T) {
+	reassignment, _ := NewUserReassignment(uuid.New(), uuid.New(), "Leaver", uuid.New(), ReassignmentStrategyRoundRobin, []ReassignmentRecipient{{UserID: uuid.New()}})
+	_ = reassignment.Start()
+
+	reassignment.Fail("customer service unavailable")
+
+	if reassignment.Status() != UserReassignmentStatusFailed || reassignment.Error() != "customer service unavailable" {
+		t.Errorf("expected a failed reassignment, got %v (%q)", reassignment.Status(), reassignment.Error())
+	}
+	if reassignment.Status().IsActive() || len(reassignment.GetDomainEvents()) != 0 {
+		t.Error("expected a failed reassignment to be inactive and notify nobody")
+	}
+}
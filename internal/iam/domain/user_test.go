@@ -150,7 +150,7 @@ func TestReconstructUser(t *testing.T) {
 	user := ReconstructUser(
 		id, tenantID, email, password,
 		"John", "Doe", "https://avatar.com/john.png", "+1234567890",
-		UserStatusActive, &now, &now,
+		UserStatusActive, nil, &now, &now,
 		map[string]interface{}{"key": "value"},
 		now, now, nil,
 	)
@@ -0,0 +1,123 @@
+// Package audit provides audit logging infrastructure.
+package audit
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/google/uuid"
+
+	"github.com/kilang-desa-murni/crm/internal/iam/application/ports"
+	"github.com/kilang-desa-murni/crm/internal/iam/application/usecase"
+	"github.com/kilang-desa-murni/crm/internal/iam/domain"
+)
+
+// SecurityEventLogger wraps another audit logger and records a security
+// event for each audited sign-in, password change, role change and
+// impersonation, so every use case auditing them feeds the security event
+// stream.
+type SecurityEventLogger struct {
+	inner  ports.AuditLogger
+	record *usecase.RecordSecurityEventUseCase
+	logger *slog.Logger
+}
+
+// NewSecurityEventLogger creates a new security event logger.
+func NewSecurityEventLogger(inner ports.AuditLogger, record *usecase.RecordSecurityEventUseCase, logger *slog.Logger) *SecurityEventLogger {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return &SecurityEventLogger{
+		inner:  inner,
+		record: record,
+		logger: logger,
+	}
+}
+
+// Log forwards the audit event to the inner logger and records its security
+// event. Failing to record the security event does not fail the action.
+func (l *SecurityEventLogger) Log(ctx context.Context, entry ports.AuditEntry) error {
+	var err error
+	if l.inner != nil {
+		err = l.inner.Log(ctx, entry)
+	}
+
+	event, ok := securityEventFromAudit(entry)
+	if !ok {
+		return err
+	}
+	if recordErr := l.record.Execute(ctx, event); recordErr != nil {
+		l.logger.Error("failed to record security event",
+			slog.String("type", event.Type().String()),
+			slog.String("user_id", event.UserID().String()),
+			slog.Any("error", recordErr),
+		)
+	}
+
+	return err
+}
+
+// securityEventFromAudit returns the security event of an audit entry, if
+// it has one. Failed sign-ins are only recorded once the user is known.
+func securityEventFromAudit(entry ports.AuditEntry) (*domain.SecurityEvent, bool) {
+	source := domain.SecuritySource{
+		IPAddress: entry.IPAddress,
+		UserAgent: entry.UserAgent,
+		DeviceID:  stringValue(entry.NewValues, "device_id"),
+		Country:   stringValue(entry.NewValues, "country"),
+	}
+
+	var (
+		eventType domain.SecurityEventType
+		userID    *uuid.UUID
+		details   map[string]interface{}
+	)
+	switch entry.Action {
+	case ports.AuditActionLogin:
+		eventType, userID = domain.SecurityEventLoginSucceeded, entry.UserID
+	case ports.AuditActionLoginFailed:
+		eventType, userID = domain.SecurityEventLoginFailed, entry.UserID
+		details = map[string]interface{}{"reason": stringValue(entry.NewValues, "reason")}
+	case ports.AuditActionPasswordChanged:
+		eventType, userID = domain.SecurityEventPasswordChanged, entry.EntityID
+	case ports.AuditActionRoleAssigned, ports.AuditActionRoleRemoved:
+		eventType, userID = domain.SecurityEventPermissionsChanged, entry.EntityID
+		role := entry.NewValues
+		if entry.Action == ports.AuditActionRoleRemoved {
+			role = entry.OldValues
+		}
+		details = map[string]interface{}{
+			"change":    entry.Action,
+			"role_name": stringValue(role, "role_name"),
+		}
+		if roleID, ok := role["role_id"]; ok {
+			details["role_id"] = roleID
+		}
+	case ports.AuditActionImpersonationStarted:
+		eventType, userID = domain.SecurityEventImpersonationStarted, entry.EntityID
+	default:
+		return nil, false
+	}
+	if userID == nil {
+		return nil, false
+	}
+
+	// Someone else acting on the user, such as an administrator, is the actor
+	if entry.UserID != nil && *entry.UserID != *userID {
+		source.ActorID = entry.UserID
+	}
+
+	event, err := domain.NewSecurityEvent(entry.TenantID, *userID, eventType, source, details)
+	if err != nil {
+		return nil, false
+	}
+	return event, true
+}
+
+// stringValue returns the string stored under key, or "".
+func stringValue(values map[string]interface{}, key string) string {
+	if s, ok := values[key].(string); ok {
+		return s
+	}
+	return ""
+}
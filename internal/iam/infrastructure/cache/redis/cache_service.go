@@ -8,6 +8,8 @@ import (
 	"time"
 
 	"github.com/redis/go-redis/v9"
+
+	"github.com/kilang-desa-murni/crm/pkg/cache"
 )
 
 // CacheService implements the application ports.CacheService interface using Redis.
@@ -15,6 +17,7 @@ type CacheService struct {
 	client     *redis.Client
 	prefix     string
 	defaultTTL time.Duration
+	loads      cache.Group[[]byte]
 }
 
 // CacheConfig holds configuration for the cache service.
@@ -58,6 +61,41 @@ func (s *CacheService) Get(ctx context.Context, key string, dest interface{}) er
 	return nil
 }
 
+// GetOrLoad retrieves a value from the cache, calling load on a miss and caching
+// its result with the given TTL. Concurrent misses for the same key share a
+// single load, so a hot key such as a user profile hits the database once.
+func (s *CacheService) GetOrLoad(ctx context.Context, key string, dest interface{}, ttl time.Duration, load func(ctx context.Context) (interface{}, error)) error {
+	err := s.Get(ctx, key, dest)
+	if err != ErrCacheMiss {
+		return err
+	}
+
+	data, _, err := s.loads.Do(ctx, key, func(ctx context.Context) ([]byte, error) {
+		value, err := load(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		data, err := json.Marshal(value)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal cache value: %w", err)
+		}
+
+		// The value is returned even if caching it fails
+		_ = s.client.Set(ctx, s.prefix+key, data, ttl).Err()
+		return data, nil
+	})
+	if err != nil {
+		return err
+	}
+
+	if err := json.Unmarshal(data, dest); err != nil {
+		return fmt.Errorf("failed to unmarshal cache value: %w", err)
+	}
+
+	return nil
+}
+
 // Set stores a value in the cache with the default TTL.
 func (s *CacheService) Set(ctx context.Context, key string, value interface{}) error {
 	return s.SetWithTTL(ctx, key, value, s.defaultTTL)
@@ -82,6 +120,7 @@ func (s *CacheService) SetWithTTL(ctx context.Context, key string, value interfa
 // Delete removes a value from the cache.
 func (s *CacheService) Delete(ctx context.Context, key string) error {
 	fullKey := s.prefix + key
+	s.loads.Forget(key)
 
 	if err := s.client.Del(ctx, fullKey).Err(); err != nil {
 		return fmt.Errorf("failed to delete from cache: %w", err)
@@ -0,0 +1,136 @@
+// Package demo seeds and purges tenant demo data in the services that own
+// it.
+package demo
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/kilang-desa-murni/crm/internal/iam/application/ports"
+)
+
+// HTTPSeederConfig holds configuration for the HTTP seeder.
+type HTTPSeederConfig struct {
+	// ServiceURLs maps each service to its base URL, e.g. "sales" to
+	// "http://sales-service:8083".
+	ServiceURLs map[string]string
+	// Transports maps services to the round tripper sending their requests,
+	// such as one authenticating them with a service token. Services without
+	// one use http.DefaultTransport.
+	Transports map[string]http.RoundTripper
+	// Timeout bounds one service's seeding or purge.
+	Timeout time.Duration
+}
+
+// DefaultHTTPSeederConfig returns the default seeder configuration.
+func DefaultHTTPSeederConfig() HTTPSeederConfig {
+	return HTTPSeederConfig{
+		ServiceURLs: map[string]string{
+			"customer":     "http://localhost:8082",
+			"sales":        "http://localhost:8083",
+			"notification": "http://localhost:8084",
+		},
+		Timeout: time.Minute,
+	}
+}
+
+// HTTPSeeder implements ports.DemoDataSeeder by calling the internal demo
+// data endpoint of each service:
+//
+//	POST   {service}/internal/tenants/{tenantID}/demo-data?seeded_by={userID}
+//	DELETE {service}/internal/tenants/{tenantID}/demo-data
+//
+// The API gateway does not route /internal paths, so they are only reachable
+// inside the cluster.
+type HTTPSeeder struct {
+	config  HTTPSeederConfig
+	clients map[string]*http.Client
+}
+
+// NewHTTPSeeder creates a new HTTPSeeder.
+func NewHTTPSeeder(config HTTPSeederConfig) *HTTPSeeder {
+	defaults := DefaultHTTPSeederConfig()
+	if config.ServiceURLs == nil {
+		config.ServiceURLs = defaults.ServiceURLs
+	}
+	if config.Timeout <= 0 {
+		config.Timeout = defaults.Timeout
+	}
+
+	clients := make(map[string]*http.Client, len(config.ServiceURLs))
+	for service := range config.ServiceURLs {
+		clients[service] = &http.Client{Timeout: config.Timeout, Transport: config.Transports[service]}
+	}
+
+	return &HTTPSeeder{
+		config:  config,
+		clients: clients,
+	}
+}
+
+// Seed creates the service's demo records of the tenant that do not exist
+// yet.
+func (s *HTTPSeeder) Seed(ctx context.Context, service string, tenantID, seededBy uuid.UUID) (ports.DemoDataResult, error) {
+	query := url.Values{"seeded_by": {seededBy.String()}}
+	return s.call(ctx, http.MethodPost, service, tenantID, query)
+}
+
+// Purge deletes the service's demo records of the tenant.
+func (s *HTTPSeeder) Purge(ctx context.Context, service string, tenantID uuid.UUID) (ports.DemoDataResult, error) {
+	return s.call(ctx, http.MethodDelete, service, tenantID, nil)
+}
+
+// call calls the demo data endpoint of the service and decodes its result.
+func (s *HTTPSeeder) call(ctx context.Context, method, service string, tenantID uuid.UUID, query url.Values) (ports.DemoDataResult, error) {
+	baseURL, ok := s.config.ServiceURLs[service]
+	if !ok {
+		return ports.DemoDataResult{}, fmt.Errorf("no URL configured for the %s service", service)
+	}
+
+	endpoint := fmt.Sprintf("%s/internal/tenants/%s/demo-data", strings.TrimSuffix(baseURL, "/"), tenantID)
+	if len(query) > 0 {
+		endpoint += "?" + query.Encode()
+	}
+	req, err := http.NewRequestWithContext(ctx, method, endpoint, nil)
+	if err != nil {
+		return ports.DemoDataResult{}, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := s.clients[service].Do(req)
+	if err != nil {
+		return ports.DemoDataResult{}, fmt.Errorf("failed to reach the %s service: %w", service, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return ports.DemoDataResult{}, fmt.Errorf("%s service responded with %d: %s", service, resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+
+	var envelope struct {
+		Data struct {
+			Created  int64 `json:"created"`
+			Existing int64 `json:"existing"`
+			Deleted  int64 `json:"deleted"`
+		} `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&envelope); err != nil {
+		return ports.DemoDataResult{}, fmt.Errorf("failed to decode the %s service's response: %w", service, err)
+	}
+
+	return ports.DemoDataResult{
+		Created:  envelope.Data.Created,
+		Existing: envelope.Data.Existing,
+		Deleted:  envelope.Data.Deleted,
+	}, nil
+}
@@ -0,0 +1,124 @@
+// Package export reads tenant data for tenant exports from the services that
+// own it.
+package export
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/kilang-desa-murni/crm/internal/iam/domain"
+)
+
+// HTTPSourceConfig holds configuration for the HTTP data source.
+type HTTPSourceConfig struct {
+	// ServiceURLs maps each service to its base URL, e.g. "sales" to
+	// "http://sales-service:8083".
+	ServiceURLs map[string]string
+	// Transports maps services to the round tripper sending their requests,
+	// such as one authenticating them with a service token. Services without
+	// one use http.DefaultTransport.
+	Transports map[string]http.RoundTripper
+	// Timeout bounds the export of one data set.
+	Timeout time.Duration
+}
+
+// DefaultHTTPSourceConfig returns the default source configuration.
+func DefaultHTTPSourceConfig() HTTPSourceConfig {
+	return HTTPSourceConfig{
+		ServiceURLs: map[string]string{
+			"customer":     "http://localhost:8082",
+			"sales":        "http://localhost:8083",
+			"notification": "http://localhost:8084",
+		},
+		Timeout: 30 * time.Minute,
+	}
+}
+
+// HTTPSource implements ports.TenantDataSource by streaming each data set
+// from the internal export endpoint of the service that owns it:
+//
+//	GET {service}/internal/tenants/{tenantID}/export/{dataset}
+//
+// The endpoint answers with one JSON record per line. The API gateway does
+// not route /internal paths, so it is only reachable inside the cluster.
+type HTTPSource struct {
+	config  HTTPSourceConfig
+	clients map[string]*http.Client
+}
+
+// NewHTTPSource creates a new HTTPSource.
+func NewHTTPSource(config HTTPSourceConfig) *HTTPSource {
+	defaults := DefaultHTTPSourceConfig()
+	if config.ServiceURLs == nil {
+		config.ServiceURLs = defaults.ServiceURLs
+	}
+	if config.Timeout <= 0 {
+		config.Timeout = defaults.Timeout
+	}
+
+	clients := make(map[string]*http.Client, len(config.ServiceURLs))
+	for service := range config.ServiceURLs {
+		clients[service] = &http.Client{Timeout: config.Timeout, Transport: config.Transports[service]}
+	}
+
+	return &HTTPSource{
+		config:  config,
+		clients: clients,
+	}
+}
+
+// ExportDataset copies the records of a data set of the tenant to w and
+// returns the number of records copied.
+func (s *HTTPSource) ExportDataset(ctx context.Context, tenantID uuid.UUID, dataset domain.TenantExportDataset, w io.Writer) (int64, error) {
+	baseURL, ok := s.config.ServiceURLs[dataset.Service()]
+	if !ok {
+		return 0, fmt.Errorf("%w: %q", domain.ErrTenantExportUnknownDataset, dataset)
+	}
+
+	url := fmt.Sprintf("%s/internal/tenants/%s/export/%s", strings.TrimSuffix(baseURL, "/"), tenantID, dataset)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return 0, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Accept", "application/x-ndjson")
+
+	resp, err := s.clients[dataset.Service()].Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("failed to reach the %s service: %w", dataset.Service(), err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return 0, fmt.Errorf("%s service responded with %d: %s", dataset.Service(), resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+
+	// Copy line by line to count the records; a record may be larger than
+	// the scanner's default buffer
+	scanner := bufio.NewScanner(resp.Body)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+
+	var rows int64
+	for scanner.Scan() {
+		line := scanner.Bytes()
+		if len(line) == 0 {
+			continue
+		}
+		if _, err := w.Write(append(line, '\n')); err != nil {
+			return rows, err
+		}
+		rows++
+	}
+	if err := scanner.Err(); err != nil {
+		return rows, fmt.Errorf("failed to read %s: %w", dataset, err)
+	}
+
+	return rows, nil
+}
@@ -0,0 +1,197 @@
+// Package postgres contains PostgreSQL repository implementations.
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+
+	"github.com/kilang-desa-murni/crm/internal/iam/domain"
+)
+
+// SecurityEventRow represents a security event database row.
+type SecurityEventRow struct {
+	ID             uuid.UUID       `db:"id"`
+	TenantID       uuid.UUID       `db:"tenant_id"`
+	UserID         uuid.UUID       `db:"user_id"`
+	Type           string          `db:"type"`
+	IPAddress      sql.NullString  `db:"ip_address"`
+	UserAgent      sql.NullString  `db:"user_agent"`
+	DeviceID       sql.NullString  `db:"device_id"`
+	Country        sql.NullString  `db:"country"`
+	ActorID        *uuid.UUID      `db:"actor_id"`
+	Details        json.RawMessage `db:"details"`
+	Suspicious     json.RawMessage `db:"suspicious"`
+	ReauthRequired bool            `db:"reauth_required"`
+	CreatedAt      time.Time       `db:"created_at"`
+}
+
+// ToEntity converts a SecurityEventRow to a SecurityEvent domain entity.
+func (r *SecurityEventRow) ToEntity() *domain.SecurityEvent {
+	var details map[string]interface{}
+	if len(r.Details) > 0 {
+		_ = json.Unmarshal(r.Details, &details)
+	}
+	var suspicious []domain.SuspiciousActivity
+	if len(r.Suspicious) > 0 {
+		_ = json.Unmarshal(r.Suspicious, &suspicious)
+	}
+
+	return domain.ReconstructSecurityEvent(
+		r.ID,
+		r.TenantID,
+		r.UserID,
+		domain.SecurityEventType(r.Type),
+		domain.SecuritySource{
+			IPAddress: r.IPAddress.String,
+			UserAgent: r.UserAgent.String,
+			DeviceID:  r.DeviceID.String,
+			Country:   r.Country.String,
+			ActorID:   r.ActorID,
+		},
+		details,
+		suspicious,
+		r.ReauthRequired,
+		r.CreatedAt,
+	)
+}
+
+const securityEventColumns = `id, tenant_id, user_id, type, ip_address, user_agent, device_id, country, actor_id, details, suspicious, reauth_required, created_at`
+
+// SecurityEventRepository implements domain.SecurityEventRepository using PostgreSQL.
+type SecurityEventRepository struct {
+	db *sqlx.DB
+}
+
+// NewSecurityEventRepository creates a new SecurityEventRepository.
+func NewSecurityEventRepository(db *sqlx.DB) *SecurityEventRepository {
+	return &SecurityEventRepository{db: db}
+}
+
+// Create creates a new security event.
+func (r *SecurityEventRepository) Create(ctx context.Context, event *domain.SecurityEvent) error {
+	details := event.Details()
+	if details == nil {
+		details = map[string]interface{}{}
+	}
+	detailsJSON, err := json.Marshal(details)
+	if err != nil {
+		return fmt.Errorf("failed to encode security event details: %w", err)
+	}
+	suspicious := event.Suspicious()
+	if suspicious == nil {
+		suspicious = []domain.SuspiciousActivity{}
+	}
+	suspiciousJSON, err := json.Marshal(suspicious)
+	if err != nil {
+		return fmt.Errorf("failed to encode security event flags: %w", err)
+	}
+
+	source := event.Source()
+	query := `
+		INSERT INTO security_events (` + securityEventColumns + `)
+		VALUES ($1, $2, $3, $4, NULLIF($5, ''), NULLIF($6, ''), NULLIF($7, ''), NULLIF($8, ''), $9, $10, $11, $12, $13)`
+
+	_, err = r.getDB(ctx).ExecContext(ctx, query,
+		event.GetID(),
+		event.TenantID(),
+		event.UserID(),
+		event.Type().String(),
+		source.IPAddress,
+		source.UserAgent,
+		source.DeviceID,
+		source.Country,
+		source.ActorID,
+		detailsJSON,
+		suspiciousJSON,
+		event.ReauthRequired(),
+		event.CreatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create security event: %w", err)
+	}
+
+	return nil
+}
+
+// FindByUser finds the security events of a user with pagination, newest first.
+func (r *SecurityEventRepository) FindByUser(ctx context.Context, tenantID, userID uuid.UUID, opts domain.SecurityEventQueryOptions) ([]*domain.SecurityEvent, int64, error) {
+	where := []string{"tenant_id = $1", "user_id = $2"}
+	args := []interface{}{tenantID, userID}
+	argIndex := 3
+
+	if opts.Type != "" {
+		where = append(where, fmt.Sprintf("type = $%d", argIndex))
+		args = append(args, opts.Type.String())
+		argIndex++
+	}
+
+	if opts.SuspiciousOnly {
+		where = append(where, "suspicious <> '[]'::jsonb")
+	}
+
+	whereClause := strings.Join(where, " AND ")
+
+	var total int64
+	countQuery := "SELECT COUNT(*) FROM security_events WHERE " + whereClause
+	if err := sqlx.GetContext(ctx, r.getDB(ctx), &total, countQuery, args...); err != nil {
+		return nil, 0, fmt.Errorf("failed to count security events: %w", err)
+	}
+
+	page := opts.Page
+	if page < 1 {
+		page = 1
+	}
+	pageSize := opts.PageSize
+	if pageSize < 1 {
+		pageSize = 50
+	}
+
+	query := fmt.Sprintf(`SELECT %s FROM security_events WHERE %s ORDER BY created_at DESC LIMIT $%d OFFSET $%d`,
+		securityEventColumns, whereClause, argIndex, argIndex+1)
+	args = append(args, pageSize, (page-1)*pageSize)
+
+	var rows []SecurityEventRow
+	if err := sqlx.SelectContext(ctx, r.getDB(ctx), &rows, query, args...); err != nil {
+		return nil, 0, fmt.Errorf("failed to find security events: %w", err)
+	}
+
+	events := make([]*domain.SecurityEvent, len(rows))
+	for i := range rows {
+		events[i] = rows[i].ToEntity()
+	}
+
+	return events, total, nil
+}
+
+// FindRecentByUser finds at most limit security events of a user recorded
+// since the given time, newest first.
+func (r *SecurityEventRepository) FindRecentByUser(ctx context.Context, userID uuid.UUID, since time.Time, limit int) ([]*domain.SecurityEvent, error) {
+	query := `SELECT ` + securityEventColumns + ` FROM security_events WHERE user_id = $1 AND created_at >= $2 ORDER BY created_at DESC LIMIT $3`
+
+	var rows []SecurityEventRow
+	if err := sqlx.SelectContext(ctx, r.getDB(ctx), &rows, query, userID, since, limit); err != nil {
+		return nil, fmt.Errorf("failed to find recent security events: %w", err)
+	}
+
+	events := make([]*domain.SecurityEvent, len(rows))
+	for i := range rows {
+		events[i] = rows[i].ToEntity()
+	}
+
+	return events, nil
+}
+
+// getDB returns the transaction from context or the database connection.
+func (r *SecurityEventRepository) getDB(ctx context.Context) sqlx.ExtContext {
+	if tx := getTxFromContext(ctx); tx != nil {
+		return tx
+	}
+	return r.db
+}
@@ -0,0 +1,234 @@
+// Package postgres contains PostgreSQL repository implementations.
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+
+	"github.com/kilang-desa-murni/crm/internal/iam/domain"
+)
+
+// TenantExportRow represents a tenant export database row.
+type TenantExportRow struct {
+	ID          uuid.UUID       `db:"id"`
+	TenantID    uuid.UUID       `db:"tenant_id"`
+	RequestedBy uuid.UUID       `db:"requested_by"`
+	Status      string          `db:"status"`
+	Files       json.RawMessage `db:"files"`
+	Error       sql.NullString  `db:"error"`
+	StartedAt   *time.Time      `db:"started_at"`
+	CompletedAt *time.Time      `db:"completed_at"`
+	ExpiresAt   *time.Time      `db:"expires_at"`
+	CreatedAt   time.Time       `db:"created_at"`
+	UpdatedAt   time.Time       `db:"updated_at"`
+}
+
+// ToEntity converts a TenantExportRow to a TenantExport domain entity.
+func (r *TenantExportRow) ToEntity() *domain.TenantExport {
+	var files []domain.TenantExportFile
+	if len(r.Files) > 0 {
+		_ = json.Unmarshal(r.Files, &files)
+	}
+
+	return domain.ReconstructTenantExport(
+		r.ID,
+		r.TenantID,
+		r.RequestedBy,
+		domain.TenantExportStatus(r.Status),
+		files,
+		r.Error.String,
+		r.StartedAt,
+		r.CompletedAt,
+		r.ExpiresAt,
+		r.CreatedAt,
+		r.UpdatedAt,
+	)
+}
+
+const tenantExportColumns = `id, tenant_id, requested_by, status, files, error, started_at, completed_at, expires_at, created_at, updated_at`
+
+// TenantExportRepository implements domain.TenantExportRepository using PostgreSQL.
+type TenantExportRepository struct {
+	db *sqlx.DB
+}
+
+// NewTenantExportRepository creates a new TenantExportRepository.
+func NewTenantExportRepository(db *sqlx.DB) *TenantExportRepository {
+	return &TenantExportRepository{db: db}
+}
+
+// Create creates a new tenant export.
+func (r *TenantExportRepository) Create(ctx context.Context, export *domain.TenantExport) error {
+	files, err := json.Marshal(export.Files())
+	if err != nil {
+		return fmt.Errorf("failed to encode tenant export files: %w", err)
+	}
+
+	query := `
+		INSERT INTO tenant_exports (id, tenant_id, requested_by, status, files, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)`
+
+	_, err = r.getDB(ctx).ExecContext(ctx, query,
+		export.GetID(),
+		export.TenantID(),
+		export.RequestedBy(),
+		export.Status().String(),
+		files,
+		export.CreatedAt,
+		export.UpdatedAt,
+	)
+	if err != nil {
+		if isUniqueViolation(err) {
+			return fmt.Errorf("an export of tenant %s is already in progress", export.TenantID())
+		}
+		return fmt.Errorf("failed to create tenant export: %w", err)
+	}
+
+	return nil
+}
+
+// Update updates an existing tenant export.
+func (r *TenantExportRepository) Update(ctx context.Context, export *domain.TenantExport) error {
+	files, err := json.Marshal(export.Files())
+	if err != nil {
+		return fmt.Errorf("failed to encode tenant export files: %w", err)
+	}
+
+	query := `
+		UPDATE tenant_exports
+		SET status = $2, files = $3, error = NULLIF($4, ''), started_at = $5, completed_at = $6, expires_at = $7
+		WHERE id = $1`
+
+	result, err := r.getDB(ctx).ExecContext(ctx, query,
+		export.GetID(),
+		export.Status().String(),
+		files,
+		export.Error(),
+		export.StartedAt(),
+		export.CompletedAt(),
+		export.ExpiresAt(),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update tenant export: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get affected rows: %w", err)
+	}
+	if rows == 0 {
+		return domain.ErrTenantExportNotFound
+	}
+
+	return nil
+}
+
+// FindByID finds an export of a tenant by ID.
+func (r *TenantExportRepository) FindByID(ctx context.Context, tenantID, id uuid.UUID) (*domain.TenantExport, error) {
+	query := `SELECT ` + tenantExportColumns + ` FROM tenant_exports WHERE id = $1 AND tenant_id = $2`
+
+	var row TenantExportRow
+	if err := sqlx.GetContext(ctx, r.getDB(ctx), &row, query, id, tenantID); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, domain.ErrTenantExportNotFound
+		}
+		return nil, fmt.Errorf("failed to find tenant export: %w", err)
+	}
+
+	return row.ToEntity(), nil
+}
+
+// FindByTenant finds the latest exports of a tenant, newest first.
+func (r *TenantExportRepository) FindByTenant(ctx context.Context, tenantID uuid.UUID, limit int) ([]*domain.TenantExport, error) {
+	query := `SELECT ` + tenantExportColumns + ` FROM tenant_exports WHERE tenant_id = $1 ORDER BY created_at DESC LIMIT $2`
+
+	var rows []TenantExportRow
+	if err := sqlx.SelectContext(ctx, r.getDB(ctx), &rows, query, tenantID, limit); err != nil {
+		return nil, fmt.Errorf("failed to find tenant exports: %w", err)
+	}
+
+	return tenantExportRowsToEntities(rows), nil
+}
+
+// HasActive checks if the tenant has a pending or running export.
+func (r *TenantExportRepository) HasActive(ctx context.Context, tenantID uuid.UUID) (bool, error) {
+	query := `SELECT EXISTS(SELECT 1 FROM tenant_exports WHERE tenant_id = $1 AND status IN ('pending', 'running'))`
+
+	var exists bool
+	if err := sqlx.GetContext(ctx, r.getDB(ctx), &exists, query, tenantID); err != nil {
+		return false, fmt.Errorf("failed to check tenant exports: %w", err)
+	}
+
+	return exists, nil
+}
+
+// staleExportAfter is how long a running export may go without progress
+// before it is taken to be abandoned by a crashed instance and claimed again.
+const staleExportAfter = 2 * time.Hour
+
+// ClaimPending starts the oldest pending export and returns it, or nil if
+// there is none. Exports locked by another instance are skipped, and running
+// exports without progress for staleExportAfter are claimed again to carry on
+// with the data sets still missing.
+func (r *TenantExportRepository) ClaimPending(ctx context.Context) (*domain.TenantExport, error) {
+	query := `
+		UPDATE tenant_exports
+		SET status = 'running', started_at = COALESCE(started_at, NOW()), updated_at = NOW()
+		WHERE id = (
+			SELECT id FROM tenant_exports
+			WHERE status = 'pending'
+				OR (status = 'running' AND updated_at < NOW() - make_interval(secs => $1))
+			ORDER BY created_at
+			LIMIT 1
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING ` + tenantExportColumns
+
+	var row TenantExportRow
+	if err := sqlx.GetContext(ctx, r.getDB(ctx), &row, query, staleExportAfter.Seconds()); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to claim tenant export: %w", err)
+	}
+
+	return row.ToEntity(), nil
+}
+
+// FindExpired finds completed exports whose files have expired.
+func (r *TenantExportRepository) FindExpired(ctx context.Context, now time.Time, limit int) ([]*domain.TenantExport, error) {
+	query := `SELECT ` + tenantExportColumns + ` FROM tenant_exports
+		WHERE status = 'completed' AND expires_at <= $1
+		ORDER BY expires_at
+		LIMIT $2`
+
+	var rows []TenantExportRow
+	if err := sqlx.SelectContext(ctx, r.getDB(ctx), &rows, query, now, limit); err != nil {
+		return nil, fmt.Errorf("failed to find expired tenant exports: %w", err)
+	}
+
+	return tenantExportRowsToEntities(rows), nil
+}
+
+// tenantExportRowsToEntities converts rows to domain entities.
+func tenantExportRowsToEntities(rows []TenantExportRow) []*domain.TenantExport {
+	exports := make([]*domain.TenantExport, len(rows))
+	for i := range rows {
+		exports[i] = rows[i].ToEntity()
+	}
+	return exports
+}
+
+// getDB returns the transaction from context or the database connection.
+func (r *TenantExportRepository) getDB(ctx context.Context) sqlx.ExtContext {
+	if tx := getTxFromContext(ctx); tx != nil {
+		return tx
+	}
+	return r.db
+}
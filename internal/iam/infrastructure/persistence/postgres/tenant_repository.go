@@ -216,6 +216,25 @@ func (r *TenantRepository) FindBySlug(ctx context.Context, slug string) (*domain
 	return row.ToEntity(), nil
 }
 
+// FindByCustomDomain finds a tenant by its custom domain.
+func (r *TenantRepository) FindByCustomDomain(ctx context.Context, host string) (*domain.Tenant, error) {
+	query := `
+		SELECT id, name, slug, status, plan, settings, metadata, created_at, updated_at, deleted_at
+		FROM tenants
+		WHERE LOWER(settings->>'custom_domain') = LOWER($1) AND deleted_at IS NULL`
+
+	var row TenantRow
+	err := r.getDB(ctx).GetContext(ctx, &row, query, host)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, domain.ErrTenantNotFound
+		}
+		return nil, fmt.Errorf("failed to find tenant by custom domain: %w", err)
+	}
+
+	return row.ToEntity(), nil
+}
+
 // FindAll finds all tenants with pagination.
 func (r *TenantRepository) FindAll(ctx context.Context, opts domain.TenantQueryOptions) ([]*domain.Tenant, int64, error) {
 	// Build WHERE clause
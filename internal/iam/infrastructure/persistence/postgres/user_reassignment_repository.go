@@ -0,0 +1,227 @@
+// Package postgres contains PostgreSQL repository implementations.
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+
+	"github.com/kilang-desa-murni/crm/internal/iam/domain"
+)
+
+// UserReassignmentRow represents a user reassignment database row.
+type UserReassignmentRow struct {
+	ID          uuid.UUID       `db:"id"`
+	TenantID    uuid.UUID       `db:"tenant_id"`
+	UserID      uuid.UUID       `db:"user_id"`
+	UserName    string          `db:"user_name"`
+	RequestedBy uuid.UUID       `db:"requested_by"`
+	Strategy    string          `db:"strategy"`
+	Recipients  json.RawMessage `db:"recipients"`
+	Datasets    json.RawMessage `db:"datasets"`
+	Status      string          `db:"status"`
+	Error       sql.NullString  `db:"error"`
+	StartedAt   *time.Time      `db:"started_at"`
+	CompletedAt *time.Time      `db:"completed_at"`
+	CreatedAt   time.Time       `db:"created_at"`
+	UpdatedAt   time.Time       `db:"updated_at"`
+}
+
+// ToEntity converts a UserReassignmentRow to a UserReassignment domain entity.
+func (r *UserReassignmentRow) ToEntity() *domain.UserReassignment {
+	var recipients []domain.ReassignmentRecipient
+	if len(r.Recipients) > 0 {
+		_ = json.Unmarshal(r.Recipients, &recipients)
+	}
+	var datasets []domain.ReassignmentProgress
+	if len(r.Datasets) > 0 {
+		_ = json.Unmarshal(r.Datasets, &datasets)
+	}
+
+	return domain.ReconstructUserReassignment(
+		r.ID,
+		r.TenantID,
+		r.UserID,
+		r.UserName,
+		r.RequestedBy,
+		domain.ReassignmentStrategy(r.Strategy),
+		recipients,
+		datasets,
+		domain.UserReassignmentStatus(r.Status),
+		r.Error.String,
+		r.StartedAt,
+		r.CompletedAt,
+		r.CreatedAt,
+		r.UpdatedAt,
+	)
+}
+
+const userReassignmentColumns = `id, tenant_id, user_id, user_name, requested_by, strategy, recipients, datasets, status, error, started_at, completed_at, created_at, updated_at`
+
+// UserReassignmentRepository implements domain.UserReassignmentRepository using PostgreSQL.
+type UserReassignmentRepository struct {
+	db *sqlx.DB
+}
+
+// NewUserReassignmentRepository creates a new UserReassignmentRepository.
+func NewUserReassignmentRepository(db *sqlx.DB) *UserReassignmentRepository {
+	return &UserReassignmentRepository{db: db}
+}
+
+// Create creates a new user reassignment.
+func (r *UserReassignmentRepository) Create(ctx context.Context, reassignment *domain.UserReassignment) error {
+	recipients, datasets, err := encodeUserReassignment(reassignment)
+	if err != nil {
+		return err
+	}
+
+	query := `
+		INSERT INTO user_reassignments (id, tenant_id, user_id, user_name, requested_by, strategy, recipients, datasets, status, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)`
+
+	_, err = r.getDB(ctx).ExecContext(ctx, query,
+		reassignment.GetID(),
+		reassignment.TenantID(),
+		reassignment.UserID(),
+		reassignment.UserName(),
+		reassignment.RequestedBy(),
+		reassignment.Strategy().String(),
+		recipients,
+		datasets,
+		reassignment.Status().String(),
+		reassignment.CreatedAt,
+		reassignment.UpdatedAt,
+	)
+	if err != nil {
+		if isUniqueViolation(err) {
+			return fmt.Errorf("a reassignment of user %s is already in progress", reassignment.UserID())
+		}
+		return fmt.Errorf("failed to create user reassignment: %w", err)
+	}
+
+	return nil
+}
+
+// Update updates an existing user reassignment.
+func (r *UserReassignmentRepository) Update(ctx context.Context, reassignment *domain.UserReassignment) error {
+	recipients, datasets, err := encodeUserReassignment(reassignment)
+	if err != nil {
+		return err
+	}
+
+	query := `
+		UPDATE user_reassignments
+		SET recipients = $2, datasets = $3, status = $4, error = NULLIF($5, ''), started_at = $6, completed_at = $7
+		WHERE id = $1`
+
+	result, err := r.getDB(ctx).ExecContext(ctx, query,
+		reassignment.GetID(),
+		recipients,
+		datasets,
+		reassignment.Status().String(),
+		reassignment.Error(),
+		reassignment.StartedAt(),
+		reassignment.CompletedAt(),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update user reassignment: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get affected rows: %w", err)
+	}
+	if rows == 0 {
+		return domain.ErrUserReassignmentNotFound
+	}
+
+	return nil
+}
+
+// FindByID finds a reassignment of a tenant by ID.
+func (r *UserReassignmentRepository) FindByID(ctx context.Context, tenantID, id uuid.UUID) (*domain.UserReassignment, error) {
+	query := `SELECT ` + userReassignmentColumns + ` FROM user_reassignments WHERE id = $1 AND tenant_id = $2`
+
+	var row UserReassignmentRow
+	if err := sqlx.GetContext(ctx, r.getDB(ctx), &row, query, id, tenantID); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, domain.ErrUserReassignmentNotFound
+		}
+		return nil, fmt.Errorf("failed to find user reassignment: %w", err)
+	}
+
+	return row.ToEntity(), nil
+}
+
+// HasActive checks if the user has a pending or running reassignment.
+func (r *UserReassignmentRepository) HasActive(ctx context.Context, tenantID, userID uuid.UUID) (bool, error) {
+	query := `SELECT EXISTS(SELECT 1 FROM user_reassignments WHERE tenant_id = $1 AND user_id = $2 AND status IN ('pending', 'running'))`
+
+	var exists bool
+	if err := sqlx.GetContext(ctx, r.getDB(ctx), &exists, query, tenantID, userID); err != nil {
+		return false, fmt.Errorf("failed to check user reassignments: %w", err)
+	}
+
+	return exists, nil
+}
+
+// staleReassignmentAfter is how long a running reassignment may go without
+// progress before it is taken to be abandoned by a crashed instance and
+// claimed again.
+const staleReassignmentAfter = 30 * time.Minute
+
+// ClaimPending starts the oldest pending reassignment and returns it, or nil
+// if there is none. Reassignments locked by another instance are skipped, and
+// running reassignments without progress for staleReassignmentAfter are
+// claimed again to carry on with the data sets still left.
+func (r *UserReassignmentRepository) ClaimPending(ctx context.Context) (*domain.UserReassignment, error) {
+	query := `
+		UPDATE user_reassignments
+		SET status = 'running', started_at = COALESCE(started_at, NOW()), updated_at = NOW()
+		WHERE id = (
+			SELECT id FROM user_reassignments
+			WHERE status = 'pending'
+				OR (status = 'running' AND updated_at < NOW() - make_interval(secs => $1))
+			ORDER BY created_at
+			LIMIT 1
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING ` + userReassignmentColumns
+
+	var row UserReassignmentRow
+	if err := sqlx.GetContext(ctx, r.getDB(ctx), &row, query, staleReassignmentAfter.Seconds()); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to claim user reassignment: %w", err)
+	}
+
+	return row.ToEntity(), nil
+}
+
+// encodeUserReassignment encodes the recipients and data sets of a
+// reassignment for their JSON columns.
+func encodeUserReassignment(reassignment *domain.UserReassignment) ([]byte, []byte, error) {
+	recipients, err := json.Marshal(reassignment.Recipients())
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to encode user reassignment recipients: %w", err)
+	}
+	datasets, err := json.Marshal(reassignment.Datasets())
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to encode user reassignment data sets: %w", err)
+	}
+	return recipients, datasets, nil
+}
+
+// getDB returns the transaction from context or the database connection.
+func (r *UserReassignmentRepository) getDB(ctx context.Context) sqlx.ExtContext {
+	if tx := getTxFromContext(ctx); tx != nil {
+		return tx
+	}
+	return r.db
+}
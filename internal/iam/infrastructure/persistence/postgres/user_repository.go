@@ -26,6 +26,7 @@ type UserRow struct {
 	AvatarURL       sql.NullString  `db:"avatar_url"`
 	Phone           sql.NullString  `db:"phone"`
 	Status          string          `db:"status"`
+	ManagerID       *uuid.UUID      `db:"manager_id"`
 	EmailVerifiedAt *time.Time      `db:"email_verified_at"`
 	LastLoginAt     *time.Time      `db:"last_login_at"`
 	Metadata        json.RawMessage `db:"metadata"`
@@ -54,6 +55,7 @@ func (r *UserRow) ToEntity() *domain.User {
 		r.AvatarURL.String,
 		r.Phone.String,
 		domain.UserStatus(r.Status),
+		r.ManagerID,
 		r.EmailVerifiedAt,
 		r.LastLoginAt,
 		metadata,
@@ -83,10 +85,10 @@ func (r *UserRepository) Create(ctx context.Context, user *domain.User) error {
 	query := `
 		INSERT INTO users (
 			id, tenant_id, email, password_hash, first_name, last_name,
-			avatar_url, phone, status, email_verified_at, last_login_at,
+			avatar_url, phone, status, manager_id, email_verified_at, last_login_at,
 			metadata, created_at, updated_at
 		) VALUES (
-			$1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14
+			$1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15
 		)`
 
 	_, err = r.getDB(ctx).ExecContext(ctx, query,
@@ -99,6 +101,7 @@ func (r *UserRepository) Create(ctx context.Context, user *domain.User) error {
 		nullString(user.AvatarURL()),
 		nullString(user.Phone()),
 		user.Status().String(),
+		user.ManagerID(),
 		user.EmailVerifiedAt(),
 		user.LastLoginAt(),
 		metadata,
@@ -132,11 +135,12 @@ func (r *UserRepository) Update(ctx context.Context, user *domain.User) error {
 			avatar_url = $5,
 			phone = $6,
 			status = $7,
-			email_verified_at = $8,
-			last_login_at = $9,
-			metadata = $10,
-			updated_at = $11
-		WHERE id = $12 AND deleted_at IS NULL`
+			manager_id = $8,
+			email_verified_at = $9,
+			last_login_at = $10,
+			metadata = $11,
+			updated_at = $12
+		WHERE id = $13 AND deleted_at IS NULL`
 
 	result, err := r.getDB(ctx).ExecContext(ctx, query,
 		user.Email().String(),
@@ -146,6 +150,7 @@ func (r *UserRepository) Update(ctx context.Context, user *domain.User) error {
 		nullString(user.AvatarURL()),
 		nullString(user.Phone()),
 		user.Status().String(),
+		user.ManagerID(),
 		user.EmailVerifiedAt(),
 		user.LastLoginAt(),
 		metadata,
@@ -201,7 +206,7 @@ func (r *UserRepository) Delete(ctx context.Context, id uuid.UUID) error {
 func (r *UserRepository) FindByID(ctx context.Context, id uuid.UUID) (*domain.User, error) {
 	query := `
 		SELECT id, tenant_id, email, password_hash, first_name, last_name,
-			avatar_url, phone, status, email_verified_at, last_login_at,
+			avatar_url, phone, status, manager_id, email_verified_at, last_login_at,
 			metadata, created_at, updated_at, deleted_at
 		FROM users
 		WHERE id = $1 AND deleted_at IS NULL`
@@ -222,7 +227,7 @@ func (r *UserRepository) FindByID(ctx context.Context, id uuid.UUID) (*domain.Us
 func (r *UserRepository) FindByEmail(ctx context.Context, tenantID uuid.UUID, email domain.Email) (*domain.User, error) {
 	query := `
 		SELECT id, tenant_id, email, password_hash, first_name, last_name,
-			avatar_url, phone, status, email_verified_at, last_login_at,
+			avatar_url, phone, status, manager_id, email_verified_at, last_login_at,
 			metadata, created_at, updated_at, deleted_at
 		FROM users
 		WHERE tenant_id = $1 AND LOWER(email) = LOWER($2) AND deleted_at IS NULL`
@@ -258,6 +263,16 @@ func (r *UserRepository) FindByTenant(ctx context.Context, tenantID uuid.UUID, o
 		argIndex++
 	}
 
+	if len(opts.IDs) > 0 {
+		placeholders := make([]string, len(opts.IDs))
+		for i, id := range opts.IDs {
+			placeholders[i] = fmt.Sprintf("$%d", argIndex)
+			args = append(args, id)
+			argIndex++
+		}
+		where = append(where, fmt.Sprintf("id IN (%s)", strings.Join(placeholders, ", ")))
+	}
+
 	whereClause := strings.Join(where, " AND ")
 
 	// Count total
@@ -284,7 +299,7 @@ func (r *UserRepository) FindByTenant(ctx context.Context, tenantID uuid.UUID, o
 	// Query users
 	query := fmt.Sprintf(`
 		SELECT id, tenant_id, email, password_hash, first_name, last_name,
-			avatar_url, phone, status, email_verified_at, last_login_at,
+			avatar_url, phone, status, manager_id, email_verified_at, last_login_at,
 			metadata, created_at, updated_at, deleted_at
 		FROM users
 		WHERE %s
@@ -312,7 +327,7 @@ func (r *UserRepository) FindByTenant(ctx context.Context, tenantID uuid.UUID, o
 func (r *UserRepository) FindByRoleID(ctx context.Context, roleID uuid.UUID) ([]*domain.User, error) {
 	query := `
 		SELECT u.id, u.tenant_id, u.email, u.password_hash, u.first_name, u.last_name,
-			u.avatar_url, u.phone, u.status, u.email_verified_at, u.last_login_at,
+			u.avatar_url, u.phone, u.status, u.manager_id, u.email_verified_at, u.last_login_at,
 			u.metadata, u.created_at, u.updated_at, u.deleted_at
 		FROM users u
 		INNER JOIN user_roles ur ON u.id = ur.user_id
@@ -332,6 +347,30 @@ func (r *UserRepository) FindByRoleID(ctx context.Context, roleID uuid.UUID) ([]
 	return users, nil
 }
 
+// FindByManagerID finds the users reporting directly to a manager.
+func (r *UserRepository) FindByManagerID(ctx context.Context, managerID uuid.UUID) ([]*domain.User, error) {
+	query := `
+		SELECT id, tenant_id, email, password_hash, first_name, last_name,
+			avatar_url, phone, status, manager_id, email_verified_at, last_login_at,
+			metadata, created_at, updated_at, deleted_at
+		FROM users
+		WHERE manager_id = $1 AND deleted_at IS NULL
+		ORDER BY first_name, last_name, email`
+
+	var rows []UserRow
+	err := r.getDB(ctx).SelectContext(ctx, &rows, query, managerID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find users by manager: %w", err)
+	}
+
+	users := make([]*domain.User, len(rows))
+	for i, row := range rows {
+		users[i] = row.ToEntity()
+	}
+
+	return users, nil
+}
+
 // ExistsByEmail checks if a user with the email exists in the tenant.
 func (r *UserRepository) ExistsByEmail(ctx context.Context, tenantID uuid.UUID, email domain.Email) (bool, error) {
 	query := `
@@ -362,6 +401,34 @@ func (r *UserRepository) CountByTenant(ctx context.Context, tenantID uuid.UUID)
 	return count, nil
 }
 
+// FindDueForAnonymization finds up to limit offboarded users of all tenants
+// deactivated before the time and not anonymized yet.
+func (r *UserRepository) FindDueForAnonymization(ctx context.Context, deactivatedBefore time.Time, limit int) ([]*domain.User, error) {
+	query := `
+		SELECT id, tenant_id, email, password_hash, first_name, last_name,
+			avatar_url, phone, status, manager_id, email_verified_at, last_login_at,
+			metadata, created_at, updated_at, deleted_at
+		FROM users
+		WHERE status = 'inactive' AND deleted_at IS NULL
+			AND metadata->>'deactivated_at' IS NOT NULL AND metadata->>'anonymized_at' IS NULL
+			AND (metadata->>'deactivated_at')::timestamptz < $1
+		ORDER BY (metadata->>'deactivated_at')::timestamptz
+		LIMIT $2`
+
+	var rows []UserRow
+	err := r.getDB(ctx).SelectContext(ctx, &rows, query, deactivatedBefore, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find users due for anonymization: %w", err)
+	}
+
+	users := make([]*domain.User, len(rows))
+	for i, row := range rows {
+		users[i] = row.ToEntity()
+	}
+
+	return users, nil
+}
+
 // getDB returns the database connection, checking for transaction in context.
 func (r *UserRepository) getDB(ctx context.Context) sqlx.ExtContext {
 	if tx := getTxFromContext(ctx); tx != nil {
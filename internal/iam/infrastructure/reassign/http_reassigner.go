@@ -0,0 +1,139 @@
+// Package reassign transfers a user's records in the services that own them.
+package reassign
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/kilang-desa-murni/crm/internal/iam/application/ports"
+	"github.com/kilang-desa-murni/crm/internal/iam/domain"
+)
+
+// HTTPReassignerConfig holds configuration for the HTTP reassigner.
+type HTTPReassignerConfig struct {
+	// ServiceURLs maps each service to its base URL, e.g. "sales" to
+	// "http://sales-service:8083".
+	ServiceURLs map[string]string
+	// Transports maps services to the round tripper sending their requests,
+	// such as one authenticating them with a service token. Services without
+	// one use http.DefaultTransport.
+	Transports map[string]http.RoundTripper
+	// Timeout bounds the reassignment of one data set.
+	Timeout time.Duration
+}
+
+// DefaultHTTPReassignerConfig returns the default reassigner configuration.
+func DefaultHTTPReassignerConfig() HTTPReassignerConfig {
+	return HTTPReassignerConfig{
+		ServiceURLs: map[string]string{
+			"customer": "http://localhost:8082",
+			"sales":    "http://localhost:8083",
+		},
+		Timeout: 5 * time.Minute,
+	}
+}
+
+// HTTPReassigner implements ports.OwnerReassigner by calling the internal
+// reassignment endpoint of the service that owns each data set:
+//
+//	POST {service}/internal/tenants/{tenantID}/reassign/{dataset}
+//
+// The API gateway does not route /internal paths, so they are only reachable
+// inside the cluster.
+type HTTPReassigner struct {
+	config  HTTPReassignerConfig
+	clients map[string]*http.Client
+}
+
+// NewHTTPReassigner creates a new HTTPReassigner.
+func NewHTTPReassigner(config HTTPReassignerConfig) *HTTPReassigner {
+	defaults := DefaultHTTPReassignerConfig()
+	if config.ServiceURLs == nil {
+		config.ServiceURLs = defaults.ServiceURLs
+	}
+	if config.Timeout <= 0 {
+		config.Timeout = defaults.Timeout
+	}
+
+	clients := make(map[string]*http.Client, len(config.ServiceURLs))
+	for service := range config.ServiceURLs {
+		clients[service] = &http.Client{Timeout: config.Timeout, Transport: config.Transports[service]}
+	}
+
+	return &HTTPReassigner{
+		config:  config,
+		clients: clients,
+	}
+}
+
+// reassignRecipient is a recipient in the body of a reassignment request.
+type reassignRecipient struct {
+	UserID uuid.UUID `json:"user_id"`
+	Name   string    `json:"name,omitempty"`
+}
+
+// Reassign hands the user's open records of the data set to the recipients
+// in turn, starting with the first.
+func (r *HTTPReassigner) Reassign(ctx context.Context, tenantID, fromUserID uuid.UUID, dataset domain.ReassignmentDataset, recipients []domain.ReassignmentRecipient) (ports.ReassignmentResult, error) {
+	service := dataset.Service()
+	baseURL, ok := r.config.ServiceURLs[service]
+	if !ok {
+		return ports.ReassignmentResult{}, fmt.Errorf("no URL configured for the %s service", service)
+	}
+
+	body := struct {
+		FromUserID uuid.UUID           `json:"from_user_id"`
+		Recipients []reassignRecipient `json:"recipients"`
+	}{FromUserID: fromUserID}
+	for _, recipient := range recipients {
+		body.Recipients = append(body.Recipients, reassignRecipient{UserID: recipient.UserID, Name: recipient.Name})
+	}
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return ports.ReassignmentResult{}, fmt.Errorf("failed to encode request: %w", err)
+	}
+
+	endpoint := fmt.Sprintf("%s/internal/tenants/%s/reassign/%s", strings.TrimSuffix(baseURL, "/"), tenantID, dataset)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(payload))
+	if err != nil {
+		return ports.ReassignmentResult{}, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := r.clients[service].Do(req)
+	if err != nil {
+		return ports.ReassignmentResult{}, fmt.Errorf("failed to reach the %s service: %w", service, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return ports.ReassignmentResult{}, fmt.Errorf("%s service responded with %d: %s", service, resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+
+	var envelope struct {
+		Data struct {
+			Transferred int64               `json:"transferred"`
+			Failed      int64               `json:"failed"`
+			Assigned    map[uuid.UUID]int64 `json:"assigned"`
+		} `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&envelope); err != nil {
+		return ports.ReassignmentResult{}, fmt.Errorf("failed to decode the %s service's response: %w", service, err)
+	}
+
+	return ports.ReassignmentResult{
+		Transferred: envelope.Data.Transferred,
+		Failed:      envelope.Data.Failed,
+		Assigned:    envelope.Data.Assigned,
+	}, nil
+}
@@ -0,0 +1,174 @@
+// Package storage contains file storage adapters for the IAM service.
+package storage
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrInvalidSignature is returned for download links that were not signed
+// by the storage or have expired.
+var ErrInvalidSignature = errors.New("invalid or expired download link")
+
+// LocalExportStorage implements ports.ExportStorage on the local filesystem,
+// typically an object storage bucket mounted into the pod. Its signed URLs
+// point at the storage's own download handler under publicURL, which serves
+// a file to whoever holds a valid link.
+type LocalExportStorage struct {
+	baseDir   string
+	publicURL string
+	secret    []byte
+}
+
+// NewLocalExportStorage creates a new local export storage rooted at baseDir.
+// publicURL is the external URL the storage's ServeHTTP is mounted at, and
+// secret the key download links are signed with.
+func NewLocalExportStorage(baseDir, publicURL string, secret []byte) (*LocalExportStorage, error) {
+	if len(secret) < 32 {
+		return nil, errors.New("export link signing secret must be at least 32 bytes")
+	}
+	if err := os.MkdirAll(baseDir, 0o750); err != nil {
+		return nil, fmt.Errorf("failed to create storage directory: %w", err)
+	}
+	return &LocalExportStorage{
+		baseDir:   baseDir,
+		publicURL: strings.TrimSuffix(publicURL, "/"),
+		secret:    secret,
+	}, nil
+}
+
+// Put stores the content of r under key and returns its size in bytes. The
+// file only appears under key once it has been written in full.
+func (s *LocalExportStorage) Put(ctx context.Context, key string, r io.Reader) (int64, error) {
+	target, err := s.path(key)
+	if err != nil {
+		return 0, err
+	}
+	if err := os.MkdirAll(filepath.Dir(target), 0o750); err != nil {
+		return 0, fmt.Errorf("failed to create file directory: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(target), ".upload-*")
+	if err != nil {
+		return 0, fmt.Errorf("failed to create file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	size, err := io.Copy(tmp, r)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return size, fmt.Errorf("failed to write file: %w", err)
+	}
+
+	if err := os.Rename(tmp.Name(), target); err != nil {
+		return size, fmt.Errorf("failed to store file: %w", err)
+	}
+	return size, nil
+}
+
+// SignedURL returns a download link for key that is valid until expiry
+// passes.
+func (s *LocalExportStorage) SignedURL(ctx context.Context, key string, expiry time.Duration) (string, error) {
+	if _, err := s.path(key); err != nil {
+		return "", err
+	}
+	expires := time.Now().Add(expiry).Unix()
+
+	query := url.Values{}
+	query.Set("expires", strconv.FormatInt(expires, 10))
+	query.Set("signature", s.sign(key, expires))
+	return s.publicURL + "/" + key + "?" + query.Encode(), nil
+}
+
+// Delete deletes the file stored under key. Missing files are ignored.
+func (s *LocalExportStorage) Delete(ctx context.Context, key string) error {
+	target, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(target); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("failed to delete file: %w", err)
+	}
+	return nil
+}
+
+// Verify checks that a download link for key was signed by the storage and
+// has not expired.
+func (s *LocalExportStorage) Verify(key, expires, signature string) error {
+	expiresAt, err := strconv.ParseInt(expires, 10, 64)
+	if err != nil || time.Now().Unix() > expiresAt {
+		return ErrInvalidSignature
+	}
+	if !hmac.Equal([]byte(signature), []byte(s.sign(key, expiresAt))) {
+		return ErrInvalidSignature
+	}
+	return nil
+}
+
+// ServeHTTP serves the file a signed download link points at. It is mounted
+// at the storage's public URL with the prefix stripped, so the request path
+// is the file's key.
+func (s *LocalExportStorage) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	key := strings.TrimPrefix(r.URL.Path, "/")
+	query := r.URL.Query()
+	if err := s.Verify(key, query.Get("expires"), query.Get("signature")); err != nil {
+		http.Error(w, err.Error(), http.StatusForbidden)
+		return
+	}
+
+	target, err := s.path(key)
+	if err != nil {
+		http.NotFound(w, r)
+		return
+	}
+	file, err := os.Open(target)
+	if err != nil {
+		http.NotFound(w, r)
+		return
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		http.NotFound(w, r)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/x-ndjson")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", path.Base(key)))
+	http.ServeContent(w, r, path.Base(key), info.ModTime(), file)
+}
+
+// sign returns the signature of a download link for key.
+func (s *LocalExportStorage) sign(key string, expires int64) string {
+	mac := hmac.New(sha256.New, s.secret)
+	mac.Write([]byte(key))
+	mac.Write([]byte{0})
+	mac.Write([]byte(strconv.FormatInt(expires, 10)))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// path resolves key inside the base directory, rejecting keys that would
+// escape it.
+func (s *LocalExportStorage) path(key string) (string, error) {
+	clean := path.Clean("/" + key)
+	if key == "" || clean != "/"+key {
+		return "", fmt.Errorf("invalid storage key %q", key)
+	}
+	return filepath.Join(s.baseDir, filepath.FromSlash(clean)), nil
+}
@@ -0,0 +1,142 @@
+// Package worker contains background workers for the IAM service.
+package worker
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/kilang-desa-murni/crm/internal/iam/application/usecase"
+)
+
+// TenantExportWorkerConfig holds configuration for the tenant export worker.
+type TenantExportWorkerConfig struct {
+	// PollInterval is how often queued exports are looked for.
+	PollInterval time.Duration
+	// PurgeInterval is how often the files of expired exports are deleted.
+	PurgeInterval time.Duration
+	// PurgeBatchSize bounds the exports expired per purge.
+	PurgeBatchSize int
+}
+
+// DefaultTenantExportWorkerConfig returns the default worker configuration.
+func DefaultTenantExportWorkerConfig() TenantExportWorkerConfig {
+	return TenantExportWorkerConfig{
+		PollInterval:   10 * time.Second,
+		PurgeInterval:  time.Hour,
+		PurgeBatchSize: 100,
+	}
+}
+
+// TenantExportWorker produces queued tenant exports one at a time and
+// deletes the files of expired ones. Every instance may run a worker; an
+// export is claimed by one of them only.
+type TenantExportWorker struct {
+	config TenantExportWorkerConfig
+	run    *usecase.RunTenantExportUseCase
+	logger *slog.Logger
+
+	stop chan struct{}
+	once sync.Once
+	wg   sync.WaitGroup
+}
+
+// NewTenantExportWorker creates a new tenant export worker.
+func NewTenantExportWorker(config TenantExportWorkerConfig, run *usecase.RunTenantExportUseCase, logger *slog.Logger) *TenantExportWorker {
+	defaults := DefaultTenantExportWorkerConfig()
+	if config.PollInterval <= 0 {
+		config.PollInterval = defaults.PollInterval
+	}
+	if config.PurgeInterval <= 0 {
+		config.PurgeInterval = defaults.PurgeInterval
+	}
+	if config.PurgeBatchSize <= 0 {
+		config.PurgeBatchSize = defaults.PurgeBatchSize
+	}
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	return &TenantExportWorker{
+		config: config,
+		run:    run,
+		logger: logger,
+		stop:   make(chan struct{}),
+	}
+}
+
+// Start starts the worker in the background.
+func (w *TenantExportWorker) Start(ctx context.Context) {
+	ctx, cancel := context.WithCancel(ctx)
+
+	w.wg.Add(1)
+	go func() {
+		defer w.wg.Done()
+		defer cancel()
+
+		poll := time.NewTicker(w.config.PollInterval)
+		defer poll.Stop()
+		purge := time.NewTicker(w.config.PurgeInterval)
+		defer purge.Stop()
+
+		for {
+			select {
+			case <-poll.C:
+				w.drain(ctx)
+			case <-purge.C:
+				if purged, err := w.run.PurgeExpired(ctx, w.config.PurgeBatchSize); err != nil {
+					w.logger.Error("failed to purge expired tenant exports", slog.String("error", err.Error()))
+				} else if purged > 0 {
+					w.logger.Info("purged expired tenant exports", slog.Int("count", purged))
+				}
+			case <-w.stop:
+				return
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	// Abandon a running export on shutdown; it is failed and can be
+	// requested again
+	go func() {
+		select {
+		case <-w.stop:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+}
+
+// drain produces queued exports until none are left or the worker stops.
+func (w *TenantExportWorker) drain(ctx context.Context) {
+	for ctx.Err() == nil {
+		processed, err := w.run.Execute(ctx)
+		if err != nil {
+			w.logger.Error("tenant export failed", slog.String("error", err.Error()))
+		}
+		if !processed {
+			return
+		}
+	}
+}
+
+// Shutdown stops the worker and waits for it to finish, or for ctx to be
+// done.
+func (w *TenantExportWorker) Shutdown(ctx context.Context) error {
+	w.once.Do(func() { close(w.stop) })
+
+	done := make(chan struct{})
+	go func() {
+		w.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,102 @@
+// Package worker contains background workers for the IAM service.
+package worker
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/kilang-desa-murni/crm/internal/iam/application/usecase"
+)
+
+// UserAnonymizationWorkerConfig holds configuration for the user
+// anonymization worker.
+type UserAnonymizationWorkerConfig struct {
+	// Interval is how often offboarded users due for anonymization are
+	// looked for.
+	Interval time.Duration
+}
+
+// DefaultUserAnonymizationWorkerConfig returns the default worker
+// configuration.
+func DefaultUserAnonymizationWorkerConfig() UserAnonymizationWorkerConfig {
+	return UserAnonymizationWorkerConfig{
+		Interval: time.Hour,
+	}
+}
+
+// UserAnonymizationWorker anonymizes offboarded users once their retention
+// period has passed. Every instance may run a worker; anonymizing a user
+// twice changes nothing.
+type UserAnonymizationWorker struct {
+	config    UserAnonymizationWorkerConfig
+	anonymize *usecase.AnonymizeOffboardedUsersUseCase
+	logger    *slog.Logger
+
+	stop chan struct{}
+	once sync.Once
+	wg   sync.WaitGroup
+}
+
+// NewUserAnonymizationWorker creates a new user anonymization worker.
+func NewUserAnonymizationWorker(config UserAnonymizationWorkerConfig, anonymize *usecase.AnonymizeOffboardedUsersUseCase, logger *slog.Logger) *UserAnonymizationWorker {
+	if config.Interval <= 0 {
+		config.Interval = DefaultUserAnonymizationWorkerConfig().Interval
+	}
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	return &UserAnonymizationWorker{
+		config:    config,
+		anonymize: anonymize,
+		logger:    logger,
+		stop:      make(chan struct{}),
+	}
+}
+
+// Start starts the worker in the background.
+func (w *UserAnonymizationWorker) Start(ctx context.Context) {
+	w.wg.Add(1)
+	go func() {
+		defer w.wg.Done()
+
+		ticker := time.NewTicker(w.config.Interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				if anonymized, err := w.anonymize.Execute(ctx); err != nil {
+					w.logger.Error("failed to anonymize offboarded users", slog.String("error", err.Error()))
+				} else if anonymized > 0 {
+					w.logger.Info("anonymized offboarded users", slog.Int("count", anonymized))
+				}
+			case <-w.stop:
+				return
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+}
+
+// Shutdown stops the worker and waits for it to finish, or for ctx to be
+// done.
+func (w *UserAnonymizationWorker) Shutdown(ctx context.Context) error {
+	w.once.Do(func() { close(w.stop) })
+
+	done := make(chan struct{})
+	go func() {
+		w.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,124 @@
+// Package worker contains background workers for the IAM service.
+package worker
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/kilang-desa-murni/crm/internal/iam/application/usecase"
+)
+
+// UserReassignmentWorkerConfig holds configuration for the user reassignment
+// worker.
+type UserReassignmentWorkerConfig struct {
+	// PollInterval is how often queued reassignments are looked for.
+	PollInterval time.Duration
+}
+
+// DefaultUserReassignmentWorkerConfig returns the default worker
+// configuration.
+func DefaultUserReassignmentWorkerConfig() UserReassignmentWorkerConfig {
+	return UserReassignmentWorkerConfig{
+		PollInterval: 5 * time.Second,
+	}
+}
+
+// UserReassignmentWorker carries out queued user reassignments one at a
+// time. Every instance may run a worker; a reassignment is claimed by one of
+// them only.
+type UserReassignmentWorker struct {
+	config UserReassignmentWorkerConfig
+	run    *usecase.RunUserReassignmentUseCase
+	logger *slog.Logger
+
+	stop chan struct{}
+	once sync.Once
+	wg   sync.WaitGroup
+}
+
+// NewUserReassignmentWorker creates a new user reassignment worker.
+func NewUserReassignmentWorker(config UserReassignmentWorkerConfig, run *usecase.RunUserReassignmentUseCase, logger *slog.Logger) *UserReassignmentWorker {
+	if config.PollInterval <= 0 {
+		config.PollInterval = DefaultUserReassignmentWorkerConfig().PollInterval
+	}
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	return &UserReassignmentWorker{
+		config: config,
+		run:    run,
+		logger: logger,
+		stop:   make(chan struct{}),
+	}
+}
+
+// Start starts the worker in the background.
+func (w *UserReassignmentWorker) Start(ctx context.Context) {
+	ctx, cancel := context.WithCancel(ctx)
+
+	w.wg.Add(1)
+	go func() {
+		defer w.wg.Done()
+		defer cancel()
+
+		poll := time.NewTicker(w.config.PollInterval)
+		defer poll.Stop()
+
+		for {
+			select {
+			case <-poll.C:
+				w.drain(ctx)
+			case <-w.stop:
+				return
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	// Abandon a running reassignment on shutdown; it is failed and can be
+	// requested again for the records left
+	go func() {
+		select {
+		case <-w.stop:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+}
+
+// drain carries out queued reassignments until none are left or the worker
+// stops.
+func (w *UserReassignmentWorker) drain(ctx context.Context) {
+	for ctx.Err() == nil {
+		processed, err := w.run.Execute(ctx)
+		if err != nil {
+			w.logger.Error("user reassignment failed", slog.String("error", err.Error()))
+		}
+		if !processed {
+			return
+		}
+	}
+}
+
+// Shutdown stops the worker and waits for it to finish, or for ctx to be
+// done.
+func (w *UserReassignmentWorker) Shutdown(ctx context.Context) error {
+	w.once.Do(func() { close(w.stop) })
+
+	done := make(chan struct{})
+	go func() {
+		w.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -106,6 +106,7 @@ func (h *AuthHandler) Login(w http.ResponseWriter, r *http.Request) {
 		IPAddress:  getClientIP(r),
 		UserAgent:  r.UserAgent(),
 		DeviceInfo: deviceInfo,
+		Country:    r.Header.Get("X-Client-Country"),
 	})
 
 	if err != nil {
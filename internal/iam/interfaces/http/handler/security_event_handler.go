@@ -0,0 +1,48 @@
+// Package handler contains HTTP handlers for the IAM service.
+package handler
+
+import (
+	"net/http"
+
+	"github.com/kilang-desa-murni/crm/internal/iam/application/dto"
+	"github.com/kilang-desa-murni/crm/internal/iam/application/usecase"
+	iamhttp "github.com/kilang-desa-murni/crm/internal/iam/interfaces/http"
+	"github.com/kilang-desa-murni/crm/internal/iam/interfaces/http/middleware"
+)
+
+// SecurityEventHandler handles security event HTTP requests.
+type SecurityEventHandler struct {
+	listSecurityEventsUC *usecase.ListSecurityEventsUseCase
+}
+
+// NewSecurityEventHandler creates a new SecurityEventHandler.
+func NewSecurityEventHandler(listSecurityEventsUC *usecase.ListSecurityEventsUseCase) *SecurityEventHandler {
+	return &SecurityEventHandler{
+		listSecurityEventsUC: listSecurityEventsUC,
+	}
+}
+
+// ListMine handles listing the security events of the current user, such as
+// their sign-ins and changes to their password or permissions.
+func (h *SecurityEventHandler) ListMine(w http.ResponseWriter, r *http.Request) {
+	query := iamhttp.NewQueryParams(r)
+	pagination := query.GetPagination()
+
+	result, err := h.listSecurityEventsUC.Execute(
+		r.Context(),
+		middleware.GetTenantID(r.Context()),
+		middleware.GetUserID(r.Context()),
+		&dto.ListSecurityEventsRequest{
+			Page:           pagination.Page,
+			PageSize:       pagination.PageSize,
+			Type:           query.String("type", ""),
+			SuspiciousOnly: query.Bool("suspicious_only", false),
+		},
+	)
+	if err != nil {
+		handleApplicationError(w, err)
+		return
+	}
+
+	iamhttp.WriteSuccess(w, http.StatusOK, result)
+}
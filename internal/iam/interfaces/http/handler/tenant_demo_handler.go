@@ -0,0 +1,81 @@
+// Package handler contains HTTP handlers for the IAM service.
+package handler
+
+import (
+	"net/http"
+
+	"github.com/google/uuid"
+
+	"github.com/kilang-desa-murni/crm/internal/iam/application/usecase"
+	iamhttp "github.com/kilang-desa-murni/crm/internal/iam/interfaces/http"
+	"github.com/kilang-desa-murni/crm/internal/iam/interfaces/http/middleware"
+)
+
+// TenantDemoHandler handles tenant demo data HTTP requests.
+type TenantDemoHandler struct {
+	seedUC       *usecase.SeedTenantDemoDataUseCase
+	purgeUC      *usecase.PurgeTenantDemoDataUseCase
+	getPathParam func(*http.Request, string) string
+}
+
+// NewTenantDemoHandler creates a new TenantDemoHandler.
+func NewTenantDemoHandler(
+	seedUC *usecase.SeedTenantDemoDataUseCase,
+	purgeUC *usecase.PurgeTenantDemoDataUseCase,
+	getPathParam func(*http.Request, string) string,
+) *TenantDemoHandler {
+	return &TenantDemoHandler{
+		seedUC:       seedUC,
+		purgeUC:      purgeUC,
+		getPathParam: getPathParam,
+	}
+}
+
+// Seed handles seeding a tenant's demo data.
+func (h *TenantDemoHandler) Seed(w http.ResponseWriter, r *http.Request) {
+	tenantID, ok := h.tenantID(w, r)
+	if !ok {
+		return
+	}
+
+	result, err := h.seedUC.Execute(r.Context(), tenantID, middleware.GetUserID(r.Context()))
+	if err != nil {
+		handleApplicationError(w, err)
+		return
+	}
+
+	iamhttp.WriteSuccess(w, http.StatusOK, result)
+}
+
+// Purge handles purging a tenant's seeded demo data.
+func (h *TenantDemoHandler) Purge(w http.ResponseWriter, r *http.Request) {
+	tenantID, ok := h.tenantID(w, r)
+	if !ok {
+		return
+	}
+
+	result, err := h.purgeUC.Execute(r.Context(), tenantID, middleware.GetUserID(r.Context()))
+	if err != nil {
+		handleApplicationError(w, err)
+		return
+	}
+
+	iamhttp.WriteSuccess(w, http.StatusOK, result)
+}
+
+// tenantID parses the tenant ID of the request. Users may only seed the
+// tenant they belong to.
+func (h *TenantDemoHandler) tenantID(w http.ResponseWriter, r *http.Request) (uuid.UUID, bool) {
+	tenantID, err := uuid.Parse(h.getPathParam(r, "id"))
+	if err != nil {
+		iamhttp.WriteError(w, http.StatusBadRequest, iamhttp.ErrCodeBadRequest, "invalid tenant ID", nil)
+		return uuid.Nil, false
+	}
+
+	if tenantID != middleware.GetTenantID(r.Context()) {
+		iamhttp.WriteError(w, http.StatusForbidden, iamhttp.ErrCodeForbidden, "cannot seed another tenant", nil)
+		return uuid.Nil, false
+	}
+
+	return tenantID, true
+}
@@ -0,0 +1,104 @@
+// Package handler contains HTTP handlers for the IAM service.
+package handler
+
+import (
+	"net/http"
+
+	"github.com/google/uuid"
+
+	"github.com/kilang-desa-murni/crm/internal/iam/application/usecase"
+	iamhttp "github.com/kilang-desa-murni/crm/internal/iam/interfaces/http"
+	"github.com/kilang-desa-murni/crm/internal/iam/interfaces/http/middleware"
+)
+
+// TenantExportHandler handles tenant data export HTTP requests.
+type TenantExportHandler struct {
+	requestExportUC *usecase.RequestTenantExportUseCase
+	getExportUC     *usecase.GetTenantExportUseCase
+	getPathParam    func(*http.Request, string) string
+}
+
+// NewTenantExportHandler creates a new TenantExportHandler.
+func NewTenantExportHandler(
+	requestExportUC *usecase.RequestTenantExportUseCase,
+	getExportUC *usecase.GetTenantExportUseCase,
+	getPathParam func(*http.Request, string) string,
+) *TenantExportHandler {
+	return &TenantExportHandler{
+		requestExportUC: requestExportUC,
+		getExportUC:     getExportUC,
+		getPathParam:    getPathParam,
+	}
+}
+
+// Create handles requesting an export of all of a tenant's data.
+func (h *TenantExportHandler) Create(w http.ResponseWriter, r *http.Request) {
+	tenantID, ok := h.tenantID(w, r)
+	if !ok {
+		return
+	}
+
+	result, err := h.requestExportUC.Execute(r.Context(), tenantID, middleware.GetUserID(r.Context()))
+	if err != nil {
+		handleApplicationError(w, err)
+		return
+	}
+
+	iamhttp.WriteSuccess(w, http.StatusAccepted, result)
+}
+
+// List handles listing a tenant's exports.
+func (h *TenantExportHandler) List(w http.ResponseWriter, r *http.Request) {
+	tenantID, ok := h.tenantID(w, r)
+	if !ok {
+		return
+	}
+
+	result, err := h.getExportUC.List(r.Context(), tenantID)
+	if err != nil {
+		handleApplicationError(w, err)
+		return
+	}
+
+	iamhttp.WriteSuccess(w, http.StatusOK, result)
+}
+
+// Get handles getting an export's progress and, once completed, its
+// download links.
+func (h *TenantExportHandler) Get(w http.ResponseWriter, r *http.Request) {
+	tenantID, ok := h.tenantID(w, r)
+	if !ok {
+		return
+	}
+
+	exportID, err := uuid.Parse(h.getPathParam(r, "exportID"))
+	if err != nil {
+		iamhttp.WriteError(w, http.StatusBadRequest, iamhttp.ErrCodeBadRequest, "invalid export ID", nil)
+		return
+	}
+
+	result, err := h.getExportUC.Execute(r.Context(), tenantID, exportID)
+	if err != nil {
+		handleApplicationError(w, err)
+		return
+	}
+
+	iamhttp.WriteSuccess(w, http.StatusOK, result)
+}
+
+// tenantID parses the tenant ID of the request. Users may only export the
+// tenant they belong to.
+func (h *TenantExportHandler) tenantID(w http.ResponseWriter, r *http.Request) (uuid.UUID, bool) {
+	tenantID, err := uuid.Parse(h.getPathParam(r, "id"))
+	if err != nil {
+		iamhttp.WriteError(w, http.StatusBadRequest, iamhttp.ErrCodeBadRequest, "invalid tenant ID", nil)
+		return uuid.Nil, false
+	}
+
+	if tenantID != middleware.GetTenantID(r.Context()) {
+		iamhttp.WriteError(w, http.StatusForbidden, iamhttp.ErrCodeForbidden, "cannot export another tenant", nil)
+		return uuid.Nil, false
+	}
+
+	return tenantID, true
+}
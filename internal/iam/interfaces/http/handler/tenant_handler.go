@@ -6,6 +6,7 @@ import (
 
 	"github.com/google/uuid"
 
+	"github.com/kilang-desa-murni/crm/internal/iam/application/dto"
 	"github.com/kilang-desa-murni/crm/internal/iam/application/usecase"
 	"github.com/kilang-desa-murni/crm/internal/iam/domain"
 	iamhttp "github.com/kilang-desa-murni/crm/internal/iam/interfaces/http"
@@ -18,6 +19,10 @@ type TenantHandler struct {
 	getTenantUC    *usecase.GetTenantUseCase
 	listTenantsUC  *usecase.ListTenantsUseCase
 	updateTenantUC *usecase.UpdateTenantUseCase
+	settingsUC     *usecase.UpdateTenantSettingsUseCase
+	originsUC      *usecase.ListTenantOriginsUseCase
+	brandingUC     *usecase.TenantBrandingUseCase
+	domainsUC      *usecase.ListTenantDomainsUseCase
 	decoder        *iamhttp.RequestDecoder
 	getPathParam   func(*http.Request, string) string
 }
@@ -28,6 +33,10 @@ func NewTenantHandler(
 	getTenantUC *usecase.GetTenantUseCase,
 	listTenantsUC *usecase.ListTenantsUseCase,
 	updateTenantUC *usecase.UpdateTenantUseCase,
+	settingsUC *usecase.UpdateTenantSettingsUseCase,
+	originsUC *usecase.ListTenantOriginsUseCase,
+	brandingUC *usecase.TenantBrandingUseCase,
+	domainsUC *usecase.ListTenantDomainsUseCase,
 	getPathParam func(*http.Request, string) string,
 ) *TenantHandler {
 	return &TenantHandler{
@@ -35,6 +44,10 @@ func NewTenantHandler(
 		getTenantUC:    getTenantUC,
 		listTenantsUC:  listTenantsUC,
 		updateTenantUC: updateTenantUC,
+		settingsUC:     settingsUC,
+		originsUC:      originsUC,
+		brandingUC:     brandingUC,
+		domainsUC:      domainsUC,
 		decoder:        iamhttp.NewRequestDecoder(),
 		getPathParam:   getPathParam,
 	}
@@ -299,6 +312,98 @@ func (h *TenantHandler) Delete(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
+// UpdateSettings handles updating a tenant's settings, including the browser
+// origins allowed for its users and its custom domain.
+func (h *TenantHandler) UpdateSettings(w http.ResponseWriter, r *http.Request) {
+	tenantIDStr := h.getPathParam(r, "id")
+	tenantID, err := uuid.Parse(tenantIDStr)
+	if err != nil {
+		iamhttp.WriteError(w, http.StatusBadRequest, iamhttp.ErrCodeBadRequest, "invalid tenant ID", nil)
+		return
+	}
+
+	var req dto.UpdateTenantSettingsRequest
+	if err := h.decoder.Decode(r, &req); err != nil {
+		iamhttp.WriteError(w, http.StatusBadRequest, iamhttp.ErrCodeValidation, "invalid request", iamhttp.ValidationErrors(err))
+		return
+	}
+
+	result, err := h.settingsUC.Execute(r.Context(), tenantID, &req)
+	if err != nil {
+		handleApplicationError(w, err)
+		return
+	}
+
+	iamhttp.WriteSuccess(w, http.StatusOK, result.Tenant)
+}
+
+// ListOrigins handles listing the browser origins active tenants allow,
+// polled by the API gateway to build its CORS policy.
+func (h *TenantHandler) ListOrigins(w http.ResponseWriter, r *http.Request) {
+	result, err := h.originsUC.Execute(r.Context())
+	if err != nil {
+		handleApplicationError(w, err)
+		return
+	}
+
+	iamhttp.WriteSuccess(w, http.StatusOK, result)
+}
+
+// GetBranding handles getting a tenant's branding and custom domain, read
+// by the web app and by the notification and sales services.
+func (h *TenantHandler) GetBranding(w http.ResponseWriter, r *http.Request) {
+	tenantIDStr := h.getPathParam(r, "id")
+	tenantID, err := uuid.Parse(tenantIDStr)
+	if err != nil {
+		iamhttp.WriteError(w, http.StatusBadRequest, iamhttp.ErrCodeBadRequest, "invalid tenant ID", nil)
+		return
+	}
+
+	result, err := h.brandingUC.Get(r.Context(), tenantID)
+	if err != nil {
+		handleApplicationError(w, err)
+		return
+	}
+
+	iamhttp.WriteSuccess(w, http.StatusOK, result)
+}
+
+// UpdateBranding handles replacing a tenant's branding.
+func (h *TenantHandler) UpdateBranding(w http.ResponseWriter, r *http.Request) {
+	tenantIDStr := h.getPathParam(r, "id")
+	tenantID, err := uuid.Parse(tenantIDStr)
+	if err != nil {
+		iamhttp.WriteError(w, http.StatusBadRequest, iamhttp.ErrCodeBadRequest, "invalid tenant ID", nil)
+		return
+	}
+
+	var req dto.UpdateTenantBrandingRequest
+	if err := h.decoder.Decode(r, &req); err != nil {
+		iamhttp.WriteError(w, http.StatusBadRequest, iamhttp.ErrCodeValidation, "invalid request", iamhttp.ValidationErrors(err))
+		return
+	}
+
+	result, err := h.brandingUC.Update(r.Context(), tenantID, &req)
+	if err != nil {
+		handleApplicationError(w, err)
+		return
+	}
+
+	iamhttp.WriteSuccess(w, http.StatusOK, result)
+}
+
+// ListDomains handles listing the custom domains of active tenants, polled
+// by the API gateway to resolve tenants by host and obtain certificates.
+func (h *TenantHandler) ListDomains(w http.ResponseWriter, r *http.Request) {
+	result, err := h.domainsUC.Execute(r.Context())
+	if err != nil {
+		handleApplicationError(w, err)
+		return
+	}
+
+	iamhttp.WriteSuccess(w, http.StatusOK, result)
+}
+
 // GetStats handles getting tenant statistics.
 func (h *TenantHandler) GetStats(w http.ResponseWriter, r *http.Request) {
 	tenantIDStr := h.getPathParam(r, "id")
@@ -6,6 +6,7 @@ import (
 
 	"github.com/google/uuid"
 
+	"github.com/kilang-desa-murni/crm/internal/iam/application/dto"
 	"github.com/kilang-desa-murni/crm/internal/iam/application/usecase"
 	"github.com/kilang-desa-murni/crm/internal/iam/domain"
 	iamhttp "github.com/kilang-desa-murni/crm/internal/iam/interfaces/http"
@@ -19,6 +20,7 @@ type UserHandler struct {
 	updateUserUC *usecase.UpdateUserUseCase
 	deleteUserUC *usecase.DeleteUserUseCase
 	assignRoleUC *usecase.AssignRoleUseCase
+	batchGetUC   *usecase.BatchGetUsersUseCase
 	decoder      *iamhttp.RequestDecoder
 	getPathParam func(*http.Request, string) string
 }
@@ -30,6 +32,7 @@ func NewUserHandler(
 	updateUserUC *usecase.UpdateUserUseCase,
 	deleteUserUC *usecase.DeleteUserUseCase,
 	assignRoleUC *usecase.AssignRoleUseCase,
+	batchGetUC *usecase.BatchGetUsersUseCase,
 	getPathParam func(*http.Request, string) string,
 ) *UserHandler {
 	return &UserHandler{
@@ -38,6 +41,7 @@ func NewUserHandler(
 		updateUserUC: updateUserUC,
 		deleteUserUC: deleteUserUC,
 		assignRoleUC: assignRoleUC,
+		batchGetUC:   batchGetUC,
 		decoder:      iamhttp.NewRequestDecoder(),
 		getPathParam: getPathParam,
 	}
@@ -107,6 +111,39 @@ func (h *UserHandler) Get(w http.ResponseWriter, r *http.Request) {
 	iamhttp.WriteSuccess(w, http.StatusOK, result.User)
 }
 
+// BatchGetRequest represents a request for several users by ID.
+type BatchGetRequest struct {
+	IDs []uuid.UUID `json:"ids" validate:"required,min=1,max=500"`
+}
+
+// BatchGet handles getting up to 500 users by ID in one request. Users that
+// do not exist in the tenant are listed as missing rather than failing the
+// request.
+func (h *UserHandler) BatchGet(w http.ResponseWriter, r *http.Request) {
+	tenantID := middleware.GetTenantID(r.Context())
+	if tenantID == uuid.Nil {
+		iamhttp.WriteError(w, http.StatusBadRequest, iamhttp.ErrCodeBadRequest, "tenant context required", nil)
+		return
+	}
+
+	var req BatchGetRequest
+	if err := h.decoder.Decode(r, &req); err != nil {
+		iamhttp.WriteError(w, http.StatusBadRequest, iamhttp.ErrCodeValidation, "invalid request", iamhttp.ValidationErrors(err))
+		return
+	}
+
+	result, err := h.batchGetUC.Execute(r.Context(), &dto.BatchGetUsersRequest{
+		TenantID: tenantID,
+		IDs:      req.IDs,
+	})
+	if err != nil {
+		handleApplicationError(w, err)
+		return
+	}
+
+	iamhttp.WriteSuccess(w, http.StatusOK, result)
+}
+
 // UpdateUserRequest represents a user update request.
 type UpdateUserRequest struct {
 	FirstName string `json:"first_name,omitempty" validate:"omitempty,max=100"`
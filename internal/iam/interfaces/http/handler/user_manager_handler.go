@@ -0,0 +1,85 @@
+// Package handler contains HTTP handlers for the IAM service.
+package handler
+
+import (
+	"net/http"
+
+	"github.com/google/uuid"
+
+	"github.com/kilang-desa-murni/crm/internal/iam/application/dto"
+	"github.com/kilang-desa-murni/crm/internal/iam/application/usecase"
+	iamhttp "github.com/kilang-desa-murni/crm/internal/iam/interfaces/http"
+	"github.com/kilang-desa-murni/crm/internal/iam/interfaces/http/middleware"
+)
+
+// UserManagerHandler handles HTTP requests for the managers users report to.
+type UserManagerHandler struct {
+	assignManagerUC    *usecase.AssignManagerUseCase
+	getReportingLineUC *usecase.GetReportingLineUseCase
+	decoder            *iamhttp.RequestDecoder
+	getPathParam       func(*http.Request, string) string
+}
+
+// NewUserManagerHandler creates a new UserManagerHandler.
+func NewUserManagerHandler(
+	assignManagerUC *usecase.AssignManagerUseCase,
+	getReportingLineUC *usecase.GetReportingLineUseCase,
+	getPathParam func(*http.Request, string) string,
+) *UserManagerHandler {
+	return &UserManagerHandler{
+		assignManagerUC:    assignManagerUC,
+		getReportingLineUC: getReportingLineUC,
+		decoder:            iamhttp.NewRequestDecoder(),
+		getPathParam:       getPathParam,
+	}
+}
+
+// AssignManager handles setting the manager a user reports to, or removing
+// it when the request names none.
+func (h *UserManagerHandler) AssignManager(w http.ResponseWriter, r *http.Request) {
+	userID, err := uuid.Parse(h.getPathParam(r, "id"))
+	if err != nil {
+		iamhttp.WriteError(w, http.StatusBadRequest, iamhttp.ErrCodeBadRequest, "invalid user ID", nil)
+		return
+	}
+
+	var req dto.AssignManagerRequest
+	if err := h.decoder.Decode(r, &req); err != nil {
+		iamhttp.WriteError(w, http.StatusBadRequest, iamhttp.ErrCodeValidation, "invalid request", iamhttp.ValidationErrors(err))
+		return
+	}
+
+	performerID := middleware.GetUserID(r.Context())
+
+	result, err := h.assignManagerUC.Execute(
+		r.Context(),
+		userID,
+		middleware.GetTenantID(r.Context()),
+		&req,
+		&performerID,
+	)
+	if err != nil {
+		handleApplicationError(w, err)
+		return
+	}
+
+	iamhttp.WriteSuccess(w, http.StatusOK, result)
+}
+
+// ReportingLine handles retrieving the managers above a user and the users
+// reporting to them directly.
+func (h *UserManagerHandler) ReportingLine(w http.ResponseWriter, r *http.Request) {
+	userID, err := uuid.Parse(h.getPathParam(r, "id"))
+	if err != nil {
+		iamhttp.WriteError(w, http.StatusBadRequest, iamhttp.ErrCodeBadRequest, "invalid user ID", nil)
+		return
+	}
+
+	result, err := h.getReportingLineUC.Execute(r.Context(), userID, middleware.GetTenantID(r.Context()))
+	if err != nil {
+		handleApplicationError(w, err)
+		return
+	}
+
+	iamhttp.WriteSuccess(w, http.StatusOK, result)
+}
@@ -0,0 +1,62 @@
+// Package handler contains HTTP handlers for the IAM service.
+package handler
+
+import (
+	"net/http"
+
+	"github.com/google/uuid"
+
+	"github.com/kilang-desa-murni/crm/internal/iam/application/dto"
+	"github.com/kilang-desa-murni/crm/internal/iam/application/usecase"
+	iamhttp "github.com/kilang-desa-murni/crm/internal/iam/interfaces/http"
+	"github.com/kilang-desa-murni/crm/internal/iam/interfaces/http/middleware"
+)
+
+// UserOffboardingHandler handles user offboarding HTTP requests.
+type UserOffboardingHandler struct {
+	offboardUserUC *usecase.OffboardUserUseCase
+	decoder        *iamhttp.RequestDecoder
+	getPathParam   func(*http.Request, string) string
+}
+
+// NewUserOffboardingHandler creates a new UserOffboardingHandler.
+func NewUserOffboardingHandler(
+	offboardUserUC *usecase.OffboardUserUseCase,
+	getPathParam func(*http.Request, string) string,
+) *UserOffboardingHandler {
+	return &UserOffboardingHandler{
+		offboardUserUC: offboardUserUC,
+		decoder:        iamhttp.NewRequestDecoder(),
+		getPathParam:   getPathParam,
+	}
+}
+
+// Offboard handles offboarding a departing user, optionally transferring
+// their open records to other users.
+func (h *UserOffboardingHandler) Offboard(w http.ResponseWriter, r *http.Request) {
+	userID, err := uuid.Parse(h.getPathParam(r, "id"))
+	if err != nil {
+		iamhttp.WriteError(w, http.StatusBadRequest, iamhttp.ErrCodeBadRequest, "invalid user ID", nil)
+		return
+	}
+
+	var req dto.OffboardUserRequest
+	if err := h.decoder.Decode(r, &req); err != nil {
+		iamhttp.WriteError(w, http.StatusBadRequest, iamhttp.ErrCodeValidation, "invalid request", iamhttp.ValidationErrors(err))
+		return
+	}
+
+	result, err := h.offboardUserUC.Execute(
+		r.Context(),
+		middleware.GetTenantID(r.Context()),
+		userID,
+		middleware.GetUserID(r.Context()),
+		&req,
+	)
+	if err != nil {
+		handleApplicationError(w, err)
+		return
+	}
+
+	iamhttp.WriteSuccess(w, http.StatusOK, result)
+}
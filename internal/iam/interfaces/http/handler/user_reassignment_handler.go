@@ -0,0 +1,90 @@
+// Package handler contains HTTP handlers for the IAM service.
+package handler
+
+import (
+	"net/http"
+
+	"github.com/google/uuid"
+
+	"github.com/kilang-desa-murni/crm/internal/iam/application/dto"
+	"github.com/kilang-desa-murni/crm/internal/iam/application/usecase"
+	iamhttp "github.com/kilang-desa-murni/crm/internal/iam/interfaces/http"
+	"github.com/kilang-desa-murni/crm/internal/iam/interfaces/http/middleware"
+)
+
+// UserReassignmentHandler handles user record reassignment HTTP requests.
+type UserReassignmentHandler struct {
+	requestReassignmentUC *usecase.RequestUserReassignmentUseCase
+	getReassignmentUC     *usecase.GetUserReassignmentUseCase
+	decoder               *iamhttp.RequestDecoder
+	getPathParam          func(*http.Request, string) string
+}
+
+// NewUserReassignmentHandler creates a new UserReassignmentHandler.
+func NewUserReassignmentHandler(
+	requestReassignmentUC *usecase.RequestUserReassignmentUseCase,
+	getReassignmentUC *usecase.GetUserReassignmentUseCase,
+	getPathParam func(*http.Request, string) string,
+) *UserReassignmentHandler {
+	return &UserReassignmentHandler{
+		requestReassignmentUC: requestReassignmentUC,
+		getReassignmentUC:     getReassignmentUC,
+		decoder:               iamhttp.NewRequestDecoder(),
+		getPathParam:          getPathParam,
+	}
+}
+
+// Create handles requesting the transfer of a user's open records to other
+// users. The records are reassigned in the background; the response links
+// the reassignment whose progress can be followed.
+func (h *UserReassignmentHandler) Create(w http.ResponseWriter, r *http.Request) {
+	userID, err := uuid.Parse(h.getPathParam(r, "id"))
+	if err != nil {
+		iamhttp.WriteError(w, http.StatusBadRequest, iamhttp.ErrCodeBadRequest, "invalid user ID", nil)
+		return
+	}
+
+	var req dto.ReassignUserRecordsRequest
+	if err := h.decoder.Decode(r, &req); err != nil {
+		iamhttp.WriteError(w, http.StatusBadRequest, iamhttp.ErrCodeValidation, "invalid request", iamhttp.ValidationErrors(err))
+		return
+	}
+
+	result, err := h.requestReassignmentUC.Execute(
+		r.Context(),
+		middleware.GetTenantID(r.Context()),
+		userID,
+		middleware.GetUserID(r.Context()),
+		&req,
+	)
+	if err != nil {
+		handleApplicationError(w, err)
+		return
+	}
+
+	iamhttp.WriteSuccess(w, http.StatusAccepted, result)
+}
+
+// Get handles getting a reassignment's progress and the records each
+// recipient received.
+func (h *UserReassignmentHandler) Get(w http.ResponseWriter, r *http.Request) {
+	userID, err := uuid.Parse(h.getPathParam(r, "id"))
+	if err != nil {
+		iamhttp.WriteError(w, http.StatusBadRequest, iamhttp.ErrCodeBadRequest, "invalid user ID", nil)
+		return
+	}
+
+	reassignmentID, err := uuid.Parse(h.getPathParam(r, "reassignmentID"))
+	if err != nil {
+		iamhttp.WriteError(w, http.StatusBadRequest, iamhttp.ErrCodeBadRequest, "invalid reassignment ID", nil)
+		return
+	}
+
+	result, err := h.getReassignmentUC.Execute(r.Context(), middleware.GetTenantID(r.Context()), userID, reassignmentID)
+	if err != nil {
+		handleApplicationError(w, err)
+		return
+	}
+
+	iamhttp.WriteSuccess(w, http.StatusOK, result)
+}
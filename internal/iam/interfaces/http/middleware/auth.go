@@ -5,6 +5,7 @@ import (
 	"context"
 	"net/http"
 	"strings"
+	"time"
 
 	"github.com/google/uuid"
 
@@ -27,15 +28,18 @@ const (
 
 // AuthMiddleware provides authentication middleware.
 type AuthMiddleware struct {
-	tokenService   ports.TokenService
-	tokenBlacklist ports.TokenBlacklist
+	tokenService     ports.TokenService
+	tokenBlacklist   ports.TokenBlacklist
+	sessionBlacklist ports.SessionBlacklist
 }
 
-// NewAuthMiddleware creates a new auth middleware.
-func NewAuthMiddleware(tokenService ports.TokenService, tokenBlacklist ports.TokenBlacklist) *AuthMiddleware {
+// NewAuthMiddleware creates a new auth middleware. The session blacklist
+// rejects the tokens of offboarded users at once; it may be nil.
+func NewAuthMiddleware(tokenService ports.TokenService, tokenBlacklist ports.TokenBlacklist, sessionBlacklist ports.SessionBlacklist) *AuthMiddleware {
 	return &AuthMiddleware{
-		tokenService:   tokenService,
-		tokenBlacklist: tokenBlacklist,
+		tokenService:     tokenService,
+		tokenBlacklist:   tokenBlacklist,
+		sessionBlacklist: sessionBlacklist,
 	}
 }
 
@@ -69,6 +73,19 @@ func (m *AuthMiddleware) RequireAuth() func(http.Handler) http.Handler {
 				return
 			}
 
+			// Check if all of the user's sessions have been revoked
+			if m.sessionBlacklist != nil {
+				valid, err := m.sessionBlacklist.IsSessionValid(r.Context(), claims.UserID.String(), time.Unix(claims.IssuedAt, 0))
+				if err != nil {
+					iamhttp.WriteError(w, http.StatusInternalServerError, iamhttp.ErrCodeInternalServer, "failed to verify token", nil)
+					return
+				}
+				if !valid {
+					iamhttp.WriteError(w, http.StatusUnauthorized, iamhttp.ErrCodeUnauthorized, "session has been revoked", nil)
+					return
+				}
+			}
+
 			// Add claims to context
 			ctx := r.Context()
 			ctx = context.WithValue(ctx, UserIDKey, claims.UserID)
@@ -98,7 +115,7 @@ func (m *AuthMiddleware) OptionalAuth() func(http.Handler) http.Handler {
 
 			// Try to validate token
 			claims, err := m.tokenService.ValidateAccessToken(token)
-			if err != nil {
+			if err != nil || !m.isSessionValid(r.Context(), claims) {
 				// Invalid token, continue without auth
 				ctx := context.WithValue(r.Context(), IsAuthenticatedKey, false)
 				next.ServeHTTP(w, r.WithContext(ctx))
@@ -120,6 +137,16 @@ func (m *AuthMiddleware) OptionalAuth() func(http.Handler) http.Handler {
 	}
 }
 
+// isSessionValid checks that the session of the claims was not revoked.
+// A failed check counts as revoked.
+func (m *AuthMiddleware) isSessionValid(ctx context.Context, claims *ports.TokenClaims) bool {
+	if m.sessionBlacklist == nil {
+		return true
+	}
+	valid, err := m.sessionBlacklist.IsSessionValid(ctx, claims.UserID.String(), time.Unix(claims.IssuedAt, 0))
+	return err == nil && valid
+}
+
 // RequirePermission returns middleware that requires specific permissions.
 func (m *AuthMiddleware) RequirePermission(permissions ...string) func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
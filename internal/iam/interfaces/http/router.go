@@ -11,6 +11,7 @@ import (
 
 	"github.com/kilang-desa-murni/crm/internal/iam/interfaces/http/handler"
 	"github.com/kilang-desa-murni/crm/internal/iam/interfaces/http/middleware"
+	"github.com/kilang-desa-murni/crm/pkg/serviceauth"
 )
 
 // RouterConfig holds configuration for the router.
@@ -20,6 +21,8 @@ type RouterConfig struct {
 	RequestTimeout     time.Duration
 	EnableCORS         bool
 	CORSConfig         middleware.CORSConfig
+	// ServiceAuth verifies the service tokens of internal endpoint calls
+	ServiceAuth        *serviceauth.Verifier
 }
 
 // DefaultRouterConfig returns default router configuration.
@@ -39,6 +42,17 @@ type Handlers struct {
 	User   *handler.UserHandler
 	Role   *handler.RoleHandler
 	Tenant *handler.TenantHandler
+
+	TenantExport *handler.TenantExportHandler
+	TenantDemo   *handler.TenantDemoHandler
+	Reassignment *handler.UserReassignmentHandler
+	Offboarding  *handler.UserOffboardingHandler
+	Managers     *handler.UserManagerHandler
+	Security     *handler.SecurityEventHandler
+	// ExportFiles serves the files of tenant exports to signed download
+	// links, such as storage.LocalExportStorage. Leave it nil when the links
+	// point at object storage directly.
+	ExportFiles http.Handler
 }
 
 // Middlewares holds all middleware instances.
@@ -75,6 +89,16 @@ func NewRouter(config RouterConfig, handlers Handlers, middlewares Middlewares)
 	r.Get("/health", healthHandler)
 	r.Get("/ready", readyHandler)
 
+	// Internal endpoints, reachable by other services only; the API gateway
+	// does not route them
+	r.Group(func(r chi.Router) {
+		r.Use(config.ServiceAuth.Require(serviceauth.ScopeTenantsRead))
+
+		r.Get("/internal/cors-origins", handlers.Tenant.ListOrigins)
+		r.Get("/internal/tenant-domains", handlers.Tenant.ListDomains)
+		r.Get("/internal/tenants/{id}/branding", handlers.Tenant.GetBranding)
+	})
+
 	// API v1 routes
 	r.Route("/api/v1", func(r chi.Router) {
 		// Auth routes (public)
@@ -109,15 +133,40 @@ func NewRouter(config RouterConfig, handlers Handlers, middlewares Middlewares)
 				r.Delete("/{id}", handlers.Tenant.Delete)
 				r.Put("/{id}/status", handlers.Tenant.UpdateStatus)
 				r.Put("/{id}/plan", handlers.Tenant.UpdatePlan)
+				r.Put("/{id}/settings", handlers.Tenant.UpdateSettings)
+				r.Get("/{id}/branding", handlers.Tenant.GetBranding)
+				r.Put("/{id}/branding", handlers.Tenant.UpdateBranding)
 				r.Get("/{id}/stats", handlers.Tenant.GetStats)
+
+				// Full data exports of the caller's own tenant
+				r.Group(func(r chi.Router) {
+					r.Use(middlewares.Auth.RequirePermission("tenants:export"))
+					r.Post("/{id}/exports", handlers.TenantExport.Create)
+					r.Get("/{id}/exports", handlers.TenantExport.List)
+					r.Get("/{id}/exports/{exportID}", handlers.TenantExport.Get)
+				})
+
+				// Demo data of the caller's own tenant
+				r.Group(func(r chi.Router) {
+					r.Use(middlewares.Auth.RequirePermission("tenants:seed"))
+					r.Post("/{id}/seed-demo", handlers.TenantDemo.Seed)
+					r.Delete("/{id}/seed-demo", handlers.TenantDemo.Purge)
+				})
 			})
 		})
 
+		// Export downloads are authorized by the signature of their link
+		if handlers.ExportFiles != nil {
+			r.Handle("/export-files/*", http.StripPrefix("/api/v1/export-files", handlers.ExportFiles))
+		}
+
 		// User routes (require tenant context)
 		r.Route("/users", func(r chi.Router) {
 			r.Use(middlewares.Auth.RequireAuth())
 
 			r.Get("/", handlers.User.List)
+			r.Post("/batch-get", handlers.User.BatchGet)
+			r.Get("/me/security-events", handlers.Security.ListMine)
 			r.Get("/{id}", handlers.User.Get)
 			r.Put("/{id}", handlers.User.Update)
 			r.Delete("/{id}", handlers.User.Delete)
@@ -126,6 +175,26 @@ func NewRouter(config RouterConfig, handlers Handlers, middlewares Middlewares)
 			r.Post("/{id}/roles", handlers.User.AssignRole)
 			r.Delete("/{id}/roles", handlers.User.RemoveRole)
 			r.Get("/{id}/permissions", handlers.User.GetPermissions)
+			r.Get("/{id}/reporting-line", handlers.Managers.ReportingLine)
+
+			// Transfers of a user's open records to other users
+			r.Group(func(r chi.Router) {
+				r.Use(middlewares.Auth.RequirePermission("users:update"))
+				r.Post("/{id}/reassign", handlers.Reassignment.Create)
+				r.Get("/{id}/reassign/{reassignmentID}", handlers.Reassignment.Get)
+			})
+
+			// Managers users report to, which route approvals
+			r.Group(func(r chi.Router) {
+				r.Use(middlewares.Auth.RequirePermission("users:update"))
+				r.Put("/{id}/manager", handlers.Managers.AssignManager)
+			})
+
+			// Offboarding of departing users
+			r.Group(func(r chi.Router) {
+				r.Use(middlewares.Auth.RequirePermission("users:delete"))
+				r.Post("/{id}/offboard", handlers.Offboarding.Offboard)
+			})
 		})
 
 		// Role routes (require tenant context)
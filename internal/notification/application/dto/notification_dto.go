@@ -123,28 +123,57 @@ type NotificationSummaryDTO struct {
 
 // NotificationStatsDTO represents notification statistics.
 type NotificationStatsDTO struct {
-	TenantID     string                       `json:"tenant_id"`
-	Period       string                       `json:"period"`
-	StartDate    time.Time                    `json:"start_date"`
-	EndDate      time.Time                    `json:"end_date"`
-	TotalSent    int64                        `json:"total_sent"`
-	TotalDelivered int64                      `json:"total_delivered"`
-	TotalFailed  int64                        `json:"total_failed"`
-	TotalOpened  int64                        `json:"total_opened"`
-	TotalClicked int64                        `json:"total_clicked"`
-	ByChannel    map[string]ChannelStatsDTO   `json:"by_channel"`
-	ByType       map[string]TypeStatsDTO      `json:"by_type"`
-	ByStatus     map[string]int64             `json:"by_status"`
+	TenantID        string                     `json:"tenant_id"`
+	Period          string                     `json:"period"` // Width of the series buckets
+	StartDate       time.Time                  `json:"start_date"`
+	EndDate         time.Time                  `json:"end_date"`
+	Channel         string                     `json:"channel,omitempty"`
+	Total           int64                      `json:"total"`
+	TotalSent       int64                      `json:"total_sent"`
+	TotalDelivered  int64                      `json:"total_delivered"`
+	TotalFailed     int64                      `json:"total_failed"`
+	TotalOpened     int64                      `json:"total_opened"`
+	TotalClicked    int64                      `json:"total_clicked"`
+	TotalBounced    int64                      `json:"total_bounced"`
+	TotalComplained int64                      `json:"total_complained"`
+	DeliveryRate    float64                    `json:"delivery_rate"`
+	OpenRate        float64                    `json:"open_rate"`
+	ClickRate       float64                    `json:"click_rate"`
+	BounceRate      float64                    `json:"bounce_rate"`
+	FailureRate     float64                    `json:"failure_rate"`
+	ByChannel       map[string]ChannelStatsDTO `json:"by_channel"`
+	ByTemplate      []TemplateStatsDTO         `json:"by_template"`
+	ByStatus        map[string]int64           `json:"by_status"`
+	Series          []StatsBucketDTO           `json:"series"`
 }
 
 // ChannelStatsDTO represents statistics for a notification channel.
 type ChannelStatsDTO struct {
 	Channel      string  `json:"channel"`
+	Total        int64   `json:"total"`
 	Sent         int64   `json:"sent"`
 	Delivered    int64   `json:"delivered"`
 	Failed       int64   `json:"failed"`
 	Opened       int64   `json:"opened"`
 	Clicked      int64   `json:"clicked"`
+	Bounced      int64   `json:"bounced"`
+	DeliveryRate float64 `json:"delivery_rate"`
+	OpenRate     float64 `json:"open_rate"`
+	ClickRate    float64 `json:"click_rate"`
+	BounceRate   float64 `json:"bounce_rate"`
+}
+
+// TemplateStatsDTO represents statistics for a notification template.
+type TemplateStatsDTO struct {
+	TemplateID   string  `json:"template_id"`
+	TemplateName string  `json:"template_name"`
+	Total        int64   `json:"total"`
+	Sent         int64   `json:"sent"`
+	Delivered    int64   `json:"delivered"`
+	Failed       int64   `json:"failed"`
+	Opened       int64   `json:"opened"`
+	Clicked      int64   `json:"clicked"`
+	Bounced      int64   `json:"bounced"`
 	DeliveryRate float64 `json:"delivery_rate"`
 	OpenRate     float64 `json:"open_rate"`
 	ClickRate    float64 `json:"click_rate"`
@@ -159,6 +188,77 @@ type TypeStatsDTO struct {
 	DeliveryRate float64 `json:"delivery_rate"`
 }
 
+// StatsBucketDTO represents the counts of one interval of a stats series.
+type StatsBucketDTO struct {
+	Start     time.Time `json:"start"`
+	Total     int64     `json:"total"`
+	Sent      int64     `json:"sent"`
+	Delivered int64     `json:"delivered"`
+	Opened    int64     `json:"opened"`
+	Clicked   int64     `json:"clicked"`
+	Bounced   int64     `json:"bounced"`
+	Failed    int64     `json:"failed"`
+}
+
+// NotificationTimelineDTO represents the delivery history of a notification.
+type NotificationTimelineDTO struct {
+	NotificationID    string             `json:"notification_id"`
+	Channel           string             `json:"channel"`
+	Recipient         string             `json:"recipient,omitempty"`
+	Status            string             `json:"status"`
+	Provider          string             `json:"provider,omitempty"`
+	ProviderMessageID string             `json:"provider_message_id,omitempty"`
+	Entries           []TimelineEntryDTO `json:"entries"`
+}
+
+// TimelineEntryDTO represents one event in a notification's delivery history.
+type TimelineEntryDTO struct {
+	Event             string    `json:"event"`            // created, scheduled, attempt or a provider status
+	Source            string    `json:"source"`           // system, attempt or webhook
+	Status            string    `json:"status,omitempty"` // Outcome of an attempt
+	Provider          string    `json:"provider,omitempty"`
+	ProviderMessageID string    `json:"provider_message_id,omitempty"`
+	AttemptNumber     int       `json:"attempt_number,omitempty"`
+	ErrorCode         string    `json:"error_code,omitempty"`
+	ErrorMessage      string    `json:"error_message,omitempty"`
+	LatencyMs         int64     `json:"latency_ms,omitempty"`
+	OccurredAt        time.Time `json:"occurred_at"`
+}
+
+// ArchivedEmailDTO represents an archived copy of a sent email.
+type ArchivedEmailDTO struct {
+	ID                string    `json:"id"`
+	TenantID          string    `json:"tenant_id"`
+	NotificationID    string    `json:"notification_id"`
+	MessageID         string    `json:"message_id"`
+	FromAddress       string    `json:"from_address"`
+	Recipients        []string  `json:"recipients"`
+	Subject           string    `json:"subject"`
+	Provider          string    `json:"provider,omitempty"`
+	ProviderMessageID string    `json:"provider_message_id,omitempty"`
+	SizeBytes         int64     `json:"size_bytes"`
+	Checksum          string    `json:"checksum"`
+	Storage           string    `json:"storage"` // inline or object
+	SentAt            time.Time `json:"sent_at"`
+	ExpiresAt         time.Time `json:"expires_at"`
+}
+
+// ArchivedEmailListDTO represents a paginated list of archived emails.
+type ArchivedEmailListDTO struct {
+	Items      []ArchivedEmailDTO `json:"items"`
+	TotalCount int64              `json:"total_count"`
+	Page       int                `json:"page"`
+	PageSize   int                `json:"page_size"`
+	TotalPages int                `json:"total_pages"`
+}
+
+// ArchivedEmailContentDTO represents the raw MIME message of an archived email.
+type ArchivedEmailContentDTO struct {
+	ID       string `json:"id"`
+	Filename string `json:"filename"`
+	Content  []byte `json:"-"`
+}
+
 // === Request DTOs ===
 
 // SendEmailRequest represents a request to send an email notification.
@@ -178,6 +278,7 @@ type SendEmailRequest struct {
 	TemplateID      string                 `json:"template_id,omitempty" validate:"omitempty,uuid"`
 	TemplateVersion *int                   `json:"template_version,omitempty"`
 	Variables       map[string]interface{} `json:"variables,omitempty"`
+	Locale          string                 `json:"locale,omitempty" validate:"omitempty,max=10"` // Template locale; defaults to the recipient's
 	Attachments     []AttachmentDTO        `json:"attachments,omitempty" validate:"omitempty,max=10,dive"`
 	Tags            []string               `json:"tags,omitempty" validate:"omitempty,max=10,dive,max=50"`
 	Metadata        map[string]interface{} `json:"metadata,omitempty"`
@@ -208,6 +309,7 @@ type SendSMSRequest struct {
 	TemplateID      string                 `json:"template_id,omitempty" validate:"omitempty,uuid"`
 	TemplateVersion *int                   `json:"template_version,omitempty"`
 	Variables       map[string]interface{} `json:"variables,omitempty"`
+	Locale          string                 `json:"locale,omitempty" validate:"omitempty,max=10"` // Template locale; defaults to the recipient's
 	Tags            []string               `json:"tags,omitempty" validate:"omitempty,max=10,dive,max=50"`
 	Metadata        map[string]interface{} `json:"metadata,omitempty"`
 	Unicode         *bool                  `json:"unicode,omitempty"`
@@ -332,6 +434,38 @@ type GetNotificationRequest struct {
 	NotificationID string `json:"notification_id" validate:"required,uuid"`
 }
 
+// GetNotificationTimelineRequest represents a request to get a notification's delivery history.
+type GetNotificationTimelineRequest struct {
+	TenantID       string `json:"tenant_id" validate:"required,uuid"`
+	NotificationID string `json:"notification_id" validate:"required,uuid"`
+}
+
+// ProviderWebhookRequest represents a delivery status reported by a provider.
+type ProviderWebhookRequest struct {
+	Provider          string    `json:"provider" validate:"required"`
+	ProviderMessageID string    `json:"provider_message_id" validate:"required"`
+	Status            string    `json:"status" validate:"required,oneof=delivered bounced complained failed opened clicked"`
+	Reason            string    `json:"reason,omitempty"`
+	OccurredAt        time.Time `json:"occurred_at,omitempty"`
+}
+
+// SearchArchivedEmailsRequest represents a request to search the sent email archive.
+type SearchArchivedEmailsRequest struct {
+	TenantID   string     `json:"tenant_id" validate:"required,uuid"`
+	Recipient  string     `json:"recipient,omitempty"`
+	Subject    string     `json:"subject,omitempty"`
+	SentAfter  *time.Time `json:"sent_after,omitempty"`
+	SentBefore *time.Time `json:"sent_before,omitempty"`
+	Page       int        `json:"page" validate:"min=1"`
+	PageSize   int        `json:"page_size" validate:"min=1,max=100"`
+}
+
+// GetArchivedEmailRequest represents a request to get an archived email.
+type GetArchivedEmailRequest struct {
+	TenantID  string `json:"tenant_id" validate:"required,uuid"`
+	ArchiveID string `json:"archive_id" validate:"required,uuid"`
+}
+
 // ListNotificationsRequest represents a request to list notifications.
 type ListNotificationsRequest struct {
 	TenantID    string     `json:"tenant_id" validate:"required,uuid"`
@@ -350,13 +484,14 @@ type ListNotificationsRequest struct {
 }
 
 // GetNotificationStatsRequest represents a request to get notification statistics.
+// The period defaults to the last 30 days, and the interval to one suited to
+// the period.
 type GetNotificationStatsRequest struct {
-	TenantID  string     `json:"tenant_id" validate:"required,uuid"`
-	StartDate time.Time  `json:"start_date" validate:"required"`
-	EndDate   time.Time  `json:"end_date" validate:"required"`
-	Channel   string     `json:"channel,omitempty"`
-	Type      string     `json:"type,omitempty"`
-	GroupBy   string     `json:"group_by,omitempty" validate:"omitempty,oneof=day week month channel type"`
+	TenantID  string    `json:"tenant_id" validate:"required,uuid"`
+	StartDate time.Time `json:"start_date"`
+	EndDate   time.Time `json:"end_date"`
+	Channel   string    `json:"channel,omitempty" validate:"omitempty,oneof=email sms push in_app webhook slack whatsapp telegram"`
+	Interval  string    `json:"interval,omitempty" validate:"omitempty,oneof=hour day week month"`
 }
 
 // === Response DTOs ===
@@ -404,6 +539,13 @@ type CancelNotificationResponse struct {
 	Message        string `json:"message,omitempty"`
 }
 
+// ProviderWebhookResponse represents a response after handling a provider webhook.
+type ProviderWebhookResponse struct {
+	NotificationID string `json:"notification_id"`
+	Status         string `json:"status"`
+	Updated        bool   `json:"updated"` // False when the event was already superseded
+}
+
 // DeliveryStatusResponse represents a delivery status response.
 type DeliveryStatusResponse struct {
 	NotificationID string     `json:"notification_id"`
@@ -421,6 +563,318 @@ type DeliveryStatusResponse struct {
 	RetryCount     int        `json:"retry_count"`
 }
 
+// === Device DTOs ===
+
+// RegisterDeviceRequest represents a request to register a device for push
+// notifications.
+type RegisterDeviceRequest struct {
+	TenantID   string `json:"-"`
+	UserID     string `json:"-"`
+	Token      string `json:"token" validate:"required,max=4096"`
+	Platform   string `json:"platform" validate:"required,oneof=ios android web"`
+	AppVersion string `json:"app_version,omitempty" validate:"omitempty,max=50"`
+}
+
+// RegisterWebPushSubscriptionRequest represents a request to register a
+// browser push subscription, as returned by PushSubscription.toJSON().
+type RegisterWebPushSubscriptionRequest struct {
+	TenantID       string                  `json:"-"`
+	UserID         string                  `json:"-"`
+	Endpoint       string                  `json:"endpoint" validate:"required,url,max=4096"`
+	ExpirationTime *int64                  `json:"expirationTime,omitempty"` // Unix time in milliseconds
+	Keys           WebPushSubscriptionKeys `json:"keys"`
+	AppVersion     string                  `json:"app_version,omitempty" validate:"omitempty,max=50"`
+}
+
+// WebPushSubscriptionKeys holds the base64url-encoded keys of a browser push
+// subscription.
+type WebPushSubscriptionKeys struct {
+	P256DH string `json:"p256dh" validate:"required"`
+	Auth   string `json:"auth" validate:"required"`
+}
+
+// WebPushKeyDTO represents the VAPID public key browsers subscribe with.
+type WebPushKeyDTO struct {
+	PublicKey string `json:"public_key"`
+}
+
+// UnregisterDeviceRequest represents a request to unregister a device.
+type UnregisterDeviceRequest struct {
+	TenantID string `json:"-"`
+	UserID   string `json:"-"`
+	DeviceID string `json:"-"`
+}
+
+// ListDevicesRequest represents a request to list a user's devices.
+type ListDevicesRequest struct {
+	TenantID string `json:"-"`
+	UserID   string `json:"-"`
+}
+
+// DeviceDTO represents a device registered for push notifications. The
+// token is not returned.
+type DeviceDTO struct {
+	ID         string     `json:"id"`
+	UserID     string     `json:"user_id"`
+	Platform   string     `json:"platform"`
+	AppVersion string     `json:"app_version,omitempty"`
+	ExpiresAt  *time.Time `json:"expires_at,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+	LastSeenAt time.Time  `json:"last_seen_at"`
+}
+
+// === Chat Integration DTOs ===
+
+// ChatTemplateDTO represents the message template of a chat trigger.
+type ChatTemplateDTO struct {
+	Title string `json:"title"`
+	Text  string `json:"text"`
+}
+
+// CreateChatIntegrationRequest represents a request to connect a Slack or
+// Microsoft Teams channel.
+type CreateChatIntegrationRequest struct {
+	TenantID         string                     `json:"-"`
+	CreatedBy        string                     `json:"-"`
+	Name             string                     `json:"name" validate:"required,max=100"`
+	Platform         string                     `json:"platform" validate:"required,oneof=slack teams"`
+	WebhookURL       string                     `json:"webhook_url" validate:"required,url"`
+	Triggers         []string                   `json:"triggers" validate:"required,min=1"`
+	BigLeadThreshold float64                    `json:"big_lead_threshold,omitempty" validate:"min=0"`
+	Templates        map[string]ChatTemplateDTO `json:"templates,omitempty"`
+}
+
+// UpdateChatIntegrationRequest represents a request to update a chat
+// integration. Templates replace the templates of the given triggers; an
+// empty template restores the default.
+type UpdateChatIntegrationRequest struct {
+	TenantID         string                     `json:"-"`
+	ID               string                     `json:"-"`
+	Name             *string                    `json:"name,omitempty"`
+	WebhookURL       *string                    `json:"webhook_url,omitempty"`
+	Triggers         []string                   `json:"triggers,omitempty"`
+	BigLeadThreshold *float64                   `json:"big_lead_threshold,omitempty"`
+	Templates        map[string]ChatTemplateDTO `json:"templates,omitempty"`
+	Enabled          *bool                      `json:"enabled,omitempty"`
+}
+
+// ChatIntegrationDTO represents a chat integration. The webhook URL carries
+// credentials, so only its host is returned.
+type ChatIntegrationDTO struct {
+	ID               string                     `json:"id"`
+	Name             string                     `json:"name"`
+	Platform         string                     `json:"platform"`
+	WebhookHost      string                     `json:"webhook_host"`
+	Triggers         []string                   `json:"triggers"`
+	BigLeadThreshold float64                    `json:"big_lead_threshold"`
+	Templates        map[string]ChatTemplateDTO `json:"templates"`
+	Enabled          bool                       `json:"enabled"`
+	LastPostedAt     *time.Time                 `json:"last_posted_at,omitempty"`
+	LastError        string                     `json:"last_error,omitempty"`
+	CreatedBy        string                     `json:"created_by,omitempty"`
+	CreatedAt        time.Time                  `json:"created_at"`
+	UpdatedAt        time.Time                  `json:"updated_at"`
+}
+
+// TestChatIntegrationResponse represents the outcome of posting a test
+// message to a chat integration.
+type TestChatIntegrationResponse struct {
+	Success  bool      `json:"success"`
+	Error    string    `json:"error,omitempty"`
+	PostedAt time.Time `json:"posted_at"`
+}
+
+// RoutingFilterDTO represents a filter on the data of routed events.
+type RoutingFilterDTO struct {
+	Field    string      `json:"field" validate:"required,max=100"`
+	Operator string      `json:"operator" validate:"required,oneof=eq neq in gt gte lt lte exists"`
+	Value    interface{} `json:"value,omitempty"`
+}
+
+// RoutingAudienceDTO represents who a routing rule notifies.
+type RoutingAudienceDTO struct {
+	Type  string `json:"type" validate:"required,oneof=owner team role user recipients"`
+	Value string `json:"value,omitempty"`
+}
+
+// CreateRoutingRuleRequest represents a request to route an event type to
+// audiences on channels.
+type CreateRoutingRuleRequest struct {
+	TenantID         string               `json:"-"`
+	CreatedBy        string               `json:"-"`
+	Name             string               `json:"name" validate:"required,max=100"`
+	EventType        string               `json:"event_type" validate:"required"`
+	Filters          []RoutingFilterDTO   `json:"filters,omitempty" validate:"omitempty,max=20,dive"`
+	Audiences        []RoutingAudienceDTO `json:"audiences" validate:"required,min=1,max=10,dive"`
+	Channels         []string             `json:"channels" validate:"required,min=1"`
+	TemplateCode     string               `json:"template_code,omitempty" validate:"omitempty,max=100"`
+	ChannelTemplates map[string]string    `json:"channel_templates,omitempty"`
+	Priority         int                  `json:"priority,omitempty"`
+	Enabled          *bool                `json:"enabled,omitempty"`
+}
+
+// UpdateRoutingRuleRequest represents a request to update a routing rule;
+// fields left out are kept, and channel templates replace the rule's.
+type UpdateRoutingRuleRequest struct {
+	TenantID         string               `json:"-"`
+	ID               string               `json:"-"`
+	Name             *string              `json:"name,omitempty"`
+	EventType        *string              `json:"event_type,omitempty"`
+	Filters          []RoutingFilterDTO   `json:"filters,omitempty"`
+	Audiences        []RoutingAudienceDTO `json:"audiences,omitempty"`
+	Channels         []string             `json:"channels,omitempty"`
+	TemplateCode     *string              `json:"template_code,omitempty"`
+	ChannelTemplates map[string]string    `json:"channel_templates,omitempty"`
+	Priority         *int                 `json:"priority,omitempty"`
+	Enabled          *bool                `json:"enabled,omitempty"`
+}
+
+// RoutingRuleDTO represents a routing rule.
+type RoutingRuleDTO struct {
+	ID               string               `json:"id"`
+	Name             string               `json:"name"`
+	EventType        string               `json:"event_type"`
+	Filters          []RoutingFilterDTO   `json:"filters"`
+	Audiences        []RoutingAudienceDTO `json:"audiences"`
+	Channels         []string             `json:"channels"`
+	TemplateCode     string               `json:"template_code,omitempty"`
+	ChannelTemplates map[string]string    `json:"channel_templates"`
+	Priority         int                  `json:"priority"`
+	Enabled          bool                 `json:"enabled"`
+	CreatedBy        string               `json:"created_by,omitempty"`
+	CreatedAt        time.Time            `json:"created_at"`
+	UpdatedAt        time.Time            `json:"updated_at"`
+}
+
+// EscalationTargetDTO represents who a level of an escalation pages.
+type EscalationTargetDTO struct {
+	Type  string `json:"type" validate:"required,oneof=user schedule role"`
+	Value string `json:"value" validate:"required,max=100"`
+}
+
+// EscalationLevelDTO represents a level of an escalation policy.
+type EscalationLevelDTO struct {
+	Targets        []EscalationTargetDTO `json:"targets" validate:"required,min=1,max=10,dive"`
+	Channels       []string              `json:"channels" validate:"required,min=1"`
+	TimeoutMinutes int                   `json:"timeout_minutes" validate:"required,min=1,max=1440"`
+}
+
+// CreateEscalationPolicyRequest represents a request to escalate critical
+// alerts through levels of on-call.
+type CreateEscalationPolicyRequest struct {
+	TenantID   string               `json:"-"`
+	CreatedBy  string               `json:"-"`
+	Name       string               `json:"name" validate:"required,max=100"`
+	EventTypes []string             `json:"event_types" validate:"required,min=1"`
+	Levels     []EscalationLevelDTO `json:"levels" validate:"required,min=1,max=10,dive"`
+	MaxCycles  int                  `json:"max_cycles,omitempty" validate:"min=0,max=100"`
+	Enabled    *bool                `json:"enabled,omitempty"`
+}
+
+// UpdateEscalationPolicyRequest represents a request to update an escalation
+// policy; fields left out are kept.
+type UpdateEscalationPolicyRequest struct {
+	TenantID   string               `json:"-"`
+	ID         string               `json:"-"`
+	Name       *string              `json:"name,omitempty"`
+	EventTypes []string             `json:"event_types,omitempty"`
+	Levels     []EscalationLevelDTO `json:"levels,omitempty"`
+	MaxCycles  *int                 `json:"max_cycles,omitempty"`
+	Enabled    *bool                `json:"enabled,omitempty"`
+}
+
+// EscalationPolicyDTO represents an escalation policy.
+type EscalationPolicyDTO struct {
+	ID         string               `json:"id"`
+	Name       string               `json:"name"`
+	EventTypes []string             `json:"event_types"`
+	Levels     []EscalationLevelDTO `json:"levels"`
+	MaxCycles  int                  `json:"max_cycles"`
+	Enabled    bool                 `json:"enabled"`
+	CreatedBy  string               `json:"created_by,omitempty"`
+	CreatedAt  time.Time            `json:"created_at"`
+	UpdatedAt  time.Time            `json:"updated_at"`
+}
+
+// OnCallOverrideDTO represents a user put on call in place of the rotation.
+type OnCallOverrideDTO struct {
+	UserID string    `json:"user_id" validate:"required,uuid"`
+	Start  time.Time `json:"start" validate:"required"`
+	End    time.Time `json:"end" validate:"required"`
+}
+
+// CreateOnCallScheduleRequest represents a request to create an on-call
+// rotation.
+type CreateOnCallScheduleRequest struct {
+	TenantID      string              `json:"-"`
+	Name          string              `json:"name" validate:"required,max=100"`
+	Members       []string            `json:"members" validate:"required,min=1,max=50"`
+	RotationStart time.Time           `json:"rotation_start" validate:"required"`
+	ShiftHours    int                 `json:"shift_hours" validate:"required,min=1,max=720"`
+	Overrides     []OnCallOverrideDTO `json:"overrides,omitempty" validate:"omitempty,max=100,dive"`
+}
+
+// UpdateOnCallScheduleRequest represents a request to update an on-call
+// schedule; fields left out are kept, and overrides replace the schedule's.
+type UpdateOnCallScheduleRequest struct {
+	TenantID      string              `json:"-"`
+	ID            string              `json:"-"`
+	Name          *string             `json:"name,omitempty"`
+	Members       []string            `json:"members,omitempty"`
+	RotationStart *time.Time          `json:"rotation_start,omitempty"`
+	ShiftHours    *int                `json:"shift_hours,omitempty"`
+	Overrides     []OnCallOverrideDTO `json:"overrides,omitempty"`
+}
+
+// OnCallScheduleDTO represents an on-call schedule.
+type OnCallScheduleDTO struct {
+	ID            string              `json:"id"`
+	Name          string              `json:"name"`
+	Members       []string            `json:"members"`
+	RotationStart time.Time           `json:"rotation_start"`
+	ShiftHours    int                 `json:"shift_hours"`
+	Overrides     []OnCallOverrideDTO `json:"overrides"`
+	OnCallUserID  string              `json:"on_call_user_id,omitempty"`
+	CreatedAt     time.Time           `json:"created_at"`
+	UpdatedAt     time.Time           `json:"updated_at"`
+}
+
+// EscalationDTO represents a critical alert being escalated.
+type EscalationDTO struct {
+	ID               string                 `json:"id"`
+	PolicyID         string                 `json:"policy_id"`
+	EventID          string                 `json:"event_id"`
+	EventType        string                 `json:"event_type"`
+	Data             map[string]interface{} `json:"data"`
+	Level            int                    `json:"level"`
+	Cycle            int                    `json:"cycle"`
+	Status           string                 `json:"status"`
+	PagedAt          *time.Time             `json:"paged_at,omitempty"`
+	NextEscalationAt *time.Time             `json:"next_escalation_at,omitempty"`
+	AcknowledgedBy   string                 `json:"acknowledged_by,omitempty"`
+	AcknowledgedAt   *time.Time             `json:"acknowledged_at,omitempty"`
+	CreatedAt        time.Time              `json:"created_at"`
+	UpdatedAt        time.Time              `json:"updated_at"`
+}
+
+// EscalationListDTO represents a paginated list of escalations.
+type EscalationListDTO struct {
+	Items      []*EscalationDTO `json:"items"`
+	TotalCount int64            `json:"total_count"`
+	Page       int              `json:"page"`
+	PageSize   int              `json:"page_size"`
+	TotalPages int              `json:"total_pages"`
+}
+
+// ListEscalationsRequest represents a request to list the escalations of a
+// tenant.
+type ListEscalationsRequest struct {
+	TenantID string `json:"-"`
+	Status   string `json:"status,omitempty" validate:"omitempty,oneof=open acknowledged exhausted"`
+	Page     int    `json:"page,omitempty"`
+	PageSize int    `json:"page_size,omitempty"`
+}
+
 // === Preference DTOs ===
 
 // NotificationPreferenceDTO represents a notification preference.
@@ -476,3 +930,11 @@ type GetPreferenceRequest struct {
 	TenantID string `json:"tenant_id" validate:"required,uuid"`
 	UserID   string `json:"user_id" validate:"required,uuid"`
 }
+
+// DemoDataResponse represents the outcome of seeding or purging a tenant's
+// demo notifications.
+type DemoDataResponse struct {
+	Created  int64 `json:"created"`
+	Existing int64 `json:"existing"`
+	Deleted  int64 `json:"deleted"`
+}
@@ -22,6 +22,7 @@ type TemplateDTO struct {
 	Variables       []TemplateVariableDTO  `json:"variables,omitempty"`
 	Localizations   []LocalizationDTO      `json:"localizations,omitempty"`
 	DefaultLocale   string                 `json:"default_locale"`
+	RetryPolicy     *RetryPolicyDTO        `json:"retry_policy,omitempty"`
 	Tags            []string               `json:"tags,omitempty"`
 	Metadata        map[string]interface{} `json:"metadata,omitempty"`
 	Version         int                    `json:"version"`
@@ -54,6 +55,25 @@ type LocalizationDTO struct {
 	TextBody string `json:"text_body,omitempty"`
 }
 
+// RetryPolicyDTO represents how delivery of a template's notifications is
+// retried.
+type RetryPolicyDTO struct {
+	MaxAttempts            int                           `json:"max_attempts" validate:"min=0,max=10"`
+	InitialIntervalSeconds int                           `json:"initial_interval_seconds" validate:"min=0"`
+	MaxIntervalSeconds     int                           `json:"max_interval_seconds" validate:"min=0"`
+	Multiplier             float64                       `json:"multiplier" validate:"min=1"`
+	Jitter                 float64                       `json:"jitter,omitempty" validate:"min=0,max=1"`
+	RetryableErrors        []string                      `json:"retryable_errors,omitempty"`
+	NonRetryableErrors     []string                      `json:"non_retryable_errors,omitempty"`
+	ProviderErrors         map[string]RetryErrorRulesDTO `json:"provider_errors,omitempty"`
+}
+
+// RetryErrorRulesDTO represents the error codes one provider retries.
+type RetryErrorRulesDTO struct {
+	Retryable    []string `json:"retryable,omitempty"`
+	NonRetryable []string `json:"non_retryable,omitempty"`
+}
+
 // TemplateListDTO represents a paginated list of templates.
 type TemplateListDTO struct {
 	Items      []TemplateSummaryDTO `json:"items"`
@@ -101,6 +121,36 @@ type TemplateVersionListDTO struct {
 	TotalCount int                  `json:"total_count"`
 }
 
+// TemplateVersionDiffDTO represents the differences between two template versions.
+type TemplateVersionDiffDTO struct {
+	TemplateID  string                 `json:"template_id"`
+	FromVersion int                    `json:"from_version"`
+	ToVersion   int                    `json:"to_version"`
+	HasChanges  bool                   `json:"has_changes"`
+	Changes     []TemplateFieldDiffDTO `json:"changes"`
+}
+
+// TemplateFieldDiffDTO represents the difference in one subject or body field.
+type TemplateFieldDiffDTO struct {
+	Field string        `json:"field"` // e.g. email.subject, sms.body
+	From  string        `json:"from"`
+	To    string        `json:"to"`
+	Lines []DiffLineDTO `json:"lines"`
+}
+
+// DiffLineDTO represents a single line of a diff.
+type DiffLineDTO struct {
+	Op   string `json:"op"` // equal, insert, delete
+	Text string `json:"text"`
+}
+
+// TemplateLocalizationListDTO represents the locale variants of a template.
+type TemplateLocalizationListDTO struct {
+	TemplateID    string            `json:"template_id"`
+	DefaultLocale string            `json:"default_locale"`
+	Localizations []LocalizationDTO `json:"localizations"`
+}
+
 // === Request DTOs ===
 
 // CreateTemplateRequest represents a request to create a notification template.
@@ -119,6 +169,7 @@ type CreateTemplateRequest struct {
 	Variables     []TemplateVariableDTO  `json:"variables,omitempty" validate:"omitempty,dive"`
 	Localizations []LocalizationDTO      `json:"localizations,omitempty" validate:"omitempty,dive"`
 	DefaultLocale string                 `json:"default_locale" validate:"required,max=10"`
+	RetryPolicy   *RetryPolicyDTO        `json:"retry_policy,omitempty" validate:"omitempty"`
 	Tags          []string               `json:"tags,omitempty" validate:"omitempty,max=10,dive,max=50"`
 	Metadata      map[string]interface{} `json:"metadata,omitempty"`
 	IsDraft       bool                   `json:"is_draft"`
@@ -139,6 +190,7 @@ type UpdateTemplateRequest struct {
 	Variables     []TemplateVariableDTO  `json:"variables,omitempty" validate:"omitempty,dive"`
 	Localizations []LocalizationDTO      `json:"localizations,omitempty" validate:"omitempty,dive"`
 	DefaultLocale *string                `json:"default_locale,omitempty" validate:"omitempty,max=10"`
+	RetryPolicy   *RetryPolicyDTO        `json:"retry_policy,omitempty" validate:"omitempty"`
 	Tags          []string               `json:"tags,omitempty" validate:"omitempty,max=10,dive,max=50"`
 	Metadata      map[string]interface{} `json:"metadata,omitempty"`
 	ChangeSummary string                 `json:"change_summary,omitempty" validate:"omitempty,max=500"`
@@ -225,6 +277,14 @@ type RevertTemplateVersionRequest struct {
 	RevertedBy string `json:"reverted_by,omitempty" validate:"omitempty,uuid"`
 }
 
+// DiffTemplateVersionsRequest represents a request to compare two template versions.
+type DiffTemplateVersionsRequest struct {
+	TenantID    string `json:"tenant_id" validate:"required,uuid"`
+	TemplateID  string `json:"template_id" validate:"required,uuid"`
+	FromVersion int    `json:"from_version" validate:"required,min=1"`
+	ToVersion   int    `json:"to_version" validate:"required,min=1"`
+}
+
 // CloneTemplateRequest represents a request to clone a template.
 type CloneTemplateRequest struct {
 	TenantID         string `json:"tenant_id" validate:"required,uuid"`
@@ -245,6 +305,51 @@ type RenderTemplateRequest struct {
 	Variables  map[string]interface{} `json:"variables,omitempty"`
 }
 
+// PreviewTemplateRequest represents a request to preview a template with sample variables.
+type PreviewTemplateRequest struct {
+	TenantID   string                 `json:"tenant_id" validate:"required,uuid"`
+	TemplateID string                 `json:"template_id" validate:"required,uuid"`
+	Channel    string                 `json:"channel,omitempty" validate:"omitempty,oneof=email sms push in_app"`
+	Locale     string                 `json:"locale,omitempty"`
+	Variables  map[string]interface{} `json:"variables,omitempty"`
+}
+
+// TestSendTemplateRequest represents a request to send a rendered template to a test recipient.
+type TestSendTemplateRequest struct {
+	TenantID    string                 `json:"tenant_id" validate:"required,uuid"`
+	TemplateID  string                 `json:"template_id" validate:"required,uuid"`
+	Channel     string                 `json:"channel,omitempty" validate:"omitempty,oneof=email sms"`
+	Locale      string                 `json:"locale,omitempty"`
+	Variables   map[string]interface{} `json:"variables,omitempty"`
+	Recipient   string                 `json:"recipient" validate:"required"` // Email address or phone number
+	RequestedBy string                 `json:"requested_by,omitempty" validate:"omitempty,uuid"`
+}
+
+// ListTemplateLocalizationsRequest represents a request to list the locale variants of a template.
+type ListTemplateLocalizationsRequest struct {
+	TenantID   string `json:"tenant_id" validate:"required,uuid"`
+	TemplateID string `json:"template_id" validate:"required,uuid"`
+}
+
+// SetTemplateLocalizationRequest represents a request to add or replace a locale variant.
+type SetTemplateLocalizationRequest struct {
+	TenantID   string `json:"tenant_id" validate:"required,uuid"`
+	TemplateID string `json:"template_id" validate:"required,uuid"`
+	Locale     string `json:"locale" validate:"required,max=10"`
+	Subject    string `json:"subject,omitempty" validate:"omitempty,max=500"`
+	Body       string `json:"body" validate:"required,max=100000"`
+	HTMLBody   string `json:"html_body,omitempty" validate:"omitempty,max=500000"`
+	UpdatedBy  string `json:"updated_by,omitempty" validate:"omitempty,uuid"`
+}
+
+// DeleteTemplateLocalizationRequest represents a request to remove a locale variant.
+type DeleteTemplateLocalizationRequest struct {
+	TenantID   string `json:"tenant_id" validate:"required,uuid"`
+	TemplateID string `json:"template_id" validate:"required,uuid"`
+	Locale     string `json:"locale" validate:"required,max=10"`
+	UpdatedBy  string `json:"updated_by,omitempty" validate:"omitempty,uuid"`
+}
+
 // ValidateTemplateRequest represents a request to validate a template.
 type ValidateTemplateRequest struct {
 	TenantID  string                 `json:"tenant_id" validate:"required,uuid"`
@@ -332,6 +437,37 @@ type RenderTemplateResponse struct {
 	TextBody string `json:"text_body,omitempty"`
 }
 
+// PreviewTemplateResponse represents a rendered template preview.
+type PreviewTemplateResponse struct {
+	TemplateID string                 `json:"template_id"`
+	Channel    string                 `json:"channel"`
+	Locale     string                 `json:"locale"`
+	Subject    string                 `json:"subject,omitempty"`
+	Body       string                 `json:"body"`
+	HTMLBody   string                 `json:"html_body,omitempty"`
+	Variables  map[string]interface{} `json:"variables"` // Values used, including defaults and examples
+}
+
+// TestSendTemplateResponse represents a response after sending a test message.
+type TestSendTemplateResponse struct {
+	TemplateID string    `json:"template_id"`
+	Channel    string    `json:"channel"`
+	Recipient  string    `json:"recipient"`
+	MessageID  string    `json:"message_id"`
+	Provider   string    `json:"provider,omitempty"`
+	Status     string    `json:"status"`
+	SentAt     time.Time `json:"sent_at"`
+	Message    string    `json:"message,omitempty"`
+}
+
+// TemplateLocalizationResponse represents a response after changing a locale variant.
+type TemplateLocalizationResponse struct {
+	TemplateID string    `json:"template_id"`
+	Locale     string    `json:"locale"`
+	UpdatedAt  time.Time `json:"updated_at"`
+	Message    string    `json:"message,omitempty"`
+}
+
 // ValidateTemplateResponse represents a response after validating a template.
 type ValidateTemplateResponse struct {
 	Valid            bool                    `json:"valid"`
@@ -377,3 +513,15 @@ type GetTemplateUsageRequest struct {
 	StartDate  time.Time `json:"start_date" validate:"required"`
 	EndDate    time.Time `json:"end_date" validate:"required"`
 }
+
+// SeedSystemTemplatesRequest represents a request to seed a tenant's system templates.
+type SeedSystemTemplatesRequest struct {
+	TenantID string `json:"tenant_id" validate:"required,uuid"`
+}
+
+// SeedSystemTemplatesResponse lists the codes of the system templates that
+// were created and of those the tenant already had.
+type SeedSystemTemplatesResponse struct {
+	Created  []string `json:"created"`
+	Existing []string `json:"existing"`
+}
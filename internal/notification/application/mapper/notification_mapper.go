@@ -2,6 +2,8 @@
 package mapper
 
 import (
+	"sort"
+
 	"github.com/kilang-desa-murni/crm/internal/notification/application/dto"
 	"github.com/kilang-desa-murni/crm/internal/notification/domain"
 )
@@ -205,6 +207,203 @@ func (m *NotificationMapper) NotificationListToDTO(
 	}
 }
 
+// ToStatsDTO converts NotificationStats to a NotificationStatsDTO.
+func (m *NotificationMapper) ToStatsDTO(tenantID string, query domain.NotificationStatsQuery, stats *domain.NotificationStats) *dto.NotificationStatsDTO {
+	if stats == nil {
+		return nil
+	}
+
+	counts := stats.Counts
+	result := &dto.NotificationStatsDTO{
+		TenantID:        tenantID,
+		Period:          string(query.Interval),
+		StartDate:       query.StartDate,
+		EndDate:         query.EndDate,
+		Total:           counts.Total,
+		TotalSent:       counts.Sent,
+		TotalDelivered:  counts.Delivered,
+		TotalFailed:     counts.Failed,
+		TotalOpened:     counts.Opened,
+		TotalClicked:    counts.Clicked,
+		TotalBounced:    counts.Bounced,
+		TotalComplained: counts.Complained,
+		DeliveryRate:    counts.DeliveryRate(),
+		OpenRate:        counts.OpenRate(),
+		ClickRate:       counts.ClickRate(),
+		BounceRate:      counts.BounceRate(),
+		FailureRate:     counts.FailureRate(),
+		ByChannel:       make(map[string]dto.ChannelStatsDTO, len(stats.ChannelCounts)),
+		ByTemplate:      make([]dto.TemplateStatsDTO, 0, len(stats.TemplateCounts)),
+		ByStatus:        make(map[string]int64, len(stats.ByStatus)),
+		Series:          make([]dto.StatsBucketDTO, 0, len(stats.Series)),
+	}
+	if query.Channel != nil {
+		result.Channel = query.Channel.String()
+	}
+
+	for channel, c := range stats.ChannelCounts {
+		result.ByChannel[channel.String()] = dto.ChannelStatsDTO{
+			Channel:      channel.String(),
+			Total:        c.Total,
+			Sent:         c.Sent,
+			Delivered:    c.Delivered,
+			Failed:       c.Failed,
+			Opened:       c.Opened,
+			Clicked:      c.Clicked,
+			Bounced:      c.Bounced,
+			DeliveryRate: c.DeliveryRate(),
+			OpenRate:     c.OpenRate(),
+			ClickRate:    c.ClickRate(),
+			BounceRate:   c.BounceRate(),
+		}
+	}
+	for _, t := range stats.TemplateCounts {
+		result.ByTemplate = append(result.ByTemplate, dto.TemplateStatsDTO{
+			TemplateID:   t.TemplateID.String(),
+			TemplateName: t.TemplateName,
+			Total:        t.Total,
+			Sent:         t.Sent,
+			Delivered:    t.Delivered,
+			Failed:       t.Failed,
+			Opened:       t.Opened,
+			Clicked:      t.Clicked,
+			Bounced:      t.Bounced,
+			DeliveryRate: t.DeliveryRate(),
+			OpenRate:     t.OpenRate(),
+			ClickRate:    t.ClickRate(),
+		})
+	}
+	for status, count := range stats.ByStatus {
+		result.ByStatus[status.String()] = count
+	}
+	for _, b := range stats.Series {
+		result.Series = append(result.Series, dto.StatsBucketDTO{
+			Start:     b.Start,
+			Total:     b.Total,
+			Sent:      b.Sent,
+			Delivered: b.Delivered,
+			Opened:    b.Opened,
+			Clicked:   b.Clicked,
+			Bounced:   b.Bounced,
+			Failed:    b.Failed,
+		})
+	}
+
+	return result
+}
+
+// ToTimelineDTO converts a notification and its delivery logs to a
+// NotificationTimelineDTO, with entries in the order they happened.
+func (m *NotificationMapper) ToTimelineDTO(entity *domain.Notification, logs []*domain.DeliveryLog) *dto.NotificationTimelineDTO {
+	if entity == nil {
+		return nil
+	}
+
+	recipient := m.recipientToDTO(entity)
+	timeline := &dto.NotificationTimelineDTO{
+		NotificationID:    entity.ID.String(),
+		Channel:           entity.Channel.String(),
+		Recipient:         firstNonEmpty(recipient.Email, recipient.Phone, recipient.DeviceToken, recipient.UserID),
+		Status:            entity.Status.String(),
+		Provider:          entity.Provider,
+		ProviderMessageID: entity.ProviderMessageID,
+		Entries:           make([]dto.TimelineEntryDTO, 0, len(logs)+2),
+	}
+
+	timeline.Entries = append(timeline.Entries, dto.TimelineEntryDTO{
+		Event:      "created",
+		Source:     "system",
+		OccurredAt: entity.CreatedAt,
+	})
+	for _, log := range logs {
+		event := log.Status
+		if log.Source == domain.DeliveryLogSourceAttempt {
+			event = "attempt"
+		}
+		timeline.Entries = append(timeline.Entries, dto.TimelineEntryDTO{
+			Event:             event,
+			Source:            string(log.Source),
+			Status:            log.Status,
+			Provider:          log.Provider,
+			ProviderMessageID: log.ProviderMessageID,
+			AttemptNumber:     log.AttemptNumber,
+			ErrorCode:         log.ErrorCode,
+			ErrorMessage:      log.ErrorMessage,
+			LatencyMs:         log.LatencyMs,
+			OccurredAt:        log.CreatedAt,
+		})
+	}
+	if entity.CancelledAt != nil {
+		timeline.Entries = append(timeline.Entries, dto.TimelineEntryDTO{
+			Event:      "cancelled",
+			Source:     "system",
+			OccurredAt: *entity.CancelledAt,
+		})
+	}
+
+	sort.SliceStable(timeline.Entries, func(i, j int) bool {
+		return timeline.Entries[i].OccurredAt.Before(timeline.Entries[j].OccurredAt)
+	})
+	return timeline
+}
+
+// ToArchivedEmailDTO converts an ArchivedEmail to an ArchivedEmailDTO.
+func (m *NotificationMapper) ToArchivedEmailDTO(entity *domain.ArchivedEmail) *dto.ArchivedEmailDTO {
+	if entity == nil {
+		return nil
+	}
+
+	storage := "inline"
+	if !entity.IsInline() {
+		storage = "object"
+	}
+	return &dto.ArchivedEmailDTO{
+		ID:                entity.ID.String(),
+		TenantID:          entity.TenantID.String(),
+		NotificationID:    entity.NotificationID.String(),
+		MessageID:         entity.MessageID,
+		FromAddress:       entity.FromAddress,
+		Recipients:        entity.Recipients,
+		Subject:           entity.Subject,
+		Provider:          entity.Provider,
+		ProviderMessageID: entity.ProviderMessageID,
+		SizeBytes:         entity.SizeBytes,
+		Checksum:          entity.Checksum,
+		Storage:           storage,
+		SentAt:            entity.SentAt,
+		ExpiresAt:         entity.ExpiresAt,
+	}
+}
+
+// ArchivedEmailListToDTO converts a page of archived emails to an ArchivedEmailListDTO.
+func (m *NotificationMapper) ArchivedEmailListToDTO(list *domain.ArchivedEmailList, page, pageSize int) *dto.ArchivedEmailListDTO {
+	totalPages := int(list.Total) / pageSize
+	if int(list.Total)%pageSize > 0 {
+		totalPages++
+	}
+
+	items := make([]dto.ArchivedEmailDTO, 0, len(list.Emails))
+	for _, email := range list.Emails {
+		items = append(items, *m.ToArchivedEmailDTO(email))
+	}
+	return &dto.ArchivedEmailListDTO{
+		Items:      items,
+		TotalCount: list.Total,
+		Page:       page,
+		PageSize:   pageSize,
+		TotalPages: totalPages,
+	}
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
 // RecipientFromDTO converts a RecipientDTO to a domain Recipient.
 func (m *NotificationMapper) RecipientFromDTO(d *dto.RecipientDTO) *domain.Recipient {
 	if d == nil {
@@ -2,6 +2,8 @@
 package mapper
 
 import (
+	"sort"
+
 	"github.com/kilang-desa-murni/crm/internal/notification/application/dto"
 	"github.com/kilang-desa-murni/crm/internal/notification/domain"
 )
@@ -60,6 +62,7 @@ func (m *TemplateMapper) ToDTO(entity *domain.NotificationTemplate) *dto.Templat
 		Variables:     m.variablesToDTO(entity.Variables),
 		Localizations: m.localizationsToDTO(entity.Localizations),
 		DefaultLocale: entity.DefaultLocale,
+		RetryPolicy:   m.RetryPolicyToDTO(entity.RetryPolicy),
 		Tags:          entity.Tags,
 		Version:       entity.TemplateVersion,
 		Status:        m.getTemplateStatus(entity),
@@ -193,9 +196,25 @@ func (m *TemplateMapper) localizationsToDTO(localizations map[string]*domain.Tem
 		}
 		result = append(result, locDTO)
 	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Locale < result[j].Locale
+	})
 	return result
 }
 
+// ToLocalizationListDTO converts the localizations of a template to a list DTO.
+func (m *TemplateMapper) ToLocalizationListDTO(entity *domain.NotificationTemplate) *dto.TemplateLocalizationListDTO {
+	localizations := m.localizationsToDTO(entity.Localizations)
+	if localizations == nil {
+		localizations = []dto.LocalizationDTO{}
+	}
+	return &dto.TemplateLocalizationListDTO{
+		TemplateID:    entity.ID.String(),
+		DefaultLocale: entity.DefaultLocale,
+		Localizations: localizations,
+	}
+}
+
 // VariablesFromDTO converts DTO template variables to domain variables.
 func (m *TemplateMapper) VariablesFromDTO(variables []dto.TemplateVariableDTO) []domain.TemplateVariable {
 	if len(variables) == 0 {
@@ -215,6 +234,60 @@ func (m *TemplateMapper) VariablesFromDTO(variables []dto.TemplateVariableDTO) [
 	return result
 }
 
+// RetryPolicyToDTO converts a domain retry policy to a DTO.
+func (m *TemplateMapper) RetryPolicyToDTO(policy *domain.RetryPolicy) *dto.RetryPolicyDTO {
+	if policy == nil {
+		return nil
+	}
+
+	result := &dto.RetryPolicyDTO{
+		MaxAttempts:            policy.MaxAttempts,
+		InitialIntervalSeconds: policy.InitialInterval,
+		MaxIntervalSeconds:     policy.MaxInterval,
+		Multiplier:             policy.Multiplier,
+		Jitter:                 policy.Jitter,
+		RetryableErrors:        policy.RetryableErrors,
+		NonRetryableErrors:     policy.NonRetryableErrors,
+	}
+	if len(policy.ProviderErrors) > 0 {
+		result.ProviderErrors = make(map[string]dto.RetryErrorRulesDTO, len(policy.ProviderErrors))
+		for provider, rules := range policy.ProviderErrors {
+			result.ProviderErrors[provider] = dto.RetryErrorRulesDTO{
+				Retryable:    rules.Retryable,
+				NonRetryable: rules.NonRetryable,
+			}
+		}
+	}
+	return result
+}
+
+// RetryPolicyFromDTO converts a DTO retry policy to a domain retry policy.
+func (m *TemplateMapper) RetryPolicyFromDTO(policy *dto.RetryPolicyDTO) *domain.RetryPolicy {
+	if policy == nil {
+		return nil
+	}
+
+	result := &domain.RetryPolicy{
+		MaxAttempts:        policy.MaxAttempts,
+		InitialInterval:    policy.InitialIntervalSeconds,
+		MaxInterval:        policy.MaxIntervalSeconds,
+		Multiplier:         policy.Multiplier,
+		Jitter:             policy.Jitter,
+		RetryableErrors:    policy.RetryableErrors,
+		NonRetryableErrors: policy.NonRetryableErrors,
+	}
+	if len(policy.ProviderErrors) > 0 {
+		result.ProviderErrors = make(map[string]domain.RetryErrorRules, len(policy.ProviderErrors))
+		for provider, rules := range policy.ProviderErrors {
+			result.ProviderErrors[provider] = domain.RetryErrorRules{
+				Retryable:    rules.Retryable,
+				NonRetryable: rules.NonRetryable,
+			}
+		}
+	}
+	return result
+}
+
 // ToVersionDTO converts a NotificationTemplate to a TemplateVersionDTO.
 func (m *TemplateMapper) ToVersionDTO(entity *domain.NotificationTemplate, changeSummary string) *dto.TemplateVersionDTO {
 	if entity == nil {
@@ -273,6 +346,88 @@ func (m *TemplateMapper) ToVersionDTOList(entities []*domain.NotificationTemplat
 	return result
 }
 
+// RevisionToVersionDTO converts a TemplateRevision to a TemplateVersionDTO.
+func (m *TemplateMapper) RevisionToVersionDTO(revision *domain.TemplateRevision) *dto.TemplateVersionDTO {
+	if revision == nil {
+		return nil
+	}
+
+	// Extract content
+	subject := ""
+	body := ""
+	htmlBody := ""
+
+	if revision.EmailTemplate != nil {
+		subject = revision.EmailTemplate.Subject
+		body = revision.EmailTemplate.Body
+		htmlBody = revision.EmailTemplate.HTMLBody
+	} else if revision.SMSTemplate != nil {
+		body = revision.SMSTemplate.Body
+	} else if revision.PushTemplate != nil {
+		subject = revision.PushTemplate.Title
+		body = revision.PushTemplate.Body
+	} else if revision.InAppTemplate != nil {
+		subject = revision.InAppTemplate.Title
+		body = revision.InAppTemplate.Body
+	}
+
+	result := &dto.TemplateVersionDTO{
+		Version:       revision.Number,
+		Subject:       subject,
+		Body:          body,
+		HTMLBody:      htmlBody,
+		Variables:     m.variablesToDTO(revision.Variables),
+		ChangeSummary: revision.ChangeSummary,
+		PublishedAt:   revision.PublishedAt,
+		CreatedAt:     revision.CreatedAt,
+	}
+
+	if revision.CreatedBy != nil {
+		result.CreatedBy = revision.CreatedBy.String()
+	}
+
+	return result
+}
+
+// RevisionsToVersionDTOList converts a list of revisions to a list of TemplateVersionDTOs.
+func (m *TemplateMapper) RevisionsToVersionDTOList(revisions []*domain.TemplateRevision) []dto.TemplateVersionDTO {
+	result := make([]dto.TemplateVersionDTO, 0, len(revisions))
+	for _, revision := range revisions {
+		if revision != nil {
+			result = append(result, *m.RevisionToVersionDTO(revision))
+		}
+	}
+	return result
+}
+
+// ToVersionDiffDTO converts the field diffs between two revisions to a TemplateVersionDiffDTO.
+func (m *TemplateMapper) ToVersionDiffDTO(templateID string, from, to int, diffs []domain.TemplateFieldDiff) *dto.TemplateVersionDiffDTO {
+	changes := make([]dto.TemplateFieldDiffDTO, len(diffs))
+	for i, diff := range diffs {
+		lines := make([]dto.DiffLineDTO, len(diff.Lines))
+		for j, line := range diff.Lines {
+			lines[j] = dto.DiffLineDTO{
+				Op:   string(line.Op),
+				Text: line.Text,
+			}
+		}
+		changes[i] = dto.TemplateFieldDiffDTO{
+			Field: diff.Field,
+			From:  diff.From,
+			To:    diff.To,
+			Lines: lines,
+		}
+	}
+
+	return &dto.TemplateVersionDiffDTO{
+		TemplateID:  templateID,
+		FromVersion: from,
+		ToVersion:   to,
+		HasChanges:  len(changes) > 0,
+		Changes:     changes,
+	}
+}
+
 // TemplateListToDTO converts a list of templates with pagination info to a TemplateListDTO.
 func (m *TemplateMapper) TemplateListToDTO(
 	templates []*domain.NotificationTemplate,
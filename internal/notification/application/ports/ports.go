@@ -3,6 +3,7 @@ package ports
 
 import (
 	"context"
+	"encoding/json"
 	"time"
 
 	"github.com/kilang-desa-murni/crm/internal/notification/domain"
@@ -121,8 +122,10 @@ type PushProvider interface {
 // PushRequest represents a request to send a push notification.
 type PushRequest struct {
 	MessageID    string
+	TenantID     string
 	DeviceToken  string
 	Platform     string // ios, android, web
+	WebPush      *WebPushSubscription // set for browser subscriptions
 	Title        string
 	Body         string
 	ImageURL     string
@@ -138,6 +141,21 @@ type PushRequest struct {
 	ScheduledAt  *time.Time
 }
 
+// WebPushSubscription is a browser's push subscription: the push service
+// endpoint and the keys the payload is encrypted with.
+type WebPushSubscription struct {
+	Endpoint string
+	P256DH   string // base64url-encoded P-256 public key of the browser
+	Auth     string // base64url-encoded authentication secret
+}
+
+// WebPushKeys provides the VAPID keys browsers subscribe with, per tenant.
+type WebPushKeys interface {
+	// PublicKey returns the base64url-encoded VAPID public key of a tenant,
+	// the applicationServerKey of its browser subscriptions.
+	PublicKey(tenantID string) (string, error)
+}
+
 // PushResponse represents the response from sending a push notification.
 type PushResponse struct {
 	MessageID       string
@@ -257,6 +275,33 @@ type SlackResponse struct {
 	SentAt       time.Time
 }
 
+// TeamsProvider defines the interface for Microsoft Teams notification delivery.
+type TeamsProvider interface {
+	// SendTeamsMessage posts a message to a Teams channel.
+	SendTeamsMessage(ctx context.Context, request TeamsRequest) (*TeamsResponse, error)
+	// GetProviderName returns the provider name.
+	GetProviderName() string
+	// IsAvailable checks if the provider is available.
+	IsAvailable(ctx context.Context) bool
+}
+
+// TeamsRequest represents a request to post a Teams message.
+type TeamsRequest struct {
+	MessageID  string
+	WebhookURL string
+	Text       string                 // fallback text
+	Card       map[string]interface{} // Adaptive Card
+}
+
+// TeamsResponse represents the response from posting a Teams message.
+type TeamsResponse struct {
+	MessageID    string
+	Status       string
+	StatusCode   int
+	ErrorMessage string
+	SentAt       time.Time
+}
+
 // WhatsAppProvider defines the interface for WhatsApp notification delivery.
 type WhatsAppProvider interface {
 	// SendWhatsAppMessage sends a WhatsApp notification.
@@ -445,6 +490,25 @@ type UserInfo struct {
 	Metadata     map[string]interface{}
 }
 
+// TenantBrandingProvider provides the branding of white-labeled tenants,
+// managed by the IAM service.
+type TenantBrandingProvider interface {
+	// GetTenantBranding retrieves a tenant's branding, or nil when the tenant
+	// has none.
+	GetTenantBranding(ctx context.Context, tenantID string) (*TenantBranding, error)
+}
+
+// TenantBranding is how a white-labeled tenant presents the emails sent on
+// its behalf.
+type TenantBranding struct {
+	TenantName       string
+	LogoURL          string
+	PrimaryColor     string
+	SecondaryColor   string
+	EmailFromAddress string
+	EmailFromName    string
+}
+
 // CustomerService defines the interface for customer-related operations.
 type CustomerService interface {
 	// GetCustomer retrieves a customer by ID.
@@ -499,6 +563,36 @@ type Scheduler interface {
 	GetScheduled(ctx context.Context, before time.Time, limit int) ([]string, error)
 }
 
+// DeliveryJob is a notification waiting in the delivery queue.
+type DeliveryJob struct {
+	NotificationID string                      `json:"notification_id"`
+	TenantID       string                      `json:"tenant_id"`
+	Channel        domain.NotificationChannel  `json:"channel"`
+	Priority       domain.NotificationPriority `json:"priority"`
+	EnqueuedAt     time.Time                   `json:"enqueued_at"`
+	// Payload is the send request, for the delivery options that are not
+	// stored on the notification, such as attachments.
+	Payload json.RawMessage `json:"payload,omitempty"`
+}
+
+// DeliveryQueue defines the interface for the queue notifications wait in
+// for delivery. Notifications are delivered in priority order.
+type DeliveryQueue interface {
+	// Enqueue adds a notification to the queue of its priority.
+	Enqueue(ctx context.Context, job *DeliveryJob) error
+}
+
+// FileStorage defines the interface for object storage, used for archived
+// emails too large to keep in the database.
+type FileStorage interface {
+	// Upload stores content under key.
+	Upload(ctx context.Context, key string, content []byte, contentType string) error
+	// Download retrieves the content stored under key.
+	Download(ctx context.Context, key string) ([]byte, error)
+	// Delete deletes the content stored under key.
+	Delete(ctx context.Context, key string) error
+}
+
 // MetricsCollector defines the interface for metrics collection.
 type MetricsCollector interface {
 	// IncrementCounter increments a counter metric.
@@ -0,0 +1,350 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/kilang-desa-murni/crm/internal/notification/application"
+	"github.com/kilang-desa-murni/crm/internal/notification/application/dto"
+	"github.com/kilang-desa-murni/crm/internal/notification/application/ports"
+	"github.com/kilang-desa-murni/crm/internal/notification/domain"
+)
+
+// ChatIntegrationUseCase defines the interface for posting CRM events to the
+// Slack and Microsoft Teams channels of a tenant.
+type ChatIntegrationUseCase interface {
+	// CreateIntegration connects a channel through its incoming webhook.
+	CreateIntegration(ctx context.Context, req *dto.CreateChatIntegrationRequest) (*dto.ChatIntegrationDTO, error)
+	// UpdateIntegration updates an integration.
+	UpdateIntegration(ctx context.Context, req *dto.UpdateChatIntegrationRequest) (*dto.ChatIntegrationDTO, error)
+	// GetIntegration gets an integration of a tenant.
+	GetIntegration(ctx context.Context, tenantID, id string) (*dto.ChatIntegrationDTO, error)
+	// ListIntegrations lists the integrations of a tenant.
+	ListIntegrations(ctx context.Context, tenantID string) ([]*dto.ChatIntegrationDTO, error)
+	// DeleteIntegration removes an integration.
+	DeleteIntegration(ctx context.Context, tenantID, id string) error
+	// TestIntegration posts a test message to verify the configuration of an
+	// integration, whether or not it is enabled.
+	TestIntegration(ctx context.Context, tenantID, id string) (*dto.TestChatIntegrationResponse, error)
+	// Post posts an event to the enabled integrations of its tenant that
+	// subscribe to it. Every integration is tried; the failures are returned
+	// together.
+	Post(ctx context.Context, event *domain.ChatEvent) error
+}
+
+// chatIntegrationUseCase implements the ChatIntegrationUseCase interface.
+type chatIntegrationUseCase struct {
+	repo  domain.ChatIntegrationRepository
+	slack ports.SlackProvider
+	teams ports.TeamsProvider
+}
+
+// NewChatIntegrationUseCase creates a new chat integration use case.
+func NewChatIntegrationUseCase(repo domain.ChatIntegrationRepository, slack ports.SlackProvider, teams ports.TeamsProvider) ChatIntegrationUseCase {
+	return &chatIntegrationUseCase{repo: repo, slack: slack, teams: teams}
+}
+
+// CreateIntegration connects a channel through its incoming webhook.
+func (uc *chatIntegrationUseCase) CreateIntegration(ctx context.Context, req *dto.CreateChatIntegrationRequest) (*dto.ChatIntegrationDTO, error) {
+	tenantID, err := uuid.Parse(req.TenantID)
+	if err != nil {
+		return nil, application.NewInvalidInputError("invalid tenant ID format")
+	}
+	var createdBy *uuid.UUID
+	if req.CreatedBy != "" {
+		userID, err := uuid.Parse(req.CreatedBy)
+		if err != nil {
+			return nil, application.NewInvalidInputError("invalid user ID format")
+		}
+		createdBy = &userID
+	}
+
+	platform := domain.ChatPlatform(strings.ToLower(strings.TrimSpace(req.Platform)))
+	integration, err := domain.NewChatIntegration(tenantID, req.Name, platform, req.WebhookURL, toChatTriggers(req.Triggers), createdBy)
+	if err != nil {
+		return nil, chatValidationError(err)
+	}
+	if err := integration.SetBigLeadThreshold(req.BigLeadThreshold); err != nil {
+		return nil, chatValidationError(err)
+	}
+	if err := setChatTemplates(integration, req.Templates); err != nil {
+		return nil, err
+	}
+
+	if err := uc.repo.Create(ctx, integration); err != nil {
+		return nil, application.NewInternalError("failed to create chat integration", err)
+	}
+	return toChatIntegrationDTO(integration), nil
+}
+
+// UpdateIntegration updates an integration.
+func (uc *chatIntegrationUseCase) UpdateIntegration(ctx context.Context, req *dto.UpdateChatIntegrationRequest) (*dto.ChatIntegrationDTO, error) {
+	integration, err := uc.find(ctx, req.TenantID, req.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Name != nil {
+		if err := integration.Rename(*req.Name); err != nil {
+			return nil, chatValidationError(err)
+		}
+	}
+	if req.WebhookURL != nil {
+		if err := integration.SetWebhookURL(*req.WebhookURL); err != nil {
+			return nil, chatValidationError(err)
+		}
+	}
+	if req.Triggers != nil {
+		if err := integration.SetTriggers(toChatTriggers(req.Triggers)); err != nil {
+			return nil, chatValidationError(err)
+		}
+	}
+	if req.BigLeadThreshold != nil {
+		if err := integration.SetBigLeadThreshold(*req.BigLeadThreshold); err != nil {
+			return nil, chatValidationError(err)
+		}
+	}
+	if err := setChatTemplates(integration, req.Templates); err != nil {
+		return nil, err
+	}
+	if req.Enabled != nil {
+		if *req.Enabled {
+			integration.Enable()
+		} else {
+			integration.Disable()
+		}
+	}
+
+	if err := uc.repo.Update(ctx, integration); err != nil {
+		if errors.Is(err, domain.ErrChatIntegrationNotFound) {
+			return nil, application.NewNotFoundError("chat integration", req.ID)
+		}
+		return nil, application.NewInternalError("failed to update chat integration", err)
+	}
+	return toChatIntegrationDTO(integration), nil
+}
+
+// GetIntegration gets an integration of a tenant.
+func (uc *chatIntegrationUseCase) GetIntegration(ctx context.Context, tenantID, id string) (*dto.ChatIntegrationDTO, error) {
+	integration, err := uc.find(ctx, tenantID, id)
+	if err != nil {
+		return nil, err
+	}
+	return toChatIntegrationDTO(integration), nil
+}
+
+// ListIntegrations lists the integrations of a tenant, oldest first.
+func (uc *chatIntegrationUseCase) ListIntegrations(ctx context.Context, tenantID string) ([]*dto.ChatIntegrationDTO, error) {
+	tid, err := uuid.Parse(tenantID)
+	if err != nil {
+		return nil, application.NewInvalidInputError("invalid tenant ID format")
+	}
+	integrations, err := uc.repo.FindByTenant(ctx, tid)
+	if err != nil {
+		return nil, application.NewInternalError("failed to list chat integrations", err)
+	}
+	result := make([]*dto.ChatIntegrationDTO, len(integrations))
+	for i, integration := range integrations {
+		result[i] = toChatIntegrationDTO(integration)
+	}
+	return result, nil
+}
+
+// DeleteIntegration removes an integration.
+func (uc *chatIntegrationUseCase) DeleteIntegration(ctx context.Context, tenantID, id string) error {
+	tid, iid, err := parseChatIntegrationID(tenantID, id)
+	if err != nil {
+		return err
+	}
+	if err := uc.repo.Delete(ctx, tid, iid); err != nil {
+		if errors.Is(err, domain.ErrChatIntegrationNotFound) {
+			return application.NewNotFoundError("chat integration", id)
+		}
+		return application.NewInternalError("failed to delete chat integration", err)
+	}
+	return nil
+}
+
+// TestIntegration posts a test message to an integration. A message the
+// platform rejects is reported in the response rather than as an error, so
+// tenants see why their configuration does not work.
+func (uc *chatIntegrationUseCase) TestIntegration(ctx context.Context, tenantID, id string) (*dto.TestChatIntegrationResponse, error) {
+	integration, err := uc.find(ctx, tenantID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	message, err := integration.Render(&domain.ChatEvent{TenantID: integration.TenantID, Trigger: domain.ChatTriggerTest})
+	if err != nil {
+		return nil, chatValidationError(err)
+	}
+	postErr := uc.send(ctx, integration, message)
+	now := time.Now()
+	integration.RecordPost(postErr, now)
+	if err := uc.repo.Update(ctx, integration); err != nil {
+		return nil, application.NewInternalError("failed to update chat integration", err)
+	}
+
+	response := &dto.TestChatIntegrationResponse{Success: postErr == nil, PostedAt: now.UTC()}
+	if postErr != nil {
+		response.Error = postErr.Error()
+	}
+	return response, nil
+}
+
+// Post posts an event to the integrations of its tenant.
+func (uc *chatIntegrationUseCase) Post(ctx context.Context, event *domain.ChatEvent) error {
+	integrations, err := uc.repo.FindEnabledByTrigger(ctx, event.TenantID, event.Trigger)
+	if err != nil {
+		return fmt.Errorf("failed to find chat integrations: %w", err)
+	}
+
+	var errs []error
+	for _, integration := range integrations {
+		if !integration.Matches(event) {
+			continue
+		}
+		message, err := integration.Render(event)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("chat integration %s: %w", integration.ID, err))
+			continue
+		}
+		postErr := uc.send(ctx, integration, message)
+		if postErr != nil {
+			errs = append(errs, fmt.Errorf("chat integration %s: %w", integration.ID, postErr))
+		}
+		integration.RecordPost(postErr, time.Now())
+		if err := uc.repo.Update(ctx, integration); err != nil {
+			errs = append(errs, fmt.Errorf("chat integration %s: %w", integration.ID, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// send posts a message to the webhook of an integration, as Block Kit blocks
+// on Slack and as an Adaptive Card on Teams.
+func (uc *chatIntegrationUseCase) send(ctx context.Context, integration *domain.ChatIntegration, message *domain.ChatMessage) error {
+	messageID := uuid.New().String()
+	switch integration.Platform {
+	case domain.ChatPlatformSlack:
+		if uc.slack == nil {
+			return application.NewChannelNotConfiguredError("slack")
+		}
+		_, err := uc.slack.SendSlackMessage(ctx, ports.SlackRequest{
+			MessageID:  messageID,
+			WebhookURL: integration.WebhookURL,
+			Text:       message.FallbackText(),
+			Blocks:     message.SlackBlocks(),
+		})
+		return err
+	case domain.ChatPlatformTeams:
+		if uc.teams == nil {
+			return application.NewChannelNotConfiguredError("teams")
+		}
+		_, err := uc.teams.SendTeamsMessage(ctx, ports.TeamsRequest{
+			MessageID:  messageID,
+			WebhookURL: integration.WebhookURL,
+			Text:       message.FallbackText(),
+			Card:       message.TeamsCard(),
+		})
+		return err
+	default:
+		return application.NewChannelNotConfiguredError(string(integration.Platform))
+	}
+}
+
+// find loads an integration of a tenant.
+func (uc *chatIntegrationUseCase) find(ctx context.Context, tenantID, id string) (*domain.ChatIntegration, error) {
+	tid, iid, err := parseChatIntegrationID(tenantID, id)
+	if err != nil {
+		return nil, err
+	}
+	integration, err := uc.repo.FindByID(ctx, tid, iid)
+	if err != nil {
+		if errors.Is(err, domain.ErrChatIntegrationNotFound) {
+			return nil, application.NewNotFoundError("chat integration", id)
+		}
+		return nil, application.NewInternalError("failed to find chat integration", err)
+	}
+	return integration, nil
+}
+
+// parseChatIntegrationID parses the tenant and ID of an integration.
+func parseChatIntegrationID(tenantID, id string) (uuid.UUID, uuid.UUID, error) {
+	tid, err := uuid.Parse(tenantID)
+	if err != nil {
+		return uuid.Nil, uuid.Nil, application.NewInvalidInputError("invalid tenant ID format")
+	}
+	iid, err := uuid.Parse(id)
+	if err != nil {
+		return uuid.Nil, uuid.Nil, application.NewInvalidInputError("invalid chat integration ID format")
+	}
+	return tid, iid, nil
+}
+
+// setChatTemplates applies the templates of a request to an integration.
+func setChatTemplates(integration *domain.ChatIntegration, templates map[string]dto.ChatTemplateDTO) error {
+	for trigger, template := range templates {
+		err := integration.SetTemplate(domain.ChatTrigger(trigger), domain.ChatTemplate{Title: template.Title, Text: template.Text})
+		if err != nil {
+			return chatValidationError(err)
+		}
+	}
+	return nil
+}
+
+// chatValidationError maps a domain validation error to an application error.
+func chatValidationError(err error) error {
+	var validationErr *domain.ValidationError
+	if errors.As(err, &validationErr) {
+		return application.NewValidationError(validationErr.Message)
+	}
+	if errors.Is(err, domain.ErrTenantIDRequired) {
+		return application.NewInvalidInputError("tenant ID is required")
+	}
+	return application.NewValidationError(err.Error())
+}
+
+func toChatTriggers(triggers []string) []domain.ChatTrigger {
+	result := make([]domain.ChatTrigger, len(triggers))
+	for i, trigger := range triggers {
+		result[i] = domain.ChatTrigger(strings.ToLower(strings.TrimSpace(trigger)))
+	}
+	return result
+}
+
+// toChatIntegrationDTO maps an integration to its DTO.
+func toChatIntegrationDTO(integration *domain.ChatIntegration) *dto.ChatIntegrationDTO {
+	result := &dto.ChatIntegrationDTO{
+		ID:               integration.ID.String(),
+		Name:             integration.Name,
+		Platform:         string(integration.Platform),
+		Triggers:         make([]string, len(integration.Triggers)),
+		BigLeadThreshold: integration.BigLeadThreshold,
+		Templates:        make(map[string]dto.ChatTemplateDTO, len(integration.Templates)),
+		Enabled:          integration.Enabled,
+		LastPostedAt:     integration.LastPostedAt,
+		LastError:        integration.LastError,
+		CreatedAt:        integration.CreatedAt,
+		UpdatedAt:        integration.UpdatedAt,
+	}
+	if parsed, err := url.Parse(integration.WebhookURL); err == nil {
+		result.WebhookHost = parsed.Hostname()
+	}
+	for i, trigger := range integration.Triggers {
+		result.Triggers[i] = string(trigger)
+	}
+	for trigger, template := range integration.Templates {
+		result.Templates[string(trigger)] = dto.ChatTemplateDTO{Title: template.Title, Text: template.Text}
+	}
+	if integration.CreatedBy != nil {
+		result.CreatedBy = integration.CreatedBy.String()
+	}
+	return result
+}
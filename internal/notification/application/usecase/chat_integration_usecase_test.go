@@ -0,0 +1,274 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/kilang-desa-murni/crm/internal/notification/application"
+	"github.com/kilang-desa-murni/crm/internal/notification/application/dto"
+	"github.com/kilang-desa-murni/crm/internal/notification/application/ports"
+	"github.com/kilang-desa-murni/crm/internal/notification/domain"
+)
+
+// MockChatIntegrationRepository is a mock implementation of
+// domain.ChatIntegrationRepository.
+type MockChatIntegrationRepository struct {
+	mu           sync.RWMutex
+	integrations map[uuid.UUID]*domain.ChatIntegration
+}
+
+func NewMockChatIntegrationRepository() *MockChatIntegrationRepository {
+	return &MockChatIntegrationRepository{integrations: make(map[uuid.UUID]*domain.ChatIntegration)}
+}
+
+func (m *MockChatIntegrationRepository) Create(ctx context.Context, integration *domain.ChatIntegration) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	stored := *integration
+	m.integrations[integration.ID] = &stored
+	return nil
+}
+
+func (m *MockChatIntegrationRepository) Update(ctx context.Context, integration *domain.ChatIntegration) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if existing, ok := m.integrations[integration.ID]; !ok || existing.TenantID != integration.TenantID {
+		return domain.ErrChatIntegrationNotFound
+	}
+	stored := *integration
+	m.integrations[integration.ID] = &stored
+	return nil
+}
+
+func (m *MockChatIntegrationRepository) FindByID(ctx context.Context, tenantID, id uuid.UUID) (*domain.ChatIntegration, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	integration, ok := m.integrations[id]
+	if !ok || integration.TenantID != tenantID {
+		return nil, domain.ErrChatIntegrationNotFound
+	}
+	found := *integration
+	return &found, nil
+}
+
+func (m *MockChatIntegrationRepository) FindByTenant(ctx context.Context, tenantID uuid.UUID) ([]*domain.ChatIntegration, error) {
+	return m.find(func(integration *domain.ChatIntegration) bool { return integration.TenantID == tenantID }), nil
+}
+
+func (m *MockChatIntegrationRepository) FindEnabledByTrigger(ctx context.Context, tenantID uuid.UUID, trigger domain.ChatTrigger) ([]*domain.ChatIntegration, error) {
+	return m.find(func(integration *domain.ChatIntegration) bool {
+		return integration.TenantID == tenantID && integration.Enabled && integration.Subscribes(trigger)
+	}), nil
+}
+
+func (m *MockChatIntegrationRepository) find(match func(*domain.ChatIntegration) bool) []*domain.ChatIntegration {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	var integrations []*domain.ChatIntegration
+	for _, integration := range m.integrations {
+		if match(integration) {
+			found := *integration
+			integrations = append(integrations, &found)
+		}
+	}
+	sort.Slice(integrations, func(i, j int) bool { return integrations[i].Name < integrations[j].Name })
+	return integrations
+}
+
+func (m *MockChatIntegrationRepository) Delete(ctx context.Context, tenantID, id uuid.UUID) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if integration, ok := m.integrations[id]; !ok || integration.TenantID != tenantID {
+		return domain.ErrChatIntegrationNotFound
+	}
+	delete(m.integrations, id)
+	return nil
+}
+
+// MockChatProvider is a mock Slack and Teams provider.
+type MockChatProvider struct {
+	mu    sync.Mutex
+	slack []ports.SlackRequest
+	teams []ports.TeamsRequest
+	err   error
+}
+
+func (m *MockChatProvider) SendSlackMessage(ctx context.Context, request ports.SlackRequest) (*ports.SlackResponse, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.slack = append(m.slack, request)
+	if m.err != nil {
+		return &ports.SlackResponse{Status: "failed", ErrorMessage: m.err.Error()}, m.err
+	}
+	return &ports.SlackResponse{Status: "sent"}, nil
+}
+
+func (m *MockChatProvider) SendTeamsMessage(ctx context.Context, request ports.TeamsRequest) (*ports.TeamsResponse, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.teams = append(m.teams, request)
+	if m.err != nil {
+		return &ports.TeamsResponse{Status: "failed", ErrorMessage: m.err.Error()}, m.err
+	}
+	return &ports.TeamsResponse{Status: "sent"}, nil
+}
+
+func (m *MockChatProvider) GetProviderName() string              { return "mock-chat" }
+func (m *MockChatProvider) IsAvailable(ctx context.Context) bool { return true }
+
+func TestChatIntegrationUseCase_CreateAndPost(t *testing.T) {
+	repo := NewMockChatIntegrationRepository()
+	provider := &MockChatProvider{}
+	uc := NewChatIntegrationUseCase(repo, provider, provider)
+	ctx := context.Background()
+	tenantID := uuid.New()
+
+	slack, err := uc.CreateIntegration(ctx, &dto.CreateChatIntegrationRequest{
+		TenantID:         tenantID.String(),
+		Name:             "Sales wins",
+		Platform:         "slack",
+		WebhookURL:       "https://hooks.slack.com/services/T000/B000/XXXX",
+		Triggers:         []string{"opportunity_won", "big_lead"},
+		BigLeadThreshold: 50000,
+	})
+	if err != nil {
+		t.Fatalf("CreateIntegration failed: %v", err)
+	}
+	if slack.WebhookHost != "hooks.slack.com" || !slack.Enabled {
+		t.Errorf("unexpected integration: %+v", slack)
+	}
+	_, err = uc.CreateIntegration(ctx, &dto.CreateChatIntegrationRequest{
+		TenantID:   tenantID.String(),
+		Name:       "Teams finance",
+		Platform:   "teams",
+		WebhookURL: "https://contoso.webhook.office.com/webhookb2/abc",
+		Triggers:   []string{"opportunity_won", "invoice_overdue"},
+		Templates:  map[string]dto.ChatTemplateDTO{"opportunity_won": {Title: "Menang: {{.opportunity_code}}"}},
+	})
+	if err != nil {
+		t.Fatalf("CreateIntegration failed: %v", err)
+	}
+
+	won := &domain.ChatEvent{
+		TenantID: tenantID,
+		Trigger:  domain.ChatTriggerOpportunityWon,
+		Amount:   12500,
+		Currency: "MYR",
+		Vars:     map[string]interface{}{"opportunity_code": "OPP-42"},
+	}
+	if err := uc.Post(ctx, won); err != nil {
+		t.Fatalf("Post failed: %v", err)
+	}
+	if len(provider.slack) != 1 || len(provider.teams) != 1 {
+		t.Fatalf("expected one Slack and one Teams post, got %d and %d", len(provider.slack), len(provider.teams))
+	}
+	if !strings.Contains(provider.slack[0].Text, "Deal won: OPP-42") || len(provider.slack[0].Blocks) == 0 {
+		t.Errorf("unexpected Slack message: %+v", provider.slack[0])
+	}
+	if provider.teams[0].Text != "Menang: OPP-42: RM12,500.00 closed" || provider.teams[0].Card["type"] != "AdaptiveCard" {
+		t.Errorf("unexpected Teams message: %+v", provider.teams[0])
+	}
+
+	// Leads under the threshold are not posted
+	small := &domain.ChatEvent{TenantID: tenantID, Trigger: domain.ChatTriggerBigLead, Amount: 1000, Currency: "MYR"}
+	if err := uc.Post(ctx, small); err != nil {
+		t.Fatalf("Post failed: %v", err)
+	}
+	if len(provider.slack) != 1 {
+		t.Errorf("expected the small lead not to be posted, got %d Slack posts", len(provider.slack))
+	}
+
+	// Failures are recorded on the integration
+	provider.err = errors.New("slack error: channel_is_archived (status 410)")
+	if err := uc.Post(ctx, won); err == nil {
+		t.Error("expected Post to report the failed posts")
+	}
+	stored, _ := uc.GetIntegration(ctx, tenantID.String(), slack.ID)
+	if stored.LastError == "" || stored.LastPostedAt == nil {
+		t.Errorf("expected the failure recorded, got %+v", stored)
+	}
+}
+
+func TestChatIntegrationUseCase_TestIntegration(t *testing.T) {
+	repo := NewMockChatIntegrationRepository()
+	provider := &MockChatProvider{}
+	uc := NewChatIntegrationUseCase(repo, provider, provider)
+	ctx := context.Background()
+	tenantID := uuid.New().String()
+
+	created, err := uc.CreateIntegration(ctx, &dto.CreateChatIntegrationRequest{
+		TenantID:   tenantID,
+		Name:       "Alerts",
+		Platform:   "slack",
+		WebhookURL: "https://hooks.slack.com/services/T000/B000/XXXX",
+		Triggers:   []string{"invoice_overdue"},
+	})
+	if err != nil {
+		t.Fatalf("CreateIntegration failed: %v", err)
+	}
+	disabled := false
+	if _, err := uc.UpdateIntegration(ctx, &dto.UpdateChatIntegrationRequest{TenantID: tenantID, ID: created.ID, Enabled: &disabled}); err != nil {
+		t.Fatalf("UpdateIntegration failed: %v", err)
+	}
+
+	// Disabled integrations can still be tested
+	result, err := uc.TestIntegration(ctx, tenantID, created.ID)
+	if err != nil || !result.Success {
+		t.Fatalf("TestIntegration = %+v, %v, want success", result, err)
+	}
+	if len(provider.slack) != 1 || provider.slack[0].Text != "Integration connected: Alerts will post CRM alerts to this channel" {
+		t.Errorf("unexpected test message: %+v", provider.slack)
+	}
+
+	provider.err = errors.New("slack error: invalid_token (status 403)")
+	result, err = uc.TestIntegration(ctx, tenantID, created.ID)
+	if err != nil || result.Success || !strings.Contains(result.Error, "invalid_token") {
+		t.Errorf("TestIntegration = %+v, %v, want the failure reported", result, err)
+	}
+
+	// Integrations of other tenants are not found
+	_, err = uc.TestIntegration(ctx, uuid.New().String(), created.ID)
+	var appErr *application.AppError
+	if !errors.As(err, &appErr) || appErr.Code != application.ErrCodeNotFound {
+		t.Errorf("expected not found for another tenant, got %v", err)
+	}
+}
+
+func TestChatIntegrationUseCase_CreateValidation(t *testing.T) {
+	uc := NewChatIntegrationUseCase(NewMockChatIntegrationRepository(), &MockChatProvider{}, &MockChatProvider{})
+	ctx := context.Background()
+
+	tests := []struct {
+		name     string
+		platform string
+		url      string
+		triggers []string
+	}{
+		{"unknown platform", "discord", "https://hooks.slack.com/services/x", []string{"opportunity_won"}},
+		{"webhook of another host", "slack", "https://attacker.example.com/hook", []string{"opportunity_won"}},
+		{"teams webhook for slack", "slack", "https://contoso.webhook.office.com/webhookb2/abc", []string{"opportunity_won"}},
+		{"plain http", "slack", "http://hooks.slack.com/services/x", []string{"opportunity_won"}},
+		{"no triggers", "slack", "https://hooks.slack.com/services/x", nil},
+		{"unknown trigger", "slack", "https://hooks.slack.com/services/x", []string{"deal_lost"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := uc.CreateIntegration(ctx, &dto.CreateChatIntegrationRequest{
+				TenantID:   uuid.New().String(),
+				Name:       "Channel",
+				Platform:   tt.platform,
+				WebhookURL: tt.url,
+				Triggers:   tt.triggers,
+			})
+			var appErr *application.AppError
+			if !errors.As(err, &appErr) || appErr.Code != application.ErrCodeValidation {
+				t.Errorf("expected a validation error, got %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,116 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+
+	"github.com/kilang-desa-murni/crm/internal/notification/application"
+	"github.com/kilang-desa-murni/crm/internal/notification/application/dto"
+	"github.com/kilang-desa-murni/crm/internal/notification/domain"
+	"github.com/kilang-desa-murni/crm/pkg/demodata"
+)
+
+// DemoDataUseCase defines the interface for seeding a tenant's demo
+// notifications.
+type DemoDataUseCase interface {
+	// Seed creates the demo notifications of the tenant that do not exist yet.
+	Seed(ctx context.Context, tenantID uuid.UUID) (*dto.DemoDataResponse, error)
+	// Purge deletes the demo notifications of the tenant.
+	Purge(ctx context.Context, tenantID uuid.UUID) (*dto.DemoDataResponse, error)
+}
+
+// demoDataUseCase implements the DemoDataUseCase interface.
+type demoDataUseCase struct {
+	notificationRepo domain.NotificationRepository
+	demoRepo         domain.DemoDataRepository
+}
+
+// NewDemoDataUseCase creates a new demo data use case. Demo notifications
+// are stored as already delivered, so they are never queued for sending.
+func NewDemoDataUseCase(notificationRepo domain.NotificationRepository, demoRepo domain.DemoDataRepository) DemoDataUseCase {
+	return &demoDataUseCase{
+		notificationRepo: notificationRepo,
+		demoRepo:         demoRepo,
+	}
+}
+
+// Seed creates the demo notifications of the tenant that do not exist yet.
+func (uc *demoDataUseCase) Seed(ctx context.Context, tenantID uuid.UUID) (*dto.DemoDataResponse, error) {
+	ds := demodata.For(tenantID)
+
+	existing, err := uc.demoRepo.FindExisting(ctx, tenantID, notificationDemoIDs(ds))
+	if err != nil {
+		return nil, application.NewInternalError("failed to find demo notifications", err)
+	}
+
+	result := &dto.DemoDataResponse{}
+	for _, fixture := range ds.Notifications {
+		if existing[fixture.ID] {
+			result.Existing++
+			continue
+		}
+
+		notification, err := newDemoNotification(tenantID, fixture)
+		if err != nil {
+			return nil, application.NewInternalError("failed to build demo notification", err)
+		}
+		if err := uc.notificationRepo.Create(ctx, notification); err != nil {
+			return nil, application.NewInternalError("failed to create demo notification", err)
+		}
+		result.Created++
+	}
+
+	return result, nil
+}
+
+// Purge deletes the demo notifications of the tenant.
+func (uc *demoDataUseCase) Purge(ctx context.Context, tenantID uuid.UUID) (*dto.DemoDataResponse, error) {
+	deleted, err := uc.demoRepo.Purge(ctx, tenantID, notificationDemoIDs(demodata.For(tenantID)))
+	if err != nil {
+		return nil, application.NewInternalError("failed to purge demo notifications", err)
+	}
+	return &dto.DemoDataResponse{Deleted: deleted}, nil
+}
+
+// notificationDemoIDs returns the IDs of the demo notifications.
+func notificationDemoIDs(ds *demodata.DataSet) []uuid.UUID {
+	ids := make([]uuid.UUID, len(ds.Notifications))
+	for i, n := range ds.Notifications {
+		ids[i] = n.ID
+	}
+	return ids
+}
+
+// newDemoNotification builds a demo email notification that has been
+// delivered, giving it its demo ID.
+func newDemoNotification(tenantID uuid.UUID, fixture demodata.Notification) (*domain.Notification, error) {
+	n, err := domain.NewNotification(tenantID, domain.TypeTransactional, domain.ChannelEmail, fixture.Body)
+	if err != nil {
+		return nil, err
+	}
+	n.ID = fixture.ID
+
+	if err := n.SetRecipientEmail(fixture.RecipientEmail, fixture.RecipientName); err != nil {
+		return nil, err
+	}
+	if err := n.SetSubject(fixture.Subject); err != nil {
+		return nil, err
+	}
+	customerID := fixture.CustomerID
+	n.SetSourceEvent("", &customerID, "customer")
+
+	for _, step := range []func() error{
+		n.Queue,
+		n.MarkSending,
+		func() error { return n.MarkSent("demo") },
+		n.MarkDelivered,
+	} {
+		if err := step(); err != nil {
+			return nil, err
+		}
+	}
+	n.ClearDomainEvents()
+
+	return n, nil
+}
@@ -0,0 +1,77 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/kilang-desa-murni/crm/internal/notification/domain"
+	"github.com/kilang-desa-murni/crm/pkg/demodata"
+)
+
+// MockDemoDataRepository is a mock implementation of domain.DemoDataRepository
+// backed by a MockNotificationRepository.
+type MockDemoDataRepository struct {
+	notifications *MockNotificationRepository
+}
+
+func (m *MockDemoDataRepository) FindExisting(ctx context.Context, tenantID uuid.UUID, ids []uuid.UUID) (map[uuid.UUID]bool, error) {
+	existing := make(map[uuid.UUID]bool)
+	for _, id := range ids {
+		if n, ok := m.notifications.notifications[id]; ok && n.TenantID == tenantID {
+			existing[id] = true
+		}
+	}
+	return existing, nil
+}
+
+func (m *MockDemoDataRepository) Purge(ctx context.Context, tenantID uuid.UUID, ids []uuid.UUID) (int64, error) {
+	var deleted int64
+	for _, id := range ids {
+		if n, ok := m.notifications.notifications[id]; ok && n.TenantID == tenantID {
+			delete(m.notifications.notifications, id)
+			deleted++
+		}
+	}
+	return deleted, nil
+}
+
+func TestDemoDataUseCase_SeedAndPurge(t *testing.T) {
+	notifications := NewMockNotificationRepository()
+	uc := NewDemoDataUseCase(notifications, &MockDemoDataRepository{notifications: notifications})
+	tenantID := uuid.New()
+	ds := demodata.For(tenantID)
+
+	result, err := uc.Seed(context.Background(), tenantID)
+	if err != nil {
+		t.Fatalf("Seed() error = %v", err)
+	}
+	if result.Created != int64(len(ds.Notifications)) {
+		t.Errorf("Seed() created %d notifications, want %d", result.Created, len(ds.Notifications))
+	}
+	for _, fixture := range ds.Notifications {
+		n := notifications.notifications[fixture.ID]
+		if n == nil {
+			t.Fatalf("notification %q was not created", fixture.Subject)
+		}
+		if n.Status != domain.StatusDelivered {
+			t.Errorf("notification %q has status %s, want delivered so it is never sent", fixture.Subject, n.Status)
+		}
+	}
+
+	result, err = uc.Seed(context.Background(), tenantID)
+	if err != nil {
+		t.Fatalf("Seed() error = %v", err)
+	}
+	if result.Created != 0 || result.Existing != int64(len(ds.Notifications)) {
+		t.Errorf("second Seed() = %+v, want everything existing", result)
+	}
+
+	result, err = uc.Purge(context.Background(), tenantID)
+	if err != nil {
+		t.Fatalf("Purge() error = %v", err)
+	}
+	if result.Deleted != int64(len(ds.Notifications)) || len(notifications.notifications) != 0 {
+		t.Errorf("Purge() deleted %d notifications, %d left", result.Deleted, len(notifications.notifications))
+	}
+}
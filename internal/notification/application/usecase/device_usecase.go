@@ -0,0 +1,195 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/kilang-desa-murni/crm/internal/notification/application"
+	"github.com/kilang-desa-murni/crm/internal/notification/application/dto"
+	"github.com/kilang-desa-murni/crm/internal/notification/application/ports"
+	"github.com/kilang-desa-murni/crm/internal/notification/domain"
+)
+
+// DeviceUseCase defines the interface for registering the devices push
+// notifications are sent to.
+type DeviceUseCase interface {
+	// RegisterDevice registers a device of the calling user, or refreshes it
+	// when its token is already registered.
+	RegisterDevice(ctx context.Context, req *dto.RegisterDeviceRequest) (*dto.DeviceDTO, error)
+	// RegisterWebPushSubscription registers a browser push subscription of
+	// the calling user, or refreshes it when its endpoint is already registered.
+	RegisterWebPushSubscription(ctx context.Context, req *dto.RegisterWebPushSubscriptionRequest) (*dto.DeviceDTO, error)
+	// GetWebPushKey returns the VAPID public key browsers of the tenant
+	// subscribe with.
+	GetWebPushKey(ctx context.Context, tenantID string) (*dto.WebPushKeyDTO, error)
+	// ListDevices lists the devices of the calling user.
+	ListDevices(ctx context.Context, req *dto.ListDevicesRequest) ([]*dto.DeviceDTO, error)
+	// UnregisterDevice unregisters a device of the calling user.
+	UnregisterDevice(ctx context.Context, req *dto.UnregisterDeviceRequest) error
+}
+
+// deviceUseCase implements the DeviceUseCase interface.
+type deviceUseCase struct {
+	deviceRepo  domain.DeviceRepository
+	webPushKeys ports.WebPushKeys
+}
+
+// NewDeviceUseCase creates a new device use case. Devices whose tokens the
+// push provider rejects are unregistered by the notification use case.
+// webPushKeys may be nil when web push is not configured, in which case
+// browser subscriptions are refused.
+func NewDeviceUseCase(deviceRepo domain.DeviceRepository, webPushKeys ports.WebPushKeys) DeviceUseCase {
+	return &deviceUseCase{deviceRepo: deviceRepo, webPushKeys: webPushKeys}
+}
+
+// RegisterDevice registers a device of the calling user.
+func (uc *deviceUseCase) RegisterDevice(ctx context.Context, req *dto.RegisterDeviceRequest) (*dto.DeviceDTO, error) {
+	tenantID, userID, err := parseDeviceOwner(req.TenantID, req.UserID)
+	if err != nil {
+		return nil, err
+	}
+	platform := domain.DevicePlatform(strings.ToLower(strings.TrimSpace(req.Platform)))
+	if !domain.ValidPlatforms[platform] {
+		return nil, application.NewValidationError("platform must be ios, android or web")
+	}
+	if len(req.AppVersion) > 50 {
+		return nil, application.NewValidationError("app_version must be at most 50 characters")
+	}
+
+	device, err := domain.NewDevice(tenantID, userID, platform, req.Token, req.AppVersion)
+	if err != nil {
+		if errors.Is(err, domain.ErrDeviceTokenRequired) {
+			return nil, application.NewValidationError("token is required")
+		}
+		return nil, application.NewInvalidDeviceTokenError(req.Token)
+	}
+
+	stored, err := uc.deviceRepo.Register(ctx, device)
+	if err != nil {
+		return nil, application.NewInternalError("failed to register device", err)
+	}
+	return toDeviceDTO(stored), nil
+}
+
+// RegisterWebPushSubscription registers a browser push subscription of the
+// calling user.
+func (uc *deviceUseCase) RegisterWebPushSubscription(ctx context.Context, req *dto.RegisterWebPushSubscriptionRequest) (*dto.DeviceDTO, error) {
+	if uc.webPushKeys == nil {
+		return nil, application.NewChannelNotConfiguredError("web push")
+	}
+	tenantID, userID, err := parseDeviceOwner(req.TenantID, req.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if len(req.AppVersion) > 50 {
+		return nil, application.NewValidationError("app_version must be at most 50 characters")
+	}
+
+	var expiresAt *time.Time
+	if req.ExpirationTime != nil {
+		expires := time.UnixMilli(*req.ExpirationTime).UTC()
+		if !expires.After(time.Now()) {
+			return nil, application.NewValidationError("push subscription has already expired")
+		}
+		expiresAt = &expires
+	}
+
+	device, err := domain.NewWebPushDevice(tenantID, userID, req.Endpoint, req.Keys.P256DH, req.Keys.Auth, req.AppVersion, expiresAt)
+	if err != nil {
+		var validationErr *domain.ValidationError
+		if errors.As(err, &validationErr) {
+			return nil, application.NewValidationError(validationErr.Message)
+		}
+		return nil, application.NewValidationError(err.Error())
+	}
+
+	stored, err := uc.deviceRepo.Register(ctx, device)
+	if err != nil {
+		return nil, application.NewInternalError("failed to register push subscription", err)
+	}
+	return toDeviceDTO(stored), nil
+}
+
+// GetWebPushKey returns the VAPID public key browsers of the tenant
+// subscribe with.
+func (uc *deviceUseCase) GetWebPushKey(ctx context.Context, tenantID string) (*dto.WebPushKeyDTO, error) {
+	if uc.webPushKeys == nil {
+		return nil, application.NewChannelNotConfiguredError("web push")
+	}
+	if _, err := uuid.Parse(tenantID); err != nil {
+		return nil, application.NewInvalidInputError("invalid tenant ID format")
+	}
+	key, err := uc.webPushKeys.PublicKey(tenantID)
+	if err != nil {
+		return nil, application.NewInternalError("failed to load web push key", err)
+	}
+	return &dto.WebPushKeyDTO{PublicKey: key}, nil
+}
+
+// ListDevices lists the devices of the calling user, most recently seen first.
+func (uc *deviceUseCase) ListDevices(ctx context.Context, req *dto.ListDevicesRequest) ([]*dto.DeviceDTO, error) {
+	tenantID, userID, err := parseDeviceOwner(req.TenantID, req.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	devices, err := uc.deviceRepo.FindByUser(ctx, tenantID, userID)
+	if err != nil {
+		return nil, application.NewInternalError("failed to list devices", err)
+	}
+	result := make([]*dto.DeviceDTO, len(devices))
+	for i, device := range devices {
+		result[i] = toDeviceDTO(device)
+	}
+	return result, nil
+}
+
+// UnregisterDevice unregisters a device of the calling user.
+func (uc *deviceUseCase) UnregisterDevice(ctx context.Context, req *dto.UnregisterDeviceRequest) error {
+	tenantID, userID, err := parseDeviceOwner(req.TenantID, req.UserID)
+	if err != nil {
+		return err
+	}
+	deviceID, err := uuid.Parse(req.DeviceID)
+	if err != nil {
+		return application.NewInvalidInputError("invalid device ID format")
+	}
+
+	if err := uc.deviceRepo.Delete(ctx, tenantID, userID, deviceID); err != nil {
+		if errors.Is(err, domain.ErrDeviceNotFound) {
+			return application.NewNotFoundError("device", req.DeviceID)
+		}
+		return application.NewInternalError("failed to unregister device", err)
+	}
+	return nil
+}
+
+// parseDeviceOwner parses the tenant and user a device belongs to.
+func parseDeviceOwner(tenant, user string) (uuid.UUID, uuid.UUID, error) {
+	tenantID, err := uuid.Parse(tenant)
+	if err != nil {
+		return uuid.Nil, uuid.Nil, application.NewInvalidInputError("invalid tenant ID format")
+	}
+	userID, err := uuid.Parse(user)
+	if err != nil {
+		return uuid.Nil, uuid.Nil, application.NewInvalidInputError("invalid user ID format")
+	}
+	return tenantID, userID, nil
+}
+
+// toDeviceDTO maps a device to its DTO.
+func toDeviceDTO(device *domain.Device) *dto.DeviceDTO {
+	return &dto.DeviceDTO{
+		ID:         device.ID.String(),
+		UserID:     device.UserID.String(),
+		Platform:   string(device.Platform),
+		AppVersion: device.AppVersion,
+		ExpiresAt:  device.ExpiresAt,
+		CreatedAt:  device.CreatedAt,
+		LastSeenAt: device.LastSeenAt,
+	}
+}
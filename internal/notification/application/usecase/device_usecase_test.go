@@ -0,0 +1,241 @@
+package usecase
+
+import (
+	"context"
+	"crypto/ecdh"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/kilang-desa-murni/crm/internal/notification/application"
+	"github.com/kilang-desa-murni/crm/internal/notification/application/dto"
+	"github.com/kilang-desa-murni/crm/internal/notification/domain"
+)
+
+// MockDeviceRepository is a mock implementation of domain.DeviceRepository.
+type MockDeviceRepository struct {
+	mu      sync.RWMutex
+	devices map[string]*domain.Device // by token
+}
+
+func NewMockDeviceRepository() *MockDeviceRepository {
+	return &MockDeviceRepository{devices: make(map[string]*domain.Device)}
+}
+
+func (m *MockDeviceRepository) Register(ctx context.Context, device *domain.Device) (*domain.Device, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if existing, ok := m.devices[device.Token]; ok {
+		refreshed := *existing
+		refreshed.TenantID = device.TenantID
+		refreshed.UserID = device.UserID
+		refreshed.Platform = device.Platform
+		refreshed.AppVersion = device.AppVersion
+		refreshed.P256DH = device.P256DH
+		refreshed.Auth = device.Auth
+		refreshed.ExpiresAt = device.ExpiresAt
+		refreshed.LastSeenAt = device.LastSeenAt
+		m.devices[device.Token] = &refreshed
+		return &refreshed, nil
+	}
+	stored := *device
+	m.devices[device.Token] = &stored
+	return &stored, nil
+}
+
+func (m *MockDeviceRepository) FindByUser(ctx context.Context, tenantID, userID uuid.UUID) ([]*domain.Device, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	var devices []*domain.Device
+	for _, device := range m.devices {
+		if device.TenantID == tenantID && device.UserID == userID {
+			devices = append(devices, device)
+		}
+	}
+	sort.Slice(devices, func(i, j int) bool { return devices[i].Token < devices[j].Token })
+	return devices, nil
+}
+
+func (m *MockDeviceRepository) Delete(ctx context.Context, tenantID, userID, id uuid.UUID) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for token, device := range m.devices {
+		if device.ID == id && device.TenantID == tenantID && device.UserID == userID {
+			delete(m.devices, token)
+			return nil
+		}
+	}
+	return domain.ErrDeviceNotFound
+}
+
+func (m *MockDeviceRepository) DeleteByTokens(ctx context.Context, tenantID uuid.UUID, tokens []string) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var deleted int64
+	for _, token := range tokens {
+		if device, ok := m.devices[token]; ok && device.TenantID == tenantID {
+			delete(m.devices, token)
+			deleted++
+		}
+	}
+	return deleted, nil
+}
+
+func (m *MockDeviceRepository) DeleteExpired(ctx context.Context, now time.Time, limit int) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var deleted int64
+	for token, device := range m.devices {
+		if int(deleted) < limit && device.IsExpired(now) {
+			delete(m.devices, token)
+			deleted++
+		}
+	}
+	return deleted, nil
+}
+
+// staticWebPushKeys is a ports.WebPushKeys with a single key.
+type staticWebPushKeys string
+
+func (k staticWebPushKeys) PublicKey(tenantID string) (string, error) {
+	return string(k), nil
+}
+
+func TestDeviceUseCase_RegisterListUnregister(t *testing.T) {
+	repo := NewMockDeviceRepository()
+	uc := NewDeviceUseCase(repo, nil)
+	ctx := context.Background()
+
+	tenantID, userID := uuid.New().String(), uuid.New().String()
+	req := &dto.RegisterDeviceRequest{
+		TenantID:   tenantID,
+		UserID:     userID,
+		Token:      "fcm-token-1",
+		Platform:   "Android",
+		AppVersion: "2.4.0",
+	}
+	first, err := uc.RegisterDevice(ctx, req)
+	if err != nil {
+		t.Fatalf("RegisterDevice failed: %v", err)
+	}
+	if first.Platform != "android" || first.AppVersion != "2.4.0" {
+		t.Errorf("unexpected device: %+v", first)
+	}
+
+	// Registering the token again refreshes the device
+	req.AppVersion = "2.5.0"
+	again, err := uc.RegisterDevice(ctx, req)
+	if err != nil {
+		t.Fatalf("RegisterDevice failed on refresh: %v", err)
+	}
+	if again.ID != first.ID || again.AppVersion != "2.5.0" {
+		t.Errorf("expected device %s refreshed to 2.5.0, got %+v", first.ID, again)
+	}
+
+	devices, err := uc.ListDevices(ctx, &dto.ListDevicesRequest{TenantID: tenantID, UserID: userID})
+	if err != nil {
+		t.Fatalf("ListDevices failed: %v", err)
+	}
+	if len(devices) != 1 {
+		t.Fatalf("expected 1 device, got %d", len(devices))
+	}
+
+	// Another user cannot unregister the device
+	err = uc.UnregisterDevice(ctx, &dto.UnregisterDeviceRequest{TenantID: tenantID, UserID: uuid.New().String(), DeviceID: first.ID})
+	var appErr *application.AppError
+	if !errors.As(err, &appErr) || appErr.Code != application.ErrCodeNotFound {
+		t.Errorf("expected not found for another user's device, got %v", err)
+	}
+
+	if err := uc.UnregisterDevice(ctx, &dto.UnregisterDeviceRequest{TenantID: tenantID, UserID: userID, DeviceID: first.ID}); err != nil {
+		t.Fatalf("UnregisterDevice failed: %v", err)
+	}
+	if devices, _ := uc.ListDevices(ctx, &dto.ListDevicesRequest{TenantID: tenantID, UserID: userID}); len(devices) != 0 {
+		t.Errorf("expected no devices after unregistering, got %d", len(devices))
+	}
+}
+
+func TestDeviceUseCase_RegisterValidation(t *testing.T) {
+	uc := NewDeviceUseCase(NewMockDeviceRepository(), nil)
+	ctx := context.Background()
+	tenantID, userID := uuid.New().String(), uuid.New().String()
+
+	tests := []struct {
+		name     string
+		token    string
+		platform string
+	}{
+		{"missing token", "", "android"},
+		{"unknown platform", "fcm-token", "blackberry"},
+		{"short APNs token", "abc123", "ios"},
+		{"token with spaces", "fcm token", "android"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := uc.RegisterDevice(ctx, &dto.RegisterDeviceRequest{TenantID: tenantID, UserID: userID, Token: tt.token, Platform: tt.platform})
+			if err == nil {
+				t.Error("expected an error")
+			}
+		})
+	}
+}
+
+func TestDeviceUseCase_RegisterWebPushSubscription(t *testing.T) {
+	repo := NewMockDeviceRepository()
+	ctx := context.Background()
+	tenantID, userID := uuid.New().String(), uuid.New().String()
+
+	browserKey, err := ecdh.P256().GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("failed to generate browser key: %v", err)
+	}
+	expires := time.Now().Add(24 * time.Hour).UnixMilli()
+	req := &dto.RegisterWebPushSubscriptionRequest{
+		TenantID:       tenantID,
+		UserID:         userID,
+		Endpoint:       "https://fcm.googleapis.com/fcm/send/abc123",
+		ExpirationTime: &expires,
+		Keys: dto.WebPushSubscriptionKeys{
+			P256DH: base64.RawURLEncoding.EncodeToString(browserKey.PublicKey().Bytes()),
+			Auth:   base64.RawURLEncoding.EncodeToString(make([]byte, 16)),
+		},
+	}
+
+	// Browser subscriptions are refused until web push is configured
+	_, err = NewDeviceUseCase(repo, nil).RegisterWebPushSubscription(ctx, req)
+	var appErr *application.AppError
+	if !errors.As(err, &appErr) || appErr.Code != application.ErrCodeChannelNotConfigured {
+		t.Fatalf("expected channel not configured, got %v", err)
+	}
+
+	uc := NewDeviceUseCase(repo, staticWebPushKeys("public-key"))
+	device, err := uc.RegisterWebPushSubscription(ctx, req)
+	if err != nil {
+		t.Fatalf("RegisterWebPushSubscription failed: %v", err)
+	}
+	if device.Platform != "web" || device.ExpiresAt == nil || device.ExpiresAt.UnixMilli() != expires {
+		t.Errorf("unexpected subscription: %+v", device)
+	}
+
+	key, err := uc.GetWebPushKey(ctx, tenantID)
+	if err != nil || key.PublicKey != "public-key" {
+		t.Errorf("GetWebPushKey() = %+v, %v", key, err)
+	}
+
+	// Subscriptions without the browser's keys cannot be encrypted to
+	req.Keys.Auth = ""
+	if _, err := uc.RegisterWebPushSubscription(ctx, req); err == nil {
+		t.Error("expected an error for a subscription without an auth secret")
+	}
+
+	// Expired subscriptions are cleaned up
+	deleted, err := repo.DeleteExpired(ctx, time.Now().Add(48*time.Hour), 100)
+	if err != nil || deleted != 1 {
+		t.Errorf("DeleteExpired() = %d, %v, want the subscription removed", deleted, err)
+	}
+}
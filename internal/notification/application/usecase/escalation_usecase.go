@@ -0,0 +1,549 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/kilang-desa-murni/crm/internal/notification/application"
+	"github.com/kilang-desa-murni/crm/internal/notification/application/dto"
+	"github.com/kilang-desa-murni/crm/internal/notification/domain"
+)
+
+// maxAcknowledgeAttempts is how many times an acknowledgment is retried when
+// the escalation is escalated at the same moment.
+const maxAcknowledgeAttempts = 3
+
+// EscalationPager pages the audiences of an escalation level on its
+// channels. Audiences are users, and roles whose users are paged.
+type EscalationPager interface {
+	Page(ctx context.Context, escalation *domain.Escalation, audiences []domain.RoutingAudience, channels []domain.NotificationChannel) error
+}
+
+// EscalationPagerFunc adapts a function to an EscalationPager.
+type EscalationPagerFunc func(ctx context.Context, escalation *domain.Escalation, audiences []domain.RoutingAudience, channels []domain.NotificationChannel) error
+
+// Page calls f.
+func (f EscalationPagerFunc) Page(ctx context.Context, escalation *domain.Escalation, audiences []domain.RoutingAudience, channels []domain.NotificationChannel) error {
+	return f(ctx, escalation, audiences, channels)
+}
+
+// EscalationUseCase defines the interface for escalating critical alerts
+// through the on-call levels tenants configure until acknowledged.
+type EscalationUseCase interface {
+	// CreatePolicy adds an escalation policy to a tenant.
+	CreatePolicy(ctx context.Context, req *dto.CreateEscalationPolicyRequest) (*dto.EscalationPolicyDTO, error)
+	// UpdatePolicy updates a policy.
+	UpdatePolicy(ctx context.Context, req *dto.UpdateEscalationPolicyRequest) (*dto.EscalationPolicyDTO, error)
+	// GetPolicy gets a policy of a tenant.
+	GetPolicy(ctx context.Context, tenantID, id string) (*dto.EscalationPolicyDTO, error)
+	// ListPolicies lists the policies of a tenant.
+	ListPolicies(ctx context.Context, tenantID string) ([]*dto.EscalationPolicyDTO, error)
+	// DeletePolicy removes a policy.
+	DeletePolicy(ctx context.Context, tenantID, id string) error
+
+	// Escalate starts escalating an alert by every enabled policy of its
+	// tenant for its event type, paging their first level.
+	Escalate(ctx context.Context, event *domain.RoutedEvent) error
+	// Acknowledge stops an escalation.
+	Acknowledge(ctx context.Context, tenantID, id, userID string) (*dto.EscalationDTO, error)
+	// GetEscalation gets an escalation of a tenant.
+	GetEscalation(ctx context.Context, tenantID, id string) (*dto.EscalationDTO, error)
+	// ListEscalations lists the escalations of a tenant, newest first.
+	ListEscalations(ctx context.Context, req *dto.ListEscalationsRequest) (*dto.EscalationListDTO, error)
+	// ProcessDue pages the next level of up to limit escalations whose
+	// level timed out, returning how many were processed.
+	ProcessDue(ctx context.Context, limit int) (int, error)
+}
+
+// escalationUseCase implements the EscalationUseCase interface.
+type escalationUseCase struct {
+	policyRepo     domain.EscalationPolicyRepository
+	scheduleRepo   domain.OnCallScheduleRepository
+	escalationRepo domain.EscalationRepository
+	pager          EscalationPager
+	now            func() time.Time
+}
+
+// NewEscalationUseCase creates a new escalation use case.
+func NewEscalationUseCase(
+	policyRepo domain.EscalationPolicyRepository,
+	scheduleRepo domain.OnCallScheduleRepository,
+	escalationRepo domain.EscalationRepository,
+	pager EscalationPager,
+) EscalationUseCase {
+	return &escalationUseCase{
+		policyRepo:     policyRepo,
+		scheduleRepo:   scheduleRepo,
+		escalationRepo: escalationRepo,
+		pager:          pager,
+		now:            time.Now,
+	}
+}
+
+// CreatePolicy adds an escalation policy to a tenant.
+func (uc *escalationUseCase) CreatePolicy(ctx context.Context, req *dto.CreateEscalationPolicyRequest) (*dto.EscalationPolicyDTO, error) {
+	tenantID, err := uuid.Parse(req.TenantID)
+	if err != nil {
+		return nil, application.NewInvalidInputError("invalid tenant ID format")
+	}
+	var createdBy *uuid.UUID
+	if req.CreatedBy != "" {
+		userID, err := uuid.Parse(req.CreatedBy)
+		if err != nil {
+			return nil, application.NewInvalidInputError("invalid user ID format")
+		}
+		createdBy = &userID
+	}
+
+	levels := toEscalationLevels(req.Levels)
+	policy, err := domain.NewEscalationPolicy(tenantID, req.Name, req.EventTypes, levels, createdBy)
+	if err != nil {
+		return nil, routingValidationError(err)
+	}
+	if err := policy.SetMaxCycles(req.MaxCycles); err != nil {
+		return nil, routingValidationError(err)
+	}
+	if req.Enabled != nil && !*req.Enabled {
+		policy.Disable()
+	}
+	if err := uc.checkSchedules(ctx, tenantID, levels); err != nil {
+		return nil, err
+	}
+
+	if err := uc.policyRepo.Create(ctx, policy); err != nil {
+		return nil, application.NewInternalError("failed to create escalation policy", err)
+	}
+	return toEscalationPolicyDTO(policy), nil
+}
+
+// UpdatePolicy updates a policy. Alerts already escalating page the new
+// levels from their next escalation.
+func (uc *escalationUseCase) UpdatePolicy(ctx context.Context, req *dto.UpdateEscalationPolicyRequest) (*dto.EscalationPolicyDTO, error) {
+	policy, err := uc.findPolicy(ctx, req.TenantID, req.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Name != nil {
+		if err := policy.Rename(*req.Name); err != nil {
+			return nil, routingValidationError(err)
+		}
+	}
+	if req.EventTypes != nil {
+		if err := policy.SetEventTypes(req.EventTypes); err != nil {
+			return nil, routingValidationError(err)
+		}
+	}
+	if req.Levels != nil {
+		levels := toEscalationLevels(req.Levels)
+		if err := policy.SetLevels(levels); err != nil {
+			return nil, routingValidationError(err)
+		}
+		if err := uc.checkSchedules(ctx, policy.TenantID, levels); err != nil {
+			return nil, err
+		}
+	}
+	if req.MaxCycles != nil {
+		if err := policy.SetMaxCycles(*req.MaxCycles); err != nil {
+			return nil, routingValidationError(err)
+		}
+	}
+	if req.Enabled != nil {
+		if *req.Enabled {
+			policy.Enable()
+		} else {
+			policy.Disable()
+		}
+	}
+
+	if err := uc.policyRepo.Update(ctx, policy); err != nil {
+		if errors.Is(err, domain.ErrEscalationPolicyNotFound) {
+			return nil, application.NewNotFoundError("escalation policy", req.ID)
+		}
+		return nil, application.NewInternalError("failed to update escalation policy", err)
+	}
+	return toEscalationPolicyDTO(policy), nil
+}
+
+// GetPolicy gets a policy of a tenant.
+func (uc *escalationUseCase) GetPolicy(ctx context.Context, tenantID, id string) (*dto.EscalationPolicyDTO, error) {
+	policy, err := uc.findPolicy(ctx, tenantID, id)
+	if err != nil {
+		return nil, err
+	}
+	return toEscalationPolicyDTO(policy), nil
+}
+
+// ListPolicies lists the policies of a tenant.
+func (uc *escalationUseCase) ListPolicies(ctx context.Context, tenantID string) ([]*dto.EscalationPolicyDTO, error) {
+	tid, err := uuid.Parse(tenantID)
+	if err != nil {
+		return nil, application.NewInvalidInputError("invalid tenant ID format")
+	}
+	policies, err := uc.policyRepo.FindByTenant(ctx, tid)
+	if err != nil {
+		return nil, application.NewInternalError("failed to list escalation policies", err)
+	}
+	result := make([]*dto.EscalationPolicyDTO, len(policies))
+	for i, policy := range policies {
+		result[i] = toEscalationPolicyDTO(policy)
+	}
+	return result, nil
+}
+
+// DeletePolicy removes a policy. Alerts it was escalating stop being paged
+// when next due.
+func (uc *escalationUseCase) DeletePolicy(ctx context.Context, tenantID, id string) error {
+	tid, pid, err := parseEscalationID(tenantID, id, "escalation policy")
+	if err != nil {
+		return err
+	}
+	if err := uc.policyRepo.Delete(ctx, tid, pid); err != nil {
+		if errors.Is(err, domain.ErrEscalationPolicyNotFound) {
+			return application.NewNotFoundError("escalation policy", id)
+		}
+		return application.NewInternalError("failed to delete escalation policy", err)
+	}
+	return nil
+}
+
+// Escalate starts escalating an alert by every enabled policy of its tenant
+// for its event type. An alert a policy already escalates, such as a
+// redelivered event, is left alone. A first level that cannot be paged is
+// paged again by ProcessDue.
+func (uc *escalationUseCase) Escalate(ctx context.Context, event *domain.RoutedEvent) error {
+	if !domain.IsEscalatableEventType(event.Type) {
+		return nil
+	}
+	policies, err := uc.policyRepo.FindByTenant(ctx, event.TenantID)
+	if err != nil {
+		return application.NewInternalError("failed to find escalation policies", err)
+	}
+
+	for _, policy := range policies {
+		if !policy.Applies(event.Type) {
+			continue
+		}
+		escalation := domain.StartEscalation(policy, event.ID, event.Type, event.Data, uc.now())
+		if err := uc.escalationRepo.Create(ctx, escalation); err != nil {
+			if errors.Is(err, domain.ErrEscalationExists) {
+				continue
+			}
+			return application.NewInternalError("failed to start escalation", err)
+		}
+		if err := uc.escalate(ctx, escalation, policy); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Acknowledge stops an escalation. Acknowledging while the escalation is
+// being escalated is retried, so it is not lost.
+func (uc *escalationUseCase) Acknowledge(ctx context.Context, tenantID, id, userID string) (*dto.EscalationDTO, error) {
+	uid, err := uuid.Parse(userID)
+	if err != nil {
+		return nil, application.NewInvalidInputError("invalid user ID format")
+	}
+
+	for attempt := 1; ; attempt++ {
+		escalation, err := uc.findEscalation(ctx, tenantID, id)
+		if err != nil {
+			return nil, err
+		}
+		if err := escalation.Acknowledge(uid, uc.now()); err != nil {
+			return nil, application.NewConflictError(err.Error())
+		}
+
+		err = uc.escalationRepo.Update(ctx, escalation)
+		if err == nil {
+			return toEscalationDTO(escalation), nil
+		}
+		if !errors.Is(err, domain.ErrConcurrentModification) || attempt == maxAcknowledgeAttempts {
+			return nil, application.NewInternalError("failed to acknowledge escalation", err)
+		}
+	}
+}
+
+// GetEscalation gets an escalation of a tenant.
+func (uc *escalationUseCase) GetEscalation(ctx context.Context, tenantID, id string) (*dto.EscalationDTO, error) {
+	escalation, err := uc.findEscalation(ctx, tenantID, id)
+	if err != nil {
+		return nil, err
+	}
+	return toEscalationDTO(escalation), nil
+}
+
+// ListEscalations lists the escalations of a tenant, newest first.
+func (uc *escalationUseCase) ListEscalations(ctx context.Context, req *dto.ListEscalationsRequest) (*dto.EscalationListDTO, error) {
+	tenantID, err := uuid.Parse(req.TenantID)
+	if err != nil {
+		return nil, application.NewInvalidInputError("invalid tenant ID format")
+	}
+	status := domain.EscalationStatus(strings.ToLower(req.Status))
+	switch status {
+	case "", domain.EscalationStatusOpen, domain.EscalationStatusAcknowledged, domain.EscalationStatusExhausted:
+	default:
+		return nil, application.NewValidationError("status must be open, acknowledged or exhausted")
+	}
+
+	page, pageSize := req.Page, req.PageSize
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 || pageSize > 100 {
+		pageSize = 20
+	}
+
+	escalations, total, err := uc.escalationRepo.FindByTenant(ctx, tenantID, status, (page-1)*pageSize, pageSize)
+	if err != nil {
+		return nil, application.NewInternalError("failed to list escalations", err)
+	}
+	result := &dto.EscalationListDTO{
+		Items:      make([]*dto.EscalationDTO, len(escalations)),
+		TotalCount: total,
+		Page:       page,
+		PageSize:   pageSize,
+		TotalPages: int((total + int64(pageSize) - 1) / int64(pageSize)),
+	}
+	for i, escalation := range escalations {
+		result.Items[i] = toEscalationDTO(escalation)
+	}
+	return result, nil
+}
+
+// ProcessDue pages the next level of up to limit escalations whose level
+// timed out, or the level of those whose page failed. An escalation whose
+// policy was removed is exhausted. A failure to page one escalation does not
+// hold up the others; the first is returned.
+func (uc *escalationUseCase) ProcessDue(ctx context.Context, limit int) (int, error) {
+	escalations, err := uc.escalationRepo.FindDue(ctx, uc.now(), limit)
+	if err != nil {
+		return 0, err
+	}
+
+	var firstErr error
+	for _, escalation := range escalations {
+		policy, err := uc.policyRepo.FindByID(ctx, escalation.TenantID, escalation.PolicyID)
+		if errors.Is(err, domain.ErrEscalationPolicyNotFound) {
+			// An empty policy exhausts the escalation
+			policy = &domain.EscalationPolicy{ID: escalation.PolicyID, TenantID: escalation.TenantID}
+		} else if err != nil {
+			if firstErr == nil {
+				firstErr = err
+			}
+			continue
+		}
+		if err := uc.escalate(ctx, escalation, policy); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return len(escalations), firstErr
+}
+
+// escalate moves an escalation on and pages the level it reached. The
+// escalation is stored before paging, so an instance escalating it at the
+// same time, or an acknowledgment, wins rather than paging twice; a page
+// that fails is stored as due again.
+func (uc *escalationUseCase) escalate(ctx context.Context, escalation *domain.Escalation, policy *domain.EscalationPolicy) error {
+	level, ok := escalation.Escalate(policy, uc.now())
+	if err := uc.escalationRepo.Update(ctx, escalation); err != nil {
+		if errors.Is(err, domain.ErrConcurrentModification) {
+			return nil
+		}
+		return application.NewInternalError("failed to escalate", err)
+	}
+	if !ok {
+		return nil
+	}
+
+	audiences := uc.resolveTargets(ctx, escalation.TenantID, level.Targets)
+	if err := uc.pager.Page(ctx, escalation, audiences, level.Channels); err != nil {
+		escalation.PageFailed(uc.now())
+		if updateErr := uc.escalationRepo.Update(ctx, escalation); updateErr != nil && !errors.Is(updateErr, domain.ErrConcurrentModification) {
+			return application.NewInternalError("failed to escalate", updateErr)
+		}
+		return err
+	}
+	return nil
+}
+
+// resolveTargets resolves the targets of a level to the users and roles
+// paged: schedules to whoever is on call. Schedules that were removed or
+// have no one on call page no one.
+func (uc *escalationUseCase) resolveTargets(ctx context.Context, tenantID uuid.UUID, targets []domain.EscalationTarget) []domain.RoutingAudience {
+	audiences := make([]domain.RoutingAudience, 0, len(targets))
+	seen := make(map[domain.RoutingAudience]bool, len(targets))
+	add := func(audience domain.RoutingAudience) {
+		if !seen[audience] {
+			seen[audience] = true
+			audiences = append(audiences, audience)
+		}
+	}
+
+	for _, target := range targets {
+		switch target.Type {
+		case domain.EscalationTargetUser:
+			add(domain.RoutingAudience{Type: domain.RoutingAudienceUser, Value: target.Value})
+		case domain.EscalationTargetRole:
+			add(domain.RoutingAudience{Type: domain.RoutingAudienceRole, Value: target.Value})
+		case domain.EscalationTargetSchedule:
+			scheduleID, err := uuid.Parse(target.Value)
+			if err != nil {
+				continue
+			}
+			schedule, err := uc.scheduleRepo.FindByID(ctx, tenantID, scheduleID)
+			if err != nil {
+				continue
+			}
+			if userID, ok := schedule.OnCall(uc.now()); ok {
+				add(domain.RoutingAudience{Type: domain.RoutingAudienceUser, Value: userID.String()})
+			}
+		}
+	}
+	return audiences
+}
+
+// checkSchedules checks that the schedules the levels page are schedules of
+// the tenant.
+func (uc *escalationUseCase) checkSchedules(ctx context.Context, tenantID uuid.UUID, levels []domain.EscalationLevel) error {
+	for _, level := range levels {
+		for _, target := range level.Targets {
+			if target.Type != domain.EscalationTargetSchedule {
+				continue
+			}
+			scheduleID, err := uuid.Parse(target.Value)
+			if err != nil {
+				return application.NewValidationError("schedule target must name an ID")
+			}
+			if _, err := uc.scheduleRepo.FindByID(ctx, tenantID, scheduleID); err != nil {
+				if errors.Is(err, domain.ErrOnCallScheduleNotFound) {
+					return application.NewValidationError("unknown on-call schedule " + target.Value)
+				}
+				return application.NewInternalError("failed to find on-call schedule", err)
+			}
+		}
+	}
+	return nil
+}
+
+// findPolicy loads a policy of a tenant.
+func (uc *escalationUseCase) findPolicy(ctx context.Context, tenantID, id string) (*domain.EscalationPolicy, error) {
+	tid, pid, err := parseEscalationID(tenantID, id, "escalation policy")
+	if err != nil {
+		return nil, err
+	}
+	policy, err := uc.policyRepo.FindByID(ctx, tid, pid)
+	if err != nil {
+		if errors.Is(err, domain.ErrEscalationPolicyNotFound) {
+			return nil, application.NewNotFoundError("escalation policy", id)
+		}
+		return nil, application.NewInternalError("failed to find escalation policy", err)
+	}
+	return policy, nil
+}
+
+// findEscalation loads an escalation of a tenant.
+func (uc *escalationUseCase) findEscalation(ctx context.Context, tenantID, id string) (*domain.Escalation, error) {
+	tid, eid, err := parseEscalationID(tenantID, id, "escalation")
+	if err != nil {
+		return nil, err
+	}
+	escalation, err := uc.escalationRepo.FindByID(ctx, tid, eid)
+	if err != nil {
+		if errors.Is(err, domain.ErrEscalationNotFound) {
+			return nil, application.NewNotFoundError("escalation", id)
+		}
+		return nil, application.NewInternalError("failed to find escalation", err)
+	}
+	return escalation, nil
+}
+
+// parseEscalationID parses the tenant and ID of a policy, schedule or
+// escalation.
+func parseEscalationID(tenantID, id, resource string) (uuid.UUID, uuid.UUID, error) {
+	tid, err := uuid.Parse(tenantID)
+	if err != nil {
+		return uuid.Nil, uuid.Nil, application.NewInvalidInputError("invalid tenant ID format")
+	}
+	rid, err := uuid.Parse(id)
+	if err != nil {
+		return uuid.Nil, uuid.Nil, application.NewInvalidInputError("invalid " + resource + " ID format")
+	}
+	return tid, rid, nil
+}
+
+func toEscalationLevels(levels []dto.EscalationLevelDTO) []domain.EscalationLevel {
+	result := make([]domain.EscalationLevel, len(levels))
+	for i, level := range levels {
+		targets := make([]domain.EscalationTarget, len(level.Targets))
+		for j, target := range level.Targets {
+			targets[j] = domain.EscalationTarget{
+				Type:  domain.EscalationTargetType(strings.ToLower(strings.TrimSpace(target.Type))),
+				Value: strings.TrimSpace(target.Value),
+			}
+		}
+		result[i] = domain.EscalationLevel{
+			Targets:        targets,
+			Channels:       toRoutingChannels(level.Channels),
+			TimeoutMinutes: level.TimeoutMinutes,
+		}
+	}
+	return result
+}
+
+// toEscalationPolicyDTO maps a policy to its DTO.
+func toEscalationPolicyDTO(policy *domain.EscalationPolicy) *dto.EscalationPolicyDTO {
+	result := &dto.EscalationPolicyDTO{
+		ID:         policy.ID.String(),
+		Name:       policy.Name,
+		EventTypes: policy.EventTypes,
+		Levels:     make([]dto.EscalationLevelDTO, len(policy.Levels)),
+		MaxCycles:  policy.MaxCycles,
+		Enabled:    policy.Enabled,
+		CreatedAt:  policy.CreatedAt,
+		UpdatedAt:  policy.UpdatedAt,
+	}
+	for i, level := range policy.Levels {
+		targets := make([]dto.EscalationTargetDTO, len(level.Targets))
+		for j, target := range level.Targets {
+			targets[j] = dto.EscalationTargetDTO{Type: string(target.Type), Value: target.Value}
+		}
+		channels := make([]string, len(level.Channels))
+		for j, channel := range level.Channels {
+			channels[j] = string(channel)
+		}
+		result.Levels[i] = dto.EscalationLevelDTO{Targets: targets, Channels: channels, TimeoutMinutes: level.TimeoutMinutes}
+	}
+	if policy.CreatedBy != nil {
+		result.CreatedBy = policy.CreatedBy.String()
+	}
+	return result
+}
+
+// toEscalationDTO maps an escalation to its DTO. Levels count from 1.
+func toEscalationDTO(escalation *domain.Escalation) *dto.EscalationDTO {
+	result := &dto.EscalationDTO{
+		ID:               escalation.ID.String(),
+		PolicyID:         escalation.PolicyID.String(),
+		EventID:          escalation.EventID,
+		EventType:        escalation.EventType,
+		Data:             escalation.Data,
+		Level:            escalation.Level + 1,
+		Cycle:            escalation.Cycle,
+		Status:           string(escalation.Status),
+		PagedAt:          escalation.PagedAt,
+		NextEscalationAt: escalation.NextEscalationAt,
+		AcknowledgedAt:   escalation.AcknowledgedAt,
+		CreatedAt:        escalation.CreatedAt,
+		UpdatedAt:        escalation.UpdatedAt,
+	}
+	if escalation.AcknowledgedBy != nil {
+		result.AcknowledgedBy = escalation.AcknowledgedBy.String()
+	}
+	return result
+}
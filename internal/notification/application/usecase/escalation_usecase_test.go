@@ -0,0 +1,418 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/kilang-desa-murni/crm/internal/notification/application"
+	"github.com/kilang-desa-murni/crm/internal/notification/application/dto"
+	"github.com/kilang-desa-murni/crm/internal/notification/domain"
+)
+
+// MockEscalationPolicyRepository is a mock implementation of
+// domain.EscalationPolicyRepository.
+type MockEscalationPolicyRepository struct {
+	mu       sync.RWMutex
+	policies map[uuid.UUID]*domain.EscalationPolicy
+}
+
+func NewMockEscalationPolicyRepository() *MockEscalationPolicyRepository {
+	return &MockEscalationPolicyRepository{policies: make(map[uuid.UUID]*domain.EscalationPolicy)}
+}
+
+func (m *MockEscalationPolicyRepository) Create(ctx context.Context, policy *domain.EscalationPolicy) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	stored := *policy
+	m.policies[policy.ID] = &stored
+	return nil
+}
+
+func (m *MockEscalationPolicyRepository) Update(ctx context.Context, policy *domain.EscalationPolicy) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if existing, ok := m.policies[policy.ID]; !ok || existing.TenantID != policy.TenantID {
+		return domain.ErrEscalationPolicyNotFound
+	}
+	stored := *policy
+	m.policies[policy.ID] = &stored
+	return nil
+}
+
+func (m *MockEscalationPolicyRepository) FindByID(ctx context.Context, tenantID, id uuid.UUID) (*domain.EscalationPolicy, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	policy, ok := m.policies[id]
+	if !ok || policy.TenantID != tenantID {
+		return nil, domain.ErrEscalationPolicyNotFound
+	}
+	found := *policy
+	return &found, nil
+}
+
+func (m *MockEscalationPolicyRepository) FindByTenant(ctx context.Context, tenantID uuid.UUID) ([]*domain.EscalationPolicy, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	var policies []*domain.EscalationPolicy
+	for _, policy := range m.policies {
+		if policy.TenantID == tenantID {
+			found := *policy
+			policies = append(policies, &found)
+		}
+	}
+	return policies, nil
+}
+
+func (m *MockEscalationPolicyRepository) Delete(ctx context.Context, tenantID, id uuid.UUID) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if policy, ok := m.policies[id]; !ok || policy.TenantID != tenantID {
+		return domain.ErrEscalationPolicyNotFound
+	}
+	delete(m.policies, id)
+	return nil
+}
+
+// MockOnCallScheduleRepository is a mock implementation of
+// domain.OnCallScheduleRepository.
+type MockOnCallScheduleRepository struct {
+	mu        sync.RWMutex
+	schedules map[uuid.UUID]*domain.OnCallSchedule
+}
+
+func NewMockOnCallScheduleRepository() *MockOnCallScheduleRepository {
+	return &MockOnCallScheduleRepository{schedules: make(map[uuid.UUID]*domain.OnCallSchedule)}
+}
+
+func (m *MockOnCallScheduleRepository) Create(ctx context.Context, schedule *domain.OnCallSchedule) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	stored := *schedule
+	m.schedules[schedule.ID] = &stored
+	return nil
+}
+
+func (m *MockOnCallScheduleRepository) Update(ctx context.Context, schedule *domain.OnCallSchedule) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if existing, ok := m.schedules[schedule.ID]; !ok || existing.TenantID != schedule.TenantID {
+		return domain.ErrOnCallScheduleNotFound
+	}
+	stored := *schedule
+	m.schedules[schedule.ID] = &stored
+	return nil
+}
+
+func (m *MockOnCallScheduleRepository) FindByID(ctx context.Context, tenantID, id uuid.UUID) (*domain.OnCallSchedule, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	schedule, ok := m.schedules[id]
+	if !ok || schedule.TenantID != tenantID {
+		return nil, domain.ErrOnCallScheduleNotFound
+	}
+	found := *schedule
+	return &found, nil
+}
+
+func (m *MockOnCallScheduleRepository) FindByTenant(ctx context.Context, tenantID uuid.UUID) ([]*domain.OnCallSchedule, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	var schedules []*domain.OnCallSchedule
+	for _, schedule := range m.schedules {
+		if schedule.TenantID == tenantID {
+			found := *schedule
+			schedules = append(schedules, &found)
+		}
+	}
+	return schedules, nil
+}
+
+func (m *MockOnCallScheduleRepository) Delete(ctx context.Context, tenantID, id uuid.UUID) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if schedule, ok := m.schedules[id]; !ok || schedule.TenantID != tenantID {
+		return domain.ErrOnCallScheduleNotFound
+	}
+	delete(m.schedules, id)
+	return nil
+}
+
+// MockEscalationRepository is a mock implementation of
+// domain.EscalationRepository.
+type MockEscalationRepository struct {
+	mu          sync.RWMutex
+	escalations map[uuid.UUID]*domain.Escalation
+}
+
+func NewMockEscalationRepository() *MockEscalationRepository {
+	return &MockEscalationRepository{escalations: make(map[uuid.UUID]*domain.Escalation)}
+}
+
+func (m *MockEscalationRepository) Create(ctx context.Context, escalation *domain.Escalation) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, existing := range m.escalations {
+		if existing.PolicyID == escalation.PolicyID && existing.EventID == escalation.EventID {
+			return domain.ErrEscalationExists
+		}
+	}
+	stored := *escalation
+	m.escalations[escalation.ID] = &stored
+	return nil
+}
+
+func (m *MockEscalationRepository) Update(ctx context.Context, escalation *domain.Escalation) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	existing, ok := m.escalations[escalation.ID]
+	if !ok || existing.TenantID != escalation.TenantID {
+		return domain.ErrEscalationNotFound
+	}
+	if existing.Version != escalation.Version {
+		return domain.ErrConcurrentModification
+	}
+	escalation.Version++
+	stored := *escalation
+	m.escalations[escalation.ID] = &stored
+	return nil
+}
+
+func (m *MockEscalationRepository) FindByID(ctx context.Context, tenantID, id uuid.UUID) (*domain.Escalation, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	escalation, ok := m.escalations[id]
+	if !ok || escalation.TenantID != tenantID {
+		return nil, domain.ErrEscalationNotFound
+	}
+	found := *escalation
+	return &found, nil
+}
+
+func (m *MockEscalationRepository) FindByTenant(ctx context.Context, tenantID uuid.UUID, status domain.EscalationStatus, offset, limit int) ([]*domain.Escalation, int64, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	var escalations []*domain.Escalation
+	for _, escalation := range m.escalations {
+		if escalation.TenantID == tenantID && (status == "" || escalation.Status == status) {
+			found := *escalation
+			escalations = append(escalations, &found)
+		}
+	}
+	sort.Slice(escalations, func(i, j int) bool { return escalations[i].CreatedAt.After(escalations[j].CreatedAt) })
+	total := int64(len(escalations))
+	if offset >= len(escalations) {
+		return nil, total, nil
+	}
+	escalations = escalations[offset:]
+	if len(escalations) > limit {
+		escalations = escalations[:limit]
+	}
+	return escalations, total, nil
+}
+
+func (m *MockEscalationRepository) FindDue(ctx context.Context, before time.Time, limit int) ([]*domain.Escalation, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	var escalations []*domain.Escalation
+	for _, escalation := range m.escalations {
+		if escalation.Status == domain.EscalationStatusOpen && escalation.NextEscalationAt != nil && !escalation.NextEscalationAt.After(before) {
+			found := *escalation
+			escalations = append(escalations, &found)
+		}
+	}
+	if len(escalations) > limit {
+		escalations = escalations[:limit]
+	}
+	return escalations, nil
+}
+
+// recordingPager records the pages sent, failing while Err is set.
+type recordingPager struct {
+	mu    sync.Mutex
+	pages [][]domain.RoutingAudience
+	Err   error
+}
+
+func (p *recordingPager) Page(ctx context.Context, escalation *domain.Escalation, audiences []domain.RoutingAudience, channels []domain.NotificationChannel) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.Err != nil {
+		return p.Err
+	}
+	p.pages = append(p.pages, audiences)
+	return nil
+}
+
+type escalationTest struct {
+	uc        *escalationUseCase
+	pager     *recordingPager
+	schedules *MockOnCallScheduleRepository
+	now       time.Time
+	tenantID  uuid.UUID
+}
+
+func newEscalationTest() *escalationTest {
+	et := &escalationTest{
+		pager:     &recordingPager{},
+		schedules: NewMockOnCallScheduleRepository(),
+		now:       time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC),
+		tenantID:  uuid.New(),
+	}
+	et.uc = NewEscalationUseCase(NewMockEscalationPolicyRepository(), et.schedules, NewMockEscalationRepository(), et.pager).(*escalationUseCase)
+	et.uc.now = func() time.Time { return et.now }
+	return et
+}
+
+func TestEscalationUseCase_CreatePolicy(t *testing.T) {
+	ctx := context.Background()
+	et := newEscalationTest()
+
+	policy, err := et.uc.CreatePolicy(ctx, &dto.CreateEscalationPolicyRequest{
+		TenantID:   et.tenantID.String(),
+		Name:       "Billing",
+		EventTypes: []string{"billing.payment.failed"},
+		Levels: []dto.EscalationLevelDTO{
+			{Targets: []dto.EscalationTargetDTO{{Type: "role", Value: "finance"}}, Channels: []string{"SMS"}, TimeoutMinutes: 10},
+		},
+		MaxCycles: 3,
+	})
+	if err != nil {
+		t.Fatalf("CreatePolicy() error = %v", err)
+	}
+	if !policy.Enabled || policy.MaxCycles != 3 || policy.Levels[0].Channels[0] != "sms" {
+		t.Errorf("CreatePolicy() = %+v", policy)
+	}
+
+	invalid := []*dto.CreateEscalationPolicyRequest{
+		{TenantID: et.tenantID.String(), Name: "Leads", EventTypes: []string{"sales.lead.created"},
+			Levels: []dto.EscalationLevelDTO{{Targets: []dto.EscalationTargetDTO{{Type: "role", Value: "sales"}}, Channels: []string{"sms"}, TimeoutMinutes: 10}}},
+		{TenantID: et.tenantID.String(), Name: "Unknown schedule", EventTypes: []string{"platform.sla.breached"},
+			Levels: []dto.EscalationLevelDTO{{Targets: []dto.EscalationTargetDTO{{Type: "schedule", Value: uuid.NewString()}}, Channels: []string{"sms"}, TimeoutMinutes: 10}}},
+		{TenantID: et.tenantID.String(), Name: "Forever", EventTypes: []string{"platform.sla.breached"}, MaxCycles: -1,
+			Levels: []dto.EscalationLevelDTO{{Targets: []dto.EscalationTargetDTO{{Type: "role", Value: "ops"}}, Channels: []string{"sms"}, TimeoutMinutes: 10}}},
+	}
+	for _, req := range invalid {
+		_, err := et.uc.CreatePolicy(ctx, req)
+		var appErr *application.AppError
+		if !errors.As(err, &appErr) || appErr.Code != application.ErrCodeValidation {
+			t.Errorf("CreatePolicy(%s) error = %v, want a validation error", req.Name, err)
+		}
+	}
+}
+
+func TestEscalationUseCase_EscalateUntilAcknowledged(t *testing.T) {
+	ctx := context.Background()
+	et := newEscalationTest()
+	primary, manager := uuid.New(), uuid.New()
+
+	schedule, _ := domain.NewOnCallSchedule(et.tenantID, "Primary", []uuid.UUID{primary}, et.now.Add(-time.Hour), 24)
+	et.schedules.Create(ctx, schedule)
+
+	if _, err := et.uc.CreatePolicy(ctx, &dto.CreateEscalationPolicyRequest{
+		TenantID:   et.tenantID.String(),
+		Name:       "Payments",
+		EventTypes: []string{domain.EscalationEventPaymentFailed},
+		Levels: []dto.EscalationLevelDTO{
+			{Targets: []dto.EscalationTargetDTO{{Type: "schedule", Value: schedule.ID.String()}}, Channels: []string{"sms", "push"}, TimeoutMinutes: 5},
+			{Targets: []dto.EscalationTargetDTO{{Type: "user", Value: manager.String()}}, Channels: []string{"sms"}, TimeoutMinutes: 15},
+		},
+	}); err != nil {
+		t.Fatalf("CreatePolicy() error = %v", err)
+	}
+
+	event := &domain.RoutedEvent{ID: "evt-1", TenantID: et.tenantID, Type: domain.EscalationEventPaymentFailed, Data: map[string]interface{}{"invoice_id": "INV-7"}}
+	if err := et.uc.Escalate(ctx, event); err != nil {
+		t.Fatalf("Escalate() error = %v", err)
+	}
+	// A redelivered alert is not escalated twice
+	if err := et.uc.Escalate(ctx, event); err != nil {
+		t.Fatalf("Escalate() error = %v", err)
+	}
+	if len(et.pager.pages) != 1 || et.pager.pages[0][0].Value != primary.String() {
+		t.Fatalf("pages = %+v, want whoever is on call paged once", et.pager.pages)
+	}
+
+	// Level 1 has five minutes to acknowledge
+	et.now = et.now.Add(4 * time.Minute)
+	if n, _ := et.uc.ProcessDue(ctx, 10); n != 0 {
+		t.Errorf("ProcessDue() = %d before the timeout, want 0", n)
+	}
+	et.now = et.now.Add(time.Minute)
+	if n, err := et.uc.ProcessDue(ctx, 10); n != 1 || err != nil {
+		t.Fatalf("ProcessDue() = %d, %v, want 1", n, err)
+	}
+	if len(et.pager.pages) != 2 || et.pager.pages[1][0].Value != manager.String() {
+		t.Fatalf("pages = %+v, want the manager paged at level 2", et.pager.pages)
+	}
+
+	// After the last level the first is paged again
+	et.now = et.now.Add(15 * time.Minute)
+	et.uc.ProcessDue(ctx, 10)
+	if len(et.pager.pages) != 3 || et.pager.pages[2][0].Value != primary.String() {
+		t.Fatalf("pages = %+v, want level 1 paged again", et.pager.pages)
+	}
+
+	list, err := et.uc.ListEscalations(ctx, &dto.ListEscalationsRequest{TenantID: et.tenantID.String(), Status: "open"})
+	if err != nil || list.TotalCount != 1 {
+		t.Fatalf("ListEscalations() = %+v, %v, want the open escalation", list, err)
+	}
+	escalation := list.Items[0]
+	if escalation.Level != 1 || escalation.Cycle != 2 {
+		t.Errorf("escalation at level %d cycle %d, want level 1 cycle 2", escalation.Level, escalation.Cycle)
+	}
+
+	acked, err := et.uc.Acknowledge(ctx, et.tenantID.String(), escalation.ID, primary.String())
+	if err != nil {
+		t.Fatalf("Acknowledge() error = %v", err)
+	}
+	if acked.Status != "acknowledged" || acked.AcknowledgedBy != primary.String() {
+		t.Errorf("Acknowledge() = %+v", acked)
+	}
+	et.now = et.now.Add(time.Hour)
+	if n, _ := et.uc.ProcessDue(ctx, 10); n != 0 || len(et.pager.pages) != 3 {
+		t.Errorf("ProcessDue() = %d, pages = %d, want no pages once acknowledged", n, len(et.pager.pages))
+	}
+
+	_, err = et.uc.Acknowledge(ctx, et.tenantID.String(), escalation.ID, manager.String())
+	var appErr *application.AppError
+	if !errors.As(err, &appErr) || appErr.Code != application.ErrCodeConflict {
+		t.Errorf("Acknowledge() error = %v, want a conflict", err)
+	}
+}
+
+func TestEscalationUseCase_FailedPageIsRetried(t *testing.T) {
+	ctx := context.Background()
+	et := newEscalationTest()
+
+	if _, err := et.uc.CreatePolicy(ctx, &dto.CreateEscalationPolicyRequest{
+		TenantID:   et.tenantID.String(),
+		Name:       "SLA",
+		EventTypes: []string{domain.EscalationEventSLABreached},
+		Levels: []dto.EscalationLevelDTO{
+			{Targets: []dto.EscalationTargetDTO{{Type: "role", Value: "support_lead"}}, Channels: []string{"sms"}, TimeoutMinutes: 30},
+		},
+	}); err != nil {
+		t.Fatalf("CreatePolicy() error = %v", err)
+	}
+
+	et.pager.Err = errors.New("queue full")
+	event := &domain.RoutedEvent{ID: "evt-1", TenantID: et.tenantID, Type: domain.EscalationEventSLABreached}
+	if err := et.uc.Escalate(ctx, event); err == nil {
+		t.Fatal("Escalate() should report the failed page")
+	}
+
+	// The level is paged by the next run rather than timing out unpaged
+	et.pager.Err = nil
+	et.now = et.now.Add(time.Second)
+	if n, err := et.uc.ProcessDue(ctx, 10); n != 1 || err != nil {
+		t.Fatalf("ProcessDue() = %d, %v, want 1", n, err)
+	}
+	if len(et.pager.pages) != 1 || et.pager.pages[0][0].Value != "support_lead" {
+		t.Errorf("pages = %+v, want the first level paged", et.pager.pages)
+	}
+}
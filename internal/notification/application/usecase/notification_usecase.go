@@ -4,7 +4,10 @@ package usecase
 import (
 	"context"
 	"encoding/base64"
+	"encoding/json"
 	"fmt"
+	"math/rand"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
@@ -33,12 +36,35 @@ type NotificationUseCase interface {
 	GetNotification(ctx context.Context, req *dto.GetNotificationRequest) (*dto.NotificationDTO, error)
 	// ListNotifications lists notifications with filtering and pagination.
 	ListNotifications(ctx context.Context, req *dto.ListNotificationsRequest) (*dto.NotificationListDTO, error)
+	// GetNotificationTimeline retrieves the delivery history of a notification.
+	GetNotificationTimeline(ctx context.Context, req *dto.GetNotificationTimelineRequest) (*dto.NotificationTimelineDTO, error)
+	// HandleProviderWebhook applies a delivery status reported by a provider.
+	HandleProviderWebhook(ctx context.Context, req *dto.ProviderWebhookRequest) (*dto.ProviderWebhookResponse, error)
+	// GetNotificationStats retrieves delivery statistics for a tenant.
+	GetNotificationStats(ctx context.Context, req *dto.GetNotificationStatsRequest) (*dto.NotificationStatsDTO, error)
+	// DeliverQueuedNotification delivers a notification taken from the delivery queue.
+	DeliverQueuedNotification(ctx context.Context, job *ports.DeliveryJob) error
+	// ProcessDueRetries delivers notifications whose retry is due.
+	ProcessDueRetries(ctx context.Context, limit int) (int, error)
+	// SearchArchivedEmails searches the archive of sent emails.
+	SearchArchivedEmails(ctx context.Context, req *dto.SearchArchivedEmailsRequest) (*dto.ArchivedEmailListDTO, error)
+	// GetArchivedEmail retrieves an archived email.
+	GetArchivedEmail(ctx context.Context, req *dto.GetArchivedEmailRequest) (*dto.ArchivedEmailDTO, error)
+	// GetArchivedEmailContent retrieves the MIME message of an archived email.
+	GetArchivedEmailContent(ctx context.Context, req *dto.GetArchivedEmailRequest) (*dto.ArchivedEmailContentDTO, error)
+	// PurgeExpiredArchive deletes archived emails whose retention has passed.
+	PurgeExpiredArchive(ctx context.Context, limit int) (int64, error)
+	// PurgeExpiredBodies clears notification bodies whose retention has passed.
+	PurgeExpiredBodies(ctx context.Context, limit int) (int64, error)
 }
 
 // notificationUseCase implements the NotificationUseCase interface.
 type notificationUseCase struct {
 	notificationRepo domain.NotificationRepository
 	templateRepo     domain.TemplateRepository
+	deliveryLogRepo  domain.DeliveryLogRepository
+	archiveRepo      domain.ArchivedEmailRepository
+	deviceRepo       domain.DeviceRepository
 
 	emailProvider ports.EmailProvider
 	smsProvider   ports.SMSProvider
@@ -50,12 +76,23 @@ type notificationUseCase struct {
 	quotaManager       ports.QuotaManager
 	userService        ports.UserService
 	scheduler          ports.Scheduler
+	deliveryQueue      ports.DeliveryQueue
 	suppressionService ports.SuppressionService
+	tenantBranding     ports.TenantBrandingProvider
 	idGenerator        ports.IdGenerator
 	timeProvider       ports.TimeProvider
 	metrics            ports.MetricsCollector
 	logger             ports.Logger
 
+	retryPolicies domain.RetryPolicies
+	random        func() float64
+
+	archiveStorage     ports.FileStorage
+	archiveRetention   domain.ArchiveRetention
+	archiveInlineLimit int
+
+	bodyRetention domain.BodyRetention
+
 	mapper *mapper.NotificationMapper
 }
 
@@ -63,6 +100,9 @@ type notificationUseCase struct {
 type NotificationUseCaseConfig struct {
 	NotificationRepo domain.NotificationRepository
 	TemplateRepo     domain.TemplateRepository
+	DeliveryLogRepo  domain.DeliveryLogRepository
+	ArchiveRepo      domain.ArchivedEmailRepository // Optional; without it sent emails are not archived
+	DeviceRepo       domain.DeviceRepository        // Optional; without it push notifications go to the user service's device tokens
 
 	EmailProvider ports.EmailProvider
 	SMSProvider   ports.SMSProvider
@@ -74,18 +114,50 @@ type NotificationUseCaseConfig struct {
 	QuotaManager       ports.QuotaManager
 	UserService        ports.UserService
 	Scheduler          ports.Scheduler
+	DeliveryQueue      ports.DeliveryQueue // Optional; without it notifications are delivered in process
 	SuppressionService ports.SuppressionService
+	TenantBranding     ports.TenantBrandingProvider // Optional; without it emails are sent unbranded
 	IdGenerator        ports.IdGenerator
 	TimeProvider       ports.TimeProvider
 	Metrics            ports.MetricsCollector
 	Logger             ports.Logger
+
+	RetryPolicies *domain.RetryPolicies // Optional; defaults to domain.DefaultRetryPolicies
+
+	// ArchiveStorage holds archived emails larger than ArchiveInlineLimit
+	// bytes; without it every archived email is stored inline.
+	ArchiveStorage     ports.FileStorage
+	ArchiveRetention   *domain.ArchiveRetention // Optional; defaults to domain.DefaultArchiveRetention
+	ArchiveInlineLimit int                      // Optional; defaults to defaultArchiveInlineLimit
+
+	BodyRetention domain.BodyRetention // Optional; by default bodies are kept
 }
 
+// defaultArchiveInlineLimit is the size up to which archived emails are
+// stored in the database rather than in object storage.
+const defaultArchiveInlineLimit = 256 * 1024
+
 // NewNotificationUseCase creates a new NotificationUseCase.
 func NewNotificationUseCase(cfg NotificationUseCaseConfig) NotificationUseCase {
+	retryPolicies := domain.DefaultRetryPolicies()
+	if cfg.RetryPolicies != nil {
+		retryPolicies = *cfg.RetryPolicies
+	}
+	archiveRetention := domain.DefaultArchiveRetention()
+	if cfg.ArchiveRetention != nil {
+		archiveRetention = *cfg.ArchiveRetention
+	}
+	archiveInlineLimit := cfg.ArchiveInlineLimit
+	if archiveInlineLimit <= 0 {
+		archiveInlineLimit = defaultArchiveInlineLimit
+	}
+
 	return &notificationUseCase{
 		notificationRepo: cfg.NotificationRepo,
 		templateRepo:     cfg.TemplateRepo,
+		deliveryLogRepo:  cfg.DeliveryLogRepo,
+		archiveRepo:      cfg.ArchiveRepo,
+		deviceRepo:       cfg.DeviceRepo,
 
 		emailProvider: cfg.EmailProvider,
 		smsProvider:   cfg.SMSProvider,
@@ -97,12 +169,23 @@ func NewNotificationUseCase(cfg NotificationUseCaseConfig) NotificationUseCase {
 		quotaManager:       cfg.QuotaManager,
 		userService:        cfg.UserService,
 		scheduler:          cfg.Scheduler,
+		deliveryQueue:      cfg.DeliveryQueue,
 		suppressionService: cfg.SuppressionService,
+		tenantBranding:     cfg.TenantBranding,
 		idGenerator:        cfg.IdGenerator,
 		timeProvider:       cfg.TimeProvider,
 		metrics:            cfg.Metrics,
 		logger:             cfg.Logger,
 
+		retryPolicies: retryPolicies,
+		random:        rand.Float64,
+
+		archiveStorage:     cfg.ArchiveStorage,
+		archiveRetention:   archiveRetention,
+		archiveInlineLimit: archiveInlineLimit,
+
+		bodyRetention: cfg.BodyRetention,
+
 		mapper: mapper.NewNotificationMapper(),
 	}
 }
@@ -149,9 +232,15 @@ func (uc *notificationUseCase) SendEmail(ctx context.Context, req *dto.SendEmail
 	subject := req.Subject
 	body := req.Body
 	htmlBody := req.HTMLBody
+	branding := uc.brandingFor(ctx, req.TenantID)
 
 	if req.TemplateID != "" {
-		renderedContent, err := uc.renderEmailTemplate(ctx, req.TenantID, req.TemplateID, req.Variables)
+		locale := req.Locale
+		if locale == "" && len(req.To) == 1 {
+			locale = uc.recipientLocale(ctx, req.To[0])
+		}
+		variables := withBrandingVariables(req.Variables, branding)
+		renderedContent, err := uc.renderEmailTemplate(ctx, req.TenantID, req.TemplateID, variables, locale)
 		if err != nil {
 			return nil, err
 		}
@@ -186,9 +275,14 @@ func (uc *notificationUseCase) SendEmail(ctx context.Context, req *dto.SendEmail
 		notification.SetHTMLBody(htmlBody)
 	}
 
-	// Set sender info
-	if req.From != "" {
-		if err := notification.SetFromAddress(req.From, req.FromName); err != nil {
+	// Set sender info; without one, white-labeled tenants send from their
+	// own address
+	from, fromName := req.From, req.FromName
+	if from == "" && branding != nil && branding.EmailFromAddress != "" {
+		from, fromName = branding.EmailFromAddress, branding.EmailFromName
+	}
+	if from != "" {
+		if err := notification.SetFromAddress(from, fromName); err != nil {
 			return nil, application.NewInvalidInputError(err.Error())
 		}
 	}
@@ -285,8 +379,10 @@ func (uc *notificationUseCase) SendEmail(ctx context.Context, req *dto.SendEmail
 	// Publish domain events
 	uc.publishDomainEvents(ctx, notification)
 
-	// Send email asynchronously
-	go uc.deliverEmail(context.Background(), notification, req)
+	// Send email through the priority queue, or asynchronously without one
+	if !uc.enqueueDelivery(ctx, notification, req) {
+		go uc.deliverEmail(context.Background(), notification, req)
+	}
 
 	uc.metrics.IncrementCounter(ctx, "notification.email.queued", map[string]string{
 		"tenant_id": req.TenantID,
@@ -341,7 +437,7 @@ func (uc *notificationUseCase) SendSMS(ctx context.Context, req *dto.SendSMSRequ
 	// Resolve template if provided
 	body := req.Body
 	if req.TemplateID != "" {
-		renderedContent, err := uc.renderSMSTemplate(ctx, req.TenantID, req.TemplateID, req.Variables)
+		renderedContent, err := uc.renderSMSTemplate(ctx, req.TenantID, req.TemplateID, req.Variables, req.Locale)
 		if err != nil {
 			return nil, err
 		}
@@ -431,8 +527,10 @@ func (uc *notificationUseCase) SendSMS(ctx context.Context, req *dto.SendSMSRequ
 	// Publish domain events
 	uc.publishDomainEvents(ctx, notification)
 
-	// Send SMS asynchronously
-	go uc.deliverSMS(context.Background(), notification, req)
+	// Send SMS through the priority queue, or asynchronously without one
+	if !uc.enqueueDelivery(ctx, notification, req) {
+		go uc.deliverSMS(context.Background(), notification, req)
+	}
 
 	uc.metrics.IncrementCounter(ctx, "notification.sms.queued", map[string]string{
 		"tenant_id": req.TenantID,
@@ -603,8 +701,10 @@ func (uc *notificationUseCase) SendInAppNotification(ctx context.Context, req *d
 	// Publish domain events
 	uc.publishDomainEvents(ctx, notification)
 
-	// Send in-app notification asynchronously
-	go uc.deliverInApp(context.Background(), notification, req)
+	// Send in-app notification through the priority queue, or asynchronously without one
+	if !uc.enqueueDelivery(ctx, notification, req) {
+		go uc.deliverInApp(context.Background(), notification, req)
+	}
 
 	uc.metrics.IncrementCounter(ctx, "notification.in_app.queued", map[string]string{
 		"tenant_id": req.TenantID,
@@ -651,14 +751,12 @@ func (uc *notificationUseCase) SendPushNotification(ctx context.Context, req *dt
 		return nil, application.NewAppError(application.ErrCodeUserInactive, "user is inactive")
 	}
 
-	// Determine device token
-	deviceToken := req.DeviceToken
-	if deviceToken == "" && len(user.DeviceTokens) > 0 {
-		deviceToken = user.DeviceTokens[0]
-	}
-	if deviceToken == "" {
+	// Determine the devices: the one given, or every device of the user
+	targets := uc.pushTargets(ctx, tenantID, userID, req, user.DeviceTokens)
+	if len(targets) == 0 {
 		return nil, application.NewDeviceNotRegisteredError(req.UserID)
 	}
+	deviceToken := targets[0].token
 
 	// Parse notification type
 	notificationType, err := domain.ParseType(req.Type)
@@ -759,8 +857,10 @@ func (uc *notificationUseCase) SendPushNotification(ctx context.Context, req *dt
 	// Publish domain events
 	uc.publishDomainEvents(ctx, notification)
 
-	// Send push notification asynchronously
-	go uc.deliverPush(context.Background(), notification, req, deviceToken)
+	// Send push notification through the priority queue, or asynchronously without one
+	if !uc.enqueueDelivery(ctx, notification, req) {
+		go uc.deliverPush(context.Background(), notification, req)
+	}
 
 	uc.metrics.IncrementCounter(ctx, "notification.push.queued", map[string]string{
 		"tenant_id": req.TenantID,
@@ -965,6 +1065,339 @@ func (uc *notificationUseCase) ListNotifications(ctx context.Context, req *dto.L
 	return uc.mapper.NotificationListToDTO(notificationList.Notifications, notificationList.Total, req.Page, req.PageSize), nil
 }
 
+// GetNotificationTimeline retrieves the delivery history of a notification:
+// when it was created, every attempt to hand it to a provider and every
+// status the provider reported afterwards.
+func (uc *notificationUseCase) GetNotificationTimeline(ctx context.Context, req *dto.GetNotificationTimelineRequest) (*dto.NotificationTimelineDTO, error) {
+	// Parse notification ID
+	notificationID, err := uuid.Parse(req.NotificationID)
+	if err != nil {
+		return nil, application.NewInvalidInputError("invalid notification ID format")
+	}
+
+	// Parse tenant ID
+	tenantID, err := uuid.Parse(req.TenantID)
+	if err != nil {
+		return nil, application.NewInvalidInputError("invalid tenant ID format")
+	}
+
+	notification, err := uc.notificationRepo.FindByID(ctx, notificationID)
+	if err != nil {
+		return nil, application.NewNotificationNotFoundError(req.NotificationID)
+	}
+
+	// Verify tenant access
+	if notification.TenantID != tenantID {
+		return nil, application.NewForbiddenError("access denied to this notification")
+	}
+
+	var logs []*domain.DeliveryLog
+	if uc.deliveryLogRepo != nil {
+		logs, err = uc.deliveryLogRepo.FindByNotification(ctx, notificationID)
+		if err != nil {
+			return nil, application.NewInternalError("failed to get delivery logs", err)
+		}
+	}
+
+	return uc.mapper.ToTimelineDTO(notification, logs), nil
+}
+
+// HandleProviderWebhook applies a delivery status reported by a provider to
+// the notification it sent. Events for a status the notification has already
+// moved past are recorded in its timeline but leave the status unchanged.
+func (uc *notificationUseCase) HandleProviderWebhook(ctx context.Context, req *dto.ProviderWebhookRequest) (*dto.ProviderWebhookResponse, error) {
+	uc.logger.WithContext(ctx).Info("HandleProviderWebhook started", map[string]interface{}{
+		"provider":            req.Provider,
+		"provider_message_id": req.ProviderMessageID,
+		"status":              req.Status,
+	})
+
+	if req.Provider == "" || req.ProviderMessageID == "" {
+		return nil, application.NewValidationError("provider and provider message ID are required")
+	}
+	status, err := domain.ParseProviderStatus(req.Status)
+	if err != nil {
+		return nil, application.NewInvalidInputError(fmt.Sprintf("unsupported delivery status %q", req.Status))
+	}
+
+	notification, err := uc.notificationRepo.FindByProviderMessageID(ctx, req.Provider, req.ProviderMessageID)
+	if err != nil {
+		return nil, application.NewNotFoundError("notification", req.ProviderMessageID)
+	}
+
+	updated, err := notification.ApplyProviderStatus(status, req.Reason)
+	if err != nil {
+		return nil, application.NewInvalidStateError(err.Error())
+	}
+	if updated {
+		if err := uc.notificationRepo.Update(ctx, notification); err != nil {
+			return nil, application.NewInternalError("failed to update notification", err)
+		}
+	}
+
+	uc.recordDeliveryLog(ctx, domain.NewProviderStatusLog(notification, status, req.Reason, req.OccurredAt))
+	uc.publishDomainEvents(ctx, notification)
+
+	uc.metrics.IncrementCounter(ctx, "notification.webhook.received", map[string]string{
+		"provider": req.Provider,
+		"status":   string(status),
+		"updated":  fmt.Sprintf("%t", updated),
+	})
+
+	return &dto.ProviderWebhookResponse{
+		NotificationID: notification.ID.String(),
+		Status:         notification.Status.String(),
+		Updated:        updated,
+	}, nil
+}
+
+// defaultStatsPeriodDays is the period stats cover when no start date is given.
+const defaultStatsPeriodDays = 30
+
+// GetNotificationStats retrieves delivery statistics for a tenant's
+// notifications: funnel counts and rates overall, per channel and per
+// template, and a series of counts per interval for charts.
+func (uc *notificationUseCase) GetNotificationStats(ctx context.Context, req *dto.GetNotificationStatsRequest) (*dto.NotificationStatsDTO, error) {
+	// Parse tenant ID
+	tenantID, err := uuid.Parse(req.TenantID)
+	if err != nil {
+		return nil, application.NewInvalidInputError("invalid tenant ID format")
+	}
+
+	query := domain.NotificationStatsQuery{
+		TenantID:  tenantID,
+		StartDate: req.StartDate.UTC(),
+		EndDate:   req.EndDate.UTC(),
+	}
+	if req.EndDate.IsZero() {
+		query.EndDate = uc.timeProvider.NowUTC()
+	}
+	if req.StartDate.IsZero() {
+		query.StartDate = query.EndDate.AddDate(0, 0, -defaultStatsPeriodDays)
+	}
+	if !query.StartDate.Before(query.EndDate) {
+		return nil, application.NewInvalidInputError("start date must be before end date")
+	}
+
+	if req.Channel != "" {
+		channel, err := domain.ParseChannel(req.Channel)
+		if err != nil {
+			return nil, application.NewInvalidInputError(fmt.Sprintf("invalid channel: %s", req.Channel))
+		}
+		query.Channel = &channel
+	}
+
+	query.Interval = domain.DefaultStatsInterval(query.StartDate, query.EndDate)
+	if req.Interval != "" {
+		if query.Interval, err = domain.ParseStatsInterval(req.Interval); err != nil {
+			return nil, application.NewInvalidInputError(fmt.Sprintf("invalid interval: %s", req.Interval))
+		}
+	}
+	if query.Interval.BucketCount(query.StartDate, query.EndDate) > domain.MaxStatsBuckets {
+		return nil, application.NewInvalidInputError(fmt.Sprintf("period is too long for an interval of one %s", query.Interval))
+	}
+
+	stats, err := uc.notificationRepo.GetStats(ctx, query)
+	if err != nil {
+		return nil, application.NewInternalError("failed to get notification stats", err)
+	}
+	stats.Series = domain.FillStatsSeries(stats.Series, query.StartDate, query.EndDate, query.Interval)
+
+	return uc.mapper.ToStatsDTO(req.TenantID, query, stats), nil
+}
+
+// DeliverQueuedNotification delivers a notification taken from the delivery
+// queue. A job whose notification is no longer queued, because it was
+// cancelled or a redelivered job already sent it, is skipped. Delivery
+// failures are recorded on the notification and retried by its retry policy,
+// so only a job that cannot be read returns an error.
+func (uc *notificationUseCase) DeliverQueuedNotification(ctx context.Context, job *ports.DeliveryJob) error {
+	notificationID, err := uuid.Parse(job.NotificationID)
+	if err != nil {
+		return application.NewInvalidInputError("invalid notification ID format")
+	}
+
+	notification, err := uc.notificationRepo.FindByID(ctx, notificationID)
+	if err != nil {
+		return application.NewNotificationNotFoundError(job.NotificationID)
+	}
+
+	if notification.Status != domain.StatusQueued {
+		uc.logger.WithContext(ctx).Info("skipping queued delivery", map[string]interface{}{
+			"notification_id": job.NotificationID,
+			"status":          notification.Status.String(),
+		})
+		return nil
+	}
+
+	switch notification.Channel {
+	case domain.ChannelEmail:
+		var req dto.SendEmailRequest
+		if err := json.Unmarshal(job.Payload, &req); err != nil {
+			return application.NewInvalidInputError("invalid email delivery payload")
+		}
+		uc.deliverEmail(ctx, notification, &req)
+	case domain.ChannelSMS:
+		var req dto.SendSMSRequest
+		if err := json.Unmarshal(job.Payload, &req); err != nil {
+			return application.NewInvalidInputError("invalid SMS delivery payload")
+		}
+		uc.deliverSMS(ctx, notification, &req)
+	case domain.ChannelInApp:
+		var req dto.SendInAppRequest
+		if err := json.Unmarshal(job.Payload, &req); err != nil {
+			return application.NewInvalidInputError("invalid in-app delivery payload")
+		}
+		uc.deliverInApp(ctx, notification, &req)
+	case domain.ChannelPush:
+		var req dto.SendPushRequest
+		if err := json.Unmarshal(job.Payload, &req); err != nil {
+			return application.NewInvalidInputError("invalid push delivery payload")
+		}
+		uc.deliverPush(ctx, notification, &req)
+	default:
+		return application.NewInvalidStateError(fmt.Sprintf("cannot deliver %s notifications from the queue", notification.Channel))
+	}
+
+	uc.metrics.RecordDuration(ctx, "notification.queue.wait", uc.timeProvider.Now().Sub(job.EnqueuedAt), map[string]string{
+		"priority": notification.Priority.String(),
+	})
+	uc.metrics.IncrementCounter(ctx, "notification.queue.delivered", map[string]string{
+		"channel":  notification.Channel.String(),
+		"priority": notification.Priority.String(),
+	})
+
+	return nil
+}
+
+// SearchArchivedEmails searches a tenant's archive of sent emails by
+// recipient, subject and sent date, newest first.
+func (uc *notificationUseCase) SearchArchivedEmails(ctx context.Context, req *dto.SearchArchivedEmailsRequest) (*dto.ArchivedEmailListDTO, error) {
+	if uc.archiveRepo == nil {
+		return nil, application.NewInvalidStateError("email archive is not configured")
+	}
+
+	// Parse tenant ID
+	tenantID, err := uuid.Parse(req.TenantID)
+	if err != nil {
+		return nil, application.NewInvalidInputError("invalid tenant ID format")
+	}
+	if req.SentAfter != nil && req.SentBefore != nil && !req.SentAfter.Before(*req.SentBefore) {
+		return nil, application.NewInvalidInputError("sent_after must be before sent_before")
+	}
+	if req.Page < 1 {
+		req.Page = 1
+	}
+	if req.PageSize < 1 || req.PageSize > 100 {
+		req.PageSize = 20
+	}
+
+	list, err := uc.archiveRepo.Search(ctx, domain.ArchivedEmailFilter{
+		TenantID:   tenantID,
+		Recipient:  strings.TrimSpace(req.Recipient),
+		Subject:    strings.TrimSpace(req.Subject),
+		SentAfter:  req.SentAfter,
+		SentBefore: req.SentBefore,
+		Offset:     (req.Page - 1) * req.PageSize,
+		Limit:      req.PageSize,
+	})
+	if err != nil {
+		return nil, application.NewInternalError("failed to search email archive", err)
+	}
+
+	return uc.mapper.ArchivedEmailListToDTO(list, req.Page, req.PageSize), nil
+}
+
+// GetArchivedEmail retrieves an archived email without its MIME message.
+func (uc *notificationUseCase) GetArchivedEmail(ctx context.Context, req *dto.GetArchivedEmailRequest) (*dto.ArchivedEmailDTO, error) {
+	archived, err := uc.findArchivedEmail(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	return uc.mapper.ToArchivedEmailDTO(archived), nil
+}
+
+// GetArchivedEmailContent retrieves the MIME message of an archived email
+// exactly as it was archived, from the database or object storage.
+func (uc *notificationUseCase) GetArchivedEmailContent(ctx context.Context, req *dto.GetArchivedEmailRequest) (*dto.ArchivedEmailContentDTO, error) {
+	archived, err := uc.findArchivedEmail(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	content := archived.MIME
+	if !archived.IsInline() {
+		if uc.archiveStorage == nil {
+			return nil, application.NewInvalidStateError("email archive storage is not configured")
+		}
+		if content, err = uc.archiveStorage.Download(ctx, archived.StorageKey); err != nil {
+			return nil, application.NewInternalError("failed to download archived email", err)
+		}
+	}
+	if !archived.VerifyChecksum(content) {
+		return nil, application.NewInternalError("archived email does not match its checksum", nil)
+	}
+
+	return &dto.ArchivedEmailContentDTO{
+		ID:       archived.ID.String(),
+		Filename: archived.ID.String() + ".eml",
+		Content:  content,
+	}, nil
+}
+
+// PurgeExpiredArchive deletes up to limit archived emails whose retention
+// has passed, with their object storage copies, and returns how many were
+// deleted. An email whose stored copy cannot be deleted is kept for the next
+// run.
+func (uc *notificationUseCase) PurgeExpiredArchive(ctx context.Context, limit int) (int64, error) {
+	if uc.archiveRepo == nil {
+		return 0, nil
+	}
+
+	expired, err := uc.archiveRepo.FindExpired(ctx, uc.timeProvider.NowUTC(), limit)
+	if err != nil {
+		return 0, application.NewInternalError("failed to find expired archived emails", err)
+	}
+
+	ids := make([]uuid.UUID, 0, len(expired))
+	for _, archived := range expired {
+		if !archived.IsInline() && uc.archiveStorage != nil {
+			if err := uc.archiveStorage.Delete(ctx, archived.StorageKey); err != nil {
+				uc.logger.WithContext(ctx).Error("failed to delete archived email from storage", err, map[string]interface{}{
+					"archive_id":  archived.ID.String(),
+					"storage_key": archived.StorageKey,
+				})
+				continue
+			}
+		}
+		ids = append(ids, archived.ID)
+	}
+
+	deleted, err := uc.archiveRepo.DeleteByIDs(ctx, ids)
+	if err != nil {
+		return 0, application.NewInternalError("failed to delete expired archived emails", err)
+	}
+	uc.metrics.RecordGauge(ctx, "notification.archive.purged", float64(deleted), nil)
+	return deleted, nil
+}
+
+// PurgeExpiredBodies clears the bodies of sent notifications older than
+// their tenant's body retention, up to limit per tenant retention, and
+// returns how many were cleared. The notifications and their delivery
+// history are kept.
+func (uc *notificationUseCase) PurgeExpiredBodies(ctx context.Context, limit int) (int64, error) {
+	var purged int64
+	for _, filter := range uc.bodyRetention.PurgeFilters(uc.timeProvider.NowUTC(), limit) {
+		count, err := uc.notificationRepo.PurgeBodies(ctx, filter)
+		if err != nil {
+			return purged, application.NewInternalError("failed to purge notification bodies", err)
+		}
+		purged += count
+	}
+	uc.metrics.RecordGauge(ctx, "notification.bodies.purged", float64(purged), nil)
+	return purged, nil
+}
+
 // === Private helper methods ===
 
 func (uc *notificationUseCase) validateSendEmailRequest(req *dto.SendEmailRequest) error {
@@ -1022,7 +1455,7 @@ func (uc *notificationUseCase) validateSendPushRequest(req *dto.SendPushRequest)
 	return nil
 }
 
-func (uc *notificationUseCase) renderEmailTemplate(ctx context.Context, tenantID, templateID string, variables map[string]interface{}) (*domain.RenderedEmail, error) {
+func (uc *notificationUseCase) renderEmailTemplate(ctx context.Context, tenantID, templateID string, variables map[string]interface{}, locale string) (*domain.RenderedEmail, error) {
 	tid, err := uuid.Parse(templateID)
 	if err != nil {
 		return nil, application.NewInvalidInputError("invalid template ID format")
@@ -1038,7 +1471,11 @@ func (uc *notificationUseCase) renderEmailTemplate(ctx context.Context, tenantID
 		return nil, application.NewForbiddenError("access denied to this template")
 	}
 
-	rendered, err := template.RenderEmail(variables, template.DefaultLocale)
+	if locale == "" {
+		locale = template.DefaultLocale
+	}
+
+	rendered, err := template.RenderEmail(variables, locale)
 	if err != nil {
 		return nil, application.NewTemplateRenderFailedError(templateID, err.Error())
 	}
@@ -1046,7 +1483,7 @@ func (uc *notificationUseCase) renderEmailTemplate(ctx context.Context, tenantID
 	return rendered, nil
 }
 
-func (uc *notificationUseCase) renderSMSTemplate(ctx context.Context, tenantID, templateID string, variables map[string]interface{}) (*domain.RenderedSMS, error) {
+func (uc *notificationUseCase) renderSMSTemplate(ctx context.Context, tenantID, templateID string, variables map[string]interface{}, locale string) (*domain.RenderedSMS, error) {
 	tid, err := uuid.Parse(templateID)
 	if err != nil {
 		return nil, application.NewInvalidInputError("invalid template ID format")
@@ -1062,7 +1499,11 @@ func (uc *notificationUseCase) renderSMSTemplate(ctx context.Context, tenantID,
 		return nil, application.NewForbiddenError("access denied to this template")
 	}
 
-	rendered, err := template.RenderSMS(variables, template.DefaultLocale)
+	if locale == "" {
+		locale = template.DefaultLocale
+	}
+
+	rendered, err := template.RenderSMS(variables, locale)
 	if err != nil {
 		return nil, application.NewTemplateRenderFailedError(templateID, err.Error())
 	}
@@ -1070,6 +1511,58 @@ func (uc *notificationUseCase) renderSMSTemplate(ctx context.Context, tenantID,
 	return rendered, nil
 }
 
+// brandingFor returns the branding of a white-labeled tenant, or nil when the
+// tenant has none or it cannot be loaded; emails are then sent unbranded.
+func (uc *notificationUseCase) brandingFor(ctx context.Context, tenantID string) *ports.TenantBranding {
+	if uc.tenantBranding == nil {
+		return nil
+	}
+	branding, err := uc.tenantBranding.GetTenantBranding(ctx, tenantID)
+	if err != nil {
+		uc.logger.WithContext(ctx).Warn("failed to load tenant branding", map[string]interface{}{
+			"tenant_id": tenantID,
+			"error":     err.Error(),
+		})
+		return nil
+	}
+	return branding
+}
+
+// withBrandingVariables returns the template variables with the tenant's
+// branding under "branding", so templates can use {{.branding.logo_url}}.
+// The variable is always set, empty for unbranded tenants, unless the
+// caller passed its own.
+func withBrandingVariables(variables map[string]interface{}, branding *ports.TenantBranding) map[string]interface{} {
+	if _, ok := variables["branding"]; ok {
+		return variables
+	}
+	if branding == nil {
+		branding = &ports.TenantBranding{}
+	}
+
+	result := make(map[string]interface{}, len(variables)+1)
+	for k, v := range variables {
+		result[k] = v
+	}
+	result["branding"] = map[string]interface{}{
+		"tenant_name":     branding.TenantName,
+		"logo_url":        branding.LogoURL,
+		"primary_color":   branding.PrimaryColor,
+		"secondary_color": branding.SecondaryColor,
+	}
+	return result
+}
+
+// recipientLocale returns the locale of the user with the given email address,
+// or "" when the address does not belong to a known user.
+func (uc *notificationUseCase) recipientLocale(ctx context.Context, email string) string {
+	user, err := uc.userService.GetUserByEmail(ctx, email)
+	if err != nil || user == nil {
+		return ""
+	}
+	return user.Locale
+}
+
 func (uc *notificationUseCase) renderInAppTemplate(ctx context.Context, tenantID, templateID string, variables map[string]interface{}, locale string) (*domain.RenderedInApp, error) {
 	tid, err := uuid.Parse(templateID)
 	if err != nil {
@@ -1145,6 +1638,41 @@ func (uc *notificationUseCase) saveAndScheduleNotification(ctx context.Context,
 	return nil
 }
 
+// enqueueDelivery hands a queued notification to the delivery queue of its
+// priority, with the send request for the options the notification does not
+// store. It returns false when there is no queue or enqueueing failed, in
+// which case the caller delivers the notification in process.
+func (uc *notificationUseCase) enqueueDelivery(ctx context.Context, notification *domain.Notification, req interface{}) bool {
+	if uc.deliveryQueue == nil {
+		return false
+	}
+
+	payload, err := json.Marshal(req)
+	if err != nil {
+		uc.logger.WithContext(ctx).Error("failed to encode delivery payload", err, map[string]interface{}{
+			"notification_id": notification.ID.String(),
+		})
+		return false
+	}
+
+	job := &ports.DeliveryJob{
+		NotificationID: notification.ID.String(),
+		TenantID:       notification.TenantID.String(),
+		Channel:        notification.Channel,
+		Priority:       notification.Priority,
+		EnqueuedAt:     uc.timeProvider.NowUTC(),
+		Payload:        payload,
+	}
+	if err := uc.deliveryQueue.Enqueue(ctx, job); err != nil {
+		uc.logger.WithContext(ctx).Error("failed to enqueue notification, delivering in process", err, map[string]interface{}{
+			"notification_id": job.NotificationID,
+			"priority":        job.Priority.String(),
+		})
+		return false
+	}
+	return true
+}
+
 func (uc *notificationUseCase) publishDomainEvents(ctx context.Context, notification *domain.Notification) {
 	events := notification.GetDomainEvents()
 	for _, event := range events {
@@ -1158,6 +1686,118 @@ func (uc *notificationUseCase) publishDomainEvents(ctx context.Context, notifica
 	notification.ClearDomainEvents()
 }
 
+// recordDeliveryLog stores a delivery log entry. A failure is logged rather
+// than returned, since the delivery itself has already happened.
+func (uc *notificationUseCase) recordDeliveryLog(ctx context.Context, log *domain.DeliveryLog) {
+	if uc.deliveryLogRepo == nil {
+		return
+	}
+	if err := uc.deliveryLogRepo.Create(ctx, log); err != nil {
+		uc.logger.WithContext(ctx).Error("failed to record delivery log", err, map[string]interface{}{
+			"notification_id": log.NotificationID.String(),
+			"status":          log.Status,
+		})
+	}
+}
+
+// archiveEmail stores the MIME message of an email that was sent. Messages
+// larger than the inline limit go to object storage. A failure is logged
+// rather than returned, since the email has already been sent.
+func (uc *notificationUseCase) archiveEmail(ctx context.Context, notification *domain.Notification, req ports.EmailRequest) {
+	if uc.archiveRepo == nil || notification.Status != domain.StatusSent {
+		return
+	}
+	fields := map[string]interface{}{"notification_id": notification.ID.String()}
+
+	attachments := make([]domain.Attachment, len(req.Attachments))
+	for i, att := range req.Attachments {
+		attachments[i] = domain.Attachment{
+			Filename:    att.Filename,
+			ContentType: att.ContentType,
+			Content:     att.Content,
+			ContentID:   att.ContentID,
+			Inline:      att.ContentID != "",
+		}
+	}
+	messageID := req.MessageID + "@" + archiveMessageDomain(req.From)
+	content, err := domain.ComposeMIME(domain.MIMEMessage{
+		MessageID:   messageID,
+		From:        req.From,
+		FromName:    notification.FromName,
+		ReplyTo:     req.ReplyTo,
+		To:          req.To,
+		CC:          req.CC,
+		Subject:     req.Subject,
+		Date:        uc.timeProvider.Now(),
+		Headers:     req.Headers,
+		TextBody:    req.TextBody,
+		HTMLBody:    req.HTMLBody,
+		Attachments: attachments,
+	})
+	if err != nil {
+		uc.logger.WithContext(ctx).Error("failed to compose archived email", err, fields)
+		return
+	}
+
+	recipients := make([]string, 0, len(req.To)+len(req.CC)+len(req.BCC))
+	recipients = append(append(append(recipients, req.To...), req.CC...), req.BCC...)
+	archived := domain.NewArchivedEmail(notification, recipients, messageID, content, uc.archiveRetention.For(notification.TenantID))
+
+	if len(content) > uc.archiveInlineLimit && uc.archiveStorage != nil {
+		key := fmt.Sprintf("email-archive/%s/%s/%s.eml", notification.TenantID, archived.SentAt.Format("2006/01/02"), archived.ID)
+		if err := uc.archiveStorage.Upload(ctx, key, content, "message/rfc822"); err != nil {
+			// Keep the message inline rather than lose it
+			uc.logger.WithContext(ctx).Error("failed to upload archived email", err, fields)
+		} else {
+			archived.MoveToStorage(key)
+		}
+	}
+
+	if err := uc.archiveRepo.Create(ctx, archived); err != nil {
+		uc.logger.WithContext(ctx).Error("failed to archive email", err, fields)
+		return
+	}
+	uc.metrics.RecordHistogram(ctx, "notification.archive.size_bytes", float64(archived.SizeBytes), map[string]string{
+		"inline": fmt.Sprintf("%t", archived.IsInline()),
+	})
+}
+
+// findArchivedEmail finds an archived email of the requesting tenant.
+func (uc *notificationUseCase) findArchivedEmail(ctx context.Context, req *dto.GetArchivedEmailRequest) (*domain.ArchivedEmail, error) {
+	if uc.archiveRepo == nil {
+		return nil, application.NewInvalidStateError("email archive is not configured")
+	}
+
+	archiveID, err := uuid.Parse(req.ArchiveID)
+	if err != nil {
+		return nil, application.NewInvalidInputError("invalid archive ID format")
+	}
+	tenantID, err := uuid.Parse(req.TenantID)
+	if err != nil {
+		return nil, application.NewInvalidInputError("invalid tenant ID format")
+	}
+
+	archived, err := uc.archiveRepo.FindByID(ctx, archiveID)
+	if err != nil {
+		return nil, application.NewNotFoundError("archived email", req.ArchiveID)
+	}
+
+	// Verify tenant access
+	if archived.TenantID != tenantID {
+		return nil, application.NewForbiddenError("access denied to this archived email")
+	}
+	return archived, nil
+}
+
+// archiveMessageDomain returns the domain of the sender address, used to
+// qualify the Message-ID of archived emails.
+func archiveMessageDomain(from string) string {
+	if at := strings.LastIndex(from, "@"); at >= 0 && at < len(from)-1 {
+		return strings.Trim(from[at+1:], "> ")
+	}
+	return "localhost"
+}
+
 func (uc *notificationUseCase) deliverEmail(ctx context.Context, notification *domain.Notification, req *dto.SendEmailRequest) {
 	// Mark as sending
 	if err := notification.MarkSending(); err != nil {
@@ -1196,8 +1836,10 @@ func (uc *notificationUseCase) deliverEmail(ctx context.Context, notification *d
 	}
 
 	// Send email
+	start := uc.timeProvider.Now()
 	response, err := uc.emailProvider.SendEmail(ctx, emailReq)
-	uc.handleDeliveryResult(ctx, notification, err, response)
+	uc.handleDeliveryResult(ctx, notification, err, response, uc.timeProvider.Now().Sub(start))
+	uc.archiveEmail(ctx, notification, emailReq)
 }
 
 func (uc *notificationUseCase) deliverSMS(ctx context.Context, notification *domain.Notification, req *dto.SendSMSRequest) {
@@ -1217,8 +1859,9 @@ func (uc *notificationUseCase) deliverSMS(ctx context.Context, notification *dom
 	}
 
 	// Send SMS
+	start := uc.timeProvider.Now()
 	response, err := uc.smsProvider.SendSMS(ctx, smsReq)
-	uc.handleSMSDeliveryResult(ctx, notification, err, response)
+	uc.handleSMSDeliveryResult(ctx, notification, err, response, uc.timeProvider.Now().Sub(start))
 }
 
 func (uc *notificationUseCase) deliverInApp(ctx context.Context, notification *domain.Notification, req *dto.SendInAppRequest) {
@@ -1243,11 +1886,17 @@ func (uc *notificationUseCase) deliverInApp(ctx context.Context, notification *d
 	}
 
 	// Send in-app notification
+	start := uc.timeProvider.Now()
 	response, err := uc.inAppProvider.SendInApp(ctx, inAppReq)
-	uc.handleInAppDeliveryResult(ctx, notification, err, response)
+	uc.handleInAppDeliveryResult(ctx, notification, err, response, uc.timeProvider.Now().Sub(start))
 }
 
-func (uc *notificationUseCase) deliverPush(ctx context.Context, notification *domain.Notification, req *dto.SendPushRequest, deviceToken string) {
+// deliverPush sends a push notification to every device of its recipient,
+// or to the device the request names. The notification is sent when any
+// device accepts it. Devices whose tokens the provider reports as no longer
+// valid are unregistered, and a notification none of whose devices remain is
+// not retried.
+func (uc *notificationUseCase) deliverPush(ctx context.Context, notification *domain.Notification, req *dto.SendPushRequest) {
 	// Mark as sending
 	if err := notification.MarkSending(); err != nil {
 		uc.logger.WithContext(ctx).Error("failed to mark notification as sending", err, nil)
@@ -1255,10 +1904,17 @@ func (uc *notificationUseCase) deliverPush(ctx context.Context, notification *do
 	}
 	_ = uc.notificationRepo.Update(ctx, notification)
 
+	// Resolve the devices again, as they may have changed since it was queued
+	var userID uuid.UUID
+	if notification.RecipientID != nil {
+		userID = *notification.RecipientID
+	}
+	targets := uc.pushTargets(ctx, notification.TenantID, userID, req, []string{notification.DeviceToken})
+
 	// Build push request
 	pushReq := ports.PushRequest{
 		MessageID:   notification.ID.String(),
-		DeviceToken: deviceToken,
+		TenantID:    notification.TenantID.String(),
 		Platform:    req.Platform,
 		Title:       notification.Subject,
 		Body:        notification.Body,
@@ -1271,15 +1927,127 @@ func (uc *notificationUseCase) deliverPush(ctx context.Context, notification *do
 		CollapseKey: req.CollapseKey,
 	}
 
-	// Send push notification
-	response, err := uc.pushProvider.SendPush(ctx, pushReq)
-	uc.handlePushDeliveryResult(ctx, notification, err, response)
+	// Send to each device
+	start := uc.timeProvider.Now()
+	var sent, failed *ports.PushResponse
+	var sendErr error
+	var stale []string
+	for _, target := range targets {
+		pushReq.DeviceToken = target.token
+		pushReq.WebPush = target.webPush
+		if target.platform != "" {
+			pushReq.Platform = target.platform
+		}
+		response, err := uc.pushProvider.SendPush(ctx, pushReq)
+		if err == nil {
+			if sent == nil {
+				sent = response
+			}
+			continue
+		}
+		failed, sendErr = response, err
+		if response != nil && domain.IsStaleDeviceToken(response.FailureReason) {
+			stale = append(stale, target.token)
+		}
+	}
+	latency := uc.timeProvider.Now().Sub(start)
+	uc.unregisterStaleDevices(ctx, notification, stale)
+
+	switch {
+	case len(targets) == 0 || (sent == nil && len(stale) == len(targets)):
+		// No device can receive it; another attempt will not help
+		uc.recordDeliveryFailure(ctx, notification, "push", domain.DeliveryErrorUndeliverable, domain.ErrDeviceNotRegistered)
+		uc.handlePushDeliveryResult(ctx, notification, nil, nil, latency)
+	case sent != nil:
+		uc.handlePushDeliveryResult(ctx, notification, nil, sent, latency)
+	default:
+		uc.handlePushDeliveryResult(ctx, notification, sendErr, failed, latency)
+	}
+}
+
+// pushTarget is a device a push notification is sent to.
+type pushTarget struct {
+	token    string
+	platform string
+	webPush  *ports.WebPushSubscription
+}
+
+// pushTargets returns the devices a push notification is sent to: the
+// device token of the request, or every registered device of the user. The
+// fallback tokens are used when the user has no devices registered with this
+// service or they cannot be loaded.
+func (uc *notificationUseCase) pushTargets(ctx context.Context, tenantID, userID uuid.UUID, req *dto.SendPushRequest, fallback []string) []pushTarget {
+	if req.DeviceToken != "" {
+		return []pushTarget{{token: req.DeviceToken, platform: req.Platform}}
+	}
+
+	if uc.deviceRepo != nil && userID != uuid.Nil {
+		devices, err := uc.deviceRepo.FindByUser(ctx, tenantID, userID)
+		if err != nil {
+			uc.logger.WithContext(ctx).Error("failed to load devices", err, map[string]interface{}{
+				"user_id": userID.String(),
+			})
+		}
+		if len(devices) > 0 {
+			// Expired browser subscriptions are left for the cleanup worker
+			now := uc.timeProvider.Now()
+			targets := make([]pushTarget, 0, len(devices))
+			for _, device := range devices {
+				if device.IsExpired(now) {
+					continue
+				}
+				target := pushTarget{token: device.Token, platform: string(device.Platform)}
+				if device.IsWebPush() {
+					target.webPush = &ports.WebPushSubscription{Endpoint: device.Token, P256DH: device.P256DH, Auth: device.Auth}
+				}
+				targets = append(targets, target)
+			}
+			return targets
+		}
+	}
+
+	var targets []pushTarget
+	for _, token := range fallback {
+		if token != "" {
+			targets = append(targets, pushTarget{token: token, platform: req.Platform})
+		}
+	}
+	// The user service's tokens are not tracked per device; send to the first
+	if len(targets) > 1 {
+		targets = targets[:1]
+	}
+	return targets
 }
 
-func (uc *notificationUseCase) handleDeliveryResult(ctx context.Context, notification *domain.Notification, err error, response *ports.EmailResponse) {
+// unregisterStaleDevices unregisters the devices whose tokens a push
+// provider reported as no longer valid.
+func (uc *notificationUseCase) unregisterStaleDevices(ctx context.Context, notification *domain.Notification, tokens []string) {
+	if uc.deviceRepo == nil || len(tokens) == 0 {
+		return
+	}
+	deleted, err := uc.deviceRepo.DeleteByTokens(ctx, notification.TenantID, tokens)
 	if err != nil {
-		_ = notification.MarkFailed("DELIVERY_FAILED", err.Error(), "")
-		notification.Provider = "email"
+		uc.logger.WithContext(ctx).Error("failed to unregister stale devices", err, map[string]interface{}{
+			"notification_id": notification.ID.String(),
+		})
+		return
+	}
+	uc.metrics.IncrementCounter(ctx, "notification.push.devices_pruned", map[string]string{
+		"tenant_id": notification.TenantID.String(),
+	})
+	uc.logger.WithContext(ctx).Info("unregistered stale devices", map[string]interface{}{
+		"notification_id": notification.ID.String(),
+		"count":           deleted,
+	})
+}
+
+func (uc *notificationUseCase) handleDeliveryResult(ctx context.Context, notification *domain.Notification, err error, response *ports.EmailResponse, latency time.Duration) {
+	if err != nil {
+		provider, errorCode := "email", domain.DeliveryErrorFailed
+		if response != nil {
+			provider, errorCode = response.Provider, domain.ClassifyDeliveryError(response.StatusCode)
+		}
+		uc.recordDeliveryFailure(ctx, notification, provider, errorCode, err)
 	} else if response != nil {
 		_ = notification.MarkSent(response.ProviderID)
 		notification.Provider = response.Provider
@@ -1290,15 +2058,19 @@ func (uc *notificationUseCase) handleDeliveryResult(ctx context.Context, notific
 	if saveErr := uc.notificationRepo.Update(ctx, notification); saveErr != nil {
 		uc.logger.WithContext(ctx).Error("failed to update notification after delivery", saveErr, nil)
 	}
+	uc.recordDeliveryLog(ctx, domain.NewDeliveryAttemptLog(notification, latency))
 
 	// Publish domain events
 	uc.publishDomainEvents(ctx, notification)
 }
 
-func (uc *notificationUseCase) handleSMSDeliveryResult(ctx context.Context, notification *domain.Notification, err error, response *ports.SMSResponse) {
+func (uc *notificationUseCase) handleSMSDeliveryResult(ctx context.Context, notification *domain.Notification, err error, response *ports.SMSResponse, latency time.Duration) {
 	if err != nil {
-		_ = notification.MarkFailed("DELIVERY_FAILED", err.Error(), "")
-		notification.Provider = "sms"
+		provider, errorCode := "sms", domain.DeliveryErrorFailed
+		if response != nil {
+			provider, errorCode = response.Provider, domain.ClassifyDeliveryError(response.StatusCode)
+		}
+		uc.recordDeliveryFailure(ctx, notification, provider, errorCode, err)
 	} else if response != nil {
 		_ = notification.MarkSent(response.ProviderID)
 		notification.Provider = response.Provider
@@ -1308,13 +2080,13 @@ func (uc *notificationUseCase) handleSMSDeliveryResult(ctx context.Context, noti
 	if saveErr := uc.notificationRepo.Update(ctx, notification); saveErr != nil {
 		uc.logger.WithContext(ctx).Error("failed to update notification after delivery", saveErr, nil)
 	}
+	uc.recordDeliveryLog(ctx, domain.NewDeliveryAttemptLog(notification, latency))
 	uc.publishDomainEvents(ctx, notification)
 }
 
-func (uc *notificationUseCase) handleInAppDeliveryResult(ctx context.Context, notification *domain.Notification, err error, response *ports.InAppResponse) {
+func (uc *notificationUseCase) handleInAppDeliveryResult(ctx context.Context, notification *domain.Notification, err error, response *ports.InAppResponse, latency time.Duration) {
 	if err != nil {
-		_ = notification.MarkFailed("DELIVERY_FAILED", err.Error(), "")
-		notification.Provider = "in_app"
+		uc.recordDeliveryFailure(ctx, notification, "in_app", domain.DeliveryErrorFailed, err)
 	} else if response != nil {
 		_ = notification.MarkSent(response.MessageID)
 		notification.Provider = "in_app"
@@ -1323,13 +2095,17 @@ func (uc *notificationUseCase) handleInAppDeliveryResult(ctx context.Context, no
 	if saveErr := uc.notificationRepo.Update(ctx, notification); saveErr != nil {
 		uc.logger.WithContext(ctx).Error("failed to update notification after delivery", saveErr, nil)
 	}
+	uc.recordDeliveryLog(ctx, domain.NewDeliveryAttemptLog(notification, latency))
 	uc.publishDomainEvents(ctx, notification)
 }
 
-func (uc *notificationUseCase) handlePushDeliveryResult(ctx context.Context, notification *domain.Notification, err error, response *ports.PushResponse) {
+func (uc *notificationUseCase) handlePushDeliveryResult(ctx context.Context, notification *domain.Notification, err error, response *ports.PushResponse, latency time.Duration) {
 	if err != nil {
-		_ = notification.MarkFailed("DELIVERY_FAILED", err.Error(), "")
-		notification.Provider = "push"
+		provider, errorCode := "push", domain.DeliveryErrorFailed
+		if response != nil {
+			provider, errorCode = response.Provider, domain.ClassifyDeliveryError(response.StatusCode)
+		}
+		uc.recordDeliveryFailure(ctx, notification, provider, errorCode, err)
 	} else if response != nil {
 		_ = notification.MarkSent(response.ProviderID)
 		notification.Provider = response.Provider
@@ -1339,9 +2115,97 @@ func (uc *notificationUseCase) handlePushDeliveryResult(ctx context.Context, not
 	if saveErr := uc.notificationRepo.Update(ctx, notification); saveErr != nil {
 		uc.logger.WithContext(ctx).Error("failed to update notification after delivery", saveErr, nil)
 	}
+	uc.recordDeliveryLog(ctx, domain.NewDeliveryAttemptLog(notification, latency))
 	uc.publishDomainEvents(ctx, notification)
 }
 
+// recordDeliveryFailure records a failed delivery attempt under the
+// notification's retry policy, which schedules another attempt or fails the
+// notification for good.
+func (uc *notificationUseCase) recordDeliveryFailure(ctx context.Context, notification *domain.Notification, provider, errorCode string, err error) {
+	retrying, recordErr := notification.RecordDeliveryFailure(uc.retryPolicyFor(ctx, notification), provider, errorCode, err.Error(), uc.random())
+	if recordErr != nil {
+		uc.logger.WithContext(ctx).Error("failed to record delivery failure", recordErr, map[string]interface{}{
+			"notification_id": notification.ID.String(),
+		})
+		return
+	}
+
+	outcome := "failed"
+	if retrying {
+		outcome = "retrying"
+	}
+	uc.metrics.IncrementCounter(ctx, "notification.delivery.failure", map[string]string{
+		"channel":    notification.Channel.String(),
+		"error_code": errorCode,
+		"outcome":    outcome,
+	})
+}
+
+// retryPolicyFor returns the retry policy of a notification: its template's
+// override if it has one, otherwise the policy of its channel.
+func (uc *notificationUseCase) retryPolicyFor(ctx context.Context, notification *domain.Notification) domain.RetryPolicy {
+	if notification.TemplateID != nil {
+		template, err := uc.templateRepo.FindByID(ctx, *notification.TemplateID)
+		if err == nil && template != nil && template.RetryPolicy != nil {
+			return *template.RetryPolicy
+		}
+	}
+	return uc.retryPolicies.For(notification.Channel)
+}
+
+// ProcessDueRetries delivers up to limit notifications whose retry is due and
+// returns how many it attempted. Each attempt is recorded like a first
+// delivery, so a notification that fails again is rescheduled or failed by
+// its retry policy.
+func (uc *notificationUseCase) ProcessDueRetries(ctx context.Context, limit int) (int, error) {
+	notifications, err := uc.notificationRepo.FindRetryable(ctx, uc.timeProvider.Now(), limit)
+	if err != nil {
+		return 0, application.NewInternalError("failed to find notifications due for retry", err)
+	}
+
+	processed := 0
+	for _, notification := range notifications {
+		if err := notification.MarkSending(); err != nil {
+			uc.logger.WithContext(ctx).Error("failed to mark notification as sending", err, map[string]interface{}{
+				"notification_id": notification.ID.String(),
+			})
+			continue
+		}
+		if err := uc.notificationRepo.Update(ctx, notification); err != nil {
+			uc.logger.WithContext(ctx).Error("failed to update notification before retry", err, map[string]interface{}{
+				"notification_id": notification.ID.String(),
+			})
+			continue
+		}
+
+		switch notification.Channel {
+		case domain.ChannelEmail:
+			uc.retryEmailDelivery(ctx, notification)
+		case domain.ChannelSMS:
+			uc.retrySMSDelivery(ctx, notification)
+		case domain.ChannelInApp:
+			uc.retryInAppDelivery(ctx, notification)
+		case domain.ChannelPush:
+			uc.retryPushDelivery(ctx, notification)
+		default:
+			uc.recordDeliveryFailure(ctx, notification, notification.Channel.String(), domain.DeliveryErrorFailed,
+				fmt.Errorf("unsupported channel: %s", notification.Channel))
+			if err := uc.notificationRepo.Update(ctx, notification); err != nil {
+				uc.logger.WithContext(ctx).Error("failed to update notification after retry", err, nil)
+			}
+			uc.publishDomainEvents(ctx, notification)
+		}
+		processed++
+
+		uc.metrics.IncrementCounter(ctx, "notification.retry.processed", map[string]string{
+			"channel": notification.Channel.String(),
+		})
+	}
+
+	return processed, nil
+}
+
 func (uc *notificationUseCase) retryEmailDelivery(ctx context.Context, notification *domain.Notification) {
 	emailReq := ports.EmailRequest{
 		MessageID:   notification.ID.String(),
@@ -1355,8 +2219,10 @@ func (uc *notificationUseCase) retryEmailDelivery(ctx context.Context, notificat
 		TrackClicks: notification.TrackClicks,
 	}
 
+	start := uc.timeProvider.Now()
 	response, err := uc.emailProvider.SendEmail(ctx, emailReq)
-	uc.handleDeliveryResult(ctx, notification, err, response)
+	uc.handleDeliveryResult(ctx, notification, err, response, uc.timeProvider.Now().Sub(start))
+	uc.archiveEmail(ctx, notification, emailReq)
 }
 
 func (uc *notificationUseCase) retrySMSDelivery(ctx context.Context, notification *domain.Notification) {
@@ -1366,8 +2232,9 @@ func (uc *notificationUseCase) retrySMSDelivery(ctx context.Context, notificatio
 		Body:      notification.Body,
 	}
 
+	start := uc.timeProvider.Now()
 	response, err := uc.smsProvider.SendSMS(ctx, smsReq)
-	uc.handleSMSDeliveryResult(ctx, notification, err, response)
+	uc.handleSMSDeliveryResult(ctx, notification, err, response, uc.timeProvider.Now().Sub(start))
 }
 
 func (uc *notificationUseCase) retryInAppDelivery(ctx context.Context, notification *domain.Notification) {
@@ -1379,8 +2246,9 @@ func (uc *notificationUseCase) retryInAppDelivery(ctx context.Context, notificat
 		Priority:  notification.Priority.String(),
 	}
 
+	start := uc.timeProvider.Now()
 	response, err := uc.inAppProvider.SendInApp(ctx, inAppReq)
-	uc.handleInAppDeliveryResult(ctx, notification, err, response)
+	uc.handleInAppDeliveryResult(ctx, notification, err, response, uc.timeProvider.Now().Sub(start))
 }
 
 func (uc *notificationUseCase) retryPushDelivery(ctx context.Context, notification *domain.Notification) {
@@ -1392,6 +2260,7 @@ func (uc *notificationUseCase) retryPushDelivery(ctx context.Context, notificati
 		Priority:    notification.Priority.String(),
 	}
 
+	start := uc.timeProvider.Now()
 	response, err := uc.pushProvider.SendPush(ctx, pushReq)
-	uc.handlePushDeliveryResult(ctx, notification, err, response)
+	uc.handlePushDeliveryResult(ctx, notification, err, response, uc.timeProvider.Now().Sub(start))
 }
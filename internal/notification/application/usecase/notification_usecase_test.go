@@ -4,6 +4,7 @@ package usecase
 import (
 	"context"
 	"errors"
+	"strings"
 	"sync"
 	"testing"
 	"time"
@@ -94,6 +95,17 @@ func (m *MockNotificationRepository) FindByCode(ctx context.Context, tenantID uu
 	return nil, errors.New("notification not found")
 }
 
+func (m *MockNotificationRepository) FindByProviderMessageID(ctx context.Context, provider, providerMessageID string) (*domain.Notification, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	for _, n := range m.notifications {
+		if n.Provider == provider && n.ProviderMessageID == providerMessageID {
+			return n, nil
+		}
+	}
+	return nil, errors.New("notification not found")
+}
+
 func (m *MockNotificationRepository) List(ctx context.Context, filter domain.NotificationFilter) (*domain.NotificationList, error) {
 	if m.listErr != nil {
 		return nil, m.listErr
@@ -139,7 +151,18 @@ func (m *MockNotificationRepository) FindScheduled(ctx context.Context, before t
 }
 
 func (m *MockNotificationRepository) FindRetryable(ctx context.Context, before time.Time, limit int) ([]*domain.Notification, error) {
-	return nil, nil
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	var result []*domain.Notification
+	for _, n := range m.notifications {
+		if n.Status == domain.StatusRetrying && n.NextRetryAt != nil && !n.NextRetryAt.After(before) {
+			result = append(result, n)
+		}
+	}
+	if limit > 0 && len(result) > limit {
+		result = result[:limit]
+	}
+	return result, nil
 }
 
 func (m *MockNotificationRepository) FindByBatch(ctx context.Context, batchID uuid.UUID) ([]*domain.Notification, error) {
@@ -162,8 +185,50 @@ func (m *MockNotificationRepository) CountByChannel(ctx context.Context, tenantI
 	return nil, nil
 }
 
-func (m *MockNotificationRepository) GetStats(ctx context.Context, tenantID uuid.UUID, startDate, endDate time.Time) (*domain.NotificationStats, error) {
-	return nil, nil
+func (m *MockNotificationRepository) GetStats(ctx context.Context, query domain.NotificationStatsQuery) (*domain.NotificationStats, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	stats := &domain.NotificationStats{
+		ChannelCounts: make(map[domain.NotificationChannel]domain.DeliveryCounts),
+		ByStatus:      make(map[domain.NotificationStatus]int64),
+	}
+	templates := make(map[uuid.UUID]*domain.TemplateDeliveryCounts)
+	buckets := make(map[time.Time]*domain.StatsBucket)
+	for _, n := range m.notifications {
+		if n.TenantID != query.TenantID || n.CreatedAt.Before(query.StartDate) || n.CreatedAt.After(query.EndDate) {
+			continue
+		}
+		if query.Channel != nil && n.Channel != *query.Channel {
+			continue
+		}
+
+		stats.Counts.Count(n)
+		channelCounts := stats.ChannelCounts[n.Channel]
+		channelCounts.Count(n)
+		stats.ChannelCounts[n.Channel] = channelCounts
+		stats.ByStatus[n.Status]++
+
+		if n.TemplateID != nil {
+			if templates[*n.TemplateID] == nil {
+				templates[*n.TemplateID] = &domain.TemplateDeliveryCounts{TemplateID: *n.TemplateID, TemplateName: n.TemplateName}
+			}
+			templates[*n.TemplateID].Count(n)
+		}
+
+		start := query.Interval.Truncate(n.CreatedAt)
+		if buckets[start] == nil {
+			buckets[start] = &domain.StatsBucket{Start: start}
+		}
+		buckets[start].Count(n)
+	}
+	for _, t := range templates {
+		stats.TemplateCounts = append(stats.TemplateCounts, *t)
+	}
+	for _, b := range buckets {
+		stats.Series = append(stats.Series, *b)
+	}
+	return stats, nil
 }
 
 func (m *MockNotificationRepository) BulkCreate(ctx context.Context, notifications []*domain.Notification) error {
@@ -214,6 +279,30 @@ func (m *MockNotificationRepository) DeleteOld(ctx context.Context, before time.
 	return 0, nil
 }
 
+func (m *MockNotificationRepository) PurgeBodies(ctx context.Context, filter domain.BodyPurgeFilter) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var purged int64
+	for _, n := range m.notifications {
+		if !n.CreatedAt.Before(filter.Before) || !(n.Status.IsSuccess() || n.Status.IsFinal()) || (n.Body == "" && n.HTMLBody == "") {
+			continue
+		}
+		if filter.TenantID != nil && n.TenantID != *filter.TenantID {
+			continue
+		}
+		excluded := false
+		for _, tenantID := range filter.ExcludeTenantIDs {
+			excluded = excluded || n.TenantID == tenantID
+		}
+		if excluded {
+			continue
+		}
+		n.Body, n.HTMLBody = "", ""
+		purged++
+	}
+	return purged, nil
+}
+
 func (m *MockNotificationRepository) SetCreateError(err error) {
 	m.createErr = err
 }
@@ -230,6 +319,160 @@ func (m *MockNotificationRepository) SetListError(err error) {
 	m.listErr = err
 }
 
+// MockDeliveryLogRepository is a mock implementation of DeliveryLogRepository.
+type MockDeliveryLogRepository struct {
+	mu   sync.RWMutex
+	logs []*domain.DeliveryLog
+}
+
+func NewMockDeliveryLogRepository() *MockDeliveryLogRepository {
+	return &MockDeliveryLogRepository{}
+}
+
+func (m *MockDeliveryLogRepository) Create(ctx context.Context, log *domain.DeliveryLog) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.logs = append(m.logs, log)
+	return nil
+}
+
+func (m *MockDeliveryLogRepository) FindByNotification(ctx context.Context, notificationID uuid.UUID) ([]*domain.DeliveryLog, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	var result []*domain.DeliveryLog
+	for _, log := range m.logs {
+		if log.NotificationID == notificationID {
+			result = append(result, log)
+		}
+	}
+	return result, nil
+}
+
+func (m *MockDeliveryLogRepository) FindByTenant(ctx context.Context, tenantID uuid.UUID, startTime, endTime time.Time, limit int) ([]*domain.DeliveryLog, error) {
+	return nil, nil
+}
+
+func (m *MockDeliveryLogRepository) DeleteOld(ctx context.Context, before time.Time) (int64, error) {
+	return 0, nil
+}
+
+// MockArchivedEmailRepository is a mock implementation of ArchivedEmailRepository.
+type MockArchivedEmailRepository struct {
+	mu     sync.RWMutex
+	emails map[uuid.UUID]*domain.ArchivedEmail
+}
+
+func NewMockArchivedEmailRepository() *MockArchivedEmailRepository {
+	return &MockArchivedEmailRepository{emails: make(map[uuid.UUID]*domain.ArchivedEmail)}
+}
+
+func (m *MockArchivedEmailRepository) Create(ctx context.Context, email *domain.ArchivedEmail) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.emails[email.ID] = email
+	return nil
+}
+
+func (m *MockArchivedEmailRepository) FindByID(ctx context.Context, id uuid.UUID) (*domain.ArchivedEmail, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	if email, ok := m.emails[id]; ok {
+		return email, nil
+	}
+	return nil, domain.ErrArchivedEmailNotFound
+}
+
+func (m *MockArchivedEmailRepository) FindByNotification(ctx context.Context, notificationID uuid.UUID) (*domain.ArchivedEmail, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	for _, email := range m.emails {
+		if email.NotificationID == notificationID {
+			return email, nil
+		}
+	}
+	return nil, domain.ErrArchivedEmailNotFound
+}
+
+func (m *MockArchivedEmailRepository) Search(ctx context.Context, filter domain.ArchivedEmailFilter) (*domain.ArchivedEmailList, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	var result []*domain.ArchivedEmail
+	for _, email := range m.emails {
+		if email.TenantID != filter.TenantID {
+			continue
+		}
+		if filter.Subject != "" && !strings.Contains(strings.ToLower(email.Subject), strings.ToLower(filter.Subject)) {
+			continue
+		}
+		if filter.Recipient != "" && !strings.Contains(strings.ToLower(strings.Join(email.Recipients, ",")), strings.ToLower(filter.Recipient)) {
+			continue
+		}
+		if filter.SentAfter != nil && email.SentAt.Before(*filter.SentAfter) {
+			continue
+		}
+		if filter.SentBefore != nil && !email.SentAt.Before(*filter.SentBefore) {
+			continue
+		}
+		result = append(result, email)
+	}
+	return &domain.ArchivedEmailList{Emails: result, Total: int64(len(result)), Offset: filter.Offset, Limit: filter.Limit}, nil
+}
+
+func (m *MockArchivedEmailRepository) FindExpired(ctx context.Context, now time.Time, limit int) ([]*domain.ArchivedEmail, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	var result []*domain.ArchivedEmail
+	for _, email := range m.emails {
+		if email.IsExpired(now) && len(result) < limit {
+			result = append(result, email)
+		}
+	}
+	return result, nil
+}
+
+func (m *MockArchivedEmailRepository) DeleteByIDs(ctx context.Context, ids []uuid.UUID) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, id := range ids {
+		delete(m.emails, id)
+	}
+	return int64(len(ids)), nil
+}
+
+// MockFileStorage is a mock implementation of FileStorage.
+type MockFileStorage struct {
+	mu      sync.RWMutex
+	objects map[string][]byte
+}
+
+func NewMockFileStorage() *MockFileStorage {
+	return &MockFileStorage{objects: make(map[string][]byte)}
+}
+
+func (m *MockFileStorage) Upload(ctx context.Context, key string, content []byte, contentType string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.objects[key] = content
+	return nil
+}
+
+func (m *MockFileStorage) Download(ctx context.Context, key string) ([]byte, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	content, ok := m.objects[key]
+	if !ok {
+		return nil, errors.New("object not found")
+	}
+	return content, nil
+}
+
+func (m *MockFileStorage) Delete(ctx context.Context, key string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.objects, key)
+	return nil
+}
+
 // MockTemplateRepository is a mock implementation of TemplateRepository.
 type MockTemplateRepository struct {
 	mu        sync.RWMutex
@@ -377,6 +620,7 @@ type MockEmailProvider struct {
 	mu        sync.RWMutex
 	sent      []ports.EmailRequest
 	sendErr   error
+	errStatus int // HTTP status of the response returned with sendErr, if any
 	available bool
 }
 
@@ -389,6 +633,9 @@ func NewMockEmailProvider() *MockEmailProvider {
 
 func (m *MockEmailProvider) SendEmail(ctx context.Context, request ports.EmailRequest) (*ports.EmailResponse, error) {
 	if m.sendErr != nil {
+		if m.errStatus > 0 {
+			return &ports.EmailResponse{MessageID: request.MessageID, Provider: "mock", StatusCode: m.errStatus}, m.sendErr
+		}
 		return nil, m.sendErr
 	}
 	m.mu.Lock()
@@ -491,6 +738,7 @@ type MockPushProvider struct {
 	mu        sync.RWMutex
 	sent      []ports.PushRequest
 	sendErr   error
+	rejected  map[string]string // failure reason by device token
 	available bool
 }
 
@@ -506,8 +754,20 @@ func (m *MockPushProvider) SendPush(ctx context.Context, request ports.PushReque
 		return nil, m.sendErr
 	}
 	m.mu.Lock()
-	m.sent = append(m.sent, request)
+	reason, rejected := m.rejected[request.DeviceToken]
+	if !rejected {
+		m.sent = append(m.sent, request)
+	}
 	m.mu.Unlock()
+	if rejected {
+		return &ports.PushResponse{
+			MessageID:     request.MessageID,
+			Provider:      "mock",
+			Status:        "failed",
+			StatusCode:    404,
+			FailureReason: reason,
+		}, errors.New("push rejected: " + reason)
+	}
 
 	return &ports.PushResponse{
 		MessageID:  request.MessageID,
@@ -534,6 +794,21 @@ func (m *MockPushProvider) SetSendError(err error) {
 	m.sendErr = err
 }
 
+func (m *MockPushProvider) Reject(token, reason string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.rejected == nil {
+		m.rejected = make(map[string]string)
+	}
+	m.rejected[token] = reason
+}
+
+func (m *MockPushProvider) GetSentPushes() []ports.PushRequest {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return append([]ports.PushRequest(nil), m.sent...)
+}
+
 // MockInAppProvider is a mock implementation of InAppProvider.
 type MockInAppProvider struct {
 	mu        sync.RWMutex
@@ -721,6 +996,11 @@ func (m *MockUserService) GetUser(ctx context.Context, userID string) (*ports.Us
 }
 
 func (m *MockUserService) GetUserByEmail(ctx context.Context, email string) (*ports.UserInfo, error) {
+	for _, user := range m.users {
+		if user.Email == email {
+			return user, nil
+		}
+	}
 	return nil, nil
 }
 
@@ -743,6 +1023,15 @@ func (m *MockUserService) SetError(err error) {
 	m.err = err
 }
 
+// MockTenantBrandingProvider is a mock implementation of TenantBrandingProvider.
+type MockTenantBrandingProvider struct {
+	branding map[string]*ports.TenantBranding
+}
+
+func (m *MockTenantBrandingProvider) GetTenantBranding(ctx context.Context, tenantID string) (*ports.TenantBranding, error) {
+	return m.branding[tenantID], nil
+}
+
 // MockScheduler is a mock implementation of Scheduler.
 type MockScheduler struct {
 	scheduled map[string]time.Time
@@ -780,6 +1069,33 @@ func (m *MockScheduler) SetError(err error) {
 	m.err = err
 }
 
+// MockDeliveryQueue is a mock implementation of DeliveryQueue.
+type MockDeliveryQueue struct {
+	mu   sync.Mutex
+	jobs []*ports.DeliveryJob
+	err  error
+}
+
+func NewMockDeliveryQueue() *MockDeliveryQueue {
+	return &MockDeliveryQueue{}
+}
+
+func (m *MockDeliveryQueue) Enqueue(ctx context.Context, job *ports.DeliveryJob) error {
+	if m.err != nil {
+		return m.err
+	}
+	m.mu.Lock()
+	m.jobs = append(m.jobs, job)
+	m.mu.Unlock()
+	return nil
+}
+
+func (m *MockDeliveryQueue) GetJobs() []*ports.DeliveryJob {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]*ports.DeliveryJob(nil), m.jobs...)
+}
+
 // MockSuppressionService is a mock implementation of SuppressionService.
 type MockSuppressionService struct {
 	suppressions map[string]bool
@@ -939,6 +1255,8 @@ func createTestUseCase(t *testing.T) (*notificationUseCase, *TestMocks) {
 	mocks := &TestMocks{
 		NotificationRepo:   NewMockNotificationRepository(),
 		TemplateRepo:       NewMockTemplateRepository(),
+		DeliveryLogRepo:    NewMockDeliveryLogRepository(),
+		ArchiveRepo:        NewMockArchivedEmailRepository(),
 		EmailProvider:      NewMockEmailProvider(),
 		SMSProvider:        NewMockSMSProvider(),
 		PushProvider:       NewMockPushProvider(),
@@ -958,6 +1276,8 @@ func createTestUseCase(t *testing.T) (*notificationUseCase, *TestMocks) {
 	cfg := NotificationUseCaseConfig{
 		NotificationRepo:   mocks.NotificationRepo,
 		TemplateRepo:       mocks.TemplateRepo,
+		DeliveryLogRepo:    mocks.DeliveryLogRepo,
+		ArchiveRepo:        mocks.ArchiveRepo,
 		EmailProvider:      mocks.EmailProvider,
 		SMSProvider:        mocks.SMSProvider,
 		PushProvider:       mocks.PushProvider,
@@ -981,6 +1301,8 @@ func createTestUseCase(t *testing.T) (*notificationUseCase, *TestMocks) {
 type TestMocks struct {
 	NotificationRepo   *MockNotificationRepository
 	TemplateRepo       *MockTemplateRepository
+	DeliveryLogRepo    *MockDeliveryLogRepository
+	ArchiveRepo        *MockArchivedEmailRepository
 	EmailProvider      *MockEmailProvider
 	SMSProvider        *MockSMSProvider
 	PushProvider       *MockPushProvider
@@ -1087,6 +1409,107 @@ func TestSendEmail_Success(t *testing.T) {
 	}
 }
 
+func TestSendEmail_TemplateInRecipientLocale(t *testing.T) {
+	uc, mocks := createTestUseCase(t)
+	ctx := context.Background()
+
+	req := createTestEmailRequest()
+	tenantID := uuid.MustParse(req.TenantID)
+
+	template, _ := domain.NewNotificationTemplate(tenantID, "welcome", "Welcome", domain.TypeTransactional)
+	template.SetEmailTemplate(&domain.EmailTemplateContent{Subject: "Welcome {{.Name}}", Body: "Hello"})
+	template.AddLocalization("ms", &domain.TemplateLocalization{
+		EmailTemplate: &domain.EmailTemplateContent{Subject: "Selamat datang {{.Name}}", Body: "Helo"},
+	})
+	mocks.TemplateRepo.Create(ctx, template)
+
+	user := createTestUser(uuid.New().String())
+	user.Email = req.To[0]
+	user.Locale = "ms-MY"
+	mocks.UserService.AddUser(user)
+
+	req.Subject = ""
+	req.TemplateID = template.ID.String()
+	req.Variables = map[string]interface{}{"Name": "Aisyah"}
+
+	resp, err := uc.SendEmail(ctx, req)
+	if err != nil {
+		t.Fatalf("SendEmail failed: %v", err)
+	}
+
+	notification, _ := mocks.NotificationRepo.FindByID(ctx, uuid.MustParse(resp.NotificationID))
+	if notification.Subject != "Selamat datang Aisyah" {
+		t.Errorf("expected the ms subject for an ms-MY recipient, got %q", notification.Subject)
+	}
+
+	// An explicit locale wins over the recipient's
+	req.Locale = "en"
+	resp, err = uc.SendEmail(ctx, req)
+	if err != nil {
+		t.Fatalf("SendEmail failed: %v", err)
+	}
+
+	notification, _ = mocks.NotificationRepo.FindByID(ctx, uuid.MustParse(resp.NotificationID))
+	if notification.Subject != "Welcome Aisyah" {
+		t.Errorf("expected the default subject, got %q", notification.Subject)
+	}
+}
+
+func TestSendEmail_TenantBranding(t *testing.T) {
+	uc, mocks := createTestUseCase(t)
+	ctx := context.Background()
+
+	req := createTestEmailRequest()
+	tenantID := uuid.MustParse(req.TenantID)
+	uc.tenantBranding = &MockTenantBrandingProvider{branding: map[string]*ports.TenantBranding{
+		req.TenantID: {
+			TenantName:       "TheirBrand",
+			LogoURL:          "https://cdn.theirbrand.com/logo.png",
+			PrimaryColor:     "#1a73e8",
+			EmailFromAddress: "sales@theirbrand.com",
+			EmailFromName:    "TheirBrand Sales",
+		},
+	}}
+
+	template, _ := domain.NewNotificationTemplate(tenantID, "welcome", "Welcome", domain.TypeTransactional)
+	template.SetEmailTemplate(&domain.EmailTemplateContent{
+		Subject:  "Welcome to {{.branding.tenant_name}}",
+		Body:     "Hello",
+		HTMLBody: `<img src="{{.branding.logo_url}}"><h1 style="color: {{.branding.primary_color}}">Hello</h1>`,
+	})
+	mocks.TemplateRepo.Create(ctx, template)
+
+	req.Subject = ""
+	req.TemplateID = template.ID.String()
+
+	resp, err := uc.SendEmail(ctx, req)
+	if err != nil {
+		t.Fatalf("SendEmail failed: %v", err)
+	}
+
+	notification, _ := mocks.NotificationRepo.FindByID(ctx, uuid.MustParse(resp.NotificationID))
+	if notification.Subject != "Welcome to TheirBrand" {
+		t.Errorf("expected the tenant name in the subject, got %q", notification.Subject)
+	}
+	if !strings.Contains(notification.HTMLBody, "https://cdn.theirbrand.com/logo.png") || !strings.Contains(notification.HTMLBody, "#1a73e8") {
+		t.Errorf("expected the logo and color in the HTML body, got %q", notification.HTMLBody)
+	}
+	if notification.FromAddress != "sales@theirbrand.com" || notification.FromName != "TheirBrand Sales" {
+		t.Errorf("expected the tenant's from address, got %q <%s>", notification.FromName, notification.FromAddress)
+	}
+
+	// An explicit sender wins over the tenant's
+	req.From = "noreply@example.com"
+	resp, err = uc.SendEmail(ctx, req)
+	if err != nil {
+		t.Fatalf("SendEmail failed: %v", err)
+	}
+	notification, _ = mocks.NotificationRepo.FindByID(ctx, uuid.MustParse(resp.NotificationID))
+	if notification.FromAddress != "noreply@example.com" {
+		t.Errorf("expected the request's from address, got %q", notification.FromAddress)
+	}
+}
+
 func TestSendEmail_ValidationErrors(t *testing.T) {
 	uc, _ := createTestUseCase(t)
 	ctx := context.Background()
@@ -1593,41 +2016,126 @@ func TestSendPushNotification_NoDeviceToken(t *testing.T) {
 	}
 }
 
-func TestSendPushNotification_UserNotFound(t *testing.T) {
-	uc, _ := createTestUseCase(t)
+// queuePushToDevices sends a push notification to every device of a user
+// registered with tokens, returning its delivery job.
+func queuePushToDevices(t *testing.T, uc *notificationUseCase, mocks *TestMocks, tokens ...string) *ports.DeliveryJob {
+	t.Helper()
 	ctx := context.Background()
+	queue := NewMockDeliveryQueue()
+	uc.deliveryQueue = queue
+	devices := NewMockDeviceRepository()
+	uc.deviceRepo = devices
 
-	req := createTestPushRequest(uuid.New().String())
-
-	_, err := uc.SendPushNotification(ctx, req)
+	userID := uuid.New().String()
+	user := createTestUser(userID)
+	mocks.UserService.AddUser(user)
+	for _, token := range tokens {
+		device, err := domain.NewDevice(uuid.MustParse(user.TenantID), uuid.MustParse(userID), domain.PlatformAndroid, token, "1.0.0")
+		if err != nil {
+			t.Fatalf("NewDevice failed: %v", err)
+		}
+		devices.Register(ctx, device)
+	}
 
-	if err == nil {
-		t.Fatal("expected user not found error, got nil")
+	req := createTestPushRequest(userID)
+	req.TenantID = user.TenantID
+	req.DeviceToken = ""
+	if _, err := uc.SendPushNotification(ctx, req); err != nil {
+		t.Fatalf("SendPushNotification failed: %v", err)
 	}
+	return queue.GetJobs()[0]
 }
 
-// ============================================================================
-// GetNotification Tests
-// ============================================================================
-
-func TestGetNotification_Success(t *testing.T) {
+func TestSendPushNotification_FansOutAndPrunesStaleDevices(t *testing.T) {
 	uc, mocks := createTestUseCase(t)
 	ctx := context.Background()
 
-	tenantID := uuid.New()
-	notification := createTestNotification(tenantID, domain.ChannelEmail)
-	notification.RecipientEmail = "test@example.com"
-	notification.Subject = "Test Subject"
-	mocks.NotificationRepo.Create(ctx, notification)
+	job := queuePushToDevices(t, uc, mocks, "token-phone", "token-tablet", "token-old-phone")
+	mocks.PushProvider.Reject("token-old-phone", "UNREGISTERED")
 
-	req := &dto.GetNotificationRequest{
-		TenantID:       tenantID.String(),
-		NotificationID: notification.ID.String(),
+	if err := uc.DeliverQueuedNotification(ctx, job); err != nil {
+		t.Fatalf("DeliverQueuedNotification failed: %v", err)
 	}
 
-	result, err := uc.GetNotification(ctx, req)
-	if err != nil {
-		t.Fatalf("GetNotification failed: %v", err)
+	sent := mocks.PushProvider.GetSentPushes()
+	if len(sent) != 2 {
+		t.Fatalf("expected the push sent to 2 devices, got %d", len(sent))
+	}
+	for _, push := range sent {
+		if push.Platform != "android" {
+			t.Errorf("expected the device's platform, got %q", push.Platform)
+		}
+	}
+
+	notification, _ := mocks.NotificationRepo.FindByID(ctx, uuid.MustParse(job.NotificationID))
+	if notification.Status != domain.StatusSent {
+		t.Errorf("expected status sent, got %s", notification.Status)
+	}
+	remaining, _ := uc.deviceRepo.FindByUser(ctx, notification.TenantID, *notification.RecipientID)
+	if len(remaining) != 2 {
+		t.Errorf("expected the stale device unregistered, %d devices remain", len(remaining))
+	}
+	if mocks.Metrics.GetCounter("notification.push.devices_pruned") != 1 {
+		t.Error("expected notification.push.devices_pruned to be incremented")
+	}
+}
+
+func TestSendPushNotification_AllDevicesStale(t *testing.T) {
+	uc, mocks := createTestUseCase(t)
+	ctx := context.Background()
+
+	job := queuePushToDevices(t, uc, mocks, "token-a", "token-b")
+	mocks.PushProvider.Reject("token-a", "BadDeviceToken")
+	mocks.PushProvider.Reject("token-b", "UNREGISTERED")
+
+	if err := uc.DeliverQueuedNotification(ctx, job); err != nil {
+		t.Fatalf("DeliverQueuedNotification failed: %v", err)
+	}
+
+	notification, _ := mocks.NotificationRepo.FindByID(ctx, uuid.MustParse(job.NotificationID))
+	if notification.Status != domain.StatusFailed {
+		t.Errorf("expected the notification failed without retrying, got %s", notification.Status)
+	}
+	if remaining, _ := uc.deviceRepo.FindByUser(ctx, notification.TenantID, *notification.RecipientID); len(remaining) != 0 {
+		t.Errorf("expected every device unregistered, %d remain", len(remaining))
+	}
+}
+
+func TestSendPushNotification_UserNotFound(t *testing.T) {
+	uc, _ := createTestUseCase(t)
+	ctx := context.Background()
+
+	req := createTestPushRequest(uuid.New().String())
+
+	_, err := uc.SendPushNotification(ctx, req)
+
+	if err == nil {
+		t.Fatal("expected user not found error, got nil")
+	}
+}
+
+// ============================================================================
+// GetNotification Tests
+// ============================================================================
+
+func TestGetNotification_Success(t *testing.T) {
+	uc, mocks := createTestUseCase(t)
+	ctx := context.Background()
+
+	tenantID := uuid.New()
+	notification := createTestNotification(tenantID, domain.ChannelEmail)
+	notification.RecipientEmail = "test@example.com"
+	notification.Subject = "Test Subject"
+	mocks.NotificationRepo.Create(ctx, notification)
+
+	req := &dto.GetNotificationRequest{
+		TenantID:       tenantID.String(),
+		NotificationID: notification.ID.String(),
+	}
+
+	result, err := uc.GetNotification(ctx, req)
+	if err != nil {
+		t.Fatalf("GetNotification failed: %v", err)
 	}
 
 	if result == nil {
@@ -1733,6 +2241,795 @@ func TestGetNotification_InvalidIDs(t *testing.T) {
 	}
 }
 
+// ============================================================================
+// Email Archive Tests
+// ============================================================================
+
+func TestDeliverEmail_ArchivesSentEmail(t *testing.T) {
+	uc, mocks := createTestUseCase(t)
+	ctx := context.Background()
+
+	notification := createTestNotification(uuid.New(), domain.ChannelEmail)
+	notification.Subject = "Your batik order"
+	notification.FromAddress = "orders@kilang.example"
+	notification.CC = []string{"sales@kilang.example"}
+	notification.BCC = []string{"audit@kilang.example"}
+	mocks.NotificationRepo.Create(ctx, notification)
+
+	notification.Status = domain.StatusQueued
+	uc.deliverEmail(ctx, notification, &dto.SendEmailRequest{To: []string{"aisyah@example.com"}})
+
+	archived, err := mocks.ArchiveRepo.FindByNotification(ctx, notification.ID)
+	if err != nil {
+		t.Fatalf("expected sent email to be archived: %v", err)
+	}
+	if !archived.IsInline() {
+		t.Error("expected small email to be stored inline")
+	}
+	if len(archived.Recipients) != 3 {
+		t.Errorf("expected To, CC and BCC recipients, got %v", archived.Recipients)
+	}
+	if archived.Provider != "mock" || archived.ProviderMessageID == "" {
+		t.Errorf("expected provider details, got %q %q", archived.Provider, archived.ProviderMessageID)
+	}
+	if !strings.Contains(string(archived.MIME), "Subject: Your batik order") {
+		t.Errorf("expected MIME message to contain the subject, got:\n%s", archived.MIME)
+	}
+	if strings.Contains(string(archived.MIME), "audit@kilang.example") {
+		t.Error("BCC recipients must not appear in the MIME message")
+	}
+	want := archived.SentAt.Add(domain.DefaultArchiveRetentionDays * 24 * time.Hour)
+	if !archived.ExpiresAt.Equal(want) {
+		t.Errorf("expected default retention, expires at %v, want %v", archived.ExpiresAt, want)
+	}
+}
+
+func TestDeliverEmail_FailedEmailNotArchived(t *testing.T) {
+	uc, mocks := createTestUseCase(t)
+	ctx := context.Background()
+
+	notification := createTestNotification(uuid.New(), domain.ChannelEmail)
+	mocks.NotificationRepo.Create(ctx, notification)
+	mocks.EmailProvider.SetSendError(errors.New("provider down"))
+
+	notification.Status = domain.StatusQueued
+	uc.deliverEmail(ctx, notification, &dto.SendEmailRequest{To: []string{"aisyah@example.com"}})
+
+	if _, err := mocks.ArchiveRepo.FindByNotification(ctx, notification.ID); err == nil {
+		t.Error("expected failed email not to be archived")
+	}
+}
+
+func TestDeliverEmail_LargeEmailArchivedInStorage(t *testing.T) {
+	uc, mocks := createTestUseCase(t)
+	ctx := context.Background()
+	storage := NewMockFileStorage()
+	uc.archiveStorage = storage
+	uc.archiveInlineLimit = 64
+	tenantID := uuid.New()
+	uc.archiveRetention = domain.ArchiveRetention{DefaultDays: 365, TenantDays: map[uuid.UUID]int{tenantID: 30}}
+
+	notification := createTestNotification(tenantID, domain.ChannelEmail)
+	notification.Subject = "Catalogue"
+	mocks.NotificationRepo.Create(ctx, notification)
+
+	notification.Status = domain.StatusQueued
+	uc.deliverEmail(ctx, notification, &dto.SendEmailRequest{To: []string{"aisyah@example.com"}})
+
+	archived, err := mocks.ArchiveRepo.FindByNotification(ctx, notification.ID)
+	if err != nil {
+		t.Fatalf("expected sent email to be archived: %v", err)
+	}
+	if archived.IsInline() || archived.MIME != nil {
+		t.Fatal("expected large email to be moved to storage")
+	}
+	if archived.ExpiresAt.Sub(archived.SentAt) != 30*24*time.Hour {
+		t.Errorf("expected tenant retention of 30 days, got %v", archived.ExpiresAt.Sub(archived.SentAt))
+	}
+
+	content, err := uc.GetArchivedEmailContent(ctx, &dto.GetArchivedEmailRequest{
+		TenantID:  tenantID.String(),
+		ArchiveID: archived.ID.String(),
+	})
+	if err != nil {
+		t.Fatalf("GetArchivedEmailContent failed: %v", err)
+	}
+	if int64(len(content.Content)) != archived.SizeBytes || !strings.HasSuffix(content.Filename, ".eml") {
+		t.Errorf("unexpected content: %d bytes, filename %q", len(content.Content), content.Filename)
+	}
+}
+
+func TestSearchArchivedEmails(t *testing.T) {
+	uc, mocks := createTestUseCase(t)
+	ctx := context.Background()
+	tenantID := uuid.New()
+
+	for _, subject := range []string{"Invoice INV-001", "Invoice INV-002", "Welcome"} {
+		notification := createTestNotification(tenantID, domain.ChannelEmail)
+		notification.Subject = subject
+		mocks.NotificationRepo.Create(ctx, notification)
+		notification.Status = domain.StatusQueued
+		uc.deliverEmail(ctx, notification, &dto.SendEmailRequest{To: []string{"aisyah@example.com"}})
+	}
+	other := createTestNotification(uuid.New(), domain.ChannelEmail)
+	other.Subject = "Invoice INV-900"
+	other.Status = domain.StatusQueued
+	uc.deliverEmail(ctx, other, &dto.SendEmailRequest{To: []string{"aisyah@example.com"}})
+
+	result, err := uc.SearchArchivedEmails(ctx, &dto.SearchArchivedEmailsRequest{
+		TenantID:  tenantID.String(),
+		Subject:   "invoice",
+		Recipient: "aisyah",
+		Page:      1,
+		PageSize:  20,
+	})
+	if err != nil {
+		t.Fatalf("SearchArchivedEmails failed: %v", err)
+	}
+	if result.TotalCount != 2 || len(result.Items) != 2 {
+		t.Errorf("expected 2 archived invoices of the tenant, got %d", result.TotalCount)
+	}
+
+	after := time.Now().Add(time.Hour)
+	before := time.Now()
+	if _, err := uc.SearchArchivedEmails(ctx, &dto.SearchArchivedEmailsRequest{
+		TenantID:   tenantID.String(),
+		SentAfter:  &after,
+		SentBefore: &before,
+	}); err == nil {
+		t.Error("expected error when sent_after is after sent_before")
+	}
+}
+
+func TestGetArchivedEmail_OtherTenantForbidden(t *testing.T) {
+	uc, mocks := createTestUseCase(t)
+	ctx := context.Background()
+
+	notification := createTestNotification(uuid.New(), domain.ChannelEmail)
+	mocks.NotificationRepo.Create(ctx, notification)
+	notification.Status = domain.StatusQueued
+	uc.deliverEmail(ctx, notification, &dto.SendEmailRequest{To: []string{"aisyah@example.com"}})
+	archived, _ := mocks.ArchiveRepo.FindByNotification(ctx, notification.ID)
+
+	_, err := uc.GetArchivedEmail(ctx, &dto.GetArchivedEmailRequest{
+		TenantID:  uuid.New().String(),
+		ArchiveID: archived.ID.String(),
+	})
+	if err == nil {
+		t.Fatal("expected error for another tenant's archived email")
+	}
+	if appErr, ok := err.(*application.AppError); !ok || appErr.Code != application.ErrCodeForbidden {
+		t.Errorf("expected forbidden error, got %v", err)
+	}
+}
+
+func TestPurgeExpiredArchive(t *testing.T) {
+	uc, mocks := createTestUseCase(t)
+	ctx := context.Background()
+	storage := NewMockFileStorage()
+	uc.archiveStorage = storage
+
+	now := mocks.TimeProvider.NowUTC()
+	expired := &domain.ArchivedEmail{ID: uuid.New(), StorageKey: "email-archive/old.eml", ExpiresAt: now.Add(-time.Hour)}
+	current := &domain.ArchivedEmail{ID: uuid.New(), ExpiresAt: now.Add(time.Hour)}
+	storage.Upload(ctx, expired.StorageKey, []byte("old"), "message/rfc822")
+	mocks.ArchiveRepo.Create(ctx, expired)
+	mocks.ArchiveRepo.Create(ctx, current)
+
+	deleted, err := uc.PurgeExpiredArchive(ctx, 100)
+	if err != nil {
+		t.Fatalf("PurgeExpiredArchive failed: %v", err)
+	}
+	if deleted != 1 {
+		t.Errorf("expected 1 archived email deleted, got %d", deleted)
+	}
+	if _, err := mocks.ArchiveRepo.FindByID(ctx, current.ID); err != nil {
+		t.Error("expected unexpired archived email to be kept")
+	}
+	if _, err := storage.Download(ctx, expired.StorageKey); err == nil {
+		t.Error("expected stored copy of expired email to be deleted")
+	}
+}
+
+func TestPurgeExpiredBodies(t *testing.T) {
+	uc, mocks := createTestUseCase(t)
+	ctx := context.Background()
+
+	tenantID, strictTenantID := uuid.New(), uuid.New()
+	uc.bodyRetention = domain.BodyRetention{DefaultDays: 90, TenantDays: map[uuid.UUID]int{strictTenantID: 30}}
+
+	now := mocks.TimeProvider.NowUTC()
+	notification := func(tenantID uuid.UUID, age time.Duration, status domain.NotificationStatus) *domain.Notification {
+		n := createTestNotification(tenantID, domain.ChannelEmail)
+		n.Status = status
+		n.CreatedAt = now.Add(-age)
+		mocks.NotificationRepo.Create(ctx, n)
+		return n
+	}
+	day := 24 * time.Hour
+	old := notification(tenantID, 100*day, domain.StatusDelivered)
+	recent := notification(tenantID, 60*day, domain.StatusDelivered)
+	strict := notification(strictTenantID, 60*day, domain.StatusSent)
+	pending := notification(tenantID, 100*day, domain.StatusPending)
+
+	purged, err := uc.PurgeExpiredBodies(ctx, 100)
+	if err != nil {
+		t.Fatalf("PurgeExpiredBodies failed: %v", err)
+	}
+	if purged != 2 {
+		t.Errorf("expected 2 bodies purged, got %d", purged)
+	}
+	if old.Body != "" || strict.Body != "" {
+		t.Error("expected the bodies past retention to be cleared")
+	}
+	if recent.Body == "" || pending.Body == "" {
+		t.Error("expected recent and undelivered notifications to keep their bodies")
+	}
+}
+
+// ============================================================================
+// Delivery Timeline Tests
+// ============================================================================
+
+func TestHandleDeliveryResult_RecordsAttempt(t *testing.T) {
+	uc, mocks := createTestUseCase(t)
+	ctx := context.Background()
+
+	notification := createTestNotification(uuid.New(), domain.ChannelEmail)
+	notification.Status = domain.StatusSending
+	notification.AttemptCount = 1
+	mocks.NotificationRepo.Create(ctx, notification)
+
+	uc.handleDeliveryResult(ctx, notification, nil, &ports.EmailResponse{
+		ProviderID: "sg-123",
+		Provider:   "sendgrid",
+	}, 120*time.Millisecond)
+
+	logs, _ := mocks.DeliveryLogRepo.FindByNotification(ctx, notification.ID)
+	if len(logs) != 1 {
+		t.Fatalf("expected 1 delivery log, got %d", len(logs))
+	}
+	log := logs[0]
+	if log.Source != domain.DeliveryLogSourceAttempt || log.Status != "sent" {
+		t.Errorf("unexpected log: %+v", log)
+	}
+	if log.Provider != "sendgrid" || log.ProviderMessageID != "sg-123" || log.LatencyMs != 120 {
+		t.Errorf("unexpected provider details: %+v", log)
+	}
+}
+
+func TestGetNotificationTimeline_Success(t *testing.T) {
+	uc, mocks := createTestUseCase(t)
+	ctx := context.Background()
+
+	tenantID := uuid.New()
+	notification := createTestNotification(tenantID, domain.ChannelEmail)
+	notification.RecipientEmail = "customer@example.com"
+	notification.Status = domain.StatusSent
+	notification.Provider = "sendgrid"
+	notification.ProviderMessageID = "sg-123"
+	mocks.NotificationRepo.Create(ctx, notification)
+
+	delivered := domain.NewProviderStatusLog(notification, domain.ProviderStatusDelivered, "", notification.CreatedAt.Add(2*time.Minute))
+	attempt := domain.NewDeliveryAttemptLog(notification, 0)
+	attempt.CreatedAt = notification.CreatedAt.Add(time.Minute)
+	mocks.DeliveryLogRepo.Create(ctx, delivered)
+	mocks.DeliveryLogRepo.Create(ctx, attempt)
+
+	timeline, err := uc.GetNotificationTimeline(ctx, &dto.GetNotificationTimelineRequest{
+		TenantID:       tenantID.String(),
+		NotificationID: notification.ID.String(),
+	})
+	if err != nil {
+		t.Fatalf("GetNotificationTimeline failed: %v", err)
+	}
+
+	if timeline.Recipient != "customer@example.com" || timeline.ProviderMessageID != "sg-123" {
+		t.Errorf("unexpected timeline header: %+v", timeline)
+	}
+	events := make([]string, len(timeline.Entries))
+	for i, entry := range timeline.Entries {
+		events[i] = entry.Event
+	}
+	want := []string{"created", "attempt", "delivered"}
+	if strings.Join(events, ",") != strings.Join(want, ",") {
+		t.Errorf("events = %v, want %v", events, want)
+	}
+}
+
+func TestGetNotificationTimeline_WrongTenant(t *testing.T) {
+	uc, mocks := createTestUseCase(t)
+	ctx := context.Background()
+
+	notification := createTestNotification(uuid.New(), domain.ChannelEmail)
+	mocks.NotificationRepo.Create(ctx, notification)
+
+	_, err := uc.GetNotificationTimeline(ctx, &dto.GetNotificationTimelineRequest{
+		TenantID:       uuid.New().String(),
+		NotificationID: notification.ID.String(),
+	})
+
+	appErr, ok := err.(*application.AppError)
+	if !ok || appErr.Code != application.ErrCodeForbidden {
+		t.Errorf("expected forbidden error, got %v", err)
+	}
+}
+
+func TestHandleProviderWebhook_Delivered(t *testing.T) {
+	uc, mocks := createTestUseCase(t)
+	ctx := context.Background()
+
+	notification := createTestNotification(uuid.New(), domain.ChannelEmail)
+	notification.Status = domain.StatusSent
+	notification.Provider = "sendgrid"
+	notification.ProviderMessageID = "sg-123"
+	mocks.NotificationRepo.Create(ctx, notification)
+
+	resp, err := uc.HandleProviderWebhook(ctx, &dto.ProviderWebhookRequest{
+		Provider:          "sendgrid",
+		ProviderMessageID: "sg-123",
+		Status:            "delivered",
+	})
+	if err != nil {
+		t.Fatalf("HandleProviderWebhook failed: %v", err)
+	}
+
+	if !resp.Updated || resp.Status != "delivered" {
+		t.Errorf("unexpected response: %+v", resp)
+	}
+	if notification.DeliveredAt == nil {
+		t.Error("DeliveredAt should be set")
+	}
+	logs, _ := mocks.DeliveryLogRepo.FindByNotification(ctx, notification.ID)
+	if len(logs) != 1 || logs[0].Source != domain.DeliveryLogSourceWebhook {
+		t.Errorf("expected a webhook delivery log, got %+v", logs)
+	}
+	if mocks.Metrics.GetCounter("notification.webhook.received") != 1 {
+		t.Error("expected webhook counter to be incremented")
+	}
+}
+
+func TestHandleProviderWebhook_StaleEvent(t *testing.T) {
+	uc, mocks := createTestUseCase(t)
+	ctx := context.Background()
+
+	notification := createTestNotification(uuid.New(), domain.ChannelEmail)
+	notification.Status = domain.StatusDelivered
+	notification.Provider = "sendgrid"
+	notification.ProviderMessageID = "sg-123"
+	mocks.NotificationRepo.Create(ctx, notification)
+
+	resp, err := uc.HandleProviderWebhook(ctx, &dto.ProviderWebhookRequest{
+		Provider:          "sendgrid",
+		ProviderMessageID: "sg-123",
+		Status:            "failed",
+		Reason:            "late report",
+	})
+	if err != nil {
+		t.Fatalf("HandleProviderWebhook failed: %v", err)
+	}
+
+	if resp.Updated || resp.Status != "delivered" {
+		t.Errorf("expected the stale event to be ignored, got %+v", resp)
+	}
+	logs, _ := mocks.DeliveryLogRepo.FindByNotification(ctx, notification.ID)
+	if len(logs) != 1 {
+		t.Errorf("expected the stale event in the timeline, got %d logs", len(logs))
+	}
+}
+
+func TestHandleProviderWebhook_Errors(t *testing.T) {
+	uc, mocks := createTestUseCase(t)
+	ctx := context.Background()
+
+	notification := createTestNotification(uuid.New(), domain.ChannelEmail)
+	notification.Provider = "sendgrid"
+	notification.ProviderMessageID = "sg-123"
+	mocks.NotificationRepo.Create(ctx, notification)
+
+	tests := []struct {
+		name     string
+		req      *dto.ProviderWebhookRequest
+		wantCode application.ErrorCode
+	}{
+		{"unknown status", &dto.ProviderWebhookRequest{Provider: "sendgrid", ProviderMessageID: "sg-123", Status: "processed"}, application.ErrCodeInvalidInput},
+		{"unknown message", &dto.ProviderWebhookRequest{Provider: "sendgrid", ProviderMessageID: "sg-999", Status: "delivered"}, application.ErrCodeNotFound},
+		{"other provider", &dto.ProviderWebhookRequest{Provider: "twilio", ProviderMessageID: "sg-123", Status: "delivered"}, application.ErrCodeNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := uc.HandleProviderWebhook(ctx, tt.req)
+			appErr, ok := err.(*application.AppError)
+			if !ok || appErr.Code != tt.wantCode {
+				t.Errorf("expected %s error, got %v", tt.wantCode, err)
+			}
+		})
+	}
+}
+
+// ============================================================================
+// Notification Stats Tests
+// ============================================================================
+
+func TestGetNotificationStats_Success(t *testing.T) {
+	uc, mocks := createTestUseCase(t)
+	ctx := context.Background()
+
+	tenantID := uuid.New()
+	templateID := uuid.New()
+	now := mocks.TimeProvider.Now()
+	yesterday := now.AddDate(0, 0, -1)
+
+	delivered := createTestNotification(tenantID, domain.ChannelEmail)
+	delivered.TemplateID = &templateID
+	delivered.TemplateName = "Order Confirmation"
+	delivered.Status = domain.StatusDelivered
+	delivered.SentAt = &yesterday
+	delivered.DeliveredAt = &yesterday
+	delivered.OpenCount = 2
+	delivered.CreatedAt = yesterday
+	mocks.NotificationRepo.Create(ctx, delivered)
+
+	bounced := createTestNotification(tenantID, domain.ChannelEmail)
+	bounced.TemplateID = &templateID
+	bounced.TemplateName = "Order Confirmation"
+	bounced.Status = domain.StatusBounced
+	bounced.SentAt = &now
+	mocks.NotificationRepo.Create(ctx, bounced)
+
+	failed := createTestNotification(tenantID, domain.ChannelSMS)
+	failed.Status = domain.StatusFailed
+	mocks.NotificationRepo.Create(ctx, failed)
+
+	// Another tenant's notification is not counted
+	mocks.NotificationRepo.Create(ctx, createTestNotification(uuid.New(), domain.ChannelEmail))
+
+	stats, err := uc.GetNotificationStats(ctx, &dto.GetNotificationStatsRequest{
+		TenantID:  tenantID.String(),
+		StartDate: now.AddDate(0, 0, -7),
+		EndDate:   now.Add(time.Minute),
+		Interval:  "day",
+	})
+	if err != nil {
+		t.Fatalf("GetNotificationStats failed: %v", err)
+	}
+
+	if stats.Total != 3 || stats.TotalSent != 2 || stats.TotalDelivered != 1 || stats.TotalBounced != 1 || stats.TotalFailed != 1 {
+		t.Errorf("unexpected totals: %+v", stats)
+	}
+	if stats.DeliveryRate != 50 || stats.BounceRate != 50 || stats.OpenRate != 100 {
+		t.Errorf("rates = %v/%v/%v, want 50/50/100", stats.DeliveryRate, stats.BounceRate, stats.OpenRate)
+	}
+	if email := stats.ByChannel["email"]; email.Sent != 2 || email.Delivered != 1 {
+		t.Errorf("unexpected email stats: %+v", email)
+	}
+	if len(stats.ByTemplate) != 1 || stats.ByTemplate[0].TemplateName != "Order Confirmation" || stats.ByTemplate[0].Total != 2 {
+		t.Errorf("unexpected template stats: %+v", stats.ByTemplate)
+	}
+	if stats.Period != "day" || len(stats.Series) != 8 {
+		t.Fatalf("expected 8 daily buckets, got %d %s buckets", len(stats.Series), stats.Period)
+	}
+	var seriesTotal int64
+	for _, b := range stats.Series {
+		seriesTotal += b.Total
+	}
+	if seriesTotal != 3 {
+		t.Errorf("series total = %d, want 3", seriesTotal)
+	}
+}
+
+func TestGetNotificationStats_Defaults(t *testing.T) {
+	uc, _ := createTestUseCase(t)
+
+	stats, err := uc.GetNotificationStats(context.Background(), &dto.GetNotificationStatsRequest{
+		TenantID: uuid.New().String(),
+		Channel:  "sms",
+	})
+	if err != nil {
+		t.Fatalf("GetNotificationStats failed: %v", err)
+	}
+
+	if days := stats.EndDate.Sub(stats.StartDate).Hours() / 24; days != 30 {
+		t.Errorf("default period = %v days, want 30", days)
+	}
+	if stats.Period != "day" || stats.Channel != "sms" {
+		t.Errorf("period = %s, channel = %s; want day, sms", stats.Period, stats.Channel)
+	}
+}
+
+func TestGetNotificationStats_InvalidInput(t *testing.T) {
+	uc, mocks := createTestUseCase(t)
+	now := mocks.TimeProvider.Now()
+
+	tests := []struct {
+		name string
+		req  *dto.GetNotificationStatsRequest
+	}{
+		{"invalid tenant", &dto.GetNotificationStatsRequest{TenantID: "invalid"}},
+		{"start after end", &dto.GetNotificationStatsRequest{TenantID: uuid.New().String(), StartDate: now, EndDate: now.Add(-time.Hour)}},
+		{"unknown channel", &dto.GetNotificationStatsRequest{TenantID: uuid.New().String(), Channel: "fax"}},
+		{"unknown interval", &dto.GetNotificationStatsRequest{TenantID: uuid.New().String(), Interval: "minute"}},
+		{"too many buckets", &dto.GetNotificationStatsRequest{TenantID: uuid.New().String(), StartDate: now.AddDate(-1, 0, 0), EndDate: now, Interval: "hour"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := uc.GetNotificationStats(context.Background(), tt.req)
+			appErr, ok := err.(*application.AppError)
+			if !ok || appErr.Code != application.ErrCodeInvalidInput {
+				t.Errorf("expected invalid input error, got %v", err)
+			}
+		})
+	}
+}
+
+// ============================================================================
+// Delivery Queue Tests
+// ============================================================================
+
+func TestSendEmail_EnqueuesDelivery(t *testing.T) {
+	uc, mocks := createTestUseCase(t)
+	queue := NewMockDeliveryQueue()
+	uc.deliveryQueue = queue
+	ctx := context.Background()
+
+	req := createTestEmailRequest()
+	req.Priority = "critical"
+
+	resp, err := uc.SendEmail(ctx, req)
+	if err != nil {
+		t.Fatalf("SendEmail failed: %v", err)
+	}
+
+	jobs := queue.GetJobs()
+	if len(jobs) != 1 {
+		t.Fatalf("expected 1 queued job, got %d", len(jobs))
+	}
+	job := jobs[0]
+	if job.NotificationID != resp.NotificationID || job.Priority != domain.PriorityCritical || job.Channel != domain.ChannelEmail {
+		t.Errorf("unexpected job: %+v", job)
+	}
+	if len(job.Payload) == 0 {
+		t.Error("expected the send request as payload")
+	}
+	if sent := mocks.EmailProvider.GetSentEmails(); len(sent) != 0 {
+		t.Errorf("expected no in-process delivery, got %d emails", len(sent))
+	}
+}
+
+func TestSendEmail_EnqueueFailureDeliversInProcess(t *testing.T) {
+	uc, mocks := createTestUseCase(t)
+	queue := NewMockDeliveryQueue()
+	queue.err = errors.New("broker unavailable")
+	uc.deliveryQueue = queue
+	ctx := context.Background()
+
+	if _, err := uc.SendEmail(ctx, createTestEmailRequest()); err != nil {
+		t.Fatalf("SendEmail failed: %v", err)
+	}
+
+	deadline := time.Now().Add(time.Second)
+	for len(mocks.EmailProvider.GetSentEmails()) == 0 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	if len(mocks.EmailProvider.GetSentEmails()) != 1 {
+		t.Error("expected the email to be delivered in process")
+	}
+}
+
+func TestDeliverQueuedNotification_Success(t *testing.T) {
+	uc, mocks := createTestUseCase(t)
+	queue := NewMockDeliveryQueue()
+	uc.deliveryQueue = queue
+	ctx := context.Background()
+
+	req := createTestEmailRequest()
+	req.CC = []string{"cc@example.com"}
+	if _, err := uc.SendEmail(ctx, req); err != nil {
+		t.Fatalf("SendEmail failed: %v", err)
+	}
+	job := queue.GetJobs()[0]
+
+	if err := uc.DeliverQueuedNotification(ctx, job); err != nil {
+		t.Fatalf("DeliverQueuedNotification failed: %v", err)
+	}
+
+	sent := mocks.EmailProvider.GetSentEmails()
+	if len(sent) != 1 || sent[0].To[0] != "test@example.com" {
+		t.Fatalf("expected the queued email to be sent, got %+v", sent)
+	}
+	notification, _ := mocks.NotificationRepo.FindByID(ctx, uuid.MustParse(job.NotificationID))
+	if notification.Status != domain.StatusSent {
+		t.Errorf("expected status sent, got %s", notification.Status)
+	}
+	if mocks.Metrics.GetCounter("notification.queue.delivered") != 1 {
+		t.Error("expected notification.queue.delivered to be incremented")
+	}
+
+	// A redelivered job is not sent twice
+	if err := uc.DeliverQueuedNotification(ctx, job); err != nil {
+		t.Fatalf("DeliverQueuedNotification failed on redelivery: %v", err)
+	}
+	if len(mocks.EmailProvider.GetSentEmails()) != 1 {
+		t.Error("expected a redelivered job to be skipped")
+	}
+}
+
+func TestDeliverQueuedNotification_Errors(t *testing.T) {
+	uc, mocks := createTestUseCase(t)
+	ctx := context.Background()
+
+	notification := createTestNotification(uuid.New(), domain.ChannelSMS)
+	notification.Status = domain.StatusQueued
+	mocks.NotificationRepo.Create(ctx, notification)
+
+	tests := []struct {
+		name     string
+		job      *ports.DeliveryJob
+		wantCode application.ErrorCode
+	}{
+		{"invalid ID", &ports.DeliveryJob{NotificationID: "not-a-uuid"}, application.ErrCodeInvalidInput},
+		{"unknown notification", &ports.DeliveryJob{NotificationID: uuid.New().String()}, application.ErrCodeNotFound},
+		{"invalid payload", &ports.DeliveryJob{NotificationID: notification.ID.String(), Payload: []byte("{")}, application.ErrCodeInvalidInput},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := uc.DeliverQueuedNotification(ctx, tt.job)
+			appErr, ok := err.(*application.AppError)
+			if !ok {
+				t.Fatalf("expected AppError, got %T: %v", err, err)
+			}
+			if appErr.Code != tt.wantCode {
+				t.Errorf("expected code %s, got %s", tt.wantCode, appErr.Code)
+			}
+		})
+	}
+}
+
+// ============================================================================
+// Retry Policy Tests
+// ============================================================================
+
+// createSendingEmail stores an email notification that is being sent.
+func createSendingEmail(t *testing.T, mocks *TestMocks) *domain.Notification {
+	t.Helper()
+	notification := createTestNotification(uuid.New(), domain.ChannelEmail)
+	notification.RecipientEmail = "test@example.com"
+	if err := notification.Queue(); err != nil {
+		t.Fatalf("Queue failed: %v", err)
+	}
+	if err := notification.MarkSending(); err != nil {
+		t.Fatalf("MarkSending failed: %v", err)
+	}
+	mocks.NotificationRepo.Create(context.Background(), notification)
+	return notification
+}
+
+func hasFailedEvent(events []domain.DomainEvent) bool {
+	for _, e := range events {
+		if _, ok := e.(*domain.NotificationFailedEvent); ok {
+			return true
+		}
+	}
+	return false
+}
+
+func TestDeliveryFailure_SchedulesRetry(t *testing.T) {
+	uc, mocks := createTestUseCase(t)
+	uc.random = func() float64 { return 0 }
+	mocks.EmailProvider.sendErr = errors.New("service unavailable")
+	mocks.EmailProvider.errStatus = 503
+	ctx := context.Background()
+
+	notification := createSendingEmail(t, mocks)
+	uc.retryEmailDelivery(ctx, notification)
+
+	if notification.Status != domain.StatusRetrying {
+		t.Fatalf("expected status retrying, got %s", notification.Status)
+	}
+	if notification.ErrorCode != domain.DeliveryErrorUnavailable {
+		t.Errorf("expected error code %s, got %s", domain.DeliveryErrorUnavailable, notification.ErrorCode)
+	}
+	if notification.NextRetryAt == nil || notification.NextRetryAt.Before(time.Now().Add(59*time.Second)) {
+		t.Errorf("expected the retry in about a minute, got %v", notification.NextRetryAt)
+	}
+	if hasFailedEvent(mocks.EventPublisher.GetPublishedEvents()) {
+		t.Error("expected no failed event while retries remain")
+	}
+}
+
+func TestDeliveryFailure_NonRetryableFails(t *testing.T) {
+	uc, mocks := createTestUseCase(t)
+	mocks.EmailProvider.sendErr = errors.New("invalid recipient")
+	mocks.EmailProvider.errStatus = 400
+	ctx := context.Background()
+
+	notification := createSendingEmail(t, mocks)
+	uc.retryEmailDelivery(ctx, notification)
+
+	if notification.Status != domain.StatusFailed || notification.ErrorCode != domain.DeliveryErrorRejected {
+		t.Fatalf("expected status failed with %s, got %s with %s", domain.DeliveryErrorRejected, notification.Status, notification.ErrorCode)
+	}
+	if !hasFailedEvent(mocks.EventPublisher.GetPublishedEvents()) {
+		t.Error("expected a failed event")
+	}
+}
+
+func TestDeliveryFailure_ExhaustedFails(t *testing.T) {
+	uc, mocks := createTestUseCase(t)
+	policies := domain.DefaultRetryPolicies()
+	policies.Channels = map[domain.NotificationChannel]domain.RetryPolicy{
+		domain.ChannelEmail: {MaxAttempts: 1, InitialInterval: 1, MaxInterval: 1, Multiplier: 1},
+	}
+	uc.retryPolicies = policies
+	mocks.EmailProvider.sendErr = errors.New("service unavailable")
+	mocks.EmailProvider.errStatus = 503
+	ctx := context.Background()
+
+	notification := createSendingEmail(t, mocks)
+	uc.retryEmailDelivery(ctx, notification)
+
+	if notification.Status != domain.StatusFailed {
+		t.Fatalf("expected status failed, got %s", notification.Status)
+	}
+	if !hasFailedEvent(mocks.EventPublisher.GetPublishedEvents()) {
+		t.Error("expected a failed event once retries are exhausted")
+	}
+}
+
+func TestDeliveryFailure_TemplateOverride(t *testing.T) {
+	uc, mocks := createTestUseCase(t)
+	mocks.EmailProvider.sendErr = errors.New("invalid recipient")
+	mocks.EmailProvider.errStatus = 400
+	ctx := context.Background()
+
+	template, _ := domain.NewNotificationTemplate(uuid.New(), "retry_everything", "Retry everything", domain.TypeTransactional)
+	template.RetryPolicy = &domain.RetryPolicy{MaxAttempts: 5, InitialInterval: 10, MaxInterval: 60, Multiplier: 2}
+	mocks.TemplateRepo.Create(ctx, template)
+
+	notification := createSendingEmail(t, mocks)
+	notification.TemplateID = &template.ID
+	uc.retryEmailDelivery(ctx, notification)
+
+	if notification.Status != domain.StatusRetrying {
+		t.Fatalf("expected the template policy to retry, got status %s", notification.Status)
+	}
+	if notification.RetryPolicy == nil || notification.RetryPolicy.MaxAttempts != 5 {
+		t.Errorf("expected the template policy on the notification, got %+v", notification.RetryPolicy)
+	}
+}
+
+func TestProcessDueRetries(t *testing.T) {
+	uc, mocks := createTestUseCase(t)
+	ctx := context.Background()
+
+	due := createSendingEmail(t, mocks)
+	due.Status = domain.StatusRetrying
+	past := time.Now().Add(-time.Minute)
+	due.NextRetryAt = &past
+
+	later := createSendingEmail(t, mocks)
+	later.Status = domain.StatusRetrying
+	future := time.Now().Add(time.Hour)
+	later.NextRetryAt = &future
+
+	processed, err := uc.ProcessDueRetries(ctx, 10)
+	if err != nil {
+		t.Fatalf("ProcessDueRetries failed: %v", err)
+	}
+	if processed != 1 {
+		t.Fatalf("expected 1 notification processed, got %d", processed)
+	}
+	if due.Status != domain.StatusSent || due.AttemptCount != 2 {
+		t.Errorf("expected the due notification sent on attempt 2, got %s on attempt %d", due.Status, due.AttemptCount)
+	}
+	if later.Status != domain.StatusRetrying {
+		t.Errorf("expected the later notification to keep waiting, got %s", later.Status)
+	}
+}
+
 // ============================================================================
 // ListNotifications Tests
 // ============================================================================
@@ -2425,6 +3722,8 @@ func createTestUseCaseForBenchmark() (*notificationUseCase, *TestMocks) {
 	mocks := &TestMocks{
 		NotificationRepo:   NewMockNotificationRepository(),
 		TemplateRepo:       NewMockTemplateRepository(),
+		DeliveryLogRepo:    NewMockDeliveryLogRepository(),
+		ArchiveRepo:        NewMockArchivedEmailRepository(),
 		EmailProvider:      NewMockEmailProvider(),
 		SMSProvider:        NewMockSMSProvider(),
 		PushProvider:       NewMockPushProvider(),
@@ -2444,6 +3743,8 @@ func createTestUseCaseForBenchmark() (*notificationUseCase, *TestMocks) {
 	cfg := NotificationUseCaseConfig{
 		NotificationRepo:   mocks.NotificationRepo,
 		TemplateRepo:       mocks.TemplateRepo,
+		DeliveryLogRepo:    mocks.DeliveryLogRepo,
+		ArchiveRepo:        mocks.ArchiveRepo,
 		EmailProvider:      mocks.EmailProvider,
 		SMSProvider:        mocks.SMSProvider,
 		PushProvider:       mocks.PushProvider,
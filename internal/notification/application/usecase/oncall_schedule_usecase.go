@@ -0,0 +1,224 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/kilang-desa-murni/crm/internal/notification/application"
+	"github.com/kilang-desa-murni/crm/internal/notification/application/dto"
+	"github.com/kilang-desa-murni/crm/internal/notification/domain"
+)
+
+// OnCallScheduleUseCase defines the interface for managing the on-call
+// rotations escalation policies page.
+type OnCallScheduleUseCase interface {
+	// CreateSchedule adds an on-call rotation to a tenant.
+	CreateSchedule(ctx context.Context, req *dto.CreateOnCallScheduleRequest) (*dto.OnCallScheduleDTO, error)
+	// UpdateSchedule updates a schedule.
+	UpdateSchedule(ctx context.Context, req *dto.UpdateOnCallScheduleRequest) (*dto.OnCallScheduleDTO, error)
+	// GetSchedule gets a schedule of a tenant, with who is on call now.
+	GetSchedule(ctx context.Context, tenantID, id string) (*dto.OnCallScheduleDTO, error)
+	// ListSchedules lists the schedules of a tenant.
+	ListSchedules(ctx context.Context, tenantID string) ([]*dto.OnCallScheduleDTO, error)
+	// DeleteSchedule removes a schedule.
+	DeleteSchedule(ctx context.Context, tenantID, id string) error
+}
+
+// onCallScheduleUseCase implements the OnCallScheduleUseCase interface.
+type onCallScheduleUseCase struct {
+	repo domain.OnCallScheduleRepository
+}
+
+// NewOnCallScheduleUseCase creates a new on-call schedule use case.
+func NewOnCallScheduleUseCase(repo domain.OnCallScheduleRepository) OnCallScheduleUseCase {
+	return &onCallScheduleUseCase{repo: repo}
+}
+
+// CreateSchedule adds an on-call rotation to a tenant.
+func (uc *onCallScheduleUseCase) CreateSchedule(ctx context.Context, req *dto.CreateOnCallScheduleRequest) (*dto.OnCallScheduleDTO, error) {
+	tenantID, err := uuid.Parse(req.TenantID)
+	if err != nil {
+		return nil, application.NewInvalidInputError("invalid tenant ID format")
+	}
+	members, err := toOnCallMembers(req.Members)
+	if err != nil {
+		return nil, err
+	}
+	overrides, err := toOnCallOverrides(req.Overrides)
+	if err != nil {
+		return nil, err
+	}
+
+	schedule, err := domain.NewOnCallSchedule(tenantID, req.Name, members, req.RotationStart, req.ShiftHours)
+	if err != nil {
+		return nil, routingValidationError(err)
+	}
+	if err := schedule.SetOverrides(overrides); err != nil {
+		return nil, routingValidationError(err)
+	}
+
+	if err := uc.repo.Create(ctx, schedule); err != nil {
+		return nil, application.NewInternalError("failed to create on-call schedule", err)
+	}
+	return toOnCallScheduleDTO(schedule, time.Now()), nil
+}
+
+// UpdateSchedule updates a schedule.
+func (uc *onCallScheduleUseCase) UpdateSchedule(ctx context.Context, req *dto.UpdateOnCallScheduleRequest) (*dto.OnCallScheduleDTO, error) {
+	schedule, err := uc.find(ctx, req.TenantID, req.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Name != nil {
+		if err := schedule.Rename(*req.Name); err != nil {
+			return nil, routingValidationError(err)
+		}
+	}
+	if req.Members != nil || req.RotationStart != nil || req.ShiftHours != nil {
+		members, start, shiftHours := schedule.Members, schedule.RotationStart, schedule.ShiftHours
+		if req.Members != nil {
+			if members, err = toOnCallMembers(req.Members); err != nil {
+				return nil, err
+			}
+		}
+		if req.RotationStart != nil {
+			start = *req.RotationStart
+		}
+		if req.ShiftHours != nil {
+			shiftHours = *req.ShiftHours
+		}
+		if err := schedule.SetRotation(members, start, shiftHours); err != nil {
+			return nil, routingValidationError(err)
+		}
+	}
+	if req.Overrides != nil {
+		overrides, err := toOnCallOverrides(req.Overrides)
+		if err != nil {
+			return nil, err
+		}
+		if err := schedule.SetOverrides(overrides); err != nil {
+			return nil, routingValidationError(err)
+		}
+	}
+
+	if err := uc.repo.Update(ctx, schedule); err != nil {
+		if errors.Is(err, domain.ErrOnCallScheduleNotFound) {
+			return nil, application.NewNotFoundError("on-call schedule", req.ID)
+		}
+		return nil, application.NewInternalError("failed to update on-call schedule", err)
+	}
+	return toOnCallScheduleDTO(schedule, time.Now()), nil
+}
+
+// GetSchedule gets a schedule of a tenant, with who is on call now.
+func (uc *onCallScheduleUseCase) GetSchedule(ctx context.Context, tenantID, id string) (*dto.OnCallScheduleDTO, error) {
+	schedule, err := uc.find(ctx, tenantID, id)
+	if err != nil {
+		return nil, err
+	}
+	return toOnCallScheduleDTO(schedule, time.Now()), nil
+}
+
+// ListSchedules lists the schedules of a tenant.
+func (uc *onCallScheduleUseCase) ListSchedules(ctx context.Context, tenantID string) ([]*dto.OnCallScheduleDTO, error) {
+	tid, err := uuid.Parse(tenantID)
+	if err != nil {
+		return nil, application.NewInvalidInputError("invalid tenant ID format")
+	}
+	schedules, err := uc.repo.FindByTenant(ctx, tid)
+	if err != nil {
+		return nil, application.NewInternalError("failed to list on-call schedules", err)
+	}
+	now := time.Now()
+	result := make([]*dto.OnCallScheduleDTO, len(schedules))
+	for i, schedule := range schedules {
+		result[i] = toOnCallScheduleDTO(schedule, now)
+	}
+	return result, nil
+}
+
+// DeleteSchedule removes a schedule. Levels paging it page no one from it
+// until they are changed.
+func (uc *onCallScheduleUseCase) DeleteSchedule(ctx context.Context, tenantID, id string) error {
+	tid, sid, err := parseEscalationID(tenantID, id, "on-call schedule")
+	if err != nil {
+		return err
+	}
+	if err := uc.repo.Delete(ctx, tid, sid); err != nil {
+		if errors.Is(err, domain.ErrOnCallScheduleNotFound) {
+			return application.NewNotFoundError("on-call schedule", id)
+		}
+		return application.NewInternalError("failed to delete on-call schedule", err)
+	}
+	return nil
+}
+
+// find loads a schedule of a tenant.
+func (uc *onCallScheduleUseCase) find(ctx context.Context, tenantID, id string) (*domain.OnCallSchedule, error) {
+	tid, sid, err := parseEscalationID(tenantID, id, "on-call schedule")
+	if err != nil {
+		return nil, err
+	}
+	schedule, err := uc.repo.FindByID(ctx, tid, sid)
+	if err != nil {
+		if errors.Is(err, domain.ErrOnCallScheduleNotFound) {
+			return nil, application.NewNotFoundError("on-call schedule", id)
+		}
+		return nil, application.NewInternalError("failed to find on-call schedule", err)
+	}
+	return schedule, nil
+}
+
+func toOnCallMembers(members []string) ([]uuid.UUID, error) {
+	result := make([]uuid.UUID, len(members))
+	for i, member := range members {
+		userID, err := uuid.Parse(strings.TrimSpace(member))
+		if err != nil {
+			return nil, application.NewValidationError("members must be user IDs")
+		}
+		result[i] = userID
+	}
+	return result, nil
+}
+
+func toOnCallOverrides(overrides []dto.OnCallOverrideDTO) ([]domain.OnCallOverride, error) {
+	result := make([]domain.OnCallOverride, len(overrides))
+	for i, override := range overrides {
+		userID, err := uuid.Parse(override.UserID)
+		if err != nil {
+			return nil, application.NewValidationError("override must name a user ID")
+		}
+		result[i] = domain.OnCallOverride{UserID: userID, Start: override.Start, End: override.End}
+	}
+	return result, nil
+}
+
+// toOnCallScheduleDTO maps a schedule to its DTO, with who is on call at a
+// time.
+func toOnCallScheduleDTO(schedule *domain.OnCallSchedule, at time.Time) *dto.OnCallScheduleDTO {
+	result := &dto.OnCallScheduleDTO{
+		ID:            schedule.ID.String(),
+		Name:          schedule.Name,
+		Members:       make([]string, len(schedule.Members)),
+		RotationStart: schedule.RotationStart,
+		ShiftHours:    schedule.ShiftHours,
+		Overrides:     make([]dto.OnCallOverrideDTO, len(schedule.Overrides)),
+		CreatedAt:     schedule.CreatedAt,
+		UpdatedAt:     schedule.UpdatedAt,
+	}
+	for i, member := range schedule.Members {
+		result.Members[i] = member.String()
+	}
+	for i, override := range schedule.Overrides {
+		result.Overrides[i] = dto.OnCallOverrideDTO{UserID: override.UserID.String(), Start: override.Start, End: override.End}
+	}
+	if userID, ok := schedule.OnCall(at); ok {
+		result.OnCallUserID = userID.String()
+	}
+	return result
+}
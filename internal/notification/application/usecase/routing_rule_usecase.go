@@ -0,0 +1,359 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/google/uuid"
+
+	"github.com/kilang-desa-murni/crm/internal/notification/application"
+	"github.com/kilang-desa-murni/crm/internal/notification/application/dto"
+	"github.com/kilang-desa-murni/crm/internal/notification/domain"
+)
+
+// RoutingRuleUseCase defines the interface for routing subscribed events to
+// the audiences and channels tenants choose.
+type RoutingRuleUseCase interface {
+	// CreateRule adds a rule routing an event type of a tenant.
+	CreateRule(ctx context.Context, req *dto.CreateRoutingRuleRequest) (*dto.RoutingRuleDTO, error)
+	// UpdateRule updates a rule.
+	UpdateRule(ctx context.Context, req *dto.UpdateRoutingRuleRequest) (*dto.RoutingRuleDTO, error)
+	// GetRule gets a rule of a tenant.
+	GetRule(ctx context.Context, tenantID, id string) (*dto.RoutingRuleDTO, error)
+	// ListRules lists the rules of a tenant.
+	ListRules(ctx context.Context, tenantID string) ([]*dto.RoutingRuleDTO, error)
+	// DeleteRule removes a rule.
+	DeleteRule(ctx context.Context, tenantID, id string) error
+	// Route returns the notifications the rules of its tenant send for an
+	// event.
+	Route(ctx context.Context, event *domain.RoutedEvent) ([]domain.RoutedNotification, error)
+	// Reload rebuilds the routing table from the stored rules, picking up
+	// the changes other instances made.
+	Reload(ctx context.Context) error
+}
+
+// routingRuleUseCase implements the RoutingRuleUseCase interface. Events are
+// routed by an in-memory table of every tenant's rules: the rules of a
+// tenant are reloaded when they change through this instance, and the whole
+// table when Reload is called.
+type routingRuleUseCase struct {
+	repo domain.RoutingRuleRepository
+
+	mu    sync.Mutex // serializes rebuilding the table
+	table atomic.Pointer[domain.RoutingTable]
+}
+
+// NewRoutingRuleUseCase creates a new routing rule use case.
+func NewRoutingRuleUseCase(repo domain.RoutingRuleRepository) RoutingRuleUseCase {
+	return &routingRuleUseCase{repo: repo}
+}
+
+// CreateRule adds a rule routing an event type of a tenant.
+func (uc *routingRuleUseCase) CreateRule(ctx context.Context, req *dto.CreateRoutingRuleRequest) (*dto.RoutingRuleDTO, error) {
+	tenantID, err := uuid.Parse(req.TenantID)
+	if err != nil {
+		return nil, application.NewInvalidInputError("invalid tenant ID format")
+	}
+	var createdBy *uuid.UUID
+	if req.CreatedBy != "" {
+		userID, err := uuid.Parse(req.CreatedBy)
+		if err != nil {
+			return nil, application.NewInvalidInputError("invalid user ID format")
+		}
+		createdBy = &userID
+	}
+
+	rule, err := domain.NewRoutingRule(tenantID, req.Name, req.EventType, toRoutingAudiences(req.Audiences), toRoutingChannels(req.Channels), createdBy)
+	if err != nil {
+		return nil, routingValidationError(err)
+	}
+	if err := rule.SetFilters(toRoutingFilters(req.Filters)); err != nil {
+		return nil, routingValidationError(err)
+	}
+	if err := rule.SetTemplates(req.TemplateCode, toRoutingChannelTemplates(req.ChannelTemplates)); err != nil {
+		return nil, routingValidationError(err)
+	}
+	rule.SetPriority(req.Priority)
+	if req.Enabled != nil && !*req.Enabled {
+		rule.Disable()
+	}
+
+	if err := uc.repo.Create(ctx, rule); err != nil {
+		return nil, application.NewInternalError("failed to create routing rule", err)
+	}
+	uc.reloadTenant(ctx, tenantID)
+	return toRoutingRuleDTO(rule), nil
+}
+
+// UpdateRule updates a rule.
+func (uc *routingRuleUseCase) UpdateRule(ctx context.Context, req *dto.UpdateRoutingRuleRequest) (*dto.RoutingRuleDTO, error) {
+	rule, err := uc.find(ctx, req.TenantID, req.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Name != nil {
+		if err := rule.Rename(*req.Name); err != nil {
+			return nil, routingValidationError(err)
+		}
+	}
+	if req.EventType != nil {
+		if err := rule.SetEventType(*req.EventType); err != nil {
+			return nil, routingValidationError(err)
+		}
+	}
+	if req.Filters != nil {
+		if err := rule.SetFilters(toRoutingFilters(req.Filters)); err != nil {
+			return nil, routingValidationError(err)
+		}
+	}
+	if req.Audiences != nil {
+		if err := rule.SetAudiences(toRoutingAudiences(req.Audiences)); err != nil {
+			return nil, routingValidationError(err)
+		}
+	}
+	if req.Channels != nil {
+		if err := rule.SetChannels(toRoutingChannels(req.Channels)); err != nil {
+			return nil, routingValidationError(err)
+		}
+	}
+	if req.TemplateCode != nil || req.ChannelTemplates != nil {
+		code, templates := rule.TemplateCode, rule.ChannelTemplates
+		if req.TemplateCode != nil {
+			code = *req.TemplateCode
+		}
+		if req.ChannelTemplates != nil {
+			templates = toRoutingChannelTemplates(req.ChannelTemplates)
+		}
+		if err := rule.SetTemplates(code, templates); err != nil {
+			return nil, routingValidationError(err)
+		}
+	}
+	if req.Priority != nil {
+		rule.SetPriority(*req.Priority)
+	}
+	if req.Enabled != nil {
+		if *req.Enabled {
+			rule.Enable()
+		} else {
+			rule.Disable()
+		}
+	}
+
+	if err := uc.repo.Update(ctx, rule); err != nil {
+		if errors.Is(err, domain.ErrRoutingRuleNotFound) {
+			return nil, application.NewNotFoundError("routing rule", req.ID)
+		}
+		return nil, application.NewInternalError("failed to update routing rule", err)
+	}
+	uc.reloadTenant(ctx, rule.TenantID)
+	return toRoutingRuleDTO(rule), nil
+}
+
+// GetRule gets a rule of a tenant.
+func (uc *routingRuleUseCase) GetRule(ctx context.Context, tenantID, id string) (*dto.RoutingRuleDTO, error) {
+	rule, err := uc.find(ctx, tenantID, id)
+	if err != nil {
+		return nil, err
+	}
+	return toRoutingRuleDTO(rule), nil
+}
+
+// ListRules lists the rules of a tenant in the order they are evaluated.
+func (uc *routingRuleUseCase) ListRules(ctx context.Context, tenantID string) ([]*dto.RoutingRuleDTO, error) {
+	tid, err := uuid.Parse(tenantID)
+	if err != nil {
+		return nil, application.NewInvalidInputError("invalid tenant ID format")
+	}
+	rules, err := uc.repo.FindByTenant(ctx, tid)
+	if err != nil {
+		return nil, application.NewInternalError("failed to list routing rules", err)
+	}
+	result := make([]*dto.RoutingRuleDTO, len(rules))
+	for i, rule := range rules {
+		result[i] = toRoutingRuleDTO(rule)
+	}
+	return result, nil
+}
+
+// DeleteRule removes a rule. Once a tenant has no rules left for an event
+// type, the default rules route it again.
+func (uc *routingRuleUseCase) DeleteRule(ctx context.Context, tenantID, id string) error {
+	tid, rid, err := parseRoutingRuleID(tenantID, id)
+	if err != nil {
+		return err
+	}
+	if err := uc.repo.Delete(ctx, tid, rid); err != nil {
+		if errors.Is(err, domain.ErrRoutingRuleNotFound) {
+			return application.NewNotFoundError("routing rule", id)
+		}
+		return application.NewInternalError("failed to delete routing rule", err)
+	}
+	uc.reloadTenant(ctx, tid)
+	return nil
+}
+
+// Route returns the notifications the rules of its tenant send for an
+// event. The table is loaded on the first event, and an event that arrives
+// while it cannot be loaded fails, so it is redelivered.
+func (uc *routingRuleUseCase) Route(ctx context.Context, event *domain.RoutedEvent) ([]domain.RoutedNotification, error) {
+	table := uc.table.Load()
+	if table == nil {
+		if err := uc.Reload(ctx); err != nil {
+			return nil, err
+		}
+		table = uc.table.Load()
+	}
+	return table.Route(event), nil
+}
+
+// Reload rebuilds the routing table from the stored rules.
+func (uc *routingRuleUseCase) Reload(ctx context.Context) error {
+	uc.mu.Lock()
+	defer uc.mu.Unlock()
+
+	rules, err := uc.repo.FindAll(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to load routing rules: %w", err)
+	}
+	uc.table.Store(domain.NewRoutingTable(rules))
+	return nil
+}
+
+// reloadTenant replaces the rules of a tenant in the routing table after
+// they changed. A failure is left for the next Reload to repair, as the
+// change itself was stored.
+func (uc *routingRuleUseCase) reloadTenant(ctx context.Context, tenantID uuid.UUID) {
+	uc.mu.Lock()
+	defer uc.mu.Unlock()
+
+	table := uc.table.Load()
+	if table == nil {
+		// Not loaded yet; the first event loads every rule
+		return
+	}
+	rules, err := uc.repo.FindByTenant(ctx, tenantID)
+	if err != nil {
+		return
+	}
+	uc.table.Store(table.WithTenant(tenantID, rules))
+}
+
+// find loads a rule of a tenant.
+func (uc *routingRuleUseCase) find(ctx context.Context, tenantID, id string) (*domain.RoutingRule, error) {
+	tid, rid, err := parseRoutingRuleID(tenantID, id)
+	if err != nil {
+		return nil, err
+	}
+	rule, err := uc.repo.FindByID(ctx, tid, rid)
+	if err != nil {
+		if errors.Is(err, domain.ErrRoutingRuleNotFound) {
+			return nil, application.NewNotFoundError("routing rule", id)
+		}
+		return nil, application.NewInternalError("failed to find routing rule", err)
+	}
+	return rule, nil
+}
+
+// parseRoutingRuleID parses the tenant and ID of a rule.
+func parseRoutingRuleID(tenantID, id string) (uuid.UUID, uuid.UUID, error) {
+	tid, err := uuid.Parse(tenantID)
+	if err != nil {
+		return uuid.Nil, uuid.Nil, application.NewInvalidInputError("invalid tenant ID format")
+	}
+	rid, err := uuid.Parse(id)
+	if err != nil {
+		return uuid.Nil, uuid.Nil, application.NewInvalidInputError("invalid routing rule ID format")
+	}
+	return tid, rid, nil
+}
+
+// routingValidationError maps a domain validation error to an application
+// error.
+func routingValidationError(err error) error {
+	var validationErr *domain.ValidationError
+	if errors.As(err, &validationErr) {
+		return application.NewValidationError(validationErr.Message)
+	}
+	if errors.Is(err, domain.ErrTenantIDRequired) {
+		return application.NewInvalidInputError("tenant ID is required")
+	}
+	return application.NewValidationError(err.Error())
+}
+
+func toRoutingFilters(filters []dto.RoutingFilterDTO) []domain.RoutingFilter {
+	result := make([]domain.RoutingFilter, len(filters))
+	for i, filter := range filters {
+		result[i] = domain.RoutingFilter{
+			Field:    strings.TrimSpace(filter.Field),
+			Operator: domain.RoutingOperator(strings.ToLower(strings.TrimSpace(filter.Operator))),
+			Value:    filter.Value,
+		}
+	}
+	return result
+}
+
+func toRoutingAudiences(audiences []dto.RoutingAudienceDTO) []domain.RoutingAudience {
+	result := make([]domain.RoutingAudience, len(audiences))
+	for i, audience := range audiences {
+		result[i] = domain.RoutingAudience{
+			Type:  domain.RoutingAudienceType(strings.ToLower(strings.TrimSpace(audience.Type))),
+			Value: audience.Value,
+		}
+	}
+	return result
+}
+
+func toRoutingChannels(channels []string) []domain.NotificationChannel {
+	result := make([]domain.NotificationChannel, len(channels))
+	for i, channel := range channels {
+		result[i] = domain.NotificationChannel(strings.ToLower(strings.TrimSpace(channel)))
+	}
+	return result
+}
+
+func toRoutingChannelTemplates(templates map[string]string) map[domain.NotificationChannel]string {
+	result := make(map[domain.NotificationChannel]string, len(templates))
+	for channel, code := range templates {
+		result[domain.NotificationChannel(strings.ToLower(strings.TrimSpace(channel)))] = code
+	}
+	return result
+}
+
+// toRoutingRuleDTO maps a rule to its DTO.
+func toRoutingRuleDTO(rule *domain.RoutingRule) *dto.RoutingRuleDTO {
+	result := &dto.RoutingRuleDTO{
+		ID:               rule.ID.String(),
+		Name:             rule.Name,
+		EventType:        rule.EventType,
+		Filters:          make([]dto.RoutingFilterDTO, len(rule.Filters)),
+		Audiences:        make([]dto.RoutingAudienceDTO, len(rule.Audiences)),
+		Channels:         make([]string, len(rule.Channels)),
+		TemplateCode:     rule.TemplateCode,
+		ChannelTemplates: make(map[string]string, len(rule.ChannelTemplates)),
+		Priority:         rule.Priority,
+		Enabled:          rule.Enabled,
+		CreatedAt:        rule.CreatedAt,
+		UpdatedAt:        rule.UpdatedAt,
+	}
+	for i, filter := range rule.Filters {
+		result.Filters[i] = dto.RoutingFilterDTO{Field: filter.Field, Operator: string(filter.Operator), Value: filter.Value}
+	}
+	for i, audience := range rule.Audiences {
+		result.Audiences[i] = dto.RoutingAudienceDTO{Type: string(audience.Type), Value: audience.Value}
+	}
+	for i, channel := range rule.Channels {
+		result.Channels[i] = string(channel)
+	}
+	for channel, code := range rule.ChannelTemplates {
+		result.ChannelTemplates[string(channel)] = code
+	}
+	if rule.CreatedBy != nil {
+		result.CreatedBy = rule.CreatedBy.String()
+	}
+	return result
+}
@@ -0,0 +1,245 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/kilang-desa-murni/crm/internal/notification/application"
+	"github.com/kilang-desa-murni/crm/internal/notification/application/dto"
+	"github.com/kilang-desa-murni/crm/internal/notification/domain"
+)
+
+// MockRoutingRuleRepository is a mock implementation of
+// domain.RoutingRuleRepository.
+type MockRoutingRuleRepository struct {
+	mu      sync.RWMutex
+	rules   map[uuid.UUID]*domain.RoutingRule
+	findAll int
+	FindErr error
+}
+
+func NewMockRoutingRuleRepository() *MockRoutingRuleRepository {
+	return &MockRoutingRuleRepository{rules: make(map[uuid.UUID]*domain.RoutingRule)}
+}
+
+func (m *MockRoutingRuleRepository) Create(ctx context.Context, rule *domain.RoutingRule) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	stored := *rule
+	m.rules[rule.ID] = &stored
+	return nil
+}
+
+func (m *MockRoutingRuleRepository) Update(ctx context.Context, rule *domain.RoutingRule) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if existing, ok := m.rules[rule.ID]; !ok || existing.TenantID != rule.TenantID {
+		return domain.ErrRoutingRuleNotFound
+	}
+	stored := *rule
+	m.rules[rule.ID] = &stored
+	return nil
+}
+
+func (m *MockRoutingRuleRepository) FindByID(ctx context.Context, tenantID, id uuid.UUID) (*domain.RoutingRule, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	rule, ok := m.rules[id]
+	if !ok || rule.TenantID != tenantID {
+		return nil, domain.ErrRoutingRuleNotFound
+	}
+	found := *rule
+	return &found, nil
+}
+
+func (m *MockRoutingRuleRepository) FindByTenant(ctx context.Context, tenantID uuid.UUID) ([]*domain.RoutingRule, error) {
+	return m.find(func(rule *domain.RoutingRule) bool { return rule.TenantID == tenantID })
+}
+
+func (m *MockRoutingRuleRepository) FindAll(ctx context.Context) ([]*domain.RoutingRule, error) {
+	m.mu.Lock()
+	m.findAll++
+	m.mu.Unlock()
+	return m.find(func(rule *domain.RoutingRule) bool { return true })
+}
+
+func (m *MockRoutingRuleRepository) find(match func(*domain.RoutingRule) bool) ([]*domain.RoutingRule, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	if m.FindErr != nil {
+		return nil, m.FindErr
+	}
+	var rules []*domain.RoutingRule
+	for _, rule := range m.rules {
+		if match(rule) {
+			found := *rule
+			rules = append(rules, &found)
+		}
+	}
+	sort.Slice(rules, func(i, j int) bool { return rules[i].Priority < rules[j].Priority })
+	return rules, nil
+}
+
+func (m *MockRoutingRuleRepository) Delete(ctx context.Context, tenantID, id uuid.UUID) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if rule, ok := m.rules[id]; !ok || rule.TenantID != tenantID {
+		return domain.ErrRoutingRuleNotFound
+	}
+	delete(m.rules, id)
+	return nil
+}
+
+func TestRoutingRuleUseCase_CreateRule(t *testing.T) {
+	ctx := context.Background()
+	uc := NewRoutingRuleUseCase(NewMockRoutingRuleRepository())
+	tenantID := uuid.NewString()
+
+	rule, err := uc.CreateRule(ctx, &dto.CreateRoutingRuleRequest{
+		TenantID:  tenantID,
+		CreatedBy: uuid.NewString(),
+		Name:      "Big website leads",
+		EventType: "sales.lead.created",
+		Filters: []dto.RoutingFilterDTO{
+			{Field: "source", Operator: "eq", Value: "website"},
+			{Field: "estimated_value", Operator: "gte", Value: float64(1000000)},
+		},
+		Audiences:        []dto.RoutingAudienceDTO{{Type: "owner"}, {Type: "role", Value: "manager"}},
+		Channels:         []string{"in_app", "EMAIL"},
+		TemplateCode:     "big_lead",
+		ChannelTemplates: map[string]string{"in_app": "big_lead_alert"},
+	})
+	if err != nil {
+		t.Fatalf("CreateRule() error = %v", err)
+	}
+	if !rule.Enabled || len(rule.Filters) != 2 || len(rule.Audiences) != 2 {
+		t.Errorf("CreateRule() = %+v", rule)
+	}
+	if rule.Channels[1] != "email" || rule.ChannelTemplates["in_app"] != "big_lead_alert" {
+		t.Errorf("Channels = %v, ChannelTemplates = %v", rule.Channels, rule.ChannelTemplates)
+	}
+
+	invalid := []*dto.CreateRoutingRuleRequest{
+		{TenantID: tenantID, Name: "Rule", EventType: "sales.lead.deleted", Audiences: []dto.RoutingAudienceDTO{{Type: "owner"}}, Channels: []string{"email"}},
+		{TenantID: tenantID, Name: "Rule", EventType: "sales.lead.created", Audiences: []dto.RoutingAudienceDTO{{Type: "team", Value: "sales"}}, Channels: []string{"email"}},
+		{TenantID: tenantID, Name: "Rule", EventType: "sales.lead.created", Audiences: []dto.RoutingAudienceDTO{{Type: "owner"}}, Channels: []string{"fax"}},
+		{TenantID: tenantID, Name: "Rule", EventType: "sales.lead.created", Audiences: []dto.RoutingAudienceDTO{{Type: "owner"}}, Channels: []string{"email"},
+			Filters: []dto.RoutingFilterDTO{{Field: "source", Operator: "like", Value: "web"}}},
+		{TenantID: tenantID, Name: "Rule", EventType: "sales.lead.created", Audiences: []dto.RoutingAudienceDTO{{Type: "owner"}}, Channels: []string{"email"},
+			TemplateCode: "Big Lead"},
+	}
+	for _, req := range invalid {
+		_, err := uc.CreateRule(ctx, req)
+		var appErr *application.AppError
+		if !errors.As(err, &appErr) || appErr.Code != application.ErrCodeValidation {
+			t.Errorf("CreateRule(%+v) error = %v, want a validation error", req, err)
+		}
+	}
+}
+
+func TestRoutingRuleUseCase_Route(t *testing.T) {
+	ctx := context.Background()
+	repo := NewMockRoutingRuleRepository()
+	uc := NewRoutingRuleUseCase(repo)
+	tenantID := uuid.New()
+	ownerID := uuid.NewString()
+	event := &domain.RoutedEvent{
+		TenantID: tenantID,
+		Type:     domain.RoutingEventLeadCreated,
+		Data:     map[string]interface{}{"owner_id": ownerID, "source": "website"},
+	}
+
+	// Without rules of its own the tenant follows the defaults
+	routed, err := uc.Route(ctx, event)
+	if err != nil {
+		t.Fatalf("Route() error = %v", err)
+	}
+	if len(routed) != 1 || routed[0].Audience.Type != domain.RoutingAudienceRole || routed[0].Channel != domain.ChannelInApp {
+		t.Fatalf("Route() = %+v, want the default in-app notification to sales reps", routed)
+	}
+
+	// A new rule is routed by at once
+	created, err := uc.CreateRule(ctx, &dto.CreateRoutingRuleRequest{
+		TenantID:  tenantID.String(),
+		Name:      "Website leads",
+		EventType: domain.RoutingEventLeadCreated,
+		Filters:   []dto.RoutingFilterDTO{{Field: "source", Operator: "eq", Value: "website"}},
+		Audiences: []dto.RoutingAudienceDTO{{Type: "owner"}},
+		Channels:  []string{"push"},
+	})
+	if err != nil {
+		t.Fatalf("CreateRule() error = %v", err)
+	}
+	routed, _ = uc.Route(ctx, event)
+	if len(routed) != 1 || routed[0].Audience.Value != ownerID || routed[0].Channel != domain.ChannelPush {
+		t.Fatalf("Route() = %+v, want a push notification to the owner", routed)
+	}
+
+	// Disabling it silences the event
+	disabled := false
+	if _, err := uc.UpdateRule(ctx, &dto.UpdateRoutingRuleRequest{TenantID: tenantID.String(), ID: created.ID, Enabled: &disabled}); err != nil {
+		t.Fatalf("UpdateRule() error = %v", err)
+	}
+	if routed, _ = uc.Route(ctx, event); len(routed) != 0 {
+		t.Errorf("Route() = %+v, want no notifications", routed)
+	}
+
+	// Deleting it restores the defaults
+	if err := uc.DeleteRule(ctx, tenantID.String(), created.ID); err != nil {
+		t.Fatalf("DeleteRule() error = %v", err)
+	}
+	if routed, _ = uc.Route(ctx, event); len(routed) != 1 || routed[0].Audience.Type != domain.RoutingAudienceRole {
+		t.Errorf("Route() = %+v, want the default notification", routed)
+	}
+
+	if repo.findAll != 1 {
+		t.Errorf("FindAll called %d times, want the table loaded once", repo.findAll)
+	}
+}
+
+func TestRoutingRuleUseCase_Reload(t *testing.T) {
+	ctx := context.Background()
+	repo := NewMockRoutingRuleRepository()
+	uc := NewRoutingRuleUseCase(repo)
+	tenantID := uuid.New()
+	event := &domain.RoutedEvent{TenantID: tenantID, Type: domain.RoutingEventOpportunityWon, Data: map[string]interface{}{}}
+
+	if _, err := uc.Route(ctx, event); err != nil {
+		t.Fatalf("Route() error = %v", err)
+	}
+
+	// A rule another instance stored is picked up on reload
+	rule, _ := domain.NewRoutingRule(tenantID, "Managers", domain.RoutingEventOpportunityWon,
+		[]domain.RoutingAudience{{Type: domain.RoutingAudienceRole, Value: "manager"}}, []domain.NotificationChannel{domain.ChannelEmail}, nil)
+	repo.Create(ctx, rule)
+
+	routed, _ := uc.Route(ctx, event)
+	if len(routed) != 1 || routed[0].Audience.Type != domain.RoutingAudienceRecipients {
+		t.Fatalf("Route() = %+v, want the defaults before the reload", routed)
+	}
+	if err := uc.Reload(ctx); err != nil {
+		t.Fatalf("Reload() error = %v", err)
+	}
+	routed, _ = uc.Route(ctx, event)
+	if len(routed) != 1 || routed[0].Audience.Value != "manager" {
+		t.Fatalf("Route() = %+v, want the stored rule after the reload", routed)
+	}
+
+	// A failed reload keeps the table routing
+	repo.FindErr = errors.New("connection refused")
+	if err := uc.Reload(ctx); err == nil {
+		t.Error("Reload() should fail")
+	}
+	if routed, err := uc.Route(ctx, event); err != nil || len(routed) != 1 {
+		t.Errorf("Route() = %+v, %v, want the last table kept", routed, err)
+	}
+
+	// An event arriving before the table ever loaded is redelivered
+	if _, err := NewRoutingRuleUseCase(repo).Route(ctx, event); err == nil {
+		t.Error("Route() should fail without a table")
+	}
+}
@@ -4,6 +4,7 @@ package usecase
 import (
 	"context"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
@@ -12,6 +13,7 @@ import (
 	"github.com/kilang-desa-murni/crm/internal/notification/application/mapper"
 	"github.com/kilang-desa-murni/crm/internal/notification/application/ports"
 	"github.com/kilang-desa-murni/crm/internal/notification/domain"
+	"github.com/kilang-desa-murni/crm/pkg/render"
 )
 
 // TemplateUseCase defines the interface for template use cases.
@@ -40,12 +42,33 @@ type TemplateUseCase interface {
 	RenderTemplate(ctx context.Context, req *dto.RenderTemplateRequest) (*dto.RenderTemplateResponse, error)
 	// ValidateTemplate validates template syntax.
 	ValidateTemplate(ctx context.Context, req *dto.ValidateTemplateRequest) (*dto.ValidateTemplateResponse, error)
+	// GetTemplateVersions lists the version history of a template.
+	GetTemplateVersions(ctx context.Context, req *dto.GetTemplateVersionsRequest) (*dto.TemplateVersionListDTO, error)
+	// RevertTemplateVersion restores the content of a previous template version.
+	RevertTemplateVersion(ctx context.Context, req *dto.RevertTemplateVersionRequest) (*dto.RevertTemplateVersionResponse, error)
+	// DiffTemplateVersions compares the subject and body of two template versions.
+	DiffTemplateVersions(ctx context.Context, req *dto.DiffTemplateVersionsRequest) (*dto.TemplateVersionDiffDTO, error)
+	// PreviewTemplate renders a template with sample variables.
+	PreviewTemplate(ctx context.Context, req *dto.PreviewTemplateRequest) (*dto.PreviewTemplateResponse, error)
+	// TestSendTemplate sends a rendered template to an allowed test recipient.
+	TestSendTemplate(ctx context.Context, req *dto.TestSendTemplateRequest) (*dto.TestSendTemplateResponse, error)
+	// ListTemplateLocalizations lists the locale variants of a template.
+	ListTemplateLocalizations(ctx context.Context, req *dto.ListTemplateLocalizationsRequest) (*dto.TemplateLocalizationListDTO, error)
+	// SetTemplateLocalization adds or replaces a locale variant of a template.
+	SetTemplateLocalization(ctx context.Context, req *dto.SetTemplateLocalizationRequest) (*dto.TemplateLocalizationResponse, error)
+	// DeleteTemplateLocalization removes a locale variant of a template.
+	DeleteTemplateLocalization(ctx context.Context, req *dto.DeleteTemplateLocalizationRequest) (*dto.TemplateLocalizationResponse, error)
+	// SeedSystemTemplates creates the system templates a tenant does not have yet.
+	SeedSystemTemplates(ctx context.Context, req *dto.SeedSystemTemplatesRequest) (*dto.SeedSystemTemplatesResponse, error)
 }
 
 // templateUseCase implements the TemplateUseCase interface.
 type templateUseCase struct {
 	templateRepo     domain.TemplateRepository
+	revisionRepo     domain.TemplateRevisionRepository
 	notificationRepo domain.NotificationRepository
+	emailProvider    ports.EmailProvider
+	smsProvider      ports.SMSProvider
 	eventPublisher   ports.EventPublisher
 	cache            ports.CacheService
 	idGenerator      ports.IdGenerator
@@ -53,32 +76,44 @@ type templateUseCase struct {
 	metrics          ports.MetricsCollector
 	logger           ports.Logger
 
+	// testRecipients are the email addresses and phone numbers that test
+	// sends may be delivered to.
+	testRecipients []string
+
 	mapper *mapper.TemplateMapper
 }
 
 // TemplateUseCaseConfig holds configuration for the template use case.
 type TemplateUseCaseConfig struct {
 	TemplateRepo     domain.TemplateRepository
+	RevisionRepo     domain.TemplateRevisionRepository
 	NotificationRepo domain.NotificationRepository
+	EmailProvider    ports.EmailProvider
+	SMSProvider      ports.SMSProvider
 	EventPublisher   ports.EventPublisher
 	Cache            ports.CacheService
 	IdGenerator      ports.IdGenerator
 	TimeProvider     ports.TimeProvider
 	Metrics          ports.MetricsCollector
 	Logger           ports.Logger
+	TestRecipients   []string
 }
 
 // NewTemplateUseCase creates a new TemplateUseCase.
 func NewTemplateUseCase(cfg TemplateUseCaseConfig) TemplateUseCase {
 	return &templateUseCase{
 		templateRepo:     cfg.TemplateRepo,
+		revisionRepo:     cfg.RevisionRepo,
 		notificationRepo: cfg.NotificationRepo,
+		emailProvider:    cfg.EmailProvider,
+		smsProvider:      cfg.SMSProvider,
 		eventPublisher:   cfg.EventPublisher,
 		cache:            cfg.Cache,
 		idGenerator:      cfg.IdGenerator,
 		timeProvider:     cfg.TimeProvider,
 		metrics:          cfg.Metrics,
 		logger:           cfg.Logger,
+		testRecipients:   cfg.TestRecipients,
 
 		mapper: mapper.NewTemplateMapper(),
 	}
@@ -142,7 +177,16 @@ func (uc *templateUseCase) CreateTemplate(ctx context.Context, req *dto.CreateTe
 
 	// Set default locale
 	if req.DefaultLocale != "" {
-		template.DefaultLocale = req.DefaultLocale
+		if err := template.SetDefaultLocale(req.DefaultLocale); err != nil {
+			return nil, application.NewInvalidInputError(err.Error())
+		}
+	}
+
+	// Set retry policy override
+	if req.RetryPolicy != nil {
+		if err := template.SetRetryPolicy(uc.mapper.RetryPolicyFromDTO(req.RetryPolicy)); err != nil {
+			return nil, application.NewInvalidInputError(err.Error())
+		}
 	}
 
 	// Set tags
@@ -191,6 +235,13 @@ func (uc *templateUseCase) CreateTemplate(ctx context.Context, req *dto.CreateTe
 		}
 	}
 
+	// Add locale variants
+	for _, l := range req.Localizations {
+		if err := template.AddLocalization(l.Locale, buildLocalization(template, l.Subject, l.Body, l.HTMLBody)); err != nil {
+			return nil, application.NewInvalidInputError(err.Error())
+		}
+	}
+
 	// Add variables
 	for _, v := range req.Variables {
 		variable := domain.TemplateVariable{
@@ -223,6 +274,9 @@ func (uc *templateUseCase) CreateTemplate(ctx context.Context, req *dto.CreateTe
 		return nil, application.NewInternalError("failed to create template", err)
 	}
 
+	// Record the first version
+	uc.recordRevision(ctx, template, domain.TemplateRevisionCreated, "Template created")
+
 	// Publish domain events
 	uc.publishDomainEvents(ctx, template)
 
@@ -337,6 +391,23 @@ func (uc *templateUseCase) UpdateTemplate(ctx context.Context, req *dto.UpdateTe
 		}
 	}
 
+	// Replace the given locale variants
+	for _, l := range req.Localizations {
+		if err := template.AddLocalization(l.Locale, buildLocalization(template, l.Subject, l.Body, l.HTMLBody)); err != nil {
+			return nil, application.NewInvalidInputError(err.Error())
+		}
+	}
+	if req.DefaultLocale != nil {
+		if err := template.SetDefaultLocale(*req.DefaultLocale); err != nil {
+			return nil, application.NewInvalidInputError(err.Error())
+		}
+	}
+	if req.RetryPolicy != nil {
+		if err := template.SetRetryPolicy(uc.mapper.RetryPolicyFromDTO(req.RetryPolicy)); err != nil {
+			return nil, application.NewInvalidInputError(err.Error())
+		}
+	}
+
 	// Set updated by
 	if req.UpdatedBy != "" {
 		updatedByID, err := uuid.Parse(req.UpdatedBy)
@@ -353,6 +424,9 @@ func (uc *templateUseCase) UpdateTemplate(ctx context.Context, req *dto.UpdateTe
 		return nil, application.NewInternalError("failed to update template", err)
 	}
 
+	// Record the new version
+	uc.recordRevision(ctx, template, domain.TemplateRevisionUpdated, "Template updated")
+
 	// Publish domain events
 	uc.publishDomainEvents(ctx, template)
 
@@ -437,6 +511,9 @@ func (uc *templateUseCase) PublishTemplate(ctx context.Context, req *dto.Publish
 		return nil, application.NewInternalError("failed to publish template", err)
 	}
 
+	// Record the published version
+	uc.recordRevision(ctx, template, domain.TemplateRevisionPublished, fmt.Sprintf("Published as version %d", template.TemplateVersion))
+
 	// Publish domain events
 	uc.publishDomainEvents(ctx, template)
 
@@ -969,8 +1046,354 @@ func (uc *templateUseCase) ValidateTemplate(ctx context.Context, req *dto.Valida
 	return uc.mapper.ToValidationResponse(len(errors) == 0, errors, warnings, detectedVariables), nil
 }
 
+// GetTemplateVersions lists the version history of a template.
+func (uc *templateUseCase) GetTemplateVersions(ctx context.Context, req *dto.GetTemplateVersionsRequest) (*dto.TemplateVersionListDTO, error) {
+	template, err := uc.findTenantTemplate(ctx, req.TenantID, req.TemplateID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Set defaults
+	page := req.Page
+	if page < 1 {
+		page = 1
+	}
+	pageSize := req.PageSize
+	if pageSize < 1 {
+		pageSize = 20
+	}
+	if pageSize > 50 {
+		pageSize = 50
+	}
+
+	revisions, total, err := uc.revisionRepo.FindByTemplate(ctx, template.ID, (page-1)*pageSize, pageSize)
+	if err != nil {
+		return nil, application.NewInternalError("failed to list template versions", err)
+	}
+
+	return &dto.TemplateVersionListDTO{
+		TemplateID: req.TemplateID,
+		Versions:   uc.mapper.RevisionsToVersionDTOList(revisions),
+		TotalCount: int(total),
+	}, nil
+}
+
+// RevertTemplateVersion restores the content of a previous template version.
+// The restored content is recorded as a new version, so the history is kept.
+func (uc *templateUseCase) RevertTemplateVersion(ctx context.Context, req *dto.RevertTemplateVersionRequest) (*dto.RevertTemplateVersionResponse, error) {
+	uc.logger.WithContext(ctx).Info("RevertTemplateVersion started", map[string]interface{}{
+		"tenant_id":   req.TenantID,
+		"template_id": req.TemplateID,
+		"version":     req.Version,
+	})
+
+	template, err := uc.findTenantTemplate(ctx, req.TenantID, req.TemplateID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Check if template is locked
+	if template.IsLocked {
+		return nil, application.NewInvalidStateError("cannot revert a locked template")
+	}
+
+	// Check if template is archived
+	if template.DeletedAt != nil {
+		return nil, application.NewInvalidStateError("cannot revert an archived template")
+	}
+
+	revision, err := uc.findRevision(ctx, template, req.Version)
+	if err != nil {
+		return nil, err
+	}
+
+	var revertedBy *uuid.UUID
+	if req.RevertedBy != "" {
+		revertedByID, err := uuid.Parse(req.RevertedBy)
+		if err == nil {
+			revertedBy = &revertedByID
+		}
+	}
+
+	if err := template.RollbackTo(revision, revertedBy); err != nil {
+		return nil, application.NewAppError(application.ErrCodeTemplateVersionError, err.Error())
+	}
+
+	// Save template
+	if err := uc.templateRepo.Update(ctx, template); err != nil {
+		return nil, application.NewInternalError("failed to revert template", err)
+	}
+
+	// Record the restored content as a new version
+	number := uc.recordRevision(ctx, template, domain.TemplateRevisionRolledBack, fmt.Sprintf("Rolled back to version %d", req.Version))
+
+	// Publish domain events
+	uc.publishDomainEvents(ctx, template)
+
+	// Invalidate cache
+	uc.invalidateTemplateCache(ctx, req.TenantID, req.TemplateID, template.Code)
+
+	uc.metrics.IncrementCounter(ctx, "template.reverted", map[string]string{
+		"tenant_id": req.TenantID,
+	})
+
+	return &dto.RevertTemplateVersionResponse{
+		TemplateID: req.TemplateID,
+		Version:    number,
+		RevertedTo: req.Version,
+		RevertedAt: template.UpdatedAt,
+		Message:    "Template reverted successfully",
+	}, nil
+}
+
+// DiffTemplateVersions compares the subject and body of two template versions.
+func (uc *templateUseCase) DiffTemplateVersions(ctx context.Context, req *dto.DiffTemplateVersionsRequest) (*dto.TemplateVersionDiffDTO, error) {
+	if req.FromVersion < 1 || req.ToVersion < 1 {
+		return nil, application.NewInvalidInputError("from_version and to_version must be positive")
+	}
+
+	template, err := uc.findTenantTemplate(ctx, req.TenantID, req.TemplateID)
+	if err != nil {
+		return nil, err
+	}
+
+	from, err := uc.findRevision(ctx, template, req.FromVersion)
+	if err != nil {
+		return nil, err
+	}
+	to, err := uc.findRevision(ctx, template, req.ToVersion)
+	if err != nil {
+		return nil, err
+	}
+
+	diffs := domain.DiffTemplateRevisions(from, to)
+	return uc.mapper.ToVersionDiffDTO(req.TemplateID, req.FromVersion, req.ToVersion, diffs), nil
+}
+
+// PreviewTemplate renders a template with sample variables. Variables that are
+// not provided fall back to their default value or example.
+func (uc *templateUseCase) PreviewTemplate(ctx context.Context, req *dto.PreviewTemplateRequest) (*dto.PreviewTemplateResponse, error) {
+	template, err := uc.findTenantTemplate(ctx, req.TenantID, req.TemplateID)
+	if err != nil {
+		return nil, err
+	}
+
+	return uc.renderPreview(template, req.Channel, req.Locale, req.Variables)
+}
+
+// TestSendTemplate sends a rendered template to an allowed test recipient. The
+// message is sent straight to the provider: it is not stored as a
+// notification, does not count towards quotas and does not record template
+// usage.
+func (uc *templateUseCase) TestSendTemplate(ctx context.Context, req *dto.TestSendTemplateRequest) (*dto.TestSendTemplateResponse, error) {
+	uc.logger.WithContext(ctx).Info("TestSendTemplate started", map[string]interface{}{
+		"tenant_id":   req.TenantID,
+		"template_id": req.TemplateID,
+		"channel":     req.Channel,
+	})
+
+	recipient := strings.TrimSpace(req.Recipient)
+	if recipient == "" {
+		return nil, application.NewInvalidInputError("recipient is required")
+	}
+	if !uc.isTestRecipient(recipient) {
+		return nil, application.NewForbiddenError("recipient is not an allowed test address")
+	}
+
+	template, err := uc.findTenantTemplate(ctx, req.TenantID, req.TemplateID)
+	if err != nil {
+		return nil, err
+	}
+
+	preview, err := uc.renderPreview(template, req.Channel, req.Locale, req.Variables)
+	if err != nil {
+		return nil, err
+	}
+
+	messageID := uc.idGenerator.GenerateWithPrefix("test")
+	tags := map[string]string{
+		"tenant_id":   req.TenantID,
+		"template_id": req.TemplateID,
+		"test_send":   "true",
+	}
+
+	result := &dto.TestSendTemplateResponse{
+		TemplateID: req.TemplateID,
+		Channel:    preview.Channel,
+		Recipient:  recipient,
+		MessageID:  messageID,
+		Message:    "Test message sent successfully",
+	}
+
+	switch domain.NotificationChannel(preview.Channel) {
+	case domain.ChannelEmail:
+		if uc.emailProvider == nil {
+			return nil, application.NewChannelNotConfiguredError(preview.Channel)
+		}
+		emailReq := ports.EmailRequest{
+			MessageID: messageID,
+			To:        []string{recipient},
+			Subject:   "[TEST] " + preview.Subject,
+			HTMLBody:  preview.HTMLBody,
+			TextBody:  preview.Body,
+			Tags:      tags,
+		}
+		if template.EmailTemplate != nil {
+			emailReq.From = template.EmailTemplate.FromAddress
+			emailReq.ReplyTo = template.EmailTemplate.ReplyTo
+		}
+		resp, err := uc.emailProvider.SendEmail(ctx, emailReq)
+		if err != nil {
+			return nil, application.NewEmailDeliveryFailedError(recipient, err.Error())
+		}
+		result.Provider = resp.Provider
+		result.Status = resp.Status
+		result.SentAt = resp.SentAt
+
+	case domain.ChannelSMS:
+		if uc.smsProvider == nil {
+			return nil, application.NewChannelNotConfiguredError(preview.Channel)
+		}
+		resp, err := uc.smsProvider.SendSMS(ctx, ports.SMSRequest{
+			MessageID: messageID,
+			To:        recipient,
+			Body:      preview.Body,
+			Tags:      tags,
+		})
+		if err != nil {
+			return nil, application.NewSMSDeliveryFailedError(recipient, err.Error())
+		}
+		result.Provider = resp.Provider
+		result.Status = resp.Status
+		result.SentAt = resp.SentAt
+
+	default:
+		return nil, application.NewInvalidInputError("test sends are supported for email and sms templates only")
+	}
+
+	uc.metrics.IncrementCounter(ctx, "template.test_sent", map[string]string{
+		"tenant_id": req.TenantID,
+		"channel":   preview.Channel,
+	})
+
+	return result, nil
+}
+
 // === Private helper methods ===
 
+// ListTemplateLocalizations lists the locale variants of a template.
+func (uc *templateUseCase) ListTemplateLocalizations(ctx context.Context, req *dto.ListTemplateLocalizationsRequest) (*dto.TemplateLocalizationListDTO, error) {
+	template, err := uc.findTenantTemplate(ctx, req.TenantID, req.TemplateID)
+	if err != nil {
+		return nil, err
+	}
+
+	return uc.mapper.ToLocalizationListDTO(template), nil
+}
+
+// SetTemplateLocalization adds or replaces a locale variant of a template. The
+// subject and body are applied to each of the template's channels.
+func (uc *templateUseCase) SetTemplateLocalization(ctx context.Context, req *dto.SetTemplateLocalizationRequest) (*dto.TemplateLocalizationResponse, error) {
+	uc.logger.WithContext(ctx).Info("SetTemplateLocalization started", map[string]interface{}{
+		"tenant_id":   req.TenantID,
+		"template_id": req.TemplateID,
+		"locale":      req.Locale,
+	})
+
+	if req.Body == "" {
+		return nil, application.NewValidationError("body is required")
+	}
+	for _, content := range []string{req.Subject, req.Body, req.HTMLBody} {
+		if err := validateTemplateSyntax(content); err != nil {
+			return nil, application.NewInvalidInputError(fmt.Sprintf("invalid template syntax: %v", err))
+		}
+	}
+
+	template, err := uc.findEditableTemplate(ctx, req.TenantID, req.TemplateID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := template.AddLocalization(req.Locale, buildLocalization(template, req.Subject, req.Body, req.HTMLBody)); err != nil {
+		return nil, application.NewInvalidInputError(err.Error())
+	}
+	locale := template.GetLocalization(req.Locale).Locale
+
+	return uc.saveLocalizationChange(ctx, template, req.UpdatedBy, locale, "template.localization_set", fmt.Sprintf("Locale %s updated", locale))
+}
+
+// DeleteTemplateLocalization removes a locale variant of a template.
+func (uc *templateUseCase) DeleteTemplateLocalization(ctx context.Context, req *dto.DeleteTemplateLocalizationRequest) (*dto.TemplateLocalizationResponse, error) {
+	uc.logger.WithContext(ctx).Info("DeleteTemplateLocalization started", map[string]interface{}{
+		"tenant_id":   req.TenantID,
+		"template_id": req.TemplateID,
+		"locale":      req.Locale,
+	})
+
+	template, err := uc.findEditableTemplate(ctx, req.TenantID, req.TemplateID)
+	if err != nil {
+		return nil, err
+	}
+
+	localization := template.GetLocalization(req.Locale)
+	if localization == nil {
+		return nil, application.NewNotFoundError("template locale", req.Locale)
+	}
+	template.RemoveLocalization(localization.Locale)
+
+	return uc.saveLocalizationChange(ctx, template, req.UpdatedBy, localization.Locale, "template.localization_deleted", fmt.Sprintf("Locale %s removed", localization.Locale))
+}
+
+// SeedSystemTemplates creates the system templates a tenant does not have yet:
+// the welcome and password reset emails, in English and Malay. Templates the
+// tenant already has, edited or not, are left as they are, so seeding again
+// is safe.
+func (uc *templateUseCase) SeedSystemTemplates(ctx context.Context, req *dto.SeedSystemTemplatesRequest) (*dto.SeedSystemTemplatesResponse, error) {
+	tenantID, err := uuid.Parse(req.TenantID)
+	if err != nil {
+		return nil, application.NewInvalidInputError("invalid tenant ID format")
+	}
+
+	templates, err := domain.NewSystemTemplates(tenantID)
+	if err != nil {
+		return nil, application.NewInternalError("failed to build system templates", err)
+	}
+
+	result := &dto.SeedSystemTemplatesResponse{Created: []string{}, Existing: []string{}}
+	for _, template := range templates {
+		exists, err := uc.templateRepo.ExistsByCode(ctx, tenantID, template.Code)
+		if err != nil {
+			return nil, application.NewInternalError("failed to check template existence", err)
+		}
+		if exists {
+			result.Existing = append(result.Existing, template.Code)
+			continue
+		}
+
+		template.Publish()
+		if err := uc.templateRepo.Create(ctx, template); err != nil {
+			return nil, application.NewInternalError("failed to create system template", err)
+		}
+		uc.recordRevision(ctx, template, domain.TemplateRevisionCreated, "System template created")
+		uc.publishDomainEvents(ctx, template)
+		uc.invalidateTemplateCache(ctx, req.TenantID, template.ID.String(), template.Code)
+		result.Created = append(result.Created, template.Code)
+	}
+
+	if len(result.Created) > 0 {
+		uc.metrics.IncrementCounter(ctx, "template.system_seeded", map[string]string{
+			"tenant_id": req.TenantID,
+		})
+	}
+	uc.logger.WithContext(ctx).Info("System templates seeded", map[string]interface{}{
+		"tenant_id": req.TenantID,
+		"created":   result.Created,
+		"existing":  result.Existing,
+	})
+
+	return result, nil
+}
+
 func (uc *templateUseCase) validateCreateTemplateRequest(req *dto.CreateTemplateRequest) error {
 	if req.TenantID == "" {
 		return application.NewValidationError("tenant_id is required")
@@ -1002,6 +1425,197 @@ func (uc *templateUseCase) validateCreateTemplateRequest(req *dto.CreateTemplate
 	return nil
 }
 
+// findTenantTemplate loads a template and verifies that it belongs to the tenant.
+func (uc *templateUseCase) findTenantTemplate(ctx context.Context, tenantIDStr, templateIDStr string) (*domain.NotificationTemplate, error) {
+	// Parse template ID
+	templateID, err := uuid.Parse(templateIDStr)
+	if err != nil {
+		return nil, application.NewInvalidInputError("invalid template ID format")
+	}
+
+	// Parse tenant ID
+	tenantID, err := uuid.Parse(tenantIDStr)
+	if err != nil {
+		return nil, application.NewInvalidInputError("invalid tenant ID format")
+	}
+
+	template, err := uc.templateRepo.FindByID(ctx, templateID)
+	if err != nil {
+		return nil, application.NewTemplateNotFoundError(templateIDStr)
+	}
+
+	// Verify tenant access
+	if template.TenantID != tenantID {
+		return nil, application.NewForbiddenError("access denied to this template")
+	}
+
+	return template, nil
+}
+
+// findEditableTemplate loads a tenant's template that is neither locked nor archived.
+func (uc *templateUseCase) findEditableTemplate(ctx context.Context, tenantIDStr, templateIDStr string) (*domain.NotificationTemplate, error) {
+	template, err := uc.findTenantTemplate(ctx, tenantIDStr, templateIDStr)
+	if err != nil {
+		return nil, err
+	}
+
+	// Check if template is locked
+	if template.IsLocked {
+		return nil, application.NewInvalidStateError("cannot update a locked template")
+	}
+
+	// Check if template is archived
+	if template.DeletedAt != nil {
+		return nil, application.NewInvalidStateError("cannot update an archived template")
+	}
+
+	return template, nil
+}
+
+// saveLocalizationChange saves a template after one of its locale variants
+// changed and records the change as a new version.
+func (uc *templateUseCase) saveLocalizationChange(ctx context.Context, template *domain.NotificationTemplate, updatedBy, locale, metric, changeSummary string) (*dto.TemplateLocalizationResponse, error) {
+	if updatedBy != "" {
+		updatedByID, err := uuid.Parse(updatedBy)
+		if err == nil {
+			template.UpdatedBy = &updatedByID
+		}
+	}
+
+	// Save template
+	if err := uc.templateRepo.Update(ctx, template); err != nil {
+		return nil, application.NewInternalError("failed to update template localization", err)
+	}
+
+	// Record the new version
+	uc.recordRevision(ctx, template, domain.TemplateRevisionUpdated, changeSummary)
+
+	// Invalidate cache
+	uc.invalidateTemplateCache(ctx, template.TenantID.String(), template.ID.String(), template.Code)
+
+	uc.metrics.IncrementCounter(ctx, metric, map[string]string{
+		"tenant_id": template.TenantID.String(),
+		"locale":    locale,
+	})
+
+	return &dto.TemplateLocalizationResponse{
+		TemplateID: template.ID.String(),
+		Locale:     locale,
+		UpdatedAt:  template.UpdatedAt,
+		Message:    "Template localization updated successfully",
+	}, nil
+}
+
+// findRevision loads a version of a template.
+func (uc *templateUseCase) findRevision(ctx context.Context, template *domain.NotificationTemplate, number int) (*domain.TemplateRevision, error) {
+	revision, err := uc.revisionRepo.FindByNumber(ctx, template.ID, number)
+	if err != nil || revision == nil || revision.TenantID != template.TenantID {
+		return nil, application.NewNotFoundError("template version", fmt.Sprintf("%s@%d", template.ID, number))
+	}
+	return revision, nil
+}
+
+// recordRevision stores the template's current content as its next version
+// and returns the version number. The template change has already been saved,
+// so a failure is logged rather than returned.
+func (uc *templateUseCase) recordRevision(ctx context.Context, template *domain.NotificationTemplate, reason domain.TemplateRevisionReason, changeSummary string) int {
+	latest, err := uc.revisionRepo.GetLatestNumber(ctx, template.ID)
+	if err == nil {
+		revision := domain.NewTemplateRevision(template, latest+1, reason, changeSummary)
+		if err = uc.revisionRepo.Create(ctx, revision); err == nil {
+			return revision.Number
+		}
+	}
+
+	uc.logger.WithContext(ctx).Error("failed to record template version", err, map[string]interface{}{
+		"template_id": template.ID.String(),
+		"reason":      string(reason),
+	})
+	return 0
+}
+
+// renderPreview renders one channel of a template with sample variables. The
+// channel defaults to the template's first channel.
+func (uc *templateUseCase) renderPreview(template *domain.NotificationTemplate, channelStr, locale string, variables map[string]interface{}) (*dto.PreviewTemplateResponse, error) {
+	if channelStr == "" && len(template.Channels) > 0 {
+		channelStr = template.Channels[0].String()
+	}
+	channel, err := domain.ParseChannel(channelStr)
+	if err != nil {
+		return nil, application.NewInvalidInputError(fmt.Sprintf("invalid channel: %s", channelStr))
+	}
+	if !template.SupportsChannel(channel) {
+		return nil, application.NewInvalidInputError(fmt.Sprintf("template has no %s content", channel))
+	}
+
+	if locale == "" {
+		locale = template.DefaultLocale
+	}
+
+	sample, err := template.SampleVariables(variables)
+	if err != nil {
+		if tmplErr, ok := err.(*domain.TemplateError); ok {
+			return nil, application.NewValidationErrorWithDetails("template variables are invalid", map[string]interface{}{
+				"missing_variables": tmplErr.MissingVars,
+				"invalid_variables": tmplErr.InvalidVars,
+			})
+		}
+		return nil, application.NewInvalidInputError(err.Error())
+	}
+
+	preview := &dto.PreviewTemplateResponse{
+		TemplateID: template.ID.String(),
+		Channel:    channel.String(),
+		Locale:     locale,
+		Variables:  sample,
+	}
+
+	switch channel {
+	case domain.ChannelEmail:
+		rendered, err := template.RenderEmail(sample, locale)
+		if err != nil {
+			return nil, application.NewTemplateRenderFailedError(preview.TemplateID, err.Error())
+		}
+		preview.Subject = rendered.Subject
+		preview.Body = rendered.Body
+		preview.HTMLBody = rendered.HTMLBody
+	case domain.ChannelSMS:
+		rendered, err := template.RenderSMS(sample, locale)
+		if err != nil {
+			return nil, application.NewTemplateRenderFailedError(preview.TemplateID, err.Error())
+		}
+		preview.Body = rendered.Body
+	case domain.ChannelPush:
+		rendered, err := template.RenderPush(sample, locale)
+		if err != nil {
+			return nil, application.NewTemplateRenderFailedError(preview.TemplateID, err.Error())
+		}
+		preview.Subject = rendered.Title
+		preview.Body = rendered.Body
+	case domain.ChannelInApp:
+		rendered, err := template.RenderInApp(sample, locale)
+		if err != nil {
+			return nil, application.NewTemplateRenderFailedError(preview.TemplateID, err.Error())
+		}
+		preview.Subject = rendered.Title
+		preview.Body = rendered.Body
+	default:
+		return nil, application.NewInvalidInputError(fmt.Sprintf("channel %s cannot be previewed", channel))
+	}
+
+	return preview, nil
+}
+
+// isTestRecipient reports whether recipient is an allowed test address.
+func (uc *templateUseCase) isTestRecipient(recipient string) bool {
+	for _, allowed := range uc.testRecipients {
+		if strings.EqualFold(strings.TrimSpace(allowed), recipient) {
+			return true
+		}
+	}
+	return false
+}
+
 func (uc *templateUseCase) publishDomainEvents(ctx context.Context, template *domain.NotificationTemplate) {
 	events := template.GetDomainEvents()
 	for _, event := range events {
@@ -1035,41 +1649,45 @@ func (uc *templateUseCase) invalidateTemplateCache(ctx context.Context, tenantID
 	}
 }
 
-// validateTemplateSyntax validates Go template syntax.
-func validateTemplateSyntax(templateStr string) error {
-	if templateStr == "" {
-		return nil
+// buildLocalization builds a locale variant with the given subject and body for
+// each channel the template has content for.
+func buildLocalization(template *domain.NotificationTemplate, subject, body, htmlBody string) *domain.TemplateLocalization {
+	localization := &domain.TemplateLocalization{}
+	if template.EmailTemplate != nil {
+		localization.EmailTemplate = &domain.EmailTemplateContent{
+			Subject:  subject,
+			Body:     body,
+			HTMLBody: htmlBody,
+		}
 	}
-	_, err := parseGoTemplate(templateStr)
-	return err
-}
-
-// parseGoTemplate parses a Go template string.
-func parseGoTemplate(templateStr string) (interface{}, error) {
-	// Using text/template from standard library
-	// Import would be: "text/template"
-	// For now, simplified validation
-	if templateStr == "" {
-		return nil, nil
+	if template.SMSTemplate != nil {
+		localization.SMSTemplate = &domain.SMSTemplateContent{
+			Body:     body,
+			SenderID: template.SMSTemplate.SenderID,
+		}
 	}
-	// Check for balanced braces
-	openCount := 0
-	for i := 0; i < len(templateStr)-1; i++ {
-		if templateStr[i] == '{' && templateStr[i+1] == '{' {
-			openCount++
-			i++
-		} else if templateStr[i] == '}' && templateStr[i+1] == '}' {
-			openCount--
-			i++
-			if openCount < 0 {
-				return nil, fmt.Errorf("unbalanced template braces at position %d", i)
-			}
+	if template.PushTemplate != nil {
+		localization.PushTemplate = &domain.PushTemplateContent{
+			Title: subject,
+			Body:  body,
+		}
+	}
+	if template.InAppTemplate != nil {
+		localization.InAppTemplate = &domain.InAppTemplateContent{
+			Title: subject,
+			Body:  body,
 		}
 	}
-	if openCount != 0 {
-		return nil, fmt.Errorf("unbalanced template braces: %d unclosed", openCount)
+	return localization
+}
+
+// validateTemplateSyntax validates Go template syntax, including that the
+// template only calls the helpers of the render package.
+func validateTemplateSyntax(templateStr string) error {
+	if templateStr == "" {
+		return nil
 	}
-	return templateStr, nil
+	return render.Validate(templateStr)
 }
 
 // extractTemplateVariables extracts variable names from a Go template string.
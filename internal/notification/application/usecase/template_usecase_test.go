@@ -267,6 +267,57 @@ func (m *mockTemplateRepository) Search(ctx context.Context, tenantID uuid.UUID,
 	return m.List(ctx, filter)
 }
 
+// mockTemplateRevisionRepository is a mock implementation of domain.TemplateRevisionRepository.
+type mockTemplateRevisionRepository struct {
+	mu        sync.RWMutex
+	revisions map[uuid.UUID][]*domain.TemplateRevision
+	createErr error
+}
+
+func newMockTemplateRevisionRepository() *mockTemplateRevisionRepository {
+	return &mockTemplateRevisionRepository{
+		revisions: make(map[uuid.UUID][]*domain.TemplateRevision),
+	}
+}
+
+func (m *mockTemplateRevisionRepository) Create(ctx context.Context, revision *domain.TemplateRevision) error {
+	if m.createErr != nil {
+		return m.createErr
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.revisions[revision.TemplateID] = append(m.revisions[revision.TemplateID], revision)
+	return nil
+}
+
+func (m *mockTemplateRevisionRepository) FindByTemplate(ctx context.Context, templateID uuid.UUID, offset, limit int) ([]*domain.TemplateRevision, int64, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	all := m.revisions[templateID]
+	result := make([]*domain.TemplateRevision, 0)
+	for i := len(all) - 1 - offset; i >= 0 && len(result) < limit; i-- {
+		result = append(result, all[i])
+	}
+	return result, int64(len(all)), nil
+}
+
+func (m *mockTemplateRevisionRepository) FindByNumber(ctx context.Context, templateID uuid.UUID, number int) (*domain.TemplateRevision, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	for _, revision := range m.revisions[templateID] {
+		if revision.Number == number {
+			return revision, nil
+		}
+	}
+	return nil, domain.ErrTemplateRevisionNotFound
+}
+
+func (m *mockTemplateRevisionRepository) GetLatestNumber(ctx context.Context, templateID uuid.UUID) (int, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return len(m.revisions[templateID]), nil
+}
+
 // mockNotificationRepository is a mock implementation of domain.NotificationRepository.
 type mockNotificationRepository struct{}
 
@@ -286,6 +337,9 @@ func (m *mockNotificationRepository) FindByID(ctx context.Context, id uuid.UUID)
 func (m *mockNotificationRepository) FindByCode(ctx context.Context, tenantID uuid.UUID, code string) (*domain.Notification, error) {
 	return nil, nil
 }
+func (m *mockNotificationRepository) FindByProviderMessageID(ctx context.Context, provider, providerMessageID string) (*domain.Notification, error) {
+	return nil, nil
+}
 func (m *mockNotificationRepository) List(ctx context.Context, filter domain.NotificationFilter) (*domain.NotificationList, error) {
 	return nil, nil
 }
@@ -322,7 +376,7 @@ func (m *mockNotificationRepository) CountByStatus(ctx context.Context, tenantID
 func (m *mockNotificationRepository) CountByChannel(ctx context.Context, tenantID uuid.UUID) (map[domain.NotificationChannel]int64, error) {
 	return nil, nil
 }
-func (m *mockNotificationRepository) GetStats(ctx context.Context, tenantID uuid.UUID, startDate, endDate time.Time) (*domain.NotificationStats, error) {
+func (m *mockNotificationRepository) GetStats(ctx context.Context, query domain.NotificationStatsQuery) (*domain.NotificationStats, error) {
 	return nil, nil
 }
 func (m *mockNotificationRepository) BulkCreate(ctx context.Context, notifications []*domain.Notification) error {
@@ -350,6 +404,10 @@ func (m *mockNotificationRepository) DeleteOld(ctx context.Context, before time.
 	return 0, nil
 }
 
+func (m *mockNotificationRepository) PurgeBodies(ctx context.Context, filter domain.BodyPurgeFilter) (int64, error) {
+	return 0, nil
+}
+
 // mockEventPublisher is a mock implementation of ports.EventPublisher.
 type mockEventPublisher struct {
 	events []domain.DomainEvent
@@ -594,6 +652,7 @@ func createTemplateTestUseCase() (TemplateUseCase, *mockTemplateRepository, *tes
 
 	cfg := TemplateUseCaseConfig{
 		TemplateRepo:     repo,
+		RevisionRepo:     newMockTemplateRevisionRepository(),
 		NotificationRepo: &mockNotificationRepository{},
 		EventPublisher:   &mockEventPublisher{},
 		Cache:            newMockCacheService(),
@@ -607,6 +666,32 @@ func createTemplateTestUseCase() (TemplateUseCase, *mockTemplateRepository, *tes
 	return uc, repo, fixtures
 }
 
+// createTemplateTestSendUseCase creates a template use case that can send test
+// messages to qa@example.com.
+func createTemplateTestSendUseCase() (TemplateUseCase, *mockTemplateRepository, *MockEmailProvider, *testFixtures) {
+	repo := newMockTemplateRepository()
+	emailProvider := NewMockEmailProvider()
+	fixtures := newTestFixtures()
+
+	cfg := TemplateUseCaseConfig{
+		TemplateRepo:     repo,
+		RevisionRepo:     newMockTemplateRevisionRepository(),
+		NotificationRepo: &mockNotificationRepository{},
+		EmailProvider:    emailProvider,
+		SMSProvider:      NewMockSMSProvider(),
+		EventPublisher:   &mockEventPublisher{},
+		Cache:            newMockCacheService(),
+		IdGenerator:      &mockIdGenerator{},
+		TimeProvider:     &mockTimeProvider{},
+		Metrics:          newMockMetricsCollector(),
+		Logger:           &mockLogger{},
+		TestRecipients:   []string{"qa@example.com"},
+	}
+
+	uc := NewTemplateUseCase(cfg)
+	return uc, repo, emailProvider, fixtures
+}
+
 // createValidCreateTemplateRequest creates a valid create template request.
 func createValidCreateTemplateRequest(tenantID, userID uuid.UUID) *dto.CreateTemplateRequest {
 	return &dto.CreateTemplateRequest{
@@ -1917,6 +2002,526 @@ func TestValidateTemplate_UnusedVariables(t *testing.T) {
 	}
 }
 
+// =============================================================================
+// Template Version Tests
+// =============================================================================
+
+// createVersionedTemplate creates a template through the use case and updates
+// its subject and body once, leaving it with two versions.
+func createVersionedTemplate(t *testing.T, uc TemplateUseCase, fixtures *testFixtures) string {
+	ctx := context.Background()
+
+	created, err := uc.CreateTemplate(ctx, createValidCreateTemplateRequest(fixtures.tenantID, fixtures.userID))
+	if err != nil {
+		t.Fatalf("expected no error creating template, got: %v", err)
+	}
+
+	subject := "Welcome aboard {{.Name}}!"
+	body := "Hello {{.Name}}, welcome to our platform!\nYour account is ready."
+	_, err = uc.UpdateTemplate(ctx, &dto.UpdateTemplateRequest{
+		TenantID:   fixtures.tenantID.String(),
+		TemplateID: created.TemplateID,
+		Subject:    &subject,
+		Body:       &body,
+		UpdatedBy:  fixtures.userID.String(),
+	})
+	if err != nil {
+		t.Fatalf("expected no error updating template, got: %v", err)
+	}
+
+	return created.TemplateID
+}
+
+func TestGetTemplateVersions_RecordsEachChange(t *testing.T) {
+	uc, _, fixtures := createTemplateTestUseCase()
+	ctx := context.Background()
+
+	templateID := createVersionedTemplate(t, uc, fixtures)
+
+	resp, err := uc.GetTemplateVersions(ctx, &dto.GetTemplateVersionsRequest{
+		TenantID:   fixtures.tenantID.String(),
+		TemplateID: templateID,
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+
+	if resp.TotalCount != 2 || len(resp.Versions) != 2 {
+		t.Fatalf("expected 2 versions, got %d (total %d)", len(resp.Versions), resp.TotalCount)
+	}
+
+	// Newest first; the first version is not changed by the update
+	if resp.Versions[0].Version != 2 || resp.Versions[0].Subject != "Welcome aboard {{.Name}}!" {
+		t.Errorf("expected version 2 with the updated subject, got %d %q", resp.Versions[0].Version, resp.Versions[0].Subject)
+	}
+	if resp.Versions[1].Version != 1 || resp.Versions[1].Subject != "Welcome {{.Name}}!" {
+		t.Errorf("expected version 1 with the original subject, got %d %q", resp.Versions[1].Version, resp.Versions[1].Subject)
+	}
+}
+
+func TestGetTemplateVersions_WrongTenant(t *testing.T) {
+	uc, _, fixtures := createTemplateTestUseCase()
+	ctx := context.Background()
+
+	templateID := createVersionedTemplate(t, uc, fixtures)
+
+	_, err := uc.GetTemplateVersions(ctx, &dto.GetTemplateVersionsRequest{
+		TenantID:   uuid.New().String(),
+		TemplateID: templateID,
+	})
+	if err == nil {
+		t.Fatal("expected error, got nil")
+	}
+
+	appErr, ok := err.(*application.AppError)
+	if !ok {
+		t.Fatalf("expected AppError, got %T", err)
+	}
+
+	if appErr.Code != application.ErrCodeForbidden {
+		t.Errorf("expected error code %s, got %s", application.ErrCodeForbidden, appErr.Code)
+	}
+}
+
+func TestRevertTemplateVersion_Success(t *testing.T) {
+	uc, repo, fixtures := createTemplateTestUseCase()
+	ctx := context.Background()
+
+	templateID := createVersionedTemplate(t, uc, fixtures)
+
+	resp, err := uc.RevertTemplateVersion(ctx, &dto.RevertTemplateVersionRequest{
+		TenantID:   fixtures.tenantID.String(),
+		TemplateID: templateID,
+		Version:    1,
+		RevertedBy: fixtures.userID.String(),
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+
+	if resp.Version != 3 || resp.RevertedTo != 1 {
+		t.Errorf("expected version 3 reverted to 1, got %d reverted to %d", resp.Version, resp.RevertedTo)
+	}
+
+	template := repo.templates[uuid.MustParse(templateID)]
+	if template.EmailTemplate.Subject != "Welcome {{.Name}}!" {
+		t.Errorf("expected the original subject to be restored, got %q", template.EmailTemplate.Subject)
+	}
+
+	versions, err := uc.GetTemplateVersions(ctx, &dto.GetTemplateVersionsRequest{
+		TenantID:   fixtures.tenantID.String(),
+		TemplateID: templateID,
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+
+	if versions.TotalCount != 3 {
+		t.Errorf("expected the revert to be recorded as a new version, got %d versions", versions.TotalCount)
+	}
+}
+
+func TestRevertTemplateVersion_NotFound(t *testing.T) {
+	uc, _, fixtures := createTemplateTestUseCase()
+	ctx := context.Background()
+
+	templateID := createVersionedTemplate(t, uc, fixtures)
+
+	_, err := uc.RevertTemplateVersion(ctx, &dto.RevertTemplateVersionRequest{
+		TenantID:   fixtures.tenantID.String(),
+		TemplateID: templateID,
+		Version:    9,
+	})
+	if err == nil {
+		t.Fatal("expected error, got nil")
+	}
+
+	appErr, ok := err.(*application.AppError)
+	if !ok {
+		t.Fatalf("expected AppError, got %T", err)
+	}
+
+	if appErr.Code != application.ErrCodeNotFound {
+		t.Errorf("expected error code %s, got %s", application.ErrCodeNotFound, appErr.Code)
+	}
+}
+
+func TestDiffTemplateVersions(t *testing.T) {
+	uc, _, fixtures := createTemplateTestUseCase()
+	ctx := context.Background()
+
+	templateID := createVersionedTemplate(t, uc, fixtures)
+
+	resp, err := uc.DiffTemplateVersions(ctx, &dto.DiffTemplateVersionsRequest{
+		TenantID:    fixtures.tenantID.String(),
+		TemplateID:  templateID,
+		FromVersion: 1,
+		ToVersion:   2,
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+
+	if !resp.HasChanges || len(resp.Changes) != 2 {
+		t.Fatalf("expected subject and body changes, got %+v", resp.Changes)
+	}
+
+	if resp.Changes[0].Field != "email.subject" || resp.Changes[1].Field != "email.body" {
+		t.Errorf("expected email.subject and email.body, got %s and %s", resp.Changes[0].Field, resp.Changes[1].Field)
+	}
+
+	body := resp.Changes[1].Lines
+	if len(body) != 2 || body[0].Op != "equal" || body[1].Op != "insert" || body[1].Text != "Your account is ready." {
+		t.Errorf("expected one equal and one inserted body line, got %+v", body)
+	}
+
+	same, err := uc.DiffTemplateVersions(ctx, &dto.DiffTemplateVersionsRequest{
+		TenantID:    fixtures.tenantID.String(),
+		TemplateID:  templateID,
+		FromVersion: 2,
+		ToVersion:   2,
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+
+	if same.HasChanges {
+		t.Errorf("expected no changes between a version and itself, got %+v", same.Changes)
+	}
+}
+
+// =============================================================================
+// PreviewTemplate Tests
+// =============================================================================
+
+func TestPreviewTemplate_UsesSampleVariables(t *testing.T) {
+	uc, repo, fixtures := createTemplateTestUseCase()
+	ctx := context.Background()
+
+	template, _ := createTestTemplate(fixtures.tenantID, "preview_template", "Preview Template", domain.ChannelEmail)
+	template.AddVariable(domain.TemplateVariable{Name: "Name", Type: "string", Required: true, Example: "Aisyah"})
+	repo.templates[template.ID] = template
+
+	resp, err := uc.PreviewTemplate(ctx, &dto.PreviewTemplateRequest{
+		TenantID:   fixtures.tenantID.String(),
+		TemplateID: template.ID.String(),
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+
+	if resp.Channel != "email" {
+		t.Errorf("expected channel email, got %s", resp.Channel)
+	}
+	if resp.Subject != "Test Subject Aisyah" {
+		t.Errorf("expected subject rendered with the example, got %q", resp.Subject)
+	}
+	if resp.HTMLBody != "<p>Hello Aisyah, this is a test.</p>" {
+		t.Errorf("expected rendered HTML body, got %q", resp.HTMLBody)
+	}
+
+	resp, err = uc.PreviewTemplate(ctx, &dto.PreviewTemplateRequest{
+		TenantID:   fixtures.tenantID.String(),
+		TemplateID: template.ID.String(),
+		Variables:  map[string]interface{}{"Name": "Farid"},
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+
+	if resp.Subject != "Test Subject Farid" {
+		t.Errorf("expected subject rendered with the provided value, got %q", resp.Subject)
+	}
+}
+
+func TestPreviewTemplate_InvalidVariables(t *testing.T) {
+	uc, repo, fixtures := createTemplateTestUseCase()
+	ctx := context.Background()
+
+	template, _ := createTestTemplate(fixtures.tenantID, "preview_template", "Preview Template", domain.ChannelEmail)
+	template.AddVariable(domain.TemplateVariable{Name: "Name", Type: "string", Required: true})
+	repo.templates[template.ID] = template
+
+	_, err := uc.PreviewTemplate(ctx, &dto.PreviewTemplateRequest{
+		TenantID:   fixtures.tenantID.String(),
+		TemplateID: template.ID.String(),
+		Variables:  map[string]interface{}{"Name": 42},
+	})
+	if err == nil {
+		t.Fatal("expected error, got nil")
+	}
+
+	appErr, ok := err.(*application.AppError)
+	if !ok {
+		t.Fatalf("expected AppError, got %T", err)
+	}
+
+	if appErr.Code != application.ErrCodeValidation {
+		t.Errorf("expected error code %s, got %s", application.ErrCodeValidation, appErr.Code)
+	}
+
+	invalid, _ := appErr.Details["invalid_variables"].([]string)
+	if len(invalid) != 1 || invalid[0] != "Name" {
+		t.Errorf("expected Name to be reported as invalid, got %v", appErr.Details)
+	}
+}
+
+func TestPreviewTemplate_UnsupportedChannel(t *testing.T) {
+	uc, repo, fixtures := createTemplateTestUseCase()
+	ctx := context.Background()
+
+	template, _ := createTestTemplate(fixtures.tenantID, "preview_template", "Preview Template", domain.ChannelEmail)
+	repo.templates[template.ID] = template
+
+	_, err := uc.PreviewTemplate(ctx, &dto.PreviewTemplateRequest{
+		TenantID:   fixtures.tenantID.String(),
+		TemplateID: template.ID.String(),
+		Channel:    "sms",
+	})
+	if err == nil {
+		t.Fatal("expected error, got nil")
+	}
+}
+
+// =============================================================================
+// TestSendTemplate Tests
+// =============================================================================
+
+func TestTestSendTemplate_Success(t *testing.T) {
+	uc, repo, emailProvider, fixtures := createTemplateTestSendUseCase()
+	ctx := context.Background()
+
+	template, _ := createTestTemplate(fixtures.tenantID, "test_send_template", "Test Send Template", domain.ChannelEmail)
+	repo.templates[template.ID] = template
+
+	resp, err := uc.TestSendTemplate(ctx, &dto.TestSendTemplateRequest{
+		TenantID:   fixtures.tenantID.String(),
+		TemplateID: template.ID.String(),
+		Variables:  map[string]interface{}{"Name": "Aisyah"},
+		Recipient:  "QA@example.com",
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+
+	if resp.Status != "sent" || resp.Channel != "email" {
+		t.Errorf("expected a sent email, got %s via %s", resp.Status, resp.Channel)
+	}
+
+	sent := emailProvider.GetSentEmails()
+	if len(sent) != 1 {
+		t.Fatalf("expected 1 email sent, got %d", len(sent))
+	}
+	if sent[0].Subject != "[TEST] Test Subject Aisyah" {
+		t.Errorf("expected a marked test subject, got %q", sent[0].Subject)
+	}
+	if sent[0].Tags["test_send"] != "true" {
+		t.Errorf("expected the email to be tagged as a test send, got %v", sent[0].Tags)
+	}
+
+	if template.UsageCount != 0 {
+		t.Errorf("expected test sends not to record usage, got %d", template.UsageCount)
+	}
+}
+
+func TestTestSendTemplate_RecipientNotAllowed(t *testing.T) {
+	uc, repo, emailProvider, fixtures := createTemplateTestSendUseCase()
+	ctx := context.Background()
+
+	template, _ := createTestTemplate(fixtures.tenantID, "test_send_template", "Test Send Template", domain.ChannelEmail)
+	repo.templates[template.ID] = template
+
+	_, err := uc.TestSendTemplate(ctx, &dto.TestSendTemplateRequest{
+		TenantID:   fixtures.tenantID.String(),
+		TemplateID: template.ID.String(),
+		Variables:  map[string]interface{}{"Name": "Aisyah"},
+		Recipient:  "customer@example.com",
+	})
+	if err == nil {
+		t.Fatal("expected error, got nil")
+	}
+
+	appErr, ok := err.(*application.AppError)
+	if !ok {
+		t.Fatalf("expected AppError, got %T", err)
+	}
+
+	if appErr.Code != application.ErrCodeForbidden {
+		t.Errorf("expected error code %s, got %s", application.ErrCodeForbidden, appErr.Code)
+	}
+
+	if len(emailProvider.GetSentEmails()) != 0 {
+		t.Error("expected no email to be sent")
+	}
+}
+
+// =============================================================================
+// Template Localization Tests
+// =============================================================================
+
+func TestSetTemplateLocalization_Success(t *testing.T) {
+	uc, repo, fixtures := createTemplateTestUseCase()
+	ctx := context.Background()
+
+	template, _ := createTestTemplate(fixtures.tenantID, "localized_template", "Localized Template", domain.ChannelEmail)
+	repo.templates[template.ID] = template
+
+	resp, err := uc.SetTemplateLocalization(ctx, &dto.SetTemplateLocalizationRequest{
+		TenantID:   fixtures.tenantID.String(),
+		TemplateID: template.ID.String(),
+		Locale:     "ms_my",
+		Subject:    "Subjek Ujian {{.Name}}",
+		Body:       "Helo {{.Name}}",
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+
+	if resp.Locale != "ms-MY" {
+		t.Errorf("expected normalized locale ms-MY, got %s", resp.Locale)
+	}
+
+	list, err := uc.ListTemplateLocalizations(ctx, &dto.ListTemplateLocalizationsRequest{
+		TenantID:   fixtures.tenantID.String(),
+		TemplateID: template.ID.String(),
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if list.DefaultLocale != "en" || len(list.Localizations) != 1 || list.Localizations[0].Subject != "Subjek Ujian {{.Name}}" {
+		t.Errorf("expected the ms-MY variant next to the en default, got %+v", list)
+	}
+
+	// A Malaysian recipient gets the variant
+	preview, err := uc.PreviewTemplate(ctx, &dto.PreviewTemplateRequest{
+		TenantID:   fixtures.tenantID.String(),
+		TemplateID: template.ID.String(),
+		Locale:     "ms-MY",
+		Variables:  map[string]interface{}{"Name": "Aisyah"},
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if preview.Subject != "Subjek Ujian Aisyah" {
+		t.Errorf("expected the localized subject, got %q", preview.Subject)
+	}
+}
+
+func TestSetTemplateLocalization_InvalidLocale(t *testing.T) {
+	uc, repo, fixtures := createTemplateTestUseCase()
+	ctx := context.Background()
+
+	template, _ := createTestTemplate(fixtures.tenantID, "localized_template", "Localized Template", domain.ChannelSMS)
+	repo.templates[template.ID] = template
+
+	for _, locale := range []string{"malay", "en"} {
+		_, err := uc.SetTemplateLocalization(ctx, &dto.SetTemplateLocalizationRequest{
+			TenantID:   fixtures.tenantID.String(),
+			TemplateID: template.ID.String(),
+			Locale:     locale,
+			Body:       "Helo {{.Name}}",
+		})
+		if err == nil {
+			t.Fatalf("expected error for locale %q, got nil", locale)
+		}
+
+		appErr, ok := err.(*application.AppError)
+		if !ok {
+			t.Fatalf("expected AppError, got %T", err)
+		}
+
+		if appErr.Code != application.ErrCodeInvalidInput {
+			t.Errorf("expected error code %s, got %s", application.ErrCodeInvalidInput, appErr.Code)
+		}
+	}
+}
+
+func TestSeedSystemTemplates(t *testing.T) {
+	uc, repo, fixtures := createTemplateTestUseCase()
+	ctx := context.Background()
+
+	// The tenant already has its own welcome email
+	welcome, _ := createTestTemplate(fixtures.tenantID, domain.TemplateCodeWelcomeEmail, "Our Welcome", domain.ChannelEmail)
+	repo.templates[welcome.ID] = welcome
+	repo.templateByCode[welcome.Code] = welcome
+
+	req := &dto.SeedSystemTemplatesRequest{TenantID: fixtures.tenantID.String()}
+	resp, err := uc.SeedSystemTemplates(ctx, req)
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if len(resp.Created) != 1 || resp.Created[0] != domain.TemplateCodePasswordReset {
+		t.Errorf("expected the password reset template created, got %v", resp.Created)
+	}
+	if len(resp.Existing) != 1 || resp.Existing[0] != domain.TemplateCodeWelcomeEmail {
+		t.Errorf("expected the tenant's welcome template kept, got %v", resp.Existing)
+	}
+	if repo.templateByCode[domain.TemplateCodeWelcomeEmail] != welcome {
+		t.Error("expected the tenant's welcome template not to be replaced")
+	}
+
+	// Malaysian users get the Malay password reset email
+	reset := repo.templateByCode[domain.TemplateCodePasswordReset]
+	preview, err := uc.PreviewTemplate(ctx, &dto.PreviewTemplateRequest{
+		TenantID:   fixtures.tenantID.String(),
+		TemplateID: reset.ID.String(),
+		Locale:     "ms-MY",
+		Variables:  map[string]interface{}{"first_name": "Aisyah", "reset_url": "https://crm.example.com/reset?token=t"},
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if preview.Subject != "Tetapkan semula kata laluan Kilang Desa Murni Batik anda" {
+		t.Errorf("expected the Malay subject, got %q", preview.Subject)
+	}
+
+	// Seeding again creates nothing
+	resp, err = uc.SeedSystemTemplates(ctx, req)
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if len(resp.Created) != 0 || len(resp.Existing) != 2 {
+		t.Errorf("expected every system template to exist, got %+v", resp)
+	}
+}
+
+func TestDeleteTemplateLocalization(t *testing.T) {
+	uc, repo, fixtures := createTemplateTestUseCase()
+	ctx := context.Background()
+
+	template, _ := createTestTemplate(fixtures.tenantID, "localized_template", "Localized Template", domain.ChannelSMS)
+	template.AddLocalization("ms", &domain.TemplateLocalization{
+		SMSTemplate: &domain.SMSTemplateContent{Body: "Helo {{.Name}}"},
+	})
+	repo.templates[template.ID] = template
+
+	req := &dto.DeleteTemplateLocalizationRequest{
+		TenantID:   fixtures.tenantID.String(),
+		TemplateID: template.ID.String(),
+		Locale:     "ms",
+	}
+	if _, err := uc.DeleteTemplateLocalization(ctx, req); err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if template.GetLocalization("ms") != nil {
+		t.Error("expected the ms variant to be removed")
+	}
+
+	_, err := uc.DeleteTemplateLocalization(ctx, req)
+	if err == nil {
+		t.Fatal("expected error, got nil")
+	}
+
+	appErr, ok := err.(*application.AppError)
+	if !ok {
+		t.Fatalf("expected AppError, got %T", err)
+	}
+
+	if appErr.Code != application.ErrCodeNotFound {
+		t.Errorf("expected error code %s, got %s", application.ErrCodeNotFound, appErr.Code)
+	}
+}
+
 // =============================================================================
 // Table-Driven Validation Tests
 // =============================================================================
@@ -0,0 +1,59 @@
+package domain
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// BodyRetention is how long the bodies of delivered notifications are kept,
+// per tenant. Once it passes the body is cleared and only the delivery
+// record remains. Zero days keeps bodies for as long as the notification.
+type BodyRetention struct {
+	DefaultDays int               `json:"default_days"`
+	TenantDays  map[uuid.UUID]int `json:"tenant_days,omitempty"`
+}
+
+// Validate checks that no retention is negative.
+func (r BodyRetention) Validate() error {
+	if r.DefaultDays < 0 {
+		return NewValidationError("default_days", "body retention cannot be negative", "INVALID_RETENTION")
+	}
+	for tenantID, days := range r.TenantDays {
+		if days < 0 {
+			return NewValidationError("tenant_days", fmt.Sprintf("body retention of tenant %s cannot be negative", tenantID), "INVALID_RETENTION")
+		}
+	}
+	return nil
+}
+
+// PurgeFilters returns the body purges due at now: one for each tenant with
+// a retention of its own, and one for all other tenants under the default.
+// Retentions of zero days are skipped.
+func (r BodyRetention) PurgeFilters(now time.Time, limit int) []BodyPurgeFilter {
+	filters := make([]BodyPurgeFilter, 0, len(r.TenantDays)+1)
+	overridden := make([]uuid.UUID, 0, len(r.TenantDays))
+
+	for tenantID, days := range r.TenantDays {
+		overridden = append(overridden, tenantID)
+		if days <= 0 {
+			continue
+		}
+		tenantID := tenantID
+		filters = append(filters, BodyPurgeFilter{
+			TenantID: &tenantID,
+			Before:   now.AddDate(0, 0, -days),
+			Limit:    limit,
+		})
+	}
+
+	if r.DefaultDays > 0 {
+		filters = append(filters, BodyPurgeFilter{
+			ExcludeTenantIDs: overridden,
+			Before:           now.AddDate(0, 0, -r.DefaultDays),
+			Limit:            limit,
+		})
+	}
+	return filters
+}
@@ -0,0 +1,50 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestBodyRetention_PurgeFilters(t *testing.T) {
+	now := time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC)
+	strict, keeper := uuid.New(), uuid.New()
+
+	retention := BodyRetention{
+		DefaultDays: 90,
+		TenantDays:  map[uuid.UUID]int{strict: 30, keeper: 0},
+	}
+	if err := retention.Validate(); err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+
+	filters := retention.PurgeFilters(now, 500)
+	if len(filters) != 2 {
+		t.Fatalf("expected a tenant purge and a default purge, got %+v", filters)
+	}
+
+	for _, filter := range filters {
+		if filter.Limit != 500 {
+			t.Errorf("expected limit 500, got %d", filter.Limit)
+		}
+		if filter.TenantID != nil {
+			if *filter.TenantID != strict || !filter.Before.Equal(now.AddDate(0, 0, -30)) {
+				t.Errorf("unexpected tenant purge %+v", filter)
+			}
+			continue
+		}
+		if !filter.Before.Equal(now.AddDate(0, 0, -90)) || len(filter.ExcludeTenantIDs) != 2 {
+			t.Errorf("expected the default purge to skip both overridden tenants, got %+v", filter)
+		}
+	}
+}
+
+func TestBodyRetention_KeepsByDefault(t *testing.T) {
+	if filters := (BodyRetention{}).PurgeFilters(time.Now(), 100); len(filters) != 0 {
+		t.Errorf("expected no purges without a retention, got %+v", filters)
+	}
+	if err := (BodyRetention{DefaultDays: -1}).Validate(); err == nil {
+		t.Error("expected a negative retention to be rejected")
+	}
+}
@@ -0,0 +1,348 @@
+package domain
+
+import (
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/kilang-desa-murni/crm/pkg/render"
+)
+
+// ChatPlatform is a team chat service integrations post to.
+type ChatPlatform string
+
+const (
+	ChatPlatformSlack ChatPlatform = "slack"
+	ChatPlatformTeams ChatPlatform = "teams"
+)
+
+// chatWebhookHosts are the hosts incoming webhooks of each platform are
+// served from, so integrations cannot post tenant data anywhere else.
+var chatWebhookHosts = map[ChatPlatform][]string{
+	ChatPlatformSlack: {"hooks.slack.com"},
+	// Office 365 connectors and Teams workflows
+	ChatPlatformTeams: {".webhook.office.com", ".logic.azure.com", ".api.powerplatform.com"},
+}
+
+// ChatTrigger is a CRM event integrations post about.
+type ChatTrigger string
+
+const (
+	// ChatTriggerOpportunityWon celebrates a won opportunity.
+	ChatTriggerOpportunityWon ChatTrigger = "opportunity_won"
+	// ChatTriggerBigLead announces a lead whose estimated value reaches the
+	// integration's threshold.
+	ChatTriggerBigLead ChatTrigger = "big_lead"
+	// ChatTriggerInvoiceOverdue alerts that an invoice passed its due date
+	// unpaid.
+	ChatTriggerInvoiceOverdue ChatTrigger = "invoice_overdue"
+	// ChatTriggerBiggestDealWon celebrates the biggest deal won so far in
+	// the quarter.
+	ChatTriggerBiggestDealWon ChatTrigger = "biggest_deal_won"
+	// ChatTriggerLowPipelineCoverage alerts that the open pipeline covers
+	// too little of the quota left in the quarter.
+	ChatTriggerLowPipelineCoverage ChatTrigger = "low_pipeline_coverage"
+	// ChatTriggerLeadVolumeDrop alerts that a week brought in markedly fewer
+	// leads than the week before.
+	ChatTriggerLeadVolumeDrop ChatTrigger = "lead_volume_drop"
+	// ChatTriggerTest is the message posted when an integration is tested.
+	ChatTriggerTest ChatTrigger = "test"
+)
+
+// ValidChatTriggers are the triggers integrations subscribe to.
+var ValidChatTriggers = map[ChatTrigger]bool{
+	ChatTriggerOpportunityWon:      true,
+	ChatTriggerBigLead:             true,
+	ChatTriggerInvoiceOverdue:      true,
+	ChatTriggerBiggestDealWon:      true,
+	ChatTriggerLowPipelineCoverage: true,
+	ChatTriggerLeadVolumeDrop:      true,
+}
+
+// ChatTemplate is the text of a chat message: Go templates rendered with the
+// variables of the event, with the helpers of the render package.
+type ChatTemplate struct {
+	Title string `json:"title"`
+	Text  string `json:"text"`
+}
+
+// DefaultChatTemplates are used for the triggers an integration has no
+// template of its own for. Amounts are in major currency units.
+var DefaultChatTemplates = map[ChatTrigger]ChatTemplate{
+	ChatTriggerOpportunityWon: {
+		Title: "Deal won: {{.opportunity_name | default .opportunity_code}}",
+		Text:  "{{currencyIn .currency .amount}} closed{{if .won_reason}}: {{.won_reason}}{{end}}",
+	},
+	ChatTriggerBigLead: {
+		Title: "Big lead: {{.company_name | default .contact_name}}",
+		Text:  "{{.contact_name}} came in from {{.source}}, worth an estimated {{currencyIn .currency .amount}}",
+	},
+	ChatTriggerInvoiceOverdue: {
+		Title: "Invoice {{.invoice_number}} is overdue",
+		Text:  "{{currencyIn .currency .amount}} from {{.customer_name | default \"the customer\"}} was due on {{date .due_date}}",
+	},
+	ChatTriggerBiggestDealWon: {
+		Title: "Biggest deal of the quarter: {{.opportunity_name | default .opportunity_code}}",
+		Text:  "{{currencyIn .currency .amount}} won by {{.owner_name | default \"the team\"}}{{if .previous_best}}, beating {{currencyIn .currency .previous_best}}{{end}}",
+	},
+	ChatTriggerLowPipelineCoverage: {
+		Title: "Pipeline coverage is down to {{number 1 .coverage}}x",
+		Text:  "{{currencyIn .currency .open_pipeline}} of open pipeline covers the {{currencyIn .currency .remaining_quota}} left of this quarter's quota, below the {{number 1 .minimum_coverage}}x target",
+	},
+	ChatTriggerLeadVolumeDrop: {
+		Title: "Lead volume fell {{number 0 .drop_percent}}% week over week",
+		Text:  "{{.leads}} new leads in the week of {{date .period_start}}, down from {{.previous_leads}} the week before",
+	},
+	ChatTriggerTest: {
+		Title: "Integration connected",
+		Text:  "{{.integration_name}} will post CRM alerts to this channel",
+	},
+}
+
+// ChatIntegration posts CRM events of a tenant to a Slack or Microsoft Teams
+// channel through an incoming webhook.
+type ChatIntegration struct {
+	ID       uuid.UUID    `json:"id"`
+	TenantID uuid.UUID    `json:"tenant_id"`
+	Name     string       `json:"name"`
+	Platform ChatPlatform `json:"platform"`
+	// WebhookURL carries the credentials of the webhook and is never
+	// returned by the API.
+	WebhookURL string        `json:"-"`
+	Triggers   []ChatTrigger `json:"triggers"`
+	// BigLeadThreshold is the estimated value, in major currency units, a
+	// lead needs to be posted as a big lead.
+	BigLeadThreshold float64                      `json:"big_lead_threshold"`
+	Templates        map[ChatTrigger]ChatTemplate `json:"templates,omitempty"`
+	Enabled          bool                         `json:"enabled"`
+	LastPostedAt     *time.Time                   `json:"last_posted_at,omitempty"`
+	LastError        string                       `json:"last_error,omitempty"`
+	CreatedBy        *uuid.UUID                   `json:"created_by,omitempty"`
+	CreatedAt        time.Time                    `json:"created_at"`
+	UpdatedAt        time.Time                    `json:"updated_at"`
+}
+
+// NewChatIntegration creates an enabled integration.
+func NewChatIntegration(tenantID uuid.UUID, name string, platform ChatPlatform, webhookURL string, triggers []ChatTrigger, createdBy *uuid.UUID) (*ChatIntegration, error) {
+	if tenantID == uuid.Nil {
+		return nil, ErrTenantIDRequired
+	}
+	now := time.Now().UTC()
+	integration := &ChatIntegration{
+		ID:        uuid.New(),
+		TenantID:  tenantID,
+		Platform:  platform,
+		Templates: make(map[ChatTrigger]ChatTemplate),
+		Enabled:   true,
+		CreatedBy: createdBy,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if err := integration.Rename(name); err != nil {
+		return nil, err
+	}
+	if err := integration.SetWebhookURL(webhookURL); err != nil {
+		return nil, err
+	}
+	if err := integration.SetTriggers(triggers); err != nil {
+		return nil, err
+	}
+	return integration, nil
+}
+
+// Rename sets the name of the integration.
+func (c *ChatIntegration) Rename(name string) error {
+	name = strings.TrimSpace(name)
+	if name == "" || len(name) > 100 {
+		return NewValidationError("name", "name is required and must be at most 100 characters", "INVALID_NAME")
+	}
+	c.Name = name
+	c.UpdatedAt = time.Now().UTC()
+	return nil
+}
+
+// SetWebhookURL sets the incoming webhook messages are posted to, which must
+// be served by the integration's platform.
+func (c *ChatIntegration) SetWebhookURL(webhookURL string) error {
+	hosts, ok := chatWebhookHosts[c.Platform]
+	if !ok {
+		return NewValidationError("platform", "platform must be slack or teams", "INVALID_CHAT_PLATFORM")
+	}
+	parsed, err := url.Parse(strings.TrimSpace(webhookURL))
+	if err != nil || parsed.Scheme != "https" || parsed.Host == "" || parsed.User != nil {
+		return NewValidationError("webhook_url", "webhook URL must be an https URL", "INVALID_WEBHOOK_URL")
+	}
+	host := strings.ToLower(parsed.Hostname())
+	for _, allowed := range hosts {
+		if host == allowed || (strings.HasPrefix(allowed, ".") && strings.HasSuffix(host, allowed)) {
+			c.WebhookURL = parsed.String()
+			c.UpdatedAt = time.Now().UTC()
+			return nil
+		}
+	}
+	return NewValidationError("webhook_url", "webhook URL is not an incoming webhook of "+string(c.Platform), "INVALID_WEBHOOK_URL")
+}
+
+// SetTriggers sets the events the integration posts about.
+func (c *ChatIntegration) SetTriggers(triggers []ChatTrigger) error {
+	if len(triggers) == 0 {
+		return NewValidationError("triggers", "at least one trigger is required", "INVALID_TRIGGERS")
+	}
+	seen := make(map[ChatTrigger]bool, len(triggers))
+	unique := make([]ChatTrigger, 0, len(triggers))
+	for _, trigger := range triggers {
+		if !ValidChatTriggers[trigger] {
+			return NewValidationError("triggers", "unknown trigger "+string(trigger), "INVALID_TRIGGERS")
+		}
+		if !seen[trigger] {
+			seen[trigger] = true
+			unique = append(unique, trigger)
+		}
+	}
+	c.Triggers = unique
+	c.UpdatedAt = time.Now().UTC()
+	return nil
+}
+
+// SetBigLeadThreshold sets the estimated value big leads reach.
+func (c *ChatIntegration) SetBigLeadThreshold(threshold float64) error {
+	if threshold < 0 {
+		return NewValidationError("big_lead_threshold", "threshold cannot be negative", "INVALID_THRESHOLD")
+	}
+	c.BigLeadThreshold = threshold
+	c.UpdatedAt = time.Now().UTC()
+	return nil
+}
+
+// SetTemplate overrides the message of a trigger; an empty template
+// restores the default.
+func (c *ChatIntegration) SetTemplate(trigger ChatTrigger, template ChatTemplate) error {
+	if !ValidChatTriggers[trigger] && trigger != ChatTriggerTest {
+		return NewValidationError("templates", "unknown trigger "+string(trigger), "INVALID_TRIGGERS")
+	}
+	if template.Title == "" && template.Text == "" {
+		delete(c.Templates, trigger)
+		c.UpdatedAt = time.Now().UTC()
+		return nil
+	}
+	for _, src := range []string{template.Title, template.Text} {
+		if err := render.Validate(src); err != nil {
+			return NewValidationError("templates", "invalid template for "+string(trigger)+": "+err.Error(), "INVALID_TEMPLATE")
+		}
+	}
+	if c.Templates == nil {
+		c.Templates = make(map[ChatTrigger]ChatTemplate)
+	}
+	c.Templates[trigger] = template
+	c.UpdatedAt = time.Now().UTC()
+	return nil
+}
+
+// Enable resumes posting.
+func (c *ChatIntegration) Enable() {
+	c.Enabled = true
+	c.UpdatedAt = time.Now().UTC()
+}
+
+// Disable stops posting without removing the integration.
+func (c *ChatIntegration) Disable() {
+	c.Enabled = false
+	c.UpdatedAt = time.Now().UTC()
+}
+
+// Subscribes reports whether the integration posts about a trigger.
+func (c *ChatIntegration) Subscribes(trigger ChatTrigger) bool {
+	for _, t := range c.Triggers {
+		if t == trigger {
+			return true
+		}
+	}
+	return false
+}
+
+// Matches reports whether the integration posts an event.
+func (c *ChatIntegration) Matches(event *ChatEvent) bool {
+	if !c.Enabled || event.TenantID != c.TenantID || !c.Subscribes(event.Trigger) {
+		return false
+	}
+	if event.Trigger == ChatTriggerBigLead {
+		return event.Amount > 0 && event.Amount >= c.BigLeadThreshold
+	}
+	return true
+}
+
+// Template returns the message template of a trigger. A custom template
+// that only sets the title or the text keeps the default of the other.
+func (c *ChatIntegration) Template(trigger ChatTrigger) ChatTemplate {
+	template := DefaultChatTemplates[trigger]
+	if custom, ok := c.Templates[trigger]; ok {
+		if custom.Title != "" {
+			template.Title = custom.Title
+		}
+		if custom.Text != "" {
+			template.Text = custom.Text
+		}
+	}
+	return template
+}
+
+// Render renders the message the integration posts for an event.
+func (c *ChatIntegration) Render(event *ChatEvent) (*ChatMessage, error) {
+	template := c.Template(event.Trigger)
+	vars := make(map[string]interface{}, len(event.Vars)+3)
+	for k, v := range event.Vars {
+		vars[k] = v
+	}
+	vars["integration_name"] = c.Name
+	if _, ok := vars["amount"]; !ok {
+		vars["amount"] = event.Amount
+	}
+	if _, ok := vars["currency"]; !ok {
+		vars["currency"] = event.Currency
+	}
+
+	title, err := render.Text(template.Title, vars)
+	if err != nil {
+		return nil, NewValidationError("templates", "failed to render title: "+err.Error(), "TEMPLATE_RENDER_FAILED")
+	}
+	text, err := render.Text(template.Text, vars)
+	if err != nil {
+		return nil, NewValidationError("templates", "failed to render text: "+err.Error(), "TEMPLATE_RENDER_FAILED")
+	}
+	return &ChatMessage{
+		Trigger: event.Trigger,
+		Title:   strings.TrimSpace(title),
+		Text:    strings.TrimSpace(text),
+		Fields:  event.Fields,
+		URL:     event.URL,
+	}, nil
+}
+
+// RecordPost records the outcome of posting to the webhook.
+func (c *ChatIntegration) RecordPost(postErr error, now time.Time) {
+	posted := now.UTC()
+	c.LastPostedAt = &posted
+	c.LastError = ""
+	if postErr != nil {
+		c.LastError = postErr.Error()
+	}
+	c.UpdatedAt = posted
+}
+
+// ChatEvent is a CRM event posted to the chat integrations of a tenant.
+type ChatEvent struct {
+	TenantID uuid.UUID
+	Trigger  ChatTrigger
+	// Amount is the value of the event in major currency units, compared
+	// with big lead thresholds.
+	Amount   float64
+	Currency string
+	// Vars are the template variables of the event.
+	Vars map[string]interface{}
+	// Fields are the facts shown with the message.
+	Fields []ChatField
+	// URL links the message to the record in the CRM.
+	URL string
+}
@@ -0,0 +1,138 @@
+package domain
+
+// ChatMessage is a rendered message for a team chat channel.
+type ChatMessage struct {
+	Trigger ChatTrigger `json:"trigger"`
+	Title   string      `json:"title"`
+	Text    string      `json:"text"`
+	Fields  []ChatField `json:"fields,omitempty"`
+	URL     string      `json:"url,omitempty"`
+}
+
+// ChatField is a fact shown with a chat message, such as the amount of a
+// deal.
+type ChatField struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// chatAccents are the colors of the triggers: good news green, alerts red.
+var chatAccents = map[ChatTrigger]string{
+	ChatTriggerOpportunityWon:      "good",
+	ChatTriggerBigLead:             "accent",
+	ChatTriggerInvoiceOverdue:      "attention",
+	ChatTriggerBiggestDealWon:      "good",
+	ChatTriggerLowPipelineCoverage: "attention",
+	ChatTriggerLeadVolumeDrop:      "attention",
+}
+
+// FallbackText is the plain text of the message, shown in notifications
+// and by clients that cannot display blocks or cards.
+func (m *ChatMessage) FallbackText() string {
+	if m.Text == "" {
+		return m.Title
+	}
+	return m.Title + ": " + m.Text
+}
+
+// SlackBlocks lays the message out in Slack Block Kit blocks: a header, the
+// text, the fields and a button to the record.
+func (m *ChatMessage) SlackBlocks() []map[string]interface{} {
+	blocks := []map[string]interface{}{
+		{
+			"type": "header",
+			"text": map[string]interface{}{"type": "plain_text", "text": truncateRunes(m.Title, 150), "emoji": true},
+		},
+	}
+	if m.Text != "" {
+		blocks = append(blocks, map[string]interface{}{
+			"type": "section",
+			"text": map[string]interface{}{"type": "mrkdwn", "text": truncateRunes(escapeSlack(m.Text), 3000)},
+		})
+	}
+	if len(m.Fields) > 0 {
+		// Slack shows at most 10 fields in a section
+		fields := make([]map[string]interface{}, 0, len(m.Fields))
+		for i, field := range m.Fields {
+			if i == 10 {
+				break
+			}
+			fields = append(fields, map[string]interface{}{
+				"type": "mrkdwn",
+				"text": "*" + escapeSlack(field.Name) + "*\n" + escapeSlack(field.Value),
+			})
+		}
+		blocks = append(blocks, map[string]interface{}{"type": "section", "fields": fields})
+	}
+	if m.URL != "" {
+		blocks = append(blocks, map[string]interface{}{
+			"type": "actions",
+			"elements": []map[string]interface{}{{
+				"type": "button",
+				"text": map[string]interface{}{"type": "plain_text", "text": "Open in CRM"},
+				"url":  m.URL,
+			}},
+		})
+	}
+	return blocks
+}
+
+// TeamsCard lays the message out in a Microsoft Teams Adaptive Card.
+func (m *ChatMessage) TeamsCard() map[string]interface{} {
+	color, ok := chatAccents[m.Trigger]
+	if !ok {
+		color = "default"
+	}
+	body := []map[string]interface{}{
+		{"type": "TextBlock", "text": m.Title, "size": "Large", "weight": "Bolder", "color": color, "wrap": true},
+	}
+	if m.Text != "" {
+		body = append(body, map[string]interface{}{"type": "TextBlock", "text": m.Text, "wrap": true})
+	}
+	if len(m.Fields) > 0 {
+		facts := make([]map[string]interface{}, len(m.Fields))
+		for i, field := range m.Fields {
+			facts[i] = map[string]interface{}{"title": field.Name, "value": field.Value}
+		}
+		body = append(body, map[string]interface{}{"type": "FactSet", "facts": facts})
+	}
+
+	card := map[string]interface{}{
+		"$schema": "http://adaptivecards.io/schemas/adaptive-card.json",
+		"type":    "AdaptiveCard",
+		"version": "1.4",
+		"body":    body,
+	}
+	if m.URL != "" {
+		card["actions"] = []map[string]interface{}{{"type": "Action.OpenUrl", "title": "Open in CRM", "url": m.URL}}
+	}
+	return card
+}
+
+// escapeSlack escapes the characters Slack mrkdwn treats as control
+// sequences.
+func escapeSlack(s string) string {
+	out := make([]rune, 0, len(s))
+	for _, r := range s {
+		switch r {
+		case '&':
+			out = append(out, []rune("&amp;")...)
+		case '<':
+			out = append(out, []rune("&lt;")...)
+		case '>':
+			out = append(out, []rune("&gt;")...)
+		default:
+			out = append(out, r)
+		}
+	}
+	return string(out)
+}
+
+// truncateRunes shortens s to at most n runes.
+func truncateRunes(s string, n int) string {
+	runes := []rune(s)
+	if len(runes) <= n {
+		return s
+	}
+	return string(runes[:n-1]) + "…"
+}
@@ -0,0 +1,136 @@
+package domain
+
+import (
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// DeliveryLogSource identifies what recorded a delivery log entry.
+type DeliveryLogSource string
+
+const (
+	// DeliveryLogSourceAttempt is an attempt to hand the notification to its provider.
+	DeliveryLogSourceAttempt DeliveryLogSource = "attempt"
+	// DeliveryLogSourceWebhook is a status reported later by the provider.
+	DeliveryLogSourceWebhook DeliveryLogSource = "webhook"
+)
+
+// ProviderStatus is a delivery status reported by a provider webhook.
+type ProviderStatus string
+
+const (
+	ProviderStatusDelivered  ProviderStatus = "delivered"
+	ProviderStatusBounced    ProviderStatus = "bounced"
+	ProviderStatusComplained ProviderStatus = "complained"
+	ProviderStatusFailed     ProviderStatus = "failed"
+	ProviderStatusOpened     ProviderStatus = "opened"
+	ProviderStatusClicked    ProviderStatus = "clicked"
+)
+
+// ParseProviderStatus parses a string into a ProviderStatus.
+func ParseProviderStatus(s string) (ProviderStatus, error) {
+	status := ProviderStatus(strings.ToLower(strings.TrimSpace(s)))
+	switch status {
+	case ProviderStatusDelivered, ProviderStatusBounced, ProviderStatusComplained,
+		ProviderStatusFailed, ProviderStatusOpened, ProviderStatusClicked:
+		return status, nil
+	}
+	return "", ErrInvalidProviderStatus
+}
+
+// NewDeliveryAttemptLog records the outcome of handing a notification to its
+// provider. It is called after the notification was marked sent or failed, and
+// latency is how long the provider took to answer.
+func NewDeliveryAttemptLog(n *Notification, latency time.Duration) *DeliveryLog {
+	log := &DeliveryLog{
+		ID:                uuid.New(),
+		NotificationID:    n.ID,
+		TenantID:          n.TenantID,
+		Channel:           n.Channel,
+		Source:            DeliveryLogSourceAttempt,
+		Provider:          n.Provider,
+		ProviderMessageID: n.ProviderMessageID,
+		Status:            n.Status.String(),
+		AttemptNumber:     n.AttemptCount,
+		LatencyMs:         latency.Milliseconds(),
+		CreatedAt:         time.Now().UTC(),
+	}
+	if n.Status == StatusFailed {
+		log.ErrorCode = n.ErrorCode
+		log.ErrorMessage = n.ErrorMessage
+	}
+	return log
+}
+
+// NewProviderStatusLog records a status reported by a provider webhook.
+// occurredAt is when the provider saw the event; a zero value means now.
+func NewProviderStatusLog(n *Notification, status ProviderStatus, reason string, occurredAt time.Time) *DeliveryLog {
+	if occurredAt.IsZero() {
+		occurredAt = time.Now()
+	}
+	return &DeliveryLog{
+		ID:                uuid.New(),
+		NotificationID:    n.ID,
+		TenantID:          n.TenantID,
+		Channel:           n.Channel,
+		Source:            DeliveryLogSourceWebhook,
+		Provider:          n.Provider,
+		ProviderMessageID: n.ProviderMessageID,
+		Status:            string(status),
+		AttemptNumber:     n.AttemptCount,
+		ErrorMessage:      reason,
+		CreatedAt:         occurredAt.UTC(),
+	}
+}
+
+// ApplyProviderStatus updates the notification with a status reported by its
+// provider and returns whether it changed. Providers report events late and
+// out of order, so a status the notification has already moved past is
+// ignored rather than rejected.
+func (n *Notification) ApplyProviderStatus(status ProviderStatus, reason string) (bool, error) {
+	switch status {
+	case ProviderStatusDelivered:
+		if n.Status != StatusSent {
+			return false, nil
+		}
+		return true, n.MarkDelivered()
+
+	case ProviderStatusOpened, ProviderStatusClicked:
+		// An open or click proves delivery even if the delivery report is missing
+		if n.Status == StatusSent {
+			if err := n.MarkDelivered(); err != nil {
+				return false, err
+			}
+		}
+		if status == ProviderStatusOpened {
+			n.RecordOpen()
+		} else {
+			n.RecordClick()
+		}
+		return true, nil
+
+	case ProviderStatusBounced:
+		if n.Status != StatusSent {
+			return false, nil
+		}
+		if n.Channel != ChannelEmail {
+			return true, n.MarkFailed("UNDELIVERABLE", reason, reason)
+		}
+		return true, n.MarkBounced("hard", reason)
+
+	case ProviderStatusComplained:
+		if n.Channel != ChannelEmail || !n.Status.CanTransitionTo(StatusComplained) {
+			return false, nil
+		}
+		return true, n.MarkComplained()
+
+	case ProviderStatusFailed:
+		if n.Status != StatusSending && n.Status != StatusSent {
+			return false, nil
+		}
+		return true, n.MarkFailed("PROVIDER_FAILED", reason, reason)
+	}
+	return false, ErrInvalidProviderStatus
+}
@@ -0,0 +1,104 @@
+package domain
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseProviderStatus(t *testing.T) {
+	status, err := ParseProviderStatus(" Delivered ")
+	if err != nil || status != ProviderStatusDelivered {
+		t.Errorf("ParseProviderStatus() = %q, %v; want %q", status, err, ProviderStatusDelivered)
+	}
+	if _, err := ParseProviderStatus("sent"); err != ErrInvalidProviderStatus {
+		t.Errorf("ParseProviderStatus(sent) error = %v, want %v", err, ErrInvalidProviderStatus)
+	}
+}
+
+func TestNotification_ApplyProviderStatus(t *testing.T) {
+	tests := []struct {
+		name        string
+		channel     NotificationChannel
+		from        NotificationStatus
+		status      ProviderStatus
+		wantChanged bool
+		wantStatus  NotificationStatus
+	}{
+		{"delivered after sent", ChannelEmail, StatusSent, ProviderStatusDelivered, true, StatusDelivered},
+		{"delivered reported twice", ChannelEmail, StatusDelivered, ProviderStatusDelivered, false, StatusDelivered},
+		{"open implies delivery", ChannelEmail, StatusSent, ProviderStatusOpened, true, StatusDelivered},
+		{"email bounce", ChannelEmail, StatusSent, ProviderStatusBounced, true, StatusBounced},
+		{"sms bounce fails", ChannelSMS, StatusSent, ProviderStatusBounced, true, StatusFailed},
+		{"bounce after delivery ignored", ChannelEmail, StatusDelivered, ProviderStatusBounced, false, StatusDelivered},
+		{"complaint after delivery", ChannelEmail, StatusDelivered, ProviderStatusComplained, true, StatusComplained},
+		{"late failure ignored", ChannelSMS, StatusDelivered, ProviderStatusFailed, false, StatusDelivered},
+		{"failure after sent", ChannelSMS, StatusSent, ProviderStatusFailed, true, StatusFailed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			notif := createTestNotification(t, tt.channel)
+			notif.Status = tt.from
+
+			changed, err := notif.ApplyProviderStatus(tt.status, "mailbox full")
+
+			if err != nil {
+				t.Fatalf("ApplyProviderStatus() error = %v", err)
+			}
+			if changed != tt.wantChanged {
+				t.Errorf("changed = %v, want %v", changed, tt.wantChanged)
+			}
+			if notif.Status != tt.wantStatus {
+				t.Errorf("Status = %v, want %v", notif.Status, tt.wantStatus)
+			}
+		})
+	}
+}
+
+func TestNotification_ApplyProviderStatus_Open(t *testing.T) {
+	notif := createTestNotification(t, ChannelEmail)
+	notif.Status = StatusDelivered
+
+	if _, err := notif.ApplyProviderStatus(ProviderStatusOpened, ""); err != nil {
+		t.Fatalf("ApplyProviderStatus() error = %v", err)
+	}
+	if notif.OpenCount != 1 {
+		t.Errorf("OpenCount = %d, want 1", notif.OpenCount)
+	}
+}
+
+func TestNewDeliveryAttemptLog(t *testing.T) {
+	notif := createTestNotification(t, ChannelEmail)
+	notif.Status = StatusSending
+	notif.AttemptCount = 2
+	_ = notif.MarkFailed("DELIVERY_FAILED", "connection refused", "")
+	notif.Provider = "smtp"
+
+	log := NewDeliveryAttemptLog(notif, 150*time.Millisecond)
+
+	if log.Source != DeliveryLogSourceAttempt || log.Status != "failed" {
+		t.Errorf("unexpected log: %+v", log)
+	}
+	if log.AttemptNumber != 2 || log.Provider != "smtp" || log.LatencyMs != 150 {
+		t.Errorf("unexpected attempt details: %+v", log)
+	}
+	if log.ErrorCode != "DELIVERY_FAILED" || log.ErrorMessage != "connection refused" {
+		t.Errorf("error = %q %q, want the notification's error", log.ErrorCode, log.ErrorMessage)
+	}
+}
+
+func TestNewProviderStatusLog(t *testing.T) {
+	notif := createTestNotification(t, ChannelEmail)
+	notif.Provider = "sendgrid"
+	notif.ProviderMessageID = "sg-123"
+	occurredAt := time.Date(2024, 6, 1, 8, 30, 0, 0, time.UTC)
+
+	log := NewProviderStatusLog(notif, ProviderStatusBounced, "mailbox full", occurredAt)
+
+	if log.Source != DeliveryLogSourceWebhook || log.Status != "bounced" || log.ErrorMessage != "mailbox full" {
+		t.Errorf("unexpected log: %+v", log)
+	}
+	if log.ProviderMessageID != "sg-123" || !log.CreatedAt.Equal(occurredAt) {
+		t.Errorf("unexpected provider details: %+v", log)
+	}
+}
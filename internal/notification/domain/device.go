@@ -0,0 +1,130 @@
+package domain
+
+import (
+	"encoding/base64"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// MaxDeviceTokenLength bounds registered push tokens. FCM registration
+// tokens are around 160 characters and APNs tokens 64.
+const MaxDeviceTokenLength = 4096
+
+// Device is a user's device registered for push notifications. A push
+// token identifies one app install, so it belongs to at most one device:
+// registering it again refreshes that device, moving it to the registering
+// user when someone else signs in on the install.
+//
+// Browsers are registered as web devices whose token is the endpoint of
+// their push subscription, with the keys payloads are encrypted with.
+type Device struct {
+	ID         uuid.UUID      `json:"id" db:"id"`
+	TenantID   uuid.UUID      `json:"tenant_id" db:"tenant_id"`
+	UserID     uuid.UUID      `json:"user_id" db:"user_id"`
+	Platform   DevicePlatform `json:"platform" db:"platform"`
+	Token      string         `json:"-" db:"token"`
+	AppVersion string         `json:"app_version,omitempty" db:"app_version"`
+	P256DH     string         `json:"-" db:"p256dh"`
+	Auth       string         `json:"-" db:"auth"`
+	ExpiresAt  *time.Time     `json:"expires_at,omitempty" db:"expires_at"`
+	CreatedAt  time.Time      `json:"created_at" db:"created_at"`
+	LastSeenAt time.Time      `json:"last_seen_at" db:"last_seen_at"`
+}
+
+// NewDevice registers a device of a user.
+func NewDevice(tenantID, userID uuid.UUID, platform DevicePlatform, token, appVersion string) (*Device, error) {
+	if userID == uuid.Nil {
+		return nil, ErrUserIDRequired
+	}
+	deviceToken, err := NewDeviceToken(token, platform)
+	if err != nil {
+		return nil, err
+	}
+	token = deviceToken.Token()
+	if len(token) > MaxDeviceTokenLength || strings.ContainsAny(token, " \t\r\n") {
+		return nil, ErrInvalidDeviceToken
+	}
+
+	now := time.Now().UTC()
+	return &Device{
+		ID:         uuid.New(),
+		TenantID:   tenantID,
+		UserID:     userID,
+		Platform:   platform,
+		Token:      token,
+		AppVersion: strings.TrimSpace(appVersion),
+		CreatedAt:  now,
+		LastSeenAt: now,
+	}, nil
+}
+
+// NewWebPushDevice registers a browser push subscription of a user. p256dh
+// and auth are the base64url-encoded keys of the subscription, and
+// expiresAt its expirationTime, if the browser set one.
+func NewWebPushDevice(tenantID, userID uuid.UUID, endpoint, p256dh, auth, appVersion string, expiresAt *time.Time) (*Device, error) {
+	endpointURL, err := url.Parse(strings.TrimSpace(endpoint))
+	if err != nil || endpointURL.Scheme != "https" || endpointURL.Host == "" {
+		return nil, NewValidationError("endpoint", "push subscription endpoint must be an https URL", "INVALID_WEB_PUSH_ENDPOINT")
+	}
+	if key, err := decodeWebPushKey(p256dh); err != nil || len(key) != 65 || key[0] != 4 {
+		return nil, NewValidationError("keys.p256dh", "push subscription key must be an uncompressed P-256 public key", "INVALID_WEB_PUSH_KEY")
+	}
+	if secret, err := decodeWebPushKey(auth); err != nil || len(secret) != 16 {
+		return nil, NewValidationError("keys.auth", "push subscription auth secret must be 16 bytes", "INVALID_WEB_PUSH_KEY")
+	}
+	device, err := NewDevice(tenantID, userID, PlatformWeb, endpointURL.String(), appVersion)
+	if err != nil {
+		return nil, err
+	}
+	device.P256DH = strings.TrimRight(strings.TrimSpace(p256dh), "=")
+	device.Auth = strings.TrimRight(strings.TrimSpace(auth), "=")
+	if expiresAt != nil {
+		expires := expiresAt.UTC()
+		device.ExpiresAt = &expires
+	}
+	return device, nil
+}
+
+// IsWebPush reports whether the device is a browser push subscription.
+func (d *Device) IsWebPush() bool {
+	return d.Platform == PlatformWeb && d.P256DH != "" && d.Auth != ""
+}
+
+// IsExpired reports whether the device's push subscription has expired.
+func (d *Device) IsExpired(now time.Time) bool {
+	return d.ExpiresAt != nil && !now.Before(*d.ExpiresAt)
+}
+
+// decodeWebPushKey decodes a base64url-encoded subscription key, with or
+// without padding.
+func decodeWebPushKey(key string) ([]byte, error) {
+	return base64.RawURLEncoding.DecodeString(strings.TrimRight(strings.TrimSpace(key), "="))
+}
+
+// staleTokenReasons are the failure reasons with which FCM and APNs report
+// that a token will never be delivered to again, compared case-insensitively.
+var staleTokenReasons = map[string]bool{
+	// FCM HTTP v1
+	"unregistered": true,
+	// FCM legacy and Admin SDK
+	"notregistered":       true,
+	"invalidregistration": true,
+	"messaging/registration-token-not-registered": true,
+	"messaging/invalid-registration-token":        true,
+	// APNs
+	"baddevicetoken":         true,
+	"devicetokennotfortopic": true,
+	// Web push services answering 404 or 410
+	"subscription_expired": true,
+	// Providers of this service
+	"invalid_token": true,
+}
+
+// IsStaleDeviceToken reports whether a push provider's failure reason means
+// the token is no longer valid, so its device should be unregistered.
+func IsStaleDeviceToken(reason string) bool {
+	return staleTokenReasons[strings.ToLower(strings.TrimSpace(reason))]
+}
@@ -0,0 +1,80 @@
+package domain
+
+import (
+	"encoding/base64"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestNewDevice(t *testing.T) {
+	tenantID, userID := uuid.New(), uuid.New()
+	apnsToken := strings.Repeat("a1", 32)
+
+	tests := []struct {
+		name     string
+		userID   uuid.UUID
+		platform DevicePlatform
+		token    string
+		wantErr  bool
+	}{
+		{"android", userID, PlatformAndroid, "fcm:APA91bHun4MxP5egoKMwt2KZFBaFUH", false},
+		{"ios", userID, PlatformIOS, apnsToken, false},
+		{"missing user", uuid.Nil, PlatformAndroid, "fcm-token", true},
+		{"missing token", userID, PlatformAndroid, "  ", true},
+		{"unknown platform", userID, DevicePlatform("symbian"), "fcm-token", true},
+		{"short APNs token", userID, PlatformIOS, "abc", true},
+		{"token too long", userID, PlatformAndroid, strings.Repeat("x", MaxDeviceTokenLength+1), true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			device, err := NewDevice(tenantID, tt.userID, tt.platform, tt.token, " 3.1.0 ")
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("NewDevice() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && (device.AppVersion != "3.1.0" || device.LastSeenAt.IsZero()) {
+				t.Errorf("NewDevice() = %+v", device)
+			}
+		})
+	}
+}
+
+func TestNewWebPushDevice(t *testing.T) {
+	tenantID, userID := uuid.New(), uuid.New()
+	key := base64.RawURLEncoding.EncodeToString(append([]byte{4}, make([]byte, 64)...))
+	auth := base64.URLEncoding.EncodeToString(make([]byte, 16))
+	expires := time.Now().Add(time.Hour)
+
+	device, err := NewWebPushDevice(tenantID, userID, "https://updates.push.services.mozilla.com/wpush/v2/abc", key, auth, "", &expires)
+	if err != nil {
+		t.Fatalf("NewWebPushDevice() error = %v", err)
+	}
+	if !device.IsWebPush() || device.Platform != PlatformWeb || strings.HasSuffix(device.Auth, "=") {
+		t.Errorf("NewWebPushDevice() = %+v", device)
+	}
+	if device.IsExpired(time.Now()) || !device.IsExpired(expires) {
+		t.Error("IsExpired() should turn true at the expiration time")
+	}
+
+	if _, err := NewWebPushDevice(tenantID, userID, "http://push.example.com/abc", key, auth, "", nil); err == nil {
+		t.Error("NewWebPushDevice() should refuse endpoints that are not https")
+	}
+	if _, err := NewWebPushDevice(tenantID, userID, "https://push.example.com/abc", key[:20], auth, "", nil); err == nil {
+		t.Error("NewWebPushDevice() should refuse truncated keys")
+	}
+}
+
+func TestIsStaleDeviceToken(t *testing.T) {
+	for _, reason := range []string{"UNREGISTERED", "BadDeviceToken", "Unregistered", "messaging/registration-token-not-registered"} {
+		if !IsStaleDeviceToken(reason) {
+			t.Errorf("IsStaleDeviceToken(%q) = false, want true", reason)
+		}
+	}
+	for _, reason := range []string{"", "QUOTA_EXCEEDED", "TooManyRequests", "INTERNAL"} {
+		if IsStaleDeviceToken(reason) {
+			t.Errorf("IsStaleDeviceToken(%q) = true, want false", reason)
+		}
+	}
+}
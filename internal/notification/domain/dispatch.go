@@ -0,0 +1,105 @@
+package domain
+
+import "time"
+
+// DispatchPriorities lists the priorities delivery queues are kept for,
+// highest first.
+var DispatchPriorities = []NotificationPriority{
+	PriorityCritical,
+	PriorityHigh,
+	PriorityNormal,
+	PriorityLow,
+}
+
+// DispatchPolicy controls how queued notifications of different priorities
+// share the delivery workers.
+type DispatchPolicy struct {
+	// Weights is the share of deliveries each priority gets while several
+	// priorities have notifications waiting.
+	Weights map[NotificationPriority]int
+	// MaxWait is how long a notification may wait before it is delivered
+	// ahead of higher priorities, so bulk sends are never starved.
+	MaxWait time.Duration
+}
+
+// DefaultDispatchPolicy returns the policy used when none is configured:
+// critical notifications get eight deliveries for every low priority one,
+// and nothing waits longer than two minutes behind higher priorities.
+func DefaultDispatchPolicy() DispatchPolicy {
+	return DispatchPolicy{
+		Weights: map[NotificationPriority]int{
+			PriorityCritical: 8,
+			PriorityHigh:     4,
+			PriorityNormal:   2,
+			PriorityLow:      1,
+		},
+		MaxWait: 2 * time.Minute,
+	}
+}
+
+// Weight returns the weight of a priority. Every priority weighs at least
+// one, so none can be excluded by configuration.
+func (p DispatchPolicy) Weight(priority NotificationPriority) int {
+	if w := p.Weights[priority]; w > 0 {
+		return w
+	}
+	return 1
+}
+
+// DispatchScheduler decides which priority the next free delivery worker
+// serves. It uses smooth weighted round robin over the priorities that have
+// notifications waiting, so a burst of low priority notifications is spread
+// between the critical ones rather than delivered in a block. It is not safe
+// for concurrent use.
+type DispatchScheduler struct {
+	policy  DispatchPolicy
+	current map[NotificationPriority]int
+}
+
+// NewDispatchScheduler creates a DispatchScheduler for a policy.
+func NewDispatchScheduler(policy DispatchPolicy) *DispatchScheduler {
+	return &DispatchScheduler{
+		policy:  policy,
+		current: make(map[NotificationPriority]int, len(DispatchPriorities)),
+	}
+}
+
+// Next returns the priority to serve next. oldest holds, for each priority
+// with notifications waiting, when its oldest notification was enqueued. A
+// priority whose oldest notification has waited MaxWait or longer is served
+// first, the one waiting longest winning. ok is false when nothing waits.
+func (s *DispatchScheduler) Next(oldest map[NotificationPriority]time.Time, now time.Time) (priority NotificationPriority, ok bool) {
+	total := 0
+	var starved NotificationPriority
+	var starvedSince time.Time
+
+	for _, p := range DispatchPriorities {
+		enqueuedAt, waiting := oldest[p]
+		if !waiting {
+			// An idle priority starts afresh rather than with saved-up credit
+			s.current[p] = 0
+			continue
+		}
+
+		weight := s.policy.Weight(p)
+		s.current[p] += weight
+		total += weight
+
+		if !ok || s.current[p] > s.current[priority] {
+			priority, ok = p, true
+		}
+		if s.policy.MaxWait > 0 && now.Sub(enqueuedAt) >= s.policy.MaxWait &&
+			(starved == "" || enqueuedAt.Before(starvedSince)) {
+			starved, starvedSince = p, enqueuedAt
+		}
+	}
+
+	if !ok {
+		return "", false
+	}
+	if starved != "" {
+		priority = starved
+	}
+	s.current[priority] -= total
+	return priority, true
+}
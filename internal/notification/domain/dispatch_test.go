@@ -0,0 +1,79 @@
+package domain
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDispatchScheduler_Weights(t *testing.T) {
+	scheduler := NewDispatchScheduler(DefaultDispatchPolicy())
+	now := time.Now()
+	oldest := map[NotificationPriority]time.Time{
+		PriorityCritical: now,
+		PriorityHigh:     now,
+		PriorityNormal:   now,
+		PriorityLow:      now,
+	}
+
+	served := make(map[NotificationPriority]int)
+	for i := 0; i < 15*4; i++ {
+		p, ok := scheduler.Next(oldest, now)
+		if !ok {
+			t.Fatal("expected a priority to serve")
+		}
+		served[p]++
+	}
+
+	want := map[NotificationPriority]int{PriorityCritical: 32, PriorityHigh: 16, PriorityNormal: 8, PriorityLow: 4}
+	for p, n := range want {
+		if served[p] != n {
+			t.Errorf("served %s %d times, want %d", p, served[p], n)
+		}
+	}
+}
+
+func TestDispatchScheduler_Interleaves(t *testing.T) {
+	scheduler := NewDispatchScheduler(DefaultDispatchPolicy())
+	now := time.Now()
+	oldest := map[NotificationPriority]time.Time{PriorityCritical: now, PriorityLow: now}
+
+	// With weights 8 and 1 the low priority notification comes in the first nine
+	lowServed := false
+	for i := 0; i < 9; i++ {
+		if p, _ := scheduler.Next(oldest, now); p == PriorityLow {
+			lowServed = true
+		}
+	}
+	if !lowServed {
+		t.Error("expected low priority to be served within one round")
+	}
+}
+
+func TestDispatchScheduler_Starvation(t *testing.T) {
+	policy := DefaultDispatchPolicy()
+	policy.Weights[PriorityLow] = 0 // Still weighs one
+	scheduler := NewDispatchScheduler(policy)
+	now := time.Now()
+
+	oldest := map[NotificationPriority]time.Time{
+		PriorityCritical: now.Add(-time.Second),
+		PriorityNormal:   now.Add(-3 * time.Minute),
+		PriorityLow:      now.Add(-5 * time.Minute),
+	}
+
+	if p, _ := scheduler.Next(oldest, now); p != PriorityLow {
+		t.Errorf("Next() = %s, want the longest waiting priority low", p)
+	}
+	delete(oldest, PriorityLow)
+	if p, _ := scheduler.Next(oldest, now); p != PriorityNormal {
+		t.Errorf("Next() = %s, want starved priority normal", p)
+	}
+}
+
+func TestDispatchScheduler_NothingWaiting(t *testing.T) {
+	scheduler := NewDispatchScheduler(DefaultDispatchPolicy())
+
+	if p, ok := scheduler.Next(nil, time.Now()); ok {
+		t.Errorf("Next() = %s, want nothing to serve", p)
+	}
+}
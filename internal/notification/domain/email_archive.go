@@ -0,0 +1,311 @@
+package domain
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/mail"
+	"net/textproto"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// DefaultArchiveRetentionDays is how long sent emails are archived when the
+// tenant has no retention of its own: seven years, the period Malaysian
+// companies must keep business records.
+const DefaultArchiveRetentionDays = 7 * 365
+
+// ArchivedEmail is the exact MIME message of a sent email, kept for
+// compliance. Small messages are stored inline; larger ones are stored in
+// object storage and referenced by StorageKey.
+type ArchivedEmail struct {
+	ID                uuid.UUID `json:"id" db:"id"`
+	TenantID          uuid.UUID `json:"tenant_id" db:"tenant_id"`
+	NotificationID    uuid.UUID `json:"notification_id" db:"notification_id"`
+	MessageID         string    `json:"message_id" db:"message_id"` // Message-ID header
+	FromAddress       string    `json:"from_address" db:"from_address"`
+	Recipients        []string  `json:"recipients" db:"-"` // To, CC and BCC
+	Subject           string    `json:"subject" db:"subject"`
+	Provider          string    `json:"provider,omitempty" db:"provider"`
+	ProviderMessageID string    `json:"provider_message_id,omitempty" db:"provider_message_id"`
+	SizeBytes         int64     `json:"size_bytes" db:"size_bytes"`
+	Checksum          string    `json:"checksum" db:"checksum"` // SHA-256 of the MIME message
+	MIME              []byte    `json:"-" db:"mime"`
+	StorageKey        string    `json:"storage_key,omitempty" db:"storage_key"`
+	SentAt            time.Time `json:"sent_at" db:"sent_at"`
+	ExpiresAt         time.Time `json:"expires_at" db:"expires_at"`
+	CreatedAt         time.Time `json:"created_at" db:"created_at"`
+}
+
+// NewArchivedEmail archives the MIME message of a sent email notification,
+// to be kept until retention has passed.
+func NewArchivedEmail(n *Notification, recipients []string, messageID string, content []byte, retention time.Duration) *ArchivedEmail {
+	sentAt := time.Now().UTC()
+	if n.SentAt != nil {
+		sentAt = n.SentAt.UTC()
+	}
+	sum := sha256.Sum256(content)
+	return &ArchivedEmail{
+		ID:                uuid.New(),
+		TenantID:          n.TenantID,
+		NotificationID:    n.ID,
+		MessageID:         messageID,
+		FromAddress:       n.FromAddress,
+		Recipients:        recipients,
+		Subject:           n.Subject,
+		Provider:          n.Provider,
+		ProviderMessageID: n.ProviderMessageID,
+		SizeBytes:         int64(len(content)),
+		Checksum:          hex.EncodeToString(sum[:]),
+		MIME:              content,
+		SentAt:            sentAt,
+		ExpiresAt:         sentAt.Add(retention),
+		CreatedAt:         time.Now().UTC(),
+	}
+}
+
+// MoveToStorage replaces the inline MIME message with a reference to the
+// object storage key it was uploaded to.
+func (a *ArchivedEmail) MoveToStorage(key string) {
+	a.StorageKey = key
+	a.MIME = nil
+}
+
+// IsInline reports whether the MIME message is stored with the archive entry.
+func (a *ArchivedEmail) IsInline() bool {
+	return a.StorageKey == ""
+}
+
+// IsExpired reports whether the retention of the archived email has passed.
+func (a *ArchivedEmail) IsExpired(now time.Time) bool {
+	return !now.Before(a.ExpiresAt)
+}
+
+// VerifyChecksum reports whether content is the MIME message that was archived.
+func (a *ArchivedEmail) VerifyChecksum(content []byte) bool {
+	sum := sha256.Sum256(content)
+	return hex.EncodeToString(sum[:]) == a.Checksum
+}
+
+// ArchiveRetention is how long sent emails are archived, per tenant.
+type ArchiveRetention struct {
+	DefaultDays int               `json:"default_days"`
+	TenantDays  map[uuid.UUID]int `json:"tenant_days,omitempty"`
+}
+
+// DefaultArchiveRetention returns the retention used when none is configured.
+func DefaultArchiveRetention() ArchiveRetention {
+	return ArchiveRetention{DefaultDays: DefaultArchiveRetentionDays}
+}
+
+// For returns how long the emails of a tenant are archived.
+func (r ArchiveRetention) For(tenantID uuid.UUID) time.Duration {
+	days := r.DefaultDays
+	if tenantDays, ok := r.TenantDays[tenantID]; ok {
+		days = tenantDays
+	}
+	if days <= 0 {
+		days = DefaultArchiveRetentionDays
+	}
+	return time.Duration(days) * 24 * time.Hour
+}
+
+// Validate checks that every retention is at least one day.
+func (r ArchiveRetention) Validate() error {
+	if r.DefaultDays < 1 {
+		return NewValidationError("default_days", "archive retention must be at least one day", "INVALID_RETENTION")
+	}
+	for tenantID, days := range r.TenantDays {
+		if days < 1 {
+			return NewValidationError("tenant_days", fmt.Sprintf("archive retention of tenant %s must be at least one day", tenantID), "INVALID_RETENTION")
+		}
+	}
+	return nil
+}
+
+// MIMEMessage is an email to compose into an RFC 5322 message.
+type MIMEMessage struct {
+	MessageID   string
+	From        string
+	FromName    string
+	ReplyTo     string
+	To          []string
+	CC          []string
+	Subject     string
+	Date        time.Time
+	Headers     map[string]string
+	TextBody    string
+	HTMLBody    string
+	Attachments []Attachment
+}
+
+// ComposeMIME composes the message as it is sent: the text and HTML bodies
+// as alternatives, wrapped in multipart/mixed when there are attachments.
+// BCC recipients are left out, as they are from the message itself.
+func ComposeMIME(msg MIMEMessage) ([]byte, error) {
+	from := msg.From
+	if msg.FromName != "" {
+		from = (&mail.Address{Name: msg.FromName, Address: msg.From}).String()
+	}
+	date := msg.Date
+	if date.IsZero() {
+		date = time.Now()
+	}
+
+	header := textproto.MIMEHeader{}
+	for name, value := range msg.Headers {
+		header.Set(name, value)
+	}
+	header.Set("Message-ID", "<"+strings.Trim(msg.MessageID, "<>")+">")
+	header.Set("Date", date.UTC().Format(time.RFC1123Z))
+	header.Set("From", from)
+	header.Set("To", strings.Join(msg.To, ", "))
+	if len(msg.CC) > 0 {
+		header.Set("Cc", strings.Join(msg.CC, ", "))
+	}
+	if msg.ReplyTo != "" {
+		header.Set("Reply-To", msg.ReplyTo)
+	}
+	header.Set("Subject", mime.QEncoding.Encode("utf-8", msg.Subject))
+	header.Set("MIME-Version", "1.0")
+
+	bodyHeader, body, err := composeBody(msg)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(msg.Attachments) > 0 {
+		var mixedBody bytes.Buffer
+		mixed := multipart.NewWriter(&mixedBody)
+		part, err := mixed.CreatePart(bodyHeader)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := part.Write(body); err != nil {
+			return nil, err
+		}
+		for _, att := range msg.Attachments {
+			if err := writeAttachment(mixed, att); err != nil {
+				return nil, err
+			}
+		}
+		if err := mixed.Close(); err != nil {
+			return nil, err
+		}
+		bodyHeader = textproto.MIMEHeader{}
+		bodyHeader.Set("Content-Type", "multipart/mixed; boundary="+mixed.Boundary())
+		body = mixedBody.Bytes()
+	}
+	for name, values := range bodyHeader {
+		header[name] = values
+	}
+
+	var buf bytes.Buffer
+	names := make([]string, 0, len(header))
+	for name := range header {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		for _, value := range header[name] {
+			buf.WriteString(name + ": " + strings.NewReplacer("\r", "", "\n", "").Replace(value) + "\r\n")
+		}
+	}
+	buf.WriteString("\r\n")
+	buf.Write(body)
+	return buf.Bytes(), nil
+}
+
+// composeBody returns the header and content of the message body: a single
+// text or HTML part, or both as multipart/alternative.
+func composeBody(msg MIMEMessage) (textproto.MIMEHeader, []byte, error) {
+	if msg.TextBody == "" || msg.HTMLBody == "" {
+		contentType, content := "text/plain; charset=utf-8", msg.TextBody
+		if msg.HTMLBody != "" {
+			contentType, content = "text/html; charset=utf-8", msg.HTMLBody
+		}
+		return quotedPrintablePart(contentType, content)
+	}
+
+	var buf bytes.Buffer
+	alt := multipart.NewWriter(&buf)
+	for _, body := range []struct{ contentType, content string }{
+		{"text/plain; charset=utf-8", msg.TextBody},
+		{"text/html; charset=utf-8", msg.HTMLBody},
+	} {
+		header, content, err := quotedPrintablePart(body.contentType, body.content)
+		if err != nil {
+			return nil, nil, err
+		}
+		part, err := alt.CreatePart(header)
+		if err != nil {
+			return nil, nil, err
+		}
+		if _, err := part.Write(content); err != nil {
+			return nil, nil, err
+		}
+	}
+	if err := alt.Close(); err != nil {
+		return nil, nil, err
+	}
+	header := textproto.MIMEHeader{}
+	header.Set("Content-Type", "multipart/alternative; boundary="+alt.Boundary())
+	return header, buf.Bytes(), nil
+}
+
+func quotedPrintablePart(contentType, content string) (textproto.MIMEHeader, []byte, error) {
+	var buf bytes.Buffer
+	qp := quotedprintable.NewWriter(&buf)
+	if _, err := qp.Write([]byte(content)); err != nil {
+		return nil, nil, err
+	}
+	if err := qp.Close(); err != nil {
+		return nil, nil, err
+	}
+	header := textproto.MIMEHeader{}
+	header.Set("Content-Type", contentType)
+	header.Set("Content-Transfer-Encoding", "quoted-printable")
+	return header, buf.Bytes(), nil
+}
+
+func writeAttachment(w *multipart.Writer, att Attachment) error {
+	contentType := att.ContentType
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+	disposition := "attachment"
+	if att.Inline {
+		disposition = "inline"
+	}
+
+	header := textproto.MIMEHeader{}
+	header.Set("Content-Type", mime.FormatMediaType(contentType, map[string]string{"name": att.Filename}))
+	header.Set("Content-Transfer-Encoding", "base64")
+	header.Set("Content-Disposition", mime.FormatMediaType(disposition, map[string]string{"filename": att.Filename}))
+	if att.ContentID != "" {
+		header.Set("Content-ID", "<"+strings.Trim(att.ContentID, "<>")+">")
+	}
+	part, err := w.CreatePart(header)
+	if err != nil {
+		return err
+	}
+
+	// Base64 lines are limited to 76 characters
+	encoded := base64.StdEncoding.EncodeToString(att.Content)
+	for len(encoded) > 76 {
+		if _, err := part.Write([]byte(encoded[:76] + "\r\n")); err != nil {
+			return err
+		}
+		encoded = encoded[76:]
+	}
+	_, err = part.Write([]byte(encoded + "\r\n"))
+	return err
+}
@@ -0,0 +1,148 @@
+package domain
+
+import (
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/mail"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestComposeMIME_Alternative(t *testing.T) {
+	content, err := ComposeMIME(MIMEMessage{
+		MessageID: "abc@kilang.example",
+		From:      "orders@kilang.example",
+		FromName:  "Kilang Desa Murni",
+		To:        []string{"aisyah@example.com"},
+		CC:        []string{"sales@kilang.example"},
+		Subject:   "Pesanan batik anda",
+		Date:      time.Date(2024, 6, 1, 8, 30, 0, 0, time.UTC),
+		Headers:   map[string]string{"x-campaign": "raya"},
+		TextBody:  "Terima kasih",
+		HTMLBody:  "<p>Terima kasih</p>",
+	})
+	if err != nil {
+		t.Fatalf("ComposeMIME() error = %v", err)
+	}
+
+	msg, err := mail.ReadMessage(strings.NewReader(string(content)))
+	if err != nil {
+		t.Fatalf("composed message does not parse: %v", err)
+	}
+	if got := msg.Header.Get("Message-Id"); got != "<abc@kilang.example>" {
+		t.Errorf("Message-Id = %q", got)
+	}
+	if got := msg.Header.Get("Cc"); got != "sales@kilang.example" {
+		t.Errorf("Cc = %q", got)
+	}
+	if got := msg.Header.Get("X-Campaign"); got != "raya" {
+		t.Errorf("X-Campaign = %q", got)
+	}
+	from, err := mail.ParseAddress(msg.Header.Get("From"))
+	if err != nil || from.Name != "Kilang Desa Murni" {
+		t.Errorf("From = %q, %v", msg.Header.Get("From"), err)
+	}
+
+	mediaType, params, err := mime.ParseMediaType(msg.Header.Get("Content-Type"))
+	if err != nil || mediaType != "multipart/alternative" {
+		t.Fatalf("Content-Type = %q, %v", msg.Header.Get("Content-Type"), err)
+	}
+	reader := multipart.NewReader(msg.Body, params["boundary"])
+	var types []string
+	for {
+		part, err := reader.NextPart()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("NextPart() error = %v", err)
+		}
+		types = append(types, part.Header.Get("Content-Type"))
+	}
+	if len(types) != 2 || !strings.HasPrefix(types[0], "text/plain") || !strings.HasPrefix(types[1], "text/html") {
+		t.Errorf("parts = %v, want text then HTML", types)
+	}
+}
+
+func TestComposeMIME_Attachments(t *testing.T) {
+	content, err := ComposeMIME(MIMEMessage{
+		MessageID: "abc@kilang.example",
+		From:      "orders@kilang.example",
+		To:        []string{"aisyah@example.com"},
+		Subject:   "Invoice",
+		TextBody:  "Please find the invoice attached.",
+		Attachments: []Attachment{
+			{Filename: "INV-001.pdf", ContentType: "application/pdf", Content: []byte("%PDF-1.4")},
+		},
+	})
+	if err != nil {
+		t.Fatalf("ComposeMIME() error = %v", err)
+	}
+
+	msg, err := mail.ReadMessage(strings.NewReader(string(content)))
+	if err != nil {
+		t.Fatalf("composed message does not parse: %v", err)
+	}
+	mediaType, params, _ := mime.ParseMediaType(msg.Header.Get("Content-Type"))
+	if mediaType != "multipart/mixed" {
+		t.Fatalf("Content-Type = %q, want multipart/mixed", mediaType)
+	}
+	reader := multipart.NewReader(msg.Body, params["boundary"])
+	if _, err := reader.NextPart(); err != nil {
+		t.Fatalf("missing body part: %v", err)
+	}
+	attachment, err := reader.NextPart()
+	if err != nil {
+		t.Fatalf("missing attachment part: %v", err)
+	}
+	if attachment.FileName() != "INV-001.pdf" {
+		t.Errorf("attachment filename = %q", attachment.FileName())
+	}
+}
+
+func TestNewArchivedEmail(t *testing.T) {
+	n, _ := NewNotification(uuid.New(), TypeTransactional, ChannelEmail, "body")
+	sentAt := time.Date(2024, 6, 1, 8, 30, 0, 0, time.UTC)
+	n.SentAt = &sentAt
+
+	archived := NewArchivedEmail(n, []string{"aisyah@example.com"}, "abc@kilang.example", []byte("message"), 30*24*time.Hour)
+
+	if !archived.ExpiresAt.Equal(sentAt.AddDate(0, 0, 30)) {
+		t.Errorf("ExpiresAt = %v", archived.ExpiresAt)
+	}
+	if archived.SizeBytes != 7 || !archived.VerifyChecksum([]byte("message")) || archived.VerifyChecksum([]byte("other")) {
+		t.Error("size or checksum does not match the content")
+	}
+	if archived.IsExpired(sentAt.AddDate(0, 0, 29)) || !archived.IsExpired(sentAt.AddDate(0, 0, 30)) {
+		t.Error("IsExpired() does not follow ExpiresAt")
+	}
+
+	archived.MoveToStorage("email-archive/abc.eml")
+	if archived.IsInline() || archived.MIME != nil {
+		t.Error("MoveToStorage() should drop the inline message")
+	}
+}
+
+func TestArchiveRetention(t *testing.T) {
+	tenantID := uuid.New()
+	retention := ArchiveRetention{DefaultDays: 365, TenantDays: map[uuid.UUID]int{tenantID: 30}}
+
+	if got := retention.For(tenantID); got != 30*24*time.Hour {
+		t.Errorf("For(tenant) = %v, want 30 days", got)
+	}
+	if got := retention.For(uuid.New()); got != 365*24*time.Hour {
+		t.Errorf("For(other) = %v, want 365 days", got)
+	}
+	if err := retention.Validate(); err != nil {
+		t.Errorf("Validate() error = %v", err)
+	}
+
+	retention.TenantDays[tenantID] = 0
+	if err := retention.Validate(); err == nil {
+		t.Error("Validate() should reject a retention under one day")
+	}
+}
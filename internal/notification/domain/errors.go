@@ -19,6 +19,8 @@ var (
 	ErrInvalidStatus             = errors.New("invalid notification status")
 	ErrInvalidRecipient          = errors.New("invalid recipient")
 	ErrInvalidContent            = errors.New("invalid notification content")
+	ErrInvalidStatsInterval      = errors.New("invalid stats interval")
+	ErrArchivedEmailNotFound     = errors.New("archived email not found")
 
 	// Delivery errors
 	ErrDeliveryFailed            = errors.New("notification delivery failed")
@@ -30,6 +32,7 @@ var (
 	ErrProviderError             = errors.New("notification provider error")
 	ErrProviderUnavailable       = errors.New("notification provider unavailable")
 	ErrInvalidProviderConfig     = errors.New("invalid provider configuration")
+	ErrInvalidProviderStatus     = errors.New("invalid provider delivery status")
 
 	// Template errors
 	ErrTemplateNameRequired      = errors.New("template name is required")
@@ -40,6 +43,8 @@ var (
 	ErrTemplateRenderFailed      = errors.New("template rendering failed")
 	ErrMissingTemplateVariable   = errors.New("missing required template variable")
 	ErrInvalidTemplateVariable   = errors.New("invalid template variable")
+	ErrTemplateRevisionNotFound  = errors.New("template revision not found")
+	ErrTemplateRevisionMismatch  = errors.New("revision does not belong to this template")
 
 	// Email specific errors
 	ErrInvalidEmailAddress       = errors.New("invalid email address")
@@ -66,6 +71,7 @@ var (
 	ErrInvalidDeviceToken        = errors.New("invalid device token")
 	ErrDeviceTokenRequired       = errors.New("device token is required")
 	ErrDeviceNotRegistered       = errors.New("device not registered for push notifications")
+	ErrDeviceNotFound            = errors.New("device not found")
 	ErrPushNotificationTooLarge  = errors.New("push notification payload too large")
 	ErrInvalidPushPayload        = errors.New("invalid push notification payload")
 
@@ -79,6 +85,7 @@ var (
 	ErrWebhookURLRequired        = errors.New("webhook URL is required")
 	ErrWebhookRequestFailed      = errors.New("webhook request failed")
 	ErrWebhookTimeout            = errors.New("webhook request timeout")
+	ErrChatIntegrationNotFound   = errors.New("chat integration not found")
 
 	// Scheduling errors
 	ErrInvalidScheduledTime      = errors.New("invalid scheduled time")
@@ -100,6 +107,16 @@ var (
 	ErrTenantNotConfigured       = errors.New("tenant notification settings not configured")
 	ErrTenantChannelDisabled     = errors.New("notification channel disabled for tenant")
 
+	// Routing errors
+	ErrRoutingRuleNotFound       = errors.New("routing rule not found")
+
+	// Escalation errors
+	ErrEscalationPolicyNotFound  = errors.New("escalation policy not found")
+	ErrOnCallScheduleNotFound    = errors.New("on-call schedule not found")
+	ErrEscalationNotFound        = errors.New("escalation not found")
+	ErrEscalationExists          = errors.New("alert is already escalating by the policy")
+	ErrEscalationAcknowledged    = errors.New("escalation is already acknowledged")
+
 	// Concurrency errors
 	ErrConcurrentModification    = errors.New("concurrent modification detected")
 	ErrVersionMismatch           = errors.New("version mismatch")
@@ -0,0 +1,496 @@
+package domain
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Event types escalation policies can be written for: the critical
+// operational alerts, which page on-call until someone acknowledges them.
+const (
+	EscalationEventPaymentFailed = "billing.payment.failed"
+	EscalationEventSLABreached   = "platform.sla.breached"
+)
+
+// EscalatableEventTypes are the event types escalation policies can be
+// written for.
+var EscalatableEventTypes = []string{
+	EscalationEventPaymentFailed,
+	EscalationEventSLABreached,
+	RoutingEventSLOBurnRateAlert,
+}
+
+// Limits of escalation policies and on-call schedules.
+const (
+	MaxEscalationLevels         = 10
+	MaxEscalationTargets        = 10
+	MaxEscalationTimeoutMinutes = 1440
+	MaxEscalationCycles         = 100
+	MaxOnCallMembers            = 50
+	MaxOnCallOverrides          = 100
+	MaxOnCallShiftHours         = 24 * 30
+)
+
+// IsEscalatableEventType reports whether escalation policies can be written
+// for an event type.
+func IsEscalatableEventType(eventType string) bool {
+	for _, t := range EscalatableEventTypes {
+		if t == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+// EscalationTargetType is who a level of an escalation pages.
+type EscalationTargetType string
+
+const (
+	// EscalationTargetUser is a single user of the tenant.
+	EscalationTargetUser EscalationTargetType = "user"
+	// EscalationTargetSchedule is whoever is on call on an on-call schedule
+	// when the level is paged.
+	EscalationTargetSchedule EscalationTargetType = "schedule"
+	// EscalationTargetRole is the users of the tenant holding a role.
+	EscalationTargetRole EscalationTargetType = "role"
+)
+
+// EscalationTarget is who a level pages. Value is the user ID, schedule ID
+// or role name.
+type EscalationTarget struct {
+	Type  EscalationTargetType `json:"type"`
+	Value string               `json:"value"`
+}
+
+// Validate checks that the target names who to page.
+func (t EscalationTarget) Validate() error {
+	switch t.Type {
+	case EscalationTargetUser, EscalationTargetSchedule:
+		if _, err := uuid.Parse(t.Value); err != nil {
+			return NewValidationError("levels", string(t.Type)+" target must name an ID", "INVALID_TARGET")
+		}
+	case EscalationTargetRole:
+		if t.Value == "" || len(t.Value) > 100 {
+			return NewValidationError("levels", "role target must name a role", "INVALID_TARGET")
+		}
+	default:
+		return NewValidationError("levels", "target must be user, schedule or role", "INVALID_TARGET")
+	}
+	return nil
+}
+
+// EscalationLevel is a step of an escalation policy: who is paged, on which
+// channels, and how long they have to acknowledge before the next level is
+// paged.
+type EscalationLevel struct {
+	Targets        []EscalationTarget    `json:"targets"`
+	Channels       []NotificationChannel `json:"channels"`
+	TimeoutMinutes int                   `json:"timeout_minutes"`
+}
+
+// Validate checks the targets, channels and timeout of the level.
+func (l EscalationLevel) Validate() error {
+	if len(l.Targets) == 0 || len(l.Targets) > MaxEscalationTargets {
+		return NewValidationError("levels", fmt.Sprintf("each level pages 1 to %d targets", MaxEscalationTargets), "INVALID_TARGET")
+	}
+	for _, target := range l.Targets {
+		if err := target.Validate(); err != nil {
+			return err
+		}
+	}
+	if len(l.Channels) == 0 {
+		return NewValidationError("levels", "each level pages on at least one channel", "INVALID_CHANNEL")
+	}
+	for _, channel := range l.Channels {
+		if !RoutableChannels[channel] {
+			return NewValidationError("levels", "escalations cannot page on "+string(channel), "INVALID_CHANNEL")
+		}
+	}
+	if l.TimeoutMinutes < 1 || l.TimeoutMinutes > MaxEscalationTimeoutMinutes {
+		return NewValidationError("levels", fmt.Sprintf("timeout must be 1 to %d minutes", MaxEscalationTimeoutMinutes), "INVALID_TIMEOUT")
+	}
+	return nil
+}
+
+// Timeout returns how long the level has to acknowledge.
+func (l EscalationLevel) Timeout() time.Duration {
+	return time.Duration(l.TimeoutMinutes) * time.Minute
+}
+
+// EscalationPolicy pages the levels of a tenant's on-call, one after the
+// other, for critical alerts until one is acknowledged.
+type EscalationPolicy struct {
+	ID         uuid.UUID         `json:"id"`
+	TenantID   uuid.UUID         `json:"tenant_id"`
+	Name       string            `json:"name"`
+	EventTypes []string          `json:"event_types"`
+	Levels     []EscalationLevel `json:"levels"`
+	// MaxCycles is how many times the levels are paged from the first; zero
+	// repeats them until the alert is acknowledged.
+	MaxCycles int        `json:"max_cycles"`
+	Enabled   bool       `json:"enabled"`
+	CreatedBy *uuid.UUID `json:"created_by,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at"`
+}
+
+// NewEscalationPolicy creates an enabled policy repeating its levels until
+// acknowledged.
+func NewEscalationPolicy(tenantID uuid.UUID, name string, eventTypes []string, levels []EscalationLevel, createdBy *uuid.UUID) (*EscalationPolicy, error) {
+	if tenantID == uuid.Nil {
+		return nil, ErrTenantIDRequired
+	}
+	now := time.Now().UTC()
+	policy := &EscalationPolicy{
+		ID:        uuid.New(),
+		TenantID:  tenantID,
+		Enabled:   true,
+		CreatedBy: createdBy,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if err := policy.Rename(name); err != nil {
+		return nil, err
+	}
+	if err := policy.SetEventTypes(eventTypes); err != nil {
+		return nil, err
+	}
+	if err := policy.SetLevels(levels); err != nil {
+		return nil, err
+	}
+	return policy, nil
+}
+
+// Rename sets the name of the policy.
+func (p *EscalationPolicy) Rename(name string) error {
+	name = strings.TrimSpace(name)
+	if name == "" || len(name) > 100 {
+		return NewValidationError("name", "name is required and must be at most 100 characters", "INVALID_NAME")
+	}
+	p.Name = name
+	p.UpdatedAt = time.Now().UTC()
+	return nil
+}
+
+// SetEventTypes sets the alerts the policy escalates.
+func (p *EscalationPolicy) SetEventTypes(eventTypes []string) error {
+	if len(eventTypes) == 0 {
+		return NewValidationError("event_types", "at least one event type is required", "INVALID_EVENT_TYPE")
+	}
+	types := make([]string, 0, len(eventTypes))
+	seen := make(map[string]bool, len(eventTypes))
+	for _, eventType := range eventTypes {
+		eventType = strings.ToLower(strings.TrimSpace(eventType))
+		if !IsEscalatableEventType(eventType) {
+			return NewValidationError("event_types", "event type "+eventType+" cannot be escalated", "INVALID_EVENT_TYPE")
+		}
+		if !seen[eventType] {
+			seen[eventType] = true
+			types = append(types, eventType)
+		}
+	}
+	p.EventTypes = types
+	p.UpdatedAt = time.Now().UTC()
+	return nil
+}
+
+// SetLevels sets the levels paged, in order.
+func (p *EscalationPolicy) SetLevels(levels []EscalationLevel) error {
+	if len(levels) == 0 || len(levels) > MaxEscalationLevels {
+		return NewValidationError("levels", fmt.Sprintf("a policy has 1 to %d levels", MaxEscalationLevels), "INVALID_LEVELS")
+	}
+	for _, level := range levels {
+		if err := level.Validate(); err != nil {
+			return err
+		}
+	}
+	p.Levels = levels
+	p.UpdatedAt = time.Now().UTC()
+	return nil
+}
+
+// SetMaxCycles sets how many times the levels are paged; zero repeats them
+// until acknowledged.
+func (p *EscalationPolicy) SetMaxCycles(cycles int) error {
+	if cycles < 0 || cycles > MaxEscalationCycles {
+		return NewValidationError("max_cycles", fmt.Sprintf("max cycles must be 0 to %d", MaxEscalationCycles), "INVALID_MAX_CYCLES")
+	}
+	p.MaxCycles = cycles
+	p.UpdatedAt = time.Now().UTC()
+	return nil
+}
+
+// Enable starts the policy escalating new alerts.
+func (p *EscalationPolicy) Enable() {
+	p.Enabled = true
+	p.UpdatedAt = time.Now().UTC()
+}
+
+// Disable stops the policy escalating new alerts. Alerts already escalating
+// are still paged until acknowledged.
+func (p *EscalationPolicy) Disable() {
+	p.Enabled = false
+	p.UpdatedAt = time.Now().UTC()
+}
+
+// Applies reports whether the policy escalates alerts of an event type.
+func (p *EscalationPolicy) Applies(eventType string) bool {
+	if !p.Enabled {
+		return false
+	}
+	for _, t := range p.EventTypes {
+		if t == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+// OnCallOverride puts a user on call in place of the rotation, such as to
+// cover a member on leave.
+type OnCallOverride struct {
+	UserID uuid.UUID `json:"user_id"`
+	Start  time.Time `json:"start"`
+	End    time.Time `json:"end"`
+}
+
+// OnCallSchedule rotates the on-call duty of a tenant through its members,
+// one shift each, starting with the first member at RotationStart.
+type OnCallSchedule struct {
+	ID            uuid.UUID        `json:"id"`
+	TenantID      uuid.UUID        `json:"tenant_id"`
+	Name          string           `json:"name"`
+	Members       []uuid.UUID      `json:"members"`
+	RotationStart time.Time        `json:"rotation_start"`
+	ShiftHours    int              `json:"shift_hours"`
+	Overrides     []OnCallOverride `json:"overrides"`
+	CreatedAt     time.Time        `json:"created_at"`
+	UpdatedAt     time.Time        `json:"updated_at"`
+}
+
+// NewOnCallSchedule creates a schedule without overrides.
+func NewOnCallSchedule(tenantID uuid.UUID, name string, members []uuid.UUID, rotationStart time.Time, shiftHours int) (*OnCallSchedule, error) {
+	if tenantID == uuid.Nil {
+		return nil, ErrTenantIDRequired
+	}
+	now := time.Now().UTC()
+	schedule := &OnCallSchedule{
+		ID:        uuid.New(),
+		TenantID:  tenantID,
+		Overrides: []OnCallOverride{},
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if err := schedule.Rename(name); err != nil {
+		return nil, err
+	}
+	if err := schedule.SetRotation(members, rotationStart, shiftHours); err != nil {
+		return nil, err
+	}
+	return schedule, nil
+}
+
+// Rename sets the name of the schedule.
+func (s *OnCallSchedule) Rename(name string) error {
+	name = strings.TrimSpace(name)
+	if name == "" || len(name) > 100 {
+		return NewValidationError("name", "name is required and must be at most 100 characters", "INVALID_NAME")
+	}
+	s.Name = name
+	s.UpdatedAt = time.Now().UTC()
+	return nil
+}
+
+// SetRotation sets the members taking turns on call, when the rotation
+// starts and how long each shift is.
+func (s *OnCallSchedule) SetRotation(members []uuid.UUID, rotationStart time.Time, shiftHours int) error {
+	if len(members) == 0 || len(members) > MaxOnCallMembers {
+		return NewValidationError("members", fmt.Sprintf("a schedule has 1 to %d members", MaxOnCallMembers), "INVALID_MEMBERS")
+	}
+	seen := make(map[uuid.UUID]bool, len(members))
+	for _, member := range members {
+		if member == uuid.Nil || seen[member] {
+			return NewValidationError("members", "members must be distinct user IDs", "INVALID_MEMBERS")
+		}
+		seen[member] = true
+	}
+	if rotationStart.IsZero() {
+		return NewValidationError("rotation_start", "rotation start is required", "INVALID_ROTATION")
+	}
+	if shiftHours < 1 || shiftHours > MaxOnCallShiftHours {
+		return NewValidationError("shift_hours", fmt.Sprintf("shifts must be 1 to %d hours", MaxOnCallShiftHours), "INVALID_ROTATION")
+	}
+	s.Members = members
+	s.RotationStart = rotationStart.UTC()
+	s.ShiftHours = shiftHours
+	s.UpdatedAt = time.Now().UTC()
+	return nil
+}
+
+// SetOverrides replaces the overrides of the schedule.
+func (s *OnCallSchedule) SetOverrides(overrides []OnCallOverride) error {
+	if len(overrides) > MaxOnCallOverrides {
+		return NewValidationError("overrides", fmt.Sprintf("at most %d overrides are allowed", MaxOnCallOverrides), "INVALID_OVERRIDE")
+	}
+	result := make([]OnCallOverride, len(overrides))
+	for i, override := range overrides {
+		if override.UserID == uuid.Nil {
+			return NewValidationError("overrides", "override must name a user ID", "INVALID_OVERRIDE")
+		}
+		if !override.End.After(override.Start) {
+			return NewValidationError("overrides", "override must end after it starts", "INVALID_OVERRIDE")
+		}
+		result[i] = OnCallOverride{UserID: override.UserID, Start: override.Start.UTC(), End: override.End.UTC()}
+	}
+	s.Overrides = result
+	s.UpdatedAt = time.Now().UTC()
+	return nil
+}
+
+// OnCall returns who is on call at a time: the user of the latest override
+// covering it, or else the member whose shift it falls in. Before the
+// rotation starts no one is on call.
+func (s *OnCallSchedule) OnCall(at time.Time) (uuid.UUID, bool) {
+	var override *OnCallOverride
+	for i := range s.Overrides {
+		o := &s.Overrides[i]
+		if !at.Before(o.Start) && at.Before(o.End) && (override == nil || o.Start.After(override.Start)) {
+			override = o
+		}
+	}
+	if override != nil {
+		return override.UserID, true
+	}
+	if len(s.Members) == 0 || s.ShiftHours <= 0 || at.Before(s.RotationStart) {
+		return uuid.Nil, false
+	}
+	shift := int64(at.Sub(s.RotationStart) / (time.Duration(s.ShiftHours) * time.Hour))
+	return s.Members[shift%int64(len(s.Members))], true
+}
+
+// EscalationStatus is the status of an escalating alert.
+type EscalationStatus string
+
+const (
+	// EscalationStatusOpen is paging its levels.
+	EscalationStatusOpen EscalationStatus = "open"
+	// EscalationStatusAcknowledged was acknowledged and pages no more.
+	EscalationStatusAcknowledged EscalationStatus = "acknowledged"
+	// EscalationStatusExhausted paged every level the policy's maximum
+	// number of times without being acknowledged. It can still be
+	// acknowledged.
+	EscalationStatusExhausted EscalationStatus = "exhausted"
+)
+
+// Escalation is a critical alert being escalated by a policy. It pages the
+// levels of the policy in turn, each once its predecessor timed out, until
+// it is acknowledged.
+type Escalation struct {
+	ID        uuid.UUID              `json:"id"`
+	TenantID  uuid.UUID              `json:"tenant_id"`
+	PolicyID  uuid.UUID              `json:"policy_id"`
+	EventID   string                 `json:"event_id"`
+	EventType string                 `json:"event_type"`
+	Data      map[string]interface{} `json:"data"`
+	// Level is the index of the level being paged, and Cycle how many times
+	// the levels were paged from the first, starting at 1.
+	Level  int              `json:"level"`
+	Cycle  int              `json:"cycle"`
+	Status EscalationStatus `json:"status"`
+	// PagedAt is when the current level was paged; nil while it waits to be.
+	PagedAt *time.Time `json:"paged_at,omitempty"`
+	// NextEscalationAt is when the escalation is next due: the current
+	// level is paged, or the next level once it was. Nil when not open.
+	NextEscalationAt *time.Time `json:"next_escalation_at,omitempty"`
+	AcknowledgedBy   *uuid.UUID `json:"acknowledged_by,omitempty"`
+	AcknowledgedAt   *time.Time `json:"acknowledged_at,omitempty"`
+	// Version guards against instances escalating the same alert twice.
+	Version   int       `json:"version"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// StartEscalation starts escalating an alert by a policy, with its first
+// level due to be paged at once.
+func StartEscalation(policy *EscalationPolicy, eventID, eventType string, data map[string]interface{}, now time.Time) *Escalation {
+	now = now.UTC()
+	if data == nil {
+		data = map[string]interface{}{}
+	}
+	return &Escalation{
+		ID:               uuid.New(),
+		TenantID:         policy.TenantID,
+		PolicyID:         policy.ID,
+		EventID:          eventID,
+		EventType:        eventType,
+		Data:             data,
+		Cycle:            1,
+		Status:           EscalationStatusOpen,
+		NextEscalationAt: &now,
+		CreatedAt:        now,
+		UpdatedAt:        now,
+	}
+}
+
+// Escalate moves the escalation on once it is due: the current level is
+// paged, or when it already was, the next level. After the last level the
+// levels are paged again from the first, until the policy's maximum number
+// of cycles is reached. It returns the level to page, or false when there
+// is none to page.
+func (e *Escalation) Escalate(policy *EscalationPolicy, now time.Time) (EscalationLevel, bool) {
+	if e.Status != EscalationStatusOpen {
+		return EscalationLevel{}, false
+	}
+	now = now.UTC()
+	if e.PagedAt != nil {
+		e.Level++
+	}
+	// The policy may have lost levels since the last page
+	if e.Level >= len(policy.Levels) {
+		e.Level = 0
+		e.Cycle++
+	}
+	if len(policy.Levels) == 0 || (policy.MaxCycles > 0 && e.Cycle > policy.MaxCycles) {
+		e.Status = EscalationStatusExhausted
+		e.PagedAt = nil
+		e.NextEscalationAt = nil
+		e.UpdatedAt = now
+		return EscalationLevel{}, false
+	}
+
+	level := policy.Levels[e.Level]
+	next := now.Add(level.Timeout())
+	e.PagedAt = &now
+	e.NextEscalationAt = &next
+	e.UpdatedAt = now
+	return level, true
+}
+
+// PageFailed records that the current level could not be paged, so it is
+// paged again when next due.
+func (e *Escalation) PageFailed(now time.Time) {
+	if e.Status != EscalationStatusOpen {
+		return
+	}
+	now = now.UTC()
+	e.PagedAt = nil
+	e.NextEscalationAt = &now
+	e.UpdatedAt = now
+}
+
+// Acknowledge stops the escalation: someone is handling the alert.
+func (e *Escalation) Acknowledge(userID uuid.UUID, now time.Time) error {
+	if e.Status == EscalationStatusAcknowledged {
+		return ErrEscalationAcknowledged
+	}
+	now = now.UTC()
+	e.Status = EscalationStatusAcknowledged
+	e.AcknowledgedBy = &userID
+	e.AcknowledgedAt = &now
+	e.NextEscalationAt = nil
+	e.UpdatedAt = now
+	return nil
+}
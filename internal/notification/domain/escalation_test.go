@@ -0,0 +1,193 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func testEscalationPolicy(t *testing.T, timeouts ...int) *EscalationPolicy {
+	t.Helper()
+	levels := make([]EscalationLevel, len(timeouts))
+	for i, timeout := range timeouts {
+		levels[i] = EscalationLevel{
+			Targets:        []EscalationTarget{{Type: EscalationTargetUser, Value: uuid.NewString()}},
+			Channels:       []NotificationChannel{ChannelSMS},
+			TimeoutMinutes: timeout,
+		}
+	}
+	policy, err := NewEscalationPolicy(uuid.New(), "Billing on-call", []string{EscalationEventPaymentFailed}, levels, nil)
+	if err != nil {
+		t.Fatalf("NewEscalationPolicy() error = %v", err)
+	}
+	return policy
+}
+
+func TestNewEscalationPolicy_Validation(t *testing.T) {
+	tenantID := uuid.New()
+	valid := EscalationLevel{
+		Targets:        []EscalationTarget{{Type: EscalationTargetSchedule, Value: uuid.NewString()}},
+		Channels:       []NotificationChannel{ChannelSMS, ChannelPush},
+		TimeoutMinutes: 15,
+	}
+	tests := []struct {
+		name       string
+		eventTypes []string
+		levels     []EscalationLevel
+		wantErr    bool
+	}{
+		{"valid", []string{EscalationEventPaymentFailed, "PLATFORM.SLA.BREACHED"}, []EscalationLevel{valid}, false},
+		{"routine event", []string{RoutingEventLeadCreated}, []EscalationLevel{valid}, true},
+		{"no event types", nil, []EscalationLevel{valid}, true},
+		{"no levels", []string{EscalationEventSLABreached}, nil, true},
+		{"no targets", []string{EscalationEventSLABreached}, []EscalationLevel{{Channels: valid.Channels, TimeoutMinutes: 15}}, true},
+		{"role without name", []string{EscalationEventSLABreached}, []EscalationLevel{{
+			Targets: []EscalationTarget{{Type: EscalationTargetRole}}, Channels: valid.Channels, TimeoutMinutes: 15}}, true},
+		{"schedule without ID", []string{EscalationEventSLABreached}, []EscalationLevel{{
+			Targets: []EscalationTarget{{Type: EscalationTargetSchedule, Value: "primary"}}, Channels: valid.Channels, TimeoutMinutes: 15}}, true},
+		{"chat channel", []string{EscalationEventSLABreached}, []EscalationLevel{{
+			Targets: valid.Targets, Channels: []NotificationChannel{ChannelSlack}, TimeoutMinutes: 15}}, true},
+		{"no timeout", []string{EscalationEventSLABreached}, []EscalationLevel{{Targets: valid.Targets, Channels: valid.Channels}}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			policy, err := NewEscalationPolicy(tenantID, "Policy", tt.eventTypes, tt.levels, nil)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("NewEscalationPolicy() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && !policy.Applies(EscalationEventSLABreached) {
+				t.Errorf("EventTypes = %v, want the event types normalized", policy.EventTypes)
+			}
+		})
+	}
+}
+
+func TestEscalation_Escalate(t *testing.T) {
+	policy := testEscalationPolicy(t, 5, 10)
+	start := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+	escalation := StartEscalation(policy, "evt-1", EscalationEventPaymentFailed, nil, start)
+
+	// The first level is due at once
+	if escalation.NextEscalationAt == nil || !escalation.NextEscalationAt.Equal(start) {
+		t.Fatalf("NextEscalationAt = %v, want %v", escalation.NextEscalationAt, start)
+	}
+
+	steps := []struct {
+		at          time.Duration
+		wantLevel   int
+		wantCycle   int
+		wantTimeout time.Duration
+	}{
+		{0, 0, 1, 5 * time.Minute},
+		{5 * time.Minute, 1, 1, 10 * time.Minute},
+		{15 * time.Minute, 0, 2, 5 * time.Minute},
+		{20 * time.Minute, 1, 2, 10 * time.Minute},
+	}
+	for _, step := range steps {
+		now := start.Add(step.at)
+		level, ok := escalation.Escalate(policy, now)
+		if !ok {
+			t.Fatalf("Escalate() at %v paged nothing", step.at)
+		}
+		if escalation.Level != step.wantLevel || escalation.Cycle != step.wantCycle || level.Timeout() != step.wantTimeout {
+			t.Errorf("Escalate() at %v = level %d cycle %d, want level %d cycle %d", step.at, escalation.Level, escalation.Cycle, step.wantLevel, step.wantCycle)
+		}
+		if want := now.Add(step.wantTimeout); !escalation.NextEscalationAt.Equal(want) {
+			t.Errorf("NextEscalationAt = %v, want %v", escalation.NextEscalationAt, want)
+		}
+	}
+
+	// A failed page is paged again rather than skipped
+	now := start.Add(30 * time.Minute)
+	escalation.Escalate(policy, now)
+	escalation.PageFailed(now)
+	if _, ok := escalation.Escalate(policy, now.Add(time.Minute)); !ok || escalation.Level != 0 || escalation.Cycle != 3 {
+		t.Errorf("Escalate() after a failed page = level %d cycle %d, want level 0 cycle 3 again", escalation.Level, escalation.Cycle)
+	}
+
+	userID := uuid.New()
+	if err := escalation.Acknowledge(userID, now); err != nil {
+		t.Fatalf("Acknowledge() error = %v", err)
+	}
+	if escalation.NextEscalationAt != nil || *escalation.AcknowledgedBy != userID {
+		t.Errorf("Acknowledge() left %+v", escalation)
+	}
+	if _, ok := escalation.Escalate(policy, now.Add(time.Hour)); ok {
+		t.Error("an acknowledged escalation should not page")
+	}
+	if err := escalation.Acknowledge(userID, now); err != ErrEscalationAcknowledged {
+		t.Errorf("Acknowledge() error = %v, want %v", err, ErrEscalationAcknowledged)
+	}
+}
+
+func TestEscalation_MaxCycles(t *testing.T) {
+	policy := testEscalationPolicy(t, 5)
+	if err := policy.SetMaxCycles(2); err != nil {
+		t.Fatalf("SetMaxCycles() error = %v", err)
+	}
+	now := time.Now()
+	escalation := StartEscalation(policy, "evt-1", EscalationEventPaymentFailed, nil, now)
+
+	for i := 0; i < 2; i++ {
+		if _, ok := escalation.Escalate(policy, now); !ok {
+			t.Fatalf("Escalate() #%d paged nothing", i+1)
+		}
+	}
+	if _, ok := escalation.Escalate(policy, now); ok || escalation.Status != EscalationStatusExhausted {
+		t.Fatalf("Escalate() status = %s, want exhausted", escalation.Status)
+	}
+
+	// Exhausted alerts can still be acknowledged
+	if err := escalation.Acknowledge(uuid.New(), now); err != nil {
+		t.Errorf("Acknowledge() error = %v", err)
+	}
+}
+
+func TestOnCallSchedule_OnCall(t *testing.T) {
+	aminah, badrul, chong := uuid.New(), uuid.New(), uuid.New()
+	start := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+	schedule, err := NewOnCallSchedule(uuid.New(), "Primary", []uuid.UUID{aminah, badrul, chong}, start, 24)
+	if err != nil {
+		t.Fatalf("NewOnCallSchedule() error = %v", err)
+	}
+
+	tests := []struct {
+		name string
+		at   time.Time
+		want uuid.UUID
+		ok   bool
+	}{
+		{"before the rotation", start.Add(-time.Hour), uuid.Nil, false},
+		{"first shift", start, aminah, true},
+		{"second shift", start.Add(30 * time.Hour), badrul, true},
+		{"wraps around", start.Add(73 * time.Hour), aminah, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := schedule.OnCall(tt.at)
+			if got != tt.want || ok != tt.ok {
+				t.Errorf("OnCall() = %v, %v, want %v, %v", got, ok, tt.want, tt.ok)
+			}
+		})
+	}
+
+	// Chong covers Badrul's shift
+	err = schedule.SetOverrides([]OnCallOverride{{UserID: chong, Start: start.Add(24 * time.Hour), End: start.Add(48 * time.Hour)}})
+	if err != nil {
+		t.Fatalf("SetOverrides() error = %v", err)
+	}
+	if got, _ := schedule.OnCall(start.Add(30 * time.Hour)); got != chong {
+		t.Errorf("OnCall() = %v, want the override", got)
+	}
+	if got, _ := schedule.OnCall(start.Add(48 * time.Hour)); got != chong {
+		t.Errorf("OnCall() = %v, want Chong's own shift after the override", got)
+	}
+
+	if err := schedule.SetOverrides([]OnCallOverride{{UserID: chong, Start: start, End: start}}); err == nil {
+		t.Error("SetOverrides() should reject an override that never starts")
+	}
+	if err := schedule.SetRotation([]uuid.UUID{aminah, aminah}, start, 24); err == nil {
+		t.Error("SetRotation() should reject duplicate members")
+	}
+}
@@ -248,13 +248,17 @@ func NewNotificationComplainedEvent(n *Notification) *NotificationComplainedEven
 	}
 }
 
-// NotificationOpenedEvent is raised when an email is opened.
+// NotificationOpenedEvent is raised when an email is opened. The source
+// entity lets the service a notification was sent about, such as the customer
+// service, attribute the engagement.
 type NotificationOpenedEvent struct {
 	BaseDomainEvent
-	NotificationCode string    `json:"notification_code"`
-	RecipientEmail   string    `json:"recipient_email"`
-	OpenedAt         time.Time `json:"opened_at"`
-	OpenCount        int       `json:"open_count"`
+	NotificationCode string     `json:"notification_code"`
+	RecipientEmail   string     `json:"recipient_email"`
+	OpenedAt         time.Time  `json:"opened_at"`
+	OpenCount        int        `json:"open_count"`
+	SourceEntityID   *uuid.UUID `json:"source_entity_id,omitempty"`
+	SourceEntityType string     `json:"source_entity_type,omitempty"`
 }
 
 // NewNotificationOpenedEvent creates a new notification opened event.
@@ -265,17 +269,21 @@ func NewNotificationOpenedEvent(n *Notification) *NotificationOpenedEvent {
 		RecipientEmail:   n.RecipientEmail,
 		OpenedAt:         time.Now().UTC(),
 		OpenCount:        n.OpenCount,
+		SourceEntityID:   n.SourceEntityID,
+		SourceEntityType: n.SourceEntityType,
 	}
 }
 
 // NotificationClickedEvent is raised when a link in an email is clicked.
 type NotificationClickedEvent struct {
 	BaseDomainEvent
-	NotificationCode string    `json:"notification_code"`
-	RecipientEmail   string    `json:"recipient_email"`
-	ClickedAt        time.Time `json:"clicked_at"`
-	ClickCount       int       `json:"click_count"`
-	URL              string    `json:"url,omitempty"`
+	NotificationCode string     `json:"notification_code"`
+	RecipientEmail   string     `json:"recipient_email"`
+	ClickedAt        time.Time  `json:"clicked_at"`
+	ClickCount       int        `json:"click_count"`
+	URL              string     `json:"url,omitempty"`
+	SourceEntityID   *uuid.UUID `json:"source_entity_id,omitempty"`
+	SourceEntityType string     `json:"source_entity_type,omitempty"`
 }
 
 // NewNotificationClickedEvent creates a new notification clicked event.
@@ -287,6 +295,8 @@ func NewNotificationClickedEvent(n *Notification, url string) *NotificationClick
 		ClickedAt:        time.Now().UTC(),
 		ClickCount:       n.ClickCount,
 		URL:              url,
+		SourceEntityID:   n.SourceEntityID,
+		SourceEntityType: n.SourceEntityType,
 	}
 }
 
@@ -507,12 +517,16 @@ const (
 	ExternalEventPasswordChanged   ExternalEventType = "user.password_changed"
 	ExternalEventEmailVerified     ExternalEventType = "user.email_verified"
 	ExternalEventUserRoleAssigned  ExternalEventType = "user.role_assigned"
+	ExternalEventUserRecordsReassigned ExternalEventType = "user.records_reassigned"
+	ExternalEventUserSuspiciousActivity ExternalEventType = "user.suspicious_activity"
 
 	// Customer Service Events
 	ExternalEventCustomerCreated   ExternalEventType = "customer.created"
 	ExternalEventCustomerUpdated   ExternalEventType = "customer.updated"
 	ExternalEventCustomerConverted ExternalEventType = "customer.converted"
 	ExternalEventCustomerChurned   ExternalEventType = "customer.churned"
+	ExternalEventCustomerLifecycleChanged ExternalEventType = "customer.lifecycle_changed"
+	ExternalEventCustomerHealthDropped    ExternalEventType = "customer.health_dropped"
 
 	// Sales Service Events
 	ExternalEventLeadCreated       ExternalEventType = "lead.created"
@@ -526,6 +540,8 @@ const (
 	ExternalEventDealCancelled     ExternalEventType = "deal.cancelled"
 	ExternalEventInvoiceCreated    ExternalEventType = "deal.invoice_created"
 	ExternalEventPaymentReceived   ExternalEventType = "deal.payment_received"
+	ExternalEventShipmentShipped   ExternalEventType = "shipment.shipped"
+	ExternalEventShipmentDelivered ExternalEventType = "shipment.delivered"
 )
 
 // ExternalEvent represents an event received from another service.
@@ -198,7 +198,7 @@ func (h *UserCreatedHandler) HandleEvent(ctx context.Context, event *ExternalEve
 		triggers = []*NotificationTrigger{
 			{
 				EventType:    ExternalEventUserCreated,
-				TemplateCode: "welcome_email",
+				TemplateCode: TemplateCodeWelcomeEmail,
 				Channel:      ChannelEmail,
 				IsActive:     true,
 			},
@@ -238,6 +238,148 @@ func (h *UserCreatedHandler) HandleEvent(ctx context.Context, event *ExternalEve
 	return notifications, nil
 }
 
+// RecordsReassignedHandler handles user.records_reassigned events. When a
+// user's records are reassigned, each new owner is told how many leads,
+// opportunities and customers they received.
+type RecordsReassignedHandler struct {
+	*BaseEventHandler
+}
+
+// NewRecordsReassignedHandler creates a new records reassigned handler.
+func NewRecordsReassignedHandler(base *BaseEventHandler) *RecordsReassignedHandler {
+	return &RecordsReassignedHandler{BaseEventHandler: base}
+}
+
+// Supports returns true if this handler supports the event type.
+func (h *RecordsReassignedHandler) Supports(eventType ExternalEventType) bool {
+	return eventType == ExternalEventUserRecordsReassigned
+}
+
+// Priority returns the handler priority.
+func (h *RecordsReassignedHandler) Priority() int {
+	return 70
+}
+
+// HandleEvent handles the user.records_reassigned event.
+func (h *RecordsReassignedHandler) HandleEvent(ctx context.Context, event *ExternalEvent) ([]*Notification, error) {
+	var notifications []*Notification
+
+	recipientID := event.GetUUID("recipient_id")
+	if recipientID == uuid.Nil {
+		return notifications, nil
+	}
+
+	triggers, err := h.GetTriggers(ctx, event.TenantID, event.EventType)
+	if err != nil {
+		triggers = []*NotificationTrigger{
+			{
+				EventType:    ExternalEventUserRecordsReassigned,
+				TemplateCode: "records_reassigned",
+				Channel:      ChannelEmail,
+				IsActive:     true,
+			},
+			{
+				EventType:    ExternalEventUserRecordsReassigned,
+				TemplateCode: "records_reassigned",
+				Channel:      ChannelInApp,
+				IsActive:     true,
+			},
+		}
+	}
+
+	recipient := NewRecipient().
+		WithUserID(recipientID.String()).
+		WithName(event.GetString("recipient_name"))
+
+	for _, trigger := range triggers {
+		if !trigger.ShouldTrigger(event) {
+			continue
+		}
+
+		canSend, err := h.CheckPreference(ctx, event.TenantID, recipientID, trigger.Channel, TypeAssignment)
+		if err == nil && !canSend {
+			continue
+		}
+
+		notification, err := h.CreateNotificationFromTrigger(ctx, trigger, event, recipient)
+		if err != nil {
+			continue
+		}
+
+		notifications = append(notifications, notification)
+	}
+
+	return notifications, nil
+}
+
+// SuspiciousActivityHandler handles user.suspicious_activity events. When a
+// sign-in or another change to a user's account looks suspicious, the user
+// is alerted by email so they can secure their account if it was not them.
+type SuspiciousActivityHandler struct {
+	*BaseEventHandler
+}
+
+// NewSuspiciousActivityHandler creates a new suspicious activity handler.
+func NewSuspiciousActivityHandler(base *BaseEventHandler) *SuspiciousActivityHandler {
+	return &SuspiciousActivityHandler{BaseEventHandler: base}
+}
+
+// Supports returns true if this handler supports the event type.
+func (h *SuspiciousActivityHandler) Supports(eventType ExternalEventType) bool {
+	return eventType == ExternalEventUserSuspiciousActivity
+}
+
+// Priority returns the handler priority.
+func (h *SuspiciousActivityHandler) Priority() int {
+	return 100
+}
+
+// HandleEvent handles the user.suspicious_activity event.
+func (h *SuspiciousActivityHandler) HandleEvent(ctx context.Context, event *ExternalEvent) ([]*Notification, error) {
+	var notifications []*Notification
+
+	email := event.GetString("email")
+	userID := event.GetUUID("user_id")
+	if email == "" {
+		return nil, fmt.Errorf("email is required for suspicious activity alert")
+	}
+
+	triggers, err := h.GetTriggers(ctx, event.TenantID, event.EventType)
+	if err != nil {
+		triggers = []*NotificationTrigger{
+			{
+				EventType:    ExternalEventUserSuspiciousActivity,
+				TemplateCode: "suspicious_activity",
+				Channel:      ChannelEmail,
+				IsActive:     true,
+			},
+		}
+	}
+
+	recipient := NewRecipient().
+		WithUserID(userID.String()).
+		WithEmail(email).
+		WithName(event.GetString("first_name"))
+
+	// Security alerts are sent whatever the user's preferences
+	for _, trigger := range triggers {
+		if !trigger.ShouldTrigger(event) {
+			continue
+		}
+
+		notification, err := h.CreateNotificationFromTrigger(ctx, trigger, event, recipient)
+		if err != nil {
+			continue
+		}
+
+		notification.SetPriority(PriorityHigh)
+
+		notifications = append(notifications, notification)
+	}
+
+	return notifications, nil
+}
+
 // ============================================================================
 // Sales Service Event Handlers
 // ============================================================================
@@ -478,6 +620,241 @@ func (h *DealLostHandler) HandleEvent(ctx context.Context, event *ExternalEvent)
 	return notifications, nil
 }
 
+// ShipmentHandler handles shipment.shipped and shipment.delivered events
+// (Customer delivery updates).
+type ShipmentHandler struct {
+	*BaseEventHandler
+}
+
+// NewShipmentHandler creates a new shipment handler.
+func NewShipmentHandler(base *BaseEventHandler) *ShipmentHandler {
+	return &ShipmentHandler{BaseEventHandler: base}
+}
+
+// Supports returns true if this handler supports the event type.
+func (h *ShipmentHandler) Supports(eventType ExternalEventType) bool {
+	return eventType == ExternalEventShipmentShipped ||
+		eventType == ExternalEventShipmentDelivered
+}
+
+// Priority returns the handler priority.
+func (h *ShipmentHandler) Priority() int {
+	return 90
+}
+
+// HandleEvent handles the shipment.shipped and shipment.delivered events.
+func (h *ShipmentHandler) HandleEvent(ctx context.Context, event *ExternalEvent) ([]*Notification, error) {
+	var notifications []*Notification
+
+	recipientEmail := event.GetString("recipient_email")
+	recipientName := event.GetString("recipient_name")
+	if recipientName == "" {
+		recipientName = event.GetString("customer_name")
+	}
+
+	if recipientEmail == "" {
+		return notifications, nil
+	}
+
+	templateCode := "shipment_shipped"
+	if event.EventType == ExternalEventShipmentDelivered {
+		templateCode = "shipment_delivered"
+	}
+
+	triggers, err := h.GetTriggers(ctx, event.TenantID, event.EventType)
+	if err != nil {
+		triggers = []*NotificationTrigger{
+			{
+				EventType:    event.EventType,
+				TemplateCode: templateCode,
+				Channel:      ChannelEmail,
+				IsActive:     true,
+			},
+		}
+	}
+
+	recipient := NewRecipient().
+		WithEmail(recipientEmail).
+		WithName(recipientName)
+
+	for _, trigger := range triggers {
+		if !trigger.ShouldTrigger(event) {
+			continue
+		}
+
+		notification, err := h.CreateNotificationFromTrigger(ctx, trigger, event, recipient)
+		if err != nil {
+			continue
+		}
+
+		notifications = append(notifications, notification)
+	}
+
+	return notifications, nil
+}
+
+// ============================================================================
+// Customer Service Event Handlers
+// ============================================================================
+
+// CustomerWinBackHandler handles customer.lifecycle_changed events (Win-back
+// campaign). When a customer turns dormant or churns, the customer's owner is
+// prompted to win them back.
+type CustomerWinBackHandler struct {
+	*BaseEventHandler
+}
+
+// NewCustomerWinBackHandler creates a new customer win-back handler.
+func NewCustomerWinBackHandler(base *BaseEventHandler) *CustomerWinBackHandler {
+	return &CustomerWinBackHandler{BaseEventHandler: base}
+}
+
+// Supports returns true if this handler supports the event type.
+func (h *CustomerWinBackHandler) Supports(eventType ExternalEventType) bool {
+	return eventType == ExternalEventCustomerLifecycleChanged
+}
+
+// Priority returns the handler priority.
+func (h *CustomerWinBackHandler) Priority() int {
+	return 70
+}
+
+// HandleEvent handles the customer.lifecycle_changed event.
+func (h *CustomerWinBackHandler) HandleEvent(ctx context.Context, event *ExternalEvent) ([]*Notification, error) {
+	var notifications []*Notification
+
+	lifecycle := event.GetString("new_lifecycle")
+	if lifecycle != "dormant" && lifecycle != "churned" {
+		return notifications, nil
+	}
+
+	ownerID := event.GetUUID("owner_id")
+	if ownerID == uuid.Nil {
+		// Nobody to win the customer back
+		return notifications, nil
+	}
+
+	triggers, err := h.GetTriggers(ctx, event.TenantID, event.EventType)
+	if err != nil {
+		triggers = []*NotificationTrigger{
+			{
+				EventType:    ExternalEventCustomerLifecycleChanged,
+				TemplateCode: "customer_win_back",
+				Channel:      ChannelEmail,
+				IsActive:     true,
+			},
+			{
+				EventType:    ExternalEventCustomerLifecycleChanged,
+				TemplateCode: "customer_win_back",
+				Channel:      ChannelInApp,
+				IsActive:     true,
+			},
+		}
+	}
+
+	event.Payload["customer_name"] = event.GetString("name")
+	event.Payload["lifecycle"] = lifecycle
+
+	recipient := NewRecipient().
+		WithUserID(ownerID.String())
+
+	for _, trigger := range triggers {
+		if !trigger.ShouldTrigger(event) {
+			continue
+		}
+
+		canSend, err := h.CheckPreference(ctx, event.TenantID, ownerID, trigger.Channel, TypeReminder)
+		if err == nil && !canSend {
+			continue
+		}
+
+		notification, err := h.CreateNotificationFromTrigger(ctx, trigger, event, recipient)
+		if err != nil {
+			continue
+		}
+
+		notifications = append(notifications, notification)
+	}
+
+	return notifications, nil
+}
+
+// CustomerHealthDropHandler handles customer.health_dropped events. When a
+// customer's health score falls below the alert threshold or drops sharply,
+// the customer's owner is alerted before the customer is lost.
+type CustomerHealthDropHandler struct {
+	*BaseEventHandler
+}
+
+// NewCustomerHealthDropHandler creates a new customer health drop handler.
+func NewCustomerHealthDropHandler(base *BaseEventHandler) *CustomerHealthDropHandler {
+	return &CustomerHealthDropHandler{BaseEventHandler: base}
+}
+
+// Supports returns true if this handler supports the event type.
+func (h *CustomerHealthDropHandler) Supports(eventType ExternalEventType) bool {
+	return eventType == ExternalEventCustomerHealthDropped
+}
+
+// Priority returns the handler priority.
+func (h *CustomerHealthDropHandler) Priority() int {
+	return 70
+}
+
+// HandleEvent handles the customer.health_dropped event.
+func (h *CustomerHealthDropHandler) HandleEvent(ctx context.Context, event *ExternalEvent) ([]*Notification, error) {
+	var notifications []*Notification
+
+	ownerID := event.GetUUID("owner_id")
+	if ownerID == uuid.Nil {
+		// Nobody to alert
+		return notifications, nil
+	}
+
+	triggers, err := h.GetTriggers(ctx, event.TenantID, event.EventType)
+	if err != nil {
+		triggers = []*NotificationTrigger{
+			{
+				EventType:    ExternalEventCustomerHealthDropped,
+				TemplateCode: "customer_health_drop",
+				Channel:      ChannelEmail,
+				IsActive:     true,
+			},
+			{
+				EventType:    ExternalEventCustomerHealthDropped,
+				TemplateCode: "customer_health_drop",
+				Channel:      ChannelInApp,
+				IsActive:     true,
+			},
+		}
+	}
+
+	event.Payload["customer_name"] = event.GetString("name")
+
+	recipient := NewRecipient().
+		WithUserID(ownerID.String())
+
+	for _, trigger := range triggers {
+		if !trigger.ShouldTrigger(event) {
+			continue
+		}
+
+		canSend, err := h.CheckPreference(ctx, event.TenantID, ownerID, trigger.Channel, TypeAlert)
+		if err == nil && !canSend {
+			continue
+		}
+
+		notification, err := h.CreateNotificationFromTrigger(ctx, trigger, event, recipient)
+		if err != nil {
+			continue
+		}
+
+		notifications = append(notifications, notification)
+	}
+
+	return notifications, nil
+}
+
 // ============================================================================
 // Event Handler Registry
 // ============================================================================
@@ -511,10 +888,14 @@ func (r *EventHandlerRegistry) getSupportedEventTypes(handler EventHandler) []Ex
 		ExternalEventPasswordChanged,
 		ExternalEventEmailVerified,
 		ExternalEventUserRoleAssigned,
+		ExternalEventUserRecordsReassigned,
+		ExternalEventUserSuspiciousActivity,
 		ExternalEventCustomerCreated,
 		ExternalEventCustomerUpdated,
 		ExternalEventCustomerConverted,
 		ExternalEventCustomerChurned,
+		ExternalEventCustomerLifecycleChanged,
+		ExternalEventCustomerHealthDropped,
 		ExternalEventLeadCreated,
 		ExternalEventLeadConverted,
 		ExternalEventLeadQualified,
@@ -587,9 +968,16 @@ func RegisterDefaultHandlers(
 
 	// IAM Service handlers
 	registry.Register(NewUserCreatedHandler(base))
+	registry.Register(NewRecordsReassignedHandler(base))
+	registry.Register(NewSuspiciousActivityHandler(base))
 
 	// Sales Service handlers
 	registry.Register(NewLeadCreatedHandler(base))
 	registry.Register(NewDealWonHandler(base))
 	registry.Register(NewDealLostHandler(base))
+	registry.Register(NewShipmentHandler(base))
+
+	// Customer Service handlers
+	registry.Register(NewCustomerWinBackHandler(base))
+	registry.Register(NewCustomerHealthDropHandler(base))
 }
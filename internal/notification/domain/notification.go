@@ -514,12 +514,14 @@ func (n *Notification) MarkComplained() error {
 func (n *Notification) RecordOpen() {
 	n.OpenCount++
 	n.MarkUpdated()
+	n.AddDomainEvent(NewNotificationOpenedEvent(n))
 }
 
 // RecordClick records a link click event.
 func (n *Notification) RecordClick() {
 	n.ClickCount++
 	n.MarkUpdated()
+	n.AddDomainEvent(NewNotificationClickedEvent(n, ""))
 }
 
 // EnableTracking enables open and click tracking.
@@ -619,6 +621,7 @@ func (n *Notification) CanRetry() bool {
 		return false
 	}
 	return n.RetryPolicy.ShouldRetry(n.AttemptCount) &&
+		n.RetryPolicy.IsRetryable(n.Provider, n.ErrorCode) &&
 		(n.Status == StatusFailed || n.Status == StatusRetrying)
 }
 
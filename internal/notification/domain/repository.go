@@ -3,6 +3,7 @@ package domain
 
 import (
 	"context"
+	"io"
 	"time"
 
 	"github.com/google/uuid"
@@ -28,6 +29,10 @@ type NotificationRepository interface {
 	// FindByCode finds a notification by code.
 	FindByCode(ctx context.Context, tenantID uuid.UUID, code string) (*Notification, error)
 
+	// FindByProviderMessageID finds a notification by the message ID its
+	// provider assigned when accepting it.
+	FindByProviderMessageID(ctx context.Context, provider, providerMessageID string) (*Notification, error)
+
 	// List lists notifications with filtering and pagination.
 	List(ctx context.Context, filter NotificationFilter) (*NotificationList, error)
 
@@ -64,8 +69,9 @@ type NotificationRepository interface {
 	// CountByChannel counts notifications by channel for a tenant.
 	CountByChannel(ctx context.Context, tenantID uuid.UUID) (map[NotificationChannel]int64, error)
 
-	// GetStats gets notification statistics.
-	GetStats(ctx context.Context, tenantID uuid.UUID, startDate, endDate time.Time) (*NotificationStats, error)
+	// GetStats gets notification statistics, broken down by channel,
+	// template, type and status, with a series bucketed by query.Interval.
+	GetStats(ctx context.Context, query NotificationStatsQuery) (*NotificationStats, error)
 
 	// BulkCreate creates multiple notifications.
 	BulkCreate(ctx context.Context, notifications []*Notification) error
@@ -90,6 +96,19 @@ type NotificationRepository interface {
 
 	// DeleteOld deletes notifications older than a specified date.
 	DeleteOld(ctx context.Context, before time.Time) (int64, error)
+
+	// PurgeBodies clears the bodies of sent notifications created before
+	// the filter's date and returns how many were cleared.
+	PurgeBodies(ctx context.Context, filter BodyPurgeFilter) (int64, error)
+}
+
+// BodyPurgeFilter selects the notifications whose bodies are cleared. When
+// TenantID is nil every tenant except ExcludeTenantIDs is purged.
+type BodyPurgeFilter struct {
+	TenantID         *uuid.UUID  `json:"tenant_id,omitempty"`
+	ExcludeTenantIDs []uuid.UUID `json:"exclude_tenant_ids,omitempty"`
+	Before           time.Time   `json:"before"`
+	Limit            int         `json:"limit"`
 }
 
 // NotificationFilter defines filtering options for notification queries.
@@ -146,6 +165,12 @@ type NotificationStats struct {
 	Period           string                         `json:"period"`
 	StartDate        time.Time                      `json:"start_date"`
 	EndDate          time.Time                      `json:"end_date"`
+
+	// Delivery funnel counts, overall and broken down
+	Counts         DeliveryCounts                         `json:"counts"`
+	ChannelCounts  map[NotificationChannel]DeliveryCounts `json:"channel_counts"`
+	TemplateCounts []TemplateDeliveryCounts               `json:"template_counts"`
+	Series         []StatsBucket                          `json:"series"`
 }
 
 // TemplateRepository defines the interface for template persistence.
@@ -238,6 +263,22 @@ type TemplateList struct {
 	HasMore   bool                    `json:"has_more"`
 }
 
+// TemplateRevisionRepository defines the interface for template revision
+// persistence. Revisions are immutable, so there is no update or delete.
+type TemplateRevisionRepository interface {
+	// Create stores a new revision.
+	Create(ctx context.Context, revision *TemplateRevision) error
+
+	// FindByTemplate lists the revisions of a template, newest first.
+	FindByTemplate(ctx context.Context, templateID uuid.UUID, offset, limit int) ([]*TemplateRevision, int64, error)
+
+	// FindByNumber finds a revision of a template by its number.
+	FindByNumber(ctx context.Context, templateID uuid.UUID, number int) (*TemplateRevision, error)
+
+	// GetLatestNumber gets the number of the latest revision of a template, or 0 if it has none.
+	GetLatestNumber(ctx context.Context, templateID uuid.UUID) (int, error)
+}
+
 // PreferenceRepository defines the interface for notification preference persistence.
 type PreferenceRepository interface {
 	// Save saves user notification preferences.
@@ -413,6 +454,9 @@ type UnitOfWork interface {
 	// Templates returns the template repository.
 	Templates() TemplateRepository
 
+	// TemplateRevisions returns the template revision repository.
+	TemplateRevisions() TemplateRevisionRepository
+
 	// Preferences returns the preference repository.
 	Preferences() PreferenceRepository
 
@@ -428,19 +472,21 @@ type UnitOfWork interface {
 
 // DeliveryLog represents a delivery attempt log entry.
 type DeliveryLog struct {
-	ID             uuid.UUID   `json:"id" db:"id"`
-	NotificationID uuid.UUID   `json:"notification_id" db:"notification_id"`
-	TenantID       uuid.UUID   `json:"tenant_id" db:"tenant_id"`
-	Channel        NotificationChannel `json:"channel" db:"channel"`
-	Provider       string      `json:"provider" db:"provider"`
-	Status         string      `json:"status" db:"status"`
-	AttemptNumber  int         `json:"attempt_number" db:"attempt_number"`
-	Request        string      `json:"request,omitempty" db:"request"`
-	Response       string      `json:"response,omitempty" db:"response"`
-	ErrorCode      string      `json:"error_code,omitempty" db:"error_code"`
-	ErrorMessage   string      `json:"error_message,omitempty" db:"error_message"`
-	LatencyMs      int64       `json:"latency_ms" db:"latency_ms"`
-	CreatedAt      time.Time   `json:"created_at" db:"created_at"`
+	ID                uuid.UUID           `json:"id" db:"id"`
+	NotificationID    uuid.UUID           `json:"notification_id" db:"notification_id"`
+	TenantID          uuid.UUID           `json:"tenant_id" db:"tenant_id"`
+	Channel           NotificationChannel `json:"channel" db:"channel"`
+	Source            DeliveryLogSource   `json:"source" db:"source"`
+	Provider          string              `json:"provider" db:"provider"`
+	ProviderMessageID string              `json:"provider_message_id,omitempty" db:"provider_message_id"`
+	Status            string              `json:"status" db:"status"`
+	AttemptNumber     int                 `json:"attempt_number" db:"attempt_number"`
+	Request           string              `json:"request,omitempty" db:"request"`
+	Response          string              `json:"response,omitempty" db:"response"`
+	ErrorCode         string              `json:"error_code,omitempty" db:"error_code"`
+	ErrorMessage      string              `json:"error_message,omitempty" db:"error_message"`
+	LatencyMs         int64               `json:"latency_ms" db:"latency_ms"`
+	CreatedAt         time.Time           `json:"created_at" db:"created_at"`
 }
 
 // DeliveryLogRepository defines the interface for delivery log persistence.
@@ -458,6 +504,83 @@ type DeliveryLogRepository interface {
 	DeleteOld(ctx context.Context, before time.Time) (int64, error)
 }
 
+// ArchivedEmailRepository defines the interface for sent email archive
+// persistence. Archived emails are immutable until their retention passes.
+type ArchivedEmailRepository interface {
+	// Create stores an archived email.
+	Create(ctx context.Context, email *ArchivedEmail) error
+
+	// FindByID finds an archived email by ID, including its inline MIME message.
+	FindByID(ctx context.Context, id uuid.UUID) (*ArchivedEmail, error)
+
+	// FindByNotification finds the archived email of a notification.
+	FindByNotification(ctx context.Context, notificationID uuid.UUID) (*ArchivedEmail, error)
+
+	// Search searches a tenant's archived emails, newest first. MIME
+	// messages are not loaded.
+	Search(ctx context.Context, filter ArchivedEmailFilter) (*ArchivedEmailList, error)
+
+	// FindExpired finds archived emails whose retention has passed.
+	FindExpired(ctx context.Context, now time.Time, limit int) ([]*ArchivedEmail, error)
+
+	// DeleteByIDs permanently deletes archived emails.
+	DeleteByIDs(ctx context.Context, ids []uuid.UUID) (int64, error)
+}
+
+// ArchivedEmailFilter defines filtering options for archived email searches.
+type ArchivedEmailFilter struct {
+	TenantID   uuid.UUID  `json:"tenant_id"`
+	Recipient  string     `json:"recipient,omitempty"` // Matches part of any recipient address
+	Subject    string     `json:"subject,omitempty"`   // Matches part of the subject
+	SentAfter  *time.Time `json:"sent_after,omitempty"`
+	SentBefore *time.Time `json:"sent_before,omitempty"`
+	Offset     int        `json:"offset"`
+	Limit      int        `json:"limit"`
+}
+
+// ArchivedEmailList represents a paginated list of archived emails.
+type ArchivedEmailList struct {
+	Emails  []*ArchivedEmail `json:"emails"`
+	Total   int64            `json:"total"`
+	Offset  int              `json:"offset"`
+	Limit   int              `json:"limit"`
+	HasMore bool             `json:"has_more"`
+}
+
+// RecipientEraser erases an erased customer's personal data from sent
+// notifications.
+type RecipientEraser interface {
+	// EraseRecipients anonymizes the notifications sent to the customer or
+	// to one of the hashes of its email addresses and phone numbers, clears
+	// their delivery logs and expires their archived emails, so the archive
+	// retention sweep deletes them. It returns the number of records changed.
+	EraseRecipients(ctx context.Context, tenantID, customerID uuid.UUID, identifierHashes []string) (int64, error)
+}
+
+// Tenant export data sets owned by the notification service.
+const (
+	TenantExportTemplates     = "templates"
+	TenantExportNotifications = "notifications"
+)
+
+// DemoDataRepository manages a tenant's seeded demo notifications.
+type DemoDataRepository interface {
+	// FindExisting returns which of the notifications exist, deleted or not.
+	FindExisting(ctx context.Context, tenantID uuid.UUID, ids []uuid.UUID) (map[uuid.UUID]bool, error)
+	// Purge permanently deletes the notifications and returns how many were
+	// deleted.
+	Purge(ctx context.Context, tenantID uuid.UUID, ids []uuid.UUID) (int64, error)
+}
+
+// TenantDataExporter writes a tenant's notification records for a tenant
+// data export.
+type TenantDataExporter interface {
+	// ExportTenantData writes every live record of the data set to w as one
+	// JSON object per line, oldest first, and returns the number written.
+	// Notifications are exported without their bodies.
+	ExportTenantData(ctx context.Context, tenantID uuid.UUID, dataset string, w io.Writer) (int64, error)
+}
+
 // WebhookLog represents a webhook delivery log entry.
 type WebhookLog struct {
 	ID             uuid.UUID `json:"id" db:"id"`
@@ -520,3 +643,131 @@ type SuppressionRepository interface {
 	// DeleteExpired deletes expired suppressions.
 	DeleteExpired(ctx context.Context) (int64, error)
 }
+
+// DeviceRepository defines the interface for push device persistence.
+type DeviceRepository interface {
+	// Register stores a device, or refreshes the device already registered
+	// with its token, taking over its user, platform and app version. It
+	// returns the stored device.
+	Register(ctx context.Context, device *Device) (*Device, error)
+
+	// FindByUser finds the devices of a user, most recently seen first.
+	FindByUser(ctx context.Context, tenantID, userID uuid.UUID) ([]*Device, error)
+
+	// Delete unregisters a device of a user.
+	Delete(ctx context.Context, tenantID, userID, id uuid.UUID) error
+
+	// DeleteByTokens unregisters the devices with the given tokens.
+	DeleteByTokens(ctx context.Context, tenantID uuid.UUID, tokens []string) (int64, error)
+
+	// DeleteExpired unregisters up to limit devices whose push subscription
+	// expired before now.
+	DeleteExpired(ctx context.Context, now time.Time, limit int) (int64, error)
+}
+
+// ChatIntegrationRepository defines the interface for chat integration
+// persistence.
+type ChatIntegrationRepository interface {
+	// Create stores a new integration.
+	Create(ctx context.Context, integration *ChatIntegration) error
+
+	// Update stores the changes to an integration.
+	Update(ctx context.Context, integration *ChatIntegration) error
+
+	// FindByID finds an integration of a tenant.
+	FindByID(ctx context.Context, tenantID, id uuid.UUID) (*ChatIntegration, error)
+
+	// FindByTenant finds the integrations of a tenant, oldest first.
+	FindByTenant(ctx context.Context, tenantID uuid.UUID) ([]*ChatIntegration, error)
+
+	// FindEnabledByTrigger finds the enabled integrations of a tenant that
+	// post about a trigger.
+	FindEnabledByTrigger(ctx context.Context, tenantID uuid.UUID, trigger ChatTrigger) ([]*ChatIntegration, error)
+
+	// Delete removes an integration.
+	Delete(ctx context.Context, tenantID, id uuid.UUID) error
+}
+
+// RoutingRuleRepository defines the interface for routing rule persistence.
+type RoutingRuleRepository interface {
+	// Create stores a new rule.
+	Create(ctx context.Context, rule *RoutingRule) error
+
+	// Update stores the changes to a rule.
+	Update(ctx context.Context, rule *RoutingRule) error
+
+	// FindByID finds a rule of a tenant.
+	FindByID(ctx context.Context, tenantID, id uuid.UUID) (*RoutingRule, error)
+
+	// FindByTenant finds the rules of a tenant, by priority and then oldest
+	// first.
+	FindByTenant(ctx context.Context, tenantID uuid.UUID) ([]*RoutingRule, error)
+
+	// FindAll finds the rules of every tenant, for building the routing
+	// table.
+	FindAll(ctx context.Context) ([]*RoutingRule, error)
+
+	// Delete removes a rule.
+	Delete(ctx context.Context, tenantID, id uuid.UUID) error
+}
+
+// EscalationPolicyRepository defines the interface for escalation policy
+// persistence.
+type EscalationPolicyRepository interface {
+	// Create stores a new policy.
+	Create(ctx context.Context, policy *EscalationPolicy) error
+
+	// Update stores the changes to a policy.
+	Update(ctx context.Context, policy *EscalationPolicy) error
+
+	// FindByID finds a policy of a tenant.
+	FindByID(ctx context.Context, tenantID, id uuid.UUID) (*EscalationPolicy, error)
+
+	// FindByTenant finds the policies of a tenant, oldest first.
+	FindByTenant(ctx context.Context, tenantID uuid.UUID) ([]*EscalationPolicy, error)
+
+	// Delete removes a policy.
+	Delete(ctx context.Context, tenantID, id uuid.UUID) error
+}
+
+// OnCallScheduleRepository defines the interface for on-call schedule
+// persistence.
+type OnCallScheduleRepository interface {
+	// Create stores a new schedule.
+	Create(ctx context.Context, schedule *OnCallSchedule) error
+
+	// Update stores the changes to a schedule.
+	Update(ctx context.Context, schedule *OnCallSchedule) error
+
+	// FindByID finds a schedule of a tenant.
+	FindByID(ctx context.Context, tenantID, id uuid.UUID) (*OnCallSchedule, error)
+
+	// FindByTenant finds the schedules of a tenant, oldest first.
+	FindByTenant(ctx context.Context, tenantID uuid.UUID) ([]*OnCallSchedule, error)
+
+	// Delete removes a schedule.
+	Delete(ctx context.Context, tenantID, id uuid.UUID) error
+}
+
+// EscalationRepository defines the interface for escalation persistence.
+type EscalationRepository interface {
+	// Create stores a new escalation. It returns ErrEscalationExists when
+	// the policy already escalates the event.
+	Create(ctx context.Context, escalation *Escalation) error
+
+	// Update stores the changes to an escalation and increments its version.
+	// It returns ErrConcurrentModification when the escalation was changed
+	// since it was loaded.
+	Update(ctx context.Context, escalation *Escalation) error
+
+	// FindByID finds an escalation of a tenant.
+	FindByID(ctx context.Context, tenantID, id uuid.UUID) (*Escalation, error)
+
+	// FindByTenant finds the escalations of a tenant with a status, or of
+	// any status when empty, newest first.
+	FindByTenant(ctx context.Context, tenantID uuid.UUID, status EscalationStatus, offset, limit int) ([]*Escalation, int64, error)
+
+	// FindDue finds open escalations of every tenant due by a time, oldest
+	// due first.
+	FindDue(ctx context.Context, before time.Time, limit int) ([]*Escalation, error)
+}
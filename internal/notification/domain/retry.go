@@ -0,0 +1,117 @@
+package domain
+
+import (
+	"errors"
+	"net/http"
+	"time"
+)
+
+// Delivery error codes recorded on a notification when its provider call fails.
+const (
+	// DeliveryErrorFailed is a failure the provider gave no details about.
+	DeliveryErrorFailed = "DELIVERY_FAILED"
+	// DeliveryErrorNetwork means the provider could not be reached.
+	DeliveryErrorNetwork = "NETWORK_ERROR"
+	// DeliveryErrorRateLimited means the provider asked to slow down.
+	DeliveryErrorRateLimited = "RATE_LIMITED"
+	// DeliveryErrorUnavailable is a server error at the provider.
+	DeliveryErrorUnavailable = "PROVIDER_UNAVAILABLE"
+	// DeliveryErrorRejected means the provider refused the request, e.g. for
+	// an invalid recipient or credentials; sending it again will not help.
+	DeliveryErrorRejected = "REJECTED"
+	// DeliveryErrorUndeliverable means the recipient cannot receive messages.
+	DeliveryErrorUndeliverable = "UNDELIVERABLE"
+)
+
+// ClassifyDeliveryError returns the delivery error code for a failed provider
+// call from the HTTP status code of its response; zero means no response.
+func ClassifyDeliveryError(statusCode int) string {
+	switch {
+	case statusCode == 0:
+		return DeliveryErrorNetwork
+	case statusCode == http.StatusTooManyRequests:
+		return DeliveryErrorRateLimited
+	case statusCode == http.StatusRequestTimeout || statusCode >= 500:
+		return DeliveryErrorUnavailable
+	case statusCode >= 400:
+		return DeliveryErrorRejected
+	}
+	return DeliveryErrorFailed
+}
+
+// DeliveryErrorDetails returns the provider and error code of err, which are
+// empty and DeliveryErrorFailed unless err wraps a DeliveryError.
+func DeliveryErrorDetails(err error) (provider, code string) {
+	var deliveryErr *DeliveryError
+	if errors.As(err, &deliveryErr) {
+		return deliveryErr.Provider, deliveryErr.Code
+	}
+	return "", DeliveryErrorFailed
+}
+
+// RetryPolicies holds the retry policy of each channel, with Default for the
+// channels that have none.
+type RetryPolicies struct {
+	Default  RetryPolicy                         `json:"default"`
+	Channels map[NotificationChannel]RetryPolicy `json:"channels,omitempty"`
+}
+
+// DefaultRetryPolicies returns the default retry policy for every channel.
+func DefaultRetryPolicies() RetryPolicies {
+	return RetryPolicies{Default: DefaultRetryPolicy()}
+}
+
+// For returns the retry policy of a channel.
+func (p RetryPolicies) For(channel NotificationChannel) RetryPolicy {
+	if policy, ok := p.Channels[channel]; ok {
+		return policy
+	}
+	return p.Default
+}
+
+// Validate checks every policy.
+func (p RetryPolicies) Validate() error {
+	if err := p.Default.Validate(); err != nil {
+		return err
+	}
+	for channel, policy := range p.Channels {
+		if !channel.IsValid() {
+			return ErrInvalidChannel
+		}
+		if err := policy.Validate(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// RecordDeliveryFailure records a failed delivery attempt under policy and
+// returns whether another attempt was scheduled. If the error is retryable
+// and attempts remain, the notification waits for its next attempt in
+// retrying status; otherwise it fails for good and NotificationFailedEvent
+// is raised. random (0 to 1) sets the jitter of the retry delay.
+func (n *Notification) RecordDeliveryFailure(policy RetryPolicy, provider, errorCode, errorMessage string, random float64) (bool, error) {
+	if !n.Status.CanTransitionTo(StatusFailed) {
+		return false, NewValidationError("status", "cannot record a delivery failure in current status", "INVALID_TRANSITION")
+	}
+
+	n.RetryPolicy = &policy
+	if provider != "" {
+		n.Provider = provider
+	}
+
+	if !policy.IsRetryable(n.Provider, errorCode) || !policy.ShouldRetry(n.AttemptCount) ||
+		!n.Status.CanTransitionTo(StatusRetrying) {
+		return false, n.MarkFailed(errorCode, errorMessage, "")
+	}
+
+	nextRetryAt := time.Now().UTC().Add(policy.NextDelay(n.AttemptCount, random))
+	n.Status = StatusRetrying
+	n.ErrorCode = errorCode
+	n.ErrorMessage = errorMessage
+	n.NextRetryAt = &nextRetryAt
+	n.MarkUpdated()
+	n.IncrementVersion()
+	n.AddDomainEvent(NewNotificationRetryScheduledEvent(n))
+	return true, nil
+}
@@ -0,0 +1,207 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestClassifyDeliveryError(t *testing.T) {
+	tests := []struct {
+		statusCode int
+		want       string
+	}{
+		{0, DeliveryErrorNetwork},
+		{400, DeliveryErrorRejected},
+		{401, DeliveryErrorRejected},
+		{408, DeliveryErrorUnavailable},
+		{429, DeliveryErrorRateLimited},
+		{500, DeliveryErrorUnavailable},
+		{503, DeliveryErrorUnavailable},
+		{302, DeliveryErrorFailed},
+	}
+
+	for _, tt := range tests {
+		if got := ClassifyDeliveryError(tt.statusCode); got != tt.want {
+			t.Errorf("ClassifyDeliveryError(%d) = %s, want %s", tt.statusCode, got, tt.want)
+		}
+	}
+}
+
+func TestRetryPolicy_NextDelay(t *testing.T) {
+	policy := RetryPolicy{MaxAttempts: 5, InitialInterval: 60, MaxInterval: 300, Multiplier: 2.0, Jitter: 0.5}
+
+	if got := policy.NextDelay(2, 0); got != 120*time.Second {
+		t.Errorf("NextDelay(2, 0) = %v, want 2m", got)
+	}
+	if got := policy.NextDelay(2, 1); got != 60*time.Second {
+		t.Errorf("NextDelay(2, 1) = %v, want 1m", got)
+	}
+
+	policy.Jitter = 0
+	if got := policy.NextDelay(4, 1); got != 300*time.Second {
+		t.Errorf("NextDelay(4, 1) without jitter = %v, want 5m", got)
+	}
+}
+
+func TestRetryPolicy_IsRetryable(t *testing.T) {
+	policy := DefaultRetryPolicy()
+	policy.ProviderErrors = map[string]RetryErrorRules{
+		"twilio":   {Retryable: []string{DeliveryErrorRejected}},
+		"sendgrid": {NonRetryable: []string{DeliveryErrorRateLimited}},
+	}
+
+	tests := []struct {
+		name      string
+		provider  string
+		errorCode string
+		want      bool
+	}{
+		{"retryable by default", "ses", DeliveryErrorUnavailable, true},
+		{"non-retryable", "ses", DeliveryErrorRejected, false},
+		{"code case ignored", "ses", "rejected", false},
+		{"provider retries", "twilio", DeliveryErrorRejected, true},
+		{"provider name case ignored", "Twilio", DeliveryErrorRejected, true},
+		{"provider does not retry", "sendgrid", DeliveryErrorRateLimited, false},
+		{"provider falls back to policy", "sendgrid", DeliveryErrorUndeliverable, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := policy.IsRetryable(tt.provider, tt.errorCode); got != tt.want {
+				t.Errorf("IsRetryable(%q, %q) = %v, want %v", tt.provider, tt.errorCode, got, tt.want)
+			}
+		})
+	}
+
+	policy.RetryableErrors = []string{DeliveryErrorNetwork}
+	if policy.IsRetryable("ses", DeliveryErrorUnavailable) {
+		t.Error("expected only the allowed codes to be retried")
+	}
+}
+
+func TestRetryPolicy_Validate(t *testing.T) {
+	policy := DefaultRetryPolicy()
+	if err := policy.Validate(); err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+
+	policy.Jitter = 1.5
+	if err := policy.Validate(); err == nil {
+		t.Error("expected an error for jitter above 1")
+	}
+}
+
+func TestRetryPolicies_For(t *testing.T) {
+	policies := DefaultRetryPolicies()
+	policies.Channels = map[NotificationChannel]RetryPolicy{
+		ChannelSMS: {MaxAttempts: 5, InitialInterval: 30, MaxInterval: 600, Multiplier: 2.0},
+	}
+
+	if got := policies.For(ChannelSMS).MaxAttempts; got != 5 {
+		t.Errorf("For(sms).MaxAttempts = %d, want 5", got)
+	}
+	if got := policies.For(ChannelEmail).MaxAttempts; got != 3 {
+		t.Errorf("For(email).MaxAttempts = %d, want the default 3", got)
+	}
+}
+
+func sendingNotification(t *testing.T) *Notification {
+	t.Helper()
+	notif := createTestNotification(t, ChannelEmail)
+	if err := notif.Queue(); err != nil {
+		t.Fatalf("Queue() error = %v", err)
+	}
+	if err := notif.MarkSending(); err != nil {
+		t.Fatalf("MarkSending() error = %v", err)
+	}
+	notif.ClearDomainEvents()
+	return notif
+}
+
+func TestNotification_RecordDeliveryFailure_Retries(t *testing.T) {
+	notif := sendingNotification(t)
+
+	retrying, err := notif.RecordDeliveryFailure(DefaultRetryPolicy(), "ses", DeliveryErrorUnavailable, "service unavailable", 0)
+	if err != nil {
+		t.Fatalf("RecordDeliveryFailure() error = %v", err)
+	}
+	if !retrying || notif.Status != StatusRetrying {
+		t.Fatalf("expected a retry, got status %s", notif.Status)
+	}
+	if notif.Provider != "ses" || notif.ErrorCode != DeliveryErrorUnavailable {
+		t.Errorf("Provider, ErrorCode = %s, %s", notif.Provider, notif.ErrorCode)
+	}
+	if notif.NextRetryAt == nil || notif.NextRetryAt.Before(time.Now().Add(59*time.Second)) {
+		t.Errorf("NextRetryAt = %v, want about a minute from now", notif.NextRetryAt)
+	}
+
+	events := notif.GetDomainEvents()
+	if len(events) != 1 {
+		t.Fatalf("expected 1 event, got %d", len(events))
+	}
+	if _, ok := events[0].(*NotificationRetryScheduledEvent); !ok {
+		t.Errorf("expected NotificationRetryScheduledEvent, got %T", events[0])
+	}
+}
+
+func TestNotification_RecordDeliveryFailure_Fails(t *testing.T) {
+	tests := []struct {
+		name      string
+		attempts  int
+		errorCode string
+	}{
+		{"non-retryable error", 1, DeliveryErrorRejected},
+		{"attempts exhausted", 3, DeliveryErrorUnavailable},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			notif := sendingNotification(t)
+			notif.AttemptCount = tt.attempts
+
+			retrying, err := notif.RecordDeliveryFailure(DefaultRetryPolicy(), "ses", tt.errorCode, "failed", 0)
+			if err != nil {
+				t.Fatalf("RecordDeliveryFailure() error = %v", err)
+			}
+			if retrying || notif.Status != StatusFailed {
+				t.Fatalf("expected the notification to fail, got status %s", notif.Status)
+			}
+
+			events := notif.GetDomainEvents()
+			if len(events) != 1 {
+				t.Fatalf("expected 1 event, got %d", len(events))
+			}
+			failed, ok := events[0].(*NotificationFailedEvent)
+			if !ok {
+				t.Fatalf("expected NotificationFailedEvent, got %T", events[0])
+			}
+			if failed.Retryable {
+				t.Error("expected the failure not to be retryable")
+			}
+		})
+	}
+}
+
+func TestNotification_RecordDeliveryFailure_InvalidStatus(t *testing.T) {
+	notif := createTestNotification(t, ChannelEmail)
+
+	if _, err := notif.RecordDeliveryFailure(DefaultRetryPolicy(), "ses", DeliveryErrorNetwork, "failed", 0); err == nil {
+		t.Error("expected an error for a notification that is not being sent")
+	}
+}
+
+func TestDeliveryErrorDetails(t *testing.T) {
+	err := fmt.Errorf("sending welcome email: %w", NewDeliveryError("n-1", "sms", "twilio", "too many requests", DeliveryErrorRateLimited, true))
+
+	provider, code := DeliveryErrorDetails(err)
+	if provider != "twilio" || code != DeliveryErrorRateLimited {
+		t.Errorf("DeliveryErrorDetails() = %s, %s, want twilio, %s", provider, code, DeliveryErrorRateLimited)
+	}
+
+	provider, code = DeliveryErrorDetails(errors.New("boom"))
+	if provider != "" || code != DeliveryErrorFailed {
+		t.Errorf("DeliveryErrorDetails() of a plain error = %q, %s", provider, code)
+	}
+}
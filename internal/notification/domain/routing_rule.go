@@ -0,0 +1,657 @@
+package domain
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Event types routing rules can be written for: the events the notification
+// service subscribes to.
+const (
+	RoutingEventUserCreated           = "iam.user.created"
+	RoutingEventLeadCreated           = "sales.lead.created"
+	RoutingEventOpportunityWon        = "sales.opportunity.won"
+	RoutingEventOpportunityLost       = "sales.opportunity.lost"
+	RoutingEventDealInvoiceOverdue    = "sales.deal.invoice_overdue"
+	RoutingEventInsightBiggestDealWon = "sales.insight.biggest_deal_won"
+	RoutingEventInsightLowPipeline    = "sales.insight.low_pipeline_coverage"
+	RoutingEventInsightLeadVolumeDrop = "sales.insight.lead_volume_drop"
+	RoutingEventCommentMentioned      = "sales.comment.mentioned"
+	RoutingEventEmailSend             = "notification.email.send"
+	RoutingEventSMSSend               = "notification.sms.send"
+	RoutingEventSLOBurnRateAlert      = "platform.slo.burn_rate_alert"
+)
+
+// RoutableEventTypes are the event types routing rules can be written for.
+var RoutableEventTypes = []string{
+	RoutingEventUserCreated,
+	RoutingEventLeadCreated,
+	RoutingEventOpportunityWon,
+	RoutingEventOpportunityLost,
+	RoutingEventDealInvoiceOverdue,
+	RoutingEventInsightBiggestDealWon,
+	RoutingEventInsightLowPipeline,
+	RoutingEventInsightLeadVolumeDrop,
+	RoutingEventCommentMentioned,
+	RoutingEventEmailSend,
+	RoutingEventSMSSend,
+	RoutingEventSLOBurnRateAlert,
+}
+
+// Limits of a routing rule.
+const (
+	MaxRoutingFilters   = 20
+	MaxRoutingAudiences = 10
+)
+
+// RoutingAudienceType is who a routing rule notifies.
+type RoutingAudienceType string
+
+const (
+	// RoutingAudienceOwner is the user owning the record the event is
+	// about, named by its owner_id.
+	RoutingAudienceOwner RoutingAudienceType = "owner"
+	// RoutingAudienceTeam is the members of a team: the one the audience
+	// names, or the team_id of the event.
+	RoutingAudienceTeam RoutingAudienceType = "team"
+	// RoutingAudienceRole is the users of the tenant holding a role.
+	RoutingAudienceRole RoutingAudienceType = "role"
+	// RoutingAudienceUser is a single user of the tenant.
+	RoutingAudienceUser RoutingAudienceType = "user"
+	// RoutingAudienceRecipients is the addresses the event carries, such as
+	// the new user of a user created event, the users a comment mentions or
+	// the to list of a send command.
+	RoutingAudienceRecipients RoutingAudienceType = "recipients"
+)
+
+// ValidRoutingAudienceTypes are the audiences rules notify.
+var ValidRoutingAudienceTypes = map[RoutingAudienceType]bool{
+	RoutingAudienceOwner:      true,
+	RoutingAudienceTeam:       true,
+	RoutingAudienceRole:       true,
+	RoutingAudienceUser:       true,
+	RoutingAudienceRecipients: true,
+}
+
+// RoutableChannels are the channels routing rules send on. Chat posts are
+// made by chat integrations instead.
+var RoutableChannels = map[NotificationChannel]bool{
+	ChannelEmail:    true,
+	ChannelSMS:      true,
+	ChannelPush:     true,
+	ChannelInApp:    true,
+	ChannelWhatsApp: true,
+}
+
+// RoutingOperator compares a field of event data with the value of a filter.
+type RoutingOperator string
+
+const (
+	RoutingOperatorEq     RoutingOperator = "eq"
+	RoutingOperatorNeq    RoutingOperator = "neq"
+	RoutingOperatorIn     RoutingOperator = "in"
+	RoutingOperatorGt     RoutingOperator = "gt"
+	RoutingOperatorGte    RoutingOperator = "gte"
+	RoutingOperatorLt     RoutingOperator = "lt"
+	RoutingOperatorLte    RoutingOperator = "lte"
+	RoutingOperatorExists RoutingOperator = "exists"
+)
+
+// routingFieldPattern matches the dotted paths filters look fields up by.
+var routingFieldPattern = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*(\.[a-zA-Z_][a-zA-Z0-9_]*)*$`)
+
+// RoutingFilter narrows the events a rule routes by a field of their data.
+// Field is a dotted path into the data, such as source or customer.tier.
+// Numbers compare numerically and everything else as text; in takes a list
+// of values and exists an optional boolean, true when left out.
+type RoutingFilter struct {
+	Field    string          `json:"field"`
+	Operator RoutingOperator `json:"operator"`
+	Value    interface{}     `json:"value,omitempty"`
+}
+
+// Validate checks that the filter can be evaluated.
+func (f RoutingFilter) Validate() error {
+	if !routingFieldPattern.MatchString(f.Field) || len(f.Field) > 100 {
+		return NewValidationError("filters", "filter field must be a dotted path of at most 100 characters", "INVALID_FILTER")
+	}
+	switch f.Operator {
+	case RoutingOperatorEq, RoutingOperatorNeq:
+		if f.Value == nil {
+			return NewValidationError("filters", "filter on "+f.Field+" needs a value", "INVALID_FILTER")
+		}
+	case RoutingOperatorIn:
+		if values, ok := f.Value.([]interface{}); !ok || len(values) == 0 {
+			return NewValidationError("filters", "filter on "+f.Field+" needs a list of values", "INVALID_FILTER")
+		}
+	case RoutingOperatorGt, RoutingOperatorGte, RoutingOperatorLt, RoutingOperatorLte:
+		if _, ok := routingNumber(f.Value); !ok {
+			return NewValidationError("filters", "filter on "+f.Field+" needs a number", "INVALID_FILTER")
+		}
+	case RoutingOperatorExists:
+		if _, ok := f.Value.(bool); f.Value != nil && !ok {
+			return NewValidationError("filters", "exists filter on "+f.Field+" takes true or false", "INVALID_FILTER")
+		}
+	default:
+		return NewValidationError("filters", "unknown filter operator "+string(f.Operator), "INVALID_FILTER")
+	}
+	return nil
+}
+
+// Matches reports whether event data passes the filter. A missing field
+// only passes neq and exists false.
+func (f RoutingFilter) Matches(data map[string]interface{}) bool {
+	value, ok := routingField(data, f.Field)
+	switch f.Operator {
+	case RoutingOperatorExists:
+		want, isBool := f.Value.(bool)
+		return ok == (want || !isBool)
+	case RoutingOperatorEq:
+		return ok && routingEqual(value, f.Value)
+	case RoutingOperatorNeq:
+		return !ok || !routingEqual(value, f.Value)
+	case RoutingOperatorIn:
+		values, _ := f.Value.([]interface{})
+		for _, v := range values {
+			if ok && routingEqual(value, v) {
+				return true
+			}
+		}
+		return false
+	}
+
+	actual, okActual := routingNumber(value)
+	limit, okLimit := routingNumber(f.Value)
+	if !ok || !okActual || !okLimit {
+		return false
+	}
+	switch f.Operator {
+	case RoutingOperatorGt:
+		return actual > limit
+	case RoutingOperatorGte:
+		return actual >= limit
+	case RoutingOperatorLt:
+		return actual < limit
+	case RoutingOperatorLte:
+		return actual <= limit
+	}
+	return false
+}
+
+// routingField looks up a dotted path in event data. A null value counts as
+// missing.
+func routingField(data map[string]interface{}, path string) (interface{}, bool) {
+	var value interface{} = data
+	for _, key := range strings.Split(path, ".") {
+		fields, ok := value.(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+		if value, ok = fields[key]; !ok {
+			return nil, false
+		}
+	}
+	return value, value != nil
+}
+
+// routingEqual compares two values numerically when both are numbers and
+// as text otherwise.
+func routingEqual(a, b interface{}) bool {
+	x, okA := routingNumber(a)
+	y, okB := routingNumber(b)
+	if okA && okB {
+		return x == y
+	}
+	return fmt.Sprint(a) == fmt.Sprint(b)
+}
+
+// routingNumber returns a numeric value as float64, which JSON decodes
+// numbers to.
+func routingNumber(value interface{}) (float64, bool) {
+	switch v := value.(type) {
+	case float64:
+		return v, true
+	case float32:
+		return float64(v), true
+	case int:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	case interface{ Float64() (float64, error) }:
+		f, err := v.Float64()
+		return f, err == nil
+	}
+	return 0, false
+}
+
+// RoutingAudience is who a rule notifies. Value names the team, role or
+// user; owner and recipients audiences take theirs from the event.
+type RoutingAudience struct {
+	Type  RoutingAudienceType `json:"type"`
+	Value string              `json:"value,omitempty"`
+}
+
+// Validate checks that the audience can be resolved.
+func (a RoutingAudience) Validate() error {
+	if !ValidRoutingAudienceTypes[a.Type] {
+		return NewValidationError("audiences", "audience must be owner, team, role, user or recipients", "INVALID_AUDIENCE")
+	}
+	switch a.Type {
+	case RoutingAudienceTeam:
+		if _, err := uuid.Parse(a.Value); a.Value != "" && err != nil {
+			return NewValidationError("audiences", "team audience must name a team ID", "INVALID_AUDIENCE")
+		}
+	case RoutingAudienceUser:
+		if _, err := uuid.Parse(a.Value); err != nil {
+			return NewValidationError("audiences", "user audience must name a user ID", "INVALID_AUDIENCE")
+		}
+	case RoutingAudienceRole:
+		if a.Value == "" || len(a.Value) > 100 {
+			return NewValidationError("audiences", "role audience must name a role", "INVALID_AUDIENCE")
+		}
+	default:
+		if a.Value != "" {
+			return NewValidationError("audiences", string(a.Type)+" audience is taken from the event and takes no value", "INVALID_AUDIENCE")
+		}
+	}
+	return nil
+}
+
+// Resolve fills in the audience of an event: the owner, or the team when
+// the audience names none. It reports false when the event has no one to
+// notify, such as an unassigned lead for an owner audience.
+func (a RoutingAudience) Resolve(event *RoutedEvent) (RoutingAudience, bool) {
+	switch a.Type {
+	case RoutingAudienceOwner:
+		a.Value = event.String("owner_id")
+		return a, a.Value != ""
+	case RoutingAudienceTeam:
+		if a.Value == "" {
+			a.Value = event.String("team_id")
+		}
+		return a, a.Value != ""
+	}
+	return a, true
+}
+
+// RoutingRule routes an event type of a tenant to audiences on channels.
+// Events of a type the tenant has rules for, enabled or not, are routed by
+// those rules only; other events follow the default rules.
+type RoutingRule struct {
+	ID        uuid.UUID             `json:"id"`
+	TenantID  uuid.UUID             `json:"tenant_id"`
+	Name      string                `json:"name"`
+	EventType string                `json:"event_type"`
+	Filters   []RoutingFilter       `json:"filters"`
+	Audiences []RoutingAudience     `json:"audiences"`
+	Channels  []NotificationChannel `json:"channels"`
+	// TemplateCode is the template sent on channels without a template of
+	// their own; empty sends the content the event carries.
+	TemplateCode     string                         `json:"template_code,omitempty"`
+	ChannelTemplates map[NotificationChannel]string `json:"channel_templates,omitempty"`
+	// Priority orders the rules of an event type, lowest first.
+	Priority  int        `json:"priority"`
+	Enabled   bool       `json:"enabled"`
+	CreatedBy *uuid.UUID `json:"created_by,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at"`
+}
+
+// NewRoutingRule creates an enabled rule.
+func NewRoutingRule(tenantID uuid.UUID, name, eventType string, audiences []RoutingAudience, channels []NotificationChannel, createdBy *uuid.UUID) (*RoutingRule, error) {
+	if tenantID == uuid.Nil {
+		return nil, ErrTenantIDRequired
+	}
+	now := time.Now().UTC()
+	rule := &RoutingRule{
+		ID:               uuid.New(),
+		TenantID:         tenantID,
+		Filters:          []RoutingFilter{},
+		ChannelTemplates: make(map[NotificationChannel]string),
+		Enabled:          true,
+		CreatedBy:        createdBy,
+		CreatedAt:        now,
+		UpdatedAt:        now,
+	}
+	if err := rule.Rename(name); err != nil {
+		return nil, err
+	}
+	if err := rule.SetEventType(eventType); err != nil {
+		return nil, err
+	}
+	if err := rule.SetAudiences(audiences); err != nil {
+		return nil, err
+	}
+	if err := rule.SetChannels(channels); err != nil {
+		return nil, err
+	}
+	return rule, nil
+}
+
+// Rename sets the name of the rule.
+func (r *RoutingRule) Rename(name string) error {
+	name = strings.TrimSpace(name)
+	if name == "" || len(name) > 100 {
+		return NewValidationError("name", "name is required and must be at most 100 characters", "INVALID_NAME")
+	}
+	r.Name = name
+	r.UpdatedAt = time.Now().UTC()
+	return nil
+}
+
+// SetEventType sets the event type the rule routes, which must be one the
+// service subscribes to.
+func (r *RoutingRule) SetEventType(eventType string) error {
+	eventType = strings.ToLower(strings.TrimSpace(eventType))
+	if !IsRoutableEventType(eventType) {
+		return NewValidationError("event_type", "unknown event type "+eventType, "INVALID_EVENT_TYPE")
+	}
+	r.EventType = eventType
+	r.UpdatedAt = time.Now().UTC()
+	return nil
+}
+
+// SetFilters sets the filters every routed event passes.
+func (r *RoutingRule) SetFilters(filters []RoutingFilter) error {
+	if len(filters) > MaxRoutingFilters {
+		return NewValidationError("filters", fmt.Sprintf("at most %d filters are allowed", MaxRoutingFilters), "INVALID_FILTER")
+	}
+	for _, filter := range filters {
+		if err := filter.Validate(); err != nil {
+			return err
+		}
+	}
+	r.Filters = append([]RoutingFilter{}, filters...)
+	r.UpdatedAt = time.Now().UTC()
+	return nil
+}
+
+// SetAudiences sets who the rule notifies.
+func (r *RoutingRule) SetAudiences(audiences []RoutingAudience) error {
+	if len(audiences) == 0 || len(audiences) > MaxRoutingAudiences {
+		return NewValidationError("audiences", fmt.Sprintf("between 1 and %d audiences are required", MaxRoutingAudiences), "INVALID_AUDIENCE")
+	}
+	seen := make(map[RoutingAudience]bool, len(audiences))
+	unique := make([]RoutingAudience, 0, len(audiences))
+	for _, audience := range audiences {
+		audience.Value = strings.TrimSpace(audience.Value)
+		if err := audience.Validate(); err != nil {
+			return err
+		}
+		if !seen[audience] {
+			seen[audience] = true
+			unique = append(unique, audience)
+		}
+	}
+	r.Audiences = unique
+	r.UpdatedAt = time.Now().UTC()
+	return nil
+}
+
+// SetChannels sets the channels the rule sends on.
+func (r *RoutingRule) SetChannels(channels []NotificationChannel) error {
+	if len(channels) == 0 {
+		return NewValidationError("channels", "at least one channel is required", "INVALID_CHANNELS")
+	}
+	seen := make(map[NotificationChannel]bool, len(channels))
+	unique := make([]NotificationChannel, 0, len(channels))
+	for _, channel := range channels {
+		if !RoutableChannels[channel] {
+			return NewValidationError("channels", "channel "+string(channel)+" cannot be routed to", "INVALID_CHANNELS")
+		}
+		if !seen[channel] {
+			seen[channel] = true
+			unique = append(unique, channel)
+		}
+	}
+	r.Channels = unique
+	r.UpdatedAt = time.Now().UTC()
+	return nil
+}
+
+// SetTemplates sets the template the rule sends and the templates of
+// channels that send another one.
+func (r *RoutingRule) SetTemplates(code string, channelTemplates map[NotificationChannel]string) error {
+	code = strings.TrimSpace(code)
+	if code != "" && !isRoutingTemplateCode(code) {
+		return NewValidationError("template_code", "template code must contain only lowercase letters, numbers, and underscores", "INVALID_TEMPLATE_CODE")
+	}
+	templates := make(map[NotificationChannel]string, len(channelTemplates))
+	for channel, channelCode := range channelTemplates {
+		if !RoutableChannels[channel] {
+			return NewValidationError("channel_templates", "channel "+string(channel)+" cannot be routed to", "INVALID_CHANNELS")
+		}
+		if channelCode = strings.TrimSpace(channelCode); !isRoutingTemplateCode(channelCode) {
+			return NewValidationError("channel_templates", "template code of "+string(channel)+" must contain only lowercase letters, numbers, and underscores", "INVALID_TEMPLATE_CODE")
+		}
+		templates[channel] = channelCode
+	}
+	r.TemplateCode = code
+	r.ChannelTemplates = templates
+	r.UpdatedAt = time.Now().UTC()
+	return nil
+}
+
+// SetPriority sets where the rule is evaluated among the rules of its event
+// type.
+func (r *RoutingRule) SetPriority(priority int) {
+	r.Priority = priority
+	r.UpdatedAt = time.Now().UTC()
+}
+
+// Enable resumes routing.
+func (r *RoutingRule) Enable() {
+	r.Enabled = true
+	r.UpdatedAt = time.Now().UTC()
+}
+
+// Disable stops routing without removing the rule. The default rules of its
+// event type stay replaced, so disabling a tenant's only rule for an event
+// type silences it.
+func (r *RoutingRule) Disable() {
+	r.Enabled = false
+	r.UpdatedAt = time.Now().UTC()
+}
+
+// Matches reports whether the rule routes an event.
+func (r *RoutingRule) Matches(event *RoutedEvent) bool {
+	if !r.Enabled || r.EventType != event.Type {
+		return false
+	}
+	if r.TenantID != uuid.Nil && r.TenantID != event.TenantID {
+		return false
+	}
+	for _, filter := range r.Filters {
+		if !filter.Matches(event.Data) {
+			return false
+		}
+	}
+	return true
+}
+
+// Template returns the code of the template the rule sends on a channel.
+func (r *RoutingRule) Template(channel NotificationChannel) string {
+	if code, ok := r.ChannelTemplates[channel]; ok {
+		return code
+	}
+	return r.TemplateCode
+}
+
+func isRoutingTemplateCode(code string) bool {
+	return len(code) <= 100 && isValidTemplateCode(code)
+}
+
+// IsRoutableEventType reports whether routing rules can be written for an
+// event type.
+func IsRoutableEventType(eventType string) bool {
+	for _, t := range RoutableEventTypes {
+		if t == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+// DefaultRoutingRules returns the rules of tenants without rules of their
+// own for an event type. Insights and overdue invoices are only posted to
+// chat integrations, so they have none.
+func DefaultRoutingRules() []*RoutingRule {
+	rule := func(name, eventType string, audience RoutingAudience, channel NotificationChannel, template string) *RoutingRule {
+		return &RoutingRule{
+			Name:         name,
+			EventType:    eventType,
+			Filters:      []RoutingFilter{},
+			Audiences:    []RoutingAudience{audience},
+			Channels:     []NotificationChannel{channel},
+			TemplateCode: template,
+			Enabled:      true,
+		}
+	}
+	recipients := RoutingAudience{Type: RoutingAudienceRecipients}
+	return []*RoutingRule{
+		rule("Welcome email", RoutingEventUserCreated, recipients, ChannelEmail, TemplateCodeWelcomeEmail),
+		rule("New lead", RoutingEventLeadCreated, RoutingAudience{Type: RoutingAudienceRole, Value: "sales_rep"}, ChannelInApp, "lead_created"),
+		rule("Deal confirmation", RoutingEventOpportunityWon, recipients, ChannelEmail, "deal_confirmation"),
+		rule("Follow-up survey", RoutingEventOpportunityLost, recipients, ChannelEmail, "follow_up_survey"),
+		rule("Mention", RoutingEventCommentMentioned, recipients, ChannelInApp, "comment_mentioned"),
+		rule("Mention email", RoutingEventCommentMentioned, recipients, ChannelEmail, "comment_mentioned"),
+		rule("Email", RoutingEventEmailSend, recipients, ChannelEmail, ""),
+		rule("SMS", RoutingEventSMSSend, recipients, ChannelSMS, ""),
+		rule("SLO burn rate alert", RoutingEventSLOBurnRateAlert, recipients, ChannelEmail, ""),
+	}
+}
+
+// RoutedEvent is an event routing rules are evaluated against.
+type RoutedEvent struct {
+	ID          string
+	TenantID    uuid.UUID
+	Type        string
+	AggregateID string
+	Data        map[string]interface{}
+}
+
+// String returns a text field of the event data.
+func (e *RoutedEvent) String(key string) string {
+	value, _ := e.Data[key].(string)
+	return value
+}
+
+// RoutedNotification is a notification a rule sends for an event: one per
+// audience and channel.
+type RoutedNotification struct {
+	// RuleID is nil for the default rules.
+	RuleID       uuid.UUID
+	RuleName     string
+	Audience     RoutingAudience
+	Channel      NotificationChannel
+	TemplateCode string
+}
+
+// RoutingTable holds the routing rules of every tenant for routing events
+// without querying the database. It is not changed once built; WithTenant
+// builds a table with the rules of a tenant replaced.
+type RoutingTable struct {
+	tenants  map[uuid.UUID]map[string][]*RoutingRule
+	defaults map[string][]*RoutingRule
+}
+
+// NewRoutingTable builds the routing table of rules.
+func NewRoutingTable(rules []*RoutingRule) *RoutingTable {
+	table := &RoutingTable{
+		tenants:  make(map[uuid.UUID]map[string][]*RoutingRule),
+		defaults: groupRoutingRules(DefaultRoutingRules()),
+	}
+	byTenant := make(map[uuid.UUID][]*RoutingRule)
+	for _, rule := range rules {
+		byTenant[rule.TenantID] = append(byTenant[rule.TenantID], rule)
+	}
+	for tenantID, tenantRules := range byTenant {
+		table.tenants[tenantID] = groupRoutingRules(tenantRules)
+	}
+	return table
+}
+
+// WithTenant returns a table with the rules of a tenant replaced.
+func (t *RoutingTable) WithTenant(tenantID uuid.UUID, rules []*RoutingRule) *RoutingTable {
+	table := &RoutingTable{
+		tenants:  make(map[uuid.UUID]map[string][]*RoutingRule, len(t.tenants)+1),
+		defaults: t.defaults,
+	}
+	for id, tenantRules := range t.tenants {
+		table.tenants[id] = tenantRules
+	}
+	delete(table.tenants, tenantID)
+	if len(rules) > 0 {
+		table.tenants[tenantID] = groupRoutingRules(rules)
+	}
+	return table
+}
+
+// Rules returns the rules an event of a tenant is routed by, in order.
+func (t *RoutingTable) Rules(tenantID uuid.UUID, eventType string) []*RoutingRule {
+	if rules, ok := t.tenants[tenantID][eventType]; ok {
+		return rules
+	}
+	return t.defaults[eventType]
+}
+
+// Route returns the notifications the matching rules send for an event.
+// An audience several rules notify on the same channel is notified once,
+// with the template of the first rule.
+func (t *RoutingTable) Route(event *RoutedEvent) []RoutedNotification {
+	type key struct {
+		audience RoutingAudience
+		channel  NotificationChannel
+	}
+	seen := make(map[key]bool)
+	var routed []RoutedNotification
+	for _, rule := range t.Rules(event.TenantID, event.Type) {
+		if !rule.Matches(event) {
+			continue
+		}
+		for _, audience := range rule.Audiences {
+			resolved, ok := audience.Resolve(event)
+			if !ok {
+				continue
+			}
+			for _, channel := range rule.Channels {
+				k := key{audience: resolved, channel: channel}
+				if seen[k] {
+					continue
+				}
+				seen[k] = true
+				routed = append(routed, RoutedNotification{
+					RuleID:       rule.ID,
+					RuleName:     rule.Name,
+					Audience:     resolved,
+					Channel:      channel,
+					TemplateCode: rule.Template(channel),
+				})
+			}
+		}
+	}
+	return routed
+}
+
+// groupRoutingRules groups rules by event type, ordered by priority and
+// then by age.
+func groupRoutingRules(rules []*RoutingRule) map[string][]*RoutingRule {
+	grouped := make(map[string][]*RoutingRule)
+	for _, rule := range rules {
+		grouped[rule.EventType] = append(grouped[rule.EventType], rule)
+	}
+	for _, group := range grouped {
+		sort.SliceStable(group, func(i, j int) bool {
+			if group[i].Priority != group[j].Priority {
+				return group[i].Priority < group[j].Priority
+			}
+			return group[i].CreatedAt.Before(group[j].CreatedAt)
+		})
+	}
+	return grouped
+}
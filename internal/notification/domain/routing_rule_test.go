@@ -0,0 +1,181 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestNewRoutingRule_Validation(t *testing.T) {
+	tenantID := uuid.New()
+	owner := []RoutingAudience{{Type: RoutingAudienceOwner}}
+	email := []NotificationChannel{ChannelEmail}
+	tests := []struct {
+		name      string
+		eventType string
+		audiences []RoutingAudience
+		channels  []NotificationChannel
+		wantErr   bool
+	}{
+		{"valid", RoutingEventLeadCreated, owner, email, false},
+		{"unknown event type", "sales.lead.deleted", owner, email, true},
+		{"no audience", RoutingEventLeadCreated, nil, email, true},
+		{"unknown audience", RoutingEventLeadCreated, []RoutingAudience{{Type: "everyone"}}, email, true},
+		{"role without name", RoutingEventLeadCreated, []RoutingAudience{{Type: RoutingAudienceRole}}, email, true},
+		{"user without ID", RoutingEventLeadCreated, []RoutingAudience{{Type: RoutingAudienceUser, Value: "aminah"}}, email, true},
+		{"owner with value", RoutingEventLeadCreated, []RoutingAudience{{Type: RoutingAudienceOwner, Value: uuid.NewString()}}, email, true},
+		{"no channel", RoutingEventLeadCreated, owner, nil, true},
+		{"chat channel", RoutingEventLeadCreated, owner, []NotificationChannel{ChannelSlack}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewRoutingRule(tenantID, "Rule", tt.eventType, tt.audiences, tt.channels, nil)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("NewRoutingRule() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+
+	if _, err := NewRoutingRule(uuid.Nil, "Rule", RoutingEventLeadCreated, owner, email, nil); err != ErrTenantIDRequired {
+		t.Errorf("NewRoutingRule() error = %v, want %v", err, ErrTenantIDRequired)
+	}
+}
+
+func TestRoutingFilter_Matches(t *testing.T) {
+	data := map[string]interface{}{
+		"source":          "website",
+		"estimated_value": float64(250000),
+		"customer":        map[string]interface{}{"tier": "gold"},
+		"owner_id":        nil,
+	}
+	tests := []struct {
+		name   string
+		filter RoutingFilter
+		want   bool
+	}{
+		{"eq", RoutingFilter{Field: "source", Operator: RoutingOperatorEq, Value: "website"}, true},
+		{"eq other", RoutingFilter{Field: "source", Operator: RoutingOperatorEq, Value: "referral"}, false},
+		{"eq number", RoutingFilter{Field: "estimated_value", Operator: RoutingOperatorEq, Value: 250000}, true},
+		{"neq", RoutingFilter{Field: "source", Operator: RoutingOperatorNeq, Value: "referral"}, true},
+		{"neq missing", RoutingFilter{Field: "campaign", Operator: RoutingOperatorNeq, Value: "spring"}, true},
+		{"in", RoutingFilter{Field: "source", Operator: RoutingOperatorIn, Value: []interface{}{"referral", "website"}}, true},
+		{"in other", RoutingFilter{Field: "source", Operator: RoutingOperatorIn, Value: []interface{}{"referral"}}, false},
+		{"gte", RoutingFilter{Field: "estimated_value", Operator: RoutingOperatorGte, Value: float64(250000)}, true},
+		{"gt", RoutingFilter{Field: "estimated_value", Operator: RoutingOperatorGt, Value: float64(250000)}, false},
+		{"lt text", RoutingFilter{Field: "source", Operator: RoutingOperatorLt, Value: float64(1)}, false},
+		{"nested", RoutingFilter{Field: "customer.tier", Operator: RoutingOperatorEq, Value: "gold"}, true},
+		{"exists", RoutingFilter{Field: "customer.tier", Operator: RoutingOperatorExists}, true},
+		{"exists null", RoutingFilter{Field: "owner_id", Operator: RoutingOperatorExists}, false},
+		{"not exists", RoutingFilter{Field: "campaign", Operator: RoutingOperatorExists, Value: false}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.filter.Validate(); err != nil {
+				t.Fatalf("Validate() error = %v", err)
+			}
+			if got := tt.filter.Matches(data); got != tt.want {
+				t.Errorf("Matches() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	invalid := []RoutingFilter{
+		{Field: "", Operator: RoutingOperatorEq, Value: "x"},
+		{Field: "source", Operator: "like", Value: "web%"},
+		{Field: "source", Operator: RoutingOperatorIn, Value: "website"},
+		{Field: "estimated_value", Operator: RoutingOperatorGt, Value: "100"},
+	}
+	for _, filter := range invalid {
+		if err := filter.Validate(); err == nil {
+			t.Errorf("Validate() of %+v should fail", filter)
+		}
+	}
+}
+
+func TestRoutingTable_Route(t *testing.T) {
+	tenantID := uuid.New()
+	ownerID := uuid.NewString()
+	teamID := uuid.NewString()
+
+	bigLeads, err := NewRoutingRule(tenantID, "Big leads", RoutingEventLeadCreated,
+		[]RoutingAudience{{Type: RoutingAudienceOwner}, {Type: RoutingAudienceTeam, Value: teamID}},
+		[]NotificationChannel{ChannelInApp, ChannelEmail}, nil)
+	if err != nil {
+		t.Fatalf("NewRoutingRule() error = %v", err)
+	}
+	bigLeads.SetFilters([]RoutingFilter{{Field: "estimated_value", Operator: RoutingOperatorGte, Value: float64(100000)}})
+	bigLeads.SetTemplates("big_lead", map[NotificationChannel]string{ChannelInApp: "big_lead_alert"})
+
+	allLeads, _ := NewRoutingRule(tenantID, "All leads", RoutingEventLeadCreated,
+		[]RoutingAudience{{Type: RoutingAudienceOwner}}, []NotificationChannel{ChannelInApp}, nil)
+	allLeads.SetPriority(10)
+
+	table := NewRoutingTable([]*RoutingRule{allLeads, bigLeads})
+
+	routed := table.Route(&RoutedEvent{
+		TenantID: tenantID,
+		Type:     RoutingEventLeadCreated,
+		Data:     map[string]interface{}{"owner_id": ownerID, "estimated_value": float64(150000)},
+	})
+	if len(routed) != 4 {
+		t.Fatalf("Route() = %d notifications, want 4: %+v", len(routed), routed)
+	}
+	if routed[0].RuleID != bigLeads.ID || routed[0].Audience.Value != ownerID || routed[0].TemplateCode != "big_lead_alert" {
+		t.Errorf("first notification = %+v, want the owner in app with the big lead alert", routed[0])
+	}
+	if routed[1].Channel != ChannelEmail || routed[1].TemplateCode != "big_lead" {
+		t.Errorf("second notification = %+v, want the owner by email with the big lead template", routed[1])
+	}
+
+	// The owner is notified in app once, by the first rule
+	for _, notification := range routed {
+		if notification.RuleID == allLeads.ID {
+			t.Errorf("duplicate notification %+v", notification)
+		}
+	}
+
+	// Small unassigned leads reach no one
+	routed = table.Route(&RoutedEvent{
+		TenantID: tenantID,
+		Type:     RoutingEventLeadCreated,
+		Data:     map[string]interface{}{"estimated_value": float64(500)},
+	})
+	if len(routed) != 0 {
+		t.Errorf("Route() = %+v, want no notifications", routed)
+	}
+}
+
+func TestRoutingTable_Defaults(t *testing.T) {
+	tenantID := uuid.New()
+	event := &RoutedEvent{TenantID: tenantID, Type: RoutingEventUserCreated, Data: map[string]interface{}{"email": "aminah@example.com"}}
+
+	table := NewRoutingTable(nil)
+	routed := table.Route(event)
+	if len(routed) != 1 || routed[0].Channel != ChannelEmail || routed[0].TemplateCode != TemplateCodeWelcomeEmail {
+		t.Fatalf("Route() = %+v, want the welcome email", routed)
+	}
+	if len(table.Route(&RoutedEvent{TenantID: tenantID, Type: RoutingEventInsightLeadVolumeDrop})) != 0 {
+		t.Error("insights should only be posted to chat by default")
+	}
+
+	// A disabled rule of the tenant replaces the defaults
+	rule, _ := NewRoutingRule(tenantID, "No welcome", RoutingEventUserCreated,
+		[]RoutingAudience{{Type: RoutingAudienceRecipients}}, []NotificationChannel{ChannelEmail}, nil)
+	rule.Disable()
+	table = table.WithTenant(tenantID, []*RoutingRule{rule})
+	if routed := table.Route(event); len(routed) != 0 {
+		t.Errorf("Route() = %+v, want the welcome email silenced", routed)
+	}
+
+	// Other tenants keep the defaults
+	event.TenantID = uuid.New()
+	if routed := table.Route(event); len(routed) != 1 {
+		t.Errorf("Route() = %+v, want the welcome email for other tenants", routed)
+	}
+
+	table = table.WithTenant(tenantID, nil)
+	event.TenantID = tenantID
+	if routed := table.Route(event); len(routed) != 1 {
+		t.Errorf("Route() = %+v, want the defaults back once the tenant's rules are removed", routed)
+	}
+}
@@ -0,0 +1,202 @@
+package domain
+
+import (
+	"math"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// MaxStatsBuckets is the largest number of buckets a stats series can have.
+const MaxStatsBuckets = 750
+
+// StatsInterval is the width of the time buckets in a stats series.
+type StatsInterval string
+
+const (
+	StatsIntervalHour  StatsInterval = "hour"
+	StatsIntervalDay   StatsInterval = "day"
+	StatsIntervalWeek  StatsInterval = "week"
+	StatsIntervalMonth StatsInterval = "month"
+)
+
+// ParseStatsInterval parses a string into a StatsInterval.
+func ParseStatsInterval(s string) (StatsInterval, error) {
+	interval := StatsInterval(strings.ToLower(strings.TrimSpace(s)))
+	switch interval {
+	case StatsIntervalHour, StatsIntervalDay, StatsIntervalWeek, StatsIntervalMonth:
+		return interval, nil
+	}
+	return "", ErrInvalidStatsInterval
+}
+
+// DefaultStatsInterval picks an interval that gives a readable chart for the
+// period from start to end: hours up to two days, days up to three months
+// and weeks beyond that.
+func DefaultStatsInterval(start, end time.Time) StatsInterval {
+	switch period := end.Sub(start); {
+	case period <= 48*time.Hour:
+		return StatsIntervalHour
+	case period <= 92*24*time.Hour:
+		return StatsIntervalDay
+	}
+	return StatsIntervalWeek
+}
+
+// Truncate returns the start of the bucket t falls in, in UTC. Weeks start
+// on Monday.
+func (i StatsInterval) Truncate(t time.Time) time.Time {
+	t = t.UTC()
+	switch i {
+	case StatsIntervalHour:
+		return t.Truncate(time.Hour)
+	case StatsIntervalWeek:
+		day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+		return day.AddDate(0, 0, -((int(day.Weekday()) + 6) % 7))
+	case StatsIntervalMonth:
+		return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+	}
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// Next returns the start of the bucket after the one starting at t.
+func (i StatsInterval) Next(t time.Time) time.Time {
+	switch i {
+	case StatsIntervalHour:
+		return t.Add(time.Hour)
+	case StatsIntervalWeek:
+		return t.AddDate(0, 0, 7)
+	case StatsIntervalMonth:
+		return t.AddDate(0, 1, 0)
+	}
+	return t.AddDate(0, 0, 1)
+}
+
+// BucketCount returns the number of buckets the period from start to end
+// spans.
+func (i StatsInterval) BucketCount(start, end time.Time) int {
+	count := 0
+	for t := i.Truncate(start); t.Before(end); t = i.Next(t) {
+		count++
+		if count > MaxStatsBuckets {
+			break
+		}
+	}
+	return count
+}
+
+// NotificationStatsQuery selects the notifications statistics are computed
+// over: those of a tenant created from StartDate up to EndDate.
+type NotificationStatsQuery struct {
+	TenantID  uuid.UUID
+	StartDate time.Time
+	EndDate   time.Time
+	Channel   *NotificationChannel // Only this channel when set
+	Interval  StatsInterval        // Width of the Series buckets
+}
+
+// DeliveryCounts counts notifications by how far their delivery got.
+type DeliveryCounts struct {
+	Total      int64 `json:"total"`
+	Sent       int64 `json:"sent"`      // Accepted by the provider
+	Delivered  int64 `json:"delivered"` // Reported delivered by the provider
+	Opened     int64 `json:"opened"`    // Opened at least once
+	Clicked    int64 `json:"clicked"`   // Clicked at least once
+	Bounced    int64 `json:"bounced"`
+	Complained int64 `json:"complained"`
+	Failed     int64 `json:"failed"`
+}
+
+// Count adds a notification to the counts.
+func (c *DeliveryCounts) Count(n *Notification) {
+	c.Total++
+	if n.SentAt != nil {
+		c.Sent++
+	}
+	if n.DeliveredAt != nil || n.Status == StatusDelivered || n.Status == StatusRead {
+		c.Delivered++
+	}
+	if n.OpenCount > 0 {
+		c.Opened++
+	}
+	if n.ClickCount > 0 {
+		c.Clicked++
+	}
+	switch n.Status {
+	case StatusBounced:
+		c.Bounced++
+	case StatusComplained:
+		c.Complained++
+	case StatusFailed:
+		c.Failed++
+	}
+}
+
+// Add adds other to the counts.
+func (c *DeliveryCounts) Add(other DeliveryCounts) {
+	c.Total += other.Total
+	c.Sent += other.Sent
+	c.Delivered += other.Delivered
+	c.Opened += other.Opened
+	c.Clicked += other.Clicked
+	c.Bounced += other.Bounced
+	c.Complained += other.Complained
+	c.Failed += other.Failed
+}
+
+// DeliveryRate is the percentage of sent notifications that were delivered.
+func (c DeliveryCounts) DeliveryRate() float64 { return percentage(c.Delivered, c.Sent) }
+
+// OpenRate is the percentage of delivered notifications that were opened.
+func (c DeliveryCounts) OpenRate() float64 { return percentage(c.Opened, c.Delivered) }
+
+// ClickRate is the percentage of delivered notifications that were clicked.
+func (c DeliveryCounts) ClickRate() float64 { return percentage(c.Clicked, c.Delivered) }
+
+// BounceRate is the percentage of sent notifications that bounced.
+func (c DeliveryCounts) BounceRate() float64 { return percentage(c.Bounced, c.Sent) }
+
+// FailureRate is the percentage of all notifications that failed.
+func (c DeliveryCounts) FailureRate() float64 { return percentage(c.Failed, c.Total) }
+
+// percentage returns part as a percentage of whole, rounded to two decimals.
+func percentage(part, whole int64) float64 {
+	if whole == 0 {
+		return 0
+	}
+	return math.Round(float64(part)/float64(whole)*10000) / 100
+}
+
+// TemplateDeliveryCounts holds the delivery counts of one template.
+type TemplateDeliveryCounts struct {
+	TemplateID   uuid.UUID `json:"template_id"`
+	TemplateName string    `json:"template_name"`
+	DeliveryCounts
+}
+
+// StatsBucket holds the delivery counts of the notifications created in one
+// interval of a stats series.
+type StatsBucket struct {
+	Start time.Time `json:"start"`
+	DeliveryCounts
+}
+
+// FillStatsSeries returns one bucket for every interval from start to end, in
+// order, taking the counts from buckets and zero counts for the intervals
+// without notifications.
+func FillStatsSeries(buckets []StatsBucket, start, end time.Time, interval StatsInterval) []StatsBucket {
+	byStart := make(map[time.Time]DeliveryCounts, len(buckets))
+	for _, b := range buckets {
+		key := interval.Truncate(b.Start)
+		counts := byStart[key]
+		counts.Add(b.DeliveryCounts)
+		byStart[key] = counts
+	}
+
+	series := make([]StatsBucket, 0, interval.BucketCount(start, end))
+	for t := interval.Truncate(start); t.Before(end) && len(series) < MaxStatsBuckets; t = interval.Next(t) {
+		series = append(series, StatsBucket{Start: t, DeliveryCounts: byStart[t]})
+	}
+	return series
+}
@@ -0,0 +1,100 @@
+package domain
+
+import (
+	"testing"
+	"time"
+)
+
+func TestStatsInterval_Truncate(t *testing.T) {
+	// A Wednesday afternoon
+	ts := time.Date(2024, 6, 12, 15, 42, 10, 0, time.UTC)
+
+	tests := []struct {
+		interval StatsInterval
+		want     time.Time
+	}{
+		{StatsIntervalHour, time.Date(2024, 6, 12, 15, 0, 0, 0, time.UTC)},
+		{StatsIntervalDay, time.Date(2024, 6, 12, 0, 0, 0, 0, time.UTC)},
+		{StatsIntervalWeek, time.Date(2024, 6, 10, 0, 0, 0, 0, time.UTC)},
+		{StatsIntervalMonth, time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.interval), func(t *testing.T) {
+			if got := tt.interval.Truncate(ts); !got.Equal(tt.want) {
+				t.Errorf("Truncate() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	sunday := time.Date(2024, 6, 16, 23, 0, 0, 0, time.UTC)
+	if got := StatsIntervalWeek.Truncate(sunday); got.Day() != 10 {
+		t.Errorf("Truncate(Sunday) = %v, want Monday 10 June", got)
+	}
+}
+
+func TestDefaultStatsInterval(t *testing.T) {
+	start := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+
+	if got := DefaultStatsInterval(start, start.Add(24*time.Hour)); got != StatsIntervalHour {
+		t.Errorf("one day = %v, want hour", got)
+	}
+	if got := DefaultStatsInterval(start, start.AddDate(0, 0, 30)); got != StatsIntervalDay {
+		t.Errorf("30 days = %v, want day", got)
+	}
+	if got := DefaultStatsInterval(start, start.AddDate(1, 0, 0)); got != StatsIntervalWeek {
+		t.Errorf("one year = %v, want week", got)
+	}
+}
+
+func TestDeliveryCounts_Count(t *testing.T) {
+	var counts DeliveryCounts
+	now := time.Now().UTC()
+
+	delivered := createTestNotification(t, ChannelEmail)
+	delivered.Status = StatusDelivered
+	delivered.SentAt = &now
+	delivered.DeliveredAt = &now
+	delivered.OpenCount = 3
+	counts.Count(delivered)
+
+	bounced := createTestNotification(t, ChannelEmail)
+	bounced.Status = StatusBounced
+	bounced.SentAt = &now
+	counts.Count(bounced)
+
+	failed := createTestNotification(t, ChannelSMS)
+	failed.Status = StatusFailed
+	counts.Count(failed)
+
+	want := DeliveryCounts{Total: 3, Sent: 2, Delivered: 1, Opened: 1, Bounced: 1, Failed: 1}
+	if counts != want {
+		t.Errorf("counts = %+v, want %+v", counts, want)
+	}
+	if counts.DeliveryRate() != 50 || counts.OpenRate() != 100 || counts.FailureRate() != 33.33 {
+		t.Errorf("rates = %v %v %v, want 50 100 33.33", counts.DeliveryRate(), counts.OpenRate(), counts.FailureRate())
+	}
+	if (DeliveryCounts{}).DeliveryRate() != 0 {
+		t.Error("expected a zero rate without notifications")
+	}
+}
+
+func TestFillStatsSeries(t *testing.T) {
+	start := time.Date(2024, 6, 1, 9, 0, 0, 0, time.UTC)
+	end := time.Date(2024, 6, 4, 0, 0, 0, 0, time.UTC)
+	buckets := []StatsBucket{
+		{Start: time.Date(2024, 6, 3, 0, 0, 0, 0, time.UTC), DeliveryCounts: DeliveryCounts{Total: 4, Sent: 4}},
+	}
+
+	series := FillStatsSeries(buckets, start, end, StatsIntervalDay)
+
+	if len(series) != 3 {
+		t.Fatalf("expected 3 daily buckets, got %d", len(series))
+	}
+	if !series[0].Start.Equal(time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)) || series[0].Total != 0 {
+		t.Errorf("first bucket = %+v, want an empty 1 June bucket", series[0])
+	}
+	if series[2].Total != 4 {
+		t.Errorf("last bucket total = %d, want 4", series[2].Total)
+	}
+}
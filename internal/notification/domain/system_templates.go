@@ -0,0 +1,131 @@
+package domain
+
+import (
+	"github.com/google/uuid"
+)
+
+// Codes of the system templates every tenant starts with.
+const (
+	TemplateCodeWelcomeEmail  = "welcome_email"
+	TemplateCodePasswordReset = "password_reset"
+)
+
+// SystemTemplateTag tags the templates seeded for a tenant, so they can be
+// told apart from the templates the tenant created.
+const SystemTemplateTag = "system"
+
+// systemTemplate is the default content of a system template, in English and
+// Malay.
+type systemTemplate struct {
+	code             string
+	name             string
+	description      string
+	notificationType NotificationType
+	variables        []TemplateVariable
+	english          EmailTemplateContent
+	malay            EmailTemplateContent
+}
+
+// companyName renders the tenant's name when it is branded, or the product's.
+const companyName = `{{with .branding}}{{.tenant_name}}{{else}}Kilang Desa Murni Batik{{end}}`
+
+var systemTemplates = []systemTemplate{
+	{
+		code:             TemplateCodeWelcomeEmail,
+		name:             "Welcome email",
+		description:      "Sent to new users when their account is created",
+		notificationType: TypeWelcome,
+		variables: []TemplateVariable{
+			{Name: "first_name", Type: "string", Description: "The user's first name", Example: "Aminah"},
+			{Name: "login_url", Type: "string", Description: "Where the user signs in", Example: "https://crm.example.com/login"},
+		},
+		english: EmailTemplateContent{
+			Subject: "Welcome to " + companyName,
+			Body: `Hi {{.first_name | default "there"}},
+
+Your account on ` + companyName + ` is ready.{{with .login_url}} Sign in at {{.}} to get started.{{end}}
+
+If you did not expect this email, please contact your administrator.`,
+			PreviewText: "Your account is ready",
+		},
+		malay: EmailTemplateContent{
+			Subject: "Selamat datang ke " + companyName,
+			Body: `Hai {{.first_name | default "di sana"}},
+
+Akaun anda di ` + companyName + ` telah sedia.{{with .login_url}} Log masuk di {{.}} untuk bermula.{{end}}
+
+Jika anda tidak menjangkakan e-mel ini, sila hubungi pentadbir anda.`,
+			PreviewText: "Akaun anda telah sedia",
+		},
+	},
+	{
+		code:             TemplateCodePasswordReset,
+		name:             "Password reset",
+		description:      "Sent when a user asks to reset their password",
+		notificationType: TypePasswordReset,
+		variables: []TemplateVariable{
+			{Name: "first_name", Type: "string", Description: "The user's first name", Example: "Aminah"},
+			{Name: "reset_url", Type: "string", Required: true, Description: "Link that resets the password", Example: "https://crm.example.com/reset-password?token=abc123"},
+			{Name: "expires_in_minutes", Type: "number", Description: "How long the link is valid", Example: 60},
+		},
+		english: EmailTemplateContent{
+			Subject: "Reset your " + companyName + " password",
+			Body: `Hi {{.first_name | default "there"}},
+
+We received a request to reset your password. Open the link below to choose a new one:
+
+{{.reset_url}}
+{{with .expires_in_minutes}}
+The link expires in {{.}} minutes.{{end}}
+If you did not ask to reset your password, you can ignore this email; your password stays the same.`,
+			PreviewText: "Choose a new password",
+		},
+		malay: EmailTemplateContent{
+			Subject: "Tetapkan semula kata laluan " + companyName + " anda",
+			Body: `Hai {{.first_name | default "di sana"}},
+
+Kami menerima permintaan untuk menetapkan semula kata laluan anda. Buka pautan di bawah untuk memilih kata laluan baharu:
+
+{{.reset_url}}
+{{with .expires_in_minutes}}
+Pautan ini tamat tempoh dalam {{.}} minit.{{end}}
+Jika anda tidak meminta untuk menetapkan semula kata laluan, abaikan e-mel ini; kata laluan anda kekal sama.`,
+			PreviewText: "Pilih kata laluan baharu",
+		},
+	},
+}
+
+// NewSystemTemplates returns the system templates of a tenant: the default
+// email templates of its welcome and password reset emails, in English with
+// a Malay localization. The localization is stored for "ms", so recipients
+// with any Malay locale, such as ms-MY, get it. Tenants may edit them like
+// any template.
+func NewSystemTemplates(tenantID uuid.UUID) ([]*NotificationTemplate, error) {
+	templates := make([]*NotificationTemplate, 0, len(systemTemplates))
+	for _, st := range systemTemplates {
+		t, err := NewNotificationTemplate(tenantID, st.code, st.name, st.notificationType)
+		if err != nil {
+			return nil, err
+		}
+		t.Description = st.description
+		t.Category = "account"
+
+		english, malay := st.english, st.malay
+		if err := t.SetEmailTemplate(&english); err != nil {
+			return nil, err
+		}
+		if err := t.AddLocalization("ms", &TemplateLocalization{EmailTemplate: &malay}); err != nil {
+			return nil, err
+		}
+		for _, variable := range st.variables {
+			if err := t.AddVariable(variable); err != nil {
+				return nil, err
+			}
+		}
+		t.SetAsDefault()
+		t.AddTag(SystemTemplateTag)
+
+		templates = append(templates, t)
+	}
+	return templates, nil
+}
@@ -0,0 +1,74 @@
+package domain
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestNewSystemTemplates(t *testing.T) {
+	tenantID := uuid.New()
+	templates, err := NewSystemTemplates(tenantID)
+	if err != nil {
+		t.Fatalf("NewSystemTemplates() error = %v", err)
+	}
+	if len(templates) != 2 || templates[0].Code != TemplateCodeWelcomeEmail || templates[1].Code != TemplateCodePasswordReset {
+		t.Fatalf("NewSystemTemplates() = %d templates, want the welcome and password reset emails", len(templates))
+	}
+
+	for _, template := range templates {
+		if template.TenantID != tenantID || !template.HasTag(SystemTemplateTag) || template.GetLocalization("ms") == nil {
+			t.Errorf("template %s = %+v, want a tagged template of the tenant with a Malay localization", template.Code, template)
+		}
+		if err := template.Validate(); err != nil {
+			t.Errorf("template %s Validate() error = %v", template.Code, err)
+		}
+	}
+}
+
+func TestNewSystemTemplates_Render(t *testing.T) {
+	templates, err := NewSystemTemplates(uuid.New())
+	if err != nil {
+		t.Fatalf("NewSystemTemplates() error = %v", err)
+	}
+	welcome, reset := templates[0], templates[1]
+
+	// Optional variables can be left out
+	email, err := welcome.RenderEmail(map[string]interface{}{}, "en")
+	if err != nil {
+		t.Fatalf("RenderEmail() error = %v", err)
+	}
+	if email.Subject != "Welcome to Kilang Desa Murni Batik" || !strings.HasPrefix(email.Body, "Hi there,") || strings.Contains(email.Body, "Sign in") {
+		t.Errorf("welcome email = %q / %q", email.Subject, email.Body)
+	}
+
+	// Branded tenants are named, and Malay recipients get the Malay content
+	email, err = welcome.RenderEmail(map[string]interface{}{
+		"first_name": "Aisyah",
+		"login_url":  "https://crm.batikwarisan.my/login",
+		"branding":   map[string]interface{}{"tenant_name": "Batik Warisan"},
+	}, "ms-MY")
+	if err != nil {
+		t.Fatalf("RenderEmail() error = %v", err)
+	}
+	if email.Locale != "ms" || email.Subject != "Selamat datang ke Batik Warisan" ||
+		!strings.Contains(email.Body, "Log masuk di https://crm.batikwarisan.my/login") {
+		t.Errorf("welcome email = %s %q / %q", email.Locale, email.Subject, email.Body)
+	}
+
+	// The reset link is required
+	if _, err := reset.RenderEmail(map[string]interface{}{"first_name": "Aisyah"}, "en"); err == nil {
+		t.Error("RenderEmail() without reset_url succeeded, want an error")
+	}
+	email, err = reset.RenderEmail(map[string]interface{}{
+		"reset_url":          "https://crm.example.com/reset?token=t",
+		"expires_in_minutes": 60,
+	}, "en")
+	if err != nil {
+		t.Fatalf("RenderEmail() error = %v", err)
+	}
+	if !strings.Contains(email.Body, "https://crm.example.com/reset?token=t") || !strings.Contains(email.Body, "The link expires in 60 minutes.") {
+		t.Errorf("password reset email = %q", email.Body)
+	}
+}
@@ -2,13 +2,13 @@
 package domain
 
 import (
-	"bytes"
 	"regexp"
 	"strings"
-	"text/template"
 	"time"
 
 	"github.com/google/uuid"
+
+	"github.com/kilang-desa-murni/crm/pkg/render"
 )
 
 // NotificationTemplate represents a reusable notification template.
@@ -44,6 +44,9 @@ type NotificationTemplate struct {
 	// Rendering settings
 	RenderEngine string `json:"render_engine" db:"render_engine"` // "go-template", "handlebars", etc.
 
+	// Delivery settings
+	RetryPolicy *RetryPolicy `json:"retry_policy,omitempty" db:"-"` // Overrides the channel's retry policy
+
 	// Template status
 	IsActive    bool `json:"is_active" db:"is_active"`
 	IsDefault   bool `json:"is_default" db:"is_default"` // Default template for type
@@ -276,6 +279,20 @@ func (t *NotificationTemplate) SetInAppTemplate(content *InAppTemplateContent) e
 	return nil
 }
 
+// SetRetryPolicy overrides the retry policy of notifications sent from the
+// template. A nil policy restores the channel's policy.
+func (t *NotificationTemplate) SetRetryPolicy(policy *RetryPolicy) error {
+	if policy != nil {
+		if err := policy.Validate(); err != nil {
+			return err
+		}
+	}
+
+	t.RetryPolicy = policy
+	t.MarkUpdated()
+	return nil
+}
+
 // hasChannel checks if the template supports a channel.
 func (t *NotificationTemplate) hasChannel(channel NotificationChannel) bool {
 	for _, c := range t.Channels {
@@ -356,34 +373,89 @@ func (t *NotificationTemplate) ValidateVariables(data map[string]interface{}) er
 	return nil
 }
 
+// SampleVariables prepares data for a preview render. Variables missing from
+// data fall back to their default value and then to their example, so a
+// template can be previewed without real data. It fails when a required
+// variable has no value or a value does not match the variable's type.
+func (t *NotificationTemplate) SampleVariables(data map[string]interface{}) (map[string]interface{}, error) {
+	sample := make(map[string]interface{}, len(data)+len(t.Variables))
+	for k, v := range data {
+		sample[k] = v
+	}
+
+	var missing, invalid []string
+	for _, v := range t.Variables {
+		value, ok := sample[v.Name]
+		if !ok || value == nil {
+			switch {
+			case v.DefaultValue != nil:
+				value = v.DefaultValue
+			case v.Example != nil:
+				value = v.Example
+			default:
+				if v.Required {
+					missing = append(missing, v.Name)
+				}
+				continue
+			}
+			sample[v.Name] = value
+		}
+		if !v.Accepts(value) {
+			invalid = append(invalid, v.Name)
+		}
+	}
+
+	if len(missing) > 0 || len(invalid) > 0 {
+		return nil, NewTemplateError(t.Code, "invalid template variables", "INVALID_VARIABLES").
+			WithMissingVars(missing).
+			WithInvalidVars(invalid)
+	}
+	return sample, nil
+}
+
 // ============================================================================
 // Localization Methods
 // ============================================================================
 
-// AddLocalization adds a localized version of the template.
+// AddLocalization adds a localized version of the template. The locale is
+// normalized, so "ms_my" is stored as "ms-MY".
 func (t *NotificationTemplate) AddLocalization(locale string, localization *TemplateLocalization) error {
 	if locale == "" {
 		return NewValidationError("locale", "locale is required", "REQUIRED")
 	}
-	localization.Locale = locale
+	normalized, err := NormalizeLocale(locale)
+	if err != nil {
+		return err
+	}
+	if normalized == t.DefaultLocale {
+		return NewValidationError("locale", "the default locale uses the template's own content", "DEFAULT_LOCALE")
+	}
+
+	localization.Locale = normalized
 	if t.Localizations == nil {
 		t.Localizations = make(map[string]*TemplateLocalization)
 	}
-	t.Localizations[locale] = localization
+	t.Localizations[normalized] = localization
 	t.MarkUpdated()
 	return nil
 }
 
 // RemoveLocalization removes a localized version.
 func (t *NotificationTemplate) RemoveLocalization(locale string) {
+	if normalized, err := NormalizeLocale(locale); err == nil {
+		locale = normalized
+	}
 	if t.Localizations != nil {
 		delete(t.Localizations, locale)
 		t.MarkUpdated()
 	}
 }
 
-// GetLocalization gets a localized version or falls back to default.
+// GetLocalization gets the localized version for exactly locale, or nil.
 func (t *NotificationTemplate) GetLocalization(locale string) *TemplateLocalization {
+	if normalized, err := NormalizeLocale(locale); err == nil {
+		locale = normalized
+	}
 	if t.Localizations != nil {
 		if loc, ok := t.Localizations[locale]; ok {
 			return loc
@@ -392,6 +464,37 @@ func (t *NotificationTemplate) GetLocalization(locale string) *TemplateLocalizat
 	return nil
 }
 
+// SetDefaultLocale sets the locale of the template's own content. A locale that
+// already has a localization cannot become the default.
+func (t *NotificationTemplate) SetDefaultLocale(locale string) error {
+	normalized, err := NormalizeLocale(locale)
+	if err != nil {
+		return err
+	}
+	if t.Localizations[normalized] != nil {
+		return NewValidationError("default_locale", "remove the "+normalized+" localization before making it the default", "LOCALIZATION_EXISTS")
+	}
+	t.DefaultLocale = normalized
+	t.MarkUpdated()
+	return nil
+}
+
+// resolveLocalization returns the localization to render for locale, following
+// the fallback chain: "ms-MY" is served by an "ms-MY" localization, then by
+// "ms". Only localizations for which has returns true are considered. It
+// returns nil when the default content should be used.
+func (t *NotificationTemplate) resolveLocalization(locale string, has func(*TemplateLocalization) bool) *TemplateLocalization {
+	for _, candidate := range LocaleFallbacks(locale) {
+		if candidate == t.DefaultLocale {
+			return nil
+		}
+		if loc := t.Localizations[candidate]; loc != nil && has(loc) {
+			return loc
+		}
+	}
+	return nil
+}
+
 // ============================================================================
 // Status Methods
 // ============================================================================
@@ -532,14 +635,13 @@ func (t *NotificationTemplate) HasTag(tag string) bool {
 func (t *NotificationTemplate) RenderEmail(data map[string]interface{}, locale string) (*RenderedEmail, error) {
 	var content *EmailTemplateContent
 
-	// Try localized content first
-	if locale != "" && locale != t.DefaultLocale {
-		if loc := t.GetLocalization(locale); loc != nil && loc.EmailTemplate != nil {
-			content = loc.EmailTemplate
-		}
+	// Try localized content first, then fall back to default
+	renderedLocale := t.DefaultLocale
+	if loc := t.resolveLocalization(locale, func(l *TemplateLocalization) bool { return l.EmailTemplate != nil }); loc != nil {
+		content = loc.EmailTemplate
+		renderedLocale = loc.Locale
 	}
 
-	// Fall back to default
 	if content == nil {
 		content = t.EmailTemplate
 	}
@@ -566,7 +668,7 @@ func (t *NotificationTemplate) RenderEmail(data map[string]interface{}, locale s
 
 	var htmlBody string
 	if content.HTMLBody != "" {
-		htmlBody, err = renderText(content.HTMLBody, data)
+		htmlBody, err = render.HTML(content.HTMLBody, data)
 		if err != nil {
 			return nil, NewTemplateError(t.Code, "failed to render HTML body", "RENDER_ERROR").WithInvalidVars([]string{"html_body"})
 		}
@@ -583,6 +685,7 @@ func (t *NotificationTemplate) RenderEmail(data map[string]interface{}, locale s
 		Headers:     content.Headers,
 		TrackOpens:  content.TrackOpens,
 		TrackClicks: content.TrackClicks,
+		Locale:      renderedLocale,
 	}, nil
 }
 
@@ -590,11 +693,11 @@ func (t *NotificationTemplate) RenderEmail(data map[string]interface{}, locale s
 func (t *NotificationTemplate) RenderSMS(data map[string]interface{}, locale string) (*RenderedSMS, error) {
 	var content *SMSTemplateContent
 
-	// Try localized content first
-	if locale != "" && locale != t.DefaultLocale {
-		if loc := t.GetLocalization(locale); loc != nil && loc.SMSTemplate != nil {
-			content = loc.SMSTemplate
-		}
+	// Try localized content first, then fall back to default
+	renderedLocale := t.DefaultLocale
+	if loc := t.resolveLocalization(locale, func(l *TemplateLocalization) bool { return l.SMSTemplate != nil }); loc != nil {
+		content = loc.SMSTemplate
+		renderedLocale = loc.Locale
 	}
 
 	if content == nil {
@@ -617,6 +720,7 @@ func (t *NotificationTemplate) RenderSMS(data map[string]interface{}, locale str
 	return &RenderedSMS{
 		Body:     body,
 		SenderID: content.SenderID,
+		Locale:   renderedLocale,
 	}, nil
 }
 
@@ -624,10 +728,11 @@ func (t *NotificationTemplate) RenderSMS(data map[string]interface{}, locale str
 func (t *NotificationTemplate) RenderPush(data map[string]interface{}, locale string) (*RenderedPush, error) {
 	var content *PushTemplateContent
 
-	if locale != "" && locale != t.DefaultLocale {
-		if loc := t.GetLocalization(locale); loc != nil && loc.PushTemplate != nil {
-			content = loc.PushTemplate
-		}
+	// Try localized content first, then fall back to default
+	renderedLocale := t.DefaultLocale
+	if loc := t.resolveLocalization(locale, func(l *TemplateLocalization) bool { return l.PushTemplate != nil }); loc != nil {
+		content = loc.PushTemplate
+		renderedLocale = loc.Locale
 	}
 
 	if content == nil {
@@ -663,6 +768,7 @@ func (t *NotificationTemplate) RenderPush(data map[string]interface{}, locale st
 		TTL:         content.TTL,
 		CollapseKey: content.CollapseKey,
 		Priority:    content.Priority,
+		Locale:      renderedLocale,
 	}, nil
 }
 
@@ -670,10 +776,11 @@ func (t *NotificationTemplate) RenderPush(data map[string]interface{}, locale st
 func (t *NotificationTemplate) RenderInApp(data map[string]interface{}, locale string) (*RenderedInApp, error) {
 	var content *InAppTemplateContent
 
-	if locale != "" && locale != t.DefaultLocale {
-		if loc := t.GetLocalization(locale); loc != nil && loc.InAppTemplate != nil {
-			content = loc.InAppTemplate
-		}
+	// Try localized content first, then fall back to default
+	renderedLocale := t.DefaultLocale
+	if loc := t.resolveLocalization(locale, func(l *TemplateLocalization) bool { return l.InAppTemplate != nil }); loc != nil {
+		content = loc.InAppTemplate
+		renderedLocale = loc.Locale
 	}
 
 	if content == nil {
@@ -709,26 +816,14 @@ func (t *NotificationTemplate) RenderInApp(data map[string]interface{}, locale s
 		Duration:    content.Duration,
 		Data:        content.Data,
 		Dismissable: content.Dismissable,
+		Locale:      renderedLocale,
 	}, nil
 }
 
-// renderText renders a Go template string with data.
+// renderText renders a Go template string with data. Rendering is sandboxed
+// and supports the helpers of the render package, such as currency and date.
 func renderText(templateStr string, data map[string]interface{}) (string, error) {
-	if templateStr == "" {
-		return "", nil
-	}
-
-	tmpl, err := template.New("notification").Parse(templateStr)
-	if err != nil {
-		return "", err
-	}
-
-	var buf bytes.Buffer
-	if err := tmpl.Execute(&buf, data); err != nil {
-		return "", err
-	}
-
-	return buf.String(), nil
+	return render.Text(templateStr, data)
 }
 
 // ============================================================================
@@ -747,12 +842,14 @@ type RenderedEmail struct {
 	Headers     map[string]string
 	TrackOpens  bool
 	TrackClicks bool
+	Locale      string // Locale of the content that was rendered
 }
 
 // RenderedSMS represents rendered SMS content.
 type RenderedSMS struct {
 	Body     string
 	SenderID string
+	Locale   string
 }
 
 // RenderedPush represents rendered push notification content.
@@ -767,6 +864,7 @@ type RenderedPush struct {
 	TTL         int
 	CollapseKey string
 	Priority    string
+	Locale      string
 }
 
 // RenderedInApp represents rendered in-app notification content.
@@ -781,6 +879,7 @@ type RenderedInApp struct {
 	Duration    int
 	Data        map[string]interface{}
 	Dismissable bool
+	Locale      string
 }
 
 // ============================================================================
@@ -817,6 +916,11 @@ func (t *NotificationTemplate) Validate() error {
 	if t.hasChannel(ChannelInApp) && t.InAppTemplate == nil {
 		errs.AddField("in_app_template", "in-app template content is required", "REQUIRED")
 	}
+	if t.RetryPolicy != nil {
+		if err := t.RetryPolicy.Validate(); err != nil {
+			errs.AddField("retry_policy", err.Error(), "INVALID_VALUE")
+		}
+	}
 
 	if errs.HasErrors() {
 		return errs
@@ -841,6 +945,7 @@ func (t *NotificationTemplate) Clone(newCode, newName string) (*NotificationTemp
 	clone.Variables = append([]TemplateVariable{}, t.Variables...)
 	clone.DefaultLocale = t.DefaultLocale
 	clone.RenderEngine = t.RenderEngine
+	clone.RetryPolicy = t.RetryPolicy
 	clone.Tags = append([]string{}, t.Tags...)
 
 	// Copy localizations
@@ -0,0 +1,359 @@
+package domain
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// TemplateRevisionReason describes why a template revision was recorded.
+type TemplateRevisionReason string
+
+const (
+	TemplateRevisionCreated    TemplateRevisionReason = "created"
+	TemplateRevisionUpdated    TemplateRevisionReason = "updated"
+	TemplateRevisionPublished  TemplateRevisionReason = "published"
+	TemplateRevisionRolledBack TemplateRevisionReason = "rolled_back"
+)
+
+// TemplateRevision is an immutable snapshot of a template's content. A
+// revision is recorded every time the template changes, so edits never lose
+// the previous content and any revision can be restored.
+type TemplateRevision struct {
+	ID         uuid.UUID `json:"id" db:"id"`
+	TenantID   uuid.UUID `json:"tenant_id" db:"tenant_id"`
+	TemplateID uuid.UUID `json:"template_id" db:"template_id"`
+	Number     int       `json:"number" db:"number"` // Sequential per template, starting at 1
+
+	// Snapshot of the template at the time of the revision
+	Name            string                           `json:"name" db:"name"`
+	Description     string                           `json:"description,omitempty" db:"description"`
+	Category        string                           `json:"category,omitempty" db:"category"`
+	Channels        []NotificationChannel            `json:"channels" db:"-"`
+	EmailTemplate   *EmailTemplateContent            `json:"email_template,omitempty" db:"-"`
+	SMSTemplate     *SMSTemplateContent              `json:"sms_template,omitempty" db:"-"`
+	PushTemplate    *PushTemplateContent             `json:"push_template,omitempty" db:"-"`
+	InAppTemplate   *InAppTemplateContent            `json:"in_app_template,omitempty" db:"-"`
+	Variables       []TemplateVariable               `json:"variables" db:"-"`
+	DefaultLocale   string                           `json:"default_locale" db:"default_locale"`
+	Localizations   map[string]*TemplateLocalization `json:"localizations,omitempty" db:"-"`
+	TemplateVersion int                              `json:"template_version" db:"template_version"` // Published version at the time
+	PublishedAt     *time.Time                       `json:"published_at,omitempty" db:"published_at"`
+
+	Reason        TemplateRevisionReason `json:"reason" db:"reason"`
+	ChangeSummary string                 `json:"change_summary,omitempty" db:"change_summary"`
+	CreatedBy     *uuid.UUID             `json:"created_by,omitempty" db:"created_by"`
+	CreatedAt     time.Time              `json:"created_at" db:"created_at"`
+}
+
+// NewTemplateRevision records the current content of a template as revision
+// number. The content is copied, so later edits to the template do not change
+// the revision.
+func NewTemplateRevision(t *NotificationTemplate, number int, reason TemplateRevisionReason, changeSummary string) *TemplateRevision {
+	createdBy := t.UpdatedBy
+	if createdBy == nil {
+		createdBy = t.CreatedBy
+	}
+
+	return &TemplateRevision{
+		ID:              uuid.New(),
+		TenantID:        t.TenantID,
+		TemplateID:      t.ID,
+		Number:          number,
+		Name:            t.Name,
+		Description:     t.Description,
+		Category:        t.Category,
+		Channels:        append([]NotificationChannel{}, t.Channels...),
+		EmailTemplate:   copyEmailContent(t.EmailTemplate),
+		SMSTemplate:     copySMSContent(t.SMSTemplate),
+		PushTemplate:    copyPushContent(t.PushTemplate),
+		InAppTemplate:   copyInAppContent(t.InAppTemplate),
+		Variables:       append([]TemplateVariable{}, t.Variables...),
+		DefaultLocale:   t.DefaultLocale,
+		Localizations:   copyLocalizations(t.Localizations),
+		TemplateVersion: t.TemplateVersion,
+		PublishedAt:     t.PublishedAt,
+		Reason:          reason,
+		ChangeSummary:   changeSummary,
+		CreatedBy:       createdBy,
+		CreatedAt:       time.Now().UTC(),
+	}
+}
+
+// RollbackTo restores the content of a previous revision. The template keeps
+// its identity, code and published version; the rollback is itself a change
+// that should be recorded as a new revision.
+func (t *NotificationTemplate) RollbackTo(rev *TemplateRevision, updatedBy *uuid.UUID) error {
+	if rev == nil {
+		return ErrTemplateRevisionNotFound
+	}
+	if rev.TemplateID != t.ID || rev.TenantID != t.TenantID {
+		return ErrTemplateRevisionMismatch
+	}
+
+	t.Name = rev.Name
+	t.Description = rev.Description
+	t.Category = rev.Category
+	t.Channels = append([]NotificationChannel{}, rev.Channels...)
+	t.EmailTemplate = copyEmailContent(rev.EmailTemplate)
+	t.SMSTemplate = copySMSContent(rev.SMSTemplate)
+	t.PushTemplate = copyPushContent(rev.PushTemplate)
+	t.InAppTemplate = copyInAppContent(rev.InAppTemplate)
+	t.Variables = append([]TemplateVariable{}, rev.Variables...)
+	if rev.DefaultLocale != "" {
+		t.DefaultLocale = rev.DefaultLocale
+	}
+	t.Localizations = copyLocalizations(rev.Localizations)
+	t.UpdatedBy = updatedBy
+	t.MarkUpdated()
+	t.IncrementVersion()
+	t.AddDomainEvent(NewTemplateUpdatedEvent(t))
+
+	return nil
+}
+
+func copyEmailContent(content *EmailTemplateContent) *EmailTemplateContent {
+	if content == nil {
+		return nil
+	}
+	c := *content
+	if content.Headers != nil {
+		c.Headers = make(map[string]string, len(content.Headers))
+		for k, v := range content.Headers {
+			c.Headers[k] = v
+		}
+	}
+	c.Attachments = append([]TemplateAttachment(nil), content.Attachments...)
+	return &c
+}
+
+func copySMSContent(content *SMSTemplateContent) *SMSTemplateContent {
+	if content == nil {
+		return nil
+	}
+	c := *content
+	return &c
+}
+
+func copyPushContent(content *PushTemplateContent) *PushTemplateContent {
+	if content == nil {
+		return nil
+	}
+	c := *content
+	c.Actions = append([]PushAction(nil), content.Actions...)
+	c.Data = copyData(content.Data)
+	if content.Badge != nil {
+		badge := *content.Badge
+		c.Badge = &badge
+	}
+	return &c
+}
+
+func copyInAppContent(content *InAppTemplateContent) *InAppTemplateContent {
+	if content == nil {
+		return nil
+	}
+	c := *content
+	c.Data = copyData(content.Data)
+	return &c
+}
+
+func copyLocalizations(localizations map[string]*TemplateLocalization) map[string]*TemplateLocalization {
+	c := make(map[string]*TemplateLocalization, len(localizations))
+	for locale, loc := range localizations {
+		c[locale] = &TemplateLocalization{
+			Locale:        loc.Locale,
+			EmailTemplate: copyEmailContent(loc.EmailTemplate),
+			SMSTemplate:   copySMSContent(loc.SMSTemplate),
+			PushTemplate:  copyPushContent(loc.PushTemplate),
+			InAppTemplate: copyInAppContent(loc.InAppTemplate),
+		}
+	}
+	return c
+}
+
+func copyData(data map[string]interface{}) map[string]interface{} {
+	if data == nil {
+		return nil
+	}
+	c := make(map[string]interface{}, len(data))
+	for k, v := range data {
+		c[k] = v
+	}
+	return c
+}
+
+// ============================================================================
+// Revision Diff
+// ============================================================================
+
+// DiffOp is the kind of change of a diff line.
+type DiffOp string
+
+const (
+	DiffEqual  DiffOp = "equal"
+	DiffInsert DiffOp = "insert"
+	DiffDelete DiffOp = "delete"
+)
+
+// DiffLine is a single line of a line-based diff.
+type DiffLine struct {
+	Op   DiffOp `json:"op"`
+	Text string `json:"text"`
+}
+
+// TemplateFieldDiff describes how one content field, such as the email
+// subject, differs between two revisions.
+type TemplateFieldDiff struct {
+	Field string     `json:"field"`
+	From  string     `json:"from"`
+	To    string     `json:"to"`
+	Lines []DiffLine `json:"lines"`
+}
+
+// DiffTemplateRevisions compares the subject and body fields of two revisions
+// and returns the fields that differ, in channel order. Localized fields
+// follow the default ones, by locale, and are prefixed with it (e.g.
+// "ms.email.subject").
+func DiffTemplateRevisions(from, to *TemplateRevision) []TemplateFieldDiff {
+	fromFields := from.contentFields()
+	toFields := to.contentFields()
+
+	diffs := make([]TemplateFieldDiff, 0)
+	for _, field := range revisionFieldNames(from, to) {
+		a, b := fromFields[field], toFields[field]
+		if a == b {
+			continue
+		}
+		diffs = append(diffs, TemplateFieldDiff{
+			Field: field,
+			From:  a,
+			To:    b,
+			Lines: DiffLines(a, b),
+		})
+	}
+	return diffs
+}
+
+// revisionFieldNames lists the fields to compare between two revisions: the
+// default content fields, then those of every locale either revision has.
+func revisionFieldNames(from, to *TemplateRevision) []string {
+	locales := make(map[string]bool)
+	for locale := range from.Localizations {
+		locales[locale] = true
+	}
+	for locale := range to.Localizations {
+		locales[locale] = true
+	}
+	sorted := make([]string, 0, len(locales))
+	for locale := range locales {
+		sorted = append(sorted, locale)
+	}
+	sort.Strings(sorted)
+
+	names := append([]string{}, templateContentFields...)
+	for _, locale := range sorted {
+		for _, field := range templateContentFields {
+			names = append(names, locale+"."+field)
+		}
+	}
+	return names
+}
+
+// templateContentFields lists the fields compared by DiffTemplateRevisions.
+var templateContentFields = []string{
+	"email.subject",
+	"email.body",
+	"email.html_body",
+	"sms.body",
+	"push.title",
+	"push.body",
+	"in_app.title",
+	"in_app.body",
+}
+
+// contentFields returns the revision's subject and body fields by name.
+func (r *TemplateRevision) contentFields() map[string]string {
+	fields := make(map[string]string)
+	addContentFields(fields, "", r.EmailTemplate, r.SMSTemplate, r.PushTemplate, r.InAppTemplate)
+	for locale, loc := range r.Localizations {
+		addContentFields(fields, locale+".", loc.EmailTemplate, loc.SMSTemplate, loc.PushTemplate, loc.InAppTemplate)
+	}
+	return fields
+}
+
+func addContentFields(fields map[string]string, prefix string, email *EmailTemplateContent, sms *SMSTemplateContent, push *PushTemplateContent, inApp *InAppTemplateContent) {
+	if email != nil {
+		fields[prefix+"email.subject"] = email.Subject
+		fields[prefix+"email.body"] = email.Body
+		fields[prefix+"email.html_body"] = email.HTMLBody
+	}
+	if sms != nil {
+		fields[prefix+"sms.body"] = sms.Body
+	}
+	if push != nil {
+		fields[prefix+"push.title"] = push.Title
+		fields[prefix+"push.body"] = push.Body
+	}
+	if inApp != nil {
+		fields[prefix+"in_app.title"] = inApp.Title
+		fields[prefix+"in_app.body"] = inApp.Body
+	}
+}
+
+// DiffLines returns a line-based diff turning a into b, using the longest
+// common subsequence of their lines.
+func DiffLines(a, b string) []DiffLine {
+	x := splitLines(a)
+	y := splitLines(b)
+
+	// lcs[i][j] is the length of the longest common subsequence of x[i:] and y[j:].
+	lcs := make([][]int, len(x)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(y)+1)
+	}
+	for i := len(x) - 1; i >= 0; i-- {
+		for j := len(y) - 1; j >= 0; j-- {
+			if x[i] == y[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else if lcs[i+1][j] >= lcs[i][j+1] {
+				lcs[i][j] = lcs[i+1][j]
+			} else {
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+
+	lines := make([]DiffLine, 0, len(x)+len(y))
+	i, j := 0, 0
+	for i < len(x) && j < len(y) {
+		switch {
+		case x[i] == y[j]:
+			lines = append(lines, DiffLine{Op: DiffEqual, Text: x[i]})
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			lines = append(lines, DiffLine{Op: DiffDelete, Text: x[i]})
+			i++
+		default:
+			lines = append(lines, DiffLine{Op: DiffInsert, Text: y[j]})
+			j++
+		}
+	}
+	for ; i < len(x); i++ {
+		lines = append(lines, DiffLine{Op: DiffDelete, Text: x[i]})
+	}
+	for ; j < len(y); j++ {
+		lines = append(lines, DiffLine{Op: DiffInsert, Text: y[j]})
+	}
+	return lines
+}
+
+func splitLines(s string) []string {
+	if s == "" {
+		return nil
+	}
+	return strings.Split(strings.TrimSuffix(s, "\n"), "\n")
+}
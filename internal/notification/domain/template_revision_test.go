@@ -0,0 +1,133 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestNewTemplateRevision_CopiesContent(t *testing.T) {
+	tmpl := createTestTemplate(t)
+	if err := tmpl.SetEmailTemplate(&EmailTemplateContent{
+		Subject: "Welcome, {{.Name}}!",
+		Body:    "Hello {{.Name}}",
+		Headers: map[string]string{"X-Campaign": "welcome"},
+	}); err != nil {
+		t.Fatalf("SetEmailTemplate() error = %v", err)
+	}
+
+	rev := NewTemplateRevision(tmpl, 1, TemplateRevisionCreated, "Template created")
+
+	tmpl.EmailTemplate.Subject = "Changed"
+	tmpl.EmailTemplate.Headers["X-Campaign"] = "changed"
+
+	if rev.Number != 1 || rev.TemplateID != tmpl.ID || rev.TenantID != tmpl.TenantID {
+		t.Errorf("unexpected revision identity: %+v", rev)
+	}
+	if rev.EmailTemplate.Subject != "Welcome, {{.Name}}!" {
+		t.Errorf("revision subject = %q, should not change with the template", rev.EmailTemplate.Subject)
+	}
+	if rev.EmailTemplate.Headers["X-Campaign"] != "welcome" {
+		t.Errorf("revision headers = %v, should not change with the template", rev.EmailTemplate.Headers)
+	}
+}
+
+func TestNotificationTemplate_RollbackTo(t *testing.T) {
+	tmpl := createTestTemplate(t)
+	if err := tmpl.SetSMSTemplate(&SMSTemplateContent{Body: "Your code is {{.Code}}"}); err != nil {
+		t.Fatalf("SetSMSTemplate() error = %v", err)
+	}
+	rev := NewTemplateRevision(tmpl, 1, TemplateRevisionCreated, "")
+
+	tmpl.Name = "Renamed"
+	tmpl.SMSTemplate.Body = "Code: {{.Code}}"
+	tmpl.ClearDomainEvents()
+	version := tmpl.Version
+
+	updatedBy := uuid.New()
+	if err := tmpl.RollbackTo(rev, &updatedBy); err != nil {
+		t.Fatalf("RollbackTo() error = %v", err)
+	}
+
+	if tmpl.Name != "Test Template" || tmpl.SMSTemplate.Body != "Your code is {{.Code}}" {
+		t.Errorf("content not restored: name %q, body %q", tmpl.Name, tmpl.SMSTemplate.Body)
+	}
+	if tmpl.Version != version+1 {
+		t.Errorf("Version = %d, want %d", tmpl.Version, version+1)
+	}
+	if len(tmpl.GetDomainEvents()) != 1 {
+		t.Errorf("expected 1 domain event, got %d", len(tmpl.GetDomainEvents()))
+	}
+
+	// The restored content is a copy of the revision
+	tmpl.SMSTemplate.Body = "Edited again"
+	if rev.SMSTemplate.Body != "Your code is {{.Code}}" {
+		t.Errorf("revision body = %q, should not change with the template", rev.SMSTemplate.Body)
+	}
+}
+
+func TestNotificationTemplate_RollbackTo_OtherTemplate(t *testing.T) {
+	tmpl := createTestTemplate(t)
+	other := createTestTemplate(t)
+
+	err := tmpl.RollbackTo(NewTemplateRevision(other, 1, TemplateRevisionCreated, ""), nil)
+	if err != ErrTemplateRevisionMismatch {
+		t.Errorf("RollbackTo() error = %v, want %v", err, ErrTemplateRevisionMismatch)
+	}
+}
+
+func TestDiffTemplateRevisions(t *testing.T) {
+	from := &TemplateRevision{
+		EmailTemplate: &EmailTemplateContent{Subject: "Hi", Body: "line 1\nline 2\nline 3"},
+	}
+	to := &TemplateRevision{
+		EmailTemplate: &EmailTemplateContent{Subject: "Hi", Body: "line 1\nline two\nline 3"},
+		SMSTemplate:   &SMSTemplateContent{Body: "New SMS"},
+	}
+
+	diffs := DiffTemplateRevisions(from, to)
+
+	if len(diffs) != 2 {
+		t.Fatalf("expected 2 changed fields, got %+v", diffs)
+	}
+	if diffs[0].Field != "email.body" || diffs[1].Field != "sms.body" {
+		t.Errorf("fields = %s, %s; want email.body, sms.body", diffs[0].Field, diffs[1].Field)
+	}
+
+	want := []DiffLine{
+		{Op: DiffEqual, Text: "line 1"},
+		{Op: DiffDelete, Text: "line 2"},
+		{Op: DiffInsert, Text: "line two"},
+		{Op: DiffEqual, Text: "line 3"},
+	}
+	if len(diffs[0].Lines) != len(want) {
+		t.Fatalf("lines = %+v, want %+v", diffs[0].Lines, want)
+	}
+	for i, line := range diffs[0].Lines {
+		if line != want[i] {
+			t.Errorf("line %d = %+v, want %+v", i, line, want[i])
+		}
+	}
+
+	to.Localizations = map[string]*TemplateLocalization{
+		"ms": {Locale: "ms", SMSTemplate: &SMSTemplateContent{Body: "SMS baharu"}},
+	}
+	diffs = DiffTemplateRevisions(from, to)
+	if len(diffs) != 3 || diffs[2].Field != "ms.sms.body" || diffs[2].To != "SMS baharu" {
+		t.Errorf("expected a localized ms.sms.body change, got %+v", diffs)
+	}
+
+	if len(DiffTemplateRevisions(to, to)) != 0 {
+		t.Error("expected no changes between a revision and itself")
+	}
+}
+
+func TestDiffLines_Empty(t *testing.T) {
+	lines := DiffLines("", "added")
+	if len(lines) != 1 || lines[0].Op != DiffInsert || lines[0].Text != "added" {
+		t.Errorf("DiffLines() = %+v, want a single insert", lines)
+	}
+	if len(DiffLines("", "")) != 0 {
+		t.Error("expected no lines for two empty strings")
+	}
+}
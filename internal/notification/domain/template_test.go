@@ -443,6 +443,33 @@ func TestNotificationTemplate_ValidateVariables(t *testing.T) {
 	}
 }
 
+func TestNotificationTemplate_SampleVariables(t *testing.T) {
+	tmpl := createTestTemplate(t)
+	tmpl.AddVariable(TemplateVariable{Name: "name", Type: "string", Required: true, Example: "Aisyah"})
+	tmpl.AddVariable(TemplateVariable{Name: "amount", Type: "number", Required: true})
+	tmpl.AddVariable(TemplateVariable{Name: "currency", Type: "string", DefaultValue: "MYR"})
+
+	sample, err := tmpl.SampleVariables(map[string]interface{}{"amount": 120.5})
+	if err != nil {
+		t.Fatalf("SampleVariables() error = %v", err)
+	}
+	if sample["name"] != "Aisyah" || sample["currency"] != "MYR" || sample["amount"] != 120.5 {
+		t.Errorf("SampleVariables() = %v, want example, default and provided values", sample)
+	}
+
+	_, err = tmpl.SampleVariables(map[string]interface{}{"name": 42})
+	tmplErr, ok := err.(*TemplateError)
+	if !ok {
+		t.Fatalf("SampleVariables() error = %v, want *TemplateError", err)
+	}
+	if len(tmplErr.MissingVars) != 1 || tmplErr.MissingVars[0] != "amount" {
+		t.Errorf("MissingVars = %v, want [amount]", tmplErr.MissingVars)
+	}
+	if len(tmplErr.InvalidVars) != 1 || tmplErr.InvalidVars[0] != "name" {
+		t.Errorf("InvalidVars = %v, want [name]", tmplErr.InvalidVars)
+	}
+}
+
 // ============================================================================
 // Localization Tests
 // ============================================================================
@@ -503,6 +530,41 @@ func TestNotificationTemplate_GetLocalization(t *testing.T) {
 	}
 }
 
+func TestNotificationTemplate_AddLocalization_Normalizes(t *testing.T) {
+	tmpl := createTestTemplate(t)
+
+	if err := tmpl.AddLocalization("ms_my", &TemplateLocalization{}); err != nil {
+		t.Fatalf("AddLocalization() error = %v", err)
+	}
+	if tmpl.Localizations["ms-MY"] == nil || tmpl.Localizations["ms-MY"].Locale != "ms-MY" {
+		t.Errorf("Localizations = %v, want an ms-MY entry", tmpl.Localizations)
+	}
+	if tmpl.GetLocalization("MS-my") == nil {
+		t.Error("GetLocalization() should normalize the locale")
+	}
+
+	for _, locale := range []string{"en", "malay", "ms-MYS"} {
+		if err := tmpl.AddLocalization(locale, &TemplateLocalization{}); err == nil {
+			t.Errorf("AddLocalization(%q) should return error", locale)
+		}
+	}
+}
+
+func TestNotificationTemplate_SetDefaultLocale(t *testing.T) {
+	tmpl := createTestTemplate(t)
+	tmpl.AddLocalization("ms", &TemplateLocalization{})
+
+	if err := tmpl.SetDefaultLocale("ms"); err == nil {
+		t.Error("SetDefaultLocale() should return error for a localized locale")
+	}
+	if err := tmpl.SetDefaultLocale("en_GB"); err != nil {
+		t.Fatalf("SetDefaultLocale() error = %v", err)
+	}
+	if tmpl.DefaultLocale != "en-GB" {
+		t.Errorf("DefaultLocale = %s, want en-GB", tmpl.DefaultLocale)
+	}
+}
+
 // ============================================================================
 // Status Tests
 // ============================================================================
@@ -810,6 +872,74 @@ func TestNotificationTemplate_RenderEmail_WithLocalization(t *testing.T) {
 	}
 }
 
+func TestNotificationTemplate_RenderEmail_Helpers(t *testing.T) {
+	tmpl := createTestTemplate(t)
+	tmpl.SetEmailTemplate(&EmailTemplateContent{
+		Subject:  "Invoice {{.Number}} due {{.DueDate | date}}",
+		Body:     "{{range .Items}}{{.Name}}: {{.Amount | currency}}\n{{end}}",
+		HTMLBody: "<p>Dear {{.Name}}</p>",
+	})
+
+	rendered, err := tmpl.RenderEmail(map[string]interface{}{
+		"Number":  "INV-7",
+		"DueDate": "2024-12-31",
+		"Name":    "<b>Aisyah</b>",
+		"Items": []map[string]interface{}{
+			{"Name": "Batik", "Amount": 1250},
+		},
+	}, "")
+	if err != nil {
+		t.Fatalf("RenderEmail() error = %v", err)
+	}
+
+	if rendered.Subject != "Invoice INV-7 due 31/12/2024" {
+		t.Errorf("Subject = %q", rendered.Subject)
+	}
+	if rendered.Body != "Batik: RM1,250.00\n" {
+		t.Errorf("Body = %q", rendered.Body)
+	}
+	if rendered.HTMLBody != "<p>Dear &lt;b&gt;Aisyah&lt;/b&gt;</p>" {
+		t.Errorf("HTMLBody = %q, want the name escaped", rendered.HTMLBody)
+	}
+}
+
+func TestNotificationTemplate_RenderSMS_LocaleFallback(t *testing.T) {
+	tmpl := createTestTemplate(t)
+	tmpl.SetSMSTemplate(&SMSTemplateContent{Body: "Your OTP is {{.Code}}"})
+	tmpl.AddLocalization("ms", &TemplateLocalization{
+		SMSTemplate: &SMSTemplateContent{Body: "Kod OTP anda {{.Code}}"},
+	})
+	tmpl.AddLocalization("zh-CN", &TemplateLocalization{
+		EmailTemplate: &EmailTemplateContent{Subject: "Email only"},
+	})
+
+	data := map[string]interface{}{"Code": "123456"}
+
+	tests := []struct {
+		locale     string
+		wantBody   string
+		wantLocale string
+	}{
+		{"ms-MY", "Kod OTP anda 123456", "ms"},
+		{"ms_my", "Kod OTP anda 123456", "ms"},
+		{"zh-CN", "Your OTP is 123456", "en"},
+		{"fr", "Your OTP is 123456", "en"},
+		{"", "Your OTP is 123456", "en"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.locale, func(t *testing.T) {
+			rendered, err := tmpl.RenderSMS(data, tt.locale)
+			if err != nil {
+				t.Fatalf("RenderSMS() error = %v", err)
+			}
+			if rendered.Body != tt.wantBody || rendered.Locale != tt.wantLocale {
+				t.Errorf("RenderSMS() = %q (%s), want %q (%s)", rendered.Body, rendered.Locale, tt.wantBody, tt.wantLocale)
+			}
+		})
+	}
+}
+
 func TestNotificationTemplate_RenderSMS(t *testing.T) {
 	tmpl := createTestTemplate(t)
 	tmpl.SetSMSTemplate(&SMSTemplateContent{
@@ -3,8 +3,10 @@ package domain
 
 import (
 	"encoding/json"
+	"reflect"
 	"regexp"
 	"strings"
+	"time"
 )
 
 // ============================================================================
@@ -245,7 +247,7 @@ func (s NotificationStatus) CanTransitionTo(target NotificationStatus) bool {
 		StatusSending:   {StatusSent, StatusFailed, StatusRetrying},
 		StatusRetrying:  {StatusSending, StatusFailed},
 		StatusSent:      {StatusDelivered, StatusBounced, StatusComplained},
-		StatusDelivered: {StatusRead},
+		StatusDelivered: {StatusRead, StatusComplained},
 	}
 
 	allowed, ok := transitions[s]
@@ -748,6 +750,45 @@ func (r *Recipient) IsEmpty() bool {
 		r.DeviceToken == "" && r.WebhookURL == "" && r.SlackUserID == "" && r.TelegramID == ""
 }
 
+// ============================================================================
+// Locale Value Object
+// ============================================================================
+
+// localePattern matches a normalized locale: a language code, optionally
+// followed by a region code (e.g. "ms", "ms-MY").
+var localePattern = regexp.MustCompile(`^[a-z]{2,3}(-[A-Z]{2})?$`)
+
+// NormalizeLocale returns locale in its canonical form, so "ms_my" and
+// "MS-MY" both become "ms-MY".
+func NormalizeLocale(locale string) (string, error) {
+	parts := strings.Split(strings.ReplaceAll(strings.TrimSpace(locale), "_", "-"), "-")
+	parts[0] = strings.ToLower(parts[0])
+	if len(parts) > 1 {
+		parts[1] = strings.ToUpper(parts[1])
+	}
+
+	normalized := strings.Join(parts, "-")
+	if !localePattern.MatchString(normalized) {
+		return "", NewValidationError("locale", "locale must be a language code, optionally with a region (e.g. ms or ms-MY)", "INVALID_FORMAT")
+	}
+	return normalized, nil
+}
+
+// LocaleFallbacks returns the locales to try for locale, most specific first:
+// "ms-MY" falls back to "ms". It returns nil for an invalid locale.
+func LocaleFallbacks(locale string) []string {
+	normalized, err := NormalizeLocale(locale)
+	if err != nil {
+		return nil
+	}
+
+	fallbacks := []string{normalized}
+	if i := strings.Index(normalized, "-"); i > 0 {
+		fallbacks = append(fallbacks, normalized[:i])
+	}
+	return fallbacks
+}
+
 // ============================================================================
 // TemplateVariable Value Object
 // ============================================================================
@@ -810,26 +851,69 @@ func (v *TemplateVariable) WithExample(example interface{}) *TemplateVariable {
 	return v
 }
 
+// Accepts reports whether value is valid for the variable's type. Values are
+// expected as decoded from JSON: numbers may be any numeric type and dates
+// are RFC 3339 strings.
+func (v TemplateVariable) Accepts(value interface{}) bool {
+	switch v.Type {
+	case "string":
+		_, ok := value.(string)
+		return ok
+	case "number":
+		switch value.(type) {
+		case float64, float32, int, int32, int64, uint, uint32, uint64, json.Number:
+			return true
+		}
+		return false
+	case "boolean":
+		_, ok := value.(bool)
+		return ok
+	case "date":
+		switch d := value.(type) {
+		case time.Time:
+			return true
+		case string:
+			_, err := time.Parse(time.RFC3339, d)
+			if err != nil {
+				_, err = time.Parse("2006-01-02", d)
+			}
+			return err == nil
+		}
+		return false
+	case "array":
+		return value != nil && reflect.TypeOf(value).Kind() == reflect.Slice
+	case "object":
+		return value != nil && reflect.TypeOf(value).Kind() == reflect.Map
+	default:
+		return true
+	}
+}
+
 // ============================================================================
 // RetryPolicy Value Object
 // ============================================================================
 
 // RetryPolicy defines how notification delivery should be retried.
 type RetryPolicy struct {
-	MaxAttempts     int   `json:"max_attempts"`
-	InitialInterval int   `json:"initial_interval_seconds"` // Initial retry interval in seconds
-	MaxInterval     int   `json:"max_interval_seconds"`     // Maximum retry interval in seconds
-	Multiplier      float64 `json:"multiplier"`             // Backoff multiplier
-	RetryableErrors []string `json:"retryable_errors,omitempty"`
+	MaxAttempts        int                        `json:"max_attempts"`
+	InitialInterval    int                        `json:"initial_interval_seconds"`       // Initial retry interval in seconds
+	MaxInterval        int                        `json:"max_interval_seconds"`           // Maximum retry interval in seconds
+	Multiplier         float64                    `json:"multiplier"`                     // Backoff multiplier
+	Jitter             float64                    `json:"jitter,omitempty"`               // Fraction of the interval taken off at random, 0 to 1
+	RetryableErrors    []string                   `json:"retryable_errors,omitempty"`     // When set, only these error codes are retried
+	NonRetryableErrors []string                   `json:"non_retryable_errors,omitempty"` // Error codes that are never retried
+	ProviderErrors     map[string]RetryErrorRules `json:"provider_errors,omitempty"`      // Per-provider overrides of the above
 }
 
 // DefaultRetryPolicy returns the default retry policy.
 func DefaultRetryPolicy() RetryPolicy {
 	return RetryPolicy{
-		MaxAttempts:     3,
-		InitialInterval: 60,   // 1 minute
-		MaxInterval:     3600, // 1 hour
-		Multiplier:      2.0,
+		MaxAttempts:        3,
+		InitialInterval:    60,   // 1 minute
+		MaxInterval:        3600, // 1 hour
+		Multiplier:         2.0,
+		Jitter:             0.2,
+		NonRetryableErrors: []string{DeliveryErrorRejected, DeliveryErrorUndeliverable},
 	}
 }
 
@@ -877,3 +961,63 @@ func (r RetryPolicy) GetInterval(attempt int) int {
 func (r RetryPolicy) ShouldRetry(attempt int) bool {
 	return attempt < r.MaxAttempts
 }
+
+// Validate checks that the policy's settings are usable.
+func (r RetryPolicy) Validate() error {
+	if _, err := NewRetryPolicy(r.MaxAttempts, r.InitialInterval, r.MaxInterval, r.Multiplier); err != nil {
+		return err
+	}
+	if r.Jitter < 0 || r.Jitter > 1 {
+		return NewValidationError("jitter", "jitter must be between 0 and 1", "INVALID_VALUE")
+	}
+	return nil
+}
+
+// NextDelay returns how long to wait before the next attempt, after attempt
+// attempts were made. Jitter takes up to that fraction off the backoff
+// interval, using random (0 to 1), so notifications that failed together do
+// not all retry at the same moment.
+func (r RetryPolicy) NextDelay(attempt int, random float64) time.Duration {
+	interval := time.Duration(r.GetInterval(attempt)) * time.Second
+	if r.Jitter <= 0 {
+		return interval
+	}
+	return time.Duration(float64(interval) * (1 - r.Jitter*random))
+}
+
+// IsRetryable reports whether a delivery that failed with errorCode at
+// provider may be retried. The provider's rules are checked first, then the
+// policy's own lists; codes in neither are retried unless RetryableErrors is
+// set.
+func (r RetryPolicy) IsRetryable(provider, errorCode string) bool {
+	if rules, ok := r.ProviderErrors[strings.ToLower(provider)]; ok {
+		if containsErrorCode(rules.NonRetryable, errorCode) {
+			return false
+		}
+		if containsErrorCode(rules.Retryable, errorCode) {
+			return true
+		}
+	}
+	if containsErrorCode(r.NonRetryableErrors, errorCode) {
+		return false
+	}
+	if len(r.RetryableErrors) > 0 {
+		return containsErrorCode(r.RetryableErrors, errorCode)
+	}
+	return true
+}
+
+// RetryErrorRules classifies a provider's delivery error codes.
+type RetryErrorRules struct {
+	Retryable    []string `json:"retryable,omitempty"`
+	NonRetryable []string `json:"non_retryable,omitempty"`
+}
+
+func containsErrorCode(codes []string, code string) bool {
+	for _, c := range codes {
+		if strings.EqualFold(c, code) {
+			return true
+		}
+	}
+	return false
+}
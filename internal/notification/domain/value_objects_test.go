@@ -372,6 +372,8 @@ func TestNotificationStatus_CanTransitionTo(t *testing.T) {
 		{"sent to delivered", StatusSent, StatusDelivered, true},
 		{"sent to bounced", StatusSent, StatusBounced, true},
 		{"delivered to read", StatusDelivered, StatusRead, true},
+		{"delivered to complained", StatusDelivered, StatusComplained, true},
+		{"delivered to bounced", StatusDelivered, StatusBounced, false},
 		{"failed to sent", StatusFailed, StatusSent, false},
 		{"cancelled to anything", StatusCancelled, StatusSent, false},
 	}
@@ -999,6 +1001,36 @@ func TestTemplateVariable_FluentMethods(t *testing.T) {
 	}
 }
 
+func TestTemplateVariable_Accepts(t *testing.T) {
+	tests := []struct {
+		varType string
+		value   interface{}
+		want    bool
+	}{
+		{"string", "text", true},
+		{"string", 1, false},
+		{"number", 1.5, true},
+		{"number", 3, true},
+		{"number", "3", false},
+		{"boolean", true, true},
+		{"boolean", "true", false},
+		{"date", "2024-03-01T10:00:00Z", true},
+		{"date", "2024-03-01", true},
+		{"date", "yesterday", false},
+		{"array", []interface{}{"a"}, true},
+		{"array", "a", false},
+		{"object", map[string]interface{}{"a": 1}, true},
+		{"object", nil, false},
+	}
+
+	for _, tt := range tests {
+		variable := TemplateVariable{Name: "v", Type: tt.varType}
+		if got := variable.Accepts(tt.value); got != tt.want {
+			t.Errorf("Accepts(%v) for %s = %v, want %v", tt.value, tt.varType, got, tt.want)
+		}
+	}
+}
+
 // ============================================================================
 // RetryPolicy Tests
 // ============================================================================
@@ -1111,3 +1143,48 @@ func TestRetryPolicy_ShouldRetry(t *testing.T) {
 		})
 	}
 }
+
+// ============================================================================
+// Locale Tests
+// ============================================================================
+
+func TestNormalizeLocale(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    string
+		wantErr bool
+	}{
+		{"ms", "ms", false},
+		{"ms_my", "ms-MY", false},
+		{" MS-my ", "ms-MY", false},
+		{"fil-PH", "fil-PH", false},
+		{"", "", true},
+		{"malay", "", true},
+		{"ms-MY-x", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := NormalizeLocale(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("NormalizeLocale() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("NormalizeLocale() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLocaleFallbacks(t *testing.T) {
+	got := LocaleFallbacks("ms_MY")
+	if len(got) != 2 || got[0] != "ms-MY" || got[1] != "ms" {
+		t.Errorf("LocaleFallbacks() = %v, want [ms-MY ms]", got)
+	}
+	if got := LocaleFallbacks("ms"); len(got) != 1 || got[0] != "ms" {
+		t.Errorf("LocaleFallbacks() = %v, want [ms]", got)
+	}
+	if got := LocaleFallbacks(""); got != nil {
+		t.Errorf("LocaleFallbacks() = %v, want nil", got)
+	}
+}
@@ -0,0 +1,154 @@
+// Package cache provides the Redis cache adapter for the notification service.
+package cache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	"github.com/kilang-desa-murni/crm/internal/notification/application/ports"
+)
+
+// ============================================================================
+// Configuration
+// ============================================================================
+
+// RedisCacheConfig holds Redis cache configuration.
+type RedisCacheConfig struct {
+	// KeyPrefix namespaces every key of the service, e.g. "notification:".
+	KeyPrefix string
+	// DefaultTTL is used when Set is called without a TTL.
+	DefaultTTL time.Duration
+	// ScanCount is the SCAN batch size used for pattern invalidation.
+	ScanCount int64
+}
+
+// DefaultRedisCacheConfig returns default Redis cache configuration.
+func DefaultRedisCacheConfig() RedisCacheConfig {
+	return RedisCacheConfig{
+		KeyPrefix:  "notification:",
+		DefaultTTL: 10 * time.Minute,
+		ScanCount:  500,
+	}
+}
+
+// ============================================================================
+// Redis Cache
+// ============================================================================
+
+// RedisCacheService implements ports.CacheService using Redis.
+type RedisCacheService struct {
+	client *redis.Client
+	config RedisCacheConfig
+}
+
+// NewRedisCacheService creates a new Redis cache service.
+func NewRedisCacheService(client *redis.Client, config RedisCacheConfig) *RedisCacheService {
+	if config.DefaultTTL <= 0 {
+		config.DefaultTTL = DefaultRedisCacheConfig().DefaultTTL
+	}
+	if config.ScanCount <= 0 {
+		config.ScanCount = DefaultRedisCacheConfig().ScanCount
+	}
+	return &RedisCacheService{client: client, config: config}
+}
+
+// buildKey builds a cache key with the prefix.
+func (c *RedisCacheService) buildKey(key string) string {
+	return c.config.KeyPrefix + key
+}
+
+// ttlFor returns the explicit TTL if set, otherwise the default.
+func (c *RedisCacheService) ttlFor(ttl time.Duration) time.Duration {
+	if ttl > 0 {
+		return ttl
+	}
+	return c.config.DefaultTTL
+}
+
+// Get retrieves a value from the cache. A missing key returns a nil value and
+// no error, so callers fall through to their repository.
+func (c *RedisCacheService) Get(ctx context.Context, key string) ([]byte, error) {
+	data, err := c.client.Get(ctx, c.buildKey(key)).Bytes()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get from cache: %w", err)
+	}
+	return data, nil
+}
+
+// Set stores a value in the cache. A zero TTL uses the default TTL.
+func (c *RedisCacheService) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	if err := c.client.Set(ctx, c.buildKey(key), value, c.ttlFor(ttl)).Err(); err != nil {
+		return fmt.Errorf("failed to set cache: %w", err)
+	}
+	return nil
+}
+
+// Delete removes a value from the cache.
+func (c *RedisCacheService) Delete(ctx context.Context, key string) error {
+	if err := c.client.Del(ctx, c.buildKey(key)).Err(); err != nil {
+		return fmt.Errorf("failed to delete from cache: %w", err)
+	}
+	return nil
+}
+
+// Exists checks if a key exists in the cache.
+func (c *RedisCacheService) Exists(ctx context.Context, key string) (bool, error) {
+	n, err := c.client.Exists(ctx, c.buildKey(key)).Result()
+	if err != nil {
+		return false, fmt.Errorf("failed to check cache key existence: %w", err)
+	}
+	return n > 0, nil
+}
+
+// GetOrSet returns the cached value, or loads and caches it on a miss. The
+// cache is an optimisation only: when Redis is unavailable the loader's value
+// is returned uncached rather than failing the request.
+func (c *RedisCacheService) GetOrSet(ctx context.Context, key string, loader func() ([]byte, error), ttl time.Duration) ([]byte, error) {
+	if data, err := c.Get(ctx, key); err == nil && data != nil {
+		return data, nil
+	}
+
+	data, err := loader()
+	if err != nil {
+		return nil, err
+	}
+	_ = c.Set(ctx, key, data, ttl)
+	return data, nil
+}
+
+// Invalidate removes all values matching a glob pattern. Keys are found with
+// SCAN rather than KEYS so large keyspaces do not block Redis.
+func (c *RedisCacheService) Invalidate(ctx context.Context, pattern string) error {
+	iter := c.client.Scan(ctx, 0, c.buildKey(pattern), c.config.ScanCount).Iterator()
+
+	batch := make([]string, 0, c.config.ScanCount)
+	for iter.Next(ctx) {
+		batch = append(batch, iter.Val())
+		if int64(len(batch)) >= c.config.ScanCount {
+			if err := c.client.Unlink(ctx, batch...).Err(); err != nil {
+				return fmt.Errorf("failed to delete cache keys: %w", err)
+			}
+			batch = batch[:0]
+		}
+	}
+	if err := iter.Err(); err != nil {
+		return fmt.Errorf("failed to scan cache keys: %w", err)
+	}
+
+	if len(batch) > 0 {
+		if err := c.client.Unlink(ctx, batch...).Err(); err != nil {
+			return fmt.Errorf("failed to delete cache keys: %w", err)
+		}
+	}
+	return nil
+}
+
+// Ensure RedisCacheService implements ports.CacheService
+var _ ports.CacheService = (*RedisCacheService)(nil)
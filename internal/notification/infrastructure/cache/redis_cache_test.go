@@ -0,0 +1,132 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	goredis "github.com/redis/go-redis/v9"
+
+	"github.com/kilang-desa-murni/crm/pkg/testing/containers"
+	"github.com/kilang-desa-murni/crm/pkg/testing/helpers"
+)
+
+// newTestRedisCache connects to the test Redis and clears it, skipping the test
+// in short mode or when Redis is not reachable.
+func newTestRedisCache(t *testing.T, config RedisCacheConfig) (*RedisCacheService, *containers.RedisContainer) {
+	t.Helper()
+	helpers.SkipIfShort(t)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	redis, err := containers.NewRedisContainer(ctx, containers.DefaultRedisConfig())
+	if err != nil {
+		t.Skipf("Redis not available: %v", err)
+	}
+	t.Cleanup(func() { redis.Close() })
+	if err := redis.FlushDB(ctx); err != nil {
+		t.Fatalf("failed to flush Redis: %v", err)
+	}
+	return NewRedisCacheService(redis.GetClient(), config), redis
+}
+
+func TestRedisCacheService_GetSetDelete(t *testing.T) {
+	cache, redis := newTestRedisCache(t, DefaultRedisCacheConfig())
+	ctx := context.Background()
+
+	// A miss is not an error, matching what the use cases expect
+	if got, err := cache.Get(ctx, "template:t:1"); err != nil || got != nil {
+		t.Fatalf("Get() on a miss = %q, %v, want nil, nil", got, err)
+	}
+	if err := cache.Set(ctx, "template:t:1", []byte("welcome"), 0); err != nil {
+		t.Fatalf("Set() error = %v", err)
+	}
+	if got, err := cache.Get(ctx, "template:t:1"); err != nil || string(got) != "welcome" {
+		t.Fatalf("Get() = %q, %v, want welcome", got, err)
+	}
+
+	// Keys are namespaced and a zero TTL falls back to the default
+	ttl, err := redis.TTL(ctx, "notification:template:t:1")
+	if err != nil || ttl <= 0 || ttl > DefaultRedisCacheConfig().DefaultTTL {
+		t.Errorf("TTL = %v, %v, want within the default TTL", ttl, err)
+	}
+
+	if exists, err := cache.Exists(ctx, "template:t:1"); err != nil || !exists {
+		t.Errorf("Exists() = %v, %v, want true", exists, err)
+	}
+	if err := cache.Delete(ctx, "template:t:1"); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	if exists, _ := cache.Exists(ctx, "template:t:1"); exists {
+		t.Error("expected the key to be deleted")
+	}
+}
+
+func TestRedisCacheService_GetOrSet(t *testing.T) {
+	cache, _ := newTestRedisCache(t, DefaultRedisCacheConfig())
+	ctx := context.Background()
+
+	loads := 0
+	loader := func() ([]byte, error) {
+		loads++
+		return []byte("rendered"), nil
+	}
+	for i := 0; i < 2; i++ {
+		got, err := cache.GetOrSet(ctx, "template:t:code:welcome", loader, time.Minute)
+		if err != nil || string(got) != "rendered" {
+			t.Fatalf("GetOrSet() = %q, %v, want rendered", got, err)
+		}
+	}
+	if loads != 1 {
+		t.Errorf("loader called %d times, want 1", loads)
+	}
+
+	loadErr := errors.New("template not found")
+	if _, err := cache.GetOrSet(ctx, "template:t:code:missing", func() ([]byte, error) { return nil, loadErr }, time.Minute); !errors.Is(err, loadErr) {
+		t.Errorf("expected the loader error, got %v", err)
+	}
+	if exists, _ := cache.Exists(ctx, "template:t:code:missing"); exists {
+		t.Error("expected a failed load not to be cached")
+	}
+}
+
+func TestRedisCacheService_Invalidate(t *testing.T) {
+	// A small scan batch exercises the batched unlink
+	cache, _ := newTestRedisCache(t, RedisCacheConfig{KeyPrefix: "notification:", ScanCount: 2})
+	ctx := context.Background()
+
+	for _, key := range []string{"template:a:1", "template:a:2", "template:a:code:welcome", "template:b:1"} {
+		if err := cache.Set(ctx, key, []byte("v"), time.Minute); err != nil {
+			t.Fatalf("Set() error = %v", err)
+		}
+	}
+
+	if err := cache.Invalidate(ctx, "template:a:*"); err != nil {
+		t.Fatalf("Invalidate() error = %v", err)
+	}
+	for _, key := range []string{"template:a:1", "template:a:2", "template:a:code:welcome"} {
+		if exists, _ := cache.Exists(ctx, key); exists {
+			t.Errorf("expected %s to be invalidated", key)
+		}
+	}
+	if exists, _ := cache.Exists(ctx, "template:b:1"); !exists {
+		t.Error("expected other tenants to be kept")
+	}
+}
+
+func TestRedisCacheService_Unavailable(t *testing.T) {
+	// Nothing listens on the port, so every call fails fast
+	client := goredis.NewClient(&goredis.Options{Addr: "127.0.0.1:1", MaxRetries: -1, DialTimeout: 100 * time.Millisecond})
+	defer client.Close()
+	cache := NewRedisCacheService(client, DefaultRedisCacheConfig())
+	ctx := context.Background()
+
+	if _, err := cache.Get(ctx, "template:t:1"); err == nil {
+		t.Error("expected Get() to report the connection error")
+	}
+	got, err := cache.GetOrSet(ctx, "template:t:1", func() ([]byte, error) { return []byte("loaded"), nil }, time.Minute)
+	if err != nil || string(got) != "loaded" {
+		t.Errorf("GetOrSet() = %q, %v, want the loaded value without the cache", got, err)
+	}
+}
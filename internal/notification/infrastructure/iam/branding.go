@@ -0,0 +1,142 @@
+// Package iam provides clients of the IAM service for the notification service.
+package iam
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/kilang-desa-murni/crm/internal/notification/application/ports"
+)
+
+// BrandingConfig holds configuration for the IAM tenant branding provider.
+type BrandingConfig struct {
+	// BaseURL is the IAM service URL, serving its internal routes.
+	BaseURL string
+	Timeout time.Duration
+	// CacheTTL is how long a tenant's branding is reused before it is
+	// fetched again.
+	CacheTTL time.Duration
+}
+
+// DefaultBrandingConfig returns default branding provider configuration for
+// the IAM service at baseURL.
+func DefaultBrandingConfig(baseURL string) BrandingConfig {
+	return BrandingConfig{
+		BaseURL:  baseURL,
+		Timeout:  3 * time.Second,
+		CacheTTL: 5 * time.Minute,
+	}
+}
+
+// HTTPBrandingProvider implements TenantBrandingProvider with the IAM
+// service's internal tenant branding route. Brandings are cached, as every
+// email sent looks up its tenant's.
+type HTTPBrandingProvider struct {
+	config     BrandingConfig
+	httpClient *http.Client
+
+	mu    sync.Mutex
+	cache map[string]cachedBranding
+}
+
+// cachedBranding is a tenant's branding, nil when it has none.
+type cachedBranding struct {
+	branding  *ports.TenantBranding
+	expiresAt time.Time
+}
+
+// NewHTTPBrandingProvider creates a new IAM tenant branding provider.
+func NewHTTPBrandingProvider(config BrandingConfig) *HTTPBrandingProvider {
+	return &HTTPBrandingProvider{
+		config: config,
+		httpClient: &http.Client{
+			Timeout: config.Timeout,
+		},
+		cache: make(map[string]cachedBranding),
+	}
+}
+
+// brandingResponse is the IAM service response envelope.
+type brandingResponse struct {
+	Data struct {
+		Name     string `json:"name"`
+		Branding *struct {
+			LogoURL          string `json:"logo_url"`
+			PrimaryColor     string `json:"primary_color"`
+			SecondaryColor   string `json:"secondary_color"`
+			EmailFromAddress string `json:"email_from_address"`
+			EmailFromName    string `json:"email_from_name"`
+		} `json:"branding"`
+	} `json:"data"`
+}
+
+// GetTenantBranding retrieves a tenant's branding, or nil when the tenant
+// has none.
+func (p *HTTPBrandingProvider) GetTenantBranding(ctx context.Context, tenantID string) (*ports.TenantBranding, error) {
+	p.mu.Lock()
+	cached, ok := p.cache[tenantID]
+	p.mu.Unlock()
+	if ok && time.Now().Before(cached.expiresAt) {
+		return cached.branding, nil
+	}
+
+	branding, err := p.fetch(ctx, tenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	p.mu.Lock()
+	p.cache[tenantID] = cachedBranding{branding: branding, expiresAt: time.Now().Add(p.config.CacheTTL)}
+	p.mu.Unlock()
+	return branding, nil
+}
+
+func (p *HTTPBrandingProvider) fetch(ctx context.Context, tenantID string) (*ports.TenantBranding, error) {
+	endpoint := fmt.Sprintf("%s/internal/tenants/%s/branding",
+		strings.TrimRight(p.config.BaseURL, "/"), url.PathEscape(tenantID))
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := p.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("tenant branding request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read tenant branding: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("tenant branding request failed: status %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+
+	var response brandingResponse
+	if err := json.Unmarshal(body, &response); err != nil {
+		return nil, fmt.Errorf("failed to parse tenant branding: %w", err)
+	}
+	if response.Data.Branding == nil {
+		return nil, nil
+	}
+	return &ports.TenantBranding{
+		TenantName:       response.Data.Name,
+		LogoURL:          response.Data.Branding.LogoURL,
+		PrimaryColor:     response.Data.Branding.PrimaryColor,
+		SecondaryColor:   response.Data.Branding.SecondaryColor,
+		EmailFromAddress: response.Data.Branding.EmailFromAddress,
+		EmailFromName:    response.Data.Branding.EmailFromName,
+	}, nil
+}
+
+// Ensure HTTPBrandingProvider implements TenantBrandingProvider
+var _ ports.TenantBrandingProvider = (*HTTPBrandingProvider)(nil)
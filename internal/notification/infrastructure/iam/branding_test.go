@@ -0,0 +1,48 @@
+package iam
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestHTTPBrandingProvider_GetTenantBranding(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if r.URL.Path != "/internal/tenants/tenant-1/branding" {
+			t.Errorf("path = %q", r.URL.Path)
+		}
+		w.Write([]byte(`{"success":true,"data":{"tenant_id":"tenant-1","name":"Batik Warisan","branding":{"primary_color":"#1a5276","email_from_address":"hello@batikwarisan.my","email_from_name":"Batik Warisan"}}}`))
+	}))
+	defer server.Close()
+
+	provider := NewHTTPBrandingProvider(BrandingConfig{BaseURL: server.URL + "/", Timeout: 5 * time.Second, CacheTTL: time.Minute})
+	branding, err := provider.GetTenantBranding(context.Background(), "tenant-1")
+	if err != nil {
+		t.Fatalf("GetTenantBranding() error = %v", err)
+	}
+	if branding.TenantName != "Batik Warisan" || branding.PrimaryColor != "#1a5276" || branding.EmailFromAddress != "hello@batikwarisan.my" {
+		t.Errorf("branding = %+v", branding)
+	}
+
+	// The branding is cached
+	if _, err := provider.GetTenantBranding(context.Background(), "tenant-1"); err != nil || requests != 1 {
+		t.Errorf("GetTenantBranding() again = %v after %d requests, want the cached branding", err, requests)
+	}
+}
+
+func TestHTTPBrandingProvider_GetTenantBranding_NoBranding(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"success":true,"data":{"tenant_id":"tenant-1","name":"Batik Warisan"}}`))
+	}))
+	defer server.Close()
+
+	provider := NewHTTPBrandingProvider(DefaultBrandingConfig(server.URL))
+	branding, err := provider.GetTenantBranding(context.Background(), "tenant-1")
+	if err != nil || branding != nil {
+		t.Errorf("GetTenantBranding() = %+v, %v, want no branding", branding, err)
+	}
+}
@@ -0,0 +1,392 @@
+package messaging
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	amqp "github.com/rabbitmq/amqp091-go"
+
+	"github.com/kilang-desa-murni/crm/internal/notification/domain"
+	"github.com/kilang-desa-murni/crm/pkg/lifecycle"
+	"github.com/kilang-desa-murni/crm/pkg/logger"
+)
+
+const (
+	// CustomerEventsExchange is the customer service's event exchange.
+	CustomerEventsExchange = "customer.events"
+
+	// NotificationEventsExchange is the notification service's event
+	// exchange.
+	NotificationEventsExchange = "notification.events"
+
+	// CustomerErasureQueue receives the customer data erasures the sent
+	// notifications must take part in.
+	CustomerErasureQueue = "notification.customer-erasure"
+
+	// customerErasedRoutingKey is the routing key of the customer service's
+	// customer.erased events.
+	customerErasedRoutingKey = "customer.erased"
+
+	// erasureConfirmedRoutingKey is the routing key of the notification
+	// service's confirmation that it erased a customer's data.
+	erasureConfirmedRoutingKey = "notification.customer.erased"
+)
+
+// ============================================================================
+// Customer Erasure Consumer
+// ============================================================================
+
+// CustomerErasureConsumerConfig holds configuration for the customer erasure
+// consumer.
+type CustomerErasureConsumerConfig struct {
+	URL string
+	// Queue is shared by every instance of the service, so each erasure is
+	// handled once per service rather than once per instance.
+	Queue          string
+	PrefetchCount  int
+	ReconnectDelay time.Duration
+}
+
+// DefaultCustomerErasureConsumerConfig returns the default consumer
+// configuration.
+func DefaultCustomerErasureConsumerConfig() CustomerErasureConsumerConfig {
+	return CustomerErasureConsumerConfig{
+		Queue:          CustomerErasureQueue,
+		PrefetchCount:  10,
+		ReconnectDelay: 5 * time.Second,
+	}
+}
+
+// CustomerErasureConsumer consumes the customer service's customer.erased
+// events. It erases the customer's personal data from the sent notifications
+// and confirms the erasure on the notification event exchange.
+type CustomerErasureConsumer struct {
+	config  CustomerErasureConsumerConfig
+	eraser  domain.RecipientEraser
+	log     *logger.Logger
+	conn    *amqp.Connection
+	channel *amqp.Channel
+	mu      sync.Mutex
+	closed  bool
+
+	// tag identifies the consumer to the broker so Shutdown can cancel it.
+	tag     string
+	drainer lifecycle.Drainer
+}
+
+// NewCustomerErasureConsumer creates a new customer erasure consumer and
+// declares its queue.
+func NewCustomerErasureConsumer(config CustomerErasureConsumerConfig, eraser domain.RecipientEraser, log *logger.Logger) (*CustomerErasureConsumer, error) {
+	defaults := DefaultCustomerErasureConsumerConfig()
+	if config.Queue == "" {
+		config.Queue = defaults.Queue
+	}
+	if config.PrefetchCount <= 0 {
+		config.PrefetchCount = defaults.PrefetchCount
+	}
+	if config.ReconnectDelay <= 0 {
+		config.ReconnectDelay = defaults.ReconnectDelay
+	}
+
+	consumer := &CustomerErasureConsumer{config: config, eraser: eraser, log: log, tag: config.Queue + "-" + uuid.NewString()}
+	if err := consumer.connect(); err != nil {
+		return nil, err
+	}
+	return consumer, nil
+}
+
+// connect opens a channel and declares the exchanges, queue and binding.
+func (c *CustomerErasureConsumer) connect() error {
+	conn, err := amqp.Dial(c.config.URL)
+	if err != nil {
+		return fmt.Errorf("failed to connect to RabbitMQ: %w", err)
+	}
+
+	ch, err := conn.Channel()
+	if err != nil {
+		conn.Close()
+		return fmt.Errorf("failed to open channel: %w", err)
+	}
+
+	if err := c.declare(ch); err != nil {
+		ch.Close()
+		conn.Close()
+		return err
+	}
+
+	c.mu.Lock()
+	c.conn = conn
+	c.channel = ch
+	c.mu.Unlock()
+	return nil
+}
+
+func (c *CustomerErasureConsumer) declare(ch *amqp.Channel) error {
+	if err := ch.Qos(c.config.PrefetchCount, 0, false); err != nil {
+		return fmt.Errorf("failed to set QoS: %w", err)
+	}
+
+	// The customer exchange is declared with the customer publisher's
+	// settings so binding works before the customer service has started
+	for _, exchange := range []string{CustomerEventsExchange, NotificationEventsExchange} {
+		if err := ch.ExchangeDeclare(
+			exchange,
+			"topic",
+			true,  // durable
+			false, // auto-delete
+			false, // internal
+			false, // no-wait
+			nil,
+		); err != nil {
+			return fmt.Errorf("failed to declare exchange %s: %w", exchange, err)
+		}
+	}
+
+	if _, err := ch.QueueDeclare(
+		c.config.Queue,
+		true,  // durable
+		false, // auto-delete
+		false, // exclusive
+		false, // no-wait
+		nil,
+	); err != nil {
+		return fmt.Errorf("failed to declare queue %s: %w", c.config.Queue, err)
+	}
+
+	if err := ch.QueueBind(
+		c.config.Queue,
+		customerErasedRoutingKey,
+		CustomerEventsExchange,
+		false, // no-wait
+		nil,
+	); err != nil {
+		return fmt.Errorf("failed to bind queue %s: %w", c.config.Queue, err)
+	}
+	return nil
+}
+
+// Start consumes events until ctx is cancelled or the consumer is closed,
+// reconnecting if the connection is lost.
+func (c *CustomerErasureConsumer) Start(ctx context.Context) error {
+	deliveries, err := c.deliveries()
+	if err != nil {
+		return err
+	}
+
+	go func() {
+		for {
+			c.handle(ctx, deliveries)
+
+			// The delivery channel closed: stop, or reconnect after a delay
+			for {
+				c.mu.Lock()
+				closed := c.closed
+				c.mu.Unlock()
+				if closed || c.drainer.Stopped() {
+					return
+				}
+
+				select {
+				case <-ctx.Done():
+					return
+				case <-time.After(c.config.ReconnectDelay):
+				}
+
+				if err := c.connect(); err != nil {
+					c.log.Warn().Err(err).Msg("Failed to reconnect customer erasure consumer")
+					continue
+				}
+				if deliveries, err = c.deliveries(); err == nil {
+					break
+				}
+			}
+		}
+	}()
+	return nil
+}
+
+func (c *CustomerErasureConsumer) deliveries() (<-chan amqp.Delivery, error) {
+	c.mu.Lock()
+	ch := c.channel
+	c.mu.Unlock()
+
+	deliveries, err := ch.Consume(
+		c.config.Queue,
+		c.tag, // consumer
+		false, // auto-ack
+		false, // exclusive
+		false, // no-local
+		false, // no-wait
+		nil,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to start consuming: %w", err)
+	}
+	return deliveries, nil
+}
+
+// handle processes deliveries until the channel closes or ctx is cancelled. A
+// failed event is requeued once and dropped if it fails again. Erasing is
+// idempotent, so a redelivered event is erased and confirmed again; the
+// customer service ignores the repeated confirmation.
+func (c *CustomerErasureConsumer) handle(ctx context.Context, deliveries <-chan amqp.Delivery) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case d, ok := <-deliveries:
+			if !ok {
+				return
+			}
+			// Deliveries prefetched after shutdown began go back to the queue
+			if !c.drainer.Begin() {
+				d.Nack(false, true)
+				continue
+			}
+
+			tenantID, _ := d.Headers["tenant_id"].(string)
+			if err := c.HandleCustomerErased(ctx, tenantID, d.Body); err != nil {
+				c.log.Warn().
+					Err(err).
+					Str("message_id", d.MessageId).
+					Msg("Failed to handle customer erased event")
+				d.Nack(false, !d.Redelivered)
+			} else {
+				d.Ack(false)
+			}
+			c.drainer.End()
+		}
+	}
+}
+
+// customerErasedEvent is the part of the customer service's customer.erased
+// event the notification service uses. The tenant is carried in the headers.
+type customerErasedEvent struct {
+	CustomerID       uuid.UUID `json:"customer_id"`
+	ErasureID        uuid.UUID `json:"erasure_id"`
+	IdentifierHashes []string  `json:"identifier_hashes"`
+}
+
+// erasureConfirmedEvent is the notification service's confirmation that it
+// erased a customer's data.
+type erasureConfirmedEvent struct {
+	ErasureID  uuid.UUID `json:"erasure_id"`
+	TenantID   uuid.UUID `json:"tenant_id"`
+	CustomerID uuid.UUID `json:"customer_id"`
+	Service    string    `json:"service"`
+	Records    int64     `json:"records"`
+	OccurredAt time.Time `json:"occurred_at"`
+}
+
+// HandleCustomerErased erases the notifications of the customer of a
+// customer.erased event and confirms the erasure. Events without a tenant,
+// customer or erasure ID are ignored.
+func (c *CustomerErasureConsumer) HandleCustomerErased(ctx context.Context, tenant string, body []byte) error {
+	tenantID, err := uuid.Parse(tenant)
+	if err != nil {
+		return nil
+	}
+
+	var event customerErasedEvent
+	if err := json.Unmarshal(body, &event); err != nil {
+		return fmt.Errorf("invalid customer erased event: %w", err)
+	}
+	if event.CustomerID == uuid.Nil || event.ErasureID == uuid.Nil {
+		return nil
+	}
+
+	records, err := c.eraser.EraseRecipients(ctx, tenantID, event.CustomerID, event.IdentifierHashes)
+	if err != nil {
+		return fmt.Errorf("failed to erase customer %s: %w", event.CustomerID, err)
+	}
+
+	if err := c.confirm(ctx, erasureConfirmedEvent{
+		ErasureID:  event.ErasureID,
+		TenantID:   tenantID,
+		CustomerID: event.CustomerID,
+		Service:    "notification",
+		Records:    records,
+		OccurredAt: time.Now().UTC(),
+	}); err != nil {
+		return fmt.Errorf("failed to confirm erasure %s: %w", event.ErasureID, err)
+	}
+
+	c.log.Info().
+		Str("erasure_id", event.ErasureID.String()).
+		Int64("records", records).
+		Msg("Customer notifications erased")
+	return nil
+}
+
+// confirm publishes the confirmation of an erasure.
+func (c *CustomerErasureConsumer) confirm(ctx context.Context, event erasureConfirmedEvent) error {
+	body, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to serialize confirmation: %w", err)
+	}
+
+	c.mu.Lock()
+	ch := c.channel
+	c.mu.Unlock()
+
+	return ch.PublishWithContext(
+		ctx,
+		NotificationEventsExchange,
+		erasureConfirmedRoutingKey,
+		false, // mandatory
+		false, // immediate
+		amqp.Publishing{
+			DeliveryMode: amqp.Persistent,
+			ContentType:  "application/json",
+			Body:         body,
+			Timestamp:    event.OccurredAt,
+			MessageId:    uuid.New().String(),
+			Headers: amqp.Table{
+				"event_type": erasureConfirmedRoutingKey,
+				"tenant_id":  event.TenantID.String(),
+			},
+		},
+	)
+}
+
+// Shutdown stops consuming, requeues the deliveries not yet handled and
+// waits for the event being handled before closing the connection.
+func (c *CustomerErasureConsumer) Shutdown(ctx context.Context) error {
+	c.drainer.Stop()
+
+	c.mu.Lock()
+	ch := c.channel
+	c.mu.Unlock()
+	if ch != nil {
+		ch.Cancel(c.tag, false)
+	}
+
+	err := c.drainer.Wait(ctx)
+	if closeErr := c.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
+
+// Close closes the consumer connection.
+func (c *CustomerErasureConsumer) Close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.closed {
+		return nil
+	}
+	c.closed = true
+
+	if c.channel != nil {
+		c.channel.Close()
+	}
+	if c.conn != nil {
+		return c.conn.Close()
+	}
+	return nil
+}
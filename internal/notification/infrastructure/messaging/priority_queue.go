@@ -0,0 +1,433 @@
+// Package messaging provides the RabbitMQ delivery queues for the Notification service.
+package messaging
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	amqp "github.com/rabbitmq/amqp091-go"
+
+	"github.com/kilang-desa-murni/crm/internal/notification/application/ports"
+	"github.com/kilang-desa-murni/crm/internal/notification/domain"
+	"github.com/kilang-desa-murni/crm/pkg/lifecycle"
+)
+
+// ============================================================================
+// Priority Delivery Queue
+// ============================================================================
+
+const (
+	// DeliveryExchange routes delivery jobs to the queue of their priority.
+	DeliveryExchange = "notification.delivery"
+
+	// deliveryQueuePrefix prefixes the per-priority queue names, e.g.
+	// notification.delivery.critical.
+	deliveryQueuePrefix = "notification.delivery."
+)
+
+// DeliveryQueueName returns the name of the queue for a priority.
+func DeliveryQueueName(priority domain.NotificationPriority) string {
+	return deliveryQueuePrefix + priority.String()
+}
+
+// PriorityQueueConfig holds priority delivery queue configuration.
+type PriorityQueueConfig struct {
+	URL string
+	// Concurrency is the number of delivery workers, shared by all
+	// priorities according to Policy.
+	Concurrency    int
+	Policy         domain.DispatchPolicy
+	ReconnectDelay time.Duration
+}
+
+// DeliveryHandler delivers a job taken from the queue. A failed job is
+// requeued once and dropped if it fails again.
+type DeliveryHandler func(ctx context.Context, job *ports.DeliveryJob) error
+
+// PriorityQueue implements ports.DeliveryQueue with one durable RabbitMQ
+// queue per priority. Consumed jobs wait in memory until a worker is free,
+// and the dispatch scheduler picks which priority each worker serves, so
+// critical notifications are not stuck behind bulk campaigns and bulk
+// campaigns still make progress.
+type PriorityQueue struct {
+	config  PriorityQueueConfig
+	conn    *amqp.Connection
+	channel *amqp.Channel
+	mu      sync.Mutex
+	closed  bool
+
+	scheduler *domain.DispatchScheduler
+	pending   map[domain.NotificationPriority][]pendingDelivery
+	ready     chan struct{}
+
+	// tag prefixes the consumer tag of each lane so Shutdown can cancel
+	// them.
+	tag     string
+	drainer lifecycle.Drainer
+}
+
+// pendingDelivery is a consumed job waiting for a worker.
+type pendingDelivery struct {
+	delivery   amqp.Delivery
+	enqueuedAt time.Time
+}
+
+// NewPriorityQueue creates a new priority queue and declares its exchange
+// and queues.
+func NewPriorityQueue(config PriorityQueueConfig) (*PriorityQueue, error) {
+	if config.Concurrency <= 0 {
+		config.Concurrency = 10
+	}
+	if config.Policy.Weights == nil {
+		config.Policy = domain.DefaultDispatchPolicy()
+	}
+	if config.ReconnectDelay <= 0 {
+		config.ReconnectDelay = 5 * time.Second
+	}
+
+	q := &PriorityQueue{
+		config:    config,
+		scheduler: domain.NewDispatchScheduler(config.Policy),
+		pending:   make(map[domain.NotificationPriority][]pendingDelivery),
+		ready:     make(chan struct{}, config.Concurrency),
+		tag:       "notification.delivery-" + uuid.NewString(),
+	}
+	if err := q.connect(); err != nil {
+		return nil, err
+	}
+	return q, nil
+}
+
+// connect opens a channel and declares the exchange and queues.
+func (q *PriorityQueue) connect() error {
+	conn, err := amqp.Dial(q.config.URL)
+	if err != nil {
+		return fmt.Errorf("failed to connect to RabbitMQ: %w", err)
+	}
+
+	ch, err := conn.Channel()
+	if err != nil {
+		conn.Close()
+		return fmt.Errorf("failed to open channel: %w", err)
+	}
+
+	if err := q.declare(ch); err != nil {
+		ch.Close()
+		conn.Close()
+		return err
+	}
+
+	q.mu.Lock()
+	q.conn = conn
+	q.channel = ch
+	q.mu.Unlock()
+	return nil
+}
+
+func (q *PriorityQueue) declare(ch *amqp.Channel) error {
+	if err := ch.ExchangeDeclare(
+		DeliveryExchange,
+		"direct",
+		true,  // durable
+		false, // auto-delete
+		false, // internal
+		false, // no-wait
+		nil,
+	); err != nil {
+		return fmt.Errorf("failed to declare exchange %s: %w", DeliveryExchange, err)
+	}
+
+	for _, priority := range domain.DispatchPriorities {
+		name := DeliveryQueueName(priority)
+		if _, err := ch.QueueDeclare(
+			name,
+			true,  // durable
+			false, // auto-delete
+			false, // exclusive
+			false, // no-wait
+			nil,
+		); err != nil {
+			return fmt.Errorf("failed to declare queue %s: %w", name, err)
+		}
+
+		if err := ch.QueueBind(name, priority.String(), DeliveryExchange, false, nil); err != nil {
+			return fmt.Errorf("failed to bind queue %s: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// Enqueue publishes a job to the queue of its priority. Unknown priorities
+// are queued as normal.
+func (q *PriorityQueue) Enqueue(ctx context.Context, job *ports.DeliveryJob) error {
+	body, err := json.Marshal(job)
+	if err != nil {
+		return fmt.Errorf("failed to marshal delivery job: %w", err)
+	}
+
+	priority := job.Priority
+	if !priority.IsValid() {
+		priority = domain.PriorityNormal
+	}
+
+	q.mu.Lock()
+	ch := q.channel
+	q.mu.Unlock()
+
+	if err := ch.PublishWithContext(ctx,
+		DeliveryExchange,
+		priority.String(),
+		false, // mandatory
+		false, // immediate
+		amqp.Publishing{
+			ContentType:  "application/json",
+			DeliveryMode: amqp.Persistent,
+			MessageId:    job.NotificationID,
+			Timestamp:    job.EnqueuedAt,
+			Body:         body,
+		},
+	); err != nil {
+		return fmt.Errorf("failed to publish delivery job: %w", err)
+	}
+	return nil
+}
+
+// Consume delivers jobs to handler with Concurrency workers until ctx is
+// cancelled or the queue is closed, reconnecting if the connection is lost.
+func (q *PriorityQueue) Consume(ctx context.Context, handler DeliveryHandler) error {
+	lanes, err := q.lanes()
+	if err != nil {
+		return err
+	}
+
+	for i := 0; i < q.config.Concurrency; i++ {
+		go q.work(ctx, handler)
+	}
+
+	go func() {
+		for {
+			q.receive(ctx, lanes)
+
+			// The delivery channels closed: stop, or reconnect after a delay
+			for {
+				q.mu.Lock()
+				closed := q.closed
+				q.mu.Unlock()
+				if closed || q.drainer.Stopped() {
+					return
+				}
+
+				select {
+				case <-ctx.Done():
+					return
+				case <-time.After(q.config.ReconnectDelay):
+				}
+
+				if err := q.connect(); err != nil {
+					continue
+				}
+				if lanes, err = q.lanes(); err == nil {
+					break
+				}
+			}
+		}
+	}()
+	return nil
+}
+
+// lanes starts consuming every priority queue. Jobs waiting from a previous
+// connection are dropped, since they can no longer be acknowledged and the
+// broker delivers them again.
+func (q *PriorityQueue) lanes() (map[domain.NotificationPriority]<-chan amqp.Delivery, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	q.pending = make(map[domain.NotificationPriority][]pendingDelivery)
+
+	// Each lane may hold enough jobs to keep every worker busy
+	if err := q.channel.Qos(q.config.Concurrency, 0, false); err != nil {
+		return nil, fmt.Errorf("failed to set QoS: %w", err)
+	}
+
+	lanes := make(map[domain.NotificationPriority]<-chan amqp.Delivery, len(domain.DispatchPriorities))
+	for _, priority := range domain.DispatchPriorities {
+		deliveries, err := q.channel.Consume(
+			DeliveryQueueName(priority),
+			q.consumerTag(priority),
+			false, // auto-ack
+			false, // exclusive
+			false, // no-local
+			false, // no-wait
+			nil,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to start consuming %s: %w", DeliveryQueueName(priority), err)
+		}
+		lanes[priority] = deliveries
+	}
+	return lanes, nil
+}
+
+// consumerTag is the consumer tag of the lane of a priority.
+func (q *PriorityQueue) consumerTag(priority domain.NotificationPriority) string {
+	return q.tag + "." + priority.String()
+}
+
+// receive moves consumed jobs to the pending lanes until every delivery
+// channel closes or ctx is cancelled.
+func (q *PriorityQueue) receive(ctx context.Context, lanes map[domain.NotificationPriority]<-chan amqp.Delivery) {
+	var wg sync.WaitGroup
+	for priority, deliveries := range lanes {
+		wg.Add(1)
+		go func(priority domain.NotificationPriority, deliveries <-chan amqp.Delivery) {
+			defer wg.Done()
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case d, ok := <-deliveries:
+					if !ok {
+						return
+					}
+					q.push(priority, d)
+				}
+			}
+		}(priority, deliveries)
+	}
+	wg.Wait()
+}
+
+func (q *PriorityQueue) push(priority domain.NotificationPriority, d amqp.Delivery) {
+	enqueuedAt := d.Timestamp
+	if enqueuedAt.IsZero() {
+		enqueuedAt = time.Now()
+	}
+
+	q.mu.Lock()
+	// Jobs prefetched after shutdown began go back to the queue
+	if q.drainer.Stopped() {
+		q.mu.Unlock()
+		d.Nack(false, true)
+		return
+	}
+	q.pending[priority] = append(q.pending[priority], pendingDelivery{delivery: d, enqueuedAt: enqueuedAt})
+	q.mu.Unlock()
+
+	// Wake a worker; if all wake-ups are taken every worker is already busy
+	select {
+	case q.ready <- struct{}{}:
+	default:
+	}
+}
+
+// next takes the job the dispatch scheduler picks, if any job is waiting.
+func (q *PriorityQueue) next() (amqp.Delivery, bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	oldest := make(map[domain.NotificationPriority]time.Time, len(q.pending))
+	for priority, waiting := range q.pending {
+		if len(waiting) > 0 {
+			oldest[priority] = waiting[0].enqueuedAt
+		}
+	}
+
+	priority, ok := q.scheduler.Next(oldest, time.Now())
+	if !ok {
+		return amqp.Delivery{}, false
+	}
+	d := q.pending[priority][0].delivery
+	q.pending[priority] = q.pending[priority][1:]
+	return d, true
+}
+
+// work runs a delivery worker until ctx is cancelled.
+func (q *PriorityQueue) work(ctx context.Context, handler DeliveryHandler) {
+	for {
+		if d, ok := q.next(); ok {
+			if !q.drainer.Begin() {
+				d.Nack(false, true)
+				continue
+			}
+			q.handle(ctx, d, handler)
+			q.drainer.End()
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-q.ready:
+		}
+	}
+}
+
+func (q *PriorityQueue) handle(ctx context.Context, d amqp.Delivery, handler DeliveryHandler) {
+	var job ports.DeliveryJob
+	if err := json.Unmarshal(d.Body, &job); err != nil {
+		// A malformed job never succeeds, so it is not requeued
+		d.Nack(false, false)
+		return
+	}
+
+	if err := handler(ctx, &job); err != nil {
+		d.Nack(false, !d.Redelivered)
+		return
+	}
+	d.Ack(false)
+}
+
+// Shutdown stops consuming, requeues the jobs waiting for a worker and waits
+// for the jobs being delivered before closing the connection.
+func (q *PriorityQueue) Shutdown(ctx context.Context) error {
+	q.drainer.Stop()
+
+	q.mu.Lock()
+	ch := q.channel
+	pending := q.pending
+	q.pending = make(map[domain.NotificationPriority][]pendingDelivery)
+	q.mu.Unlock()
+
+	if ch != nil {
+		for _, priority := range domain.DispatchPriorities {
+			ch.Cancel(q.consumerTag(priority), false)
+		}
+	}
+	for _, waiting := range pending {
+		for _, p := range waiting {
+			p.delivery.Nack(false, true)
+		}
+	}
+
+	err := q.drainer.Wait(ctx)
+	if closeErr := q.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
+
+// Close closes the queue connection.
+func (q *PriorityQueue) Close() error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if q.closed {
+		return nil
+	}
+	q.closed = true
+
+	if q.channel != nil {
+		q.channel.Close()
+	}
+	if q.conn != nil {
+		return q.conn.Close()
+	}
+	return nil
+}
+
+// Ensure PriorityQueue implements ports.DeliveryQueue
+var _ ports.DeliveryQueue = (*PriorityQueue)(nil)
@@ -0,0 +1,199 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+
+	"github.com/kilang-desa-murni/crm/internal/notification/domain"
+)
+
+// ============================================================================
+// Archived Email Repository Implementation
+// ============================================================================
+
+// ArchivedEmailRepository implements domain.ArchivedEmailRepository using PostgreSQL.
+type ArchivedEmailRepository struct {
+	db *sqlx.DB
+}
+
+// NewArchivedEmailRepository creates a new ArchivedEmailRepository instance.
+func NewArchivedEmailRepository(db *sqlx.DB) *ArchivedEmailRepository {
+	return &ArchivedEmailRepository{db: db}
+}
+
+// archivedEmailSummaryColumns are the columns of an archived email without
+// its MIME message, for searches.
+const archivedEmailSummaryColumns = `id, tenant_id, notification_id, message_id, from_address, recipients, subject,
+			provider, provider_message_id, size_bytes, checksum, storage_key, sent_at, expires_at, created_at`
+
+const archivedEmailColumns = archivedEmailSummaryColumns + `, mime`
+
+// archivedEmailRow is the database row of an archived email.
+type archivedEmailRow struct {
+	domain.ArchivedEmail
+	RecipientList StringArray `db:"recipients"`
+}
+
+func (r archivedEmailRow) toDomain() *domain.ArchivedEmail {
+	email := r.ArchivedEmail
+	email.Recipients = []string(r.RecipientList)
+	return &email
+}
+
+// Create stores an archived email.
+func (r *ArchivedEmailRepository) Create(ctx context.Context, email *domain.ArchivedEmail) error {
+	executor := getExecutor(ctx, r.db)
+
+	query := `
+		INSERT INTO notification_email_archive (` + archivedEmailColumns + `)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16)`
+
+	_, err := executor.ExecContext(ctx, query,
+		email.ID, email.TenantID, email.NotificationID, email.MessageID, email.FromAddress,
+		pq.Array(email.Recipients), email.Subject, email.Provider, email.ProviderMessageID,
+		email.SizeBytes, email.Checksum, email.StorageKey, email.SentAt, email.ExpiresAt,
+		email.CreatedAt, email.MIME,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to archive email: %w", err)
+	}
+	return nil
+}
+
+// FindByID finds an archived email by ID, including its inline MIME message.
+func (r *ArchivedEmailRepository) FindByID(ctx context.Context, id uuid.UUID) (*domain.ArchivedEmail, error) {
+	return r.findOne(ctx, `WHERE id = $1`, id)
+}
+
+// FindByNotification finds the archived email of a notification.
+func (r *ArchivedEmailRepository) FindByNotification(ctx context.Context, notificationID uuid.UUID) (*domain.ArchivedEmail, error) {
+	return r.findOne(ctx, `WHERE notification_id = $1 ORDER BY created_at DESC LIMIT 1`, notificationID)
+}
+
+func (r *ArchivedEmailRepository) findOne(ctx context.Context, where string, arg interface{}) (*domain.ArchivedEmail, error) {
+	executor := getExecutor(ctx, r.db)
+
+	query := `SELECT ` + archivedEmailColumns + ` FROM notification_email_archive ` + where
+
+	var row archivedEmailRow
+	if err := sqlx.GetContext(ctx, executor, &row, query, arg); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, domain.ErrArchivedEmailNotFound
+		}
+		return nil, fmt.Errorf("failed to find archived email: %w", err)
+	}
+	return row.toDomain(), nil
+}
+
+// Search searches a tenant's archived emails by recipient, subject and sent
+// date, newest first. MIME messages are not loaded.
+func (r *ArchivedEmailRepository) Search(ctx context.Context, filter domain.ArchivedEmailFilter) (*domain.ArchivedEmailList, error) {
+	executor := getExecutor(ctx, r.db)
+
+	conditions := []string{"tenant_id = $1"}
+	args := []interface{}{filter.TenantID}
+	argIndex := 2
+
+	if filter.Recipient != "" {
+		conditions = append(conditions, fmt.Sprintf(
+			"EXISTS (SELECT 1 FROM unnest(recipients) AS recipient WHERE recipient ILIKE $%d)", argIndex))
+		args = append(args, "%"+escapeLike(filter.Recipient)+"%")
+		argIndex++
+	}
+	if filter.Subject != "" {
+		conditions = append(conditions, fmt.Sprintf("subject ILIKE $%d", argIndex))
+		args = append(args, "%"+escapeLike(filter.Subject)+"%")
+		argIndex++
+	}
+	if filter.SentAfter != nil {
+		conditions = append(conditions, fmt.Sprintf("sent_at >= $%d", argIndex))
+		args = append(args, *filter.SentAfter)
+		argIndex++
+	}
+	if filter.SentBefore != nil {
+		conditions = append(conditions, fmt.Sprintf("sent_at < $%d", argIndex))
+		args = append(args, *filter.SentBefore)
+		argIndex++
+	}
+	where := "WHERE " + strings.Join(conditions, " AND ")
+
+	var total int64
+	countQuery := `SELECT COUNT(*) FROM notification_email_archive ` + where
+	if err := sqlx.GetContext(ctx, executor, &total, countQuery, args...); err != nil {
+		return nil, fmt.Errorf("failed to count archived emails: %w", err)
+	}
+
+	query := fmt.Sprintf(`
+		SELECT %s
+		FROM notification_email_archive
+		%s
+		ORDER BY sent_at DESC, id DESC
+		LIMIT $%d OFFSET $%d`, archivedEmailSummaryColumns, where, argIndex, argIndex+1)
+	args = append(args, filter.Limit, filter.Offset)
+
+	var rows []archivedEmailRow
+	if err := sqlx.SelectContext(ctx, executor, &rows, query, args...); err != nil {
+		return nil, fmt.Errorf("failed to search archived emails: %w", err)
+	}
+
+	emails := make([]*domain.ArchivedEmail, len(rows))
+	for i, row := range rows {
+		emails[i] = row.toDomain()
+	}
+	return &domain.ArchivedEmailList{
+		Emails:  emails,
+		Total:   total,
+		Offset:  filter.Offset,
+		Limit:   filter.Limit,
+		HasMore: int64(filter.Offset+len(emails)) < total,
+	}, nil
+}
+
+// FindExpired finds archived emails whose retention has passed, oldest first.
+func (r *ArchivedEmailRepository) FindExpired(ctx context.Context, now time.Time, limit int) ([]*domain.ArchivedEmail, error) {
+	executor := getExecutor(ctx, r.db)
+
+	query := `
+		SELECT ` + archivedEmailSummaryColumns + `
+		FROM notification_email_archive
+		WHERE expires_at <= $1
+		ORDER BY expires_at ASC
+		LIMIT $2`
+
+	var rows []archivedEmailRow
+	if err := sqlx.SelectContext(ctx, executor, &rows, query, now, limit); err != nil {
+		return nil, fmt.Errorf("failed to find expired archived emails: %w", err)
+	}
+
+	emails := make([]*domain.ArchivedEmail, len(rows))
+	for i, row := range rows {
+		emails[i] = row.toDomain()
+	}
+	return emails, nil
+}
+
+// DeleteByIDs permanently deletes archived emails.
+func (r *ArchivedEmailRepository) DeleteByIDs(ctx context.Context, ids []uuid.UUID) (int64, error) {
+	if len(ids) == 0 {
+		return 0, nil
+	}
+	executor := getExecutor(ctx, r.db)
+
+	result, err := executor.ExecContext(ctx, `DELETE FROM notification_email_archive WHERE id = ANY($1)`, pq.Array(ids))
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete archived emails: %w", err)
+	}
+	return result.RowsAffected()
+}
+
+// escapeLike escapes the LIKE wildcards in a search term.
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
+}
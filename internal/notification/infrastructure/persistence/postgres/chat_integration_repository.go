@@ -0,0 +1,207 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+
+	"github.com/kilang-desa-murni/crm/internal/notification/domain"
+)
+
+// ============================================================================
+// Chat Integration Repository Implementation
+// ============================================================================
+
+// ChatIntegrationRepository implements domain.ChatIntegrationRepository using
+// PostgreSQL.
+type ChatIntegrationRepository struct {
+	db *sqlx.DB
+}
+
+// NewChatIntegrationRepository creates a new ChatIntegrationRepository instance.
+func NewChatIntegrationRepository(db *sqlx.DB) *ChatIntegrationRepository {
+	return &ChatIntegrationRepository{db: db}
+}
+
+const chatIntegrationColumns = `id, tenant_id, name, platform, webhook_url, triggers, big_lead_threshold, templates,
+			enabled, last_posted_at, last_error, created_by, created_at, updated_at`
+
+// chatIntegrationRow is the database row of a chat integration. Templates
+// are stored as JSONB.
+type chatIntegrationRow struct {
+	ID               uuid.UUID      `db:"id"`
+	TenantID         uuid.UUID      `db:"tenant_id"`
+	Name             string         `db:"name"`
+	Platform         string         `db:"platform"`
+	WebhookURL       string         `db:"webhook_url"`
+	Triggers         StringArray    `db:"triggers"`
+	BigLeadThreshold float64        `db:"big_lead_threshold"`
+	Templates        []byte         `db:"templates"`
+	Enabled          bool           `db:"enabled"`
+	LastPostedAt     *time.Time     `db:"last_posted_at"`
+	LastError        sql.NullString `db:"last_error"`
+	CreatedBy        *uuid.UUID     `db:"created_by"`
+	CreatedAt        time.Time      `db:"created_at"`
+	UpdatedAt        time.Time      `db:"updated_at"`
+}
+
+func (r chatIntegrationRow) toDomain() *domain.ChatIntegration {
+	integration := &domain.ChatIntegration{
+		ID:               r.ID,
+		TenantID:         r.TenantID,
+		Name:             r.Name,
+		Platform:         domain.ChatPlatform(r.Platform),
+		WebhookURL:       r.WebhookURL,
+		Triggers:         make([]domain.ChatTrigger, len(r.Triggers)),
+		BigLeadThreshold: r.BigLeadThreshold,
+		Templates:        make(map[domain.ChatTrigger]domain.ChatTemplate),
+		Enabled:          r.Enabled,
+		LastPostedAt:     r.LastPostedAt,
+		LastError:        r.LastError.String,
+		CreatedBy:        r.CreatedBy,
+		CreatedAt:        r.CreatedAt,
+		UpdatedAt:        r.UpdatedAt,
+	}
+	for i, trigger := range r.Triggers {
+		integration.Triggers[i] = domain.ChatTrigger(trigger)
+	}
+	if len(r.Templates) > 0 {
+		json.Unmarshal(r.Templates, &integration.Templates)
+	}
+	return integration
+}
+
+// chatIntegrationArgs returns the column values of an integration in the
+// order of chatIntegrationColumns.
+func chatIntegrationArgs(integration *domain.ChatIntegration) ([]interface{}, error) {
+	triggers := make([]string, len(integration.Triggers))
+	for i, trigger := range integration.Triggers {
+		triggers[i] = string(trigger)
+	}
+	templates, err := json.Marshal(integration.Templates)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal templates: %w", err)
+	}
+	return []interface{}{
+		integration.ID, integration.TenantID, integration.Name, integration.Platform, integration.WebhookURL,
+		pq.Array(triggers), integration.BigLeadThreshold, templates, integration.Enabled,
+		integration.LastPostedAt, integration.LastError, integration.CreatedBy, integration.CreatedAt,
+		integration.UpdatedAt,
+	}, nil
+}
+
+// Create stores a new integration.
+func (r *ChatIntegrationRepository) Create(ctx context.Context, integration *domain.ChatIntegration) error {
+	executor := getExecutor(ctx, r.db)
+
+	args, err := chatIntegrationArgs(integration)
+	if err != nil {
+		return err
+	}
+	query := `
+		INSERT INTO notification_chat_integrations (` + chatIntegrationColumns + `)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14)`
+	if _, err := executor.ExecContext(ctx, query, args...); err != nil {
+		return fmt.Errorf("failed to create chat integration: %w", err)
+	}
+	return nil
+}
+
+// Update stores the changes to an integration.
+func (r *ChatIntegrationRepository) Update(ctx context.Context, integration *domain.ChatIntegration) error {
+	executor := getExecutor(ctx, r.db)
+
+	args, err := chatIntegrationArgs(integration)
+	if err != nil {
+		return err
+	}
+	// created_by and created_at never change
+	query := `
+		UPDATE notification_chat_integrations SET
+			name = $3, platform = $4, webhook_url = $5, triggers = $6, big_lead_threshold = $7,
+			templates = $8, enabled = $9, last_posted_at = $10, last_error = $11, updated_at = $12
+		WHERE id = $1 AND tenant_id = $2`
+	result, err := executor.ExecContext(ctx, query, append(args[:11:11], integration.UpdatedAt)...)
+	if err != nil {
+		return fmt.Errorf("failed to update chat integration: %w", err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to update chat integration: %w", err)
+	}
+	if rows == 0 {
+		return domain.ErrChatIntegrationNotFound
+	}
+	return nil
+}
+
+// FindByID finds an integration of a tenant.
+func (r *ChatIntegrationRepository) FindByID(ctx context.Context, tenantID, id uuid.UUID) (*domain.ChatIntegration, error) {
+	executor := getExecutor(ctx, r.db)
+
+	query := `SELECT ` + chatIntegrationColumns + ` FROM notification_chat_integrations WHERE id = $1 AND tenant_id = $2`
+
+	var row chatIntegrationRow
+	if err := sqlx.GetContext(ctx, executor, &row, query, id, tenantID); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, domain.ErrChatIntegrationNotFound
+		}
+		return nil, fmt.Errorf("failed to find chat integration: %w", err)
+	}
+	return row.toDomain(), nil
+}
+
+// FindByTenant finds the integrations of a tenant, oldest first.
+func (r *ChatIntegrationRepository) FindByTenant(ctx context.Context, tenantID uuid.UUID) ([]*domain.ChatIntegration, error) {
+	return r.find(ctx, `WHERE tenant_id = $1 ORDER BY created_at`, tenantID)
+}
+
+// FindEnabledByTrigger finds the enabled integrations of a tenant that post
+// about a trigger.
+func (r *ChatIntegrationRepository) FindEnabledByTrigger(ctx context.Context, tenantID uuid.UUID, trigger domain.ChatTrigger) ([]*domain.ChatIntegration, error) {
+	return r.find(ctx, `WHERE tenant_id = $1 AND enabled AND $2 = ANY(triggers) ORDER BY created_at`, tenantID, string(trigger))
+}
+
+func (r *ChatIntegrationRepository) find(ctx context.Context, where string, args ...interface{}) ([]*domain.ChatIntegration, error) {
+	executor := getExecutor(ctx, r.db)
+
+	query := `SELECT ` + chatIntegrationColumns + ` FROM notification_chat_integrations ` + where
+
+	var rows []chatIntegrationRow
+	if err := sqlx.SelectContext(ctx, executor, &rows, query, args...); err != nil {
+		return nil, fmt.Errorf("failed to find chat integrations: %w", err)
+	}
+	integrations := make([]*domain.ChatIntegration, len(rows))
+	for i, row := range rows {
+		integrations[i] = row.toDomain()
+	}
+	return integrations, nil
+}
+
+// Delete removes an integration.
+func (r *ChatIntegrationRepository) Delete(ctx context.Context, tenantID, id uuid.UUID) error {
+	executor := getExecutor(ctx, r.db)
+
+	query := `DELETE FROM notification_chat_integrations WHERE id = $1 AND tenant_id = $2`
+	result, err := executor.ExecContext(ctx, query, id, tenantID)
+	if err != nil {
+		return fmt.Errorf("failed to delete chat integration: %w", err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to delete chat integration: %w", err)
+	}
+	if rows == 0 {
+		return domain.ErrChatIntegrationNotFound
+	}
+	return nil
+}
+
+// Ensure ChatIntegrationRepository implements domain.ChatIntegrationRepository
+var _ domain.ChatIntegrationRepository = (*ChatIntegrationRepository)(nil)
@@ -0,0 +1,93 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+
+	"github.com/kilang-desa-murni/crm/internal/notification/domain"
+)
+
+// ============================================================================
+// Delivery Log Repository Implementation
+// ============================================================================
+
+// DeliveryLogRepository implements domain.DeliveryLogRepository using PostgreSQL.
+type DeliveryLogRepository struct {
+	db *sqlx.DB
+}
+
+// NewDeliveryLogRepository creates a new DeliveryLogRepository instance.
+func NewDeliveryLogRepository(db *sqlx.DB) *DeliveryLogRepository {
+	return &DeliveryLogRepository{db: db}
+}
+
+const deliveryLogColumns = `id, notification_id, tenant_id, channel, source, provider, provider_message_id,
+			status, attempt_number, request, response, error_code, error_message, latency_ms, created_at`
+
+// Create creates a delivery log entry.
+func (r *DeliveryLogRepository) Create(ctx context.Context, log *domain.DeliveryLog) error {
+	executor := getExecutor(ctx, r.db)
+
+	query := `
+		INSERT INTO notification_delivery_logs (` + deliveryLogColumns + `)
+		VALUES (
+			:id, :notification_id, :tenant_id, :channel, :source, :provider, :provider_message_id,
+			:status, :attempt_number, :request, :response, :error_code, :error_message, :latency_ms, :created_at
+		)`
+
+	if _, err := sqlx.NamedExecContext(ctx, executor, query, log); err != nil {
+		return fmt.Errorf("failed to create delivery log: %w", err)
+	}
+	return nil
+}
+
+// FindByNotification finds logs for a notification, oldest first.
+func (r *DeliveryLogRepository) FindByNotification(ctx context.Context, notificationID uuid.UUID) ([]*domain.DeliveryLog, error) {
+	executor := getExecutor(ctx, r.db)
+
+	query := `
+		SELECT ` + deliveryLogColumns + `
+		FROM notification_delivery_logs
+		WHERE notification_id = $1
+		ORDER BY created_at ASC`
+
+	var logs []*domain.DeliveryLog
+	if err := sqlx.SelectContext(ctx, executor, &logs, query, notificationID); err != nil {
+		return nil, fmt.Errorf("failed to find delivery logs: %w", err)
+	}
+	return logs, nil
+}
+
+// FindByTenant finds logs for a tenant within a time range, newest first.
+func (r *DeliveryLogRepository) FindByTenant(ctx context.Context, tenantID uuid.UUID, startTime, endTime time.Time, limit int) ([]*domain.DeliveryLog, error) {
+	executor := getExecutor(ctx, r.db)
+
+	query := `
+		SELECT ` + deliveryLogColumns + `
+		FROM notification_delivery_logs
+		WHERE tenant_id = $1 AND created_at >= $2 AND created_at < $3
+		ORDER BY created_at DESC
+		LIMIT $4`
+
+	var logs []*domain.DeliveryLog
+	if err := sqlx.SelectContext(ctx, executor, &logs, query, tenantID, startTime, endTime, limit); err != nil {
+		return nil, fmt.Errorf("failed to find delivery logs: %w", err)
+	}
+	return logs, nil
+}
+
+// DeleteOld deletes logs older than a specified date.
+func (r *DeliveryLogRepository) DeleteOld(ctx context.Context, before time.Time) (int64, error) {
+	executor := getExecutor(ctx, r.db)
+
+	query := `DELETE FROM notification_delivery_logs WHERE created_at < $1`
+	result, err := executor.ExecContext(ctx, query, before)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete old delivery logs: %w", err)
+	}
+	return result.RowsAffected()
+}
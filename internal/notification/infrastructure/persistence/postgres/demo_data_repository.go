@@ -0,0 +1,62 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+
+	"github.com/kilang-desa-murni/crm/internal/notification/domain"
+)
+
+// ============================================================================
+// Demo Data Repository Implementation
+// ============================================================================
+
+// DemoDataRepository implements domain.DemoDataRepository using PostgreSQL.
+type DemoDataRepository struct {
+	db *sqlx.DB
+}
+
+// NewDemoDataRepository creates a new DemoDataRepository instance.
+func NewDemoDataRepository(db *sqlx.DB) *DemoDataRepository {
+	return &DemoDataRepository{db: db}
+}
+
+// Ensure DemoDataRepository implements domain.DemoDataRepository.
+var _ domain.DemoDataRepository = (*DemoDataRepository)(nil)
+
+// FindExisting returns which of the notifications exist, including
+// soft-deleted ones as they still hold their ID.
+func (r *DemoDataRepository) FindExisting(ctx context.Context, tenantID uuid.UUID, ids []uuid.UUID) (map[uuid.UUID]bool, error) {
+	query := `SELECT id FROM notifications WHERE tenant_id = $1 AND id = ANY($2)`
+
+	var found []uuid.UUID
+	if err := sqlx.SelectContext(ctx, getExecutor(ctx, r.db), &found, query, tenantID, pq.Array(ids)); err != nil {
+		return nil, fmt.Errorf("failed to find demo notifications: %w", err)
+	}
+
+	existing := make(map[uuid.UUID]bool, len(found))
+	for _, id := range found {
+		existing[id] = true
+	}
+	return existing, nil
+}
+
+// Purge permanently deletes the notifications.
+func (r *DemoDataRepository) Purge(ctx context.Context, tenantID uuid.UUID, ids []uuid.UUID) (int64, error) {
+	query := `DELETE FROM notifications WHERE tenant_id = $1 AND id = ANY($2)`
+
+	result, err := getExecutor(ctx, r.db).ExecContext(ctx, query, tenantID, pq.Array(ids))
+	if err != nil {
+		return 0, fmt.Errorf("failed to purge demo notifications: %w", err)
+	}
+
+	deleted, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to count purged demo notifications: %w", err)
+	}
+	return deleted, nil
+}
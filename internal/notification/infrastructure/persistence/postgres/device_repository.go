@@ -0,0 +1,131 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+
+	"github.com/kilang-desa-murni/crm/internal/notification/domain"
+)
+
+// ============================================================================
+// Device Repository Implementation
+// ============================================================================
+
+// DeviceRepository implements domain.DeviceRepository using PostgreSQL. The
+// notification_devices table has a unique index on token.
+type DeviceRepository struct {
+	db *sqlx.DB
+}
+
+// NewDeviceRepository creates a new DeviceRepository instance.
+func NewDeviceRepository(db *sqlx.DB) *DeviceRepository {
+	return &DeviceRepository{db: db}
+}
+
+const deviceColumns = `id, tenant_id, user_id, platform, token, app_version, p256dh, auth, expires_at,
+			created_at, last_seen_at`
+
+// Register stores a device, or refreshes the device already registered with
+// its token. The ID and creation time of a refreshed device are kept.
+func (r *DeviceRepository) Register(ctx context.Context, device *domain.Device) (*domain.Device, error) {
+	executor := getExecutor(ctx, r.db)
+
+	query := `
+		INSERT INTO notification_devices (` + deviceColumns + `)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+		ON CONFLICT (token) DO UPDATE SET
+			tenant_id = EXCLUDED.tenant_id,
+			user_id = EXCLUDED.user_id,
+			platform = EXCLUDED.platform,
+			app_version = EXCLUDED.app_version,
+			p256dh = EXCLUDED.p256dh,
+			auth = EXCLUDED.auth,
+			expires_at = EXCLUDED.expires_at,
+			last_seen_at = EXCLUDED.last_seen_at
+		RETURNING ` + deviceColumns
+
+	var stored domain.Device
+	err := sqlx.GetContext(ctx, executor, &stored, query,
+		device.ID, device.TenantID, device.UserID, device.Platform, device.Token,
+		device.AppVersion, device.P256DH, device.Auth, device.ExpiresAt, device.CreatedAt, device.LastSeenAt,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to register device: %w", err)
+	}
+	return &stored, nil
+}
+
+// FindByUser finds the devices of a user, most recently seen first.
+func (r *DeviceRepository) FindByUser(ctx context.Context, tenantID, userID uuid.UUID) ([]*domain.Device, error) {
+	executor := getExecutor(ctx, r.db)
+
+	query := `
+		SELECT ` + deviceColumns + `
+		FROM notification_devices
+		WHERE tenant_id = $1 AND user_id = $2
+		ORDER BY last_seen_at DESC`
+
+	var devices []*domain.Device
+	if err := sqlx.SelectContext(ctx, executor, &devices, query, tenantID, userID); err != nil {
+		return nil, fmt.Errorf("failed to find devices: %w", err)
+	}
+	return devices, nil
+}
+
+// Delete unregisters a device of a user.
+func (r *DeviceRepository) Delete(ctx context.Context, tenantID, userID, id uuid.UUID) error {
+	executor := getExecutor(ctx, r.db)
+
+	query := `DELETE FROM notification_devices WHERE id = $1 AND tenant_id = $2 AND user_id = $3`
+	result, err := executor.ExecContext(ctx, query, id, tenantID, userID)
+	if err != nil {
+		return fmt.Errorf("failed to delete device: %w", err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to delete device: %w", err)
+	}
+	if rows == 0 {
+		return domain.ErrDeviceNotFound
+	}
+	return nil
+}
+
+// DeleteByTokens unregisters the devices with the given tokens.
+func (r *DeviceRepository) DeleteByTokens(ctx context.Context, tenantID uuid.UUID, tokens []string) (int64, error) {
+	if len(tokens) == 0 {
+		return 0, nil
+	}
+	executor := getExecutor(ctx, r.db)
+
+	query := `DELETE FROM notification_devices WHERE tenant_id = $1 AND token = ANY($2)`
+	result, err := executor.ExecContext(ctx, query, tenantID, pq.Array(tokens))
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete devices: %w", err)
+	}
+	return result.RowsAffected()
+}
+
+// DeleteExpired unregisters up to limit devices whose push subscription
+// expired before now.
+func (r *DeviceRepository) DeleteExpired(ctx context.Context, now time.Time, limit int) (int64, error) {
+	executor := getExecutor(ctx, r.db)
+
+	query := `
+		DELETE FROM notification_devices
+		WHERE id IN (
+			SELECT id FROM notification_devices
+			WHERE expires_at IS NOT NULL AND expires_at <= $1
+			LIMIT $2
+		)`
+	result, err := executor.ExecContext(ctx, query, now, limit)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete expired devices: %w", err)
+	}
+	return result.RowsAffected()
+}
@@ -0,0 +1,368 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+
+	"github.com/kilang-desa-murni/crm/internal/notification/domain"
+)
+
+// ============================================================================
+// Escalation Policy Repository Implementation
+// ============================================================================
+
+// EscalationPolicyRepository implements domain.EscalationPolicyRepository
+// using PostgreSQL.
+type EscalationPolicyRepository struct {
+	db *sqlx.DB
+}
+
+// NewEscalationPolicyRepository creates a new EscalationPolicyRepository
+// instance.
+func NewEscalationPolicyRepository(db *sqlx.DB) *EscalationPolicyRepository {
+	return &EscalationPolicyRepository{db: db}
+}
+
+const escalationPolicyColumns = `id, tenant_id, name, event_types, levels, max_cycles, enabled, created_by,
+			created_at, updated_at`
+
+// escalationPolicyRow is the database row of an escalation policy. Levels
+// are stored as JSONB.
+type escalationPolicyRow struct {
+	ID         uuid.UUID   `db:"id"`
+	TenantID   uuid.UUID   `db:"tenant_id"`
+	Name       string      `db:"name"`
+	EventTypes StringArray `db:"event_types"`
+	Levels     []byte      `db:"levels"`
+	MaxCycles  int         `db:"max_cycles"`
+	Enabled    bool        `db:"enabled"`
+	CreatedBy  *uuid.UUID  `db:"created_by"`
+	CreatedAt  time.Time   `db:"created_at"`
+	UpdatedAt  time.Time   `db:"updated_at"`
+}
+
+func (r escalationPolicyRow) toDomain() *domain.EscalationPolicy {
+	policy := &domain.EscalationPolicy{
+		ID:         r.ID,
+		TenantID:   r.TenantID,
+		Name:       r.Name,
+		EventTypes: []string(r.EventTypes),
+		Levels:     []domain.EscalationLevel{},
+		MaxCycles:  r.MaxCycles,
+		Enabled:    r.Enabled,
+		CreatedBy:  r.CreatedBy,
+		CreatedAt:  r.CreatedAt,
+		UpdatedAt:  r.UpdatedAt,
+	}
+	if len(r.Levels) > 0 {
+		json.Unmarshal(r.Levels, &policy.Levels)
+	}
+	return policy
+}
+
+// escalationPolicyArgs returns the column values of a policy in the order of
+// escalationPolicyColumns.
+func escalationPolicyArgs(policy *domain.EscalationPolicy) ([]interface{}, error) {
+	levels, err := json.Marshal(policy.Levels)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal levels: %w", err)
+	}
+	return []interface{}{
+		policy.ID, policy.TenantID, policy.Name, pq.Array(policy.EventTypes), levels, policy.MaxCycles,
+		policy.Enabled, policy.CreatedBy, policy.CreatedAt, policy.UpdatedAt,
+	}, nil
+}
+
+// Create stores a new policy.
+func (r *EscalationPolicyRepository) Create(ctx context.Context, policy *domain.EscalationPolicy) error {
+	executor := getExecutor(ctx, r.db)
+
+	args, err := escalationPolicyArgs(policy)
+	if err != nil {
+		return err
+	}
+	query := `
+		INSERT INTO notification_escalation_policies (` + escalationPolicyColumns + `)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)`
+	if _, err := executor.ExecContext(ctx, query, args...); err != nil {
+		return fmt.Errorf("failed to create escalation policy: %w", err)
+	}
+	return nil
+}
+
+// Update stores the changes to a policy.
+func (r *EscalationPolicyRepository) Update(ctx context.Context, policy *domain.EscalationPolicy) error {
+	executor := getExecutor(ctx, r.db)
+
+	args, err := escalationPolicyArgs(policy)
+	if err != nil {
+		return err
+	}
+	// created_by and created_at never change
+	query := `
+		UPDATE notification_escalation_policies SET
+			name = $3, event_types = $4, levels = $5, max_cycles = $6, enabled = $7, updated_at = $8
+		WHERE id = $1 AND tenant_id = $2`
+	result, err := executor.ExecContext(ctx, query, append(args[:7:7], policy.UpdatedAt)...)
+	if err != nil {
+		return fmt.Errorf("failed to update escalation policy: %w", err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to update escalation policy: %w", err)
+	}
+	if rows == 0 {
+		return domain.ErrEscalationPolicyNotFound
+	}
+	return nil
+}
+
+// FindByID finds a policy of a tenant.
+func (r *EscalationPolicyRepository) FindByID(ctx context.Context, tenantID, id uuid.UUID) (*domain.EscalationPolicy, error) {
+	executor := getExecutor(ctx, r.db)
+
+	query := `SELECT ` + escalationPolicyColumns + ` FROM notification_escalation_policies WHERE id = $1 AND tenant_id = $2`
+
+	var row escalationPolicyRow
+	if err := sqlx.GetContext(ctx, executor, &row, query, id, tenantID); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, domain.ErrEscalationPolicyNotFound
+		}
+		return nil, fmt.Errorf("failed to find escalation policy: %w", err)
+	}
+	return row.toDomain(), nil
+}
+
+// FindByTenant finds the policies of a tenant, oldest first.
+func (r *EscalationPolicyRepository) FindByTenant(ctx context.Context, tenantID uuid.UUID) ([]*domain.EscalationPolicy, error) {
+	executor := getExecutor(ctx, r.db)
+
+	query := `SELECT ` + escalationPolicyColumns + ` FROM notification_escalation_policies WHERE tenant_id = $1 ORDER BY created_at`
+
+	var rows []escalationPolicyRow
+	if err := sqlx.SelectContext(ctx, executor, &rows, query, tenantID); err != nil {
+		return nil, fmt.Errorf("failed to find escalation policies: %w", err)
+	}
+	policies := make([]*domain.EscalationPolicy, len(rows))
+	for i, row := range rows {
+		policies[i] = row.toDomain()
+	}
+	return policies, nil
+}
+
+// Delete removes a policy.
+func (r *EscalationPolicyRepository) Delete(ctx context.Context, tenantID, id uuid.UUID) error {
+	executor := getExecutor(ctx, r.db)
+
+	query := `DELETE FROM notification_escalation_policies WHERE id = $1 AND tenant_id = $2`
+	result, err := executor.ExecContext(ctx, query, id, tenantID)
+	if err != nil {
+		return fmt.Errorf("failed to delete escalation policy: %w", err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to delete escalation policy: %w", err)
+	}
+	if rows == 0 {
+		return domain.ErrEscalationPolicyNotFound
+	}
+	return nil
+}
+
+// Ensure EscalationPolicyRepository implements domain.EscalationPolicyRepository
+var _ domain.EscalationPolicyRepository = (*EscalationPolicyRepository)(nil)
+
+// ============================================================================
+// Escalation Repository Implementation
+// ============================================================================
+
+// EscalationRepository implements domain.EscalationRepository using
+// PostgreSQL. Escalations are unique by policy and event.
+type EscalationRepository struct {
+	db *sqlx.DB
+}
+
+// NewEscalationRepository creates a new EscalationRepository instance.
+func NewEscalationRepository(db *sqlx.DB) *EscalationRepository {
+	return &EscalationRepository{db: db}
+}
+
+const escalationColumns = `id, tenant_id, policy_id, event_id, event_type, data, level, cycle, status, paged_at,
+			next_escalation_at, acknowledged_by, acknowledged_at, version, created_at, updated_at`
+
+// escalationRow is the database row of an escalation. Event data is stored
+// as JSONB.
+type escalationRow struct {
+	ID               uuid.UUID  `db:"id"`
+	TenantID         uuid.UUID  `db:"tenant_id"`
+	PolicyID         uuid.UUID  `db:"policy_id"`
+	EventID          string     `db:"event_id"`
+	EventType        string     `db:"event_type"`
+	Data             []byte     `db:"data"`
+	Level            int        `db:"level"`
+	Cycle            int        `db:"cycle"`
+	Status           string     `db:"status"`
+	PagedAt          *time.Time `db:"paged_at"`
+	NextEscalationAt *time.Time `db:"next_escalation_at"`
+	AcknowledgedBy   *uuid.UUID `db:"acknowledged_by"`
+	AcknowledgedAt   *time.Time `db:"acknowledged_at"`
+	Version          int        `db:"version"`
+	CreatedAt        time.Time  `db:"created_at"`
+	UpdatedAt        time.Time  `db:"updated_at"`
+}
+
+func (r escalationRow) toDomain() *domain.Escalation {
+	escalation := &domain.Escalation{
+		ID:               r.ID,
+		TenantID:         r.TenantID,
+		PolicyID:         r.PolicyID,
+		EventID:          r.EventID,
+		EventType:        r.EventType,
+		Data:             map[string]interface{}{},
+		Level:            r.Level,
+		Cycle:            r.Cycle,
+		Status:           domain.EscalationStatus(r.Status),
+		PagedAt:          r.PagedAt,
+		NextEscalationAt: r.NextEscalationAt,
+		AcknowledgedBy:   r.AcknowledgedBy,
+		AcknowledgedAt:   r.AcknowledgedAt,
+		Version:          r.Version,
+		CreatedAt:        r.CreatedAt,
+		UpdatedAt:        r.UpdatedAt,
+	}
+	if len(r.Data) > 0 {
+		json.Unmarshal(r.Data, &escalation.Data)
+	}
+	return escalation
+}
+
+// Create stores a new escalation.
+func (r *EscalationRepository) Create(ctx context.Context, escalation *domain.Escalation) error {
+	executor := getExecutor(ctx, r.db)
+
+	data, err := json.Marshal(escalation.Data)
+	if err != nil {
+		return fmt.Errorf("failed to marshal escalation data: %w", err)
+	}
+	query := `
+		INSERT INTO notification_escalations (` + escalationColumns + `)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16)`
+	_, err = executor.ExecContext(ctx, query,
+		escalation.ID, escalation.TenantID, escalation.PolicyID, escalation.EventID, escalation.EventType, data,
+		escalation.Level, escalation.Cycle, string(escalation.Status), escalation.PagedAt,
+		escalation.NextEscalationAt, escalation.AcknowledgedBy, escalation.AcknowledgedAt, escalation.Version,
+		escalation.CreatedAt, escalation.UpdatedAt)
+	if err != nil {
+		if IsUniqueViolation(err) {
+			return domain.ErrEscalationExists
+		}
+		return fmt.Errorf("failed to create escalation: %w", err)
+	}
+	return nil
+}
+
+// Update stores the changes to an escalation if it is still at the version
+// it was loaded at, and increments its version.
+func (r *EscalationRepository) Update(ctx context.Context, escalation *domain.Escalation) error {
+	executor := getExecutor(ctx, r.db)
+
+	query := `
+		UPDATE notification_escalations SET
+			level = $4, cycle = $5, status = $6, paged_at = $7, next_escalation_at = $8,
+			acknowledged_by = $9, acknowledged_at = $10, version = version + 1, updated_at = $11
+		WHERE id = $1 AND tenant_id = $2 AND version = $3`
+	result, err := executor.ExecContext(ctx, query,
+		escalation.ID, escalation.TenantID, escalation.Version, escalation.Level, escalation.Cycle,
+		string(escalation.Status), escalation.PagedAt, escalation.NextEscalationAt, escalation.AcknowledgedBy,
+		escalation.AcknowledgedAt, escalation.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to update escalation: %w", err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to update escalation: %w", err)
+	}
+	if rows == 0 {
+		return domain.ErrConcurrentModification
+	}
+	escalation.Version++
+	return nil
+}
+
+// FindByID finds an escalation of a tenant.
+func (r *EscalationRepository) FindByID(ctx context.Context, tenantID, id uuid.UUID) (*domain.Escalation, error) {
+	executor := getExecutor(ctx, r.db)
+
+	query := `SELECT ` + escalationColumns + ` FROM notification_escalations WHERE id = $1 AND tenant_id = $2`
+
+	var row escalationRow
+	if err := sqlx.GetContext(ctx, executor, &row, query, id, tenantID); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, domain.ErrEscalationNotFound
+		}
+		return nil, fmt.Errorf("failed to find escalation: %w", err)
+	}
+	return row.toDomain(), nil
+}
+
+// FindByTenant finds the escalations of a tenant with a status, or of any
+// status when empty, newest first.
+func (r *EscalationRepository) FindByTenant(ctx context.Context, tenantID uuid.UUID, status domain.EscalationStatus, offset, limit int) ([]*domain.Escalation, int64, error) {
+	executor := getExecutor(ctx, r.db)
+
+	where := `WHERE tenant_id = $1 AND ($2 = '' OR status = $2)`
+
+	var total int64
+	countQuery := `SELECT COUNT(*) FROM notification_escalations ` + where
+	if err := sqlx.GetContext(ctx, executor, &total, countQuery, tenantID, string(status)); err != nil {
+		return nil, 0, fmt.Errorf("failed to count escalations: %w", err)
+	}
+
+	query := `SELECT ` + escalationColumns + ` FROM notification_escalations ` + where + `
+		ORDER BY created_at DESC, id DESC
+		LIMIT $3 OFFSET $4`
+
+	var rows []escalationRow
+	if err := sqlx.SelectContext(ctx, executor, &rows, query, tenantID, string(status), limit, offset); err != nil {
+		return nil, 0, fmt.Errorf("failed to find escalations: %w", err)
+	}
+	escalations := make([]*domain.Escalation, len(rows))
+	for i, row := range rows {
+		escalations[i] = row.toDomain()
+	}
+	return escalations, total, nil
+}
+
+// FindDue finds open escalations of every tenant due by a time, oldest due
+// first. Escalations locked by another instance are skipped.
+func (r *EscalationRepository) FindDue(ctx context.Context, before time.Time, limit int) ([]*domain.Escalation, error) {
+	executor := getExecutor(ctx, r.db)
+
+	query := `
+		SELECT ` + escalationColumns + `
+		FROM notification_escalations
+		WHERE status = 'open' AND next_escalation_at <= $1
+		ORDER BY next_escalation_at ASC
+		LIMIT $2
+		FOR UPDATE SKIP LOCKED`
+
+	var rows []escalationRow
+	if err := sqlx.SelectContext(ctx, executor, &rows, query, before, limit); err != nil {
+		return nil, fmt.Errorf("failed to find due escalations: %w", err)
+	}
+	escalations := make([]*domain.Escalation, len(rows))
+	for i, row := range rows {
+		escalations[i] = row.toDomain()
+	}
+	return escalations, nil
+}
+
+// Ensure EscalationRepository implements domain.EscalationRepository
+var _ domain.EscalationRepository = (*EscalationRepository)(nil)
@@ -6,6 +6,7 @@ import (
 	"database/sql"
 	"encoding/json"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
@@ -271,6 +272,34 @@ func (r *NotificationRepository) FindByCode(ctx context.Context, tenantID uuid.U
 	return r.toEntity(&row), nil
 }
 
+// FindByProviderMessageID finds a notification by the message ID its provider assigned.
+func (r *NotificationRepository) FindByProviderMessageID(ctx context.Context, provider, providerMessageID string) (*domain.Notification, error) {
+	executor := getExecutor(ctx, r.db)
+
+	query := `
+		SELECT id, tenant_id, code, type, channel, priority, status,
+			template_id, template_name, recipient_id, recipient_email, recipient_phone,
+			recipient_name, device_token, subject, body, html_body, data, metadata,
+			from_address, from_name, reply_to, scheduled_at, sent_at, delivered_at,
+			read_at, failed_at, cancelled_at, attempt_count, last_attempt_at, next_retry_at,
+			error_code, error_message, provider_error, provider, provider_message_id,
+			track_opens, track_clicks, open_count, click_count,
+			source_event, source_entity_id, source_entity_type, correlation_id,
+			batch_id, batch_index, created_by, updated_by, version, created_at, updated_at, deleted_at
+		FROM notifications WHERE provider = $1 AND provider_message_id = $2 AND deleted_at IS NULL`
+
+	var row notificationRow
+	err := sqlx.GetContext(ctx, executor, &row, query, provider, providerMessageID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, domain.ErrNotificationNotFound
+		}
+		return nil, fmt.Errorf("failed to find notification by provider message ID: %w", err)
+	}
+
+	return r.toEntity(&row), nil
+}
+
 // List lists notifications with filtering and pagination.
 func (r *NotificationRepository) List(ctx context.Context, filter domain.NotificationFilter) (*domain.NotificationList, error) {
 	executor := getExecutor(ctx, r.db)
@@ -590,75 +619,146 @@ func (r *NotificationRepository) CountByChannel(ctx context.Context, tenantID uu
 	return result, nil
 }
 
+// deliveryCountColumns aggregates notifications into domain.DeliveryCounts,
+// counting them the way DeliveryCounts.Count does.
+const deliveryCountColumns = `
+			COUNT(*) as total,
+			COUNT(sent_at) as sent,
+			COUNT(*) FILTER (WHERE delivered_at IS NOT NULL OR status IN ('delivered', 'read')) as delivered,
+			COUNT(*) FILTER (WHERE open_count > 0) as opened,
+			COUNT(*) FILTER (WHERE click_count > 0) as clicked,
+			COUNT(*) FILTER (WHERE status = 'bounced') as bounced,
+			COUNT(*) FILTER (WHERE status = 'complained') as complained,
+			COUNT(*) FILTER (WHERE status = 'failed') as failed`
+
+// deliveryCountsRow is a row of deliveryCountColumns.
+type deliveryCountsRow struct {
+	Total      int64 `db:"total"`
+	Sent       int64 `db:"sent"`
+	Delivered  int64 `db:"delivered"`
+	Opened     int64 `db:"opened"`
+	Clicked    int64 `db:"clicked"`
+	Bounced    int64 `db:"bounced"`
+	Complained int64 `db:"complained"`
+	Failed     int64 `db:"failed"`
+}
+
+func (row deliveryCountsRow) toCounts() domain.DeliveryCounts {
+	return domain.DeliveryCounts(row)
+}
+
 // GetStats gets notification statistics.
-func (r *NotificationRepository) GetStats(ctx context.Context, tenantID uuid.UUID, startDate, endDate time.Time) (*domain.NotificationStats, error) {
+func (r *NotificationRepository) GetStats(ctx context.Context, query domain.NotificationStatsQuery) (*domain.NotificationStats, error) {
 	executor := getExecutor(ctx, r.db)
 
-	query := `
-		SELECT
-			COUNT(*) as total_count,
-			COALESCE(SUM(CASE WHEN status IN ('sent', 'delivered', 'read') THEN 1 ELSE 0 END), 0) as sent_count,
-			COALESCE(SUM(CASE WHEN status IN ('delivered', 'read') THEN 1 ELSE 0 END), 0) as delivered_count,
-			COALESCE(SUM(CASE WHEN status IN ('failed', 'bounced', 'complained') THEN 1 ELSE 0 END), 0) as failed_count,
-			COALESCE(SUM(CASE WHEN status IN ('pending', 'queued', 'scheduled') THEN 1 ELSE 0 END), 0) as pending_count,
-			COALESCE(SUM(open_count), 0) as total_opens,
-			COALESCE(SUM(click_count), 0) as total_clicks,
-			COALESCE(SUM(CASE WHEN status = 'bounced' THEN 1 ELSE 0 END), 0) as bounced_count,
-			COALESCE(SUM(CASE WHEN status = 'complained' THEN 1 ELSE 0 END), 0) as complained_count,
-			COALESCE(AVG(CASE WHEN delivered_at IS NOT NULL AND sent_at IS NOT NULL
-				THEN EXTRACT(EPOCH FROM (delivered_at - sent_at)) ELSE NULL END), 0) as avg_delivery_time
-		FROM notifications
-		WHERE tenant_id = $1 AND created_at >= $2 AND created_at <= $3 AND deleted_at IS NULL`
-
-	type statsResult struct {
-		TotalCount      int64   `db:"total_count"`
-		SentCount       int64   `db:"sent_count"`
-		DeliveredCount  int64   `db:"delivered_count"`
-		FailedCount     int64   `db:"failed_count"`
-		PendingCount    int64   `db:"pending_count"`
-		TotalOpens      int64   `db:"total_opens"`
-		TotalClicks     int64   `db:"total_clicks"`
-		BouncedCount    int64   `db:"bounced_count"`
-		ComplainedCount int64   `db:"complained_count"`
-		AvgDeliveryTime float64 `db:"avg_delivery_time"`
-	}
-
-	var result statsResult
-	if err := sqlx.GetContext(ctx, executor, &result, query, tenantID, startDate, endDate); err != nil {
-		return nil, fmt.Errorf("failed to get stats: %w", err)
-	}
-
-	// Calculate rates
-	var openRate, clickRate, bounceRate, complaintRate float64
-	if result.DeliveredCount > 0 {
-		openRate = float64(result.TotalOpens) / float64(result.DeliveredCount) * 100
-		clickRate = float64(result.TotalClicks) / float64(result.DeliveredCount) * 100
-	}
-	if result.SentCount > 0 {
-		bounceRate = float64(result.BouncedCount) / float64(result.SentCount) * 100
-		complaintRate = float64(result.ComplainedCount) / float64(result.SentCount) * 100
-	}
-
-	// Get counts by channel and status
-	byChannel, _ := r.CountByChannel(ctx, tenantID)
-	byStatus, _ := r.CountByStatus(ctx, tenantID)
-
-	return &domain.NotificationStats{
-		TotalCount:          result.TotalCount,
-		SentCount:           result.SentCount,
-		DeliveredCount:      result.DeliveredCount,
-		FailedCount:         result.FailedCount,
-		PendingCount:        result.PendingCount,
-		ByChannel:           byChannel,
-		ByStatus:            byStatus,
-		OpenRate:            openRate,
-		ClickRate:           clickRate,
-		BounceRate:          bounceRate,
-		ComplaintRate:       complaintRate,
-		AverageDeliveryTime: result.AvgDeliveryTime,
-		StartDate:           startDate,
-		EndDate:             endDate,
-	}, nil
+	where := `tenant_id = $1 AND created_at >= $2 AND created_at < $3 AND deleted_at IS NULL`
+	args := []interface{}{query.TenantID, query.StartDate, query.EndDate}
+	if query.Channel != nil {
+		where += ` AND channel = $4`
+		args = append(args, string(*query.Channel))
+	}
+
+	stats := &domain.NotificationStats{
+		Period:        string(query.Interval),
+		StartDate:     query.StartDate,
+		EndDate:       query.EndDate,
+		ChannelCounts: make(map[domain.NotificationChannel]domain.DeliveryCounts),
+		ByChannel:     make(map[domain.NotificationChannel]int64),
+		ByStatus:      make(map[domain.NotificationStatus]int64),
+		ByType:        make(map[domain.NotificationType]int64),
+	}
+
+	// By channel; the overall counts are their sum
+	var channelRows []struct {
+		Channel string `db:"channel"`
+		deliveryCountsRow
+	}
+	channelQuery := `SELECT channel,` + deliveryCountColumns + ` FROM notifications WHERE ` + where + ` GROUP BY channel`
+	if err := sqlx.SelectContext(ctx, executor, &channelRows, channelQuery, args...); err != nil {
+		return nil, fmt.Errorf("failed to get stats by channel: %w", err)
+	}
+	for _, row := range channelRows {
+		channel := domain.NotificationChannel(row.Channel)
+		stats.ChannelCounts[channel] = row.toCounts()
+		stats.ByChannel[channel] = row.Total
+		stats.Counts.Add(row.toCounts())
+	}
+
+	// By template
+	var templateRows []struct {
+		TemplateID   uuid.UUID `db:"template_id"`
+		TemplateName string    `db:"template_name"`
+		deliveryCountsRow
+	}
+	templateQuery := `SELECT template_id, COALESCE(MAX(template_name), '') as template_name,` + deliveryCountColumns + `
+		FROM notifications WHERE ` + where + ` AND template_id IS NOT NULL
+		GROUP BY template_id ORDER BY total DESC`
+	if err := sqlx.SelectContext(ctx, executor, &templateRows, templateQuery, args...); err != nil {
+		return nil, fmt.Errorf("failed to get stats by template: %w", err)
+	}
+	for _, row := range templateRows {
+		stats.TemplateCounts = append(stats.TemplateCounts, domain.TemplateDeliveryCounts{
+			TemplateID:     row.TemplateID,
+			TemplateName:   row.TemplateName,
+			DeliveryCounts: row.toCounts(),
+		})
+	}
+
+	// Series; date_trunc starts weeks on Monday, like StatsInterval.Truncate
+	var bucketRows []struct {
+		Start time.Time `db:"bucket"`
+		deliveryCountsRow
+	}
+	bucketQuery := fmt.Sprintf(`SELECT date_trunc('%s', created_at AT TIME ZONE 'UTC') AT TIME ZONE 'UTC' as bucket,`,
+		statsIntervalUnit(query.Interval)) + deliveryCountColumns + `
+		FROM notifications WHERE ` + where + ` GROUP BY bucket ORDER BY bucket`
+	if err := sqlx.SelectContext(ctx, executor, &bucketRows, bucketQuery, args...); err != nil {
+		return nil, fmt.Errorf("failed to get stats series: %w", err)
+	}
+	for _, row := range bucketRows {
+		stats.Series = append(stats.Series, domain.StatsBucket{Start: row.Start.UTC(), DeliveryCounts: row.toCounts()})
+	}
+
+	// By status and type
+	var groupRows []struct {
+		Status string `db:"status"`
+		Type   string `db:"type"`
+		Count  int64  `db:"count"`
+	}
+	groupQuery := `SELECT status, type, COUNT(*) as count FROM notifications WHERE ` + where + ` GROUP BY status, type`
+	if err := sqlx.SelectContext(ctx, executor, &groupRows, groupQuery, args...); err != nil {
+		return nil, fmt.Errorf("failed to get stats by status: %w", err)
+	}
+	for _, row := range groupRows {
+		stats.ByStatus[domain.NotificationStatus(row.Status)] += row.Count
+		stats.ByType[domain.NotificationType(row.Type)] += row.Count
+	}
+
+	// Summary fields
+	stats.TotalCount = stats.Counts.Total
+	stats.SentCount = stats.Counts.Sent
+	stats.DeliveredCount = stats.Counts.Delivered
+	stats.FailedCount = stats.Counts.Failed + stats.Counts.Bounced + stats.Counts.Complained
+	for _, status := range []domain.NotificationStatus{domain.StatusPending, domain.StatusQueued, domain.StatusScheduled} {
+		stats.PendingCount += stats.ByStatus[status]
+	}
+	stats.OpenRate = stats.Counts.OpenRate()
+	stats.ClickRate = stats.Counts.ClickRate()
+	stats.BounceRate = stats.Counts.BounceRate()
+	if stats.Counts.Sent > 0 {
+		stats.ComplaintRate = float64(stats.Counts.Complained) / float64(stats.Counts.Sent) * 100
+	}
+
+	return stats, nil
+}
+
+// statsIntervalUnit returns the date_trunc unit for an interval.
+func statsIntervalUnit(interval domain.StatsInterval) string {
+	switch interval {
+	case domain.StatsIntervalHour, domain.StatsIntervalWeek, domain.StatsIntervalMonth:
+		return string(interval)
+	}
+	return "day"
 }
 
 // BulkCreate creates multiple notifications.
@@ -838,6 +938,46 @@ func (r *NotificationRepository) DeleteOld(ctx context.Context, before time.Time
 	return rowsAffected, nil
 }
 
+// bodyPurgeStatuses are the statuses whose bodies can be purged: the final
+// statuses, and sent, which is where notifications stay when their provider
+// does not report delivery.
+var bodyPurgeStatuses = []string{
+	string(domain.StatusSent), string(domain.StatusDelivered), string(domain.StatusRead), string(domain.StatusFailed),
+	string(domain.StatusCancelled), string(domain.StatusBounced), string(domain.StatusComplained),
+}
+
+// PurgeBodies clears the bodies of notifications in a final state created
+// before the filter's date, oldest first.
+func (r *NotificationRepository) PurgeBodies(ctx context.Context, filter domain.BodyPurgeFilter) (int64, error) {
+	executor := getExecutor(ctx, r.db)
+
+	conditions := []string{"created_at < $1", "status = ANY($2)", "(body <> '' OR html_body IS NOT NULL)"}
+	args := []interface{}{filter.Before, pq.Array(bodyPurgeStatuses)}
+	if filter.TenantID != nil {
+		args = append(args, *filter.TenantID)
+		conditions = append(conditions, fmt.Sprintf("tenant_id = $%d", len(args)))
+	} else if len(filter.ExcludeTenantIDs) > 0 {
+		args = append(args, pq.Array(filter.ExcludeTenantIDs))
+		conditions = append(conditions, fmt.Sprintf("tenant_id <> ALL($%d)", len(args)))
+	}
+	args = append(args, filter.Limit)
+
+	query := fmt.Sprintf(`
+		UPDATE notifications SET body = '', html_body = NULL
+		WHERE id IN (
+			SELECT id FROM notifications
+			WHERE %s
+			ORDER BY created_at
+			LIMIT $%d
+		)`, strings.Join(conditions, " AND "), len(args))
+
+	result, err := executor.ExecContext(ctx, query, args...)
+	if err != nil {
+		return 0, fmt.Errorf("failed to purge notification bodies: %w", err)
+	}
+	return result.RowsAffected()
+}
+
 // ============================================================================
 // Helper Methods
 // ============================================================================
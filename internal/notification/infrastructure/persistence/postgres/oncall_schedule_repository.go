@@ -0,0 +1,181 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+
+	"github.com/kilang-desa-murni/crm/internal/notification/domain"
+)
+
+// ============================================================================
+// On-Call Schedule Repository Implementation
+// ============================================================================
+
+// OnCallScheduleRepository implements domain.OnCallScheduleRepository using
+// PostgreSQL.
+type OnCallScheduleRepository struct {
+	db *sqlx.DB
+}
+
+// NewOnCallScheduleRepository creates a new OnCallScheduleRepository
+// instance.
+func NewOnCallScheduleRepository(db *sqlx.DB) *OnCallScheduleRepository {
+	return &OnCallScheduleRepository{db: db}
+}
+
+const onCallScheduleColumns = `id, tenant_id, name, members, rotation_start, shift_hours, overrides, created_at, updated_at`
+
+// onCallScheduleRow is the database row of an on-call schedule. Overrides
+// are stored as JSONB.
+type onCallScheduleRow struct {
+	ID            uuid.UUID `db:"id"`
+	TenantID      uuid.UUID `db:"tenant_id"`
+	Name          string    `db:"name"`
+	Members       UUIDArray `db:"members"`
+	RotationStart time.Time `db:"rotation_start"`
+	ShiftHours    int       `db:"shift_hours"`
+	Overrides     []byte    `db:"overrides"`
+	CreatedAt     time.Time `db:"created_at"`
+	UpdatedAt     time.Time `db:"updated_at"`
+}
+
+func (r onCallScheduleRow) toDomain() *domain.OnCallSchedule {
+	schedule := &domain.OnCallSchedule{
+		ID:            r.ID,
+		TenantID:      r.TenantID,
+		Name:          r.Name,
+		Members:       []uuid.UUID(r.Members),
+		RotationStart: r.RotationStart,
+		ShiftHours:    r.ShiftHours,
+		Overrides:     []domain.OnCallOverride{},
+		CreatedAt:     r.CreatedAt,
+		UpdatedAt:     r.UpdatedAt,
+	}
+	if len(r.Overrides) > 0 {
+		json.Unmarshal(r.Overrides, &schedule.Overrides)
+	}
+	return schedule
+}
+
+// onCallScheduleArgs returns the column values of a schedule in the order of
+// onCallScheduleColumns.
+func onCallScheduleArgs(schedule *domain.OnCallSchedule) ([]interface{}, error) {
+	overrides, err := json.Marshal(schedule.Overrides)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal overrides: %w", err)
+	}
+	members := make([]string, len(schedule.Members))
+	for i, member := range schedule.Members {
+		members[i] = member.String()
+	}
+	return []interface{}{
+		schedule.ID, schedule.TenantID, schedule.Name, pq.Array(members), schedule.RotationStart,
+		schedule.ShiftHours, overrides, schedule.CreatedAt, schedule.UpdatedAt,
+	}, nil
+}
+
+// Create stores a new schedule.
+func (r *OnCallScheduleRepository) Create(ctx context.Context, schedule *domain.OnCallSchedule) error {
+	executor := getExecutor(ctx, r.db)
+
+	args, err := onCallScheduleArgs(schedule)
+	if err != nil {
+		return err
+	}
+	query := `
+		INSERT INTO notification_oncall_schedules (` + onCallScheduleColumns + `)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)`
+	if _, err := executor.ExecContext(ctx, query, args...); err != nil {
+		return fmt.Errorf("failed to create on-call schedule: %w", err)
+	}
+	return nil
+}
+
+// Update stores the changes to a schedule.
+func (r *OnCallScheduleRepository) Update(ctx context.Context, schedule *domain.OnCallSchedule) error {
+	executor := getExecutor(ctx, r.db)
+
+	args, err := onCallScheduleArgs(schedule)
+	if err != nil {
+		return err
+	}
+	// created_at never changes
+	query := `
+		UPDATE notification_oncall_schedules SET
+			name = $3, members = $4, rotation_start = $5, shift_hours = $6, overrides = $7, updated_at = $8
+		WHERE id = $1 AND tenant_id = $2`
+	result, err := executor.ExecContext(ctx, query, append(args[:7:7], schedule.UpdatedAt)...)
+	if err != nil {
+		return fmt.Errorf("failed to update on-call schedule: %w", err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to update on-call schedule: %w", err)
+	}
+	if rows == 0 {
+		return domain.ErrOnCallScheduleNotFound
+	}
+	return nil
+}
+
+// FindByID finds a schedule of a tenant.
+func (r *OnCallScheduleRepository) FindByID(ctx context.Context, tenantID, id uuid.UUID) (*domain.OnCallSchedule, error) {
+	executor := getExecutor(ctx, r.db)
+
+	query := `SELECT ` + onCallScheduleColumns + ` FROM notification_oncall_schedules WHERE id = $1 AND tenant_id = $2`
+
+	var row onCallScheduleRow
+	if err := sqlx.GetContext(ctx, executor, &row, query, id, tenantID); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, domain.ErrOnCallScheduleNotFound
+		}
+		return nil, fmt.Errorf("failed to find on-call schedule: %w", err)
+	}
+	return row.toDomain(), nil
+}
+
+// FindByTenant finds the schedules of a tenant, oldest first.
+func (r *OnCallScheduleRepository) FindByTenant(ctx context.Context, tenantID uuid.UUID) ([]*domain.OnCallSchedule, error) {
+	executor := getExecutor(ctx, r.db)
+
+	query := `SELECT ` + onCallScheduleColumns + ` FROM notification_oncall_schedules WHERE tenant_id = $1 ORDER BY created_at`
+
+	var rows []onCallScheduleRow
+	if err := sqlx.SelectContext(ctx, executor, &rows, query, tenantID); err != nil {
+		return nil, fmt.Errorf("failed to find on-call schedules: %w", err)
+	}
+	schedules := make([]*domain.OnCallSchedule, len(rows))
+	for i, row := range rows {
+		schedules[i] = row.toDomain()
+	}
+	return schedules, nil
+}
+
+// Delete removes a schedule.
+func (r *OnCallScheduleRepository) Delete(ctx context.Context, tenantID, id uuid.UUID) error {
+	executor := getExecutor(ctx, r.db)
+
+	query := `DELETE FROM notification_oncall_schedules WHERE id = $1 AND tenant_id = $2`
+	result, err := executor.ExecContext(ctx, query, id, tenantID)
+	if err != nil {
+		return fmt.Errorf("failed to delete on-call schedule: %w", err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to delete on-call schedule: %w", err)
+	}
+	if rows == 0 {
+		return domain.ErrOnCallScheduleNotFound
+	}
+	return nil
+}
+
+// Ensure OnCallScheduleRepository implements domain.OnCallScheduleRepository
+var _ domain.OnCallScheduleRepository = (*OnCallScheduleRepository)(nil)
@@ -0,0 +1,106 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+
+	"github.com/kilang-desa-murni/crm/internal/notification/domain"
+)
+
+// ============================================================================
+// Recipient Eraser Implementation
+// ============================================================================
+
+// Hashes of recipient identifiers, computed as the customer service hashes
+// them: the hex SHA-256 of "email:" and the lowercased address, or of "phone:"
+// and the number's digits.
+const (
+	recipientEmailHash = `encode(sha256(convert_to('email:' || lower(trim(recipient_email)), 'UTF8')), 'hex')`
+	recipientPhoneHash = `encode(sha256(convert_to('phone:' || regexp_replace(recipient_phone, '[^0-9]', '', 'g'), 'UTF8')), 'hex')`
+)
+
+// erasedNotifications selects the notifications sent to an erased customer,
+// or about them.
+const erasedNotifications = `
+	SELECT id FROM notifications
+	WHERE tenant_id = $1 AND (
+		` + recipientEmailHash + ` = ANY($3)
+		OR ` + recipientPhoneHash + ` = ANY($3)
+		OR (source_entity_type = 'customer' AND source_entity_id = $2)
+	)`
+
+// RecipientEraser implements domain.RecipientEraser using PostgreSQL.
+type RecipientEraser struct {
+	db *sqlx.DB
+	tm *TransactionManager
+}
+
+// NewRecipientEraser creates a new RecipientEraser instance.
+func NewRecipientEraser(db *sqlx.DB) *RecipientEraser {
+	return &RecipientEraser{db: db, tm: NewTransactionManager(db)}
+}
+
+// Ensure RecipientEraser implements domain.RecipientEraser.
+var _ domain.RecipientEraser = (*RecipientEraser)(nil)
+
+// EraseRecipients erases the customer's notifications in one transaction. The
+// delivery logs and archived emails are matched through their notifications,
+// so they are erased before the notifications are anonymized.
+func (r *RecipientEraser) EraseRecipients(ctx context.Context, tenantID, customerID uuid.UUID, identifierHashes []string) (int64, error) {
+	if identifierHashes == nil {
+		identifierHashes = []string{}
+	}
+
+	statements := []struct {
+		name  string
+		query string
+	}{
+		{"delivery logs", `
+			UPDATE notification_delivery_logs
+			SET request = '', response = '', error_message = ''
+			WHERE tenant_id = $1 AND notification_id IN (` + erasedNotifications + `)`},
+		// Expired archived emails are deleted with their stored messages by
+		// the archive retention sweep
+		{"archived emails", `
+			UPDATE notification_email_archive
+			SET subject = '', expires_at = NOW()
+			WHERE tenant_id = $1 AND (
+				notification_id IN (` + erasedNotifications + `)
+				OR EXISTS (
+					SELECT 1 FROM unnest(recipients) AS recipient
+					WHERE encode(sha256(convert_to('email:' || lower(trim(recipient)), 'UTF8')), 'hex') = ANY($3)
+				)
+			)`},
+		{"notifications", `
+			UPDATE notifications
+			SET recipient_email = NULL, recipient_phone = NULL, recipient_name = NULL, device_token = NULL,
+				subject = NULL, body = '', html_body = NULL, data = '{}', metadata = '{}',
+				updated_at = NOW(), version = version + 1
+			WHERE id IN (` + erasedNotifications + `)`},
+	}
+
+	var records int64
+	err := r.tm.WithTransaction(ctx, func(ctx context.Context) error {
+		executor := getExecutor(ctx, r.db)
+		for _, stmt := range statements {
+			result, err := executor.ExecContext(ctx, stmt.query, tenantID, customerID, pq.Array(identifierHashes))
+			if err != nil {
+				return fmt.Errorf("failed to erase %s: %w", stmt.name, err)
+			}
+			affected, err := result.RowsAffected()
+			if err != nil {
+				return fmt.Errorf("failed to count erased %s: %w", stmt.name, err)
+			}
+			records += affected
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return records, nil
+}
@@ -0,0 +1,220 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+
+	"github.com/kilang-desa-murni/crm/internal/notification/domain"
+)
+
+// ============================================================================
+// Routing Rule Repository Implementation
+// ============================================================================
+
+// RoutingRuleRepository implements domain.RoutingRuleRepository using
+// PostgreSQL.
+type RoutingRuleRepository struct {
+	db *sqlx.DB
+}
+
+// NewRoutingRuleRepository creates a new RoutingRuleRepository instance.
+func NewRoutingRuleRepository(db *sqlx.DB) *RoutingRuleRepository {
+	return &RoutingRuleRepository{db: db}
+}
+
+const routingRuleColumns = `id, tenant_id, name, event_type, filters, audiences, channels, template_code,
+			channel_templates, priority, enabled, created_by, created_at, updated_at`
+
+// routingRuleRow is the database row of a routing rule. Filters, audiences
+// and channel templates are stored as JSONB.
+type routingRuleRow struct {
+	ID               uuid.UUID   `db:"id"`
+	TenantID         uuid.UUID   `db:"tenant_id"`
+	Name             string      `db:"name"`
+	EventType        string      `db:"event_type"`
+	Filters          []byte      `db:"filters"`
+	Audiences        []byte      `db:"audiences"`
+	Channels         StringArray `db:"channels"`
+	TemplateCode     string      `db:"template_code"`
+	ChannelTemplates []byte      `db:"channel_templates"`
+	Priority         int         `db:"priority"`
+	Enabled          bool        `db:"enabled"`
+	CreatedBy        *uuid.UUID  `db:"created_by"`
+	CreatedAt        time.Time   `db:"created_at"`
+	UpdatedAt        time.Time   `db:"updated_at"`
+}
+
+func (r routingRuleRow) toDomain() *domain.RoutingRule {
+	rule := &domain.RoutingRule{
+		ID:               r.ID,
+		TenantID:         r.TenantID,
+		Name:             r.Name,
+		EventType:        r.EventType,
+		Filters:          []domain.RoutingFilter{},
+		Audiences:        []domain.RoutingAudience{},
+		Channels:         make([]domain.NotificationChannel, len(r.Channels)),
+		TemplateCode:     r.TemplateCode,
+		ChannelTemplates: make(map[domain.NotificationChannel]string),
+		Priority:         r.Priority,
+		Enabled:          r.Enabled,
+		CreatedBy:        r.CreatedBy,
+		CreatedAt:        r.CreatedAt,
+		UpdatedAt:        r.UpdatedAt,
+	}
+	for i, channel := range r.Channels {
+		rule.Channels[i] = domain.NotificationChannel(channel)
+	}
+	if len(r.Filters) > 0 {
+		json.Unmarshal(r.Filters, &rule.Filters)
+	}
+	if len(r.Audiences) > 0 {
+		json.Unmarshal(r.Audiences, &rule.Audiences)
+	}
+	if len(r.ChannelTemplates) > 0 {
+		json.Unmarshal(r.ChannelTemplates, &rule.ChannelTemplates)
+	}
+	return rule
+}
+
+// routingRuleArgs returns the column values of a rule in the order of
+// routingRuleColumns.
+func routingRuleArgs(rule *domain.RoutingRule) ([]interface{}, error) {
+	filters, err := json.Marshal(rule.Filters)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal filters: %w", err)
+	}
+	audiences, err := json.Marshal(rule.Audiences)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal audiences: %w", err)
+	}
+	channelTemplates, err := json.Marshal(rule.ChannelTemplates)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal channel templates: %w", err)
+	}
+	channels := make([]string, len(rule.Channels))
+	for i, channel := range rule.Channels {
+		channels[i] = string(channel)
+	}
+	return []interface{}{
+		rule.ID, rule.TenantID, rule.Name, rule.EventType, filters, audiences, pq.Array(channels),
+		rule.TemplateCode, channelTemplates, rule.Priority, rule.Enabled, rule.CreatedBy, rule.CreatedAt,
+		rule.UpdatedAt,
+	}, nil
+}
+
+// Create stores a new rule.
+func (r *RoutingRuleRepository) Create(ctx context.Context, rule *domain.RoutingRule) error {
+	executor := getExecutor(ctx, r.db)
+
+	args, err := routingRuleArgs(rule)
+	if err != nil {
+		return err
+	}
+	query := `
+		INSERT INTO notification_routing_rules (` + routingRuleColumns + `)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14)`
+	if _, err := executor.ExecContext(ctx, query, args...); err != nil {
+		return fmt.Errorf("failed to create routing rule: %w", err)
+	}
+	return nil
+}
+
+// Update stores the changes to a rule.
+func (r *RoutingRuleRepository) Update(ctx context.Context, rule *domain.RoutingRule) error {
+	executor := getExecutor(ctx, r.db)
+
+	args, err := routingRuleArgs(rule)
+	if err != nil {
+		return err
+	}
+	// created_by and created_at never change
+	query := `
+		UPDATE notification_routing_rules SET
+			name = $3, event_type = $4, filters = $5, audiences = $6, channels = $7, template_code = $8,
+			channel_templates = $9, priority = $10, enabled = $11, updated_at = $12
+		WHERE id = $1 AND tenant_id = $2`
+	result, err := executor.ExecContext(ctx, query, append(args[:11:11], rule.UpdatedAt)...)
+	if err != nil {
+		return fmt.Errorf("failed to update routing rule: %w", err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to update routing rule: %w", err)
+	}
+	if rows == 0 {
+		return domain.ErrRoutingRuleNotFound
+	}
+	return nil
+}
+
+// FindByID finds a rule of a tenant.
+func (r *RoutingRuleRepository) FindByID(ctx context.Context, tenantID, id uuid.UUID) (*domain.RoutingRule, error) {
+	executor := getExecutor(ctx, r.db)
+
+	query := `SELECT ` + routingRuleColumns + ` FROM notification_routing_rules WHERE id = $1 AND tenant_id = $2`
+
+	var row routingRuleRow
+	if err := sqlx.GetContext(ctx, executor, &row, query, id, tenantID); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, domain.ErrRoutingRuleNotFound
+		}
+		return nil, fmt.Errorf("failed to find routing rule: %w", err)
+	}
+	return row.toDomain(), nil
+}
+
+// FindByTenant finds the rules of a tenant, by priority and then oldest
+// first.
+func (r *RoutingRuleRepository) FindByTenant(ctx context.Context, tenantID uuid.UUID) ([]*domain.RoutingRule, error) {
+	return r.find(ctx, `WHERE tenant_id = $1 ORDER BY priority, created_at`, tenantID)
+}
+
+// FindAll finds the rules of every tenant.
+func (r *RoutingRuleRepository) FindAll(ctx context.Context) ([]*domain.RoutingRule, error) {
+	return r.find(ctx, `ORDER BY tenant_id, priority, created_at`)
+}
+
+func (r *RoutingRuleRepository) find(ctx context.Context, where string, args ...interface{}) ([]*domain.RoutingRule, error) {
+	executor := getExecutor(ctx, r.db)
+
+	query := `SELECT ` + routingRuleColumns + ` FROM notification_routing_rules ` + where
+
+	var rows []routingRuleRow
+	if err := sqlx.SelectContext(ctx, executor, &rows, query, args...); err != nil {
+		return nil, fmt.Errorf("failed to find routing rules: %w", err)
+	}
+	rules := make([]*domain.RoutingRule, len(rows))
+	for i, row := range rows {
+		rules[i] = row.toDomain()
+	}
+	return rules, nil
+}
+
+// Delete removes a rule.
+func (r *RoutingRuleRepository) Delete(ctx context.Context, tenantID, id uuid.UUID) error {
+	executor := getExecutor(ctx, r.db)
+
+	query := `DELETE FROM notification_routing_rules WHERE id = $1 AND tenant_id = $2`
+	result, err := executor.ExecContext(ctx, query, id, tenantID)
+	if err != nil {
+		return fmt.Errorf("failed to delete routing rule: %w", err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to delete routing rule: %w", err)
+	}
+	if rows == 0 {
+		return domain.ErrRoutingRuleNotFound
+	}
+	return nil
+}
+
+// Ensure RoutingRuleRepository implements domain.RoutingRuleRepository
+var _ domain.RoutingRuleRepository = (*RoutingRuleRepository)(nil)
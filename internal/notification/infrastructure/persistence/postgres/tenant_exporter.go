@@ -0,0 +1,72 @@
+package postgres
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+
+	"github.com/kilang-desa-murni/crm/internal/notification/domain"
+)
+
+// ============================================================================
+// Tenant Exporter Implementation
+// ============================================================================
+
+// TenantExporter implements domain.TenantDataExporter using PostgreSQL.
+type TenantExporter struct {
+	db *sqlx.DB
+}
+
+// NewTenantExporter creates a new TenantExporter instance.
+func NewTenantExporter(db *sqlx.DB) *TenantExporter {
+	return &TenantExporter{db: db}
+}
+
+// Ensure TenantExporter implements domain.TenantDataExporter.
+var _ domain.TenantDataExporter = (*TenantExporter)(nil)
+
+// ExportTenantData streams the tenant's notifications as JSON without their
+// bodies, which hold the rendered content rather than metadata. Templates
+// are not stored by this service yet, so that data set is always empty.
+func (e *TenantExporter) ExportTenantData(ctx context.Context, tenantID uuid.UUID, dataset string, w io.Writer) (int64, error) {
+	switch dataset {
+	case domain.TenantExportTemplates:
+		return 0, nil
+	case domain.TenantExportNotifications:
+	default:
+		return 0, fmt.Errorf("unknown export data set %q", dataset)
+	}
+
+	query := `
+		SELECT (to_jsonb(n) - 'body' - 'html_body')::text FROM notifications n
+		WHERE n.tenant_id = $1 AND n.deleted_at IS NULL
+		ORDER BY n.created_at, n.id`
+
+	rows, err := e.db.QueryContext(ctx, query, tenantID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to query notifications: %w", err)
+	}
+	defer rows.Close()
+
+	out := bufio.NewWriter(w)
+	var count int64
+	for rows.Next() {
+		var record string
+		if err := rows.Scan(&record); err != nil {
+			return count, fmt.Errorf("failed to scan notification: %w", err)
+		}
+		if _, err := out.WriteString(record + "\n"); err != nil {
+			return count, err
+		}
+		count++
+	}
+	if err := rows.Err(); err != nil {
+		return count, fmt.Errorf("failed to read notifications: %w", err)
+	}
+
+	return count, out.Flush()
+}
@@ -0,0 +1,207 @@
+// Package chat provides team chat provider implementations for the notification service.
+package chat
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/kilang-desa-murni/crm/internal/notification/application/ports"
+)
+
+// WebhookConfig holds configuration for posting to incoming webhooks.
+type WebhookConfig struct {
+	Timeout time.Duration
+}
+
+// DefaultWebhookConfig returns default webhook configuration.
+func DefaultWebhookConfig() WebhookConfig {
+	return WebhookConfig{
+		Timeout: 10 * time.Second,
+	}
+}
+
+// webhookResult is the outcome of a webhook post.
+type webhookResult struct {
+	statusCode int
+	body       string
+}
+
+// postJSON posts payload to an incoming webhook. Redirects are not
+// followed, so a webhook cannot send the message on to another host.
+func postJSON(ctx context.Context, client *http.Client, webhookURL string, payload interface{}) (*webhookResult, error) {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal payload: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, "POST", webhookURL, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+	return &webhookResult{statusCode: resp.StatusCode, body: strings.TrimSpace(string(respBody))}, nil
+}
+
+// newWebhookClient creates the HTTP client of a webhook provider.
+func newWebhookClient(config WebhookConfig) *http.Client {
+	return &http.Client{
+		Timeout:       config.Timeout,
+		CheckRedirect: func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse },
+	}
+}
+
+// ============================================================================
+// Slack Provider
+// ============================================================================
+
+// SlackWebhookProvider implements SlackProvider with Slack incoming webhooks.
+type SlackWebhookProvider struct {
+	httpClient *http.Client
+}
+
+// NewSlackWebhookProvider creates a new Slack webhook provider.
+func NewSlackWebhookProvider(config WebhookConfig) *SlackWebhookProvider {
+	return &SlackWebhookProvider{httpClient: newWebhookClient(config)}
+}
+
+// SendSlackMessage posts a message to a Slack incoming webhook.
+func (p *SlackWebhookProvider) SendSlackMessage(ctx context.Context, request ports.SlackRequest) (*ports.SlackResponse, error) {
+	startTime := time.Now()
+	payload := map[string]interface{}{"text": request.Text}
+	if len(request.Blocks) > 0 {
+		payload["blocks"] = request.Blocks
+	}
+	if len(request.Attachments) > 0 {
+		payload["attachments"] = request.Attachments
+	}
+	if request.ThreadTS != "" {
+		payload["thread_ts"] = request.ThreadTS
+	}
+	payload["unfurl_links"] = request.UnfurlLinks
+	payload["unfurl_media"] = request.UnfurlMedia
+
+	result, err := postJSON(ctx, p.httpClient, request.WebhookURL, payload)
+	if err != nil {
+		return &ports.SlackResponse{
+			MessageID:    request.MessageID,
+			Status:       "failed",
+			ErrorMessage: err.Error(),
+			SentAt:       startTime,
+		}, err
+	}
+
+	// Slack answers "ok", or an error code such as invalid_blocks or
+	// channel_is_archived
+	if result.statusCode != http.StatusOK {
+		return &ports.SlackResponse{
+			MessageID:    request.MessageID,
+			Status:       "failed",
+			StatusCode:   result.statusCode,
+			ErrorMessage: result.body,
+			SentAt:       startTime,
+		}, fmt.Errorf("slack error: %s (status %d)", result.body, result.statusCode)
+	}
+
+	return &ports.SlackResponse{
+		MessageID:  request.MessageID,
+		Status:     "sent",
+		StatusCode: result.statusCode,
+		SentAt:     startTime,
+	}, nil
+}
+
+// GetProviderName returns the provider name.
+func (p *SlackWebhookProvider) GetProviderName() string {
+	return "slack-webhook"
+}
+
+// IsAvailable checks if the provider is available. Webhooks are configured
+// per integration.
+func (p *SlackWebhookProvider) IsAvailable(ctx context.Context) bool {
+	return true
+}
+
+// Ensure SlackWebhookProvider implements SlackProvider
+var _ ports.SlackProvider = (*SlackWebhookProvider)(nil)
+
+// ============================================================================
+// Teams Provider
+// ============================================================================
+
+// TeamsWebhookProvider implements TeamsProvider with Teams incoming webhooks
+// and workflows.
+type TeamsWebhookProvider struct {
+	httpClient *http.Client
+}
+
+// NewTeamsWebhookProvider creates a new Teams webhook provider.
+func NewTeamsWebhookProvider(config WebhookConfig) *TeamsWebhookProvider {
+	return &TeamsWebhookProvider{httpClient: newWebhookClient(config)}
+}
+
+// SendTeamsMessage posts an Adaptive Card to a Teams webhook.
+func (p *TeamsWebhookProvider) SendTeamsMessage(ctx context.Context, request ports.TeamsRequest) (*ports.TeamsResponse, error) {
+	startTime := time.Now()
+	payload := map[string]interface{}{
+		"type":    "message",
+		"summary": request.Text,
+		"attachments": []map[string]interface{}{{
+			"contentType": "application/vnd.microsoft.card.adaptive",
+			"content":     request.Card,
+		}},
+	}
+
+	result, err := postJSON(ctx, p.httpClient, request.WebhookURL, payload)
+	if err != nil {
+		return &ports.TeamsResponse{
+			MessageID:    request.MessageID,
+			Status:       "failed",
+			ErrorMessage: err.Error(),
+			SentAt:       startTime,
+		}, err
+	}
+
+	// Connectors answer 200 and workflows 202
+	if result.statusCode < 200 || result.statusCode >= 300 {
+		return &ports.TeamsResponse{
+			MessageID:    request.MessageID,
+			Status:       "failed",
+			StatusCode:   result.statusCode,
+			ErrorMessage: result.body,
+			SentAt:       startTime,
+		}, fmt.Errorf("teams error: status %d", result.statusCode)
+	}
+
+	return &ports.TeamsResponse{
+		MessageID:  request.MessageID,
+		Status:     "sent",
+		StatusCode: result.statusCode,
+		SentAt:     startTime,
+	}, nil
+}
+
+// GetProviderName returns the provider name.
+func (p *TeamsWebhookProvider) GetProviderName() string {
+	return "teams-webhook"
+}
+
+// IsAvailable checks if the provider is available. Webhooks are configured
+// per integration.
+func (p *TeamsWebhookProvider) IsAvailable(ctx context.Context) bool {
+	return true
+}
+
+// Ensure TeamsWebhookProvider implements TeamsProvider
+var _ ports.TeamsProvider = (*TeamsWebhookProvider)(nil)
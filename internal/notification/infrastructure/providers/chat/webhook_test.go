@@ -0,0 +1,93 @@
+package chat
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/kilang-desa-murni/crm/internal/notification/application/ports"
+)
+
+func TestSlackWebhookProvider_SendSlackMessage(t *testing.T) {
+	var payload map[string]interface{}
+	status, answer := http.StatusOK, "ok"
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewDecoder(r.Body).Decode(&payload)
+		w.WriteHeader(status)
+		w.Write([]byte(answer))
+	}))
+	defer server.Close()
+
+	provider := NewSlackWebhookProvider(WebhookConfig{Timeout: 5 * time.Second})
+	request := ports.SlackRequest{
+		MessageID:  "msg-1",
+		WebhookURL: server.URL,
+		Text:       "Deal won: OPP-1",
+		Blocks:     []map[string]interface{}{{"type": "header"}},
+	}
+	resp, err := provider.SendSlackMessage(context.Background(), request)
+	if err != nil || resp.Status != "sent" {
+		t.Fatalf("SendSlackMessage() = %+v, %v, want it sent", resp, err)
+	}
+	if payload["text"] != "Deal won: OPP-1" || len(payload["blocks"].([]interface{})) != 1 {
+		t.Errorf("payload = %v", payload)
+	}
+
+	// Slack reports rejected messages with an error code
+	status, answer = http.StatusBadRequest, "invalid_blocks"
+	resp, err = provider.SendSlackMessage(context.Background(), request)
+	if err == nil || resp.ErrorMessage != "invalid_blocks" {
+		t.Errorf("SendSlackMessage() = %+v, %v, want the Slack error", resp, err)
+	}
+}
+
+func TestTeamsWebhookProvider_SendTeamsMessage(t *testing.T) {
+	var payload struct {
+		Type        string `json:"type"`
+		Attachments []struct {
+			ContentType string                 `json:"contentType"`
+			Content     map[string]interface{} `json:"content"`
+		} `json:"attachments"`
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewDecoder(r.Body).Decode(&payload)
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer server.Close()
+
+	provider := NewTeamsWebhookProvider(DefaultWebhookConfig())
+	resp, err := provider.SendTeamsMessage(context.Background(), ports.TeamsRequest{
+		WebhookURL: server.URL,
+		Text:       "Deal won",
+		Card:       map[string]interface{}{"type": "AdaptiveCard", "version": "1.4"},
+	})
+	if err != nil || resp.Status != "sent" {
+		t.Fatalf("SendTeamsMessage() = %+v, %v, want it sent", resp, err)
+	}
+	if payload.Type != "message" || len(payload.Attachments) != 1 ||
+		payload.Attachments[0].ContentType != "application/vnd.microsoft.card.adaptive" ||
+		payload.Attachments[0].Content["type"] != "AdaptiveCard" {
+		t.Errorf("payload = %+v", payload)
+	}
+}
+
+func TestWebhookProviders_DoNotFollowRedirects(t *testing.T) {
+	redirected := false
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		redirected = true
+	}))
+	defer target.Close()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, target.URL, http.StatusTemporaryRedirect)
+	}))
+	defer server.Close()
+
+	provider := NewTeamsWebhookProvider(DefaultWebhookConfig())
+	_, err := provider.SendTeamsMessage(context.Background(), ports.TeamsRequest{WebhookURL: server.URL})
+	if err == nil || redirected {
+		t.Errorf("SendTeamsMessage() error = %v, redirected = %v, want the redirect refused", err, redirected)
+	}
+}
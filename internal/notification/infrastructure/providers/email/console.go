@@ -0,0 +1,77 @@
+package email
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/kilang-desa-murni/crm/internal/notification/application/ports"
+)
+
+// ============================================================================
+// Console Provider (Dev Mode)
+// ============================================================================
+
+// ConsoleProvider implements EmailProvider by writing emails to the console
+// instead of sending them, for dev mode.
+type ConsoleProvider struct {
+	out io.Writer
+	mu  sync.Mutex
+}
+
+// NewConsoleProvider creates a console email provider writing to out, or to
+// standard output if out is nil.
+func NewConsoleProvider(out io.Writer) *ConsoleProvider {
+	if out == nil {
+		out = os.Stdout
+	}
+	return &ConsoleProvider{out: out}
+}
+
+// SendEmail writes the email to the console.
+func (p *ConsoleProvider) SendEmail(ctx context.Context, request ports.EmailRequest) (*ports.EmailResponse, error) {
+	body := request.TextBody
+	if body == "" {
+		body = request.HTMLBody
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	_, err := fmt.Fprintf(p.out, "----- email %s -----\nFrom: %s\nTo: %s\nSubject: %s\n\n%s\n-----\n",
+		request.MessageID, request.From, strings.Join(request.To, ", "), request.Subject, body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to write email: %w", err)
+	}
+
+	return &ports.EmailResponse{
+		MessageID:  request.MessageID,
+		ProviderID: fmt.Sprintf("console-%d", time.Now().UnixNano()),
+		Provider:   "console",
+		Status:     "sent",
+		StatusCode: 200,
+		SentAt:     time.Now(),
+	}, nil
+}
+
+// ValidateEmail validates an email address.
+func (p *ConsoleProvider) ValidateEmail(ctx context.Context, email string) (bool, error) {
+	return len(email) >= 3 && containsAt(email), nil
+}
+
+// GetProviderName returns the provider name.
+func (p *ConsoleProvider) GetProviderName() string {
+	return "console"
+}
+
+// IsAvailable always returns true.
+func (p *ConsoleProvider) IsAvailable(ctx context.Context) bool {
+	return true
+}
+
+// Ensure ConsoleProvider implements EmailProvider
+var _ ports.EmailProvider = (*ConsoleProvider)(nil)
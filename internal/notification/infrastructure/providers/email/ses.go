@@ -577,6 +577,7 @@ const (
 	ProviderSendGrid ProviderType = "sendgrid"
 	ProviderSES      ProviderType = "ses"
 	ProviderSMTP     ProviderType = "smtp"
+	ProviderConsole  ProviderType = "console"
 )
 
 // ProviderFactory creates email providers.
@@ -604,6 +605,8 @@ func (f *ProviderFactory) CreateProvider(providerType ProviderType) ports.EmailP
 		return NewSESProvider(f.sesConfig)
 	case ProviderSMTP:
 		return NewSMTPProvider(f.smtpConfig)
+	case ProviderConsole:
+		return NewConsoleProvider(nil)
 	default:
 		return NewSMTPProvider(f.smtpConfig)
 	}
@@ -0,0 +1,68 @@
+package push
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/kilang-desa-murni/crm/internal/notification/application/ports"
+)
+
+// ============================================================================
+// Platform Router
+// ============================================================================
+
+// PlatformRouter sends browser subscriptions through web push and the other
+// devices through the mobile push provider, so both can serve one
+// notification service.
+type PlatformRouter struct {
+	mobile ports.PushProvider
+	web    ports.PushProvider
+}
+
+// NewPlatformRouter creates a router. Either provider may be nil when its
+// devices are not served.
+func NewPlatformRouter(mobile, web ports.PushProvider) *PlatformRouter {
+	return &PlatformRouter{mobile: mobile, web: web}
+}
+
+// SendPush sends a push notification through the provider of its device.
+func (r *PlatformRouter) SendPush(ctx context.Context, request ports.PushRequest) (*ports.PushResponse, error) {
+	provider := r.provider(request.WebPush != nil)
+	if provider == nil {
+		return nil, errors.New("no push provider for platform " + request.Platform)
+	}
+	return provider.SendPush(ctx, request)
+}
+
+// ValidateDeviceToken validates a device token with the provider of its
+// platform. Browser subscriptions are identified by their endpoint URL; web
+// tokens of the mobile provider, such as FCM's, are not URLs.
+func (r *PlatformRouter) ValidateDeviceToken(ctx context.Context, token string, platform string) (bool, error) {
+	provider := r.provider(platform == "web" && strings.HasPrefix(token, "https://"))
+	if provider == nil {
+		return false, nil
+	}
+	return provider.ValidateDeviceToken(ctx, token, platform)
+}
+
+// GetProviderName returns the provider name.
+func (r *PlatformRouter) GetProviderName() string {
+	return "push-router"
+}
+
+// IsAvailable checks if any provider is available.
+func (r *PlatformRouter) IsAvailable(ctx context.Context) bool {
+	return (r.mobile != nil && r.mobile.IsAvailable(ctx)) || (r.web != nil && r.web.IsAvailable(ctx))
+}
+
+// provider returns the web or the mobile provider.
+func (r *PlatformRouter) provider(web bool) ports.PushProvider {
+	if web {
+		return r.web
+	}
+	return r.mobile
+}
+
+// Ensure PlatformRouter implements PushProvider
+var _ ports.PushProvider = (*PlatformRouter)(nil)
@@ -0,0 +1,141 @@
+// Package push provides push provider implementations for the notification service.
+package push
+
+import (
+	"crypto/ecdh"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// vapidTokenLifetime is the validity of VAPID tokens; push services refuse
+// tokens valid for more than 24 hours.
+const vapidTokenLifetime = 12 * time.Hour
+
+// ErrVAPIDKeyNotConfigured is returned when neither the tenant nor the
+// service has a VAPID key.
+var ErrVAPIDKeyNotConfigured = errors.New("vapid key not configured")
+
+// ============================================================================
+// VAPID Keys
+// ============================================================================
+
+// vapidKey is a VAPID key pair (RFC 8292).
+type vapidKey struct {
+	private *ecdsa.PrivateKey
+	// public is the base64url-encoded uncompressed public key
+	public string
+}
+
+// VAPIDKeys holds the VAPID keys the application server identifies itself
+// to push services with. Tenants may have their own key; the others share
+// the default key. It implements ports.WebPushKeys.
+type VAPIDKeys struct {
+	defaultKey *vapidKey
+	tenantKeys map[string]*vapidKey
+}
+
+// NewVAPIDKeys creates the VAPID keys from base64url-encoded P-256 private
+// keys, as generated by `npx web-push generate-vapid-keys`. tenantKeys maps
+// tenant IDs to their own private key.
+func NewVAPIDKeys(defaultKey string, tenantKeys map[string]string) (*VAPIDKeys, error) {
+	keys := &VAPIDKeys{tenantKeys: make(map[string]*vapidKey, len(tenantKeys))}
+	if defaultKey != "" {
+		key, err := parseVAPIDKey(defaultKey)
+		if err != nil {
+			return nil, fmt.Errorf("invalid default vapid key: %w", err)
+		}
+		keys.defaultKey = key
+	}
+	for tenantID, privateKey := range tenantKeys {
+		key, err := parseVAPIDKey(privateKey)
+		if err != nil {
+			return nil, fmt.Errorf("invalid vapid key of tenant %s: %w", tenantID, err)
+		}
+		keys.tenantKeys[tenantID] = key
+	}
+	return keys, nil
+}
+
+// PublicKey returns the base64url-encoded VAPID public key of a tenant, the
+// applicationServerKey of its browser subscriptions.
+func (k *VAPIDKeys) PublicKey(tenantID string) (string, error) {
+	key, err := k.key(tenantID)
+	if err != nil {
+		return "", err
+	}
+	return key.public, nil
+}
+
+// Authorization returns the Authorization header of a push message sent to
+// endpoint on behalf of a tenant. subject is the contact of the application
+// server, a mailto: or https: URL.
+func (k *VAPIDKeys) Authorization(tenantID, endpoint, subject string, now time.Time) (string, error) {
+	key, err := k.key(tenantID)
+	if err != nil {
+		return "", err
+	}
+
+	endpointURL, err := url.Parse(endpoint)
+	if err != nil || endpointURL.Host == "" {
+		return "", fmt.Errorf("invalid push endpoint %q", endpoint)
+	}
+	claims := jwt.MapClaims{
+		"aud": endpointURL.Scheme + "://" + endpointURL.Host,
+		"exp": now.Add(vapidTokenLifetime).Unix(),
+	}
+	if subject != "" {
+		claims["sub"] = subject
+	}
+
+	token, err := jwt.NewWithClaims(jwt.SigningMethodES256, claims).SignedString(key.private)
+	if err != nil {
+		return "", fmt.Errorf("failed to sign vapid token: %w", err)
+	}
+	return fmt.Sprintf("vapid t=%s, k=%s", token, key.public), nil
+}
+
+// key returns the key of a tenant, or the default key.
+func (k *VAPIDKeys) key(tenantID string) (*vapidKey, error) {
+	if key, ok := k.tenantKeys[tenantID]; ok {
+		return key, nil
+	}
+	if k.defaultKey == nil {
+		return nil, ErrVAPIDKeyNotConfigured
+	}
+	return k.defaultKey, nil
+}
+
+// parseVAPIDKey parses a base64url-encoded P-256 private key.
+func parseVAPIDKey(privateKey string) (*vapidKey, error) {
+	scalar, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(strings.TrimSpace(privateKey), "="))
+	if err != nil {
+		return nil, fmt.Errorf("key is not base64url-encoded: %w", err)
+	}
+	key, err := ecdh.P256().NewPrivateKey(scalar)
+	if err != nil {
+		return nil, err
+	}
+
+	// The uncompressed point is 0x04 || X || Y
+	public := key.PublicKey().Bytes()
+	return &vapidKey{
+		private: &ecdsa.PrivateKey{
+			PublicKey: ecdsa.PublicKey{
+				Curve: elliptic.P256(),
+				X:     new(big.Int).SetBytes(public[1:33]),
+				Y:     new(big.Int).SetBytes(public[33:]),
+			},
+			D: new(big.Int).SetBytes(scalar),
+		},
+		public: base64.RawURLEncoding.EncodeToString(public),
+	}, nil
+}
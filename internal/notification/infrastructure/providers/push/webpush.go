@@ -0,0 +1,207 @@
+package push
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/kilang-desa-murni/crm/internal/notification/application/ports"
+)
+
+// ============================================================================
+// Web Push Provider
+// ============================================================================
+
+// WebPushConfig holds configuration for web push.
+type WebPushConfig struct {
+	// Subject is the contact push services reach the operator at, a
+	// mailto: or https: URL.
+	Subject string
+	Keys    *VAPIDKeys
+	// TTL is how long push services keep undelivered messages, unless the
+	// request sets its own.
+	TTL     time.Duration
+	Timeout time.Duration
+}
+
+// DefaultWebPushConfig returns default web push configuration.
+func DefaultWebPushConfig() WebPushConfig {
+	return WebPushConfig{
+		TTL:     24 * time.Hour,
+		Timeout: 30 * time.Second,
+	}
+}
+
+// WebPushProvider implements PushProvider for browser push subscriptions,
+// sending to their push service with VAPID authentication (RFC 8292) and
+// encrypted payloads (RFC 8291).
+type WebPushProvider struct {
+	config     WebPushConfig
+	httpClient *http.Client
+}
+
+// NewWebPushProvider creates a new web push provider.
+func NewWebPushProvider(config WebPushConfig) *WebPushProvider {
+	return &WebPushProvider{
+		config: config,
+		httpClient: &http.Client{
+			Timeout: config.Timeout,
+		},
+	}
+}
+
+// webPushPayload is the message a service worker receives.
+type webPushPayload struct {
+	Title       string            `json:"title"`
+	Body        string            `json:"body"`
+	Image       string            `json:"image,omitempty"`
+	Data        map[string]string `json:"data,omitempty"`
+	ClickAction string            `json:"click_action,omitempty"`
+	Tag         string            `json:"tag,omitempty"`
+}
+
+// SendPush sends a push notification to a browser subscription.
+func (p *WebPushProvider) SendPush(ctx context.Context, request ports.PushRequest) (*ports.PushResponse, error) {
+	startTime := time.Now()
+	subscription := request.WebPush
+	if subscription == nil {
+		return nil, errors.New("webpush: request has no browser subscription")
+	}
+
+	payload, err := json.Marshal(webPushPayload{
+		Title:       request.Title,
+		Body:        request.Body,
+		Image:       request.ImageURL,
+		Data:        request.Data,
+		ClickAction: request.ClickAction,
+		Tag:         request.CollapseKey,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal payload: %w", err)
+	}
+	body, err := encryptPayload(payload, subscription.P256DH, subscription.Auth)
+	if err != nil {
+		return p.failure(request, 0, "invalid_payload", err.Error(), startTime), fmt.Errorf("webpush: %w", err)
+	}
+
+	authorization, err := p.config.Keys.Authorization(request.TenantID, subscription.Endpoint, p.config.Subject, startTime)
+	if err != nil {
+		return nil, fmt.Errorf("webpush: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", subscription.Endpoint, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	ttl := time.Duration(request.TTL) * time.Second
+	if ttl <= 0 {
+		ttl = p.config.TTL
+	}
+	req.Header.Set("Content-Type", "application/octet-stream")
+	req.Header.Set("Content-Encoding", "aes128gcm")
+	req.Header.Set("TTL", strconv.Itoa(int(ttl.Seconds())))
+	req.Header.Set("Authorization", authorization)
+	if request.Priority == "high" {
+		req.Header.Set("Urgency", "high")
+	} else {
+		req.Header.Set("Urgency", "normal")
+	}
+	if topic := webPushTopic(request.CollapseKey); topic != "" {
+		req.Header.Set("Topic", topic)
+	}
+
+	resp, err := p.httpClient.Do(req)
+	if err != nil {
+		return p.failure(request, 0, "", fmt.Sprintf("request failed: %v", err), startTime), err
+	}
+	defer resp.Body.Close()
+	respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+
+	if resp.StatusCode >= 300 {
+		reason := webPushFailureReason(resp.StatusCode)
+		return p.failure(request, resp.StatusCode, reason, string(respBody), startTime),
+			fmt.Errorf("webpush error: status %d", resp.StatusCode)
+	}
+
+	return &ports.PushResponse{
+		MessageID:  request.MessageID,
+		ProviderID: resp.Header.Get("Location"),
+		Provider:   "webpush",
+		Status:     "sent",
+		StatusCode: resp.StatusCode,
+		SentAt:     startTime,
+	}, nil
+}
+
+// failure builds the response of an undelivered message.
+func (p *WebPushProvider) failure(request ports.PushRequest, statusCode int, reason, message string, startTime time.Time) *ports.PushResponse {
+	return &ports.PushResponse{
+		MessageID:     request.MessageID,
+		Provider:      "webpush",
+		Status:        "failed",
+		StatusCode:    statusCode,
+		ErrorMessage:  message,
+		FailureReason: reason,
+		SentAt:        startTime,
+	}
+}
+
+// webPushFailureReason maps a push service status to a failure reason.
+// 404 and 410 mean the subscription is gone for good.
+func webPushFailureReason(statusCode int) string {
+	switch statusCode {
+	case http.StatusNotFound, http.StatusGone:
+		return "subscription_expired"
+	case http.StatusRequestEntityTooLarge:
+		return "payload_too_large"
+	case http.StatusTooManyRequests:
+		return "rate_limited"
+	case http.StatusUnauthorized, http.StatusForbidden:
+		return "vapid_rejected"
+	default:
+		return ""
+	}
+}
+
+// webPushTopic returns the Topic header replacing pending messages with the
+// same collapse key. Push services accept up to 32 base64url characters.
+func webPushTopic(collapseKey string) string {
+	if collapseKey == "" || len(collapseKey) > 32 {
+		return ""
+	}
+	for _, r := range collapseKey {
+		if !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '-' || r == '_') {
+			return ""
+		}
+	}
+	return collapseKey
+}
+
+// ValidateDeviceToken validates a browser subscription endpoint.
+func (p *WebPushProvider) ValidateDeviceToken(ctx context.Context, token string, platform string) (bool, error) {
+	endpoint, err := url.Parse(token)
+	if err != nil {
+		return false, nil
+	}
+	return endpoint.Scheme == "https" && endpoint.Host != "", nil
+}
+
+// GetProviderName returns the provider name.
+func (p *WebPushProvider) GetProviderName() string {
+	return "webpush"
+}
+
+// IsAvailable checks if web push is configured.
+func (p *WebPushProvider) IsAvailable(ctx context.Context) bool {
+	return p.config.Keys != nil
+}
+
+// Ensure WebPushProvider implements PushProvider
+var _ ports.PushProvider = (*WebPushProvider)(nil)
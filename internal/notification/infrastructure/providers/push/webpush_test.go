@@ -0,0 +1,233 @@
+package push
+
+import (
+	"bytes"
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/ecdh"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+
+	"github.com/kilang-desa-murni/crm/internal/notification/application/ports"
+)
+
+func mustDecode(t *testing.T, value string) []byte {
+	t.Helper()
+	decoded, err := decodeKey(value)
+	if err != nil {
+		t.Fatalf("failed to decode %q: %v", value, err)
+	}
+	return decoded
+}
+
+// TestEncryptWithKey_RFC8291Vector checks the example of RFC 8291 Appendix A.
+func TestEncryptWithKey_RFC8291Vector(t *testing.T) {
+	asPrivate, err := ecdh.P256().NewPrivateKey(mustDecode(t, "yfWPiYE-n46HLnH0KqZOF1fJJU3MYrct3AELtAQ-oRw"))
+	if err != nil {
+		t.Fatalf("invalid application server key: %v", err)
+	}
+	uaPublic, err := ecdh.P256().NewPublicKey(mustDecode(t, "BCVxsr7N_eNgVRqvHtD0zTZsEc6-VV-JvLexhqUzORcxaOzi6-AYWXvTBHm4bjyPjs7Vd8pZGH6SRpkNtoIAiw4"))
+	if err != nil {
+		t.Fatalf("invalid user agent key: %v", err)
+	}
+
+	body, err := encryptWithKey(
+		[]byte("When I grow up, I want to be a watermelon"),
+		uaPublic,
+		mustDecode(t, "BTBZMqHH6r4Tts7J_aSIgg"),
+		asPrivate,
+		mustDecode(t, "DGv6ra1nlYgDCS1FRnbzlw"),
+	)
+	if err != nil {
+		t.Fatalf("encryptWithKey() error = %v", err)
+	}
+
+	want := "DGv6ra1nlYgDCS1FRnbzlwAAEABBBP4z9KsN6nGRTbVYI_c7VJSPQTBtkgcy27mlmlMoZIIgDll6e3vCYLocInmYWAmS6TlzAC8wEqKK6PBru3jl7A_yl95bQpu6cVPTpK4Mqgkf1CXztLVBSt2Ks3oZwbuwXPXLWyouBWLVWGNWQexSgSxsj_Qulcy4a-fN"
+	if got := base64.RawURLEncoding.EncodeToString(body); got != want {
+		t.Errorf("encryptWithKey() = %s, want %s", got, want)
+	}
+}
+
+// decrypt decrypts a message as the browser holding uaPrivate does.
+func decrypt(t *testing.T, body []byte, uaPrivate *ecdh.PrivateKey, authSecret []byte) []byte {
+	t.Helper()
+	salt, keyID := body[:16], body[21:21+int(body[20])]
+	asPublic, err := ecdh.P256().NewPublicKey(keyID)
+	if err != nil {
+		t.Fatalf("invalid key id: %v", err)
+	}
+	sharedSecret, err := uaPrivate.ECDH(asPublic)
+	if err != nil {
+		t.Fatalf("key agreement failed: %v", err)
+	}
+
+	keyInfo := append([]byte("WebPush: info\x00"), uaPrivate.PublicKey().Bytes()...)
+	keyInfo = append(keyInfo, keyID...)
+	ikm, _ := deriveKey(sharedSecret, authSecret, keyInfo, 32)
+	cek, _ := deriveKey(ikm, salt, []byte("Content-Encoding: aes128gcm\x00"), 16)
+	nonce, _ := deriveKey(ikm, salt, []byte("Content-Encoding: nonce\x00"), 12)
+
+	block, _ := aes.NewCipher(cek)
+	gcm, _ := cipher.NewGCM(block)
+	record, err := gcm.Open(nil, nonce, body[21+len(keyID):], nil)
+	if err != nil {
+		t.Fatalf("failed to decrypt: %v", err)
+	}
+	if record[len(record)-1] != 0x02 {
+		t.Fatalf("record does not end with the last record delimiter")
+	}
+	return record[:len(record)-1]
+}
+
+func newTestVAPIDKeys(t *testing.T) *VAPIDKeys {
+	t.Helper()
+	key, err := ecdh.P256().GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+	keys, err := NewVAPIDKeys(base64.RawURLEncoding.EncodeToString(key.Bytes()), nil)
+	if err != nil {
+		t.Fatalf("NewVAPIDKeys() error = %v", err)
+	}
+	return keys
+}
+
+func TestWebPushProvider_SendPush(t *testing.T) {
+	uaPrivate, err := ecdh.P256().GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("failed to generate browser key: %v", err)
+	}
+	authSecret := make([]byte, 16)
+	rand.Read(authSecret)
+
+	var got *http.Request
+	var gotBody []byte
+	status := http.StatusCreated
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r
+		gotBody, _ = io.ReadAll(r.Body)
+		w.Header().Set("Location", "https://push.example.com/message/1")
+		w.WriteHeader(status)
+	}))
+	defer server.Close()
+
+	keys := newTestVAPIDKeys(t)
+	provider := NewWebPushProvider(WebPushConfig{Subject: "mailto:ops@example.com", Keys: keys, TTL: time.Hour, Timeout: 5 * time.Second})
+	provider.httpClient = server.Client()
+
+	request := ports.PushRequest{
+		MessageID:   "msg-1",
+		TenantID:    "tenant-1",
+		Platform:    "web",
+		Title:       "Sebut harga diluluskan",
+		Body:        "Boutique Anggun accepted the quote",
+		Data:        map[string]string{"quote_id": "q-1"},
+		Priority:    "high",
+		CollapseKey: "quote-q-1",
+		WebPush: &ports.WebPushSubscription{
+			Endpoint: server.URL + "/push/abc",
+			P256DH:   base64.RawURLEncoding.EncodeToString(uaPrivate.PublicKey().Bytes()),
+			Auth:     base64.RawURLEncoding.EncodeToString(authSecret),
+		},
+	}
+	resp, err := provider.SendPush(context.Background(), request)
+	if err != nil {
+		t.Fatalf("SendPush() error = %v", err)
+	}
+	if resp.Status != "sent" || resp.ProviderID != "https://push.example.com/message/1" {
+		t.Errorf("SendPush() = %+v, want it sent", resp)
+	}
+
+	if got.Header.Get("Content-Encoding") != "aes128gcm" || got.Header.Get("TTL") != "3600" ||
+		got.Header.Get("Urgency") != "high" || got.Header.Get("Topic") != "quote-q-1" {
+		t.Errorf("headers = %v", got.Header)
+	}
+
+	var payload webPushPayload
+	if err := json.Unmarshal(decrypt(t, gotBody, uaPrivate, authSecret), &payload); err != nil {
+		t.Fatalf("payload is not JSON: %v", err)
+	}
+	if payload.Title != request.Title || payload.Data["quote_id"] != "q-1" {
+		t.Errorf("payload = %+v", payload)
+	}
+
+	// The VAPID token is signed for the push service's origin
+	authorization := got.Header.Get("Authorization")
+	parts := strings.SplitN(strings.TrimPrefix(authorization, "vapid t="), ", k=", 2)
+	if len(parts) != 2 {
+		t.Fatalf("Authorization = %q, want a vapid token and key", authorization)
+	}
+	publicKey, _ := keys.PublicKey("tenant-1")
+	if parts[1] != publicKey {
+		t.Errorf("k = %s, want the public key %s", parts[1], publicKey)
+	}
+	token, err := jwt.Parse(parts[0], func(*jwt.Token) (interface{}, error) {
+		return &keys.defaultKey.private.PublicKey, nil
+	}, jwt.WithValidMethods([]string{"ES256"}), jwt.WithAudience(server.URL))
+	if err != nil {
+		t.Fatalf("invalid vapid token: %v", err)
+	}
+	if sub, _ := token.Claims.GetSubject(); sub != "mailto:ops@example.com" {
+		t.Errorf("sub = %q", sub)
+	}
+
+	// Push services answer 410 for subscriptions that are gone
+	status = http.StatusGone
+	resp, err = provider.SendPush(context.Background(), request)
+	if err == nil || resp.FailureReason != "subscription_expired" {
+		t.Errorf("SendPush() = %+v, %v, want the subscription reported expired", resp, err)
+	}
+}
+
+func TestWebPushProvider_RejectsLargePayloads(t *testing.T) {
+	uaPrivate, _ := ecdh.P256().GenerateKey(rand.Reader)
+	provider := NewWebPushProvider(WebPushConfig{Keys: newTestVAPIDKeys(t)})
+
+	_, err := provider.SendPush(context.Background(), ports.PushRequest{
+		Body: string(bytes.Repeat([]byte("a"), MaxPayloadSize)),
+		WebPush: &ports.WebPushSubscription{
+			Endpoint: "https://push.example.com/abc",
+			P256DH:   base64.RawURLEncoding.EncodeToString(uaPrivate.PublicKey().Bytes()),
+			Auth:     base64.RawURLEncoding.EncodeToString(make([]byte, 16)),
+		},
+	})
+	if err == nil {
+		t.Error("SendPush() should refuse payloads larger than a record")
+	}
+}
+
+func TestVAPIDKeys_PerTenant(t *testing.T) {
+	tenantKey, _ := ecdh.P256().GenerateKey(rand.Reader)
+	keys, err := NewVAPIDKeys("", map[string]string{"tenant-1": base64.RawURLEncoding.EncodeToString(tenantKey.Bytes())})
+	if err != nil {
+		t.Fatalf("NewVAPIDKeys() error = %v", err)
+	}
+
+	public, err := keys.PublicKey("tenant-1")
+	if err != nil || public != base64.RawURLEncoding.EncodeToString(tenantKey.PublicKey().Bytes()) {
+		t.Errorf("PublicKey() = %s, %v, want the tenant's key", public, err)
+	}
+	if _, err := keys.PublicKey("tenant-2"); err != ErrVAPIDKeyNotConfigured {
+		t.Errorf("PublicKey() error = %v, want ErrVAPIDKeyNotConfigured without a default key", err)
+	}
+
+	// The signing key matches the advertised public key
+	ecdsaKey := keys.tenantKeys["tenant-1"].private
+	if ecdsaKey.PublicKey.X == nil || !ecdsaKey.PublicKey.Curve.IsOnCurve(ecdsaKey.PublicKey.X, ecdsaKey.PublicKey.Y) {
+		t.Error("signing key is not on the curve")
+	}
+
+	if _, err := NewVAPIDKeys("not-a-key", nil); err == nil {
+		t.Error("NewVAPIDKeys() should refuse invalid keys")
+	}
+}
@@ -0,0 +1,81 @@
+package sms
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/kilang-desa-murni/crm/internal/notification/application/ports"
+)
+
+// ============================================================================
+// Console Provider (Dev Mode)
+// ============================================================================
+
+// ConsoleProvider implements SMSProvider by writing messages to the console
+// instead of sending them, for dev mode.
+type ConsoleProvider struct {
+	out io.Writer
+	mu  sync.Mutex
+}
+
+// NewConsoleProvider creates a console SMS provider writing to out, or to
+// standard output if out is nil.
+func NewConsoleProvider(out io.Writer) *ConsoleProvider {
+	if out == nil {
+		out = os.Stdout
+	}
+	return &ConsoleProvider{out: out}
+}
+
+// SendSMS writes the message to the console.
+func (p *ConsoleProvider) SendSMS(ctx context.Context, request ports.SMSRequest) (*ports.SMSResponse, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	_, err := fmt.Fprintf(p.out, "----- sms %s -----\nFrom: %s\nTo: %s\n\n%s\n-----\n",
+		request.MessageID, request.From, request.To, request.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to write SMS: %w", err)
+	}
+
+	return &ports.SMSResponse{
+		MessageID:    request.MessageID,
+		ProviderID:   fmt.Sprintf("console-%d", time.Now().UnixNano()),
+		Provider:     "console",
+		Status:       "sent",
+		StatusCode:   200,
+		SegmentCount: 1,
+		SentAt:       time.Now(),
+	}, nil
+}
+
+// ValidatePhoneNumber validates a phone number.
+func (p *ConsoleProvider) ValidatePhoneNumber(ctx context.Context, phone string) (bool, error) {
+	return isValidPhoneFormat(phone), nil
+}
+
+// GetProviderName returns the provider name.
+func (p *ConsoleProvider) GetProviderName() string {
+	return "console"
+}
+
+// IsAvailable always returns true.
+func (p *ConsoleProvider) IsAvailable(ctx context.Context) bool {
+	return true
+}
+
+// GetDeliveryStatus reports every message as delivered.
+func (p *ConsoleProvider) GetDeliveryStatus(ctx context.Context, messageID string) (*ports.SMSDeliveryStatus, error) {
+	return &ports.SMSDeliveryStatus{
+		MessageID:  messageID,
+		ProviderID: messageID,
+		Status:     "delivered",
+	}, nil
+}
+
+// Ensure ConsoleProvider implements SMSProvider
+var _ ports.SMSProvider = (*ConsoleProvider)(nil)
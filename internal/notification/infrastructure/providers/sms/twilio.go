@@ -496,8 +496,9 @@ var _ ports.SMSProvider = (*VonageProvider)(nil)
 type SMSProviderType string
 
 const (
-	ProviderTwilio SMSProviderType = "twilio"
-	ProviderVonage SMSProviderType = "vonage"
+	ProviderTwilio  SMSProviderType = "twilio"
+	ProviderVonage  SMSProviderType = "vonage"
+	ProviderConsole SMSProviderType = "console"
 )
 
 // SMSProviderFactory creates SMS providers.
@@ -521,6 +522,8 @@ func (f *SMSProviderFactory) CreateProvider(providerType SMSProviderType) ports.
 		return NewTwilioProvider(f.twilioConfig)
 	case ProviderVonage:
 		return NewVonageProvider(f.vonageConfig)
+	case ProviderConsole:
+		return NewConsoleProvider(nil)
 	default:
 		return NewTwilioProvider(f.twilioConfig)
 	}
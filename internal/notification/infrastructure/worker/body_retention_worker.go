@@ -0,0 +1,120 @@
+package worker
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/kilang-desa-murni/crm/internal/notification/domain"
+	"github.com/kilang-desa-murni/crm/pkg/logger"
+)
+
+// BodyRetentionConfig holds configuration for the body retention worker.
+type BodyRetentionConfig struct {
+	// Retention is how long notification bodies are kept, per tenant.
+	Retention domain.BodyRetention
+	// Interval is how often expired bodies are cleared.
+	Interval time.Duration
+	// BatchSize bounds the bodies cleared per tenant retention in one run;
+	// anything left over is cleared on the next run.
+	BatchSize int
+}
+
+// DefaultBodyRetentionConfig returns the default worker configuration. The
+// default retention keeps bodies.
+func DefaultBodyRetentionConfig() BodyRetentionConfig {
+	return BodyRetentionConfig{
+		Interval:  time.Hour,
+		BatchSize: 1000,
+	}
+}
+
+// BodyRetentionWorker periodically clears the bodies of sent notifications
+// that have outlived their tenant's body retention. The notifications and
+// their delivery history are kept.
+type BodyRetentionWorker struct {
+	config BodyRetentionConfig
+	repo   domain.NotificationRepository
+	log    *logger.Logger
+
+	stop chan struct{}
+	once sync.Once
+	wg   sync.WaitGroup
+}
+
+// NewBodyRetentionWorker creates a body retention worker.
+func NewBodyRetentionWorker(config BodyRetentionConfig, repo domain.NotificationRepository, log *logger.Logger) *BodyRetentionWorker {
+	defaults := DefaultBodyRetentionConfig()
+	if config.Interval <= 0 {
+		config.Interval = defaults.Interval
+	}
+	if config.BatchSize <= 0 {
+		config.BatchSize = defaults.BatchSize
+	}
+
+	return &BodyRetentionWorker{
+		config: config,
+		repo:   repo,
+		log:    log,
+		stop:   make(chan struct{}),
+	}
+}
+
+// Start starts clearing expired bodies in the background.
+func (w *BodyRetentionWorker) Start(ctx context.Context) {
+	w.wg.Add(1)
+	go func() {
+		defer w.wg.Done()
+
+		ticker := time.NewTicker(w.config.Interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				w.Purge(ctx)
+			case <-w.stop:
+				return
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+}
+
+// Purge clears the bodies that are past their retention now and returns how
+// many were cleared.
+func (w *BodyRetentionWorker) Purge(ctx context.Context) int64 {
+	var purged int64
+	for _, filter := range w.config.Retention.PurgeFilters(time.Now().UTC(), w.config.BatchSize) {
+		count, err := w.repo.PurgeBodies(ctx, filter)
+		if err != nil {
+			w.log.Error().Err(err).Time("before", filter.Before).Msg("Failed to purge notification bodies")
+			continue
+		}
+		purged += count
+	}
+	if purged > 0 {
+		w.log.Info().Int64("purged", purged).Msg("Purged expired notification bodies")
+	}
+	return purged
+}
+
+// Shutdown stops the worker and waits for a running purge to finish, or for
+// ctx to be done.
+func (w *BodyRetentionWorker) Shutdown(ctx context.Context) error {
+	w.once.Do(func() { close(w.stop) })
+
+	done := make(chan struct{})
+	go func() {
+		w.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,428 @@
+// Package worker contains background workers for the Notification service.
+package worker
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/kilang-desa-murni/crm/internal/notification/application/ports"
+	"github.com/kilang-desa-murni/crm/internal/notification/domain"
+	"github.com/kilang-desa-murni/crm/pkg/logger"
+)
+
+var (
+	// ErrDispatchQueueFull is returned when a channel's queue stays full for
+	// the whole submit timeout.
+	ErrDispatchQueueFull = errors.New("dispatch queue full")
+
+	// ErrDispatchPoolClosed is returned when submitting to a pool that is
+	// shutting down.
+	ErrDispatchPoolClosed = errors.New("dispatch pool closed")
+
+	// ErrUnknownDispatchChannel is returned when submitting for a channel the
+	// pool has no workers for.
+	ErrUnknownDispatchChannel = errors.New("unknown dispatch channel")
+)
+
+// DispatchPoolConfig holds configuration for the dispatch pool.
+type DispatchPoolConfig struct {
+	// Workers is the number of workers per channel.
+	Workers map[domain.NotificationChannel]int
+	// DefaultWorkers is the number of workers for channels not in Workers.
+	DefaultWorkers int
+	// QueueSize bounds the jobs waiting for each channel's workers.
+	QueueSize int
+	// SubmitTimeout is how long Submit waits for room in a full queue before
+	// rejecting the job, pushing back on the caller.
+	SubmitTimeout time.Duration
+	// RetryPolicies retries failed jobs by the policy of their channel. Jobs
+	// are not retried when it is nil.
+	RetryPolicies *domain.RetryPolicies
+	// OnExhausted, if set, is called for a job that failed and will not be
+	// retried again.
+	OnExhausted func(ctx context.Context, failure DispatchFailure)
+}
+
+// DefaultDispatchPoolConfig returns the default pool configuration.
+func DefaultDispatchPoolConfig() DispatchPoolConfig {
+	return DispatchPoolConfig{
+		Workers: map[domain.NotificationChannel]int{
+			domain.ChannelEmail: 4,
+			domain.ChannelSMS:   2,
+			domain.ChannelPush:  2,
+			domain.ChannelInApp: 2,
+		},
+		DefaultWorkers: 1,
+		QueueSize:      100,
+		SubmitTimeout:  5 * time.Second,
+	}
+}
+
+// DispatchJob is the work of dispatching one notification.
+type DispatchJob func(ctx context.Context) error
+
+// DispatchFailure describes a job that failed for good.
+type DispatchFailure struct {
+	Channel   domain.NotificationChannel
+	Attempts  int
+	Provider  string
+	ErrorCode string
+	Err       error
+}
+
+// DispatchLaneStats reports the state of one channel's workers and queue.
+type DispatchLaneStats struct {
+	Channel       domain.NotificationChannel `json:"channel"`
+	Workers       int                        `json:"workers"`
+	QueueDepth    int                        `json:"queue_depth"`
+	QueueCapacity int                        `json:"queue_capacity"`
+	Active        int64                      `json:"active"`
+	Processed     int64                      `json:"processed"`
+	Failed        int64                      `json:"failed"`
+	Rejected      int64                      `json:"rejected"`
+	Retried       int64                      `json:"retried"`
+	Exhausted     int64                      `json:"exhausted"`
+	AvgLatency    time.Duration              `json:"avg_latency"`
+}
+
+// DispatchPool runs notification dispatch jobs on a fixed number of workers
+// per channel, so a slow SMS gateway cannot hold up email and a burst of
+// events waits in bounded queues instead of piling up in memory.
+type DispatchPool struct {
+	config  DispatchPoolConfig
+	metrics ports.MetricsCollector
+	log     *logger.Logger
+
+	lanes map[domain.NotificationChannel]*dispatchLane
+
+	mu      sync.RWMutex
+	started bool
+	closed  bool
+	wg      sync.WaitGroup
+
+	// stop is closed on shutdown to abandon the retries waiting for their delay
+	stop      chan struct{}
+	retryWait sync.WaitGroup
+}
+
+// dispatchLane is the queue and counters of one channel.
+type dispatchLane struct {
+	channel domain.NotificationChannel
+	workers int
+	jobs    chan queuedJob
+
+	active       atomic.Int64
+	processed    atomic.Int64
+	failed       atomic.Int64
+	rejected     atomic.Int64
+	retried      atomic.Int64
+	exhausted    atomic.Int64
+	latencyTotal atomic.Int64
+}
+
+type queuedJob struct {
+	run      DispatchJob
+	queuedAt time.Time
+	attempts int
+}
+
+// NewDispatchPool creates a dispatch pool with a lane for every notification
+// channel. metrics may be nil.
+func NewDispatchPool(config DispatchPoolConfig, metrics ports.MetricsCollector, log *logger.Logger) *DispatchPool {
+	defaults := DefaultDispatchPoolConfig()
+	if config.Workers == nil {
+		config.Workers = defaults.Workers
+	}
+	if config.DefaultWorkers <= 0 {
+		config.DefaultWorkers = defaults.DefaultWorkers
+	}
+	if config.QueueSize <= 0 {
+		config.QueueSize = defaults.QueueSize
+	}
+	if config.SubmitTimeout <= 0 {
+		config.SubmitTimeout = defaults.SubmitTimeout
+	}
+
+	p := &DispatchPool{
+		config:  config,
+		metrics: metrics,
+		log:     log,
+		lanes:   make(map[domain.NotificationChannel]*dispatchLane, len(domain.ValidChannels)),
+		stop:    make(chan struct{}),
+	}
+	for channel := range domain.ValidChannels {
+		workers := config.Workers[channel]
+		if workers <= 0 {
+			workers = config.DefaultWorkers
+		}
+		p.lanes[channel] = &dispatchLane{
+			channel: channel,
+			workers: workers,
+			jobs:    make(chan queuedJob, config.QueueSize),
+		}
+	}
+	return p
+}
+
+// Start starts the workers. Jobs run with ctx, so cancelling it aborts the
+// jobs in progress; use Shutdown to stop after the queued jobs are done.
+func (p *DispatchPool) Start(ctx context.Context) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.started || p.closed {
+		return
+	}
+	p.started = true
+
+	for _, lane := range p.lanes {
+		for i := 0; i < lane.workers; i++ {
+			p.wg.Add(1)
+			go p.work(ctx, lane)
+		}
+	}
+}
+
+// Submit queues a job for a channel's workers. When the queue is full it
+// waits up to SubmitTimeout for room, and returns ErrDispatchQueueFull if
+// none frees up, so callers such as event consumers can requeue the work.
+func (p *DispatchPool) Submit(ctx context.Context, channel domain.NotificationChannel, job DispatchJob) error {
+	lane, ok := p.lanes[channel]
+	if !ok {
+		return ErrUnknownDispatchChannel
+	}
+
+	return p.enqueue(ctx, lane, queuedJob{run: job, queuedAt: time.Now()})
+}
+
+// enqueue queues a job, waiting up to SubmitTimeout for room.
+func (p *DispatchPool) enqueue(ctx context.Context, lane *dispatchLane, queued queuedJob) error {
+	// The read lock keeps Shutdown from closing the queue during the send
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	if p.closed {
+		return ErrDispatchPoolClosed
+	}
+
+	select {
+	case lane.jobs <- queued:
+		p.recordDepth(ctx, lane)
+		return nil
+	default:
+	}
+
+	timer := time.NewTimer(p.config.SubmitTimeout)
+	defer timer.Stop()
+
+	select {
+	case lane.jobs <- queued:
+		p.recordDepth(ctx, lane)
+		return nil
+	case <-timer.C:
+	case <-ctx.Done():
+	}
+
+	lane.rejected.Add(1)
+	p.count(ctx, "notification.dispatch.rejected", lane)
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+	return ErrDispatchQueueFull
+}
+
+// Shutdown stops accepting jobs and waits for the queued and running jobs to
+// finish, or for ctx to expire. Retries still waiting for their delay are
+// dropped.
+func (p *DispatchPool) Shutdown(ctx context.Context) error {
+	p.mu.Lock()
+	if !p.closed {
+		p.closed = true
+		close(p.stop)
+		for _, lane := range p.lanes {
+			close(lane.jobs)
+		}
+	}
+	p.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		p.wg.Wait()
+		p.retryWait.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Stats returns the state of every channel's lane, ordered by channel.
+func (p *DispatchPool) Stats() []DispatchLaneStats {
+	stats := make([]DispatchLaneStats, 0, len(p.lanes))
+	for _, lane := range p.lanes {
+		s := DispatchLaneStats{
+			Channel:       lane.channel,
+			Workers:       lane.workers,
+			QueueDepth:    len(lane.jobs),
+			QueueCapacity: cap(lane.jobs),
+			Active:        lane.active.Load(),
+			Processed:     lane.processed.Load(),
+			Failed:        lane.failed.Load(),
+			Rejected:      lane.rejected.Load(),
+			Retried:       lane.retried.Load(),
+			Exhausted:     lane.exhausted.Load(),
+		}
+		if done := s.Processed + s.Failed; done > 0 {
+			s.AvgLatency = time.Duration(lane.latencyTotal.Load() / done)
+		}
+		stats = append(stats, s)
+	}
+	sort.Slice(stats, func(i, j int) bool { return stats[i].Channel < stats[j].Channel })
+	return stats
+}
+
+// work runs a lane's jobs until its queue is closed and drained.
+func (p *DispatchPool) work(ctx context.Context, lane *dispatchLane) {
+	defer p.wg.Done()
+
+	for job := range lane.jobs {
+		p.run(ctx, lane, job)
+	}
+}
+
+func (p *DispatchPool) run(ctx context.Context, lane *dispatchLane, job queuedJob) {
+	lane.active.Add(1)
+	defer lane.active.Add(-1)
+
+	start := time.Now()
+	job.attempts++
+	err := p.safeRun(ctx, job.run)
+	latency := time.Since(start)
+	lane.latencyTotal.Add(int64(latency))
+
+	if err != nil {
+		lane.failed.Add(1)
+		p.count(ctx, "notification.dispatch.failed", lane)
+		p.log.Error().Err(err).Str("channel", lane.channel.String()).Msg("Notification dispatch failed")
+		p.retry(ctx, lane, job, err)
+	} else {
+		lane.processed.Add(1)
+		p.count(ctx, "notification.dispatch.processed", lane)
+	}
+
+	if p.metrics != nil {
+		tags := map[string]string{"channel": lane.channel.String()}
+		p.metrics.RecordDuration(ctx, "notification.dispatch.wait", start.Sub(job.queuedAt), tags)
+		p.metrics.RecordDuration(ctx, "notification.dispatch.latency", latency, tags)
+	}
+	p.recordDepth(ctx, lane)
+}
+
+// retry queues a failed job again after the backoff delay of its channel's
+// retry policy, or reports it as exhausted when the error is not retryable
+// or the attempts are used up. The provider and error code are taken from a
+// domain.DeliveryError wrapped in err.
+func (p *DispatchPool) retry(ctx context.Context, lane *dispatchLane, job queuedJob, err error) {
+	if p.config.RetryPolicies == nil {
+		return
+	}
+
+	policy := p.config.RetryPolicies.For(lane.channel)
+	provider, code := domain.DeliveryErrorDetails(err)
+	if !policy.IsRetryable(provider, code) || !policy.ShouldRetry(job.attempts) {
+		p.exhaust(ctx, lane, DispatchFailure{
+			Channel:   lane.channel,
+			Attempts:  job.attempts,
+			Provider:  provider,
+			ErrorCode: code,
+			Err:       err,
+		})
+		return
+	}
+
+	p.mu.RLock()
+	closed := p.closed
+	if !closed {
+		p.retryWait.Add(1)
+	}
+	p.mu.RUnlock()
+	if closed {
+		p.log.Warn().Str("channel", lane.channel.String()).Msg("Dropping dispatch retry, the pool is shutting down")
+		return
+	}
+
+	delay := policy.NextDelay(job.attempts, rand.Float64())
+	lane.retried.Add(1)
+	p.count(ctx, "notification.dispatch.retried", lane)
+
+	go func() {
+		defer p.retryWait.Done()
+
+		timer := time.NewTimer(delay)
+		defer timer.Stop()
+		select {
+		case <-timer.C:
+		case <-p.stop:
+			return
+		case <-ctx.Done():
+			return
+		}
+
+		job.queuedAt = time.Now()
+		if err := p.enqueue(ctx, lane, job); err != nil && !errors.Is(err, ErrDispatchPoolClosed) {
+			p.log.Error().Err(err).Str("channel", lane.channel.String()).Msg("Failed to queue dispatch retry")
+			p.exhaust(ctx, lane, DispatchFailure{
+				Channel:   lane.channel,
+				Attempts:  job.attempts,
+				Provider:  provider,
+				ErrorCode: code,
+				Err:       err,
+			})
+		}
+	}()
+}
+
+// exhaust records a job that failed for good.
+func (p *DispatchPool) exhaust(ctx context.Context, lane *dispatchLane, failure DispatchFailure) {
+	lane.exhausted.Add(1)
+	p.count(ctx, "notification.dispatch.exhausted", lane)
+	if p.config.OnExhausted != nil {
+		p.config.OnExhausted(ctx, failure)
+	}
+}
+
+// safeRun runs a job, turning a panic into an error so one bad job does not
+// take a worker down.
+func (p *DispatchPool) safeRun(ctx context.Context, job DispatchJob) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = errors.New("dispatch job panicked")
+			p.log.Error().Interface("panic", r).Msg("Recovered from panic in dispatch job")
+		}
+	}()
+	return job(ctx)
+}
+
+func (p *DispatchPool) recordDepth(ctx context.Context, lane *dispatchLane) {
+	if p.metrics == nil {
+		return
+	}
+	p.metrics.RecordGauge(ctx, "notification.dispatch.queue_depth", float64(len(lane.jobs)), map[string]string{
+		"channel": lane.channel.String(),
+	})
+}
+
+func (p *DispatchPool) count(ctx context.Context, name string, lane *dispatchLane) {
+	if p.metrics == nil {
+		return
+	}
+	p.metrics.IncrementCounter(ctx, name, map[string]string{"channel": lane.channel.String()})
+}
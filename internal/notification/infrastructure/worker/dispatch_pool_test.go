@@ -0,0 +1,261 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/kilang-desa-murni/crm/internal/notification/domain"
+	"github.com/kilang-desa-murni/crm/pkg/logger"
+)
+
+func newTestDispatchPool(config DispatchPoolConfig) *DispatchPool {
+	return NewDispatchPool(config, nil, logger.New(logger.Config{Level: "error"}))
+}
+
+// laneStats returns the stats of one channel's lane.
+func laneStats(t *testing.T, pool *DispatchPool, channel domain.NotificationChannel) DispatchLaneStats {
+	t.Helper()
+	for _, stats := range pool.Stats() {
+		if stats.Channel == channel {
+			return stats
+		}
+	}
+	t.Fatalf("no lane for %s", channel)
+	return DispatchLaneStats{}
+}
+
+func TestDispatchPool_SubmitQueueFull(t *testing.T) {
+	// Without workers the first job fills the queue for good
+	pool := newTestDispatchPool(DispatchPoolConfig{QueueSize: 1, SubmitTimeout: 50 * time.Millisecond})
+	ctx := context.Background()
+	noop := func(ctx context.Context) error { return nil }
+
+	if err := pool.Submit(ctx, domain.ChannelEmail, noop); err != nil {
+		t.Fatalf("Submit() error = %v", err)
+	}
+
+	start := time.Now()
+	if err := pool.Submit(ctx, domain.ChannelEmail, noop); !errors.Is(err, ErrDispatchQueueFull) {
+		t.Fatalf("expected ErrDispatchQueueFull, got %v", err)
+	}
+	if waited := time.Since(start); waited < 50*time.Millisecond {
+		t.Errorf("expected Submit to wait the submit timeout, returned after %v", waited)
+	}
+
+	cancelled, cancel := context.WithCancel(ctx)
+	cancel()
+	if err := pool.Submit(cancelled, domain.ChannelEmail, noop); !errors.Is(err, context.Canceled) {
+		t.Errorf("expected the context error, got %v", err)
+	}
+
+	// Other channels have their own queue
+	if err := pool.Submit(ctx, domain.ChannelSMS, noop); err != nil {
+		t.Errorf("expected the SMS queue to accept the job, got %v", err)
+	}
+	if err := pool.Submit(ctx, domain.NotificationChannel("fax"), noop); !errors.Is(err, ErrUnknownDispatchChannel) {
+		t.Errorf("expected ErrUnknownDispatchChannel, got %v", err)
+	}
+
+	if stats := laneStats(t, pool, domain.ChannelEmail); stats.Rejected != 2 || stats.QueueDepth != 1 {
+		t.Errorf("expected 2 rejected and 1 queued, got %d and %d", stats.Rejected, stats.QueueDepth)
+	}
+}
+
+func TestDispatchPool_Retry(t *testing.T) {
+	rejected := domain.NewDeliveryError("n-1", "email", "ses", "invalid recipient", domain.DeliveryErrorRejected, false)
+	unavailable := domain.NewDeliveryError("n-1", "email", "ses", "service unavailable", domain.DeliveryErrorUnavailable, true)
+
+	tests := []struct {
+		name          string
+		failures      int32
+		err           error
+		wantCalls     int32
+		wantExhausted *DispatchFailure
+	}{
+		{
+			name:      "succeeds on a retry",
+			failures:  2,
+			err:       unavailable,
+			wantCalls: 3,
+		},
+		{
+			name:          "attempts used up",
+			failures:      10,
+			err:           unavailable,
+			wantCalls:     3,
+			wantExhausted: &DispatchFailure{Attempts: 3, Provider: "ses", ErrorCode: domain.DeliveryErrorUnavailable},
+		},
+		{
+			name:          "non-retryable error",
+			failures:      10,
+			err:           rejected,
+			wantCalls:     1,
+			wantExhausted: &DispatchFailure{Attempts: 1, Provider: "ses", ErrorCode: domain.DeliveryErrorRejected},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			policies := domain.RetryPolicies{Default: domain.RetryPolicy{
+				MaxAttempts:        3,
+				Multiplier:         1,
+				NonRetryableErrors: []string{domain.DeliveryErrorRejected},
+			}}
+			done := make(chan struct{}, 1)
+			var exhausted *DispatchFailure
+			pool := newTestDispatchPool(DispatchPoolConfig{
+				RetryPolicies: &policies,
+				OnExhausted: func(ctx context.Context, failure DispatchFailure) {
+					exhausted = &failure
+					done <- struct{}{}
+				},
+			})
+			pool.Start(context.Background())
+			defer pool.Shutdown(context.Background())
+
+			var calls int32
+			err := pool.Submit(context.Background(), domain.ChannelEmail, func(ctx context.Context) error {
+				if atomic.AddInt32(&calls, 1) <= tt.failures {
+					return tt.err
+				}
+				done <- struct{}{}
+				return nil
+			})
+			if err != nil {
+				t.Fatalf("Submit() error = %v", err)
+			}
+
+			select {
+			case <-done:
+			case <-time.After(time.Second):
+				t.Fatal("the job neither succeeded nor was exhausted")
+			}
+
+			if got := atomic.LoadInt32(&calls); got != tt.wantCalls {
+				t.Errorf("job ran %d times, want %d", got, tt.wantCalls)
+			}
+			if tt.wantExhausted == nil {
+				if exhausted != nil {
+					t.Errorf("expected no exhausted failure, got %+v", exhausted)
+				}
+				return
+			}
+			if exhausted == nil {
+				t.Fatal("expected OnExhausted to be called")
+			}
+			if exhausted.Channel != domain.ChannelEmail || exhausted.Attempts != tt.wantExhausted.Attempts ||
+				exhausted.Provider != tt.wantExhausted.Provider || exhausted.ErrorCode != tt.wantExhausted.ErrorCode ||
+				!errors.Is(exhausted.Err, tt.err) {
+				t.Errorf("exhausted = %+v, want %+v", exhausted, tt.wantExhausted)
+			}
+		})
+	}
+}
+
+func TestDispatchPool_ShutdownDrainsQueue(t *testing.T) {
+	pool := newTestDispatchPool(DispatchPoolConfig{Workers: map[domain.NotificationChannel]int{domain.ChannelEmail: 1}, QueueSize: 10})
+	pool.Start(context.Background())
+
+	var ran int32
+	for i := 0; i < 5; i++ {
+		err := pool.Submit(context.Background(), domain.ChannelEmail, func(ctx context.Context) error {
+			time.Sleep(10 * time.Millisecond)
+			atomic.AddInt32(&ran, 1)
+			return nil
+		})
+		if err != nil {
+			t.Fatalf("Submit() error = %v", err)
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if err := pool.Shutdown(ctx); err != nil {
+		t.Fatalf("Shutdown() error = %v", err)
+	}
+	if got := atomic.LoadInt32(&ran); got != 5 {
+		t.Errorf("expected every queued job to run before Shutdown returned, %d ran", got)
+	}
+	if err := pool.Submit(context.Background(), domain.ChannelEmail, func(ctx context.Context) error { return nil }); !errors.Is(err, ErrDispatchPoolClosed) {
+		t.Errorf("expected ErrDispatchPoolClosed after Shutdown, got %v", err)
+	}
+}
+
+func TestDispatchPool_ShutdownDropsWaitingRetries(t *testing.T) {
+	policies := domain.RetryPolicies{Default: domain.RetryPolicy{MaxAttempts: 3, InitialInterval: 60, MaxInterval: 60, Multiplier: 1}}
+	pool := newTestDispatchPool(DispatchPoolConfig{RetryPolicies: &policies})
+	pool.Start(context.Background())
+
+	failed := make(chan struct{})
+	err := pool.Submit(context.Background(), domain.ChannelEmail, func(ctx context.Context) error {
+		close(failed)
+		return errors.New("connection reset")
+	})
+	if err != nil {
+		t.Fatalf("Submit() error = %v", err)
+	}
+	<-failed
+
+	// The retry waits a minute, which Shutdown must not
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if err := pool.Shutdown(ctx); err != nil {
+		t.Fatalf("Shutdown() error = %v", err)
+	}
+	if stats := laneStats(t, pool, domain.ChannelEmail); stats.Retried != 1 || stats.Exhausted != 0 {
+		t.Errorf("expected 1 retry scheduled and none exhausted, got %d and %d", stats.Retried, stats.Exhausted)
+	}
+}
+
+func TestDispatchPool_ShutdownTimeout(t *testing.T) {
+	pool := newTestDispatchPool(DispatchPoolConfig{})
+	pool.Start(context.Background())
+
+	release := make(chan struct{})
+	defer close(release)
+	started := make(chan struct{})
+	err := pool.Submit(context.Background(), domain.ChannelEmail, func(ctx context.Context) error {
+		close(started)
+		<-release
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Submit() error = %v", err)
+	}
+	<-started
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if err := pool.Shutdown(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected the deadline to stop waiting for a stuck job, got %v", err)
+	}
+}
+
+func TestDispatchPool_RecoversFromPanic(t *testing.T) {
+	pool := newTestDispatchPool(DispatchPoolConfig{Workers: map[domain.NotificationChannel]int{domain.ChannelEmail: 1}})
+	pool.Start(context.Background())
+
+	ctx := context.Background()
+	if err := pool.Submit(ctx, domain.ChannelEmail, func(ctx context.Context) error { panic("template missing") }); err != nil {
+		t.Fatalf("Submit() error = %v", err)
+	}
+	ran := make(chan struct{})
+	if err := pool.Submit(ctx, domain.ChannelEmail, func(ctx context.Context) error { close(ran); return nil }); err != nil {
+		t.Fatalf("Submit() error = %v", err)
+	}
+
+	select {
+	case <-ran:
+	case <-time.After(time.Second):
+		t.Fatal("expected the worker to survive the panic and run the next job")
+	}
+	if err := pool.Shutdown(ctx); err != nil {
+		t.Fatalf("Shutdown() error = %v", err)
+	}
+	if stats := laneStats(t, pool, domain.ChannelEmail); stats.Failed != 1 || stats.Processed != 1 {
+		t.Errorf("expected 1 failed and 1 processed, got %d and %d", stats.Failed, stats.Processed)
+	}
+}
@@ -0,0 +1,108 @@
+package worker
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/kilang-desa-murni/crm/pkg/logger"
+)
+
+// EscalationProcessor pages the next level of escalations whose level timed
+// out.
+type EscalationProcessor interface {
+	ProcessDue(ctx context.Context, limit int) (int, error)
+}
+
+// EscalationWorker periodically escalates critical alerts nobody
+// acknowledged in time to their next level.
+type EscalationWorker struct {
+	interval  time.Duration
+	batchSize int
+	processor EscalationProcessor
+	log       *logger.Logger
+
+	stop chan struct{}
+	once sync.Once
+	wg   sync.WaitGroup
+}
+
+// NewEscalationWorker creates an escalation worker. A non-positive interval
+// checks every 30 seconds, and a non-positive batch size escalates up to 100
+// alerts a run.
+func NewEscalationWorker(interval time.Duration, batchSize int, processor EscalationProcessor, log *logger.Logger) *EscalationWorker {
+	if interval <= 0 {
+		interval = 30 * time.Second
+	}
+	if batchSize <= 0 {
+		batchSize = 100
+	}
+	return &EscalationWorker{
+		interval:  interval,
+		batchSize: batchSize,
+		processor: processor,
+		log:       log,
+		stop:      make(chan struct{}),
+	}
+}
+
+// Start starts escalating due alerts in the background.
+func (w *EscalationWorker) Start(ctx context.Context) {
+	w.wg.Add(1)
+	go func() {
+		defer w.wg.Done()
+
+		ticker := time.NewTicker(w.interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				w.process(ctx)
+			case <-w.stop:
+				return
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+}
+
+// process escalates due alerts, a batch at a time, until none are left or
+// a batch fails.
+func (w *EscalationWorker) process(ctx context.Context) {
+	for {
+		processed, err := w.processor.ProcessDue(ctx, w.batchSize)
+		if err != nil {
+			w.log.Error().Err(err).Int("processed", processed).Msg("Failed to escalate due alerts")
+			return
+		}
+		if processed < w.batchSize {
+			return
+		}
+		select {
+		case <-w.stop:
+			return
+		default:
+		}
+	}
+}
+
+// Shutdown stops the worker and waits for a running batch to finish, or for
+// ctx to be done.
+func (w *EscalationWorker) Shutdown(ctx context.Context) error {
+	w.once.Do(func() { close(w.stop) })
+
+	done := make(chan struct{})
+	go func() {
+		w.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
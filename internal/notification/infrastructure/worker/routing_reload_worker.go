@@ -0,0 +1,93 @@
+package worker
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/kilang-desa-murni/crm/pkg/logger"
+)
+
+// RoutingTableLoader rebuilds the routing table events are routed by.
+type RoutingTableLoader interface {
+	Reload(ctx context.Context) error
+}
+
+// RoutingReloadWorker periodically reloads the routing rules, so changes
+// made through other instances of the service take effect without a
+// restart. Changes made through this instance take effect at once.
+type RoutingReloadWorker struct {
+	interval time.Duration
+	loader   RoutingTableLoader
+	log      *logger.Logger
+
+	stop chan struct{}
+	once sync.Once
+	wg   sync.WaitGroup
+}
+
+// NewRoutingReloadWorker creates a routing reload worker. A non-positive
+// interval reloads every 30 seconds.
+func NewRoutingReloadWorker(interval time.Duration, loader RoutingTableLoader, log *logger.Logger) *RoutingReloadWorker {
+	if interval <= 0 {
+		interval = 30 * time.Second
+	}
+	return &RoutingReloadWorker{
+		interval: interval,
+		loader:   loader,
+		log:      log,
+		stop:     make(chan struct{}),
+	}
+}
+
+// Start loads the routing rules, then starts reloading them in the
+// background. A failed load leaves the rules to be loaded by the first event.
+func (w *RoutingReloadWorker) Start(ctx context.Context) {
+	w.reload(ctx)
+
+	w.wg.Add(1)
+	go func() {
+		defer w.wg.Done()
+
+		ticker := time.NewTicker(w.interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				w.reload(ctx)
+			case <-w.stop:
+				return
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+}
+
+// reload reloads the routing rules; on failure the rules loaded before keep
+// routing events.
+func (w *RoutingReloadWorker) reload(ctx context.Context) {
+	if err := w.loader.Reload(ctx); err != nil {
+		w.log.Error().Err(err).Msg("Failed to reload notification routing rules")
+	}
+}
+
+// Shutdown stops the worker and waits for a running reload to finish, or for
+// ctx to be done.
+func (w *RoutingReloadWorker) Shutdown(ctx context.Context) error {
+	w.once.Do(func() { close(w.stop) })
+
+	done := make(chan struct{})
+	go func() {
+		w.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,115 @@
+package worker
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/kilang-desa-murni/crm/internal/notification/domain"
+	"github.com/kilang-desa-murni/crm/pkg/logger"
+)
+
+// SubscriptionCleanupConfig holds configuration for the subscription cleanup
+// worker.
+type SubscriptionCleanupConfig struct {
+	// Interval is how often expired subscriptions are removed.
+	Interval time.Duration
+	// BatchSize bounds the subscriptions removed in one run; anything left
+	// over is removed on the next run.
+	BatchSize int
+}
+
+// DefaultSubscriptionCleanupConfig returns the default worker configuration.
+func DefaultSubscriptionCleanupConfig() SubscriptionCleanupConfig {
+	return SubscriptionCleanupConfig{
+		Interval:  time.Hour,
+		BatchSize: 1000,
+	}
+}
+
+// SubscriptionCleanupWorker periodically unregisters browser push
+// subscriptions past the expiration time their browser gave them.
+// Subscriptions the push service reports gone are unregistered as soon as a
+// notification to them fails.
+type SubscriptionCleanupWorker struct {
+	config SubscriptionCleanupConfig
+	repo   domain.DeviceRepository
+	log    *logger.Logger
+
+	stop chan struct{}
+	once sync.Once
+	wg   sync.WaitGroup
+}
+
+// NewSubscriptionCleanupWorker creates a subscription cleanup worker.
+func NewSubscriptionCleanupWorker(config SubscriptionCleanupConfig, repo domain.DeviceRepository, log *logger.Logger) *SubscriptionCleanupWorker {
+	defaults := DefaultSubscriptionCleanupConfig()
+	if config.Interval <= 0 {
+		config.Interval = defaults.Interval
+	}
+	if config.BatchSize <= 0 {
+		config.BatchSize = defaults.BatchSize
+	}
+
+	return &SubscriptionCleanupWorker{
+		config: config,
+		repo:   repo,
+		log:    log,
+		stop:   make(chan struct{}),
+	}
+}
+
+// Start starts removing expired subscriptions in the background.
+func (w *SubscriptionCleanupWorker) Start(ctx context.Context) {
+	w.wg.Add(1)
+	go func() {
+		defer w.wg.Done()
+
+		ticker := time.NewTicker(w.config.Interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				w.Cleanup(ctx)
+			case <-w.stop:
+				return
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+}
+
+// Cleanup removes the subscriptions that have expired by now and returns
+// how many were removed.
+func (w *SubscriptionCleanupWorker) Cleanup(ctx context.Context) int64 {
+	removed, err := w.repo.DeleteExpired(ctx, time.Now().UTC(), w.config.BatchSize)
+	if err != nil {
+		w.log.Error().Err(err).Msg("Failed to remove expired push subscriptions")
+		return 0
+	}
+	if removed > 0 {
+		w.log.Info().Int64("removed", removed).Msg("Removed expired push subscriptions")
+	}
+	return removed
+}
+
+// Shutdown stops the worker and waits for a running cleanup to finish, or
+// for ctx to be done.
+func (w *SubscriptionCleanupWorker) Shutdown(ctx context.Context) error {
+	w.once.Do(func() { close(w.stop) })
+
+	done := make(chan struct{})
+	go func() {
+		w.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,105 @@
+package dto
+
+import (
+	"time"
+)
+
+// ============================================================================
+// Accounting Request DTOs
+// ============================================================================
+
+// AccountingMappingDTO maps CRM records to the tenant's chart of accounts.
+type AccountingMappingDTO struct {
+	SalesAccount   string            `json:"sales_account" validate:"required,max=50"`
+	PaymentAccount string            `json:"payment_account" validate:"required,max=50"`
+	TaxCodes       map[string]string `json:"tax_codes,omitempty"`
+	DefaultTaxCode string            `json:"default_tax_code,omitempty" validate:"omitempty,max=50"`
+}
+
+// UpdateAccountingConnectionRequest represents a request to connect the
+// tenant's accounting system.
+type UpdateAccountingConnectionRequest struct {
+	Provider string                `json:"provider" validate:"required,oneof=xero sql_account autocount"`
+	URL      string                `json:"url" validate:"required,url,max=2000"`
+	Enabled  *bool                 `json:"enabled,omitempty"`
+	Mapping  *AccountingMappingDTO `json:"mapping,omitempty"`
+	// RotateSecret replaces the signing secret of an existing connection.
+	RotateSecret bool `json:"rotate_secret,omitempty"`
+}
+
+// ListAccountingSyncsRequest represents a request to list sync records.
+type ListAccountingSyncsRequest struct {
+	Statuses []string `json:"statuses,omitempty" validate:"omitempty,dive,oneof=pending synced failed"`
+	Limit    int      `json:"limit,omitempty" validate:"omitempty,min=1,max=500"`
+}
+
+// ============================================================================
+// Accounting Response DTOs
+// ============================================================================
+
+// AccountingConnectionResponse represents the tenant's accounting connection.
+// The secret verifies the X-Webhook-Signature header of each push and signs
+// the payments the connector posts back.
+type AccountingConnectionResponse struct {
+	Provider  string               `json:"provider"`
+	URL       string               `json:"url"`
+	Secret    string               `json:"secret"`
+	Enabled   bool                 `json:"enabled"`
+	Mapping   AccountingMappingDTO `json:"mapping"`
+	CreatedAt time.Time            `json:"created_at"`
+	UpdatedAt time.Time            `json:"updated_at"`
+}
+
+// AccountingSyncResponse represents the sync of an invoice or payment.
+type AccountingSyncResponse struct {
+	ID            string     `json:"id"`
+	DealID        string     `json:"deal_id"`
+	RecordType    string     `json:"record_type"`
+	RecordID      string     `json:"record_id"`
+	Reference     string     `json:"reference"`
+	Amount        int64      `json:"amount"`
+	Currency      string     `json:"currency"`
+	Status        string     `json:"status"`
+	ExternalID    string     `json:"external_id,omitempty"`
+	Attempts      int        `json:"attempts"`
+	NextAttemptAt *time.Time `json:"next_attempt_at,omitempty"`
+	LastError     string     `json:"last_error,omitempty"`
+	SyncedAt      *time.Time `json:"synced_at,omitempty"`
+	CreatedAt     time.Time  `json:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at"`
+}
+
+// UnsyncedAccountingRecordResponse represents an issued invoice or a payment
+// that has not been queued for accounting.
+type UnsyncedAccountingRecordResponse struct {
+	DealID     string    `json:"deal_id"`
+	DealCode   string    `json:"deal_code"`
+	RecordType string    `json:"record_type"`
+	RecordID   string    `json:"record_id"`
+	Reference  string    `json:"reference"`
+	Amount     int64     `json:"amount"`
+	Currency   string    `json:"currency"`
+	Date       time.Time `json:"date"`
+}
+
+// AccountingReconciliationResponse represents the records that are not in
+// the accounting system.
+type AccountingReconciliationResponse struct {
+	Connected bool                               `json:"connected"`
+	Unsynced  []UnsyncedAccountingRecordResponse `json:"unsynced"`
+	Failed    []AccountingSyncResponse           `json:"failed"`
+	Pending   int                                `json:"pending"`
+	Synced    int                                `json:"synced"`
+}
+
+// AccountingResyncResponse reports the records queued by a re-sync.
+type AccountingResyncResponse struct {
+	Queued int `json:"queued"`
+}
+
+// AccountingWebhookResponse reports the payments recorded from an
+// accounting webhook.
+type AccountingWebhookResponse struct {
+	Received int `json:"received"`
+	Recorded int `json:"recorded"`
+}
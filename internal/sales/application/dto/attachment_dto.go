@@ -0,0 +1,54 @@
+package dto
+
+import (
+	"time"
+)
+
+// ============================================================================
+// Attachment Request DTOs
+// ============================================================================
+
+// UploadAttachmentRequest represents a file attached to an opportunity.
+type UploadAttachmentRequest struct {
+	Filename    string `json:"filename" validate:"required,max=255"`
+	ContentType string `json:"content_type,omitempty"`
+	Content     []byte `json:"-"`
+}
+
+// ============================================================================
+// Attachment Response DTOs
+// ============================================================================
+
+// AttachmentResponse represents an attached file. Variants lists the image
+// sizes that can be requested with ?size= on the download endpoint.
+type AttachmentResponse struct {
+	Name        string    `json:"name"`
+	Filename    string    `json:"filename"`
+	ContentType string    `json:"content_type,omitempty"`
+	Size        int64     `json:"size"`
+	Variants    []string  `json:"variants,omitempty"`
+	UploadedAt  time.Time `json:"uploaded_at"`
+
+	Scan *AttachmentScanResponse `json:"scan,omitempty"`
+}
+
+// AttachmentScanResponse represents the virus scan of an attached file. A
+// file is quarantined, and cannot be downloaded, until scanned clean.
+type AttachmentScanResponse struct {
+	Status      string     `json:"status"`
+	Signature   string     `json:"signature,omitempty"`
+	Quarantined bool       `json:"quarantined"`
+	ScannedAt   *time.Time `json:"scanned_at,omitempty"`
+}
+
+// AttachmentListResponse represents the files attached to an opportunity.
+type AttachmentListResponse struct {
+	Attachments []*AttachmentResponse `json:"attachments"`
+}
+
+// AttachmentFileResponse represents the content of an attached file or one of its image variants.
+type AttachmentFileResponse struct {
+	FileName    string `json:"file_name"`
+	ContentType string `json:"content_type"`
+	Content     []byte `json:"-"`
+}
@@ -0,0 +1,81 @@
+package dto
+
+import (
+	"time"
+)
+
+// ============================================================================
+// Board Request DTOs
+// ============================================================================
+
+// GetBoardRequest represents a request for a pipeline's opportunity board.
+// Without StageID every column is returned with its first page of cards; with
+// StageID only that column is returned, continuing after Cursor.
+type GetBoardRequest struct {
+	StageID string `json:"stage_id,omitempty" validate:"omitempty,uuid"`
+	Cursor  string `json:"cursor,omitempty"`
+	Limit   int    `json:"limit,omitempty" validate:"omitempty,min=1,max=100"`
+}
+
+// ============================================================================
+// Board Response DTOs
+// ============================================================================
+
+// BoardResponse represents a pipeline's opportunity board.
+type BoardResponse struct {
+	PipelineID   string                 `json:"pipeline_id"`
+	PipelineName string                 `json:"pipeline_name"`
+	Currency     string                 `json:"currency"`
+	Columns      []*BoardColumnResponse `json:"columns"`
+}
+
+// BoardColumnResponse represents one stage column of the board. Totals hold
+// one entry per currency present in the column. WIPStatus compares Count with
+// the stage's WIP limit: unlimited, under, at or over.
+type BoardColumnResponse struct {
+	StageID     string               `json:"stage_id"`
+	Name        string               `json:"name"`
+	Type        string               `json:"type"`
+	Order       int                  `json:"order"`
+	Color       string               `json:"color,omitempty"`
+	Probability int                  `json:"probability"`
+	Count       int64                `json:"count"`
+	WIPLimit    int                  `json:"wip_limit,omitempty"`
+	WIPStatus   string               `json:"wip_status"`
+	Totals      []BoardTotalDTO      `json:"totals"`
+	Cards       []*BoardCardResponse `json:"cards"`
+	NextCursor  string               `json:"next_cursor,omitempty"`
+}
+
+// BoardTotalDTO represents the value of a column's cards in one currency.
+type BoardTotalDTO struct {
+	Count          int64    `json:"count"`
+	Amount         MoneyDTO `json:"amount"`
+	WeightedAmount MoneyDTO `json:"weighted_amount"`
+}
+
+// BoardCardResponse represents an opportunity card on the board.
+type BoardCardResponse struct {
+	ID                string     `json:"id"`
+	Code              string     `json:"code"`
+	Name              string     `json:"name"`
+	Status            string     `json:"status"`
+	Priority          string     `json:"priority"`
+	CustomerName      string     `json:"customer_name,omitempty"`
+	OwnerID           string     `json:"owner_id"`
+	OwnerName         string     `json:"owner_name,omitempty"`
+	Amount            MoneyDTO   `json:"amount"`
+	WeightedAmount    MoneyDTO   `json:"weighted_amount"`
+	Probability       int        `json:"probability"`
+	ExpectedCloseDate *time.Time `json:"expected_close_date,omitempty"`
+	StageEnteredAt    time.Time  `json:"stage_entered_at"`
+	DaysInStage       int        `json:"days_in_stage"`
+	IsRotten          bool       `json:"is_rotten"`
+}
+
+// BoardRebuildResponse represents the outcome of rebuilding a board.
+type BoardRebuildResponse struct {
+	PipelineID string    `json:"pipeline_id"`
+	Cards      int       `json:"cards"`
+	RebuiltAt  time.Time `json:"rebuilt_at"`
+}
@@ -0,0 +1,90 @@
+package dto
+
+import (
+	"time"
+)
+
+// ============================================================================
+// Comment Request DTOs
+// ============================================================================
+
+// CreateCommentRequest represents a request to comment on a record. Users
+// are mentioned as @[Display Name](user ID); a comment with a parent replies
+// to the parent's thread.
+type CreateCommentRequest struct {
+	Body     string  `json:"body" validate:"required,max=10000"`
+	ParentID *string `json:"parent_id,omitempty" validate:"omitempty,uuid"`
+}
+
+// UpdateCommentRequest represents a request to edit a comment.
+type UpdateCommentRequest struct {
+	Body string `json:"body" validate:"required,max=10000"`
+}
+
+// ListCommentsRequest represents a request to list the threads on a record
+// or the replies of a thread.
+type ListCommentsRequest struct {
+	Page     int `json:"page,omitempty" validate:"omitempty,min=1"`
+	PageSize int `json:"page_size,omitempty" validate:"omitempty,min=1,max=100"`
+}
+
+// CommentReactionRequest represents a request to react to a comment.
+type CommentReactionRequest struct {
+	Reaction string `json:"reaction" validate:"required,oneof=thumbs_up thumbs_down heart laugh celebrate eyes"`
+}
+
+// ============================================================================
+// Comment Response DTOs
+// ============================================================================
+
+// CommentResponse represents a comment. A deleted comment keeps its place in
+// its thread without a body.
+type CommentResponse struct {
+	ID         string                     `json:"id"`
+	EntityType string                     `json:"entity_type"`
+	EntityID   string                     `json:"entity_id"`
+	ParentID   *string                    `json:"parent_id,omitempty"`
+	AuthorID   string                     `json:"author_id"`
+	Body       string                     `json:"body"`
+	Mentions   []string                   `json:"mentions"`
+	Reactions  []*CommentReactionResponse `json:"reactions"`
+	ReplyCount int64                      `json:"reply_count"`
+	Edited     bool                       `json:"edited"`
+	Deleted    bool                       `json:"deleted"`
+	EditedAt   *time.Time                 `json:"edited_at,omitempty"`
+	DeletedAt  *time.Time                 `json:"deleted_at,omitempty"`
+	CreatedAt  time.Time                  `json:"created_at"`
+	UpdatedAt  time.Time                  `json:"updated_at"`
+}
+
+// CommentReactionResponse represents the users who added a reaction.
+type CommentReactionResponse struct {
+	Reaction string   `json:"reaction"`
+	Count    int      `json:"count"`
+	UserIDs  []string `json:"user_ids"`
+}
+
+// CommentListResponse represents a page of comments.
+type CommentListResponse struct {
+	Comments   []*CommentResponse `json:"comments"`
+	Pagination PaginationResponse `json:"pagination"`
+}
+
+// CommentHistoryResponse represents the audit trail of a comment: the
+// bodies it had before each edit and its deletion.
+type CommentHistoryResponse struct {
+	CommentID string                     `json:"comment_id"`
+	AuthorID  string                     `json:"author_id"`
+	Body      string                     `json:"body"`
+	DeletedBy *string                    `json:"deleted_by,omitempty"`
+	CreatedAt time.Time                  `json:"created_at"`
+	Revisions []*CommentRevisionResponse `json:"revisions"`
+}
+
+// CommentRevisionResponse represents one change of a comment.
+type CommentRevisionResponse struct {
+	Action    string    `json:"action"`
+	Body      string    `json:"body"`
+	ChangedBy string    `json:"changed_by"`
+	ChangedAt time.Time `json:"changed_at"`
+}
@@ -17,6 +17,10 @@ type CreateDealRequest struct {
 	// Source - OpportunityID is required for creating a deal
 	OpportunityID string `json:"opportunity_id" validate:"required,uuid"`
 
+	// Recurring revenue - Subscription is required for recurring deals
+	Type         string                   `json:"type,omitempty" validate:"omitempty,oneof=one_time recurring"`
+	Subscription *DealSubscriptionRequest `json:"subscription,omitempty"`
+
 	// Payment Terms
 	PaymentTerm     string `json:"payment_term,omitempty" validate:"omitempty,oneof=net_15 net_30 net_45 net_60 net_90 due_on_receipt prepaid custom"`
 	PaymentTermDays int    `json:"payment_term_days,omitempty" validate:"omitempty,min=0,max=365"`
@@ -37,6 +41,9 @@ type UpdateDealRequest struct {
 	PaymentTerm     *string `json:"payment_term,omitempty" validate:"omitempty,oneof=net_15 net_30 net_45 net_60 net_90 due_on_receipt prepaid custom"`
 	PaymentTermDays *int    `json:"payment_term_days,omitempty" validate:"omitempty,min=0,max=365"`
 
+	// Recurring revenue - setting a subscription makes the deal recurring
+	Subscription *DealSubscriptionRequest `json:"subscription,omitempty"`
+
 	// Additional Information
 	Tags         []string               `json:"tags,omitempty" validate:"omitempty,max=20,dive,max=50"`
 	CustomFields map[string]interface{} `json:"custom_fields,omitempty"`
@@ -46,6 +53,28 @@ type UpdateDealRequest struct {
 	Version int `json:"version" validate:"required,min=1"`
 }
 
+// DealSubscriptionRequest represents the contract of a recurring deal.
+type DealSubscriptionRequest struct {
+	BillingFrequency  string `json:"billing_frequency" validate:"required,oneof=monthly quarterly semi_annual annual"`
+	RecurringAmount   int64  `json:"recurring_amount" validate:"required,min=1"` // Billed each period
+	ContractStart     string `json:"contract_start" validate:"required,datetime=2006-01-02"`
+	ContractEnd       string `json:"contract_end" validate:"required,datetime=2006-01-02"`
+	RenewalNoticeDays int    `json:"renewal_notice_days,omitempty" validate:"omitempty,min=1,max=365"`
+}
+
+// ChurnDealRequest represents a request to record that a recurring deal will
+// not be renewed.
+type ChurnDealRequest struct {
+	Reason string `json:"reason" validate:"required,max=500"`
+}
+
+// RecurringRevenueRequest represents a request for a recurring revenue report.
+type RecurringRevenueRequest struct {
+	From     string `json:"from" validate:"required,datetime=2006-01-02"`
+	To       string `json:"to" validate:"required,datetime=2006-01-02"`
+	Currency string `json:"currency,omitempty" validate:"omitempty,len=3"`
+}
+
 // AddLineItemRequest represents a request to add a line item to a deal.
 type AddLineItemRequest struct {
 	ProductID    string  `json:"product_id" validate:"required,uuid"`
@@ -59,6 +88,7 @@ type AddLineItemRequest struct {
 	DiscountType string  `json:"discount_type,omitempty" validate:"omitempty,oneof=percentage fixed"`
 	Tax          float64 `json:"tax,omitempty" validate:"omitempty,min=0"`
 	TaxType      string  `json:"tax_type,omitempty" validate:"omitempty,oneof=percentage fixed"`
+	TaxCode      *string `json:"tax_code,omitempty" validate:"omitempty,max=20"`
 	Notes        string  `json:"notes,omitempty" validate:"omitempty,max=500"`
 }
 
@@ -70,6 +100,7 @@ type UpdateLineItemRequest struct {
 	UnitPrice   *int64   `json:"unit_price,omitempty" validate:"omitempty,min=0"`
 	Discount    *float64 `json:"discount,omitempty" validate:"omitempty,min=0"`
 	Tax         *float64 `json:"tax,omitempty" validate:"omitempty,min=0"`
+	TaxCode     *string  `json:"tax_code,omitempty" validate:"omitempty,max=20"`
 	Notes       *string  `json:"notes,omitempty" validate:"omitempty,max=500"`
 }
 
@@ -162,13 +193,19 @@ type DealFilterRequest struct {
 	// Tags
 	Tags []string `json:"tags,omitempty" validate:"omitempty,max=10,dive,max=50"`
 
-	// Pagination
-	Page     int `json:"page,omitempty" validate:"omitempty,min=1"`
-	PageSize int `json:"page_size,omitempty" validate:"omitempty,min=1,max=100"`
+	// Pagination. Cursor, when set, takes precedence over Page.
+	Page     int    `json:"page,omitempty" validate:"omitempty,min=1"`
+	PageSize int    `json:"page_size,omitempty" validate:"omitempty,min=1,max=100"`
+	Cursor   string `json:"cursor,omitempty"`
 
 	// Sorting
 	SortBy    string `json:"sort_by,omitempty" validate:"omitempty,oneof=created_at updated_at closed_date signed_date total_amount deal_number"`
 	SortOrder string `json:"sort_order,omitempty" validate:"omitempty,oneof=asc desc"`
+
+	// Filter and sort expressions, e.g. "status:in:active|fulfilled,total_amount:gte:1000"
+	// and "-created_at". Sort, when set, takes precedence over SortBy.
+	Filter string `json:"filter,omitempty" validate:"omitempty,max=2000"`
+	Sort   string `json:"sort,omitempty" validate:"omitempty,max=200"`
 }
 
 // ============================================================================
@@ -187,12 +224,14 @@ type DealResponse struct {
 
 	// Status
 	Status string `json:"status"`
+	Type   string `json:"type"`
 
 	// Source
 	OpportunityID string                    `json:"opportunity_id"`
 	Opportunity   *OpportunityBriefResponse `json:"opportunity,omitempty"`
 	PipelineID    string                    `json:"pipeline_id"`
 	WonReason     string                    `json:"won_reason,omitempty"`
+	Attribution   *AttributionDTO           `json:"attribution,omitempty"`
 
 	// Customer
 	CustomerID   string            `json:"customer_id"`
@@ -236,6 +275,9 @@ type DealResponse struct {
 	// Timeline
 	Timeline *DealTimelineDTO `json:"timeline,omitempty"`
 
+	// Subscription, for recurring deals
+	Subscription *DealSubscriptionDTO `json:"subscription,omitempty"`
+
 	// Contract
 	ContractURL    string  `json:"contract_url,omitempty"`
 	ContractNumber *string `json:"contract_number,omitempty"`
@@ -401,6 +443,26 @@ type DealTimelineDTO struct {
 	FirstPaymentDue *time.Time `json:"first_payment_due,omitempty"`
 }
 
+// DealSubscriptionDTO represents the contract of a recurring deal.
+type DealSubscriptionDTO struct {
+	BillingFrequency     string     `json:"billing_frequency"`
+	RecurringAmount      MoneyDTO   `json:"recurring_amount"`
+	MRR                  MoneyDTO   `json:"mrr"`
+	ARR                  MoneyDTO   `json:"arr"`
+	ContractStart        time.Time  `json:"contract_start"`
+	ContractEnd          time.Time  `json:"contract_end"`
+	TermMonths           int        `json:"term_months"`
+	ContractValue        MoneyDTO   `json:"contract_value"`
+	RenewalNoticeDays    int        `json:"renewal_notice_days"`
+	RenewalDueAt         time.Time  `json:"renewal_due_at"`
+	RenewalStatus        string     `json:"renewal_status"` // upcoming, in_progress, renewed, churned
+	RenewalOpportunityID *string    `json:"renewal_opportunity_id,omitempty"`
+	RenewedFromDealID    *string    `json:"renewed_from_deal_id,omitempty"`
+	RenewedAt            *time.Time `json:"renewed_at,omitempty"`
+	ChurnedAt            *time.Time `json:"churned_at,omitempty"`
+	ChurnReason          string     `json:"churn_reason,omitempty"`
+}
+
 // DealLineItemDTO represents a line item in a deal (domain-aligned).
 type DealLineItemDTO struct {
 	ID                string     `json:"id"`
@@ -414,6 +476,9 @@ type DealLineItemDTO struct {
 	DiscountType      string     `json:"discount_type,omitempty"` // percentage, fixed
 	Tax               float64    `json:"tax"`
 	TaxType           string     `json:"tax_type,omitempty"` // percentage, fixed
+	TaxCode           string     `json:"tax_code,omitempty"`
+	TaxName           string     `json:"tax_name,omitempty"`
+	TaxInclusive      bool       `json:"tax_inclusive"`
 	Subtotal          MoneyDTO   `json:"subtotal"`
 	TaxAmount         MoneyDTO   `json:"tax_amount"`
 	Total             MoneyDTO   `json:"total"`
@@ -426,18 +491,21 @@ type DealLineItemDTO struct {
 
 // InvoiceDTO represents an invoice in a deal (domain-aligned).
 type InvoiceDTO struct {
-	ID                string     `json:"id"`
-	InvoiceNumber     string     `json:"invoice_number"`
-	Amount            MoneyDTO   `json:"amount"`
-	DueDate           time.Time  `json:"due_date"`
-	Status            string     `json:"status"` // draft, sent, paid, overdue, cancelled
-	SentAt            *time.Time `json:"sent_at,omitempty"`
-	PaidAt            *time.Time `json:"paid_at,omitempty"`
-	PaidAmount        MoneyDTO   `json:"paid_amount"`
-	OutstandingAmount MoneyDTO   `json:"outstanding_amount"`
-	IsOverdue         bool       `json:"is_overdue"`
-	Notes             string     `json:"notes,omitempty"`
-	CreatedAt         time.Time  `json:"created_at"`
+	ID                string       `json:"id"`
+	InvoiceNumber     string       `json:"invoice_number"`
+	Amount            MoneyDTO     `json:"amount"`
+	Subtotal          MoneyDTO     `json:"subtotal"`
+	TaxAmount         MoneyDTO     `json:"tax_amount"`
+	TaxLines          []TaxLineDTO `json:"tax_lines,omitempty"`
+	DueDate           time.Time    `json:"due_date"`
+	Status            string       `json:"status"` // draft, sent, paid, overdue, cancelled
+	SentAt            *time.Time   `json:"sent_at,omitempty"`
+	PaidAt            *time.Time   `json:"paid_at,omitempty"`
+	PaidAmount        MoneyDTO     `json:"paid_amount"`
+	OutstandingAmount MoneyDTO     `json:"outstanding_amount"`
+	IsOverdue         bool         `json:"is_overdue"`
+	Notes             string       `json:"notes,omitempty"`
+	CreatedAt         time.Time    `json:"created_at"`
 }
 
 // PaymentDTO represents a payment in a deal (domain-aligned).
@@ -527,3 +595,55 @@ type RevenueTrendDTO struct {
 	Collected  MoneyDTO `json:"collected"`
 	DealCount  int64    `json:"deal_count"`
 }
+
+// ============================================================================
+// Recurring Revenue DTOs
+// ============================================================================
+
+// RecurringRevenueResponse represents recurring revenue, renewals and churn
+// over a period.
+type RecurringRevenueResponse struct {
+	Period      DateRangeDTO `json:"period"`
+	Currency    string       `json:"currency"`
+	StartingMRR MoneyDTO     `json:"starting_mrr"`
+	EndingMRR   MoneyDTO     `json:"ending_mrr"`
+	EndingARR   MoneyDTO     `json:"ending_arr"`
+	NewMRR      MoneyDTO     `json:"new_mrr"`
+	RenewedMRR  MoneyDTO     `json:"renewed_mrr"`
+	ChurnedMRR  MoneyDTO     `json:"churned_mrr"`
+
+	// Contracts ending in the period, by renewal outcome
+	RenewalsDue    int     `json:"renewals_due"`
+	Renewed        int     `json:"renewed"`
+	Churned        int     `json:"churned"`
+	PendingRenewal int     `json:"pending_renewal"`
+	RenewalRate    float64 `json:"renewal_rate"` // percentage of decided renewals
+	ChurnRate      float64 `json:"churn_rate"`   // churned MRR as percentage of starting MRR
+
+	GeneratedAt time.Time `json:"generated_at"`
+}
+
+// DealRenewalDTO represents a recurring deal coming up for renewal.
+type DealRenewalDTO struct {
+	DealID               string    `json:"deal_id"`
+	Code                 string    `json:"code"`
+	Name                 string    `json:"name"`
+	CustomerID           string    `json:"customer_id"`
+	CustomerName         string    `json:"customer_name"`
+	OwnerID              string    `json:"owner_id"`
+	OwnerName            string    `json:"owner_name"`
+	ContractEnd          time.Time `json:"contract_end"`
+	MRR                  MoneyDTO  `json:"mrr"`
+	RenewalStatus        string    `json:"renewal_status"`
+	RenewalOpportunityID *string   `json:"renewal_opportunity_id,omitempty"`
+}
+
+// DealRenewalsResponse represents the recurring deals whose contract ends
+// within a window.
+type DealRenewalsResponse struct {
+	Currency string            `json:"currency"`
+	From     time.Time         `json:"from"`
+	To       time.Time         `json:"to"`
+	MRRDue   MoneyDTO          `json:"mrr_due"`
+	Renewals []*DealRenewalDTO `json:"renewals"`
+}
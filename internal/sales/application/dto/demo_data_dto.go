@@ -0,0 +1,13 @@
+package dto
+
+// ============================================================================
+// Demo Data Response DTOs
+// ============================================================================
+
+// DemoDataResponse represents the outcome of seeding or purging a tenant's
+// demo data.
+type DemoDataResponse struct {
+	Created  int64 `json:"created"`
+	Existing int64 `json:"existing"`
+	Deleted  int64 `json:"deleted"`
+}
@@ -0,0 +1,110 @@
+package dto
+
+import (
+	"time"
+)
+
+// ============================================================================
+// Discount Approval Request DTOs
+// ============================================================================
+
+// CreateDiscountApprovalRuleRequest represents a request to add a discount approval rule.
+type CreateDiscountApprovalRuleRequest struct {
+	Name             string   `json:"name" validate:"required,min=1,max=200"`
+	ThresholdType    string   `json:"threshold_type" validate:"required,oneof=percentage amount"`
+	ThresholdPercent float64  `json:"threshold_percent,omitempty" validate:"omitempty,gt=0,max=100"`
+	ThresholdAmount  int64    `json:"threshold_amount,omitempty" validate:"omitempty,gt=0"`
+	Currency         string   `json:"currency,omitempty" validate:"omitempty,len=3"`
+	ApproverIDs      []string `json:"approver_ids" validate:"required,min=1,dive,uuid"`
+}
+
+// UpdateDiscountApprovalRuleRequest represents a request to update a discount
+// approval rule. The threshold fields replace the rule's threshold when
+// ThresholdType is set.
+type UpdateDiscountApprovalRuleRequest struct {
+	Name             *string  `json:"name,omitempty" validate:"omitempty,min=1,max=200"`
+	ThresholdType    *string  `json:"threshold_type,omitempty" validate:"omitempty,oneof=percentage amount"`
+	ThresholdPercent float64  `json:"threshold_percent,omitempty" validate:"omitempty,gt=0,max=100"`
+	ThresholdAmount  int64    `json:"threshold_amount,omitempty" validate:"omitempty,gt=0"`
+	Currency         string   `json:"currency,omitempty" validate:"omitempty,len=3"`
+	ApproverIDs      []string `json:"approver_ids,omitempty" validate:"omitempty,min=1,dive,uuid"`
+	IsActive         *bool    `json:"is_active,omitempty"`
+}
+
+// SubmitDiscountApprovalRequest represents a request for approval of an opportunity's discount.
+type SubmitDiscountApprovalRequest struct {
+	Comment string `json:"comment,omitempty" validate:"omitempty,max=2000"`
+}
+
+// DecideDiscountApprovalRequest represents an approver's decision on a discount.
+// A comment is required to reject.
+type DecideDiscountApprovalRequest struct {
+	Comment string `json:"comment,omitempty" validate:"omitempty,max=2000"`
+}
+
+// ListDiscountApprovalsRequest represents a request to list the discount
+// approvals waiting for the current user.
+type ListDiscountApprovalsRequest struct {
+	Page     int `json:"page,omitempty" validate:"omitempty,min=1"`
+	PageSize int `json:"page_size,omitempty" validate:"omitempty,min=1,max=100"`
+}
+
+// ============================================================================
+// Discount Approval Response DTOs
+// ============================================================================
+
+// DiscountApprovalRuleResponse represents a discount approval rule.
+type DiscountApprovalRuleResponse struct {
+	ID               string    `json:"id"`
+	Name             string    `json:"name"`
+	ThresholdType    string    `json:"threshold_type"`
+	ThresholdPercent float64   `json:"threshold_percent,omitempty"`
+	ThresholdAmount  int64     `json:"threshold_amount,omitempty"`
+	Currency         string    `json:"currency,omitempty"`
+	ApproverIDs      []string  `json:"approver_ids"`
+	IsActive         bool      `json:"is_active"`
+	CreatedAt        time.Time `json:"created_at"`
+	UpdatedAt        time.Time `json:"updated_at"`
+}
+
+// DiscountApprovalRuleListResponse represents a list of discount approval rules.
+type DiscountApprovalRuleListResponse struct {
+	Rules []*DiscountApprovalRuleResponse `json:"rules"`
+}
+
+// DiscountApprovalResponse represents a discount approval.
+type DiscountApprovalResponse struct {
+	ID              string     `json:"id"`
+	OpportunityID   string     `json:"opportunity_id"`
+	OpportunityName string     `json:"opportunity_name"`
+	Status          string     `json:"status"`
+	DiscountAmount  int64      `json:"discount_amount"`
+	DiscountPercent float64    `json:"discount_percent"`
+	Currency        string     `json:"currency"`
+	RuleIDs         []string   `json:"rule_ids"`
+	ApproverIDs     []string   `json:"approver_ids"`
+	RequestedBy     string     `json:"requested_by"`
+	RequestComment  string     `json:"request_comment,omitempty"`
+	DecidedBy       *string    `json:"decided_by,omitempty"`
+	DecisionComment string     `json:"decision_comment,omitempty"`
+	RequestedAt     time.Time  `json:"requested_at"`
+	DecidedAt       *time.Time `json:"decided_at,omitempty"`
+}
+
+// DiscountApprovalListResponse represents a list of discount approvals.
+type DiscountApprovalListResponse struct {
+	Approvals  []*DiscountApprovalResponse `json:"approvals"`
+	Pagination PaginationResponse          `json:"pagination"`
+}
+
+// OpportunityDiscountApprovalsResponse represents an opportunity's current
+// discount and the approvals requested for it.
+type OpportunityDiscountApprovalsResponse struct {
+	DiscountAmount  int64   `json:"discount_amount"`
+	DiscountPercent float64 `json:"discount_percent"`
+	Currency        string  `json:"currency"`
+	// ApprovalRequired is true while the discount needs an approval it does not
+	// have, blocking the quote PDF and winning the opportunity.
+	ApprovalRequired bool                        `json:"approval_required"`
+	Approvals        []*DiscountApprovalResponse `json:"approvals"`
+}
@@ -0,0 +1,107 @@
+package dto
+
+import (
+	"time"
+)
+
+// ============================================================================
+// E-Commerce Request DTOs
+// ============================================================================
+
+// CreateEcommerceStoreRequest represents a request to connect an online store.
+type CreateEcommerceStoreRequest struct {
+	Platform             string  `json:"platform" validate:"required,oneof=shopify woocommerce"`
+	Name                 string  `json:"name" validate:"required,max=100"`
+	StoreURL             string  `json:"store_url" validate:"required,url,max=500"`
+	APIKey               string  `json:"api_key,omitempty" validate:"omitempty,max=200"`
+	APISecret            string  `json:"api_secret,omitempty" validate:"omitempty,max=200"`
+	WebhookSecret        string  `json:"webhook_secret,omitempty" validate:"omitempty,max=200"`
+	ImportAbandonedCarts bool    `json:"import_abandoned_carts"`
+	PipelineID           *string `json:"pipeline_id,omitempty" validate:"omitempty,uuid"`
+	OwnerID              *string `json:"owner_id,omitempty" validate:"omitempty,uuid"`
+}
+
+// UpdateEcommerceStoreRequest represents a request to change a store
+// connector. Credentials left empty are kept.
+type UpdateEcommerceStoreRequest struct {
+	Name                 *string `json:"name,omitempty" validate:"omitempty,max=100"`
+	StoreURL             *string `json:"store_url,omitempty" validate:"omitempty,url,max=500"`
+	APIKey               string  `json:"api_key,omitempty" validate:"omitempty,max=200"`
+	APISecret            string  `json:"api_secret,omitempty" validate:"omitempty,max=200"`
+	WebhookSecret        string  `json:"webhook_secret,omitempty" validate:"omitempty,max=200"`
+	Enabled              *bool   `json:"enabled,omitempty"`
+	ImportAbandonedCarts *bool   `json:"import_abandoned_carts,omitempty"`
+	PipelineID           *string `json:"pipeline_id,omitempty" validate:"omitempty,uuid"`
+	OwnerID              *string `json:"owner_id,omitempty" validate:"omitempty,uuid"`
+}
+
+// ListEcommerceImportsRequest represents a request to list a store's imports.
+type ListEcommerceImportsRequest struct {
+	Statuses []string `json:"statuses,omitempty" validate:"omitempty,dive,oneof=pending imported skipped failed"`
+	Limit    int      `json:"limit,omitempty" validate:"omitempty,min=1,max=500"`
+}
+
+// ============================================================================
+// E-Commerce Response DTOs
+// ============================================================================
+
+// EcommerceStoreResponse represents an online store connector. Credentials
+// are never returned, only whether they are set.
+type EcommerceStoreResponse struct {
+	ID                   string     `json:"id"`
+	Platform             string     `json:"platform"`
+	Name                 string     `json:"name"`
+	StoreURL             string     `json:"store_url"`
+	HasAPICredentials    bool       `json:"has_api_credentials"`
+	HasWebhookSecret     bool       `json:"has_webhook_secret"`
+	WebhookPath          string     `json:"webhook_path"`
+	Enabled              bool       `json:"enabled"`
+	ImportAbandonedCarts bool       `json:"import_abandoned_carts"`
+	PipelineID           *string    `json:"pipeline_id,omitempty"`
+	OwnerID              string     `json:"owner_id"`
+	SyncedThrough        *time.Time `json:"synced_through,omitempty"`
+	LastSyncAt           *time.Time `json:"last_sync_at,omitempty"`
+	LastSyncError        string     `json:"last_sync_error,omitempty"`
+	LastWebhookAt        *time.Time `json:"last_webhook_at,omitempty"`
+	CreatedAt            time.Time  `json:"created_at"`
+	UpdatedAt            time.Time  `json:"updated_at"`
+	Version              int        `json:"version"`
+}
+
+// EcommerceImportResponse represents the import of a store order or cart.
+type EcommerceImportResponse struct {
+	ID         string    `json:"id"`
+	Kind       string    `json:"kind"`
+	ExternalID string    `json:"external_id"`
+	Reference  string    `json:"reference"`
+	Email      string    `json:"email,omitempty"`
+	Amount     int64     `json:"amount"`
+	Currency   string    `json:"currency"`
+	Status     string    `json:"status"`
+	CustomerID *string   `json:"customer_id,omitempty"`
+	DealID     *string   `json:"deal_id,omitempty"`
+	LeadID     *string   `json:"lead_id,omitempty"`
+	Reason     string    `json:"reason,omitempty"`
+	Attempts   int       `json:"attempts"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+// EcommerceSyncStatusResponse represents the sync status of a store.
+type EcommerceSyncStatusResponse struct {
+	Store          EcommerceStoreResponse    `json:"store"`
+	OrdersImported int                       `json:"orders_imported"`
+	LeadsCreated   int                       `json:"leads_created"`
+	Skipped        int                       `json:"skipped"`
+	Failed         int                       `json:"failed"`
+	LastImportedAt *time.Time                `json:"last_imported_at,omitempty"`
+	RecentFailures []EcommerceImportResponse `json:"recent_failures"`
+}
+
+// EcommerceImportResultResponse reports the records a sync or webhook imported.
+type EcommerceImportResultResponse struct {
+	Received int `json:"received"`
+	Imported int `json:"imported"`
+	Skipped  int `json:"skipped"`
+	Failed   int `json:"failed"`
+}
@@ -0,0 +1,109 @@
+package dto
+
+import (
+	"time"
+)
+
+// ============================================================================
+// E-Invoice Request DTOs
+// ============================================================================
+
+// EInvoiceAddressDTO represents an address on an e-invoice. State is the
+// MyInvois state code and Country the ISO 3166-1 alpha-3 code.
+type EInvoiceAddressDTO struct {
+	Line1    string `json:"line1" validate:"required,max=150"`
+	Line2    string `json:"line2,omitempty" validate:"omitempty,max=150"`
+	City     string `json:"city" validate:"required,max=50"`
+	Postcode string `json:"postcode,omitempty" validate:"omitempty,max=10"`
+	State    string `json:"state" validate:"required,max=2"`
+	Country  string `json:"country" validate:"required,len=3"`
+}
+
+// EInvoicePartyDTO represents the buyer of an e-invoice.
+type EInvoicePartyDTO struct {
+	Name               string             `json:"name" validate:"required,max=300"`
+	TIN                string             `json:"tin" validate:"required,max=14"`
+	RegistrationNumber string             `json:"registration_number" validate:"required,max=20"`
+	SSTNumber          string             `json:"sst_number,omitempty" validate:"omitempty,max=35"`
+	Email              string             `json:"email,omitempty" validate:"omitempty,email,max=320"`
+	Phone              string             `json:"phone,omitempty" validate:"omitempty,max=20"`
+	Address            EInvoiceAddressDTO `json:"address" validate:"required"`
+}
+
+// UpdateEInvoiceSettingsRequest represents a request to update a tenant's
+// e-invoice supplier details.
+type UpdateEInvoiceSettingsRequest struct {
+	Enabled            *bool               `json:"enabled,omitempty"`
+	Name               *string             `json:"name,omitempty" validate:"omitempty,max=300"`
+	TIN                *string             `json:"tin,omitempty" validate:"omitempty,max=14"`
+	RegistrationNumber *string             `json:"registration_number,omitempty" validate:"omitempty,max=20"`
+	SSTNumber          *string             `json:"sst_number,omitempty" validate:"omitempty,max=35"`
+	MSICCode           *string             `json:"msic_code,omitempty" validate:"omitempty,len=5,numeric"`
+	BusinessActivity   *string             `json:"business_activity,omitempty" validate:"omitempty,max=300"`
+	Email              *string             `json:"email,omitempty" validate:"omitempty,max=320"`
+	Phone              *string             `json:"phone,omitempty" validate:"omitempty,max=20"`
+	Address            *EInvoiceAddressDTO `json:"address,omitempty"`
+}
+
+// SubmitEInvoiceRequest represents a request to submit an invoice to MyInvois.
+// Without a buyer, the invoice is issued to the general public.
+type SubmitEInvoiceRequest struct {
+	Buyer *EInvoicePartyDTO `json:"buyer,omitempty"`
+}
+
+// ResubmitEInvoiceRequest represents a request to submit a rejected or failed
+// e-invoice again, optionally with corrected buyer details.
+type ResubmitEInvoiceRequest struct {
+	Buyer *EInvoicePartyDTO `json:"buyer,omitempty"`
+}
+
+// CancelEInvoiceRequest represents a request to cancel a valid e-invoice.
+type CancelEInvoiceRequest struct {
+	Reason string `json:"reason" validate:"required,max=300"`
+}
+
+// ============================================================================
+// E-Invoice Response DTOs
+// ============================================================================
+
+// EInvoiceSettingsResponse represents a tenant's e-invoice supplier details.
+type EInvoiceSettingsResponse struct {
+	Enabled            bool               `json:"enabled"`
+	Name               string             `json:"name"`
+	TIN                string             `json:"tin"`
+	RegistrationNumber string             `json:"registration_number"`
+	SSTNumber          string             `json:"sst_number,omitempty"`
+	MSICCode           string             `json:"msic_code"`
+	BusinessActivity   string             `json:"business_activity"`
+	Email              string             `json:"email,omitempty"`
+	Phone              string             `json:"phone"`
+	Address            EInvoiceAddressDTO `json:"address"`
+	UpdatedAt          *time.Time         `json:"updated_at,omitempty"`
+}
+
+// EInvoiceResponse represents the submission of an invoice to MyInvois.
+type EInvoiceResponse struct {
+	ID               string           `json:"id"`
+	DealID           string           `json:"deal_id"`
+	InvoiceID        string           `json:"invoice_id"`
+	InvoiceNumber    string           `json:"invoice_number"`
+	Buyer            EInvoicePartyDTO `json:"buyer"`
+	Status           string           `json:"status"`
+	SubmissionUID    string           `json:"submission_uid,omitempty"`
+	DocumentUUID     string           `json:"document_uuid,omitempty"`
+	LongID           string           `json:"long_id,omitempty"`
+	ValidationURL    string           `json:"validation_url,omitempty"` // Encoded in the invoice's QR code
+	ValidationErrors []string         `json:"validation_errors"`
+	Attempts         int              `json:"attempts"`
+	NextAttemptAt    *time.Time       `json:"next_attempt_at,omitempty"`
+	LastError        string           `json:"last_error,omitempty"`
+	SubmittedAt      *time.Time       `json:"submitted_at,omitempty"`
+	ValidatedAt      *time.Time       `json:"validated_at,omitempty"`
+	CancellableUntil *time.Time       `json:"cancellable_until,omitempty"`
+	CancelledAt      *time.Time       `json:"cancelled_at,omitempty"`
+	CancelReason     string           `json:"cancel_reason,omitempty"`
+	CreatedBy        string           `json:"created_by"`
+	CreatedAt        time.Time        `json:"created_at"`
+	UpdatedAt        time.Time        `json:"updated_at"`
+	Version          int              `json:"version"`
+}
@@ -0,0 +1,71 @@
+package dto
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// ============================================================================
+// Event Store Request DTOs
+// ============================================================================
+
+// ListEventsRequest represents a request to read a tenant's recorded events.
+// From and To accept a date (2006-01-02) or an RFC 3339 time.
+type ListEventsRequest struct {
+	AggregateID   string `json:"aggregate_id,omitempty" validate:"omitempty,uuid"`
+	AggregateType string `json:"aggregate_type,omitempty"`
+	EventType     string `json:"event_type,omitempty"`
+	From          string `json:"from,omitempty"`
+	To            string `json:"to,omitempty"`
+	After         int64  `json:"after,omitempty" validate:"omitempty,min=0"`
+	Limit         int    `json:"limit,omitempty" validate:"omitempty,min=1,max=500"`
+}
+
+// ReplayEventsRequest represents a request to replay recorded events in store
+// order. An empty TenantID replays every tenant.
+type ReplayEventsRequest struct {
+	TenantID      string   `json:"tenant_id,omitempty" validate:"omitempty,uuid"`
+	AggregateID   string   `json:"aggregate_id,omitempty" validate:"omitempty,uuid"`
+	AggregateType string   `json:"aggregate_type,omitempty"`
+	EventTypes    []string `json:"event_types,omitempty"`
+	From          string   `json:"from,omitempty"`
+	To            string   `json:"to,omitempty"`
+	AfterPosition int64    `json:"after_position,omitempty" validate:"omitempty,min=0"`
+	BatchSize     int      `json:"batch_size,omitempty" validate:"omitempty,min=1,max=5000"`
+}
+
+// ============================================================================
+// Event Store Response DTOs
+// ============================================================================
+
+// EventRecordResponse represents a recorded domain event.
+type EventRecordResponse struct {
+	Position      int64             `json:"position"`
+	Sequence      int64             `json:"sequence"`
+	ID            string            `json:"id"`
+	AggregateID   string            `json:"aggregate_id"`
+	AggregateType string            `json:"aggregate_type"`
+	EventType     string            `json:"event_type"`
+	Version       int               `json:"version"`
+	Data          json.RawMessage   `json:"data"`
+	Metadata      map[string]string `json:"metadata,omitempty"`
+	OccurredAt    time.Time         `json:"occurred_at"`
+	RecordedAt    time.Time         `json:"recorded_at"`
+}
+
+// EventListResponse represents a page of recorded events. NextAfter is set
+// when more events may follow; pass it as after to read the next page.
+type EventListResponse struct {
+	Events    []*EventRecordResponse `json:"events"`
+	NextAfter *int64                 `json:"next_after,omitempty"`
+}
+
+// ReplayEventsResponse represents the outcome of an event replay.
+// LastPosition is the position of the last event handled, from which a failed
+// replay can be resumed.
+type ReplayEventsResponse struct {
+	Events       int       `json:"events"`
+	LastPosition int64     `json:"last_position"`
+	StartedAt    time.Time `json:"started_at"`
+	CompletedAt  time.Time `json:"completed_at"`
+}
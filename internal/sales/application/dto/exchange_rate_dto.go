@@ -0,0 +1,103 @@
+package dto
+
+import (
+	"time"
+)
+
+// ============================================================================
+// Exchange Rate Request DTOs
+// ============================================================================
+
+// SetExchangeRateRequest represents a request to set a manual exchange rate.
+type SetExchangeRateRequest struct {
+	FromCurrency  string  `json:"from_currency" validate:"required,len=3"`
+	ToCurrency    string  `json:"to_currency" validate:"required,len=3"`
+	Rate          float64 `json:"rate" validate:"required,gt=0"`
+	EffectiveDate string  `json:"effective_date,omitempty" validate:"omitempty,datetime=2006-01-02"`
+}
+
+// ListExchangeRatesRequest represents a request to list exchange rates.
+type ListExchangeRatesRequest struct {
+	FromCurrency string `json:"from_currency,omitempty" validate:"omitempty,len=3"`
+	ToCurrency   string `json:"to_currency,omitempty" validate:"omitempty,len=3"`
+	Source       string `json:"source,omitempty" validate:"omitempty,oneof=manual ecb bnm"`
+	Page         int    `json:"page,omitempty" validate:"omitempty,min=1"`
+	PageSize     int    `json:"page_size,omitempty" validate:"omitempty,min=1,max=100"`
+}
+
+// ConvertCurrencyRequest represents a request to convert an amount between currencies.
+type ConvertCurrencyRequest struct {
+	Amount int64  `json:"amount" validate:"required"`
+	From   string `json:"from" validate:"required,len=3"`
+	To     string `json:"to,omitempty" validate:"omitempty,len=3"` // defaults to the base currency
+	Date   string `json:"date,omitempty" validate:"omitempty,datetime=2006-01-02"`
+}
+
+// UpdateCurrencySettingsRequest represents a request to update currency settings.
+type UpdateCurrencySettingsRequest struct {
+	BaseCurrency *string `json:"base_currency,omitempty" validate:"omitempty,len=3"`
+	RateProvider *string `json:"rate_provider,omitempty" validate:"omitempty,oneof=ecb bnm none"`
+}
+
+// ============================================================================
+// Exchange Rate Response DTOs
+// ============================================================================
+
+// ExchangeRateResponse represents an exchange rate.
+type ExchangeRateResponse struct {
+	ID            string    `json:"id"`
+	FromCurrency  string    `json:"from_currency"`
+	ToCurrency    string    `json:"to_currency"`
+	Rate          float64   `json:"rate"`
+	InverseRate   float64   `json:"inverse_rate"`
+	EffectiveDate string    `json:"effective_date"`
+	Source        string    `json:"source"`
+	CreatedBy     *string   `json:"created_by,omitempty"`
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
+}
+
+// ExchangeRateListResponse represents a paginated list of exchange rates.
+type ExchangeRateListResponse struct {
+	Rates      []*ExchangeRateResponse `json:"rates"`
+	Pagination PaginationResponse      `json:"pagination"`
+}
+
+// ConvertCurrencyResponse represents the result of a currency conversion.
+type ConvertCurrencyResponse struct {
+	Amount    MoneyDTO `json:"amount"`
+	Converted MoneyDTO `json:"converted"`
+	Rate      float64  `json:"rate"`
+	Date      string   `json:"date"`
+}
+
+// RefreshExchangeRatesResponse represents the outcome of fetching provider rates.
+type RefreshExchangeRatesResponse struct {
+	Source        string    `json:"source"`
+	RatesUpdated  int       `json:"rates_updated"`
+	EffectiveDate string    `json:"effective_date,omitempty"`
+	FetchedAt     time.Time `json:"fetched_at"`
+}
+
+// CurrencySettingsResponse represents a tenant's currency settings.
+type CurrencySettingsResponse struct {
+	BaseCurrency  string     `json:"base_currency"`
+	RateProvider  *string    `json:"rate_provider,omitempty"`
+	LastFetchedAt *time.Time `json:"last_fetched_at,omitempty"`
+	UpdatedAt     *time.Time `json:"updated_at,omitempty"`
+}
+
+// CurrencyBreakdownDTO represents the part of a total held in one currency.
+// Converted values are in the currency of the enclosing total; opportunities
+// without a rate into that currency are counted as unconverted and left out
+// of the total.
+type CurrencyBreakdownDTO struct {
+	Currency                string   `json:"currency"`
+	OpportunityCount        int64    `json:"opportunity_count"`
+	Amount                  MoneyDTO `json:"amount"`
+	WeightedAmount          MoneyDTO `json:"weighted_amount"`
+	ConvertedAmount         MoneyDTO `json:"converted_amount"`
+	ConvertedWeightedAmount MoneyDTO `json:"converted_weighted_amount"`
+	ExchangeRate            *float64 `json:"exchange_rate,omitempty"`
+	UnconvertedCount        int64    `json:"unconverted_count,omitempty"`
+}
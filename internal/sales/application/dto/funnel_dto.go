@@ -0,0 +1,61 @@
+package dto
+
+import (
+	"time"
+)
+
+// ============================================================================
+// Funnel Request DTOs
+// ============================================================================
+
+// GetFunnelRequest represents a request for a pipeline's conversion funnel.
+// From and To select opportunities created in an inclusive date range; the
+// other fields narrow the cohort further.
+type GetFunnelRequest struct {
+	From      string `json:"from,omitempty" validate:"omitempty,datetime=2006-01-02"`
+	To        string `json:"to,omitempty" validate:"omitempty,datetime=2006-01-02"`
+	OwnerID   string `json:"owner_id,omitempty" validate:"omitempty,uuid"`
+	Source    string `json:"source,omitempty" validate:"omitempty,max=100"`
+	ProductID string `json:"product_id,omitempty" validate:"omitempty,uuid"`
+}
+
+// ============================================================================
+// Funnel Response DTOs
+// ============================================================================
+
+// FunnelResponse represents a pipeline's conversion funnel for a cohort.
+type FunnelResponse struct {
+	PipelineID            string                `json:"pipeline_id"`
+	PipelineName          string                `json:"pipeline_name"`
+	Filters               GetFunnelRequest      `json:"filters"`
+	Opportunities         int64                 `json:"opportunities"`
+	Lost                  int64                 `json:"lost"`
+	OverallConversionRate float64               `json:"overall_conversion_rate"`
+	Steps                 []*FunnelStepResponse `json:"steps"`
+}
+
+// FunnelStepResponse represents one stage of the funnel. Reached counts the
+// opportunities that got at least as far as the stage, and ConversionRate
+// the percentage of those that reached the next stage. Durations cover
+// completed visits only.
+type FunnelStepResponse struct {
+	StageID         string  `json:"stage_id"`
+	Name            string  `json:"name"`
+	Type            string  `json:"type"`
+	Order           int     `json:"order"`
+	Reached         int64   `json:"reached"`
+	Entered         int64   `json:"entered"`
+	ConversionRate  float64 `json:"conversion_rate"`
+	DropOff         int64   `json:"drop_off"`
+	CompletedVisits int64   `json:"completed_visits"`
+	AverageHours    float64 `json:"average_hours"`
+	MedianHours     float64 `json:"median_hours"`
+}
+
+// FunnelRebuildResponse represents the outcome of rebuilding a pipeline's funnel.
+type FunnelRebuildResponse struct {
+	PipelineID    string    `json:"pipeline_id"`
+	Opportunities int       `json:"opportunities"`
+	Entries       int       `json:"entries"`
+	RebuiltAt     time.Time `json:"rebuilt_at"`
+}
@@ -0,0 +1,71 @@
+package dto
+
+import (
+	"time"
+)
+
+// ============================================================================
+// Inbound Email Request DTOs
+// ============================================================================
+
+// UpdateInboundMailboxRequest represents a request to configure the tenant's
+// forwarding address.
+type UpdateInboundMailboxRequest struct {
+	Enabled        *bool   `json:"enabled,omitempty"`
+	DefaultOwnerID *string `json:"default_owner_id,omitempty" validate:"omitempty,uuid"`
+	RotateAddress  bool    `json:"rotate_address,omitempty"`
+}
+
+// ReceiveInboundEmailRequest represents a raw email delivered by the mail relay.
+// Recipient is the envelope recipient when the relay reports it.
+type ReceiveInboundEmailRequest struct {
+	Recipient string `json:"recipient,omitempty"`
+	Raw       []byte `json:"-"`
+}
+
+// ListInboundEmailsRequest represents a request to list the emails logged on a lead.
+type ListInboundEmailsRequest struct {
+	Page     int `json:"page,omitempty" validate:"omitempty,min=1"`
+	PageSize int `json:"page_size,omitempty" validate:"omitempty,min=1,max=100"`
+}
+
+// ============================================================================
+// Inbound Email Response DTOs
+// ============================================================================
+
+// InboundMailboxResponse represents the tenant's forwarding address.
+type InboundMailboxResponse struct {
+	Address        string     `json:"address"`
+	Enabled        bool       `json:"enabled"`
+	DefaultOwnerID *string    `json:"default_owner_id,omitempty"`
+	UpdatedAt      *time.Time `json:"updated_at,omitempty"`
+}
+
+// InboundEmailResponse represents an ingested email.
+type InboundEmailResponse struct {
+	ID            string    `json:"id"`
+	MessageID     string    `json:"message_id"`
+	FromAddress   string    `json:"from_address"`
+	FromName      string    `json:"from_name,omitempty"`
+	Subject       string    `json:"subject"`
+	Body          string    `json:"body"`
+	LeadID        *string   `json:"lead_id,omitempty"`
+	OpportunityID *string   `json:"opportunity_id,omitempty"`
+	LeadCreated   bool      `json:"lead_created"`
+	AttachmentIDs []string  `json:"attachment_ids,omitempty"`
+	ReceivedAt    time.Time `json:"received_at"`
+}
+
+// InboundEmailListResponse represents the emails logged on a lead.
+type InboundEmailListResponse struct {
+	Emails     []*InboundEmailResponse `json:"emails"`
+	Pagination PaginationResponse      `json:"pagination"`
+}
+
+// InboundEmailResultResponse represents the outcome of delivering an email.
+// Skipped emails are acknowledged so the relay does not retry them.
+type InboundEmailResultResponse struct {
+	Status     string                `json:"status"` // processed, skipped
+	SkipReason string                `json:"skip_reason,omitempty"`
+	Email      *InboundEmailResponse `json:"email,omitempty"`
+}
@@ -0,0 +1,68 @@
+package dto
+
+import (
+	"time"
+)
+
+// ============================================================================
+// Insight Request DTOs
+// ============================================================================
+
+// UpdateInsightSettingsRequest represents a request to update a tenant's
+// insight thresholds. Omitted fields are left unchanged.
+type UpdateInsightSettingsRequest struct {
+	BiggestDealWon      *bool    `json:"biggest_deal_won,omitempty"`
+	LowPipelineCoverage *bool    `json:"low_pipeline_coverage,omitempty"`
+	LeadVolumeDrop      *bool    `json:"lead_volume_drop,omitempty"`
+	Currency            *string  `json:"currency,omitempty" validate:"omitempty,len=3"`
+	QuarterlyQuota      *int64   `json:"quarterly_quota,omitempty" validate:"omitempty,min=0"`
+	MinimumCoverage     *float64 `json:"minimum_coverage,omitempty" validate:"omitempty,gt=0,max=100"`
+	LeadDropPercent     *float64 `json:"lead_drop_percent,omitempty" validate:"omitempty,min=1,max=100"`
+	MinimumWeeklyLeads  *int64   `json:"minimum_weekly_leads,omitempty" validate:"omitempty,min=0"`
+}
+
+// ListInsightsRequest represents a request for a tenant's recent insights.
+type ListInsightsRequest struct {
+	Type  string `json:"type,omitempty" validate:"omitempty,oneof=biggest_deal_won low_pipeline_coverage lead_volume_drop"`
+	Limit int    `json:"limit,omitempty" validate:"omitempty,min=1,max=100"`
+}
+
+// ============================================================================
+// Insight Response DTOs
+// ============================================================================
+
+// InsightSettingsResponse represents a tenant's insight thresholds. Amounts
+// are in minor units of Currency.
+type InsightSettingsResponse struct {
+	BiggestDealWon      bool       `json:"biggest_deal_won"`
+	LowPipelineCoverage bool       `json:"low_pipeline_coverage"`
+	LeadVolumeDrop      bool       `json:"lead_volume_drop"`
+	Currency            string     `json:"currency"`
+	QuarterlyQuota      int64      `json:"quarterly_quota"`
+	MinimumCoverage     float64    `json:"minimum_coverage"`
+	LeadDropPercent     float64    `json:"lead_drop_percent"`
+	MinimumWeeklyLeads  int64      `json:"minimum_weekly_leads"`
+	UpdatedAt           *time.Time `json:"updated_at,omitempty"`
+}
+
+// InsightResponse represents a detected insight. Details holds the figures
+// behind it, which depend on its type.
+type InsightResponse struct {
+	ID         string                 `json:"id"`
+	Type       string                 `json:"type"`
+	PeriodKey  string                 `json:"period_key"`
+	Details    map[string]interface{} `json:"details"`
+	DetectedAt time.Time              `json:"detected_at"`
+}
+
+// InsightListResponse represents a tenant's recent insights, newest first.
+type InsightListResponse struct {
+	Insights []*InsightResponse `json:"insights"`
+}
+
+// InsightRunResponse summarises a run of the scheduled insight checks.
+type InsightRunResponse struct {
+	Tenants  int       `json:"tenants"`
+	Detected int       `json:"detected"`
+	RanAt    time.Time `json:"ran_at"`
+}
@@ -42,6 +42,8 @@ type CreateLeadRequest struct {
 	UTMCampaign    *string `json:"utm_campaign,omitempty" validate:"omitempty,max=100"`
 	UTMTerm        *string `json:"utm_term,omitempty" validate:"omitempty,max=100"`
 	UTMContent     *string `json:"utm_content,omitempty" validate:"omitempty,max=100"`
+	Referrer       *string `json:"referrer,omitempty" validate:"omitempty,max=2000"`
+	LandingPage    *string `json:"landing_page,omitempty" validate:"omitempty,max=2000"`
 
 	// Assignment
 	OwnerID *string `json:"owner_id,omitempty" validate:"omitempty,uuid"`
@@ -61,6 +63,10 @@ type CreateLeadRequest struct {
 	// Consent
 	MarketingConsent *bool `json:"marketing_consent,omitempty"`
 	PrivacyConsent   *bool `json:"privacy_consent,omitempty"`
+
+	// Force creates the lead even when it matches existing leads or
+	// customers.
+	Force bool `json:"force,omitempty"`
 }
 
 // UpdateLeadRequest represents a request to update an existing lead.
@@ -204,6 +210,12 @@ type BulkAssignLeadsRequest struct {
 	Notes   string   `json:"notes,omitempty" validate:"omitempty,max=500"`
 }
 
+// MergeLeadsRequest represents a request to merge duplicate leads into a lead.
+type MergeLeadsRequest struct {
+	TargetID  string   `json:"target_id" validate:"required,uuid"`
+	SourceIDs []string `json:"source_ids" validate:"required,min=1,max=10,dive,uuid"`
+}
+
 // BulkUpdateLeadStatusRequest represents a request to bulk update lead statuses.
 type BulkUpdateLeadStatusRequest struct {
 	LeadIDs []string `json:"lead_ids" validate:"required,min=1,max=100,dive,uuid"`
@@ -245,13 +257,19 @@ type LeadFilterRequest struct {
 	Companies  []string `json:"companies,omitempty" validate:"omitempty,max=20,dive,max=200"`
 	Industries []string `json:"industries,omitempty" validate:"omitempty,max=20,dive,max=100"`
 
-	// Pagination
-	Page     int `json:"page,omitempty" validate:"omitempty,min=1"`
-	PageSize int `json:"page_size,omitempty" validate:"omitempty,min=1,max=100"`
+	// Pagination. Cursor, when set, takes precedence over Page.
+	Page     int    `json:"page,omitempty" validate:"omitempty,min=1"`
+	PageSize int    `json:"page_size,omitempty" validate:"omitempty,min=1,max=100"`
+	Cursor   string `json:"cursor,omitempty"`
 
 	// Sorting
 	SortBy    string `json:"sort_by,omitempty" validate:"omitempty,oneof=created_at updated_at score first_name last_name company"`
 	SortOrder string `json:"sort_order,omitempty" validate:"omitempty,oneof=asc desc"`
+
+	// Filter and sort expressions, e.g. "status:eq:new,score:gte:50"
+	// and "-created_at". Sort, when set, takes precedence over SortBy.
+	Filter string `json:"filter,omitempty" validate:"omitempty,max=2000"`
+	Sort   string `json:"sort,omitempty" validate:"omitempty,max=200"`
 }
 
 // ============================================================================
@@ -297,6 +315,7 @@ type LeadResponse struct {
 	CampaignID     *string `json:"campaign_id,omitempty"`
 	ReferralSource *string `json:"referral_source,omitempty"`
 	UTMParams      *UTMParamsDTO `json:"utm_params,omitempty"`
+	Attribution    *AttributionDTO `json:"attribution,omitempty"`
 
 	// Assignment
 	OwnerID   *string       `json:"owner_id,omitempty"`
@@ -372,6 +391,27 @@ type LeadListResponse struct {
 	Pagination PaginationResponse   `json:"pagination"`
 }
 
+// LeadMatchResponse represents an existing lead or customer a new lead
+// matches.
+type LeadMatchResponse struct {
+	Type      string     `json:"type"` // lead or customer
+	ID        string     `json:"id"`
+	Code      string     `json:"code,omitempty"`
+	Name      string     `json:"name"`
+	Company   *string    `json:"company,omitempty"`
+	Email     *string    `json:"email,omitempty"`
+	Phone     *string    `json:"phone,omitempty"`
+	Status    string     `json:"status"`
+	OwnerID   *string    `json:"owner_id,omitempty"`
+	MatchedOn []string   `json:"matched_on"`
+	CreatedAt *time.Time `json:"created_at,omitempty"`
+}
+
+// LeadMatchesResponse lists the potential duplicates of a new lead.
+type LeadMatchesResponse struct {
+	Matches []*LeadMatchResponse `json:"matches"`
+}
+
 // LeadConversionResponse represents the result of converting a lead.
 type LeadConversionResponse struct {
 	LeadID        string `json:"lead_id"`
@@ -417,6 +457,19 @@ type UTMParamsDTO struct {
 	Content  *string `json:"content,omitempty"`
 }
 
+// AttributionDTO represents where a lead came from, as carried over to its
+// opportunity and deal.
+type AttributionDTO struct {
+	UTMSource   string `json:"utm_source,omitempty"`
+	UTMMedium   string `json:"utm_medium,omitempty"`
+	UTMCampaign string `json:"utm_campaign,omitempty"`
+	UTMTerm     string `json:"utm_term,omitempty"`
+	UTMContent  string `json:"utm_content,omitempty"`
+	Referrer    string `json:"referrer,omitempty"`
+	LandingPage string `json:"landing_page,omitempty"`
+	Channel     string `json:"channel,omitempty"`
+}
+
 // MoneyDTO represents a monetary amount.
 type MoneyDTO struct {
 	Amount   int64  `json:"amount"`
@@ -434,12 +487,13 @@ type UserBriefDTO struct {
 
 // PaginationResponse represents pagination metadata.
 type PaginationResponse struct {
-	Page       int   `json:"page"`
-	PageSize   int   `json:"page_size"`
-	TotalItems int64 `json:"total_items"`
-	TotalPages int   `json:"total_pages"`
-	HasNext    bool  `json:"has_next"`
-	HasPrev    bool  `json:"has_prev"`
+	Page       int    `json:"page"`
+	PageSize   int    `json:"page_size"`
+	TotalItems int64  `json:"total_items"`
+	TotalPages int    `json:"total_pages"`
+	HasNext    bool   `json:"has_next"`
+	HasPrev    bool   `json:"has_prev"`
+	NextCursor string `json:"next_cursor,omitempty"`
 }
 
 // NewPaginationResponse creates a new pagination response.
@@ -504,6 +558,14 @@ func StringPtr(s string) *string {
 	return &s
 }
 
+// StringValue returns the string a pointer points to, or "" for nil.
+func StringValue(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
+
 // IntPtr returns a pointer to the int.
 func IntPtr(i int) *int {
 	return &i
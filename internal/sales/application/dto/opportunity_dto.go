@@ -22,8 +22,9 @@ type CreateOpportunityRequest struct {
 	Amount   int64  `json:"amount" validate:"required,min=0"`
 	Currency string `json:"currency" validate:"required,len=3"`
 
-	// Probability
-	Probability *int `json:"probability,omitempty" validate:"omitempty,min=0,max=100"`
+	// Probability overrides the stage probability until cleared
+	Probability      *int    `json:"probability,omitempty" validate:"omitempty,min=0,max=100"`
+	ForecastCategory *string `json:"forecast_category,omitempty" validate:"omitempty,oneof=commit best_case pipeline omitted"`
 
 	// Dates
 	ExpectedCloseDate string  `json:"expected_close_date" validate:"required,datetime=2006-01-02"`
@@ -69,8 +70,12 @@ type UpdateOpportunityRequest struct {
 	Amount   *int64  `json:"amount,omitempty" validate:"omitempty,min=0"`
 	Currency *string `json:"currency,omitempty" validate:"omitempty,len=3"`
 
-	// Probability
-	Probability *int `json:"probability,omitempty" validate:"omitempty,min=0,max=100"`
+	// Probability overrides the stage probability until cleared with
+	// clear_probability_override; an empty forecast_category derives the
+	// category from the probability again
+	Probability              *int    `json:"probability,omitempty" validate:"omitempty,min=0,max=100"`
+	ClearProbabilityOverride bool    `json:"clear_probability_override,omitempty"`
+	ForecastCategory         *string `json:"forecast_category,omitempty" validate:"omitempty,oneof=commit best_case pipeline omitted"`
 
 	// Dates
 	ExpectedCloseDate *string `json:"expected_close_date,omitempty" validate:"omitempty,datetime=2006-01-02"`
@@ -95,16 +100,31 @@ type UpdateOpportunityRequest struct {
 }
 
 // MoveStageRequest represents a request to move an opportunity to a different stage.
+// Managers set OverrideRules to move it past the stage's WIP limit and entry
+// criteria.
 type MoveStageRequest struct {
-	StageID     string  `json:"stage_id" validate:"required,uuid"`
-	Probability *int    `json:"probability,omitempty" validate:"omitempty,min=0,max=100"`
-	Notes       *string `json:"notes,omitempty" validate:"omitempty,max=1000"`
+	StageID       string  `json:"stage_id" validate:"required,uuid"`
+	Probability   *int    `json:"probability,omitempty" validate:"omitempty,min=0,max=100"`
+	Notes         *string `json:"notes,omitempty" validate:"omitempty,max=1000"`
+	OverrideRules bool    `json:"override_rules,omitempty"`
+}
+
+// RepositionOpportunityRequest represents a request to move an opportunity to a
+// position on the pipeline board. Position is the zero-based index among the
+// other cards of the stage; a position past the end places the card last.
+// Managers set OverrideRules to move it past the stage's WIP limit and entry
+// criteria.
+type RepositionOpportunityRequest struct {
+	StageID       string `json:"stage_id" validate:"required,uuid"`
+	Position      int    `json:"position" validate:"min=0"`
+	OverrideRules bool   `json:"override_rules,omitempty"`
 }
 
 // WinOpportunityRequest represents a request to mark an opportunity as won.
 type WinOpportunityRequest struct {
 	WonStageID    *string `json:"won_stage_id,omitempty" validate:"omitempty,uuid"`
-	WonReason     string  `json:"won_reason" validate:"required,max=200"`
+	WonReasonID   *string `json:"won_reason_id,omitempty" validate:"omitempty,uuid"`
+	WonReason     string  `json:"won_reason" validate:"required_without=WonReasonID,max=200"`
 	WonNotes      *string `json:"won_notes,omitempty" validate:"omitempty,max=2000"`
 	ActualAmount  *int64  `json:"actual_amount,omitempty" validate:"omitempty,min=0"`
 	ActualCloseDate *string `json:"actual_close_date,omitempty" validate:"omitempty,datetime=2006-01-02"`
@@ -119,7 +139,8 @@ type WinOpportunityRequest struct {
 // LoseOpportunityRequest represents a request to mark an opportunity as lost.
 type LoseOpportunityRequest struct {
 	LostStageID    *string `json:"lost_stage_id,omitempty" validate:"omitempty,uuid"`
-	LostReason     string  `json:"lost_reason" validate:"required,max=200"`
+	LostReasonID   *string `json:"lost_reason_id,omitempty" validate:"omitempty,uuid"`
+	LostReason     string  `json:"lost_reason" validate:"required_without=LostReasonID,max=200"`
 	LostNotes      *string `json:"lost_notes,omitempty" validate:"omitempty,max=2000"`
 	CompetitorID   *string `json:"competitor_id,omitempty" validate:"omitempty,uuid"`
 	CompetitorName *string `json:"competitor_name,omitempty" validate:"omitempty,max=200"`
@@ -138,33 +159,40 @@ type AddProductRequest struct {
 	ProductID       string  `json:"product_id" validate:"required,uuid"`
 	ProductName     string  `json:"product_name" validate:"required,max=200"`
 	Quantity        int     `json:"quantity" validate:"required,min=1"`
-	UnitPrice       int64   `json:"unit_price" validate:"required,min=0"`
-	Currency        string  `json:"currency" validate:"required,len=3"`
+	UnitPrice       *int64  `json:"unit_price,omitempty" validate:"omitempty,min=0"` // Defaults to the customer's price list
+	Currency        string  `json:"currency,omitempty" validate:"omitempty,len=3"`   // Defaults to the pipeline currency
 	DiscountPercent *int    `json:"discount_percent,omitempty" validate:"omitempty,min=0,max=100"`
 	DiscountAmount  *int64  `json:"discount_amount,omitempty" validate:"omitempty,min=0"`
+	TaxCode         *string `json:"tax_code,omitempty" validate:"omitempty,max=20"`
 	Description     *string `json:"description,omitempty" validate:"omitempty,max=500"`
 }
 
+// ReplaceProductsRequest represents a request to replace all products of an opportunity.
+type ReplaceProductsRequest struct {
+	Products []*OpportunityProductRequestDTO `json:"products" validate:"max=100,dive"`
+}
+
 // UpdateProductRequest represents a request to update a product in an opportunity.
 type UpdateProductRequest struct {
 	Quantity        *int    `json:"quantity,omitempty" validate:"omitempty,min=1"`
 	UnitPrice       *int64  `json:"unit_price,omitempty" validate:"omitempty,min=0"`
 	DiscountPercent *int    `json:"discount_percent,omitempty" validate:"omitempty,min=0,max=100"`
 	DiscountAmount  *int64  `json:"discount_amount,omitempty" validate:"omitempty,min=0"`
+	TaxCode         *string `json:"tax_code,omitempty" validate:"omitempty,max=20"`
 	Description     *string `json:"description,omitempty" validate:"omitempty,max=500"`
 }
 
 // AddContactRequest represents a request to add a contact to an opportunity.
 type AddContactRequest struct {
 	ContactID  string  `json:"contact_id" validate:"required,uuid"`
-	Role       string  `json:"role" validate:"required,oneof=decision_maker influencer evaluator champion blocker user technical_contact billing_contact other"`
+	Role       string  `json:"role" validate:"required,oneof=decision_maker influencer finance evaluator champion blocker user technical_contact billing_contact other"`
 	IsPrimary  bool    `json:"is_primary"`
 	Notes      *string `json:"notes,omitempty" validate:"omitempty,max=500"`
 }
 
 // UpdateContactRequest represents a request to update a contact in an opportunity.
 type UpdateContactRequest struct {
-	Role      *string `json:"role,omitempty" validate:"omitempty,oneof=decision_maker influencer evaluator champion blocker user technical_contact billing_contact other"`
+	Role      *string `json:"role,omitempty" validate:"omitempty,oneof=decision_maker influencer finance evaluator champion blocker user technical_contact billing_contact other"`
 	IsPrimary *bool   `json:"is_primary,omitempty"`
 	Notes     *string `json:"notes,omitempty" validate:"omitempty,max=500"`
 }
@@ -199,6 +227,14 @@ type BulkAssignOpportunitiesRequest struct {
 	Notes          *string  `json:"notes,omitempty" validate:"omitempty,max=500"`
 }
 
+// MaxBatchGetOpportunities is the most opportunities a batch get may request.
+const MaxBatchGetOpportunities = 500
+
+// BatchGetOpportunitiesRequest represents a request for several opportunities by ID.
+type BatchGetOpportunitiesRequest struct {
+	IDs []string `json:"ids" validate:"required,min=1,max=500,dive,uuid"`
+}
+
 // OpportunityFilterRequest represents filter options for listing opportunities.
 type OpportunityFilterRequest struct {
 	// Status filters
@@ -247,13 +283,19 @@ type OpportunityFilterRequest struct {
 	ClosingThisQuarter bool `json:"closing_this_quarter,omitempty"`
 	Overdue           bool `json:"overdue,omitempty"`
 
-	// Pagination
-	Page     int `json:"page,omitempty" validate:"omitempty,min=1"`
-	PageSize int `json:"page_size,omitempty" validate:"omitempty,min=1,max=100"`
+	// Pagination. Cursor, when set, takes precedence over Page.
+	Page     int    `json:"page,omitempty" validate:"omitempty,min=1"`
+	PageSize int    `json:"page_size,omitempty" validate:"omitempty,min=1,max=100"`
+	Cursor   string `json:"cursor,omitempty"`
 
 	// Sorting
 	SortBy    string `json:"sort_by,omitempty" validate:"omitempty,oneof=created_at updated_at expected_close_date amount probability name"`
 	SortOrder string `json:"sort_order,omitempty" validate:"omitempty,oneof=asc desc"`
+
+	// Filter and sort expressions, e.g. "status:eq:open,amount:gte:1000"
+	// and "-created_at". Sort, when set, takes precedence over SortBy.
+	Filter string `json:"filter,omitempty" validate:"omitempty,max=2000"`
+	Sort   string `json:"sort,omitempty" validate:"omitempty,max=200"`
 }
 
 // ============================================================================
@@ -278,13 +320,21 @@ type OpportunityResponse struct {
 	StageID      string            `json:"stage_id"`
 	Stage        *StageBriefDTO    `json:"stage,omitempty"`
 	StageHistory []*StageHistoryDTO `json:"stage_history,omitempty"`
+	BoardPosition string            `json:"board_position"`
 
 	// Value
 	Amount         MoneyDTO `json:"amount"`
 	WeightedAmount MoneyDTO `json:"weighted_amount"`
 
+	// Base currency value
+	BaseAmount         *MoneyDTO `json:"base_amount,omitempty"`
+	BaseWeightedAmount *MoneyDTO `json:"base_weighted_amount,omitempty"`
+	ExchangeRate       *float64  `json:"exchange_rate,omitempty"`
+
 	// Probability
-	Probability int `json:"probability"`
+	Probability         int    `json:"probability"`
+	ProbabilityOverride *int   `json:"probability_override,omitempty"`
+	ForecastCategory    string `json:"forecast_category"`
 
 	// Dates
 	ExpectedCloseDate time.Time  `json:"expected_close_date"`
@@ -304,23 +354,27 @@ type OpportunityResponse struct {
 	// Products
 	Products     []*OpportunityProductResponseDTO `json:"products,omitempty"`
 	ProductCount int                              `json:"product_count"`
+	TaxSummary   *TaxSummaryDTO                   `json:"tax_summary,omitempty"`
 
 	// Assignment
 	OwnerID string        `json:"owner_id"`
 	Owner   *UserBriefDTO `json:"owner,omitempty"`
 
 	// Source and Attribution
-	Source        string  `json:"source,omitempty"`
-	SourceDetails *string `json:"source_details,omitempty"`
-	CampaignID    *string `json:"campaign_id,omitempty"`
+	Source        string          `json:"source,omitempty"`
+	SourceDetails *string         `json:"source_details,omitempty"`
+	CampaignID    *string         `json:"campaign_id,omitempty"`
+	Attribution   *AttributionDTO `json:"attribution,omitempty"`
 
 	// Win/Loss Information
 	WonAt          *time.Time `json:"won_at,omitempty"`
 	WonBy          *string    `json:"won_by,omitempty"`
+	WonReasonID    *string    `json:"won_reason_id,omitempty"`
 	WonReason      *string    `json:"won_reason,omitempty"`
 	WonNotes       *string    `json:"won_notes,omitempty"`
 	LostAt         *time.Time `json:"lost_at,omitempty"`
 	LostBy         *string    `json:"lost_by,omitempty"`
+	LostReasonID   *string    `json:"lost_reason_id,omitempty"`
 	LostReason     *string    `json:"lost_reason,omitempty"`
 	LostNotes      *string    `json:"lost_notes,omitempty"`
 	CompetitorID   *string    `json:"competitor_id,omitempty"`
@@ -359,6 +413,7 @@ type OpportunityBriefResponse struct {
 	Amount            MoneyDTO   `json:"amount"`
 	WeightedAmount    MoneyDTO   `json:"weighted_amount"`
 	Probability       int        `json:"probability"`
+	ForecastCategory  string     `json:"forecast_category"`
 	StageID           string     `json:"stage_id"`
 	StageName         string     `json:"stage_name"`
 	ExpectedCloseDate time.Time  `json:"expected_close_date"`
@@ -377,6 +432,13 @@ type OpportunityListResponse struct {
 	Summary       *OpportunitySummaryDTO      `json:"summary,omitempty"`
 }
 
+// BatchGetOpportunitiesResponse represents the opportunities found by a batch
+// get and the requested IDs that were not found.
+type BatchGetOpportunitiesResponse struct {
+	Found   []*OpportunityResponse `json:"found"`
+	Missing []string               `json:"missing"`
+}
+
 // OpportunitySummaryDTO represents a summary of opportunities in a list.
 type OpportunitySummaryDTO struct {
 	TotalCount       int64    `json:"total_count"`
@@ -408,7 +470,7 @@ type OpportunityLoseResponse struct {
 // OpportunityContactRequestDTO represents a contact to add to an opportunity.
 type OpportunityContactRequestDTO struct {
 	ContactID string  `json:"contact_id" validate:"required,uuid"`
-	Role      string  `json:"role" validate:"required,oneof=decision_maker influencer evaluator champion blocker user technical_contact billing_contact other"`
+	Role      string  `json:"role" validate:"required,oneof=decision_maker influencer finance evaluator champion blocker user technical_contact billing_contact other"`
 	IsPrimary bool    `json:"is_primary"`
 	Notes     *string `json:"notes,omitempty" validate:"omitempty,max=500"`
 }
@@ -428,10 +490,11 @@ type OpportunityProductRequestDTO struct {
 	ProductID       string  `json:"product_id" validate:"required,uuid"`
 	ProductName     string  `json:"product_name" validate:"required,max=200"`
 	Quantity        int     `json:"quantity" validate:"required,min=1"`
-	UnitPrice       int64   `json:"unit_price" validate:"required,min=0"`
-	Currency        string  `json:"currency" validate:"required,len=3"`
+	UnitPrice       *int64  `json:"unit_price,omitempty" validate:"omitempty,min=0"` // Defaults to the customer's price list
+	Currency        string  `json:"currency,omitempty" validate:"omitempty,len=3"`   // Defaults to the pipeline currency
 	DiscountPercent *int    `json:"discount_percent,omitempty" validate:"omitempty,min=0,max=100"`
 	DiscountAmount  *int64  `json:"discount_amount,omitempty" validate:"omitempty,min=0"`
+	TaxCode         *string `json:"tax_code,omitempty" validate:"omitempty,max=20"`
 	Description     *string `json:"description,omitempty" validate:"omitempty,max=500"`
 }
 
@@ -440,10 +503,16 @@ type OpportunityProductResponseDTO struct {
 	ID              string   `json:"id"`
 	ProductID       string   `json:"product_id"`
 	ProductName     string   `json:"product_name"`
+	Category        string   `json:"category,omitempty"`
 	Quantity        int      `json:"quantity"`
 	UnitPrice       MoneyDTO `json:"unit_price"`
 	DiscountPercent int      `json:"discount_percent"`
 	DiscountAmount  MoneyDTO `json:"discount_amount"`
+	TaxCode         *string  `json:"tax_code,omitempty"`
+	TaxName         *string  `json:"tax_name,omitempty"`
+	TaxRate         float64  `json:"tax_rate"` // Percentage
+	TaxInclusive    bool     `json:"tax_inclusive"`
+	TaxAmount       MoneyDTO `json:"tax_amount"`
 	TotalPrice      MoneyDTO `json:"total_price"`
 	Description     *string  `json:"description,omitempty"`
 }
@@ -457,6 +526,7 @@ type StageHistoryDTO struct {
 	Duration    *int      `json:"duration_seconds,omitempty"`
 	ChangedBy   string    `json:"changed_by"`
 	Notes       *string   `json:"notes,omitempty"`
+	PipelineVersion int   `json:"pipeline_version,omitempty"`
 }
 
 // CompetitorDTO represents a competitor.
@@ -495,6 +565,8 @@ type CustomerBriefDTO struct {
 	Type   string  `json:"type"`
 	Status string  `json:"status"`
 	Email  *string `json:"email,omitempty"`
+	// Segment is the customer's tier when set on the opportunity
+	Segment string `json:"segment,omitempty"`
 }
 
 // ContactBriefDTO represents a brief contact summary.
@@ -523,6 +595,7 @@ type OpportunityStatisticsResponse struct {
 	ByStatus            map[string]int64   `json:"by_status"`
 	ClosingThisMonth    int64              `json:"closing_this_month"`
 	ClosingThisQuarter  int64              `json:"closing_this_quarter"`
+	PipelineByCurrency  []CurrencyBreakdownDTO `json:"pipeline_by_currency,omitempty"`
 }
 
 // PipelineAnalyticsResponse represents pipeline analytics.
@@ -537,6 +610,27 @@ type PipelineAnalyticsResponse struct {
 	Stages            []*StageAnalyticsDTO      `json:"stages"`
 	ConversionFunnel  []*FunnelStepDTO          `json:"conversion_funnel"`
 	Trends            *PipelineTrendsDTO        `json:"trends,omitempty"`
+	WinReasons        []*CloseReasonBreakdownDTO `json:"win_reasons"`
+	LossReasons       []*CloseReasonBreakdownDTO `json:"loss_reasons"`
+	LossCompetitors   []*CompetitorBreakdownDTO  `json:"loss_competitors"`
+	ForecastCategories []ForecastCategoryDTO     `json:"forecast_categories"`
+}
+
+// CloseReasonBreakdownDTO represents the closed opportunities sharing a reason.
+// ReasonID is empty for free-text reasons recorded outside the catalog.
+type CloseReasonBreakdownDTO struct {
+	ReasonID string   `json:"reason_id,omitempty"`
+	Reason   string   `json:"reason"`
+	Count    int64    `json:"count"`
+	Amount   MoneyDTO `json:"amount"`
+}
+
+// CompetitorBreakdownDTO represents the opportunities lost to a competitor.
+type CompetitorBreakdownDTO struct {
+	CompetitorID   string   `json:"competitor_id,omitempty"`
+	CompetitorName string   `json:"competitor_name"`
+	Count          int64    `json:"count"`
+	Amount         MoneyDTO `json:"amount"`
 }
 
 // StageAnalyticsDTO represents analytics for a pipeline stage.
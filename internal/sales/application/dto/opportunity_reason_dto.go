@@ -0,0 +1,52 @@
+package dto
+
+import (
+	"time"
+)
+
+// ============================================================================
+// Opportunity Reason Request DTOs
+// ============================================================================
+
+// CreateOpportunityReasonRequest represents a request to add a reason to the catalog.
+type CreateOpportunityReasonRequest struct {
+	Type        string `json:"type" validate:"required,oneof=won lost"`
+	Name        string `json:"name" validate:"required,min=1,max=200"`
+	Description string `json:"description,omitempty" validate:"omitempty,max=1000"`
+	Order       int    `json:"order,omitempty" validate:"omitempty,min=0"`
+}
+
+// UpdateOpportunityReasonRequest represents a request to update a catalog reason.
+type UpdateOpportunityReasonRequest struct {
+	Name        *string `json:"name,omitempty" validate:"omitempty,min=1,max=200"`
+	Description *string `json:"description,omitempty" validate:"omitempty,max=1000"`
+	Order       *int    `json:"order,omitempty" validate:"omitempty,min=0"`
+	IsActive    *bool   `json:"is_active,omitempty"`
+}
+
+// ListOpportunityReasonsRequest represents a request to list catalog reasons.
+type ListOpportunityReasonsRequest struct {
+	Type       string `json:"type,omitempty" validate:"omitempty,oneof=won lost"`
+	ActiveOnly bool   `json:"active_only,omitempty"`
+}
+
+// ============================================================================
+// Opportunity Reason Response DTOs
+// ============================================================================
+
+// OpportunityReasonResponse represents a catalog reason.
+type OpportunityReasonResponse struct {
+	ID          string    `json:"id"`
+	Type        string    `json:"type"`
+	Name        string    `json:"name"`
+	Description string    `json:"description,omitempty"`
+	IsActive    bool      `json:"is_active"`
+	Order       int       `json:"order"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// OpportunityReasonListResponse represents a list of catalog reasons.
+type OpportunityReasonListResponse struct {
+	Reasons []*OpportunityReasonResponse `json:"reasons"`
+}
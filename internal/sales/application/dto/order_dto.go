@@ -0,0 +1,122 @@
+package dto
+
+import (
+	"time"
+)
+
+// ============================================================================
+// Order Request DTOs
+// ============================================================================
+
+// CreateOrderRequest represents a request to create a production order from a deal.
+type CreateOrderRequest struct {
+	DeliveryDate    string                   `json:"delivery_date,omitempty" validate:"omitempty,datetime=2006-01-02"`
+	ProductionNotes string                   `json:"production_notes,omitempty" validate:"omitempty,max=5000"`
+	Items           []CreateOrderItemRequest `json:"items,omitempty" validate:"omitempty,dive"`
+}
+
+// CreateOrderItemRequest sets the delivery date and production notes of the
+// order item made from a deal line item.
+type CreateOrderItemRequest struct {
+	DealLineItemID string `json:"deal_line_item_id" validate:"required,uuid"`
+	DeliveryDate   string `json:"delivery_date,omitempty" validate:"omitempty,datetime=2006-01-02"`
+	Notes          string `json:"notes,omitempty" validate:"omitempty,max=2000"`
+}
+
+// UpdateOrderRequest represents a request to update an order. An empty
+// delivery date clears it.
+type UpdateOrderRequest struct {
+	DeliveryDate      *string `json:"delivery_date,omitempty" validate:"omitempty,datetime=2006-01-02"`
+	ProductionNotes   *string `json:"production_notes,omitempty" validate:"omitempty,max=5000"`
+	ExternalReference *string `json:"external_reference,omitempty" validate:"omitempty,max=255"`
+}
+
+// UpdateOrderStatusRequest represents a request to move an order through production.
+type UpdateOrderStatusRequest struct {
+	Status         string `json:"status" validate:"required,oneof=in_production shipped delivered cancelled"`
+	Note           string `json:"note,omitempty" validate:"omitempty,max=2000"`
+	TrackingNumber string `json:"tracking_number,omitempty" validate:"omitempty,max=255"`
+}
+
+// ListOrdersRequest represents a request to list orders.
+type ListOrdersRequest struct {
+	Statuses   []string `json:"statuses,omitempty"`
+	DealID     *string  `json:"deal_id,omitempty" validate:"omitempty,uuid"`
+	CustomerID *string  `json:"customer_id,omitempty" validate:"omitempty,uuid"`
+	Page       int      `json:"page,omitempty" validate:"omitempty,min=1"`
+	PageSize   int      `json:"page_size,omitempty" validate:"omitempty,min=1,max=100"`
+}
+
+// UpdateOrderWebhookRequest represents a request to set the tenant's order webhook.
+type UpdateOrderWebhookRequest struct {
+	URL     string `json:"url" validate:"required,url,max=2000"`
+	Enabled *bool  `json:"enabled,omitempty"`
+	// RotateSecret replaces the signing secret of an existing webhook.
+	RotateSecret bool `json:"rotate_secret,omitempty"`
+}
+
+// ============================================================================
+// Order Response DTOs
+// ============================================================================
+
+// OrderResponse represents a production order.
+type OrderResponse struct {
+	ID                string                       `json:"id"`
+	OrderNumber       string                       `json:"order_number"`
+	DealID            string                       `json:"deal_id"`
+	DealCode          string                       `json:"deal_code"`
+	DealName          string                       `json:"deal_name"`
+	CustomerID        string                       `json:"customer_id"`
+	CustomerName      string                       `json:"customer_name"`
+	Status            string                       `json:"status"`
+	Items             []*OrderItemResponse         `json:"items"`
+	DeliveryDate      *time.Time                   `json:"delivery_date,omitempty"`
+	ProductionNotes   string                       `json:"production_notes,omitempty"`
+	ExternalReference string                       `json:"external_reference,omitempty"`
+	TrackingNumber    string                       `json:"tracking_number,omitempty"`
+	StatusHistory     []*OrderStatusChangeResponse `json:"status_history"`
+	CreatedBy         string                       `json:"created_by"`
+	CreatedAt         time.Time                    `json:"created_at"`
+	UpdatedAt         time.Time                    `json:"updated_at"`
+	ShippedAt         *time.Time                   `json:"shipped_at,omitempty"`
+	DeliveredAt       *time.Time                   `json:"delivered_at,omitempty"`
+	CancelledAt       *time.Time                   `json:"cancelled_at,omitempty"`
+	Version           int                          `json:"version"`
+}
+
+// OrderItemResponse represents a product to produce for an order.
+type OrderItemResponse struct {
+	ID             string     `json:"id"`
+	DealLineItemID string     `json:"deal_line_item_id"`
+	ProductID      string     `json:"product_id"`
+	ProductName    string     `json:"product_name"`
+	ProductSKU     string     `json:"product_sku,omitempty"`
+	Description    string     `json:"description,omitempty"`
+	Quantity       int        `json:"quantity"`
+	DeliveryDate   *time.Time `json:"delivery_date,omitempty"`
+	Notes          string     `json:"notes,omitempty"`
+}
+
+// OrderStatusChangeResponse represents a change of an order's status.
+type OrderStatusChangeResponse struct {
+	From      string    `json:"from,omitempty"`
+	To        string    `json:"to"`
+	ChangedBy string    `json:"changed_by"`
+	Note      string    `json:"note,omitempty"`
+	ChangedAt time.Time `json:"changed_at"`
+}
+
+// OrderListResponse represents a paginated list of orders.
+type OrderListResponse struct {
+	Orders     []*OrderResponse   `json:"orders"`
+	Pagination PaginationResponse `json:"pagination"`
+}
+
+// OrderWebhookResponse represents the tenant's order webhook. The secret
+// verifies the X-Webhook-Signature header of each delivery.
+type OrderWebhookResponse struct {
+	URL       string    `json:"url"`
+	Secret    string    `json:"secret"`
+	Enabled   bool      `json:"enabled"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
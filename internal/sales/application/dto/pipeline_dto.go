@@ -72,6 +72,8 @@ type CreateStageRequest struct {
 	// Settings
 	RottenDays       *int  `json:"rotten_days,omitempty" validate:"omitempty,min=1"`
 	RequiredFields   []string `json:"required_fields,omitempty" validate:"omitempty,max=20,dive,max=50"`
+	MinActivities    *int     `json:"min_activities,omitempty" validate:"omitempty,min=0,max=100"`
+	WIPLimit         *int     `json:"wip_limit,omitempty" validate:"omitempty,min=0"`
 	AutoActions      []*StageAutoActionDTO `json:"auto_actions,omitempty" validate:"omitempty,max=10,dive"`
 }
 
@@ -85,6 +87,9 @@ type UpdateStageRequest struct {
 	// Settings
 	RottenDays       *int     `json:"rotten_days,omitempty" validate:"omitempty,min=1"`
 	RequiredFields   []string `json:"required_fields,omitempty" validate:"omitempty,max=20,dive,max=50"`
+	MinActivities    *int     `json:"min_activities,omitempty" validate:"omitempty,min=0,max=100"`
+	// WIPLimit caps the open opportunities in the stage; 0 removes the limit
+	WIPLimit         *int     `json:"wip_limit,omitempty" validate:"omitempty,min=0"`
 	AutoActions      []*StageAutoActionDTO `json:"auto_actions,omitempty" validate:"omitempty,max=10,dive"`
 
 	// Activation
@@ -115,6 +120,24 @@ type AddStageRequest struct {
 	// Settings
 	RottenDays     *int     `json:"rotten_days,omitempty" validate:"omitempty,min=1"`
 	RequiredFields []string `json:"required_fields,omitempty" validate:"omitempty,max=20,dive,max=50"`
+	MinActivities  *int     `json:"min_activities,omitempty" validate:"omitempty,min=0,max=100"`
+	WIPLimit       *int     `json:"wip_limit,omitempty" validate:"omitempty,min=0"`
+}
+
+// MigrateStagesRequest represents a request to move the open opportunities of
+// some stages to other stages of the same pipeline.
+type MigrateStagesRequest struct {
+	Mappings []StageMappingDTO `json:"mappings" validate:"required,min=1,max=20,dive"`
+
+	// DeactivateSourceStages deactivates the source stages once they are empty
+	DeactivateSourceStages bool    `json:"deactivate_source_stages,omitempty"`
+	Notes                  *string `json:"notes,omitempty" validate:"omitempty,max=500"`
+}
+
+// StageMappingDTO maps a source stage to the stage its opportunities move to.
+type StageMappingDTO struct {
+	FromStageID string `json:"from_stage_id" validate:"required,uuid"`
+	ToStageID   string `json:"to_stage_id" validate:"required,uuid"`
 }
 
 // PipelineFilterRequest represents filter options for listing pipelines.
@@ -216,6 +239,8 @@ type StageResponse struct {
 	// Settings
 	RottenDays     *int                  `json:"rotten_days,omitempty"`
 	RequiredFields []string              `json:"required_fields,omitempty"`
+	MinActivities  *int                  `json:"min_activities,omitempty"`
+	WIPLimit       *int                  `json:"wip_limit,omitempty"`
 	AutoActions    []*StageAutoActionDTO `json:"auto_actions,omitempty"`
 
 	// Statistics (optional)
@@ -227,6 +252,38 @@ type StageResponse struct {
 	UpdatedAt time.Time `json:"updated_at"`
 }
 
+// PipelineVersionResponse represents a recorded version of a pipeline.
+type PipelineVersionResponse struct {
+	PipelineID string           `json:"pipeline_id"`
+	Version    int              `json:"version"`
+	Name       string           `json:"name"`
+	Currency   string           `json:"currency"`
+	Stages     []*StageResponse `json:"stages"`
+	CreatedAt  time.Time        `json:"created_at"`
+}
+
+// PipelineVersionListResponse represents a pipeline's version history, newest first.
+type PipelineVersionListResponse struct {
+	PipelineID     string                     `json:"pipeline_id"`
+	CurrentVersion int                        `json:"current_version"`
+	Versions       []*PipelineVersionResponse `json:"versions"`
+}
+
+// StageMigrationResponse represents the outcome of a stage migration.
+type StageMigrationResponse struct {
+	PipelineID string                     `json:"pipeline_id"`
+	Version    int                        `json:"version"`
+	Stages     []*StageMigrationResultDTO `json:"stages"`
+	TotalMoved int                        `json:"total_moved"`
+}
+
+// StageMigrationResultDTO represents the opportunities moved out of one stage.
+type StageMigrationResultDTO struct {
+	FromStageID string `json:"from_stage_id"`
+	ToStageID   string `json:"to_stage_id"`
+	Moved       int    `json:"moved"`
+}
+
 // StageBriefResponse represents a brief stage summary.
 type StageBriefResponse struct {
 	ID          string `json:"id"`
@@ -293,6 +350,15 @@ type PipelineStatisticsDTO struct {
 	AverageDealSize      MoneyDTO           `json:"average_deal_size"`
 	StageDistribution    map[string]int64   `json:"stage_distribution"`
 	ConversionRates      map[string]float64 `json:"conversion_rates"`
+	ForecastCategories   []ForecastCategoryDTO `json:"forecast_categories"`
+}
+
+// ForecastCategoryDTO represents the open opportunities of a forecast category.
+type ForecastCategoryDTO struct {
+	Category         string   `json:"category"` // commit, best_case, pipeline, omitted
+	OpportunityCount int64    `json:"opportunity_count"`
+	TotalValue       MoneyDTO `json:"total_value"`
+	WeightedValue    MoneyDTO `json:"weighted_value"`
 }
 
 // PipelineComparisonRequest represents a request to compare pipelines.
@@ -369,7 +435,7 @@ type ForecastRequest struct {
 	PipelineID  *string `json:"pipeline_id,omitempty" validate:"omitempty,uuid"`
 	StartDate   string  `json:"start_date" validate:"required,datetime=2006-01-02"`
 	EndDate     string  `json:"end_date" validate:"required,datetime=2006-01-02"`
-	Currency    string  `json:"currency" validate:"required,len=3"`
+	Currency    string  `json:"currency,omitempty" validate:"omitempty,len=3"` // defaults to the base currency
 	GroupBy     string  `json:"group_by,omitempty" validate:"omitempty,oneof=day week month quarter"`
 }
 
@@ -382,11 +448,14 @@ type ForecastResponse struct {
 	TotalForecast MoneyDTO          `json:"total_forecast"`
 	BestCase      MoneyDTO          `json:"best_case"`
 	WorstCase     MoneyDTO          `json:"worst_case"`
-	Committed     MoneyDTO          `json:"committed"` // High probability deals
-	Pipeline      MoneyDTO          `json:"pipeline"`  // Medium probability
-	Upside        MoneyDTO          `json:"upside"`    // Low probability
+	Committed     MoneyDTO          `json:"committed"` // Commit category
+	Pipeline      MoneyDTO          `json:"pipeline"`  // Pipeline category
+	Upside        MoneyDTO          `json:"upside"`    // Best case
+	Omitted       MoneyDTO          `json:"omitted"`   // Left out of the forecast
+	ByCategory    []ForecastCategoryDTO `json:"by_category"`
 	ByPeriod      []ForecastPeriodDTO `json:"by_period"`
 	ByOwner       []OwnerForecastDTO  `json:"by_owner,omitempty"`
+	ByCurrency    []CurrencyBreakdownDTO `json:"by_currency,omitempty"`
 }
 
 // ForecastPeriodDTO represents forecast for a specific period.
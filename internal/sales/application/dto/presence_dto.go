@@ -0,0 +1,55 @@
+package dto
+
+import (
+	"time"
+)
+
+// ============================================================================
+// Presence Request DTOs
+// ============================================================================
+
+// SetPresenceModeRequest represents a request to switch a connection between
+// viewing and editing a record.
+type SetPresenceModeRequest struct {
+	Mode string `json:"mode" validate:"required,oneof=viewing editing"`
+}
+
+// ============================================================================
+// Presence Response DTOs
+// ============================================================================
+
+// PresenceResponse represents who is present on a record, and who holds its
+// soft lock.
+type PresenceResponse struct {
+	RecordType    string                  `json:"record_type"`
+	RecordID      string                  `json:"record_id"`
+	Collaborators []*CollaboratorResponse `json:"collaborators"`
+	Lock          *PresenceLockResponse   `json:"lock,omitempty"`
+}
+
+// CollaboratorResponse represents one connection of a user to a record.
+type CollaboratorResponse struct {
+	ConnectionID string     `json:"connection_id"`
+	UserID       string     `json:"user_id"`
+	Mode         string     `json:"mode"`
+	JoinedAt     time.Time  `json:"joined_at"`
+	EditingSince *time.Time `json:"editing_since,omitempty"`
+}
+
+// PresenceLockResponse represents the soft lock on a record. It is a hint
+// that someone is editing the record; saving is not blocked.
+type PresenceLockResponse struct {
+	UserID       string    `json:"user_id"`
+	ConnectionID string    `json:"connection_id"`
+	Since        time.Time `json:"since"`
+}
+
+// PresenceEvent represents a change to who is present on a record, sent to
+// every connection on it. Each event carries the whole presence, so a
+// connection that misses events is still up to date after the next one.
+type PresenceEvent struct {
+	Type         string            `json:"type"` // joined, left or mode_changed
+	ConnectionID string            `json:"connection_id"`
+	UserID       string            `json:"user_id"`
+	Presence     *PresenceResponse `json:"presence"`
+}
@@ -0,0 +1,39 @@
+package dto
+
+import (
+	"time"
+)
+
+// ============================================================================
+// Pricing Request DTOs
+// ============================================================================
+
+// ProductPriceDTO represents the price of a product on a price list.
+type ProductPriceDTO struct {
+	ProductID string `json:"product_id" validate:"required,uuid"`
+	UnitPrice int64  `json:"unit_price" validate:"min=0"`
+}
+
+// PriceListDTO represents a price list for customers of one tier.
+type PriceListDTO struct {
+	Name            string            `json:"name" validate:"required,max=100"`
+	CustomerTier    string            `json:"customer_tier" validate:"required,max=50"`
+	Currency        string            `json:"currency" validate:"required,len=3"`
+	DiscountPercent float64           `json:"discount_percent" validate:"min=0,max=100"`
+	Prices          []ProductPriceDTO `json:"prices" validate:"omitempty,max=1000,dive"`
+}
+
+// UpdatePriceListsRequest represents a request to replace the price lists.
+type UpdatePriceListsRequest struct {
+	PriceLists []PriceListDTO `json:"price_lists" validate:"max=50,dive"`
+}
+
+// ============================================================================
+// Pricing Response DTOs
+// ============================================================================
+
+// PriceListsResponse represents a tenant's price lists.
+type PriceListsResponse struct {
+	PriceLists []PriceListDTO `json:"price_lists"`
+	UpdatedAt  *time.Time     `json:"updated_at,omitempty"`
+}
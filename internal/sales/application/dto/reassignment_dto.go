@@ -0,0 +1,28 @@
+package dto
+
+// ============================================================================
+// Owner Reassignment DTOs
+// ============================================================================
+
+// ReassignRecordsRequest represents a request to transfer a user's open
+// records to other users. The records are handed to the recipients in turn,
+// starting with the first; a single recipient receives all of them.
+type ReassignRecordsRequest struct {
+	FromUserID string                 `json:"from_user_id" validate:"required,uuid"`
+	Recipients []ReassignRecipientDTO `json:"recipients" validate:"required,min=1,dive"`
+}
+
+// ReassignRecipientDTO represents a user receiving reassigned records.
+type ReassignRecipientDTO struct {
+	UserID string `json:"user_id" validate:"required,uuid"`
+	Name   string `json:"name,omitempty"`
+}
+
+// ReassignRecordsResponse represents the outcome of a reassignment: the
+// records transferred, those that could not be, and the number each
+// recipient received by user ID.
+type ReassignRecordsResponse struct {
+	Transferred int64            `json:"transferred"`
+	Failed      int64            `json:"failed"`
+	Assigned    map[string]int64 `json:"assigned"`
+}
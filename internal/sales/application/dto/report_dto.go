@@ -0,0 +1,183 @@
+package dto
+
+import (
+	"time"
+)
+
+// ============================================================================
+// Report Request DTOs
+// ============================================================================
+
+// SalesPerformanceRequest represents a request for a sales performance report.
+type SalesPerformanceRequest struct {
+	From     string `json:"from" validate:"required,datetime=2006-01-02"`
+	To       string `json:"to" validate:"required,datetime=2006-01-02"`
+	GroupBy  string `json:"group_by,omitempty" validate:"omitempty,oneof=owner product region day campaign channel product_category customer_segment"`
+	Currency string `json:"currency,omitempty" validate:"omitempty,len=3"`
+}
+
+// WinLossReportRequest represents a request for a win/loss cohort report. The
+// previous period is the one of the same length ending where From starts.
+type WinLossReportRequest struct {
+	From     string `json:"from" validate:"required,datetime=2006-01-02"`
+	To       string `json:"to" validate:"required,datetime=2006-01-02"`
+	GroupBy  string `json:"group_by,omitempty" validate:"omitempty,oneof=owner product_category customer_segment"`
+	Currency string `json:"currency,omitempty" validate:"omitempty,len=3"`
+}
+
+// RunAggregationRequest represents a request to (re)build daily aggregates.
+type RunAggregationRequest struct {
+	From string `json:"from" validate:"required,datetime=2006-01-02"`
+	To   string `json:"to" validate:"required,datetime=2006-01-02"`
+}
+
+// ============================================================================
+// Report Response DTOs
+// ============================================================================
+
+// SalesPerformanceResponse represents a sales performance report.
+type SalesPerformanceResponse struct {
+	Period      DateRangeDTO                `json:"period"`
+	GroupBy     string                      `json:"group_by"`
+	Currency    string                      `json:"currency"`
+	Totals      SalesPerformanceMetricsDTO  `json:"totals"`
+	Groups      []*SalesPerformanceGroupDTO `json:"groups"`
+	DataAsOf    *time.Time                  `json:"data_as_of,omitempty"`
+	GeneratedAt time.Time                   `json:"generated_at"`
+}
+
+// AttributionReportResponse represents revenue and conversions by the
+// marketing campaign and channel leads came from.
+type AttributionReportResponse struct {
+	Period      DateRangeDTO                `json:"period"`
+	Currency    string                      `json:"currency"`
+	Totals      SalesPerformanceMetricsDTO  `json:"totals"`
+	Campaigns   []*SalesPerformanceGroupDTO `json:"campaigns"`
+	Channels    []*SalesPerformanceGroupDTO `json:"channels"`
+	DataAsOf    *time.Time                  `json:"data_as_of,omitempty"`
+	GeneratedAt time.Time                   `json:"generated_at"`
+}
+
+// SalesPerformanceGroupDTO represents one group of a sales performance report.
+type SalesPerformanceGroupDTO struct {
+	Key   string `json:"key"`
+	Label string `json:"label"`
+	SalesPerformanceMetricsDTO
+}
+
+// SalesPerformanceMetricsDTO contains the metrics reported for a group.
+type SalesPerformanceMetricsDTO struct {
+	NewLeads             int64    `json:"new_leads"`
+	ConvertedLeads       int64    `json:"converted_leads"`
+	ConversionRate       float64  `json:"conversion_rate"`
+	OpportunitiesCreated int64    `json:"opportunities_created"`
+	OpportunitiesWon     int64    `json:"opportunities_won"`
+	OpportunitiesLost    int64    `json:"opportunities_lost"`
+	WinRate              float64  `json:"win_rate"`
+	Revenue              MoneyDTO `json:"revenue"`
+	AverageDealSize      MoneyDTO `json:"average_deal_size"`
+}
+
+// WinLossReportResponse represents win rates and average deal sizes by
+// cohort, compared with the previous period.
+type WinLossReportResponse struct {
+	Period         DateRangeDTO        `json:"period"`
+	PreviousPeriod DateRangeDTO        `json:"previous_period"`
+	GroupBy        string              `json:"group_by"`
+	Currency       string              `json:"currency"`
+	Totals         WinLossCohortDTO    `json:"totals"`
+	Cohorts        []*WinLossCohortDTO `json:"cohorts"`
+	DataAsOf       *time.Time          `json:"data_as_of,omitempty"`
+	GeneratedAt    time.Time           `json:"generated_at"`
+}
+
+// WinLossCohortDTO represents one cohort of a win/loss report.
+type WinLossCohortDTO struct {
+	Key      string            `json:"key,omitempty"`
+	Label    string            `json:"label,omitempty"`
+	Current  WinLossMetricsDTO `json:"current"`
+	Previous WinLossMetricsDTO `json:"previous"`
+	Change   WinLossChangeDTO  `json:"change"`
+}
+
+// WinLossMetricsDTO contains the closed opportunity metrics of a cohort.
+type WinLossMetricsDTO struct {
+	OpportunitiesWon  int64    `json:"opportunities_won"`
+	OpportunitiesLost int64    `json:"opportunities_lost"`
+	WinRate           float64  `json:"win_rate"`
+	Revenue           MoneyDTO `json:"revenue"`
+	AverageDealSize   MoneyDTO `json:"average_deal_size"`
+}
+
+// WinLossChangeDTO contains the change of a cohort since the previous period.
+// WinRate is in percentage points; AverageDealSizePercent is omitted when
+// nothing was won in the previous period.
+type WinLossChangeDTO struct {
+	OpportunitiesWon       int64    `json:"opportunities_won"`
+	WinRate                float64  `json:"win_rate"`
+	AverageDealSize        MoneyDTO `json:"average_deal_size"`
+	AverageDealSizePercent *float64 `json:"average_deal_size_percent,omitempty"`
+}
+
+// AggregationRunResponse represents the outcome of an aggregation run.
+type AggregationRunResponse struct {
+	Days        int       `json:"days"`
+	RowsWritten int64     `json:"rows_written"`
+	StartedAt   time.Time `json:"started_at"`
+	CompletedAt time.Time `json:"completed_at"`
+}
+
+// ============================================================================
+// Report Export DTOs
+// ============================================================================
+
+// CreateReportExportRequest represents a request to export a report asynchronously.
+type CreateReportExportRequest struct {
+	Report string `json:"report" validate:"required,oneof=sales-performance"`
+	Format string `json:"format" validate:"required,oneof=xlsx pdf"`
+	SalesPerformanceRequest
+}
+
+// ReportExportResponse represents an asynchronous report export.
+type ReportExportResponse struct {
+	ID          string     `json:"id"`
+	Report      string     `json:"report"`
+	Format      string     `json:"format"`
+	Status      string     `json:"status"`
+	FileName    *string    `json:"file_name,omitempty"`
+	FileSize    int64      `json:"file_size,omitempty"`
+	Error       *string    `json:"error,omitempty"`
+	DownloadURL *string    `json:"download_url,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+	CompletedAt *time.Time `json:"completed_at,omitempty"`
+	ExpiresAt   *time.Time `json:"expires_at,omitempty"`
+}
+
+// ReportFileResponse contains a rendered report file.
+type ReportFileResponse struct {
+	FileName    string `json:"file_name"`
+	ContentType string `json:"content_type"`
+	Content     []byte `json:"-"`
+}
+
+// ============================================================================
+// Report Branding DTOs
+// ============================================================================
+
+// UpdateReportBrandingRequest represents a request to customize rendered reports.
+type UpdateReportBrandingRequest struct {
+	CompanyName     *string `json:"company_name,omitempty" validate:"omitempty,min=1,max=255"`
+	Locale          *string `json:"locale,omitempty" validate:"omitempty,oneof=en-MY ms-MY"`
+	CurrencyDisplay *string `json:"currency_display,omitempty" validate:"omitempty,oneof=symbol code"`
+	LogoBase64      *string `json:"logo_base64,omitempty"`
+	RemoveLogo      bool    `json:"remove_logo,omitempty"`
+}
+
+// ReportBrandingResponse represents a tenant's report branding.
+type ReportBrandingResponse struct {
+	CompanyName     string     `json:"company_name"`
+	HasLogo         bool       `json:"has_logo"`
+	Locale          string     `json:"locale"`
+	CurrencyDisplay string     `json:"currency_display"`
+	UpdatedAt       *time.Time `json:"updated_at,omitempty"`
+}
@@ -0,0 +1,69 @@
+package dto
+
+import (
+	"time"
+)
+
+// ============================================================================
+// Data Retention Request DTOs
+// ============================================================================
+
+// SetRetentionPolicyRequest represents a request to set the retention policy
+// of an entity type.
+type SetRetentionPolicyRequest struct {
+	Action    string `json:"action" validate:"required,oneof=archive purge"`
+	AfterDays int    `json:"after_days" validate:"required,min=30"`
+	Enabled   *bool  `json:"enabled,omitempty"` // defaults to true
+}
+
+// ListArchivedRecordsRequest represents a request to list archived records.
+type ListArchivedRecordsRequest struct {
+	EntityType      string `json:"entity_type,omitempty" validate:"omitempty,oneof=lead"`
+	EntityID        string `json:"entity_id,omitempty" validate:"omitempty,uuid"`
+	IncludeRestored bool   `json:"include_restored,omitempty"`
+	Page            int    `json:"page,omitempty" validate:"omitempty,min=1"`
+	PageSize        int    `json:"page_size,omitempty" validate:"omitempty,min=1,max=100"`
+}
+
+// ============================================================================
+// Data Retention Response DTOs
+// ============================================================================
+
+// RetentionPolicyResponse represents a retention policy.
+type RetentionPolicyResponse struct {
+	EntityType string     `json:"entity_type"`
+	Action     string     `json:"action"`
+	AfterDays  int        `json:"after_days"`
+	Enabled    bool       `json:"enabled"`
+	LastRunAt  *time.Time `json:"last_run_at,omitempty"`
+	UpdatedBy  *string    `json:"updated_by,omitempty"`
+	UpdatedAt  time.Time  `json:"updated_at"`
+}
+
+// RetentionPolicyListResponse represents a tenant's retention policies.
+type RetentionPolicyListResponse struct {
+	Policies []*RetentionPolicyResponse `json:"policies"`
+}
+
+// ArchivedRecordResponse represents a record moved to cold storage.
+type ArchivedRecordResponse struct {
+	ID         string     `json:"id"`
+	EntityType string     `json:"entity_type"`
+	EntityID   string     `json:"entity_id"`
+	ArchivedAt time.Time  `json:"archived_at"`
+	RestoredAt *time.Time `json:"restored_at,omitempty"`
+	RestoredBy *string    `json:"restored_by,omitempty"`
+}
+
+// ArchivedRecordListResponse represents a paginated list of archived records.
+type ArchivedRecordListResponse struct {
+	Records    []*ArchivedRecordResponse `json:"records"`
+	Pagination PaginationResponse        `json:"pagination"`
+}
+
+// RetentionRunResponse represents the outcome of applying retention policies.
+type RetentionRunResponse struct {
+	PoliciesApplied int   `json:"policies_applied"`
+	Archived        int64 `json:"archived"`
+	Purged          int64 `json:"purged"`
+}
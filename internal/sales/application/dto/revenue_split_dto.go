@@ -0,0 +1,68 @@
+package dto
+
+import (
+	"time"
+)
+
+// ============================================================================
+// Revenue Split Request DTOs
+// ============================================================================
+
+// SetRevenueSplitsRequest represents a request to share the revenue of an
+// opportunity among users. Percents are whole numbers totalling 100.
+type SetRevenueSplitsRequest struct {
+	Splits []RevenueSplitInput `json:"splits" validate:"required,min=1,max=10,dive"`
+	Reason string              `json:"reason,omitempty" validate:"max=500"`
+}
+
+// RevenueSplitInput represents the share of revenue credited to a user.
+type RevenueSplitInput struct {
+	UserID  string `json:"user_id" validate:"required,uuid"`
+	Percent int    `json:"percent" validate:"required,min=1,max=100"`
+}
+
+// ListRevenueSplitHistoryRequest represents a request to list the changes of
+// an opportunity's revenue splits.
+type ListRevenueSplitHistoryRequest struct {
+	Page     int `json:"page,omitempty" validate:"omitempty,min=1"`
+	PageSize int `json:"page_size,omitempty" validate:"omitempty,min=1,max=100"`
+}
+
+// ============================================================================
+// Revenue Split Response DTOs
+// ============================================================================
+
+// RevenueSplitsResponse represents how the revenue of an opportunity is
+// credited. An opportunity without splits credits its owner in full.
+type RevenueSplitsResponse struct {
+	OpportunityID string                  `json:"opportunity_id"`
+	Amount        MoneyDTO                `json:"amount"`
+	IsSplit       bool                    `json:"is_split"`
+	Splits        []*RevenueSplitResponse `json:"splits"`
+}
+
+// RevenueSplitResponse represents the share of revenue credited to a user.
+type RevenueSplitResponse struct {
+	UserID   string   `json:"user_id"`
+	UserName string   `json:"user_name,omitempty"`
+	Percent  int      `json:"percent"`
+	Credit   MoneyDTO `json:"credit"`
+}
+
+// RevenueSplitChangeResponse represents one change of an opportunity's
+// revenue splits. Empty splits mean they were cleared.
+type RevenueSplitChangeResponse struct {
+	ID             string                  `json:"id"`
+	Splits         []*RevenueSplitResponse `json:"splits"`
+	PreviousSplits []*RevenueSplitResponse `json:"previous_splits"`
+	Reason         string                  `json:"reason,omitempty"`
+	ChangedBy      string                  `json:"changed_by"`
+	ChangedAt      time.Time               `json:"changed_at"`
+}
+
+// RevenueSplitHistoryResponse represents a page of an opportunity's revenue
+// split changes, newest first.
+type RevenueSplitHistoryResponse struct {
+	Changes    []*RevenueSplitChangeResponse `json:"changes"`
+	Pagination PaginationResponse            `json:"pagination"`
+}
@@ -0,0 +1,87 @@
+package dto
+
+import (
+	"time"
+)
+
+// ============================================================================
+// Shipment Request DTOs
+// ============================================================================
+
+// CreateShipmentRequest represents a request to record a shipment of a deal.
+// Without a recipient email, the customer's email is notified when known.
+type CreateShipmentRequest struct {
+	Carrier           string `json:"carrier" validate:"required,max=50"`
+	TrackingNumber    string `json:"tracking_number" validate:"required,max=100"`
+	TrackingURL       string `json:"tracking_url,omitempty" validate:"omitempty,url,max=2000"`
+	EstimatedDelivery string `json:"estimated_delivery,omitempty" validate:"omitempty,datetime=2006-01-02"`
+	RecipientName     string `json:"recipient_name,omitempty" validate:"omitempty,max=255"`
+	RecipientEmail    string `json:"recipient_email,omitempty" validate:"omitempty,email,max=255"`
+}
+
+// UpdateShipmentRequest represents a request to update a shipment's carrier
+// details. An empty estimated delivery date clears it.
+type UpdateShipmentRequest struct {
+	Carrier           *string `json:"carrier,omitempty" validate:"omitempty,max=50"`
+	TrackingNumber    *string `json:"tracking_number,omitempty" validate:"omitempty,max=100"`
+	TrackingURL       *string `json:"tracking_url,omitempty" validate:"omitempty,max=2000"`
+	EstimatedDelivery *string `json:"estimated_delivery,omitempty" validate:"omitempty,datetime=2006-01-02"`
+	RecipientName     *string `json:"recipient_name,omitempty" validate:"omitempty,max=255"`
+	RecipientEmail    *string `json:"recipient_email,omitempty" validate:"omitempty,max=255"`
+}
+
+// RecordShipmentTrackingRequest represents a tracking update entered by hand.
+type RecordShipmentTrackingRequest struct {
+	Status      string     `json:"status" validate:"required,oneof=pending in_transit out_for_delivery delivered exception returned"`
+	Description string     `json:"description,omitempty" validate:"omitempty,max=500"`
+	Location    string     `json:"location,omitempty" validate:"omitempty,max=255"`
+	OccurredAt  *time.Time `json:"occurred_at,omitempty"`
+}
+
+// ============================================================================
+// Shipment Response DTOs
+// ============================================================================
+
+// ShipmentResponse represents a shipment of a deal.
+type ShipmentResponse struct {
+	ID                string                           `json:"id"`
+	DealID            string                           `json:"deal_id"`
+	DealCode          string                           `json:"deal_code"`
+	CustomerID        string                           `json:"customer_id"`
+	CustomerName      string                           `json:"customer_name"`
+	Carrier           string                           `json:"carrier"`
+	TrackingNumber    string                           `json:"tracking_number"`
+	TrackingURL       string                           `json:"tracking_url,omitempty"`
+	Status            string                           `json:"status"`
+	RecipientName     string                           `json:"recipient_name,omitempty"`
+	RecipientEmail    string                           `json:"recipient_email,omitempty"`
+	EstimatedDelivery *time.Time                       `json:"estimated_delivery,omitempty"`
+	TrackingEvents    []*ShipmentTrackingEventResponse `json:"tracking_events"`
+	ShippedAt         *time.Time                       `json:"shipped_at,omitempty"`
+	DeliveredAt       *time.Time                       `json:"delivered_at,omitempty"`
+	LastCheckedAt     *time.Time                       `json:"last_checked_at,omitempty"`
+	CreatedBy         string                           `json:"created_by"`
+	CreatedAt         time.Time                        `json:"created_at"`
+	UpdatedAt         time.Time                        `json:"updated_at"`
+	Version           int                              `json:"version"`
+}
+
+// ShipmentTrackingEventResponse represents a checkpoint in a shipment's progress.
+type ShipmentTrackingEventResponse struct {
+	Status      string    `json:"status"`
+	Description string    `json:"description,omitempty"`
+	Location    string    `json:"location,omitempty"`
+	OccurredAt  time.Time `json:"occurred_at"`
+	Source      string    `json:"source"`
+}
+
+// ShipmentListResponse represents a deal's shipments.
+type ShipmentListResponse struct {
+	Shipments []*ShipmentResponse `json:"shipments"`
+}
+
+// CarrierWebhookResponse reports what a carrier webhook changed.
+type CarrierWebhookResponse struct {
+	Received int `json:"received"`
+	Updated  int `json:"updated"`
+}
@@ -0,0 +1,70 @@
+package dto
+
+import (
+	"time"
+)
+
+// ============================================================================
+// Tax Request DTOs
+// ============================================================================
+
+// TaxRateDTO represents a configured tax rate.
+type TaxRateDTO struct {
+	Code      string  `json:"code" validate:"required,max=20"`
+	Name      string  `json:"name" validate:"required,max=100"`
+	Type      string  `json:"type" validate:"required,oneof=sales_tax service_tax gst exempt"`
+	Rate      float64 `json:"rate" validate:"min=0,max=100"` // Percentage
+	IsDefault bool    `json:"is_default"`
+}
+
+// UpdateTaxSettingsRequest represents a request to update tax settings.
+type UpdateTaxSettingsRequest struct {
+	Enabled            *bool         `json:"enabled,omitempty"`
+	RegistrationNumber *string       `json:"registration_number,omitempty" validate:"omitempty,max=50"`
+	PricesIncludeTax   *bool         `json:"prices_include_tax,omitempty"`
+	Rates              *[]TaxRateDTO `json:"rates,omitempty" validate:"omitempty,dive"`
+}
+
+// ============================================================================
+// Tax Response DTOs
+// ============================================================================
+
+// TaxSettingsResponse represents a tenant's tax settings.
+type TaxSettingsResponse struct {
+	Enabled            bool         `json:"enabled"`
+	RegistrationNumber string       `json:"registration_number,omitempty"`
+	PricesIncludeTax   bool         `json:"prices_include_tax"`
+	Rates              []TaxRateDTO `json:"rates"`
+	UpdatedAt          *time.Time   `json:"updated_at,omitempty"`
+}
+
+// TaxLineDTO represents the tax charged at one rate.
+type TaxLineDTO struct {
+	Code          string   `json:"code,omitempty"`
+	Name          string   `json:"name"`
+	Rate          float64  `json:"rate"` // Percentage
+	TaxableAmount MoneyDTO `json:"taxable_amount"`
+	TaxAmount     MoneyDTO `json:"tax_amount"`
+}
+
+// TaxSummaryDTO represents the tax breakdown of a quote or invoice.
+type TaxSummaryDTO struct {
+	Subtotal MoneyDTO     `json:"subtotal"` // Net of tax
+	TotalTax MoneyDTO     `json:"total_tax"`
+	Total    MoneyDTO     `json:"total"`
+	Lines    []TaxLineDTO `json:"lines"`
+}
+
+// QuoteResponse represents a priced quote for an opportunity.
+type QuoteResponse struct {
+	OpportunityID      string                           `json:"opportunity_id"`
+	OpportunityCode    string                           `json:"opportunity_code,omitempty"`
+	CustomerName       string                           `json:"customer_name"`
+	Currency           string                           `json:"currency"`
+	RegistrationNumber string                           `json:"registration_number,omitempty"`
+	PricesIncludeTax   bool                             `json:"prices_include_tax"`
+	Products           []*OpportunityProductResponseDTO `json:"products"`
+	Tax                TaxSummaryDTO                    `json:"tax"`
+	QuoteDate          time.Time                        `json:"quote_date"`
+	ValidUntil         time.Time                        `json:"valid_until"`
+}
@@ -2,6 +2,7 @@ package application
 
 import (
 	"fmt"
+	"strings"
 )
 
 // ============================================================================
@@ -36,6 +37,7 @@ const (
 	ErrCodeLeadAssignmentFailed      ErrorCode = "LEAD_ASSIGNMENT_FAILED"
 	ErrCodeLeadConversionFailed      ErrorCode = "LEAD_CONVERSION_FAILED"
 	ErrCodeLeadScoringFailed         ErrorCode = "LEAD_SCORING_FAILED"
+	ErrCodeLeadPotentialDuplicate    ErrorCode = "LEAD_POTENTIAL_DUPLICATE"
 
 	// Opportunity errors
 	ErrCodeOpportunityNotFound            ErrorCode = "OPPORTUNITY_NOT_FOUND"
@@ -53,6 +55,10 @@ const (
 	ErrCodeOpportunityAssignmentFailed    ErrorCode = "OPPORTUNITY_ASSIGNMENT_FAILED"
 	ErrCodeOpportunityWinFailed           ErrorCode = "OPPORTUNITY_WIN_FAILED"
 	ErrCodeOpportunityLoseFailed          ErrorCode = "OPPORTUNITY_LOSE_FAILED"
+	ErrCodeOpportunityReasonNotFound      ErrorCode = "OPPORTUNITY_REASON_NOT_FOUND"
+	ErrCodeOpportunityReasonAlreadyExists ErrorCode = "OPPORTUNITY_REASON_ALREADY_EXISTS"
+	ErrCodeOpportunityReasonInvalid       ErrorCode = "OPPORTUNITY_REASON_INVALID"
+	ErrCodeOpportunityReasonRequired      ErrorCode = "OPPORTUNITY_REASON_REQUIRED"
 
 	// Deal errors
 	ErrCodeDealNotFound              ErrorCode = "DEAL_NOT_FOUND"
@@ -69,6 +75,7 @@ const (
 	ErrCodeDealInvoiceAlreadyPaid    ErrorCode = "DEAL_INVOICE_ALREADY_PAID"
 	ErrCodeDealCannotCancel          ErrorCode = "DEAL_CANNOT_CANCEL"
 	ErrCodeDealNumberGeneration      ErrorCode = "DEAL_NUMBER_GENERATION_FAILED"
+	ErrCodeDealNotRecurring          ErrorCode = "DEAL_NOT_RECURRING"
 
 	// Pipeline errors
 	ErrCodePipelineNotFound          ErrorCode = "PIPELINE_NOT_FOUND"
@@ -86,6 +93,9 @@ const (
 	ErrCodePipelineMinStagesRequired ErrorCode = "PIPELINE_MIN_STAGES_REQUIRED"
 	ErrCodePipelineWonStageRequired  ErrorCode = "PIPELINE_WON_STAGE_REQUIRED"
 	ErrCodePipelineLostStageRequired ErrorCode = "PIPELINE_LOST_STAGE_REQUIRED"
+	ErrCodePipelineVersionNotFound   ErrorCode = "PIPELINE_VERSION_NOT_FOUND"
+	ErrCodePipelineStageWIPLimitReached     ErrorCode = "PIPELINE_STAGE_WIP_LIMIT_REACHED"
+	ErrCodePipelineStageEntryCriteriaNotMet ErrorCode = "PIPELINE_STAGE_ENTRY_CRITERIA_NOT_MET"
 
 	// Customer/Contact errors
 	ErrCodeCustomerNotFound          ErrorCode = "CUSTOMER_NOT_FOUND"
@@ -103,6 +113,57 @@ const (
 	// Currency errors
 	ErrCodeCurrencyMismatch          ErrorCode = "CURRENCY_MISMATCH"
 	ErrCodeCurrencyInvalid           ErrorCode = "CURRENCY_INVALID"
+	ErrCodeExchangeRateNotFound      ErrorCode = "EXCHANGE_RATE_NOT_FOUND"
+
+	// Tax errors
+	ErrCodeTaxRateNotFound           ErrorCode = "TAX_RATE_NOT_FOUND"
+
+	// Attachment errors
+	ErrCodeAttachmentNotFound        ErrorCode = "ATTACHMENT_NOT_FOUND"
+	ErrCodeAttachmentQuarantined     ErrorCode = "ATTACHMENT_QUARANTINED"
+
+	// Inbound email errors
+	ErrCodeInboundMailboxNotFound    ErrorCode = "INBOUND_MAILBOX_NOT_FOUND"
+
+	// Data retention errors
+	ErrCodeRetentionPolicyNotFound   ErrorCode = "RETENTION_POLICY_NOT_FOUND"
+	ErrCodeArchivedRecordNotFound    ErrorCode = "ARCHIVED_RECORD_NOT_FOUND"
+	ErrCodeArchivedRecordRestored    ErrorCode = "ARCHIVED_RECORD_RESTORED"
+
+	// Discount approval errors
+	ErrCodeDiscountApprovalRuleNotFound ErrorCode = "DISCOUNT_APPROVAL_RULE_NOT_FOUND"
+	ErrCodeDiscountApprovalNotFound     ErrorCode = "DISCOUNT_APPROVAL_NOT_FOUND"
+	ErrCodeDiscountApprovalNotRequired  ErrorCode = "DISCOUNT_APPROVAL_NOT_REQUIRED"
+	ErrCodeDiscountApprovalDecided      ErrorCode = "DISCOUNT_APPROVAL_ALREADY_DECIDED"
+	ErrCodeDiscountApprovalRequired     ErrorCode = "DISCOUNT_APPROVAL_REQUIRED"
+
+	// Comment errors
+	ErrCodeCommentNotFound           ErrorCode = "COMMENT_NOT_FOUND"
+	ErrCodeCommentDeleted            ErrorCode = "COMMENT_DELETED"
+
+	// Order errors
+	ErrCodeOrderNotFound             ErrorCode = "ORDER_NOT_FOUND"
+	ErrCodeOrderAlreadyExists        ErrorCode = "ORDER_ALREADY_EXISTS"
+	ErrCodeOrderInvalidTransition    ErrorCode = "ORDER_INVALID_STATUS_TRANSITION"
+	ErrCodeOrderWebhookNotFound      ErrorCode = "ORDER_WEBHOOK_NOT_FOUND"
+
+	// Shipment errors
+	ErrCodeShipmentNotFound          ErrorCode = "SHIPMENT_NOT_FOUND"
+	ErrCodeShipmentInvalidTransition ErrorCode = "SHIPMENT_INVALID_STATUS_TRANSITION"
+	ErrCodeCarrierNotSupported       ErrorCode = "CARRIER_NOT_SUPPORTED"
+
+	// E-invoice errors
+	ErrCodeEInvoiceNotFound          ErrorCode = "EINVOICE_NOT_FOUND"
+	ErrCodeEInvoiceAlreadyExists     ErrorCode = "EINVOICE_ALREADY_EXISTS"
+	ErrCodeEInvoiceInvalidTransition ErrorCode = "EINVOICE_INVALID_STATUS_TRANSITION"
+	ErrCodeEInvoiceNotEnabled        ErrorCode = "EINVOICE_NOT_ENABLED"
+
+	// Accounting errors
+	ErrCodeAccountingNotConnected    ErrorCode = "ACCOUNTING_NOT_CONNECTED"
+	ErrCodeAccountingSyncNotFound    ErrorCode = "ACCOUNTING_SYNC_NOT_FOUND"
+
+	// E-commerce errors
+	ErrCodeEcommerceStoreNotFound    ErrorCode = "ECOMMERCE_STORE_NOT_FOUND"
 
 	// Version/Concurrency errors
 	ErrCodeVersionMismatch           ErrorCode = "VERSION_MISMATCH"
@@ -259,6 +320,10 @@ func ErrLeadDuplicateEmail(email string) *AppError {
 	return NewAppErrorf(ErrCodeLeadDuplicateEmail, "lead with email already exists: %s", email)
 }
 
+func ErrLeadPotentialDuplicate(matches int) *AppError {
+	return NewAppErrorf(ErrCodeLeadPotentialDuplicate, "lead matches %d existing leads or customers; create it with force=true to keep it anyway", matches)
+}
+
 func ErrLeadConversionFailed(id interface{}, reason string) *AppError {
 	return NewAppErrorf(ErrCodeLeadConversionFailed, "failed to convert lead %v: %s", id, reason)
 }
@@ -300,6 +365,23 @@ func ErrOpportunityContactDuplicate(opportunityID, contactID interface{}) *AppEr
 	return NewAppErrorf(ErrCodeOpportunityContactDuplicate, "contact %v already exists in opportunity %v", contactID, opportunityID)
 }
 
+func ErrOpportunityReasonNotFound(id interface{}) *AppError {
+	return NewAppErrorf(ErrCodeOpportunityReasonNotFound, "opportunity reason not found: %v", id)
+}
+
+func ErrOpportunityReasonAlreadyExists(reasonType, name string) *AppError {
+	return NewAppErrorf(ErrCodeOpportunityReasonAlreadyExists, "%s reason %q already exists", reasonType, name)
+}
+
+func ErrOpportunityReasonInvalid(id interface{}, reasonType string) *AppError {
+	return NewAppErrorf(ErrCodeOpportunityReasonInvalid, "reason %v is not an active %s reason", id, reasonType)
+}
+
+func ErrOpportunityReasonRequired(reasonType string) *AppError {
+	return NewAppErrorf(ErrCodeOpportunityReasonRequired, "a %s reason must be selected from the reason catalog", reasonType).
+		WithDetail("reason_type", reasonType)
+}
+
 // Deal errors
 func ErrDealNotFound(id interface{}) *AppError {
 	return NewAppErrorf(ErrCodeDealNotFound, "deal not found: %v", id)
@@ -317,10 +399,18 @@ func ErrDealInvalidStatusTransition(from, to string) *AppError {
 	return NewAppErrorf(ErrCodeDealInvalidTransition, "invalid deal status transition from %s to %s", from, to)
 }
 
+func ErrDealNotRecurring(id interface{}) *AppError {
+	return NewAppErrorf(ErrCodeDealNotRecurring, "deal is not recurring: %v", id)
+}
+
 func ErrDealLineItemNotFound(dealID, lineItemID interface{}) *AppError {
 	return NewAppErrorf(ErrCodeDealLineItemNotFound, "line item %v not found in deal %v", lineItemID, dealID)
 }
 
+func ErrDealInvoiceNotFound(dealID, invoiceID interface{}) *AppError {
+	return NewAppErrorf(ErrCodeDealInvoiceNotFound, "invoice %v not found in deal %v", invoiceID, dealID)
+}
+
 func ErrDealPaymentExceedsBalance(amount, balance int64) *AppError {
 	return NewAppErrorf(ErrCodeDealPaymentExceedsBalance, "payment amount %d exceeds remaining balance %d", amount, balance)
 }
@@ -358,8 +448,29 @@ func ErrPipelineStageInactive(stageID interface{}) *AppError {
 	return NewAppErrorf(ErrCodePipelineStageInactive, "stage is inactive: %v", stageID)
 }
 
-func ErrPipelineStageHasOpportunities(stageID interface{}) *AppError {
-	return NewAppErrorf(ErrCodePipelineStageHasOpportunities, "cannot delete stage %v: has associated opportunities", stageID)
+func ErrPipelineStageHasOpportunities(stageID interface{}, openCount int64) *AppError {
+	return NewAppErrorf(ErrCodePipelineStageHasOpportunities,
+		"stage %v has %d open opportunities; migrate them to another stage before removing or deactivating it", stageID, openCount).
+		WithDetail("stage_id", fmt.Sprint(stageID)).
+		WithDetail("open_opportunities", openCount)
+}
+
+func ErrPipelineStageWIPLimitReached(stageID interface{}, stageName string, limit int) *AppError {
+	return NewAppErrorf(ErrCodePipelineStageWIPLimitReached,
+		"stage %s has reached its WIP limit of %d open opportunities; move an opportunity out or ask a manager to override the limit", stageName, limit).
+		WithDetail("stage_id", fmt.Sprint(stageID)).
+		WithDetail("wip_limit", limit)
+}
+
+func ErrPipelineStageEntryCriteriaNotMet(stageID interface{}, stageName string, unmet []string) *AppError {
+	return NewAppErrorf(ErrCodePipelineStageEntryCriteriaNotMet,
+		"opportunity does not meet the entry criteria of stage %s: %s", stageName, strings.Join(unmet, "; ")).
+		WithDetail("stage_id", fmt.Sprint(stageID)).
+		WithDetail("unmet_criteria", unmet)
+}
+
+func ErrPipelineVersionNotFound(pipelineID interface{}, version int) *AppError {
+	return NewAppErrorf(ErrCodePipelineVersionNotFound, "version %d of pipeline %v not found", version, pipelineID)
 }
 
 func ErrPipelineMinStagesRequired(min int) *AppError {
@@ -383,6 +494,150 @@ func ErrCurrencyInvalid(currency string) *AppError {
 	return NewAppErrorf(ErrCodeCurrencyInvalid, "invalid currency: %s", currency)
 }
 
+func ErrExchangeRateNotFound(from, to string) *AppError {
+	return NewAppErrorf(ErrCodeExchangeRateNotFound, "no exchange rate from %s to %s", from, to)
+}
+
+// Tax errors
+func ErrTaxRateNotFound(code string) *AppError {
+	return NewAppErrorf(ErrCodeTaxRateNotFound, "tax code %s is not configured", code)
+}
+
+// Attachment errors
+func ErrAttachmentNotFound(name string) *AppError {
+	return NewAppErrorf(ErrCodeAttachmentNotFound, "attachment not found: %s", name)
+}
+
+// ErrAttachmentQuarantined is returned when an attachment is downloaded
+// before it has been scanned clean.
+func ErrAttachmentQuarantined(name string) *AppError {
+	return NewAppErrorf(ErrCodeAttachmentQuarantined, "attachment %s is quarantined until it has been scanned", name)
+}
+
+// Inbound email errors
+func ErrInboundMailboxNotFound(address string) *AppError {
+	return NewAppErrorf(ErrCodeInboundMailboxNotFound, "no enabled inbound mailbox for %s", address)
+}
+
+// Data retention errors
+func ErrRetentionPolicyNotFound(entityType string) *AppError {
+	return NewAppErrorf(ErrCodeRetentionPolicyNotFound, "no retention policy for %s", entityType)
+}
+
+func ErrArchivedRecordNotFound(id interface{}) *AppError {
+	return NewAppErrorf(ErrCodeArchivedRecordNotFound, "archived record not found: %v", id)
+}
+
+func ErrArchivedRecordRestored(id interface{}) *AppError {
+	return NewAppErrorf(ErrCodeArchivedRecordRestored, "archived record already restored: %v", id)
+}
+
+// Discount approval errors
+func ErrDiscountApprovalRuleNotFound(id interface{}) *AppError {
+	return NewAppErrorf(ErrCodeDiscountApprovalRuleNotFound, "discount approval rule not found: %v", id)
+}
+
+func ErrDiscountApprovalNotFound(id interface{}) *AppError {
+	return NewAppErrorf(ErrCodeDiscountApprovalNotFound, "discount approval not found: %v", id)
+}
+
+func ErrDiscountApprovalNotRequired(opportunityID interface{}) *AppError {
+	return NewAppErrorf(ErrCodeDiscountApprovalNotRequired, "the discount on opportunity %v does not need approval", opportunityID)
+}
+
+func ErrDiscountApprovalDecided(id interface{}, status string) *AppError {
+	return NewAppErrorf(ErrCodeDiscountApprovalDecided, "discount approval %v is already %s", id, status)
+}
+
+// ErrDiscountApprovalRequired is returned when a quote is sent or an
+// opportunity won with a discount that has not been approved. status is the
+// status of the opportunity's latest approval, or empty if none was requested.
+func ErrDiscountApprovalRequired(opportunityID interface{}, status string) *AppError {
+	err := NewAppErrorf(ErrCodeDiscountApprovalRequired, "the discount on opportunity %v needs manager approval", opportunityID)
+	if status != "" {
+		err = err.WithDetail("approval_status", status)
+	}
+	return err
+}
+
+// Comment errors
+func ErrCommentNotFound(id interface{}) *AppError {
+	return NewAppErrorf(ErrCodeCommentNotFound, "comment not found: %v", id)
+}
+
+func ErrCommentDeleted(id interface{}) *AppError {
+	return NewAppErrorf(ErrCodeCommentDeleted, "comment has been deleted: %v", id)
+}
+
+// Order errors
+func ErrOrderNotFound(id interface{}) *AppError {
+	return NewAppErrorf(ErrCodeOrderNotFound, "order not found: %v", id)
+}
+
+func ErrOrderAlreadyExists(dealID interface{}) *AppError {
+	return NewAppErrorf(ErrCodeOrderAlreadyExists, "deal %v already has an open order", dealID)
+}
+
+func ErrOrderInvalidStatusTransition(from, to string) *AppError {
+	return NewAppErrorf(ErrCodeOrderInvalidTransition, "invalid order status transition from %s to %s", from, to)
+}
+
+func ErrOrderClosed(id interface{}, status string) *AppError {
+	return NewAppErrorf(ErrCodeOrderInvalidTransition, "order %v is %s and can no longer change", id, status)
+}
+
+func ErrOrderWebhookNotFound() *AppError {
+	return NewAppError(ErrCodeOrderWebhookNotFound, "no order webhook is configured")
+}
+
+// Shipment errors
+func ErrShipmentNotFound(id interface{}) *AppError {
+	return NewAppErrorf(ErrCodeShipmentNotFound, "shipment not found: %v", id)
+}
+
+func ErrShipmentInvalidStatusTransition(from, to string) *AppError {
+	return NewAppErrorf(ErrCodeShipmentInvalidTransition, "invalid shipment status transition from %s to %s", from, to)
+}
+
+func ErrShipmentClosed(id interface{}, status string) *AppError {
+	return NewAppErrorf(ErrCodeShipmentInvalidTransition, "shipment %v is %s and can no longer change", id, status)
+}
+
+func ErrCarrierNotSupported(carrier string) *AppError {
+	return NewAppErrorf(ErrCodeCarrierNotSupported, "carrier %s does not send tracking updates", carrier)
+}
+
+// E-invoice errors
+func ErrEInvoiceNotFound(invoiceID interface{}) *AppError {
+	return NewAppErrorf(ErrCodeEInvoiceNotFound, "invoice %v has no e-invoice", invoiceID)
+}
+
+func ErrEInvoiceAlreadyExists(invoiceID interface{}) *AppError {
+	return NewAppErrorf(ErrCodeEInvoiceAlreadyExists, "invoice %v already has an e-invoice", invoiceID)
+}
+
+func ErrEInvoiceInvalidState(invoiceID interface{}, status, action string) *AppError {
+	return NewAppErrorf(ErrCodeEInvoiceInvalidTransition, "e-invoice of invoice %v is %s and cannot be %s", invoiceID, status, action)
+}
+
+func ErrEInvoiceNotEnabled() *AppError {
+	return NewAppError(ErrCodeEInvoiceNotEnabled, "e-invoicing is not enabled; complete the e-invoice settings first")
+}
+
+// Accounting errors
+func ErrAccountingNotConnected() *AppError {
+	return NewAppError(ErrCodeAccountingNotConnected, "no accounting system is connected")
+}
+
+func ErrAccountingSyncNotFound(id interface{}) *AppError {
+	return NewAppErrorf(ErrCodeAccountingSyncNotFound, "accounting sync record %v not found", id)
+}
+
+// E-commerce errors
+func ErrEcommerceStoreNotFound(id interface{}) *AppError {
+	return NewAppErrorf(ErrCodeEcommerceStoreNotFound, "e-commerce store %v not found", id)
+}
+
 // Version errors
 func ErrVersionMismatch(expected, actual int) *AppError {
 	return NewAppErrorf(ErrCodeVersionMismatch, "version mismatch: expected %d, got %d", expected, actual)
@@ -445,6 +700,7 @@ func IsNotFoundError(err error) bool {
 			ErrCodeDealNotFound,
 			ErrCodePipelineNotFound,
 			ErrCodePipelineStageNotFound,
+			ErrCodePipelineVersionNotFound,
 			ErrCodeCustomerNotFound,
 			ErrCodeContactNotFound,
 			ErrCodeProductNotFound,
@@ -453,7 +709,20 @@ func IsNotFoundError(err error) bool {
 			ErrCodeDealInvoiceNotFound,
 			ErrCodeDealPaymentNotFound,
 			ErrCodeOpportunityProductNotFound,
-			ErrCodeOpportunityContactNotFound:
+			ErrCodeOpportunityContactNotFound,
+			ErrCodeOpportunityReasonNotFound,
+			ErrCodeAttachmentNotFound,
+			ErrCodeInboundMailboxNotFound,
+			ErrCodeRetentionPolicyNotFound,
+			ErrCodeArchivedRecordNotFound,
+			ErrCodeOrderNotFound,
+			ErrCodeOrderWebhookNotFound,
+			ErrCodeShipmentNotFound,
+			ErrCodeCarrierNotSupported,
+			ErrCodeEInvoiceNotFound,
+			ErrCodeAccountingNotConnected,
+			ErrCodeAccountingSyncNotFound,
+			ErrCodeEcommerceStoreNotFound:
 			return true
 		}
 	}
@@ -479,12 +748,16 @@ func IsConflictError(err error) bool {
 			ErrCodeLeadAlreadyExists,
 			ErrCodeLeadAlreadyConverted,
 			ErrCodeLeadDuplicateEmail,
+			ErrCodeLeadPotentialDuplicate,
 			ErrCodeOpportunityAlreadyExists,
 			ErrCodeOpportunityContactDuplicate,
 			ErrCodeOpportunityProductDuplicate,
 			ErrCodeDealAlreadyExists,
 			ErrCodePipelineAlreadyExists,
-			ErrCodePipelineStageDuplicate:
+			ErrCodePipelineStageDuplicate,
+			ErrCodeArchivedRecordRestored,
+			ErrCodeAttachmentQuarantined,
+			ErrCodeOrderAlreadyExists:
 			return true
 		}
 	}
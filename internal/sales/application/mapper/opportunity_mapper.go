@@ -2,6 +2,7 @@
 package mapper
 
 import (
+	"errors"
 	"time"
 
 	"github.com/kilang-desa-murni/crm/internal/sales/application/dto"
@@ -31,13 +32,15 @@ func (m *OpportunityMapper) ToResponse(opp *domain.Opportunity) *dto.Opportunity
 	}
 
 	response := &dto.OpportunityResponse{
-		ID:          opp.ID.String(),
-		TenantID:    opp.TenantID.String(),
-		Name:        opp.Name,
-		Status:      string(opp.Status),
-		PipelineID:  opp.PipelineID.String(),
-		StageID:     opp.StageID.String(),
-		Probability: opp.Probability,
+		ID:                  opp.ID.String(),
+		TenantID:            opp.TenantID.String(),
+		Name:                opp.Name,
+		Status:              string(opp.Status),
+		PipelineID:          opp.PipelineID.String(),
+		StageID:             opp.StageID.String(),
+		Probability:         opp.Probability,
+		ProbabilityOverride: opp.ProbabilityOverride,
+		ForecastCategory:    string(opp.EffectiveForecastCategory()),
 		Amount: dto.MoneyDTO{
 			Amount:   opp.Amount.Amount,
 			Currency: opp.Amount.Currency,
@@ -82,8 +85,9 @@ func (m *OpportunityMapper) ToResponse(opp *domain.Opportunity) *dto.Opportunity
 	customerIDStr := opp.CustomerID.String()
 	response.CustomerID = &customerIDStr
 	response.Customer = &dto.CustomerBriefDTO{
-		ID:   opp.CustomerID.String(),
-		Name: opp.CustomerName,
+		ID:      opp.CustomerID.String(),
+		Name:    opp.CustomerName,
+		Segment: opp.CustomerSegment,
 	}
 
 	// Lead
@@ -189,16 +193,17 @@ func (m *OpportunityMapper) ToBriefResponse(opp *domain.Opportunity) *dto.Opport
 	}
 
 	response := &dto.OpportunityBriefResponse{
-		ID:          opp.ID.String(),
-		Name:        opp.Name,
-		Status:      string(opp.Status),
-		Probability: opp.Probability,
-		StageID:     opp.StageID.String(),
-		StageName:   opp.StageName,
-		OwnerID:     opp.OwnerID.String(),
-		OwnerName:   opp.OwnerName,
-		DaysOpen:    opp.DaysInPipeline(),
-		CreatedAt:   opp.CreatedAt,
+		ID:               opp.ID.String(),
+		Name:             opp.Name,
+		Status:           string(opp.Status),
+		Probability:      opp.Probability,
+		ForecastCategory: string(opp.EffectiveForecastCategory()),
+		StageID:          opp.StageID.String(),
+		StageName:        opp.StageName,
+		OwnerID:          opp.OwnerID.String(),
+		OwnerName:        opp.OwnerName,
+		DaysOpen:         opp.DaysInPipeline(),
+		CreatedAt:        opp.CreatedAt,
 		Amount: dto.MoneyDTO{
 			Amount:   opp.Amount.Amount,
 			Currency: opp.Amount.Currency,
@@ -289,6 +294,7 @@ func (m *OpportunityMapper) mapContacts(contacts []domain.OpportunityContact) []
 			ContactID: c.ContactID.String(),
 			Role:      c.Role,
 			IsPrimary: c.IsPrimary,
+			AddedAt:   c.AddedAt,
 			Contact: &dto.ContactBriefDTO{
 				ID:       c.ContactID.String(),
 				FullName: c.Name,
@@ -299,6 +305,9 @@ func (m *OpportunityMapper) mapContacts(contacts []domain.OpportunityContact) []
 		if c.Phone != "" {
 			contactDTO.Contact.Phone = dto.StringPtr(c.Phone)
 		}
+		if c.Notes != "" {
+			contactDTO.Notes = dto.StringPtr(c.Notes)
+		}
 
 		result = append(result, contactDTO)
 	}
@@ -321,6 +330,7 @@ func (m *OpportunityMapper) mapProducts(products []domain.OpportunityProduct) []
 			ID:          p.ID.String(),
 			ProductID:   p.ProductID.String(),
 			ProductName: p.ProductName,
+			Category:    p.Category,
 			Quantity:    p.Quantity,
 			UnitPrice: dto.MoneyDTO{
 				Amount:   p.UnitPrice.Amount,
@@ -373,7 +383,11 @@ func (m *OpportunityMapper) ToProduct(req *dto.AddProductRequest) (domain.Opport
 		return domain.OpportunityProduct{}, err
 	}
 
-	unitPrice, err := domain.NewMoney(req.UnitPrice, req.Currency)
+	// Price lists are applied by the use case; the mapper needs a price
+	if req.UnitPrice == nil {
+		return domain.OpportunityProduct{}, errors.New("unit_price is required")
+	}
+	unitPrice, err := domain.NewMoney(*req.UnitPrice, req.Currency)
 	if err != nil {
 		return domain.OpportunityProduct{}, err
 	}
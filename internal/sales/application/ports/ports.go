@@ -2,6 +2,8 @@ package ports
 
 import (
 	"context"
+	"errors"
+	"io"
 	"time"
 
 	"github.com/google/uuid"
@@ -79,6 +81,9 @@ type CustomerService interface {
 	// GetCustomer retrieves a customer by ID.
 	GetCustomer(ctx context.Context, tenantID, customerID uuid.UUID) (*CustomerInfo, error)
 
+	// GetCustomersByIDs retrieves multiple customers by IDs.
+	GetCustomersByIDs(ctx context.Context, tenantID uuid.UUID, customerIDs []uuid.UUID) ([]*CustomerInfo, error)
+
 	// GetCustomerByCode retrieves a customer by code.
 	GetCustomerByCode(ctx context.Context, tenantID uuid.UUID, code string) (*CustomerInfo, error)
 
@@ -96,6 +101,12 @@ type CustomerService interface {
 
 	// CreateContact creates a new contact for a customer.
 	CreateContact(ctx context.Context, tenantID, customerID uuid.UUID, req CreateContactRequest) (*ContactInfo, error)
+
+	// DeleteCustomer deletes a customer, undoing its creation.
+	DeleteCustomer(ctx context.Context, tenantID, customerID uuid.UUID) error
+
+	// DeleteContact deletes a contact of a customer, undoing its creation.
+	DeleteContact(ctx context.Context, tenantID, customerID, contactID uuid.UUID) error
 }
 
 // CustomerInfo represents customer information from the customer service.
@@ -109,6 +120,7 @@ type CustomerInfo struct {
 	Email       *string   `json:"email,omitempty"`
 	Phone       *string   `json:"phone,omitempty"`
 	Industry    *string   `json:"industry,omitempty"`
+	Tier        string    `json:"tier,omitempty"`
 	OwnerID     *uuid.UUID `json:"owner_id,omitempty"`
 }
 
@@ -160,6 +172,25 @@ type AddressInfo struct {
 	Country    string  `json:"country"`
 }
 
+// ============================================================================
+// Customer Matcher Port
+// ============================================================================
+
+// CustomerMatcher finds the existing customers a new lead may already be.
+type CustomerMatcher interface {
+	// FindMatchingCustomers returns the customers with the email address, one
+	// of the phone numbers or the company name.
+	FindMatchingCustomers(ctx context.Context, tenantID uuid.UUID, email string, phones []string, companyName string) ([]*CustomerMatch, error)
+}
+
+// CustomerMatch is a customer matching a new lead.
+type CustomerMatch struct {
+	CustomerInfo
+	// MatchedOn are the fields the customer matched on: email, phone or
+	// company.
+	MatchedOn []string `json:"matched_on"`
+}
+
 // ============================================================================
 // User Service Port
 // ============================================================================
@@ -422,11 +453,17 @@ type SearchHit struct {
 // File Storage Port
 // ============================================================================
 
+// ErrFileNotFound is returned by FileStorageService when a file does not exist.
+var ErrFileNotFound = errors.New("file not found")
+
 // FileStorageService defines the interface for file storage operations.
 type FileStorageService interface {
 	// Upload uploads a file.
 	Upload(ctx context.Context, req FileUploadRequest) (*FileInfo, error)
 
+	// Store stores content under a caller-chosen file ID, replacing any existing file.
+	Store(ctx context.Context, tenantID uuid.UUID, fileID string, content []byte) error
+
 	// Download downloads a file.
 	Download(ctx context.Context, tenantID uuid.UUID, fileID string) ([]byte, error)
 
@@ -466,6 +503,56 @@ type FileInfo struct {
 	UploadedBy  uuid.UUID         `json:"uploaded_by"`
 }
 
+// ============================================================================
+// Image Resizer Port
+// ============================================================================
+
+// ImageResizer generates downscaled copies of images.
+type ImageResizer interface {
+	// Resize scales an image to fit within maxDimension pixels on its longest
+	// side and returns it encoded as JPEG. Smaller images are re-encoded unscaled.
+	Resize(content []byte, maxDimension int) ([]byte, error)
+}
+
+// ============================================================================
+// Virus Scanner Port
+// ============================================================================
+
+// VirusScanner scans files for malware.
+type VirusScanner interface {
+	// Name names the scanner in scan results, e.g. "clamav".
+	Name() string
+
+	// Scan scans content and returns the name of the malware signature
+	// found, or an empty string when the content is clean.
+	Scan(ctx context.Context, content []byte) (string, error)
+}
+
+// ============================================================================
+// Email Parser Port
+// ============================================================================
+
+// EmailParser parses raw inbound email.
+type EmailParser interface {
+	// Parse parses an RFC 5322 message with its MIME parts.
+	Parse(raw []byte) (*ParsedEmail, error)
+}
+
+// ParsedEmail represents a parsed inbound email. Body is the plain text body,
+// converted from HTML when the message has no plain text part. Headers holds
+// single-valued headers under their canonical names.
+type ParsedEmail struct {
+	MessageID   string            `json:"message_id"`
+	FromAddress string            `json:"from_address"`
+	FromName    string            `json:"from_name,omitempty"`
+	Recipients  []string          `json:"recipients"`
+	Subject     string            `json:"subject"`
+	Body        string            `json:"body"`
+	Headers     map[string]string `json:"headers,omitempty"`
+	Attachments []EmailAttachment `json:"attachments,omitempty"`
+	Date        time.Time         `json:"date"`
+}
+
 // ============================================================================
 // Audit Log Port
 // ============================================================================
@@ -556,6 +643,9 @@ type WebhookRequest struct {
 	Headers   map[string]string      `json:"headers,omitempty"`
 	Payload   map[string]interface{} `json:"payload"`
 	RetryCount int                   `json:"retry_count,omitempty"`
+	// Secret signs the payload with HMAC-SHA256 when set, so receivers can
+	// verify the request came from the CRM.
+	Secret    string                 `json:"-"`
 }
 
 // ============================================================================
@@ -603,3 +693,472 @@ type MetricDataPoint struct {
 	Value      float64                `json:"value"`
 	Dimensions map[string]string      `json:"dimensions,omitempty"`
 }
+
+// ============================================================================
+// Report Renderer Port
+// ============================================================================
+
+// ReportRenderer renders a report document to a file format.
+type ReportRenderer interface {
+	// Format returns the file format produced by the renderer (e.g. xlsx, pdf).
+	Format() string
+
+	// Render writes the rendered document to w.
+	Render(ctx context.Context, w io.Writer, doc *ReportDocument) error
+}
+
+// ReportDocument is a format-independent report ready for rendering.
+// All values are pre-formatted for the report's locale.
+type ReportDocument struct {
+	Title       string              `json:"title"`
+	Subtitle    string              `json:"subtitle"`
+	Branding    ReportBrandingAsset `json:"branding"`
+	Columns     []ReportColumn      `json:"columns"`
+	Rows        [][]ReportCell      `json:"rows"`
+	Totals      []ReportCell        `json:"totals,omitempty"`
+	Details     []ReportField       `json:"details,omitempty"` // Shown above the table
+	Summary     []ReportField       `json:"summary,omitempty"` // Shown below the table
+	Chart       *ReportChart        `json:"chart,omitempty"`
+	FooterText  string              `json:"footer_text,omitempty"`
+	GeneratedAt time.Time           `json:"generated_at"`
+}
+
+// ReportBrandingAsset contains the branding applied to a rendered report.
+type ReportBrandingAsset struct {
+	CompanyName     string `json:"company_name"`
+	Logo            []byte `json:"-"`
+	LogoContentType string `json:"logo_content_type,omitempty"`
+	PrimaryColor    string `json:"primary_color,omitempty"` // #rrggbb, the renderer's default when empty
+}
+
+// ReportColumn describes a report column.
+type ReportColumn struct {
+	Header   string `json:"header"`
+	Numeric  bool   `json:"numeric"`
+	Decimals int    `json:"decimals"`
+}
+
+// ReportCell is a single report value with its display text.
+type ReportCell struct {
+	Display string   `json:"display"`
+	Value   *float64 `json:"value,omitempty"`
+}
+
+// ReportField is a labelled value, such as an invoice number or a tax total.
+type ReportField struct {
+	Label    string `json:"label"`
+	Value    string `json:"value"`
+	Emphasis bool   `json:"emphasis,omitempty"`
+}
+
+// ReportChart is a bar chart rendered alongside the report table.
+type ReportChart struct {
+	Title  string    `json:"title"`
+	Labels []string  `json:"labels"`
+	Values []float64 `json:"values"`
+}
+
+// ============================================================================
+// Exchange Rate Ports
+// ============================================================================
+
+// ExchangeRateProvider fetches published exchange rates from an external source.
+type ExchangeRateProvider interface {
+	// Source returns the rate source identifier (e.g. ecb, bnm).
+	Source() string
+
+	// FetchRates returns the latest published rates.
+	FetchRates(ctx context.Context) ([]ExchangeRateQuote, error)
+}
+
+// ExchangeRateQuote is a published rate: one From unit is worth Rate To units.
+type ExchangeRateQuote struct {
+	From          string    `json:"from"`
+	To            string    `json:"to"`
+	Rate          float64   `json:"rate"`
+	EffectiveDate time.Time `json:"effective_date"`
+}
+
+// CurrencyConverter resolves a tenant's base currency and conversion rates.
+type CurrencyConverter interface {
+	// BaseCurrency returns the tenant's base currency.
+	BaseCurrency(ctx context.Context, tenantID uuid.UUID) (string, error)
+
+	// Rate returns the rate converting one unit of from into to at the given time.
+	Rate(ctx context.Context, tenantID uuid.UUID, from, to string, at time.Time) (float64, error)
+}
+
+// ============================================================================
+// Tax Ports
+// ============================================================================
+
+// TaxRateResolver resolves the tenant tax rates applied to quoted and invoiced lines.
+type TaxRateResolver interface {
+	// ResolveTaxRate returns the rate for a tax code, or the tenant's default rate
+	// when code is empty. It returns nil when no tax applies to the tenant.
+	ResolveTaxRate(ctx context.Context, tenantID uuid.UUID, code string) (*AppliedTaxRate, error)
+}
+
+// AppliedTaxRate is a resolved tax rate and the tenant's pricing mode.
+type AppliedTaxRate struct {
+	Code      string  `json:"code"`
+	Name      string  `json:"name"`
+	Rate      float64 `json:"rate"`
+	Inclusive bool    `json:"inclusive"`
+}
+
+// PriceListResolver prices products from the tenant's price lists.
+type PriceListResolver interface {
+	// ResolvePrice returns the unit price of a product for customers of a tier
+	// in currency, or nil when no price list prices it. product, if known,
+	// carries the catalogue price a price list's discount is taken off.
+	ResolvePrice(ctx context.Context, tenantID uuid.UUID, tier string, productID uuid.UUID, currency string, product *ProductInfo) (*AppliedPrice, error)
+}
+
+// AppliedPrice is a unit price taken from a price list, in the smallest
+// currency unit.
+type AppliedPrice struct {
+	PriceList string `json:"price_list"`
+	UnitPrice int64  `json:"unit_price"`
+	Currency  string `json:"currency"`
+}
+
+// ============================================================================
+// Carrier Tracking Ports
+// ============================================================================
+
+// CarrierTracker polls a carrier for the progress of its shipments.
+type CarrierTracker interface {
+	// Carrier returns the carrier code (e.g. poslaju, dhl) the tracker serves.
+	Carrier() string
+
+	// Track returns the tracking updates the carrier has for a tracking number.
+	Track(ctx context.Context, trackingNumber string) ([]CarrierTrackingUpdate, error)
+}
+
+// CarrierWebhookParser reads the tracking updates a carrier pushes to the service.
+type CarrierWebhookParser interface {
+	// Carrier returns the carrier code (e.g. poslaju, dhl) the parser serves.
+	Carrier() string
+
+	// ParseWebhook verifies a carrier's request and returns its updates.
+	// It returns ErrInvalidCarrierWebhook when the request cannot be verified.
+	ParseWebhook(ctx context.Context, headers map[string]string, body []byte) ([]CarrierTrackingUpdate, error)
+}
+
+// ErrInvalidCarrierWebhook is returned for carrier webhooks that fail verification.
+var ErrInvalidCarrierWebhook = errors.New("invalid carrier webhook")
+
+// CarrierTrackingUpdate is a checkpoint a carrier reported for a shipment.
+type CarrierTrackingUpdate struct {
+	TrackingNumber string `json:"tracking_number"`
+	// Status is a shipment status: pending, in_transit, out_for_delivery,
+	// delivered, exception or returned.
+	Status            string     `json:"status"`
+	Description       string     `json:"description,omitempty"`
+	Location          string     `json:"location,omitempty"`
+	OccurredAt        time.Time  `json:"occurred_at"`
+	EstimatedDelivery *time.Time `json:"estimated_delivery,omitempty"`
+}
+
+// ============================================================================
+// E-Invoicing Ports
+// ============================================================================
+
+// EInvoiceService submits invoices to LHDN's MyInvois e-invoicing system.
+type EInvoiceService interface {
+	// Submit submits an invoice for validation. A document MyInvois rejects
+	// outright is returned with its errors rather than as an error.
+	Submit(ctx context.Context, doc *EInvoiceDocument) (*EInvoiceSubmission, error)
+
+	// GetDocument returns the validation result of a submitted document.
+	GetDocument(ctx context.Context, supplierTIN, documentUUID string) (*EInvoiceDocumentResult, error)
+
+	// Cancel cancels a valid document.
+	Cancel(ctx context.Context, supplierTIN, documentUUID, reason string) error
+}
+
+// EInvoiceDocument is an invoice as it is submitted to MyInvois. Amounts are
+// in the smallest currency unit.
+type EInvoiceDocument struct {
+	InvoiceNumber string
+	IssuedAt      time.Time
+	Currency      string
+	Supplier      EInvoiceParty
+	Buyer         EInvoiceParty
+	Lines         []EInvoiceLine
+	Taxes         []EInvoiceTax
+	Subtotal      int64 // Net of tax
+	TaxAmount     int64
+	Total         int64
+}
+
+// EInvoiceParty is the supplier or buyer of an e-invoice.
+type EInvoiceParty struct {
+	Name               string
+	TIN                string
+	RegistrationNumber string
+	SSTNumber          string
+	MSICCode           string // Supplier only
+	BusinessActivity   string // Supplier only
+	Email              string
+	Phone              string
+	Address            EInvoiceAddress
+}
+
+// EInvoiceAddress is a party's address. State is the MyInvois state code
+// and Country the ISO 3166-1 alpha-3 code.
+type EInvoiceAddress struct {
+	Line1    string
+	Line2    string
+	City     string
+	Postcode string
+	State    string
+	Country  string
+}
+
+// EInvoiceLine is a line of an e-invoice.
+type EInvoiceLine struct {
+	Description string
+	// ClassificationCode is the MyInvois product or service classification,
+	// e.g. "022" for others.
+	ClassificationCode string
+	Quantity           float64
+	UnitPrice          int64
+	Subtotal           int64 // Net of tax
+	TaxAmount          int64
+	Taxes              []EInvoiceTax
+}
+
+// EInvoiceTax is an amount of tax of one type.
+type EInvoiceTax struct {
+	// TaxType is the MyInvois tax type code: "01" sales tax, "02" service
+	// tax, "06" not applicable or "E" exempt.
+	TaxType       string
+	Rate          float64 // Percentage
+	TaxableAmount int64
+	TaxAmount     int64
+	// ExemptionReason is required for exempt taxes.
+	ExemptionReason string
+}
+
+// EInvoiceSubmission is MyInvois' answer to a submission.
+type EInvoiceSubmission struct {
+	SubmissionUID string
+	// DocumentUUID is empty when the document was rejected, with the
+	// reasons in Errors.
+	DocumentUUID string
+	Errors       []string
+}
+
+// EInvoiceDocumentResult is the validation result of a submitted document.
+type EInvoiceDocumentResult struct {
+	// Status is one of submitted, valid, invalid or cancelled.
+	Status string
+	LongID string
+	// ValidationURL is the public validation link for the invoice's QR code.
+	ValidationURL string
+	Errors        []string
+}
+
+// ============================================================================
+// Accounting Ports
+// ============================================================================
+
+// AccountingConnector pushes invoices and payments to a tenant's accounting
+// system and reads the payments the system posts back.
+type AccountingConnector interface {
+	// PushInvoice pushes an invoice and returns its ID in the accounting system.
+	PushInvoice(ctx context.Context, endpoint AccountingEndpoint, invoice *AccountingInvoice) (string, error)
+
+	// PushPayment pushes a payment and returns its ID in the accounting system.
+	PushPayment(ctx context.Context, endpoint AccountingEndpoint, payment *AccountingPayment) (string, error)
+
+	// ParseWebhook verifies a request from the accounting system and returns
+	// its payments. It returns ErrInvalidAccountingWebhook when the request
+	// cannot be verified.
+	ParseWebhook(ctx context.Context, secret string, headers map[string]string, body []byte) ([]AccountingPaymentUpdate, error)
+}
+
+// ErrInvalidAccountingWebhook is returned for accounting webhooks that fail verification.
+var ErrInvalidAccountingWebhook = errors.New("invalid accounting webhook")
+
+// AccountingEndpoint is where and how a tenant's records are pushed.
+type AccountingEndpoint struct {
+	// Provider is the accounting system: xero, sql_account or autocount.
+	Provider string
+	URL      string
+	Secret   string
+}
+
+// AccountingInvoice is an invoice as it is pushed to an accounting system.
+// Amounts are in the smallest currency unit.
+type AccountingInvoice struct {
+	InvoiceID     uuid.UUID               `json:"invoice_id"`
+	InvoiceNumber string                  `json:"invoice_number"`
+	DealID        uuid.UUID               `json:"deal_id"`
+	DealCode      string                  `json:"deal_code"`
+	CustomerID    uuid.UUID               `json:"customer_id"`
+	CustomerName  string                  `json:"customer_name"`
+	Currency      string                  `json:"currency"`
+	IssuedAt      time.Time               `json:"issued_at"`
+	DueDate       time.Time               `json:"due_date"`
+	Lines         []AccountingInvoiceLine `json:"lines"`
+	Subtotal      int64                   `json:"subtotal"` // Net of tax
+	TaxAmount     int64                   `json:"tax_amount"`
+	Total         int64                   `json:"total"`
+}
+
+// AccountingInvoiceLine is a line of an invoice posted to a revenue account.
+type AccountingInvoiceLine struct {
+	Description string `json:"description"`
+	AccountCode string `json:"account_code"`
+	TaxCode     string `json:"tax_code,omitempty"`
+	Amount      int64  `json:"amount"` // Net of tax
+	TaxAmount   int64  `json:"tax_amount"`
+}
+
+// AccountingPayment is a payment as it is pushed to an accounting system.
+type AccountingPayment struct {
+	PaymentID uuid.UUID `json:"payment_id"`
+	InvoiceID uuid.UUID `json:"invoice_id,omitempty"`
+	// InvoiceExternalID is the paid invoice's ID in the accounting system.
+	InvoiceExternalID string    `json:"invoice_external_id,omitempty"`
+	DealID            uuid.UUID `json:"deal_id"`
+	AccountCode       string    `json:"account_code"`
+	Amount            int64     `json:"amount"`
+	Currency          string    `json:"currency"`
+	Method            string    `json:"method"`
+	Reference         string    `json:"reference,omitempty"`
+	ReceivedAt        time.Time `json:"received_at"`
+}
+
+// AccountingPaymentUpdate is a payment the accounting system recorded
+// against an invoice.
+type AccountingPaymentUpdate struct {
+	// ExternalID is the payment's ID in the accounting system.
+	ExternalID string `json:"external_id"`
+	// InvoiceID is the CRM invoice paid; InvoiceExternalID identifies it
+	// when the accounting system does not know the CRM ID.
+	InvoiceID         *uuid.UUID `json:"invoice_id,omitempty"`
+	InvoiceExternalID string     `json:"invoice_external_id,omitempty"`
+	Amount            int64      `json:"amount"`
+	Currency          string     `json:"currency"`
+	Method            string     `json:"method,omitempty"`
+	Reference         string     `json:"reference,omitempty"`
+	ReceivedAt        time.Time  `json:"received_at"`
+}
+
+// ============================================================================
+// E-Commerce Ports
+// ============================================================================
+
+// EcommerceClient reads orders and abandoned checkouts from the online stores
+// of one e-commerce platform.
+type EcommerceClient interface {
+	// Platform returns the platform the client serves, such as "shopify".
+	Platform() string
+
+	// FetchOrders returns the store's orders updated since the time, oldest
+	// update first.
+	FetchOrders(ctx context.Context, store EcommerceStoreAccess, since time.Time) ([]EcommerceOrder, error)
+
+	// FetchAbandonedCarts returns the store's checkouts updated since the time
+	// that were left without an order.
+	FetchAbandonedCarts(ctx context.Context, store EcommerceStoreAccess, since time.Time) ([]EcommerceCart, error)
+
+	// ParseWebhook verifies a webhook pushed by the store and returns the
+	// orders and carts it carries. It returns ErrInvalidEcommerceWebhook
+	// when the request cannot be verified.
+	ParseWebhook(ctx context.Context, store EcommerceStoreAccess, headers map[string]string, body []byte) (*EcommerceWebhook, error)
+}
+
+// ErrInvalidEcommerceWebhook is returned for store webhooks that fail verification.
+var ErrInvalidEcommerceWebhook = errors.New("invalid e-commerce webhook")
+
+// EcommerceStoreAccess is where a store is and how it is accessed.
+type EcommerceStoreAccess struct {
+	StoreURL      string
+	APIKey        string
+	APISecret     string
+	WebhookSecret string
+}
+
+// EcommerceCustomer is the shopper of an order or cart.
+type EcommerceCustomer struct {
+	Email     string `json:"email"`
+	Phone     string `json:"phone,omitempty"`
+	FirstName string `json:"first_name"`
+	LastName  string `json:"last_name"`
+	Company   string `json:"company,omitempty"`
+	Street1   string `json:"street1,omitempty"`
+	City      string `json:"city,omitempty"`
+	State     string `json:"state,omitempty"`
+	Postcode  string `json:"postcode,omitempty"`
+	Country   string `json:"country,omitempty"`
+}
+
+// EcommerceLine is a line of an order or cart. Amounts are in the smallest
+// currency unit, after discounts.
+type EcommerceLine struct {
+	SKU       string `json:"sku,omitempty"`
+	Name      string `json:"name"`
+	Quantity  int    `json:"quantity"`
+	UnitPrice int64  `json:"unit_price"`
+}
+
+// EcommerceOrder is a store order in a platform-neutral form. Amounts are in
+// the smallest currency unit.
+type EcommerceOrder struct {
+	ExternalID string            `json:"external_id"`
+	Number     string            `json:"number"`
+	Customer   EcommerceCustomer `json:"customer"`
+	Currency   string            `json:"currency"`
+	Lines      []EcommerceLine   `json:"lines"`
+	Shipping   int64             `json:"shipping"`
+	Total      int64             `json:"total"`
+	// Cancelled is set for cancelled, refunded and failed orders.
+	Cancelled bool      `json:"cancelled"`
+	Paid      bool      `json:"paid"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// EcommerceCart is a checkout left without an order.
+type EcommerceCart struct {
+	ExternalID  string            `json:"external_id"`
+	Customer    EcommerceCustomer `json:"customer"`
+	Currency    string            `json:"currency"`
+	Lines       []EcommerceLine   `json:"lines"`
+	Total       int64             `json:"total"`
+	RecoveryURL string            `json:"recovery_url,omitempty"`
+	// Completed is set once the checkout became an order.
+	Completed bool      `json:"completed"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// EcommerceWebhook is the orders and carts a store webhook carries.
+type EcommerceWebhook struct {
+	Orders []EcommerceOrder
+	Carts  []EcommerceCart
+}
+
+// ============================================================================
+// Tenant Branding Port
+// ============================================================================
+
+// TenantBrandingProvider provides the white-label branding of tenants, as
+// configured in the IAM service.
+type TenantBrandingProvider interface {
+	// GetTenantBranding returns the tenant's branding, or nil when the tenant
+	// has none.
+	GetTenantBranding(ctx context.Context, tenantID uuid.UUID) (*TenantBranding, error)
+}
+
+// TenantBranding is how a white-labeled tenant presents itself.
+type TenantBranding struct {
+	TenantName     string `json:"tenant_name"`
+	LogoURL        string `json:"logo_url,omitempty"`
+	PrimaryColor   string `json:"primary_color,omitempty"`
+	SecondaryColor string `json:"secondary_color,omitempty"`
+}
@@ -0,0 +1,793 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/kilang-desa-murni/crm/internal/sales/application"
+	"github.com/kilang-desa-murni/crm/internal/sales/application/dto"
+	"github.com/kilang-desa-murni/crm/internal/sales/application/ports"
+	"github.com/kilang-desa-murni/crm/internal/sales/domain"
+)
+
+const (
+	// accountingBatchSize is how many sync records are pushed per batch.
+	accountingBatchSize = 50
+
+	// accountingQueueLimit is how many unsynced records of a tenant are
+	// queued per run.
+	accountingQueueLimit = 200
+
+	// accountingReportLimit is how many records each reconciliation list holds.
+	accountingReportLimit = 500
+)
+
+// ============================================================================
+// Accounting Use Case Interface
+// ============================================================================
+
+// AccountingUseCase defines the interface for syncing deal invoices and
+// payments with tenants' accounting systems.
+type AccountingUseCase interface {
+	// Connection
+	GetConnection(ctx context.Context, tenantID uuid.UUID) (*dto.AccountingConnectionResponse, error)
+	UpdateConnection(ctx context.Context, tenantID uuid.UUID, req *dto.UpdateAccountingConnectionRequest) (*dto.AccountingConnectionResponse, error)
+	DeleteConnection(ctx context.Context, tenantID uuid.UUID) error
+
+	// Sync records and reconciliation
+	ListSyncs(ctx context.Context, tenantID uuid.UUID, req *dto.ListAccountingSyncsRequest) ([]*dto.AccountingSyncResponse, error)
+	GetReconciliation(ctx context.Context, tenantID uuid.UUID) (*dto.AccountingReconciliationResponse, error)
+	Resync(ctx context.Context, tenantID, syncID uuid.UUID) (*dto.AccountingSyncResponse, error)
+	ResyncInvoice(ctx context.Context, tenantID, dealID, invoiceID uuid.UUID) (*dto.AccountingSyncResponse, error)
+	ResyncAll(ctx context.Context, tenantID uuid.UUID) (*dto.AccountingResyncResponse, error)
+
+	// ReceiveWebhook records the payments a tenant's accounting system posts.
+	ReceiveWebhook(ctx context.Context, tenantID uuid.UUID, headers map[string]string, body []byte) (*dto.AccountingWebhookResponse, error)
+
+	// ProcessDue queues the unsynced records of connected tenants and pushes
+	// the sync records that are due, returning how many were synced.
+	ProcessDue(ctx context.Context, now time.Time) (int, error)
+}
+
+// ============================================================================
+// Accounting Use Case Implementation
+// ============================================================================
+
+// accountingUseCase implements AccountingUseCase.
+type accountingUseCase struct {
+	connectionRepo domain.AccountingConnectionRepository
+	syncRepo       domain.AccountingSyncRepository
+	dealRepo       domain.DealRepository
+	eventPublisher ports.EventPublisher
+	connector      ports.AccountingConnector
+}
+
+// NewAccountingUseCase creates a new accounting use case.
+func NewAccountingUseCase(
+	connectionRepo domain.AccountingConnectionRepository,
+	syncRepo domain.AccountingSyncRepository,
+	dealRepo domain.DealRepository,
+	eventPublisher ports.EventPublisher,
+	connector ports.AccountingConnector,
+) AccountingUseCase {
+	return &accountingUseCase{
+		connectionRepo: connectionRepo,
+		syncRepo:       syncRepo,
+		dealRepo:       dealRepo,
+		eventPublisher: eventPublisher,
+		connector:      connector,
+	}
+}
+
+// ============================================================================
+// Connection
+// ============================================================================
+
+// GetConnection retrieves the tenant's accounting connection.
+func (uc *accountingUseCase) GetConnection(ctx context.Context, tenantID uuid.UUID) (*dto.AccountingConnectionResponse, error) {
+	connection, err := uc.getConnection(ctx, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	if connection == nil {
+		return nil, application.ErrAccountingNotConnected()
+	}
+	return mapAccountingConnectionToResponse(connection), nil
+}
+
+// UpdateConnection connects the tenant's accounting system or changes the
+// connection. The mapping is kept unless one is given.
+func (uc *accountingUseCase) UpdateConnection(ctx context.Context, tenantID uuid.UUID, req *dto.UpdateAccountingConnectionRequest) (*dto.AccountingConnectionResponse, error) {
+	connection, err := uc.getConnection(ctx, tenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	provider := domain.AccountingProvider(req.Provider)
+	if connection == nil {
+		if connection, err = domain.NewAccountingConnection(tenantID, provider, req.URL); err != nil {
+			if errors.Is(err, domain.ErrInvalidAccountingProvider) || errors.Is(err, domain.ErrInvalidAccountingConnectorURL) {
+				return nil, application.ErrValidation(err.Error())
+			}
+			return nil, application.WrapError(application.ErrCodeInternal, "failed to create accounting connection", err)
+		}
+	} else {
+		if err := connection.SetProvider(provider); err != nil {
+			return nil, application.ErrValidation(err.Error())
+		}
+		if err := connection.SetURL(req.URL); err != nil {
+			return nil, application.ErrValidation(err.Error())
+		}
+		if req.RotateSecret {
+			if err := connection.RotateSecret(); err != nil {
+				return nil, application.WrapError(application.ErrCodeInternal, "failed to rotate accounting secret", err)
+			}
+		}
+	}
+	if req.Enabled != nil {
+		connection.Enabled = *req.Enabled
+	}
+	if req.Mapping != nil {
+		connection.SetMapping(domain.AccountingMapping{
+			SalesAccount:   req.Mapping.SalesAccount,
+			PaymentAccount: req.Mapping.PaymentAccount,
+			TaxCodes:       req.Mapping.TaxCodes,
+			DefaultTaxCode: req.Mapping.DefaultTaxCode,
+		})
+	}
+	if connection.Enabled && (connection.Mapping.SalesAccount == "" || connection.Mapping.PaymentAccount == "") {
+		return nil, application.ErrValidation("the sales and payment accounts must be mapped before syncing")
+	}
+
+	if err := uc.connectionRepo.Upsert(ctx, connection); err != nil {
+		return nil, application.WrapError(application.ErrCodeInternal, "failed to save accounting connection", err)
+	}
+	return mapAccountingConnectionToResponse(connection), nil
+}
+
+// DeleteConnection disconnects the tenant's accounting system. Sync records
+// are kept for when it is connected again.
+func (uc *accountingUseCase) DeleteConnection(ctx context.Context, tenantID uuid.UUID) error {
+	if err := uc.connectionRepo.Delete(ctx, tenantID); err != nil {
+		return application.WrapError(application.ErrCodeInternal, "failed to delete accounting connection", err)
+	}
+	return nil
+}
+
+// ============================================================================
+// Sync Records and Reconciliation
+// ============================================================================
+
+// ListSyncs lists the tenant's sync records, most recently updated first.
+func (uc *accountingUseCase) ListSyncs(ctx context.Context, tenantID uuid.UUID, req *dto.ListAccountingSyncsRequest) ([]*dto.AccountingSyncResponse, error) {
+	statuses := make([]domain.AccountingSyncStatus, 0, len(req.Statuses))
+	for _, status := range req.Statuses {
+		s := domain.AccountingSyncStatus(status)
+		if !s.IsValid() {
+			return nil, application.ErrValidation(fmt.Sprintf("invalid sync status: %s", status))
+		}
+		statuses = append(statuses, s)
+	}
+	limit := req.Limit
+	if limit <= 0 || limit > accountingReportLimit {
+		limit = 100
+	}
+
+	syncs, err := uc.syncRepo.List(ctx, tenantID, statuses, limit)
+	if err != nil {
+		return nil, application.WrapError(application.ErrCodeInternal, "failed to list accounting syncs", err)
+	}
+
+	responses := make([]*dto.AccountingSyncResponse, len(syncs))
+	for i, sync := range syncs {
+		responses[i] = mapAccountingSyncToResponse(sync)
+	}
+	return responses, nil
+}
+
+// GetReconciliation reports the issued invoices and payments that are not in
+// the accounting system: those never queued, and those whose sync failed.
+func (uc *accountingUseCase) GetReconciliation(ctx context.Context, tenantID uuid.UUID) (*dto.AccountingReconciliationResponse, error) {
+	connection, err := uc.getConnection(ctx, tenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	unsynced, err := uc.syncRepo.FindUnsynced(ctx, tenantID, accountingReportLimit)
+	if err != nil {
+		return nil, application.WrapError(application.ErrCodeInternal, "failed to find unsynced records", err)
+	}
+	failed, err := uc.syncRepo.List(ctx, tenantID, []domain.AccountingSyncStatus{domain.AccountingSyncStatusFailed}, accountingReportLimit)
+	if err != nil {
+		return nil, application.WrapError(application.ErrCodeInternal, "failed to list failed accounting syncs", err)
+	}
+	counts, err := uc.syncRepo.CountByStatus(ctx, tenantID)
+	if err != nil {
+		return nil, application.WrapError(application.ErrCodeInternal, "failed to count accounting syncs", err)
+	}
+
+	response := &dto.AccountingReconciliationResponse{
+		Connected: connection != nil && connection.Enabled,
+		Unsynced:  make([]dto.UnsyncedAccountingRecordResponse, len(unsynced)),
+		Failed:    make([]dto.AccountingSyncResponse, len(failed)),
+		Pending:   counts[domain.AccountingSyncStatusPending],
+		Synced:    counts[domain.AccountingSyncStatusSynced],
+	}
+	for i, record := range unsynced {
+		response.Unsynced[i] = dto.UnsyncedAccountingRecordResponse{
+			DealID:     record.DealID.String(),
+			DealCode:   record.DealCode,
+			RecordType: string(record.RecordType),
+			RecordID:   record.RecordID.String(),
+			Reference:  record.Reference,
+			Amount:     record.Amount.Amount,
+			Currency:   record.Amount.Currency,
+			Date:       record.Date,
+		}
+	}
+	for i, sync := range failed {
+		response.Failed[i] = *mapAccountingSyncToResponse(sync)
+	}
+	return response, nil
+}
+
+// Resync pushes a sync record again straight away.
+func (uc *accountingUseCase) Resync(ctx context.Context, tenantID, syncID uuid.UUID) (*dto.AccountingSyncResponse, error) {
+	connection, err := uc.requireConnection(ctx, tenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	sync, err := uc.syncRepo.GetByID(ctx, tenantID, syncID)
+	if err != nil {
+		if errors.Is(err, domain.ErrAccountingSyncNotFound) {
+			return nil, application.ErrAccountingSyncNotFound(syncID)
+		}
+		return nil, application.WrapError(application.ErrCodeInternal, "failed to get accounting sync", err)
+	}
+
+	now := time.Now().UTC()
+	sync.Requeue(now)
+	uc.push(ctx, connection, sync, now)
+	if err := uc.saveSync(ctx, sync); err != nil {
+		return nil, err
+	}
+	return mapAccountingSyncToResponse(sync), nil
+}
+
+// ResyncInvoice pushes a deal invoice straight away, queueing it if it was
+// never synced.
+func (uc *accountingUseCase) ResyncInvoice(ctx context.Context, tenantID, dealID, invoiceID uuid.UUID) (*dto.AccountingSyncResponse, error) {
+	connection, err := uc.requireConnection(ctx, tenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	sync, err := uc.syncRepo.GetByRecord(ctx, tenantID, domain.AccountingRecordInvoice, invoiceID)
+	if err != nil {
+		return nil, application.WrapError(application.ErrCodeInternal, "failed to get accounting sync", err)
+	}
+
+	now := time.Now().UTC()
+	if sync == nil {
+		deal, err := uc.dealRepo.GetByID(ctx, tenantID, dealID)
+		if err != nil {
+			return nil, application.ErrDealNotFound(dealID)
+		}
+		invoice := findDealInvoice(deal, invoiceID)
+		if invoice == nil {
+			return nil, application.ErrDealInvoiceNotFound(dealID, invoiceID)
+		}
+		if invoice.Status == "draft" || invoice.Status == "cancelled" {
+			return nil, application.ErrValidation("only issued invoices are synced to accounting")
+		}
+		if sync, err = domain.NewAccountingSync(tenantID, dealID, domain.AccountingRecordInvoice, invoiceID, invoice.InvoiceNumber, invoice.Amount); err != nil {
+			return nil, application.ErrValidation(err.Error())
+		}
+		if err := uc.syncRepo.Create(ctx, sync); err != nil {
+			return nil, application.WrapError(application.ErrCodeInternal, "failed to create accounting sync", err)
+		}
+	} else {
+		if sync.DealID != dealID {
+			return nil, application.ErrDealInvoiceNotFound(dealID, invoiceID)
+		}
+		sync.Requeue(now)
+	}
+
+	uc.push(ctx, connection, sync, now)
+	if err := uc.saveSync(ctx, sync); err != nil {
+		return nil, err
+	}
+	return mapAccountingSyncToResponse(sync), nil
+}
+
+// ResyncAll queues the tenant's failed syncs and unsynced records to be
+// pushed by the next run.
+func (uc *accountingUseCase) ResyncAll(ctx context.Context, tenantID uuid.UUID) (*dto.AccountingResyncResponse, error) {
+	if _, err := uc.requireConnection(ctx, tenantID); err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	failed, err := uc.syncRepo.List(ctx, tenantID, []domain.AccountingSyncStatus{domain.AccountingSyncStatusFailed}, accountingReportLimit)
+	if err != nil {
+		return nil, application.WrapError(application.ErrCodeInternal, "failed to list failed accounting syncs", err)
+	}
+
+	queued := 0
+	for _, sync := range failed {
+		sync.Requeue(now)
+		if err := uc.saveSync(ctx, sync); err != nil {
+			return nil, err
+		}
+		queued++
+	}
+
+	created, err := uc.queueUnsynced(ctx, tenantID, now)
+	if err != nil {
+		return nil, err
+	}
+	return &dto.AccountingResyncResponse{Queued: queued + created}, nil
+}
+
+// ============================================================================
+// Webhook
+// ============================================================================
+
+// ReceiveWebhook records the payments the tenant's accounting system posts
+// against synced invoices. Payments already recorded are skipped, so the
+// connector may post them again.
+func (uc *accountingUseCase) ReceiveWebhook(ctx context.Context, tenantID uuid.UUID, headers map[string]string, body []byte) (*dto.AccountingWebhookResponse, error) {
+	if uc.connector == nil {
+		return nil, application.ErrServiceUnavailable("accounting")
+	}
+
+	connection, err := uc.getConnection(ctx, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	if connection == nil || !connection.Enabled {
+		return nil, application.ErrUnauthorized("invalid accounting webhook signature")
+	}
+
+	updates, err := uc.connector.ParseWebhook(ctx, connection.Secret, headers, body)
+	if err != nil {
+		if errors.Is(err, ports.ErrInvalidAccountingWebhook) {
+			return nil, application.ErrUnauthorized("invalid accounting webhook signature")
+		}
+		return nil, application.ErrValidation(err.Error())
+	}
+
+	result := &dto.AccountingWebhookResponse{Received: len(updates)}
+	for _, update := range updates {
+		recorded, err := uc.recordPayment(ctx, tenantID, update)
+		if err != nil {
+			return nil, err
+		}
+		if recorded {
+			result.Recorded++
+		}
+	}
+	return result, nil
+}
+
+// recordPayment records a payment from the accounting system on the deal
+// of the invoice it pays, reporting whether it was new.
+func (uc *accountingUseCase) recordPayment(ctx context.Context, tenantID uuid.UUID, update ports.AccountingPaymentUpdate) (bool, error) {
+	if update.ExternalID == "" {
+		return false, application.ErrValidation("payment external_id is required")
+	}
+
+	existing, err := uc.syncRepo.GetByExternalID(ctx, tenantID, domain.AccountingRecordPayment, update.ExternalID)
+	if err != nil {
+		return false, application.WrapError(application.ErrCodeInternal, "failed to get accounting sync", err)
+	}
+	if existing != nil {
+		return false, nil
+	}
+
+	// The accounting system only knows invoices that were pushed to it
+	var invoiceSync *domain.AccountingSync
+	if update.InvoiceID != nil {
+		invoiceSync, err = uc.syncRepo.GetByRecord(ctx, tenantID, domain.AccountingRecordInvoice, *update.InvoiceID)
+	} else if update.InvoiceExternalID != "" {
+		invoiceSync, err = uc.syncRepo.GetByExternalID(ctx, tenantID, domain.AccountingRecordInvoice, update.InvoiceExternalID)
+	}
+	if err != nil {
+		return false, application.WrapError(application.ErrCodeInternal, "failed to get invoice sync", err)
+	}
+	if invoiceSync == nil {
+		return false, application.ErrValidation(fmt.Sprintf("payment %s is not for a synced invoice", update.ExternalID))
+	}
+
+	deal, err := uc.dealRepo.GetByID(ctx, tenantID, invoiceSync.DealID)
+	if err != nil {
+		return false, application.ErrDealNotFound(invoiceSync.DealID)
+	}
+
+	amount, err := domain.NewMoney(update.Amount, update.Currency)
+	if err != nil {
+		return false, application.ErrValidation(fmt.Sprintf("payment %s has an invalid amount", update.ExternalID))
+	}
+	receivedAt := update.ReceivedAt
+	if receivedAt.IsZero() {
+		receivedAt = time.Now().UTC()
+	}
+	method := update.Method
+	if method == "" {
+		method = "bank_transfer"
+	}
+
+	invoiceID := invoiceSync.RecordID
+	payment := domain.Payment{
+		InvoiceID:     &invoiceID,
+		Amount:        amount,
+		PaymentMethod: method,
+		Reference:     update.Reference,
+		ReceivedAt:    receivedAt,
+		Notes:         "Recorded from accounting payment " + update.ExternalID,
+	}
+	if err := deal.RecordPayment(payment); err != nil {
+		return false, application.WrapError(application.ErrCodeDealPaymentExceedsBalance, err.Error(), err)
+	}
+	recorded := deal.Payments[len(deal.Payments)-1]
+
+	deal.Version++
+	if err := uc.dealRepo.Update(ctx, deal); err != nil {
+		return false, application.WrapError(application.ErrCodeInternal, "failed to update deal", err)
+	}
+	uc.publishDealEvents(ctx, deal)
+
+	// The payment came from the accounting system, so it is not pushed back
+	sync, err := domain.NewAccountingSync(tenantID, deal.ID, domain.AccountingRecordPayment, recorded.ID, update.ExternalID, amount)
+	if err != nil {
+		return false, application.ErrValidation(err.Error())
+	}
+	_ = sync.MarkSynced(update.ExternalID, time.Now().UTC())
+	if err := uc.syncRepo.Create(ctx, sync); err != nil {
+		return false, application.WrapError(application.ErrCodeInternal, "failed to create accounting sync", err)
+	}
+	return true, nil
+}
+
+// ============================================================================
+// Background Processing
+// ============================================================================
+
+// ProcessDue queues the issued invoices and payments of connected tenants
+// that have no sync record, then pushes the pending records that are due.
+func (uc *accountingUseCase) ProcessDue(ctx context.Context, now time.Time) (int, error) {
+	if uc.connector == nil {
+		return 0, nil
+	}
+
+	connections, err := uc.connectionRepo.ListEnabled(ctx)
+	if err != nil {
+		return 0, application.WrapError(application.ErrCodeInternal, "failed to list accounting connections", err)
+	}
+	connectionByTenant := make(map[uuid.UUID]*domain.AccountingConnection, len(connections))
+	for _, connection := range connections {
+		connectionByTenant[connection.TenantID] = connection
+		// A tenant whose records cannot be queued is retried by the next run
+		_, _ = uc.queueUnsynced(ctx, connection.TenantID, now)
+	}
+
+	synced := 0
+	seen := make(map[uuid.UUID]bool)
+	for {
+		syncs, err := uc.syncRepo.GetDue(ctx, now, accountingBatchSize)
+		if err != nil {
+			return synced, application.WrapError(application.ErrCodeInternal, "failed to get accounting syncs due", err)
+		}
+
+		progressed := false
+		for _, sync := range syncs {
+			if seen[sync.ID] {
+				continue
+			}
+			seen[sync.ID] = true
+			progressed = true
+
+			// Records of disconnected tenants wait until they reconnect
+			connection, ok := connectionByTenant[sync.TenantID]
+			if !ok {
+				continue
+			}
+
+			uc.push(ctx, connection, sync, now)
+			if err := uc.saveSync(ctx, sync); err != nil {
+				continue
+			}
+			if sync.Status == domain.AccountingSyncStatusSynced {
+				synced++
+			}
+		}
+
+		if len(syncs) < accountingBatchSize || !progressed {
+			return synced, nil
+		}
+	}
+}
+
+// queueUnsynced creates pending sync records, due at the given time, for a
+// tenant's issued invoices and payments that have none.
+func (uc *accountingUseCase) queueUnsynced(ctx context.Context, tenantID uuid.UUID, at time.Time) (int, error) {
+	records, err := uc.syncRepo.FindUnsynced(ctx, tenantID, accountingQueueLimit)
+	if err != nil {
+		return 0, application.WrapError(application.ErrCodeInternal, "failed to find unsynced records", err)
+	}
+
+	queued := 0
+	for _, record := range records {
+		sync, err := domain.NewAccountingSync(tenantID, record.DealID, record.RecordType, record.RecordID, record.Reference, record.Amount)
+		if err != nil {
+			continue
+		}
+		sync.Requeue(at)
+		if err := uc.syncRepo.Create(ctx, sync); err != nil {
+			// Queued concurrently by another run
+			if errors.Is(err, domain.ErrAccountingSyncAlreadyExists) {
+				continue
+			}
+			return queued, application.WrapError(application.ErrCodeInternal, "failed to create accounting sync", err)
+		}
+		queued++
+	}
+	return queued, nil
+}
+
+// push pushes a pending sync record's invoice or payment to the accounting
+// system. Failures schedule a retry.
+func (uc *accountingUseCase) push(ctx context.Context, connection *domain.AccountingConnection, sync *domain.AccountingSync, now time.Time) {
+	if uc.connector == nil {
+		_ = sync.MarkFailed("no accounting connector is configured", now)
+		return
+	}
+
+	deal, err := uc.dealRepo.GetByID(ctx, sync.TenantID, sync.DealID)
+	if err != nil {
+		_ = sync.MarkFailed("deal not found", now)
+		return
+	}
+
+	endpoint := ports.AccountingEndpoint{
+		Provider: string(connection.Provider),
+		URL:      connection.URL,
+		Secret:   connection.Secret,
+	}
+
+	var externalID string
+	switch sync.RecordType {
+	case domain.AccountingRecordInvoice:
+		invoice := findDealInvoice(deal, sync.RecordID)
+		if invoice == nil {
+			_ = sync.MarkFailed("invoice not found on deal", now)
+			return
+		}
+		externalID, err = uc.connector.PushInvoice(ctx, endpoint, buildAccountingInvoice(connection.Mapping, deal, invoice))
+	case domain.AccountingRecordPayment:
+		var payment *ports.AccountingPayment
+		if payment, err = uc.buildAccountingPayment(ctx, connection.Mapping, deal, sync.RecordID); err == nil {
+			externalID, err = uc.connector.PushPayment(ctx, endpoint, payment)
+		}
+	}
+	if err != nil {
+		_ = sync.MarkFailed(err.Error(), now)
+		return
+	}
+	_ = sync.MarkSynced(externalID, now)
+}
+
+// buildAccountingInvoice builds the invoice pushed to the accounting system,
+// with a revenue line for each tax rate the invoice was taxed at.
+func buildAccountingInvoice(mapping domain.AccountingMapping, deal *domain.Deal, invoice *domain.Invoice) *ports.AccountingInvoice {
+	description := fmt.Sprintf("%s (%s)", deal.Name, deal.Code)
+
+	lines := make([]ports.AccountingInvoiceLine, 0, len(invoice.TaxLines))
+	for _, taxLine := range invoice.TaxLines {
+		lines = append(lines, ports.AccountingInvoiceLine{
+			Description: fmt.Sprintf("%s - %s", description, taxLine.Name),
+			AccountCode: mapping.SalesAccount,
+			TaxCode:     mapping.TaxCode(taxLine.Code),
+			Amount:      taxLine.TaxableAmount.Amount,
+			TaxAmount:   taxLine.TaxAmount.Amount,
+		})
+	}
+	if len(lines) == 0 {
+		lines = append(lines, ports.AccountingInvoiceLine{
+			Description: description,
+			AccountCode: mapping.SalesAccount,
+			TaxCode:     mapping.DefaultTaxCode,
+			Amount:      invoice.Subtotal.Amount,
+			TaxAmount:   invoice.TaxAmount.Amount,
+		})
+	}
+
+	issuedAt := invoice.CreatedAt
+	if invoice.SentAt != nil {
+		issuedAt = *invoice.SentAt
+	}
+
+	return &ports.AccountingInvoice{
+		InvoiceID:     invoice.ID,
+		InvoiceNumber: invoice.InvoiceNumber,
+		DealID:        deal.ID,
+		DealCode:      deal.Code,
+		CustomerID:    deal.CustomerID,
+		CustomerName:  deal.CustomerName,
+		Currency:      invoice.Amount.Currency,
+		IssuedAt:      issuedAt,
+		DueDate:       invoice.DueDate,
+		Lines:         lines,
+		Subtotal:      invoice.Subtotal.Amount,
+		TaxAmount:     invoice.TaxAmount.Amount,
+		Total:         invoice.Amount.Amount,
+	}
+}
+
+// buildAccountingPayment builds the payment pushed to the accounting system.
+// A payment against an invoice waits until the invoice is synced.
+func (uc *accountingUseCase) buildAccountingPayment(ctx context.Context, mapping domain.AccountingMapping, deal *domain.Deal, paymentID uuid.UUID) (*ports.AccountingPayment, error) {
+	var payment *domain.Payment
+	for i := range deal.Payments {
+		if deal.Payments[i].ID == paymentID {
+			payment = &deal.Payments[i]
+			break
+		}
+	}
+	if payment == nil {
+		return nil, errors.New("payment not found on deal")
+	}
+
+	result := &ports.AccountingPayment{
+		PaymentID:   payment.ID,
+		DealID:      deal.ID,
+		AccountCode: mapping.PaymentAccount,
+		Amount:      payment.Amount.Amount,
+		Currency:    payment.Amount.Currency,
+		Method:      payment.PaymentMethod,
+		Reference:   payment.Reference,
+		ReceivedAt:  payment.ReceivedAt,
+	}
+	if payment.InvoiceID != nil {
+		invoiceSync, err := uc.syncRepo.GetByRecord(ctx, deal.TenantID, domain.AccountingRecordInvoice, *payment.InvoiceID)
+		if err != nil {
+			return nil, err
+		}
+		if invoiceSync == nil || invoiceSync.Status != domain.AccountingSyncStatusSynced {
+			return nil, errors.New("the paid invoice is not synced yet")
+		}
+		result.InvoiceID = *payment.InvoiceID
+		result.InvoiceExternalID = invoiceSync.ExternalID
+	}
+	return result, nil
+}
+
+// ============================================================================
+// Helpers
+// ============================================================================
+
+func (uc *accountingUseCase) getConnection(ctx context.Context, tenantID uuid.UUID) (*domain.AccountingConnection, error) {
+	connection, err := uc.connectionRepo.Get(ctx, tenantID)
+	if err != nil {
+		return nil, application.WrapError(application.ErrCodeInternal, "failed to get accounting connection", err)
+	}
+	return connection, nil
+}
+
+// requireConnection returns the tenant's connection if it can be synced to.
+func (uc *accountingUseCase) requireConnection(ctx context.Context, tenantID uuid.UUID) (*domain.AccountingConnection, error) {
+	if uc.connector == nil {
+		return nil, application.ErrServiceUnavailable("accounting")
+	}
+	connection, err := uc.getConnection(ctx, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	if connection == nil || !connection.Enabled {
+		return nil, application.ErrAccountingNotConnected()
+	}
+	return connection, nil
+}
+
+func findDealInvoice(deal *domain.Deal, invoiceID uuid.UUID) *domain.Invoice {
+	for i := range deal.Invoices {
+		if deal.Invoices[i].ID == invoiceID {
+			return &deal.Invoices[i]
+		}
+	}
+	return nil
+}
+
+func (uc *accountingUseCase) saveSync(ctx context.Context, sync *domain.AccountingSync) error {
+	sync.Version++
+	if err := uc.syncRepo.Update(ctx, sync); err != nil {
+		if errors.Is(err, domain.ErrAccountingSyncNotFound) {
+			return application.ErrConcurrentModification("accounting sync", sync.ID)
+		}
+		return application.WrapError(application.ErrCodeInternal, "failed to update accounting sync", err)
+	}
+
+	uc.publishEvents(ctx, sync.GetEvents())
+	sync.ClearEvents()
+	return nil
+}
+
+func (uc *accountingUseCase) publishDealEvents(ctx context.Context, deal *domain.Deal) {
+	uc.publishEvents(ctx, deal.GetEvents())
+	deal.ClearEvents()
+}
+
+func (uc *accountingUseCase) publishEvents(ctx context.Context, events []domain.DomainEvent) {
+	if uc.eventPublisher == nil {
+		return
+	}
+
+	for _, event := range events {
+		var payload map[string]interface{}
+		if data, err := json.Marshal(event); err == nil {
+			json.Unmarshal(data, &payload)
+		}
+
+		uc.eventPublisher.Publish(ctx, ports.Event{
+			ID:            event.EventID().String(),
+			Type:          event.EventType(),
+			AggregateID:   event.AggregateID().String(),
+			AggregateType: event.AggregateType(),
+			TenantID:      event.TenantID().String(),
+			Payload:       payload,
+			OccurredAt:    event.OccurredAt(),
+			Version:       event.Version(),
+		})
+	}
+}
+
+// ============================================================================
+// Mapping Functions
+// ============================================================================
+
+func mapAccountingConnectionToResponse(connection *domain.AccountingConnection) *dto.AccountingConnectionResponse {
+	taxCodes := make(map[string]string, len(connection.Mapping.TaxCodes))
+	for code, taxCode := range connection.Mapping.TaxCodes {
+		taxCodes[code] = taxCode
+	}
+	return &dto.AccountingConnectionResponse{
+		Provider: string(connection.Provider),
+		URL:      connection.URL,
+		Secret:   connection.Secret,
+		Enabled:  connection.Enabled,
+		Mapping: dto.AccountingMappingDTO{
+			SalesAccount:   connection.Mapping.SalesAccount,
+			PaymentAccount: connection.Mapping.PaymentAccount,
+			TaxCodes:       taxCodes,
+			DefaultTaxCode: connection.Mapping.DefaultTaxCode,
+		},
+		CreatedAt: connection.CreatedAt,
+		UpdatedAt: connection.UpdatedAt,
+	}
+}
+
+func mapAccountingSyncToResponse(sync *domain.AccountingSync) *dto.AccountingSyncResponse {
+	return &dto.AccountingSyncResponse{
+		ID:            sync.ID.String(),
+		DealID:        sync.DealID.String(),
+		RecordType:    string(sync.RecordType),
+		RecordID:      sync.RecordID.String(),
+		Reference:     sync.Reference,
+		Amount:        sync.Amount.Amount,
+		Currency:      sync.Amount.Currency,
+		Status:        string(sync.Status),
+		ExternalID:    sync.ExternalID,
+		Attempts:      sync.Attempts,
+		NextAttemptAt: sync.NextAttemptAt,
+		LastError:     sync.LastError,
+		SyncedAt:      sync.SyncedAt,
+		CreatedAt:     sync.CreatedAt,
+		UpdatedAt:     sync.UpdatedAt,
+	}
+}
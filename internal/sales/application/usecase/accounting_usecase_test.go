@@ -0,0 +1,427 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/kilang-desa-murni/crm/internal/sales/application"
+	"github.com/kilang-desa-murni/crm/internal/sales/application/dto"
+	"github.com/kilang-desa-murni/crm/internal/sales/application/ports"
+	"github.com/kilang-desa-murni/crm/internal/sales/domain"
+)
+
+// ============================================================================
+// Mock Implementations for Accounting Tests
+// ============================================================================
+
+// MockAccountingConnectionRepository is a mock implementation of domain.AccountingConnectionRepository.
+type MockAccountingConnectionRepository struct {
+	connections map[uuid.UUID]*domain.AccountingConnection
+}
+
+func NewMockAccountingConnectionRepository() *MockAccountingConnectionRepository {
+	return &MockAccountingConnectionRepository{connections: make(map[uuid.UUID]*domain.AccountingConnection)}
+}
+
+func (m *MockAccountingConnectionRepository) Get(ctx context.Context, tenantID uuid.UUID) (*domain.AccountingConnection, error) {
+	return m.connections[tenantID], nil
+}
+
+func (m *MockAccountingConnectionRepository) Upsert(ctx context.Context, connection *domain.AccountingConnection) error {
+	m.connections[connection.TenantID] = connection
+	return nil
+}
+
+func (m *MockAccountingConnectionRepository) Delete(ctx context.Context, tenantID uuid.UUID) error {
+	delete(m.connections, tenantID)
+	return nil
+}
+
+func (m *MockAccountingConnectionRepository) ListEnabled(ctx context.Context) ([]*domain.AccountingConnection, error) {
+	var result []*domain.AccountingConnection
+	for _, connection := range m.connections {
+		if connection.Enabled {
+			result = append(result, connection)
+		}
+	}
+	return result, nil
+}
+
+// MockAccountingSyncRepository is a mock implementation of domain.AccountingSyncRepository.
+// Records reports the issued invoices and payments FindUnsynced looks through.
+type MockAccountingSyncRepository struct {
+	syncs   map[uuid.UUID]*domain.AccountingSync
+	records []*domain.UnsyncedAccountingRecord
+}
+
+func NewMockAccountingSyncRepository() *MockAccountingSyncRepository {
+	return &MockAccountingSyncRepository{syncs: make(map[uuid.UUID]*domain.AccountingSync)}
+}
+
+func (m *MockAccountingSyncRepository) Create(ctx context.Context, sync *domain.AccountingSync) error {
+	for _, existing := range m.syncs {
+		if existing.TenantID == sync.TenantID && existing.RecordType == sync.RecordType && existing.RecordID == sync.RecordID {
+			return domain.ErrAccountingSyncAlreadyExists
+		}
+	}
+	m.syncs[sync.ID] = sync
+	return nil
+}
+
+func (m *MockAccountingSyncRepository) GetByID(ctx context.Context, tenantID, syncID uuid.UUID) (*domain.AccountingSync, error) {
+	sync, ok := m.syncs[syncID]
+	if !ok || sync.TenantID != tenantID {
+		return nil, domain.ErrAccountingSyncNotFound
+	}
+	return sync, nil
+}
+
+func (m *MockAccountingSyncRepository) Update(ctx context.Context, sync *domain.AccountingSync) error {
+	if _, ok := m.syncs[sync.ID]; !ok {
+		return domain.ErrAccountingSyncNotFound
+	}
+	m.syncs[sync.ID] = sync
+	return nil
+}
+
+func (m *MockAccountingSyncRepository) GetByRecord(ctx context.Context, tenantID uuid.UUID, recordType domain.AccountingRecordType, recordID uuid.UUID) (*domain.AccountingSync, error) {
+	for _, sync := range m.syncs {
+		if sync.TenantID == tenantID && sync.RecordType == recordType && sync.RecordID == recordID {
+			return sync, nil
+		}
+	}
+	return nil, nil
+}
+
+func (m *MockAccountingSyncRepository) GetByExternalID(ctx context.Context, tenantID uuid.UUID, recordType domain.AccountingRecordType, externalID string) (*domain.AccountingSync, error) {
+	for _, sync := range m.syncs {
+		if sync.TenantID == tenantID && sync.RecordType == recordType && sync.ExternalID == externalID {
+			return sync, nil
+		}
+	}
+	return nil, nil
+}
+
+func (m *MockAccountingSyncRepository) List(ctx context.Context, tenantID uuid.UUID, statuses []domain.AccountingSyncStatus, limit int) ([]*domain.AccountingSync, error) {
+	var result []*domain.AccountingSync
+	for _, sync := range m.syncs {
+		if sync.TenantID != tenantID {
+			continue
+		}
+		matches := len(statuses) == 0
+		for _, status := range statuses {
+			matches = matches || sync.Status == status
+		}
+		if matches {
+			result = append(result, sync)
+		}
+	}
+	return result, nil
+}
+
+func (m *MockAccountingSyncRepository) CountByStatus(ctx context.Context, tenantID uuid.UUID) (map[domain.AccountingSyncStatus]int, error) {
+	counts := make(map[domain.AccountingSyncStatus]int)
+	for _, sync := range m.syncs {
+		if sync.TenantID == tenantID {
+			counts[sync.Status]++
+		}
+	}
+	return counts, nil
+}
+
+func (m *MockAccountingSyncRepository) GetDue(ctx context.Context, dueBy time.Time, limit int) ([]*domain.AccountingSync, error) {
+	var result []*domain.AccountingSync
+	for _, sync := range m.syncs {
+		if sync.Status == domain.AccountingSyncStatusPending && sync.NextAttemptAt != nil && !sync.NextAttemptAt.After(dueBy) {
+			result = append(result, sync)
+		}
+	}
+	// Invoices are pushed before the payments against them
+	sort.Slice(result, func(i, j int) bool { return result[i].RecordType < result[j].RecordType })
+	return result, nil
+}
+
+func (m *MockAccountingSyncRepository) FindUnsynced(ctx context.Context, tenantID uuid.UUID, limit int) ([]*domain.UnsyncedAccountingRecord, error) {
+	var result []*domain.UnsyncedAccountingRecord
+	for _, record := range m.records {
+		if sync, _ := m.GetByRecord(ctx, tenantID, record.RecordType, record.RecordID); sync == nil {
+			result = append(result, record)
+		}
+	}
+	return result, nil
+}
+
+// MockAccountingConnector is a mock implementation of ports.AccountingConnector.
+type MockAccountingConnector struct {
+	invoices []*ports.AccountingInvoice
+	payments []*ports.AccountingPayment
+	pushErr  error
+	updates  []ports.AccountingPaymentUpdate
+}
+
+func (m *MockAccountingConnector) PushInvoice(ctx context.Context, endpoint ports.AccountingEndpoint, invoice *ports.AccountingInvoice) (string, error) {
+	if m.pushErr != nil {
+		return "", m.pushErr
+	}
+	m.invoices = append(m.invoices, invoice)
+	return "XERO-" + invoice.InvoiceNumber, nil
+}
+
+func (m *MockAccountingConnector) PushPayment(ctx context.Context, endpoint ports.AccountingEndpoint, payment *ports.AccountingPayment) (string, error) {
+	if m.pushErr != nil {
+		return "", m.pushErr
+	}
+	m.payments = append(m.payments, payment)
+	return "XERO-PAY-" + payment.PaymentID.String(), nil
+}
+
+func (m *MockAccountingConnector) ParseWebhook(ctx context.Context, secret string, headers map[string]string, body []byte) ([]ports.AccountingPaymentUpdate, error) {
+	if headers["X-Webhook-Signature"] != secret {
+		return nil, ports.ErrInvalidAccountingWebhook
+	}
+	return m.updates, nil
+}
+
+// ============================================================================
+// Helper Functions
+// ============================================================================
+
+func createAccountingTestConnection(uc AccountingUseCase, t *testing.T, tenantID uuid.UUID) *dto.AccountingConnectionResponse {
+	t.Helper()
+
+	connection, err := uc.UpdateConnection(context.Background(), tenantID, &dto.UpdateAccountingConnectionRequest{
+		Provider: "xero",
+		URL:      "https://connector.example.com/xero",
+		Mapping: &dto.AccountingMappingDTO{
+			SalesAccount:   "200",
+			PaymentAccount: "090",
+			TaxCodes:       map[string]string{"SST8": "OUTPUT-SST8"},
+			DefaultTaxCode: "NONE",
+		},
+	})
+	if err != nil {
+		t.Fatalf("UpdateConnection() error = %v", err)
+	}
+	return connection
+}
+
+func createAccountingTestDeal(tenantID uuid.UUID) (*domain.Deal, *domain.Invoice) {
+	deal, invoice := createEInvoiceTestDeal(tenantID)
+	deal.TotalAmount = domain.Money{Amount: 108000, Currency: "MYR"}
+	deal.PaidAmount = domain.Money{Amount: 0, Currency: "MYR"}
+	deal.OutstandingAmount = domain.Money{Amount: 108000, Currency: "MYR"}
+	invoice.PaidAmount = domain.Money{Amount: 0, Currency: "MYR"}
+	return deal, invoice
+}
+
+// ============================================================================
+// AccountingUseCase Tests
+// ============================================================================
+
+func TestAccountingUseCase_UpdateConnection(t *testing.T) {
+	ctx := context.Background()
+	tenantID := uuid.New()
+	uc := NewAccountingUseCase(NewMockAccountingConnectionRepository(), NewMockAccountingSyncRepository(), NewDealMockDealRepository(), nil, &MockAccountingConnector{})
+
+	_, err := uc.GetConnection(ctx, tenantID)
+	if appErr := application.GetAppError(err); appErr == nil || appErr.Code != application.ErrCodeAccountingNotConnected {
+		t.Fatalf("GetConnection() before connecting error = %v, want %s", err, application.ErrCodeAccountingNotConnected)
+	}
+
+	// Records cannot be synced until the accounts are mapped
+	_, err = uc.UpdateConnection(ctx, tenantID, &dto.UpdateAccountingConnectionRequest{Provider: "xero", URL: "https://connector.example.com/xero"})
+	if appErr := application.GetAppError(err); appErr == nil || appErr.Code != application.ErrCodeValidation {
+		t.Fatalf("UpdateConnection() without mapping error = %v, want %s", err, application.ErrCodeValidation)
+	}
+
+	connection := createAccountingTestConnection(uc, t, tenantID)
+	if connection.Secret == "" || !connection.Enabled || connection.Mapping.TaxCodes["SST8"] != "OUTPUT-SST8" {
+		t.Errorf("connection = %+v, want an enabled connection with its secret and mapping", connection)
+	}
+
+	// Changing the provider keeps the mapping and secret unless asked
+	updated, err := uc.UpdateConnection(ctx, tenantID, &dto.UpdateAccountingConnectionRequest{Provider: "autocount", URL: "https://bridge.example.com"})
+	if err != nil {
+		t.Fatalf("UpdateConnection() error = %v", err)
+	}
+	if updated.Provider != "autocount" || updated.Secret != connection.Secret || updated.Mapping.SalesAccount != "200" {
+		t.Errorf("connection = %+v, want autocount with the same secret and mapping", updated)
+	}
+
+	rotated, err := uc.UpdateConnection(ctx, tenantID, &dto.UpdateAccountingConnectionRequest{Provider: "autocount", URL: "https://bridge.example.com", RotateSecret: true})
+	if err != nil || rotated.Secret == connection.Secret {
+		t.Errorf("UpdateConnection() rotating the secret = %v, secret changed = %v", err, err == nil && rotated.Secret != connection.Secret)
+	}
+}
+
+func TestAccountingUseCase_ProcessDue(t *testing.T) {
+	ctx := context.Background()
+	tenantID := uuid.New()
+	dealRepo := NewDealMockDealRepository()
+	syncRepo := NewMockAccountingSyncRepository()
+	connector := &MockAccountingConnector{pushErr: errors.New("connector unreachable")}
+	uc := NewAccountingUseCase(NewMockAccountingConnectionRepository(), syncRepo, dealRepo, NewDealMockEventPublisher(), connector)
+
+	createAccountingTestConnection(uc, t, tenantID)
+	deal, invoice := createAccountingTestDeal(tenantID)
+	dealRepo.deals[deal.ID] = deal
+	syncRepo.records = []*domain.UnsyncedAccountingRecord{{
+		DealID:     deal.ID,
+		DealCode:   deal.Code,
+		RecordType: domain.AccountingRecordInvoice,
+		RecordID:   invoice.ID,
+		Reference:  invoice.InvoiceNumber,
+		Amount:     invoice.Amount,
+	}}
+
+	report, err := uc.GetReconciliation(ctx, tenantID)
+	if err != nil {
+		t.Fatalf("GetReconciliation() error = %v", err)
+	}
+	if !report.Connected || len(report.Unsynced) != 1 || report.Unsynced[0].Reference != "INV-2026-0001" {
+		t.Errorf("report = %+v, want the unsynced invoice", report)
+	}
+
+	// The connector cannot be reached, so the push is retried later
+	now := time.Now().UTC()
+	if synced, err := uc.ProcessDue(ctx, now); err != nil || synced != 0 {
+		t.Fatalf("ProcessDue() = %d, %v, want 0, nil", synced, err)
+	}
+	sync, _ := syncRepo.GetByRecord(ctx, tenantID, domain.AccountingRecordInvoice, invoice.ID)
+	if sync == nil || sync.Status != domain.AccountingSyncStatusPending || sync.Attempts != 1 {
+		t.Fatalf("sync = %+v, want a pending retry", sync)
+	}
+
+	connector.pushErr = nil
+	if synced, err := uc.ProcessDue(ctx, sync.NextAttemptAt.Add(time.Second)); err != nil || synced != 1 {
+		t.Fatalf("ProcessDue() retry = %d, %v, want 1, nil", synced, err)
+	}
+	if sync.Status != domain.AccountingSyncStatusSynced || sync.ExternalID != "XERO-INV-2026-0001" {
+		t.Errorf("Status, ExternalID = %s, %q, want synced", sync.Status, sync.ExternalID)
+	}
+
+	if len(connector.invoices) != 1 {
+		t.Fatalf("pushed %d invoices, want 1", len(connector.invoices))
+	}
+	pushed := connector.invoices[0]
+	if pushed.Total != 108000 || len(pushed.Lines) != 1 {
+		t.Fatalf("invoice = %+v, want the total with one line", pushed)
+	}
+	if line := pushed.Lines[0]; line.AccountCode != "200" || line.TaxCode != "OUTPUT-SST8" || line.Amount != 100000 || line.TaxAmount != 8000 {
+		t.Errorf("line = %+v, want the mapped sales account and tax code", line)
+	}
+}
+
+func TestAccountingUseCase_ResyncFailed(t *testing.T) {
+	ctx := context.Background()
+	tenantID := uuid.New()
+	dealRepo := NewDealMockDealRepository()
+	syncRepo := NewMockAccountingSyncRepository()
+	eventPublisher := NewDealMockEventPublisher()
+	connector := &MockAccountingConnector{pushErr: errors.New("account 200 is archived")}
+	uc := NewAccountingUseCase(NewMockAccountingConnectionRepository(), syncRepo, dealRepo, eventPublisher, connector)
+
+	createAccountingTestConnection(uc, t, tenantID)
+	deal, invoice := createAccountingTestDeal(tenantID)
+	dealRepo.deals[deal.ID] = deal
+
+	result, err := uc.ResyncInvoice(ctx, tenantID, deal.ID, invoice.ID)
+	if err != nil {
+		t.Fatalf("ResyncInvoice() error = %v", err)
+	}
+	sync := syncRepo.syncs[uuid.MustParse(result.ID)]
+	for sync.Status == domain.AccountingSyncStatusPending {
+		if _, err := uc.ProcessDue(ctx, sync.NextAttemptAt.Add(time.Second)); err != nil {
+			t.Fatalf("ProcessDue() error = %v", err)
+		}
+	}
+	if sync.Status != domain.AccountingSyncStatusFailed || sync.Attempts != domain.MaxAccountingSyncAttempts {
+		t.Fatalf("Status, Attempts = %s, %d, want failed after every attempt", sync.Status, sync.Attempts)
+	}
+
+	types := make([]string, 0, len(eventPublisher.events))
+	for _, event := range eventPublisher.events {
+		types = append(types, event.Type)
+	}
+	if !containsEventType(types, "accounting_sync.failed") {
+		t.Errorf("published %v, want accounting_sync.failed", types)
+	}
+
+	report, err := uc.GetReconciliation(ctx, tenantID)
+	if err != nil || len(report.Failed) != 1 {
+		t.Fatalf("GetReconciliation() = %+v, %v, want the failed sync", report, err)
+	}
+
+	// Once the account is fixed, the sync is pushed again by hand
+	connector.pushErr = nil
+	resynced, err := uc.Resync(ctx, tenantID, sync.ID)
+	if err != nil {
+		t.Fatalf("Resync() error = %v", err)
+	}
+	if resynced.Status != string(domain.AccountingSyncStatusSynced) || resynced.Attempts != 0 {
+		t.Errorf("Status, Attempts = %s, %d, want synced", resynced.Status, resynced.Attempts)
+	}
+
+	_, err = uc.Resync(ctx, tenantID, uuid.New())
+	if !application.IsNotFoundError(err) {
+		t.Errorf("Resync() of unknown sync error = %v, want not found", err)
+	}
+}
+
+func TestAccountingUseCase_ReceiveWebhook(t *testing.T) {
+	ctx := context.Background()
+	tenantID := uuid.New()
+	dealRepo := NewDealMockDealRepository()
+	syncRepo := NewMockAccountingSyncRepository()
+	eventPublisher := NewDealMockEventPublisher()
+	connector := &MockAccountingConnector{}
+	uc := NewAccountingUseCase(NewMockAccountingConnectionRepository(), syncRepo, dealRepo, eventPublisher, connector)
+
+	connection := createAccountingTestConnection(uc, t, tenantID)
+	deal, invoice := createAccountingTestDeal(tenantID)
+	dealRepo.deals[deal.ID] = deal
+	if _, err := uc.ResyncInvoice(ctx, tenantID, deal.ID, invoice.ID); err != nil {
+		t.Fatalf("ResyncInvoice() error = %v", err)
+	}
+
+	connector.updates = []ports.AccountingPaymentUpdate{{
+		ExternalID:        "XERO-PAY-1",
+		InvoiceExternalID: "XERO-INV-2026-0001",
+		Amount:            108000,
+		Currency:          "MYR",
+		Reference:         "FPX-778812",
+	}}
+
+	_, err := uc.ReceiveWebhook(ctx, tenantID, map[string]string{"X-Webhook-Signature": "forged"}, nil)
+	if appErr := application.GetAppError(err); appErr == nil || appErr.Code != application.ErrCodeUnauthorized {
+		t.Fatalf("ReceiveWebhook() with a bad signature error = %v, want %s", err, application.ErrCodeUnauthorized)
+	}
+
+	headers := map[string]string{"X-Webhook-Signature": connection.Secret}
+	result, err := uc.ReceiveWebhook(ctx, tenantID, headers, nil)
+	if err != nil {
+		t.Fatalf("ReceiveWebhook() error = %v", err)
+	}
+	if result.Received != 1 || result.Recorded != 1 {
+		t.Errorf("result = %+v, want the payment recorded", result)
+	}
+	if len(deal.Payments) != 1 || deal.Invoices[0].Status != "paid" {
+		t.Errorf("payments, invoice status = %d, %s, want the invoice paid", len(deal.Payments), deal.Invoices[0].Status)
+	}
+
+	// The payment came from the accounting system, so it is not pushed back
+	if synced, err := uc.ProcessDue(ctx, time.Now().UTC()); err != nil || synced != 0 || len(connector.payments) != 0 {
+		t.Errorf("ProcessDue() = %d, %v with %d payments pushed, want none", synced, err, len(connector.payments))
+	}
+
+	// Posting the same payment again records nothing
+	result, err = uc.ReceiveWebhook(ctx, tenantID, headers, nil)
+	if err != nil || result.Recorded != 0 || len(deal.Payments) != 1 {
+		t.Errorf("ReceiveWebhook() again = %+v, %v, want nothing recorded", result, err)
+	}
+}
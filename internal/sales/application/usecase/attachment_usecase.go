@@ -0,0 +1,552 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"mime"
+	"net/http"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/kilang-desa-murni/crm/internal/sales/application"
+	"github.com/kilang-desa-murni/crm/internal/sales/application/dto"
+	"github.com/kilang-desa-murni/crm/internal/sales/application/ports"
+	"github.com/kilang-desa-murni/crm/internal/sales/domain"
+)
+
+// ============================================================================
+// Attachment Use Case Interface
+// ============================================================================
+
+// AttachmentUseCase defines the interface for files attached to opportunities,
+// such as product and batik photos.
+type AttachmentUseCase interface {
+	// Upload attaches a file to an opportunity and announces it with a
+	// file.uploaded event, which triggers image variant generation and,
+	// with a virus scanner, the file's scan. Until scanned clean the file
+	// is quarantined.
+	Upload(ctx context.Context, tenantID, opportunityID, userID uuid.UUID, req *dto.UploadAttachmentRequest) (*dto.AttachmentResponse, error)
+
+	// List lists the files attached to an opportunity.
+	List(ctx context.Context, tenantID, opportunityID uuid.UUID) (*dto.AttachmentListResponse, error)
+
+	// Download returns an attached file, or one of its image variants. A
+	// variant that has not been generated yet is generated on the fly, as
+	// is the scan of a quarantined file.
+	Download(ctx context.Context, tenantID, opportunityID uuid.UUID, name, size string) (*dto.AttachmentFileResponse, error)
+
+	// GenerateVariants generates and stores every image variant of an
+	// attached file. Files that are not images, or are quarantined, are
+	// skipped.
+	GenerateVariants(ctx context.Context, tenantID uuid.UUID, fileID string) error
+
+	// ScanAttachment scans a quarantined file for viruses and announces the
+	// result with a file.scanned event. A clean file is released; an
+	// infected one is deleted and its uploader notified. Files already
+	// scanned are skipped.
+	ScanAttachment(ctx context.Context, tenantID uuid.UUID, fileID string) error
+}
+
+// ============================================================================
+// Attachment Use Case Implementation
+// ============================================================================
+
+// maxAttachmentSize is the largest file that can be attached.
+const maxAttachmentSize = 10 << 20
+
+// AttachmentScanConfig configures the virus scanning of attachments.
+type AttachmentScanConfig struct {
+	// DetectOnly records scan results without quarantining files or
+	// deleting infected ones, e.g. while trying out a scanner.
+	DetectOnly bool
+}
+
+// attachmentUseCase implements AttachmentUseCase.
+type attachmentUseCase struct {
+	opportunityRepo domain.OpportunityRepository
+	fileStorage     ports.FileStorageService
+	resizer         ports.ImageResizer
+	scanner         ports.VirusScanner
+	eventPublisher  ports.EventPublisher
+	notificationSvc ports.NotificationService
+	scanConfig      AttachmentScanConfig
+}
+
+// NewAttachmentUseCase creates a new attachment use case. Attachments are
+// scanned for viruses when scanner is set, and uploaders of infected files
+// are notified in-app when notificationSvc is set.
+func NewAttachmentUseCase(
+	opportunityRepo domain.OpportunityRepository,
+	fileStorage ports.FileStorageService,
+	resizer ports.ImageResizer,
+	scanner ports.VirusScanner,
+	eventPublisher ports.EventPublisher,
+	notificationSvc ports.NotificationService,
+	scanConfig AttachmentScanConfig,
+) AttachmentUseCase {
+	return &attachmentUseCase{
+		opportunityRepo: opportunityRepo,
+		fileStorage:     fileStorage,
+		resizer:         resizer,
+		scanner:         scanner,
+		eventPublisher:  eventPublisher,
+		notificationSvc: notificationSvc,
+		scanConfig:      scanConfig,
+	}
+}
+
+// Upload attaches a file to an opportunity.
+func (uc *attachmentUseCase) Upload(ctx context.Context, tenantID, opportunityID, userID uuid.UUID, req *dto.UploadAttachmentRequest) (*dto.AttachmentResponse, error) {
+	if uc.fileStorage == nil {
+		return nil, application.ErrServiceUnavailable("file storage")
+	}
+	if len(req.Content) == 0 {
+		return nil, application.ErrValidation("file is empty")
+	}
+	if len(req.Content) > maxAttachmentSize {
+		return nil, application.ErrValidationWithDetails("file is too large", map[string]interface{}{
+			"max_bytes": maxAttachmentSize,
+		})
+	}
+	filename := path.Base(strings.ReplaceAll(req.Filename, "\\", "/"))
+	if filename == "" || filename == "." || filename == "/" {
+		return nil, application.ErrValidation("filename is required")
+	}
+
+	if _, err := uc.opportunityRepo.GetByID(ctx, tenantID, opportunityID); err != nil {
+		return nil, application.ErrOpportunityNotFound(opportunityID)
+	}
+
+	// The declared type is only trusted when it agrees with the content
+	contentType := http.DetectContentType(req.Content)
+	if domain.IsThumbnailable(req.ContentType) && !domain.IsThumbnailable(contentType) {
+		return nil, application.ErrValidationWithDetails("file content does not match its content type", map[string]interface{}{
+			"content_type":  req.ContentType,
+			"detected_type": contentType,
+		})
+	}
+
+	info, err := uc.fileStorage.Upload(ctx, ports.FileUploadRequest{
+		TenantID:    tenantID,
+		EntityType:  domain.AttachmentEntityOpportunity,
+		EntityID:    opportunityID,
+		Filename:    filename,
+		ContentType: contentType,
+		Content:     req.Content,
+	})
+	if err != nil {
+		return nil, application.WrapError(application.ErrCodeInternal, "failed to store attachment", err)
+	}
+
+	// The file is quarantined before it is announced, so it cannot be
+	// downloaded or resized before it is scanned
+	var scan *domain.AttachmentScan
+	if uc.scanner != nil {
+		scan = domain.NewAttachmentScan(info.ID, filename, userID, uc.scanConfig.DetectOnly)
+		if err := uc.storeScan(ctx, tenantID, scan); err != nil {
+			_ = uc.fileStorage.Delete(ctx, tenantID, info.ID)
+			return nil, err
+		}
+	}
+
+	event := domain.NewFileUploadedEvent(tenantID, opportunityID, domain.AttachmentEntityOpportunity, info.ID, filename, contentType, info.Size)
+	uc.publishFileUploaded(ctx, event, userID)
+
+	info.ContentType = contentType
+	if info.UploadedAt.IsZero() {
+		info.UploadedAt = time.Now().UTC()
+	}
+	resp := mapAttachmentToResponse(info, scan)
+	resp.Filename = filename
+	return resp, nil
+}
+
+// List lists the files attached to an opportunity, newest first.
+func (uc *attachmentUseCase) List(ctx context.Context, tenantID, opportunityID uuid.UUID) (*dto.AttachmentListResponse, error) {
+	if uc.fileStorage == nil {
+		return nil, application.ErrServiceUnavailable("file storage")
+	}
+	if _, err := uc.opportunityRepo.GetByID(ctx, tenantID, opportunityID); err != nil {
+		return nil, application.ErrOpportunityNotFound(opportunityID)
+	}
+
+	files, err := uc.fileStorage.ListFiles(ctx, tenantID, domain.AttachmentEntityOpportunity, opportunityID)
+	if err != nil {
+		return nil, application.WrapError(application.ErrCodeInternal, "failed to list attachments", err)
+	}
+	sort.Slice(files, func(i, j int) bool {
+		return files[i].UploadedAt.After(files[j].UploadedAt)
+	})
+
+	resp := &dto.AttachmentListResponse{Attachments: make([]*dto.AttachmentResponse, len(files))}
+	for i, file := range files {
+		scan, err := uc.loadScan(ctx, tenantID, file.ID)
+		if err != nil {
+			return nil, err
+		}
+		resp.Attachments[i] = mapAttachmentToResponse(file, scan)
+	}
+	return resp, nil
+}
+
+// Download returns an attached file or one of its image variants.
+func (uc *attachmentUseCase) Download(ctx context.Context, tenantID, opportunityID uuid.UUID, name, size string) (*dto.AttachmentFileResponse, error) {
+	if uc.fileStorage == nil {
+		return nil, application.ErrServiceUnavailable("file storage")
+	}
+	if name == "" || strings.ContainsAny(name, "/\\") || name == "." || name == ".." {
+		return nil, application.ErrAttachmentNotFound(name)
+	}
+
+	imageSize := domain.ImageSizeOriginal
+	if size != "" {
+		imageSize = domain.ImageSize(size)
+		if !imageSize.IsValid() {
+			return nil, application.ErrValidationWithDetails("invalid size", map[string]interface{}{
+				"size":    size,
+				"allowed": []domain.ImageSize{domain.ImageSizeOriginal, domain.ImageSizeThumb, domain.ImageSizeMedium},
+			})
+		}
+	}
+
+	fileID := path.Join(domain.AttachmentEntityOpportunity, opportunityID.String(), name)
+	contentType := attachmentContentType(name)
+
+	if err := uc.checkQuarantine(ctx, tenantID, fileID, name); err != nil {
+		return nil, err
+	}
+
+	if imageSize == domain.ImageSizeOriginal {
+		content, err := uc.download(ctx, tenantID, fileID, name)
+		if err != nil {
+			return nil, err
+		}
+		return &dto.AttachmentFileResponse{FileName: attachmentFilename(name), ContentType: contentType, Content: content}, nil
+	}
+
+	if !domain.IsThumbnailable(contentType) {
+		return nil, application.ErrValidation(domain.ErrImageSizeUnsupported.Error())
+	}
+
+	variantFileName := strings.TrimSuffix(attachmentFilename(name), path.Ext(name)) + "-" + string(imageSize) + ".jpg"
+	content, err := uc.fileStorage.Download(ctx, tenantID, domain.ImageVariantFileID(fileID, imageSize))
+	if err == nil {
+		return &dto.AttachmentFileResponse{FileName: variantFileName, ContentType: "image/jpeg", Content: content}, nil
+	}
+	if !errors.Is(err, ports.ErrFileNotFound) {
+		return nil, application.WrapError(application.ErrCodeInternal, "failed to download attachment", err)
+	}
+
+	// The variant is not there yet, e.g. the worker is behind or was down
+	original, err := uc.download(ctx, tenantID, fileID, name)
+	if err != nil {
+		return nil, err
+	}
+	content, err = uc.generateVariant(ctx, tenantID, fileID, original, imageSize)
+	if err != nil {
+		return nil, err
+	}
+	return &dto.AttachmentFileResponse{FileName: variantFileName, ContentType: "image/jpeg", Content: content}, nil
+}
+
+// GenerateVariants generates and stores every image variant of an attached file.
+func (uc *attachmentUseCase) GenerateVariants(ctx context.Context, tenantID uuid.UUID, fileID string) error {
+	if uc.fileStorage == nil {
+		return application.ErrServiceUnavailable("file storage")
+	}
+	if !domain.IsThumbnailable(attachmentContentType(fileID)) {
+		return nil
+	}
+
+	// Quarantined files are resized once released
+	scan, err := uc.loadScan(ctx, tenantID, fileID)
+	if err != nil {
+		return err
+	}
+	if scan != nil && scan.IsQuarantined() {
+		return nil
+	}
+
+	original, err := uc.download(ctx, tenantID, fileID, path.Base(fileID))
+	if err != nil {
+		return err
+	}
+	for _, size := range domain.ImageVariantSizes {
+		if _, err := uc.generateVariant(ctx, tenantID, fileID, original, size); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ScanAttachment scans a quarantined file for viruses.
+func (uc *attachmentUseCase) ScanAttachment(ctx context.Context, tenantID uuid.UUID, fileID string) error {
+	if uc.fileStorage == nil {
+		return application.ErrServiceUnavailable("file storage")
+	}
+
+	scan, err := uc.loadScan(ctx, tenantID, fileID)
+	if err != nil {
+		return err
+	}
+	// Files uploaded without a scanner have no scan, and files downloaded
+	// before the event arrived were scanned then
+	if scan == nil || scan.Status != domain.ScanStatusPending {
+		return nil
+	}
+	return uc.scan(ctx, tenantID, scan)
+}
+
+// checkQuarantine refuses the download of a quarantined file. A file still
+// waiting for its scan, e.g. because the scan worker is behind or was down,
+// is scanned on the fly.
+func (uc *attachmentUseCase) checkQuarantine(ctx context.Context, tenantID uuid.UUID, fileID, name string) error {
+	scan, err := uc.loadScan(ctx, tenantID, fileID)
+	if err != nil {
+		return err
+	}
+	if scan == nil || !scan.IsQuarantined() {
+		return nil
+	}
+	if scan.Status == domain.ScanStatusPending {
+		if uc.scanner == nil {
+			return application.ErrAttachmentQuarantined(name)
+		}
+		if err := uc.scan(ctx, tenantID, scan); err != nil {
+			if application.IsNotFoundError(err) {
+				return application.ErrAttachmentNotFound(name)
+			}
+			return application.ErrAttachmentQuarantined(name)
+		}
+	}
+	if scan.IsRejected() {
+		return application.ErrAttachmentNotFound(name)
+	}
+	return nil
+}
+
+// scan scans a file, records the result and deletes the file when it is
+// rejected.
+func (uc *attachmentUseCase) scan(ctx context.Context, tenantID uuid.UUID, scan *domain.AttachmentScan) error {
+	if uc.scanner == nil {
+		return application.ErrServiceUnavailable("virus scanner")
+	}
+
+	content, err := uc.download(ctx, tenantID, scan.FileID, path.Base(scan.FileID))
+	if err != nil {
+		return err
+	}
+	signature, err := uc.scanner.Scan(ctx, content)
+	if err != nil {
+		return application.WrapError(application.ErrCodeServiceUnavailable, "failed to scan attachment", err)
+	}
+
+	released := scan.IsQuarantined()
+	scan.Record(uc.scanner.Name(), signature)
+	released = released && !scan.IsQuarantined()
+
+	// The scan is kept as the record of a rejected file
+	if err := uc.storeScan(ctx, tenantID, scan); err != nil {
+		return err
+	}
+	if scan.IsRejected() {
+		if err := uc.fileStorage.Delete(ctx, tenantID, scan.FileID); err != nil {
+			return application.WrapError(application.ErrCodeInternal, "failed to delete infected attachment", err)
+		}
+		for _, size := range domain.ImageVariantSizes {
+			_ = uc.fileStorage.Delete(ctx, tenantID, domain.ImageVariantFileID(scan.FileID, size))
+		}
+		uc.notifyInfected(ctx, tenantID, scan)
+	}
+
+	uc.publishFileScanned(ctx, tenantID, scan, released)
+	return nil
+}
+
+// loadScan loads the scan of a file, or nil when the file has none.
+func (uc *attachmentUseCase) loadScan(ctx context.Context, tenantID uuid.UUID, fileID string) (*domain.AttachmentScan, error) {
+	content, err := uc.fileStorage.Download(ctx, tenantID, domain.AttachmentScanFileID(fileID))
+	if err != nil {
+		if errors.Is(err, ports.ErrFileNotFound) {
+			return nil, nil
+		}
+		return nil, application.WrapError(application.ErrCodeInternal, "failed to load attachment scan", err)
+	}
+
+	var scan domain.AttachmentScan
+	if err := json.Unmarshal(content, &scan); err != nil {
+		return nil, application.WrapError(application.ErrCodeInternal, "failed to decode attachment scan", err)
+	}
+	return &scan, nil
+}
+
+func (uc *attachmentUseCase) storeScan(ctx context.Context, tenantID uuid.UUID, scan *domain.AttachmentScan) error {
+	content, err := json.Marshal(scan)
+	if err != nil {
+		return application.WrapError(application.ErrCodeInternal, "failed to encode attachment scan", err)
+	}
+	if err := uc.fileStorage.Store(ctx, tenantID, domain.AttachmentScanFileID(scan.FileID), content); err != nil {
+		return application.WrapError(application.ErrCodeInternal, "failed to store attachment scan", err)
+	}
+	return nil
+}
+
+// notifyInfected tells the uploader their file was rejected.
+func (uc *attachmentUseCase) notifyInfected(ctx context.Context, tenantID uuid.UUID, scan *domain.AttachmentScan) {
+	if uc.notificationSvc == nil || scan.UploadedBy == uuid.Nil {
+		return
+	}
+
+	_ = uc.notificationSvc.SendInApp(ctx, ports.InAppNotificationRequest{
+		TenantID: tenantID,
+		UserIDs:  []uuid.UUID{scan.UploadedBy},
+		Title:    "Attachment rejected",
+		Message:  fmt.Sprintf("%s was found to contain %s and has been deleted.", scan.Filename, scan.Signature),
+		Type:     "error",
+		Data: map[string]interface{}{
+			"file_id":   scan.FileID,
+			"filename":  scan.Filename,
+			"signature": scan.Signature,
+		},
+	})
+}
+
+// generateVariant resizes an original image and stores the variant.
+func (uc *attachmentUseCase) generateVariant(ctx context.Context, tenantID uuid.UUID, fileID string, original []byte, size domain.ImageSize) ([]byte, error) {
+	if uc.resizer == nil {
+		return nil, application.ErrServiceUnavailable("image resizer")
+	}
+
+	content, err := uc.resizer.Resize(original, size.MaxDimension())
+	if err != nil {
+		return nil, application.ErrValidation("attachment is not a readable image")
+	}
+	if err := uc.fileStorage.Store(ctx, tenantID, domain.ImageVariantFileID(fileID, size), content); err != nil {
+		return nil, application.WrapError(application.ErrCodeInternal, "failed to store image variant", err)
+	}
+	return content, nil
+}
+
+func (uc *attachmentUseCase) download(ctx context.Context, tenantID uuid.UUID, fileID, name string) ([]byte, error) {
+	content, err := uc.fileStorage.Download(ctx, tenantID, fileID)
+	if err != nil {
+		if errors.Is(err, ports.ErrFileNotFound) {
+			return nil, application.ErrAttachmentNotFound(name)
+		}
+		return nil, application.WrapError(application.ErrCodeInternal, "failed to download attachment", err)
+	}
+	return content, nil
+}
+
+func (uc *attachmentUseCase) publishFileUploaded(ctx context.Context, event *domain.FileUploadedEvent, userID uuid.UUID) {
+	if uc.eventPublisher == nil {
+		return
+	}
+
+	uc.eventPublisher.Publish(ctx, ports.Event{
+		ID:            event.EventID().String(),
+		Type:          event.EventType(),
+		AggregateID:   event.AggregateID().String(),
+		AggregateType: event.AggregateType(),
+		TenantID:      event.TenantID().String(),
+		Payload: map[string]interface{}{
+			"file_id":      event.FileID,
+			"filename":     event.Filename,
+			"content_type": event.ContentType,
+			"size":         event.Size,
+		},
+		Metadata:   map[string]string{"user_id": userID.String()},
+		OccurredAt: event.OccurredAt(),
+		Version:    event.Version(),
+	})
+}
+
+func (uc *attachmentUseCase) publishFileScanned(ctx context.Context, tenantID uuid.UUID, scan *domain.AttachmentScan, released bool) {
+	if uc.eventPublisher == nil {
+		return
+	}
+
+	entityID, err := uuid.Parse(path.Base(path.Dir(scan.FileID)))
+	if err != nil {
+		return
+	}
+	event := domain.NewFileScannedEvent(tenantID, entityID, domain.AttachmentEntityOpportunity, scan)
+
+	uc.eventPublisher.Publish(ctx, ports.Event{
+		ID:            event.EventID().String(),
+		Type:          event.EventType(),
+		AggregateID:   event.AggregateID().String(),
+		AggregateType: event.AggregateType(),
+		TenantID:      event.TenantID().String(),
+		Payload: map[string]interface{}{
+			"file_id":     event.FileID,
+			"filename":    event.Filename,
+			"uploaded_by": event.UploadedBy.String(),
+			"status":      string(event.Status),
+			"signature":   event.Signature,
+			"rejected":    event.Rejected,
+			// A released file was quarantined and can now be downloaded
+			"released": released,
+		},
+		OccurredAt: event.OccurredAt(),
+		Version:    event.Version(),
+	})
+}
+
+// ============================================================================
+// Helpers
+// ============================================================================
+
+// attachmentFilename strips the unique prefix storage adds to a stored file name.
+func attachmentFilename(name string) string {
+	if len(name) > 37 && name[36] == '-' {
+		if _, err := uuid.Parse(name[:36]); err == nil {
+			return name[37:]
+		}
+	}
+	return name
+}
+
+// attachmentContentType derives a stored file's content type from its extension.
+func attachmentContentType(name string) string {
+	if contentType := mime.TypeByExtension(strings.ToLower(path.Ext(name))); contentType != "" {
+		return contentType
+	}
+	return "application/octet-stream"
+}
+
+func mapAttachmentToResponse(file *ports.FileInfo, scan *domain.AttachmentScan) *dto.AttachmentResponse {
+	name := path.Base(file.ID)
+	contentType := file.ContentType
+	if contentType == "" {
+		contentType = attachmentContentType(name)
+	}
+
+	resp := &dto.AttachmentResponse{
+		Name:        name,
+		Filename:    attachmentFilename(name),
+		ContentType: contentType,
+		Size:        file.Size,
+		UploadedAt:  file.UploadedAt,
+	}
+	if domain.IsThumbnailable(contentType) {
+		for _, size := range domain.ImageVariantSizes {
+			resp.Variants = append(resp.Variants, string(size))
+		}
+	}
+	if scan != nil {
+		resp.Scan = &dto.AttachmentScanResponse{
+			Status:      string(scan.Status),
+			Signature:   scan.Signature,
+			Quarantined: scan.IsQuarantined(),
+			ScannedAt:   scan.ScannedAt,
+		}
+	}
+	return resp
+}
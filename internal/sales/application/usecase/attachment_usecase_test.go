@@ -0,0 +1,382 @@
+package usecase
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"image"
+	"image/png"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"github.com/kilang-desa-murni/crm/internal/sales/application"
+	"github.com/kilang-desa-murni/crm/internal/sales/application/dto"
+	"github.com/kilang-desa-murni/crm/internal/sales/domain"
+)
+
+// ============================================================================
+// Mock Implementations for Attachment Tests
+// ============================================================================
+
+// MockImageResizer records the sizes it was asked for and returns a marker
+// instead of a real image.
+type MockImageResizer struct {
+	calls []int
+}
+
+func (m *MockImageResizer) Resize(content []byte, maxDimension int) ([]byte, error) {
+	if _, _, err := image.DecodeConfig(bytes.NewReader(content)); err != nil {
+		return nil, errors.New("not an image")
+	}
+	m.calls = append(m.calls, maxDimension)
+	return []byte(fmt.Sprintf("resized-%d", maxDimension)), nil
+}
+
+// MockVirusScanner reports content containing "EICAR" as infected.
+type MockVirusScanner struct {
+	scans int
+	err   error
+}
+
+func (m *MockVirusScanner) Name() string {
+	return "mock"
+}
+
+func (m *MockVirusScanner) Scan(ctx context.Context, content []byte) (string, error) {
+	if m.err != nil {
+		return "", m.err
+	}
+	m.scans++
+	if bytes.Contains(content, []byte("EICAR")) {
+		return "Win.Test.EICAR_HDB-1", nil
+	}
+	return "", nil
+}
+
+type attachmentFixture struct {
+	uc            AttachmentUseCase
+	oppRepo       *ExtendedMockOpportunityRepository
+	storage       *MockFileStorageService
+	resizer       *MockImageResizer
+	scanner       *MockVirusScanner
+	publisher     *MockPipelineEventPublisher
+	notifications *MockReportNotificationService
+	tenantID      uuid.UUID
+	opp           *domain.Opportunity
+}
+
+func newAttachmentFixture() *attachmentFixture {
+	f := &attachmentFixture{
+		oppRepo:   NewExtendedMockOpportunityRepository(),
+		storage:   NewMockFileStorageService(),
+		resizer:   &MockImageResizer{},
+		publisher: NewMockPipelineEventPublisher(),
+		tenantID:  uuid.New(),
+	}
+	f.opp = &domain.Opportunity{ID: uuid.New(), TenantID: f.tenantID}
+	f.oppRepo.opportunities[f.opp.ID] = f.opp
+	f.uc = NewAttachmentUseCase(f.oppRepo, f.storage, f.resizer, nil, f.publisher, nil, AttachmentScanConfig{})
+	return f
+}
+
+// newScanningAttachmentFixture creates a fixture scanning attachments for viruses.
+func newScanningAttachmentFixture(detectOnly bool) *attachmentFixture {
+	f := newAttachmentFixture()
+	f.scanner = &MockVirusScanner{}
+	f.notifications = &MockReportNotificationService{}
+	f.uc = NewAttachmentUseCase(f.oppRepo, f.storage, f.resizer, f.scanner, f.publisher, f.notifications,
+		AttachmentScanConfig{DetectOnly: detectOnly})
+	return f
+}
+
+// storeQuarantined stores an attachment waiting for its scan.
+func (f *attachmentFixture) storeQuarantined(t *testing.T, name string, content []byte, detectOnly bool) string {
+	t.Helper()
+	fileID := f.storeOriginal(name, content)
+	scan, err := json.Marshal(domain.NewAttachmentScan(fileID, attachmentFilename(name), uuid.New(), detectOnly))
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.storage.files[domain.AttachmentScanFileID(fileID)] = scan
+	return fileID
+}
+
+func (f *attachmentFixture) storedScan(t *testing.T, fileID string) *domain.AttachmentScan {
+	t.Helper()
+	var scan domain.AttachmentScan
+	if err := json.Unmarshal(f.storage.files[domain.AttachmentScanFileID(fileID)], &scan); err != nil {
+		t.Fatalf("scan of %s was not stored: %v", fileID, err)
+	}
+	return &scan
+}
+
+// storeOriginal stores an attachment the way file storage names it.
+func (f *attachmentFixture) storeOriginal(name string, content []byte) string {
+	fileID := "opportunity/" + f.opp.ID.String() + "/" + name
+	f.storage.files[fileID] = content
+	return fileID
+}
+
+func testPNG(t *testing.T) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, image.NewRGBA(image.Rect(0, 0, 4, 2))); err != nil {
+		t.Fatalf("png.Encode() error = %v", err)
+	}
+	return buf.Bytes()
+}
+
+// ============================================================================
+// Attachment Use Case Tests
+// ============================================================================
+
+func TestAttachmentUseCase_Upload(t *testing.T) {
+	f := newAttachmentFixture()
+
+	resp, err := f.uc.Upload(context.Background(), f.tenantID, f.opp.ID, uuid.New(), &dto.UploadAttachmentRequest{
+		Filename:    "motif.png",
+		ContentType: "image/png",
+		Content:     testPNG(t),
+	})
+	if err != nil {
+		t.Fatalf("Upload() error = %v", err)
+	}
+	if resp.Filename != "motif.png" || resp.ContentType != "image/png" {
+		t.Errorf("Upload() = %+v, want motif.png as image/png", resp)
+	}
+	if len(resp.Variants) != len(domain.ImageVariantSizes) {
+		t.Errorf("Upload() variants = %v, want %v", resp.Variants, domain.ImageVariantSizes)
+	}
+
+	if len(f.publisher.events) != 1 {
+		t.Fatalf("published %d events, want 1", len(f.publisher.events))
+	}
+	event := f.publisher.events[0]
+	if event.Type != "file.uploaded" || event.AggregateID != f.opp.ID.String() {
+		t.Errorf("published %s for %s, want file.uploaded for %s", event.Type, event.AggregateID, f.opp.ID)
+	}
+	if event.Payload["file_id"] == "" {
+		t.Error("published event has no file_id")
+	}
+}
+
+func TestAttachmentUseCase_Upload_Errors(t *testing.T) {
+	f := newAttachmentFixture()
+	ctx := context.Background()
+
+	_, err := f.uc.Upload(ctx, f.tenantID, uuid.New(), uuid.New(), &dto.UploadAttachmentRequest{Filename: "a.png", Content: testPNG(t)})
+	if appErr := application.GetAppError(err); appErr == nil || appErr.Code != application.ErrCodeOpportunityNotFound {
+		t.Errorf("Upload() unknown opportunity error = %v, want %s", err, application.ErrCodeOpportunityNotFound)
+	}
+
+	_, err = f.uc.Upload(ctx, f.tenantID, f.opp.ID, uuid.New(), &dto.UploadAttachmentRequest{Filename: "a.png", ContentType: "image/png", Content: []byte("not a png")})
+	if !application.IsValidationError(err) {
+		t.Errorf("Upload() mismatched content error = %v, want validation error", err)
+	}
+
+	_, err = f.uc.Upload(ctx, f.tenantID, f.opp.ID, uuid.New(), &dto.UploadAttachmentRequest{Filename: "a.png"})
+	if !application.IsValidationError(err) {
+		t.Errorf("Upload() empty file error = %v, want validation error", err)
+	}
+}
+
+func TestAttachmentUseCase_GenerateVariants(t *testing.T) {
+	f := newAttachmentFixture()
+	fileID := f.storeOriginal(uuid.New().String()+"-motif.png", testPNG(t))
+
+	if err := f.uc.GenerateVariants(context.Background(), f.tenantID, fileID); err != nil {
+		t.Fatalf("GenerateVariants() error = %v", err)
+	}
+	for _, size := range domain.ImageVariantSizes {
+		if _, ok := f.storage.files[domain.ImageVariantFileID(fileID, size)]; !ok {
+			t.Errorf("variant %s was not stored", size)
+		}
+	}
+
+	// Files that are not images are skipped
+	docID := f.storeOriginal(uuid.New().String()+"-quote.pdf", []byte("%PDF-1.4"))
+	if err := f.uc.GenerateVariants(context.Background(), f.tenantID, docID); err != nil {
+		t.Fatalf("GenerateVariants() non-image error = %v", err)
+	}
+	if len(f.resizer.calls) != len(domain.ImageVariantSizes) {
+		t.Errorf("resizer called %d times, want %d", len(f.resizer.calls), len(domain.ImageVariantSizes))
+	}
+}
+
+func TestAttachmentUseCase_Download(t *testing.T) {
+	f := newAttachmentFixture()
+	ctx := context.Background()
+	original := testPNG(t)
+	name := uuid.New().String() + "-motif.png"
+	fileID := f.storeOriginal(name, original)
+
+	file, err := f.uc.Download(ctx, f.tenantID, f.opp.ID, name, "")
+	if err != nil {
+		t.Fatalf("Download() error = %v", err)
+	}
+	if !bytes.Equal(file.Content, original) || file.FileName != "motif.png" || file.ContentType != "image/png" {
+		t.Errorf("Download() = %s (%s), want the original motif.png", file.FileName, file.ContentType)
+	}
+
+	// A missing variant is generated on the fly and kept for next time
+	file, err = f.uc.Download(ctx, f.tenantID, f.opp.ID, name, "thumb")
+	if err != nil {
+		t.Fatalf("Download(thumb) error = %v", err)
+	}
+	if string(file.Content) != "resized-200" || file.ContentType != "image/jpeg" || file.FileName != "motif-thumb.jpg" {
+		t.Errorf("Download(thumb) = %s %q (%s), want generated thumbnail", file.FileName, file.Content, file.ContentType)
+	}
+	if _, ok := f.storage.files[domain.ImageVariantFileID(fileID, domain.ImageSizeThumb)]; !ok {
+		t.Error("lazily generated thumbnail was not stored")
+	}
+
+	if _, err := f.uc.Download(ctx, f.tenantID, f.opp.ID, name, "thumb"); err != nil {
+		t.Fatalf("Download(thumb) again error = %v", err)
+	}
+	if len(f.resizer.calls) != 1 {
+		t.Errorf("resizer called %d times, want the stored thumbnail to be reused", len(f.resizer.calls))
+	}
+}
+
+func TestAttachmentUseCase_Download_Errors(t *testing.T) {
+	f := newAttachmentFixture()
+	ctx := context.Background()
+	f.storeOriginal("quote.pdf", []byte("%PDF-1.4"))
+
+	tests := []struct {
+		name       string
+		file       string
+		size       string
+		validation bool
+		code       application.ErrorCode
+	}{
+		{name: "missing file", file: "missing.png", code: application.ErrCodeAttachmentNotFound},
+		{name: "path traversal", file: "../secret.png", code: application.ErrCodeAttachmentNotFound},
+		{name: "invalid size", file: "quote.pdf", size: "huge", validation: true},
+		{name: "variant of a non-image", file: "quote.pdf", size: "thumb", validation: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := f.uc.Download(ctx, f.tenantID, f.opp.ID, tt.file, tt.size)
+			if tt.validation {
+				if !application.IsValidationError(err) {
+					t.Errorf("Download() error = %v, want validation error", err)
+				}
+				return
+			}
+			if appErr := application.GetAppError(err); appErr == nil || appErr.Code != tt.code {
+				t.Errorf("Download() error = %v, want %s", err, tt.code)
+			}
+		})
+	}
+}
+
+func TestAttachmentUseCase_Upload_Quarantined(t *testing.T) {
+	f := newScanningAttachmentFixture(false)
+
+	resp, err := f.uc.Upload(context.Background(), f.tenantID, f.opp.ID, uuid.New(), &dto.UploadAttachmentRequest{
+		Filename: "quote.pdf",
+		Content:  []byte("%PDF-1.4"),
+	})
+	if err != nil {
+		t.Fatalf("Upload() error = %v", err)
+	}
+	if resp.Scan == nil || resp.Scan.Status != string(domain.ScanStatusPending) || !resp.Scan.Quarantined {
+		t.Errorf("Upload() scan = %+v, want pending and quarantined", resp.Scan)
+	}
+	if f.scanner.scans != 0 {
+		t.Error("Upload() scanned the file, want it scanned in the background")
+	}
+}
+
+func TestAttachmentUseCase_ScanAttachment(t *testing.T) {
+	f := newScanningAttachmentFixture(false)
+	ctx := context.Background()
+	photo := f.storeQuarantined(t, uuid.New().String()+"-motif.png", testPNG(t), false)
+	infected := f.storeQuarantined(t, uuid.New().String()+"-invoice.pdf", []byte("X5O!P%@AP EICAR"), false)
+
+	// Quarantined images are not resized
+	if err := f.uc.GenerateVariants(ctx, f.tenantID, photo); err != nil {
+		t.Fatalf("GenerateVariants() error = %v", err)
+	}
+	if len(f.resizer.calls) != 0 {
+		t.Error("GenerateVariants() resized a quarantined image")
+	}
+
+	if err := f.uc.ScanAttachment(ctx, f.tenantID, photo); err != nil {
+		t.Fatalf("ScanAttachment() error = %v", err)
+	}
+	if scan := f.storedScan(t, photo); scan.Status != domain.ScanStatusClean || scan.IsQuarantined() || scan.Scanner != "mock" {
+		t.Errorf("clean scan = %+v, want released", scan)
+	}
+	if last := f.publisher.events[len(f.publisher.events)-1]; last.Type != "file.scanned" || last.Payload["released"] != true {
+		t.Errorf("published %s %v, want file.scanned releasing the file", last.Type, last.Payload)
+	}
+
+	if err := f.uc.ScanAttachment(ctx, f.tenantID, infected); err != nil {
+		t.Fatalf("ScanAttachment() infected error = %v", err)
+	}
+	if _, ok := f.storage.files[infected]; ok {
+		t.Error("infected attachment was not deleted")
+	}
+	if scan := f.storedScan(t, infected); scan.Status != domain.ScanStatusInfected || scan.Signature != "Win.Test.EICAR_HDB-1" {
+		t.Errorf("infected scan = %+v, want the signature recorded", scan)
+	}
+	if len(f.notifications.inApp) != 1 || f.notifications.inApp[0].UserIDs[0] != f.storedScan(t, infected).UploadedBy {
+		t.Errorf("notifications = %+v, want the uploader notified", f.notifications.inApp)
+	}
+
+	// Scanned files are not scanned again
+	if err := f.uc.ScanAttachment(ctx, f.tenantID, photo); err != nil || f.scanner.scans != 2 {
+		t.Errorf("ScanAttachment() again = %v after %d scans, want it skipped", err, f.scanner.scans)
+	}
+}
+
+func TestAttachmentUseCase_Download_Quarantined(t *testing.T) {
+	f := newScanningAttachmentFixture(false)
+	ctx := context.Background()
+	name := uuid.New().String() + "-quote.pdf"
+	f.storeQuarantined(t, name, []byte("%PDF-1.4"), false)
+	infectedName := uuid.New().String() + "-invoice.pdf"
+	f.storeQuarantined(t, infectedName, []byte("EICAR"), false)
+
+	// The scan worker is down: the scanner cannot be reached either
+	f.scanner.err = errors.New("connection refused")
+	_, err := f.uc.Download(ctx, f.tenantID, f.opp.ID, name, "")
+	if appErr := application.GetAppError(err); appErr == nil || appErr.Code != application.ErrCodeAttachmentQuarantined {
+		t.Errorf("Download() error = %v, want %s", err, application.ErrCodeAttachmentQuarantined)
+	}
+
+	// Files still waiting are scanned on the fly
+	f.scanner.err = nil
+	if _, err := f.uc.Download(ctx, f.tenantID, f.opp.ID, name, ""); err != nil {
+		t.Errorf("Download() error = %v, want the file scanned and served", err)
+	}
+	_, err = f.uc.Download(ctx, f.tenantID, f.opp.ID, infectedName, "")
+	if appErr := application.GetAppError(err); appErr == nil || appErr.Code != application.ErrCodeAttachmentNotFound {
+		t.Errorf("Download() infected error = %v, want %s", err, application.ErrCodeAttachmentNotFound)
+	}
+}
+
+func TestAttachmentUseCase_ScanAttachment_DetectOnly(t *testing.T) {
+	f := newScanningAttachmentFixture(true)
+	ctx := context.Background()
+	name := uuid.New().String() + "-invoice.pdf"
+	fileID := f.storeQuarantined(t, name, []byte("EICAR"), true)
+
+	if err := f.uc.ScanAttachment(ctx, f.tenantID, fileID); err != nil {
+		t.Fatalf("ScanAttachment() error = %v", err)
+	}
+	if scan := f.storedScan(t, fileID); scan.Status != domain.ScanStatusInfected {
+		t.Errorf("scan = %+v, want infected recorded", scan)
+	}
+	if _, err := f.uc.Download(ctx, f.tenantID, f.opp.ID, name, ""); err != nil {
+		t.Errorf("Download() error = %v, want the file kept in detect-only mode", err)
+	}
+	if len(f.notifications.inApp) != 0 {
+		t.Error("uploader notified of a file that was not rejected")
+	}
+}
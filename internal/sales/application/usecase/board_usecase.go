@@ -0,0 +1,251 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/kilang-desa-murni/crm/internal/sales/application"
+	"github.com/kilang-desa-murni/crm/internal/sales/application/dto"
+	"github.com/kilang-desa-murni/crm/internal/sales/domain"
+)
+
+// ============================================================================
+// Board Use Case Interface
+// ============================================================================
+
+// BoardUseCase defines the interface for the opportunity board read model.
+type BoardUseCase interface {
+	// GetBoard returns a pipeline's board from the read model.
+	GetBoard(ctx context.Context, tenantID, pipelineID uuid.UUID, req *dto.GetBoardRequest) (*dto.BoardResponse, error)
+
+	// ProjectOpportunity brings an opportunity's card up to date with the
+	// opportunity, removing the card if the opportunity no longer exists.
+	ProjectOpportunity(ctx context.Context, tenantID, opportunityID uuid.UUID) error
+
+	// RebuildBoard rebuilds every card of a pipeline from its opportunities.
+	RebuildBoard(ctx context.Context, tenantID, pipelineID uuid.UUID) (*dto.BoardRebuildResponse, error)
+}
+
+// ============================================================================
+// Board Use Case Implementation
+// ============================================================================
+
+const (
+	defaultBoardColumnSize = 20
+	maxBoardColumnSize     = 100
+)
+
+// boardUseCase implements BoardUseCase.
+type boardUseCase struct {
+	boardRepo       domain.OpportunityBoardRepository
+	pipelineRepo    domain.PipelineRepository
+	opportunityRepo domain.OpportunityRepository
+}
+
+// NewBoardUseCase creates a new board use case.
+func NewBoardUseCase(
+	boardRepo domain.OpportunityBoardRepository,
+	pipelineRepo domain.PipelineRepository,
+	opportunityRepo domain.OpportunityRepository,
+) BoardUseCase {
+	return &boardUseCase{
+		boardRepo:       boardRepo,
+		pipelineRepo:    pipelineRepo,
+		opportunityRepo: opportunityRepo,
+	}
+}
+
+// GetBoard returns a pipeline's board from the read model. Columns follow the
+// order of the pipeline's active stages.
+func (uc *boardUseCase) GetBoard(ctx context.Context, tenantID, pipelineID uuid.UUID, req *dto.GetBoardRequest) (*dto.BoardResponse, error) {
+	limit := req.Limit
+	if limit < 1 {
+		limit = defaultBoardColumnSize
+	}
+	if limit > maxBoardColumnSize {
+		limit = maxBoardColumnSize
+	}
+
+	var stageID *uuid.UUID
+	if req.StageID != "" {
+		id, err := uuid.Parse(req.StageID)
+		if err != nil {
+			return nil, application.ErrValidationWithDetails("invalid stage_id", map[string]interface{}{
+				"stage_id": req.StageID,
+			})
+		}
+		stageID = &id
+	}
+
+	var cursor *domain.BoardCursor
+	if req.Cursor != "" {
+		if stageID == nil {
+			return nil, application.ErrValidation("cursor requires stage_id")
+		}
+		decoded, err := domain.DecodeBoardCursor(req.Cursor)
+		if err != nil {
+			return nil, application.ErrValidation("invalid cursor")
+		}
+		cursor = &decoded
+	}
+
+	pipeline, err := uc.pipelineRepo.GetByID(ctx, tenantID, pipelineID)
+	if err != nil {
+		if errors.Is(err, domain.ErrPipelineNotFound) {
+			return nil, application.ErrPipelineNotFound(pipelineID)
+		}
+		return nil, application.WrapError(application.ErrCodeInternal, "failed to get pipeline", err)
+	}
+
+	stages := pipeline.GetActiveStages()
+	if stageID != nil {
+		stage := pipeline.GetStage(*stageID)
+		if stage == nil {
+			return nil, application.ErrOpportunityStageNotFound(*stageID)
+		}
+		stages = []*domain.Stage{stage}
+	}
+
+	totals, err := uc.boardRepo.GetColumnTotals(ctx, tenantID, pipelineID)
+	if err != nil {
+		return nil, application.WrapError(application.ErrCodeInternal, "failed to get board totals", err)
+	}
+	totalsByStage := make(map[uuid.UUID][]*domain.BoardColumnTotal)
+	for _, total := range totals {
+		totalsByStage[total.StageID] = append(totalsByStage[total.StageID], total)
+	}
+
+	now := time.Now().UTC()
+	resp := &dto.BoardResponse{
+		PipelineID:   pipeline.ID.String(),
+		PipelineName: pipeline.Name,
+		Currency:     pipeline.Currency,
+		Columns:      make([]*dto.BoardColumnResponse, 0, len(stages)),
+	}
+	for _, stage := range stages {
+		// One extra card tells whether the column continues
+		cards, err := uc.boardRepo.ListColumnCards(ctx, tenantID, pipelineID, stage.ID, cursor, limit+1)
+		if err != nil {
+			return nil, application.WrapError(application.ErrCodeInternal, "failed to list board cards", err)
+		}
+
+		column := &dto.BoardColumnResponse{
+			StageID:     stage.ID.String(),
+			Name:        stage.Name,
+			Type:        string(stage.Type),
+			Order:       stage.Order,
+			Color:       stage.Color,
+			Probability: stage.Probability,
+			WIPLimit:    stage.WIPLimit,
+			Totals:      make([]dto.BoardTotalDTO, 0, len(totalsByStage[stage.ID])),
+		}
+		for _, total := range totalsByStage[stage.ID] {
+			column.Count += total.Count
+			column.Totals = append(column.Totals, dto.BoardTotalDTO{
+				Count:          total.Count,
+				Amount:         moneyToDTO(domain.Money{Amount: total.Amount, Currency: total.Currency}),
+				WeightedAmount: moneyToDTO(domain.Money{Amount: total.WeightedAmount, Currency: total.Currency}),
+			})
+		}
+		column.WIPStatus = string(stage.WIPStatus(column.Count))
+
+		if len(cards) > limit {
+			cards = cards[:limit]
+			column.NextCursor = domain.NewBoardCursor(cards[len(cards)-1]).Encode()
+		}
+		column.Cards = make([]*dto.BoardCardResponse, len(cards))
+		for i, card := range cards {
+			column.Cards[i] = mapBoardCardToResponse(card, stage, now)
+		}
+
+		resp.Columns = append(resp.Columns, column)
+	}
+
+	return resp, nil
+}
+
+// ProjectOpportunity brings an opportunity's card up to date. The card is
+// rebuilt from the opportunity rather than from the event, so a missed or
+// repeated event is corrected by the next one.
+func (uc *boardUseCase) ProjectOpportunity(ctx context.Context, tenantID, opportunityID uuid.UUID) error {
+	opportunity, err := uc.opportunityRepo.GetByID(ctx, tenantID, opportunityID)
+	if err != nil {
+		if errors.Is(err, domain.ErrOpportunityNotFound) {
+			if err := uc.boardRepo.DeleteCard(ctx, tenantID, opportunityID); err != nil {
+				return application.WrapError(application.ErrCodeInternal, "failed to remove board card", err)
+			}
+			return nil
+		}
+		return application.WrapError(application.ErrCodeInternal, "failed to get opportunity", err)
+	}
+
+	if err := uc.boardRepo.UpsertCard(ctx, domain.NewOpportunityBoardCard(opportunity)); err != nil {
+		return application.WrapError(application.ErrCodeInternal, "failed to update board card", err)
+	}
+	return nil
+}
+
+// RebuildBoard rebuilds every card of a pipeline from its opportunities.
+func (uc *boardUseCase) RebuildBoard(ctx context.Context, tenantID, pipelineID uuid.UUID) (*dto.BoardRebuildResponse, error) {
+	if _, err := uc.pipelineRepo.GetByID(ctx, tenantID, pipelineID); err != nil {
+		if errors.Is(err, domain.ErrPipelineNotFound) {
+			return nil, application.ErrPipelineNotFound(pipelineID)
+		}
+		return nil, application.WrapError(application.ErrCodeInternal, "failed to get pipeline", err)
+	}
+
+	var cards []*domain.OpportunityBoardCard
+	opts := domain.ListOptions{Page: 1, PageSize: 100, SortBy: "created_at", SortOrder: "asc"}
+	for {
+		opportunities, total, err := uc.opportunityRepo.GetByPipeline(ctx, tenantID, pipelineID, opts)
+		if err != nil {
+			return nil, application.WrapError(application.ErrCodeInternal, "failed to list pipeline opportunities", err)
+		}
+		for _, opportunity := range opportunities {
+			cards = append(cards, domain.NewOpportunityBoardCard(opportunity))
+		}
+		if len(opportunities) == 0 || int64(len(cards)) >= total {
+			break
+		}
+		opts.Page++
+	}
+
+	if err := uc.boardRepo.ReplacePipelineCards(ctx, tenantID, pipelineID, cards); err != nil {
+		return nil, application.WrapError(application.ErrCodeInternal, "failed to rebuild board", err)
+	}
+
+	return &dto.BoardRebuildResponse{
+		PipelineID: pipelineID.String(),
+		Cards:      len(cards),
+		RebuiltAt:  time.Now().UTC(),
+	}, nil
+}
+
+// ============================================================================
+// Helpers
+// ============================================================================
+
+func mapBoardCardToResponse(card *domain.OpportunityBoardCard, stage *domain.Stage, now time.Time) *dto.BoardCardResponse {
+	daysInStage := int(now.Sub(card.StageEnteredAt).Hours() / 24)
+
+	return &dto.BoardCardResponse{
+		ID:                card.OpportunityID.String(),
+		Code:              card.Code,
+		Name:              card.Name,
+		Status:            string(card.Status),
+		Priority:          string(card.Priority),
+		CustomerName:      card.CustomerName,
+		OwnerID:           card.OwnerID.String(),
+		OwnerName:         card.OwnerName,
+		Amount:            moneyToDTO(domain.Money{Amount: card.Amount, Currency: card.Currency}),
+		WeightedAmount:    moneyToDTO(domain.Money{Amount: card.WeightedAmount, Currency: card.Currency}),
+		Probability:       card.Probability,
+		ExpectedCloseDate: card.ExpectedCloseDate,
+		StageEnteredAt:    card.StageEnteredAt,
+		DaysInStage:       daysInStage,
+		IsRotten:          card.Status == domain.OpportunityStatusOpen && stage.RottenDays > 0 && daysInStage >= stage.RottenDays,
+	}
+}
@@ -0,0 +1,232 @@
+package usecase
+
+import (
+	"context"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/kilang-desa-murni/crm/internal/sales/application"
+	"github.com/kilang-desa-murni/crm/internal/sales/application/dto"
+	"github.com/kilang-desa-murni/crm/internal/sales/domain"
+)
+
+// ============================================================================
+// Mock Implementations for Board Tests
+// ============================================================================
+
+// MockBoardRepository is a mock implementation of domain.OpportunityBoardRepository.
+type MockBoardRepository struct {
+	cards map[uuid.UUID]*domain.OpportunityBoardCard
+}
+
+func NewMockBoardRepository() *MockBoardRepository {
+	return &MockBoardRepository{cards: make(map[uuid.UUID]*domain.OpportunityBoardCard)}
+}
+
+func (m *MockBoardRepository) UpsertCard(ctx context.Context, card *domain.OpportunityBoardCard) error {
+	if existing, ok := m.cards[card.OpportunityID]; ok && existing.Version > card.Version {
+		return nil
+	}
+	m.cards[card.OpportunityID] = card
+	return nil
+}
+
+func (m *MockBoardRepository) DeleteCard(ctx context.Context, tenantID, opportunityID uuid.UUID) error {
+	delete(m.cards, opportunityID)
+	return nil
+}
+
+func (m *MockBoardRepository) ListColumnCards(ctx context.Context, tenantID, pipelineID, stageID uuid.UUID, after *domain.BoardCursor, limit int) ([]*domain.OpportunityBoardCard, error) {
+	var cards []*domain.OpportunityBoardCard
+	for _, card := range m.cards {
+		if card.TenantID == tenantID && card.PipelineID == pipelineID && card.StageID == stageID {
+			cards = append(cards, card)
+		}
+	}
+	sort.Slice(cards, func(i, j int) bool {
+		return cards[i].BoardPosition < cards[j].BoardPosition
+	})
+	if after != nil {
+		for i, card := range cards {
+			if card.OpportunityID == after.OpportunityID {
+				cards = cards[i+1:]
+				break
+			}
+		}
+	}
+	if len(cards) > limit {
+		cards = cards[:limit]
+	}
+	return cards, nil
+}
+
+func (m *MockBoardRepository) GetColumnTotals(ctx context.Context, tenantID, pipelineID uuid.UUID) ([]*domain.BoardColumnTotal, error) {
+	totals := make(map[uuid.UUID]*domain.BoardColumnTotal)
+	for _, card := range m.cards {
+		if card.TenantID != tenantID || card.PipelineID != pipelineID {
+			continue
+		}
+		total, ok := totals[card.StageID]
+		if !ok {
+			total = &domain.BoardColumnTotal{StageID: card.StageID, Currency: card.Currency}
+			totals[card.StageID] = total
+		}
+		total.Count++
+		total.Amount += card.Amount
+		total.WeightedAmount += card.WeightedAmount
+	}
+	result := make([]*domain.BoardColumnTotal, 0, len(totals))
+	for _, total := range totals {
+		result = append(result, total)
+	}
+	return result, nil
+}
+
+func (m *MockBoardRepository) ReplacePipelineCards(ctx context.Context, tenantID, pipelineID uuid.UUID, cards []*domain.OpportunityBoardCard) error {
+	for id, card := range m.cards {
+		if card.TenantID == tenantID && card.PipelineID == pipelineID {
+			delete(m.cards, id)
+		}
+	}
+	for _, card := range cards {
+		m.cards[card.OpportunityID] = card
+	}
+	return nil
+}
+
+func newBoardTestCard(tenantID, pipelineID, stageID uuid.UUID, amount int64, enteredAt time.Time) *domain.OpportunityBoardCard {
+	return &domain.OpportunityBoardCard{
+		OpportunityID:  uuid.New(),
+		TenantID:       tenantID,
+		PipelineID:     pipelineID,
+		StageID:        stageID,
+		Status:         domain.OpportunityStatusOpen,
+		Amount:         amount,
+		WeightedAmount: amount / 10,
+		Currency:       "MYR",
+		StageEnteredAt: enteredAt,
+		BoardPosition:  domain.DefaultBoardPosition(enteredAt),
+	}
+}
+
+// ============================================================================
+// Board Use Case Tests
+// ============================================================================
+
+func TestBoardUseCase_GetBoard(t *testing.T) {
+	tenantID := uuid.New()
+	pipeline, _ := domain.NewPipeline(tenantID, "Sales", "MYR", uuid.New())
+	pipelineRepo := NewMockPipelineRepository()
+	pipelineRepo.pipelines[pipeline.ID] = pipeline
+	boardRepo := NewMockBoardRepository()
+
+	first := pipeline.GetActiveStages()[0]
+	first.WIPLimit = 2
+	now := time.Now().UTC()
+	for i := 0; i < 3; i++ {
+		boardRepo.UpsertCard(context.Background(), newBoardTestCard(tenantID, pipeline.ID, first.ID, 1000, now.Add(-time.Duration(i)*time.Hour)))
+	}
+
+	uc := NewBoardUseCase(boardRepo, pipelineRepo, NewMockOpportunityRepository())
+
+	resp, err := uc.GetBoard(context.Background(), tenantID, pipeline.ID, &dto.GetBoardRequest{Limit: 2})
+	if err != nil {
+		t.Fatalf("GetBoard() error = %v", err)
+	}
+	if len(resp.Columns) != len(pipeline.GetActiveStages()) {
+		t.Fatalf("GetBoard() columns = %d, want %d", len(resp.Columns), len(pipeline.GetActiveStages()))
+	}
+
+	column := resp.Columns[0]
+	if column.StageID != first.ID.String() || column.Count != 3 || len(column.Cards) != 2 || column.NextCursor == "" {
+		t.Fatalf("GetBoard() first column = stage %s, count %d, %d cards, cursor %q; want stage %s, count 3, 2 cards and a cursor",
+			column.StageID, column.Count, len(column.Cards), column.NextCursor, first.ID)
+	}
+	if len(column.Totals) != 1 || column.Totals[0].Amount.Amount != 3000 {
+		t.Errorf("GetBoard() first column totals = %+v, want 3000 MYR", column.Totals)
+	}
+	if column.WIPLimit != 2 || column.WIPStatus != string(domain.StageWIPStatusOver) {
+		t.Errorf("GetBoard() first column WIP = limit %d, status %s; want limit 2, over", column.WIPLimit, column.WIPStatus)
+	}
+	if status := resp.Columns[1].WIPStatus; status != string(domain.StageWIPStatusUnlimited) {
+		t.Errorf("GetBoard() second column WIP status = %s, want unlimited", status)
+	}
+
+	next, err := uc.GetBoard(context.Background(), tenantID, pipeline.ID, &dto.GetBoardRequest{
+		StageID: first.ID.String(),
+		Cursor:  column.NextCursor,
+		Limit:   2,
+	})
+	if err != nil {
+		t.Fatalf("GetBoard() next page error = %v", err)
+	}
+	if len(next.Columns) != 1 || len(next.Columns[0].Cards) != 1 || next.Columns[0].NextCursor != "" {
+		t.Errorf("GetBoard() next page = %d columns, want 1 column with 1 card and no cursor", len(next.Columns))
+	}
+}
+
+func TestBoardUseCase_GetBoard_Validation(t *testing.T) {
+	tenantID := uuid.New()
+	pipeline, _ := domain.NewPipeline(tenantID, "Sales", "MYR", uuid.New())
+	pipelineRepo := NewMockPipelineRepository()
+	pipelineRepo.pipelines[pipeline.ID] = pipeline
+	uc := NewBoardUseCase(NewMockBoardRepository(), pipelineRepo, NewMockOpportunityRepository())
+
+	tests := []struct {
+		name string
+		req  *dto.GetBoardRequest
+		code application.ErrorCode
+	}{
+		{"cursor without stage", &dto.GetBoardRequest{Cursor: "abc"}, application.ErrCodeValidation},
+		{"invalid cursor", &dto.GetBoardRequest{StageID: uuid.New().String(), Cursor: "!!"}, application.ErrCodeValidation},
+		{"invalid stage_id", &dto.GetBoardRequest{StageID: "nope"}, application.ErrCodeValidation},
+		{"unknown stage", &dto.GetBoardRequest{StageID: uuid.New().String()}, application.ErrCodeOpportunityStageNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := uc.GetBoard(context.Background(), tenantID, pipeline.ID, tt.req)
+			if appErr := application.GetAppError(err); appErr == nil || appErr.Code != tt.code {
+				t.Errorf("GetBoard() error = %v, want code %s", err, tt.code)
+			}
+		})
+	}
+}
+
+func TestBoardUseCase_ProjectOpportunity(t *testing.T) {
+	tenantID := uuid.New()
+	boardRepo := NewMockBoardRepository()
+	oppRepo := NewMockOpportunityRepository()
+	uc := NewBoardUseCase(boardRepo, NewMockPipelineRepository(), oppRepo)
+
+	opp := &domain.Opportunity{
+		ID:         uuid.New(),
+		TenantID:   tenantID,
+		PipelineID: uuid.New(),
+		StageID:    uuid.New(),
+		Name:       "Batik order",
+		Status:     domain.OpportunityStatusOpen,
+		Amount:     domain.Money{Amount: 5000, Currency: "MYR"},
+		Version:    2,
+	}
+	oppRepo.opportunities[opp.ID] = opp
+
+	if err := uc.ProjectOpportunity(context.Background(), tenantID, opp.ID); err != nil {
+		t.Fatalf("ProjectOpportunity() error = %v", err)
+	}
+	card, ok := boardRepo.cards[opp.ID]
+	if !ok || card.Amount != 5000 || card.StageID != opp.StageID {
+		t.Fatalf("ProjectOpportunity() card = %+v, want card for the opportunity", card)
+	}
+
+	oppRepo.getByIDErr = domain.ErrOpportunityNotFound
+	if err := uc.ProjectOpportunity(context.Background(), tenantID, opp.ID); err != nil {
+		t.Fatalf("ProjectOpportunity() deleted opportunity error = %v", err)
+	}
+	if _, ok := boardRepo.cards[opp.ID]; ok {
+		t.Error("ProjectOpportunity() kept the card of a deleted opportunity")
+	}
+}
@@ -0,0 +1,458 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sort"
+
+	"github.com/google/uuid"
+
+	"github.com/kilang-desa-murni/crm/internal/sales/application"
+	"github.com/kilang-desa-murni/crm/internal/sales/application/dto"
+	"github.com/kilang-desa-murni/crm/internal/sales/application/ports"
+	"github.com/kilang-desa-murni/crm/internal/sales/domain"
+)
+
+// ============================================================================
+// Comment Use Case Interface
+// ============================================================================
+
+// CommentUseCase defines the interface for comment threads on leads,
+// opportunities and customers. Mentioned users are notified through the
+// comment mentioned event.
+type CommentUseCase interface {
+	// Create comments on a record, or replies to a thread on it.
+	Create(ctx context.Context, tenantID, userID uuid.UUID, entityType string, entityID uuid.UUID, req *dto.CreateCommentRequest) (*dto.CommentResponse, error)
+
+	// List lists the threads on a record, newest first.
+	List(ctx context.Context, tenantID uuid.UUID, entityType string, entityID uuid.UUID, req *dto.ListCommentsRequest) (*dto.CommentListResponse, error)
+
+	// ListReplies lists the replies of a thread, oldest first.
+	ListReplies(ctx context.Context, tenantID uuid.UUID, entityType string, entityID, commentID uuid.UUID, req *dto.ListCommentsRequest) (*dto.CommentListResponse, error)
+
+	// Update edits a comment of the user.
+	Update(ctx context.Context, tenantID, userID uuid.UUID, entityType string, entityID, commentID uuid.UUID, req *dto.UpdateCommentRequest) (*dto.CommentResponse, error)
+
+	// Delete deletes a comment of the user, or any comment for moderators.
+	Delete(ctx context.Context, tenantID, userID uuid.UUID, entityType string, entityID, commentID uuid.UUID, moderator bool) error
+
+	// GetHistory returns the bodies a comment had before it was edited or
+	// deleted.
+	GetHistory(ctx context.Context, tenantID uuid.UUID, entityType string, entityID, commentID uuid.UUID) (*dto.CommentHistoryResponse, error)
+
+	// AddReaction adds the user's reaction to a comment.
+	AddReaction(ctx context.Context, tenantID, userID uuid.UUID, entityType string, entityID, commentID uuid.UUID, req *dto.CommentReactionRequest) (*dto.CommentResponse, error)
+
+	// RemoveReaction removes the user's reaction from a comment.
+	RemoveReaction(ctx context.Context, tenantID, userID uuid.UUID, entityType string, entityID, commentID uuid.UUID, reaction string) (*dto.CommentResponse, error)
+}
+
+// ============================================================================
+// Comment Use Case Implementation
+// ============================================================================
+
+// commentUseCase implements CommentUseCase.
+type commentUseCase struct {
+	commentRepo     domain.CommentRepository
+	leadRepo        domain.LeadRepository
+	opportunityRepo domain.OpportunityRepository
+	customerService ports.CustomerService
+	eventPublisher  ports.EventPublisher
+}
+
+// NewCommentUseCase creates a new comment use case. Customers are only
+// checked to exist when customerService is set.
+func NewCommentUseCase(
+	commentRepo domain.CommentRepository,
+	leadRepo domain.LeadRepository,
+	opportunityRepo domain.OpportunityRepository,
+	customerService ports.CustomerService,
+	eventPublisher ports.EventPublisher,
+) CommentUseCase {
+	return &commentUseCase{
+		commentRepo:     commentRepo,
+		leadRepo:        leadRepo,
+		opportunityRepo: opportunityRepo,
+		customerService: customerService,
+		eventPublisher:  eventPublisher,
+	}
+}
+
+// Create comments on a record. A reply to a reply joins the thread of the
+// comment replied to.
+func (uc *commentUseCase) Create(ctx context.Context, tenantID, userID uuid.UUID, entityType string, entityID uuid.UUID, req *dto.CreateCommentRequest) (*dto.CommentResponse, error) {
+	commentEntity, err := uc.findEntity(ctx, tenantID, entityType, entityID)
+	if err != nil {
+		return nil, err
+	}
+
+	var parent *domain.Comment
+	if req.ParentID != nil {
+		parentID, err := uuid.Parse(*req.ParentID)
+		if err != nil {
+			return nil, application.ErrValidation("invalid parent_id")
+		}
+		if parent, err = uc.getComment(ctx, tenantID, commentEntity, entityID, parentID); err != nil {
+			return nil, err
+		}
+	}
+
+	comment, err := domain.NewComment(tenantID, commentEntity, entityID, userID, req.Body, parent)
+	if err != nil {
+		if errors.Is(err, domain.ErrCommentDeleted) {
+			return nil, application.ErrCommentDeleted(parent.ID)
+		}
+		return nil, application.ErrValidation(err.Error())
+	}
+
+	if err := uc.commentRepo.Create(ctx, comment); err != nil {
+		return nil, application.WrapError(application.ErrCodeInternal, "failed to create comment", err)
+	}
+
+	uc.publishMentions(ctx, comment, comment.Mentions)
+
+	return mapCommentToResponse(comment), nil
+}
+
+// List lists the threads on a record, newest first, with their reply counts.
+func (uc *commentUseCase) List(ctx context.Context, tenantID uuid.UUID, entityType string, entityID uuid.UUID, req *dto.ListCommentsRequest) (*dto.CommentListResponse, error) {
+	commentEntity, err := uc.findEntity(ctx, tenantID, entityType, entityID)
+	if err != nil {
+		return nil, err
+	}
+
+	opts := commentListOptions(req)
+	comments, total, err := uc.commentRepo.ListThreads(ctx, tenantID, commentEntity, entityID, opts)
+	if err != nil {
+		return nil, application.WrapError(application.ErrCodeInternal, "failed to list comments", err)
+	}
+	return mapCommentsToListResponse(comments, opts, total), nil
+}
+
+// ListReplies lists the replies of a thread, oldest first, so they read as
+// a conversation.
+func (uc *commentUseCase) ListReplies(ctx context.Context, tenantID uuid.UUID, entityType string, entityID, commentID uuid.UUID, req *dto.ListCommentsRequest) (*dto.CommentListResponse, error) {
+	commentEntity, err := uc.parseEntityType(entityType)
+	if err != nil {
+		return nil, err
+	}
+	comment, err := uc.getComment(ctx, tenantID, commentEntity, entityID, commentID)
+	if err != nil {
+		return nil, err
+	}
+
+	opts := commentListOptions(req)
+	comments, total, err := uc.commentRepo.ListReplies(ctx, tenantID, comment.ThreadID(), opts)
+	if err != nil {
+		return nil, application.WrapError(application.ErrCodeInternal, "failed to list comment replies", err)
+	}
+	return mapCommentsToListResponse(comments, opts, total), nil
+}
+
+// Update edits a comment of the user. Users the edit mentions for the first
+// time are notified; those mentioned before are not notified again.
+func (uc *commentUseCase) Update(ctx context.Context, tenantID, userID uuid.UUID, entityType string, entityID, commentID uuid.UUID, req *dto.UpdateCommentRequest) (*dto.CommentResponse, error) {
+	comment, err := uc.getEntityComment(ctx, tenantID, entityType, entityID, commentID)
+	if err != nil {
+		return nil, err
+	}
+
+	revisions := len(comment.Revisions)
+	added, err := comment.Edit(userID, req.Body)
+	if err != nil {
+		return nil, mapCommentError(comment, err)
+	}
+	if len(comment.Revisions) == revisions {
+		// Saving the same body changes nothing
+		return mapCommentToResponse(comment), nil
+	}
+
+	if err := uc.save(ctx, comment); err != nil {
+		return nil, err
+	}
+
+	uc.publishMentions(ctx, comment, added)
+
+	return mapCommentToResponse(comment), nil
+}
+
+// Delete deletes a comment. Its replies stay in the thread.
+func (uc *commentUseCase) Delete(ctx context.Context, tenantID, userID uuid.UUID, entityType string, entityID, commentID uuid.UUID, moderator bool) error {
+	comment, err := uc.getEntityComment(ctx, tenantID, entityType, entityID, commentID)
+	if err != nil {
+		return err
+	}
+
+	if err := comment.Delete(userID, moderator); err != nil {
+		return mapCommentError(comment, err)
+	}
+	return uc.save(ctx, comment)
+}
+
+// GetHistory returns the audit trail of a comment.
+func (uc *commentUseCase) GetHistory(ctx context.Context, tenantID uuid.UUID, entityType string, entityID, commentID uuid.UUID) (*dto.CommentHistoryResponse, error) {
+	comment, err := uc.getEntityComment(ctx, tenantID, entityType, entityID, commentID)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &dto.CommentHistoryResponse{
+		CommentID: comment.ID.String(),
+		AuthorID:  comment.AuthorID.String(),
+		Body:      comment.Body,
+		CreatedAt: comment.CreatedAt,
+		Revisions: make([]*dto.CommentRevisionResponse, len(comment.Revisions)),
+	}
+	if comment.DeletedBy != nil {
+		deletedBy := comment.DeletedBy.String()
+		resp.DeletedBy = &deletedBy
+	}
+	for i, revision := range comment.Revisions {
+		resp.Revisions[i] = &dto.CommentRevisionResponse{
+			Action:    string(revision.Action),
+			Body:      revision.Body,
+			ChangedBy: revision.ChangedBy.String(),
+			ChangedAt: revision.ChangedAt,
+		}
+	}
+	return resp, nil
+}
+
+// AddReaction adds the user's reaction to a comment; adding it again has no
+// effect.
+func (uc *commentUseCase) AddReaction(ctx context.Context, tenantID, userID uuid.UUID, entityType string, entityID, commentID uuid.UUID, req *dto.CommentReactionRequest) (*dto.CommentResponse, error) {
+	return uc.react(ctx, tenantID, entityType, entityID, commentID, req.Reaction, func(reaction domain.CommentReaction) error {
+		return uc.commentRepo.AddReaction(ctx, tenantID, commentID, userID, reaction)
+	})
+}
+
+// RemoveReaction removes the user's reaction from a comment.
+func (uc *commentUseCase) RemoveReaction(ctx context.Context, tenantID, userID uuid.UUID, entityType string, entityID, commentID uuid.UUID, reaction string) (*dto.CommentResponse, error) {
+	return uc.react(ctx, tenantID, entityType, entityID, commentID, reaction, func(reaction domain.CommentReaction) error {
+		return uc.commentRepo.RemoveReaction(ctx, tenantID, commentID, userID, reaction)
+	})
+}
+
+func (uc *commentUseCase) react(ctx context.Context, tenantID uuid.UUID, entityType string, entityID, commentID uuid.UUID, reaction string, apply func(domain.CommentReaction) error) (*dto.CommentResponse, error) {
+	commentReaction := domain.CommentReaction(reaction)
+	if !commentReaction.IsValid() {
+		return nil, application.ErrValidation(domain.ErrInvalidCommentReaction.Error())
+	}
+
+	comment, err := uc.getEntityComment(ctx, tenantID, entityType, entityID, commentID)
+	if err != nil {
+		return nil, err
+	}
+	if comment.IsDeleted() {
+		return nil, application.ErrCommentDeleted(commentID)
+	}
+
+	if err := apply(commentReaction); err != nil {
+		return nil, application.WrapError(application.ErrCodeInternal, "failed to update comment reaction", err)
+	}
+
+	// Reload the comment for the reactions of everyone
+	comment, err = uc.getComment(ctx, tenantID, comment.EntityType, entityID, commentID)
+	if err != nil {
+		return nil, err
+	}
+	return mapCommentToResponse(comment), nil
+}
+
+// ============================================================================
+// Helpers
+// ============================================================================
+
+// findEntity checks the tenant has the record comments are listed or written
+// on.
+func (uc *commentUseCase) findEntity(ctx context.Context, tenantID uuid.UUID, entityType string, entityID uuid.UUID) (domain.CommentEntityType, error) {
+	commentEntity, err := uc.parseEntityType(entityType)
+	if err != nil {
+		return commentEntity, err
+	}
+
+	switch commentEntity {
+	case domain.CommentEntityLead:
+		if _, err := uc.leadRepo.GetByID(ctx, tenantID, entityID); err != nil {
+			return commentEntity, application.ErrLeadNotFound(entityID)
+		}
+	case domain.CommentEntityOpportunity:
+		if _, err := uc.opportunityRepo.GetByID(ctx, tenantID, entityID); err != nil {
+			return commentEntity, application.ErrOpportunityNotFound(entityID)
+		}
+	case domain.CommentEntityCustomer:
+		if uc.customerService != nil {
+			exists, err := uc.customerService.CustomerExists(ctx, tenantID, entityID)
+			if err != nil {
+				return commentEntity, application.WrapError(application.ErrCodeInternal, "failed to check customer", err)
+			}
+			if !exists {
+				return commentEntity, application.ErrCustomerNotFound(entityID)
+			}
+		}
+	}
+	return commentEntity, nil
+}
+
+func (uc *commentUseCase) parseEntityType(entityType string) (domain.CommentEntityType, error) {
+	commentEntity := domain.CommentEntityType(entityType)
+	if !commentEntity.IsValid() {
+		return commentEntity, application.ErrValidation(domain.ErrInvalidCommentEntityType.Error())
+	}
+	return commentEntity, nil
+}
+
+func (uc *commentUseCase) getEntityComment(ctx context.Context, tenantID uuid.UUID, entityType string, entityID, commentID uuid.UUID) (*domain.Comment, error) {
+	commentEntity, err := uc.parseEntityType(entityType)
+	if err != nil {
+		return nil, err
+	}
+	return uc.getComment(ctx, tenantID, commentEntity, entityID, commentID)
+}
+
+// getComment retrieves a comment on a record. A comment on another record is
+// reported as not found.
+func (uc *commentUseCase) getComment(ctx context.Context, tenantID uuid.UUID, entityType domain.CommentEntityType, entityID, commentID uuid.UUID) (*domain.Comment, error) {
+	comment, err := uc.commentRepo.GetByID(ctx, tenantID, commentID)
+	if err != nil {
+		if errors.Is(err, domain.ErrCommentNotFound) {
+			return nil, application.ErrCommentNotFound(commentID)
+		}
+		return nil, application.WrapError(application.ErrCodeInternal, "failed to get comment", err)
+	}
+	if comment.EntityType != entityType || comment.EntityID != entityID {
+		return nil, application.ErrCommentNotFound(commentID)
+	}
+	return comment, nil
+}
+
+func (uc *commentUseCase) save(ctx context.Context, comment *domain.Comment) error {
+	comment.Version++
+	if err := uc.commentRepo.Update(ctx, comment); err != nil {
+		if errors.Is(err, domain.ErrCommentModified) {
+			return application.ErrConcurrentModification("comment", comment.ID)
+		}
+		return application.WrapError(application.ErrCodeInternal, "failed to update comment", err)
+	}
+	return nil
+}
+
+// mapCommentError maps an error editing or deleting a comment.
+func mapCommentError(comment *domain.Comment, err error) error {
+	switch {
+	case errors.Is(err, domain.ErrCommentDeleted):
+		return application.ErrCommentDeleted(comment.ID)
+	case errors.Is(err, domain.ErrNotCommentAuthor):
+		return application.ErrForbidden(err.Error())
+	}
+	return application.ErrValidation(err.Error())
+}
+
+// commentListOptions returns the page of a list request, 20 comments by
+// default.
+func commentListOptions(req *dto.ListCommentsRequest) domain.ListOptions {
+	page, pageSize := req.Page, req.PageSize
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 {
+		pageSize = 20
+	}
+	if pageSize > 100 {
+		pageSize = 100
+	}
+	return domain.ListOptions{Page: page, PageSize: pageSize}
+}
+
+// publishMentions publishes the comment mentioned event for the users the
+// comment mentions, leaving out its author.
+func (uc *commentUseCase) publishMentions(ctx context.Context, comment *domain.Comment, mentioned []uuid.UUID) {
+	if uc.eventPublisher == nil {
+		return
+	}
+
+	recipients := make([]uuid.UUID, 0, len(mentioned))
+	for _, id := range mentioned {
+		if id != comment.AuthorID {
+			recipients = append(recipients, id)
+		}
+	}
+	if len(recipients) == 0 {
+		return
+	}
+
+	event := domain.NewCommentMentionedEvent(comment, recipients)
+	var payload map[string]interface{}
+	if data, err := json.Marshal(event); err == nil {
+		json.Unmarshal(data, &payload)
+	}
+
+	_ = uc.eventPublisher.Publish(ctx, ports.Event{
+		ID:            event.EventID().String(),
+		Type:          event.EventType(),
+		AggregateID:   event.AggregateID().String(),
+		AggregateType: event.AggregateType(),
+		TenantID:      event.TenantID().String(),
+		Payload:       payload,
+		OccurredAt:    event.OccurredAt(),
+		Version:       event.Version(),
+	})
+}
+
+// ============================================================================
+// Mappers
+// ============================================================================
+
+func mapCommentToResponse(comment *domain.Comment) *dto.CommentResponse {
+	resp := &dto.CommentResponse{
+		ID:         comment.ID.String(),
+		EntityType: string(comment.EntityType),
+		EntityID:   comment.EntityID.String(),
+		AuthorID:   comment.AuthorID.String(),
+		Body:       comment.Body,
+		Mentions:   make([]string, len(comment.Mentions)),
+		Reactions:  make([]*dto.CommentReactionResponse, 0, len(comment.Reactions)),
+		ReplyCount: comment.ReplyCount,
+		Edited:     comment.EditedAt != nil,
+		Deleted:    comment.IsDeleted(),
+		EditedAt:   comment.EditedAt,
+		DeletedAt:  comment.DeletedAt,
+		CreatedAt:  comment.CreatedAt,
+		UpdatedAt:  comment.UpdatedAt,
+	}
+	if comment.ParentID != nil {
+		parentID := comment.ParentID.String()
+		resp.ParentID = &parentID
+	}
+	for i, id := range comment.Mentions {
+		resp.Mentions[i] = id.String()
+	}
+	for reaction, userIDs := range comment.Reactions {
+		if len(userIDs) == 0 {
+			continue
+		}
+		reactionResp := &dto.CommentReactionResponse{
+			Reaction: string(reaction),
+			Count:    len(userIDs),
+			UserIDs:  make([]string, len(userIDs)),
+		}
+		for i, id := range userIDs {
+			reactionResp.UserIDs[i] = id.String()
+		}
+		resp.Reactions = append(resp.Reactions, reactionResp)
+	}
+	sort.Slice(resp.Reactions, func(i, j int) bool { return resp.Reactions[i].Reaction < resp.Reactions[j].Reaction })
+	return resp
+}
+
+func mapCommentsToListResponse(comments []*domain.Comment, opts domain.ListOptions, total int64) *dto.CommentListResponse {
+	resp := &dto.CommentListResponse{
+		Comments:   make([]*dto.CommentResponse, len(comments)),
+		Pagination: dto.NewPaginationResponse(opts.Page, opts.PageSize, total),
+	}
+	for i, comment := range comments {
+		resp.Comments[i] = mapCommentToResponse(comment)
+	}
+	return resp
+}
@@ -0,0 +1,299 @@
+package usecase
+
+import (
+	"context"
+	"sort"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"github.com/kilang-desa-murni/crm/internal/sales/application"
+	"github.com/kilang-desa-murni/crm/internal/sales/application/dto"
+	"github.com/kilang-desa-murni/crm/internal/sales/domain"
+)
+
+// ============================================================================
+// Mock Implementations for Comment Tests
+// ============================================================================
+
+// MockCommentRepository is a mock implementation of domain.CommentRepository.
+type MockCommentRepository struct {
+	comments map[uuid.UUID]*domain.Comment
+}
+
+func NewMockCommentRepository() *MockCommentRepository {
+	return &MockCommentRepository{comments: make(map[uuid.UUID]*domain.Comment)}
+}
+
+func (m *MockCommentRepository) Create(ctx context.Context, comment *domain.Comment) error {
+	m.comments[comment.ID] = comment
+	return nil
+}
+
+func (m *MockCommentRepository) Update(ctx context.Context, comment *domain.Comment) error {
+	m.comments[comment.ID] = comment
+	return nil
+}
+
+func (m *MockCommentRepository) GetByID(ctx context.Context, tenantID, commentID uuid.UUID) (*domain.Comment, error) {
+	comment, ok := m.comments[commentID]
+	if !ok || comment.TenantID != tenantID {
+		return nil, domain.ErrCommentNotFound
+	}
+	comment.ReplyCount = int64(len(m.filter(func(c *domain.Comment) bool {
+		return c.ParentID != nil && *c.ParentID == comment.ID
+	})))
+	return comment, nil
+}
+
+func (m *MockCommentRepository) ListThreads(ctx context.Context, tenantID uuid.UUID, entityType domain.CommentEntityType, entityID uuid.UUID, opts domain.ListOptions) ([]*domain.Comment, int64, error) {
+	threads := m.filter(func(c *domain.Comment) bool {
+		return c.TenantID == tenantID && c.EntityType == entityType && c.EntityID == entityID && c.ParentID == nil
+	})
+	sort.Slice(threads, func(i, j int) bool { return threads[i].CreatedAt.After(threads[j].CreatedAt) })
+	return pageComments(threads, opts), int64(len(threads)), nil
+}
+
+func (m *MockCommentRepository) ListReplies(ctx context.Context, tenantID, threadID uuid.UUID, opts domain.ListOptions) ([]*domain.Comment, int64, error) {
+	replies := m.filter(func(c *domain.Comment) bool {
+		return c.TenantID == tenantID && c.ParentID != nil && *c.ParentID == threadID
+	})
+	sort.Slice(replies, func(i, j int) bool { return replies[i].CreatedAt.Before(replies[j].CreatedAt) })
+	return pageComments(replies, opts), int64(len(replies)), nil
+}
+
+func (m *MockCommentRepository) AddReaction(ctx context.Context, tenantID, commentID, userID uuid.UUID, reaction domain.CommentReaction) error {
+	comment := m.comments[commentID]
+	for _, id := range comment.Reactions[reaction] {
+		if id == userID {
+			return nil
+		}
+	}
+	comment.Reactions[reaction] = append(comment.Reactions[reaction], userID)
+	return nil
+}
+
+func (m *MockCommentRepository) RemoveReaction(ctx context.Context, tenantID, commentID, userID uuid.UUID, reaction domain.CommentReaction) error {
+	comment := m.comments[commentID]
+	users := comment.Reactions[reaction][:0]
+	for _, id := range comment.Reactions[reaction] {
+		if id != userID {
+			users = append(users, id)
+		}
+	}
+	comment.Reactions[reaction] = users
+	return nil
+}
+
+func (m *MockCommentRepository) filter(keep func(*domain.Comment) bool) []*domain.Comment {
+	var comments []*domain.Comment
+	for _, comment := range m.comments {
+		if keep(comment) {
+			comments = append(comments, comment)
+		}
+	}
+	return comments
+}
+
+func pageComments(comments []*domain.Comment, opts domain.ListOptions) []*domain.Comment {
+	start := opts.Offset()
+	if start > len(comments) {
+		start = len(comments)
+	}
+	end := start + opts.Limit()
+	if end > len(comments) {
+		end = len(comments)
+	}
+	return comments[start:end]
+}
+
+// ============================================================================
+// Comment Use Case Tests
+// ============================================================================
+
+func newTestCommentUseCase(tenantID uuid.UUID) (CommentUseCase, *MockSalesEventPublisher, uuid.UUID) {
+	opportunityRepo := NewMockOpportunityRepository()
+	opportunity := &domain.Opportunity{ID: uuid.New(), TenantID: tenantID}
+	opportunityRepo.opportunities[opportunity.ID] = opportunity
+	publisher := NewMockSalesEventPublisher()
+	uc := NewCommentUseCase(NewMockCommentRepository(), NewMockLeadRepository(), opportunityRepo, nil, publisher)
+	return uc, publisher, opportunity.ID
+}
+
+func commentMention(name string, userID uuid.UUID) string {
+	return "@[" + name + "](" + userID.String() + ")"
+}
+
+func TestCommentUseCase_Create_PublishesMentions(t *testing.T) {
+	ctx := context.Background()
+	tenantID := uuid.New()
+	uc, publisher, opportunityID := newTestCommentUseCase(tenantID)
+	author, aminah := uuid.New(), uuid.New()
+
+	comment, err := uc.Create(ctx, tenantID, author, "opportunity", opportunityID, &dto.CreateCommentRequest{
+		Body: commentMention("Aminah", aminah) + " the buyer wants songket samples, cc " + commentMention("Me", author),
+	})
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	if len(comment.Mentions) != 2 {
+		t.Errorf("Mentions = %v, want 2", comment.Mentions)
+	}
+
+	if len(publisher.events) != 1 || publisher.events[0].Type != "comment.mentioned" {
+		t.Fatalf("events = %+v, want one comment mentioned event", publisher.events)
+	}
+	// The author is not notified of their own comment
+	mentioned, _ := publisher.events[0].Payload["mentioned_user_ids"].([]interface{})
+	if len(mentioned) != 1 || mentioned[0] != aminah.String() {
+		t.Errorf("mentioned_user_ids = %v, want [%s]", mentioned, aminah)
+	}
+}
+
+func TestCommentUseCase_Create_RecordNotFound(t *testing.T) {
+	tenantID := uuid.New()
+	uc, _, _ := newTestCommentUseCase(tenantID)
+
+	_, err := uc.Create(context.Background(), tenantID, uuid.New(), "opportunity", uuid.New(), &dto.CreateCommentRequest{Body: "Hello"})
+	if appErr := application.GetAppError(err); appErr == nil || appErr.Code != application.ErrCodeOpportunityNotFound {
+		t.Errorf("Create() error = %v, want opportunity not found", err)
+	}
+}
+
+func TestCommentUseCase_Threads(t *testing.T) {
+	ctx := context.Background()
+	tenantID := uuid.New()
+	uc, _, opportunityID := newTestCommentUseCase(tenantID)
+	userID := uuid.New()
+
+	thread, _ := uc.Create(ctx, tenantID, userID, "opportunity", opportunityID, &dto.CreateCommentRequest{Body: "Kickoff"})
+	reply, err := uc.Create(ctx, tenantID, userID, "opportunity", opportunityID, &dto.CreateCommentRequest{Body: "First", ParentID: &thread.ID})
+	if err != nil {
+		t.Fatalf("Create() reply error = %v", err)
+	}
+	if _, err := uc.Create(ctx, tenantID, userID, "opportunity", opportunityID, &dto.CreateCommentRequest{Body: "Second", ParentID: &reply.ID}); err != nil {
+		t.Fatalf("Create() reply to reply error = %v", err)
+	}
+
+	threads, err := uc.List(ctx, tenantID, "opportunity", opportunityID, &dto.ListCommentsRequest{})
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	if len(threads.Comments) != 1 || threads.Pagination.TotalItems != 1 {
+		t.Fatalf("List() = %+v, want one thread", threads)
+	}
+
+	replies, err := uc.ListReplies(ctx, tenantID, "opportunity", opportunityID, uuid.MustParse(thread.ID), &dto.ListCommentsRequest{PageSize: 1})
+	if err != nil {
+		t.Fatalf("ListReplies() error = %v", err)
+	}
+	if len(replies.Comments) != 1 || replies.Comments[0].Body != "First" || replies.Pagination.TotalItems != 2 {
+		t.Errorf("ListReplies() = %+v, want the first of two replies", replies)
+	}
+
+	// A comment is only found on its own record
+	_, err = uc.ListReplies(ctx, tenantID, "lead", opportunityID, uuid.MustParse(thread.ID), &dto.ListCommentsRequest{})
+	if appErr := application.GetAppError(err); appErr == nil || appErr.Code != application.ErrCodeCommentNotFound {
+		t.Errorf("ListReplies() on another record error = %v, want comment not found", err)
+	}
+}
+
+func TestCommentUseCase_Update_NotifiesNewMentions(t *testing.T) {
+	ctx := context.Background()
+	tenantID := uuid.New()
+	uc, publisher, opportunityID := newTestCommentUseCase(tenantID)
+	author, aminah, badrul := uuid.New(), uuid.New(), uuid.New()
+
+	comment, _ := uc.Create(ctx, tenantID, author, "opportunity", opportunityID, &dto.CreateCommentRequest{Body: "Ask " + commentMention("Aminah", aminah)})
+	commentID := uuid.MustParse(comment.ID)
+
+	_, err := uc.Update(ctx, tenantID, uuid.New(), "opportunity", opportunityID, commentID, &dto.UpdateCommentRequest{Body: "Mine now"})
+	if appErr := application.GetAppError(err); appErr == nil || appErr.Code != application.ErrCodeForbidden {
+		t.Errorf("Update() by another user error = %v, want forbidden", err)
+	}
+
+	updated, err := uc.Update(ctx, tenantID, author, "opportunity", opportunityID, commentID, &dto.UpdateCommentRequest{
+		Body: "Ask " + commentMention("Aminah", aminah) + " and " + commentMention("Badrul", badrul),
+	})
+	if err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+	if !updated.Edited {
+		t.Error("Edited = false, want true")
+	}
+
+	if len(publisher.events) != 2 {
+		t.Fatalf("len(events) = %d, want 2", len(publisher.events))
+	}
+	mentioned, _ := publisher.events[1].Payload["mentioned_user_ids"].([]interface{})
+	if len(mentioned) != 1 || mentioned[0] != badrul.String() {
+		t.Errorf("mentioned_user_ids = %v, want only the newly mentioned %s", mentioned, badrul)
+	}
+
+	history, err := uc.GetHistory(ctx, tenantID, "opportunity", opportunityID, commentID)
+	if err != nil {
+		t.Fatalf("GetHistory() error = %v", err)
+	}
+	if len(history.Revisions) != 1 || history.Revisions[0].Body != "Ask "+commentMention("Aminah", aminah) {
+		t.Errorf("Revisions = %+v, want the original body", history.Revisions)
+	}
+}
+
+func TestCommentUseCase_Delete(t *testing.T) {
+	ctx := context.Background()
+	tenantID := uuid.New()
+	uc, _, opportunityID := newTestCommentUseCase(tenantID)
+	author := uuid.New()
+
+	comment, _ := uc.Create(ctx, tenantID, author, "opportunity", opportunityID, &dto.CreateCommentRequest{Body: "Oops"})
+	commentID := uuid.MustParse(comment.ID)
+
+	err := uc.Delete(ctx, tenantID, uuid.New(), "opportunity", opportunityID, commentID, false)
+	if appErr := application.GetAppError(err); appErr == nil || appErr.Code != application.ErrCodeForbidden {
+		t.Errorf("Delete() by another user error = %v, want forbidden", err)
+	}
+	if err := uc.Delete(ctx, tenantID, uuid.New(), "opportunity", opportunityID, commentID, true); err != nil {
+		t.Fatalf("Delete() by a moderator error = %v", err)
+	}
+
+	err = uc.Delete(ctx, tenantID, author, "opportunity", opportunityID, commentID, false)
+	if appErr := application.GetAppError(err); appErr == nil || appErr.Code != application.ErrCodeCommentDeleted {
+		t.Errorf("Delete() again error = %v, want comment deleted", err)
+	}
+	_, err = uc.AddReaction(ctx, tenantID, author, "opportunity", opportunityID, commentID, &dto.CommentReactionRequest{Reaction: "heart"})
+	if appErr := application.GetAppError(err); appErr == nil || appErr.Code != application.ErrCodeCommentDeleted {
+		t.Errorf("AddReaction() to a deleted comment error = %v, want comment deleted", err)
+	}
+}
+
+func TestCommentUseCase_Reactions(t *testing.T) {
+	ctx := context.Background()
+	tenantID := uuid.New()
+	uc, _, opportunityID := newTestCommentUseCase(tenantID)
+	alice, bob := uuid.New(), uuid.New()
+
+	comment, _ := uc.Create(ctx, tenantID, alice, "opportunity", opportunityID, &dto.CreateCommentRequest{Body: "We won!"})
+	commentID := uuid.MustParse(comment.ID)
+
+	for _, userID := range []uuid.UUID{alice, bob, bob} {
+		if comment, _ = uc.AddReaction(ctx, tenantID, userID, "opportunity", opportunityID, commentID, &dto.CommentReactionRequest{Reaction: "celebrate"}); comment == nil {
+			t.Fatal("AddReaction() returned no comment")
+		}
+	}
+	if len(comment.Reactions) != 1 || comment.Reactions[0].Count != 2 {
+		t.Errorf("Reactions = %+v, want celebrate by 2 users", comment.Reactions)
+	}
+
+	comment, err := uc.RemoveReaction(ctx, tenantID, bob, "opportunity", opportunityID, commentID, "celebrate")
+	if err != nil {
+		t.Fatalf("RemoveReaction() error = %v", err)
+	}
+	if len(comment.Reactions) != 1 || comment.Reactions[0].Count != 1 || comment.Reactions[0].UserIDs[0] != alice.String() {
+		t.Errorf("Reactions = %+v, want celebrate by alice", comment.Reactions)
+	}
+
+	_, err = uc.AddReaction(ctx, tenantID, bob, "opportunity", opportunityID, commentID, &dto.CommentReactionRequest{Reaction: "rocket"})
+	if appErr := application.GetAppError(err); appErr == nil || appErr.Code != application.ErrCodeValidation {
+		t.Errorf("AddReaction() error = %v, want validation error", err)
+	}
+}
@@ -0,0 +1,235 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/kilang-desa-murni/crm/internal/sales/application"
+	"github.com/kilang-desa-murni/crm/internal/sales/application/dto"
+	"github.com/kilang-desa-murni/crm/internal/sales/domain"
+)
+
+// Windows of the upcoming renewals listing, in days.
+const (
+	defaultRenewalWindowDays = 90
+	maxRenewalWindowDays     = 366
+)
+
+// ============================================================================
+// Recurring Deals
+// ============================================================================
+
+// Churn records that the customer of a recurring deal will not renew it.
+func (uc *dealUseCase) Churn(ctx context.Context, tenantID, dealID, userID uuid.UUID, req *dto.ChurnDealRequest) (*dto.DealResponse, error) {
+	deal, err := uc.dealRepo.GetByID(ctx, tenantID, dealID)
+	if err != nil {
+		return nil, application.ErrDealNotFound(dealID)
+	}
+	if !deal.IsRecurring() {
+		return nil, application.ErrDealNotRecurring(dealID)
+	}
+
+	if err := deal.Churn(req.Reason); err != nil {
+		return nil, application.WrapError(application.ErrCodeDealInvalidTransition, err.Error(), err)
+	}
+	deal.Version++
+
+	if err := uc.dealRepo.Update(ctx, deal); err != nil {
+		return nil, application.WrapError(application.ErrCodeInternal, "failed to update deal", err)
+	}
+
+	for _, event := range deal.GetEvents() {
+		uc.publishEvent(ctx, event)
+	}
+	deal.ClearEvents()
+
+	uc.invalidateDealCache(ctx, tenantID)
+
+	return uc.mapDealToResponse(ctx, deal), nil
+}
+
+// GetRecurringRevenue reports the recurring revenue of a period: MRR at its
+// start and end, new MRR, and the renewals and churn of the contracts ending
+// in it. Only deals in the requested currency are counted.
+func (uc *dealUseCase) GetRecurringRevenue(ctx context.Context, tenantID uuid.UUID, req *dto.RecurringRevenueRequest) (*dto.RecurringRevenueResponse, error) {
+	period, err := parseReportPeriod(req.From, req.To)
+	if err != nil {
+		return nil, err
+	}
+	currency, err := resolveRecurringCurrency(req.Currency)
+	if err != nil {
+		return nil, err
+	}
+
+	deals, err := uc.dealRepo.GetRecurringDeals(ctx, tenantID, currency, period.From, period.To)
+	if err != nil {
+		return nil, application.WrapError(application.ErrCodeInternal, "failed to get recurring deals", err)
+	}
+
+	summary := domain.SummarizeRecurringRevenue(deals, period)
+	money := func(amount int64) dto.MoneyDTO {
+		return moneyToDTO(domain.Money{Amount: amount, Currency: currency})
+	}
+
+	return &dto.RecurringRevenueResponse{
+		Period: dto.DateRangeDTO{
+			StartDate: period.From,
+			EndDate:   period.To.AddDate(0, 0, -1),
+		},
+		Currency:       currency,
+		StartingMRR:    money(summary.StartingMRR),
+		EndingMRR:      money(summary.EndingMRR),
+		EndingARR:      money(summary.EndingMRR * 12),
+		NewMRR:         money(summary.NewMRR),
+		RenewedMRR:     money(summary.RenewedMRR),
+		ChurnedMRR:     money(summary.ChurnedMRR),
+		RenewalsDue:    summary.RenewalsDue,
+		Renewed:        summary.Renewed,
+		Churned:        summary.Churned,
+		PendingRenewal: summary.RenewalsDue - summary.Renewed - summary.Churned,
+		RenewalRate:    summary.RenewalRate(),
+		ChurnRate:      summary.ChurnRate(),
+		GeneratedAt:    time.Now().UTC(),
+	}, nil
+}
+
+// GetUpcomingRenewals lists the recurring deals in currency whose contract
+// ends within the next days, soonest first.
+func (uc *dealUseCase) GetUpcomingRenewals(ctx context.Context, tenantID uuid.UUID, days int, currency string) (*dto.DealRenewalsResponse, error) {
+	if days <= 0 {
+		days = defaultRenewalWindowDays
+	}
+	if days > maxRenewalWindowDays {
+		return nil, application.ErrValidationWithDetails("renewal window too long", map[string]interface{}{
+			"days": days,
+			"max":  maxRenewalWindowDays,
+		})
+	}
+	currency, err := resolveRecurringCurrency(currency)
+	if err != nil {
+		return nil, err
+	}
+
+	from := time.Now().UTC()
+	to := from.AddDate(0, 0, days)
+	deals, err := uc.dealRepo.GetRecurringDeals(ctx, tenantID, currency, from, to)
+	if err != nil {
+		return nil, application.WrapError(application.ErrCodeInternal, "failed to get recurring deals", err)
+	}
+
+	resp := &dto.DealRenewalsResponse{
+		Currency: currency,
+		From:     from,
+		To:       to,
+		Renewals: make([]*dto.DealRenewalDTO, 0, len(deals)),
+	}
+	mrrDue := domain.Money{Currency: currency}
+	for _, deal := range deals {
+		if !deal.IsRecurring() || deal.Status == domain.DealStatusCancelled ||
+			deal.Subscription.ContractEnd.Before(from) || deal.Subscription.ContractEnd.After(to) {
+			continue
+		}
+		subscription := deal.Subscription
+		if subscription.RenewalStatus != domain.RenewalStatusChurned {
+			mrrDue.Amount += subscription.MRR().Amount
+		}
+
+		renewal := &dto.DealRenewalDTO{
+			DealID:        deal.ID.String(),
+			Code:          deal.Code,
+			Name:          deal.Name,
+			CustomerID:    deal.CustomerID.String(),
+			CustomerName:  deal.CustomerName,
+			OwnerID:       deal.OwnerID.String(),
+			OwnerName:     deal.OwnerName,
+			ContractEnd:   subscription.ContractEnd,
+			MRR:           moneyToDTO(subscription.MRR()),
+			RenewalStatus: string(subscription.RenewalStatus),
+		}
+		if subscription.RenewalOpportunityID != nil {
+			s := subscription.RenewalOpportunityID.String()
+			renewal.RenewalOpportunityID = &s
+		}
+		resp.Renewals = append(resp.Renewals, renewal)
+	}
+	resp.MRRDue = moneyToDTO(mrrDue)
+
+	return resp, nil
+}
+
+// ============================================================================
+// Helper Methods
+// ============================================================================
+
+// applyDealSubscription makes the deal recurring under the requested contract.
+func applyDealSubscription(deal *domain.Deal, req *dto.DealSubscriptionRequest) error {
+	start, err := time.Parse("2006-01-02", req.ContractStart)
+	if err != nil {
+		return application.ErrValidation("invalid contract_start format")
+	}
+	end, err := time.Parse("2006-01-02", req.ContractEnd)
+	if err != nil {
+		return application.ErrValidation("invalid contract_end format")
+	}
+	amount, err := domain.NewMoney(req.RecurringAmount, deal.Currency)
+	if err != nil {
+		return application.ErrValidation("invalid recurring_amount")
+	}
+
+	subscription, err := domain.NewDealSubscription(domain.BillingFrequency(req.BillingFrequency), amount, start, end, req.RenewalNoticeDays)
+	if err != nil {
+		return application.WrapError(application.ErrCodeValidation, err.Error(), err)
+	}
+	if err := deal.SetSubscription(subscription); err != nil {
+		if errors.Is(err, domain.ErrDealAlreadyClosed) {
+			return application.WrapError(application.ErrCodeDealInvalidTransition, err.Error(), err)
+		}
+		return application.WrapError(application.ErrCodeValidation, err.Error(), err)
+	}
+	return nil
+}
+
+// resolveRecurringCurrency returns the currency recurring revenue is reported
+// in, the report default when none is given.
+func resolveRecurringCurrency(currency string) (string, error) {
+	if currency == "" {
+		return defaultReportCurrency, nil
+	}
+	currency = strings.ToUpper(currency)
+	if !domain.IsSupportedCurrency(currency) {
+		return "", application.ErrCurrencyInvalid(currency)
+	}
+	return currency, nil
+}
+
+func mapSubscriptionToResponse(subscription *domain.DealSubscription) *dto.DealSubscriptionDTO {
+	resp := &dto.DealSubscriptionDTO{
+		BillingFrequency:  string(subscription.BillingFrequency),
+		RecurringAmount:   moneyToDTO(subscription.RecurringAmount),
+		MRR:               moneyToDTO(subscription.MRR()),
+		ARR:               moneyToDTO(subscription.ARR()),
+		ContractStart:     subscription.ContractStart,
+		ContractEnd:       subscription.ContractEnd,
+		TermMonths:        subscription.TermMonths(),
+		ContractValue:     moneyToDTO(subscription.ContractValue()),
+		RenewalNoticeDays: subscription.RenewalNoticeDays,
+		RenewalDueAt:      subscription.RenewalDueAt(),
+		RenewalStatus:     string(subscription.RenewalStatus),
+		RenewedAt:         subscription.RenewedAt,
+		ChurnedAt:         subscription.ChurnedAt,
+		ChurnReason:       subscription.ChurnReason,
+	}
+	if subscription.RenewalOpportunityID != nil {
+		s := subscription.RenewalOpportunityID.String()
+		resp.RenewalOpportunityID = &s
+	}
+	if subscription.RenewedFromDealID != nil {
+		s := subscription.RenewedFromDealID.String()
+		resp.RenewedFromDealID = &s
+	}
+	return resp
+}
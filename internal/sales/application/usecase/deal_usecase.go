@@ -2,6 +2,7 @@ package usecase
 
 import (
 	"context"
+	"encoding/json"
 	"time"
 
 	"github.com/google/uuid"
@@ -35,6 +36,7 @@ type DealUseCase interface {
 	// Invoice operations
 	CreateInvoice(ctx context.Context, tenantID, dealID, userID uuid.UUID, req *dto.CreateInvoiceRequest) (*dto.DealResponse, error)
 	SendInvoice(ctx context.Context, tenantID, dealID, invoiceID, userID uuid.UUID) (*dto.DealResponse, error)
+	MarkOverdueInvoices(ctx context.Context, now time.Time) (int, error)
 
 	// Payment operations
 	RecordPayment(ctx context.Context, tenantID, dealID, userID uuid.UUID, req *dto.RecordPaymentRequest) (*dto.DealResponse, error)
@@ -46,6 +48,11 @@ type DealUseCase interface {
 	PutOnHold(ctx context.Context, tenantID, dealID, userID uuid.UUID, reason string) (*dto.DealResponse, error)
 	Resume(ctx context.Context, tenantID, dealID, userID uuid.UUID) (*dto.DealResponse, error)
 
+	// Recurring deal operations
+	Churn(ctx context.Context, tenantID, dealID, userID uuid.UUID, req *dto.ChurnDealRequest) (*dto.DealResponse, error)
+	GetRecurringRevenue(ctx context.Context, tenantID uuid.UUID, req *dto.RecurringRevenueRequest) (*dto.RecurringRevenueResponse, error)
+	GetUpcomingRenewals(ctx context.Context, tenantID uuid.UUID, days int, currency string) (*dto.DealRenewalsResponse, error)
+
 	// Statistics
 	GetStatistics(ctx context.Context, tenantID uuid.UUID) (*dto.DealStatisticsResponse, error)
 }
@@ -66,6 +73,7 @@ type dealUseCase struct {
 	searchService   ports.SearchService
 	idGenerator     ports.IDGenerator
 	notificationSvc ports.NotificationService
+	taxResolver     ports.TaxRateResolver
 }
 
 // NewDealUseCase creates a new deal use case.
@@ -80,6 +88,7 @@ func NewDealUseCase(
 	searchService ports.SearchService,
 	idGenerator ports.IDGenerator,
 	notificationSvc ports.NotificationService,
+	taxResolver ports.TaxRateResolver,
 ) DealUseCase {
 	return &dealUseCase{
 		dealRepo:        dealRepo,
@@ -92,6 +101,7 @@ func NewDealUseCase(
 		searchService:   searchService,
 		idGenerator:     idGenerator,
 		notificationSvc: notificationSvc,
+		taxResolver:     taxResolver,
 	}
 }
 
@@ -148,6 +158,20 @@ func (uc *dealUseCase) Create(ctx context.Context, tenantID, userID uuid.UUID, r
 		deal.PaymentTermDays = req.PaymentTermDays
 	}
 
+	// Recurring deals bill under a contract
+	if req.Subscription != nil {
+		if err := applyDealSubscription(deal, req.Subscription); err != nil {
+			return nil, err
+		}
+	} else if renewed, _ := uc.dealRepo.GetByRenewalOpportunity(ctx, tenantID, opportunityID); renewed != nil {
+		// A renewal continues the subscription of the deal it renews
+		if err := deal.ContinueSubscription(renewed); err != nil {
+			return nil, application.WrapError(application.ErrCodeValidation, err.Error(), err)
+		}
+	} else if req.Type == string(domain.DealTypeRecurring) {
+		return nil, application.ErrValidation("subscription is required for recurring deals")
+	}
+
 	// Save deal
 	if err := uc.dealRepo.Create(ctx, deal); err != nil {
 		return nil, application.WrapError(application.ErrCodeInternal, "failed to save deal", err)
@@ -242,6 +266,13 @@ func (uc *dealUseCase) Update(ctx context.Context, tenantID, dealID, userID uuid
 		deal.Notes = *req.Notes
 	}
 
+	// Update contract if provided
+	if req.Subscription != nil {
+		if err := applyDealSubscription(deal, req.Subscription); err != nil {
+			return nil, err
+		}
+	}
+
 	// Save changes
 	if err := uc.dealRepo.Update(ctx, deal); err != nil {
 		return nil, application.WrapError(application.ErrCodeInternal, "failed to update deal", err)
@@ -302,6 +333,16 @@ func (uc *dealUseCase) List(ctx context.Context, tenantID uuid.UUID, filter *dto
 	if filter.SortOrder != "" {
 		opts.SortOrder = filter.SortOrder
 	}
+	expr, err := dealListQuery.parse(filter.Filter, filter.Sort, &opts)
+	if err != nil {
+		return nil, err
+	}
+	domainFilter.Expr = expr
+	after, err := dealCursorSorts.after(filter.Cursor, opts.SortBy, opts.SortOrder)
+	if err != nil {
+		return nil, err
+	}
+	opts.After = after
 
 	// Get deals
 	deals, total, err := uc.dealRepo.List(ctx, tenantID, domainFilter, opts)
@@ -315,9 +356,12 @@ func (uc *dealUseCase) List(ctx context.Context, tenantID uuid.UUID, filter *dto
 		dealResponses[i] = uc.mapDealToBriefResponse(deal)
 	}
 
+	pagination := dto.NewPaginationResponse(opts.Page, opts.PageSize, total)
+	dealCursorSorts.paginate(&pagination, deals, opts.Limit(), opts, func(d *domain.Deal) uuid.UUID { return d.ID })
+
 	return &dto.DealListResponse{
 		Deals:      dealResponses,
-		Pagination: dto.NewPaginationResponse(opts.Page, opts.PageSize, total),
+		Pagination: pagination,
 	}, nil
 }
 
@@ -359,6 +403,17 @@ func (uc *dealUseCase) AddLineItem(ctx context.Context, tenantID, dealID, userID
 		Notes:        req.Notes,
 	}
 
+	// Apply the requested tax code, or the default rate when no tax is given
+	if req.TaxCode != nil || req.Tax == 0 {
+		tax, err := resolveLineTax(ctx, uc.taxResolver, tenantID, req.TaxCode)
+		if err != nil {
+			return nil, err
+		}
+		if tax != nil {
+			lineItem.ApplyTaxRate(tax.rate, tax.inclusive)
+		}
+	}
+
 	// Add line item
 	if err := deal.AddLineItem(lineItem); err != nil {
 		return nil, application.WrapError(application.ErrCodeInternal, err.Error(), err)
@@ -415,6 +470,19 @@ func (uc *dealUseCase) UpdateLineItem(ctx context.Context, tenantID, dealID, lin
 		return nil, application.ErrDealLineItemNotFound(dealID, lineItemID)
 	}
 
+	// Change the tax rate when a tax code is given
+	if req.TaxCode != nil {
+		lineTax, err := resolveLineTax(ctx, uc.taxResolver, tenantID, req.TaxCode)
+		if err != nil {
+			return nil, err
+		}
+		if lineTax != nil {
+			if err := deal.ApplyLineItemTax(lineItemID, lineTax.rate, lineTax.inclusive); err != nil {
+				return nil, application.WrapError(application.ErrCodeInternal, err.Error(), err)
+			}
+		}
+	}
+
 	deal.Version++
 
 	// Save changes
@@ -536,6 +604,50 @@ func (uc *dealUseCase) SendInvoice(ctx context.Context, tenantID, dealID, invoic
 	return uc.mapDealToResponse(ctx, deal), nil
 }
 
+// overdueInvoiceBatchSize is the number of deals loaded at a time when
+// marking invoices overdue.
+const overdueInvoiceBatchSize = 200
+
+// MarkOverdueInvoices marks the sent invoices of all tenants whose due date
+// passed before now as overdue, publishing an overdue event for each, and
+// returns how many were marked.
+func (uc *dealUseCase) MarkOverdueInvoices(ctx context.Context, now time.Time) (int, error) {
+	marked := 0
+	for {
+		deals, err := uc.dealRepo.GetDealsWithLapsedInvoices(ctx, now, overdueInvoiceBatchSize)
+		if err != nil {
+			return marked, application.WrapError(application.ErrCodeInternal, "failed to get deals with lapsed invoices", err)
+		}
+
+		for _, deal := range deals {
+			count := deal.MarkOverdueInvoices(now)
+			if count == 0 {
+				continue
+			}
+			deal.Version++
+
+			// The deal is saved before its events are published, so a
+			// failed save is retried on the next run without duplicate alerts
+			if err := uc.dealRepo.Update(ctx, deal); err != nil {
+				return marked, application.WrapError(application.ErrCodeInternal, "failed to update deal", err)
+			}
+			for _, event := range deal.GetEvents() {
+				uc.publishEvent(ctx, event)
+			}
+			deal.ClearEvents()
+			uc.invalidateDealCache(ctx, deal.TenantID)
+			marked += count
+		}
+
+		if len(deals) < overdueInvoiceBatchSize {
+			return marked, nil
+		}
+		if err := ctx.Err(); err != nil {
+			return marked, err
+		}
+	}
+}
+
 // ============================================================================
 // Payment Operations
 // ============================================================================
@@ -774,6 +886,7 @@ func (uc *dealUseCase) mapDealToResponse(ctx context.Context, deal *domain.Deal)
 		Name:        deal.Name,
 		Description: deal.Description,
 		Status:      string(deal.Status),
+		Type:        string(deal.Type),
 		CustomerID:  deal.CustomerID.String(),
 		CustomerName: deal.CustomerName,
 		OwnerID:     deal.OwnerID.String(),
@@ -832,6 +945,12 @@ func (uc *dealUseCase) mapDealToResponse(ctx context.Context, deal *domain.Deal)
 	resp.OpportunityID = deal.OpportunityID.String()
 	resp.PipelineID = deal.PipelineID.String()
 	resp.WonReason = deal.WonReason
+	if deal.Attribution != nil {
+		resp.Attribution = mapAttributionToResponse(deal.Attribution, "")
+	}
+	if deal.Subscription != nil {
+		resp.Subscription = mapSubscriptionToResponse(deal.Subscription)
+	}
 
 	// Map primary contact
 	if deal.PrimaryContactID != nil {
@@ -874,6 +993,9 @@ func (uc *dealUseCase) mapDealToResponse(ctx context.Context, deal *domain.Deal)
 			DiscountType: item.DiscountType,
 			Tax:          item.Tax,
 			TaxType:      item.TaxType,
+			TaxCode:      item.TaxCode,
+			TaxName:      item.TaxName,
+			TaxInclusive: item.TaxInclusive,
 			Subtotal: dto.MoneyDTO{
 				Amount:   item.Subtotal.Amount,
 				Currency: item.Subtotal.Currency,
@@ -904,6 +1026,15 @@ func (uc *dealUseCase) mapDealToResponse(ctx context.Context, deal *domain.Deal)
 				Amount:   inv.Amount.Amount,
 				Currency: inv.Amount.Currency,
 			},
+			Subtotal: dto.MoneyDTO{
+				Amount:   inv.Subtotal.Amount,
+				Currency: inv.Amount.Currency,
+			},
+			TaxAmount: dto.MoneyDTO{
+				Amount:   inv.TaxAmount.Amount,
+				Currency: inv.Amount.Currency,
+			},
+			TaxLines:   mapTaxLinesToDTO(inv.TaxLines),
 			DueDate:    inv.DueDate,
 			Status:     inv.Status,
 			SentAt:     inv.SentAt,
@@ -1026,12 +1157,20 @@ func (uc *dealUseCase) publishEvent(ctx context.Context, event domain.DomainEven
 		return nil
 	}
 
+	// The payload carries the fields of the event, such as the amounts
+	// consumers show
+	var payload map[string]interface{}
+	if data, err := json.Marshal(event); err == nil {
+		json.Unmarshal(data, &payload)
+	}
+
 	return uc.eventPublisher.Publish(ctx, ports.Event{
 		ID:            event.EventID().String(),
 		Type:          event.EventType(),
 		AggregateID:   event.AggregateID().String(),
 		AggregateType: event.AggregateType(),
 		TenantID:      event.TenantID().String(),
+		Payload:       payload,
 		OccurredAt:    event.OccurredAt(),
 		Version:       event.Version(),
 	})
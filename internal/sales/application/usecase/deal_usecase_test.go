@@ -8,6 +8,7 @@ import (
 
 	"github.com/google/uuid"
 
+	"github.com/kilang-desa-murni/crm/internal/sales/application"
 	"github.com/kilang-desa-murni/crm/internal/sales/application/dto"
 	"github.com/kilang-desa-murni/crm/internal/sales/application/ports"
 	"github.com/kilang-desa-murni/crm/internal/sales/domain"
@@ -141,6 +142,56 @@ func (m *DealMockDealRepository) GetOverduePayments(ctx context.Context, tenantI
 	return []*domain.Deal{}, 0, nil
 }
 
+func (m *DealMockDealRepository) GetDealsWithLapsedInvoices(ctx context.Context, dueBefore time.Time, limit int) ([]*domain.Deal, error) {
+	var deals []*domain.Deal
+	for _, deal := range m.deals {
+		for _, invoice := range deal.Invoices {
+			if invoice.Status == "sent" && invoice.DueDate.Before(dueBefore) {
+				deals = append(deals, deal)
+				break
+			}
+		}
+		if len(deals) == limit {
+			break
+		}
+	}
+	return deals, nil
+}
+
+func (m *DealMockDealRepository) GetDealsDueForRenewal(ctx context.Context, dueBy time.Time, limit int) ([]*domain.Deal, error) {
+	var deals []*domain.Deal
+	for _, deal := range m.deals {
+		if deal.IsRenewalDue(dueBy) {
+			deals = append(deals, deal)
+		}
+		if len(deals) == limit {
+			break
+		}
+	}
+	return deals, nil
+}
+
+func (m *DealMockDealRepository) GetByRenewalOpportunity(ctx context.Context, tenantID, opportunityID uuid.UUID) (*domain.Deal, error) {
+	for _, deal := range m.deals {
+		if deal.TenantID == tenantID && deal.Subscription != nil &&
+			deal.Subscription.RenewalOpportunityID != nil && *deal.Subscription.RenewalOpportunityID == opportunityID {
+			return deal, nil
+		}
+	}
+	return nil, nil
+}
+
+func (m *DealMockDealRepository) GetRecurringDeals(ctx context.Context, tenantID uuid.UUID, currency string, from, to time.Time) ([]*domain.Deal, error) {
+	var deals []*domain.Deal
+	for _, deal := range m.deals {
+		if deal.TenantID == tenantID && deal.IsRecurring() && deal.Currency == currency &&
+			!deal.Subscription.ContractStart.After(to) && !deal.Subscription.ContractEnd.Before(from) {
+			deals = append(deals, deal)
+		}
+	}
+	return deals, nil
+}
+
 func (m *DealMockDealRepository) GetFullyPaidDeals(ctx context.Context, tenantID uuid.UUID, opts domain.ListOptions) ([]*domain.Deal, int64, error) {
 	return []*domain.Deal{}, 0, nil
 }
@@ -257,6 +308,10 @@ func (m *DealMockOpportunityRepository) GetByStage(ctx context.Context, tenantID
 	return []*domain.Opportunity{}, 0, nil
 }
 
+func (m *DealMockOpportunityRepository) GetBoardPositionNeighbors(ctx context.Context, tenantID, stageID, excludeID uuid.UUID, index int) (string, string, error) {
+	return "", "", nil
+}
+
 func (m *DealMockOpportunityRepository) GetByCustomer(ctx context.Context, tenantID, customerID uuid.UUID, opts domain.ListOptions) ([]*domain.Opportunity, int64, error) {
 	return []*domain.Opportunity{}, 0, nil
 }
@@ -270,7 +325,13 @@ func (m *DealMockOpportunityRepository) GetByLead(ctx context.Context, tenantID,
 }
 
 func (m *DealMockOpportunityRepository) GetByOwner(ctx context.Context, tenantID, ownerID uuid.UUID, opts domain.ListOptions) ([]*domain.Opportunity, int64, error) {
-	return []*domain.Opportunity{}, 0, nil
+	result := []*domain.Opportunity{}
+	for _, opp := range m.opportunities {
+		if opp.TenantID == tenantID && opp.OwnerID == ownerID {
+			result = append(result, opp)
+		}
+	}
+	return result, int64(len(result)), nil
 }
 
 func (m *DealMockOpportunityRepository) GetClosingThisMonth(ctx context.Context, tenantID uuid.UUID, opts domain.ListOptions) ([]*domain.Opportunity, int64, error) {
@@ -301,6 +362,10 @@ func (m *DealMockOpportunityRepository) GetWeightedPipelineValue(ctx context.Con
 	return 0, nil
 }
 
+func (m *DealMockOpportunityRepository) GetPipelineValueByCurrency(ctx context.Context, tenantID uuid.UUID) ([]*domain.PipelineCurrencyValue, error) {
+	return nil, nil
+}
+
 func (m *DealMockOpportunityRepository) BulkUpdateOwner(ctx context.Context, tenantID uuid.UUID, opportunityIDs []uuid.UUID, ownerID uuid.UUID) error {
 	return nil
 }
@@ -329,6 +394,10 @@ func (m *DealMockOpportunityRepository) GetAverageSalesCycle(ctx context.Context
 	return 0, nil
 }
 
+func (m *DealMockOpportunityRepository) GetCloseBreakdown(ctx context.Context, tenantID uuid.UUID, pipelineID *uuid.UUID, status domain.OpportunityStatus) (*domain.CloseBreakdown, error) {
+	return &domain.CloseBreakdown{}, nil
+}
+
 // DealMockEventPublisher is a mock implementation of ports.EventPublisher.
 type DealMockEventPublisher struct {
 	events []ports.Event
@@ -376,6 +445,16 @@ func (m *DealMockCustomerService) GetCustomer(ctx context.Context, tenantID, cus
 	return customer, nil
 }
 
+func (m *DealMockCustomerService) GetCustomersByIDs(ctx context.Context, tenantID uuid.UUID, customerIDs []uuid.UUID) ([]*ports.CustomerInfo, error) {
+	var result []*ports.CustomerInfo
+	for _, id := range customerIDs {
+		if customer, ok := m.customers[id]; ok {
+			result = append(result, customer)
+		}
+	}
+	return result, nil
+}
+
 func (m *DealMockCustomerService) GetCustomerByCode(ctx context.Context, tenantID uuid.UUID, code string) (*ports.CustomerInfo, error) {
 	for _, customer := range m.customers {
 		if customer.Code == code {
@@ -424,6 +503,16 @@ func (m *DealMockCustomerService) CreateContact(ctx context.Context, tenantID, c
 	return contact, nil
 }
 
+func (m *DealMockCustomerService) DeleteCustomer(ctx context.Context, tenantID, customerID uuid.UUID) error {
+	delete(m.customers, customerID)
+	return nil
+}
+
+func (m *DealMockCustomerService) DeleteContact(ctx context.Context, tenantID, customerID, contactID uuid.UUID) error {
+	delete(m.contacts, contactID)
+	return nil
+}
+
 // DealMockUserService is a mock implementation of ports.UserService.
 type DealMockUserService struct {
 	users map[uuid.UUID]*ports.UserInfo
@@ -700,7 +789,7 @@ func TestDealUseCase_GetByID_Success(t *testing.T) {
 	idGenerator := NewDealMockIDGenerator()
 	notificationSvc := NewDealMockNotificationService()
 
-	uc := NewDealUseCase(dealRepo, oppRepo, eventPublisher, customerService, userService, productService, cacheService, searchService, idGenerator, notificationSvc)
+	uc := NewDealUseCase(dealRepo, oppRepo, eventPublisher, customerService, userService, productService, cacheService, searchService, idGenerator, notificationSvc, nil)
 
 	tenantID := uuid.New()
 	deal := createDealTestDeal(tenantID)
@@ -740,7 +829,7 @@ func TestDealUseCase_GetByID_NotFound(t *testing.T) {
 	idGenerator := NewDealMockIDGenerator()
 	notificationSvc := NewDealMockNotificationService()
 
-	uc := NewDealUseCase(dealRepo, oppRepo, eventPublisher, customerService, userService, productService, cacheService, searchService, idGenerator, notificationSvc)
+	uc := NewDealUseCase(dealRepo, oppRepo, eventPublisher, customerService, userService, productService, cacheService, searchService, idGenerator, notificationSvc, nil)
 
 	// Act
 	_, err := uc.GetByID(context.Background(), uuid.New(), uuid.New())
@@ -768,7 +857,7 @@ func TestDealUseCase_GetByCode_Success(t *testing.T) {
 	idGenerator := NewDealMockIDGenerator()
 	notificationSvc := NewDealMockNotificationService()
 
-	uc := NewDealUseCase(dealRepo, oppRepo, eventPublisher, customerService, userService, productService, cacheService, searchService, idGenerator, notificationSvc)
+	uc := NewDealUseCase(dealRepo, oppRepo, eventPublisher, customerService, userService, productService, cacheService, searchService, idGenerator, notificationSvc, nil)
 
 	tenantID := uuid.New()
 	deal := createDealTestDeal(tenantID)
@@ -804,7 +893,7 @@ func TestDealUseCase_GetByCode_NotFound(t *testing.T) {
 	idGenerator := NewDealMockIDGenerator()
 	notificationSvc := NewDealMockNotificationService()
 
-	uc := NewDealUseCase(dealRepo, oppRepo, eventPublisher, customerService, userService, productService, cacheService, searchService, idGenerator, notificationSvc)
+	uc := NewDealUseCase(dealRepo, oppRepo, eventPublisher, customerService, userService, productService, cacheService, searchService, idGenerator, notificationSvc, nil)
 
 	// Act
 	_, err := uc.GetByCode(context.Background(), uuid.New(), "NONEXISTENT")
@@ -832,7 +921,7 @@ func TestDealUseCase_List_Success(t *testing.T) {
 	idGenerator := NewDealMockIDGenerator()
 	notificationSvc := NewDealMockNotificationService()
 
-	uc := NewDealUseCase(dealRepo, oppRepo, eventPublisher, customerService, userService, productService, cacheService, searchService, idGenerator, notificationSvc)
+	uc := NewDealUseCase(dealRepo, oppRepo, eventPublisher, customerService, userService, productService, cacheService, searchService, idGenerator, notificationSvc, nil)
 
 	tenantID := uuid.New()
 	deal1 := createDealTestDeal(tenantID)
@@ -877,7 +966,7 @@ func TestDealUseCase_Update_Success(t *testing.T) {
 	idGenerator := NewDealMockIDGenerator()
 	notificationSvc := NewDealMockNotificationService()
 
-	uc := NewDealUseCase(dealRepo, oppRepo, eventPublisher, customerService, userService, productService, cacheService, searchService, idGenerator, notificationSvc)
+	uc := NewDealUseCase(dealRepo, oppRepo, eventPublisher, customerService, userService, productService, cacheService, searchService, idGenerator, notificationSvc, nil)
 
 	tenantID := uuid.New()
 	userID := uuid.New()
@@ -920,7 +1009,7 @@ func TestDealUseCase_Update_DealNotFound(t *testing.T) {
 	idGenerator := NewDealMockIDGenerator()
 	notificationSvc := NewDealMockNotificationService()
 
-	uc := NewDealUseCase(dealRepo, oppRepo, eventPublisher, customerService, userService, productService, cacheService, searchService, idGenerator, notificationSvc)
+	uc := NewDealUseCase(dealRepo, oppRepo, eventPublisher, customerService, userService, productService, cacheService, searchService, idGenerator, notificationSvc, nil)
 
 	req := &dto.UpdateDealRequest{
 		Version: 1,
@@ -948,7 +1037,7 @@ func TestDealUseCase_Update_VersionMismatch(t *testing.T) {
 	idGenerator := NewDealMockIDGenerator()
 	notificationSvc := NewDealMockNotificationService()
 
-	uc := NewDealUseCase(dealRepo, oppRepo, eventPublisher, customerService, userService, productService, cacheService, searchService, idGenerator, notificationSvc)
+	uc := NewDealUseCase(dealRepo, oppRepo, eventPublisher, customerService, userService, productService, cacheService, searchService, idGenerator, notificationSvc, nil)
 
 	tenantID := uuid.New()
 	deal := createDealTestDeal(tenantID)
@@ -985,7 +1074,7 @@ func TestDealUseCase_Delete_Success(t *testing.T) {
 	idGenerator := NewDealMockIDGenerator()
 	notificationSvc := NewDealMockNotificationService()
 
-	uc := NewDealUseCase(dealRepo, oppRepo, eventPublisher, customerService, userService, productService, cacheService, searchService, idGenerator, notificationSvc)
+	uc := NewDealUseCase(dealRepo, oppRepo, eventPublisher, customerService, userService, productService, cacheService, searchService, idGenerator, notificationSvc, nil)
 
 	tenantID := uuid.New()
 	userID := uuid.New()
@@ -1014,7 +1103,7 @@ func TestDealUseCase_Delete_DealNotFound(t *testing.T) {
 	idGenerator := NewDealMockIDGenerator()
 	notificationSvc := NewDealMockNotificationService()
 
-	uc := NewDealUseCase(dealRepo, oppRepo, eventPublisher, customerService, userService, productService, cacheService, searchService, idGenerator, notificationSvc)
+	uc := NewDealUseCase(dealRepo, oppRepo, eventPublisher, customerService, userService, productService, cacheService, searchService, idGenerator, notificationSvc, nil)
 
 	// Act
 	err := uc.Delete(context.Background(), uuid.New(), uuid.New(), uuid.New())
@@ -1029,6 +1118,128 @@ func TestDealUseCase_Delete_DealNotFound(t *testing.T) {
 // Table-Driven Tests
 // ============================================================================
 
+func TestDealUseCase_MarkOverdueInvoices(t *testing.T) {
+	// Arrange
+	dealRepo := NewDealMockDealRepository()
+	eventPublisher := NewDealMockEventPublisher()
+	uc := NewDealUseCase(dealRepo, NewDealMockOpportunityRepository(), eventPublisher, NewDealMockCustomerService(), NewDealMockUserService(),
+		NewDealMockProductService(), NewDealMockCacheService(), NewDealMockSearchService(), NewDealMockIDGenerator(), NewDealMockNotificationService(), nil)
+
+	now := time.Now().UTC()
+	deal := createDealTestDeal(uuid.New())
+	deal.Invoices = []domain.Invoice{
+		{ID: uuid.New(), InvoiceNumber: "INV-100", Status: "sent", DueDate: now.AddDate(0, 0, -2), Amount: domain.Money{Amount: 150000, Currency: "MYR"}},
+		{ID: uuid.New(), InvoiceNumber: "INV-101", Status: "sent", DueDate: now.AddDate(0, 0, 5)},
+	}
+	dealRepo.deals[deal.ID] = deal
+	version := deal.Version
+
+	// Act
+	marked, err := uc.MarkOverdueInvoices(context.Background(), now)
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if marked != 1 {
+		t.Errorf("Expected 1 invoice marked, got %d", marked)
+	}
+	if deal.Invoices[0].Status != "overdue" || deal.Version != version+1 {
+		t.Errorf("Expected the invoice overdue and the deal saved, got status %s, version %d", deal.Invoices[0].Status, deal.Version)
+	}
+	if len(eventPublisher.events) != 1 || eventPublisher.events[0].Type != "deal.invoice_overdue" {
+		t.Fatalf("Expected one overdue event, got %+v", eventPublisher.events)
+	}
+	if payload := eventPublisher.events[0].Payload; payload["invoice_number"] != "INV-100" || payload["amount"] != float64(150000) {
+		t.Errorf("Expected the invoice in the payload, got %v", payload)
+	}
+
+	// Already overdue invoices are not marked again
+	if marked, _ := uc.MarkOverdueInvoices(context.Background(), now); marked != 0 {
+		t.Errorf("Expected no invoices marked on the second run, got %d", marked)
+	}
+}
+
+func createDealTestRecurringDeal(tenantID uuid.UUID, start time.Time, monthly int64) *domain.Deal {
+	deal := createDealTestDeal(tenantID)
+	deal.Currency = "MYR"
+	deal.Type = domain.DealTypeRecurring
+	deal.Subscription, _ = domain.NewDealSubscription(domain.BillingFrequencyMonthly,
+		domain.Money{Amount: monthly, Currency: "MYR"}, start, start.AddDate(1, 0, 0), 0)
+	return deal
+}
+
+func TestDealUseCase_Churn(t *testing.T) {
+	// Arrange
+	dealRepo := NewDealMockDealRepository()
+	eventPublisher := NewDealMockEventPublisher()
+	uc := NewDealUseCase(dealRepo, NewDealMockOpportunityRepository(), eventPublisher, NewDealMockCustomerService(), NewDealMockUserService(),
+		NewDealMockProductService(), NewDealMockCacheService(), NewDealMockSearchService(), NewDealMockIDGenerator(), NewDealMockNotificationService(), nil)
+
+	tenantID := uuid.New()
+	deal := createDealTestRecurringDeal(tenantID, time.Now().UTC().AddDate(0, -6, 0), 50000)
+	dealRepo.deals[deal.ID] = deal
+	oneTime := createDealTestDeal(tenantID)
+	dealRepo.deals[oneTime.ID] = oneTime
+
+	// Act
+	resp, err := uc.Churn(context.Background(), tenantID, deal.ID, uuid.New(), &dto.ChurnDealRequest{Reason: "Moved to another supplier"})
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if resp.Subscription == nil || resp.Subscription.RenewalStatus != "churned" || resp.Subscription.ChurnReason != "Moved to another supplier" {
+		t.Errorf("Expected the subscription churned, got %+v", resp.Subscription)
+	}
+	if len(eventPublisher.events) != 1 || eventPublisher.events[0].Type != "deal.churned" {
+		t.Errorf("Expected one churned event, got %+v", eventPublisher.events)
+	}
+
+	// Churning again or churning a one time deal fails
+	if _, err := uc.Churn(context.Background(), tenantID, deal.ID, uuid.New(), &dto.ChurnDealRequest{Reason: "again"}); err == nil {
+		t.Error("Expected an error churning a churned deal")
+	}
+	_, err = uc.Churn(context.Background(), tenantID, oneTime.ID, uuid.New(), &dto.ChurnDealRequest{Reason: "not recurring"})
+	if appErr := application.GetAppError(err); appErr == nil || appErr.Code != application.ErrCodeDealNotRecurring {
+		t.Errorf("Expected a not recurring error, got: %v", err)
+	}
+}
+
+func TestDealUseCase_GetRecurringRevenue(t *testing.T) {
+	// Arrange
+	dealRepo := NewDealMockDealRepository()
+	uc := NewDealUseCase(dealRepo, NewDealMockOpportunityRepository(), NewDealMockEventPublisher(), NewDealMockCustomerService(), NewDealMockUserService(),
+		NewDealMockProductService(), NewDealMockCacheService(), NewDealMockSearchService(), NewDealMockIDGenerator(), NewDealMockNotificationService(), nil)
+
+	tenantID := uuid.New()
+	renewed := createDealTestRecurringDeal(tenantID, time.Date(2025, 6, 5, 0, 0, 0, 0, time.UTC), 50000)
+	renewed.Subscription.RenewalStatus = domain.RenewalStatusRenewed
+	churned := createDealTestRecurringDeal(tenantID, time.Date(2025, 6, 10, 0, 0, 0, 0, time.UTC), 30000)
+	churned.Subscription.RenewalStatus = domain.RenewalStatusChurned
+	started := createDealTestRecurringDeal(tenantID, time.Date(2026, 6, 15, 0, 0, 0, 0, time.UTC), 20000)
+	for _, deal := range []*domain.Deal{renewed, churned, started} {
+		dealRepo.deals[deal.ID] = deal
+	}
+
+	// Act
+	report, err := uc.GetRecurringRevenue(context.Background(), tenantID, &dto.RecurringRevenueRequest{From: "2026-06-01", To: "2026-06-30"})
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if report.Currency != "MYR" || report.StartingMRR.Amount != 80000 || report.EndingMRR.Amount != 20000 || report.NewMRR.Amount != 20000 {
+		t.Errorf("Expected MRR 800.00 to 200.00 with 200.00 new, got %+v", report)
+	}
+	if report.EndingARR.Amount != 240000 {
+		t.Errorf("Expected ARR 2,400.00, got %d", report.EndingARR.Amount)
+	}
+	if report.RenewalsDue != 2 || report.Renewed != 1 || report.Churned != 1 || report.ChurnedMRR.Amount != 30000 || report.RenewalRate != 50 {
+		t.Errorf("Expected one renewal and one churn, got %+v", report)
+	}
+}
+
 func TestDealUseCase_GetByID_ValidationCases(t *testing.T) {
 	tests := []struct {
 		name      string
@@ -1082,7 +1293,7 @@ func TestDealUseCase_GetByID_ValidationCases(t *testing.T) {
 				}
 			}
 
-			uc := NewDealUseCase(dealRepo, oppRepo, eventPublisher, customerService, userService, productService, cacheService, searchService, idGenerator, notificationSvc)
+			uc := NewDealUseCase(dealRepo, oppRepo, eventPublisher, customerService, userService, productService, cacheService, searchService, idGenerator, notificationSvc, nil)
 
 			_, err := uc.GetByID(context.Background(), tt.tenantID, tt.dealID)
 
@@ -1117,7 +1328,7 @@ func BenchmarkDealUseCase_GetByID(b *testing.B) {
 	dealRepo.deals[deal.ID] = deal
 	customerService.customers[deal.CustomerID] = &ports.CustomerInfo{ID: deal.CustomerID, Name: "Customer"}
 
-	uc := NewDealUseCase(dealRepo, oppRepo, eventPublisher, customerService, userService, productService, cacheService, searchService, idGenerator, notificationSvc)
+	uc := NewDealUseCase(dealRepo, oppRepo, eventPublisher, customerService, userService, productService, cacheService, searchService, idGenerator, notificationSvc, nil)
 	ctx := context.Background()
 
 	b.ResetTimer()
@@ -1145,7 +1356,7 @@ func BenchmarkDealUseCase_List(b *testing.B) {
 		customerService.customers[deal.CustomerID] = &ports.CustomerInfo{ID: deal.CustomerID, Name: "Customer"}
 	}
 
-	uc := NewDealUseCase(dealRepo, oppRepo, eventPublisher, customerService, userService, productService, cacheService, searchService, idGenerator, notificationSvc)
+	uc := NewDealUseCase(dealRepo, oppRepo, eventPublisher, customerService, userService, productService, cacheService, searchService, idGenerator, notificationSvc, nil)
 	ctx := context.Background()
 
 	filter := &dto.DealFilterRequest{Page: 1, PageSize: 10}
@@ -1173,7 +1384,7 @@ func TestDealUseCase_GetByID_ContextTimeout(t *testing.T) {
 	idGenerator := NewDealMockIDGenerator()
 	notificationSvc := NewDealMockNotificationService()
 
-	uc := NewDealUseCase(dealRepo, oppRepo, eventPublisher, customerService, userService, productService, cacheService, searchService, idGenerator, notificationSvc)
+	uc := NewDealUseCase(dealRepo, oppRepo, eventPublisher, customerService, userService, productService, cacheService, searchService, idGenerator, notificationSvc, nil)
 
 	tenantID := uuid.New()
 	deal := createDealTestDeal(tenantID)
@@ -0,0 +1,196 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/kilang-desa-murni/crm/internal/sales/application"
+	"github.com/kilang-desa-murni/crm/internal/sales/application/dto"
+	"github.com/kilang-desa-murni/crm/internal/sales/domain"
+	"github.com/kilang-desa-murni/crm/pkg/demodata"
+)
+
+// ============================================================================
+// Demo Data Use Case Interface
+// ============================================================================
+
+// DemoDataUseCase defines the interface for seeding a tenant's demo data.
+type DemoDataUseCase interface {
+	// Seed creates the demo pipeline, leads and opportunities of the tenant
+	// that do not exist yet.
+	Seed(ctx context.Context, tenantID, userID uuid.UUID) (*dto.DemoDataResponse, error)
+
+	// Purge deletes the demo pipeline, leads and opportunities of the tenant.
+	Purge(ctx context.Context, tenantID uuid.UUID) (*dto.DemoDataResponse, error)
+}
+
+// ============================================================================
+// Demo Data Use Case Implementation
+// ============================================================================
+
+// demoDataUseCase implements DemoDataUseCase.
+type demoDataUseCase struct {
+	demoRepo        domain.DemoDataRepository
+	pipelineRepo    domain.PipelineRepository
+	leadRepo        domain.LeadRepository
+	opportunityRepo domain.OpportunityRepository
+}
+
+// NewDemoDataUseCase creates a new demo data use case. Seeded records publish
+// no events, so demo data never reaches other tenants' integrations or
+// notifications.
+func NewDemoDataUseCase(
+	demoRepo domain.DemoDataRepository,
+	pipelineRepo domain.PipelineRepository,
+	leadRepo domain.LeadRepository,
+	opportunityRepo domain.OpportunityRepository,
+) DemoDataUseCase {
+	return &demoDataUseCase{
+		demoRepo:        demoRepo,
+		pipelineRepo:    pipelineRepo,
+		leadRepo:        leadRepo,
+		opportunityRepo: opportunityRepo,
+	}
+}
+
+// Seed creates the demo pipeline, leads and opportunities of the tenant that
+// do not exist yet.
+func (uc *demoDataUseCase) Seed(ctx context.Context, tenantID, userID uuid.UUID) (*dto.DemoDataResponse, error) {
+	ds := demodata.For(tenantID)
+
+	existing, err := uc.demoRepo.FindExisting(ctx, tenantID, salesDemoIDs(ds))
+	if err != nil {
+		return nil, application.WrapError(application.ErrCodeInternal, "failed to find demo data", err)
+	}
+
+	result := &dto.DemoDataResponse{}
+
+	var pipeline *domain.Pipeline
+	if existing[ds.Pipeline.ID] {
+		result.Existing++
+		pipeline, err = uc.pipelineRepo.GetByID(ctx, tenantID, ds.Pipeline.ID)
+		if err != nil {
+			return nil, application.ErrConflict("the demo pipeline was deleted; purge the demo data before seeding it again")
+		}
+	} else {
+		pipeline, err = newDemoPipeline(ds, userID)
+		if err != nil {
+			return nil, application.WrapError(application.ErrCodeInternal, "failed to build demo pipeline", err)
+		}
+		if err := uc.pipelineRepo.Create(ctx, pipeline); err != nil {
+			return nil, application.WrapError(application.ErrCodeInternal, "failed to create demo pipeline", err)
+		}
+		result.Created++
+	}
+
+	for _, fixture := range ds.Leads {
+		if existing[fixture.ID] {
+			result.Existing++
+			continue
+		}
+		lead, err := domain.NewLead(tenantID,
+			domain.LeadContact{
+				FirstName: fixture.FirstName,
+				LastName:  fixture.LastName,
+				Email:     fixture.Email,
+				Phone:     fixture.Phone,
+				JobTitle:  fixture.JobTitle,
+			},
+			domain.LeadCompany{Name: fixture.Company, City: fixture.City, Country: "MY"},
+			domain.LeadSource(fixture.Source),
+			userID,
+		)
+		if err != nil {
+			return nil, application.WrapError(application.ErrCodeInternal, "failed to build demo lead", err)
+		}
+		lead.ID = fixture.ID
+		lead.AddTag("demo")
+		if err := uc.leadRepo.Create(ctx, lead); err != nil {
+			return nil, application.WrapError(application.ErrCodeInternal, "failed to create demo lead", err)
+		}
+		result.Created++
+	}
+
+	now := time.Now().UTC()
+	for _, fixture := range ds.Opportunities {
+		if existing[fixture.ID] {
+			result.Existing++
+			continue
+		}
+		opp, err := newDemoOpportunity(ds, pipeline, fixture, userID, now)
+		if err != nil {
+			return nil, application.WrapError(application.ErrCodeInternal, "failed to build demo opportunity", err)
+		}
+		if err := uc.opportunityRepo.Create(ctx, opp); err != nil {
+			return nil, application.WrapError(application.ErrCodeInternal, "failed to create demo opportunity", err)
+		}
+		result.Created++
+	}
+
+	return result, nil
+}
+
+// Purge deletes the demo pipeline, leads and opportunities of the tenant.
+// Records other data depends on, like an opportunity that became a deal,
+// are kept.
+func (uc *demoDataUseCase) Purge(ctx context.Context, tenantID uuid.UUID) (*dto.DemoDataResponse, error) {
+	deleted, err := uc.demoRepo.Purge(ctx, tenantID, salesDemoIDs(demodata.For(tenantID)))
+	if err != nil {
+		return nil, application.WrapError(application.ErrCodeInternal, "failed to purge demo data", err)
+	}
+	return &dto.DemoDataResponse{Deleted: deleted}, nil
+}
+
+// salesDemoIDs returns the IDs of the demo records the sales service owns.
+func salesDemoIDs(ds *demodata.DataSet) []uuid.UUID {
+	ids := []uuid.UUID{ds.Pipeline.ID}
+	for _, l := range ds.Leads {
+		ids = append(ids, l.ID)
+	}
+	for _, o := range ds.Opportunities {
+		ids = append(ids, o.ID)
+	}
+	return ids
+}
+
+// newDemoPipeline builds the demo pipeline with the default stages, giving it
+// and its stages their demo IDs.
+func newDemoPipeline(ds *demodata.DataSet, userID uuid.UUID) (*domain.Pipeline, error) {
+	pipeline, err := domain.NewPipeline(ds.TenantID, ds.Pipeline.Name, demodata.Currency, userID)
+	if err != nil {
+		return nil, err
+	}
+	pipeline.ID = ds.Pipeline.ID
+	for _, stage := range pipeline.Stages {
+		stage.ID = demodata.ID(ds.TenantID, demodata.KindStage, stage.Order)
+		stage.PipelineID = pipeline.ID
+	}
+	return pipeline, nil
+}
+
+// newDemoOpportunity builds a demo opportunity in its stage of the pipeline.
+func newDemoOpportunity(ds *demodata.DataSet, pipeline *domain.Pipeline, fixture demodata.Opportunity, userID uuid.UUID, now time.Time) (*domain.Opportunity, error) {
+	amount, err := domain.NewMoney(fixture.Amount, demodata.Currency)
+	if err != nil {
+		return nil, err
+	}
+
+	opp, err := domain.NewOpportunity(ds.TenantID, fixture.Name, pipeline, fixture.CustomerID, fixture.Customer, amount, userID, "", userID)
+	if err != nil {
+		return nil, err
+	}
+	opp.ID = fixture.ID
+
+	stage := pipeline.GetStage(demodata.ID(ds.TenantID, demodata.KindStage, fixture.Stage))
+	if stage != nil && stage.ID != opp.StageID {
+		if err := opp.MoveToStage(stage, userID, ""); err != nil {
+			return nil, err
+		}
+	}
+	opp.SetExpectedCloseDate(now.Add(fixture.CloseIn))
+	opp.AddTag("demo")
+
+	return opp, nil
+}
@@ -0,0 +1,157 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"github.com/kilang-desa-murni/crm/internal/sales/domain"
+	"github.com/kilang-desa-murni/crm/pkg/demodata"
+)
+
+// ============================================================================
+// Mock Implementations for Demo Data Tests
+// ============================================================================
+
+// MockDemoDataRepository is a mock implementation of domain.DemoDataRepository
+// backed by the other mock repositories.
+type MockDemoDataRepository struct {
+	pipelines     *MockPipelineRepository
+	leads         *MockLeadRepository
+	opportunities *MockOpportunityRepository
+}
+
+func (m *MockDemoDataRepository) FindExisting(ctx context.Context, tenantID uuid.UUID, ids []uuid.UUID) (map[uuid.UUID]bool, error) {
+	existing := make(map[uuid.UUID]bool)
+	for _, id := range ids {
+		_, pipeline := m.pipelines.pipelines[id]
+		_, lead := m.leads.leads[id]
+		_, opp := m.opportunities.opportunities[id]
+		if pipeline || lead || opp {
+			existing[id] = true
+		}
+	}
+	return existing, nil
+}
+
+func (m *MockDemoDataRepository) Purge(ctx context.Context, tenantID uuid.UUID, ids []uuid.UUID) (int64, error) {
+	var deleted int64
+	for _, id := range ids {
+		if _, ok := m.pipelines.pipelines[id]; ok {
+			delete(m.pipelines.pipelines, id)
+			deleted++
+		}
+		if _, ok := m.leads.leads[id]; ok {
+			delete(m.leads.leads, id)
+			deleted++
+		}
+		if _, ok := m.opportunities.opportunities[id]; ok {
+			delete(m.opportunities.opportunities, id)
+			deleted++
+		}
+	}
+	return deleted, nil
+}
+
+func newTestDemoDataUseCase() (DemoDataUseCase, *MockDemoDataRepository) {
+	repo := &MockDemoDataRepository{
+		pipelines:     NewMockPipelineRepository(),
+		leads:         NewMockLeadRepository(),
+		opportunities: NewMockOpportunityRepository(),
+	}
+	return NewDemoDataUseCase(repo, repo.pipelines, repo.leads, repo.opportunities), repo
+}
+
+// ============================================================================
+// Demo Data Use Case Tests
+// ============================================================================
+
+func TestDemoDataUseCase_Seed(t *testing.T) {
+	uc, repo := newTestDemoDataUseCase()
+	tenantID := uuid.New()
+	ds := demodata.For(tenantID)
+
+	result, err := uc.Seed(context.Background(), tenantID, uuid.New())
+	if err != nil {
+		t.Fatalf("Seed() error = %v", err)
+	}
+
+	want := int64(1 + len(ds.Leads) + len(ds.Opportunities))
+	if result.Created != want || result.Existing != 0 {
+		t.Errorf("Seed() = %+v, want %d created", result, want)
+	}
+
+	for _, fixture := range ds.Opportunities {
+		opp := repo.opportunities.opportunities[fixture.ID]
+		if opp == nil {
+			t.Fatalf("opportunity %q was not created", fixture.Name)
+		}
+		if opp.PipelineID != ds.Pipeline.ID {
+			t.Errorf("opportunity %q is in pipeline %s, want the demo pipeline", fixture.Name, opp.PipelineID)
+		}
+		if want := demodata.ID(tenantID, demodata.KindStage, fixture.Stage); opp.StageID != want {
+			t.Errorf("opportunity %q is in stage %s, want %s", fixture.Name, opp.StageID, want)
+		}
+		if opp.CustomerID != fixture.CustomerID {
+			t.Errorf("opportunity %q has customer %s, want %s", fixture.Name, opp.CustomerID, fixture.CustomerID)
+		}
+	}
+}
+
+func TestDemoDataUseCase_Seed_IsIdempotent(t *testing.T) {
+	uc, repo := newTestDemoDataUseCase()
+	tenantID := uuid.New()
+	userID := uuid.New()
+	ds := demodata.For(tenantID)
+
+	if _, err := uc.Seed(context.Background(), tenantID, userID); err != nil {
+		t.Fatalf("Seed() error = %v", err)
+	}
+
+	// Deleting one lead means only that lead is seeded again
+	delete(repo.leads.leads, ds.Leads[0].ID)
+
+	result, err := uc.Seed(context.Background(), tenantID, userID)
+	if err != nil {
+		t.Fatalf("Seed() error = %v", err)
+	}
+	if result.Created != 1 {
+		t.Errorf("Seed() created %d records, want 1", result.Created)
+	}
+	if want := int64(len(ds.Leads) + len(ds.Opportunities)); result.Existing != want {
+		t.Errorf("Seed() found %d existing records, want %d", result.Existing, want)
+	}
+	if len(repo.opportunities.opportunities) != len(ds.Opportunities) {
+		t.Errorf("got %d opportunities, want %d", len(repo.opportunities.opportunities), len(ds.Opportunities))
+	}
+}
+
+func TestDemoDataUseCase_Purge_KeepsOtherRecords(t *testing.T) {
+	uc, repo := newTestDemoDataUseCase()
+	tenantID := uuid.New()
+	userID := uuid.New()
+
+	if _, err := uc.Seed(context.Background(), tenantID, userID); err != nil {
+		t.Fatalf("Seed() error = %v", err)
+	}
+
+	own, _ := domain.NewLead(tenantID, domain.LeadContact{FirstName: "Real", LastName: "Lead", Email: "real@example.com"}, domain.LeadCompany{Name: "Real Co"}, domain.LeadSourceWebsite, userID)
+	repo.leads.leads[own.ID] = own
+
+	result, err := uc.Purge(context.Background(), tenantID)
+	if err != nil {
+		t.Fatalf("Purge() error = %v", err)
+	}
+
+	ds := demodata.For(tenantID)
+	if want := int64(1 + len(ds.Leads) + len(ds.Opportunities)); result.Deleted != want {
+		t.Errorf("Purge() deleted %d records, want %d", result.Deleted, want)
+	}
+	if _, ok := repo.leads.leads[own.ID]; !ok {
+		t.Error("Purge() deleted a lead that was not demo data")
+	}
+	if len(repo.leads.leads) != 1 || len(repo.opportunities.opportunities) != 0 || len(repo.pipelines.pipelines) != 0 {
+		t.Error("Purge() left demo records behind")
+	}
+}
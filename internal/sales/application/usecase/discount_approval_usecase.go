@@ -0,0 +1,543 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+
+	"github.com/kilang-desa-murni/crm/internal/sales/application"
+	"github.com/kilang-desa-murni/crm/internal/sales/application/dto"
+	"github.com/kilang-desa-murni/crm/internal/sales/application/ports"
+	"github.com/kilang-desa-murni/crm/internal/sales/domain"
+)
+
+// ============================================================================
+// Discount Approval Use Case Interface
+// ============================================================================
+
+// DiscountApprovalUseCase defines the interface for discount approval rules and
+// the approvals opportunities need for discounts above them.
+type DiscountApprovalUseCase interface {
+	// Rules
+	CreateRule(ctx context.Context, tenantID uuid.UUID, req *dto.CreateDiscountApprovalRuleRequest) (*dto.DiscountApprovalRuleResponse, error)
+	GetRule(ctx context.Context, tenantID, ruleID uuid.UUID) (*dto.DiscountApprovalRuleResponse, error)
+	UpdateRule(ctx context.Context, tenantID, ruleID uuid.UUID, req *dto.UpdateDiscountApprovalRuleRequest) (*dto.DiscountApprovalRuleResponse, error)
+	DeleteRule(ctx context.Context, tenantID, ruleID uuid.UUID) error
+	ListRules(ctx context.Context, tenantID uuid.UUID) (*dto.DiscountApprovalRuleListResponse, error)
+
+	// Approvals
+	Submit(ctx context.Context, tenantID, opportunityID, userID uuid.UUID, req *dto.SubmitDiscountApprovalRequest) (*dto.DiscountApprovalResponse, error)
+	GetByID(ctx context.Context, tenantID, approvalID uuid.UUID) (*dto.DiscountApprovalResponse, error)
+	ListByOpportunity(ctx context.Context, tenantID, opportunityID uuid.UUID) (*dto.OpportunityDiscountApprovalsResponse, error)
+	// ListPending lists the pending approvals the user can decide.
+	ListPending(ctx context.Context, tenantID, userID uuid.UUID, req *dto.ListDiscountApprovalsRequest) (*dto.DiscountApprovalListResponse, error)
+	Approve(ctx context.Context, tenantID, approvalID, userID uuid.UUID, req *dto.DecideDiscountApprovalRequest) (*dto.DiscountApprovalResponse, error)
+	Reject(ctx context.Context, tenantID, approvalID, userID uuid.UUID, req *dto.DecideDiscountApprovalRequest) (*dto.DiscountApprovalResponse, error)
+}
+
+// ============================================================================
+// Discount Approval Use Case Implementation
+// ============================================================================
+
+// discountApprovalUseCase implements DiscountApprovalUseCase.
+type discountApprovalUseCase struct {
+	approvalRepo    domain.DiscountApprovalRepository
+	opportunityRepo domain.OpportunityRepository
+	eventPublisher  ports.EventPublisher
+	notificationSvc ports.NotificationService
+}
+
+// NewDiscountApprovalUseCase creates a new discount approval use case.
+// Approvers and requesters are notified in-app when notificationSvc is set.
+func NewDiscountApprovalUseCase(
+	approvalRepo domain.DiscountApprovalRepository,
+	opportunityRepo domain.OpportunityRepository,
+	eventPublisher ports.EventPublisher,
+	notificationSvc ports.NotificationService,
+) DiscountApprovalUseCase {
+	return &discountApprovalUseCase{
+		approvalRepo:    approvalRepo,
+		opportunityRepo: opportunityRepo,
+		eventPublisher:  eventPublisher,
+		notificationSvc: notificationSvc,
+	}
+}
+
+// ============================================================================
+// Rules
+// ============================================================================
+
+// CreateRule adds a discount approval rule.
+func (uc *discountApprovalUseCase) CreateRule(ctx context.Context, tenantID uuid.UUID, req *dto.CreateDiscountApprovalRuleRequest) (*dto.DiscountApprovalRuleResponse, error) {
+	approverIDs, err := parseApproverIDs(req.ApproverIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	thresholdAmount := domain.Money{Amount: req.ThresholdAmount, Currency: strings.ToUpper(req.Currency)}
+	rule, err := domain.NewDiscountApprovalRule(tenantID, req.Name, domain.DiscountThresholdType(req.ThresholdType),
+		req.ThresholdPercent, thresholdAmount, approverIDs)
+	if err != nil {
+		return nil, application.ErrValidation(err.Error())
+	}
+
+	if err := uc.approvalRepo.CreateRule(ctx, rule); err != nil {
+		return nil, application.WrapError(application.ErrCodeInternal, "failed to create discount approval rule", err)
+	}
+
+	return mapDiscountApprovalRuleToResponse(rule), nil
+}
+
+// GetRule retrieves a discount approval rule.
+func (uc *discountApprovalUseCase) GetRule(ctx context.Context, tenantID, ruleID uuid.UUID) (*dto.DiscountApprovalRuleResponse, error) {
+	rule, err := uc.getRule(ctx, tenantID, ruleID)
+	if err != nil {
+		return nil, err
+	}
+	return mapDiscountApprovalRuleToResponse(rule), nil
+}
+
+// UpdateRule updates a discount approval rule. Pending approvals keep the
+// approvers they were requested from.
+func (uc *discountApprovalUseCase) UpdateRule(ctx context.Context, tenantID, ruleID uuid.UUID, req *dto.UpdateDiscountApprovalRuleRequest) (*dto.DiscountApprovalRuleResponse, error) {
+	rule, err := uc.getRule(ctx, tenantID, ruleID)
+	if err != nil {
+		return nil, err
+	}
+
+	name, thresholdType := rule.Name, rule.ThresholdType
+	thresholdPercent, thresholdAmount, approverIDs := rule.ThresholdPercent, rule.ThresholdAmount, rule.ApproverIDs
+	if req.Name != nil {
+		name = *req.Name
+	}
+	if req.ThresholdType != nil {
+		thresholdType = domain.DiscountThresholdType(*req.ThresholdType)
+		thresholdPercent = req.ThresholdPercent
+		thresholdAmount = domain.Money{Amount: req.ThresholdAmount, Currency: strings.ToUpper(req.Currency)}
+	}
+	if len(req.ApproverIDs) > 0 {
+		if approverIDs, err = parseApproverIDs(req.ApproverIDs); err != nil {
+			return nil, err
+		}
+	}
+	if err := rule.Update(name, thresholdType, thresholdPercent, thresholdAmount, approverIDs); err != nil {
+		return nil, application.ErrValidation(err.Error())
+	}
+
+	if req.IsActive != nil {
+		if *req.IsActive {
+			rule.Activate()
+		} else {
+			rule.Deactivate()
+		}
+	}
+
+	if err := uc.approvalRepo.UpdateRule(ctx, rule); err != nil {
+		return nil, application.WrapError(application.ErrCodeInternal, "failed to update discount approval rule", err)
+	}
+
+	return mapDiscountApprovalRuleToResponse(rule), nil
+}
+
+// DeleteRule deletes a discount approval rule.
+func (uc *discountApprovalUseCase) DeleteRule(ctx context.Context, tenantID, ruleID uuid.UUID) error {
+	if err := uc.approvalRepo.DeleteRule(ctx, tenantID, ruleID); err != nil {
+		if errors.Is(err, domain.ErrDiscountApprovalRuleNotFound) {
+			return application.ErrDiscountApprovalRuleNotFound(ruleID)
+		}
+		return application.WrapError(application.ErrCodeInternal, "failed to delete discount approval rule", err)
+	}
+	return nil
+}
+
+// ListRules lists the tenant's discount approval rules.
+func (uc *discountApprovalUseCase) ListRules(ctx context.Context, tenantID uuid.UUID) (*dto.DiscountApprovalRuleListResponse, error) {
+	rules, err := uc.approvalRepo.ListRules(ctx, tenantID, false)
+	if err != nil {
+		return nil, application.WrapError(application.ErrCodeInternal, "failed to list discount approval rules", err)
+	}
+
+	resp := &dto.DiscountApprovalRuleListResponse{Rules: make([]*dto.DiscountApprovalRuleResponse, len(rules))}
+	for i, rule := range rules {
+		resp.Rules[i] = mapDiscountApprovalRuleToResponse(rule)
+	}
+	return resp, nil
+}
+
+func (uc *discountApprovalUseCase) getRule(ctx context.Context, tenantID, ruleID uuid.UUID) (*domain.DiscountApprovalRule, error) {
+	rule, err := uc.approvalRepo.GetRule(ctx, tenantID, ruleID)
+	if err != nil {
+		if errors.Is(err, domain.ErrDiscountApprovalRuleNotFound) {
+			return nil, application.ErrDiscountApprovalRuleNotFound(ruleID)
+		}
+		return nil, application.WrapError(application.ErrCodeInternal, "failed to get discount approval rule", err)
+	}
+	return rule, nil
+}
+
+// ============================================================================
+// Approvals
+// ============================================================================
+
+// Submit requests approval of an opportunity's current discount from the
+// approvers of the rules it exceeds. A pending approval of the opportunity is
+// cancelled, as the new request supersedes it.
+func (uc *discountApprovalUseCase) Submit(ctx context.Context, tenantID, opportunityID, userID uuid.UUID, req *dto.SubmitDiscountApprovalRequest) (*dto.DiscountApprovalResponse, error) {
+	opportunity, err := uc.opportunityRepo.GetByID(ctx, tenantID, opportunityID)
+	if err != nil {
+		return nil, application.ErrOpportunityNotFound(opportunityID)
+	}
+
+	rules, err := uc.approvalRepo.ListRules(ctx, tenantID, true)
+	if err != nil {
+		return nil, application.WrapError(application.ErrCodeInternal, "failed to list discount approval rules", err)
+	}
+
+	approval, err := domain.NewDiscountApproval(opportunity, rules, userID, req.Comment)
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrOpportunityAlreadyClosed):
+			return nil, application.ErrOpportunityClosed(opportunityID)
+		case errors.Is(err, domain.ErrDiscountApprovalNotRequired):
+			return nil, application.ErrDiscountApprovalNotRequired(opportunityID)
+		}
+		return nil, application.ErrValidation(err.Error())
+	}
+
+	latest, err := uc.approvalRepo.GetLatestByOpportunity(ctx, tenantID, opportunityID)
+	if err != nil {
+		return nil, application.WrapError(application.ErrCodeInternal, "failed to get discount approval", err)
+	}
+	if latest != nil && latest.Status == domain.DiscountApprovalStatusPending {
+		if err := latest.Cancel(); err == nil {
+			latest.Version++
+			if err := uc.approvalRepo.Update(ctx, latest); err != nil {
+				return nil, application.WrapError(application.ErrCodeInternal, "failed to cancel discount approval", err)
+			}
+		}
+	}
+
+	if err := uc.approvalRepo.Create(ctx, approval); err != nil {
+		return nil, application.WrapError(application.ErrCodeInternal, "failed to create discount approval", err)
+	}
+
+	uc.publishEvents(ctx, approval)
+	uc.notifyApprovers(ctx, approval)
+
+	return mapDiscountApprovalToResponse(approval), nil
+}
+
+// GetByID retrieves a discount approval.
+func (uc *discountApprovalUseCase) GetByID(ctx context.Context, tenantID, approvalID uuid.UUID) (*dto.DiscountApprovalResponse, error) {
+	approval, err := uc.getApproval(ctx, tenantID, approvalID)
+	if err != nil {
+		return nil, err
+	}
+	return mapDiscountApprovalToResponse(approval), nil
+}
+
+// ListByOpportunity returns an opportunity's current discount, whether it
+// still needs approval, and the approvals requested for it.
+func (uc *discountApprovalUseCase) ListByOpportunity(ctx context.Context, tenantID, opportunityID uuid.UUID) (*dto.OpportunityDiscountApprovalsResponse, error) {
+	opportunity, err := uc.opportunityRepo.GetByID(ctx, tenantID, opportunityID)
+	if err != nil {
+		return nil, application.ErrOpportunityNotFound(opportunityID)
+	}
+
+	approvals, err := uc.approvalRepo.ListByOpportunity(ctx, tenantID, opportunityID)
+	if err != nil {
+		return nil, application.WrapError(application.ErrCodeInternal, "failed to list discount approvals", err)
+	}
+
+	discount := domain.OpportunityDiscount(opportunity)
+	resp := &dto.OpportunityDiscountApprovalsResponse{
+		DiscountAmount:  discount.Amount.Amount,
+		DiscountPercent: discount.Percent,
+		Currency:        discount.Amount.Currency,
+		Approvals:       make([]*dto.DiscountApprovalResponse, len(approvals)),
+	}
+	for i, approval := range approvals {
+		resp.Approvals[i] = mapDiscountApprovalToResponse(approval)
+	}
+
+	if resp.ApprovalRequired, _, err = discountApprovalPending(ctx, uc.approvalRepo, opportunity); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// ListPending lists the pending approvals the user can decide, oldest first.
+func (uc *discountApprovalUseCase) ListPending(ctx context.Context, tenantID, userID uuid.UUID, req *dto.ListDiscountApprovalsRequest) (*dto.DiscountApprovalListResponse, error) {
+	page, pageSize := req.Page, req.PageSize
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 {
+		pageSize = 20
+	}
+
+	approvals, total, err := uc.approvalRepo.ListPendingForApprover(ctx, tenantID, userID, domain.ListOptions{Page: page, PageSize: pageSize})
+	if err != nil {
+		return nil, application.WrapError(application.ErrCodeInternal, "failed to list pending discount approvals", err)
+	}
+
+	resp := &dto.DiscountApprovalListResponse{
+		Approvals:  make([]*dto.DiscountApprovalResponse, len(approvals)),
+		Pagination: dto.NewPaginationResponse(page, pageSize, total),
+	}
+	for i, approval := range approvals {
+		resp.Approvals[i] = mapDiscountApprovalToResponse(approval)
+	}
+	return resp, nil
+}
+
+// Approve grants a discount.
+func (uc *discountApprovalUseCase) Approve(ctx context.Context, tenantID, approvalID, userID uuid.UUID, req *dto.DecideDiscountApprovalRequest) (*dto.DiscountApprovalResponse, error) {
+	return uc.decide(ctx, tenantID, approvalID, func(approval *domain.DiscountApproval) error {
+		return approval.Approve(userID, req.Comment)
+	})
+}
+
+// Reject refuses a discount; the comment tells the requester why.
+func (uc *discountApprovalUseCase) Reject(ctx context.Context, tenantID, approvalID, userID uuid.UUID, req *dto.DecideDiscountApprovalRequest) (*dto.DiscountApprovalResponse, error) {
+	return uc.decide(ctx, tenantID, approvalID, func(approval *domain.DiscountApproval) error {
+		return approval.Reject(userID, req.Comment)
+	})
+}
+
+func (uc *discountApprovalUseCase) decide(ctx context.Context, tenantID, approvalID uuid.UUID, decide func(*domain.DiscountApproval) error) (*dto.DiscountApprovalResponse, error) {
+	approval, err := uc.getApproval(ctx, tenantID, approvalID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := decide(approval); err != nil {
+		switch {
+		case errors.Is(err, domain.ErrDiscountApprovalAlreadyDecided):
+			return nil, application.ErrDiscountApprovalDecided(approvalID, string(approval.Status))
+		case errors.Is(err, domain.ErrNotDiscountApprover):
+			return nil, application.ErrForbidden(err.Error())
+		}
+		return nil, application.ErrValidation(err.Error())
+	}
+
+	approval.Version++
+	if err := uc.approvalRepo.Update(ctx, approval); err != nil {
+		return nil, application.WrapError(application.ErrCodeInternal, "failed to update discount approval", err)
+	}
+
+	uc.publishEvents(ctx, approval)
+	uc.notifyRequester(ctx, approval)
+
+	return mapDiscountApprovalToResponse(approval), nil
+}
+
+func (uc *discountApprovalUseCase) getApproval(ctx context.Context, tenantID, approvalID uuid.UUID) (*domain.DiscountApproval, error) {
+	approval, err := uc.approvalRepo.GetByID(ctx, tenantID, approvalID)
+	if err != nil {
+		if errors.Is(err, domain.ErrDiscountApprovalNotFound) {
+			return nil, application.ErrDiscountApprovalNotFound(approvalID)
+		}
+		return nil, application.WrapError(application.ErrCodeInternal, "failed to get discount approval", err)
+	}
+	return approval, nil
+}
+
+// requireDiscountApproval returns an error unless the opportunity's discount
+// is within the tenant's active rules or covered by an approval. Sending the
+// quote and winning the opportunity call it; no check is made without a
+// repository.
+func requireDiscountApproval(ctx context.Context, approvalRepo domain.DiscountApprovalRepository, opportunity *domain.Opportunity) error {
+	required, latest, err := discountApprovalPending(ctx, approvalRepo, opportunity)
+	if err != nil || !required {
+		return err
+	}
+
+	status := ""
+	if latest != nil {
+		status = string(latest.Status)
+	}
+	return application.ErrDiscountApprovalRequired(opportunity.ID, status)
+}
+
+// discountApprovalPending reports whether the opportunity's discount needs an
+// approval it does not have, along with its latest approval if one was loaded.
+func discountApprovalPending(ctx context.Context, approvalRepo domain.DiscountApprovalRepository, opportunity *domain.Opportunity) (bool, *domain.DiscountApproval, error) {
+	if approvalRepo == nil {
+		return false, nil, nil
+	}
+
+	rules, err := approvalRepo.ListRules(ctx, opportunity.TenantID, true)
+	if err != nil {
+		return false, nil, application.WrapError(application.ErrCodeInternal, "failed to list discount approval rules", err)
+	}
+	if len(domain.ExceededDiscountRules(rules, domain.OpportunityDiscount(opportunity))) == 0 {
+		return false, nil, nil
+	}
+
+	latest, err := approvalRepo.GetLatestByOpportunity(ctx, opportunity.TenantID, opportunity.ID)
+	if err != nil {
+		return false, nil, application.WrapError(application.ErrCodeInternal, "failed to get discount approval", err)
+	}
+	return domain.CheckDiscountApproval(opportunity, rules, latest) != nil, latest, nil
+}
+
+// ============================================================================
+// Events and Notifications
+// ============================================================================
+
+func (uc *discountApprovalUseCase) publishEvents(ctx context.Context, approval *domain.DiscountApproval) {
+	if uc.eventPublisher != nil {
+		for _, event := range approval.GetEvents() {
+			// The payload carries the approvers and requester consumers notify
+			var payload map[string]interface{}
+			if data, err := json.Marshal(event); err == nil {
+				json.Unmarshal(data, &payload)
+			}
+
+			_ = uc.eventPublisher.Publish(ctx, ports.Event{
+				ID:            event.EventID().String(),
+				Type:          event.EventType(),
+				AggregateID:   event.AggregateID().String(),
+				AggregateType: event.AggregateType(),
+				TenantID:      event.TenantID().String(),
+				Payload:       payload,
+				OccurredAt:    event.OccurredAt(),
+				Version:       event.Version(),
+			})
+		}
+	}
+	approval.ClearEvents()
+}
+
+// notifyApprovers puts the approval in its approvers' notifications.
+func (uc *discountApprovalUseCase) notifyApprovers(ctx context.Context, approval *domain.DiscountApproval) {
+	if uc.notificationSvc == nil {
+		return
+	}
+
+	actionURL := discountApprovalURL(approval.ID)
+	actionLabel := "Review"
+	message := fmt.Sprintf("A %.2f%% discount (%s) on %s needs your approval.",
+		approval.Discount.Percent, approval.Discount.Amount.Format(), approval.OpportunityName)
+	if approval.RequestComment != "" {
+		message += " " + approval.RequestComment
+	}
+
+	_ = uc.notificationSvc.SendInApp(ctx, ports.InAppNotificationRequest{
+		TenantID:    approval.TenantID,
+		UserIDs:     approval.ApproverIDs,
+		Title:       "Discount approval requested",
+		Message:     message,
+		Type:        "warning",
+		ActionURL:   &actionURL,
+		ActionLabel: &actionLabel,
+		Data:        discountApprovalNotificationData(approval),
+	})
+}
+
+// notifyRequester tells the requester an approver decided their discount.
+func (uc *discountApprovalUseCase) notifyRequester(ctx context.Context, approval *domain.DiscountApproval) {
+	if uc.notificationSvc == nil {
+		return
+	}
+
+	req := ports.InAppNotificationRequest{
+		TenantID: approval.TenantID,
+		UserIDs:  []uuid.UUID{approval.RequestedBy},
+		Data:     discountApprovalNotificationData(approval),
+	}
+	if approval.Status == domain.DiscountApprovalStatusApproved {
+		req.Title = "Discount approved"
+		req.Message = fmt.Sprintf("The discount on %s was approved.", approval.OpportunityName)
+		req.Type = "success"
+	} else {
+		req.Title = "Discount rejected"
+		req.Message = fmt.Sprintf("The discount on %s was rejected.", approval.OpportunityName)
+		req.Type = "error"
+	}
+	if approval.DecisionComment != "" {
+		req.Message += " " + approval.DecisionComment
+	}
+
+	_ = uc.notificationSvc.SendInApp(ctx, req)
+}
+
+func discountApprovalNotificationData(approval *domain.DiscountApproval) map[string]interface{} {
+	return map[string]interface{}{
+		"approval_id":    approval.ID.String(),
+		"opportunity_id": approval.OpportunityID.String(),
+		"status":         string(approval.Status),
+	}
+}
+
+func discountApprovalURL(approvalID uuid.UUID) string {
+	return "/api/v1/discount-approvals/" + approvalID.String()
+}
+
+// ============================================================================
+// Mapping Functions
+// ============================================================================
+
+func parseApproverIDs(ids []string) ([]uuid.UUID, error) {
+	approverIDs := make([]uuid.UUID, len(ids))
+	for i, id := range ids {
+		approverID, err := uuid.Parse(id)
+		if err != nil {
+			return nil, application.ErrValidation("invalid approver ID: " + id)
+		}
+		approverIDs[i] = approverID
+	}
+	return approverIDs, nil
+}
+
+func uuidStrings(ids []uuid.UUID) []string {
+	strs := make([]string, len(ids))
+	for i, id := range ids {
+		strs[i] = id.String()
+	}
+	return strs
+}
+
+func mapDiscountApprovalRuleToResponse(rule *domain.DiscountApprovalRule) *dto.DiscountApprovalRuleResponse {
+	return &dto.DiscountApprovalRuleResponse{
+		ID:               rule.ID.String(),
+		Name:             rule.Name,
+		ThresholdType:    string(rule.ThresholdType),
+		ThresholdPercent: rule.ThresholdPercent,
+		ThresholdAmount:  rule.ThresholdAmount.Amount,
+		Currency:         rule.ThresholdAmount.Currency,
+		ApproverIDs:      uuidStrings(rule.ApproverIDs),
+		IsActive:         rule.IsActive,
+		CreatedAt:        rule.CreatedAt,
+		UpdatedAt:        rule.UpdatedAt,
+	}
+}
+
+func mapDiscountApprovalToResponse(approval *domain.DiscountApproval) *dto.DiscountApprovalResponse {
+	resp := &dto.DiscountApprovalResponse{
+		ID:              approval.ID.String(),
+		OpportunityID:   approval.OpportunityID.String(),
+		OpportunityName: approval.OpportunityName,
+		Status:          string(approval.Status),
+		DiscountAmount:  approval.Discount.Amount.Amount,
+		DiscountPercent: approval.Discount.Percent,
+		Currency:        approval.Discount.Amount.Currency,
+		RuleIDs:         uuidStrings(approval.RuleIDs),
+		ApproverIDs:     uuidStrings(approval.ApproverIDs),
+		RequestedBy:     approval.RequestedBy.String(),
+		RequestComment:  approval.RequestComment,
+		DecisionComment: approval.DecisionComment,
+		RequestedAt:     approval.RequestedAt,
+		DecidedAt:       approval.DecidedAt,
+	}
+	if approval.DecidedBy != nil {
+		decidedBy := approval.DecidedBy.String()
+		resp.DecidedBy = &decidedBy
+	}
+	return resp
+}
@@ -0,0 +1,228 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"github.com/kilang-desa-murni/crm/internal/sales/application"
+	"github.com/kilang-desa-murni/crm/internal/sales/application/dto"
+	"github.com/kilang-desa-murni/crm/internal/sales/domain"
+)
+
+// ============================================================================
+// Mock Implementations for Discount Approval Tests
+// ============================================================================
+
+// MockDiscountApprovalRepository is a mock implementation of domain.DiscountApprovalRepository.
+type MockDiscountApprovalRepository struct {
+	rules     map[uuid.UUID]*domain.DiscountApprovalRule
+	approvals []*domain.DiscountApproval
+}
+
+func NewMockDiscountApprovalRepository() *MockDiscountApprovalRepository {
+	return &MockDiscountApprovalRepository{rules: make(map[uuid.UUID]*domain.DiscountApprovalRule)}
+}
+
+func (m *MockDiscountApprovalRepository) CreateRule(ctx context.Context, rule *domain.DiscountApprovalRule) error {
+	m.rules[rule.ID] = rule
+	return nil
+}
+
+func (m *MockDiscountApprovalRepository) GetRule(ctx context.Context, tenantID, ruleID uuid.UUID) (*domain.DiscountApprovalRule, error) {
+	rule, ok := m.rules[ruleID]
+	if !ok || rule.TenantID != tenantID {
+		return nil, domain.ErrDiscountApprovalRuleNotFound
+	}
+	return rule, nil
+}
+
+func (m *MockDiscountApprovalRepository) UpdateRule(ctx context.Context, rule *domain.DiscountApprovalRule) error {
+	m.rules[rule.ID] = rule
+	return nil
+}
+
+func (m *MockDiscountApprovalRepository) DeleteRule(ctx context.Context, tenantID, ruleID uuid.UUID) error {
+	delete(m.rules, ruleID)
+	return nil
+}
+
+func (m *MockDiscountApprovalRepository) ListRules(ctx context.Context, tenantID uuid.UUID, activeOnly bool) ([]*domain.DiscountApprovalRule, error) {
+	var rules []*domain.DiscountApprovalRule
+	for _, rule := range m.rules {
+		if rule.TenantID == tenantID && (rule.IsActive || !activeOnly) {
+			rules = append(rules, rule)
+		}
+	}
+	return rules, nil
+}
+
+func (m *MockDiscountApprovalRepository) Create(ctx context.Context, approval *domain.DiscountApproval) error {
+	m.approvals = append(m.approvals, approval)
+	return nil
+}
+
+func (m *MockDiscountApprovalRepository) GetByID(ctx context.Context, tenantID, approvalID uuid.UUID) (*domain.DiscountApproval, error) {
+	for _, approval := range m.approvals {
+		if approval.ID == approvalID && approval.TenantID == tenantID {
+			return approval, nil
+		}
+	}
+	return nil, domain.ErrDiscountApprovalNotFound
+}
+
+func (m *MockDiscountApprovalRepository) Update(ctx context.Context, approval *domain.DiscountApproval) error {
+	return nil
+}
+
+func (m *MockDiscountApprovalRepository) GetLatestByOpportunity(ctx context.Context, tenantID, opportunityID uuid.UUID) (*domain.DiscountApproval, error) {
+	for i := len(m.approvals) - 1; i >= 0; i-- {
+		if m.approvals[i].TenantID == tenantID && m.approvals[i].OpportunityID == opportunityID {
+			return m.approvals[i], nil
+		}
+	}
+	return nil, nil
+}
+
+func (m *MockDiscountApprovalRepository) ListByOpportunity(ctx context.Context, tenantID, opportunityID uuid.UUID) ([]*domain.DiscountApproval, error) {
+	var approvals []*domain.DiscountApproval
+	for _, approval := range m.approvals {
+		if approval.TenantID == tenantID && approval.OpportunityID == opportunityID {
+			approvals = append(approvals, approval)
+		}
+	}
+	return approvals, nil
+}
+
+func (m *MockDiscountApprovalRepository) ListPendingForApprover(ctx context.Context, tenantID, approverID uuid.UUID, opts domain.ListOptions) ([]*domain.DiscountApproval, int64, error) {
+	var approvals []*domain.DiscountApproval
+	for _, approval := range m.approvals {
+		if approval.TenantID == tenantID && approval.Status == domain.DiscountApprovalStatusPending && approval.IsApprover(approverID) {
+			approvals = append(approvals, approval)
+		}
+	}
+	return approvals, int64(len(approvals)), nil
+}
+
+// newDiscountedTestOpportunity returns an open opportunity with a 20% discount.
+func newDiscountedTestOpportunity(tenantID uuid.UUID) *domain.Opportunity {
+	opp := createTestOpportunityWithPipeline(tenantID, createOpportunityTestPipeline(tenantID))
+	opp.Products = []domain.OpportunityProduct{
+		{
+			ID:        uuid.New(),
+			Quantity:  2,
+			UnitPrice: domain.Money{Amount: 5000, Currency: "USD"},
+			Discount:  20,
+		},
+	}
+	return opp
+}
+
+// ============================================================================
+// Discount Approval Use Case Tests
+// ============================================================================
+
+func TestDiscountApprovalUseCase_SubmitAndDecide(t *testing.T) {
+	ctx := context.Background()
+	tenantID := uuid.New()
+	requester := uuid.New()
+	manager := uuid.New()
+	repo := NewMockDiscountApprovalRepository()
+	oppRepo := NewMockOpportunityRepository()
+	eventPublisher := NewMockSalesEventPublisher()
+	uc := NewDiscountApprovalUseCase(repo, oppRepo, eventPublisher, NewMockNotificationService())
+
+	opp := newDiscountedTestOpportunity(tenantID)
+	oppRepo.opportunities[opp.ID] = opp
+
+	_, err := uc.Submit(ctx, tenantID, opp.ID, requester, &dto.SubmitDiscountApprovalRequest{})
+	if appErr := application.GetAppError(err); appErr == nil || appErr.Code != application.ErrCodeDiscountApprovalNotRequired {
+		t.Errorf("Submit() without rules error = %v, want %s", err, application.ErrCodeDiscountApprovalNotRequired)
+	}
+
+	if _, err := uc.CreateRule(ctx, tenantID, &dto.CreateDiscountApprovalRuleRequest{
+		Name:             "Sales managers",
+		ThresholdType:    "percentage",
+		ThresholdPercent: 15,
+		ApproverIDs:      []string{manager.String()},
+	}); err != nil {
+		t.Fatalf("CreateRule() error = %v", err)
+	}
+
+	approval, err := uc.Submit(ctx, tenantID, opp.ID, requester, &dto.SubmitDiscountApprovalRequest{Comment: "Bulk order"})
+	if err != nil {
+		t.Fatalf("Submit() error = %v", err)
+	}
+	if approval.Status != "pending" || approval.DiscountAmount != 2000 || approval.DiscountPercent != 20 {
+		t.Errorf("Submit() = %+v, want a pending approval of 2000 (20%%)", approval)
+	}
+	if len(eventPublisher.events) != 1 || eventPublisher.events[0].Type != "discount_approval.requested" {
+		t.Errorf("Submit() published %d events, want discount_approval.requested", len(eventPublisher.events))
+	}
+
+	pending, err := uc.ListPending(ctx, tenantID, manager, &dto.ListDiscountApprovalsRequest{})
+	if err != nil {
+		t.Fatalf("ListPending() error = %v", err)
+	}
+	if len(pending.Approvals) != 1 || pending.Approvals[0].ID != approval.ID {
+		t.Errorf("ListPending() = %+v, want the submitted approval", pending.Approvals)
+	}
+
+	approvalID := uuid.MustParse(approval.ID)
+	if _, err := uc.Approve(ctx, tenantID, approvalID, requester, &dto.DecideDiscountApprovalRequest{}); !application.IsForbiddenError(err) {
+		t.Errorf("Approve() by requester error = %v, want forbidden", err)
+	}
+	if _, err := uc.Reject(ctx, tenantID, approvalID, manager, &dto.DecideDiscountApprovalRequest{}); !application.IsValidationError(err) {
+		t.Errorf("Reject() without comment error = %v, want validation error", err)
+	}
+
+	approved, err := uc.Approve(ctx, tenantID, approvalID, manager, &dto.DecideDiscountApprovalRequest{Comment: "OK for this order"})
+	if err != nil {
+		t.Fatalf("Approve() error = %v", err)
+	}
+	if approved.Status != "approved" || approved.DecidedBy == nil || *approved.DecidedBy != manager.String() {
+		t.Errorf("Approve() = %+v, want approved by the manager", approved)
+	}
+
+	_, err = uc.Reject(ctx, tenantID, approvalID, manager, &dto.DecideDiscountApprovalRequest{Comment: "Changed my mind"})
+	if appErr := application.GetAppError(err); appErr == nil || appErr.Code != application.ErrCodeDiscountApprovalDecided {
+		t.Errorf("Reject() after approval error = %v, want %s", err, application.ErrCodeDiscountApprovalDecided)
+	}
+
+	list, err := uc.ListByOpportunity(ctx, tenantID, opp.ID)
+	if err != nil {
+		t.Fatalf("ListByOpportunity() error = %v", err)
+	}
+	if list.ApprovalRequired || len(list.Approvals) != 1 {
+		t.Errorf("ListByOpportunity() = %+v, want one approval covering the discount", list)
+	}
+}
+
+func TestOpportunityUseCase_Win_DiscountApprovalRequired(t *testing.T) {
+	oppRepo, pipelineRepo, dealRepo, eventPublisher, customerService, userService, productService, cacheService, searchService, idGenerator := setupOpportunityTestDependencies()
+	approvalRepo := NewMockDiscountApprovalRepository()
+
+	uc := NewOpportunityUseCase(oppRepo, pipelineRepo, dealRepo, eventPublisher, customerService, userService, productService, cacheService, searchService, idGenerator, nil, nil, nil, approvalRepo, nil)
+
+	tenantID := uuid.New()
+	manager := uuid.New()
+	pipeline := createOpportunityTestPipeline(tenantID)
+	pipelineRepo.pipelines[pipeline.ID] = pipeline
+
+	opp := newDiscountedTestOpportunity(tenantID)
+	opp.PipelineID = pipeline.ID
+	opp.StageID = pipeline.Stages[0].ID
+	oppRepo.opportunities[opp.ID] = opp
+
+	rule, _ := domain.NewDiscountApprovalRule(tenantID, "Sales managers", domain.DiscountThresholdPercentage, 15, domain.Money{}, []uuid.UUID{manager})
+	approvalRepo.rules[rule.ID] = rule
+
+	_, err := uc.Win(context.Background(), tenantID, opp.ID, uuid.New(), &dto.WinOpportunityRequest{WonReason: "Great proposal"})
+	if appErr := application.GetAppError(err); appErr == nil || appErr.Code != application.ErrCodeDiscountApprovalRequired {
+		t.Errorf("Win() error = %v, want %s", err, application.ErrCodeDiscountApprovalRequired)
+	}
+	if opp.Status != domain.OpportunityStatusOpen {
+		t.Errorf("Win() status = %s, want open", opp.Status)
+	}
+}
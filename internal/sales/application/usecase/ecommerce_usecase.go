@@ -0,0 +1,1002 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/kilang-desa-murni/crm/internal/sales/application"
+	"github.com/kilang-desa-murni/crm/internal/sales/application/dto"
+	"github.com/kilang-desa-murni/crm/internal/sales/application/ports"
+	"github.com/kilang-desa-murni/crm/internal/sales/domain"
+)
+
+const (
+	// ecommerceClaimTimeout is how long an import stays claimed by the run
+	// importing it. A pending import older than this was abandoned by a
+	// failed run and is imported again.
+	ecommerceClaimTimeout = 10 * time.Minute
+
+	// ecommerceRecentFailures is how many failed imports the sync status lists.
+	ecommerceRecentFailures = 20
+
+	// ecommerceImportListLimit is the most imports listed at once.
+	ecommerceImportListLimit = 500
+
+	// ecommerceWebhookPath is where stores push webhooks, followed by the
+	// store ID.
+	ecommerceWebhookPath = "/api/v1/ecommerce/webhooks/"
+)
+
+// ============================================================================
+// E-Commerce Use Case Interface
+// ============================================================================
+
+// EcommerceUseCase defines the interface for importing online store orders
+// as won deals and abandoned carts as leads.
+type EcommerceUseCase interface {
+	// Stores
+	ListStores(ctx context.Context, tenantID uuid.UUID) ([]*dto.EcommerceStoreResponse, error)
+	CreateStore(ctx context.Context, tenantID, userID uuid.UUID, req *dto.CreateEcommerceStoreRequest) (*dto.EcommerceStoreResponse, error)
+	GetStore(ctx context.Context, tenantID, storeID uuid.UUID) (*dto.EcommerceStoreResponse, error)
+	UpdateStore(ctx context.Context, tenantID, storeID uuid.UUID, req *dto.UpdateEcommerceStoreRequest) (*dto.EcommerceStoreResponse, error)
+	DeleteStore(ctx context.Context, tenantID, storeID uuid.UUID) error
+
+	// Sync status and imports
+	GetSyncStatus(ctx context.Context, tenantID, storeID uuid.UUID) (*dto.EcommerceSyncStatusResponse, error)
+	ListImports(ctx context.Context, tenantID, storeID uuid.UUID, req *dto.ListEcommerceImportsRequest) ([]*dto.EcommerceImportResponse, error)
+	SyncStore(ctx context.Context, tenantID, storeID uuid.UUID) (*dto.EcommerceImportResultResponse, error)
+
+	// ReceiveWebhook imports the orders and carts a store pushes.
+	ReceiveWebhook(ctx context.Context, storeID uuid.UUID, headers map[string]string, body []byte) (*dto.EcommerceImportResultResponse, error)
+
+	// ProcessDue polls the enabled stores for orders and carts, returning
+	// how many were imported.
+	ProcessDue(ctx context.Context, now time.Time) (int, error)
+}
+
+// ============================================================================
+// E-Commerce Use Case Implementation
+// ============================================================================
+
+// ecommerceUseCase implements EcommerceUseCase.
+type ecommerceUseCase struct {
+	storeRepo       domain.EcommerceStoreRepository
+	importRepo      domain.EcommerceImportRepository
+	pipelineRepo    domain.PipelineRepository
+	opportunityRepo domain.OpportunityRepository
+	dealRepo        domain.DealRepository
+	leadRepo        domain.LeadRepository
+	customerService ports.CustomerService
+	customerMatcher ports.CustomerMatcher
+	idGenerator     ports.IDGenerator
+	eventPublisher  ports.EventPublisher
+	clients         map[domain.EcommercePlatform]ports.EcommerceClient
+}
+
+// NewEcommerceUseCase creates a new e-commerce use case with a client for
+// each platform whose stores can be connected.
+func NewEcommerceUseCase(
+	storeRepo domain.EcommerceStoreRepository,
+	importRepo domain.EcommerceImportRepository,
+	pipelineRepo domain.PipelineRepository,
+	opportunityRepo domain.OpportunityRepository,
+	dealRepo domain.DealRepository,
+	leadRepo domain.LeadRepository,
+	customerService ports.CustomerService,
+	customerMatcher ports.CustomerMatcher,
+	idGenerator ports.IDGenerator,
+	eventPublisher ports.EventPublisher,
+	clients []ports.EcommerceClient,
+) EcommerceUseCase {
+	byPlatform := make(map[domain.EcommercePlatform]ports.EcommerceClient, len(clients))
+	for _, client := range clients {
+		byPlatform[domain.EcommercePlatform(client.Platform())] = client
+	}
+	return &ecommerceUseCase{
+		storeRepo:       storeRepo,
+		importRepo:      importRepo,
+		pipelineRepo:    pipelineRepo,
+		opportunityRepo: opportunityRepo,
+		dealRepo:        dealRepo,
+		leadRepo:        leadRepo,
+		customerService: customerService,
+		customerMatcher: customerMatcher,
+		idGenerator:     idGenerator,
+		eventPublisher:  eventPublisher,
+		clients:         byPlatform,
+	}
+}
+
+// ============================================================================
+// Stores
+// ============================================================================
+
+// ListStores lists the tenant's store connectors.
+func (uc *ecommerceUseCase) ListStores(ctx context.Context, tenantID uuid.UUID) ([]*dto.EcommerceStoreResponse, error) {
+	stores, err := uc.storeRepo.List(ctx, tenantID)
+	if err != nil {
+		return nil, application.WrapError(application.ErrCodeInternal, "failed to list e-commerce stores", err)
+	}
+
+	responses := make([]*dto.EcommerceStoreResponse, len(stores))
+	for i, store := range stores {
+		responses[i] = mapEcommerceStoreToResponse(store)
+	}
+	return responses, nil
+}
+
+// CreateStore connects an online store. Its orders are imported from the
+// next poll, and from its webhooks once they are pointed at the store's
+// webhook path.
+func (uc *ecommerceUseCase) CreateStore(ctx context.Context, tenantID, userID uuid.UUID, req *dto.CreateEcommerceStoreRequest) (*dto.EcommerceStoreResponse, error) {
+	platform := domain.EcommercePlatform(req.Platform)
+	if _, ok := uc.clients[platform]; !ok {
+		return nil, application.ErrValidation(fmt.Sprintf("unsupported e-commerce platform: %s", req.Platform))
+	}
+
+	ownerID := userID
+	if req.OwnerID != nil {
+		id, err := uuid.Parse(*req.OwnerID)
+		if err != nil {
+			return nil, application.ErrValidation("invalid owner_id")
+		}
+		ownerID = id
+	}
+
+	store, err := domain.NewEcommerceStore(tenantID, platform, req.Name, req.StoreURL, ownerID, userID)
+	if err != nil {
+		return nil, application.ErrValidation(err.Error())
+	}
+	store.SetCredentials(req.APIKey, req.APISecret, req.WebhookSecret)
+	store.ImportAbandonedCarts = req.ImportAbandonedCarts
+	if req.PipelineID != nil {
+		if err := uc.setPipeline(ctx, store, *req.PipelineID); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := uc.storeRepo.Create(ctx, store); err != nil {
+		return nil, application.WrapError(application.ErrCodeInternal, "failed to create e-commerce store", err)
+	}
+	return mapEcommerceStoreToResponse(store), nil
+}
+
+// GetStore retrieves a store connector.
+func (uc *ecommerceUseCase) GetStore(ctx context.Context, tenantID, storeID uuid.UUID) (*dto.EcommerceStoreResponse, error) {
+	store, err := uc.getStore(ctx, tenantID, storeID)
+	if err != nil {
+		return nil, err
+	}
+	return mapEcommerceStoreToResponse(store), nil
+}
+
+// UpdateStore changes a store connector. Credentials left empty are kept.
+func (uc *ecommerceUseCase) UpdateStore(ctx context.Context, tenantID, storeID uuid.UUID, req *dto.UpdateEcommerceStoreRequest) (*dto.EcommerceStoreResponse, error) {
+	store, err := uc.getStore(ctx, tenantID, storeID)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Name != nil {
+		if err := store.Rename(*req.Name); err != nil {
+			return nil, application.ErrValidation(err.Error())
+		}
+	}
+	if req.StoreURL != nil {
+		if err := store.SetStoreURL(*req.StoreURL); err != nil {
+			return nil, application.ErrValidation(err.Error())
+		}
+	}
+	store.SetCredentials(req.APIKey, req.APISecret, req.WebhookSecret)
+	if req.Enabled != nil {
+		store.Enabled = *req.Enabled
+	}
+	if req.ImportAbandonedCarts != nil {
+		store.ImportAbandonedCarts = *req.ImportAbandonedCarts
+	}
+	if req.PipelineID != nil {
+		if err := uc.setPipeline(ctx, store, *req.PipelineID); err != nil {
+			return nil, err
+		}
+	}
+	if req.OwnerID != nil {
+		ownerID, err := uuid.Parse(*req.OwnerID)
+		if err != nil {
+			return nil, application.ErrValidation("invalid owner_id")
+		}
+		store.OwnerID = ownerID
+	}
+
+	if err := uc.saveStore(ctx, store); err != nil {
+		return nil, err
+	}
+	return mapEcommerceStoreToResponse(store), nil
+}
+
+// DeleteStore disconnects a store. Deals and leads it imported are kept.
+func (uc *ecommerceUseCase) DeleteStore(ctx context.Context, tenantID, storeID uuid.UUID) error {
+	if err := uc.storeRepo.Delete(ctx, tenantID, storeID); err != nil {
+		if errors.Is(err, domain.ErrEcommerceStoreNotFound) {
+			return application.ErrEcommerceStoreNotFound(storeID)
+		}
+		return application.WrapError(application.ErrCodeInternal, "failed to delete e-commerce store", err)
+	}
+	return nil
+}
+
+// setPipeline sets the pipeline a store's deals are won in. An empty ID
+// uses the tenant's default pipeline.
+func (uc *ecommerceUseCase) setPipeline(ctx context.Context, store *domain.EcommerceStore, pipelineID string) error {
+	if pipelineID == "" {
+		store.PipelineID = nil
+		return nil
+	}
+	id, err := uuid.Parse(pipelineID)
+	if err != nil {
+		return application.ErrValidation("invalid pipeline_id")
+	}
+	if _, err := uc.pipelineRepo.GetByID(ctx, store.TenantID, id); err != nil {
+		return application.ErrPipelineNotFound(id)
+	}
+	store.PipelineID = &id
+	return nil
+}
+
+// ============================================================================
+// Sync Status and Imports
+// ============================================================================
+
+// GetSyncStatus reports how a store's imports are going: when it was last
+// polled and pushed a webhook, what it imported, and its recent failures.
+func (uc *ecommerceUseCase) GetSyncStatus(ctx context.Context, tenantID, storeID uuid.UUID) (*dto.EcommerceSyncStatusResponse, error) {
+	store, err := uc.getStore(ctx, tenantID, storeID)
+	if err != nil {
+		return nil, err
+	}
+
+	summary, err := uc.importRepo.Summarize(ctx, tenantID, storeID)
+	if err != nil {
+		return nil, application.WrapError(application.ErrCodeInternal, "failed to summarize e-commerce imports", err)
+	}
+	failed, err := uc.importRepo.List(ctx, tenantID, storeID, []domain.EcommerceImportStatus{domain.EcommerceImportStatusFailed}, ecommerceRecentFailures)
+	if err != nil {
+		return nil, application.WrapError(application.ErrCodeInternal, "failed to list failed e-commerce imports", err)
+	}
+
+	response := &dto.EcommerceSyncStatusResponse{
+		Store:          *mapEcommerceStoreToResponse(store),
+		OrdersImported: summary.OrdersImported,
+		LeadsCreated:   summary.LeadsCreated,
+		Skipped:        summary.Skipped,
+		Failed:         summary.Failed,
+		LastImportedAt: summary.LastImportedAt,
+		RecentFailures: make([]dto.EcommerceImportResponse, len(failed)),
+	}
+	for i, record := range failed {
+		response.RecentFailures[i] = *mapEcommerceImportToResponse(record)
+	}
+	return response, nil
+}
+
+// ListImports lists a store's imports, most recently updated first.
+func (uc *ecommerceUseCase) ListImports(ctx context.Context, tenantID, storeID uuid.UUID, req *dto.ListEcommerceImportsRequest) ([]*dto.EcommerceImportResponse, error) {
+	if _, err := uc.getStore(ctx, tenantID, storeID); err != nil {
+		return nil, err
+	}
+
+	statuses := make([]domain.EcommerceImportStatus, 0, len(req.Statuses))
+	for _, status := range req.Statuses {
+		s := domain.EcommerceImportStatus(status)
+		if !s.IsValid() {
+			return nil, application.ErrValidation(fmt.Sprintf("invalid import status: %s", status))
+		}
+		statuses = append(statuses, s)
+	}
+	limit := req.Limit
+	if limit <= 0 || limit > ecommerceImportListLimit {
+		limit = 100
+	}
+
+	records, err := uc.importRepo.List(ctx, tenantID, storeID, statuses, limit)
+	if err != nil {
+		return nil, application.WrapError(application.ErrCodeInternal, "failed to list e-commerce imports", err)
+	}
+
+	responses := make([]*dto.EcommerceImportResponse, len(records))
+	for i, record := range records {
+		responses[i] = mapEcommerceImportToResponse(record)
+	}
+	return responses, nil
+}
+
+// SyncStore polls a store straight away.
+func (uc *ecommerceUseCase) SyncStore(ctx context.Context, tenantID, storeID uuid.UUID) (*dto.EcommerceImportResultResponse, error) {
+	store, err := uc.getStore(ctx, tenantID, storeID)
+	if err != nil {
+		return nil, err
+	}
+	client, ok := uc.clients[store.Platform]
+	if !ok {
+		return nil, application.ErrServiceUnavailable(string(store.Platform))
+	}
+	if !store.CanPoll() {
+		return nil, application.ErrValidation("the store must be enabled and have API credentials to sync")
+	}
+
+	now := time.Now().UTC()
+	result, latest, syncErr := uc.poll(ctx, client, store, now)
+	store.RecordSync(now, latest, syncErr)
+	if err := uc.saveStore(ctx, store); err != nil {
+		return nil, err
+	}
+	if syncErr != nil {
+		return nil, application.WrapError(application.ErrCodeServiceUnavailable, "failed to sync e-commerce store", syncErr)
+	}
+	return result, nil
+}
+
+// ============================================================================
+// Webhook
+// ============================================================================
+
+// ReceiveWebhook imports the orders and carts a store pushes. Records
+// already imported are skipped, so stores may push them again.
+func (uc *ecommerceUseCase) ReceiveWebhook(ctx context.Context, storeID uuid.UUID, headers map[string]string, body []byte) (*dto.EcommerceImportResultResponse, error) {
+	store, err := uc.storeRepo.GetForWebhook(ctx, storeID)
+	if err != nil {
+		if errors.Is(err, domain.ErrEcommerceStoreNotFound) {
+			return nil, application.ErrUnauthorized("invalid e-commerce webhook signature")
+		}
+		return nil, application.WrapError(application.ErrCodeInternal, "failed to get e-commerce store", err)
+	}
+	if !store.Enabled || store.WebhookSecret == "" {
+		return nil, application.ErrUnauthorized("invalid e-commerce webhook signature")
+	}
+	client, ok := uc.clients[store.Platform]
+	if !ok {
+		return nil, application.ErrServiceUnavailable(string(store.Platform))
+	}
+
+	webhook, err := client.ParseWebhook(ctx, ecommerceStoreAccess(store), headers, body)
+	if err != nil {
+		if errors.Is(err, ports.ErrInvalidEcommerceWebhook) {
+			return nil, application.ErrUnauthorized("invalid e-commerce webhook signature")
+		}
+		return nil, application.ErrValidation(err.Error())
+	}
+
+	result := &dto.EcommerceImportResultResponse{}
+	for _, order := range webhook.Orders {
+		status, err := uc.importOrder(ctx, store, order)
+		if err != nil {
+			return nil, err
+		}
+		countEcommerceImport(result, status)
+	}
+	if store.ImportAbandonedCarts {
+		for _, cart := range webhook.Carts {
+			status, err := uc.importCart(ctx, store, cart)
+			if err != nil {
+				return nil, err
+			}
+			countEcommerceImport(result, status)
+		}
+	}
+
+	// The delivery is not failed for a webhook time lost to a concurrent poll
+	store.RecordWebhook(time.Now().UTC())
+	_ = uc.saveStore(ctx, store)
+	return result, nil
+}
+
+// ============================================================================
+// Background Processing
+// ============================================================================
+
+// ProcessDue polls every enabled store that has API credentials. A store
+// whose poll fails keeps its sync position and is polled again next run.
+func (uc *ecommerceUseCase) ProcessDue(ctx context.Context, now time.Time) (int, error) {
+	stores, err := uc.storeRepo.ListEnabled(ctx)
+	if err != nil {
+		return 0, application.WrapError(application.ErrCodeInternal, "failed to list e-commerce stores", err)
+	}
+
+	imported := 0
+	for _, store := range stores {
+		client, ok := uc.clients[store.Platform]
+		if !ok || !store.CanPoll() {
+			continue
+		}
+
+		result, latest, syncErr := uc.poll(ctx, client, store, now)
+		store.RecordSync(now, latest, syncErr)
+		if err := uc.saveStore(ctx, store); err != nil {
+			continue
+		}
+		imported += result.Imported
+	}
+	return imported, nil
+}
+
+// poll imports the orders, and abandoned carts if the store imports them,
+// updated since the store was last synced. It returns the latest order
+// update imported, which is where the next poll continues from.
+func (uc *ecommerceUseCase) poll(ctx context.Context, client ports.EcommerceClient, store *domain.EcommerceStore, now time.Time) (*dto.EcommerceImportResultResponse, *time.Time, error) {
+	result := &dto.EcommerceImportResultResponse{}
+	access := ecommerceStoreAccess(store)
+	since := store.SyncFrom(now)
+
+	orders, err := client.FetchOrders(ctx, access, since)
+	if err != nil {
+		return result, nil, err
+	}
+
+	var latest *time.Time
+	for _, order := range orders {
+		status, err := uc.importOrder(ctx, store, order)
+		if err != nil {
+			return result, latest, err
+		}
+		countEcommerceImport(result, status)
+		if latest == nil || order.UpdatedAt.After(*latest) {
+			updatedAt := order.UpdatedAt
+			latest = &updatedAt
+		}
+	}
+
+	if !store.ImportAbandonedCarts {
+		return result, latest, nil
+	}
+	carts, err := client.FetchAbandonedCarts(ctx, access, since)
+	if err != nil {
+		return result, latest, err
+	}
+	for _, cart := range carts {
+		status, err := uc.importCart(ctx, store, cart)
+		if err != nil {
+			return result, latest, err
+		}
+		countEcommerceImport(result, status)
+	}
+	return result, latest, nil
+}
+
+// ============================================================================
+// Importing
+// ============================================================================
+
+// importOrder imports an order as a won deal for the shopper's customer.
+// An order that cannot be imported is marked failed and imported again the
+// next time the store sends it; the returned error is for failures to
+// record the import itself.
+func (uc *ecommerceUseCase) importOrder(ctx context.Context, store *domain.EcommerceStore, order ports.EcommerceOrder) (domain.EcommerceImportStatus, error) {
+	amount, _ := domain.NewMoney(order.Total, order.Currency)
+	record, err := uc.claim(ctx, store, domain.EcommerceImportOrder, order.ExternalID, order.Number, order.Customer.Email, amount)
+	if err != nil || record == nil {
+		return domain.EcommerceImportStatusSkipped, err
+	}
+
+	if order.Cancelled {
+		record.MarkSkipped("order is cancelled", nil)
+	} else if customerID, dealID, err := uc.createOrderDeal(ctx, store, order); err != nil {
+		record.MarkFailed(err.Error())
+	} else {
+		record.MarkImported(customerID, &dealID, nil)
+	}
+
+	if err := uc.saveImport(ctx, record); err != nil {
+		return "", err
+	}
+	return record.Status, nil
+}
+
+// importCart imports an abandoned cart as a lead, unless the shopper is
+// already one.
+func (uc *ecommerceUseCase) importCart(ctx context.Context, store *domain.EcommerceStore, cart ports.EcommerceCart) (domain.EcommerceImportStatus, error) {
+	amount, _ := domain.NewMoney(cart.Total, cart.Currency)
+	record, err := uc.claim(ctx, store, domain.EcommerceImportAbandonedCart, cart.ExternalID, cart.ExternalID, cart.Customer.Email, amount)
+	if err != nil || record == nil {
+		return domain.EcommerceImportStatusSkipped, err
+	}
+
+	switch {
+	case cart.Completed:
+		record.MarkSkipped("checkout became an order", nil)
+	case record.Email == "":
+		record.MarkSkipped("checkout has no email address", nil)
+	default:
+		lead, err := uc.matchLead(ctx, store.TenantID, cart.Customer)
+		if err != nil {
+			record.MarkFailed(err.Error())
+			break
+		}
+		if lead != nil {
+			record.MarkSkipped("shopper is already a lead", &lead.ID)
+			break
+		}
+		if lead, err = uc.createCartLead(ctx, store, cart, amount); err != nil {
+			record.MarkFailed(err.Error())
+			break
+		}
+		record.MarkImported(nil, nil, &lead.ID)
+	}
+
+	if err := uc.saveImport(ctx, record); err != nil {
+		return "", err
+	}
+	return record.Status, nil
+}
+
+// claim starts the import of a store record by creating its pending import,
+// or by reopening a failed one. It returns nil when the record was already
+// imported or skipped, or is being imported by another run.
+func (uc *ecommerceUseCase) claim(ctx context.Context, store *domain.EcommerceStore, kind domain.EcommerceImportKind, externalID, reference, email string, amount domain.Money) (*domain.EcommerceImport, error) {
+	if externalID == "" {
+		return nil, application.ErrValidation(fmt.Sprintf("%s external_id is required", kind))
+	}
+
+	record, err := uc.importRepo.GetByExternalID(ctx, store.TenantID, store.ID, kind, externalID)
+	if err != nil {
+		return nil, application.WrapError(application.ErrCodeInternal, "failed to get e-commerce import", err)
+	}
+
+	if record != nil {
+		if record.IsResolved() {
+			return nil, nil
+		}
+		if record.Status == domain.EcommerceImportStatusPending && time.Since(record.UpdatedAt) < ecommerceClaimTimeout {
+			return nil, nil
+		}
+		if err := record.Retry(); err != nil {
+			return nil, nil
+		}
+		record.Version++
+		if err := uc.importRepo.Update(ctx, record); err != nil {
+			// Claimed concurrently by another run
+			if errors.Is(err, domain.ErrEcommerceImportNotFound) {
+				return nil, nil
+			}
+			return nil, application.WrapError(application.ErrCodeInternal, "failed to update e-commerce import", err)
+		}
+		return record, nil
+	}
+
+	if record, err = domain.NewEcommerceImport(store, kind, externalID, reference, email, amount); err != nil {
+		return nil, application.ErrValidation(err.Error())
+	}
+	if err := uc.importRepo.Create(ctx, record); err != nil {
+		// Claimed concurrently by another run
+		if errors.Is(err, domain.ErrEcommerceImportAlreadyExists) {
+			return nil, nil
+		}
+		return nil, application.WrapError(application.ErrCodeInternal, "failed to create e-commerce import", err)
+	}
+	return record, nil
+}
+
+// createOrderDeal wins an opportunity for the order in the store's pipeline
+// and creates its deal, returning the customer and the deal.
+func (uc *ecommerceUseCase) createOrderDeal(ctx context.Context, store *domain.EcommerceStore, order ports.EcommerceOrder) (*uuid.UUID, uuid.UUID, error) {
+	zero, err := domain.Zero(order.Currency)
+	if err != nil {
+		return nil, uuid.Nil, fmt.Errorf("order currency %q is not supported", order.Currency)
+	}
+	if len(order.Lines) == 0 {
+		return nil, uuid.Nil, errors.New("order has no lines")
+	}
+
+	pipeline, err := uc.storePipeline(ctx, store)
+	if err != nil {
+		return nil, uuid.Nil, err
+	}
+	var wonStage *domain.Stage
+	for _, s := range pipeline.Stages {
+		if s.Type == domain.StageTypeWon && s.IsActive {
+			wonStage = s
+			break
+		}
+	}
+	if wonStage == nil {
+		return nil, uuid.Nil, fmt.Errorf("pipeline %s has no won stage", pipeline.Name)
+	}
+
+	customerID, customerName, err := uc.resolveCustomer(ctx, store, order.Customer)
+	if err != nil {
+		return nil, uuid.Nil, err
+	}
+
+	name := fmt.Sprintf("%s order %s", store.Name, order.Number)
+	opportunity, err := domain.NewOpportunity(store.TenantID, name, pipeline, customerID, customerName, zero, store.OwnerID, "", uuid.Nil)
+	if err != nil {
+		return nil, uuid.Nil, err
+	}
+	for _, line := range order.Lines {
+		if line.Quantity <= 0 {
+			continue
+		}
+		opportunity.AddProduct(domain.OpportunityProduct{
+			ProductName: line.Name,
+			SKU:         line.SKU,
+			Quantity:    line.Quantity,
+			UnitPrice:   domain.Money{Amount: line.UnitPrice, Currency: zero.Currency},
+		})
+	}
+	if order.Shipping > 0 {
+		opportunity.AddProduct(domain.OpportunityProduct{
+			ProductName: "Shipping",
+			Quantity:    1,
+			UnitPrice:   domain.Money{Amount: order.Shipping, Currency: zero.Currency},
+		})
+	}
+	opportunity.AddTag("ecommerce")
+	opportunity.AddTag(string(store.Platform))
+
+	notes := fmt.Sprintf("Order %s from %s, awaiting payment", order.Number, store.Name)
+	if order.Paid {
+		notes = fmt.Sprintf("Order %s from %s, paid online", order.Number, store.Name)
+	}
+	if err := opportunity.Win(wonStage, "Online store order", notes, uuid.Nil); err != nil {
+		return nil, uuid.Nil, err
+	}
+	if !order.CreatedAt.IsZero() {
+		orderedAt := order.CreatedAt.UTC()
+		opportunity.ActualCloseDate = &orderedAt
+	}
+
+	if err := uc.opportunityRepo.Create(ctx, opportunity); err != nil {
+		return nil, uuid.Nil, fmt.Errorf("failed to create opportunity: %w", err)
+	}
+	uc.publishEvents(ctx, opportunity.GetEvents())
+	opportunity.ClearEvents()
+
+	deal, err := domain.NewDealFromOpportunity(opportunity, uuid.Nil)
+	if err != nil {
+		return nil, uuid.Nil, err
+	}
+	deal.Code = "DL-" + deal.ID.String()[:8]
+	if uc.idGenerator != nil {
+		if code, err := uc.idGenerator.GenerateDealNumber(ctx, store.TenantID); err == nil {
+			deal.Code = code
+		}
+	}
+	if err := uc.dealRepo.Create(ctx, deal); err != nil {
+		return nil, uuid.Nil, fmt.Errorf("failed to create deal: %w", err)
+	}
+	uc.publishEvents(ctx, deal.GetEvents())
+	deal.ClearEvents()
+
+	if customerID == uuid.Nil {
+		return nil, deal.ID, nil
+	}
+	return &customerID, deal.ID, nil
+}
+
+// storePipeline returns the pipeline a store's deals are won in.
+func (uc *ecommerceUseCase) storePipeline(ctx context.Context, store *domain.EcommerceStore) (*domain.Pipeline, error) {
+	if store.PipelineID != nil {
+		pipeline, err := uc.pipelineRepo.GetByID(ctx, store.TenantID, *store.PipelineID)
+		if err != nil {
+			return nil, fmt.Errorf("pipeline %s not found", *store.PipelineID)
+		}
+		return pipeline, nil
+	}
+	pipeline, err := uc.pipelineRepo.GetDefaultPipeline(ctx, store.TenantID)
+	if err != nil || pipeline == nil {
+		return nil, errors.New("the tenant has no default pipeline")
+	}
+	return pipeline, nil
+}
+
+// resolveCustomer finds the shopper's customer by email address or phone
+// number, creating it with the shopper as its primary contact when there
+// is none. Without a customer service the deal only carries the shopper's
+// name.
+func (uc *ecommerceUseCase) resolveCustomer(ctx context.Context, store *domain.EcommerceStore, shopper ports.EcommerceCustomer) (uuid.UUID, string, error) {
+	name := ecommerceShopperName(shopper)
+
+	if uc.customerMatcher != nil && (shopper.Email != "" || shopper.Phone != "") {
+		var phones []string
+		if shopper.Phone != "" {
+			phones = []string{shopper.Phone}
+		}
+		matches, err := uc.customerMatcher.FindMatchingCustomers(ctx, store.TenantID, shopper.Email, phones, "")
+		if err != nil {
+			return uuid.Nil, "", fmt.Errorf("failed to match customer: %w", err)
+		}
+		if len(matches) > 0 {
+			return matches[0].ID, matches[0].Name, nil
+		}
+	}
+
+	if uc.customerService == nil {
+		return uuid.Nil, name, nil
+	}
+
+	ownerID := store.OwnerID
+	req := ports.CreateCustomerRequest{
+		Name:    name,
+		Type:    "individual",
+		OwnerID: &ownerID,
+		Source:  "ecommerce",
+	}
+	if shopper.Company != "" {
+		req.Type = "business"
+	}
+	if shopper.Email != "" {
+		email := shopper.Email
+		req.Email = &email
+	}
+	if shopper.Phone != "" {
+		phone := shopper.Phone
+		req.Phone = &phone
+	}
+	if shopper.Street1 != "" || shopper.City != "" {
+		req.Address = &ports.AddressInfo{
+			Street1: shopper.Street1,
+			City:    shopper.City,
+			Country: shopper.Country,
+		}
+		if shopper.State != "" {
+			state := shopper.State
+			req.Address.State = &state
+		}
+		if shopper.Postcode != "" {
+			postcode := shopper.Postcode
+			req.Address.PostalCode = &postcode
+		}
+	}
+
+	customer, err := uc.customerService.CreateCustomer(ctx, store.TenantID, req)
+	if err != nil {
+		return uuid.Nil, "", fmt.Errorf("failed to create customer: %w", err)
+	}
+
+	// The deal is still imported for a customer whose contact was not created
+	if shopper.FirstName != "" || shopper.LastName != "" {
+		contact := ports.CreateContactRequest{
+			FirstName: shopper.FirstName,
+			LastName:  shopper.LastName,
+			Email:     shopper.Email,
+			IsPrimary: true,
+		}
+		if shopper.Phone != "" {
+			phone := shopper.Phone
+			contact.Phone = &phone
+		}
+		_, _ = uc.customerService.CreateContact(ctx, store.TenantID, customer.ID, contact)
+	}
+	return customer.ID, customer.Name, nil
+}
+
+// matchLead finds the shopper's existing lead by email address or phone
+// number. Deleted leads do not match.
+func (uc *ecommerceUseCase) matchLead(ctx context.Context, tenantID uuid.UUID, shopper ports.EcommerceCustomer) (*domain.Lead, error) {
+	lead, err := uc.leadRepo.GetByEmail(ctx, tenantID, strings.ToLower(strings.TrimSpace(shopper.Email)))
+	if err != nil && !errors.Is(err, domain.ErrLeadNotFound) {
+		return nil, fmt.Errorf("failed to match lead: %w", err)
+	}
+	if (lead == nil || lead.IsDeleted()) && shopper.Phone != "" {
+		lead, err = uc.leadRepo.GetByPhone(ctx, tenantID, shopper.Phone)
+		if err != nil && !errors.Is(err, domain.ErrLeadNotFound) {
+			return nil, fmt.Errorf("failed to match lead: %w", err)
+		}
+	}
+	if lead == nil || lead.IsDeleted() {
+		return nil, nil
+	}
+	return lead, nil
+}
+
+// createCartLead creates a website lead for the shopper of an abandoned
+// cart, describing what was left in it.
+func (uc *ecommerceUseCase) createCartLead(ctx context.Context, store *domain.EcommerceStore, cart ports.EcommerceCart, amount domain.Money) (*domain.Lead, error) {
+	shopper := cart.Customer
+	contact := domain.LeadContact{
+		FirstName: shopper.FirstName,
+		LastName:  shopper.LastName,
+		Email:     strings.ToLower(strings.TrimSpace(shopper.Email)),
+		Phone:     shopper.Phone,
+	}
+	if contact.FirstName == "" {
+		contact.FirstName, _, _ = strings.Cut(contact.Email, "@")
+	}
+	company := domain.LeadCompany{
+		Name:       shopper.Company,
+		Address:    shopper.Street1,
+		City:       shopper.City,
+		State:      shopper.State,
+		Country:    shopper.Country,
+		PostalCode: shopper.Postcode,
+	}
+	if company.Name == "" {
+		company.Name = contact.FullName()
+	}
+
+	lead, err := domain.NewLead(store.TenantID, contact, company, domain.LeadSourceWebsite, uuid.Nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var description strings.Builder
+	fmt.Fprintf(&description, "Abandoned checkout at %s", store.Name)
+	for _, line := range cart.Lines {
+		fmt.Fprintf(&description, "\n- %d x %s", line.Quantity, line.Name)
+	}
+	if cart.RecoveryURL != "" {
+		fmt.Fprintf(&description, "\n\nRecover the checkout: %s", cart.RecoveryURL)
+	}
+	lead.Description = description.String()
+	lead.AddTag("abandoned-cart")
+	lead.AddTag(string(store.Platform))
+	if amount.Currency != "" {
+		lead.SetEstimatedValue(amount)
+	}
+	lead.AssignOwner(store.OwnerID, "")
+
+	if err := uc.leadRepo.Create(ctx, lead); err != nil {
+		return nil, fmt.Errorf("failed to create lead: %w", err)
+	}
+	uc.publishEvents(ctx, lead.GetEvents())
+	lead.ClearEvents()
+	return lead, nil
+}
+
+// ============================================================================
+// Helpers
+// ============================================================================
+
+func (uc *ecommerceUseCase) getStore(ctx context.Context, tenantID, storeID uuid.UUID) (*domain.EcommerceStore, error) {
+	store, err := uc.storeRepo.GetByID(ctx, tenantID, storeID)
+	if err != nil {
+		if errors.Is(err, domain.ErrEcommerceStoreNotFound) {
+			return nil, application.ErrEcommerceStoreNotFound(storeID)
+		}
+		return nil, application.WrapError(application.ErrCodeInternal, "failed to get e-commerce store", err)
+	}
+	return store, nil
+}
+
+func (uc *ecommerceUseCase) saveStore(ctx context.Context, store *domain.EcommerceStore) error {
+	store.Version++
+	if err := uc.storeRepo.Update(ctx, store); err != nil {
+		if errors.Is(err, domain.ErrEcommerceStoreNotFound) {
+			return application.ErrConcurrentModification("e-commerce store", store.ID)
+		}
+		return application.WrapError(application.ErrCodeInternal, "failed to update e-commerce store", err)
+	}
+	return nil
+}
+
+func (uc *ecommerceUseCase) saveImport(ctx context.Context, record *domain.EcommerceImport) error {
+	record.Version++
+	if err := uc.importRepo.Update(ctx, record); err != nil {
+		if errors.Is(err, domain.ErrEcommerceImportNotFound) {
+			return application.ErrConcurrentModification("e-commerce import", record.ID)
+		}
+		return application.WrapError(application.ErrCodeInternal, "failed to update e-commerce import", err)
+	}
+	return nil
+}
+
+func (uc *ecommerceUseCase) publishEvents(ctx context.Context, events []domain.DomainEvent) {
+	if uc.eventPublisher == nil {
+		return
+	}
+
+	for _, event := range events {
+		var payload map[string]interface{}
+		if data, err := json.Marshal(event); err == nil {
+			json.Unmarshal(data, &payload)
+		}
+
+		uc.eventPublisher.Publish(ctx, ports.Event{
+			ID:            event.EventID().String(),
+			Type:          event.EventType(),
+			AggregateID:   event.AggregateID().String(),
+			AggregateType: event.AggregateType(),
+			TenantID:      event.TenantID().String(),
+			Payload:       payload,
+			OccurredAt:    event.OccurredAt(),
+			Version:       event.Version(),
+		})
+	}
+}
+
+func ecommerceStoreAccess(store *domain.EcommerceStore) ports.EcommerceStoreAccess {
+	return ports.EcommerceStoreAccess{
+		StoreURL:      store.StoreURL,
+		APIKey:        store.APIKey,
+		APISecret:     store.APISecret,
+		WebhookSecret: store.WebhookSecret,
+	}
+}
+
+// ecommerceShopperName is the name a shopper's customer is created with.
+func ecommerceShopperName(shopper ports.EcommerceCustomer) string {
+	if shopper.Company != "" {
+		return shopper.Company
+	}
+	if name := strings.TrimSpace(shopper.FirstName + " " + shopper.LastName); name != "" {
+		return name
+	}
+	return shopper.Email
+}
+
+func countEcommerceImport(result *dto.EcommerceImportResultResponse, status domain.EcommerceImportStatus) {
+	result.Received++
+	switch status {
+	case domain.EcommerceImportStatusImported:
+		result.Imported++
+	case domain.EcommerceImportStatusSkipped:
+		result.Skipped++
+	case domain.EcommerceImportStatusFailed:
+		result.Failed++
+	}
+}
+
+// ============================================================================
+// Mapping Functions
+// ============================================================================
+
+func mapEcommerceStoreToResponse(store *domain.EcommerceStore) *dto.EcommerceStoreResponse {
+	response := &dto.EcommerceStoreResponse{
+		ID:                   store.ID.String(),
+		Platform:             string(store.Platform),
+		Name:                 store.Name,
+		StoreURL:             store.StoreURL,
+		HasAPICredentials:    store.APIKey != "" && (store.Platform != domain.EcommercePlatformWooCommerce || store.APISecret != ""),
+		HasWebhookSecret:     store.WebhookSecret != "",
+		WebhookPath:          ecommerceWebhookPath + store.ID.String(),
+		Enabled:              store.Enabled,
+		ImportAbandonedCarts: store.ImportAbandonedCarts,
+		OwnerID:              store.OwnerID.String(),
+		SyncedThrough:        store.SyncedThrough,
+		LastSyncAt:           store.LastSyncAt,
+		LastSyncError:        store.LastSyncError,
+		LastWebhookAt:        store.LastWebhookAt,
+		CreatedAt:            store.CreatedAt,
+		UpdatedAt:            store.UpdatedAt,
+		Version:              store.Version,
+	}
+	if store.PipelineID != nil {
+		pipelineID := store.PipelineID.String()
+		response.PipelineID = &pipelineID
+	}
+	return response
+}
+
+func mapEcommerceImportToResponse(record *domain.EcommerceImport) *dto.EcommerceImportResponse {
+	response := &dto.EcommerceImportResponse{
+		ID:         record.ID.String(),
+		Kind:       string(record.Kind),
+		ExternalID: record.ExternalID,
+		Reference:  record.Reference,
+		Email:      record.Email,
+		Amount:     record.Amount.Amount,
+		Currency:   record.Amount.Currency,
+		Status:     string(record.Status),
+		Reason:     record.Reason,
+		Attempts:   record.Attempts,
+		CreatedAt:  record.CreatedAt,
+		UpdatedAt:  record.UpdatedAt,
+	}
+	if record.CustomerID != nil {
+		id := record.CustomerID.String()
+		response.CustomerID = &id
+	}
+	if record.DealID != nil {
+		id := record.DealID.String()
+		response.DealID = &id
+	}
+	if record.LeadID != nil {
+		id := record.LeadID.String()
+		response.LeadID = &id
+	}
+	return response
+}
@@ -0,0 +1,496 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/kilang-desa-murni/crm/internal/sales/application"
+	"github.com/kilang-desa-murni/crm/internal/sales/application/dto"
+	"github.com/kilang-desa-murni/crm/internal/sales/application/ports"
+	"github.com/kilang-desa-murni/crm/internal/sales/domain"
+)
+
+// ============================================================================
+// Mock Implementations for E-Commerce Tests
+// ============================================================================
+
+// MockEcommerceStoreRepository is a mock implementation of domain.EcommerceStoreRepository.
+type MockEcommerceStoreRepository struct {
+	stores map[uuid.UUID]*domain.EcommerceStore
+}
+
+func NewMockEcommerceStoreRepository() *MockEcommerceStoreRepository {
+	return &MockEcommerceStoreRepository{stores: make(map[uuid.UUID]*domain.EcommerceStore)}
+}
+
+func (m *MockEcommerceStoreRepository) Create(ctx context.Context, store *domain.EcommerceStore) error {
+	m.stores[store.ID] = store
+	return nil
+}
+
+func (m *MockEcommerceStoreRepository) GetByID(ctx context.Context, tenantID, storeID uuid.UUID) (*domain.EcommerceStore, error) {
+	store, ok := m.stores[storeID]
+	if !ok || store.TenantID != tenantID {
+		return nil, domain.ErrEcommerceStoreNotFound
+	}
+	return store, nil
+}
+
+func (m *MockEcommerceStoreRepository) Update(ctx context.Context, store *domain.EcommerceStore) error {
+	m.stores[store.ID] = store
+	return nil
+}
+
+func (m *MockEcommerceStoreRepository) Delete(ctx context.Context, tenantID, storeID uuid.UUID) error {
+	if _, err := m.GetByID(ctx, tenantID, storeID); err != nil {
+		return err
+	}
+	delete(m.stores, storeID)
+	return nil
+}
+
+func (m *MockEcommerceStoreRepository) List(ctx context.Context, tenantID uuid.UUID) ([]*domain.EcommerceStore, error) {
+	var result []*domain.EcommerceStore
+	for _, store := range m.stores {
+		if store.TenantID == tenantID {
+			result = append(result, store)
+		}
+	}
+	return result, nil
+}
+
+func (m *MockEcommerceStoreRepository) GetForWebhook(ctx context.Context, storeID uuid.UUID) (*domain.EcommerceStore, error) {
+	store, ok := m.stores[storeID]
+	if !ok {
+		return nil, domain.ErrEcommerceStoreNotFound
+	}
+	return store, nil
+}
+
+func (m *MockEcommerceStoreRepository) ListEnabled(ctx context.Context) ([]*domain.EcommerceStore, error) {
+	var result []*domain.EcommerceStore
+	for _, store := range m.stores {
+		if store.Enabled {
+			result = append(result, store)
+		}
+	}
+	return result, nil
+}
+
+// MockEcommerceImportRepository is a mock implementation of domain.EcommerceImportRepository.
+type MockEcommerceImportRepository struct {
+	imports map[uuid.UUID]*domain.EcommerceImport
+}
+
+func NewMockEcommerceImportRepository() *MockEcommerceImportRepository {
+	return &MockEcommerceImportRepository{imports: make(map[uuid.UUID]*domain.EcommerceImport)}
+}
+
+func (m *MockEcommerceImportRepository) Create(ctx context.Context, record *domain.EcommerceImport) error {
+	if existing, _ := m.GetByExternalID(ctx, record.TenantID, record.StoreID, record.Kind, record.ExternalID); existing != nil {
+		return domain.ErrEcommerceImportAlreadyExists
+	}
+	m.imports[record.ID] = record
+	return nil
+}
+
+func (m *MockEcommerceImportRepository) Update(ctx context.Context, record *domain.EcommerceImport) error {
+	m.imports[record.ID] = record
+	return nil
+}
+
+func (m *MockEcommerceImportRepository) GetByExternalID(ctx context.Context, tenantID, storeID uuid.UUID, kind domain.EcommerceImportKind, externalID string) (*domain.EcommerceImport, error) {
+	for _, record := range m.imports {
+		if record.TenantID == tenantID && record.StoreID == storeID && record.Kind == kind && record.ExternalID == externalID {
+			return record, nil
+		}
+	}
+	return nil, nil
+}
+
+func (m *MockEcommerceImportRepository) List(ctx context.Context, tenantID, storeID uuid.UUID, statuses []domain.EcommerceImportStatus, limit int) ([]*domain.EcommerceImport, error) {
+	var result []*domain.EcommerceImport
+	for _, record := range m.imports {
+		if record.TenantID != tenantID || record.StoreID != storeID {
+			continue
+		}
+		matched := len(statuses) == 0
+		for _, status := range statuses {
+			if record.Status == status {
+				matched = true
+			}
+		}
+		if matched && len(result) < limit {
+			result = append(result, record)
+		}
+	}
+	return result, nil
+}
+
+func (m *MockEcommerceImportRepository) Summarize(ctx context.Context, tenantID, storeID uuid.UUID) (*domain.EcommerceImportSummary, error) {
+	summary := &domain.EcommerceImportSummary{}
+	records, _ := m.List(ctx, tenantID, storeID, nil, len(m.imports))
+	for _, record := range records {
+		switch record.Status {
+		case domain.EcommerceImportStatusImported:
+			if record.Kind == domain.EcommerceImportOrder {
+				summary.OrdersImported++
+			} else {
+				summary.LeadsCreated++
+			}
+		case domain.EcommerceImportStatusSkipped:
+			summary.Skipped++
+		case domain.EcommerceImportStatusFailed:
+			summary.Failed++
+		}
+	}
+	return summary, nil
+}
+
+// MockEcommerceClient is a mock implementation of ports.EcommerceClient. Its
+// webhooks are verified by an X-Test-Signature header equal to the secret.
+type MockEcommerceClient struct {
+	orders   []ports.EcommerceOrder
+	carts    []ports.EcommerceCart
+	fetchErr error
+	since    []time.Time
+}
+
+func (m *MockEcommerceClient) Platform() string {
+	return "shopify"
+}
+
+func (m *MockEcommerceClient) FetchOrders(ctx context.Context, store ports.EcommerceStoreAccess, since time.Time) ([]ports.EcommerceOrder, error) {
+	m.since = append(m.since, since)
+	if m.fetchErr != nil {
+		return nil, m.fetchErr
+	}
+	return m.orders, nil
+}
+
+func (m *MockEcommerceClient) FetchAbandonedCarts(ctx context.Context, store ports.EcommerceStoreAccess, since time.Time) ([]ports.EcommerceCart, error) {
+	return m.carts, nil
+}
+
+func (m *MockEcommerceClient) ParseWebhook(ctx context.Context, store ports.EcommerceStoreAccess, headers map[string]string, body []byte) (*ports.EcommerceWebhook, error) {
+	if headers["X-Test-Signature"] != store.WebhookSecret {
+		return nil, ports.ErrInvalidEcommerceWebhook
+	}
+	return &ports.EcommerceWebhook{Orders: m.orders, Carts: m.carts}, nil
+}
+
+// ============================================================================
+// Helper Functions
+// ============================================================================
+
+type ecommerceTestEnv struct {
+	uc        EcommerceUseCase
+	stores    *MockEcommerceStoreRepository
+	imports   *MockEcommerceImportRepository
+	pipelines *MockPipelineRepository
+	deals     *DealMockDealRepository
+	leads     *MockLeadRepository
+	customers *DealMockCustomerService
+	matcher   *MockCustomerMatcher
+	client    *MockEcommerceClient
+	tenantID  uuid.UUID
+	ownerID   uuid.UUID
+	store     *dto.EcommerceStoreResponse
+}
+
+func setupEcommerceTest(t *testing.T) *ecommerceTestEnv {
+	t.Helper()
+
+	env := &ecommerceTestEnv{
+		stores:    NewMockEcommerceStoreRepository(),
+		imports:   NewMockEcommerceImportRepository(),
+		pipelines: NewMockPipelineRepository(),
+		deals:     NewDealMockDealRepository(),
+		leads:     NewMockLeadRepository(),
+		customers: NewDealMockCustomerService(),
+		matcher:   &MockCustomerMatcher{},
+		client:    &MockEcommerceClient{},
+		tenantID:  uuid.New(),
+		ownerID:   uuid.New(),
+	}
+	pipeline := createTestPipeline(env.tenantID)
+	env.pipelines.pipelines[pipeline.ID] = pipeline
+
+	env.uc = NewEcommerceUseCase(
+		env.stores, env.imports, env.pipelines, NewDealMockOpportunityRepository(), env.deals, env.leads,
+		env.customers, env.matcher, NewDealMockIDGenerator(), NewDealMockEventPublisher(),
+		[]ports.EcommerceClient{env.client},
+	)
+
+	store, err := env.uc.CreateStore(context.Background(), env.tenantID, env.ownerID, &dto.CreateEcommerceStoreRequest{
+		Platform:             "shopify",
+		Name:                 "Batik Online",
+		StoreURL:             "https://batik.myshopify.com",
+		APIKey:               "shpat_token",
+		WebhookSecret:        "whsec",
+		ImportAbandonedCarts: true,
+	})
+	if err != nil {
+		t.Fatalf("CreateStore() error = %v", err)
+	}
+	env.store = store
+	return env
+}
+
+func (env *ecommerceTestEnv) storeID() uuid.UUID {
+	return uuid.MustParse(env.store.ID)
+}
+
+func createEcommerceTestOrder(externalID string) ports.EcommerceOrder {
+	placedAt := time.Date(2026, 3, 10, 9, 30, 0, 0, time.UTC)
+	return ports.EcommerceOrder{
+		ExternalID: externalID,
+		Number:     "#" + externalID,
+		Customer: ports.EcommerceCustomer{
+			Email:     "aminah@example.com",
+			Phone:     "+60123456789",
+			FirstName: "Aminah",
+			LastName:  "Yusof",
+			City:      "Kuala Terengganu",
+			Country:   "MY",
+		},
+		Currency: "MYR",
+		Lines: []ports.EcommerceLine{
+			{SKU: "BTK-SUTERA-01", Name: "Batik Sutera Sarong", Quantity: 2, UnitPrice: 18000},
+		},
+		Shipping:  1000,
+		Total:     37000,
+		Paid:      true,
+		CreatedAt: placedAt,
+		UpdatedAt: placedAt.Add(time.Minute),
+	}
+}
+
+// ============================================================================
+// EcommerceUseCase Tests
+// ============================================================================
+
+func TestEcommerceUseCase_CreateStore(t *testing.T) {
+	env := setupEcommerceTest(t)
+
+	if !env.store.HasAPICredentials || !env.store.HasWebhookSecret || env.store.OwnerID != env.ownerID.String() {
+		t.Errorf("store = %+v, want credentials reported as set and the creator as owner", env.store)
+	}
+	if env.store.WebhookPath != "/api/v1/ecommerce/webhooks/"+env.store.ID {
+		t.Errorf("WebhookPath = %q, want the store's webhook path", env.store.WebhookPath)
+	}
+
+	// WooCommerce has no client in this use case
+	_, err := env.uc.CreateStore(context.Background(), env.tenantID, env.ownerID, &dto.CreateEcommerceStoreRequest{
+		Platform: "woocommerce",
+		Name:     "Kedai",
+		StoreURL: "https://shop.example.com",
+	})
+	if appErr := application.GetAppError(err); appErr == nil || appErr.Code != application.ErrCodeValidation {
+		t.Errorf("CreateStore() for an unsupported platform error = %v, want %s", err, application.ErrCodeValidation)
+	}
+
+	if _, err := env.uc.GetStore(context.Background(), uuid.New(), env.storeID()); !application.IsNotFoundError(err) {
+		t.Errorf("GetStore() from another tenant error = %v, want not found", err)
+	}
+}
+
+func TestEcommerceUseCase_ReceiveWebhook_ImportsOrder(t *testing.T) {
+	ctx := context.Background()
+	env := setupEcommerceTest(t)
+	env.client.orders = []ports.EcommerceOrder{createEcommerceTestOrder("1001")}
+
+	_, err := env.uc.ReceiveWebhook(ctx, env.storeID(), map[string]string{"X-Test-Signature": "wrong"}, nil)
+	if appErr := application.GetAppError(err); appErr == nil || appErr.Code != application.ErrCodeUnauthorized {
+		t.Fatalf("ReceiveWebhook() with a bad signature error = %v, want %s", err, application.ErrCodeUnauthorized)
+	}
+
+	result, err := env.uc.ReceiveWebhook(ctx, env.storeID(), map[string]string{"X-Test-Signature": "whsec"}, nil)
+	if err != nil {
+		t.Fatalf("ReceiveWebhook() error = %v", err)
+	}
+	if result.Received != 1 || result.Imported != 1 {
+		t.Fatalf("result = %+v, want the order imported", result)
+	}
+
+	if len(env.deals.deals) != 1 {
+		t.Fatalf("deals = %d, want 1", len(env.deals.deals))
+	}
+	var deal *domain.Deal
+	for _, d := range env.deals.deals {
+		deal = d
+	}
+	if deal.TotalAmount.Amount != 37000 || deal.Code != "DEAL-001" || len(deal.LineItems) != 2 {
+		t.Errorf("deal total, code, lines = %d, %q, %d, want 37000, DEAL-001 and the product and shipping lines", deal.TotalAmount.Amount, deal.Code, len(deal.LineItems))
+	}
+	if !deal.WonAt.Equal(time.Date(2026, 3, 10, 9, 30, 0, 0, time.UTC)) || deal.OwnerID != env.ownerID {
+		t.Errorf("deal won at %v by owner %v, want the order time and the store owner", deal.WonAt, deal.OwnerID)
+	}
+
+	// The shopper had no customer, so one was created with a primary contact
+	if len(env.customers.customers) != 1 || len(env.customers.contacts) != 1 {
+		t.Fatalf("customers, contacts = %d, %d, want 1, 1", len(env.customers.customers), len(env.customers.contacts))
+	}
+	if env.customers.customers[deal.CustomerID] == nil || deal.CustomerName != "Aminah Yusof" {
+		t.Errorf("deal customer = %v %q, want the created customer", deal.CustomerID, deal.CustomerName)
+	}
+
+	// Stores retry webhooks, and polls overlap them
+	result, err = env.uc.ReceiveWebhook(ctx, env.storeID(), map[string]string{"X-Test-Signature": "whsec"}, nil)
+	if err != nil {
+		t.Fatalf("ReceiveWebhook() again error = %v", err)
+	}
+	if result.Imported != 0 || len(env.deals.deals) != 1 {
+		t.Errorf("result = %+v with %d deals, want the order imported once", result, len(env.deals.deals))
+	}
+
+	store, _ := env.uc.GetStore(ctx, env.tenantID, env.storeID())
+	if store.LastWebhookAt == nil {
+		t.Error("LastWebhookAt = nil, want the webhook recorded")
+	}
+}
+
+func TestEcommerceUseCase_ImportOrder_MatchesCustomer(t *testing.T) {
+	ctx := context.Background()
+	env := setupEcommerceTest(t)
+
+	existingID := uuid.New()
+	env.matcher.matches = []*ports.CustomerMatch{
+		{CustomerInfo: ports.CustomerInfo{ID: existingID, Name: "Butik Aminah"}, MatchedOn: []string{"phone"}},
+	}
+	cancelled := createEcommerceTestOrder("1002")
+	cancelled.Cancelled = true
+	env.client.orders = []ports.EcommerceOrder{createEcommerceTestOrder("1001"), cancelled}
+
+	result, err := env.uc.ReceiveWebhook(ctx, env.storeID(), map[string]string{"X-Test-Signature": "whsec"}, nil)
+	if err != nil {
+		t.Fatalf("ReceiveWebhook() error = %v", err)
+	}
+	if result.Imported != 1 || result.Skipped != 1 {
+		t.Errorf("result = %+v, want the order imported and the cancelled order skipped", result)
+	}
+	if len(env.customers.customers) != 0 {
+		t.Errorf("customers created = %d, want the matching customer used", len(env.customers.customers))
+	}
+	for _, deal := range env.deals.deals {
+		if deal.CustomerID != existingID || deal.CustomerName != "Butik Aminah" {
+			t.Errorf("deal customer = %v %q, want the matching customer", deal.CustomerID, deal.CustomerName)
+		}
+	}
+}
+
+func TestEcommerceUseCase_ImportCart(t *testing.T) {
+	ctx := context.Background()
+	env := setupEcommerceTest(t)
+
+	existing := createTestLead(env.tenantID)
+	env.leads.leads[existing.ID] = existing
+
+	env.client.carts = []ports.EcommerceCart{
+		{
+			ExternalID:  "cart-new",
+			Customer:    ports.EcommerceCustomer{Email: "Siti@Example.com", FirstName: "Siti"},
+			Currency:    "MYR",
+			Lines:       []ports.EcommerceLine{{Name: "Batik Tulis Kaftan", Quantity: 1, UnitPrice: 45000}},
+			Total:       45000,
+			RecoveryURL: "https://batik.myshopify.com/recover/abc",
+		},
+		{ExternalID: "cart-lead", Customer: ports.EcommerceCustomer{Email: existing.Contact.Email}, Currency: "MYR", Total: 9000},
+		{ExternalID: "cart-done", Customer: ports.EcommerceCustomer{Email: "done@example.com"}, Currency: "MYR", Completed: true},
+	}
+
+	result, err := env.uc.ReceiveWebhook(ctx, env.storeID(), map[string]string{"X-Test-Signature": "whsec"}, nil)
+	if err != nil {
+		t.Fatalf("ReceiveWebhook() error = %v", err)
+	}
+	if result.Received != 3 || result.Imported != 1 || result.Skipped != 2 {
+		t.Fatalf("result = %+v, want one lead created and two carts skipped", result)
+	}
+
+	var lead *domain.Lead
+	for _, l := range env.leads.leads {
+		if l.ID != existing.ID {
+			lead = l
+		}
+	}
+	if lead == nil || lead.Contact.Email != "siti@example.com" || lead.Source != domain.LeadSourceWebsite {
+		t.Fatalf("lead = %+v, want a website lead for the shopper", lead)
+	}
+	if lead.EstimatedValue.Amount != 45000 || lead.OwnerID == nil || *lead.OwnerID != env.ownerID {
+		t.Errorf("lead value, owner = %d, %v, want the cart total and the store owner", lead.EstimatedValue.Amount, lead.OwnerID)
+	}
+
+	// The existing lead is linked to the cart it left
+	record, _ := env.imports.GetByExternalID(ctx, env.tenantID, env.storeID(), domain.EcommerceImportAbandonedCart, "cart-lead")
+	if record.Status != domain.EcommerceImportStatusSkipped || record.LeadID == nil || *record.LeadID != existing.ID {
+		t.Errorf("import = %+v, want it skipped and linked to the existing lead", record)
+	}
+
+	// Stores that do not import carts ignore them
+	importCarts := false
+	if _, err := env.uc.UpdateStore(ctx, env.tenantID, env.storeID(), &dto.UpdateEcommerceStoreRequest{ImportAbandonedCarts: &importCarts}); err != nil {
+		t.Fatalf("UpdateStore() error = %v", err)
+	}
+	env.client.carts = []ports.EcommerceCart{{ExternalID: "cart-later", Customer: ports.EcommerceCustomer{Email: "later@example.com"}, Currency: "MYR"}}
+	if result, _ := env.uc.ReceiveWebhook(ctx, env.storeID(), map[string]string{"X-Test-Signature": "whsec"}, nil); result.Received != 0 {
+		t.Errorf("result = %+v, want carts ignored", result)
+	}
+}
+
+func TestEcommerceUseCase_ProcessDue(t *testing.T) {
+	ctx := context.Background()
+	env := setupEcommerceTest(t)
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+
+	// Without a default pipeline the order fails, and is retried next poll
+	for _, pipeline := range env.pipelines.pipelines {
+		pipeline.IsDefault = false
+	}
+	order := createEcommerceTestOrder("1001")
+	env.client.orders = []ports.EcommerceOrder{order}
+
+	imported, err := env.uc.ProcessDue(ctx, now)
+	if err != nil || imported != 0 {
+		t.Fatalf("ProcessDue() = %d, %v, want nothing imported", imported, err)
+	}
+	status, err := env.uc.GetSyncStatus(ctx, env.tenantID, env.storeID())
+	if err != nil {
+		t.Fatalf("GetSyncStatus() error = %v", err)
+	}
+	if status.Failed != 1 || len(status.RecentFailures) != 1 || status.RecentFailures[0].Reason != "the tenant has no default pipeline" {
+		t.Errorf("status = %+v, want the failed order listed", status)
+	}
+	if status.Store.SyncedThrough == nil || !status.Store.SyncedThrough.Equal(order.UpdatedAt) {
+		t.Errorf("SyncedThrough = %v, want the order's update time", status.Store.SyncedThrough)
+	}
+
+	// The failed order is imported when the overlap sends it again
+	for _, pipeline := range env.pipelines.pipelines {
+		pipeline.IsDefault = true
+	}
+	if imported, err = env.uc.ProcessDue(ctx, now.Add(15*time.Minute)); err != nil || imported != 1 {
+		t.Fatalf("ProcessDue() = %d, %v, want the failed order imported", imported, err)
+	}
+	if got := env.client.since[1]; !got.Equal(order.UpdatedAt.Add(-domain.EcommerceSyncOverlap)) {
+		t.Errorf("second poll since = %v, want the overlap before the last synced order", got)
+	}
+
+	status, _ = env.uc.GetSyncStatus(ctx, env.tenantID, env.storeID())
+	if status.OrdersImported != 1 || status.Failed != 0 {
+		t.Errorf("status = %+v, want the order imported", status)
+	}
+
+	// A failing poll is reported and keeps the sync position
+	env.client.fetchErr = errors.New("shopify: unexpected status 503")
+	if _, err := env.uc.ProcessDue(ctx, now.Add(30*time.Minute)); err != nil {
+		t.Fatalf("ProcessDue() error = %v", err)
+	}
+	store, _ := env.uc.GetStore(ctx, env.tenantID, env.storeID())
+	if store.LastSyncError != "shopify: unexpected status 503" || !store.SyncedThrough.Equal(order.UpdatedAt) {
+		t.Errorf("store = %+v, want the error recorded and the position kept", store)
+	}
+}
@@ -0,0 +1,682 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/kilang-desa-murni/crm/internal/sales/application"
+	"github.com/kilang-desa-murni/crm/internal/sales/application/dto"
+	"github.com/kilang-desa-murni/crm/internal/sales/application/ports"
+	"github.com/kilang-desa-murni/crm/internal/sales/domain"
+)
+
+// einvoiceBatchSize is how many e-invoices are loaded per batch when
+// submitting and checking them in the background.
+const einvoiceBatchSize = 50
+
+// einvoiceClassificationOthers is the MyInvois classification of goods and
+// services not otherwise classified.
+const einvoiceClassificationOthers = "022"
+
+// ============================================================================
+// E-Invoice Use Case Interface
+// ============================================================================
+
+// EInvoiceUseCase defines the interface for submitting deal invoices to
+// LHDN's MyInvois e-invoicing system.
+type EInvoiceUseCase interface {
+	// Settings
+	GetSettings(ctx context.Context, tenantID uuid.UUID) (*dto.EInvoiceSettingsResponse, error)
+	UpdateSettings(ctx context.Context, tenantID uuid.UUID, req *dto.UpdateEInvoiceSettingsRequest) (*dto.EInvoiceSettingsResponse, error)
+
+	// Invoice e-invoices
+	Submit(ctx context.Context, tenantID, dealID, invoiceID, userID uuid.UUID, req *dto.SubmitEInvoiceRequest) (*dto.EInvoiceResponse, error)
+	Get(ctx context.Context, tenantID, dealID, invoiceID uuid.UUID) (*dto.EInvoiceResponse, error)
+	Resubmit(ctx context.Context, tenantID, dealID, invoiceID uuid.UUID, req *dto.ResubmitEInvoiceRequest) (*dto.EInvoiceResponse, error)
+	Cancel(ctx context.Context, tenantID, dealID, invoiceID uuid.UUID, req *dto.CancelEInvoiceRequest) (*dto.EInvoiceResponse, error)
+
+	// ProcessDue submits the pending e-invoices of all tenants and checks the
+	// validation of submitted ones, returning how many changed status.
+	ProcessDue(ctx context.Context, now time.Time) (int, error)
+}
+
+// ============================================================================
+// E-Invoice Use Case Implementation
+// ============================================================================
+
+// einvoiceUseCase implements EInvoiceUseCase.
+type einvoiceUseCase struct {
+	einvoiceRepo   domain.EInvoiceRepository
+	settingsRepo   domain.EInvoiceSettingsRepository
+	dealRepo       domain.DealRepository
+	taxRepo        domain.TaxSettingsRepository
+	eventPublisher ports.EventPublisher
+	service        ports.EInvoiceService
+}
+
+// NewEInvoiceUseCase creates a new e-invoice use case. Without a service,
+// e-invoices cannot be submitted or cancelled.
+func NewEInvoiceUseCase(
+	einvoiceRepo domain.EInvoiceRepository,
+	settingsRepo domain.EInvoiceSettingsRepository,
+	dealRepo domain.DealRepository,
+	taxRepo domain.TaxSettingsRepository,
+	eventPublisher ports.EventPublisher,
+	service ports.EInvoiceService,
+) EInvoiceUseCase {
+	return &einvoiceUseCase{
+		einvoiceRepo:   einvoiceRepo,
+		settingsRepo:   settingsRepo,
+		dealRepo:       dealRepo,
+		taxRepo:        taxRepo,
+		eventPublisher: eventPublisher,
+		service:        service,
+	}
+}
+
+// ============================================================================
+// Settings
+// ============================================================================
+
+// GetSettings returns the tenant's e-invoice settings, or the defaults if none are configured.
+func (uc *einvoiceUseCase) GetSettings(ctx context.Context, tenantID uuid.UUID) (*dto.EInvoiceSettingsResponse, error) {
+	settings, err := uc.loadSettings(ctx, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	return mapEInvoiceSettingsToResponse(settings), nil
+}
+
+// UpdateSettings updates the tenant's e-invoice settings.
+func (uc *einvoiceUseCase) UpdateSettings(ctx context.Context, tenantID uuid.UUID, req *dto.UpdateEInvoiceSettingsRequest) (*dto.EInvoiceSettingsResponse, error) {
+	settings, err := uc.loadSettings(ctx, tenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Enabled != nil {
+		settings.Enabled = *req.Enabled
+	}
+	if req.Name != nil {
+		settings.Name = *req.Name
+	}
+	if req.TIN != nil {
+		settings.TIN = *req.TIN
+	}
+	if req.RegistrationNumber != nil {
+		settings.RegistrationNumber = *req.RegistrationNumber
+	}
+	if req.SSTNumber != nil {
+		settings.SSTNumber = *req.SSTNumber
+	}
+	if req.MSICCode != nil {
+		settings.MSICCode = *req.MSICCode
+	}
+	if req.BusinessActivity != nil {
+		settings.BusinessActivity = *req.BusinessActivity
+	}
+	if req.Email != nil {
+		settings.Email = *req.Email
+	}
+	if req.Phone != nil {
+		settings.Phone = *req.Phone
+	}
+	if req.Address != nil {
+		settings.Address = mapEInvoiceAddressFromDTO(*req.Address)
+	}
+
+	settings.Normalize()
+	if err := settings.Validate(); err != nil {
+		return nil, application.ErrValidation(err.Error())
+	}
+
+	settings.UpdatedAt = time.Now().UTC()
+	if err := uc.settingsRepo.Upsert(ctx, settings); err != nil {
+		return nil, application.WrapError(application.ErrCodeInternal, "failed to save e-invoice settings", err)
+	}
+
+	return mapEInvoiceSettingsToResponse(settings), nil
+}
+
+// ============================================================================
+// Invoice E-Invoices
+// ============================================================================
+
+// Submit queues an issued invoice for MyInvois and submits it straight away.
+// A submission that cannot reach MyInvois is retried in the background.
+func (uc *einvoiceUseCase) Submit(ctx context.Context, tenantID, dealID, invoiceID, userID uuid.UUID, req *dto.SubmitEInvoiceRequest) (*dto.EInvoiceResponse, error) {
+	if uc.service == nil {
+		return nil, application.ErrServiceUnavailable("e-invoicing")
+	}
+
+	settings, err := uc.loadSettings(ctx, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	if !settings.Enabled {
+		return nil, application.ErrEInvoiceNotEnabled()
+	}
+
+	deal, invoice, err := uc.getInvoice(ctx, tenantID, dealID, invoiceID)
+	if err != nil {
+		return nil, err
+	}
+
+	existing, err := uc.einvoiceRepo.GetByInvoice(ctx, tenantID, invoiceID)
+	if err != nil {
+		return nil, application.WrapError(application.ErrCodeInternal, "failed to get e-invoice", err)
+	}
+	if existing != nil && existing.Status != domain.EInvoiceStatusCancelled {
+		return nil, application.ErrEInvoiceAlreadyExists(invoiceID)
+	}
+
+	buyer := domain.GeneralPublicBuyer(deal.CustomerName)
+	if req.Buyer != nil {
+		buyer = mapEInvoicePartyFromDTO(*req.Buyer)
+	}
+
+	einvoice, err := domain.NewEInvoice(deal, invoice, buyer, userID)
+	if err != nil {
+		return nil, application.ErrValidation(err.Error())
+	}
+
+	if err := uc.einvoiceRepo.Create(ctx, einvoice); err != nil {
+		return nil, application.WrapError(application.ErrCodeInternal, "failed to create e-invoice", err)
+	}
+	uc.publishEvents(ctx, einvoice)
+
+	uc.submit(ctx, einvoice, settings, deal, invoice, time.Now().UTC())
+	if err := uc.saveEInvoice(ctx, einvoice); err != nil {
+		return nil, err
+	}
+
+	return mapEInvoiceToResponse(einvoice), nil
+}
+
+// Get returns the latest e-invoice of an invoice.
+func (uc *einvoiceUseCase) Get(ctx context.Context, tenantID, dealID, invoiceID uuid.UUID) (*dto.EInvoiceResponse, error) {
+	einvoice, err := uc.getEInvoice(ctx, tenantID, dealID, invoiceID)
+	if err != nil {
+		return nil, err
+	}
+	return mapEInvoiceToResponse(einvoice), nil
+}
+
+// Resubmit submits a rejected or failed e-invoice again.
+func (uc *einvoiceUseCase) Resubmit(ctx context.Context, tenantID, dealID, invoiceID uuid.UUID, req *dto.ResubmitEInvoiceRequest) (*dto.EInvoiceResponse, error) {
+	if uc.service == nil {
+		return nil, application.ErrServiceUnavailable("e-invoicing")
+	}
+
+	einvoice, err := uc.getEInvoice(ctx, tenantID, dealID, invoiceID)
+	if err != nil {
+		return nil, err
+	}
+
+	settings, err := uc.loadSettings(ctx, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	if !settings.Enabled {
+		return nil, application.ErrEInvoiceNotEnabled()
+	}
+
+	deal, invoice, err := uc.getInvoice(ctx, tenantID, dealID, invoiceID)
+	if err != nil {
+		return nil, err
+	}
+
+	var buyer *domain.EInvoiceParty
+	if req.Buyer != nil {
+		party := mapEInvoicePartyFromDTO(*req.Buyer)
+		buyer = &party
+	}
+
+	now := time.Now().UTC()
+	if err := einvoice.Resubmit(buyer, now); err != nil {
+		if errors.Is(err, domain.ErrEInvoiceInvalidStatusTransition) {
+			return nil, application.ErrEInvoiceInvalidState(invoiceID, string(einvoice.Status), "resubmitted")
+		}
+		return nil, application.ErrValidation(err.Error())
+	}
+
+	uc.submit(ctx, einvoice, settings, deal, invoice, now)
+	if err := uc.saveEInvoice(ctx, einvoice); err != nil {
+		return nil, err
+	}
+
+	return mapEInvoiceToResponse(einvoice), nil
+}
+
+// Cancel cancels a valid e-invoice with MyInvois, which allows it within 72
+// hours of validation.
+func (uc *einvoiceUseCase) Cancel(ctx context.Context, tenantID, dealID, invoiceID uuid.UUID, req *dto.CancelEInvoiceRequest) (*dto.EInvoiceResponse, error) {
+	if uc.service == nil {
+		return nil, application.ErrServiceUnavailable("e-invoicing")
+	}
+
+	einvoice, err := uc.getEInvoice(ctx, tenantID, dealID, invoiceID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	if err := einvoice.CheckCancellable(now); err != nil {
+		if errors.Is(err, domain.ErrEInvoiceCancellationWindowClosed) {
+			return nil, application.NewAppError(application.ErrCodeEInvoiceInvalidTransition, err.Error())
+		}
+		return nil, application.ErrEInvoiceInvalidState(invoiceID, string(einvoice.Status), "cancelled")
+	}
+
+	settings, err := uc.loadSettings(ctx, tenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := uc.service.Cancel(ctx, settings.TIN, einvoice.DocumentUUID, req.Reason); err != nil {
+		return nil, application.WrapError(application.ErrCodeServiceUnavailable, "failed to cancel e-invoice with MyInvois", err)
+	}
+
+	if err := einvoice.Cancel(req.Reason, now); err != nil {
+		return nil, application.ErrValidation(err.Error())
+	}
+	if err := uc.saveEInvoice(ctx, einvoice); err != nil {
+		return nil, err
+	}
+
+	return mapEInvoiceToResponse(einvoice), nil
+}
+
+// ============================================================================
+// Background Processing
+// ============================================================================
+
+// ProcessDue submits the pending e-invoices of all tenants that are due,
+// including retries, and checks the validation of submitted ones.
+func (uc *einvoiceUseCase) ProcessDue(ctx context.Context, now time.Time) (int, error) {
+	if uc.service == nil {
+		return 0, nil
+	}
+
+	changed := 0
+	seen := make(map[uuid.UUID]bool)
+	settingsByTenant := make(map[uuid.UUID]*domain.EInvoiceSettings)
+	for {
+		einvoices, err := uc.einvoiceRepo.GetDue(ctx, now, einvoiceBatchSize)
+		if err != nil {
+			return changed, application.WrapError(application.ErrCodeInternal, "failed to get e-invoices due", err)
+		}
+
+		progressed := false
+		for _, einvoice := range einvoices {
+			if seen[einvoice.ID] {
+				continue
+			}
+			seen[einvoice.ID] = true
+			progressed = true
+
+			settings, ok := settingsByTenant[einvoice.TenantID]
+			if !ok {
+				if settings, err = uc.loadSettings(ctx, einvoice.TenantID); err != nil {
+					continue
+				}
+				settingsByTenant[einvoice.TenantID] = settings
+			}
+
+			status := einvoice.Status
+			switch status {
+			case domain.EInvoiceStatusPending:
+				uc.retry(ctx, einvoice, settings, now)
+			case domain.EInvoiceStatusSubmitted:
+				uc.checkValidation(ctx, einvoice, settings, now)
+			default:
+				continue
+			}
+
+			// An e-invoice that failed to save is due again, so it is retried
+			// by a later run
+			if err := uc.saveEInvoice(ctx, einvoice); err != nil {
+				continue
+			}
+			if einvoice.Status != status {
+				changed++
+			}
+		}
+
+		if len(einvoices) < einvoiceBatchSize || !progressed {
+			return changed, nil
+		}
+	}
+}
+
+// retry submits a pending e-invoice again.
+func (uc *einvoiceUseCase) retry(ctx context.Context, einvoice *domain.EInvoice, settings *domain.EInvoiceSettings, now time.Time) {
+	if !settings.Enabled {
+		einvoice.MarkFailed("e-invoicing is disabled for the tenant", now)
+		return
+	}
+
+	deal, invoice, err := uc.getInvoice(ctx, einvoice.TenantID, einvoice.DealID, einvoice.InvoiceID)
+	if err != nil {
+		einvoice.MarkFailed(err.Error(), now)
+		return
+	}
+	uc.submit(ctx, einvoice, settings, deal, invoice, now)
+}
+
+// submit submits a pending e-invoice. Rejections mark it invalid; failures to
+// reach MyInvois schedule a retry.
+func (uc *einvoiceUseCase) submit(ctx context.Context, einvoice *domain.EInvoice, settings *domain.EInvoiceSettings, deal *domain.Deal, invoice *domain.Invoice, now time.Time) {
+	doc := uc.buildDocument(ctx, einvoice, settings, deal, invoice, now)
+
+	result, err := uc.service.Submit(ctx, doc)
+	if err != nil {
+		einvoice.MarkFailed(err.Error(), now)
+		return
+	}
+	if result.DocumentUUID == "" {
+		einvoice.MarkInvalid(result.Errors, now)
+		return
+	}
+	einvoice.MarkSubmitted(result.SubmissionUID, result.DocumentUUID, now)
+}
+
+// checkValidation records the validation result of a submitted e-invoice.
+func (uc *einvoiceUseCase) checkValidation(ctx context.Context, einvoice *domain.EInvoice, settings *domain.EInvoiceSettings, now time.Time) {
+	result, err := uc.service.GetDocument(ctx, settings.TIN, einvoice.DocumentUUID)
+	if err != nil {
+		einvoice.ScheduleCheck(err.Error(), now)
+		return
+	}
+
+	switch result.Status {
+	case "valid":
+		einvoice.MarkValid(result.LongID, result.ValidationURL, now)
+	case "invalid":
+		einvoice.MarkInvalid(result.Errors, now)
+	default:
+		einvoice.ScheduleCheck("", now)
+	}
+}
+
+// buildDocument builds the e-invoice document of a deal invoice. The invoice
+// is a single line for the deal, taxed as the invoice was.
+func (uc *einvoiceUseCase) buildDocument(ctx context.Context, einvoice *domain.EInvoice, settings *domain.EInvoiceSettings, deal *domain.Deal, invoice *domain.Invoice, now time.Time) *ports.EInvoiceDocument {
+	taxTypes := make(map[string]domain.TaxType)
+	if uc.taxRepo != nil {
+		if taxSettings, err := uc.taxRepo.Get(ctx, einvoice.TenantID); err == nil && taxSettings != nil {
+			for _, rate := range taxSettings.Rates {
+				taxTypes[rate.Code] = rate.Type
+			}
+		}
+	}
+
+	taxes := make([]ports.EInvoiceTax, 0, len(invoice.TaxLines))
+	for _, line := range invoice.TaxLines {
+		tax := ports.EInvoiceTax{
+			TaxType:       einvoiceTaxType(taxTypes[line.Code]),
+			Rate:          line.Rate,
+			TaxableAmount: line.TaxableAmount.Amount,
+			TaxAmount:     line.TaxAmount.Amount,
+		}
+		if tax.TaxType == "E" {
+			tax.ExemptionReason = line.Name
+		}
+		taxes = append(taxes, tax)
+	}
+	if len(taxes) == 0 {
+		taxes = append(taxes, ports.EInvoiceTax{
+			TaxType:       einvoiceTaxType(""),
+			TaxableAmount: invoice.Subtotal.Amount,
+		})
+	}
+
+	supplier := ports.EInvoiceParty{
+		Name:               settings.Name,
+		TIN:                settings.TIN,
+		RegistrationNumber: settings.RegistrationNumber,
+		SSTNumber:          settings.SSTNumber,
+		MSICCode:           settings.MSICCode,
+		BusinessActivity:   settings.BusinessActivity,
+		Email:              settings.Email,
+		Phone:              settings.Phone,
+		Address:            mapEInvoiceAddressToPort(settings.Address),
+	}
+	buyer := ports.EInvoiceParty{
+		Name:               einvoice.Buyer.Name,
+		TIN:                einvoice.Buyer.TIN,
+		RegistrationNumber: einvoice.Buyer.RegistrationNumber,
+		SSTNumber:          einvoice.Buyer.SSTNumber,
+		Email:              einvoice.Buyer.Email,
+		Phone:              einvoice.Buyer.Phone,
+		Address:            mapEInvoiceAddressToPort(einvoice.Buyer.Address),
+	}
+
+	return &ports.EInvoiceDocument{
+		InvoiceNumber: invoice.InvoiceNumber,
+		IssuedAt:      now,
+		Currency:      invoice.Amount.Currency,
+		Supplier:      supplier,
+		Buyer:         buyer,
+		Lines: []ports.EInvoiceLine{
+			{
+				Description:        fmt.Sprintf("%s (%s)", deal.Name, deal.Code),
+				ClassificationCode: einvoiceClassificationOthers,
+				Quantity:           1,
+				UnitPrice:          invoice.Subtotal.Amount,
+				Subtotal:           invoice.Subtotal.Amount,
+				TaxAmount:          invoice.TaxAmount.Amount,
+				Taxes:              taxes,
+			},
+		},
+		Taxes:     taxes,
+		Subtotal:  invoice.Subtotal.Amount,
+		TaxAmount: invoice.TaxAmount.Amount,
+		Total:     invoice.Amount.Amount,
+	}
+}
+
+// einvoiceTaxType returns the MyInvois tax type code of a tax type.
+func einvoiceTaxType(taxType domain.TaxType) string {
+	switch taxType {
+	case domain.TaxTypeSalesTax:
+		return "01"
+	case domain.TaxTypeServiceTax:
+		return "02"
+	case domain.TaxTypeExempt:
+		return "E"
+	default:
+		return "06" // Not applicable
+	}
+}
+
+// ============================================================================
+// Helpers
+// ============================================================================
+
+func (uc *einvoiceUseCase) loadSettings(ctx context.Context, tenantID uuid.UUID) (*domain.EInvoiceSettings, error) {
+	settings, err := uc.settingsRepo.Get(ctx, tenantID)
+	if err != nil {
+		return nil, application.WrapError(application.ErrCodeInternal, "failed to get e-invoice settings", err)
+	}
+	if settings == nil {
+		settings = domain.DefaultEInvoiceSettings(tenantID)
+	}
+	return settings, nil
+}
+
+func (uc *einvoiceUseCase) getInvoice(ctx context.Context, tenantID, dealID, invoiceID uuid.UUID) (*domain.Deal, *domain.Invoice, error) {
+	deal, err := uc.dealRepo.GetByID(ctx, tenantID, dealID)
+	if err != nil {
+		return nil, nil, application.ErrDealNotFound(dealID)
+	}
+	for i := range deal.Invoices {
+		if deal.Invoices[i].ID == invoiceID {
+			return deal, &deal.Invoices[i], nil
+		}
+	}
+	return nil, nil, application.ErrDealInvoiceNotFound(dealID, invoiceID)
+}
+
+func (uc *einvoiceUseCase) getEInvoice(ctx context.Context, tenantID, dealID, invoiceID uuid.UUID) (*domain.EInvoice, error) {
+	einvoice, err := uc.einvoiceRepo.GetByInvoice(ctx, tenantID, invoiceID)
+	if err != nil {
+		return nil, application.WrapError(application.ErrCodeInternal, "failed to get e-invoice", err)
+	}
+	if einvoice == nil || einvoice.DealID != dealID {
+		return nil, application.ErrEInvoiceNotFound(invoiceID)
+	}
+	return einvoice, nil
+}
+
+func (uc *einvoiceUseCase) saveEInvoice(ctx context.Context, einvoice *domain.EInvoice) error {
+	einvoice.Version++
+	if err := uc.einvoiceRepo.Update(ctx, einvoice); err != nil {
+		if errors.Is(err, domain.ErrEInvoiceNotFound) {
+			return application.ErrConcurrentModification("e-invoice", einvoice.ID)
+		}
+		return application.WrapError(application.ErrCodeInternal, "failed to update e-invoice", err)
+	}
+
+	uc.publishEvents(ctx, einvoice)
+	return nil
+}
+
+func (uc *einvoiceUseCase) publishEvents(ctx context.Context, einvoice *domain.EInvoice) {
+	events := einvoice.GetEvents()
+	einvoice.ClearEvents()
+	if uc.eventPublisher == nil {
+		return
+	}
+
+	for _, event := range events {
+		var payload map[string]interface{}
+		if data, err := json.Marshal(event); err == nil {
+			json.Unmarshal(data, &payload)
+		}
+
+		uc.eventPublisher.Publish(ctx, ports.Event{
+			ID:            event.EventID().String(),
+			Type:          event.EventType(),
+			AggregateID:   event.AggregateID().String(),
+			AggregateType: event.AggregateType(),
+			TenantID:      event.TenantID().String(),
+			Payload:       payload,
+			OccurredAt:    event.OccurredAt(),
+			Version:       event.Version(),
+		})
+	}
+}
+
+func mapEInvoiceAddressFromDTO(address dto.EInvoiceAddressDTO) domain.EInvoiceAddress {
+	return domain.EInvoiceAddress{
+		Line1:    address.Line1,
+		Line2:    address.Line2,
+		City:     address.City,
+		Postcode: address.Postcode,
+		State:    address.State,
+		Country:  address.Country,
+	}
+}
+
+func mapEInvoiceAddressToDTO(address domain.EInvoiceAddress) dto.EInvoiceAddressDTO {
+	return dto.EInvoiceAddressDTO{
+		Line1:    address.Line1,
+		Line2:    address.Line2,
+		City:     address.City,
+		Postcode: address.Postcode,
+		State:    address.State,
+		Country:  address.Country,
+	}
+}
+
+func mapEInvoiceAddressToPort(address domain.EInvoiceAddress) ports.EInvoiceAddress {
+	return ports.EInvoiceAddress{
+		Line1:    address.Line1,
+		Line2:    address.Line2,
+		City:     address.City,
+		Postcode: address.Postcode,
+		State:    address.State,
+		Country:  address.Country,
+	}
+}
+
+func mapEInvoicePartyFromDTO(party dto.EInvoicePartyDTO) domain.EInvoiceParty {
+	return domain.EInvoiceParty{
+		Name:               party.Name,
+		TIN:                party.TIN,
+		RegistrationNumber: party.RegistrationNumber,
+		SSTNumber:          party.SSTNumber,
+		Email:              party.Email,
+		Phone:              party.Phone,
+		Address:            mapEInvoiceAddressFromDTO(party.Address),
+	}
+}
+
+func mapEInvoiceSettingsToResponse(settings *domain.EInvoiceSettings) *dto.EInvoiceSettingsResponse {
+	resp := &dto.EInvoiceSettingsResponse{
+		Enabled:            settings.Enabled,
+		Name:               settings.Name,
+		TIN:                settings.TIN,
+		RegistrationNumber: settings.RegistrationNumber,
+		SSTNumber:          settings.SSTNumber,
+		MSICCode:           settings.MSICCode,
+		BusinessActivity:   settings.BusinessActivity,
+		Email:              settings.Email,
+		Phone:              settings.Phone,
+		Address:            mapEInvoiceAddressToDTO(settings.Address),
+	}
+	if !settings.UpdatedAt.IsZero() {
+		updatedAt := settings.UpdatedAt
+		resp.UpdatedAt = &updatedAt
+	}
+	return resp
+}
+
+func mapEInvoiceToResponse(einvoice *domain.EInvoice) *dto.EInvoiceResponse {
+	resp := &dto.EInvoiceResponse{
+		ID:            einvoice.ID.String(),
+		DealID:        einvoice.DealID.String(),
+		InvoiceID:     einvoice.InvoiceID.String(),
+		InvoiceNumber: einvoice.InvoiceNumber,
+		Buyer: dto.EInvoicePartyDTO{
+			Name:               einvoice.Buyer.Name,
+			TIN:                einvoice.Buyer.TIN,
+			RegistrationNumber: einvoice.Buyer.RegistrationNumber,
+			SSTNumber:          einvoice.Buyer.SSTNumber,
+			Email:              einvoice.Buyer.Email,
+			Phone:              einvoice.Buyer.Phone,
+			Address:            mapEInvoiceAddressToDTO(einvoice.Buyer.Address),
+		},
+		Status:           string(einvoice.Status),
+		SubmissionUID:    einvoice.SubmissionUID,
+		DocumentUUID:     einvoice.DocumentUUID,
+		LongID:           einvoice.LongID,
+		ValidationURL:    einvoice.ValidationURL,
+		ValidationErrors: einvoice.ValidationErrors,
+		Attempts:         einvoice.Attempts,
+		NextAttemptAt:    einvoice.NextAttemptAt,
+		LastError:        einvoice.LastError,
+		SubmittedAt:      einvoice.SubmittedAt,
+		ValidatedAt:      einvoice.ValidatedAt,
+		CancelledAt:      einvoice.CancelledAt,
+		CancelReason:     einvoice.CancelReason,
+		CreatedBy:        einvoice.CreatedBy.String(),
+		CreatedAt:        einvoice.CreatedAt,
+		UpdatedAt:        einvoice.UpdatedAt,
+		Version:          einvoice.Version,
+	}
+	if resp.ValidationErrors == nil {
+		resp.ValidationErrors = []string{}
+	}
+	if einvoice.Status == domain.EInvoiceStatusValid && einvoice.ValidatedAt != nil {
+		until := einvoice.ValidatedAt.Add(domain.EInvoiceCancellationWindow)
+		resp.CancellableUntil = &until
+	}
+	return resp
+}
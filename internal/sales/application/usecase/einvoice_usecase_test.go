@@ -0,0 +1,368 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/kilang-desa-murni/crm/internal/sales/application"
+	"github.com/kilang-desa-murni/crm/internal/sales/application/dto"
+	"github.com/kilang-desa-murni/crm/internal/sales/application/ports"
+	"github.com/kilang-desa-murni/crm/internal/sales/domain"
+)
+
+// ============================================================================
+// Mock Implementations for E-Invoice Tests
+// ============================================================================
+
+// MockEInvoiceRepository is a mock implementation of domain.EInvoiceRepository.
+type MockEInvoiceRepository struct {
+	einvoices map[uuid.UUID]*domain.EInvoice
+}
+
+func NewMockEInvoiceRepository() *MockEInvoiceRepository {
+	return &MockEInvoiceRepository{einvoices: make(map[uuid.UUID]*domain.EInvoice)}
+}
+
+func (m *MockEInvoiceRepository) Create(ctx context.Context, einvoice *domain.EInvoice) error {
+	m.einvoices[einvoice.ID] = einvoice
+	return nil
+}
+
+func (m *MockEInvoiceRepository) GetByID(ctx context.Context, tenantID, einvoiceID uuid.UUID) (*domain.EInvoice, error) {
+	einvoice, ok := m.einvoices[einvoiceID]
+	if !ok || einvoice.TenantID != tenantID {
+		return nil, domain.ErrEInvoiceNotFound
+	}
+	return einvoice, nil
+}
+
+func (m *MockEInvoiceRepository) Update(ctx context.Context, einvoice *domain.EInvoice) error {
+	if _, ok := m.einvoices[einvoice.ID]; !ok {
+		return domain.ErrEInvoiceNotFound
+	}
+	m.einvoices[einvoice.ID] = einvoice
+	return nil
+}
+
+func (m *MockEInvoiceRepository) GetByInvoice(ctx context.Context, tenantID, invoiceID uuid.UUID) (*domain.EInvoice, error) {
+	var latest *domain.EInvoice
+	for _, einvoice := range m.einvoices {
+		if einvoice.TenantID != tenantID || einvoice.InvoiceID != invoiceID {
+			continue
+		}
+		if latest == nil || einvoice.CreatedAt.After(latest.CreatedAt) {
+			latest = einvoice
+		}
+	}
+	return latest, nil
+}
+
+func (m *MockEInvoiceRepository) GetDue(ctx context.Context, dueBy time.Time, limit int) ([]*domain.EInvoice, error) {
+	einvoices := make([]*domain.EInvoice, 0)
+	for _, einvoice := range m.einvoices {
+		if einvoice.Status != domain.EInvoiceStatusPending && einvoice.Status != domain.EInvoiceStatusSubmitted {
+			continue
+		}
+		if einvoice.NextAttemptAt != nil && !einvoice.NextAttemptAt.After(dueBy) {
+			einvoices = append(einvoices, einvoice)
+		}
+	}
+	sort.Slice(einvoices, func(i, j int) bool {
+		return einvoices[i].NextAttemptAt.Before(*einvoices[j].NextAttemptAt)
+	})
+	if len(einvoices) > limit {
+		einvoices = einvoices[:limit]
+	}
+	return einvoices, nil
+}
+
+// MockEInvoiceSettingsRepository is a mock implementation of domain.EInvoiceSettingsRepository.
+type MockEInvoiceSettingsRepository struct {
+	settings map[uuid.UUID]*domain.EInvoiceSettings
+}
+
+func NewMockEInvoiceSettingsRepository() *MockEInvoiceSettingsRepository {
+	return &MockEInvoiceSettingsRepository{settings: make(map[uuid.UUID]*domain.EInvoiceSettings)}
+}
+
+func (m *MockEInvoiceSettingsRepository) Get(ctx context.Context, tenantID uuid.UUID) (*domain.EInvoiceSettings, error) {
+	return m.settings[tenantID], nil
+}
+
+func (m *MockEInvoiceSettingsRepository) Upsert(ctx context.Context, settings *domain.EInvoiceSettings) error {
+	m.settings[settings.TenantID] = settings
+	return nil
+}
+
+// MockEInvoiceService is a mock implementation of ports.EInvoiceService.
+type MockEInvoiceService struct {
+	submitted []*ports.EInvoiceDocument
+	cancelled []string
+	submitErr error
+	rejection []string
+	result    *ports.EInvoiceDocumentResult
+}
+
+func (m *MockEInvoiceService) Submit(ctx context.Context, doc *ports.EInvoiceDocument) (*ports.EInvoiceSubmission, error) {
+	m.submitted = append(m.submitted, doc)
+	if m.submitErr != nil {
+		return nil, m.submitErr
+	}
+	if len(m.rejection) > 0 {
+		return &ports.EInvoiceSubmission{SubmissionUID: "SUB1", Errors: m.rejection}, nil
+	}
+	return &ports.EInvoiceSubmission{SubmissionUID: "SUB1", DocumentUUID: "DOC1"}, nil
+}
+
+func (m *MockEInvoiceService) GetDocument(ctx context.Context, supplierTIN, documentUUID string) (*ports.EInvoiceDocumentResult, error) {
+	if m.result == nil {
+		return &ports.EInvoiceDocumentResult{Status: "submitted"}, nil
+	}
+	return m.result, nil
+}
+
+func (m *MockEInvoiceService) Cancel(ctx context.Context, supplierTIN, documentUUID, reason string) error {
+	m.cancelled = append(m.cancelled, documentUUID)
+	return nil
+}
+
+// ============================================================================
+// Helper Functions
+// ============================================================================
+
+func createEInvoiceTestSettings(repo *MockEInvoiceSettingsRepository, tenantID uuid.UUID) {
+	settings := domain.DefaultEInvoiceSettings(tenantID)
+	settings.Enabled = true
+	settings.Name = "Kilang Desa Murni Batik"
+	settings.TIN = "C98765432100"
+	settings.RegistrationNumber = "201501000001"
+	settings.MSICCode = "13921"
+	settings.BusinessActivity = "Batik manufacturing"
+	settings.Phone = "+6097654321"
+	settings.Address = domain.EInvoiceAddress{Line1: "Jalan Pantai", City: "Kota Bharu", State: "03", Country: "MYS"}
+	repo.settings[tenantID] = settings
+}
+
+func createEInvoiceTestDeal(tenantID uuid.UUID) (*domain.Deal, *domain.Invoice) {
+	deal := createOrderTestDeal(tenantID)
+	deal.Invoices = append(deal.Invoices, domain.Invoice{
+		ID:            uuid.New(),
+		InvoiceNumber: "INV-2026-0001",
+		Amount:        domain.Money{Amount: 108000, Currency: "MYR"},
+		Subtotal:      domain.Money{Amount: 100000, Currency: "MYR"},
+		TaxAmount:     domain.Money{Amount: 8000, Currency: "MYR"},
+		TaxLines: []domain.TaxLine{{
+			Code:          "SST8",
+			Name:          "Service Tax",
+			Rate:          8,
+			TaxableAmount: domain.Money{Amount: 100000, Currency: "MYR"},
+			TaxAmount:     domain.Money{Amount: 8000, Currency: "MYR"},
+		}},
+		Status: "sent",
+	})
+	return deal, &deal.Invoices[len(deal.Invoices)-1]
+}
+
+// ============================================================================
+// EInvoiceUseCase Tests
+// ============================================================================
+
+func TestEInvoiceUseCase_Submit(t *testing.T) {
+	ctx := context.Background()
+	tenantID := uuid.New()
+	dealRepo := NewDealMockDealRepository()
+	einvoiceRepo := NewMockEInvoiceRepository()
+	settingsRepo := NewMockEInvoiceSettingsRepository()
+	eventPublisher := NewDealMockEventPublisher()
+	service := &MockEInvoiceService{}
+	uc := NewEInvoiceUseCase(einvoiceRepo, settingsRepo, dealRepo, nil, eventPublisher, service)
+
+	deal, invoice := createEInvoiceTestDeal(tenantID)
+	dealRepo.deals[deal.ID] = deal
+
+	// E-invoicing has to be enabled for the tenant first
+	_, err := uc.Submit(ctx, tenantID, deal.ID, invoice.ID, uuid.New(), &dto.SubmitEInvoiceRequest{})
+	if appErr := application.GetAppError(err); appErr == nil || appErr.Code != application.ErrCodeEInvoiceNotEnabled {
+		t.Fatalf("Submit() before enabling error = %v, want %s", err, application.ErrCodeEInvoiceNotEnabled)
+	}
+	createEInvoiceTestSettings(settingsRepo, tenantID)
+
+	einvoice, err := uc.Submit(ctx, tenantID, deal.ID, invoice.ID, uuid.New(), &dto.SubmitEInvoiceRequest{})
+	if err != nil {
+		t.Fatalf("Submit() error = %v", err)
+	}
+	if einvoice.Status != string(domain.EInvoiceStatusSubmitted) || einvoice.DocumentUUID != "DOC1" {
+		t.Errorf("Status, DocumentUUID = %s, %q, want submitted DOC1", einvoice.Status, einvoice.DocumentUUID)
+	}
+	if einvoice.Buyer.TIN != domain.EInvoiceGeneralPublicTIN {
+		t.Errorf("Buyer.TIN = %q, want the general public TIN", einvoice.Buyer.TIN)
+	}
+
+	if len(service.submitted) != 1 {
+		t.Fatalf("submitted %d documents, want 1", len(service.submitted))
+	}
+	doc := service.submitted[0]
+	if doc.Supplier.TIN != "C98765432100" || doc.Total != 108000 || doc.Subtotal != 100000 {
+		t.Errorf("document = %+v, want the supplier and invoice totals", doc)
+	}
+	if len(doc.Taxes) != 1 || doc.Taxes[0].TaxAmount != 8000 || doc.Taxes[0].Rate != 8 {
+		t.Errorf("Taxes = %+v, want the invoice's tax line", doc.Taxes)
+	}
+
+	_, err = uc.Submit(ctx, tenantID, deal.ID, invoice.ID, uuid.New(), &dto.SubmitEInvoiceRequest{})
+	if appErr := application.GetAppError(err); appErr == nil || appErr.Code != application.ErrCodeEInvoiceAlreadyExists {
+		t.Errorf("Submit() again error = %v, want %s", err, application.ErrCodeEInvoiceAlreadyExists)
+	}
+}
+
+func TestEInvoiceUseCase_Submit_Rejected(t *testing.T) {
+	ctx := context.Background()
+	tenantID := uuid.New()
+	dealRepo := NewDealMockDealRepository()
+	settingsRepo := NewMockEInvoiceSettingsRepository()
+	eventPublisher := NewDealMockEventPublisher()
+	service := &MockEInvoiceService{rejection: []string{"Buyer TIN is not registered"}}
+	uc := NewEInvoiceUseCase(NewMockEInvoiceRepository(), settingsRepo, dealRepo, nil, eventPublisher, service)
+
+	createEInvoiceTestSettings(settingsRepo, tenantID)
+	deal, invoice := createEInvoiceTestDeal(tenantID)
+	dealRepo.deals[deal.ID] = deal
+
+	einvoice, err := uc.Submit(ctx, tenantID, deal.ID, invoice.ID, uuid.New(), &dto.SubmitEInvoiceRequest{})
+	if err != nil {
+		t.Fatalf("Submit() error = %v", err)
+	}
+	if einvoice.Status != string(domain.EInvoiceStatusInvalid) || len(einvoice.ValidationErrors) != 1 {
+		t.Errorf("Status, ValidationErrors = %s, %v, want invalid with the rejection", einvoice.Status, einvoice.ValidationErrors)
+	}
+
+	types := make([]string, 0, len(eventPublisher.events))
+	for _, event := range eventPublisher.events {
+		types = append(types, event.Type)
+	}
+	if !containsEventType(types, "einvoice.rejected") {
+		t.Errorf("published %v, want einvoice.rejected", types)
+	}
+
+	// Resubmitting with a corrected buyer submits again
+	service.rejection = nil
+	einvoice, err = uc.Resubmit(ctx, tenantID, deal.ID, invoice.ID, &dto.ResubmitEInvoiceRequest{
+		Buyer: &dto.EInvoicePartyDTO{
+			Name:               "Syarikat Batik Sdn Bhd",
+			TIN:                "C12345678900",
+			RegistrationNumber: "201901000005",
+			Phone:              "+60123456789",
+			Address:            dto.EInvoiceAddressDTO{Line1: "Lot 5", City: "Kota Bharu", State: "03", Country: "MYS"},
+		},
+	})
+	if err != nil {
+		t.Fatalf("Resubmit() error = %v", err)
+	}
+	if einvoice.Status != string(domain.EInvoiceStatusSubmitted) || einvoice.Buyer.TIN != "C12345678900" {
+		t.Errorf("Status, Buyer.TIN = %s, %q, want submitted for the corrected buyer", einvoice.Status, einvoice.Buyer.TIN)
+	}
+}
+
+func TestEInvoiceUseCase_ProcessDue(t *testing.T) {
+	ctx := context.Background()
+	tenantID := uuid.New()
+	dealRepo := NewDealMockDealRepository()
+	einvoiceRepo := NewMockEInvoiceRepository()
+	settingsRepo := NewMockEInvoiceSettingsRepository()
+	service := &MockEInvoiceService{submitErr: errors.New("connection refused")}
+	uc := NewEInvoiceUseCase(einvoiceRepo, settingsRepo, dealRepo, nil, NewDealMockEventPublisher(), service)
+
+	createEInvoiceTestSettings(settingsRepo, tenantID)
+	deal, invoice := createEInvoiceTestDeal(tenantID)
+	dealRepo.deals[deal.ID] = deal
+
+	// MyInvois cannot be reached, so the submission is retried later
+	einvoice, err := uc.Submit(ctx, tenantID, deal.ID, invoice.ID, uuid.New(), &dto.SubmitEInvoiceRequest{})
+	if err != nil {
+		t.Fatalf("Submit() error = %v", err)
+	}
+	if einvoice.Status != string(domain.EInvoiceStatusPending) || einvoice.Attempts != 1 || einvoice.NextAttemptAt == nil {
+		t.Fatalf("Status, Attempts, NextAttemptAt = %s, %d, %v, want a pending retry", einvoice.Status, einvoice.Attempts, einvoice.NextAttemptAt)
+	}
+
+	if updated, err := uc.ProcessDue(ctx, time.Now().UTC()); err != nil || updated != 0 {
+		t.Errorf("ProcessDue() before the retry = %d, %v, want 0, nil", updated, err)
+	}
+
+	service.submitErr = nil
+	now := einvoice.NextAttemptAt.Add(time.Second)
+	if updated, err := uc.ProcessDue(ctx, now); err != nil || updated != 1 {
+		t.Fatalf("ProcessDue() retry = %d, %v, want 1, nil", updated, err)
+	}
+
+	service.result = &ports.EInvoiceDocumentResult{
+		Status:        "valid",
+		LongID:        "LONG1",
+		ValidationURL: "https://myinvois.example/DOC1/share/LONG1",
+	}
+	if updated, err := uc.ProcessDue(ctx, now.Add(domain.EInvoiceValidationInterval)); err != nil || updated != 1 {
+		t.Fatalf("ProcessDue() validation = %d, %v, want 1, nil", updated, err)
+	}
+
+	result, err := uc.Get(ctx, tenantID, deal.ID, invoice.ID)
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if result.Status != string(domain.EInvoiceStatusValid) || result.ValidationURL == "" || result.CancellableUntil == nil {
+		t.Errorf("e-invoice = %+v, want valid with its validation link", result)
+	}
+}
+
+func TestEInvoiceUseCase_Cancel(t *testing.T) {
+	ctx := context.Background()
+	tenantID := uuid.New()
+	dealRepo := NewDealMockDealRepository()
+	einvoiceRepo := NewMockEInvoiceRepository()
+	settingsRepo := NewMockEInvoiceSettingsRepository()
+	service := &MockEInvoiceService{}
+	uc := NewEInvoiceUseCase(einvoiceRepo, settingsRepo, dealRepo, nil, NewDealMockEventPublisher(), service)
+
+	createEInvoiceTestSettings(settingsRepo, tenantID)
+	deal, invoice := createEInvoiceTestDeal(tenantID)
+	dealRepo.deals[deal.ID] = deal
+
+	if _, err := uc.Submit(ctx, tenantID, deal.ID, invoice.ID, uuid.New(), &dto.SubmitEInvoiceRequest{}); err != nil {
+		t.Fatalf("Submit() error = %v", err)
+	}
+
+	// Only validated e-invoices can be cancelled
+	_, err := uc.Cancel(ctx, tenantID, deal.ID, invoice.ID, &dto.CancelEInvoiceRequest{Reason: "Wrong buyer"})
+	if appErr := application.GetAppError(err); appErr == nil || appErr.Code != application.ErrCodeEInvoiceInvalidTransition {
+		t.Errorf("Cancel() of submitted e-invoice error = %v, want %s", err, application.ErrCodeEInvoiceInvalidTransition)
+	}
+
+	einvoice, _ := einvoiceRepo.GetByInvoice(ctx, tenantID, invoice.ID)
+	_ = einvoice.MarkValid("LONG1", "", time.Now().UTC())
+
+	result, err := uc.Cancel(ctx, tenantID, deal.ID, invoice.ID, &dto.CancelEInvoiceRequest{Reason: "Wrong buyer"})
+	if err != nil {
+		t.Fatalf("Cancel() error = %v", err)
+	}
+	if result.Status != string(domain.EInvoiceStatusCancelled) || len(service.cancelled) != 1 || service.cancelled[0] != "DOC1" {
+		t.Errorf("Status = %s, cancelled = %v, want DOC1 cancelled", result.Status, service.cancelled)
+	}
+
+	// A cancelled e-invoice leaves the invoice free to be submitted again
+	if _, err := uc.Submit(ctx, tenantID, deal.ID, invoice.ID, uuid.New(), &dto.SubmitEInvoiceRequest{}); err != nil {
+		t.Errorf("Submit() after cancellation error = %v", err)
+	}
+}
+
+func TestEInvoiceUseCase_NotFound(t *testing.T) {
+	uc := NewEInvoiceUseCase(NewMockEInvoiceRepository(), NewMockEInvoiceSettingsRepository(), NewDealMockDealRepository(), nil, nil, &MockEInvoiceService{})
+
+	_, err := uc.Get(context.Background(), uuid.New(), uuid.New(), uuid.New())
+	if !application.IsNotFoundError(err) {
+		t.Errorf("Get() error = %v, want not found", err)
+	}
+}
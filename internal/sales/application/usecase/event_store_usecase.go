@@ -0,0 +1,221 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/kilang-desa-murni/crm/internal/sales/application"
+	"github.com/kilang-desa-murni/crm/internal/sales/application/dto"
+	"github.com/kilang-desa-murni/crm/internal/sales/domain"
+)
+
+// ============================================================================
+// Event Store Use Case Interface
+// ============================================================================
+
+// EventReplayHandler handles one replayed event. Returning an error stops the
+// replay.
+type EventReplayHandler func(ctx context.Context, record *domain.EventRecord) error
+
+// EventStoreUseCase defines the interface for reading and replaying the event store.
+type EventStoreUseCase interface {
+	// ListEvents reads a page of a tenant's recorded events, oldest first.
+	ListEvents(ctx context.Context, tenantID uuid.UUID, req *dto.ListEventsRequest) (*dto.EventListResponse, error)
+
+	// Replay hands every matching event to handle in store order. When handle
+	// fails, the response still reports the last position handled so the
+	// replay can resume after it.
+	Replay(ctx context.Context, req *dto.ReplayEventsRequest, handle EventReplayHandler) (*dto.ReplayEventsResponse, error)
+}
+
+// ============================================================================
+// Event Store Use Case Implementation
+// ============================================================================
+
+const (
+	defaultEventPageSize   = 100
+	maxEventPageSize       = 500
+	defaultReplayBatchSize = 500
+)
+
+// eventStoreUseCase implements EventStoreUseCase.
+type eventStoreUseCase struct {
+	eventStore domain.EventStore
+}
+
+// NewEventStoreUseCase creates a new event store use case.
+func NewEventStoreUseCase(eventStore domain.EventStore) EventStoreUseCase {
+	return &eventStoreUseCase{eventStore: eventStore}
+}
+
+// ListEvents reads a page of a tenant's recorded events, oldest first.
+func (uc *eventStoreUseCase) ListEvents(ctx context.Context, tenantID uuid.UUID, req *dto.ListEventsRequest) (*dto.EventListResponse, error) {
+	filter := domain.EventRecordFilter{
+		TenantID:      &tenantID,
+		AfterPosition: req.After,
+		Limit:         req.Limit,
+	}
+	if filter.Limit < 1 {
+		filter.Limit = defaultEventPageSize
+	}
+	if filter.Limit > maxEventPageSize {
+		filter.Limit = maxEventPageSize
+	}
+	if req.EventType != "" {
+		filter.EventTypes = []string{req.EventType}
+	}
+	if err := applyEventFilter(&filter, req.AggregateID, req.AggregateType, req.From, req.To); err != nil {
+		return nil, err
+	}
+
+	records, err := uc.eventStore.ReadEvents(ctx, filter)
+	if err != nil {
+		return nil, application.WrapError(application.ErrCodeInternal, "failed to read events", err)
+	}
+
+	resp := &dto.EventListResponse{Events: make([]*dto.EventRecordResponse, len(records))}
+	for i, record := range records {
+		resp.Events[i] = mapEventRecordToResponse(record)
+	}
+	if len(records) == filter.Limit {
+		next := records[len(records)-1].Position
+		resp.NextAfter = &next
+	}
+
+	return resp, nil
+}
+
+// Replay hands every matching event to handle in store order, reading the
+// store in batches so replays of any size run in constant memory.
+func (uc *eventStoreUseCase) Replay(ctx context.Context, req *dto.ReplayEventsRequest, handle EventReplayHandler) (*dto.ReplayEventsResponse, error) {
+	filter := domain.EventRecordFilter{
+		EventTypes:    req.EventTypes,
+		AfterPosition: req.AfterPosition,
+		Limit:         req.BatchSize,
+	}
+	if filter.Limit < 1 {
+		filter.Limit = defaultReplayBatchSize
+	}
+	if req.TenantID != "" {
+		tenantID, err := uuid.Parse(req.TenantID)
+		if err != nil {
+			return nil, application.ErrValidationWithDetails("invalid tenant_id", map[string]interface{}{
+				"tenant_id": req.TenantID,
+			})
+		}
+		filter.TenantID = &tenantID
+	}
+	if err := applyEventFilter(&filter, req.AggregateID, req.AggregateType, req.From, req.To); err != nil {
+		return nil, err
+	}
+
+	resp := &dto.ReplayEventsResponse{
+		LastPosition: req.AfterPosition,
+		StartedAt:    time.Now().UTC(),
+	}
+	for {
+		if err := ctx.Err(); err != nil {
+			return resp, application.WrapError(application.ErrCodeInternal, "event replay cancelled", err)
+		}
+
+		records, err := uc.eventStore.ReadEvents(ctx, filter)
+		if err != nil {
+			return resp, application.WrapError(application.ErrCodeInternal, "failed to read events", err)
+		}
+
+		for _, record := range records {
+			if err := handle(ctx, record); err != nil {
+				return resp, application.WrapError(application.ErrCodeInternal, "failed to replay event "+record.ID.String(), err)
+			}
+			resp.Events++
+			resp.LastPosition = record.Position
+		}
+
+		if len(records) < filter.Limit {
+			break
+		}
+		filter.AfterPosition = resp.LastPosition
+	}
+	resp.CompletedAt = time.Now().UTC()
+
+	return resp, nil
+}
+
+// ============================================================================
+// Helpers
+// ============================================================================
+
+// applyEventFilter validates and applies the filters shared by listing and replay.
+func applyEventFilter(filter *domain.EventRecordFilter, aggregateID, aggregateType, from, to string) error {
+	if aggregateID != "" {
+		id, err := uuid.Parse(aggregateID)
+		if err != nil {
+			return application.ErrValidationWithDetails("invalid aggregate_id", map[string]interface{}{
+				"aggregate_id": aggregateID,
+			})
+		}
+		filter.AggregateID = &id
+	}
+	if aggregateType != "" {
+		filter.AggregateType = &aggregateType
+	}
+
+	if from != "" {
+		fromTime, err := parseEventTime(from, false)
+		if err != nil {
+			return application.ErrValidationWithDetails("invalid from time", map[string]interface{}{
+				"from": from,
+			})
+		}
+		filter.OccurredFrom = &fromTime
+	}
+	if to != "" {
+		toTime, err := parseEventTime(to, true)
+		if err != nil {
+			return application.ErrValidationWithDetails("invalid to time", map[string]interface{}{
+				"to": to,
+			})
+		}
+		filter.OccurredTo = &toTime
+	}
+	if filter.OccurredFrom != nil && filter.OccurredTo != nil && !filter.OccurredFrom.Before(*filter.OccurredTo) {
+		return application.ErrValidation("from must be before to")
+	}
+
+	return nil
+}
+
+// parseEventTime parses an RFC 3339 time or a date. A date used as an upper
+// bound covers the whole day.
+func parseEventTime(value string, upper bool) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t.UTC(), nil
+	}
+
+	day, err := time.Parse("2006-01-02", value)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if upper {
+		day = day.AddDate(0, 0, 1)
+	}
+	return day, nil
+}
+
+func mapEventRecordToResponse(record *domain.EventRecord) *dto.EventRecordResponse {
+	return &dto.EventRecordResponse{
+		Position:      record.Position,
+		Sequence:      record.Sequence,
+		ID:            record.ID.String(),
+		AggregateID:   record.AggregateID.String(),
+		AggregateType: record.AggregateType,
+		EventType:     record.EventType,
+		Version:       record.Version,
+		Data:          record.Data,
+		Metadata:      record.Metadata,
+		OccurredAt:    record.OccurredAt,
+		RecordedAt:    record.RecordedAt,
+	}
+}
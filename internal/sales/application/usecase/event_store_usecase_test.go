@@ -0,0 +1,207 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/kilang-desa-murni/crm/internal/sales/application"
+	"github.com/kilang-desa-murni/crm/internal/sales/application/dto"
+	"github.com/kilang-desa-murni/crm/internal/sales/domain"
+)
+
+// ============================================================================
+// Mock Implementations for Event Store Tests
+// ============================================================================
+
+// MockEventStore is a mock implementation of domain.EventStore.
+type MockEventStore struct {
+	records []*domain.EventRecord
+	reads   int
+}
+
+func (m *MockEventStore) Save(ctx context.Context, events ...domain.DomainEvent) error {
+	return nil
+}
+
+func (m *MockEventStore) GetByAggregateID(ctx context.Context, tenantID, aggregateID uuid.UUID) ([]domain.DomainEvent, error) {
+	return nil, nil
+}
+
+func (m *MockEventStore) GetByAggregateType(ctx context.Context, tenantID uuid.UUID, aggregateType string, opts domain.ListOptions) ([]domain.DomainEvent, error) {
+	return nil, nil
+}
+
+func (m *MockEventStore) GetByEventType(ctx context.Context, tenantID uuid.UUID, eventType string, opts domain.ListOptions) ([]domain.DomainEvent, error) {
+	return nil, nil
+}
+
+func (m *MockEventStore) GetEventsSince(ctx context.Context, tenantID uuid.UUID, since time.Time, opts domain.ListOptions) ([]domain.DomainEvent, error) {
+	return nil, nil
+}
+
+func (m *MockEventStore) GetEventsInRange(ctx context.Context, tenantID uuid.UUID, start, end time.Time, opts domain.ListOptions) ([]domain.DomainEvent, error) {
+	return nil, nil
+}
+
+func (m *MockEventStore) ReadEvents(ctx context.Context, filter domain.EventRecordFilter) ([]*domain.EventRecord, error) {
+	m.reads++
+	var result []*domain.EventRecord
+	for _, record := range m.records {
+		if record.Position <= filter.AfterPosition {
+			continue
+		}
+		if filter.TenantID != nil && record.TenantID != *filter.TenantID {
+			continue
+		}
+		if filter.AggregateID != nil && record.AggregateID != *filter.AggregateID {
+			continue
+		}
+		if filter.OccurredFrom != nil && record.OccurredAt.Before(*filter.OccurredFrom) {
+			continue
+		}
+		if filter.OccurredTo != nil && !record.OccurredAt.Before(*filter.OccurredTo) {
+			continue
+		}
+		result = append(result, record)
+		if len(result) == filter.Limit {
+			break
+		}
+	}
+	return result, nil
+}
+
+func newMockEventStore(tenantID, aggregateID uuid.UUID, count int) *MockEventStore {
+	store := &MockEventStore{}
+	start := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+	for i := 1; i <= count; i++ {
+		store.records = append(store.records, &domain.EventRecord{
+			Position:      int64(i),
+			Sequence:      int64(i),
+			ID:            uuid.New(),
+			TenantID:      tenantID,
+			AggregateID:   aggregateID,
+			AggregateType: "opportunity",
+			EventType:     "opportunity.updated",
+			Data:          []byte(`{}`),
+			OccurredAt:    start.Add(time.Duration(i) * time.Hour),
+		})
+	}
+	return store
+}
+
+// ============================================================================
+// Tests
+// ============================================================================
+
+func TestEventStoreUseCase_ListEvents(t *testing.T) {
+	tenantID := uuid.New()
+	aggregateID := uuid.New()
+	store := newMockEventStore(tenantID, aggregateID, 5)
+	store.records = append(store.records, &domain.EventRecord{Position: 6, TenantID: uuid.New(), AggregateID: aggregateID})
+	uc := NewEventStoreUseCase(store)
+
+	resp, err := uc.ListEvents(context.Background(), tenantID, &dto.ListEventsRequest{
+		AggregateID: aggregateID.String(),
+		Limit:       3,
+	})
+	if err != nil {
+		t.Fatalf("ListEvents() error = %v", err)
+	}
+	if len(resp.Events) != 3 || resp.NextAfter == nil || *resp.NextAfter != 3 {
+		t.Fatalf("ListEvents() = %d events, next_after %v, want 3 events and next_after 3", len(resp.Events), resp.NextAfter)
+	}
+
+	resp, err = uc.ListEvents(context.Background(), tenantID, &dto.ListEventsRequest{
+		AggregateID: aggregateID.String(),
+		After:       *resp.NextAfter,
+		Limit:       3,
+	})
+	if err != nil {
+		t.Fatalf("ListEvents() next page error = %v", err)
+	}
+	// The other tenant's event is not visible
+	if len(resp.Events) != 2 || resp.NextAfter != nil {
+		t.Errorf("ListEvents() next page = %d events, next_after %v, want 2 events and no next_after", len(resp.Events), resp.NextAfter)
+	}
+}
+
+func TestEventStoreUseCase_ListEvents_Validation(t *testing.T) {
+	uc := NewEventStoreUseCase(&MockEventStore{})
+
+	tests := []struct {
+		name string
+		req  dto.ListEventsRequest
+	}{
+		{"invalid aggregate id", dto.ListEventsRequest{AggregateID: "not-a-uuid"}},
+		{"invalid from", dto.ListEventsRequest{From: "yesterday"}},
+		{"from after to", dto.ListEventsRequest{From: "2026-03-02", To: "2026-03-01"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := uc.ListEvents(context.Background(), uuid.New(), &tt.req)
+			appErr := application.GetAppError(err)
+			if appErr == nil || appErr.Code != application.ErrCodeValidation {
+				t.Errorf("ListEvents() error = %v, want validation error", err)
+			}
+		})
+	}
+}
+
+func TestEventStoreUseCase_Replay(t *testing.T) {
+	tenantID := uuid.New()
+	store := newMockEventStore(tenantID, uuid.New(), 7)
+	uc := NewEventStoreUseCase(store)
+
+	var positions []int64
+	resp, err := uc.Replay(context.Background(), &dto.ReplayEventsRequest{
+		From:          "2026-03-01T12:00:00Z",
+		AfterPosition: 1,
+		BatchSize:     2,
+	}, func(ctx context.Context, record *domain.EventRecord) error {
+		positions = append(positions, record.Position)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Replay() error = %v", err)
+	}
+
+	// Events before 12:00 (positions 1-2) are skipped, the rest are read in batches
+	want := []int64{3, 4, 5, 6, 7}
+	if len(positions) != len(want) {
+		t.Fatalf("Replay() handled positions %v, want %v", positions, want)
+	}
+	for i := range want {
+		if positions[i] != want[i] {
+			t.Fatalf("Replay() handled positions %v, want %v", positions, want)
+		}
+	}
+	if resp.Events != 5 || resp.LastPosition != 7 {
+		t.Errorf("Replay() = %d events, last position %d, want 5 and 7", resp.Events, resp.LastPosition)
+	}
+	if store.reads != 3 {
+		t.Errorf("ReadEvents called %d times, want 3", store.reads)
+	}
+}
+
+func TestEventStoreUseCase_Replay_HandlerError(t *testing.T) {
+	store := newMockEventStore(uuid.New(), uuid.New(), 5)
+	uc := NewEventStoreUseCase(store)
+
+	resp, err := uc.Replay(context.Background(), &dto.ReplayEventsRequest{}, func(ctx context.Context, record *domain.EventRecord) error {
+		if record.Position == 3 {
+			return errors.New("consumer unavailable")
+		}
+		return nil
+	})
+	if err == nil {
+		t.Fatal("Replay() error = nil, want handler error")
+	}
+	if resp == nil || resp.Events != 2 || resp.LastPosition != 2 {
+		t.Errorf("Replay() = %+v, want 2 events handled up to position 2", resp)
+	}
+}
@@ -0,0 +1,595 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/kilang-desa-murni/crm/internal/sales/application"
+	"github.com/kilang-desa-murni/crm/internal/sales/application/dto"
+	"github.com/kilang-desa-murni/crm/internal/sales/application/ports"
+	"github.com/kilang-desa-murni/crm/internal/sales/domain"
+)
+
+// ============================================================================
+// Exchange Rate Use Case Interface
+// ============================================================================
+
+// ExchangeRateUseCase defines the interface for exchange rate and currency operations.
+type ExchangeRateUseCase interface {
+	ports.CurrencyConverter
+
+	// Rates
+	SetRate(ctx context.Context, tenantID, userID uuid.UUID, req *dto.SetExchangeRateRequest) (*dto.ExchangeRateResponse, error)
+	ListRates(ctx context.Context, tenantID uuid.UUID, req *dto.ListExchangeRatesRequest) (*dto.ExchangeRateListResponse, error)
+	DeleteRate(ctx context.Context, tenantID, rateID uuid.UUID) error
+	Convert(ctx context.Context, tenantID uuid.UUID, req *dto.ConvertCurrencyRequest) (*dto.ConvertCurrencyResponse, error)
+
+	// Provider rates
+	RefreshRates(ctx context.Context, tenantID uuid.UUID) (*dto.RefreshExchangeRatesResponse, error)
+	RefreshAllTenants(ctx context.Context) (int, error)
+
+	// Settings
+	GetSettings(ctx context.Context, tenantID uuid.UUID) (*dto.CurrencySettingsResponse, error)
+	UpdateSettings(ctx context.Context, tenantID uuid.UUID, req *dto.UpdateCurrencySettingsRequest) (*dto.CurrencySettingsResponse, error)
+}
+
+// ============================================================================
+// Exchange Rate Use Case Implementation
+// ============================================================================
+
+// exchangeRateUseCase implements ExchangeRateUseCase.
+type exchangeRateUseCase struct {
+	rateRepo  domain.ExchangeRateRepository
+	providers map[domain.ExchangeRateSource]ports.ExchangeRateProvider
+}
+
+// NewExchangeRateUseCase creates a new exchange rate use case.
+func NewExchangeRateUseCase(
+	rateRepo domain.ExchangeRateRepository,
+	providers []ports.ExchangeRateProvider,
+) ExchangeRateUseCase {
+	providerBySource := make(map[domain.ExchangeRateSource]ports.ExchangeRateProvider, len(providers))
+	for _, provider := range providers {
+		providerBySource[domain.ExchangeRateSource(provider.Source())] = provider
+	}
+
+	return &exchangeRateUseCase{
+		rateRepo:  rateRepo,
+		providers: providerBySource,
+	}
+}
+
+// ============================================================================
+// Currency Converter
+// ============================================================================
+
+// BaseCurrency returns the tenant's base currency.
+func (uc *exchangeRateUseCase) BaseCurrency(ctx context.Context, tenantID uuid.UUID) (string, error) {
+	settings, err := uc.loadSettings(ctx, tenantID)
+	if err != nil {
+		return "", err
+	}
+	return settings.BaseCurrency, nil
+}
+
+// Rate returns the rate converting one unit of from into to at the given time.
+func (uc *exchangeRateUseCase) Rate(ctx context.Context, tenantID uuid.UUID, from, to string, at time.Time) (float64, error) {
+	from = strings.ToUpper(from)
+	to = strings.ToUpper(to)
+	if from == to {
+		return 1, nil
+	}
+
+	rates, err := uc.rateRepo.ListEffective(ctx, tenantID, at)
+	if err != nil {
+		return 0, application.WrapError(application.ErrCodeInternal, "failed to get exchange rates", err)
+	}
+
+	rate, ok := domain.NewExchangeRateTable(rates).Rate(from, to)
+	if !ok {
+		return 0, application.ErrExchangeRateNotFound(from, to)
+	}
+	return rate, nil
+}
+
+// ============================================================================
+// Rates
+// ============================================================================
+
+// SetRate sets a manual exchange rate, replacing any manual rate for the same pair and day.
+func (uc *exchangeRateUseCase) SetRate(ctx context.Context, tenantID, userID uuid.UUID, req *dto.SetExchangeRateRequest) (*dto.ExchangeRateResponse, error) {
+	effectiveDate := time.Now().UTC()
+	if req.EffectiveDate != "" {
+		parsed, err := time.Parse("2006-01-02", req.EffectiveDate)
+		if err != nil {
+			return nil, application.ErrValidation("invalid effective_date format")
+		}
+		effectiveDate = parsed
+	}
+
+	rate, err := domain.NewExchangeRate(tenantID, req.FromCurrency, req.ToCurrency, req.Rate, effectiveDate, domain.ExchangeRateSourceManual, &userID)
+	if err != nil {
+		return nil, mapExchangeRateError(err, req.FromCurrency, req.ToCurrency)
+	}
+
+	if err := uc.rateRepo.Upsert(ctx, rate); err != nil {
+		return nil, application.WrapError(application.ErrCodeInternal, "failed to save exchange rate", err)
+	}
+
+	return mapExchangeRateToResponse(rate), nil
+}
+
+// ListRates lists the tenant's exchange rates, newest first.
+func (uc *exchangeRateUseCase) ListRates(ctx context.Context, tenantID uuid.UUID, req *dto.ListExchangeRatesRequest) (*dto.ExchangeRateListResponse, error) {
+	filter := domain.ExchangeRateFilter{}
+	if req.FromCurrency != "" {
+		from := strings.ToUpper(req.FromCurrency)
+		filter.FromCurrency = &from
+	}
+	if req.ToCurrency != "" {
+		to := strings.ToUpper(req.ToCurrency)
+		filter.ToCurrency = &to
+	}
+	if req.Source != "" {
+		source := domain.ExchangeRateSource(req.Source)
+		if !source.IsValid() {
+			return nil, application.ErrValidationWithDetails("invalid source", map[string]interface{}{
+				"source": req.Source,
+			})
+		}
+		filter.Source = &source
+	}
+
+	page := req.Page
+	if page < 1 {
+		page = 1
+	}
+	pageSize := req.PageSize
+	if pageSize < 1 {
+		pageSize = 20
+	}
+
+	rates, total, err := uc.rateRepo.List(ctx, tenantID, filter, domain.ListOptions{
+		Page:     page,
+		PageSize: pageSize,
+	})
+	if err != nil {
+		return nil, application.WrapError(application.ErrCodeInternal, "failed to list exchange rates", err)
+	}
+
+	responses := make([]*dto.ExchangeRateResponse, len(rates))
+	for i, rate := range rates {
+		responses[i] = mapExchangeRateToResponse(rate)
+	}
+
+	return &dto.ExchangeRateListResponse{
+		Rates:      responses,
+		Pagination: dto.NewPaginationResponse(page, pageSize, total),
+	}, nil
+}
+
+// DeleteRate deletes an exchange rate.
+func (uc *exchangeRateUseCase) DeleteRate(ctx context.Context, tenantID, rateID uuid.UUID) error {
+	if err := uc.rateRepo.Delete(ctx, tenantID, rateID); err != nil {
+		if errors.Is(err, domain.ErrExchangeRateNotFound) {
+			return application.ErrNotFound("exchange rate", rateID)
+		}
+		return application.WrapError(application.ErrCodeInternal, "failed to delete exchange rate", err)
+	}
+	return nil
+}
+
+// Convert converts an amount between currencies at the rate effective on the given date.
+func (uc *exchangeRateUseCase) Convert(ctx context.Context, tenantID uuid.UUID, req *dto.ConvertCurrencyRequest) (*dto.ConvertCurrencyResponse, error) {
+	at := time.Now().UTC()
+	if req.Date != "" {
+		parsed, err := time.Parse("2006-01-02", req.Date)
+		if err != nil {
+			return nil, application.ErrValidation("invalid date format")
+		}
+		at = parsed
+	}
+
+	amount, err := domain.NewMoney(req.Amount, req.From)
+	if err != nil {
+		return nil, application.ErrCurrencyInvalid(req.From)
+	}
+
+	to := strings.ToUpper(req.To)
+	if to == "" {
+		if to, err = uc.BaseCurrency(ctx, tenantID); err != nil {
+			return nil, err
+		}
+	}
+	if !domain.IsSupportedCurrency(to) {
+		return nil, application.ErrCurrencyInvalid(to)
+	}
+
+	rate, err := uc.Rate(ctx, tenantID, amount.Currency, to, at)
+	if err != nil {
+		return nil, err
+	}
+
+	converted, err := amount.ConvertTo(to, rate)
+	if err != nil {
+		return nil, application.WrapError(application.ErrCodeValidation, "failed to convert amount", err)
+	}
+
+	return &dto.ConvertCurrencyResponse{
+		Amount:    moneyToDTO(amount),
+		Converted: moneyToDTO(converted),
+		Rate:      rate,
+		Date:      at.Format("2006-01-02"),
+	}, nil
+}
+
+// ============================================================================
+// Provider Rates
+// ============================================================================
+
+// RefreshRates fetches the latest rates from the tenant's configured provider.
+func (uc *exchangeRateUseCase) RefreshRates(ctx context.Context, tenantID uuid.UUID) (*dto.RefreshExchangeRatesResponse, error) {
+	settings, err := uc.loadSettings(ctx, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	if settings.RateProvider == nil {
+		return nil, application.ErrValidation("no exchange rate provider is configured")
+	}
+
+	return uc.refreshFromProvider(ctx, settings)
+}
+
+// RefreshAllTenants fetches provider rates for every tenant with a provider
+// configured and returns the number of tenants refreshed. Each provider is
+// called at most once per run.
+func (uc *exchangeRateUseCase) RefreshAllTenants(ctx context.Context) (int, error) {
+	settingsList, err := uc.rateRepo.ListSettingsWithProvider(ctx)
+	if err != nil {
+		return 0, application.WrapError(application.ErrCodeInternal, "failed to list currency settings", err)
+	}
+
+	quotesBySource := make(map[domain.ExchangeRateSource][]ports.ExchangeRateQuote)
+	refreshed := 0
+	var firstErr error
+
+	for _, settings := range settingsList {
+		if err := ctx.Err(); err != nil {
+			return refreshed, err
+		}
+
+		source := *settings.RateProvider
+		quotes, fetched := quotesBySource[source]
+		if !fetched {
+			provider, ok := uc.providers[source]
+			if !ok {
+				continue
+			}
+			if quotes, err = provider.FetchRates(ctx); err != nil {
+				if firstErr == nil {
+					firstErr = application.WrapError(application.ErrCodeServiceUnavailable, "failed to fetch exchange rates", err)
+				}
+				quotes = nil
+			}
+			quotesBySource[source] = quotes
+		}
+		if len(quotes) == 0 {
+			continue
+		}
+
+		if _, err := uc.storeQuotes(ctx, settings, quotes); err != nil {
+			if firstErr == nil {
+				firstErr = err
+			}
+			continue
+		}
+		refreshed++
+	}
+
+	return refreshed, firstErr
+}
+
+// refreshFromProvider fetches and stores the rates of the tenant's provider.
+func (uc *exchangeRateUseCase) refreshFromProvider(ctx context.Context, settings *domain.CurrencySettings) (*dto.RefreshExchangeRatesResponse, error) {
+	provider, ok := uc.providers[*settings.RateProvider]
+	if !ok {
+		return nil, application.ErrServiceUnavailable("exchange rate provider " + string(*settings.RateProvider))
+	}
+
+	quotes, err := provider.FetchRates(ctx)
+	if err != nil {
+		return nil, application.WrapError(application.ErrCodeServiceUnavailable, "failed to fetch exchange rates", err)
+	}
+
+	return uc.storeQuotes(ctx, settings, quotes)
+}
+
+// storeQuotes saves provider quotes as tenant rates and records the fetch time.
+func (uc *exchangeRateUseCase) storeQuotes(ctx context.Context, settings *domain.CurrencySettings, quotes []ports.ExchangeRateQuote) (*dto.RefreshExchangeRatesResponse, error) {
+	source := *settings.RateProvider
+	resp := &dto.RefreshExchangeRatesResponse{
+		Source:    string(source),
+		FetchedAt: time.Now().UTC(),
+	}
+
+	for _, quote := range quotes {
+		// Providers publish currencies the service does not support; skip those
+		rate, err := domain.NewExchangeRate(settings.TenantID, quote.From, quote.To, quote.Rate, quote.EffectiveDate, source, nil)
+		if err != nil {
+			continue
+		}
+		if err := uc.rateRepo.Upsert(ctx, rate); err != nil {
+			return nil, application.WrapError(application.ErrCodeInternal, "failed to save exchange rate", err)
+		}
+		resp.RatesUpdated++
+		resp.EffectiveDate = rate.EffectiveDate.Format("2006-01-02")
+	}
+
+	settings.LastFetchedAt = &resp.FetchedAt
+	settings.UpdatedAt = resp.FetchedAt
+	if err := uc.rateRepo.SaveSettings(ctx, settings); err != nil {
+		return nil, application.WrapError(application.ErrCodeInternal, "failed to save currency settings", err)
+	}
+
+	return resp, nil
+}
+
+// ============================================================================
+// Settings
+// ============================================================================
+
+// GetSettings returns the tenant's currency settings, or the defaults if none are configured.
+func (uc *exchangeRateUseCase) GetSettings(ctx context.Context, tenantID uuid.UUID) (*dto.CurrencySettingsResponse, error) {
+	settings, err := uc.loadSettings(ctx, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	return mapCurrencySettingsToResponse(settings), nil
+}
+
+// UpdateSettings updates the tenant's base currency and rate provider.
+func (uc *exchangeRateUseCase) UpdateSettings(ctx context.Context, tenantID uuid.UUID, req *dto.UpdateCurrencySettingsRequest) (*dto.CurrencySettingsResponse, error) {
+	settings, err := uc.loadSettings(ctx, tenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.BaseCurrency != nil {
+		currency := strings.ToUpper(*req.BaseCurrency)
+		if !domain.IsSupportedCurrency(currency) {
+			return nil, application.ErrCurrencyInvalid(*req.BaseCurrency)
+		}
+		settings.BaseCurrency = currency
+	}
+	if req.RateProvider != nil {
+		if *req.RateProvider == "none" {
+			settings.RateProvider = nil
+		} else {
+			source := domain.ExchangeRateSource(*req.RateProvider)
+			if !source.IsProvider() {
+				return nil, application.ErrValidationWithDetails("invalid rate_provider", map[string]interface{}{
+					"rate_provider": *req.RateProvider,
+				})
+			}
+			if _, ok := uc.providers[source]; !ok {
+				return nil, application.ErrServiceUnavailable("exchange rate provider " + string(source))
+			}
+			settings.RateProvider = &source
+		}
+	}
+
+	settings.UpdatedAt = time.Now().UTC()
+	if err := uc.rateRepo.SaveSettings(ctx, settings); err != nil {
+		return nil, application.WrapError(application.ErrCodeInternal, "failed to save currency settings", err)
+	}
+
+	return mapCurrencySettingsToResponse(settings), nil
+}
+
+// loadSettings returns the tenant's currency settings, falling back to the defaults.
+func (uc *exchangeRateUseCase) loadSettings(ctx context.Context, tenantID uuid.UUID) (*domain.CurrencySettings, error) {
+	settings, err := uc.rateRepo.GetSettings(ctx, tenantID)
+	if err != nil {
+		return nil, application.WrapError(application.ErrCodeInternal, "failed to get currency settings", err)
+	}
+	if settings == nil {
+		settings = domain.DefaultCurrencySettings(tenantID)
+	}
+	return settings, nil
+}
+
+// ============================================================================
+// Mapping Helpers
+// ============================================================================
+
+// mapExchangeRateError converts domain validation errors into application errors.
+func mapExchangeRateError(err error, from, to string) error {
+	switch {
+	case errors.Is(err, domain.ErrInvalidCurrency):
+		if !domain.IsSupportedCurrency(strings.ToUpper(from)) {
+			return application.ErrCurrencyInvalid(from)
+		}
+		return application.ErrCurrencyInvalid(to)
+	case errors.Is(err, domain.ErrSameCurrencyExchangeRate),
+		errors.Is(err, domain.ErrInvalidExchangeRate),
+		errors.Is(err, domain.ErrInvalidExchangeRateSource):
+		return application.ErrValidation(err.Error())
+	}
+	return application.WrapError(application.ErrCodeInternal, "failed to create exchange rate", err)
+}
+
+func mapExchangeRateToResponse(rate *domain.ExchangeRate) *dto.ExchangeRateResponse {
+	resp := &dto.ExchangeRateResponse{
+		ID:            rate.ID.String(),
+		FromCurrency:  rate.FromCurrency,
+		ToCurrency:    rate.ToCurrency,
+		Rate:          rate.Rate,
+		InverseRate:   1 / rate.Rate,
+		EffectiveDate: rate.EffectiveDate.Format("2006-01-02"),
+		Source:        string(rate.Source),
+		CreatedAt:     rate.CreatedAt,
+		UpdatedAt:     rate.UpdatedAt,
+	}
+	if rate.CreatedBy != nil {
+		createdBy := rate.CreatedBy.String()
+		resp.CreatedBy = &createdBy
+	}
+	return resp
+}
+
+func mapCurrencySettingsToResponse(settings *domain.CurrencySettings) *dto.CurrencySettingsResponse {
+	resp := &dto.CurrencySettingsResponse{
+		BaseCurrency:  settings.BaseCurrency,
+		LastFetchedAt: settings.LastFetchedAt,
+	}
+	if settings.RateProvider != nil {
+		provider := string(*settings.RateProvider)
+		resp.RateProvider = &provider
+	}
+	if !settings.UpdatedAt.IsZero() {
+		updatedAt := settings.UpdatedAt
+		resp.UpdatedAt = &updatedAt
+	}
+	return resp
+}
+
+func moneyToDTO(m domain.Money) dto.MoneyDTO {
+	return dto.MoneyDTO{
+		Amount:   m.Amount,
+		Currency: m.Currency,
+		Display:  m.Format(),
+	}
+}
+
+// ============================================================================
+// Currency Totals
+// ============================================================================
+
+// currencyTotals accumulates values held in several currencies into a single
+// reporting currency, keeping a per-currency breakdown. Values already stored
+// in the reporting currency are used as-is; other values are converted at the
+// current rate, and values without a rate are counted as unconverted.
+type currencyTotals struct {
+	ctx       context.Context
+	converter ports.CurrencyConverter
+	tenantID  uuid.UUID
+	currency  string
+	at        time.Time
+
+	rates      map[string]*float64
+	byCurrency map[string]*dto.CurrencyBreakdownDTO
+
+	total         int64
+	weightedTotal int64
+}
+
+func newCurrencyTotals(ctx context.Context, converter ports.CurrencyConverter, tenantID uuid.UUID, currency string, at time.Time) *currencyTotals {
+	return &currencyTotals{
+		ctx:        ctx,
+		converter:  converter,
+		tenantID:   tenantID,
+		currency:   currency,
+		at:         at,
+		rates:      make(map[string]*float64),
+		byCurrency: make(map[string]*dto.CurrencyBreakdownDTO),
+	}
+}
+
+// rate returns the rate from a currency into the reporting currency, looking
+// each currency up at most once.
+func (t *currencyTotals) rate(from string) (float64, bool) {
+	if from == t.currency {
+		return 1, true
+	}
+	if rate, ok := t.rates[from]; ok {
+		if rate == nil {
+			return 0, false
+		}
+		return *rate, true
+	}
+	if t.converter == nil {
+		t.rates[from] = nil
+		return 0, false
+	}
+	rate, err := t.converter.Rate(t.ctx, t.tenantID, from, t.currency, t.at)
+	if err != nil {
+		t.rates[from] = nil
+		return 0, false
+	}
+	t.rates[from] = &rate
+	return rate, true
+}
+
+// add records count opportunities worth amount and weighted in currency.
+// stored and storedWeighted are values already converted into the reporting
+// currency, or nil. It returns the converted values and whether they are known.
+func (t *currencyTotals) add(currency string, count, amount, weighted int64, stored, storedWeighted *domain.Money) (int64, int64, bool) {
+	entry, ok := t.byCurrency[currency]
+	if !ok {
+		entry = &dto.CurrencyBreakdownDTO{
+			Currency:                currency,
+			Amount:                  dto.MoneyDTO{Currency: currency},
+			WeightedAmount:          dto.MoneyDTO{Currency: currency},
+			ConvertedAmount:         dto.MoneyDTO{Currency: t.currency},
+			ConvertedWeightedAmount: dto.MoneyDTO{Currency: t.currency},
+		}
+		if rate, known := t.rate(currency); known && currency != t.currency {
+			entry.ExchangeRate = &rate
+		}
+		t.byCurrency[currency] = entry
+	}
+	entry.OpportunityCount += count
+	entry.Amount.Amount += amount
+	entry.WeightedAmount.Amount += weighted
+
+	var convAmount, convWeighted int64
+	switch {
+	case currency == t.currency:
+		convAmount, convWeighted = amount, weighted
+	case stored != nil && stored.Currency == t.currency:
+		convAmount = stored.Amount
+		if storedWeighted != nil {
+			convWeighted = storedWeighted.Amount
+		}
+	default:
+		rate, known := t.rate(currency)
+		if !known {
+			entry.UnconvertedCount += count
+			return 0, 0, false
+		}
+		a, errA := domain.Money{Amount: amount, Currency: currency}.ConvertTo(t.currency, rate)
+		w, errW := domain.Money{Amount: weighted, Currency: currency}.ConvertTo(t.currency, rate)
+		if errA != nil || errW != nil {
+			entry.UnconvertedCount += count
+			return 0, 0, false
+		}
+		convAmount, convWeighted = a.Amount, w.Amount
+	}
+
+	entry.ConvertedAmount.Amount += convAmount
+	entry.ConvertedWeightedAmount.Amount += convWeighted
+	t.total += convAmount
+	t.weightedTotal += convWeighted
+	return convAmount, convWeighted, true
+}
+
+// breakdown returns the per-currency breakdown ordered by currency code.
+func (t *currencyTotals) breakdown() []dto.CurrencyBreakdownDTO {
+	result := make([]dto.CurrencyBreakdownDTO, 0, len(t.byCurrency))
+	for _, entry := range t.byCurrency {
+		entry.Amount.Display = domain.Money{Amount: entry.Amount.Amount, Currency: entry.Currency}.Format()
+		entry.WeightedAmount.Display = domain.Money{Amount: entry.WeightedAmount.Amount, Currency: entry.Currency}.Format()
+		entry.ConvertedAmount.Display = domain.Money{Amount: entry.ConvertedAmount.Amount, Currency: t.currency}.Format()
+		entry.ConvertedWeightedAmount.Display = domain.Money{Amount: entry.ConvertedWeightedAmount.Amount, Currency: t.currency}.Format()
+		result = append(result, *entry)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Currency < result[j].Currency
+	})
+	return result
+}
@@ -0,0 +1,325 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/kilang-desa-murni/crm/internal/sales/application"
+	"github.com/kilang-desa-murni/crm/internal/sales/application/dto"
+	"github.com/kilang-desa-murni/crm/internal/sales/application/ports"
+	"github.com/kilang-desa-murni/crm/internal/sales/domain"
+)
+
+// ============================================================================
+// Mock Implementations for Exchange Rate Tests
+// ============================================================================
+
+// MockExchangeRateRepository is a mock implementation of domain.ExchangeRateRepository.
+type MockExchangeRateRepository struct {
+	rates    []*domain.ExchangeRate
+	settings map[uuid.UUID]*domain.CurrencySettings
+}
+
+func NewMockExchangeRateRepository() *MockExchangeRateRepository {
+	return &MockExchangeRateRepository{settings: make(map[uuid.UUID]*domain.CurrencySettings)}
+}
+
+func (m *MockExchangeRateRepository) Upsert(ctx context.Context, rate *domain.ExchangeRate) error {
+	for i, existing := range m.rates {
+		if existing.TenantID == rate.TenantID && existing.FromCurrency == rate.FromCurrency &&
+			existing.ToCurrency == rate.ToCurrency && existing.EffectiveDate.Equal(rate.EffectiveDate) &&
+			existing.Source == rate.Source {
+			rate.ID = existing.ID
+			m.rates[i] = rate
+			return nil
+		}
+	}
+	m.rates = append(m.rates, rate)
+	return nil
+}
+
+func (m *MockExchangeRateRepository) GetByID(ctx context.Context, tenantID, rateID uuid.UUID) (*domain.ExchangeRate, error) {
+	for _, rate := range m.rates {
+		if rate.TenantID == tenantID && rate.ID == rateID {
+			return rate, nil
+		}
+	}
+	return nil, domain.ErrExchangeRateNotFound
+}
+
+func (m *MockExchangeRateRepository) Delete(ctx context.Context, tenantID, rateID uuid.UUID) error {
+	for i, rate := range m.rates {
+		if rate.TenantID == tenantID && rate.ID == rateID {
+			m.rates = append(m.rates[:i], m.rates[i+1:]...)
+			return nil
+		}
+	}
+	return domain.ErrExchangeRateNotFound
+}
+
+func (m *MockExchangeRateRepository) List(ctx context.Context, tenantID uuid.UUID, filter domain.ExchangeRateFilter, opts domain.ListOptions) ([]*domain.ExchangeRate, int64, error) {
+	var result []*domain.ExchangeRate
+	for _, rate := range m.rates {
+		if rate.TenantID == tenantID {
+			result = append(result, rate)
+		}
+	}
+	return result, int64(len(result)), nil
+}
+
+func (m *MockExchangeRateRepository) ListEffective(ctx context.Context, tenantID uuid.UUID, at time.Time) ([]*domain.ExchangeRate, error) {
+	var result []*domain.ExchangeRate
+	for _, rate := range m.rates {
+		if rate.TenantID == tenantID && !rate.EffectiveDate.After(at) {
+			result = append(result, rate)
+		}
+	}
+	return result, nil
+}
+
+func (m *MockExchangeRateRepository) GetSettings(ctx context.Context, tenantID uuid.UUID) (*domain.CurrencySettings, error) {
+	return m.settings[tenantID], nil
+}
+
+func (m *MockExchangeRateRepository) SaveSettings(ctx context.Context, settings *domain.CurrencySettings) error {
+	m.settings[settings.TenantID] = settings
+	return nil
+}
+
+func (m *MockExchangeRateRepository) ListSettingsWithProvider(ctx context.Context) ([]*domain.CurrencySettings, error) {
+	var result []*domain.CurrencySettings
+	for _, settings := range m.settings {
+		if settings.RateProvider != nil {
+			result = append(result, settings)
+		}
+	}
+	return result, nil
+}
+
+// MockExchangeRateProvider is a mock implementation of ports.ExchangeRateProvider.
+type MockExchangeRateProvider struct {
+	source string
+	quotes []ports.ExchangeRateQuote
+	err    error
+	calls  int
+}
+
+func (m *MockExchangeRateProvider) Source() string {
+	return m.source
+}
+
+func (m *MockExchangeRateProvider) FetchRates(ctx context.Context) ([]ports.ExchangeRateQuote, error) {
+	m.calls++
+	return m.quotes, m.err
+}
+
+// ============================================================================
+// Exchange Rate Use Case Tests
+// ============================================================================
+
+func TestExchangeRateUseCase_SetRate(t *testing.T) {
+	repo := NewMockExchangeRateRepository()
+	uc := NewExchangeRateUseCase(repo, nil)
+	tenantID := uuid.New()
+
+	resp, err := uc.SetRate(context.Background(), tenantID, uuid.New(), &dto.SetExchangeRateRequest{
+		FromCurrency:  "usd",
+		ToCurrency:    "myr",
+		Rate:          4.5,
+		EffectiveDate: "2024-01-01",
+	})
+	if err != nil {
+		t.Fatalf("SetRate() error = %v", err)
+	}
+	if resp.FromCurrency != "USD" || resp.ToCurrency != "MYR" || resp.Source != "manual" {
+		t.Errorf("SetRate() = %+v", resp)
+	}
+
+	// Setting the same pair and day replaces the rate
+	if _, err := uc.SetRate(context.Background(), tenantID, uuid.New(), &dto.SetExchangeRateRequest{
+		FromCurrency:  "USD",
+		ToCurrency:    "MYR",
+		Rate:          4.6,
+		EffectiveDate: "2024-01-01",
+	}); err != nil {
+		t.Fatalf("SetRate() error = %v", err)
+	}
+	if len(repo.rates) != 1 || repo.rates[0].Rate != 4.6 {
+		t.Errorf("expected rate to be replaced, got %d rates", len(repo.rates))
+	}
+}
+
+func TestExchangeRateUseCase_SetRate_Validation(t *testing.T) {
+	uc := NewExchangeRateUseCase(NewMockExchangeRateRepository(), nil)
+
+	tests := []struct {
+		name string
+		req  *dto.SetExchangeRateRequest
+	}{
+		{"same currency", &dto.SetExchangeRateRequest{FromCurrency: "USD", ToCurrency: "USD", Rate: 1}},
+		{"unsupported currency", &dto.SetExchangeRateRequest{FromCurrency: "XYZ", ToCurrency: "USD", Rate: 1}},
+		{"invalid date", &dto.SetExchangeRateRequest{FromCurrency: "USD", ToCurrency: "MYR", Rate: 1, EffectiveDate: "01/01/2024"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := uc.SetRate(context.Background(), uuid.New(), uuid.New(), tt.req)
+			appErr := application.GetAppError(err)
+			if appErr == nil || (appErr.Code != application.ErrCodeValidation && appErr.Code != application.ErrCodeCurrencyInvalid) {
+				t.Errorf("SetRate() error = %v, want validation error", err)
+			}
+		})
+	}
+}
+
+func TestExchangeRateUseCase_Convert(t *testing.T) {
+	repo := NewMockExchangeRateRepository()
+	uc := NewExchangeRateUseCase(repo, nil)
+	tenantID := uuid.New()
+
+	rate, _ := domain.NewExchangeRate(tenantID, "EUR", "MYR", 5, time.Now().AddDate(0, 0, -1), domain.ExchangeRateSourceManual, nil)
+	repo.rates = append(repo.rates, rate)
+
+	resp, err := uc.Convert(context.Background(), tenantID, &dto.ConvertCurrencyRequest{Amount: 1000, From: "EUR"})
+	if err != nil {
+		t.Fatalf("Convert() error = %v", err)
+	}
+	if resp.Converted.Currency != "MYR" || resp.Converted.Amount != 5000 {
+		t.Errorf("Convert() = %+v, want 5000 MYR", resp.Converted)
+	}
+
+	_, err = uc.Convert(context.Background(), tenantID, &dto.ConvertCurrencyRequest{Amount: 1000, From: "JPY"})
+	if appErr := application.GetAppError(err); appErr == nil || appErr.Code != application.ErrCodeExchangeRateNotFound {
+		t.Errorf("Convert() error = %v, want exchange rate not found", err)
+	}
+}
+
+func TestExchangeRateUseCase_UpdateSettings(t *testing.T) {
+	repo := NewMockExchangeRateRepository()
+	uc := NewExchangeRateUseCase(repo, []ports.ExchangeRateProvider{&MockExchangeRateProvider{source: "ecb"}})
+	tenantID := uuid.New()
+
+	base, err := uc.BaseCurrency(context.Background(), tenantID)
+	if err != nil || base != domain.DefaultBaseCurrency {
+		t.Fatalf("BaseCurrency() = %q, %v, want default", base, err)
+	}
+
+	currency, provider := "sgd", "ecb"
+	resp, err := uc.UpdateSettings(context.Background(), tenantID, &dto.UpdateCurrencySettingsRequest{
+		BaseCurrency: &currency,
+		RateProvider: &provider,
+	})
+	if err != nil {
+		t.Fatalf("UpdateSettings() error = %v", err)
+	}
+	if resp.BaseCurrency != "SGD" || resp.RateProvider == nil || *resp.RateProvider != "ecb" {
+		t.Errorf("UpdateSettings() = %+v", resp)
+	}
+
+	unavailable := "bnm"
+	if _, err := uc.UpdateSettings(context.Background(), tenantID, &dto.UpdateCurrencySettingsRequest{RateProvider: &unavailable}); err == nil {
+		t.Error("UpdateSettings() expected error for unregistered provider")
+	}
+}
+
+func TestExchangeRateUseCase_RefreshAllTenants(t *testing.T) {
+	repo := NewMockExchangeRateRepository()
+	effective := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+	provider := &MockExchangeRateProvider{
+		source: "ecb",
+		quotes: []ports.ExchangeRateQuote{
+			{From: "EUR", To: "USD", Rate: 1.1, EffectiveDate: effective},
+			{From: "EUR", To: "XYZ", Rate: 2, EffectiveDate: effective}, // unsupported, skipped
+		},
+	}
+	uc := NewExchangeRateUseCase(repo, []ports.ExchangeRateProvider{provider})
+
+	ecb := domain.ExchangeRateSourceECB
+	for i := 0; i < 2; i++ {
+		tenantID := uuid.New()
+		repo.settings[tenantID] = &domain.CurrencySettings{TenantID: tenantID, BaseCurrency: "MYR", RateProvider: &ecb}
+	}
+	manualTenant := uuid.New()
+	repo.settings[manualTenant] = &domain.CurrencySettings{TenantID: manualTenant, BaseCurrency: "MYR"}
+
+	refreshed, err := uc.RefreshAllTenants(context.Background())
+	if err != nil {
+		t.Fatalf("RefreshAllTenants() error = %v", err)
+	}
+	if refreshed != 2 {
+		t.Errorf("RefreshAllTenants() refreshed = %d, want 2", refreshed)
+	}
+	if provider.calls != 1 {
+		t.Errorf("provider called %d times, want 1", provider.calls)
+	}
+	if len(repo.rates) != 2 {
+		t.Errorf("stored %d rates, want 2", len(repo.rates))
+	}
+	if repo.settings[manualTenant].LastFetchedAt != nil {
+		t.Error("tenant without a provider should not be refreshed")
+	}
+}
+
+func TestExchangeRateUseCase_RefreshAllTenants_ProviderError(t *testing.T) {
+	repo := NewMockExchangeRateRepository()
+	uc := NewExchangeRateUseCase(repo, []ports.ExchangeRateProvider{
+		&MockExchangeRateProvider{source: "bnm", err: errors.New("timeout")},
+	})
+
+	bnm := domain.ExchangeRateSourceBNM
+	tenantID := uuid.New()
+	repo.settings[tenantID] = &domain.CurrencySettings{TenantID: tenantID, BaseCurrency: "MYR", RateProvider: &bnm}
+
+	refreshed, err := uc.RefreshAllTenants(context.Background())
+	if err == nil {
+		t.Fatal("RefreshAllTenants() expected error")
+	}
+	if refreshed != 0 {
+		t.Errorf("RefreshAllTenants() refreshed = %d, want 0", refreshed)
+	}
+}
+
+func TestCurrencyTotals(t *testing.T) {
+	repo := NewMockExchangeRateRepository()
+	uc := NewExchangeRateUseCase(repo, nil)
+	tenantID := uuid.New()
+
+	rate, _ := domain.NewExchangeRate(tenantID, "USD", "MYR", 4.5, time.Now().AddDate(0, 0, -1), domain.ExchangeRateSourceManual, nil)
+	repo.rates = append(repo.rates, rate)
+
+	totals := newCurrencyTotals(context.Background(), uc, tenantID, "MYR", time.Now())
+	totals.add("MYR", 2, 10000, 5000, nil, nil)
+	totals.add("USD", 1, 10000, 5000, nil, nil)
+	// A stored base value takes precedence over the current rate
+	totals.add("USD", 1, 10000, 5000, &domain.Money{Amount: 40000, Currency: "MYR"}, &domain.Money{Amount: 20000, Currency: "MYR"})
+	if _, _, ok := totals.add("JPY", 3, 1000, 500, nil, nil); ok {
+		t.Error("add() expected JPY to be unconverted")
+	}
+
+	if totals.total != 10000+45000+40000 {
+		t.Errorf("total = %d, want %d", totals.total, 10000+45000+40000)
+	}
+	if totals.weightedTotal != 5000+22500+20000 {
+		t.Errorf("weightedTotal = %d, want %d", totals.weightedTotal, 5000+22500+20000)
+	}
+
+	breakdown := totals.breakdown()
+	if len(breakdown) != 3 || breakdown[0].Currency != "JPY" || breakdown[1].Currency != "MYR" || breakdown[2].Currency != "USD" {
+		t.Fatalf("breakdown() = %+v", breakdown)
+	}
+	if breakdown[0].UnconvertedCount != 3 || breakdown[0].ExchangeRate != nil {
+		t.Errorf("JPY breakdown = %+v", breakdown[0])
+	}
+	usd := breakdown[2]
+	if usd.OpportunityCount != 2 || usd.Amount.Amount != 20000 || usd.ConvertedAmount.Amount != 85000 {
+		t.Errorf("USD breakdown = %+v", usd)
+	}
+	if usd.ExchangeRate == nil || math.Abs(*usd.ExchangeRate-4.5) > 1e-9 {
+		t.Errorf("USD exchange rate = %v, want 4.5", usd.ExchangeRate)
+	}
+}
@@ -0,0 +1,233 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/kilang-desa-murni/crm/internal/sales/application"
+	"github.com/kilang-desa-murni/crm/internal/sales/application/dto"
+	"github.com/kilang-desa-murni/crm/internal/sales/domain"
+)
+
+// ============================================================================
+// Funnel Use Case Interface
+// ============================================================================
+
+// FunnelUseCase defines the interface for stage duration analytics and the
+// pipeline conversion funnel, served from the stage funnel read model.
+type FunnelUseCase interface {
+	// GetFunnel returns a pipeline's conversion funnel for a cohort.
+	GetFunnel(ctx context.Context, tenantID, pipelineID uuid.UUID, req *dto.GetFunnelRequest) (*dto.FunnelResponse, error)
+
+	// ProjectOpportunity brings an opportunity's funnel entries up to date
+	// with its stage history, removing them if the opportunity no longer exists.
+	ProjectOpportunity(ctx context.Context, tenantID, opportunityID uuid.UUID) error
+
+	// RebuildFunnel rebuilds every funnel entry of a pipeline from its opportunities.
+	RebuildFunnel(ctx context.Context, tenantID, pipelineID uuid.UUID) (*dto.FunnelRebuildResponse, error)
+}
+
+// ============================================================================
+// Funnel Use Case Implementation
+// ============================================================================
+
+// funnelUseCase implements FunnelUseCase.
+type funnelUseCase struct {
+	funnelRepo      domain.StageFunnelRepository
+	pipelineRepo    domain.PipelineRepository
+	opportunityRepo domain.OpportunityRepository
+}
+
+// NewFunnelUseCase creates a new funnel use case.
+func NewFunnelUseCase(
+	funnelRepo domain.StageFunnelRepository,
+	pipelineRepo domain.PipelineRepository,
+	opportunityRepo domain.OpportunityRepository,
+) FunnelUseCase {
+	return &funnelUseCase{
+		funnelRepo:      funnelRepo,
+		pipelineRepo:    pipelineRepo,
+		opportunityRepo: opportunityRepo,
+	}
+}
+
+// GetFunnel returns a pipeline's conversion funnel for a cohort. Steps follow
+// the order of the pipeline's active stages, lost stages excepted.
+func (uc *funnelUseCase) GetFunnel(ctx context.Context, tenantID, pipelineID uuid.UUID, req *dto.GetFunnelRequest) (*dto.FunnelResponse, error) {
+	filter, err := parseFunnelFilter(req)
+	if err != nil {
+		return nil, err
+	}
+
+	pipeline, err := uc.pipelineRepo.GetByID(ctx, tenantID, pipelineID)
+	if err != nil {
+		if errors.Is(err, domain.ErrPipelineNotFound) {
+			return nil, application.ErrPipelineNotFound(pipelineID)
+		}
+		return nil, application.WrapError(application.ErrCodeInternal, "failed to get pipeline", err)
+	}
+
+	durations, err := uc.funnelRepo.GetStageDurations(ctx, tenantID, pipelineID, filter)
+	if err != nil {
+		return nil, application.WrapError(application.ErrCodeInternal, "failed to get stage durations", err)
+	}
+	reached, err := uc.funnelRepo.GetFurthestStages(ctx, tenantID, pipelineID, filter)
+	if err != nil {
+		return nil, application.WrapError(application.ErrCodeInternal, "failed to get funnel stages", err)
+	}
+
+	funnel := domain.BuildStageFunnel(pipeline, durations, reached)
+
+	resp := &dto.FunnelResponse{
+		PipelineID:            pipeline.ID.String(),
+		PipelineName:          pipeline.Name,
+		Filters:               *req,
+		Opportunities:         funnel.Opportunities,
+		Lost:                  funnel.Lost,
+		OverallConversionRate: funnel.OverallConversionRate,
+		Steps:                 make([]*dto.FunnelStepResponse, len(funnel.Steps)),
+	}
+	for i, step := range funnel.Steps {
+		resp.Steps[i] = &dto.FunnelStepResponse{
+			StageID:         step.Stage.ID.String(),
+			Name:            step.Stage.Name,
+			Type:            string(step.Stage.Type),
+			Order:           step.Stage.Order,
+			Reached:         step.Reached,
+			Entered:         step.Entered,
+			ConversionRate:  step.ConversionRate,
+			DropOff:         step.DropOff,
+			CompletedVisits: step.CompletedVisits,
+			AverageHours:    step.AverageHours,
+			MedianHours:     step.MedianHours,
+		}
+	}
+
+	return resp, nil
+}
+
+// ProjectOpportunity brings an opportunity's funnel entries up to date. Like
+// the board, entries are rebuilt from the opportunity rather than from the
+// event, so a missed or repeated event is corrected by the next one.
+func (uc *funnelUseCase) ProjectOpportunity(ctx context.Context, tenantID, opportunityID uuid.UUID) error {
+	opportunity, err := uc.opportunityRepo.GetByID(ctx, tenantID, opportunityID)
+	if err != nil {
+		if errors.Is(err, domain.ErrOpportunityNotFound) {
+			if err := uc.funnelRepo.ReplaceOpportunityEntries(ctx, tenantID, opportunityID, nil); err != nil {
+				return application.WrapError(application.ErrCodeInternal, "failed to remove funnel entries", err)
+			}
+			return nil
+		}
+		return application.WrapError(application.ErrCodeInternal, "failed to get opportunity", err)
+	}
+
+	pipeline, err := uc.pipelineRepo.GetByID(ctx, tenantID, opportunity.PipelineID)
+	if err != nil {
+		return application.WrapError(application.ErrCodeInternal, "failed to get pipeline", err)
+	}
+
+	entries := domain.NewStageFunnelEntries(opportunity, pipeline)
+	if err := uc.funnelRepo.ReplaceOpportunityEntries(ctx, tenantID, opportunityID, entries); err != nil {
+		return application.WrapError(application.ErrCodeInternal, "failed to update funnel entries", err)
+	}
+	return nil
+}
+
+// RebuildFunnel rebuilds every funnel entry of a pipeline from its
+// opportunities, picking up stage reorders made since they were projected.
+func (uc *funnelUseCase) RebuildFunnel(ctx context.Context, tenantID, pipelineID uuid.UUID) (*dto.FunnelRebuildResponse, error) {
+	pipeline, err := uc.pipelineRepo.GetByID(ctx, tenantID, pipelineID)
+	if err != nil {
+		if errors.Is(err, domain.ErrPipelineNotFound) {
+			return nil, application.ErrPipelineNotFound(pipelineID)
+		}
+		return nil, application.WrapError(application.ErrCodeInternal, "failed to get pipeline", err)
+	}
+
+	var entries []*domain.StageFunnelEntry
+	count := 0
+	opts := domain.ListOptions{Page: 1, PageSize: 100, SortBy: "created_at", SortOrder: "asc"}
+	for {
+		opportunities, total, err := uc.opportunityRepo.GetByPipeline(ctx, tenantID, pipelineID, opts)
+		if err != nil {
+			return nil, application.WrapError(application.ErrCodeInternal, "failed to list pipeline opportunities", err)
+		}
+		for _, opportunity := range opportunities {
+			entries = append(entries, domain.NewStageFunnelEntries(opportunity, pipeline)...)
+		}
+		count += len(opportunities)
+		if len(opportunities) == 0 || int64(count) >= total {
+			break
+		}
+		opts.Page++
+	}
+
+	if err := uc.funnelRepo.ReplacePipelineEntries(ctx, tenantID, pipelineID, entries); err != nil {
+		return nil, application.WrapError(application.ErrCodeInternal, "failed to rebuild funnel", err)
+	}
+
+	return &dto.FunnelRebuildResponse{
+		PipelineID:    pipelineID.String(),
+		Opportunities: count,
+		Entries:       len(entries),
+		RebuiltAt:     time.Now().UTC(),
+	}, nil
+}
+
+// ============================================================================
+// Helpers
+// ============================================================================
+
+// parseFunnelFilter parses a funnel request into a cohort filter. The date
+// range is inclusive, so To selects up to the end of that day.
+func parseFunnelFilter(req *dto.GetFunnelRequest) (domain.StageFunnelFilter, error) {
+	var filter domain.StageFunnelFilter
+
+	if req.From != "" {
+		from, err := time.Parse("2006-01-02", req.From)
+		if err != nil {
+			return filter, application.ErrValidationWithDetails("invalid from date", map[string]interface{}{
+				"from": req.From,
+			})
+		}
+		filter.From = &from
+	}
+	if req.To != "" {
+		to, err := time.Parse("2006-01-02", req.To)
+		if err != nil {
+			return filter, application.ErrValidationWithDetails("invalid to date", map[string]interface{}{
+				"to": req.To,
+			})
+		}
+		to = to.AddDate(0, 0, 1)
+		filter.To = &to
+	}
+	if filter.From != nil && filter.To != nil && !filter.From.Before(*filter.To) {
+		return filter, application.ErrValidation("from must not be after to")
+	}
+
+	if req.OwnerID != "" {
+		ownerID, err := uuid.Parse(req.OwnerID)
+		if err != nil {
+			return filter, application.ErrValidationWithDetails("invalid owner_id", map[string]interface{}{
+				"owner_id": req.OwnerID,
+			})
+		}
+		filter.OwnerID = &ownerID
+	}
+	if req.ProductID != "" {
+		productID, err := uuid.Parse(req.ProductID)
+		if err != nil {
+			return filter, application.ErrValidationWithDetails("invalid product_id", map[string]interface{}{
+				"product_id": req.ProductID,
+			})
+		}
+		filter.ProductID = &productID
+	}
+	filter.Source = req.Source
+
+	return filter, nil
+}
@@ -0,0 +1,176 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/kilang-desa-murni/crm/internal/sales/application"
+	"github.com/kilang-desa-murni/crm/internal/sales/application/dto"
+	"github.com/kilang-desa-murni/crm/internal/sales/domain"
+)
+
+// ============================================================================
+// Mock Implementations for Funnel Tests
+// ============================================================================
+
+// MockStageFunnelRepository is a mock implementation of domain.StageFunnelRepository.
+type MockStageFunnelRepository struct {
+	entries    map[uuid.UUID][]*domain.StageFunnelEntry
+	durations  []*domain.StageDurationStatistics
+	reached    []*domain.StageReachCount
+	lastFilter domain.StageFunnelFilter
+}
+
+func NewMockStageFunnelRepository() *MockStageFunnelRepository {
+	return &MockStageFunnelRepository{entries: make(map[uuid.UUID][]*domain.StageFunnelEntry)}
+}
+
+func (m *MockStageFunnelRepository) ReplaceOpportunityEntries(ctx context.Context, tenantID, opportunityID uuid.UUID, entries []*domain.StageFunnelEntry) error {
+	if len(entries) == 0 {
+		delete(m.entries, opportunityID)
+		return nil
+	}
+	m.entries[opportunityID] = entries
+	return nil
+}
+
+func (m *MockStageFunnelRepository) ReplacePipelineEntries(ctx context.Context, tenantID, pipelineID uuid.UUID, entries []*domain.StageFunnelEntry) error {
+	for id, existing := range m.entries {
+		if existing[0].PipelineID == pipelineID {
+			delete(m.entries, id)
+		}
+	}
+	for _, entry := range entries {
+		m.entries[entry.OpportunityID] = append(m.entries[entry.OpportunityID], entry)
+	}
+	return nil
+}
+
+func (m *MockStageFunnelRepository) GetStageDurations(ctx context.Context, tenantID, pipelineID uuid.UUID, filter domain.StageFunnelFilter) ([]*domain.StageDurationStatistics, error) {
+	m.lastFilter = filter
+	return m.durations, nil
+}
+
+func (m *MockStageFunnelRepository) GetFurthestStages(ctx context.Context, tenantID, pipelineID uuid.UUID, filter domain.StageFunnelFilter) ([]*domain.StageReachCount, error) {
+	return m.reached, nil
+}
+
+// ============================================================================
+// Funnel Use Case Tests
+// ============================================================================
+
+func TestFunnelUseCase_GetFunnel(t *testing.T) {
+	tenantID := uuid.New()
+	pipeline, _ := domain.NewPipeline(tenantID, "Sales", "MYR", uuid.New())
+	pipelineRepo := NewMockPipelineRepository()
+	pipelineRepo.pipelines[pipeline.ID] = pipeline
+
+	stages := pipeline.GetActiveStages()
+	funnelRepo := NewMockStageFunnelRepository()
+	funnelRepo.durations = []*domain.StageDurationStatistics{
+		{StageID: stages[0].ID, Opportunities: 4, CompletedVisits: 2, AverageHours: 12, MedianHours: 10},
+	}
+	funnelRepo.reached = []*domain.StageReachCount{
+		{StageID: stages[0].ID, Opportunities: 2},
+		{StageID: stages[1].ID, Opportunities: 2},
+	}
+
+	uc := NewFunnelUseCase(funnelRepo, pipelineRepo, NewMockOpportunityRepository())
+
+	ownerID := uuid.New()
+	resp, err := uc.GetFunnel(context.Background(), tenantID, pipeline.ID, &dto.GetFunnelRequest{
+		From:    "2026-01-01",
+		To:      "2026-03-31",
+		OwnerID: ownerID.String(),
+		Source:  "website",
+	})
+	if err != nil {
+		t.Fatalf("GetFunnel() error = %v", err)
+	}
+
+	filter := funnelRepo.lastFilter
+	wantTo := time.Date(2026, 4, 1, 0, 0, 0, 0, time.UTC)
+	if filter.To == nil || !filter.To.Equal(wantTo) || filter.OwnerID == nil || *filter.OwnerID != ownerID || filter.Source != "website" {
+		t.Errorf("GetFunnel() filter = %+v, want owner, source and the day after to", filter)
+	}
+
+	if len(resp.Steps) != len(stages) || resp.Opportunities != 4 {
+		t.Fatalf("GetFunnel() = %d steps, %d opportunities; want %d steps, 4 opportunities", len(resp.Steps), resp.Opportunities, len(stages))
+	}
+	first := resp.Steps[0]
+	if first.Reached != 4 || first.ConversionRate != 50 || first.DropOff != 2 || first.MedianHours != 10 {
+		t.Errorf("GetFunnel() first step = %+v, want 4 reached, 50%% conversion, 2 dropped, 10h median", first)
+	}
+}
+
+func TestFunnelUseCase_GetFunnel_Validation(t *testing.T) {
+	tenantID := uuid.New()
+	pipeline, _ := domain.NewPipeline(tenantID, "Sales", "MYR", uuid.New())
+	pipelineRepo := NewMockPipelineRepository()
+	pipelineRepo.pipelines[pipeline.ID] = pipeline
+	uc := NewFunnelUseCase(NewMockStageFunnelRepository(), pipelineRepo, NewMockOpportunityRepository())
+
+	tests := []struct {
+		name string
+		req  *dto.GetFunnelRequest
+	}{
+		{"invalid from", &dto.GetFunnelRequest{From: "01/02/2026"}},
+		{"from after to", &dto.GetFunnelRequest{From: "2026-03-01", To: "2026-02-01"}},
+		{"invalid owner_id", &dto.GetFunnelRequest{OwnerID: "nope"}},
+		{"invalid product_id", &dto.GetFunnelRequest{ProductID: "nope"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := uc.GetFunnel(context.Background(), tenantID, pipeline.ID, tt.req)
+			if appErr := application.GetAppError(err); appErr == nil || appErr.Code != application.ErrCodeValidation {
+				t.Errorf("GetFunnel() error = %v, want validation error", err)
+			}
+		})
+	}
+}
+
+func TestFunnelUseCase_ProjectOpportunity(t *testing.T) {
+	tenantID := uuid.New()
+	pipeline, _ := domain.NewPipeline(tenantID, "Sales", "MYR", uuid.New())
+	pipelineRepo := NewMockPipelineRepository()
+	pipelineRepo.pipelines[pipeline.ID] = pipeline
+	funnelRepo := NewMockStageFunnelRepository()
+	oppRepo := NewMockOpportunityRepository()
+	uc := NewFunnelUseCase(funnelRepo, pipelineRepo, oppRepo)
+
+	stages := pipeline.GetActiveStages()
+	entered := time.Now().UTC().Add(-48 * time.Hour)
+	exited := entered.Add(24 * time.Hour)
+	opp := &domain.Opportunity{
+		ID:         uuid.New(),
+		TenantID:   tenantID,
+		PipelineID: pipeline.ID,
+		StageID:    stages[1].ID,
+		Status:     domain.OpportunityStatusOpen,
+		StageHistory: []domain.StageHistory{
+			{StageID: stages[0].ID, EnteredAt: entered, ExitedAt: &exited},
+			{StageID: stages[1].ID, EnteredAt: exited},
+		},
+	}
+	oppRepo.opportunities[opp.ID] = opp
+
+	if err := uc.ProjectOpportunity(context.Background(), tenantID, opp.ID); err != nil {
+		t.Fatalf("ProjectOpportunity() error = %v", err)
+	}
+	entries := funnelRepo.entries[opp.ID]
+	if len(entries) != 2 || entries[0].DurationSeconds == nil || *entries[0].DurationSeconds != 24*3600 {
+		t.Fatalf("ProjectOpportunity() entries = %d, want 2 with a day in the first stage", len(entries))
+	}
+
+	oppRepo.getByIDErr = domain.ErrOpportunityNotFound
+	if err := uc.ProjectOpportunity(context.Background(), tenantID, opp.ID); err != nil {
+		t.Fatalf("ProjectOpportunity() deleted opportunity error = %v", err)
+	}
+	if _, ok := funnelRepo.entries[opp.ID]; ok {
+		t.Error("ProjectOpportunity() kept the entries of a deleted opportunity")
+	}
+}
@@ -0,0 +1,435 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/kilang-desa-murni/crm/internal/sales/application"
+	"github.com/kilang-desa-murni/crm/internal/sales/application/dto"
+	"github.com/kilang-desa-murni/crm/internal/sales/application/ports"
+	"github.com/kilang-desa-murni/crm/internal/sales/domain"
+)
+
+// ============================================================================
+// Inbound Email Use Case Interface
+// ============================================================================
+
+// InboundEmailUseCase defines the interface for turning emailed enquiries into
+// leads. Each tenant gets a forwarding address on the inbound domain; email
+// delivered to it creates a lead, or is logged on the sender's existing lead.
+type InboundEmailUseCase interface {
+	// GetMailbox returns the tenant's forwarding address, creating a disabled
+	// one on first use.
+	GetMailbox(ctx context.Context, tenantID uuid.UUID) (*dto.InboundMailboxResponse, error)
+	UpdateMailbox(ctx context.Context, tenantID uuid.UUID, req *dto.UpdateInboundMailboxRequest) (*dto.InboundMailboxResponse, error)
+
+	// Receive ingests a raw email delivered by the mail relay.
+	Receive(ctx context.Context, req *dto.ReceiveInboundEmailRequest) (*dto.InboundEmailResultResponse, error)
+
+	// ListLeadEmails lists the emails logged on a lead.
+	ListLeadEmails(ctx context.Context, tenantID, leadID uuid.UUID, req *dto.ListInboundEmailsRequest) (*dto.InboundEmailListResponse, error)
+}
+
+// ============================================================================
+// Inbound Email Use Case Implementation
+// ============================================================================
+
+// inboundEmailUseCase implements InboundEmailUseCase.
+type inboundEmailUseCase struct {
+	mailboxRepo     domain.InboundMailboxRepository
+	emailRepo       domain.InboundEmailRepository
+	leadRepo        domain.LeadRepository
+	opportunityRepo domain.OpportunityRepository
+	parser          ports.EmailParser
+	fileStorage     ports.FileStorageService
+	eventPublisher  ports.EventPublisher
+	inboundDomain   string
+}
+
+// NewInboundEmailUseCase creates a new inbound email use case. inboundDomain
+// is the mail domain forwarding addresses are created on; ingestion is off
+// when it is empty.
+func NewInboundEmailUseCase(
+	mailboxRepo domain.InboundMailboxRepository,
+	emailRepo domain.InboundEmailRepository,
+	leadRepo domain.LeadRepository,
+	opportunityRepo domain.OpportunityRepository,
+	parser ports.EmailParser,
+	fileStorage ports.FileStorageService,
+	eventPublisher ports.EventPublisher,
+	inboundDomain string,
+) InboundEmailUseCase {
+	return &inboundEmailUseCase{
+		mailboxRepo:     mailboxRepo,
+		emailRepo:       emailRepo,
+		leadRepo:        leadRepo,
+		opportunityRepo: opportunityRepo,
+		parser:          parser,
+		fileStorage:     fileStorage,
+		eventPublisher:  eventPublisher,
+		inboundDomain:   inboundDomain,
+	}
+}
+
+// GetMailbox returns the tenant's forwarding address.
+func (uc *inboundEmailUseCase) GetMailbox(ctx context.Context, tenantID uuid.UUID) (*dto.InboundMailboxResponse, error) {
+	mailbox, err := uc.getOrCreateMailbox(ctx, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	return uc.mapMailboxToResponse(mailbox), nil
+}
+
+// UpdateMailbox enables or disables the forwarding address, sets the owner of
+// new leads, or replaces the address.
+func (uc *inboundEmailUseCase) UpdateMailbox(ctx context.Context, tenantID uuid.UUID, req *dto.UpdateInboundMailboxRequest) (*dto.InboundMailboxResponse, error) {
+	mailbox, err := uc.getOrCreateMailbox(ctx, tenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	enabled, ownerID := mailbox.Enabled, mailbox.DefaultOwnerID
+	if req.Enabled != nil {
+		enabled = *req.Enabled
+	}
+	if req.DefaultOwnerID != nil {
+		if *req.DefaultOwnerID == "" {
+			ownerID = nil
+		} else {
+			id, err := uuid.Parse(*req.DefaultOwnerID)
+			if err != nil {
+				return nil, application.ErrValidation("invalid default owner ID")
+			}
+			ownerID = &id
+		}
+	}
+	if enabled && uc.inboundDomain == "" {
+		return nil, application.ErrServiceUnavailable("inbound email")
+	}
+	mailbox.Configure(enabled, ownerID)
+
+	if req.RotateAddress {
+		if err := mailbox.RotateAddress(); err != nil {
+			return nil, application.WrapError(application.ErrCodeInternal, "failed to generate inbound address", err)
+		}
+	}
+
+	if err := uc.mailboxRepo.Upsert(ctx, mailbox); err != nil {
+		return nil, application.WrapError(application.ErrCodeInternal, "failed to save inbound mailbox", err)
+	}
+	return uc.mapMailboxToResponse(mailbox), nil
+}
+
+// Receive ingests a raw email. The tenant is found from the forwarding
+// address the email was delivered to. Automated mail, duplicates and floods
+// from one sender are acknowledged as skipped without touching any lead.
+func (uc *inboundEmailUseCase) Receive(ctx context.Context, req *dto.ReceiveInboundEmailRequest) (*dto.InboundEmailResultResponse, error) {
+	if uc.inboundDomain == "" || uc.parser == nil {
+		return nil, application.ErrServiceUnavailable("inbound email")
+	}
+
+	parsed, err := uc.parser.Parse(req.Raw)
+	if err != nil {
+		return nil, application.ErrValidation("invalid email: " + err.Error())
+	}
+	msg := &domain.InboundEmailMessage{
+		MessageID:   parsed.MessageID,
+		FromAddress: parsed.FromAddress,
+		FromName:    parsed.FromName,
+		Recipients:  parsed.Recipients,
+		Subject:     parsed.Subject,
+		Body:        parsed.Body,
+		Headers:     parsed.Headers,
+		Date:        parsed.Date,
+	}
+
+	mailbox, err := uc.findMailbox(ctx, req.Recipient, msg.Recipients)
+	if err != nil {
+		return nil, err
+	}
+	tenantID := mailbox.TenantID
+
+	if reason := msg.SkipReason(uc.inboundDomain); reason != "" {
+		return skippedInboundEmail(reason), nil
+	}
+
+	exists, err := uc.emailRepo.ExistsByMessageID(ctx, tenantID, msg.MessageID)
+	if err != nil {
+		return nil, application.WrapError(application.ErrCodeInternal, "failed to check for duplicate email", err)
+	}
+	if exists {
+		return skippedInboundEmail(domain.InboundEmailSkipDuplicate), nil
+	}
+
+	recent, err := uc.emailRepo.CountFromSenderSince(ctx, tenantID, msg.SenderContact().Email, time.Now().UTC().Add(-time.Hour))
+	if err != nil {
+		return nil, application.WrapError(application.ErrCodeInternal, "failed to count recent emails", err)
+	}
+	if recent >= domain.MaxInboundEmailsPerSenderPerHour {
+		return skippedInboundEmail(domain.InboundEmailSkipRateLimited), nil
+	}
+
+	email := domain.NewInboundEmail(tenantID, msg)
+	lead, err := uc.matchLead(ctx, tenantID, msg)
+	if err != nil {
+		return nil, err
+	}
+	if lead == nil {
+		if lead, err = uc.createLead(ctx, mailbox, msg); err != nil {
+			return nil, err
+		}
+		email.LeadCreated = true
+	}
+	email.LeadID = &lead.ID
+
+	// Replies from a converted lead belong to the deal in progress
+	if lead.ConversionInfo != nil {
+		if opp, err := uc.opportunityRepo.GetByID(ctx, tenantID, lead.ConversionInfo.OpportunityID); err == nil {
+			opp.RecordActivity(email.ReceivedAt)
+			if err := uc.opportunityRepo.Update(ctx, opp); err != nil {
+				return nil, application.WrapError(application.ErrCodeInternal, "failed to record opportunity activity", err)
+			}
+			email.OpportunityID = &opp.ID
+		}
+	}
+
+	email.AttachmentIDs = uc.storeAttachments(ctx, tenantID, lead.ID, msg.MessageID, parsed.Attachments)
+
+	if err := uc.emailRepo.Create(ctx, email); err != nil {
+		if errors.Is(err, domain.ErrInboundEmailDuplicate) {
+			return skippedInboundEmail(domain.InboundEmailSkipDuplicate), nil
+		}
+		return nil, application.WrapError(application.ErrCodeInternal, "failed to record inbound email", err)
+	}
+
+	if !email.LeadCreated {
+		lead.RecordEngagement("email_received")
+		if err := uc.leadRepo.Update(ctx, lead); err != nil {
+			return nil, application.WrapError(application.ErrCodeInternal, "failed to update lead", err)
+		}
+	}
+	lead.AddEvent(domain.NewLeadEmailReceivedEvent(lead, email))
+	uc.publishDomainEvents(ctx, lead.GetEvents())
+	lead.ClearEvents()
+
+	return &dto.InboundEmailResultResponse{
+		Status: "processed",
+		Email:  mapInboundEmailToResponse(email),
+	}, nil
+}
+
+// ListLeadEmails lists the emails logged on a lead.
+func (uc *inboundEmailUseCase) ListLeadEmails(ctx context.Context, tenantID, leadID uuid.UUID, req *dto.ListInboundEmailsRequest) (*dto.InboundEmailListResponse, error) {
+	if _, err := uc.leadRepo.GetByID(ctx, tenantID, leadID); err != nil {
+		return nil, application.ErrLeadNotFound(leadID)
+	}
+
+	page, pageSize := req.Page, req.PageSize
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 {
+		pageSize = 20
+	}
+
+	emails, total, err := uc.emailRepo.ListByLead(ctx, tenantID, leadID, domain.ListOptions{Page: page, PageSize: pageSize})
+	if err != nil {
+		return nil, application.WrapError(application.ErrCodeInternal, "failed to list lead emails", err)
+	}
+
+	resp := &dto.InboundEmailListResponse{
+		Emails:     make([]*dto.InboundEmailResponse, len(emails)),
+		Pagination: dto.NewPaginationResponse(page, pageSize, total),
+	}
+	for i, email := range emails {
+		resp.Emails[i] = mapInboundEmailToResponse(email)
+	}
+	return resp, nil
+}
+
+func (uc *inboundEmailUseCase) getOrCreateMailbox(ctx context.Context, tenantID uuid.UUID) (*domain.InboundMailbox, error) {
+	mailbox, err := uc.mailboxRepo.Get(ctx, tenantID)
+	if err != nil {
+		return nil, application.WrapError(application.ErrCodeInternal, "failed to get inbound mailbox", err)
+	}
+	if mailbox != nil {
+		return mailbox, nil
+	}
+
+	mailbox, err = domain.NewInboundMailbox(tenantID)
+	if err != nil {
+		return nil, application.WrapError(application.ErrCodeInternal, "failed to generate inbound address", err)
+	}
+	if err := uc.mailboxRepo.Upsert(ctx, mailbox); err != nil {
+		return nil, application.WrapError(application.ErrCodeInternal, "failed to save inbound mailbox", err)
+	}
+	return mailbox, nil
+}
+
+// findMailbox finds the enabled mailbox an email was delivered to, preferring
+// the envelope recipient over the message headers.
+func (uc *inboundEmailUseCase) findMailbox(ctx context.Context, envelopeRecipient string, recipients []string) (*domain.InboundMailbox, error) {
+	candidates := recipients
+	if envelopeRecipient != "" {
+		candidates = []string{envelopeRecipient}
+	}
+
+	for _, recipient := range candidates {
+		token, ok := domain.InboundMailboxToken(recipient, uc.inboundDomain)
+		if !ok {
+			continue
+		}
+		mailbox, err := uc.mailboxRepo.GetByToken(ctx, token)
+		if err != nil {
+			if errors.Is(err, domain.ErrInboundMailboxNotFound) {
+				continue
+			}
+			return nil, application.WrapError(application.ErrCodeInternal, "failed to get inbound mailbox", err)
+		}
+		if mailbox.Enabled {
+			return mailbox, nil
+		}
+	}
+
+	address := envelopeRecipient
+	if address == "" && len(recipients) > 0 {
+		address = recipients[0]
+	}
+	return nil, application.ErrInboundMailboxNotFound(address)
+}
+
+// matchLead finds the sender's existing lead. Deleted leads do not match, so
+// a returning sender starts a new lead.
+func (uc *inboundEmailUseCase) matchLead(ctx context.Context, tenantID uuid.UUID, msg *domain.InboundEmailMessage) (*domain.Lead, error) {
+	lead, err := uc.leadRepo.GetByEmail(ctx, tenantID, msg.SenderContact().Email)
+	if err != nil {
+		if errors.Is(err, domain.ErrLeadNotFound) {
+			return nil, nil
+		}
+		return nil, application.WrapError(application.ErrCodeInternal, "failed to match sender to a lead", err)
+	}
+	if lead == nil || lead.IsDeleted() {
+		return nil, nil
+	}
+	return lead, nil
+}
+
+func (uc *inboundEmailUseCase) createLead(ctx context.Context, mailbox *domain.InboundMailbox, msg *domain.InboundEmailMessage) (*domain.Lead, error) {
+	lead, err := domain.NewLead(mailbox.TenantID, msg.SenderContact(), msg.SenderCompany(), domain.LeadSourceEmail, uuid.Nil)
+	if err != nil {
+		return nil, application.ErrValidation(err.Error())
+	}
+	lead.Description = msg.Subject
+	if body := msg.TruncatedBody(); body != "" {
+		lead.Description += "\n\n" + body
+	}
+	lead.AddTag("email-enquiry")
+	if mailbox.DefaultOwnerID != nil {
+		lead.AssignOwner(*mailbox.DefaultOwnerID, "")
+	}
+
+	if err := uc.leadRepo.Create(ctx, lead); err != nil {
+		return nil, application.WrapError(application.ErrCodeInternal, "failed to create lead", err)
+	}
+	return lead, nil
+}
+
+// storeAttachments stores the email's attachments on the lead. Attachments
+// over the limits are dropped rather than failing the whole email.
+func (uc *inboundEmailUseCase) storeAttachments(ctx context.Context, tenantID, leadID uuid.UUID, messageID string, attachments []ports.EmailAttachment) []string {
+	if uc.fileStorage == nil {
+		return nil
+	}
+
+	var ids []string
+	for _, attachment := range attachments {
+		if len(ids) == domain.MaxInboundEmailAttachments {
+			break
+		}
+		if len(attachment.Content) == 0 || len(attachment.Content) > domain.MaxInboundEmailAttachmentSize {
+			continue
+		}
+		info, err := uc.fileStorage.Upload(ctx, ports.FileUploadRequest{
+			TenantID:    tenantID,
+			EntityType:  "lead",
+			EntityID:    leadID,
+			Filename:    attachment.Filename,
+			ContentType: attachment.ContentType,
+			Content:     attachment.Content,
+			Metadata:    map[string]string{"message_id": messageID},
+		})
+		if err != nil {
+			continue
+		}
+		ids = append(ids, info.ID)
+	}
+	return ids
+}
+
+// publishDomainEvents converts domain events to ports.Event and publishes them.
+func (uc *inboundEmailUseCase) publishDomainEvents(ctx context.Context, events []domain.DomainEvent) {
+	if uc.eventPublisher == nil {
+		return
+	}
+
+	for _, domainEvent := range events {
+		event := ports.Event{
+			ID:            domainEvent.EventID().String(),
+			Type:          domainEvent.EventType(),
+			AggregateID:   domainEvent.AggregateID().String(),
+			AggregateType: domainEvent.AggregateType(),
+			TenantID:      domainEvent.TenantID().String(),
+			Payload:       make(map[string]interface{}),
+			Metadata:      map[string]string{"source": "inbound_email"},
+			OccurredAt:    domainEvent.OccurredAt(),
+			Version:       domainEvent.Version(),
+		}
+		_ = uc.eventPublisher.Publish(ctx, event)
+	}
+}
+
+// ============================================================================
+// Helpers
+// ============================================================================
+
+func skippedInboundEmail(reason domain.InboundEmailSkipReason) *dto.InboundEmailResultResponse {
+	return &dto.InboundEmailResultResponse{Status: "skipped", SkipReason: string(reason)}
+}
+
+func (uc *inboundEmailUseCase) mapMailboxToResponse(mailbox *domain.InboundMailbox) *dto.InboundMailboxResponse {
+	resp := &dto.InboundMailboxResponse{
+		Enabled:   mailbox.Enabled,
+		UpdatedAt: &mailbox.UpdatedAt,
+	}
+	if uc.inboundDomain != "" {
+		resp.Address = mailbox.Address(uc.inboundDomain)
+	}
+	if mailbox.DefaultOwnerID != nil {
+		resp.DefaultOwnerID = dto.StringPtr(mailbox.DefaultOwnerID.String())
+	}
+	return resp
+}
+
+func mapInboundEmailToResponse(email *domain.InboundEmail) *dto.InboundEmailResponse {
+	resp := &dto.InboundEmailResponse{
+		ID:            email.ID.String(),
+		MessageID:     email.MessageID,
+		FromAddress:   email.FromAddress,
+		FromName:      email.FromName,
+		Subject:       email.Subject,
+		Body:          email.Body,
+		LeadCreated:   email.LeadCreated,
+		AttachmentIDs: email.AttachmentIDs,
+		ReceivedAt:    email.ReceivedAt,
+	}
+	if email.LeadID != nil {
+		resp.LeadID = dto.StringPtr(email.LeadID.String())
+	}
+	if email.OpportunityID != nil {
+		resp.OpportunityID = dto.StringPtr(email.OpportunityID.String())
+	}
+	return resp
+}
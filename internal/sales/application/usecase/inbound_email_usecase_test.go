@@ -0,0 +1,409 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/kilang-desa-murni/crm/internal/sales/application"
+	"github.com/kilang-desa-murni/crm/internal/sales/application/dto"
+	"github.com/kilang-desa-murni/crm/internal/sales/application/ports"
+	"github.com/kilang-desa-murni/crm/internal/sales/domain"
+)
+
+// ============================================================================
+// Mock Implementations for Inbound Email Tests
+// ============================================================================
+
+// MockInboundMailboxRepository is a mock implementation of domain.InboundMailboxRepository.
+type MockInboundMailboxRepository struct {
+	mailboxes map[uuid.UUID]*domain.InboundMailbox
+}
+
+func NewMockInboundMailboxRepository() *MockInboundMailboxRepository {
+	return &MockInboundMailboxRepository{mailboxes: make(map[uuid.UUID]*domain.InboundMailbox)}
+}
+
+func (m *MockInboundMailboxRepository) Get(ctx context.Context, tenantID uuid.UUID) (*domain.InboundMailbox, error) {
+	return m.mailboxes[tenantID], nil
+}
+
+func (m *MockInboundMailboxRepository) GetByToken(ctx context.Context, token string) (*domain.InboundMailbox, error) {
+	for _, mailbox := range m.mailboxes {
+		if mailbox.Token == token {
+			return mailbox, nil
+		}
+	}
+	return nil, domain.ErrInboundMailboxNotFound
+}
+
+func (m *MockInboundMailboxRepository) Upsert(ctx context.Context, mailbox *domain.InboundMailbox) error {
+	m.mailboxes[mailbox.TenantID] = mailbox
+	return nil
+}
+
+// MockInboundEmailRepository is a mock implementation of domain.InboundEmailRepository.
+type MockInboundEmailRepository struct {
+	emails []*domain.InboundEmail
+}
+
+func (m *MockInboundEmailRepository) Create(ctx context.Context, email *domain.InboundEmail) error {
+	for _, e := range m.emails {
+		if e.TenantID == email.TenantID && e.MessageID == email.MessageID {
+			return domain.ErrInboundEmailDuplicate
+		}
+	}
+	m.emails = append(m.emails, email)
+	return nil
+}
+
+func (m *MockInboundEmailRepository) ExistsByMessageID(ctx context.Context, tenantID uuid.UUID, messageID string) (bool, error) {
+	for _, e := range m.emails {
+		if e.TenantID == tenantID && e.MessageID == messageID {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (m *MockInboundEmailRepository) CountFromSenderSince(ctx context.Context, tenantID uuid.UUID, fromAddress string, since time.Time) (int, error) {
+	count := 0
+	for _, e := range m.emails {
+		if e.TenantID == tenantID && e.FromAddress == fromAddress && !e.CreatedAt.Before(since) {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (m *MockInboundEmailRepository) ListByLead(ctx context.Context, tenantID, leadID uuid.UUID, opts domain.ListOptions) ([]*domain.InboundEmail, int64, error) {
+	var emails []*domain.InboundEmail
+	for _, e := range m.emails {
+		if e.TenantID == tenantID && e.LeadID != nil && *e.LeadID == leadID {
+			emails = append(emails, e)
+		}
+	}
+	return emails, int64(len(emails)), nil
+}
+
+// MockEmailParser returns a preset parsed email.
+type MockEmailParser struct {
+	parsed *ports.ParsedEmail
+	err    error
+}
+
+func (m *MockEmailParser) Parse(raw []byte) (*ports.ParsedEmail, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	parsed := *m.parsed
+	return &parsed, nil
+}
+
+const testInboundDomain = "leads.kilang.my"
+
+type inboundEmailFixture struct {
+	uc          InboundEmailUseCase
+	mailboxRepo *MockInboundMailboxRepository
+	emailRepo   *MockInboundEmailRepository
+	leadRepo    *MockLeadRepository
+	oppRepo     *ExtendedMockOpportunityRepository
+	parser      *MockEmailParser
+	storage     *MockFileStorageService
+	publisher   *MockPipelineEventPublisher
+	mailbox     *domain.InboundMailbox
+}
+
+func newInboundEmailFixture(t *testing.T) *inboundEmailFixture {
+	t.Helper()
+
+	f := &inboundEmailFixture{
+		mailboxRepo: NewMockInboundMailboxRepository(),
+		emailRepo:   &MockInboundEmailRepository{},
+		leadRepo:    NewMockLeadRepository(),
+		oppRepo:     NewExtendedMockOpportunityRepository(),
+		storage:     NewMockFileStorageService(),
+		publisher:   NewMockPipelineEventPublisher(),
+	}
+
+	mailbox, err := domain.NewInboundMailbox(uuid.New())
+	if err != nil {
+		t.Fatalf("NewInboundMailbox() error = %v", err)
+	}
+	mailbox.Enabled = true
+	f.mailbox = mailbox
+	f.mailboxRepo.mailboxes[mailbox.TenantID] = mailbox
+
+	f.parser = &MockEmailParser{parsed: &ports.ParsedEmail{
+		MessageID:   "<enquiry-1@butik.my>",
+		FromAddress: "Siti@Butik.my",
+		FromName:    "Siti Aminah",
+		Recipients:  []string{mailbox.Address(testInboundDomain)},
+		Subject:     "Corporate batik order",
+		Body:        "We would like 200 pieces for our annual dinner.",
+		Date:        time.Now().Add(-time.Minute),
+	}}
+
+	f.uc = NewInboundEmailUseCase(f.mailboxRepo, f.emailRepo, f.leadRepo, f.oppRepo, f.parser, f.storage, f.publisher, testInboundDomain)
+	return f
+}
+
+func (f *inboundEmailFixture) receive(t *testing.T) *dto.InboundEmailResultResponse {
+	t.Helper()
+	result, err := f.uc.Receive(context.Background(), &dto.ReceiveInboundEmailRequest{Raw: []byte("raw")})
+	if err != nil {
+		t.Fatalf("Receive() error = %v", err)
+	}
+	return result
+}
+
+func (f *inboundEmailFixture) publishedTypes() []string {
+	types := make([]string, len(f.publisher.events))
+	for i, event := range f.publisher.events {
+		types[i] = event.Type
+	}
+	return types
+}
+
+// ============================================================================
+// Inbound Email Use Case Tests
+// ============================================================================
+
+func TestInboundEmailUseCase_Receive_CreatesLead(t *testing.T) {
+	f := newInboundEmailFixture(t)
+	ownerID := uuid.New()
+	f.mailbox.DefaultOwnerID = &ownerID
+	f.parser.parsed.Attachments = []ports.EmailAttachment{
+		{Filename: "design.pdf", ContentType: "application/pdf", Content: []byte("%PDF")},
+		{Filename: "empty.txt", ContentType: "text/plain"},
+	}
+
+	result := f.receive(t)
+
+	if result.Status != "processed" || result.Email == nil || !result.Email.LeadCreated {
+		t.Fatalf("Receive() = %+v, want a processed email that created a lead", result)
+	}
+	if len(f.leadRepo.leads) != 1 {
+		t.Fatalf("lead count = %d, want 1", len(f.leadRepo.leads))
+	}
+	lead := f.leadRepo.leads[uuid.MustParse(*result.Email.LeadID)]
+	if lead.TenantID != f.mailbox.TenantID || lead.Source != domain.LeadSourceEmail {
+		t.Errorf("lead tenant/source = %s/%s, want %s/%s", lead.TenantID, lead.Source, f.mailbox.TenantID, domain.LeadSourceEmail)
+	}
+	if lead.Contact.Email != "siti@butik.my" || lead.Contact.FirstName != "Siti" || lead.Company.Name != "butik.my" {
+		t.Errorf("lead contact = %+v, company = %+v", lead.Contact, lead.Company)
+	}
+	if lead.OwnerID == nil || *lead.OwnerID != ownerID {
+		t.Errorf("lead owner = %v, want mailbox default owner %s", lead.OwnerID, ownerID)
+	}
+	if len(result.Email.AttachmentIDs) != 1 || len(f.storage.files) != 1 {
+		t.Errorf("stored %d attachments, want only the non-empty one", len(f.storage.files))
+	}
+	if len(f.emailRepo.emails) != 1 {
+		t.Errorf("recorded %d emails, want 1", len(f.emailRepo.emails))
+	}
+
+	types := f.publishedTypes()
+	if len(types) == 0 || types[len(types)-1] != "lead.email_received" {
+		t.Errorf("published events = %v, want lead.email_received last", types)
+	}
+}
+
+func TestInboundEmailUseCase_Receive_LogsOnExistingLead(t *testing.T) {
+	f := newInboundEmailFixture(t)
+	lead, err := domain.NewLead(f.mailbox.TenantID, domain.LeadContact{FirstName: "Siti", Email: "siti@butik.my"}, domain.LeadCompany{Name: "Butik"}, domain.LeadSourceWebsite, uuid.New())
+	if err != nil {
+		t.Fatalf("NewLead() error = %v", err)
+	}
+	opp := &domain.Opportunity{ID: uuid.New(), TenantID: f.mailbox.TenantID}
+	lead.ConversionInfo = &domain.ConversionInfo{OpportunityID: opp.ID}
+	f.leadRepo.leads[lead.ID] = lead
+	f.oppRepo.opportunities[opp.ID] = opp
+
+	result := f.receive(t)
+
+	if result.Status != "processed" || result.Email.LeadCreated {
+		t.Fatalf("Receive() = %+v, want an email logged on the existing lead", result)
+	}
+	if len(f.leadRepo.leads) != 1 || *result.Email.LeadID != lead.ID.String() {
+		t.Errorf("email lead = %s, want existing lead %s", *result.Email.LeadID, lead.ID)
+	}
+	if result.Email.OpportunityID == nil || *result.Email.OpportunityID != opp.ID.String() {
+		t.Errorf("email opportunity = %v, want %s", result.Email.OpportunityID, opp.ID)
+	}
+	if opp.LastActivityAt == nil {
+		t.Error("opportunity activity was not recorded")
+	}
+	if lead.Engagement.LastEngagement == nil {
+		t.Error("lead engagement was not recorded")
+	}
+}
+
+func TestInboundEmailUseCase_Receive_Skips(t *testing.T) {
+	tests := []struct {
+		name  string
+		setup func(f *inboundEmailFixture)
+		want  domain.InboundEmailSkipReason
+	}{
+		{
+			name: "auto reply",
+			setup: func(f *inboundEmailFixture) {
+				f.parser.parsed.Headers = map[string]string{"Auto-Submitted": "auto-replied"}
+			},
+			want: domain.InboundEmailSkipAutoReply,
+		},
+		{
+			name: "duplicate message",
+			setup: func(f *inboundEmailFixture) {
+				f.emailRepo.emails = append(f.emailRepo.emails, &domain.InboundEmail{TenantID: f.mailbox.TenantID, MessageID: f.parser.parsed.MessageID})
+			},
+			want: domain.InboundEmailSkipDuplicate,
+		},
+		{
+			name: "sender over rate limit",
+			setup: func(f *inboundEmailFixture) {
+				for i := 0; i < domain.MaxInboundEmailsPerSenderPerHour; i++ {
+					f.emailRepo.emails = append(f.emailRepo.emails, &domain.InboundEmail{
+						TenantID:    f.mailbox.TenantID,
+						MessageID:   uuid.NewString(),
+						FromAddress: "siti@butik.my",
+						CreatedAt:   time.Now().UTC(),
+					})
+				}
+			},
+			want: domain.InboundEmailSkipRateLimited,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newInboundEmailFixture(t)
+			tt.setup(f)
+
+			result := f.receive(t)
+			if result.Status != "skipped" || result.SkipReason != string(tt.want) {
+				t.Errorf("Receive() = %+v, want skipped with %q", result, tt.want)
+			}
+			if len(f.leadRepo.leads) != 0 || len(f.publisher.events) != 0 {
+				t.Error("skipped email touched leads or published events")
+			}
+		})
+	}
+}
+
+func TestInboundEmailUseCase_Receive_UnknownMailbox(t *testing.T) {
+	tests := []struct {
+		name  string
+		setup func(f *inboundEmailFixture)
+	}{
+		{"disabled mailbox", func(f *inboundEmailFixture) { f.mailbox.Enabled = false }},
+		{"unknown address", func(f *inboundEmailFixture) { f.parser.parsed.Recipients = []string{"deadbeef@" + testInboundDomain} }},
+		{"other domain", func(f *inboundEmailFixture) { f.parser.parsed.Recipients = []string{"sales@kilang.my"} }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newInboundEmailFixture(t)
+			tt.setup(f)
+
+			_, err := f.uc.Receive(context.Background(), &dto.ReceiveInboundEmailRequest{Raw: []byte("raw")})
+			if appErr := application.GetAppError(err); appErr == nil || appErr.Code != application.ErrCodeInboundMailboxNotFound {
+				t.Errorf("Receive() error = %v, want %s", err, application.ErrCodeInboundMailboxNotFound)
+			}
+		})
+	}
+}
+
+func TestInboundEmailUseCase_Receive_EnvelopeRecipientWins(t *testing.T) {
+	f := newInboundEmailFixture(t)
+	f.parser.parsed.Recipients = []string{"sales@butik.my"}
+
+	result, err := f.uc.Receive(context.Background(), &dto.ReceiveInboundEmailRequest{
+		Recipient: f.mailbox.Address(testInboundDomain),
+		Raw:       []byte("raw"),
+	})
+	if err != nil {
+		t.Fatalf("Receive() error = %v", err)
+	}
+	if result.Status != "processed" {
+		t.Errorf("Receive() status = %q, want processed", result.Status)
+	}
+}
+
+func TestInboundEmailUseCase_Receive_InvalidEmail(t *testing.T) {
+	f := newInboundEmailFixture(t)
+	f.parser.err = errors.New("malformed header")
+
+	_, err := f.uc.Receive(context.Background(), &dto.ReceiveInboundEmailRequest{Raw: []byte("raw")})
+	if !application.IsValidationError(err) {
+		t.Errorf("Receive() error = %v, want validation error", err)
+	}
+}
+
+func TestInboundEmailUseCase_Receive_NotConfigured(t *testing.T) {
+	f := newInboundEmailFixture(t)
+	uc := NewInboundEmailUseCase(f.mailboxRepo, f.emailRepo, f.leadRepo, f.oppRepo, f.parser, f.storage, f.publisher, "")
+
+	_, err := uc.Receive(context.Background(), &dto.ReceiveInboundEmailRequest{Raw: []byte("raw")})
+	if appErr := application.GetAppError(err); appErr == nil || appErr.Code != application.ErrCodeServiceUnavailable {
+		t.Errorf("Receive() error = %v, want %s", err, application.ErrCodeServiceUnavailable)
+	}
+}
+
+func TestInboundEmailUseCase_Mailbox(t *testing.T) {
+	f := newInboundEmailFixture(t)
+	ctx := context.Background()
+	tenantID := uuid.New()
+
+	mailbox, err := f.uc.GetMailbox(ctx, tenantID)
+	if err != nil {
+		t.Fatalf("GetMailbox() error = %v", err)
+	}
+	if mailbox.Enabled || mailbox.Address == "" {
+		t.Errorf("GetMailbox() = %+v, want a disabled mailbox with an address", mailbox)
+	}
+
+	enabled := true
+	ownerID := uuid.NewString()
+	updated, err := f.uc.UpdateMailbox(ctx, tenantID, &dto.UpdateInboundMailboxRequest{
+		Enabled:        &enabled,
+		DefaultOwnerID: &ownerID,
+		RotateAddress:  true,
+	})
+	if err != nil {
+		t.Fatalf("UpdateMailbox() error = %v", err)
+	}
+	if !updated.Enabled || updated.Address == mailbox.Address {
+		t.Errorf("UpdateMailbox() = %+v, want an enabled mailbox with a new address", updated)
+	}
+	if updated.DefaultOwnerID == nil || *updated.DefaultOwnerID != ownerID {
+		t.Errorf("UpdateMailbox() owner = %v, want %s", updated.DefaultOwnerID, ownerID)
+	}
+
+	invalid := "not-a-uuid"
+	if _, err := f.uc.UpdateMailbox(ctx, tenantID, &dto.UpdateInboundMailboxRequest{DefaultOwnerID: &invalid}); !application.IsValidationError(err) {
+		t.Errorf("UpdateMailbox() error = %v, want validation error", err)
+	}
+}
+
+func TestInboundEmailUseCase_ListLeadEmails(t *testing.T) {
+	f := newInboundEmailFixture(t)
+	result := f.receive(t)
+	leadID := uuid.MustParse(*result.Email.LeadID)
+
+	list, err := f.uc.ListLeadEmails(context.Background(), f.mailbox.TenantID, leadID, &dto.ListInboundEmailsRequest{})
+	if err != nil {
+		t.Fatalf("ListLeadEmails() error = %v", err)
+	}
+	if len(list.Emails) != 1 || list.Emails[0].Subject != "Corporate batik order" {
+		t.Errorf("ListLeadEmails() = %+v, want the received email", list.Emails)
+	}
+
+	_, err = f.uc.ListLeadEmails(context.Background(), f.mailbox.TenantID, uuid.New(), &dto.ListInboundEmailsRequest{})
+	if !application.IsNotFoundError(err) {
+		t.Errorf("ListLeadEmails() error = %v, want not found", err)
+	}
+}
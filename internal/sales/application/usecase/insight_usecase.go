@@ -0,0 +1,368 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/kilang-desa-murni/crm/internal/sales/application"
+	"github.com/kilang-desa-murni/crm/internal/sales/application/dto"
+	"github.com/kilang-desa-murni/crm/internal/sales/application/ports"
+	"github.com/kilang-desa-murni/crm/internal/sales/domain"
+)
+
+// Insight check defaults
+const (
+	// insightActivityWindow is how recently a tenant must have changed leads
+	// or opportunities for the scheduled checks to look at it.
+	insightActivityWindow = 14 * 24 * time.Hour
+	// defaultInsightListLimit is the number of insights listed by default.
+	defaultInsightListLimit = 20
+	// maxInsightListLimit is the most insights listed at once.
+	maxInsightListLimit = 100
+)
+
+// ============================================================================
+// Insight Use Case Interface
+// ============================================================================
+
+// InsightUseCase defines the interface for the insights engine, which raises
+// an event when something notable happens in a tenant's sales. Events are
+// delivered to the tenant's chat integrations by the notification service.
+type InsightUseCase interface {
+	// Settings
+	GetSettings(ctx context.Context, tenantID uuid.UUID) (*dto.InsightSettingsResponse, error)
+	UpdateSettings(ctx context.Context, tenantID, userID uuid.UUID, req *dto.UpdateInsightSettingsRequest) (*dto.InsightSettingsResponse, error)
+
+	// ListInsights returns a tenant's most recent insights.
+	ListInsights(ctx context.Context, tenantID uuid.UUID, req *dto.ListInsightsRequest) (*dto.InsightListResponse, error)
+
+	// EvaluateWonOpportunity checks whether a won opportunity is the biggest
+	// deal of its quarter.
+	EvaluateWonOpportunity(ctx context.Context, tenantID, opportunityID uuid.UUID) error
+
+	// RunScheduledChecks checks pipeline coverage and lead volume for every
+	// active tenant.
+	RunScheduledChecks(ctx context.Context, now time.Time) (*dto.InsightRunResponse, error)
+}
+
+// ============================================================================
+// Insight Use Case Implementation
+// ============================================================================
+
+// insightUseCase implements InsightUseCase.
+type insightUseCase struct {
+	insightRepo     domain.InsightRepository
+	opportunityRepo domain.OpportunityRepository
+	eventPublisher  ports.EventPublisher
+}
+
+// NewInsightUseCase creates a new insight use case.
+func NewInsightUseCase(
+	insightRepo domain.InsightRepository,
+	opportunityRepo domain.OpportunityRepository,
+	eventPublisher ports.EventPublisher,
+) InsightUseCase {
+	return &insightUseCase{
+		insightRepo:     insightRepo,
+		opportunityRepo: opportunityRepo,
+		eventPublisher:  eventPublisher,
+	}
+}
+
+// ============================================================================
+// Settings
+// ============================================================================
+
+// GetSettings returns the tenant's insight settings, or the defaults if none are configured.
+func (uc *insightUseCase) GetSettings(ctx context.Context, tenantID uuid.UUID) (*dto.InsightSettingsResponse, error) {
+	settings, err := uc.loadSettings(ctx, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	return mapInsightSettingsToResponse(settings), nil
+}
+
+// UpdateSettings updates the tenant's insight settings.
+func (uc *insightUseCase) UpdateSettings(ctx context.Context, tenantID, userID uuid.UUID, req *dto.UpdateInsightSettingsRequest) (*dto.InsightSettingsResponse, error) {
+	settings, err := uc.loadSettings(ctx, tenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.BiggestDealWon != nil {
+		settings.BiggestDealWon = *req.BiggestDealWon
+	}
+	if req.LowPipelineCoverage != nil {
+		settings.LowPipelineCoverage = *req.LowPipelineCoverage
+	}
+	if req.LeadVolumeDrop != nil {
+		settings.LeadVolumeDrop = *req.LeadVolumeDrop
+	}
+	if req.Currency != nil {
+		settings.Currency = strings.ToUpper(strings.TrimSpace(*req.Currency))
+	}
+	if req.QuarterlyQuota != nil {
+		settings.QuarterlyQuota = *req.QuarterlyQuota
+	}
+	if req.MinimumCoverage != nil {
+		settings.MinimumCoverage = *req.MinimumCoverage
+	}
+	if req.LeadDropPercent != nil {
+		settings.LeadDropPercent = *req.LeadDropPercent
+	}
+	if req.MinimumWeeklyLeads != nil {
+		settings.MinimumWeeklyLeads = *req.MinimumWeeklyLeads
+	}
+
+	if err := settings.Validate(); err != nil {
+		return nil, application.ErrValidation(err.Error())
+	}
+
+	settings.UpdatedBy = &userID
+	settings.UpdatedAt = time.Now().UTC()
+	if err := uc.insightRepo.UpsertSettings(ctx, settings); err != nil {
+		return nil, application.WrapError(application.ErrCodeInternal, "failed to save insight settings", err)
+	}
+
+	return mapInsightSettingsToResponse(settings), nil
+}
+
+func (uc *insightUseCase) loadSettings(ctx context.Context, tenantID uuid.UUID) (*domain.InsightSettings, error) {
+	settings, err := uc.insightRepo.GetSettings(ctx, tenantID)
+	if err != nil {
+		return nil, application.WrapError(application.ErrCodeInternal, "failed to get insight settings", err)
+	}
+	if settings == nil {
+		return domain.DefaultInsightSettings(tenantID), nil
+	}
+	return settings, nil
+}
+
+// ============================================================================
+// Insights
+// ============================================================================
+
+// ListInsights returns a tenant's most recent insights, optionally of one type.
+func (uc *insightUseCase) ListInsights(ctx context.Context, tenantID uuid.UUID, req *dto.ListInsightsRequest) (*dto.InsightListResponse, error) {
+	var insightType *domain.InsightType
+	if req.Type != "" {
+		t := domain.InsightType(req.Type)
+		if !isValidInsightType(t) {
+			return nil, application.ErrValidationWithDetails("invalid insight type", map[string]interface{}{
+				"type": req.Type,
+			})
+		}
+		insightType = &t
+	}
+	limit := req.Limit
+	if limit <= 0 || limit > maxInsightListLimit {
+		limit = defaultInsightListLimit
+	}
+
+	insights, err := uc.insightRepo.List(ctx, tenantID, insightType, limit)
+	if err != nil {
+		return nil, application.WrapError(application.ErrCodeInternal, "failed to list insights", err)
+	}
+
+	resp := &dto.InsightListResponse{Insights: make([]*dto.InsightResponse, len(insights))}
+	for i, insight := range insights {
+		resp.Insights[i] = mapInsightToResponse(insight)
+	}
+	return resp, nil
+}
+
+// EvaluateWonOpportunity checks a won opportunity against the deals won
+// earlier in its quarter. Opportunities that are no longer won, such as
+// reopened ones, are ignored.
+func (uc *insightUseCase) EvaluateWonOpportunity(ctx context.Context, tenantID, opportunityID uuid.UUID) error {
+	opportunity, err := uc.opportunityRepo.GetByID(ctx, tenantID, opportunityID)
+	if err != nil {
+		if errors.Is(err, domain.ErrOpportunityNotFound) {
+			return nil
+		}
+		return application.WrapError(application.ErrCodeInternal, "failed to get opportunity", err)
+	}
+	if opportunity.Status != domain.OpportunityStatusWon {
+		return nil
+	}
+
+	settings, err := uc.loadSettings(ctx, tenantID)
+	if err != nil {
+		return err
+	}
+	if !settings.IsEnabled(domain.InsightTypeBiggestDealWon) {
+		return nil
+	}
+
+	now := time.Now().UTC()
+	closedAt := now
+	if opportunity.CloseInfo != nil {
+		closedAt = opportunity.CloseInfo.ClosedAt
+	}
+	previousBest, err := uc.insightRepo.GetBiggestWonAmount(ctx, tenantID, settings.Currency, domain.QuarterOf(closedAt), opportunity.ID)
+	if err != nil {
+		return application.WrapError(application.ErrCodeInternal, "failed to get biggest won deal", err)
+	}
+
+	_, err = uc.raise(ctx, domain.DetectBiggestDealWon(settings, opportunity, previousBest, now))
+	return err
+}
+
+// RunScheduledChecks checks the pipeline coverage of the current quarter and
+// the lead volume of the last complete week for every tenant active in the
+// past two weeks. A failing tenant does not stop the others from being checked.
+func (uc *insightUseCase) RunScheduledChecks(ctx context.Context, now time.Time) (*dto.InsightRunResponse, error) {
+	now = now.UTC()
+	tenantIDs, err := uc.insightRepo.ListActiveTenants(ctx, now.Add(-insightActivityWindow))
+	if err != nil {
+		return nil, application.WrapError(application.ErrCodeInternal, "failed to list active tenants", err)
+	}
+
+	resp := &dto.InsightRunResponse{Tenants: len(tenantIDs), RanAt: now}
+	var firstErr error
+	for _, tenantID := range tenantIDs {
+		detected, err := uc.checkTenant(ctx, tenantID, now)
+		resp.Detected += detected
+		if err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+
+	return resp, firstErr
+}
+
+// checkTenant runs the scheduled checks for one tenant, returning the number
+// of new insights raised.
+func (uc *insightUseCase) checkTenant(ctx context.Context, tenantID uuid.UUID, now time.Time) (int, error) {
+	settings, err := uc.loadSettings(ctx, tenantID)
+	if err != nil {
+		return 0, err
+	}
+
+	var insights []*domain.Insight
+	if settings.IsEnabled(domain.InsightTypeLowPipelineCoverage) {
+		won, open, err := uc.insightRepo.GetQuarterPipeline(ctx, tenantID, settings.Currency, domain.QuarterOf(now))
+		if err != nil {
+			return 0, application.WrapError(application.ErrCodeInternal, "failed to get quarter pipeline", err)
+		}
+		insights = append(insights, domain.DetectLowPipelineCoverage(settings, won, open, now))
+	}
+	if settings.IsEnabled(domain.InsightTypeLeadVolumeDrop) {
+		// Compare the last complete week with the one before it
+		week := domain.WeekOf(domain.WeekOf(now).From.AddDate(0, 0, -7))
+		previousWeek := domain.WeekOf(week.From.AddDate(0, 0, -7))
+		leads, err := uc.insightRepo.CountLeads(ctx, tenantID, week)
+		if err != nil {
+			return 0, application.WrapError(application.ErrCodeInternal, "failed to count leads", err)
+		}
+		previousLeads, err := uc.insightRepo.CountLeads(ctx, tenantID, previousWeek)
+		if err != nil {
+			return 0, application.WrapError(application.ErrCodeInternal, "failed to count leads", err)
+		}
+		insights = append(insights, domain.DetectLeadVolumeDrop(settings, week, leads, previousLeads, now))
+	}
+
+	detected := 0
+	for _, insight := range insights {
+		raised, err := uc.raise(ctx, insight)
+		if err != nil {
+			return detected, err
+		}
+		if raised {
+			detected++
+		}
+	}
+	return detected, nil
+}
+
+// raise records an insight and publishes its event. Insights already
+// recorded for the same period are not published again, so repeated checks
+// alert once.
+func (uc *insightUseCase) raise(ctx context.Context, insight *domain.Insight) (bool, error) {
+	if insight == nil {
+		return false, nil
+	}
+
+	recorded, err := uc.insightRepo.Record(ctx, insight)
+	if err != nil {
+		return false, application.WrapError(application.ErrCodeInternal, "failed to record insight", err)
+	}
+	if !recorded {
+		return false, nil
+	}
+
+	uc.publishEvent(ctx, domain.NewInsightDetectedEvent(insight))
+	return true, nil
+}
+
+func (uc *insightUseCase) publishEvent(ctx context.Context, event domain.DomainEvent) {
+	if uc.eventPublisher == nil {
+		return
+	}
+
+	var payload map[string]interface{}
+	if data, err := json.Marshal(event); err == nil {
+		json.Unmarshal(data, &payload)
+	}
+
+	uc.eventPublisher.Publish(ctx, ports.Event{
+		ID:            event.EventID().String(),
+		Type:          event.EventType(),
+		AggregateID:   event.AggregateID().String(),
+		AggregateType: event.AggregateType(),
+		TenantID:      event.TenantID().String(),
+		Payload:       payload,
+		OccurredAt:    event.OccurredAt(),
+		Version:       event.Version(),
+	})
+}
+
+func isValidInsightType(insightType domain.InsightType) bool {
+	for _, t := range domain.ValidInsightTypes() {
+		if t == insightType {
+			return true
+		}
+	}
+	return false
+}
+
+// ============================================================================
+// Mapping Functions
+// ============================================================================
+
+func mapInsightSettingsToResponse(settings *domain.InsightSettings) *dto.InsightSettingsResponse {
+	resp := &dto.InsightSettingsResponse{
+		BiggestDealWon:      settings.BiggestDealWon,
+		LowPipelineCoverage: settings.LowPipelineCoverage,
+		LeadVolumeDrop:      settings.LeadVolumeDrop,
+		Currency:            settings.Currency,
+		QuarterlyQuota:      settings.QuarterlyQuota,
+		MinimumCoverage:     settings.MinimumCoverage,
+		LeadDropPercent:     settings.LeadDropPercent,
+		MinimumWeeklyLeads:  settings.MinimumWeeklyLeads,
+	}
+	if !settings.UpdatedAt.IsZero() {
+		updatedAt := settings.UpdatedAt
+		resp.UpdatedAt = &updatedAt
+	}
+	return resp
+}
+
+func mapInsightToResponse(insight *domain.Insight) *dto.InsightResponse {
+	var details map[string]interface{}
+	if data, err := json.Marshal(insight.Details); err == nil {
+		json.Unmarshal(data, &details)
+	}
+	return &dto.InsightResponse{
+		ID:         insight.ID.String(),
+		Type:       string(insight.Type),
+		PeriodKey:  insight.PeriodKey,
+		Details:    details,
+		DetectedAt: insight.DetectedAt,
+	}
+}
@@ -0,0 +1,182 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/kilang-desa-murni/crm/internal/sales/application"
+	"github.com/kilang-desa-murni/crm/internal/sales/application/dto"
+	"github.com/kilang-desa-murni/crm/internal/sales/domain"
+)
+
+// ============================================================================
+// Mock Implementations for Insight Tests
+// ============================================================================
+
+// MockInsightRepository is a mock implementation of domain.InsightRepository.
+type MockInsightRepository struct {
+	settings     map[uuid.UUID]*domain.InsightSettings
+	insights     []*domain.Insight
+	tenants      []uuid.UUID
+	biggestWon   int64
+	won          int64
+	open         int64
+	leadsByStart map[time.Time]int64
+}
+
+func NewMockInsightRepository() *MockInsightRepository {
+	return &MockInsightRepository{
+		settings:     make(map[uuid.UUID]*domain.InsightSettings),
+		leadsByStart: make(map[time.Time]int64),
+	}
+}
+
+func (m *MockInsightRepository) GetSettings(ctx context.Context, tenantID uuid.UUID) (*domain.InsightSettings, error) {
+	return m.settings[tenantID], nil
+}
+
+func (m *MockInsightRepository) UpsertSettings(ctx context.Context, settings *domain.InsightSettings) error {
+	m.settings[settings.TenantID] = settings
+	return nil
+}
+
+func (m *MockInsightRepository) Record(ctx context.Context, insight *domain.Insight) (bool, error) {
+	for _, existing := range m.insights {
+		if existing.TenantID == insight.TenantID && existing.Type == insight.Type && existing.PeriodKey == insight.PeriodKey {
+			return false, nil
+		}
+	}
+	m.insights = append(m.insights, insight)
+	return true, nil
+}
+
+func (m *MockInsightRepository) List(ctx context.Context, tenantID uuid.UUID, insightType *domain.InsightType, limit int) ([]*domain.Insight, error) {
+	var insights []*domain.Insight
+	for _, insight := range m.insights {
+		if insight.TenantID == tenantID && (insightType == nil || insight.Type == *insightType) {
+			insights = append(insights, insight)
+		}
+	}
+	return insights, nil
+}
+
+func (m *MockInsightRepository) ListActiveTenants(ctx context.Context, since time.Time) ([]uuid.UUID, error) {
+	return m.tenants, nil
+}
+
+func (m *MockInsightRepository) GetBiggestWonAmount(ctx context.Context, tenantID uuid.UUID, currency string, period domain.ReportPeriod, excludeID uuid.UUID) (int64, error) {
+	return m.biggestWon, nil
+}
+
+func (m *MockInsightRepository) GetQuarterPipeline(ctx context.Context, tenantID uuid.UUID, currency string, quarter domain.ReportPeriod) (int64, int64, error) {
+	return m.won, m.open, nil
+}
+
+func (m *MockInsightRepository) CountLeads(ctx context.Context, tenantID uuid.UUID, period domain.ReportPeriod) (int64, error) {
+	return m.leadsByStart[period.From], nil
+}
+
+// ============================================================================
+// Insight Use Case Tests
+// ============================================================================
+
+func TestInsightUseCase_UpdateSettings(t *testing.T) {
+	repo := NewMockInsightRepository()
+	uc := NewInsightUseCase(repo, nil, nil)
+	tenantID := uuid.New()
+
+	coverage := -1.0
+	_, err := uc.UpdateSettings(context.Background(), tenantID, uuid.New(), &dto.UpdateInsightSettingsRequest{MinimumCoverage: &coverage})
+	if appErr := application.GetAppError(err); appErr == nil || appErr.Code != application.ErrCodeValidation {
+		t.Fatalf("UpdateSettings() negative coverage error = %v, want validation error", err)
+	}
+
+	quota := int64(50000000)
+	currency := " sgd "
+	resp, err := uc.UpdateSettings(context.Background(), tenantID, uuid.New(), &dto.UpdateInsightSettingsRequest{
+		QuarterlyQuota: &quota,
+		Currency:       &currency,
+	})
+	if err != nil {
+		t.Fatalf("UpdateSettings() error = %v", err)
+	}
+	if resp.QuarterlyQuota != quota || resp.Currency != "SGD" || resp.MinimumCoverage != domain.DefaultMinimumCoverage {
+		t.Errorf("UpdateSettings() = %+v, want the quota in SGD and default coverage", resp)
+	}
+	if saved := repo.settings[tenantID]; saved == nil || saved.UpdatedBy == nil {
+		t.Error("UpdateSettings() did not save who updated the settings")
+	}
+}
+
+func TestInsightUseCase_EvaluateWonOpportunity(t *testing.T) {
+	tenantID := uuid.New()
+	repo := NewMockInsightRepository()
+	repo.biggestWon = 8000000
+	oppRepo := NewMockOpportunityRepository()
+	publisher := NewMockSalesEventPublisher()
+	uc := NewInsightUseCase(repo, oppRepo, publisher)
+
+	opp := &domain.Opportunity{
+		ID:        uuid.New(),
+		TenantID:  tenantID,
+		Status:    domain.OpportunityStatusWon,
+		Amount:    domain.Money{Amount: 12000000, Currency: "MYR"},
+		CloseInfo: &domain.CloseInfo{ClosedAt: time.Now().UTC()},
+	}
+	oppRepo.opportunities[opp.ID] = opp
+
+	for i := 0; i < 2; i++ {
+		if err := uc.EvaluateWonOpportunity(context.Background(), tenantID, opp.ID); err != nil {
+			t.Fatalf("EvaluateWonOpportunity() error = %v", err)
+		}
+	}
+
+	if len(repo.insights) != 1 || repo.insights[0].Type != domain.InsightTypeBiggestDealWon {
+		t.Fatalf("EvaluateWonOpportunity() recorded %d insights, want one biggest deal", len(repo.insights))
+	}
+	if len(publisher.events) != 1 || publisher.events[0].Type != "insight.biggest_deal_won" {
+		t.Errorf("EvaluateWonOpportunity() published %d events, want one insight.biggest_deal_won", len(publisher.events))
+	}
+}
+
+func TestInsightUseCase_RunScheduledChecks(t *testing.T) {
+	tenantID := uuid.New()
+	repo := NewMockInsightRepository()
+	repo.tenants = []uuid.UUID{tenantID}
+	settings := domain.DefaultInsightSettings(tenantID)
+	settings.QuarterlyQuota = 100000000
+	repo.settings[tenantID] = settings
+	repo.won = 50000000
+	repo.open = 60000000
+
+	now := time.Date(2026, 10, 19, 6, 0, 0, 0, time.UTC)
+	repo.leadsByStart[time.Date(2026, 10, 12, 0, 0, 0, 0, time.UTC)] = 20
+	repo.leadsByStart[time.Date(2026, 10, 5, 0, 0, 0, 0, time.UTC)] = 50
+
+	publisher := NewMockSalesEventPublisher()
+	uc := NewInsightUseCase(repo, nil, publisher)
+
+	resp, err := uc.RunScheduledChecks(context.Background(), now)
+	if err != nil {
+		t.Fatalf("RunScheduledChecks() error = %v", err)
+	}
+	if resp.Tenants != 1 || resp.Detected != 2 {
+		t.Fatalf("RunScheduledChecks() = %d tenants, %d detected; want 1 and 2", resp.Tenants, resp.Detected)
+	}
+
+	resp, err = uc.RunScheduledChecks(context.Background(), now.Add(time.Hour))
+	if err != nil || resp.Detected != 0 {
+		t.Errorf("RunScheduledChecks() rerun detected %d (error %v), want none", resp.Detected, err)
+	}
+	if len(publisher.events) != 2 {
+		t.Errorf("RunScheduledChecks() published %d events, want 2", len(publisher.events))
+	}
+
+	list, err := uc.ListInsights(context.Background(), tenantID, &dto.ListInsightsRequest{Type: string(domain.InsightTypeLeadVolumeDrop)})
+	if err != nil || len(list.Insights) != 1 || list.Insights[0].Details["previous_leads"] != float64(50) {
+		t.Errorf("ListInsights() = %+v (error %v), want the lead drop from 50 leads", list, err)
+	}
+}
@@ -2,6 +2,8 @@ package usecase
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"time"
 
@@ -41,6 +43,9 @@ type LeadUseCase interface {
 
 	// Statistics
 	GetStatistics(ctx context.Context, tenantID uuid.UUID) (*dto.LeadStatisticsResponse, error)
+
+	// Duplicates
+	MergeLeads(ctx context.Context, tenantID, userID uuid.UUID, req *dto.MergeLeadsRequest) (*dto.LeadResponse, error)
 }
 
 // ============================================================================
@@ -58,6 +63,8 @@ type leadUseCase struct {
 	cacheService    ports.CacheService
 	searchService   ports.SearchService
 	idGenerator     ports.IDGenerator
+	conversion      LeadConversionOrchestrator
+	customerMatcher ports.CustomerMatcher
 }
 
 // NewLeadUseCase creates a new lead use case.
@@ -71,6 +78,8 @@ func NewLeadUseCase(
 	cacheService ports.CacheService,
 	searchService ports.SearchService,
 	idGenerator ports.IDGenerator,
+	conversion LeadConversionOrchestrator,
+	customerMatcher ports.CustomerMatcher,
 ) LeadUseCase {
 	return &leadUseCase{
 		leadRepo:        leadRepo,
@@ -82,6 +91,8 @@ func NewLeadUseCase(
 		cacheService:    cacheService,
 		searchService:   searchService,
 		idGenerator:     idGenerator,
+		conversion:      conversion,
+		customerMatcher: customerMatcher,
 	}
 }
 
@@ -145,6 +156,15 @@ func (uc *leadUseCase) Create(ctx context.Context, tenantID, userID uuid.UUID, r
 	}
 
 	// Set optional fields
+	lead.Attribution = domain.NewAttribution(domain.Attribution{
+		UTMSource:   dto.StringValue(req.UTMSource),
+		UTMMedium:   dto.StringValue(req.UTMMedium),
+		UTMCampaign: dto.StringValue(req.UTMCampaign),
+		UTMTerm:     dto.StringValue(req.UTMTerm),
+		UTMContent:  dto.StringValue(req.UTMContent),
+		Referrer:    dto.StringValue(req.Referrer),
+		LandingPage: dto.StringValue(req.LandingPage),
+	})
 	if req.Description != nil {
 		lead.Description = *req.Description
 	}
@@ -159,7 +179,7 @@ func (uc *leadUseCase) Create(ctx context.Context, tenantID, userID uuid.UUID, r
 		if err != nil {
 			return nil, application.ErrValidation("invalid budget currency: " + err.Error())
 		}
-		lead.EstimatedValue = money
+		lead.SetEstimatedValue(money)
 	}
 
 	// Handle owner assignment
@@ -178,6 +198,13 @@ func (uc *leadUseCase) Create(ctx context.Context, tenantID, userID uuid.UUID, r
 		}
 	}
 
+	// Hold back leads matching existing leads or customers, unless forced
+	if !req.Force {
+		if matches := uc.findMatches(ctx, tenantID, lead); len(matches) > 0 {
+			return nil, application.ErrLeadPotentialDuplicate(len(matches)).WithDetail("matches", matches)
+		}
+	}
+
 	// Generate code
 	if uc.idGenerator != nil {
 		code, err := uc.idGenerator.GenerateLeadNumber(ctx, tenantID)
@@ -385,6 +412,16 @@ func (uc *leadUseCase) List(ctx context.Context, tenantID uuid.UUID, filter *dto
 	if opts.SortOrder == "" {
 		opts.SortOrder = "desc"
 	}
+	expr, err := leadListQuery.parse(filter.Filter, filter.Sort, &opts)
+	if err != nil {
+		return nil, err
+	}
+	domainFilter.Expr = expr
+	after, err := leadCursorSorts.after(filter.Cursor, opts.SortBy, opts.SortOrder)
+	if err != nil {
+		return nil, err
+	}
+	opts.After = after
 
 	// Get leads
 	leads, total, err := uc.leadRepo.List(ctx, tenantID, domainFilter, opts)
@@ -401,6 +438,7 @@ func (uc *leadUseCase) List(ctx context.Context, tenantID uuid.UUID, filter *dto
 	for _, lead := range leads {
 		response.Leads = append(response.Leads, uc.mapLeadToBriefResponse(lead))
 	}
+	leadCursorSorts.paginate(&response.Pagination, leads, pageSize, opts, func(l *domain.Lead) uuid.UUID { return l.ID })
 
 	return response, nil
 }
@@ -456,119 +494,113 @@ func (uc *leadUseCase) Disqualify(ctx context.Context, tenantID, leadID, userID
 	return uc.mapLeadToResponse(lead), nil
 }
 
-// Convert converts a lead to an opportunity.
+// Convert converts a lead into an opportunity, creating or linking the
+// customer and contact on the way. The steps run as a saga: when one fails,
+// the steps already done are undone.
 func (uc *leadUseCase) Convert(ctx context.Context, tenantID, leadID, userID uuid.UUID, req *dto.ConvertLeadRequest) (*dto.LeadConversionResponse, error) {
-	// Get lead
-	lead, err := uc.leadRepo.GetByID(ctx, tenantID, leadID)
+	request, err := buildLeadConversionRequest(leadID, req)
 	if err != nil {
-		return nil, application.ErrLeadNotFound(leadID)
+		return nil, err
 	}
 
-	if !lead.CanConvert() {
-		return nil, application.ErrConflict("lead cannot be converted - must be qualified first")
+	saga, err := uc.conversion.Convert(ctx, tenantID, leadID, userID, request)
+	if err != nil {
+		if appErr := application.GetAppError(err); appErr != nil {
+			return nil, appErr
+		}
+		if saga != nil && saga.State == domain.SagaStateCompensated {
+			return nil, application.ErrInternal("lead conversion failed and was rolled back", err)
+		}
+		return nil, application.ErrInternal("failed to convert lead", err)
 	}
 
-	// Get pipeline
+	resp := &dto.LeadConversionResponse{
+		LeadID:  leadID.String(),
+		Message: "Lead converted successfully",
+	}
+	if saga.OpportunityID != nil {
+		resp.OpportunityID = saga.OpportunityID.String()
+	}
+	if saga.CustomerID != nil {
+		resp.CustomerID = dto.StringPtr(saga.CustomerID.String())
+	}
+	if saga.ContactID != nil {
+		resp.ContactID = dto.StringPtr(saga.ContactID.String())
+	}
+	return resp, nil
+}
+
+// buildLeadConversionRequest validates a conversion request and parses it
+// into the saga's request.
+func buildLeadConversionRequest(leadID uuid.UUID, req *dto.ConvertLeadRequest) (*domain.LeadConversionRequest, error) {
 	pipelineID, err := uuid.Parse(req.PipelineID)
 	if err != nil {
 		return nil, application.ErrValidation("invalid pipeline ID")
 	}
 
-	pipeline, err := uc.pipelineRepo.GetByID(ctx, tenantID, pipelineID)
-	if err != nil {
-		return nil, application.ErrPipelineNotFound(pipelineID)
+	request := &domain.LeadConversionRequest{
+		LeadID:            leadID,
+		PipelineID:        pipelineID,
+		CustomerName:      req.CustomerName,
+		CreateNewCustomer: req.CreateNewCustomer,
+		CreateNewContact:  req.CreateNewContact,
+		Description:       req.Description,
+		Probability:       req.Probability,
+	}
+	if req.OpportunityName != "" {
+		request.OpportunityName = &req.OpportunityName
 	}
 
-	// Determine customer
-	var customerID uuid.UUID
-	customerName := lead.Company.Name
-	if req.CustomerID != nil {
-		customerID, err = uuid.Parse(*req.CustomerID)
+	// The opportunity needs a customer: an existing one or one created now
+	switch {
+	case req.CustomerID != nil && req.CreateNewCustomer:
+		return nil, application.ErrValidation("customer_id and create_new_customer cannot both be set")
+	case req.CustomerID != nil:
+		customerID, err := uuid.Parse(*req.CustomerID)
 		if err != nil {
 			return nil, application.ErrValidation("invalid customer ID")
 		}
-		if req.CustomerName != nil {
-			customerName = *req.CustomerName
-		}
-	} else if req.CreateNewCustomer && uc.customerService != nil {
-		// Create new customer - use pointer types for optional fields
-		email := lead.Contact.Email
-		phone := lead.Contact.Phone
-		website := lead.Company.Website
+		request.CustomerID = &customerID
+	case !req.CreateNewCustomer:
+		return nil, application.ErrValidation("customer_id or create_new_customer is required")
+	}
 
-		createReq := ports.CreateCustomerRequest{
-			Name:   lead.Company.Name,
-			Type:   "business",
-			Source: "lead_conversion",
-		}
-		if email != "" {
-			createReq.Email = &email
+	if req.ContactID != nil {
+		if req.CreateNewContact {
+			return nil, application.ErrValidation("contact_id and create_new_contact cannot both be set")
 		}
-		if phone != "" {
-			createReq.Phone = &phone
-		}
-		if website != "" {
-			createReq.Website = &website
-		}
-
-		newCustomer, err := uc.customerService.CreateCustomer(ctx, tenantID, createReq)
+		contactID, err := uuid.Parse(*req.ContactID)
 		if err != nil {
-			return nil, application.ErrInternal("failed to create customer", err)
+			return nil, application.ErrValidation("invalid contact ID")
 		}
-		customerID = newCustomer.ID
-		customerName = newCustomer.Name
+		request.ContactID = &contactID
 	}
 
-	// Get owner
-	ownerID := userID
-	ownerName := ""
 	if req.OwnerID != nil {
-		ownerID, _ = uuid.Parse(*req.OwnerID)
-	} else if lead.OwnerID != nil {
-		ownerID = *lead.OwnerID
-		ownerName = lead.OwnerName
-	}
-
-	// Create opportunity from lead
-	opportunity, err := domain.NewOpportunityFromLead(lead, pipeline, customerID, customerName, userID)
-	if err != nil {
-		return nil, application.ErrInternal("failed to create opportunity from lead", err)
-	}
-
-	// Set owner
-	opportunity.AssignOwner(ownerID, ownerName)
-
-	// Set description if provided
-	if req.Description != nil {
-		opportunity.Description = *req.Description
-	}
-
-	// Save opportunity
-	if err := uc.opportunityRepo.Create(ctx, opportunity); err != nil {
-		return nil, application.ErrInternal("failed to create opportunity", err)
+		ownerID, err := uuid.Parse(*req.OwnerID)
+		if err != nil {
+			return nil, application.ErrValidation("invalid owner ID")
+		}
+		request.OwnerID = &ownerID
 	}
 
-	// Mark lead as converted
-	var contactID *uuid.UUID
-	if err := lead.ConvertToOpportunity(opportunity.ID, userID, &customerID, contactID); err != nil {
-		return nil, application.ErrInternal("failed to convert lead", err)
+	if req.ExpectedCloseDate != nil {
+		closeDate, err := time.Parse("2006-01-02", *req.ExpectedCloseDate)
+		if err != nil {
+			return nil, application.ErrValidation("expected_close_date must be a YYYY-MM-DD date")
+		}
+		request.ExpectedCloseDate = &closeDate
 	}
 
-	// Save lead
-	if err := uc.leadRepo.Update(ctx, lead); err != nil {
-		return nil, application.ErrInternal("failed to update lead", err)
+	if req.ExpectedAmount != nil {
+		amount := domain.Money{Amount: *req.ExpectedAmount}
+		if req.Currency != nil {
+			amount.Currency = *req.Currency
+		}
+		request.Amount = &amount
 	}
 
-	// Publish events
-	uc.publishDomainEvents(ctx, lead.GetEvents())
-	uc.publishDomainEvents(ctx, opportunity.GetEvents())
-
-	return &dto.LeadConversionResponse{
-		LeadID:        lead.ID.String(),
-		OpportunityID: opportunity.ID.String(),
-		CustomerID:    dto.StringPtr(customerID.String()),
-		Message:       "Lead converted successfully",
-	}, nil
+	return request, nil
 }
 
 // Nurture moves a lead to nurturing status.
@@ -764,6 +796,93 @@ func (uc *leadUseCase) GetStatistics(ctx context.Context, tenantID uuid.UUID) (*
 	}, nil
 }
 
+// maxLeadMatches is the number of existing leads a new lead is reported to
+// match at most.
+const maxLeadMatches = 10
+
+// findMatches returns the open leads and the customers a new lead may
+// duplicate. Failed lookups do not hold back the lead.
+func (uc *leadUseCase) findMatches(ctx context.Context, tenantID uuid.UUID, lead *domain.Lead) []*dto.LeadMatchResponse {
+	criteria := domain.NewLeadMatchCriteria(lead.Contact, lead.Company)
+	if criteria.IsEmpty() {
+		return nil
+	}
+
+	var matches []*dto.LeadMatchResponse
+	if leads, err := uc.leadRepo.FindMatches(ctx, tenantID, criteria, maxLeadMatches); err == nil {
+		for _, existing := range leads {
+			if matchedOn := criteria.MatchLead(existing); len(matchedOn) > 0 {
+				matches = append(matches, uc.mapLeadToMatchResponse(existing, matchedOn))
+			}
+		}
+	}
+
+	if uc.customerMatcher != nil {
+		phones := []string{lead.Contact.Phone, lead.Contact.Mobile}
+		customers, err := uc.customerMatcher.FindMatchingCustomers(ctx, tenantID, lead.Contact.Email, phones, lead.Company.Name)
+		if err == nil {
+			for _, customer := range customers {
+				if len(customer.MatchedOn) > 0 {
+					matches = append(matches, uc.mapCustomerToMatchResponse(customer))
+				}
+			}
+		}
+	}
+
+	return matches
+}
+
+// MergeLeads merges duplicate leads into a lead, which takes over their
+// activities and attribution.
+func (uc *leadUseCase) MergeLeads(ctx context.Context, tenantID, userID uuid.UUID, req *dto.MergeLeadsRequest) (*dto.LeadResponse, error) {
+	targetID, err := uuid.Parse(req.TargetID)
+	if err != nil {
+		return nil, application.ErrValidation("invalid target lead ID")
+	}
+	if len(req.SourceIDs) > domain.MaxLeadMergeSources {
+		return nil, application.ErrValidation(fmt.Sprintf("at most %d leads can be merged at once", domain.MaxLeadMergeSources))
+	}
+
+	target, err := uc.leadRepo.GetByID(ctx, tenantID, targetID)
+	if err != nil {
+		return nil, application.ErrLeadNotFound(targetID)
+	}
+
+	sources := make([]*domain.Lead, 0, len(req.SourceIDs))
+	for _, id := range req.SourceIDs {
+		sourceID, err := uuid.Parse(id)
+		if err != nil {
+			return nil, application.ErrValidation("invalid lead ID: " + id)
+		}
+		source, err := uc.leadRepo.GetByID(ctx, tenantID, sourceID)
+		if err != nil {
+			return nil, application.ErrLeadNotFound(sourceID)
+		}
+		sources = append(sources, source)
+	}
+
+	if err := target.Merge(sources, userID); err != nil {
+		if errors.Is(err, domain.ErrLeadAlreadyConverted) {
+			return nil, application.ErrConflict(err.Error())
+		}
+		return nil, application.ErrValidation(err.Error())
+	}
+
+	if err := uc.leadRepo.Merge(ctx, target, sources); err != nil {
+		return nil, application.ErrInternal("failed to merge leads", err)
+	}
+
+	// Publish events
+	uc.publishDomainEvents(ctx, target.GetEvents())
+	target.ClearEvents()
+	for _, source := range sources {
+		uc.publishDomainEvents(ctx, source.GetEvents())
+		source.ClearEvents()
+	}
+
+	return uc.mapLeadToResponse(target), nil
+}
+
 // ============================================================================
 // Helper Functions
 // ============================================================================
@@ -775,13 +894,20 @@ func (uc *leadUseCase) publishDomainEvents(ctx context.Context, events []domain.
 	}
 
 	for _, domainEvent := range events {
+		// The payload carries the fields of the event, such as the leads
+		// merged into a lead
+		payload := make(map[string]interface{})
+		if data, err := json.Marshal(domainEvent); err == nil {
+			json.Unmarshal(data, &payload)
+		}
+
 		event := ports.Event{
 			ID:            domainEvent.EventID().String(),
 			Type:          domainEvent.EventType(),
 			AggregateID:   domainEvent.AggregateID().String(),
 			AggregateType: domainEvent.AggregateType(),
 			TenantID:      domainEvent.TenantID().String(),
-			Payload:       make(map[string]interface{}),
+			Payload:       payload,
 			Metadata:      make(map[string]string),
 			OccurredAt:    domainEvent.OccurredAt(),
 			Version:       domainEvent.Version(),
@@ -830,6 +956,17 @@ func (uc *leadUseCase) mapLeadToResponse(lead *domain.Lead) *dto.LeadResponse {
 		resp.OwnerID = dto.StringPtr(lead.OwnerID.String())
 	}
 
+	if lead.Attribution != nil {
+		resp.Attribution = mapAttributionToResponse(lead.Attribution, string(lead.Source))
+		resp.UTMParams = &dto.UTMParamsDTO{
+			Source:   dto.StringPtr(lead.Attribution.UTMSource),
+			Medium:   dto.StringPtr(lead.Attribution.UTMMedium),
+			Campaign: dto.StringPtr(lead.Attribution.UTMCampaign),
+			Term:     dto.StringPtr(lead.Attribution.UTMTerm),
+			Content:  dto.StringPtr(lead.Attribution.UTMContent),
+		}
+	}
+
 	if lead.EstimatedValue.Amount > 0 {
 		resp.Budget = &dto.MoneyDTO{
 			Amount:   lead.EstimatedValue.Amount,
@@ -877,6 +1014,60 @@ func (uc *leadUseCase) mapLeadToResponse(lead *domain.Lead) *dto.LeadResponse {
 	return resp
 }
 
+// mapAttributionToResponse maps the attribution of a lead, or of the
+// opportunity or deal it became, with the channel it is reported under.
+func mapAttributionToResponse(attribution *domain.Attribution, source string) *dto.AttributionDTO {
+	return &dto.AttributionDTO{
+		UTMSource:   attribution.UTMSource,
+		UTMMedium:   attribution.UTMMedium,
+		UTMCampaign: attribution.UTMCampaign,
+		UTMTerm:     attribution.UTMTerm,
+		UTMContent:  attribution.UTMContent,
+		Referrer:    attribution.Referrer,
+		LandingPage: attribution.LandingPage,
+		Channel:     attribution.Channel(source),
+	}
+}
+
+func (uc *leadUseCase) mapLeadToMatchResponse(lead *domain.Lead, matchedOn []string) *dto.LeadMatchResponse {
+	resp := &dto.LeadMatchResponse{
+		Type:      "lead",
+		ID:        lead.ID.String(),
+		Code:      lead.Code,
+		Name:      lead.Contact.FullName(),
+		Company:   dto.StringPtr(lead.Company.Name),
+		Email:     dto.StringPtr(lead.Contact.Email),
+		Phone:     dto.StringPtr(lead.Contact.Phone),
+		Status:    string(lead.Status),
+		MatchedOn: matchedOn,
+		CreatedAt: &lead.CreatedAt,
+	}
+	if resp.Phone == nil {
+		resp.Phone = dto.StringPtr(lead.Contact.Mobile)
+	}
+	if lead.OwnerID != nil {
+		resp.OwnerID = dto.StringPtr(lead.OwnerID.String())
+	}
+	return resp
+}
+
+func (uc *leadUseCase) mapCustomerToMatchResponse(customer *ports.CustomerMatch) *dto.LeadMatchResponse {
+	resp := &dto.LeadMatchResponse{
+		Type:      "customer",
+		ID:        customer.ID.String(),
+		Code:      customer.Code,
+		Name:      customer.Name,
+		Email:     customer.Email,
+		Phone:     customer.Phone,
+		Status:    customer.Status,
+		MatchedOn: customer.MatchedOn,
+	}
+	if customer.OwnerID != nil {
+		resp.OwnerID = dto.StringPtr(customer.OwnerID.String())
+	}
+	return resp
+}
+
 func (uc *leadUseCase) mapLeadToBriefResponse(lead *domain.Lead) *dto.LeadBriefResponse {
 	resp := &dto.LeadBriefResponse{
 		ID:        lead.ID.String(),
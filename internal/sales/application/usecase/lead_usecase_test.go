@@ -3,11 +3,13 @@ package usecase
 import (
 	"context"
 	"errors"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/google/uuid"
 
+	"github.com/kilang-desa-murni/crm/internal/sales/application"
 	"github.com/kilang-desa-murni/crm/internal/sales/application/dto"
 	"github.com/kilang-desa-murni/crm/internal/sales/application/ports"
 	"github.com/kilang-desa-murni/crm/internal/sales/domain"
@@ -89,11 +91,11 @@ func (m *MockLeadRepository) List(ctx context.Context, tenantID uuid.UUID, filte
 
 func (m *MockLeadRepository) GetByEmail(ctx context.Context, tenantID uuid.UUID, email string) (*domain.Lead, error) {
 	for _, lead := range m.leads {
-		if lead.TenantID == tenantID && lead.Contact.Email == email {
+		if lead.TenantID == tenantID && strings.EqualFold(lead.Contact.Email, email) {
 			return lead, nil
 		}
 	}
-	return nil, errors.New("lead not found")
+	return nil, nil
 }
 
 func (m *MockLeadRepository) GetByPhone(ctx context.Context, tenantID uuid.UUID, phone string) (*domain.Lead, error) {
@@ -105,6 +107,27 @@ func (m *MockLeadRepository) GetByPhone(ctx context.Context, tenantID uuid.UUID,
 	return nil, errors.New("lead not found")
 }
 
+func (m *MockLeadRepository) FindMatches(ctx context.Context, tenantID uuid.UUID, criteria domain.LeadMatchCriteria, limit int) ([]*domain.Lead, error) {
+	var result []*domain.Lead
+	for _, lead := range m.leads {
+		if lead.TenantID == tenantID && lead.IsOpen() && len(criteria.MatchLead(lead)) > 0 && len(result) < limit {
+			result = append(result, lead)
+		}
+	}
+	return result, nil
+}
+
+func (m *MockLeadRepository) Merge(ctx context.Context, target *domain.Lead, sources []*domain.Lead) error {
+	if m.updateErr != nil {
+		return m.updateErr
+	}
+	m.leads[target.ID] = target
+	for _, source := range sources {
+		m.leads[source.ID] = source
+	}
+	return nil
+}
+
 func (m *MockLeadRepository) GetByStatus(ctx context.Context, tenantID uuid.UUID, status domain.LeadStatus, opts domain.ListOptions) ([]*domain.Lead, int64, error) {
 	var result []*domain.Lead
 	for _, lead := range m.leads {
@@ -363,6 +386,14 @@ func (m *MockPipelineRepository) ReorderStages(ctx context.Context, tenantID, pi
 	return nil
 }
 
+func (m *MockPipelineRepository) GetVersion(ctx context.Context, tenantID, pipelineID uuid.UUID, version int) (*domain.PipelineVersion, error) {
+	return nil, domain.ErrPipelineVersionNotFound
+}
+
+func (m *MockPipelineRepository) ListVersions(ctx context.Context, tenantID, pipelineID uuid.UUID) ([]*domain.PipelineVersion, error) {
+	return nil, nil
+}
+
 func (m *MockPipelineRepository) GetPipelineStatistics(ctx context.Context, tenantID, pipelineID uuid.UUID) (*domain.PipelineStatistics, error) {
 	return &domain.PipelineStatistics{}, nil
 }
@@ -411,6 +442,9 @@ func setupLeadUseCase() (*leadUseCase, *MockLeadRepository, *MockOpportunityRepo
 	searchService := NewMockSearchService()
 	idGenerator := NewMockSalesIDGenerator()
 
+	uow := NewMockSagaUnitOfWork(nil, nil, nil, NewMockSagaRepository())
+	conversion := NewLeadConversionOrchestrator(uow, leadRepo, oppRepo, pipelineRepo, eventPublisher, customerService)
+
 	uc := NewLeadUseCase(
 		leadRepo,
 		oppRepo,
@@ -421,6 +455,8 @@ func setupLeadUseCase() (*leadUseCase, *MockLeadRepository, *MockOpportunityRepo
 		cacheService,
 		searchService,
 		idGenerator,
+		conversion,
+		nil,
 	)
 
 	return uc.(*leadUseCase), leadRepo, oppRepo, pipelineRepo, customerService, userService
@@ -1021,6 +1057,31 @@ func TestLeadUseCase_Convert_Success(t *testing.T) {
 	}
 }
 
+func TestLeadUseCase_Convert_RequiresOneCustomer(t *testing.T) {
+	uc, leadRepo, _, pipelineRepo, _, _ := setupLeadUseCase()
+	tenantID := uuid.New()
+
+	lead := createTestLead(tenantID)
+	lead.Status = domain.LeadStatusQualified
+	leadRepo.leads[lead.ID] = lead
+	pipeline := createTestPipeline(tenantID)
+	pipelineRepo.pipelines[pipeline.ID] = pipeline
+
+	customerID := uuid.New().String()
+	for name, req := range map[string]*dto.ConvertLeadRequest{
+		"neither": {PipelineID: pipeline.ID.String()},
+		"both":    {PipelineID: pipeline.ID.String(), CustomerID: &customerID, CreateNewCustomer: true},
+	} {
+		_, err := uc.Convert(context.Background(), tenantID, lead.ID, uuid.New(), req)
+		if appErr := application.GetAppError(err); appErr == nil || appErr.Code != application.ErrCodeValidation {
+			t.Errorf("%s: expected validation error, got %v", name, err)
+		}
+	}
+	if leadRepo.leads[lead.ID].Status != domain.LeadStatusQualified {
+		t.Error("Expected lead to stay qualified")
+	}
+}
+
 func TestLeadUseCase_Convert_NotQualified(t *testing.T) {
 	// Arrange
 	uc, leadRepo, _, pipelineRepo, _, _ := setupLeadUseCase()
@@ -2136,3 +2197,249 @@ func TestLeadUseCase_Convert_WithNewCustomer(t *testing.T) {
 		t.Fatal("Expected result, got nil")
 	}
 }
+
+// ============================================================================
+// LeadUseCase Tests - Duplicates
+// ============================================================================
+
+// MockCustomerMatcher is a mock implementation of ports.CustomerMatcher
+type MockCustomerMatcher struct {
+	matches []*ports.CustomerMatch
+	err     error
+}
+
+func (m *MockCustomerMatcher) FindMatchingCustomers(ctx context.Context, tenantID uuid.UUID, email string, phones []string, companyName string) ([]*ports.CustomerMatch, error) {
+	return m.matches, m.err
+}
+
+func TestLeadUseCase_Create_PotentialDuplicate(t *testing.T) {
+	// Arrange
+	uc, leadRepo, _, _, _, _ := setupLeadUseCase()
+	tenantID := uuid.New()
+	userID := uuid.New()
+
+	existing := createTestLead(tenantID)
+	leadRepo.leads[existing.ID] = existing
+
+	phone := "+1 234 567 890"
+	company := "Other Company"
+	req := &dto.CreateLeadRequest{
+		FirstName: "Johnny",
+		LastName:  "Doe",
+		Email:     "johnny@example.com",
+		Phone:     &phone,
+		Company:   &company,
+		Source:    "website",
+	}
+
+	// Act
+	_, err := uc.Create(context.Background(), tenantID, userID, req)
+
+	// Assert
+	appErr := application.GetAppError(err)
+	if appErr == nil || appErr.Code != application.ErrCodeLeadPotentialDuplicate {
+		t.Fatalf("Expected potential duplicate error, got: %v", err)
+	}
+	matches, ok := appErr.Details["matches"].([]*dto.LeadMatchResponse)
+	if !ok || len(matches) != 1 || matches[0].ID != existing.ID.String() {
+		t.Fatalf("Expected the existing lead as match, got: %v", appErr.Details["matches"])
+	}
+	if len(matches[0].MatchedOn) != 1 || matches[0].MatchedOn[0] != domain.LeadMatchPhone {
+		t.Errorf("Expected a phone match, got: %v", matches[0].MatchedOn)
+	}
+	if len(leadRepo.leads) != 1 {
+		t.Errorf("Expected no lead to be created, got %d leads", len(leadRepo.leads))
+	}
+
+	// A forced create keeps the lead anyway
+	req.Force = true
+	if _, err := uc.Create(context.Background(), tenantID, userID, req); err != nil {
+		t.Fatalf("Expected no error on forced create, got: %v", err)
+	}
+	if len(leadRepo.leads) != 2 {
+		t.Errorf("Expected 2 leads in repository, got %d", len(leadRepo.leads))
+	}
+}
+
+func TestLeadUseCase_Create_MatchesCustomer(t *testing.T) {
+	// Arrange
+	uc, leadRepo, _, _, _, _ := setupLeadUseCase()
+	tenantID := uuid.New()
+	userID := uuid.New()
+
+	customerID := uuid.New()
+	uc.customerMatcher = &MockCustomerMatcher{
+		matches: []*ports.CustomerMatch{{
+			CustomerInfo: ports.CustomerInfo{ID: customerID, Name: "Batik Murni Sdn Bhd"},
+			MatchedOn:    []string{domain.LeadMatchCompany},
+		}},
+	}
+
+	company := "Batik Murni"
+	req := &dto.CreateLeadRequest{
+		FirstName: "Aminah",
+		Email:     "aminah@example.com",
+		Company:   &company,
+		Source:    "website",
+	}
+
+	// Act
+	_, err := uc.Create(context.Background(), tenantID, userID, req)
+
+	// Assert
+	appErr := application.GetAppError(err)
+	if appErr == nil || appErr.Code != application.ErrCodeLeadPotentialDuplicate {
+		t.Fatalf("Expected potential duplicate error, got: %v", err)
+	}
+	matches := appErr.Details["matches"].([]*dto.LeadMatchResponse)
+	if len(matches) != 1 || matches[0].Type != "customer" || matches[0].ID != customerID.String() {
+		t.Errorf("Expected the customer as match, got: %+v", matches)
+	}
+	if len(leadRepo.leads) != 0 {
+		t.Errorf("Expected no lead to be created, got %d leads", len(leadRepo.leads))
+	}
+}
+
+func TestLeadUseCase_Create_CustomerMatcherError(t *testing.T) {
+	// Arrange
+	uc, leadRepo, _, _, _, _ := setupLeadUseCase()
+	uc.customerMatcher = &MockCustomerMatcher{err: errors.New("customer service unavailable")}
+
+	company := "Batik Murni"
+	req := &dto.CreateLeadRequest{
+		FirstName: "Aminah",
+		Email:     "aminah@example.com",
+		Company:   &company,
+		Source:    "website",
+	}
+
+	// Act
+	_, err := uc.Create(context.Background(), uuid.New(), uuid.New(), req)
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if len(leadRepo.leads) != 1 {
+		t.Errorf("Expected 1 lead in repository, got %d", len(leadRepo.leads))
+	}
+}
+
+func TestLeadUseCase_MergeLeads_Success(t *testing.T) {
+	// Arrange
+	uc, leadRepo, _, _, _, _ := setupLeadUseCase()
+	tenantID := uuid.New()
+	userID := uuid.New()
+
+	target := createTestLead(tenantID)
+	source := createTestLead(tenantID)
+	source.Contact.Mobile = "+60123456789"
+	leadRepo.leads[target.ID] = target
+	leadRepo.leads[source.ID] = source
+
+	req := &dto.MergeLeadsRequest{
+		TargetID:  target.ID.String(),
+		SourceIDs: []string{source.ID.String()},
+	}
+
+	// Act
+	result, err := uc.MergeLeads(context.Background(), tenantID, userID, req)
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if result.ID != target.ID.String() {
+		t.Errorf("Expected merged lead %s, got %s", target.ID, result.ID)
+	}
+	if target.Contact.Mobile != "+60123456789" {
+		t.Errorf("Expected mobile to be taken over, got %q", target.Contact.Mobile)
+	}
+	merged := leadRepo.leads[source.ID]
+	if merged.MergedIntoID == nil || *merged.MergedIntoID != target.ID || !merged.IsDeleted() {
+		t.Error("Expected source lead to be merged into the target")
+	}
+}
+
+func TestLeadUseCase_MergeLeads_ConvertedLead(t *testing.T) {
+	// Arrange
+	uc, leadRepo, _, _, _, _ := setupLeadUseCase()
+	tenantID := uuid.New()
+
+	target := createTestLead(tenantID)
+	source := createTestLead(tenantID)
+	source.Status = domain.LeadStatusConverted
+	leadRepo.leads[target.ID] = target
+	leadRepo.leads[source.ID] = source
+
+	req := &dto.MergeLeadsRequest{
+		TargetID:  target.ID.String(),
+		SourceIDs: []string{source.ID.String()},
+	}
+
+	// Act
+	_, err := uc.MergeLeads(context.Background(), tenantID, uuid.New(), req)
+
+	// Assert
+	if appErr := application.GetAppError(err); appErr == nil || appErr.Code != application.ErrCodeConflict {
+		t.Fatalf("Expected conflict error, got: %v", err)
+	}
+}
+
+func TestLeadUseCase_MergeLeads_IntoItself(t *testing.T) {
+	// Arrange
+	uc, leadRepo, _, _, _, _ := setupLeadUseCase()
+	tenantID := uuid.New()
+
+	target := createTestLead(tenantID)
+	leadRepo.leads[target.ID] = target
+
+	req := &dto.MergeLeadsRequest{
+		TargetID:  target.ID.String(),
+		SourceIDs: []string{target.ID.String()},
+	}
+
+	// Act
+	_, err := uc.MergeLeads(context.Background(), tenantID, uuid.New(), req)
+
+	// Assert
+	if appErr := application.GetAppError(err); appErr == nil || appErr.Code != application.ErrCodeValidation {
+		t.Fatalf("Expected validation error, got: %v", err)
+	}
+}
+
+func TestLeadUseCase_Create_CapturesAttribution(t *testing.T) {
+	uc, leadRepo, _, _, _, _ := setupLeadUseCase()
+
+	company := "Batik Murni"
+	source := "Facebook"
+	landingPage := "https://kilangdesamurni.com/raya?utm_medium=CPC&utm_campaign=Raya-2024"
+	req := &dto.CreateLeadRequest{
+		FirstName:   "Aminah",
+		Email:       "aminah@example.com",
+		Company:     &company,
+		Source:      "website",
+		UTMSource:   &source,
+		LandingPage: &landingPage,
+	}
+
+	result, err := uc.Create(context.Background(), uuid.New(), uuid.New(), req)
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+
+	if result.Attribution == nil {
+		t.Fatal("expected attribution in the response")
+	}
+	if result.Attribution.UTMSource != "facebook" || result.Attribution.UTMCampaign != "raya-2024" {
+		t.Errorf("unexpected attribution: %+v", result.Attribution)
+	}
+	if result.Attribution.Channel != "cpc" {
+		t.Errorf("expected channel cpc, got %q", result.Attribution.Channel)
+	}
+	for _, lead := range leadRepo.leads {
+		if lead.Attribution == nil || lead.Attribution.LandingPage != landingPage {
+			t.Errorf("attribution not stored: %+v", lead.Attribution)
+		}
+	}
+}
@@ -0,0 +1,102 @@
+package usecase
+
+import (
+	"github.com/kilang-desa-murni/crm/internal/sales/application"
+	"github.com/kilang-desa-murni/crm/internal/sales/domain"
+	"github.com/kilang-desa-murni/crm/pkg/query"
+)
+
+// listQuery is the allow-list of the filter and sort expressions a list
+// endpoint accepts.
+type listQuery struct {
+	fields query.Fields
+	sorts  []string
+}
+
+var leadListQuery = listQuery{
+	fields: query.Fields{
+		"status":            query.String,
+		"source":            query.String,
+		"rating":            query.String,
+		"score":             query.Integer,
+		"first_name":        query.String,
+		"last_name":         query.String,
+		"email":             query.String,
+		"company":           query.String,
+		"industry":          query.String,
+		"city":              query.String,
+		"country":           query.String,
+		"owner_id":          query.UUID,
+		"campaign_id":       query.UUID,
+		"estimated_amount":  query.Integer,
+		"last_contacted_at": query.Time,
+		"created_at":        query.Time,
+		"updated_at":        query.Time,
+	},
+	sorts: []string{"created_at", "updated_at", "score", "first_name", "last_name", "company", "status", "source"},
+}
+
+var opportunityListQuery = listQuery{
+	fields: query.Fields{
+		"status":              query.String,
+		"priority":            query.String,
+		"name":                query.String,
+		"source":              query.String,
+		"currency":            query.String,
+		"amount":              query.Integer,
+		"probability":         query.Integer,
+		"pipeline_id":         query.UUID,
+		"stage_id":            query.UUID,
+		"customer_id":         query.UUID,
+		"owner_id":            query.UUID,
+		"expected_close_date": query.Time,
+		"closed_at":           query.Time,
+		"created_at":          query.Time,
+		"updated_at":          query.Time,
+	},
+	sorts: []string{"created_at", "updated_at", "expected_close_date", "amount", "probability", "name", "status", "board_position"},
+}
+
+var dealListQuery = listQuery{
+	fields: query.Fields{
+		"status":             query.String,
+		"deal_number":        query.String,
+		"name":               query.String,
+		"currency":           query.String,
+		"total_amount":       query.Integer,
+		"paid_amount":        query.Integer,
+		"outstanding_amount": query.Integer,
+		"opportunity_id":     query.UUID,
+		"customer_id":        query.UUID,
+		"owner_id":           query.UUID,
+		"won_at":             query.Time,
+		"created_at":         query.Time,
+		"updated_at":         query.Time,
+	},
+	sorts: []string{"created_at", "updated_at", "won_at", "signed_date", "total_amount", "deal_number", "name"},
+}
+
+// parse parses a list request's filter and sort expressions. The sort, when
+// given, replaces the sort field and order of opts. Lists are paged on a
+// single sort key, so only one sort field is accepted.
+func (q listQuery) parse(filter, sort string, opts *domain.ListOptions) (query.Filter, error) {
+	expr, err := query.ParseFilter(filter, q.fields)
+	if err != nil {
+		return nil, application.ErrValidation("invalid filter: " + err.Error())
+	}
+
+	fields, err := query.ParseSort(sort, q.sorts...)
+	if err != nil {
+		return nil, application.ErrValidation("invalid sort: " + err.Error())
+	}
+	switch len(fields) {
+	case 0:
+	case 1:
+		opts.SortBy = fields[0].Field
+		opts.SortOrder = fields[0].Order()
+	default:
+		return nil, application.ErrValidation("invalid sort: only one sort field is supported")
+	}
+
+	return expr, nil
+}
@@ -0,0 +1,70 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"github.com/kilang-desa-murni/crm/internal/sales/application/dto"
+	"github.com/kilang-desa-murni/crm/internal/sales/domain"
+	"github.com/kilang-desa-murni/crm/pkg/query"
+)
+
+func TestListQuery_Parse(t *testing.T) {
+	opts := domain.ListOptions{SortBy: "created_at", SortOrder: "desc"}
+
+	expr, err := opportunityListQuery.parse("status:eq:open,amount:gte:1000", "-amount", &opts)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(expr) != 2 || expr[0].Field != "status" || expr[1].Op != query.Gte {
+		t.Errorf("unexpected filter %+v", expr)
+	}
+	if opts.SortBy != "amount" || opts.SortOrder != "desc" {
+		t.Errorf("expected sort by amount desc, got %s %s", opts.SortBy, opts.SortOrder)
+	}
+}
+
+func TestListQuery_Parse_KeepsSortWhenNoneGiven(t *testing.T) {
+	opts := domain.ListOptions{SortBy: "score", SortOrder: "asc"}
+
+	if _, err := leadListQuery.parse("", "", &opts); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if opts.SortBy != "score" || opts.SortOrder != "asc" {
+		t.Errorf("expected sort to be unchanged, got %s %s", opts.SortBy, opts.SortOrder)
+	}
+}
+
+func TestListQuery_Parse_Errors(t *testing.T) {
+	tests := []struct {
+		name   string
+		filter string
+		sort   string
+	}{
+		{"field not allowed", "custom_fields:eq:x", ""},
+		{"bad value", "total_amount:gte:many", ""},
+		{"sort field not allowed", "", "-outstanding_amount"},
+		{"several sort fields", "", "-created_at,name"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := domain.DefaultListOptions()
+			if _, err := dealListQuery.parse(tt.filter, tt.sort, &opts); err == nil {
+				t.Error("expected an error")
+			}
+		})
+	}
+}
+
+func TestLeadUseCase_List_InvalidFilterExpression(t *testing.T) {
+	uc, _, _, _, _, _ := setupLeadUseCase()
+
+	_, err := uc.List(context.Background(), uuid.New(), &dto.LeadFilterRequest{Filter: "score:gte:high"})
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+}
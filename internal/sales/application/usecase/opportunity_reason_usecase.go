@@ -0,0 +1,171 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+
+	"github.com/kilang-desa-murni/crm/internal/sales/application"
+	"github.com/kilang-desa-murni/crm/internal/sales/application/dto"
+	"github.com/kilang-desa-murni/crm/internal/sales/domain"
+)
+
+// ============================================================================
+// Opportunity Reason Use Case Interface
+// ============================================================================
+
+// OpportunityReasonUseCase defines the interface for managing win and loss reason catalogs.
+type OpportunityReasonUseCase interface {
+	Create(ctx context.Context, tenantID uuid.UUID, req *dto.CreateOpportunityReasonRequest) (*dto.OpportunityReasonResponse, error)
+	GetByID(ctx context.Context, tenantID, reasonID uuid.UUID) (*dto.OpportunityReasonResponse, error)
+	Update(ctx context.Context, tenantID, reasonID uuid.UUID, req *dto.UpdateOpportunityReasonRequest) (*dto.OpportunityReasonResponse, error)
+	// Delete deactivates a reason; closed opportunities keep referring to it.
+	Delete(ctx context.Context, tenantID, reasonID uuid.UUID) error
+	List(ctx context.Context, tenantID uuid.UUID, req *dto.ListOpportunityReasonsRequest) (*dto.OpportunityReasonListResponse, error)
+}
+
+// ============================================================================
+// Opportunity Reason Use Case Implementation
+// ============================================================================
+
+// opportunityReasonUseCase implements OpportunityReasonUseCase.
+type opportunityReasonUseCase struct {
+	reasonRepo domain.OpportunityReasonRepository
+}
+
+// NewOpportunityReasonUseCase creates a new opportunity reason use case.
+func NewOpportunityReasonUseCase(reasonRepo domain.OpportunityReasonRepository) OpportunityReasonUseCase {
+	return &opportunityReasonUseCase{reasonRepo: reasonRepo}
+}
+
+// Create adds a reason to the tenant's catalog.
+func (uc *opportunityReasonUseCase) Create(ctx context.Context, tenantID uuid.UUID, req *dto.CreateOpportunityReasonRequest) (*dto.OpportunityReasonResponse, error) {
+	reason, err := domain.NewOpportunityReason(tenantID, domain.ReasonType(req.Type), req.Name, req.Description, req.Order)
+	if err != nil {
+		return nil, application.ErrValidation(err.Error())
+	}
+
+	if err := uc.reasonRepo.Create(ctx, reason); err != nil {
+		if errors.Is(err, domain.ErrOpportunityReasonAlreadyExists) {
+			return nil, application.ErrOpportunityReasonAlreadyExists(req.Type, reason.Name)
+		}
+		return nil, application.WrapError(application.ErrCodeInternal, "failed to create opportunity reason", err)
+	}
+
+	return mapOpportunityReasonToResponse(reason), nil
+}
+
+// GetByID retrieves a catalog reason.
+func (uc *opportunityReasonUseCase) GetByID(ctx context.Context, tenantID, reasonID uuid.UUID) (*dto.OpportunityReasonResponse, error) {
+	reason, err := uc.getReason(ctx, tenantID, reasonID)
+	if err != nil {
+		return nil, err
+	}
+	return mapOpportunityReasonToResponse(reason), nil
+}
+
+// Update updates a catalog reason. Renaming a reason also renames it in
+// analytics, since breakdowns report catalog reasons under their current name.
+func (uc *opportunityReasonUseCase) Update(ctx context.Context, tenantID, reasonID uuid.UUID, req *dto.UpdateOpportunityReasonRequest) (*dto.OpportunityReasonResponse, error) {
+	reason, err := uc.getReason(ctx, tenantID, reasonID)
+	if err != nil {
+		return nil, err
+	}
+
+	name, description, order := reason.Name, reason.Description, reason.Order
+	if req.Name != nil {
+		name = *req.Name
+	}
+	if req.Description != nil {
+		description = *req.Description
+	}
+	if req.Order != nil {
+		order = *req.Order
+	}
+	if err := reason.Update(name, description, order); err != nil {
+		return nil, application.ErrValidation(err.Error())
+	}
+
+	if req.IsActive != nil {
+		if *req.IsActive {
+			reason.Activate()
+		} else {
+			reason.Deactivate()
+		}
+	}
+
+	if err := uc.reasonRepo.Update(ctx, reason); err != nil {
+		if errors.Is(err, domain.ErrOpportunityReasonAlreadyExists) {
+			return nil, application.ErrOpportunityReasonAlreadyExists(string(reason.Type), reason.Name)
+		}
+		return nil, application.WrapError(application.ErrCodeInternal, "failed to update opportunity reason", err)
+	}
+
+	return mapOpportunityReasonToResponse(reason), nil
+}
+
+// Delete deactivates a catalog reason.
+func (uc *opportunityReasonUseCase) Delete(ctx context.Context, tenantID, reasonID uuid.UUID) error {
+	reason, err := uc.getReason(ctx, tenantID, reasonID)
+	if err != nil {
+		return err
+	}
+
+	reason.Deactivate()
+	if err := uc.reasonRepo.Update(ctx, reason); err != nil {
+		return application.WrapError(application.ErrCodeInternal, "failed to deactivate opportunity reason", err)
+	}
+	return nil
+}
+
+// List lists the tenant's catalog reasons.
+func (uc *opportunityReasonUseCase) List(ctx context.Context, tenantID uuid.UUID, req *dto.ListOpportunityReasonsRequest) (*dto.OpportunityReasonListResponse, error) {
+	filter := domain.OpportunityReasonFilter{ActiveOnly: req.ActiveOnly}
+	if req.Type != "" {
+		reasonType := domain.ReasonType(req.Type)
+		if !reasonType.IsValid() {
+			return nil, application.ErrValidation("invalid reason type")
+		}
+		filter.Type = &reasonType
+	}
+
+	reasons, err := uc.reasonRepo.List(ctx, tenantID, filter)
+	if err != nil {
+		return nil, application.WrapError(application.ErrCodeInternal, "failed to list opportunity reasons", err)
+	}
+
+	resp := &dto.OpportunityReasonListResponse{Reasons: make([]*dto.OpportunityReasonResponse, len(reasons))}
+	for i, reason := range reasons {
+		resp.Reasons[i] = mapOpportunityReasonToResponse(reason)
+	}
+	return resp, nil
+}
+
+func (uc *opportunityReasonUseCase) getReason(ctx context.Context, tenantID, reasonID uuid.UUID) (*domain.OpportunityReason, error) {
+	reason, err := uc.reasonRepo.GetByID(ctx, tenantID, reasonID)
+	if err != nil {
+		if errors.Is(err, domain.ErrOpportunityReasonNotFound) {
+			return nil, application.ErrOpportunityReasonNotFound(reasonID)
+		}
+		return nil, application.WrapError(application.ErrCodeInternal, "failed to get opportunity reason", err)
+	}
+	return reason, nil
+}
+
+// ============================================================================
+// Helpers
+// ============================================================================
+
+func mapOpportunityReasonToResponse(reason *domain.OpportunityReason) *dto.OpportunityReasonResponse {
+	return &dto.OpportunityReasonResponse{
+		ID:          reason.ID.String(),
+		Type:        string(reason.Type),
+		Name:        reason.Name,
+		Description: reason.Description,
+		IsActive:    reason.IsActive,
+		Order:       reason.Order,
+		CreatedAt:   reason.CreatedAt,
+		UpdatedAt:   reason.UpdatedAt,
+	}
+}
@@ -0,0 +1,211 @@
+package usecase
+
+import (
+	"context"
+	"sort"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"github.com/kilang-desa-murni/crm/internal/sales/application"
+	"github.com/kilang-desa-murni/crm/internal/sales/application/dto"
+	"github.com/kilang-desa-murni/crm/internal/sales/domain"
+)
+
+// ============================================================================
+// Mock Implementations for Opportunity Reason Tests
+// ============================================================================
+
+// MockOpportunityReasonRepository is a mock implementation of domain.OpportunityReasonRepository.
+type MockOpportunityReasonRepository struct {
+	reasons map[uuid.UUID]*domain.OpportunityReason
+}
+
+func NewMockOpportunityReasonRepository() *MockOpportunityReasonRepository {
+	return &MockOpportunityReasonRepository{reasons: make(map[uuid.UUID]*domain.OpportunityReason)}
+}
+
+func (m *MockOpportunityReasonRepository) Create(ctx context.Context, reason *domain.OpportunityReason) error {
+	for _, existing := range m.reasons {
+		if existing.TenantID == reason.TenantID && existing.Type == reason.Type && existing.Name == reason.Name {
+			return domain.ErrOpportunityReasonAlreadyExists
+		}
+	}
+	m.reasons[reason.ID] = reason
+	return nil
+}
+
+func (m *MockOpportunityReasonRepository) GetByID(ctx context.Context, tenantID, reasonID uuid.UUID) (*domain.OpportunityReason, error) {
+	reason, ok := m.reasons[reasonID]
+	if !ok || reason.TenantID != tenantID {
+		return nil, domain.ErrOpportunityReasonNotFound
+	}
+	return reason, nil
+}
+
+func (m *MockOpportunityReasonRepository) Update(ctx context.Context, reason *domain.OpportunityReason) error {
+	if _, ok := m.reasons[reason.ID]; !ok {
+		return domain.ErrOpportunityReasonNotFound
+	}
+	m.reasons[reason.ID] = reason
+	return nil
+}
+
+func (m *MockOpportunityReasonRepository) List(ctx context.Context, tenantID uuid.UUID, filter domain.OpportunityReasonFilter) ([]*domain.OpportunityReason, error) {
+	var reasons []*domain.OpportunityReason
+	for _, reason := range m.reasons {
+		if reason.TenantID != tenantID {
+			continue
+		}
+		if filter.Type != nil && reason.Type != *filter.Type {
+			continue
+		}
+		if filter.ActiveOnly && !reason.IsActive {
+			continue
+		}
+		reasons = append(reasons, reason)
+	}
+	sort.Slice(reasons, func(i, j int) bool {
+		return reasons[i].Order < reasons[j].Order
+	})
+	return reasons, nil
+}
+
+func newTestOpportunityReason(repo *MockOpportunityReasonRepository, tenantID uuid.UUID, reasonType domain.ReasonType, name string) *domain.OpportunityReason {
+	reason, _ := domain.NewOpportunityReason(tenantID, reasonType, name, "", len(repo.reasons))
+	repo.reasons[reason.ID] = reason
+	return reason
+}
+
+// ============================================================================
+// Opportunity Reason Use Case Tests
+// ============================================================================
+
+func TestOpportunityReasonUseCase_Create(t *testing.T) {
+	tenantID := uuid.New()
+	uc := NewOpportunityReasonUseCase(NewMockOpportunityReasonRepository())
+
+	resp, err := uc.Create(context.Background(), tenantID, &dto.CreateOpportunityReasonRequest{Type: "lost", Name: " Price too high "})
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	if resp.Name != "Price too high" || resp.Type != "lost" || !resp.IsActive {
+		t.Errorf("Create() = %+v, want an active lost reason named %q", resp, "Price too high")
+	}
+
+	_, err = uc.Create(context.Background(), tenantID, &dto.CreateOpportunityReasonRequest{Type: "lost", Name: "Price too high"})
+	if appErr := application.GetAppError(err); appErr == nil || appErr.Code != application.ErrCodeOpportunityReasonAlreadyExists {
+		t.Errorf("Create() duplicate error = %v, want %s", err, application.ErrCodeOpportunityReasonAlreadyExists)
+	}
+
+	_, err = uc.Create(context.Background(), tenantID, &dto.CreateOpportunityReasonRequest{Type: "pending", Name: "Other"})
+	if !application.IsValidationError(err) {
+		t.Errorf("Create() invalid type error = %v, want validation error", err)
+	}
+}
+
+func TestOpportunityReasonUseCase_UpdateAndDelete(t *testing.T) {
+	tenantID := uuid.New()
+	repo := NewMockOpportunityReasonRepository()
+	reason := newTestOpportunityReason(repo, tenantID, domain.ReasonTypeWon, "Relationship")
+	uc := NewOpportunityReasonUseCase(repo)
+
+	name := "Existing relationship"
+	resp, err := uc.Update(context.Background(), tenantID, reason.ID, &dto.UpdateOpportunityReasonRequest{Name: &name})
+	if err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+	if resp.Name != name {
+		t.Errorf("Update() name = %q, want %q", resp.Name, name)
+	}
+
+	if err := uc.Delete(context.Background(), tenantID, reason.ID); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	if repo.reasons[reason.ID].IsActive {
+		t.Error("Delete() should deactivate the reason")
+	}
+
+	list, err := uc.List(context.Background(), tenantID, &dto.ListOpportunityReasonsRequest{Type: "won", ActiveOnly: true})
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	if len(list.Reasons) != 0 {
+		t.Errorf("List() active reasons = %d, want 0", len(list.Reasons))
+	}
+
+	if err := uc.Delete(context.Background(), tenantID, uuid.New()); !application.IsNotFoundError(err) {
+		t.Errorf("Delete() unknown reason error = %v, want not found", err)
+	}
+}
+
+func TestOpportunityUseCase_Win_WithCatalogReason(t *testing.T) {
+	oppRepo, pipelineRepo, dealRepo, eventPublisher, customerService, userService, productService, cacheService, searchService, idGenerator := setupOpportunityTestDependencies()
+	reasonRepo := NewMockOpportunityReasonRepository()
+	uc := NewOpportunityUseCase(oppRepo, pipelineRepo, dealRepo, eventPublisher, customerService, userService, productService, cacheService, searchService, idGenerator, nil, nil, reasonRepo, nil, nil)
+
+	tenantID := uuid.New()
+	pipeline := createOpportunityTestPipeline(tenantID)
+	pipelineRepo.pipelines[pipeline.ID] = pipeline
+	opp := createTestOpportunityWithPipeline(tenantID, pipeline)
+	oppRepo.opportunities[opp.ID] = opp
+
+	reason := newTestOpportunityReason(reasonRepo, tenantID, domain.ReasonTypeWon, "Best quality")
+	lostReason := newTestOpportunityReason(reasonRepo, tenantID, domain.ReasonTypeLost, "Price too high")
+
+	// Free text is rejected once the catalog has reasons
+	_, err := uc.Win(context.Background(), tenantID, opp.ID, uuid.New(), &dto.WinOpportunityRequest{WonReason: "Great proposal"})
+	if appErr := application.GetAppError(err); appErr == nil || appErr.Code != application.ErrCodeOpportunityReasonRequired {
+		t.Fatalf("Win() free text error = %v, want %s", err, application.ErrCodeOpportunityReasonRequired)
+	}
+
+	// A loss reason cannot close a win
+	lostReasonID := lostReason.ID.String()
+	_, err = uc.Win(context.Background(), tenantID, opp.ID, uuid.New(), &dto.WinOpportunityRequest{WonReasonID: &lostReasonID})
+	if appErr := application.GetAppError(err); appErr == nil || appErr.Code != application.ErrCodeOpportunityReasonInvalid {
+		t.Fatalf("Win() lost reason error = %v, want %s", err, application.ErrCodeOpportunityReasonInvalid)
+	}
+
+	reasonID := reason.ID.String()
+	comment := "Chose us for the hand-drawn batik"
+	if _, err := uc.Win(context.Background(), tenantID, opp.ID, uuid.New(), &dto.WinOpportunityRequest{WonReasonID: &reasonID, WonNotes: &comment}); err != nil {
+		t.Fatalf("Win() error = %v", err)
+	}
+
+	closeInfo := oppRepo.opportunities[opp.ID].CloseInfo
+	if closeInfo == nil || closeInfo.ReasonID == nil || *closeInfo.ReasonID != reason.ID {
+		t.Fatalf("Win() close info = %+v, want reason %s", closeInfo, reason.ID)
+	}
+	if closeInfo.Reason != "Best quality" || closeInfo.Notes != comment {
+		t.Errorf("Win() close reason = %q, notes = %q; want catalog name and comment", closeInfo.Reason, closeInfo.Notes)
+	}
+}
+
+func TestOpportunityUseCase_Lose_InactiveCatalogReason(t *testing.T) {
+	oppRepo, pipelineRepo, dealRepo, eventPublisher, customerService, userService, productService, cacheService, searchService, idGenerator := setupOpportunityTestDependencies()
+	reasonRepo := NewMockOpportunityReasonRepository()
+	uc := NewOpportunityUseCase(oppRepo, pipelineRepo, dealRepo, eventPublisher, customerService, userService, productService, cacheService, searchService, idGenerator, nil, nil, reasonRepo, nil, nil)
+
+	tenantID := uuid.New()
+	pipeline := createOpportunityTestPipeline(tenantID)
+	pipelineRepo.pipelines[pipeline.ID] = pipeline
+	opp := createTestOpportunityWithPipeline(tenantID, pipeline)
+	oppRepo.opportunities[opp.ID] = opp
+
+	reason := newTestOpportunityReason(reasonRepo, tenantID, domain.ReasonTypeLost, "No budget")
+	reason.Deactivate()
+
+	reasonID := reason.ID.String()
+	_, err := uc.Lose(context.Background(), tenantID, opp.ID, uuid.New(), &dto.LoseOpportunityRequest{LostReasonID: &reasonID})
+	if appErr := application.GetAppError(err); appErr == nil || appErr.Code != application.ErrCodeOpportunityReasonInvalid {
+		t.Fatalf("Lose() inactive reason error = %v, want %s", err, application.ErrCodeOpportunityReasonInvalid)
+	}
+
+	// With no active loss reasons left, free text is accepted again
+	if _, err := uc.Lose(context.Background(), tenantID, opp.ID, uuid.New(), &dto.LoseOpportunityRequest{LostReason: "Timing"}); err != nil {
+		t.Fatalf("Lose() free text error = %v", err)
+	}
+	if closeInfo := oppRepo.opportunities[opp.ID].CloseInfo; closeInfo == nil || closeInfo.ReasonID != nil || closeInfo.Reason != "Timing" {
+		t.Errorf("Lose() close info = %+v, want free-text reason without catalog ID", closeInfo)
+	}
+}
@@ -0,0 +1,140 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"github.com/kilang-desa-murni/crm/internal/sales/application"
+	"github.com/kilang-desa-murni/crm/internal/sales/domain"
+)
+
+// renewalBatchSize is the number of deals loaded at a time when creating
+// renewal opportunities.
+const renewalBatchSize = 100
+
+// ============================================================================
+// Renewals
+// ============================================================================
+
+// CreateRenewalOpportunities creates a renewal opportunity for every recurring
+// deal of any tenant whose renewal notice started by now, and returns how many
+// were created. Deals whose opportunity cannot be created are skipped and
+// tried again on the next run.
+func (uc *opportunityUseCase) CreateRenewalOpportunities(ctx context.Context, now time.Time) (int, error) {
+	created := 0
+	for {
+		deals, err := uc.dealRepo.GetDealsDueForRenewal(ctx, now, renewalBatchSize)
+		if err != nil {
+			return created, application.WrapError(application.ErrCodeInternal, "failed to get deals due for renewal", err)
+		}
+
+		batchCreated := 0
+		for _, deal := range deals {
+			if !deal.IsRenewalDue(now) {
+				continue
+			}
+			if err := uc.createRenewalOpportunity(ctx, deal); err != nil {
+				continue
+			}
+			batchCreated++
+		}
+		created += batchCreated
+
+		// A full batch of deals that all failed would be loaded again
+		if len(deals) < renewalBatchSize || batchCreated == 0 {
+			return created, nil
+		}
+		if err := ctx.Err(); err != nil {
+			return created, err
+		}
+	}
+}
+
+// createRenewalOpportunity opens the opportunity renewing a deal, in the
+// pipeline the deal was won in or else the default pipeline.
+func (uc *opportunityUseCase) createRenewalOpportunity(ctx context.Context, deal *domain.Deal) error {
+	pipeline, err := uc.renewalPipeline(ctx, deal)
+	if err != nil {
+		return err
+	}
+
+	opportunity, err := domain.NewRenewalOpportunity(deal, pipeline)
+	if err != nil {
+		return err
+	}
+	uc.applyBaseCurrency(ctx, opportunity)
+	if err := uc.opportunityRepo.Create(ctx, opportunity); err != nil {
+		return err
+	}
+
+	if err := deal.StartRenewal(opportunity.ID); err != nil {
+		return err
+	}
+	deal.Version++
+	if err := uc.dealRepo.Update(ctx, deal); err != nil {
+		return err
+	}
+
+	for _, event := range opportunity.GetEvents() {
+		uc.publishEvent(ctx, event)
+	}
+	opportunity.ClearEvents()
+	for _, event := range deal.GetEvents() {
+		uc.publishEvent(ctx, event)
+	}
+	deal.ClearEvents()
+
+	if uc.searchService != nil {
+		go uc.indexOpportunity(context.Background(), opportunity, pipeline)
+	}
+	uc.invalidateOpportunityCache(ctx, deal.TenantID)
+	return nil
+}
+
+func (uc *opportunityUseCase) renewalPipeline(ctx context.Context, deal *domain.Deal) (*domain.Pipeline, error) {
+	if source, err := uc.opportunityRepo.GetByID(ctx, deal.TenantID, deal.OpportunityID); err == nil {
+		if pipeline, err := uc.pipelineRepo.GetByID(ctx, deal.TenantID, source.PipelineID); err == nil && pipeline.IsActive {
+			return pipeline, nil
+		}
+	}
+	return uc.pipelineRepo.GetDefaultPipeline(ctx, deal.TenantID)
+}
+
+// closeRenewal records the outcome of a closed opportunity on the deal it
+// renews: renewed when won, churned for the lost reason when lost. It returns
+// the renewed deal, or nil when the opportunity renews none.
+func (uc *opportunityUseCase) closeRenewal(ctx context.Context, opportunity *domain.Opportunity) *domain.Deal {
+	if opportunity.Source != domain.RenewalOpportunitySource {
+		return nil
+	}
+	deal, err := uc.dealRepo.GetByRenewalOpportunity(ctx, opportunity.TenantID, opportunity.ID)
+	if err != nil || deal == nil {
+		return nil
+	}
+
+	switch opportunity.Status {
+	case domain.OpportunityStatusWon:
+		err = deal.MarkRenewed()
+	case domain.OpportunityStatusLost:
+		reason := ""
+		if opportunity.CloseInfo != nil {
+			reason = opportunity.CloseInfo.Reason
+		}
+		err = deal.Churn(reason)
+	default:
+		return nil
+	}
+	if err != nil {
+		return nil
+	}
+	deal.Version++
+	if err := uc.dealRepo.Update(ctx, deal); err != nil {
+		return nil
+	}
+
+	for _, event := range deal.GetEvents() {
+		uc.publishEvent(ctx, event)
+	}
+	deal.ClearEvents()
+	return deal
+}
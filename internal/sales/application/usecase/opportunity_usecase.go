@@ -2,6 +2,10 @@ package usecase
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
@@ -24,15 +28,18 @@ type OpportunityUseCase interface {
 	Update(ctx context.Context, tenantID, opportunityID, userID uuid.UUID, req *dto.UpdateOpportunityRequest) (*dto.OpportunityResponse, error)
 	Delete(ctx context.Context, tenantID, opportunityID, userID uuid.UUID) error
 	List(ctx context.Context, tenantID uuid.UUID, filter *dto.OpportunityFilterRequest) (*dto.OpportunityListResponse, error)
+	BatchGet(ctx context.Context, tenantID uuid.UUID, req *dto.BatchGetOpportunitiesRequest) (*dto.BatchGetOpportunitiesResponse, error)
 
 	// Stage operations
 	MoveStage(ctx context.Context, tenantID, opportunityID, userID uuid.UUID, req *dto.MoveStageRequest) (*dto.OpportunityResponse, error)
+	Reposition(ctx context.Context, tenantID, opportunityID, userID uuid.UUID, req *dto.RepositionOpportunityRequest) (*dto.OpportunityResponse, error)
 	Win(ctx context.Context, tenantID, opportunityID, userID uuid.UUID, req *dto.WinOpportunityRequest) (*dto.OpportunityWinResponse, error)
 	Lose(ctx context.Context, tenantID, opportunityID, userID uuid.UUID, req *dto.LoseOpportunityRequest) (*dto.OpportunityLoseResponse, error)
 	Reopen(ctx context.Context, tenantID, opportunityID, userID uuid.UUID, req *dto.ReopenOpportunityRequest) (*dto.OpportunityResponse, error)
 
 	// Product operations
 	AddProduct(ctx context.Context, tenantID, opportunityID, userID uuid.UUID, req *dto.AddProductRequest) (*dto.OpportunityResponse, error)
+	ReplaceProducts(ctx context.Context, tenantID, opportunityID, userID uuid.UUID, req *dto.ReplaceProductsRequest) (*dto.OpportunityResponse, error)
 	UpdateProduct(ctx context.Context, tenantID, opportunityID, productID, userID uuid.UUID, req *dto.UpdateProductRequest) (*dto.OpportunityResponse, error)
 	RemoveProduct(ctx context.Context, tenantID, opportunityID, productID, userID uuid.UUID) (*dto.OpportunityResponse, error)
 
@@ -40,6 +47,7 @@ type OpportunityUseCase interface {
 	AddContact(ctx context.Context, tenantID, opportunityID, userID uuid.UUID, req *dto.AddContactRequest) (*dto.OpportunityResponse, error)
 	UpdateContact(ctx context.Context, tenantID, opportunityID, contactID, userID uuid.UUID, req *dto.UpdateContactRequest) (*dto.OpportunityResponse, error)
 	RemoveContact(ctx context.Context, tenantID, opportunityID, contactID, userID uuid.UUID) (*dto.OpportunityResponse, error)
+	SetPrimaryContact(ctx context.Context, tenantID, opportunityID, contactID, userID uuid.UUID) (*dto.OpportunityResponse, error)
 
 	// Competitor operations
 	AddCompetitor(ctx context.Context, tenantID, opportunityID, userID uuid.UUID, req *dto.AddCompetitorRequest) (*dto.OpportunityResponse, error)
@@ -54,6 +62,9 @@ type OpportunityUseCase interface {
 	// Analytics
 	GetStatistics(ctx context.Context, tenantID uuid.UUID) (*dto.OpportunityStatisticsResponse, error)
 	GetPipelineAnalytics(ctx context.Context, tenantID, pipelineID uuid.UUID) (*dto.PipelineAnalyticsResponse, error)
+
+	// Renewals
+	CreateRenewalOpportunities(ctx context.Context, now time.Time) (int, error)
 }
 
 // ============================================================================
@@ -62,16 +73,21 @@ type OpportunityUseCase interface {
 
 // opportunityUseCase implements OpportunityUseCase.
 type opportunityUseCase struct {
-	opportunityRepo domain.OpportunityRepository
-	pipelineRepo    domain.PipelineRepository
-	dealRepo        domain.DealRepository
-	eventPublisher  ports.EventPublisher
-	customerService ports.CustomerService
-	userService     ports.UserService
-	productService  ports.ProductService
-	cacheService    ports.CacheService
-	searchService   ports.SearchService
-	idGenerator     ports.IDGenerator
+	opportunityRepo   domain.OpportunityRepository
+	pipelineRepo      domain.PipelineRepository
+	dealRepo          domain.DealRepository
+	eventPublisher    ports.EventPublisher
+	customerService   ports.CustomerService
+	userService       ports.UserService
+	productService    ports.ProductService
+	cacheService      ports.CacheService
+	searchService     ports.SearchService
+	idGenerator       ports.IDGenerator
+	currencyConverter ports.CurrencyConverter
+	taxResolver       ports.TaxRateResolver
+	reasonRepo        domain.OpportunityReasonRepository
+	approvalRepo      domain.DiscountApprovalRepository
+	priceResolver     ports.PriceListResolver
 }
 
 // NewOpportunityUseCase creates a new opportunity use case.
@@ -86,18 +102,28 @@ func NewOpportunityUseCase(
 	cacheService ports.CacheService,
 	searchService ports.SearchService,
 	idGenerator ports.IDGenerator,
+	currencyConverter ports.CurrencyConverter,
+	taxResolver ports.TaxRateResolver,
+	reasonRepo domain.OpportunityReasonRepository,
+	approvalRepo domain.DiscountApprovalRepository,
+	priceResolver ports.PriceListResolver,
 ) OpportunityUseCase {
 	return &opportunityUseCase{
-		opportunityRepo: opportunityRepo,
-		pipelineRepo:    pipelineRepo,
-		dealRepo:        dealRepo,
-		eventPublisher:  eventPublisher,
-		customerService: customerService,
-		userService:     userService,
-		productService:  productService,
-		cacheService:    cacheService,
-		searchService:   searchService,
-		idGenerator:     idGenerator,
+		opportunityRepo:   opportunityRepo,
+		pipelineRepo:      pipelineRepo,
+		dealRepo:          dealRepo,
+		eventPublisher:    eventPublisher,
+		customerService:   customerService,
+		userService:       userService,
+		productService:    productService,
+		cacheService:      cacheService,
+		searchService:     searchService,
+		idGenerator:       idGenerator,
+		currencyConverter: currencyConverter,
+		taxResolver:       taxResolver,
+		reasonRepo:        reasonRepo,
+		approvalRepo:      approvalRepo,
+		priceResolver:     priceResolver,
 	}
 }
 
@@ -148,7 +174,7 @@ func (uc *opportunityUseCase) Create(ctx context.Context, tenantID, userID uuid.
 
 	// Parse customer ID and name (required for NewOpportunity)
 	var customerID uuid.UUID
-	customerName := ""
+	customerName, customerSegment := "", ""
 	if req.CustomerID != nil {
 		customerID, _ = uuid.Parse(*req.CustomerID)
 		exists, err := uc.customerService.CustomerExists(ctx, tenantID, customerID)
@@ -158,9 +184,10 @@ func (uc *opportunityUseCase) Create(ctx context.Context, tenantID, userID uuid.
 		if !exists {
 			return nil, application.ErrCustomerNotFound(customerID)
 		}
-		// Get customer name
+		// Get customer name and segment
 		if customer, err := uc.customerService.GetCustomer(ctx, tenantID, customerID); err == nil && customer != nil {
 			customerName = customer.Name
+			customerSegment = customer.Tier
 		}
 	}
 
@@ -185,10 +212,18 @@ func (uc *opportunityUseCase) Create(ctx context.Context, tenantID, userID uuid.
 	if err != nil {
 		return nil, application.WrapError(application.ErrCodeValidation, "failed to create opportunity", err)
 	}
+	opportunity.CustomerSegment = customerSegment
 
-	// Set probability
+	// Override the stage probability and forecast category
 	if req.Probability != nil {
-		opportunity.Probability = *req.Probability
+		if err := opportunity.SetProbabilityOverride(req.Probability, opportunity.Probability, userID); err != nil {
+			return nil, application.ErrValidation(err.Error())
+		}
+	}
+	if req.ForecastCategory != nil {
+		if err := opportunity.SetForecastCategory(domain.ForecastCategory(*req.ForecastCategory), userID); err != nil {
+			return nil, application.ErrValidation(err.Error())
+		}
 	}
 
 	// Set optional fields
@@ -249,22 +284,11 @@ func (uc *opportunityUseCase) Create(ctx context.Context, tenantID, userID uuid.
 
 	// Add products
 	if len(req.Products) > 0 {
+		pricing := uc.productPricing(ctx, tenantID, customerID, pipeline)
 		for _, productReq := range req.Products {
-			productID, _ := uuid.Parse(productReq.ProductID)
-			unitPrice, _ := domain.NewMoney(productReq.UnitPrice, productReq.Currency)
-			discount := float64(0)
-			if productReq.DiscountPercent != nil {
-				discount = float64(*productReq.DiscountPercent)
-			}
-			product := domain.OpportunityProduct{
-				ProductID:   productID,
-				ProductName: productReq.ProductName,
-				Quantity:    productReq.Quantity,
-				UnitPrice:   unitPrice,
-				Discount:    discount,
-			}
-			if productReq.Description != nil {
-				product.Notes = *productReq.Description
+			product, err := uc.buildProductLine(ctx, tenantID, pricing, productReq)
+			if err != nil {
+				return nil, err
 			}
 			opportunity.AddProduct(product)
 		}
@@ -272,6 +296,9 @@ func (uc *opportunityUseCase) Create(ctx context.Context, tenantID, userID uuid.
 
 	// Note: Competitors list not supported in domain - use CloseInfo.CompetitorID/Name when losing
 
+	// Store the value in the tenant's base currency
+	uc.applyBaseCurrency(ctx, opportunity)
+
 	// Save opportunity
 	if err := uc.opportunityRepo.Create(ctx, opportunity); err != nil {
 		return nil, application.WrapError(application.ErrCodeInternal, "failed to save opportunity", err)
@@ -307,6 +334,62 @@ func (uc *opportunityUseCase) GetByID(ctx context.Context, tenantID, opportunity
 	return uc.mapOpportunityToResponse(ctx, opportunity, pipeline), nil
 }
 
+// BatchGet retrieves several opportunities by ID. Opportunities that do not
+// exist are reported as missing.
+func (uc *opportunityUseCase) BatchGet(ctx context.Context, tenantID uuid.UUID, req *dto.BatchGetOpportunitiesRequest) (*dto.BatchGetOpportunitiesResponse, error) {
+	ids := make([]uuid.UUID, 0, len(req.IDs))
+	seen := make(map[uuid.UUID]bool, len(req.IDs))
+	for _, id := range req.IDs {
+		parsedID, err := uuid.Parse(id)
+		if err != nil {
+			return nil, application.ErrValidation("invalid opportunity id format")
+		}
+		if !seen[parsedID] {
+			seen[parsedID] = true
+			ids = append(ids, parsedID)
+		}
+	}
+	if len(ids) == 0 {
+		return nil, application.ErrValidation("ids is required")
+	}
+	if len(ids) > dto.MaxBatchGetOpportunities {
+		return nil, application.ErrValidation(fmt.Sprintf("at most %d ids may be requested", dto.MaxBatchGetOpportunities))
+	}
+
+	opts := domain.DefaultListOptions()
+	opts.PageSize = len(ids)
+	opportunities, _, err := uc.opportunityRepo.List(ctx, tenantID, domain.OpportunityFilter{IDs: ids}, opts)
+	if err != nil {
+		return nil, application.WrapError(application.ErrCodeInternal, "failed to get opportunities", err)
+	}
+
+	byID := make(map[uuid.UUID]*domain.Opportunity, len(opportunities))
+	for _, opp := range opportunities {
+		byID[opp.ID] = opp
+	}
+	pipelineMap := make(map[uuid.UUID]*domain.Pipeline)
+	refs := uc.loadOpportunityRefs(ctx, tenantID, opportunities)
+
+	resp := &dto.BatchGetOpportunitiesResponse{
+		Found:   make([]*dto.OpportunityResponse, 0, len(opportunities)),
+		Missing: []string{},
+	}
+	for _, id := range ids {
+		opp, ok := byID[id]
+		if !ok {
+			resp.Missing = append(resp.Missing, id.String())
+			continue
+		}
+		if _, ok := pipelineMap[opp.PipelineID]; !ok {
+			pipeline, _ := uc.pipelineRepo.GetByID(ctx, tenantID, opp.PipelineID)
+			pipelineMap[opp.PipelineID] = pipeline
+		}
+		resp.Found = append(resp.Found, uc.mapOpportunityWithRefs(opp, pipelineMap[opp.PipelineID], refs))
+	}
+
+	return resp, nil
+}
+
 // Update updates an opportunity.
 func (uc *opportunityUseCase) Update(ctx context.Context, tenantID, opportunityID, userID uuid.UUID, req *dto.UpdateOpportunityRequest) (*dto.OpportunityResponse, error) {
 	opportunity, err := uc.opportunityRepo.GetByID(ctx, tenantID, opportunityID)
@@ -335,8 +418,8 @@ func (uc *opportunityUseCase) Update(ctx context.Context, tenantID, opportunityI
 		amount, _ := domain.NewMoney(*req.Amount, *req.Currency)
 		opportunity.Amount = amount
 	}
-	if req.Probability != nil {
-		opportunity.Probability = *req.Probability
+	if err := uc.applyForecastChanges(ctx, opportunity, userID, req); err != nil {
+		return nil, err
 	}
 	if req.ExpectedCloseDate != nil {
 		expectedCloseDate, _ := time.Parse("2006-01-02", *req.ExpectedCloseDate)
@@ -349,9 +432,10 @@ func (uc *opportunityUseCase) Update(ctx context.Context, tenantID, opportunityI
 			return nil, application.ErrCustomerNotFound(customerID)
 		}
 		opportunity.CustomerID = customerID
-		// Get customer name
+		// Get customer name and segment
 		if customer, err := uc.customerService.GetCustomer(ctx, tenantID, customerID); err == nil && customer != nil {
 			opportunity.CustomerName = customer.Name
+			opportunity.CustomerSegment = customer.Tier
 		}
 	}
 	if req.Source != nil {
@@ -374,6 +458,9 @@ func (uc *opportunityUseCase) Update(ctx context.Context, tenantID, opportunityI
 	// Add update event
 	opportunity.AddEvent(domain.NewOpportunityUpdatedEvent(opportunity))
 
+	// Store the value in the tenant's base currency
+	uc.applyBaseCurrency(ctx, opportunity)
+
 	// Save changes
 	if err := uc.opportunityRepo.Update(ctx, opportunity); err != nil {
 		return nil, application.WrapError(application.ErrCodeInternal, "failed to update opportunity", err)
@@ -447,6 +534,16 @@ func (uc *opportunityUseCase) List(ctx context.Context, tenantID uuid.UUID, filt
 	if filter.SortOrder != "" {
 		opts.SortOrder = filter.SortOrder
 	}
+	expr, err := opportunityListQuery.parse(filter.Filter, filter.Sort, &opts)
+	if err != nil {
+		return nil, err
+	}
+	domainFilter.Expr = expr
+	after, err := opportunityCursorSorts.after(filter.Cursor, opts.SortBy, opts.SortOrder)
+	if err != nil {
+		return nil, err
+	}
+	opts.After = after
 
 	// Get opportunities
 	opportunities, total, err := uc.opportunityRepo.List(ctx, tenantID, domainFilter, opts)
@@ -456,6 +553,7 @@ func (uc *opportunityUseCase) List(ctx context.Context, tenantID uuid.UUID, filt
 
 	// Get pipelines for stage names
 	pipelineMap := make(map[uuid.UUID]*domain.Pipeline)
+	refs := uc.loadOpportunityRefs(ctx, tenantID, opportunities)
 
 	// Map to response
 	opportunityResponses := make([]*dto.OpportunityBriefResponse, len(opportunities))
@@ -465,15 +563,18 @@ func (uc *opportunityUseCase) List(ctx context.Context, tenantID uuid.UUID, filt
 			pipeline, _ := uc.pipelineRepo.GetByID(ctx, tenantID, opp.PipelineID)
 			pipelineMap[opp.PipelineID] = pipeline
 		}
-		opportunityResponses[i] = uc.mapOpportunityToBriefResponse(ctx, opp, pipelineMap[opp.PipelineID])
+		opportunityResponses[i] = uc.mapOpportunityToBriefResponse(opp, pipelineMap[opp.PipelineID], refs)
 	}
 
 	// Calculate summary
 	summary := uc.calculateListSummary(opportunities)
 
+	pagination := dto.NewPaginationResponse(opts.Page, opts.PageSize, total)
+	opportunityCursorSorts.paginate(&pagination, opportunities, opts.Limit(), opts, func(o *domain.Opportunity) uuid.UUID { return o.ID })
+
 	return &dto.OpportunityListResponse{
 		Opportunities: opportunityResponses,
-		Pagination:    dto.NewPaginationResponse(opts.Page, opts.PageSize, total),
+		Pagination:    pagination,
 		Summary:       summary,
 	}, nil
 }
@@ -514,6 +615,9 @@ func (uc *opportunityUseCase) MoveStage(ctx context.Context, tenantID, opportuni
 	if !stage.IsActive {
 		return nil, application.ErrPipelineStageInactive(stageID)
 	}
+	if err := uc.checkStageRules(ctx, opportunity, stage, req.OverrideRules); err != nil {
+		return nil, err
+	}
 
 	// Move stage
 	notes := ""
@@ -524,9 +628,11 @@ func (uc *opportunityUseCase) MoveStage(ctx context.Context, tenantID, opportuni
 		return nil, application.WrapError(application.ErrCodeOpportunityInvalidTransition, err.Error(), err)
 	}
 
-	// Update probability if provided
+	// Override the stage probability if provided
 	if req.Probability != nil {
-		opportunity.Probability = *req.Probability
+		if err := opportunity.SetProbabilityOverride(req.Probability, stage.Probability, userID); err != nil {
+			return nil, application.ErrValidation(err.Error())
+		}
 	}
 
 	// Note: weighted amount is calculated automatically in domain
@@ -550,6 +656,103 @@ func (uc *opportunityUseCase) MoveStage(ctx context.Context, tenantID, opportuni
 	return uc.mapOpportunityToResponse(ctx, opportunity, pipeline), nil
 }
 
+// Reposition moves an opportunity to a position on the pipeline board, moving
+// it into the requested stage if needed. The new position key is placed
+// between its neighbours, so no other opportunity is renumbered.
+func (uc *opportunityUseCase) Reposition(ctx context.Context, tenantID, opportunityID, userID uuid.UUID, req *dto.RepositionOpportunityRequest) (*dto.OpportunityResponse, error) {
+	if req.Position < 0 {
+		return nil, application.ErrValidation("position must not be negative")
+	}
+
+	opportunity, err := uc.opportunityRepo.GetByID(ctx, tenantID, opportunityID)
+	if err != nil {
+		return nil, application.ErrOpportunityNotFound(opportunityID)
+	}
+
+	// Closed opportunities stay where they were closed
+	if opportunity.Status != domain.OpportunityStatusOpen {
+		return nil, application.ErrOpportunityClosed(opportunityID)
+	}
+
+	// Get pipeline
+	pipeline, err := uc.pipelineRepo.GetByID(ctx, tenantID, opportunity.PipelineID)
+	if err != nil {
+		return nil, application.ErrPipelineNotFound(opportunity.PipelineID)
+	}
+
+	// Parse stage ID
+	stageID, err := uuid.Parse(req.StageID)
+	if err != nil {
+		return nil, application.ErrValidation("invalid stage_id format")
+	}
+
+	// Get stage
+	stage := pipeline.GetStage(stageID)
+	if stage == nil {
+		return nil, application.ErrPipelineStageNotFound(pipeline.ID, stageID)
+	}
+	if !stage.IsActive {
+		return nil, application.ErrPipelineStageInactive(stageID)
+	}
+	if stage.Type.IsClosedType() {
+		return nil, application.ErrValidation("use win or lose to move an opportunity to a closed stage")
+	}
+	if err := uc.checkStageRules(ctx, opportunity, stage, req.OverrideRules); err != nil {
+		return nil, err
+	}
+
+	before, after, err := uc.opportunityRepo.GetBoardPositionNeighbors(ctx, tenantID, stageID, opportunityID, req.Position)
+	if err != nil {
+		return nil, application.WrapError(application.ErrCodeInternal, "failed to get board positions", err)
+	}
+	position, err := domain.BoardPositionBetween(before, after)
+	if err != nil {
+		return nil, application.WrapError(application.ErrCodeInternal, "failed to compute board position", err)
+	}
+
+	if err := opportunity.Reposition(stage, position, userID); err != nil {
+		return nil, application.WrapError(application.ErrCodeOpportunityInvalidTransition, err.Error(), err)
+	}
+
+	// Save changes
+	if err := uc.opportunityRepo.Update(ctx, opportunity); err != nil {
+		return nil, application.WrapError(application.ErrCodeInternal, "failed to update opportunity", err)
+	}
+
+	// Publish events
+	for _, event := range opportunity.GetEvents() {
+		uc.publishEvent(ctx, event)
+	}
+	opportunity.ClearEvents()
+
+	return uc.mapOpportunityToResponse(ctx, opportunity, pipeline), nil
+}
+
+// checkStageRules enforces the entry criteria and WIP limit of the stage an
+// opportunity is moving into. Repositioning within its own stage is always
+// allowed, and managers override the rules.
+func (uc *opportunityUseCase) checkStageRules(ctx context.Context, opportunity *domain.Opportunity, stage *domain.Stage, override bool) error {
+	if override || stage.ID == opportunity.StageID {
+		return nil
+	}
+
+	if unmet := stage.UnmetEntryCriteria(opportunity); len(unmet) > 0 {
+		return application.ErrPipelineStageEntryCriteriaNotMet(stage.ID, stage.Name, unmet)
+	}
+
+	if stage.WIPLimit > 0 {
+		counts, err := uc.opportunityRepo.CountByStage(ctx, opportunity.TenantID, opportunity.PipelineID)
+		if err != nil {
+			return application.WrapError(application.ErrCodeInternal, "failed to count stage opportunities", err)
+		}
+		if !stage.HasRoomFor(counts[stage.ID]) {
+			return application.ErrPipelineStageWIPLimitReached(stage.ID, stage.Name, stage.WIPLimit)
+		}
+	}
+
+	return nil
+}
+
 // Win marks an opportunity as won.
 func (uc *opportunityUseCase) Win(ctx context.Context, tenantID, opportunityID, userID uuid.UUID, req *dto.WinOpportunityRequest) (*dto.OpportunityWinResponse, error) {
 	opportunity, err := uc.opportunityRepo.GetByID(ctx, tenantID, opportunityID)
@@ -590,19 +793,31 @@ func (uc *opportunityUseCase) Win(ctx context.Context, tenantID, opportunityID,
 		return nil, application.NewAppError(application.ErrCodePipelineStageNotFound, "no won stage found in pipeline")
 	}
 
+	reason, reasonID, err := uc.resolveCloseReason(ctx, tenantID, domain.ReasonTypeWon, req.WonReasonID, req.WonReason)
+	if err != nil {
+		return nil, err
+	}
+
+	// Discounts above the approval rules must be approved before winning
+	if err := requireDiscountApproval(ctx, uc.approvalRepo, opportunity); err != nil {
+		return nil, err
+	}
+
 	// Win the opportunity
 	notes := ""
 	if req.WonNotes != nil {
 		notes = *req.WonNotes
 	}
-	if err := opportunity.Win(wonStage, req.WonReason, notes, userID); err != nil {
+	if err := opportunity.Win(wonStage, reason, notes, userID); err != nil {
 		return nil, application.WrapError(application.ErrCodeOpportunityWinFailed, err.Error(), err)
 	}
+	opportunity.CloseInfo.ReasonID = reasonID
 
 	// Update actual amount if provided
 	if req.ActualAmount != nil {
 		actualAmount, _ := domain.NewMoney(*req.ActualAmount, opportunity.Amount.Currency)
 		opportunity.Amount = actualAmount
+		uc.applyBaseCurrency(ctx, opportunity)
 	}
 
 	// Update actual close date if provided
@@ -616,10 +831,13 @@ func (uc *opportunityUseCase) Win(ctx context.Context, tenantID, opportunityID,
 		return nil, application.WrapError(application.ErrCodeInternal, "failed to update opportunity", err)
 	}
 
+	// Mark the deal a renewal opportunity renews as renewed
+	renewed := uc.closeRenewal(ctx, opportunity)
+
 	// Create deal if requested
 	var dealID *string
 	if req.CreateDeal {
-		deal, err := uc.createDealFromOpportunity(ctx, opportunity, userID, req)
+		deal, err := uc.createDealFromOpportunity(ctx, opportunity, renewed, userID, req)
 		if err != nil {
 			// Log error but don't fail
 		} else {
@@ -690,6 +908,11 @@ func (uc *opportunityUseCase) Lose(ctx context.Context, tenantID, opportunityID,
 		return nil, application.NewAppError(application.ErrCodePipelineStageNotFound, "no lost stage found in pipeline")
 	}
 
+	reason, reasonID, err := uc.resolveCloseReason(ctx, tenantID, domain.ReasonTypeLost, req.LostReasonID, req.LostReason)
+	if err != nil {
+		return nil, err
+	}
+
 	// Prepare competitor info
 	var competitorID *uuid.UUID
 	if req.CompetitorID != nil {
@@ -707,15 +930,19 @@ func (uc *opportunityUseCase) Lose(ctx context.Context, tenantID, opportunityID,
 	}
 
 	// Lose the opportunity
-	if err := opportunity.Lose(lostStage, req.LostReason, notes, competitorID, competitorName, userID); err != nil {
+	if err := opportunity.Lose(lostStage, reason, notes, competitorID, competitorName, userID); err != nil {
 		return nil, application.WrapError(application.ErrCodeOpportunityLoseFailed, err.Error(), err)
 	}
+	opportunity.CloseInfo.ReasonID = reasonID
 
 	// Save changes
 	if err := uc.opportunityRepo.Update(ctx, opportunity); err != nil {
 		return nil, application.WrapError(application.ErrCodeInternal, "failed to update opportunity", err)
 	}
 
+	// Mark the deal a renewal opportunity renews as churned
+	uc.closeRenewal(ctx, opportunity)
+
 	// Publish events
 	for _, event := range opportunity.GetEvents() {
 		uc.publishEvent(ctx, event)
@@ -780,9 +1007,11 @@ func (uc *opportunityUseCase) Reopen(ctx context.Context, tenantID, opportunityI
 	// Set expected close date
 	opportunity.ExpectedCloseDate = &expectedCloseDate
 
-	// Update probability if provided
+	// Override the stage probability if provided
 	if req.Probability != nil {
-		opportunity.Probability = *req.Probability
+		if err := opportunity.SetProbabilityOverride(req.Probability, pipeline.GetFirstStage().Probability, userID); err != nil {
+			return nil, application.ErrValidation(err.Error())
+		}
 	}
 
 	// Note: weighted amount is calculated automatically in domain
@@ -812,93 +1041,69 @@ func (uc *opportunityUseCase) Reopen(ctx context.Context, tenantID, opportunityI
 
 // AddProduct adds a product to an opportunity.
 func (uc *opportunityUseCase) AddProduct(ctx context.Context, tenantID, opportunityID, userID uuid.UUID, req *dto.AddProductRequest) (*dto.OpportunityResponse, error) {
-	opportunity, err := uc.opportunityRepo.GetByID(ctx, tenantID, opportunityID)
+	opportunity, err := uc.getOpenOpportunity(ctx, tenantID, opportunityID)
 	if err != nil {
-		return nil, application.ErrOpportunityNotFound(opportunityID)
-	}
-
-	if opportunity.Status != domain.OpportunityStatusOpen {
-		return nil, application.ErrOpportunityClosed(opportunityID)
+		return nil, err
 	}
 
-	// Parse product ID
-	productID, err := uuid.Parse(req.ProductID)
+	pipeline, err := uc.pipelineRepo.GetByID(ctx, tenantID, opportunity.PipelineID)
 	if err != nil {
-		return nil, application.ErrValidation("invalid product_id format")
+		return nil, application.ErrPipelineNotFound(opportunity.PipelineID)
 	}
 
-	// Verify product exists
-	exists, err := uc.productService.ProductExists(ctx, tenantID, productID)
+	product, err := uc.buildProductLine(ctx, tenantID, uc.productPricing(ctx, tenantID, opportunity.CustomerID, pipeline), (*dto.OpportunityProductRequestDTO)(req))
 	if err != nil {
-		return nil, application.WrapError(application.ErrCodeProductServiceError, "failed to verify product", err)
-	}
-	if !exists {
-		return nil, application.ErrProductNotFound(productID)
+		return nil, err
 	}
+	opportunity.AddProduct(product)
 
-	// Create money
-	unitPrice, err := domain.NewMoney(req.UnitPrice, req.Currency)
-	if err != nil {
-		return nil, application.ErrValidation("invalid unit_price or currency")
-	}
+	return uc.saveProducts(ctx, opportunity, pipeline)
+}
 
-	// Prepare discount
-	discount := float64(0)
-	if req.DiscountPercent != nil {
-		discount = float64(*req.DiscountPercent)
+// ReplaceProducts replaces all products of an opportunity. Every line is
+// validated before the products are changed, so a rejected request leaves
+// them as they were.
+func (uc *opportunityUseCase) ReplaceProducts(ctx context.Context, tenantID, opportunityID, userID uuid.UUID, req *dto.ReplaceProductsRequest) (*dto.OpportunityResponse, error) {
+	opportunity, err := uc.getOpenOpportunity(ctx, tenantID, opportunityID)
+	if err != nil {
+		return nil, err
 	}
 
-	// Add product using OpportunityProduct struct
-	product := domain.OpportunityProduct{
-		ProductID:   productID,
-		ProductName: req.ProductName,
-		Quantity:    req.Quantity,
-		UnitPrice:   unitPrice,
-		Discount:    discount,
-	}
-	if req.Description != nil {
-		product.Notes = *req.Description
+	pipeline, err := uc.pipelineRepo.GetByID(ctx, tenantID, opportunity.PipelineID)
+	if err != nil {
+		return nil, application.ErrPipelineNotFound(opportunity.PipelineID)
 	}
-	opportunity.AddProduct(product)
-
-	// Update metadata
-	opportunity.UpdatedAt = time.Now()
-	opportunity.Version++
 
-	// Save changes
-	if err := uc.opportunityRepo.Update(ctx, opportunity); err != nil {
-		return nil, application.WrapError(application.ErrCodeInternal, "failed to update opportunity", err)
+	pricing := uc.productPricing(ctx, tenantID, opportunity.CustomerID, pipeline)
+	products := make([]domain.OpportunityProduct, 0, len(req.Products))
+	for i, line := range req.Products {
+		if line == nil {
+			return nil, application.ErrValidation(fmt.Sprintf("products[%d] is required", i))
+		}
+		product, err := uc.buildProductLine(ctx, tenantID, pricing, line)
+		if err != nil {
+			return nil, err
+		}
+		products = append(products, product)
 	}
+	opportunity.ReplaceProducts(products)
 
-	// Get pipeline
-	pipeline, _ := uc.pipelineRepo.GetByID(ctx, tenantID, opportunity.PipelineID)
-
-	return uc.mapOpportunityToResponse(ctx, opportunity, pipeline), nil
+	return uc.saveProducts(ctx, opportunity, pipeline)
 }
 
-// UpdateProduct updates a product in an opportunity.
+// UpdateProduct updates a product in an opportunity. The product is found by
+// its line ID or its product ID.
 func (uc *opportunityUseCase) UpdateProduct(ctx context.Context, tenantID, opportunityID, productID, userID uuid.UUID, req *dto.UpdateProductRequest) (*dto.OpportunityResponse, error) {
-	opportunity, err := uc.opportunityRepo.GetByID(ctx, tenantID, opportunityID)
+	opportunity, err := uc.getOpenOpportunity(ctx, tenantID, opportunityID)
 	if err != nil {
-		return nil, application.ErrOpportunityNotFound(opportunityID)
-	}
-
-	if opportunity.Status != domain.OpportunityStatusOpen {
-		return nil, application.ErrOpportunityClosed(opportunityID)
-	}
-
-	// Find the product first
-	var existingProduct *domain.OpportunityProduct
-	for i := range opportunity.Products {
-		if opportunity.Products[i].ProductID == productID {
-			existingProduct = &opportunity.Products[i]
-			break
-		}
+		return nil, err
 	}
 
+	existingProduct := opportunity.FindProduct(productID)
 	if existingProduct == nil {
 		return nil, application.ErrOpportunityProductNotFound(opportunityID, productID)
 	}
+	lineID := existingProduct.ID
 
 	// Prepare update values
 	quantity := existingProduct.Quantity
@@ -910,55 +1115,172 @@ func (uc *opportunityUseCase) UpdateProduct(ctx context.Context, tenantID, oppor
 		quantity = *req.Quantity
 	}
 	if req.UnitPrice != nil {
-		unitPrice, _ = domain.NewMoney(*req.UnitPrice, opportunity.Amount.Currency)
+		unitPrice = domain.Money{Amount: *req.UnitPrice, Currency: unitPrice.Currency}
 	}
 	if req.DiscountPercent != nil {
 		discount = float64(*req.DiscountPercent)
 	}
+	if req.Description != nil {
+		existingProduct.Notes = *req.Description
+	}
 
 	// Use domain's UpdateProduct method
-	if err := opportunity.UpdateProduct(existingProduct.ID, quantity, unitPrice, discount, tax); err != nil {
+	if err := opportunity.UpdateProduct(lineID, quantity, unitPrice, discount, tax); err != nil {
 		return nil, application.WrapError(application.ErrCodeInternal, "failed to update product", err)
 	}
 
-	// Update metadata
-	opportunity.UpdatedAt = time.Now()
-	opportunity.Version++
+	// Change the tax rate when a tax code is given
+	if req.TaxCode != nil {
+		lineTax, err := resolveLineTax(ctx, uc.taxResolver, tenantID, req.TaxCode)
+		if err != nil {
+			return nil, err
+		}
+		if lineTax != nil {
+			if err := opportunity.ApplyProductTax(lineID, lineTax.rate, lineTax.inclusive); err != nil {
+				return nil, application.WrapError(application.ErrCodeInternal, "failed to update product", err)
+			}
+		}
+	}
 
-	// Save changes
-	if err := uc.opportunityRepo.Update(ctx, opportunity); err != nil {
-		return nil, application.WrapError(application.ErrCodeInternal, "failed to update opportunity", err)
+	pipeline, _ := uc.pipelineRepo.GetByID(ctx, tenantID, opportunity.PipelineID)
+	return uc.saveProducts(ctx, opportunity, pipeline)
+}
+
+// RemoveProduct removes a product from an opportunity. The product is found
+// by its line ID or its product ID.
+func (uc *opportunityUseCase) RemoveProduct(ctx context.Context, tenantID, opportunityID, productID, userID uuid.UUID) (*dto.OpportunityResponse, error) {
+	opportunity, err := uc.getOpenOpportunity(ctx, tenantID, opportunityID)
+	if err != nil {
+		return nil, err
 	}
 
-	// Get pipeline
+	product := opportunity.FindProduct(productID)
+	if product == nil {
+		return nil, application.ErrOpportunityProductNotFound(opportunityID, productID)
+	}
+	opportunity.RemoveProduct(product.ID)
+
 	pipeline, _ := uc.pipelineRepo.GetByID(ctx, tenantID, opportunity.PipelineID)
+	return uc.saveProducts(ctx, opportunity, pipeline)
+}
 
-	return uc.mapOpportunityToResponse(ctx, opportunity, pipeline), nil
+// productPricing is how an opportunity's product lines are priced: in the
+// pipeline's currency, from the price list of the customer's tier.
+type productPricing struct {
+	currency string
+	tier     string
 }
 
-// RemoveProduct removes a product from an opportunity.
-func (uc *opportunityUseCase) RemoveProduct(ctx context.Context, tenantID, opportunityID, productID, userID uuid.UUID) (*dto.OpportunityResponse, error) {
-	opportunity, err := uc.opportunityRepo.GetByID(ctx, tenantID, opportunityID)
+// productPricing returns the pricing of the product lines of a customer's
+// opportunity in a pipeline.
+func (uc *opportunityUseCase) productPricing(ctx context.Context, tenantID, customerID uuid.UUID, pipeline *domain.Pipeline) productPricing {
+	pricing := productPricing{currency: pipeline.Currency}
+	if uc.customerService != nil && customerID != uuid.Nil {
+		if customer, err := uc.customerService.GetCustomer(ctx, tenantID, customerID); err == nil && customer != nil {
+			pricing.tier = customer.Tier
+		}
+	}
+	return pricing
+}
+
+// buildProductLine validates a requested product line and prices it. Lines
+// must be in the pipeline's currency. Without a unit price, the line is
+// priced from the price list of the customer's tier, or else at the product's
+// catalogue price.
+func (uc *opportunityUseCase) buildProductLine(ctx context.Context, tenantID uuid.UUID, pricing productPricing, req *dto.OpportunityProductRequestDTO) (domain.OpportunityProduct, error) {
+	productID, err := uuid.Parse(req.ProductID)
 	if err != nil {
-		return nil, application.ErrOpportunityNotFound(opportunityID)
+		return domain.OpportunityProduct{}, application.ErrValidation("invalid product_id format")
 	}
 
-	if opportunity.Status != domain.OpportunityStatusOpen {
-		return nil, application.ErrOpportunityClosed(opportunityID)
+	currency := strings.ToUpper(strings.TrimSpace(req.Currency))
+	if currency == "" {
+		currency = pricing.currency
+	}
+	if currency != pricing.currency {
+		return domain.OpportunityProduct{}, application.ErrValidation(fmt.Sprintf("product currency %s does not match the pipeline currency %s", currency, pricing.currency))
 	}
 
-	// Check if product exists and remove
-	found := false
-	for _, p := range opportunity.Products {
-		if p.ID == productID || p.ProductID == productID {
-			found = true
-			break
+	// Verify the product exists, and look up its catalogue price and category
+	var catalogue *ports.ProductInfo
+	if uc.productService != nil {
+		exists, err := uc.productService.ProductExists(ctx, tenantID, productID)
+		if err != nil {
+			return domain.OpportunityProduct{}, application.WrapError(application.ErrCodeProductServiceError, "failed to verify product", err)
 		}
+		if !exists {
+			return domain.OpportunityProduct{}, application.ErrProductNotFound(productID)
+		}
+		catalogue, _ = uc.productService.GetProduct(ctx, tenantID, productID)
 	}
-	if !found {
-		return nil, application.ErrOpportunityProductNotFound(opportunityID, productID)
+
+	unitPrice, err := uc.resolveUnitPrice(ctx, tenantID, pricing, productID, currency, req.UnitPrice, catalogue)
+	if err != nil {
+		return domain.OpportunityProduct{}, err
+	}
+
+	discount := float64(0)
+	if req.DiscountPercent != nil {
+		discount = float64(*req.DiscountPercent)
+	}
+
+	product := domain.OpportunityProduct{
+		ProductID:   productID,
+		ProductName: req.ProductName,
+		Quantity:    req.Quantity,
+		UnitPrice:   unitPrice,
+		Discount:    discount,
+	}
+	if catalogue != nil {
+		product.SKU = catalogue.SKU
+		if catalogue.Category != nil {
+			product.Category = *catalogue.Category
+		}
+	}
+	if req.Description != nil {
+		product.Notes = *req.Description
 	}
-	opportunity.RemoveProduct(productID)
+
+	// Apply the requested or default tax rate
+	tax, err := resolveLineTax(ctx, uc.taxResolver, tenantID, req.TaxCode)
+	if err != nil {
+		return domain.OpportunityProduct{}, err
+	}
+	if tax != nil {
+		product.ApplyTaxRate(tax.rate, tax.inclusive)
+	}
+
+	return product, nil
+}
+
+// resolveUnitPrice returns the requested unit price, or else the price of the
+// product on the price list of the customer's tier, or else its catalogue
+// price.
+func (uc *opportunityUseCase) resolveUnitPrice(ctx context.Context, tenantID uuid.UUID, pricing productPricing, productID uuid.UUID, currency string, requested *int64, catalogue *ports.ProductInfo) (domain.Money, error) {
+	if requested != nil {
+		return domain.NewMoney(*requested, currency)
+	}
+
+	if uc.priceResolver != nil {
+		applied, err := uc.priceResolver.ResolvePrice(ctx, tenantID, pricing.tier, productID, currency, catalogue)
+		if err != nil {
+			return domain.Money{}, err
+		}
+		if applied != nil {
+			return domain.NewMoney(applied.UnitPrice, applied.Currency)
+		}
+	}
+
+	if catalogue != nil && strings.EqualFold(catalogue.Currency, currency) {
+		return domain.NewMoney(catalogue.UnitPrice, currency)
+	}
+	return domain.Money{}, application.ErrValidation(fmt.Sprintf("unit_price is required: product %s has no price in %s", productID, currency))
+}
+
+// saveProducts saves an opportunity after a change to its products, whose
+// amount and weighted amount follow them.
+func (uc *opportunityUseCase) saveProducts(ctx context.Context, opportunity *domain.Opportunity, pipeline *domain.Pipeline) (*dto.OpportunityResponse, error) {
+	uc.applyBaseCurrency(ctx, opportunity)
 
 	// Update metadata
 	opportunity.UpdatedAt = time.Now()
@@ -969,9 +1291,6 @@ func (uc *opportunityUseCase) RemoveProduct(ctx context.Context, tenantID, oppor
 		return nil, application.WrapError(application.ErrCodeInternal, "failed to update opportunity", err)
 	}
 
-	// Get pipeline
-	pipeline, _ := uc.pipelineRepo.GetByID(ctx, tenantID, opportunity.PipelineID)
-
 	return uc.mapOpportunityToResponse(ctx, opportunity, pipeline), nil
 }
 
@@ -981,13 +1300,9 @@ func (uc *opportunityUseCase) RemoveProduct(ctx context.Context, tenantID, oppor
 
 // AddContact adds a contact to an opportunity.
 func (uc *opportunityUseCase) AddContact(ctx context.Context, tenantID, opportunityID, userID uuid.UUID, req *dto.AddContactRequest) (*dto.OpportunityResponse, error) {
-	opportunity, err := uc.opportunityRepo.GetByID(ctx, tenantID, opportunityID)
+	opportunity, err := uc.getOpenOpportunity(ctx, tenantID, opportunityID)
 	if err != nil {
-		return nil, application.ErrOpportunityNotFound(opportunityID)
-	}
-
-	if opportunity.Status != domain.OpportunityStatusOpen {
-		return nil, application.ErrOpportunityClosed(opportunityID)
+		return nil, err
 	}
 
 	// Parse contact ID
@@ -1006,104 +1321,117 @@ func (uc *opportunityUseCase) AddContact(ctx context.Context, tenantID, opportun
 	}
 
 	// Add contact
-	opportunity.AddContact(domain.OpportunityContact{
+	contact := domain.OpportunityContact{
 		ContactID:  contactID,
 		CustomerID: opportunity.CustomerID,
 		Role:       req.Role,
 		IsPrimary:  req.IsPrimary,
-	})
-
-	// Update metadata
-	opportunity.UpdatedAt = time.Now()
-	opportunity.Version++
-
-	// Save changes
-	if err := uc.opportunityRepo.Update(ctx, opportunity); err != nil {
-		return nil, application.WrapError(application.ErrCodeInternal, "failed to update opportunity", err)
+	}
+	if req.Notes != nil {
+		contact.Notes = *req.Notes
+	}
+	if err := opportunity.AttachContact(contact); err != nil {
+		return nil, uc.contactError(err, opportunityID, contactID)
 	}
 
-	// Get pipeline
-	pipeline, _ := uc.pipelineRepo.GetByID(ctx, tenantID, opportunity.PipelineID)
-
-	return uc.mapOpportunityToResponse(ctx, opportunity, pipeline), nil
+	return uc.saveContacts(ctx, opportunity)
 }
 
-// UpdateContact updates a contact in an opportunity.
+// UpdateContact updates the role, notes or primary designation of a contact in an opportunity.
 func (uc *opportunityUseCase) UpdateContact(ctx context.Context, tenantID, opportunityID, contactID, userID uuid.UUID, req *dto.UpdateContactRequest) (*dto.OpportunityResponse, error) {
-	opportunity, err := uc.opportunityRepo.GetByID(ctx, tenantID, opportunityID)
+	opportunity, err := uc.getOpenOpportunity(ctx, tenantID, opportunityID)
 	if err != nil {
-		return nil, application.ErrOpportunityNotFound(opportunityID)
+		return nil, err
 	}
 
-	if opportunity.Status != domain.OpportunityStatusOpen {
-		return nil, application.ErrOpportunityClosed(opportunityID)
+	role := ""
+	if req.Role != nil {
+		role = *req.Role
 	}
-
-	// Find and update contact
-	contactIdx := -1
-	for i, contact := range opportunity.Contacts {
-		if contact.ContactID == contactID {
-			contactIdx = i
-			break
+	if err := opportunity.UpdateContactRole(contactID, role, req.Notes); err != nil {
+		return nil, uc.contactError(err, opportunityID, contactID)
+	}
+	if req.IsPrimary != nil && *req.IsPrimary {
+		if err := opportunity.SetPrimaryContact(contactID); err != nil {
+			return nil, uc.contactError(err, opportunityID, contactID)
 		}
 	}
 
-	if contactIdx == -1 {
-		return nil, application.ErrOpportunityContactNotFound(opportunityID, contactID)
-	}
+	return uc.saveContacts(ctx, opportunity)
+}
 
-	// Update role
-	if req.Role != nil {
-		opportunity.Contacts[contactIdx].Role = *req.Role
+// RemoveContact removes a contact from an opportunity.
+func (uc *opportunityUseCase) RemoveContact(ctx context.Context, tenantID, opportunityID, contactID, userID uuid.UUID) (*dto.OpportunityResponse, error) {
+	opportunity, err := uc.getOpenOpportunity(ctx, tenantID, opportunityID)
+	if err != nil {
+		return nil, err
 	}
-	// Handle primary contact
-	if req.IsPrimary != nil && *req.IsPrimary {
-		// Remove primary from others first
-		for i := range opportunity.Contacts {
-			opportunity.Contacts[i].IsPrimary = false
-		}
-		opportunity.Contacts[contactIdx].IsPrimary = true
+
+	if opportunity.FindContact(contactID) == nil {
+		return nil, application.ErrOpportunityContactNotFound(opportunityID, contactID)
 	}
+	opportunity.RemoveContact(contactID)
 
-	// Update metadata
-	opportunity.UpdatedAt = time.Now()
-	opportunity.Version++
+	return uc.saveContacts(ctx, opportunity)
+}
 
-	// Save changes
-	if err := uc.opportunityRepo.Update(ctx, opportunity); err != nil {
-		return nil, application.WrapError(application.ErrCodeInternal, "failed to update opportunity", err)
+// SetPrimaryContact makes a contact the primary contact of an opportunity.
+func (uc *opportunityUseCase) SetPrimaryContact(ctx context.Context, tenantID, opportunityID, contactID, userID uuid.UUID) (*dto.OpportunityResponse, error) {
+	opportunity, err := uc.getOpenOpportunity(ctx, tenantID, opportunityID)
+	if err != nil {
+		return nil, err
 	}
 
-	// Get pipeline
-	pipeline, _ := uc.pipelineRepo.GetByID(ctx, tenantID, opportunity.PipelineID)
+	if err := opportunity.SetPrimaryContact(contactID); err != nil {
+		return nil, uc.contactError(err, opportunityID, contactID)
+	}
 
-	return uc.mapOpportunityToResponse(ctx, opportunity, pipeline), nil
+	return uc.saveContacts(ctx, opportunity)
 }
 
-// RemoveContact removes a contact from an opportunity.
-func (uc *opportunityUseCase) RemoveContact(ctx context.Context, tenantID, opportunityID, contactID, userID uuid.UUID) (*dto.OpportunityResponse, error) {
+// getOpenOpportunity loads an opportunity whose contacts may be changed.
+func (uc *opportunityUseCase) getOpenOpportunity(ctx context.Context, tenantID, opportunityID uuid.UUID) (*domain.Opportunity, error) {
 	opportunity, err := uc.opportunityRepo.GetByID(ctx, tenantID, opportunityID)
 	if err != nil {
 		return nil, application.ErrOpportunityNotFound(opportunityID)
 	}
-
 	if opportunity.Status != domain.OpportunityStatusOpen {
 		return nil, application.ErrOpportunityClosed(opportunityID)
 	}
+	return opportunity, nil
+}
 
-	// Check if contact exists and remove
-	contactFound := false
-	for _, c := range opportunity.Contacts {
-		if c.ContactID == contactID {
-			contactFound = true
-			break
+// applyForecastChanges applies the probability override and forecast category
+// of an update request.
+func (uc *opportunityUseCase) applyForecastChanges(ctx context.Context, opportunity *domain.Opportunity, userID uuid.UUID, req *dto.UpdateOpportunityRequest) error {
+	if req.Probability != nil && req.ClearProbabilityOverride {
+		return application.ErrValidation("probability cannot be set while clearing the probability override")
+	}
+
+	if req.Probability != nil || req.ClearProbabilityOverride {
+		pipeline, err := uc.pipelineRepo.GetByID(ctx, opportunity.TenantID, opportunity.PipelineID)
+		if err != nil {
+			return application.ErrPipelineNotFound(opportunity.PipelineID)
+		}
+		stage := pipeline.GetStage(opportunity.StageID)
+		if stage == nil {
+			return application.ErrPipelineStageNotFound(pipeline.ID, opportunity.StageID)
+		}
+		if err := opportunity.SetProbabilityOverride(req.Probability, stage.Probability, userID); err != nil {
+			return application.ErrValidation(err.Error())
 		}
 	}
-	if !contactFound {
-		return nil, application.ErrOpportunityContactNotFound(opportunityID, contactID)
+
+	if req.ForecastCategory != nil {
+		if err := opportunity.SetForecastCategory(domain.ForecastCategory(*req.ForecastCategory), userID); err != nil {
+			return application.ErrValidation(err.Error())
+		}
 	}
-	opportunity.RemoveContact(contactID)
+	return nil
+}
 
+// saveContacts saves an opportunity after a change to its contacts.
+func (uc *opportunityUseCase) saveContacts(ctx context.Context, opportunity *domain.Opportunity) (*dto.OpportunityResponse, error) {
 	// Update metadata
 	opportunity.UpdatedAt = time.Now()
 	opportunity.Version++
@@ -1114,11 +1442,24 @@ func (uc *opportunityUseCase) RemoveContact(ctx context.Context, tenantID, oppor
 	}
 
 	// Get pipeline
-	pipeline, _ := uc.pipelineRepo.GetByID(ctx, tenantID, opportunity.PipelineID)
+	pipeline, _ := uc.pipelineRepo.GetByID(ctx, opportunity.TenantID, opportunity.PipelineID)
 
 	return uc.mapOpportunityToResponse(ctx, opportunity, pipeline), nil
 }
 
+// contactError maps a domain contact error to an application error.
+func (uc *opportunityUseCase) contactError(err error, opportunityID, contactID uuid.UUID) error {
+	switch {
+	case errors.Is(err, domain.ErrInvalidContactRole):
+		return application.ErrValidation("role must be one of decision_maker, influencer, finance, evaluator, champion, blocker, user, technical_contact, billing_contact, other")
+	case errors.Is(err, domain.ErrContactAlreadyAttached):
+		return application.ErrOpportunityContactDuplicate(opportunityID, contactID)
+	case errors.Is(err, domain.ErrContactNotAttached):
+		return application.ErrOpportunityContactNotFound(opportunityID, contactID)
+	}
+	return application.WrapError(application.ErrCodeInternal, "failed to update opportunity contacts", err)
+}
+
 // ============================================================================
 // Competitor Operations
 // ============================================================================
@@ -1288,10 +1629,9 @@ func (uc *opportunityUseCase) GetStatistics(ctx context.Context, tenantID uuid.U
 		}
 	}
 
-	// Get pipeline values (using default currency)
-	currency := "USD"
-	totalPipelineValue, _ := uc.opportunityRepo.GetTotalPipelineValue(ctx, tenantID, currency)
-	weightedPipelineValue, _ := uc.opportunityRepo.GetWeightedPipelineValue(ctx, tenantID, currency)
+	// Get pipeline values in the tenant's base currency
+	currency := uc.baseCurrency(ctx, tenantID)
+	totalPipelineValue, weightedPipelineValue, pipelineByCurrency := uc.getPipelineValue(ctx, tenantID, currency)
 
 	// Get win rate
 	now := time.Now()
@@ -1336,9 +1676,65 @@ func (uc *opportunityUseCase) GetStatistics(ctx context.Context, tenantID uuid.U
 		ByStatus:           statusMap,
 		ClosingThisMonth:   int64(len(closingThisMonth)),
 		ClosingThisQuarter: int64(len(closingThisQuarter)),
+		PipelineByCurrency: pipelineByCurrency,
 	}, nil
 }
 
+// getPipelineValue returns the total and weighted open pipeline value in the
+// given currency with a per-currency breakdown. Amounts in other currencies
+// are counted by their stored base value, or converted at the current rate.
+func (uc *opportunityUseCase) getPipelineValue(ctx context.Context, tenantID uuid.UUID, currency string) (int64, int64, []dto.CurrencyBreakdownDTO) {
+	values, err := uc.opportunityRepo.GetPipelineValueByCurrency(ctx, tenantID)
+	if err != nil {
+		total, _ := uc.opportunityRepo.GetTotalPipelineValue(ctx, tenantID, currency)
+		weighted, _ := uc.opportunityRepo.GetWeightedPipelineValue(ctx, tenantID, currency)
+		return total, weighted, nil
+	}
+
+	totals := newCurrencyTotals(ctx, uc.currencyConverter, tenantID, currency, time.Now())
+	for _, v := range values {
+		stored := &domain.Money{Amount: v.BaseAmount, Currency: v.BaseCurrency}
+		storedWeighted := &domain.Money{Amount: v.BaseWeightedAmount, Currency: v.BaseCurrency}
+		totals.add(v.Currency, v.OpportunityCount, v.Amount, v.WeightedAmount, stored, storedWeighted)
+	}
+
+	return totals.total, totals.weightedTotal, totals.breakdown()
+}
+
+// baseCurrency returns the tenant's base currency, or the default when no converter is configured.
+func (uc *opportunityUseCase) baseCurrency(ctx context.Context, tenantID uuid.UUID) string {
+	if uc.currencyConverter != nil {
+		if currency, err := uc.currencyConverter.BaseCurrency(ctx, tenantID); err == nil {
+			return currency
+		}
+	}
+	return domain.DefaultBaseCurrency
+}
+
+// applyBaseCurrency stores the opportunity value converted into the tenant's
+// base currency. Opportunities without an exchange rate are saved unconverted
+// and reported separately until a rate is available.
+func (uc *opportunityUseCase) applyBaseCurrency(ctx context.Context, opportunity *domain.Opportunity) {
+	if uc.currencyConverter == nil {
+		return
+	}
+
+	baseCurrency, err := uc.currencyConverter.BaseCurrency(ctx, opportunity.TenantID)
+	if err != nil {
+		return
+	}
+
+	rate, err := uc.currencyConverter.Rate(ctx, opportunity.TenantID, opportunity.Amount.Currency, baseCurrency, time.Now())
+	if err != nil {
+		opportunity.ClearExchangeRate()
+		return
+	}
+
+	if err := opportunity.ApplyExchangeRate(baseCurrency, rate); err != nil {
+		opportunity.ClearExchangeRate()
+	}
+}
+
 // GetPipelineAnalytics retrieves pipeline analytics.
 func (uc *opportunityUseCase) GetPipelineAnalytics(ctx context.Context, tenantID, pipelineID uuid.UUID) (*dto.PipelineAnalyticsResponse, error) {
 	// Get pipeline
@@ -1379,6 +1775,15 @@ func (uc *opportunityUseCase) GetPipelineAnalytics(ctx context.Context, tenantID
 		})
 	}
 
+	won, err := uc.opportunityRepo.GetCloseBreakdown(ctx, tenantID, &pipelineID, domain.OpportunityStatusWon)
+	if err != nil {
+		return nil, application.WrapError(application.ErrCodeInternal, "failed to get win reason breakdown", err)
+	}
+	lost, err := uc.opportunityRepo.GetCloseBreakdown(ctx, tenantID, &pipelineID, domain.OpportunityStatusLost)
+	if err != nil {
+		return nil, application.WrapError(application.ErrCodeInternal, "failed to get loss reason breakdown", err)
+	}
+
 	return &dto.PipelineAnalyticsResponse{
 		PipelineID:        pipelineID.String(),
 		PipelineName:      pipeline.Name,
@@ -1394,6 +1799,10 @@ func (uc *opportunityUseCase) GetPipelineAnalytics(ctx context.Context, tenantID
 		WinRate:           stats.WinRate,
 		AverageSalesCycle: stats.AverageSalesCycle,
 		Stages:            stageAnalytics,
+		WinReasons:        mapCloseReasonBreakdown(won.ByReason),
+		LossReasons:       mapCloseReasonBreakdown(lost.ByReason),
+		LossCompetitors:   mapCompetitorBreakdown(lost.ByCompetitor),
+		ForecastCategories: mapForecastCategoryStatistics(stats.ForecastCategories),
 	}, nil
 }
 
@@ -1401,12 +1810,61 @@ func (uc *opportunityUseCase) GetPipelineAnalytics(ctx context.Context, tenantID
 // Helper Methods
 // ============================================================================
 
-func (uc *opportunityUseCase) createDealFromOpportunity(ctx context.Context, opportunity *domain.Opportunity, userID uuid.UUID, req *dto.WinOpportunityRequest) (*domain.Deal, error) {
+// resolveCloseReason returns the reason text and catalog reason ID to close an
+// opportunity with. Once a tenant has active reasons of a type, closing with
+// that outcome must select one of them; free text is only accepted while the
+// catalog is empty.
+func (uc *opportunityUseCase) resolveCloseReason(ctx context.Context, tenantID uuid.UUID, reasonType domain.ReasonType, reasonIDStr *string, freeText string) (string, *uuid.UUID, error) {
+	if reasonIDStr != nil && *reasonIDStr != "" {
+		if uc.reasonRepo == nil {
+			return "", nil, application.ErrOpportunityReasonNotFound(*reasonIDStr)
+		}
+		reasonID, err := uuid.Parse(*reasonIDStr)
+		if err != nil {
+			return "", nil, application.ErrValidation(fmt.Sprintf("invalid %s_reason_id format", reasonType))
+		}
+		reason, err := uc.reasonRepo.GetByID(ctx, tenantID, reasonID)
+		if err != nil {
+			if errors.Is(err, domain.ErrOpportunityReasonNotFound) {
+				return "", nil, application.ErrOpportunityReasonNotFound(reasonID)
+			}
+			return "", nil, application.WrapError(application.ErrCodeInternal, "failed to get opportunity reason", err)
+		}
+		if err := reason.CanCloseAs(reasonType); err != nil {
+			return "", nil, application.ErrOpportunityReasonInvalid(reasonID, string(reasonType))
+		}
+		return reason.Name, &reason.ID, nil
+	}
+
+	if uc.reasonRepo != nil {
+		reasons, err := uc.reasonRepo.List(ctx, tenantID, domain.OpportunityReasonFilter{Type: &reasonType, ActiveOnly: true})
+		if err != nil {
+			return "", nil, application.WrapError(application.ErrCodeInternal, "failed to list opportunity reasons", err)
+		}
+		if len(reasons) > 0 {
+			return "", nil, application.ErrOpportunityReasonRequired(string(reasonType))
+		}
+	}
+
+	if freeText == "" {
+		return "", nil, application.ErrValidation(fmt.Sprintf("%s_reason is required", reasonType))
+	}
+	return freeText, nil, nil
+}
+
+func (uc *opportunityUseCase) createDealFromOpportunity(ctx context.Context, opportunity *domain.Opportunity, renewed *domain.Deal, userID uuid.UUID, req *dto.WinOpportunityRequest) (*domain.Deal, error) {
 	deal, err := domain.NewDealFromOpportunity(opportunity, userID)
 	if err != nil {
 		return nil, err
 	}
 
+	// A renewal continues the subscription of the deal it renews
+	if renewed != nil {
+		if err := deal.ContinueSubscription(renewed); err != nil {
+			return nil, err
+		}
+	}
+
 	// Set additional details from request
 	if req.PaymentTerms != nil {
 		deal.PaymentTerm = domain.PaymentTerm(*req.PaymentTerms)
@@ -1438,6 +1896,59 @@ func (uc *opportunityUseCase) createDealFromOpportunity(ctx context.Context, opp
 }
 
 func (uc *opportunityUseCase) mapOpportunityToResponse(ctx context.Context, opportunity *domain.Opportunity, pipeline *domain.Pipeline) *dto.OpportunityResponse {
+	refs := uc.loadOpportunityRefs(ctx, opportunity.TenantID, []*domain.Opportunity{opportunity})
+	return uc.mapOpportunityWithRefs(opportunity, pipeline, refs)
+}
+
+// opportunityRefs holds the customers and owners referenced by a set of
+// opportunities, fetched from the other services in one call each.
+type opportunityRefs struct {
+	customers map[uuid.UUID]*ports.CustomerInfo
+	owners    map[uuid.UUID]*ports.UserInfo
+}
+
+// loadOpportunityRefs fetches the customers and owners of opportunities.
+// Lookup failures leave the references unresolved.
+func (uc *opportunityUseCase) loadOpportunityRefs(ctx context.Context, tenantID uuid.UUID, opportunities []*domain.Opportunity) opportunityRefs {
+	refs := opportunityRefs{
+		customers: make(map[uuid.UUID]*ports.CustomerInfo),
+		owners:    make(map[uuid.UUID]*ports.UserInfo),
+	}
+
+	var customerIDs, ownerIDs []uuid.UUID
+	seen := make(map[uuid.UUID]bool)
+	for _, opp := range opportunities {
+		if opp.CustomerID != uuid.Nil && !seen[opp.CustomerID] {
+			seen[opp.CustomerID] = true
+			customerIDs = append(customerIDs, opp.CustomerID)
+		}
+		if opp.OwnerID != uuid.Nil && !seen[opp.OwnerID] {
+			seen[opp.OwnerID] = true
+			ownerIDs = append(ownerIDs, opp.OwnerID)
+		}
+	}
+
+	if uc.customerService != nil && len(customerIDs) > 0 {
+		if customers, err := uc.customerService.GetCustomersByIDs(ctx, tenantID, customerIDs); err == nil {
+			for _, customer := range customers {
+				refs.customers[customer.ID] = customer
+			}
+		}
+	}
+	if uc.userService != nil && len(ownerIDs) > 0 {
+		if owners, err := uc.userService.GetUsersByIDs(ctx, tenantID, ownerIDs); err == nil {
+			for _, owner := range owners {
+				refs.owners[owner.ID] = owner
+			}
+		}
+	}
+
+	return refs
+}
+
+// mapOpportunityWithRefs maps an opportunity to a response DTO, resolving its
+// customer and owner from refs.
+func (uc *opportunityUseCase) mapOpportunityWithRefs(opportunity *domain.Opportunity, pipeline *domain.Pipeline, refs opportunityRefs) *dto.OpportunityResponse {
 	resp := &dto.OpportunityResponse{
 		ID:         opportunity.ID.String(),
 		TenantID:   opportunity.TenantID.String(),
@@ -1445,6 +1956,7 @@ func (uc *opportunityUseCase) mapOpportunityToResponse(ctx context.Context, oppo
 		Status:     string(opportunity.Status),
 		PipelineID: opportunity.PipelineID.String(),
 		StageID:    opportunity.StageID.String(),
+		BoardPosition: opportunity.BoardPosition,
 		Amount: dto.MoneyDTO{
 			Amount:   opportunity.Amount.Amount,
 			Currency: opportunity.Amount.Currency,
@@ -1455,7 +1967,9 @@ func (uc *opportunityUseCase) mapOpportunityToResponse(ctx context.Context, oppo
 			Currency: opportunity.WeightedAmount.Currency,
 			Display:  opportunity.WeightedAmount.Format(),
 		},
-		Probability:  opportunity.Probability,
+		Probability:         opportunity.Probability,
+		ProbabilityOverride: opportunity.ProbabilityOverride,
+		ForecastCategory:    string(opportunity.EffectiveForecastCategory()),
 		Source:       opportunity.Source,
 		OwnerID:      opportunity.OwnerID.String(),
 		Tags:         opportunity.Tags,
@@ -1471,6 +1985,18 @@ func (uc *opportunityUseCase) mapOpportunityToResponse(ctx context.Context, oppo
 		resp.ExpectedCloseDate = *opportunity.ExpectedCloseDate
 	}
 
+	// Map base currency values
+	if opportunity.BaseAmount != nil {
+		base := moneyToDTO(*opportunity.BaseAmount)
+		resp.BaseAmount = &base
+		rate := opportunity.ExchangeRate
+		resp.ExchangeRate = &rate
+	}
+	if opportunity.BaseWeightedAmount != nil {
+		baseWeighted := moneyToDTO(*opportunity.BaseWeightedAmount)
+		resp.BaseWeightedAmount = &baseWeighted
+	}
+
 	// Map pipeline info
 	if pipeline != nil {
 		resp.Pipeline = &dto.PipelineBriefDTO{
@@ -1502,16 +2028,14 @@ func (uc *opportunityUseCase) mapOpportunityToResponse(ctx context.Context, oppo
 	if opportunity.CustomerID != uuid.Nil {
 		s := opportunity.CustomerID.String()
 		resp.CustomerID = &s
-		// Fetch customer details if needed
-		if uc.customerService != nil {
-			if customer, err := uc.customerService.GetCustomer(ctx, opportunity.TenantID, opportunity.CustomerID); err == nil {
-				resp.Customer = &dto.CustomerBriefDTO{
-					ID:     customer.ID.String(),
-					Name:   customer.Name,
-					Code:   customer.Code,
-					Type:   customer.Type,
-					Status: customer.Status,
-				}
+		if customer, ok := refs.customers[opportunity.CustomerID]; ok {
+			resp.Customer = &dto.CustomerBriefDTO{
+				ID:      customer.ID.String(),
+				Name:    customer.Name,
+				Code:    customer.Code,
+				Type:    customer.Type,
+				Status:  customer.Status,
+				Segment: opportunity.CustomerSegment,
 			}
 		}
 	}
@@ -1531,6 +2055,9 @@ func (uc *opportunityUseCase) mapOpportunityToResponse(ctx context.Context, oppo
 		s := opportunity.CampaignID.String()
 		resp.CampaignID = &s
 	}
+	if opportunity.Attribution != nil {
+		resp.Attribution = mapAttributionToResponse(opportunity.Attribution, opportunity.Source)
+	}
 	// Notes is string
 	if opportunity.Notes != "" {
 		notes := opportunity.Notes
@@ -1546,6 +2073,10 @@ func (uc *opportunityUseCase) mapOpportunityToResponse(ctx context.Context, oppo
 		if opportunity.Status == domain.OpportunityStatusWon {
 			resp.WonAt = &closeInfo.ClosedAt
 			resp.WonBy = &closedByStr
+			if closeInfo.ReasonID != nil {
+				s := closeInfo.ReasonID.String()
+				resp.WonReasonID = &s
+			}
 			if closeInfo.Reason != "" {
 				resp.WonReason = &closeInfo.Reason
 			}
@@ -1555,6 +2086,10 @@ func (uc *opportunityUseCase) mapOpportunityToResponse(ctx context.Context, oppo
 		} else {
 			resp.LostAt = &closeInfo.ClosedAt
 			resp.LostBy = &closedByStr
+			if closeInfo.ReasonID != nil {
+				s := closeInfo.ReasonID.String()
+				resp.LostReasonID = &s
+			}
 			if closeInfo.Reason != "" {
 				resp.LostReason = &closeInfo.Reason
 			}
@@ -1574,34 +2109,35 @@ func (uc *opportunityUseCase) mapOpportunityToResponse(ctx context.Context, oppo
 	// Map products - use domain fields
 	resp.Products = make([]*dto.OpportunityProductResponseDTO, len(opportunity.Products))
 	for i, product := range opportunity.Products {
-		resp.Products[i] = &dto.OpportunityProductResponseDTO{
-			ID:              product.ID.String(),
-			ProductID:       product.ProductID.String(),
-			ProductName:     product.ProductName,
-			Quantity:        product.Quantity,
-			UnitPrice: dto.MoneyDTO{
-				Amount:   product.UnitPrice.Amount,
-				Currency: product.UnitPrice.Currency,
-			},
-			DiscountPercent: int(product.Discount),
-			TotalPrice: dto.MoneyDTO{
-				Amount:   product.TotalPrice.Amount,
-				Currency: product.TotalPrice.Currency,
-			},
-		}
-		if product.Notes != "" {
-			resp.Products[i].Description = &product.Notes
-		}
+		resp.Products[i] = mapOpportunityProductToResponse(product)
 	}
 	resp.ProductCount = len(opportunity.Products)
+	if len(opportunity.Products) > 0 {
+		taxSummary := mapTaxSummaryToDTO(opportunity.TaxSummary())
+		resp.TaxSummary = &taxSummary
+	}
 
-	// Map contacts - domain has simpler OpportunityContact
+	// Map contacts with their roles
 	resp.Contacts = make([]*dto.OpportunityContactResponseDTO, len(opportunity.Contacts))
 	for i, contact := range opportunity.Contacts {
 		resp.Contacts[i] = &dto.OpportunityContactResponseDTO{
 			ContactID: contact.ContactID.String(),
 			Role:      contact.Role,
 			IsPrimary: contact.IsPrimary,
+			AddedAt:   contact.AddedAt,
+		}
+		if contact.Name != "" || contact.Email != "" {
+			resp.Contacts[i].Contact = &dto.ContactBriefDTO{
+				ID:       contact.ContactID.String(),
+				FullName: contact.Name,
+				Email:    contact.Email,
+			}
+			if contact.Phone != "" {
+				resp.Contacts[i].Contact.Phone = dto.StringPtr(contact.Phone)
+			}
+		}
+		if contact.Notes != "" {
+			resp.Contacts[i].Notes = dto.StringPtr(contact.Notes)
 		}
 	}
 
@@ -1617,10 +2153,11 @@ func (uc *opportunityUseCase) mapOpportunityToResponse(ctx context.Context, oppo
 			}
 		}
 		historyDTO := &dto.StageHistoryDTO{
-			StageID:   history.StageID.String(),
-			StageName: stageName,
-			EnteredAt: history.EnteredAt,
-			ExitedAt:  history.ExitedAt,
+			StageID:         history.StageID.String(),
+			StageName:       stageName,
+			EnteredAt:       history.EnteredAt,
+			ExitedAt:        history.ExitedAt,
+			PipelineVersion: history.PipelineVersion,
 		}
 		if history.Notes != "" {
 			historyDTO.Notes = &history.Notes
@@ -1636,21 +2173,55 @@ func (uc *opportunityUseCase) mapOpportunityToResponse(ctx context.Context, oppo
 	}
 
 	// Get owner info
-	if uc.userService != nil {
-		if owner, err := uc.userService.GetUser(ctx, opportunity.TenantID, opportunity.OwnerID); err == nil {
-			resp.Owner = &dto.UserBriefDTO{
-				ID:        owner.ID.String(),
-				Name:      owner.FullName,
-				Email:     owner.Email,
-				AvatarURL: owner.AvatarURL,
-			}
+	if owner, ok := refs.owners[opportunity.OwnerID]; ok {
+		resp.Owner = &dto.UserBriefDTO{
+			ID:        owner.ID.String(),
+			Name:      owner.FullName,
+			Email:     owner.Email,
+			AvatarURL: owner.AvatarURL,
 		}
 	}
 
 	return resp
 }
 
-func (uc *opportunityUseCase) mapOpportunityToBriefResponse(ctx context.Context, opportunity *domain.Opportunity, pipeline *domain.Pipeline) *dto.OpportunityBriefResponse {
+// mapOpportunityProductToResponse maps a product line, including its tax, to a response DTO.
+func mapOpportunityProductToResponse(product domain.OpportunityProduct) *dto.OpportunityProductResponseDTO {
+	resp := &dto.OpportunityProductResponseDTO{
+		ID:          product.ID.String(),
+		ProductID:   product.ProductID.String(),
+		ProductName: product.ProductName,
+		Category:    product.Category,
+		Quantity:    product.Quantity,
+		UnitPrice: dto.MoneyDTO{
+			Amount:   product.UnitPrice.Amount,
+			Currency: product.UnitPrice.Currency,
+		},
+		DiscountPercent: int(product.Discount),
+		TaxRate:         product.Tax,
+		TaxInclusive:    product.TaxInclusive,
+		TaxAmount: dto.MoneyDTO{
+			Amount:   product.TaxAmount.Amount,
+			Currency: product.TotalPrice.Currency,
+		},
+		TotalPrice: dto.MoneyDTO{
+			Amount:   product.TotalPrice.Amount,
+			Currency: product.TotalPrice.Currency,
+		},
+	}
+	if product.TaxCode != "" {
+		resp.TaxCode = &product.TaxCode
+	}
+	if product.TaxName != "" {
+		resp.TaxName = &product.TaxName
+	}
+	if product.Notes != "" {
+		resp.Description = &product.Notes
+	}
+	return resp
+}
+
+func (uc *opportunityUseCase) mapOpportunityToBriefResponse(opportunity *domain.Opportunity, pipeline *domain.Pipeline, refs opportunityRefs) *dto.OpportunityBriefResponse {
 	stageName := ""
 	if pipeline != nil {
 		if stage := pipeline.GetStage(opportunity.StageID); stage != nil {
@@ -1663,18 +2234,14 @@ func (uc *opportunityUseCase) mapOpportunityToBriefResponse(ctx context.Context,
 	if opportunity.CustomerID != uuid.Nil {
 		s := opportunity.CustomerID.String()
 		customerID = &s
-		if uc.customerService != nil {
-			if customer, err := uc.customerService.GetCustomer(ctx, opportunity.TenantID, opportunity.CustomerID); err == nil {
-				customerName = &customer.Name
-			}
+		if customer, ok := refs.customers[opportunity.CustomerID]; ok {
+			customerName = &customer.Name
 		}
 	}
 
 	ownerName := ""
-	if uc.userService != nil {
-		if owner, err := uc.userService.GetUser(ctx, opportunity.TenantID, opportunity.OwnerID); err == nil {
-			ownerName = owner.FullName
-		}
+	if owner, ok := refs.owners[opportunity.OwnerID]; ok {
+		ownerName = owner.FullName
 	}
 
 	resp := &dto.OpportunityBriefResponse{
@@ -1692,6 +2259,7 @@ func (uc *opportunityUseCase) mapOpportunityToBriefResponse(ctx context.Context,
 			Display:  opportunity.WeightedAmount.Format(),
 		},
 		Probability: opportunity.Probability,
+		ForecastCategory: string(opportunity.EffectiveForecastCategory()),
 		StageID:     opportunity.StageID.String(),
 		StageName:   stageName,
 		CustomerID:  customerID,
@@ -1843,12 +2411,20 @@ func (uc *opportunityUseCase) publishEvent(ctx context.Context, event domain.Dom
 		return nil
 	}
 
+	// The payload carries the fields of the event, such as the forecast
+	// values before and after a change
+	var payload map[string]interface{}
+	if data, err := json.Marshal(event); err == nil {
+		json.Unmarshal(data, &payload)
+	}
+
 	return uc.eventPublisher.Publish(ctx, ports.Event{
 		ID:            event.EventID().String(),
 		Type:          event.EventType(),
 		AggregateID:   event.AggregateID().String(),
 		AggregateType: event.AggregateType(),
 		TenantID:      event.TenantID().String(),
+		Payload:       payload,
 		OccurredAt:    event.OccurredAt(),
 		Version:       event.Version(),
 	})
@@ -1904,3 +2480,33 @@ func (uc *opportunityUseCase) invalidateOpportunityCache(ctx context.Context, te
 	pattern := "opportunity:" + tenantID.String() + ":*"
 	uc.cacheService.DeletePattern(ctx, pattern)
 }
+
+func mapCloseReasonBreakdown(entries []*domain.CloseBreakdownEntry) []*dto.CloseReasonBreakdownDTO {
+	result := make([]*dto.CloseReasonBreakdownDTO, len(entries))
+	for i, entry := range entries {
+		result[i] = &dto.CloseReasonBreakdownDTO{
+			Reason: entry.Reason,
+			Count:  entry.Count,
+			Amount: moneyToDTO(domain.Money{Amount: entry.Amount, Currency: entry.Currency}),
+		}
+		if entry.ReasonID != nil {
+			result[i].ReasonID = entry.ReasonID.String()
+		}
+	}
+	return result
+}
+
+func mapCompetitorBreakdown(entries []*domain.CloseBreakdownEntry) []*dto.CompetitorBreakdownDTO {
+	result := make([]*dto.CompetitorBreakdownDTO, len(entries))
+	for i, entry := range entries {
+		result[i] = &dto.CompetitorBreakdownDTO{
+			CompetitorName: entry.CompetitorName,
+			Count:          entry.Count,
+			Amount:         moneyToDTO(domain.Money{Amount: entry.Amount, Currency: entry.Currency}),
+		}
+		if entry.CompetitorID != nil {
+			result[i].CompetitorID = entry.CompetitorID.String()
+		}
+	}
+	return result
+}
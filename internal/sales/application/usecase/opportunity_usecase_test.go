@@ -3,11 +3,13 @@ package usecase
 import (
 	"context"
 	"errors"
+	"sort"
 	"testing"
 	"time"
 
 	"github.com/google/uuid"
 
+	"github.com/kilang-desa-murni/crm/internal/sales/application"
 	"github.com/kilang-desa-murni/crm/internal/sales/application/dto"
 	"github.com/kilang-desa-murni/crm/internal/sales/application/ports"
 	"github.com/kilang-desa-murni/crm/internal/sales/domain"
@@ -126,6 +128,27 @@ func (m *ExtendedMockOpportunityRepository) GetByStage(ctx context.Context, tena
 	return result, int64(len(result)), nil
 }
 
+func (m *ExtendedMockOpportunityRepository) GetBoardPositionNeighbors(ctx context.Context, tenantID, stageID, excludeID uuid.UUID, index int) (string, string, error) {
+	var positions []string
+	for _, opp := range m.opportunities {
+		if opp.TenantID == tenantID && opp.StageID == stageID && opp.ID != excludeID {
+			positions = append(positions, opp.BoardPosition)
+		}
+	}
+	sort.Strings(positions)
+
+	before := ""
+	if index > 0 && len(positions) > 0 {
+		before = positions[min(index, len(positions))-1]
+	}
+	for _, position := range positions {
+		if position > before {
+			return before, position, nil
+		}
+	}
+	return before, "", nil
+}
+
 func (m *ExtendedMockOpportunityRepository) GetByCustomer(ctx context.Context, tenantID, customerID uuid.UUID, opts domain.ListOptions) ([]*domain.Opportunity, int64, error) {
 	var result []*domain.Opportunity
 	for _, opp := range m.opportunities {
@@ -276,6 +299,26 @@ func (m *ExtendedMockOpportunityRepository) GetWeightedPipelineValue(ctx context
 	return total, nil
 }
 
+func (m *ExtendedMockOpportunityRepository) GetPipelineValueByCurrency(ctx context.Context, tenantID uuid.UUID) ([]*domain.PipelineCurrencyValue, error) {
+	byCurrency := make(map[string]*domain.PipelineCurrencyValue)
+	var result []*domain.PipelineCurrencyValue
+	for _, opp := range m.opportunities {
+		if opp.TenantID != tenantID || opp.Status != domain.OpportunityStatusOpen {
+			continue
+		}
+		value, ok := byCurrency[opp.Amount.Currency]
+		if !ok {
+			value = &domain.PipelineCurrencyValue{Currency: opp.Amount.Currency}
+			byCurrency[opp.Amount.Currency] = value
+			result = append(result, value)
+		}
+		value.OpportunityCount++
+		value.Amount += opp.Amount.Amount
+		value.WeightedAmount += opp.WeightedAmount.Amount
+	}
+	return result, nil
+}
+
 func (m *ExtendedMockOpportunityRepository) GetWinRate(ctx context.Context, tenantID uuid.UUID, start, end time.Time) (float64, error) {
 	var won, total int64
 	for _, opp := range m.opportunities {
@@ -313,6 +356,10 @@ func (m *ExtendedMockOpportunityRepository) GetAverageSalesCycle(ctx context.Con
 	return 30, nil
 }
 
+func (m *ExtendedMockOpportunityRepository) GetCloseBreakdown(ctx context.Context, tenantID uuid.UUID, pipelineID *uuid.UUID, status domain.OpportunityStatus) (*domain.CloseBreakdown, error) {
+	return &domain.CloseBreakdown{}, nil
+}
+
 func (m *ExtendedMockOpportunityRepository) GetClosingThisMonth(ctx context.Context, tenantID uuid.UUID, opts domain.ListOptions) ([]*domain.Opportunity, int64, error) {
 	var result []*domain.Opportunity
 	now := time.Now()
@@ -415,6 +462,19 @@ func (m *ExtendedMockCustomerService) GetCustomer(ctx context.Context, tenantID,
 	return customer, nil
 }
 
+func (m *ExtendedMockCustomerService) GetCustomersByIDs(ctx context.Context, tenantID uuid.UUID, customerIDs []uuid.UUID) ([]*ports.CustomerInfo, error) {
+	if m.getErr != nil {
+		return nil, m.getErr
+	}
+	var result []*ports.CustomerInfo
+	for _, id := range customerIDs {
+		if customer, ok := m.customers[id]; ok {
+			result = append(result, customer)
+		}
+	}
+	return result, nil
+}
+
 func (m *ExtendedMockCustomerService) GetCustomerByCode(ctx context.Context, tenantID uuid.UUID, code string) (*ports.CustomerInfo, error) {
 	for _, customer := range m.customers {
 		if customer.Code == code {
@@ -469,6 +529,16 @@ func (m *ExtendedMockCustomerService) CreateContact(ctx context.Context, tenantI
 	return contact, nil
 }
 
+func (m *ExtendedMockCustomerService) DeleteCustomer(ctx context.Context, tenantID, customerID uuid.UUID) error {
+	delete(m.customers, customerID)
+	return nil
+}
+
+func (m *ExtendedMockCustomerService) DeleteContact(ctx context.Context, tenantID, customerID, contactID uuid.UUID) error {
+	delete(m.contacts, contactID)
+	return nil
+}
+
 // ExtendedMockProductService extends MockProductService for opportunity tests.
 type ExtendedMockProductService struct {
 	products    map[uuid.UUID]*ports.ProductInfo
@@ -670,7 +740,7 @@ func TestOpportunityUseCase_Create_Success(t *testing.T) {
 	// Arrange
 	oppRepo, pipelineRepo, dealRepo, eventPublisher, customerService, userService, productService, cacheService, searchService, idGenerator := setupOpportunityTestDependencies()
 
-	uc := NewOpportunityUseCase(oppRepo, pipelineRepo, dealRepo, eventPublisher, customerService, userService, productService, cacheService, searchService, idGenerator)
+	uc := NewOpportunityUseCase(oppRepo, pipelineRepo, dealRepo, eventPublisher, customerService, userService, productService, cacheService, searchService, idGenerator, nil, nil, nil, nil, nil)
 
 	tenantID := uuid.New()
 	userID := uuid.New()
@@ -724,7 +794,7 @@ func TestOpportunityUseCase_Create_PipelineNotFound(t *testing.T) {
 	// Arrange
 	oppRepo, pipelineRepo, dealRepo, eventPublisher, customerService, userService, productService, cacheService, searchService, idGenerator := setupOpportunityTestDependencies()
 
-	uc := NewOpportunityUseCase(oppRepo, pipelineRepo, dealRepo, eventPublisher, customerService, userService, productService, cacheService, searchService, idGenerator)
+	uc := NewOpportunityUseCase(oppRepo, pipelineRepo, dealRepo, eventPublisher, customerService, userService, productService, cacheService, searchService, idGenerator, nil, nil, nil, nil, nil)
 
 	tenantID := uuid.New()
 	userID := uuid.New()
@@ -750,7 +820,7 @@ func TestOpportunityUseCase_Create_InvalidPipelineID(t *testing.T) {
 	// Arrange
 	oppRepo, pipelineRepo, dealRepo, eventPublisher, customerService, userService, productService, cacheService, searchService, idGenerator := setupOpportunityTestDependencies()
 
-	uc := NewOpportunityUseCase(oppRepo, pipelineRepo, dealRepo, eventPublisher, customerService, userService, productService, cacheService, searchService, idGenerator)
+	uc := NewOpportunityUseCase(oppRepo, pipelineRepo, dealRepo, eventPublisher, customerService, userService, productService, cacheService, searchService, idGenerator, nil, nil, nil, nil, nil)
 
 	tenantID := uuid.New()
 	userID := uuid.New()
@@ -776,7 +846,7 @@ func TestOpportunityUseCase_Create_CustomerNotFound(t *testing.T) {
 	// Arrange
 	oppRepo, pipelineRepo, dealRepo, eventPublisher, customerService, userService, productService, cacheService, searchService, idGenerator := setupOpportunityTestDependencies()
 
-	uc := NewOpportunityUseCase(oppRepo, pipelineRepo, dealRepo, eventPublisher, customerService, userService, productService, cacheService, searchService, idGenerator)
+	uc := NewOpportunityUseCase(oppRepo, pipelineRepo, dealRepo, eventPublisher, customerService, userService, productService, cacheService, searchService, idGenerator, nil, nil, nil, nil, nil)
 
 	tenantID := uuid.New()
 	userID := uuid.New()
@@ -817,7 +887,7 @@ func TestOpportunityUseCase_GetByID_Success(t *testing.T) {
 	// Arrange
 	oppRepo, pipelineRepo, dealRepo, eventPublisher, customerService, userService, productService, cacheService, searchService, idGenerator := setupOpportunityTestDependencies()
 
-	uc := NewOpportunityUseCase(oppRepo, pipelineRepo, dealRepo, eventPublisher, customerService, userService, productService, cacheService, searchService, idGenerator)
+	uc := NewOpportunityUseCase(oppRepo, pipelineRepo, dealRepo, eventPublisher, customerService, userService, productService, cacheService, searchService, idGenerator, nil, nil, nil, nil, nil)
 
 	tenantID := uuid.New()
 	pipeline := createOpportunityTestPipeline(tenantID)
@@ -860,7 +930,7 @@ func TestOpportunityUseCase_GetByID_NotFound(t *testing.T) {
 	// Arrange
 	oppRepo, pipelineRepo, dealRepo, eventPublisher, customerService, userService, productService, cacheService, searchService, idGenerator := setupOpportunityTestDependencies()
 
-	uc := NewOpportunityUseCase(oppRepo, pipelineRepo, dealRepo, eventPublisher, customerService, userService, productService, cacheService, searchService, idGenerator)
+	uc := NewOpportunityUseCase(oppRepo, pipelineRepo, dealRepo, eventPublisher, customerService, userService, productService, cacheService, searchService, idGenerator, nil, nil, nil, nil, nil)
 
 	// Act
 	_, err := uc.GetByID(context.Background(), uuid.New(), uuid.New())
@@ -871,6 +941,59 @@ func TestOpportunityUseCase_GetByID_NotFound(t *testing.T) {
 	}
 }
 
+func TestOpportunityUseCase_BatchGet_Success(t *testing.T) {
+	// Arrange
+	oppRepo, pipelineRepo, dealRepo, eventPublisher, customerService, userService, productService, cacheService, searchService, idGenerator := setupOpportunityTestDependencies()
+
+	uc := NewOpportunityUseCase(oppRepo, pipelineRepo, dealRepo, eventPublisher, customerService, userService, productService, cacheService, searchService, idGenerator, nil, nil, nil, nil, nil)
+
+	tenantID := uuid.New()
+	pipeline := createOpportunityTestPipeline(tenantID)
+	pipelineRepo.pipelines[pipeline.ID] = pipeline
+
+	opp := createTestOpportunityWithPipeline(tenantID, pipeline)
+	oppRepo.opportunities[opp.ID] = opp
+	customerService.customers[opp.CustomerID] = &ports.CustomerInfo{
+		ID:   opp.CustomerID,
+		Name: opp.CustomerName,
+	}
+	missing := uuid.New()
+
+	// Act
+	result, err := uc.BatchGet(context.Background(), tenantID, &dto.BatchGetOpportunitiesRequest{
+		IDs: []string{missing.String(), opp.ID.String(), opp.ID.String()},
+	})
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if len(result.Found) != 1 || result.Found[0].ID != opp.ID.String() {
+		t.Fatalf("Expected the opportunity to be found once, got %+v", result.Found)
+	}
+	if result.Found[0].Customer == nil || result.Found[0].Customer.Name != opp.CustomerName {
+		t.Errorf("Expected the customer to be resolved, got %+v", result.Found[0].Customer)
+	}
+	if len(result.Missing) != 1 || result.Missing[0] != missing.String() {
+		t.Errorf("Expected %s to be missing, got %v", missing, result.Missing)
+	}
+}
+
+func TestOpportunityUseCase_BatchGet_InvalidID(t *testing.T) {
+	// Arrange
+	oppRepo, pipelineRepo, dealRepo, eventPublisher, customerService, userService, productService, cacheService, searchService, idGenerator := setupOpportunityTestDependencies()
+
+	uc := NewOpportunityUseCase(oppRepo, pipelineRepo, dealRepo, eventPublisher, customerService, userService, productService, cacheService, searchService, idGenerator, nil, nil, nil, nil, nil)
+
+	// Act
+	_, err := uc.BatchGet(context.Background(), uuid.New(), &dto.BatchGetOpportunitiesRequest{IDs: []string{"not-a-uuid"}})
+
+	// Assert
+	if err == nil {
+		t.Fatal("Expected error for invalid id, got nil")
+	}
+}
+
 // ============================================================================
 // OpportunityUseCase Tests - Update
 // ============================================================================
@@ -879,7 +1002,7 @@ func TestOpportunityUseCase_Update_Success(t *testing.T) {
 	// Arrange
 	oppRepo, pipelineRepo, dealRepo, eventPublisher, customerService, userService, productService, cacheService, searchService, idGenerator := setupOpportunityTestDependencies()
 
-	uc := NewOpportunityUseCase(oppRepo, pipelineRepo, dealRepo, eventPublisher, customerService, userService, productService, cacheService, searchService, idGenerator)
+	uc := NewOpportunityUseCase(oppRepo, pipelineRepo, dealRepo, eventPublisher, customerService, userService, productService, cacheService, searchService, idGenerator, nil, nil, nil, nil, nil)
 
 	tenantID := uuid.New()
 	userID := uuid.New()
@@ -923,7 +1046,7 @@ func TestOpportunityUseCase_Update_NotFound(t *testing.T) {
 	// Arrange
 	oppRepo, pipelineRepo, dealRepo, eventPublisher, customerService, userService, productService, cacheService, searchService, idGenerator := setupOpportunityTestDependencies()
 
-	uc := NewOpportunityUseCase(oppRepo, pipelineRepo, dealRepo, eventPublisher, customerService, userService, productService, cacheService, searchService, idGenerator)
+	uc := NewOpportunityUseCase(oppRepo, pipelineRepo, dealRepo, eventPublisher, customerService, userService, productService, cacheService, searchService, idGenerator, nil, nil, nil, nil, nil)
 
 	newName := "Updated Name"
 	req := &dto.UpdateOpportunityRequest{
@@ -944,7 +1067,7 @@ func TestOpportunityUseCase_Update_VersionMismatch(t *testing.T) {
 	// Arrange
 	oppRepo, pipelineRepo, dealRepo, eventPublisher, customerService, userService, productService, cacheService, searchService, idGenerator := setupOpportunityTestDependencies()
 
-	uc := NewOpportunityUseCase(oppRepo, pipelineRepo, dealRepo, eventPublisher, customerService, userService, productService, cacheService, searchService, idGenerator)
+	uc := NewOpportunityUseCase(oppRepo, pipelineRepo, dealRepo, eventPublisher, customerService, userService, productService, cacheService, searchService, idGenerator, nil, nil, nil, nil, nil)
 
 	tenantID := uuid.New()
 	pipeline := createOpportunityTestPipeline(tenantID)
@@ -973,7 +1096,7 @@ func TestOpportunityUseCase_Update_ClosedOpportunity(t *testing.T) {
 	// Arrange
 	oppRepo, pipelineRepo, dealRepo, eventPublisher, customerService, userService, productService, cacheService, searchService, idGenerator := setupOpportunityTestDependencies()
 
-	uc := NewOpportunityUseCase(oppRepo, pipelineRepo, dealRepo, eventPublisher, customerService, userService, productService, cacheService, searchService, idGenerator)
+	uc := NewOpportunityUseCase(oppRepo, pipelineRepo, dealRepo, eventPublisher, customerService, userService, productService, cacheService, searchService, idGenerator, nil, nil, nil, nil, nil)
 
 	tenantID := uuid.New()
 	pipeline := createOpportunityTestPipeline(tenantID)
@@ -1006,7 +1129,7 @@ func TestOpportunityUseCase_Delete_Success(t *testing.T) {
 	// Arrange
 	oppRepo, pipelineRepo, dealRepo, eventPublisher, customerService, userService, productService, cacheService, searchService, idGenerator := setupOpportunityTestDependencies()
 
-	uc := NewOpportunityUseCase(oppRepo, pipelineRepo, dealRepo, eventPublisher, customerService, userService, productService, cacheService, searchService, idGenerator)
+	uc := NewOpportunityUseCase(oppRepo, pipelineRepo, dealRepo, eventPublisher, customerService, userService, productService, cacheService, searchService, idGenerator, nil, nil, nil, nil, nil)
 
 	tenantID := uuid.New()
 	userID := uuid.New()
@@ -1034,7 +1157,7 @@ func TestOpportunityUseCase_Delete_NotFound(t *testing.T) {
 	// Arrange
 	oppRepo, pipelineRepo, dealRepo, eventPublisher, customerService, userService, productService, cacheService, searchService, idGenerator := setupOpportunityTestDependencies()
 
-	uc := NewOpportunityUseCase(oppRepo, pipelineRepo, dealRepo, eventPublisher, customerService, userService, productService, cacheService, searchService, idGenerator)
+	uc := NewOpportunityUseCase(oppRepo, pipelineRepo, dealRepo, eventPublisher, customerService, userService, productService, cacheService, searchService, idGenerator, nil, nil, nil, nil, nil)
 
 	// Act
 	err := uc.Delete(context.Background(), uuid.New(), uuid.New(), uuid.New())
@@ -1049,7 +1172,7 @@ func TestOpportunityUseCase_Delete_ClosedOpportunity(t *testing.T) {
 	// Arrange
 	oppRepo, pipelineRepo, dealRepo, eventPublisher, customerService, userService, productService, cacheService, searchService, idGenerator := setupOpportunityTestDependencies()
 
-	uc := NewOpportunityUseCase(oppRepo, pipelineRepo, dealRepo, eventPublisher, customerService, userService, productService, cacheService, searchService, idGenerator)
+	uc := NewOpportunityUseCase(oppRepo, pipelineRepo, dealRepo, eventPublisher, customerService, userService, productService, cacheService, searchService, idGenerator, nil, nil, nil, nil, nil)
 
 	tenantID := uuid.New()
 	pipeline := createOpportunityTestPipeline(tenantID)
@@ -1076,7 +1199,7 @@ func TestOpportunityUseCase_List_Success(t *testing.T) {
 	// Arrange
 	oppRepo, pipelineRepo, dealRepo, eventPublisher, customerService, userService, productService, cacheService, searchService, idGenerator := setupOpportunityTestDependencies()
 
-	uc := NewOpportunityUseCase(oppRepo, pipelineRepo, dealRepo, eventPublisher, customerService, userService, productService, cacheService, searchService, idGenerator)
+	uc := NewOpportunityUseCase(oppRepo, pipelineRepo, dealRepo, eventPublisher, customerService, userService, productService, cacheService, searchService, idGenerator, nil, nil, nil, nil, nil)
 
 	tenantID := uuid.New()
 	pipeline := createOpportunityTestPipeline(tenantID)
@@ -1118,7 +1241,7 @@ func TestOpportunityUseCase_List_Empty(t *testing.T) {
 	// Arrange
 	oppRepo, pipelineRepo, dealRepo, eventPublisher, customerService, userService, productService, cacheService, searchService, idGenerator := setupOpportunityTestDependencies()
 
-	uc := NewOpportunityUseCase(oppRepo, pipelineRepo, dealRepo, eventPublisher, customerService, userService, productService, cacheService, searchService, idGenerator)
+	uc := NewOpportunityUseCase(oppRepo, pipelineRepo, dealRepo, eventPublisher, customerService, userService, productService, cacheService, searchService, idGenerator, nil, nil, nil, nil, nil)
 
 	filter := &dto.OpportunityFilterRequest{
 		PageSize: 10,
@@ -1147,7 +1270,7 @@ func TestOpportunityUseCase_MoveStage_Success(t *testing.T) {
 	// Arrange
 	oppRepo, pipelineRepo, dealRepo, eventPublisher, customerService, userService, productService, cacheService, searchService, idGenerator := setupOpportunityTestDependencies()
 
-	uc := NewOpportunityUseCase(oppRepo, pipelineRepo, dealRepo, eventPublisher, customerService, userService, productService, cacheService, searchService, idGenerator)
+	uc := NewOpportunityUseCase(oppRepo, pipelineRepo, dealRepo, eventPublisher, customerService, userService, productService, cacheService, searchService, idGenerator, nil, nil, nil, nil, nil)
 
 	tenantID := uuid.New()
 	userID := uuid.New()
@@ -1185,7 +1308,7 @@ func TestOpportunityUseCase_MoveStage_OpportunityNotFound(t *testing.T) {
 	// Arrange
 	oppRepo, pipelineRepo, dealRepo, eventPublisher, customerService, userService, productService, cacheService, searchService, idGenerator := setupOpportunityTestDependencies()
 
-	uc := NewOpportunityUseCase(oppRepo, pipelineRepo, dealRepo, eventPublisher, customerService, userService, productService, cacheService, searchService, idGenerator)
+	uc := NewOpportunityUseCase(oppRepo, pipelineRepo, dealRepo, eventPublisher, customerService, userService, productService, cacheService, searchService, idGenerator, nil, nil, nil, nil, nil)
 
 	req := &dto.MoveStageRequest{
 		StageID: uuid.New().String(),
@@ -1204,7 +1327,7 @@ func TestOpportunityUseCase_MoveStage_ClosedOpportunity(t *testing.T) {
 	// Arrange
 	oppRepo, pipelineRepo, dealRepo, eventPublisher, customerService, userService, productService, cacheService, searchService, idGenerator := setupOpportunityTestDependencies()
 
-	uc := NewOpportunityUseCase(oppRepo, pipelineRepo, dealRepo, eventPublisher, customerService, userService, productService, cacheService, searchService, idGenerator)
+	uc := NewOpportunityUseCase(oppRepo, pipelineRepo, dealRepo, eventPublisher, customerService, userService, productService, cacheService, searchService, idGenerator, nil, nil, nil, nil, nil)
 
 	tenantID := uuid.New()
 	pipeline := createOpportunityTestPipeline(tenantID)
@@ -1231,7 +1354,7 @@ func TestOpportunityUseCase_MoveStage_InvalidStageID(t *testing.T) {
 	// Arrange
 	oppRepo, pipelineRepo, dealRepo, eventPublisher, customerService, userService, productService, cacheService, searchService, idGenerator := setupOpportunityTestDependencies()
 
-	uc := NewOpportunityUseCase(oppRepo, pipelineRepo, dealRepo, eventPublisher, customerService, userService, productService, cacheService, searchService, idGenerator)
+	uc := NewOpportunityUseCase(oppRepo, pipelineRepo, dealRepo, eventPublisher, customerService, userService, productService, cacheService, searchService, idGenerator, nil, nil, nil, nil, nil)
 
 	tenantID := uuid.New()
 	pipeline := createOpportunityTestPipeline(tenantID)
@@ -1253,6 +1376,112 @@ func TestOpportunityUseCase_MoveStage_InvalidStageID(t *testing.T) {
 	}
 }
 
+func TestOpportunityUseCase_MoveStage_EntryCriteriaNotMet(t *testing.T) {
+	// Arrange
+	oppRepo, pipelineRepo, dealRepo, eventPublisher, customerService, userService, productService, cacheService, searchService, idGenerator := setupOpportunityTestDependencies()
+
+	uc := NewOpportunityUseCase(oppRepo, pipelineRepo, dealRepo, eventPublisher, customerService, userService, productService, cacheService, searchService, idGenerator, nil, nil, nil, nil, nil)
+
+	tenantID := uuid.New()
+	pipeline := createOpportunityTestPipeline(tenantID)
+	pipeline.Stages[1].EntryCriteria = domain.StageEntryCriteria{
+		RequiredFields: []string{domain.StageFieldPrimaryContact},
+		MinActivities:  1,
+	}
+	pipelineRepo.pipelines[pipeline.ID] = pipeline
+
+	opp := createTestOpportunityWithPipeline(tenantID, pipeline)
+	oppRepo.opportunities[opp.ID] = opp
+
+	req := &dto.MoveStageRequest{
+		StageID: pipeline.Stages[1].ID.String(),
+	}
+
+	// Act
+	_, err := uc.MoveStage(context.Background(), tenantID, opp.ID, uuid.New(), req)
+
+	// Assert
+	appErr := application.GetAppError(err)
+	if appErr == nil || appErr.Code != application.ErrCodePipelineStageEntryCriteriaNotMet {
+		t.Fatalf("Expected entry criteria error, got: %v", err)
+	}
+	if unmet, _ := appErr.Details["unmet_criteria"].([]string); len(unmet) != 2 {
+		t.Errorf("Expected 2 unmet criteria, got %v", appErr.Details["unmet_criteria"])
+	}
+	if opp.StageID != pipeline.Stages[0].ID {
+		t.Error("Expected opportunity to stay in its stage")
+	}
+}
+
+func TestOpportunityUseCase_MoveStage_WIPLimitReached(t *testing.T) {
+	// Arrange
+	oppRepo, pipelineRepo, dealRepo, eventPublisher, customerService, userService, productService, cacheService, searchService, idGenerator := setupOpportunityTestDependencies()
+
+	uc := NewOpportunityUseCase(oppRepo, pipelineRepo, dealRepo, eventPublisher, customerService, userService, productService, cacheService, searchService, idGenerator, nil, nil, nil, nil, nil)
+
+	tenantID := uuid.New()
+	pipeline := createOpportunityTestPipeline(tenantID)
+	stage := pipeline.Stages[1]
+	stage.WIPLimit = 1
+	pipelineRepo.pipelines[pipeline.ID] = pipeline
+
+	other := createTestOpportunityWithPipeline(tenantID, pipeline)
+	other.StageID = stage.ID
+	oppRepo.opportunities[other.ID] = other
+
+	opp := createTestOpportunityWithPipeline(tenantID, pipeline)
+	oppRepo.opportunities[opp.ID] = opp
+
+	req := &dto.MoveStageRequest{
+		StageID: stage.ID.String(),
+	}
+
+	// Act
+	_, err := uc.MoveStage(context.Background(), tenantID, opp.ID, uuid.New(), req)
+
+	// Assert
+	if appErr := application.GetAppError(err); appErr == nil || appErr.Code != application.ErrCodePipelineStageWIPLimitReached {
+		t.Fatalf("Expected WIP limit error, got: %v", err)
+	}
+}
+
+func TestOpportunityUseCase_MoveStage_OverrideRules(t *testing.T) {
+	// Arrange
+	oppRepo, pipelineRepo, dealRepo, eventPublisher, customerService, userService, productService, cacheService, searchService, idGenerator := setupOpportunityTestDependencies()
+
+	uc := NewOpportunityUseCase(oppRepo, pipelineRepo, dealRepo, eventPublisher, customerService, userService, productService, cacheService, searchService, idGenerator, nil, nil, nil, nil, nil)
+
+	tenantID := uuid.New()
+	pipeline := createOpportunityTestPipeline(tenantID)
+	stage := pipeline.Stages[1]
+	stage.WIPLimit = 1
+	stage.EntryCriteria.MinActivities = 3
+	pipelineRepo.pipelines[pipeline.ID] = pipeline
+
+	other := createTestOpportunityWithPipeline(tenantID, pipeline)
+	other.StageID = stage.ID
+	oppRepo.opportunities[other.ID] = other
+
+	opp := createTestOpportunityWithPipeline(tenantID, pipeline)
+	oppRepo.opportunities[opp.ID] = opp
+
+	req := &dto.MoveStageRequest{
+		StageID:       stage.ID.String(),
+		OverrideRules: true,
+	}
+
+	// Act
+	result, err := uc.MoveStage(context.Background(), tenantID, opp.ID, uuid.New(), req)
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if result.StageID != stage.ID.String() {
+		t.Errorf("Expected stage ID %s, got %s", stage.ID, result.StageID)
+	}
+}
+
 // ============================================================================
 // OpportunityUseCase Tests - Win
 // ============================================================================
@@ -1261,7 +1490,7 @@ func TestOpportunityUseCase_Win_Success(t *testing.T) {
 	// Arrange
 	oppRepo, pipelineRepo, dealRepo, eventPublisher, customerService, userService, productService, cacheService, searchService, idGenerator := setupOpportunityTestDependencies()
 
-	uc := NewOpportunityUseCase(oppRepo, pipelineRepo, dealRepo, eventPublisher, customerService, userService, productService, cacheService, searchService, idGenerator)
+	uc := NewOpportunityUseCase(oppRepo, pipelineRepo, dealRepo, eventPublisher, customerService, userService, productService, cacheService, searchService, idGenerator, nil, nil, nil, nil, nil)
 
 	tenantID := uuid.New()
 	userID := uuid.New()
@@ -1299,7 +1528,7 @@ func TestOpportunityUseCase_Win_AlreadyWon(t *testing.T) {
 	// Arrange
 	oppRepo, pipelineRepo, dealRepo, eventPublisher, customerService, userService, productService, cacheService, searchService, idGenerator := setupOpportunityTestDependencies()
 
-	uc := NewOpportunityUseCase(oppRepo, pipelineRepo, dealRepo, eventPublisher, customerService, userService, productService, cacheService, searchService, idGenerator)
+	uc := NewOpportunityUseCase(oppRepo, pipelineRepo, dealRepo, eventPublisher, customerService, userService, productService, cacheService, searchService, idGenerator, nil, nil, nil, nil, nil)
 
 	tenantID := uuid.New()
 	pipeline := createOpportunityTestPipeline(tenantID)
@@ -1326,7 +1555,7 @@ func TestOpportunityUseCase_Win_AlreadyLost(t *testing.T) {
 	// Arrange
 	oppRepo, pipelineRepo, dealRepo, eventPublisher, customerService, userService, productService, cacheService, searchService, idGenerator := setupOpportunityTestDependencies()
 
-	uc := NewOpportunityUseCase(oppRepo, pipelineRepo, dealRepo, eventPublisher, customerService, userService, productService, cacheService, searchService, idGenerator)
+	uc := NewOpportunityUseCase(oppRepo, pipelineRepo, dealRepo, eventPublisher, customerService, userService, productService, cacheService, searchService, idGenerator, nil, nil, nil, nil, nil)
 
 	tenantID := uuid.New()
 	pipeline := createOpportunityTestPipeline(tenantID)
@@ -1357,7 +1586,7 @@ func TestOpportunityUseCase_Lose_Success(t *testing.T) {
 	// Arrange
 	oppRepo, pipelineRepo, dealRepo, eventPublisher, customerService, userService, productService, cacheService, searchService, idGenerator := setupOpportunityTestDependencies()
 
-	uc := NewOpportunityUseCase(oppRepo, pipelineRepo, dealRepo, eventPublisher, customerService, userService, productService, cacheService, searchService, idGenerator)
+	uc := NewOpportunityUseCase(oppRepo, pipelineRepo, dealRepo, eventPublisher, customerService, userService, productService, cacheService, searchService, idGenerator, nil, nil, nil, nil, nil)
 
 	tenantID := uuid.New()
 	userID := uuid.New()
@@ -1396,7 +1625,7 @@ func TestOpportunityUseCase_Lose_AlreadyWon(t *testing.T) {
 	// Arrange
 	oppRepo, pipelineRepo, dealRepo, eventPublisher, customerService, userService, productService, cacheService, searchService, idGenerator := setupOpportunityTestDependencies()
 
-	uc := NewOpportunityUseCase(oppRepo, pipelineRepo, dealRepo, eventPublisher, customerService, userService, productService, cacheService, searchService, idGenerator)
+	uc := NewOpportunityUseCase(oppRepo, pipelineRepo, dealRepo, eventPublisher, customerService, userService, productService, cacheService, searchService, idGenerator, nil, nil, nil, nil, nil)
 
 	tenantID := uuid.New()
 	pipeline := createOpportunityTestPipeline(tenantID)
@@ -1423,7 +1652,7 @@ func TestOpportunityUseCase_Lose_AlreadyLost(t *testing.T) {
 	// Arrange
 	oppRepo, pipelineRepo, dealRepo, eventPublisher, customerService, userService, productService, cacheService, searchService, idGenerator := setupOpportunityTestDependencies()
 
-	uc := NewOpportunityUseCase(oppRepo, pipelineRepo, dealRepo, eventPublisher, customerService, userService, productService, cacheService, searchService, idGenerator)
+	uc := NewOpportunityUseCase(oppRepo, pipelineRepo, dealRepo, eventPublisher, customerService, userService, productService, cacheService, searchService, idGenerator, nil, nil, nil, nil, nil)
 
 	tenantID := uuid.New()
 	pipeline := createOpportunityTestPipeline(tenantID)
@@ -1446,6 +1675,105 @@ func TestOpportunityUseCase_Lose_AlreadyLost(t *testing.T) {
 	}
 }
 
+// ============================================================================
+// OpportunityUseCase Tests - Renewals
+// ============================================================================
+
+func TestOpportunityUseCase_CreateRenewalOpportunities(t *testing.T) {
+	// Arrange
+	oppRepo, pipelineRepo, dealRepo, eventPublisher, customerService, userService, productService, cacheService, searchService, idGenerator := setupOpportunityTestDependencies()
+
+	uc := NewOpportunityUseCase(oppRepo, pipelineRepo, dealRepo, eventPublisher, customerService, userService, productService, cacheService, searchService, idGenerator, nil, nil, nil, nil, nil)
+
+	tenantID := uuid.New()
+	pipeline := createOpportunityTestPipeline(tenantID)
+	pipelineRepo.pipelines[pipeline.ID] = pipeline
+
+	now := time.Now().UTC()
+	due := createDealTestRecurringDeal(tenantID, now.AddDate(-1, 0, 10), 50000)
+	due.Status = domain.DealStatusActive
+	notDue := createDealTestRecurringDeal(tenantID, now.AddDate(0, -1, 0), 50000)
+	notDue.Status = domain.DealStatusActive
+	dealRepo.deals[due.ID] = due
+	dealRepo.deals[notDue.ID] = notDue
+
+	// Act
+	created, err := uc.CreateRenewalOpportunities(context.Background(), now)
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if created != 1 {
+		t.Fatalf("Expected 1 renewal opportunity, got %d", created)
+	}
+	if due.Subscription.RenewalStatus != domain.RenewalStatusInProgress || due.Subscription.RenewalOpportunityID == nil {
+		t.Fatalf("Expected the renewal in progress, got %+v", due.Subscription)
+	}
+	renewal := oppRepo.opportunities[*due.Subscription.RenewalOpportunityID]
+	if renewal == nil || renewal.Source != domain.RenewalOpportunitySource || renewal.PipelineID != pipeline.ID {
+		t.Fatalf("Expected a renewal opportunity in the default pipeline, got %+v", renewal)
+	}
+	if renewal.Amount.Amount != 600000 || renewal.Amount.Currency != "MYR" {
+		t.Errorf("Expected the renewal worth another year, got %+v", renewal.Amount)
+	}
+
+	// Renewals already started are not created again
+	if created, _ := uc.CreateRenewalOpportunities(context.Background(), now); created != 0 {
+		t.Errorf("Expected no renewal opportunities on the second run, got %d", created)
+	}
+
+	// Winning the renewal renews the deal and continues its subscription
+	userService.users[renewal.OwnerID] = &ports.UserInfo{ID: renewal.OwnerID, FullName: "Owner"}
+	result, err := uc.Win(context.Background(), tenantID, renewal.ID, uuid.New(), &dto.WinOpportunityRequest{WonReason: "Renewed", CreateDeal: true})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if due.Subscription.RenewalStatus != domain.RenewalStatusRenewed {
+		t.Errorf("Expected the deal renewed, got %s", due.Subscription.RenewalStatus)
+	}
+	if result.DealID == nil {
+		t.Fatal("Expected a renewal deal")
+	}
+	next := dealRepo.deals[uuid.MustParse(*result.DealID)]
+	if !next.IsRecurring() || !next.Subscription.ContractStart.Equal(due.Subscription.ContractEnd) ||
+		next.Subscription.RenewedFromDealID == nil || *next.Subscription.RenewedFromDealID != due.ID {
+		t.Errorf("Expected the renewal deal to continue the subscription, got %+v", next.Subscription)
+	}
+}
+
+func TestOpportunityUseCase_Lose_Renewal(t *testing.T) {
+	// Arrange
+	oppRepo, pipelineRepo, dealRepo, eventPublisher, customerService, userService, productService, cacheService, searchService, idGenerator := setupOpportunityTestDependencies()
+
+	uc := NewOpportunityUseCase(oppRepo, pipelineRepo, dealRepo, eventPublisher, customerService, userService, productService, cacheService, searchService, idGenerator, nil, nil, nil, nil, nil)
+
+	tenantID := uuid.New()
+	pipeline := createOpportunityTestPipeline(tenantID)
+	pipelineRepo.pipelines[pipeline.ID] = pipeline
+
+	opp := createTestOpportunityWithPipeline(tenantID, pipeline)
+	opp.Source = domain.RenewalOpportunitySource
+	oppRepo.opportunities[opp.ID] = opp
+
+	deal := createDealTestRecurringDeal(tenantID, time.Now().UTC().AddDate(-1, 0, 10), 50000)
+	deal.Status = domain.DealStatusActive
+	deal.Subscription.RenewalStatus = domain.RenewalStatusInProgress
+	deal.Subscription.RenewalOpportunityID = &opp.ID
+	dealRepo.deals[deal.ID] = deal
+
+	// Act
+	_, err := uc.Lose(context.Background(), tenantID, opp.ID, uuid.New(), &dto.LoseOpportunityRequest{LostReason: "Budget cut"})
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if deal.Subscription.RenewalStatus != domain.RenewalStatusChurned || deal.Subscription.ChurnReason != "Budget cut" {
+		t.Errorf("Expected the deal churned for the lost reason, got %+v", deal.Subscription)
+	}
+}
+
 // ============================================================================
 // OpportunityUseCase Tests - GetStatistics
 // ============================================================================
@@ -1454,7 +1782,7 @@ func TestOpportunityUseCase_GetStatistics_Success(t *testing.T) {
 	// Arrange
 	oppRepo, pipelineRepo, dealRepo, eventPublisher, customerService, userService, productService, cacheService, searchService, idGenerator := setupOpportunityTestDependencies()
 
-	uc := NewOpportunityUseCase(oppRepo, pipelineRepo, dealRepo, eventPublisher, customerService, userService, productService, cacheService, searchService, idGenerator)
+	uc := NewOpportunityUseCase(oppRepo, pipelineRepo, dealRepo, eventPublisher, customerService, userService, productService, cacheService, searchService, idGenerator, nil, nil, nil, nil, nil)
 
 	tenantID := uuid.New()
 	pipeline := createOpportunityTestPipeline(tenantID)
@@ -1506,7 +1834,7 @@ func TestOpportunityUseCase_Assign_Success(t *testing.T) {
 	// Arrange
 	oppRepo, pipelineRepo, dealRepo, eventPublisher, customerService, userService, productService, cacheService, searchService, idGenerator := setupOpportunityTestDependencies()
 
-	uc := NewOpportunityUseCase(oppRepo, pipelineRepo, dealRepo, eventPublisher, customerService, userService, productService, cacheService, searchService, idGenerator)
+	uc := NewOpportunityUseCase(oppRepo, pipelineRepo, dealRepo, eventPublisher, customerService, userService, productService, cacheService, searchService, idGenerator, nil, nil, nil, nil, nil)
 
 	tenantID := uuid.New()
 	userID := uuid.New()
@@ -1544,7 +1872,7 @@ func TestOpportunityUseCase_Assign_UserNotFound(t *testing.T) {
 	// Arrange
 	oppRepo, pipelineRepo, dealRepo, eventPublisher, customerService, userService, productService, cacheService, searchService, idGenerator := setupOpportunityTestDependencies()
 
-	uc := NewOpportunityUseCase(oppRepo, pipelineRepo, dealRepo, eventPublisher, customerService, userService, productService, cacheService, searchService, idGenerator)
+	uc := NewOpportunityUseCase(oppRepo, pipelineRepo, dealRepo, eventPublisher, customerService, userService, productService, cacheService, searchService, idGenerator, nil, nil, nil, nil, nil)
 
 	tenantID := uuid.New()
 	pipeline := createOpportunityTestPipeline(tenantID)
@@ -1640,7 +1968,7 @@ func TestOpportunityUseCase_Create_ValidationCases(t *testing.T) {
 			oppRepo, pipelineRepo, dealRepo, eventPublisher, customerService, userService, productService, cacheService, searchService, idGenerator := setupOpportunityTestDependencies()
 			tt.setupMocks(pipelineRepo, customerService, userService)
 
-			uc := NewOpportunityUseCase(oppRepo, pipelineRepo, dealRepo, eventPublisher, customerService, userService, productService, cacheService, searchService, idGenerator)
+			uc := NewOpportunityUseCase(oppRepo, pipelineRepo, dealRepo, eventPublisher, customerService, userService, productService, cacheService, searchService, idGenerator, nil, nil, nil, nil, nil)
 
 			tenantID := uuid.MustParse("11111111-1111-1111-1111-111111111111")
 			userID := uuid.MustParse("44444444-4444-4444-4444-444444444444")
@@ -1726,7 +2054,7 @@ func TestOpportunityUseCase_Update_ValidationCases(t *testing.T) {
 
 			tenantID, oppID := tt.setupOpp(oppRepo, pipelineRepo)
 
-			uc := NewOpportunityUseCase(oppRepo, pipelineRepo, dealRepo, eventPublisher, customerService, userService, productService, cacheService, searchService, idGenerator)
+			uc := NewOpportunityUseCase(oppRepo, pipelineRepo, dealRepo, eventPublisher, customerService, userService, productService, cacheService, searchService, idGenerator, nil, nil, nil, nil, nil)
 
 			_, err := uc.Update(context.Background(), tenantID, oppID, uuid.New(), tt.request)
 
@@ -1792,7 +2120,7 @@ func TestOpportunityUseCase_GetByID_ValidationCases(t *testing.T) {
 				}
 			}
 
-			uc := NewOpportunityUseCase(oppRepo, pipelineRepo, dealRepo, eventPublisher, customerService, userService, productService, cacheService, searchService, idGenerator)
+			uc := NewOpportunityUseCase(oppRepo, pipelineRepo, dealRepo, eventPublisher, customerService, userService, productService, cacheService, searchService, idGenerator, nil, nil, nil, nil, nil)
 
 			_, err := uc.GetByID(context.Background(), tt.tenantID, tt.oppID)
 
@@ -1823,7 +2151,7 @@ func BenchmarkOpportunityUseCase_GetByID(b *testing.B) {
 	customerService.customers[opp.CustomerID] = &ports.CustomerInfo{ID: opp.CustomerID, Name: opp.CustomerName}
 	userService.users[opp.OwnerID] = &ports.UserInfo{ID: opp.OwnerID, FullName: opp.OwnerName}
 
-	uc := NewOpportunityUseCase(oppRepo, pipelineRepo, dealRepo, eventPublisher, customerService, userService, productService, cacheService, searchService, idGenerator)
+	uc := NewOpportunityUseCase(oppRepo, pipelineRepo, dealRepo, eventPublisher, customerService, userService, productService, cacheService, searchService, idGenerator, nil, nil, nil, nil, nil)
 	ctx := context.Background()
 
 	b.ResetTimer()
@@ -1848,7 +2176,7 @@ func BenchmarkOpportunityUseCase_List(b *testing.B) {
 		userService.users[opp.OwnerID] = &ports.UserInfo{ID: opp.OwnerID, FullName: opp.OwnerName}
 	}
 
-	uc := NewOpportunityUseCase(oppRepo, pipelineRepo, dealRepo, eventPublisher, customerService, userService, productService, cacheService, searchService, idGenerator)
+	uc := NewOpportunityUseCase(oppRepo, pipelineRepo, dealRepo, eventPublisher, customerService, userService, productService, cacheService, searchService, idGenerator, nil, nil, nil, nil, nil)
 	ctx := context.Background()
 	filter := &dto.OpportunityFilterRequest{PageSize: 10}
 
@@ -1872,7 +2200,7 @@ func BenchmarkOpportunityUseCase_Update(b *testing.B) {
 	customerService.customers[opp.CustomerID] = &ports.CustomerInfo{ID: opp.CustomerID, Name: opp.CustomerName}
 	userService.users[opp.OwnerID] = &ports.UserInfo{ID: opp.OwnerID, FullName: opp.OwnerName}
 
-	uc := NewOpportunityUseCase(oppRepo, pipelineRepo, dealRepo, eventPublisher, customerService, userService, productService, cacheService, searchService, idGenerator)
+	uc := NewOpportunityUseCase(oppRepo, pipelineRepo, dealRepo, eventPublisher, customerService, userService, productService, cacheService, searchService, idGenerator, nil, nil, nil, nil, nil)
 	ctx := context.Background()
 
 	b.ResetTimer()
@@ -1908,7 +2236,7 @@ func BenchmarkOpportunityUseCase_GetStatistics(b *testing.B) {
 		oppRepo.opportunities[opp.ID] = opp
 	}
 
-	uc := NewOpportunityUseCase(oppRepo, pipelineRepo, dealRepo, eventPublisher, customerService, userService, productService, cacheService, searchService, idGenerator)
+	uc := NewOpportunityUseCase(oppRepo, pipelineRepo, dealRepo, eventPublisher, customerService, userService, productService, cacheService, searchService, idGenerator, nil, nil, nil, nil, nil)
 	ctx := context.Background()
 
 	b.ResetTimer()
@@ -1925,7 +2253,7 @@ func TestOpportunityUseCase_GetByID_ContextTimeout(t *testing.T) {
 	// Arrange
 	oppRepo, pipelineRepo, dealRepo, eventPublisher, customerService, userService, productService, cacheService, searchService, idGenerator := setupOpportunityTestDependencies()
 
-	uc := NewOpportunityUseCase(oppRepo, pipelineRepo, dealRepo, eventPublisher, customerService, userService, productService, cacheService, searchService, idGenerator)
+	uc := NewOpportunityUseCase(oppRepo, pipelineRepo, dealRepo, eventPublisher, customerService, userService, productService, cacheService, searchService, idGenerator, nil, nil, nil, nil, nil)
 
 	tenantID := uuid.New()
 	pipeline := createOpportunityTestPipeline(tenantID)
@@ -1949,7 +2277,7 @@ func TestOpportunityUseCase_List_ContextTimeout(t *testing.T) {
 	// Arrange
 	oppRepo, pipelineRepo, dealRepo, eventPublisher, customerService, userService, productService, cacheService, searchService, idGenerator := setupOpportunityTestDependencies()
 
-	uc := NewOpportunityUseCase(oppRepo, pipelineRepo, dealRepo, eventPublisher, customerService, userService, productService, cacheService, searchService, idGenerator)
+	uc := NewOpportunityUseCase(oppRepo, pipelineRepo, dealRepo, eventPublisher, customerService, userService, productService, cacheService, searchService, idGenerator, nil, nil, nil, nil, nil)
 
 	tenantID := uuid.New()
 	pipeline := createOpportunityTestPipeline(tenantID)
@@ -1975,7 +2303,7 @@ func TestOpportunityUseCase_Update_ContextTimeout(t *testing.T) {
 	// Arrange
 	oppRepo, pipelineRepo, dealRepo, eventPublisher, customerService, userService, productService, cacheService, searchService, idGenerator := setupOpportunityTestDependencies()
 
-	uc := NewOpportunityUseCase(oppRepo, pipelineRepo, dealRepo, eventPublisher, customerService, userService, productService, cacheService, searchService, idGenerator)
+	uc := NewOpportunityUseCase(oppRepo, pipelineRepo, dealRepo, eventPublisher, customerService, userService, productService, cacheService, searchService, idGenerator, nil, nil, nil, nil, nil)
 
 	tenantID := uuid.New()
 	pipeline := createOpportunityTestPipeline(tenantID)
@@ -2002,7 +2330,7 @@ func TestOpportunityUseCase_Delete_ContextTimeout(t *testing.T) {
 	// Arrange
 	oppRepo, pipelineRepo, dealRepo, eventPublisher, customerService, userService, productService, cacheService, searchService, idGenerator := setupOpportunityTestDependencies()
 
-	uc := NewOpportunityUseCase(oppRepo, pipelineRepo, dealRepo, eventPublisher, customerService, userService, productService, cacheService, searchService, idGenerator)
+	uc := NewOpportunityUseCase(oppRepo, pipelineRepo, dealRepo, eventPublisher, customerService, userService, productService, cacheService, searchService, idGenerator, nil, nil, nil, nil, nil)
 
 	tenantID := uuid.New()
 	pipeline := createOpportunityTestPipeline(tenantID)
@@ -2023,7 +2351,7 @@ func TestOpportunityUseCase_Win_ContextTimeout(t *testing.T) {
 	// Arrange
 	oppRepo, pipelineRepo, dealRepo, eventPublisher, customerService, userService, productService, cacheService, searchService, idGenerator := setupOpportunityTestDependencies()
 
-	uc := NewOpportunityUseCase(oppRepo, pipelineRepo, dealRepo, eventPublisher, customerService, userService, productService, cacheService, searchService, idGenerator)
+	uc := NewOpportunityUseCase(oppRepo, pipelineRepo, dealRepo, eventPublisher, customerService, userService, productService, cacheService, searchService, idGenerator, nil, nil, nil, nil, nil)
 
 	tenantID := uuid.New()
 	pipeline := createOpportunityTestPipeline(tenantID)
@@ -2048,7 +2376,7 @@ func TestOpportunityUseCase_Lose_ContextTimeout(t *testing.T) {
 	// Arrange
 	oppRepo, pipelineRepo, dealRepo, eventPublisher, customerService, userService, productService, cacheService, searchService, idGenerator := setupOpportunityTestDependencies()
 
-	uc := NewOpportunityUseCase(oppRepo, pipelineRepo, dealRepo, eventPublisher, customerService, userService, productService, cacheService, searchService, idGenerator)
+	uc := NewOpportunityUseCase(oppRepo, pipelineRepo, dealRepo, eventPublisher, customerService, userService, productService, cacheService, searchService, idGenerator, nil, nil, nil, nil, nil)
 
 	tenantID := uuid.New()
 	pipeline := createOpportunityTestPipeline(tenantID)
@@ -2073,7 +2401,7 @@ func TestOpportunityUseCase_MoveStage_ContextTimeout(t *testing.T) {
 	// Arrange
 	oppRepo, pipelineRepo, dealRepo, eventPublisher, customerService, userService, productService, cacheService, searchService, idGenerator := setupOpportunityTestDependencies()
 
-	uc := NewOpportunityUseCase(oppRepo, pipelineRepo, dealRepo, eventPublisher, customerService, userService, productService, cacheService, searchService, idGenerator)
+	uc := NewOpportunityUseCase(oppRepo, pipelineRepo, dealRepo, eventPublisher, customerService, userService, productService, cacheService, searchService, idGenerator, nil, nil, nil, nil, nil)
 
 	tenantID := uuid.New()
 	pipeline := createOpportunityTestPipeline(tenantID)
@@ -2098,7 +2426,7 @@ func TestOpportunityUseCase_GetStatistics_ContextTimeout(t *testing.T) {
 	// Arrange
 	oppRepo, pipelineRepo, dealRepo, eventPublisher, customerService, userService, productService, cacheService, searchService, idGenerator := setupOpportunityTestDependencies()
 
-	uc := NewOpportunityUseCase(oppRepo, pipelineRepo, dealRepo, eventPublisher, customerService, userService, productService, cacheService, searchService, idGenerator)
+	uc := NewOpportunityUseCase(oppRepo, pipelineRepo, dealRepo, eventPublisher, customerService, userService, productService, cacheService, searchService, idGenerator, nil, nil, nil, nil, nil)
 
 	tenantID := uuid.New()
 
@@ -2128,7 +2456,7 @@ func TestOpportunityUseCase_Create_RepositoryError(t *testing.T) {
 	// Set repository error
 	oppRepo.createErr = errors.New("database connection failed")
 
-	uc := NewOpportunityUseCase(oppRepo, pipelineRepo, dealRepo, eventPublisher, customerService, userService, productService, cacheService, searchService, idGenerator)
+	uc := NewOpportunityUseCase(oppRepo, pipelineRepo, dealRepo, eventPublisher, customerService, userService, productService, cacheService, searchService, idGenerator, nil, nil, nil, nil, nil)
 
 	req := &dto.CreateOpportunityRequest{
 		Name:              "New Opportunity",
@@ -2161,7 +2489,7 @@ func TestOpportunityUseCase_Update_RepositoryError(t *testing.T) {
 	// Set repository error
 	oppRepo.updateErr = errors.New("database connection failed")
 
-	uc := NewOpportunityUseCase(oppRepo, pipelineRepo, dealRepo, eventPublisher, customerService, userService, productService, cacheService, searchService, idGenerator)
+	uc := NewOpportunityUseCase(oppRepo, pipelineRepo, dealRepo, eventPublisher, customerService, userService, productService, cacheService, searchService, idGenerator, nil, nil, nil, nil, nil)
 
 	newName := "Updated Name"
 	req := &dto.UpdateOpportunityRequest{
@@ -2192,7 +2520,7 @@ func TestOpportunityUseCase_Delete_RepositoryError(t *testing.T) {
 	// Set repository error
 	oppRepo.deleteErr = errors.New("database connection failed")
 
-	uc := NewOpportunityUseCase(oppRepo, pipelineRepo, dealRepo, eventPublisher, customerService, userService, productService, cacheService, searchService, idGenerator)
+	uc := NewOpportunityUseCase(oppRepo, pipelineRepo, dealRepo, eventPublisher, customerService, userService, productService, cacheService, searchService, idGenerator, nil, nil, nil, nil, nil)
 
 	// Act
 	err := uc.Delete(context.Background(), tenantID, opp.ID, uuid.New())
@@ -2210,7 +2538,7 @@ func TestOpportunityUseCase_List_RepositoryError(t *testing.T) {
 	// Set repository error
 	oppRepo.listErr = errors.New("database connection failed")
 
-	uc := NewOpportunityUseCase(oppRepo, pipelineRepo, dealRepo, eventPublisher, customerService, userService, productService, cacheService, searchService, idGenerator)
+	uc := NewOpportunityUseCase(oppRepo, pipelineRepo, dealRepo, eventPublisher, customerService, userService, productService, cacheService, searchService, idGenerator, nil, nil, nil, nil, nil)
 
 	filter := &dto.OpportunityFilterRequest{PageSize: 10}
 
@@ -2230,7 +2558,7 @@ func TestOpportunityUseCase_GetStatistics_RepositoryError(t *testing.T) {
 	// Set repository error
 	oppRepo.countByStatusErr = errors.New("database connection failed")
 
-	uc := NewOpportunityUseCase(oppRepo, pipelineRepo, dealRepo, eventPublisher, customerService, userService, productService, cacheService, searchService, idGenerator)
+	uc := NewOpportunityUseCase(oppRepo, pipelineRepo, dealRepo, eventPublisher, customerService, userService, productService, cacheService, searchService, idGenerator, nil, nil, nil, nil, nil)
 
 	// Act
 	_, err := uc.GetStatistics(context.Background(), uuid.New())
@@ -2249,7 +2577,7 @@ func TestOpportunityUseCase_BulkAssign_Success(t *testing.T) {
 	// Arrange
 	oppRepo, pipelineRepo, dealRepo, eventPublisher, customerService, userService, productService, cacheService, searchService, idGenerator := setupOpportunityTestDependencies()
 
-	uc := NewOpportunityUseCase(oppRepo, pipelineRepo, dealRepo, eventPublisher, customerService, userService, productService, cacheService, searchService, idGenerator)
+	uc := NewOpportunityUseCase(oppRepo, pipelineRepo, dealRepo, eventPublisher, customerService, userService, productService, cacheService, searchService, idGenerator, nil, nil, nil, nil, nil)
 
 	tenantID := uuid.New()
 	userID := uuid.New()
@@ -2286,7 +2614,7 @@ func TestOpportunityUseCase_BulkMoveStage_Success(t *testing.T) {
 	// Arrange
 	oppRepo, pipelineRepo, dealRepo, eventPublisher, customerService, userService, productService, cacheService, searchService, idGenerator := setupOpportunityTestDependencies()
 
-	uc := NewOpportunityUseCase(oppRepo, pipelineRepo, dealRepo, eventPublisher, customerService, userService, productService, cacheService, searchService, idGenerator)
+	uc := NewOpportunityUseCase(oppRepo, pipelineRepo, dealRepo, eventPublisher, customerService, userService, productService, cacheService, searchService, idGenerator, nil, nil, nil, nil, nil)
 
 	tenantID := uuid.New()
 	userID := uuid.New()
@@ -2320,7 +2648,7 @@ func TestOpportunityUseCase_GetPipelineAnalytics_Success(t *testing.T) {
 	// Arrange
 	oppRepo, pipelineRepo, dealRepo, eventPublisher, customerService, userService, productService, cacheService, searchService, idGenerator := setupOpportunityTestDependencies()
 
-	uc := NewOpportunityUseCase(oppRepo, pipelineRepo, dealRepo, eventPublisher, customerService, userService, productService, cacheService, searchService, idGenerator)
+	uc := NewOpportunityUseCase(oppRepo, pipelineRepo, dealRepo, eventPublisher, customerService, userService, productService, cacheService, searchService, idGenerator, nil, nil, nil, nil, nil)
 
 	tenantID := uuid.New()
 	pipeline := createOpportunityTestPipeline(tenantID)
@@ -2345,7 +2673,7 @@ func TestOpportunityUseCase_GetPipelineAnalytics_PipelineNotFound(t *testing.T)
 	// Arrange
 	oppRepo, pipelineRepo, dealRepo, eventPublisher, customerService, userService, productService, cacheService, searchService, idGenerator := setupOpportunityTestDependencies()
 
-	uc := NewOpportunityUseCase(oppRepo, pipelineRepo, dealRepo, eventPublisher, customerService, userService, productService, cacheService, searchService, idGenerator)
+	uc := NewOpportunityUseCase(oppRepo, pipelineRepo, dealRepo, eventPublisher, customerService, userService, productService, cacheService, searchService, idGenerator, nil, nil, nil, nil, nil)
 
 	// Act
 	_, err := uc.GetPipelineAnalytics(context.Background(), uuid.New(), uuid.New())
@@ -2355,3 +2683,283 @@ func TestOpportunityUseCase_GetPipelineAnalytics_PipelineNotFound(t *testing.T)
 		t.Fatal("Expected error for pipeline not found, got nil")
 	}
 }
+
+// ============================================================================
+// OpportunityUseCase Tests - Reposition
+// ============================================================================
+
+func TestOpportunityUseCase_Reposition_Success(t *testing.T) {
+	// Arrange
+	oppRepo, pipelineRepo, dealRepo, eventPublisher, customerService, userService, productService, cacheService, searchService, idGenerator := setupOpportunityTestDependencies()
+
+	uc := NewOpportunityUseCase(oppRepo, pipelineRepo, dealRepo, eventPublisher, customerService, userService, productService, cacheService, searchService, idGenerator, nil, nil, nil, nil, nil)
+
+	tenantID := uuid.New()
+	pipeline := createOpportunityTestPipeline(tenantID)
+	pipelineRepo.pipelines[pipeline.ID] = pipeline
+
+	stage := pipeline.Stages[1]
+	positions := []string{"a", "b", "c"}
+	for _, position := range positions {
+		other := createTestOpportunityWithPipeline(tenantID, pipeline)
+		other.StageID = stage.ID
+		other.BoardPosition = position
+		oppRepo.opportunities[other.ID] = other
+	}
+
+	opp := createTestOpportunityWithPipeline(tenantID, pipeline)
+	opp.BoardPosition = domain.DefaultBoardPosition(opp.StageEnteredAt)
+	oppRepo.opportunities[opp.ID] = opp
+
+	req := &dto.RepositionOpportunityRequest{
+		StageID:  stage.ID.String(),
+		Position: 2,
+	}
+
+	// Act
+	result, err := uc.Reposition(context.Background(), tenantID, opp.ID, uuid.New(), req)
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if result.StageID != stage.ID.String() {
+		t.Errorf("Expected stage ID %s, got %s", stage.ID, result.StageID)
+	}
+	if result.BoardPosition <= "b" || result.BoardPosition >= "c" {
+		t.Errorf("Expected position between b and c, got %s", result.BoardPosition)
+	}
+}
+
+func TestOpportunityUseCase_Reposition_ClosedOpportunity(t *testing.T) {
+	// Arrange
+	oppRepo, pipelineRepo, dealRepo, eventPublisher, customerService, userService, productService, cacheService, searchService, idGenerator := setupOpportunityTestDependencies()
+
+	uc := NewOpportunityUseCase(oppRepo, pipelineRepo, dealRepo, eventPublisher, customerService, userService, productService, cacheService, searchService, idGenerator, nil, nil, nil, nil, nil)
+
+	tenantID := uuid.New()
+	pipeline := createOpportunityTestPipeline(tenantID)
+	pipelineRepo.pipelines[pipeline.ID] = pipeline
+
+	opp := createTestOpportunityWithPipeline(tenantID, pipeline)
+	opp.Status = domain.OpportunityStatusLost
+	oppRepo.opportunities[opp.ID] = opp
+
+	req := &dto.RepositionOpportunityRequest{
+		StageID: pipeline.Stages[0].ID.String(),
+	}
+
+	// Act
+	_, err := uc.Reposition(context.Background(), tenantID, opp.ID, uuid.New(), req)
+
+	// Assert
+	if err == nil {
+		t.Fatal("Expected error for repositioning closed opportunity, got nil")
+	}
+}
+
+// ============================================================================
+// OpportunityUseCase Tests - Products
+// ============================================================================
+
+func TestOpportunityUseCase_ReplaceProducts(t *testing.T) {
+	// Arrange
+	oppRepo, pipelineRepo, dealRepo, eventPublisher, customerService, userService, productService, cacheService, searchService, idGenerator := setupOpportunityTestDependencies()
+	pricingRepo := NewMockPricingSettingsRepository()
+
+	uc := NewOpportunityUseCase(oppRepo, pipelineRepo, dealRepo, eventPublisher, customerService, userService, productService, cacheService, searchService, idGenerator, nil, nil, nil, nil, NewPricingUseCase(pricingRepo))
+
+	tenantID := uuid.New()
+	pipeline := createOpportunityTestPipeline(tenantID)
+	pipelineRepo.pipelines[pipeline.ID] = pipeline
+
+	opp := createTestOpportunityWithPipeline(tenantID, pipeline)
+	oppRepo.opportunities[opp.ID] = opp
+	customerService.customers[opp.CustomerID] = &ports.CustomerInfo{ID: opp.CustomerID, Name: "Test Customer", Tier: "gold"}
+
+	listed, catalogued := uuid.New(), uuid.New()
+	productService.products[listed] = &ports.ProductInfo{ID: listed, Name: "Batik Sarong", UnitPrice: 20000, Currency: "USD"}
+	productService.products[catalogued] = &ports.ProductInfo{ID: catalogued, SKU: "SCF-01", Name: "Silk Scarf", UnitPrice: 5000, Currency: "USD"}
+	pricingRepo.settings[tenantID] = &domain.PricingSettings{TenantID: tenantID, PriceLists: []domain.PriceList{{
+		Name:         "Gold",
+		CustomerTier: "gold",
+		Currency:     "USD",
+		Prices:       []domain.ProductPrice{{ProductID: listed, UnitPrice: 15000}},
+	}}}
+
+	explicitPrice := int64(1000)
+	discount := 50
+	req := &dto.ReplaceProductsRequest{Products: []*dto.OpportunityProductRequestDTO{
+		{ProductID: listed.String(), ProductName: "Batik Sarong", Quantity: 2},
+		{ProductID: catalogued.String(), ProductName: "Silk Scarf", Quantity: 1, DiscountPercent: &discount},
+		{ProductID: catalogued.String(), ProductName: "Silk Scarf (sample)", Quantity: 1, UnitPrice: &explicitPrice, Currency: "usd"},
+	}}
+
+	// Act
+	result, err := uc.ReplaceProducts(context.Background(), tenantID, opp.ID, uuid.New(), req)
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if len(result.Products) != 3 {
+		t.Fatalf("Expected 3 products, got %d", len(result.Products))
+	}
+	if result.Products[0].UnitPrice.Amount != 15000 {
+		t.Errorf("Expected the gold price list price 15000, got %d", result.Products[0].UnitPrice.Amount)
+	}
+	if result.Products[1].UnitPrice.Amount != 5000 {
+		t.Errorf("Expected the catalogue price 5000, got %d", result.Products[1].UnitPrice.Amount)
+	}
+	// 2 x 150.00, 50.00 less 50%, and 10.00
+	if result.Amount.Amount != 33500 {
+		t.Errorf("Expected amount 33500, got %d", result.Amount.Amount)
+	}
+	if saved := oppRepo.opportunities[opp.ID]; saved.WeightedAmount.Amount != 6700 {
+		t.Errorf("Expected weighted amount 6700 at 20%%, got %d", saved.WeightedAmount.Amount)
+	}
+}
+
+func TestOpportunityUseCase_ReplaceProducts_CurrencyMismatch(t *testing.T) {
+	// Arrange
+	oppRepo, pipelineRepo, dealRepo, eventPublisher, customerService, userService, productService, cacheService, searchService, idGenerator := setupOpportunityTestDependencies()
+
+	uc := NewOpportunityUseCase(oppRepo, pipelineRepo, dealRepo, eventPublisher, customerService, userService, productService, cacheService, searchService, idGenerator, nil, nil, nil, nil, nil)
+
+	tenantID := uuid.New()
+	pipeline := createOpportunityTestPipeline(tenantID)
+	pipelineRepo.pipelines[pipeline.ID] = pipeline
+
+	opp := createTestOpportunityWithPipeline(tenantID, pipeline)
+	unitPrice, _ := domain.NewMoney(1000, "USD")
+	opp.AddProduct(domain.OpportunityProduct{ProductID: uuid.New(), ProductName: "Existing", Quantity: 1, UnitPrice: unitPrice})
+	oppRepo.opportunities[opp.ID] = opp
+
+	productID := uuid.New()
+	productService.products[productID] = &ports.ProductInfo{ID: productID, UnitPrice: 5000, Currency: "MYR"}
+	price := int64(5000)
+	req := &dto.ReplaceProductsRequest{Products: []*dto.OpportunityProductRequestDTO{
+		{ProductID: productID.String(), ProductName: "Batik Sarong", Quantity: 1, UnitPrice: &price, Currency: "MYR"},
+	}}
+
+	// Act
+	_, err := uc.ReplaceProducts(context.Background(), tenantID, opp.ID, uuid.New(), req)
+
+	// Assert
+	if err == nil {
+		t.Fatal("Expected error for a product in another currency, got nil")
+	}
+	if len(opp.Products) != 1 || opp.Products[0].ProductName != "Existing" {
+		t.Errorf("Expected the products to be unchanged, got %+v", opp.Products)
+	}
+
+	// Without a price, the MYR catalogue price cannot be used either
+	req.Products[0].UnitPrice, req.Products[0].Currency = nil, ""
+	if _, err := uc.ReplaceProducts(context.Background(), tenantID, opp.ID, uuid.New(), req); err == nil {
+		t.Error("Expected error for an unpriced product, got nil")
+	}
+}
+
+func TestOpportunityUseCase_UpdateAndRemoveProduct(t *testing.T) {
+	// Arrange
+	oppRepo, pipelineRepo, dealRepo, eventPublisher, customerService, userService, productService, cacheService, searchService, idGenerator := setupOpportunityTestDependencies()
+
+	uc := NewOpportunityUseCase(oppRepo, pipelineRepo, dealRepo, eventPublisher, customerService, userService, productService, cacheService, searchService, idGenerator, nil, nil, nil, nil, nil)
+
+	tenantID := uuid.New()
+	pipeline := createOpportunityTestPipeline(tenantID)
+	pipelineRepo.pipelines[pipeline.ID] = pipeline
+
+	opp := createTestOpportunityWithPipeline(tenantID, pipeline)
+	unitPrice, _ := domain.NewMoney(1000, "USD")
+	productID := uuid.New()
+	opp.AddProduct(domain.OpportunityProduct{ProductID: productID, ProductName: "Batik Sarong", Quantity: 1, UnitPrice: unitPrice})
+	oppRepo.opportunities[opp.ID] = opp
+	lineID := opp.Products[0].ID
+
+	// Act: lines are addressed by line ID or product ID
+	quantity := 3
+	result, err := uc.UpdateProduct(context.Background(), tenantID, opp.ID, lineID, uuid.New(), &dto.UpdateProductRequest{Quantity: &quantity})
+	if err != nil {
+		t.Fatalf("UpdateProduct() error = %v", err)
+	}
+	if result.Amount.Amount != 3000 {
+		t.Errorf("Expected amount 3000 after update, got %d", result.Amount.Amount)
+	}
+
+	result, err = uc.RemoveProduct(context.Background(), tenantID, opp.ID, productID, uuid.New())
+	if err != nil {
+		t.Fatalf("RemoveProduct() error = %v", err)
+	}
+	if len(result.Products) != 0 || result.Amount.Amount != 0 {
+		t.Errorf("Expected no products and no amount, got %d products and %d", len(result.Products), result.Amount.Amount)
+	}
+
+	if _, err := uc.RemoveProduct(context.Background(), tenantID, opp.ID, productID, uuid.New()); err == nil {
+		t.Error("Expected error removing a product that is not on the opportunity")
+	}
+}
+
+func TestOpportunityUseCase_Update_ForecastOverrides(t *testing.T) {
+	// Arrange
+	oppRepo, pipelineRepo, dealRepo, eventPublisher, customerService, userService, productService, cacheService, searchService, idGenerator := setupOpportunityTestDependencies()
+
+	uc := NewOpportunityUseCase(oppRepo, pipelineRepo, dealRepo, eventPublisher, customerService, userService, productService, cacheService, searchService, idGenerator, nil, nil, nil, nil, nil)
+
+	tenantID := uuid.New()
+	pipeline := createOpportunityTestPipeline(tenantID)
+	pipelineRepo.pipelines[pipeline.ID] = pipeline
+
+	opp := createTestOpportunityWithPipeline(tenantID, pipeline)
+	oppRepo.opportunities[opp.ID] = opp
+
+	probability := 90
+	category := "commit"
+
+	// Act
+	result, err := uc.Update(context.Background(), tenantID, opp.ID, uuid.New(), &dto.UpdateOpportunityRequest{
+		Probability:      &probability,
+		ForecastCategory: &category,
+		Version:          1,
+	})
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if result.Probability != 90 || result.ProbabilityOverride == nil || *result.ProbabilityOverride != 90 {
+		t.Errorf("Expected probability override 90, got %d (%v)", result.Probability, result.ProbabilityOverride)
+	}
+	if result.WeightedAmount.Amount != 9000 || result.ForecastCategory != "commit" {
+		t.Errorf("Expected weighted 9000 in commit, got %d in %s", result.WeightedAmount.Amount, result.ForecastCategory)
+	}
+	var audited bool
+	for _, event := range eventPublisher.events {
+		if event.Type == "opportunity.forecast_changed" && event.Payload["previous"] != nil {
+			audited = true
+		}
+	}
+	if !audited {
+		t.Error("Expected an opportunity.forecast_changed event with the previous values")
+	}
+
+	// Clearing the override restores the stage probability
+	result, err = uc.Update(context.Background(), tenantID, opp.ID, uuid.New(), &dto.UpdateOpportunityRequest{
+		ClearProbabilityOverride: true,
+		Version:                  opp.Version,
+	})
+	if err != nil {
+		t.Fatalf("Expected no error clearing the override, got: %v", err)
+	}
+	if result.ProbabilityOverride != nil || result.Probability != pipeline.Stages[0].Probability {
+		t.Errorf("Expected stage probability %d, got %d", pipeline.Stages[0].Probability, result.Probability)
+	}
+
+	if _, err := uc.Update(context.Background(), tenantID, opp.ID, uuid.New(), &dto.UpdateOpportunityRequest{
+		Probability:              &probability,
+		ClearProbabilityOverride: true,
+		Version:                  opp.Version,
+	}); err == nil {
+		t.Error("Expected error setting and clearing the override together")
+	}
+}
@@ -0,0 +1,487 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/kilang-desa-murni/crm/internal/sales/application"
+	"github.com/kilang-desa-murni/crm/internal/sales/application/dto"
+	"github.com/kilang-desa-murni/crm/internal/sales/application/ports"
+	"github.com/kilang-desa-murni/crm/internal/sales/domain"
+)
+
+// orderWebhookRetries is how many times a failed order webhook delivery is retried.
+const orderWebhookRetries = 3
+
+// ============================================================================
+// Order Use Case Interface
+// ============================================================================
+
+// OrderUseCase defines the interface for production orders made from won
+// deals and the webhook that keeps factory systems in sync with them.
+type OrderUseCase interface {
+	CreateFromDeal(ctx context.Context, tenantID, dealID, userID uuid.UUID, req *dto.CreateOrderRequest) (*dto.OrderResponse, error)
+	GetByID(ctx context.Context, tenantID, orderID uuid.UUID) (*dto.OrderResponse, error)
+	List(ctx context.Context, tenantID uuid.UUID, req *dto.ListOrdersRequest) (*dto.OrderListResponse, error)
+	Update(ctx context.Context, tenantID, orderID uuid.UUID, req *dto.UpdateOrderRequest) (*dto.OrderResponse, error)
+	UpdateStatus(ctx context.Context, tenantID, orderID, userID uuid.UUID, req *dto.UpdateOrderStatusRequest) (*dto.OrderResponse, error)
+
+	// Webhook
+	GetWebhook(ctx context.Context, tenantID uuid.UUID) (*dto.OrderWebhookResponse, error)
+	UpdateWebhook(ctx context.Context, tenantID uuid.UUID, req *dto.UpdateOrderWebhookRequest) (*dto.OrderWebhookResponse, error)
+	DeleteWebhook(ctx context.Context, tenantID uuid.UUID) error
+}
+
+// ============================================================================
+// Order Use Case Implementation
+// ============================================================================
+
+// orderUseCase implements OrderUseCase.
+type orderUseCase struct {
+	orderRepo      domain.OrderRepository
+	dealRepo       domain.DealRepository
+	webhookRepo    domain.OrderWebhookRepository
+	eventPublisher ports.EventPublisher
+	webhookService ports.WebhookService
+}
+
+// NewOrderUseCase creates a new order use case. Order events are delivered to
+// the tenant's webhook when webhookService is set.
+func NewOrderUseCase(
+	orderRepo domain.OrderRepository,
+	dealRepo domain.DealRepository,
+	webhookRepo domain.OrderWebhookRepository,
+	eventPublisher ports.EventPublisher,
+	webhookService ports.WebhookService,
+) OrderUseCase {
+	return &orderUseCase{
+		orderRepo:      orderRepo,
+		dealRepo:       dealRepo,
+		webhookRepo:    webhookRepo,
+		eventPublisher: eventPublisher,
+		webhookService: webhookService,
+	}
+}
+
+// CreateFromDeal creates a pending production order for the unfulfilled
+// products of a deal. A deal has at most one order that is not cancelled.
+func (uc *orderUseCase) CreateFromDeal(ctx context.Context, tenantID, dealID, userID uuid.UUID, req *dto.CreateOrderRequest) (*dto.OrderResponse, error) {
+	deal, err := uc.dealRepo.GetByID(ctx, tenantID, dealID)
+	if err != nil {
+		return nil, application.ErrDealNotFound(dealID)
+	}
+
+	existing, err := uc.orderRepo.GetOpenByDeal(ctx, tenantID, dealID)
+	if err != nil {
+		return nil, application.WrapError(application.ErrCodeInternal, "failed to get deal order", err)
+	}
+	if existing != nil {
+		return nil, application.ErrOrderAlreadyExists(dealID)
+	}
+
+	deliveryDate, err := parseOrderDate(req.DeliveryDate)
+	if err != nil {
+		return nil, err
+	}
+	items := make([]domain.OrderItemInput, len(req.Items))
+	for i, item := range req.Items {
+		lineItemID, err := uuid.Parse(item.DealLineItemID)
+		if err != nil {
+			return nil, application.ErrValidation("invalid deal_line_item_id")
+		}
+		itemDate, err := parseOrderDate(item.DeliveryDate)
+		if err != nil {
+			return nil, err
+		}
+		items[i] = domain.OrderItemInput{DealLineItemID: lineItemID, DeliveryDate: itemDate, Notes: item.Notes}
+	}
+
+	order, err := domain.NewOrderFromDeal(deal, deliveryDate, req.ProductionNotes, items, userID)
+	if err != nil {
+		if errors.Is(err, domain.ErrOrderDealCancelled) {
+			return nil, application.ErrDealCancelled(dealID)
+		}
+		return nil, application.ErrValidation(err.Error())
+	}
+
+	if err := uc.orderRepo.Create(ctx, order); err != nil {
+		if errors.Is(err, domain.ErrOrderAlreadyExists) {
+			return nil, application.ErrOrderAlreadyExists(dealID)
+		}
+		return nil, application.WrapError(application.ErrCodeInternal, "failed to create order", err)
+	}
+
+	uc.publishEvents(ctx, order)
+
+	return mapOrderToResponse(order), nil
+}
+
+// GetByID retrieves an order.
+func (uc *orderUseCase) GetByID(ctx context.Context, tenantID, orderID uuid.UUID) (*dto.OrderResponse, error) {
+	order, err := uc.getOrder(ctx, tenantID, orderID)
+	if err != nil {
+		return nil, err
+	}
+	return mapOrderToResponse(order), nil
+}
+
+// List lists orders, newest first.
+func (uc *orderUseCase) List(ctx context.Context, tenantID uuid.UUID, req *dto.ListOrdersRequest) (*dto.OrderListResponse, error) {
+	page, pageSize := req.Page, req.PageSize
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 {
+		pageSize = 20
+	}
+
+	filter := domain.OrderFilter{}
+	for _, s := range req.Statuses {
+		status := domain.OrderStatus(s)
+		if !status.IsValid() {
+			return nil, application.ErrValidation("invalid order status: " + s)
+		}
+		filter.Statuses = append(filter.Statuses, status)
+	}
+	if req.DealID != nil {
+		dealID, err := uuid.Parse(*req.DealID)
+		if err != nil {
+			return nil, application.ErrValidation("invalid deal_id")
+		}
+		filter.DealID = &dealID
+	}
+	if req.CustomerID != nil {
+		customerID, err := uuid.Parse(*req.CustomerID)
+		if err != nil {
+			return nil, application.ErrValidation("invalid customer_id")
+		}
+		filter.CustomerID = &customerID
+	}
+
+	orders, total, err := uc.orderRepo.List(ctx, tenantID, filter, domain.ListOptions{Page: page, PageSize: pageSize})
+	if err != nil {
+		return nil, application.WrapError(application.ErrCodeInternal, "failed to list orders", err)
+	}
+
+	resp := &dto.OrderListResponse{
+		Orders:     make([]*dto.OrderResponse, len(orders)),
+		Pagination: dto.NewPaginationResponse(page, pageSize, total),
+	}
+	for i, order := range orders {
+		resp.Orders[i] = mapOrderToResponse(order)
+	}
+	return resp, nil
+}
+
+// Update updates an order's delivery date, production notes and reference in
+// the factory's system.
+func (uc *orderUseCase) Update(ctx context.Context, tenantID, orderID uuid.UUID, req *dto.UpdateOrderRequest) (*dto.OrderResponse, error) {
+	order, err := uc.getOrder(ctx, tenantID, orderID)
+	if err != nil {
+		return nil, err
+	}
+
+	deliveryDate := order.DeliveryDate
+	if req.DeliveryDate != nil {
+		if deliveryDate, err = parseOrderDate(*req.DeliveryDate); err != nil {
+			return nil, err
+		}
+	}
+	productionNotes := order.ProductionNotes
+	if req.ProductionNotes != nil {
+		productionNotes = *req.ProductionNotes
+	}
+	externalReference := order.ExternalReference
+	if req.ExternalReference != nil {
+		externalReference = *req.ExternalReference
+	}
+
+	if err := order.Update(deliveryDate, productionNotes, externalReference); err != nil {
+		return nil, application.ErrOrderClosed(orderID, string(order.Status))
+	}
+
+	if err := uc.saveOrder(ctx, order); err != nil {
+		return nil, err
+	}
+	return mapOrderToResponse(order), nil
+}
+
+// UpdateStatus moves an order through production. Delivered quantities are
+// recorded as fulfilled on the deal's line items.
+func (uc *orderUseCase) UpdateStatus(ctx context.Context, tenantID, orderID, userID uuid.UUID, req *dto.UpdateOrderStatusRequest) (*dto.OrderResponse, error) {
+	order, err := uc.getOrder(ctx, tenantID, orderID)
+	if err != nil {
+		return nil, err
+	}
+
+	status := domain.OrderStatus(req.Status)
+	if err := order.ChangeStatus(status, userID, req.Note, req.TrackingNumber); err != nil {
+		if errors.Is(err, domain.ErrInvalidOrderStatusTransition) {
+			return nil, application.ErrOrderInvalidStatusTransition(string(order.Status), req.Status)
+		}
+		return nil, application.ErrValidation(err.Error())
+	}
+
+	if err := uc.saveOrder(ctx, order); err != nil {
+		return nil, err
+	}
+
+	if status == domain.OrderStatusDelivered {
+		uc.fulfillDeal(ctx, order)
+	}
+
+	return mapOrderToResponse(order), nil
+}
+
+func (uc *orderUseCase) getOrder(ctx context.Context, tenantID, orderID uuid.UUID) (*domain.Order, error) {
+	order, err := uc.orderRepo.GetByID(ctx, tenantID, orderID)
+	if err != nil {
+		if errors.Is(err, domain.ErrOrderNotFound) {
+			return nil, application.ErrOrderNotFound(orderID)
+		}
+		return nil, application.WrapError(application.ErrCodeInternal, "failed to get order", err)
+	}
+	return order, nil
+}
+
+func (uc *orderUseCase) saveOrder(ctx context.Context, order *domain.Order) error {
+	order.Version++
+	if err := uc.orderRepo.Update(ctx, order); err != nil {
+		if errors.Is(err, domain.ErrOrderNotFound) {
+			return application.ErrConcurrentModification("order", order.ID)
+		}
+		return application.WrapError(application.ErrCodeInternal, "failed to update order", err)
+	}
+
+	uc.publishEvents(ctx, order)
+	return nil
+}
+
+// fulfillDeal records a delivered order's quantities on its deal. The order
+// is already delivered, so a deal changed since the order was made is only
+// updated for the line items it still has.
+func (uc *orderUseCase) fulfillDeal(ctx context.Context, order *domain.Order) {
+	deal, err := uc.dealRepo.GetByID(ctx, order.TenantID, order.DealID)
+	if err != nil {
+		return
+	}
+
+	for _, item := range order.Items {
+		for _, lineItem := range deal.LineItems {
+			if lineItem.ID == item.DealLineItemID {
+				quantity := item.Quantity
+				if remaining := lineItem.RemainingQuantity(); quantity > remaining {
+					quantity = remaining
+				}
+				if quantity > 0 {
+					_ = deal.FulfillLineItem(lineItem.ID, quantity)
+				}
+			}
+		}
+	}
+
+	deal.Version++
+	_ = uc.dealRepo.Update(ctx, deal)
+}
+
+// ============================================================================
+// Webhook
+// ============================================================================
+
+// GetWebhook retrieves the tenant's order webhook.
+func (uc *orderUseCase) GetWebhook(ctx context.Context, tenantID uuid.UUID) (*dto.OrderWebhookResponse, error) {
+	webhook, err := uc.webhookRepo.Get(ctx, tenantID)
+	if err != nil {
+		return nil, application.WrapError(application.ErrCodeInternal, "failed to get order webhook", err)
+	}
+	if webhook == nil {
+		return nil, application.ErrOrderWebhookNotFound()
+	}
+	return mapOrderWebhookToResponse(webhook), nil
+}
+
+// UpdateWebhook sets the tenant's order webhook. A new webhook gets a new
+// signing secret; an existing one keeps its secret unless it is rotated.
+func (uc *orderUseCase) UpdateWebhook(ctx context.Context, tenantID uuid.UUID, req *dto.UpdateOrderWebhookRequest) (*dto.OrderWebhookResponse, error) {
+	webhook, err := uc.webhookRepo.Get(ctx, tenantID)
+	if err != nil {
+		return nil, application.WrapError(application.ErrCodeInternal, "failed to get order webhook", err)
+	}
+
+	if webhook == nil {
+		if webhook, err = domain.NewOrderWebhook(tenantID, req.URL); err != nil {
+			if errors.Is(err, domain.ErrInvalidOrderWebhookURL) {
+				return nil, application.ErrValidation(err.Error())
+			}
+			return nil, application.WrapError(application.ErrCodeInternal, "failed to create order webhook", err)
+		}
+	} else {
+		if err := webhook.SetURL(req.URL); err != nil {
+			return nil, application.ErrValidation(err.Error())
+		}
+		if req.RotateSecret {
+			if err := webhook.RotateSecret(); err != nil {
+				return nil, application.WrapError(application.ErrCodeInternal, "failed to rotate order webhook secret", err)
+			}
+		}
+	}
+	if req.Enabled != nil {
+		webhook.Enabled = *req.Enabled
+	}
+
+	if err := uc.webhookRepo.Upsert(ctx, webhook); err != nil {
+		return nil, application.WrapError(application.ErrCodeInternal, "failed to save order webhook", err)
+	}
+	return mapOrderWebhookToResponse(webhook), nil
+}
+
+// DeleteWebhook removes the tenant's order webhook.
+func (uc *orderUseCase) DeleteWebhook(ctx context.Context, tenantID uuid.UUID) error {
+	if err := uc.webhookRepo.Delete(ctx, tenantID); err != nil {
+		return application.WrapError(application.ErrCodeInternal, "failed to delete order webhook", err)
+	}
+	return nil
+}
+
+// ============================================================================
+// Events and Webhooks
+// ============================================================================
+
+// publishEvents publishes the order's events and delivers them to the
+// tenant's webhook with the order as it is now.
+func (uc *orderUseCase) publishEvents(ctx context.Context, order *domain.Order) {
+	events := order.GetEvents()
+	order.ClearEvents()
+
+	if uc.eventPublisher != nil {
+		for _, event := range events {
+			var payload map[string]interface{}
+			if data, err := json.Marshal(event); err == nil {
+				json.Unmarshal(data, &payload)
+			}
+
+			uc.eventPublisher.Publish(ctx, ports.Event{
+				ID:            event.EventID().String(),
+				Type:          event.EventType(),
+				AggregateID:   event.AggregateID().String(),
+				AggregateType: event.AggregateType(),
+				TenantID:      event.TenantID().String(),
+				Payload:       payload,
+				OccurredAt:    event.OccurredAt(),
+				Version:       event.Version(),
+			})
+		}
+	}
+
+	if uc.webhookService == nil || uc.webhookRepo == nil || len(events) == 0 {
+		return
+	}
+	webhook, err := uc.webhookRepo.Get(ctx, order.TenantID)
+	if err != nil || webhook == nil || !webhook.Enabled {
+		return
+	}
+
+	var orderPayload map[string]interface{}
+	if data, err := json.Marshal(mapOrderToResponse(order)); err == nil {
+		json.Unmarshal(data, &orderPayload)
+	}
+	for _, event := range events {
+		uc.webhookService.SendAsync(ctx, ports.WebhookRequest{
+			TenantID: order.TenantID,
+			URL:      webhook.URL,
+			Method:   "POST",
+			Headers: map[string]string{
+				"X-Webhook-Event": event.EventType(),
+				"X-Webhook-ID":    event.EventID().String(),
+			},
+			Payload: map[string]interface{}{
+				"id":          event.EventID().String(),
+				"event":       event.EventType(),
+				"occurred_at": event.OccurredAt(),
+				"order":       orderPayload,
+			},
+			RetryCount: orderWebhookRetries,
+			Secret:     webhook.Secret,
+		})
+	}
+}
+
+// ============================================================================
+// Helpers
+// ============================================================================
+
+// parseOrderDate parses a YYYY-MM-DD date; an empty date is nil.
+func parseOrderDate(value string) (*time.Time, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return nil, nil
+	}
+	date, err := time.Parse("2006-01-02", value)
+	if err != nil {
+		return nil, application.ErrValidation("invalid date, expected YYYY-MM-DD: " + value)
+	}
+	return &date, nil
+}
+
+func mapOrderToResponse(order *domain.Order) *dto.OrderResponse {
+	resp := &dto.OrderResponse{
+		ID:                order.ID.String(),
+		OrderNumber:       order.OrderNumber,
+		DealID:            order.DealID.String(),
+		DealCode:          order.DealCode,
+		DealName:          order.DealName,
+		CustomerID:        order.CustomerID.String(),
+		CustomerName:      order.CustomerName,
+		Status:            string(order.Status),
+		Items:             make([]*dto.OrderItemResponse, len(order.Items)),
+		DeliveryDate:      order.DeliveryDate,
+		ProductionNotes:   order.ProductionNotes,
+		ExternalReference: order.ExternalReference,
+		TrackingNumber:    order.TrackingNumber,
+		StatusHistory:     make([]*dto.OrderStatusChangeResponse, len(order.StatusHistory)),
+		CreatedBy:         order.CreatedBy.String(),
+		CreatedAt:         order.CreatedAt,
+		UpdatedAt:         order.UpdatedAt,
+		ShippedAt:         order.ShippedAt,
+		DeliveredAt:       order.DeliveredAt,
+		CancelledAt:       order.CancelledAt,
+		Version:           order.Version,
+	}
+	for i, item := range order.Items {
+		resp.Items[i] = &dto.OrderItemResponse{
+			ID:             item.ID.String(),
+			DealLineItemID: item.DealLineItemID.String(),
+			ProductID:      item.ProductID.String(),
+			ProductName:    item.ProductName,
+			ProductSKU:     item.ProductSKU,
+			Description:    item.Description,
+			Quantity:       item.Quantity,
+			DeliveryDate:   item.DeliveryDate,
+			Notes:          item.Notes,
+		}
+	}
+	for i, change := range order.StatusHistory {
+		resp.StatusHistory[i] = &dto.OrderStatusChangeResponse{
+			From:      string(change.From),
+			To:        string(change.To),
+			ChangedBy: change.ChangedBy.String(),
+			Note:      change.Note,
+			ChangedAt: change.ChangedAt,
+		}
+	}
+	return resp
+}
+
+func mapOrderWebhookToResponse(webhook *domain.OrderWebhook) *dto.OrderWebhookResponse {
+	return &dto.OrderWebhookResponse{
+		URL:       webhook.URL,
+		Secret:    webhook.Secret,
+		Enabled:   webhook.Enabled,
+		UpdatedAt: webhook.UpdatedAt,
+	}
+}
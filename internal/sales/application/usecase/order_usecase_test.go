@@ -0,0 +1,287 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"github.com/kilang-desa-murni/crm/internal/sales/application"
+	"github.com/kilang-desa-murni/crm/internal/sales/application/dto"
+	"github.com/kilang-desa-murni/crm/internal/sales/application/ports"
+	"github.com/kilang-desa-murni/crm/internal/sales/domain"
+)
+
+// ============================================================================
+// Mock Implementations for Order Tests
+// ============================================================================
+
+// MockOrderRepository is a mock implementation of domain.OrderRepository.
+type MockOrderRepository struct {
+	orders map[uuid.UUID]*domain.Order
+}
+
+func NewMockOrderRepository() *MockOrderRepository {
+	return &MockOrderRepository{orders: make(map[uuid.UUID]*domain.Order)}
+}
+
+func (m *MockOrderRepository) Create(ctx context.Context, order *domain.Order) error {
+	m.orders[order.ID] = order
+	return nil
+}
+
+func (m *MockOrderRepository) GetByID(ctx context.Context, tenantID, orderID uuid.UUID) (*domain.Order, error) {
+	order, ok := m.orders[orderID]
+	if !ok || order.TenantID != tenantID {
+		return nil, domain.ErrOrderNotFound
+	}
+	return order, nil
+}
+
+func (m *MockOrderRepository) Update(ctx context.Context, order *domain.Order) error {
+	if _, ok := m.orders[order.ID]; !ok {
+		return domain.ErrOrderNotFound
+	}
+	m.orders[order.ID] = order
+	return nil
+}
+
+func (m *MockOrderRepository) GetOpenByDeal(ctx context.Context, tenantID, dealID uuid.UUID) (*domain.Order, error) {
+	for _, order := range m.orders {
+		if order.TenantID == tenantID && order.DealID == dealID && order.Status != domain.OrderStatusCancelled {
+			return order, nil
+		}
+	}
+	return nil, nil
+}
+
+func (m *MockOrderRepository) List(ctx context.Context, tenantID uuid.UUID, filter domain.OrderFilter, opts domain.ListOptions) ([]*domain.Order, int64, error) {
+	orders := make([]*domain.Order, 0)
+	for _, order := range m.orders {
+		if order.TenantID == tenantID {
+			orders = append(orders, order)
+		}
+	}
+	return orders, int64(len(orders)), nil
+}
+
+// MockOrderWebhookRepository is a mock implementation of domain.OrderWebhookRepository.
+type MockOrderWebhookRepository struct {
+	webhooks map[uuid.UUID]*domain.OrderWebhook
+}
+
+func NewMockOrderWebhookRepository() *MockOrderWebhookRepository {
+	return &MockOrderWebhookRepository{webhooks: make(map[uuid.UUID]*domain.OrderWebhook)}
+}
+
+func (m *MockOrderWebhookRepository) Get(ctx context.Context, tenantID uuid.UUID) (*domain.OrderWebhook, error) {
+	return m.webhooks[tenantID], nil
+}
+
+func (m *MockOrderWebhookRepository) Upsert(ctx context.Context, webhook *domain.OrderWebhook) error {
+	m.webhooks[webhook.TenantID] = webhook
+	return nil
+}
+
+func (m *MockOrderWebhookRepository) Delete(ctx context.Context, tenantID uuid.UUID) error {
+	delete(m.webhooks, tenantID)
+	return nil
+}
+
+// MockWebhookService is a mock implementation of ports.WebhookService.
+type MockWebhookService struct {
+	requests []ports.WebhookRequest
+}
+
+func NewMockWebhookService() *MockWebhookService {
+	return &MockWebhookService{}
+}
+
+func (m *MockWebhookService) Send(ctx context.Context, req ports.WebhookRequest) error {
+	m.requests = append(m.requests, req)
+	return nil
+}
+
+func (m *MockWebhookService) SendAsync(ctx context.Context, req ports.WebhookRequest) error {
+	m.requests = append(m.requests, req)
+	return nil
+}
+
+// ============================================================================
+// Helper Functions
+// ============================================================================
+
+func createOrderTestDeal(tenantID uuid.UUID) *domain.Deal {
+	deal := createDealTestDeal(tenantID)
+	deal.Status = domain.DealStatusActive
+	deal.LineItems = []domain.DealLineItem{
+		{ID: uuid.New(), ProductID: uuid.New(), ProductName: "Batik Sarong", Quantity: 10},
+		{ID: uuid.New(), ProductID: uuid.New(), ProductName: "Batik Shirt", Quantity: 4, FulfilledQty: 1},
+	}
+	return deal
+}
+
+// ============================================================================
+// OrderUseCase Tests
+// ============================================================================
+
+func TestOrderUseCase_CreateFromDeal(t *testing.T) {
+	ctx := context.Background()
+	tenantID := uuid.New()
+	dealRepo := NewDealMockDealRepository()
+	orderRepo := NewMockOrderRepository()
+	eventPublisher := NewDealMockEventPublisher()
+	uc := NewOrderUseCase(orderRepo, dealRepo, NewMockOrderWebhookRepository(), eventPublisher, nil)
+
+	deal := createOrderTestDeal(tenantID)
+	dealRepo.deals[deal.ID] = deal
+
+	order, err := uc.CreateFromDeal(ctx, tenantID, deal.ID, uuid.New(), &dto.CreateOrderRequest{
+		DeliveryDate:    "2026-03-01",
+		ProductionNotes: "Hand-drawn motif",
+		Items: []dto.CreateOrderItemRequest{
+			{DealLineItemID: deal.LineItems[1].ID.String(), DeliveryDate: "2026-02-15", Notes: "Size L"},
+		},
+	})
+	if err != nil {
+		t.Fatalf("CreateFromDeal() error = %v", err)
+	}
+
+	if order.Status != string(domain.OrderStatusPending) || order.DealID != deal.ID.String() {
+		t.Errorf("order = %+v, want pending order for the deal", order)
+	}
+	if len(order.Items) != 2 || order.Items[1].Quantity != 3 || order.Items[1].Notes != "Size L" {
+		t.Errorf("Items = %+v, want remaining quantities with item notes", order.Items)
+	}
+	if order.DeliveryDate == nil || order.DeliveryDate.Format("2006-01-02") != "2026-03-01" {
+		t.Errorf("DeliveryDate = %v, want 2026-03-01", order.DeliveryDate)
+	}
+	if len(eventPublisher.events) != 1 || eventPublisher.events[0].Type != "order.created" {
+		t.Errorf("events = %+v, want order.created", eventPublisher.events)
+	}
+
+	_, err = uc.CreateFromDeal(ctx, tenantID, deal.ID, uuid.New(), &dto.CreateOrderRequest{})
+	if appErr := application.GetAppError(err); appErr == nil || appErr.Code != application.ErrCodeOrderAlreadyExists {
+		t.Errorf("second CreateFromDeal() error = %v, want %s", err, application.ErrCodeOrderAlreadyExists)
+	}
+}
+
+func TestOrderUseCase_CreateFromDeal_Invalid(t *testing.T) {
+	ctx := context.Background()
+	tenantID := uuid.New()
+	dealRepo := NewDealMockDealRepository()
+	uc := NewOrderUseCase(NewMockOrderRepository(), dealRepo, nil, nil, nil)
+
+	if _, err := uc.CreateFromDeal(ctx, tenantID, uuid.New(), uuid.New(), &dto.CreateOrderRequest{}); !application.IsNotFoundError(err) {
+		t.Errorf("CreateFromDeal() for missing deal error = %v, want not found", err)
+	}
+
+	deal := createOrderTestDeal(tenantID)
+	dealRepo.deals[deal.ID] = deal
+	if _, err := uc.CreateFromDeal(ctx, tenantID, deal.ID, uuid.New(), &dto.CreateOrderRequest{DeliveryDate: "01/03/2026"}); !application.IsValidationError(err) {
+		t.Errorf("CreateFromDeal() with invalid date error = %v, want validation error", err)
+	}
+
+	deal.Status = domain.DealStatusCancelled
+	if _, err := uc.CreateFromDeal(ctx, tenantID, deal.ID, uuid.New(), &dto.CreateOrderRequest{}); err == nil {
+		t.Error("CreateFromDeal() for cancelled deal should fail")
+	}
+}
+
+func TestOrderUseCase_UpdateStatus(t *testing.T) {
+	ctx := context.Background()
+	tenantID := uuid.New()
+	userID := uuid.New()
+	dealRepo := NewDealMockDealRepository()
+	webhookRepo := NewMockOrderWebhookRepository()
+	webhookService := NewMockWebhookService()
+	uc := NewOrderUseCase(NewMockOrderRepository(), dealRepo, webhookRepo, NewDealMockEventPublisher(), webhookService)
+
+	deal := createOrderTestDeal(tenantID)
+	dealRepo.deals[deal.ID] = deal
+
+	hook, err := uc.UpdateWebhook(ctx, tenantID, &dto.UpdateOrderWebhookRequest{URL: "https://factory.example.com/orders"})
+	if err != nil {
+		t.Fatalf("UpdateWebhook() error = %v", err)
+	}
+
+	order, err := uc.CreateFromDeal(ctx, tenantID, deal.ID, userID, &dto.CreateOrderRequest{})
+	if err != nil {
+		t.Fatalf("CreateFromDeal() error = %v", err)
+	}
+	orderID := uuid.MustParse(order.ID)
+
+	_, err = uc.UpdateStatus(ctx, tenantID, orderID, userID, &dto.UpdateOrderStatusRequest{Status: "delivered"})
+	if appErr := application.GetAppError(err); appErr == nil || appErr.Code != application.ErrCodeOrderInvalidTransition {
+		t.Errorf("UpdateStatus() pending -> delivered error = %v, want %s", err, application.ErrCodeOrderInvalidTransition)
+	}
+
+	for _, status := range []string{"in_production", "shipped", "delivered"} {
+		if order, err = uc.UpdateStatus(ctx, tenantID, orderID, userID, &dto.UpdateOrderStatusRequest{Status: status, TrackingNumber: "TRK-1"}); err != nil {
+			t.Fatalf("UpdateStatus(%s) error = %v", status, err)
+		}
+	}
+	if order.Status != "delivered" || order.TrackingNumber != "TRK-1" {
+		t.Errorf("order = %+v, want delivered with tracking number", order)
+	}
+
+	// Delivered quantities are fulfilled on the deal
+	if deal.LineItems[0].FulfilledQty != 10 || deal.LineItems[1].FulfilledQty != 4 {
+		t.Errorf("deal fulfilled quantities = %d, %d, want 10, 4", deal.LineItems[0].FulfilledQty, deal.LineItems[1].FulfilledQty)
+	}
+
+	// One created and three status changes are sent to the factory webhook
+	if len(webhookService.requests) != 4 {
+		t.Fatalf("len(webhook requests) = %d, want 4", len(webhookService.requests))
+	}
+	last := webhookService.requests[3]
+	if last.URL != hook.URL || last.Secret != hook.Secret || last.Headers["X-Webhook-Event"] != "order.status_changed" {
+		t.Errorf("webhook request = %+v, want signed order.status_changed delivery", last)
+	}
+
+	if _, err := uc.Update(ctx, tenantID, orderID, &dto.UpdateOrderRequest{}); err == nil {
+		t.Error("Update() on delivered order should fail")
+	}
+}
+
+func TestOrderUseCase_Webhook(t *testing.T) {
+	ctx := context.Background()
+	tenantID := uuid.New()
+	uc := NewOrderUseCase(NewMockOrderRepository(), NewDealMockDealRepository(), NewMockOrderWebhookRepository(), nil, nil)
+
+	if _, err := uc.GetWebhook(ctx, tenantID); !application.IsNotFoundError(err) {
+		t.Errorf("GetWebhook() error = %v, want not found", err)
+	}
+	if _, err := uc.UpdateWebhook(ctx, tenantID, &dto.UpdateOrderWebhookRequest{URL: "ftp://factory.example.com"}); !application.IsValidationError(err) {
+		t.Errorf("UpdateWebhook() with invalid URL error = %v, want validation error", err)
+	}
+
+	created, err := uc.UpdateWebhook(ctx, tenantID, &dto.UpdateOrderWebhookRequest{URL: "https://factory.example.com/a"})
+	if err != nil {
+		t.Fatalf("UpdateWebhook() error = %v", err)
+	}
+
+	disabled := false
+	updated, err := uc.UpdateWebhook(ctx, tenantID, &dto.UpdateOrderWebhookRequest{URL: "https://factory.example.com/b", Enabled: &disabled})
+	if err != nil {
+		t.Fatalf("UpdateWebhook() error = %v", err)
+	}
+	if updated.Secret != created.Secret || updated.Enabled {
+		t.Errorf("updated = %+v, want same secret and disabled", updated)
+	}
+
+	rotated, err := uc.UpdateWebhook(ctx, tenantID, &dto.UpdateOrderWebhookRequest{URL: updated.URL, RotateSecret: true})
+	if err != nil {
+		t.Fatalf("UpdateWebhook() error = %v", err)
+	}
+	if rotated.Secret == created.Secret {
+		t.Error("UpdateWebhook() with RotateSecret should replace the secret")
+	}
+
+	if err := uc.DeleteWebhook(ctx, tenantID); err != nil {
+		t.Fatalf("DeleteWebhook() error = %v", err)
+	}
+	if _, err := uc.GetWebhook(ctx, tenantID); !application.IsNotFoundError(err) {
+		t.Errorf("GetWebhook() after delete error = %v, want not found", err)
+	}
+}
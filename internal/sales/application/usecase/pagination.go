@@ -0,0 +1,95 @@
+package usecase
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/kilang-desa-murni/crm/internal/sales/application"
+	"github.com/kilang-desa-murni/crm/internal/sales/application/dto"
+	"github.com/kilang-desa-murni/crm/internal/sales/domain"
+	"github.com/kilang-desa-murni/crm/pkg/response"
+)
+
+// cursorSorts maps the sort fields a list can be paged on by cursor to the
+// value of that field on an item. Only fields whose columns are never NULL
+// are listed, since a NULL sort value has no keyset position.
+type cursorSorts[T any] map[string]func(T) string
+
+// after decodes a request cursor into the position the list continues after.
+// An empty cursor starts from the first page.
+func (s cursorSorts[T]) after(raw, sortBy, sortOrder string) (*domain.PageCursor, error) {
+	if raw == "" {
+		return nil, nil
+	}
+	if _, ok := s[sortBy]; !ok {
+		return nil, application.ErrValidation("cursor pagination is not supported when sorting by " + sortBy)
+	}
+
+	cursor, err := response.DecodeCursor(raw, sortBy, strings.ToLower(sortOrder))
+	if err != nil {
+		return nil, application.ErrValidation("invalid cursor")
+	}
+	id, err := uuid.Parse(cursor.ID)
+	if err != nil {
+		return nil, application.ErrValidation("invalid cursor")
+	}
+
+	return &domain.PageCursor{SortValue: cursor.Value, ID: id}, nil
+}
+
+// paginate sets the cursor of the next page on a list's pagination. A page
+// continued from a cursor has no meaningful page number, so whether more
+// items follow is judged by whether the page came back full.
+func (s cursorSorts[T]) paginate(p *dto.PaginationResponse, items []T, limit int, opts domain.ListOptions, id func(T) uuid.UUID) {
+	if opts.After != nil {
+		p.HasPrev = true
+		p.HasNext = len(items) == limit
+	}
+
+	value, ok := s[opts.SortBy]
+	if !ok || !p.HasNext || len(items) == 0 {
+		return
+	}
+	last := items[len(items)-1]
+	p.NextCursor = response.Cursor{
+		SortBy:    opts.SortBy,
+		SortOrder: strings.ToLower(opts.SortOrder),
+		Value:     value(last),
+		ID:        id(last).String(),
+	}.Encode()
+}
+
+// cursorTime formats a timestamp sort value without losing precision.
+func cursorTime(t time.Time) string {
+	return t.UTC().Format(time.RFC3339Nano)
+}
+
+var leadCursorSorts = cursorSorts[*domain.Lead]{
+	"created_at": func(l *domain.Lead) string { return cursorTime(l.CreatedAt) },
+	"updated_at": func(l *domain.Lead) string { return cursorTime(l.UpdatedAt) },
+	"score":      func(l *domain.Lead) string { return strconv.Itoa(l.Score.Score) },
+	"first_name": func(l *domain.Lead) string { return l.Contact.FirstName },
+	"last_name":  func(l *domain.Lead) string { return l.Contact.LastName },
+	"status":     func(l *domain.Lead) string { return string(l.Status) },
+	"source":     func(l *domain.Lead) string { return string(l.Source) },
+}
+
+var opportunityCursorSorts = cursorSorts[*domain.Opportunity]{
+	"created_at":  func(o *domain.Opportunity) string { return cursorTime(o.CreatedAt) },
+	"updated_at":  func(o *domain.Opportunity) string { return cursorTime(o.UpdatedAt) },
+	"amount":      func(o *domain.Opportunity) string { return strconv.FormatInt(o.Amount.Amount, 10) },
+	"probability": func(o *domain.Opportunity) string { return strconv.Itoa(o.Probability) },
+	"name":        func(o *domain.Opportunity) string { return o.Name },
+	"status":      func(o *domain.Opportunity) string { return string(o.Status) },
+}
+
+var dealCursorSorts = cursorSorts[*domain.Deal]{
+	"created_at":   func(d *domain.Deal) string { return cursorTime(d.CreatedAt) },
+	"updated_at":   func(d *domain.Deal) string { return cursorTime(d.UpdatedAt) },
+	"total_amount": func(d *domain.Deal) string { return strconv.FormatInt(d.TotalAmount.Amount, 10) },
+	"deal_number":  func(d *domain.Deal) string { return d.Code },
+	"name":         func(d *domain.Deal) string { return d.Name },
+}
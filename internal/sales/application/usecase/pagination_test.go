@@ -0,0 +1,102 @@
+package usecase
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/kilang-desa-murni/crm/internal/sales/application/dto"
+	"github.com/kilang-desa-murni/crm/internal/sales/domain"
+)
+
+func leadID(l *domain.Lead) uuid.UUID { return l.ID }
+
+func TestCursorSorts_RoundTrip(t *testing.T) {
+	tenantID := uuid.New()
+	first := createTestLead(tenantID)
+	last := createTestLead(tenantID)
+	last.CreatedAt = time.Date(2024, 5, 1, 8, 30, 0, 123456000, time.UTC)
+
+	opts := domain.ListOptions{Page: 1, PageSize: 2, SortBy: "created_at", SortOrder: "DESC"}
+	pagination := dto.NewPaginationResponse(1, 2, 5)
+	leadCursorSorts.paginate(&pagination, []*domain.Lead{first, last}, 2, opts, leadID)
+
+	if pagination.NextCursor == "" {
+		t.Fatal("expected a next cursor")
+	}
+
+	after, err := leadCursorSorts.after(pagination.NextCursor, "created_at", "desc")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if after.ID != last.ID {
+		t.Errorf("expected cursor ID %s, got %s", last.ID, after.ID)
+	}
+	if after.SortValue != "2024-05-01T08:30:00.123456Z" {
+		t.Errorf("unexpected sort value %q", after.SortValue)
+	}
+}
+
+func TestCursorSorts_Paginate(t *testing.T) {
+	tenantID := uuid.New()
+	leads := []*domain.Lead{createTestLead(tenantID), createTestLead(tenantID)}
+	cursor := &domain.PageCursor{SortValue: "50", ID: uuid.New()}
+
+	tests := []struct {
+		name       string
+		opts       domain.ListOptions
+		total      int64
+		items      []*domain.Lead
+		wantCursor bool
+		wantNext   bool
+	}{
+		{"offset page with more", domain.ListOptions{Page: 1, SortBy: "score"}, 5, leads, true, true},
+		{"offset last page", domain.ListOptions{Page: 3, SortBy: "score"}, 5, leads[:1], false, false},
+		{"cursor page full", domain.ListOptions{Page: 1, SortBy: "score", After: cursor}, 5, leads, true, true},
+		{"cursor page short", domain.ListOptions{Page: 1, SortBy: "score", After: cursor}, 5, leads[:1], false, false},
+		{"unsupported sort", domain.ListOptions{Page: 1, SortBy: "company"}, 5, leads, false, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pagination := dto.NewPaginationResponse(tt.opts.Page, 2, tt.total)
+			leadCursorSorts.paginate(&pagination, tt.items, 2, tt.opts, leadID)
+
+			if (pagination.NextCursor != "") != tt.wantCursor {
+				t.Errorf("expected cursor %v, got %q", tt.wantCursor, pagination.NextCursor)
+			}
+			if pagination.HasNext != tt.wantNext {
+				t.Errorf("expected has_next %v, got %v", tt.wantNext, pagination.HasNext)
+			}
+		})
+	}
+}
+
+func TestCursorSorts_After_Errors(t *testing.T) {
+	pagination := dto.NewPaginationResponse(1, 1, 2)
+	leadCursorSorts.paginate(&pagination, []*domain.Lead{createTestLead(uuid.New())}, 1, domain.ListOptions{Page: 1, SortBy: "score", SortOrder: "desc"}, leadID)
+
+	tests := []struct {
+		name      string
+		raw       string
+		sortBy    string
+		sortOrder string
+	}{
+		{"unsupported sort", pagination.NextCursor, "company", "desc"},
+		{"garbage", "not-a-cursor", "score", "desc"},
+		{"issued for another sort", pagination.NextCursor, "created_at", "desc"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := leadCursorSorts.after(tt.raw, tt.sortBy, tt.sortOrder); err == nil {
+				t.Error("expected an error")
+			}
+		})
+	}
+
+	if after, err := leadCursorSorts.after("", "score", "desc"); err != nil || after != nil {
+		t.Errorf("expected an empty cursor to start from the first page, got %v, %v", after, err)
+	}
+}
@@ -2,6 +2,8 @@ package usecase
 
 import (
 	"context"
+	"errors"
+	"sort"
 	"time"
 
 	"github.com/google/uuid"
@@ -10,6 +12,7 @@ import (
 	"github.com/kilang-desa-murni/crm/internal/sales/application/dto"
 	"github.com/kilang-desa-murni/crm/internal/sales/application/ports"
 	"github.com/kilang-desa-murni/crm/internal/sales/domain"
+	"github.com/kilang-desa-murni/crm/pkg/cache"
 )
 
 // ============================================================================
@@ -37,6 +40,11 @@ type PipelineUseCase interface {
 	UpdateStage(ctx context.Context, tenantID, pipelineID, stageID, userID uuid.UUID, req *dto.UpdateStageRequest) (*dto.PipelineResponse, error)
 	RemoveStage(ctx context.Context, tenantID, pipelineID, stageID, userID uuid.UUID) (*dto.PipelineResponse, error)
 	ReorderStages(ctx context.Context, tenantID, pipelineID, userID uuid.UUID, req *dto.ReorderStagesRequest) (*dto.PipelineResponse, error)
+	MigrateStages(ctx context.Context, tenantID, pipelineID, userID uuid.UUID, req *dto.MigrateStagesRequest) (*dto.StageMigrationResponse, error)
+
+	// Version history
+	ListVersions(ctx context.Context, tenantID, pipelineID uuid.UUID) (*dto.PipelineVersionListResponse, error)
+	GetVersion(ctx context.Context, tenantID, pipelineID uuid.UUID, version int) (*dto.PipelineVersionResponse, error)
 
 	// Analytics
 	GetStatistics(ctx context.Context, tenantID, pipelineID uuid.UUID) (*dto.PipelineStatisticsDTO, error)
@@ -54,27 +62,36 @@ type PipelineUseCase interface {
 
 // pipelineUseCase implements PipelineUseCase.
 type pipelineUseCase struct {
-	pipelineRepo    domain.PipelineRepository
-	opportunityRepo domain.OpportunityRepository
-	eventPublisher  ports.EventPublisher
-	cacheService    ports.CacheService
-	idGenerator     ports.IDGenerator
+	pipelineRepo      domain.PipelineRepository
+	opportunityRepo   domain.OpportunityRepository
+	eventPublisher    ports.EventPublisher
+	cacheService      ports.CacheService
+	idGenerator       ports.IDGenerator
+	currencyConverter ports.CurrencyConverter
+	revenueSplitRepo  domain.RevenueSplitRepository
+	// reads coalesces concurrent identical pipeline lookups into one repository call
+	reads cache.Group[*domain.Pipeline]
 }
 
-// NewPipelineUseCase creates a new pipeline use case.
+// NewPipelineUseCase creates a new pipeline use case. Forecasts credit
+// opportunity owners in full when revenueSplitRepo is nil.
 func NewPipelineUseCase(
 	pipelineRepo domain.PipelineRepository,
 	opportunityRepo domain.OpportunityRepository,
 	eventPublisher ports.EventPublisher,
 	cacheService ports.CacheService,
 	idGenerator ports.IDGenerator,
+	currencyConverter ports.CurrencyConverter,
+	revenueSplitRepo domain.RevenueSplitRepository,
 ) PipelineUseCase {
 	return &pipelineUseCase{
-		pipelineRepo:    pipelineRepo,
-		opportunityRepo: opportunityRepo,
-		eventPublisher:  eventPublisher,
-		cacheService:    cacheService,
-		idGenerator:     idGenerator,
+		pipelineRepo:      pipelineRepo,
+		opportunityRepo:   opportunityRepo,
+		eventPublisher:    eventPublisher,
+		cacheService:      cacheService,
+		idGenerator:       idGenerator,
+		currencyConverter: currencyConverter,
+		revenueSplitRepo:  revenueSplitRepo,
 	}
 }
 
@@ -143,6 +160,9 @@ func (uc *pipelineUseCase) Create(ctx context.Context, tenantID, userID uuid.UUI
 			if stageReq.RottenDays != nil {
 				stage.RottenDays = *stageReq.RottenDays
 			}
+			if err := uc.setStageRules(stage, stageReq.WIPLimit, stageReq.RequiredFields, stageReq.MinActivities); err != nil {
+				return nil, err
+			}
 		}
 	}
 	// Default stages are already added by NewPipeline
@@ -176,7 +196,10 @@ func (uc *pipelineUseCase) Create(ctx context.Context, tenantID, userID uuid.UUI
 
 // GetByID retrieves a pipeline by ID.
 func (uc *pipelineUseCase) GetByID(ctx context.Context, tenantID, pipelineID uuid.UUID) (*dto.PipelineResponse, error) {
-	pipeline, err := uc.pipelineRepo.GetByID(ctx, tenantID, pipelineID)
+	key := "pipeline:" + tenantID.String() + ":" + pipelineID.String()
+	pipeline, _, err := uc.reads.Do(ctx, key, func(ctx context.Context) (*domain.Pipeline, error) {
+		return uc.pipelineRepo.GetByID(ctx, tenantID, pipelineID)
+	})
 	if err != nil {
 		return nil, application.ErrPipelineNotFound(pipelineID)
 	}
@@ -338,7 +361,10 @@ func (uc *pipelineUseCase) List(ctx context.Context, tenantID uuid.UUID, filter
 
 // GetDefault retrieves the default pipeline.
 func (uc *pipelineUseCase) GetDefault(ctx context.Context, tenantID uuid.UUID) (*dto.PipelineResponse, error) {
-	pipeline, err := uc.pipelineRepo.GetDefaultPipeline(ctx, tenantID)
+	key := "pipeline:" + tenantID.String() + ":default"
+	pipeline, _, err := uc.reads.Do(ctx, key, func(ctx context.Context) (*domain.Pipeline, error) {
+		return uc.pipelineRepo.GetDefaultPipeline(ctx, tenantID)
+	})
 	if err != nil {
 		return nil, application.NewAppError(application.ErrCodePipelineNotFound, "no default pipeline found")
 	}
@@ -474,8 +500,13 @@ func (uc *pipelineUseCase) Clone(ctx context.Context, tenantID, userID uuid.UUID
 			IsActive:    s.IsActive,
 			RottenDays:  s.RottenDays,
 			AutoActions: s.AutoActions,
-			CreatedAt:   now,
-			UpdatedAt:   now,
+			WIPLimit:    s.WIPLimit,
+			EntryCriteria: domain.StageEntryCriteria{
+				RequiredFields: append([]string(nil), s.EntryCriteria.RequiredFields...),
+				MinActivities:  s.EntryCriteria.MinActivities,
+			},
+			CreatedAt: now,
+			UpdatedAt: now,
 		}
 	}
 
@@ -544,7 +575,9 @@ func (uc *pipelineUseCase) AddStage(ctx context.Context, tenantID, pipelineID, u
 	if req.RottenDays != nil {
 		stage.RottenDays = *req.RottenDays
 	}
-	// Note: RequiredFields doesn't exist on Stage
+	if err := uc.setStageRules(stage, req.WIPLimit, req.RequiredFields, req.MinActivities); err != nil {
+		return nil, err
+	}
 
 	// Update metadata
 	pipeline.UpdatedAt = time.Now().UTC()
@@ -598,7 +631,9 @@ func (uc *pipelineUseCase) UpdateStage(ctx context.Context, tenantID, pipelineID
 	if req.RottenDays != nil {
 		stage.RottenDays = *req.RottenDays
 	}
-	// Note: RequiredFields doesn't exist on Stage
+	if err := uc.setStageRules(stage, req.WIPLimit, req.RequiredFields, req.MinActivities); err != nil {
+		return nil, err
+	}
 
 	// Map auto actions (AutoAction has Type string, Config map, DelayHours int)
 	if req.AutoActions != nil {
@@ -615,6 +650,12 @@ func (uc *pipelineUseCase) UpdateStage(ctx context.Context, tenantID, pipelineID
 		}
 	}
 	if req.IsActive != nil {
+		// Opportunities cannot be left in a stage that no longer shows on the board
+		if stage.IsActive && !*req.IsActive {
+			if err := uc.ensureStageHasNoOpenOpportunities(ctx, tenantID, pipelineID, stageID); err != nil {
+				return nil, err
+			}
+		}
 		stage.IsActive = *req.IsActive
 	}
 
@@ -642,10 +683,10 @@ func (uc *pipelineUseCase) RemoveStage(ctx context.Context, tenantID, pipelineID
 		return nil, application.ErrPipelineNotFound(pipelineID)
 	}
 
-	// Check if stage has opportunities
-	opportunities, _, _ := uc.opportunityRepo.GetByStage(ctx, tenantID, pipelineID, stageID, domain.ListOptions{PageSize: 1})
-	if len(opportunities) > 0 {
-		return nil, application.ErrPipelineStageHasOpportunities(stageID)
+	// Open opportunities must be migrated first; closed ones keep referencing
+	// the stage, which is only deactivated
+	if err := uc.ensureStageHasNoOpenOpportunities(ctx, tenantID, pipelineID, stageID); err != nil {
+		return nil, err
 	}
 
 	// Remove stage
@@ -727,6 +768,155 @@ func (uc *pipelineUseCase) ReorderStages(ctx context.Context, tenantID, pipeline
 	return uc.mapPipelineToResponse(ctx, pipeline), nil
 }
 
+// MigrateStages moves the open opportunities of each source stage to its
+// mapped stage, recording the move in their stage history. Opportunities are
+// moved one by one, so a failed migration can be retried to move the rest.
+func (uc *pipelineUseCase) MigrateStages(ctx context.Context, tenantID, pipelineID, userID uuid.UUID, req *dto.MigrateStagesRequest) (*dto.StageMigrationResponse, error) {
+	pipeline, err := uc.pipelineRepo.GetByID(ctx, tenantID, pipelineID)
+	if err != nil {
+		return nil, application.ErrPipelineNotFound(pipelineID)
+	}
+
+	mapping := make(domain.StageMapping, len(req.Mappings))
+	sources := make([]uuid.UUID, 0, len(req.Mappings))
+	for _, m := range req.Mappings {
+		fromID, err := uuid.Parse(m.FromStageID)
+		if err != nil {
+			return nil, application.ErrValidationWithDetails("invalid from_stage_id", map[string]interface{}{
+				"from_stage_id": m.FromStageID,
+			})
+		}
+		toID, err := uuid.Parse(m.ToStageID)
+		if err != nil {
+			return nil, application.ErrValidationWithDetails("invalid to_stage_id", map[string]interface{}{
+				"to_stage_id": m.ToStageID,
+			})
+		}
+		if _, ok := mapping[fromID]; ok {
+			return nil, application.ErrValidationWithDetails("stage mapped more than once", map[string]interface{}{
+				"from_stage_id": m.FromStageID,
+			})
+		}
+		mapping[fromID] = toID
+		sources = append(sources, fromID)
+	}
+
+	if err := pipeline.ValidateStageMapping(mapping); err != nil {
+		return nil, application.WrapError(application.ErrCodeValidation,
+			"each stage must map to another active open stage of the pipeline that is not itself migrated", err)
+	}
+
+	notes := ""
+	if req.Notes != nil {
+		notes = *req.Notes
+	}
+
+	resp := &dto.StageMigrationResponse{
+		PipelineID: pipelineID.String(),
+		Stages:     make([]*dto.StageMigrationResultDTO, 0, len(sources)),
+	}
+	for _, fromID := range sources {
+		target := pipeline.GetStage(mapping[fromID])
+
+		opportunities, err := uc.listOpenOpportunitiesInStage(ctx, tenantID, pipelineID, fromID)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, opportunity := range opportunities {
+			if err := opportunity.MoveToStage(target, userID, notes); err != nil {
+				return nil, application.WrapError(application.ErrCodeOpportunityInvalidTransition, err.Error(), err)
+			}
+			opportunity.StageHistory[len(opportunity.StageHistory)-1].PipelineVersion = pipeline.Version
+
+			if err := uc.opportunityRepo.Update(ctx, opportunity); err != nil {
+				return nil, application.WrapError(application.ErrCodeInternal, "failed to migrate opportunity", err)
+			}
+
+			for _, event := range opportunity.GetEvents() {
+				uc.publishEvent(ctx, event)
+			}
+			opportunity.ClearEvents()
+		}
+
+		resp.Stages = append(resp.Stages, &dto.StageMigrationResultDTO{
+			FromStageID: fromID.String(),
+			ToStageID:   target.ID.String(),
+			Moved:       len(opportunities),
+		})
+		resp.TotalMoved += len(opportunities)
+	}
+
+	if req.DeactivateSourceStages {
+		for _, fromID := range sources {
+			if !pipeline.GetStage(fromID).IsActive {
+				continue
+			}
+			if err := pipeline.RemoveStage(fromID); err != nil {
+				return nil, application.WrapError(application.ErrCodeValidation, err.Error(), err)
+			}
+		}
+
+		if err := uc.validatePipelineStages(pipeline); err != nil {
+			return nil, err
+		}
+
+		pipeline.UpdatedAt = time.Now().UTC()
+		pipeline.Version++
+
+		if err := uc.pipelineRepo.Update(ctx, pipeline); err != nil {
+			return nil, application.WrapError(application.ErrCodeInternal, "failed to update pipeline", err)
+		}
+	}
+	resp.Version = pipeline.Version
+
+	// Invalidate cache
+	uc.invalidatePipelineCache(ctx, tenantID)
+
+	return resp, nil
+}
+
+// ============================================================================
+// Version History
+// ============================================================================
+
+// ListVersions lists the recorded versions of a pipeline, newest first.
+func (uc *pipelineUseCase) ListVersions(ctx context.Context, tenantID, pipelineID uuid.UUID) (*dto.PipelineVersionListResponse, error) {
+	pipeline, err := uc.pipelineRepo.GetByID(ctx, tenantID, pipelineID)
+	if err != nil {
+		return nil, application.ErrPipelineNotFound(pipelineID)
+	}
+
+	versions, err := uc.pipelineRepo.ListVersions(ctx, tenantID, pipelineID)
+	if err != nil {
+		return nil, application.WrapError(application.ErrCodeInternal, "failed to list pipeline versions", err)
+	}
+
+	resp := &dto.PipelineVersionListResponse{
+		PipelineID:     pipelineID.String(),
+		CurrentVersion: pipeline.Version,
+		Versions:       make([]*dto.PipelineVersionResponse, len(versions)),
+	}
+	for i, version := range versions {
+		resp.Versions[i] = uc.mapPipelineVersionToResponse(version)
+	}
+
+	return resp, nil
+}
+
+// GetVersion retrieves one recorded version of a pipeline.
+func (uc *pipelineUseCase) GetVersion(ctx context.Context, tenantID, pipelineID uuid.UUID, version int) (*dto.PipelineVersionResponse, error) {
+	pipelineVersion, err := uc.pipelineRepo.GetVersion(ctx, tenantID, pipelineID, version)
+	if err != nil {
+		if errors.Is(err, domain.ErrPipelineVersionNotFound) {
+			return nil, application.ErrPipelineVersionNotFound(pipelineID, version)
+		}
+		return nil, application.WrapError(application.ErrCodeInternal, "failed to get pipeline version", err)
+	}
+
+	return uc.mapPipelineVersionToResponse(pipelineVersion), nil
+}
+
 // ============================================================================
 // Analytics
 // ============================================================================
@@ -768,6 +958,7 @@ func (uc *pipelineUseCase) GetStatistics(ctx context.Context, tenantID, pipeline
 		AverageSalesCycle: stats.AverageSalesCycle,
 		StageDistribution: stageDistribution,
 		ConversionRates:   conversionRates,
+		ForecastCategories: mapForecastCategoryStatistics(stats.ForecastCategories),
 	}, nil
 }
 
@@ -838,12 +1029,22 @@ func (uc *pipelineUseCase) GetForecast(ctx context.Context, tenantID uuid.UUID,
 		return nil, application.ErrValidation("invalid end_date format")
 	}
 
+	// Forecast in the requested currency, defaulting to the tenant's base currency
+	currency := req.Currency
+	if currency == "" {
+		currency = domain.DefaultBaseCurrency
+		if uc.currencyConverter != nil {
+			if base, err := uc.currencyConverter.BaseCurrency(ctx, tenantID); err == nil {
+				currency = base
+			}
+		}
+	}
+
 	// Build filter
 	filter := domain.OpportunityFilter{
 		Statuses:               []domain.OpportunityStatus{domain.OpportunityStatusOpen},
 		ExpectedCloseDateAfter: &startDate,
 		ExpectedCloseDateBefore: &endDate,
-		Currency:               &req.Currency,
 	}
 
 	if req.PipelineID != nil {
@@ -857,19 +1058,55 @@ func (uc *pipelineUseCase) GetForecast(ctx context.Context, tenantID uuid.UUID,
 		return nil, application.WrapError(application.ErrCodeInternal, "failed to get opportunities", err)
 	}
 
+	// Split opportunities are forecast for each rep by their share
+	splits := make(map[uuid.UUID]domain.RevenueSplits)
+	if uc.revenueSplitRepo != nil && len(opportunities) > 0 {
+		ids := make([]uuid.UUID, len(opportunities))
+		for i, opp := range opportunities {
+			ids[i] = opp.ID
+		}
+		if splits, err = uc.revenueSplitRepo.GetByOpportunities(ctx, tenantID, ids); err != nil {
+			return nil, application.WrapError(application.ErrCodeInternal, "failed to get revenue splits", err)
+		}
+	}
+
 	// Calculate forecast
-	var totalForecast, committed, pipeline, upside int64
+	var totalForecast, committed, pipeline, upside, omitted int64
 	byPeriod := make(map[string]*dto.ForecastPeriodDTO)
+	byOwner := make(map[uuid.UUID]*dto.OwnerForecastDTO)
+	byCategory := make(map[domain.ForecastCategory]*dto.ForecastCategoryDTO)
+	totals := newCurrencyTotals(ctx, uc.currencyConverter, tenantID, currency, time.Now())
 
 	for _, opp := range opportunities {
-		amount := opp.WeightedAmount.Amount
+		// Opportunities without a known rate are only reported in the breakdown
+		expected, amount, ok := totals.add(opp.Amount.Currency, 1, opp.Amount.Amount, opp.WeightedAmount.Amount, opp.BaseAmount, opp.BaseWeightedAmount)
+		if !ok {
+			continue
+		}
 
-		// Categorize by probability
-		if opp.Probability >= 75 {
+		// Categorize by forecast category, derived from the probability when unset
+		category := opp.EffectiveForecastCategory()
+		if _, ok := byCategory[category]; !ok {
+			byCategory[category] = &dto.ForecastCategoryDTO{
+				Category:      string(category),
+				TotalValue:    dto.MoneyDTO{Currency: currency},
+				WeightedValue: dto.MoneyDTO{Currency: currency},
+			}
+		}
+		byCategory[category].OpportunityCount++
+		byCategory[category].TotalValue.Amount += expected
+		byCategory[category].WeightedValue.Amount += amount
+
+		switch category {
+		case domain.ForecastCategoryOmitted:
+			// Omitted opportunities are left out of the forecast
+			omitted += amount
+			continue
+		case domain.ForecastCategoryCommit:
 			committed += amount
-		} else if opp.Probability >= 25 {
+		case domain.ForecastCategoryPipeline:
 			pipeline += amount
-		} else {
+		default:
 			upside += amount
 		}
 		totalForecast += amount
@@ -886,37 +1123,44 @@ func (uc *pipelineUseCase) GetForecast(ctx context.Context, tenantID uuid.UUID,
 				Period: periodKey,
 				ExpectedRevenue: dto.MoneyDTO{
 					Amount:   0,
-					Currency: req.Currency,
+					Currency: currency,
 				},
 				WeightedRevenue: dto.MoneyDTO{
 					Amount:   0,
-					Currency: req.Currency,
+					Currency: currency,
 				},
 				Committed: dto.MoneyDTO{
 					Amount:   0,
-					Currency: req.Currency,
+					Currency: currency,
 				},
 				Pipeline: dto.MoneyDTO{
 					Amount:   0,
-					Currency: req.Currency,
+					Currency: currency,
 				},
 				Upside: dto.MoneyDTO{
 					Amount:   0,
-					Currency: req.Currency,
+					Currency: currency,
 				},
 			}
 		}
-		byPeriod[periodKey].ExpectedRevenue.Amount += opp.Amount.Amount
+		byPeriod[periodKey].ExpectedRevenue.Amount += expected
 		byPeriod[periodKey].WeightedRevenue.Amount += amount
 		byPeriod[periodKey].OpportunityCount++
 
-		if opp.Probability >= 75 {
+		switch category {
+		case domain.ForecastCategoryCommit:
 			byPeriod[periodKey].Committed.Amount += amount
-		} else if opp.Probability >= 25 {
+		case domain.ForecastCategoryPipeline:
 			byPeriod[periodKey].Pipeline.Amount += amount
-		} else {
+		default:
 			byPeriod[periodKey].Upside.Amount += amount
 		}
+
+		oppSplits, ok := splits[opp.ID]
+		if !ok {
+			oppSplits = domain.OwnerRevenueSplits(opp)
+		}
+		creditForecastOwners(byOwner, oppSplits, category, expected, amount, currency)
 	}
 
 	// Convert map to slice
@@ -925,6 +1169,24 @@ func (uc *pipelineUseCase) GetForecast(ctx context.Context, tenantID uuid.UUID,
 		periods = append(periods, *period)
 	}
 
+	owners := make([]dto.OwnerForecastDTO, 0, len(byOwner))
+	for _, owner := range byOwner {
+		owners = append(owners, *owner)
+	}
+	sort.Slice(owners, func(i, j int) bool {
+		if owners[i].WeightedRevenue.Amount != owners[j].WeightedRevenue.Amount {
+			return owners[i].WeightedRevenue.Amount > owners[j].WeightedRevenue.Amount
+		}
+		return owners[i].OwnerID < owners[j].OwnerID
+	})
+
+	categories := make([]dto.ForecastCategoryDTO, 0, len(byCategory))
+	for _, category := range domain.ValidForecastCategories() {
+		if c, ok := byCategory[category]; ok {
+			categories = append(categories, *c)
+		}
+	}
+
 	// Calculate best/worst case
 	bestCase := committed + pipeline + upside
 	worstCase := committed
@@ -934,35 +1196,73 @@ func (uc *pipelineUseCase) GetForecast(ctx context.Context, tenantID uuid.UUID,
 			StartDate: startDate,
 			EndDate:   endDate,
 		},
-		Currency: req.Currency,
+		Currency: currency,
 		TotalForecast: dto.MoneyDTO{
 			Amount:   totalForecast,
-			Currency: req.Currency,
+			Currency: currency,
 		},
 		BestCase: dto.MoneyDTO{
 			Amount:   bestCase,
-			Currency: req.Currency,
+			Currency: currency,
 		},
 		WorstCase: dto.MoneyDTO{
 			Amount:   worstCase,
-			Currency: req.Currency,
+			Currency: currency,
 		},
 		Committed: dto.MoneyDTO{
 			Amount:   committed,
-			Currency: req.Currency,
+			Currency: currency,
 		},
 		Pipeline: dto.MoneyDTO{
 			Amount:   pipeline,
-			Currency: req.Currency,
+			Currency: currency,
 		},
 		Upside: dto.MoneyDTO{
 			Amount:   upside,
-			Currency: req.Currency,
+			Currency: currency,
+		},
+		Omitted: dto.MoneyDTO{
+			Amount:   omitted,
+			Currency: currency,
 		},
-		ByPeriod: periods,
+		ByCategory: categories,
+		ByPeriod:   periods,
+		ByOwner:    owners,
+		ByCurrency: totals.breakdown(),
 	}, nil
 }
 
+// creditForecastOwners adds an opportunity's expected and weighted revenue to
+// the forecast of each rep by their split of it.
+func creditForecastOwners(byOwner map[uuid.UUID]*dto.OwnerForecastDTO, splits domain.RevenueSplits, category domain.ForecastCategory, expected, weighted int64, currency string) {
+	expectedCredits := splits.Credits(expected)
+	weightedCredits := splits.Credits(weighted)
+	for i, split := range splits {
+		owner, ok := byOwner[split.UserID]
+		if !ok {
+			owner = &dto.OwnerForecastDTO{
+				OwnerID:         split.UserID.String(),
+				OwnerName:       split.UserName,
+				ExpectedRevenue: dto.MoneyDTO{Currency: currency},
+				WeightedRevenue: dto.MoneyDTO{Currency: currency},
+				Committed:       dto.MoneyDTO{Currency: currency},
+				Pipeline:        dto.MoneyDTO{Currency: currency},
+			}
+			byOwner[split.UserID] = owner
+		}
+		owner.ExpectedRevenue.Amount += expectedCredits[i]
+		owner.WeightedRevenue.Amount += weightedCredits[i]
+		owner.OpportunityCount++
+
+		switch category {
+		case domain.ForecastCategoryCommit:
+			owner.Committed.Amount += weightedCredits[i]
+		case domain.ForecastCategoryPipeline:
+			owner.Pipeline.Amount += weightedCredits[i]
+		}
+	}
+}
+
 // ============================================================================
 // Templates
 // ============================================================================
@@ -1098,45 +1398,7 @@ func (uc *pipelineUseCase) mapPipelineToResponse(ctx context.Context, pipeline *
 	// Map stages
 	resp.Stages = make([]*dto.StageResponse, len(pipeline.Stages))
 	for i, stage := range pipeline.Stages {
-		stageResp := &dto.StageResponse{
-			ID:          stage.ID.String(),
-			PipelineID:  pipeline.ID.String(),
-			Name:        stage.Name,
-			Type:        string(stage.Type),
-			Order:       stage.Order,
-			Probability: stage.Probability,
-			Color:       stage.Color,
-			IsActive:    stage.IsActive,
-			CreatedAt:   stage.CreatedAt,
-			UpdatedAt:   stage.UpdatedAt,
-		}
-
-		// Description is string in domain, *string in DTO
-		if stage.Description != "" {
-			stageResp.Description = &stage.Description
-		}
-
-		// RottenDays is int in domain, *int in DTO
-		if stage.RottenDays > 0 {
-			stageResp.RottenDays = &stage.RottenDays
-		}
-
-		// Note: RequiredFields doesn't exist on Stage
-
-		// Map auto actions (AutoAction has Type string, Config map, DelayHours int)
-		if len(stage.AutoActions) > 0 {
-			stageResp.AutoActions = make([]*dto.StageAutoActionDTO, len(stage.AutoActions))
-			for j, action := range stage.AutoActions {
-				delayHours := action.DelayHours
-				stageResp.AutoActions[j] = &dto.StageAutoActionDTO{
-					Type:       action.Type,
-					Config:     action.Config,
-					DelayHours: &delayHours,
-				}
-			}
-		}
-
-		resp.Stages[i] = stageResp
+		resp.Stages[i] = uc.mapStageToResponse(pipeline.ID, stage)
 	}
 
 	// Map custom fields schema
@@ -1150,6 +1412,71 @@ func (uc *pipelineUseCase) mapPipelineToResponse(ctx context.Context, pipeline *
 	return resp
 }
 
+func (uc *pipelineUseCase) mapStageToResponse(pipelineID uuid.UUID, stage *domain.Stage) *dto.StageResponse {
+	stageResp := &dto.StageResponse{
+		ID:          stage.ID.String(),
+		PipelineID:  pipelineID.String(),
+		Name:        stage.Name,
+		Type:        string(stage.Type),
+		Order:       stage.Order,
+		Probability: stage.Probability,
+		Color:       stage.Color,
+		IsActive:    stage.IsActive,
+		CreatedAt:   stage.CreatedAt,
+		UpdatedAt:   stage.UpdatedAt,
+	}
+
+	// Description is string in domain, *string in DTO
+	if stage.Description != "" {
+		stageResp.Description = &stage.Description
+	}
+
+	// RottenDays is int in domain, *int in DTO
+	if stage.RottenDays > 0 {
+		stageResp.RottenDays = &stage.RottenDays
+	}
+
+	if len(stage.EntryCriteria.RequiredFields) > 0 {
+		stageResp.RequiredFields = stage.EntryCriteria.RequiredFields
+	}
+	if stage.EntryCriteria.MinActivities > 0 {
+		stageResp.MinActivities = &stage.EntryCriteria.MinActivities
+	}
+	if stage.WIPLimit > 0 {
+		stageResp.WIPLimit = &stage.WIPLimit
+	}
+
+	// Map auto actions (AutoAction has Type string, Config map, DelayHours int)
+	if len(stage.AutoActions) > 0 {
+		stageResp.AutoActions = make([]*dto.StageAutoActionDTO, len(stage.AutoActions))
+		for j, action := range stage.AutoActions {
+			delayHours := action.DelayHours
+			stageResp.AutoActions[j] = &dto.StageAutoActionDTO{
+				Type:       action.Type,
+				Config:     action.Config,
+				DelayHours: &delayHours,
+			}
+		}
+	}
+
+	return stageResp
+}
+
+func (uc *pipelineUseCase) mapPipelineVersionToResponse(version *domain.PipelineVersion) *dto.PipelineVersionResponse {
+	resp := &dto.PipelineVersionResponse{
+		PipelineID: version.PipelineID.String(),
+		Version:    version.Version,
+		Name:       version.Name,
+		Currency:   version.Currency,
+		Stages:     make([]*dto.StageResponse, len(version.Stages)),
+		CreatedAt:  version.CreatedAt,
+	}
+	for i, stage := range version.Stages {
+		resp.Stages[i] = uc.mapStageToResponse(version.PipelineID, stage)
+	}
+	return resp
+}
+
 func (uc *pipelineUseCase) createStageFromRequest(req *dto.CreateStageRequest) *domain.Stage {
 	now := time.Now().UTC()
 	stage := &domain.Stage{
@@ -1174,7 +1501,13 @@ func (uc *pipelineUseCase) createStageFromRequest(req *dto.CreateStageRequest) *
 		stage.RottenDays = *req.RottenDays
 	}
 
-	// Note: RequiredFields doesn't exist on Stage
+	stage.EntryCriteria.RequiredFields = req.RequiredFields
+	if req.MinActivities != nil {
+		stage.EntryCriteria.MinActivities = *req.MinActivities
+	}
+	if req.WIPLimit != nil {
+		stage.WIPLimit = *req.WIPLimit
+	}
 
 	// Map auto actions (AutoAction has Type string, Config map, DelayHours int)
 	if len(req.AutoActions) > 0 {
@@ -1194,6 +1527,26 @@ func (uc *pipelineUseCase) createStageFromRequest(req *dto.CreateStageRequest) *
 	return stage
 }
 
+// setStageRules sets the WIP limit and entry criteria of a stage from a
+// request, keeping the current value of each setting the request leaves out.
+func (uc *pipelineUseCase) setStageRules(stage *domain.Stage, wipLimit *int, requiredFields []string, minActivities *int) error {
+	limit := stage.WIPLimit
+	if wipLimit != nil {
+		limit = *wipLimit
+	}
+	criteria := stage.EntryCriteria
+	if requiredFields != nil {
+		criteria.RequiredFields = requiredFields
+	}
+	if minActivities != nil {
+		criteria.MinActivities = *minActivities
+	}
+	if err := stage.SetRules(limit, criteria); err != nil {
+		return application.ErrValidation(err.Error())
+	}
+	return nil
+}
+
 func (uc *pipelineUseCase) addDefaultStages(pipeline *domain.Pipeline) {
 	defaultStages := []*domain.Stage{
 		{
@@ -1287,6 +1640,48 @@ func (uc *pipelineUseCase) validatePipelineStages(pipeline *domain.Pipeline) err
 	return nil
 }
 
+// listOpenOpportunitiesInStage returns every open opportunity in a stage.
+func (uc *pipelineUseCase) listOpenOpportunitiesInStage(ctx context.Context, tenantID, pipelineID, stageID uuid.UUID) ([]*domain.Opportunity, error) {
+	filter := domain.OpportunityFilter{
+		Statuses:    []domain.OpportunityStatus{domain.OpportunityStatusOpen},
+		PipelineIDs: []uuid.UUID{pipelineID},
+		StageIDs:    []uuid.UUID{stageID},
+	}
+	opts := domain.ListOptions{Page: 1, PageSize: 100, SortBy: "created_at", SortOrder: "asc"}
+
+	var result []*domain.Opportunity
+	for {
+		opportunities, total, err := uc.opportunityRepo.List(ctx, tenantID, filter, opts)
+		if err != nil {
+			return nil, application.WrapError(application.ErrCodeInternal, "failed to list stage opportunities", err)
+		}
+		result = append(result, opportunities...)
+		if len(opportunities) == 0 || int64(len(result)) >= total {
+			return result, nil
+		}
+		opts.Page++
+	}
+}
+
+// ensureStageHasNoOpenOpportunities returns an error naming the number of open
+// opportunities left in a stage, if any.
+func (uc *pipelineUseCase) ensureStageHasNoOpenOpportunities(ctx context.Context, tenantID, pipelineID, stageID uuid.UUID) error {
+	filter := domain.OpportunityFilter{
+		Statuses:    []domain.OpportunityStatus{domain.OpportunityStatusOpen},
+		PipelineIDs: []uuid.UUID{pipelineID},
+		StageIDs:    []uuid.UUID{stageID},
+	}
+
+	_, total, err := uc.opportunityRepo.List(ctx, tenantID, filter, domain.ListOptions{Page: 1, PageSize: 1})
+	if err != nil {
+		return application.WrapError(application.ErrCodeInternal, "failed to count stage opportunities", err)
+	}
+	if total > 0 {
+		return application.ErrPipelineStageHasOpportunities(stageID, total)
+	}
+	return nil
+}
+
 func (uc *pipelineUseCase) unsetOtherDefaults(ctx context.Context, tenantID, exceptPipelineID uuid.UUID) error {
 	pipelines, _, err := uc.pipelineRepo.List(ctx, tenantID, domain.ListOptions{PageSize: 100})
 	if err != nil {
@@ -1353,6 +1748,26 @@ func (uc *pipelineUseCase) mapCustomFieldSchemaDomainToDTO(field domain.CustomFi
 	return result
 }
 
+// mapForecastCategoryStatistics maps the open value of each forecast category.
+func mapForecastCategoryStatistics(stats []domain.ForecastCategoryStatistics) []dto.ForecastCategoryDTO {
+	categories := make([]dto.ForecastCategoryDTO, len(stats))
+	for i, s := range stats {
+		categories[i] = dto.ForecastCategoryDTO{
+			Category:         string(s.Category),
+			OpportunityCount: s.OpportunityCount,
+			TotalValue: dto.MoneyDTO{
+				Amount:   s.TotalValue.Amount,
+				Currency: s.TotalValue.Currency,
+			},
+			WeightedValue: dto.MoneyDTO{
+				Amount:   s.WeightedValue.Amount,
+				Currency: s.WeightedValue.Currency,
+			},
+		}
+	}
+	return categories
+}
+
 func (uc *pipelineUseCase) getPeriodKey(date time.Time, groupBy string) string {
 	switch groupBy {
 	case "day":
@@ -1385,6 +1800,9 @@ func (uc *pipelineUseCase) publishEvent(ctx context.Context, event domain.Domain
 }
 
 func (uc *pipelineUseCase) invalidatePipelineCache(ctx context.Context, tenantID uuid.UUID) {
+	// Reads after a write must not join a lookup that started before it
+	uc.reads.ForgetPrefix("pipeline:" + tenantID.String() + ":")
+
 	if uc.cacheService == nil {
 		return
 	}
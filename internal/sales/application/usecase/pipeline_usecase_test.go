@@ -8,6 +8,7 @@ import (
 
 	"github.com/google/uuid"
 
+	"github.com/kilang-desa-murni/crm/internal/sales/application"
 	"github.com/kilang-desa-murni/crm/internal/sales/application/dto"
 	"github.com/kilang-desa-murni/crm/internal/sales/application/ports"
 	"github.com/kilang-desa-murni/crm/internal/sales/domain"
@@ -20,6 +21,7 @@ import (
 // PipelineMockRepo is a mock implementation of domain.PipelineRepository.
 type PipelineMockRepo struct {
 	pipelines        map[uuid.UUID]*domain.Pipeline
+	versions         []*domain.PipelineVersion
 	createErr        error
 	updateErr        error
 	deleteErr        error
@@ -39,6 +41,7 @@ func (m *PipelineMockRepo) Create(ctx context.Context, pipeline *domain.Pipeline
 		return m.createErr
 	}
 	m.pipelines[pipeline.ID] = pipeline
+	m.versions = append(m.versions, domain.NewPipelineVersion(pipeline))
 	return nil
 }
 
@@ -47,6 +50,7 @@ func (m *PipelineMockRepo) Update(ctx context.Context, pipeline *domain.Pipeline
 		return m.updateErr
 	}
 	m.pipelines[pipeline.ID] = pipeline
+	m.versions = append(m.versions, domain.NewPipelineVersion(pipeline))
 	return nil
 }
 
@@ -159,6 +163,25 @@ func (m *PipelineMockRepo) ReorderStages(ctx context.Context, tenantID, pipeline
 	return nil
 }
 
+func (m *PipelineMockRepo) GetVersion(ctx context.Context, tenantID, pipelineID uuid.UUID, version int) (*domain.PipelineVersion, error) {
+	for _, v := range m.versions {
+		if v.TenantID == tenantID && v.PipelineID == pipelineID && v.Version == version {
+			return v, nil
+		}
+	}
+	return nil, domain.ErrPipelineVersionNotFound
+}
+
+func (m *PipelineMockRepo) ListVersions(ctx context.Context, tenantID, pipelineID uuid.UUID) ([]*domain.PipelineVersion, error) {
+	var result []*domain.PipelineVersion
+	for i := len(m.versions) - 1; i >= 0; i-- {
+		if v := m.versions[i]; v.TenantID == tenantID && v.PipelineID == pipelineID {
+			result = append(result, v)
+		}
+	}
+	return result, nil
+}
+
 func (m *PipelineMockRepo) GetPipelineStatistics(ctx context.Context, tenantID, pipelineID uuid.UUID) (*domain.PipelineStatistics, error) {
 	if m.getStatisticsErr != nil {
 		return nil, m.getStatisticsErr
@@ -228,13 +251,41 @@ func (m *MockPipelineOpportunityRepository) GetByID(ctx context.Context, tenantI
 func (m *MockPipelineOpportunityRepository) List(ctx context.Context, tenantID uuid.UUID, filter domain.OpportunityFilter, opts domain.ListOptions) ([]*domain.Opportunity, int64, error) {
 	var result []*domain.Opportunity
 	for _, opp := range m.opportunities {
-		if opp.TenantID == tenantID {
-			result = append(result, opp)
+		if opp.TenantID != tenantID {
+			continue
+		}
+		if len(filter.Statuses) > 0 && !containsStatus(filter.Statuses, opp.Status) {
+			continue
 		}
+		if len(filter.PipelineIDs) > 0 && !containsUUID(filter.PipelineIDs, opp.PipelineID) {
+			continue
+		}
+		if len(filter.StageIDs) > 0 && !containsUUID(filter.StageIDs, opp.StageID) {
+			continue
+		}
+		result = append(result, opp)
 	}
 	return result, int64(len(result)), nil
 }
 
+func containsStatus(statuses []domain.OpportunityStatus, status domain.OpportunityStatus) bool {
+	for _, s := range statuses {
+		if s == status {
+			return true
+		}
+	}
+	return false
+}
+
+func containsUUID(ids []uuid.UUID, id uuid.UUID) bool {
+	for _, i := range ids {
+		if i == id {
+			return true
+		}
+	}
+	return false
+}
+
 func (m *MockPipelineOpportunityRepository) GetByStatus(ctx context.Context, tenantID uuid.UUID, status domain.OpportunityStatus, opts domain.ListOptions) ([]*domain.Opportunity, int64, error) {
 	return []*domain.Opportunity{}, 0, nil
 }
@@ -277,6 +328,10 @@ func (m *MockPipelineOpportunityRepository) GetByStage(ctx context.Context, tena
 	return result, int64(len(result)), nil
 }
 
+func (m *MockPipelineOpportunityRepository) GetBoardPositionNeighbors(ctx context.Context, tenantID, stageID, excludeID uuid.UUID, index int) (string, string, error) {
+	return "", "", nil
+}
+
 func (m *MockPipelineOpportunityRepository) GetByCustomer(ctx context.Context, tenantID, customerID uuid.UUID, opts domain.ListOptions) ([]*domain.Opportunity, int64, error) {
 	return []*domain.Opportunity{}, 0, nil
 }
@@ -321,6 +376,10 @@ func (m *MockPipelineOpportunityRepository) GetWeightedPipelineValue(ctx context
 	return 0, nil
 }
 
+func (m *MockPipelineOpportunityRepository) GetPipelineValueByCurrency(ctx context.Context, tenantID uuid.UUID) ([]*domain.PipelineCurrencyValue, error) {
+	return nil, nil
+}
+
 func (m *MockPipelineOpportunityRepository) BulkUpdateOwner(ctx context.Context, tenantID uuid.UUID, opportunityIDs []uuid.UUID, newOwnerID uuid.UUID) error {
 	return nil
 }
@@ -349,6 +408,10 @@ func (m *MockPipelineOpportunityRepository) GetAverageSalesCycle(ctx context.Con
 	return 0, nil
 }
 
+func (m *MockPipelineOpportunityRepository) GetCloseBreakdown(ctx context.Context, tenantID uuid.UUID, pipelineID *uuid.UUID, status domain.OpportunityStatus) (*domain.CloseBreakdown, error) {
+	return &domain.CloseBreakdown{}, nil
+}
+
 // MockPipelineEventPublisher is a mock implementation of ports.EventPublisher for pipeline tests.
 type MockPipelineEventPublisher struct {
 	events []ports.Event
@@ -596,7 +659,7 @@ func setupPipelineUseCase() (*pipelineUseCase, *PipelineMockRepo, *MockPipelineO
 	cacheService := NewMockPipelineCacheService()
 	idGenerator := NewMockPipelineIDGenerator()
 
-	uc := NewPipelineUseCase(pipelineRepo, oppRepo, eventPublisher, cacheService, idGenerator)
+	uc := NewPipelineUseCase(pipelineRepo, oppRepo, eventPublisher, cacheService, idGenerator, nil, nil)
 	return uc.(*pipelineUseCase), pipelineRepo, oppRepo
 }
 
@@ -1261,6 +1324,45 @@ func TestPipelineUseCase_UpdateStage_StageNotFound(t *testing.T) {
 	}
 }
 
+func TestPipelineUseCase_UpdateStage_Rules(t *testing.T) {
+	// Arrange
+	uc, pipelineRepo, _ := setupPipelineUseCase()
+
+	tenantID := uuid.New()
+	userID := uuid.New()
+	pipeline := createPipelineForTest(tenantID)
+	pipelineRepo.pipelines[pipeline.ID] = pipeline
+
+	stageID := pipeline.Stages[0].ID
+	wipLimit := 10
+	minActivities := 2
+	req := &dto.UpdateStageRequest{
+		WIPLimit:       &wipLimit,
+		RequiredFields: []string{"amount", "custom_fields.budget"},
+		MinActivities:  &minActivities,
+	}
+
+	// Act
+	result, err := uc.UpdateStage(context.Background(), tenantID, pipeline.ID, stageID, userID, req)
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	stage := result.Stages[0]
+	if stage.WIPLimit == nil || *stage.WIPLimit != 10 || len(stage.RequiredFields) != 2 || stage.MinActivities == nil || *stage.MinActivities != 2 {
+		t.Errorf("Expected stage rules to be set, got limit %v, fields %v, activities %v", stage.WIPLimit, stage.RequiredFields, stage.MinActivities)
+	}
+
+	// Unknown fields cannot be required
+	_, err = uc.UpdateStage(context.Background(), tenantID, pipeline.ID, stageID, userID, &dto.UpdateStageRequest{
+		RequiredFields: []string{"favourite_colour"},
+	})
+	if appErr := application.GetAppError(err); appErr == nil || appErr.Code != application.ErrCodeValidation {
+		t.Errorf("Expected validation error for unknown field, got: %v", err)
+	}
+}
+
 // ============================================================================
 // PipelineUseCase Tests - RemoveStage
 // ============================================================================
@@ -1321,6 +1423,7 @@ func TestPipelineUseCase_RemoveStage_WithOpportunities(t *testing.T) {
 		PipelineID: pipeline.ID,
 		StageID:    stageID,
 		Name:       "Test Opportunity",
+		Status:     domain.OpportunityStatusOpen,
 		Amount:     amount,
 	}
 	oppRepo.opportunities[opp.ID] = opp
@@ -1332,6 +1435,161 @@ func TestPipelineUseCase_RemoveStage_WithOpportunities(t *testing.T) {
 	if err == nil {
 		t.Fatal("Expected error for removing stage with opportunities, got nil")
 	}
+	if appErr := application.GetAppError(err); appErr == nil || appErr.Code != application.ErrCodePipelineStageHasOpportunities {
+		t.Errorf("Expected stage has opportunities error, got: %v", err)
+	}
+}
+
+func TestPipelineUseCase_UpdateStage_DeactivateWithOpenOpportunities(t *testing.T) {
+	uc, pipelineRepo, oppRepo := setupPipelineUseCase()
+
+	tenantID := uuid.New()
+	pipeline := createPipelineForTest(tenantID)
+	pipelineRepo.pipelines[pipeline.ID] = pipeline
+	stage := pipeline.Stages[1]
+	opp := newPipelineTestOpportunity(tenantID, pipeline.ID, stage.ID)
+	oppRepo.opportunities[opp.ID] = opp
+
+	inactive := false
+	_, err := uc.UpdateStage(context.Background(), tenantID, pipeline.ID, stage.ID, uuid.New(), &dto.UpdateStageRequest{IsActive: &inactive})
+	if appErr := application.GetAppError(err); appErr == nil || appErr.Code != application.ErrCodePipelineStageHasOpportunities {
+		t.Fatalf("UpdateStage() error = %v, want stage has opportunities error", err)
+	}
+	if !stage.IsActive {
+		t.Error("UpdateStage() deactivated a stage with open opportunities")
+	}
+
+	// Closed opportunities do not block the stage
+	opp.Status = domain.OpportunityStatusWon
+	if _, err := uc.UpdateStage(context.Background(), tenantID, pipeline.ID, stage.ID, uuid.New(), &dto.UpdateStageRequest{IsActive: &inactive}); err != nil {
+		t.Fatalf("UpdateStage() error = %v", err)
+	}
+}
+
+// ============================================================================
+// PipelineUseCase Tests - Versions and Stage Migration
+// ============================================================================
+
+func newPipelineTestOpportunity(tenantID, pipelineID, stageID uuid.UUID) *domain.Opportunity {
+	amount, _ := domain.NewMoney(10000, "USD")
+	return &domain.Opportunity{
+		ID:         uuid.New(),
+		TenantID:   tenantID,
+		PipelineID: pipelineID,
+		StageID:    stageID,
+		Name:       "Test Opportunity",
+		Status:     domain.OpportunityStatusOpen,
+		Amount:     amount,
+	}
+}
+
+func TestPipelineUseCase_MigrateStages_Success(t *testing.T) {
+	uc, pipelineRepo, oppRepo := setupPipelineUseCase()
+
+	tenantID := uuid.New()
+	pipeline := createPipelineForTest(tenantID)
+	pipelineRepo.pipelines[pipeline.ID] = pipeline
+	from, to := pipeline.Stages[1], pipeline.Stages[2]
+
+	moved := []*domain.Opportunity{
+		newPipelineTestOpportunity(tenantID, pipeline.ID, from.ID),
+		newPipelineTestOpportunity(tenantID, pipeline.ID, from.ID),
+	}
+	closed := newPipelineTestOpportunity(tenantID, pipeline.ID, from.ID)
+	closed.Status = domain.OpportunityStatusLost
+	for _, opp := range append(moved, closed) {
+		oppRepo.opportunities[opp.ID] = opp
+	}
+
+	resp, err := uc.MigrateStages(context.Background(), tenantID, pipeline.ID, uuid.New(), &dto.MigrateStagesRequest{
+		Mappings:               []dto.StageMappingDTO{{FromStageID: from.ID.String(), ToStageID: to.ID.String()}},
+		DeactivateSourceStages: true,
+	})
+	if err != nil {
+		t.Fatalf("MigrateStages() error = %v", err)
+	}
+	if resp.TotalMoved != 2 || len(resp.Stages) != 1 || resp.Stages[0].Moved != 2 {
+		t.Fatalf("MigrateStages() moved %d opportunities, want 2", resp.TotalMoved)
+	}
+	for _, opp := range moved {
+		if opp.StageID != to.ID {
+			t.Errorf("MigrateStages() left opportunity in stage %s, want %s", opp.StageID, to.ID)
+		}
+		if last := opp.StageHistory[len(opp.StageHistory)-1]; last.PipelineVersion != 1 {
+			t.Errorf("MigrateStages() history pipeline version = %d, want 1", last.PipelineVersion)
+		}
+	}
+	if closed.StageID != from.ID {
+		t.Error("MigrateStages() moved a closed opportunity")
+	}
+	if from.IsActive || resp.Version != 2 {
+		t.Errorf("MigrateStages() source active = %v, version = %d; want inactive source and version 2", from.IsActive, resp.Version)
+	}
+}
+
+func TestPipelineUseCase_MigrateStages_InvalidMapping(t *testing.T) {
+	uc, pipelineRepo, _ := setupPipelineUseCase()
+
+	tenantID := uuid.New()
+	pipeline := createPipelineForTest(tenantID)
+	pipelineRepo.pipelines[pipeline.ID] = pipeline
+	open, won := pipeline.Stages[1], pipeline.GetWonStage()
+
+	tests := []struct {
+		name     string
+		mappings []dto.StageMappingDTO
+	}{
+		{"invalid uuid", []dto.StageMappingDTO{{FromStageID: "nope", ToStageID: open.ID.String()}}},
+		{"closed target", []dto.StageMappingDTO{{FromStageID: open.ID.String(), ToStageID: won.ID.String()}}},
+		{"duplicate source", []dto.StageMappingDTO{
+			{FromStageID: open.ID.String(), ToStageID: pipeline.Stages[0].ID.String()},
+			{FromStageID: open.ID.String(), ToStageID: pipeline.Stages[2].ID.String()},
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := uc.MigrateStages(context.Background(), tenantID, pipeline.ID, uuid.New(), &dto.MigrateStagesRequest{Mappings: tt.mappings})
+			if appErr := application.GetAppError(err); appErr == nil || appErr.Code != application.ErrCodeValidation {
+				t.Errorf("MigrateStages() error = %v, want validation error", err)
+			}
+		})
+	}
+}
+
+func TestPipelineUseCase_Versions(t *testing.T) {
+	uc, pipelineRepo, _ := setupPipelineUseCase()
+
+	tenantID := uuid.New()
+	pipeline := createPipelineForTest(tenantID)
+	pipelineRepo.Create(context.Background(), pipeline)
+	originalName := pipeline.Stages[0].Name
+
+	newName := "Discovery"
+	if _, err := uc.UpdateStage(context.Background(), tenantID, pipeline.ID, pipeline.Stages[0].ID, uuid.New(), &dto.UpdateStageRequest{Name: &newName}); err != nil {
+		t.Fatalf("UpdateStage() error = %v", err)
+	}
+
+	list, err := uc.ListVersions(context.Background(), tenantID, pipeline.ID)
+	if err != nil {
+		t.Fatalf("ListVersions() error = %v", err)
+	}
+	if len(list.Versions) != 2 || list.Versions[0].Version != 2 || list.CurrentVersion != 2 {
+		t.Fatalf("ListVersions() = %d versions, current %d; want versions 2 and 1", len(list.Versions), list.CurrentVersion)
+	}
+
+	first, err := uc.GetVersion(context.Background(), tenantID, pipeline.ID, 1)
+	if err != nil {
+		t.Fatalf("GetVersion() error = %v", err)
+	}
+	if first.Stages[0].Name != originalName {
+		t.Errorf("GetVersion(1) stage name = %q, want %q", first.Stages[0].Name, originalName)
+	}
+
+	_, err = uc.GetVersion(context.Background(), tenantID, pipeline.ID, 9)
+	if appErr := application.GetAppError(err); appErr == nil || appErr.Code != application.ErrCodePipelineVersionNotFound {
+		t.Errorf("GetVersion(9) error = %v, want version not found", err)
+	}
 }
 
 // ============================================================================
@@ -2330,3 +2588,109 @@ func TestPipelineUseCase_Delete_RepositoryError(t *testing.T) {
 		t.Fatal("Expected error for repository error, got nil")
 	}
 }
+
+func TestPipelineUseCase_GetForecast_ByForecastCategory(t *testing.T) {
+	// Arrange
+	uc, _, oppRepo := setupPipelineUseCase()
+
+	tenantID := uuid.New()
+	closeDate := time.Date(2026, 3, 15, 0, 0, 0, 0, time.UTC)
+	addOpportunity := func(amount int64, probability int, category domain.ForecastCategory) {
+		opp := &domain.Opportunity{
+			ID:                uuid.New(),
+			TenantID:          tenantID,
+			Status:            domain.OpportunityStatusOpen,
+			Amount:            domain.Money{Amount: amount, Currency: "USD"},
+			WeightedAmount:    domain.Money{Amount: amount * int64(probability) / 100, Currency: "USD"},
+			Probability:       probability,
+			ForecastCategory:  category,
+			ExpectedCloseDate: &closeDate,
+		}
+		oppRepo.opportunities[opp.ID] = opp
+	}
+	addOpportunity(10000, 80, "")                              // commit by probability
+	addOpportunity(10000, 10, domain.ForecastCategoryCommit)   // committed by hand
+	addOpportunity(10000, 50, "")                              // pipeline by probability
+	addOpportunity(10000, 90, domain.ForecastCategoryOmitted)  // left out
+
+	req := &dto.ForecastRequest{StartDate: "2026-01-01", EndDate: "2026-12-31", Currency: "USD"}
+
+	// Act
+	result, err := uc.GetForecast(context.Background(), tenantID, req)
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if result.Committed.Amount != 9000 {
+		t.Errorf("Expected committed 9000, got %d", result.Committed.Amount)
+	}
+	if result.Pipeline.Amount != 5000 {
+		t.Errorf("Expected pipeline 5000, got %d", result.Pipeline.Amount)
+	}
+	if result.Omitted.Amount != 9000 {
+		t.Errorf("Expected omitted 9000, got %d", result.Omitted.Amount)
+	}
+	if result.TotalForecast.Amount != 14000 {
+		t.Errorf("Expected total forecast 14000 without omitted, got %d", result.TotalForecast.Amount)
+	}
+	if len(result.ByCategory) != 3 || result.ByCategory[0].Category != "commit" || result.ByCategory[0].OpportunityCount != 2 {
+		t.Errorf("Unexpected category breakdown: %+v", result.ByCategory)
+	}
+}
+
+func TestPipelineUseCase_GetForecast_ByOwnerWithRevenueSplits(t *testing.T) {
+	// Arrange
+	uc, _, oppRepo := setupPipelineUseCase()
+	splitRepo := NewMockRevenueSplitRepository()
+	uc.revenueSplitRepo = splitRepo
+
+	tenantID := uuid.New()
+	hafiz, siti := uuid.New(), uuid.New()
+	closeDate := time.Date(2026, 3, 15, 0, 0, 0, 0, time.UTC)
+	addOpportunity := func(amount int64, probability int) *domain.Opportunity {
+		opp := &domain.Opportunity{
+			ID:                uuid.New(),
+			TenantID:          tenantID,
+			Status:            domain.OpportunityStatusOpen,
+			OwnerID:           hafiz,
+			OwnerName:         "Hafiz",
+			Amount:            domain.Money{Amount: amount, Currency: "USD"},
+			WeightedAmount:    domain.Money{Amount: amount * int64(probability) / 100, Currency: "USD"},
+			Probability:       probability,
+			ExpectedCloseDate: &closeDate,
+		}
+		oppRepo.opportunities[opp.ID] = opp
+		return opp
+	}
+	addOpportunity(10000, 50)
+	split := addOpportunity(20000, 80)
+	splitRepo.splits[split.ID] = domain.RevenueSplits{
+		{UserID: hafiz, UserName: "Hafiz", Percent: 25},
+		{UserID: siti, UserName: "Siti", Percent: 75},
+	}
+
+	req := &dto.ForecastRequest{StartDate: "2026-01-01", EndDate: "2026-12-31", Currency: "USD"}
+
+	// Act
+	result, err := uc.GetForecast(context.Background(), tenantID, req)
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if len(result.ByOwner) != 2 {
+		t.Fatalf("Expected 2 owners, got %+v", result.ByOwner)
+	}
+	// Siti is credited 75% of the 16000 weighted, Hafiz 5000 plus 25%
+	sitiForecast, hafizForecast := result.ByOwner[0], result.ByOwner[1]
+	if sitiForecast.OwnerID != siti.String() || sitiForecast.WeightedRevenue.Amount != 12000 || sitiForecast.Committed.Amount != 12000 {
+		t.Errorf("Unexpected forecast for Siti: %+v", sitiForecast)
+	}
+	if hafizForecast.WeightedRevenue.Amount != 9000 || hafizForecast.ExpectedRevenue.Amount != 15000 || hafizForecast.OpportunityCount != 2 {
+		t.Errorf("Unexpected forecast for Hafiz: %+v", hafizForecast)
+	}
+	if sitiForecast.WeightedRevenue.Amount+hafizForecast.WeightedRevenue.Amount != result.TotalForecast.Amount {
+		t.Errorf("Owner forecasts do not add up to the total %d", result.TotalForecast.Amount)
+	}
+}
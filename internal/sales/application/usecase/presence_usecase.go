@@ -0,0 +1,313 @@
+package usecase
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/kilang-desa-murni/crm/internal/sales/application"
+	"github.com/kilang-desa-murni/crm/internal/sales/application/dto"
+	"github.com/kilang-desa-murni/crm/internal/sales/domain"
+)
+
+// presenceEventBuffer is the number of presence events queued for a
+// connection that has not read them yet.
+const presenceEventBuffer = 16
+
+// Presence event types
+const (
+	PresenceEventJoined      = "joined"
+	PresenceEventLeft        = "left"
+	PresenceEventModeChanged = "mode_changed"
+)
+
+// ============================================================================
+// Presence Use Case Interface
+// ============================================================================
+
+// PresenceUseCase defines the interface for tracking who is viewing or
+// editing a record, so collaborators see each other and the soft lock of
+// whoever is editing before they overwrite each other's changes. Presence
+// is held in memory by each instance of the service.
+type PresenceUseCase interface {
+	// Join adds a connection of the user to a record. The connection's
+	// first event is its own joined event; it leaves the record when the
+	// subscription is closed.
+	Join(ctx context.Context, tenantID, userID uuid.UUID, recordType string, recordID uuid.UUID) (*PresenceSubscription, error)
+
+	// SetMode switches a connection of the user between viewing and
+	// editing a record.
+	SetMode(ctx context.Context, tenantID, userID uuid.UUID, recordType string, recordID uuid.UUID, connectionID string, req *dto.SetPresenceModeRequest) (*dto.PresenceResponse, error)
+
+	// GetPresence returns who is present on a record.
+	GetPresence(ctx context.Context, tenantID uuid.UUID, recordType string, recordID uuid.UUID) (*dto.PresenceResponse, error)
+
+	// Close ends the subscription of every connection, so streams do not
+	// hold up the service shutting down.
+	Close()
+}
+
+// PresenceSubscription is a connection to a record, receiving the record's
+// presence events until it is closed.
+type PresenceSubscription struct {
+	ConnectionID string
+	Events       <-chan *dto.PresenceEvent
+
+	leave func()
+	once  sync.Once
+}
+
+// Close leaves the record. The events channel is closed once it has left.
+func (s *PresenceSubscription) Close() {
+	s.once.Do(s.leave)
+}
+
+// ============================================================================
+// Presence Use Case Implementation
+// ============================================================================
+
+// presenceRoom is a record's room together with the event channels of its
+// connections.
+type presenceRoom struct {
+	room        *domain.PresenceRoom
+	subscribers map[string]chan *dto.PresenceEvent
+}
+
+// presenceUseCase implements PresenceUseCase.
+type presenceUseCase struct {
+	leadRepo        domain.LeadRepository
+	opportunityRepo domain.OpportunityRepository
+	dealRepo        domain.DealRepository
+
+	mu    sync.Mutex
+	rooms map[domain.PresenceRecord]*presenceRoom
+
+	now func() time.Time
+}
+
+// NewPresenceUseCase creates a new presence use case.
+func NewPresenceUseCase(
+	leadRepo domain.LeadRepository,
+	opportunityRepo domain.OpportunityRepository,
+	dealRepo domain.DealRepository,
+) PresenceUseCase {
+	return &presenceUseCase{
+		leadRepo:        leadRepo,
+		opportunityRepo: opportunityRepo,
+		dealRepo:        dealRepo,
+		rooms:           make(map[domain.PresenceRecord]*presenceRoom),
+		now:             time.Now,
+	}
+}
+
+// Join adds a connection of the user to a record.
+func (uc *presenceUseCase) Join(ctx context.Context, tenantID, userID uuid.UUID, recordType string, recordID uuid.UUID) (*PresenceSubscription, error) {
+	record, err := uc.findRecord(ctx, tenantID, recordType, recordID)
+	if err != nil {
+		return nil, err
+	}
+
+	uc.mu.Lock()
+	defer uc.mu.Unlock()
+
+	room, ok := uc.rooms[record]
+	if !ok {
+		domainRoom, err := domain.NewPresenceRoom(record)
+		if err != nil {
+			return nil, err
+		}
+		room = &presenceRoom{room: domainRoom, subscribers: make(map[string]chan *dto.PresenceEvent)}
+	}
+
+	connectionID := uuid.NewString()
+	if err := room.room.Join(connectionID, userID, uc.now()); err != nil {
+		return nil, err
+	}
+	uc.rooms[record] = room
+
+	events := make(chan *dto.PresenceEvent, presenceEventBuffer)
+	room.subscribers[connectionID] = events
+	room.broadcast(PresenceEventJoined, connectionID, userID)
+
+	return &PresenceSubscription{
+		ConnectionID: connectionID,
+		Events:       events,
+		leave:        func() { uc.leave(record, connectionID) },
+	}, nil
+}
+
+// leave removes a connection from a record, telling the connections left.
+func (uc *presenceUseCase) leave(record domain.PresenceRecord, connectionID string) {
+	uc.mu.Lock()
+	defer uc.mu.Unlock()
+
+	room, ok := uc.rooms[record]
+	if !ok {
+		return
+	}
+	presence, ok := room.room.Connection(connectionID)
+	if !ok {
+		return
+	}
+	room.room.Leave(connectionID)
+	close(room.subscribers[connectionID])
+	delete(room.subscribers, connectionID)
+
+	if room.room.IsEmpty() {
+		delete(uc.rooms, record)
+		return
+	}
+	room.broadcast(PresenceEventLeft, connectionID, presence.UserID)
+}
+
+// SetMode switches a connection of the user between viewing and editing.
+// Another user's connection is reported as not found.
+func (uc *presenceUseCase) SetMode(ctx context.Context, tenantID, userID uuid.UUID, recordType string, recordID uuid.UUID, connectionID string, req *dto.SetPresenceModeRequest) (*dto.PresenceResponse, error) {
+	record, err := parsePresenceRecord(tenantID, recordType, recordID)
+	if err != nil {
+		return nil, err
+	}
+	mode := domain.PresenceMode(req.Mode)
+
+	uc.mu.Lock()
+	defer uc.mu.Unlock()
+
+	room, ok := uc.rooms[record]
+	if !ok {
+		return nil, domain.ErrPresenceNotFound
+	}
+	presence, ok := room.room.Connection(connectionID)
+	if !ok || presence.UserID != userID {
+		return nil, domain.ErrPresenceNotFound
+	}
+	if err := room.room.SetMode(connectionID, mode, uc.now()); err != nil {
+		return nil, err
+	}
+	if presence.Mode != mode {
+		room.broadcast(PresenceEventModeChanged, connectionID, userID)
+	}
+	return toPresenceResponse(room.room), nil
+}
+
+// GetPresence returns who is present on a record.
+func (uc *presenceUseCase) GetPresence(ctx context.Context, tenantID uuid.UUID, recordType string, recordID uuid.UUID) (*dto.PresenceResponse, error) {
+	record, err := uc.findRecord(ctx, tenantID, recordType, recordID)
+	if err != nil {
+		return nil, err
+	}
+
+	uc.mu.Lock()
+	defer uc.mu.Unlock()
+
+	if room, ok := uc.rooms[record]; ok {
+		return toPresenceResponse(room.room), nil
+	}
+	empty, err := domain.NewPresenceRoom(record)
+	if err != nil {
+		return nil, err
+	}
+	return toPresenceResponse(empty), nil
+}
+
+// Close ends the subscription of every connection by closing its events.
+func (uc *presenceUseCase) Close() {
+	uc.mu.Lock()
+	defer uc.mu.Unlock()
+
+	for record, room := range uc.rooms {
+		for _, events := range room.subscribers {
+			close(events)
+		}
+		delete(uc.rooms, record)
+	}
+}
+
+// findRecord checks the tenant has the record collaborators are joining.
+func (uc *presenceUseCase) findRecord(ctx context.Context, tenantID uuid.UUID, recordType string, recordID uuid.UUID) (domain.PresenceRecord, error) {
+	record, err := parsePresenceRecord(tenantID, recordType, recordID)
+	if err != nil {
+		return record, err
+	}
+
+	switch record.Type {
+	case domain.PresenceRecordLead:
+		if _, err := uc.leadRepo.GetByID(ctx, tenantID, recordID); err != nil {
+			return record, application.ErrLeadNotFound(recordID)
+		}
+	case domain.PresenceRecordOpportunity:
+		if _, err := uc.opportunityRepo.GetByID(ctx, tenantID, recordID); err != nil {
+			return record, application.ErrOpportunityNotFound(recordID)
+		}
+	case domain.PresenceRecordDeal:
+		if _, err := uc.dealRepo.GetByID(ctx, tenantID, recordID); err != nil {
+			return record, application.ErrDealNotFound(recordID)
+		}
+	}
+	return record, nil
+}
+
+// parsePresenceRecord identifies a record of the tenant.
+func parsePresenceRecord(tenantID uuid.UUID, recordType string, recordID uuid.UUID) (domain.PresenceRecord, error) {
+	record := domain.PresenceRecord{TenantID: tenantID, Type: domain.PresenceRecordType(recordType), ID: recordID}
+	if !record.Type.IsValid() {
+		return record, domain.ErrInvalidPresenceRecordType
+	}
+	return record, nil
+}
+
+// broadcast sends an event with the room's presence to each of its
+// connections. A connection too slow to keep up loses its oldest event
+// rather than holding up the others; as every event carries the whole
+// presence, it only misses intermediate states.
+func (r *presenceRoom) broadcast(eventType, connectionID string, userID uuid.UUID) {
+	event := &dto.PresenceEvent{
+		Type:         eventType,
+		ConnectionID: connectionID,
+		UserID:       userID.String(),
+		Presence:     toPresenceResponse(r.room),
+	}
+	for _, events := range r.subscribers {
+		select {
+		case events <- event:
+			continue
+		default:
+		}
+		select {
+		case <-events:
+		default:
+		}
+		select {
+		case events <- event:
+		default:
+		}
+	}
+}
+
+// toPresenceResponse maps a room to its response.
+func toPresenceResponse(room *domain.PresenceRoom) *dto.PresenceResponse {
+	collaborators := room.Collaborators()
+	resp := &dto.PresenceResponse{
+		RecordType:    string(room.Record.Type),
+		RecordID:      room.Record.ID.String(),
+		Collaborators: make([]*dto.CollaboratorResponse, len(collaborators)),
+	}
+	for i, presence := range collaborators {
+		resp.Collaborators[i] = &dto.CollaboratorResponse{
+			ConnectionID: presence.ConnectionID,
+			UserID:       presence.UserID.String(),
+			Mode:         string(presence.Mode),
+			JoinedAt:     presence.JoinedAt,
+			EditingSince: presence.EditingSince,
+		}
+	}
+	if lock := room.Lock(); lock != nil {
+		resp.Lock = &dto.PresenceLockResponse{
+			UserID:       lock.UserID.String(),
+			ConnectionID: lock.ConnectionID,
+			Since:        lock.Since,
+		}
+	}
+	return resp
+}
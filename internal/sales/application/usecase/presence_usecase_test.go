@@ -0,0 +1,172 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"github.com/kilang-desa-murni/crm/internal/sales/application"
+	"github.com/kilang-desa-murni/crm/internal/sales/application/dto"
+	"github.com/kilang-desa-murni/crm/internal/sales/domain"
+)
+
+// ============================================================================
+// Presence Use Case Tests
+// ============================================================================
+
+func newTestPresenceUseCase(tenantID uuid.UUID) (PresenceUseCase, uuid.UUID) {
+	opportunityRepo := NewMockOpportunityRepository()
+	opportunity := &domain.Opportunity{ID: uuid.New(), TenantID: tenantID}
+	opportunityRepo.opportunities[opportunity.ID] = opportunity
+	return NewPresenceUseCase(NewMockLeadRepository(), opportunityRepo, NewMockDealRepository()), opportunity.ID
+}
+
+// nextPresenceEvent reads the next event of a subscription.
+func nextPresenceEvent(t *testing.T, sub *PresenceSubscription) *dto.PresenceEvent {
+	t.Helper()
+
+	select {
+	case event := <-sub.Events:
+		return event
+	default:
+		t.Fatal("no presence event")
+		return nil
+	}
+}
+
+func TestPresenceUseCase_Join(t *testing.T) {
+	ctx := context.Background()
+	tenantID := uuid.New()
+	uc, opportunityID := newTestPresenceUseCase(tenantID)
+	alice, bob := uuid.New(), uuid.New()
+
+	first, err := uc.Join(ctx, tenantID, alice, "opportunity", opportunityID)
+	if err != nil {
+		t.Fatalf("Join() error = %v", err)
+	}
+	defer first.Close()
+
+	event := nextPresenceEvent(t, first)
+	if event.Type != PresenceEventJoined || event.ConnectionID != first.ConnectionID {
+		t.Errorf("first event = %+v, want own joined event", event)
+	}
+
+	second, err := uc.Join(ctx, tenantID, bob, "opportunity", opportunityID)
+	if err != nil {
+		t.Fatalf("Join() error = %v", err)
+	}
+
+	event = nextPresenceEvent(t, first)
+	if event.Type != PresenceEventJoined || event.UserID != bob.String() || len(event.Presence.Collaborators) != 2 {
+		t.Errorf("event = %+v, want bob joined", event)
+	}
+
+	// Leaving releases the connection and tells the others; closing twice
+	// is harmless
+	second.Close()
+	second.Close()
+	<-second.Events // bob's own joined event
+	if _, ok := <-second.Events; ok {
+		t.Error("events of a closed subscription are still open")
+	}
+	event = nextPresenceEvent(t, first)
+	if event.Type != PresenceEventLeft || len(event.Presence.Collaborators) != 1 {
+		t.Errorf("event = %+v, want bob left", event)
+	}
+}
+
+func TestPresenceUseCase_Join_RecordNotFound(t *testing.T) {
+	tenantID := uuid.New()
+	uc, _ := newTestPresenceUseCase(tenantID)
+
+	_, err := uc.Join(context.Background(), tenantID, uuid.New(), "opportunity", uuid.New())
+	if appErr := application.GetAppError(err); appErr == nil || appErr.Code != application.ErrCodeOpportunityNotFound {
+		t.Errorf("Join() error = %v, want opportunity not found", err)
+	}
+
+	_, err = uc.Join(context.Background(), tenantID, uuid.New(), "customer", uuid.New())
+	if err != domain.ErrInvalidPresenceRecordType {
+		t.Errorf("Join() error = %v, want %v", err, domain.ErrInvalidPresenceRecordType)
+	}
+}
+
+func TestPresenceUseCase_SetMode(t *testing.T) {
+	ctx := context.Background()
+	tenantID := uuid.New()
+	uc, opportunityID := newTestPresenceUseCase(tenantID)
+	alice, bob := uuid.New(), uuid.New()
+
+	aliceSub, _ := uc.Join(ctx, tenantID, alice, "opportunity", opportunityID)
+	defer aliceSub.Close()
+	bobSub, _ := uc.Join(ctx, tenantID, bob, "opportunity", opportunityID)
+	nextPresenceEvent(t, aliceSub)
+	nextPresenceEvent(t, aliceSub)
+	nextPresenceEvent(t, bobSub)
+
+	// Only the user of a connection can switch it
+	_, err := uc.SetMode(ctx, tenantID, alice, "opportunity", opportunityID, bobSub.ConnectionID, &dto.SetPresenceModeRequest{Mode: "editing"})
+	if err != domain.ErrPresenceNotFound {
+		t.Errorf("SetMode() error = %v, want %v", err, domain.ErrPresenceNotFound)
+	}
+
+	presence, err := uc.SetMode(ctx, tenantID, bob, "opportunity", opportunityID, bobSub.ConnectionID, &dto.SetPresenceModeRequest{Mode: "editing"})
+	if err != nil {
+		t.Fatalf("SetMode() error = %v", err)
+	}
+	if presence.Lock == nil || presence.Lock.UserID != bob.String() {
+		t.Fatalf("Lock = %+v, want held by bob", presence.Lock)
+	}
+
+	event := nextPresenceEvent(t, aliceSub)
+	if event.Type != PresenceEventModeChanged || event.Presence.Lock == nil || event.Presence.Lock.UserID != bob.String() {
+		t.Errorf("event = %+v, want bob's lock hint", event)
+	}
+
+	// Disconnecting releases the lock
+	bobSub.Close()
+	event = nextPresenceEvent(t, aliceSub)
+	if event.Type != PresenceEventLeft || event.Presence.Lock != nil {
+		t.Errorf("event = %+v, want the lock released", event)
+	}
+
+	presence, err = uc.GetPresence(ctx, tenantID, "opportunity", opportunityID)
+	if err != nil {
+		t.Fatalf("GetPresence() error = %v", err)
+	}
+	if len(presence.Collaborators) != 1 || presence.Lock != nil {
+		t.Errorf("GetPresence() = %+v, want alice alone", presence)
+	}
+}
+
+func TestPresenceUseCase_SlowConnectionKeepsLatest(t *testing.T) {
+	ctx := context.Background()
+	tenantID := uuid.New()
+	uc, opportunityID := newTestPresenceUseCase(tenantID)
+	alice := uuid.New()
+
+	slow, _ := uc.Join(ctx, tenantID, uuid.New(), "opportunity", opportunityID)
+	defer slow.Close()
+	sub, _ := uc.Join(ctx, tenantID, alice, "opportunity", opportunityID)
+	defer sub.Close()
+
+	mode := "viewing"
+	for i := 0; i < presenceEventBuffer*2; i++ {
+		if mode == "viewing" {
+			mode = "editing"
+		} else {
+			mode = "viewing"
+		}
+		if _, err := uc.SetMode(ctx, tenantID, alice, "opportunity", opportunityID, sub.ConnectionID, &dto.SetPresenceModeRequest{Mode: mode}); err != nil {
+			t.Fatalf("SetMode() error = %v", err)
+		}
+	}
+
+	var last *dto.PresenceEvent
+	for len(slow.Events) > 0 {
+		last = <-slow.Events
+	}
+	if last == nil || last.Presence.Collaborators[1].Mode != mode {
+		t.Errorf("last event = %+v, want the latest mode %s", last, mode)
+	}
+}
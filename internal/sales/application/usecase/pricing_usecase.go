@@ -0,0 +1,147 @@
+package usecase
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/kilang-desa-murni/crm/internal/sales/application"
+	"github.com/kilang-desa-murni/crm/internal/sales/application/dto"
+	"github.com/kilang-desa-murni/crm/internal/sales/application/ports"
+	"github.com/kilang-desa-murni/crm/internal/sales/domain"
+)
+
+// ============================================================================
+// Pricing Use Case Interface
+// ============================================================================
+
+// PricingUseCase defines the interface for the price lists products are
+// priced from.
+type PricingUseCase interface {
+	ports.PriceListResolver
+
+	GetPriceLists(ctx context.Context, tenantID uuid.UUID) (*dto.PriceListsResponse, error)
+	UpdatePriceLists(ctx context.Context, tenantID uuid.UUID, req *dto.UpdatePriceListsRequest) (*dto.PriceListsResponse, error)
+}
+
+// ============================================================================
+// Pricing Use Case Implementation
+// ============================================================================
+
+// pricingUseCase implements PricingUseCase.
+type pricingUseCase struct {
+	pricingRepo domain.PricingSettingsRepository
+}
+
+// NewPricingUseCase creates a new pricing use case.
+func NewPricingUseCase(pricingRepo domain.PricingSettingsRepository) PricingUseCase {
+	return &pricingUseCase{pricingRepo: pricingRepo}
+}
+
+// GetPriceLists returns the tenant's price lists.
+func (uc *pricingUseCase) GetPriceLists(ctx context.Context, tenantID uuid.UUID) (*dto.PriceListsResponse, error) {
+	settings, err := uc.loadSettings(ctx, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	return mapPricingSettingsToResponse(settings), nil
+}
+
+// UpdatePriceLists replaces the tenant's price lists.
+func (uc *pricingUseCase) UpdatePriceLists(ctx context.Context, tenantID uuid.UUID, req *dto.UpdatePriceListsRequest) (*dto.PriceListsResponse, error) {
+	settings := domain.DefaultPricingSettings(tenantID)
+	for _, list := range req.PriceLists {
+		priceList := domain.PriceList{
+			Name:            strings.TrimSpace(list.Name),
+			CustomerTier:    strings.ToLower(strings.TrimSpace(list.CustomerTier)),
+			Currency:        strings.ToUpper(strings.TrimSpace(list.Currency)),
+			DiscountPercent: list.DiscountPercent,
+			Prices:          make([]domain.ProductPrice, 0, len(list.Prices)),
+		}
+		for _, price := range list.Prices {
+			productID, err := uuid.Parse(price.ProductID)
+			if err != nil {
+				return nil, application.ErrValidation("invalid product_id format")
+			}
+			priceList.Prices = append(priceList.Prices, domain.ProductPrice{ProductID: productID, UnitPrice: price.UnitPrice})
+		}
+		settings.PriceLists = append(settings.PriceLists, priceList)
+	}
+
+	if err := settings.Validate(); err != nil {
+		return nil, application.ErrValidation(err.Error())
+	}
+
+	settings.UpdatedAt = time.Now().UTC()
+	if err := uc.pricingRepo.Upsert(ctx, settings); err != nil {
+		return nil, application.WrapError(application.ErrCodeInternal, "failed to save price lists", err)
+	}
+
+	return mapPricingSettingsToResponse(settings), nil
+}
+
+// ResolvePrice returns the unit price of a product on the price list of a
+// customer tier in currency, or nil when there is no such list or it does not
+// price the product.
+func (uc *pricingUseCase) ResolvePrice(ctx context.Context, tenantID uuid.UUID, tier string, productID uuid.UUID, currency string, product *ports.ProductInfo) (*ports.AppliedPrice, error) {
+	settings, err := uc.loadSettings(ctx, tenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	list := settings.FindPriceList(tier, currency)
+	if list == nil {
+		return nil, nil
+	}
+
+	var listPrice *domain.Money
+	if product != nil {
+		listPrice = &domain.Money{Amount: product.UnitPrice, Currency: strings.ToUpper(product.Currency)}
+	}
+	price, ok := list.PriceFor(productID, listPrice)
+	if !ok {
+		return nil, nil
+	}
+
+	return &ports.AppliedPrice{
+		PriceList: list.Name,
+		UnitPrice: price.Amount,
+		Currency:  price.Currency,
+	}, nil
+}
+
+// loadSettings returns the stored settings, or the defaults when none exist.
+func (uc *pricingUseCase) loadSettings(ctx context.Context, tenantID uuid.UUID) (*domain.PricingSettings, error) {
+	settings, err := uc.pricingRepo.Get(ctx, tenantID)
+	if err != nil {
+		return nil, application.WrapError(application.ErrCodeInternal, "failed to get price lists", err)
+	}
+	if settings == nil {
+		return domain.DefaultPricingSettings(tenantID), nil
+	}
+	return settings, nil
+}
+
+func mapPricingSettingsToResponse(settings *domain.PricingSettings) *dto.PriceListsResponse {
+	resp := &dto.PriceListsResponse{PriceLists: make([]dto.PriceListDTO, len(settings.PriceLists))}
+	for i, list := range settings.PriceLists {
+		prices := make([]dto.ProductPriceDTO, len(list.Prices))
+		for j, price := range list.Prices {
+			prices[j] = dto.ProductPriceDTO{ProductID: price.ProductID.String(), UnitPrice: price.UnitPrice}
+		}
+		resp.PriceLists[i] = dto.PriceListDTO{
+			Name:            list.Name,
+			CustomerTier:    list.CustomerTier,
+			Currency:        list.Currency,
+			DiscountPercent: list.DiscountPercent,
+			Prices:          prices,
+		}
+	}
+	if !settings.UpdatedAt.IsZero() {
+		updatedAt := settings.UpdatedAt
+		resp.UpdatedAt = &updatedAt
+	}
+	return resp
+}
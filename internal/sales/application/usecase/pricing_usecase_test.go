@@ -0,0 +1,106 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"github.com/kilang-desa-murni/crm/internal/sales/application"
+	"github.com/kilang-desa-murni/crm/internal/sales/application/dto"
+	"github.com/kilang-desa-murni/crm/internal/sales/application/ports"
+	"github.com/kilang-desa-murni/crm/internal/sales/domain"
+)
+
+// ============================================================================
+// Mock Implementations for Pricing Tests
+// ============================================================================
+
+// MockPricingSettingsRepository is a mock implementation of domain.PricingSettingsRepository.
+type MockPricingSettingsRepository struct {
+	settings map[uuid.UUID]*domain.PricingSettings
+}
+
+func NewMockPricingSettingsRepository() *MockPricingSettingsRepository {
+	return &MockPricingSettingsRepository{settings: make(map[uuid.UUID]*domain.PricingSettings)}
+}
+
+func (m *MockPricingSettingsRepository) Get(ctx context.Context, tenantID uuid.UUID) (*domain.PricingSettings, error) {
+	return m.settings[tenantID], nil
+}
+
+func (m *MockPricingSettingsRepository) Upsert(ctx context.Context, settings *domain.PricingSettings) error {
+	m.settings[settings.TenantID] = settings
+	return nil
+}
+
+// ============================================================================
+// Pricing Use Case Tests
+// ============================================================================
+
+func TestPricingUseCase_UpdatePriceLists(t *testing.T) {
+	uc := NewPricingUseCase(NewMockPricingSettingsRepository())
+	tenantID := uuid.New()
+	productID := uuid.New()
+
+	resp, err := uc.GetPriceLists(context.Background(), tenantID)
+	if err != nil || len(resp.PriceLists) != 0 {
+		t.Fatalf("GetPriceLists() = %+v, %v, want no price lists", resp, err)
+	}
+
+	resp, err = uc.UpdatePriceLists(context.Background(), tenantID, &dto.UpdatePriceListsRequest{
+		PriceLists: []dto.PriceListDTO{{
+			Name:            "Gold",
+			CustomerTier:    " Gold ",
+			Currency:        "myr",
+			DiscountPercent: 10,
+			Prices:          []dto.ProductPriceDTO{{ProductID: productID.String(), UnitPrice: 8000}},
+		}},
+	})
+	if err != nil {
+		t.Fatalf("UpdatePriceLists() error = %v", err)
+	}
+	if len(resp.PriceLists) != 1 || resp.PriceLists[0].CustomerTier != "gold" || resp.PriceLists[0].Currency != "MYR" || resp.UpdatedAt == nil {
+		t.Errorf("UpdatePriceLists() = %+v, want the normalized gold list", resp)
+	}
+
+	_, err = uc.UpdatePriceLists(context.Background(), tenantID, &dto.UpdatePriceListsRequest{
+		PriceLists: []dto.PriceListDTO{{Name: "Gold", CustomerTier: "gold", Currency: "MYR"}, {Name: "Gold 2", CustomerTier: "GOLD", Currency: "MYR"}},
+	})
+	var appErr *application.AppError
+	if !errors.As(err, &appErr) || appErr.Code != application.ErrCodeValidation {
+		t.Errorf("UpdatePriceLists() with two gold lists error = %v, want validation error", err)
+	}
+}
+
+func TestPricingUseCase_ResolvePrice(t *testing.T) {
+	repo := NewMockPricingSettingsRepository()
+	uc := NewPricingUseCase(repo)
+	tenantID := uuid.New()
+	priced, unpriced := uuid.New(), uuid.New()
+	repo.settings[tenantID] = &domain.PricingSettings{TenantID: tenantID, PriceLists: []domain.PriceList{{
+		Name:            "Gold",
+		CustomerTier:    "gold",
+		Currency:        "MYR",
+		DiscountPercent: 20,
+		Prices:          []domain.ProductPrice{{ProductID: priced, UnitPrice: 8000}},
+	}}}
+
+	price, err := uc.ResolvePrice(context.Background(), tenantID, "gold", priced, "MYR", nil)
+	if err != nil || price == nil || price.UnitPrice != 8000 || price.PriceList != "Gold" {
+		t.Errorf("ResolvePrice(priced) = %+v, %v, want MYR 80.00 from Gold", price, err)
+	}
+
+	catalogue := &ports.ProductInfo{ID: unpriced, UnitPrice: 5000, Currency: "myr"}
+	price, err = uc.ResolvePrice(context.Background(), tenantID, "gold", unpriced, "MYR", catalogue)
+	if err != nil || price == nil || price.UnitPrice != 4000 {
+		t.Errorf("ResolvePrice(unpriced) = %+v, %v, want the catalogue price less 20%%", price, err)
+	}
+
+	for _, tier := range []string{"silver", ""} {
+		if price, err := uc.ResolvePrice(context.Background(), tenantID, tier, priced, "MYR", nil); err != nil || price != nil {
+			t.Errorf("ResolvePrice(%q) = %+v, %v, want no price", tier, price, err)
+		}
+	}
+}
@@ -0,0 +1,210 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/google/uuid"
+
+	"github.com/kilang-desa-murni/crm/internal/sales/application"
+	"github.com/kilang-desa-murni/crm/internal/sales/application/dto"
+	"github.com/kilang-desa-murni/crm/internal/sales/application/ports"
+	"github.com/kilang-desa-murni/crm/internal/sales/domain"
+)
+
+// reassignPageSize is the number of a user's records read per query when
+// collecting the records to reassign.
+const reassignPageSize = 100
+
+// ============================================================================
+// Owner Reassignment Use Case Interface
+// ============================================================================
+
+// OwnerReassignmentUseCase defines the interface for transferring the open
+// records of a user to other users. The IAM service calls it when a user
+// leaves, so their leads and opportunities are not orphaned.
+type OwnerReassignmentUseCase interface {
+	// ReassignLeads transfers the user's open leads to the recipients.
+	ReassignLeads(ctx context.Context, tenantID uuid.UUID, req *dto.ReassignRecordsRequest) (*dto.ReassignRecordsResponse, error)
+
+	// ReassignOpportunities transfers the user's open opportunities to the
+	// recipients.
+	ReassignOpportunities(ctx context.Context, tenantID uuid.UUID, req *dto.ReassignRecordsRequest) (*dto.ReassignRecordsResponse, error)
+}
+
+// ============================================================================
+// Owner Reassignment Use Case Implementation
+// ============================================================================
+
+// ownerReassignmentUseCase implements OwnerReassignmentUseCase.
+type ownerReassignmentUseCase struct {
+	leadRepo        domain.LeadRepository
+	opportunityRepo domain.OpportunityRepository
+	eventPublisher  ports.EventPublisher
+}
+
+// NewOwnerReassignmentUseCase creates a new owner reassignment use case.
+func NewOwnerReassignmentUseCase(
+	leadRepo domain.LeadRepository,
+	opportunityRepo domain.OpportunityRepository,
+	eventPublisher ports.EventPublisher,
+) OwnerReassignmentUseCase {
+	return &ownerReassignmentUseCase{
+		leadRepo:        leadRepo,
+		opportunityRepo: opportunityRepo,
+		eventPublisher:  eventPublisher,
+	}
+}
+
+// reassignRecipient is a user receiving reassigned records.
+type reassignRecipient struct {
+	id   uuid.UUID
+	name string
+}
+
+// ReassignLeads transfers the user's open leads to the recipients in turn.
+// A lead that fails to save is counted as failed and keeps its owner.
+func (uc *ownerReassignmentUseCase) ReassignLeads(ctx context.Context, tenantID uuid.UUID, req *dto.ReassignRecordsRequest) (*dto.ReassignRecordsResponse, error) {
+	fromUserID, recipients, err := parseReassignRequest(req)
+	if err != nil {
+		return nil, err
+	}
+
+	// Collect the leads before reassigning any, as each one reassigned
+	// leaves the user's pages
+	var leads []*domain.Lead
+	for page := 1; ; page++ {
+		batch, total, err := uc.leadRepo.GetByOwner(ctx, tenantID, fromUserID, domain.ListOptions{
+			Page:      page,
+			PageSize:  reassignPageSize,
+			SortBy:    "created_at",
+			SortOrder: "asc",
+		})
+		if err != nil {
+			return nil, application.WrapError(application.ErrCodeInternal, "failed to find leads", err)
+		}
+		for _, lead := range batch {
+			if lead.IsOpen() && lead.OwnerID != nil && *lead.OwnerID == fromUserID {
+				leads = append(leads, lead)
+			}
+		}
+		if len(batch) < reassignPageSize || int64(page*reassignPageSize) >= total {
+			break
+		}
+	}
+
+	result := &dto.ReassignRecordsResponse{Assigned: make(map[string]int64)}
+	for _, lead := range leads {
+		recipient := recipients[result.Transferred%int64(len(recipients))]
+		lead.AssignOwner(recipient.id, recipient.name)
+		if err := uc.leadRepo.Update(ctx, lead); err != nil {
+			result.Failed++
+			continue
+		}
+		result.Transferred++
+		result.Assigned[recipient.id.String()]++
+
+		uc.publishEvents(ctx, lead.GetEvents())
+		lead.ClearEvents()
+	}
+
+	return result, nil
+}
+
+// ReassignOpportunities transfers the user's open opportunities to the
+// recipients in turn. An opportunity that fails to save is counted as failed
+// and keeps its owner.
+func (uc *ownerReassignmentUseCase) ReassignOpportunities(ctx context.Context, tenantID uuid.UUID, req *dto.ReassignRecordsRequest) (*dto.ReassignRecordsResponse, error) {
+	fromUserID, recipients, err := parseReassignRequest(req)
+	if err != nil {
+		return nil, err
+	}
+
+	var opportunities []*domain.Opportunity
+	for page := 1; ; page++ {
+		batch, total, err := uc.opportunityRepo.GetByOwner(ctx, tenantID, fromUserID, domain.ListOptions{
+			Page:      page,
+			PageSize:  reassignPageSize,
+			SortBy:    "created_at",
+			SortOrder: "asc",
+		})
+		if err != nil {
+			return nil, application.WrapError(application.ErrCodeInternal, "failed to find opportunities", err)
+		}
+		for _, opportunity := range batch {
+			if opportunity.IsOpen() && !opportunity.IsDeleted() && opportunity.OwnerID == fromUserID {
+				opportunities = append(opportunities, opportunity)
+			}
+		}
+		if len(batch) < reassignPageSize || int64(page*reassignPageSize) >= total {
+			break
+		}
+	}
+
+	result := &dto.ReassignRecordsResponse{Assigned: make(map[string]int64)}
+	for _, opportunity := range opportunities {
+		recipient := recipients[result.Transferred%int64(len(recipients))]
+		opportunity.AssignOwner(recipient.id, recipient.name)
+		if err := uc.opportunityRepo.Update(ctx, opportunity); err != nil {
+			result.Failed++
+			continue
+		}
+		result.Transferred++
+		result.Assigned[recipient.id.String()]++
+
+		uc.publishEvents(ctx, opportunity.GetEvents())
+		opportunity.ClearEvents()
+	}
+
+	return result, nil
+}
+
+// parseReassignRequest parses the user whose records are reassigned and the
+// recipients, who must not include the user.
+func parseReassignRequest(req *dto.ReassignRecordsRequest) (uuid.UUID, []reassignRecipient, error) {
+	fromUserID, err := uuid.Parse(req.FromUserID)
+	if err != nil {
+		return uuid.Nil, nil, application.ErrValidation("invalid from_user_id format")
+	}
+	if len(req.Recipients) == 0 {
+		return uuid.Nil, nil, application.ErrValidation("at least one recipient is required")
+	}
+
+	recipients := make([]reassignRecipient, 0, len(req.Recipients))
+	for _, r := range req.Recipients {
+		id, err := uuid.Parse(r.UserID)
+		if err != nil {
+			return uuid.Nil, nil, application.ErrValidation("invalid recipient user_id format")
+		}
+		if id == fromUserID {
+			return uuid.Nil, nil, application.ErrValidation("records cannot be reassigned to their current owner")
+		}
+		recipients = append(recipients, reassignRecipient{id: id, name: r.Name})
+	}
+
+	return fromUserID, recipients, nil
+}
+
+func (uc *ownerReassignmentUseCase) publishEvents(ctx context.Context, events []domain.DomainEvent) {
+	if uc.eventPublisher == nil {
+		return
+	}
+
+	for _, event := range events {
+		var payload map[string]interface{}
+		if data, err := json.Marshal(event); err == nil {
+			json.Unmarshal(data, &payload)
+		}
+
+		uc.eventPublisher.Publish(ctx, ports.Event{
+			ID:            event.EventID().String(),
+			Type:          event.EventType(),
+			AggregateID:   event.AggregateID().String(),
+			AggregateType: event.AggregateType(),
+			TenantID:      event.TenantID().String(),
+			Payload:       payload,
+			OccurredAt:    event.OccurredAt(),
+			Version:       event.Version(),
+		})
+	}
+}
@@ -0,0 +1,120 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"github.com/kilang-desa-murni/crm/internal/sales/application/dto"
+	"github.com/kilang-desa-murni/crm/internal/sales/domain"
+)
+
+// ============================================================================
+// Owner Reassignment Use Case Tests
+// ============================================================================
+
+func newTestReassignRequest(from uuid.UUID, recipients ...uuid.UUID) *dto.ReassignRecordsRequest {
+	req := &dto.ReassignRecordsRequest{FromUserID: from.String()}
+	for i, id := range recipients {
+		req.Recipients = append(req.Recipients, dto.ReassignRecipientDTO{
+			UserID: id.String(),
+			Name:   []string{"Aminah", "Badrul", "Chong"}[i%3],
+		})
+	}
+	return req
+}
+
+func TestOwnerReassignmentUseCase_ReassignLeads(t *testing.T) {
+	leadRepo := NewMockLeadRepository()
+	publisher := NewMockSalesEventPublisher()
+	uc := NewOwnerReassignmentUseCase(leadRepo, NewMockOpportunityRepository(), publisher)
+
+	tenantID := uuid.New()
+	leaver, first, second := uuid.New(), uuid.New(), uuid.New()
+
+	for i := 0; i < 5; i++ {
+		lead := createTestLead(tenantID)
+		lead.AssignOwner(leaver, "Leaver")
+		lead.ClearEvents()
+		leadRepo.leads[lead.ID] = lead
+	}
+	converted := createTestLead(tenantID)
+	converted.AssignOwner(leaver, "Leaver")
+	converted.Status = domain.LeadStatusConverted
+	leadRepo.leads[converted.ID] = converted
+
+	result, err := uc.ReassignLeads(context.Background(), tenantID, newTestReassignRequest(leaver, first, second))
+	if err != nil {
+		t.Fatalf("ReassignLeads() error = %v", err)
+	}
+
+	if result.Transferred != 5 || result.Failed != 0 {
+		t.Errorf("transferred %d (%d failed), want 5", result.Transferred, result.Failed)
+	}
+	if result.Assigned[first.String()] != 3 || result.Assigned[second.String()] != 2 {
+		t.Errorf("assigned = %v, want 3 and 2 in turn", result.Assigned)
+	}
+	if *converted.OwnerID != leaver {
+		t.Error("a converted lead was reassigned")
+	}
+	for _, lead := range leadRepo.leads {
+		if lead.ID != converted.ID && *lead.OwnerID == leaver {
+			t.Errorf("lead %s still belongs to the leaver", lead.ID)
+		}
+	}
+	if len(publisher.events) != 5 {
+		t.Errorf("published %d events, want one per reassigned lead", len(publisher.events))
+	}
+}
+
+func TestOwnerReassignmentUseCase_ReassignOpportunities(t *testing.T) {
+	oppRepo := NewMockOpportunityRepository()
+	uc := NewOwnerReassignmentUseCase(NewMockLeadRepository(), oppRepo, nil)
+
+	tenantID := uuid.New()
+	pipeline := createTestPipeline(tenantID)
+	leaver, recipient := uuid.New(), uuid.New()
+
+	open := createTestOpportunityWithPipeline(tenantID, pipeline)
+	open.OwnerID = leaver
+	oppRepo.opportunities[open.ID] = open
+	won := createTestOpportunityWithPipeline(tenantID, pipeline)
+	won.OwnerID = leaver
+	won.Status = domain.OpportunityStatusWon
+	oppRepo.opportunities[won.ID] = won
+
+	result, err := uc.ReassignOpportunities(context.Background(), tenantID, newTestReassignRequest(leaver, recipient))
+	if err != nil {
+		t.Fatalf("ReassignOpportunities() error = %v", err)
+	}
+
+	if result.Transferred != 1 || open.OwnerID != recipient || open.OwnerName != "Aminah" {
+		t.Errorf("transferred %d, open opportunity owned by %s (%s), want it reassigned", result.Transferred, open.OwnerID, open.OwnerName)
+	}
+	if won.OwnerID != leaver {
+		t.Error("a won opportunity was reassigned")
+	}
+}
+
+func TestOwnerReassignmentUseCase_InvalidRequest(t *testing.T) {
+	uc := NewOwnerReassignmentUseCase(NewMockLeadRepository(), NewMockOpportunityRepository(), nil)
+	leaver := uuid.New()
+
+	tests := []struct {
+		name string
+		req  *dto.ReassignRecordsRequest
+	}{
+		{"no recipients", newTestReassignRequest(leaver)},
+		{"recipient is the owner", newTestReassignRequest(leaver, leaver)},
+		{"invalid owner", &dto.ReassignRecordsRequest{FromUserID: "nobody", Recipients: []dto.ReassignRecipientDTO{{UserID: uuid.NewString()}}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := uc.ReassignLeads(context.Background(), uuid.New(), tt.req); err == nil {
+				t.Error("ReassignLeads() error = nil, want a validation error")
+			}
+		})
+	}
+}
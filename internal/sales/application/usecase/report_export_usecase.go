@@ -0,0 +1,679 @@
+package usecase
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/kilang-desa-murni/crm/internal/sales/application"
+	"github.com/kilang-desa-murni/crm/internal/sales/application/dto"
+	"github.com/kilang-desa-murni/crm/internal/sales/application/ports"
+	"github.com/kilang-desa-murni/crm/internal/sales/domain"
+)
+
+// reportExportTimeout bounds the generation of a single asynchronous export.
+const reportExportTimeout = 5 * time.Minute
+
+// maxReportLogoSize is the largest logo accepted for report branding.
+const maxReportLogoSize = 512 * 1024
+
+// recentExportsLimit is the number of exports returned when listing a user's exports.
+const recentExportsLimit = 20
+
+// ============================================================================
+// Exports
+// ============================================================================
+
+// ExportSalesPerformance renders a sales performance report directly to w.
+// Nothing is written to w if the report cannot be built.
+func (uc *reportUseCase) ExportSalesPerformance(ctx context.Context, tenantID uuid.UUID, req *dto.SalesPerformanceRequest, format string, w io.Writer) error {
+	renderer, err := uc.rendererFor(format)
+	if err != nil {
+		return err
+	}
+
+	doc, err := uc.buildSalesPerformanceDocument(ctx, tenantID, req)
+	if err != nil {
+		return err
+	}
+
+	if err := renderer.Render(ctx, w, doc); err != nil {
+		return application.WrapError(application.ErrCodeInternal, "failed to render report", err)
+	}
+	return nil
+}
+
+// RequestExport queues an asynchronous report export. The requester is notified
+// with a download link once the file is ready.
+func (uc *reportUseCase) RequestExport(ctx context.Context, tenantID, userID uuid.UUID, req *dto.CreateReportExportRequest) (*dto.ReportExportResponse, error) {
+	if uc.exportRepo == nil || uc.fileStorage == nil {
+		return nil, application.ErrServiceUnavailable("report export storage")
+	}
+	if _, err := uc.rendererFor(req.Format); err != nil {
+		return nil, err
+	}
+	if !domain.ReportType(req.Report).IsValid() {
+		return nil, application.ErrValidationWithDetails("invalid report", map[string]interface{}{
+			"report": req.Report,
+		})
+	}
+	if _, _, _, err := resolveSalesPerformanceQuery(&req.SalesPerformanceRequest); err != nil {
+		return nil, err
+	}
+
+	params, err := json.Marshal(req.SalesPerformanceRequest)
+	if err != nil {
+		return nil, application.WrapError(application.ErrCodeInternal, "failed to encode export parameters", err)
+	}
+
+	export, err := domain.NewReportExport(tenantID, userID, domain.ReportType(req.Report), domain.ReportExportFormat(req.Format), params)
+	if err != nil {
+		return nil, application.WrapError(application.ErrCodeValidation, "invalid report export", err)
+	}
+
+	if err := uc.exportRepo.Create(ctx, export); err != nil {
+		return nil, application.WrapError(application.ErrCodeInternal, "failed to create report export", err)
+	}
+
+	resp := uc.mapExportToResponse(export)
+
+	uc.runAsync(func() {
+		jobCtx, cancel := context.WithTimeout(context.Background(), reportExportTimeout)
+		defer cancel()
+		uc.processExport(jobCtx, export)
+	})
+
+	return resp, nil
+}
+
+// GetExport returns the status of a report export.
+func (uc *reportUseCase) GetExport(ctx context.Context, tenantID, exportID uuid.UUID) (*dto.ReportExportResponse, error) {
+	if uc.exportRepo == nil {
+		return nil, application.ErrServiceUnavailable("report export storage")
+	}
+
+	export, err := uc.exportRepo.GetByID(ctx, tenantID, exportID)
+	if err != nil {
+		return nil, uc.mapExportError(exportID, err)
+	}
+
+	return uc.mapExportToResponse(export), nil
+}
+
+// ListExports returns the most recent exports requested by a user.
+func (uc *reportUseCase) ListExports(ctx context.Context, tenantID, userID uuid.UUID) ([]*dto.ReportExportResponse, error) {
+	if uc.exportRepo == nil {
+		return nil, application.ErrServiceUnavailable("report export storage")
+	}
+
+	exports, err := uc.exportRepo.ListByUser(ctx, tenantID, userID, recentExportsLimit)
+	if err != nil {
+		return nil, application.WrapError(application.ErrCodeInternal, "failed to list report exports", err)
+	}
+
+	responses := make([]*dto.ReportExportResponse, len(exports))
+	for i, export := range exports {
+		responses[i] = uc.mapExportToResponse(export)
+	}
+	return responses, nil
+}
+
+// DownloadExport returns the generated file of a completed export.
+func (uc *reportUseCase) DownloadExport(ctx context.Context, tenantID, exportID uuid.UUID) (*dto.ReportFileResponse, error) {
+	if uc.exportRepo == nil || uc.fileStorage == nil {
+		return nil, application.ErrServiceUnavailable("report export storage")
+	}
+
+	export, err := uc.exportRepo.GetByID(ctx, tenantID, exportID)
+	if err != nil {
+		return nil, uc.mapExportError(exportID, err)
+	}
+	if !export.IsDownloadable() {
+		return nil, application.ErrConflict("report export is not ready for download")
+	}
+
+	content, err := uc.fileStorage.Download(ctx, tenantID, *export.FileID)
+	if err != nil {
+		return nil, application.WrapError(application.ErrCodeInternal, "failed to download report export", err)
+	}
+
+	return &dto.ReportFileResponse{
+		FileName:    *export.FileName,
+		ContentType: export.Format.ContentType(),
+		Content:     content,
+	}, nil
+}
+
+// processExport generates an export file, stores it and notifies the requester.
+func (uc *reportUseCase) processExport(ctx context.Context, export *domain.ReportExport) {
+	export.MarkProcessing()
+	_ = uc.exportRepo.Update(ctx, export)
+
+	if err := uc.generateExport(ctx, export); err != nil {
+		export.MarkFailed(err.Error())
+	}
+	_ = uc.exportRepo.Update(ctx, export)
+
+	uc.notifyExportFinished(ctx, export)
+}
+
+func (uc *reportUseCase) generateExport(ctx context.Context, export *domain.ReportExport) error {
+	renderer, err := uc.rendererFor(string(export.Format))
+	if err != nil {
+		return err
+	}
+
+	var req dto.SalesPerformanceRequest
+	if err := json.Unmarshal(export.Parameters, &req); err != nil {
+		return fmt.Errorf("invalid export parameters: %w", err)
+	}
+
+	doc, err := uc.buildSalesPerformanceDocument(ctx, export.TenantID, &req)
+	if err != nil {
+		return err
+	}
+
+	var buf bytes.Buffer
+	if err := renderer.Render(ctx, &buf, doc); err != nil {
+		return fmt.Errorf("failed to render report: %w", err)
+	}
+
+	fileName := export.Format.FileName(export.ReportType, export.CreatedAt)
+	info, err := uc.fileStorage.Upload(ctx, ports.FileUploadRequest{
+		TenantID:    export.TenantID,
+		EntityType:  "report_export",
+		EntityID:    export.ID,
+		Filename:    fileName,
+		ContentType: export.Format.ContentType(),
+		Content:     buf.Bytes(),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to store report: %w", err)
+	}
+
+	export.MarkCompleted(info.ID, fileName, int64(buf.Len()))
+	return nil
+}
+
+func (uc *reportUseCase) notifyExportFinished(ctx context.Context, export *domain.ReportExport) {
+	if uc.notificationSvc == nil {
+		return
+	}
+
+	req := ports.InAppNotificationRequest{
+		TenantID: export.TenantID,
+		UserIDs:  []uuid.UUID{export.RequestedBy},
+		Data: map[string]interface{}{
+			"export_id": export.ID.String(),
+			"report":    string(export.ReportType),
+			"format":    string(export.Format),
+		},
+	}
+
+	if export.Status == domain.ReportExportStatusCompleted {
+		downloadURL := exportDownloadURL(export.ID)
+		actionLabel := "Download"
+		req.Title = "Your report is ready"
+		req.Message = fmt.Sprintf("%s is ready to download.", *export.FileName)
+		req.Type = "success"
+		req.ActionURL = &downloadURL
+		req.ActionLabel = &actionLabel
+		req.ExpiresAt = export.ExpiresAt
+	} else {
+		req.Title = "Report export failed"
+		req.Message = fmt.Sprintf("The %s export of the %s report could not be generated.", export.Format, export.ReportType)
+		req.Type = "error"
+	}
+
+	_ = uc.notificationSvc.SendInApp(ctx, req)
+}
+
+// ============================================================================
+// Branding
+// ============================================================================
+
+// GetBranding returns the tenant's report branding, or the defaults if none is configured.
+func (uc *reportUseCase) GetBranding(ctx context.Context, tenantID uuid.UUID) (*dto.ReportBrandingResponse, error) {
+	branding, err := uc.loadBranding(ctx, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	return uc.mapBrandingToResponse(branding), nil
+}
+
+// UpdateBranding updates the tenant's report branding.
+func (uc *reportUseCase) UpdateBranding(ctx context.Context, tenantID uuid.UUID, req *dto.UpdateReportBrandingRequest) (*dto.ReportBrandingResponse, error) {
+	if uc.brandingRepo == nil {
+		return nil, application.ErrServiceUnavailable("report branding storage")
+	}
+
+	branding, err := uc.loadBranding(ctx, tenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.CompanyName != nil {
+		name := strings.TrimSpace(*req.CompanyName)
+		if name == "" {
+			return nil, application.ErrValidation("company_name cannot be empty")
+		}
+		branding.CompanyName = name
+	}
+	if req.Locale != nil {
+		locale := domain.ReportLocale(*req.Locale)
+		if !locale.IsValid() {
+			return nil, application.ErrValidationWithDetails("invalid locale", map[string]interface{}{
+				"locale": *req.Locale,
+			})
+		}
+		branding.Locale = locale
+	}
+	if req.CurrencyDisplay != nil {
+		display := domain.CurrencyDisplay(*req.CurrencyDisplay)
+		if !display.IsValid() {
+			return nil, application.ErrValidationWithDetails("invalid currency_display", map[string]interface{}{
+				"currency_display": *req.CurrencyDisplay,
+			})
+		}
+		branding.CurrencyDisplay = display
+	}
+
+	if req.RemoveLogo {
+		branding.LogoFileID = nil
+	} else if req.LogoBase64 != nil {
+		fileID, err := uc.uploadLogo(ctx, tenantID, *req.LogoBase64)
+		if err != nil {
+			return nil, err
+		}
+		branding.LogoFileID = &fileID
+	}
+
+	branding.UpdatedAt = time.Now().UTC()
+	if err := uc.brandingRepo.Upsert(ctx, branding); err != nil {
+		return nil, application.WrapError(application.ErrCodeInternal, "failed to update report branding", err)
+	}
+
+	return uc.mapBrandingToResponse(branding), nil
+}
+
+func (uc *reportUseCase) loadBranding(ctx context.Context, tenantID uuid.UUID) (*domain.ReportBranding, error) {
+	return loadReportBranding(ctx, uc.brandingRepo, tenantID)
+}
+
+// loadReportBranding returns the tenant's report branding, or the defaults if none is configured.
+func loadReportBranding(ctx context.Context, brandingRepo domain.ReportBrandingRepository, tenantID uuid.UUID) (*domain.ReportBranding, error) {
+	if brandingRepo == nil {
+		return domain.DefaultReportBranding(tenantID), nil
+	}
+
+	branding, err := brandingRepo.Get(ctx, tenantID)
+	if err != nil {
+		return nil, application.WrapError(application.ErrCodeInternal, "failed to get report branding", err)
+	}
+	if branding == nil {
+		return domain.DefaultReportBranding(tenantID), nil
+	}
+	return branding, nil
+}
+
+// uploadLogo validates and stores a base64-encoded JPEG logo.
+func (uc *reportUseCase) uploadLogo(ctx context.Context, tenantID uuid.UUID, encoded string) (string, error) {
+	if uc.fileStorage == nil {
+		return "", application.ErrServiceUnavailable("file storage")
+	}
+
+	if i := strings.Index(encoded, ","); strings.HasPrefix(encoded, "data:") && i > 0 {
+		encoded = encoded[i+1:]
+	}
+	logo, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return "", application.ErrValidation("logo_base64 is not valid base64")
+	}
+	if len(logo) > maxReportLogoSize {
+		return "", application.ErrValidationWithDetails("logo is too large", map[string]interface{}{
+			"max_bytes": maxReportLogoSize,
+		})
+	}
+	if contentType := http.DetectContentType(logo); contentType != "image/jpeg" {
+		return "", application.ErrValidationWithDetails("logo must be a JPEG image", map[string]interface{}{
+			"content_type": contentType,
+		})
+	}
+
+	info, err := uc.fileStorage.Upload(ctx, ports.FileUploadRequest{
+		TenantID:    tenantID,
+		EntityType:  "report_branding",
+		EntityID:    tenantID,
+		Filename:    "report-logo.jpg",
+		ContentType: "image/jpeg",
+		Content:     logo,
+	})
+	if err != nil {
+		return "", application.WrapError(application.ErrCodeInternal, "failed to store logo", err)
+	}
+	return info.ID, nil
+}
+
+// ============================================================================
+// Document Building
+// ============================================================================
+
+// buildSalesPerformanceDocument builds a localized, render-ready sales performance report.
+func (uc *reportUseCase) buildSalesPerformanceDocument(ctx context.Context, tenantID uuid.UUID, req *dto.SalesPerformanceRequest) (*ports.ReportDocument, error) {
+	report, err := uc.GetSalesPerformance(ctx, tenantID, req)
+	if err != nil {
+		return nil, err
+	}
+
+	branding, err := uc.loadBranding(ctx, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	labels := reportLabelsFor(branding.Locale)
+	money := func(m dto.MoneyDTO) ports.ReportCell {
+		return moneyCell(m.Amount, m.Currency, branding.CurrencyDisplay)
+	}
+
+	doc := &ports.ReportDocument{
+		Title: labels.salesPerformanceTitle,
+		Subtitle: fmt.Sprintf(labels.periodFormat,
+			formatReportDate(report.Period.StartDate, branding.Locale),
+			formatReportDate(report.Period.EndDate, branding.Locale),
+			report.Currency),
+		Branding: reportBrandingAsset(ctx, uc.fileStorage, uc.tenantBranding, tenantID, branding),
+		Columns: []ports.ReportColumn{
+			{Header: labels.groupBy[domain.ReportGroupBy(report.GroupBy)]},
+			{Header: labels.newLeads, Numeric: true},
+			{Header: labels.convertedLeads, Numeric: true},
+			{Header: labels.conversionRate, Numeric: true, Decimals: 1},
+			{Header: labels.opportunitiesWon, Numeric: true},
+			{Header: labels.opportunitiesLost, Numeric: true},
+			{Header: labels.winRate, Numeric: true, Decimals: 1},
+			{Header: labels.revenue, Numeric: true, Decimals: 2},
+			{Header: labels.averageDealSize, Numeric: true, Decimals: 2},
+		},
+		Chart: &ports.ReportChart{
+			Title: fmt.Sprintf(labels.chartTitleFormat, strings.ToLower(labels.groupBy[domain.ReportGroupBy(report.GroupBy)])),
+		},
+		FooterText:  fmt.Sprintf(labels.generatedFormat, report.GeneratedAt.Format("2006-01-02 15:04 MST")),
+		GeneratedAt: report.GeneratedAt,
+	}
+
+	metricCells := func(label string, m dto.SalesPerformanceMetricsDTO) []ports.ReportCell {
+		return []ports.ReportCell{
+			{Display: label},
+			countCell(m.NewLeads),
+			countCell(m.ConvertedLeads),
+			percentCell(m.ConversionRate),
+			countCell(m.OpportunitiesWon),
+			countCell(m.OpportunitiesLost),
+			percentCell(m.WinRate),
+			money(m.Revenue),
+			money(m.AverageDealSize),
+		}
+	}
+
+	for _, group := range report.Groups {
+		label := group.Label
+		if label == "" {
+			label = group.Key
+		}
+		doc.Rows = append(doc.Rows, metricCells(label, group.SalesPerformanceMetricsDTO))
+		doc.Chart.Labels = append(doc.Chart.Labels, label)
+		doc.Chart.Values = append(doc.Chart.Values, *money(group.Revenue).Value)
+	}
+	doc.Totals = metricCells(labels.total, report.Totals)
+
+	return doc, nil
+}
+
+// reportBrandingAsset returns the branding to render, including the logo when it can be downloaded.
+// The tenant's white-label branding supplies the brand color, and the company name when the report
+// branding still has the default one.
+func reportBrandingAsset(ctx context.Context, fileStorage ports.FileStorageService, tenantBranding ports.TenantBrandingProvider, tenantID uuid.UUID, branding *domain.ReportBranding) ports.ReportBrandingAsset {
+	asset := ports.ReportBrandingAsset{CompanyName: branding.CompanyName}
+	if tenantBranding != nil {
+		if tenant, err := tenantBranding.GetTenantBranding(ctx, tenantID); err == nil && tenant != nil {
+			asset.PrimaryColor = tenant.PrimaryColor
+			if tenant.TenantName != "" && branding.CompanyName == domain.DefaultReportBranding(tenantID).CompanyName {
+				asset.CompanyName = tenant.TenantName
+			}
+		}
+	}
+	if branding.LogoFileID != nil && fileStorage != nil {
+		if logo, err := fileStorage.Download(ctx, tenantID, *branding.LogoFileID); err == nil {
+			asset.Logo = logo
+			asset.LogoContentType = "image/jpeg"
+		}
+	}
+	return asset
+}
+
+func (uc *reportUseCase) rendererFor(format string) (ports.ReportRenderer, error) {
+	if !domain.ReportExportFormat(format).IsValid() {
+		return nil, application.ErrValidationWithDetails("invalid export format", map[string]interface{}{
+			"format":  format,
+			"allowed": []string{string(domain.ReportExportFormatXLSX), string(domain.ReportExportFormatPDF)},
+		})
+	}
+
+	renderer, ok := uc.renderers[format]
+	if !ok {
+		return nil, application.ErrServiceUnavailable(format + " renderer")
+	}
+	return renderer, nil
+}
+
+// ============================================================================
+// Mapping Helpers
+// ============================================================================
+
+func (uc *reportUseCase) mapExportToResponse(export *domain.ReportExport) *dto.ReportExportResponse {
+	resp := &dto.ReportExportResponse{
+		ID:          export.ID.String(),
+		Report:      string(export.ReportType),
+		Format:      string(export.Format),
+		Status:      string(export.Status),
+		FileName:    export.FileName,
+		FileSize:    export.FileSize,
+		Error:       export.Error,
+		CreatedAt:   export.CreatedAt,
+		CompletedAt: export.CompletedAt,
+		ExpiresAt:   export.ExpiresAt,
+	}
+	if export.IsDownloadable() {
+		downloadURL := exportDownloadURL(export.ID)
+		resp.DownloadURL = &downloadURL
+	}
+	return resp
+}
+
+func (uc *reportUseCase) mapExportError(exportID uuid.UUID, err error) error {
+	if err == domain.ErrReportExportNotFound {
+		return application.ErrNotFound("report export", exportID)
+	}
+	return application.WrapError(application.ErrCodeInternal, "failed to get report export", err)
+}
+
+func (uc *reportUseCase) mapBrandingToResponse(branding *domain.ReportBranding) *dto.ReportBrandingResponse {
+	resp := &dto.ReportBrandingResponse{
+		CompanyName:     branding.CompanyName,
+		HasLogo:         branding.LogoFileID != nil,
+		Locale:          string(branding.Locale),
+		CurrencyDisplay: string(branding.CurrencyDisplay),
+	}
+	if !branding.UpdatedAt.IsZero() {
+		updatedAt := branding.UpdatedAt
+		resp.UpdatedAt = &updatedAt
+	}
+	return resp
+}
+
+func exportDownloadURL(exportID uuid.UUID) string {
+	return "/api/v1/reports/exports/" + exportID.String() + "/download"
+}
+
+// ============================================================================
+// Localization
+// ============================================================================
+
+// reportLabels holds the translated text used in rendered reports.
+type reportLabels struct {
+	salesPerformanceTitle string
+	periodFormat          string
+	chartTitleFormat      string
+	generatedFormat       string
+	total                 string
+	groupBy               map[domain.ReportGroupBy]string
+	newLeads              string
+	convertedLeads        string
+	conversionRate        string
+	opportunitiesWon      string
+	opportunitiesLost     string
+	winRate               string
+	revenue               string
+	averageDealSize       string
+	months                [12]string
+}
+
+var reportLabelsByLocale = map[domain.ReportLocale]reportLabels{
+	domain.ReportLocaleEnglish: {
+		salesPerformanceTitle: "Sales Performance Report",
+		periodFormat:          "Period: %s to %s (%s)",
+		chartTitleFormat:      "Won revenue by %s",
+		generatedFormat:       "Generated on %s",
+		total:                 "Total",
+		groupBy: map[domain.ReportGroupBy]string{
+			domain.ReportGroupByOwner:   "Owner",
+			domain.ReportGroupByProduct: "Product",
+			domain.ReportGroupByRegion:  "Region",
+			domain.ReportGroupByDay:     "Date",
+		},
+		newLeads:          "New Leads",
+		convertedLeads:    "Converted",
+		conversionRate:    "Conversion %",
+		opportunitiesWon:  "Won",
+		opportunitiesLost: "Lost",
+		winRate:           "Win Rate %",
+		revenue:           "Revenue",
+		averageDealSize:   "Avg Deal Size",
+		months:            [12]string{"Jan", "Feb", "Mar", "Apr", "May", "Jun", "Jul", "Aug", "Sep", "Oct", "Nov", "Dec"},
+	},
+	domain.ReportLocaleMalay: {
+		salesPerformanceTitle: "Laporan Prestasi Jualan",
+		periodFormat:          "Tempoh: %s hingga %s (%s)",
+		chartTitleFormat:      "Hasil dimenangi mengikut %s",
+		generatedFormat:       "Dijana pada %s",
+		total:                 "Jumlah",
+		groupBy: map[domain.ReportGroupBy]string{
+			domain.ReportGroupByOwner:   "Pemilik",
+			domain.ReportGroupByProduct: "Produk",
+			domain.ReportGroupByRegion:  "Wilayah",
+			domain.ReportGroupByDay:     "Tarikh",
+		},
+		newLeads:          "Prospek Baharu",
+		convertedLeads:    "Ditukar",
+		conversionRate:    "Kadar Penukaran %",
+		opportunitiesWon:  "Menang",
+		opportunitiesLost: "Kalah",
+		winRate:           "Kadar Kemenangan %",
+		revenue:           "Hasil",
+		averageDealSize:   "Purata Nilai Urus Niaga",
+		months:            [12]string{"Jan", "Feb", "Mac", "Apr", "Mei", "Jun", "Jul", "Ogo", "Sep", "Okt", "Nov", "Dis"},
+	},
+}
+
+// reportLabelsFor returns the labels of a locale, falling back to English.
+func reportLabelsFor(locale domain.ReportLocale) reportLabels {
+	if labels, ok := reportLabelsByLocale[locale]; ok {
+		return labels
+	}
+	return reportLabelsByLocale[domain.ReportLocaleEnglish]
+}
+
+// formatReportDate formats a date as e.g. "5 Mar 2024" using the locale's month names.
+func formatReportDate(t time.Time, locale domain.ReportLocale) string {
+	labels := reportLabelsFor(locale)
+	return fmt.Sprintf("%d %s %d", t.Day(), labels.months[t.Month()-1], t.Year())
+}
+
+// reportCurrencySymbols maps currencies to the symbols used in rendered reports.
+// Symbols are limited to Latin-1 so they render with the PDF base fonts.
+var reportCurrencySymbols = map[string]string{
+	"MYR": "RM",
+	"SGD": "S$",
+	"USD": "US$",
+	"GBP": "£",
+}
+
+// formatReportMoney formats an amount in the smallest currency unit with thousands separators.
+func formatReportMoney(amount int64, currency string, display domain.CurrencyDisplay) string {
+	decimals := domain.Money{Amount: amount, Currency: currency}.GetDecimals()
+
+	negative := amount < 0
+	if negative {
+		amount = -amount
+	}
+
+	divisor := int64(1)
+	for i := 0; i < decimals; i++ {
+		divisor *= 10
+	}
+	number := groupThousands(strconv.FormatInt(amount/divisor, 10))
+	if decimals > 0 {
+		number += fmt.Sprintf(".%0*d", decimals, amount%divisor)
+	}
+	if negative {
+		number = "-" + number
+	}
+
+	if symbol, ok := reportCurrencySymbols[currency]; ok && display != domain.CurrencyDisplayCode {
+		return symbol + number
+	}
+	return currency + " " + number
+}
+
+// groupThousands inserts comma separators into a string of digits.
+func groupThousands(digits string) string {
+	if len(digits) <= 3 {
+		return digits
+	}
+	var b strings.Builder
+	head := len(digits) % 3
+	if head > 0 {
+		b.WriteString(digits[:head])
+	}
+	for i := head; i < len(digits); i += 3 {
+		if b.Len() > 0 {
+			b.WriteByte(',')
+		}
+		b.WriteString(digits[i : i+3])
+	}
+	return b.String()
+}
+
+func countCell(v int64) ports.ReportCell {
+	value := float64(v)
+	return ports.ReportCell{Display: groupThousands(strconv.FormatInt(v, 10)), Value: &value}
+}
+
+func percentCell(v float64) ports.ReportCell {
+	value := float64(int64(v*10+0.5)) / 10
+	return ports.ReportCell{Display: strconv.FormatFloat(value, 'f', 1, 64) + "%", Value: &value}
+}
+
+func moneyCell(amount int64, currency string, display domain.CurrencyDisplay) ports.ReportCell {
+	decimals := domain.Money{Amount: amount, Currency: currency}.GetDecimals()
+	value := float64(amount) / math.Pow10(decimals)
+	return ports.ReportCell{Display: formatReportMoney(amount, currency, display), Value: &value}
+}
@@ -0,0 +1,378 @@
+package usecase
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/kilang-desa-murni/crm/internal/sales/application"
+	"github.com/kilang-desa-murni/crm/internal/sales/application/dto"
+	"github.com/kilang-desa-murni/crm/internal/sales/application/ports"
+	"github.com/kilang-desa-murni/crm/internal/sales/domain"
+)
+
+// ============================================================================
+// Mock Implementations for Report Export Tests
+// ============================================================================
+
+// MockReportExportRepository is a mock implementation of domain.ReportExportRepository.
+type MockReportExportRepository struct {
+	exports map[uuid.UUID]*domain.ReportExport
+	updates int
+}
+
+func NewMockReportExportRepository() *MockReportExportRepository {
+	return &MockReportExportRepository{exports: make(map[uuid.UUID]*domain.ReportExport)}
+}
+
+func (m *MockReportExportRepository) Create(ctx context.Context, export *domain.ReportExport) error {
+	m.exports[export.ID] = export
+	return nil
+}
+
+func (m *MockReportExportRepository) Update(ctx context.Context, export *domain.ReportExport) error {
+	m.updates++
+	m.exports[export.ID] = export
+	return nil
+}
+
+func (m *MockReportExportRepository) GetByID(ctx context.Context, tenantID, exportID uuid.UUID) (*domain.ReportExport, error) {
+	export, ok := m.exports[exportID]
+	if !ok || export.TenantID != tenantID {
+		return nil, domain.ErrReportExportNotFound
+	}
+	return export, nil
+}
+
+func (m *MockReportExportRepository) ListByUser(ctx context.Context, tenantID, userID uuid.UUID, limit int) ([]*domain.ReportExport, error) {
+	var exports []*domain.ReportExport
+	for _, export := range m.exports {
+		if export.TenantID == tenantID && export.RequestedBy == userID {
+			exports = append(exports, export)
+		}
+	}
+	return exports, nil
+}
+
+// MockReportBrandingRepository is a mock implementation of domain.ReportBrandingRepository.
+type MockReportBrandingRepository struct {
+	branding map[uuid.UUID]*domain.ReportBranding
+}
+
+func (m *MockReportBrandingRepository) Get(ctx context.Context, tenantID uuid.UUID) (*domain.ReportBranding, error) {
+	return m.branding[tenantID], nil
+}
+
+func (m *MockReportBrandingRepository) Upsert(ctx context.Context, branding *domain.ReportBranding) error {
+	if m.branding == nil {
+		m.branding = make(map[uuid.UUID]*domain.ReportBranding)
+	}
+	m.branding[branding.TenantID] = branding
+	return nil
+}
+
+// MockFileStorageService is a mock implementation of ports.FileStorageService.
+type MockFileStorageService struct {
+	files map[string][]byte
+}
+
+func NewMockFileStorageService() *MockFileStorageService {
+	return &MockFileStorageService{files: make(map[string][]byte)}
+}
+
+func (m *MockFileStorageService) Upload(ctx context.Context, req ports.FileUploadRequest) (*ports.FileInfo, error) {
+	id := uuid.New().String()
+	m.files[id] = req.Content
+	return &ports.FileInfo{ID: id, TenantID: req.TenantID, Filename: req.Filename, Size: int64(len(req.Content))}, nil
+}
+
+func (m *MockFileStorageService) Store(ctx context.Context, tenantID uuid.UUID, fileID string, content []byte) error {
+	m.files[fileID] = content
+	return nil
+}
+
+func (m *MockFileStorageService) Download(ctx context.Context, tenantID uuid.UUID, fileID string) ([]byte, error) {
+	content, ok := m.files[fileID]
+	if !ok {
+		return nil, ports.ErrFileNotFound
+	}
+	return content, nil
+}
+
+func (m *MockFileStorageService) Delete(ctx context.Context, tenantID uuid.UUID, fileID string) error {
+	delete(m.files, fileID)
+	return nil
+}
+
+func (m *MockFileStorageService) GetURL(ctx context.Context, tenantID uuid.UUID, fileID string, expiresIn time.Duration) (string, error) {
+	return "https://files.example.com/" + fileID, nil
+}
+
+func (m *MockFileStorageService) ListFiles(ctx context.Context, tenantID uuid.UUID, entityType string, entityID uuid.UUID) ([]*ports.FileInfo, error) {
+	return nil, nil
+}
+
+// MockReportNotificationService records in-app notifications.
+type MockReportNotificationService struct {
+	inApp []ports.InAppNotificationRequest
+}
+
+func (m *MockReportNotificationService) SendEmail(ctx context.Context, req ports.EmailRequest) error {
+	return nil
+}
+
+func (m *MockReportNotificationService) SendInApp(ctx context.Context, req ports.InAppNotificationRequest) error {
+	m.inApp = append(m.inApp, req)
+	return nil
+}
+
+func (m *MockReportNotificationService) SendSMS(ctx context.Context, req ports.SMSRequest) error {
+	return nil
+}
+
+func (m *MockReportNotificationService) SendBatch(ctx context.Context, notifications []ports.NotificationRequest) error {
+	return nil
+}
+
+// MockReportRenderer writes the document title and row labels as plain text.
+type MockReportRenderer struct {
+	format  string
+	lastDoc *ports.ReportDocument
+}
+
+func (m *MockReportRenderer) Format() string {
+	return m.format
+}
+
+func (m *MockReportRenderer) Render(ctx context.Context, w io.Writer, doc *ports.ReportDocument) error {
+	m.lastDoc = doc
+	_, err := io.WriteString(w, doc.Title)
+	return err
+}
+
+type reportExportFixture struct {
+	uc            *reportUseCase
+	exportRepo    *MockReportExportRepository
+	brandingRepo  *MockReportBrandingRepository
+	storage       *MockFileStorageService
+	notifications *MockReportNotificationService
+	pdf           *MockReportRenderer
+}
+
+func newReportExportFixture() *reportExportFixture {
+	f := &reportExportFixture{
+		exportRepo:    NewMockReportExportRepository(),
+		brandingRepo:  &MockReportBrandingRepository{},
+		storage:       NewMockFileStorageService(),
+		notifications: &MockReportNotificationService{},
+		pdf:           &MockReportRenderer{format: "pdf"},
+	}
+	repo := &MockReportRepository{
+		rows: []*domain.SalesPerformanceRow{
+			{GroupKey: "owner-1", GroupLabel: "Aminah", NewLeads: 4, ConvertedLeads: 2, OpportunitiesWon: 2, WonRevenue: 123456789},
+		},
+	}
+	uc := NewReportUseCase(repo, f.exportRepo, f.brandingRepo,
+		[]ports.ReportRenderer{f.pdf, &MockReportRenderer{format: "xlsx"}},
+		f.storage, f.notifications, nil, nil).(*reportUseCase)
+	uc.runAsync = func(fn func()) { fn() }
+	f.uc = uc
+	return f
+}
+
+// ============================================================================
+// Report Export Use Case Tests
+// ============================================================================
+
+func TestReportUseCase_RequestExport_CompletesAndNotifies(t *testing.T) {
+	f := newReportExportFixture()
+	tenantID, userID := uuid.New(), uuid.New()
+
+	resp, err := f.uc.RequestExport(context.Background(), tenantID, userID, &dto.CreateReportExportRequest{
+		Report: "sales-performance",
+		Format: "pdf",
+		SalesPerformanceRequest: dto.SalesPerformanceRequest{
+			From: "2024-01-01",
+			To:   "2024-01-31",
+		},
+	})
+	if err != nil {
+		t.Fatalf("RequestExport() error = %v", err)
+	}
+	if resp.Status != string(domain.ReportExportStatusPending) {
+		t.Errorf("expected pending status in response, got %s", resp.Status)
+	}
+
+	exportID := uuid.MustParse(resp.ID)
+	stored, _ := f.exportRepo.GetByID(context.Background(), tenantID, exportID)
+	if stored.Status != domain.ReportExportStatusCompleted {
+		t.Fatalf("expected completed export, got %s (error %v)", stored.Status, stored.Error)
+	}
+
+	if len(f.notifications.inApp) != 1 {
+		t.Fatalf("expected 1 notification, got %d", len(f.notifications.inApp))
+	}
+	notification := f.notifications.inApp[0]
+	if notification.UserIDs[0] != userID || notification.ActionURL == nil ||
+		!strings.HasSuffix(*notification.ActionURL, exportID.String()+"/download") {
+		t.Errorf("unexpected notification: %+v", notification)
+	}
+
+	file, err := f.uc.DownloadExport(context.Background(), tenantID, exportID)
+	if err != nil {
+		t.Fatalf("DownloadExport() error = %v", err)
+	}
+	if file.ContentType != "application/pdf" || !strings.HasSuffix(file.FileName, ".pdf") {
+		t.Errorf("unexpected file: %s (%s)", file.FileName, file.ContentType)
+	}
+	if string(file.Content) != "Sales Performance Report" {
+		t.Errorf("unexpected content %q", file.Content)
+	}
+}
+
+func TestReportUseCase_RequestExport_InvalidFormat(t *testing.T) {
+	f := newReportExportFixture()
+
+	_, err := f.uc.RequestExport(context.Background(), uuid.New(), uuid.New(), &dto.CreateReportExportRequest{
+		Report: "sales-performance",
+		Format: "docx",
+		SalesPerformanceRequest: dto.SalesPerformanceRequest{
+			From: "2024-01-01",
+			To:   "2024-01-31",
+		},
+	})
+	if !application.IsValidationError(err) {
+		t.Errorf("expected validation error, got %v", err)
+	}
+}
+
+func TestReportUseCase_DownloadExport_NotReady(t *testing.T) {
+	f := newReportExportFixture()
+	tenantID := uuid.New()
+
+	export, _ := domain.NewReportExport(tenantID, uuid.New(), domain.ReportTypeSalesPerformance, domain.ReportExportFormatXLSX, nil)
+	_ = f.exportRepo.Create(context.Background(), export)
+
+	if _, err := f.uc.DownloadExport(context.Background(), tenantID, export.ID); err == nil {
+		t.Error("expected error for pending export")
+	}
+	if _, err := f.uc.DownloadExport(context.Background(), uuid.New(), export.ID); err == nil {
+		t.Error("expected error for export of another tenant")
+	}
+}
+
+func TestReportUseCase_ExportSalesPerformance_UsesBranding(t *testing.T) {
+	f := newReportExportFixture()
+	tenantID := uuid.New()
+
+	locale := "ms-MY"
+	display := "code"
+	logo := base64.StdEncoding.EncodeToString([]byte("\xff\xd8\xff\xe0\x00\x10JFIF\x00"))
+	if _, err := f.uc.UpdateBranding(context.Background(), tenantID, &dto.UpdateReportBrandingRequest{
+		Locale:          &locale,
+		CurrencyDisplay: &display,
+		LogoBase64:      &logo,
+	}); err != nil {
+		t.Fatalf("UpdateBranding() error = %v", err)
+	}
+
+	var buf bytes.Buffer
+	err := f.uc.ExportSalesPerformance(context.Background(), tenantID, &dto.SalesPerformanceRequest{
+		From: "2024-03-01",
+		To:   "2024-03-31",
+	}, "pdf", &buf)
+	if err != nil {
+		t.Fatalf("ExportSalesPerformance() error = %v", err)
+	}
+
+	doc := f.pdf.lastDoc
+	if doc.Title != "Laporan Prestasi Jualan" {
+		t.Errorf("expected Malay title, got %q", doc.Title)
+	}
+	if doc.Subtitle != "Tempoh: 1 Mac 2024 hingga 31 Mac 2024 (MYR)" {
+		t.Errorf("unexpected subtitle %q", doc.Subtitle)
+	}
+	if got := doc.Rows[0][7].Display; got != "MYR 1,234,567.89" {
+		t.Errorf("expected revenue in code display, got %q", got)
+	}
+	if len(doc.Branding.Logo) == 0 {
+		t.Error("expected logo to be embedded")
+	}
+	if doc.Chart == nil || doc.Chart.Values[0] != 1234567.89 {
+		t.Errorf("unexpected chart: %+v", doc.Chart)
+	}
+}
+
+// MockTenantBrandingProvider returns a fixed tenant branding.
+type MockTenantBrandingProvider struct {
+	branding *ports.TenantBranding
+}
+
+func (m *MockTenantBrandingProvider) GetTenantBranding(ctx context.Context, tenantID uuid.UUID) (*ports.TenantBranding, error) {
+	return m.branding, nil
+}
+
+func TestReportUseCase_ExportSalesPerformance_UsesTenantBranding(t *testing.T) {
+	f := newReportExportFixture()
+	f.uc.tenantBranding = &MockTenantBrandingProvider{branding: &ports.TenantBranding{
+		TenantName:   "Batik Warisan",
+		PrimaryColor: "#1a5276",
+	}}
+
+	var buf bytes.Buffer
+	err := f.uc.ExportSalesPerformance(context.Background(), uuid.New(), &dto.SalesPerformanceRequest{
+		From: "2024-03-01",
+		To:   "2024-03-31",
+	}, "pdf", &buf)
+	if err != nil {
+		t.Fatalf("ExportSalesPerformance() error = %v", err)
+	}
+
+	branding := f.pdf.lastDoc.Branding
+	if branding.CompanyName != "Batik Warisan" {
+		t.Errorf("expected tenant name in place of the default company name, got %q", branding.CompanyName)
+	}
+	if branding.PrimaryColor != "#1a5276" {
+		t.Errorf("expected tenant brand color, got %q", branding.PrimaryColor)
+	}
+}
+
+func TestReportUseCase_UpdateBranding_RejectsNonJPEGLogo(t *testing.T) {
+	f := newReportExportFixture()
+
+	logo := base64.StdEncoding.EncodeToString([]byte("\x89PNG\r\n\x1a\n"))
+	_, err := f.uc.UpdateBranding(context.Background(), uuid.New(), &dto.UpdateReportBrandingRequest{
+		LogoBase64: &logo,
+	})
+	if !application.IsValidationError(err) {
+		t.Errorf("expected validation error, got %v", err)
+	}
+}
+
+func TestFormatReportMoney(t *testing.T) {
+	tests := []struct {
+		amount   int64
+		currency string
+		display  domain.CurrencyDisplay
+		want     string
+	}{
+		{0, "MYR", domain.CurrencyDisplaySymbol, "RM0.00"},
+		{123456789, "MYR", domain.CurrencyDisplaySymbol, "RM1,234,567.89"},
+		{123456789, "MYR", domain.CurrencyDisplayCode, "MYR 1,234,567.89"},
+		{-150005, "SGD", domain.CurrencyDisplaySymbol, "S$-1,500.05"},
+		{1500000, "JPY", domain.CurrencyDisplaySymbol, "JPY 1,500,000"},
+		{100, "EUR", domain.CurrencyDisplaySymbol, "EUR 1.00"},
+	}
+
+	for _, tt := range tests {
+		if got := formatReportMoney(tt.amount, tt.currency, tt.display); got != tt.want {
+			t.Errorf("formatReportMoney(%d, %s, %s) = %q, want %q", tt.amount, tt.currency, tt.display, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,481 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/kilang-desa-murni/crm/internal/sales/application"
+	"github.com/kilang-desa-murni/crm/internal/sales/application/dto"
+	"github.com/kilang-desa-murni/crm/internal/sales/application/ports"
+	"github.com/kilang-desa-murni/crm/internal/sales/domain"
+)
+
+// defaultReportCurrency is used when a report request does not specify a currency.
+const defaultReportCurrency = "MYR"
+
+// reportCacheTTL is how long rendered reports are cached. Aggregates only change
+// when the aggregation worker runs, which invalidates the cache.
+const reportCacheTTL = 15 * time.Minute
+
+// ============================================================================
+// Report Use Case Interface
+// ============================================================================
+
+// ReportUseCase defines the interface for reporting operations.
+type ReportUseCase interface {
+	// Reports
+	GetSalesPerformance(ctx context.Context, tenantID uuid.UUID, req *dto.SalesPerformanceRequest) (*dto.SalesPerformanceResponse, error)
+	GetAttributionReport(ctx context.Context, tenantID uuid.UUID, req *dto.SalesPerformanceRequest) (*dto.AttributionReportResponse, error)
+	GetWinLossReport(ctx context.Context, tenantID uuid.UUID, req *dto.WinLossReportRequest) (*dto.WinLossReportResponse, error)
+
+	// Aggregation
+	RunDailyAggregation(ctx context.Context, day time.Time) (*dto.AggregationRunResponse, error)
+	RebuildAggregates(ctx context.Context, req *dto.RunAggregationRequest) (*dto.AggregationRunResponse, error)
+	GetLastAggregatedDay(ctx context.Context) (*time.Time, error)
+
+	// Exports
+	ExportSalesPerformance(ctx context.Context, tenantID uuid.UUID, req *dto.SalesPerformanceRequest, format string, w io.Writer) error
+	RequestExport(ctx context.Context, tenantID, userID uuid.UUID, req *dto.CreateReportExportRequest) (*dto.ReportExportResponse, error)
+	GetExport(ctx context.Context, tenantID, exportID uuid.UUID) (*dto.ReportExportResponse, error)
+	ListExports(ctx context.Context, tenantID, userID uuid.UUID) ([]*dto.ReportExportResponse, error)
+	DownloadExport(ctx context.Context, tenantID, exportID uuid.UUID) (*dto.ReportFileResponse, error)
+
+	// Branding
+	GetBranding(ctx context.Context, tenantID uuid.UUID) (*dto.ReportBrandingResponse, error)
+	UpdateBranding(ctx context.Context, tenantID uuid.UUID, req *dto.UpdateReportBrandingRequest) (*dto.ReportBrandingResponse, error)
+}
+
+// ============================================================================
+// Report Use Case Implementation
+// ============================================================================
+
+// reportUseCase implements ReportUseCase.
+type reportUseCase struct {
+	reportRepo      domain.ReportRepository
+	exportRepo      domain.ReportExportRepository
+	brandingRepo    domain.ReportBrandingRepository
+	renderers       map[string]ports.ReportRenderer
+	fileStorage     ports.FileStorageService
+	notificationSvc ports.NotificationService
+	cacheService    ports.CacheService
+	tenantBranding  ports.TenantBrandingProvider
+
+	// runAsync runs background export jobs.
+	runAsync func(func())
+}
+
+// NewReportUseCase creates a new report use case.
+func NewReportUseCase(
+	reportRepo domain.ReportRepository,
+	exportRepo domain.ReportExportRepository,
+	brandingRepo domain.ReportBrandingRepository,
+	renderers []ports.ReportRenderer,
+	fileStorage ports.FileStorageService,
+	notificationSvc ports.NotificationService,
+	cacheService ports.CacheService,
+	tenantBranding ports.TenantBrandingProvider,
+) ReportUseCase {
+	rendererByFormat := make(map[string]ports.ReportRenderer, len(renderers))
+	for _, renderer := range renderers {
+		rendererByFormat[renderer.Format()] = renderer
+	}
+
+	return &reportUseCase{
+		reportRepo:      reportRepo,
+		exportRepo:      exportRepo,
+		brandingRepo:    brandingRepo,
+		renderers:       rendererByFormat,
+		fileStorage:     fileStorage,
+		notificationSvc: notificationSvc,
+		cacheService:    cacheService,
+		tenantBranding:  tenantBranding,
+		runAsync:        func(fn func()) { go fn() },
+	}
+}
+
+// ============================================================================
+// Reports
+// ============================================================================
+
+// GetSalesPerformance returns sales performance for a period, grouped by owner, product, region, day,
+// campaign or channel.
+func (uc *reportUseCase) GetSalesPerformance(ctx context.Context, tenantID uuid.UUID, req *dto.SalesPerformanceRequest) (*dto.SalesPerformanceResponse, error) {
+	period, groupBy, currency, err := resolveSalesPerformanceQuery(req)
+	if err != nil {
+		return nil, err
+	}
+
+	cacheKey := uc.reportCacheKey(tenantID, "sales-performance", period, string(groupBy), currency)
+	var cached dto.SalesPerformanceResponse
+	if uc.getCachedReport(ctx, cacheKey, &cached) {
+		return &cached, nil
+	}
+
+	rows, err := uc.reportRepo.GetSalesPerformance(ctx, tenantID, period, groupBy, currency)
+	if err != nil {
+		return nil, application.WrapError(application.ErrCodeInternal, "failed to get sales performance", err)
+	}
+
+	totals := &domain.SalesPerformanceRow{}
+	groups := make([]*dto.SalesPerformanceGroupDTO, 0, len(rows))
+	for _, row := range rows {
+		totals.Merge(row)
+		groups = append(groups, &dto.SalesPerformanceGroupDTO{
+			Key:                        row.GroupKey,
+			Label:                      row.GroupLabel,
+			SalesPerformanceMetricsDTO: uc.mapPerformanceMetrics(row, currency),
+		})
+	}
+
+	resp := &dto.SalesPerformanceResponse{
+		Period: dto.DateRangeDTO{
+			StartDate: period.From,
+			EndDate:   period.To.AddDate(0, 0, -1),
+		},
+		GroupBy:     string(groupBy),
+		Currency:    currency,
+		Totals:      uc.mapPerformanceMetrics(totals, currency),
+		Groups:      groups,
+		GeneratedAt: time.Now().UTC(),
+	}
+
+	if lastDay, err := uc.GetLastAggregatedDay(ctx); err == nil && lastDay != nil {
+		resp.DataAsOf = lastDay
+	}
+
+	uc.cacheReport(ctx, cacheKey, resp)
+
+	return resp, nil
+}
+
+// GetAttributionReport returns sales performance for a period by the campaign
+// and by the channel leads came from. Won revenue follows a lead through its
+// opportunity, so campaigns are credited with the deals they brought in.
+func (uc *reportUseCase) GetAttributionReport(ctx context.Context, tenantID uuid.UUID, req *dto.SalesPerformanceRequest) (*dto.AttributionReportResponse, error) {
+	campaignReq := *req
+	campaignReq.GroupBy = string(domain.ReportGroupByCampaign)
+	campaigns, err := uc.GetSalesPerformance(ctx, tenantID, &campaignReq)
+	if err != nil {
+		return nil, err
+	}
+
+	channelReq := *req
+	channelReq.GroupBy = string(domain.ReportGroupByChannel)
+	channels, err := uc.GetSalesPerformance(ctx, tenantID, &channelReq)
+	if err != nil {
+		return nil, err
+	}
+
+	return &dto.AttributionReportResponse{
+		Period:      campaigns.Period,
+		Currency:    campaigns.Currency,
+		Totals:      campaigns.Totals,
+		Campaigns:   campaigns.Groups,
+		Channels:    channels.Groups,
+		DataAsOf:    campaigns.DataAsOf,
+		GeneratedAt: time.Now().UTC(),
+	}, nil
+}
+
+// ============================================================================
+// Aggregation
+// ============================================================================
+
+// GetWinLossReport returns win rates and average deal sizes for a period by
+// owner, product category or customer segment, each compared with the same
+// cohort in the previous period of the same length.
+func (uc *reportUseCase) GetWinLossReport(ctx context.Context, tenantID uuid.UUID, req *dto.WinLossReportRequest) (*dto.WinLossReportResponse, error) {
+	period, groupBy, currency, err := resolveSalesPerformanceQuery(&dto.SalesPerformanceRequest{
+		From:     req.From,
+		To:       req.To,
+		GroupBy:  req.GroupBy,
+		Currency: req.Currency,
+	})
+	if err != nil {
+		return nil, err
+	}
+	switch groupBy {
+	case domain.ReportGroupByOwner, domain.ReportGroupByProductCategory, domain.ReportGroupByCustomerSegment:
+	default:
+		return nil, application.ErrValidationWithDetails("invalid group_by", map[string]interface{}{
+			"group_by": req.GroupBy,
+			"allowed":  []string{"owner", "product_category", "customer_segment"},
+		})
+	}
+
+	cacheKey := uc.reportCacheKey(tenantID, "win-loss", period, string(groupBy), currency)
+	var cached dto.WinLossReportResponse
+	if uc.getCachedReport(ctx, cacheKey, &cached) {
+		return &cached, nil
+	}
+
+	previousPeriod := period.Previous()
+	current, err := uc.reportRepo.GetSalesPerformance(ctx, tenantID, period, groupBy, currency)
+	if err != nil {
+		return nil, application.WrapError(application.ErrCodeInternal, "failed to get sales performance", err)
+	}
+	previous, err := uc.reportRepo.GetSalesPerformance(ctx, tenantID, previousPeriod, groupBy, currency)
+	if err != nil {
+		return nil, application.WrapError(application.ErrCodeInternal, "failed to get previous sales performance", err)
+	}
+
+	totals := &domain.WinLossCohort{Current: &domain.SalesPerformanceRow{}, Previous: &domain.SalesPerformanceRow{}}
+	cohorts := domain.BuildWinLossCohorts(current, previous)
+	resp := &dto.WinLossReportResponse{
+		Period: dto.DateRangeDTO{
+			StartDate: period.From,
+			EndDate:   period.To.AddDate(0, 0, -1),
+		},
+		PreviousPeriod: dto.DateRangeDTO{
+			StartDate: previousPeriod.From,
+			EndDate:   previousPeriod.To.AddDate(0, 0, -1),
+		},
+		GroupBy:     string(groupBy),
+		Currency:    currency,
+		Cohorts:     make([]*dto.WinLossCohortDTO, 0, len(cohorts)),
+		GeneratedAt: time.Now().UTC(),
+	}
+	for _, cohort := range cohorts {
+		totals.Current.Merge(cohort.Current)
+		totals.Previous.Merge(cohort.Previous)
+		group := uc.mapWinLossCohort(cohort, currency)
+		resp.Cohorts = append(resp.Cohorts, &group)
+	}
+	resp.Totals = uc.mapWinLossCohort(totals, currency)
+
+	if lastDay, err := uc.GetLastAggregatedDay(ctx); err == nil && lastDay != nil {
+		resp.DataAsOf = lastDay
+	}
+
+	uc.cacheReport(ctx, cacheKey, resp)
+
+	return resp, nil
+}
+
+// RunDailyAggregation recomputes the aggregates for a single day.
+func (uc *reportUseCase) RunDailyAggregation(ctx context.Context, day time.Time) (*dto.AggregationRunResponse, error) {
+	startedAt := time.Now().UTC()
+	day = domain.TruncateToDay(day)
+
+	rows, err := uc.reportRepo.RefreshDailyAggregates(ctx, day)
+	if err != nil {
+		return nil, application.WrapError(application.ErrCodeInternal, "failed to refresh daily aggregates", err)
+	}
+
+	run := &domain.AggregationRun{
+		Day:         day,
+		RowsWritten: rows,
+		StartedAt:   startedAt,
+		CompletedAt: time.Now().UTC(),
+	}
+	if err := uc.reportRepo.RecordAggregationRun(ctx, run); err != nil {
+		return nil, application.WrapError(application.ErrCodeInternal, "failed to record aggregation run", err)
+	}
+
+	uc.invalidateReportCache(ctx)
+
+	return &dto.AggregationRunResponse{
+		Days:        1,
+		RowsWritten: run.RowsWritten,
+		StartedAt:   run.StartedAt,
+		CompletedAt: run.CompletedAt,
+	}, nil
+}
+
+// RebuildAggregates recomputes the aggregates for every day in a range.
+func (uc *reportUseCase) RebuildAggregates(ctx context.Context, req *dto.RunAggregationRequest) (*dto.AggregationRunResponse, error) {
+	period, err := parseReportPeriod(req.From, req.To)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &dto.AggregationRunResponse{StartedAt: time.Now().UTC()}
+	for day := period.From; day.Before(period.To); day = day.AddDate(0, 0, 1) {
+		if err := ctx.Err(); err != nil {
+			return nil, application.WrapError(application.ErrCodeInternal, "aggregation rebuild cancelled", err)
+		}
+
+		run, err := uc.RunDailyAggregation(ctx, day)
+		if err != nil {
+			return nil, err
+		}
+		resp.Days++
+		resp.RowsWritten += run.RowsWritten
+	}
+	resp.CompletedAt = time.Now().UTC()
+
+	return resp, nil
+}
+
+// GetLastAggregatedDay returns the most recent day that has been aggregated.
+func (uc *reportUseCase) GetLastAggregatedDay(ctx context.Context) (*time.Time, error) {
+	run, err := uc.reportRepo.GetLastAggregationRun(ctx)
+	if err != nil {
+		return nil, application.WrapError(application.ErrCodeInternal, "failed to get last aggregation run", err)
+	}
+	if run == nil {
+		return nil, nil
+	}
+	return &run.Day, nil
+}
+
+// ============================================================================
+// Helper Methods
+// ============================================================================
+
+// resolveSalesPerformanceQuery validates a sales performance request and applies defaults.
+func resolveSalesPerformanceQuery(req *dto.SalesPerformanceRequest) (domain.ReportPeriod, domain.ReportGroupBy, string, error) {
+	period, err := parseReportPeriod(req.From, req.To)
+	if err != nil {
+		return domain.ReportPeriod{}, "", "", err
+	}
+
+	groupBy := domain.ReportGroupByOwner
+	if req.GroupBy != "" {
+		groupBy = domain.ReportGroupBy(req.GroupBy)
+	}
+	if !groupBy.IsValid() {
+		return domain.ReportPeriod{}, "", "", application.ErrValidationWithDetails("invalid group_by", map[string]interface{}{
+			"group_by": req.GroupBy,
+			"allowed":  domain.ValidReportGroupBys(),
+		})
+	}
+
+	currency := defaultReportCurrency
+	if req.Currency != "" {
+		currency = strings.ToUpper(req.Currency)
+	}
+	if !domain.IsSupportedCurrency(currency) {
+		return domain.ReportPeriod{}, "", "", application.ErrCurrencyInvalid(currency)
+	}
+
+	return period, groupBy, currency, nil
+}
+
+// parseReportPeriod parses an inclusive YYYY-MM-DD date range into a report period.
+func parseReportPeriod(from, to string) (domain.ReportPeriod, error) {
+	fromDate, err := time.Parse("2006-01-02", from)
+	if err != nil {
+		return domain.ReportPeriod{}, application.ErrValidationWithDetails("invalid from date", map[string]interface{}{
+			"from": from,
+		})
+	}
+	toDate, err := time.Parse("2006-01-02", to)
+	if err != nil {
+		return domain.ReportPeriod{}, application.ErrValidationWithDetails("invalid to date", map[string]interface{}{
+			"to": to,
+		})
+	}
+
+	period, err := domain.NewReportPeriod(fromDate, toDate)
+	if err != nil {
+		return domain.ReportPeriod{}, application.WrapError(application.ErrCodeValidation, "invalid report period", err)
+	}
+	return period, nil
+}
+
+func (uc *reportUseCase) mapPerformanceMetrics(row *domain.SalesPerformanceRow, currency string) dto.SalesPerformanceMetricsDTO {
+	revenue := domain.Money{Amount: row.WonRevenue, Currency: currency}
+	averageDealSize := domain.Money{Amount: row.AverageDealSize(), Currency: currency}
+
+	return dto.SalesPerformanceMetricsDTO{
+		NewLeads:             row.NewLeads,
+		ConvertedLeads:       row.ConvertedLeads,
+		ConversionRate:       row.ConversionRate(),
+		OpportunitiesCreated: row.OpportunitiesCreated,
+		OpportunitiesWon:     row.OpportunitiesWon,
+		OpportunitiesLost:    row.OpportunitiesLost,
+		WinRate:              row.WinRate(),
+		Revenue: dto.MoneyDTO{
+			Amount:   revenue.Amount,
+			Currency: revenue.Currency,
+			Display:  revenue.Format(),
+		},
+		AverageDealSize: dto.MoneyDTO{
+			Amount:   averageDealSize.Amount,
+			Currency: averageDealSize.Currency,
+			Display:  averageDealSize.Format(),
+		},
+	}
+}
+
+func (uc *reportUseCase) mapWinLossCohort(cohort *domain.WinLossCohort, currency string) dto.WinLossCohortDTO {
+	averageDealSizeChange := domain.Money{Amount: cohort.AverageDealSizeChange(), Currency: currency}
+
+	return dto.WinLossCohortDTO{
+		Key:      cohort.GroupKey,
+		Label:    cohort.GroupLabel,
+		Current:  uc.mapWinLossMetrics(cohort.Current, currency),
+		Previous: uc.mapWinLossMetrics(cohort.Previous, currency),
+		Change: dto.WinLossChangeDTO{
+			OpportunitiesWon: cohort.Current.OpportunitiesWon - cohort.Previous.OpportunitiesWon,
+			WinRate:          cohort.WinRateChange(),
+			AverageDealSize: dto.MoneyDTO{
+				Amount:   averageDealSizeChange.Amount,
+				Currency: averageDealSizeChange.Currency,
+				Display:  averageDealSizeChange.Format(),
+			},
+			AverageDealSizePercent: cohort.AverageDealSizeChangePercent(),
+		},
+	}
+}
+
+func (uc *reportUseCase) mapWinLossMetrics(row *domain.SalesPerformanceRow, currency string) dto.WinLossMetricsDTO {
+	metrics := uc.mapPerformanceMetrics(row, currency)
+
+	return dto.WinLossMetricsDTO{
+		OpportunitiesWon:  metrics.OpportunitiesWon,
+		OpportunitiesLost: metrics.OpportunitiesLost,
+		WinRate:           metrics.WinRate,
+		Revenue:           metrics.Revenue,
+		AverageDealSize:   metrics.AverageDealSize,
+	}
+}
+
+func (uc *reportUseCase) reportCacheKey(tenantID uuid.UUID, report string, period domain.ReportPeriod, parts ...string) string {
+	key := "report:" + tenantID.String() + ":" + report + ":" +
+		period.From.Format("2006-01-02") + ":" + period.To.Format("2006-01-02")
+	for _, part := range parts {
+		key += ":" + part
+	}
+	return key
+}
+
+// getCachedReport decodes a cached report into resp, reporting whether one was found.
+func (uc *reportUseCase) getCachedReport(ctx context.Context, key string, resp interface{}) bool {
+	if uc.cacheService == nil {
+		return false
+	}
+
+	data, err := uc.cacheService.Get(ctx, key)
+	if err != nil || data == nil {
+		return false
+	}
+
+	return json.Unmarshal(data, resp) == nil
+}
+
+func (uc *reportUseCase) cacheReport(ctx context.Context, key string, resp interface{}) {
+	if uc.cacheService == nil {
+		return
+	}
+
+	data, err := json.Marshal(resp)
+	if err != nil {
+		return
+	}
+	uc.cacheService.Set(ctx, key, data, reportCacheTTL)
+}
+
+func (uc *reportUseCase) invalidateReportCache(ctx context.Context) {
+	if uc.cacheService == nil {
+		return
+	}
+
+	uc.cacheService.DeletePattern(ctx, "report:*")
+}
@@ -0,0 +1,296 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/kilang-desa-murni/crm/internal/sales/application"
+	"github.com/kilang-desa-murni/crm/internal/sales/application/dto"
+	"github.com/kilang-desa-murni/crm/internal/sales/domain"
+)
+
+// ============================================================================
+// Mock Implementations for Report Tests
+// ============================================================================
+
+// MockReportRepository is a mock implementation of domain.ReportRepository.
+type MockReportRepository struct {
+	rows          []*domain.SalesPerformanceRow
+	previousRows  []*domain.SalesPerformanceRow
+	refreshedDays []time.Time
+	runs          []*domain.AggregationRun
+	lastGroupBy   domain.ReportGroupBy
+	lastCurrency  string
+	lastPeriod    domain.ReportPeriod
+	refreshErr    error
+	performErr    error
+}
+
+func (m *MockReportRepository) RefreshDailyAggregates(ctx context.Context, day time.Time) (int64, error) {
+	if m.refreshErr != nil {
+		return 0, m.refreshErr
+	}
+	m.refreshedDays = append(m.refreshedDays, day)
+	return 3, nil
+}
+
+func (m *MockReportRepository) GetSalesPerformance(ctx context.Context, tenantID uuid.UUID, period domain.ReportPeriod, groupBy domain.ReportGroupBy, currency string) ([]*domain.SalesPerformanceRow, error) {
+	if m.performErr != nil {
+		return nil, m.performErr
+	}
+	if m.previousRows != nil && period.To.Equal(m.lastPeriod.From) {
+		return m.previousRows, nil
+	}
+	m.lastPeriod = period
+	m.lastGroupBy = groupBy
+	m.lastCurrency = currency
+	return m.rows, nil
+}
+
+func (m *MockReportRepository) GetDailyAggregates(ctx context.Context, tenantID uuid.UUID, period domain.ReportPeriod, dimension domain.AggregateDimension) ([]*domain.DailySalesAggregate, error) {
+	return nil, nil
+}
+
+func (m *MockReportRepository) RecordAggregationRun(ctx context.Context, run *domain.AggregationRun) error {
+	m.runs = append(m.runs, run)
+	return nil
+}
+
+func (m *MockReportRepository) GetLastAggregationRun(ctx context.Context) (*domain.AggregationRun, error) {
+	if len(m.runs) == 0 {
+		return nil, nil
+	}
+	return m.runs[len(m.runs)-1], nil
+}
+
+// ============================================================================
+// Report Use Case Tests
+// ============================================================================
+
+func TestReportUseCase_GetSalesPerformance_Success(t *testing.T) {
+	repo := &MockReportRepository{
+		rows: []*domain.SalesPerformanceRow{
+			{GroupKey: "owner-1", GroupLabel: "Aminah", NewLeads: 10, ConvertedLeads: 4, OpportunitiesWon: 3, OpportunitiesLost: 1, WonRevenue: 300000},
+			{GroupKey: "owner-2", GroupLabel: "Farid", NewLeads: 10, ConvertedLeads: 1, OpportunitiesWon: 1, OpportunitiesLost: 3, WonRevenue: 50000},
+		},
+	}
+	uc := NewReportUseCase(repo, nil, nil, nil, nil, nil, nil, nil)
+
+	resp, err := uc.GetSalesPerformance(context.Background(), uuid.New(), &dto.SalesPerformanceRequest{
+		From: "2024-01-01",
+		To:   "2024-01-31",
+	})
+	if err != nil {
+		t.Fatalf("GetSalesPerformance() error = %v", err)
+	}
+
+	if repo.lastGroupBy != domain.ReportGroupByOwner {
+		t.Errorf("expected default group_by owner, got %s", repo.lastGroupBy)
+	}
+	if repo.lastCurrency != defaultReportCurrency {
+		t.Errorf("expected default currency %s, got %s", defaultReportCurrency, repo.lastCurrency)
+	}
+	if len(resp.Groups) != 2 {
+		t.Fatalf("expected 2 groups, got %d", len(resp.Groups))
+	}
+	if resp.Totals.NewLeads != 20 || resp.Totals.ConvertedLeads != 5 {
+		t.Errorf("unexpected lead totals: %+v", resp.Totals)
+	}
+	if resp.Totals.ConversionRate != 25 {
+		t.Errorf("expected conversion rate 25, got %v", resp.Totals.ConversionRate)
+	}
+	if resp.Totals.WinRate != 50 {
+		t.Errorf("expected win rate 50, got %v", resp.Totals.WinRate)
+	}
+	if resp.Totals.Revenue.Amount != 350000 {
+		t.Errorf("expected revenue 350000, got %d", resp.Totals.Revenue.Amount)
+	}
+	if !resp.Period.EndDate.Equal(time.Date(2024, 1, 31, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("expected inclusive end date, got %v", resp.Period.EndDate)
+	}
+}
+
+func TestReportUseCase_GetSalesPerformance_InvalidGroupBy(t *testing.T) {
+	uc := NewReportUseCase(&MockReportRepository{}, nil, nil, nil, nil, nil, nil, nil)
+
+	_, err := uc.GetSalesPerformance(context.Background(), uuid.New(), &dto.SalesPerformanceRequest{
+		From:    "2024-01-01",
+		To:      "2024-01-31",
+		GroupBy: "customer",
+	})
+	if !application.IsValidationError(err) {
+		t.Errorf("expected validation error, got %v", err)
+	}
+}
+
+func TestReportUseCase_GetSalesPerformance_InvalidPeriod(t *testing.T) {
+	uc := NewReportUseCase(&MockReportRepository{}, nil, nil, nil, nil, nil, nil, nil)
+
+	tests := []struct {
+		name string
+		from string
+		to   string
+	}{
+		{"bad from", "01-01-2024", "2024-01-31"},
+		{"bad to", "2024-01-01", "tomorrow"},
+		{"reversed", "2024-02-01", "2024-01-01"},
+		{"too long", "2022-01-01", "2024-01-01"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := uc.GetSalesPerformance(context.Background(), uuid.New(), &dto.SalesPerformanceRequest{
+				From: tt.from,
+				To:   tt.to,
+			})
+			if !application.IsValidationError(err) {
+				t.Errorf("expected validation error, got %v", err)
+			}
+		})
+	}
+}
+
+func TestReportUseCase_GetSalesPerformance_RepositoryError(t *testing.T) {
+	uc := NewReportUseCase(&MockReportRepository{performErr: errors.New("db down")}, nil, nil, nil, nil, nil, nil, nil)
+
+	_, err := uc.GetSalesPerformance(context.Background(), uuid.New(), &dto.SalesPerformanceRequest{
+		From: "2024-01-01",
+		To:   "2024-01-31",
+	})
+	if err == nil {
+		t.Fatal("expected error")
+	}
+}
+
+func TestReportUseCase_GetAttributionReport(t *testing.T) {
+	repo := &MockReportRepository{
+		rows: []*domain.SalesPerformanceRow{
+			{GroupKey: "raya-2024", GroupLabel: "raya-2024", NewLeads: 8, OpportunitiesWon: 2, WonRevenue: 200000},
+			{GroupKey: "none", GroupLabel: "none", NewLeads: 2, OpportunitiesWon: 1, WonRevenue: 50000},
+		},
+	}
+	uc := NewReportUseCase(repo, nil, nil, nil, nil, nil, nil, nil)
+
+	resp, err := uc.GetAttributionReport(context.Background(), uuid.New(), &dto.SalesPerformanceRequest{
+		From:    "2024-01-01",
+		To:      "2024-01-31",
+		GroupBy: "owner",
+	})
+	if err != nil {
+		t.Fatalf("GetAttributionReport() error = %v", err)
+	}
+
+	if repo.lastGroupBy != domain.ReportGroupByChannel {
+		t.Errorf("expected the channel grouping last, got %s", repo.lastGroupBy)
+	}
+	if len(resp.Campaigns) != 2 || len(resp.Channels) != 2 {
+		t.Fatalf("expected 2 campaigns and channels, got %d and %d", len(resp.Campaigns), len(resp.Channels))
+	}
+	if resp.Totals.Revenue.Amount != 250000 {
+		t.Errorf("expected revenue 250000, got %d", resp.Totals.Revenue.Amount)
+	}
+}
+
+func TestReportUseCase_GetWinLossReport(t *testing.T) {
+	repo := &MockReportRepository{
+		rows: []*domain.SalesPerformanceRow{
+			{GroupKey: "gold", GroupLabel: "gold", OpportunitiesWon: 3, OpportunitiesLost: 1, WonRevenue: 90000},
+			{GroupKey: "retail", GroupLabel: "retail", OpportunitiesCreated: 2},
+		},
+		previousRows: []*domain.SalesPerformanceRow{
+			{GroupKey: "gold", GroupLabel: "gold", OpportunitiesWon: 1, OpportunitiesLost: 1, WonRevenue: 20000},
+		},
+	}
+	uc := NewReportUseCase(repo, nil, nil, nil, nil, nil, nil, nil)
+
+	resp, err := uc.GetWinLossReport(context.Background(), uuid.New(), &dto.WinLossReportRequest{
+		From:    "2024-03-01",
+		To:      "2024-03-31",
+		GroupBy: "customer_segment",
+	})
+	if err != nil {
+		t.Fatalf("GetWinLossReport() error = %v", err)
+	}
+
+	if repo.lastGroupBy != domain.ReportGroupByCustomerSegment {
+		t.Errorf("expected group_by customer_segment, got %s", repo.lastGroupBy)
+	}
+	if !resp.PreviousPeriod.StartDate.Equal(time.Date(2024, 1, 30, 0, 0, 0, 0, time.UTC)) ||
+		!resp.PreviousPeriod.EndDate.Equal(time.Date(2024, 2, 29, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("unexpected previous period: %+v", resp.PreviousPeriod)
+	}
+	if len(resp.Cohorts) != 1 {
+		t.Fatalf("expected 1 cohort with closed opportunities, got %d", len(resp.Cohorts))
+	}
+
+	gold := resp.Cohorts[0]
+	if gold.Current.WinRate != 75 || gold.Previous.WinRate != 50 || gold.Change.WinRate != 25 {
+		t.Errorf("unexpected win rates: %+v", gold)
+	}
+	if gold.Change.AverageDealSize.Amount != 10000 || gold.Change.AverageDealSizePercent == nil || *gold.Change.AverageDealSizePercent != 50 {
+		t.Errorf("unexpected average deal size change: %+v", gold.Change)
+	}
+	if resp.Totals.Change.OpportunitiesWon != 2 {
+		t.Errorf("expected 2 more wins in total, got %d", resp.Totals.Change.OpportunitiesWon)
+	}
+}
+
+func TestReportUseCase_GetWinLossReport_InvalidGroupBy(t *testing.T) {
+	uc := NewReportUseCase(&MockReportRepository{}, nil, nil, nil, nil, nil, nil, nil)
+
+	_, err := uc.GetWinLossReport(context.Background(), uuid.New(), &dto.WinLossReportRequest{
+		From:    "2024-01-01",
+		To:      "2024-01-31",
+		GroupBy: "day",
+	})
+	if !application.IsValidationError(err) {
+		t.Errorf("expected validation error, got %v", err)
+	}
+}
+
+func TestReportUseCase_RunDailyAggregation(t *testing.T) {
+	repo := &MockReportRepository{}
+	uc := NewReportUseCase(repo, nil, nil, nil, nil, nil, nil, nil)
+
+	day := time.Date(2024, 3, 5, 17, 45, 0, 0, time.UTC)
+	resp, err := uc.RunDailyAggregation(context.Background(), day)
+	if err != nil {
+		t.Fatalf("RunDailyAggregation() error = %v", err)
+	}
+
+	if resp.RowsWritten != 3 {
+		t.Errorf("expected 3 rows written, got %d", resp.RowsWritten)
+	}
+	if len(repo.runs) != 1 || !repo.runs[0].Day.Equal(time.Date(2024, 3, 5, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("expected run recorded for truncated day, got %+v", repo.runs)
+	}
+
+	lastDay, err := uc.GetLastAggregatedDay(context.Background())
+	if err != nil || lastDay == nil || !lastDay.Equal(repo.runs[0].Day) {
+		t.Errorf("GetLastAggregatedDay() = %v, %v", lastDay, err)
+	}
+}
+
+func TestReportUseCase_RebuildAggregates(t *testing.T) {
+	repo := &MockReportRepository{}
+	uc := NewReportUseCase(repo, nil, nil, nil, nil, nil, nil, nil)
+
+	resp, err := uc.RebuildAggregates(context.Background(), &dto.RunAggregationRequest{
+		From: "2024-03-01",
+		To:   "2024-03-07",
+	})
+	if err != nil {
+		t.Fatalf("RebuildAggregates() error = %v", err)
+	}
+
+	if resp.Days != 7 || len(repo.refreshedDays) != 7 {
+		t.Errorf("expected 7 days rebuilt, got %d (%d refreshed)", resp.Days, len(repo.refreshedDays))
+	}
+	if resp.RowsWritten != 21 {
+		t.Errorf("expected 21 rows written, got %d", resp.RowsWritten)
+	}
+}
@@ -0,0 +1,476 @@
+package usecase
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/kilang-desa-murni/crm/internal/sales/application"
+	"github.com/kilang-desa-murni/crm/internal/sales/application/dto"
+	"github.com/kilang-desa-murni/crm/internal/sales/application/ports"
+	"github.com/kilang-desa-murni/crm/internal/sales/domain"
+)
+
+const (
+	// retentionBatchSize is how many records are archived to one file.
+	retentionBatchSize = 500
+
+	// retentionMaxBatchesPerPolicy bounds the work one policy does per run,
+	// so a tenant's first run cannot hold up the others. The rest of its
+	// backlog is picked up by the next runs.
+	retentionMaxBatchesPerPolicy = 20
+)
+
+// ============================================================================
+// Data Retention Use Case Interface
+// ============================================================================
+
+// RetentionUseCase defines the interface for data retention operations.
+type RetentionUseCase interface {
+	// Policies
+	ListPolicies(ctx context.Context, tenantID uuid.UUID) (*dto.RetentionPolicyListResponse, error)
+	SetPolicy(ctx context.Context, tenantID, userID uuid.UUID, entityType string, req *dto.SetRetentionPolicyRequest) (*dto.RetentionPolicyResponse, error)
+	DeletePolicy(ctx context.Context, tenantID uuid.UUID, entityType string) error
+
+	// Archive
+	ListArchived(ctx context.Context, tenantID uuid.UUID, req *dto.ListArchivedRecordsRequest) (*dto.ArchivedRecordListResponse, error)
+	Restore(ctx context.Context, tenantID, userID, recordID uuid.UUID) (*dto.ArchivedRecordResponse, error)
+
+	// ApplyPolicies archives or purges the expired records of every tenant
+	// with an enabled policy.
+	ApplyPolicies(ctx context.Context) (*dto.RetentionRunResponse, error)
+}
+
+// Transactor runs a function in a database transaction.
+type Transactor interface {
+	WithTransaction(ctx context.Context, fn func(ctx context.Context) error) error
+}
+
+// ============================================================================
+// Data Retention Use Case Implementation
+// ============================================================================
+
+// retentionUseCase implements RetentionUseCase.
+type retentionUseCase struct {
+	policyRepo     domain.RetentionPolicyRepository
+	archiveRepo    domain.ArchivedRecordRepository
+	store          domain.RetentionStore
+	tx             Transactor
+	archiveStorage ports.FileStorageService
+}
+
+// NewRetentionUseCase creates a new data retention use case. Archived records
+// are written to archiveStorage as gzipped JSON lines, one file per batch.
+func NewRetentionUseCase(
+	policyRepo domain.RetentionPolicyRepository,
+	archiveRepo domain.ArchivedRecordRepository,
+	store domain.RetentionStore,
+	tx Transactor,
+	archiveStorage ports.FileStorageService,
+) RetentionUseCase {
+	return &retentionUseCase{
+		policyRepo:     policyRepo,
+		archiveRepo:    archiveRepo,
+		store:          store,
+		tx:             tx,
+		archiveStorage: archiveStorage,
+	}
+}
+
+// ============================================================================
+// Policies
+// ============================================================================
+
+// ListPolicies lists the tenant's retention policies.
+func (uc *retentionUseCase) ListPolicies(ctx context.Context, tenantID uuid.UUID) (*dto.RetentionPolicyListResponse, error) {
+	policies, err := uc.policyRepo.List(ctx, tenantID)
+	if err != nil {
+		return nil, application.WrapError(application.ErrCodeInternal, "failed to list retention policies", err)
+	}
+
+	responses := make([]*dto.RetentionPolicyResponse, len(policies))
+	for i, policy := range policies {
+		responses[i] = mapRetentionPolicyToResponse(policy)
+	}
+	return &dto.RetentionPolicyListResponse{Policies: responses}, nil
+}
+
+// SetPolicy creates or replaces the tenant's policy for an entity type.
+func (uc *retentionUseCase) SetPolicy(ctx context.Context, tenantID, userID uuid.UUID, entityType string, req *dto.SetRetentionPolicyRequest) (*dto.RetentionPolicyResponse, error) {
+	entity := domain.RetentionEntityType(entityType)
+	if !entity.IsValid() {
+		return nil, application.ErrValidationWithDetails("invalid entity type", map[string]interface{}{
+			"entity_type": entityType,
+		})
+	}
+
+	enabled := true
+	if req.Enabled != nil {
+		enabled = *req.Enabled
+	}
+
+	policy, err := uc.policyRepo.Get(ctx, tenantID, entity)
+	if err != nil {
+		return nil, application.WrapError(application.ErrCodeInternal, "failed to get retention policy", err)
+	}
+	if policy == nil {
+		policy, err = domain.NewRetentionPolicy(tenantID, entity, domain.RetentionAction(req.Action), req.AfterDays, &userID)
+		if err == nil {
+			policy.Enabled = enabled
+		}
+	} else {
+		err = policy.Update(domain.RetentionAction(req.Action), req.AfterDays, enabled, &userID)
+	}
+	if err != nil {
+		return nil, mapRetentionError(err)
+	}
+
+	if err := uc.policyRepo.Upsert(ctx, policy); err != nil {
+		return nil, application.WrapError(application.ErrCodeInternal, "failed to save retention policy", err)
+	}
+
+	return mapRetentionPolicyToResponse(policy), nil
+}
+
+// DeletePolicy deletes the tenant's policy for an entity type. Records
+// already archived stay archived.
+func (uc *retentionUseCase) DeletePolicy(ctx context.Context, tenantID uuid.UUID, entityType string) error {
+	if err := uc.policyRepo.Delete(ctx, tenantID, domain.RetentionEntityType(entityType)); err != nil {
+		if errors.Is(err, domain.ErrRetentionPolicyNotFound) {
+			return application.ErrRetentionPolicyNotFound(entityType)
+		}
+		return application.WrapError(application.ErrCodeInternal, "failed to delete retention policy", err)
+	}
+	return nil
+}
+
+// ============================================================================
+// Archive
+// ============================================================================
+
+// ListArchived lists the tenant's archived records, newest first.
+func (uc *retentionUseCase) ListArchived(ctx context.Context, tenantID uuid.UUID, req *dto.ListArchivedRecordsRequest) (*dto.ArchivedRecordListResponse, error) {
+	filter := domain.ArchivedRecordFilter{IncludeRestored: req.IncludeRestored}
+	if req.EntityType != "" {
+		entity := domain.RetentionEntityType(req.EntityType)
+		if !entity.IsValid() {
+			return nil, application.ErrValidationWithDetails("invalid entity_type", map[string]interface{}{
+				"entity_type": req.EntityType,
+			})
+		}
+		filter.EntityType = &entity
+	}
+	if req.EntityID != "" {
+		entityID, err := uuid.Parse(req.EntityID)
+		if err != nil {
+			return nil, application.ErrValidation("invalid entity_id")
+		}
+		filter.EntityID = &entityID
+	}
+
+	page := req.Page
+	if page < 1 {
+		page = 1
+	}
+	pageSize := req.PageSize
+	if pageSize < 1 {
+		pageSize = 20
+	}
+
+	records, total, err := uc.archiveRepo.List(ctx, tenantID, filter, domain.ListOptions{
+		Page:     page,
+		PageSize: pageSize,
+	})
+	if err != nil {
+		return nil, application.WrapError(application.ErrCodeInternal, "failed to list archived records", err)
+	}
+
+	responses := make([]*dto.ArchivedRecordResponse, len(records))
+	for i, record := range records {
+		responses[i] = mapArchivedRecordToResponse(record)
+	}
+
+	return &dto.ArchivedRecordListResponse{
+		Records:    responses,
+		Pagination: dto.NewPaginationResponse(page, pageSize, total),
+	}, nil
+}
+
+// Restore reads an archived record back from cold storage and inserts it
+// unchanged.
+func (uc *retentionUseCase) Restore(ctx context.Context, tenantID, userID, recordID uuid.UUID) (*dto.ArchivedRecordResponse, error) {
+	record, err := uc.archiveRepo.GetByID(ctx, tenantID, recordID)
+	if err != nil {
+		if errors.Is(err, domain.ErrArchivedRecordNotFound) {
+			return nil, application.ErrArchivedRecordNotFound(recordID)
+		}
+		return nil, application.WrapError(application.ErrCodeInternal, "failed to get archived record", err)
+	}
+	if err := record.MarkRestored(userID); err != nil {
+		return nil, application.ErrArchivedRecordRestored(recordID)
+	}
+
+	content, err := uc.archiveStorage.Download(ctx, tenantID, record.StorageKey)
+	if err != nil {
+		return nil, application.WrapError(application.ErrCodeServiceUnavailable, "failed to read archive", err)
+	}
+	data, err := findArchivedRow(content, record.EntityID)
+	if err != nil {
+		return nil, application.WrapError(application.ErrCodeInternal, "failed to read archive", err)
+	}
+
+	err = uc.tx.WithTransaction(ctx, func(txCtx context.Context) error {
+		if err := uc.store.Restore(txCtx, tenantID, record.EntityType, data); err != nil {
+			return err
+		}
+		return uc.archiveRepo.MarkRestored(txCtx, record)
+	})
+	if err != nil {
+		if errors.Is(err, domain.ErrArchivedRecordRestored) {
+			return nil, application.ErrArchivedRecordRestored(recordID)
+		}
+		if errors.Is(err, domain.ErrArchivedRecordNotFound) {
+			return nil, application.ErrArchivedRecordNotFound(recordID)
+		}
+		return nil, application.WrapError(application.ErrCodeInternal, "failed to restore archived record", err)
+	}
+
+	return mapArchivedRecordToResponse(record), nil
+}
+
+// ============================================================================
+// Applying Policies
+// ============================================================================
+
+// ApplyPolicies applies the enabled policy of every tenant. A failing policy
+// does not stop the others; the first error is returned with the totals.
+func (uc *retentionUseCase) ApplyPolicies(ctx context.Context) (*dto.RetentionRunResponse, error) {
+	policies, err := uc.policyRepo.ListEnabled(ctx)
+	if err != nil {
+		return nil, application.WrapError(application.ErrCodeInternal, "failed to list retention policies", err)
+	}
+
+	resp := &dto.RetentionRunResponse{}
+	var firstErr error
+
+	for _, policy := range policies {
+		if err := ctx.Err(); err != nil {
+			return resp, err
+		}
+
+		count, err := uc.applyPolicy(ctx, policy)
+		if policy.Action == domain.RetentionActionPurge {
+			resp.Purged += count
+		} else {
+			resp.Archived += count
+		}
+		if err != nil {
+			if firstErr == nil {
+				firstErr = err
+			}
+			continue
+		}
+
+		if err := uc.policyRepo.MarkRun(ctx, policy.TenantID, policy.EntityType, time.Now().UTC()); err != nil && firstErr == nil {
+			firstErr = application.WrapError(application.ErrCodeInternal, "failed to mark retention policy run", err)
+		}
+		resp.PoliciesApplied++
+	}
+
+	return resp, firstErr
+}
+
+// applyPolicy archives or purges the expired records of one policy in
+// batches and returns how many were removed.
+func (uc *retentionUseCase) applyPolicy(ctx context.Context, policy *domain.RetentionPolicy) (int64, error) {
+	cutoff := policy.Cutoff(time.Now().UTC())
+	var removed int64
+
+	for batch := 0; batch < retentionMaxBatchesPerPolicy; batch++ {
+		records, err := uc.store.FindExpired(ctx, policy.TenantID, policy.EntityType, cutoff, retentionBatchSize)
+		if err != nil {
+			return removed, application.WrapError(application.ErrCodeInternal, "failed to find expired records", err)
+		}
+		if len(records) == 0 {
+			return removed, nil
+		}
+
+		var count int64
+		if policy.Action == domain.RetentionActionPurge {
+			count, err = uc.purge(ctx, policy, records)
+		} else {
+			count, err = uc.archive(ctx, policy, records)
+		}
+		removed += count
+		if err != nil {
+			return removed, err
+		}
+		if len(records) < retentionBatchSize {
+			return removed, nil
+		}
+	}
+
+	return removed, nil
+}
+
+// purge deletes a batch of expired records.
+func (uc *retentionUseCase) purge(ctx context.Context, policy *domain.RetentionPolicy, records []domain.RetainedRecord) (int64, error) {
+	deleted, err := uc.store.Delete(ctx, policy.TenantID, policy.EntityType, retainedRecordIDs(records))
+	if err != nil {
+		return 0, application.WrapError(application.ErrCodeInternal, "failed to purge expired records", err)
+	}
+	return deleted, nil
+}
+
+// archive writes a batch of expired records to cold storage, then indexes
+// and deletes them in one transaction. If the transaction fails the archive
+// file is left behind unreferenced, and the records are archived again by
+// the next run.
+func (uc *retentionUseCase) archive(ctx context.Context, policy *domain.RetentionPolicy, records []domain.RetainedRecord) (int64, error) {
+	now := time.Now().UTC()
+	storageKey := fmt.Sprintf("retention/%s/%s/%s.jsonl.gz", policy.EntityType, now.Format("2006-01-02"), uuid.New())
+
+	content, err := encodeArchive(records)
+	if err != nil {
+		return 0, application.WrapError(application.ErrCodeInternal, "failed to encode archive", err)
+	}
+	if err := uc.archiveStorage.Store(ctx, policy.TenantID, storageKey, content); err != nil {
+		return 0, application.WrapError(application.ErrCodeServiceUnavailable, "failed to write archive", err)
+	}
+
+	archived := make([]*domain.ArchivedRecord, len(records))
+	for i, record := range records {
+		archived[i] = domain.NewArchivedRecord(policy.TenantID, policy.EntityType, record.ID, storageKey, now)
+	}
+
+	var deleted int64
+	err = uc.tx.WithTransaction(ctx, func(txCtx context.Context) error {
+		if err := uc.archiveRepo.CreateBatch(txCtx, archived); err != nil {
+			return err
+		}
+		deleted, err = uc.store.Delete(txCtx, policy.TenantID, policy.EntityType, retainedRecordIDs(records))
+		return err
+	})
+	if err != nil {
+		return 0, application.WrapError(application.ErrCodeInternal, "failed to archive expired records", err)
+	}
+	return deleted, nil
+}
+
+// ============================================================================
+// Archive Files
+// ============================================================================
+
+// encodeArchive writes records as gzipped JSON lines.
+func encodeArchive(records []domain.RetainedRecord) ([]byte, error) {
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	for _, record := range records {
+		if _, err := zw.Write(record.Data); err != nil {
+			return nil, err
+		}
+		if _, err := zw.Write([]byte("\n")); err != nil {
+			return nil, err
+		}
+	}
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// findArchivedRow returns the row of an entity from an archive file.
+func findArchivedRow(content []byte, entityID uuid.UUID) ([]byte, error) {
+	zr, err := gzip.NewReader(bytes.NewReader(content))
+	if err != nil {
+		return nil, err
+	}
+	defer zr.Close()
+
+	reader := bufio.NewReader(zr)
+	for {
+		line, err := reader.ReadBytes('\n')
+		if len(bytes.TrimSpace(line)) > 0 {
+			var row struct {
+				ID uuid.UUID `json:"id"`
+			}
+			if jsonErr := json.Unmarshal(line, &row); jsonErr != nil {
+				return nil, jsonErr
+			}
+			if row.ID == entityID {
+				return bytes.TrimSpace(line), nil
+			}
+		}
+		if err == io.EOF {
+			return nil, fmt.Errorf("entity %s is not in the archive", entityID)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+}
+
+func retainedRecordIDs(records []domain.RetainedRecord) []uuid.UUID {
+	ids := make([]uuid.UUID, len(records))
+	for i, record := range records {
+		ids[i] = record.ID
+	}
+	return ids
+}
+
+// ============================================================================
+// Mapping Helpers
+// ============================================================================
+
+// mapRetentionError converts domain validation errors into application errors.
+func mapRetentionError(err error) error {
+	switch {
+	case errors.Is(err, domain.ErrInvalidRetentionEntity),
+		errors.Is(err, domain.ErrInvalidRetentionAction):
+		return application.ErrValidation(err.Error())
+	case errors.Is(err, domain.ErrRetentionPeriodTooShort):
+		return application.ErrValidationWithDetails(err.Error(), map[string]interface{}{
+			"min_days": domain.MinRetentionDays,
+		})
+	}
+	return application.WrapError(application.ErrCodeInternal, "failed to set retention policy", err)
+}
+
+func mapRetentionPolicyToResponse(policy *domain.RetentionPolicy) *dto.RetentionPolicyResponse {
+	resp := &dto.RetentionPolicyResponse{
+		EntityType: string(policy.EntityType),
+		Action:     string(policy.Action),
+		AfterDays:  policy.AfterDays,
+		Enabled:    policy.Enabled,
+		LastRunAt:  policy.LastRunAt,
+		UpdatedAt:  policy.UpdatedAt,
+	}
+	if policy.UpdatedBy != nil {
+		updatedBy := policy.UpdatedBy.String()
+		resp.UpdatedBy = &updatedBy
+	}
+	return resp
+}
+
+func mapArchivedRecordToResponse(record *domain.ArchivedRecord) *dto.ArchivedRecordResponse {
+	resp := &dto.ArchivedRecordResponse{
+		ID:         record.ID.String(),
+		EntityType: string(record.EntityType),
+		EntityID:   record.EntityID.String(),
+		ArchivedAt: record.ArchivedAt,
+		RestoredAt: record.RestoredAt,
+	}
+	if record.RestoredBy != nil {
+		restoredBy := record.RestoredBy.String()
+		resp.RestoredBy = &restoredBy
+	}
+	return resp
+}
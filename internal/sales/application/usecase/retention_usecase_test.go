@@ -0,0 +1,322 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/kilang-desa-murni/crm/internal/sales/application"
+	"github.com/kilang-desa-murni/crm/internal/sales/application/dto"
+	"github.com/kilang-desa-murni/crm/internal/sales/domain"
+)
+
+// ============================================================================
+// Mock Implementations for Data Retention Tests
+// ============================================================================
+
+type retentionPolicyKey struct {
+	tenantID   uuid.UUID
+	entityType domain.RetentionEntityType
+}
+
+// MockRetentionPolicyRepository is a mock implementation of domain.RetentionPolicyRepository.
+type MockRetentionPolicyRepository struct {
+	policies map[retentionPolicyKey]*domain.RetentionPolicy
+}
+
+func NewMockRetentionPolicyRepository() *MockRetentionPolicyRepository {
+	return &MockRetentionPolicyRepository{policies: make(map[retentionPolicyKey]*domain.RetentionPolicy)}
+}
+
+func (m *MockRetentionPolicyRepository) List(ctx context.Context, tenantID uuid.UUID) ([]*domain.RetentionPolicy, error) {
+	var policies []*domain.RetentionPolicy
+	for key, policy := range m.policies {
+		if key.tenantID == tenantID {
+			policies = append(policies, policy)
+		}
+	}
+	return policies, nil
+}
+
+func (m *MockRetentionPolicyRepository) Get(ctx context.Context, tenantID uuid.UUID, entityType domain.RetentionEntityType) (*domain.RetentionPolicy, error) {
+	return m.policies[retentionPolicyKey{tenantID, entityType}], nil
+}
+
+func (m *MockRetentionPolicyRepository) ListEnabled(ctx context.Context) ([]*domain.RetentionPolicy, error) {
+	var policies []*domain.RetentionPolicy
+	for _, policy := range m.policies {
+		if policy.Enabled {
+			policies = append(policies, policy)
+		}
+	}
+	return policies, nil
+}
+
+func (m *MockRetentionPolicyRepository) Upsert(ctx context.Context, policy *domain.RetentionPolicy) error {
+	m.policies[retentionPolicyKey{policy.TenantID, policy.EntityType}] = policy
+	return nil
+}
+
+func (m *MockRetentionPolicyRepository) Delete(ctx context.Context, tenantID uuid.UUID, entityType domain.RetentionEntityType) error {
+	key := retentionPolicyKey{tenantID, entityType}
+	if _, ok := m.policies[key]; !ok {
+		return domain.ErrRetentionPolicyNotFound
+	}
+	delete(m.policies, key)
+	return nil
+}
+
+func (m *MockRetentionPolicyRepository) MarkRun(ctx context.Context, tenantID uuid.UUID, entityType domain.RetentionEntityType, at time.Time) error {
+	if policy, ok := m.policies[retentionPolicyKey{tenantID, entityType}]; ok {
+		policy.LastRunAt = &at
+	}
+	return nil
+}
+
+// MockArchivedRecordRepository is a mock implementation of domain.ArchivedRecordRepository.
+type MockArchivedRecordRepository struct {
+	records []*domain.ArchivedRecord
+}
+
+func (m *MockArchivedRecordRepository) CreateBatch(ctx context.Context, records []*domain.ArchivedRecord) error {
+	m.records = append(m.records, records...)
+	return nil
+}
+
+func (m *MockArchivedRecordRepository) GetByID(ctx context.Context, tenantID, recordID uuid.UUID) (*domain.ArchivedRecord, error) {
+	for _, record := range m.records {
+		if record.TenantID == tenantID && record.ID == recordID {
+			return record, nil
+		}
+	}
+	return nil, domain.ErrArchivedRecordNotFound
+}
+
+func (m *MockArchivedRecordRepository) List(ctx context.Context, tenantID uuid.UUID, filter domain.ArchivedRecordFilter, opts domain.ListOptions) ([]*domain.ArchivedRecord, int64, error) {
+	var records []*domain.ArchivedRecord
+	for _, record := range m.records {
+		if record.TenantID == tenantID && (filter.IncludeRestored || !record.IsRestored()) {
+			records = append(records, record)
+		}
+	}
+	return records, int64(len(records)), nil
+}
+
+func (m *MockArchivedRecordRepository) MarkRestored(ctx context.Context, record *domain.ArchivedRecord) error {
+	return nil
+}
+
+// MockRetentionStore is a mock implementation of domain.RetentionStore
+// holding rows by ID with the time they were last changed.
+type MockRetentionStore struct {
+	rows      map[uuid.UUID][]byte
+	changedAt map[uuid.UUID]time.Time
+}
+
+func NewMockRetentionStore() *MockRetentionStore {
+	return &MockRetentionStore{rows: make(map[uuid.UUID][]byte), changedAt: make(map[uuid.UUID]time.Time)}
+}
+
+func (m *MockRetentionStore) add(tenantID uuid.UUID, changedAt time.Time) uuid.UUID {
+	id := uuid.New()
+	m.rows[id] = []byte(fmt.Sprintf(`{"id":"%s","tenant_id":"%s","first_name":"Siti"}`, id, tenantID))
+	m.changedAt[id] = changedAt
+	return id
+}
+
+func (m *MockRetentionStore) FindExpired(ctx context.Context, tenantID uuid.UUID, entityType domain.RetentionEntityType, cutoff time.Time, limit int) ([]domain.RetainedRecord, error) {
+	var records []domain.RetainedRecord
+	for id, changedAt := range m.changedAt {
+		if changedAt.Before(cutoff) && len(records) < limit {
+			records = append(records, domain.RetainedRecord{ID: id, Data: m.rows[id]})
+		}
+	}
+	return records, nil
+}
+
+func (m *MockRetentionStore) Delete(ctx context.Context, tenantID uuid.UUID, entityType domain.RetentionEntityType, ids []uuid.UUID) (int64, error) {
+	for _, id := range ids {
+		delete(m.rows, id)
+		delete(m.changedAt, id)
+	}
+	return int64(len(ids)), nil
+}
+
+func (m *MockRetentionStore) Restore(ctx context.Context, tenantID uuid.UUID, entityType domain.RetentionEntityType, data []byte) error {
+	id := uuid.New()
+	m.rows[id] = data
+	m.changedAt[id] = time.Now()
+	return nil
+}
+
+// MockTransactor runs functions without a transaction.
+type MockTransactor struct{}
+
+func (MockTransactor) WithTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
+
+type retentionFixture struct {
+	uc          RetentionUseCase
+	policyRepo  *MockRetentionPolicyRepository
+	archiveRepo *MockArchivedRecordRepository
+	store       *MockRetentionStore
+	storage     *MockFileStorageService
+}
+
+func newRetentionFixture() *retentionFixture {
+	f := &retentionFixture{
+		policyRepo:  NewMockRetentionPolicyRepository(),
+		archiveRepo: &MockArchivedRecordRepository{},
+		store:       NewMockRetentionStore(),
+		storage:     NewMockFileStorageService(),
+	}
+	f.uc = NewRetentionUseCase(f.policyRepo, f.archiveRepo, f.store, MockTransactor{}, f.storage)
+	return f
+}
+
+// ============================================================================
+// Data Retention Use Case Tests
+// ============================================================================
+
+func TestRetentionUseCase_SetPolicy(t *testing.T) {
+	f := newRetentionFixture()
+	tenantID, userID := uuid.New(), uuid.New()
+
+	resp, err := f.uc.SetPolicy(context.Background(), tenantID, userID, "lead", &dto.SetRetentionPolicyRequest{
+		Action:    "archive",
+		AfterDays: 730,
+	})
+	if err != nil {
+		t.Fatalf("SetPolicy() error = %v", err)
+	}
+	if !resp.Enabled || resp.AfterDays != 730 || resp.Action != "archive" {
+		t.Errorf("unexpected policy %+v", resp)
+	}
+
+	disabled := false
+	resp, err = f.uc.SetPolicy(context.Background(), tenantID, userID, "lead", &dto.SetRetentionPolicyRequest{
+		Action:    "purge",
+		AfterDays: 365,
+		Enabled:   &disabled,
+	})
+	if err != nil {
+		t.Fatalf("SetPolicy() update error = %v", err)
+	}
+	if resp.Enabled || resp.Action != "purge" {
+		t.Errorf("expected the policy replaced, got %+v", resp)
+	}
+	if len(f.policyRepo.policies) != 1 {
+		t.Errorf("expected one policy, got %d", len(f.policyRepo.policies))
+	}
+}
+
+func TestRetentionUseCase_SetPolicy_Invalid(t *testing.T) {
+	tests := []struct {
+		name       string
+		entityType string
+		req        *dto.SetRetentionPolicyRequest
+	}{
+		{"unknown entity", "invoice", &dto.SetRetentionPolicyRequest{Action: "archive", AfterDays: 730}},
+		{"unknown action", "lead", &dto.SetRetentionPolicyRequest{Action: "shred", AfterDays: 730}},
+		{"too short", "lead", &dto.SetRetentionPolicyRequest{Action: "purge", AfterDays: 7}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newRetentionFixture()
+			_, err := f.uc.SetPolicy(context.Background(), uuid.New(), uuid.New(), tt.entityType, tt.req)
+			if !application.IsValidationError(err) {
+				t.Errorf("expected a validation error, got %v", err)
+			}
+		})
+	}
+}
+
+func TestRetentionUseCase_ApplyPolicies_ArchivesAndRestores(t *testing.T) {
+	f := newRetentionFixture()
+	ctx := context.Background()
+	tenantID, userID := uuid.New(), uuid.New()
+
+	if _, err := f.uc.SetPolicy(ctx, tenantID, userID, "lead", &dto.SetRetentionPolicyRequest{Action: "archive", AfterDays: 730}); err != nil {
+		t.Fatalf("SetPolicy() error = %v", err)
+	}
+	oldID := f.store.add(tenantID, time.Now().AddDate(-3, 0, 0))
+	recentID := f.store.add(tenantID, time.Now().AddDate(0, -1, 0))
+
+	result, err := f.uc.ApplyPolicies(ctx)
+	if err != nil {
+		t.Fatalf("ApplyPolicies() error = %v", err)
+	}
+	if result.Archived != 1 || result.PoliciesApplied != 1 {
+		t.Errorf("expected one record archived by one policy, got %+v", result)
+	}
+	if _, ok := f.store.rows[oldID]; ok {
+		t.Error("expected the old lead removed")
+	}
+	if _, ok := f.store.rows[recentID]; !ok {
+		t.Error("expected the recent lead kept")
+	}
+	if len(f.archiveRepo.records) != 1 || f.archiveRepo.records[0].EntityID != oldID {
+		t.Fatalf("expected the old lead indexed, got %+v", f.archiveRepo.records)
+	}
+	if len(f.storage.files) != 1 {
+		t.Errorf("expected one archive file, got %d", len(f.storage.files))
+	}
+	if f.policyRepo.policies[retentionPolicyKey{tenantID, domain.RetentionEntityLead}].LastRunAt == nil {
+		t.Error("expected the policy run recorded")
+	}
+
+	record := f.archiveRepo.records[0]
+	restored, err := f.uc.Restore(ctx, tenantID, userID, record.ID)
+	if err != nil {
+		t.Fatalf("Restore() error = %v", err)
+	}
+	if restored.RestoredAt == nil {
+		t.Error("expected the record marked restored")
+	}
+	if len(f.store.rows) != 2 {
+		t.Errorf("expected the lead inserted back, got %d rows", len(f.store.rows))
+	}
+
+	_, err = f.uc.Restore(ctx, tenantID, userID, record.ID)
+	var appErr *application.AppError
+	if !errors.As(err, &appErr) || appErr.Code != application.ErrCodeArchivedRecordRestored {
+		t.Errorf("expected %s restoring twice, got %v", application.ErrCodeArchivedRecordRestored, err)
+	}
+}
+
+func TestRetentionUseCase_ApplyPolicies_Purges(t *testing.T) {
+	f := newRetentionFixture()
+	ctx := context.Background()
+	tenantID := uuid.New()
+
+	if _, err := f.uc.SetPolicy(ctx, tenantID, uuid.New(), "lead", &dto.SetRetentionPolicyRequest{Action: "purge", AfterDays: 90}); err != nil {
+		t.Fatalf("SetPolicy() error = %v", err)
+	}
+	f.store.add(tenantID, time.Now().AddDate(0, -6, 0))
+
+	result, err := f.uc.ApplyPolicies(ctx)
+	if err != nil {
+		t.Fatalf("ApplyPolicies() error = %v", err)
+	}
+	if result.Purged != 1 || result.Archived != 0 {
+		t.Errorf("expected one record purged, got %+v", result)
+	}
+	if len(f.archiveRepo.records) != 0 || len(f.storage.files) != 0 {
+		t.Error("expected nothing archived when purging")
+	}
+}
+
+func TestRetentionUseCase_Restore_NotFound(t *testing.T) {
+	f := newRetentionFixture()
+
+	_, err := f.uc.Restore(context.Background(), uuid.New(), uuid.New(), uuid.New())
+	if !application.IsNotFoundError(err) {
+		t.Errorf("expected a not found error, got %v", err)
+	}
+}
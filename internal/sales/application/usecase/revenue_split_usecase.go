@@ -0,0 +1,269 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/google/uuid"
+
+	"github.com/kilang-desa-murni/crm/internal/sales/application"
+	"github.com/kilang-desa-murni/crm/internal/sales/application/dto"
+	"github.com/kilang-desa-murni/crm/internal/sales/application/ports"
+	"github.com/kilang-desa-murni/crm/internal/sales/domain"
+)
+
+// ============================================================================
+// Revenue Split Use Case Interface
+// ============================================================================
+
+// RevenueSplitUseCase defines the interface for sharing the revenue of an
+// opportunity among the users who worked it. Split revenue is credited to
+// each user in rep reports and forecasts.
+type RevenueSplitUseCase interface {
+	// Get returns how the revenue of an opportunity is credited.
+	Get(ctx context.Context, tenantID, opportunityID uuid.UUID) (*dto.RevenueSplitsResponse, error)
+
+	// Set replaces the splits of an opportunity. Only its owner and
+	// managers may change them.
+	Set(ctx context.Context, tenantID, userID, opportunityID uuid.UUID, manager bool, req *dto.SetRevenueSplitsRequest) (*dto.RevenueSplitsResponse, error)
+
+	// Clear removes the splits of an opportunity, crediting its owner in
+	// full again.
+	Clear(ctx context.Context, tenantID, userID, opportunityID uuid.UUID, manager bool) (*dto.RevenueSplitsResponse, error)
+
+	// ListHistory lists the changes of an opportunity's splits, newest first.
+	ListHistory(ctx context.Context, tenantID, opportunityID uuid.UUID, req *dto.ListRevenueSplitHistoryRequest) (*dto.RevenueSplitHistoryResponse, error)
+}
+
+// ============================================================================
+// Revenue Split Use Case Implementation
+// ============================================================================
+
+// revenueSplitUseCase implements RevenueSplitUseCase.
+type revenueSplitUseCase struct {
+	revenueSplitRepo domain.RevenueSplitRepository
+	opportunityRepo  domain.OpportunityRepository
+	userService      ports.UserService
+	eventPublisher   ports.EventPublisher
+}
+
+// NewRevenueSplitUseCase creates a new revenue split use case. Split users
+// are only checked to exist, and named, when userService is set.
+func NewRevenueSplitUseCase(
+	revenueSplitRepo domain.RevenueSplitRepository,
+	opportunityRepo domain.OpportunityRepository,
+	userService ports.UserService,
+	eventPublisher ports.EventPublisher,
+) RevenueSplitUseCase {
+	return &revenueSplitUseCase{
+		revenueSplitRepo: revenueSplitRepo,
+		opportunityRepo:  opportunityRepo,
+		userService:      userService,
+		eventPublisher:   eventPublisher,
+	}
+}
+
+// Get returns the splits of an opportunity, or its owner with the whole
+// amount when it has none.
+func (uc *revenueSplitUseCase) Get(ctx context.Context, tenantID, opportunityID uuid.UUID) (*dto.RevenueSplitsResponse, error) {
+	opp, err := uc.opportunityRepo.GetByID(ctx, tenantID, opportunityID)
+	if err != nil {
+		return nil, application.ErrOpportunityNotFound(opportunityID)
+	}
+
+	splits, err := uc.revenueSplitRepo.GetByOpportunity(ctx, tenantID, opportunityID)
+	if err != nil {
+		return nil, application.WrapError(application.ErrCodeInternal, "failed to get revenue splits", err)
+	}
+	return mapRevenueSplitsToResponse(opp, splits), nil
+}
+
+// Set replaces the splits of an opportunity and records the change. Setting
+// the splits it already has records nothing.
+func (uc *revenueSplitUseCase) Set(ctx context.Context, tenantID, userID, opportunityID uuid.UUID, manager bool, req *dto.SetRevenueSplitsRequest) (*dto.RevenueSplitsResponse, error) {
+	splits := make(domain.RevenueSplits, len(req.Splits))
+	for i, input := range req.Splits {
+		splitUserID, err := uuid.Parse(input.UserID)
+		if err != nil {
+			return nil, application.ErrValidation("invalid user_id")
+		}
+		splits[i] = domain.RevenueSplit{UserID: splitUserID, Percent: input.Percent}
+	}
+	if err := splits.Validate(); err != nil {
+		return nil, application.ErrValidation(err.Error())
+	}
+	if err := uc.nameUsers(ctx, tenantID, splits); err != nil {
+		return nil, err
+	}
+
+	return uc.apply(ctx, tenantID, userID, opportunityID, manager, splits, req.Reason)
+}
+
+// Clear removes the splits of an opportunity.
+func (uc *revenueSplitUseCase) Clear(ctx context.Context, tenantID, userID, opportunityID uuid.UUID, manager bool) (*dto.RevenueSplitsResponse, error) {
+	return uc.apply(ctx, tenantID, userID, opportunityID, manager, domain.RevenueSplits{}, "")
+}
+
+// ListHistory lists the changes of an opportunity's splits.
+func (uc *revenueSplitUseCase) ListHistory(ctx context.Context, tenantID, opportunityID uuid.UUID, req *dto.ListRevenueSplitHistoryRequest) (*dto.RevenueSplitHistoryResponse, error) {
+	opp, err := uc.opportunityRepo.GetByID(ctx, tenantID, opportunityID)
+	if err != nil {
+		return nil, application.ErrOpportunityNotFound(opportunityID)
+	}
+
+	opts := revenueSplitHistoryOptions(req)
+	changes, total, err := uc.revenueSplitRepo.ListChanges(ctx, tenantID, opportunityID, opts)
+	if err != nil {
+		return nil, application.WrapError(application.ErrCodeInternal, "failed to list revenue split changes", err)
+	}
+
+	resp := &dto.RevenueSplitHistoryResponse{
+		Changes:    make([]*dto.RevenueSplitChangeResponse, len(changes)),
+		Pagination: dto.NewPaginationResponse(opts.Page, opts.PageSize, total),
+	}
+	for i, change := range changes {
+		resp.Changes[i] = &dto.RevenueSplitChangeResponse{
+			ID:             change.ID.String(),
+			Splits:         mapRevenueSplits(opp, change.Splits),
+			PreviousSplits: mapRevenueSplits(opp, change.PreviousSplits),
+			Reason:         change.Reason,
+			ChangedBy:      change.ChangedBy.String(),
+			ChangedAt:      change.ChangedAt,
+		}
+	}
+	return resp, nil
+}
+
+// apply replaces the splits of an opportunity the user may change, unless
+// they are unchanged, and publishes the revenue split changed event.
+func (uc *revenueSplitUseCase) apply(ctx context.Context, tenantID, userID, opportunityID uuid.UUID, manager bool, splits domain.RevenueSplits, reason string) (*dto.RevenueSplitsResponse, error) {
+	opp, err := uc.opportunityRepo.GetByID(ctx, tenantID, opportunityID)
+	if err != nil {
+		return nil, application.ErrOpportunityNotFound(opportunityID)
+	}
+	if !manager && opp.OwnerID != userID {
+		return nil, application.ErrForbidden("only the opportunity owner or a manager can change its revenue splits")
+	}
+
+	previous, err := uc.revenueSplitRepo.GetByOpportunity(ctx, tenantID, opportunityID)
+	if err != nil {
+		return nil, application.WrapError(application.ErrCodeInternal, "failed to get revenue splits", err)
+	}
+	if previous.Equal(splits) {
+		return mapRevenueSplitsToResponse(opp, previous), nil
+	}
+
+	change, err := domain.NewRevenueSplitChange(opp, previous, splits, userID, reason)
+	if err != nil {
+		return nil, application.ErrValidation(err.Error())
+	}
+	if err := uc.revenueSplitRepo.Apply(ctx, change); err != nil {
+		return nil, application.WrapError(application.ErrCodeInternal, "failed to save revenue splits", err)
+	}
+
+	uc.publishChange(ctx, opp, change)
+
+	return mapRevenueSplitsToResponse(opp, change.Splits), nil
+}
+
+// nameUsers checks that the split users exist and sets their names.
+func (uc *revenueSplitUseCase) nameUsers(ctx context.Context, tenantID uuid.UUID, splits domain.RevenueSplits) error {
+	if uc.userService == nil {
+		return nil
+	}
+
+	ids := make([]uuid.UUID, len(splits))
+	for i, split := range splits {
+		ids[i] = split.UserID
+	}
+	users, err := uc.userService.GetUsersByIDs(ctx, tenantID, ids)
+	if err != nil {
+		return application.WrapError(application.ErrCodeInternal, "failed to get split users", err)
+	}
+
+	names := make(map[uuid.UUID]string, len(users))
+	for _, user := range users {
+		if user != nil {
+			names[user.ID] = user.FullName
+		}
+	}
+	for i := range splits {
+		name, ok := names[splits[i].UserID]
+		if !ok {
+			return application.ErrValidation("user not found: " + splits[i].UserID.String())
+		}
+		splits[i].UserName = name
+	}
+	return nil
+}
+
+func revenueSplitHistoryOptions(req *dto.ListRevenueSplitHistoryRequest) domain.ListOptions {
+	page, pageSize := req.Page, req.PageSize
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 {
+		pageSize = 20
+	}
+	if pageSize > 100 {
+		pageSize = 100
+	}
+	return domain.ListOptions{Page: page, PageSize: pageSize}
+}
+
+// publishChange publishes the revenue split changed event.
+func (uc *revenueSplitUseCase) publishChange(ctx context.Context, opp *domain.Opportunity, change *domain.RevenueSplitChange) {
+	if uc.eventPublisher == nil {
+		return
+	}
+
+	event := domain.NewOpportunityRevenueSplitChangedEvent(opp, change)
+	var payload map[string]interface{}
+	if data, err := json.Marshal(event); err == nil {
+		json.Unmarshal(data, &payload)
+	}
+
+	_ = uc.eventPublisher.Publish(ctx, ports.Event{
+		ID:            event.EventID().String(),
+		Type:          event.EventType(),
+		AggregateID:   event.AggregateID().String(),
+		AggregateType: event.AggregateType(),
+		TenantID:      event.TenantID().String(),
+		Payload:       payload,
+		OccurredAt:    event.OccurredAt(),
+		Version:       event.Version(),
+	})
+}
+
+// ============================================================================
+// Mappers
+// ============================================================================
+
+func mapRevenueSplitsToResponse(opp *domain.Opportunity, splits domain.RevenueSplits) *dto.RevenueSplitsResponse {
+	resp := &dto.RevenueSplitsResponse{
+		OpportunityID: opp.ID.String(),
+		Amount:        moneyToDTO(opp.Amount),
+		IsSplit:       len(splits) > 0,
+	}
+	if !resp.IsSplit {
+		splits = domain.OwnerRevenueSplits(opp)
+	}
+	resp.Splits = mapRevenueSplits(opp, splits)
+	return resp
+}
+
+// mapRevenueSplits maps splits with their credits of the opportunity's
+// current amount.
+func mapRevenueSplits(opp *domain.Opportunity, splits domain.RevenueSplits) []*dto.RevenueSplitResponse {
+	credits := splits.Credits(opp.Amount.Amount)
+	resp := make([]*dto.RevenueSplitResponse, len(splits))
+	for i, split := range splits {
+		resp[i] = &dto.RevenueSplitResponse{
+			UserID:   split.UserID.String(),
+			UserName: split.UserName,
+			Percent:  split.Percent,
+			Credit:   moneyToDTO(domain.Money{Amount: credits[i], Currency: opp.Amount.Currency}),
+		}
+	}
+	return resp
+}
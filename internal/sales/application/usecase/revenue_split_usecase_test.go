@@ -0,0 +1,209 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"github.com/kilang-desa-murni/crm/internal/sales/application"
+	"github.com/kilang-desa-murni/crm/internal/sales/application/dto"
+	"github.com/kilang-desa-murni/crm/internal/sales/application/ports"
+	"github.com/kilang-desa-murni/crm/internal/sales/domain"
+)
+
+// ============================================================================
+// Mock Implementations for Revenue Split Tests
+// ============================================================================
+
+// MockRevenueSplitRepository is a mock implementation of domain.RevenueSplitRepository.
+type MockRevenueSplitRepository struct {
+	splits  map[uuid.UUID]domain.RevenueSplits
+	changes []*domain.RevenueSplitChange
+}
+
+func NewMockRevenueSplitRepository() *MockRevenueSplitRepository {
+	return &MockRevenueSplitRepository{splits: make(map[uuid.UUID]domain.RevenueSplits)}
+}
+
+func (m *MockRevenueSplitRepository) GetByOpportunity(ctx context.Context, tenantID, opportunityID uuid.UUID) (domain.RevenueSplits, error) {
+	if splits, ok := m.splits[opportunityID]; ok {
+		return splits, nil
+	}
+	return domain.RevenueSplits{}, nil
+}
+
+func (m *MockRevenueSplitRepository) GetByOpportunities(ctx context.Context, tenantID uuid.UUID, opportunityIDs []uuid.UUID) (map[uuid.UUID]domain.RevenueSplits, error) {
+	result := make(map[uuid.UUID]domain.RevenueSplits)
+	for _, id := range opportunityIDs {
+		if splits, ok := m.splits[id]; ok {
+			result[id] = splits
+		}
+	}
+	return result, nil
+}
+
+func (m *MockRevenueSplitRepository) Apply(ctx context.Context, change *domain.RevenueSplitChange) error {
+	if len(change.Splits) == 0 {
+		delete(m.splits, change.OpportunityID)
+	} else {
+		m.splits[change.OpportunityID] = change.Splits
+	}
+	m.changes = append(m.changes, change)
+	return nil
+}
+
+func (m *MockRevenueSplitRepository) ListChanges(ctx context.Context, tenantID, opportunityID uuid.UUID, opts domain.ListOptions) ([]*domain.RevenueSplitChange, int64, error) {
+	var changes []*domain.RevenueSplitChange
+	for i := len(m.changes) - 1; i >= 0; i-- {
+		if m.changes[i].OpportunityID == opportunityID {
+			changes = append(changes, m.changes[i])
+		}
+	}
+	return changes, int64(len(changes)), nil
+}
+
+// ============================================================================
+// Revenue Split Use Case Tests
+// ============================================================================
+
+func newTestRevenueSplitUseCase(tenantID uuid.UUID) (RevenueSplitUseCase, *MockRevenueSplitRepository, *MockSalesEventPublisher, *DealMockUserService, *domain.Opportunity) {
+	opportunityRepo := NewMockOpportunityRepository()
+	opportunity := &domain.Opportunity{
+		ID:        uuid.New(),
+		TenantID:  tenantID,
+		Status:    domain.OpportunityStatusOpen,
+		OwnerID:   uuid.New(),
+		OwnerName: "Hafiz",
+		Amount:    domain.Money{Amount: 1000001, Currency: "MYR"},
+	}
+	opportunityRepo.opportunities[opportunity.ID] = opportunity
+
+	userService := NewDealMockUserService()
+	userService.users[opportunity.OwnerID] = &ports.UserInfo{ID: opportunity.OwnerID, FullName: "Hafiz"}
+
+	splitRepo := NewMockRevenueSplitRepository()
+	publisher := NewMockSalesEventPublisher()
+	uc := NewRevenueSplitUseCase(splitRepo, opportunityRepo, userService, publisher)
+	return uc, splitRepo, publisher, userService, opportunity
+}
+
+func TestRevenueSplitUseCase_Get_DefaultsToOwner(t *testing.T) {
+	tenantID := uuid.New()
+	uc, _, _, _, opp := newTestRevenueSplitUseCase(tenantID)
+
+	resp, err := uc.Get(context.Background(), tenantID, opp.ID)
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if resp.IsSplit || len(resp.Splits) != 1 {
+		t.Fatalf("Get() = %+v, want the owner only", resp)
+	}
+	if resp.Splits[0].UserID != opp.OwnerID.String() || resp.Splits[0].Credit.Amount != opp.Amount.Amount {
+		t.Errorf("Get() split = %+v, want the owner with the whole amount", resp.Splits[0])
+	}
+}
+
+func TestRevenueSplitUseCase_Set(t *testing.T) {
+	ctx := context.Background()
+	tenantID := uuid.New()
+	uc, splitRepo, publisher, userService, opp := newTestRevenueSplitUseCase(tenantID)
+	partner := uuid.New()
+	userService.users[partner] = &ports.UserInfo{ID: partner, FullName: "Siti"}
+
+	req := &dto.SetRevenueSplitsRequest{
+		Splits: []dto.RevenueSplitInput{
+			{UserID: opp.OwnerID.String(), Percent: 70},
+			{UserID: partner.String(), Percent: 30},
+		},
+		Reason: "Siti ran the technical evaluation",
+	}
+	resp, err := uc.Set(ctx, tenantID, opp.OwnerID, opp.ID, false, req)
+	if err != nil {
+		t.Fatalf("Set() error = %v", err)
+	}
+	if !resp.IsSplit || len(resp.Splits) != 2 {
+		t.Fatalf("Set() = %+v, want two splits", resp)
+	}
+	if resp.Splits[1].UserName != "Siti" || resp.Splits[0].Credit.Amount+resp.Splits[1].Credit.Amount != opp.Amount.Amount {
+		t.Errorf("Set() splits = %+v, %+v", resp.Splits[0], resp.Splits[1])
+	}
+	if len(publisher.events) != 1 || publisher.events[0].Type != "opportunity.revenue_split_changed" {
+		t.Errorf("events = %+v, want one revenue split changed event", publisher.events)
+	}
+
+	// Setting the same splits again records nothing
+	if _, err := uc.Set(ctx, tenantID, opp.OwnerID, opp.ID, false, req); err != nil {
+		t.Fatalf("Set() again error = %v", err)
+	}
+	if len(splitRepo.changes) != 1 {
+		t.Errorf("changes = %d, want 1", len(splitRepo.changes))
+	}
+
+	cleared, err := uc.Clear(ctx, tenantID, opp.OwnerID, opp.ID, false)
+	if err != nil {
+		t.Fatalf("Clear() error = %v", err)
+	}
+	if cleared.IsSplit {
+		t.Errorf("Clear() = %+v, want the owner only", cleared)
+	}
+
+	history, err := uc.ListHistory(ctx, tenantID, opp.ID, &dto.ListRevenueSplitHistoryRequest{})
+	if err != nil {
+		t.Fatalf("ListHistory() error = %v", err)
+	}
+	if len(history.Changes) != 2 || len(history.Changes[0].Splits) != 0 || len(history.Changes[0].PreviousSplits) != 2 {
+		t.Errorf("ListHistory() = %+v, want the clearing first", history.Changes)
+	}
+	if history.Changes[1].Reason != req.Reason {
+		t.Errorf("ListHistory() reason = %q, want %q", history.Changes[1].Reason, req.Reason)
+	}
+}
+
+func TestRevenueSplitUseCase_Set_Rejected(t *testing.T) {
+	ctx := context.Background()
+	tenantID := uuid.New()
+	uc, _, _, _, opp := newTestRevenueSplitUseCase(tenantID)
+	unknown := uuid.New()
+
+	tests := []struct {
+		name     string
+		userID   uuid.UUID
+		manager  bool
+		splits   []dto.RevenueSplitInput
+		wantCode application.ErrorCode
+	}{
+		{
+			name:     "not totalling 100",
+			userID:   opp.OwnerID,
+			splits:   []dto.RevenueSplitInput{{UserID: opp.OwnerID.String(), Percent: 90}},
+			wantCode: application.ErrCodeValidation,
+		},
+		{
+			name:     "unknown user",
+			userID:   opp.OwnerID,
+			splits:   []dto.RevenueSplitInput{{UserID: opp.OwnerID.String(), Percent: 50}, {UserID: unknown.String(), Percent: 50}},
+			wantCode: application.ErrCodeValidation,
+		},
+		{
+			name:     "not the owner",
+			userID:   uuid.New(),
+			splits:   []dto.RevenueSplitInput{{UserID: opp.OwnerID.String(), Percent: 100}},
+			wantCode: application.ErrCodeForbidden,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := uc.Set(ctx, tenantID, tt.userID, opp.ID, tt.manager, &dto.SetRevenueSplitsRequest{Splits: tt.splits})
+			if appErr := application.GetAppError(err); appErr == nil || appErr.Code != tt.wantCode {
+				t.Errorf("Set() error = %v, want %s", err, tt.wantCode)
+			}
+		})
+	}
+
+	// Managers change the splits of opportunities they do not own
+	if _, err := uc.Clear(ctx, tenantID, uuid.New(), opp.ID, true); err != nil {
+		t.Errorf("Clear() by a manager error = %v", err)
+	}
+}
@@ -19,6 +19,10 @@ import (
 
 // LeadConversionOrchestrator orchestrates the lead-to-opportunity conversion saga.
 type LeadConversionOrchestrator interface {
+	// Convert starts and runs a conversion saga for the lead. A conversion
+	// already completed for the lead is returned as is.
+	Convert(ctx context.Context, tenantID, leadID, userID uuid.UUID, request *domain.LeadConversionRequest) (*domain.LeadConversionSaga, error)
+
 	// Execute runs the lead conversion saga.
 	Execute(ctx context.Context, saga *domain.LeadConversionSaga) error
 
@@ -79,6 +83,8 @@ func NewLeadConversionOrchestrator(
 		domain.StepTypeValidateLead:      orch.executeValidateLead,
 		domain.StepTypeCreateCustomer:    orch.executeCreateCustomer,
 		domain.StepTypeLookupCustomer:    orch.executeLookupCustomer,
+		domain.StepTypeCreateContact:     orch.executeCreateContact,
+		domain.StepTypeLookupContact:     orch.executeLookupContact,
 		domain.StepTypeCreateOpportunity: orch.executeCreateOpportunity,
 		domain.StepTypeMarkLeadConverted: orch.executeMarkLeadConverted,
 		domain.StepTypePublishEvents:     orch.executePublishEvents,
@@ -87,6 +93,7 @@ func NewLeadConversionOrchestrator(
 	// Initialize compensation handlers
 	orch.compensationHandlers = map[domain.SagaStepType]compensationHandler{
 		domain.StepTypeCreateCustomer:    orch.compensateCreateCustomer,
+		domain.StepTypeCreateContact:     orch.compensateCreateContact,
 		domain.StepTypeCreateOpportunity: orch.compensateCreateOpportunity,
 		domain.StepTypeMarkLeadConverted: orch.compensateMarkLeadConverted,
 		domain.StepTypePublishEvents:     orch.compensatePublishEvents,
@@ -95,6 +102,37 @@ func NewLeadConversionOrchestrator(
 	return orch
 }
 
+// Convert starts and runs a conversion saga for the lead.
+func (o *leadConversionOrchestrator) Convert(ctx context.Context, tenantID, leadID, userID uuid.UUID, request *domain.LeadConversionRequest) (*domain.LeadConversionSaga, error) {
+	sagas := o.uow.Sagas()
+
+	// Only one conversion of a lead may run at a time
+	if existing, err := sagas.GetByLeadID(ctx, tenantID, leadID); err == nil {
+		switch {
+		case existing.State == domain.SagaStateCompleted:
+			return existing, nil
+		case !existing.State.IsTerminal():
+			return nil, application.ErrConflict("lead conversion is already in progress")
+		}
+	}
+
+	// A retry of a failed conversion within the key's window gets a key of its own
+	key := domain.GenerateIdempotencyKey(tenantID, leadID, userID)
+	if _, err := sagas.GetByIdempotencyKey(ctx, tenantID, key); err == nil {
+		key += ":" + uuid.NewString()
+	}
+
+	saga := domain.NewLeadConversionSaga(tenantID, leadID, key, userID, request)
+	if err := sagas.Create(ctx, saga); err != nil {
+		return nil, fmt.Errorf("failed to create saga: %w", err)
+	}
+
+	if err := o.Execute(ctx, saga); err != nil {
+		return saga, err
+	}
+	return saga, nil
+}
+
 // Execute runs the lead conversion saga.
 func (o *leadConversionOrchestrator) Execute(ctx context.Context, saga *domain.LeadConversionSaga) error {
 	// Start the saga if it's in started state
@@ -385,6 +423,83 @@ func (o *leadConversionOrchestrator) executeLookupCustomer(ctx context.Context,
 	return nil
 }
 
+// executeCreateContact creates a contact for the customer from lead data.
+func (o *leadConversionOrchestrator) executeCreateContact(ctx context.Context, saga *domain.LeadConversionSaga, step *domain.SagaStep) error {
+	if o.customerService == nil {
+		return fmt.Errorf("customer service is not available")
+	}
+	if saga.CustomerID == nil {
+		return fmt.Errorf("customer ID is required")
+	}
+
+	lead, err := o.leadRepo.GetByID(ctx, saga.TenantID, saga.LeadID)
+	if err != nil {
+		return err
+	}
+
+	// The lead's contact is the primary contact of a customer created for it
+	createReq := ports.CreateContactRequest{
+		FirstName: lead.Contact.FirstName,
+		LastName:  lead.Contact.LastName,
+		Email:     lead.Contact.Email,
+		IsPrimary: saga.CustomerCreated,
+	}
+	if lead.Contact.Phone != "" {
+		phone := lead.Contact.Phone
+		createReq.Phone = &phone
+	}
+	if lead.Contact.Mobile != "" {
+		mobile := lead.Contact.Mobile
+		createReq.Mobile = &mobile
+	}
+	if lead.Contact.JobTitle != "" {
+		jobTitle := lead.Contact.JobTitle
+		createReq.JobTitle = &jobTitle
+	}
+	if lead.Contact.Department != "" {
+		department := lead.Contact.Department
+		createReq.Department = &department
+	}
+
+	contact, err := o.customerService.CreateContact(ctx, saga.TenantID, *saga.CustomerID, createReq)
+	if err != nil {
+		return fmt.Errorf("failed to create contact: %w", err)
+	}
+
+	saga.SetContactID(contact.ID)
+	step.Output["contact_id"] = contact.ID.String()
+	step.Output["customer_id"] = contact.CustomerID.String()
+
+	return nil
+}
+
+// executeLookupContact looks up an existing contact of the customer.
+func (o *leadConversionOrchestrator) executeLookupContact(ctx context.Context, saga *domain.LeadConversionSaga, step *domain.SagaStep) error {
+	if saga.Request.ContactID == nil {
+		return fmt.Errorf("contact ID is required for lookup")
+	}
+
+	if o.customerService == nil {
+		// If no customer service, just trust the ID
+		saga.SetContactID(*saga.Request.ContactID)
+		step.Output["contact_id"] = saga.Request.ContactID.String()
+		return nil
+	}
+
+	contact, err := o.customerService.GetContact(ctx, saga.TenantID, *saga.Request.ContactID)
+	if err != nil {
+		return fmt.Errorf("contact not found: %w", err)
+	}
+	if saga.CustomerID != nil && contact.CustomerID != *saga.CustomerID {
+		return application.ErrValidation("contact does not belong to the customer")
+	}
+
+	saga.SetContactID(contact.ID)
+	step.Output["contact_id"] = contact.ID.String()
+
+	return nil
+}
+
 // executeCreateOpportunity creates the opportunity from the lead.
 func (o *leadConversionOrchestrator) executeCreateOpportunity(ctx context.Context, saga *domain.LeadConversionSaga, step *domain.SagaStep) error {
 	// Get lead
@@ -431,7 +546,19 @@ func (o *leadConversionOrchestrator) executeCreateOpportunity(ctx context.Contex
 	}
 	opportunity.AssignOwner(ownerID, ownerName)
 
+	// Link the lead's contact to the customer's contact
+	if saga.ContactID != nil {
+		for i := range opportunity.Contacts {
+			if opportunity.Contacts[i].IsPrimary {
+				opportunity.Contacts[i].ContactID = *saga.ContactID
+			}
+		}
+	}
+
 	// Set optional fields
+	if saga.Request.OpportunityName != nil && *saga.Request.OpportunityName != "" {
+		opportunity.Name = *saga.Request.OpportunityName
+	}
 	if saga.Request.Description != nil {
 		opportunity.Description = *saga.Request.Description
 	}
@@ -442,7 +569,11 @@ func (o *leadConversionOrchestrator) executeCreateOpportunity(ctx context.Contex
 		opportunity.Probability = *saga.Request.Probability
 	}
 	if saga.Request.Amount != nil {
-		opportunity.Amount = *saga.Request.Amount
+		amount := *saga.Request.Amount
+		if amount.Currency == "" {
+			amount.Currency = opportunity.Amount.Currency
+		}
+		opportunity.Amount = amount
 	}
 
 	// Save opportunity
@@ -452,6 +583,7 @@ func (o *leadConversionOrchestrator) executeCreateOpportunity(ctx context.Contex
 
 	// Store in saga and step output
 	saga.SetOpportunityID(opportunity.ID)
+	saga.RecordEvents(opportunity.GetEvents())
 	step.Output["opportunity_id"] = opportunity.ID.String()
 	step.Output["opportunity_code"] = opportunity.Code
 	step.Output["opportunity_name"] = opportunity.Name
@@ -479,6 +611,7 @@ func (o *leadConversionOrchestrator) executeMarkLeadConverted(ctx context.Contex
 		return fmt.Errorf("failed to save converted lead: %w", err)
 	}
 
+	saga.RecordEvents(lead.GetEvents())
 	step.Output["lead_id"] = lead.ID.String()
 	step.Output["new_status"] = string(lead.Status)
 
@@ -499,9 +632,21 @@ func (o *leadConversionOrchestrator) executePublishEvents(ctx context.Context, s
 		return nil
 	}
 
-	// Publish events
-	// Note: In a production system, you would use an outbox pattern here
-	// For now, we just track that events need to be published
+	batch := make([]ports.Event, 0, len(events))
+	for _, event := range events {
+		batch = append(batch, ports.Event{
+			ID:            event.EventID().String(),
+			Type:          event.EventType(),
+			AggregateID:   event.AggregateID().String(),
+			AggregateType: event.AggregateType(),
+			TenantID:      event.TenantID().String(),
+			OccurredAt:    event.OccurredAt(),
+			Version:       event.Version(),
+		})
+	}
+	if err := o.eventPublisher.PublishBatch(ctx, batch); err != nil {
+		return fmt.Errorf("failed to publish events: %w", err)
+	}
 	step.Output["events_count"] = len(events)
 
 	saga.ClearEvents()
@@ -512,17 +657,28 @@ func (o *leadConversionOrchestrator) executePublishEvents(ctx context.Context, s
 // Compensation Handlers
 // ============================================================================
 
-// compensateCreateCustomer handles customer creation compensation.
-// Note: Customer deletion would require the CustomerService to implement DeleteCustomer.
-// For now, we log and continue since not all services may support deletion.
+// compensateCreateCustomer deletes the created customer.
 func (o *leadConversionOrchestrator) compensateCreateCustomer(ctx context.Context, saga *domain.LeadConversionSaga, step *domain.SagaStep) error {
-	if !saga.CustomerCreated || saga.CustomerID == nil {
+	if !saga.CustomerCreated || saga.CustomerID == nil || o.customerService == nil {
 		return nil // Nothing to compensate
 	}
 
-	// Customer deletion would be handled here if the service supports it
-	// For now, we just acknowledge the compensation was attempted
-	step.Output["compensation_note"] = "customer deletion requires manual cleanup"
+	if err := o.customerService.DeleteCustomer(ctx, saga.TenantID, *saga.CustomerID); err != nil {
+		return fmt.Errorf("failed to delete customer: %w", err)
+	}
+
+	return nil
+}
+
+// compensateCreateContact deletes the created contact.
+func (o *leadConversionOrchestrator) compensateCreateContact(ctx context.Context, saga *domain.LeadConversionSaga, step *domain.SagaStep) error {
+	if saga.ContactID == nil || saga.CustomerID == nil || o.customerService == nil {
+		return nil // Nothing to compensate
+	}
+
+	if err := o.customerService.DeleteContact(ctx, saga.TenantID, *saga.CustomerID, *saga.ContactID); err != nil {
+		return fmt.Errorf("failed to delete contact: %w", err)
+	}
 
 	return nil
 }
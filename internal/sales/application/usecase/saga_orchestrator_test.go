@@ -260,6 +260,14 @@ func (m *SagaMockLeadRepo) GetByPhone(ctx context.Context, tenantID uuid.UUID, p
 	return nil, domain.ErrLeadNotFound
 }
 
+func (m *SagaMockLeadRepo) FindMatches(ctx context.Context, tenantID uuid.UUID, criteria domain.LeadMatchCriteria, limit int) ([]*domain.Lead, error) {
+	return []*domain.Lead{}, nil
+}
+
+func (m *SagaMockLeadRepo) Merge(ctx context.Context, target *domain.Lead, sources []*domain.Lead) error {
+	return nil
+}
+
 func (m *SagaMockLeadRepo) GetByStatus(ctx context.Context, tenantID uuid.UUID, status domain.LeadStatus, opts domain.ListOptions) ([]*domain.Lead, int64, error) {
 	return []*domain.Lead{}, 0, nil
 }
@@ -415,6 +423,10 @@ func (m *MockSagaOpportunityRepository) GetByStage(ctx context.Context, tenantID
 	return []*domain.Opportunity{}, 0, nil
 }
 
+func (m *MockSagaOpportunityRepository) GetBoardPositionNeighbors(ctx context.Context, tenantID, stageID, excludeID uuid.UUID, index int) (string, string, error) {
+	return "", "", nil
+}
+
 func (m *MockSagaOpportunityRepository) GetByCustomer(ctx context.Context, tenantID, customerID uuid.UUID, opts domain.ListOptions) ([]*domain.Opportunity, int64, error) {
 	return []*domain.Opportunity{}, 0, nil
 }
@@ -459,6 +471,10 @@ func (m *MockSagaOpportunityRepository) GetWeightedPipelineValue(ctx context.Con
 	return 0, nil
 }
 
+func (m *MockSagaOpportunityRepository) GetPipelineValueByCurrency(ctx context.Context, tenantID uuid.UUID) ([]*domain.PipelineCurrencyValue, error) {
+	return nil, nil
+}
+
 func (m *MockSagaOpportunityRepository) BulkUpdateOwner(ctx context.Context, tenantID uuid.UUID, opportunityIDs []uuid.UUID, newOwnerID uuid.UUID) error {
 	return nil
 }
@@ -487,6 +503,10 @@ func (m *MockSagaOpportunityRepository) GetAverageSalesCycle(ctx context.Context
 	return 0, nil
 }
 
+func (m *MockSagaOpportunityRepository) GetCloseBreakdown(ctx context.Context, tenantID uuid.UUID, pipelineID *uuid.UUID, status domain.OpportunityStatus) (*domain.CloseBreakdown, error) {
+	return &domain.CloseBreakdown{}, nil
+}
+
 // MockSagaPipelineRepository is a mock implementation of domain.PipelineRepository for saga tests.
 type MockSagaPipelineRepository struct {
 	mu        sync.RWMutex
@@ -575,6 +595,14 @@ func (m *MockSagaPipelineRepository) ReorderStages(ctx context.Context, tenantID
 	return nil
 }
 
+func (m *MockSagaPipelineRepository) GetVersion(ctx context.Context, tenantID, pipelineID uuid.UUID, version int) (*domain.PipelineVersion, error) {
+	return nil, domain.ErrPipelineVersionNotFound
+}
+
+func (m *MockSagaPipelineRepository) ListVersions(ctx context.Context, tenantID, pipelineID uuid.UUID) ([]*domain.PipelineVersion, error) {
+	return nil, nil
+}
+
 func (m *MockSagaPipelineRepository) GetPipelineStatistics(ctx context.Context, tenantID, pipelineID uuid.UUID) (*domain.PipelineStatistics, error) {
 	return &domain.PipelineStatistics{}, nil
 }
@@ -697,16 +725,21 @@ func (m *MockSagaEventPublisher) PublishAsync(ctx context.Context, event ports.E
 
 // MockSagaCustomerService is a mock implementation of ports.CustomerService for saga tests.
 type MockSagaCustomerService struct {
-	mu              sync.RWMutex
-	customers       map[uuid.UUID]*ports.CustomerInfo
-	createErr       error
-	getErr          error
+	mu               sync.RWMutex
+	customers        map[uuid.UUID]*ports.CustomerInfo
+	contacts         map[uuid.UUID]*ports.ContactInfo
+	createErr        error
+	createContactErr error
+	getErr           error
 	createCustomerID uuid.UUID
+	deletedCustomers []uuid.UUID
+	deletedContacts  []uuid.UUID
 }
 
 func NewMockSagaCustomerService() *MockSagaCustomerService {
 	return &MockSagaCustomerService{
 		customers: make(map[uuid.UUID]*ports.CustomerInfo),
+		contacts:  make(map[uuid.UUID]*ports.ContactInfo),
 	}
 }
 
@@ -724,6 +757,22 @@ func (m *MockSagaCustomerService) GetCustomer(ctx context.Context, tenantID, cus
 	return customer, nil
 }
 
+func (m *MockSagaCustomerService) GetCustomersByIDs(ctx context.Context, tenantID uuid.UUID, customerIDs []uuid.UUID) ([]*ports.CustomerInfo, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	if m.getErr != nil {
+		return nil, m.getErr
+	}
+	var result []*ports.CustomerInfo
+	for _, id := range customerIDs {
+		if customer, ok := m.customers[id]; ok {
+			result = append(result, customer)
+		}
+	}
+	return result, nil
+}
+
 func (m *MockSagaCustomerService) GetCustomerByCode(ctx context.Context, tenantID uuid.UUID, code string) (*ports.CustomerInfo, error) {
 	m.mu.RLock()
 	defer m.mu.RUnlock()
@@ -772,22 +821,61 @@ func (m *MockSagaCustomerService) CreateCustomer(ctx context.Context, tenantID u
 }
 
 func (m *MockSagaCustomerService) GetContact(ctx context.Context, tenantID, contactID uuid.UUID) (*ports.ContactInfo, error) {
-	return nil, errors.New("contact not found")
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	contact, ok := m.contacts[contactID]
+	if !ok {
+		return nil, errors.New("contact not found")
+	}
+	return contact, nil
 }
 
 func (m *MockSagaCustomerService) ContactExists(ctx context.Context, tenantID, contactID uuid.UUID) (bool, error) {
-	return false, nil
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	_, ok := m.contacts[contactID]
+	return ok, nil
 }
 
 func (m *MockSagaCustomerService) CreateContact(ctx context.Context, tenantID, customerID uuid.UUID, req ports.CreateContactRequest) (*ports.ContactInfo, error) {
-	return &ports.ContactInfo{
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.createContactErr != nil {
+		return nil, m.createContactErr
+	}
+
+	contact := &ports.ContactInfo{
 		ID:         uuid.New(),
 		TenantID:   tenantID,
 		CustomerID: customerID,
 		FirstName:  req.FirstName,
 		LastName:   req.LastName,
 		Email:      req.Email,
-	}, nil
+		IsPrimary:  req.IsPrimary,
+	}
+	m.contacts[contact.ID] = contact
+	return contact, nil
+}
+
+func (m *MockSagaCustomerService) DeleteCustomer(ctx context.Context, tenantID, customerID uuid.UUID) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	delete(m.customers, customerID)
+	m.deletedCustomers = append(m.deletedCustomers, customerID)
+	return nil
+}
+
+func (m *MockSagaCustomerService) DeleteContact(ctx context.Context, tenantID, customerID, contactID uuid.UUID) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	delete(m.contacts, contactID)
+	m.deletedContacts = append(m.deletedContacts, contactID)
+	return nil
 }
 
 // ============================================================================
@@ -1244,6 +1332,183 @@ func TestSagaOrchestrator_Compensate_OpportunityDeletionFailed(t *testing.T) {
 	}
 }
 
+func TestSagaOrchestrator_Execute_CreatesContact(t *testing.T) {
+	// Arrange
+	orchestrator, leadRepo, oppRepo, pipelineRepo, sagaRepo, customerService := setupSagaOrchestratorTest()
+
+	tenantID := uuid.New()
+	lead := createTestQualifiedLead(tenantID)
+	pipeline := createSagaTestPipeline(tenantID)
+
+	leadRepo.leads[lead.ID] = lead
+	pipelineRepo.pipelines[pipeline.ID] = pipeline
+
+	request := createTestConversionRequest(lead, pipeline, true)
+	request.CreateNewContact = true
+	saga := createTestSaga(tenantID, lead, request)
+	sagaRepo.sagas[saga.ID] = saga
+
+	// Act
+	err := orchestrator.Execute(context.Background(), saga)
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if saga.ContactID == nil {
+		t.Fatal("Expected contact to be created")
+	}
+	contact := customerService.contacts[*saga.ContactID]
+	if contact == nil || contact.CustomerID != *saga.CustomerID || !contact.IsPrimary {
+		t.Errorf("Expected primary contact of the new customer, got %+v", contact)
+	}
+	opportunity := oppRepo.opportunities[*saga.OpportunityID]
+	if opportunity.Contacts[0].ContactID != *saga.ContactID {
+		t.Error("Expected opportunity contact to be the created contact")
+	}
+	if leadRepo.leads[lead.ID].ConversionInfo.ContactID == nil {
+		t.Error("Expected lead conversion to record the contact")
+	}
+}
+
+func TestSagaOrchestrator_Execute_ContactCreationFailed_DeletesCustomer(t *testing.T) {
+	// Arrange
+	orchestrator, leadRepo, oppRepo, pipelineRepo, sagaRepo, customerService := setupSagaOrchestratorTest()
+
+	tenantID := uuid.New()
+	lead := createTestQualifiedLead(tenantID)
+	pipeline := createSagaTestPipeline(tenantID)
+
+	leadRepo.leads[lead.ID] = lead
+	pipelineRepo.pipelines[pipeline.ID] = pipeline
+	customerService.createContactErr = errors.New("contact service unavailable")
+
+	request := createTestConversionRequest(lead, pipeline, true)
+	request.CreateNewContact = true
+	saga := createTestSaga(tenantID, lead, request)
+	sagaRepo.sagas[saga.ID] = saga
+
+	// Act
+	err := orchestrator.Execute(context.Background(), saga)
+
+	// Assert
+	if err == nil {
+		t.Fatal("Expected error for contact creation failure, got nil")
+	}
+	if saga.State != domain.SagaStateCompensated {
+		t.Errorf("Expected saga state %s, got %s", domain.SagaStateCompensated, saga.State)
+	}
+	if len(customerService.deletedCustomers) != 1 || customerService.deletedCustomers[0] != *saga.CustomerID {
+		t.Errorf("Expected created customer to be deleted, got %v", customerService.deletedCustomers)
+	}
+	if len(oppRepo.opportunities) != 0 {
+		t.Error("Expected no opportunity to be created")
+	}
+}
+
+func TestSagaOrchestrator_Execute_OpportunityFailed_DeletesContactAndCustomer(t *testing.T) {
+	// Arrange
+	orchestrator, leadRepo, oppRepo, pipelineRepo, sagaRepo, customerService := setupSagaOrchestratorTest()
+
+	tenantID := uuid.New()
+	lead := createTestQualifiedLead(tenantID)
+	pipeline := createSagaTestPipeline(tenantID)
+
+	leadRepo.leads[lead.ID] = lead
+	pipelineRepo.pipelines[pipeline.ID] = pipeline
+	oppRepo.createErr = errors.New("database error")
+
+	request := createTestConversionRequest(lead, pipeline, true)
+	request.CreateNewContact = true
+	saga := createTestSaga(tenantID, lead, request)
+	sagaRepo.sagas[saga.ID] = saga
+
+	// Act
+	err := orchestrator.Execute(context.Background(), saga)
+
+	// Assert
+	if err == nil {
+		t.Fatal("Expected error for opportunity creation failure, got nil")
+	}
+	if len(customerService.deletedContacts) != 1 || len(customerService.deletedCustomers) != 1 {
+		t.Errorf("Expected contact and customer to be deleted, got contacts %v customers %v",
+			customerService.deletedContacts, customerService.deletedCustomers)
+	}
+	if leadRepo.leads[lead.ID].Status != domain.LeadStatusQualified {
+		t.Errorf("Expected lead to stay qualified, got %s", leadRepo.leads[lead.ID].Status)
+	}
+}
+
+func TestSagaOrchestrator_Execute_LookupContact_OtherCustomer(t *testing.T) {
+	// Arrange
+	orchestrator, leadRepo, _, pipelineRepo, sagaRepo, customerService := setupSagaOrchestratorTest()
+
+	tenantID := uuid.New()
+	lead := createTestQualifiedLead(tenantID)
+	pipeline := createSagaTestPipeline(tenantID)
+
+	leadRepo.leads[lead.ID] = lead
+	pipelineRepo.pipelines[pipeline.ID] = pipeline
+
+	request := createTestConversionRequest(lead, pipeline, false)
+	customerService.customers[*request.CustomerID] = &ports.CustomerInfo{ID: *request.CustomerID, Name: "Acme"}
+	contactID := uuid.New()
+	customerService.contacts[contactID] = &ports.ContactInfo{ID: contactID, CustomerID: uuid.New()}
+	request.ContactID = &contactID
+
+	saga := createTestSaga(tenantID, lead, request)
+	sagaRepo.sagas[saga.ID] = saga
+
+	// Act
+	err := orchestrator.Execute(context.Background(), saga)
+
+	// Assert
+	if err == nil {
+		t.Fatal("Expected error for contact of another customer, got nil")
+	}
+	if len(customerService.deletedCustomers) != 0 {
+		t.Error("Expected existing customer not to be deleted")
+	}
+}
+
+func TestSagaOrchestrator_Convert(t *testing.T) {
+	// Arrange
+	orchestrator, leadRepo, _, pipelineRepo, sagaRepo, _ := setupSagaOrchestratorTest()
+
+	tenantID := uuid.New()
+	userID := uuid.New()
+	lead := createTestQualifiedLead(tenantID)
+	pipeline := createSagaTestPipeline(tenantID)
+
+	leadRepo.leads[lead.ID] = lead
+	pipelineRepo.pipelines[pipeline.ID] = pipeline
+
+	request := createTestConversionRequest(lead, pipeline, true)
+
+	// Act
+	saga, err := orchestrator.Convert(context.Background(), tenantID, lead.ID, userID, request)
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if saga.State != domain.SagaStateCompleted {
+		t.Errorf("Expected saga state %s, got %s", domain.SagaStateCompleted, saga.State)
+	}
+	if _, ok := sagaRepo.sagas[saga.ID]; !ok {
+		t.Error("Expected saga to be persisted")
+	}
+
+	// Converting again returns the completed conversion
+	again, err := orchestrator.Convert(context.Background(), tenantID, lead.ID, userID, request)
+	if err != nil {
+		t.Fatalf("Expected no error on repeat, got: %v", err)
+	}
+	if again.ID != saga.ID {
+		t.Error("Expected repeat conversion to return the completed saga")
+	}
+}
+
 // ============================================================================
 // Test Cases - Resume
 // ============================================================================
@@ -0,0 +1,464 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/kilang-desa-murni/crm/internal/sales/application"
+	"github.com/kilang-desa-murni/crm/internal/sales/application/dto"
+	"github.com/kilang-desa-murni/crm/internal/sales/application/ports"
+	"github.com/kilang-desa-murni/crm/internal/sales/domain"
+)
+
+const (
+	// shipmentTrackingInterval is how long a shipment on its way goes between
+	// checks with its carrier.
+	shipmentTrackingInterval = 2 * time.Hour
+	// shipmentTrackingBatchSize is how many shipments are loaded per batch
+	// when polling carriers.
+	shipmentTrackingBatchSize = 100
+)
+
+// ============================================================================
+// Shipment Use Case Interface
+// ============================================================================
+
+// ShipmentUseCase defines the interface for the carrier shipments of deals
+// and the tracking updates carriers send for them.
+type ShipmentUseCase interface {
+	Create(ctx context.Context, tenantID, dealID, userID uuid.UUID, req *dto.CreateShipmentRequest) (*dto.ShipmentResponse, error)
+	GetByID(ctx context.Context, tenantID, shipmentID uuid.UUID) (*dto.ShipmentResponse, error)
+	ListByDeal(ctx context.Context, tenantID, dealID uuid.UUID) (*dto.ShipmentListResponse, error)
+	Update(ctx context.Context, tenantID, shipmentID uuid.UUID, req *dto.UpdateShipmentRequest) (*dto.ShipmentResponse, error)
+	RecordTracking(ctx context.Context, tenantID, shipmentID uuid.UUID, req *dto.RecordShipmentTrackingRequest) (*dto.ShipmentResponse, error)
+
+	// Carrier updates
+	ReceiveCarrierWebhook(ctx context.Context, carrier string, headers map[string]string, body []byte) (*dto.CarrierWebhookResponse, error)
+	PollCarriers(ctx context.Context, now time.Time) (int, error)
+}
+
+// ============================================================================
+// Shipment Use Case Implementation
+// ============================================================================
+
+// shipmentUseCase implements ShipmentUseCase.
+type shipmentUseCase struct {
+	shipmentRepo    domain.ShipmentRepository
+	dealRepo        domain.DealRepository
+	eventPublisher  ports.EventPublisher
+	customerService ports.CustomerService
+	trackers        map[string]ports.CarrierTracker
+	webhookParsers  map[string]ports.CarrierWebhookParser
+}
+
+// NewShipmentUseCase creates a new shipment use case. Shipments with a
+// carrier that has a tracker are polled; carriers with a webhook parser can
+// push their updates.
+func NewShipmentUseCase(
+	shipmentRepo domain.ShipmentRepository,
+	dealRepo domain.DealRepository,
+	eventPublisher ports.EventPublisher,
+	customerService ports.CustomerService,
+	trackers []ports.CarrierTracker,
+	webhookParsers []ports.CarrierWebhookParser,
+) ShipmentUseCase {
+	uc := &shipmentUseCase{
+		shipmentRepo:    shipmentRepo,
+		dealRepo:        dealRepo,
+		eventPublisher:  eventPublisher,
+		customerService: customerService,
+		trackers:        make(map[string]ports.CarrierTracker, len(trackers)),
+		webhookParsers:  make(map[string]ports.CarrierWebhookParser, len(webhookParsers)),
+	}
+	for _, tracker := range trackers {
+		uc.trackers[domain.NormalizeCarrier(tracker.Carrier())] = tracker
+	}
+	for _, parser := range webhookParsers {
+		uc.webhookParsers[domain.NormalizeCarrier(parser.Carrier())] = parser
+	}
+	return uc
+}
+
+// Create records a shipment of a deal. Without a recipient, the customer's
+// email is notified of the shipment's progress when it is known.
+func (uc *shipmentUseCase) Create(ctx context.Context, tenantID, dealID, userID uuid.UUID, req *dto.CreateShipmentRequest) (*dto.ShipmentResponse, error) {
+	deal, err := uc.dealRepo.GetByID(ctx, tenantID, dealID)
+	if err != nil {
+		return nil, application.ErrDealNotFound(dealID)
+	}
+
+	estimatedDelivery, err := parseOrderDate(req.EstimatedDelivery)
+	if err != nil {
+		return nil, err
+	}
+
+	shipment, err := domain.NewShipment(deal, req.Carrier, req.TrackingNumber, userID)
+	if err != nil {
+		if errors.Is(err, domain.ErrShipmentDealCancelled) {
+			return nil, application.ErrDealCancelled(dealID)
+		}
+		return nil, application.ErrValidation(err.Error())
+	}
+	shipment.TrackingURL = strings.TrimSpace(req.TrackingURL)
+	shipment.EstimatedDelivery = estimatedDelivery
+
+	recipientName, recipientEmail := req.RecipientName, req.RecipientEmail
+	if recipientEmail == "" && uc.customerService != nil {
+		if customer, err := uc.customerService.GetCustomer(ctx, tenantID, deal.CustomerID); err == nil && customer != nil && customer.Email != nil {
+			recipientEmail = *customer.Email
+			if recipientName == "" {
+				recipientName = customer.Name
+			}
+		}
+	}
+	shipment.SetRecipient(recipientName, recipientEmail)
+
+	if err := uc.shipmentRepo.Create(ctx, shipment); err != nil {
+		return nil, application.WrapError(application.ErrCodeInternal, "failed to create shipment", err)
+	}
+
+	uc.publishEvents(ctx, shipment)
+
+	return mapShipmentToResponse(shipment), nil
+}
+
+// GetByID retrieves a shipment.
+func (uc *shipmentUseCase) GetByID(ctx context.Context, tenantID, shipmentID uuid.UUID) (*dto.ShipmentResponse, error) {
+	shipment, err := uc.getShipment(ctx, tenantID, shipmentID)
+	if err != nil {
+		return nil, err
+	}
+	return mapShipmentToResponse(shipment), nil
+}
+
+// ListByDeal lists a deal's shipments, newest first.
+func (uc *shipmentUseCase) ListByDeal(ctx context.Context, tenantID, dealID uuid.UUID) (*dto.ShipmentListResponse, error) {
+	if _, err := uc.dealRepo.GetByID(ctx, tenantID, dealID); err != nil {
+		return nil, application.ErrDealNotFound(dealID)
+	}
+
+	shipments, err := uc.shipmentRepo.ListByDeal(ctx, tenantID, dealID)
+	if err != nil {
+		return nil, application.WrapError(application.ErrCodeInternal, "failed to list shipments", err)
+	}
+
+	resp := &dto.ShipmentListResponse{Shipments: make([]*dto.ShipmentResponse, len(shipments))}
+	for i, shipment := range shipments {
+		resp.Shipments[i] = mapShipmentToResponse(shipment)
+	}
+	return resp, nil
+}
+
+// Update updates a shipment's carrier details and recipient.
+func (uc *shipmentUseCase) Update(ctx context.Context, tenantID, shipmentID uuid.UUID, req *dto.UpdateShipmentRequest) (*dto.ShipmentResponse, error) {
+	shipment, err := uc.getShipment(ctx, tenantID, shipmentID)
+	if err != nil {
+		return nil, err
+	}
+
+	carrier := shipment.Carrier
+	if req.Carrier != nil {
+		carrier = *req.Carrier
+	}
+	trackingNumber := shipment.TrackingNumber
+	if req.TrackingNumber != nil {
+		trackingNumber = *req.TrackingNumber
+	}
+	trackingURL := shipment.TrackingURL
+	if req.TrackingURL != nil {
+		trackingURL = *req.TrackingURL
+	}
+	estimatedDelivery := shipment.EstimatedDelivery
+	if req.EstimatedDelivery != nil {
+		if estimatedDelivery, err = parseOrderDate(*req.EstimatedDelivery); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := shipment.Update(carrier, trackingNumber, trackingURL, estimatedDelivery); err != nil {
+		if errors.Is(err, domain.ErrInvalidShipmentStatusTransition) {
+			return nil, application.ErrShipmentClosed(shipmentID, string(shipment.Status))
+		}
+		return nil, application.ErrValidation(err.Error())
+	}
+	if req.RecipientName != nil || req.RecipientEmail != nil {
+		recipientName, recipientEmail := shipment.RecipientName, shipment.RecipientEmail
+		if req.RecipientName != nil {
+			recipientName = *req.RecipientName
+		}
+		if req.RecipientEmail != nil {
+			recipientEmail = *req.RecipientEmail
+		}
+		shipment.SetRecipient(recipientName, recipientEmail)
+	}
+
+	if err := uc.saveShipment(ctx, shipment); err != nil {
+		return nil, err
+	}
+	return mapShipmentToResponse(shipment), nil
+}
+
+// RecordTracking records a tracking update entered by hand, for carriers
+// without a tracker or webhook.
+func (uc *shipmentUseCase) RecordTracking(ctx context.Context, tenantID, shipmentID uuid.UUID, req *dto.RecordShipmentTrackingRequest) (*dto.ShipmentResponse, error) {
+	shipment, err := uc.getShipment(ctx, tenantID, shipmentID)
+	if err != nil {
+		return nil, err
+	}
+
+	event := domain.ShipmentTrackingEvent{
+		Status:      domain.ShipmentStatus(req.Status),
+		Description: req.Description,
+		Location:    req.Location,
+		Source:      domain.ShipmentSourceManual,
+	}
+	if req.OccurredAt != nil {
+		event.OccurredAt = *req.OccurredAt
+	}
+
+	changed, err := shipment.RecordTracking(event)
+	if err != nil {
+		if errors.Is(err, domain.ErrInvalidShipmentStatusTransition) {
+			return nil, application.ErrShipmentInvalidStatusTransition(string(shipment.Status), req.Status)
+		}
+		return nil, application.ErrValidation(err.Error())
+	}
+
+	if changed {
+		if err := uc.saveShipment(ctx, shipment); err != nil {
+			return nil, err
+		}
+	}
+	return mapShipmentToResponse(shipment), nil
+}
+
+// ============================================================================
+// Carrier Updates
+// ============================================================================
+
+// ReceiveCarrierWebhook applies the tracking updates a carrier pushed to the
+// shipments of every tenant with their tracking numbers.
+func (uc *shipmentUseCase) ReceiveCarrierWebhook(ctx context.Context, carrier string, headers map[string]string, body []byte) (*dto.CarrierWebhookResponse, error) {
+	carrier = domain.NormalizeCarrier(carrier)
+	parser, ok := uc.webhookParsers[carrier]
+	if !ok {
+		return nil, application.ErrCarrierNotSupported(carrier)
+	}
+
+	updates, err := parser.ParseWebhook(ctx, headers, body)
+	if err != nil {
+		if errors.Is(err, ports.ErrInvalidCarrierWebhook) {
+			return nil, application.ErrUnauthorized("invalid carrier webhook signature")
+		}
+		return nil, application.ErrValidation(err.Error())
+	}
+
+	byTrackingNumber := make(map[string][]ports.CarrierTrackingUpdate)
+	for _, update := range updates {
+		trackingNumber := strings.TrimSpace(update.TrackingNumber)
+		if trackingNumber != "" {
+			byTrackingNumber[trackingNumber] = append(byTrackingNumber[trackingNumber], update)
+		}
+	}
+
+	resp := &dto.CarrierWebhookResponse{Received: len(updates)}
+	for trackingNumber, trackingUpdates := range byTrackingNumber {
+		shipments, err := uc.shipmentRepo.GetByTracking(ctx, carrier, trackingNumber)
+		if err != nil {
+			return nil, application.WrapError(application.ErrCodeInternal, "failed to get shipments", err)
+		}
+		for _, shipment := range shipments {
+			if !applyCarrierUpdates(shipment, trackingUpdates, domain.ShipmentSourceWebhook) {
+				continue
+			}
+			if err := uc.saveShipment(ctx, shipment); err != nil {
+				return nil, err
+			}
+			resp.Updated++
+		}
+	}
+	return resp, nil
+}
+
+// PollCarriers asks the carriers with a tracker for the progress of the
+// shipments on their way that are due a check, returning how many changed.
+func (uc *shipmentUseCase) PollCarriers(ctx context.Context, now time.Time) (int, error) {
+	if len(uc.trackers) == 0 {
+		return 0, nil
+	}
+	carriers := make([]string, 0, len(uc.trackers))
+	for carrier := range uc.trackers {
+		carriers = append(carriers, carrier)
+	}
+
+	updated := 0
+	seen := make(map[uuid.UUID]bool)
+	for {
+		shipments, err := uc.shipmentRepo.GetDueForTracking(ctx, carriers, now.Add(-shipmentTrackingInterval), shipmentTrackingBatchSize)
+		if err != nil {
+			return updated, application.WrapError(application.ErrCodeInternal, "failed to get shipments due for tracking", err)
+		}
+
+		progressed := false
+		for _, shipment := range shipments {
+			if seen[shipment.ID] {
+				continue
+			}
+			seen[shipment.ID] = true
+			progressed = true
+
+			// A carrier that fails is asked again once the shipment is next
+			// due, so it does not hold up the shipments behind it
+			changed := false
+			if updates, err := uc.trackers[shipment.Carrier].Track(ctx, shipment.TrackingNumber); err == nil {
+				changed = applyCarrierUpdates(shipment, updates, domain.ShipmentSourcePolling)
+			}
+			shipment.MarkChecked(now)
+			if err := uc.saveShipment(ctx, shipment); err != nil {
+				continue
+			}
+			if changed {
+				updated++
+			}
+		}
+
+		// A shipment that failed to save is due again, so stop once a batch
+		// has nothing new
+		if len(shipments) < shipmentTrackingBatchSize || !progressed {
+			return updated, nil
+		}
+	}
+}
+
+// applyCarrierUpdates records a carrier's updates on a shipment, oldest
+// first, returning whether the shipment changed. Updates the shipment cannot
+// take, such as progress after delivery, are skipped.
+func applyCarrierUpdates(shipment *domain.Shipment, updates []ports.CarrierTrackingUpdate, source string) bool {
+	sorted := make([]ports.CarrierTrackingUpdate, len(updates))
+	copy(sorted, updates)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].OccurredAt.Before(sorted[j].OccurredAt)
+	})
+
+	changed := false
+	for _, update := range sorted {
+		if update.EstimatedDelivery != nil && !shipment.Status.IsFinal() &&
+			(shipment.EstimatedDelivery == nil || !shipment.EstimatedDelivery.Equal(*update.EstimatedDelivery)) {
+			estimatedDelivery := *update.EstimatedDelivery
+			shipment.EstimatedDelivery = &estimatedDelivery
+			changed = true
+		}
+
+		recorded, err := shipment.RecordTracking(domain.ShipmentTrackingEvent{
+			Status:      domain.ShipmentStatus(update.Status),
+			Description: update.Description,
+			Location:    update.Location,
+			OccurredAt:  update.OccurredAt,
+			Source:      source,
+		})
+		if err == nil && recorded {
+			changed = true
+		}
+	}
+	return changed
+}
+
+// ============================================================================
+// Helpers
+// ============================================================================
+
+func (uc *shipmentUseCase) getShipment(ctx context.Context, tenantID, shipmentID uuid.UUID) (*domain.Shipment, error) {
+	shipment, err := uc.shipmentRepo.GetByID(ctx, tenantID, shipmentID)
+	if err != nil {
+		if errors.Is(err, domain.ErrShipmentNotFound) {
+			return nil, application.ErrShipmentNotFound(shipmentID)
+		}
+		return nil, application.WrapError(application.ErrCodeInternal, "failed to get shipment", err)
+	}
+	return shipment, nil
+}
+
+func (uc *shipmentUseCase) saveShipment(ctx context.Context, shipment *domain.Shipment) error {
+	shipment.Version++
+	if err := uc.shipmentRepo.Update(ctx, shipment); err != nil {
+		if errors.Is(err, domain.ErrShipmentNotFound) {
+			return application.ErrConcurrentModification("shipment", shipment.ID)
+		}
+		return application.WrapError(application.ErrCodeInternal, "failed to update shipment", err)
+	}
+
+	uc.publishEvents(ctx, shipment)
+	return nil
+}
+
+// publishEvents publishes the shipment's events. The notification service
+// tells the recipient when the shipment is shipped and delivered.
+func (uc *shipmentUseCase) publishEvents(ctx context.Context, shipment *domain.Shipment) {
+	events := shipment.GetEvents()
+	shipment.ClearEvents()
+	if uc.eventPublisher == nil {
+		return
+	}
+
+	for _, event := range events {
+		var payload map[string]interface{}
+		if data, err := json.Marshal(event); err == nil {
+			json.Unmarshal(data, &payload)
+		}
+
+		uc.eventPublisher.Publish(ctx, ports.Event{
+			ID:            event.EventID().String(),
+			Type:          event.EventType(),
+			AggregateID:   event.AggregateID().String(),
+			AggregateType: event.AggregateType(),
+			TenantID:      event.TenantID().String(),
+			Payload:       payload,
+			OccurredAt:    event.OccurredAt(),
+			Version:       event.Version(),
+		})
+	}
+}
+
+func mapShipmentToResponse(shipment *domain.Shipment) *dto.ShipmentResponse {
+	resp := &dto.ShipmentResponse{
+		ID:                shipment.ID.String(),
+		DealID:            shipment.DealID.String(),
+		DealCode:          shipment.DealCode,
+		CustomerID:        shipment.CustomerID.String(),
+		CustomerName:      shipment.CustomerName,
+		Carrier:           shipment.Carrier,
+		TrackingNumber:    shipment.TrackingNumber,
+		TrackingURL:       shipment.TrackingURL,
+		Status:            string(shipment.Status),
+		RecipientName:     shipment.RecipientName,
+		RecipientEmail:    shipment.RecipientEmail,
+		EstimatedDelivery: shipment.EstimatedDelivery,
+		TrackingEvents:    make([]*dto.ShipmentTrackingEventResponse, len(shipment.TrackingEvents)),
+		ShippedAt:         shipment.ShippedAt,
+		DeliveredAt:       shipment.DeliveredAt,
+		LastCheckedAt:     shipment.LastCheckedAt,
+		CreatedBy:         shipment.CreatedBy.String(),
+		CreatedAt:         shipment.CreatedAt,
+		UpdatedAt:         shipment.UpdatedAt,
+		Version:           shipment.Version,
+	}
+	for i, event := range shipment.TrackingEvents {
+		resp.TrackingEvents[i] = &dto.ShipmentTrackingEventResponse{
+			Status:      string(event.Status),
+			Description: event.Description,
+			Location:    event.Location,
+			OccurredAt:  event.OccurredAt,
+			Source:      event.Source,
+		}
+	}
+	sort.SliceStable(resp.TrackingEvents, func(i, j int) bool {
+		return resp.TrackingEvents[i].OccurredAt.Before(resp.TrackingEvents[j].OccurredAt)
+	})
+	return resp
+}
@@ -0,0 +1,356 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/kilang-desa-murni/crm/internal/sales/application"
+	"github.com/kilang-desa-murni/crm/internal/sales/application/dto"
+	"github.com/kilang-desa-murni/crm/internal/sales/application/ports"
+	"github.com/kilang-desa-murni/crm/internal/sales/domain"
+)
+
+// ============================================================================
+// Mock Implementations for Shipment Tests
+// ============================================================================
+
+// MockShipmentRepository is a mock implementation of domain.ShipmentRepository.
+type MockShipmentRepository struct {
+	shipments map[uuid.UUID]*domain.Shipment
+}
+
+func NewMockShipmentRepository() *MockShipmentRepository {
+	return &MockShipmentRepository{shipments: make(map[uuid.UUID]*domain.Shipment)}
+}
+
+func (m *MockShipmentRepository) Create(ctx context.Context, shipment *domain.Shipment) error {
+	m.shipments[shipment.ID] = shipment
+	return nil
+}
+
+func (m *MockShipmentRepository) GetByID(ctx context.Context, tenantID, shipmentID uuid.UUID) (*domain.Shipment, error) {
+	shipment, ok := m.shipments[shipmentID]
+	if !ok || shipment.TenantID != tenantID {
+		return nil, domain.ErrShipmentNotFound
+	}
+	return shipment, nil
+}
+
+func (m *MockShipmentRepository) Update(ctx context.Context, shipment *domain.Shipment) error {
+	if _, ok := m.shipments[shipment.ID]; !ok {
+		return domain.ErrShipmentNotFound
+	}
+	m.shipments[shipment.ID] = shipment
+	return nil
+}
+
+func (m *MockShipmentRepository) ListByDeal(ctx context.Context, tenantID, dealID uuid.UUID) ([]*domain.Shipment, error) {
+	shipments := make([]*domain.Shipment, 0)
+	for _, shipment := range m.shipments {
+		if shipment.TenantID == tenantID && shipment.DealID == dealID {
+			shipments = append(shipments, shipment)
+		}
+	}
+	return shipments, nil
+}
+
+func (m *MockShipmentRepository) GetByTracking(ctx context.Context, carrier, trackingNumber string) ([]*domain.Shipment, error) {
+	shipments := make([]*domain.Shipment, 0)
+	for _, shipment := range m.shipments {
+		if shipment.Carrier == carrier && shipment.TrackingNumber == trackingNumber {
+			shipments = append(shipments, shipment)
+		}
+	}
+	return shipments, nil
+}
+
+func (m *MockShipmentRepository) GetDueForTracking(ctx context.Context, carriers []string, checkedBefore time.Time, limit int) ([]*domain.Shipment, error) {
+	shipments := make([]*domain.Shipment, 0)
+	for _, shipment := range m.shipments {
+		if shipment.Status.IsFinal() || (shipment.LastCheckedAt != nil && !shipment.LastCheckedAt.Before(checkedBefore)) {
+			continue
+		}
+		for _, carrier := range carriers {
+			if shipment.Carrier == carrier && len(shipments) < limit {
+				shipments = append(shipments, shipment)
+			}
+		}
+	}
+	return shipments, nil
+}
+
+// MockCarrierTracker is a mock implementation of ports.CarrierTracker.
+type MockCarrierTracker struct {
+	carrier string
+	updates map[string][]ports.CarrierTrackingUpdate
+	err     error
+}
+
+func (m *MockCarrierTracker) Carrier() string {
+	return m.carrier
+}
+
+func (m *MockCarrierTracker) Track(ctx context.Context, trackingNumber string) ([]ports.CarrierTrackingUpdate, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	return m.updates[trackingNumber], nil
+}
+
+// MockCarrierWebhookParser is a mock implementation of ports.CarrierWebhookParser.
+type MockCarrierWebhookParser struct {
+	carrier string
+	secret  string
+	updates []ports.CarrierTrackingUpdate
+}
+
+func (m *MockCarrierWebhookParser) Carrier() string {
+	return m.carrier
+}
+
+func (m *MockCarrierWebhookParser) ParseWebhook(ctx context.Context, headers map[string]string, body []byte) ([]ports.CarrierTrackingUpdate, error) {
+	if headers["X-Webhook-Signature"] != m.secret {
+		return nil, ports.ErrInvalidCarrierWebhook
+	}
+	return m.updates, nil
+}
+
+// ============================================================================
+// Helper Functions
+// ============================================================================
+
+func createShipmentTestShipment(t *testing.T, repo *MockShipmentRepository, tenantID uuid.UUID, carrier, trackingNumber string) *domain.Shipment {
+	t.Helper()
+
+	deal := createOrderTestDeal(tenantID)
+	shipment, err := domain.NewShipment(deal, carrier, trackingNumber, uuid.New())
+	if err != nil {
+		t.Fatalf("NewShipment() error = %v", err)
+	}
+	shipment.SetRecipient("Siti Aminah", "siti@example.com")
+	shipment.ClearEvents()
+	repo.shipments[shipment.ID] = shipment
+	return shipment
+}
+
+func shipmentPublishedTypes(publisher *DealMockEventPublisher) []string {
+	types := make([]string, 0, len(publisher.events))
+	for _, event := range publisher.events {
+		types = append(types, event.Type)
+	}
+	return types
+}
+
+func containsEventType(types []string, eventType string) bool {
+	for _, t := range types {
+		if t == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+// ============================================================================
+// ShipmentUseCase Tests
+// ============================================================================
+
+func TestShipmentUseCase_Create(t *testing.T) {
+	ctx := context.Background()
+	tenantID := uuid.New()
+	dealRepo := NewDealMockDealRepository()
+	shipmentRepo := NewMockShipmentRepository()
+	customerService := NewDealMockCustomerService()
+	eventPublisher := NewDealMockEventPublisher()
+	uc := NewShipmentUseCase(shipmentRepo, dealRepo, eventPublisher, customerService, nil, nil)
+
+	deal := createOrderTestDeal(tenantID)
+	dealRepo.deals[deal.ID] = deal
+	email := "Buyer@Example.com"
+	customerService.customers[deal.CustomerID] = &ports.CustomerInfo{ID: deal.CustomerID, Name: "Kedai Batik Siti", Email: &email}
+
+	shipment, err := uc.Create(ctx, tenantID, deal.ID, uuid.New(), &dto.CreateShipmentRequest{
+		Carrier:           "PosLaju",
+		TrackingNumber:    "EP123456789MY",
+		EstimatedDelivery: "2026-03-05",
+	})
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+
+	if shipment.Carrier != "poslaju" || shipment.Status != string(domain.ShipmentStatusPending) {
+		t.Errorf("Carrier, Status = %q, %q, want poslaju, pending", shipment.Carrier, shipment.Status)
+	}
+	if shipment.RecipientEmail != "buyer@example.com" || shipment.RecipientName != "Kedai Batik Siti" {
+		t.Errorf("Recipient = %q <%s>, want the customer", shipment.RecipientName, shipment.RecipientEmail)
+	}
+	if shipment.EstimatedDelivery == nil {
+		t.Error("EstimatedDelivery should be set")
+	}
+	if types := shipmentPublishedTypes(eventPublisher); len(types) != 1 || types[0] != "shipment.created" {
+		t.Errorf("published = %v, want shipment.created", types)
+	}
+
+	list, err := uc.ListByDeal(ctx, tenantID, deal.ID)
+	if err != nil {
+		t.Fatalf("ListByDeal() error = %v", err)
+	}
+	if len(list.Shipments) != 1 || list.Shipments[0].ID != shipment.ID {
+		t.Errorf("ListByDeal() = %+v, want the created shipment", list.Shipments)
+	}
+
+	if _, err := uc.ListByDeal(ctx, tenantID, uuid.New()); !application.IsNotFoundError(err) {
+		t.Errorf("ListByDeal() of unknown deal error = %v, want not found", err)
+	}
+}
+
+func TestShipmentUseCase_Create_CancelledDeal(t *testing.T) {
+	ctx := context.Background()
+	tenantID := uuid.New()
+	dealRepo := NewDealMockDealRepository()
+	uc := NewShipmentUseCase(NewMockShipmentRepository(), dealRepo, nil, nil, nil, nil)
+
+	deal := createOrderTestDeal(tenantID)
+	deal.Status = domain.DealStatusCancelled
+	dealRepo.deals[deal.ID] = deal
+
+	_, err := uc.Create(ctx, tenantID, deal.ID, uuid.New(), &dto.CreateShipmentRequest{Carrier: "dhl", TrackingNumber: "123"})
+	if err == nil {
+		t.Fatal("Create() should fail for a cancelled deal")
+	}
+}
+
+func TestShipmentUseCase_RecordTracking(t *testing.T) {
+	ctx := context.Background()
+	tenantID := uuid.New()
+	shipmentRepo := NewMockShipmentRepository()
+	eventPublisher := NewDealMockEventPublisher()
+	uc := NewShipmentUseCase(shipmentRepo, NewDealMockDealRepository(), eventPublisher, nil, nil, nil)
+
+	shipment := createShipmentTestShipment(t, shipmentRepo, tenantID, "citylink", "CL0001")
+
+	resp, err := uc.RecordTracking(ctx, tenantID, shipment.ID, &dto.RecordShipmentTrackingRequest{
+		Status:      "delivered",
+		Description: "Signed by receptionist",
+	})
+	if err != nil {
+		t.Fatalf("RecordTracking() error = %v", err)
+	}
+	if resp.Status != "delivered" || resp.DeliveredAt == nil || resp.ShippedAt == nil {
+		t.Errorf("RecordTracking() = %+v, want delivered with shipped and delivered times", resp)
+	}
+	if len(resp.TrackingEvents) != 1 || resp.TrackingEvents[0].Source != domain.ShipmentSourceManual {
+		t.Errorf("TrackingEvents = %+v, want one manual event", resp.TrackingEvents)
+	}
+	types := shipmentPublishedTypes(eventPublisher)
+	if !containsEventType(types, "shipment.shipped") || !containsEventType(types, "shipment.delivered") {
+		t.Errorf("published = %v, want shipment.shipped and shipment.delivered", types)
+	}
+
+	_, err = uc.RecordTracking(ctx, tenantID, shipment.ID, &dto.RecordShipmentTrackingRequest{Status: "in_transit"})
+	if code := application.GetAppError(err).Code; code != application.ErrCodeShipmentInvalidTransition {
+		t.Errorf("RecordTracking() after delivery code = %s, want %s", code, application.ErrCodeShipmentInvalidTransition)
+	}
+
+	if _, err := uc.GetByID(ctx, uuid.New(), shipment.ID); !application.IsNotFoundError(err) {
+		t.Errorf("GetByID() of another tenant error = %v, want not found", err)
+	}
+}
+
+func TestShipmentUseCase_ReceiveCarrierWebhook(t *testing.T) {
+	ctx := context.Background()
+	shipmentRepo := NewMockShipmentRepository()
+	eventPublisher := NewDealMockEventPublisher()
+	eta := time.Date(2026, 3, 5, 0, 0, 0, 0, time.UTC)
+	parser := &MockCarrierWebhookParser{
+		carrier: "PosLaju",
+		secret:  "valid",
+		updates: []ports.CarrierTrackingUpdate{
+			{TrackingNumber: "EP1", Status: "out_for_delivery", OccurredAt: time.Date(2026, 3, 4, 8, 0, 0, 0, time.UTC), EstimatedDelivery: &eta},
+			{TrackingNumber: "EP1", Status: "in_transit", OccurredAt: time.Date(2026, 3, 3, 8, 0, 0, 0, time.UTC)},
+			{TrackingNumber: "UNKNOWN", Status: "in_transit", OccurredAt: time.Date(2026, 3, 3, 8, 0, 0, 0, time.UTC)},
+		},
+	}
+	uc := NewShipmentUseCase(shipmentRepo, NewDealMockDealRepository(), eventPublisher, nil, nil, []ports.CarrierWebhookParser{parser})
+
+	shipment := createShipmentTestShipment(t, shipmentRepo, uuid.New(), "poslaju", "EP1")
+
+	resp, err := uc.ReceiveCarrierWebhook(ctx, "poslaju", map[string]string{"X-Webhook-Signature": "valid"}, []byte("{}"))
+	if err != nil {
+		t.Fatalf("ReceiveCarrierWebhook() error = %v", err)
+	}
+	if resp.Received != 3 || resp.Updated != 1 {
+		t.Errorf("ReceiveCarrierWebhook() = %+v, want 3 received and 1 updated", resp)
+	}
+	if shipment.Status != domain.ShipmentStatusOutForDelivery || len(shipment.TrackingEvents) != 2 {
+		t.Errorf("Status = %s with %d events, want out_for_delivery with 2", shipment.Status, len(shipment.TrackingEvents))
+	}
+	if shipment.EstimatedDelivery == nil || !shipment.EstimatedDelivery.Equal(eta) {
+		t.Errorf("EstimatedDelivery = %v, want %v", shipment.EstimatedDelivery, eta)
+	}
+	if !containsEventType(shipmentPublishedTypes(eventPublisher), "shipment.shipped") {
+		t.Errorf("published = %v, want shipment.shipped", shipmentPublishedTypes(eventPublisher))
+	}
+
+	_, err = uc.ReceiveCarrierWebhook(ctx, "poslaju", map[string]string{"X-Webhook-Signature": "forged"}, []byte("{}"))
+	if code := application.GetAppError(err).Code; code != application.ErrCodeUnauthorized {
+		t.Errorf("ReceiveCarrierWebhook() with bad signature code = %s, want %s", code, application.ErrCodeUnauthorized)
+	}
+
+	_, err = uc.ReceiveCarrierWebhook(ctx, "fedex", nil, []byte("{}"))
+	if code := application.GetAppError(err).Code; code != application.ErrCodeCarrierNotSupported {
+		t.Errorf("ReceiveCarrierWebhook() of unknown carrier code = %s, want %s", code, application.ErrCodeCarrierNotSupported)
+	}
+}
+
+func TestShipmentUseCase_PollCarriers(t *testing.T) {
+	ctx := context.Background()
+	tenantID := uuid.New()
+	shipmentRepo := NewMockShipmentRepository()
+	eventPublisher := NewDealMockEventPublisher()
+	now := time.Date(2026, 3, 3, 12, 0, 0, 0, time.UTC)
+
+	tracker := &MockCarrierTracker{
+		carrier: "poslaju",
+		updates: map[string][]ports.CarrierTrackingUpdate{
+			"EP1": {{TrackingNumber: "EP1", Status: "in_transit", OccurredAt: now.Add(-time.Hour)}},
+		},
+	}
+	failing := &MockCarrierTracker{carrier: "dhl", err: errors.New("carrier unavailable")}
+	uc := NewShipmentUseCase(shipmentRepo, NewDealMockDealRepository(), eventPublisher, nil, []ports.CarrierTracker{tracker, failing}, nil)
+
+	shipped := createShipmentTestShipment(t, shipmentRepo, tenantID, "poslaju", "EP1")
+	quiet := createShipmentTestShipment(t, shipmentRepo, tenantID, "poslaju", "EP2")
+	unavailable := createShipmentTestShipment(t, shipmentRepo, tenantID, "dhl", "DHL1")
+	manual := createShipmentTestShipment(t, shipmentRepo, tenantID, "lalamove", "LM1")
+
+	updated, err := uc.PollCarriers(ctx, now)
+	if err != nil {
+		t.Fatalf("PollCarriers() error = %v", err)
+	}
+	if updated != 1 {
+		t.Errorf("PollCarriers() = %d, want 1", updated)
+	}
+	if shipped.Status != domain.ShipmentStatusInTransit {
+		t.Errorf("Status = %s, want %s", shipped.Status, domain.ShipmentStatusInTransit)
+	}
+	for _, shipment := range []*domain.Shipment{shipped, quiet, unavailable} {
+		if shipment.LastCheckedAt == nil || !shipment.LastCheckedAt.Equal(now) {
+			t.Errorf("%s LastCheckedAt = %v, want %v", shipment.TrackingNumber, shipment.LastCheckedAt, now)
+		}
+	}
+	if manual.LastCheckedAt != nil {
+		t.Error("Shipments of carriers without a tracker should not be checked")
+	}
+	if !containsEventType(shipmentPublishedTypes(eventPublisher), "shipment.shipped") {
+		t.Errorf("published = %v, want shipment.shipped", shipmentPublishedTypes(eventPublisher))
+	}
+
+	// Shipments just checked are not due again
+	if updated, err := uc.PollCarriers(ctx, now.Add(time.Minute)); err != nil || updated != 0 {
+		t.Errorf("PollCarriers() again = %d, %v, want 0, nil", updated, err)
+	}
+}
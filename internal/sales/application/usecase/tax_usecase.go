@@ -0,0 +1,581 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/kilang-desa-murni/crm/internal/sales/application"
+	"github.com/kilang-desa-murni/crm/internal/sales/application/dto"
+	"github.com/kilang-desa-murni/crm/internal/sales/application/ports"
+	"github.com/kilang-desa-murni/crm/internal/sales/domain"
+)
+
+// quoteValidity is how long a generated quotation remains valid.
+const quoteValidity = 30 * 24 * time.Hour
+
+// ============================================================================
+// Tax Use Case Interface
+// ============================================================================
+
+// TaxUseCase defines the interface for tax configuration and taxed documents.
+type TaxUseCase interface {
+	ports.TaxRateResolver
+
+	// Settings
+	GetSettings(ctx context.Context, tenantID uuid.UUID) (*dto.TaxSettingsResponse, error)
+	UpdateSettings(ctx context.Context, tenantID uuid.UUID, req *dto.UpdateTaxSettingsRequest) (*dto.TaxSettingsResponse, error)
+
+	// Documents
+	GetQuote(ctx context.Context, tenantID, opportunityID uuid.UUID) (*dto.QuoteResponse, error)
+	RenderQuote(ctx context.Context, tenantID, opportunityID uuid.UUID, w io.Writer) error
+	RenderInvoice(ctx context.Context, tenantID, dealID, invoiceID uuid.UUID, w io.Writer) error
+}
+
+// ============================================================================
+// Tax Use Case Implementation
+// ============================================================================
+
+// taxUseCase implements TaxUseCase.
+type taxUseCase struct {
+	taxRepo         domain.TaxSettingsRepository
+	opportunityRepo domain.OpportunityRepository
+	dealRepo        domain.DealRepository
+	brandingRepo    domain.ReportBrandingRepository
+	fileStorage     ports.FileStorageService
+	renderer        ports.ReportRenderer
+	approvalRepo    domain.DiscountApprovalRepository
+	tenantBranding  ports.TenantBrandingProvider
+}
+
+// NewTaxUseCase creates a new tax use case. Documents are rendered with the
+// given renderer, which is expected to produce PDF files.
+func NewTaxUseCase(
+	taxRepo domain.TaxSettingsRepository,
+	opportunityRepo domain.OpportunityRepository,
+	dealRepo domain.DealRepository,
+	brandingRepo domain.ReportBrandingRepository,
+	fileStorage ports.FileStorageService,
+	renderer ports.ReportRenderer,
+	approvalRepo domain.DiscountApprovalRepository,
+	tenantBranding ports.TenantBrandingProvider,
+) TaxUseCase {
+	return &taxUseCase{
+		taxRepo:         taxRepo,
+		opportunityRepo: opportunityRepo,
+		dealRepo:        dealRepo,
+		brandingRepo:    brandingRepo,
+		fileStorage:     fileStorage,
+		renderer:        renderer,
+		approvalRepo:    approvalRepo,
+		tenantBranding:  tenantBranding,
+	}
+}
+
+// ============================================================================
+// Settings
+// ============================================================================
+
+// GetSettings returns the tenant's tax settings, or the defaults if none are configured.
+func (uc *taxUseCase) GetSettings(ctx context.Context, tenantID uuid.UUID) (*dto.TaxSettingsResponse, error) {
+	settings, err := uc.loadSettings(ctx, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	return mapTaxSettingsToResponse(settings), nil
+}
+
+// UpdateSettings updates the tenant's tax settings.
+func (uc *taxUseCase) UpdateSettings(ctx context.Context, tenantID uuid.UUID, req *dto.UpdateTaxSettingsRequest) (*dto.TaxSettingsResponse, error) {
+	settings, err := uc.loadSettings(ctx, tenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Enabled != nil {
+		settings.Enabled = *req.Enabled
+	}
+	if req.RegistrationNumber != nil {
+		settings.RegistrationNumber = strings.TrimSpace(*req.RegistrationNumber)
+	}
+	if req.PricesIncludeTax != nil {
+		settings.PricesIncludeTax = *req.PricesIncludeTax
+	}
+	if req.Rates != nil {
+		settings.Rates = make([]domain.TaxRate, len(*req.Rates))
+		for i, rate := range *req.Rates {
+			settings.Rates[i] = domain.TaxRate{
+				Code:      strings.ToUpper(strings.TrimSpace(rate.Code)),
+				Name:      strings.TrimSpace(rate.Name),
+				Type:      domain.TaxType(rate.Type),
+				Rate:      rate.Rate,
+				IsDefault: rate.IsDefault,
+			}
+		}
+	}
+
+	if err := settings.Validate(); err != nil {
+		return nil, application.ErrValidation(err.Error())
+	}
+
+	settings.UpdatedAt = time.Now().UTC()
+	if err := uc.taxRepo.Upsert(ctx, settings); err != nil {
+		return nil, application.WrapError(application.ErrCodeInternal, "failed to save tax settings", err)
+	}
+
+	return mapTaxSettingsToResponse(settings), nil
+}
+
+// ResolveTaxRate returns the rate for a tax code, or the tenant's default rate
+// when code is empty. It returns nil while tax is disabled for the tenant.
+func (uc *taxUseCase) ResolveTaxRate(ctx context.Context, tenantID uuid.UUID, code string) (*ports.AppliedTaxRate, error) {
+	settings, err := uc.loadSettings(ctx, tenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	rate, err := settings.ResolveRate(code)
+	if err != nil {
+		if errors.Is(err, domain.ErrTaxRateNotFound) {
+			return nil, application.ErrTaxRateNotFound(code)
+		}
+		return nil, err
+	}
+	if rate == nil {
+		return nil, nil
+	}
+
+	return &ports.AppliedTaxRate{
+		Code:      rate.Code,
+		Name:      rate.Name,
+		Rate:      rate.Rate,
+		Inclusive: settings.PricesIncludeTax,
+	}, nil
+}
+
+func (uc *taxUseCase) loadSettings(ctx context.Context, tenantID uuid.UUID) (*domain.TaxSettings, error) {
+	settings, err := uc.taxRepo.Get(ctx, tenantID)
+	if err != nil {
+		return nil, application.WrapError(application.ErrCodeInternal, "failed to get tax settings", err)
+	}
+	if settings == nil {
+		return domain.DefaultTaxSettings(tenantID), nil
+	}
+	return settings, nil
+}
+
+// ============================================================================
+// Documents
+// ============================================================================
+
+// GetQuote returns the priced products and tax breakdown of an opportunity.
+func (uc *taxUseCase) GetQuote(ctx context.Context, tenantID, opportunityID uuid.UUID) (*dto.QuoteResponse, error) {
+	opportunity, err := uc.opportunityRepo.GetByID(ctx, tenantID, opportunityID)
+	if err != nil {
+		return nil, application.ErrOpportunityNotFound(opportunityID)
+	}
+
+	settings, err := uc.loadSettings(ctx, tenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	resp := &dto.QuoteResponse{
+		OpportunityID:    opportunity.ID.String(),
+		OpportunityCode:  opportunity.Code,
+		CustomerName:     opportunity.CustomerName,
+		Currency:         opportunity.Amount.Currency,
+		PricesIncludeTax: settings.PricesIncludeTax,
+		Products:         make([]*dto.OpportunityProductResponseDTO, len(opportunity.Products)),
+		Tax:              mapTaxSummaryToDTO(opportunity.TaxSummary()),
+		QuoteDate:        now,
+		ValidUntil:       now.Add(quoteValidity),
+	}
+	if settings.Enabled {
+		resp.RegistrationNumber = settings.RegistrationNumber
+	}
+	for i, product := range opportunity.Products {
+		resp.Products[i] = mapOpportunityProductToResponse(product)
+	}
+
+	return resp, nil
+}
+
+// RenderQuote writes a quotation for an opportunity's products as a PDF. It
+// fails while the opportunity's discount waits for approval.
+func (uc *taxUseCase) RenderQuote(ctx context.Context, tenantID, opportunityID uuid.UUID, w io.Writer) error {
+	if uc.renderer == nil {
+		return application.ErrServiceUnavailable("pdf renderer")
+	}
+
+	opportunity, err := uc.opportunityRepo.GetByID(ctx, tenantID, opportunityID)
+	if err != nil {
+		return application.ErrOpportunityNotFound(opportunityID)
+	}
+
+	// The PDF is what is sent to the customer, so discounts above the approval
+	// rules must be approved first
+	if err := requireDiscountApproval(ctx, uc.approvalRepo, opportunity); err != nil {
+		return err
+	}
+
+	doc, labels, settings, err := uc.newDocument(ctx, tenantID)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now().UTC()
+	doc.Title = labels.quotation
+	doc.Subtitle = opportunity.Name
+	doc.Details = []ports.ReportField{
+		{Label: labels.customer, Value: opportunity.CustomerName},
+		{Label: labels.quoteNumber, Value: opportunity.Code},
+		{Label: labels.date, Value: formatReportDate(now, labels.locale)},
+		{Label: labels.validUntil, Value: formatReportDate(now.Add(quoteValidity), labels.locale)},
+	}
+	doc.Details = append(doc.Details, labels.registrationField(settings)...)
+
+	for i, p := range opportunity.Products {
+		doc.Rows = append(doc.Rows, labels.lineCells(i+1, p.ProductName, p.Quantity, p.UnitPrice, p.Discount, p.TaxCode, p.TaxAmount, p.TotalPrice))
+	}
+	doc.Summary = labels.summaryFields(opportunity.TaxSummary(), settings)
+
+	if err := uc.renderer.Render(ctx, w, doc); err != nil {
+		return application.WrapError(application.ErrCodeInternal, "failed to render quotation", err)
+	}
+	return nil
+}
+
+// RenderInvoice writes a deal invoice and its tax breakdown as a PDF.
+func (uc *taxUseCase) RenderInvoice(ctx context.Context, tenantID, dealID, invoiceID uuid.UUID, w io.Writer) error {
+	if uc.renderer == nil {
+		return application.ErrServiceUnavailable("pdf renderer")
+	}
+
+	deal, err := uc.dealRepo.GetByID(ctx, tenantID, dealID)
+	if err != nil {
+		return application.ErrDealNotFound(dealID)
+	}
+
+	var invoice *domain.Invoice
+	for i := range deal.Invoices {
+		if deal.Invoices[i].ID == invoiceID {
+			invoice = &deal.Invoices[i]
+			break
+		}
+	}
+	if invoice == nil {
+		return application.ErrDealInvoiceNotFound(dealID, invoiceID)
+	}
+
+	doc, labels, settings, err := uc.newDocument(ctx, tenantID)
+	if err != nil {
+		return err
+	}
+
+	// Only SST-registered businesses may issue tax invoices
+	doc.Title = labels.invoice
+	if settings.Enabled {
+		doc.Title = labels.taxInvoice
+	}
+	doc.Subtitle = deal.Name
+	doc.Details = []ports.ReportField{
+		{Label: labels.customer, Value: deal.CustomerName},
+		{Label: labels.invoiceNumber, Value: invoice.InvoiceNumber},
+		{Label: labels.date, Value: formatReportDate(invoice.CreatedAt, labels.locale)},
+		{Label: labels.dueDate, Value: formatReportDate(invoice.DueDate, labels.locale)},
+	}
+	doc.Details = append(doc.Details, labels.registrationField(settings)...)
+	if invoice.Amount.Amount != deal.TotalAmount.Amount {
+		doc.Details = append(doc.Details, ports.ReportField{Label: labels.dealTotal, Value: labels.money(deal.TotalAmount)})
+	}
+
+	for i, li := range deal.LineItems {
+		discount := li.Discount
+		if li.DiscountType != "percentage" {
+			discount = 0
+		}
+		doc.Rows = append(doc.Rows, labels.lineCells(i+1, li.ProductName, li.Quantity, li.UnitPrice, discount, li.TaxCode, li.TaxAmount, li.Total))
+	}
+	doc.Summary = labels.summaryFields(domain.TaxSummary{
+		Subtotal: invoice.Subtotal,
+		TotalTax: invoice.TaxAmount,
+		Total:    invoice.Amount,
+		Lines:    invoice.TaxLines,
+	}, settings)
+
+	if err := uc.renderer.Render(ctx, w, doc); err != nil {
+		return application.WrapError(application.ErrCodeInternal, "failed to render invoice", err)
+	}
+	return nil
+}
+
+// newDocument builds a branded document with the line item columns of a quote or invoice.
+func (uc *taxUseCase) newDocument(ctx context.Context, tenantID uuid.UUID) (*ports.ReportDocument, documentLabels, *domain.TaxSettings, error) {
+	settings, err := uc.loadSettings(ctx, tenantID)
+	if err != nil {
+		return nil, documentLabels{}, nil, err
+	}
+
+	branding, err := loadReportBranding(ctx, uc.brandingRepo, tenantID)
+	if err != nil {
+		return nil, documentLabels{}, nil, err
+	}
+	labels := documentLabelsFor(branding)
+
+	now := time.Now().UTC()
+	doc := &ports.ReportDocument{
+		Branding: reportBrandingAsset(ctx, uc.fileStorage, uc.tenantBranding, tenantID, branding),
+		Columns: []ports.ReportColumn{
+			{Header: labels.number, Numeric: true},
+			{Header: labels.item},
+			{Header: labels.quantity, Numeric: true},
+			{Header: labels.unitPrice, Numeric: true, Decimals: 2},
+			{Header: labels.discount, Numeric: true, Decimals: 1},
+			{Header: labels.taxCode},
+			{Header: labels.taxAmount, Numeric: true, Decimals: 2},
+			{Header: labels.amount, Numeric: true, Decimals: 2},
+		},
+		FooterText:  fmt.Sprintf(reportLabelsFor(branding.Locale).generatedFormat, now.Format("2006-01-02 15:04 MST")),
+		GeneratedAt: now,
+	}
+	return doc, labels, settings, nil
+}
+
+// ============================================================================
+// Document Localization
+// ============================================================================
+
+// documentLabels holds the translated text and formatting used in quotes and invoices.
+type documentLabels struct {
+	locale          domain.ReportLocale
+	currencyDisplay domain.CurrencyDisplay
+
+	quotation          string
+	invoice            string
+	taxInvoice         string
+	customer           string
+	quoteNumber        string
+	invoiceNumber      string
+	date               string
+	validUntil         string
+	dueDate            string
+	dealTotal          string
+	registrationNumber string
+	number             string
+	item               string
+	quantity           string
+	unitPrice          string
+	discount           string
+	taxCode            string
+	taxAmount          string
+	amount             string
+	subtotal           string
+	total              string
+	pricesIncludeTax   string
+}
+
+var documentLabelsByLocale = map[domain.ReportLocale]documentLabels{
+	domain.ReportLocaleEnglish: {
+		quotation:          "Quotation",
+		invoice:            "Invoice",
+		taxInvoice:         "Tax Invoice",
+		customer:           "Customer",
+		quoteNumber:        "Quotation No.",
+		invoiceNumber:      "Invoice No.",
+		date:               "Date",
+		validUntil:         "Valid Until",
+		dueDate:            "Due Date",
+		dealTotal:          "Deal Total",
+		registrationNumber: "SST Registration No.",
+		number:             "No.",
+		item:               "Item",
+		quantity:           "Qty",
+		unitPrice:          "Unit Price",
+		discount:           "Disc %",
+		taxCode:            "Tax",
+		taxAmount:          "Tax Amount",
+		amount:             "Amount",
+		subtotal:           "Subtotal (excl. tax)",
+		total:              "Total",
+		pricesIncludeTax:   "Prices include tax",
+	},
+	domain.ReportLocaleMalay: {
+		quotation:          "Sebut Harga",
+		invoice:            "Invois",
+		taxInvoice:         "Invois Cukai",
+		customer:           "Pelanggan",
+		quoteNumber:        "No. Sebut Harga",
+		invoiceNumber:      "No. Invois",
+		date:               "Tarikh",
+		validUntil:         "Sah Sehingga",
+		dueDate:            "Tarikh Akhir",
+		dealTotal:          "Jumlah Urus Niaga",
+		registrationNumber: "No. Pendaftaran SST",
+		number:             "No.",
+		item:               "Item",
+		quantity:           "Kuantiti",
+		unitPrice:          "Harga Seunit",
+		discount:           "Diskaun %",
+		taxCode:            "Cukai",
+		taxAmount:          "Amaun Cukai",
+		amount:             "Amaun",
+		subtotal:           "Jumlah Kecil (tanpa cukai)",
+		total:              "Jumlah",
+		pricesIncludeTax:   "Harga termasuk cukai",
+	},
+}
+
+// documentLabelsFor returns the labels for the branding locale, falling back to English.
+func documentLabelsFor(branding *domain.ReportBranding) documentLabels {
+	labels, ok := documentLabelsByLocale[branding.Locale]
+	if !ok {
+		labels = documentLabelsByLocale[domain.ReportLocaleEnglish]
+	}
+	labels.locale = branding.Locale
+	labels.currencyDisplay = branding.CurrencyDisplay
+	return labels
+}
+
+func (l documentLabels) money(m domain.Money) string {
+	return formatReportMoney(m.Amount, m.Currency, l.currencyDisplay)
+}
+
+// lineCells returns the table cells of a priced line.
+func (l documentLabels) lineCells(number int, name string, quantity int, unitPrice domain.Money, discount float64, taxCode string, taxAmount, total domain.Money) []ports.ReportCell {
+	return []ports.ReportCell{
+		countCell(int64(number)),
+		{Display: name},
+		countCell(int64(quantity)),
+		moneyCell(unitPrice.Amount, unitPrice.Currency, l.currencyDisplay),
+		percentCell(discount),
+		{Display: taxCode},
+		moneyCell(taxAmount.Amount, total.Currency, l.currencyDisplay),
+		moneyCell(total.Amount, total.Currency, l.currencyDisplay),
+	}
+}
+
+// registrationField returns the SST registration number detail of a registered tenant.
+func (l documentLabels) registrationField(settings *domain.TaxSettings) []ports.ReportField {
+	if !settings.Enabled || settings.RegistrationNumber == "" {
+		return nil
+	}
+	return []ports.ReportField{{Label: l.registrationNumber, Value: settings.RegistrationNumber}}
+}
+
+// summaryFields returns the subtotal, a line per tax rate and the total.
+func (l documentLabels) summaryFields(summary domain.TaxSummary, settings *domain.TaxSettings) []ports.ReportField {
+	fields := []ports.ReportField{{Label: l.subtotal, Value: l.money(summary.Subtotal)}}
+	for _, line := range summary.Lines {
+		label := line.Name
+		if line.Rate > 0 && !strings.Contains(label, "%") {
+			label += " " + strconv.FormatFloat(line.Rate, 'f', -1, 64) + "%"
+		}
+		fields = append(fields, ports.ReportField{Label: label, Value: l.money(line.TaxAmount)})
+	}
+	fields = append(fields, ports.ReportField{Label: l.total, Value: l.money(summary.Total), Emphasis: true})
+	if settings.Enabled && settings.PricesIncludeTax {
+		fields = append(fields, ports.ReportField{Label: l.pricesIncludeTax})
+	}
+	return fields
+}
+
+// ============================================================================
+// Line Tax Resolution
+// ============================================================================
+
+// lineTax is the tax rate to apply to a quoted or invoiced line.
+type lineTax struct {
+	rate      *domain.TaxRate
+	inclusive bool
+}
+
+// resolveLineTax resolves the tax for a line from an optional tax code; a nil
+// code selects the tenant's default rate. It returns nil when tax is not
+// configured, in which case the line keeps its current tax.
+func resolveLineTax(ctx context.Context, resolver ports.TaxRateResolver, tenantID uuid.UUID, code *string) (*lineTax, error) {
+	if resolver == nil {
+		return nil, nil
+	}
+
+	taxCode := ""
+	if code != nil {
+		taxCode = strings.TrimSpace(*code)
+	}
+
+	applied, err := resolver.ResolveTaxRate(ctx, tenantID, taxCode)
+	if err != nil {
+		return nil, err
+	}
+	if applied == nil {
+		return nil, nil
+	}
+
+	return &lineTax{
+		rate:      &domain.TaxRate{Code: applied.Code, Name: applied.Name, Rate: applied.Rate},
+		inclusive: applied.Inclusive,
+	}, nil
+}
+
+// ============================================================================
+// Mapping Helpers
+// ============================================================================
+
+func mapTaxSettingsToResponse(settings *domain.TaxSettings) *dto.TaxSettingsResponse {
+	resp := &dto.TaxSettingsResponse{
+		Enabled:            settings.Enabled,
+		RegistrationNumber: settings.RegistrationNumber,
+		PricesIncludeTax:   settings.PricesIncludeTax,
+		Rates:              make([]dto.TaxRateDTO, len(settings.Rates)),
+	}
+	for i, rate := range settings.Rates {
+		resp.Rates[i] = dto.TaxRateDTO{
+			Code:      rate.Code,
+			Name:      rate.Name,
+			Type:      string(rate.Type),
+			Rate:      rate.Rate,
+			IsDefault: rate.IsDefault,
+		}
+	}
+	if !settings.UpdatedAt.IsZero() {
+		updatedAt := settings.UpdatedAt
+		resp.UpdatedAt = &updatedAt
+	}
+	return resp
+}
+
+func mapTaxSummaryToDTO(summary domain.TaxSummary) dto.TaxSummaryDTO {
+	return dto.TaxSummaryDTO{
+		Subtotal: moneyToDTO(summary.Subtotal),
+		TotalTax: moneyToDTO(summary.TotalTax),
+		Total:    moneyToDTO(summary.Total),
+		Lines:    mapTaxLinesToDTO(summary.Lines),
+	}
+}
+
+func mapTaxLinesToDTO(lines []domain.TaxLine) []dto.TaxLineDTO {
+	if len(lines) == 0 {
+		return nil
+	}
+	result := make([]dto.TaxLineDTO, len(lines))
+	for i, line := range lines {
+		result[i] = dto.TaxLineDTO{
+			Code:          line.Code,
+			Name:          line.Name,
+			Rate:          line.Rate,
+			TaxableAmount: moneyToDTO(line.TaxableAmount),
+			TaxAmount:     moneyToDTO(line.TaxAmount),
+		}
+	}
+	return result
+}
+
+// Ensure taxUseCase implements ports.TaxRateResolver
+var _ ports.TaxRateResolver = (*taxUseCase)(nil)
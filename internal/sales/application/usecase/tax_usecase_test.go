@@ -0,0 +1,179 @@
+package usecase
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"github.com/kilang-desa-murni/crm/internal/sales/application"
+	"github.com/kilang-desa-murni/crm/internal/sales/application/dto"
+	"github.com/kilang-desa-murni/crm/internal/sales/domain"
+)
+
+// ============================================================================
+// Mock Implementations for Tax Tests
+// ============================================================================
+
+// MockTaxSettingsRepository is a mock implementation of domain.TaxSettingsRepository.
+type MockTaxSettingsRepository struct {
+	settings map[uuid.UUID]*domain.TaxSettings
+}
+
+func NewMockTaxSettingsRepository() *MockTaxSettingsRepository {
+	return &MockTaxSettingsRepository{settings: make(map[uuid.UUID]*domain.TaxSettings)}
+}
+
+func (m *MockTaxSettingsRepository) Get(ctx context.Context, tenantID uuid.UUID) (*domain.TaxSettings, error) {
+	return m.settings[tenantID], nil
+}
+
+func (m *MockTaxSettingsRepository) Upsert(ctx context.Context, settings *domain.TaxSettings) error {
+	m.settings[settings.TenantID] = settings
+	return nil
+}
+
+func newRegisteredTaxSettings(tenantID uuid.UUID) *domain.TaxSettings {
+	settings := domain.DefaultTaxSettings(tenantID)
+	settings.Enabled = true
+	settings.RegistrationNumber = "W10-1808-32000123"
+	return settings
+}
+
+// ============================================================================
+// Tax Use Case Tests
+// ============================================================================
+
+func TestTaxUseCase_GetSettings_Defaults(t *testing.T) {
+	uc := NewTaxUseCase(NewMockTaxSettingsRepository(), nil, nil, nil, nil, nil, nil, nil)
+
+	settings, err := uc.GetSettings(context.Background(), uuid.New())
+	if err != nil {
+		t.Fatalf("GetSettings() error = %v", err)
+	}
+	if settings.Enabled {
+		t.Error("GetSettings() Enabled = true, want tax disabled by default")
+	}
+	if len(settings.Rates) != len(domain.DefaultTaxRates()) {
+		t.Errorf("GetSettings() rates = %d, want %d", len(settings.Rates), len(domain.DefaultTaxRates()))
+	}
+}
+
+func TestTaxUseCase_UpdateSettings(t *testing.T) {
+	repo := NewMockTaxSettingsRepository()
+	uc := NewTaxUseCase(repo, nil, nil, nil, nil, nil, nil, nil)
+	tenantID := uuid.New()
+	enabled := true
+
+	_, err := uc.UpdateSettings(context.Background(), tenantID, &dto.UpdateTaxSettingsRequest{Enabled: &enabled})
+	var appErr *application.AppError
+	if !errors.As(err, &appErr) || appErr.Code != application.ErrCodeValidation {
+		t.Fatalf("UpdateSettings() without registration error = %v, want validation error", err)
+	}
+
+	registration := " W10-1808-32000123 "
+	resp, err := uc.UpdateSettings(context.Background(), tenantID, &dto.UpdateTaxSettingsRequest{
+		Enabled:            &enabled,
+		RegistrationNumber: &registration,
+		Rates: &[]dto.TaxRateDTO{
+			{Code: "st-8", Name: "Service Tax 8%", Type: "service_tax", Rate: 8, IsDefault: true},
+		},
+	})
+	if err != nil {
+		t.Fatalf("UpdateSettings() error = %v", err)
+	}
+	if !resp.Enabled || resp.RegistrationNumber != "W10-1808-32000123" {
+		t.Errorf("UpdateSettings() = %+v", resp)
+	}
+	if len(resp.Rates) != 1 || resp.Rates[0].Code != "ST-8" {
+		t.Errorf("UpdateSettings() rates = %+v", resp.Rates)
+	}
+	if repo.settings[tenantID] == nil {
+		t.Error("UpdateSettings() did not save settings")
+	}
+}
+
+func TestTaxUseCase_ResolveTaxRate(t *testing.T) {
+	repo := NewMockTaxSettingsRepository()
+	uc := NewTaxUseCase(repo, nil, nil, nil, nil, nil, nil, nil)
+	ctx := context.Background()
+	tenantID := uuid.New()
+
+	rate, err := uc.ResolveTaxRate(ctx, tenantID, "")
+	if err != nil || rate != nil {
+		t.Fatalf("ResolveTaxRate() while disabled = (%v, %v), want (nil, nil)", rate, err)
+	}
+
+	settings := newRegisteredTaxSettings(tenantID)
+	settings.PricesIncludeTax = true
+	repo.settings[tenantID] = settings
+
+	rate, err = uc.ResolveTaxRate(ctx, tenantID, "")
+	if err != nil || rate == nil || rate.Code != "SST-10" || !rate.Inclusive {
+		t.Errorf("ResolveTaxRate() default = (%+v, %v), want inclusive SST-10", rate, err)
+	}
+
+	_, err = uc.ResolveTaxRate(ctx, tenantID, "VAT")
+	var appErr *application.AppError
+	if !errors.As(err, &appErr) || appErr.Code != application.ErrCodeTaxRateNotFound {
+		t.Errorf("ResolveTaxRate() unknown code error = %v, want %s", err, application.ErrCodeTaxRateNotFound)
+	}
+}
+
+func TestTaxUseCase_GetQuote(t *testing.T) {
+	repo := NewMockTaxSettingsRepository()
+	opportunityRepo := NewMockOpportunityRepository()
+	uc := NewTaxUseCase(repo, opportunityRepo, nil, &MockReportBrandingRepository{}, NewMockFileStorageService(), &MockReportRenderer{format: "pdf"}, nil, nil)
+	ctx := context.Background()
+	tenantID := uuid.New()
+	repo.settings[tenantID] = newRegisteredTaxSettings(tenantID)
+
+	product := domain.OpportunityProduct{
+		ID:          uuid.New(),
+		ProductName: "Batik Sarong",
+		Quantity:    2,
+		UnitPrice:   domain.Money{Amount: 5000, Currency: "MYR"},
+	}
+	product.ApplyTaxRate(&domain.TaxRate{Code: "SST-10", Name: "Sales Tax 10%", Rate: 10}, false)
+
+	opp := &domain.Opportunity{
+		ID:           uuid.New(),
+		TenantID:     tenantID,
+		Name:         "Corporate uniforms",
+		CustomerName: "Syarikat Maju",
+		Amount:       domain.Money{Amount: 11000, Currency: "MYR"},
+		Products:     []domain.OpportunityProduct{product},
+	}
+	opportunityRepo.opportunities[opp.ID] = opp
+
+	quote, err := uc.GetQuote(ctx, tenantID, opp.ID)
+	if err != nil {
+		t.Fatalf("GetQuote() error = %v", err)
+	}
+	if quote.RegistrationNumber != "W10-1808-32000123" {
+		t.Errorf("GetQuote() RegistrationNumber = %q", quote.RegistrationNumber)
+	}
+	if quote.Tax.Subtotal.Amount != 10000 || quote.Tax.TotalTax.Amount != 1000 || quote.Tax.Total.Amount != 11000 {
+		t.Errorf("GetQuote() tax = %+v", quote.Tax)
+	}
+	if len(quote.Products) != 1 || quote.Products[0].TaxAmount.Amount != 1000 {
+		t.Errorf("GetQuote() products = %+v", quote.Products)
+	}
+	if !quote.ValidUntil.After(quote.QuoteDate) {
+		t.Errorf("GetQuote() ValidUntil = %v, want after %v", quote.ValidUntil, quote.QuoteDate)
+	}
+
+	var buf bytes.Buffer
+	if err := uc.RenderQuote(ctx, tenantID, opp.ID, &buf); err != nil {
+		t.Fatalf("RenderQuote() error = %v", err)
+	}
+	if buf.String() != "Quotation" {
+		t.Errorf("RenderQuote() title = %q, want Quotation", buf.String())
+	}
+
+	if _, err := uc.GetQuote(ctx, tenantID, uuid.New()); err == nil {
+		t.Error("GetQuote() for unknown opportunity returned nil error")
+	}
+}
@@ -0,0 +1,328 @@
+package domain
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Accounting errors
+var (
+	ErrAccountingSyncNotFound          = errors.New("accounting sync record not found")
+	ErrAccountingSyncAlreadyExists     = errors.New("record is already synced to accounting")
+	ErrInvalidAccountingProvider       = errors.New("invalid accounting provider")
+	ErrInvalidAccountingConnectorURL   = errors.New("accounting connector URL must be an absolute http or https URL")
+	ErrInvalidAccountingRecordType     = errors.New("invalid accounting record type")
+	ErrInvalidAccountingSyncTransition = errors.New("invalid accounting sync status transition")
+)
+
+const (
+	// MaxAccountingSyncAttempts is how many times a record is pushed before
+	// its sync fails and must be re-synced by hand.
+	MaxAccountingSyncAttempts = 5
+)
+
+// accountingSyncBackoff is the wait before each retry of a failed push.
+var accountingSyncBackoff = []time.Duration{
+	time.Minute,
+	5 * time.Minute,
+	30 * time.Minute,
+	2 * time.Hour,
+}
+
+// AccountingProvider is the accounting system a tenant keeps its books in.
+type AccountingProvider string
+
+const (
+	AccountingProviderXero       AccountingProvider = "xero"
+	AccountingProviderSQLAccount AccountingProvider = "sql_account"
+	AccountingProviderAutoCount  AccountingProvider = "autocount"
+)
+
+// IsValid checks if the accounting provider is valid.
+func (p AccountingProvider) IsValid() bool {
+	switch p {
+	case AccountingProviderXero, AccountingProviderSQLAccount, AccountingProviderAutoCount:
+		return true
+	}
+	return false
+}
+
+// AccountingMapping maps CRM records to the tenant's chart of accounts.
+type AccountingMapping struct {
+	// SalesAccount is the revenue account invoice lines are posted to.
+	SalesAccount string `json:"sales_account"`
+	// PaymentAccount is the bank or cash account payments are received into.
+	PaymentAccount string `json:"payment_account"`
+	// TaxCodes maps CRM tax rate codes to the accounting system's tax codes.
+	TaxCodes map[string]string `json:"tax_codes,omitempty"`
+	// DefaultTaxCode is used for untaxed invoices and unmapped tax rates.
+	DefaultTaxCode string `json:"default_tax_code,omitempty"`
+}
+
+// TaxCode returns the accounting tax code of a CRM tax rate code.
+func (m AccountingMapping) TaxCode(code string) string {
+	if mapped, ok := m.TaxCodes[code]; ok && mapped != "" {
+		return mapped
+	}
+	return m.DefaultTaxCode
+}
+
+// AccountingConnection is a tenant's link to its accounting system. Invoices
+// and payments are pushed to the connector at URL, which posts payments
+// received in the accounting system back. Both directions are signed with
+// Secret.
+type AccountingConnection struct {
+	TenantID  uuid.UUID          `json:"tenant_id"`
+	Provider  AccountingProvider `json:"provider"`
+	URL       string             `json:"url"`
+	Secret    string             `json:"secret"`
+	Enabled   bool               `json:"enabled"`
+	Mapping   AccountingMapping  `json:"mapping"`
+	CreatedAt time.Time          `json:"created_at"`
+	UpdatedAt time.Time          `json:"updated_at"`
+}
+
+// NewAccountingConnection creates an enabled connection with a new secret.
+func NewAccountingConnection(tenantID uuid.UUID, provider AccountingProvider, connectorURL string) (*AccountingConnection, error) {
+	if !provider.IsValid() {
+		return nil, ErrInvalidAccountingProvider
+	}
+
+	now := time.Now().UTC()
+	connection := &AccountingConnection{
+		TenantID:  tenantID,
+		Provider:  provider,
+		Enabled:   true,
+		CreatedAt: now,
+	}
+	if err := connection.SetURL(connectorURL); err != nil {
+		return nil, err
+	}
+	if err := connection.RotateSecret(); err != nil {
+		return nil, err
+	}
+	return connection, nil
+}
+
+// SetProvider changes the accounting system the connector serves.
+func (c *AccountingConnection) SetProvider(provider AccountingProvider) error {
+	if !provider.IsValid() {
+		return ErrInvalidAccountingProvider
+	}
+	c.Provider = provider
+	c.UpdatedAt = time.Now().UTC()
+	return nil
+}
+
+// SetURL changes the URL records are pushed to.
+func (c *AccountingConnection) SetURL(connectorURL string) error {
+	connectorURL = strings.TrimSpace(connectorURL)
+	u, err := url.Parse(connectorURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return ErrInvalidAccountingConnectorURL
+	}
+	c.URL = connectorURL
+	c.UpdatedAt = time.Now().UTC()
+	return nil
+}
+
+// SetMapping replaces the account and tax code mapping.
+func (c *AccountingConnection) SetMapping(mapping AccountingMapping) {
+	mapping.SalesAccount = strings.TrimSpace(mapping.SalesAccount)
+	mapping.PaymentAccount = strings.TrimSpace(mapping.PaymentAccount)
+	mapping.DefaultTaxCode = strings.TrimSpace(mapping.DefaultTaxCode)
+	taxCodes := make(map[string]string, len(mapping.TaxCodes))
+	for code, taxCode := range mapping.TaxCodes {
+		if code = strings.TrimSpace(code); code != "" {
+			taxCodes[code] = strings.TrimSpace(taxCode)
+		}
+	}
+	mapping.TaxCodes = taxCodes
+	c.Mapping = mapping
+	c.UpdatedAt = time.Now().UTC()
+}
+
+// RotateSecret replaces the signing secret. The connector must be given the
+// new secret to keep verifying pushes and signing its callbacks.
+func (c *AccountingConnection) RotateSecret() error {
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return fmt.Errorf("failed to generate accounting secret: %w", err)
+	}
+	c.Secret = hex.EncodeToString(secret)
+	c.UpdatedAt = time.Now().UTC()
+	return nil
+}
+
+// AccountingRecordType is the kind of CRM record synced to accounting.
+type AccountingRecordType string
+
+const (
+	AccountingRecordInvoice AccountingRecordType = "invoice"
+	AccountingRecordPayment AccountingRecordType = "payment"
+)
+
+// IsValid checks if the record type is valid.
+func (t AccountingRecordType) IsValid() bool {
+	return t == AccountingRecordInvoice || t == AccountingRecordPayment
+}
+
+// AccountingSyncStatus represents where a record is in its sync to accounting.
+type AccountingSyncStatus string
+
+const (
+	// AccountingSyncStatusPending is a record waiting to be pushed, including
+	// after a push that failed and will be retried.
+	AccountingSyncStatusPending AccountingSyncStatus = "pending"
+	AccountingSyncStatusSynced  AccountingSyncStatus = "synced"
+	// AccountingSyncStatusFailed is a record that could not be pushed after
+	// MaxAccountingSyncAttempts tries. It can be re-synced.
+	AccountingSyncStatusFailed AccountingSyncStatus = "failed"
+)
+
+// IsValid checks if the sync status is valid.
+func (s AccountingSyncStatus) IsValid() bool {
+	switch s {
+	case AccountingSyncStatusPending, AccountingSyncStatusSynced, AccountingSyncStatusFailed:
+		return true
+	}
+	return false
+}
+
+// AccountingSync tracks the sync of a deal invoice or payment to the
+// tenant's accounting system.
+type AccountingSync struct {
+	ID         uuid.UUID            `json:"id"`
+	TenantID   uuid.UUID            `json:"tenant_id"`
+	DealID     uuid.UUID            `json:"deal_id"`
+	RecordType AccountingRecordType `json:"record_type"`
+	RecordID   uuid.UUID            `json:"record_id"`
+	// Reference is the invoice number or payment reference shown to users.
+	Reference string `json:"reference"`
+	Amount    Money  `json:"amount"`
+
+	Status AccountingSyncStatus `json:"status"`
+	// ExternalID is the record's ID in the accounting system.
+	ExternalID    string     `json:"external_id,omitempty"`
+	Attempts      int        `json:"attempts"`
+	NextAttemptAt *time.Time `json:"next_attempt_at,omitempty"`
+	LastError     string     `json:"last_error,omitempty"`
+	SyncedAt      *time.Time `json:"synced_at,omitempty"`
+	CreatedAt     time.Time  `json:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at"`
+	Version       int        `json:"version"`
+
+	// Domain events
+	events []DomainEvent `json:"-"`
+}
+
+// NewAccountingSync creates a pending sync of a deal invoice or payment.
+func NewAccountingSync(tenantID, dealID uuid.UUID, recordType AccountingRecordType, recordID uuid.UUID, reference string, amount Money) (*AccountingSync, error) {
+	if !recordType.IsValid() {
+		return nil, ErrInvalidAccountingRecordType
+	}
+
+	now := time.Now().UTC()
+	return &AccountingSync{
+		ID:            uuid.New(),
+		TenantID:      tenantID,
+		DealID:        dealID,
+		RecordType:    recordType,
+		RecordID:      recordID,
+		Reference:     reference,
+		Amount:        amount,
+		Status:        AccountingSyncStatusPending,
+		NextAttemptAt: &now,
+		CreatedAt:     now,
+		UpdatedAt:     now,
+		Version:       1,
+		events:        make([]DomainEvent, 0),
+	}, nil
+}
+
+// MarkSynced records that the accounting system holds the record.
+func (s *AccountingSync) MarkSynced(externalID string, at time.Time) error {
+	if s.Status != AccountingSyncStatusPending {
+		return ErrInvalidAccountingSyncTransition
+	}
+
+	at = at.UTC()
+	s.Status = AccountingSyncStatusSynced
+	if externalID != "" {
+		s.ExternalID = externalID
+	}
+	s.NextAttemptAt = nil
+	s.LastError = ""
+	s.SyncedAt = &at
+	s.UpdatedAt = at
+	return nil
+}
+
+// MarkFailed records a push that failed and schedules a retry, failing the
+// sync after MaxAccountingSyncAttempts tries.
+func (s *AccountingSync) MarkFailed(reason string, at time.Time) error {
+	if s.Status != AccountingSyncStatusPending {
+		return ErrInvalidAccountingSyncTransition
+	}
+
+	at = at.UTC()
+	s.Attempts++
+	s.LastError = reason
+	s.UpdatedAt = at
+
+	if s.Attempts >= MaxAccountingSyncAttempts {
+		s.Status = AccountingSyncStatusFailed
+		s.NextAttemptAt = nil
+		s.AddEvent(NewAccountingSyncFailedEvent(s))
+		return nil
+	}
+
+	next := at.Add(accountingSyncBackoff[s.Attempts-1])
+	s.NextAttemptAt = &next
+	return nil
+}
+
+// Requeue queues the record to be pushed again, such as after it was
+// corrected or deleted in the accounting system.
+func (s *AccountingSync) Requeue(at time.Time) {
+	at = at.UTC()
+	s.Status = AccountingSyncStatusPending
+	s.Attempts = 0
+	s.NextAttemptAt = &at
+	s.LastError = ""
+	s.UpdatedAt = at
+}
+
+// AddEvent adds a domain event.
+func (s *AccountingSync) AddEvent(event DomainEvent) {
+	s.events = append(s.events, event)
+}
+
+// GetEvents returns all domain events.
+func (s *AccountingSync) GetEvents() []DomainEvent {
+	return s.events
+}
+
+// ClearEvents clears all domain events.
+func (s *AccountingSync) ClearEvents() {
+	s.events = make([]DomainEvent, 0)
+}
+
+// UnsyncedAccountingRecord is an issued invoice or a payment that has no
+// sync record yet.
+type UnsyncedAccountingRecord struct {
+	DealID     uuid.UUID            `json:"deal_id"`
+	DealCode   string               `json:"deal_code"`
+	RecordType AccountingRecordType `json:"record_type"`
+	RecordID   uuid.UUID            `json:"record_id"`
+	Reference  string               `json:"reference"`
+	Amount     Money                `json:"amount"`
+	Date       time.Time            `json:"date"`
+}
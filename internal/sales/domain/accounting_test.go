@@ -0,0 +1,124 @@
+package domain
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func createTestAccountingSync(t *testing.T) *AccountingSync {
+	t.Helper()
+
+	sync, err := NewAccountingSync(uuid.New(), uuid.New(), AccountingRecordInvoice, uuid.New(), "INV-001", Money{Amount: 108000, Currency: "MYR"})
+	if err != nil {
+		t.Fatalf("NewAccountingSync() error = %v", err)
+	}
+	return sync
+}
+
+func TestNewAccountingConnection(t *testing.T) {
+	connection, err := NewAccountingConnection(uuid.New(), AccountingProviderXero, " https://connector.example.com/xero ")
+	if err != nil {
+		t.Fatalf("NewAccountingConnection() error = %v", err)
+	}
+	if !connection.Enabled || connection.URL != "https://connector.example.com/xero" {
+		t.Errorf("Enabled, URL = %v, %q, want an enabled connection to the trimmed URL", connection.Enabled, connection.URL)
+	}
+	if len(connection.Secret) != 64 {
+		t.Errorf("Secret length = %d, want 64", len(connection.Secret))
+	}
+
+	secret := connection.Secret
+	if err := connection.RotateSecret(); err != nil || connection.Secret == secret {
+		t.Errorf("RotateSecret() error = %v, secret changed = %v", err, connection.Secret != secret)
+	}
+
+	if _, err := NewAccountingConnection(uuid.New(), "quickbooks", "https://connector.example.com"); !errors.Is(err, ErrInvalidAccountingProvider) {
+		t.Errorf("NewAccountingConnection() with unknown provider error = %v, want %v", err, ErrInvalidAccountingProvider)
+	}
+	if _, err := NewAccountingConnection(uuid.New(), AccountingProviderAutoCount, "ftp://connector.example.com"); !errors.Is(err, ErrInvalidAccountingConnectorURL) {
+		t.Errorf("NewAccountingConnection() with ftp URL error = %v, want %v", err, ErrInvalidAccountingConnectorURL)
+	}
+}
+
+func TestAccountingMapping_TaxCode(t *testing.T) {
+	connection := &AccountingConnection{}
+	connection.SetMapping(AccountingMapping{
+		SalesAccount:   " 500-000 ",
+		PaymentAccount: "310-000",
+		TaxCodes:       map[string]string{" SST8 ": " SV-8 ", "": "X"},
+		DefaultTaxCode: "NR",
+	})
+
+	if connection.Mapping.SalesAccount != "500-000" {
+		t.Errorf("SalesAccount = %q, want it trimmed", connection.Mapping.SalesAccount)
+	}
+	if len(connection.Mapping.TaxCodes) != 1 {
+		t.Errorf("TaxCodes = %v, want blank codes dropped", connection.Mapping.TaxCodes)
+	}
+	if got := connection.Mapping.TaxCode("SST8"); got != "SV-8" {
+		t.Errorf("TaxCode(SST8) = %q, want SV-8", got)
+	}
+	if got := connection.Mapping.TaxCode("SST10"); got != "NR" {
+		t.Errorf("TaxCode(SST10) = %q, want the default NR", got)
+	}
+}
+
+func TestAccountingSync_MarkSynced(t *testing.T) {
+	sync := createTestAccountingSync(t)
+	at := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+
+	if err := sync.MarkSynced("XERO-INV-1", at); err != nil {
+		t.Fatalf("MarkSynced() error = %v", err)
+	}
+	if sync.Status != AccountingSyncStatusSynced || sync.ExternalID != "XERO-INV-1" || sync.NextAttemptAt != nil {
+		t.Errorf("sync = %+v, want synced with its external ID", sync)
+	}
+	if err := sync.MarkSynced("XERO-INV-2", at); !errors.Is(err, ErrInvalidAccountingSyncTransition) {
+		t.Errorf("MarkSynced() again error = %v, want %v", err, ErrInvalidAccountingSyncTransition)
+	}
+
+	// Re-syncing pushes the record again
+	sync.Requeue(at.Add(time.Hour))
+	if sync.Status != AccountingSyncStatusPending || sync.NextAttemptAt == nil || sync.ExternalID != "XERO-INV-1" {
+		t.Errorf("sync = %+v, want pending and keeping its external ID", sync)
+	}
+}
+
+func TestAccountingSync_MarkFailed(t *testing.T) {
+	sync := createTestAccountingSync(t)
+	at := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+
+	if err := sync.MarkFailed("connector unreachable", at); err != nil {
+		t.Fatalf("MarkFailed() error = %v", err)
+	}
+	if sync.Status != AccountingSyncStatusPending || sync.Attempts != 1 || !sync.NextAttemptAt.Equal(at.Add(time.Minute)) {
+		t.Errorf("Status, Attempts, NextAttemptAt = %s, %d, %v, want a retry in a minute", sync.Status, sync.Attempts, sync.NextAttemptAt)
+	}
+
+	for i := 1; i < MaxAccountingSyncAttempts; i++ {
+		if err := sync.MarkFailed("connector unreachable", at); err != nil {
+			t.Fatalf("MarkFailed() attempt %d error = %v", i+1, err)
+		}
+	}
+	if sync.Status != AccountingSyncStatusFailed || sync.NextAttemptAt != nil {
+		t.Errorf("Status, NextAttemptAt = %s, %v, want failed with no retry", sync.Status, sync.NextAttemptAt)
+	}
+	events := sync.GetEvents()
+	if len(events) != 1 || events[0].EventType() != "accounting_sync.failed" {
+		t.Errorf("events = %v, want accounting_sync.failed", events)
+	}
+
+	sync.Requeue(at)
+	if sync.Status != AccountingSyncStatusPending || sync.Attempts != 0 || sync.LastError != "" {
+		t.Errorf("sync = %+v, want pending with attempts reset", sync)
+	}
+}
+
+func TestNewAccountingSync_InvalidRecordType(t *testing.T) {
+	if _, err := NewAccountingSync(uuid.New(), uuid.New(), "quote", uuid.New(), "Q-1", Money{}); !errors.Is(err, ErrInvalidAccountingRecordType) {
+		t.Errorf("NewAccountingSync() error = %v, want %v", err, ErrInvalidAccountingRecordType)
+	}
+}
@@ -0,0 +1,144 @@
+// Package domain contains the domain layer for the Sales Pipeline service.
+package domain
+
+import (
+	"errors"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Attachment errors
+var (
+	ErrAttachmentNotFound   = errors.New("attachment not found")
+	ErrInvalidImageSize     = errors.New("invalid image size")
+	ErrImageSizeUnsupported = errors.New("size variants are only available for images")
+)
+
+// AttachmentEntityOpportunity is the storage entity type of opportunity attachments.
+const AttachmentEntityOpportunity = "opportunity"
+
+// ImageSize names a stored rendition of an attached image.
+type ImageSize string
+
+const (
+	ImageSizeOriginal ImageSize = "original"
+	ImageSizeThumb    ImageSize = "thumb"
+	ImageSizeMedium   ImageSize = "medium"
+)
+
+// ImageVariantSizes lists the downscaled variants generated for every attached image.
+var ImageVariantSizes = []ImageSize{ImageSizeThumb, ImageSizeMedium}
+
+// IsValid checks if the image size is valid.
+func (s ImageSize) IsValid() bool {
+	switch s {
+	case ImageSizeOriginal, ImageSizeThumb, ImageSizeMedium:
+		return true
+	}
+	return false
+}
+
+// MaxDimension returns the longest side, in pixels, of a variant. The original
+// has no limit and returns 0.
+func (s ImageSize) MaxDimension() int {
+	switch s {
+	case ImageSizeThumb:
+		return 200
+	case ImageSizeMedium:
+		return 800
+	}
+	return 0
+}
+
+// IsThumbnailable reports whether image variants can be generated for a content type.
+func IsThumbnailable(contentType string) bool {
+	contentType = strings.ToLower(strings.TrimSpace(strings.Split(contentType, ";")[0]))
+	switch contentType {
+	case "image/jpeg", "image/png", "image/gif":
+		return true
+	}
+	return false
+}
+
+// ImageVariantFileID returns the file ID of an image variant. Variants are
+// stored as JPEG in a variants directory beside the original, so listing an
+// entity's files does not return them.
+func ImageVariantFileID(fileID string, size ImageSize) string {
+	dir, name := path.Split(fileID)
+	return dir + "variants/" + name + "." + string(size) + ".jpg"
+}
+
+// ============================================================================
+// Virus Scanning
+// ============================================================================
+
+// ScanStatus is the virus scan status of an attached file.
+type ScanStatus string
+
+const (
+	ScanStatusPending  ScanStatus = "pending"
+	ScanStatusClean    ScanStatus = "clean"
+	ScanStatusInfected ScanStatus = "infected"
+)
+
+// AttachmentScan is the virus scan metadata of an attached file. Files are
+// quarantined from upload until scanned clean; an infected file is deleted
+// and its scan kept as the record of the rejection. In detect-only mode scans
+// are recorded without quarantining or deleting anything.
+type AttachmentScan struct {
+	FileID     string     `json:"file_id"`
+	Filename   string     `json:"filename"`
+	UploadedBy uuid.UUID  `json:"uploaded_by"`
+	Status     ScanStatus `json:"status"`
+	Signature  string     `json:"signature,omitempty"` // The malware found
+	Scanner    string     `json:"scanner,omitempty"`
+	DetectOnly bool       `json:"detect_only"`
+	UploadedAt time.Time  `json:"uploaded_at"`
+	ScannedAt  *time.Time `json:"scanned_at,omitempty"`
+}
+
+// NewAttachmentScan creates the pending scan of an uploaded file.
+func NewAttachmentScan(fileID, filename string, uploadedBy uuid.UUID, detectOnly bool) *AttachmentScan {
+	return &AttachmentScan{
+		FileID:     fileID,
+		Filename:   filename,
+		UploadedBy: uploadedBy,
+		Status:     ScanStatusPending,
+		DetectOnly: detectOnly,
+		UploadedAt: time.Now().UTC(),
+	}
+}
+
+// Record records the outcome of a scan. An empty signature means the file
+// is clean.
+func (s *AttachmentScan) Record(scanner, signature string) {
+	now := time.Now().UTC()
+	s.Scanner = scanner
+	s.Signature = signature
+	s.ScannedAt = &now
+	s.Status = ScanStatusClean
+	if signature != "" {
+		s.Status = ScanStatusInfected
+	}
+}
+
+// IsQuarantined reports whether the file may not be downloaded yet.
+func (s *AttachmentScan) IsQuarantined() bool {
+	return !s.DetectOnly && s.Status != ScanStatusClean
+}
+
+// IsRejected reports whether the file was found infected and deleted.
+func (s *AttachmentScan) IsRejected() bool {
+	return !s.DetectOnly && s.Status == ScanStatusInfected
+}
+
+// AttachmentScanFileID returns the file ID of an attached file's scan. Scans
+// are stored as JSON in a scans directory beside the file, like image
+// variants, so listing an entity's files does not return them.
+func AttachmentScanFileID(fileID string) string {
+	dir, name := path.Split(fileID)
+	return dir + "scans/" + name + ".json"
+}
@@ -0,0 +1,78 @@
+// Package domain contains the domain layer for the Sales Pipeline service.
+package domain
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestImageVariantFileID(t *testing.T) {
+	got := ImageVariantFileID("opportunity/42/abc-batik.png", ImageSizeThumb)
+	want := "opportunity/42/variants/abc-batik.png.thumb.jpg"
+	if got != want {
+		t.Errorf("ImageVariantFileID() = %q, want %q", got, want)
+	}
+}
+
+func TestIsThumbnailable(t *testing.T) {
+	tests := []struct {
+		contentType string
+		want        bool
+	}{
+		{"image/jpeg", true},
+		{"IMAGE/PNG", true},
+		{"image/gif; charset=binary", true},
+		{"image/svg+xml", false},
+		{"application/pdf", false},
+	}
+
+	for _, tt := range tests {
+		if got := IsThumbnailable(tt.contentType); got != tt.want {
+			t.Errorf("IsThumbnailable(%q) = %v, want %v", tt.contentType, got, tt.want)
+		}
+	}
+}
+
+func TestAttachmentScanFileID(t *testing.T) {
+	got := AttachmentScanFileID("opportunity/42/abc-batik.png")
+	want := "opportunity/42/scans/abc-batik.png.json"
+	if got != want {
+		t.Errorf("AttachmentScanFileID() = %q, want %q", got, want)
+	}
+}
+
+func TestAttachmentScan_Quarantine(t *testing.T) {
+	tests := []struct {
+		name        string
+		detectOnly  bool
+		signature   string
+		scanned     bool
+		quarantined bool
+		rejected    bool
+	}{
+		{name: "pending", quarantined: true},
+		{name: "clean", scanned: true},
+		{name: "infected", scanned: true, signature: "Win.Test.EICAR_HDB-1", quarantined: true, rejected: true},
+		{name: "pending detect-only", detectOnly: true},
+		{name: "infected detect-only", detectOnly: true, scanned: true, signature: "Win.Test.EICAR_HDB-1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			scan := NewAttachmentScan("opportunity/42/abc-batik.png", "batik.png", uuid.New(), tt.detectOnly)
+			if tt.scanned {
+				scan.Record("clamav", tt.signature)
+				if scan.ScannedAt == nil {
+					t.Error("ScannedAt was not set")
+				}
+			}
+			if got := scan.IsQuarantined(); got != tt.quarantined {
+				t.Errorf("IsQuarantined() = %v, want %v", got, tt.quarantined)
+			}
+			if got := scan.IsRejected(); got != tt.rejected {
+				t.Errorf("IsRejected() = %v, want %v", got, tt.rejected)
+			}
+		})
+	}
+}
@@ -0,0 +1,76 @@
+package domain
+
+import (
+	"net/url"
+	"strings"
+)
+
+// Attribution records where a lead came from: the UTM parameters of the link
+// it followed, the page that referred it and the page it landed on. It is
+// captured with the lead and carried over to its opportunity and deal, so
+// revenue can be reported by campaign and channel.
+type Attribution struct {
+	UTMSource   string `json:"utm_source,omitempty" bson:"utm_source,omitempty"`
+	UTMMedium   string `json:"utm_medium,omitempty" bson:"utm_medium,omitempty"`
+	UTMCampaign string `json:"utm_campaign,omitempty" bson:"utm_campaign,omitempty"`
+	UTMTerm     string `json:"utm_term,omitempty" bson:"utm_term,omitempty"`
+	UTMContent  string `json:"utm_content,omitempty" bson:"utm_content,omitempty"`
+	Referrer    string `json:"referrer,omitempty" bson:"referrer,omitempty"`
+	LandingPage string `json:"landing_page,omitempty" bson:"landing_page,omitempty"`
+}
+
+// NewAttribution returns the attribution of a lead, with UTM parameters not
+// given read from the query string of the landing page. Source, medium and
+// campaign are lower cased so reports do not split "Facebook" and "facebook".
+// It returns nil when there is nothing to record.
+func NewAttribution(a Attribution) *Attribution {
+	a.Referrer = strings.TrimSpace(a.Referrer)
+	a.LandingPage = strings.TrimSpace(a.LandingPage)
+	if page, err := url.Parse(a.LandingPage); err == nil && a.LandingPage != "" {
+		query := page.Query()
+		for field, param := range map[*string]string{
+			&a.UTMSource:   "utm_source",
+			&a.UTMMedium:   "utm_medium",
+			&a.UTMCampaign: "utm_campaign",
+			&a.UTMTerm:     "utm_term",
+			&a.UTMContent:  "utm_content",
+		} {
+			if strings.TrimSpace(*field) == "" {
+				*field = query.Get(param)
+			}
+		}
+	}
+
+	a.UTMSource = strings.ToLower(strings.TrimSpace(a.UTMSource))
+	a.UTMMedium = strings.ToLower(strings.TrimSpace(a.UTMMedium))
+	a.UTMCampaign = strings.ToLower(strings.TrimSpace(a.UTMCampaign))
+	a.UTMTerm = strings.TrimSpace(a.UTMTerm)
+	a.UTMContent = strings.TrimSpace(a.UTMContent)
+	if a.IsEmpty() {
+		return nil
+	}
+	return &a
+}
+
+// IsEmpty reports whether nothing is recorded.
+func (a Attribution) IsEmpty() bool {
+	return a == Attribution{}
+}
+
+// Channel returns the marketing channel a record is reported under: the UTM
+// medium, or else the lead source.
+func (a *Attribution) Channel(source string) string {
+	if a != nil && a.UTMMedium != "" {
+		return a.UTMMedium
+	}
+	return source
+}
+
+// Copy returns a copy of the attribution, or nil.
+func (a *Attribution) Copy() *Attribution {
+	if a == nil {
+		return nil
+	}
+	c := *a
+	return &c
+}
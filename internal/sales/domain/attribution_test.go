@@ -0,0 +1,69 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestNewAttribution(t *testing.T) {
+	attribution := NewAttribution(Attribution{
+		UTMSource:   " Facebook ",
+		Referrer:    "https://www.facebook.com/",
+		LandingPage: " https://kilangdesamurni.com/raya?utm_source=ignored&utm_medium=CPC&utm_campaign=Raya-2024&utm_content=Banner ",
+	})
+	if attribution == nil {
+		t.Fatal("NewAttribution() = nil")
+	}
+
+	want := Attribution{
+		UTMSource:   "facebook",
+		UTMMedium:   "cpc",
+		UTMCampaign: "raya-2024",
+		UTMContent:  "Banner",
+		Referrer:    "https://www.facebook.com/",
+		LandingPage: "https://kilangdesamurni.com/raya?utm_source=ignored&utm_medium=CPC&utm_campaign=Raya-2024&utm_content=Banner",
+	}
+	if *attribution != want {
+		t.Errorf("NewAttribution() = %+v, want %+v", *attribution, want)
+	}
+
+	if got := NewAttribution(Attribution{UTMSource: "  "}); got != nil {
+		t.Errorf("NewAttribution() = %+v, want nil for blank values", got)
+	}
+}
+
+func TestAttribution_Channel(t *testing.T) {
+	var none *Attribution
+	if got := none.Channel("website"); got != "website" {
+		t.Errorf("Channel() = %q, want the lead source", got)
+	}
+	if got := (&Attribution{UTMSource: "google"}).Channel("website"); got != "website" {
+		t.Errorf("Channel() = %q, want the lead source without a medium", got)
+	}
+	if got := (&Attribution{UTMMedium: "email"}).Channel("website"); got != "email" {
+		t.Errorf("Channel() = %q, want the UTM medium", got)
+	}
+}
+
+func TestNewOpportunityFromLead_CarriesAttribution(t *testing.T) {
+	lead := createTestLead(t)
+	lead.Attribution = &Attribution{UTMSource: "facebook", UTMCampaign: "raya-2024"}
+	if err := lead.Qualify(); err != nil {
+		t.Fatalf("Qualify() error = %v", err)
+	}
+	pipeline := createTestOpportunityPipeline(t)
+	pipeline.TenantID = lead.TenantID
+
+	opp, err := NewOpportunityFromLead(lead, pipeline, uuid.New(), "Batik Murni", uuid.New())
+	if err != nil {
+		t.Fatalf("NewOpportunityFromLead() error = %v", err)
+	}
+
+	if opp.Attribution == nil || *opp.Attribution != *lead.Attribution {
+		t.Fatalf("Attribution = %+v, want %+v", opp.Attribution, lead.Attribution)
+	}
+	if opp.Attribution == lead.Attribution {
+		t.Error("Attribution shared with the lead, want a copy")
+	}
+}
@@ -0,0 +1,210 @@
+// Package domain contains the domain layer for the Sales Pipeline service.
+package domain
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Board errors
+var (
+	ErrInvalidBoardCursor   = errors.New("invalid board cursor")
+	ErrInvalidBoardPosition = errors.New("invalid board position")
+)
+
+// ============================================================================
+// Board Positions
+// ============================================================================
+
+// boardPositionDigits are the digits of board position keys in ascending
+// byte order. Keys compare as plain byte strings, so stored keys must use a
+// byte-order collation.
+const boardPositionDigits = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz"
+
+// DefaultBoardPosition returns the position of an opportunity that entered
+// its stage at t. Later times give smaller keys, so cards that were never
+// moved by hand list newest first.
+func DefaultBoardPosition(t time.Time) string {
+	return fmt.Sprintf("%019d", math.MaxInt64-t.UnixNano()) + "V"
+}
+
+// IsValidBoardPosition reports whether s is a well-formed position key. Keys
+// never end in the lowest digit, so a key can always be placed before them.
+func IsValidBoardPosition(s string) bool {
+	if s == "" || s[len(s)-1] == boardPositionDigits[0] {
+		return false
+	}
+	for i := 0; i < len(s); i++ {
+		if strings.IndexByte(boardPositionDigits, s[i]) < 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// BoardPositionBetween returns a position key that sorts strictly between
+// before and after. An empty before or after leaves that side unbounded, so
+// moving a card never renumbers its neighbours.
+func BoardPositionBetween(before, after string) (string, error) {
+	if before != "" && !IsValidBoardPosition(before) {
+		return "", ErrInvalidBoardPosition
+	}
+	if after != "" && (!IsValidBoardPosition(after) || before >= after) {
+		return "", ErrInvalidBoardPosition
+	}
+	return boardPositionMidpoint(before, after, after != ""), nil
+}
+
+// boardPositionMidpoint returns a key between a and b, where b only bounds
+// the result when bounded is set.
+func boardPositionMidpoint(a, b string, bounded bool) string {
+	if bounded {
+		// Keep the shared prefix, reading missing digits of a as the lowest digit
+		n := 0
+		for n < len(b) && boardPositionDigitAt(a, n) == b[n] {
+			n++
+		}
+		if n > 0 {
+			rest := ""
+			if n < len(a) {
+				rest = a[n:]
+			}
+			return b[:n] + boardPositionMidpoint(rest, b[n:], true)
+		}
+	}
+
+	digitA := 0
+	if a != "" {
+		digitA = strings.IndexByte(boardPositionDigits, a[0])
+	}
+	digitB := len(boardPositionDigits)
+	if bounded {
+		digitB = strings.IndexByte(boardPositionDigits, b[0])
+	}
+
+	if digitB-digitA > 1 {
+		return string(boardPositionDigits[(digitA+digitB+1)/2])
+	}
+
+	// The first digits are adjacent
+	if bounded && len(b) > 1 {
+		return b[:1]
+	}
+	rest := ""
+	if a != "" {
+		rest = a[1:]
+	}
+	return string(boardPositionDigits[digitA]) + boardPositionMidpoint(rest, "", false)
+}
+
+func boardPositionDigitAt(s string, i int) byte {
+	if i < len(s) {
+		return s[i]
+	}
+	return boardPositionDigits[0]
+}
+
+// OpportunityBoardCard is the denormalized read model of an opportunity as
+// shown on a pipeline's Kanban board. Cards are projected from opportunity
+// events and hold only what the board renders.
+type OpportunityBoardCard struct {
+	OpportunityID     uuid.UUID           `json:"opportunity_id" db:"opportunity_id"`
+	TenantID          uuid.UUID           `json:"tenant_id" db:"tenant_id"`
+	PipelineID        uuid.UUID           `json:"pipeline_id" db:"pipeline_id"`
+	StageID           uuid.UUID           `json:"stage_id" db:"stage_id"`
+	Code              string              `json:"code" db:"code"`
+	Name              string              `json:"name" db:"name"`
+	Status            OpportunityStatus   `json:"status" db:"status"`
+	Priority          OpportunityPriority `json:"priority" db:"priority"`
+	CustomerName      string              `json:"customer_name" db:"customer_name"`
+	OwnerID           uuid.UUID           `json:"owner_id" db:"owner_id"`
+	OwnerName         string              `json:"owner_name" db:"owner_name"`
+	Amount            int64               `json:"amount" db:"amount"`
+	WeightedAmount    int64               `json:"weighted_amount" db:"weighted_amount"`
+	Currency          string              `json:"currency" db:"currency"`
+	Probability       int                 `json:"probability" db:"probability"`
+	ExpectedCloseDate *time.Time          `json:"expected_close_date,omitempty" db:"expected_close_date"`
+	StageEnteredAt    time.Time           `json:"stage_entered_at" db:"stage_entered_at"`
+	BoardPosition     string              `json:"board_position" db:"board_position"`
+	Version           int                 `json:"version" db:"version"`
+	UpdatedAt         time.Time           `json:"updated_at" db:"updated_at"`
+}
+
+// NewOpportunityBoardCard projects an opportunity onto a board card.
+func NewOpportunityBoardCard(opp *Opportunity) *OpportunityBoardCard {
+	return &OpportunityBoardCard{
+		OpportunityID:     opp.ID,
+		TenantID:          opp.TenantID,
+		PipelineID:        opp.PipelineID,
+		StageID:           opp.StageID,
+		Code:              opp.Code,
+		Name:              opp.Name,
+		Status:            opp.Status,
+		Priority:          opp.Priority,
+		CustomerName:      opp.CustomerName,
+		OwnerID:           opp.OwnerID,
+		OwnerName:         opp.OwnerName,
+		Amount:            opp.Amount.Amount,
+		WeightedAmount:    opp.WeightedAmount.Amount,
+		Currency:          opp.Amount.Currency,
+		Probability:       opp.Probability,
+		ExpectedCloseDate: opp.ExpectedCloseDate,
+		StageEnteredAt:    opp.StageEnteredAt,
+		BoardPosition:     opp.BoardPosition,
+		Version:           opp.Version,
+		UpdatedAt:         time.Now().UTC(),
+	}
+}
+
+// BoardColumnTotal holds the card count and value of one board column in one
+// currency. A column holding several currencies has one total per currency.
+type BoardColumnTotal struct {
+	StageID        uuid.UUID `json:"stage_id" db:"stage_id"`
+	Currency       string    `json:"currency" db:"currency"`
+	Count          int64     `json:"count" db:"count"`
+	Amount         int64     `json:"amount" db:"amount"`
+	WeightedAmount int64     `json:"weighted_amount" db:"weighted_amount"`
+}
+
+// BoardCursor marks the last card returned from a board column. Columns are
+// ordered by board position, with ties broken by opportunity ID.
+type BoardCursor struct {
+	BoardPosition string
+	OpportunityID uuid.UUID
+}
+
+// NewBoardCursor creates the cursor that continues after a card.
+func NewBoardCursor(card *OpportunityBoardCard) BoardCursor {
+	return BoardCursor{BoardPosition: card.BoardPosition, OpportunityID: card.OpportunityID}
+}
+
+// Encode returns the opaque string form of the cursor.
+func (c BoardCursor) Encode() string {
+	raw := c.BoardPosition + "|" + c.OpportunityID.String()
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+// DecodeBoardCursor parses a cursor produced by Encode.
+func DecodeBoardCursor(s string) (BoardCursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return BoardCursor{}, ErrInvalidBoardCursor
+	}
+
+	position, id, ok := strings.Cut(string(raw), "|")
+	if !ok || !IsValidBoardPosition(position) {
+		return BoardCursor{}, ErrInvalidBoardCursor
+	}
+
+	cursor := BoardCursor{BoardPosition: position}
+	if cursor.OpportunityID, err = uuid.Parse(id); err != nil {
+		return BoardCursor{}, ErrInvalidBoardCursor
+	}
+	return cursor, nil
+}
@@ -0,0 +1,87 @@
+package domain
+
+import (
+	"testing"
+	"time"
+)
+
+func TestBoardPositionBetween(t *testing.T) {
+	tests := []struct {
+		name   string
+		before string
+		after  string
+	}{
+		{"empty column", "", ""},
+		{"first", "", "V"},
+		{"last", "V", ""},
+		{"wide gap", "1", "z"},
+		{"adjacent digits", "a", "b"},
+		{"shared prefix", "aV", "aW"},
+		{"prefix of after", "a", "a1"},
+		{"long keys", "0000000000000000001V", "0000000000000000002V"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := BoardPositionBetween(tt.before, tt.after)
+			if err != nil {
+				t.Fatalf("BoardPositionBetween(%q, %q) error = %v", tt.before, tt.after, err)
+			}
+			if !IsValidBoardPosition(got) {
+				t.Errorf("BoardPositionBetween(%q, %q) = %q, not a valid position", tt.before, tt.after, got)
+			}
+			if got <= tt.before || (tt.after != "" && got >= tt.after) {
+				t.Errorf("BoardPositionBetween(%q, %q) = %q, not between", tt.before, tt.after, got)
+			}
+		})
+	}
+}
+
+func TestBoardPositionBetween_Invalid(t *testing.T) {
+	tests := []struct {
+		name   string
+		before string
+		after  string
+	}{
+		{"reversed", "b", "a"},
+		{"equal", "a", "a"},
+		{"trailing lowest digit", "a0", ""},
+		{"outside alphabet", "a-b", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := BoardPositionBetween(tt.before, tt.after); err != ErrInvalidBoardPosition {
+				t.Errorf("BoardPositionBetween(%q, %q) error = %v, want ErrInvalidBoardPosition", tt.before, tt.after, err)
+			}
+		})
+	}
+}
+
+func TestBoardPositionBetween_RepeatedInserts(t *testing.T) {
+	// Inserting at the same spot keeps producing ordered keys
+	before, after := "a", "b"
+	for i := 0; i < 50; i++ {
+		got, err := BoardPositionBetween(before, after)
+		if err != nil {
+			t.Fatalf("insert %d: error = %v", i, err)
+		}
+		if got <= before || got >= after {
+			t.Fatalf("insert %d: %q not between %q and %q", i, got, before, after)
+		}
+		after = got
+	}
+}
+
+func TestDefaultBoardPosition(t *testing.T) {
+	now := time.Now()
+	older := DefaultBoardPosition(now.Add(-time.Minute))
+	newer := DefaultBoardPosition(now)
+
+	if !IsValidBoardPosition(newer) {
+		t.Fatalf("DefaultBoardPosition() = %q, not a valid position", newer)
+	}
+	if newer >= older {
+		t.Errorf("DefaultBoardPosition() newer %q should sort before older %q", newer, older)
+	}
+}
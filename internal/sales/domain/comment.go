@@ -0,0 +1,286 @@
+package domain
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Comment errors
+var (
+	ErrCommentNotFound          = errors.New("comment not found")
+	ErrCommentModified          = errors.New("comment was changed by another request")
+	ErrInvalidCommentEntityType = errors.New("invalid comment entity type")
+	ErrCommentBodyRequired      = errors.New("comment body is required")
+	ErrCommentBodyTooLong       = errors.New("comment body is too long")
+	ErrTooManyCommentMentions   = errors.New("comment mentions too many users")
+	ErrCommentDeleted           = errors.New("comment has been deleted")
+	ErrNotCommentAuthor         = errors.New("only the author can change a comment")
+	ErrInvalidCommentReaction   = errors.New("invalid comment reaction")
+)
+
+// Limits of a comment.
+const (
+	MaxCommentBodyLength = 10000
+	MaxCommentMentions   = 20
+)
+
+// CommentEntityType is the kind of record comments are written on.
+type CommentEntityType string
+
+const (
+	CommentEntityLead        CommentEntityType = "lead"
+	CommentEntityOpportunity CommentEntityType = "opportunity"
+	CommentEntityCustomer    CommentEntityType = "customer"
+)
+
+// IsValid checks if the entity type is valid.
+func (t CommentEntityType) IsValid() bool {
+	switch t {
+	case CommentEntityLead, CommentEntityOpportunity, CommentEntityCustomer:
+		return true
+	default:
+		return false
+	}
+}
+
+// CommentReaction is a reaction users add to a comment.
+type CommentReaction string
+
+const (
+	CommentReactionThumbsUp   CommentReaction = "thumbs_up"
+	CommentReactionThumbsDown CommentReaction = "thumbs_down"
+	CommentReactionHeart      CommentReaction = "heart"
+	CommentReactionLaugh      CommentReaction = "laugh"
+	CommentReactionCelebrate  CommentReaction = "celebrate"
+	CommentReactionEyes       CommentReaction = "eyes"
+)
+
+// IsValid checks if the reaction is valid.
+func (r CommentReaction) IsValid() bool {
+	switch r {
+	case CommentReactionThumbsUp, CommentReactionThumbsDown, CommentReactionHeart,
+		CommentReactionLaugh, CommentReactionCelebrate, CommentReactionEyes:
+		return true
+	default:
+		return false
+	}
+}
+
+// CommentRevisionAction is what a revision of a comment recorded.
+type CommentRevisionAction string
+
+const (
+	CommentRevisionEdited  CommentRevisionAction = "edited"
+	CommentRevisionDeleted CommentRevisionAction = "deleted"
+)
+
+// CommentRevision is the body a comment had before it was edited or
+// deleted, kept as an audit trail of the change.
+type CommentRevision struct {
+	Action    CommentRevisionAction `json:"action"`
+	Body      string                `json:"body"`
+	ChangedBy uuid.UUID             `json:"changed_by"`
+	ChangedAt time.Time             `json:"changed_at"`
+}
+
+// mentionPattern matches a mention as the comment editor writes it when a
+// user is picked: @[Display Name](user ID).
+var mentionPattern = regexp.MustCompile(`@\[([^\[\]\n]{1,100})\]\(([0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12})\)`)
+
+// ParseCommentMentions returns the users a comment body mentions, each once,
+// in the order they are first mentioned.
+func ParseCommentMentions(body string) []uuid.UUID {
+	mentions := []uuid.UUID{}
+	seen := make(map[uuid.UUID]bool)
+	for _, match := range mentionPattern.FindAllStringSubmatch(body, -1) {
+		id, err := uuid.Parse(match[2])
+		if err != nil || seen[id] {
+			continue
+		}
+		seen[id] = true
+		mentions = append(mentions, id)
+	}
+	return mentions
+}
+
+// Comment is a comment on a lead, opportunity or customer. Comments form
+// threads one level deep: a reply belongs to the thread of the top-level
+// comment it answers.
+type Comment struct {
+	ID         uuid.UUID         `json:"id"`
+	TenantID   uuid.UUID         `json:"tenant_id"`
+	EntityType CommentEntityType `json:"entity_type"`
+	EntityID   uuid.UUID         `json:"entity_id"`
+	ParentID   *uuid.UUID        `json:"parent_id,omitempty"`
+	AuthorID   uuid.UUID         `json:"author_id"`
+	Body       string            `json:"body"`
+	Mentions   []uuid.UUID       `json:"mentions"`
+
+	// Users who added each reaction, loaded with the comment
+	Reactions map[CommentReaction][]uuid.UUID `json:"reactions"`
+	// Replies to a top-level comment, loaded with the comment
+	ReplyCount int64 `json:"reply_count"`
+
+	Revisions []CommentRevision `json:"revisions"`
+	EditedAt  *time.Time        `json:"edited_at,omitempty"`
+	DeletedAt *time.Time        `json:"deleted_at,omitempty"`
+	DeletedBy *uuid.UUID        `json:"deleted_by,omitempty"`
+	CreatedAt time.Time         `json:"created_at"`
+	UpdatedAt time.Time         `json:"updated_at"`
+	Version   int               `json:"version"`
+}
+
+// NewComment creates a comment by the author on a record. A comment with a
+// parent replies to the parent's thread.
+func NewComment(tenantID uuid.UUID, entityType CommentEntityType, entityID, authorID uuid.UUID, body string, parent *Comment) (*Comment, error) {
+	if !entityType.IsValid() {
+		return nil, ErrInvalidCommentEntityType
+	}
+	body, mentions, err := validateCommentBody(body)
+	if err != nil {
+		return nil, err
+	}
+
+	var parentID *uuid.UUID
+	if parent != nil {
+		if parent.EntityType != entityType || parent.EntityID != entityID {
+			return nil, ErrCommentNotFound
+		}
+		if parent.IsDeleted() {
+			return nil, ErrCommentDeleted
+		}
+		threadID := parent.ThreadID()
+		parentID = &threadID
+	}
+
+	now := time.Now().UTC()
+	return &Comment{
+		ID:         uuid.New(),
+		TenantID:   tenantID,
+		EntityType: entityType,
+		EntityID:   entityID,
+		ParentID:   parentID,
+		AuthorID:   authorID,
+		Body:       body,
+		Mentions:   mentions,
+		Reactions:  map[CommentReaction][]uuid.UUID{},
+		Revisions:  []CommentRevision{},
+		CreatedAt:  now,
+		UpdatedAt:  now,
+		Version:    1,
+	}, nil
+}
+
+// validateCommentBody trims a comment body and returns it with the users it
+// mentions.
+func validateCommentBody(body string) (string, []uuid.UUID, error) {
+	body = strings.TrimSpace(body)
+	if body == "" {
+		return "", nil, ErrCommentBodyRequired
+	}
+	if len([]rune(body)) > MaxCommentBodyLength {
+		return "", nil, ErrCommentBodyTooLong
+	}
+	mentions := ParseCommentMentions(body)
+	if len(mentions) > MaxCommentMentions {
+		return "", nil, ErrTooManyCommentMentions
+	}
+	return body, mentions, nil
+}
+
+// ThreadID returns the ID of the top-level comment of the comment's thread.
+func (c *Comment) ThreadID() uuid.UUID {
+	if c.ParentID != nil {
+		return *c.ParentID
+	}
+	return c.ID
+}
+
+// IsDeleted reports whether the comment has been deleted.
+func (c *Comment) IsDeleted() bool {
+	return c.DeletedAt != nil
+}
+
+// Edit replaces the body of the comment, keeping the previous body as a
+// revision. Only the author can edit a comment. It returns the users the
+// new body mentions that the previous one did not.
+func (c *Comment) Edit(userID uuid.UUID, body string) ([]uuid.UUID, error) {
+	if c.IsDeleted() {
+		return nil, ErrCommentDeleted
+	}
+	if userID != c.AuthorID {
+		return nil, ErrNotCommentAuthor
+	}
+	body, mentions, err := validateCommentBody(body)
+	if err != nil {
+		return nil, err
+	}
+	if body == c.Body {
+		return []uuid.UUID{}, nil
+	}
+
+	mentioned := make(map[uuid.UUID]bool, len(c.Mentions))
+	for _, id := range c.Mentions {
+		mentioned[id] = true
+	}
+	added := []uuid.UUID{}
+	for _, id := range mentions {
+		if !mentioned[id] {
+			added = append(added, id)
+		}
+	}
+
+	now := time.Now().UTC()
+	c.Revisions = append(c.Revisions, CommentRevision{
+		Action:    CommentRevisionEdited,
+		Body:      c.Body,
+		ChangedBy: userID,
+		ChangedAt: now,
+	})
+	c.Body = body
+	c.Mentions = mentions
+	c.EditedAt = &now
+	c.UpdatedAt = now
+	return added, nil
+}
+
+// Delete removes the body of the comment, keeping it as a revision, and
+// leaves the comment in its thread as deleted. The author can delete a
+// comment, as can moderators.
+func (c *Comment) Delete(userID uuid.UUID, moderator bool) error {
+	if c.IsDeleted() {
+		return ErrCommentDeleted
+	}
+	if userID != c.AuthorID && !moderator {
+		return ErrNotCommentAuthor
+	}
+
+	now := time.Now().UTC()
+	c.Revisions = append(c.Revisions, CommentRevision{
+		Action:    CommentRevisionDeleted,
+		Body:      c.Body,
+		ChangedBy: userID,
+		ChangedAt: now,
+	})
+	c.Body = ""
+	c.Mentions = []uuid.UUID{}
+	c.DeletedAt = &now
+	c.DeletedBy = &userID
+	c.UpdatedAt = now
+	return nil
+}
+
+// Excerpt returns the start of the comment body, with mentions shown as
+// their display names, for notifications about the comment.
+func (c *Comment) Excerpt(maxLength int) string {
+	text := mentionPattern.ReplaceAllString(c.Body, "@$1")
+	runes := []rune(text)
+	if len(runes) <= maxLength {
+		return text
+	}
+	return strings.TrimSpace(string(runes[:maxLength])) + "…"
+}
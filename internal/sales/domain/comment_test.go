@@ -0,0 +1,148 @@
+package domain
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func createTestComment(t *testing.T, body string) *Comment {
+	t.Helper()
+
+	comment, err := NewComment(uuid.New(), CommentEntityOpportunity, uuid.New(), uuid.New(), body, nil)
+	if err != nil {
+		t.Fatalf("NewComment() error = %v", err)
+	}
+	return comment
+}
+
+func TestParseCommentMentions(t *testing.T) {
+	aminah, badrul := uuid.New(), uuid.New()
+	body := "@[Aminah](" + aminah.String() + ") can you check with @[Badrul Hisham](" + badrul.String() + ")? " +
+		"Thanks @[Aminah](" + aminah.String() + "), and ignore @Badrul and @[Nobody](not-a-uuid)"
+
+	mentions := ParseCommentMentions(body)
+	if len(mentions) != 2 || mentions[0] != aminah || mentions[1] != badrul {
+		t.Errorf("ParseCommentMentions() = %v, want [%s %s]", mentions, aminah, badrul)
+	}
+}
+
+func TestNewComment_Invalid(t *testing.T) {
+	tooManyMentions := ""
+	for i := 0; i <= MaxCommentMentions; i++ {
+		tooManyMentions += "@[User](" + uuid.NewString() + ") "
+	}
+
+	tests := []struct {
+		name       string
+		entityType CommentEntityType
+		body       string
+		want       error
+	}{
+		{"entity type", CommentEntityType("deal"), "Hello", ErrInvalidCommentEntityType},
+		{"empty", CommentEntityLead, "   ", ErrCommentBodyRequired},
+		{"too long", CommentEntityLead, strings.Repeat("a", MaxCommentBodyLength+1), ErrCommentBodyTooLong},
+		{"mentions", CommentEntityLead, tooManyMentions, ErrTooManyCommentMentions},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := NewComment(uuid.New(), tt.entityType, uuid.New(), uuid.New(), tt.body, nil); err != tt.want {
+				t.Errorf("NewComment() error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewComment_ReplyJoinsThread(t *testing.T) {
+	root := createTestComment(t, "Pricing approved?")
+
+	reply, err := NewComment(root.TenantID, root.EntityType, root.EntityID, uuid.New(), "Yes", root)
+	if err != nil {
+		t.Fatalf("NewComment() error = %v", err)
+	}
+	answer, err := NewComment(root.TenantID, root.EntityType, root.EntityID, uuid.New(), "Great", reply)
+	if err != nil {
+		t.Fatalf("NewComment() error = %v", err)
+	}
+	if answer.ParentID == nil || *answer.ParentID != root.ID {
+		t.Errorf("ParentID = %v, want the thread's top-level comment %s", answer.ParentID, root.ID)
+	}
+
+	if _, err := NewComment(root.TenantID, root.EntityType, uuid.New(), uuid.New(), "Elsewhere", root); err != ErrCommentNotFound {
+		t.Errorf("NewComment() on another record error = %v, want %v", err, ErrCommentNotFound)
+	}
+
+	root.Delete(root.AuthorID, false)
+	if _, err := NewComment(root.TenantID, root.EntityType, root.EntityID, uuid.New(), "Late", root); err != ErrCommentDeleted {
+		t.Errorf("NewComment() replying to a deleted comment error = %v, want %v", err, ErrCommentDeleted)
+	}
+}
+
+func TestComment_Edit(t *testing.T) {
+	aminah, badrul := uuid.New(), uuid.New()
+	comment := createTestComment(t, "Ask @[Aminah]("+aminah.String()+")")
+
+	if _, err := comment.Edit(uuid.New(), "Hijacked"); err != ErrNotCommentAuthor {
+		t.Errorf("Edit() by another user error = %v, want %v", err, ErrNotCommentAuthor)
+	}
+
+	added, err := comment.Edit(comment.AuthorID, "Ask @[Aminah]("+aminah.String()+") and @[Badrul]("+badrul.String()+")")
+	if err != nil {
+		t.Fatalf("Edit() error = %v", err)
+	}
+	if len(added) != 1 || added[0] != badrul {
+		t.Errorf("Edit() = %v, want only the newly mentioned %s", added, badrul)
+	}
+	if len(comment.Revisions) != 1 || comment.Revisions[0].Action != CommentRevisionEdited ||
+		comment.Revisions[0].Body != "Ask @[Aminah]("+aminah.String()+")" {
+		t.Errorf("Revisions = %+v, want the previous body", comment.Revisions)
+	}
+	if comment.EditedAt == nil {
+		t.Error("EditedAt = nil, want set")
+	}
+
+	// Saving the same body records nothing
+	comment.Edit(comment.AuthorID, comment.Body)
+	if len(comment.Revisions) != 1 {
+		t.Errorf("len(Revisions) = %d, want 1", len(comment.Revisions))
+	}
+}
+
+func TestComment_Delete(t *testing.T) {
+	comment := createTestComment(t, "Wrong customer, sorry")
+	moderator := uuid.New()
+
+	if err := comment.Delete(uuid.New(), false); err != ErrNotCommentAuthor {
+		t.Errorf("Delete() by another user error = %v, want %v", err, ErrNotCommentAuthor)
+	}
+	if err := comment.Delete(moderator, true); err != nil {
+		t.Fatalf("Delete() by a moderator error = %v", err)
+	}
+
+	if !comment.IsDeleted() || comment.Body != "" || comment.DeletedBy == nil || *comment.DeletedBy != moderator {
+		t.Errorf("comment = %+v, want deleted by the moderator", comment)
+	}
+	if len(comment.Revisions) != 1 || comment.Revisions[0].Action != CommentRevisionDeleted || comment.Revisions[0].Body != "Wrong customer, sorry" {
+		t.Errorf("Revisions = %+v, want the deleted body kept", comment.Revisions)
+	}
+
+	if err := comment.Delete(comment.AuthorID, false); err != ErrCommentDeleted {
+		t.Errorf("Delete() again error = %v, want %v", err, ErrCommentDeleted)
+	}
+	if _, err := comment.Edit(comment.AuthorID, "Back"); err != ErrCommentDeleted {
+		t.Errorf("Edit() of a deleted comment error = %v, want %v", err, ErrCommentDeleted)
+	}
+}
+
+func TestComment_Excerpt(t *testing.T) {
+	comment := createTestComment(t, "@[Aminah]("+uuid.NewString()+") please call the buyer about the batik order")
+
+	if got := comment.Excerpt(200); got != "@Aminah please call the buyer about the batik order" {
+		t.Errorf("Excerpt() = %q", got)
+	}
+	if got := comment.Excerpt(13); got != "@Aminah pleas…" {
+		t.Errorf("Excerpt(13) = %q", got)
+	}
+}
@@ -99,23 +99,26 @@ func (t PaymentTerm) DaysUntilDue() int {
 
 // DealLineItem represents a line item in a deal.
 type DealLineItem struct {
-	ID            uuid.UUID `json:"id" bson:"id"`
-	ProductID     uuid.UUID `json:"product_id" bson:"product_id"`
-	ProductName   string    `json:"product_name" bson:"product_name"`
-	ProductSKU    string    `json:"product_sku,omitempty" bson:"product_sku,omitempty"`
-	Description   string    `json:"description,omitempty" bson:"description,omitempty"`
-	Quantity      int       `json:"quantity" bson:"quantity"`
-	UnitPrice     Money     `json:"unit_price" bson:"unit_price"`
-	Discount      float64   `json:"discount" bson:"discount"`
-	DiscountType  string    `json:"discount_type" bson:"discount_type"` // percentage, fixed
-	Tax           float64   `json:"tax" bson:"tax"`
-	TaxType       string    `json:"tax_type" bson:"tax_type"` // percentage, fixed
-	Subtotal      Money     `json:"subtotal" bson:"subtotal"`
-	TaxAmount     Money     `json:"tax_amount" bson:"tax_amount"`
-	Total         Money     `json:"total" bson:"total"`
-	FulfilledQty  int       `json:"fulfilled_qty" bson:"fulfilled_qty"`
-	DeliveryDate  *time.Time `json:"delivery_date,omitempty" bson:"delivery_date,omitempty"`
-	Notes         string    `json:"notes,omitempty" bson:"notes,omitempty"`
+	ID           uuid.UUID  `json:"id" bson:"id"`
+	ProductID    uuid.UUID  `json:"product_id" bson:"product_id"`
+	ProductName  string     `json:"product_name" bson:"product_name"`
+	ProductSKU   string     `json:"product_sku,omitempty" bson:"product_sku,omitempty"`
+	Description  string     `json:"description,omitempty" bson:"description,omitempty"`
+	Quantity     int        `json:"quantity" bson:"quantity"`
+	UnitPrice    Money      `json:"unit_price" bson:"unit_price"`
+	Discount     float64    `json:"discount" bson:"discount"`
+	DiscountType string     `json:"discount_type" bson:"discount_type"` // percentage, fixed
+	Tax          float64    `json:"tax" bson:"tax"`
+	TaxType      string     `json:"tax_type" bson:"tax_type"` // percentage, fixed
+	TaxCode      string     `json:"tax_code,omitempty" bson:"tax_code,omitempty"`
+	TaxName      string     `json:"tax_name,omitempty" bson:"tax_name,omitempty"`
+	TaxInclusive bool       `json:"tax_inclusive" bson:"tax_inclusive"` // UnitPrice already includes percentage tax
+	Subtotal     Money      `json:"subtotal" bson:"subtotal"`
+	TaxAmount    Money      `json:"tax_amount" bson:"tax_amount"`
+	Total        Money      `json:"total" bson:"total"`
+	FulfilledQty int        `json:"fulfilled_qty" bson:"fulfilled_qty"`
+	DeliveryDate *time.Time `json:"delivery_date,omitempty" bson:"delivery_date,omitempty"`
+	Notes        string     `json:"notes,omitempty" bson:"notes,omitempty"`
 }
 
 // Calculate calculates the line item totals.
@@ -132,9 +135,9 @@ func (li *DealLineItem) Calculate() {
 
 	li.Subtotal, _ = basePrice.Subtract(discountAmount)
 
-	// Calculate tax
+	// Calculate tax; inclusive prices are split so the subtotal is net of tax
 	if li.TaxType == "percentage" {
-		li.TaxAmount = li.Subtotal.Multiply(li.Tax / 100)
+		li.Subtotal, li.TaxAmount = CalculateTax(li.Subtotal, li.Tax, li.TaxInclusive)
 	} else {
 		li.TaxAmount, _ = NewMoneyFromFloat(li.Tax, li.UnitPrice.Currency)
 	}
@@ -142,6 +145,22 @@ func (li *DealLineItem) Calculate() {
 	li.Total, _ = li.Subtotal.Add(li.TaxAmount)
 }
 
+// ApplyTaxRate sets the percentage tax charged on the line item. A nil rate removes tax.
+func (li *DealLineItem) ApplyTaxRate(rate *TaxRate, inclusive bool) {
+	li.TaxType = "percentage"
+	li.TaxInclusive = inclusive
+	if rate == nil {
+		li.TaxCode = ""
+		li.TaxName = ""
+		li.Tax = 0
+	} else {
+		li.TaxCode = rate.Code
+		li.TaxName = rate.Name
+		li.Tax = rate.Rate
+	}
+	li.Calculate()
+}
+
 // IsFulfilled returns true if the line item is fully fulfilled.
 func (li *DealLineItem) IsFulfilled() bool {
 	return li.FulfilledQty >= li.Quantity
@@ -154,16 +173,19 @@ func (li *DealLineItem) RemainingQuantity() int {
 
 // Invoice represents an invoice for a deal.
 type Invoice struct {
-	ID            uuid.UUID   `json:"id" bson:"id"`
-	InvoiceNumber string      `json:"invoice_number" bson:"invoice_number"`
-	Amount        Money       `json:"amount" bson:"amount"`
-	DueDate       time.Time   `json:"due_date" bson:"due_date"`
-	Status        string      `json:"status" bson:"status"` // draft, sent, paid, overdue, cancelled
-	SentAt        *time.Time  `json:"sent_at,omitempty" bson:"sent_at,omitempty"`
-	PaidAt        *time.Time  `json:"paid_at,omitempty" bson:"paid_at,omitempty"`
-	PaidAmount    Money       `json:"paid_amount" bson:"paid_amount"`
-	Notes         string      `json:"notes,omitempty" bson:"notes,omitempty"`
-	CreatedAt     time.Time   `json:"created_at" bson:"created_at"`
+	ID            uuid.UUID  `json:"id" bson:"id"`
+	InvoiceNumber string     `json:"invoice_number" bson:"invoice_number"`
+	Amount        Money      `json:"amount" bson:"amount"`
+	Subtotal      Money      `json:"subtotal" bson:"subtotal"`     // Amount net of tax
+	TaxAmount     Money      `json:"tax_amount" bson:"tax_amount"` // Tax included in Amount
+	TaxLines      []TaxLine  `json:"tax_lines,omitempty" bson:"tax_lines,omitempty"`
+	DueDate       time.Time  `json:"due_date" bson:"due_date"`
+	Status        string     `json:"status" bson:"status"` // draft, sent, paid, overdue, cancelled
+	SentAt        *time.Time `json:"sent_at,omitempty" bson:"sent_at,omitempty"`
+	PaidAt        *time.Time `json:"paid_at,omitempty" bson:"paid_at,omitempty"`
+	PaidAmount    Money      `json:"paid_amount" bson:"paid_amount"`
+	Notes         string     `json:"notes,omitempty" bson:"notes,omitempty"`
+	CreatedAt     time.Time  `json:"created_at" bson:"created_at"`
 }
 
 // IsPaid returns true if the invoice is paid.
@@ -212,11 +234,13 @@ type Deal struct {
 	Name              string                 `json:"name" bson:"name"`
 	Description       string                 `json:"description,omitempty" bson:"description,omitempty"`
 	Status            DealStatus             `json:"status" bson:"status"`
+	Type              DealType               `json:"type" bson:"type"`
 
 	// Source
 	OpportunityID     uuid.UUID              `json:"opportunity_id" bson:"opportunity_id"`
 	PipelineID        uuid.UUID              `json:"pipeline_id" bson:"pipeline_id"`
 	WonReason         string                 `json:"won_reason" bson:"won_reason"`
+	Attribution       *Attribution           `json:"attribution,omitempty" bson:"attribution,omitempty"` // Of the lead the opportunity came from
 
 	// Customer
 	CustomerID        uuid.UUID              `json:"customer_id" bson:"customer_id"`
@@ -236,6 +260,9 @@ type Deal struct {
 	// Line Items
 	LineItems         []DealLineItem         `json:"line_items" bson:"line_items"`
 
+	// Subscription, for recurring deals
+	Subscription      *DealSubscription      `json:"subscription,omitempty" bson:"subscription,omitempty"`
+
 	// Payment
 	PaymentTerm       PaymentTerm            `json:"payment_term" bson:"payment_term"`
 	PaymentTermDays   int                    `json:"payment_term_days" bson:"payment_term_days"`
@@ -285,9 +312,11 @@ func NewDealFromOpportunity(opportunity *Opportunity, createdBy uuid.UUID) (*Dea
 		Name:            opportunity.Name,
 		Description:     opportunity.Description,
 		Status:          DealStatusDraft,
+		Type:            DealTypeOneTime,
 		OpportunityID:   opportunity.ID,
 		PipelineID:      opportunity.PipelineID,
 		WonReason:       opportunity.CloseInfo.Reason,
+		Attribution:     opportunity.Attribution.Copy(),
 		CustomerID:      opportunity.CustomerID,
 		CustomerName:    opportunity.CustomerName,
 		Currency:        opportunity.Amount.Currency,
@@ -334,6 +363,9 @@ func NewDealFromOpportunity(opportunity *Opportunity, createdBy uuid.UUID) (*Dea
 			DiscountType: "percentage",
 			Tax:         p.Tax,
 			TaxType:     "percentage",
+			TaxCode:     p.TaxCode,
+			TaxName:     p.TaxName,
+			TaxInclusive: p.TaxInclusive,
 		}
 		lineItem.Calculate()
 		deal.LineItems = append(deal.LineItems, lineItem)
@@ -495,6 +527,24 @@ func (d *Deal) UpdateLineItem(itemID uuid.UUID, quantity int, unitPrice Money, d
 	return errors.New("line item not found")
 }
 
+// ApplyLineItemTax sets the tax rate charged on a line item in the deal.
+func (d *Deal) ApplyLineItemTax(itemID uuid.UUID, rate *TaxRate, inclusive bool) error {
+	if d.Status.IsClosed() {
+		return ErrDealAlreadyClosed
+	}
+
+	for i := range d.LineItems {
+		if d.LineItems[i].ID == itemID {
+			d.LineItems[i].ApplyTaxRate(rate, inclusive)
+			d.recalculateTotals()
+			d.UpdatedAt = time.Now().UTC()
+			return nil
+		}
+	}
+
+	return errors.New("line item not found")
+}
+
 // RemoveLineItem removes a line item from the deal.
 func (d *Deal) RemoveLineItem(itemID uuid.UUID) error {
 	if d.Status.IsClosed() {
@@ -541,9 +591,13 @@ func (d *Deal) recalculateTotals() {
 		totalTax, _ = totalTax.Add(li.TaxAmount)
 		total, _ = total.Add(li.Total)
 
-		// Calculate discount amount
+		// Calculate discount amount; inclusive prices carry the tax in the base price
 		basePrice := li.UnitPrice.Multiply(float64(li.Quantity))
-		discountAmount, _ := basePrice.Subtract(li.Subtotal)
+		discounted := li.Subtotal
+		if li.TaxInclusive && li.TaxType == "percentage" {
+			discounted = li.Total
+		}
+		discountAmount, _ := basePrice.Subtract(discounted)
 		totalDiscount, _ = totalDiscount.Add(discountAmount)
 	}
 
@@ -573,10 +627,14 @@ func (d *Deal) CreateInvoice(invoiceNumber string, amount Money, dueDate time.Ti
 	}
 
 	paidAmount, _ := Zero(d.Currency)
+	tax := d.TaxSummary().Prorate(amount)
 	invoice := Invoice{
 		ID:            uuid.New(),
 		InvoiceNumber: invoiceNumber,
 		Amount:        amount,
+		Subtotal:      tax.Subtotal,
+		TaxAmount:     tax.TotalTax,
+		TaxLines:      tax.Lines,
 		DueDate:       dueDate,
 		Status:        "draft",
 		PaidAmount:    paidAmount,
@@ -648,6 +706,26 @@ func (d *Deal) IsFullyPaid() bool {
 	return d.OutstandingAmount.Amount <= 0
 }
 
+// MarkOverdueInvoices marks the sent invoices whose due date passed before
+// now as overdue and returns how many were marked. Each raises an overdue
+// event once.
+func (d *Deal) MarkOverdueInvoices(now time.Time) int {
+	marked := 0
+	for i := range d.Invoices {
+		invoice := &d.Invoices[i]
+		if invoice.Status != "sent" || !now.After(invoice.DueDate) {
+			continue
+		}
+		invoice.Status = "overdue"
+		d.AddEvent(NewDealInvoiceOverdueEvent(d, invoice))
+		marked++
+	}
+	if marked > 0 {
+		d.UpdatedAt = now.UTC()
+	}
+	return marked
+}
+
 // GetOverdueInvoices returns all overdue invoices.
 func (d *Deal) GetOverdueInvoices() []Invoice {
 	var overdue []Invoice
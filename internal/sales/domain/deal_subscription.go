@@ -0,0 +1,395 @@
+package domain
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Subscription errors
+var (
+	ErrDealNotRecurring           = errors.New("deal is not recurring")
+	ErrInvalidBillingFrequency    = errors.New("invalid billing frequency")
+	ErrInvalidContractPeriod      = errors.New("contract end must be after its start")
+	ErrInvalidRecurringAmount     = errors.New("recurring amount must be positive")
+	ErrSubscriptionAlreadyChurned = errors.New("subscription has already churned")
+	ErrSubscriptionAlreadyRenewed = errors.New("subscription has already been renewed")
+)
+
+// DefaultRenewalNoticeDays is how many days before the contract end a
+// renewal opportunity is created, unless the deal sets its own notice.
+const DefaultRenewalNoticeDays = 30
+
+// RenewalOpportunitySource is the source of the opportunities created to
+// renew recurring deals.
+const RenewalOpportunitySource = "renewal"
+
+// DealType represents whether a deal is paid once or recurs.
+type DealType string
+
+const (
+	DealTypeOneTime   DealType = "one_time"
+	DealTypeRecurring DealType = "recurring"
+)
+
+// IsValid checks if the deal type is valid.
+func (t DealType) IsValid() bool {
+	return t == DealTypeOneTime || t == DealTypeRecurring
+}
+
+// BillingFrequency represents how often a recurring deal is billed.
+type BillingFrequency string
+
+const (
+	BillingFrequencyMonthly    BillingFrequency = "monthly"
+	BillingFrequencyQuarterly  BillingFrequency = "quarterly"
+	BillingFrequencySemiAnnual BillingFrequency = "semi_annual"
+	BillingFrequencyAnnual     BillingFrequency = "annual"
+)
+
+// Months returns the number of months billed at a time, or 0 for an invalid
+// frequency.
+func (f BillingFrequency) Months() int {
+	switch f {
+	case BillingFrequencyMonthly:
+		return 1
+	case BillingFrequencyQuarterly:
+		return 3
+	case BillingFrequencySemiAnnual:
+		return 6
+	case BillingFrequencyAnnual:
+		return 12
+	default:
+		return 0
+	}
+}
+
+// IsValid checks if the billing frequency is valid.
+func (f BillingFrequency) IsValid() bool {
+	return f.Months() > 0
+}
+
+// RenewalStatus represents where a subscription is in its renewal.
+type RenewalStatus string
+
+const (
+	RenewalStatusUpcoming   RenewalStatus = "upcoming"    // No renewal opportunity yet
+	RenewalStatusInProgress RenewalStatus = "in_progress" // Renewal opportunity open
+	RenewalStatusRenewed    RenewalStatus = "renewed"
+	RenewalStatusChurned    RenewalStatus = "churned"
+)
+
+// DealSubscription holds the contract of a recurring deal.
+type DealSubscription struct {
+	BillingFrequency     BillingFrequency `json:"billing_frequency" bson:"billing_frequency"`
+	RecurringAmount      Money            `json:"recurring_amount" bson:"recurring_amount"` // Billed each period
+	ContractStart        time.Time        `json:"contract_start" bson:"contract_start"`
+	ContractEnd          time.Time        `json:"contract_end" bson:"contract_end"`
+	RenewalNoticeDays    int              `json:"renewal_notice_days" bson:"renewal_notice_days"`
+	RenewalStatus        RenewalStatus    `json:"renewal_status" bson:"renewal_status"`
+	RenewalOpportunityID *uuid.UUID       `json:"renewal_opportunity_id,omitempty" bson:"renewal_opportunity_id,omitempty"`
+	RenewedFromDealID    *uuid.UUID       `json:"renewed_from_deal_id,omitempty" bson:"renewed_from_deal_id,omitempty"`
+	RenewedAt            *time.Time       `json:"renewed_at,omitempty" bson:"renewed_at,omitempty"`
+	ChurnedAt            *time.Time       `json:"churned_at,omitempty" bson:"churned_at,omitempty"`
+	ChurnReason          string           `json:"churn_reason,omitempty" bson:"churn_reason,omitempty"`
+}
+
+// NewDealSubscription creates the contract of a recurring deal. A notice of
+// zero days or less uses DefaultRenewalNoticeDays.
+func NewDealSubscription(frequency BillingFrequency, amount Money, start, end time.Time, renewalNoticeDays int) (*DealSubscription, error) {
+	if !frequency.IsValid() {
+		return nil, ErrInvalidBillingFrequency
+	}
+	if !amount.IsPositive() {
+		return nil, ErrInvalidRecurringAmount
+	}
+	if !end.After(start) {
+		return nil, ErrInvalidContractPeriod
+	}
+	if renewalNoticeDays <= 0 {
+		renewalNoticeDays = DefaultRenewalNoticeDays
+	}
+
+	return &DealSubscription{
+		BillingFrequency:  frequency,
+		RecurringAmount:   amount,
+		ContractStart:     start.UTC(),
+		ContractEnd:       end.UTC(),
+		RenewalNoticeDays: renewalNoticeDays,
+		RenewalStatus:     RenewalStatusUpcoming,
+	}, nil
+}
+
+// MRR returns the monthly recurring revenue of the contract.
+func (s *DealSubscription) MRR() Money {
+	return s.RecurringAmount.Multiply(1 / float64(s.BillingFrequency.Months()))
+}
+
+// ARR returns the annual recurring revenue of the contract.
+func (s *DealSubscription) ARR() Money {
+	return s.RecurringAmount.Multiply(12 / float64(s.BillingFrequency.Months()))
+}
+
+// TermMonths returns the length of the contract in whole months, at least one.
+func (s *DealSubscription) TermMonths() int {
+	months := (s.ContractEnd.Year()-s.ContractStart.Year())*12 + int(s.ContractEnd.Month()-s.ContractStart.Month())
+	if s.ContractEnd.Day() < s.ContractStart.Day() {
+		months--
+	}
+	if months < 1 {
+		return 1
+	}
+	return months
+}
+
+// ContractValue returns what the contract bills over its whole term.
+func (s *DealSubscription) ContractValue() Money {
+	return s.RecurringAmount.Multiply(float64(s.TermMonths()) / float64(s.BillingFrequency.Months()))
+}
+
+// RenewalDueAt returns when the renewal opportunity is due to be created.
+func (s *DealSubscription) RenewalDueAt() time.Time {
+	return s.ContractEnd.AddDate(0, 0, -s.RenewalNoticeDays)
+}
+
+// IsActiveAt reports whether the contract runs at t.
+func (s *DealSubscription) IsActiveAt(t time.Time) bool {
+	return !s.ContractStart.After(t) && s.ContractEnd.After(t)
+}
+
+// IsRecurring reports whether the deal is a subscription.
+func (d *Deal) IsRecurring() bool {
+	return d.Type == DealTypeRecurring && d.Subscription != nil
+}
+
+// SetSubscription makes the deal recurring under the contract, or changes
+// its terms. The renewal progress of a deal already recurring is kept.
+func (d *Deal) SetSubscription(subscription *DealSubscription) error {
+	if d.Status.IsClosed() {
+		return ErrDealAlreadyClosed
+	}
+	if subscription.RecurringAmount.Currency != d.Currency {
+		return ErrCurrencyMismatch
+	}
+
+	if d.Subscription != nil {
+		subscription.RenewalStatus = d.Subscription.RenewalStatus
+		subscription.RenewalOpportunityID = d.Subscription.RenewalOpportunityID
+		subscription.RenewedFromDealID = d.Subscription.RenewedFromDealID
+		subscription.RenewedAt = d.Subscription.RenewedAt
+		subscription.ChurnedAt = d.Subscription.ChurnedAt
+		subscription.ChurnReason = d.Subscription.ChurnReason
+	}
+	d.Type = DealTypeRecurring
+	d.Subscription = subscription
+	d.syncSubscriptionTimeline()
+	d.UpdatedAt = time.Now().UTC()
+
+	d.AddEvent(NewDealUpdatedEvent(d))
+	return nil
+}
+
+// ContinueSubscription makes the deal, won from the renewal opportunity of
+// previous, the next term of its subscription: the same billing for a term
+// as long, starting when the previous one ends. The recurring amount follows
+// the value the renewal was won at.
+func (d *Deal) ContinueSubscription(previous *Deal) error {
+	if !previous.IsRecurring() {
+		return ErrDealNotRecurring
+	}
+	prior := previous.Subscription
+
+	periods := float64(prior.TermMonths()) / float64(prior.BillingFrequency.Months())
+	amount := d.TotalAmount.Multiply(1 / periods)
+	if !amount.IsPositive() {
+		amount = prior.RecurringAmount
+	}
+	subscription, err := NewDealSubscription(prior.BillingFrequency, amount,
+		prior.ContractEnd, prior.ContractEnd.AddDate(0, prior.TermMonths(), 0), prior.RenewalNoticeDays)
+	if err != nil {
+		return err
+	}
+	subscription.RenewedFromDealID = &previous.ID
+	return d.SetSubscription(subscription)
+}
+
+// IsRenewalDue reports whether the deal needs a renewal opportunity at now.
+func (d *Deal) IsRenewalDue(now time.Time) bool {
+	return d.IsRecurring() && !d.IsDeleted() && d.Status != DealStatusCancelled &&
+		d.Subscription.RenewalStatus == RenewalStatusUpcoming &&
+		!now.Before(d.Subscription.RenewalDueAt())
+}
+
+// StartRenewal records the opportunity created to renew the subscription.
+func (d *Deal) StartRenewal(opportunityID uuid.UUID) error {
+	if err := d.checkRenewalOpen(); err != nil {
+		return err
+	}
+
+	d.Subscription.RenewalStatus = RenewalStatusInProgress
+	d.Subscription.RenewalOpportunityID = &opportunityID
+	d.UpdatedAt = time.Now().UTC()
+
+	d.AddEvent(NewDealRenewalStartedEvent(d))
+	return nil
+}
+
+// MarkRenewed records that the customer renewed the subscription.
+func (d *Deal) MarkRenewed() error {
+	if err := d.checkRenewalOpen(); err != nil {
+		return err
+	}
+
+	now := time.Now().UTC()
+	d.Subscription.RenewalStatus = RenewalStatusRenewed
+	d.Subscription.RenewedAt = &now
+	d.UpdatedAt = now
+
+	d.AddEvent(NewDealRenewedEvent(d))
+	return nil
+}
+
+// Churn records that the customer will not renew the subscription. The
+// contract still runs until its end.
+func (d *Deal) Churn(reason string) error {
+	if err := d.checkRenewalOpen(); err != nil {
+		return err
+	}
+
+	now := time.Now().UTC()
+	d.Subscription.RenewalStatus = RenewalStatusChurned
+	d.Subscription.ChurnedAt = &now
+	d.Subscription.ChurnReason = reason
+	d.UpdatedAt = now
+
+	d.AddEvent(NewDealChurnedEvent(d))
+	return nil
+}
+
+func (d *Deal) checkRenewalOpen() error {
+	if !d.IsRecurring() {
+		return ErrDealNotRecurring
+	}
+	switch d.Subscription.RenewalStatus {
+	case RenewalStatusChurned:
+		return ErrSubscriptionAlreadyChurned
+	case RenewalStatusRenewed:
+		return ErrSubscriptionAlreadyRenewed
+	}
+	return nil
+}
+
+// syncSubscriptionTimeline shows the contract in the deal timeline.
+func (d *Deal) syncSubscriptionTimeline() {
+	start := d.Subscription.ContractStart
+	end := d.Subscription.ContractEnd
+	renewal := d.Subscription.RenewalDueAt()
+	d.Timeline.StartDate = &start
+	d.Timeline.EndDate = &end
+	d.Timeline.RenewalDate = &renewal
+}
+
+// NewRenewalOpportunity creates the opportunity to renew a recurring deal,
+// for the value of another term, owned by the deal owner and expected to
+// close when the contract ends.
+func NewRenewalOpportunity(deal *Deal, pipeline *Pipeline) (*Opportunity, error) {
+	if !deal.IsRecurring() {
+		return nil, ErrDealNotRecurring
+	}
+
+	opportunity, err := NewOpportunity(
+		deal.TenantID,
+		"Renewal: "+deal.Name,
+		pipeline,
+		deal.CustomerID,
+		deal.CustomerName,
+		deal.Subscription.ContractValue(),
+		deal.OwnerID,
+		deal.OwnerName,
+		deal.OwnerID,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	closeDate := deal.Subscription.ContractEnd
+	opportunity.ExpectedCloseDate = &closeDate
+	opportunity.Source = RenewalOpportunitySource
+	opportunity.Attribution = deal.Attribution.Copy()
+	opportunity.TeamID = deal.TeamID
+	opportunity.Tags = append([]string{RenewalOpportunitySource}, deal.Tags...)
+	return opportunity, nil
+}
+
+// ============================================================================
+// Recurring Revenue Reporting
+// ============================================================================
+
+// RecurringRevenueSummary sums up recurring revenue over a report period, in
+// minor units of one currency.
+type RecurringRevenueSummary struct {
+	StartingMRR int64 // Of contracts running when the period starts
+	EndingMRR   int64 // Of contracts running when the period ends
+	NewMRR      int64 // Of new subscriptions starting in the period
+
+	// Contracts ending in the period, by renewal outcome
+	RenewalsDue int
+	Renewed     int
+	Churned     int
+	RenewedMRR  int64
+	ChurnedMRR  int64
+}
+
+// SummarizeRecurringRevenue sums up the recurring deals over the period.
+func SummarizeRecurringRevenue(deals []*Deal, period ReportPeriod) RecurringRevenueSummary {
+	var summary RecurringRevenueSummary
+	for _, deal := range deals {
+		if !deal.IsRecurring() || deal.Status == DealStatusCancelled {
+			continue
+		}
+		subscription := deal.Subscription
+		mrr := subscription.MRR().Amount
+
+		if subscription.IsActiveAt(period.From) {
+			summary.StartingMRR += mrr
+		}
+		if subscription.IsActiveAt(period.To) {
+			summary.EndingMRR += mrr
+		}
+		if period.Contains(subscription.ContractStart) && subscription.RenewedFromDealID == nil {
+			summary.NewMRR += mrr
+		}
+		// The contract end is the instant it stops running, so a contract
+		// ending at the start of the next period ends in this one
+		if subscription.ContractEnd.After(period.From) && !subscription.ContractEnd.After(period.To) {
+			summary.RenewalsDue++
+			switch subscription.RenewalStatus {
+			case RenewalStatusRenewed:
+				summary.Renewed++
+				summary.RenewedMRR += mrr
+			case RenewalStatusChurned:
+				summary.Churned++
+				summary.ChurnedMRR += mrr
+			}
+		}
+	}
+	return summary
+}
+
+// RenewalRate returns the percentage of decided renewals that renewed.
+func (s RecurringRevenueSummary) RenewalRate() float64 {
+	decided := s.Renewed + s.Churned
+	if decided == 0 {
+		return 0
+	}
+	return float64(s.Renewed) / float64(decided) * 100
+}
+
+// ChurnRate returns the churned MRR as a percentage of the starting MRR.
+func (s RecurringRevenueSummary) ChurnRate() float64 {
+	if s.StartingMRR == 0 {
+		return 0
+	}
+	return float64(s.ChurnedMRR) / float64(s.StartingMRR) * 100
+}
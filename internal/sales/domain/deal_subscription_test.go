@@ -0,0 +1,245 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func createTestRecurringDeal(t *testing.T, frequency BillingFrequency, amount int64) *Deal {
+	t.Helper()
+
+	deal := createTestDeal(t)
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	subscription, err := NewDealSubscription(frequency, Money{Amount: amount, Currency: "MYR"}, start, start.AddDate(1, 0, 0), 0)
+	if err != nil {
+		t.Fatalf("NewDealSubscription() error = %v", err)
+	}
+	if err := deal.SetSubscription(subscription); err != nil {
+		t.Fatalf("SetSubscription() error = %v", err)
+	}
+	deal.ClearEvents()
+	return deal
+}
+
+func TestNewDealSubscription_Invalid(t *testing.T) {
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	amount := Money{Amount: 10000, Currency: "MYR"}
+
+	tests := []struct {
+		name      string
+		frequency BillingFrequency
+		amount    Money
+		end       time.Time
+		want      error
+	}{
+		{"frequency", BillingFrequency("weekly"), amount, start.AddDate(1, 0, 0), ErrInvalidBillingFrequency},
+		{"amount", BillingFrequencyMonthly, Money{Currency: "MYR"}, start.AddDate(1, 0, 0), ErrInvalidRecurringAmount},
+		{"period", BillingFrequencyMonthly, amount, start, ErrInvalidContractPeriod},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := NewDealSubscription(tt.frequency, tt.amount, start, tt.end, 0); err != tt.want {
+				t.Errorf("NewDealSubscription() error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestDealSubscription_RecurringRevenue(t *testing.T) {
+	tests := []struct {
+		frequency BillingFrequency
+		amount    int64
+		wantMRR   int64
+		wantARR   int64
+	}{
+		{BillingFrequencyMonthly, 50000, 50000, 600000},
+		{BillingFrequencyQuarterly, 120000, 40000, 480000},
+		{BillingFrequencySemiAnnual, 300000, 50000, 600000},
+		{BillingFrequencyAnnual, 1200000, 100000, 1200000},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.frequency), func(t *testing.T) {
+			deal := createTestRecurringDeal(t, tt.frequency, tt.amount)
+			subscription := deal.Subscription
+			if got := subscription.MRR().Amount; got != tt.wantMRR {
+				t.Errorf("MRR() = %d, want %d", got, tt.wantMRR)
+			}
+			if got := subscription.ARR().Amount; got != tt.wantARR {
+				t.Errorf("ARR() = %d, want %d", got, tt.wantARR)
+			}
+			if got := subscription.TermMonths(); got != 12 {
+				t.Errorf("TermMonths() = %d, want 12", got)
+			}
+			if got := subscription.ContractValue().Amount; got != tt.wantARR {
+				t.Errorf("ContractValue() = %d, want %d", got, tt.wantARR)
+			}
+		})
+	}
+}
+
+func TestDeal_SetSubscription(t *testing.T) {
+	deal := createTestRecurringDeal(t, BillingFrequencyMonthly, 50000)
+
+	if !deal.IsRecurring() || deal.Type != DealTypeRecurring {
+		t.Fatalf("Type = %s, want recurring", deal.Type)
+	}
+	if deal.Timeline.EndDate == nil || !deal.Timeline.EndDate.Equal(deal.Subscription.ContractEnd) {
+		t.Errorf("Timeline.EndDate = %v, want the contract end", deal.Timeline.EndDate)
+	}
+	wantDue := time.Date(2026, 12, 2, 0, 0, 0, 0, time.UTC)
+	if !deal.Subscription.RenewalDueAt().Equal(wantDue) {
+		t.Errorf("RenewalDueAt() = %v, want %v", deal.Subscription.RenewalDueAt(), wantDue)
+	}
+
+	usd, _ := NewDealSubscription(BillingFrequencyMonthly, Money{Amount: 100, Currency: "USD"},
+		deal.Subscription.ContractStart, deal.Subscription.ContractEnd, 0)
+	if err := deal.SetSubscription(usd); err != ErrCurrencyMismatch {
+		t.Errorf("SetSubscription() error = %v, want %v", err, ErrCurrencyMismatch)
+	}
+}
+
+func TestDeal_Renewal(t *testing.T) {
+	deal := createTestRecurringDeal(t, BillingFrequencyMonthly, 50000)
+
+	if deal.IsRenewalDue(time.Date(2026, 12, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Error("IsRenewalDue() = true before the renewal notice")
+	}
+	if !deal.IsRenewalDue(time.Date(2026, 12, 2, 0, 0, 0, 0, time.UTC)) {
+		t.Error("IsRenewalDue() = false once the renewal notice started")
+	}
+
+	opportunityID := uuid.New()
+	if err := deal.StartRenewal(opportunityID); err != nil {
+		t.Fatalf("StartRenewal() error = %v", err)
+	}
+	if deal.IsRenewalDue(time.Date(2026, 12, 2, 0, 0, 0, 0, time.UTC)) {
+		t.Error("IsRenewalDue() = true with a renewal in progress")
+	}
+	if err := deal.MarkRenewed(); err != nil {
+		t.Fatalf("MarkRenewed() error = %v", err)
+	}
+	if err := deal.Churn("too expensive"); err != ErrSubscriptionAlreadyRenewed {
+		t.Errorf("Churn() error = %v, want %v", err, ErrSubscriptionAlreadyRenewed)
+	}
+
+	events := deal.GetEvents()
+	if len(events) != 2 || events[0].EventType() != "deal.renewal_started" || events[1].EventType() != "deal.renewed" {
+		t.Errorf("events = %v, want renewal started and renewed", events)
+	}
+}
+
+func TestDeal_Churn(t *testing.T) {
+	deal := createTestRecurringDeal(t, BillingFrequencyMonthly, 50000)
+
+	if err := deal.Churn("moved to another supplier"); err != nil {
+		t.Fatalf("Churn() error = %v", err)
+	}
+	if deal.Subscription.RenewalStatus != RenewalStatusChurned || deal.Subscription.ChurnedAt == nil {
+		t.Errorf("Subscription = %+v, want churned", deal.Subscription)
+	}
+	if err := deal.Churn("again"); err != ErrSubscriptionAlreadyChurned {
+		t.Errorf("Churn() error = %v, want %v", err, ErrSubscriptionAlreadyChurned)
+	}
+
+	oneTime := createTestDeal(t)
+	if err := oneTime.Churn("not recurring"); err != ErrDealNotRecurring {
+		t.Errorf("Churn() error = %v, want %v", err, ErrDealNotRecurring)
+	}
+}
+
+func TestDeal_ContinueSubscription(t *testing.T) {
+	previous := createTestRecurringDeal(t, BillingFrequencyQuarterly, 120000)
+	deal := createTestDeal(t)
+	deal.TotalAmount = Money{Amount: 600000, Currency: "MYR"}
+
+	if err := deal.ContinueSubscription(previous); err != nil {
+		t.Fatalf("ContinueSubscription() error = %v", err)
+	}
+
+	subscription := deal.Subscription
+	if !subscription.ContractStart.Equal(previous.Subscription.ContractEnd) {
+		t.Errorf("ContractStart = %v, want the previous contract end", subscription.ContractStart)
+	}
+	if subscription.TermMonths() != 12 || subscription.BillingFrequency != BillingFrequencyQuarterly {
+		t.Errorf("Subscription = %+v, want another quarterly year", subscription)
+	}
+	if subscription.RecurringAmount.Amount != 150000 {
+		t.Errorf("RecurringAmount = %d, want the renewal value over four quarters", subscription.RecurringAmount.Amount)
+	}
+	if subscription.RenewedFromDealID == nil || *subscription.RenewedFromDealID != previous.ID {
+		t.Error("RenewedFromDealID not set to the previous deal")
+	}
+}
+
+func TestNewRenewalOpportunity(t *testing.T) {
+	deal := createTestRecurringDeal(t, BillingFrequencyMonthly, 50000)
+	pipeline := createTestOpportunityPipeline(t)
+	pipeline.TenantID = deal.TenantID
+
+	opportunity, err := NewRenewalOpportunity(deal, pipeline)
+	if err != nil {
+		t.Fatalf("NewRenewalOpportunity() error = %v", err)
+	}
+
+	if opportunity.Source != RenewalOpportunitySource || opportunity.CustomerID != deal.CustomerID || opportunity.OwnerID != deal.OwnerID {
+		t.Errorf("opportunity = %+v, want a renewal for the deal customer and owner", opportunity)
+	}
+	if opportunity.Amount.Amount != 600000 {
+		t.Errorf("Amount = %d, want the value of another term", opportunity.Amount.Amount)
+	}
+	if opportunity.ExpectedCloseDate == nil || !opportunity.ExpectedCloseDate.Equal(deal.Subscription.ContractEnd) {
+		t.Errorf("ExpectedCloseDate = %v, want the contract end", opportunity.ExpectedCloseDate)
+	}
+
+	if _, err := NewRenewalOpportunity(createTestDeal(t), pipeline); err != ErrDealNotRecurring {
+		t.Errorf("NewRenewalOpportunity() error = %v, want %v", err, ErrDealNotRecurring)
+	}
+}
+
+func TestSummarizeRecurringRevenue(t *testing.T) {
+	// Runs through 2026, renewed
+	renewed := createTestRecurringDeal(t, BillingFrequencyMonthly, 50000)
+	renewed.Subscription.RenewalStatus = RenewalStatusRenewed
+
+	// Runs through 2026, churned
+	churned := createTestRecurringDeal(t, BillingFrequencyAnnual, 1200000)
+	churned.Subscription.RenewalStatus = RenewalStatusChurned
+
+	// Starts mid December
+	started := createTestRecurringDeal(t, BillingFrequencyMonthly, 30000)
+	started.Subscription.ContractStart = time.Date(2026, 12, 15, 0, 0, 0, 0, time.UTC)
+	started.Subscription.ContractEnd = time.Date(2027, 12, 15, 0, 0, 0, 0, time.UTC)
+
+	cancelled := createTestRecurringDeal(t, BillingFrequencyMonthly, 99999)
+	cancelled.Status = DealStatusCancelled
+
+	period := ReportPeriod{
+		From: time.Date(2026, 12, 1, 0, 0, 0, 0, time.UTC),
+		To:   time.Date(2027, 1, 1, 0, 0, 0, 0, time.UTC),
+	}
+	summary := SummarizeRecurringRevenue([]*Deal{renewed, churned, started, cancelled, createTestDeal(t)}, period)
+
+	want := RecurringRevenueSummary{
+		StartingMRR: 150000,
+		EndingMRR:   30000,
+		NewMRR:      30000,
+		RenewalsDue: 2,
+		Renewed:     1,
+		Churned:     1,
+		RenewedMRR:  50000,
+		ChurnedMRR:  100000,
+	}
+	if summary != want {
+		t.Errorf("SummarizeRecurringRevenue() = %+v, want %+v", summary, want)
+	}
+	if summary.RenewalRate() != 50 {
+		t.Errorf("RenewalRate() = %v, want 50", summary.RenewalRate())
+	}
+	if rate := summary.ChurnRate(); rate < 66.6 || rate > 66.7 {
+		t.Errorf("ChurnRate() = %v, want about 66.7", rate)
+	}
+}
@@ -438,6 +438,7 @@ func createTestPipelineForDeal(t *testing.T, tenantID uuid.UUID) *Pipeline {
 
 func TestNewDealFromOpportunity_Success(t *testing.T) {
 	opp, _ := createTestWonOpportunity(t)
+	opp.Attribution = &Attribution{UTMSource: "google", UTMMedium: "cpc"}
 	createdBy := uuid.New()
 
 	deal, err := NewDealFromOpportunity(opp, createdBy)
@@ -466,6 +467,9 @@ func TestNewDealFromOpportunity_Success(t *testing.T) {
 	if deal.CustomerID != opp.CustomerID {
 		t.Errorf("CustomerID = %v, want %v", deal.CustomerID, opp.CustomerID)
 	}
+	if deal.Attribution == nil || *deal.Attribution != *opp.Attribution {
+		t.Errorf("Attribution = %+v, want %+v", deal.Attribution, opp.Attribution)
+	}
 	if deal.WonReason != opp.CloseInfo.Reason {
 		t.Errorf("WonReason = %s, want %s", deal.WonReason, opp.CloseInfo.Reason)
 	}
@@ -1202,6 +1206,39 @@ func TestDeal_GetOverdueInvoices(t *testing.T) {
 	}
 }
 
+func TestDeal_MarkOverdueInvoices(t *testing.T) {
+	deal := createTestDeal(t)
+	now := time.Now().UTC()
+
+	deal.Invoices = []Invoice{
+		{ID: uuid.New(), InvoiceNumber: "INV-001", Status: "sent", DueDate: now.AddDate(0, 0, -1)},
+		{ID: uuid.New(), InvoiceNumber: "INV-002", Status: "paid", DueDate: now.AddDate(0, 0, -5)},
+		{ID: uuid.New(), InvoiceNumber: "INV-003", Status: "sent", DueDate: now.AddDate(0, 0, 10)},
+		{ID: uuid.New(), InvoiceNumber: "INV-004", Status: "draft", DueDate: now.AddDate(0, 0, -3)},
+	}
+	deal.ClearEvents()
+
+	if marked := deal.MarkOverdueInvoices(now); marked != 1 {
+		t.Fatalf("MarkOverdueInvoices() = %d, want 1", marked)
+	}
+	if deal.Invoices[0].Status != "overdue" || deal.Invoices[2].Status != "sent" || deal.Invoices[3].Status != "draft" {
+		t.Errorf("invoice statuses = %s, %s, %s", deal.Invoices[0].Status, deal.Invoices[2].Status, deal.Invoices[3].Status)
+	}
+
+	events := deal.GetEvents()
+	if len(events) != 1 || events[0].EventType() != "deal.invoice_overdue" {
+		t.Fatalf("events = %v, want one overdue event", events)
+	}
+	if event := events[0].(*DealInvoiceOverdueEvent); event.InvoiceNumber != "INV-001" {
+		t.Errorf("InvoiceNumber = %s, want INV-001", event.InvoiceNumber)
+	}
+
+	// An invoice is only marked overdue once
+	if marked := deal.MarkOverdueInvoices(now); marked != 0 {
+		t.Errorf("MarkOverdueInvoices() = %d on the second run, want 0", marked)
+	}
+}
+
 func TestDeal_FulfillmentProgress(t *testing.T) {
 	tests := []struct {
 		name           string
@@ -0,0 +1,369 @@
+// Package domain contains the domain layer for the Sales Pipeline service.
+package domain
+
+import (
+	"errors"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Discount approval errors
+var (
+	ErrDiscountApprovalRuleNotFound     = errors.New("discount approval rule not found")
+	ErrDiscountApprovalNotFound         = errors.New("discount approval not found")
+	ErrInvalidDiscountApprovalRuleName  = errors.New("discount approval rule name is required")
+	ErrInvalidDiscountThresholdType     = errors.New("invalid discount threshold type")
+	ErrInvalidDiscountThreshold         = errors.New("discount threshold must be positive and a percentage at most 100")
+	ErrDiscountApproversRequired        = errors.New("a discount approval rule needs at least one approver")
+	ErrDiscountApprovalNotRequired      = errors.New("the discount is within every approval rule")
+	ErrNoDiscountApprover               = errors.New("no approver other than the requester")
+	ErrDiscountApprovalAlreadyDecided   = errors.New("discount approval has already been decided")
+	ErrNotDiscountApprover              = errors.New("user is not an approver of this discount")
+	ErrDiscountRejectionCommentRequired = errors.New("a comment is required to reject a discount")
+	ErrDiscountApprovalRequired         = errors.New("the discount needs an approved discount approval")
+)
+
+// DiscountThresholdType identifies what a discount approval rule measures.
+type DiscountThresholdType string
+
+const (
+	// DiscountThresholdPercentage rules compare the discount as a share of the list price.
+	DiscountThresholdPercentage DiscountThresholdType = "percentage"
+	// DiscountThresholdAmount rules compare the discount amount, in the rule's currency.
+	DiscountThresholdAmount DiscountThresholdType = "amount"
+)
+
+// IsValid checks if the threshold type is valid.
+func (t DiscountThresholdType) IsValid() bool {
+	return t == DiscountThresholdPercentage || t == DiscountThresholdAmount
+}
+
+// DiscountApprovalStatus represents the status of a discount approval.
+type DiscountApprovalStatus string
+
+const (
+	DiscountApprovalStatusPending   DiscountApprovalStatus = "pending"
+	DiscountApprovalStatusApproved  DiscountApprovalStatus = "approved"
+	DiscountApprovalStatusRejected  DiscountApprovalStatus = "rejected"
+	DiscountApprovalStatusCancelled DiscountApprovalStatus = "cancelled"
+)
+
+// Discount is the discount given on an opportunity's product lines.
+type Discount struct {
+	// Amount is the discount off the list price, before tax.
+	Amount Money `json:"amount"`
+	// Percent is Amount as a share of the list price, rounded to two decimals.
+	Percent float64 `json:"percent"`
+}
+
+// OpportunityDiscount returns the discount given on an opportunity's product lines.
+func OpportunityDiscount(o *Opportunity) Discount {
+	var listPrice, discount int64
+	for _, p := range o.Products {
+		base := p.UnitPrice.Multiply(float64(p.Quantity))
+		listPrice += base.Amount
+		discount += base.Multiply(p.Discount / 100).Amount
+	}
+
+	d := Discount{Amount: Money{Amount: discount, Currency: o.Amount.Currency}}
+	if listPrice > 0 {
+		d.Percent = math.Round(float64(discount)/float64(listPrice)*10000) / 100
+	}
+	return d
+}
+
+// ============================================================================
+// Discount Approval Rule
+// ============================================================================
+
+// DiscountApprovalRule requires one of its approvers to approve discounts
+// above its threshold before the quote is sent or the opportunity is won.
+type DiscountApprovalRule struct {
+	ID            uuid.UUID             `json:"id"`
+	TenantID      uuid.UUID             `json:"tenant_id"`
+	Name          string                `json:"name"`
+	ThresholdType DiscountThresholdType `json:"threshold_type"`
+	// ThresholdPercent is the highest percentage allowed without approval, for percentage rules.
+	ThresholdPercent float64 `json:"threshold_percent,omitempty"`
+	// ThresholdAmount is the highest amount allowed without approval, for amount
+	// rules. Discounts in other currencies are not checked against it.
+	ThresholdAmount Money       `json:"threshold_amount"`
+	ApproverIDs     []uuid.UUID `json:"approver_ids"`
+	IsActive        bool        `json:"is_active"`
+	CreatedAt       time.Time   `json:"created_at"`
+	UpdatedAt       time.Time   `json:"updated_at"`
+}
+
+// NewDiscountApprovalRule creates a new active rule.
+func NewDiscountApprovalRule(tenantID uuid.UUID, name string, thresholdType DiscountThresholdType, thresholdPercent float64, thresholdAmount Money, approverIDs []uuid.UUID) (*DiscountApprovalRule, error) {
+	now := time.Now().UTC()
+	rule := &DiscountApprovalRule{
+		ID:        uuid.New(),
+		TenantID:  tenantID,
+		IsActive:  true,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if err := rule.Update(name, thresholdType, thresholdPercent, thresholdAmount, approverIDs); err != nil {
+		return nil, err
+	}
+	return rule, nil
+}
+
+// Update updates the rule's name, threshold and approvers.
+func (r *DiscountApprovalRule) Update(name string, thresholdType DiscountThresholdType, thresholdPercent float64, thresholdAmount Money, approverIDs []uuid.UUID) error {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return ErrInvalidDiscountApprovalRuleName
+	}
+
+	switch thresholdType {
+	case DiscountThresholdPercentage:
+		if thresholdPercent <= 0 || thresholdPercent > 100 {
+			return ErrInvalidDiscountThreshold
+		}
+		thresholdAmount = Money{}
+	case DiscountThresholdAmount:
+		if !thresholdAmount.IsPositive() {
+			return ErrInvalidDiscountThreshold
+		}
+		if !IsSupportedCurrency(thresholdAmount.Currency) {
+			return ErrInvalidCurrency
+		}
+		thresholdPercent = 0
+	default:
+		return ErrInvalidDiscountThresholdType
+	}
+
+	approvers := uniqueUUIDs(approverIDs)
+	if len(approvers) == 0 {
+		return ErrDiscountApproversRequired
+	}
+
+	r.Name = name
+	r.ThresholdType = thresholdType
+	r.ThresholdPercent = thresholdPercent
+	r.ThresholdAmount = thresholdAmount
+	r.ApproverIDs = approvers
+	r.UpdatedAt = time.Now().UTC()
+	return nil
+}
+
+// Activate makes the rule apply again.
+func (r *DiscountApprovalRule) Activate() {
+	r.IsActive = true
+	r.UpdatedAt = time.Now().UTC()
+}
+
+// Deactivate stops the rule from applying.
+func (r *DiscountApprovalRule) Deactivate() {
+	r.IsActive = false
+	r.UpdatedAt = time.Now().UTC()
+}
+
+// Exceeds returns true if the discount is above the rule's threshold.
+func (r *DiscountApprovalRule) Exceeds(discount Discount) bool {
+	if !r.IsActive {
+		return false
+	}
+	switch r.ThresholdType {
+	case DiscountThresholdPercentage:
+		return discount.Percent > r.ThresholdPercent
+	case DiscountThresholdAmount:
+		return discount.Amount.Currency == r.ThresholdAmount.Currency && discount.Amount.Amount > r.ThresholdAmount.Amount
+	}
+	return false
+}
+
+// ExceededDiscountRules returns the rules whose threshold the discount is above.
+func ExceededDiscountRules(rules []*DiscountApprovalRule, discount Discount) []*DiscountApprovalRule {
+	var exceeded []*DiscountApprovalRule
+	for _, rule := range rules {
+		if rule.Exceeds(discount) {
+			exceeded = append(exceeded, rule)
+		}
+	}
+	return exceeded
+}
+
+// ============================================================================
+// Discount Approval
+// ============================================================================
+
+// DiscountApproval is a request for a manager to approve the discount given on
+// an opportunity. An approval covers the discount it was requested for and any
+// smaller one; a larger discount needs a new approval.
+type DiscountApproval struct {
+	ID              uuid.UUID              `json:"id"`
+	TenantID        uuid.UUID              `json:"tenant_id"`
+	OpportunityID   uuid.UUID              `json:"opportunity_id"`
+	OpportunityName string                 `json:"opportunity_name"`
+	Status          DiscountApprovalStatus `json:"status"`
+	Discount        Discount               `json:"discount"`
+	RuleIDs         []uuid.UUID            `json:"rule_ids"`
+	ApproverIDs     []uuid.UUID            `json:"approver_ids"`
+	RequestedBy     uuid.UUID              `json:"requested_by"`
+	RequestComment  string                 `json:"request_comment,omitempty"`
+	DecidedBy       *uuid.UUID             `json:"decided_by,omitempty"`
+	DecisionComment string                 `json:"decision_comment,omitempty"`
+	RequestedAt     time.Time              `json:"requested_at"`
+	DecidedAt       *time.Time             `json:"decided_at,omitempty"`
+	Version         int                    `json:"version"`
+
+	// Domain events
+	events []DomainEvent `json:"-"`
+}
+
+// NewDiscountApproval requests approval of an open opportunity's current
+// discount from the approvers of the rules it exceeds. The requester cannot
+// approve their own discount.
+func NewDiscountApproval(opportunity *Opportunity, rules []*DiscountApprovalRule, requestedBy uuid.UUID, comment string) (*DiscountApproval, error) {
+	if opportunity.Status.IsClosed() {
+		return nil, ErrOpportunityAlreadyClosed
+	}
+
+	discount := OpportunityDiscount(opportunity)
+	exceeded := ExceededDiscountRules(rules, discount)
+	if len(exceeded) == 0 {
+		return nil, ErrDiscountApprovalNotRequired
+	}
+
+	ruleIDs := make([]uuid.UUID, len(exceeded))
+	var approvers []uuid.UUID
+	for i, rule := range exceeded {
+		ruleIDs[i] = rule.ID
+		for _, approverID := range rule.ApproverIDs {
+			if approverID != requestedBy {
+				approvers = append(approvers, approverID)
+			}
+		}
+	}
+	approvers = uniqueUUIDs(approvers)
+	if len(approvers) == 0 {
+		return nil, ErrNoDiscountApprover
+	}
+
+	approval := &DiscountApproval{
+		ID:              uuid.New(),
+		TenantID:        opportunity.TenantID,
+		OpportunityID:   opportunity.ID,
+		OpportunityName: opportunity.Name,
+		Status:          DiscountApprovalStatusPending,
+		Discount:        discount,
+		RuleIDs:         ruleIDs,
+		ApproverIDs:     approvers,
+		RequestedBy:     requestedBy,
+		RequestComment:  strings.TrimSpace(comment),
+		RequestedAt:     time.Now().UTC(),
+		Version:         1,
+		events:          make([]DomainEvent, 0),
+	}
+	approval.AddEvent(NewDiscountApprovalRequestedEvent(approval))
+	return approval, nil
+}
+
+// IsApprover returns true if the user can decide the approval.
+func (a *DiscountApproval) IsApprover(userID uuid.UUID) bool {
+	for _, approverID := range a.ApproverIDs {
+		if approverID == userID {
+			return true
+		}
+	}
+	return false
+}
+
+// Approve grants the discount.
+func (a *DiscountApproval) Approve(approverID uuid.UUID, comment string) error {
+	return a.decide(DiscountApprovalStatusApproved, approverID, comment)
+}
+
+// Reject refuses the discount. A comment telling the requester why is required.
+func (a *DiscountApproval) Reject(approverID uuid.UUID, comment string) error {
+	if strings.TrimSpace(comment) == "" {
+		return ErrDiscountRejectionCommentRequired
+	}
+	return a.decide(DiscountApprovalStatusRejected, approverID, comment)
+}
+
+func (a *DiscountApproval) decide(status DiscountApprovalStatus, approverID uuid.UUID, comment string) error {
+	if a.Status != DiscountApprovalStatusPending {
+		return ErrDiscountApprovalAlreadyDecided
+	}
+	if !a.IsApprover(approverID) {
+		return ErrNotDiscountApprover
+	}
+
+	now := time.Now().UTC()
+	a.Status = status
+	a.DecidedBy = &approverID
+	a.DecisionComment = strings.TrimSpace(comment)
+	a.DecidedAt = &now
+
+	a.AddEvent(NewDiscountApprovalDecidedEvent(a))
+	return nil
+}
+
+// Cancel withdraws a pending approval, as when it is superseded by a new request.
+func (a *DiscountApproval) Cancel() error {
+	if a.Status != DiscountApprovalStatusPending {
+		return ErrDiscountApprovalAlreadyDecided
+	}
+	now := time.Now().UTC()
+	a.Status = DiscountApprovalStatusCancelled
+	a.DecidedAt = &now
+	return nil
+}
+
+// Covers returns true if the approval was granted for the discount or a larger one.
+func (a *DiscountApproval) Covers(discount Discount) bool {
+	return a.Status == DiscountApprovalStatusApproved &&
+		a.Discount.Amount.Currency == discount.Amount.Currency &&
+		discount.Amount.Amount <= a.Discount.Amount.Amount &&
+		discount.Percent <= a.Discount.Percent
+}
+
+// AddEvent adds a domain event.
+func (a *DiscountApproval) AddEvent(event DomainEvent) {
+	a.events = append(a.events, event)
+}
+
+// GetEvents returns all domain events.
+func (a *DiscountApproval) GetEvents() []DomainEvent {
+	return a.events
+}
+
+// ClearEvents clears all domain events.
+func (a *DiscountApproval) ClearEvents() {
+	a.events = make([]DomainEvent, 0)
+}
+
+// CheckDiscountApproval returns ErrDiscountApprovalRequired if an opportunity's
+// discount exceeds an active rule and latest, the opportunity's most recent
+// approval, does not cover it. latest may be nil.
+func CheckDiscountApproval(opportunity *Opportunity, rules []*DiscountApprovalRule, latest *DiscountApproval) error {
+	discount := OpportunityDiscount(opportunity)
+	if len(ExceededDiscountRules(rules, discount)) == 0 {
+		return nil
+	}
+	if latest != nil && latest.Covers(discount) {
+		return nil
+	}
+	return ErrDiscountApprovalRequired
+}
+
+// uniqueUUIDs returns the IDs without duplicates or nil IDs, keeping their order.
+func uniqueUUIDs(ids []uuid.UUID) []uuid.UUID {
+	seen := make(map[uuid.UUID]bool, len(ids))
+	unique := make([]uuid.UUID, 0, len(ids))
+	for _, id := range ids {
+		if id == uuid.Nil || seen[id] {
+			continue
+		}
+		seen[id] = true
+		unique = append(unique, id)
+	}
+	return unique
+}
@@ -0,0 +1,211 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func createTestDiscountedOpportunity(t *testing.T, discountPercent float64) *Opportunity {
+	t.Helper()
+
+	opp, _ := createTestOpportunity(t)
+	opp.Products = []OpportunityProduct{
+		{
+			ID:        uuid.New(),
+			Quantity:  4,
+			UnitPrice: Money{Amount: 25000, Currency: "USD"},
+			Discount:  discountPercent,
+		},
+	}
+	return opp
+}
+
+func createTestDiscountRule(t *testing.T, tenantID uuid.UUID, percent float64, approverIDs ...uuid.UUID) *DiscountApprovalRule {
+	t.Helper()
+
+	rule, err := NewDiscountApprovalRule(tenantID, "Managers", DiscountThresholdPercentage, percent, Money{}, approverIDs)
+	if err != nil {
+		t.Fatalf("NewDiscountApprovalRule() error = %v", err)
+	}
+	return rule
+}
+
+func TestNewDiscountApprovalRule_Invalid(t *testing.T) {
+	approvers := []uuid.UUID{uuid.New()}
+
+	tests := []struct {
+		name          string
+		ruleName      string
+		thresholdType DiscountThresholdType
+		percent       float64
+		amount        Money
+		approvers     []uuid.UUID
+		want          error
+	}{
+		{"name", " ", DiscountThresholdPercentage, 10, Money{}, approvers, ErrInvalidDiscountApprovalRuleName},
+		{"type", "Managers", DiscountThresholdType("margin"), 10, Money{}, approvers, ErrInvalidDiscountThresholdType},
+		{"percent", "Managers", DiscountThresholdPercentage, 120, Money{}, approvers, ErrInvalidDiscountThreshold},
+		{"amount", "Managers", DiscountThresholdAmount, 0, Money{Currency: "MYR"}, approvers, ErrInvalidDiscountThreshold},
+		{"currency", "Managers", DiscountThresholdAmount, 0, Money{Amount: 100, Currency: "XXX"}, approvers, ErrInvalidCurrency},
+		{"approvers", "Managers", DiscountThresholdPercentage, 10, Money{}, []uuid.UUID{uuid.Nil}, ErrDiscountApproversRequired},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := NewDiscountApprovalRule(uuid.New(), tt.ruleName, tt.thresholdType, tt.percent, tt.amount, tt.approvers); err != tt.want {
+				t.Errorf("NewDiscountApprovalRule() error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestOpportunityDiscount(t *testing.T) {
+	opp := createTestDiscountedOpportunity(t, 15)
+	opp.Products = append(opp.Products, OpportunityProduct{
+		ID:        uuid.New(),
+		Quantity:  1,
+		UnitPrice: Money{Amount: 100000, Currency: "USD"},
+	})
+
+	discount := OpportunityDiscount(opp)
+	if discount.Amount.Amount != 15000 || discount.Amount.Currency != "USD" {
+		t.Errorf("OpportunityDiscount() Amount = %+v, want 15000 USD", discount.Amount)
+	}
+	if discount.Percent != 7.5 {
+		t.Errorf("OpportunityDiscount() Percent = %v, want 7.5", discount.Percent)
+	}
+}
+
+func TestDiscountApprovalRule_Exceeds(t *testing.T) {
+	tenantID := uuid.New()
+	percentRule := createTestDiscountRule(t, tenantID, 10, uuid.New())
+	amountRule, err := NewDiscountApprovalRule(tenantID, "Large discounts", DiscountThresholdAmount, 0, Money{Amount: 20000, Currency: "USD"}, []uuid.UUID{uuid.New()})
+	if err != nil {
+		t.Fatalf("NewDiscountApprovalRule() error = %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		rule     *DiscountApprovalRule
+		discount Discount
+		want     bool
+	}{
+		{"percent at threshold", percentRule, Discount{Amount: Money{Amount: 10000, Currency: "USD"}, Percent: 10}, false},
+		{"percent above threshold", percentRule, Discount{Amount: Money{Amount: 11000, Currency: "USD"}, Percent: 11}, true},
+		{"amount above threshold", amountRule, Discount{Amount: Money{Amount: 25000, Currency: "USD"}, Percent: 5}, true},
+		{"amount in other currency", amountRule, Discount{Amount: Money{Amount: 25000, Currency: "MYR"}, Percent: 5}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.rule.Exceeds(tt.discount); got != tt.want {
+				t.Errorf("Exceeds() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	percentRule.Deactivate()
+	if percentRule.Exceeds(Discount{Percent: 50}) {
+		t.Error("Exceeds() should be false for an inactive rule")
+	}
+}
+
+func TestNewDiscountApproval(t *testing.T) {
+	opp := createTestDiscountedOpportunity(t, 20)
+	requester := uuid.New()
+	manager := uuid.New()
+	rules := []*DiscountApprovalRule{
+		createTestDiscountRule(t, opp.TenantID, 10, requester, manager),
+		createTestDiscountRule(t, opp.TenantID, 30, uuid.New()),
+	}
+
+	approval, err := NewDiscountApproval(opp, rules, requester, " Repeat customer ")
+	if err != nil {
+		t.Fatalf("NewDiscountApproval() error = %v", err)
+	}
+	if approval.Status != DiscountApprovalStatusPending || approval.RequestComment != "Repeat customer" {
+		t.Errorf("NewDiscountApproval() = %+v, want a pending approval", approval)
+	}
+	if len(approval.RuleIDs) != 1 || approval.RuleIDs[0] != rules[0].ID {
+		t.Errorf("NewDiscountApproval() RuleIDs = %v, want only the exceeded rule", approval.RuleIDs)
+	}
+	if len(approval.ApproverIDs) != 1 || approval.ApproverIDs[0] != manager {
+		t.Errorf("NewDiscountApproval() ApproverIDs = %v, want the manager without the requester", approval.ApproverIDs)
+	}
+	if len(approval.GetEvents()) != 1 {
+		t.Errorf("NewDiscountApproval() should add DiscountApprovalRequestedEvent, got %d events", len(approval.GetEvents()))
+	}
+
+	if _, err := NewDiscountApproval(opp, rules[1:], requester, ""); err != ErrDiscountApprovalNotRequired {
+		t.Errorf("NewDiscountApproval() within rules error = %v, want %v", err, ErrDiscountApprovalNotRequired)
+	}
+	if _, err := NewDiscountApproval(opp, []*DiscountApprovalRule{createTestDiscountRule(t, opp.TenantID, 10, requester)}, requester, ""); err != ErrNoDiscountApprover {
+		t.Errorf("NewDiscountApproval() self-approval error = %v, want %v", err, ErrNoDiscountApprover)
+	}
+}
+
+func TestDiscountApproval_Decide(t *testing.T) {
+	opp := createTestDiscountedOpportunity(t, 20)
+	manager := uuid.New()
+	rules := []*DiscountApprovalRule{createTestDiscountRule(t, opp.TenantID, 10, manager)}
+
+	approval, _ := NewDiscountApproval(opp, rules, uuid.New(), "")
+	approval.ClearEvents()
+
+	if err := approval.Approve(uuid.New(), ""); err != ErrNotDiscountApprover {
+		t.Errorf("Approve() by other user error = %v, want %v", err, ErrNotDiscountApprover)
+	}
+	if err := approval.Reject(manager, " "); err != ErrDiscountRejectionCommentRequired {
+		t.Errorf("Reject() without comment error = %v, want %v", err, ErrDiscountRejectionCommentRequired)
+	}
+	if err := approval.Reject(manager, "Keep it under 15%"); err != nil {
+		t.Fatalf("Reject() error = %v", err)
+	}
+	if approval.Status != DiscountApprovalStatusRejected || approval.DecidedBy == nil || *approval.DecidedBy != manager {
+		t.Errorf("Reject() = %+v, want rejected by the manager", approval)
+	}
+	if len(approval.GetEvents()) != 1 {
+		t.Errorf("Reject() should add DiscountApprovalDecidedEvent, got %d events", len(approval.GetEvents()))
+	}
+	if err := approval.Approve(manager, ""); err != ErrDiscountApprovalAlreadyDecided {
+		t.Errorf("Approve() after rejection error = %v, want %v", err, ErrDiscountApprovalAlreadyDecided)
+	}
+}
+
+func TestCheckDiscountApproval(t *testing.T) {
+	opp := createTestDiscountedOpportunity(t, 20)
+	manager := uuid.New()
+	rules := []*DiscountApprovalRule{createTestDiscountRule(t, opp.TenantID, 10, manager)}
+
+	if err := CheckDiscountApproval(opp, rules, nil); err != ErrDiscountApprovalRequired {
+		t.Errorf("CheckDiscountApproval() without approval error = %v, want %v", err, ErrDiscountApprovalRequired)
+	}
+
+	approval, _ := NewDiscountApproval(opp, rules, uuid.New(), "")
+	if err := CheckDiscountApproval(opp, rules, approval); err != ErrDiscountApprovalRequired {
+		t.Errorf("CheckDiscountApproval() pending error = %v, want %v", err, ErrDiscountApprovalRequired)
+	}
+
+	if err := approval.Approve(manager, ""); err != nil {
+		t.Fatalf("Approve() error = %v", err)
+	}
+	if err := CheckDiscountApproval(opp, rules, approval); err != nil {
+		t.Errorf("CheckDiscountApproval() approved error = %v", err)
+	}
+
+	// A smaller discount stays covered; a larger one needs a new approval
+	opp.Products[0].Discount = 15
+	if err := CheckDiscountApproval(opp, rules, approval); err != nil {
+		t.Errorf("CheckDiscountApproval() smaller discount error = %v", err)
+	}
+	opp.Products[0].Discount = 25
+	if err := CheckDiscountApproval(opp, rules, approval); err != ErrDiscountApprovalRequired {
+		t.Errorf("CheckDiscountApproval() larger discount error = %v, want %v", err, ErrDiscountApprovalRequired)
+	}
+
+	opp.Products[0].Discount = 5
+	if err := CheckDiscountApproval(opp, rules, nil); err != nil {
+		t.Errorf("CheckDiscountApproval() within rules error = %v", err)
+	}
+}
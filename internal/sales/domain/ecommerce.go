@@ -0,0 +1,333 @@
+package domain
+
+import (
+	"errors"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// E-commerce errors
+var (
+	ErrEcommerceStoreNotFound         = errors.New("e-commerce store not found")
+	ErrEcommerceImportNotFound        = errors.New("e-commerce import not found")
+	ErrEcommerceImportAlreadyExists   = errors.New("e-commerce record is already imported")
+	ErrInvalidEcommercePlatform       = errors.New("invalid e-commerce platform")
+	ErrInvalidEcommerceStoreURL       = errors.New("store URL must be an absolute https URL")
+	ErrEcommerceStoreNameRequired     = errors.New("store name is required")
+	ErrInvalidEcommerceImportKind     = errors.New("invalid e-commerce import kind")
+	ErrInvalidEcommerceImportResolved = errors.New("e-commerce import is already resolved")
+)
+
+const (
+	// EcommerceSyncOverlap is how far before the last synced order update a
+	// poll starts, so orders updated while the previous poll ran are not
+	// missed. Orders already imported are skipped.
+	EcommerceSyncOverlap = 10 * time.Minute
+
+	// EcommerceInitialSyncWindow is how far back the first poll of a store
+	// imports orders.
+	EcommerceInitialSyncWindow = 30 * 24 * time.Hour
+)
+
+// EcommercePlatform is the e-commerce platform an online store runs on.
+type EcommercePlatform string
+
+const (
+	EcommercePlatformShopify     EcommercePlatform = "shopify"
+	EcommercePlatformWooCommerce EcommercePlatform = "woocommerce"
+)
+
+// IsValid checks if the e-commerce platform is valid.
+func (p EcommercePlatform) IsValid() bool {
+	return p == EcommercePlatformShopify || p == EcommercePlatformWooCommerce
+}
+
+// EcommerceStore is an online store whose orders are imported as won deals
+// and whose abandoned carts are imported as leads. Orders are polled from
+// the store's API with the API credentials and pushed by the store's
+// webhooks, which are verified with WebhookSecret.
+type EcommerceStore struct {
+	ID       uuid.UUID         `json:"id"`
+	TenantID uuid.UUID         `json:"tenant_id"`
+	Platform EcommercePlatform `json:"platform"`
+	Name     string            `json:"name"`
+	StoreURL string            `json:"store_url"`
+	// APIKey is the Shopify Admin API access token or the WooCommerce
+	// consumer key; APISecret is the WooCommerce consumer secret.
+	APIKey    string `json:"-"`
+	APISecret string `json:"-"`
+	// WebhookSecret verifies webhooks: the Shopify app's client secret or
+	// the secret of the WooCommerce webhooks.
+	WebhookSecret string `json:"-"`
+
+	Enabled bool `json:"enabled"`
+	// ImportAbandonedCarts creates leads for checkouts left without an order.
+	ImportAbandonedCarts bool `json:"import_abandoned_carts"`
+	// PipelineID is the pipeline imported deals are won in; the tenant's
+	// default pipeline is used when it is not set.
+	PipelineID *uuid.UUID `json:"pipeline_id,omitempty"`
+	// OwnerID owns the imported deals and leads.
+	OwnerID uuid.UUID `json:"owner_id"`
+
+	// SyncedThrough is the latest order update imported by polling.
+	SyncedThrough *time.Time `json:"synced_through,omitempty"`
+	LastSyncAt    *time.Time `json:"last_sync_at,omitempty"`
+	LastSyncError string     `json:"last_sync_error,omitempty"`
+	// LastWebhookAt is when the store last pushed a webhook.
+	LastWebhookAt *time.Time `json:"last_webhook_at,omitempty"`
+
+	CreatedBy uuid.UUID `json:"created_by"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+	Version   int       `json:"version"`
+}
+
+// NewEcommerceStore creates an enabled store connector.
+func NewEcommerceStore(tenantID uuid.UUID, platform EcommercePlatform, name, storeURL string, ownerID, createdBy uuid.UUID) (*EcommerceStore, error) {
+	if !platform.IsValid() {
+		return nil, ErrInvalidEcommercePlatform
+	}
+
+	now := time.Now().UTC()
+	store := &EcommerceStore{
+		ID:        uuid.New(),
+		TenantID:  tenantID,
+		Platform:  platform,
+		Enabled:   true,
+		OwnerID:   ownerID,
+		CreatedBy: createdBy,
+		CreatedAt: now,
+		UpdatedAt: now,
+		Version:   1,
+	}
+	if err := store.Rename(name); err != nil {
+		return nil, err
+	}
+	if err := store.SetStoreURL(storeURL); err != nil {
+		return nil, err
+	}
+	return store, nil
+}
+
+// Rename changes the store's display name.
+func (s *EcommerceStore) Rename(name string) error {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return ErrEcommerceStoreNameRequired
+	}
+	s.Name = name
+	s.UpdatedAt = time.Now().UTC()
+	return nil
+}
+
+// SetStoreURL changes the store's base URL, such as
+// https://batik.myshopify.com or https://shop.example.com.
+func (s *EcommerceStore) SetStoreURL(storeURL string) error {
+	storeURL = strings.TrimRight(strings.TrimSpace(storeURL), "/")
+	u, err := url.Parse(storeURL)
+	if err != nil || u.Scheme != "https" || u.Host == "" {
+		return ErrInvalidEcommerceStoreURL
+	}
+	s.StoreURL = storeURL
+	s.UpdatedAt = time.Now().UTC()
+	return nil
+}
+
+// SetCredentials replaces the API credentials and webhook secret. Empty
+// values keep the current ones.
+func (s *EcommerceStore) SetCredentials(apiKey, apiSecret, webhookSecret string) {
+	if apiKey = strings.TrimSpace(apiKey); apiKey != "" {
+		s.APIKey = apiKey
+	}
+	if apiSecret = strings.TrimSpace(apiSecret); apiSecret != "" {
+		s.APISecret = apiSecret
+	}
+	if webhookSecret = strings.TrimSpace(webhookSecret); webhookSecret != "" {
+		s.WebhookSecret = webhookSecret
+	}
+	s.UpdatedAt = time.Now().UTC()
+}
+
+// CanPoll reports whether orders can be polled from the store's API.
+func (s *EcommerceStore) CanPoll() bool {
+	if !s.Enabled || s.APIKey == "" {
+		return false
+	}
+	return s.Platform != EcommercePlatformWooCommerce || s.APISecret != ""
+}
+
+// SyncFrom returns the time a poll imports order updates from.
+func (s *EcommerceStore) SyncFrom(now time.Time) time.Time {
+	if s.SyncedThrough == nil {
+		return now.Add(-EcommerceInitialSyncWindow)
+	}
+	return s.SyncedThrough.Add(-EcommerceSyncOverlap)
+}
+
+// RecordSync records a poll, advancing SyncedThrough to the latest order
+// update it saw. A failed poll keeps SyncedThrough so it is polled again.
+func (s *EcommerceStore) RecordSync(at time.Time, latest *time.Time, syncErr error) {
+	at = at.UTC()
+	s.LastSyncAt = &at
+	s.LastSyncError = ""
+	if syncErr != nil {
+		s.LastSyncError = syncErr.Error()
+	}
+	if latest != nil && (s.SyncedThrough == nil || latest.After(*s.SyncedThrough)) {
+		through := latest.UTC()
+		s.SyncedThrough = &through
+	}
+	s.UpdatedAt = at
+}
+
+// RecordWebhook records that the store pushed a webhook.
+func (s *EcommerceStore) RecordWebhook(at time.Time) {
+	at = at.UTC()
+	s.LastWebhookAt = &at
+	s.UpdatedAt = at
+}
+
+// EcommerceImportKind is the kind of store record imported.
+type EcommerceImportKind string
+
+const (
+	// EcommerceImportOrder is an order, imported as a won deal.
+	EcommerceImportOrder EcommerceImportKind = "order"
+	// EcommerceImportAbandonedCart is a checkout left without an order,
+	// imported as a lead.
+	EcommerceImportAbandonedCart EcommerceImportKind = "abandoned_cart"
+)
+
+// IsValid checks if the import kind is valid.
+func (k EcommerceImportKind) IsValid() bool {
+	return k == EcommerceImportOrder || k == EcommerceImportAbandonedCart
+}
+
+// EcommerceImportStatus represents the outcome of importing a store record.
+type EcommerceImportStatus string
+
+const (
+	// EcommerceImportStatusPending is a record being imported.
+	EcommerceImportStatusPending  EcommerceImportStatus = "pending"
+	EcommerceImportStatusImported EcommerceImportStatus = "imported"
+	// EcommerceImportStatusSkipped is a record that was not imported, such
+	// as a cancelled order or a cart whose shopper is already a lead.
+	EcommerceImportStatusSkipped EcommerceImportStatus = "skipped"
+	// EcommerceImportStatusFailed is a record that could not be imported. It
+	// is imported again when the store next sends it.
+	EcommerceImportStatusFailed EcommerceImportStatus = "failed"
+)
+
+// IsValid checks if the import status is valid.
+func (s EcommerceImportStatus) IsValid() bool {
+	switch s {
+	case EcommerceImportStatusPending, EcommerceImportStatusImported, EcommerceImportStatusSkipped, EcommerceImportStatusFailed:
+		return true
+	}
+	return false
+}
+
+// EcommerceImport records the import of a store order or abandoned cart, so
+// each is imported once however often the store sends it.
+type EcommerceImport struct {
+	ID       uuid.UUID           `json:"id"`
+	TenantID uuid.UUID           `json:"tenant_id"`
+	StoreID  uuid.UUID           `json:"store_id"`
+	Kind     EcommerceImportKind `json:"kind"`
+	// ExternalID is the order or checkout ID in the store.
+	ExternalID string `json:"external_id"`
+	// Reference is the order number or checkout token shown to users.
+	Reference string `json:"reference"`
+	Email     string `json:"email,omitempty"`
+	Amount    Money  `json:"amount"`
+
+	Status     EcommerceImportStatus `json:"status"`
+	CustomerID *uuid.UUID            `json:"customer_id,omitempty"`
+	DealID     *uuid.UUID            `json:"deal_id,omitempty"`
+	LeadID     *uuid.UUID            `json:"lead_id,omitempty"`
+	Reason     string                `json:"reason,omitempty"`
+	Attempts   int                   `json:"attempts"`
+	CreatedAt  time.Time             `json:"created_at"`
+	UpdatedAt  time.Time             `json:"updated_at"`
+	Version    int                   `json:"version"`
+}
+
+// NewEcommerceImport creates a pending import of a store record.
+func NewEcommerceImport(store *EcommerceStore, kind EcommerceImportKind, externalID, reference, email string, amount Money) (*EcommerceImport, error) {
+	if !kind.IsValid() {
+		return nil, ErrInvalidEcommerceImportKind
+	}
+
+	now := time.Now().UTC()
+	return &EcommerceImport{
+		ID:         uuid.New(),
+		TenantID:   store.TenantID,
+		StoreID:    store.ID,
+		Kind:       kind,
+		ExternalID: externalID,
+		Reference:  reference,
+		Email:      strings.ToLower(strings.TrimSpace(email)),
+		Amount:     amount,
+		Status:     EcommerceImportStatusPending,
+		Attempts:   1,
+		CreatedAt:  now,
+		UpdatedAt:  now,
+		Version:    1,
+	}, nil
+}
+
+// IsResolved reports whether the record was imported or skipped, so it is
+// not imported again.
+func (i *EcommerceImport) IsResolved() bool {
+	return i.Status == EcommerceImportStatusImported || i.Status == EcommerceImportStatusSkipped
+}
+
+// Retry starts another attempt at a failed import.
+func (i *EcommerceImport) Retry() error {
+	if i.IsResolved() {
+		return ErrInvalidEcommerceImportResolved
+	}
+	i.Status = EcommerceImportStatusPending
+	i.Attempts++
+	i.Reason = ""
+	i.UpdatedAt = time.Now().UTC()
+	return nil
+}
+
+// MarkImported records the deal or lead the record was imported as.
+func (i *EcommerceImport) MarkImported(customerID, dealID, leadID *uuid.UUID) {
+	i.Status = EcommerceImportStatusImported
+	i.CustomerID = customerID
+	i.DealID = dealID
+	i.LeadID = leadID
+	i.Reason = ""
+	i.UpdatedAt = time.Now().UTC()
+}
+
+// MarkSkipped records why the record was not imported, linking the existing
+// lead it matched, if any.
+func (i *EcommerceImport) MarkSkipped(reason string, leadID *uuid.UUID) {
+	i.Status = EcommerceImportStatusSkipped
+	i.LeadID = leadID
+	i.Reason = reason
+	i.UpdatedAt = time.Now().UTC()
+}
+
+// MarkFailed records why the record could not be imported.
+func (i *EcommerceImport) MarkFailed(reason string) {
+	i.Status = EcommerceImportStatusFailed
+	i.Reason = reason
+	i.UpdatedAt = time.Now().UTC()
+}
+
+// EcommerceImportSummary counts a store's imports for its sync status.
+type EcommerceImportSummary struct {
+	OrdersImported int        `json:"orders_imported"`
+	LeadsCreated   int        `json:"leads_created"`
+	Skipped        int        `json:"skipped"`
+	Failed         int        `json:"failed"`
+	LastImportedAt *time.Time `json:"last_imported_at,omitempty"`
+}
@@ -0,0 +1,136 @@
+package domain
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func createTestEcommerceStore(t *testing.T, platform EcommercePlatform) *EcommerceStore {
+	t.Helper()
+
+	store, err := NewEcommerceStore(uuid.New(), platform, "Batik Online", "https://batik.myshopify.com", uuid.New(), uuid.New())
+	if err != nil {
+		t.Fatalf("NewEcommerceStore() error = %v", err)
+	}
+	return store
+}
+
+func TestNewEcommerceStore(t *testing.T) {
+	store, err := NewEcommerceStore(uuid.New(), EcommercePlatformWooCommerce, " Kedai Batik ", "https://shop.example.com/ ", uuid.New(), uuid.New())
+	if err != nil {
+		t.Fatalf("NewEcommerceStore() error = %v", err)
+	}
+	if !store.Enabled || store.Name != "Kedai Batik" || store.StoreURL != "https://shop.example.com" {
+		t.Errorf("store = %+v, want an enabled store with the trimmed name and URL", store)
+	}
+
+	if _, err := NewEcommerceStore(uuid.New(), "magento", "Shop", "https://shop.example.com", uuid.New(), uuid.New()); !errors.Is(err, ErrInvalidEcommercePlatform) {
+		t.Errorf("NewEcommerceStore() with unknown platform error = %v, want %v", err, ErrInvalidEcommercePlatform)
+	}
+	if _, err := NewEcommerceStore(uuid.New(), EcommercePlatformShopify, "Shop", "http://shop.example.com", uuid.New(), uuid.New()); !errors.Is(err, ErrInvalidEcommerceStoreURL) {
+		t.Errorf("NewEcommerceStore() with http URL error = %v, want %v", err, ErrInvalidEcommerceStoreURL)
+	}
+	if _, err := NewEcommerceStore(uuid.New(), EcommercePlatformShopify, " ", "https://shop.example.com", uuid.New(), uuid.New()); !errors.Is(err, ErrEcommerceStoreNameRequired) {
+		t.Errorf("NewEcommerceStore() without name error = %v, want %v", err, ErrEcommerceStoreNameRequired)
+	}
+}
+
+func TestEcommerceStore_CanPoll(t *testing.T) {
+	shopify := createTestEcommerceStore(t, EcommercePlatformShopify)
+	if shopify.CanPoll() {
+		t.Error("CanPoll() without credentials = true, want false")
+	}
+	shopify.SetCredentials("shpat_token", "", "")
+	if !shopify.CanPoll() {
+		t.Error("CanPoll() for Shopify with an access token = false, want true")
+	}
+
+	// Empty credentials keep the current ones
+	shopify.SetCredentials("", "", "whsec")
+	if shopify.APIKey != "shpat_token" || shopify.WebhookSecret != "whsec" {
+		t.Errorf("APIKey, WebhookSecret = %q, %q, want the token kept and the secret set", shopify.APIKey, shopify.WebhookSecret)
+	}
+	shopify.Enabled = false
+	if shopify.CanPoll() {
+		t.Error("CanPoll() for a disabled store = true, want false")
+	}
+
+	woo := createTestEcommerceStore(t, EcommercePlatformWooCommerce)
+	woo.SetCredentials("ck_key", "", "")
+	if woo.CanPoll() {
+		t.Error("CanPoll() for WooCommerce without a consumer secret = true, want false")
+	}
+	woo.SetCredentials("", "cs_secret", "")
+	if !woo.CanPoll() {
+		t.Error("CanPoll() for WooCommerce with key and secret = false, want true")
+	}
+}
+
+func TestEcommerceStore_RecordSync(t *testing.T) {
+	store := createTestEcommerceStore(t, EcommercePlatformShopify)
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+
+	if got := store.SyncFrom(now); !got.Equal(now.Add(-EcommerceInitialSyncWindow)) {
+		t.Errorf("SyncFrom() before the first sync = %v, want the initial window", got)
+	}
+
+	latest := now.Add(-time.Hour)
+	store.RecordSync(now, &latest, nil)
+	if store.SyncedThrough == nil || !store.SyncedThrough.Equal(latest) {
+		t.Fatalf("SyncedThrough = %v, want %v", store.SyncedThrough, latest)
+	}
+	if got := store.SyncFrom(now); !got.Equal(latest.Add(-EcommerceSyncOverlap)) {
+		t.Errorf("SyncFrom() = %v, want the overlap before the last synced update", got)
+	}
+
+	// A failed poll keeps its position and reports the error
+	older := latest.Add(-time.Hour)
+	store.RecordSync(now.Add(time.Minute), &older, errors.New("unexpected status 503"))
+	if !store.SyncedThrough.Equal(latest) || store.LastSyncError != "unexpected status 503" {
+		t.Errorf("SyncedThrough, LastSyncError = %v, %q, want the position kept and the error recorded", store.SyncedThrough, store.LastSyncError)
+	}
+
+	store.RecordSync(now.Add(2*time.Minute), nil, nil)
+	if store.LastSyncError != "" {
+		t.Errorf("LastSyncError = %q, want it cleared by a successful poll", store.LastSyncError)
+	}
+}
+
+func TestEcommerceImport_Lifecycle(t *testing.T) {
+	store := createTestEcommerceStore(t, EcommercePlatformShopify)
+
+	if _, err := NewEcommerceImport(store, "refund", "1", "#1001", "", Money{}); !errors.Is(err, ErrInvalidEcommerceImportKind) {
+		t.Errorf("NewEcommerceImport() with unknown kind error = %v, want %v", err, ErrInvalidEcommerceImportKind)
+	}
+
+	record, err := NewEcommerceImport(store, EcommerceImportOrder, "450789469", "#1001", " Aminah@Example.com ", Money{Amount: 19900, Currency: "MYR"})
+	if err != nil {
+		t.Fatalf("NewEcommerceImport() error = %v", err)
+	}
+	if record.Status != EcommerceImportStatusPending || record.Attempts != 1 || record.Email != "aminah@example.com" {
+		t.Errorf("import = %+v, want a pending first attempt with the normalized email", record)
+	}
+
+	record.MarkFailed("the tenant has no default pipeline")
+	if record.IsResolved() {
+		t.Error("IsResolved() after failing = true, want false")
+	}
+	if err := record.Retry(); err != nil {
+		t.Fatalf("Retry() error = %v", err)
+	}
+	if record.Status != EcommerceImportStatusPending || record.Attempts != 2 || record.Reason != "" {
+		t.Errorf("import = %+v, want a pending second attempt", record)
+	}
+
+	dealID := uuid.New()
+	record.MarkImported(nil, &dealID, nil)
+	if !record.IsResolved() || *record.DealID != dealID {
+		t.Errorf("import = %+v, want it resolved with the deal", record)
+	}
+	if err := record.Retry(); !errors.Is(err, ErrInvalidEcommerceImportResolved) {
+		t.Errorf("Retry() after importing error = %v, want %v", err, ErrInvalidEcommerceImportResolved)
+	}
+}
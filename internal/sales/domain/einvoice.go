@@ -0,0 +1,482 @@
+package domain
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// E-invoice errors
+var (
+	ErrEInvoiceNotFound                 = errors.New("e-invoice not found")
+	ErrEInvoiceAlreadySubmitted         = errors.New("invoice already has an e-invoice")
+	ErrEInvoiceInvoiceNotIssued         = errors.New("only issued invoices can be e-invoiced")
+	ErrEInvoiceInvalidStatusTransition  = errors.New("invalid e-invoice status transition")
+	ErrEInvoiceCancellationWindowClosed = errors.New("e-invoices can only be cancelled within 72 hours of validation")
+	ErrEInvoiceCancelReasonRequired     = errors.New("e-invoice cancellation reason is required")
+	ErrEInvoiceTINRequired              = errors.New("tax identification number (TIN) is required")
+	ErrEInvoiceRegistrationRequired     = errors.New("business registration or identification number is required")
+	ErrEInvoiceNameRequired             = errors.New("party name is required")
+	ErrEInvoiceAddressRequired          = errors.New("address line, city, state and country are required")
+	ErrEInvoiceInvalidMSICCode          = errors.New("MSIC code must be 5 digits")
+	ErrEInvoiceActivityRequired         = errors.New("business activity description is required")
+	ErrEInvoicePhoneRequired            = errors.New("contact number is required")
+)
+
+const (
+	// EInvoiceGeneralPublicTIN is the TIN LHDN assigns to buyers without
+	// one, such as individual consumers.
+	EInvoiceGeneralPublicTIN = "EI00000000010"
+
+	// EInvoiceCancellationWindow is how long after validation the supplier
+	// may cancel an e-invoice.
+	EInvoiceCancellationWindow = 72 * time.Hour
+
+	// EInvoiceValidationInterval is how long to wait between checks of a
+	// submitted e-invoice's validation result.
+	EInvoiceValidationInterval = time.Minute
+
+	// MaxEInvoiceAttempts is how many times a submission is tried before
+	// the e-invoice fails and must be resubmitted.
+	MaxEInvoiceAttempts = 5
+)
+
+// eInvoiceRetryBackoff is the wait before each retry of a failed submission.
+var eInvoiceRetryBackoff = []time.Duration{
+	time.Minute,
+	5 * time.Minute,
+	30 * time.Minute,
+	2 * time.Hour,
+}
+
+var msicCodePattern = regexp.MustCompile(`^\d{5}$`)
+
+// EInvoiceStatus represents where an e-invoice is in its submission to the
+// MyInvois system.
+type EInvoiceStatus string
+
+const (
+	// EInvoiceStatusPending is an e-invoice waiting to be submitted, including
+	// after a submission that could not reach MyInvois.
+	EInvoiceStatusPending EInvoiceStatus = "pending"
+	// EInvoiceStatusSubmitted is an e-invoice MyInvois accepted and is validating.
+	EInvoiceStatusSubmitted EInvoiceStatus = "submitted"
+	EInvoiceStatusValid     EInvoiceStatus = "valid"
+	// EInvoiceStatusInvalid is an e-invoice MyInvois rejected. It can be
+	// resubmitted once its details are corrected.
+	EInvoiceStatusInvalid EInvoiceStatus = "invalid"
+	// EInvoiceStatusFailed is an e-invoice that could not be submitted after
+	// MaxEInvoiceAttempts tries. It can be resubmitted.
+	EInvoiceStatusFailed    EInvoiceStatus = "failed"
+	EInvoiceStatusCancelled EInvoiceStatus = "cancelled"
+)
+
+// IsValid checks if the e-invoice status is valid.
+func (s EInvoiceStatus) IsValid() bool {
+	switch s {
+	case EInvoiceStatusPending, EInvoiceStatusSubmitted, EInvoiceStatusValid,
+		EInvoiceStatusInvalid, EInvoiceStatusFailed, EInvoiceStatusCancelled:
+		return true
+	}
+	return false
+}
+
+// CanResubmit returns true if the e-invoice may be submitted again.
+func (s EInvoiceStatus) CanResubmit() bool {
+	return s == EInvoiceStatusInvalid || s == EInvoiceStatusFailed
+}
+
+// EInvoiceAddress is a party's address as MyInvois expects it.
+type EInvoiceAddress struct {
+	Line1    string `json:"line1"`
+	Line2    string `json:"line2,omitempty"`
+	City     string `json:"city"`
+	Postcode string `json:"postcode,omitempty"`
+	// State is the MyInvois state code, e.g. "03" for Kelantan or "14" for
+	// Wilayah Persekutuan Kuala Lumpur.
+	State string `json:"state"`
+	// Country is the ISO 3166-1 alpha-3 country code, e.g. "MYS".
+	Country string `json:"country"`
+}
+
+// Normalize trims the address and upper-cases its country code.
+func (a *EInvoiceAddress) Normalize() {
+	a.Line1 = strings.TrimSpace(a.Line1)
+	a.Line2 = strings.TrimSpace(a.Line2)
+	a.City = strings.TrimSpace(a.City)
+	a.Postcode = strings.TrimSpace(a.Postcode)
+	a.State = strings.TrimSpace(a.State)
+	a.Country = strings.ToUpper(strings.TrimSpace(a.Country))
+}
+
+// Validate checks the address has the parts MyInvois requires.
+func (a EInvoiceAddress) Validate() error {
+	if a.Line1 == "" || a.City == "" || a.State == "" || a.Country == "" {
+		return ErrEInvoiceAddressRequired
+	}
+	return nil
+}
+
+// EInvoiceParty is the buyer of an e-invoice.
+type EInvoiceParty struct {
+	Name string `json:"name"`
+	TIN  string `json:"tin"`
+	// RegistrationNumber is the business registration number, or the NRIC or
+	// passport number of an individual.
+	RegistrationNumber string          `json:"registration_number"`
+	SSTNumber          string          `json:"sst_number,omitempty"`
+	Email              string          `json:"email,omitempty"`
+	Phone              string          `json:"phone,omitempty"`
+	Address            EInvoiceAddress `json:"address"`
+}
+
+// Normalize trims the party's details.
+func (p *EInvoiceParty) Normalize() {
+	p.Name = strings.TrimSpace(p.Name)
+	p.TIN = strings.ToUpper(strings.TrimSpace(p.TIN))
+	p.RegistrationNumber = strings.TrimSpace(p.RegistrationNumber)
+	p.SSTNumber = strings.TrimSpace(p.SSTNumber)
+	p.Email = strings.ToLower(strings.TrimSpace(p.Email))
+	p.Phone = strings.TrimSpace(p.Phone)
+	p.Address.Normalize()
+}
+
+// Validate checks the party has the details MyInvois requires of a buyer.
+func (p EInvoiceParty) Validate() error {
+	if p.Name == "" {
+		return ErrEInvoiceNameRequired
+	}
+	if p.TIN == "" {
+		return ErrEInvoiceTINRequired
+	}
+	if p.RegistrationNumber == "" {
+		return ErrEInvoiceRegistrationRequired
+	}
+	return p.Address.Validate()
+}
+
+// GeneralPublicBuyer returns the buyer of an e-invoice issued to a customer
+// without a TIN, using the placeholders LHDN prescribes for the general public.
+func GeneralPublicBuyer(name string) EInvoiceParty {
+	if strings.TrimSpace(name) == "" {
+		name = "General Public"
+	}
+	return EInvoiceParty{
+		Name:               name,
+		TIN:                EInvoiceGeneralPublicTIN,
+		RegistrationNumber: "NA",
+		Address: EInvoiceAddress{
+			Line1:   "NA",
+			City:    "NA",
+			State:   "17", // Not applicable
+			Country: "MYS",
+		},
+	}
+}
+
+// EInvoiceSettings are the supplier details a tenant submits e-invoices with.
+type EInvoiceSettings struct {
+	TenantID           uuid.UUID `json:"tenant_id"`
+	Enabled            bool      `json:"enabled"`
+	Name               string    `json:"name"`
+	TIN                string    `json:"tin"`
+	RegistrationNumber string    `json:"registration_number"`
+	SSTNumber          string    `json:"sst_number,omitempty"`
+	// MSICCode is the Malaysia Standard Industrial Classification code of the
+	// tenant's business activity.
+	MSICCode         string          `json:"msic_code"`
+	BusinessActivity string          `json:"business_activity"`
+	Email            string          `json:"email,omitempty"`
+	Phone            string          `json:"phone"`
+	Address          EInvoiceAddress `json:"address"`
+	UpdatedAt        time.Time       `json:"updated_at"`
+}
+
+// DefaultEInvoiceSettings returns the settings used for tenants without a
+// configuration. E-invoicing is off until the tenant records its details.
+func DefaultEInvoiceSettings(tenantID uuid.UUID) *EInvoiceSettings {
+	return &EInvoiceSettings{
+		TenantID: tenantID,
+		Address:  EInvoiceAddress{Country: "MYS"},
+	}
+}
+
+// Normalize trims the settings.
+func (s *EInvoiceSettings) Normalize() {
+	s.Name = strings.TrimSpace(s.Name)
+	s.TIN = strings.ToUpper(strings.TrimSpace(s.TIN))
+	s.RegistrationNumber = strings.TrimSpace(s.RegistrationNumber)
+	s.SSTNumber = strings.TrimSpace(s.SSTNumber)
+	s.MSICCode = strings.TrimSpace(s.MSICCode)
+	s.BusinessActivity = strings.TrimSpace(s.BusinessActivity)
+	s.Email = strings.ToLower(strings.TrimSpace(s.Email))
+	s.Phone = strings.TrimSpace(s.Phone)
+	s.Address.Normalize()
+}
+
+// Validate validates the settings. The supplier details are only required
+// once e-invoicing is enabled.
+func (s *EInvoiceSettings) Validate() error {
+	if !s.Enabled {
+		return nil
+	}
+	if s.Name == "" {
+		return ErrEInvoiceNameRequired
+	}
+	if s.TIN == "" {
+		return ErrEInvoiceTINRequired
+	}
+	if s.RegistrationNumber == "" {
+		return ErrEInvoiceRegistrationRequired
+	}
+	if !msicCodePattern.MatchString(s.MSICCode) {
+		return ErrEInvoiceInvalidMSICCode
+	}
+	if s.BusinessActivity == "" {
+		return ErrEInvoiceActivityRequired
+	}
+	if s.Phone == "" {
+		return ErrEInvoicePhoneRequired
+	}
+	return s.Address.Validate()
+}
+
+// EInvoice is the submission of a deal invoice to LHDN's MyInvois
+// e-invoicing system and its validation result.
+type EInvoice struct {
+	ID            uuid.UUID      `json:"id"`
+	TenantID      uuid.UUID      `json:"tenant_id"`
+	DealID        uuid.UUID      `json:"deal_id"`
+	InvoiceID     uuid.UUID      `json:"invoice_id"`
+	InvoiceNumber string         `json:"invoice_number"`
+	CustomerID    uuid.UUID      `json:"customer_id"`
+	Buyer         EInvoiceParty  `json:"buyer"`
+	Status        EInvoiceStatus `json:"status"`
+
+	// Identifiers MyInvois assigns
+	SubmissionUID string `json:"submission_uid,omitempty"`
+	DocumentUUID  string `json:"document_uuid,omitempty"`
+	LongID        string `json:"long_id,omitempty"`
+	// ValidationURL is the public validation link the invoice's QR code encodes.
+	ValidationURL    string   `json:"validation_url,omitempty"`
+	ValidationErrors []string `json:"validation_errors"`
+
+	Attempts      int        `json:"attempts"`
+	NextAttemptAt *time.Time `json:"next_attempt_at,omitempty"`
+	LastError     string     `json:"last_error,omitempty"`
+
+	SubmittedAt  *time.Time `json:"submitted_at,omitempty"`
+	ValidatedAt  *time.Time `json:"validated_at,omitempty"`
+	CancelledAt  *time.Time `json:"cancelled_at,omitempty"`
+	CancelReason string     `json:"cancel_reason,omitempty"`
+	CreatedBy    uuid.UUID  `json:"created_by"`
+	CreatedAt    time.Time  `json:"created_at"`
+	UpdatedAt    time.Time  `json:"updated_at"`
+	Version      int        `json:"version"`
+
+	// Domain events
+	events []DomainEvent `json:"-"`
+}
+
+// NewEInvoice creates a pending e-invoice of an issued deal invoice.
+func NewEInvoice(deal *Deal, invoice *Invoice, buyer EInvoiceParty, createdBy uuid.UUID) (*EInvoice, error) {
+	if invoice.Status == "draft" || invoice.Status == "cancelled" {
+		return nil, ErrEInvoiceInvoiceNotIssued
+	}
+	buyer.Normalize()
+	if err := buyer.Validate(); err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	einvoice := &EInvoice{
+		ID:               uuid.New(),
+		TenantID:         deal.TenantID,
+		DealID:           deal.ID,
+		InvoiceID:        invoice.ID,
+		InvoiceNumber:    invoice.InvoiceNumber,
+		CustomerID:       deal.CustomerID,
+		Buyer:            buyer,
+		Status:           EInvoiceStatusPending,
+		ValidationErrors: []string{},
+		NextAttemptAt:    &now,
+		CreatedBy:        createdBy,
+		CreatedAt:        now,
+		UpdatedAt:        now,
+		Version:          1,
+		events:           make([]DomainEvent, 0),
+	}
+
+	einvoice.AddEvent(NewEInvoiceCreatedEvent(einvoice))
+	return einvoice, nil
+}
+
+// MarkSubmitted records that MyInvois accepted the e-invoice for validation.
+func (e *EInvoice) MarkSubmitted(submissionUID, documentUUID string, at time.Time) error {
+	if e.Status != EInvoiceStatusPending {
+		return ErrEInvoiceInvalidStatusTransition
+	}
+
+	at = at.UTC()
+	next := at.Add(EInvoiceValidationInterval)
+	e.Status = EInvoiceStatusSubmitted
+	e.SubmissionUID = submissionUID
+	e.DocumentUUID = documentUUID
+	e.SubmittedAt = &at
+	e.NextAttemptAt = &next
+	e.LastError = ""
+	e.UpdatedAt = at
+	return nil
+}
+
+// ScheduleCheck schedules the next check of a submitted e-invoice's
+// validation result, after MyInvois is still validating it or could not be
+// reached.
+func (e *EInvoice) ScheduleCheck(reason string, at time.Time) {
+	if e.Status != EInvoiceStatusSubmitted {
+		return
+	}
+	at = at.UTC()
+	next := at.Add(EInvoiceValidationInterval)
+	e.NextAttemptAt = &next
+	e.LastError = reason
+	e.UpdatedAt = at
+}
+
+// MarkValid records that MyInvois validated the e-invoice.
+func (e *EInvoice) MarkValid(longID, validationURL string, at time.Time) error {
+	if e.Status != EInvoiceStatusSubmitted {
+		return ErrEInvoiceInvalidStatusTransition
+	}
+
+	at = at.UTC()
+	e.Status = EInvoiceStatusValid
+	e.LongID = longID
+	e.ValidationURL = validationURL
+	e.ValidationErrors = []string{}
+	e.ValidatedAt = &at
+	e.NextAttemptAt = nil
+	e.LastError = ""
+	e.UpdatedAt = at
+
+	e.AddEvent(NewEInvoiceValidatedEvent(e))
+	return nil
+}
+
+// MarkInvalid records that MyInvois rejected the e-invoice, either when it
+// was submitted or once validated.
+func (e *EInvoice) MarkInvalid(validationErrors []string, at time.Time) error {
+	if e.Status != EInvoiceStatusPending && e.Status != EInvoiceStatusSubmitted {
+		return ErrEInvoiceInvalidStatusTransition
+	}
+
+	at = at.UTC()
+	e.Status = EInvoiceStatusInvalid
+	e.ValidationErrors = append([]string{}, validationErrors...)
+	e.NextAttemptAt = nil
+	e.LastError = ""
+	e.UpdatedAt = at
+
+	e.AddEvent(NewEInvoiceRejectedEvent(e))
+	return nil
+}
+
+// MarkFailed records a submission that could not reach MyInvois and
+// schedules a retry, failing the e-invoice after MaxEInvoiceAttempts tries.
+func (e *EInvoice) MarkFailed(reason string, at time.Time) error {
+	if e.Status != EInvoiceStatusPending {
+		return ErrEInvoiceInvalidStatusTransition
+	}
+
+	at = at.UTC()
+	e.Attempts++
+	e.LastError = reason
+	e.UpdatedAt = at
+
+	if e.Attempts >= MaxEInvoiceAttempts {
+		e.Status = EInvoiceStatusFailed
+		e.NextAttemptAt = nil
+		e.AddEvent(NewEInvoiceRejectedEvent(e))
+		return nil
+	}
+
+	next := at.Add(eInvoiceRetryBackoff[e.Attempts-1])
+	e.NextAttemptAt = &next
+	return nil
+}
+
+// Resubmit queues a rejected or failed e-invoice for submission again,
+// optionally with corrected buyer details.
+func (e *EInvoice) Resubmit(buyer *EInvoiceParty, at time.Time) error {
+	if !e.Status.CanResubmit() {
+		return ErrEInvoiceInvalidStatusTransition
+	}
+	if buyer != nil {
+		buyer.Normalize()
+		if err := buyer.Validate(); err != nil {
+			return err
+		}
+		e.Buyer = *buyer
+	}
+
+	at = at.UTC()
+	e.Status = EInvoiceStatusPending
+	e.SubmissionUID = ""
+	e.DocumentUUID = ""
+	e.ValidationErrors = []string{}
+	e.Attempts = 0
+	e.NextAttemptAt = &at
+	e.LastError = ""
+	e.UpdatedAt = at
+	return nil
+}
+
+// CheckCancellable returns an error unless the e-invoice is valid and still
+// within the cancellation window.
+func (e *EInvoice) CheckCancellable(at time.Time) error {
+	if e.Status != EInvoiceStatusValid || e.ValidatedAt == nil {
+		return ErrEInvoiceInvalidStatusTransition
+	}
+	if at.Sub(*e.ValidatedAt) > EInvoiceCancellationWindow {
+		return ErrEInvoiceCancellationWindowClosed
+	}
+	return nil
+}
+
+// Cancel records the cancellation of a valid e-invoice.
+func (e *EInvoice) Cancel(reason string, at time.Time) error {
+	reason = strings.TrimSpace(reason)
+	if reason == "" {
+		return ErrEInvoiceCancelReasonRequired
+	}
+	if err := e.CheckCancellable(at); err != nil {
+		return err
+	}
+
+	at = at.UTC()
+	e.Status = EInvoiceStatusCancelled
+	e.CancelReason = reason
+	e.CancelledAt = &at
+	e.UpdatedAt = at
+
+	e.AddEvent(NewEInvoiceCancelledEvent(e))
+	return nil
+}
+
+// AddEvent adds a domain event.
+func (e *EInvoice) AddEvent(event DomainEvent) {
+	e.events = append(e.events, event)
+}
+
+// GetEvents returns all domain events.
+func (e *EInvoice) GetEvents() []DomainEvent {
+	return e.events
+}
+
+// ClearEvents clears all domain events.
+func (e *EInvoice) ClearEvents() {
+	e.events = make([]DomainEvent, 0)
+}
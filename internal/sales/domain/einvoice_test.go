@@ -0,0 +1,205 @@
+package domain
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func createTestEInvoice(t *testing.T) *EInvoice {
+	t.Helper()
+
+	invoice := &Invoice{ID: uuid.New(), InvoiceNumber: "INV-001", Status: "sent"}
+	einvoice, err := NewEInvoice(createTestDeal(t), invoice, GeneralPublicBuyer("Kedai Batik Siti"), uuid.New())
+	if err != nil {
+		t.Fatalf("NewEInvoice() error = %v", err)
+	}
+	einvoice.ClearEvents()
+	return einvoice
+}
+
+func einvoiceEventTypes(einvoice *EInvoice) []string {
+	types := make([]string, 0, len(einvoice.GetEvents()))
+	for _, event := range einvoice.GetEvents() {
+		types = append(types, event.EventType())
+	}
+	return types
+}
+
+func TestNewEInvoice(t *testing.T) {
+	deal := createTestDeal(t)
+	invoice := &Invoice{ID: uuid.New(), InvoiceNumber: "INV-001", Status: "sent"}
+
+	einvoice, err := NewEInvoice(deal, invoice, GeneralPublicBuyer(" Kedai Batik Siti "), uuid.New())
+	if err != nil {
+		t.Fatalf("NewEInvoice() error = %v", err)
+	}
+
+	if einvoice.Status != EInvoiceStatusPending {
+		t.Errorf("Status = %s, want %s", einvoice.Status, EInvoiceStatusPending)
+	}
+	if einvoice.DealID != deal.ID || einvoice.InvoiceID != invoice.ID || einvoice.InvoiceNumber != "INV-001" {
+		t.Error("E-invoice should reference the deal and invoice")
+	}
+	if einvoice.Buyer.TIN != EInvoiceGeneralPublicTIN || einvoice.Buyer.Name != "Kedai Batik Siti" {
+		t.Errorf("Buyer = %+v, want the general public buyer", einvoice.Buyer)
+	}
+	if einvoice.NextAttemptAt == nil {
+		t.Error("NextAttemptAt should be set so the e-invoice is submitted")
+	}
+	if types := einvoiceEventTypes(einvoice); len(types) != 1 || types[0] != "einvoice.created" {
+		t.Errorf("events = %v, want einvoice.created", types)
+	}
+}
+
+func TestNewEInvoice_Invalid(t *testing.T) {
+	draft := &Invoice{ID: uuid.New(), InvoiceNumber: "INV-001", Status: "draft"}
+	if _, err := NewEInvoice(createTestDeal(t), draft, GeneralPublicBuyer("Siti"), uuid.New()); !errors.Is(err, ErrEInvoiceInvoiceNotIssued) {
+		t.Errorf("NewEInvoice() of draft invoice error = %v, want %v", err, ErrEInvoiceInvoiceNotIssued)
+	}
+
+	sent := &Invoice{ID: uuid.New(), InvoiceNumber: "INV-001", Status: "sent"}
+	buyer := GeneralPublicBuyer("Siti")
+	buyer.TIN = ""
+	if _, err := NewEInvoice(createTestDeal(t), sent, buyer, uuid.New()); !errors.Is(err, ErrEInvoiceTINRequired) {
+		t.Errorf("NewEInvoice() without buyer TIN error = %v, want %v", err, ErrEInvoiceTINRequired)
+	}
+}
+
+func TestEInvoice_Validation(t *testing.T) {
+	einvoice := createTestEInvoice(t)
+	submitted := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+
+	if err := einvoice.MarkSubmitted("SUB1", "DOC1", submitted); err != nil {
+		t.Fatalf("MarkSubmitted() error = %v", err)
+	}
+	if einvoice.Status != EInvoiceStatusSubmitted || einvoice.DocumentUUID != "DOC1" {
+		t.Errorf("Status, DocumentUUID = %s, %q", einvoice.Status, einvoice.DocumentUUID)
+	}
+	if einvoice.NextAttemptAt == nil || !einvoice.NextAttemptAt.Equal(submitted.Add(EInvoiceValidationInterval)) {
+		t.Errorf("NextAttemptAt = %v, want the first validation check", einvoice.NextAttemptAt)
+	}
+
+	validated := submitted.Add(2 * time.Minute)
+	if err := einvoice.MarkValid("LONG1", "https://myinvois.example/DOC1/share/LONG1", validated); err != nil {
+		t.Fatalf("MarkValid() error = %v", err)
+	}
+	if einvoice.Status != EInvoiceStatusValid || einvoice.NextAttemptAt != nil || einvoice.LongID != "LONG1" {
+		t.Errorf("Status, NextAttemptAt, LongID = %s, %v, %q", einvoice.Status, einvoice.NextAttemptAt, einvoice.LongID)
+	}
+	if types := einvoiceEventTypes(einvoice); len(types) != 1 || types[0] != "einvoice.validated" {
+		t.Errorf("events = %v, want einvoice.validated", types)
+	}
+
+	if err := einvoice.MarkInvalid([]string{"late"}, validated); !errors.Is(err, ErrEInvoiceInvalidStatusTransition) {
+		t.Errorf("MarkInvalid() of valid e-invoice error = %v, want %v", err, ErrEInvoiceInvalidStatusTransition)
+	}
+}
+
+func TestEInvoice_MarkFailed(t *testing.T) {
+	einvoice := createTestEInvoice(t)
+	now := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+
+	if err := einvoice.MarkFailed("connection refused", now); err != nil {
+		t.Fatalf("MarkFailed() error = %v", err)
+	}
+	if einvoice.Status != EInvoiceStatusPending || einvoice.Attempts != 1 {
+		t.Errorf("Status, Attempts = %s, %d, want pending after 1 attempt", einvoice.Status, einvoice.Attempts)
+	}
+	if einvoice.NextAttemptAt == nil || !einvoice.NextAttemptAt.Equal(now.Add(time.Minute)) {
+		t.Errorf("NextAttemptAt = %v, want a retry in a minute", einvoice.NextAttemptAt)
+	}
+
+	for i := 1; i < MaxEInvoiceAttempts; i++ {
+		if err := einvoice.MarkFailed("connection refused", now); err != nil {
+			t.Fatalf("MarkFailed() error = %v", err)
+		}
+	}
+	if einvoice.Status != EInvoiceStatusFailed || einvoice.NextAttemptAt != nil {
+		t.Errorf("Status, NextAttemptAt = %s, %v, want failed with no retry", einvoice.Status, einvoice.NextAttemptAt)
+	}
+	if types := einvoiceEventTypes(einvoice); len(types) != 1 || types[0] != "einvoice.rejected" {
+		t.Errorf("events = %v, want einvoice.rejected", types)
+	}
+}
+
+func TestEInvoice_Resubmit(t *testing.T) {
+	einvoice := createTestEInvoice(t)
+	now := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+
+	if err := einvoice.Resubmit(nil, now); !errors.Is(err, ErrEInvoiceInvalidStatusTransition) {
+		t.Errorf("Resubmit() of pending e-invoice error = %v, want %v", err, ErrEInvoiceInvalidStatusTransition)
+	}
+
+	if err := einvoice.MarkInvalid([]string{"Buyer TIN is not registered"}, now); err != nil {
+		t.Fatalf("MarkInvalid() error = %v", err)
+	}
+
+	buyer := EInvoiceParty{
+		Name:               "Syarikat Batik Sdn Bhd",
+		TIN:                "C12345678900",
+		RegistrationNumber: "201901000005",
+		Phone:              "+60123456789",
+		Address:            EInvoiceAddress{Line1: "Lot 5", City: "Kota Bharu", State: "03", Country: "mys"},
+	}
+	if err := einvoice.Resubmit(&buyer, now.Add(time.Hour)); err != nil {
+		t.Fatalf("Resubmit() error = %v", err)
+	}
+	if einvoice.Status != EInvoiceStatusPending || len(einvoice.ValidationErrors) != 0 || einvoice.Attempts != 0 {
+		t.Errorf("Status, ValidationErrors, Attempts = %s, %v, %d", einvoice.Status, einvoice.ValidationErrors, einvoice.Attempts)
+	}
+	if einvoice.Buyer.TIN != "C12345678900" || einvoice.Buyer.Address.Country != "MYS" {
+		t.Errorf("Buyer = %+v, want the corrected buyer", einvoice.Buyer)
+	}
+}
+
+func TestEInvoice_Cancel(t *testing.T) {
+	einvoice := createTestEInvoice(t)
+	validated := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+	_ = einvoice.MarkSubmitted("SUB1", "DOC1", validated)
+	_ = einvoice.MarkValid("LONG1", "", validated)
+	einvoice.ClearEvents()
+
+	if err := einvoice.Cancel(" ", validated.Add(time.Hour)); !errors.Is(err, ErrEInvoiceCancelReasonRequired) {
+		t.Errorf("Cancel() without reason error = %v, want %v", err, ErrEInvoiceCancelReasonRequired)
+	}
+	if err := einvoice.Cancel("Wrong buyer", validated.Add(EInvoiceCancellationWindow+time.Minute)); !errors.Is(err, ErrEInvoiceCancellationWindowClosed) {
+		t.Errorf("Cancel() after window error = %v, want %v", err, ErrEInvoiceCancellationWindowClosed)
+	}
+
+	if err := einvoice.Cancel("Wrong buyer", validated.Add(time.Hour)); err != nil {
+		t.Fatalf("Cancel() error = %v", err)
+	}
+	if einvoice.Status != EInvoiceStatusCancelled || einvoice.CancelReason != "Wrong buyer" || einvoice.CancelledAt == nil {
+		t.Errorf("Status, CancelReason, CancelledAt = %s, %q, %v", einvoice.Status, einvoice.CancelReason, einvoice.CancelledAt)
+	}
+	if types := einvoiceEventTypes(einvoice); len(types) != 1 || types[0] != "einvoice.cancelled" {
+		t.Errorf("events = %v, want einvoice.cancelled", types)
+	}
+}
+
+func TestEInvoiceSettings_Validate(t *testing.T) {
+	settings := DefaultEInvoiceSettings(uuid.New())
+	if err := settings.Validate(); err != nil {
+		t.Errorf("Validate() of disabled settings error = %v", err)
+	}
+
+	settings.Enabled = true
+	settings.Name = "Kilang Desa Murni Batik"
+	settings.TIN = "C98765432100"
+	settings.RegistrationNumber = "201501000001"
+	settings.MSICCode = "1392"
+	settings.BusinessActivity = "Batik manufacturing"
+	settings.Phone = "+6097654321"
+	settings.Address = EInvoiceAddress{Line1: "Jalan Pantai", City: "Kota Bharu", State: "03", Country: "MYS"}
+	if err := settings.Validate(); !errors.Is(err, ErrEInvoiceInvalidMSICCode) {
+		t.Errorf("Validate() with 4 digit MSIC code error = %v, want %v", err, ErrEInvoiceInvalidMSICCode)
+	}
+
+	settings.MSICCode = "13921"
+	if err := settings.Validate(); err != nil {
+		t.Errorf("Validate() error = %v", err)
+	}
+}
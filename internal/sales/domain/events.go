@@ -2,6 +2,7 @@
 package domain
 
 import (
+	"encoding/json"
 	"time"
 
 	"github.com/google/uuid"
@@ -75,17 +76,22 @@ type LeadCreatedEvent struct {
 	CompanyName string     `json:"company_name"`
 	Source      LeadSource `json:"source"`
 	OwnerID     *uuid.UUID `json:"owner_id,omitempty"`
+	// EstimatedValue is in minor units of Currency; zero when unknown.
+	EstimatedValue int64  `json:"estimated_value,omitempty"`
+	Currency       string `json:"currency,omitempty"`
 }
 
 // NewLeadCreatedEvent creates a new lead created event.
 func NewLeadCreatedEvent(lead *Lead) *LeadCreatedEvent {
 	return &LeadCreatedEvent{
-		BaseEvent:   newBaseEvent("lead.created", "lead", lead.ID, lead.TenantID, lead.Version),
-		LeadCode:    lead.Code,
-		ContactName: lead.Contact.FullName(),
-		CompanyName: lead.Company.Name,
-		Source:      lead.Source,
-		OwnerID:     lead.OwnerID,
+		BaseEvent:      newBaseEvent("lead.created", "lead", lead.ID, lead.TenantID, lead.Version),
+		LeadCode:       lead.Code,
+		ContactName:    lead.Contact.FullName(),
+		CompanyName:    lead.Company.Name,
+		Source:         lead.Source,
+		OwnerID:        lead.OwnerID,
+		EstimatedValue: lead.EstimatedValue.Amount,
+		Currency:       lead.EstimatedValue.Currency,
 	}
 }
 
@@ -219,6 +225,45 @@ func NewLeadDeletedEvent(lead *Lead) *LeadDeletedEvent {
 	}
 }
 
+// LeadMergedEvent is raised when duplicate leads are merged into a lead.
+type LeadMergedEvent struct {
+	BaseEvent
+	LeadCode      string      `json:"lead_code"`
+	MergedLeadIDs []uuid.UUID `json:"merged_lead_ids"`
+	// FirstTouchID is the lead the merged lead's attribution comes from.
+	FirstTouchID uuid.UUID `json:"first_touch_id"`
+	MergedBy     uuid.UUID `json:"merged_by"`
+}
+
+// NewLeadMergedEvent creates a new lead merged event.
+func NewLeadMergedEvent(lead *Lead, mergedLeadIDs []uuid.UUID, firstTouchID, mergedBy uuid.UUID) *LeadMergedEvent {
+	return &LeadMergedEvent{
+		BaseEvent:     newBaseEvent("lead.merged", "lead", lead.ID, lead.TenantID, lead.Version),
+		LeadCode:      lead.Code,
+		MergedLeadIDs: mergedLeadIDs,
+		FirstTouchID:  firstTouchID,
+		MergedBy:      mergedBy,
+	}
+}
+
+// LeadEmailReceivedEvent is raised when an inbound email is logged on a lead.
+type LeadEmailReceivedEvent struct {
+	BaseEvent
+	LeadCode string    `json:"lead_code"`
+	EmailID  uuid.UUID `json:"email_id"`
+	Subject  string    `json:"subject"`
+}
+
+// NewLeadEmailReceivedEvent creates a new lead email received event.
+func NewLeadEmailReceivedEvent(lead *Lead, email *InboundEmail) *LeadEmailReceivedEvent {
+	return &LeadEmailReceivedEvent{
+		BaseEvent: newBaseEvent("lead.email_received", "lead", lead.ID, lead.TenantID, lead.Version),
+		LeadCode:  lead.Code,
+		EmailID:   email.ID,
+		Subject:   email.Subject,
+	}
+}
+
 // ============================================================================
 // Opportunity Events
 // ============================================================================
@@ -291,6 +336,25 @@ func NewOpportunityStageChangedEvent(opp *Opportunity, oldStageID uuid.UUID, old
 	}
 }
 
+// OpportunityPositionChangedEvent is raised when an opportunity is moved to a
+// new position on the pipeline board.
+type OpportunityPositionChangedEvent struct {
+	BaseEvent
+	OpportunityCode string    `json:"opportunity_code"`
+	StageID         uuid.UUID `json:"stage_id"`
+	BoardPosition   string    `json:"board_position"`
+}
+
+// NewOpportunityPositionChangedEvent creates a new opportunity position changed event.
+func NewOpportunityPositionChangedEvent(opp *Opportunity) *OpportunityPositionChangedEvent {
+	return &OpportunityPositionChangedEvent{
+		BaseEvent:       newBaseEvent("opportunity.position_changed", "opportunity", opp.ID, opp.TenantID, opp.Version),
+		OpportunityCode: opp.Code,
+		StageID:         opp.StageID,
+		BoardPosition:   opp.BoardPosition,
+	}
+}
+
 // OpportunityAmountChangedEvent is raised when an opportunity amount changes.
 type OpportunityAmountChangedEvent struct {
 	BaseEvent
@@ -311,6 +375,59 @@ func NewOpportunityAmountChangedEvent(opp *Opportunity) *OpportunityAmountChange
 	}
 }
 
+// OpportunityForecastChangedEvent is raised when the probability override or
+// forecast category of an opportunity changes, recording the values before
+// and after the change.
+type OpportunityForecastChangedEvent struct {
+	BaseEvent
+	OpportunityCode string        `json:"opportunity_code"`
+	Previous        ForecastState `json:"previous"`
+	Current         ForecastState `json:"current"`
+	WeightedAmount  int64         `json:"weighted_amount"`
+	Currency        string        `json:"currency"`
+	ChangedBy       uuid.UUID     `json:"changed_by"`
+}
+
+// NewOpportunityForecastChangedEvent creates a new opportunity forecast changed event.
+func NewOpportunityForecastChangedEvent(opp *Opportunity, previous, current ForecastState, changedBy uuid.UUID) *OpportunityForecastChangedEvent {
+	return &OpportunityForecastChangedEvent{
+		BaseEvent:       newBaseEvent("opportunity.forecast_changed", "opportunity", opp.ID, opp.TenantID, opp.Version),
+		OpportunityCode: opp.Code,
+		Previous:        previous,
+		Current:         current,
+		WeightedAmount:  opp.WeightedAmount.Amount,
+		Currency:        opp.Amount.Currency,
+		ChangedBy:       changedBy,
+	}
+}
+
+// OpportunityRevenueSplitChangedEvent is raised when the revenue splits of an
+// opportunity are set or cleared. Empty splits credit the owner in full.
+type OpportunityRevenueSplitChangedEvent struct {
+	BaseEvent
+	OpportunityCode string        `json:"opportunity_code"`
+	Previous        RevenueSplits `json:"previous"`
+	Current         RevenueSplits `json:"current"`
+	Amount          int64         `json:"amount"`
+	Currency        string        `json:"currency"`
+	Reason          string        `json:"reason,omitempty"`
+	ChangedBy       uuid.UUID     `json:"changed_by"`
+}
+
+// NewOpportunityRevenueSplitChangedEvent creates a new opportunity revenue split changed event.
+func NewOpportunityRevenueSplitChangedEvent(opp *Opportunity, change *RevenueSplitChange) *OpportunityRevenueSplitChangedEvent {
+	return &OpportunityRevenueSplitChangedEvent{
+		BaseEvent:       newBaseEvent("opportunity.revenue_split_changed", "opportunity", opp.ID, opp.TenantID, opp.Version),
+		OpportunityCode: opp.Code,
+		Previous:        change.PreviousSplits,
+		Current:         change.Splits,
+		Amount:          opp.Amount.Amount,
+		Currency:        opp.Amount.Currency,
+		Reason:          change.Reason,
+		ChangedBy:       change.ChangedBy,
+	}
+}
+
 // OpportunityWonEvent is raised when an opportunity is won.
 type OpportunityWonEvent struct {
 	BaseEvent
@@ -525,6 +642,7 @@ func NewDealInvoiceCreatedEvent(deal *Deal, invoice *Invoice) *DealInvoiceCreate
 type DealPaymentReceivedEvent struct {
 	BaseEvent
 	DealCode          string     `json:"deal_code"`
+	CustomerID        uuid.UUID  `json:"customer_id"`
 	PaymentID         uuid.UUID  `json:"payment_id"`
 	InvoiceID         *uuid.UUID `json:"invoice_id,omitempty"`
 	Amount            int64      `json:"amount"`
@@ -538,6 +656,7 @@ func NewDealPaymentReceivedEvent(deal *Deal, payment *Payment) *DealPaymentRecei
 	return &DealPaymentReceivedEvent{
 		BaseEvent:         newBaseEvent("deal.payment_received", "deal", deal.ID, deal.TenantID, deal.Version),
 		DealCode:          deal.Code,
+		CustomerID:        deal.CustomerID,
 		PaymentID:         payment.ID,
 		InvoiceID:         payment.InvoiceID,
 		Amount:            payment.Amount.Amount,
@@ -547,6 +666,37 @@ func NewDealPaymentReceivedEvent(deal *Deal, payment *Payment) *DealPaymentRecei
 	}
 }
 
+// DealInvoiceOverdueEvent is raised when a sent invoice passes its due date
+// unpaid.
+type DealInvoiceOverdueEvent struct {
+	BaseEvent
+	DealCode          string    `json:"deal_code"`
+	CustomerID        uuid.UUID `json:"customer_id"`
+	CustomerName      string    `json:"customer_name"`
+	InvoiceID         uuid.UUID `json:"invoice_id"`
+	InvoiceNumber     string    `json:"invoice_number"`
+	Amount            int64     `json:"amount"`
+	OutstandingAmount int64     `json:"outstanding_amount"`
+	Currency          string    `json:"currency"`
+	DueDate           time.Time `json:"due_date"`
+}
+
+// NewDealInvoiceOverdueEvent creates a new deal invoice overdue event.
+func NewDealInvoiceOverdueEvent(deal *Deal, invoice *Invoice) *DealInvoiceOverdueEvent {
+	return &DealInvoiceOverdueEvent{
+		BaseEvent:         newBaseEvent("deal.invoice_overdue", "deal", deal.ID, deal.TenantID, deal.Version),
+		DealCode:          deal.Code,
+		CustomerID:        deal.CustomerID,
+		CustomerName:      deal.CustomerName,
+		InvoiceID:         invoice.ID,
+		InvoiceNumber:     invoice.InvoiceNumber,
+		Amount:            invoice.Amount.Amount,
+		OutstandingAmount: invoice.OutstandingAmount().Amount,
+		Currency:          deal.Currency,
+		DueDate:           invoice.DueDate,
+	}
+}
+
 // DealDeletedEvent is raised when a deal is deleted.
 type DealDeletedEvent struct {
 	BaseEvent
@@ -561,6 +711,78 @@ func NewDealDeletedEvent(deal *Deal) *DealDeletedEvent {
 	}
 }
 
+// DealRenewalStartedEvent is raised when a renewal opportunity is created for
+// a recurring deal.
+type DealRenewalStartedEvent struct {
+	BaseEvent
+	DealCode      string    `json:"deal_code"`
+	CustomerID    uuid.UUID `json:"customer_id"`
+	OpportunityID uuid.UUID `json:"opportunity_id"`
+	ContractEnd   time.Time `json:"contract_end"`
+	MRR           int64     `json:"mrr"`
+	Currency      string    `json:"currency"`
+}
+
+// NewDealRenewalStartedEvent creates a new deal renewal started event.
+func NewDealRenewalStartedEvent(deal *Deal) *DealRenewalStartedEvent {
+	return &DealRenewalStartedEvent{
+		BaseEvent:     newBaseEvent("deal.renewal_started", "deal", deal.ID, deal.TenantID, deal.Version),
+		DealCode:      deal.Code,
+		CustomerID:    deal.CustomerID,
+		OpportunityID: *deal.Subscription.RenewalOpportunityID,
+		ContractEnd:   deal.Subscription.ContractEnd,
+		MRR:           deal.Subscription.MRR().Amount,
+		Currency:      deal.Currency,
+	}
+}
+
+// DealRenewedEvent is raised when the customer renews a recurring deal.
+type DealRenewedEvent struct {
+	BaseEvent
+	DealCode    string    `json:"deal_code"`
+	CustomerID  uuid.UUID `json:"customer_id"`
+	ContractEnd time.Time `json:"contract_end"`
+	MRR         int64     `json:"mrr"`
+	Currency    string    `json:"currency"`
+}
+
+// NewDealRenewedEvent creates a new deal renewed event.
+func NewDealRenewedEvent(deal *Deal) *DealRenewedEvent {
+	return &DealRenewedEvent{
+		BaseEvent:   newBaseEvent("deal.renewed", "deal", deal.ID, deal.TenantID, deal.Version),
+		DealCode:    deal.Code,
+		CustomerID:  deal.CustomerID,
+		ContractEnd: deal.Subscription.ContractEnd,
+		MRR:         deal.Subscription.MRR().Amount,
+		Currency:    deal.Currency,
+	}
+}
+
+// DealChurnedEvent is raised when the customer of a recurring deal will not
+// renew it.
+type DealChurnedEvent struct {
+	BaseEvent
+	DealCode    string    `json:"deal_code"`
+	CustomerID  uuid.UUID `json:"customer_id"`
+	ContractEnd time.Time `json:"contract_end"`
+	MRR         int64     `json:"mrr"`
+	Currency    string    `json:"currency"`
+	Reason      string    `json:"reason,omitempty"`
+}
+
+// NewDealChurnedEvent creates a new deal churned event.
+func NewDealChurnedEvent(deal *Deal) *DealChurnedEvent {
+	return &DealChurnedEvent{
+		BaseEvent:   newBaseEvent("deal.churned", "deal", deal.ID, deal.TenantID, deal.Version),
+		DealCode:    deal.Code,
+		CustomerID:  deal.CustomerID,
+		ContractEnd: deal.Subscription.ContractEnd,
+		MRR:         deal.Subscription.MRR().Amount,
+		Currency:    deal.Currency,
+		Reason:      deal.Subscription.ChurnReason,
+	}
+}
+
 // ============================================================================
 // Pipeline Events
 // ============================================================================
@@ -594,3 +816,528 @@ func NewPipelineUpdatedEvent(pipeline *Pipeline) *PipelineUpdatedEvent {
 		Name:      pipeline.Name,
 	}
 }
+
+// ============================================================================
+// File Events
+// ============================================================================
+
+// FileUploadedEvent is raised when a file is attached to an entity. The
+// aggregate is the entity the file is attached to.
+type FileUploadedEvent struct {
+	BaseEvent
+	FileID      string `json:"file_id"`
+	Filename    string `json:"filename"`
+	ContentType string `json:"content_type"`
+	Size        int64  `json:"size"`
+}
+
+// NewFileUploadedEvent creates a new file uploaded event.
+func NewFileUploadedEvent(tenantID, entityID uuid.UUID, entityType, fileID, filename, contentType string, size int64) *FileUploadedEvent {
+	return &FileUploadedEvent{
+		BaseEvent:   newBaseEvent("file.uploaded", entityType, entityID, tenantID, 1),
+		FileID:      fileID,
+		Filename:    filename,
+		ContentType: contentType,
+		Size:        size,
+	}
+}
+
+// FileScannedEvent is raised when an attached file has been scanned for
+// viruses. The aggregate is the entity the file is attached to.
+type FileScannedEvent struct {
+	BaseEvent
+	FileID     string     `json:"file_id"`
+	Filename   string     `json:"filename"`
+	UploadedBy uuid.UUID  `json:"uploaded_by"`
+	Status     ScanStatus `json:"status"`
+	Signature  string     `json:"signature,omitempty"`
+	Rejected   bool       `json:"rejected"`
+}
+
+// NewFileScannedEvent creates a new file scanned event.
+func NewFileScannedEvent(tenantID, entityID uuid.UUID, entityType string, scan *AttachmentScan) *FileScannedEvent {
+	return &FileScannedEvent{
+		BaseEvent:  newBaseEvent("file.scanned", entityType, entityID, tenantID, 1),
+		FileID:     scan.FileID,
+		Filename:   scan.Filename,
+		UploadedBy: scan.UploadedBy,
+		Status:     scan.Status,
+		Signature:  scan.Signature,
+		Rejected:   scan.IsRejected(),
+	}
+}
+
+// ============================================================================
+// Customer Erasure Events
+// ============================================================================
+
+// CustomerDataErasedEvent is raised when the sales service has erased a
+// customer's personal data, confirming its part of the customer's erasure.
+type CustomerDataErasedEvent struct {
+	BaseEvent
+	ErasureID  uuid.UUID `json:"erasure_id"`
+	CustomerID uuid.UUID `json:"customer_id"`
+	Service    string    `json:"service"`
+	Records    int64     `json:"records"`
+}
+
+// NewCustomerDataErasedEvent creates a new customer data erased event.
+func NewCustomerDataErasedEvent(tenantID, customerID, erasureID uuid.UUID, records int64) *CustomerDataErasedEvent {
+	return &CustomerDataErasedEvent{
+		BaseEvent:  newBaseEvent("customer.erased", "customer", customerID, tenantID, 1),
+		ErasureID:  erasureID,
+		CustomerID: customerID,
+		Service:    "sales",
+		Records:    records,
+	}
+}
+
+// ============================================================================
+// Discount Approval Events
+// ============================================================================
+
+// DiscountApprovalRequestedEvent is raised when approval of an opportunity's
+// discount is requested from its approvers.
+type DiscountApprovalRequestedEvent struct {
+	BaseEvent
+	OpportunityID   uuid.UUID   `json:"opportunity_id"`
+	OpportunityName string      `json:"opportunity_name"`
+	DiscountAmount  int64       `json:"discount_amount"`
+	DiscountPercent float64     `json:"discount_percent"`
+	Currency        string      `json:"currency"`
+	ApproverIDs     []uuid.UUID `json:"approver_ids"`
+	RequestedBy     uuid.UUID   `json:"requested_by"`
+	Comment         string      `json:"comment,omitempty"`
+}
+
+// NewDiscountApprovalRequestedEvent creates a new discount approval requested event.
+func NewDiscountApprovalRequestedEvent(approval *DiscountApproval) *DiscountApprovalRequestedEvent {
+	return &DiscountApprovalRequestedEvent{
+		BaseEvent:       newBaseEvent("discount_approval.requested", "discount_approval", approval.ID, approval.TenantID, approval.Version),
+		OpportunityID:   approval.OpportunityID,
+		OpportunityName: approval.OpportunityName,
+		DiscountAmount:  approval.Discount.Amount.Amount,
+		DiscountPercent: approval.Discount.Percent,
+		Currency:        approval.Discount.Amount.Currency,
+		ApproverIDs:     approval.ApproverIDs,
+		RequestedBy:     approval.RequestedBy,
+		Comment:         approval.RequestComment,
+	}
+}
+
+// DiscountApprovalDecidedEvent is raised when an approver approves or rejects a discount.
+type DiscountApprovalDecidedEvent struct {
+	BaseEvent
+	OpportunityID uuid.UUID              `json:"opportunity_id"`
+	Status        DiscountApprovalStatus `json:"status"`
+	RequestedBy   uuid.UUID              `json:"requested_by"`
+	DecidedBy     uuid.UUID              `json:"decided_by"`
+	Comment       string                 `json:"comment,omitempty"`
+}
+
+// NewDiscountApprovalDecidedEvent creates a new discount approval decided event.
+func NewDiscountApprovalDecidedEvent(approval *DiscountApproval) *DiscountApprovalDecidedEvent {
+	return &DiscountApprovalDecidedEvent{
+		BaseEvent:     newBaseEvent("discount_approval."+string(approval.Status), "discount_approval", approval.ID, approval.TenantID, approval.Version),
+		OpportunityID: approval.OpportunityID,
+		Status:        approval.Status,
+		RequestedBy:   approval.RequestedBy,
+		DecidedBy:     *approval.DecidedBy,
+		Comment:       approval.DecisionComment,
+	}
+}
+
+// ============================================================================
+// Order Events
+// ============================================================================
+
+// OrderCreatedEvent is raised when a production order is created from a deal.
+type OrderCreatedEvent struct {
+	BaseEvent
+	OrderNumber  string      `json:"order_number"`
+	DealID       uuid.UUID   `json:"deal_id"`
+	CustomerID   uuid.UUID   `json:"customer_id"`
+	CustomerName string      `json:"customer_name"`
+	Items        []OrderItem `json:"items"`
+	DeliveryDate *time.Time  `json:"delivery_date,omitempty"`
+	CreatedBy    uuid.UUID   `json:"created_by"`
+}
+
+// NewOrderCreatedEvent creates a new order created event.
+func NewOrderCreatedEvent(order *Order) *OrderCreatedEvent {
+	return &OrderCreatedEvent{
+		BaseEvent:    newBaseEvent("order.created", "order", order.ID, order.TenantID, order.Version),
+		OrderNumber:  order.OrderNumber,
+		DealID:       order.DealID,
+		CustomerID:   order.CustomerID,
+		CustomerName: order.CustomerName,
+		Items:        order.Items,
+		DeliveryDate: order.DeliveryDate,
+		CreatedBy:    order.CreatedBy,
+	}
+}
+
+// OrderUpdatedEvent is raised when an order's delivery date, production notes
+// or external reference change.
+type OrderUpdatedEvent struct {
+	BaseEvent
+	OrderNumber       string     `json:"order_number"`
+	DeliveryDate      *time.Time `json:"delivery_date,omitempty"`
+	ProductionNotes   string     `json:"production_notes,omitempty"`
+	ExternalReference string     `json:"external_reference,omitempty"`
+}
+
+// NewOrderUpdatedEvent creates a new order updated event.
+func NewOrderUpdatedEvent(order *Order) *OrderUpdatedEvent {
+	return &OrderUpdatedEvent{
+		BaseEvent:         newBaseEvent("order.updated", "order", order.ID, order.TenantID, order.Version),
+		OrderNumber:       order.OrderNumber,
+		DeliveryDate:      order.DeliveryDate,
+		ProductionNotes:   order.ProductionNotes,
+		ExternalReference: order.ExternalReference,
+	}
+}
+
+// OrderStatusChangedEvent is raised when an order moves through production.
+type OrderStatusChangedEvent struct {
+	BaseEvent
+	OrderNumber    string      `json:"order_number"`
+	DealID         uuid.UUID   `json:"deal_id"`
+	FromStatus     OrderStatus `json:"from_status"`
+	ToStatus       OrderStatus `json:"to_status"`
+	TrackingNumber string      `json:"tracking_number,omitempty"`
+	ChangedBy      uuid.UUID   `json:"changed_by"`
+	Note           string      `json:"note,omitempty"`
+}
+
+// NewOrderStatusChangedEvent creates a new order status changed event.
+func NewOrderStatusChangedEvent(order *Order, from OrderStatus) *OrderStatusChangedEvent {
+	change := order.StatusHistory[len(order.StatusHistory)-1]
+	return &OrderStatusChangedEvent{
+		BaseEvent:      newBaseEvent("order.status_changed", "order", order.ID, order.TenantID, order.Version),
+		OrderNumber:    order.OrderNumber,
+		DealID:         order.DealID,
+		FromStatus:     from,
+		ToStatus:       order.Status,
+		TrackingNumber: order.TrackingNumber,
+		ChangedBy:      change.ChangedBy,
+		Note:           change.Note,
+	}
+}
+
+// ============================================================================
+// Shipment Events
+// ============================================================================
+
+// ShipmentCreatedEvent is raised when a deal's products are handed to a carrier.
+type ShipmentCreatedEvent struct {
+	BaseEvent
+	DealID         uuid.UUID `json:"deal_id"`
+	CustomerID     uuid.UUID `json:"customer_id"`
+	Carrier        string    `json:"carrier"`
+	TrackingNumber string    `json:"tracking_number"`
+	CreatedBy      uuid.UUID `json:"created_by"`
+}
+
+// NewShipmentCreatedEvent creates a new shipment created event.
+func NewShipmentCreatedEvent(shipment *Shipment) *ShipmentCreatedEvent {
+	return &ShipmentCreatedEvent{
+		BaseEvent:      newBaseEvent("shipment.created", "shipment", shipment.ID, shipment.TenantID, shipment.Version),
+		DealID:         shipment.DealID,
+		CustomerID:     shipment.CustomerID,
+		Carrier:        shipment.Carrier,
+		TrackingNumber: shipment.TrackingNumber,
+		CreatedBy:      shipment.CreatedBy,
+	}
+}
+
+// ShipmentNotificationEvent carries what the customer is told about a
+// shipment's progress.
+type ShipmentNotificationEvent struct {
+	BaseEvent
+	DealID            uuid.UUID  `json:"deal_id"`
+	DealCode          string     `json:"deal_code"`
+	CustomerID        uuid.UUID  `json:"customer_id"`
+	CustomerName      string     `json:"customer_name"`
+	RecipientName     string     `json:"recipient_name,omitempty"`
+	RecipientEmail    string     `json:"recipient_email,omitempty"`
+	Carrier           string     `json:"carrier"`
+	TrackingNumber    string     `json:"tracking_number"`
+	TrackingURL       string     `json:"tracking_url,omitempty"`
+	EstimatedDelivery *time.Time `json:"estimated_delivery,omitempty"`
+	ShippedAt         *time.Time `json:"shipped_at,omitempty"`
+	DeliveredAt       *time.Time `json:"delivered_at,omitempty"`
+}
+
+func newShipmentNotificationEvent(eventType string, shipment *Shipment) ShipmentNotificationEvent {
+	return ShipmentNotificationEvent{
+		BaseEvent:         newBaseEvent(eventType, "shipment", shipment.ID, shipment.TenantID, shipment.Version),
+		DealID:            shipment.DealID,
+		DealCode:          shipment.DealCode,
+		CustomerID:        shipment.CustomerID,
+		CustomerName:      shipment.CustomerName,
+		RecipientName:     shipment.RecipientName,
+		RecipientEmail:    shipment.RecipientEmail,
+		Carrier:           shipment.Carrier,
+		TrackingNumber:    shipment.TrackingNumber,
+		TrackingURL:       shipment.TrackingURL,
+		EstimatedDelivery: shipment.EstimatedDelivery,
+		ShippedAt:         shipment.ShippedAt,
+		DeliveredAt:       shipment.DeliveredAt,
+	}
+}
+
+// ShipmentShippedEvent is raised when the carrier first reports having a shipment.
+type ShipmentShippedEvent struct {
+	ShipmentNotificationEvent
+}
+
+// NewShipmentShippedEvent creates a new shipment shipped event.
+func NewShipmentShippedEvent(shipment *Shipment) *ShipmentShippedEvent {
+	return &ShipmentShippedEvent{newShipmentNotificationEvent("shipment.shipped", shipment)}
+}
+
+// ShipmentDeliveredEvent is raised when a shipment is delivered.
+type ShipmentDeliveredEvent struct {
+	ShipmentNotificationEvent
+}
+
+// NewShipmentDeliveredEvent creates a new shipment delivered event.
+func NewShipmentDeliveredEvent(shipment *Shipment) *ShipmentDeliveredEvent {
+	return &ShipmentDeliveredEvent{newShipmentNotificationEvent("shipment.delivered", shipment)}
+}
+
+// ShipmentStatusChangedEvent is raised on every change of a shipment's status.
+type ShipmentStatusChangedEvent struct {
+	BaseEvent
+	DealID         uuid.UUID      `json:"deal_id"`
+	Carrier        string         `json:"carrier"`
+	TrackingNumber string         `json:"tracking_number"`
+	FromStatus     ShipmentStatus `json:"from_status"`
+	ToStatus       ShipmentStatus `json:"to_status"`
+	Description    string         `json:"description,omitempty"`
+	Location       string         `json:"location,omitempty"`
+	Source         string         `json:"source"`
+}
+
+// NewShipmentStatusChangedEvent creates a new shipment status changed event.
+func NewShipmentStatusChangedEvent(shipment *Shipment, from ShipmentStatus, tracking ShipmentTrackingEvent) *ShipmentStatusChangedEvent {
+	return &ShipmentStatusChangedEvent{
+		BaseEvent:      newBaseEvent("shipment.status_changed", "shipment", shipment.ID, shipment.TenantID, shipment.Version),
+		DealID:         shipment.DealID,
+		Carrier:        shipment.Carrier,
+		TrackingNumber: shipment.TrackingNumber,
+		FromStatus:     from,
+		ToStatus:       shipment.Status,
+		Description:    tracking.Description,
+		Location:       tracking.Location,
+		Source:         tracking.Source,
+	}
+}
+
+// ============================================================================
+// E-Invoice Events
+// ============================================================================
+
+// EInvoiceCreatedEvent is raised when a deal invoice is queued for MyInvois.
+type EInvoiceCreatedEvent struct {
+	BaseEvent
+	DealID        uuid.UUID `json:"deal_id"`
+	InvoiceID     uuid.UUID `json:"invoice_id"`
+	InvoiceNumber string    `json:"invoice_number"`
+	CreatedBy     uuid.UUID `json:"created_by"`
+}
+
+// NewEInvoiceCreatedEvent creates a new e-invoice created event.
+func NewEInvoiceCreatedEvent(einvoice *EInvoice) *EInvoiceCreatedEvent {
+	return &EInvoiceCreatedEvent{
+		BaseEvent:     newBaseEvent("einvoice.created", "einvoice", einvoice.ID, einvoice.TenantID, einvoice.Version),
+		DealID:        einvoice.DealID,
+		InvoiceID:     einvoice.InvoiceID,
+		InvoiceNumber: einvoice.InvoiceNumber,
+		CreatedBy:     einvoice.CreatedBy,
+	}
+}
+
+// EInvoiceValidatedEvent is raised when MyInvois validates an e-invoice.
+type EInvoiceValidatedEvent struct {
+	BaseEvent
+	DealID        uuid.UUID `json:"deal_id"`
+	InvoiceID     uuid.UUID `json:"invoice_id"`
+	InvoiceNumber string    `json:"invoice_number"`
+	DocumentUUID  string    `json:"document_uuid"`
+	LongID        string    `json:"long_id"`
+	ValidationURL string    `json:"validation_url"`
+	ValidatedAt   time.Time `json:"validated_at"`
+}
+
+// NewEInvoiceValidatedEvent creates a new e-invoice validated event.
+func NewEInvoiceValidatedEvent(einvoice *EInvoice) *EInvoiceValidatedEvent {
+	event := &EInvoiceValidatedEvent{
+		BaseEvent:     newBaseEvent("einvoice.validated", "einvoice", einvoice.ID, einvoice.TenantID, einvoice.Version),
+		DealID:        einvoice.DealID,
+		InvoiceID:     einvoice.InvoiceID,
+		InvoiceNumber: einvoice.InvoiceNumber,
+		DocumentUUID:  einvoice.DocumentUUID,
+		LongID:        einvoice.LongID,
+		ValidationURL: einvoice.ValidationURL,
+	}
+	if einvoice.ValidatedAt != nil {
+		event.ValidatedAt = *einvoice.ValidatedAt
+	}
+	return event
+}
+
+// EInvoiceRejectedEvent is raised when MyInvois rejects an e-invoice or it
+// cannot be submitted, so that it is corrected and resubmitted.
+type EInvoiceRejectedEvent struct {
+	BaseEvent
+	DealID           uuid.UUID `json:"deal_id"`
+	InvoiceID        uuid.UUID `json:"invoice_id"`
+	InvoiceNumber    string    `json:"invoice_number"`
+	Status           string    `json:"status"`
+	ValidationErrors []string  `json:"validation_errors,omitempty"`
+	LastError        string    `json:"last_error,omitempty"`
+}
+
+// NewEInvoiceRejectedEvent creates a new e-invoice rejected event.
+func NewEInvoiceRejectedEvent(einvoice *EInvoice) *EInvoiceRejectedEvent {
+	return &EInvoiceRejectedEvent{
+		BaseEvent:        newBaseEvent("einvoice.rejected", "einvoice", einvoice.ID, einvoice.TenantID, einvoice.Version),
+		DealID:           einvoice.DealID,
+		InvoiceID:        einvoice.InvoiceID,
+		InvoiceNumber:    einvoice.InvoiceNumber,
+		Status:           string(einvoice.Status),
+		ValidationErrors: einvoice.ValidationErrors,
+		LastError:        einvoice.LastError,
+	}
+}
+
+// EInvoiceCancelledEvent is raised when a valid e-invoice is cancelled.
+type EInvoiceCancelledEvent struct {
+	BaseEvent
+	DealID        uuid.UUID `json:"deal_id"`
+	InvoiceID     uuid.UUID `json:"invoice_id"`
+	InvoiceNumber string    `json:"invoice_number"`
+	DocumentUUID  string    `json:"document_uuid"`
+	Reason        string    `json:"reason"`
+}
+
+// NewEInvoiceCancelledEvent creates a new e-invoice cancelled event.
+func NewEInvoiceCancelledEvent(einvoice *EInvoice) *EInvoiceCancelledEvent {
+	return &EInvoiceCancelledEvent{
+		BaseEvent:     newBaseEvent("einvoice.cancelled", "einvoice", einvoice.ID, einvoice.TenantID, einvoice.Version),
+		DealID:        einvoice.DealID,
+		InvoiceID:     einvoice.InvoiceID,
+		InvoiceNumber: einvoice.InvoiceNumber,
+		DocumentUUID:  einvoice.DocumentUUID,
+		Reason:        einvoice.CancelReason,
+	}
+}
+
+// ============================================================================
+// Accounting Events
+// ============================================================================
+
+// AccountingSyncFailedEvent is raised when an invoice or payment could not be
+// pushed to the accounting system and needs a manual re-sync.
+type AccountingSyncFailedEvent struct {
+	BaseEvent
+	DealID     uuid.UUID `json:"deal_id"`
+	RecordType string    `json:"record_type"`
+	RecordID   uuid.UUID `json:"record_id"`
+	Reference  string    `json:"reference"`
+	Attempts   int       `json:"attempts"`
+	LastError  string    `json:"last_error"`
+}
+
+// NewAccountingSyncFailedEvent creates a new accounting sync failed event.
+func NewAccountingSyncFailedEvent(sync *AccountingSync) *AccountingSyncFailedEvent {
+	return &AccountingSyncFailedEvent{
+		BaseEvent:  newBaseEvent("accounting_sync.failed", "accounting_sync", sync.ID, sync.TenantID, sync.Version),
+		DealID:     sync.DealID,
+		RecordType: string(sync.RecordType),
+		RecordID:   sync.RecordID,
+		Reference:  sync.Reference,
+		Attempts:   sync.Attempts,
+		LastError:  sync.LastError,
+	}
+}
+
+// ============================================================================
+// Insight Events
+// ============================================================================
+
+// InsightDetectedEvent is raised when the insights engine detects a notable
+// change in a tenant's sales. Its type is insight.<insight type>, so
+// consumers subscribe to the insights they route.
+type InsightDetectedEvent struct {
+	BaseEvent
+	Insight   InsightType `json:"insight"`
+	PeriodKey string      `json:"period_key"`
+	InsightDetails
+}
+
+// NewInsightDetectedEvent creates a new insight detected event.
+func NewInsightDetectedEvent(insight *Insight) *InsightDetectedEvent {
+	event := &InsightDetectedEvent{
+		BaseEvent:      newBaseEvent("insight."+string(insight.Type), "insight", insight.ID, insight.TenantID, 1),
+		Insight:        insight.Type,
+		PeriodKey:      insight.PeriodKey,
+		InsightDetails: insight.Details,
+	}
+	event.Occurred = insight.DetectedAt
+	return event
+}
+
+// ============================================================================
+// Comment Events
+// ============================================================================
+
+// CommentMentionedEvent is raised when a comment mentions users, on creation
+// or when an edit mentions more. MentionedUserIDs only holds the users not
+// notified of the comment before.
+type CommentMentionedEvent struct {
+	BaseEvent
+	EntityType       CommentEntityType `json:"entity_type"`
+	EntityID         uuid.UUID         `json:"entity_id"`
+	ThreadID         uuid.UUID         `json:"thread_id"`
+	AuthorID         uuid.UUID         `json:"author_id"`
+	MentionedUserIDs []uuid.UUID       `json:"mentioned_user_ids"`
+	Excerpt          string            `json:"excerpt"`
+}
+
+// NewCommentMentionedEvent creates a new comment mentioned event.
+func NewCommentMentionedEvent(comment *Comment, mentioned []uuid.UUID) *CommentMentionedEvent {
+	return &CommentMentionedEvent{
+		BaseEvent:        newBaseEvent("comment.mentioned", "comment", comment.ID, comment.TenantID, comment.Version),
+		EntityType:       comment.EntityType,
+		EntityID:         comment.EntityID,
+		ThreadID:         comment.ThreadID(),
+		AuthorID:         comment.AuthorID,
+		MentionedUserIDs: mentioned,
+		Excerpt:          comment.Excerpt(200),
+	}
+}
+
+// ============================================================================
+// Event Records
+// ============================================================================
+
+// EventRecord is a domain event as recorded in the append-only event store.
+// Position orders every event in the store; Sequence numbers the events of
+// one aggregate from 1 without gaps.
+type EventRecord struct {
+	Position      int64             `json:"position"`
+	Sequence      int64             `json:"sequence"`
+	ID            uuid.UUID         `json:"id"`
+	TenantID      uuid.UUID         `json:"tenant_id"`
+	AggregateID   uuid.UUID         `json:"aggregate_id"`
+	AggregateType string            `json:"aggregate_type"`
+	EventType     string            `json:"event_type"`
+	Data          json.RawMessage   `json:"data"`
+	Metadata      map[string]string `json:"metadata,omitempty"`
+	Version       int               `json:"version"`
+	OccurredAt    time.Time         `json:"occurred_at"`
+	RecordedAt    time.Time         `json:"recorded_at"`
+}
@@ -0,0 +1,207 @@
+// Package domain contains the domain layer for the Sales Pipeline service.
+package domain
+
+import (
+	"errors"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Exchange rate errors
+var (
+	ErrExchangeRateNotFound      = errors.New("exchange rate not found")
+	ErrInvalidExchangeRate       = errors.New("exchange rate must be a positive number")
+	ErrSameCurrencyExchangeRate  = errors.New("exchange rate currencies must differ")
+	ErrInvalidExchangeRateSource = errors.New("invalid exchange rate source")
+)
+
+// DefaultBaseCurrency is used when a tenant has not configured a base currency.
+const DefaultBaseCurrency = "MYR"
+
+// ExchangeRateSource identifies where an exchange rate came from.
+type ExchangeRateSource string
+
+const (
+	ExchangeRateSourceManual ExchangeRateSource = "manual"
+	ExchangeRateSourceECB    ExchangeRateSource = "ecb"
+	ExchangeRateSourceBNM    ExchangeRateSource = "bnm"
+)
+
+// IsValid checks if the exchange rate source is valid.
+func (s ExchangeRateSource) IsValid() bool {
+	switch s {
+	case ExchangeRateSourceManual, ExchangeRateSourceECB, ExchangeRateSourceBNM:
+		return true
+	}
+	return false
+}
+
+// IsProvider checks if the source is an external rate provider.
+func (s ExchangeRateSource) IsProvider() bool {
+	return s == ExchangeRateSourceECB || s == ExchangeRateSourceBNM
+}
+
+// ExchangeRate is the number of ToCurrency major units for one FromCurrency
+// major unit, effective from EffectiveDate until a newer rate for the pair.
+type ExchangeRate struct {
+	ID            uuid.UUID          `json:"id" db:"id"`
+	TenantID      uuid.UUID          `json:"tenant_id" db:"tenant_id"`
+	FromCurrency  string             `json:"from_currency" db:"from_currency"`
+	ToCurrency    string             `json:"to_currency" db:"to_currency"`
+	Rate          float64            `json:"rate" db:"rate"`
+	EffectiveDate time.Time          `json:"effective_date" db:"effective_date"`
+	Source        ExchangeRateSource `json:"source" db:"source"`
+	CreatedBy     *uuid.UUID         `json:"created_by,omitempty" db:"created_by"`
+	CreatedAt     time.Time          `json:"created_at" db:"created_at"`
+	UpdatedAt     time.Time          `json:"updated_at" db:"updated_at"`
+}
+
+// NewExchangeRate creates a new exchange rate.
+func NewExchangeRate(tenantID uuid.UUID, from, to string, rate float64, effectiveDate time.Time, source ExchangeRateSource, createdBy *uuid.UUID) (*ExchangeRate, error) {
+	from = strings.ToUpper(strings.TrimSpace(from))
+	to = strings.ToUpper(strings.TrimSpace(to))
+
+	if !IsSupportedCurrency(from) || !IsSupportedCurrency(to) {
+		return nil, ErrInvalidCurrency
+	}
+	if from == to {
+		return nil, ErrSameCurrencyExchangeRate
+	}
+	if !isValidRate(rate) {
+		return nil, ErrInvalidExchangeRate
+	}
+	if !source.IsValid() {
+		return nil, ErrInvalidExchangeRateSource
+	}
+
+	now := time.Now().UTC()
+	return &ExchangeRate{
+		ID:            uuid.New(),
+		TenantID:      tenantID,
+		FromCurrency:  from,
+		ToCurrency:    to,
+		Rate:          rate,
+		EffectiveDate: TruncateToDay(effectiveDate),
+		Source:        source,
+		CreatedBy:     createdBy,
+		CreatedAt:     now,
+		UpdatedAt:     now,
+	}, nil
+}
+
+// isValidRate checks that a rate can be used for conversion.
+func isValidRate(rate float64) bool {
+	return rate > 0 && !math.IsInf(rate, 0) && !math.IsNaN(rate)
+}
+
+// ============================================================================
+// Exchange Rate Table
+// ============================================================================
+
+// ExchangeRateTable resolves conversion rates between currencies from a set
+// of known rates. Pairs missing a direct rate are resolved through the
+// inverse rate or through a single intermediate currency.
+type ExchangeRateTable struct {
+	rates map[string]map[string]float64
+}
+
+// NewExchangeRateTable builds a table from rates ordered by preference; the
+// first rate seen for a pair wins.
+func NewExchangeRateTable(rates []*ExchangeRate) *ExchangeRateTable {
+	t := &ExchangeRateTable{rates: make(map[string]map[string]float64)}
+	for _, r := range rates {
+		t.add(r.FromCurrency, r.ToCurrency, r.Rate)
+	}
+	return t
+}
+
+func (t *ExchangeRateTable) add(from, to string, rate float64) {
+	if !isValidRate(rate) {
+		return
+	}
+	if t.rates[from] == nil {
+		t.rates[from] = make(map[string]float64)
+	}
+	if _, exists := t.rates[from][to]; !exists {
+		t.rates[from][to] = rate
+	}
+}
+
+// Rate returns the rate converting from one currency to another.
+func (t *ExchangeRateTable) Rate(from, to string) (float64, bool) {
+	if from == to {
+		return 1, true
+	}
+	if rate, ok := t.direct(from, to); ok {
+		return rate, true
+	}
+
+	// Triangulate through a currency known to both sides
+	for via := range t.rates[from] {
+		first, _ := t.direct(from, via)
+		if second, ok := t.direct(via, to); ok {
+			return first * second, true
+		}
+	}
+	for via, rates := range t.rates {
+		if _, ok := rates[from]; !ok {
+			continue
+		}
+		first, _ := t.direct(from, via)
+		if second, ok := t.direct(via, to); ok {
+			return first * second, true
+		}
+	}
+	return 0, false
+}
+
+// direct returns the stored rate for a pair, or the inverse of the reverse pair.
+func (t *ExchangeRateTable) direct(from, to string) (float64, bool) {
+	if rate, ok := t.rates[from][to]; ok {
+		return rate, true
+	}
+	if rate, ok := t.rates[to][from]; ok {
+		return 1 / rate, true
+	}
+	return 0, false
+}
+
+// ============================================================================
+// Currency Settings
+// ============================================================================
+
+// CurrencySettings holds a tenant's base currency and automatic rate source.
+type CurrencySettings struct {
+	TenantID      uuid.UUID           `json:"tenant_id" db:"tenant_id"`
+	BaseCurrency  string              `json:"base_currency" db:"base_currency"`
+	RateProvider  *ExchangeRateSource `json:"rate_provider,omitempty" db:"rate_provider"`
+	LastFetchedAt *time.Time          `json:"last_fetched_at,omitempty" db:"last_fetched_at"`
+	UpdatedAt     time.Time           `json:"updated_at" db:"updated_at"`
+}
+
+// DefaultCurrencySettings returns the settings used for tenants without a configuration.
+func DefaultCurrencySettings(tenantID uuid.UUID) *CurrencySettings {
+	return &CurrencySettings{
+		TenantID:     tenantID,
+		BaseCurrency: DefaultBaseCurrency,
+	}
+}
+
+// ============================================================================
+// Pipeline Currency Breakdown
+// ============================================================================
+
+// PipelineCurrencyValue is the open pipeline value held in one currency,
+// grouped by the base currency the stored amounts were converted to.
+type PipelineCurrencyValue struct {
+	Currency           string `db:"currency"`
+	BaseCurrency       string `db:"base_currency"` // empty when not converted
+	OpportunityCount   int64  `db:"opportunity_count"`
+	Amount             int64  `db:"amount"`
+	WeightedAmount     int64  `db:"weighted_amount"`
+	BaseAmount         int64  `db:"base_amount"`
+	BaseWeightedAmount int64  `db:"base_weighted_amount"`
+}
@@ -0,0 +1,159 @@
+// Package domain contains the domain layer for the Sales Pipeline service.
+package domain
+
+import (
+	"errors"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestNewExchangeRate(t *testing.T) {
+	effective := time.Date(2024, 3, 15, 14, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		from    string
+		to      string
+		rate    float64
+		source  ExchangeRateSource
+		wantErr error
+	}{
+		{"valid", "usd", " myr ", 4.7, ExchangeRateSourceManual, nil},
+		{"same currency", "USD", "USD", 1, ExchangeRateSourceManual, ErrSameCurrencyExchangeRate},
+		{"unsupported currency", "USD", "XYZ", 1, ExchangeRateSourceManual, ErrInvalidCurrency},
+		{"zero rate", "USD", "MYR", 0, ExchangeRateSourceManual, ErrInvalidExchangeRate},
+		{"infinite rate", "USD", "MYR", math.Inf(1), ExchangeRateSourceManual, ErrInvalidExchangeRate},
+		{"invalid source", "USD", "MYR", 4.7, ExchangeRateSource("fed"), ErrInvalidExchangeRateSource},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rate, err := NewExchangeRate(uuid.New(), tt.from, tt.to, tt.rate, effective, tt.source, nil)
+
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Errorf("NewExchangeRate() error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("NewExchangeRate() unexpected error: %v", err)
+			}
+			if rate.FromCurrency != "USD" || rate.ToCurrency != "MYR" {
+				t.Errorf("NewExchangeRate() pair = %s/%s, want USD/MYR", rate.FromCurrency, rate.ToCurrency)
+			}
+			if !rate.EffectiveDate.Equal(time.Date(2024, 3, 15, 0, 0, 0, 0, time.UTC)) {
+				t.Errorf("NewExchangeRate() EffectiveDate = %v", rate.EffectiveDate)
+			}
+		})
+	}
+}
+
+func TestExchangeRateTable_Rate(t *testing.T) {
+	table := NewExchangeRateTable([]*ExchangeRate{
+		{FromCurrency: "USD", ToCurrency: "MYR", Rate: 4.5},
+		{FromCurrency: "USD", ToCurrency: "MYR", Rate: 9.9}, // ignored, first rate wins
+		{FromCurrency: "EUR", ToCurrency: "USD", Rate: 1.1},
+		{FromCurrency: "SGD", ToCurrency: "MYR", Rate: 3.5},
+	})
+
+	tests := []struct {
+		name   string
+		from   string
+		to     string
+		want   float64
+		wantOK bool
+	}{
+		{"same currency", "MYR", "MYR", 1, true},
+		{"direct", "USD", "MYR", 4.5, true},
+		{"inverse", "MYR", "USD", 1 / 4.5, true},
+		{"through intermediate", "EUR", "MYR", 1.1 * 4.5, true},
+		{"through intermediate inverse", "SGD", "USD", 3.5 / 4.5, true},
+		{"unknown", "JPY", "MYR", 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := table.Rate(tt.from, tt.to)
+			if ok != tt.wantOK {
+				t.Fatalf("Rate() ok = %v, want %v", ok, tt.wantOK)
+			}
+			if math.Abs(got-tt.want) > 1e-9 {
+				t.Errorf("Rate() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMoney_ConvertTo(t *testing.T) {
+	tests := []struct {
+		name     string
+		money    Money
+		currency string
+		rate     float64
+		want     int64
+		wantErr  bool
+	}{
+		{"same currency", Money{Amount: 1050, Currency: "USD"}, "USD", 4.5, 1050, false},
+		{"two decimals", Money{Amount: 10000, Currency: "USD"}, "MYR", 4.5, 45000, false},
+		{"rounds half up", Money{Amount: 1, Currency: "USD"}, "MYR", 4.5, 5, false},
+		{"to zero decimals", Money{Amount: 10000, Currency: "USD"}, "JPY", 150, 15000, false},
+		{"from zero decimals", Money{Amount: 15000, Currency: "JPY"}, "USD", 1.0 / 150, 10000, false},
+		{"invalid rate", Money{Amount: 100, Currency: "USD"}, "MYR", -1, 0, true},
+		{"overflow", Money{Amount: math.MaxInt64, Currency: "USD"}, "MYR", 4.5, 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.money.ConvertTo(tt.currency, tt.rate)
+
+			if tt.wantErr {
+				if err == nil {
+					t.Error("ConvertTo() expected error, got nil")
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("ConvertTo() unexpected error: %v", err)
+			}
+			if got.Amount != tt.want || got.Currency != tt.currency {
+				t.Errorf("ConvertTo() = %d %s, want %d %s", got.Amount, got.Currency, tt.want, tt.currency)
+			}
+		})
+	}
+}
+
+func TestOpportunity_ApplyExchangeRate(t *testing.T) {
+	opp := &Opportunity{
+		Amount:         Money{Amount: 10000, Currency: "USD"},
+		WeightedAmount: Money{Amount: 5000, Currency: "USD"},
+		Probability:    50,
+	}
+
+	if err := opp.ApplyExchangeRate("MYR", 4.5); err != nil {
+		t.Fatalf("ApplyExchangeRate() error = %v", err)
+	}
+	if opp.BaseAmount == nil || opp.BaseAmount.Amount != 45000 || opp.BaseAmount.Currency != "MYR" {
+		t.Errorf("BaseAmount = %v, want 45000 MYR", opp.BaseAmount)
+	}
+	if opp.BaseWeightedAmount == nil || opp.BaseWeightedAmount.Amount != 22500 {
+		t.Errorf("BaseWeightedAmount = %v, want 22500 MYR", opp.BaseWeightedAmount)
+	}
+
+	// Changing the amount in the same currency keeps the base values in step
+	opp.SetAmount(Money{Amount: 20000, Currency: "USD"})
+	if opp.BaseAmount == nil || opp.BaseAmount.Amount != 90000 {
+		t.Errorf("BaseAmount after SetAmount = %v, want 90000 MYR", opp.BaseAmount)
+	}
+
+	// Changing the currency invalidates the rate
+	opp.SetAmount(Money{Amount: 20000, Currency: "EUR"})
+	if opp.BaseAmount != nil || opp.BaseWeightedAmount != nil || opp.ExchangeRate != 0 {
+		t.Errorf("base values not cleared after currency change")
+	}
+}
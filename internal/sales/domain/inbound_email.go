@@ -0,0 +1,298 @@
+package domain
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+)
+
+// ============================================================================
+// Inbound Email Errors
+// ============================================================================
+
+var (
+	ErrInboundMailboxNotFound = errors.New("inbound mailbox not found")
+	ErrInboundEmailDuplicate  = errors.New("inbound email already received")
+)
+
+// ============================================================================
+// Inbound Email Limits
+// ============================================================================
+
+const (
+	// MaxInboundEmailsPerSenderPerHour caps how many emails from one sender
+	// are ingested per hour, so a mail loop cannot flood the lead list.
+	MaxInboundEmailsPerSenderPerHour = 20
+
+	// MaxInboundEmailBodyLength is the longest body kept from an email, in bytes.
+	MaxInboundEmailBodyLength = 64 << 10
+
+	// MaxInboundEmailAttachments is the most attachments kept from an email.
+	MaxInboundEmailAttachments = 10
+
+	// MaxInboundEmailAttachmentSize is the largest attachment kept from an email.
+	MaxInboundEmailAttachmentSize = 10 << 20
+)
+
+// ============================================================================
+// Inbound Mailbox
+// ============================================================================
+
+// InboundMailbox is a tenant's forwarding address for sales enquiries. Email
+// sent or forwarded to <token>@<inbound domain> is ingested for the tenant.
+type InboundMailbox struct {
+	TenantID       uuid.UUID  `json:"tenant_id" db:"tenant_id"`
+	Token          string     `json:"token" db:"token"`
+	Enabled        bool       `json:"enabled" db:"enabled"`
+	DefaultOwnerID *uuid.UUID `json:"default_owner_id,omitempty" db:"default_owner_id"`
+	CreatedAt      time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at" db:"updated_at"`
+}
+
+// NewInboundMailbox creates a disabled mailbox with a fresh address.
+func NewInboundMailbox(tenantID uuid.UUID) (*InboundMailbox, error) {
+	token, err := newInboundMailboxToken()
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	return &InboundMailbox{
+		TenantID:  tenantID,
+		Token:     token,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}, nil
+}
+
+// Address returns the mailbox's forwarding address on the inbound domain.
+func (m *InboundMailbox) Address(inboundDomain string) string {
+	return m.Token + "@" + inboundDomain
+}
+
+// RotateAddress replaces the mailbox address, e.g. after it leaked to spammers.
+func (m *InboundMailbox) RotateAddress() error {
+	token, err := newInboundMailboxToken()
+	if err != nil {
+		return err
+	}
+	m.Token = token
+	m.UpdatedAt = time.Now().UTC()
+	return nil
+}
+
+// Configure enables or disables the mailbox and sets the owner of new leads.
+func (m *InboundMailbox) Configure(enabled bool, defaultOwnerID *uuid.UUID) {
+	m.Enabled = enabled
+	m.DefaultOwnerID = defaultOwnerID
+	m.UpdatedAt = time.Now().UTC()
+}
+
+// newInboundMailboxToken returns a lowercase token, since mail relays often
+// lowercase the local part of an address.
+func newInboundMailboxToken() (string, error) {
+	b := make([]byte, 12)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// InboundMailboxToken returns the mailbox token of an address on the inbound
+// domain, or false if the address is not on it.
+func InboundMailboxToken(address, inboundDomain string) (string, bool) {
+	local, host, ok := splitAddress(address)
+	if !ok || inboundDomain == "" || !strings.EqualFold(host, inboundDomain) {
+		return "", false
+	}
+	return strings.ToLower(local), true
+}
+
+// ============================================================================
+// Inbound Email Message
+// ============================================================================
+
+// InboundEmailMessage is a parsed inbound email. Headers holds the
+// loop-relevant headers under their canonical names.
+type InboundEmailMessage struct {
+	MessageID   string
+	FromAddress string
+	FromName    string
+	Recipients  []string
+	Subject     string
+	Body        string
+	Headers     map[string]string
+	Date        time.Time
+}
+
+// InboundEmailSkipReason explains why an inbound email was not ingested.
+type InboundEmailSkipReason string
+
+const (
+	InboundEmailSkipNoSender     InboundEmailSkipReason = "no_sender"
+	InboundEmailSkipBounce       InboundEmailSkipReason = "bounce"
+	InboundEmailSkipAutoReply    InboundEmailSkipReason = "auto_reply"
+	InboundEmailSkipBulk         InboundEmailSkipReason = "bulk"
+	InboundEmailSkipLoop         InboundEmailSkipReason = "loop"
+	InboundEmailSkipSystemSender InboundEmailSkipReason = "system_sender"
+	InboundEmailSkipDuplicate    InboundEmailSkipReason = "duplicate"
+	InboundEmailSkipRateLimited  InboundEmailSkipReason = "rate_limited"
+)
+
+// systemSenders are local parts of addresses that only send automated mail.
+var systemSenders = map[string]bool{
+	"mailer-daemon": true,
+	"postmaster":    true,
+	"noreply":       true,
+	"no-reply":      true,
+	"donotreply":    true,
+	"do-not-reply":  true,
+}
+
+// SkipReason reports why the email must not be ingested, or "" if it can be.
+// Bounces, auto-replies, bulk mail and mail from the inbound domain itself are
+// skipped so the CRM never answers or re-ingests automated mail in a loop.
+func (m *InboundEmailMessage) SkipReason(inboundDomain string) InboundEmailSkipReason {
+	local, host, ok := splitAddress(m.FromAddress)
+	if !ok {
+		return InboundEmailSkipNoSender
+	}
+	if strings.TrimSpace(m.Headers["Return-Path"]) == "<>" {
+		return InboundEmailSkipBounce
+	}
+	if autoSubmitted := strings.ToLower(strings.TrimSpace(m.Headers["Auto-Submitted"])); autoSubmitted != "" && autoSubmitted != "no" {
+		return InboundEmailSkipAutoReply
+	}
+	if m.Headers["X-Autoreply"] != "" || m.Headers["X-Autorespond"] != "" {
+		return InboundEmailSkipAutoReply
+	}
+	switch strings.ToLower(strings.TrimSpace(m.Headers["Precedence"])) {
+	case "bulk", "junk", "list", "auto_reply":
+		return InboundEmailSkipBulk
+	}
+	if inboundDomain != "" {
+		if strings.EqualFold(host, inboundDomain) || strings.Contains(strings.ToLower(m.Headers["X-Loop"]), strings.ToLower(inboundDomain)) {
+			return InboundEmailSkipLoop
+		}
+	}
+	if systemSenders[strings.ToLower(local)] {
+		return InboundEmailSkipSystemSender
+	}
+	return ""
+}
+
+// freeMailDomains are personal mailbox providers, which say nothing about the
+// sender's company.
+var freeMailDomains = map[string]bool{
+	"gmail.com":      true,
+	"googlemail.com": true,
+	"yahoo.com":      true,
+	"yahoo.com.my":   true,
+	"hotmail.com":    true,
+	"outlook.com":    true,
+	"live.com":       true,
+	"icloud.com":     true,
+	"me.com":         true,
+	"aol.com":        true,
+	"proton.me":      true,
+	"protonmail.com": true,
+}
+
+// SenderContact returns lead contact details for the sender. Without a
+// display name the local part of the address stands in for the name.
+func (m *InboundEmailMessage) SenderContact() LeadContact {
+	contact := LeadContact{Email: strings.ToLower(m.FromAddress)}
+
+	name := strings.TrimSpace(m.FromName)
+	if name == "" {
+		local, _, _ := splitAddress(m.FromAddress)
+		name = local
+	}
+	if first, last, ok := strings.Cut(name, " "); ok {
+		contact.FirstName, contact.LastName = first, strings.TrimSpace(last)
+	} else {
+		contact.FirstName = name
+	}
+	return contact
+}
+
+// SenderCompany returns lead company details for the sender, derived from
+// the sender's domain. Senders on personal mail providers are their own company.
+func (m *InboundEmailMessage) SenderCompany() LeadCompany {
+	_, host, _ := splitAddress(m.FromAddress)
+	host = strings.ToLower(host)
+	if host == "" || freeMailDomains[host] {
+		return LeadCompany{Name: m.SenderContact().FullName()}
+	}
+	return LeadCompany{Name: host, Website: "https://" + host}
+}
+
+// TruncatedBody returns the body cut to MaxInboundEmailBodyLength on a
+// character boundary.
+func (m *InboundEmailMessage) TruncatedBody() string {
+	if len(m.Body) <= MaxInboundEmailBodyLength {
+		return m.Body
+	}
+	body := m.Body[:MaxInboundEmailBodyLength]
+	for len(body) > 0 && !utf8.ValidString(body) {
+		body = body[:len(body)-1]
+	}
+	return body
+}
+
+// splitAddress splits an email address into its local part and domain.
+func splitAddress(address string) (local, host string, ok bool) {
+	at := strings.LastIndex(address, "@")
+	if at <= 0 || at == len(address)-1 {
+		return "", "", false
+	}
+	return address[:at], address[at+1:], true
+}
+
+// ============================================================================
+// Inbound Email
+// ============================================================================
+
+// InboundEmail is an ingested email, logged as an activity on the lead it was
+// matched to or created for, and on the opportunity of a converted lead.
+type InboundEmail struct {
+	ID            uuid.UUID  `json:"id"`
+	TenantID      uuid.UUID  `json:"tenant_id"`
+	MessageID     string     `json:"message_id"`
+	FromAddress   string     `json:"from_address"`
+	FromName      string     `json:"from_name,omitempty"`
+	Subject       string     `json:"subject"`
+	Body          string     `json:"body"`
+	LeadID        *uuid.UUID `json:"lead_id,omitempty"`
+	OpportunityID *uuid.UUID `json:"opportunity_id,omitempty"`
+	LeadCreated   bool       `json:"lead_created"`
+	AttachmentIDs []string   `json:"attachment_ids,omitempty"`
+	ReceivedAt    time.Time  `json:"received_at"`
+	CreatedAt     time.Time  `json:"created_at"`
+}
+
+// NewInboundEmail creates the activity record of an ingested email.
+func NewInboundEmail(tenantID uuid.UUID, msg *InboundEmailMessage) *InboundEmail {
+	now := time.Now().UTC()
+	receivedAt := msg.Date.UTC()
+	if msg.Date.IsZero() || msg.Date.After(now) {
+		receivedAt = now
+	}
+
+	return &InboundEmail{
+		ID:          uuid.New(),
+		TenantID:    tenantID,
+		MessageID:   msg.MessageID,
+		FromAddress: strings.ToLower(msg.FromAddress),
+		FromName:    msg.FromName,
+		Subject:     msg.Subject,
+		Body:        msg.TruncatedBody(),
+		ReceivedAt:  receivedAt,
+		CreatedAt:   now,
+	}
+}
@@ -0,0 +1,130 @@
+package domain
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestInboundMailboxToken(t *testing.T) {
+	tests := []struct {
+		address   string
+		wantToken string
+		wantOK    bool
+	}{
+		{"ab12cd@leads.kilang.my", "ab12cd", true},
+		{"AB12CD@Leads.Kilang.MY", "ab12cd", true},
+		{"sales@kilang.my", "", false},
+		{"not-an-address", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.address, func(t *testing.T) {
+			token, ok := InboundMailboxToken(tt.address, "leads.kilang.my")
+			if token != tt.wantToken || ok != tt.wantOK {
+				t.Errorf("InboundMailboxToken() = %q, %v, want %q, %v", token, ok, tt.wantToken, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestInboundMailbox_RotateAddress(t *testing.T) {
+	mailbox, err := NewInboundMailbox(uuid.New())
+	if err != nil {
+		t.Fatalf("NewInboundMailbox() error = %v", err)
+	}
+	if mailbox.Enabled {
+		t.Error("NewInboundMailbox() should start disabled")
+	}
+
+	old := mailbox.Address("leads.kilang.my")
+	if err := mailbox.RotateAddress(); err != nil {
+		t.Fatalf("RotateAddress() error = %v", err)
+	}
+	if mailbox.Address("leads.kilang.my") == old {
+		t.Error("RotateAddress() kept the old address")
+	}
+	if token, ok := InboundMailboxToken(mailbox.Address("leads.kilang.my"), "leads.kilang.my"); !ok || token != mailbox.Token {
+		t.Errorf("address %q does not resolve back to token %q", mailbox.Address("leads.kilang.my"), mailbox.Token)
+	}
+}
+
+func TestInboundEmailMessage_SkipReason(t *testing.T) {
+	tests := []struct {
+		name    string
+		from    string
+		headers map[string]string
+		want    InboundEmailSkipReason
+	}{
+		{"enquiry", "siti@butik.my", nil, ""},
+		{"explicit human reply", "siti@butik.my", map[string]string{"Auto-Submitted": "no"}, ""},
+		{"no sender", "", nil, InboundEmailSkipNoSender},
+		{"bounce", "siti@butik.my", map[string]string{"Return-Path": "<>"}, InboundEmailSkipBounce},
+		{"auto reply", "siti@butik.my", map[string]string{"Auto-Submitted": "auto-replied"}, InboundEmailSkipAutoReply},
+		{"vacation responder", "siti@butik.my", map[string]string{"X-Autoreply": "yes"}, InboundEmailSkipAutoReply},
+		{"newsletter", "news@butik.my", map[string]string{"Precedence": "bulk"}, InboundEmailSkipBulk},
+		{"from inbound domain", "ab12cd@leads.kilang.my", nil, InboundEmailSkipLoop},
+		{"x-loop", "siti@butik.my", map[string]string{"X-Loop": "ab12cd@leads.kilang.my"}, InboundEmailSkipLoop},
+		{"mailer daemon", "MAILER-DAEMON@mx.butik.my", nil, InboundEmailSkipSystemSender},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			msg := &InboundEmailMessage{FromAddress: tt.from, Headers: tt.headers}
+			if got := msg.SkipReason("leads.kilang.my"); got != tt.want {
+				t.Errorf("SkipReason() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestInboundEmailMessage_Sender(t *testing.T) {
+	tests := []struct {
+		name        string
+		from        string
+		fromName    string
+		wantFirst   string
+		wantLast    string
+		wantCompany string
+	}{
+		{"company sender", "Aminah@Butik-Sutera.my", "Aminah Binti Yusof", "Aminah", "Binti Yusof", "butik-sutera.my"},
+		{"personal mailbox", "lim.kw@gmail.com", "Lim", "Lim", "", "Lim"},
+		{"no display name", "orders@batikhaus.com", "", "orders", "", "batikhaus.com"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			msg := &InboundEmailMessage{FromAddress: tt.from, FromName: tt.fromName}
+
+			contact := msg.SenderContact()
+			if contact.FirstName != tt.wantFirst || contact.LastName != tt.wantLast || contact.Email != strings.ToLower(tt.from) {
+				t.Errorf("SenderContact() = %+v, want %q %q <%s>", contact, tt.wantFirst, tt.wantLast, strings.ToLower(tt.from))
+			}
+			if company := msg.SenderCompany(); company.Name != tt.wantCompany {
+				t.Errorf("SenderCompany() = %q, want %q", company.Name, tt.wantCompany)
+			}
+		})
+	}
+}
+
+func TestNewInboundEmail(t *testing.T) {
+	msg := &InboundEmailMessage{
+		MessageID:   "<1@butik.my>",
+		FromAddress: "Siti@Butik.my",
+		Body:        strings.Repeat("é", MaxInboundEmailBodyLength),
+		Date:        time.Now().Add(time.Hour),
+	}
+
+	email := NewInboundEmail(uuid.New(), msg)
+	if len(email.Body) > MaxInboundEmailBodyLength || !strings.HasPrefix(msg.Body, email.Body) {
+		t.Errorf("NewInboundEmail() body length = %d, want at most %d", len(email.Body), MaxInboundEmailBodyLength)
+	}
+	if email.FromAddress != "siti@butik.my" {
+		t.Errorf("NewInboundEmail() from = %q, want lowercased address", email.FromAddress)
+	}
+	if email.ReceivedAt.After(time.Now()) {
+		t.Error("NewInboundEmail() kept a date in the future")
+	}
+}
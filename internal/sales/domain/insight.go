@@ -0,0 +1,299 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Insight errors
+var (
+	ErrInvalidInsightCurrency = errors.New("insight currency must be a supported currency")
+	ErrInvalidQuarterlyQuota  = errors.New("quarterly quota cannot be negative")
+	ErrInvalidMinimumCoverage = errors.New("minimum pipeline coverage must be between 0 and 100")
+	ErrInvalidLeadDropPercent = errors.New("lead drop percentage must be between 1 and 100")
+	ErrInvalidMinimumLeads    = errors.New("minimum weekly leads cannot be negative")
+)
+
+// InsightType is a notable change in a tenant's sales the insights engine
+// alerts about.
+type InsightType string
+
+const (
+	// InsightTypeBiggestDealWon is raised when an opportunity is won for
+	// more than any other won in the quarter.
+	InsightTypeBiggestDealWon InsightType = "biggest_deal_won"
+	// InsightTypeLowPipelineCoverage is raised when the open pipeline
+	// expected to close in the quarter covers too little of the quota left.
+	InsightTypeLowPipelineCoverage InsightType = "low_pipeline_coverage"
+	// InsightTypeLeadVolumeDrop is raised when a week brings in markedly
+	// fewer leads than the week before.
+	InsightTypeLeadVolumeDrop InsightType = "lead_volume_drop"
+)
+
+// ValidInsightTypes returns all insight types.
+func ValidInsightTypes() []InsightType {
+	return []InsightType{
+		InsightTypeBiggestDealWon,
+		InsightTypeLowPipelineCoverage,
+		InsightTypeLeadVolumeDrop,
+	}
+}
+
+// Insight setting defaults
+const (
+	DefaultMinimumCoverage    = 2.0
+	DefaultLeadDropPercent    = 30.0
+	DefaultMinimumWeeklyLeads = 10
+)
+
+// InsightSettings holds a tenant's thresholds for insights. Amounts are in
+// minor units of Currency, which deals are compared in.
+type InsightSettings struct {
+	TenantID            uuid.UUID `json:"tenant_id" db:"tenant_id"`
+	BiggestDealWon      bool      `json:"biggest_deal_won" db:"biggest_deal_won"`
+	LowPipelineCoverage bool      `json:"low_pipeline_coverage" db:"low_pipeline_coverage"`
+	LeadVolumeDrop      bool      `json:"lead_volume_drop" db:"lead_volume_drop"`
+	Currency            string    `json:"currency" db:"currency"`
+	// QuarterlyQuota is the revenue target of a quarter; pipeline coverage
+	// is not checked while it is zero.
+	QuarterlyQuota int64 `json:"quarterly_quota" db:"quarterly_quota"`
+	// MinimumCoverage is the multiple of the remaining quota the pipeline
+	// should cover.
+	MinimumCoverage float64 `json:"minimum_coverage" db:"minimum_coverage"`
+	// LeadDropPercent is the week-over-week fall in new leads, in percent,
+	// that is alerted about.
+	LeadDropPercent float64 `json:"lead_drop_percent" db:"lead_drop_percent"`
+	// MinimumWeeklyLeads keeps tenants with few leads from being alerted
+	// about small swings: weeks after one with fewer leads are not compared.
+	MinimumWeeklyLeads int64      `json:"minimum_weekly_leads" db:"minimum_weekly_leads"`
+	UpdatedBy          *uuid.UUID `json:"updated_by,omitempty" db:"updated_by"`
+	UpdatedAt          time.Time  `json:"updated_at" db:"updated_at"`
+}
+
+// DefaultInsightSettings returns the settings used for tenants without a
+// configuration: every insight enabled, and no quota until one is set.
+func DefaultInsightSettings(tenantID uuid.UUID) *InsightSettings {
+	return &InsightSettings{
+		TenantID:            tenantID,
+		BiggestDealWon:      true,
+		LowPipelineCoverage: true,
+		LeadVolumeDrop:      true,
+		Currency:            "MYR",
+		MinimumCoverage:     DefaultMinimumCoverage,
+		LeadDropPercent:     DefaultLeadDropPercent,
+		MinimumWeeklyLeads:  DefaultMinimumWeeklyLeads,
+	}
+}
+
+// Validate validates the insight settings.
+func (s *InsightSettings) Validate() error {
+	if !IsSupportedCurrency(strings.ToUpper(s.Currency)) {
+		return ErrInvalidInsightCurrency
+	}
+	if s.QuarterlyQuota < 0 {
+		return ErrInvalidQuarterlyQuota
+	}
+	if s.MinimumCoverage <= 0 || s.MinimumCoverage > 100 {
+		return ErrInvalidMinimumCoverage
+	}
+	if s.LeadDropPercent < 1 || s.LeadDropPercent > 100 {
+		return ErrInvalidLeadDropPercent
+	}
+	if s.MinimumWeeklyLeads < 0 {
+		return ErrInvalidMinimumLeads
+	}
+	return nil
+}
+
+// IsEnabled reports whether the tenant is alerted about an insight type.
+func (s *InsightSettings) IsEnabled(insightType InsightType) bool {
+	switch insightType {
+	case InsightTypeBiggestDealWon:
+		return s.BiggestDealWon
+	case InsightTypeLowPipelineCoverage:
+		return s.LowPipelineCoverage && s.QuarterlyQuota > 0
+	case InsightTypeLeadVolumeDrop:
+		return s.LeadVolumeDrop
+	default:
+		return false
+	}
+}
+
+// Insight is a notable change detected in a tenant's sales. The tenant,
+// type and period key identify an insight, so each is raised once.
+type Insight struct {
+	ID         uuid.UUID      `json:"id" db:"id"`
+	TenantID   uuid.UUID      `json:"tenant_id" db:"tenant_id"`
+	Type       InsightType    `json:"type" db:"type"`
+	PeriodKey  string         `json:"period_key" db:"period_key"`
+	Details    InsightDetails `json:"details" db:"-"`
+	DetectedAt time.Time      `json:"detected_at" db:"detected_at"`
+}
+
+// InsightDetails holds the figures behind an insight; only those of its type
+// are set. Amounts are in minor units of Currency.
+type InsightDetails struct {
+	Currency string `json:"currency,omitempty"`
+
+	// Biggest deal won
+	OpportunityID   *uuid.UUID `json:"opportunity_id,omitempty"`
+	OpportunityCode string     `json:"opportunity_code,omitempty"`
+	OpportunityName string     `json:"opportunity_name,omitempty"`
+	OwnerID         *uuid.UUID `json:"owner_id,omitempty"`
+	OwnerName       string     `json:"owner_name,omitempty"`
+	Amount          int64      `json:"amount,omitempty"`
+	PreviousBest    int64      `json:"previous_best,omitempty"`
+
+	// Pipeline coverage
+	Quota           int64   `json:"quota,omitempty"`
+	WonAmount       int64   `json:"won_amount,omitempty"`
+	RemainingQuota  int64   `json:"remaining_quota,omitempty"`
+	OpenPipeline    int64   `json:"open_pipeline,omitempty"`
+	Coverage        float64 `json:"coverage,omitempty"`
+	MinimumCoverage float64 `json:"minimum_coverage,omitempty"`
+
+	// Lead volume
+	Leads         int64   `json:"leads,omitempty"`
+	PreviousLeads int64   `json:"previous_leads,omitempty"`
+	ChangePercent float64 `json:"change_percent,omitempty"`
+	Threshold     float64 `json:"threshold_percent,omitempty"`
+
+	// The quarter or week the insight covers, end exclusive
+	PeriodStart *time.Time `json:"period_start,omitempty"`
+	PeriodEnd   *time.Time `json:"period_end,omitempty"`
+}
+
+// newInsight creates an insight detected now.
+func newInsight(tenantID uuid.UUID, insightType InsightType, periodKey string, details InsightDetails, now time.Time) *Insight {
+	return &Insight{
+		ID:         uuid.New(),
+		TenantID:   tenantID,
+		Type:       insightType,
+		PeriodKey:  periodKey,
+		Details:    details,
+		DetectedAt: now.UTC(),
+	}
+}
+
+// QuarterOf returns the calendar quarter a time falls in.
+func QuarterOf(t time.Time) ReportPeriod {
+	t = t.UTC()
+	from := time.Date(t.Year(), time.Month((int(t.Month())-1)/3*3+1), 1, 0, 0, 0, 0, time.UTC)
+	return ReportPeriod{From: from, To: from.AddDate(0, 3, 0)}
+}
+
+// QuarterKey identifies the quarter a time falls in, such as 2026-Q4.
+func QuarterKey(t time.Time) string {
+	t = t.UTC()
+	return fmt.Sprintf("%d-Q%d", t.Year(), (int(t.Month())-1)/3+1)
+}
+
+// WeekOf returns the ISO week, Monday to Sunday, a time falls in.
+func WeekOf(t time.Time) ReportPeriod {
+	day := TruncateToDay(t)
+	from := day.AddDate(0, 0, -((int(day.Weekday()) + 6) % 7))
+	return ReportPeriod{From: from, To: from.AddDate(0, 0, 7)}
+}
+
+// WeekKey identifies the ISO week a time falls in, such as 2026-W42.
+func WeekKey(t time.Time) string {
+	year, week := t.UTC().ISOWeek()
+	return fmt.Sprintf("%d-W%02d", year, week)
+}
+
+// AmountIn returns the opportunity's amount in a currency: the amount itself,
+// or its base currency value. It reports false when neither is in currency.
+func (o *Opportunity) AmountIn(currency string) (int64, bool) {
+	if strings.EqualFold(o.Amount.Currency, currency) {
+		return o.Amount.Amount, true
+	}
+	if o.BaseAmount != nil && strings.EqualFold(o.BaseAmount.Currency, currency) {
+		return o.BaseAmount.Amount, true
+	}
+	return 0, false
+}
+
+// DetectBiggestDealWon raises an insight when a won opportunity beats the
+// biggest deal won earlier in its quarter. Opportunities without a value in
+// the settings currency are not compared.
+func DetectBiggestDealWon(settings *InsightSettings, opportunity *Opportunity, previousBest int64, now time.Time) *Insight {
+	if !settings.IsEnabled(InsightTypeBiggestDealWon) || opportunity.Status != OpportunityStatusWon {
+		return nil
+	}
+	amount, ok := opportunity.AmountIn(settings.Currency)
+	if !ok || amount <= 0 || amount <= previousBest {
+		return nil
+	}
+
+	closedAt := now
+	if opportunity.CloseInfo != nil {
+		closedAt = opportunity.CloseInfo.ClosedAt
+	}
+	opportunityID := opportunity.ID
+	ownerID := opportunity.OwnerID
+	return newInsight(opportunity.TenantID, InsightTypeBiggestDealWon, QuarterKey(closedAt)+":"+opportunity.ID.String(), InsightDetails{
+		Currency:        settings.Currency,
+		OpportunityID:   &opportunityID,
+		OpportunityCode: opportunity.Code,
+		OpportunityName: opportunity.Name,
+		OwnerID:         &ownerID,
+		OwnerName:       opportunity.OwnerName,
+		Amount:          amount,
+		PreviousBest:    previousBest,
+	}, now)
+}
+
+// DetectLowPipelineCoverage raises an insight when the open pipeline expected
+// to close in the current quarter covers less than the minimum multiple of
+// the quota still to win. It is raised at most once a week.
+func DetectLowPipelineCoverage(settings *InsightSettings, won, openPipeline int64, now time.Time) *Insight {
+	if !settings.IsEnabled(InsightTypeLowPipelineCoverage) {
+		return nil
+	}
+	remaining := settings.QuarterlyQuota - won
+	if remaining <= 0 {
+		return nil
+	}
+	coverage := float64(openPipeline) / float64(remaining)
+	if coverage >= settings.MinimumCoverage {
+		return nil
+	}
+
+	quarter := QuarterOf(now)
+	return newInsight(settings.TenantID, InsightTypeLowPipelineCoverage, QuarterKey(now)+":"+WeekKey(now), InsightDetails{
+		Currency:        settings.Currency,
+		Quota:           settings.QuarterlyQuota,
+		WonAmount:       won,
+		RemainingQuota:  remaining,
+		OpenPipeline:    openPipeline,
+		Coverage:        coverage,
+		MinimumCoverage: settings.MinimumCoverage,
+		PeriodStart:     &quarter.From,
+		PeriodEnd:       &quarter.To,
+	}, now)
+}
+
+// DetectLeadVolumeDrop raises an insight when a week brought in more than the
+// threshold percentage fewer leads than the week before it.
+func DetectLeadVolumeDrop(settings *InsightSettings, week ReportPeriod, leads, previousLeads int64, now time.Time) *Insight {
+	if !settings.IsEnabled(InsightTypeLeadVolumeDrop) || previousLeads == 0 || previousLeads < settings.MinimumWeeklyLeads {
+		return nil
+	}
+	change := float64(leads-previousLeads) / float64(previousLeads) * 100
+	if -change <= settings.LeadDropPercent {
+		return nil
+	}
+
+	return newInsight(settings.TenantID, InsightTypeLeadVolumeDrop, WeekKey(week.From), InsightDetails{
+		Leads:         leads,
+		PreviousLeads: previousLeads,
+		ChangePercent: change,
+		Threshold:     settings.LeadDropPercent,
+		PeriodStart:   &week.From,
+		PeriodEnd:     &week.To,
+	}, now)
+}
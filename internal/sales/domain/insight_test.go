@@ -0,0 +1,147 @@
+package domain
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestInsightPeriods(t *testing.T) {
+	// A Saturday in the fourth quarter
+	now := time.Date(2026, 10, 17, 15, 30, 0, 0, time.UTC)
+
+	quarter := QuarterOf(now)
+	if !quarter.From.Equal(time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)) || !quarter.To.Equal(time.Date(2027, 1, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("QuarterOf() = %v to %v, want October to January", quarter.From, quarter.To)
+	}
+	if key := QuarterKey(now); key != "2026-Q4" {
+		t.Errorf("QuarterKey() = %q, want 2026-Q4", key)
+	}
+
+	week := WeekOf(now)
+	if !week.From.Equal(time.Date(2026, 10, 12, 0, 0, 0, 0, time.UTC)) || !week.To.Equal(time.Date(2026, 10, 19, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("WeekOf() = %v to %v, want Monday 12 to Monday 19 October", week.From, week.To)
+	}
+	if key := WeekKey(now); key != "2026-W42" {
+		t.Errorf("WeekKey() = %q, want 2026-W42", key)
+	}
+}
+
+func TestInsightSettings_Validate(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(s *InsightSettings)
+		want   error
+	}{
+		{"defaults", func(s *InsightSettings) {}, nil},
+		{"unsupported currency", func(s *InsightSettings) { s.Currency = "XYZ" }, ErrInvalidInsightCurrency},
+		{"negative quota", func(s *InsightSettings) { s.QuarterlyQuota = -1 }, ErrInvalidQuarterlyQuota},
+		{"zero coverage", func(s *InsightSettings) { s.MinimumCoverage = 0 }, ErrInvalidMinimumCoverage},
+		{"lead drop above 100", func(s *InsightSettings) { s.LeadDropPercent = 120 }, ErrInvalidLeadDropPercent},
+		{"negative minimum leads", func(s *InsightSettings) { s.MinimumWeeklyLeads = -5 }, ErrInvalidMinimumLeads},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			settings := DefaultInsightSettings(uuid.New())
+			tt.modify(settings)
+			if err := settings.Validate(); err != tt.want {
+				t.Errorf("Validate() error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestDetectBiggestDealWon(t *testing.T) {
+	settings := DefaultInsightSettings(uuid.New())
+	closedAt := time.Date(2026, 8, 20, 10, 0, 0, 0, time.UTC)
+	opp := &Opportunity{
+		ID:        uuid.New(),
+		TenantID:  settings.TenantID,
+		Code:      "OPP-0042",
+		Status:    OpportunityStatusWon,
+		Amount:    Money{Amount: 5000000, Currency: "SGD"},
+		OwnerID:   uuid.New(),
+		CloseInfo: &CloseInfo{ClosedAt: closedAt},
+	}
+
+	if insight := DetectBiggestDealWon(settings, opp, 0, closedAt); insight != nil {
+		t.Error("DetectBiggestDealWon() raised an insight for a deal without a value in MYR")
+	}
+
+	opp.BaseAmount = &Money{Amount: 15000000, Currency: "MYR"}
+	if insight := DetectBiggestDealWon(settings, opp, 15000000, closedAt); insight != nil {
+		t.Error("DetectBiggestDealWon() raised an insight for a deal that only ties the best")
+	}
+
+	insight := DetectBiggestDealWon(settings, opp, 9000000, closedAt)
+	if insight == nil {
+		t.Fatal("DetectBiggestDealWon() = nil, want an insight for a new biggest deal")
+	}
+	if insight.PeriodKey != "2026-Q3:"+opp.ID.String() || insight.Details.Amount != 15000000 || insight.Details.PreviousBest != 9000000 {
+		t.Errorf("DetectBiggestDealWon() = key %q, amount %d, previous %d", insight.PeriodKey, insight.Details.Amount, insight.Details.PreviousBest)
+	}
+
+	settings.BiggestDealWon = false
+	if insight := DetectBiggestDealWon(settings, opp, 0, closedAt); insight != nil {
+		t.Error("DetectBiggestDealWon() raised an insight while disabled")
+	}
+}
+
+func TestDetectLowPipelineCoverage(t *testing.T) {
+	settings := DefaultInsightSettings(uuid.New())
+	now := time.Date(2026, 10, 17, 9, 0, 0, 0, time.UTC)
+
+	if insight := DetectLowPipelineCoverage(settings, 0, 0, now); insight != nil {
+		t.Error("DetectLowPipelineCoverage() raised an insight without a quota")
+	}
+
+	settings.QuarterlyQuota = 100000000
+	if insight := DetectLowPipelineCoverage(settings, 40000000, 120000000, now); insight != nil {
+		t.Error("DetectLowPipelineCoverage() raised an insight at 2x coverage")
+	}
+	if insight := DetectLowPipelineCoverage(settings, 100000000, 0, now); insight != nil {
+		t.Error("DetectLowPipelineCoverage() raised an insight with the quota met")
+	}
+
+	insight := DetectLowPipelineCoverage(settings, 40000000, 90000000, now)
+	if insight == nil {
+		t.Fatal("DetectLowPipelineCoverage() = nil, want an insight at 1.5x coverage")
+	}
+	if insight.PeriodKey != "2026-Q4:2026-W42" || insight.Details.RemainingQuota != 60000000 || insight.Details.Coverage != 1.5 {
+		t.Errorf("DetectLowPipelineCoverage() = key %q, remaining %d, coverage %.2f", insight.PeriodKey, insight.Details.RemainingQuota, insight.Details.Coverage)
+	}
+}
+
+func TestDetectLeadVolumeDrop(t *testing.T) {
+	settings := DefaultInsightSettings(uuid.New())
+	now := time.Date(2026, 10, 19, 6, 0, 0, 0, time.UTC)
+	week := WeekOf(time.Date(2026, 10, 12, 0, 0, 0, 0, time.UTC))
+
+	tests := []struct {
+		name          string
+		leads         int64
+		previousLeads int64
+		want          bool
+	}{
+		{"drop above threshold", 60, 100, true},
+		{"drop at threshold", 70, 100, false},
+		{"growth", 120, 100, false},
+		{"previous week below minimum", 0, 8, false},
+		{"no previous leads", 0, 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			insight := DetectLeadVolumeDrop(settings, week, tt.leads, tt.previousLeads, now)
+			if (insight != nil) != tt.want {
+				t.Fatalf("DetectLeadVolumeDrop(%d, %d) raised = %v, want %v", tt.leads, tt.previousLeads, insight != nil, tt.want)
+			}
+			if insight != nil && (insight.PeriodKey != "2026-W42" || math.Abs(insight.Details.ChangePercent+40) > 0.01) {
+				t.Errorf("DetectLeadVolumeDrop() = key %q, change %.2f; want 2026-W42, -40", insight.PeriodKey, insight.Details.ChangePercent)
+			}
+		})
+	}
+}
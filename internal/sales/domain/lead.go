@@ -205,6 +205,7 @@ type Lead struct {
 	Engagement      LeadEngagement         `json:"engagement" bson:"engagement"`
 	Campaign        string                 `json:"campaign,omitempty" bson:"campaign,omitempty"`
 	CampaignID      *uuid.UUID             `json:"campaign_id,omitempty" bson:"campaign_id,omitempty"`
+	Attribution     *Attribution           `json:"attribution,omitempty" bson:"attribution,omitempty"`
 	OwnerID         *uuid.UUID             `json:"owner_id,omitempty" bson:"owner_id,omitempty"`
 	OwnerName       string                 `json:"owner_name,omitempty" bson:"owner_name,omitempty"`
 	Tags            []string               `json:"tags,omitempty" bson:"tags,omitempty"`
@@ -219,6 +220,7 @@ type Lead struct {
 	CreatedAt       time.Time              `json:"created_at" bson:"created_at"`
 	UpdatedAt       time.Time              `json:"updated_at" bson:"updated_at"`
 	DeletedAt       *time.Time             `json:"deleted_at,omitempty" bson:"deleted_at,omitempty"`
+	MergedIntoID    *uuid.UUID             `json:"merged_into_id,omitempty" bson:"merged_into_id,omitempty"` // Lead a duplicate was merged into
 	Version         int                    `json:"version" bson:"version"`
 
 	// Domain events
@@ -297,10 +299,17 @@ func (l *Lead) Update(contact LeadContact, company LeadCompany, source LeadSourc
 	l.AddEvent(NewLeadUpdatedEvent(l))
 }
 
-// SetEstimatedValue sets the estimated deal value.
+// SetEstimatedValue sets the estimated deal value. A lead that has not been
+// saved yet carries the value in its pending created event.
 func (l *Lead) SetEstimatedValue(value Money) {
 	l.EstimatedValue = value
 	l.UpdatedAt = time.Now().UTC()
+	for _, event := range l.events {
+		if created, ok := event.(*LeadCreatedEvent); ok {
+			created.EstimatedValue = value.Amount
+			created.Currency = value.Currency
+		}
+	}
 }
 
 // AssignOwner assigns an owner to the lead.
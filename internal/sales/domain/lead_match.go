@@ -0,0 +1,333 @@
+package domain
+
+import (
+	"errors"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Lead merge errors
+var (
+	ErrLeadMergeNoSources   = errors.New("at least one lead to merge is required")
+	ErrLeadMergeIntoItself  = errors.New("a lead cannot be merged into itself")
+	ErrLeadMergeRepeated    = errors.New("a lead to merge is listed more than once")
+	ErrTooManyLeadsToMerge  = errors.New("too many leads to merge")
+	ErrLeadMergeTenantMixed = errors.New("leads to merge belong to another tenant")
+)
+
+// MaxLeadMergeSources is the number of duplicates merged into a lead at once.
+const MaxLeadMergeSources = 10
+
+// Fields leads and customers are matched on.
+const (
+	LeadMatchEmail   = "email"
+	LeadMatchPhone   = "phone"
+	LeadMatchCompany = "company"
+)
+
+// DefaultPhoneCountryCode is the country code assumed for phone numbers
+// written without one, as Malaysian numbers usually are.
+const DefaultPhoneCountryCode = "60"
+
+// companySuffixes are the legal form and filler words dropped from the end of
+// company names, so "Batik Murni Sdn. Bhd." matches "Batik Murni".
+var companySuffixes = map[string]bool{
+	"sdn": true, "bhd": true, "berhad": true, "plt": true, "llp": true,
+	"enterprise": true, "enterprises": true, "ent": true, "trading": true,
+	"pte": true, "ltd": true, "limited": true, "inc": true, "llc": true,
+	"co": true, "corp": true, "corporation": true, "company": true,
+}
+
+// NormalizeEmail returns the form emails are compared in.
+func NormalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
+
+// NormalizePhone returns a phone number in E.164 form, reading numbers
+// without a country code as Malaysian: "012-345 6789" becomes
+// "+60123456789". It returns "" for values too short or long to be a number.
+func NormalizePhone(phone string) string {
+	phone = strings.TrimSpace(phone)
+	international := strings.HasPrefix(phone, "+")
+	digits := strings.Map(func(r rune) rune {
+		if r >= '0' && r <= '9' {
+			return r
+		}
+		return -1
+	}, phone)
+
+	// No country code starts with 0, so "+012..." is a local number too
+	switch {
+	case international && !strings.HasPrefix(digits, "0"):
+	case strings.HasPrefix(digits, "00"):
+		digits = digits[2:]
+	case strings.HasPrefix(digits, "0"):
+		digits = DefaultPhoneCountryCode + digits[1:]
+	case !strings.HasPrefix(digits, DefaultPhoneCountryCode):
+		digits = DefaultPhoneCountryCode + digits
+	}
+	if len(digits) < 8 || len(digits) > 15 {
+		return ""
+	}
+	return "+" + digits
+}
+
+// NormalizeCompanyName returns the form company names are compared in: the
+// letters and digits of the name in lower case, without legal form suffixes.
+// Names with fewer than three such characters normalize to "", as they
+// match too much to be useful.
+func NormalizeCompanyName(name string) string {
+	name = strings.ReplaceAll(strings.ToLower(name), "&", " and ")
+	words := strings.FieldsFunc(name, func(r rune) bool {
+		return !(r >= 'a' && r <= 'z' || r >= '0' && r <= '9')
+	})
+	for len(words) > 1 && companySuffixes[words[len(words)-1]] {
+		words = words[:len(words)-1]
+	}
+	normalized := strings.Join(words, "")
+	if len(normalized) < 3 {
+		return ""
+	}
+	return normalized
+}
+
+// LeadMatchCriteria are the normalized contact details a new lead is matched
+// against existing leads and customers on.
+type LeadMatchCriteria struct {
+	Email   string
+	Phones  []string
+	Company string
+}
+
+// NewLeadMatchCriteria returns the criteria matching the contact and company
+// of a lead.
+func NewLeadMatchCriteria(contact LeadContact, company LeadCompany) LeadMatchCriteria {
+	criteria := LeadMatchCriteria{
+		Email:   NormalizeEmail(contact.Email),
+		Company: NormalizeCompanyName(company.Name),
+	}
+	for _, phone := range []string{contact.Phone, contact.Mobile} {
+		if normalized := NormalizePhone(phone); normalized != "" && !containsString(criteria.Phones, normalized) {
+			criteria.Phones = append(criteria.Phones, normalized)
+		}
+	}
+	return criteria
+}
+
+// IsEmpty reports whether there is nothing to match on.
+func (c LeadMatchCriteria) IsEmpty() bool {
+	return c.Email == "" && len(c.Phones) == 0 && c.Company == ""
+}
+
+// Match returns the fields a record with the given details matches the
+// criteria on, or nil when it does not match.
+func (c LeadMatchCriteria) Match(email string, phones []string, company string) []string {
+	var matched []string
+	if c.Email != "" && NormalizeEmail(email) == c.Email {
+		matched = append(matched, LeadMatchEmail)
+	}
+	for _, phone := range phones {
+		if normalized := NormalizePhone(phone); normalized != "" && containsString(c.Phones, normalized) {
+			matched = append(matched, LeadMatchPhone)
+			break
+		}
+	}
+	if c.Company != "" && NormalizeCompanyName(company) == c.Company {
+		matched = append(matched, LeadMatchCompany)
+	}
+	return matched
+}
+
+// MatchLead returns the fields a lead matches the criteria on.
+func (c LeadMatchCriteria) MatchLead(lead *Lead) []string {
+	return c.Match(lead.Contact.Email, []string{lead.Contact.Phone, lead.Contact.Mobile}, lead.Company.Name)
+}
+
+// IsOpen reports whether the lead is still being worked: neither converted,
+// disqualified, merged nor deleted. New leads are matched against open leads
+// only.
+func (l *Lead) IsOpen() bool {
+	return l.Status != LeadStatusConverted && l.Status != LeadStatusUnqualified &&
+		l.MergedIntoID == nil && !l.IsDeleted()
+}
+
+// Merge folds duplicates of the lead into it. The lead keeps its own details
+// and takes over what the duplicates add:
+//   - attribution is first touch: the source, campaign and UTM parameters
+//     of the lead created first
+//   - blank contact and company details, custom fields and the owner are
+//     filled in from the duplicates
+//   - tags are combined, engagement counts added up and the most recent
+//     engagement and contact kept
+//   - the best score is kept, with the score history of all the leads
+//   - notes are appended
+//
+// The duplicates are marked merged into the lead and deleted; the repository
+// moves their activities over when it stores the merge.
+func (l *Lead) Merge(sources []*Lead, mergedBy uuid.UUID) error {
+	if len(sources) == 0 {
+		return ErrLeadMergeNoSources
+	}
+	if len(sources) > MaxLeadMergeSources {
+		return ErrTooManyLeadsToMerge
+	}
+	if l.IsConverted() {
+		return ErrLeadAlreadyConverted
+	}
+	seen := map[uuid.UUID]bool{l.ID: true}
+	for _, source := range sources {
+		if source.ID == l.ID {
+			return ErrLeadMergeIntoItself
+		}
+		if source.TenantID != l.TenantID {
+			return ErrLeadMergeTenantMixed
+		}
+		if source.IsConverted() {
+			return ErrLeadAlreadyConverted
+		}
+		if seen[source.ID] {
+			return ErrLeadMergeRepeated
+		}
+		if source.IsDeleted() || source.MergedIntoID != nil {
+			return ErrLeadNotFound
+		}
+		seen[source.ID] = true
+	}
+
+	// Oldest first, so the first touch and older notes come first
+	ordered := make([]*Lead, len(sources))
+	copy(ordered, sources)
+	sort.SliceStable(ordered, func(i, j int) bool {
+		return ordered[i].CreatedAt.Before(ordered[j].CreatedAt)
+	})
+
+	now := time.Now().UTC()
+	firstTouch := l
+	mergedIDs := make([]uuid.UUID, 0, len(ordered))
+	for _, source := range ordered {
+		if source.CreatedAt.Before(firstTouch.CreatedAt) {
+			firstTouch = source
+		}
+		l.absorb(source)
+
+		source.MergedIntoID = &l.ID
+		source.DeletedAt = &now
+		source.UpdatedAt = now
+		source.AddEvent(NewLeadDeletedEvent(source))
+		mergedIDs = append(mergedIDs, source.ID)
+	}
+	if firstTouch != l {
+		l.Source = firstTouch.Source
+		l.Campaign = firstTouch.Campaign
+		l.CampaignID = firstTouch.CampaignID
+		l.Attribution = firstTouch.Attribution.Copy()
+	}
+	l.UpdatedAt = now
+
+	l.AddEvent(NewLeadMergedEvent(l, mergedIDs, firstTouch.ID, mergedBy))
+	return nil
+}
+
+// absorb takes over the details a duplicate adds to the lead.
+func (l *Lead) absorb(source *Lead) {
+	fillString(&l.Contact.LastName, source.Contact.LastName)
+	fillString(&l.Contact.Phone, source.Contact.Phone)
+	fillString(&l.Contact.Mobile, source.Contact.Mobile)
+	fillString(&l.Contact.JobTitle, source.Contact.JobTitle)
+	fillString(&l.Contact.Department, source.Contact.Department)
+	fillString(&l.Contact.LinkedIn, source.Contact.LinkedIn)
+	fillString(&l.Contact.Twitter, source.Contact.Twitter)
+	fillString(&l.Company.Website, source.Company.Website)
+	fillString(&l.Company.Industry, source.Company.Industry)
+	fillString(&l.Company.Size, source.Company.Size)
+	fillString(&l.Company.Revenue, source.Company.Revenue)
+	if l.Company.Address == "" && l.Company.City == "" {
+		l.Company.Address = source.Company.Address
+		l.Company.City = source.Company.City
+		l.Company.State = source.Company.State
+		l.Company.Country = source.Company.Country
+		l.Company.PostalCode = source.Company.PostalCode
+	}
+	fillString(&l.Description, source.Description)
+	if l.Attribution == nil {
+		l.Attribution = source.Attribution.Copy()
+	}
+
+	for key, value := range source.CustomFields {
+		if _, ok := l.CustomFields[key]; !ok {
+			if l.CustomFields == nil {
+				l.CustomFields = make(map[string]interface{})
+			}
+			l.CustomFields[key] = value
+		}
+	}
+	for _, tag := range source.Tags {
+		if !containsString(l.Tags, tag) {
+			l.Tags = append(l.Tags, tag)
+		}
+	}
+	if l.OwnerID == nil && source.OwnerID != nil {
+		l.OwnerID = source.OwnerID
+		l.OwnerName = source.OwnerName
+	}
+	if l.EstimatedValue.Amount == 0 && source.EstimatedValue.Amount > 0 {
+		l.EstimatedValue = source.EstimatedValue
+	}
+	if source.Notes != "" {
+		if l.Notes != "" {
+			l.Notes += "\n\n"
+		}
+		l.Notes += source.Notes
+	}
+
+	l.Engagement.EmailsOpened += source.Engagement.EmailsOpened
+	l.Engagement.EmailsClicked += source.Engagement.EmailsClicked
+	l.Engagement.WebVisits += source.Engagement.WebVisits
+	l.Engagement.FormSubmissions += source.Engagement.FormSubmissions
+	l.Engagement.LastEngagement = latest(l.Engagement.LastEngagement, source.Engagement.LastEngagement)
+	l.LastContactedAt = latest(l.LastContactedAt, source.LastContactedAt)
+	if source.NextFollowUp != nil && (l.NextFollowUp == nil || source.NextFollowUp.Before(*l.NextFollowUp)) {
+		l.NextFollowUp = source.NextFollowUp
+	}
+
+	if source.Score.Score > l.Score.Score {
+		history := l.Score.ScoreHistory
+		l.Score = source.Score
+		l.Score.ScoreHistory = history
+		l.Rating = source.Rating
+	}
+	l.Score.ScoreHistory = append(l.Score.ScoreHistory, source.Score.ScoreHistory...)
+	sort.SliceStable(l.Score.ScoreHistory, func(i, j int) bool {
+		return l.Score.ScoreHistory[i].Timestamp.Before(l.Score.ScoreHistory[j].Timestamp)
+	})
+	if len(l.Score.ScoreHistory) > 10 {
+		l.Score.ScoreHistory = l.Score.ScoreHistory[len(l.Score.ScoreHistory)-10:]
+	}
+}
+
+// fillString sets a blank value from another.
+func fillString(value *string, from string) {
+	if *value == "" {
+		*value = from
+	}
+}
+
+// latest returns the later of two times.
+func latest(a, b *time.Time) *time.Time {
+	if a == nil || (b != nil && b.After(*a)) {
+		return b
+	}
+	return a
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,192 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestNormalizePhone(t *testing.T) {
+	tests := []struct {
+		phone string
+		want  string
+	}{
+		{"012-345 6789", "+60123456789"},
+		{"+60 12-345 6789", "+60123456789"},
+		{"60123456789", "+60123456789"},
+		{"0060123456789", "+60123456789"},
+		{"+0123456789", "+60123456789"},
+		{"123456789", "+60123456789"},
+		{"+65 6123 4567", "+6561234567"},
+		{"123", ""},
+		{"", ""},
+	}
+
+	for _, tt := range tests {
+		if got := NormalizePhone(tt.phone); got != tt.want {
+			t.Errorf("NormalizePhone(%q) = %q, want %q", tt.phone, got, tt.want)
+		}
+	}
+}
+
+func TestNormalizeCompanyName(t *testing.T) {
+	tests := []struct {
+		name string
+		want string
+	}{
+		{"Batik Murni Sdn. Bhd.", "batikmurni"},
+		{"BATIK MURNI", "batikmurni"},
+		{"Batik-Murni Enterprise", "batikmurni"},
+		{"Kraf & Seni Trading", "krafandseni"},
+		{"Berhad", "berhad"},
+		{"AB Sdn Bhd", ""},
+		{"", ""},
+	}
+
+	for _, tt := range tests {
+		if got := NormalizeCompanyName(tt.name); got != tt.want {
+			t.Errorf("NormalizeCompanyName(%q) = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestLeadMatchCriteria_Match(t *testing.T) {
+	criteria := NewLeadMatchCriteria(
+		LeadContact{Email: " Aminah@Example.com ", Phone: "012-345 6789", Mobile: "+60123456789"},
+		LeadCompany{Name: "Batik Murni Sdn Bhd"},
+	)
+	if len(criteria.Phones) != 1 {
+		t.Fatalf("Phones = %v, want the number once", criteria.Phones)
+	}
+
+	tests := []struct {
+		name    string
+		email   string
+		phones  []string
+		company string
+		want    []string
+	}{
+		{"email", "aminah@example.com", nil, "", []string{LeadMatchEmail}},
+		{"phone", "other@example.com", []string{"", "0123456789"}, "", []string{LeadMatchPhone}},
+		{"company", "", nil, "Batik Murni", []string{LeadMatchCompany}},
+		{"all", "AMINAH@example.com", []string{"+60 12 345 6789"}, "batik murni bhd", []string{LeadMatchEmail, LeadMatchPhone, LeadMatchCompany}},
+		{"none", "other@example.com", []string{"0198765432"}, "Kraf Seni", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := criteria.Match(tt.email, tt.phones, tt.company)
+			if len(got) != len(tt.want) {
+				t.Fatalf("Match() = %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("Match() = %v, want %v", got, tt.want)
+				}
+			}
+		})
+	}
+
+	if !(LeadMatchCriteria{}).IsEmpty() {
+		t.Error("IsEmpty() = false for empty criteria")
+	}
+}
+
+func TestLead_IsOpen(t *testing.T) {
+	lead := createTestLead(t)
+	if !lead.IsOpen() {
+		t.Error("IsOpen() = false for a new lead")
+	}
+
+	lead.Status = LeadStatusUnqualified
+	if lead.IsOpen() {
+		t.Error("IsOpen() = true for a disqualified lead")
+	}
+
+	lead = createTestLead(t)
+	lead.MergedIntoID = &uuid.Nil
+	if lead.IsOpen() {
+		t.Error("IsOpen() = true for a merged lead")
+	}
+}
+
+func TestLead_Merge(t *testing.T) {
+	target := createTestLead(t)
+	target.Tags = []string{"batik"}
+	target.Engagement.WebVisits = 2
+	target.Score.Score = 40
+
+	source := createTestLead(t)
+	source.TenantID = target.TenantID
+	source.CreatedAt = target.CreatedAt.Add(-24 * time.Hour)
+	source.Source = LeadSourceTradeShow
+	source.Campaign = "Raya Expo"
+	source.Contact.Mobile = "0123456789"
+	source.Tags = []string{"batik", "wholesale"}
+	source.Engagement.WebVisits = 3
+	source.Score.Score = 70
+	source.Notes = "Met at the expo"
+	mergedBy := uuid.New()
+
+	if err := target.Merge([]*Lead{source}, mergedBy); err != nil {
+		t.Fatalf("Merge() error = %v", err)
+	}
+
+	if target.Source != LeadSourceTradeShow || target.Campaign != "Raya Expo" {
+		t.Errorf("Source = %s, Campaign = %q, want first touch attribution", target.Source, target.Campaign)
+	}
+	if target.Contact.Mobile != "0123456789" {
+		t.Errorf("Contact.Mobile = %q, want it filled in", target.Contact.Mobile)
+	}
+	if len(target.Tags) != 2 {
+		t.Errorf("Tags = %v, want batik and wholesale", target.Tags)
+	}
+	if target.Engagement.WebVisits != 5 {
+		t.Errorf("Engagement.WebVisits = %d, want 5", target.Engagement.WebVisits)
+	}
+	if target.Score.Score != 70 {
+		t.Errorf("Score = %d, want the best score 70", target.Score.Score)
+	}
+	if target.Notes != "Met at the expo" {
+		t.Errorf("Notes = %q", target.Notes)
+	}
+	if source.MergedIntoID == nil || *source.MergedIntoID != target.ID || !source.IsDeleted() {
+		t.Error("source not marked merged into the target")
+	}
+
+	events := target.GetEvents()
+	if len(events) != 1 || events[0].EventType() != "lead.merged" {
+		t.Fatalf("events = %v, want a lead merged event", events)
+	}
+	merged := events[0].(*LeadMergedEvent)
+	if merged.FirstTouchID != source.ID || merged.MergedBy != mergedBy {
+		t.Errorf("LeadMergedEvent = %+v", merged)
+	}
+}
+
+func TestLead_Merge_Invalid(t *testing.T) {
+	target := createTestLead(t)
+	other := createTestLead(t)
+	sameTenant := createTestLead(t)
+	sameTenant.TenantID = target.TenantID
+
+	tests := []struct {
+		name    string
+		sources []*Lead
+		want    error
+	}{
+		{"no sources", nil, ErrLeadMergeNoSources},
+		{"itself", []*Lead{target}, ErrLeadMergeIntoItself},
+		{"other tenant", []*Lead{other}, ErrLeadMergeTenantMixed},
+		{"repeated", []*Lead{sameTenant, sameTenant}, ErrLeadMergeRepeated},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := target.Merge(tt.sources, uuid.New()); err != tt.want {
+				t.Errorf("Merge() error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
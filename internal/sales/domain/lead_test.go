@@ -357,6 +357,25 @@ func TestLead_SetEstimatedValue(t *testing.T) {
 	}
 }
 
+func TestLead_SetEstimatedValue_NewLead(t *testing.T) {
+	lead, err := NewLead(uuid.New(), LeadContact{FirstName: "Siti", LastName: "Aminah", Email: "siti@example.com"},
+		LeadCompany{Name: "Butik Seri"}, LeadSourceWebsite, uuid.New())
+	if err != nil {
+		t.Fatalf("NewLead() error = %v", err)
+	}
+	value, _ := NewMoneyFromFloat(75000, "MYR")
+
+	lead.SetEstimatedValue(value)
+
+	created, ok := lead.GetEvents()[0].(*LeadCreatedEvent)
+	if !ok {
+		t.Fatalf("first event = %T, want *LeadCreatedEvent", lead.GetEvents()[0])
+	}
+	if created.EstimatedValue != value.Amount || created.Currency != "MYR" {
+		t.Errorf("LeadCreatedEvent value = %d %s, want %d MYR", created.EstimatedValue, created.Currency, value.Amount)
+	}
+}
+
 func TestLead_AssignOwner(t *testing.T) {
 	lead := createTestLead(t)
 	ownerID := uuid.New()
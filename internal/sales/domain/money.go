@@ -151,6 +151,32 @@ func (m Money) Multiply(factor float64) Money {
 	}
 }
 
+// ConvertTo converts the amount into another currency. The rate is the number
+// of target currency major units for one major unit of this currency.
+func (m Money) ConvertTo(currency string, rate float64) (Money, error) {
+	currency = strings.ToUpper(strings.TrimSpace(currency))
+	toDecimals, ok := currencyDecimals[currency]
+	if !ok {
+		return Money{}, ErrInvalidCurrency
+	}
+	if currency == m.Currency {
+		return m, nil
+	}
+	if !isValidRate(rate) {
+		return Money{}, ErrInvalidExchangeRate
+	}
+
+	converted := float64(m.Amount) * rate * math.Pow10(toDecimals-m.GetDecimals())
+	if converted >= math.MaxInt64 || converted <= math.MinInt64 {
+		return Money{}, ErrOverflow
+	}
+
+	return Money{
+		Amount:   int64(math.Round(converted)),
+		Currency: currency,
+	}, nil
+}
+
 // MultiplyInt multiplies by an integer factor.
 func (m Money) MultiplyInt(factor int64) (Money, error) {
 	// Check for overflow
@@ -18,6 +18,11 @@ var (
 	ErrInvalidStageTransition     = errors.New("invalid stage transition")
 	ErrOpportunityVersionMismatch = errors.New("opportunity version mismatch")
 	ErrCannotReopenAfterDays      = errors.New("cannot reopen opportunity after 30 days")
+	ErrInvalidContactRole         = errors.New("invalid contact role")
+	ErrContactAlreadyAttached     = errors.New("contact is already on the opportunity")
+	ErrContactNotAttached         = errors.New("contact is not on the opportunity")
+	ErrInvalidProbability         = errors.New("probability must be between 0 and 100")
+	ErrInvalidForecastCategory    = errors.New("invalid forecast category")
 )
 
 // OpportunityStatus represents the status of an opportunity.
@@ -63,18 +68,68 @@ const (
 	OpportunityPriorityCritical OpportunityPriority = "critical"
 )
 
+// ForecastCategory places an opportunity in the sales forecast.
+type ForecastCategory string
+
+const (
+	ForecastCategoryCommit   ForecastCategory = "commit"
+	ForecastCategoryBestCase ForecastCategory = "best_case"
+	ForecastCategoryPipeline ForecastCategory = "pipeline"
+	ForecastCategoryOmitted  ForecastCategory = "omitted"
+)
+
+// ValidForecastCategories returns all valid forecast categories.
+func ValidForecastCategories() []ForecastCategory {
+	return []ForecastCategory{
+		ForecastCategoryCommit,
+		ForecastCategoryBestCase,
+		ForecastCategoryPipeline,
+		ForecastCategoryOmitted,
+	}
+}
+
+// IsValid checks if the forecast category is valid.
+func (c ForecastCategory) IsValid() bool {
+	for _, valid := range ValidForecastCategories() {
+		if c == valid {
+			return true
+		}
+	}
+	return false
+}
+
+// ForecastCategoryForProbability returns the category of an opportunity with
+// no category set: commit from 75%, pipeline from 25%, and best case (the
+// upside) below that.
+func ForecastCategoryForProbability(probability int) ForecastCategory {
+	switch {
+	case probability >= 75:
+		return ForecastCategoryCommit
+	case probability >= 25:
+		return ForecastCategoryPipeline
+	default:
+		return ForecastCategoryBestCase
+	}
+}
+
 // OpportunityProduct represents a product/service in an opportunity.
 type OpportunityProduct struct {
-	ID          uuid.UUID `json:"id" bson:"id"`
-	ProductID   uuid.UUID `json:"product_id" bson:"product_id"`
-	ProductName string    `json:"product_name" bson:"product_name"`
-	SKU         string    `json:"sku,omitempty" bson:"sku,omitempty"`
-	Quantity    int       `json:"quantity" bson:"quantity"`
-	UnitPrice   Money     `json:"unit_price" bson:"unit_price"`
-	Discount    float64   `json:"discount" bson:"discount"` // Percentage
-	Tax         float64   `json:"tax" bson:"tax"`           // Percentage
-	TotalPrice  Money     `json:"total_price" bson:"total_price"`
-	Notes       string    `json:"notes,omitempty" bson:"notes,omitempty"`
+	ID           uuid.UUID `json:"id" bson:"id"`
+	ProductID    uuid.UUID `json:"product_id" bson:"product_id"`
+	ProductName  string    `json:"product_name" bson:"product_name"`
+	SKU          string    `json:"sku,omitempty" bson:"sku,omitempty"`
+	// Category is the catalogue category of the product when the line was priced
+	Category     string    `json:"category,omitempty" bson:"category,omitempty"`
+	Quantity     int       `json:"quantity" bson:"quantity"`
+	UnitPrice    Money     `json:"unit_price" bson:"unit_price"`
+	Discount     float64   `json:"discount" bson:"discount"` // Percentage
+	Tax          float64   `json:"tax" bson:"tax"`           // Percentage
+	TaxCode      string    `json:"tax_code,omitempty" bson:"tax_code,omitempty"`
+	TaxName      string    `json:"tax_name,omitempty" bson:"tax_name,omitempty"`
+	TaxInclusive bool      `json:"tax_inclusive" bson:"tax_inclusive"` // UnitPrice already includes tax
+	TaxAmount    Money     `json:"tax_amount" bson:"tax_amount"`
+	TotalPrice   Money     `json:"total_price" bson:"total_price"`
+	Notes        string    `json:"notes,omitempty" bson:"notes,omitempty"`
 }
 
 // CalculateTotalPrice calculates the total price for the product line.
@@ -82,11 +137,69 @@ func (p *OpportunityProduct) CalculateTotalPrice() {
 	basePrice := p.UnitPrice.Multiply(float64(p.Quantity))
 	discountAmount := basePrice.Multiply(p.Discount / 100)
 	afterDiscount, _ := basePrice.Subtract(discountAmount)
-	taxAmount := afterDiscount.Multiply(p.Tax / 100)
-	total, _ := afterDiscount.Add(taxAmount)
+	net, taxAmount := CalculateTax(afterDiscount, p.Tax, p.TaxInclusive)
+	total, _ := net.Add(taxAmount)
+	p.TaxAmount = taxAmount
 	p.TotalPrice = total
 }
 
+// ApplyTaxRate sets the tax charged on the product line. A nil rate removes tax.
+func (p *OpportunityProduct) ApplyTaxRate(rate *TaxRate, inclusive bool) {
+	p.TaxInclusive = inclusive
+	if rate == nil {
+		p.TaxCode = ""
+		p.TaxName = ""
+		p.Tax = 0
+	} else {
+		p.TaxCode = rate.Code
+		p.TaxName = rate.Name
+		p.Tax = rate.Rate
+	}
+	p.CalculateTotalPrice()
+}
+
+// ContactRole is the part a contact plays in an opportunity's buying decision.
+type ContactRole string
+
+const (
+	ContactRoleDecisionMaker    ContactRole = "decision_maker"
+	ContactRoleInfluencer       ContactRole = "influencer"
+	ContactRoleFinance          ContactRole = "finance"
+	ContactRoleEvaluator        ContactRole = "evaluator"
+	ContactRoleChampion         ContactRole = "champion"
+	ContactRoleBlocker          ContactRole = "blocker"
+	ContactRoleUser             ContactRole = "user"
+	ContactRoleTechnicalContact ContactRole = "technical_contact"
+	ContactRoleBillingContact   ContactRole = "billing_contact"
+	ContactRoleOther            ContactRole = "other"
+)
+
+// ValidContactRoles returns all valid contact roles.
+func ValidContactRoles() []ContactRole {
+	return []ContactRole{
+		ContactRoleDecisionMaker,
+		ContactRoleInfluencer,
+		ContactRoleFinance,
+		ContactRoleEvaluator,
+		ContactRoleChampion,
+		ContactRoleBlocker,
+		ContactRoleUser,
+		ContactRoleTechnicalContact,
+		ContactRoleBillingContact,
+		ContactRoleOther,
+	}
+}
+
+// IsValid checks if the contact role is valid.
+func (r ContactRole) IsValid() bool {
+	for _, valid := range ValidContactRoles() {
+		if r == valid {
+			return true
+		}
+	}
+	return false
+}
+
 // OpportunityContact represents a contact associated with an opportunity.
 type OpportunityContact struct {
 	ContactID   uuid.UUID `json:"contact_id" bson:"contact_id"`
@@ -94,8 +207,10 @@ type OpportunityContact struct {
 	Name        string    `json:"name" bson:"name"`
 	Email       string    `json:"email" bson:"email"`
 	Phone       string    `json:"phone,omitempty" bson:"phone,omitempty"`
-	Role        string    `json:"role" bson:"role"` // decision_maker, influencer, gatekeeper, etc.
+	Role        string    `json:"role" bson:"role"` // one of ValidContactRoles
 	IsPrimary   bool      `json:"is_primary" bson:"is_primary"`
+	Notes       string    `json:"notes,omitempty" bson:"notes,omitempty"`
+	AddedAt     time.Time `json:"added_at" bson:"added_at"`
 }
 
 // StageHistory represents a stage transition in the opportunity's history.
@@ -107,6 +222,8 @@ type StageHistory struct {
 	Duration    int        `json:"duration" bson:"duration"` // Duration in hours
 	MovedBy     uuid.UUID  `json:"moved_by" bson:"moved_by"`
 	Notes       string     `json:"notes,omitempty" bson:"notes,omitempty"`
+	// PipelineVersion is the pipeline version the stage belonged to when entered
+	PipelineVersion int    `json:"pipeline_version,omitempty" bson:"pipeline_version,omitempty"`
 }
 
 // CloseInfo contains information about closing an opportunity.
@@ -114,11 +231,29 @@ type CloseInfo struct {
 	ClosedAt     time.Time  `json:"closed_at" bson:"closed_at"`
 	ClosedBy     uuid.UUID  `json:"closed_by" bson:"closed_by"`
 	Reason       string     `json:"reason" bson:"reason"`
+	ReasonID     *uuid.UUID `json:"reason_id,omitempty" bson:"reason_id,omitempty"`
 	Notes        string     `json:"notes,omitempty" bson:"notes,omitempty"`
 	CompetitorID *uuid.UUID `json:"competitor_id,omitempty" bson:"competitor_id,omitempty"`
 	CompetitorName string   `json:"competitor_name,omitempty" bson:"competitor_name,omitempty"`
 }
 
+// ForecastState holds the values that place an opportunity in the forecast.
+type ForecastState struct {
+	Probability         int              `json:"probability"`
+	ProbabilityOverride *int             `json:"probability_override,omitempty"`
+	ForecastCategory    ForecastCategory `json:"forecast_category,omitempty"`
+}
+
+func (s ForecastState) equal(other ForecastState) bool {
+	if s.Probability != other.Probability || s.ForecastCategory != other.ForecastCategory {
+		return false
+	}
+	if s.ProbabilityOverride == nil || other.ProbabilityOverride == nil {
+		return s.ProbabilityOverride == other.ProbabilityOverride
+	}
+	return *s.ProbabilityOverride == *other.ProbabilityOverride
+}
+
 // Opportunity represents a sales opportunity in the pipeline.
 type Opportunity struct {
 	ID               uuid.UUID              `json:"id" bson:"_id"`
@@ -136,18 +271,30 @@ type Opportunity struct {
 	StageName        string                 `json:"stage_name" bson:"stage_name"`
 	StageEnteredAt   time.Time              `json:"stage_entered_at" bson:"stage_entered_at"`
 	StageHistory     []StageHistory         `json:"stage_history" bson:"stage_history"`
+	BoardPosition    string                 `json:"board_position" bson:"board_position"`
 
 	// Customer and Contacts
 	CustomerID       uuid.UUID              `json:"customer_id" bson:"customer_id"`
 	CustomerName     string                 `json:"customer_name" bson:"customer_name"`
+	// CustomerSegment is the customer's tier when the customer was set
+	CustomerSegment  string                 `json:"customer_segment,omitempty" bson:"customer_segment,omitempty"`
 	Contacts         []OpportunityContact   `json:"contacts" bson:"contacts"`
 
 	// Value
 	Amount           Money                  `json:"amount" bson:"amount"`
 	WeightedAmount   Money                  `json:"weighted_amount" bson:"weighted_amount"`
 	Probability      int                    `json:"probability" bson:"probability"` // 0-100
+	// ProbabilityOverride replaces the stage probability across stage moves until cleared
+	ProbabilityOverride *int                `json:"probability_override,omitempty" bson:"probability_override,omitempty"`
+	// ForecastCategory is derived from the probability when empty
+	ForecastCategory ForecastCategory       `json:"forecast_category,omitempty" bson:"forecast_category,omitempty"`
 	Products         []OpportunityProduct   `json:"products,omitempty" bson:"products,omitempty"`
 
+	// Base currency value, converted at ExchangeRate when the amount was set
+	BaseAmount         *Money  `json:"base_amount,omitempty" bson:"base_amount,omitempty"`
+	BaseWeightedAmount *Money  `json:"base_weighted_amount,omitempty" bson:"base_weighted_amount,omitempty"`
+	ExchangeRate       float64 `json:"exchange_rate,omitempty" bson:"exchange_rate,omitempty"`
+
 	// Timeline
 	ExpectedCloseDate *time.Time            `json:"expected_close_date,omitempty" bson:"expected_close_date,omitempty"`
 	ActualCloseDate   *time.Time            `json:"actual_close_date,omitempty" bson:"actual_close_date,omitempty"`
@@ -157,6 +304,7 @@ type Opportunity struct {
 	Source           string                 `json:"source,omitempty" bson:"source,omitempty"`
 	Campaign         string                 `json:"campaign,omitempty" bson:"campaign,omitempty"`
 	CampaignID       *uuid.UUID             `json:"campaign_id,omitempty" bson:"campaign_id,omitempty"`
+	Attribution      *Attribution           `json:"attribution,omitempty" bson:"attribution,omitempty"`
 
 	// Ownership
 	OwnerID          uuid.UUID              `json:"owner_id" bson:"owner_id"`
@@ -227,6 +375,7 @@ func NewOpportunity(
 		StageID:        firstStage.ID,
 		StageName:      firstStage.Name,
 		StageEnteredAt: now,
+		BoardPosition:  DefaultBoardPosition(now),
 		StageHistory: []StageHistory{
 			{
 				StageID:   firstStage.ID,
@@ -268,6 +417,12 @@ func NewOpportunityFromLead(
 		return nil, ErrLeadNotQualified
 	}
 
+	// An unassigned lead's opportunity is owned by whoever converts it
+	ownerID, ownerName := createdBy, ""
+	if lead.OwnerID != nil {
+		ownerID, ownerName = *lead.OwnerID, lead.OwnerName
+	}
+
 	opp, err := NewOpportunity(
 		lead.TenantID,
 		lead.Company.Name+" - "+lead.Contact.FullName(),
@@ -275,8 +430,8 @@ func NewOpportunityFromLead(
 		customerID,
 		customerName,
 		lead.EstimatedValue,
-		*lead.OwnerID,
-		lead.OwnerName,
+		ownerID,
+		ownerName,
 		createdBy,
 	)
 	if err != nil {
@@ -287,6 +442,7 @@ func NewOpportunityFromLead(
 	opp.Source = string(lead.Source)
 	opp.Campaign = lead.Campaign
 	opp.CampaignID = lead.CampaignID
+	opp.Attribution = lead.Attribution.Copy()
 
 	// Add lead contact as opportunity contact
 	opp.AddContact(OpportunityContact{
@@ -295,7 +451,7 @@ func NewOpportunityFromLead(
 		Name:       lead.Contact.FullName(),
 		Email:      lead.Contact.Email,
 		Phone:      lead.Contact.Phone,
-		Role:       "primary",
+		Role:       string(ContactRoleDecisionMaker),
 		IsPrimary:  true,
 	})
 
@@ -316,6 +472,9 @@ func (o *Opportunity) Update(name, description string, priority OpportunityPrior
 
 // SetAmount updates the opportunity amount.
 func (o *Opportunity) SetAmount(amount Money) {
+	if amount.Currency != o.Amount.Currency {
+		o.ClearExchangeRate()
+	}
 	o.Amount = amount
 	o.recalculateWeightedAmount()
 	o.UpdatedAt = time.Now().UTC()
@@ -326,6 +485,126 @@ func (o *Opportunity) SetAmount(amount Money) {
 // recalculateWeightedAmount recalculates the weighted amount.
 func (o *Opportunity) recalculateWeightedAmount() {
 	o.WeightedAmount = o.Amount.Multiply(float64(o.Probability) / 100)
+	o.recalculateBaseAmounts()
+}
+
+// SetProbabilityOverride sets the probability used in place of the stage
+// probability, or restores stageProbability, the probability of the current
+// stage, when override is nil.
+func (o *Opportunity) SetProbabilityOverride(override *int, stageProbability int, changedBy uuid.UUID) error {
+	if o.Status.IsClosed() {
+		return ErrOpportunityAlreadyClosed
+	}
+	if override != nil && (*override < 0 || *override > 100) {
+		return ErrInvalidProbability
+	}
+
+	previous := o.forecastState()
+	if override != nil {
+		value := *override
+		o.ProbabilityOverride = &value
+	} else {
+		o.ProbabilityOverride = nil
+	}
+	o.Probability = o.probabilityFor(stageProbability)
+	o.recalculateWeightedAmount()
+
+	o.recordForecastChange(previous, changedBy)
+	return nil
+}
+
+// SetForecastCategory sets the forecast category. An empty category derives
+// it from the probability again.
+func (o *Opportunity) SetForecastCategory(category ForecastCategory, changedBy uuid.UUID) error {
+	if o.Status.IsClosed() {
+		return ErrOpportunityAlreadyClosed
+	}
+	if category != "" && !category.IsValid() {
+		return ErrInvalidForecastCategory
+	}
+
+	previous := o.forecastState()
+	o.ForecastCategory = category
+
+	o.recordForecastChange(previous, changedBy)
+	return nil
+}
+
+// EffectiveForecastCategory returns the forecast category, derived from the
+// probability when none is set.
+func (o *Opportunity) EffectiveForecastCategory() ForecastCategory {
+	if o.ForecastCategory != "" {
+		return o.ForecastCategory
+	}
+	return ForecastCategoryForProbability(o.Probability)
+}
+
+// probabilityFor returns the probability of the opportunity in a stage with
+// the given probability, which an override replaces.
+func (o *Opportunity) probabilityFor(stageProbability int) int {
+	if o.ProbabilityOverride != nil {
+		return *o.ProbabilityOverride
+	}
+	return stageProbability
+}
+
+// forecastState captures the values that place the opportunity in the forecast.
+func (o *Opportunity) forecastState() ForecastState {
+	state := ForecastState{Probability: o.Probability, ForecastCategory: o.ForecastCategory}
+	if o.ProbabilityOverride != nil {
+		override := *o.ProbabilityOverride
+		state.ProbabilityOverride = &override
+	}
+	return state
+}
+
+// recordForecastChange raises a forecast changed event when the forecast
+// values differ from previous.
+func (o *Opportunity) recordForecastChange(previous ForecastState, changedBy uuid.UUID) {
+	current := o.forecastState()
+	if current.equal(previous) {
+		return
+	}
+	o.UpdatedAt = time.Now().UTC()
+	o.AddEvent(NewOpportunityForecastChangedEvent(o, previous, current, changedBy))
+}
+
+// ApplyExchangeRate stores the amounts converted into the base currency.
+func (o *Opportunity) ApplyExchangeRate(baseCurrency string, rate float64) error {
+	baseAmount, err := o.Amount.ConvertTo(baseCurrency, rate)
+	if err != nil {
+		return err
+	}
+	if baseAmount.Currency == o.Amount.Currency {
+		rate = 1
+	}
+
+	o.ExchangeRate = rate
+	o.BaseAmount = &baseAmount
+	o.recalculateBaseAmounts()
+	return nil
+}
+
+// ClearExchangeRate removes the base currency values.
+func (o *Opportunity) ClearExchangeRate() {
+	o.ExchangeRate = 0
+	o.BaseAmount = nil
+	o.BaseWeightedAmount = nil
+}
+
+// recalculateBaseAmounts keeps the base currency values in step with the amount.
+func (o *Opportunity) recalculateBaseAmounts() {
+	if o.BaseAmount == nil || o.ExchangeRate <= 0 {
+		return
+	}
+	baseAmount, err := o.Amount.ConvertTo(o.BaseAmount.Currency, o.ExchangeRate)
+	if err != nil {
+		o.ClearExchangeRate()
+		return
+	}
+	baseWeighted, _ := o.WeightedAmount.ConvertTo(baseAmount.Currency, o.ExchangeRate)
+	o.BaseAmount = &baseAmount
+	o.BaseWeightedAmount = &baseWeighted
 }
 
 // MoveToStage moves the opportunity to a new stage.
@@ -350,7 +629,8 @@ func (o *Opportunity) MoveToStage(newStage *Stage, movedBy uuid.UUID, notes stri
 	o.StageID = newStage.ID
 	o.StageName = newStage.Name
 	o.StageEnteredAt = now
-	o.Probability = newStage.Probability
+	o.BoardPosition = DefaultBoardPosition(now)
+	o.Probability = o.probabilityFor(newStage.Probability)
 	o.recalculateWeightedAmount()
 
 	// Add new stage history entry
@@ -368,6 +648,29 @@ func (o *Opportunity) MoveToStage(newStage *Stage, movedBy uuid.UUID, notes stri
 	return nil
 }
 
+// Reposition places the opportunity at a board position, moving it into the
+// stage first when the position is in another stage.
+func (o *Opportunity) Reposition(stage *Stage, position string, movedBy uuid.UUID) error {
+	if o.Status.IsClosed() {
+		return ErrOpportunityAlreadyClosed
+	}
+	if !IsValidBoardPosition(position) {
+		return ErrInvalidBoardPosition
+	}
+
+	if stage.ID != o.StageID {
+		if err := o.MoveToStage(stage, movedBy, ""); err != nil {
+			return err
+		}
+	}
+
+	o.BoardPosition = position
+	o.UpdatedAt = time.Now().UTC()
+
+	o.AddEvent(NewOpportunityPositionChangedEvent(o))
+	return nil
+}
+
 // Win marks the opportunity as won.
 func (o *Opportunity) Win(wonStage *Stage, reason, notes string, wonBy uuid.UUID) error {
 	if o.Status.IsClosed() {
@@ -453,7 +756,8 @@ func (o *Opportunity) Reopen(pipeline *Pipeline, reopenedBy uuid.UUID, notes str
 	o.StageID = firstStage.ID
 	o.StageName = firstStage.Name
 	o.StageEnteredAt = now
-	o.Probability = firstStage.Probability
+	o.BoardPosition = DefaultBoardPosition(now)
+	o.Probability = o.probabilityFor(firstStage.Probability)
 	o.recalculateWeightedAmount()
 	o.ActualCloseDate = nil
 	o.CloseInfo = nil
@@ -473,24 +777,77 @@ func (o *Opportunity) Reopen(pipeline *Pipeline, reopenedBy uuid.UUID, notes str
 	return nil
 }
 
-// AddContact adds a contact to the opportunity.
+// AddContact adds a contact to the opportunity. The first contact, or one
+// marked as primary, becomes the only primary contact.
 func (o *Opportunity) AddContact(contact OpportunityContact) {
-	// If this is the first contact or marked as primary, ensure only one primary
+	if len(o.Contacts) == 0 {
+		contact.IsPrimary = true
+	}
 	if contact.IsPrimary {
 		for i := range o.Contacts {
 			o.Contacts[i].IsPrimary = false
 		}
 	}
+	if contact.AddedAt.IsZero() {
+		contact.AddedAt = time.Now().UTC()
+	}
 
 	o.Contacts = append(o.Contacts, contact)
 	o.UpdatedAt = time.Now().UTC()
 }
 
-// RemoveContact removes a contact from the opportunity.
+// AttachContact adds a contact with a role, rejecting invalid roles and
+// contacts already on the opportunity.
+func (o *Opportunity) AttachContact(contact OpportunityContact) error {
+	if !ContactRole(contact.Role).IsValid() {
+		return ErrInvalidContactRole
+	}
+	if o.FindContact(contact.ContactID) != nil {
+		return ErrContactAlreadyAttached
+	}
+	o.AddContact(contact)
+	return nil
+}
+
+// UpdateContactRole changes the role of a contact, and its notes when notes
+// is not nil.
+func (o *Opportunity) UpdateContactRole(contactID uuid.UUID, role string, notes *string) error {
+	contact := o.FindContact(contactID)
+	if contact == nil {
+		return ErrContactNotAttached
+	}
+	if role != "" {
+		if !ContactRole(role).IsValid() {
+			return ErrInvalidContactRole
+		}
+		contact.Role = role
+	}
+	if notes != nil {
+		contact.Notes = *notes
+	}
+	o.UpdatedAt = time.Now().UTC()
+	return nil
+}
+
+// FindContact returns the contact on the opportunity with the given ID.
+func (o *Opportunity) FindContact(contactID uuid.UUID) *OpportunityContact {
+	for i := range o.Contacts {
+		if o.Contacts[i].ContactID == contactID {
+			return &o.Contacts[i]
+		}
+	}
+	return nil
+}
+
+// RemoveContact removes a contact from the opportunity. When the primary
+// contact is removed, the earliest remaining contact becomes primary.
 func (o *Opportunity) RemoveContact(contactID uuid.UUID) {
 	for i, c := range o.Contacts {
 		if c.ContactID == contactID {
 			o.Contacts = append(o.Contacts[:i], o.Contacts[i+1:]...)
+			if c.IsPrimary && len(o.Contacts) > 0 {
+				o.Contacts[0].IsPrimary = true
+			}
 			o.UpdatedAt = time.Now().UTC()
 			return
 		}
@@ -498,11 +855,15 @@ func (o *Opportunity) RemoveContact(contactID uuid.UUID) {
 }
 
 // SetPrimaryContact sets a contact as the primary contact.
-func (o *Opportunity) SetPrimaryContact(contactID uuid.UUID) {
+func (o *Opportunity) SetPrimaryContact(contactID uuid.UUID) error {
+	if o.FindContact(contactID) == nil {
+		return ErrContactNotAttached
+	}
 	for i := range o.Contacts {
 		o.Contacts[i].IsPrimary = o.Contacts[i].ContactID == contactID
 	}
 	o.UpdatedAt = time.Now().UTC()
+	return nil
 }
 
 // GetPrimaryContact returns the primary contact.
@@ -544,6 +905,19 @@ func (o *Opportunity) UpdateProduct(productID uuid.UUID, quantity int, unitPrice
 	return errors.New("product not found")
 }
 
+// ApplyProductTax sets the tax rate charged on a product in the opportunity.
+func (o *Opportunity) ApplyProductTax(productID uuid.UUID, rate *TaxRate, inclusive bool) error {
+	for i := range o.Products {
+		if o.Products[i].ID == productID {
+			o.Products[i].ApplyTaxRate(rate, inclusive)
+			o.recalculateAmountFromProducts()
+			o.UpdatedAt = time.Now().UTC()
+			return nil
+		}
+	}
+	return errors.New("product not found")
+}
+
 // RemoveProduct removes a product from the opportunity.
 func (o *Opportunity) RemoveProduct(productID uuid.UUID) {
 	for i, p := range o.Products {
@@ -556,9 +930,35 @@ func (o *Opportunity) RemoveProduct(productID uuid.UUID) {
 	}
 }
 
+// ReplaceProducts replaces all products of the opportunity.
+func (o *Opportunity) ReplaceProducts(products []OpportunityProduct) {
+	o.Products = make([]OpportunityProduct, 0, len(products))
+	for _, product := range products {
+		product.ID = uuid.New()
+		product.CalculateTotalPrice()
+		o.Products = append(o.Products, product)
+	}
+	o.recalculateAmountFromProducts()
+	o.UpdatedAt = time.Now().UTC()
+}
+
+// FindProduct returns the product line with the given line or product ID,
+// or nil if the opportunity has none.
+func (o *Opportunity) FindProduct(id uuid.UUID) *OpportunityProduct {
+	for i := range o.Products {
+		if o.Products[i].ID == id || o.Products[i].ProductID == id {
+			return &o.Products[i]
+		}
+	}
+	return nil
+}
+
 // recalculateAmountFromProducts recalculates the total amount from products.
+// Removing the last product leaves the opportunity without value.
 func (o *Opportunity) recalculateAmountFromProducts() {
 	if len(o.Products) == 0 {
+		o.Amount = Money{Amount: 0, Currency: o.Amount.Currency}
+		o.recalculateWeightedAmount()
 		return
 	}
 
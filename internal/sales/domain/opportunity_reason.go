@@ -0,0 +1,127 @@
+// Package domain contains the domain layer for the Sales Pipeline service.
+package domain
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Opportunity reason errors
+var (
+	ErrOpportunityReasonNotFound      = errors.New("opportunity reason not found")
+	ErrOpportunityReasonAlreadyExists = errors.New("opportunity reason with this name already exists")
+	ErrOpportunityReasonInactive      = errors.New("opportunity reason is inactive")
+	ErrInvalidOpportunityReasonType   = errors.New("invalid opportunity reason type")
+	ErrInvalidOpportunityReasonName   = errors.New("opportunity reason name is required")
+	ErrOpportunityReasonTypeMismatch  = errors.New("opportunity reason does not match the close outcome")
+)
+
+// ReasonType identifies the close outcome a reason explains.
+type ReasonType string
+
+const (
+	ReasonTypeWon  ReasonType = "won"
+	ReasonTypeLost ReasonType = "lost"
+)
+
+// IsValid checks if the reason type is valid.
+func (t ReasonType) IsValid() bool {
+	return t == ReasonTypeWon || t == ReasonTypeLost
+}
+
+// OpportunityReason is an entry of a tenant's catalog of win or loss reasons.
+// Reasons are deactivated rather than deleted so closed opportunities keep
+// pointing at them.
+type OpportunityReason struct {
+	ID          uuid.UUID  `json:"id" db:"id"`
+	TenantID    uuid.UUID  `json:"tenant_id" db:"tenant_id"`
+	Type        ReasonType `json:"type" db:"type"`
+	Name        string     `json:"name" db:"name"`
+	Description string     `json:"description,omitempty" db:"description"`
+	IsActive    bool       `json:"is_active" db:"is_active"`
+	Order       int        `json:"order" db:"sort_order"`
+	CreatedAt   time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at" db:"updated_at"`
+}
+
+// NewOpportunityReason creates a new active reason.
+func NewOpportunityReason(tenantID uuid.UUID, reasonType ReasonType, name, description string, order int) (*OpportunityReason, error) {
+	if !reasonType.IsValid() {
+		return nil, ErrInvalidOpportunityReasonType
+	}
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return nil, ErrInvalidOpportunityReasonName
+	}
+
+	now := time.Now().UTC()
+	return &OpportunityReason{
+		ID:          uuid.New(),
+		TenantID:    tenantID,
+		Type:        reasonType,
+		Name:        name,
+		Description: strings.TrimSpace(description),
+		IsActive:    true,
+		Order:       order,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}, nil
+}
+
+// Update updates the reason's name, description and order.
+func (r *OpportunityReason) Update(name, description string, order int) error {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return ErrInvalidOpportunityReasonName
+	}
+
+	r.Name = name
+	r.Description = strings.TrimSpace(description)
+	r.Order = order
+	r.UpdatedAt = time.Now().UTC()
+	return nil
+}
+
+// Activate makes the reason selectable again.
+func (r *OpportunityReason) Activate() {
+	r.IsActive = true
+	r.UpdatedAt = time.Now().UTC()
+}
+
+// Deactivate hides the reason from selection while keeping it on closed opportunities.
+func (r *OpportunityReason) Deactivate() {
+	r.IsActive = false
+	r.UpdatedAt = time.Now().UTC()
+}
+
+// CanCloseAs checks that the reason can be selected for the given close outcome.
+func (r *OpportunityReason) CanCloseAs(reasonType ReasonType) error {
+	if r.Type != reasonType {
+		return ErrOpportunityReasonTypeMismatch
+	}
+	if !r.IsActive {
+		return ErrOpportunityReasonInactive
+	}
+	return nil
+}
+
+// CloseBreakdownEntry aggregates closed opportunities sharing a reason or competitor.
+// ReasonID and CompetitorID are nil for free-text reasons and unnamed competitors.
+type CloseBreakdownEntry struct {
+	ReasonID       *uuid.UUID `db:"reason_id"`
+	Reason         string     `db:"reason"`
+	CompetitorID   *uuid.UUID `db:"competitor_id"`
+	CompetitorName string     `db:"competitor_name"`
+	Count          int64      `db:"count"`
+	Amount         int64      `db:"amount"`
+	Currency       string     `db:"currency"`
+}
+
+// CloseBreakdown holds the closed opportunities of one outcome grouped by reason and competitor.
+type CloseBreakdown struct {
+	ByReason     []*CloseBreakdownEntry
+	ByCompetitor []*CloseBreakdownEntry
+}
@@ -0,0 +1,59 @@
+// Package domain contains the domain layer for the Sales Pipeline service.
+package domain
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestNewOpportunityReason(t *testing.T) {
+	tests := []struct {
+		name       string
+		reasonType ReasonType
+		reason     string
+		wantErr    error
+	}{
+		{"valid won", ReasonTypeWon, " Best quality ", nil},
+		{"valid lost", ReasonTypeLost, "Price too high", nil},
+		{"invalid type", ReasonType("pending"), "Other", ErrInvalidOpportunityReasonType},
+		{"blank name", ReasonTypeLost, "   ", ErrInvalidOpportunityReasonName},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reason, err := NewOpportunityReason(uuid.New(), tt.reasonType, tt.reason, "", 0)
+
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Errorf("NewOpportunityReason() error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("NewOpportunityReason() error = %v", err)
+			}
+			if !reason.IsActive || reason.Name != strings.TrimSpace(tt.reason) {
+				t.Errorf("NewOpportunityReason() = %+v, want an active reason named %q", reason, strings.TrimSpace(tt.reason))
+			}
+		})
+	}
+}
+
+func TestOpportunityReason_CanCloseAs(t *testing.T) {
+	reason, _ := NewOpportunityReason(uuid.New(), ReasonTypeLost, "No budget", "", 0)
+
+	if err := reason.CanCloseAs(ReasonTypeLost); err != nil {
+		t.Errorf("CanCloseAs(lost) error = %v, want nil", err)
+	}
+	if err := reason.CanCloseAs(ReasonTypeWon); !errors.Is(err, ErrOpportunityReasonTypeMismatch) {
+		t.Errorf("CanCloseAs(won) error = %v, want %v", err, ErrOpportunityReasonTypeMismatch)
+	}
+
+	reason.Deactivate()
+	if err := reason.CanCloseAs(ReasonTypeLost); !errors.Is(err, ErrOpportunityReasonInactive) {
+		t.Errorf("CanCloseAs() inactive error = %v, want %v", err, ErrOpportunityReasonInactive)
+	}
+}
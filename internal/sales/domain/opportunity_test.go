@@ -505,6 +505,71 @@ func TestOpportunity_GetPrimaryContact_Empty(t *testing.T) {
 	}
 }
 
+func TestOpportunity_AttachContact(t *testing.T) {
+	opp, _ := createTestOpportunity(t)
+	finance := OpportunityContact{ContactID: uuid.New(), Role: string(ContactRoleFinance)}
+	champion := OpportunityContact{ContactID: uuid.New(), Role: string(ContactRoleChampion)}
+
+	if err := opp.AttachContact(finance); err != nil {
+		t.Fatalf("AttachContact() error = %v", err)
+	}
+	if err := opp.AttachContact(champion); err != nil {
+		t.Fatalf("AttachContact() error = %v", err)
+	}
+	if primary := opp.GetPrimaryContact(); primary.ContactID != finance.ContactID {
+		t.Error("the first contact should become primary")
+	}
+	if opp.Contacts[0].AddedAt.IsZero() {
+		t.Error("AttachContact() should record when the contact was added")
+	}
+
+	if err := opp.AttachContact(finance); err != ErrContactAlreadyAttached {
+		t.Errorf("AttachContact(duplicate) error = %v, want %v", err, ErrContactAlreadyAttached)
+	}
+	if err := opp.AttachContact(OpportunityContact{ContactID: uuid.New(), Role: "gatekeeper"}); err != ErrInvalidContactRole {
+		t.Errorf("AttachContact(invalid role) error = %v, want %v", err, ErrInvalidContactRole)
+	}
+}
+
+func TestOpportunity_UpdateContactRole(t *testing.T) {
+	opp, _ := createTestOpportunity(t)
+	contact := OpportunityContact{ContactID: uuid.New(), Role: string(ContactRoleInfluencer)}
+	_ = opp.AttachContact(contact)
+
+	notes := "Signs off purchases above RM 50,000"
+	if err := opp.UpdateContactRole(contact.ContactID, string(ContactRoleDecisionMaker), &notes); err != nil {
+		t.Fatalf("UpdateContactRole() error = %v", err)
+	}
+	updated := opp.FindContact(contact.ContactID)
+	if updated.Role != string(ContactRoleDecisionMaker) || updated.Notes != notes {
+		t.Errorf("contact = %+v, want decision maker with notes", updated)
+	}
+
+	if err := opp.UpdateContactRole(contact.ContactID, "owner", nil); err != ErrInvalidContactRole {
+		t.Errorf("UpdateContactRole(invalid role) error = %v, want %v", err, ErrInvalidContactRole)
+	}
+	if err := opp.UpdateContactRole(uuid.New(), string(ContactRoleUser), nil); err != ErrContactNotAttached {
+		t.Errorf("UpdateContactRole(unknown contact) error = %v, want %v", err, ErrContactNotAttached)
+	}
+	if err := opp.SetPrimaryContact(uuid.New()); err != ErrContactNotAttached {
+		t.Errorf("SetPrimaryContact(unknown contact) error = %v, want %v", err, ErrContactNotAttached)
+	}
+}
+
+func TestOpportunity_RemoveContact_PromotesPrimary(t *testing.T) {
+	opp, _ := createTestOpportunity(t)
+	first := OpportunityContact{ContactID: uuid.New(), Role: string(ContactRoleDecisionMaker)}
+	second := OpportunityContact{ContactID: uuid.New(), Role: string(ContactRoleFinance)}
+	_ = opp.AttachContact(first)
+	_ = opp.AttachContact(second)
+
+	opp.RemoveContact(first.ContactID)
+
+	if primary := opp.GetPrimaryContact(); primary == nil || primary.ContactID != second.ContactID || !primary.IsPrimary {
+		t.Error("removing the primary contact should promote the remaining contact")
+	}
+}
+
 func TestOpportunity_Products(t *testing.T) {
 	opp, _ := createTestOpportunity(t)
 	unitPrice, _ := NewMoneyFromFloat(100, "USD")
@@ -551,6 +616,125 @@ func TestOpportunity_Products(t *testing.T) {
 	}
 }
 
+func TestOpportunity_ReplaceProducts(t *testing.T) {
+	opp, _ := createTestOpportunity(t)
+	opp.Probability = 50
+	unitPrice, _ := NewMoneyFromFloat(100, "USD")
+	productID := uuid.New()
+
+	opp.ReplaceProducts([]OpportunityProduct{
+		{ProductID: productID, ProductName: "Product A", Quantity: 2, UnitPrice: unitPrice, Discount: 10},
+		{ProductID: uuid.New(), ProductName: "Product B", Quantity: 1, UnitPrice: unitPrice},
+	})
+	if len(opp.Products) != 2 || opp.Products[0].ID == uuid.Nil {
+		t.Fatalf("Opportunity.ReplaceProducts() products = %+v, want 2 lines with IDs", opp.Products)
+	}
+	// 2 x 100 less 10%, plus 100
+	if opp.Amount.Amount != 28000 || opp.WeightedAmount.Amount != 14000 {
+		t.Errorf("Opportunity.ReplaceProducts() amount = %d, weighted = %d, want 28000 and 14000", opp.Amount.Amount, opp.WeightedAmount.Amount)
+	}
+	if line := opp.FindProduct(productID); line == nil || line.ProductName != "Product A" {
+		t.Errorf("Opportunity.FindProduct() by product ID = %+v, want Product A", line)
+	}
+
+	// Removing every product leaves no value
+	opp.ReplaceProducts(nil)
+	if len(opp.Products) != 0 || opp.Amount.Amount != 0 || opp.WeightedAmount.Amount != 0 {
+		t.Errorf("Opportunity.ReplaceProducts(nil) amount = %d, want 0", opp.Amount.Amount)
+	}
+}
+
+func TestOpportunity_SetProbabilityOverride(t *testing.T) {
+	opp, pipeline := createTestOpportunity(t)
+	stageProbability := opp.Probability
+	changedBy := uuid.New()
+
+	override := 80
+	if err := opp.SetProbabilityOverride(&override, stageProbability, changedBy); err != nil {
+		t.Fatalf("Opportunity.SetProbabilityOverride() unexpected error = %v", err)
+	}
+	if opp.Probability != 80 || opp.WeightedAmount.Amount != opp.Amount.Amount*80/100 {
+		t.Errorf("Opportunity.SetProbabilityOverride() probability = %d, weighted = %d", opp.Probability, opp.WeightedAmount.Amount)
+	}
+	events := opp.GetEvents()
+	if len(events) != 1 {
+		t.Fatalf("Opportunity.SetProbabilityOverride() events = %d, want 1", len(events))
+	}
+	changed, ok := events[0].(*OpportunityForecastChangedEvent)
+	if !ok || changed.Previous.Probability != stageProbability || changed.Current.Probability != 80 || changed.ChangedBy != changedBy {
+		t.Errorf("Opportunity.SetProbabilityOverride() event = %+v", events[0])
+	}
+
+	// The override holds across stage moves
+	for _, stage := range pipeline.GetActiveStages() {
+		if stage.ID != opp.StageID && !stage.Type.IsClosedType() {
+			if err := opp.MoveToStage(stage, changedBy, ""); err != nil {
+				t.Fatalf("Opportunity.MoveToStage() unexpected error = %v", err)
+			}
+			stageProbability = stage.Probability
+			break
+		}
+	}
+	if opp.Probability != 80 {
+		t.Errorf("Opportunity.MoveToStage() probability = %d, want the override 80", opp.Probability)
+	}
+
+	// Setting the same override again records nothing
+	opp.ClearEvents()
+	_ = opp.SetProbabilityOverride(&override, stageProbability, changedBy)
+	if len(opp.GetEvents()) != 0 {
+		t.Errorf("Opportunity.SetProbabilityOverride() unchanged events = %d, want 0", len(opp.GetEvents()))
+	}
+
+	// Clearing it restores the stage probability
+	if err := opp.SetProbabilityOverride(nil, stageProbability, changedBy); err != nil {
+		t.Fatalf("Opportunity.SetProbabilityOverride(nil) unexpected error = %v", err)
+	}
+	if opp.ProbabilityOverride != nil || opp.Probability != stageProbability {
+		t.Errorf("Opportunity.SetProbabilityOverride(nil) probability = %d, want %d", opp.Probability, stageProbability)
+	}
+
+	invalid := 101
+	if err := opp.SetProbabilityOverride(&invalid, stageProbability, changedBy); err != ErrInvalidProbability {
+		t.Errorf("Opportunity.SetProbabilityOverride(101) error = %v, want %v", err, ErrInvalidProbability)
+	}
+}
+
+func TestOpportunity_SetForecastCategory(t *testing.T) {
+	opp, _ := createTestOpportunity(t)
+	opp.Probability = 80
+
+	if got := opp.EffectiveForecastCategory(); got != ForecastCategoryCommit {
+		t.Errorf("Opportunity.EffectiveForecastCategory() = %v, want %v", got, ForecastCategoryCommit)
+	}
+
+	if err := opp.SetForecastCategory(ForecastCategoryOmitted, uuid.New()); err != nil {
+		t.Fatalf("Opportunity.SetForecastCategory() unexpected error = %v", err)
+	}
+	if got := opp.EffectiveForecastCategory(); got != ForecastCategoryOmitted {
+		t.Errorf("Opportunity.EffectiveForecastCategory() = %v, want %v", got, ForecastCategoryOmitted)
+	}
+	if len(opp.GetEvents()) != 1 {
+		t.Errorf("Opportunity.SetForecastCategory() events = %d, want 1", len(opp.GetEvents()))
+	}
+
+	if err := opp.SetForecastCategory("maybe", uuid.New()); err != ErrInvalidForecastCategory {
+		t.Errorf("Opportunity.SetForecastCategory(maybe) error = %v, want %v", err, ErrInvalidForecastCategory)
+	}
+
+	// An empty category derives it from the probability again
+	_ = opp.SetForecastCategory("", uuid.New())
+	opp.Probability = 10
+	if got := opp.EffectiveForecastCategory(); got != ForecastCategoryBestCase {
+		t.Errorf("Opportunity.EffectiveForecastCategory() = %v, want %v", got, ForecastCategoryBestCase)
+	}
+
+	opp.Status = OpportunityStatusWon
+	if err := opp.SetForecastCategory(ForecastCategoryCommit, uuid.New()); err != ErrOpportunityAlreadyClosed {
+		t.Errorf("Opportunity.SetForecastCategory() on closed error = %v, want %v", err, ErrOpportunityAlreadyClosed)
+	}
+}
+
 func TestOpportunity_AssignOwner(t *testing.T) {
 	opp, _ := createTestOpportunity(t)
 	opp.ClearEvents()
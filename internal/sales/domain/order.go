@@ -0,0 +1,329 @@
+// Package domain contains the domain layer for the Sales Pipeline service.
+package domain
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Order errors
+var (
+	ErrOrderNotFound                = errors.New("order not found")
+	ErrOrderAlreadyExists           = errors.New("deal already has an open order")
+	ErrOrderDealCancelled           = errors.New("cannot create an order for a cancelled deal")
+	ErrOrderNoItems                 = errors.New("order has no items to produce")
+	ErrInvalidOrderStatus           = errors.New("invalid order status")
+	ErrInvalidOrderStatusTransition = errors.New("invalid order status transition")
+	ErrInvalidOrderWebhookURL       = errors.New("order webhook URL must be an absolute http or https URL")
+)
+
+// OrderStatus represents the production status of an order.
+type OrderStatus string
+
+const (
+	OrderStatusPending      OrderStatus = "pending"
+	OrderStatusInProduction OrderStatus = "in_production"
+	OrderStatusShipped      OrderStatus = "shipped"
+	OrderStatusDelivered    OrderStatus = "delivered"
+	OrderStatusCancelled    OrderStatus = "cancelled"
+)
+
+// orderTransitions lists the statuses each status can move to. Orders move
+// forward only; an order can be cancelled until it is shipped.
+var orderTransitions = map[OrderStatus][]OrderStatus{
+	OrderStatusPending:      {OrderStatusInProduction, OrderStatusCancelled},
+	OrderStatusInProduction: {OrderStatusShipped, OrderStatusCancelled},
+	OrderStatusShipped:      {OrderStatusDelivered},
+}
+
+// IsValid checks if the order status is valid.
+func (s OrderStatus) IsValid() bool {
+	switch s {
+	case OrderStatusPending, OrderStatusInProduction, OrderStatusShipped, OrderStatusDelivered, OrderStatusCancelled:
+		return true
+	}
+	return false
+}
+
+// IsClosed returns true if the order can no longer change.
+func (s OrderStatus) IsClosed() bool {
+	return s == OrderStatusDelivered || s == OrderStatusCancelled
+}
+
+// CanTransitionTo returns true if an order can move from s to status.
+func (s OrderStatus) CanTransitionTo(status OrderStatus) bool {
+	for _, next := range orderTransitions[s] {
+		if next == status {
+			return true
+		}
+	}
+	return false
+}
+
+// OrderItem is a product to produce for an order.
+type OrderItem struct {
+	ID             uuid.UUID  `json:"id"`
+	DealLineItemID uuid.UUID  `json:"deal_line_item_id"`
+	ProductID      uuid.UUID  `json:"product_id"`
+	ProductName    string     `json:"product_name"`
+	ProductSKU     string     `json:"product_sku,omitempty"`
+	Description    string     `json:"description,omitempty"`
+	Quantity       int        `json:"quantity"`
+	DeliveryDate   *time.Time `json:"delivery_date,omitempty"`
+	Notes          string     `json:"notes,omitempty"`
+}
+
+// OrderStatusChange records a change of an order's status.
+type OrderStatusChange struct {
+	From      OrderStatus `json:"from,omitempty"`
+	To        OrderStatus `json:"to"`
+	ChangedBy uuid.UUID   `json:"changed_by"`
+	Note      string      `json:"note,omitempty"`
+	ChangedAt time.Time   `json:"changed_at"`
+}
+
+// OrderItemInput overrides the delivery date and notes of the order item made
+// from a deal line item.
+type OrderItemInput struct {
+	DealLineItemID uuid.UUID
+	DeliveryDate   *time.Time
+	Notes          string
+}
+
+// Order is a production order made from a won deal's products, tracked from
+// the factory floor to delivery.
+type Order struct {
+	ID           uuid.UUID   `json:"id"`
+	TenantID     uuid.UUID   `json:"tenant_id"`
+	OrderNumber  string      `json:"order_number"`
+	DealID       uuid.UUID   `json:"deal_id"`
+	DealCode     string      `json:"deal_code"`
+	DealName     string      `json:"deal_name"`
+	CustomerID   uuid.UUID   `json:"customer_id"`
+	CustomerName string      `json:"customer_name"`
+	Status       OrderStatus `json:"status"`
+	Items        []OrderItem `json:"items"`
+
+	// DeliveryDate is the date the customer expects the order by. Items
+	// without their own delivery date are due then.
+	DeliveryDate    *time.Time `json:"delivery_date,omitempty"`
+	ProductionNotes string     `json:"production_notes,omitempty"`
+	// ExternalReference is the order's ID in the factory's system.
+	ExternalReference string              `json:"external_reference,omitempty"`
+	TrackingNumber    string              `json:"tracking_number,omitempty"`
+	StatusHistory     []OrderStatusChange `json:"status_history"`
+
+	CreatedBy   uuid.UUID  `json:"created_by"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+	ShippedAt   *time.Time `json:"shipped_at,omitempty"`
+	DeliveredAt *time.Time `json:"delivered_at,omitempty"`
+	CancelledAt *time.Time `json:"cancelled_at,omitempty"`
+	Version     int        `json:"version"`
+
+	// Domain events
+	events []DomainEvent `json:"-"`
+}
+
+// NewOrderFromDeal creates a pending order for the quantities of a deal's line
+// items not yet fulfilled. items overrides the delivery date and notes of
+// individual items; other items are due on the order's delivery date unless
+// the line item has its own.
+func NewOrderFromDeal(deal *Deal, deliveryDate *time.Time, productionNotes string, items []OrderItemInput, createdBy uuid.UUID) (*Order, error) {
+	if deal.Status == DealStatusCancelled || deal.IsDeleted() {
+		return nil, ErrOrderDealCancelled
+	}
+
+	inputs := make(map[uuid.UUID]OrderItemInput, len(items))
+	for _, input := range items {
+		inputs[input.DealLineItemID] = input
+	}
+
+	orderItems := make([]OrderItem, 0, len(deal.LineItems))
+	for _, lineItem := range deal.LineItems {
+		quantity := lineItem.RemainingQuantity()
+		if quantity <= 0 {
+			continue
+		}
+
+		item := OrderItem{
+			ID:             uuid.New(),
+			DealLineItemID: lineItem.ID,
+			ProductID:      lineItem.ProductID,
+			ProductName:    lineItem.ProductName,
+			ProductSKU:     lineItem.ProductSKU,
+			Description:    lineItem.Description,
+			Quantity:       quantity,
+			DeliveryDate:   lineItem.DeliveryDate,
+			Notes:          lineItem.Notes,
+		}
+		if item.DeliveryDate == nil {
+			item.DeliveryDate = deliveryDate
+		}
+		if input, ok := inputs[lineItem.ID]; ok {
+			if input.DeliveryDate != nil {
+				item.DeliveryDate = input.DeliveryDate
+			}
+			if notes := strings.TrimSpace(input.Notes); notes != "" {
+				item.Notes = notes
+			}
+		}
+		orderItems = append(orderItems, item)
+	}
+	if len(orderItems) == 0 {
+		return nil, ErrOrderNoItems
+	}
+
+	now := time.Now().UTC()
+	id := uuid.New()
+	order := &Order{
+		ID:              id,
+		TenantID:        deal.TenantID,
+		OrderNumber:     fmt.Sprintf("ORD-%s-%s", now.Format("20060102"), strings.ToUpper(id.String()[:8])),
+		DealID:          deal.ID,
+		DealCode:        deal.Code,
+		DealName:        deal.Name,
+		CustomerID:      deal.CustomerID,
+		CustomerName:    deal.CustomerName,
+		Status:          OrderStatusPending,
+		Items:           orderItems,
+		DeliveryDate:    deliveryDate,
+		ProductionNotes: strings.TrimSpace(productionNotes),
+		StatusHistory: []OrderStatusChange{
+			{To: OrderStatusPending, ChangedBy: createdBy, ChangedAt: now},
+		},
+		CreatedBy: createdBy,
+		CreatedAt: now,
+		UpdatedAt: now,
+		Version:   1,
+		events:    make([]DomainEvent, 0),
+	}
+
+	order.AddEvent(NewOrderCreatedEvent(order))
+	return order, nil
+}
+
+// Update updates the order's delivery date, production notes and reference
+// in the factory's system. Closed orders cannot be updated.
+func (o *Order) Update(deliveryDate *time.Time, productionNotes, externalReference string) error {
+	if o.Status.IsClosed() {
+		return ErrInvalidOrderStatusTransition
+	}
+
+	o.DeliveryDate = deliveryDate
+	o.ProductionNotes = strings.TrimSpace(productionNotes)
+	o.ExternalReference = strings.TrimSpace(externalReference)
+	o.UpdatedAt = time.Now().UTC()
+
+	o.AddEvent(NewOrderUpdatedEvent(o))
+	return nil
+}
+
+// ChangeStatus moves the order to status. The tracking number is kept when
+// the order is shipped.
+func (o *Order) ChangeStatus(status OrderStatus, changedBy uuid.UUID, note, trackingNumber string) error {
+	if !status.IsValid() {
+		return ErrInvalidOrderStatus
+	}
+	if !o.Status.CanTransitionTo(status) {
+		return ErrInvalidOrderStatusTransition
+	}
+
+	now := time.Now().UTC()
+	from := o.Status
+	o.Status = status
+	switch status {
+	case OrderStatusShipped:
+		o.ShippedAt = &now
+		if trackingNumber = strings.TrimSpace(trackingNumber); trackingNumber != "" {
+			o.TrackingNumber = trackingNumber
+		}
+	case OrderStatusDelivered:
+		o.DeliveredAt = &now
+	case OrderStatusCancelled:
+		o.CancelledAt = &now
+	}
+	o.StatusHistory = append(o.StatusHistory, OrderStatusChange{
+		From:      from,
+		To:        status,
+		ChangedBy: changedBy,
+		Note:      strings.TrimSpace(note),
+		ChangedAt: now,
+	})
+	o.UpdatedAt = now
+
+	o.AddEvent(NewOrderStatusChangedEvent(o, from))
+	return nil
+}
+
+// AddEvent adds a domain event.
+func (o *Order) AddEvent(event DomainEvent) {
+	o.events = append(o.events, event)
+}
+
+// GetEvents returns all domain events.
+func (o *Order) GetEvents() []DomainEvent {
+	return o.events
+}
+
+// ClearEvents clears all domain events.
+func (o *Order) ClearEvents() {
+	o.events = make([]DomainEvent, 0)
+}
+
+// ============================================================================
+// Order Webhook
+// ============================================================================
+
+// OrderWebhook is a tenant's endpoint in its factory systems that receives
+// order events. Each delivery is signed with Secret.
+type OrderWebhook struct {
+	TenantID  uuid.UUID `json:"tenant_id"`
+	URL       string    `json:"url"`
+	Secret    string    `json:"secret"`
+	Enabled   bool      `json:"enabled"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// NewOrderWebhook creates an enabled webhook with a new secret.
+func NewOrderWebhook(tenantID uuid.UUID, webhookURL string) (*OrderWebhook, error) {
+	webhook := &OrderWebhook{TenantID: tenantID, Enabled: true}
+	if err := webhook.SetURL(webhookURL); err != nil {
+		return nil, err
+	}
+	if err := webhook.RotateSecret(); err != nil {
+		return nil, err
+	}
+	return webhook, nil
+}
+
+// SetURL changes the URL events are delivered to.
+func (w *OrderWebhook) SetURL(webhookURL string) error {
+	webhookURL = strings.TrimSpace(webhookURL)
+	u, err := url.Parse(webhookURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return ErrInvalidOrderWebhookURL
+	}
+	w.URL = webhookURL
+	w.UpdatedAt = time.Now().UTC()
+	return nil
+}
+
+// RotateSecret replaces the signing secret. Receivers must be given the new
+// secret to keep verifying deliveries.
+func (w *OrderWebhook) RotateSecret() error {
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return fmt.Errorf("failed to generate webhook secret: %w", err)
+	}
+	w.Secret = hex.EncodeToString(secret)
+	w.UpdatedAt = time.Now().UTC()
+	return nil
+}
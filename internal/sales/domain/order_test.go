@@ -0,0 +1,178 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func createTestOrderDeal(t *testing.T) *Deal {
+	t.Helper()
+
+	deal := createTestDeal(t)
+	lineDelivery := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	deal.LineItems = []DealLineItem{
+		{ID: uuid.New(), ProductID: uuid.New(), ProductName: "Batik Sarong", Quantity: 10},
+		{ID: uuid.New(), ProductID: uuid.New(), ProductName: "Batik Shirt", Quantity: 5, FulfilledQty: 2, DeliveryDate: &lineDelivery},
+		{ID: uuid.New(), ProductID: uuid.New(), ProductName: "Scarf", Quantity: 3, FulfilledQty: 3},
+	}
+	return deal
+}
+
+func createTestOrder(t *testing.T) *Order {
+	t.Helper()
+
+	order, err := NewOrderFromDeal(createTestOrderDeal(t), nil, "", nil, uuid.New())
+	if err != nil {
+		t.Fatalf("NewOrderFromDeal() error = %v", err)
+	}
+	order.ClearEvents()
+	return order
+}
+
+func TestNewOrderFromDeal(t *testing.T) {
+	deal := createTestOrderDeal(t)
+	deliveryDate := time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC)
+	itemDelivery := time.Date(2026, 2, 15, 0, 0, 0, 0, time.UTC)
+	createdBy := uuid.New()
+
+	order, err := NewOrderFromDeal(deal, &deliveryDate, " Use indigo dye ", []OrderItemInput{
+		{DealLineItemID: deal.LineItems[0].ID, DeliveryDate: &itemDelivery, Notes: "Gift wrap"},
+	}, createdBy)
+	if err != nil {
+		t.Fatalf("NewOrderFromDeal() error = %v", err)
+	}
+
+	if order.Status != OrderStatusPending {
+		t.Errorf("Status = %s, want %s", order.Status, OrderStatusPending)
+	}
+	if order.DealID != deal.ID || order.CustomerID != deal.CustomerID {
+		t.Error("Order should reference the deal and its customer")
+	}
+	if order.ProductionNotes != "Use indigo dye" {
+		t.Errorf("ProductionNotes = %q, want %q", order.ProductionNotes, "Use indigo dye")
+	}
+	if len(order.Items) != 2 {
+		t.Fatalf("len(Items) = %d, want 2 (fulfilled line items are skipped)", len(order.Items))
+	}
+
+	first, second := order.Items[0], order.Items[1]
+	if first.Quantity != 10 || !first.DeliveryDate.Equal(itemDelivery) || first.Notes != "Gift wrap" {
+		t.Errorf("Items[0] = %+v, want quantity 10 with the item delivery date and notes", first)
+	}
+	if second.Quantity != 3 {
+		t.Errorf("Items[1].Quantity = %d, want remaining quantity 3", second.Quantity)
+	}
+	if !second.DeliveryDate.Equal(*deal.LineItems[1].DeliveryDate) {
+		t.Errorf("Items[1].DeliveryDate = %v, want the line item's delivery date", second.DeliveryDate)
+	}
+
+	if len(order.StatusHistory) != 1 || order.StatusHistory[0].ChangedBy != createdBy {
+		t.Errorf("StatusHistory = %+v, want one pending entry by the creator", order.StatusHistory)
+	}
+	events := order.GetEvents()
+	if len(events) != 1 || events[0].EventType() != "order.created" {
+		t.Errorf("events = %v, want order.created", events)
+	}
+}
+
+func TestNewOrderFromDeal_Invalid(t *testing.T) {
+	t.Run("cancelled deal", func(t *testing.T) {
+		deal := createTestOrderDeal(t)
+		deal.Status = DealStatusCancelled
+
+		if _, err := NewOrderFromDeal(deal, nil, "", nil, uuid.New()); err != ErrOrderDealCancelled {
+			t.Errorf("NewOrderFromDeal() error = %v, want %v", err, ErrOrderDealCancelled)
+		}
+	})
+
+	t.Run("fulfilled deal", func(t *testing.T) {
+		deal := createTestOrderDeal(t)
+		for i := range deal.LineItems {
+			deal.LineItems[i].FulfilledQty = deal.LineItems[i].Quantity
+		}
+
+		if _, err := NewOrderFromDeal(deal, nil, "", nil, uuid.New()); err != ErrOrderNoItems {
+			t.Errorf("NewOrderFromDeal() error = %v, want %v", err, ErrOrderNoItems)
+		}
+	})
+}
+
+func TestOrder_ChangeStatus(t *testing.T) {
+	order := createTestOrder(t)
+	userID := uuid.New()
+
+	steps := []OrderStatus{OrderStatusInProduction, OrderStatusShipped, OrderStatusDelivered}
+	for _, status := range steps {
+		if err := order.ChangeStatus(status, userID, "", "TRK-123"); err != nil {
+			t.Fatalf("ChangeStatus(%s) error = %v", status, err)
+		}
+	}
+
+	if order.TrackingNumber != "TRK-123" {
+		t.Errorf("TrackingNumber = %q, want TRK-123", order.TrackingNumber)
+	}
+	if order.ShippedAt == nil || order.DeliveredAt == nil {
+		t.Error("ShippedAt and DeliveredAt should be set")
+	}
+	if len(order.StatusHistory) != 4 {
+		t.Errorf("len(StatusHistory) = %d, want 4", len(order.StatusHistory))
+	}
+	if last := order.StatusHistory[3]; last.From != OrderStatusShipped || last.To != OrderStatusDelivered {
+		t.Errorf("last change = %s -> %s, want shipped -> delivered", last.From, last.To)
+	}
+	if len(order.GetEvents()) != 3 {
+		t.Errorf("len(events) = %d, want 3", len(order.GetEvents()))
+	}
+
+	if err := order.Update(nil, "late change", ""); err != ErrInvalidOrderStatusTransition {
+		t.Errorf("Update() on delivered order error = %v, want %v", err, ErrInvalidOrderStatusTransition)
+	}
+}
+
+func TestOrder_ChangeStatus_Invalid(t *testing.T) {
+	tests := []struct {
+		name string
+		from OrderStatus
+		to   OrderStatus
+		want error
+	}{
+		{"unknown status", OrderStatusPending, OrderStatus("lost"), ErrInvalidOrderStatus},
+		{"skip production", OrderStatusPending, OrderStatusShipped, ErrInvalidOrderStatusTransition},
+		{"cancel shipped", OrderStatusShipped, OrderStatusCancelled, ErrInvalidOrderStatusTransition},
+		{"reopen cancelled", OrderStatusCancelled, OrderStatusPending, ErrInvalidOrderStatusTransition},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			order := createTestOrder(t)
+			order.Status = tt.from
+
+			if err := order.ChangeStatus(tt.to, uuid.New(), "", ""); err != tt.want {
+				t.Errorf("ChangeStatus() error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewOrderWebhook(t *testing.T) {
+	webhook, err := NewOrderWebhook(uuid.New(), "https://factory.example.com/hooks/orders")
+	if err != nil {
+		t.Fatalf("NewOrderWebhook() error = %v", err)
+	}
+	if !webhook.Enabled || len(webhook.Secret) != 64 {
+		t.Errorf("webhook = %+v, want enabled with a 64 character secret", webhook)
+	}
+
+	secret := webhook.Secret
+	if err := webhook.RotateSecret(); err != nil || webhook.Secret == secret {
+		t.Error("RotateSecret() should replace the secret")
+	}
+
+	for _, invalid := range []string{"", "ftp://factory.example.com", "https://", "factory.example.com"} {
+		if _, err := NewOrderWebhook(uuid.New(), invalid); err != ErrInvalidOrderWebhookURL {
+			t.Errorf("NewOrderWebhook(%q) error = %v, want %v", invalid, err, ErrInvalidOrderWebhookURL)
+		}
+	}
+}
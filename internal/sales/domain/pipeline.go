@@ -22,6 +22,8 @@ var (
 	ErrMinimumStagesRequired  = errors.New("minimum 2 stages required")
 	ErrDefaultPipelineRequired = errors.New("at least one default pipeline required")
 	ErrCannotDeleteDefaultPipeline = errors.New("cannot delete default pipeline")
+	ErrPipelineVersionNotFound = errors.New("pipeline version not found")
+	ErrInvalidStageMapping    = errors.New("invalid stage mapping")
 )
 
 // StageType represents the type of a pipeline stage.
@@ -74,6 +76,8 @@ type Stage struct {
 	IsActive     bool       `json:"is_active" bson:"is_active"`
 	RottenDays   int        `json:"rotten_days,omitempty" bson:"rotten_days,omitempty"` // Days until opportunity is considered stale
 	AutoActions  []AutoAction `json:"auto_actions,omitempty" bson:"auto_actions,omitempty"`
+	WIPLimit     int        `json:"wip_limit,omitempty" bson:"wip_limit,omitempty"` // Maximum open opportunities, 0 for no limit
+	EntryCriteria StageEntryCriteria `json:"entry_criteria" bson:"entry_criteria"`
 	CreatedAt    time.Time  `json:"created_at" bson:"created_at"`
 	UpdatedAt    time.Time  `json:"updated_at" bson:"updated_at"`
 }
@@ -608,3 +612,74 @@ func (p *Pipeline) CanDelete() error {
 	}
 	return nil
 }
+
+// ============================================================================
+// Pipeline Versions
+// ============================================================================
+
+// PipelineVersion is the stage layout of a pipeline as it was at one version.
+// A version is recorded every time the pipeline is saved, so stage references
+// in historical records can be resolved even after the pipeline changes.
+type PipelineVersion struct {
+	PipelineID uuid.UUID `json:"pipeline_id"`
+	TenantID   uuid.UUID `json:"tenant_id"`
+	Version    int       `json:"version"`
+	Name       string    `json:"name"`
+	Currency   string    `json:"currency"`
+	Stages     []*Stage  `json:"stages"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// NewPipelineVersion snapshots the pipeline at its current version.
+func NewPipelineVersion(p *Pipeline) *PipelineVersion {
+	stages := make([]*Stage, len(p.Stages))
+	for i, stage := range p.Stages {
+		copied := *stage
+		copied.AutoActions = append([]AutoAction(nil), stage.AutoActions...)
+		copied.EntryCriteria.RequiredFields = append([]string(nil), stage.EntryCriteria.RequiredFields...)
+		stages[i] = &copied
+	}
+
+	return &PipelineVersion{
+		PipelineID: p.ID,
+		TenantID:   p.TenantID,
+		Version:    p.Version,
+		Name:       p.Name,
+		Currency:   p.Currency,
+		Stages:     stages,
+		CreatedAt:  time.Now().UTC(),
+	}
+}
+
+// GetStage returns a stage of the version by ID.
+func (v *PipelineVersion) GetStage(stageID uuid.UUID) *Stage {
+	for _, stage := range v.Stages {
+		if stage.ID == stageID {
+			return stage
+		}
+	}
+	return nil
+}
+
+// StageMapping maps source stages to the stages their open opportunities move to.
+type StageMapping map[uuid.UUID]uuid.UUID
+
+// ValidateStageMapping checks that every mapping moves opportunities between
+// two different stages of the pipeline, into an active open stage that is not
+// itself being migrated away from.
+func (p *Pipeline) ValidateStageMapping(mapping StageMapping) error {
+	if len(mapping) == 0 {
+		return ErrInvalidStageMapping
+	}
+
+	for fromID, toID := range mapping {
+		if _, chained := mapping[toID]; chained || p.GetStage(fromID) == nil {
+			return ErrInvalidStageMapping
+		}
+		target := p.GetStage(toID)
+		if target == nil || !target.IsActive || target.Type.IsClosedType() {
+			return ErrInvalidStageMapping
+		}
+	}
+	return nil
+}
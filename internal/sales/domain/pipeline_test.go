@@ -851,3 +851,54 @@ func TestStage_Timestamps(t *testing.T) {
 		t.Error("Stage.Update() should update UpdatedAt")
 	}
 }
+
+func TestNewPipelineVersion(t *testing.T) {
+	pipeline, _ := NewPipeline(uuid.New(), "Sales", "USD", uuid.New())
+	pipeline.Version = 3
+
+	version := NewPipelineVersion(pipeline)
+	if version.Version != 3 || version.PipelineID != pipeline.ID || len(version.Stages) != len(pipeline.Stages) {
+		t.Fatalf("NewPipelineVersion() = version %d with %d stages, want version 3 with %d stages",
+			version.Version, len(version.Stages), len(pipeline.Stages))
+	}
+
+	stage := pipeline.Stages[0]
+	stage.Name = "Renamed"
+	if version.GetStage(stage.ID).Name == "Renamed" {
+		t.Error("NewPipelineVersion() stages should not change with the pipeline")
+	}
+}
+
+func TestPipeline_ValidateStageMapping(t *testing.T) {
+	pipeline, _ := NewPipeline(uuid.New(), "Sales", "USD", uuid.New())
+	pipeline.EnsureClosedStages()
+	open := pipeline.GetActiveStages()
+	first, second, third := open[0], open[1], open[2]
+	inactive, _ := pipeline.AddStage("Parked", StageTypeOpen, 10)
+	inactive.Deactivate()
+
+	tests := []struct {
+		name    string
+		mapping StageMapping
+		wantErr bool
+	}{
+		{"valid", StageMapping{first.ID: second.ID}, false},
+		{"from inactive stage", StageMapping{inactive.ID: second.ID}, false},
+		{"empty", StageMapping{}, true},
+		{"same stage", StageMapping{first.ID: first.ID}, true},
+		{"unknown source", StageMapping{uuid.New(): second.ID}, true},
+		{"unknown target", StageMapping{first.ID: uuid.New()}, true},
+		{"inactive target", StageMapping{first.ID: inactive.ID}, true},
+		{"closed target", StageMapping{first.ID: pipeline.GetWonStage().ID}, true},
+		{"chained", StageMapping{first.ID: second.ID, second.ID: third.ID}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := pipeline.ValidateStageMapping(tt.mapping)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ValidateStageMapping() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
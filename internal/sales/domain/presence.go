@@ -0,0 +1,199 @@
+package domain
+
+import (
+	"errors"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Presence errors
+var (
+	ErrInvalidPresenceRecordType = errors.New("invalid presence record type")
+	ErrInvalidPresenceMode       = errors.New("invalid presence mode")
+	ErrPresenceRoomFull          = errors.New("too many collaborators on the record")
+	ErrPresenceNotFound          = errors.New("presence connection not found")
+)
+
+// MaxPresenceConnections is the most connections a record tracks presence
+// for at a time.
+const MaxPresenceConnections = 100
+
+// PresenceRecordType is the kind of record collaborators are present on.
+type PresenceRecordType string
+
+const (
+	PresenceRecordLead        PresenceRecordType = "lead"
+	PresenceRecordOpportunity PresenceRecordType = "opportunity"
+	PresenceRecordDeal        PresenceRecordType = "deal"
+)
+
+// IsValid checks if the record type is valid.
+func (t PresenceRecordType) IsValid() bool {
+	switch t {
+	case PresenceRecordLead, PresenceRecordOpportunity, PresenceRecordDeal:
+		return true
+	default:
+		return false
+	}
+}
+
+// PresenceMode is what a collaborator is doing with a record.
+type PresenceMode string
+
+const (
+	PresenceModeViewing PresenceMode = "viewing"
+	PresenceModeEditing PresenceMode = "editing"
+)
+
+// IsValid checks if the presence mode is valid.
+func (m PresenceMode) IsValid() bool {
+	return m == PresenceModeViewing || m == PresenceModeEditing
+}
+
+// PresenceRecord identifies the record of a tenant collaborators are present
+// on.
+type PresenceRecord struct {
+	TenantID uuid.UUID
+	Type     PresenceRecordType
+	ID       uuid.UUID
+}
+
+// Presence is one connection of a collaborator to a record. A user with the
+// record open in two tabs has two connections.
+type Presence struct {
+	ConnectionID string       `json:"connection_id"`
+	UserID       uuid.UUID    `json:"user_id"`
+	Mode         PresenceMode `json:"mode"`
+	JoinedAt     time.Time    `json:"joined_at"`
+	EditingSince *time.Time   `json:"editing_since,omitempty"`
+}
+
+// PresenceLock is the soft lock on a record: a hint to other collaborators
+// that someone is editing it. It does not stop them from saving.
+type PresenceLock struct {
+	UserID       uuid.UUID `json:"user_id"`
+	ConnectionID string    `json:"connection_id"`
+	Since        time.Time `json:"since"`
+}
+
+// PresenceRoom holds the collaborators present on a record.
+type PresenceRoom struct {
+	Record      PresenceRecord
+	connections map[string]*Presence
+}
+
+// NewPresenceRoom creates an empty room for a record.
+func NewPresenceRoom(record PresenceRecord) (*PresenceRoom, error) {
+	if !record.Type.IsValid() {
+		return nil, ErrInvalidPresenceRecordType
+	}
+	return &PresenceRoom{
+		Record:      record,
+		connections: make(map[string]*Presence),
+	}, nil
+}
+
+// Join adds a connection of the user to the room, viewing the record.
+func (r *PresenceRoom) Join(connectionID string, userID uuid.UUID, now time.Time) error {
+	if _, ok := r.connections[connectionID]; ok {
+		return nil
+	}
+	if len(r.connections) >= MaxPresenceConnections {
+		return ErrPresenceRoomFull
+	}
+	r.connections[connectionID] = &Presence{
+		ConnectionID: connectionID,
+		UserID:       userID,
+		Mode:         PresenceModeViewing,
+		JoinedAt:     now.UTC(),
+	}
+	return nil
+}
+
+// SetMode switches a connection between viewing and editing the record. A
+// connection that keeps editing keeps the time it started.
+func (r *PresenceRoom) SetMode(connectionID string, mode PresenceMode, now time.Time) error {
+	if !mode.IsValid() {
+		return ErrInvalidPresenceMode
+	}
+	presence, ok := r.connections[connectionID]
+	if !ok {
+		return ErrPresenceNotFound
+	}
+	if presence.Mode == mode {
+		return nil
+	}
+	presence.Mode = mode
+	if mode == PresenceModeEditing {
+		since := now.UTC()
+		presence.EditingSince = &since
+	} else {
+		presence.EditingSince = nil
+	}
+	return nil
+}
+
+// Leave removes a connection from the room, releasing the lock if it held
+// it. It reports whether the connection was in the room.
+func (r *PresenceRoom) Leave(connectionID string) bool {
+	if _, ok := r.connections[connectionID]; !ok {
+		return false
+	}
+	delete(r.connections, connectionID)
+	return true
+}
+
+// Connection returns a connection of the room.
+func (r *PresenceRoom) Connection(connectionID string) (Presence, bool) {
+	presence, ok := r.connections[connectionID]
+	if !ok {
+		return Presence{}, false
+	}
+	return *presence, true
+}
+
+// IsEmpty reports whether nobody is present on the record.
+func (r *PresenceRoom) IsEmpty() bool {
+	return len(r.connections) == 0
+}
+
+// Collaborators returns the connections of the room, first joined first.
+func (r *PresenceRoom) Collaborators() []Presence {
+	collaborators := make([]Presence, 0, len(r.connections))
+	for _, presence := range r.connections {
+		collaborators = append(collaborators, *presence)
+	}
+	sort.Slice(collaborators, func(i, j int) bool {
+		if !collaborators[i].JoinedAt.Equal(collaborators[j].JoinedAt) {
+			return collaborators[i].JoinedAt.Before(collaborators[j].JoinedAt)
+		}
+		return collaborators[i].ConnectionID < collaborators[j].ConnectionID
+	})
+	return collaborators
+}
+
+// Lock returns the soft lock on the record, or nil if nobody is editing it.
+// The connection editing the longest holds the lock, so it passes to the
+// next editor when its holder stops editing or leaves.
+func (r *PresenceRoom) Lock() *PresenceLock {
+	var holder *Presence
+	for _, presence := range r.connections {
+		if presence.EditingSince == nil {
+			continue
+		}
+		if holder == nil || presence.EditingSince.Before(*holder.EditingSince) ||
+			(presence.EditingSince.Equal(*holder.EditingSince) && presence.ConnectionID < holder.ConnectionID) {
+			holder = presence
+		}
+	}
+	if holder == nil {
+		return nil
+	}
+	return &PresenceLock{
+		UserID:       holder.UserID,
+		ConnectionID: holder.ConnectionID,
+		Since:        *holder.EditingSince,
+	}
+}
@@ -0,0 +1,134 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func createTestPresenceRoom(t *testing.T) *PresenceRoom {
+	t.Helper()
+
+	room, err := NewPresenceRoom(PresenceRecord{
+		TenantID: uuid.New(),
+		Type:     PresenceRecordOpportunity,
+		ID:       uuid.New(),
+	})
+	if err != nil {
+		t.Fatalf("NewPresenceRoom() error = %v", err)
+	}
+	return room
+}
+
+func TestNewPresenceRoom_InvalidRecordType(t *testing.T) {
+	_, err := NewPresenceRoom(PresenceRecord{TenantID: uuid.New(), Type: "customer", ID: uuid.New()})
+	if err != ErrInvalidPresenceRecordType {
+		t.Errorf("NewPresenceRoom() error = %v, want %v", err, ErrInvalidPresenceRecordType)
+	}
+}
+
+func TestPresenceRoom_JoinAndLeave(t *testing.T) {
+	room := createTestPresenceRoom(t)
+	now := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+	alice, bob := uuid.New(), uuid.New()
+
+	if err := room.Join("b", bob, now.Add(time.Second)); err != nil {
+		t.Fatalf("Join() error = %v", err)
+	}
+	if err := room.Join("a", alice, now); err != nil {
+		t.Fatalf("Join() error = %v", err)
+	}
+
+	collaborators := room.Collaborators()
+	if len(collaborators) != 2 || collaborators[0].UserID != alice || collaborators[1].UserID != bob {
+		t.Fatalf("Collaborators() = %+v, want alice then bob", collaborators)
+	}
+	if collaborators[0].Mode != PresenceModeViewing {
+		t.Errorf("Mode = %s, want %s", collaborators[0].Mode, PresenceModeViewing)
+	}
+
+	if !room.Leave("a") {
+		t.Error("Leave() = false, want true")
+	}
+	if room.Leave("a") {
+		t.Error("Leave() of a connection that left = true, want false")
+	}
+	room.Leave("b")
+	if !room.IsEmpty() {
+		t.Error("IsEmpty() = false, want true")
+	}
+}
+
+func TestPresenceRoom_JoinFull(t *testing.T) {
+	room := createTestPresenceRoom(t)
+	now := time.Now()
+
+	for i := 0; i < MaxPresenceConnections; i++ {
+		if err := room.Join(uuid.NewString(), uuid.New(), now); err != nil {
+			t.Fatalf("Join() error = %v", err)
+		}
+	}
+	if err := room.Join("extra", uuid.New(), now); err != ErrPresenceRoomFull {
+		t.Errorf("Join() error = %v, want %v", err, ErrPresenceRoomFull)
+	}
+}
+
+func TestPresenceRoom_SetMode(t *testing.T) {
+	room := createTestPresenceRoom(t)
+	now := time.Now()
+	room.Join("a", uuid.New(), now)
+
+	if err := room.SetMode("a", "typing", now); err != ErrInvalidPresenceMode {
+		t.Errorf("SetMode() error = %v, want %v", err, ErrInvalidPresenceMode)
+	}
+	if err := room.SetMode("missing", PresenceModeEditing, now); err != ErrPresenceNotFound {
+		t.Errorf("SetMode() error = %v, want %v", err, ErrPresenceNotFound)
+	}
+
+	if err := room.SetMode("a", PresenceModeEditing, now); err != nil {
+		t.Fatalf("SetMode() error = %v", err)
+	}
+	// Editing again keeps the time editing started
+	room.SetMode("a", PresenceModeEditing, now.Add(time.Minute))
+	presence, _ := room.Connection("a")
+	if presence.EditingSince == nil || !presence.EditingSince.Equal(now.UTC()) {
+		t.Errorf("EditingSince = %v, want %v", presence.EditingSince, now.UTC())
+	}
+
+	room.SetMode("a", PresenceModeViewing, now)
+	presence, _ = room.Connection("a")
+	if presence.EditingSince != nil {
+		t.Errorf("EditingSince = %v, want nil", presence.EditingSince)
+	}
+}
+
+func TestPresenceRoom_Lock(t *testing.T) {
+	room := createTestPresenceRoom(t)
+	now := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+	alice, bob, carol := uuid.New(), uuid.New(), uuid.New()
+	room.Join("a", alice, now)
+	room.Join("b", bob, now)
+	room.Join("c", carol, now)
+
+	if lock := room.Lock(); lock != nil {
+		t.Fatalf("Lock() = %+v, want nil while nobody edits", lock)
+	}
+
+	room.SetMode("b", PresenceModeEditing, now.Add(time.Minute))
+	room.SetMode("a", PresenceModeEditing, now.Add(2*time.Minute))
+	if lock := room.Lock(); lock == nil || lock.UserID != bob {
+		t.Fatalf("Lock() = %+v, want held by the first editor", lock)
+	}
+
+	// The lock passes to the next editor when the holder leaves
+	room.Leave("b")
+	if lock := room.Lock(); lock == nil || lock.UserID != alice || !lock.Since.Equal(now.Add(2*time.Minute)) {
+		t.Fatalf("Lock() = %+v, want passed to alice", lock)
+	}
+
+	room.SetMode("a", PresenceModeViewing, now.Add(3*time.Minute))
+	if lock := room.Lock(); lock != nil {
+		t.Errorf("Lock() = %+v, want nil once editing stops", lock)
+	}
+}
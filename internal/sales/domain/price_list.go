@@ -0,0 +1,133 @@
+// Package domain contains the domain layer for the Sales Pipeline service.
+package domain
+
+import (
+	"errors"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Price list errors
+var (
+	ErrPriceListTierRequired     = errors.New("price list customer tier is required")
+	ErrDuplicatePriceList        = errors.New("only one price list per customer tier and currency")
+	ErrInvalidPriceListDiscount  = errors.New("price list discount must be between 0 and 100 percent")
+	ErrInvalidPriceListPrice     = errors.New("price list prices cannot be negative")
+	ErrDuplicatePriceListProduct = errors.New("a product can only be priced once per price list")
+)
+
+// ProductPrice is the unit price of a product on a price list, in the
+// smallest currency unit.
+type ProductPrice struct {
+	ProductID uuid.UUID `json:"product_id"`
+	UnitPrice int64     `json:"unit_price"`
+}
+
+// PriceList holds the prices a tenant charges customers of one tier in one
+// currency. Products without a price on the list are charged their catalogue
+// price less the list's discount.
+type PriceList struct {
+	Name            string         `json:"name"`
+	CustomerTier    string         `json:"customer_tier"`
+	Currency        string         `json:"currency"`
+	DiscountPercent float64        `json:"discount_percent"`
+	Prices          []ProductPrice `json:"prices"`
+}
+
+// Validate validates the price list.
+func (l PriceList) Validate() error {
+	if strings.TrimSpace(l.CustomerTier) == "" {
+		return ErrPriceListTierRequired
+	}
+	if !IsSupportedCurrency(l.Currency) {
+		return ErrInvalidCurrency
+	}
+	if l.DiscountPercent < 0 || l.DiscountPercent > 100 || math.IsNaN(l.DiscountPercent) {
+		return ErrInvalidPriceListDiscount
+	}
+
+	seen := make(map[uuid.UUID]bool, len(l.Prices))
+	for _, price := range l.Prices {
+		if price.UnitPrice < 0 {
+			return ErrInvalidPriceListPrice
+		}
+		if seen[price.ProductID] {
+			return ErrDuplicatePriceListProduct
+		}
+		seen[price.ProductID] = true
+	}
+	return nil
+}
+
+// PriceFor returns the unit price of a product on the list. listPrice is the
+// product's catalogue price, if known, which the list's discount is taken off
+// for products the list does not price. It reports false when the list has no
+// price for the product.
+func (l PriceList) PriceFor(productID uuid.UUID, listPrice *Money) (Money, bool) {
+	for _, price := range l.Prices {
+		if price.ProductID == productID {
+			return Money{Amount: price.UnitPrice, Currency: l.Currency}, true
+		}
+	}
+	if listPrice == nil || listPrice.Currency != l.Currency || l.DiscountPercent == 0 {
+		return Money{}, false
+	}
+	discounted, err := listPrice.Subtract(listPrice.Multiply(l.DiscountPercent / 100))
+	if err != nil {
+		return Money{}, false
+	}
+	return discounted, true
+}
+
+// ============================================================================
+// Pricing Settings
+// ============================================================================
+
+// PricingSettings holds a tenant's price lists.
+type PricingSettings struct {
+	TenantID   uuid.UUID   `json:"tenant_id" db:"tenant_id"`
+	PriceLists []PriceList `json:"price_lists" db:"-"`
+	UpdatedAt  time.Time   `json:"updated_at" db:"updated_at"`
+}
+
+// DefaultPricingSettings returns the settings used for tenants without a
+// configuration: no price lists, so products are sold at the price quoted.
+func DefaultPricingSettings(tenantID uuid.UUID) *PricingSettings {
+	return &PricingSettings{
+		TenantID:   tenantID,
+		PriceLists: []PriceList{},
+	}
+}
+
+// Validate validates the pricing settings.
+func (s *PricingSettings) Validate() error {
+	seen := make(map[string]bool, len(s.PriceLists))
+	for _, list := range s.PriceLists {
+		if err := list.Validate(); err != nil {
+			return err
+		}
+		key := strings.ToLower(list.CustomerTier) + "|" + strings.ToUpper(list.Currency)
+		if seen[key] {
+			return ErrDuplicatePriceList
+		}
+		seen[key] = true
+	}
+	return nil
+}
+
+// FindPriceList returns the price list for customers of a tier in a currency,
+// ignoring case, or nil if there is none.
+func (s *PricingSettings) FindPriceList(tier, currency string) *PriceList {
+	if strings.TrimSpace(tier) == "" {
+		return nil
+	}
+	for i := range s.PriceLists {
+		if strings.EqualFold(s.PriceLists[i].CustomerTier, tier) && strings.EqualFold(s.PriceLists[i].Currency, currency) {
+			return &s.PriceLists[i]
+		}
+	}
+	return nil
+}
@@ -0,0 +1,70 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestPricingSettings_Validate(t *testing.T) {
+	productID := uuid.New()
+	tests := []struct {
+		name  string
+		lists []PriceList
+		want  error
+	}{
+		{"no lists", nil, nil},
+		{"valid", []PriceList{{CustomerTier: "gold", Currency: "MYR", DiscountPercent: 10, Prices: []ProductPrice{{ProductID: productID, UnitPrice: 9000}}}}, nil},
+		{"one per tier and currency", []PriceList{{CustomerTier: "gold", Currency: "MYR"}, {CustomerTier: "GOLD", Currency: "MYR"}}, ErrDuplicatePriceList},
+		{"tier and currency differ", []PriceList{{CustomerTier: "gold", Currency: "MYR"}, {CustomerTier: "gold", Currency: "SGD"}}, nil},
+		{"tier required", []PriceList{{Currency: "MYR"}}, ErrPriceListTierRequired},
+		{"unknown currency", []PriceList{{CustomerTier: "gold", Currency: "XXX"}}, ErrInvalidCurrency},
+		{"discount above 100", []PriceList{{CustomerTier: "gold", Currency: "MYR", DiscountPercent: 120}}, ErrInvalidPriceListDiscount},
+		{"negative price", []PriceList{{CustomerTier: "gold", Currency: "MYR", Prices: []ProductPrice{{ProductID: productID, UnitPrice: -1}}}}, ErrInvalidPriceListPrice},
+		{"product priced twice", []PriceList{{CustomerTier: "gold", Currency: "MYR", Prices: []ProductPrice{{ProductID: productID, UnitPrice: 1}, {ProductID: productID, UnitPrice: 2}}}}, ErrDuplicatePriceListProduct},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			settings := &PricingSettings{TenantID: uuid.New(), PriceLists: tt.lists}
+			if err := settings.Validate(); err != tt.want {
+				t.Errorf("Validate() error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestPriceList_PriceFor(t *testing.T) {
+	priced, unpriced := uuid.New(), uuid.New()
+	settings := &PricingSettings{PriceLists: []PriceList{{
+		Name:            "Gold",
+		CustomerTier:    "gold",
+		Currency:        "MYR",
+		DiscountPercent: 10,
+		Prices:          []ProductPrice{{ProductID: priced, UnitPrice: 8000}},
+	}}}
+
+	list := settings.FindPriceList("Gold", "myr")
+	if list == nil {
+		t.Fatal("FindPriceList() = nil, want the gold list")
+	}
+	if settings.FindPriceList("silver", "MYR") != nil || settings.FindPriceList("", "MYR") != nil {
+		t.Error("FindPriceList() found a list for another tier")
+	}
+
+	if price, ok := list.PriceFor(priced, nil); !ok || price.Amount != 8000 || price.Currency != "MYR" {
+		t.Errorf("PriceFor(priced) = %v, %v, want MYR 80.00", price, ok)
+	}
+
+	// Unpriced products get the discount off their catalogue price
+	catalogue := Money{Amount: 10000, Currency: "MYR"}
+	if price, ok := list.PriceFor(unpriced, &catalogue); !ok || price.Amount != 9000 {
+		t.Errorf("PriceFor(unpriced) = %v, %v, want MYR 90.00", price, ok)
+	}
+	if _, ok := list.PriceFor(unpriced, &Money{Amount: 10000, Currency: "USD"}); ok {
+		t.Error("PriceFor() discounted a catalogue price in another currency")
+	}
+	if _, ok := list.PriceFor(unpriced, nil); ok {
+		t.Error("PriceFor() priced a product without a catalogue price")
+	}
+}
@@ -0,0 +1,295 @@
+// Package domain contains the domain layer for the Sales Pipeline service.
+package domain
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Report errors
+var (
+	ErrInvalidReportGroupBy = errors.New("invalid report group by")
+	ErrInvalidReportPeriod  = errors.New("invalid report period")
+	ErrReportPeriodTooLong  = errors.New("report period exceeds maximum range")
+)
+
+// MaxReportPeriodDays is the longest date range a single report may cover.
+const MaxReportPeriodDays = 366
+
+// ReportGroupBy represents the dimension a report is grouped by.
+type ReportGroupBy string
+
+const (
+	ReportGroupByOwner   ReportGroupBy = "owner"
+	ReportGroupByProduct ReportGroupBy = "product"
+	ReportGroupByRegion  ReportGroupBy = "region"
+	ReportGroupByDay     ReportGroupBy = "day"
+
+	// Marketing attribution: the UTM campaign, and the UTM medium or else the
+	// lead source
+	ReportGroupByCampaign ReportGroupBy = "campaign"
+	ReportGroupByChannel  ReportGroupBy = "channel"
+
+	// The catalogue category of the products sold, and the tier of the
+	// customer they were sold to
+	ReportGroupByProductCategory ReportGroupBy = "product_category"
+	ReportGroupByCustomerSegment ReportGroupBy = "customer_segment"
+)
+
+// ValidReportGroupBys returns all valid report groupings.
+func ValidReportGroupBys() []ReportGroupBy {
+	return []ReportGroupBy{
+		ReportGroupByOwner,
+		ReportGroupByProduct,
+		ReportGroupByRegion,
+		ReportGroupByDay,
+		ReportGroupByCampaign,
+		ReportGroupByChannel,
+		ReportGroupByProductCategory,
+		ReportGroupByCustomerSegment,
+	}
+}
+
+// IsValid checks if the grouping is valid.
+func (g ReportGroupBy) IsValid() bool {
+	for _, valid := range ValidReportGroupBys() {
+		if g == valid {
+			return true
+		}
+	}
+	return false
+}
+
+// AggregateDimension represents the dimension a daily aggregate row is keyed by.
+// Day-level reports are served from the owner dimension, which covers every record.
+type AggregateDimension string
+
+const (
+	AggregateDimensionOwner    AggregateDimension = "owner"
+	AggregateDimensionProduct  AggregateDimension = "product"
+	AggregateDimensionRegion   AggregateDimension = "region"
+	AggregateDimensionCampaign AggregateDimension = "campaign"
+	AggregateDimensionChannel  AggregateDimension = "channel"
+
+	AggregateDimensionProductCategory AggregateDimension = "product_category"
+	AggregateDimensionCustomerSegment AggregateDimension = "customer_segment"
+)
+
+// AggregateDimensions returns all aggregate dimensions.
+func AggregateDimensions() []AggregateDimension {
+	return []AggregateDimension{
+		AggregateDimensionOwner,
+		AggregateDimensionRegion,
+		AggregateDimensionProduct,
+		AggregateDimensionCampaign,
+		AggregateDimensionChannel,
+		AggregateDimensionProductCategory,
+		AggregateDimensionCustomerSegment,
+	}
+}
+
+// Dimension returns the aggregate dimension that backs the grouping.
+func (g ReportGroupBy) Dimension() AggregateDimension {
+	switch g {
+	case ReportGroupByProduct:
+		return AggregateDimensionProduct
+	case ReportGroupByRegion:
+		return AggregateDimensionRegion
+	case ReportGroupByCampaign:
+		return AggregateDimensionCampaign
+	case ReportGroupByChannel:
+		return AggregateDimensionChannel
+	case ReportGroupByProductCategory:
+		return AggregateDimensionProductCategory
+	case ReportGroupByCustomerSegment:
+		return AggregateDimensionCustomerSegment
+	default:
+		return AggregateDimensionOwner
+	}
+}
+
+// ReportPeriod is a half-open date range [From, To) used for reporting.
+type ReportPeriod struct {
+	From time.Time `json:"from"`
+	To   time.Time `json:"to"`
+}
+
+// NewReportPeriod creates a report period truncated to whole UTC days.
+// The To date is inclusive on input and stored as the start of the following day.
+func NewReportPeriod(from, to time.Time) (ReportPeriod, error) {
+	from = TruncateToDay(from)
+	to = TruncateToDay(to).AddDate(0, 0, 1)
+
+	if !from.Before(to) {
+		return ReportPeriod{}, ErrInvalidReportPeriod
+	}
+	if to.Sub(from) > MaxReportPeriodDays*24*time.Hour {
+		return ReportPeriod{}, ErrReportPeriodTooLong
+	}
+
+	return ReportPeriod{From: from, To: to}, nil
+}
+
+// Previous returns the period of the same length that ends where this one starts.
+func (p ReportPeriod) Previous() ReportPeriod {
+	return ReportPeriod{From: p.From.AddDate(0, 0, -p.Days()), To: p.From}
+}
+
+// Days returns the number of days covered by the period.
+func (p ReportPeriod) Days() int {
+	return int(p.To.Sub(p.From).Hours() / 24)
+}
+
+// Contains reports whether t falls within the period.
+func (p ReportPeriod) Contains(t time.Time) bool {
+	return !t.Before(p.From) && t.Before(p.To)
+}
+
+// TruncateToDay returns the start of the UTC day for the given time.
+func TruncateToDay(t time.Time) time.Time {
+	t = t.UTC()
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// DailySalesAggregate is a pre-aggregated row of sales activity for one day,
+// keyed by tenant, dimension and dimension value.
+type DailySalesAggregate struct {
+	TenantID             uuid.UUID          `json:"tenant_id" db:"tenant_id"`
+	Day                  time.Time          `json:"day" db:"day"`
+	Dimension            AggregateDimension `json:"dimension" db:"dimension"`
+	DimensionKey         string             `json:"dimension_key" db:"dimension_key"`
+	DimensionLabel       string             `json:"dimension_label" db:"dimension_label"`
+	Currency             string             `json:"currency" db:"currency"`
+	NewLeads             int64              `json:"new_leads" db:"new_leads"`
+	ConvertedLeads       int64              `json:"converted_leads" db:"converted_leads"`
+	OpportunitiesCreated int64              `json:"opportunities_created" db:"opportunities_created"`
+	OpportunitiesWon     int64              `json:"opportunities_won" db:"opportunities_won"`
+	OpportunitiesLost    int64              `json:"opportunities_lost" db:"opportunities_lost"`
+	WonRevenue           int64              `json:"won_revenue" db:"won_revenue"`
+	AggregatedAt         time.Time          `json:"aggregated_at" db:"aggregated_at"`
+}
+
+// SalesPerformanceRow is one group of a sales performance report.
+type SalesPerformanceRow struct {
+	GroupKey             string `json:"group_key" db:"group_key"`
+	GroupLabel           string `json:"group_label" db:"group_label"`
+	Currency             string `json:"currency" db:"currency"`
+	NewLeads             int64  `json:"new_leads" db:"new_leads"`
+	ConvertedLeads       int64  `json:"converted_leads" db:"converted_leads"`
+	OpportunitiesCreated int64  `json:"opportunities_created" db:"opportunities_created"`
+	OpportunitiesWon     int64  `json:"opportunities_won" db:"opportunities_won"`
+	OpportunitiesLost    int64  `json:"opportunities_lost" db:"opportunities_lost"`
+	WonRevenue           int64  `json:"won_revenue" db:"won_revenue"`
+}
+
+// ConversionRate returns the lead conversion rate as a percentage.
+func (r *SalesPerformanceRow) ConversionRate() float64 {
+	if r.NewLeads == 0 {
+		return 0
+	}
+	return float64(r.ConvertedLeads) / float64(r.NewLeads) * 100
+}
+
+// WinRate returns the win rate of closed opportunities as a percentage.
+func (r *SalesPerformanceRow) WinRate() float64 {
+	closed := r.OpportunitiesWon + r.OpportunitiesLost
+	if closed == 0 {
+		return 0
+	}
+	return float64(r.OpportunitiesWon) / float64(closed) * 100
+}
+
+// AverageDealSize returns the average revenue of won opportunities.
+func (r *SalesPerformanceRow) AverageDealSize() int64 {
+	if r.OpportunitiesWon == 0 {
+		return 0
+	}
+	return r.WonRevenue / r.OpportunitiesWon
+}
+
+// Merge adds the counters of another row into this one.
+func (r *SalesPerformanceRow) Merge(other *SalesPerformanceRow) {
+	r.NewLeads += other.NewLeads
+	r.ConvertedLeads += other.ConvertedLeads
+	r.OpportunitiesCreated += other.OpportunitiesCreated
+	r.OpportunitiesWon += other.OpportunitiesWon
+	r.OpportunitiesLost += other.OpportunitiesLost
+	r.WonRevenue += other.WonRevenue
+}
+
+// WinLossCohort compares the closed opportunities of one group with those of
+// the same group in the previous period.
+type WinLossCohort struct {
+	GroupKey   string
+	GroupLabel string
+	Current    *SalesPerformanceRow
+	Previous   *SalesPerformanceRow
+}
+
+// WinRateChange returns the change in win rate, in percentage points.
+func (c *WinLossCohort) WinRateChange() float64 {
+	return c.Current.WinRate() - c.Previous.WinRate()
+}
+
+// AverageDealSizeChange returns the change in average deal size.
+func (c *WinLossCohort) AverageDealSizeChange() int64 {
+	return c.Current.AverageDealSize() - c.Previous.AverageDealSize()
+}
+
+// AverageDealSizeChangePercent returns the change in average deal size as a
+// percentage of the previous one, or nil when nothing was won before.
+func (c *WinLossCohort) AverageDealSizeChangePercent() *float64 {
+	previous := c.Previous.AverageDealSize()
+	if previous == 0 {
+		return nil
+	}
+	change := float64(c.AverageDealSizeChange()) / float64(previous) * 100
+	return &change
+}
+
+// BuildWinLossCohorts pairs the groups of two periods by key. Groups keep the
+// order of the current period, followed by those only seen in the previous
+// one; groups that closed nothing in either period are left out.
+func BuildWinLossCohorts(current, previous []*SalesPerformanceRow) []*WinLossCohort {
+	previousByKey := make(map[string]*SalesPerformanceRow, len(previous))
+	for _, row := range previous {
+		previousByKey[row.GroupKey] = row
+	}
+
+	cohorts := make([]*WinLossCohort, 0, len(current))
+	seen := make(map[string]bool, len(current))
+	for _, row := range current {
+		seen[row.GroupKey] = true
+		prev, ok := previousByKey[row.GroupKey]
+		if !ok {
+			prev = &SalesPerformanceRow{GroupKey: row.GroupKey, Currency: row.Currency}
+		}
+		cohorts = append(cohorts, &WinLossCohort{GroupKey: row.GroupKey, GroupLabel: row.GroupLabel, Current: row, Previous: prev})
+	}
+	for _, row := range previous {
+		if seen[row.GroupKey] {
+			continue
+		}
+		cur := &SalesPerformanceRow{GroupKey: row.GroupKey, Currency: row.Currency}
+		cohorts = append(cohorts, &WinLossCohort{GroupKey: row.GroupKey, GroupLabel: row.GroupLabel, Current: cur, Previous: row})
+	}
+
+	closed := cohorts[:0]
+	for _, cohort := range cohorts {
+		if cohort.Current.OpportunitiesWon+cohort.Current.OpportunitiesLost+
+			cohort.Previous.OpportunitiesWon+cohort.Previous.OpportunitiesLost > 0 {
+			closed = append(closed, cohort)
+		}
+	}
+	return closed
+}
+
+// AggregationRun records the outcome of a daily aggregation run.
+type AggregationRun struct {
+	Day         time.Time `json:"day" db:"day"`
+	RowsWritten int64     `json:"rows_written" db:"rows_written"`
+	StartedAt   time.Time `json:"started_at" db:"started_at"`
+	CompletedAt time.Time `json:"completed_at" db:"completed_at"`
+}
@@ -0,0 +1,191 @@
+// Package domain contains the domain layer for the Sales Pipeline service.
+package domain
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Report export errors
+var (
+	ErrReportExportNotFound      = errors.New("report export not found")
+	ErrInvalidReportExportFormat = errors.New("invalid report export format")
+	ErrInvalidReportType         = errors.New("invalid report type")
+)
+
+// ReportExportRetention is how long generated export files remain downloadable.
+const ReportExportRetention = 7 * 24 * time.Hour
+
+// ReportType identifies a report that can be exported.
+type ReportType string
+
+const (
+	ReportTypeSalesPerformance ReportType = "sales-performance"
+)
+
+// IsValid checks if the report type is valid.
+func (t ReportType) IsValid() bool {
+	return t == ReportTypeSalesPerformance
+}
+
+// ReportExportFormat represents the file format of a report export.
+type ReportExportFormat string
+
+const (
+	ReportExportFormatXLSX ReportExportFormat = "xlsx"
+	ReportExportFormatPDF  ReportExportFormat = "pdf"
+)
+
+// IsValid checks if the format is valid.
+func (f ReportExportFormat) IsValid() bool {
+	return f == ReportExportFormatXLSX || f == ReportExportFormatPDF
+}
+
+// ContentType returns the MIME type of the format.
+func (f ReportExportFormat) ContentType() string {
+	switch f {
+	case ReportExportFormatXLSX:
+		return "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	case ReportExportFormatPDF:
+		return "application/pdf"
+	default:
+		return "application/octet-stream"
+	}
+}
+
+// FileName returns a file name for a report of this format.
+func (f ReportExportFormat) FileName(report ReportType, at time.Time) string {
+	return string(report) + "_" + at.UTC().Format("20060102_150405") + "." + string(f)
+}
+
+// ReportExportStatus represents the status of an asynchronous report export.
+type ReportExportStatus string
+
+const (
+	ReportExportStatusPending    ReportExportStatus = "pending"
+	ReportExportStatusProcessing ReportExportStatus = "processing"
+	ReportExportStatusCompleted  ReportExportStatus = "completed"
+	ReportExportStatusFailed     ReportExportStatus = "failed"
+)
+
+// ReportExport is an asynchronous report export requested by a user.
+type ReportExport struct {
+	ID          uuid.UUID          `json:"id" db:"id"`
+	TenantID    uuid.UUID          `json:"tenant_id" db:"tenant_id"`
+	RequestedBy uuid.UUID          `json:"requested_by" db:"requested_by"`
+	ReportType  ReportType         `json:"report_type" db:"report_type"`
+	Format      ReportExportFormat `json:"format" db:"format"`
+	Parameters  []byte             `json:"parameters" db:"parameters"`
+	Status      ReportExportStatus `json:"status" db:"status"`
+	FileID      *string            `json:"file_id,omitempty" db:"file_id"`
+	FileName    *string            `json:"file_name,omitempty" db:"file_name"`
+	FileSize    int64              `json:"file_size" db:"file_size"`
+	Error       *string            `json:"error,omitempty" db:"error"`
+	CreatedAt   time.Time          `json:"created_at" db:"created_at"`
+	CompletedAt *time.Time         `json:"completed_at,omitempty" db:"completed_at"`
+	ExpiresAt   *time.Time         `json:"expires_at,omitempty" db:"expires_at"`
+}
+
+// NewReportExport creates a new pending report export.
+func NewReportExport(tenantID, requestedBy uuid.UUID, reportType ReportType, format ReportExportFormat, parameters []byte) (*ReportExport, error) {
+	if !reportType.IsValid() {
+		return nil, ErrInvalidReportType
+	}
+	if !format.IsValid() {
+		return nil, ErrInvalidReportExportFormat
+	}
+
+	return &ReportExport{
+		ID:          uuid.New(),
+		TenantID:    tenantID,
+		RequestedBy: requestedBy,
+		ReportType:  reportType,
+		Format:      format,
+		Parameters:  parameters,
+		Status:      ReportExportStatusPending,
+		CreatedAt:   time.Now().UTC(),
+	}, nil
+}
+
+// MarkProcessing marks the export as being generated.
+func (e *ReportExport) MarkProcessing() {
+	e.Status = ReportExportStatusProcessing
+}
+
+// MarkCompleted marks the export as completed with the stored file.
+func (e *ReportExport) MarkCompleted(fileID, fileName string, size int64) {
+	now := time.Now().UTC()
+	expiresAt := now.Add(ReportExportRetention)
+
+	e.Status = ReportExportStatusCompleted
+	e.FileID = &fileID
+	e.FileName = &fileName
+	e.FileSize = size
+	e.Error = nil
+	e.CompletedAt = &now
+	e.ExpiresAt = &expiresAt
+}
+
+// MarkFailed marks the export as failed.
+func (e *ReportExport) MarkFailed(reason string) {
+	now := time.Now().UTC()
+	e.Status = ReportExportStatusFailed
+	e.Error = &reason
+	e.CompletedAt = &now
+}
+
+// IsDownloadable checks if the export file can be downloaded.
+func (e *ReportExport) IsDownloadable() bool {
+	if e.Status != ReportExportStatusCompleted || e.FileID == nil {
+		return false
+	}
+	return e.ExpiresAt == nil || time.Now().Before(*e.ExpiresAt)
+}
+
+// ReportLocale represents the locale used to render reports.
+type ReportLocale string
+
+const (
+	ReportLocaleEnglish ReportLocale = "en-MY"
+	ReportLocaleMalay   ReportLocale = "ms-MY"
+)
+
+// IsValid checks if the locale is valid.
+func (l ReportLocale) IsValid() bool {
+	return l == ReportLocaleEnglish || l == ReportLocaleMalay
+}
+
+// CurrencyDisplay controls how currency amounts are shown in rendered reports.
+type CurrencyDisplay string
+
+const (
+	CurrencyDisplaySymbol CurrencyDisplay = "symbol"
+	CurrencyDisplayCode   CurrencyDisplay = "code"
+)
+
+// IsValid checks if the currency display is valid.
+func (d CurrencyDisplay) IsValid() bool {
+	return d == CurrencyDisplaySymbol || d == CurrencyDisplayCode
+}
+
+// ReportBranding holds a tenant's customization of rendered reports.
+type ReportBranding struct {
+	TenantID        uuid.UUID       `json:"tenant_id" db:"tenant_id"`
+	CompanyName     string          `json:"company_name" db:"company_name"`
+	LogoFileID      *string         `json:"logo_file_id,omitempty" db:"logo_file_id"`
+	Locale          ReportLocale    `json:"locale" db:"locale"`
+	CurrencyDisplay CurrencyDisplay `json:"currency_display" db:"currency_display"`
+	UpdatedAt       time.Time       `json:"updated_at" db:"updated_at"`
+}
+
+// DefaultReportBranding returns the branding used when a tenant has not customized reports.
+func DefaultReportBranding(tenantID uuid.UUID) *ReportBranding {
+	return &ReportBranding{
+		TenantID:        tenantID,
+		CompanyName:     "Kilang Desa Murni Batik",
+		Locale:          ReportLocaleEnglish,
+		CurrencyDisplay: CurrencyDisplaySymbol,
+	}
+}
@@ -0,0 +1,66 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestNewReportExport(t *testing.T) {
+	export, err := NewReportExport(uuid.New(), uuid.New(), ReportTypeSalesPerformance, ReportExportFormatPDF, []byte(`{}`))
+	if err != nil {
+		t.Fatalf("NewReportExport() error = %v", err)
+	}
+	if export.Status != ReportExportStatusPending {
+		t.Errorf("expected pending status, got %s", export.Status)
+	}
+	if export.IsDownloadable() {
+		t.Error("pending export should not be downloadable")
+	}
+
+	if _, err := NewReportExport(uuid.New(), uuid.New(), ReportTypeSalesPerformance, "csv", nil); err != ErrInvalidReportExportFormat {
+		t.Errorf("expected ErrInvalidReportExportFormat, got %v", err)
+	}
+	if _, err := NewReportExport(uuid.New(), uuid.New(), "pipeline", ReportExportFormatXLSX, nil); err != ErrInvalidReportType {
+		t.Errorf("expected ErrInvalidReportType, got %v", err)
+	}
+}
+
+func TestReportExport_Lifecycle(t *testing.T) {
+	export, _ := NewReportExport(uuid.New(), uuid.New(), ReportTypeSalesPerformance, ReportExportFormatXLSX, nil)
+
+	export.MarkProcessing()
+	if export.Status != ReportExportStatusProcessing {
+		t.Errorf("expected processing status, got %s", export.Status)
+	}
+
+	export.MarkCompleted("file-1", "report.xlsx", 2048)
+	if !export.IsDownloadable() {
+		t.Error("completed export should be downloadable")
+	}
+	if export.ExpiresAt == nil || export.ExpiresAt.Sub(*export.CompletedAt) != ReportExportRetention {
+		t.Errorf("unexpected expiry %v", export.ExpiresAt)
+	}
+
+	expired := time.Now().Add(-time.Minute)
+	export.ExpiresAt = &expired
+	if export.IsDownloadable() {
+		t.Error("expired export should not be downloadable")
+	}
+
+	export.MarkFailed("render failed")
+	if export.Status != ReportExportStatusFailed || export.Error == nil {
+		t.Errorf("expected failed export with error, got %+v", export)
+	}
+}
+
+func TestReportExportFormat_FileName(t *testing.T) {
+	at := time.Date(2024, 3, 5, 14, 30, 0, 0, time.UTC)
+	if got := ReportExportFormatPDF.FileName(ReportTypeSalesPerformance, at); got != "sales-performance_20240305_143000.pdf" {
+		t.Errorf("FileName() = %s", got)
+	}
+	if ReportExportFormatXLSX.ContentType() != "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet" {
+		t.Errorf("unexpected xlsx content type %s", ReportExportFormatXLSX.ContentType())
+	}
+}
@@ -0,0 +1,174 @@
+// Package domain contains the domain layer for the Sales Pipeline service.
+package domain
+
+import (
+	"testing"
+	"time"
+)
+
+func TestReportGroupBy_IsValid(t *testing.T) {
+	tests := []struct {
+		groupBy  ReportGroupBy
+		expected bool
+	}{
+		{ReportGroupByOwner, true},
+		{ReportGroupByProduct, true},
+		{ReportGroupByRegion, true},
+		{ReportGroupByDay, true},
+		{ReportGroupBy("customer"), false},
+		{ReportGroupBy(""), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.groupBy), func(t *testing.T) {
+			if tt.groupBy.IsValid() != tt.expected {
+				t.Errorf("ReportGroupBy.IsValid() = %v, want %v", tt.groupBy.IsValid(), tt.expected)
+			}
+		})
+	}
+}
+
+func TestReportGroupBy_Dimension(t *testing.T) {
+	tests := []struct {
+		groupBy  ReportGroupBy
+		expected AggregateDimension
+	}{
+		{ReportGroupByOwner, AggregateDimensionOwner},
+		{ReportGroupByProduct, AggregateDimensionProduct},
+		{ReportGroupByRegion, AggregateDimensionRegion},
+		{ReportGroupByDay, AggregateDimensionOwner},
+		{ReportGroupByProductCategory, AggregateDimensionProductCategory},
+		{ReportGroupByCustomerSegment, AggregateDimensionCustomerSegment},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.groupBy), func(t *testing.T) {
+			if got := tt.groupBy.Dimension(); got != tt.expected {
+				t.Errorf("ReportGroupBy.Dimension() = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestNewReportPeriod(t *testing.T) {
+	from := time.Date(2024, 3, 1, 15, 30, 0, 0, time.UTC)
+	to := time.Date(2024, 3, 31, 8, 0, 0, 0, time.UTC)
+
+	period, err := NewReportPeriod(from, to)
+	if err != nil {
+		t.Fatalf("NewReportPeriod() error = %v", err)
+	}
+	if !period.From.Equal(time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("NewReportPeriod() From = %v", period.From)
+	}
+	if !period.To.Equal(time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("NewReportPeriod() To = %v", period.To)
+	}
+	if period.Days() != 31 {
+		t.Errorf("ReportPeriod.Days() = %v, want 31", period.Days())
+	}
+}
+
+func TestNewReportPeriod_SingleDay(t *testing.T) {
+	day := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	period, err := NewReportPeriod(day, day)
+	if err != nil {
+		t.Fatalf("NewReportPeriod() error = %v", err)
+	}
+	if period.Days() != 1 {
+		t.Errorf("ReportPeriod.Days() = %v, want 1", period.Days())
+	}
+}
+
+func TestReportPeriod_Previous(t *testing.T) {
+	period, _ := NewReportPeriod(time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC), time.Date(2024, 3, 31, 0, 0, 0, 0, time.UTC))
+
+	previous := period.Previous()
+	if !previous.From.Equal(time.Date(2024, 1, 30, 0, 0, 0, 0, time.UTC)) || !previous.To.Equal(period.From) {
+		t.Errorf("ReportPeriod.Previous() = %v - %v, want the 31 days before March", previous.From, previous.To)
+	}
+}
+
+func TestNewReportPeriod_Invalid(t *testing.T) {
+	from := time.Date(2024, 3, 10, 0, 0, 0, 0, time.UTC)
+
+	if _, err := NewReportPeriod(from, from.AddDate(0, 0, -1)); err != ErrInvalidReportPeriod {
+		t.Errorf("NewReportPeriod() error = %v, want %v", err, ErrInvalidReportPeriod)
+	}
+	if _, err := NewReportPeriod(from, from.AddDate(2, 0, 0)); err != ErrReportPeriodTooLong {
+		t.Errorf("NewReportPeriod() error = %v, want %v", err, ErrReportPeriodTooLong)
+	}
+}
+
+func TestSalesPerformanceRow_Rates(t *testing.T) {
+	row := &SalesPerformanceRow{
+		NewLeads:          20,
+		ConvertedLeads:    5,
+		OpportunitiesWon:  3,
+		OpportunitiesLost: 1,
+		WonRevenue:        90000,
+	}
+
+	if got := row.ConversionRate(); got != 25 {
+		t.Errorf("ConversionRate() = %v, want 25", got)
+	}
+	if got := row.WinRate(); got != 75 {
+		t.Errorf("WinRate() = %v, want 75", got)
+	}
+	if got := row.AverageDealSize(); got != 30000 {
+		t.Errorf("AverageDealSize() = %v, want 30000", got)
+	}
+}
+
+func TestSalesPerformanceRow_RatesWithoutData(t *testing.T) {
+	row := &SalesPerformanceRow{}
+
+	if row.ConversionRate() != 0 || row.WinRate() != 0 || row.AverageDealSize() != 0 {
+		t.Error("expected zero rates for an empty row")
+	}
+}
+
+func TestSalesPerformanceRow_Merge(t *testing.T) {
+	row := &SalesPerformanceRow{NewLeads: 1, OpportunitiesWon: 1, WonRevenue: 100}
+	row.Merge(&SalesPerformanceRow{NewLeads: 2, ConvertedLeads: 1, OpportunitiesWon: 2, OpportunitiesLost: 1, WonRevenue: 250})
+
+	if row.NewLeads != 3 || row.ConvertedLeads != 1 || row.OpportunitiesWon != 3 || row.OpportunitiesLost != 1 || row.WonRevenue != 350 {
+		t.Errorf("Merge() produced %+v", row)
+	}
+}
+
+func TestBuildWinLossCohorts(t *testing.T) {
+	current := []*SalesPerformanceRow{
+		{GroupKey: "gold", GroupLabel: "gold", OpportunitiesWon: 3, OpportunitiesLost: 1, WonRevenue: 90000},
+		{GroupKey: "silver", GroupLabel: "silver", OpportunitiesCreated: 4},
+		{GroupKey: "bronze", GroupLabel: "bronze", OpportunitiesLost: 2},
+	}
+	previous := []*SalesPerformanceRow{
+		{GroupKey: "gold", GroupLabel: "gold", OpportunitiesWon: 1, OpportunitiesLost: 1, WonRevenue: 20000},
+		{GroupKey: "platinum", GroupLabel: "platinum", OpportunitiesWon: 1, WonRevenue: 50000},
+	}
+
+	cohorts := BuildWinLossCohorts(current, previous)
+	if len(cohorts) != 3 {
+		t.Fatalf("BuildWinLossCohorts() = %d cohorts, want 3 (silver closed nothing)", len(cohorts))
+	}
+	if cohorts[0].GroupKey != "gold" || cohorts[1].GroupKey != "bronze" || cohorts[2].GroupKey != "platinum" {
+		t.Errorf("BuildWinLossCohorts() order = %s, %s, %s; want gold, bronze, platinum", cohorts[0].GroupKey, cohorts[1].GroupKey, cohorts[2].GroupKey)
+	}
+
+	gold := cohorts[0]
+	if gold.WinRateChange() != 25 || gold.AverageDealSizeChange() != 10000 {
+		t.Errorf("gold change = %v points, %d; want 25 points, 10000", gold.WinRateChange(), gold.AverageDealSizeChange())
+	}
+	if pct := gold.AverageDealSizeChangePercent(); pct == nil || *pct != 50 {
+		t.Errorf("gold AverageDealSizeChangePercent() = %v, want 50", pct)
+	}
+
+	if pct := cohorts[1].AverageDealSizeChangePercent(); pct != nil {
+		t.Errorf("bronze AverageDealSizeChangePercent() = %v, want nil without previous wins", *pct)
+	}
+	if platinum := cohorts[2]; platinum.Current.OpportunitiesWon != 0 || platinum.WinRateChange() != -100 {
+		t.Errorf("platinum change = %v points, want -100", platinum.WinRateChange())
+	}
+}
@@ -2,9 +2,12 @@ package domain
 
 import (
 	"context"
+	"io"
 	"time"
 
 	"github.com/google/uuid"
+
+	"github.com/kilang-desa-murni/crm/pkg/query"
 )
 
 // ============================================================================
@@ -24,6 +27,12 @@ type LeadRepository interface {
 	GetByEmail(ctx context.Context, tenantID uuid.UUID, email string) (*Lead, error)
 	GetByPhone(ctx context.Context, tenantID uuid.UUID, phone string) (*Lead, error)
 
+	// Duplicate detection
+	FindMatches(ctx context.Context, tenantID uuid.UUID, criteria LeadMatchCriteria, limit int) ([]*Lead, error)
+	// Merge stores a lead and the duplicates merged into it, moving the
+	// duplicates' activities over to it.
+	Merge(ctx context.Context, target *Lead, sources []*Lead) error
+
 	// Status-based queries
 	GetByStatus(ctx context.Context, tenantID uuid.UUID, status LeadStatus, opts ListOptions) ([]*Lead, int64, error)
 	GetQualifiedLeads(ctx context.Context, tenantID uuid.UUID, opts ListOptions) ([]*Lead, int64, error)
@@ -82,6 +91,10 @@ type LeadFilter struct {
 
 	// Campaign filter
 	CampaignID *uuid.UUID `json:"campaign_id,omitempty"`
+
+	// Expr holds the conditions of a filter expression, combined with the
+	// filters above.
+	Expr query.Filter `json:"-"`
 }
 
 // ============================================================================
@@ -109,6 +122,11 @@ type OpportunityRepository interface {
 	GetByPipeline(ctx context.Context, tenantID, pipelineID uuid.UUID, opts ListOptions) ([]*Opportunity, int64, error)
 	GetByStage(ctx context.Context, tenantID, pipelineID, stageID uuid.UUID, opts ListOptions) ([]*Opportunity, int64, error)
 
+	// GetBoardPositionNeighbors returns the board positions on either side of
+	// index in a stage, leaving out excludeID. An empty position means that
+	// side is the end of the column.
+	GetBoardPositionNeighbors(ctx context.Context, tenantID, stageID, excludeID uuid.UUID, index int) (before, after string, err error)
+
 	// Relationship queries
 	GetByCustomer(ctx context.Context, tenantID, customerID uuid.UUID, opts ListOptions) ([]*Opportunity, int64, error)
 	GetByContact(ctx context.Context, tenantID, contactID uuid.UUID, opts ListOptions) ([]*Opportunity, int64, error)
@@ -125,6 +143,7 @@ type OpportunityRepository interface {
 	GetHighValueOpportunities(ctx context.Context, tenantID uuid.UUID, minAmount int64, currency string, opts ListOptions) ([]*Opportunity, int64, error)
 	GetTotalPipelineValue(ctx context.Context, tenantID uuid.UUID, currency string) (int64, error)
 	GetWeightedPipelineValue(ctx context.Context, tenantID uuid.UUID, currency string) (int64, error)
+	GetPipelineValueByCurrency(ctx context.Context, tenantID uuid.UUID) ([]*PipelineCurrencyValue, error)
 
 	// Bulk operations
 	BulkUpdateOwner(ctx context.Context, tenantID uuid.UUID, opportunityIDs []uuid.UUID, newOwnerID uuid.UUID) error
@@ -136,10 +155,14 @@ type OpportunityRepository interface {
 	GetWinRate(ctx context.Context, tenantID uuid.UUID, start, end time.Time) (float64, error)
 	GetAverageDealSize(ctx context.Context, tenantID uuid.UUID, currency string, start, end time.Time) (int64, error)
 	GetAverageSalesCycle(ctx context.Context, tenantID uuid.UUID, start, end time.Time) (int, error) // days
+	GetCloseBreakdown(ctx context.Context, tenantID uuid.UUID, pipelineID *uuid.UUID, status OpportunityStatus) (*CloseBreakdown, error)
 }
 
 // OpportunityFilter defines filtering options for opportunity queries.
 type OpportunityFilter struct {
+	// IDs restricts the results to these opportunities
+	IDs []uuid.UUID `json:"ids,omitempty"`
+
 	// Status filters
 	Statuses []OpportunityStatus `json:"statuses,omitempty"`
 
@@ -176,6 +199,10 @@ type OpportunityFilter struct {
 
 	// Source filter
 	Sources []string `json:"sources,omitempty"`
+
+	// Expr holds the conditions of a filter expression, combined with the
+	// filters above.
+	Expr query.Filter `json:"-"`
 }
 
 // ============================================================================
@@ -207,8 +234,21 @@ type DealRepository interface {
 	// Payment queries
 	GetDealsWithPendingPayments(ctx context.Context, tenantID uuid.UUID, opts ListOptions) ([]*Deal, int64, error)
 	GetOverduePayments(ctx context.Context, tenantID uuid.UUID, opts ListOptions) ([]*Deal, int64, error)
+	// GetDealsWithLapsedInvoices retrieves up to limit deals of any tenant
+	// with a sent invoice due before dueBefore, with their invoices loaded.
+	GetDealsWithLapsedInvoices(ctx context.Context, dueBefore time.Time, limit int) ([]*Deal, error)
 	GetFullyPaidDeals(ctx context.Context, tenantID uuid.UUID, opts ListOptions) ([]*Deal, int64, error)
 
+	// Subscription queries
+	// GetDealsDueForRenewal retrieves up to limit recurring deals of any
+	// tenant without a renewal opportunity whose renewal is due by dueBy,
+	// with their line items, invoices and payments loaded.
+	GetDealsDueForRenewal(ctx context.Context, dueBy time.Time, limit int) ([]*Deal, error)
+	GetByRenewalOpportunity(ctx context.Context, tenantID, opportunityID uuid.UUID) (*Deal, error)
+	// GetRecurringDeals retrieves the recurring deals in currency whose
+	// contract runs at some time between from and to.
+	GetRecurringDeals(ctx context.Context, tenantID uuid.UUID, currency string, from, to time.Time) ([]*Deal, error)
+
 	// Fulfillment queries
 	GetDealsForFulfillment(ctx context.Context, tenantID uuid.UUID, opts ListOptions) ([]*Deal, int64, error)
 	GetPartiallyFulfilledDeals(ctx context.Context, tenantID uuid.UUID, opts ListOptions) ([]*Deal, int64, error)
@@ -261,6 +301,10 @@ type DealFilter struct {
 
 	// Deal number search
 	DealNumber *string `json:"deal_number,omitempty"`
+
+	// Expr holds the conditions of a filter expression, combined with the
+	// filters above.
+	Expr query.Filter `json:"-"`
 }
 
 // ============================================================================
@@ -287,6 +331,10 @@ type PipelineRepository interface {
 	RemoveStage(ctx context.Context, tenantID, pipelineID, stageID uuid.UUID) error
 	ReorderStages(ctx context.Context, tenantID, pipelineID uuid.UUID, stageIDs []uuid.UUID) error
 
+	// Version history - a version is recorded on every Create and Update
+	GetVersion(ctx context.Context, tenantID, pipelineID uuid.UUID, version int) (*PipelineVersion, error)
+	ListVersions(ctx context.Context, tenantID, pipelineID uuid.UUID) ([]*PipelineVersion, error)
+
 	// Statistics
 	GetPipelineStatistics(ctx context.Context, tenantID, pipelineID uuid.UUID) (*PipelineStatistics, error)
 	GetStageStatistics(ctx context.Context, tenantID, pipelineID, stageID uuid.UUID) (*StageStatistics, error)
@@ -294,17 +342,27 @@ type PipelineRepository interface {
 
 // PipelineStatistics contains aggregated statistics for a pipeline.
 type PipelineStatistics struct {
-	PipelineID         uuid.UUID             `json:"pipeline_id"`
-	TotalOpportunities int64                 `json:"total_opportunities"`
-	OpenOpportunities  int64                 `json:"open_opportunities"`
-	WonOpportunities   int64                 `json:"won_opportunities"`
-	LostOpportunities  int64                 `json:"lost_opportunities"`
-	TotalValue         Money                 `json:"total_value"`
-	WeightedValue      Money                 `json:"weighted_value"`
-	WinRate            float64               `json:"win_rate"`
-	AverageSalesCycle  int                   `json:"average_sales_cycle_days"`
-	StageDistribution  map[uuid.UUID]int64   `json:"stage_distribution"`
-	ConversionRates    map[uuid.UUID]float64 `json:"conversion_rates"` // stage -> next stage conversion
+	PipelineID         uuid.UUID                    `json:"pipeline_id"`
+	TotalOpportunities int64                        `json:"total_opportunities"`
+	OpenOpportunities  int64                        `json:"open_opportunities"`
+	WonOpportunities   int64                        `json:"won_opportunities"`
+	LostOpportunities  int64                        `json:"lost_opportunities"`
+	TotalValue         Money                        `json:"total_value"`
+	WeightedValue      Money                        `json:"weighted_value"`
+	WinRate            float64                      `json:"win_rate"`
+	AverageSalesCycle  int                          `json:"average_sales_cycle_days"`
+	StageDistribution  map[uuid.UUID]int64          `json:"stage_distribution"`
+	ConversionRates    map[uuid.UUID]float64        `json:"conversion_rates"` // stage -> next stage conversion
+	ForecastCategories []ForecastCategoryStatistics `json:"forecast_categories"`
+}
+
+// ForecastCategoryStatistics contains the open opportunities of a forecast
+// category, in the pipeline currency.
+type ForecastCategoryStatistics struct {
+	Category         ForecastCategory `json:"category"`
+	OpportunityCount int64            `json:"opportunity_count"`
+	TotalValue       Money            `json:"total_value"`
+	WeightedValue    Money            `json:"weighted_value"`
 }
 
 // StageStatistics contains aggregated statistics for a pipeline stage.
@@ -317,6 +375,555 @@ type StageStatistics struct {
 	AverageOpportunityAge int       `json:"average_opportunity_age_days"`
 }
 
+// ============================================================================
+// Report Repository
+// ============================================================================
+
+// ReportRepository defines the interface for pre-aggregated reporting data.
+type ReportRepository interface {
+	// RefreshDailyAggregates recomputes the aggregates of all tenants for the given day
+	// from the source tables and returns the number of rows written.
+	RefreshDailyAggregates(ctx context.Context, day time.Time) (int64, error)
+
+	// GetSalesPerformance returns aggregated sales performance for a period grouped by the given dimension.
+	GetSalesPerformance(ctx context.Context, tenantID uuid.UUID, period ReportPeriod, groupBy ReportGroupBy, currency string) ([]*SalesPerformanceRow, error)
+
+	// GetDailyAggregates returns the raw daily aggregate rows for a dimension.
+	GetDailyAggregates(ctx context.Context, tenantID uuid.UUID, period ReportPeriod, dimension AggregateDimension) ([]*DailySalesAggregate, error)
+
+	// RecordAggregationRun stores the outcome of an aggregation run.
+	RecordAggregationRun(ctx context.Context, run *AggregationRun) error
+
+	// GetLastAggregationRun returns the most recent aggregation run, or nil if none exists.
+	GetLastAggregationRun(ctx context.Context) (*AggregationRun, error)
+}
+
+// ReportExportRepository defines the interface for report export persistence.
+type ReportExportRepository interface {
+	// Create creates a new report export.
+	Create(ctx context.Context, export *ReportExport) error
+
+	// Update updates a report export.
+	Update(ctx context.Context, export *ReportExport) error
+
+	// GetByID retrieves a report export by ID.
+	GetByID(ctx context.Context, tenantID, exportID uuid.UUID) (*ReportExport, error)
+
+	// ListByUser lists the most recent report exports requested by a user.
+	ListByUser(ctx context.Context, tenantID, userID uuid.UUID, limit int) ([]*ReportExport, error)
+}
+
+// ReportBrandingRepository defines the interface for report branding persistence.
+type ReportBrandingRepository interface {
+	// Get retrieves a tenant's report branding, returning nil if none is configured.
+	Get(ctx context.Context, tenantID uuid.UUID) (*ReportBranding, error)
+
+	// Upsert creates or updates a tenant's report branding.
+	Upsert(ctx context.Context, branding *ReportBranding) error
+}
+
+// ============================================================================
+// Exchange Rate Repository
+// ============================================================================
+
+// ExchangeRateRepository defines the interface for exchange rate persistence.
+type ExchangeRateRepository interface {
+	// Upsert creates or replaces the rate for a pair, effective date and source.
+	Upsert(ctx context.Context, rate *ExchangeRate) error
+
+	// GetByID retrieves an exchange rate by ID.
+	GetByID(ctx context.Context, tenantID, rateID uuid.UUID) (*ExchangeRate, error)
+
+	// Delete deletes an exchange rate.
+	Delete(ctx context.Context, tenantID, rateID uuid.UUID) error
+
+	// List lists exchange rates, newest effective date first.
+	List(ctx context.Context, tenantID uuid.UUID, filter ExchangeRateFilter, opts ListOptions) ([]*ExchangeRate, int64, error)
+
+	// ListEffective returns the latest rate of every pair effective at the given time,
+	// with manual rates ordered before provider rates.
+	ListEffective(ctx context.Context, tenantID uuid.UUID, at time.Time) ([]*ExchangeRate, error)
+
+	// GetSettings retrieves a tenant's currency settings, returning nil if none are configured.
+	GetSettings(ctx context.Context, tenantID uuid.UUID) (*CurrencySettings, error)
+
+	// SaveSettings creates or updates a tenant's currency settings.
+	SaveSettings(ctx context.Context, settings *CurrencySettings) error
+
+	// ListSettingsWithProvider returns the settings of all tenants with a rate provider configured.
+	ListSettingsWithProvider(ctx context.Context) ([]*CurrencySettings, error)
+}
+
+// ExchangeRateFilter defines filter options for exchange rate queries.
+type ExchangeRateFilter struct {
+	FromCurrency *string             `json:"from_currency,omitempty"`
+	ToCurrency   *string             `json:"to_currency,omitempty"`
+	Source       *ExchangeRateSource `json:"source,omitempty"`
+}
+
+// ============================================================================
+// Opportunity Reason Repository
+// ============================================================================
+
+// OpportunityReasonRepository defines the interface for win and loss reason catalogs.
+type OpportunityReasonRepository interface {
+	// Create creates a new reason.
+	Create(ctx context.Context, reason *OpportunityReason) error
+
+	// GetByID retrieves a reason by ID.
+	GetByID(ctx context.Context, tenantID, reasonID uuid.UUID) (*OpportunityReason, error)
+
+	// Update updates a reason.
+	Update(ctx context.Context, reason *OpportunityReason) error
+
+	// List lists reasons ordered by type, order and name.
+	List(ctx context.Context, tenantID uuid.UUID, filter OpportunityReasonFilter) ([]*OpportunityReason, error)
+}
+
+// OpportunityReasonFilter defines filtering options for reasons.
+type OpportunityReasonFilter struct {
+	Type       *ReasonType `json:"type,omitempty"`
+	ActiveOnly bool        `json:"active_only,omitempty"`
+}
+
+// ============================================================================
+// Discount Approval Repository
+// ============================================================================
+
+// DiscountApprovalRepository defines the interface for discount approval rules
+// and the approvals requested under them.
+type DiscountApprovalRepository interface {
+	// CreateRule creates a new rule.
+	CreateRule(ctx context.Context, rule *DiscountApprovalRule) error
+
+	// GetRule retrieves a rule by ID.
+	GetRule(ctx context.Context, tenantID, ruleID uuid.UUID) (*DiscountApprovalRule, error)
+
+	// UpdateRule updates a rule.
+	UpdateRule(ctx context.Context, rule *DiscountApprovalRule) error
+
+	// DeleteRule deletes a rule. Approvals keep the IDs of the rules they were requested under.
+	DeleteRule(ctx context.Context, tenantID, ruleID uuid.UUID) error
+
+	// ListRules lists a tenant's rules ordered by name.
+	ListRules(ctx context.Context, tenantID uuid.UUID, activeOnly bool) ([]*DiscountApprovalRule, error)
+
+	// Create creates a new approval.
+	Create(ctx context.Context, approval *DiscountApproval) error
+
+	// GetByID retrieves an approval by ID.
+	GetByID(ctx context.Context, tenantID, approvalID uuid.UUID) (*DiscountApproval, error)
+
+	// Update updates an approval.
+	Update(ctx context.Context, approval *DiscountApproval) error
+
+	// GetLatestByOpportunity retrieves the most recently requested approval of
+	// an opportunity, returning nil if none was requested.
+	GetLatestByOpportunity(ctx context.Context, tenantID, opportunityID uuid.UUID) (*DiscountApproval, error)
+
+	// ListByOpportunity lists an opportunity's approvals, newest first.
+	ListByOpportunity(ctx context.Context, tenantID, opportunityID uuid.UUID) ([]*DiscountApproval, error)
+
+	// ListPendingForApprover lists the pending approvals a user can decide, oldest first.
+	ListPendingForApprover(ctx context.Context, tenantID, approverID uuid.UUID, opts ListOptions) ([]*DiscountApproval, int64, error)
+}
+
+// ============================================================================
+// Order Repository
+// ============================================================================
+
+// OrderFilter contains filter options for listing orders.
+type OrderFilter struct {
+	Statuses   []OrderStatus `json:"statuses,omitempty"`
+	DealID     *uuid.UUID    `json:"deal_id,omitempty"`
+	CustomerID *uuid.UUID    `json:"customer_id,omitempty"`
+	// DeliveryBefore lists orders due for delivery before the time.
+	DeliveryBefore *time.Time `json:"delivery_before,omitempty"`
+}
+
+// OrderRepository defines the interface for production order persistence.
+type OrderRepository interface {
+	// Create creates a new order.
+	Create(ctx context.Context, order *Order) error
+
+	// GetByID retrieves an order by ID.
+	GetByID(ctx context.Context, tenantID, orderID uuid.UUID) (*Order, error)
+
+	// Update updates an order, checking the version it was loaded at.
+	Update(ctx context.Context, order *Order) error
+
+	// GetOpenByDeal retrieves the order of a deal that is not cancelled,
+	// returning nil if there is none.
+	GetOpenByDeal(ctx context.Context, tenantID, dealID uuid.UUID) (*Order, error)
+
+	// List lists orders, newest first.
+	List(ctx context.Context, tenantID uuid.UUID, filter OrderFilter, opts ListOptions) ([]*Order, int64, error)
+}
+
+// OrderWebhookRepository defines the interface for order webhook persistence.
+type OrderWebhookRepository interface {
+	// Get retrieves a tenant's order webhook, returning nil if none is configured.
+	Get(ctx context.Context, tenantID uuid.UUID) (*OrderWebhook, error)
+
+	// Upsert creates or updates a tenant's order webhook.
+	Upsert(ctx context.Context, webhook *OrderWebhook) error
+
+	// Delete removes a tenant's order webhook.
+	Delete(ctx context.Context, tenantID uuid.UUID) error
+}
+
+// ============================================================================
+// Shipment Repository
+// ============================================================================
+
+// ShipmentRepository defines the interface for shipment persistence.
+type ShipmentRepository interface {
+	// Create creates a new shipment.
+	Create(ctx context.Context, shipment *Shipment) error
+
+	// GetByID retrieves a shipment by ID.
+	GetByID(ctx context.Context, tenantID, shipmentID uuid.UUID) (*Shipment, error)
+
+	// Update updates a shipment, checking the version it was loaded at.
+	Update(ctx context.Context, shipment *Shipment) error
+
+	// ListByDeal lists a deal's shipments, newest first.
+	ListByDeal(ctx context.Context, tenantID, dealID uuid.UUID) ([]*Shipment, error)
+
+	// GetByTracking retrieves the shipments of all tenants with a carrier's
+	// tracking number, for updates the carrier pushes.
+	GetByTracking(ctx context.Context, carrier, trackingNumber string) ([]*Shipment, error)
+
+	// GetDueForTracking retrieves shipments of all tenants with the carriers
+	// that are not yet delivered or returned and were last checked before the
+	// time, least recently checked first.
+	GetDueForTracking(ctx context.Context, carriers []string, checkedBefore time.Time, limit int) ([]*Shipment, error)
+}
+
+// ============================================================================
+// E-Invoice Repositories
+// ============================================================================
+
+// EInvoiceRepository defines the interface for e-invoice persistence.
+type EInvoiceRepository interface {
+	// Create creates a new e-invoice.
+	Create(ctx context.Context, einvoice *EInvoice) error
+
+	// GetByID retrieves an e-invoice by ID.
+	GetByID(ctx context.Context, tenantID, einvoiceID uuid.UUID) (*EInvoice, error)
+
+	// Update updates an e-invoice, checking the version it was loaded at.
+	Update(ctx context.Context, einvoice *EInvoice) error
+
+	// GetByInvoice retrieves the latest e-invoice of a deal invoice,
+	// returning nil if the invoice has none.
+	GetByInvoice(ctx context.Context, tenantID, invoiceID uuid.UUID) (*EInvoice, error)
+
+	// GetDue retrieves e-invoices of all tenants that are pending submission
+	// or awaiting validation and are due an attempt by the time, earliest
+	// first.
+	GetDue(ctx context.Context, dueBy time.Time, limit int) ([]*EInvoice, error)
+}
+
+// EInvoiceSettingsRepository defines the interface for tenants' e-invoice
+// supplier details.
+type EInvoiceSettingsRepository interface {
+	// Get retrieves a tenant's e-invoice settings, returning nil if none are configured.
+	Get(ctx context.Context, tenantID uuid.UUID) (*EInvoiceSettings, error)
+
+	// Upsert creates or updates a tenant's e-invoice settings.
+	Upsert(ctx context.Context, settings *EInvoiceSettings) error
+}
+
+// ============================================================================
+// Accounting Repositories
+// ============================================================================
+
+// AccountingConnectionRepository defines the interface for tenants'
+// accounting system connections.
+type AccountingConnectionRepository interface {
+	// Get retrieves a tenant's connection, returning nil if none is configured.
+	Get(ctx context.Context, tenantID uuid.UUID) (*AccountingConnection, error)
+
+	// Upsert creates or updates a tenant's connection.
+	Upsert(ctx context.Context, connection *AccountingConnection) error
+
+	// Delete removes a tenant's connection.
+	Delete(ctx context.Context, tenantID uuid.UUID) error
+
+	// ListEnabled retrieves the enabled connections of all tenants.
+	ListEnabled(ctx context.Context) ([]*AccountingConnection, error)
+}
+
+// AccountingSyncRepository defines the interface for accounting sync persistence.
+type AccountingSyncRepository interface {
+	// Create creates a new sync record. It returns ErrAccountingSyncAlreadyExists
+	// if the record already has one.
+	Create(ctx context.Context, sync *AccountingSync) error
+
+	// GetByID retrieves a sync record by ID.
+	GetByID(ctx context.Context, tenantID, syncID uuid.UUID) (*AccountingSync, error)
+
+	// Update updates a sync record, checking the version it was loaded at.
+	Update(ctx context.Context, sync *AccountingSync) error
+
+	// GetByRecord retrieves the sync record of an invoice or payment,
+	// returning nil if it has none.
+	GetByRecord(ctx context.Context, tenantID uuid.UUID, recordType AccountingRecordType, recordID uuid.UUID) (*AccountingSync, error)
+
+	// GetByExternalID retrieves the sync record of a record known to the
+	// accounting system by its ID there, returning nil if there is none.
+	GetByExternalID(ctx context.Context, tenantID uuid.UUID, recordType AccountingRecordType, externalID string) (*AccountingSync, error)
+
+	// List retrieves a tenant's sync records in the given statuses, or all
+	// of them without statuses, most recently updated first.
+	List(ctx context.Context, tenantID uuid.UUID, statuses []AccountingSyncStatus, limit int) ([]*AccountingSync, error)
+
+	// CountByStatus counts a tenant's sync records by status.
+	CountByStatus(ctx context.Context, tenantID uuid.UUID) (map[AccountingSyncStatus]int, error)
+
+	// GetDue retrieves pending sync records of all tenants that are due a
+	// push by the time, earliest first.
+	GetDue(ctx context.Context, dueBy time.Time, limit int) ([]*AccountingSync, error)
+
+	// FindUnsynced retrieves a tenant's issued invoices and payments that
+	// have no sync record, oldest first, invoices before their payments.
+	FindUnsynced(ctx context.Context, tenantID uuid.UUID, limit int) ([]*UnsyncedAccountingRecord, error)
+}
+
+// ============================================================================
+// E-Commerce Repositories
+// ============================================================================
+
+// EcommerceStoreRepository defines the interface for online store connectors.
+type EcommerceStoreRepository interface {
+	Create(ctx context.Context, store *EcommerceStore) error
+	GetByID(ctx context.Context, tenantID, storeID uuid.UUID) (*EcommerceStore, error)
+
+	// Update updates a store, checking the version it was loaded at.
+	Update(ctx context.Context, store *EcommerceStore) error
+	Delete(ctx context.Context, tenantID, storeID uuid.UUID) error
+
+	// List retrieves a tenant's stores, by name.
+	List(ctx context.Context, tenantID uuid.UUID) ([]*EcommerceStore, error)
+
+	// GetForWebhook retrieves a store by ID for any tenant, for verifying
+	// the webhooks it pushes.
+	GetForWebhook(ctx context.Context, storeID uuid.UUID) (*EcommerceStore, error)
+
+	// ListEnabled retrieves the enabled stores of all tenants.
+	ListEnabled(ctx context.Context) ([]*EcommerceStore, error)
+}
+
+// EcommerceImportRepository defines the interface for store import records.
+type EcommerceImportRepository interface {
+	// Create creates a new import. It returns ErrEcommerceImportAlreadyExists
+	// if the store record already has one.
+	Create(ctx context.Context, record *EcommerceImport) error
+
+	// Update updates an import, checking the version it was loaded at.
+	Update(ctx context.Context, record *EcommerceImport) error
+
+	// GetByExternalID retrieves the import of a store record, returning nil
+	// if it has none.
+	GetByExternalID(ctx context.Context, tenantID, storeID uuid.UUID, kind EcommerceImportKind, externalID string) (*EcommerceImport, error)
+
+	// List retrieves a store's imports in the given statuses, or all of them
+	// without statuses, most recently updated first.
+	List(ctx context.Context, tenantID, storeID uuid.UUID, statuses []EcommerceImportStatus, limit int) ([]*EcommerceImport, error)
+
+	// Summarize counts a store's imports.
+	Summarize(ctx context.Context, tenantID, storeID uuid.UUID) (*EcommerceImportSummary, error)
+}
+
+// ============================================================================
+// Opportunity Board Repository
+// ============================================================================
+
+// OpportunityBoardRepository defines the interface for the opportunity board read model.
+type OpportunityBoardRepository interface {
+	// UpsertCard creates or replaces a card, ignoring cards older than the stored one.
+	UpsertCard(ctx context.Context, card *OpportunityBoardCard) error
+
+	// DeleteCard removes an opportunity's card, if any.
+	DeleteCard(ctx context.Context, tenantID, opportunityID uuid.UUID) error
+
+	// ListColumnCards lists the cards of one board column, newest in the stage
+	// first, continuing after the cursor if one is given.
+	ListColumnCards(ctx context.Context, tenantID, pipelineID, stageID uuid.UUID, after *BoardCursor, limit int) ([]*OpportunityBoardCard, error)
+
+	// GetColumnTotals returns the card counts and values of every column of a board.
+	GetColumnTotals(ctx context.Context, tenantID, pipelineID uuid.UUID) ([]*BoardColumnTotal, error)
+
+	// ReplacePipelineCards replaces every card of a pipeline in one transaction.
+	ReplacePipelineCards(ctx context.Context, tenantID, pipelineID uuid.UUID, cards []*OpportunityBoardCard) error
+}
+
+// ============================================================================
+// Stage Funnel Repository
+// ============================================================================
+
+// StageFunnelRepository defines the interface for the stage funnel read model.
+type StageFunnelRepository interface {
+	// ReplaceOpportunityEntries replaces every funnel entry of an opportunity.
+	// An empty list removes the opportunity from the funnel.
+	ReplaceOpportunityEntries(ctx context.Context, tenantID, opportunityID uuid.UUID, entries []*StageFunnelEntry) error
+
+	// ReplacePipelineEntries replaces every funnel entry of a pipeline in one transaction.
+	ReplacePipelineEntries(ctx context.Context, tenantID, pipelineID uuid.UUID, entries []*StageFunnelEntry) error
+
+	// GetStageDurations returns, per stage, how many of the cohort's
+	// opportunities entered it and how long completed visits lasted.
+	GetStageDurations(ctx context.Context, tenantID, pipelineID uuid.UUID, filter StageFunnelFilter) ([]*StageDurationStatistics, error)
+
+	// GetFurthestStages counts the cohort's opportunities by the furthest
+	// stage they entered, ignoring lost stages.
+	GetFurthestStages(ctx context.Context, tenantID, pipelineID uuid.UUID, filter StageFunnelFilter) ([]*StageReachCount, error)
+}
+
+// ============================================================================
+// Insight Repository
+// ============================================================================
+
+// InsightRepository defines the interface for insight settings, detected
+// insights and the figures they are detected from.
+type InsightRepository interface {
+	// GetSettings retrieves a tenant's insight settings, returning nil if none are configured.
+	GetSettings(ctx context.Context, tenantID uuid.UUID) (*InsightSettings, error)
+
+	// UpsertSettings creates or updates a tenant's insight settings.
+	UpsertSettings(ctx context.Context, settings *InsightSettings) error
+
+	// Record stores a detected insight, reporting false if the tenant already
+	// has one of the same type and period key.
+	Record(ctx context.Context, insight *Insight) (bool, error)
+
+	// List returns a tenant's most recent insights, optionally of one type.
+	List(ctx context.Context, tenantID uuid.UUID, insightType *InsightType, limit int) ([]*Insight, error)
+
+	// ListActiveTenants returns the tenants that created or changed leads or
+	// opportunities since a time.
+	ListActiveTenants(ctx context.Context, since time.Time) ([]uuid.UUID, error)
+
+	// GetBiggestWonAmount returns the largest amount in currency of the
+	// opportunities won in a period, other than the one excluded.
+	GetBiggestWonAmount(ctx context.Context, tenantID uuid.UUID, currency string, period ReportPeriod, excludeID uuid.UUID) (int64, error)
+
+	// GetQuarterPipeline returns, in currency, the amount won in a quarter
+	// and the open pipeline expected to close in it.
+	GetQuarterPipeline(ctx context.Context, tenantID uuid.UUID, currency string, quarter ReportPeriod) (won, open int64, err error)
+
+	// CountLeads returns the number of leads created in a period.
+	CountLeads(ctx context.Context, tenantID uuid.UUID, period ReportPeriod) (int64, error)
+}
+
+// ============================================================================
+// Tax Settings Repository
+// ============================================================================
+
+// TaxSettingsRepository defines the interface for tax settings persistence.
+type TaxSettingsRepository interface {
+	// Get retrieves a tenant's tax settings, returning nil if none are configured.
+	Get(ctx context.Context, tenantID uuid.UUID) (*TaxSettings, error)
+
+	// Upsert creates or updates a tenant's tax settings.
+	Upsert(ctx context.Context, settings *TaxSettings) error
+}
+
+// ============================================================================
+// Pricing Settings Repository
+// ============================================================================
+
+// PricingSettingsRepository defines the interface for price list persistence.
+type PricingSettingsRepository interface {
+	// Get retrieves a tenant's pricing settings, returning nil if none are configured.
+	Get(ctx context.Context, tenantID uuid.UUID) (*PricingSettings, error)
+
+	// Upsert creates or updates a tenant's pricing settings.
+	Upsert(ctx context.Context, settings *PricingSettings) error
+}
+
+// ============================================================================
+// Inbound Email Repositories
+// ============================================================================
+
+// InboundMailboxRepository defines the interface for inbound mailbox persistence.
+type InboundMailboxRepository interface {
+	// Get retrieves a tenant's mailbox, returning nil if none is configured.
+	Get(ctx context.Context, tenantID uuid.UUID) (*InboundMailbox, error)
+
+	// GetByToken retrieves the mailbox of an address across all tenants.
+	GetByToken(ctx context.Context, token string) (*InboundMailbox, error)
+
+	// Upsert creates or updates a tenant's mailbox.
+	Upsert(ctx context.Context, mailbox *InboundMailbox) error
+}
+
+// InboundEmailRepository defines the interface for ingested email persistence.
+type InboundEmailRepository interface {
+	// Create records an ingested email, returning ErrInboundEmailDuplicate if
+	// its message ID was already recorded for the tenant.
+	Create(ctx context.Context, email *InboundEmail) error
+
+	// ExistsByMessageID checks whether an email was already recorded.
+	ExistsByMessageID(ctx context.Context, tenantID uuid.UUID, messageID string) (bool, error)
+
+	// CountFromSenderSince counts the emails recorded from a sender since a time.
+	CountFromSenderSince(ctx context.Context, tenantID uuid.UUID, fromAddress string, since time.Time) (int, error)
+
+	// ListByLead lists the emails logged on a lead, newest first.
+	ListByLead(ctx context.Context, tenantID, leadID uuid.UUID, opts ListOptions) ([]*InboundEmail, int64, error)
+}
+
+// ============================================================================
+// Customer Data Erasure
+// ============================================================================
+
+// CustomerDataEraser erases a customer's personal data from the sales records.
+type CustomerDataEraser interface {
+	// EraseCustomer anonymizes the customer's name and contacts on its
+	// opportunities and deals, and the leads and inbound emails that match the
+	// customer or one of the hashes of its email addresses and phone numbers.
+	// Amounts, stages and dates are kept. It returns the number of records
+	// changed.
+	EraseCustomer(ctx context.Context, tenantID, customerID uuid.UUID, identifierHashes []string) (int64, error)
+}
+
+// ============================================================================
+// Tenant Data Export
+// ============================================================================
+
+// Tenant export data sets owned by the sales service.
+const (
+	TenantExportLeads         = "leads"
+	TenantExportOpportunities = "opportunities"
+)
+
+// TenantDataExporter writes a tenant's sales records for a tenant data
+// export.
+type TenantDataExporter interface {
+	// ExportTenantData writes every live record of the data set to w as one
+	// JSON object per line, oldest first, and returns the number written.
+	ExportTenantData(ctx context.Context, tenantID uuid.UUID, dataset string, w io.Writer) (int64, error)
+}
+
+// ============================================================================
+// Demo Data
+// ============================================================================
+
+// DemoDataRepository finds and removes the demo records seeded into a tenant.
+type DemoDataRepository interface {
+	// FindExisting returns which of the pipelines, leads and opportunities
+	// with the given IDs exist in the tenant, soft-deleted ones included.
+	FindExisting(ctx context.Context, tenantID uuid.UUID, ids []uuid.UUID) (map[uuid.UUID]bool, error)
+
+	// Purge permanently deletes the tenant's opportunities, leads and
+	// pipelines with the given IDs, and returns the number deleted.
+	// Opportunities that became deals and pipelines still holding other
+	// opportunities are kept.
+	Purge(ctx context.Context, tenantID uuid.UUID, ids []uuid.UUID) (int64, error)
+}
+
 // ============================================================================
 // Common Types
 // ============================================================================
@@ -333,6 +940,17 @@ type ListOptions struct {
 
 	// Include soft-deleted records
 	IncludeDeleted bool `json:"include_deleted"`
+
+	// Keyset pagination. When set, the list continues after this position
+	// instead of skipping Page-1 pages.
+	After *PageCursor `json:"-"`
+}
+
+// PageCursor is a keyset pagination position: the sort value of the last item
+// seen and its ID, which breaks ties between equal sort values.
+type PageCursor struct {
+	SortValue string
+	ID        uuid.UUID
 }
 
 // DefaultListOptions returns default list options.
@@ -388,6 +1006,24 @@ type EventStore interface {
 
 	// GetEventsInRange retrieves events within a time range.
 	GetEventsInRange(ctx context.Context, tenantID uuid.UUID, start, end time.Time, opts ListOptions) ([]DomainEvent, error)
+
+	// ReadEvents reads recorded events in store order, oldest first.
+	ReadEvents(ctx context.Context, filter EventRecordFilter) ([]*EventRecord, error)
+}
+
+// EventRecordFilter defines filter options for reading the event store.
+type EventRecordFilter struct {
+	// TenantID restricts the read to one tenant; nil reads every tenant.
+	TenantID      *uuid.UUID `json:"tenant_id,omitempty"`
+	AggregateID   *uuid.UUID `json:"aggregate_id,omitempty"`
+	AggregateType *string    `json:"aggregate_type,omitempty"`
+	EventTypes    []string   `json:"event_types,omitempty"`
+	// OccurredFrom and OccurredTo bound the event time, inclusive and exclusive.
+	OccurredFrom *time.Time `json:"occurred_from,omitempty"`
+	OccurredTo   *time.Time `json:"occurred_to,omitempty"`
+	// AfterPosition resumes a read after the last position already seen.
+	AfterPosition int64 `json:"after_position,omitempty"`
+	Limit         int   `json:"limit,omitempty"`
 }
 
 // ============================================================================
@@ -455,6 +1091,118 @@ type IdempotencyRepository interface {
 	Extend(ctx context.Context, tenantID uuid.UUID, key string, newExpiry time.Time) error
 }
 
+// ============================================================================
+// Data Retention Repositories
+// ============================================================================
+
+// RetentionPolicyRepository defines the interface for retention policy persistence.
+type RetentionPolicyRepository interface {
+	// List lists a tenant's retention policies.
+	List(ctx context.Context, tenantID uuid.UUID) ([]*RetentionPolicy, error)
+
+	// Get retrieves a tenant's policy for an entity type, returning nil if none is configured.
+	Get(ctx context.Context, tenantID uuid.UUID, entityType RetentionEntityType) (*RetentionPolicy, error)
+
+	// ListEnabled lists the enabled policies of all tenants, least recently run first.
+	ListEnabled(ctx context.Context) ([]*RetentionPolicy, error)
+
+	// Upsert creates or updates a policy.
+	Upsert(ctx context.Context, policy *RetentionPolicy) error
+
+	// Delete deletes a policy.
+	Delete(ctx context.Context, tenantID uuid.UUID, entityType RetentionEntityType) error
+
+	// MarkRun records when a policy was last applied.
+	MarkRun(ctx context.Context, tenantID uuid.UUID, entityType RetentionEntityType, at time.Time) error
+}
+
+// ArchivedRecordRepository defines the interface for the index of archived records.
+type ArchivedRecordRepository interface {
+	// CreateBatch records archived entities.
+	CreateBatch(ctx context.Context, records []*ArchivedRecord) error
+
+	// GetByID retrieves an archived record.
+	GetByID(ctx context.Context, tenantID, recordID uuid.UUID) (*ArchivedRecord, error)
+
+	// List lists a tenant's archived records, newest first.
+	List(ctx context.Context, tenantID uuid.UUID, filter ArchivedRecordFilter, opts ListOptions) ([]*ArchivedRecord, int64, error)
+
+	// MarkRestored records that an archived entity was restored.
+	MarkRestored(ctx context.Context, record *ArchivedRecord) error
+}
+
+// ArchivedRecordFilter defines filter options for archived record queries.
+type ArchivedRecordFilter struct {
+	EntityType      *RetentionEntityType `json:"entity_type,omitempty"`
+	EntityID        *uuid.UUID           `json:"entity_id,omitempty"`
+	IncludeRestored bool                 `json:"include_restored,omitempty"`
+}
+
+// RetentionStore reads and removes the stored rows retention policies apply to.
+type RetentionStore interface {
+	// FindExpired finds up to limit records of an entity type that were last
+	// changed before cutoff and may be archived or purged, oldest first.
+	FindExpired(ctx context.Context, tenantID uuid.UUID, entityType RetentionEntityType, cutoff time.Time, limit int) ([]RetainedRecord, error)
+
+	// Delete permanently deletes records.
+	Delete(ctx context.Context, tenantID uuid.UUID, entityType RetentionEntityType, ids []uuid.UUID) (int64, error)
+
+	// Restore inserts an archived row back, as returned by FindExpired.
+	Restore(ctx context.Context, tenantID uuid.UUID, entityType RetentionEntityType, data []byte) error
+}
+
+// ============================================================================
+// Comment Repository
+// ============================================================================
+
+// CommentRepository defines the interface for comments and their reactions.
+// Comments are loaded with their reactions, and top-level comments with
+// their reply counts.
+type CommentRepository interface {
+	// Create stores a new comment.
+	Create(ctx context.Context, comment *Comment) error
+
+	// Update stores an edited or deleted comment, returning
+	// ErrCommentModified if it changed since it was read.
+	Update(ctx context.Context, comment *Comment) error
+
+	// GetByID retrieves a comment of a tenant, returning ErrCommentNotFound
+	// if there is none.
+	GetByID(ctx context.Context, tenantID, commentID uuid.UUID) (*Comment, error)
+
+	// ListThreads lists the top-level comments on a record, newest first.
+	ListThreads(ctx context.Context, tenantID uuid.UUID, entityType CommentEntityType, entityID uuid.UUID, opts ListOptions) ([]*Comment, int64, error)
+
+	// ListReplies lists the replies of a thread, oldest first.
+	ListReplies(ctx context.Context, tenantID, threadID uuid.UUID, opts ListOptions) ([]*Comment, int64, error)
+
+	// AddReaction adds a user's reaction to a comment; adding it again has
+	// no effect.
+	AddReaction(ctx context.Context, tenantID, commentID, userID uuid.UUID, reaction CommentReaction) error
+
+	// RemoveReaction removes a user's reaction from a comment.
+	RemoveReaction(ctx context.Context, tenantID, commentID, userID uuid.UUID, reaction CommentReaction) error
+}
+
+// RevenueSplitRepository defines the interface for the revenue splits of
+// opportunities and the history of their changes.
+type RevenueSplitRepository interface {
+	// GetByOpportunity retrieves the splits of an opportunity, which are
+	// empty when its owner is credited in full.
+	GetByOpportunity(ctx context.Context, tenantID, opportunityID uuid.UUID) (RevenueSplits, error)
+
+	// GetByOpportunities retrieves the splits of the opportunities that have
+	// any, by opportunity ID.
+	GetByOpportunities(ctx context.Context, tenantID uuid.UUID, opportunityIDs []uuid.UUID) (map[uuid.UUID]RevenueSplits, error)
+
+	// Apply replaces the splits of an opportunity with those of a change and
+	// records the change in its history.
+	Apply(ctx context.Context, change *RevenueSplitChange) error
+
+	// ListChanges lists the changes of an opportunity's splits, newest first.
+	ListChanges(ctx context.Context, tenantID, opportunityID uuid.UUID, opts ListOptions) ([]*RevenueSplitChange, int64, error)
+}
+
 // ============================================================================
 // Unit of Work Interface
 // ============================================================================
@@ -0,0 +1,186 @@
+package domain
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ============================================================================
+// Data Retention Errors
+// ============================================================================
+
+var (
+	ErrRetentionPolicyNotFound = errors.New("retention policy not found")
+	ErrInvalidRetentionEntity  = errors.New("invalid retention entity type")
+	ErrInvalidRetentionAction  = errors.New("invalid retention action")
+	ErrRetentionPeriodTooShort = errors.New("retention period is shorter than the minimum")
+	ErrArchivedRecordNotFound  = errors.New("archived record not found")
+	ErrArchivedRecordRestored  = errors.New("archived record already restored")
+)
+
+// MinRetentionDays is the shortest period a retention policy can keep
+// records for, so a mistyped policy cannot sweep away recent work.
+const MinRetentionDays = 30
+
+// ============================================================================
+// Retention Entity Type
+// ============================================================================
+
+// RetentionEntityType identifies the records a retention policy applies to.
+type RetentionEntityType string
+
+const (
+	// RetentionEntityLead covers closed leads: converted or unqualified leads
+	// that no opportunity was created from.
+	RetentionEntityLead RetentionEntityType = "lead"
+)
+
+// IsValid checks if the entity type is valid.
+func (t RetentionEntityType) IsValid() bool {
+	switch t {
+	case RetentionEntityLead:
+		return true
+	}
+	return false
+}
+
+// ============================================================================
+// Retention Action
+// ============================================================================
+
+// RetentionAction is what happens to records once their retention passes.
+type RetentionAction string
+
+const (
+	// RetentionActionArchive moves records to cold storage, from where they
+	// can be restored.
+	RetentionActionArchive RetentionAction = "archive"
+	// RetentionActionPurge deletes records permanently.
+	RetentionActionPurge RetentionAction = "purge"
+)
+
+// IsValid checks if the action is valid.
+func (a RetentionAction) IsValid() bool {
+	switch a {
+	case RetentionActionArchive, RetentionActionPurge:
+		return true
+	}
+	return false
+}
+
+// ============================================================================
+// Retention Policy
+// ============================================================================
+
+// RetentionPolicy is a tenant's rule for how long records of one entity type
+// are kept before they are archived or purged.
+type RetentionPolicy struct {
+	TenantID   uuid.UUID           `json:"tenant_id" db:"tenant_id"`
+	EntityType RetentionEntityType `json:"entity_type" db:"entity_type"`
+	Action     RetentionAction     `json:"action" db:"action"`
+	AfterDays  int                 `json:"after_days" db:"after_days"`
+	Enabled    bool                `json:"enabled" db:"enabled"`
+	LastRunAt  *time.Time          `json:"last_run_at,omitempty" db:"last_run_at"`
+	UpdatedBy  *uuid.UUID          `json:"updated_by,omitempty" db:"updated_by"`
+	CreatedAt  time.Time           `json:"created_at" db:"created_at"`
+	UpdatedAt  time.Time           `json:"updated_at" db:"updated_at"`
+}
+
+// NewRetentionPolicy creates an enabled retention policy.
+func NewRetentionPolicy(tenantID uuid.UUID, entityType RetentionEntityType, action RetentionAction, afterDays int, updatedBy *uuid.UUID) (*RetentionPolicy, error) {
+	if !entityType.IsValid() {
+		return nil, ErrInvalidRetentionEntity
+	}
+
+	now := time.Now().UTC()
+	policy := &RetentionPolicy{
+		TenantID:   tenantID,
+		EntityType: entityType,
+		Enabled:    true,
+		CreatedAt:  now,
+	}
+	if err := policy.Update(action, afterDays, true, updatedBy); err != nil {
+		return nil, err
+	}
+	return policy, nil
+}
+
+// Update changes what the policy does and when.
+func (p *RetentionPolicy) Update(action RetentionAction, afterDays int, enabled bool, updatedBy *uuid.UUID) error {
+	if !action.IsValid() {
+		return ErrInvalidRetentionAction
+	}
+	if afterDays < MinRetentionDays {
+		return ErrRetentionPeriodTooShort
+	}
+
+	p.Action = action
+	p.AfterDays = afterDays
+	p.Enabled = enabled
+	p.UpdatedBy = updatedBy
+	p.UpdatedAt = time.Now().UTC()
+	return nil
+}
+
+// Cutoff returns the time before which records are past their retention.
+func (p *RetentionPolicy) Cutoff(now time.Time) time.Time {
+	return now.AddDate(0, 0, -p.AfterDays)
+}
+
+// ============================================================================
+// Archived Record
+// ============================================================================
+
+// ArchivedRecord indexes a record moved to cold storage. StorageKey is the
+// archive file holding it, one JSON row per line.
+type ArchivedRecord struct {
+	ID         uuid.UUID           `json:"id" db:"id"`
+	TenantID   uuid.UUID           `json:"tenant_id" db:"tenant_id"`
+	EntityType RetentionEntityType `json:"entity_type" db:"entity_type"`
+	EntityID   uuid.UUID           `json:"entity_id" db:"entity_id"`
+	StorageKey string              `json:"storage_key" db:"storage_key"`
+	ArchivedAt time.Time           `json:"archived_at" db:"archived_at"`
+	RestoredAt *time.Time          `json:"restored_at,omitempty" db:"restored_at"`
+	RestoredBy *uuid.UUID          `json:"restored_by,omitempty" db:"restored_by"`
+}
+
+// NewArchivedRecord records that an entity was archived to a storage key.
+func NewArchivedRecord(tenantID uuid.UUID, entityType RetentionEntityType, entityID uuid.UUID, storageKey string, archivedAt time.Time) *ArchivedRecord {
+	return &ArchivedRecord{
+		ID:         uuid.New(),
+		TenantID:   tenantID,
+		EntityType: entityType,
+		EntityID:   entityID,
+		StorageKey: storageKey,
+		ArchivedAt: archivedAt,
+	}
+}
+
+// IsRestored reports whether the record was restored from the archive.
+func (r *ArchivedRecord) IsRestored() bool {
+	return r.RestoredAt != nil
+}
+
+// MarkRestored records that the entity was put back by a user.
+func (r *ArchivedRecord) MarkRestored(userID uuid.UUID) error {
+	if r.IsRestored() {
+		return ErrArchivedRecordRestored
+	}
+	now := time.Now().UTC()
+	r.RestoredAt = &now
+	r.RestoredBy = &userID
+	return nil
+}
+
+// ============================================================================
+// Retained Record
+// ============================================================================
+
+// RetainedRecord is a stored row due for archiving, serialized as JSON with
+// every column so it can be inserted back unchanged.
+type RetainedRecord struct {
+	ID   uuid.UUID
+	Data []byte
+}
@@ -0,0 +1,65 @@
+package domain
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestNewRetentionPolicy(t *testing.T) {
+	tests := []struct {
+		name       string
+		entityType RetentionEntityType
+		action     RetentionAction
+		afterDays  int
+		wantErr    error
+	}{
+		{"archive leads", RetentionEntityLead, RetentionActionArchive, 730, nil},
+		{"purge leads", RetentionEntityLead, RetentionActionPurge, MinRetentionDays, nil},
+		{"unknown entity", RetentionEntityType("invoice"), RetentionActionArchive, 730, ErrInvalidRetentionEntity},
+		{"unknown action", RetentionEntityLead, RetentionAction("shred"), 730, ErrInvalidRetentionAction},
+		{"too short", RetentionEntityLead, RetentionActionPurge, MinRetentionDays - 1, ErrRetentionPeriodTooShort},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			policy, err := NewRetentionPolicy(uuid.New(), tt.entityType, tt.action, tt.afterDays, nil)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("NewRetentionPolicy() error = %v, want %v", err, tt.wantErr)
+			}
+			if err == nil && !policy.Enabled {
+				t.Error("NewRetentionPolicy() should start enabled")
+			}
+		})
+	}
+}
+
+func TestRetentionPolicy_Cutoff(t *testing.T) {
+	policy, err := NewRetentionPolicy(uuid.New(), RetentionEntityLead, RetentionActionArchive, 730, nil)
+	if err != nil {
+		t.Fatalf("NewRetentionPolicy() error = %v", err)
+	}
+
+	now := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	want := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	if got := policy.Cutoff(now); !got.Equal(want) {
+		t.Errorf("Cutoff() = %v, want %v", got, want)
+	}
+}
+
+func TestArchivedRecord_MarkRestored(t *testing.T) {
+	record := NewArchivedRecord(uuid.New(), RetentionEntityLead, uuid.New(), "retention/lead/2026-03-01/a.jsonl", time.Now())
+	userID := uuid.New()
+
+	if err := record.MarkRestored(userID); err != nil {
+		t.Fatalf("MarkRestored() error = %v", err)
+	}
+	if !record.IsRestored() || *record.RestoredBy != userID {
+		t.Errorf("expected the record restored by %s, got %+v", userID, record)
+	}
+	if err := record.MarkRestored(userID); !errors.Is(err, ErrArchivedRecordRestored) {
+		t.Errorf("MarkRestored() twice error = %v, want %v", err, ErrArchivedRecordRestored)
+	}
+}
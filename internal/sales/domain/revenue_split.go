@@ -0,0 +1,163 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Revenue split errors
+var (
+	ErrRevenueSplitsRequired       = errors.New("at least one revenue split is required")
+	ErrTooManyRevenueSplits        = errors.New("opportunity has too many revenue splits")
+	ErrRevenueSplitUserRequired    = errors.New("revenue split user is required")
+	ErrDuplicateRevenueSplitUser   = errors.New("user has more than one revenue split")
+	ErrInvalidRevenueSplitPercent  = errors.New("revenue split percent must be between 1 and 100")
+	ErrRevenueSplitTotalNot100     = errors.New("revenue split percents must total 100")
+	ErrRevenueSplitReasonTooLong   = errors.New("revenue split reason is too long")
+	ErrRevenueSplitOpportunityLost = errors.New("revenue of a lost opportunity cannot be split")
+)
+
+// Limits of revenue splits.
+const (
+	MaxRevenueSplits            = 10
+	MaxRevenueSplitReasonLength = 500
+)
+
+// RevenueSplit is the share of an opportunity's revenue credited to a user.
+type RevenueSplit struct {
+	UserID   uuid.UUID `json:"user_id" bson:"user_id"`
+	UserName string    `json:"user_name" bson:"user_name"`
+	Percent  int       `json:"percent" bson:"percent"`
+}
+
+// RevenueSplits are the shares of an opportunity's revenue, in the order
+// they were set. An opportunity without splits credits its owner in full.
+type RevenueSplits []RevenueSplit
+
+// OwnerRevenueSplits returns the splits of an opportunity without any: all
+// of its revenue to the owner.
+func OwnerRevenueSplits(opp *Opportunity) RevenueSplits {
+	return RevenueSplits{{UserID: opp.OwnerID, UserName: opp.OwnerName, Percent: 100}}
+}
+
+// Validate checks that the splits share the revenue among different users
+// and total 100 percent.
+func (s RevenueSplits) Validate() error {
+	if len(s) == 0 {
+		return ErrRevenueSplitsRequired
+	}
+	if len(s) > MaxRevenueSplits {
+		return fmt.Errorf("%w: at most %d", ErrTooManyRevenueSplits, MaxRevenueSplits)
+	}
+
+	seen := make(map[uuid.UUID]bool, len(s))
+	total := 0
+	for _, split := range s {
+		if split.UserID == uuid.Nil {
+			return ErrRevenueSplitUserRequired
+		}
+		if seen[split.UserID] {
+			return fmt.Errorf("%w: %s", ErrDuplicateRevenueSplitUser, split.UserID)
+		}
+		seen[split.UserID] = true
+		if split.Percent < 1 || split.Percent > 100 {
+			return ErrInvalidRevenueSplitPercent
+		}
+		total += split.Percent
+	}
+	if total != 100 {
+		return fmt.Errorf("%w, got %d", ErrRevenueSplitTotalNot100, total)
+	}
+	return nil
+}
+
+// Credits divides amount among the splits by their percents. Each credit is
+// rounded down at the running total, so the credits always add up to amount
+// and the remainder goes to the later splits.
+func (s RevenueSplits) Credits(amount int64) []int64 {
+	credits := make([]int64, len(s))
+	cumulative := int64(0)
+	for i, split := range s {
+		previous := amount * cumulative / 100
+		cumulative += int64(split.Percent)
+		credits[i] = amount*cumulative/100 - previous
+	}
+	return credits
+}
+
+// CreditFor returns the part of amount credited to a user, which is zero
+// for users without a split.
+func (s RevenueSplits) CreditFor(userID uuid.UUID, amount int64) int64 {
+	for i, credit := range s.Credits(amount) {
+		if s[i].UserID == userID {
+			return credit
+		}
+	}
+	return 0
+}
+
+// Equal reports whether both splits credit the same users the same percents
+// in the same order.
+func (s RevenueSplits) Equal(other RevenueSplits) bool {
+	if len(s) != len(other) {
+		return false
+	}
+	for i := range s {
+		if s[i].UserID != other[i].UserID || s[i].Percent != other[i].Percent {
+			return false
+		}
+	}
+	return true
+}
+
+// RevenueSplitChange records a change of an opportunity's revenue splits.
+// Empty splits mean the splits were cleared, crediting the owner in full
+// again.
+type RevenueSplitChange struct {
+	ID             uuid.UUID     `json:"id" bson:"_id"`
+	TenantID       uuid.UUID     `json:"tenant_id" bson:"tenant_id"`
+	OpportunityID  uuid.UUID     `json:"opportunity_id" bson:"opportunity_id"`
+	Splits         RevenueSplits `json:"splits" bson:"splits"`
+	PreviousSplits RevenueSplits `json:"previous_splits" bson:"previous_splits"`
+	Reason         string        `json:"reason,omitempty" bson:"reason,omitempty"`
+	ChangedBy      uuid.UUID     `json:"changed_by" bson:"changed_by"`
+	ChangedAt      time.Time     `json:"changed_at" bson:"changed_at"`
+}
+
+// NewRevenueSplitChange records a change of an opportunity's splits from
+// previous to splits. Splits are validated unless they are being cleared.
+func NewRevenueSplitChange(opp *Opportunity, previous, splits RevenueSplits, changedBy uuid.UUID, reason string) (*RevenueSplitChange, error) {
+	if opp.Status == OpportunityStatusLost {
+		return nil, ErrRevenueSplitOpportunityLost
+	}
+	if len(splits) > 0 {
+		if err := splits.Validate(); err != nil {
+			return nil, err
+		}
+	}
+	reason = strings.TrimSpace(reason)
+	if len(reason) > MaxRevenueSplitReasonLength {
+		return nil, ErrRevenueSplitReasonTooLong
+	}
+	if previous == nil {
+		previous = RevenueSplits{}
+	}
+	if splits == nil {
+		splits = RevenueSplits{}
+	}
+
+	return &RevenueSplitChange{
+		ID:             uuid.New(),
+		TenantID:       opp.TenantID,
+		OpportunityID:  opp.ID,
+		Splits:         splits,
+		PreviousSplits: previous,
+		Reason:         reason,
+		ChangedBy:      changedBy,
+		ChangedAt:      time.Now().UTC(),
+	}, nil
+}
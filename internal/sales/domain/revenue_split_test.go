@@ -0,0 +1,114 @@
+package domain
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestRevenueSplits_Validate(t *testing.T) {
+	alice, bob := uuid.New(), uuid.New()
+
+	tests := []struct {
+		name    string
+		splits  RevenueSplits
+		wantErr error
+	}{
+		{"even split", RevenueSplits{{UserID: alice, Percent: 50}, {UserID: bob, Percent: 50}}, nil},
+		{"single user", RevenueSplits{{UserID: alice, Percent: 100}}, nil},
+		{"empty", RevenueSplits{}, ErrRevenueSplitsRequired},
+		{"missing user", RevenueSplits{{Percent: 100}}, ErrRevenueSplitUserRequired},
+		{"duplicate user", RevenueSplits{{UserID: alice, Percent: 50}, {UserID: alice, Percent: 50}}, ErrDuplicateRevenueSplitUser},
+		{"zero percent", RevenueSplits{{UserID: alice, Percent: 100}, {UserID: bob, Percent: 0}}, ErrInvalidRevenueSplitPercent},
+		{"under 100", RevenueSplits{{UserID: alice, Percent: 60}, {UserID: bob, Percent: 30}}, ErrRevenueSplitTotalNot100},
+		{"over 100", RevenueSplits{{UserID: alice, Percent: 70}, {UserID: bob, Percent: 40}}, ErrRevenueSplitTotalNot100},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.splits.Validate()
+			if tt.wantErr == nil && err != nil {
+				t.Errorf("Validate() error = %v", err)
+			}
+			if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Errorf("Validate() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+
+	tooMany := make(RevenueSplits, MaxRevenueSplits+1)
+	for i := range tooMany {
+		tooMany[i] = RevenueSplit{UserID: uuid.New(), Percent: 1}
+	}
+	if err := tooMany.Validate(); !errors.Is(err, ErrTooManyRevenueSplits) {
+		t.Errorf("Validate() error = %v, want %v", err, ErrTooManyRevenueSplits)
+	}
+}
+
+func TestRevenueSplits_Credits(t *testing.T) {
+	alice, bob, carol := uuid.New(), uuid.New(), uuid.New()
+	splits := RevenueSplits{
+		{UserID: alice, Percent: 33},
+		{UserID: bob, Percent: 33},
+		{UserID: carol, Percent: 34},
+	}
+
+	credits := splits.Credits(1001)
+	want := []int64{330, 330, 341}
+	var total int64
+	for i, credit := range credits {
+		if credit != want[i] {
+			t.Errorf("Credits(1001)[%d] = %d, want %d", i, credit, want[i])
+		}
+		total += credit
+	}
+	if total != 1001 {
+		t.Errorf("Credits(1001) total = %d, want 1001", total)
+	}
+
+	if got := splits.CreditFor(carol, 1001); got != 341 {
+		t.Errorf("CreditFor(carol) = %d, want 341", got)
+	}
+	if got := splits.CreditFor(uuid.New(), 1001); got != 0 {
+		t.Errorf("CreditFor(unsplit user) = %d, want 0", got)
+	}
+}
+
+func TestNewRevenueSplitChange(t *testing.T) {
+	opp, _ := createTestOpportunity(t)
+	changedBy := uuid.New()
+	previous := OwnerRevenueSplits(opp)
+	splits := RevenueSplits{{UserID: opp.OwnerID, Percent: 60}, {UserID: uuid.New(), Percent: 40}}
+
+	change, err := NewRevenueSplitChange(opp, previous, splits, changedBy, "  co-sold with the partner team ")
+	if err != nil {
+		t.Fatalf("NewRevenueSplitChange() error = %v", err)
+	}
+	if change.OpportunityID != opp.ID || change.TenantID != opp.TenantID || change.ChangedBy != changedBy {
+		t.Errorf("NewRevenueSplitChange() = %+v", change)
+	}
+	if change.Reason != "co-sold with the partner team" {
+		t.Errorf("Reason = %q", change.Reason)
+	}
+	if !change.PreviousSplits.Equal(previous) || !change.Splits.Equal(splits) {
+		t.Errorf("NewRevenueSplitChange() splits = %+v, previous %+v", change.Splits, change.PreviousSplits)
+	}
+
+	cleared, err := NewRevenueSplitChange(opp, splits, nil, changedBy, "")
+	if err != nil {
+		t.Fatalf("NewRevenueSplitChange() clearing error = %v", err)
+	}
+	if cleared.Splits == nil || len(cleared.Splits) != 0 {
+		t.Errorf("cleared Splits = %+v, want empty", cleared.Splits)
+	}
+
+	if _, err := NewRevenueSplitChange(opp, previous, RevenueSplits{{UserID: opp.OwnerID, Percent: 90}}, changedBy, ""); !errors.Is(err, ErrRevenueSplitTotalNot100) {
+		t.Errorf("NewRevenueSplitChange() error = %v, want %v", err, ErrRevenueSplitTotalNot100)
+	}
+
+	opp.Status = OpportunityStatusLost
+	if _, err := NewRevenueSplitChange(opp, previous, splits, changedBy, ""); !errors.Is(err, ErrRevenueSplitOpportunityLost) {
+		t.Errorf("NewRevenueSplitChange() error = %v, want %v", err, ErrRevenueSplitOpportunityLost)
+	}
+}
@@ -95,6 +95,8 @@ const (
 	StepTypeValidateLead      SagaStepType = "validate_lead"
 	StepTypeCreateCustomer    SagaStepType = "create_customer"
 	StepTypeLookupCustomer    SagaStepType = "lookup_customer"
+	StepTypeCreateContact     SagaStepType = "create_contact"
+	StepTypeLookupContact     SagaStepType = "lookup_contact"
 	StepTypeCreateOpportunity SagaStepType = "create_opportunity"
 	StepTypeMarkLeadConverted SagaStepType = "mark_lead_converted"
 	StepTypePublishEvents     SagaStepType = "publish_events"
@@ -307,6 +309,9 @@ type LeadConversionRequest struct {
 	CustomerID        *uuid.UUID `json:"customer_id,omitempty"`
 	CustomerName      *string    `json:"customer_name,omitempty"`
 	CreateNewCustomer bool       `json:"create_new_customer"`
+	ContactID         *uuid.UUID `json:"contact_id,omitempty"`
+	CreateNewContact  bool       `json:"create_new_contact"`
+	OpportunityName   *string    `json:"opportunity_name,omitempty"`
 	OwnerID           *uuid.UUID `json:"owner_id,omitempty"`
 	OwnerName         *string    `json:"owner_name,omitempty"`
 	Description       *string    `json:"description,omitempty"`
@@ -383,15 +388,25 @@ func (s *LeadConversionSaga) buildSteps(request *LeadConversionRequest) {
 	}
 	order++
 
-	// Step 3: Create opportunity (compensatable - can delete)
+	// Step 3: Contact handling (optional, under the customer)
+	if request.CreateNewContact {
+		// Create new contact (compensatable - can delete)
+		s.Steps = append(s.Steps, NewSagaStep(StepTypeCreateContact, order, true))
+	} else if request.ContactID != nil {
+		// Lookup existing contact (not compensatable)
+		s.Steps = append(s.Steps, NewSagaStep(StepTypeLookupContact, order, false))
+	}
+	order++
+
+	// Step 4: Create opportunity (compensatable - can delete)
 	s.Steps = append(s.Steps, NewSagaStep(StepTypeCreateOpportunity, order, true))
 	order++
 
-	// Step 4: Mark lead as converted (compensatable - can revert status)
+	// Step 5: Mark lead as converted (compensatable - can revert status)
 	s.Steps = append(s.Steps, NewSagaStep(StepTypeMarkLeadConverted, order, true))
 	order++
 
-	// Step 5: Publish events via outbox (compensatable - can remove from outbox)
+	// Step 6: Publish events via outbox (compensatable - can remove from outbox)
 	s.Steps = append(s.Steps, NewSagaStep(StepTypePublishEvents, order, true))
 }
 
@@ -554,6 +569,12 @@ func (s *LeadConversionSaga) addEvent(event DomainEvent) {
 	s.events = append(s.events, event)
 }
 
+// RecordEvents collects the events raised by the aggregates changed in a
+// step, to be published with the saga's own events.
+func (s *LeadConversionSaga) RecordEvents(events []DomainEvent) {
+	s.events = append(s.events, events...)
+}
+
 // GetEvents returns all accumulated domain events.
 func (s *LeadConversionSaga) GetEvents() []DomainEvent {
 	return s.events
@@ -100,6 +100,7 @@ type LeadConversionSagaCompletedEvent struct {
 	LeadID          uuid.UUID  `json:"lead_id"`
 	OpportunityID   uuid.UUID  `json:"opportunity_id"`
 	CustomerID      *uuid.UUID `json:"customer_id,omitempty"`
+	ContactID       *uuid.UUID `json:"contact_id,omitempty"`
 	CustomerCreated bool       `json:"customer_created"`
 	TotalDurationMs int64      `json:"total_duration_ms"`
 	StepsCompleted  int        `json:"steps_completed"`
@@ -119,12 +120,18 @@ func NewLeadConversionSagaCompletedEvent(saga *LeadConversionSaga) *LeadConversi
 		}
 	}
 
+	var opportunityID uuid.UUID
+	if saga.OpportunityID != nil {
+		opportunityID = *saga.OpportunityID
+	}
+
 	return &LeadConversionSagaCompletedEvent{
 		BaseEvent:       newBaseEvent("saga.lead_conversion.completed", "saga", saga.ID, saga.TenantID, saga.Version),
 		SagaID:          saga.ID,
 		LeadID:          saga.LeadID,
-		OpportunityID:   *saga.OpportunityID,
+		OpportunityID:   opportunityID,
 		CustomerID:      saga.CustomerID,
+		ContactID:       saga.ContactID,
 		CustomerCreated: saga.CustomerCreated,
 		TotalDurationMs: durationMs,
 		StepsCompleted:  stepsCompleted,
@@ -0,0 +1,254 @@
+package domain
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Shipment errors
+var (
+	ErrShipmentNotFound                = errors.New("shipment not found")
+	ErrShipmentDealCancelled           = errors.New("cannot ship a cancelled deal")
+	ErrShipmentCarrierRequired         = errors.New("shipment carrier is required")
+	ErrShipmentTrackingNumberRequired  = errors.New("shipment tracking number is required")
+	ErrInvalidShipmentStatus           = errors.New("invalid shipment status")
+	ErrInvalidShipmentStatusTransition = errors.New("invalid shipment status transition")
+)
+
+// ShipmentStatus represents where a shipment is on its way to the customer.
+type ShipmentStatus string
+
+const (
+	// ShipmentStatusPending is a shipment booked with the carrier but not yet collected.
+	ShipmentStatusPending        ShipmentStatus = "pending"
+	ShipmentStatusInTransit      ShipmentStatus = "in_transit"
+	ShipmentStatusOutForDelivery ShipmentStatus = "out_for_delivery"
+	ShipmentStatusDelivered      ShipmentStatus = "delivered"
+	// ShipmentStatusException is a failed delivery attempt, a delay or damage
+	// the carrier reported. The shipment may still be delivered.
+	ShipmentStatusException ShipmentStatus = "exception"
+	ShipmentStatusReturned  ShipmentStatus = "returned"
+)
+
+// Shipment update sources
+const (
+	ShipmentSourceManual  = "manual"
+	ShipmentSourcePolling = "polling"
+	ShipmentSourceWebhook = "webhook"
+)
+
+// IsValid checks if the shipment status is valid.
+func (s ShipmentStatus) IsValid() bool {
+	switch s {
+	case ShipmentStatusPending, ShipmentStatusInTransit, ShipmentStatusOutForDelivery,
+		ShipmentStatusDelivered, ShipmentStatusException, ShipmentStatusReturned:
+		return true
+	}
+	return false
+}
+
+// IsFinal returns true if the carrier will report no further progress.
+func (s ShipmentStatus) IsFinal() bool {
+	return s == ShipmentStatusDelivered || s == ShipmentStatusReturned
+}
+
+// IsShipped returns true once the carrier has the shipment.
+func (s ShipmentStatus) IsShipped() bool {
+	return s != ShipmentStatusPending
+}
+
+// ShipmentTrackingEvent is a checkpoint in a shipment's progress.
+type ShipmentTrackingEvent struct {
+	Status      ShipmentStatus `json:"status"`
+	Description string         `json:"description,omitempty"`
+	Location    string         `json:"location,omitempty"`
+	OccurredAt  time.Time      `json:"occurred_at"`
+	// Source is how the update arrived: manual, polling or webhook.
+	Source     string    `json:"source"`
+	RecordedAt time.Time `json:"recorded_at"`
+}
+
+// Shipment is a consignment of a deal's products handed to a carrier.
+type Shipment struct {
+	ID             uuid.UUID      `json:"id"`
+	TenantID       uuid.UUID      `json:"tenant_id"`
+	DealID         uuid.UUID      `json:"deal_id"`
+	DealCode       string         `json:"deal_code"`
+	CustomerID     uuid.UUID      `json:"customer_id"`
+	CustomerName   string         `json:"customer_name"`
+	Carrier        string         `json:"carrier"`
+	TrackingNumber string         `json:"tracking_number"`
+	TrackingURL    string         `json:"tracking_url,omitempty"`
+	Status         ShipmentStatus `json:"status"`
+
+	// RecipientEmail is notified when the shipment is shipped and delivered.
+	RecipientName     string                  `json:"recipient_name,omitempty"`
+	RecipientEmail    string                  `json:"recipient_email,omitempty"`
+	EstimatedDelivery *time.Time              `json:"estimated_delivery,omitempty"`
+	TrackingEvents    []ShipmentTrackingEvent `json:"tracking_events"`
+
+	ShippedAt     *time.Time `json:"shipped_at,omitempty"`
+	DeliveredAt   *time.Time `json:"delivered_at,omitempty"`
+	LastCheckedAt *time.Time `json:"last_checked_at,omitempty"`
+	CreatedBy     uuid.UUID  `json:"created_by"`
+	CreatedAt     time.Time  `json:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at"`
+	Version       int        `json:"version"`
+
+	// Domain events
+	events []DomainEvent `json:"-"`
+}
+
+// NewShipment creates a pending shipment of a deal with a carrier.
+func NewShipment(deal *Deal, carrier, trackingNumber string, createdBy uuid.UUID) (*Shipment, error) {
+	if deal.Status == DealStatusCancelled || deal.IsDeleted() {
+		return nil, ErrShipmentDealCancelled
+	}
+
+	now := time.Now().UTC()
+	shipment := &Shipment{
+		ID:             uuid.New(),
+		TenantID:       deal.TenantID,
+		DealID:         deal.ID,
+		DealCode:       deal.Code,
+		CustomerID:     deal.CustomerID,
+		CustomerName:   deal.CustomerName,
+		Status:         ShipmentStatusPending,
+		TrackingEvents: []ShipmentTrackingEvent{},
+		CreatedBy:      createdBy,
+		CreatedAt:      now,
+		UpdatedAt:      now,
+		Version:        1,
+		events:         make([]DomainEvent, 0),
+	}
+	if err := shipment.setCarrier(carrier, trackingNumber); err != nil {
+		return nil, err
+	}
+
+	shipment.AddEvent(NewShipmentCreatedEvent(shipment))
+	return shipment, nil
+}
+
+// NormalizeCarrier returns the carrier code shipments are stored and matched by.
+func NormalizeCarrier(carrier string) string {
+	return strings.ToLower(strings.TrimSpace(carrier))
+}
+
+// setCarrier sets the carrier and tracking number.
+func (s *Shipment) setCarrier(carrier, trackingNumber string) error {
+	carrier = NormalizeCarrier(carrier)
+	if carrier == "" {
+		return ErrShipmentCarrierRequired
+	}
+	trackingNumber = strings.TrimSpace(trackingNumber)
+	if trackingNumber == "" {
+		return ErrShipmentTrackingNumberRequired
+	}
+	s.Carrier = carrier
+	s.TrackingNumber = trackingNumber
+	return nil
+}
+
+// SetRecipient sets who is notified of the shipment's progress.
+func (s *Shipment) SetRecipient(name, email string) {
+	s.RecipientName = strings.TrimSpace(name)
+	s.RecipientEmail = strings.ToLower(strings.TrimSpace(email))
+	s.UpdatedAt = time.Now().UTC()
+}
+
+// Update changes the shipment's carrier details. A shipment the carrier has
+// finished with cannot be changed.
+func (s *Shipment) Update(carrier, trackingNumber, trackingURL string, estimatedDelivery *time.Time) error {
+	if s.Status.IsFinal() {
+		return ErrInvalidShipmentStatusTransition
+	}
+	if err := s.setCarrier(carrier, trackingNumber); err != nil {
+		return err
+	}
+	s.TrackingURL = strings.TrimSpace(trackingURL)
+	s.EstimatedDelivery = estimatedDelivery
+	s.UpdatedAt = time.Now().UTC()
+	return nil
+}
+
+// RecordTracking records a tracking event and moves the shipment to its
+// status. Events already recorded are ignored, so carriers may resend them,
+// and an event older than the latest one is kept in the history without
+// changing the status. It returns whether the shipment changed.
+func (s *Shipment) RecordTracking(event ShipmentTrackingEvent) (bool, error) {
+	if !event.Status.IsValid() {
+		return false, ErrInvalidShipmentStatus
+	}
+	if event.OccurredAt.IsZero() {
+		event.OccurredAt = time.Now().UTC()
+	}
+	event.OccurredAt = event.OccurredAt.UTC()
+	event.Description = strings.TrimSpace(event.Description)
+	event.Location = strings.TrimSpace(event.Location)
+
+	var latest *ShipmentTrackingEvent
+	for i := range s.TrackingEvents {
+		recorded := &s.TrackingEvents[i]
+		if recorded.Status == event.Status && recorded.OccurredAt.Equal(event.OccurredAt) && recorded.Description == event.Description {
+			return false, nil
+		}
+		if latest == nil || recorded.OccurredAt.After(latest.OccurredAt) {
+			latest = recorded
+		}
+	}
+
+	current := latest == nil || !event.OccurredAt.Before(latest.OccurredAt)
+	if current && event.Status != s.Status {
+		if s.Status.IsFinal() || event.Status == ShipmentStatusPending {
+			return false, ErrInvalidShipmentStatusTransition
+		}
+	}
+
+	now := time.Now().UTC()
+	event.RecordedAt = now
+	s.TrackingEvents = append(s.TrackingEvents, event)
+	s.UpdatedAt = now
+
+	if !current || event.Status == s.Status {
+		return true, nil
+	}
+
+	from := s.Status
+	s.Status = event.Status
+	if s.ShippedAt == nil && s.Status.IsShipped() {
+		shippedAt := event.OccurredAt
+		s.ShippedAt = &shippedAt
+		s.AddEvent(NewShipmentShippedEvent(s))
+	}
+	if s.Status == ShipmentStatusDelivered {
+		deliveredAt := event.OccurredAt
+		s.DeliveredAt = &deliveredAt
+		s.AddEvent(NewShipmentDeliveredEvent(s))
+	}
+	s.AddEvent(NewShipmentStatusChangedEvent(s, from, event))
+	return true, nil
+}
+
+// MarkChecked records that the carrier was asked for the shipment's progress.
+func (s *Shipment) MarkChecked(at time.Time) {
+	at = at.UTC()
+	s.LastCheckedAt = &at
+}
+
+// AddEvent adds a domain event.
+func (s *Shipment) AddEvent(event DomainEvent) {
+	s.events = append(s.events, event)
+}
+
+// GetEvents returns all domain events.
+func (s *Shipment) GetEvents() []DomainEvent {
+	return s.events
+}
+
+// ClearEvents clears all domain events.
+func (s *Shipment) ClearEvents() {
+	s.events = make([]DomainEvent, 0)
+}
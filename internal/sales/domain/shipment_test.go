@@ -0,0 +1,171 @@
+package domain
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func createTestShipment(t *testing.T) *Shipment {
+	t.Helper()
+
+	shipment, err := NewShipment(createTestDeal(t), "PosLaju", "EP123456789MY", uuid.New())
+	if err != nil {
+		t.Fatalf("NewShipment() error = %v", err)
+	}
+	shipment.ClearEvents()
+	return shipment
+}
+
+func shipmentEventTypes(shipment *Shipment) []string {
+	types := make([]string, 0, len(shipment.GetEvents()))
+	for _, event := range shipment.GetEvents() {
+		types = append(types, event.EventType())
+	}
+	return types
+}
+
+func TestNewShipment(t *testing.T) {
+	deal := createTestDeal(t)
+	createdBy := uuid.New()
+
+	shipment, err := NewShipment(deal, " PosLaju ", " EP123456789MY ", createdBy)
+	if err != nil {
+		t.Fatalf("NewShipment() error = %v", err)
+	}
+
+	if shipment.Status != ShipmentStatusPending {
+		t.Errorf("Status = %s, want %s", shipment.Status, ShipmentStatusPending)
+	}
+	if shipment.Carrier != "poslaju" || shipment.TrackingNumber != "EP123456789MY" {
+		t.Errorf("Carrier, TrackingNumber = %q, %q, want the normalized carrier and trimmed number", shipment.Carrier, shipment.TrackingNumber)
+	}
+	if shipment.DealID != deal.ID || shipment.CustomerID != deal.CustomerID || shipment.TenantID != deal.TenantID {
+		t.Error("Shipment should reference the deal, its customer and tenant")
+	}
+	if shipment.CreatedBy != createdBy {
+		t.Errorf("CreatedBy = %v, want %v", shipment.CreatedBy, createdBy)
+	}
+	if types := shipmentEventTypes(shipment); len(types) != 1 || types[0] != "shipment.created" {
+		t.Errorf("events = %v, want shipment.created", types)
+	}
+}
+
+func TestNewShipment_Invalid(t *testing.T) {
+	if _, err := NewShipment(createTestDeal(t), " ", "EP123", uuid.New()); !errors.Is(err, ErrShipmentCarrierRequired) {
+		t.Errorf("NewShipment() without carrier error = %v, want %v", err, ErrShipmentCarrierRequired)
+	}
+	if _, err := NewShipment(createTestDeal(t), "poslaju", "", uuid.New()); !errors.Is(err, ErrShipmentTrackingNumberRequired) {
+		t.Errorf("NewShipment() without tracking number error = %v, want %v", err, ErrShipmentTrackingNumberRequired)
+	}
+
+	cancelled := createTestDeal(t)
+	cancelled.Status = DealStatusCancelled
+	if _, err := NewShipment(cancelled, "poslaju", "EP123", uuid.New()); !errors.Is(err, ErrShipmentDealCancelled) {
+		t.Errorf("NewShipment() of cancelled deal error = %v, want %v", err, ErrShipmentDealCancelled)
+	}
+}
+
+func TestShipment_RecordTracking(t *testing.T) {
+	shipment := createTestShipment(t)
+	collected := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+
+	changed, err := shipment.RecordTracking(ShipmentTrackingEvent{
+		Status:      ShipmentStatusInTransit,
+		Description: "Item collected",
+		Location:    "Kota Bharu",
+		OccurredAt:  collected,
+		Source:      ShipmentSourcePolling,
+	})
+	if err != nil || !changed {
+		t.Fatalf("RecordTracking() = %v, %v, want true, nil", changed, err)
+	}
+	if shipment.Status != ShipmentStatusInTransit {
+		t.Errorf("Status = %s, want %s", shipment.Status, ShipmentStatusInTransit)
+	}
+	if shipment.ShippedAt == nil || !shipment.ShippedAt.Equal(collected) {
+		t.Errorf("ShippedAt = %v, want %v", shipment.ShippedAt, collected)
+	}
+	types := shipmentEventTypes(shipment)
+	if len(types) != 2 || types[0] != "shipment.shipped" || types[1] != "shipment.status_changed" {
+		t.Errorf("events = %v, want shipment.shipped and shipment.status_changed", types)
+	}
+	shipment.ClearEvents()
+
+	// A resent event is ignored
+	changed, err = shipment.RecordTracking(ShipmentTrackingEvent{
+		Status:      ShipmentStatusInTransit,
+		Description: "Item collected",
+		OccurredAt:  collected,
+		Source:      ShipmentSourceWebhook,
+	})
+	if err != nil || changed {
+		t.Errorf("RecordTracking() of duplicate = %v, %v, want false, nil", changed, err)
+	}
+	if len(shipment.TrackingEvents) != 1 {
+		t.Errorf("len(TrackingEvents) = %d, want 1", len(shipment.TrackingEvents))
+	}
+
+	delivered := collected.Add(26 * time.Hour)
+	if _, err := shipment.RecordTracking(ShipmentTrackingEvent{Status: ShipmentStatusDelivered, OccurredAt: delivered}); err != nil {
+		t.Fatalf("RecordTracking() delivered error = %v", err)
+	}
+	if shipment.Status != ShipmentStatusDelivered || shipment.DeliveredAt == nil || !shipment.DeliveredAt.Equal(delivered) {
+		t.Errorf("Status, DeliveredAt = %s, %v, want delivered at %v", shipment.Status, shipment.DeliveredAt, delivered)
+	}
+	types = shipmentEventTypes(shipment)
+	if len(types) != 2 || types[0] != "shipment.delivered" {
+		t.Errorf("events = %v, want shipment.delivered then shipment.status_changed", types)
+	}
+	shipment.ClearEvents()
+
+	// An older checkpoint arriving late is kept without changing the status
+	changed, err = shipment.RecordTracking(ShipmentTrackingEvent{
+		Status:     ShipmentStatusOutForDelivery,
+		OccurredAt: delivered.Add(-3 * time.Hour),
+	})
+	if err != nil || !changed {
+		t.Fatalf("RecordTracking() of older event = %v, %v, want true, nil", changed, err)
+	}
+	if shipment.Status != ShipmentStatusDelivered {
+		t.Errorf("Status = %s, want %s", shipment.Status, ShipmentStatusDelivered)
+	}
+	if len(shipment.TrackingEvents) != 3 || len(shipment.GetEvents()) != 0 {
+		t.Errorf("TrackingEvents = %d, events = %d, want 3 and 0", len(shipment.TrackingEvents), len(shipment.GetEvents()))
+	}
+}
+
+func TestShipment_RecordTracking_Invalid(t *testing.T) {
+	shipment := createTestShipment(t)
+
+	if _, err := shipment.RecordTracking(ShipmentTrackingEvent{Status: "lost"}); !errors.Is(err, ErrInvalidShipmentStatus) {
+		t.Errorf("RecordTracking() with unknown status error = %v, want %v", err, ErrInvalidShipmentStatus)
+	}
+
+	if _, err := shipment.RecordTracking(ShipmentTrackingEvent{Status: ShipmentStatusDelivered}); err != nil {
+		t.Fatalf("RecordTracking() error = %v", err)
+	}
+	if _, err := shipment.RecordTracking(ShipmentTrackingEvent{Status: ShipmentStatusInTransit}); !errors.Is(err, ErrInvalidShipmentStatusTransition) {
+		t.Errorf("RecordTracking() after delivery error = %v, want %v", err, ErrInvalidShipmentStatusTransition)
+	}
+	if err := shipment.Update("dhl", "123", "", nil); !errors.Is(err, ErrInvalidShipmentStatusTransition) {
+		t.Errorf("Update() after delivery error = %v, want %v", err, ErrInvalidShipmentStatusTransition)
+	}
+}
+
+func TestShipment_Update(t *testing.T) {
+	shipment := createTestShipment(t)
+	eta := time.Date(2026, 3, 5, 0, 0, 0, 0, time.UTC)
+
+	if err := shipment.Update("J&T", "JT0001", " https://track.example.com/JT0001 ", &eta); err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+	if shipment.Carrier != "j&t" || shipment.TrackingNumber != "JT0001" {
+		t.Errorf("Carrier, TrackingNumber = %q, %q", shipment.Carrier, shipment.TrackingNumber)
+	}
+	if shipment.TrackingURL != "https://track.example.com/JT0001" || shipment.EstimatedDelivery == nil {
+		t.Errorf("TrackingURL, EstimatedDelivery = %q, %v", shipment.TrackingURL, shipment.EstimatedDelivery)
+	}
+}
@@ -0,0 +1,218 @@
+// Package domain contains the domain layer for the Sales Pipeline service.
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ============================================================================
+// Stage Funnel Read Model
+// ============================================================================
+
+// StageFunnelEntry is one visit of an opportunity to a pipeline stage, as
+// held by the stage funnel read model. Entries are projected from the
+// opportunity's stage history and carry the cohort attributes the funnel is
+// filtered by.
+type StageFunnelEntry struct {
+	TenantID             uuid.UUID   `json:"tenant_id"`
+	OpportunityID        uuid.UUID   `json:"opportunity_id"`
+	Visit                int         `json:"visit"`
+	PipelineID           uuid.UUID   `json:"pipeline_id"`
+	StageID              uuid.UUID   `json:"stage_id"`
+	StageType            StageType   `json:"stage_type"`
+	StageOrder           int         `json:"stage_order"`
+	EnteredAt            time.Time   `json:"entered_at"`
+	ExitedAt             *time.Time  `json:"exited_at,omitempty"`
+	DurationSeconds      *int64      `json:"duration_seconds,omitempty"`
+	OwnerID              uuid.UUID   `json:"owner_id"`
+	Source               string      `json:"source,omitempty"`
+	ProductIDs           []uuid.UUID `json:"product_ids"`
+	OpportunityCreatedAt time.Time   `json:"opportunity_created_at"`
+}
+
+// NewStageFunnelEntries projects an opportunity's stage history onto funnel
+// entries. Visits to stages no longer in the pipeline are left out, since
+// the funnel has no step to put them in.
+func NewStageFunnelEntries(opp *Opportunity, pipeline *Pipeline) []*StageFunnelEntry {
+	productIDs := make([]uuid.UUID, 0, len(opp.Products))
+	seen := make(map[uuid.UUID]bool, len(opp.Products))
+	for _, product := range opp.Products {
+		if product.ProductID == uuid.Nil || seen[product.ProductID] {
+			continue
+		}
+		seen[product.ProductID] = true
+		productIDs = append(productIDs, product.ProductID)
+	}
+
+	entries := make([]*StageFunnelEntry, 0, len(opp.StageHistory))
+	for i, history := range opp.StageHistory {
+		stage := pipeline.GetStage(history.StageID)
+		if stage == nil {
+			continue
+		}
+
+		entry := &StageFunnelEntry{
+			TenantID:             opp.TenantID,
+			OpportunityID:        opp.ID,
+			Visit:                i + 1,
+			PipelineID:           opp.PipelineID,
+			StageID:              stage.ID,
+			StageType:            stage.Type,
+			StageOrder:           stage.Order,
+			EnteredAt:            history.EnteredAt,
+			ExitedAt:             history.ExitedAt,
+			OwnerID:              opp.OwnerID,
+			Source:               opp.Source,
+			ProductIDs:           productIDs,
+			OpportunityCreatedAt: opp.CreatedAt,
+		}
+		if history.ExitedAt != nil {
+			duration := int64(history.ExitedAt.Sub(history.EnteredAt).Seconds())
+			if duration < 0 {
+				duration = 0
+			}
+			entry.DurationSeconds = &duration
+		}
+		entries = append(entries, entry)
+	}
+
+	return entries
+}
+
+// StageFunnelFilter selects the opportunity cohort a funnel is computed for.
+// From and To bound the opportunities' creation time.
+type StageFunnelFilter struct {
+	From      *time.Time
+	To        *time.Time
+	OwnerID   *uuid.UUID
+	Source    string
+	ProductID *uuid.UUID
+}
+
+// StageDurationStatistics holds how long a cohort's opportunities stayed in
+// one stage. Only completed visits count towards the durations.
+type StageDurationStatistics struct {
+	StageID         uuid.UUID `json:"stage_id" db:"stage_id"`
+	Opportunities   int64     `json:"opportunities" db:"opportunities"`
+	CompletedVisits int64     `json:"completed_visits" db:"completed_visits"`
+	AverageHours    float64   `json:"average_hours" db:"average_hours"`
+	MedianHours     float64   `json:"median_hours" db:"median_hours"`
+}
+
+// StageReachCount counts the opportunities whose furthest stage, ignoring
+// lost stages, is the given stage.
+type StageReachCount struct {
+	StageID       uuid.UUID `json:"stage_id" db:"stage_id"`
+	Opportunities int64     `json:"opportunities" db:"opportunities"`
+}
+
+// ============================================================================
+// Stage Funnel
+// ============================================================================
+
+// StageFunnelStep is one stage of a pipeline funnel.
+type StageFunnelStep struct {
+	Stage *Stage
+
+	// Reached counts the opportunities that got at least as far as the
+	// stage, whether or not they were recorded in it.
+	Reached int64
+
+	// Entered counts the opportunities recorded in the stage.
+	Entered int64
+
+	// ConversionRate is the percentage of Reached that went on to the next
+	// step. The last step has none.
+	ConversionRate float64
+	DropOff        int64
+
+	CompletedVisits int64
+	AverageHours    float64
+	MedianHours     float64
+}
+
+// StageFunnel is the funnel of a pipeline for an opportunity cohort.
+type StageFunnel struct {
+	Steps []*StageFunnelStep
+
+	// Opportunities counts the cohort's opportunities that entered any step.
+	Opportunities int64
+
+	// Lost counts the cohort's opportunities recorded in a lost stage.
+	Lost int64
+
+	// OverallConversionRate is the percentage of the first step's
+	// opportunities that reached the won stage.
+	OverallConversionRate float64
+}
+
+// BuildStageFunnel lays a cohort's stage statistics out as the pipeline's
+// funnel. Steps are the pipeline's active stages in order, lost stages
+// excepted; an opportunity counts towards every step up to the furthest one
+// it entered, so skipped stages still count as passed.
+func BuildStageFunnel(pipeline *Pipeline, durations []*StageDurationStatistics, reached []*StageReachCount) *StageFunnel {
+	durationByStage := make(map[uuid.UUID]*StageDurationStatistics, len(durations))
+	for _, d := range durations {
+		durationByStage[d.StageID] = d
+	}
+
+	funnel := &StageFunnel{}
+	for _, stage := range pipeline.GetActiveStages() {
+		if stage.Type == StageTypeLost {
+			if d := durationByStage[stage.ID]; d != nil {
+				funnel.Lost += d.Opportunities
+			}
+			continue
+		}
+
+		step := &StageFunnelStep{Stage: stage}
+		if d := durationByStage[stage.ID]; d != nil {
+			step.Entered = d.Opportunities
+			step.CompletedVisits = d.CompletedVisits
+			step.AverageHours = d.AverageHours
+			step.MedianHours = d.MedianHours
+		}
+		funnel.Steps = append(funnel.Steps, step)
+	}
+
+	// An opportunity furthest in a stage that is no longer a step counts
+	// towards the steps ordered up to that stage
+	for _, r := range reached {
+		stage := pipeline.GetStage(r.StageID)
+		if stage == nil || stage.Type == StageTypeLost {
+			continue
+		}
+		for _, step := range funnel.Steps {
+			if step.Stage.Order <= stage.Order {
+				step.Reached += r.Opportunities
+			}
+		}
+		funnel.Opportunities += r.Opportunities
+	}
+
+	for i, step := range funnel.Steps {
+		if i == len(funnel.Steps)-1 {
+			break
+		}
+		next := funnel.Steps[i+1]
+		step.DropOff = step.Reached - next.Reached
+		if step.Reached > 0 {
+			step.ConversionRate = float64(next.Reached) / float64(step.Reached) * 100
+		}
+	}
+
+	if len(funnel.Steps) > 0 {
+		first := funnel.Steps[0]
+		if won := pipeline.GetWonStage(); won != nil && first.Reached > 0 {
+			for _, step := range funnel.Steps {
+				if step.Stage.ID == won.ID {
+					funnel.OverallConversionRate = float64(step.Reached) / float64(first.Reached) * 100
+				}
+			}
+		}
+	}
+
+	return funnel
+}
@@ -0,0 +1,124 @@
+package domain
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func newFunnelTestPipeline() *Pipeline {
+	stage := func(name string, stageType StageType, order int) *Stage {
+		return &Stage{ID: uuid.New(), Name: name, Type: stageType, Order: order, IsActive: true}
+	}
+	return &Pipeline{
+		ID:       uuid.New(),
+		TenantID: uuid.New(),
+		Name:     "Sales",
+		Stages: []*Stage{
+			stage("Qualified", StageTypeQualifying, 1),
+			stage("Proposal", StageTypeNegotiating, 2),
+			stage("Won", StageTypeWon, 3),
+			stage("Lost", StageTypeLost, 4),
+		},
+	}
+}
+
+func TestNewStageFunnelEntries(t *testing.T) {
+	pipeline := newFunnelTestPipeline()
+	qualified, proposal := pipeline.Stages[0], pipeline.Stages[1]
+
+	entered := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+	exited := entered.Add(36 * time.Hour)
+	productID := uuid.New()
+	opp := &Opportunity{
+		ID:         uuid.New(),
+		TenantID:   pipeline.TenantID,
+		PipelineID: pipeline.ID,
+		OwnerID:    uuid.New(),
+		Source:     "referral",
+		CreatedAt:  entered,
+		Products: []OpportunityProduct{
+			{ProductID: productID},
+			{ProductID: productID},
+		},
+		StageHistory: []StageHistory{
+			{StageID: qualified.ID, EnteredAt: entered, ExitedAt: &exited},
+			{StageID: uuid.New(), EnteredAt: exited},
+			{StageID: proposal.ID, EnteredAt: exited},
+		},
+	}
+
+	entries := NewStageFunnelEntries(opp, pipeline)
+	if len(entries) != 2 {
+		t.Fatalf("NewStageFunnelEntries() = %d entries, want 2 (removed stage left out)", len(entries))
+	}
+
+	first := entries[0]
+	if first.Visit != 1 || first.StageOrder != 1 || first.StageType != StageTypeQualifying {
+		t.Errorf("first entry = visit %d, order %d, type %s; want visit 1, order 1, qualifying", first.Visit, first.StageOrder, first.StageType)
+	}
+	if first.DurationSeconds == nil || *first.DurationSeconds != 36*3600 {
+		t.Errorf("first entry duration = %v, want %d seconds", first.DurationSeconds, 36*3600)
+	}
+	if len(first.ProductIDs) != 1 || first.ProductIDs[0] != productID || first.Source != "referral" {
+		t.Errorf("first entry cohort = products %v, source %q; want one product and referral", first.ProductIDs, first.Source)
+	}
+
+	last := entries[1]
+	if last.Visit != 3 || last.StageID != proposal.ID || last.DurationSeconds != nil {
+		t.Errorf("last entry = visit %d, stage %s, duration %v; want visit 3 in the open proposal stage", last.Visit, last.StageID, last.DurationSeconds)
+	}
+}
+
+func TestBuildStageFunnel(t *testing.T) {
+	pipeline := newFunnelTestPipeline()
+	qualified, proposal, won, lost := pipeline.Stages[0], pipeline.Stages[1], pipeline.Stages[2], pipeline.Stages[3]
+
+	durations := []*StageDurationStatistics{
+		{StageID: qualified.ID, Opportunities: 8, CompletedVisits: 6, AverageHours: 30, MedianHours: 24},
+		{StageID: proposal.ID, Opportunities: 5, CompletedVisits: 4, AverageHours: 72, MedianHours: 48},
+		{StageID: won.ID, Opportunities: 2},
+		{StageID: lost.ID, Opportunities: 3},
+	}
+	// Two opportunities were won straight from qualification, skipping the proposal
+	reached := []*StageReachCount{
+		{StageID: qualified.ID, Opportunities: 4},
+		{StageID: proposal.ID, Opportunities: 4},
+		{StageID: won.ID, Opportunities: 2},
+	}
+
+	funnel := BuildStageFunnel(pipeline, durations, reached)
+
+	if len(funnel.Steps) != 3 {
+		t.Fatalf("BuildStageFunnel() = %d steps, want 3 (lost stage excluded)", len(funnel.Steps))
+	}
+	if funnel.Opportunities != 10 || funnel.Lost != 3 {
+		t.Errorf("BuildStageFunnel() opportunities = %d, lost = %d; want 10 and 3", funnel.Opportunities, funnel.Lost)
+	}
+
+	want := []struct {
+		reached        int64
+		conversionRate float64
+		dropOff        int64
+	}{
+		{10, 60, 4},
+		{6, 100.0 / 3, 4},
+		{2, 0, 0},
+	}
+	for i, w := range want {
+		step := funnel.Steps[i]
+		if step.Reached != w.reached || math.Abs(step.ConversionRate-w.conversionRate) > 0.01 || step.DropOff != w.dropOff {
+			t.Errorf("step %s = reached %d, conversion %.2f, drop-off %d; want %d, %.2f, %d",
+				step.Stage.Name, step.Reached, step.ConversionRate, step.DropOff, w.reached, w.conversionRate, w.dropOff)
+		}
+	}
+
+	if step := funnel.Steps[1]; step.Entered != 5 || step.MedianHours != 48 || step.CompletedVisits != 4 {
+		t.Errorf("proposal step = entered %d, median %.0fh, %d visits; want 5, 48h, 4", step.Entered, step.MedianHours, step.CompletedVisits)
+	}
+	if funnel.OverallConversionRate != 20 {
+		t.Errorf("BuildStageFunnel() overall conversion = %.2f, want 20", funnel.OverallConversionRate)
+	}
+}
@@ -0,0 +1,184 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Stage rule errors
+var (
+	ErrInvalidStageWIPLimit      = errors.New("stage WIP limit must not be negative")
+	ErrInvalidStageRequiredField = errors.New("invalid stage required field")
+	ErrInvalidStageMinActivities = errors.New("stage minimum activities must not be negative")
+)
+
+// Opportunity fields a stage can require before an opportunity enters it.
+// Custom fields are required by their name prefixed with
+// StageCustomFieldPrefix.
+const (
+	StageFieldAmount            = "amount"
+	StageFieldExpectedCloseDate = "expected_close_date"
+	StageFieldDescription       = "description"
+	StageFieldPrimaryContact    = "primary_contact"
+	StageFieldProducts          = "products"
+	StageFieldSource            = "source"
+	StageFieldCampaign          = "campaign"
+
+	StageCustomFieldPrefix = "custom_fields."
+)
+
+// stageFieldLabels names the standard required fields in error messages.
+var stageFieldLabels = map[string]string{
+	StageFieldAmount:            "amount",
+	StageFieldExpectedCloseDate: "expected close date",
+	StageFieldDescription:       "description",
+	StageFieldPrimaryContact:    "primary contact",
+	StageFieldProducts:          "at least one product",
+	StageFieldSource:            "source",
+	StageFieldCampaign:          "campaign",
+}
+
+// IsValidStageField reports whether field can be required by a stage.
+func IsValidStageField(field string) bool {
+	if name, ok := strings.CutPrefix(field, StageCustomFieldPrefix); ok {
+		return name != ""
+	}
+	_, ok := stageFieldLabels[field]
+	return ok
+}
+
+// StageEntryCriteria are the conditions an opportunity must meet to move
+// into a stage.
+type StageEntryCriteria struct {
+	RequiredFields []string `json:"required_fields,omitempty" bson:"required_fields,omitempty"`
+	// MinActivities is the number of activities that must have been logged
+	// on the opportunity
+	MinActivities int `json:"min_activities,omitempty" bson:"min_activities,omitempty"`
+}
+
+// Validate checks that the criteria only require known fields.
+func (c StageEntryCriteria) Validate() error {
+	for _, field := range c.RequiredFields {
+		if !IsValidStageField(field) {
+			return fmt.Errorf("%w: %s", ErrInvalidStageRequiredField, field)
+		}
+	}
+	if c.MinActivities < 0 {
+		return ErrInvalidStageMinActivities
+	}
+	return nil
+}
+
+// IsEmpty reports whether the criteria let any opportunity in.
+func (c StageEntryCriteria) IsEmpty() bool {
+	return len(c.RequiredFields) == 0 && c.MinActivities == 0
+}
+
+// SetRules sets the stage's WIP limit and entry criteria. A WIP limit of 0
+// leaves the stage unlimited.
+func (s *Stage) SetRules(wipLimit int, criteria StageEntryCriteria) error {
+	if wipLimit < 0 {
+		return ErrInvalidStageWIPLimit
+	}
+	if err := criteria.Validate(); err != nil {
+		return err
+	}
+	s.WIPLimit = wipLimit
+	s.EntryCriteria = criteria
+	return nil
+}
+
+// UnmetEntryCriteria describes each entry criterion of the stage that the
+// opportunity does not meet, in the order they are configured. The
+// opportunity may enter the stage when none are returned.
+func (s *Stage) UnmetEntryCriteria(o *Opportunity) []string {
+	var unmet []string
+	for _, field := range s.EntryCriteria.RequiredFields {
+		if !o.hasStageField(field) {
+			unmet = append(unmet, stageFieldLabel(field)+" is required")
+		}
+	}
+	if o.ActivityCount < s.EntryCriteria.MinActivities {
+		unmet = append(unmet, fmt.Sprintf("at least %d activities are required, %d logged",
+			s.EntryCriteria.MinActivities, o.ActivityCount))
+	}
+	return unmet
+}
+
+// StageWIPStatus describes how full a stage is against its WIP limit.
+type StageWIPStatus string
+
+const (
+	StageWIPStatusUnlimited StageWIPStatus = "unlimited"
+	StageWIPStatusUnder     StageWIPStatus = "under"
+	StageWIPStatusAt        StageWIPStatus = "at"
+	StageWIPStatusOver      StageWIPStatus = "over"
+)
+
+// WIPStatus returns the status of the stage when it holds openCount open
+// opportunities. A stage goes over its limit when the limit is lowered or
+// a manager overrides it.
+func (s *Stage) WIPStatus(openCount int64) StageWIPStatus {
+	limit := int64(s.WIPLimit)
+	switch {
+	case limit == 0:
+		return StageWIPStatusUnlimited
+	case openCount > limit:
+		return StageWIPStatusOver
+	case openCount == limit:
+		return StageWIPStatusAt
+	default:
+		return StageWIPStatusUnder
+	}
+}
+
+// HasRoomFor reports whether an opportunity can move into the stage while it
+// holds openCount open opportunities.
+func (s *Stage) HasRoomFor(openCount int64) bool {
+	return s.WIPLimit == 0 || openCount < int64(s.WIPLimit)
+}
+
+// hasStageField reports whether the opportunity has a value for a field a
+// stage requires.
+func (o *Opportunity) hasStageField(field string) bool {
+	if name, ok := strings.CutPrefix(field, StageCustomFieldPrefix); ok {
+		value, exists := o.CustomFields[name]
+		if !exists || value == nil {
+			return false
+		}
+		if str, isString := value.(string); isString {
+			return strings.TrimSpace(str) != ""
+		}
+		return true
+	}
+
+	switch field {
+	case StageFieldAmount:
+		return o.Amount.Amount > 0
+	case StageFieldExpectedCloseDate:
+		return o.ExpectedCloseDate != nil
+	case StageFieldDescription:
+		return strings.TrimSpace(o.Description) != ""
+	case StageFieldPrimaryContact:
+		return o.GetPrimaryContact() != nil
+	case StageFieldProducts:
+		return len(o.Products) > 0
+	case StageFieldSource:
+		return o.Source != ""
+	case StageFieldCampaign:
+		return o.Campaign != "" || o.CampaignID != nil
+	}
+	return false
+}
+
+// stageFieldLabel names a required field in error messages.
+func stageFieldLabel(field string) string {
+	if name, ok := strings.CutPrefix(field, StageCustomFieldPrefix); ok {
+		return "custom field " + name
+	}
+	if label, ok := stageFieldLabels[field]; ok {
+		return label
+	}
+	return field
+}
@@ -0,0 +1,103 @@
+package domain
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestStage_SetRules(t *testing.T) {
+	stage := &Stage{ID: uuid.New(), Name: "Proposal"}
+
+	criteria := StageEntryCriteria{
+		RequiredFields: []string{StageFieldAmount, StageCustomFieldPrefix + "budget"},
+		MinActivities:  2,
+	}
+	if err := stage.SetRules(5, criteria); err != nil {
+		t.Fatalf("SetRules() error = %v", err)
+	}
+	if stage.WIPLimit != 5 || stage.EntryCriteria.MinActivities != 2 {
+		t.Errorf("SetRules() = limit %d, min activities %d", stage.WIPLimit, stage.EntryCriteria.MinActivities)
+	}
+
+	if err := stage.SetRules(-1, StageEntryCriteria{}); !errors.Is(err, ErrInvalidStageWIPLimit) {
+		t.Errorf("SetRules() negative limit error = %v, want %v", err, ErrInvalidStageWIPLimit)
+	}
+	if err := stage.SetRules(0, StageEntryCriteria{RequiredFields: []string{"favourite_colour"}}); !errors.Is(err, ErrInvalidStageRequiredField) {
+		t.Errorf("SetRules() unknown field error = %v, want %v", err, ErrInvalidStageRequiredField)
+	}
+	if err := stage.SetRules(0, StageEntryCriteria{RequiredFields: []string{StageCustomFieldPrefix}}); !errors.Is(err, ErrInvalidStageRequiredField) {
+		t.Errorf("SetRules() unnamed custom field error = %v, want %v", err, ErrInvalidStageRequiredField)
+	}
+	if stage.WIPLimit != 5 {
+		t.Errorf("invalid rules changed the WIP limit to %d", stage.WIPLimit)
+	}
+}
+
+func TestStage_UnmetEntryCriteria(t *testing.T) {
+	stage := &Stage{
+		Name: "Proposal",
+		EntryCriteria: StageEntryCriteria{
+			RequiredFields: []string{
+				StageFieldAmount,
+				StageFieldExpectedCloseDate,
+				StageFieldPrimaryContact,
+				StageCustomFieldPrefix + "budget",
+			},
+			MinActivities: 2,
+		},
+	}
+
+	opp := &Opportunity{CustomFields: map[string]interface{}{"budget": "  "}}
+	unmet := stage.UnmetEntryCriteria(opp)
+	want := []string{
+		"amount is required",
+		"expected close date is required",
+		"primary contact is required",
+		"custom field budget is required",
+		"at least 2 activities are required, 0 logged",
+	}
+	if strings.Join(unmet, "|") != strings.Join(want, "|") {
+		t.Errorf("UnmetEntryCriteria() = %q, want %q", unmet, want)
+	}
+
+	closeDate := time.Now().AddDate(0, 1, 0)
+	opp.Amount = Money{Amount: 1000, Currency: "MYR"}
+	opp.ExpectedCloseDate = &closeDate
+	opp.Contacts = []OpportunityContact{{ContactID: uuid.New(), IsPrimary: true}}
+	opp.CustomFields["budget"] = 50000
+	opp.ActivityCount = 2
+	if unmet := stage.UnmetEntryCriteria(opp); len(unmet) != 0 {
+		t.Errorf("UnmetEntryCriteria() = %q, want none", unmet)
+	}
+}
+
+func TestStage_WIPStatus(t *testing.T) {
+	tests := []struct {
+		name     string
+		limit    int
+		count    int64
+		want     StageWIPStatus
+		wantRoom bool
+	}{
+		{"no limit", 0, 40, StageWIPStatusUnlimited, true},
+		{"under", 3, 2, StageWIPStatusUnder, true},
+		{"at", 3, 3, StageWIPStatusAt, false},
+		{"over", 3, 4, StageWIPStatusOver, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stage := &Stage{WIPLimit: tt.limit}
+			if got := stage.WIPStatus(tt.count); got != tt.want {
+				t.Errorf("WIPStatus(%d) = %s, want %s", tt.count, got, tt.want)
+			}
+			if got := stage.HasRoomFor(tt.count); got != tt.wantRoom {
+				t.Errorf("HasRoomFor(%d) = %v, want %v", tt.count, got, tt.wantRoom)
+			}
+		})
+	}
+}
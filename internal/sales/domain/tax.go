@@ -0,0 +1,303 @@
+// Package domain contains the domain layer for the Sales Pipeline service.
+package domain
+
+import (
+	"errors"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Tax errors
+var (
+	ErrInvalidTaxRate          = errors.New("tax rate must be between 0 and 100 percent")
+	ErrInvalidTaxCode          = errors.New("tax code is required")
+	ErrInvalidTaxType          = errors.New("invalid tax type")
+	ErrDuplicateTaxCode        = errors.New("duplicate tax code")
+	ErrMultipleDefaultTaxRates = errors.New("only one tax rate can be the default")
+	ErrTaxRateNotFound         = errors.New("tax rate not found")
+	ErrTaxRegistrationRequired = errors.New("tax registration number is required when tax is enabled")
+)
+
+// TaxType identifies the kind of tax a rate charges.
+type TaxType string
+
+const (
+	// TaxTypeSalesTax is Malaysian Sales Tax, charged on taxable goods.
+	TaxTypeSalesTax TaxType = "sales_tax"
+	// TaxTypeServiceTax is Malaysian Service Tax, charged on taxable services.
+	TaxTypeServiceTax TaxType = "service_tax"
+	// TaxTypeGST is a goods and services tax, kept for historical GST documents.
+	TaxTypeGST TaxType = "gst"
+	// TaxTypeExempt marks exempt or zero-rated supplies.
+	TaxTypeExempt TaxType = "exempt"
+)
+
+// IsValid checks if the tax type is valid.
+func (t TaxType) IsValid() bool {
+	switch t {
+	case TaxTypeSalesTax, TaxTypeServiceTax, TaxTypeGST, TaxTypeExempt:
+		return true
+	}
+	return false
+}
+
+// TaxRate is a named tax rate a tenant can apply to priced lines.
+type TaxRate struct {
+	Code      string  `json:"code"`
+	Name      string  `json:"name"`
+	Type      TaxType `json:"type"`
+	Rate      float64 `json:"rate"` // Percentage
+	IsDefault bool    `json:"is_default"`
+}
+
+// Validate validates the tax rate.
+func (r TaxRate) Validate() error {
+	if strings.TrimSpace(r.Code) == "" {
+		return ErrInvalidTaxCode
+	}
+	if !r.Type.IsValid() {
+		return ErrInvalidTaxType
+	}
+	if r.Rate < 0 || r.Rate > 100 || math.IsNaN(r.Rate) {
+		return ErrInvalidTaxRate
+	}
+	return nil
+}
+
+// DefaultTaxRates returns the Malaysian SST rates offered to new tenants.
+func DefaultTaxRates() []TaxRate {
+	return []TaxRate{
+		{Code: "SST-10", Name: "Sales Tax 10%", Type: TaxTypeSalesTax, Rate: 10, IsDefault: true},
+		{Code: "SST-5", Name: "Sales Tax 5%", Type: TaxTypeSalesTax, Rate: 5},
+		{Code: "ST-8", Name: "Service Tax 8%", Type: TaxTypeServiceTax, Rate: 8},
+		{Code: "ST-6", Name: "Service Tax 6%", Type: TaxTypeServiceTax, Rate: 6},
+		{Code: "EX", Name: "Exempt", Type: TaxTypeExempt, Rate: 0},
+	}
+}
+
+// ============================================================================
+// Tax Settings
+// ============================================================================
+
+// TaxSettings holds a tenant's tax registration and configured rates.
+type TaxSettings struct {
+	TenantID           uuid.UUID `json:"tenant_id" db:"tenant_id"`
+	Enabled            bool      `json:"enabled" db:"enabled"`
+	RegistrationNumber string    `json:"registration_number,omitempty" db:"registration_number"`
+	PricesIncludeTax   bool      `json:"prices_include_tax" db:"prices_include_tax"`
+	Rates              []TaxRate `json:"rates" db:"-"`
+	UpdatedAt          time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// DefaultTaxSettings returns the settings used for tenants without a configuration.
+// Tax is disabled until the tenant records its SST registration number.
+func DefaultTaxSettings(tenantID uuid.UUID) *TaxSettings {
+	return &TaxSettings{
+		TenantID: tenantID,
+		Rates:    DefaultTaxRates(),
+	}
+}
+
+// Validate validates the tax settings.
+func (s *TaxSettings) Validate() error {
+	if s.Enabled && strings.TrimSpace(s.RegistrationNumber) == "" {
+		return ErrTaxRegistrationRequired
+	}
+
+	seen := make(map[string]bool, len(s.Rates))
+	defaults := 0
+	for _, rate := range s.Rates {
+		if err := rate.Validate(); err != nil {
+			return err
+		}
+		code := strings.ToUpper(rate.Code)
+		if seen[code] {
+			return ErrDuplicateTaxCode
+		}
+		seen[code] = true
+		if rate.IsDefault {
+			defaults++
+		}
+	}
+	if defaults > 1 {
+		return ErrMultipleDefaultTaxRates
+	}
+	return nil
+}
+
+// FindRate returns the rate with the given code, ignoring case.
+func (s *TaxSettings) FindRate(code string) (*TaxRate, bool) {
+	for i := range s.Rates {
+		if strings.EqualFold(s.Rates[i].Code, code) {
+			return &s.Rates[i], true
+		}
+	}
+	return nil, false
+}
+
+// DefaultRate returns the tenant's default rate, or nil if none is marked.
+func (s *TaxSettings) DefaultRate() *TaxRate {
+	for i := range s.Rates {
+		if s.Rates[i].IsDefault {
+			return &s.Rates[i]
+		}
+	}
+	return nil
+}
+
+// ResolveRate returns the rate to apply for a tax code. An empty code selects
+// the default rate. A nil rate means no tax applies, which is always the case
+// while tax is disabled.
+func (s *TaxSettings) ResolveRate(code string) (*TaxRate, error) {
+	if !s.Enabled {
+		return nil, nil
+	}
+	if strings.TrimSpace(code) == "" {
+		return s.DefaultRate(), nil
+	}
+	rate, ok := s.FindRate(code)
+	if !ok {
+		return nil, ErrTaxRateNotFound
+	}
+	return rate, nil
+}
+
+// ============================================================================
+// Tax Calculation
+// ============================================================================
+
+// CalculateTax splits a line amount into its net amount and tax at a
+// percentage rate. Inclusive amounts already contain the tax.
+func CalculateTax(amount Money, rate float64, inclusive bool) (net, tax Money) {
+	if rate <= 0 {
+		return amount, Money{Amount: 0, Currency: amount.Currency}
+	}
+	if inclusive {
+		tax = Money{
+			Amount:   int64(math.Round(float64(amount.Amount) * rate / (100 + rate))),
+			Currency: amount.Currency,
+		}
+		net, _ = amount.Subtract(tax)
+		return net, tax
+	}
+	return amount, amount.Multiply(rate / 100)
+}
+
+// TaxLine is the tax charged at one rate on a document.
+type TaxLine struct {
+	Code          string  `json:"code"`
+	Name          string  `json:"name"`
+	Rate          float64 `json:"rate"`
+	TaxableAmount Money   `json:"taxable_amount"`
+	TaxAmount     Money   `json:"tax_amount"`
+}
+
+// TaxSummary is the tax breakdown of a quote or invoice.
+type TaxSummary struct {
+	Subtotal Money     `json:"subtotal"` // Net of tax
+	TotalTax Money     `json:"total_tax"`
+	Total    Money     `json:"total"`
+	Lines    []TaxLine `json:"lines"`
+}
+
+// taxSummaryBuilder accumulates tax lines grouped by code and rate.
+type taxSummaryBuilder struct {
+	summary TaxSummary
+	index   map[string]int
+}
+
+func newTaxSummaryBuilder(currency string) *taxSummaryBuilder {
+	zero, _ := Zero(currency)
+	return &taxSummaryBuilder{
+		summary: TaxSummary{Subtotal: zero, TotalTax: zero, Total: zero, Lines: []TaxLine{}},
+		index:   make(map[string]int),
+	}
+}
+
+// add records a line's net amount and tax. Untaxed lines only count towards the totals.
+func (b *taxSummaryBuilder) add(code, name string, rate float64, net, tax Money) {
+	b.summary.Subtotal, _ = b.summary.Subtotal.Add(net)
+	b.summary.TotalTax, _ = b.summary.TotalTax.Add(tax)
+	b.summary.Total, _ = b.summary.Total.Add(net)
+	b.summary.Total, _ = b.summary.Total.Add(tax)
+
+	if code == "" && rate == 0 && tax.IsZero() {
+		return
+	}
+	if name == "" {
+		name = code
+	}
+	if name == "" {
+		name = "Tax"
+	}
+
+	key := strings.ToUpper(code) + "|" + name
+	i, ok := b.index[key]
+	if !ok {
+		zero, _ := Zero(net.Currency)
+		b.summary.Lines = append(b.summary.Lines, TaxLine{Code: code, Name: name, Rate: rate, TaxableAmount: zero, TaxAmount: zero})
+		i = len(b.summary.Lines) - 1
+		b.index[key] = i
+	}
+	line := &b.summary.Lines[i]
+	line.TaxableAmount, _ = line.TaxableAmount.Add(net)
+	line.TaxAmount, _ = line.TaxAmount.Add(tax)
+}
+
+// Prorate scales the summary to a partial amount of its total, e.g. for an
+// invoice covering part of a deal. Rounding differences go to the last line
+// so the prorated tax lines add up to the prorated total tax.
+func (s TaxSummary) Prorate(amount Money) TaxSummary {
+	zero, _ := Zero(amount.Currency)
+	result := TaxSummary{Subtotal: amount, TotalTax: zero, Total: amount, Lines: []TaxLine{}}
+	if s.Total.Amount == 0 || s.TotalTax.Amount == 0 {
+		return result
+	}
+
+	ratio := float64(amount.Amount) / float64(s.Total.Amount)
+	result.TotalTax = Money{Amount: int64(math.Round(float64(s.TotalTax.Amount) * ratio)), Currency: amount.Currency}
+	result.Subtotal, _ = amount.Subtract(result.TotalTax)
+
+	remaining := result.TotalTax.Amount
+	for i, line := range s.Lines {
+		tax := int64(math.Round(float64(line.TaxAmount.Amount) * ratio))
+		if i == len(s.Lines)-1 {
+			tax = remaining
+		}
+		remaining -= tax
+		result.Lines = append(result.Lines, TaxLine{
+			Code:          line.Code,
+			Name:          line.Name,
+			Rate:          line.Rate,
+			TaxableAmount: Money{Amount: int64(math.Round(float64(line.TaxableAmount.Amount) * ratio)), Currency: amount.Currency},
+			TaxAmount:     Money{Amount: tax, Currency: amount.Currency},
+		})
+	}
+	return result
+}
+
+// TaxSummary returns the tax breakdown of the opportunity's products.
+func (o *Opportunity) TaxSummary() TaxSummary {
+	b := newTaxSummaryBuilder(o.Amount.Currency)
+	for _, p := range o.Products {
+		net, _ := p.TotalPrice.Subtract(p.TaxAmount)
+		b.add(p.TaxCode, p.TaxName, p.Tax, net, p.TaxAmount)
+	}
+	return b.summary
+}
+
+// TaxSummary returns the tax breakdown of the deal's line items.
+func (d *Deal) TaxSummary() TaxSummary {
+	b := newTaxSummaryBuilder(d.Currency)
+	for _, li := range d.LineItems {
+		rate := li.Tax
+		if li.TaxType != "percentage" {
+			rate = 0
+		}
+		b.add(li.TaxCode, li.TaxName, rate, li.Subtotal, li.TaxAmount)
+	}
+	return b.summary
+}
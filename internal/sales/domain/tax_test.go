@@ -0,0 +1,195 @@
+// Package domain contains the domain layer for the Sales Pipeline service.
+package domain
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestCalculateTax(t *testing.T) {
+	tests := []struct {
+		name      string
+		amount    int64
+		rate      float64
+		inclusive bool
+		wantNet   int64
+		wantTax   int64
+	}{
+		{"exclusive", 10000, 10, false, 10000, 1000},
+		{"inclusive", 11000, 10, true, 10000, 1000},
+		{"inclusive rounding", 10000, 8, true, 9259, 741},
+		{"zero rate", 10000, 0, false, 10000, 0},
+		{"zero rate inclusive", 10000, 0, true, 10000, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			net, tax := CalculateTax(Money{Amount: tt.amount, Currency: "MYR"}, tt.rate, tt.inclusive)
+			if net.Amount != tt.wantNet || tax.Amount != tt.wantTax {
+				t.Errorf("CalculateTax() = (%d, %d), want (%d, %d)", net.Amount, tax.Amount, tt.wantNet, tt.wantTax)
+			}
+			if net.Currency != "MYR" || tax.Currency != "MYR" {
+				t.Errorf("CalculateTax() currency = (%s, %s), want MYR", net.Currency, tax.Currency)
+			}
+		})
+	}
+}
+
+func TestTaxSettings_Validate(t *testing.T) {
+	tests := []struct {
+		name     string
+		settings TaxSettings
+		wantErr  error
+	}{
+		{"defaults", *DefaultTaxSettings(uuid.New()), nil},
+		{"enabled without registration", TaxSettings{Enabled: true, Rates: DefaultTaxRates()}, ErrTaxRegistrationRequired},
+		{"enabled with registration", TaxSettings{Enabled: true, RegistrationNumber: "W10-1808-32000123", Rates: DefaultTaxRates()}, nil},
+		{"invalid rate", TaxSettings{Rates: []TaxRate{{Code: "X", Name: "X", Type: TaxTypeSalesTax, Rate: 120}}}, ErrInvalidTaxRate},
+		{"invalid type", TaxSettings{Rates: []TaxRate{{Code: "X", Name: "X", Type: "vat", Rate: 5}}}, ErrInvalidTaxType},
+		{"missing code", TaxSettings{Rates: []TaxRate{{Name: "X", Type: TaxTypeSalesTax, Rate: 5}}}, ErrInvalidTaxCode},
+		{"duplicate code", TaxSettings{Rates: []TaxRate{
+			{Code: "SST-10", Name: "A", Type: TaxTypeSalesTax, Rate: 10},
+			{Code: "sst-10", Name: "B", Type: TaxTypeSalesTax, Rate: 10},
+		}}, ErrDuplicateTaxCode},
+		{"multiple defaults", TaxSettings{Rates: []TaxRate{
+			{Code: "A", Name: "A", Type: TaxTypeSalesTax, Rate: 10, IsDefault: true},
+			{Code: "B", Name: "B", Type: TaxTypeServiceTax, Rate: 8, IsDefault: true},
+		}}, ErrMultipleDefaultTaxRates},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.settings.Validate()
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("Validate() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestTaxSettings_ResolveRate(t *testing.T) {
+	settings := DefaultTaxSettings(uuid.New())
+
+	rate, err := settings.ResolveRate("ST-8")
+	if err != nil || rate != nil {
+		t.Fatalf("ResolveRate() while disabled = (%v, %v), want (nil, nil)", rate, err)
+	}
+
+	settings.Enabled = true
+	settings.RegistrationNumber = "W10-1808-32000123"
+
+	rate, err = settings.ResolveRate("")
+	if err != nil || rate == nil || rate.Code != "SST-10" {
+		t.Errorf("ResolveRate(\"\") = (%v, %v), want default SST-10", rate, err)
+	}
+
+	rate, err = settings.ResolveRate("st-8")
+	if err != nil || rate == nil || rate.Rate != 8 {
+		t.Errorf("ResolveRate(st-8) = (%v, %v), want 8%%", rate, err)
+	}
+
+	if _, err := settings.ResolveRate("VAT"); !errors.Is(err, ErrTaxRateNotFound) {
+		t.Errorf("ResolveRate(VAT) error = %v, want %v", err, ErrTaxRateNotFound)
+	}
+}
+
+func TestOpportunity_TaxSummary(t *testing.T) {
+	salesTax := &TaxRate{Code: "SST-10", Name: "Sales Tax 10%", Rate: 10}
+	serviceTax := &TaxRate{Code: "ST-8", Name: "Service Tax 8%", Rate: 8}
+
+	products := []OpportunityProduct{
+		{Quantity: 2, UnitPrice: Money{Amount: 5000, Currency: "MYR"}},
+		{Quantity: 1, UnitPrice: Money{Amount: 20000, Currency: "MYR"}},
+		{Quantity: 1, UnitPrice: Money{Amount: 5000, Currency: "MYR"}},
+	}
+	products[0].ApplyTaxRate(salesTax, false)
+	products[1].ApplyTaxRate(serviceTax, false)
+	products[2].ApplyTaxRate(salesTax, false)
+
+	opp := &Opportunity{Amount: Money{Currency: "MYR"}, Products: products}
+	summary := opp.TaxSummary()
+
+	if summary.Subtotal.Amount != 35000 || summary.TotalTax.Amount != 3100 || summary.Total.Amount != 38100 {
+		t.Errorf("TaxSummary() totals = %d/%d/%d, want 35000/3100/38100",
+			summary.Subtotal.Amount, summary.TotalTax.Amount, summary.Total.Amount)
+	}
+	if len(summary.Lines) != 2 {
+		t.Fatalf("TaxSummary() lines = %d, want 2", len(summary.Lines))
+	}
+	if summary.Lines[0].Code != "SST-10" || summary.Lines[0].TaxableAmount.Amount != 15000 || summary.Lines[0].TaxAmount.Amount != 1500 {
+		t.Errorf("TaxSummary() SST line = %+v", summary.Lines[0])
+	}
+	if summary.Lines[1].Code != "ST-8" || summary.Lines[1].TaxAmount.Amount != 1600 {
+		t.Errorf("TaxSummary() ST line = %+v", summary.Lines[1])
+	}
+}
+
+func TestTaxSummary_Prorate(t *testing.T) {
+	summary := TaxSummary{
+		Subtotal: Money{Amount: 30000, Currency: "MYR"},
+		TotalTax: Money{Amount: 2600, Currency: "MYR"},
+		Total:    Money{Amount: 32600, Currency: "MYR"},
+		Lines: []TaxLine{
+			{Code: "SST-10", Rate: 10, TaxableAmount: Money{Amount: 10000, Currency: "MYR"}, TaxAmount: Money{Amount: 1000, Currency: "MYR"}},
+			{Code: "ST-8", Rate: 8, TaxableAmount: Money{Amount: 20000, Currency: "MYR"}, TaxAmount: Money{Amount: 1600, Currency: "MYR"}},
+		},
+	}
+
+	prorated := summary.Prorate(Money{Amount: 10000, Currency: "MYR"})
+
+	if prorated.Total.Amount != 10000 {
+		t.Errorf("Prorate() Total = %d, want 10000", prorated.Total.Amount)
+	}
+	if prorated.Subtotal.Amount+prorated.TotalTax.Amount != 10000 {
+		t.Errorf("Prorate() Subtotal + TotalTax = %d, want 10000", prorated.Subtotal.Amount+prorated.TotalTax.Amount)
+	}
+
+	var lineTax int64
+	for _, line := range prorated.Lines {
+		lineTax += line.TaxAmount.Amount
+	}
+	if lineTax != prorated.TotalTax.Amount {
+		t.Errorf("Prorate() line tax = %d, want %d", lineTax, prorated.TotalTax.Amount)
+	}
+
+	untaxed := TaxSummary{Total: Money{Amount: 5000, Currency: "MYR"}}.Prorate(Money{Amount: 2500, Currency: "MYR"})
+	if untaxed.TotalTax.Amount != 0 || untaxed.Subtotal.Amount != 2500 || len(untaxed.Lines) != 0 {
+		t.Errorf("Prorate() untaxed = %+v", untaxed)
+	}
+}
+
+func TestDeal_CreateInvoiceIncludesTax(t *testing.T) {
+	item := DealLineItem{
+		ID:           uuid.New(),
+		Quantity:     1,
+		UnitPrice:    Money{Amount: 10000, Currency: "MYR"},
+		DiscountType: "percentage",
+	}
+	item.ApplyTaxRate(&TaxRate{Code: "SST-10", Name: "Sales Tax 10%", Rate: 10}, false)
+
+	if item.Subtotal.Amount != 10000 || item.TaxAmount.Amount != 1000 || item.Total.Amount != 11000 {
+		t.Fatalf("ApplyTaxRate() line = %d/%d/%d, want 10000/1000/11000", item.Subtotal.Amount, item.TaxAmount.Amount, item.Total.Amount)
+	}
+
+	deal := &Deal{
+		ID:                uuid.New(),
+		Currency:          "MYR",
+		LineItems:         []DealLineItem{item},
+		TotalAmount:       item.Total,
+		OutstandingAmount: item.Total,
+	}
+
+	invoice, err := deal.CreateInvoice("INV-001", Money{Amount: 5500, Currency: "MYR"}, time.Now().AddDate(0, 0, 30))
+	if err != nil {
+		t.Fatalf("CreateInvoice() error = %v", err)
+	}
+	if invoice.Subtotal.Amount != 5000 || invoice.TaxAmount.Amount != 500 {
+		t.Errorf("CreateInvoice() subtotal/tax = %d/%d, want 5000/500", invoice.Subtotal.Amount, invoice.TaxAmount.Amount)
+	}
+	if len(invoice.TaxLines) != 1 || invoice.TaxLines[0].Code != "SST-10" || invoice.TaxLines[0].TaxAmount.Amount != 500 {
+		t.Errorf("CreateInvoice() tax lines = %+v", invoice.TaxLines)
+	}
+}
@@ -0,0 +1,156 @@
+// Package accounting connects the sales service to tenants' accounting
+// systems, such as Xero, SQL Account and AutoCount.
+package accounting
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/kilang-desa-murni/crm/internal/sales/application/ports"
+	"github.com/kilang-desa-murni/crm/internal/sales/infrastructure/webhook"
+)
+
+// maxWebhookAge is how old a signed webhook may be, to stop replays.
+const maxWebhookAge = 5 * time.Minute
+
+// ============================================================================
+// HTTP Connector
+// ============================================================================
+
+// HTTPConnectorConfig holds configuration for the accounting connector.
+type HTTPConnectorConfig struct {
+	Timeout time.Duration
+}
+
+// HTTPConnector talks to the connector a tenant runs in front of its
+// accounting system, such as a Xero app or an on-premise SQL Account or
+// AutoCount bridge. Records are posted to the connection URL as
+//
+//	{"provider": "xero", "record_type": "invoice", "data": {...}}
+//
+// signed the way the service signs its webhooks, and the connector answers
+// with {"external_id": "..."}. The connector posts payments received in the
+// accounting system back as {"payments": [...]}, signed with the same secret.
+type HTTPConnector struct {
+	httpClient *http.Client
+}
+
+// NewHTTPConnector creates a new accounting connector. Redirects are not
+// followed, so records cannot be sent on to another host.
+func NewHTTPConnector(config HTTPConnectorConfig) *HTTPConnector {
+	if config.Timeout <= 0 {
+		config.Timeout = 30 * time.Second
+	}
+	return &HTTPConnector{
+		httpClient: &http.Client{
+			Timeout:       config.Timeout,
+			CheckRedirect: func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse },
+		},
+	}
+}
+
+// pushRequest is the envelope records are pushed in.
+type pushRequest struct {
+	Provider   string      `json:"provider"`
+	RecordType string      `json:"record_type"`
+	Data       interface{} `json:"data"`
+}
+
+// pushResponse is the connector's answer to a push.
+type pushResponse struct {
+	ExternalID string `json:"external_id"`
+}
+
+// webhookPayload is the format the connector posts payments in.
+type webhookPayload struct {
+	Payments []ports.AccountingPaymentUpdate `json:"payments"`
+}
+
+// PushInvoice pushes an invoice and returns its ID in the accounting system.
+func (c *HTTPConnector) PushInvoice(ctx context.Context, endpoint ports.AccountingEndpoint, invoice *ports.AccountingInvoice) (string, error) {
+	return c.push(ctx, endpoint, "invoice", invoice)
+}
+
+// PushPayment pushes a payment and returns its ID in the accounting system.
+func (c *HTTPConnector) PushPayment(ctx context.Context, endpoint ports.AccountingEndpoint, payment *ports.AccountingPayment) (string, error) {
+	return c.push(ctx, endpoint, "payment", payment)
+}
+
+func (c *HTTPConnector) push(ctx context.Context, endpoint ports.AccountingEndpoint, recordType string, data interface{}) (string, error) {
+	body, err := json.Marshal(pushRequest{
+		Provider:   endpoint.Provider,
+		RecordType: recordType,
+		Data:       data,
+	})
+	if err != nil {
+		return "", fmt.Errorf("%s: failed to marshal %s: %w", endpoint.Provider, recordType, err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint.URL, bytes.NewReader(body))
+	if err != nil {
+		return "", fmt.Errorf("%s: failed to create request: %w", endpoint.Provider, err)
+	}
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set(webhook.TimestampHeader, timestamp)
+	req.Header.Set(webhook.SignatureHeader, "sha256="+webhook.Sign(endpoint.Secret, timestamp, body))
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("%s: request failed: %w", endpoint.Provider, err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return "", fmt.Errorf("%s: failed to read response: %w", endpoint.Provider, err)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return "", fmt.Errorf("%s: connector returned status %d: %s", endpoint.Provider, resp.StatusCode, strings.TrimSpace(string(respBody)))
+	}
+
+	var result pushResponse
+	if err := json.Unmarshal(respBody, &result); err != nil {
+		return "", fmt.Errorf("%s: failed to parse response: %w", endpoint.Provider, err)
+	}
+	if result.ExternalID == "" {
+		return "", fmt.Errorf("%s: connector returned no external_id", endpoint.Provider)
+	}
+	return result.ExternalID, nil
+}
+
+// ParseWebhook verifies the request signature and returns its payments.
+func (c *HTTPConnector) ParseWebhook(ctx context.Context, secret string, headers map[string]string, body []byte) ([]ports.AccountingPaymentUpdate, error) {
+	timestamp := headers[webhook.TimestampHeader]
+	signedAt, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return nil, ports.ErrInvalidAccountingWebhook
+	}
+	if age := time.Since(time.Unix(signedAt, 0)); age > maxWebhookAge || age < -maxWebhookAge {
+		return nil, ports.ErrInvalidAccountingWebhook
+	}
+
+	signature := strings.TrimPrefix(headers[webhook.SignatureHeader], "sha256=")
+	expected := webhook.Sign(secret, timestamp, body)
+	if secret == "" || !hmac.Equal([]byte(signature), []byte(expected)) {
+		return nil, ports.ErrInvalidAccountingWebhook
+	}
+
+	var payload webhookPayload
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return nil, fmt.Errorf("failed to parse accounting payments: %w", err)
+	}
+	return payload.Payments, nil
+}
+
+// Ensure HTTPConnector implements ports.AccountingConnector
+var _ ports.AccountingConnector = (*HTTPConnector)(nil)
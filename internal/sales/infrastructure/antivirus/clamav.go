@@ -0,0 +1,122 @@
+// Package antivirus contains virus scanner adapters for the Sales Pipeline service.
+package antivirus
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/kilang-desa-murni/crm/internal/sales/application/ports"
+)
+
+// ClamAVConfig holds configuration for the ClamAV scanner.
+type ClamAVConfig struct {
+	// Address is the clamd TCP address, e.g. "clamav:3310".
+	Address string
+	// Timeout bounds a scan, including connecting to clamd.
+	Timeout time.Duration
+	// ChunkSize is the size of the chunks content is streamed to clamd in.
+	ChunkSize int
+}
+
+// DefaultClamAVConfig returns the default ClamAV configuration.
+func DefaultClamAVConfig(address string) ClamAVConfig {
+	return ClamAVConfig{
+		Address:   address,
+		Timeout:   30 * time.Second,
+		ChunkSize: 64 << 10,
+	}
+}
+
+// ClamAV implements ports.VirusScanner with a clamd daemon, streaming content
+// to it with the INSTREAM command. clamd's StreamMaxLength must be at least
+// the largest attachment, 10 MB, or larger files fail to scan.
+type ClamAV struct {
+	config ClamAVConfig
+	dialer net.Dialer
+}
+
+// NewClamAV creates a new ClamAV scanner.
+func NewClamAV(config ClamAVConfig) *ClamAV {
+	defaults := DefaultClamAVConfig(config.Address)
+	if config.Timeout <= 0 {
+		config.Timeout = defaults.Timeout
+	}
+	if config.ChunkSize <= 0 {
+		config.ChunkSize = defaults.ChunkSize
+	}
+	return &ClamAV{config: config}
+}
+
+// Name names the scanner in scan results.
+func (c *ClamAV) Name() string {
+	return "clamav"
+}
+
+// Scan streams content to clamd and returns the signature it found, or an
+// empty string when the content is clean.
+func (c *ClamAV) Scan(ctx context.Context, content []byte) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, c.config.Timeout)
+	defer cancel()
+
+	conn, err := c.dialer.DialContext(ctx, "tcp", c.config.Address)
+	if err != nil {
+		return "", fmt.Errorf("failed to connect to clamd: %w", err)
+	}
+	defer conn.Close()
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = conn.SetDeadline(deadline)
+	}
+
+	// The z prefix makes clamd terminate its reply with a null byte
+	if _, err := conn.Write([]byte("zINSTREAM\x00")); err != nil {
+		return "", fmt.Errorf("failed to send scan command: %w", err)
+	}
+	size := make([]byte, 4)
+	for offset := 0; offset < len(content); offset += c.config.ChunkSize {
+		chunk := content[offset:min(offset+c.config.ChunkSize, len(content))]
+		binary.BigEndian.PutUint32(size, uint32(len(chunk)))
+		if _, err := conn.Write(size); err != nil {
+			return "", fmt.Errorf("failed to stream content: %w", err)
+		}
+		if _, err := conn.Write(chunk); err != nil {
+			return "", fmt.Errorf("failed to stream content: %w", err)
+		}
+	}
+	// A zero-length chunk ends the stream
+	binary.BigEndian.PutUint32(size, 0)
+	if _, err := conn.Write(size); err != nil {
+		return "", fmt.Errorf("failed to end stream: %w", err)
+	}
+
+	reply, err := bufio.NewReader(conn).ReadBytes(0)
+	if err != nil {
+		return "", fmt.Errorf("failed to read scan result: %w", err)
+	}
+	return parseReply(string(bytes.TrimRight(reply, "\x00")))
+}
+
+// parseReply parses a clamd INSTREAM reply: "stream: OK", "stream: <signature>
+// FOUND" or "<message> ERROR".
+func parseReply(reply string) (string, error) {
+	reply = strings.TrimSpace(reply)
+	switch {
+	case strings.HasSuffix(reply, " FOUND"):
+		signature := strings.TrimSuffix(strings.TrimPrefix(reply, "stream: "), " FOUND")
+		return signature, nil
+	case strings.HasSuffix(reply, "OK"):
+		return "", nil
+	case strings.HasSuffix(reply, " ERROR"):
+		return "", fmt.Errorf("clamd: %s", strings.TrimSuffix(reply, " ERROR"))
+	}
+	return "", errors.New("unexpected clamd reply: " + reply)
+}
+
+// Ensure ClamAV implements ports.VirusScanner
+var _ ports.VirusScanner = (*ClamAV)(nil)
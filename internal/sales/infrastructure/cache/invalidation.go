@@ -0,0 +1,101 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+
+	"github.com/kilang-desa-murni/crm/internal/sales/application/ports"
+	"github.com/kilang-desa-murni/crm/internal/sales/infrastructure/messaging"
+	"github.com/kilang-desa-murni/crm/pkg/logger"
+)
+
+// ============================================================================
+// Event-Driven Invalidation
+// ============================================================================
+
+// invalidationRule maps events to the cache entities they make stale. A
+// routing key ending in ".*" matches every event of that aggregate.
+type invalidationRule struct {
+	exchange   string
+	routingKey string
+	entities   []string
+}
+
+var invalidationRules = []invalidationRule{
+	{messaging.SalesEventsExchange, "sales.opportunity.*", []string{"opportunity", "report"}},
+	{messaging.SalesEventsExchange, "sales.deal.*", []string{"deal", "report"}},
+	{messaging.SalesEventsExchange, "sales.lead.*", []string{"lead"}},
+	{messaging.SalesEventsExchange, "sales.pipeline.*", []string{"pipeline"}},
+	// Sales entities embed customer names and contacts
+	{messaging.CustomerEventsExchange, "customer.updated", []string{"lead", "opportunity", "deal"}},
+	{messaging.CustomerEventsExchange, "customer.deleted", []string{"lead", "opportunity", "deal"}},
+	// Erased customers are dropped once the sales records are erased
+	{messaging.SalesEventsExchange, "sales.customer.erased", []string{"lead", "opportunity", "deal"}},
+}
+
+// matches reports whether a routing key matches the rule.
+func (r invalidationRule) matches(routingKey string) bool {
+	if prefix, ok := strings.CutSuffix(r.routingKey, "*"); ok {
+		rest, found := strings.CutPrefix(routingKey, prefix)
+		return found && rest != "" && !strings.Contains(rest, ".")
+	}
+	return r.routingKey == routingKey
+}
+
+// CacheInvalidator removes cached entries when domain events report that the
+// underlying data changed, including changes made by other services.
+type CacheInvalidator struct {
+	cache ports.CacheService
+	log   *logger.Logger
+}
+
+// NewCacheInvalidator creates a new cache invalidator.
+func NewCacheInvalidator(cache ports.CacheService, log *logger.Logger) *CacheInvalidator {
+	return &CacheInvalidator{cache: cache, log: log}
+}
+
+// Bindings returns the queue bindings for the events the invalidator handles.
+func (i *CacheInvalidator) Bindings() []messaging.ConsumerBinding {
+	bindings := make([]messaging.ConsumerBinding, len(invalidationRules))
+	for i, rule := range invalidationRules {
+		bindings[i] = messaging.ConsumerBinding{Exchange: rule.exchange, RoutingKey: rule.routingKey}
+	}
+	return bindings
+}
+
+// Handle invalidates the cache entries affected by an event. Entries are
+// invalidated for the event's tenant, or for every tenant when the event does
+// not carry one. Unknown events are ignored.
+func (i *CacheInvalidator) Handle(ctx context.Context, event messaging.ConsumedEvent) error {
+	var entities []string
+	for _, rule := range invalidationRules {
+		if rule.matches(event.Type) {
+			entities = rule.entities
+			break
+		}
+	}
+	if entities == nil {
+		return nil
+	}
+
+	tenant := "*"
+	if tenantID, err := uuid.Parse(event.TenantID); err == nil {
+		tenant = tenantID.String()
+	}
+
+	for _, entity := range entities {
+		if err := i.cache.DeletePattern(ctx, entity+":"+tenant+":*"); err != nil {
+			return fmt.Errorf("failed to invalidate %s cache: %w", entity, err)
+		}
+	}
+
+	i.log.Debug().
+		Str("event_type", event.Type).
+		Str("tenant_id", tenant).
+		Int("entities", len(entities)).
+		Msg("Cache invalidated")
+	return nil
+}
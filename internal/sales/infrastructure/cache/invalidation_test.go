@@ -0,0 +1,126 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/kilang-desa-murni/crm/internal/sales/infrastructure/messaging"
+	"github.com/kilang-desa-murni/crm/pkg/logger"
+)
+
+// recordingCache records the patterns deleted through DeletePattern.
+type recordingCache struct {
+	*MemoryCacheService
+	patterns []string
+	err      error
+}
+
+func (c *recordingCache) DeletePattern(ctx context.Context, pattern string) error {
+	if c.err != nil {
+		return c.err
+	}
+	c.patterns = append(c.patterns, pattern)
+	return nil
+}
+
+func TestInvalidationRule_Matches(t *testing.T) {
+	tests := []struct {
+		name       string
+		rule       string
+		routingKey string
+		want       bool
+	}{
+		{"exact key", "customer.updated", "customer.updated", true},
+		{"other exact key", "customer.updated", "customer.deleted", false},
+		{"wildcard matches one segment", "sales.deal.*", "sales.deal.won", true},
+		{"wildcard needs a segment", "sales.deal.*", "sales.deal.", false},
+		{"wildcard does not match deeper keys", "sales.deal.*", "sales.deal.line_item.added", false},
+		{"wildcard does not match the aggregate alone", "sales.deal.*", "sales.deal", false},
+		{"wildcard does not match a longer aggregate", "sales.deal.*", "sales.dealer.created", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rule := invalidationRule{routingKey: tt.rule}
+			if got := rule.matches(tt.routingKey); got != tt.want {
+				t.Errorf("matches(%q) = %v, want %v", tt.routingKey, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCacheInvalidator_Handle(t *testing.T) {
+	const tenantID = "5f0e7b1c-3a3d-4c8e-9a55-1c2b3d4e5f60"
+
+	tests := []struct {
+		name  string
+		event messaging.ConsumedEvent
+		want  []string
+	}{
+		{
+			name:  "opportunity event clears opportunities and reports",
+			event: messaging.ConsumedEvent{Type: "sales.opportunity.won", TenantID: tenantID},
+			want:  []string{"opportunity:" + tenantID + ":*", "report:" + tenantID + ":*"},
+		},
+		{
+			name:  "customer event clears entities embedding the customer",
+			event: messaging.ConsumedEvent{Type: "customer.updated", TenantID: tenantID},
+			want:  []string{"lead:" + tenantID + ":*", "opportunity:" + tenantID + ":*", "deal:" + tenantID + ":*"},
+		},
+		{
+			name:  "event without a tenant clears every tenant",
+			event: messaging.ConsumedEvent{Type: "sales.pipeline.updated"},
+			want:  []string{"pipeline:*:*"},
+		},
+		{
+			name:  "malformed tenant is not used in the pattern",
+			event: messaging.ConsumedEvent{Type: "sales.lead.created", TenantID: "*:secret"},
+			want:  []string{"lead:*:*"},
+		},
+		{
+			name:  "unknown event is ignored",
+			event: messaging.ConsumedEvent{Type: "sales.quota.updated", TenantID: tenantID},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cache := &recordingCache{MemoryCacheService: NewMemoryCacheService(time.Minute)}
+			invalidator := NewCacheInvalidator(cache, logger.New(logger.Config{Level: "error"}))
+
+			if err := invalidator.Handle(context.Background(), tt.event); err != nil {
+				t.Fatalf("Handle() error = %v", err)
+			}
+			if !reflect.DeepEqual(cache.patterns, tt.want) {
+				t.Errorf("deleted patterns = %v, want %v", cache.patterns, tt.want)
+			}
+		})
+	}
+}
+
+func TestCacheInvalidator_HandleError(t *testing.T) {
+	cache := &recordingCache{MemoryCacheService: NewMemoryCacheService(time.Minute), err: errors.New("connection refused")}
+	invalidator := NewCacheInvalidator(cache, logger.New(logger.Config{Level: "error"}))
+
+	err := invalidator.Handle(context.Background(), messaging.ConsumedEvent{Type: "sales.deal.won"})
+	if err == nil {
+		t.Fatal("expected the error to be returned so the event is redelivered")
+	}
+}
+
+func TestCacheInvalidator_Bindings(t *testing.T) {
+	invalidator := NewCacheInvalidator(NewMemoryCacheService(time.Minute), logger.New(logger.Config{Level: "error"}))
+
+	bindings := invalidator.Bindings()
+	if len(bindings) != len(invalidationRules) {
+		t.Fatalf("expected %d bindings, got %d", len(invalidationRules), len(bindings))
+	}
+	for i, binding := range bindings {
+		if binding.Exchange != invalidationRules[i].exchange || binding.RoutingKey != invalidationRules[i].routingKey {
+			t.Errorf("binding %d = %+v, want %s %s", i, binding, invalidationRules[i].exchange, invalidationRules[i].routingKey)
+		}
+	}
+}
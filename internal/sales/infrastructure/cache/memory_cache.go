@@ -0,0 +1,214 @@
+package cache
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/kilang-desa-murni/crm/internal/sales/application/ports"
+)
+
+// ============================================================================
+// Memory Cache
+// ============================================================================
+
+// memoryEntry is a cached value and its expiry.
+type memoryEntry struct {
+	value     []byte
+	expiresAt time.Time
+}
+
+// expired reports whether the entry has expired at now.
+func (e memoryEntry) expired(now time.Time) bool {
+	return !e.expiresAt.IsZero() && !now.Before(e.expiresAt)
+}
+
+// MemoryCacheService implements ports.CacheService in process memory, for
+// dev mode and tests. Expired entries are dropped when they are read.
+type MemoryCacheService struct {
+	mu         sync.Mutex
+	entries    map[string]memoryEntry
+	defaultTTL time.Duration
+}
+
+// NewMemoryCacheService creates a new memory cache service. Entries set
+// without a TTL expire after defaultTTL, or never if it is zero.
+func NewMemoryCacheService(defaultTTL time.Duration) *MemoryCacheService {
+	return &MemoryCacheService{entries: make(map[string]memoryEntry), defaultTTL: defaultTTL}
+}
+
+// set stores a value. The caller must hold the lock.
+func (c *MemoryCacheService) set(key string, value []byte, ttl time.Duration) {
+	if ttl <= 0 {
+		ttl = c.defaultTTL
+	}
+
+	entry := memoryEntry{value: append([]byte(nil), value...)}
+	if ttl > 0 {
+		entry.expiresAt = time.Now().Add(ttl)
+	}
+	c.entries[key] = entry
+}
+
+// get returns a live entry. The caller must hold the lock.
+func (c *MemoryCacheService) get(key string) (memoryEntry, bool) {
+	entry, ok := c.entries[key]
+	if !ok {
+		return memoryEntry{}, false
+	}
+	if entry.expired(time.Now()) {
+		delete(c.entries, key)
+		return memoryEntry{}, false
+	}
+	return entry, true
+}
+
+// Get retrieves a value from the cache.
+func (c *MemoryCacheService) Get(ctx context.Context, key string) ([]byte, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.get(key)
+	if !ok {
+		return nil, ErrCacheMiss
+	}
+	return append([]byte(nil), entry.value...), nil
+}
+
+// Set stores a value in the cache. A zero TTL uses the default TTL.
+func (c *MemoryCacheService) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.set(key, value, ttl)
+	return nil
+}
+
+// Delete removes a value from the cache.
+func (c *MemoryCacheService) Delete(ctx context.Context, key string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	delete(c.entries, key)
+	return nil
+}
+
+// DeletePattern removes all values matching a glob pattern, where "*"
+// matches any run of characters and "?" any single character, as in Redis.
+func (c *MemoryCacheService) DeletePattern(ctx context.Context, pattern string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for key := range c.entries {
+		if globMatch(pattern, key) {
+			delete(c.entries, key)
+		}
+	}
+	return nil
+}
+
+// Exists checks if a key exists in the cache.
+func (c *MemoryCacheService) Exists(ctx context.Context, key string) (bool, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	_, ok := c.get(key)
+	return ok, nil
+}
+
+// GetMulti retrieves multiple values from the cache. Missing keys are omitted
+// from the result.
+func (c *MemoryCacheService) GetMulti(ctx context.Context, keys []string) (map[string][]byte, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	result := make(map[string][]byte, len(keys))
+	for _, key := range keys {
+		if entry, ok := c.get(key); ok {
+			result[key] = append([]byte(nil), entry.value...)
+		}
+	}
+	return result, nil
+}
+
+// SetMulti stores multiple values in the cache.
+func (c *MemoryCacheService) SetMulti(ctx context.Context, items map[string][]byte, ttl time.Duration) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for key, value := range items {
+		c.set(key, value, ttl)
+	}
+	return nil
+}
+
+// Increment increments a numeric value, starting from zero for a missing key.
+// The key keeps its expiry.
+func (c *MemoryCacheService) Increment(ctx context.Context, key string, delta int64) (int64, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.get(key)
+	var current int64
+	if ok {
+		n, err := strconv.ParseInt(string(entry.value), 10, 64)
+		if err != nil {
+			return 0, ErrNotInteger
+		}
+		current = n
+	}
+
+	current += delta
+	entry.value = []byte(strconv.FormatInt(current, 10))
+	c.entries[key] = entry
+	return current, nil
+}
+
+// SetNX sets a value only if it doesn't exist.
+func (c *MemoryCacheService) SetNX(ctx context.Context, key string, value []byte, ttl time.Duration) (bool, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if _, ok := c.get(key); ok {
+		return false, nil
+	}
+	c.set(key, value, ttl)
+	return true, nil
+}
+
+// globMatch reports whether key matches a Redis glob pattern made of "*" and
+// "?" wildcards.
+func globMatch(pattern, key string) bool {
+	for len(pattern) > 0 {
+		switch pattern[0] {
+		case '*':
+			// Collapse runs of stars, then try every split of the key
+			for len(pattern) > 0 && pattern[0] == '*' {
+				pattern = pattern[1:]
+			}
+			if pattern == "" {
+				return true
+			}
+			for i := 0; i <= len(key); i++ {
+				if globMatch(pattern, key[i:]) {
+					return true
+				}
+			}
+			return false
+		case '?':
+			if key == "" {
+				return false
+			}
+		default:
+			if key == "" || key[0] != pattern[0] {
+				return false
+			}
+		}
+		pattern, key = pattern[1:], key[1:]
+	}
+	return key == ""
+}
+
+// Ensure MemoryCacheService implements ports.CacheService
+var _ ports.CacheService = (*MemoryCacheService)(nil)
@@ -0,0 +1,247 @@
+// Package cache provides the Redis cache adapter for the sales service.
+package cache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+
+	"github.com/kilang-desa-murni/crm/internal/sales/application/ports"
+)
+
+// ============================================================================
+// Configuration
+// ============================================================================
+
+// RedisCacheConfig holds Redis cache configuration.
+type RedisCacheConfig struct {
+	// KeyPrefix namespaces every key of the service, e.g. "sales:".
+	KeyPrefix string
+	// DefaultTTL is used when Set is called without a TTL and no entity TTL applies.
+	DefaultTTL time.Duration
+	// EntityTTLs sets the TTL per entity, the first segment of a key.
+	EntityTTLs map[string]time.Duration
+	// TTLJitter spreads expiry by up to this fraction of the TTL so keys written
+	// together do not all expire, and reload, at the same moment.
+	TTLJitter float64
+	// ScanCount is the SCAN batch size used for pattern invalidation.
+	ScanCount int64
+}
+
+// DefaultRedisCacheConfig returns default Redis cache configuration.
+func DefaultRedisCacheConfig() RedisCacheConfig {
+	return RedisCacheConfig{
+		KeyPrefix:  "sales:",
+		DefaultTTL: 5 * time.Minute,
+		EntityTTLs: map[string]time.Duration{
+			// Pipelines change rarely and are read on every board load
+			"pipeline":    30 * time.Minute,
+			"opportunity": 5 * time.Minute,
+			"deal":        5 * time.Minute,
+			"lead":        5 * time.Minute,
+			"report":      15 * time.Minute,
+		},
+		TTLJitter: 0.1,
+		ScanCount: 500,
+	}
+}
+
+// TenantKey builds a cache key following the service's key convention,
+// "<entity>:<tenant_id>:<parts...>", which keeps every tenant's entries under
+// its own namespace so they can be invalidated together.
+func TenantKey(entity string, tenantID uuid.UUID, parts ...string) string {
+	key := entity + ":" + tenantID.String()
+	for _, part := range parts {
+		key += ":" + part
+	}
+	return key
+}
+
+// ============================================================================
+// Redis Cache
+// ============================================================================
+
+// RedisCacheService implements ports.CacheService using Redis.
+type RedisCacheService struct {
+	client *redis.Client
+	config RedisCacheConfig
+}
+
+// NewRedisCacheService creates a new Redis cache service.
+func NewRedisCacheService(client *redis.Client, config RedisCacheConfig) *RedisCacheService {
+	if config.DefaultTTL == 0 {
+		config.DefaultTTL = DefaultRedisCacheConfig().DefaultTTL
+	}
+	if config.ScanCount <= 0 {
+		config.ScanCount = DefaultRedisCacheConfig().ScanCount
+	}
+	return &RedisCacheService{client: client, config: config}
+}
+
+// buildKey builds a cache key with the prefix.
+func (c *RedisCacheService) buildKey(key string) string {
+	return c.config.KeyPrefix + key
+}
+
+// ttlFor returns the TTL for a key: the explicit TTL if set, otherwise the
+// entity TTL or the default, with jitter applied.
+func (c *RedisCacheService) ttlFor(key string, ttl time.Duration) time.Duration {
+	if ttl > 0 {
+		return ttl
+	}
+
+	ttl = c.config.DefaultTTL
+	entity, _, _ := strings.Cut(key, ":")
+	if entityTTL, ok := c.config.EntityTTLs[entity]; ok {
+		ttl = entityTTL
+	}
+
+	if c.config.TTLJitter > 0 {
+		ttl += time.Duration(rand.Float64() * c.config.TTLJitter * float64(ttl))
+	}
+	return ttl
+}
+
+// Get retrieves a value from the cache.
+func (c *RedisCacheService) Get(ctx context.Context, key string) ([]byte, error) {
+	data, err := c.client.Get(ctx, c.buildKey(key)).Bytes()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return nil, ErrCacheMiss
+		}
+		return nil, fmt.Errorf("failed to get from cache: %w", err)
+	}
+	return data, nil
+}
+
+// Set stores a value in the cache. A zero TTL uses the TTL strategy for the key.
+func (c *RedisCacheService) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	if err := c.client.Set(ctx, c.buildKey(key), value, c.ttlFor(key, ttl)).Err(); err != nil {
+		return fmt.Errorf("failed to set cache: %w", err)
+	}
+	return nil
+}
+
+// Delete removes a value from the cache.
+func (c *RedisCacheService) Delete(ctx context.Context, key string) error {
+	if err := c.client.Del(ctx, c.buildKey(key)).Err(); err != nil {
+		return fmt.Errorf("failed to delete from cache: %w", err)
+	}
+	return nil
+}
+
+// DeletePattern removes all values matching a glob pattern. Keys are found with
+// SCAN rather than KEYS so large keyspaces do not block Redis.
+func (c *RedisCacheService) DeletePattern(ctx context.Context, pattern string) error {
+	iter := c.client.Scan(ctx, 0, c.buildKey(pattern), c.config.ScanCount).Iterator()
+
+	batch := make([]string, 0, c.config.ScanCount)
+	for iter.Next(ctx) {
+		batch = append(batch, iter.Val())
+		if int64(len(batch)) >= c.config.ScanCount {
+			if err := c.client.Unlink(ctx, batch...).Err(); err != nil {
+				return fmt.Errorf("failed to delete cache keys: %w", err)
+			}
+			batch = batch[:0]
+		}
+	}
+	if err := iter.Err(); err != nil {
+		return fmt.Errorf("failed to scan cache keys: %w", err)
+	}
+
+	if len(batch) > 0 {
+		if err := c.client.Unlink(ctx, batch...).Err(); err != nil {
+			return fmt.Errorf("failed to delete cache keys: %w", err)
+		}
+	}
+	return nil
+}
+
+// InvalidateTenant removes every cached entry of a tenant.
+func (c *RedisCacheService) InvalidateTenant(ctx context.Context, tenantID uuid.UUID) error {
+	return c.DeletePattern(ctx, "*:"+tenantID.String()+":*")
+}
+
+// Exists checks if a key exists in the cache.
+func (c *RedisCacheService) Exists(ctx context.Context, key string) (bool, error) {
+	n, err := c.client.Exists(ctx, c.buildKey(key)).Result()
+	if err != nil {
+		return false, fmt.Errorf("failed to check cache key existence: %w", err)
+	}
+	return n > 0, nil
+}
+
+// GetMulti retrieves multiple values from the cache. Missing keys are omitted
+// from the result.
+func (c *RedisCacheService) GetMulti(ctx context.Context, keys []string) (map[string][]byte, error) {
+	if len(keys) == 0 {
+		return map[string][]byte{}, nil
+	}
+
+	fullKeys := make([]string, len(keys))
+	for i, key := range keys {
+		fullKeys[i] = c.buildKey(key)
+	}
+
+	values, err := c.client.MGet(ctx, fullKeys...).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get from cache: %w", err)
+	}
+
+	result := make(map[string][]byte, len(keys))
+	for i, value := range values {
+		if s, ok := value.(string); ok {
+			result[keys[i]] = []byte(s)
+		}
+	}
+	return result, nil
+}
+
+// SetMulti stores multiple values in the cache in one round trip.
+func (c *RedisCacheService) SetMulti(ctx context.Context, items map[string][]byte, ttl time.Duration) error {
+	if len(items) == 0 {
+		return nil
+	}
+
+	pipe := c.client.Pipeline()
+	for key, value := range items {
+		pipe.Set(ctx, c.buildKey(key), value, c.ttlFor(key, ttl))
+	}
+	if _, err := pipe.Exec(ctx); err != nil {
+		return fmt.Errorf("failed to set cache: %w", err)
+	}
+	return nil
+}
+
+// Increment increments a numeric value.
+func (c *RedisCacheService) Increment(ctx context.Context, key string, delta int64) (int64, error) {
+	result, err := c.client.IncrBy(ctx, c.buildKey(key), delta).Result()
+	if err != nil {
+		return 0, fmt.Errorf("failed to increment cache value: %w", err)
+	}
+	return result, nil
+}
+
+// SetNX sets a value only if it doesn't exist (for distributed locks).
+func (c *RedisCacheService) SetNX(ctx context.Context, key string, value []byte, ttl time.Duration) (bool, error) {
+	ok, err := c.client.SetNX(ctx, c.buildKey(key), value, c.ttlFor(key, ttl)).Result()
+	if err != nil {
+		return false, fmt.Errorf("failed to set cache value: %w", err)
+	}
+	return ok, nil
+}
+
+// Ensure RedisCacheService implements ports.CacheService
+var _ ports.CacheService = (*RedisCacheService)(nil)
+
+// Cache errors
+var (
+	ErrCacheMiss  = errors.New("cache miss")
+	ErrNotInteger = errors.New("cached value is not an integer")
+)
@@ -0,0 +1,185 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/kilang-desa-murni/crm/pkg/testing/containers"
+	"github.com/kilang-desa-murni/crm/pkg/testing/helpers"
+)
+
+// newTestRedisCache connects to the test Redis and clears it, skipping the test
+// in short mode or when Redis is not reachable.
+func newTestRedisCache(t *testing.T, config RedisCacheConfig) *RedisCacheService {
+	t.Helper()
+	helpers.SkipIfShort(t)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	redis, err := containers.NewRedisContainer(ctx, containers.DefaultRedisConfig())
+	if err != nil {
+		t.Skipf("Redis not available: %v", err)
+	}
+	t.Cleanup(func() { redis.Close() })
+	if err := redis.FlushDB(ctx); err != nil {
+		t.Fatalf("failed to flush Redis: %v", err)
+	}
+	return NewRedisCacheService(redis.GetClient(), config)
+}
+
+func TestTenantKey(t *testing.T) {
+	tenantID := uuid.MustParse("5f0e7b1c-3a3d-4c8e-9a55-1c2b3d4e5f60")
+
+	tests := []struct {
+		entity string
+		parts  []string
+		want   string
+	}{
+		{"pipeline", nil, "pipeline:5f0e7b1c-3a3d-4c8e-9a55-1c2b3d4e5f60"},
+		{"deal", []string{"42"}, "deal:5f0e7b1c-3a3d-4c8e-9a55-1c2b3d4e5f60:42"},
+		{"report", []string{"forecast", "2026-Q4"}, "report:5f0e7b1c-3a3d-4c8e-9a55-1c2b3d4e5f60:forecast:2026-Q4"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.want, func(t *testing.T) {
+			if got := TenantKey(tt.entity, tenantID, tt.parts...); got != tt.want {
+				t.Errorf("TenantKey() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRedisCacheService_TTLFor(t *testing.T) {
+	cache := NewRedisCacheService(nil, RedisCacheConfig{
+		DefaultTTL: time.Minute,
+		EntityTTLs: map[string]time.Duration{"pipeline": 30 * time.Minute},
+		TTLJitter:  0.1,
+	})
+
+	tests := []struct {
+		name string
+		key  string
+		ttl  time.Duration
+		min  time.Duration
+		max  time.Duration
+	}{
+		{"explicit TTL is kept as is", "pipeline:t:1", 5 * time.Second, 5 * time.Second, 5 * time.Second},
+		{"entity TTL with jitter", "pipeline:t:1", 0, 30 * time.Minute, 33 * time.Minute},
+		{"default TTL with jitter", "lead:t:1", 0, time.Minute, 66 * time.Second},
+		{"key without an entity", "standalone", 0, time.Minute, 66 * time.Second},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for i := 0; i < 100; i++ {
+				if got := cache.ttlFor(tt.key, tt.ttl); got < tt.min || got > tt.max {
+					t.Fatalf("ttlFor(%q, %v) = %v, want within [%v, %v]", tt.key, tt.ttl, got, tt.min, tt.max)
+				}
+			}
+		})
+	}
+}
+
+func TestNewRedisCacheService_Defaults(t *testing.T) {
+	cache := NewRedisCacheService(nil, RedisCacheConfig{})
+	defaults := DefaultRedisCacheConfig()
+	if cache.config.DefaultTTL != defaults.DefaultTTL || cache.config.ScanCount != defaults.ScanCount {
+		t.Errorf("expected defaults for DefaultTTL and ScanCount, got %v and %d", cache.config.DefaultTTL, cache.config.ScanCount)
+	}
+}
+
+func TestRedisCacheService_GetSetDelete(t *testing.T) {
+	cache := newTestRedisCache(t, DefaultRedisCacheConfig())
+	ctx := context.Background()
+
+	if _, err := cache.Get(ctx, "deal:t:1"); !errors.Is(err, ErrCacheMiss) {
+		t.Fatalf("expected ErrCacheMiss, got %v", err)
+	}
+	if err := cache.Set(ctx, "deal:t:1", []byte("won"), 0); err != nil {
+		t.Fatalf("Set() error = %v", err)
+	}
+	got, err := cache.Get(ctx, "deal:t:1")
+	if err != nil || string(got) != "won" {
+		t.Fatalf("Get() = %q, %v, want won", got, err)
+	}
+	if exists, err := cache.Exists(ctx, "deal:t:1"); err != nil || !exists {
+		t.Errorf("Exists() = %v, %v, want true", exists, err)
+	}
+	if err := cache.Delete(ctx, "deal:t:1"); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	if exists, _ := cache.Exists(ctx, "deal:t:1"); exists {
+		t.Error("expected the key to be deleted")
+	}
+}
+
+func TestRedisCacheService_DeletePattern(t *testing.T) {
+	// A small scan batch exercises the batched unlink
+	cache := newTestRedisCache(t, RedisCacheConfig{KeyPrefix: "sales:", ScanCount: 2})
+	ctx := context.Background()
+	tenantA, tenantB := uuid.New(), uuid.New()
+
+	keys := []string{
+		TenantKey("deal", tenantA, "1"),
+		TenantKey("deal", tenantA, "2"),
+		TenantKey("deal", tenantA, "3"),
+		TenantKey("lead", tenantA, "1"),
+		TenantKey("deal", tenantB, "1"),
+	}
+	for _, key := range keys {
+		if err := cache.Set(ctx, key, []byte("v"), time.Minute); err != nil {
+			t.Fatalf("Set() error = %v", err)
+		}
+	}
+
+	if err := cache.DeletePattern(ctx, "deal:"+tenantA.String()+":*"); err != nil {
+		t.Fatalf("DeletePattern() error = %v", err)
+	}
+	remaining, err := cache.GetMulti(ctx, keys)
+	if err != nil {
+		t.Fatalf("GetMulti() error = %v", err)
+	}
+	var got []string
+	for key := range remaining {
+		got = append(got, key)
+	}
+	sort.Strings(got)
+	want := []string{TenantKey("deal", tenantB, "1"), TenantKey("lead", tenantA, "1")}
+	sort.Strings(want)
+	if len(got) != len(want) || got[0] != want[0] || got[1] != want[1] {
+		t.Errorf("remaining keys = %v, want %v", got, want)
+	}
+
+	if err := cache.InvalidateTenant(ctx, tenantA); err != nil {
+		t.Fatalf("InvalidateTenant() error = %v", err)
+	}
+	if exists, _ := cache.Exists(ctx, TenantKey("lead", tenantA, "1")); exists {
+		t.Error("expected every entry of the tenant to be invalidated")
+	}
+	if exists, _ := cache.Exists(ctx, TenantKey("deal", tenantB, "1")); !exists {
+		t.Error("expected other tenants to be kept")
+	}
+}
+
+func TestRedisCacheService_IncrementAndSetNX(t *testing.T) {
+	cache := newTestRedisCache(t, DefaultRedisCacheConfig())
+	ctx := context.Background()
+
+	for want := int64(1); want <= 3; want++ {
+		if got, err := cache.Increment(ctx, "counter:t", 1); err != nil || got != want {
+			t.Fatalf("Increment() = %d, %v, want %d", got, err, want)
+		}
+	}
+
+	if ok, err := cache.SetNX(ctx, "lock:t:deal", []byte("a"), time.Minute); err != nil || !ok {
+		t.Fatalf("SetNX() = %v, %v, want true", ok, err)
+	}
+	if ok, err := cache.SetNX(ctx, "lock:t:deal", []byte("b"), time.Minute); err != nil || ok {
+		t.Errorf("SetNX() on a held key = %v, %v, want false", ok, err)
+	}
+}
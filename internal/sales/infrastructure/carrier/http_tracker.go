@@ -0,0 +1,120 @@
+// Package carrier adapts carrier tracking APIs and webhooks to the sales service.
+package carrier
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/kilang-desa-murni/crm/internal/sales/application/ports"
+)
+
+// ============================================================================
+// HTTP Tracker
+// ============================================================================
+
+// HTTPTrackerConfig holds configuration for a tracking API.
+type HTTPTrackerConfig struct {
+	// URL is the tracking endpoint. The carrier and tracking number are sent
+	// as the carrier and tracking_number query parameters.
+	URL     string
+	APIKey  string
+	Timeout time.Duration
+}
+
+// HTTPTracker polls a tracking API, such as a tracking aggregator or a
+// logistics partner's gateway, that answers in the normalized update format:
+//
+//	{"updates": [{"tracking_number": "...", "status": "in_transit", "occurred_at": "..."}]}
+type HTTPTracker struct {
+	carrier    string
+	config     HTTPTrackerConfig
+	httpClient *http.Client
+}
+
+// NewHTTPTracker creates a tracker for a carrier.
+func NewHTTPTracker(carrier string, config HTTPTrackerConfig) *HTTPTracker {
+	if config.Timeout <= 0 {
+		config.Timeout = 15 * time.Second
+	}
+	return &HTTPTracker{
+		carrier: carrier,
+		config:  config,
+		httpClient: &http.Client{
+			Timeout: config.Timeout,
+		},
+	}
+}
+
+// trackingResponse is the normalized tracking update format.
+type trackingResponse struct {
+	Updates []ports.CarrierTrackingUpdate `json:"updates"`
+}
+
+// Carrier returns the carrier code the tracker serves.
+func (t *HTTPTracker) Carrier() string {
+	return t.carrier
+}
+
+// Track returns the tracking updates the API has for a tracking number.
+func (t *HTTPTracker) Track(ctx context.Context, trackingNumber string) ([]ports.CarrierTrackingUpdate, error) {
+	endpoint, err := url.Parse(t.config.URL)
+	if err != nil {
+		return nil, fmt.Errorf("%s: invalid tracking URL: %w", t.carrier, err)
+	}
+	query := endpoint.Query()
+	query.Set("carrier", t.carrier)
+	query.Set("tracking_number", trackingNumber)
+	endpoint.RawQuery = query.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint.String(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to create request: %w", t.carrier, err)
+	}
+	req.Header.Set("Accept", "application/json")
+	if t.config.APIKey != "" {
+		req.Header.Set("Authorization", "Bearer "+t.config.APIKey)
+	}
+
+	resp, err := t.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("%s: request failed: %w", t.carrier, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		// Carriers only know a tracking number once the parcel is booked in
+		return nil, nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%s: unexpected status %d", t.carrier, resp.StatusCode)
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to read response: %w", t.carrier, err)
+	}
+
+	var response trackingResponse
+	if err := json.Unmarshal(body, &response); err != nil {
+		return nil, fmt.Errorf("%s: failed to parse tracking updates: %w", t.carrier, err)
+	}
+
+	updates := response.Updates[:0]
+	for _, update := range response.Updates {
+		if update.TrackingNumber == "" {
+			update.TrackingNumber = trackingNumber
+		}
+		if update.TrackingNumber == trackingNumber {
+			updates = append(updates, update)
+		}
+	}
+	return updates, nil
+}
+
+// Ensure HTTPTracker implements ports.CarrierTracker
+var _ ports.CarrierTracker = (*HTTPTracker)(nil)
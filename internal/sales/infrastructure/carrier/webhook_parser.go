@@ -0,0 +1,70 @@
+package carrier
+
+import (
+	"context"
+	"crypto/hmac"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/kilang-desa-murni/crm/internal/sales/application/ports"
+	"github.com/kilang-desa-murni/crm/internal/sales/infrastructure/webhook"
+)
+
+// ============================================================================
+// Signed Webhook Parser
+// ============================================================================
+
+// maxWebhookAge is how old a signed webhook may be, to stop replays.
+const maxWebhookAge = 5 * time.Minute
+
+// SignedWebhookParser reads carrier updates posted in the normalized update
+// format and signed the way the service signs its own webhooks: the
+// X-Webhook-Signature header is "sha256=" and the hex HMAC-SHA256 of
+// "<X-Webhook-Timestamp>.<body>" with the shared secret.
+type SignedWebhookParser struct {
+	carrier string
+	secret  string
+}
+
+// NewSignedWebhookParser creates a webhook parser for a carrier.
+func NewSignedWebhookParser(carrier, secret string) *SignedWebhookParser {
+	return &SignedWebhookParser{
+		carrier: carrier,
+		secret:  secret,
+	}
+}
+
+// Carrier returns the carrier code the parser serves.
+func (p *SignedWebhookParser) Carrier() string {
+	return p.carrier
+}
+
+// ParseWebhook verifies the request signature and returns its updates.
+func (p *SignedWebhookParser) ParseWebhook(ctx context.Context, headers map[string]string, body []byte) ([]ports.CarrierTrackingUpdate, error) {
+	timestamp := headers[webhook.TimestampHeader]
+	signedAt, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return nil, ports.ErrInvalidCarrierWebhook
+	}
+	if age := time.Since(time.Unix(signedAt, 0)); age > maxWebhookAge || age < -maxWebhookAge {
+		return nil, ports.ErrInvalidCarrierWebhook
+	}
+
+	signature := strings.TrimPrefix(headers[webhook.SignatureHeader], "sha256=")
+	expected := webhook.Sign(p.secret, timestamp, body)
+	if p.secret == "" || !hmac.Equal([]byte(signature), []byte(expected)) {
+		return nil, ports.ErrInvalidCarrierWebhook
+	}
+
+	var payload trackingResponse
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return nil, fmt.Errorf("%s: failed to parse tracking updates: %w", p.carrier, err)
+	}
+	return payload.Updates, nil
+}
+
+// Ensure SignedWebhookParser implements ports.CarrierWebhookParser
+var _ ports.CarrierWebhookParser = (*SignedWebhookParser)(nil)
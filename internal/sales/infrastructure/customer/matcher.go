@@ -0,0 +1,115 @@
+// Package customer provides clients of the customer service for the sales service.
+package customer
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/kilang-desa-murni/crm/internal/sales/application/ports"
+)
+
+// ============================================================================
+// Customer Matcher
+// ============================================================================
+
+// MatcherConfig holds configuration for the customer service matcher.
+type MatcherConfig struct {
+	// BaseURL is the customer service URL, serving its internal routes.
+	BaseURL string
+	Timeout time.Duration
+	// Transport sends the requests, authenticating them with a service
+	// token; http.DefaultTransport when nil.
+	Transport http.RoundTripper
+}
+
+// DefaultMatcherConfig returns default matcher configuration for the
+// customer service at baseURL. Lead creation waits for the lookup, so it
+// gives up quickly.
+func DefaultMatcherConfig(baseURL string) MatcherConfig {
+	return MatcherConfig{
+		BaseURL: baseURL,
+		Timeout: 3 * time.Second,
+	}
+}
+
+// HTTPMatcher implements CustomerMatcher with the customer service's internal
+// customer matches route.
+type HTTPMatcher struct {
+	config     MatcherConfig
+	httpClient *http.Client
+}
+
+// NewHTTPMatcher creates a new customer service matcher.
+func NewHTTPMatcher(config MatcherConfig) *HTTPMatcher {
+	return &HTTPMatcher{
+		config: config,
+		httpClient: &http.Client{
+			Timeout:   config.Timeout,
+			Transport: config.Transport,
+		},
+	}
+}
+
+// matchesResponse is the customer service response envelope.
+type matchesResponse struct {
+	Data []*ports.CustomerMatch `json:"data"`
+}
+
+// FindMatchingCustomers returns the customers with the email address, one of
+// the phone numbers or the company name.
+func (m *HTTPMatcher) FindMatchingCustomers(ctx context.Context, tenantID uuid.UUID, email string, phones []string, companyName string) ([]*ports.CustomerMatch, error) {
+	query := url.Values{}
+	if email != "" {
+		query.Set("email", email)
+	}
+	for _, phone := range phones {
+		if phone != "" {
+			query.Add("phone", phone)
+		}
+	}
+	if companyName != "" {
+		query.Set("name", companyName)
+	}
+	if len(query) == 0 {
+		return nil, nil
+	}
+
+	endpoint := fmt.Sprintf("%s/internal/tenants/%s/customers/matches?%s",
+		strings.TrimRight(m.config.BaseURL, "/"), tenantID, query.Encode())
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := m.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("customer matches request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read customer matches: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("customer matches request failed: status %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+
+	var response matchesResponse
+	if err := json.Unmarshal(body, &response); err != nil {
+		return nil, fmt.Errorf("failed to parse customer matches: %w", err)
+	}
+	return response.Data, nil
+}
+
+// Ensure HTTPMatcher implements CustomerMatcher
+var _ ports.CustomerMatcher = (*HTTPMatcher)(nil)
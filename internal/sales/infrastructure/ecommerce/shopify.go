@@ -0,0 +1,316 @@
+package ecommerce
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/kilang-desa-murni/crm/internal/sales/application/ports"
+)
+
+// ============================================================================
+// Shopify Client
+// ============================================================================
+
+// shopifyAPIVersion is the Shopify Admin REST API version called.
+const shopifyAPIVersion = "2024-01"
+
+// ShopifyClient reads orders and abandoned checkouts from Shopify stores
+// through the Admin REST API, authenticated with an Admin API access token,
+// and verifies the webhooks of the store's app.
+type ShopifyClient struct {
+	httpClient *http.Client
+}
+
+// NewShopifyClient creates a Shopify client.
+func NewShopifyClient(timeout time.Duration) *ShopifyClient {
+	if timeout <= 0 {
+		timeout = defaultTimeout
+	}
+	return &ShopifyClient{
+		httpClient: &http.Client{
+			Timeout: timeout,
+		},
+	}
+}
+
+// Platform returns "shopify".
+func (c *ShopifyClient) Platform() string {
+	return "shopify"
+}
+
+// FetchOrders returns the store's orders updated since the time, oldest
+// update first.
+func (c *ShopifyClient) FetchOrders(ctx context.Context, store ports.EcommerceStoreAccess, since time.Time) ([]ports.EcommerceOrder, error) {
+	query := url.Values{}
+	query.Set("status", "any")
+	query.Set("updated_at_min", since.UTC().Format(time.RFC3339))
+	query.Set("limit", "250")
+
+	var orders []ports.EcommerceOrder
+	next := c.endpoint(store, "orders.json") + "?" + query.Encode()
+	for page := 0; next != "" && page < maxPages; page++ {
+		var response struct {
+			Orders []shopifyOrder `json:"orders"`
+		}
+		headers, err := getJSON(ctx, c.httpClient, c.Platform(), next, c.authorize(store), &response)
+		if err != nil {
+			return nil, err
+		}
+		for _, order := range response.Orders {
+			orders = append(orders, order.toPort())
+		}
+		next = nextPageLink(headers.Get("Link"))
+	}
+
+	sort.SliceStable(orders, func(i, j int) bool {
+		return orders[i].UpdatedAt.Before(orders[j].UpdatedAt)
+	})
+	return orders, nil
+}
+
+// FetchAbandonedCarts returns the store's abandoned checkouts updated since
+// the time.
+func (c *ShopifyClient) FetchAbandonedCarts(ctx context.Context, store ports.EcommerceStoreAccess, since time.Time) ([]ports.EcommerceCart, error) {
+	query := url.Values{}
+	query.Set("updated_at_min", since.UTC().Format(time.RFC3339))
+	query.Set("limit", "250")
+
+	var carts []ports.EcommerceCart
+	next := c.endpoint(store, "checkouts.json") + "?" + query.Encode()
+	for page := 0; next != "" && page < maxPages; page++ {
+		var response struct {
+			Checkouts []shopifyCheckout `json:"checkouts"`
+		}
+		headers, err := getJSON(ctx, c.httpClient, c.Platform(), next, c.authorize(store), &response)
+		if err != nil {
+			return nil, err
+		}
+		for _, checkout := range response.Checkouts {
+			carts = append(carts, checkout.toPort())
+		}
+		next = nextPageLink(headers.Get("Link"))
+	}
+	return carts, nil
+}
+
+// ParseWebhook verifies the X-Shopify-Hmac-Sha256 header, the base64
+// HMAC-SHA256 of the body with the app's client secret, and returns the
+// order or checkout the webhook carries. Webhooks of other topics carry
+// nothing.
+func (c *ShopifyClient) ParseWebhook(ctx context.Context, store ports.EcommerceStoreAccess, headers map[string]string, body []byte) (*ports.EcommerceWebhook, error) {
+	if !validSignature(store.WebhookSecret, headers["X-Shopify-Hmac-Sha256"], body) {
+		return nil, ports.ErrInvalidEcommerceWebhook
+	}
+
+	topic := headers["X-Shopify-Topic"]
+	switch {
+	case strings.HasPrefix(topic, "orders/"):
+		var order shopifyOrder
+		if err := json.Unmarshal(body, &order); err != nil {
+			return nil, fmt.Errorf("shopify: failed to parse order: %w", err)
+		}
+		return &ports.EcommerceWebhook{Orders: []ports.EcommerceOrder{order.toPort()}}, nil
+	case strings.HasPrefix(topic, "checkouts/"):
+		var checkout shopifyCheckout
+		if err := json.Unmarshal(body, &checkout); err != nil {
+			return nil, fmt.Errorf("shopify: failed to parse checkout: %w", err)
+		}
+		return &ports.EcommerceWebhook{Carts: []ports.EcommerceCart{checkout.toPort()}}, nil
+	}
+	return &ports.EcommerceWebhook{}, nil
+}
+
+func (c *ShopifyClient) endpoint(store ports.EcommerceStoreAccess, resource string) string {
+	return strings.TrimRight(store.StoreURL, "/") + "/admin/api/" + shopifyAPIVersion + "/" + resource
+}
+
+func (c *ShopifyClient) authorize(store ports.EcommerceStoreAccess) func(*http.Request) {
+	return func(req *http.Request) {
+		req.Header.Set("X-Shopify-Access-Token", store.APIKey)
+	}
+}
+
+// nextPageLink returns the rel="next" URL of a Link header, which Shopify
+// paginates with.
+func nextPageLink(header string) string {
+	for _, link := range strings.Split(header, ",") {
+		parts := strings.Split(link, ";")
+		if len(parts) < 2 || !strings.Contains(parts[1], `rel="next"`) {
+			continue
+		}
+		return strings.Trim(strings.TrimSpace(parts[0]), "<>")
+	}
+	return ""
+}
+
+// ============================================================================
+// Shopify Payloads
+// ============================================================================
+
+type shopifyCustomer struct {
+	Email     string `json:"email"`
+	Phone     string `json:"phone"`
+	FirstName string `json:"first_name"`
+	LastName  string `json:"last_name"`
+}
+
+type shopifyAddress struct {
+	FirstName   string `json:"first_name"`
+	LastName    string `json:"last_name"`
+	Company     string `json:"company"`
+	Address1    string `json:"address1"`
+	City        string `json:"city"`
+	Province    string `json:"province"`
+	Zip         string `json:"zip"`
+	CountryCode string `json:"country_code"`
+	Phone       string `json:"phone"`
+}
+
+type shopifyLineItem struct {
+	SKU           string `json:"sku"`
+	Title         string `json:"title"`
+	Name          string `json:"name"`
+	Quantity      int    `json:"quantity"`
+	Price         string `json:"price"`
+	TotalDiscount string `json:"total_discount"`
+}
+
+type shopifyOrder struct {
+	ID              int64             `json:"id"`
+	Name            string            `json:"name"`
+	Email           string            `json:"email"`
+	Phone           string            `json:"phone"`
+	Currency        string            `json:"currency"`
+	TotalPrice      string            `json:"total_price"`
+	FinancialStatus string            `json:"financial_status"`
+	CancelledAt     *time.Time        `json:"cancelled_at"`
+	CreatedAt       time.Time         `json:"created_at"`
+	UpdatedAt       time.Time         `json:"updated_at"`
+	Customer        *shopifyCustomer  `json:"customer"`
+	BillingAddress  *shopifyAddress   `json:"billing_address"`
+	LineItems       []shopifyLineItem `json:"line_items"`
+	ShippingLines   []struct {
+		Price string `json:"price"`
+	} `json:"shipping_lines"`
+}
+
+type shopifyCheckout struct {
+	ID                   int64             `json:"id"`
+	Token                string            `json:"token"`
+	Email                string            `json:"email"`
+	Phone                string            `json:"phone"`
+	Currency             string            `json:"currency"`
+	TotalPrice           string            `json:"total_price"`
+	AbandonedCheckoutURL string            `json:"abandoned_checkout_url"`
+	CompletedAt          *time.Time        `json:"completed_at"`
+	UpdatedAt            time.Time         `json:"updated_at"`
+	Customer             *shopifyCustomer  `json:"customer"`
+	BillingAddress       *shopifyAddress   `json:"billing_address"`
+	LineItems            []shopifyLineItem `json:"line_items"`
+}
+
+func (o shopifyOrder) toPort() ports.EcommerceOrder {
+	order := ports.EcommerceOrder{
+		ExternalID: strconv.FormatInt(o.ID, 10),
+		Number:     o.Name,
+		Customer:   shopifyShopper(o.Email, o.Phone, o.Customer, o.BillingAddress),
+		Currency:   o.Currency,
+		Lines:      shopifyLines(o.LineItems, o.Currency),
+		Total:      minorUnits(o.TotalPrice, o.Currency),
+		CreatedAt:  o.CreatedAt,
+		UpdatedAt:  o.UpdatedAt,
+	}
+	for _, line := range o.ShippingLines {
+		order.Shipping += minorUnits(line.Price, o.Currency)
+	}
+	switch o.FinancialStatus {
+	case "paid", "partially_refunded":
+		order.Paid = true
+	case "refunded", "voided":
+		order.Cancelled = true
+	}
+	if o.CancelledAt != nil {
+		order.Cancelled = true
+	}
+	return order
+}
+
+func (c shopifyCheckout) toPort() ports.EcommerceCart {
+	externalID := c.Token
+	if externalID == "" {
+		externalID = strconv.FormatInt(c.ID, 10)
+	}
+	return ports.EcommerceCart{
+		ExternalID:  externalID,
+		Customer:    shopifyShopper(c.Email, c.Phone, c.Customer, c.BillingAddress),
+		Currency:    c.Currency,
+		Lines:       shopifyLines(c.LineItems, c.Currency),
+		Total:       minorUnits(c.TotalPrice, c.Currency),
+		RecoveryURL: c.AbandonedCheckoutURL,
+		Completed:   c.CompletedAt != nil,
+		UpdatedAt:   c.UpdatedAt,
+	}
+}
+
+func shopifyShopper(email, phone string, customer *shopifyCustomer, address *shopifyAddress) ports.EcommerceCustomer {
+	shopper := ports.EcommerceCustomer{Email: email, Phone: phone}
+	if customer != nil {
+		if shopper.Email == "" {
+			shopper.Email = customer.Email
+		}
+		if shopper.Phone == "" {
+			shopper.Phone = customer.Phone
+		}
+		shopper.FirstName = customer.FirstName
+		shopper.LastName = customer.LastName
+	}
+	if address != nil {
+		if shopper.FirstName == "" && shopper.LastName == "" {
+			shopper.FirstName = address.FirstName
+			shopper.LastName = address.LastName
+		}
+		if shopper.Phone == "" {
+			shopper.Phone = address.Phone
+		}
+		shopper.Company = address.Company
+		shopper.Street1 = address.Address1
+		shopper.City = address.City
+		shopper.State = address.Province
+		shopper.Postcode = address.Zip
+		shopper.Country = address.CountryCode
+	}
+	return shopper
+}
+
+// shopifyLines converts line items, spreading each line's discount over its
+// unit price.
+func shopifyLines(items []shopifyLineItem, currency string) []ports.EcommerceLine {
+	lines := make([]ports.EcommerceLine, 0, len(items))
+	for _, item := range items {
+		name := item.Name
+		if name == "" {
+			name = item.Title
+		}
+		unitPrice := minorUnits(item.Price, currency)
+		if discount := minorUnits(item.TotalDiscount, currency); discount > 0 && item.Quantity > 0 {
+			unitPrice -= discount / int64(item.Quantity)
+		}
+		lines = append(lines, ports.EcommerceLine{
+			SKU:       item.SKU,
+			Name:      name,
+			Quantity:  item.Quantity,
+			UnitPrice: unitPrice,
+		})
+	}
+	return lines
+}
+
+// Ensure ShopifyClient implements ports.EcommerceClient
+var _ ports.EcommerceClient = (*ShopifyClient)(nil)
@@ -0,0 +1,221 @@
+// Package email contains inbound email adapters for the Sales Pipeline service.
+package email
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"html"
+	"io"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/mail"
+	"regexp"
+	"strings"
+
+	"github.com/kilang-desa-murni/crm/internal/sales/application/ports"
+)
+
+// maxMIMEDepth bounds how deeply nested multipart bodies are walked.
+const maxMIMEDepth = 5
+
+// keptHeaders are the headers the domain inspects to recognise automated mail.
+var keptHeaders = []string{
+	"Return-Path",
+	"Auto-Submitted",
+	"Precedence",
+	"X-Loop",
+	"X-Autoreply",
+	"X-Autorespond",
+	"List-Id",
+}
+
+// recipientHeaders are the headers the delivery address may appear in. Relays
+// add Delivered-To or X-Original-To when the mailbox was Bcc'd or forwarded to.
+var recipientHeaders = []string{"To", "Cc", "Delivered-To", "X-Original-To"}
+
+var (
+	htmlBlockPattern = regexp.MustCompile(`(?is)<(script|style)[^>]*>.*?</(script|style)>`)
+	htmlBreakPattern = regexp.MustCompile(`(?i)<(br|/p|/div|/tr|/li|/h[1-6])[^>]*>`)
+	htmlTagPattern   = regexp.MustCompile(`<[^>]*>`)
+	blankLinePattern = regexp.MustCompile(`\n{3,}`)
+)
+
+// MIMEParser implements ports.EmailParser with the standard library.
+type MIMEParser struct {
+	decoder *mime.WordDecoder
+}
+
+// NewMIMEParser creates a new MIME email parser.
+func NewMIMEParser() *MIMEParser {
+	return &MIMEParser{decoder: new(mime.WordDecoder)}
+}
+
+// Parse parses a raw RFC 5322 message. The plain text body is preferred; an
+// HTML-only body is reduced to text.
+func (p *MIMEParser) Parse(raw []byte) (*ports.ParsedEmail, error) {
+	msg, err := mail.ReadMessage(bytes.NewReader(raw))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read message: %w", err)
+	}
+
+	parsed := &ports.ParsedEmail{
+		MessageID: strings.TrimSpace(msg.Header.Get("Message-Id")),
+		Subject:   p.decodeHeader(msg.Header.Get("Subject")),
+		Headers:   make(map[string]string),
+	}
+	if parsed.MessageID == "" {
+		// Without a Message-ID the content is the only stable identity, so
+		// a relay retrying the same message is still recognised as a duplicate
+		sum := sha256.Sum256(raw)
+		parsed.MessageID = "<" + hex.EncodeToString(sum[:16]) + "@generated>"
+	}
+	if date, err := msg.Header.Date(); err == nil {
+		parsed.Date = date
+	}
+
+	if from := msg.Header.Get("From"); from != "" {
+		addr, err := p.parseAddress(from)
+		if err != nil {
+			return nil, fmt.Errorf("invalid From header: %w", err)
+		}
+		parsed.FromAddress, parsed.FromName = addr.Address, addr.Name
+	}
+
+	for _, header := range recipientHeaders {
+		for _, value := range msg.Header[header] {
+			addrs, err := (&mail.AddressParser{WordDecoder: p.decoder}).ParseList(value)
+			if err != nil {
+				continue
+			}
+			for _, addr := range addrs {
+				parsed.Recipients = append(parsed.Recipients, addr.Address)
+			}
+		}
+	}
+
+	for _, header := range keptHeaders {
+		if value := msg.Header.Get(header); value != "" {
+			parsed.Headers[header] = value
+		}
+	}
+
+	var plain, htmlBody string
+	if err := p.walk(msg.Header, msg.Body, 0, &plain, &htmlBody, &parsed.Attachments); err != nil {
+		return nil, err
+	}
+	parsed.Body = strings.TrimSpace(plain)
+	if parsed.Body == "" && htmlBody != "" {
+		parsed.Body = htmlToText(htmlBody)
+	}
+	return parsed, nil
+}
+
+// partHeader is satisfied by both message and multipart part headers.
+type partHeader interface {
+	Get(key string) string
+}
+
+// walk decodes one MIME entity, recursing into multipart bodies. The first
+// text/plain and text/html parts become the body; parts with a filename or an
+// attachment disposition become attachments.
+func (p *MIMEParser) walk(header partHeader, body io.Reader, depth int, plain, htmlBody *string, attachments *[]ports.EmailAttachment) error {
+	mediaType, params, err := mime.ParseMediaType(header.Get("Content-Type"))
+	if err != nil {
+		mediaType, params = "text/plain", map[string]string{}
+	}
+
+	if strings.HasPrefix(mediaType, "multipart/") {
+		if depth >= maxMIMEDepth || params["boundary"] == "" {
+			return nil
+		}
+		reader := multipart.NewReader(body, params["boundary"])
+		for {
+			part, err := reader.NextPart()
+			if err == io.EOF {
+				return nil
+			}
+			if err != nil {
+				return fmt.Errorf("failed to read multipart body: %w", err)
+			}
+			if err := p.walk(part.Header, part, depth+1, plain, htmlBody, attachments); err != nil {
+				return err
+			}
+		}
+	}
+
+	content, err := io.ReadAll(decodeTransfer(header.Get("Content-Transfer-Encoding"), body))
+	if err != nil {
+		return fmt.Errorf("failed to decode body: %w", err)
+	}
+
+	disposition, dispParams, _ := mime.ParseMediaType(header.Get("Content-Disposition"))
+	filename := p.decodeHeader(dispParams["filename"])
+	if filename == "" {
+		filename = p.decodeHeader(params["name"])
+	}
+	if disposition == "attachment" || filename != "" {
+		if filename == "" {
+			filename = "attachment"
+		}
+		*attachments = append(*attachments, ports.EmailAttachment{
+			Filename:    filename,
+			ContentType: mediaType,
+			Content:     content,
+		})
+		return nil
+	}
+
+	switch mediaType {
+	case "text/plain":
+		if *plain == "" {
+			*plain = string(content)
+		}
+	case "text/html":
+		if *htmlBody == "" {
+			*htmlBody = string(content)
+		}
+	}
+	return nil
+}
+
+func (p *MIMEParser) decodeHeader(value string) string {
+	decoded, err := p.decoder.DecodeHeader(value)
+	if err != nil {
+		return value
+	}
+	return strings.TrimSpace(decoded)
+}
+
+func (p *MIMEParser) parseAddress(value string) (*mail.Address, error) {
+	return (&mail.AddressParser{WordDecoder: p.decoder}).Parse(value)
+}
+
+// decodeTransfer undoes the part's Content-Transfer-Encoding.
+func decodeTransfer(encoding string, body io.Reader) io.Reader {
+	switch strings.ToLower(strings.TrimSpace(encoding)) {
+	case "base64":
+		return base64.NewDecoder(base64.StdEncoding, body)
+	case "quoted-printable":
+		return quotedprintable.NewReader(body)
+	default:
+		return body
+	}
+}
+
+// htmlToText reduces an HTML body to readable text.
+func htmlToText(body string) string {
+	text := htmlBlockPattern.ReplaceAllString(body, "")
+	text = htmlBreakPattern.ReplaceAllString(text, "\n")
+	text = htmlTagPattern.ReplaceAllString(text, "")
+	text = html.UnescapeString(text)
+
+	lines := strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimSpace(line)
+	}
+	return strings.TrimSpace(blankLinePattern.ReplaceAllString(strings.Join(lines, "\n"), "\n\n"))
+}
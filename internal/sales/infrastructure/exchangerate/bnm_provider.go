@@ -0,0 +1,122 @@
+package exchangerate
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/kilang-desa-murni/crm/internal/sales/application/ports"
+)
+
+// ============================================================================
+// Bank Negara Malaysia Provider
+// ============================================================================
+
+// BNMConfig holds configuration for the Bank Negara Malaysia exchange rate API.
+type BNMConfig struct {
+	URL     string
+	Timeout time.Duration
+}
+
+// DefaultBNMConfig returns default BNM configuration.
+func DefaultBNMConfig() BNMConfig {
+	return BNMConfig{
+		URL:     "https://api.bnm.gov.my/public/exchange-rate",
+		Timeout: 15 * time.Second,
+	}
+}
+
+// BNMProvider fetches the ringgit exchange rates published by Bank Negara Malaysia.
+type BNMProvider struct {
+	config     BNMConfig
+	httpClient *http.Client
+}
+
+// NewBNMProvider creates a new BNM exchange rate provider.
+func NewBNMProvider(config BNMConfig) *BNMProvider {
+	return &BNMProvider{
+		config: config,
+		httpClient: &http.Client{
+			Timeout: config.Timeout,
+		},
+	}
+}
+
+// bnmResponse is the BNM exchange rate API response.
+type bnmResponse struct {
+	Data []struct {
+		CurrencyCode string  `json:"currency_code"`
+		Unit         float64 `json:"unit"`
+		Rate         struct {
+			Date        string   `json:"date"`
+			BuyingRate  *float64 `json:"buying_rate"`
+			SellingRate *float64 `json:"selling_rate"`
+			MiddleRate  *float64 `json:"middle_rate"`
+		} `json:"rate"`
+	} `json:"data"`
+}
+
+// Source returns the rate source identifier.
+func (p *BNMProvider) Source() string {
+	return "bnm"
+}
+
+// FetchRates returns the latest MYR rates. BNM quotes the ringgit price of
+// a number of foreign currency units, which is normalized to a single unit.
+func (p *BNMProvider) FetchRates(ctx context.Context) ([]ports.ExchangeRateQuote, error) {
+	body, err := fetch(ctx, p.httpClient, p.config.URL, "application/vnd.BNM.API.v1+json")
+	if err != nil {
+		return nil, fmt.Errorf("bnm: %w", err)
+	}
+
+	var response bnmResponse
+	if err := json.Unmarshal(body, &response); err != nil {
+		return nil, fmt.Errorf("bnm: failed to parse rates: %w", err)
+	}
+	if len(response.Data) == 0 {
+		return nil, fmt.Errorf("bnm: response contains no rates")
+	}
+
+	quotes := make([]ports.ExchangeRateQuote, 0, len(response.Data))
+	for _, d := range response.Data {
+		rate := bnmRate(d.Rate.MiddleRate, d.Rate.BuyingRate, d.Rate.SellingRate)
+		if rate <= 0 {
+			continue
+		}
+		effectiveDate, err := time.Parse("2006-01-02", d.Rate.Date)
+		if err != nil {
+			continue
+		}
+
+		unit := d.Unit
+		if unit <= 0 {
+			unit = 1
+		}
+
+		quotes = append(quotes, ports.ExchangeRateQuote{
+			From:          strings.ToUpper(d.CurrencyCode),
+			To:            "MYR",
+			Rate:          rate / unit,
+			EffectiveDate: effectiveDate,
+		})
+	}
+
+	return quotes, nil
+}
+
+// bnmRate prefers the middle rate, falling back to the mean of the buying and selling rates.
+func bnmRate(middle, buying, selling *float64) float64 {
+	if middle != nil && *middle > 0 {
+		return *middle
+	}
+	if buying != nil && selling != nil && *buying > 0 && *selling > 0 {
+		return (*buying + *selling) / 2
+	}
+	return 0
+}
+
+// Ensure BNMProvider implements ports.ExchangeRateProvider
+var _ ports.ExchangeRateProvider = (*BNMProvider)(nil)
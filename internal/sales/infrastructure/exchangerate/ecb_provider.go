@@ -0,0 +1,134 @@
+// Package exchangerate contains exchange rate providers for the Sales Pipeline service.
+package exchangerate
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/kilang-desa-murni/crm/internal/sales/application/ports"
+)
+
+// ============================================================================
+// European Central Bank Provider
+// ============================================================================
+
+// ECBConfig holds configuration for the European Central Bank reference rates.
+type ECBConfig struct {
+	URL     string
+	Timeout time.Duration
+}
+
+// DefaultECBConfig returns default ECB configuration.
+func DefaultECBConfig() ECBConfig {
+	return ECBConfig{
+		URL:     "https://www.ecb.europa.eu/stats/eurofxref/eurofxref-daily.xml",
+		Timeout: 15 * time.Second,
+	}
+}
+
+// ECBProvider fetches the daily euro foreign exchange reference rates.
+type ECBProvider struct {
+	config     ECBConfig
+	httpClient *http.Client
+}
+
+// NewECBProvider creates a new ECB exchange rate provider.
+func NewECBProvider(config ECBConfig) *ECBProvider {
+	return &ECBProvider{
+		config: config,
+		httpClient: &http.Client{
+			Timeout: config.Timeout,
+		},
+	}
+}
+
+// ecbEnvelope is the ECB daily reference rate document.
+type ecbEnvelope struct {
+	Days []struct {
+		Time  string `xml:"time,attr"`
+		Rates []struct {
+			Currency string `xml:"currency,attr"`
+			Rate     string `xml:"rate,attr"`
+		} `xml:"Cube"`
+	} `xml:"Cube>Cube"`
+}
+
+// Source returns the rate source identifier.
+func (p *ECBProvider) Source() string {
+	return "ecb"
+}
+
+// FetchRates returns the latest EUR reference rates.
+func (p *ECBProvider) FetchRates(ctx context.Context) ([]ports.ExchangeRateQuote, error) {
+	body, err := fetch(ctx, p.httpClient, p.config.URL, "application/xml")
+	if err != nil {
+		return nil, fmt.Errorf("ecb: %w", err)
+	}
+
+	var envelope ecbEnvelope
+	if err := xml.Unmarshal(body, &envelope); err != nil {
+		return nil, fmt.Errorf("ecb: failed to parse rates: %w", err)
+	}
+	if len(envelope.Days) == 0 {
+		return nil, fmt.Errorf("ecb: response contains no rates")
+	}
+
+	// The daily feed has a single day; the historical feeds list the newest first
+	day := envelope.Days[0]
+	effectiveDate, err := time.Parse("2006-01-02", day.Time)
+	if err != nil {
+		return nil, fmt.Errorf("ecb: invalid rate date %q: %w", day.Time, err)
+	}
+
+	quotes := make([]ports.ExchangeRateQuote, 0, len(day.Rates))
+	for _, r := range day.Rates {
+		rate, err := strconv.ParseFloat(strings.TrimSpace(r.Rate), 64)
+		if err != nil || rate <= 0 {
+			continue
+		}
+		quotes = append(quotes, ports.ExchangeRateQuote{
+			From:          "EUR",
+			To:            strings.ToUpper(r.Currency),
+			Rate:          rate,
+			EffectiveDate: effectiveDate,
+		})
+	}
+
+	return quotes, nil
+}
+
+// fetch performs a GET request and returns the response body.
+func fetch(ctx context.Context, client *http.Client, url, accept string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Accept", accept)
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	// Rate documents are small; anything larger is not a rate document
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+
+	return body, nil
+}
+
+// Ensure ECBProvider implements ports.ExchangeRateProvider
+var _ ports.ExchangeRateProvider = (*ECBProvider)(nil)
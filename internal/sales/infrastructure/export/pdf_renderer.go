@@ -0,0 +1,558 @@
+package export
+
+import (
+	"bytes"
+	"compress/zlib"
+	"context"
+	"fmt"
+	"image"
+	"image/color"
+	_ "image/jpeg" // register JPEG decoding for logo dimensions
+	"io"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/kilang-desa-murni/crm/internal/sales/application/ports"
+)
+
+// A4 portrait page geometry in points.
+const (
+	pdfPageWidth  = 595.28
+	pdfPageHeight = 841.89
+	pdfMargin     = 40.0
+
+	pdfChartHeight    = 170.0
+	pdfChartMaxBars   = 12
+	pdfTableFontSize  = 8.0
+	pdfTableRowHeight = 15.0
+)
+
+// pdfBrandColor is the RGB fill used for the title, chart bars and table
+// headers, unless the tenant has a brand color of its own.
+var pdfBrandColor = [3]float64{0.55, 0.27, 0.07}
+
+// helveticaWidths holds the Helvetica glyph widths (per 1000 units) for ASCII 32-126.
+var helveticaWidths = [95]int{
+	278, 278, 355, 556, 556, 889, 667, 191, 333, 333, 389, 584, 278, 333, 278, 278,
+	556, 556, 556, 556, 556, 556, 556, 556, 556, 556, 278, 278, 584, 584, 584, 556,
+	1015, 667, 667, 722, 722, 667, 611, 778, 722, 278, 500, 667, 556, 833, 722, 778,
+	667, 778, 722, 667, 611, 722, 667, 944, 667, 667, 611, 278, 278, 278, 469, 556,
+	333, 556, 556, 500, 556, 556, 278, 556, 556, 222, 222, 500, 222, 833, 556, 556,
+	556, 556, 333, 500, 278, 556, 500, 722, 500, 500, 500, 334, 260, 334, 584,
+}
+
+// PDFRenderer renders report documents as PDF files with a server-rendered bar chart.
+// It only depends on the standard library and uses the built-in Helvetica fonts,
+// so text is limited to the Latin-1 character set.
+type PDFRenderer struct{}
+
+// NewPDFRenderer creates a new PDF renderer.
+func NewPDFRenderer() *PDFRenderer {
+	return &PDFRenderer{}
+}
+
+// Format returns the file format produced by the renderer.
+func (r *PDFRenderer) Format() string {
+	return "pdf"
+}
+
+// Render writes the document to w as a PDF file.
+func (r *PDFRenderer) Render(ctx context.Context, w io.Writer, doc *ports.ReportDocument) error {
+	logo := decodePDFLogo(doc.Branding)
+
+	layout := &pdfLayout{doc: doc, logo: logo, brand: pdfColor(doc.Branding.PrimaryColor, pdfBrandColor)}
+	layout.build()
+
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	data, err := layout.encode()
+	if err != nil {
+		return fmt.Errorf("failed to encode pdf: %w", err)
+	}
+
+	if _, err := w.Write(data); err != nil {
+		return fmt.Errorf("failed to write pdf: %w", err)
+	}
+	return nil
+}
+
+// ============================================================================
+// Layout
+// ============================================================================
+
+// pdfLogo is a JPEG logo embedded as an image XObject.
+type pdfLogo struct {
+	data       []byte
+	width      int
+	height     int
+	colorSpace string
+}
+
+// decodePDFLogo returns the branding logo if it is a JPEG the renderer can embed.
+func decodePDFLogo(branding ports.ReportBrandingAsset) *pdfLogo {
+	if len(branding.Logo) == 0 {
+		return nil
+	}
+
+	cfg, format, err := image.DecodeConfig(bytes.NewReader(branding.Logo))
+	if err != nil || format != "jpeg" || cfg.Width == 0 || cfg.Height == 0 {
+		return nil
+	}
+
+	colorSpace := "DeviceRGB"
+	switch cfg.ColorModel {
+	case color.GrayModel:
+		colorSpace = "DeviceGray"
+	case color.CMYKModel:
+		return nil
+	}
+
+	return &pdfLogo{data: branding.Logo, width: cfg.Width, height: cfg.Height, colorSpace: colorSpace}
+}
+
+// pdfColor parses a #rrggbb color, returning fallback when it is not one.
+func pdfColor(hex string, fallback [3]float64) [3]float64 {
+	if len(hex) != 7 || hex[0] != '#' {
+		return fallback
+	}
+	value, err := strconv.ParseUint(hex[1:], 16, 32)
+	if err != nil {
+		return fallback
+	}
+	return [3]float64{
+		float64(value>>16&0xff) / 255,
+		float64(value>>8&0xff) / 255,
+		float64(value&0xff) / 255,
+	}
+}
+
+// pdfLayout lays out a document into page content streams.
+type pdfLayout struct {
+	doc   *ports.ReportDocument
+	logo  *pdfLogo
+	brand [3]float64
+	pages []*bytes.Buffer
+	page  *bytes.Buffer
+	y     float64
+}
+
+func (l *pdfLayout) build() {
+	l.newPage()
+	l.drawDetails()
+	l.drawChart()
+	l.drawTable()
+	l.drawSummary()
+	l.drawFooters()
+}
+
+func (l *pdfLayout) newPage() {
+	l.page = &bytes.Buffer{}
+	l.pages = append(l.pages, l.page)
+	l.y = pdfPageHeight - pdfMargin
+	l.drawHeader()
+}
+
+// drawHeader draws the company name, title and logo at the top of the page.
+func (l *pdfLayout) drawHeader() {
+	top := l.y
+
+	if l.logo != nil {
+		height := 40.0
+		width := height * float64(l.logo.width) / float64(l.logo.height)
+		if width > 120 {
+			width = 120
+			height = width * float64(l.logo.height) / float64(l.logo.width)
+		}
+		fmt.Fprintf(l.page, "q %.2f 0 0 %.2f %.2f %.2f cm /Im1 Do Q\n",
+			width, height, pdfPageWidth-pdfMargin-width, top-height)
+	}
+
+	l.text(pdfMargin, top-14, "F2", 14, l.doc.Branding.CompanyName, [3]float64{0, 0, 0})
+	l.text(pdfMargin, top-32, "F2", 11, l.doc.Title, l.brand)
+	if l.doc.Subtitle != "" {
+		l.text(pdfMargin, top-46, "F1", 9, l.doc.Subtitle, [3]float64{0.35, 0.35, 0.35})
+	}
+
+	l.line(pdfMargin, top-54, pdfPageWidth-pdfMargin, top-54, 0.8, [3]float64{0.75, 0.75, 0.75})
+	l.y = top - 70
+}
+
+// drawDetails draws the document detail fields as label/value pairs below the header.
+func (l *pdfLayout) drawDetails() {
+	if len(l.doc.Details) == 0 {
+		return
+	}
+
+	labelWidth := 0.0
+	for _, field := range l.doc.Details {
+		labelWidth = math.Max(labelWidth, textWidth(field.Label, 9, true))
+	}
+
+	for _, field := range l.doc.Details {
+		l.text(pdfMargin, l.y-10, "F2", 9, field.Label, [3]float64{0.35, 0.35, 0.35})
+		l.text(pdfMargin+labelWidth+12, l.y-10, "F1", 9, field.Value, [3]float64{0, 0, 0})
+		l.y -= 13
+	}
+	l.y -= 12
+}
+
+// drawChart draws a vertical bar chart of the document chart series.
+func (l *pdfLayout) drawChart() {
+	chart := l.doc.Chart
+	if chart == nil || len(chart.Values) == 0 {
+		return
+	}
+
+	labels := chart.Labels
+	values := chart.Values
+	if len(values) > pdfChartMaxBars {
+		values = values[:pdfChartMaxBars]
+		labels = labels[:pdfChartMaxBars]
+	}
+
+	l.text(pdfMargin, l.y-10, "F2", 10, chart.Title, [3]float64{0, 0, 0})
+
+	axisLeft := pdfMargin + 45
+	axisRight := pdfPageWidth - pdfMargin
+	axisBottom := l.y - pdfChartHeight
+	axisTop := l.y - 24
+	plotHeight := axisTop - axisBottom
+
+	maxValue := 0.0
+	for _, v := range values {
+		maxValue = math.Max(maxValue, v)
+	}
+	scaleMax := niceCeiling(maxValue)
+
+	// Gridlines and axis labels
+	const gridLines = 4
+	for i := 0; i <= gridLines; i++ {
+		value := scaleMax * float64(i) / gridLines
+		y := axisBottom + plotHeight*float64(i)/gridLines
+		l.line(axisLeft, y, axisRight, y, 0.4, [3]float64{0.88, 0.88, 0.88})
+		label := compactNumber(value)
+		l.text(axisLeft-6-textWidth(label, 7, false), y-2.5, "F1", 7, label, [3]float64{0.4, 0.4, 0.4})
+	}
+	l.line(axisLeft, axisBottom, axisRight, axisBottom, 0.8, [3]float64{0.3, 0.3, 0.3})
+
+	// Bars
+	slot := (axisRight - axisLeft) / float64(len(values))
+	barWidth := math.Min(slot*0.6, 48)
+	for i, v := range values {
+		x := axisLeft + slot*float64(i) + (slot-barWidth)/2
+		height := 0.0
+		if scaleMax > 0 {
+			height = plotHeight * v / scaleMax
+		}
+		fmt.Fprintf(l.page, "%.3f %.3f %.3f rg %.2f %.2f %.2f %.2f re f\n",
+			l.brand[0], l.brand[1], l.brand[2], x, axisBottom, barWidth, height)
+
+		valueLabel := compactNumber(v)
+		l.text(x+(barWidth-textWidth(valueLabel, 7, false))/2, axisBottom+height+3, "F1", 7, valueLabel, [3]float64{0.2, 0.2, 0.2})
+
+		label := ""
+		if i < len(labels) {
+			label = fitText(labels[i], slot-4, 7, false)
+		}
+		l.text(axisLeft+slot*float64(i)+(slot-textWidth(label, 7, false))/2, axisBottom-10, "F1", 7, label, [3]float64{0.2, 0.2, 0.2})
+	}
+
+	l.y = axisBottom - 30
+}
+
+// drawTable draws the report table, continuing onto new pages as needed.
+func (l *pdfLayout) drawTable() {
+	if len(l.doc.Columns) == 0 {
+		return
+	}
+
+	colWidth := (pdfPageWidth - 2*pdfMargin) / float64(len(l.doc.Columns))
+
+	drawHeaderRow := func() {
+		fmt.Fprintf(l.page, "%.3f %.3f %.3f rg %.2f %.2f %.2f %.2f re f\n",
+			l.brand[0], l.brand[1], l.brand[2],
+			pdfMargin, l.y-pdfTableRowHeight, pdfPageWidth-2*pdfMargin, pdfTableRowHeight)
+		for i, col := range l.doc.Columns {
+			l.cell(i, colWidth, col.Header, col.Numeric, true, [3]float64{1, 1, 1})
+		}
+		l.y -= pdfTableRowHeight
+	}
+
+	ensureSpace := func() {
+		if l.y-pdfTableRowHeight < pdfMargin+20 {
+			l.newPage()
+			drawHeaderRow()
+		}
+	}
+
+	drawHeaderRow()
+	for rowIndex, row := range l.doc.Rows {
+		ensureSpace()
+		if rowIndex%2 == 1 {
+			fmt.Fprintf(l.page, "0.96 0.94 0.92 rg %.2f %.2f %.2f %.2f re f\n",
+				pdfMargin, l.y-pdfTableRowHeight, pdfPageWidth-2*pdfMargin, pdfTableRowHeight)
+		}
+		for i, cell := range row {
+			if i >= len(l.doc.Columns) {
+				break
+			}
+			l.cell(i, colWidth, cell.Display, l.doc.Columns[i].Numeric, false, [3]float64{0, 0, 0})
+		}
+		l.y -= pdfTableRowHeight
+	}
+
+	if len(l.doc.Totals) > 0 {
+		ensureSpace()
+		l.line(pdfMargin, l.y, pdfPageWidth-pdfMargin, l.y, 0.8, [3]float64{0.3, 0.3, 0.3})
+		for i, cell := range l.doc.Totals {
+			if i >= len(l.doc.Columns) {
+				break
+			}
+			l.cell(i, colWidth, cell.Display, l.doc.Columns[i].Numeric, true, [3]float64{0, 0, 0})
+		}
+		l.y -= pdfTableRowHeight
+	}
+}
+
+// drawSummary draws the document summary fields right-aligned below the table.
+func (l *pdfLayout) drawSummary() {
+	if len(l.doc.Summary) == 0 {
+		return
+	}
+
+	const rowHeight = 14.0
+	right := pdfPageWidth - pdfMargin
+	valueLeft := right - 110
+
+	l.y -= 8
+	for _, field := range l.doc.Summary {
+		if l.y-rowHeight < pdfMargin+20 {
+			l.newPage()
+		}
+
+		font := "F1"
+		if field.Emphasis {
+			font = "F2"
+			l.line(valueLeft-120, l.y, right, l.y, 0.8, [3]float64{0.3, 0.3, 0.3})
+		}
+		baseline := l.y - rowHeight + 4
+		l.text(valueLeft-8-textWidth(field.Label, 9, field.Emphasis), baseline, font, 9, field.Label, [3]float64{0.2, 0.2, 0.2})
+		l.text(right-textWidth(field.Value, 9, field.Emphasis), baseline, font, 9, field.Value, [3]float64{0, 0, 0})
+		l.y -= rowHeight
+	}
+}
+
+// drawFooters adds the footer text and page numbers to every page.
+func (l *pdfLayout) drawFooters() {
+	for i, page := range l.pages {
+		l.page = page
+		footer := l.doc.FooterText
+		pageLabel := fmt.Sprintf("%d / %d", i+1, len(l.pages))
+		gray := [3]float64{0.45, 0.45, 0.45}
+
+		l.line(pdfMargin, pdfMargin-6, pdfPageWidth-pdfMargin, pdfMargin-6, 0.4, [3]float64{0.8, 0.8, 0.8})
+		if footer != "" {
+			l.text(pdfMargin, pdfMargin-18, "F1", 7, fitText(footer, pdfPageWidth-2*pdfMargin-40, 7, false), gray)
+		}
+		l.text(pdfPageWidth-pdfMargin-textWidth(pageLabel, 7, false), pdfMargin-18, "F1", 7, pageLabel, gray)
+	}
+}
+
+func (l *pdfLayout) cell(col int, colWidth float64, value string, numeric, bold bool, rgb [3]float64) {
+	font := "F1"
+	if bold {
+		font = "F2"
+	}
+
+	value = fitText(value, colWidth-8, pdfTableFontSize, bold)
+	x := pdfMargin + colWidth*float64(col) + 4
+	if numeric {
+		x = pdfMargin + colWidth*float64(col+1) - 4 - textWidth(value, pdfTableFontSize, bold)
+	}
+	l.text(x, l.y-pdfTableRowHeight+4.5, font, pdfTableFontSize, value, rgb)
+}
+
+func (l *pdfLayout) text(x, y float64, font string, size float64, value string, rgb [3]float64) {
+	if value == "" {
+		return
+	}
+	fmt.Fprintf(l.page, "BT %.3f %.3f %.3f rg /%s %.1f Tf %.2f %.2f Td (%s) Tj ET\n",
+		rgb[0], rgb[1], rgb[2], font, size, x, y, pdfEscape(value))
+}
+
+func (l *pdfLayout) line(x1, y1, x2, y2, width float64, rgb [3]float64) {
+	fmt.Fprintf(l.page, "%.3f %.3f %.3f RG %.2f w %.2f %.2f m %.2f %.2f l S\n",
+		rgb[0], rgb[1], rgb[2], width, x1, y1, x2, y2)
+}
+
+// ============================================================================
+// Encoding
+// ============================================================================
+
+// encode serializes the laid-out pages into a PDF file.
+func (l *pdfLayout) encode() ([]byte, error) {
+	var out bytes.Buffer
+	var offsets []int
+
+	object := func(body string) int {
+		offsets = append(offsets, out.Len())
+		id := len(offsets)
+		fmt.Fprintf(&out, "%d 0 obj\n%s\nendobj\n", id, body)
+		return id
+	}
+	streamObject := func(dict string, data []byte) int {
+		offsets = append(offsets, out.Len())
+		id := len(offsets)
+		fmt.Fprintf(&out, "%d 0 obj\n<< %s /Length %d >>\nstream\n", id, dict, len(data))
+		out.Write(data)
+		out.WriteString("\nendstream\nendobj\n")
+		return id
+	}
+
+	out.WriteString("%PDF-1.4\n%\xe2\xe3\xcf\xd3\n")
+
+	// Object numbers are fixed up front: catalog, page tree, fonts, optional logo.
+	catalogID := object("<< /Type /Catalog /Pages 2 0 R >>")
+	pagesIndex := len(offsets)
+	offsets = append(offsets, 0) // placeholder for the page tree
+	regularID := object("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica /Encoding /WinAnsiEncoding >>")
+	boldID := object("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica-Bold /Encoding /WinAnsiEncoding >>")
+
+	xObjects := ""
+	if l.logo != nil {
+		logoID := streamObject(fmt.Sprintf(
+			"/Type /XObject /Subtype /Image /Width %d /Height %d /ColorSpace /%s /BitsPerComponent 8 /Filter /DCTDecode",
+			l.logo.width, l.logo.height, l.logo.colorSpace), l.logo.data)
+		xObjects = fmt.Sprintf(" /XObject << /Im1 %d 0 R >>", logoID)
+	}
+	resources := fmt.Sprintf("<< /Font << /F1 %d 0 R /F2 %d 0 R >>%s >>", regularID, boldID, xObjects)
+
+	pageIDs := make([]string, 0, len(l.pages))
+	for _, page := range l.pages {
+		var compressed bytes.Buffer
+		zw := zlib.NewWriter(&compressed)
+		if _, err := zw.Write(page.Bytes()); err != nil {
+			return nil, err
+		}
+		if err := zw.Close(); err != nil {
+			return nil, err
+		}
+
+		contentID := streamObject("/Filter /FlateDecode", compressed.Bytes())
+		pageID := object(fmt.Sprintf(
+			"<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %.2f %.2f] /Resources %s /Contents %d 0 R >>",
+			pdfPageWidth, pdfPageHeight, resources, contentID))
+		pageIDs = append(pageIDs, fmt.Sprintf("%d 0 R", pageID))
+	}
+
+	// Page tree, written out of order and located through the xref table.
+	offsets[pagesIndex] = out.Len()
+	fmt.Fprintf(&out, "%d 0 obj\n<< /Type /Pages /Kids [%s] /Count %d >>\nendobj\n",
+		pagesIndex+1, strings.Join(pageIDs, " "), len(pageIDs))
+
+	xrefOffset := out.Len()
+	fmt.Fprintf(&out, "xref\n0 %d\n0000000000 65535 f \n", len(offsets)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(&out, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(&out, "trailer\n<< /Size %d /Root %d 0 R >>\nstartxref\n%d\n%%%%EOF\n",
+		len(offsets)+1, catalogID, xrefOffset)
+
+	return out.Bytes(), nil
+}
+
+// ============================================================================
+// Text Helpers
+// ============================================================================
+
+// pdfEscape converts text to an escaped WinAnsi PDF string literal body.
+func pdfEscape(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		switch {
+		case r == '\\' || r == '(' || r == ')':
+			b.WriteByte('\\')
+			b.WriteRune(r)
+		case r < 32:
+			b.WriteByte(' ')
+		case r < 256:
+			b.WriteByte(byte(r))
+		default:
+			b.WriteByte('?')
+		}
+	}
+	return b.String()
+}
+
+// textWidth approximates the rendered width of text in Helvetica.
+func textWidth(s string, size float64, bold bool) float64 {
+	units := 0
+	for _, r := range s {
+		if r >= 32 && r <= 126 {
+			units += helveticaWidths[r-32]
+		} else {
+			units += 556
+		}
+	}
+	width := float64(units) * size / 1000
+	if bold {
+		width *= 1.06
+	}
+	return width
+}
+
+// fitText truncates text with an ellipsis so that it fits within maxWidth.
+func fitText(s string, maxWidth, size float64, bold bool) string {
+	if textWidth(s, size, bold) <= maxWidth {
+		return s
+	}
+	runes := []rune(s)
+	for len(runes) > 0 {
+		runes = runes[:len(runes)-1]
+		candidate := string(runes) + "..."
+		if textWidth(candidate, size, bold) <= maxWidth {
+			return candidate
+		}
+	}
+	return ""
+}
+
+// niceCeiling rounds a value up to 1, 2, 2.5 or 5 times a power of ten.
+func niceCeiling(v float64) float64 {
+	if v <= 0 {
+		return 1
+	}
+	exp := math.Pow(10, math.Floor(math.Log10(v)))
+	for _, step := range []float64{1, 2, 2.5, 5, 10} {
+		if v <= step*exp {
+			return step * exp
+		}
+	}
+	return 10 * exp
+}
+
+// compactNumber formats a number for chart labels, e.g. 1.2K or 3.4M.
+func compactNumber(v float64) string {
+	abs := math.Abs(v)
+	switch {
+	case abs >= 1e9:
+		return trimDecimal(v/1e9) + "B"
+	case abs >= 1e6:
+		return trimDecimal(v/1e6) + "M"
+	case abs >= 1e3:
+		return trimDecimal(v/1e3) + "K"
+	default:
+		return trimDecimal(v)
+	}
+}
+
+func trimDecimal(v float64) string {
+	s := fmt.Sprintf("%.1f", v)
+	return strings.TrimSuffix(s, ".0")
+}
+
+// Ensure PDFRenderer implements ports.ReportRenderer
+var _ ports.ReportRenderer = (*PDFRenderer)(nil)
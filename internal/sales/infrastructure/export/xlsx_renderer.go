@@ -0,0 +1,304 @@
+// Package export contains report renderers for the Sales Pipeline service.
+package export
+
+import (
+	"archive/zip"
+	"bufio"
+	"context"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/kilang-desa-murni/crm/internal/sales/application/ports"
+)
+
+// XLSX cell style indexes, matching the cellXfs in xlsxStyles.
+const (
+	xlsxStyleDefault     = 0
+	xlsxStyleBold        = 1
+	xlsxStyleTitle       = 2
+	xlsxStyleInteger     = 3
+	xlsxStyleDecimal     = 4
+	xlsxStyleBoldInteger = 5
+	xlsxStyleBoldDecimal = 6
+)
+
+const xlsxContentTypes = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">` +
+	`<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>` +
+	`<Default Extension="xml" ContentType="application/xml"/>` +
+	`<Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/>` +
+	`<Override PartName="/xl/worksheets/sheet1.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/>` +
+	`<Override PartName="/xl/styles.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.styles+xml"/>` +
+	`</Types>`
+
+const xlsxRootRels = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+	`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/>` +
+	`</Relationships>`
+
+const xlsxWorkbookRels = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+	`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet1.xml"/>` +
+	`<Relationship Id="rId2" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/styles" Target="styles.xml"/>` +
+	`</Relationships>`
+
+const xlsxWorkbook = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">` +
+	`<sheets><sheet name="Report" sheetId="1" r:id="rId1"/></sheets>` +
+	`</workbook>`
+
+// xlsxStyles uses the built-in number formats 3 (#,##0) and 4 (#,##0.00).
+const xlsxStyles = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<styleSheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">` +
+	`<fonts count="3">` +
+	`<font><sz val="11"/><name val="Calibri"/></font>` +
+	`<font><b/><sz val="11"/><name val="Calibri"/></font>` +
+	`<font><b/><sz val="14"/><name val="Calibri"/></font>` +
+	`</fonts>` +
+	`<fills count="2"><fill><patternFill patternType="none"/></fill><fill><patternFill patternType="gray125"/></fill></fills>` +
+	`<borders count="1"><border><left/><right/><top/><bottom/><diagonal/></border></borders>` +
+	`<cellStyleXfs count="1"><xf numFmtId="0" fontId="0" fillId="0" borderId="0"/></cellStyleXfs>` +
+	`<cellXfs count="7">` +
+	`<xf numFmtId="0" fontId="0" fillId="0" borderId="0" xfId="0"/>` +
+	`<xf numFmtId="0" fontId="1" fillId="0" borderId="0" xfId="0" applyFont="1"/>` +
+	`<xf numFmtId="0" fontId="2" fillId="0" borderId="0" xfId="0" applyFont="1"/>` +
+	`<xf numFmtId="3" fontId="0" fillId="0" borderId="0" xfId="0" applyNumberFormat="1"/>` +
+	`<xf numFmtId="4" fontId="0" fillId="0" borderId="0" xfId="0" applyNumberFormat="1"/>` +
+	`<xf numFmtId="3" fontId="1" fillId="0" borderId="0" xfId="0" applyNumberFormat="1" applyFont="1"/>` +
+	`<xf numFmtId="4" fontId="1" fillId="0" borderId="0" xfId="0" applyNumberFormat="1" applyFont="1"/>` +
+	`</cellXfs>` +
+	`</styleSheet>`
+
+// XLSXRenderer renders report documents as Office Open XML spreadsheets.
+// Rows are streamed straight into the zip archive, so large reports are never
+// held in memory as a whole.
+type XLSXRenderer struct{}
+
+// NewXLSXRenderer creates a new XLSX renderer.
+func NewXLSXRenderer() *XLSXRenderer {
+	return &XLSXRenderer{}
+}
+
+// Format returns the file format produced by the renderer.
+func (r *XLSXRenderer) Format() string {
+	return "xlsx"
+}
+
+// Render writes the document to w as an XLSX workbook.
+func (r *XLSXRenderer) Render(ctx context.Context, w io.Writer, doc *ports.ReportDocument) error {
+	zw := zip.NewWriter(w)
+
+	parts := []struct {
+		name    string
+		content string
+	}{
+		{"[Content_Types].xml", xlsxContentTypes},
+		{"_rels/.rels", xlsxRootRels},
+		{"xl/workbook.xml", xlsxWorkbook},
+		{"xl/_rels/workbook.xml.rels", xlsxWorkbookRels},
+		{"xl/styles.xml", xlsxStyles},
+	}
+	for _, part := range parts {
+		fw, err := zw.Create(part.name)
+		if err != nil {
+			return fmt.Errorf("failed to create %s: %w", part.name, err)
+		}
+		if _, err := io.WriteString(fw, part.content); err != nil {
+			return fmt.Errorf("failed to write %s: %w", part.name, err)
+		}
+	}
+
+	fw, err := zw.Create("xl/worksheets/sheet1.xml")
+	if err != nil {
+		return fmt.Errorf("failed to create worksheet: %w", err)
+	}
+	if err := r.writeSheet(ctx, fw, doc); err != nil {
+		return err
+	}
+
+	if err := zw.Close(); err != nil {
+		return fmt.Errorf("failed to finalize workbook: %w", err)
+	}
+	return nil
+}
+
+// writeSheet streams the worksheet XML for the document.
+func (r *XLSXRenderer) writeSheet(ctx context.Context, w io.Writer, doc *ports.ReportDocument) error {
+	sw := &sheetWriter{w: bufio.NewWriter(w)}
+
+	sw.raw(`<?xml version="1.0" encoding="UTF-8" standalone="yes"?>` + "\n")
+	sw.raw(`<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">`)
+
+	if len(doc.Columns) > 0 {
+		sw.raw(`<cols>`)
+		for i, col := range doc.Columns {
+			width := len(col.Header) + 4
+			if width < 14 {
+				width = 14
+			}
+			sw.raw(fmt.Sprintf(`<col min="%d" max="%d" width="%d" customWidth="1"/>`, i+1, i+1, width))
+		}
+		sw.raw(`</cols>`)
+	}
+
+	sw.raw(`<sheetData>`)
+
+	// Title block
+	if doc.Branding.CompanyName != "" {
+		sw.textRow([]string{doc.Branding.CompanyName}, xlsxStyleTitle)
+	}
+	sw.textRow([]string{doc.Title}, xlsxStyleBold)
+	if doc.Subtitle != "" {
+		sw.textRow([]string{doc.Subtitle}, xlsxStyleDefault)
+	}
+	sw.blankRow()
+
+	// Details block
+	if len(doc.Details) > 0 {
+		for _, field := range doc.Details {
+			sw.fieldRow(0, field.Label, field.Value, xlsxStyleDefault)
+		}
+		sw.blankRow()
+	}
+
+	// Table
+	headers := make([]string, len(doc.Columns))
+	for i, col := range doc.Columns {
+		headers[i] = col.Header
+	}
+	sw.textRow(headers, xlsxStyleBold)
+
+	for i, row := range doc.Rows {
+		if i%500 == 0 {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+		}
+		sw.cellRow(doc.Columns, row, false)
+	}
+	if len(doc.Totals) > 0 {
+		sw.cellRow(doc.Columns, doc.Totals, true)
+	}
+
+	// Summary block, aligned with the last column
+	if len(doc.Summary) > 0 {
+		sw.blankRow()
+		for _, field := range doc.Summary {
+			style := xlsxStyleDefault
+			if field.Emphasis {
+				style = xlsxStyleBold
+			}
+			sw.fieldRow(max(len(doc.Columns)-2, 0), field.Label, field.Value, style)
+		}
+	}
+
+	if doc.FooterText != "" {
+		sw.blankRow()
+		sw.textRow([]string{doc.FooterText}, xlsxStyleDefault)
+	}
+
+	sw.raw(`</sheetData></worksheet>`)
+
+	if sw.err != nil {
+		return fmt.Errorf("failed to write worksheet: %w", sw.err)
+	}
+	if err := sw.w.Flush(); err != nil {
+		return fmt.Errorf("failed to write worksheet: %w", err)
+	}
+	return nil
+}
+
+// sheetWriter writes worksheet rows, remembering the first write error.
+type sheetWriter struct {
+	w   *bufio.Writer
+	row int
+	err error
+}
+
+func (sw *sheetWriter) raw(s string) {
+	if sw.err != nil {
+		return
+	}
+	_, sw.err = sw.w.WriteString(s)
+}
+
+func (sw *sheetWriter) blankRow() {
+	sw.row++
+}
+
+func (sw *sheetWriter) textRow(values []string, style int) {
+	sw.row++
+	sw.raw(fmt.Sprintf(`<row r="%d">`, sw.row))
+	for i, v := range values {
+		sw.inlineString(i, v, style)
+	}
+	sw.raw(`</row>`)
+}
+
+// fieldRow writes a label/value pair starting at the given zero-based column.
+func (sw *sheetWriter) fieldRow(col int, label, value string, style int) {
+	sw.row++
+	sw.raw(fmt.Sprintf(`<row r="%d">`, sw.row))
+	sw.inlineString(col, label, style)
+	sw.inlineString(col+1, value, style)
+	sw.raw(`</row>`)
+}
+
+func (sw *sheetWriter) cellRow(columns []ports.ReportColumn, cells []ports.ReportCell, bold bool) {
+	sw.row++
+	sw.raw(fmt.Sprintf(`<row r="%d">`, sw.row))
+	for i, cell := range cells {
+		var col ports.ReportColumn
+		if i < len(columns) {
+			col = columns[i]
+		}
+
+		if col.Numeric && cell.Value != nil {
+			style := xlsxStyleInteger
+			switch {
+			case bold && col.Decimals > 0:
+				style = xlsxStyleBoldDecimal
+			case bold:
+				style = xlsxStyleBoldInteger
+			case col.Decimals > 0:
+				style = xlsxStyleDecimal
+			}
+			sw.raw(fmt.Sprintf(`<c r="%s" s="%d"><v>%s</v></c>`,
+				cellRef(i, sw.row), style, strconv.FormatFloat(*cell.Value, 'f', -1, 64)))
+			continue
+		}
+
+		style := xlsxStyleDefault
+		if bold {
+			style = xlsxStyleBold
+		}
+		sw.inlineString(i, cell.Display, style)
+	}
+	sw.raw(`</row>`)
+}
+
+func (sw *sheetWriter) inlineString(col int, value string, style int) {
+	var escaped strings.Builder
+	if err := xml.EscapeText(&escaped, []byte(value)); err != nil {
+		sw.err = err
+		return
+	}
+	sw.raw(fmt.Sprintf(`<c r="%s" s="%d" t="inlineStr"><is><t xml:space="preserve">%s</t></is></c>`,
+		cellRef(col, sw.row), style, escaped.String()))
+}
+
+// cellRef returns the A1-style reference of a zero-based column and one-based row.
+func cellRef(col, row int) string {
+	name := ""
+	for col >= 0 {
+		name = string(rune('A'+col%26)) + name
+		col = col/26 - 1
+	}
+	return name + strconv.Itoa(row)
+}
+
+// Ensure XLSXRenderer implements ports.ReportRenderer
+var _ ports.ReportRenderer = (*XLSXRenderer)(nil)
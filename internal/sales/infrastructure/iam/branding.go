@@ -0,0 +1,111 @@
+// Package iam provides clients of the IAM service for the sales service.
+package iam
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/kilang-desa-murni/crm/internal/sales/application/ports"
+)
+
+// ============================================================================
+// Tenant Branding Provider
+// ============================================================================
+
+// BrandingConfig holds configuration for the IAM tenant branding provider.
+type BrandingConfig struct {
+	// BaseURL is the IAM service URL, serving its internal routes.
+	BaseURL string
+	Timeout time.Duration
+	// Transport sends the requests, authenticating them with a service
+	// token; http.DefaultTransport when nil.
+	Transport http.RoundTripper
+}
+
+// DefaultBrandingConfig returns default branding provider configuration for
+// the IAM service at baseURL.
+func DefaultBrandingConfig(baseURL string) BrandingConfig {
+	return BrandingConfig{
+		BaseURL: baseURL,
+		Timeout: 3 * time.Second,
+	}
+}
+
+// HTTPBrandingProvider implements TenantBrandingProvider with the IAM
+// service's internal tenant branding route.
+type HTTPBrandingProvider struct {
+	config     BrandingConfig
+	httpClient *http.Client
+}
+
+// NewHTTPBrandingProvider creates a new IAM tenant branding provider.
+func NewHTTPBrandingProvider(config BrandingConfig) *HTTPBrandingProvider {
+	return &HTTPBrandingProvider{
+		config: config,
+		httpClient: &http.Client{
+			Timeout:   config.Timeout,
+			Transport: config.Transport,
+		},
+	}
+}
+
+// brandingResponse is the IAM service response envelope.
+type brandingResponse struct {
+	Data struct {
+		Name     string `json:"name"`
+		Branding *struct {
+			LogoURL        string `json:"logo_url"`
+			PrimaryColor   string `json:"primary_color"`
+			SecondaryColor string `json:"secondary_color"`
+		} `json:"branding"`
+	} `json:"data"`
+}
+
+// GetTenantBranding returns the tenant's branding, or nil when the tenant
+// has none.
+func (p *HTTPBrandingProvider) GetTenantBranding(ctx context.Context, tenantID uuid.UUID) (*ports.TenantBranding, error) {
+	endpoint := fmt.Sprintf("%s/internal/tenants/%s/branding", strings.TrimRight(p.config.BaseURL, "/"), tenantID)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := p.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("tenant branding request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read tenant branding: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("tenant branding request failed: status %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+
+	var response brandingResponse
+	if err := json.Unmarshal(body, &response); err != nil {
+		return nil, fmt.Errorf("failed to parse tenant branding: %w", err)
+	}
+	if response.Data.Branding == nil {
+		return nil, nil
+	}
+	return &ports.TenantBranding{
+		TenantName:     response.Data.Name,
+		LogoURL:        response.Data.Branding.LogoURL,
+		PrimaryColor:   response.Data.Branding.PrimaryColor,
+		SecondaryColor: response.Data.Branding.SecondaryColor,
+	}, nil
+}
+
+// Ensure HTTPBrandingProvider implements TenantBrandingProvider
+var _ ports.TenantBrandingProvider = (*HTTPBrandingProvider)(nil)
@@ -0,0 +1,120 @@
+// Package imaging contains image processing adapters for the Sales Pipeline service.
+package imaging
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/jpeg"
+
+	// Register the decoders for the other attachable image formats
+	_ "image/gif"
+	_ "image/png"
+
+	"github.com/kilang-desa-murni/crm/internal/sales/application/ports"
+)
+
+// DefaultJPEGQuality is the quality resized images are encoded with.
+const DefaultJPEGQuality = 85
+
+// Resizer implements ports.ImageResizer with the standard library. Images are
+// downscaled with a box filter, which averages every source pixel into the
+// destination pixel covering it and avoids the aliasing of nearest-neighbour
+// sampling on fine batik patterns.
+type Resizer struct {
+	quality int
+}
+
+// NewResizer creates a new resizer encoding JPEG at the given quality.
+func NewResizer(quality int) *Resizer {
+	if quality < 1 || quality > 100 {
+		quality = DefaultJPEGQuality
+	}
+	return &Resizer{quality: quality}
+}
+
+// Resize scales an image to fit within maxDimension pixels on its longest side
+// and returns it encoded as JPEG. Transparent areas are flattened onto white.
+func (r *Resizer) Resize(content []byte, maxDimension int) ([]byte, error) {
+	src, _, err := image.Decode(bytes.NewReader(content))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode image: %w", err)
+	}
+
+	bounds := src.Bounds()
+	width, height := bounds.Dx(), bounds.Dy()
+	if width == 0 || height == 0 {
+		return nil, fmt.Errorf("image has no pixels")
+	}
+
+	// Flatten onto white once so sampling reads plain RGBA pixels
+	flat := image.NewRGBA(image.Rect(0, 0, width, height))
+	draw.Draw(flat, flat.Bounds(), &image.Uniform{C: color.White}, image.Point{}, draw.Src)
+	draw.Draw(flat, flat.Bounds(), src, bounds.Min, draw.Over)
+
+	dstWidth, dstHeight := fitWithin(width, height, maxDimension)
+	dst := flat
+	if dstWidth != width || dstHeight != height {
+		dst = boxResize(flat, dstWidth, dstHeight)
+	}
+
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, dst, &jpeg.Options{Quality: r.quality}); err != nil {
+		return nil, fmt.Errorf("failed to encode image: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// fitWithin returns the size of an image scaled to fit within maxDimension on
+// its longest side, keeping the aspect ratio. Images already within the limit
+// keep their size.
+func fitWithin(width, height, maxDimension int) (int, int) {
+	if maxDimension <= 0 || (width <= maxDimension && height <= maxDimension) {
+		return width, height
+	}
+	if width >= height {
+		return maxDimension, max(1, height*maxDimension/width)
+	}
+	return max(1, width*maxDimension/height), maxDimension
+}
+
+// boxResize downscales src to width x height, averaging the source pixels
+// covered by each destination pixel.
+func boxResize(src *image.RGBA, width, height int) *image.RGBA {
+	srcWidth, srcHeight := src.Bounds().Dx(), src.Bounds().Dy()
+	dst := image.NewRGBA(image.Rect(0, 0, width, height))
+
+	for y := 0; y < height; y++ {
+		y0 := y * srcHeight / height
+		y1 := max(y0+1, (y+1)*srcHeight/height)
+		for x := 0; x < width; x++ {
+			x0 := x * srcWidth / width
+			x1 := max(x0+1, (x+1)*srcWidth/width)
+
+			var rSum, gSum, bSum, aSum, count uint64
+			for sy := y0; sy < y1; sy++ {
+				row := src.Pix[sy*src.Stride:]
+				for sx := x0; sx < x1; sx++ {
+					p := row[sx*4 : sx*4+4]
+					rSum += uint64(p[0])
+					gSum += uint64(p[1])
+					bSum += uint64(p[2])
+					aSum += uint64(p[3])
+					count++
+				}
+			}
+
+			d := dst.Pix[y*dst.Stride+x*4:]
+			d[0] = uint8(rSum / count)
+			d[1] = uint8(gSum / count)
+			d[2] = uint8(bSum / count)
+			d[3] = uint8(aSum / count)
+		}
+	}
+	return dst
+}
+
+// Ensure Resizer implements ports.ImageResizer
+var _ ports.ImageResizer = (*Resizer)(nil)
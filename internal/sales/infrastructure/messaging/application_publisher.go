@@ -0,0 +1,126 @@
+package messaging
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+
+	"github.com/kilang-desa-murni/crm/internal/sales/application/ports"
+)
+
+// ============================================================================
+// Application Event Publisher
+// ============================================================================
+
+// messagePublisher publishes a message on the sales exchange. It is
+// implemented by RabbitMQPublisher and MemoryBus.
+type messagePublisher interface {
+	publishMessage(ctx context.Context, routingKey string, msg amqp.Publishing) error
+}
+
+// ApplicationPublisher implements ports.EventPublisher on a RabbitMQPublisher,
+// so the use cases' events go out on the sales exchange with the same routing
+// keys and headers as the domain events.
+type ApplicationPublisher struct {
+	publisher messagePublisher
+}
+
+// NewApplicationPublisher creates a new application event publisher.
+func NewApplicationPublisher(publisher *RabbitMQPublisher) *ApplicationPublisher {
+	return &ApplicationPublisher{publisher: publisher}
+}
+
+// NewMemoryApplicationPublisher creates an application event publisher that
+// delivers the events on a memory bus, for dev mode.
+func NewMemoryApplicationPublisher(bus *MemoryBus) *ApplicationPublisher {
+	return &ApplicationPublisher{publisher: bus}
+}
+
+// Publish publishes a single event.
+func (a *ApplicationPublisher) Publish(ctx context.Context, event ports.Event) error {
+	body, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to serialize event: %w", err)
+	}
+
+	occurredAt := event.OccurredAt
+	if occurredAt.IsZero() {
+		occurredAt = time.Now().UTC()
+	}
+
+	msg := amqp.Publishing{
+		Body:      body,
+		Timestamp: time.Now().UTC(),
+		MessageId: event.ID,
+		Headers: amqp.Table{
+			"event_type":     event.Type,
+			"aggregate_type": event.AggregateType,
+			"aggregate_id":   event.AggregateID,
+			"tenant_id":      event.TenantID,
+			"version":        int32(event.Version),
+			"occurred_at":    occurredAt.Format(time.RFC3339Nano),
+		},
+	}
+
+	return a.publisher.publishMessage(ctx, fmt.Sprintf("sales.%s", event.Type), msg)
+}
+
+// PublishBatch publishes multiple events.
+func (a *ApplicationPublisher) PublishBatch(ctx context.Context, events []ports.Event) error {
+	for _, event := range events {
+		if err := a.Publish(ctx, event); err != nil {
+			return fmt.Errorf("failed to publish event %s: %w", event.ID, err)
+		}
+	}
+	return nil
+}
+
+// PublishAsync publishes an event without waiting for the broker. Failures
+// are dropped; use the outbox where events must not be lost.
+func (a *ApplicationPublisher) PublishAsync(ctx context.Context, event ports.Event) error {
+	go func() {
+		ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), 30*time.Second)
+		defer cancel()
+		_ = a.Publish(ctx, event)
+	}()
+	return nil
+}
+
+// publishMessage publishes a message to the exchange with the configured
+// delivery mode and content type, and waits for the broker's confirmation.
+func (p *RabbitMQPublisher) publishMessage(ctx context.Context, routingKey string, msg amqp.Publishing) error {
+	msg.DeliveryMode = p.config.DeliveryMode
+	msg.ContentType = p.config.ContentType
+
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	if p.closed {
+		return fmt.Errorf("publisher is closed")
+	}
+
+	if p.channel == nil {
+		return fmt.Errorf("channel is not available")
+	}
+
+	confirm, err := p.channel.PublishWithDeferredConfirmWithContext(
+		ctx,
+		p.config.Exchange,
+		routingKey,
+		false, // mandatory
+		false, // immediate
+		msg,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to publish event: %w", err)
+	}
+
+	if !confirm.Wait() {
+		return fmt.Errorf("failed to confirm event publication")
+	}
+
+	return nil
+}
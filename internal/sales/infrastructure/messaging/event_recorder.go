@@ -0,0 +1,109 @@
+package messaging
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/kilang-desa-murni/crm/internal/sales/application/ports"
+	"github.com/kilang-desa-murni/crm/internal/sales/domain"
+	"github.com/kilang-desa-murni/crm/pkg/logger"
+)
+
+// ============================================================================
+// Event Recorder
+// ============================================================================
+
+// RecordingPublisher appends every published event to the event store before
+// handing it to the next publisher, so the store holds a complete audit
+// stream that can be queried and replayed. A failed append is logged and does
+// not stop the event from being published.
+type RecordingPublisher struct {
+	store domain.EventStore
+	next  ports.EventPublisher
+	log   *logger.Logger
+}
+
+// NewRecordingPublisher creates a new recording publisher.
+func NewRecordingPublisher(store domain.EventStore, next ports.EventPublisher, log *logger.Logger) *RecordingPublisher {
+	return &RecordingPublisher{store: store, next: next, log: log}
+}
+
+// Publish records and publishes a single event.
+func (p *RecordingPublisher) Publish(ctx context.Context, event ports.Event) error {
+	p.record(ctx, event)
+	return p.next.Publish(ctx, event)
+}
+
+// PublishBatch records and publishes multiple events.
+func (p *RecordingPublisher) PublishBatch(ctx context.Context, events []ports.Event) error {
+	p.record(ctx, events...)
+	return p.next.PublishBatch(ctx, events)
+}
+
+// PublishAsync records an event and publishes it asynchronously.
+func (p *RecordingPublisher) PublishAsync(ctx context.Context, event ports.Event) error {
+	p.record(ctx, event)
+	return p.next.PublishAsync(ctx, event)
+}
+
+func (p *RecordingPublisher) record(ctx context.Context, events ...ports.Event) {
+	recorded := make([]domain.DomainEvent, 0, len(events))
+	for _, event := range events {
+		e, err := newPublishedEvent(event)
+		if err != nil {
+			p.log.Error().Err(err).Str("event_type", event.Type).Msg("Failed to record event")
+			continue
+		}
+		recorded = append(recorded, e)
+	}
+
+	if err := p.store.Save(ctx, recorded...); err != nil {
+		p.log.Error().Err(err).Int("events", len(recorded)).Msg("Failed to record events")
+	}
+}
+
+// publishedEvent adapts a published ports.Event to domain.DomainEvent for the
+// event store. The stored event data is the event as published.
+type publishedEvent struct {
+	event       ports.Event
+	id          uuid.UUID
+	tenantID    uuid.UUID
+	aggregateID uuid.UUID
+}
+
+func newPublishedEvent(event ports.Event) (*publishedEvent, error) {
+	e := &publishedEvent{event: event}
+
+	var err error
+	if e.id, err = uuid.Parse(event.ID); err != nil {
+		return nil, fmt.Errorf("invalid event id %q: %w", event.ID, err)
+	}
+	if e.tenantID, err = uuid.Parse(event.TenantID); err != nil {
+		return nil, fmt.Errorf("invalid tenant id %q: %w", event.TenantID, err)
+	}
+	if e.aggregateID, err = uuid.Parse(event.AggregateID); err != nil {
+		return nil, fmt.Errorf("invalid aggregate id %q: %w", event.AggregateID, err)
+	}
+	return e, nil
+}
+
+func (e *publishedEvent) EventID() uuid.UUID          { return e.id }
+func (e *publishedEvent) TenantID() uuid.UUID         { return e.tenantID }
+func (e *publishedEvent) AggregateID() uuid.UUID      { return e.aggregateID }
+func (e *publishedEvent) AggregateType() string       { return e.event.AggregateType }
+func (e *publishedEvent) EventType() string           { return e.event.Type }
+func (e *publishedEvent) Version() int                { return e.event.Version }
+func (e *publishedEvent) OccurredAt() time.Time       { return e.event.OccurredAt }
+func (e *publishedEvent) Metadata() map[string]string { return e.event.Metadata }
+
+// MarshalJSON returns the event as published.
+func (e *publishedEvent) MarshalJSON() ([]byte, error) {
+	return json.Marshal(e.event)
+}
+
+// Ensure RecordingPublisher implements ports.EventPublisher
+var _ ports.EventPublisher = (*RecordingPublisher)(nil)
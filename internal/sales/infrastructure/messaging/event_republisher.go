@@ -0,0 +1,113 @@
+package messaging
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+
+	"github.com/kilang-desa-murni/crm/internal/sales/domain"
+)
+
+// ============================================================================
+// Event Republisher
+// ============================================================================
+
+// ReplayedHeader marks messages that were republished from the event store, so
+// consumers can tell a replay from a live event.
+const ReplayedHeader = "replayed"
+
+// RoutingKeyHeader carries the original routing key of an event delivered
+// straight to a queue, where the routing key is the queue name.
+const RoutingKeyHeader = "routing_key"
+
+// EventRepublisher republishes recorded events to RabbitMQ to re-drive
+// consumers. Events go either to the sales exchange, reaching every bound
+// consumer, or straight to one consumer's queue.
+type EventRepublisher struct {
+	conn    *amqp.Connection
+	channel *amqp.Channel
+}
+
+// NewEventRepublisher connects a new event republisher.
+func NewEventRepublisher(url string) (*EventRepublisher, error) {
+	conn, err := amqp.Dial(url)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to RabbitMQ: %w", err)
+	}
+
+	ch, err := conn.Channel()
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("failed to open channel: %w", err)
+	}
+
+	if err := ch.Confirm(false); err != nil {
+		ch.Close()
+		conn.Close()
+		return nil, fmt.Errorf("failed to enable publisher confirms: %w", err)
+	}
+
+	return &EventRepublisher{conn: conn, channel: ch}, nil
+}
+
+// Republish publishes a recorded event with the body and headers it was
+// originally published with. An empty queue publishes to the sales exchange.
+func (r *EventRepublisher) Republish(ctx context.Context, record *domain.EventRecord, queue string) error {
+	routingKey := fmt.Sprintf("sales.%s", record.EventType)
+
+	msg := amqp.Publishing{
+		DeliveryMode: amqp.Persistent,
+		ContentType:  "application/json",
+		Body:         record.Data,
+		Timestamp:    time.Now().UTC(),
+		MessageId:    record.ID.String(),
+		Headers: amqp.Table{
+			"event_type":     record.EventType,
+			"aggregate_type": record.AggregateType,
+			"aggregate_id":   record.AggregateID.String(),
+			"tenant_id":      record.TenantID.String(),
+			"version":        int32(record.Version),
+			"occurred_at":    record.OccurredAt.Format(time.RFC3339Nano),
+			ReplayedHeader:   true,
+		},
+	}
+
+	exchange := SalesEventsExchange
+	if queue != "" {
+		msg.Headers[RoutingKeyHeader] = routingKey
+		exchange, routingKey = "", queue
+	}
+
+	confirm, err := r.channel.PublishWithDeferredConfirmWithContext(
+		ctx,
+		exchange,
+		routingKey,
+		false, // mandatory
+		false, // immediate
+		msg,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to republish event %s: %w", record.ID, err)
+	}
+	if !confirm.Wait() {
+		return fmt.Errorf("failed to confirm republished event %s", record.ID)
+	}
+	return nil
+}
+
+// CheckQueue reports an error if a queue does not exist. Call it before
+// republishing to a queue: the broker silently drops messages routed nowhere.
+func (r *EventRepublisher) CheckQueue(queue string) error {
+	if _, err := r.channel.QueueDeclarePassive(queue, true, false, false, false, nil); err != nil {
+		return fmt.Errorf("queue %s is not available: %w", queue, err)
+	}
+	return nil
+}
+
+// Close closes the republisher connection.
+func (r *EventRepublisher) Close() error {
+	r.channel.Close()
+	return r.conn.Close()
+}
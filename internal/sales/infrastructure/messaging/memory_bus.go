@@ -0,0 +1,229 @@
+package messaging
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+
+	"github.com/kilang-desa-murni/crm/internal/sales/domain"
+	"github.com/kilang-desa-murni/crm/pkg/lifecycle"
+)
+
+// ============================================================================
+// Memory Event Bus
+// ============================================================================
+
+// memoryQueueSize is the number of events a memory consumer buffers before
+// publishers wait for it.
+const memoryQueueSize = 256
+
+// EventConsumer consumes events from the sales exchange. It is implemented by
+// RabbitMQConsumer and, in dev mode, by MemoryConsumer.
+type EventConsumer interface {
+	// Consume delivers events to handler until ctx is cancelled or the
+	// consumer is shut down.
+	Consume(ctx context.Context, handler EventHandler) error
+
+	// Shutdown stops consuming and waits for the event being handled.
+	Shutdown(ctx context.Context) error
+}
+
+// MemoryBus delivers the service's events to its own consumers in process,
+// so the service runs without RabbitMQ in dev mode. Events are published on
+// the sales exchange and routed by the consumers' topic bindings; they are
+// not persisted and never reach other services.
+type MemoryBus struct {
+	mu        sync.RWMutex
+	consumers map[*MemoryConsumer]struct{}
+	closed    bool
+}
+
+// NewMemoryBus creates a new memory event bus.
+func NewMemoryBus() *MemoryBus {
+	return &MemoryBus{consumers: make(map[*MemoryConsumer]struct{})}
+}
+
+// NewConsumer creates a consumer receiving the events that match bindings.
+// Events published before Consume is called are buffered.
+func (b *MemoryBus) NewConsumer(bindings []ConsumerBinding) *MemoryConsumer {
+	c := &MemoryConsumer{
+		bus:      b,
+		bindings: bindings,
+		events:   make(chan ConsumedEvent, memoryQueueSize),
+	}
+
+	b.mu.Lock()
+	b.consumers[c] = struct{}{}
+	b.mu.Unlock()
+	return c
+}
+
+// Publish publishes a domain event.
+func (b *MemoryBus) Publish(ctx context.Context, event domain.DomainEvent) error {
+	body, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to serialize event: %w", err)
+	}
+
+	return b.publishMessage(ctx, fmt.Sprintf("sales.%s", event.EventType()), amqp.Publishing{
+		Body:      body,
+		MessageId: event.EventID().String(),
+		Headers: amqp.Table{
+			"tenant_id":    event.TenantID().String(),
+			"aggregate_id": event.AggregateID().String(),
+		},
+	})
+}
+
+// PublishBatch publishes multiple domain events.
+func (b *MemoryBus) PublishBatch(ctx context.Context, events []domain.DomainEvent) error {
+	for _, event := range events {
+		if err := b.Publish(ctx, event); err != nil {
+			return fmt.Errorf("failed to publish event %s: %w", event.EventID(), err)
+		}
+	}
+	return nil
+}
+
+// Close stops the bus. Events published afterwards fail.
+func (b *MemoryBus) Close() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.closed = true
+	return nil
+}
+
+// IsConnected reports whether the bus is open.
+func (b *MemoryBus) IsConnected() bool {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	return !b.closed
+}
+
+// publishMessage routes a message on the sales exchange to the consumers
+// bound to its routing key, waiting while a consumer's buffer is full.
+func (b *MemoryBus) publishMessage(ctx context.Context, routingKey string, msg amqp.Publishing) error {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	if b.closed {
+		return fmt.Errorf("publisher is closed")
+	}
+
+	event := newConsumedEvent(amqp.Delivery{
+		Exchange:   SalesEventsExchange,
+		RoutingKey: routingKey,
+		MessageId:  msg.MessageId,
+		Headers:    msg.Headers,
+		Body:       msg.Body,
+	})
+
+	for c := range b.consumers {
+		if !c.matches(SalesEventsExchange, routingKey) {
+			continue
+		}
+		select {
+		case c.events <- event:
+		case <-ctx.Done():
+			return fmt.Errorf("failed to publish event: %w", ctx.Err())
+		}
+	}
+	return nil
+}
+
+// remove detaches a consumer so it receives no more events.
+func (b *MemoryBus) remove(c *MemoryConsumer) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	delete(b.consumers, c)
+}
+
+// MemoryConsumer consumes events from a MemoryBus.
+type MemoryConsumer struct {
+	bus      *MemoryBus
+	bindings []ConsumerBinding
+	events   chan ConsumedEvent
+	drainer  lifecycle.Drainer
+}
+
+// matches reports whether an event published on exchange with routingKey is
+// bound to the consumer.
+func (c *MemoryConsumer) matches(exchange, routingKey string) bool {
+	for _, binding := range c.bindings {
+		if binding.Exchange == exchange && topicMatches(binding.RoutingKey, routingKey) {
+			return true
+		}
+	}
+	return false
+}
+
+// Consume delivers events to handler until ctx is cancelled or the consumer is
+// shut down. A failed event is retried once and dropped if it fails again,
+// as with RabbitMQConsumer.
+func (c *MemoryConsumer) Consume(ctx context.Context, handler EventHandler) error {
+	go func() {
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case event := <-c.events:
+				if !c.drainer.Begin() {
+					return
+				}
+				if err := handler(ctx, event); err != nil {
+					_ = handler(ctx, event)
+				}
+				c.drainer.End()
+			}
+		}
+	}()
+	return nil
+}
+
+// Shutdown stops consuming, drops the events not yet handled and waits for
+// the event being handled.
+func (c *MemoryConsumer) Shutdown(ctx context.Context) error {
+	c.bus.remove(c)
+	c.drainer.Stop()
+	return c.drainer.Wait(ctx)
+}
+
+// topicMatches reports whether a routing key matches a topic binding, where
+// "*" matches exactly one word and "#" zero or more words.
+func topicMatches(pattern, routingKey string) bool {
+	return matchWords(strings.Split(pattern, "."), strings.Split(routingKey, "."))
+}
+
+func matchWords(pattern, words []string) bool {
+	if len(pattern) == 0 {
+		return len(words) == 0
+	}
+
+	switch pattern[0] {
+	case "#":
+		for i := 0; i <= len(words); i++ {
+			if matchWords(pattern[1:], words[i:]) {
+				return true
+			}
+		}
+		return false
+	case "*":
+		return len(words) > 0 && matchWords(pattern[1:], words[1:])
+	default:
+		return len(words) > 0 && words[0] == pattern[0] && matchWords(pattern[1:], words[1:])
+	}
+}
+
+// Ensure the memory bus can stand in for RabbitMQ
+var (
+	_ EventPublisher = (*MemoryBus)(nil)
+	_ EventConsumer  = (*MemoryConsumer)(nil)
+	_ EventConsumer  = (*RabbitMQConsumer)(nil)
+)
@@ -0,0 +1,336 @@
+package messaging
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	amqp "github.com/rabbitmq/amqp091-go"
+
+	"github.com/kilang-desa-murni/crm/pkg/lifecycle"
+)
+
+// ============================================================================
+// Event Consumer
+// ============================================================================
+
+const (
+	// CustomerEventsExchange is the customer service's event exchange.
+	CustomerEventsExchange = "customer.events"
+
+	// CacheInvalidationQueue receives the events that invalidate cached sales data.
+	CacheInvalidationQueue = "sales.cache-invalidation"
+
+	// OpportunityBoardQueue receives the events that update the opportunity board read model.
+	OpportunityBoardQueue = "sales.opportunity-board"
+
+	// StageFunnelQueue receives the events that update the stage funnel read model.
+	StageFunnelQueue = "sales.stage-funnel"
+
+	// ThumbnailQueue receives the file uploads that need image variants generated.
+	ThumbnailQueue = "sales.thumbnails"
+
+	// AttachmentScanQueue receives the file uploads that need a virus scan.
+	AttachmentScanQueue = "sales.attachment-scans"
+
+	// CustomerErasureQueue receives the customer data erasures the sales
+	// records must take part in.
+	CustomerErasureQueue = "sales.customer-erasure"
+
+	// InsightQueue receives the won opportunities the insights engine
+	// compares against the quarter's other deals.
+	InsightQueue = "sales.insights"
+)
+
+// ConsumerBinding binds the consumer queue to a routing key on an exchange.
+// Routing keys may use topic wildcards, e.g. "sales.opportunity.*".
+type ConsumerBinding struct {
+	Exchange   string
+	RoutingKey string
+}
+
+// RabbitMQConsumerConfig holds RabbitMQ consumer configuration.
+type RabbitMQConsumerConfig struct {
+	URL string
+	// Queue is shared by every instance of the service, so each event is
+	// handled once per service rather than once per instance.
+	Queue          string
+	Bindings       []ConsumerBinding
+	PrefetchCount  int
+	ReconnectDelay time.Duration
+}
+
+// ConsumedEvent is an event received from RabbitMQ. Type is the routing key
+// the event was published with.
+type ConsumedEvent struct {
+	ID          string
+	Type        string
+	TenantID    string
+	AggregateID string
+	Body        []byte
+}
+
+// EventHandler handles a consumed event. A failed event is requeued once and
+// dropped if it fails again, so handlers must tolerate missed events.
+type EventHandler func(ctx context.Context, event ConsumedEvent) error
+
+// RabbitMQConsumer consumes events from other services and the sales service.
+type RabbitMQConsumer struct {
+	config  RabbitMQConsumerConfig
+	conn    *amqp.Connection
+	channel *amqp.Channel
+	mu      sync.Mutex
+	closed  bool
+
+	// tag identifies the consumer to the broker so Shutdown can cancel it.
+	tag     string
+	drainer lifecycle.Drainer
+}
+
+// NewRabbitMQConsumer creates a new RabbitMQ consumer and declares its queue.
+func NewRabbitMQConsumer(config RabbitMQConsumerConfig) (*RabbitMQConsumer, error) {
+	if config.PrefetchCount <= 0 {
+		config.PrefetchCount = 10
+	}
+	if config.ReconnectDelay <= 0 {
+		config.ReconnectDelay = 5 * time.Second
+	}
+
+	consumer := &RabbitMQConsumer{config: config, tag: config.Queue + "-" + uuid.NewString()}
+	if err := consumer.connect(); err != nil {
+		return nil, err
+	}
+	return consumer, nil
+}
+
+// connect opens a channel and declares the queue and its bindings.
+func (c *RabbitMQConsumer) connect() error {
+	conn, err := amqp.Dial(c.config.URL)
+	if err != nil {
+		return fmt.Errorf("failed to connect to RabbitMQ: %w", err)
+	}
+
+	ch, err := conn.Channel()
+	if err != nil {
+		conn.Close()
+		return fmt.Errorf("failed to open channel: %w", err)
+	}
+
+	if err := c.declare(ch); err != nil {
+		ch.Close()
+		conn.Close()
+		return err
+	}
+
+	c.mu.Lock()
+	c.conn = conn
+	c.channel = ch
+	c.mu.Unlock()
+	return nil
+}
+
+func (c *RabbitMQConsumer) declare(ch *amqp.Channel) error {
+	if err := ch.Qos(c.config.PrefetchCount, 0, false); err != nil {
+		return fmt.Errorf("failed to set QoS: %w", err)
+	}
+
+	if _, err := ch.QueueDeclare(
+		c.config.Queue,
+		true,  // durable
+		false, // auto-delete
+		false, // exclusive
+		false, // no-wait
+		nil,
+	); err != nil {
+		return fmt.Errorf("failed to declare queue %s: %w", c.config.Queue, err)
+	}
+
+	for _, binding := range c.config.Bindings {
+		// Exchanges are declared with the publishers' settings so binding works
+		// before the publishing service has started
+		if err := ch.ExchangeDeclare(
+			binding.Exchange,
+			"topic",
+			true,  // durable
+			false, // auto-delete
+			false, // internal
+			false, // no-wait
+			nil,
+		); err != nil {
+			return fmt.Errorf("failed to declare exchange %s: %w", binding.Exchange, err)
+		}
+
+		if err := ch.QueueBind(
+			c.config.Queue,
+			binding.RoutingKey,
+			binding.Exchange,
+			false, // no-wait
+			nil,
+		); err != nil {
+			return fmt.Errorf("failed to bind queue %s to %s: %w", c.config.Queue, binding.RoutingKey, err)
+		}
+	}
+	return nil
+}
+
+// Consume delivers events to handler until ctx is cancelled or the consumer is
+// closed, reconnecting if the connection is lost.
+func (c *RabbitMQConsumer) Consume(ctx context.Context, handler EventHandler) error {
+	deliveries, err := c.deliveries()
+	if err != nil {
+		return err
+	}
+
+	go func() {
+		for {
+			c.handle(ctx, deliveries, handler)
+
+			// The delivery channel closed: stop, or reconnect after a delay
+			for {
+				c.mu.Lock()
+				closed := c.closed
+				c.mu.Unlock()
+				if closed || c.drainer.Stopped() {
+					return
+				}
+
+				select {
+				case <-ctx.Done():
+					return
+				case <-time.After(c.config.ReconnectDelay):
+				}
+
+				if err := c.connect(); err != nil {
+					continue
+				}
+				if deliveries, err = c.deliveries(); err == nil {
+					break
+				}
+			}
+		}
+	}()
+	return nil
+}
+
+func (c *RabbitMQConsumer) deliveries() (<-chan amqp.Delivery, error) {
+	c.mu.Lock()
+	ch := c.channel
+	c.mu.Unlock()
+
+	deliveries, err := ch.Consume(
+		c.config.Queue,
+		c.tag, // consumer
+		false, // auto-ack
+		false, // exclusive
+		false, // no-local
+		false, // no-wait
+		nil,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to start consuming: %w", err)
+	}
+	return deliveries, nil
+}
+
+// handle processes deliveries until the channel closes or ctx is cancelled.
+func (c *RabbitMQConsumer) handle(ctx context.Context, deliveries <-chan amqp.Delivery, handler EventHandler) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case d, ok := <-deliveries:
+			if !ok {
+				return
+			}
+			// Deliveries prefetched after shutdown began go back to the queue
+			if !c.drainer.Begin() {
+				d.Nack(false, true)
+				continue
+			}
+
+			if err := handler(ctx, newConsumedEvent(d)); err != nil {
+				d.Nack(false, !d.Redelivered)
+			} else {
+				d.Ack(false)
+			}
+			c.drainer.End()
+		}
+	}
+}
+
+// newConsumedEvent reads the event envelope from the message headers, falling
+// back to the JSON body for fields the publisher did not set as headers.
+func newConsumedEvent(d amqp.Delivery) ConsumedEvent {
+	event := ConsumedEvent{
+		ID:   d.MessageId,
+		Type: d.RoutingKey,
+		Body: d.Body,
+	}
+	// Replays sent straight to the queue carry the original routing key
+	if v, ok := d.Headers[RoutingKeyHeader].(string); ok && d.Exchange == "" {
+		event.Type = v
+	}
+	if v, ok := d.Headers["tenant_id"].(string); ok {
+		event.TenantID = v
+	}
+	if v, ok := d.Headers["aggregate_id"].(string); ok {
+		event.AggregateID = v
+	}
+
+	if event.TenantID == "" || event.AggregateID == "" {
+		var body struct {
+			TenantID    string `json:"tenant_id"`
+			AggregateID string `json:"aggregate_id"`
+		}
+		if err := json.Unmarshal(d.Body, &body); err == nil {
+			if event.TenantID == "" {
+				event.TenantID = body.TenantID
+			}
+			if event.AggregateID == "" {
+				event.AggregateID = body.AggregateID
+			}
+		}
+	}
+	return event
+}
+
+// Shutdown stops consuming, requeues the deliveries not yet handled and
+// waits for the event being handled before closing the connection.
+func (c *RabbitMQConsumer) Shutdown(ctx context.Context) error {
+	c.drainer.Stop()
+
+	c.mu.Lock()
+	ch := c.channel
+	c.mu.Unlock()
+	if ch != nil {
+		ch.Cancel(c.tag, false)
+	}
+
+	err := c.drainer.Wait(ctx)
+	if closeErr := c.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
+
+// Close closes the consumer connection.
+func (c *RabbitMQConsumer) Close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.closed {
+		return nil
+	}
+	c.closed = true
+
+	if c.channel != nil {
+		c.channel.Close()
+	}
+	if c.conn != nil {
+		return c.conn.Close()
+	}
+	return nil
+}
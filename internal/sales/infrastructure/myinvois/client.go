@@ -0,0 +1,373 @@
+// Package myinvois adapts LHDN's MyInvois e-invoicing API to the sales service.
+package myinvois
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/kilang-desa-murni/crm/internal/sales/application/ports"
+)
+
+// ============================================================================
+// Configuration
+// ============================================================================
+
+// MyInvois environments.
+const (
+	SandboxBaseURL      = "https://preprod-api.myinvois.hasil.gov.my"
+	SandboxPortalURL    = "https://preprod.myinvois.hasil.gov.my"
+	ProductionBaseURL   = "https://api.myinvois.hasil.gov.my"
+	ProductionPortalURL = "https://myinvois.hasil.gov.my"
+)
+
+// Config holds configuration for the MyInvois client. The client signs in as
+// an intermediary and acts on behalf of each tenant's TIN.
+type Config struct {
+	BaseURL string
+	// IdentityURL is the identity service host; it defaults to BaseURL.
+	IdentityURL string
+	// PortalURL is the host of the public validation links.
+	PortalURL    string
+	ClientID     string
+	ClientSecret string
+	Timeout      time.Duration
+}
+
+// SandboxConfig returns the configuration of the pre-production environment.
+func SandboxConfig(clientID, clientSecret string) Config {
+	return Config{
+		BaseURL:      SandboxBaseURL,
+		PortalURL:    SandboxPortalURL,
+		ClientID:     clientID,
+		ClientSecret: clientSecret,
+	}
+}
+
+// ProductionConfig returns the configuration of the production environment.
+func ProductionConfig(clientID, clientSecret string) Config {
+	return Config{
+		BaseURL:      ProductionBaseURL,
+		PortalURL:    ProductionPortalURL,
+		ClientID:     clientID,
+		ClientSecret: clientSecret,
+	}
+}
+
+// ============================================================================
+// Client
+// ============================================================================
+
+// Client submits e-invoices to MyInvois.
+type Client struct {
+	config     Config
+	httpClient *http.Client
+
+	mu     sync.Mutex
+	tokens map[string]accessToken
+}
+
+type accessToken struct {
+	value     string
+	expiresAt time.Time
+}
+
+// NewClient creates a new MyInvois client.
+func NewClient(config Config) *Client {
+	if config.Timeout <= 0 {
+		config.Timeout = 30 * time.Second
+	}
+	if config.IdentityURL == "" {
+		config.IdentityURL = config.BaseURL
+	}
+	config.BaseURL = strings.TrimRight(config.BaseURL, "/")
+	config.IdentityURL = strings.TrimRight(config.IdentityURL, "/")
+	config.PortalURL = strings.TrimRight(config.PortalURL, "/")
+
+	return &Client{
+		config: config,
+		httpClient: &http.Client{
+			Timeout: config.Timeout,
+		},
+		tokens: make(map[string]accessToken),
+	}
+}
+
+// submitRequest is the body of a document submission.
+type submitRequest struct {
+	Documents []submitDocument `json:"documents"`
+}
+
+type submitDocument struct {
+	Format       string `json:"format"`
+	DocumentHash string `json:"documentHash"`
+	CodeNumber   string `json:"codeNumber"`
+	Document     string `json:"document"`
+}
+
+// submitResponse is MyInvois' answer to a document submission.
+type submitResponse struct {
+	SubmissionUID     string `json:"submissionUid"`
+	AcceptedDocuments []struct {
+		UUID              string `json:"uuid"`
+		InvoiceCodeNumber string `json:"invoiceCodeNumber"`
+	} `json:"acceptedDocuments"`
+	RejectedDocuments []struct {
+		InvoiceCodeNumber string   `json:"invoiceCodeNumber"`
+		Error             apiError `json:"error"`
+	} `json:"rejectedDocuments"`
+}
+
+// apiError is a MyInvois error with its nested details.
+type apiError struct {
+	Code    string     `json:"code"`
+	Message string     `json:"message"`
+	Target  string     `json:"target"`
+	Details []apiError `json:"details"`
+}
+
+// messages flattens an error into its most specific messages.
+func (e apiError) messages() []string {
+	if len(e.Details) > 0 {
+		var messages []string
+		for _, detail := range e.Details {
+			messages = append(messages, detail.messages()...)
+		}
+		return messages
+	}
+	message := e.Message
+	if message == "" {
+		message = e.Code
+	}
+	if e.Target != "" {
+		message = e.Target + ": " + message
+	}
+	return []string{message}
+}
+
+// Submit submits an invoice for validation.
+func (c *Client) Submit(ctx context.Context, doc *ports.EInvoiceDocument) (*ports.EInvoiceSubmission, error) {
+	document, err := json.Marshal(buildInvoice(doc))
+	if err != nil {
+		return nil, fmt.Errorf("myinvois: failed to build document: %w", err)
+	}
+	hash := sha256.Sum256(document)
+
+	body := submitRequest{
+		Documents: []submitDocument{{
+			Format:       "JSON",
+			DocumentHash: hex.EncodeToString(hash[:]),
+			CodeNumber:   doc.InvoiceNumber,
+			Document:     base64.StdEncoding.EncodeToString(document),
+		}},
+	}
+
+	var response submitResponse
+	if err := c.do(ctx, doc.Supplier.TIN, http.MethodPost, "/api/v1.0/documentsubmissions/", body, &response); err != nil {
+		return nil, err
+	}
+
+	submission := &ports.EInvoiceSubmission{SubmissionUID: response.SubmissionUID}
+	for _, accepted := range response.AcceptedDocuments {
+		if accepted.InvoiceCodeNumber == doc.InvoiceNumber {
+			submission.DocumentUUID = accepted.UUID
+		}
+	}
+	for _, rejected := range response.RejectedDocuments {
+		submission.Errors = append(submission.Errors, rejected.Error.messages()...)
+	}
+	if submission.DocumentUUID == "" && len(submission.Errors) == 0 {
+		return nil, fmt.Errorf("myinvois: submission %s returned no result for %s", response.SubmissionUID, doc.InvoiceNumber)
+	}
+	return submission, nil
+}
+
+// documentDetails is the validation result of a document.
+type documentDetails struct {
+	UUID              string `json:"uuid"`
+	LongID            string `json:"longId"`
+	Status            string `json:"status"`
+	ValidationResults struct {
+		ValidationSteps []struct {
+			Name   string   `json:"name"`
+			Status string   `json:"status"`
+			Error  apiError `json:"error"`
+		} `json:"validationSteps"`
+	} `json:"validationResults"`
+}
+
+// GetDocument returns the validation result of a submitted document.
+func (c *Client) GetDocument(ctx context.Context, supplierTIN, documentUUID string) (*ports.EInvoiceDocumentResult, error) {
+	var details documentDetails
+	path := "/api/v1.0/documents/" + url.PathEscape(documentUUID) + "/details"
+	if err := c.do(ctx, supplierTIN, http.MethodGet, path, nil, &details); err != nil {
+		return nil, err
+	}
+
+	result := &ports.EInvoiceDocumentResult{
+		Status: strings.ToLower(details.Status),
+		LongID: details.LongID,
+	}
+	if result.LongID != "" {
+		result.ValidationURL = c.config.PortalURL + "/" + documentUUID + "/share/" + result.LongID
+	}
+	for _, step := range details.ValidationResults.ValidationSteps {
+		if !strings.EqualFold(step.Status, "invalid") {
+			continue
+		}
+		messages := step.Error.messages()
+		if len(messages) == 1 && messages[0] == "" {
+			messages = []string{step.Name}
+		}
+		result.Errors = append(result.Errors, messages...)
+	}
+	return result, nil
+}
+
+// Cancel cancels a valid document.
+func (c *Client) Cancel(ctx context.Context, supplierTIN, documentUUID, reason string) error {
+	body := map[string]string{
+		"status": "cancelled",
+		"reason": reason,
+	}
+	path := "/api/v1.0/documents/state/" + url.PathEscape(documentUUID) + "/state"
+	return c.do(ctx, supplierTIN, http.MethodPut, path, body, nil)
+}
+
+// do sends an API request on behalf of a taxpayer and decodes the response.
+func (c *Client) do(ctx context.Context, tin, method, path string, body, result interface{}) error {
+	token, err := c.token(ctx, tin)
+	if err != nil {
+		return err
+	}
+
+	var reader io.Reader
+	if body != nil {
+		payload, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("myinvois: failed to encode request: %w", err)
+		}
+		reader = bytes.NewReader(payload)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, c.config.BaseURL+path, reader)
+	if err != nil {
+		return fmt.Errorf("myinvois: failed to create request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("Authorization", "Bearer "+token)
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("myinvois: request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return fmt.Errorf("myinvois: failed to read response: %w", err)
+	}
+
+	if resp.StatusCode == http.StatusUnauthorized {
+		c.forgetToken(tin)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		var failure struct {
+			Error apiError `json:"error"`
+		}
+		if json.Unmarshal(data, &failure) == nil && (failure.Error.Code != "" || failure.Error.Message != "") {
+			return fmt.Errorf("myinvois: %s %s: status %d: %s", method, path, resp.StatusCode, strings.Join(failure.Error.messages(), "; "))
+		}
+		return fmt.Errorf("myinvois: %s %s: unexpected status %d", method, path, resp.StatusCode)
+	}
+
+	if result == nil || len(data) == 0 {
+		return nil
+	}
+	if err := json.Unmarshal(data, result); err != nil {
+		return fmt.Errorf("myinvois: failed to parse response: %w", err)
+	}
+	return nil
+}
+
+// token returns an access token for acting on behalf of a taxpayer,
+// signing in again shortly before the cached one expires.
+func (c *Client) token(ctx context.Context, tin string) (string, error) {
+	c.mu.Lock()
+	cached, ok := c.tokens[tin]
+	c.mu.Unlock()
+	if ok && time.Now().Before(cached.expiresAt) {
+		return cached.value, nil
+	}
+
+	form := url.Values{}
+	form.Set("client_id", c.config.ClientID)
+	form.Set("client_secret", c.config.ClientSecret)
+	form.Set("grant_type", "client_credentials")
+	form.Set("scope", "InvoicingAPI")
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.config.IdentityURL+"/connect/token", strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", fmt.Errorf("myinvois: failed to create token request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	if tin != "" {
+		req.Header.Set("onbehalfof", tin)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("myinvois: token request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("myinvois: sign in on behalf of %s failed with status %d", tin, resp.StatusCode)
+	}
+
+	var response struct {
+		AccessToken string `json:"access_token"`
+		ExpiresIn   int    `json:"expires_in"`
+	}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, 1<<20)).Decode(&response); err != nil {
+		return "", fmt.Errorf("myinvois: failed to parse token: %w", err)
+	}
+	if response.AccessToken == "" {
+		return "", fmt.Errorf("myinvois: sign in on behalf of %s returned no token", tin)
+	}
+
+	lifetime := time.Duration(response.ExpiresIn) * time.Second
+	if lifetime > time.Minute {
+		lifetime -= time.Minute
+	}
+
+	c.mu.Lock()
+	c.tokens[tin] = accessToken{value: response.AccessToken, expiresAt: time.Now().Add(lifetime)}
+	c.mu.Unlock()
+
+	return response.AccessToken, nil
+}
+
+// forgetToken drops a token MyInvois no longer accepts.
+func (c *Client) forgetToken(tin string) {
+	c.mu.Lock()
+	delete(c.tokens, tin)
+	c.mu.Unlock()
+}
+
+// Ensure Client implements ports.EInvoiceService
+var _ ports.EInvoiceService = (*Client)(nil)
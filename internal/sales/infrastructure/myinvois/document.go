@@ -0,0 +1,184 @@
+package myinvois
+
+import (
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/kilang-desa-murni/crm/internal/sales/application/ports"
+)
+
+// ============================================================================
+// UBL 2.1 JSON Document
+// ============================================================================
+
+// MyInvois takes UBL 2.1 invoices in JSON form, in which every element is an
+// array of objects and every value is held in the "_" member next to its
+// attributes. Documents are submitted as version 1.0, which needs no digital
+// signature.
+
+const (
+	ublInvoiceNamespace   = "urn:oasis:names:specification:ubl:schema:xsd:Invoice-2"
+	ublAggregateNamespace = "urn:oasis:names:specification:ubl:schema:xsd:CommonAggregateComponents-2"
+	ublBasicNamespace     = "urn:oasis:names:specification:ubl:schema:xsd:CommonBasicComponents-2"
+
+	invoiceTypeCode    = "01"
+	invoiceTypeVersion = "1.0"
+	notApplicable      = "NA"
+)
+
+// node is a UBL JSON element.
+type node = map[string]interface{}
+
+// value wraps a value as a UBL JSON element with optional attribute pairs.
+func value(v interface{}, attributes ...string) []node {
+	element := node{"_": v}
+	for i := 0; i+1 < len(attributes); i += 2 {
+		element[attributes[i]] = attributes[i+1]
+	}
+	return []node{element}
+}
+
+// amount wraps an amount in the smallest currency unit as a UBL amount.
+func amount(minor int64, currency string) []node {
+	return value(float64(minor)/100, "currencyID", currency)
+}
+
+// orNA returns NA for blank optional values, as MyInvois expects.
+func orNA(s string) string {
+	if strings.TrimSpace(s) == "" {
+		return notApplicable
+	}
+	return s
+}
+
+// buildInvoice builds the UBL JSON document of an invoice.
+func buildInvoice(doc *ports.EInvoiceDocument) node {
+	issuedAt := doc.IssuedAt.UTC()
+
+	supplier := buildParty(doc.Supplier)
+	supplier["IndustryClassificationCode"] = value(doc.Supplier.MSICCode, "name", doc.Supplier.BusinessActivity)
+
+	lines := make([]node, len(doc.Lines))
+	for i, line := range doc.Lines {
+		lines[i] = buildLine(i+1, line, doc.Currency)
+	}
+
+	invoice := node{
+		"ID":                   value(doc.InvoiceNumber),
+		"IssueDate":            value(issuedAt.Format("2006-01-02")),
+		"IssueTime":            value(issuedAt.Format("15:04:05Z")),
+		"InvoiceTypeCode":      value(invoiceTypeCode, "listVersionID", invoiceTypeVersion),
+		"DocumentCurrencyCode": value(doc.Currency),
+		"TaxCurrencyCode":      value(doc.Currency),
+		"AccountingSupplierParty": []node{{
+			"Party": []node{supplier},
+		}},
+		"AccountingCustomerParty": []node{{
+			"Party": []node{buildParty(doc.Buyer)},
+		}},
+		"TaxTotal": buildTaxTotal(doc.TaxAmount, doc.Taxes, doc.Currency),
+		"LegalMonetaryTotal": []node{{
+			"LineExtensionAmount": amount(doc.Subtotal, doc.Currency),
+			"TaxExclusiveAmount":  amount(doc.Subtotal, doc.Currency),
+			"TaxInclusiveAmount":  amount(doc.Total, doc.Currency),
+			"PayableAmount":       amount(doc.Total, doc.Currency),
+		}},
+		"InvoiceLine": lines,
+	}
+
+	return node{
+		"_D":      ublInvoiceNamespace,
+		"_A":      ublAggregateNamespace,
+		"_B":      ublBasicNamespace,
+		"Invoice": []node{invoice},
+	}
+}
+
+// buildParty builds the supplier or buyer party of an invoice.
+func buildParty(party ports.EInvoiceParty) node {
+	address := node{
+		"CityName":             value(party.Address.City),
+		"PostalZone":           value(party.Address.Postcode),
+		"CountrySubentityCode": value(party.Address.State),
+		"AddressLine": []node{
+			{"Line": value(party.Address.Line1)},
+		},
+		"Country": []node{{
+			"IdentificationCode": value(party.Address.Country, "listID", "ISO3166-1", "listAgencyID", "6"),
+		}},
+	}
+	if party.Address.Line2 != "" {
+		address["AddressLine"] = append(address["AddressLine"].([]node), node{"Line": value(party.Address.Line2)})
+	}
+
+	return node{
+		"PartyIdentification": []node{
+			{"ID": value(party.TIN, "schemeID", "TIN")},
+			{"ID": value(party.RegistrationNumber, "schemeID", "BRN")},
+			{"ID": value(orNA(party.SSTNumber), "schemeID", "SST")},
+		},
+		"PostalAddress": []node{address},
+		"PartyLegalEntity": []node{{
+			"RegistrationName": value(party.Name),
+		}},
+		"Contact": []node{{
+			"Telephone":      value(orNA(party.Phone)),
+			"ElectronicMail": value(orNA(party.Email)),
+		}},
+	}
+}
+
+// buildLine builds an invoice line.
+func buildLine(number int, line ports.EInvoiceLine, currency string) node {
+	quantity := line.Quantity
+	if quantity <= 0 {
+		quantity = 1
+	}
+
+	return node{
+		"ID":                  value(strconv.Itoa(number)),
+		"InvoicedQuantity":    value(quantity, "unitCode", "C62"),
+		"LineExtensionAmount": amount(line.Subtotal, currency),
+		"TaxTotal":            buildTaxTotal(line.TaxAmount, line.Taxes, currency),
+		"Item": []node{{
+			"CommodityClassification": []node{{
+				"ItemClassificationCode": value(line.ClassificationCode, "listID", "CLASS"),
+			}},
+			"Description": value(line.Description),
+		}},
+		"Price": []node{{
+			"PriceAmount": amount(line.UnitPrice, currency),
+		}},
+		"ItemPriceExtension": []node{{
+			"Amount": amount(line.Subtotal, currency),
+		}},
+	}
+}
+
+// buildTaxTotal builds the tax total of an invoice or line.
+func buildTaxTotal(total int64, taxes []ports.EInvoiceTax, currency string) []node {
+	subtotals := make([]node, len(taxes))
+	for i, tax := range taxes {
+		category := node{
+			"ID":      value(tax.TaxType),
+			"Percent": value(math.Round(tax.Rate*100) / 100),
+			"TaxScheme": []node{{
+				"ID": value("OTH", "schemeID", "UN/ECE 5153", "schemeAgencyID", "6"),
+			}},
+		}
+		if tax.ExemptionReason != "" {
+			category["TaxExemptionReason"] = value(tax.ExemptionReason)
+		}
+		subtotals[i] = node{
+			"TaxableAmount": amount(tax.TaxableAmount, currency),
+			"TaxAmount":     amount(tax.TaxAmount, currency),
+			"TaxCategory":   []node{category},
+		}
+	}
+
+	return []node{{
+		"TaxAmount":   amount(total, currency),
+		"TaxSubtotal": subtotals,
+	}}
+}
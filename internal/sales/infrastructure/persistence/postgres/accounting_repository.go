@@ -0,0 +1,443 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+
+	"github.com/kilang-desa-murni/crm/internal/sales/domain"
+)
+
+// ============================================================================
+// Accounting Connection Repository
+// ============================================================================
+
+// AccountingConnectionRepository implements domain.AccountingConnectionRepository for PostgreSQL.
+type AccountingConnectionRepository struct {
+	db *sqlx.DB
+}
+
+// NewAccountingConnectionRepository creates a new AccountingConnectionRepository.
+func NewAccountingConnectionRepository(db *sqlx.DB) *AccountingConnectionRepository {
+	return &AccountingConnectionRepository{db: db}
+}
+
+// accountingConnectionRow is the database representation of an accounting connection.
+type accountingConnectionRow struct {
+	TenantID  uuid.UUID    `db:"tenant_id"`
+	Provider  string       `db:"provider"`
+	URL       string       `db:"url"`
+	Secret    string       `db:"secret"`
+	Enabled   bool         `db:"enabled"`
+	Mapping   NullableJSON `db:"mapping"`
+	CreatedAt time.Time    `db:"created_at"`
+	UpdatedAt time.Time    `db:"updated_at"`
+}
+
+const accountingConnectionColumns = `
+	tenant_id, provider, url, secret, enabled, mapping, created_at, updated_at`
+
+// Get retrieves a tenant's accounting connection, returning nil if none is configured.
+func (r *AccountingConnectionRepository) Get(ctx context.Context, tenantID uuid.UUID) (*domain.AccountingConnection, error) {
+	exec := getExecutor(ctx, r.db)
+
+	query := `SELECT ` + accountingConnectionColumns + `
+		FROM sales.accounting_connections
+		WHERE tenant_id = $1`
+
+	var row accountingConnectionRow
+	if err := sqlx.GetContext(ctx, exec, &row, query, tenantID); err != nil {
+		if IsNotFoundError(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get accounting connection: %w", err)
+	}
+
+	return row.toDomain()
+}
+
+// Upsert creates or updates a tenant's accounting connection.
+func (r *AccountingConnectionRepository) Upsert(ctx context.Context, connection *domain.AccountingConnection) error {
+	exec := getExecutor(ctx, r.db)
+
+	mappingJSON, err := ToJSON(connection.Mapping)
+	if err != nil {
+		return fmt.Errorf("failed to marshal accounting mapping: %w", err)
+	}
+
+	query := `
+		INSERT INTO sales.accounting_connections (` + accountingConnectionColumns + `)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		ON CONFLICT (tenant_id) DO UPDATE SET
+			provider = EXCLUDED.provider,
+			url = EXCLUDED.url,
+			secret = EXCLUDED.secret,
+			enabled = EXCLUDED.enabled,
+			mapping = EXCLUDED.mapping,
+			updated_at = EXCLUDED.updated_at`
+
+	_, err = exec.ExecContext(ctx, query,
+		connection.TenantID, string(connection.Provider), connection.URL, connection.Secret,
+		connection.Enabled, mappingJSON, connection.CreatedAt, connection.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to upsert accounting connection: %w", err)
+	}
+
+	return nil
+}
+
+// Delete removes a tenant's accounting connection.
+func (r *AccountingConnectionRepository) Delete(ctx context.Context, tenantID uuid.UUID) error {
+	exec := getExecutor(ctx, r.db)
+
+	if _, err := exec.ExecContext(ctx, `DELETE FROM sales.accounting_connections WHERE tenant_id = $1`, tenantID); err != nil {
+		return fmt.Errorf("failed to delete accounting connection: %w", err)
+	}
+
+	return nil
+}
+
+// ListEnabled retrieves the enabled accounting connections of all tenants.
+func (r *AccountingConnectionRepository) ListEnabled(ctx context.Context) ([]*domain.AccountingConnection, error) {
+	exec := getExecutor(ctx, r.db)
+
+	query := `SELECT ` + accountingConnectionColumns + `
+		FROM sales.accounting_connections
+		WHERE enabled
+		ORDER BY tenant_id`
+
+	var rows []accountingConnectionRow
+	if err := sqlx.SelectContext(ctx, exec, &rows, query); err != nil {
+		return nil, fmt.Errorf("failed to list accounting connections: %w", err)
+	}
+
+	connections := make([]*domain.AccountingConnection, len(rows))
+	for i := range rows {
+		connection, err := rows[i].toDomain()
+		if err != nil {
+			return nil, err
+		}
+		connections[i] = connection
+	}
+	return connections, nil
+}
+
+func (row *accountingConnectionRow) toDomain() (*domain.AccountingConnection, error) {
+	connection := &domain.AccountingConnection{
+		TenantID:  row.TenantID,
+		Provider:  domain.AccountingProvider(row.Provider),
+		URL:       row.URL,
+		Secret:    row.Secret,
+		Enabled:   row.Enabled,
+		CreatedAt: row.CreatedAt,
+		UpdatedAt: row.UpdatedAt,
+	}
+	if err := row.Mapping.MarshalTo(&connection.Mapping); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal accounting mapping: %w", err)
+	}
+	return connection, nil
+}
+
+// ============================================================================
+// Accounting Sync Repository
+// ============================================================================
+
+// AccountingSyncRepository implements domain.AccountingSyncRepository for PostgreSQL.
+type AccountingSyncRepository struct {
+	db *sqlx.DB
+}
+
+// NewAccountingSyncRepository creates a new AccountingSyncRepository.
+func NewAccountingSyncRepository(db *sqlx.DB) *AccountingSyncRepository {
+	return &AccountingSyncRepository{db: db}
+}
+
+// accountingSyncRow is the database representation of an accounting sync.
+type accountingSyncRow struct {
+	ID            uuid.UUID  `db:"id"`
+	TenantID      uuid.UUID  `db:"tenant_id"`
+	DealID        uuid.UUID  `db:"deal_id"`
+	RecordType    string     `db:"record_type"`
+	RecordID      uuid.UUID  `db:"record_id"`
+	Reference     string     `db:"reference"`
+	Amount        int64      `db:"amount"`
+	Currency      string     `db:"currency"`
+	Status        string     `db:"status"`
+	ExternalID    string     `db:"external_id"`
+	Attempts      int        `db:"attempts"`
+	NextAttemptAt *time.Time `db:"next_attempt_at"`
+	LastError     string     `db:"last_error"`
+	SyncedAt      *time.Time `db:"synced_at"`
+	CreatedAt     time.Time  `db:"created_at"`
+	UpdatedAt     time.Time  `db:"updated_at"`
+	Version       int        `db:"version"`
+}
+
+const accountingSyncColumns = `
+	id, tenant_id, deal_id, record_type, record_id, reference, amount, currency,
+	status, external_id, attempts, next_attempt_at, last_error, synced_at,
+	created_at, updated_at, version`
+
+// Create creates a new accounting sync.
+func (r *AccountingSyncRepository) Create(ctx context.Context, sync *domain.AccountingSync) error {
+	exec := getExecutor(ctx, r.db)
+
+	query := `
+		INSERT INTO sales.accounting_syncs (` + accountingSyncColumns + `)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17)`
+
+	_, err := exec.ExecContext(ctx, query,
+		sync.ID, sync.TenantID, sync.DealID, string(sync.RecordType), sync.RecordID,
+		sync.Reference, sync.Amount.Amount, sync.Amount.Currency, string(sync.Status),
+		sync.ExternalID, sync.Attempts, sync.NextAttemptAt, sync.LastError,
+		sync.SyncedAt, sync.CreatedAt, sync.UpdatedAt, sync.Version,
+	)
+	if err != nil {
+		if IsUniqueViolation(err) {
+			return domain.ErrAccountingSyncAlreadyExists
+		}
+		return fmt.Errorf("failed to create accounting sync: %w", err)
+	}
+
+	return nil
+}
+
+// GetByID retrieves an accounting sync by ID.
+func (r *AccountingSyncRepository) GetByID(ctx context.Context, tenantID, syncID uuid.UUID) (*domain.AccountingSync, error) {
+	return r.getOne(ctx, `tenant_id = $1 AND id = $2`, domain.ErrAccountingSyncNotFound, tenantID, syncID)
+}
+
+// Update updates an accounting sync, checking the version it was loaded at.
+func (r *AccountingSyncRepository) Update(ctx context.Context, sync *domain.AccountingSync) error {
+	exec := getExecutor(ctx, r.db)
+
+	query := `
+		UPDATE sales.accounting_syncs SET
+			status = $3, external_id = $4, attempts = $5, next_attempt_at = $6,
+			last_error = $7, synced_at = $8, updated_at = $9, version = $10
+		WHERE tenant_id = $1 AND id = $2 AND version = $10 - 1`
+
+	result, err := exec.ExecContext(ctx, query,
+		sync.TenantID, sync.ID, string(sync.Status), sync.ExternalID,
+		sync.Attempts, sync.NextAttemptAt, sync.LastError, sync.SyncedAt,
+		sync.UpdatedAt, sync.Version,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update accounting sync: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return domain.ErrAccountingSyncNotFound
+	}
+
+	return nil
+}
+
+// GetByRecord retrieves the sync of an invoice or payment, returning nil if it has none.
+func (r *AccountingSyncRepository) GetByRecord(ctx context.Context, tenantID uuid.UUID, recordType domain.AccountingRecordType, recordID uuid.UUID) (*domain.AccountingSync, error) {
+	return r.getOne(ctx, `tenant_id = $1 AND record_type = $2 AND record_id = $3`, nil, tenantID, string(recordType), recordID)
+}
+
+// GetByExternalID retrieves the sync of a record by its accounting system ID,
+// returning nil if there is none.
+func (r *AccountingSyncRepository) GetByExternalID(ctx context.Context, tenantID uuid.UUID, recordType domain.AccountingRecordType, externalID string) (*domain.AccountingSync, error) {
+	return r.getOne(ctx, `tenant_id = $1 AND record_type = $2 AND external_id = $3`, nil, tenantID, string(recordType), externalID)
+}
+
+// List retrieves a tenant's accounting syncs in the given statuses, most
+// recently updated first.
+func (r *AccountingSyncRepository) List(ctx context.Context, tenantID uuid.UUID, statuses []domain.AccountingSyncStatus, limit int) ([]*domain.AccountingSync, error) {
+	exec := getExecutor(ctx, r.db)
+
+	statusValues := make([]string, len(statuses))
+	for i, status := range statuses {
+		statusValues[i] = string(status)
+	}
+
+	query := `SELECT ` + accountingSyncColumns + `
+		FROM sales.accounting_syncs
+		WHERE tenant_id = $1
+		  AND (cardinality($2::text[]) = 0 OR status = ANY($2))
+		ORDER BY updated_at DESC, id
+		LIMIT $3`
+
+	var rows []accountingSyncRow
+	if err := sqlx.SelectContext(ctx, exec, &rows, query, tenantID, pq.Array(statusValues), limit); err != nil {
+		return nil, fmt.Errorf("failed to list accounting syncs: %w", err)
+	}
+
+	return accountingSyncsToDomain(rows), nil
+}
+
+// CountByStatus counts a tenant's accounting syncs by status.
+func (r *AccountingSyncRepository) CountByStatus(ctx context.Context, tenantID uuid.UUID) (map[domain.AccountingSyncStatus]int, error) {
+	exec := getExecutor(ctx, r.db)
+
+	query := `
+		SELECT status, COUNT(*) AS count
+		FROM sales.accounting_syncs
+		WHERE tenant_id = $1
+		GROUP BY status`
+
+	var rows []struct {
+		Status string `db:"status"`
+		Count  int    `db:"count"`
+	}
+	if err := sqlx.SelectContext(ctx, exec, &rows, query, tenantID); err != nil {
+		return nil, fmt.Errorf("failed to count accounting syncs: %w", err)
+	}
+
+	counts := make(map[domain.AccountingSyncStatus]int, len(rows))
+	for _, row := range rows {
+		counts[domain.AccountingSyncStatus(row.Status)] = row.Count
+	}
+	return counts, nil
+}
+
+// GetDue retrieves pending accounting syncs of all tenants that are due a
+// push, earliest first.
+func (r *AccountingSyncRepository) GetDue(ctx context.Context, dueBy time.Time, limit int) ([]*domain.AccountingSync, error) {
+	exec := getExecutor(ctx, r.db)
+
+	query := `SELECT ` + accountingSyncColumns + `
+		FROM sales.accounting_syncs
+		WHERE status = 'pending'
+		  AND next_attempt_at <= $1
+		ORDER BY next_attempt_at, id
+		LIMIT $2`
+
+	var rows []accountingSyncRow
+	if err := sqlx.SelectContext(ctx, exec, &rows, query, dueBy, limit); err != nil {
+		return nil, fmt.Errorf("failed to get accounting syncs due: %w", err)
+	}
+
+	return accountingSyncsToDomain(rows), nil
+}
+
+// FindUnsynced retrieves a tenant's issued invoices and payments that have
+// no accounting sync, oldest first, invoices before payments of the same time.
+func (r *AccountingSyncRepository) FindUnsynced(ctx context.Context, tenantID uuid.UUID, limit int) ([]*domain.UnsyncedAccountingRecord, error) {
+	exec := getExecutor(ctx, r.db)
+
+	query := `
+		SELECT deal_id, deal_code, record_type, record_id, reference, amount, currency, date
+		FROM (
+			SELECT d.id AS deal_id, d.code AS deal_code, 'invoice' AS record_type,
+				i.id AS record_id, i.invoice_number AS reference, i.amount, i.currency,
+				i.created_at AS date, 0 AS type_order
+			FROM sales.deal_invoices i
+			JOIN sales.deals d ON d.id = i.deal_id AND d.tenant_id = i.tenant_id
+			LEFT JOIN sales.accounting_syncs s
+				ON s.tenant_id = i.tenant_id AND s.record_type = 'invoice' AND s.record_id = i.id
+			WHERE i.tenant_id = $1
+				AND i.status IN ('sent', 'paid', 'overdue')
+				AND d.deleted_at IS NULL
+				AND s.id IS NULL
+			UNION ALL
+			SELECT d.id, d.code, 'payment',
+				p.id, COALESCE(NULLIF(p.reference, ''), p.id::text), p.amount, p.currency,
+				p.received_at, 1
+			FROM sales.deal_payments p
+			JOIN sales.deals d ON d.id = p.deal_id AND d.tenant_id = p.tenant_id
+			LEFT JOIN sales.accounting_syncs s
+				ON s.tenant_id = p.tenant_id AND s.record_type = 'payment' AND s.record_id = p.id
+			WHERE p.tenant_id = $1
+				AND d.deleted_at IS NULL
+				AND s.id IS NULL
+		) unsynced
+		ORDER BY date, type_order, record_id
+		LIMIT $2`
+
+	var rows []struct {
+		DealID     uuid.UUID `db:"deal_id"`
+		DealCode   string    `db:"deal_code"`
+		RecordType string    `db:"record_type"`
+		RecordID   uuid.UUID `db:"record_id"`
+		Reference  string    `db:"reference"`
+		Amount     int64     `db:"amount"`
+		Currency   string    `db:"currency"`
+		Date       time.Time `db:"date"`
+	}
+	if err := sqlx.SelectContext(ctx, exec, &rows, query, tenantID, limit); err != nil {
+		return nil, fmt.Errorf("failed to find unsynced accounting records: %w", err)
+	}
+
+	records := make([]*domain.UnsyncedAccountingRecord, len(rows))
+	for i, row := range rows {
+		records[i] = &domain.UnsyncedAccountingRecord{
+			DealID:     row.DealID,
+			DealCode:   row.DealCode,
+			RecordType: domain.AccountingRecordType(row.RecordType),
+			RecordID:   row.RecordID,
+			Reference:  row.Reference,
+			Amount:     domain.Money{Amount: row.Amount, Currency: row.Currency},
+			Date:       row.Date,
+		}
+	}
+	return records, nil
+}
+
+// getOne retrieves the accounting sync matching the condition, returning
+// notFound if there is none.
+func (r *AccountingSyncRepository) getOne(ctx context.Context, condition string, notFound error, args ...interface{}) (*domain.AccountingSync, error) {
+	exec := getExecutor(ctx, r.db)
+
+	query := `SELECT ` + accountingSyncColumns + `
+		FROM sales.accounting_syncs
+		WHERE ` + condition
+
+	var row accountingSyncRow
+	if err := sqlx.GetContext(ctx, exec, &row, query, args...); err != nil {
+		if IsNotFoundError(err) {
+			return nil, notFound
+		}
+		return nil, fmt.Errorf("failed to get accounting sync: %w", err)
+	}
+
+	return row.toDomain(), nil
+}
+
+func accountingSyncsToDomain(rows []accountingSyncRow) []*domain.AccountingSync {
+	syncs := make([]*domain.AccountingSync, len(rows))
+	for i := range rows {
+		syncs[i] = rows[i].toDomain()
+	}
+	return syncs
+}
+
+func (row *accountingSyncRow) toDomain() *domain.AccountingSync {
+	return &domain.AccountingSync{
+		ID:            row.ID,
+		TenantID:      row.TenantID,
+		DealID:        row.DealID,
+		RecordType:    domain.AccountingRecordType(row.RecordType),
+		RecordID:      row.RecordID,
+		Reference:     row.Reference,
+		Amount:        domain.Money{Amount: row.Amount, Currency: row.Currency},
+		Status:        domain.AccountingSyncStatus(row.Status),
+		ExternalID:    row.ExternalID,
+		Attempts:      row.Attempts,
+		NextAttemptAt: row.NextAttemptAt,
+		LastError:     row.LastError,
+		SyncedAt:      row.SyncedAt,
+		CreatedAt:     row.CreatedAt,
+		UpdatedAt:     row.UpdatedAt,
+		Version:       row.Version,
+	}
+}
+
+// Ensure the repositories implement their domain interfaces
+var (
+	_ domain.AccountingConnectionRepository = (*AccountingConnectionRepository)(nil)
+	_ domain.AccountingSyncRepository       = (*AccountingSyncRepository)(nil)
+)
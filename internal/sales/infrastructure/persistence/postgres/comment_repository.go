@@ -0,0 +1,302 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+
+	"github.com/kilang-desa-murni/crm/internal/sales/domain"
+)
+
+// CommentRepository implements domain.CommentRepository for PostgreSQL.
+type CommentRepository struct {
+	db *sqlx.DB
+}
+
+// NewCommentRepository creates a new CommentRepository.
+func NewCommentRepository(db *sqlx.DB) *CommentRepository {
+	return &CommentRepository{db: db}
+}
+
+// commentRow is the database representation of a comment.
+type commentRow struct {
+	ID         uuid.UUID     `db:"id"`
+	TenantID   uuid.UUID     `db:"tenant_id"`
+	EntityType string        `db:"entity_type"`
+	EntityID   uuid.UUID     `db:"entity_id"`
+	ParentID   uuid.NullUUID `db:"parent_id"`
+	AuthorID   uuid.UUID     `db:"author_id"`
+	Body       string        `db:"body"`
+	Mentions   UUIDArray     `db:"mentions"`
+	Revisions  NullableJSON  `db:"revisions"`
+	EditedAt   *time.Time    `db:"edited_at"`
+	DeletedAt  *time.Time    `db:"deleted_at"`
+	DeletedBy  uuid.NullUUID `db:"deleted_by"`
+	CreatedAt  time.Time     `db:"created_at"`
+	UpdatedAt  time.Time     `db:"updated_at"`
+	Version    int           `db:"version"`
+	ReplyCount int64         `db:"reply_count"`
+}
+
+const commentColumns = `
+	c.id, c.tenant_id, c.entity_type, c.entity_id, c.parent_id, c.author_id, c.body,
+	c.mentions, c.revisions, c.edited_at, c.deleted_at, c.deleted_by, c.created_at,
+	c.updated_at, c.version,
+	(SELECT COUNT(*) FROM sales.comments r WHERE r.parent_id = c.id) AS reply_count`
+
+// Create creates a new comment.
+func (r *CommentRepository) Create(ctx context.Context, comment *domain.Comment) error {
+	exec := getExecutor(ctx, r.db)
+
+	revisionsJSON, err := ToJSON(comment.Revisions)
+	if err != nil {
+		return fmt.Errorf("failed to marshal comment revisions: %w", err)
+	}
+
+	query := `
+		INSERT INTO sales.comments (
+			id, tenant_id, entity_type, entity_id, parent_id, author_id, body,
+			mentions, revisions, created_at, updated_at, version
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)`
+
+	_, err = exec.ExecContext(ctx, query,
+		comment.ID, comment.TenantID, string(comment.EntityType), comment.EntityID, nullUUID(comment.ParentID),
+		comment.AuthorID, comment.Body, pq.Array(comment.Mentions), revisionsJSON,
+		comment.CreatedAt, comment.UpdatedAt, comment.Version,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create comment: %w", err)
+	}
+
+	return nil
+}
+
+// Update updates an edited or deleted comment, if it is still at the version
+// before the change.
+func (r *CommentRepository) Update(ctx context.Context, comment *domain.Comment) error {
+	exec := getExecutor(ctx, r.db)
+
+	revisionsJSON, err := ToJSON(comment.Revisions)
+	if err != nil {
+		return fmt.Errorf("failed to marshal comment revisions: %w", err)
+	}
+
+	query := `
+		UPDATE sales.comments SET
+			body = $3, mentions = $4, revisions = $5, edited_at = $6, deleted_at = $7,
+			deleted_by = $8, updated_at = $9, version = $10
+		WHERE tenant_id = $1 AND id = $2 AND version = $10 - 1`
+
+	result, err := exec.ExecContext(ctx, query,
+		comment.TenantID, comment.ID, comment.Body, pq.Array(comment.Mentions), revisionsJSON,
+		comment.EditedAt, comment.DeletedAt, nullUUID(comment.DeletedBy), comment.UpdatedAt, comment.Version,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update comment: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return domain.ErrCommentModified
+	}
+
+	return nil
+}
+
+// GetByID retrieves a comment by ID.
+func (r *CommentRepository) GetByID(ctx context.Context, tenantID, commentID uuid.UUID) (*domain.Comment, error) {
+	exec := getExecutor(ctx, r.db)
+
+	query := `SELECT ` + commentColumns + `
+		FROM sales.comments c
+		WHERE c.tenant_id = $1 AND c.id = $2`
+
+	var row commentRow
+	if err := sqlx.GetContext(ctx, exec, &row, query, tenantID, commentID); err != nil {
+		if IsNotFoundError(err) {
+			return nil, domain.ErrCommentNotFound
+		}
+		return nil, fmt.Errorf("failed to get comment: %w", err)
+	}
+
+	comments, err := r.withReactions(ctx, tenantID, []commentRow{row})
+	if err != nil {
+		return nil, err
+	}
+	return comments[0], nil
+}
+
+// ListThreads lists the top-level comments on a record, newest first.
+func (r *CommentRepository) ListThreads(ctx context.Context, tenantID uuid.UUID, entityType domain.CommentEntityType, entityID uuid.UUID, opts domain.ListOptions) ([]*domain.Comment, int64, error) {
+	exec := getExecutor(ctx, r.db)
+
+	where := `WHERE c.tenant_id = $1 AND c.entity_type = $2 AND c.entity_id = $3 AND c.parent_id IS NULL`
+
+	var total int64
+	if err := sqlx.GetContext(ctx, exec, &total, `SELECT COUNT(*) FROM sales.comments c `+where, tenantID, string(entityType), entityID); err != nil {
+		return nil, 0, fmt.Errorf("failed to count comments: %w", err)
+	}
+
+	query := `SELECT ` + commentColumns + `
+		FROM sales.comments c ` + where + `
+		ORDER BY c.created_at DESC, c.id DESC
+		LIMIT $4 OFFSET $5`
+
+	var rows []commentRow
+	if err := sqlx.SelectContext(ctx, exec, &rows, query, tenantID, string(entityType), entityID, opts.Limit(), opts.Offset()); err != nil {
+		return nil, 0, fmt.Errorf("failed to list comments: %w", err)
+	}
+
+	comments, err := r.withReactions(ctx, tenantID, rows)
+	if err != nil {
+		return nil, 0, err
+	}
+	return comments, total, nil
+}
+
+// ListReplies lists the replies of a thread, oldest first.
+func (r *CommentRepository) ListReplies(ctx context.Context, tenantID, threadID uuid.UUID, opts domain.ListOptions) ([]*domain.Comment, int64, error) {
+	exec := getExecutor(ctx, r.db)
+
+	where := `WHERE c.tenant_id = $1 AND c.parent_id = $2`
+
+	var total int64
+	if err := sqlx.GetContext(ctx, exec, &total, `SELECT COUNT(*) FROM sales.comments c `+where, tenantID, threadID); err != nil {
+		return nil, 0, fmt.Errorf("failed to count comment replies: %w", err)
+	}
+
+	query := `SELECT ` + commentColumns + `
+		FROM sales.comments c ` + where + `
+		ORDER BY c.created_at, c.id
+		LIMIT $3 OFFSET $4`
+
+	var rows []commentRow
+	if err := sqlx.SelectContext(ctx, exec, &rows, query, tenantID, threadID, opts.Limit(), opts.Offset()); err != nil {
+		return nil, 0, fmt.Errorf("failed to list comment replies: %w", err)
+	}
+
+	comments, err := r.withReactions(ctx, tenantID, rows)
+	if err != nil {
+		return nil, 0, err
+	}
+	return comments, total, nil
+}
+
+// AddReaction adds a user's reaction to a comment.
+func (r *CommentRepository) AddReaction(ctx context.Context, tenantID, commentID, userID uuid.UUID, reaction domain.CommentReaction) error {
+	exec := getExecutor(ctx, r.db)
+
+	query := `
+		INSERT INTO sales.comment_reactions (comment_id, tenant_id, user_id, reaction, created_at)
+		VALUES ($1, $2, $3, $4, NOW())
+		ON CONFLICT (comment_id, user_id, reaction) DO NOTHING`
+
+	if _, err := exec.ExecContext(ctx, query, commentID, tenantID, userID, string(reaction)); err != nil {
+		return fmt.Errorf("failed to add comment reaction: %w", err)
+	}
+
+	return nil
+}
+
+// RemoveReaction removes a user's reaction from a comment.
+func (r *CommentRepository) RemoveReaction(ctx context.Context, tenantID, commentID, userID uuid.UUID, reaction domain.CommentReaction) error {
+	exec := getExecutor(ctx, r.db)
+
+	query := `
+		DELETE FROM sales.comment_reactions
+		WHERE tenant_id = $1 AND comment_id = $2 AND user_id = $3 AND reaction = $4`
+
+	if _, err := exec.ExecContext(ctx, query, tenantID, commentID, userID, string(reaction)); err != nil {
+		return fmt.Errorf("failed to remove comment reaction: %w", err)
+	}
+
+	return nil
+}
+
+// commentReactionRow is the database representation of a comment reaction.
+type commentReactionRow struct {
+	CommentID uuid.UUID `db:"comment_id"`
+	UserID    uuid.UUID `db:"user_id"`
+	Reaction  string    `db:"reaction"`
+}
+
+// withReactions converts comment rows to comments, loading their reactions
+// in the order they were added.
+func (r *CommentRepository) withReactions(ctx context.Context, tenantID uuid.UUID, rows []commentRow) ([]*domain.Comment, error) {
+	comments := make([]*domain.Comment, len(rows))
+	byID := make(map[uuid.UUID]*domain.Comment, len(rows))
+	ids := make([]uuid.UUID, len(rows))
+	for i := range rows {
+		comment, err := rows[i].toDomain()
+		if err != nil {
+			return nil, err
+		}
+		comments[i] = comment
+		byID[comment.ID] = comment
+		ids[i] = comment.ID
+	}
+	if len(ids) == 0 {
+		return comments, nil
+	}
+
+	exec := getExecutor(ctx, r.db)
+
+	query := `
+		SELECT comment_id, user_id, reaction
+		FROM sales.comment_reactions
+		WHERE tenant_id = $1 AND comment_id = ANY($2)
+		ORDER BY created_at, user_id`
+
+	var reactions []commentReactionRow
+	if err := sqlx.SelectContext(ctx, exec, &reactions, query, tenantID, pq.Array(ids)); err != nil {
+		return nil, fmt.Errorf("failed to list comment reactions: %w", err)
+	}
+
+	for _, reaction := range reactions {
+		comment := byID[reaction.CommentID]
+		key := domain.CommentReaction(reaction.Reaction)
+		comment.Reactions[key] = append(comment.Reactions[key], reaction.UserID)
+	}
+	return comments, nil
+}
+
+func (row *commentRow) toDomain() (*domain.Comment, error) {
+	comment := &domain.Comment{
+		ID:         row.ID,
+		TenantID:   row.TenantID,
+		EntityType: domain.CommentEntityType(row.EntityType),
+		EntityID:   row.EntityID,
+		AuthorID:   row.AuthorID,
+		Body:       row.Body,
+		Mentions:   []uuid.UUID(row.Mentions),
+		Reactions:  map[domain.CommentReaction][]uuid.UUID{},
+		ReplyCount: row.ReplyCount,
+		Revisions:  []domain.CommentRevision{},
+		EditedAt:   row.EditedAt,
+		DeletedAt:  row.DeletedAt,
+		CreatedAt:  row.CreatedAt,
+		UpdatedAt:  row.UpdatedAt,
+		Version:    row.Version,
+	}
+	if comment.Mentions == nil {
+		comment.Mentions = []uuid.UUID{}
+	}
+	if row.ParentID.Valid {
+		comment.ParentID = &row.ParentID.UUID
+	}
+	if row.DeletedBy.Valid {
+		comment.DeletedBy = &row.DeletedBy.UUID
+	}
+	if err := row.Revisions.MarshalTo(&comment.Revisions); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal comment revisions: %w", err)
+	}
+	return comment, nil
+}
@@ -12,6 +12,8 @@ import (
 	"github.com/google/uuid"
 	"github.com/jmoiron/sqlx"
 	"github.com/lib/pq"
+
+	"github.com/kilang-desa-murni/crm/pkg/query"
 )
 
 // ============================================================================
@@ -249,6 +251,20 @@ func (qb *QueryBuilder) WhereInStrings(column string, values []string) *QueryBui
 	return qb
 }
 
+// WhereExpr adds the conditions of a filter expression, mapping its fields to
+// columns.
+func (qb *QueryBuilder) WhereExpr(expr query.Filter, columns map[string]string) error {
+	if len(expr) == 0 {
+		return nil
+	}
+	predicate, args, err := expr.SQL(columns, qb.NextParam())
+	if err != nil {
+		return err
+	}
+	qb.Where(predicate, args...)
+	return nil
+}
+
 // OrderBy sets the ORDER BY clause.
 func (qb *QueryBuilder) OrderBy(column, direction string) *QueryBuilder {
 	if direction != "asc" && direction != "desc" {
@@ -258,6 +274,28 @@ func (qb *QueryBuilder) OrderBy(column, direction string) *QueryBuilder {
 	return qb
 }
 
+// OrderByKeyset sets an ORDER BY clause on the sort column with the ID column
+// as a tie-breaker, giving the total order keyset pagination relies on.
+func (qb *QueryBuilder) OrderByKeyset(column, idColumn, direction string) *QueryBuilder {
+	if direction != "asc" && direction != "desc" {
+		direction = "desc"
+	}
+	direction = strings.ToUpper(direction)
+	qb.orderBy = fmt.Sprintf("ORDER BY %s %s, %s %s", column, direction, idColumn, direction)
+	return qb
+}
+
+// After adds a keyset condition that continues after the given sort value and
+// ID in the order set by OrderByKeyset.
+func (qb *QueryBuilder) After(column, idColumn, direction, value string, id uuid.UUID) *QueryBuilder {
+	op := "<"
+	if direction == "asc" {
+		op = ">"
+	}
+	n := qb.NextParam()
+	return qb.Where(fmt.Sprintf("(%s, %s) %s ($%d, $%d)", column, idColumn, op, n, n+1), value, id)
+}
+
 // Limit sets the LIMIT clause.
 func (qb *QueryBuilder) Limit(limit int) *QueryBuilder {
 	qb.limit = limit
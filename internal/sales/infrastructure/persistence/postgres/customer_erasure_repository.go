@@ -0,0 +1,130 @@
+// Package postgres provides PostgreSQL implementations for sales domain repositories.
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+
+	"github.com/kilang-desa-murni/crm/internal/sales/domain"
+)
+
+const (
+	erasedCustomerName = "Erased customer"
+	erasedContactName  = "Erased contact"
+)
+
+// Hashes of lead identifiers, computed as the customer service hashes them:
+// the hex SHA-256 of "email:" and the lowercased address, or of "phone:" and
+// the number's digits.
+const (
+	leadEmailHash  = `encode(sha256(convert_to('email:' || lower(trim(email)), 'UTF8')), 'hex')`
+	leadPhoneHash  = `encode(sha256(convert_to('phone:' || regexp_replace(phone, '[^0-9]', '', 'g'), 'UTF8')), 'hex')`
+	leadMobileHash = `encode(sha256(convert_to('phone:' || regexp_replace(mobile, '[^0-9]', '', 'g'), 'UTF8')), 'hex')`
+	senderHash     = `encode(sha256(convert_to('email:' || lower(trim(from_address)), 'UTF8')), 'hex')`
+)
+
+// CustomerErasureRepository implements domain.CustomerDataEraser.
+type CustomerErasureRepository struct {
+	db *sqlx.DB
+	tm *TransactionManager
+}
+
+// NewCustomerErasureRepository creates a new CustomerErasureRepository.
+func NewCustomerErasureRepository(db *sqlx.DB) *CustomerErasureRepository {
+	return &CustomerErasureRepository{db: db, tm: NewTransactionManager(db)}
+}
+
+// Ensure CustomerErasureRepository implements domain.CustomerDataEraser.
+var _ domain.CustomerDataEraser = (*CustomerErasureRepository)(nil)
+
+// EraseCustomer anonymizes the customer's sales records in one transaction.
+// Leads and inbound emails are matched before the leads are anonymized, as
+// anonymizing them clears the identifiers they are matched by.
+func (r *CustomerErasureRepository) EraseCustomer(ctx context.Context, tenantID, customerID uuid.UUID, identifierHashes []string) (int64, error) {
+	hashes := pq.Array(identifierHashes)
+	if identifierHashes == nil {
+		hashes = pq.Array([]string{})
+	}
+
+	byHashes := []interface{}{tenantID, customerID, hashes}
+	byCustomer := []interface{}{tenantID, customerID}
+
+	statements := []struct {
+		name  string
+		query string
+		args  []interface{}
+	}{
+		{"inbound emails", `
+			UPDATE sales.inbound_emails
+			SET from_address = '', from_name = NULL, subject = '', body = '', attachment_ids = '[]'
+			WHERE tenant_id = $1 AND (
+				` + senderHash + ` = ANY($3)
+				OR lead_id IN (
+					SELECT id FROM sales.leads
+					WHERE tenant_id = $1 AND (customer_id = $2
+						OR ` + leadEmailHash + ` = ANY($3)
+						OR ` + leadPhoneHash + ` = ANY($3)
+						OR ` + leadMobileHash + ` = ANY($3))
+				)
+			)`, byHashes},
+		{"leads", `
+			UPDATE sales.leads
+			SET first_name = 'Erased', last_name = 'lead', email = '', phone = '', mobile = '',
+				job_title = '', department = '', company_name = '', website = '',
+				address = '', city = '', state = '', postal_code = '', country = '',
+				description = '', custom_fields = '{}', attribution = attribution - 'referrer' - 'landing_page',
+				updated_at = NOW(), version = version + 1
+			WHERE tenant_id = $1 AND (customer_id = $2
+				OR ` + leadEmailHash + ` = ANY($3)
+				OR ` + leadPhoneHash + ` = ANY($3)
+				OR ` + leadMobileHash + ` = ANY($3))`, byHashes},
+		{"opportunity contacts", `
+			UPDATE sales.opportunity_contacts
+			SET name = '` + erasedContactName + `', email = '', phone = '', notes = ''
+			WHERE tenant_id = $1 AND opportunity_id IN (
+				SELECT id FROM sales.opportunities WHERE tenant_id = $1 AND customer_id = $2
+			)`, byCustomer},
+		{"opportunity board cards", `
+			UPDATE sales.opportunity_board_cards
+			SET customer_name = '` + erasedCustomerName + `'
+			WHERE tenant_id = $1 AND opportunity_id IN (
+				SELECT id FROM sales.opportunities WHERE tenant_id = $1 AND customer_id = $2
+			)`, byCustomer},
+		{"opportunities", `
+			UPDATE sales.opportunities
+			SET customer_name = '` + erasedCustomerName + `', notes = '', custom_fields = '{}',
+				attribution = attribution - 'referrer' - 'landing_page', updated_at = NOW(), version = version + 1
+			WHERE tenant_id = $1 AND customer_id = $2`, byCustomer},
+		{"deals", `
+			UPDATE sales.deals
+			SET customer_name = '` + erasedCustomerName + `', primary_contact_name = '', notes = '',
+				custom_fields = '{}', attribution = attribution - 'referrer' - 'landing_page',
+				updated_at = NOW(), version = version + 1
+			WHERE tenant_id = $1 AND customer_id = $2`, byCustomer},
+	}
+
+	var records int64
+	err := r.tm.WithTransaction(ctx, func(ctx context.Context) error {
+		exec := getExecutor(ctx, r.db)
+		for _, stmt := range statements {
+			result, err := exec.ExecContext(ctx, stmt.query, stmt.args...)
+			if err != nil {
+				return fmt.Errorf("failed to erase %s: %w", stmt.name, err)
+			}
+			affected, err := result.RowsAffected()
+			if err != nil {
+				return fmt.Errorf("failed to count erased %s: %w", stmt.name, err)
+			}
+			records += affected
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return records, nil
+}
@@ -26,6 +26,7 @@ type dealRow struct {
 	Name                 string         `db:"name"`
 	Description          sql.NullString `db:"description"`
 	Status               string         `db:"status"`
+	DealType             string         `db:"deal_type"`
 	OpportunityID        uuid.UUID      `db:"opportunity_id"`
 	CustomerID           uuid.UUID      `db:"customer_id"`
 	CustomerName         sql.NullString `db:"customer_name"`
@@ -45,6 +46,8 @@ type dealRow struct {
 	Notes                sql.NullString `db:"notes"`
 	Tags                 StringArray    `db:"tags"`
 	CustomFields         NullableJSON   `db:"custom_fields"`
+	Attribution          NullableJSON   `db:"attribution"`
+	Subscription         NullableJSON   `db:"subscription"`
 	WonAt                time.Time      `db:"won_at"`
 	ContractDate         sql.NullTime   `db:"contract_date"`
 	StartDate            sql.NullTime   `db:"start_date"`
@@ -81,6 +84,23 @@ var allowedDealSortColumns = map[string]string{
 	"name":         "d.name",
 }
 
+// dealFilterColumns maps the fields of a deal filter expression to columns.
+var dealFilterColumns = map[string]string{
+	"status":             "d.status",
+	"deal_number":        "d.code",
+	"name":               "d.name",
+	"currency":           "d.currency",
+	"total_amount":       "d.total_amount",
+	"paid_amount":        "d.paid_amount",
+	"outstanding_amount": "d.outstanding_amount",
+	"opportunity_id":     "d.opportunity_id",
+	"customer_id":        "d.customer_id",
+	"owner_id":           "d.owner_id",
+	"won_at":             "d.won_at",
+	"created_at":         "d.created_at",
+	"updated_at":         "d.updated_at",
+}
+
 // Create inserts a new deal into the database.
 func (r *DealRepository) Create(ctx context.Context, deal *domain.Deal) error {
 	exec := getExecutor(ctx, r.db)
@@ -98,13 +118,24 @@ func (r *DealRepository) Create(ctx context.Context, deal *domain.Deal) error {
 			currency, subtotal, total_discount, total_tax, total_amount,
 			paid_amount, outstanding_amount, payment_term, contract_url, notes,
 			tags, custom_fields, won_at, contract_date, start_date, end_date,
-			created_at, updated_at, created_by, updated_by, version
+			created_at, updated_at, created_by, updated_by, version, attribution,
+			deal_type, subscription
 		) VALUES (
 			$1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13,
 			$14, $15, $16, $17, $18, $19, $20, $21, $22, $23,
-			$24, $25, $26, $27, $28, $29, $30, $31, $32, $33, $34
+			$24, $25, $26, $27, $28, $29, $30, $31, $32, $33, $34, $35,
+			$36, $37
 		)`
 
+	attributionJSON, err := nullAttribution(deal.Attribution)
+	if err != nil {
+		return err
+	}
+	subscriptionJSON, err := nullSubscription(deal.Subscription)
+	if err != nil {
+		return err
+	}
+
 	_, err = exec.ExecContext(ctx, query,
 		deal.ID,
 		deal.TenantID,
@@ -140,6 +171,9 @@ func (r *DealRepository) Create(ctx context.Context, deal *domain.Deal) error {
 		deal.CreatedBy,
 		deal.CreatedBy,
 		deal.Version,
+		attributionJSON,
+		dealType(deal),
+		subscriptionJSON,
 	)
 
 	if err != nil {
@@ -167,7 +201,7 @@ func (r *DealRepository) GetByID(ctx context.Context, tenantID, dealID uuid.UUID
 			d.primary_contact_id, d.primary_contact_name, d.owner_id, d.owner_name,
 			d.currency, d.subtotal, d.total_discount, d.total_tax, d.total_amount,
 			d.paid_amount, d.outstanding_amount, d.payment_term, d.contract_url, d.notes,
-			d.tags, d.custom_fields, d.won_at, d.contract_date, d.start_date, d.end_date,
+			d.tags, d.custom_fields, d.attribution, d.deal_type, d.subscription, d.won_at, d.contract_date, d.start_date, d.end_date,
 			d.cancelled_at, d.cancelled_by, d.cancel_reason,
 			d.created_at, d.updated_at, d.created_by, d.updated_by, d.deleted_at, d.version
 		FROM sales.deals d
@@ -229,9 +263,15 @@ func (r *DealRepository) Update(ctx context.Context, deal *domain.Deal) error {
 			contract_url = $18, notes = $19, tags = $20, custom_fields = $21,
 			contract_date = $22, start_date = $23, end_date = $24,
 			cancelled_at = $25,
-			updated_at = $26, updated_by = $27, version = version + 1
+			updated_at = $26, updated_by = $27, version = version + 1,
+			deal_type = $29, subscription = $30
 		WHERE tenant_id = $1 AND id = $2 AND deleted_at IS NULL AND version = $28`
 
+	subscriptionJSON, err := nullSubscription(deal.Subscription)
+	if err != nil {
+		return err
+	}
+
 	result, err := exec.ExecContext(ctx, query,
 		deal.TenantID,
 		deal.ID,
@@ -261,6 +301,8 @@ func (r *DealRepository) Update(ctx context.Context, deal *domain.Deal) error {
 		time.Now().UTC(),
 		deal.CreatedBy,
 		deal.Version,
+		dealType(deal),
+		subscriptionJSON,
 	)
 
 	if err != nil {
@@ -331,7 +373,7 @@ func (r *DealRepository) List(ctx context.Context, tenantID uuid.UUID, filter do
 			d.primary_contact_id, d.primary_contact_name, d.owner_id, d.owner_name,
 			d.currency, d.subtotal, d.total_discount, d.total_tax, d.total_amount,
 			d.paid_amount, d.outstanding_amount, d.payment_term, d.contract_url, d.notes,
-			d.tags, d.custom_fields, d.won_at, d.contract_date, d.start_date, d.end_date,
+			d.tags, d.custom_fields, d.attribution, d.deal_type, d.subscription, d.won_at, d.contract_date, d.start_date, d.end_date,
 			d.cancelled_at, d.cancelled_by, d.cancel_reason,
 			d.created_at, d.updated_at, d.created_by, d.updated_by, d.deleted_at, d.version
 		FROM sales.deals d
@@ -342,6 +384,9 @@ func (r *DealRepository) List(ctx context.Context, tenantID uuid.UUID, filter do
 
 	// Apply filters
 	r.applyFilters(qb, filter)
+	if err := qb.WhereExpr(filter.Expr, dealFilterColumns); err != nil {
+		return nil, 0, fmt.Errorf("failed to filter deals: %w", err)
+	}
 
 	// Get total count
 	countQuery, countArgs := qb.BuildCount()
@@ -353,9 +398,13 @@ func (r *DealRepository) List(ctx context.Context, tenantID uuid.UUID, filter do
 	// Apply sorting and pagination
 	sortColumn := ValidateSortColumn(opts.SortBy, allowedDealSortColumns)
 	sortOrder := ValidateSortOrder(opts.SortOrder)
-	qb.OrderBy(sortColumn, sortOrder)
+	qb.OrderByKeyset(sortColumn, "d.id", sortOrder)
 	qb.Limit(opts.Limit())
-	qb.Offset(opts.Offset())
+	if opts.After != nil {
+		qb.After(sortColumn, "d.id", sortOrder, opts.After.SortValue, opts.After.ID)
+	} else {
+		qb.Offset(opts.Offset())
+	}
 
 	query, args := qb.Build()
 
@@ -386,7 +435,7 @@ func (r *DealRepository) GetByNumber(ctx context.Context, tenantID uuid.UUID, de
 			d.primary_contact_id, d.primary_contact_name, d.owner_id, d.owner_name,
 			d.currency, d.subtotal, d.total_discount, d.total_tax, d.total_amount,
 			d.paid_amount, d.outstanding_amount, d.payment_term, d.contract_url, d.notes,
-			d.tags, d.custom_fields, d.won_at, d.contract_date, d.start_date, d.end_date,
+			d.tags, d.custom_fields, d.attribution, d.deal_type, d.subscription, d.won_at, d.contract_date, d.start_date, d.end_date,
 			d.cancelled_at, d.cancelled_by, d.cancel_reason,
 			d.created_at, d.updated_at, d.created_by, d.updated_by, d.deleted_at, d.version
 		FROM sales.deals d
@@ -431,7 +480,7 @@ func (r *DealRepository) GetByOpportunity(ctx context.Context, tenantID, opportu
 			d.primary_contact_id, d.primary_contact_name, d.owner_id, d.owner_name,
 			d.currency, d.subtotal, d.total_discount, d.total_tax, d.total_amount,
 			d.paid_amount, d.outstanding_amount, d.payment_term, d.contract_url, d.notes,
-			d.tags, d.custom_fields, d.won_at, d.contract_date, d.start_date, d.end_date,
+			d.tags, d.custom_fields, d.attribution, d.deal_type, d.subscription, d.won_at, d.contract_date, d.start_date, d.end_date,
 			d.cancelled_at, d.cancelled_by, d.cancel_reason,
 			d.created_at, d.updated_at, d.created_by, d.updated_by, d.deleted_at, d.version
 		FROM sales.deals d
@@ -483,7 +532,7 @@ func (r *DealRepository) GetOverduePayments(ctx context.Context, tenantID uuid.U
 			d.primary_contact_id, d.primary_contact_name, d.owner_id, d.owner_name,
 			d.currency, d.subtotal, d.total_discount, d.total_tax, d.total_amount,
 			d.paid_amount, d.outstanding_amount, d.payment_term, d.contract_url, d.notes,
-			d.tags, d.custom_fields, d.won_at, d.contract_date, d.start_date, d.end_date,
+			d.tags, d.custom_fields, d.attribution, d.deal_type, d.subscription, d.won_at, d.contract_date, d.start_date, d.end_date,
 			d.cancelled_at, d.cancelled_by, d.cancel_reason,
 			d.created_at, d.updated_at, d.created_by, d.updated_by, d.deleted_at, d.version
 		FROM sales.deals d
@@ -527,6 +576,138 @@ func (r *DealRepository) GetOverduePayments(ctx context.Context, tenantID uuid.U
 	return deals, total, nil
 }
 
+// GetDealsWithLapsedInvoices retrieves deals of all tenants with a sent
+// invoice due before dueBefore, oldest due date first.
+func (r *DealRepository) GetDealsWithLapsedInvoices(ctx context.Context, dueBefore time.Time, limit int) ([]*domain.Deal, error) {
+	exec := getExecutor(ctx, r.db)
+
+	query := `
+		SELECT d.tenant_id, d.id
+		FROM sales.deals d
+		JOIN sales.deal_invoices i ON i.deal_id = d.id AND i.tenant_id = d.tenant_id
+		WHERE d.deleted_at IS NULL
+			AND i.status = 'sent'
+			AND i.due_date < $1
+		GROUP BY d.tenant_id, d.id
+		ORDER BY MIN(i.due_date)
+		LIMIT $2`
+
+	var keys []struct {
+		TenantID uuid.UUID `db:"tenant_id"`
+		ID       uuid.UUID `db:"id"`
+	}
+	if err := sqlx.SelectContext(ctx, exec, &keys, query, dueBefore, limit); err != nil {
+		return nil, fmt.Errorf("failed to get deals with lapsed invoices: %w", err)
+	}
+
+	deals := make([]*domain.Deal, 0, len(keys))
+	for _, key := range keys {
+		deal, err := r.GetByID(ctx, key.TenantID, key.ID)
+		if err != nil {
+			return nil, err
+		}
+		deals = append(deals, deal)
+	}
+	return deals, nil
+}
+
+// GetDealsDueForRenewal retrieves up to limit recurring deals of any tenant
+// without a renewal opportunity whose renewal is due by dueBy.
+func (r *DealRepository) GetDealsDueForRenewal(ctx context.Context, dueBy time.Time, limit int) ([]*domain.Deal, error) {
+	exec := getExecutor(ctx, r.db)
+
+	query := `
+		SELECT d.tenant_id, d.id
+		FROM sales.deals d
+		WHERE d.deal_type = 'recurring'
+			AND d.deleted_at IS NULL
+			AND d.status <> 'cancelled'
+			AND d.subscription->>'renewal_status' = 'upcoming'
+			AND d.end_date - make_interval(days => (d.subscription->>'renewal_notice_days')::int) <= $1
+		ORDER BY d.end_date
+		LIMIT $2`
+
+	var keys []struct {
+		TenantID uuid.UUID `db:"tenant_id"`
+		ID       uuid.UUID `db:"id"`
+	}
+	if err := sqlx.SelectContext(ctx, exec, &keys, query, dueBy, limit); err != nil {
+		return nil, fmt.Errorf("failed to get deals due for renewal: %w", err)
+	}
+
+	deals := make([]*domain.Deal, 0, len(keys))
+	for _, key := range keys {
+		deal, err := r.GetByID(ctx, key.TenantID, key.ID)
+		if err != nil {
+			return nil, err
+		}
+		deals = append(deals, deal)
+	}
+	return deals, nil
+}
+
+// GetByRenewalOpportunity retrieves the recurring deal an opportunity renews.
+func (r *DealRepository) GetByRenewalOpportunity(ctx context.Context, tenantID, opportunityID uuid.UUID) (*domain.Deal, error) {
+	exec := getExecutor(ctx, r.db)
+
+	query := `
+		SELECT d.id
+		FROM sales.deals d
+		WHERE d.tenant_id = $1
+			AND d.deal_type = 'recurring'
+			AND d.subscription->>'renewal_opportunity_id' = $2
+			AND d.deleted_at IS NULL`
+
+	var dealID uuid.UUID
+	if err := sqlx.GetContext(ctx, exec, &dealID, query, tenantID, opportunityID.String()); err != nil {
+		if IsNotFoundError(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get deal by renewal opportunity: %w", err)
+	}
+
+	return r.GetByID(ctx, tenantID, dealID)
+}
+
+// GetRecurringDeals retrieves the recurring deals in currency whose contract
+// runs at some time between from and to.
+func (r *DealRepository) GetRecurringDeals(ctx context.Context, tenantID uuid.UUID, currency string, from, to time.Time) ([]*domain.Deal, error) {
+	exec := getExecutor(ctx, r.db)
+
+	query := `
+		SELECT d.id, d.tenant_id, d.code, d.name, d.description, d.status,
+			d.opportunity_id, d.customer_id, d.customer_name,
+			d.primary_contact_id, d.primary_contact_name, d.owner_id, d.owner_name,
+			d.currency, d.subtotal, d.total_discount, d.total_tax, d.total_amount,
+			d.paid_amount, d.outstanding_amount, d.payment_term, d.contract_url, d.notes,
+			d.tags, d.custom_fields, d.attribution, d.deal_type, d.subscription, d.won_at, d.contract_date, d.start_date, d.end_date,
+			d.cancelled_at, d.cancelled_by, d.cancel_reason,
+			d.created_at, d.updated_at, d.created_by, d.updated_by, d.deleted_at, d.version
+		FROM sales.deals d
+		WHERE d.tenant_id = $1
+			AND d.deal_type = 'recurring'
+			AND d.deleted_at IS NULL
+			AND d.currency = $2
+			AND d.start_date <= $4
+			AND d.end_date >= $3
+		ORDER BY d.end_date`
+
+	var rows []dealRow
+	if err := sqlx.SelectContext(ctx, exec, &rows, query, tenantID, currency, from, to); err != nil {
+		return nil, fmt.Errorf("failed to get recurring deals: %w", err)
+	}
+
+	deals := make([]*domain.Deal, 0, len(rows))
+	for _, row := range rows {
+		deal, err := r.toDomain(&row)
+		if err != nil {
+			return nil, err
+		}
+		deals = append(deals, deal)
+	}
+	return deals, nil
+}
+
 // GetFullyPaidDeals retrieves fully paid deals.
 func (r *DealRepository) GetFullyPaidDeals(ctx context.Context, tenantID uuid.UUID, opts domain.ListOptions) ([]*domain.Deal, int64, error) {
 	fullyPaid := true
@@ -556,7 +737,7 @@ func (r *DealRepository) GetPartiallyFulfilledDeals(ctx context.Context, tenantI
 			d.primary_contact_id, d.primary_contact_name, d.owner_id, d.owner_name,
 			d.currency, d.subtotal, d.total_discount, d.total_tax, d.total_amount,
 			d.paid_amount, d.outstanding_amount, d.payment_term, d.contract_url, d.notes,
-			d.tags, d.custom_fields, d.won_at, d.contract_date, d.start_date, d.end_date,
+			d.tags, d.custom_fields, d.attribution, d.deal_type, d.subscription, d.won_at, d.contract_date, d.start_date, d.end_date,
 			d.cancelled_at, d.cancelled_by, d.cancel_reason,
 			d.created_at, d.updated_at, d.created_by, d.updated_by, d.deleted_at, d.version
 		FROM sales.deals d
@@ -887,6 +1068,21 @@ func (r *DealRepository) toDomain(row *dealRow) (*domain.Deal, error) {
 		deal.CustomFields = customFields
 	}
 
+	// Attribution
+	if err := row.Attribution.MarshalTo(&deal.Attribution); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal attribution: %w", err)
+	}
+
+	// Subscription
+	deal.Type = domain.DealType(row.DealType)
+	if err := row.Subscription.MarshalTo(&deal.Subscription); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal subscription: %w", err)
+	}
+	if deal.Subscription != nil {
+		renewal := deal.Subscription.RenewalDueAt()
+		deal.Timeline.RenewalDate = &renewal
+	}
+
 	return deal, nil
 }
 
@@ -905,8 +1101,9 @@ func (r *DealRepository) insertLineItems(ctx context.Context, dealID, tenantID u
 		INSERT INTO sales.deal_line_items (
 			id, deal_id, tenant_id, product_id, product_name, product_sku, description,
 			quantity, unit_price, currency, discount, discount_type, subtotal,
-			tax, tax_type, tax_amount, total, fulfilled_qty, notes, created_at
-		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20)`
+			tax, tax_type, tax_code, tax_name, tax_inclusive, tax_amount, total,
+			fulfilled_qty, notes, created_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, $21, $22, $23)`
 
 	for _, item := range items {
 		_, err := exec.ExecContext(ctx, query,
@@ -914,7 +1111,8 @@ func (r *DealRepository) insertLineItems(ctx context.Context, dealID, tenantID u
 			nullString(item.ProductSKU), nullString(item.Description),
 			item.Quantity, item.UnitPrice.Amount, item.UnitPrice.Currency,
 			item.Discount, item.DiscountType, item.Subtotal.Amount,
-			item.Tax, item.TaxType, item.TaxAmount.Amount, item.Total.Amount,
+			item.Tax, item.TaxType, nullString(item.TaxCode), nullString(item.TaxName),
+			item.TaxInclusive, item.TaxAmount.Amount, item.Total.Amount,
 			item.FulfilledQty, nullString(item.Notes), time.Now().UTC(),
 		)
 		if err != nil {
@@ -931,7 +1129,8 @@ func (r *DealRepository) getLineItems(ctx context.Context, dealID, tenantID uuid
 	query := `
 		SELECT id, product_id, product_name, product_sku, description,
 			quantity, unit_price, currency, discount, discount_type, subtotal,
-			tax, tax_type, tax_amount, total, fulfilled_qty, notes
+			tax, tax_type, tax_code, tax_name, tax_inclusive, tax_amount, total,
+			fulfilled_qty, notes
 		FROM sales.deal_line_items
 		WHERE deal_id = $1 AND tenant_id = $2
 		ORDER BY created_at ASC`
@@ -947,19 +1146,21 @@ func (r *DealRepository) getLineItems(ctx context.Context, dealID, tenantID uuid
 		var item domain.DealLineItem
 		var unitPrice, subtotal, taxAmount, total int64
 		var currency string
-		var sku, description, notes sql.NullString
+		var sku, description, taxCode, taxName, notes sql.NullString
 
 		if err := rows.Scan(
 			&item.ID, &item.ProductID, &item.ProductName, &sku, &description,
 			&item.Quantity, &unitPrice, &currency, &item.Discount, &item.DiscountType,
-			&subtotal, &item.Tax, &item.TaxType, &taxAmount, &total,
-			&item.FulfilledQty, &notes,
+			&subtotal, &item.Tax, &item.TaxType, &taxCode, &taxName, &item.TaxInclusive,
+			&taxAmount, &total, &item.FulfilledQty, &notes,
 		); err != nil {
 			return nil, fmt.Errorf("failed to scan line item: %w", err)
 		}
 
 		item.ProductSKU = sku.String
 		item.Description = description.String
+		item.TaxCode = taxCode.String
+		item.TaxName = taxName.String
 		item.Notes = notes.String
 		item.UnitPrice = domain.Money{Amount: unitPrice, Currency: currency}
 		item.Subtotal = domain.Money{Amount: subtotal, Currency: currency}
@@ -992,8 +1193,8 @@ func (r *DealRepository) getInvoices(ctx context.Context, dealID, tenantID uuid.
 	exec := getExecutor(ctx, r.db)
 
 	query := `
-		SELECT id, invoice_number, amount, currency, status, due_date,
-			paid_amount, paid_at, notes, created_at
+		SELECT id, invoice_number, amount, subtotal, tax_amount, tax_lines, currency,
+			status, due_date, paid_amount, paid_at, notes, created_at
 		FROM sales.deal_invoices
 		WHERE deal_id = $1 AND tenant_id = $2
 		ORDER BY created_at ASC`
@@ -1007,19 +1208,29 @@ func (r *DealRepository) getInvoices(ctx context.Context, dealID, tenantID uuid.
 	var invoices []domain.Invoice
 	for rows.Next() {
 		var inv domain.Invoice
-		var amount, paidAmount int64
+		var amount, taxAmount, paidAmount int64
+		var subtotal sql.NullInt64
 		var currency string
+		var taxLines NullableJSON
 		var paidAt sql.NullTime
 		var notes sql.NullString
 
 		if err := rows.Scan(
-			&inv.ID, &inv.InvoiceNumber, &amount, &currency, &inv.Status,
-			&inv.DueDate, &paidAmount, &paidAt, &notes, &inv.CreatedAt,
+			&inv.ID, &inv.InvoiceNumber, &amount, &subtotal, &taxAmount, &taxLines,
+			&currency, &inv.Status, &inv.DueDate, &paidAmount, &paidAt, &notes, &inv.CreatedAt,
 		); err != nil {
 			return nil, fmt.Errorf("failed to scan invoice: %w", err)
 		}
 
 		inv.Amount = domain.Money{Amount: amount, Currency: currency}
+		inv.TaxAmount = domain.Money{Amount: taxAmount, Currency: currency}
+		inv.Subtotal = inv.Amount
+		if subtotal.Valid {
+			inv.Subtotal = domain.Money{Amount: subtotal.Int64, Currency: currency}
+		}
+		if err := taxLines.MarshalTo(&inv.TaxLines); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal invoice tax lines: %w", err)
+		}
 		inv.PaidAmount = domain.Money{Amount: paidAmount, Currency: currency}
 		if paidAt.Valid {
 			inv.PaidAt = &paidAt.Time
@@ -1036,19 +1247,29 @@ func (r *DealRepository) syncInvoices(ctx context.Context, dealID, tenantID uuid
 	exec := getExecutor(ctx, r.db)
 
 	for _, inv := range invoices {
+		taxLines := inv.TaxLines
+		if taxLines == nil {
+			taxLines = []domain.TaxLine{}
+		}
+		taxLinesJSON, err := ToJSON(taxLines)
+		if err != nil {
+			return fmt.Errorf("failed to marshal invoice tax lines: %w", err)
+		}
+
 		query := `
 			INSERT INTO sales.deal_invoices (
-				id, deal_id, tenant_id, invoice_number, amount, currency,
-				status, due_date, paid_amount, paid_at, notes, created_at
-			) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+				id, deal_id, tenant_id, invoice_number, amount, subtotal, tax_amount, tax_lines,
+				currency, status, due_date, paid_amount, paid_at, notes, created_at
+			) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15)
 			ON CONFLICT (id) DO UPDATE SET
 				status = EXCLUDED.status,
 				paid_amount = EXCLUDED.paid_amount,
 				paid_at = EXCLUDED.paid_at`
 
-		_, err := exec.ExecContext(ctx, query,
+		_, err = exec.ExecContext(ctx, query,
 			inv.ID, dealID, tenantID, inv.InvoiceNumber,
-			inv.Amount.Amount, inv.Amount.Currency, inv.Status, inv.DueDate,
+			inv.Amount.Amount, inv.Subtotal.Amount, inv.TaxAmount.Amount, taxLinesJSON,
+			inv.Amount.Currency, inv.Status, inv.DueDate,
 			inv.PaidAmount.Amount, NewNullTime(inv.PaidAt).NullTime,
 			nullString(inv.Notes), inv.CreatedAt,
 		)
@@ -1132,3 +1353,23 @@ func (r *DealRepository) syncPayments(ctx context.Context, dealID, tenantID uuid
 
 	return nil
 }
+
+func nullSubscription(subscription *domain.DealSubscription) (sql.NullString, error) {
+	if subscription == nil {
+		return sql.NullString{}, nil
+	}
+	data, err := json.Marshal(subscription)
+	if err != nil {
+		return sql.NullString{}, fmt.Errorf("failed to marshal subscription: %w", err)
+	}
+	return sql.NullString{String: string(data), Valid: true}, nil
+}
+
+// dealType returns the type a deal is stored with; deals created before
+// types were introduced are one-time.
+func dealType(deal *domain.Deal) string {
+	if deal.Type == "" {
+		return string(domain.DealTypeOneTime)
+	}
+	return string(deal.Type)
+}
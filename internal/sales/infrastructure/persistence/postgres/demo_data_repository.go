@@ -0,0 +1,110 @@
+// Package postgres provides PostgreSQL implementations for sales domain repositories.
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+
+	"github.com/kilang-desa-murni/crm/internal/sales/domain"
+)
+
+// DemoDataRepository implements domain.DemoDataRepository.
+type DemoDataRepository struct {
+	db *sqlx.DB
+	tm *TransactionManager
+}
+
+// NewDemoDataRepository creates a new DemoDataRepository.
+func NewDemoDataRepository(db *sqlx.DB) *DemoDataRepository {
+	return &DemoDataRepository{db: db, tm: NewTransactionManager(db)}
+}
+
+// Ensure DemoDataRepository implements domain.DemoDataRepository.
+var _ domain.DemoDataRepository = (*DemoDataRepository)(nil)
+
+// FindExisting returns which of the records exist, deleted or not, as a
+// soft-deleted record still holds its ID.
+func (r *DemoDataRepository) FindExisting(ctx context.Context, tenantID uuid.UUID, ids []uuid.UUID) (map[uuid.UUID]bool, error) {
+	query := `
+		SELECT id FROM pipelines WHERE tenant_id = $1 AND id = ANY($2)
+		UNION ALL
+		SELECT id FROM sales.leads WHERE tenant_id = $1 AND id = ANY($2)
+		UNION ALL
+		SELECT id FROM sales.opportunities WHERE tenant_id = $1 AND id = ANY($2)`
+
+	var found []uuid.UUID
+	if err := sqlx.SelectContext(ctx, getExecutor(ctx, r.db), &found, query, tenantID, pq.Array(ids)); err != nil {
+		return nil, fmt.Errorf("failed to find demo records: %w", err)
+	}
+
+	existing := make(map[uuid.UUID]bool, len(found))
+	for _, id := range found {
+		existing[id] = true
+	}
+	return existing, nil
+}
+
+// Purge deletes the records in one transaction, children before the records
+// they reference.
+func (r *DemoDataRepository) Purge(ctx context.Context, tenantID uuid.UUID, ids []uuid.UUID) (int64, error) {
+	args := []interface{}{tenantID, pq.Array(ids)}
+
+	// Demo opportunities that became deals are kept, as the deal is not demo
+	// data
+	const purgedOpportunities = `
+		SELECT o.id FROM sales.opportunities o
+		WHERE o.tenant_id = $1 AND o.id = ANY($2)
+			AND NOT EXISTS (SELECT 1 FROM sales.deals d WHERE d.opportunity_id = o.id)`
+
+	statements := []struct {
+		name    string
+		query   string
+		counted bool
+	}{
+		{"opportunity contacts", `
+			DELETE FROM sales.opportunity_contacts
+			WHERE tenant_id = $1 AND opportunity_id IN (` + purgedOpportunities + `)`, false},
+		{"opportunity board cards", `
+			DELETE FROM sales.opportunity_board_cards
+			WHERE tenant_id = $1 AND opportunity_id IN (` + purgedOpportunities + `)`, false},
+		{"opportunities", `
+			DELETE FROM sales.opportunities
+			WHERE tenant_id = $1 AND id IN (` + purgedOpportunities + `)`, true},
+		{"leads", `
+			DELETE FROM sales.leads
+			WHERE tenant_id = $1 AND id = ANY($2)
+				AND NOT EXISTS (SELECT 1 FROM sales.opportunities o WHERE o.lead_id = sales.leads.id)`, true},
+		{"pipelines", `
+			DELETE FROM pipelines
+			WHERE tenant_id = $1 AND id = ANY($2)
+				AND NOT EXISTS (SELECT 1 FROM sales.opportunities o WHERE o.pipeline_id = pipelines.id)`, true},
+	}
+
+	var records int64
+	err := r.tm.WithTransaction(ctx, func(ctx context.Context) error {
+		exec := getExecutor(ctx, r.db)
+		for _, stmt := range statements {
+			result, err := exec.ExecContext(ctx, stmt.query, args...)
+			if err != nil {
+				return fmt.Errorf("failed to purge demo %s: %w", stmt.name, err)
+			}
+			if !stmt.counted {
+				continue
+			}
+			affected, err := result.RowsAffected()
+			if err != nil {
+				return fmt.Errorf("failed to count purged demo %s: %w", stmt.name, err)
+			}
+			records += affected
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return records, nil
+}
@@ -0,0 +1,378 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+
+	"github.com/kilang-desa-murni/crm/internal/sales/domain"
+)
+
+// DiscountApprovalRepository implements domain.DiscountApprovalRepository for PostgreSQL.
+type DiscountApprovalRepository struct {
+	db *sqlx.DB
+}
+
+// NewDiscountApprovalRepository creates a new DiscountApprovalRepository.
+func NewDiscountApprovalRepository(db *sqlx.DB) *DiscountApprovalRepository {
+	return &DiscountApprovalRepository{db: db}
+}
+
+// ============================================================================
+// Rules
+// ============================================================================
+
+// discountApprovalRuleRow is the database representation of a discount approval rule.
+type discountApprovalRuleRow struct {
+	ID               uuid.UUID `db:"id"`
+	TenantID         uuid.UUID `db:"tenant_id"`
+	Name             string    `db:"name"`
+	ThresholdType    string    `db:"threshold_type"`
+	ThresholdPercent float64   `db:"threshold_percent"`
+	ThresholdAmount  int64     `db:"threshold_amount"`
+	Currency         string    `db:"currency"`
+	ApproverIDs      UUIDArray `db:"approver_ids"`
+	IsActive         bool      `db:"is_active"`
+	CreatedAt        time.Time `db:"created_at"`
+	UpdatedAt        time.Time `db:"updated_at"`
+}
+
+const discountApprovalRuleColumns = `
+	id, tenant_id, name, threshold_type, threshold_percent, threshold_amount,
+	currency, approver_ids, is_active, created_at, updated_at`
+
+// CreateRule creates a new rule.
+func (r *DiscountApprovalRepository) CreateRule(ctx context.Context, rule *domain.DiscountApprovalRule) error {
+	exec := getExecutor(ctx, r.db)
+
+	query := `
+		INSERT INTO sales.discount_approval_rules (` + discountApprovalRuleColumns + `)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)`
+
+	_, err := exec.ExecContext(ctx, query,
+		rule.ID, rule.TenantID, rule.Name, string(rule.ThresholdType), rule.ThresholdPercent,
+		rule.ThresholdAmount.Amount, rule.ThresholdAmount.Currency, pq.Array(rule.ApproverIDs),
+		rule.IsActive, rule.CreatedAt, rule.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create discount approval rule: %w", err)
+	}
+
+	return nil
+}
+
+// GetRule retrieves a rule by ID.
+func (r *DiscountApprovalRepository) GetRule(ctx context.Context, tenantID, ruleID uuid.UUID) (*domain.DiscountApprovalRule, error) {
+	exec := getExecutor(ctx, r.db)
+
+	query := `SELECT ` + discountApprovalRuleColumns + `
+		FROM sales.discount_approval_rules
+		WHERE tenant_id = $1 AND id = $2`
+
+	var row discountApprovalRuleRow
+	if err := sqlx.GetContext(ctx, exec, &row, query, tenantID, ruleID); err != nil {
+		if IsNotFoundError(err) {
+			return nil, domain.ErrDiscountApprovalRuleNotFound
+		}
+		return nil, fmt.Errorf("failed to get discount approval rule: %w", err)
+	}
+
+	return row.toDomain(), nil
+}
+
+// UpdateRule updates a rule.
+func (r *DiscountApprovalRepository) UpdateRule(ctx context.Context, rule *domain.DiscountApprovalRule) error {
+	exec := getExecutor(ctx, r.db)
+
+	query := `
+		UPDATE sales.discount_approval_rules SET
+			name = $3, threshold_type = $4, threshold_percent = $5, threshold_amount = $6,
+			currency = $7, approver_ids = $8, is_active = $9, updated_at = $10
+		WHERE tenant_id = $1 AND id = $2`
+
+	result, err := exec.ExecContext(ctx, query,
+		rule.TenantID, rule.ID, rule.Name, string(rule.ThresholdType), rule.ThresholdPercent,
+		rule.ThresholdAmount.Amount, rule.ThresholdAmount.Currency, pq.Array(rule.ApproverIDs),
+		rule.IsActive, rule.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update discount approval rule: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return domain.ErrDiscountApprovalRuleNotFound
+	}
+
+	return nil
+}
+
+// DeleteRule deletes a rule.
+func (r *DiscountApprovalRepository) DeleteRule(ctx context.Context, tenantID, ruleID uuid.UUID) error {
+	exec := getExecutor(ctx, r.db)
+
+	query := `DELETE FROM sales.discount_approval_rules WHERE tenant_id = $1 AND id = $2`
+
+	result, err := exec.ExecContext(ctx, query, tenantID, ruleID)
+	if err != nil {
+		return fmt.Errorf("failed to delete discount approval rule: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return domain.ErrDiscountApprovalRuleNotFound
+	}
+
+	return nil
+}
+
+// ListRules lists a tenant's rules ordered by name.
+func (r *DiscountApprovalRepository) ListRules(ctx context.Context, tenantID uuid.UUID, activeOnly bool) ([]*domain.DiscountApprovalRule, error) {
+	exec := getExecutor(ctx, r.db)
+
+	query := `SELECT ` + discountApprovalRuleColumns + `
+		FROM sales.discount_approval_rules
+		WHERE tenant_id = $1`
+	if activeOnly {
+		query += ` AND is_active = TRUE`
+	}
+	query += ` ORDER BY name`
+
+	var rows []discountApprovalRuleRow
+	if err := sqlx.SelectContext(ctx, exec, &rows, query, tenantID); err != nil {
+		return nil, fmt.Errorf("failed to list discount approval rules: %w", err)
+	}
+
+	rules := make([]*domain.DiscountApprovalRule, len(rows))
+	for i := range rows {
+		rules[i] = rows[i].toDomain()
+	}
+
+	return rules, nil
+}
+
+func (row *discountApprovalRuleRow) toDomain() *domain.DiscountApprovalRule {
+	return &domain.DiscountApprovalRule{
+		ID:               row.ID,
+		TenantID:         row.TenantID,
+		Name:             row.Name,
+		ThresholdType:    domain.DiscountThresholdType(row.ThresholdType),
+		ThresholdPercent: row.ThresholdPercent,
+		ThresholdAmount:  domain.Money{Amount: row.ThresholdAmount, Currency: row.Currency},
+		ApproverIDs:      []uuid.UUID(row.ApproverIDs),
+		IsActive:         row.IsActive,
+		CreatedAt:        row.CreatedAt,
+		UpdatedAt:        row.UpdatedAt,
+	}
+}
+
+// ============================================================================
+// Approvals
+// ============================================================================
+
+// discountApprovalRow is the database representation of a discount approval.
+type discountApprovalRow struct {
+	ID              uuid.UUID     `db:"id"`
+	TenantID        uuid.UUID     `db:"tenant_id"`
+	OpportunityID   uuid.UUID     `db:"opportunity_id"`
+	OpportunityName string        `db:"opportunity_name"`
+	Status          string        `db:"status"`
+	DiscountAmount  int64         `db:"discount_amount"`
+	DiscountPercent float64       `db:"discount_percent"`
+	Currency        string        `db:"currency"`
+	RuleIDs         UUIDArray     `db:"rule_ids"`
+	ApproverIDs     UUIDArray     `db:"approver_ids"`
+	RequestedBy     uuid.UUID     `db:"requested_by"`
+	RequestComment  string        `db:"request_comment"`
+	DecidedBy       uuid.NullUUID `db:"decided_by"`
+	DecisionComment string        `db:"decision_comment"`
+	RequestedAt     time.Time     `db:"requested_at"`
+	DecidedAt       *time.Time    `db:"decided_at"`
+	Version         int           `db:"version"`
+}
+
+const discountApprovalColumns = `
+	id, tenant_id, opportunity_id, opportunity_name, status, discount_amount,
+	discount_percent, currency, rule_ids, approver_ids, requested_by, request_comment,
+	decided_by, decision_comment, requested_at, decided_at, version`
+
+// Create creates a new approval.
+func (r *DiscountApprovalRepository) Create(ctx context.Context, approval *domain.DiscountApproval) error {
+	exec := getExecutor(ctx, r.db)
+
+	query := `
+		INSERT INTO sales.discount_approvals (` + discountApprovalColumns + `)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17)`
+
+	_, err := exec.ExecContext(ctx, query,
+		approval.ID, approval.TenantID, approval.OpportunityID, approval.OpportunityName,
+		string(approval.Status), approval.Discount.Amount.Amount, approval.Discount.Percent,
+		approval.Discount.Amount.Currency, pq.Array(approval.RuleIDs), pq.Array(approval.ApproverIDs),
+		approval.RequestedBy, approval.RequestComment, nullUUID(approval.DecidedBy),
+		approval.DecisionComment, approval.RequestedAt, approval.DecidedAt, approval.Version,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create discount approval: %w", err)
+	}
+
+	return nil
+}
+
+// GetByID retrieves an approval by ID.
+func (r *DiscountApprovalRepository) GetByID(ctx context.Context, tenantID, approvalID uuid.UUID) (*domain.DiscountApproval, error) {
+	exec := getExecutor(ctx, r.db)
+
+	query := `SELECT ` + discountApprovalColumns + `
+		FROM sales.discount_approvals
+		WHERE tenant_id = $1 AND id = $2`
+
+	var row discountApprovalRow
+	if err := sqlx.GetContext(ctx, exec, &row, query, tenantID, approvalID); err != nil {
+		if IsNotFoundError(err) {
+			return nil, domain.ErrDiscountApprovalNotFound
+		}
+		return nil, fmt.Errorf("failed to get discount approval: %w", err)
+	}
+
+	return row.toDomain(), nil
+}
+
+// Update updates an approval's decision, checking the version it was loaded at.
+func (r *DiscountApprovalRepository) Update(ctx context.Context, approval *domain.DiscountApproval) error {
+	exec := getExecutor(ctx, r.db)
+
+	query := `
+		UPDATE sales.discount_approvals SET
+			status = $3, decided_by = $4, decision_comment = $5, decided_at = $6, version = $7
+		WHERE tenant_id = $1 AND id = $2 AND version = $7 - 1`
+
+	result, err := exec.ExecContext(ctx, query,
+		approval.TenantID, approval.ID, string(approval.Status), nullUUID(approval.DecidedBy),
+		approval.DecisionComment, approval.DecidedAt, approval.Version,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update discount approval: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return domain.ErrDiscountApprovalNotFound
+	}
+
+	return nil
+}
+
+// GetLatestByOpportunity retrieves the most recently requested approval of an opportunity.
+func (r *DiscountApprovalRepository) GetLatestByOpportunity(ctx context.Context, tenantID, opportunityID uuid.UUID) (*domain.DiscountApproval, error) {
+	exec := getExecutor(ctx, r.db)
+
+	query := `SELECT ` + discountApprovalColumns + `
+		FROM sales.discount_approvals
+		WHERE tenant_id = $1 AND opportunity_id = $2
+		ORDER BY requested_at DESC
+		LIMIT 1`
+
+	var row discountApprovalRow
+	if err := sqlx.GetContext(ctx, exec, &row, query, tenantID, opportunityID); err != nil {
+		if IsNotFoundError(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get latest discount approval: %w", err)
+	}
+
+	return row.toDomain(), nil
+}
+
+// ListByOpportunity lists an opportunity's approvals, newest first.
+func (r *DiscountApprovalRepository) ListByOpportunity(ctx context.Context, tenantID, opportunityID uuid.UUID) ([]*domain.DiscountApproval, error) {
+	exec := getExecutor(ctx, r.db)
+
+	query := `SELECT ` + discountApprovalColumns + `
+		FROM sales.discount_approvals
+		WHERE tenant_id = $1 AND opportunity_id = $2
+		ORDER BY requested_at DESC`
+
+	var rows []discountApprovalRow
+	if err := sqlx.SelectContext(ctx, exec, &rows, query, tenantID, opportunityID); err != nil {
+		return nil, fmt.Errorf("failed to list opportunity discount approvals: %w", err)
+	}
+
+	return toDomainDiscountApprovals(rows), nil
+}
+
+// ListPendingForApprover lists the pending approvals a user can decide, oldest first.
+func (r *DiscountApprovalRepository) ListPendingForApprover(ctx context.Context, tenantID, approverID uuid.UUID, opts domain.ListOptions) ([]*domain.DiscountApproval, int64, error) {
+	exec := getExecutor(ctx, r.db)
+
+	where := `WHERE tenant_id = $1 AND status = 'pending' AND approver_ids @> ARRAY[$2]::uuid[]`
+
+	var total int64
+	if err := sqlx.GetContext(ctx, exec, &total, `SELECT COUNT(*) FROM sales.discount_approvals `+where, tenantID, approverID); err != nil {
+		return nil, 0, fmt.Errorf("failed to count pending discount approvals: %w", err)
+	}
+
+	query := `SELECT ` + discountApprovalColumns + `
+		FROM sales.discount_approvals ` + where + `
+		ORDER BY requested_at
+		LIMIT $3 OFFSET $4`
+
+	var rows []discountApprovalRow
+	if err := sqlx.SelectContext(ctx, exec, &rows, query, tenantID, approverID, opts.Limit(), opts.Offset()); err != nil {
+		return nil, 0, fmt.Errorf("failed to list pending discount approvals: %w", err)
+	}
+
+	return toDomainDiscountApprovals(rows), total, nil
+}
+
+func (row *discountApprovalRow) toDomain() *domain.DiscountApproval {
+	approval := &domain.DiscountApproval{
+		ID:              row.ID,
+		TenantID:        row.TenantID,
+		OpportunityID:   row.OpportunityID,
+		OpportunityName: row.OpportunityName,
+		Status:          domain.DiscountApprovalStatus(row.Status),
+		Discount: domain.Discount{
+			Amount:  domain.Money{Amount: row.DiscountAmount, Currency: row.Currency},
+			Percent: row.DiscountPercent,
+		},
+		RuleIDs:         []uuid.UUID(row.RuleIDs),
+		ApproverIDs:     []uuid.UUID(row.ApproverIDs),
+		RequestedBy:     row.RequestedBy,
+		RequestComment:  row.RequestComment,
+		DecisionComment: row.DecisionComment,
+		RequestedAt:     row.RequestedAt,
+		DecidedAt:       row.DecidedAt,
+		Version:         row.Version,
+	}
+	if row.DecidedBy.Valid {
+		approval.DecidedBy = &row.DecidedBy.UUID
+	}
+	return approval
+}
+
+func toDomainDiscountApprovals(rows []discountApprovalRow) []*domain.DiscountApproval {
+	approvals := make([]*domain.DiscountApproval, len(rows))
+	for i := range rows {
+		approvals[i] = rows[i].toDomain()
+	}
+	return approvals
+}
+
+// Ensure DiscountApprovalRepository implements domain.DiscountApprovalRepository
+var _ domain.DiscountApprovalRepository = (*DiscountApprovalRepository)(nil)
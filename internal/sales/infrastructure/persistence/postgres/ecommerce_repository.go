@@ -0,0 +1,430 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+
+	"github.com/kilang-desa-murni/crm/internal/sales/domain"
+)
+
+// ============================================================================
+// E-Commerce Store Repository
+// ============================================================================
+
+// EcommerceStoreRepository implements domain.EcommerceStoreRepository for PostgreSQL.
+type EcommerceStoreRepository struct {
+	db *sqlx.DB
+}
+
+// NewEcommerceStoreRepository creates a new EcommerceStoreRepository.
+func NewEcommerceStoreRepository(db *sqlx.DB) *EcommerceStoreRepository {
+	return &EcommerceStoreRepository{db: db}
+}
+
+// ecommerceStoreRow is the database representation of an online store connector.
+type ecommerceStoreRow struct {
+	ID                   uuid.UUID  `db:"id"`
+	TenantID             uuid.UUID  `db:"tenant_id"`
+	Platform             string     `db:"platform"`
+	Name                 string     `db:"name"`
+	StoreURL             string     `db:"store_url"`
+	APIKey               string     `db:"api_key"`
+	APISecret            string     `db:"api_secret"`
+	WebhookSecret        string     `db:"webhook_secret"`
+	Enabled              bool       `db:"enabled"`
+	ImportAbandonedCarts bool       `db:"import_abandoned_carts"`
+	PipelineID           *uuid.UUID `db:"pipeline_id"`
+	OwnerID              uuid.UUID  `db:"owner_id"`
+	SyncedThrough        *time.Time `db:"synced_through"`
+	LastSyncAt           *time.Time `db:"last_sync_at"`
+	LastSyncError        string     `db:"last_sync_error"`
+	LastWebhookAt        *time.Time `db:"last_webhook_at"`
+	CreatedBy            uuid.UUID  `db:"created_by"`
+	CreatedAt            time.Time  `db:"created_at"`
+	UpdatedAt            time.Time  `db:"updated_at"`
+	Version              int        `db:"version"`
+}
+
+const ecommerceStoreColumns = `
+	id, tenant_id, platform, name, store_url, api_key, api_secret, webhook_secret,
+	enabled, import_abandoned_carts, pipeline_id, owner_id, synced_through,
+	last_sync_at, last_sync_error, last_webhook_at, created_by, created_at,
+	updated_at, version`
+
+// Create creates a new store connector.
+func (r *EcommerceStoreRepository) Create(ctx context.Context, store *domain.EcommerceStore) error {
+	exec := getExecutor(ctx, r.db)
+
+	query := `
+		INSERT INTO sales.ecommerce_stores (` + ecommerceStoreColumns + `)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20)`
+
+	_, err := exec.ExecContext(ctx, query,
+		store.ID, store.TenantID, string(store.Platform), store.Name, store.StoreURL,
+		store.APIKey, store.APISecret, store.WebhookSecret, store.Enabled,
+		store.ImportAbandonedCarts, store.PipelineID, store.OwnerID, store.SyncedThrough,
+		store.LastSyncAt, store.LastSyncError, store.LastWebhookAt, store.CreatedBy,
+		store.CreatedAt, store.UpdatedAt, store.Version,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create e-commerce store: %w", err)
+	}
+
+	return nil
+}
+
+// GetByID retrieves a store connector by ID.
+func (r *EcommerceStoreRepository) GetByID(ctx context.Context, tenantID, storeID uuid.UUID) (*domain.EcommerceStore, error) {
+	return r.getOne(ctx, `tenant_id = $1 AND id = $2`, tenantID, storeID)
+}
+
+// GetForWebhook retrieves a store connector by ID for any tenant.
+func (r *EcommerceStoreRepository) GetForWebhook(ctx context.Context, storeID uuid.UUID) (*domain.EcommerceStore, error) {
+	return r.getOne(ctx, `id = $1`, storeID)
+}
+
+// Update updates a store connector, checking the version it was loaded at.
+func (r *EcommerceStoreRepository) Update(ctx context.Context, store *domain.EcommerceStore) error {
+	exec := getExecutor(ctx, r.db)
+
+	query := `
+		UPDATE sales.ecommerce_stores SET
+			name = $3, store_url = $4, api_key = $5, api_secret = $6,
+			webhook_secret = $7, enabled = $8, import_abandoned_carts = $9,
+			pipeline_id = $10, owner_id = $11, synced_through = $12,
+			last_sync_at = $13, last_sync_error = $14, last_webhook_at = $15,
+			updated_at = $16, version = $17
+		WHERE tenant_id = $1 AND id = $2 AND version = $17 - 1`
+
+	result, err := exec.ExecContext(ctx, query,
+		store.TenantID, store.ID, store.Name, store.StoreURL, store.APIKey,
+		store.APISecret, store.WebhookSecret, store.Enabled, store.ImportAbandonedCarts,
+		store.PipelineID, store.OwnerID, store.SyncedThrough, store.LastSyncAt,
+		store.LastSyncError, store.LastWebhookAt, store.UpdatedAt, store.Version,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update e-commerce store: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return domain.ErrEcommerceStoreNotFound
+	}
+
+	return nil
+}
+
+// Delete removes a store connector and its import records.
+func (r *EcommerceStoreRepository) Delete(ctx context.Context, tenantID, storeID uuid.UUID) error {
+	exec := getExecutor(ctx, r.db)
+
+	result, err := exec.ExecContext(ctx, `DELETE FROM sales.ecommerce_stores WHERE tenant_id = $1 AND id = $2`, tenantID, storeID)
+	if err != nil {
+		return fmt.Errorf("failed to delete e-commerce store: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return domain.ErrEcommerceStoreNotFound
+	}
+
+	return nil
+}
+
+// List retrieves a tenant's store connectors, by name.
+func (r *EcommerceStoreRepository) List(ctx context.Context, tenantID uuid.UUID) ([]*domain.EcommerceStore, error) {
+	exec := getExecutor(ctx, r.db)
+
+	query := `SELECT ` + ecommerceStoreColumns + `
+		FROM sales.ecommerce_stores
+		WHERE tenant_id = $1
+		ORDER BY name, id`
+
+	var rows []ecommerceStoreRow
+	if err := sqlx.SelectContext(ctx, exec, &rows, query, tenantID); err != nil {
+		return nil, fmt.Errorf("failed to list e-commerce stores: %w", err)
+	}
+
+	return ecommerceStoresToDomain(rows), nil
+}
+
+// ListEnabled retrieves the enabled store connectors of all tenants.
+func (r *EcommerceStoreRepository) ListEnabled(ctx context.Context) ([]*domain.EcommerceStore, error) {
+	exec := getExecutor(ctx, r.db)
+
+	query := `SELECT ` + ecommerceStoreColumns + `
+		FROM sales.ecommerce_stores
+		WHERE enabled
+		ORDER BY id`
+
+	var rows []ecommerceStoreRow
+	if err := sqlx.SelectContext(ctx, exec, &rows, query); err != nil {
+		return nil, fmt.Errorf("failed to list enabled e-commerce stores: %w", err)
+	}
+
+	return ecommerceStoresToDomain(rows), nil
+}
+
+func (r *EcommerceStoreRepository) getOne(ctx context.Context, condition string, args ...interface{}) (*domain.EcommerceStore, error) {
+	exec := getExecutor(ctx, r.db)
+
+	query := `SELECT ` + ecommerceStoreColumns + `
+		FROM sales.ecommerce_stores
+		WHERE ` + condition
+
+	var row ecommerceStoreRow
+	if err := sqlx.GetContext(ctx, exec, &row, query, args...); err != nil {
+		if IsNotFoundError(err) {
+			return nil, domain.ErrEcommerceStoreNotFound
+		}
+		return nil, fmt.Errorf("failed to get e-commerce store: %w", err)
+	}
+
+	return row.toDomain(), nil
+}
+
+func ecommerceStoresToDomain(rows []ecommerceStoreRow) []*domain.EcommerceStore {
+	stores := make([]*domain.EcommerceStore, len(rows))
+	for i := range rows {
+		stores[i] = rows[i].toDomain()
+	}
+	return stores
+}
+
+func (row *ecommerceStoreRow) toDomain() *domain.EcommerceStore {
+	return &domain.EcommerceStore{
+		ID:                   row.ID,
+		TenantID:             row.TenantID,
+		Platform:             domain.EcommercePlatform(row.Platform),
+		Name:                 row.Name,
+		StoreURL:             row.StoreURL,
+		APIKey:               row.APIKey,
+		APISecret:            row.APISecret,
+		WebhookSecret:        row.WebhookSecret,
+		Enabled:              row.Enabled,
+		ImportAbandonedCarts: row.ImportAbandonedCarts,
+		PipelineID:           row.PipelineID,
+		OwnerID:              row.OwnerID,
+		SyncedThrough:        row.SyncedThrough,
+		LastSyncAt:           row.LastSyncAt,
+		LastSyncError:        row.LastSyncError,
+		LastWebhookAt:        row.LastWebhookAt,
+		CreatedBy:            row.CreatedBy,
+		CreatedAt:            row.CreatedAt,
+		UpdatedAt:            row.UpdatedAt,
+		Version:              row.Version,
+	}
+}
+
+// ============================================================================
+// E-Commerce Import Repository
+// ============================================================================
+
+// EcommerceImportRepository implements domain.EcommerceImportRepository for PostgreSQL.
+type EcommerceImportRepository struct {
+	db *sqlx.DB
+}
+
+// NewEcommerceImportRepository creates a new EcommerceImportRepository.
+func NewEcommerceImportRepository(db *sqlx.DB) *EcommerceImportRepository {
+	return &EcommerceImportRepository{db: db}
+}
+
+// ecommerceImportRow is the database representation of a store import.
+type ecommerceImportRow struct {
+	ID         uuid.UUID  `db:"id"`
+	TenantID   uuid.UUID  `db:"tenant_id"`
+	StoreID    uuid.UUID  `db:"store_id"`
+	Kind       string     `db:"kind"`
+	ExternalID string     `db:"external_id"`
+	Reference  string     `db:"reference"`
+	Email      string     `db:"email"`
+	Amount     int64      `db:"amount"`
+	Currency   string     `db:"currency"`
+	Status     string     `db:"status"`
+	CustomerID *uuid.UUID `db:"customer_id"`
+	DealID     *uuid.UUID `db:"deal_id"`
+	LeadID     *uuid.UUID `db:"lead_id"`
+	Reason     string     `db:"reason"`
+	Attempts   int        `db:"attempts"`
+	CreatedAt  time.Time  `db:"created_at"`
+	UpdatedAt  time.Time  `db:"updated_at"`
+	Version    int        `db:"version"`
+}
+
+const ecommerceImportColumns = `
+	id, tenant_id, store_id, kind, external_id, reference, email, amount, currency,
+	status, customer_id, deal_id, lead_id, reason, attempts, created_at,
+	updated_at, version`
+
+// Create creates a new store import.
+func (r *EcommerceImportRepository) Create(ctx context.Context, record *domain.EcommerceImport) error {
+	exec := getExecutor(ctx, r.db)
+
+	query := `
+		INSERT INTO sales.ecommerce_imports (` + ecommerceImportColumns + `)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18)`
+
+	_, err := exec.ExecContext(ctx, query,
+		record.ID, record.TenantID, record.StoreID, string(record.Kind), record.ExternalID,
+		record.Reference, record.Email, record.Amount.Amount, record.Amount.Currency,
+		string(record.Status), record.CustomerID, record.DealID, record.LeadID,
+		record.Reason, record.Attempts, record.CreatedAt, record.UpdatedAt, record.Version,
+	)
+	if err != nil {
+		if IsUniqueViolation(err) {
+			return domain.ErrEcommerceImportAlreadyExists
+		}
+		return fmt.Errorf("failed to create e-commerce import: %w", err)
+	}
+
+	return nil
+}
+
+// Update updates a store import, checking the version it was loaded at.
+func (r *EcommerceImportRepository) Update(ctx context.Context, record *domain.EcommerceImport) error {
+	exec := getExecutor(ctx, r.db)
+
+	query := `
+		UPDATE sales.ecommerce_imports SET
+			status = $3, customer_id = $4, deal_id = $5, lead_id = $6,
+			reason = $7, attempts = $8, updated_at = $9, version = $10
+		WHERE tenant_id = $1 AND id = $2 AND version = $10 - 1`
+
+	result, err := exec.ExecContext(ctx, query,
+		record.TenantID, record.ID, string(record.Status), record.CustomerID,
+		record.DealID, record.LeadID, record.Reason, record.Attempts,
+		record.UpdatedAt, record.Version,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update e-commerce import: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return domain.ErrEcommerceImportNotFound
+	}
+
+	return nil
+}
+
+// GetByExternalID retrieves the import of a store record, returning nil if it has none.
+func (r *EcommerceImportRepository) GetByExternalID(ctx context.Context, tenantID, storeID uuid.UUID, kind domain.EcommerceImportKind, externalID string) (*domain.EcommerceImport, error) {
+	exec := getExecutor(ctx, r.db)
+
+	query := `SELECT ` + ecommerceImportColumns + `
+		FROM sales.ecommerce_imports
+		WHERE tenant_id = $1 AND store_id = $2 AND kind = $3 AND external_id = $4`
+
+	var row ecommerceImportRow
+	if err := sqlx.GetContext(ctx, exec, &row, query, tenantID, storeID, string(kind), externalID); err != nil {
+		if IsNotFoundError(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get e-commerce import: %w", err)
+	}
+
+	return row.toDomain(), nil
+}
+
+// List retrieves a store's imports in the given statuses, most recently
+// updated first.
+func (r *EcommerceImportRepository) List(ctx context.Context, tenantID, storeID uuid.UUID, statuses []domain.EcommerceImportStatus, limit int) ([]*domain.EcommerceImport, error) {
+	exec := getExecutor(ctx, r.db)
+
+	statusValues := make([]string, len(statuses))
+	for i, status := range statuses {
+		statusValues[i] = string(status)
+	}
+
+	query := `SELECT ` + ecommerceImportColumns + `
+		FROM sales.ecommerce_imports
+		WHERE tenant_id = $1 AND store_id = $2
+		  AND (cardinality($3::text[]) = 0 OR status = ANY($3))
+		ORDER BY updated_at DESC, id
+		LIMIT $4`
+
+	var rows []ecommerceImportRow
+	if err := sqlx.SelectContext(ctx, exec, &rows, query, tenantID, storeID, pq.Array(statusValues), limit); err != nil {
+		return nil, fmt.Errorf("failed to list e-commerce imports: %w", err)
+	}
+
+	records := make([]*domain.EcommerceImport, len(rows))
+	for i := range rows {
+		records[i] = rows[i].toDomain()
+	}
+	return records, nil
+}
+
+// Summarize counts a store's imports.
+func (r *EcommerceImportRepository) Summarize(ctx context.Context, tenantID, storeID uuid.UUID) (*domain.EcommerceImportSummary, error) {
+	exec := getExecutor(ctx, r.db)
+
+	query := `
+		SELECT
+			COUNT(*) FILTER (WHERE status = 'imported' AND kind = 'order') AS orders_imported,
+			COUNT(*) FILTER (WHERE status = 'imported' AND kind = 'abandoned_cart') AS leads_created,
+			COUNT(*) FILTER (WHERE status = 'skipped') AS skipped,
+			COUNT(*) FILTER (WHERE status = 'failed') AS failed,
+			MAX(updated_at) FILTER (WHERE status = 'imported') AS last_imported_at
+		FROM sales.ecommerce_imports
+		WHERE tenant_id = $1 AND store_id = $2`
+
+	var row struct {
+		OrdersImported int        `db:"orders_imported"`
+		LeadsCreated   int        `db:"leads_created"`
+		Skipped        int        `db:"skipped"`
+		Failed         int        `db:"failed"`
+		LastImportedAt *time.Time `db:"last_imported_at"`
+	}
+	if err := sqlx.GetContext(ctx, exec, &row, query, tenantID, storeID); err != nil {
+		return nil, fmt.Errorf("failed to summarize e-commerce imports: %w", err)
+	}
+
+	return &domain.EcommerceImportSummary{
+		OrdersImported: row.OrdersImported,
+		LeadsCreated:   row.LeadsCreated,
+		Skipped:        row.Skipped,
+		Failed:         row.Failed,
+		LastImportedAt: row.LastImportedAt,
+	}, nil
+}
+
+func (row *ecommerceImportRow) toDomain() *domain.EcommerceImport {
+	return &domain.EcommerceImport{
+		ID:         row.ID,
+		TenantID:   row.TenantID,
+		StoreID:    row.StoreID,
+		Kind:       domain.EcommerceImportKind(row.Kind),
+		ExternalID: row.ExternalID,
+		Reference:  row.Reference,
+		Email:      row.Email,
+		Amount:     domain.Money{Amount: row.Amount, Currency: row.Currency},
+		Status:     domain.EcommerceImportStatus(row.Status),
+		CustomerID: row.CustomerID,
+		DealID:     row.DealID,
+		LeadID:     row.LeadID,
+		Reason:     row.Reason,
+		Attempts:   row.Attempts,
+		CreatedAt:  row.CreatedAt,
+		UpdatedAt:  row.UpdatedAt,
+		Version:    row.Version,
+	}
+}
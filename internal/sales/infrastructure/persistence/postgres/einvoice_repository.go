@@ -0,0 +1,365 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+
+	"github.com/kilang-desa-murni/crm/internal/sales/domain"
+)
+
+// ============================================================================
+// E-Invoice Repository
+// ============================================================================
+
+// EInvoiceRepository implements domain.EInvoiceRepository for PostgreSQL.
+type EInvoiceRepository struct {
+	db *sqlx.DB
+}
+
+// NewEInvoiceRepository creates a new EInvoiceRepository.
+func NewEInvoiceRepository(db *sqlx.DB) *EInvoiceRepository {
+	return &EInvoiceRepository{db: db}
+}
+
+// einvoiceRow is the database representation of an e-invoice.
+type einvoiceRow struct {
+	ID               uuid.UUID    `db:"id"`
+	TenantID         uuid.UUID    `db:"tenant_id"`
+	DealID           uuid.UUID    `db:"deal_id"`
+	InvoiceID        uuid.UUID    `db:"invoice_id"`
+	InvoiceNumber    string       `db:"invoice_number"`
+	CustomerID       uuid.UUID    `db:"customer_id"`
+	Buyer            NullableJSON `db:"buyer"`
+	Status           string       `db:"status"`
+	SubmissionUID    string       `db:"submission_uid"`
+	DocumentUUID     string       `db:"document_uuid"`
+	LongID           string       `db:"long_id"`
+	ValidationURL    string       `db:"validation_url"`
+	ValidationErrors NullableJSON `db:"validation_errors"`
+	Attempts         int          `db:"attempts"`
+	NextAttemptAt    *time.Time   `db:"next_attempt_at"`
+	LastError        string       `db:"last_error"`
+	SubmittedAt      *time.Time   `db:"submitted_at"`
+	ValidatedAt      *time.Time   `db:"validated_at"`
+	CancelledAt      *time.Time   `db:"cancelled_at"`
+	CancelReason     string       `db:"cancel_reason"`
+	CreatedBy        uuid.UUID    `db:"created_by"`
+	CreatedAt        time.Time    `db:"created_at"`
+	UpdatedAt        time.Time    `db:"updated_at"`
+	Version          int          `db:"version"`
+}
+
+const einvoiceColumns = `
+	id, tenant_id, deal_id, invoice_id, invoice_number, customer_id, buyer, status,
+	submission_uid, document_uuid, long_id, validation_url, validation_errors,
+	attempts, next_attempt_at, last_error, submitted_at, validated_at, cancelled_at,
+	cancel_reason, created_by, created_at, updated_at, version`
+
+// Create creates a new e-invoice.
+func (r *EInvoiceRepository) Create(ctx context.Context, einvoice *domain.EInvoice) error {
+	exec := getExecutor(ctx, r.db)
+
+	buyerJSON, errorsJSON, err := einvoiceJSON(einvoice)
+	if err != nil {
+		return err
+	}
+
+	query := `
+		INSERT INTO sales.einvoices (` + einvoiceColumns + `)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, $21, $22, $23, $24)`
+
+	_, err = exec.ExecContext(ctx, query,
+		einvoice.ID, einvoice.TenantID, einvoice.DealID, einvoice.InvoiceID, einvoice.InvoiceNumber,
+		einvoice.CustomerID, buyerJSON, string(einvoice.Status), einvoice.SubmissionUID,
+		einvoice.DocumentUUID, einvoice.LongID, einvoice.ValidationURL, errorsJSON,
+		einvoice.Attempts, einvoice.NextAttemptAt, einvoice.LastError, einvoice.SubmittedAt,
+		einvoice.ValidatedAt, einvoice.CancelledAt, einvoice.CancelReason, einvoice.CreatedBy,
+		einvoice.CreatedAt, einvoice.UpdatedAt, einvoice.Version,
+	)
+	if err != nil {
+		if IsUniqueViolation(err) {
+			return domain.ErrEInvoiceAlreadySubmitted
+		}
+		return fmt.Errorf("failed to create e-invoice: %w", err)
+	}
+
+	return nil
+}
+
+// GetByID retrieves an e-invoice by ID.
+func (r *EInvoiceRepository) GetByID(ctx context.Context, tenantID, einvoiceID uuid.UUID) (*domain.EInvoice, error) {
+	exec := getExecutor(ctx, r.db)
+
+	query := `SELECT ` + einvoiceColumns + `
+		FROM sales.einvoices
+		WHERE tenant_id = $1 AND id = $2`
+
+	var row einvoiceRow
+	if err := sqlx.GetContext(ctx, exec, &row, query, tenantID, einvoiceID); err != nil {
+		if IsNotFoundError(err) {
+			return nil, domain.ErrEInvoiceNotFound
+		}
+		return nil, fmt.Errorf("failed to get e-invoice: %w", err)
+	}
+
+	return row.toDomain()
+}
+
+// Update updates an e-invoice, checking the version it was loaded at.
+func (r *EInvoiceRepository) Update(ctx context.Context, einvoice *domain.EInvoice) error {
+	exec := getExecutor(ctx, r.db)
+
+	buyerJSON, errorsJSON, err := einvoiceJSON(einvoice)
+	if err != nil {
+		return err
+	}
+
+	query := `
+		UPDATE sales.einvoices SET
+			buyer = $3, status = $4, submission_uid = $5, document_uuid = $6,
+			long_id = $7, validation_url = $8, validation_errors = $9, attempts = $10,
+			next_attempt_at = $11, last_error = $12, submitted_at = $13,
+			validated_at = $14, cancelled_at = $15, cancel_reason = $16,
+			updated_at = $17, version = $18
+		WHERE tenant_id = $1 AND id = $2 AND version = $18 - 1`
+
+	result, err := exec.ExecContext(ctx, query,
+		einvoice.TenantID, einvoice.ID, buyerJSON, string(einvoice.Status),
+		einvoice.SubmissionUID, einvoice.DocumentUUID, einvoice.LongID, einvoice.ValidationURL,
+		errorsJSON, einvoice.Attempts, einvoice.NextAttemptAt, einvoice.LastError,
+		einvoice.SubmittedAt, einvoice.ValidatedAt, einvoice.CancelledAt, einvoice.CancelReason,
+		einvoice.UpdatedAt, einvoice.Version,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update e-invoice: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return domain.ErrEInvoiceNotFound
+	}
+
+	return nil
+}
+
+// GetByInvoice retrieves the latest e-invoice of a deal invoice.
+func (r *EInvoiceRepository) GetByInvoice(ctx context.Context, tenantID, invoiceID uuid.UUID) (*domain.EInvoice, error) {
+	exec := getExecutor(ctx, r.db)
+
+	query := `SELECT ` + einvoiceColumns + `
+		FROM sales.einvoices
+		WHERE tenant_id = $1 AND invoice_id = $2
+		ORDER BY created_at DESC, id DESC
+		LIMIT 1`
+
+	var row einvoiceRow
+	if err := sqlx.GetContext(ctx, exec, &row, query, tenantID, invoiceID); err != nil {
+		if IsNotFoundError(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get invoice e-invoice: %w", err)
+	}
+
+	return row.toDomain()
+}
+
+// GetDue retrieves e-invoices of all tenants pending submission or awaiting
+// validation that are due an attempt, earliest first.
+func (r *EInvoiceRepository) GetDue(ctx context.Context, dueBy time.Time, limit int) ([]*domain.EInvoice, error) {
+	exec := getExecutor(ctx, r.db)
+
+	query := `SELECT ` + einvoiceColumns + `
+		FROM sales.einvoices
+		WHERE status IN ('pending', 'submitted')
+		  AND next_attempt_at <= $1
+		ORDER BY next_attempt_at, id
+		LIMIT $2`
+
+	var rows []einvoiceRow
+	if err := sqlx.SelectContext(ctx, exec, &rows, query, dueBy, limit); err != nil {
+		return nil, fmt.Errorf("failed to get e-invoices due: %w", err)
+	}
+
+	einvoices := make([]*domain.EInvoice, len(rows))
+	for i := range rows {
+		einvoice, err := rows[i].toDomain()
+		if err != nil {
+			return nil, err
+		}
+		einvoices[i] = einvoice
+	}
+	return einvoices, nil
+}
+
+func einvoiceJSON(einvoice *domain.EInvoice) (interface{}, interface{}, error) {
+	buyerJSON, err := ToJSON(einvoice.Buyer)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to marshal e-invoice buyer: %w", err)
+	}
+	validationErrors := einvoice.ValidationErrors
+	if validationErrors == nil {
+		validationErrors = []string{}
+	}
+	errorsJSON, err := ToJSON(validationErrors)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to marshal e-invoice validation errors: %w", err)
+	}
+	return buyerJSON, errorsJSON, nil
+}
+
+func (row *einvoiceRow) toDomain() (*domain.EInvoice, error) {
+	einvoice := &domain.EInvoice{
+		ID:               row.ID,
+		TenantID:         row.TenantID,
+		DealID:           row.DealID,
+		InvoiceID:        row.InvoiceID,
+		InvoiceNumber:    row.InvoiceNumber,
+		CustomerID:       row.CustomerID,
+		Status:           domain.EInvoiceStatus(row.Status),
+		SubmissionUID:    row.SubmissionUID,
+		DocumentUUID:     row.DocumentUUID,
+		LongID:           row.LongID,
+		ValidationURL:    row.ValidationURL,
+		ValidationErrors: []string{},
+		Attempts:         row.Attempts,
+		NextAttemptAt:    row.NextAttemptAt,
+		LastError:        row.LastError,
+		SubmittedAt:      row.SubmittedAt,
+		ValidatedAt:      row.ValidatedAt,
+		CancelledAt:      row.CancelledAt,
+		CancelReason:     row.CancelReason,
+		CreatedBy:        row.CreatedBy,
+		CreatedAt:        row.CreatedAt,
+		UpdatedAt:        row.UpdatedAt,
+		Version:          row.Version,
+	}
+	if err := row.Buyer.MarshalTo(&einvoice.Buyer); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal e-invoice buyer: %w", err)
+	}
+	if err := row.ValidationErrors.MarshalTo(&einvoice.ValidationErrors); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal e-invoice validation errors: %w", err)
+	}
+	return einvoice, nil
+}
+
+// ============================================================================
+// E-Invoice Settings Repository
+// ============================================================================
+
+// EInvoiceSettingsRepository implements domain.EInvoiceSettingsRepository for PostgreSQL.
+type EInvoiceSettingsRepository struct {
+	db *sqlx.DB
+}
+
+// NewEInvoiceSettingsRepository creates a new EInvoiceSettingsRepository.
+func NewEInvoiceSettingsRepository(db *sqlx.DB) *EInvoiceSettingsRepository {
+	return &EInvoiceSettingsRepository{db: db}
+}
+
+// einvoiceSettingsRow is the database representation of e-invoice settings.
+type einvoiceSettingsRow struct {
+	TenantID           uuid.UUID    `db:"tenant_id"`
+	Enabled            bool         `db:"enabled"`
+	Name               string       `db:"name"`
+	TIN                string       `db:"tin"`
+	RegistrationNumber string       `db:"registration_number"`
+	SSTNumber          string       `db:"sst_number"`
+	MSICCode           string       `db:"msic_code"`
+	BusinessActivity   string       `db:"business_activity"`
+	Email              string       `db:"email"`
+	Phone              string       `db:"phone"`
+	Address            NullableJSON `db:"address"`
+	UpdatedAt          time.Time    `db:"updated_at"`
+}
+
+// Get retrieves a tenant's e-invoice settings, returning nil if none are configured.
+func (r *EInvoiceSettingsRepository) Get(ctx context.Context, tenantID uuid.UUID) (*domain.EInvoiceSettings, error) {
+	exec := getExecutor(ctx, r.db)
+
+	query := `
+		SELECT tenant_id, enabled, name, tin, registration_number, sst_number, msic_code,
+			business_activity, email, phone, address, updated_at
+		FROM sales.einvoice_settings
+		WHERE tenant_id = $1`
+
+	var row einvoiceSettingsRow
+	if err := sqlx.GetContext(ctx, exec, &row, query, tenantID); err != nil {
+		if IsNotFoundError(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get e-invoice settings: %w", err)
+	}
+
+	settings := &domain.EInvoiceSettings{
+		TenantID:           row.TenantID,
+		Enabled:            row.Enabled,
+		Name:               row.Name,
+		TIN:                row.TIN,
+		RegistrationNumber: row.RegistrationNumber,
+		SSTNumber:          row.SSTNumber,
+		MSICCode:           row.MSICCode,
+		BusinessActivity:   row.BusinessActivity,
+		Email:              row.Email,
+		Phone:              row.Phone,
+		UpdatedAt:          row.UpdatedAt,
+	}
+	if err := row.Address.MarshalTo(&settings.Address); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal e-invoice address: %w", err)
+	}
+
+	return settings, nil
+}
+
+// Upsert creates or updates a tenant's e-invoice settings.
+func (r *EInvoiceSettingsRepository) Upsert(ctx context.Context, settings *domain.EInvoiceSettings) error {
+	exec := getExecutor(ctx, r.db)
+
+	addressJSON, err := ToJSON(settings.Address)
+	if err != nil {
+		return fmt.Errorf("failed to marshal e-invoice address: %w", err)
+	}
+
+	query := `
+		INSERT INTO sales.einvoice_settings (
+			tenant_id, enabled, name, tin, registration_number, sst_number, msic_code,
+			business_activity, email, phone, address, updated_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+		ON CONFLICT (tenant_id) DO UPDATE SET
+			enabled = EXCLUDED.enabled,
+			name = EXCLUDED.name,
+			tin = EXCLUDED.tin,
+			registration_number = EXCLUDED.registration_number,
+			sst_number = EXCLUDED.sst_number,
+			msic_code = EXCLUDED.msic_code,
+			business_activity = EXCLUDED.business_activity,
+			email = EXCLUDED.email,
+			phone = EXCLUDED.phone,
+			address = EXCLUDED.address,
+			updated_at = EXCLUDED.updated_at`
+
+	_, err = exec.ExecContext(ctx, query,
+		settings.TenantID, settings.Enabled, settings.Name, settings.TIN,
+		settings.RegistrationNumber, settings.SSTNumber, settings.MSICCode,
+		settings.BusinessActivity, settings.Email, settings.Phone, addressJSON,
+		settings.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to upsert e-invoice settings: %w", err)
+	}
+
+	return nil
+}
+
+// Ensure the repositories implement their domain interfaces
+var (
+	_ domain.EInvoiceRepository         = (*EInvoiceRepository)(nil)
+	_ domain.EInvoiceSettingsRepository = (*EInvoiceSettingsRepository)(nil)
+)
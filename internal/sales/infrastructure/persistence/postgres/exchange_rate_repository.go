@@ -0,0 +1,238 @@
+// Package postgres contains PostgreSQL repository implementations for the sales service.
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+
+	"github.com/kilang-desa-murni/crm/internal/sales/domain"
+)
+
+// ExchangeRateRepository implements domain.ExchangeRateRepository for PostgreSQL.
+type ExchangeRateRepository struct {
+	db *sqlx.DB
+}
+
+// NewExchangeRateRepository creates a new ExchangeRateRepository.
+func NewExchangeRateRepository(db *sqlx.DB) *ExchangeRateRepository {
+	return &ExchangeRateRepository{db: db}
+}
+
+const exchangeRateColumns = `
+	id, tenant_id, from_currency, to_currency, rate, effective_date, source,
+	created_by, created_at, updated_at`
+
+// allowedExchangeRateSortColumns lists the columns exchange rates can be sorted by.
+var allowedExchangeRateSortColumns = map[string]string{
+	"effective_date": "effective_date",
+	"from_currency":  "from_currency",
+	"to_currency":    "to_currency",
+	"created_at":     "created_at",
+}
+
+// Upsert creates or replaces the rate for a pair, effective date and source.
+func (r *ExchangeRateRepository) Upsert(ctx context.Context, rate *domain.ExchangeRate) error {
+	exec := getExecutor(ctx, r.db)
+
+	query := `
+		INSERT INTO sales.exchange_rates (` + exchangeRateColumns + `)
+		VALUES (
+			:id, :tenant_id, :from_currency, :to_currency, :rate, :effective_date, :source,
+			:created_by, :created_at, :updated_at
+		)
+		ON CONFLICT (tenant_id, from_currency, to_currency, effective_date, source) DO UPDATE SET
+			rate = EXCLUDED.rate,
+			created_by = EXCLUDED.created_by,
+			updated_at = EXCLUDED.updated_at
+		RETURNING id, created_at`
+
+	rows, err := sqlx.NamedQueryContext(ctx, exec, query, rate)
+	if err != nil {
+		return fmt.Errorf("failed to upsert exchange rate: %w", err)
+	}
+	defer rows.Close()
+
+	// An existing rate keeps its identity
+	if rows.Next() {
+		if err := rows.Scan(&rate.ID, &rate.CreatedAt); err != nil {
+			return fmt.Errorf("failed to upsert exchange rate: %w", err)
+		}
+	}
+
+	return rows.Err()
+}
+
+// GetByID retrieves an exchange rate by ID.
+func (r *ExchangeRateRepository) GetByID(ctx context.Context, tenantID, rateID uuid.UUID) (*domain.ExchangeRate, error) {
+	exec := getExecutor(ctx, r.db)
+
+	query := `SELECT ` + exchangeRateColumns + `
+		FROM sales.exchange_rates
+		WHERE tenant_id = $1 AND id = $2`
+
+	var rate domain.ExchangeRate
+	if err := sqlx.GetContext(ctx, exec, &rate, query, tenantID, rateID); err != nil {
+		if IsNotFoundError(err) {
+			return nil, domain.ErrExchangeRateNotFound
+		}
+		return nil, fmt.Errorf("failed to get exchange rate: %w", err)
+	}
+
+	return &rate, nil
+}
+
+// Delete deletes an exchange rate.
+func (r *ExchangeRateRepository) Delete(ctx context.Context, tenantID, rateID uuid.UUID) error {
+	exec := getExecutor(ctx, r.db)
+
+	query := `DELETE FROM sales.exchange_rates WHERE tenant_id = $1 AND id = $2`
+
+	result, err := exec.ExecContext(ctx, query, tenantID, rateID)
+	if err != nil {
+		return fmt.Errorf("failed to delete exchange rate: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return domain.ErrExchangeRateNotFound
+	}
+
+	return nil
+}
+
+// List lists exchange rates, newest effective date first.
+func (r *ExchangeRateRepository) List(ctx context.Context, tenantID uuid.UUID, filter domain.ExchangeRateFilter, opts domain.ListOptions) ([]*domain.ExchangeRate, int64, error) {
+	exec := getExecutor(ctx, r.db)
+
+	baseQuery := `SELECT ` + exchangeRateColumns + `
+		FROM sales.exchange_rates
+		WHERE tenant_id = $1`
+
+	qb := NewQueryBuilder(baseQuery)
+	qb.args = append(qb.args, tenantID)
+
+	if filter.FromCurrency != nil {
+		qb.Where(fmt.Sprintf("from_currency = $%d", qb.NextParam()), *filter.FromCurrency)
+	}
+	if filter.ToCurrency != nil {
+		qb.Where(fmt.Sprintf("to_currency = $%d", qb.NextParam()), *filter.ToCurrency)
+	}
+	if filter.Source != nil {
+		qb.Where(fmt.Sprintf("source = $%d", qb.NextParam()), string(*filter.Source))
+	}
+
+	countQuery, countArgs := qb.BuildCount()
+	var total int64
+	if err := sqlx.GetContext(ctx, exec, &total, countQuery, countArgs...); err != nil {
+		return nil, 0, fmt.Errorf("failed to count exchange rates: %w", err)
+	}
+
+	sortBy := opts.SortBy
+	if sortBy == "" {
+		sortBy = "effective_date"
+	}
+	qb.OrderBy(ValidateSortColumn(sortBy, allowedExchangeRateSortColumns), ValidateSortOrder(opts.SortOrder))
+	qb.Limit(opts.Limit())
+	qb.Offset(opts.Offset())
+
+	query, args := qb.Build()
+
+	var rates []*domain.ExchangeRate
+	if err := sqlx.SelectContext(ctx, exec, &rates, query, args...); err != nil {
+		return nil, 0, fmt.Errorf("failed to list exchange rates: %w", err)
+	}
+
+	return rates, total, nil
+}
+
+// ListEffective returns the latest rate of every pair effective at the given time,
+// with manual rates ordered before provider rates.
+func (r *ExchangeRateRepository) ListEffective(ctx context.Context, tenantID uuid.UUID, at time.Time) ([]*domain.ExchangeRate, error) {
+	exec := getExecutor(ctx, r.db)
+
+	query := `
+		SELECT ` + exchangeRateColumns + `
+		FROM (
+			SELECT DISTINCT ON (from_currency, to_currency) ` + exchangeRateColumns + `
+			FROM sales.exchange_rates
+			WHERE tenant_id = $1 AND effective_date <= $2
+			ORDER BY from_currency, to_currency, effective_date DESC, (source = 'manual') DESC, updated_at DESC
+		) effective
+		ORDER BY (source = 'manual') DESC, effective_date DESC`
+
+	var rates []*domain.ExchangeRate
+	if err := sqlx.SelectContext(ctx, exec, &rates, query, tenantID, at); err != nil {
+		return nil, fmt.Errorf("failed to list effective exchange rates: %w", err)
+	}
+
+	return rates, nil
+}
+
+// GetSettings retrieves a tenant's currency settings, returning nil if none are configured.
+func (r *ExchangeRateRepository) GetSettings(ctx context.Context, tenantID uuid.UUID) (*domain.CurrencySettings, error) {
+	exec := getExecutor(ctx, r.db)
+
+	query := `
+		SELECT tenant_id, base_currency, rate_provider, last_fetched_at, updated_at
+		FROM sales.currency_settings
+		WHERE tenant_id = $1`
+
+	var settings domain.CurrencySettings
+	if err := sqlx.GetContext(ctx, exec, &settings, query, tenantID); err != nil {
+		if IsNotFoundError(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get currency settings: %w", err)
+	}
+
+	return &settings, nil
+}
+
+// SaveSettings creates or updates a tenant's currency settings.
+func (r *ExchangeRateRepository) SaveSettings(ctx context.Context, settings *domain.CurrencySettings) error {
+	exec := getExecutor(ctx, r.db)
+
+	query := `
+		INSERT INTO sales.currency_settings (tenant_id, base_currency, rate_provider, last_fetched_at, updated_at)
+		VALUES (:tenant_id, :base_currency, :rate_provider, :last_fetched_at, :updated_at)
+		ON CONFLICT (tenant_id) DO UPDATE SET
+			base_currency = EXCLUDED.base_currency,
+			rate_provider = EXCLUDED.rate_provider,
+			last_fetched_at = EXCLUDED.last_fetched_at,
+			updated_at = EXCLUDED.updated_at`
+
+	if _, err := sqlx.NamedExecContext(ctx, exec, query, settings); err != nil {
+		return fmt.Errorf("failed to save currency settings: %w", err)
+	}
+
+	return nil
+}
+
+// ListSettingsWithProvider returns the settings of all tenants with a rate provider configured.
+func (r *ExchangeRateRepository) ListSettingsWithProvider(ctx context.Context) ([]*domain.CurrencySettings, error) {
+	exec := getExecutor(ctx, r.db)
+
+	query := `
+		SELECT tenant_id, base_currency, rate_provider, last_fetched_at, updated_at
+		FROM sales.currency_settings
+		WHERE rate_provider IS NOT NULL
+		ORDER BY last_fetched_at NULLS FIRST`
+
+	var settings []*domain.CurrencySettings
+	if err := sqlx.SelectContext(ctx, exec, &settings, query); err != nil {
+		return nil, fmt.Errorf("failed to list currency settings: %w", err)
+	}
+
+	return settings, nil
+}
+
+// Ensure ExchangeRateRepository implements domain.ExchangeRateRepository
+var _ domain.ExchangeRateRepository = (*ExchangeRateRepository)(nil)
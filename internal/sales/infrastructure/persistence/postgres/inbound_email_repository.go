@@ -0,0 +1,253 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+
+	"github.com/kilang-desa-murni/crm/internal/sales/domain"
+)
+
+// ============================================================================
+// Inbound Mailbox Repository
+// ============================================================================
+
+// InboundMailboxRepository implements domain.InboundMailboxRepository for PostgreSQL.
+type InboundMailboxRepository struct {
+	db *sqlx.DB
+}
+
+// NewInboundMailboxRepository creates a new InboundMailboxRepository.
+func NewInboundMailboxRepository(db *sqlx.DB) *InboundMailboxRepository {
+	return &InboundMailboxRepository{db: db}
+}
+
+const inboundMailboxColumns = `tenant_id, token, enabled, default_owner_id, created_at, updated_at`
+
+// Get retrieves a tenant's mailbox, returning nil if none has been created.
+func (r *InboundMailboxRepository) Get(ctx context.Context, tenantID uuid.UUID) (*domain.InboundMailbox, error) {
+	exec := getExecutor(ctx, r.db)
+
+	query := `SELECT ` + inboundMailboxColumns + `
+		FROM sales.inbound_mailboxes
+		WHERE tenant_id = $1`
+
+	var mailbox domain.InboundMailbox
+	if err := sqlx.GetContext(ctx, exec, &mailbox, query, tenantID); err != nil {
+		if IsNotFoundError(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get inbound mailbox: %w", err)
+	}
+
+	return &mailbox, nil
+}
+
+// GetByToken retrieves a mailbox by its address token, across tenants.
+func (r *InboundMailboxRepository) GetByToken(ctx context.Context, token string) (*domain.InboundMailbox, error) {
+	exec := getExecutor(ctx, r.db)
+
+	query := `SELECT ` + inboundMailboxColumns + `
+		FROM sales.inbound_mailboxes
+		WHERE token = $1`
+
+	var mailbox domain.InboundMailbox
+	if err := sqlx.GetContext(ctx, exec, &mailbox, query, token); err != nil {
+		if IsNotFoundError(err) {
+			return nil, domain.ErrInboundMailboxNotFound
+		}
+		return nil, fmt.Errorf("failed to get inbound mailbox by token: %w", err)
+	}
+
+	return &mailbox, nil
+}
+
+// Upsert creates or updates a tenant's mailbox.
+func (r *InboundMailboxRepository) Upsert(ctx context.Context, mailbox *domain.InboundMailbox) error {
+	exec := getExecutor(ctx, r.db)
+
+	query := `
+		INSERT INTO sales.inbound_mailboxes (` + inboundMailboxColumns + `)
+		VALUES (:tenant_id, :token, :enabled, :default_owner_id, :created_at, :updated_at)
+		ON CONFLICT (tenant_id) DO UPDATE SET
+			token = EXCLUDED.token,
+			enabled = EXCLUDED.enabled,
+			default_owner_id = EXCLUDED.default_owner_id,
+			updated_at = EXCLUDED.updated_at`
+
+	if _, err := sqlx.NamedExecContext(ctx, exec, query, mailbox); err != nil {
+		return fmt.Errorf("failed to upsert inbound mailbox: %w", err)
+	}
+
+	return nil
+}
+
+// Ensure InboundMailboxRepository implements domain.InboundMailboxRepository
+var _ domain.InboundMailboxRepository = (*InboundMailboxRepository)(nil)
+
+// ============================================================================
+// Inbound Email Repository
+// ============================================================================
+
+// InboundEmailRepository implements domain.InboundEmailRepository for PostgreSQL.
+type InboundEmailRepository struct {
+	db *sqlx.DB
+}
+
+// NewInboundEmailRepository creates a new InboundEmailRepository.
+func NewInboundEmailRepository(db *sqlx.DB) *InboundEmailRepository {
+	return &InboundEmailRepository{db: db}
+}
+
+// inboundEmailRow is the database representation of an inbound email.
+type inboundEmailRow struct {
+	ID            uuid.UUID      `db:"id"`
+	TenantID      uuid.UUID      `db:"tenant_id"`
+	MessageID     string         `db:"message_id"`
+	FromAddress   string         `db:"from_address"`
+	FromName      sql.NullString `db:"from_name"`
+	Subject       string         `db:"subject"`
+	Body          string         `db:"body"`
+	LeadID        uuid.NullUUID  `db:"lead_id"`
+	OpportunityID uuid.NullUUID  `db:"opportunity_id"`
+	LeadCreated   bool           `db:"lead_created"`
+	AttachmentIDs NullableJSON   `db:"attachment_ids"`
+	ReceivedAt    time.Time      `db:"received_at"`
+	CreatedAt     time.Time      `db:"created_at"`
+}
+
+const inboundEmailColumns = `
+	id, tenant_id, message_id, from_address, from_name, subject, body,
+	lead_id, opportunity_id, lead_created, attachment_ids, received_at, created_at`
+
+// Create records an ingested email.
+func (r *InboundEmailRepository) Create(ctx context.Context, email *domain.InboundEmail) error {
+	exec := getExecutor(ctx, r.db)
+
+	attachmentIDs := email.AttachmentIDs
+	if attachmentIDs == nil {
+		attachmentIDs = []string{}
+	}
+	attachmentsJSON, err := ToJSON(attachmentIDs)
+	if err != nil {
+		return fmt.Errorf("failed to marshal attachment IDs: %w", err)
+	}
+
+	query := `
+		INSERT INTO sales.inbound_emails (` + inboundEmailColumns + `)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)`
+
+	_, err = exec.ExecContext(ctx, query,
+		email.ID, email.TenantID, email.MessageID, email.FromAddress, nullString(email.FromName),
+		email.Subject, email.Body, nullUUID(email.LeadID), nullUUID(email.OpportunityID),
+		email.LeadCreated, attachmentsJSON, email.ReceivedAt, email.CreatedAt,
+	)
+	if err != nil {
+		if IsUniqueViolation(err) {
+			return domain.ErrInboundEmailDuplicate
+		}
+		return fmt.Errorf("failed to create inbound email: %w", err)
+	}
+
+	return nil
+}
+
+// ExistsByMessageID reports whether the tenant already received a message.
+func (r *InboundEmailRepository) ExistsByMessageID(ctx context.Context, tenantID uuid.UUID, messageID string) (bool, error) {
+	exec := getExecutor(ctx, r.db)
+
+	query := `
+		SELECT EXISTS (
+			SELECT 1 FROM sales.inbound_emails
+			WHERE tenant_id = $1 AND message_id = $2
+		)`
+
+	var exists bool
+	if err := sqlx.GetContext(ctx, exec, &exists, query, tenantID, messageID); err != nil {
+		return false, fmt.Errorf("failed to check inbound email: %w", err)
+	}
+
+	return exists, nil
+}
+
+// CountFromSenderSince counts the emails ingested from a sender since a time.
+func (r *InboundEmailRepository) CountFromSenderSince(ctx context.Context, tenantID uuid.UUID, fromAddress string, since time.Time) (int, error) {
+	exec := getExecutor(ctx, r.db)
+
+	query := `
+		SELECT COUNT(*) FROM sales.inbound_emails
+		WHERE tenant_id = $1 AND from_address = $2 AND created_at >= $3`
+
+	var count int
+	if err := sqlx.GetContext(ctx, exec, &count, query, tenantID, fromAddress, since); err != nil {
+		return 0, fmt.Errorf("failed to count inbound emails: %w", err)
+	}
+
+	return count, nil
+}
+
+// ListByLead lists the emails logged on a lead, newest first.
+func (r *InboundEmailRepository) ListByLead(ctx context.Context, tenantID, leadID uuid.UUID, opts domain.ListOptions) ([]*domain.InboundEmail, int64, error) {
+	exec := getExecutor(ctx, r.db)
+
+	countQuery := `SELECT COUNT(*) FROM sales.inbound_emails WHERE tenant_id = $1 AND lead_id = $2`
+
+	var total int64
+	if err := sqlx.GetContext(ctx, exec, &total, countQuery, tenantID, leadID); err != nil {
+		return nil, 0, fmt.Errorf("failed to count lead emails: %w", err)
+	}
+
+	query := `SELECT ` + inboundEmailColumns + `
+		FROM sales.inbound_emails
+		WHERE tenant_id = $1 AND lead_id = $2
+		ORDER BY received_at DESC
+		LIMIT $3 OFFSET $4`
+
+	var rows []inboundEmailRow
+	if err := sqlx.SelectContext(ctx, exec, &rows, query, tenantID, leadID, opts.Limit(), opts.Offset()); err != nil {
+		return nil, 0, fmt.Errorf("failed to list lead emails: %w", err)
+	}
+
+	emails := make([]*domain.InboundEmail, 0, len(rows))
+	for i := range rows {
+		email, err := r.toDomain(&rows[i])
+		if err != nil {
+			return nil, 0, err
+		}
+		emails = append(emails, email)
+	}
+
+	return emails, total, nil
+}
+
+func (r *InboundEmailRepository) toDomain(row *inboundEmailRow) (*domain.InboundEmail, error) {
+	email := &domain.InboundEmail{
+		ID:          row.ID,
+		TenantID:    row.TenantID,
+		MessageID:   row.MessageID,
+		FromAddress: row.FromAddress,
+		FromName:    nullStringValue(row.FromName),
+		Subject:     row.Subject,
+		Body:        row.Body,
+		LeadCreated: row.LeadCreated,
+		ReceivedAt:  row.ReceivedAt,
+		CreatedAt:   row.CreatedAt,
+	}
+	if row.LeadID.Valid {
+		email.LeadID = &row.LeadID.UUID
+	}
+	if row.OpportunityID.Valid {
+		email.OpportunityID = &row.OpportunityID.UUID
+	}
+	if err := row.AttachmentIDs.MarshalTo(&email.AttachmentIDs); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal attachment IDs: %w", err)
+	}
+	return email, nil
+}
+
+// Ensure InboundEmailRepository implements domain.InboundEmailRepository
+var _ domain.InboundEmailRepository = (*InboundEmailRepository)(nil)
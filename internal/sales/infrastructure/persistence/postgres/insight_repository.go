@@ -0,0 +1,249 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+
+	"github.com/kilang-desa-murni/crm/internal/sales/domain"
+)
+
+// InsightRepository implements domain.InsightRepository for PostgreSQL.
+type InsightRepository struct {
+	db *sqlx.DB
+}
+
+// NewInsightRepository creates a new InsightRepository.
+func NewInsightRepository(db *sqlx.DB) *InsightRepository {
+	return &InsightRepository{db: db}
+}
+
+// insightRow is the database representation of an insight.
+type insightRow struct {
+	ID         uuid.UUID    `db:"id"`
+	TenantID   uuid.UUID    `db:"tenant_id"`
+	Type       string       `db:"type"`
+	PeriodKey  string       `db:"period_key"`
+	Details    NullableJSON `db:"details"`
+	DetectedAt time.Time    `db:"detected_at"`
+}
+
+// GetSettings retrieves a tenant's insight settings, returning nil if none are configured.
+func (r *InsightRepository) GetSettings(ctx context.Context, tenantID uuid.UUID) (*domain.InsightSettings, error) {
+	exec := getExecutor(ctx, r.db)
+
+	query := `
+		SELECT tenant_id, biggest_deal_won, low_pipeline_coverage, lead_volume_drop, currency,
+			quarterly_quota, minimum_coverage::float8 AS minimum_coverage,
+			lead_drop_percent::float8 AS lead_drop_percent, minimum_weekly_leads, updated_by, updated_at
+		FROM sales.insight_settings
+		WHERE tenant_id = $1`
+
+	var settings domain.InsightSettings
+	if err := sqlx.GetContext(ctx, exec, &settings, query, tenantID); err != nil {
+		if IsNotFoundError(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get insight settings: %w", err)
+	}
+
+	return &settings, nil
+}
+
+// UpsertSettings creates or updates a tenant's insight settings.
+func (r *InsightRepository) UpsertSettings(ctx context.Context, settings *domain.InsightSettings) error {
+	exec := getExecutor(ctx, r.db)
+
+	query := `
+		INSERT INTO sales.insight_settings (
+			tenant_id, biggest_deal_won, low_pipeline_coverage, lead_volume_drop, currency,
+			quarterly_quota, minimum_coverage, lead_drop_percent, minimum_weekly_leads,
+			updated_by, updated_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+		ON CONFLICT (tenant_id) DO UPDATE SET
+			biggest_deal_won = EXCLUDED.biggest_deal_won,
+			low_pipeline_coverage = EXCLUDED.low_pipeline_coverage,
+			lead_volume_drop = EXCLUDED.lead_volume_drop,
+			currency = EXCLUDED.currency,
+			quarterly_quota = EXCLUDED.quarterly_quota,
+			minimum_coverage = EXCLUDED.minimum_coverage,
+			lead_drop_percent = EXCLUDED.lead_drop_percent,
+			minimum_weekly_leads = EXCLUDED.minimum_weekly_leads,
+			updated_by = EXCLUDED.updated_by,
+			updated_at = EXCLUDED.updated_at`
+
+	_, err := exec.ExecContext(ctx, query,
+		settings.TenantID, settings.BiggestDealWon, settings.LowPipelineCoverage, settings.LeadVolumeDrop, settings.Currency,
+		settings.QuarterlyQuota, settings.MinimumCoverage, settings.LeadDropPercent, settings.MinimumWeeklyLeads,
+		settings.UpdatedBy, settings.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to upsert insight settings: %w", err)
+	}
+
+	return nil
+}
+
+// Record stores a detected insight, reporting false if the tenant already has
+// one of the same type and period key.
+func (r *InsightRepository) Record(ctx context.Context, insight *domain.Insight) (bool, error) {
+	exec := getExecutor(ctx, r.db)
+
+	details, err := ToJSON(insight.Details)
+	if err != nil {
+		return false, fmt.Errorf("failed to marshal insight details: %w", err)
+	}
+
+	query := `
+		INSERT INTO sales.insights (id, tenant_id, type, period_key, details, detected_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (tenant_id, type, period_key) DO NOTHING`
+
+	result, err := exec.ExecContext(ctx, query,
+		insight.ID, insight.TenantID, string(insight.Type), insight.PeriodKey, details, insight.DetectedAt)
+	if err != nil {
+		return false, fmt.Errorf("failed to record insight: %w", err)
+	}
+	rows, _ := result.RowsAffected()
+
+	return rows > 0, nil
+}
+
+// List returns a tenant's most recent insights, optionally of one type.
+func (r *InsightRepository) List(ctx context.Context, tenantID uuid.UUID, insightType *domain.InsightType, limit int) ([]*domain.Insight, error) {
+	exec := getExecutor(ctx, r.db)
+
+	query := `
+		SELECT id, tenant_id, type, period_key, details, detected_at
+		FROM sales.insights
+		WHERE tenant_id = $1 AND ($2::text IS NULL OR type = $2)
+		ORDER BY detected_at DESC
+		LIMIT $3`
+
+	var typeFilter *string
+	if insightType != nil {
+		t := string(*insightType)
+		typeFilter = &t
+	}
+
+	var rows []insightRow
+	if err := sqlx.SelectContext(ctx, exec, &rows, query, tenantID, typeFilter, limit); err != nil {
+		return nil, fmt.Errorf("failed to list insights: %w", err)
+	}
+
+	insights := make([]*domain.Insight, len(rows))
+	for i, row := range rows {
+		insight := &domain.Insight{
+			ID:         row.ID,
+			TenantID:   row.TenantID,
+			Type:       domain.InsightType(row.Type),
+			PeriodKey:  row.PeriodKey,
+			DetectedAt: row.DetectedAt,
+		}
+		if err := row.Details.MarshalTo(&insight.Details); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal insight details: %w", err)
+		}
+		insights[i] = insight
+	}
+
+	return insights, nil
+}
+
+// ListActiveTenants returns the tenants that created or changed leads or
+// opportunities since a time.
+func (r *InsightRepository) ListActiveTenants(ctx context.Context, since time.Time) ([]uuid.UUID, error) {
+	exec := getExecutor(ctx, r.db)
+
+	query := `
+		SELECT tenant_id FROM sales.leads WHERE updated_at >= $1 AND deleted_at IS NULL
+		UNION
+		SELECT tenant_id FROM sales.opportunities WHERE updated_at >= $1 AND deleted_at IS NULL`
+
+	var tenantIDs []uuid.UUID
+	if err := sqlx.SelectContext(ctx, exec, &tenantIDs, query, since); err != nil {
+		return nil, fmt.Errorf("failed to list active tenants: %w", err)
+	}
+
+	return tenantIDs, nil
+}
+
+// opportunityAmountIn is the amount of an opportunity in currency $2: its
+// amount, or its base currency value.
+const opportunityAmountIn = `
+	CASE
+		WHEN o.currency = $2 THEN o.amount
+		WHEN o.base_currency = $2 THEN o.base_amount
+		ELSE 0
+	END`
+
+// GetBiggestWonAmount returns the largest amount in currency of the
+// opportunities won in a period, other than the one excluded.
+func (r *InsightRepository) GetBiggestWonAmount(ctx context.Context, tenantID uuid.UUID, currency string, period domain.ReportPeriod, excludeID uuid.UUID) (int64, error) {
+	exec := getExecutor(ctx, r.db)
+
+	query := `
+		SELECT COALESCE(MAX(` + opportunityAmountIn + `), 0)
+		FROM sales.opportunities o
+		WHERE o.tenant_id = $1
+			AND o.status = 'won'
+			AND o.closed_at >= $3 AND o.closed_at < $4
+			AND o.id <> $5
+			AND o.deleted_at IS NULL`
+
+	var amount int64
+	if err := sqlx.GetContext(ctx, exec, &amount, query, tenantID, currency, period.From, period.To, excludeID); err != nil {
+		return 0, fmt.Errorf("failed to get biggest won amount: %w", err)
+	}
+
+	return amount, nil
+}
+
+// GetQuarterPipeline returns, in currency, the amount won in a quarter and
+// the open pipeline expected to close in it.
+func (r *InsightRepository) GetQuarterPipeline(ctx context.Context, tenantID uuid.UUID, currency string, quarter domain.ReportPeriod) (int64, int64, error) {
+	exec := getExecutor(ctx, r.db)
+
+	query := `
+		SELECT
+			COALESCE(SUM(CASE WHEN o.status = 'won' AND o.closed_at >= $3 AND o.closed_at < $4
+				THEN ` + opportunityAmountIn + ` ELSE 0 END), 0) AS won,
+			COALESCE(SUM(CASE WHEN o.status = 'open' AND o.expected_close_date >= $3::date AND o.expected_close_date < $4::date
+				THEN ` + opportunityAmountIn + ` ELSE 0 END), 0) AS open
+		FROM sales.opportunities o
+		WHERE o.tenant_id = $1
+			AND o.deleted_at IS NULL
+			AND (o.status = 'open' OR (o.closed_at >= $3 AND o.closed_at < $4))`
+
+	var result struct {
+		Won  int64 `db:"won"`
+		Open int64 `db:"open"`
+	}
+	if err := sqlx.GetContext(ctx, exec, &result, query, tenantID, currency, quarter.From, quarter.To); err != nil {
+		return 0, 0, fmt.Errorf("failed to get quarter pipeline: %w", err)
+	}
+
+	return result.Won, result.Open, nil
+}
+
+// CountLeads returns the number of leads created in a period.
+func (r *InsightRepository) CountLeads(ctx context.Context, tenantID uuid.UUID, period domain.ReportPeriod) (int64, error) {
+	exec := getExecutor(ctx, r.db)
+
+	query := `
+		SELECT COUNT(*)
+		FROM sales.leads
+		WHERE tenant_id = $1 AND created_at >= $2 AND created_at < $3 AND deleted_at IS NULL`
+
+	var count int64
+	if err := sqlx.GetContext(ctx, exec, &count, query, tenantID, period.From, period.To); err != nil {
+		return 0, fmt.Errorf("failed to count leads: %w", err)
+	}
+
+	return count, nil
+}
+
+// Ensure InsightRepository implements domain.InsightRepository
+var _ domain.InsightRepository = (*InsightRepository)(nil)
@@ -10,6 +10,7 @@ import (
 
 	"github.com/google/uuid"
 	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
 
 	"github.com/kilang-desa-murni/crm/internal/sales/domain"
 )
@@ -51,6 +52,7 @@ type leadRow struct {
 	EstimatedCurrency string        `db:"estimated_currency"`
 	Tags             StringArray    `db:"tags"`
 	CustomFields     NullableJSON   `db:"custom_fields"`
+	Attribution      NullableJSON   `db:"attribution"`
 	LastContactedAt  sql.NullTime   `db:"last_contacted_at"`
 	ConvertedAt      sql.NullTime   `db:"converted_at"`
 	ConvertedBy      uuid.NullUUID  `db:"converted_by"`
@@ -95,6 +97,27 @@ var allowedLeadSortColumns = map[string]string{
 	"source":      "source",
 }
 
+// leadFilterColumns maps the fields of a lead filter expression to columns.
+var leadFilterColumns = map[string]string{
+	"status":            "status",
+	"source":            "source",
+	"rating":            "rating",
+	"score":             "score",
+	"first_name":        "first_name",
+	"last_name":         "last_name",
+	"email":             "email",
+	"company":           "company_name",
+	"industry":          "industry",
+	"city":              "city",
+	"country":           "country",
+	"owner_id":          "owner_id",
+	"campaign_id":       "campaign_id",
+	"estimated_amount":  "estimated_amount",
+	"last_contacted_at": "last_contacted_at",
+	"created_at":        "created_at",
+	"updated_at":        "updated_at",
+}
+
 // Create inserts a new lead into the database.
 func (r *LeadRepository) Create(ctx context.Context, lead *domain.Lead) error {
 	exec := getExecutor(ctx, r.db)
@@ -113,14 +136,19 @@ func (r *LeadRepository) Create(ctx context.Context, lead *domain.Lead) error {
 			owner_id, campaign_id, description, estimated_amount, estimated_currency,
 			tags, custom_fields, last_contacted_at,
 			emails_opened, emails_clicked, web_visits, form_submissions, last_engagement,
-			created_at, updated_at, created_by, updated_by, version
+			created_at, updated_at, created_by, updated_by, version, attribution
 		) VALUES (
 			$1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13,
 			$14, $15, $16, $17, $18, $19, $20, $21, $22, $23, $24,
 			$25, $26, $27, $28, $29, $30, $31, $32,
-			$33, $34, $35, $36, $37, $38, $39, $40, $41, $42
+			$33, $34, $35, $36, $37, $38, $39, $40, $41, $42, $43
 		)`
 
+	attributionJSON, err := nullAttribution(lead.Attribution)
+	if err != nil {
+		return err
+	}
+
 	_, err = exec.ExecContext(ctx, query,
 		lead.ID,
 		lead.TenantID,
@@ -164,6 +192,7 @@ func (r *LeadRepository) Create(ctx context.Context, lead *domain.Lead) error {
 		lead.CreatedBy,
 		lead.CreatedBy,
 		lead.Version,
+		attributionJSON,
 	)
 
 	if err != nil {
@@ -186,7 +215,7 @@ func (r *LeadRepository) GetByID(ctx context.Context, tenantID, leadID uuid.UUID
 			address, city, state, postal_code, country,
 			status, source, rating, score, demographic_score, behavioral_score,
 			owner_id, campaign_id, description, estimated_amount, estimated_currency,
-			tags, custom_fields, last_contacted_at,
+			tags, custom_fields, attribution, last_contacted_at,
 			converted_at, converted_by, opportunity_id, customer_id, contact_id,
 			disqualified_at, disqualified_by, disqualify_reason,
 			emails_opened, emails_clicked, web_visits, form_submissions, last_engagement,
@@ -229,9 +258,14 @@ func (r *LeadRepository) Update(ctx context.Context, lead *domain.Lead) error {
 			disqualified_at = $38, disqualified_by = $39, disqualify_reason = $40,
 			emails_opened = $41, emails_clicked = $42, web_visits = $43,
 			form_submissions = $44, last_engagement = $45,
-			updated_at = $46, updated_by = $47, version = version + 1
+			updated_at = $46, updated_by = $47, version = version + 1, attribution = $49
 		WHERE tenant_id = $1 AND id = $2 AND deleted_at IS NULL AND version = $48`
 
+	attributionJSON, err := nullAttribution(lead.Attribution)
+	if err != nil {
+		return err
+	}
+
 	var convertedAt, convertedBy, opportunityID, customerID, contactID interface{}
 	var disqualifiedAt, disqualifiedBy, disqualifyReason interface{}
 
@@ -302,6 +336,7 @@ func (r *LeadRepository) Update(ctx context.Context, lead *domain.Lead) error {
 		time.Now().UTC(),
 		lead.CreatedBy,
 		lead.Version,
+		attributionJSON,
 	)
 
 	if err != nil {
@@ -357,7 +392,7 @@ func (r *LeadRepository) List(ctx context.Context, tenantID uuid.UUID, filter do
 			address, city, state, postal_code, country,
 			status, source, rating, score, demographic_score, behavioral_score,
 			owner_id, campaign_id, description, estimated_amount, estimated_currency,
-			tags, custom_fields, last_contacted_at,
+			tags, custom_fields, attribution, last_contacted_at,
 			converted_at, converted_by, opportunity_id, customer_id, contact_id,
 			disqualified_at, disqualified_by, disqualify_reason,
 			emails_opened, emails_clicked, web_visits, form_submissions, last_engagement,
@@ -370,6 +405,9 @@ func (r *LeadRepository) List(ctx context.Context, tenantID uuid.UUID, filter do
 
 	// Apply filters
 	r.applyFilters(qb, filter)
+	if err := qb.WhereExpr(filter.Expr, leadFilterColumns); err != nil {
+		return nil, 0, fmt.Errorf("failed to filter leads: %w", err)
+	}
 
 	// Get total count
 	countQuery, countArgs := qb.BuildCount()
@@ -381,9 +419,13 @@ func (r *LeadRepository) List(ctx context.Context, tenantID uuid.UUID, filter do
 	// Apply sorting and pagination
 	sortColumn := ValidateSortColumn(opts.SortBy, allowedLeadSortColumns)
 	sortOrder := ValidateSortOrder(opts.SortOrder)
-	qb.OrderBy(sortColumn, sortOrder)
+	qb.OrderByKeyset(sortColumn, "id", sortOrder)
 	qb.Limit(opts.Limit())
-	qb.Offset(opts.Offset())
+	if opts.After != nil {
+		qb.After(sortColumn, "id", sortOrder, opts.After.SortValue, opts.After.ID)
+	} else {
+		qb.Offset(opts.Offset())
+	}
 
 	query, args := qb.Build()
 
@@ -414,7 +456,7 @@ func (r *LeadRepository) GetByEmail(ctx context.Context, tenantID uuid.UUID, ema
 			address, city, state, postal_code, country,
 			status, source, rating, score, demographic_score, behavioral_score,
 			owner_id, campaign_id, description, estimated_amount, estimated_currency,
-			tags, custom_fields, last_contacted_at,
+			tags, custom_fields, attribution, last_contacted_at,
 			converted_at, converted_by, opportunity_id, customer_id, contact_id,
 			disqualified_at, disqualified_by, disqualify_reason,
 			emails_opened, emails_clicked, web_visits, form_submissions, last_engagement,
@@ -443,7 +485,7 @@ func (r *LeadRepository) GetByPhone(ctx context.Context, tenantID uuid.UUID, pho
 			address, city, state, postal_code, country,
 			status, source, rating, score, demographic_score, behavioral_score,
 			owner_id, campaign_id, description, estimated_amount, estimated_currency,
-			tags, custom_fields, last_contacted_at,
+			tags, custom_fields, attribution, last_contacted_at,
 			converted_at, converted_by, opportunity_id, customer_id, contact_id,
 			disqualified_at, disqualified_by, disqualify_reason,
 			emails_opened, emails_clicked, web_visits, form_submissions, last_engagement,
@@ -462,6 +504,120 @@ func (r *LeadRepository) GetByPhone(ctx context.Context, tenantID uuid.UUID, pho
 	return r.toDomain(&row)
 }
 
+// Expressions leads are matched on, in step with the indexes of the lead
+// duplicates migration. Phone numbers are compared on their last eight
+// digits, which are the same with or without a country code or trunk
+// prefix; companies on the letters and digits of the name, which legal form
+// suffixes follow.
+const (
+	leadPhoneSuffix  = `RIGHT(regexp_replace(phone, '[^0-9]', '', 'g'), 8)`
+	leadMobileSuffix = `RIGHT(regexp_replace(mobile, '[^0-9]', '', 'g'), 8)`
+	leadCompanyKey   = `regexp_replace(LOWER(company_name), '[^a-z0-9]', '', 'g')`
+)
+
+// FindMatches retrieves the open leads matching the criteria, most recent
+// first. Candidates are found in SQL and confirmed with the criteria.
+func (r *LeadRepository) FindMatches(ctx context.Context, tenantID uuid.UUID, criteria domain.LeadMatchCriteria, limit int) ([]*domain.Lead, error) {
+	if criteria.IsEmpty() {
+		return nil, nil
+	}
+	exec := getExecutor(ctx, r.db)
+
+	phoneSuffixes := make([]string, 0, len(criteria.Phones))
+	for _, phone := range criteria.Phones {
+		phoneSuffixes = append(phoneSuffixes, phone[len(phone)-8:])
+	}
+
+	query := `
+		SELECT id, tenant_id, first_name, last_name, email, phone, mobile,
+			job_title, department, company_name, company_size, industry, website,
+			address, city, state, postal_code, country,
+			status, source, rating, score, demographic_score, behavioral_score,
+			owner_id, campaign_id, description, estimated_amount, estimated_currency,
+			tags, custom_fields, attribution, last_contacted_at,
+			converted_at, converted_by, opportunity_id, customer_id, contact_id,
+			disqualified_at, disqualified_by, disqualify_reason,
+			emails_opened, emails_clicked, web_visits, form_submissions, last_engagement,
+			created_at, updated_at, created_by, updated_by, deleted_at, version
+		FROM sales.leads
+		WHERE tenant_id = $1 AND deleted_at IS NULL AND merged_into_id IS NULL
+			AND status NOT IN ('converted', 'unqualified')
+			AND (
+				($2 <> '' AND LOWER(email) = $2)
+				OR ` + leadPhoneSuffix + ` = ANY($3)
+				OR ` + leadMobileSuffix + ` = ANY($3)
+				OR ($4 <> '' AND ` + leadCompanyKey + ` LIKE $4 || '%')
+			)
+		ORDER BY created_at DESC
+		LIMIT $5`
+
+	// Company names starting alike are confirmed below, so fetch extra
+	var rows []leadRow
+	if err := sqlx.SelectContext(ctx, exec, &rows, query,
+		tenantID, criteria.Email, pq.Array(phoneSuffixes), criteria.Company, limit*2); err != nil {
+		return nil, fmt.Errorf("failed to find matching leads: %w", err)
+	}
+
+	leads := make([]*domain.Lead, 0, len(rows))
+	for _, row := range rows {
+		lead, err := r.toDomain(&row)
+		if err != nil {
+			return nil, err
+		}
+		if len(criteria.MatchLead(lead)) == 0 {
+			continue
+		}
+		leads = append(leads, lead)
+		if len(leads) == limit {
+			break
+		}
+	}
+
+	return leads, nil
+}
+
+// Merge stores a lead with the duplicates merged into it in one transaction:
+// the duplicates are marked merged and deleted, and the emails logged on
+// them move to the lead.
+func (r *LeadRepository) Merge(ctx context.Context, target *domain.Lead, sources []*domain.Lead) error {
+	return NewTransactionManager(r.db).WithTransaction(ctx, func(txCtx context.Context) error {
+		if err := r.Update(txCtx, target); err != nil {
+			return err
+		}
+		exec := getExecutor(txCtx, r.db)
+
+		sourceIDs := make([]uuid.UUID, 0, len(sources))
+		for _, source := range sources {
+			result, err := exec.ExecContext(txCtx, `
+				UPDATE sales.leads
+				SET merged_into_id = $3, deleted_at = $4, updated_at = $4, version = version + 1
+				WHERE tenant_id = $1 AND id = $2 AND deleted_at IS NULL AND version = $5`,
+				source.TenantID, source.ID, target.ID, NewNullTime(source.DeletedAt).NullTime, source.Version)
+			if err != nil {
+				return fmt.Errorf("failed to merge lead: %w", err)
+			}
+			rowsAffected, err := result.RowsAffected()
+			if err != nil {
+				return fmt.Errorf("failed to get rows affected: %w", err)
+			}
+			if rowsAffected == 0 {
+				return fmt.Errorf("lead %s not found or version mismatch", source.ID)
+			}
+			source.Version++
+			sourceIDs = append(sourceIDs, source.ID)
+		}
+
+		if _, err := exec.ExecContext(txCtx, `
+			UPDATE sales.inbound_emails
+			SET lead_id = $2
+			WHERE tenant_id = $1 AND lead_id = ANY($3)`,
+			target.TenantID, target.ID, pq.Array(sourceIDs)); err != nil {
+			return fmt.Errorf("failed to move merged lead emails: %w", err)
+		}
+		return nil
+	})
+}
+
 // GetByStatus retrieves leads by status.
 func (r *LeadRepository) GetByStatus(ctx context.Context, tenantID uuid.UUID, status domain.LeadStatus, opts domain.ListOptions) ([]*domain.Lead, int64, error) {
 	filter := domain.LeadFilter{
@@ -542,7 +698,7 @@ func (r *LeadRepository) GetStaleLeads(ctx context.Context, tenantID uuid.UUID,
 			address, city, state, postal_code, country,
 			status, source, rating, score, demographic_score, behavioral_score,
 			owner_id, campaign_id, description, estimated_amount, estimated_currency,
-			tags, custom_fields, last_contacted_at,
+			tags, custom_fields, attribution, last_contacted_at,
 			converted_at, converted_by, opportunity_id, customer_id, contact_id,
 			disqualified_at, disqualified_by, disqualify_reason,
 			emails_opened, emails_clicked, web_visits, form_submissions, last_engagement,
@@ -607,13 +763,20 @@ func (r *LeadRepository) BulkCreate(ctx context.Context, leads []*domain.Lead) e
 				address, city, state, postal_code, country,
 				status, source, rating, score, demographic_score, behavioral_score,
 				owner_id, campaign_id, description, estimated_amount, estimated_currency,
-				tags, custom_fields, created_at, updated_at, created_by, updated_by, version
+				tags, custom_fields, created_at, updated_at, created_by, updated_by, version,
+				attribution
 			) VALUES (
 				$1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13,
 				$14, $15, $16, $17, $18, $19, $20, $21, $22, $23, $24,
-				$25, $26, $27, $28, $29, $30, $31, $32, $33, $34, $35, $36
+				$25, $26, $27, $28, $29, $30, $31, $32, $33, $34, $35, $36,
+				$37
 			)`
 
+		attributionJSON, err := nullAttribution(lead.Attribution)
+		if err != nil {
+			return err
+		}
+
 		_, err = exec.ExecContext(ctx, query,
 			lead.ID, lead.TenantID,
 			lead.Contact.FirstName, lead.Contact.LastName, lead.Contact.Email,
@@ -631,6 +794,7 @@ func (r *LeadRepository) BulkCreate(ctx context.Context, leads []*domain.Lead) e
 			lead.EstimatedValue.Amount, lead.EstimatedValue.Currency,
 			lead.Tags, customFieldsJSON,
 			lead.CreatedAt, lead.UpdatedAt, lead.CreatedBy, lead.CreatedBy, lead.Version,
+			attributionJSON,
 		)
 
 		if err != nil {
@@ -939,6 +1103,11 @@ func (r *LeadRepository) toDomain(row *leadRow) (*domain.Lead, error) {
 		lead.CustomFields = customFields
 	}
 
+	// Attribution
+	if err := row.Attribution.MarshalTo(&lead.Attribution); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal attribution: %w", err)
+	}
+
 	return lead, nil
 }
 
@@ -957,3 +1126,15 @@ func nullUUID(id *uuid.UUID) uuid.NullUUID {
 	}
 	return uuid.NullUUID{UUID: *id, Valid: true}
 }
+
+// nullAttribution converts attribution to a nullable JSON column value.
+func nullAttribution(attribution *domain.Attribution) (sql.NullString, error) {
+	if attribution == nil {
+		return sql.NullString{}, nil
+	}
+	data, err := json.Marshal(attribution)
+	if err != nil {
+		return sql.NullString{}, fmt.Errorf("failed to marshal attribution: %w", err)
+	}
+	return sql.NullString{String: string(data), Valid: true}, nil
+}
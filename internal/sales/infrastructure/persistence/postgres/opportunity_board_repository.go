@@ -0,0 +1,151 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+
+	"github.com/kilang-desa-murni/crm/internal/sales/domain"
+)
+
+// OpportunityBoardRepository implements domain.OpportunityBoardRepository for PostgreSQL.
+type OpportunityBoardRepository struct {
+	db *sqlx.DB
+}
+
+// NewOpportunityBoardRepository creates a new OpportunityBoardRepository.
+func NewOpportunityBoardRepository(db *sqlx.DB) *OpportunityBoardRepository {
+	return &OpportunityBoardRepository{db: db}
+}
+
+const boardCardColumns = `
+	opportunity_id, tenant_id, pipeline_id, stage_id, code, name, status, priority,
+	customer_name, owner_id, owner_name, amount, weighted_amount, currency, probability,
+	expected_close_date, stage_entered_at, board_position, version, updated_at`
+
+const insertBoardCardQuery = `
+	INSERT INTO sales.opportunity_board_cards (` + boardCardColumns + `)
+	VALUES (
+		:opportunity_id, :tenant_id, :pipeline_id, :stage_id, :code, :name, :status, :priority,
+		:customer_name, :owner_id, :owner_name, :amount, :weighted_amount, :currency, :probability,
+		:expected_close_date, :stage_entered_at, :board_position, :version, :updated_at
+	)`
+
+const upsertBoardCardQuery = insertBoardCardQuery + `
+	ON CONFLICT (opportunity_id) DO UPDATE SET
+		pipeline_id = EXCLUDED.pipeline_id,
+		stage_id = EXCLUDED.stage_id,
+		code = EXCLUDED.code,
+		name = EXCLUDED.name,
+		status = EXCLUDED.status,
+		priority = EXCLUDED.priority,
+		customer_name = EXCLUDED.customer_name,
+		owner_id = EXCLUDED.owner_id,
+		owner_name = EXCLUDED.owner_name,
+		amount = EXCLUDED.amount,
+		weighted_amount = EXCLUDED.weighted_amount,
+		currency = EXCLUDED.currency,
+		probability = EXCLUDED.probability,
+		expected_close_date = EXCLUDED.expected_close_date,
+		stage_entered_at = EXCLUDED.stage_entered_at,
+		board_position = EXCLUDED.board_position,
+		version = EXCLUDED.version,
+		updated_at = EXCLUDED.updated_at`
+
+// UpsertCard creates or replaces a card, ignoring cards older than the stored one.
+// Events can be handled out of order, so the version decides which card wins.
+func (r *OpportunityBoardRepository) UpsertCard(ctx context.Context, card *domain.OpportunityBoardCard) error {
+	exec := getExecutor(ctx, r.db)
+
+	query := upsertBoardCardQuery + `
+		WHERE sales.opportunity_board_cards.version <= EXCLUDED.version`
+
+	if _, err := sqlx.NamedExecContext(ctx, exec, query, card); err != nil {
+		return fmt.Errorf("failed to upsert board card: %w", err)
+	}
+
+	return nil
+}
+
+// DeleteCard removes an opportunity's card, if any.
+func (r *OpportunityBoardRepository) DeleteCard(ctx context.Context, tenantID, opportunityID uuid.UUID) error {
+	exec := getExecutor(ctx, r.db)
+
+	query := `DELETE FROM sales.opportunity_board_cards WHERE tenant_id = $1 AND opportunity_id = $2`
+
+	if _, err := exec.ExecContext(ctx, query, tenantID, opportunityID); err != nil {
+		return fmt.Errorf("failed to delete board card: %w", err)
+	}
+
+	return nil
+}
+
+// ListColumnCards lists the cards of one board column in board position
+// order, continuing after the cursor if one is given.
+func (r *OpportunityBoardRepository) ListColumnCards(ctx context.Context, tenantID, pipelineID, stageID uuid.UUID, after *domain.BoardCursor, limit int) ([]*domain.OpportunityBoardCard, error) {
+	exec := getExecutor(ctx, r.db)
+
+	query := `SELECT ` + boardCardColumns + `
+		FROM sales.opportunity_board_cards
+		WHERE tenant_id = $1 AND pipeline_id = $2 AND stage_id = $3`
+	args := []interface{}{tenantID, pipelineID, stageID}
+
+	if after != nil {
+		query += ` AND (board_position, opportunity_id) > ($4, $5)`
+		args = append(args, after.BoardPosition, after.OpportunityID)
+	}
+
+	query += fmt.Sprintf(` ORDER BY board_position, opportunity_id LIMIT %d`, limit)
+
+	var cards []*domain.OpportunityBoardCard
+	if err := sqlx.SelectContext(ctx, exec, &cards, query, args...); err != nil {
+		return nil, fmt.Errorf("failed to list board cards: %w", err)
+	}
+
+	return cards, nil
+}
+
+// GetColumnTotals returns the card counts and values of every column of a board.
+func (r *OpportunityBoardRepository) GetColumnTotals(ctx context.Context, tenantID, pipelineID uuid.UUID) ([]*domain.BoardColumnTotal, error) {
+	exec := getExecutor(ctx, r.db)
+
+	query := `
+		SELECT stage_id, currency, COUNT(*) AS count,
+			COALESCE(SUM(amount), 0) AS amount,
+			COALESCE(SUM(weighted_amount), 0) AS weighted_amount
+		FROM sales.opportunity_board_cards
+		WHERE tenant_id = $1 AND pipeline_id = $2
+		GROUP BY stage_id, currency
+		ORDER BY stage_id, count DESC`
+
+	var totals []*domain.BoardColumnTotal
+	if err := sqlx.SelectContext(ctx, exec, &totals, query, tenantID, pipelineID); err != nil {
+		return nil, fmt.Errorf("failed to get board column totals: %w", err)
+	}
+
+	return totals, nil
+}
+
+// ReplacePipelineCards replaces every card of a pipeline in one transaction.
+func (r *OpportunityBoardRepository) ReplacePipelineCards(ctx context.Context, tenantID, pipelineID uuid.UUID, cards []*domain.OpportunityBoardCard) error {
+	return NewTransactionManager(r.db).WithTransaction(ctx, func(txCtx context.Context) error {
+		exec := getExecutor(txCtx, r.db)
+
+		if _, err := exec.ExecContext(txCtx,
+			`DELETE FROM sales.opportunity_board_cards WHERE tenant_id = $1 AND pipeline_id = $2`,
+			tenantID, pipelineID); err != nil {
+			return fmt.Errorf("failed to clear board cards: %w", err)
+		}
+
+		for _, card := range cards {
+			// A card still filed under another pipeline is moved here
+			if _, err := sqlx.NamedExecContext(txCtx, exec, upsertBoardCardQuery, card); err != nil {
+				return fmt.Errorf("failed to insert board card: %w", err)
+			}
+		}
+
+		return nil
+	})
+}
@@ -0,0 +1,120 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+
+	"github.com/kilang-desa-murni/crm/internal/sales/domain"
+)
+
+// OpportunityReasonRepository implements domain.OpportunityReasonRepository for PostgreSQL.
+type OpportunityReasonRepository struct {
+	db *sqlx.DB
+}
+
+// NewOpportunityReasonRepository creates a new OpportunityReasonRepository.
+func NewOpportunityReasonRepository(db *sqlx.DB) *OpportunityReasonRepository {
+	return &OpportunityReasonRepository{db: db}
+}
+
+const opportunityReasonColumns = `
+	id, tenant_id, type, name, description, is_active, sort_order, created_at, updated_at`
+
+// Create creates a new reason.
+func (r *OpportunityReasonRepository) Create(ctx context.Context, reason *domain.OpportunityReason) error {
+	exec := getExecutor(ctx, r.db)
+
+	query := `
+		INSERT INTO sales.opportunity_reasons (` + opportunityReasonColumns + `)
+		VALUES (
+			:id, :tenant_id, :type, :name, :description, :is_active, :sort_order, :created_at, :updated_at
+		)`
+
+	if _, err := sqlx.NamedExecContext(ctx, exec, query, reason); err != nil {
+		if IsUniqueViolation(err) {
+			return domain.ErrOpportunityReasonAlreadyExists
+		}
+		return fmt.Errorf("failed to create opportunity reason: %w", err)
+	}
+
+	return nil
+}
+
+// GetByID retrieves a reason by ID.
+func (r *OpportunityReasonRepository) GetByID(ctx context.Context, tenantID, reasonID uuid.UUID) (*domain.OpportunityReason, error) {
+	exec := getExecutor(ctx, r.db)
+
+	query := `SELECT ` + opportunityReasonColumns + `
+		FROM sales.opportunity_reasons
+		WHERE tenant_id = $1 AND id = $2`
+
+	var reason domain.OpportunityReason
+	if err := sqlx.GetContext(ctx, exec, &reason, query, tenantID, reasonID); err != nil {
+		if IsNotFoundError(err) {
+			return nil, domain.ErrOpportunityReasonNotFound
+		}
+		return nil, fmt.Errorf("failed to get opportunity reason: %w", err)
+	}
+
+	return &reason, nil
+}
+
+// Update updates a reason.
+func (r *OpportunityReasonRepository) Update(ctx context.Context, reason *domain.OpportunityReason) error {
+	exec := getExecutor(ctx, r.db)
+
+	query := `
+		UPDATE sales.opportunity_reasons SET
+			name = :name, description = :description, is_active = :is_active,
+			sort_order = :sort_order, updated_at = :updated_at
+		WHERE tenant_id = :tenant_id AND id = :id`
+
+	result, err := sqlx.NamedExecContext(ctx, exec, query, reason)
+	if err != nil {
+		if IsUniqueViolation(err) {
+			return domain.ErrOpportunityReasonAlreadyExists
+		}
+		return fmt.Errorf("failed to update opportunity reason: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return domain.ErrOpportunityReasonNotFound
+	}
+
+	return nil
+}
+
+// List lists reasons ordered by type, order and name.
+func (r *OpportunityReasonRepository) List(ctx context.Context, tenantID uuid.UUID, filter domain.OpportunityReasonFilter) ([]*domain.OpportunityReason, error) {
+	exec := getExecutor(ctx, r.db)
+
+	qb := NewQueryBuilder(`SELECT ` + opportunityReasonColumns + `
+		FROM sales.opportunity_reasons
+		WHERE tenant_id = $1`)
+	qb.args = append(qb.args, tenantID)
+
+	if filter.Type != nil {
+		qb.Where(fmt.Sprintf("type = $%d", qb.NextParam()), string(*filter.Type))
+	}
+	if filter.ActiveOnly {
+		qb.Where("is_active = TRUE")
+	}
+
+	query, args := qb.Build()
+	query += ` ORDER BY type, sort_order, name`
+
+	var reasons []*domain.OpportunityReason
+	if err := sqlx.SelectContext(ctx, exec, &reasons, query, args...); err != nil {
+		return nil, fmt.Errorf("failed to list opportunity reasons: %w", err)
+	}
+
+	return reasons, nil
+}
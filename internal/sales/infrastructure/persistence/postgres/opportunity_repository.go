@@ -19,50 +19,61 @@ import (
 // ============================================================================
 
 // opportunityRow represents an opportunity database row.
+
 type opportunityRow struct {
-	ID                 uuid.UUID      `db:"id"`
-	TenantID           uuid.UUID      `db:"tenant_id"`
-	Name               string         `db:"name"`
-	Description        sql.NullString `db:"description"`
-	Status             string         `db:"status"`
-	Priority           string         `db:"priority"`
-	PipelineID         uuid.UUID      `db:"pipeline_id"`
-	PipelineName       sql.NullString `db:"pipeline_name"`
-	StageID            uuid.UUID      `db:"stage_id"`
-	StageName          sql.NullString `db:"stage_name"`
-	StageEnteredAt     time.Time      `db:"stage_entered_at"`
-	Amount             int64          `db:"amount"`
-	Currency           string         `db:"currency"`
-	WeightedAmount     int64          `db:"weighted_amount"`
-	Probability        int            `db:"probability"`
-	ExpectedCloseDate  sql.NullTime   `db:"expected_close_date"`
-	ActualCloseDate    sql.NullTime   `db:"actual_close_date"`
-	CustomerID         uuid.UUID      `db:"customer_id"`
-	CustomerName       sql.NullString `db:"customer_name"`
-	LeadID             uuid.NullUUID  `db:"lead_id"`
-	OwnerID            uuid.UUID      `db:"owner_id"`
-	OwnerName          sql.NullString `db:"owner_name"`
-	Source             sql.NullString `db:"source"`
-	Campaign           sql.NullString `db:"campaign"`
-	CampaignID         uuid.NullUUID  `db:"campaign_id"`
-	Notes              sql.NullString `db:"notes"`
-	Tags               StringArray    `db:"tags"`
-	CustomFields       NullableJSON   `db:"custom_fields"`
-	CloseReason        sql.NullString `db:"close_reason"`
-	CloseNotes         sql.NullString `db:"close_notes"`
-	ClosedAt           sql.NullTime   `db:"closed_at"`
-	ClosedBy           uuid.NullUUID  `db:"closed_by"`
-	CompetitorID       uuid.NullUUID  `db:"competitor_id"`
-	CompetitorName     sql.NullString `db:"competitor_name"`
-	DealID             uuid.NullUUID  `db:"deal_id"`
-	ActivityCount      int            `db:"activity_count"`
-	LastActivityAt     sql.NullTime   `db:"last_activity_at"`
-	CreatedAt          time.Time      `db:"created_at"`
-	UpdatedAt          time.Time      `db:"updated_at"`
-	CreatedBy          uuid.UUID      `db:"created_by"`
-	UpdatedBy          uuid.UUID      `db:"updated_by"`
-	DeletedAt          sql.NullTime   `db:"deleted_at"`
-	Version            int            `db:"version"`
+	ID                 uuid.UUID       `db:"id"`
+	TenantID           uuid.UUID       `db:"tenant_id"`
+	Name               string          `db:"name"`
+	Description        sql.NullString  `db:"description"`
+	Status             string          `db:"status"`
+	Priority           string          `db:"priority"`
+	PipelineID         uuid.UUID       `db:"pipeline_id"`
+	PipelineName       sql.NullString  `db:"pipeline_name"`
+	StageID            uuid.UUID       `db:"stage_id"`
+	StageName          sql.NullString  `db:"stage_name"`
+	StageEnteredAt     time.Time       `db:"stage_entered_at"`
+	BoardPosition      string          `db:"board_position"`
+	Amount             int64           `db:"amount"`
+	Currency           string          `db:"currency"`
+	WeightedAmount     int64           `db:"weighted_amount"`
+	BaseAmount         sql.NullInt64   `db:"base_amount"`
+	BaseWeightedAmount sql.NullInt64   `db:"base_weighted_amount"`
+	BaseCurrency       sql.NullString  `db:"base_currency"`
+	ExchangeRate       sql.NullFloat64 `db:"exchange_rate"`
+	Probability        int             `db:"probability"`
+	ProbabilityOverride sql.NullInt64  `db:"probability_override"`
+	ForecastCategory   sql.NullString  `db:"forecast_category"`
+	ExpectedCloseDate  sql.NullTime    `db:"expected_close_date"`
+	ActualCloseDate    sql.NullTime    `db:"actual_close_date"`
+	CustomerID         uuid.UUID       `db:"customer_id"`
+	CustomerName       sql.NullString  `db:"customer_name"`
+	CustomerSegment    sql.NullString  `db:"customer_segment"`
+	LeadID             uuid.NullUUID   `db:"lead_id"`
+	OwnerID            uuid.UUID       `db:"owner_id"`
+	OwnerName          sql.NullString  `db:"owner_name"`
+	Source             sql.NullString  `db:"source"`
+	Campaign           sql.NullString  `db:"campaign"`
+	CampaignID         uuid.NullUUID   `db:"campaign_id"`
+	Notes              sql.NullString  `db:"notes"`
+	Tags               StringArray     `db:"tags"`
+	CustomFields       NullableJSON    `db:"custom_fields"`
+	Attribution        NullableJSON    `db:"attribution"`
+	CloseReason        sql.NullString  `db:"close_reason"`
+	CloseReasonID      uuid.NullUUID   `db:"close_reason_id"`
+	CloseNotes         sql.NullString  `db:"close_notes"`
+	ClosedAt           sql.NullTime    `db:"closed_at"`
+	ClosedBy           uuid.NullUUID   `db:"closed_by"`
+	CompetitorID       uuid.NullUUID   `db:"competitor_id"`
+	CompetitorName     sql.NullString  `db:"competitor_name"`
+	DealID             uuid.NullUUID   `db:"deal_id"`
+	ActivityCount      int             `db:"activity_count"`
+	LastActivityAt     sql.NullTime    `db:"last_activity_at"`
+	CreatedAt          time.Time       `db:"created_at"`
+	UpdatedAt          time.Time       `db:"updated_at"`
+	CreatedBy          uuid.UUID       `db:"created_by"`
+	UpdatedBy          uuid.UUID       `db:"updated_by"`
+	DeletedAt          sql.NullTime    `db:"deleted_at"`
+	Version            int             `db:"version"`
 }
 
 // OpportunityRepository implements domain.OpportunityRepository for PostgreSQL.
@@ -84,6 +95,27 @@ var allowedOpportunitySortColumns = map[string]string{
 	"probability":         "o.probability",
 	"name":                "o.name",
 	"status":              "o.status",
+	"board_position":      "o.board_position",
+}
+
+// opportunityFilterColumns maps the fields of an opportunity filter expression
+// to columns.
+var opportunityFilterColumns = map[string]string{
+	"status":              "o.status",
+	"priority":            "o.priority",
+	"name":                "o.name",
+	"source":              "o.source",
+	"currency":            "o.currency",
+	"amount":              "o.amount",
+	"probability":         "o.probability",
+	"pipeline_id":         "o.pipeline_id",
+	"stage_id":            "o.stage_id",
+	"customer_id":         "o.customer_id",
+	"owner_id":            "o.owner_id",
+	"expected_close_date": "o.expected_close_date",
+	"closed_at":           "o.closed_at",
+	"created_at":          "o.created_at",
+	"updated_at":          "o.updated_at",
 }
 
 // Create inserts a new opportunity into the database.
@@ -103,13 +135,24 @@ func (r *OpportunityRepository) Create(ctx context.Context, opp *domain.Opportun
 			expected_close_date, customer_id, customer_name, lead_id,
 			owner_id, owner_name, source, campaign, campaign_id,
 			notes, tags, custom_fields, activity_count,
-			created_at, updated_at, created_by, updated_by, version
+			created_at, updated_at, created_by, updated_by, version,
+			base_amount, base_weighted_amount, base_currency, exchange_rate,
+			board_position, attribution, probability_override, forecast_category,
+			customer_segment
 		) VALUES (
 			$1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15,
 			$16, $17, $18, $19, $20, $21, $22, $23, $24, $25, $26, $27, $28,
-			$29, $30, $31, $32, $33
+			$29, $30, $31, $32, $33, $34, $35, $36, $37, $38, $39, $40, $41,
+			$42
 		)`
 
+	attributionJSON, err := nullAttribution(opp.Attribution)
+	if err != nil {
+		return err
+	}
+
+	baseAmount, baseWeightedAmount, baseCurrency, exchangeRate := baseCurrencyValues(opp)
+
 	_, err = exec.ExecContext(ctx, query,
 		opp.ID,
 		opp.TenantID,
@@ -144,6 +187,15 @@ func (r *OpportunityRepository) Create(ctx context.Context, opp *domain.Opportun
 		opp.CreatedBy,
 		opp.CreatedBy,
 		opp.Version,
+		baseAmount,
+		baseWeightedAmount,
+		baseCurrency,
+		exchangeRate,
+		opp.BoardPosition,
+		attributionJSON,
+		probabilityOverrideValue(opp),
+		nullString(string(opp.ForecastCategory)),
+		nullString(opp.CustomerSegment),
 	)
 
 	if err != nil {
@@ -177,12 +229,13 @@ func (r *OpportunityRepository) GetByID(ctx context.Context, tenantID, opportuni
 
 	query := `
 		SELECT o.id, o.tenant_id, o.name, o.description, o.status, o.priority,
-			o.pipeline_id, o.pipeline_name, o.stage_id, o.stage_name, o.stage_entered_at,
-			o.amount, o.currency, o.weighted_amount, o.probability,
+			o.pipeline_id, o.pipeline_name, o.stage_id, o.stage_name, o.stage_entered_at, o.board_position,
+			o.amount, o.currency, o.weighted_amount, o.probability, o.probability_override, o.forecast_category,
+			o.base_amount, o.base_weighted_amount, o.base_currency, o.exchange_rate,
 			o.expected_close_date, o.actual_close_date,
-			o.customer_id, o.customer_name, o.lead_id, o.owner_id, o.owner_name,
-			o.source, o.campaign, o.campaign_id, o.notes, o.tags, o.custom_fields,
-			o.close_reason, o.close_notes, o.closed_at, o.closed_by,
+			o.customer_id, o.customer_name, o.customer_segment, o.lead_id, o.owner_id, o.owner_name,
+			o.source, o.campaign, o.campaign_id, o.attribution, o.notes, o.tags, o.custom_fields,
+			o.close_reason, o.close_reason_id, o.close_notes, o.closed_at, o.closed_by,
 			o.competitor_id, o.competitor_name, o.deal_id,
 			o.activity_count, o.last_activity_at,
 			o.created_at, o.updated_at, o.created_by, o.updated_by, o.deleted_at, o.version
@@ -192,7 +245,7 @@ func (r *OpportunityRepository) GetByID(ctx context.Context, tenantID, opportuni
 	var row opportunityRow
 	if err := sqlx.GetContext(ctx, exec, &row, query, tenantID, opportunityID); err != nil {
 		if IsNotFoundError(err) {
-			return nil, fmt.Errorf("opportunity not found")
+			return nil, domain.ErrOpportunityNotFound
 		}
 		return nil, fmt.Errorf("failed to get opportunity: %w", err)
 	}
@@ -247,14 +300,21 @@ func (r *OpportunityRepository) Update(ctx context.Context, opp *domain.Opportun
 			close_reason = $29, close_notes = $30, closed_at = $31, closed_by = $32,
 			competitor_id = $33, competitor_name = $34,
 			activity_count = $35, last_activity_at = $36,
-			updated_at = $37, updated_by = $38, version = version + 1
+			updated_at = $37, updated_by = $38, version = version + 1,
+			base_amount = $40, base_weighted_amount = $41, base_currency = $42, exchange_rate = $43,
+			board_position = $44, close_reason_id = $45,
+			probability_override = $46, forecast_category = $47,
+			customer_segment = $48
 		WHERE tenant_id = $1 AND id = $2 AND deleted_at IS NULL AND version = $39`
 
-	var closeReason, closeNotes interface{}
+	var closeReason, closeReasonID, closeNotes interface{}
 	var closedAt, closedBy, competitorID, competitorName interface{}
 
 	if opp.CloseInfo != nil {
 		closeReason = opp.CloseInfo.Reason
+		if opp.CloseInfo.ReasonID != nil {
+			closeReasonID = *opp.CloseInfo.ReasonID
+		}
 		closeNotes = opp.CloseInfo.Notes
 		closedAt = opp.CloseInfo.ClosedAt
 		closedBy = opp.CloseInfo.ClosedBy
@@ -264,6 +324,8 @@ func (r *OpportunityRepository) Update(ctx context.Context, opp *domain.Opportun
 		competitorName = opp.CloseInfo.CompetitorName
 	}
 
+	baseAmount, baseWeightedAmount, baseCurrency, exchangeRate := baseCurrencyValues(opp)
+
 	result, err := exec.ExecContext(ctx, query,
 		opp.TenantID,
 		opp.ID,
@@ -304,6 +366,15 @@ func (r *OpportunityRepository) Update(ctx context.Context, opp *domain.Opportun
 		time.Now().UTC(),
 		opp.CreatedBy,
 		opp.Version,
+		baseAmount,
+		baseWeightedAmount,
+		baseCurrency,
+		exchangeRate,
+		opp.BoardPosition,
+		closeReasonID,
+		probabilityOverrideValue(opp),
+		nullString(string(opp.ForecastCategory)),
+		nullString(opp.CustomerSegment),
 	)
 
 	if err != nil {
@@ -335,7 +406,10 @@ func (r *OpportunityRepository) Update(ctx context.Context, opp *domain.Opportun
 		return err
 	}
 
-	// Insert new stage history entries
+	// Update stage history - delete and re-insert, so closed entries get their exit time
+	if err := r.deleteStageHistory(ctx, opp.ID, opp.TenantID); err != nil {
+		return err
+	}
 	if err := r.insertStageHistory(ctx, opp.ID, opp.TenantID, opp.StageHistory); err != nil {
 		return err
 	}
@@ -376,12 +450,13 @@ func (r *OpportunityRepository) List(ctx context.Context, tenantID uuid.UUID, fi
 
 	baseQuery := `
 		SELECT o.id, o.tenant_id, o.name, o.description, o.status, o.priority,
-			o.pipeline_id, o.pipeline_name, o.stage_id, o.stage_name, o.stage_entered_at,
-			o.amount, o.currency, o.weighted_amount, o.probability,
+			o.pipeline_id, o.pipeline_name, o.stage_id, o.stage_name, o.stage_entered_at, o.board_position,
+			o.amount, o.currency, o.weighted_amount, o.probability, o.probability_override, o.forecast_category,
+			o.base_amount, o.base_weighted_amount, o.base_currency, o.exchange_rate,
 			o.expected_close_date, o.actual_close_date,
-			o.customer_id, o.customer_name, o.lead_id, o.owner_id, o.owner_name,
-			o.source, o.campaign, o.campaign_id, o.notes, o.tags, o.custom_fields,
-			o.close_reason, o.close_notes, o.closed_at, o.closed_by,
+			o.customer_id, o.customer_name, o.customer_segment, o.lead_id, o.owner_id, o.owner_name,
+			o.source, o.campaign, o.campaign_id, o.attribution, o.notes, o.tags, o.custom_fields,
+			o.close_reason, o.close_reason_id, o.close_notes, o.closed_at, o.closed_by,
 			o.competitor_id, o.competitor_name, o.deal_id,
 			o.activity_count, o.last_activity_at,
 			o.created_at, o.updated_at, o.created_by, o.updated_by, o.deleted_at, o.version
@@ -393,6 +468,9 @@ func (r *OpportunityRepository) List(ctx context.Context, tenantID uuid.UUID, fi
 
 	// Apply filters
 	r.applyFilters(qb, filter)
+	if err := qb.WhereExpr(filter.Expr, opportunityFilterColumns); err != nil {
+		return nil, 0, fmt.Errorf("failed to filter opportunities: %w", err)
+	}
 
 	// Get total count
 	countQuery, countArgs := qb.BuildCount()
@@ -404,9 +482,13 @@ func (r *OpportunityRepository) List(ctx context.Context, tenantID uuid.UUID, fi
 	// Apply sorting and pagination
 	sortColumn := ValidateSortColumn(opts.SortBy, allowedOpportunitySortColumns)
 	sortOrder := ValidateSortOrder(opts.SortOrder)
-	qb.OrderBy(sortColumn, sortOrder)
+	qb.OrderByKeyset(sortColumn, "o.id", sortOrder)
 	qb.Limit(opts.Limit())
-	qb.Offset(opts.Offset())
+	if opts.After != nil {
+		qb.After(sortColumn, "o.id", sortOrder, opts.After.SortValue, opts.After.ID)
+	} else {
+		qb.Offset(opts.Offset())
+	}
 
 	query, args := qb.Build()
 
@@ -467,6 +549,45 @@ func (r *OpportunityRepository) GetByStage(ctx context.Context, tenantID, pipeli
 	return r.List(ctx, tenantID, filter, opts)
 }
 
+// GetBoardPositionNeighbors returns the board positions on either side of
+// index in a stage, leaving out excludeID. After is the next position greater
+// than before, so cards sharing a position never leave an empty gap.
+func (r *OpportunityRepository) GetBoardPositionNeighbors(ctx context.Context, tenantID, stageID, excludeID uuid.UUID, index int) (string, string, error) {
+	exec := getExecutor(ctx, r.db)
+
+	var before string
+	if index > 0 {
+		// A missing row means the index is past the end of the column
+		query := `
+			SELECT COALESCE((
+				SELECT board_position FROM sales.opportunities
+				WHERE tenant_id = $1 AND stage_id = $2 AND id <> $3 AND deleted_at IS NULL
+				ORDER BY board_position, id
+				OFFSET $4 LIMIT 1
+			), (
+				SELECT MAX(board_position) FROM sales.opportunities
+				WHERE tenant_id = $1 AND stage_id = $2 AND id <> $3 AND deleted_at IS NULL
+			), '')`
+
+		if err := sqlx.GetContext(ctx, exec, &before, query, tenantID, stageID, excludeID, index-1); err != nil {
+			return "", "", fmt.Errorf("failed to get board position before index: %w", err)
+		}
+	}
+
+	query := `
+		SELECT COALESCE(MIN(board_position), '')
+		FROM sales.opportunities
+		WHERE tenant_id = $1 AND stage_id = $2 AND id <> $3 AND deleted_at IS NULL
+			AND board_position > $4`
+
+	var after string
+	if err := sqlx.GetContext(ctx, exec, &after, query, tenantID, stageID, excludeID, before); err != nil {
+		return "", "", fmt.Errorf("failed to get board position after index: %w", err)
+	}
+
+	return before, after, nil
+}
+
 // GetByCustomer retrieves opportunities for a customer.
 func (r *OpportunityRepository) GetByCustomer(ctx context.Context, tenantID, customerID uuid.UUID, opts domain.ListOptions) ([]*domain.Opportunity, int64, error) {
 	filter := domain.OpportunityFilter{
@@ -489,12 +610,13 @@ func (r *OpportunityRepository) GetByLead(ctx context.Context, tenantID, leadID
 
 	query := `
 		SELECT o.id, o.tenant_id, o.name, o.description, o.status, o.priority,
-			o.pipeline_id, o.pipeline_name, o.stage_id, o.stage_name, o.stage_entered_at,
-			o.amount, o.currency, o.weighted_amount, o.probability,
+			o.pipeline_id, o.pipeline_name, o.stage_id, o.stage_name, o.stage_entered_at, o.board_position,
+			o.amount, o.currency, o.weighted_amount, o.probability, o.probability_override, o.forecast_category,
+			o.base_amount, o.base_weighted_amount, o.base_currency, o.exchange_rate,
 			o.expected_close_date, o.actual_close_date,
-			o.customer_id, o.customer_name, o.lead_id, o.owner_id, o.owner_name,
-			o.source, o.campaign, o.campaign_id, o.notes, o.tags, o.custom_fields,
-			o.close_reason, o.close_notes, o.closed_at, o.closed_by,
+			o.customer_id, o.customer_name, o.customer_segment, o.lead_id, o.owner_id, o.owner_name,
+			o.source, o.campaign, o.campaign_id, o.attribution, o.notes, o.tags, o.custom_fields,
+			o.close_reason, o.close_reason_id, o.close_notes, o.closed_at, o.closed_by,
 			o.competitor_id, o.competitor_name, o.deal_id,
 			o.activity_count, o.last_activity_at,
 			o.created_at, o.updated_at, o.created_by, o.updated_by, o.deleted_at, o.version
@@ -577,14 +699,16 @@ func (r *OpportunityRepository) GetHighValueOpportunities(ctx context.Context, t
 	return r.List(ctx, tenantID, filter, opts)
 }
 
-// GetTotalPipelineValue returns the total value of open opportunities.
+// GetTotalPipelineValue returns the total value of open opportunities in the given
+// currency, counting opportunities in other currencies by their stored base value.
 func (r *OpportunityRepository) GetTotalPipelineValue(ctx context.Context, tenantID uuid.UUID, currency string) (int64, error) {
 	exec := getExecutor(ctx, r.db)
 
 	query := `
-		SELECT COALESCE(SUM(amount), 0)
+		SELECT COALESCE(SUM(CASE WHEN currency = $2 THEN amount ELSE base_amount END), 0)
 		FROM sales.opportunities
-		WHERE tenant_id = $1 AND currency = $2 AND status = 'open' AND deleted_at IS NULL`
+		WHERE tenant_id = $1 AND (currency = $2 OR base_currency = $2)
+			AND status = 'open' AND deleted_at IS NULL`
 
 	var total int64
 	if err := sqlx.GetContext(ctx, exec, &total, query, tenantID, currency); err != nil {
@@ -594,14 +718,16 @@ func (r *OpportunityRepository) GetTotalPipelineValue(ctx context.Context, tenan
 	return total, nil
 }
 
-// GetWeightedPipelineValue returns the weighted value of open opportunities.
+// GetWeightedPipelineValue returns the weighted value of open opportunities in the given
+// currency, counting opportunities in other currencies by their stored base value.
 func (r *OpportunityRepository) GetWeightedPipelineValue(ctx context.Context, tenantID uuid.UUID, currency string) (int64, error) {
 	exec := getExecutor(ctx, r.db)
 
 	query := `
-		SELECT COALESCE(SUM(weighted_amount), 0)
+		SELECT COALESCE(SUM(CASE WHEN currency = $2 THEN weighted_amount ELSE base_weighted_amount END), 0)
 		FROM sales.opportunities
-		WHERE tenant_id = $1 AND currency = $2 AND status = 'open' AND deleted_at IS NULL`
+		WHERE tenant_id = $1 AND (currency = $2 OR base_currency = $2)
+			AND status = 'open' AND deleted_at IS NULL`
 
 	var total int64
 	if err := sqlx.GetContext(ctx, exec, &total, query, tenantID, currency); err != nil {
@@ -611,6 +737,32 @@ func (r *OpportunityRepository) GetWeightedPipelineValue(ctx context.Context, te
 	return total, nil
 }
 
+// GetPipelineValueByCurrency returns the open pipeline value grouped by currency
+// and by the base currency the stored values were converted to.
+func (r *OpportunityRepository) GetPipelineValueByCurrency(ctx context.Context, tenantID uuid.UUID) ([]*domain.PipelineCurrencyValue, error) {
+	exec := getExecutor(ctx, r.db)
+
+	query := `
+		SELECT currency,
+			COALESCE(base_currency, '') AS base_currency,
+			COUNT(*) AS opportunity_count,
+			COALESCE(SUM(amount), 0) AS amount,
+			COALESCE(SUM(weighted_amount), 0) AS weighted_amount,
+			COALESCE(SUM(base_amount), 0) AS base_amount,
+			COALESCE(SUM(base_weighted_amount), 0) AS base_weighted_amount
+		FROM sales.opportunities
+		WHERE tenant_id = $1 AND status = 'open' AND deleted_at IS NULL
+		GROUP BY currency, base_currency
+		ORDER BY currency, base_currency`
+
+	var values []*domain.PipelineCurrencyValue
+	if err := sqlx.SelectContext(ctx, exec, &values, query, tenantID); err != nil {
+		return nil, fmt.Errorf("failed to get pipeline value by currency: %w", err)
+	}
+
+	return values, nil
+}
+
 // BulkUpdateOwner updates owner for multiple opportunities.
 func (r *OpportunityRepository) BulkUpdateOwner(ctx context.Context, tenantID uuid.UUID, opportunityIDs []uuid.UUID, newOwnerID uuid.UUID) error {
 	if len(opportunityIDs) == 0 {
@@ -642,11 +794,11 @@ func (r *OpportunityRepository) BulkUpdateStage(ctx context.Context, tenantID uu
 
 	query := `
 		UPDATE sales.opportunities
-		SET stage_id = $2, stage_entered_at = $3, updated_at = $3
+		SET stage_id = $2, stage_entered_at = $3, board_position = $5, updated_at = $3
 		WHERE tenant_id = $1 AND id = ANY($4) AND deleted_at IS NULL`
 
 	now := time.Now().UTC()
-	_, err := exec.ExecContext(ctx, query, tenantID, stageID, now, opportunityIDs)
+	_, err := exec.ExecContext(ctx, query, tenantID, stageID, now, opportunityIDs, domain.DefaultBoardPosition(now))
 	if err != nil {
 		return fmt.Errorf("failed to bulk update stage: %w", err)
 	}
@@ -780,6 +932,53 @@ func (r *OpportunityRepository) GetAverageSalesCycle(ctx context.Context, tenant
 	return avgDays, nil
 }
 
+// GetCloseBreakdown groups the closed opportunities of one outcome by reason and
+// by competitor, per currency. Catalog reasons are reported under their current name.
+func (r *OpportunityRepository) GetCloseBreakdown(ctx context.Context, tenantID uuid.UUID, pipelineID *uuid.UUID, status domain.OpportunityStatus) (*domain.CloseBreakdown, error) {
+	exec := getExecutor(ctx, r.db)
+
+	var pipelineFilter interface{}
+	if pipelineID != nil {
+		pipelineFilter = *pipelineID
+	}
+
+	reasonQuery := `
+		SELECT o.close_reason_id AS reason_id,
+			COALESCE(cr.name, NULLIF(o.close_reason, ''), 'Unspecified') AS reason,
+			COUNT(*) AS count, COALESCE(SUM(o.amount), 0) AS amount, o.currency
+		FROM sales.opportunities o
+		LEFT JOIN sales.opportunity_reasons cr ON cr.id = o.close_reason_id
+		WHERE o.tenant_id = $1
+			AND o.status = $2
+			AND o.deleted_at IS NULL
+			AND ($3::uuid IS NULL OR o.pipeline_id = $3)
+		GROUP BY 1, 2, o.currency
+		ORDER BY count DESC, amount DESC`
+
+	breakdown := &domain.CloseBreakdown{}
+	if err := sqlx.SelectContext(ctx, exec, &breakdown.ByReason, reasonQuery, tenantID, string(status), pipelineFilter); err != nil {
+		return nil, fmt.Errorf("failed to get close reason breakdown: %w", err)
+	}
+
+	competitorQuery := `
+		SELECT o.competitor_id, COALESCE(NULLIF(o.competitor_name, ''), 'Unknown') AS competitor_name,
+			COUNT(*) AS count, COALESCE(SUM(o.amount), 0) AS amount, o.currency
+		FROM sales.opportunities o
+		WHERE o.tenant_id = $1
+			AND o.status = $2
+			AND o.deleted_at IS NULL
+			AND ($3::uuid IS NULL OR o.pipeline_id = $3)
+			AND (o.competitor_id IS NOT NULL OR COALESCE(o.competitor_name, '') <> '')
+		GROUP BY 1, 2, o.currency
+		ORDER BY count DESC, amount DESC`
+
+	if err := sqlx.SelectContext(ctx, exec, &breakdown.ByCompetitor, competitorQuery, tenantID, string(status), pipelineFilter); err != nil {
+		return nil, fmt.Errorf("failed to get competitor breakdown: %w", err)
+	}
+
+	return breakdown, nil
+}
+
 // ============================================================================
 // Helper Functions
 // ============================================================================
@@ -794,6 +993,11 @@ func (r *OpportunityRepository) applyFilters(qb *QueryBuilder, filter domain.Opp
 		qb.WhereInStrings("o.status", statuses)
 	}
 
+	// ID filter
+	if len(filter.IDs) > 0 {
+		qb.WhereIn("o.id", filter.IDs)
+	}
+
 	// Pipeline filter
 	if len(filter.PipelineIDs) > 0 {
 		qb.WhereIn("o.pipeline_id", filter.PipelineIDs)
@@ -862,6 +1066,28 @@ func (r *OpportunityRepository) applyFilters(qb *QueryBuilder, filter domain.Opp
 	}
 }
 
+// baseCurrencyValues returns the nullable base currency columns of an opportunity.
+func baseCurrencyValues(opp *domain.Opportunity) (baseAmount, baseWeightedAmount sql.NullInt64, baseCurrency sql.NullString, exchangeRate sql.NullFloat64) {
+	if opp.BaseAmount == nil {
+		return
+	}
+	baseAmount = sql.NullInt64{Int64: opp.BaseAmount.Amount, Valid: true}
+	if opp.BaseWeightedAmount != nil {
+		baseWeightedAmount = sql.NullInt64{Int64: opp.BaseWeightedAmount.Amount, Valid: true}
+	}
+	baseCurrency = sql.NullString{String: opp.BaseAmount.Currency, Valid: true}
+	exchangeRate = sql.NullFloat64{Float64: opp.ExchangeRate, Valid: opp.ExchangeRate > 0}
+	return
+}
+
+// probabilityOverrideValue returns the nullable probability override column of an opportunity.
+func probabilityOverrideValue(opp *domain.Opportunity) sql.NullInt64 {
+	if opp.ProbabilityOverride == nil {
+		return sql.NullInt64{}
+	}
+	return sql.NullInt64{Int64: int64(*opp.ProbabilityOverride), Valid: true}
+}
+
 func (r *OpportunityRepository) toDomain(row *opportunityRow) (*domain.Opportunity, error) {
 	opp := &domain.Opportunity{
 		ID:           row.ID,
@@ -875,6 +1101,7 @@ func (r *OpportunityRepository) toDomain(row *opportunityRow) (*domain.Opportuni
 		StageID:      row.StageID,
 		StageName:    row.StageName.String,
 		StageEnteredAt: row.StageEnteredAt,
+		BoardPosition:  row.BoardPosition,
 		Amount: domain.Money{
 			Amount:   row.Amount,
 			Currency: row.Currency,
@@ -884,8 +1111,10 @@ func (r *OpportunityRepository) toDomain(row *opportunityRow) (*domain.Opportuni
 			Currency: row.Currency,
 		},
 		Probability:   row.Probability,
+		ForecastCategory: domain.ForecastCategory(row.ForecastCategory.String),
 		CustomerID:    row.CustomerID,
 		CustomerName:  row.CustomerName.String,
+		CustomerSegment: row.CustomerSegment.String,
 		OwnerID:       row.OwnerID,
 		OwnerName:     row.OwnerName.String,
 		Source:        row.Source.String,
@@ -909,6 +1138,19 @@ func (r *OpportunityRepository) toDomain(row *opportunityRow) (*domain.Opportuni
 		opp.ActualCloseDate = &row.ActualCloseDate.Time
 	}
 
+	// Probability override
+	if row.ProbabilityOverride.Valid {
+		override := int(row.ProbabilityOverride.Int64)
+		opp.ProbabilityOverride = &override
+	}
+
+	// Base currency values
+	if row.BaseAmount.Valid && row.BaseCurrency.Valid {
+		opp.BaseAmount = &domain.Money{Amount: row.BaseAmount.Int64, Currency: row.BaseCurrency.String}
+		opp.BaseWeightedAmount = &domain.Money{Amount: row.BaseWeightedAmount.Int64, Currency: row.BaseCurrency.String}
+		opp.ExchangeRate = row.ExchangeRate.Float64
+	}
+
 	// Lead
 	if row.LeadID.Valid {
 		opp.LeadID = &row.LeadID.UUID
@@ -932,6 +1174,9 @@ func (r *OpportunityRepository) toDomain(row *opportunityRow) (*domain.Opportuni
 			Reason:   row.CloseReason.String,
 			Notes:    row.CloseNotes.String,
 		}
+		if row.CloseReasonID.Valid {
+			opp.CloseInfo.ReasonID = &row.CloseReasonID.UUID
+		}
 		if row.CompetitorID.Valid {
 			opp.CloseInfo.CompetitorID = &row.CompetitorID.UUID
 		}
@@ -949,6 +1194,11 @@ func (r *OpportunityRepository) toDomain(row *opportunityRow) (*domain.Opportuni
 		opp.CustomFields = customFields
 	}
 
+	// Attribution
+	if err := row.Attribution.MarshalTo(&opp.Attribution); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal attribution: %w", err)
+	}
+
 	return opp, nil
 }
 
@@ -966,16 +1216,18 @@ func (r *OpportunityRepository) insertProducts(ctx context.Context, opportunityI
 	query := `
 		INSERT INTO sales.opportunity_products (
 			id, opportunity_id, tenant_id, product_id, product_name, quantity,
-			unit_price_amount, unit_price_currency, discount, total_price_amount,
-			total_price_currency, notes, created_at
-		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)`
+			unit_price_amount, unit_price_currency, discount, tax, tax_code,
+			tax_name, tax_inclusive, tax_amount, total_price_amount,
+			total_price_currency, notes, category, created_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19)`
 
 	for _, p := range products {
 		_, err := exec.ExecContext(ctx, query,
 			p.ID, opportunityID, tenantID, p.ProductID, p.ProductName, p.Quantity,
 			p.UnitPrice.Amount, p.UnitPrice.Currency, p.Discount,
+			p.Tax, nullString(p.TaxCode), nullString(p.TaxName), p.TaxInclusive, p.TaxAmount.Amount,
 			p.TotalPrice.Amount, p.TotalPrice.Currency,
-			nullString(p.Notes), time.Now().UTC(),
+			nullString(p.Notes), nullString(p.Category), time.Now().UTC(),
 		)
 		if err != nil {
 			return fmt.Errorf("failed to insert product: %w", err)
@@ -991,7 +1243,8 @@ func (r *OpportunityRepository) getProducts(ctx context.Context, opportunityID,
 	query := `
 		SELECT id, product_id, product_name, quantity,
 			unit_price_amount, unit_price_currency, discount,
-			total_price_amount, total_price_currency, notes
+			tax, tax_code, tax_name, tax_inclusive, tax_amount,
+			total_price_amount, total_price_currency, notes, category
 		FROM sales.opportunity_products
 		WHERE opportunity_id = $1 AND tenant_id = $2`
 
@@ -1004,21 +1257,26 @@ func (r *OpportunityRepository) getProducts(ctx context.Context, opportunityID,
 	var products []domain.OpportunityProduct
 	for rows.Next() {
 		var p domain.OpportunityProduct
-		var unitPriceAmount, totalPriceAmount int64
+		var unitPriceAmount, taxAmount, totalPriceAmount int64
 		var unitPriceCurrency, totalPriceCurrency string
-		var notes sql.NullString
+		var taxCode, taxName, notes, category sql.NullString
 
 		if err := rows.Scan(
 			&p.ID, &p.ProductID, &p.ProductName, &p.Quantity,
 			&unitPriceAmount, &unitPriceCurrency, &p.Discount,
-			&totalPriceAmount, &totalPriceCurrency, &notes,
+			&p.Tax, &taxCode, &taxName, &p.TaxInclusive, &taxAmount,
+			&totalPriceAmount, &totalPriceCurrency, &notes, &category,
 		); err != nil {
 			return nil, fmt.Errorf("failed to scan product: %w", err)
 		}
 
 		p.UnitPrice = domain.Money{Amount: unitPriceAmount, Currency: unitPriceCurrency}
+		p.TaxCode = taxCode.String
+		p.TaxName = taxName.String
+		p.TaxAmount = domain.Money{Amount: taxAmount, Currency: totalPriceCurrency}
 		p.TotalPrice = domain.Money{Amount: totalPriceAmount, Currency: totalPriceCurrency}
 		p.Notes = notes.String
+		p.Category = category.String
 
 		products = append(products, p)
 	}
@@ -1047,13 +1305,17 @@ func (r *OpportunityRepository) insertContacts(ctx context.Context, opportunityI
 
 	query := `
 		INSERT INTO sales.opportunity_contacts (
-			opportunity_id, tenant_id, contact_id, name, email, phone, role, is_primary, created_at
-		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)`
+			opportunity_id, tenant_id, contact_id, name, email, phone, role, is_primary, notes, created_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)`
 
 	for _, c := range contacts {
+		addedAt := c.AddedAt
+		if addedAt.IsZero() {
+			addedAt = time.Now().UTC()
+		}
 		_, err := exec.ExecContext(ctx, query,
 			opportunityID, tenantID, c.ContactID, c.Name, c.Email,
-			nullString(c.Phone), c.Role, c.IsPrimary, time.Now().UTC(),
+			nullString(c.Phone), c.Role, c.IsPrimary, nullString(c.Notes), addedAt,
 		)
 		if err != nil {
 			return fmt.Errorf("failed to insert contact: %w", err)
@@ -1067,9 +1329,10 @@ func (r *OpportunityRepository) getContacts(ctx context.Context, opportunityID,
 	exec := getExecutor(ctx, r.db)
 
 	query := `
-		SELECT contact_id, name, email, phone, role, is_primary
+		SELECT contact_id, name, email, phone, role, is_primary, notes, created_at
 		FROM sales.opportunity_contacts
-		WHERE opportunity_id = $1 AND tenant_id = $2`
+		WHERE opportunity_id = $1 AND tenant_id = $2
+		ORDER BY created_at, contact_id`
 
 	rows, err := exec.QueryContext(ctx, query, opportunityID, tenantID)
 	if err != nil {
@@ -1080,13 +1343,14 @@ func (r *OpportunityRepository) getContacts(ctx context.Context, opportunityID,
 	var contacts []domain.OpportunityContact
 	for rows.Next() {
 		var c domain.OpportunityContact
-		var phone sql.NullString
+		var phone, notes sql.NullString
 
-		if err := rows.Scan(&c.ContactID, &c.Name, &c.Email, &phone, &c.Role, &c.IsPrimary); err != nil {
+		if err := rows.Scan(&c.ContactID, &c.Name, &c.Email, &phone, &c.Role, &c.IsPrimary, &notes, &c.AddedAt); err != nil {
 			return nil, fmt.Errorf("failed to scan contact: %w", err)
 		}
 
 		c.Phone = phone.String
+		c.Notes = notes.String
 		contacts = append(contacts, c)
 	}
 
@@ -1112,11 +1376,16 @@ func (r *OpportunityRepository) insertStageHistory(ctx context.Context, opportun
 
 	exec := getExecutor(ctx, r.db)
 
+	// Entries without a pipeline version belong to the pipeline's current version
 	query := `
 		INSERT INTO sales.opportunity_stage_history (
 			id, opportunity_id, tenant_id, stage_id, stage_name,
-			entered_at, exited_at, duration_hours, moved_by, notes
-		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+			entered_at, exited_at, duration_hours, moved_by, notes, pipeline_version
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, COALESCE(NULLIF($11, 0), (
+			SELECT p.version FROM sales.opportunities o
+			JOIN sales.pipelines p ON p.id = o.pipeline_id
+			WHERE o.id = $2
+		)))
 		ON CONFLICT (id) DO NOTHING`
 
 	for _, h := range history {
@@ -1124,7 +1393,7 @@ func (r *OpportunityRepository) insertStageHistory(ctx context.Context, opportun
 		_, err := exec.ExecContext(ctx, query,
 			id, opportunityID, tenantID, h.StageID, h.StageName,
 			h.EnteredAt, NewNullTime(h.ExitedAt).NullTime, h.Duration,
-			h.MovedBy, nullString(h.Notes),
+			h.MovedBy, nullString(h.Notes), h.PipelineVersion,
 		)
 		if err != nil {
 			return fmt.Errorf("failed to insert stage history: %w", err)
@@ -1134,11 +1403,19 @@ func (r *OpportunityRepository) insertStageHistory(ctx context.Context, opportun
 	return nil
 }
 
+func (r *OpportunityRepository) deleteStageHistory(ctx context.Context, opportunityID, tenantID uuid.UUID) error {
+	exec := getExecutor(ctx, r.db)
+
+	query := `DELETE FROM sales.opportunity_stage_history WHERE opportunity_id = $1 AND tenant_id = $2`
+	_, err := exec.ExecContext(ctx, query, opportunityID, tenantID)
+	return err
+}
+
 func (r *OpportunityRepository) getStageHistory(ctx context.Context, opportunityID, tenantID uuid.UUID) ([]domain.StageHistory, error) {
 	exec := getExecutor(ctx, r.db)
 
 	query := `
-		SELECT stage_id, stage_name, entered_at, exited_at, duration_hours, moved_by, notes
+		SELECT stage_id, stage_name, entered_at, exited_at, duration_hours, moved_by, notes, pipeline_version
 		FROM sales.opportunity_stage_history
 		WHERE opportunity_id = $1 AND tenant_id = $2
 		ORDER BY entered_at ASC`
@@ -1154,8 +1431,9 @@ func (r *OpportunityRepository) getStageHistory(ctx context.Context, opportunity
 		var h domain.StageHistory
 		var exitedAt sql.NullTime
 		var notes sql.NullString
+		var pipelineVersion sql.NullInt64
 
-		if err := rows.Scan(&h.StageID, &h.StageName, &h.EnteredAt, &exitedAt, &h.Duration, &h.MovedBy, &notes); err != nil {
+		if err := rows.Scan(&h.StageID, &h.StageName, &h.EnteredAt, &exitedAt, &h.Duration, &h.MovedBy, &notes, &pipelineVersion); err != nil {
 			return nil, fmt.Errorf("failed to scan stage history: %w", err)
 		}
 
@@ -1163,6 +1441,7 @@ func (r *OpportunityRepository) getStageHistory(ctx context.Context, opportunity
 			h.ExitedAt = &exitedAt.Time
 		}
 		h.Notes = notes.String
+		h.PipelineVersion = int(pipelineVersion.Int64)
 		history = append(history, h)
 	}
 
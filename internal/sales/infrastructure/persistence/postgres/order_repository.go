@@ -0,0 +1,352 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+
+	"github.com/kilang-desa-murni/crm/internal/sales/domain"
+)
+
+// OrderRepository implements domain.OrderRepository for PostgreSQL.
+type OrderRepository struct {
+	db *sqlx.DB
+}
+
+// NewOrderRepository creates a new OrderRepository.
+func NewOrderRepository(db *sqlx.DB) *OrderRepository {
+	return &OrderRepository{db: db}
+}
+
+// orderRow is the database representation of an order.
+type orderRow struct {
+	ID                uuid.UUID    `db:"id"`
+	TenantID          uuid.UUID    `db:"tenant_id"`
+	OrderNumber       string       `db:"order_number"`
+	DealID            uuid.UUID    `db:"deal_id"`
+	DealCode          string       `db:"deal_code"`
+	DealName          string       `db:"deal_name"`
+	CustomerID        uuid.UUID    `db:"customer_id"`
+	CustomerName      string       `db:"customer_name"`
+	Status            string       `db:"status"`
+	Items             NullableJSON `db:"items"`
+	DeliveryDate      *time.Time   `db:"delivery_date"`
+	ProductionNotes   string       `db:"production_notes"`
+	ExternalReference string       `db:"external_reference"`
+	TrackingNumber    string       `db:"tracking_number"`
+	StatusHistory     NullableJSON `db:"status_history"`
+	CreatedBy         uuid.UUID    `db:"created_by"`
+	CreatedAt         time.Time    `db:"created_at"`
+	UpdatedAt         time.Time    `db:"updated_at"`
+	ShippedAt         *time.Time   `db:"shipped_at"`
+	DeliveredAt       *time.Time   `db:"delivered_at"`
+	CancelledAt       *time.Time   `db:"cancelled_at"`
+	Version           int          `db:"version"`
+}
+
+const orderColumns = `
+	id, tenant_id, order_number, deal_id, deal_code, deal_name, customer_id,
+	customer_name, status, items, delivery_date, production_notes, external_reference,
+	tracking_number, status_history, created_by, created_at, updated_at, shipped_at,
+	delivered_at, cancelled_at, version`
+
+// Create creates a new order.
+func (r *OrderRepository) Create(ctx context.Context, order *domain.Order) error {
+	exec := getExecutor(ctx, r.db)
+
+	itemsJSON, historyJSON, err := marshalOrderJSON(order)
+	if err != nil {
+		return err
+	}
+
+	query := `
+		INSERT INTO sales.orders (` + orderColumns + `)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, $21, $22)`
+
+	_, err = exec.ExecContext(ctx, query,
+		order.ID, order.TenantID, order.OrderNumber, order.DealID, order.DealCode, order.DealName,
+		order.CustomerID, order.CustomerName, string(order.Status), itemsJSON, order.DeliveryDate,
+		order.ProductionNotes, order.ExternalReference, order.TrackingNumber, historyJSON,
+		order.CreatedBy, order.CreatedAt, order.UpdatedAt, order.ShippedAt, order.DeliveredAt,
+		order.CancelledAt, order.Version,
+	)
+	if err != nil {
+		if IsUniqueViolation(err) {
+			return domain.ErrOrderAlreadyExists
+		}
+		return fmt.Errorf("failed to create order: %w", err)
+	}
+
+	return nil
+}
+
+// GetByID retrieves an order by ID.
+func (r *OrderRepository) GetByID(ctx context.Context, tenantID, orderID uuid.UUID) (*domain.Order, error) {
+	exec := getExecutor(ctx, r.db)
+
+	query := `SELECT ` + orderColumns + `
+		FROM sales.orders
+		WHERE tenant_id = $1 AND id = $2`
+
+	var row orderRow
+	if err := sqlx.GetContext(ctx, exec, &row, query, tenantID, orderID); err != nil {
+		if IsNotFoundError(err) {
+			return nil, domain.ErrOrderNotFound
+		}
+		return nil, fmt.Errorf("failed to get order: %w", err)
+	}
+
+	return row.toDomain()
+}
+
+// Update updates an order, checking the version it was loaded at.
+func (r *OrderRepository) Update(ctx context.Context, order *domain.Order) error {
+	exec := getExecutor(ctx, r.db)
+
+	itemsJSON, historyJSON, err := marshalOrderJSON(order)
+	if err != nil {
+		return err
+	}
+
+	query := `
+		UPDATE sales.orders SET
+			status = $3, items = $4, delivery_date = $5, production_notes = $6,
+			external_reference = $7, tracking_number = $8, status_history = $9,
+			updated_at = $10, shipped_at = $11, delivered_at = $12, cancelled_at = $13,
+			version = $14
+		WHERE tenant_id = $1 AND id = $2 AND version = $14 - 1`
+
+	result, err := exec.ExecContext(ctx, query,
+		order.TenantID, order.ID, string(order.Status), itemsJSON, order.DeliveryDate,
+		order.ProductionNotes, order.ExternalReference, order.TrackingNumber, historyJSON,
+		order.UpdatedAt, order.ShippedAt, order.DeliveredAt, order.CancelledAt, order.Version,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update order: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return domain.ErrOrderNotFound
+	}
+
+	return nil
+}
+
+// GetOpenByDeal retrieves the order of a deal that is not cancelled.
+func (r *OrderRepository) GetOpenByDeal(ctx context.Context, tenantID, dealID uuid.UUID) (*domain.Order, error) {
+	exec := getExecutor(ctx, r.db)
+
+	query := `SELECT ` + orderColumns + `
+		FROM sales.orders
+		WHERE tenant_id = $1 AND deal_id = $2 AND status <> 'cancelled'`
+
+	var row orderRow
+	if err := sqlx.GetContext(ctx, exec, &row, query, tenantID, dealID); err != nil {
+		if IsNotFoundError(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get deal order: %w", err)
+	}
+
+	return row.toDomain()
+}
+
+// List lists orders, newest first.
+func (r *OrderRepository) List(ctx context.Context, tenantID uuid.UUID, filter domain.OrderFilter, opts domain.ListOptions) ([]*domain.Order, int64, error) {
+	exec := getExecutor(ctx, r.db)
+
+	conditions := []string{"tenant_id = $1"}
+	args := []interface{}{tenantID}
+	if len(filter.Statuses) > 0 {
+		statuses := make([]string, len(filter.Statuses))
+		for i, status := range filter.Statuses {
+			statuses[i] = string(status)
+		}
+		args = append(args, pq.Array(statuses))
+		conditions = append(conditions, fmt.Sprintf("status = ANY($%d)", len(args)))
+	}
+	if filter.DealID != nil {
+		args = append(args, *filter.DealID)
+		conditions = append(conditions, fmt.Sprintf("deal_id = $%d", len(args)))
+	}
+	if filter.CustomerID != nil {
+		args = append(args, *filter.CustomerID)
+		conditions = append(conditions, fmt.Sprintf("customer_id = $%d", len(args)))
+	}
+	if filter.DeliveryBefore != nil {
+		args = append(args, *filter.DeliveryBefore)
+		conditions = append(conditions, fmt.Sprintf("delivery_date < $%d", len(args)))
+	}
+	where := ` WHERE ` + strings.Join(conditions, " AND ")
+
+	var total int64
+	if err := sqlx.GetContext(ctx, exec, &total, `SELECT COUNT(*) FROM sales.orders`+where, args...); err != nil {
+		return nil, 0, fmt.Errorf("failed to count orders: %w", err)
+	}
+
+	query := fmt.Sprintf(`SELECT %s
+		FROM sales.orders%s
+		ORDER BY created_at DESC, id DESC
+		LIMIT $%d OFFSET $%d`, orderColumns, where, len(args)+1, len(args)+2)
+
+	var rows []orderRow
+	if err := sqlx.SelectContext(ctx, exec, &rows, query, append(args, opts.Limit(), opts.Offset())...); err != nil {
+		return nil, 0, fmt.Errorf("failed to list orders: %w", err)
+	}
+
+	orders := make([]*domain.Order, len(rows))
+	for i := range rows {
+		order, err := rows[i].toDomain()
+		if err != nil {
+			return nil, 0, err
+		}
+		orders[i] = order
+	}
+
+	return orders, total, nil
+}
+
+func (row *orderRow) toDomain() (*domain.Order, error) {
+	order := &domain.Order{
+		ID:                row.ID,
+		TenantID:          row.TenantID,
+		OrderNumber:       row.OrderNumber,
+		DealID:            row.DealID,
+		DealCode:          row.DealCode,
+		DealName:          row.DealName,
+		CustomerID:        row.CustomerID,
+		CustomerName:      row.CustomerName,
+		Status:            domain.OrderStatus(row.Status),
+		Items:             []domain.OrderItem{},
+		DeliveryDate:      row.DeliveryDate,
+		ProductionNotes:   row.ProductionNotes,
+		ExternalReference: row.ExternalReference,
+		TrackingNumber:    row.TrackingNumber,
+		StatusHistory:     []domain.OrderStatusChange{},
+		CreatedBy:         row.CreatedBy,
+		CreatedAt:         row.CreatedAt,
+		UpdatedAt:         row.UpdatedAt,
+		ShippedAt:         row.ShippedAt,
+		DeliveredAt:       row.DeliveredAt,
+		CancelledAt:       row.CancelledAt,
+		Version:           row.Version,
+	}
+	if err := row.Items.MarshalTo(&order.Items); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal order items: %w", err)
+	}
+	if err := row.StatusHistory.MarshalTo(&order.StatusHistory); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal order status history: %w", err)
+	}
+	return order, nil
+}
+
+func marshalOrderJSON(order *domain.Order) (string, string, error) {
+	itemsJSON, err := ToJSON(order.Items)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to marshal order items: %w", err)
+	}
+	historyJSON, err := ToJSON(order.StatusHistory)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to marshal order status history: %w", err)
+	}
+	return itemsJSON, historyJSON, nil
+}
+
+// ============================================================================
+// Order Webhooks
+// ============================================================================
+
+// OrderWebhookRepository implements domain.OrderWebhookRepository for PostgreSQL.
+type OrderWebhookRepository struct {
+	db *sqlx.DB
+}
+
+// NewOrderWebhookRepository creates a new OrderWebhookRepository.
+func NewOrderWebhookRepository(db *sqlx.DB) *OrderWebhookRepository {
+	return &OrderWebhookRepository{db: db}
+}
+
+// orderWebhookRow is the database representation of an order webhook.
+type orderWebhookRow struct {
+	TenantID  uuid.UUID `db:"tenant_id"`
+	URL       string    `db:"url"`
+	Secret    string    `db:"secret"`
+	Enabled   bool      `db:"enabled"`
+	UpdatedAt time.Time `db:"updated_at"`
+}
+
+// Get retrieves a tenant's order webhook, returning nil if none is configured.
+func (r *OrderWebhookRepository) Get(ctx context.Context, tenantID uuid.UUID) (*domain.OrderWebhook, error) {
+	exec := getExecutor(ctx, r.db)
+
+	query := `
+		SELECT tenant_id, url, secret, enabled, updated_at
+		FROM sales.order_webhooks
+		WHERE tenant_id = $1`
+
+	var row orderWebhookRow
+	if err := sqlx.GetContext(ctx, exec, &row, query, tenantID); err != nil {
+		if IsNotFoundError(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get order webhook: %w", err)
+	}
+
+	return &domain.OrderWebhook{
+		TenantID:  row.TenantID,
+		URL:       row.URL,
+		Secret:    row.Secret,
+		Enabled:   row.Enabled,
+		UpdatedAt: row.UpdatedAt,
+	}, nil
+}
+
+// Upsert creates or updates a tenant's order webhook.
+func (r *OrderWebhookRepository) Upsert(ctx context.Context, webhook *domain.OrderWebhook) error {
+	exec := getExecutor(ctx, r.db)
+
+	query := `
+		INSERT INTO sales.order_webhooks (tenant_id, url, secret, enabled, updated_at)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (tenant_id) DO UPDATE SET
+			url = EXCLUDED.url,
+			secret = EXCLUDED.secret,
+			enabled = EXCLUDED.enabled,
+			updated_at = EXCLUDED.updated_at`
+
+	_, err := exec.ExecContext(ctx, query,
+		webhook.TenantID, webhook.URL, webhook.Secret, webhook.Enabled, webhook.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to upsert order webhook: %w", err)
+	}
+
+	return nil
+}
+
+// Delete removes a tenant's order webhook.
+func (r *OrderWebhookRepository) Delete(ctx context.Context, tenantID uuid.UUID) error {
+	exec := getExecutor(ctx, r.db)
+
+	if _, err := exec.ExecContext(ctx, `DELETE FROM sales.order_webhooks WHERE tenant_id = $1`, tenantID); err != nil {
+		return fmt.Errorf("failed to delete order webhook: %w", err)
+	}
+
+	return nil
+}
+
+// Ensure the repositories implement their domain interfaces
+var (
+	_ domain.OrderRepository        = (*OrderRepository)(nil)
+	_ domain.OrderWebhookRepository = (*OrderWebhookRepository)(nil)
+)
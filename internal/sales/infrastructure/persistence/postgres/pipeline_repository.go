@@ -57,24 +57,36 @@ type pipelineRow struct {
 
 // stageRow represents the database row structure for stages.
 type stageRow struct {
-	ID          uuid.UUID       `db:"id"`
-	TenantID    uuid.UUID       `db:"tenant_id"`
-	PipelineID  uuid.UUID       `db:"pipeline_id"`
-	Name        string          `db:"name"`
-	Description sql.NullString  `db:"description"`
-	Type        string          `db:"type"`
-	Order       int             `db:"stage_order"`
-	Probability int             `db:"probability"`
-	Color       sql.NullString  `db:"color"`
-	IsActive    bool            `db:"is_active"`
-	RottenDays  int             `db:"rotten_days"`
-	AutoActions json.RawMessage `db:"auto_actions"`
-	CreatedAt   time.Time       `db:"created_at"`
-	UpdatedAt   time.Time       `db:"updated_at"`
+	ID            uuid.UUID       `db:"id"`
+	TenantID      uuid.UUID       `db:"tenant_id"`
+	PipelineID    uuid.UUID       `db:"pipeline_id"`
+	Name          string          `db:"name"`
+	Description   sql.NullString  `db:"description"`
+	Type          string          `db:"type"`
+	Order         int             `db:"stage_order"`
+	Probability   int             `db:"probability"`
+	Color         sql.NullString  `db:"color"`
+	IsActive      bool            `db:"is_active"`
+	RottenDays    int             `db:"rotten_days"`
+	AutoActions   json.RawMessage `db:"auto_actions"`
+	WIPLimit      int             `db:"wip_limit"`
+	EntryCriteria json.RawMessage `db:"entry_criteria"`
+	CreatedAt     time.Time       `db:"created_at"`
+	UpdatedAt     time.Time       `db:"updated_at"`
 }
 
-// Create creates a new pipeline in the database.
+// Create creates a new pipeline in the database and records its first version.
 func (r *PipelineRepository) Create(ctx context.Context, pipeline *domain.Pipeline) error {
+	return NewTransactionManager(r.db).WithTransaction(ctx, func(txCtx context.Context) error {
+		if err := r.create(txCtx, pipeline); err != nil {
+			return err
+		}
+		return r.insertVersion(txCtx, domain.NewPipelineVersion(pipeline))
+	})
+}
+
+// create inserts the pipeline and its stages.
+func (r *PipelineRepository) create(ctx context.Context, pipeline *domain.Pipeline) error {
 	executor := getExecutor(ctx, r.db)
 
 	// Marshal custom fields
@@ -135,7 +147,8 @@ func (r *PipelineRepository) Create(ctx context.Context, pipeline *domain.Pipeli
 	return nil
 }
 
-// insertStages inserts multiple stages for a pipeline.
+// insertStages inserts multiple stages for a pipeline, updating stages that
+// already exist in place so opportunities keep referencing them.
 func (r *PipelineRepository) insertStages(ctx context.Context, tenantID, pipelineID uuid.UUID, stages []*domain.Stage) error {
 	executor := getExecutor(ctx, r.db)
 
@@ -143,16 +156,33 @@ func (r *PipelineRepository) insertStages(ctx context.Context, tenantID, pipelin
 		INSERT INTO pipeline_stages (
 			id, tenant_id, pipeline_id, name, description, type, stage_order,
 			probability, color, is_active, rotten_days, auto_actions,
-			created_at, updated_at
+			wip_limit, entry_criteria, created_at, updated_at
 		) VALUES (
-			$1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14
-		)`
+			$1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16
+		)
+		ON CONFLICT (id) DO UPDATE SET
+			name = EXCLUDED.name,
+			description = EXCLUDED.description,
+			type = EXCLUDED.type,
+			stage_order = EXCLUDED.stage_order,
+			probability = EXCLUDED.probability,
+			color = EXCLUDED.color,
+			is_active = EXCLUDED.is_active,
+			rotten_days = EXCLUDED.rotten_days,
+			auto_actions = EXCLUDED.auto_actions,
+			wip_limit = EXCLUDED.wip_limit,
+			entry_criteria = EXCLUDED.entry_criteria,
+			updated_at = EXCLUDED.updated_at`
 
 	for _, stage := range stages {
 		autoActionsJSON, err := json.Marshal(stage.AutoActions)
 		if err != nil {
 			return fmt.Errorf("failed to marshal auto actions: %w", err)
 		}
+		entryCriteriaJSON, err := json.Marshal(stage.EntryCriteria)
+		if err != nil {
+			return fmt.Errorf("failed to marshal entry criteria: %w", err)
+		}
 
 		_, err = executor.ExecContext(ctx, query,
 			stage.ID,
@@ -167,6 +197,8 @@ func (r *PipelineRepository) insertStages(ctx context.Context, tenantID, pipelin
 			stage.IsActive,
 			stage.RottenDays,
 			autoActionsJSON,
+			stage.WIPLimit,
+			entryCriteriaJSON,
 			stage.CreatedAt,
 			stage.UpdatedAt,
 		)
@@ -216,7 +248,7 @@ func (r *PipelineRepository) getStages(ctx context.Context, tenantID, pipelineID
 	query := `
 		SELECT id, tenant_id, pipeline_id, name, description, type, stage_order,
 			probability, color, is_active, rotten_days, auto_actions,
-			created_at, updated_at
+			wip_limit, entry_criteria, created_at, updated_at
 		FROM pipeline_stages
 		WHERE pipeline_id = $1 AND tenant_id = $2
 		ORDER BY stage_order ASC`
@@ -239,8 +271,18 @@ func (r *PipelineRepository) getStages(ctx context.Context, tenantID, pipelineID
 	return stages, nil
 }
 
-// Update updates an existing pipeline.
+// Update updates an existing pipeline and records the new version.
 func (r *PipelineRepository) Update(ctx context.Context, pipeline *domain.Pipeline) error {
+	return NewTransactionManager(r.db).WithTransaction(ctx, func(txCtx context.Context) error {
+		if err := r.update(txCtx, pipeline); err != nil {
+			return err
+		}
+		return r.insertVersion(txCtx, domain.NewPipelineVersion(pipeline))
+	})
+}
+
+// update saves the pipeline and its stages.
+func (r *PipelineRepository) update(ctx context.Context, pipeline *domain.Pipeline) error {
 	executor := getExecutor(ctx, r.db)
 
 	// Marshal custom fields
@@ -307,19 +349,29 @@ func (r *PipelineRepository) Update(ctx context.Context, pipeline *domain.Pipeli
 	return nil
 }
 
-// syncStages syncs stages by deleting existing and inserting new ones.
+// syncStages syncs stages by upserting the pipeline's stages and deleting the
+// ones it no longer has. Stages are updated in place rather than re-created,
+// because opportunities and stage history reference them.
 func (r *PipelineRepository) syncStages(ctx context.Context, tenantID, pipelineID uuid.UUID, stages []*domain.Stage) error {
 	executor := getExecutor(ctx, r.db)
 
-	// Delete existing stages
+	stageIDs := make([]string, len(stages))
+	for i, stage := range stages {
+		stageIDs[i] = stage.ID.String()
+	}
+
+	// Delete stages removed from the pipeline
 	_, err := executor.ExecContext(ctx,
-		`DELETE FROM pipeline_stages WHERE pipeline_id = $1 AND tenant_id = $2`,
-		pipelineID, tenantID)
+		`DELETE FROM pipeline_stages WHERE pipeline_id = $1 AND tenant_id = $2 AND NOT (id = ANY($3::uuid[]))`,
+		pipelineID, tenantID, pq.Array(stageIDs))
 	if err != nil {
+		if IsForeignKeyViolation(err) {
+			return domain.ErrStageInUse
+		}
 		return fmt.Errorf("failed to delete stages: %w", err)
 	}
 
-	// Insert new stages
+	// Insert new stages and update existing ones
 	if len(stages) > 0 {
 		return r.insertStages(ctx, tenantID, pipelineID, stages)
 	}
@@ -327,6 +379,117 @@ func (r *PipelineRepository) syncStages(ctx context.Context, tenantID, pipelineI
 	return nil
 }
 
+// ============================================================================
+// Version History
+// ============================================================================
+
+// pipelineVersionRow represents the database row structure for pipeline versions.
+type pipelineVersionRow struct {
+	PipelineID uuid.UUID       `db:"pipeline_id"`
+	TenantID   uuid.UUID       `db:"tenant_id"`
+	Version    int             `db:"version"`
+	Name       string          `db:"name"`
+	Currency   string          `db:"currency"`
+	Stages     json.RawMessage `db:"stages"`
+	CreatedAt  time.Time       `db:"created_at"`
+}
+
+// insertVersion records a pipeline version. Versions are immutable, so a
+// version that was already recorded is left as it is.
+func (r *PipelineRepository) insertVersion(ctx context.Context, version *domain.PipelineVersion) error {
+	executor := getExecutor(ctx, r.db)
+
+	stagesJSON, err := json.Marshal(version.Stages)
+	if err != nil {
+		return fmt.Errorf("failed to marshal version stages: %w", err)
+	}
+
+	query := `
+		INSERT INTO pipeline_versions (
+			pipeline_id, tenant_id, version, name, currency, stages, created_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7)
+		ON CONFLICT (pipeline_id, version) DO NOTHING`
+
+	_, err = executor.ExecContext(ctx, query,
+		version.PipelineID,
+		version.TenantID,
+		version.Version,
+		version.Name,
+		version.Currency,
+		stagesJSON,
+		version.CreatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to insert pipeline version: %w", err)
+	}
+
+	return nil
+}
+
+// GetVersion retrieves one version of a pipeline.
+func (r *PipelineRepository) GetVersion(ctx context.Context, tenantID, pipelineID uuid.UUID, version int) (*domain.PipelineVersion, error) {
+	executor := getExecutor(ctx, r.db)
+
+	query := `
+		SELECT pipeline_id, tenant_id, version, name, currency, stages, created_at
+		FROM pipeline_versions
+		WHERE pipeline_id = $1 AND tenant_id = $2 AND version = $3`
+
+	var row pipelineVersionRow
+	if err := sqlx.GetContext(ctx, executor, &row, query, pipelineID, tenantID, version); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, domain.ErrPipelineVersionNotFound
+		}
+		return nil, fmt.Errorf("failed to get pipeline version: %w", err)
+	}
+
+	return r.toDomainPipelineVersion(&row)
+}
+
+// ListVersions retrieves every recorded version of a pipeline, newest first.
+func (r *PipelineRepository) ListVersions(ctx context.Context, tenantID, pipelineID uuid.UUID) ([]*domain.PipelineVersion, error) {
+	executor := getExecutor(ctx, r.db)
+
+	query := `
+		SELECT pipeline_id, tenant_id, version, name, currency, stages, created_at
+		FROM pipeline_versions
+		WHERE pipeline_id = $1 AND tenant_id = $2
+		ORDER BY version DESC`
+
+	var rows []pipelineVersionRow
+	if err := sqlx.SelectContext(ctx, executor, &rows, query, pipelineID, tenantID); err != nil {
+		return nil, fmt.Errorf("failed to list pipeline versions: %w", err)
+	}
+
+	versions := make([]*domain.PipelineVersion, len(rows))
+	for i := range rows {
+		version, err := r.toDomainPipelineVersion(&rows[i])
+		if err != nil {
+			return nil, err
+		}
+		versions[i] = version
+	}
+
+	return versions, nil
+}
+
+func (r *PipelineRepository) toDomainPipelineVersion(row *pipelineVersionRow) (*domain.PipelineVersion, error) {
+	var stages []*domain.Stage
+	if err := json.Unmarshal(row.Stages, &stages); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal version stages: %w", err)
+	}
+
+	return &domain.PipelineVersion{
+		PipelineID: row.PipelineID,
+		TenantID:   row.TenantID,
+		Version:    row.Version,
+		Name:       row.Name,
+		Currency:   row.Currency,
+		Stages:     stages,
+		CreatedAt:  row.CreatedAt,
+	}, nil
+}
+
 // Delete soft-deletes a pipeline.
 func (r *PipelineRepository) Delete(ctx context.Context, tenantID, pipelineID uuid.UUID) error {
 	executor := getExecutor(ctx, r.db)
@@ -491,7 +654,7 @@ func (r *PipelineRepository) GetStageByID(ctx context.Context, tenantID, pipelin
 	query := `
 		SELECT id, tenant_id, pipeline_id, name, description, type, stage_order,
 			probability, color, is_active, rotten_days, auto_actions,
-			created_at, updated_at
+			wip_limit, entry_criteria, created_at, updated_at
 		FROM pipeline_stages
 		WHERE id = $1 AND pipeline_id = $2 AND tenant_id = $3`
 
@@ -515,14 +678,18 @@ func (r *PipelineRepository) AddStage(ctx context.Context, tenantID, pipelineID
 	if err != nil {
 		return fmt.Errorf("failed to marshal auto actions: %w", err)
 	}
+	entryCriteriaJSON, err := json.Marshal(stage.EntryCriteria)
+	if err != nil {
+		return fmt.Errorf("failed to marshal entry criteria: %w", err)
+	}
 
 	query := `
 		INSERT INTO pipeline_stages (
 			id, tenant_id, pipeline_id, name, description, type, stage_order,
 			probability, color, is_active, rotten_days, auto_actions,
-			created_at, updated_at
+			wip_limit, entry_criteria, created_at, updated_at
 		) VALUES (
-			$1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14
+			$1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16
 		)`
 
 	_, err = executor.ExecContext(ctx, query,
@@ -538,6 +705,8 @@ func (r *PipelineRepository) AddStage(ctx context.Context, tenantID, pipelineID
 		stage.IsActive,
 		stage.RottenDays,
 		autoActionsJSON,
+		stage.WIPLimit,
+		entryCriteriaJSON,
 		stage.CreatedAt,
 		stage.UpdatedAt,
 	)
@@ -567,6 +736,10 @@ func (r *PipelineRepository) UpdateStage(ctx context.Context, tenantID, pipeline
 	if err != nil {
 		return fmt.Errorf("failed to marshal auto actions: %w", err)
 	}
+	entryCriteriaJSON, err := json.Marshal(stage.EntryCriteria)
+	if err != nil {
+		return fmt.Errorf("failed to marshal entry criteria: %w", err)
+	}
 
 	query := `
 		UPDATE pipeline_stages SET
@@ -579,7 +752,9 @@ func (r *PipelineRepository) UpdateStage(ctx context.Context, tenantID, pipeline
 			is_active = $10,
 			rotten_days = $11,
 			auto_actions = $12,
-			updated_at = $13
+			wip_limit = $13,
+			entry_criteria = $14,
+			updated_at = $15
 		WHERE id = $1 AND pipeline_id = $2 AND tenant_id = $3`
 
 	result, err := executor.ExecContext(ctx, query,
@@ -595,6 +770,8 @@ func (r *PipelineRepository) UpdateStage(ctx context.Context, tenantID, pipeline
 		stage.IsActive,
 		stage.RottenDays,
 		autoActionsJSON,
+		stage.WIPLimit,
+		entryCriteriaJSON,
 		time.Now().UTC(),
 	)
 	if err != nil {
@@ -823,6 +1000,42 @@ func (r *PipelineRepository) GetPipelineStatistics(ctx context.Context, tenantID
 		stats.StageDistribution[sd.StageID] = sd.Count
 	}
 
+	// Get open value by forecast category, derived from the probability
+	// where none is set
+	type categoryValue struct {
+		Category      string `db:"category"`
+		Count         int64  `db:"count"`
+		TotalValue    int64  `db:"total_value"`
+		WeightedValue int64  `db:"weighted_value"`
+	}
+	var categoryValues []categoryValue
+	err = sqlx.SelectContext(ctx, executor, &categoryValues, `
+		SELECT COALESCE(forecast_category, CASE
+				WHEN probability >= 75 THEN 'commit'
+				WHEN probability >= 25 THEN 'pipeline'
+				ELSE 'best_case'
+			END) AS category,
+			COUNT(*) AS count,
+			COALESCE(SUM(amount), 0) AS total_value,
+			COALESCE(SUM(amount * probability / 100), 0) AS weighted_value
+		FROM opportunities
+		WHERE pipeline_id = $1 AND tenant_id = $2 AND deleted_at IS NULL
+			AND status = $3 AND currency = $4
+		GROUP BY 1
+		ORDER BY 1`,
+		pipelineID, tenantID, domain.OpportunityStatusOpen, currency)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get forecast category values: %w", err)
+	}
+	for _, cv := range categoryValues {
+		stats.ForecastCategories = append(stats.ForecastCategories, domain.ForecastCategoryStatistics{
+			Category:         domain.ForecastCategory(cv.Category),
+			OpportunityCount: cv.Count,
+			TotalValue:       domain.Money{Amount: cv.TotalValue, Currency: currency},
+			WeightedValue:    domain.Money{Amount: cv.WeightedValue, Currency: currency},
+		})
+	}
+
 	// Calculate conversion rates between stages
 	stages, err := r.getStages(ctx, tenantID, pipelineID)
 	if err != nil {
@@ -1019,19 +1232,28 @@ func (r *PipelineRepository) toDomainStage(row *stageRow) (*domain.Stage, error)
 		}
 	}
 
+	var entryCriteria domain.StageEntryCriteria
+	if len(row.EntryCriteria) > 0 && string(row.EntryCriteria) != "null" {
+		if err := json.Unmarshal(row.EntryCriteria, &entryCriteria); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal entry criteria: %w", err)
+		}
+	}
+
 	return &domain.Stage{
-		ID:          row.ID,
-		PipelineID:  row.PipelineID,
-		Name:        row.Name,
-		Description: nullStringValue(row.Description),
-		Type:        domain.StageType(row.Type),
-		Order:       row.Order,
-		Probability: row.Probability,
-		Color:       nullStringValue(row.Color),
-		IsActive:    row.IsActive,
-		RottenDays:  row.RottenDays,
-		AutoActions: autoActions,
-		CreatedAt:   row.CreatedAt,
-		UpdatedAt:   row.UpdatedAt,
+		ID:            row.ID,
+		PipelineID:    row.PipelineID,
+		Name:          row.Name,
+		Description:   nullStringValue(row.Description),
+		Type:          domain.StageType(row.Type),
+		Order:         row.Order,
+		Probability:   row.Probability,
+		Color:         nullStringValue(row.Color),
+		IsActive:      row.IsActive,
+		RottenDays:    row.RottenDays,
+		AutoActions:   autoActions,
+		WIPLimit:      row.WIPLimit,
+		EntryCriteria: entryCriteria,
+		CreatedAt:     row.CreatedAt,
+		UpdatedAt:     row.UpdatedAt,
 	}, nil
 }
@@ -0,0 +1,88 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+
+	"github.com/kilang-desa-murni/crm/internal/sales/domain"
+)
+
+// PricingSettingsRepository implements domain.PricingSettingsRepository for PostgreSQL.
+type PricingSettingsRepository struct {
+	db *sqlx.DB
+}
+
+// NewPricingSettingsRepository creates a new PricingSettingsRepository.
+func NewPricingSettingsRepository(db *sqlx.DB) *PricingSettingsRepository {
+	return &PricingSettingsRepository{db: db}
+}
+
+// pricingSettingsRow is the database representation of pricing settings.
+type pricingSettingsRow struct {
+	TenantID   uuid.UUID    `db:"tenant_id"`
+	PriceLists NullableJSON `db:"price_lists"`
+	UpdatedAt  time.Time    `db:"updated_at"`
+}
+
+// Get retrieves a tenant's pricing settings, returning nil if none are configured.
+func (r *PricingSettingsRepository) Get(ctx context.Context, tenantID uuid.UUID) (*domain.PricingSettings, error) {
+	exec := getExecutor(ctx, r.db)
+
+	query := `
+		SELECT tenant_id, price_lists, updated_at
+		FROM sales.pricing_settings
+		WHERE tenant_id = $1`
+
+	var row pricingSettingsRow
+	if err := sqlx.GetContext(ctx, exec, &row, query, tenantID); err != nil {
+		if IsNotFoundError(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get pricing settings: %w", err)
+	}
+
+	settings := &domain.PricingSettings{
+		TenantID:   row.TenantID,
+		PriceLists: []domain.PriceList{},
+		UpdatedAt:  row.UpdatedAt,
+	}
+	if err := row.PriceLists.MarshalTo(&settings.PriceLists); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal price lists: %w", err)
+	}
+
+	return settings, nil
+}
+
+// Upsert creates or updates a tenant's pricing settings.
+func (r *PricingSettingsRepository) Upsert(ctx context.Context, settings *domain.PricingSettings) error {
+	exec := getExecutor(ctx, r.db)
+
+	priceLists := settings.PriceLists
+	if priceLists == nil {
+		priceLists = []domain.PriceList{}
+	}
+	priceListsJSON, err := ToJSON(priceLists)
+	if err != nil {
+		return fmt.Errorf("failed to marshal price lists: %w", err)
+	}
+
+	query := `
+		INSERT INTO sales.pricing_settings (tenant_id, price_lists, updated_at)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (tenant_id) DO UPDATE SET
+			price_lists = EXCLUDED.price_lists,
+			updated_at = EXCLUDED.updated_at`
+
+	if _, err := exec.ExecContext(ctx, query, settings.TenantID, priceListsJSON, settings.UpdatedAt); err != nil {
+		return fmt.Errorf("failed to upsert pricing settings: %w", err)
+	}
+
+	return nil
+}
+
+// Ensure PricingSettingsRepository implements domain.PricingSettingsRepository
+var _ domain.PricingSettingsRepository = (*PricingSettingsRepository)(nil)
@@ -0,0 +1,174 @@
+// Package postgres contains PostgreSQL repository implementations for the sales service.
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+
+	"github.com/kilang-desa-murni/crm/internal/sales/domain"
+)
+
+// ============================================================================
+// Report Export Repository
+// ============================================================================
+
+// ReportExportRepository implements domain.ReportExportRepository for PostgreSQL.
+type ReportExportRepository struct {
+	db *sqlx.DB
+}
+
+// NewReportExportRepository creates a new ReportExportRepository.
+func NewReportExportRepository(db *sqlx.DB) *ReportExportRepository {
+	return &ReportExportRepository{db: db}
+}
+
+const reportExportColumns = `
+	id, tenant_id, requested_by, report_type, format, parameters, status,
+	file_id, file_name, file_size, error, created_at, completed_at, expires_at`
+
+// Create creates a new report export.
+func (r *ReportExportRepository) Create(ctx context.Context, export *domain.ReportExport) error {
+	exec := getExecutor(ctx, r.db)
+
+	query := `
+		INSERT INTO sales.report_exports (` + reportExportColumns + `)
+		VALUES (
+			:id, :tenant_id, :requested_by, :report_type, :format, :parameters, :status,
+			:file_id, :file_name, :file_size, :error, :created_at, :completed_at, :expires_at
+		)`
+
+	if _, err := sqlx.NamedExecContext(ctx, exec, query, export); err != nil {
+		return fmt.Errorf("failed to create report export: %w", err)
+	}
+
+	return nil
+}
+
+// Update updates a report export.
+func (r *ReportExportRepository) Update(ctx context.Context, export *domain.ReportExport) error {
+	exec := getExecutor(ctx, r.db)
+
+	query := `
+		UPDATE sales.report_exports SET
+			status = :status,
+			file_id = :file_id,
+			file_name = :file_name,
+			file_size = :file_size,
+			error = :error,
+			completed_at = :completed_at,
+			expires_at = :expires_at
+		WHERE id = :id AND tenant_id = :tenant_id`
+
+	result, err := sqlx.NamedExecContext(ctx, exec, query, export)
+	if err != nil {
+		return fmt.Errorf("failed to update report export: %w", err)
+	}
+
+	rows, _ := result.RowsAffected()
+	if rows == 0 {
+		return domain.ErrReportExportNotFound
+	}
+
+	return nil
+}
+
+// GetByID retrieves a report export by ID.
+func (r *ReportExportRepository) GetByID(ctx context.Context, tenantID, exportID uuid.UUID) (*domain.ReportExport, error) {
+	exec := getExecutor(ctx, r.db)
+
+	query := `SELECT ` + reportExportColumns + `
+		FROM sales.report_exports
+		WHERE id = $1 AND tenant_id = $2`
+
+	var export domain.ReportExport
+	if err := sqlx.GetContext(ctx, exec, &export, query, exportID, tenantID); err != nil {
+		if IsNotFoundError(err) {
+			return nil, domain.ErrReportExportNotFound
+		}
+		return nil, fmt.Errorf("failed to get report export: %w", err)
+	}
+
+	return &export, nil
+}
+
+// ListByUser lists the most recent report exports requested by a user.
+func (r *ReportExportRepository) ListByUser(ctx context.Context, tenantID, userID uuid.UUID, limit int) ([]*domain.ReportExport, error) {
+	exec := getExecutor(ctx, r.db)
+
+	query := `SELECT ` + reportExportColumns + `
+		FROM sales.report_exports
+		WHERE tenant_id = $1 AND requested_by = $2
+		ORDER BY created_at DESC
+		LIMIT $3`
+
+	var exports []*domain.ReportExport
+	if err := sqlx.SelectContext(ctx, exec, &exports, query, tenantID, userID, limit); err != nil {
+		return nil, fmt.Errorf("failed to list report exports: %w", err)
+	}
+
+	return exports, nil
+}
+
+// ============================================================================
+// Report Branding Repository
+// ============================================================================
+
+// ReportBrandingRepository implements domain.ReportBrandingRepository for PostgreSQL.
+type ReportBrandingRepository struct {
+	db *sqlx.DB
+}
+
+// NewReportBrandingRepository creates a new ReportBrandingRepository.
+func NewReportBrandingRepository(db *sqlx.DB) *ReportBrandingRepository {
+	return &ReportBrandingRepository{db: db}
+}
+
+// Get retrieves a tenant's report branding, returning nil if none is configured.
+func (r *ReportBrandingRepository) Get(ctx context.Context, tenantID uuid.UUID) (*domain.ReportBranding, error) {
+	exec := getExecutor(ctx, r.db)
+
+	query := `
+		SELECT tenant_id, company_name, logo_file_id, locale, currency_display, updated_at
+		FROM sales.report_branding
+		WHERE tenant_id = $1`
+
+	var branding domain.ReportBranding
+	if err := sqlx.GetContext(ctx, exec, &branding, query, tenantID); err != nil {
+		if IsNotFoundError(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get report branding: %w", err)
+	}
+
+	return &branding, nil
+}
+
+// Upsert creates or updates a tenant's report branding.
+func (r *ReportBrandingRepository) Upsert(ctx context.Context, branding *domain.ReportBranding) error {
+	exec := getExecutor(ctx, r.db)
+
+	query := `
+		INSERT INTO sales.report_branding (tenant_id, company_name, logo_file_id, locale, currency_display, updated_at)
+		VALUES (:tenant_id, :company_name, :logo_file_id, :locale, :currency_display, :updated_at)
+		ON CONFLICT (tenant_id) DO UPDATE SET
+			company_name = EXCLUDED.company_name,
+			logo_file_id = EXCLUDED.logo_file_id,
+			locale = EXCLUDED.locale,
+			currency_display = EXCLUDED.currency_display,
+			updated_at = EXCLUDED.updated_at`
+
+	if _, err := sqlx.NamedExecContext(ctx, exec, query, branding); err != nil {
+		return fmt.Errorf("failed to upsert report branding: %w", err)
+	}
+
+	return nil
+}
+
+// Ensure repositories implement their domain interfaces
+var (
+	_ domain.ReportExportRepository   = (*ReportExportRepository)(nil)
+	_ domain.ReportBrandingRepository = (*ReportBrandingRepository)(nil)
+)
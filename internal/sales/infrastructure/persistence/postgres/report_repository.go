@@ -0,0 +1,339 @@
+// Package postgres contains PostgreSQL repository implementations for the sales service.
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+
+	"github.com/kilang-desa-murni/crm/internal/sales/domain"
+)
+
+// ============================================================================
+// Report Repository
+// ============================================================================
+
+// ReportRepository implements domain.ReportRepository for PostgreSQL.
+type ReportRepository struct {
+	db *sqlx.DB
+}
+
+// NewReportRepository creates a new ReportRepository.
+func NewReportRepository(db *sqlx.DB) *ReportRepository {
+	return &ReportRepository{db: db}
+}
+
+// aggregateInsertColumns is the column list shared by all aggregate refresh queries.
+const aggregateInsertColumns = `
+	INSERT INTO sales.daily_sales_aggregates (
+		tenant_id, day, dimension, dimension_key, dimension_label, currency,
+		new_leads, converted_leads, opportunities_created,
+		opportunities_won, opportunities_lost, won_revenue, aggregated_at
+	)`
+
+// aggregateSelectColumns sums the source counters of a dimension subquery.
+const aggregateSelectColumns = `
+	SELECT
+		tenant_id, $1::date, $3, dimension_key, MAX(dimension_label), currency,
+		SUM(new_leads), SUM(converted_leads), SUM(opportunities_created),
+		SUM(opportunities_won), SUM(opportunities_lost), SUM(won_revenue), NOW()`
+
+// opportunityCounters computes opportunity counters for the [$1, $2) window.
+const opportunityCounters = `
+	0 AS new_leads,
+	0 AS converted_leads,
+	CASE WHEN o.created_at >= $1 AND o.created_at < $2 THEN 1 ELSE 0 END AS opportunities_created,
+	CASE WHEN o.status = 'won' AND o.closed_at >= $1 AND o.closed_at < $2 THEN 1 ELSE 0 END AS opportunities_won,
+	CASE WHEN o.status = 'lost' AND o.closed_at >= $1 AND o.closed_at < $2 THEN 1 ELSE 0 END AS opportunities_lost`
+
+// opportunityWindow restricts opportunities to those touched in the [$1, $2) window.
+const opportunityWindow = `
+	o.deleted_at IS NULL
+	AND ((o.created_at >= $1 AND o.created_at < $2) OR (o.closed_at >= $1 AND o.closed_at < $2))`
+
+// leadCounters computes lead counters for the [$1, $2) window.
+const leadCounters = `
+	'' AS currency,
+	CASE WHEN l.created_at >= $1 AND l.created_at < $2 THEN 1 ELSE 0 END AS new_leads,
+	CASE WHEN l.converted_at >= $1 AND l.converted_at < $2 THEN 1 ELSE 0 END AS converted_leads,
+	0 AS opportunities_created,
+	0 AS opportunities_won,
+	0 AS opportunities_lost,
+	0 AS won_revenue`
+
+// leadWindow restricts leads to those touched in the [$1, $2) window.
+const leadWindow = `
+	l.deleted_at IS NULL
+	AND ((l.created_at >= $1 AND l.created_at < $2) OR (l.converted_at >= $1 AND l.converted_at < $2))`
+
+// Campaign and channel of leads and opportunities. Opportunities entered
+// without a lead fall back to their own campaign and source.
+const (
+	leadCampaign        = `COALESCE(NULLIF(l.attribution->>'utm_campaign', ''), 'none')`
+	opportunityCampaign = `COALESCE(NULLIF(o.attribution->>'utm_campaign', ''), NULLIF(LOWER(o.campaign), ''), 'none')`
+	leadChannel         = `COALESCE(NULLIF(l.attribution->>'utm_medium', ''), NULLIF(l.source, ''), 'unknown')`
+	opportunityChannel  = `COALESCE(NULLIF(o.attribution->>'utm_medium', ''), NULLIF(o.source, ''), 'unknown')`
+)
+
+// revenueSplitCredit is the part of a split opportunity's amount credited
+// to a split user: rounded down at the running total of percents, so the
+// credits add up to the amount as domain.RevenueSplits.Credits does.
+const revenueSplitCredit = `
+	o.amount * SUM(rs.percent) OVER (PARTITION BY rs.opportunity_id ORDER BY rs.position) / 100
+	- o.amount * (SUM(rs.percent) OVER (PARTITION BY rs.opportunity_id ORDER BY rs.position) - rs.percent) / 100`
+
+// refreshQueries maps each dimension to its aggregation query.
+var refreshQueries = map[domain.AggregateDimension]string{
+	// The owner keeps the counters of a split opportunity while its won
+	// revenue is credited to the split users
+	domain.AggregateDimensionOwner: aggregateInsertColumns + aggregateSelectColumns + `
+		FROM (
+			SELECT l.tenant_id, COALESCE(l.owner_id::text, 'unassigned') AS dimension_key, '' AS dimension_label,` + leadCounters + `
+			FROM sales.leads l
+			WHERE` + leadWindow + `
+			UNION ALL
+			SELECT o.tenant_id, COALESCE(o.owner_id::text, 'unassigned'), COALESCE(o.owner_name, ''), o.currency,` + opportunityCounters + `,
+				CASE WHEN o.status = 'won' AND o.closed_at >= $1 AND o.closed_at < $2
+					AND NOT EXISTS (SELECT 1 FROM sales.opportunity_revenue_splits rs WHERE rs.opportunity_id = o.id)
+					THEN o.amount ELSE 0 END
+			FROM sales.opportunities o
+			WHERE` + opportunityWindow + `
+			UNION ALL
+			SELECT o.tenant_id, rs.user_id::text, rs.user_name, o.currency, 0, 0, 0, 0, 0,` + revenueSplitCredit + `
+			FROM sales.opportunity_revenue_splits rs
+			JOIN sales.opportunities o ON o.id = rs.opportunity_id
+			WHERE o.deleted_at IS NULL AND o.status = 'won' AND o.closed_at >= $1 AND o.closed_at < $2
+		) src
+		GROUP BY tenant_id, dimension_key, currency`,
+
+	domain.AggregateDimensionRegion: aggregateInsertColumns + aggregateSelectColumns + `
+		FROM (
+			SELECT l.tenant_id, COALESCE(NULLIF(l.state, ''), 'unknown') AS dimension_key,
+				COALESCE(NULLIF(l.state, ''), 'Unknown') AS dimension_label,` + leadCounters + `
+			FROM sales.leads l
+			WHERE` + leadWindow + `
+			UNION ALL
+			SELECT o.tenant_id, COALESCE(NULLIF(ol.state, ''), 'unknown'), COALESCE(NULLIF(ol.state, ''), 'Unknown'), o.currency,` + opportunityCounters + `,
+				CASE WHEN o.status = 'won' AND o.closed_at >= $1 AND o.closed_at < $2 THEN o.amount ELSE 0 END
+			FROM sales.opportunities o
+			LEFT JOIN sales.leads ol ON ol.id = o.lead_id
+			WHERE` + opportunityWindow + `
+		) src
+		GROUP BY tenant_id, dimension_key, currency`,
+
+	domain.AggregateDimensionProduct: aggregateInsertColumns + aggregateSelectColumns + `
+		FROM (
+			SELECT o.tenant_id, op.product_id::text AS dimension_key, op.product_name AS dimension_label,
+				op.total_price_currency AS currency,` + opportunityCounters + `,
+				CASE WHEN o.status = 'won' AND o.closed_at >= $1 AND o.closed_at < $2 THEN op.total_price_amount ELSE 0 END AS won_revenue
+			FROM sales.opportunity_products op
+			JOIN sales.opportunities o ON o.id = op.opportunity_id
+			WHERE` + opportunityWindow + `
+		) src
+		GROUP BY tenant_id, dimension_key, currency`,
+
+	domain.AggregateDimensionCampaign: aggregateInsertColumns + aggregateSelectColumns + `
+		FROM (
+			SELECT l.tenant_id, ` + leadCampaign + ` AS dimension_key, ` + leadCampaign + ` AS dimension_label,` + leadCounters + `
+			FROM sales.leads l
+			WHERE` + leadWindow + `
+			UNION ALL
+			SELECT o.tenant_id, ` + opportunityCampaign + `, ` + opportunityCampaign + `, o.currency,` + opportunityCounters + `,
+				CASE WHEN o.status = 'won' AND o.closed_at >= $1 AND o.closed_at < $2 THEN o.amount ELSE 0 END
+			FROM sales.opportunities o
+			WHERE` + opportunityWindow + `
+		) src
+		GROUP BY tenant_id, dimension_key, currency`,
+
+	domain.AggregateDimensionChannel: aggregateInsertColumns + aggregateSelectColumns + `
+		FROM (
+			SELECT l.tenant_id, ` + leadChannel + ` AS dimension_key, ` + leadChannel + ` AS dimension_label,` + leadCounters + `
+			FROM sales.leads l
+			WHERE` + leadWindow + `
+			UNION ALL
+			SELECT o.tenant_id, ` + opportunityChannel + `, ` + opportunityChannel + `, o.currency,` + opportunityCounters + `,
+				CASE WHEN o.status = 'won' AND o.closed_at >= $1 AND o.closed_at < $2 THEN o.amount ELSE 0 END
+			FROM sales.opportunities o
+			WHERE` + opportunityWindow + `
+		) src
+		GROUP BY tenant_id, dimension_key, currency`,
+
+	// Lines are summed per opportunity first, so an opportunity counts once
+	// per category however many of its products fall in it
+	domain.AggregateDimensionProductCategory: aggregateInsertColumns + aggregateSelectColumns + `
+		FROM (
+			SELECT o.tenant_id, op.category AS dimension_key, op.category AS dimension_label,
+				op.currency AS currency,` + opportunityCounters + `,
+				CASE WHEN o.status = 'won' AND o.closed_at >= $1 AND o.closed_at < $2 THEN op.amount ELSE 0 END AS won_revenue
+			FROM (
+				SELECT opportunity_id, COALESCE(NULLIF(category, ''), 'uncategorized') AS category,
+					total_price_currency AS currency, SUM(total_price_amount) AS amount
+				FROM sales.opportunity_products
+				GROUP BY 1, 2, 3
+			) op
+			JOIN sales.opportunities o ON o.id = op.opportunity_id
+			WHERE` + opportunityWindow + `
+		) src
+		GROUP BY tenant_id, dimension_key, currency`,
+
+	domain.AggregateDimensionCustomerSegment: aggregateInsertColumns + aggregateSelectColumns + `
+		FROM (
+			SELECT o.tenant_id, COALESCE(NULLIF(o.customer_segment, ''), 'unknown') AS dimension_key,
+				COALESCE(NULLIF(o.customer_segment, ''), 'unknown') AS dimension_label, o.currency,` + opportunityCounters + `,
+				CASE WHEN o.status = 'won' AND o.closed_at >= $1 AND o.closed_at < $2 THEN o.amount ELSE 0 END
+			FROM sales.opportunities o
+			WHERE` + opportunityWindow + `
+		) src
+		GROUP BY tenant_id, dimension_key, currency`,
+}
+
+// RefreshDailyAggregates recomputes the aggregates of all tenants for the given day.
+func (r *ReportRepository) RefreshDailyAggregates(ctx context.Context, day time.Time) (int64, error) {
+	start := domain.TruncateToDay(day)
+	end := start.AddDate(0, 0, 1)
+
+	var written int64
+	err := NewTransactionManager(r.db).WithTransaction(ctx, func(txCtx context.Context) error {
+		exec := getExecutor(txCtx, r.db)
+
+		if _, err := exec.ExecContext(txCtx,
+			`DELETE FROM sales.daily_sales_aggregates WHERE day = $1::date`, start); err != nil {
+			return fmt.Errorf("failed to clear daily aggregates: %w", err)
+		}
+
+		for _, dimension := range domain.AggregateDimensions() {
+			result, err := exec.ExecContext(txCtx, refreshQueries[dimension], start, end, string(dimension))
+			if err != nil {
+				return fmt.Errorf("failed to aggregate %s dimension: %w", dimension, err)
+			}
+			rows, _ := result.RowsAffected()
+			written += rows
+		}
+
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return written, nil
+}
+
+// GetSalesPerformance returns aggregated sales performance grouped by the given dimension.
+// Counters include every currency; revenue is limited to the requested currency.
+func (r *ReportRepository) GetSalesPerformance(
+	ctx context.Context,
+	tenantID uuid.UUID,
+	period domain.ReportPeriod,
+	groupBy domain.ReportGroupBy,
+	currency string,
+) ([]*domain.SalesPerformanceRow, error) {
+	exec := getExecutor(ctx, r.db)
+
+	groupKey := "dimension_key"
+	groupLabel := "MAX(dimension_label)"
+	orderBy := "won_revenue DESC, group_key"
+	if groupBy == domain.ReportGroupByDay {
+		groupKey = "to_char(day, 'YYYY-MM-DD')"
+		groupLabel = groupKey
+		orderBy = "group_key"
+	}
+
+	query := fmt.Sprintf(`
+		SELECT
+			%s AS group_key,
+			%s AS group_label,
+			$5::text AS currency,
+			COALESCE(SUM(new_leads), 0) AS new_leads,
+			COALESCE(SUM(converted_leads), 0) AS converted_leads,
+			COALESCE(SUM(opportunities_created), 0) AS opportunities_created,
+			COALESCE(SUM(opportunities_won), 0) AS opportunities_won,
+			COALESCE(SUM(opportunities_lost), 0) AS opportunities_lost,
+			COALESCE(SUM(CASE WHEN currency = $5 THEN won_revenue ELSE 0 END), 0) AS won_revenue
+		FROM sales.daily_sales_aggregates
+		WHERE tenant_id = $1
+			AND dimension = $2
+			AND day >= $3::date
+			AND day < $4::date
+		GROUP BY %s
+		ORDER BY %s`, groupKey, groupLabel, groupKey, orderBy)
+
+	var rows []*domain.SalesPerformanceRow
+	if err := sqlx.SelectContext(ctx, exec, &rows, query,
+		tenantID, string(groupBy.Dimension()), period.From, period.To, currency,
+	); err != nil {
+		return nil, fmt.Errorf("failed to get sales performance: %w", err)
+	}
+
+	return rows, nil
+}
+
+// GetDailyAggregates returns the raw daily aggregate rows for a dimension.
+func (r *ReportRepository) GetDailyAggregates(
+	ctx context.Context,
+	tenantID uuid.UUID,
+	period domain.ReportPeriod,
+	dimension domain.AggregateDimension,
+) ([]*domain.DailySalesAggregate, error) {
+	exec := getExecutor(ctx, r.db)
+
+	query := `
+		SELECT tenant_id, day, dimension, dimension_key, dimension_label, currency,
+			new_leads, converted_leads, opportunities_created,
+			opportunities_won, opportunities_lost, won_revenue, aggregated_at
+		FROM sales.daily_sales_aggregates
+		WHERE tenant_id = $1
+			AND dimension = $2
+			AND day >= $3::date
+			AND day < $4::date
+		ORDER BY day, dimension_key, currency`
+
+	var rows []*domain.DailySalesAggregate
+	if err := sqlx.SelectContext(ctx, exec, &rows, query, tenantID, string(dimension), period.From, period.To); err != nil {
+		return nil, fmt.Errorf("failed to get daily aggregates: %w", err)
+	}
+
+	return rows, nil
+}
+
+// RecordAggregationRun stores the outcome of an aggregation run.
+func (r *ReportRepository) RecordAggregationRun(ctx context.Context, run *domain.AggregationRun) error {
+	exec := getExecutor(ctx, r.db)
+
+	query := `
+		INSERT INTO sales.report_aggregation_runs (day, rows_written, started_at, completed_at)
+		VALUES ($1::date, $2, $3, $4)`
+
+	if _, err := exec.ExecContext(ctx, query, run.Day, run.RowsWritten, run.StartedAt, run.CompletedAt); err != nil {
+		return fmt.Errorf("failed to record aggregation run: %w", err)
+	}
+
+	return nil
+}
+
+// GetLastAggregationRun returns the most recent aggregation run.
+func (r *ReportRepository) GetLastAggregationRun(ctx context.Context) (*domain.AggregationRun, error) {
+	exec := getExecutor(ctx, r.db)
+
+	query := `
+		SELECT day, rows_written, started_at, completed_at
+		FROM sales.report_aggregation_runs
+		ORDER BY day DESC, completed_at DESC
+		LIMIT 1`
+
+	var run domain.AggregationRun
+	if err := sqlx.GetContext(ctx, exec, &run, query); err != nil {
+		if IsNotFoundError(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get last aggregation run: %w", err)
+	}
+
+	return &run, nil
+}
+
+// Ensure ReportRepository implements domain.ReportRepository
+var _ domain.ReportRepository = (*ReportRepository)(nil)
@@ -0,0 +1,390 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+
+	"github.com/kilang-desa-murni/crm/internal/sales/domain"
+)
+
+// ============================================================================
+// Retention Policy Repository
+// ============================================================================
+
+// RetentionPolicyRepository implements domain.RetentionPolicyRepository for PostgreSQL.
+type RetentionPolicyRepository struct {
+	db *sqlx.DB
+}
+
+// NewRetentionPolicyRepository creates a new RetentionPolicyRepository.
+func NewRetentionPolicyRepository(db *sqlx.DB) *RetentionPolicyRepository {
+	return &RetentionPolicyRepository{db: db}
+}
+
+const retentionPolicyColumns = `tenant_id, entity_type, action, after_days, enabled, last_run_at, updated_by, created_at, updated_at`
+
+// List lists a tenant's retention policies.
+func (r *RetentionPolicyRepository) List(ctx context.Context, tenantID uuid.UUID) ([]*domain.RetentionPolicy, error) {
+	exec := getExecutor(ctx, r.db)
+
+	query := `SELECT ` + retentionPolicyColumns + `
+		FROM sales.retention_policies
+		WHERE tenant_id = $1
+		ORDER BY entity_type`
+
+	var policies []*domain.RetentionPolicy
+	if err := sqlx.SelectContext(ctx, exec, &policies, query, tenantID); err != nil {
+		return nil, fmt.Errorf("failed to list retention policies: %w", err)
+	}
+
+	return policies, nil
+}
+
+// Get retrieves a tenant's policy for an entity type, returning nil if none is configured.
+func (r *RetentionPolicyRepository) Get(ctx context.Context, tenantID uuid.UUID, entityType domain.RetentionEntityType) (*domain.RetentionPolicy, error) {
+	exec := getExecutor(ctx, r.db)
+
+	query := `SELECT ` + retentionPolicyColumns + `
+		FROM sales.retention_policies
+		WHERE tenant_id = $1 AND entity_type = $2`
+
+	var policy domain.RetentionPolicy
+	if err := sqlx.GetContext(ctx, exec, &policy, query, tenantID, entityType); err != nil {
+		if IsNotFoundError(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get retention policy: %w", err)
+	}
+
+	return &policy, nil
+}
+
+// ListEnabled lists the enabled policies of all tenants, least recently run first.
+func (r *RetentionPolicyRepository) ListEnabled(ctx context.Context) ([]*domain.RetentionPolicy, error) {
+	exec := getExecutor(ctx, r.db)
+
+	query := `SELECT ` + retentionPolicyColumns + `
+		FROM sales.retention_policies
+		WHERE enabled
+		ORDER BY last_run_at NULLS FIRST`
+
+	var policies []*domain.RetentionPolicy
+	if err := sqlx.SelectContext(ctx, exec, &policies, query); err != nil {
+		return nil, fmt.Errorf("failed to list enabled retention policies: %w", err)
+	}
+
+	return policies, nil
+}
+
+// Upsert creates or updates a policy.
+func (r *RetentionPolicyRepository) Upsert(ctx context.Context, policy *domain.RetentionPolicy) error {
+	exec := getExecutor(ctx, r.db)
+
+	query := `
+		INSERT INTO sales.retention_policies (` + retentionPolicyColumns + `)
+		VALUES (:tenant_id, :entity_type, :action, :after_days, :enabled, :last_run_at, :updated_by, :created_at, :updated_at)
+		ON CONFLICT (tenant_id, entity_type) DO UPDATE SET
+			action = EXCLUDED.action,
+			after_days = EXCLUDED.after_days,
+			enabled = EXCLUDED.enabled,
+			updated_by = EXCLUDED.updated_by,
+			updated_at = EXCLUDED.updated_at`
+
+	if _, err := sqlx.NamedExecContext(ctx, exec, query, policy); err != nil {
+		return fmt.Errorf("failed to upsert retention policy: %w", err)
+	}
+
+	return nil
+}
+
+// Delete deletes a policy.
+func (r *RetentionPolicyRepository) Delete(ctx context.Context, tenantID uuid.UUID, entityType domain.RetentionEntityType) error {
+	exec := getExecutor(ctx, r.db)
+
+	query := `DELETE FROM sales.retention_policies WHERE tenant_id = $1 AND entity_type = $2`
+
+	result, err := exec.ExecContext(ctx, query, tenantID, entityType)
+	if err != nil {
+		return fmt.Errorf("failed to delete retention policy: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return domain.ErrRetentionPolicyNotFound
+	}
+
+	return nil
+}
+
+// MarkRun records when a policy was last applied.
+func (r *RetentionPolicyRepository) MarkRun(ctx context.Context, tenantID uuid.UUID, entityType domain.RetentionEntityType, at time.Time) error {
+	exec := getExecutor(ctx, r.db)
+
+	query := `UPDATE sales.retention_policies SET last_run_at = $3 WHERE tenant_id = $1 AND entity_type = $2`
+
+	if _, err := exec.ExecContext(ctx, query, tenantID, entityType, at); err != nil {
+		return fmt.Errorf("failed to mark retention policy run: %w", err)
+	}
+
+	return nil
+}
+
+// Ensure RetentionPolicyRepository implements domain.RetentionPolicyRepository
+var _ domain.RetentionPolicyRepository = (*RetentionPolicyRepository)(nil)
+
+// ============================================================================
+// Archived Record Repository
+// ============================================================================
+
+// ArchivedRecordRepository implements domain.ArchivedRecordRepository for PostgreSQL.
+type ArchivedRecordRepository struct {
+	db *sqlx.DB
+}
+
+// NewArchivedRecordRepository creates a new ArchivedRecordRepository.
+func NewArchivedRecordRepository(db *sqlx.DB) *ArchivedRecordRepository {
+	return &ArchivedRecordRepository{db: db}
+}
+
+const archivedRecordColumns = `id, tenant_id, entity_type, entity_id, storage_key, archived_at, restored_at, restored_by`
+
+// CreateBatch records archived entities.
+func (r *ArchivedRecordRepository) CreateBatch(ctx context.Context, records []*domain.ArchivedRecord) error {
+	if len(records) == 0 {
+		return nil
+	}
+	exec := getExecutor(ctx, r.db)
+
+	query := `
+		INSERT INTO sales.archived_records (` + archivedRecordColumns + `)
+		VALUES (:id, :tenant_id, :entity_type, :entity_id, :storage_key, :archived_at, :restored_at, :restored_by)`
+
+	if _, err := sqlx.NamedExecContext(ctx, exec, query, records); err != nil {
+		return fmt.Errorf("failed to create archived records: %w", err)
+	}
+
+	return nil
+}
+
+// GetByID retrieves an archived record.
+func (r *ArchivedRecordRepository) GetByID(ctx context.Context, tenantID, recordID uuid.UUID) (*domain.ArchivedRecord, error) {
+	exec := getExecutor(ctx, r.db)
+
+	query := `SELECT ` + archivedRecordColumns + `
+		FROM sales.archived_records
+		WHERE tenant_id = $1 AND id = $2`
+
+	var record domain.ArchivedRecord
+	if err := sqlx.GetContext(ctx, exec, &record, query, tenantID, recordID); err != nil {
+		if IsNotFoundError(err) {
+			return nil, domain.ErrArchivedRecordNotFound
+		}
+		return nil, fmt.Errorf("failed to get archived record: %w", err)
+	}
+
+	return &record, nil
+}
+
+// List lists a tenant's archived records, newest first.
+func (r *ArchivedRecordRepository) List(ctx context.Context, tenantID uuid.UUID, filter domain.ArchivedRecordFilter, opts domain.ListOptions) ([]*domain.ArchivedRecord, int64, error) {
+	exec := getExecutor(ctx, r.db)
+
+	baseQuery := `SELECT ` + archivedRecordColumns + `
+		FROM sales.archived_records
+		WHERE tenant_id = $1`
+
+	qb := NewQueryBuilder(baseQuery)
+	qb.args = append(qb.args, tenantID)
+
+	if filter.EntityType != nil {
+		qb.Where(fmt.Sprintf("entity_type = $%d", qb.NextParam()), string(*filter.EntityType))
+	}
+	if filter.EntityID != nil {
+		qb.Where(fmt.Sprintf("entity_id = $%d", qb.NextParam()), *filter.EntityID)
+	}
+	if !filter.IncludeRestored {
+		qb.Where("restored_at IS NULL")
+	}
+
+	countQuery, countArgs := qb.BuildCount()
+	var total int64
+	if err := sqlx.GetContext(ctx, exec, &total, countQuery, countArgs...); err != nil {
+		return nil, 0, fmt.Errorf("failed to count archived records: %w", err)
+	}
+
+	qb.OrderBy("archived_at", "DESC")
+	qb.Limit(opts.Limit())
+	qb.Offset(opts.Offset())
+
+	query, args := qb.Build()
+
+	var records []*domain.ArchivedRecord
+	if err := sqlx.SelectContext(ctx, exec, &records, query, args...); err != nil {
+		return nil, 0, fmt.Errorf("failed to list archived records: %w", err)
+	}
+
+	return records, total, nil
+}
+
+// MarkRestored records that an archived entity was restored.
+func (r *ArchivedRecordRepository) MarkRestored(ctx context.Context, record *domain.ArchivedRecord) error {
+	exec := getExecutor(ctx, r.db)
+
+	query := `
+		UPDATE sales.archived_records SET restored_at = $3, restored_by = $4
+		WHERE tenant_id = $1 AND id = $2 AND restored_at IS NULL`
+
+	result, err := exec.ExecContext(ctx, query, record.TenantID, record.ID, record.RestoredAt, record.RestoredBy)
+	if err != nil {
+		return fmt.Errorf("failed to mark archived record restored: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	// Another request restored the record first
+	if rowsAffected == 0 {
+		return domain.ErrArchivedRecordRestored
+	}
+
+	return nil
+}
+
+// Ensure ArchivedRecordRepository implements domain.ArchivedRecordRepository
+var _ domain.ArchivedRecordRepository = (*ArchivedRecordRepository)(nil)
+
+// ============================================================================
+// Retention Store
+// ============================================================================
+
+// retentionTable describes the table an entity type is stored in and which of
+// its rows a retention policy applies to.
+type retentionTable struct {
+	table      string
+	expiryExpr string
+	condition  string
+}
+
+// retentionTables maps retention entity types to their tables. Leads that an
+// opportunity was created from are kept, as the opportunity references them.
+var retentionTables = map[domain.RetentionEntityType]retentionTable{
+	domain.RetentionEntityLead: {
+		table:      "sales.leads",
+		expiryExpr: "t.updated_at",
+		condition: `t.status IN ('converted', 'unqualified')
+			AND NOT EXISTS (SELECT 1 FROM sales.opportunities o WHERE o.lead_id = t.id)`,
+	},
+}
+
+// RetentionStore implements domain.RetentionStore for PostgreSQL.
+type RetentionStore struct {
+	db *sqlx.DB
+}
+
+// NewRetentionStore creates a new RetentionStore.
+func NewRetentionStore(db *sqlx.DB) *RetentionStore {
+	return &RetentionStore{db: db}
+}
+
+// retainedRecordRow is a row serialized with row_to_json.
+type retainedRecordRow struct {
+	ID   uuid.UUID `db:"id"`
+	Data []byte    `db:"data"`
+}
+
+// FindExpired finds up to limit records last changed before cutoff, oldest first.
+func (s *RetentionStore) FindExpired(ctx context.Context, tenantID uuid.UUID, entityType domain.RetentionEntityType, cutoff time.Time, limit int) ([]domain.RetainedRecord, error) {
+	table, ok := retentionTables[entityType]
+	if !ok {
+		return nil, domain.ErrInvalidRetentionEntity
+	}
+	exec := getExecutor(ctx, s.db)
+
+	query := `
+		SELECT t.id, row_to_json(t)::text AS data
+		FROM ` + table.table + ` t
+		WHERE t.tenant_id = $1 AND ` + table.expiryExpr + ` < $2
+			AND ` + table.condition + `
+		ORDER BY ` + table.expiryExpr + `
+		LIMIT $3`
+
+	var rows []retainedRecordRow
+	if err := sqlx.SelectContext(ctx, exec, &rows, query, tenantID, cutoff, limit); err != nil {
+		return nil, fmt.Errorf("failed to find expired %s records: %w", entityType, err)
+	}
+
+	records := make([]domain.RetainedRecord, len(rows))
+	for i, row := range rows {
+		records[i] = domain.RetainedRecord{ID: row.ID, Data: row.Data}
+	}
+	return records, nil
+}
+
+// Delete permanently deletes records.
+func (s *RetentionStore) Delete(ctx context.Context, tenantID uuid.UUID, entityType domain.RetentionEntityType, ids []uuid.UUID) (int64, error) {
+	table, ok := retentionTables[entityType]
+	if !ok {
+		return 0, domain.ErrInvalidRetentionEntity
+	}
+	if len(ids) == 0 {
+		return 0, nil
+	}
+	exec := getExecutor(ctx, s.db)
+
+	query := `DELETE FROM ` + table.table + ` WHERE tenant_id = $1 AND id = ANY($2)`
+
+	result, err := exec.ExecContext(ctx, query, tenantID, pq.Array(ids))
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete %s records: %w", entityType, err)
+	}
+	return result.RowsAffected()
+}
+
+// Restore inserts an archived row back. Columns added since the row was
+// archived get their defaults; columns since dropped are ignored.
+func (s *RetentionStore) Restore(ctx context.Context, tenantID uuid.UUID, entityType domain.RetentionEntityType, data []byte) error {
+	table, ok := retentionTables[entityType]
+	if !ok {
+		return domain.ErrInvalidRetentionEntity
+	}
+	exec := getExecutor(ctx, s.db)
+
+	query := `
+		INSERT INTO ` + table.table + `
+		SELECT * FROM json_populate_record(NULL::` + table.table + `, $2::json) r
+		WHERE r.tenant_id = $1`
+
+	result, err := exec.ExecContext(ctx, query, tenantID, string(data))
+	if err != nil {
+		if IsUniqueViolation(err) {
+			return domain.ErrArchivedRecordRestored
+		}
+		return fmt.Errorf("failed to restore %s record: %w", entityType, err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	// The archived row belongs to another tenant
+	if rowsAffected == 0 {
+		return domain.ErrArchivedRecordNotFound
+	}
+
+	return nil
+}
+
+// Ensure RetentionStore implements domain.RetentionStore
+var _ domain.RetentionStore = (*RetentionStore)(nil)
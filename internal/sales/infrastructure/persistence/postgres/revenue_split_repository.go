@@ -0,0 +1,187 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+
+	"github.com/kilang-desa-murni/crm/internal/sales/domain"
+)
+
+// RevenueSplitRepository implements domain.RevenueSplitRepository for PostgreSQL.
+type RevenueSplitRepository struct {
+	db *sqlx.DB
+}
+
+// NewRevenueSplitRepository creates a new RevenueSplitRepository.
+func NewRevenueSplitRepository(db *sqlx.DB) *RevenueSplitRepository {
+	return &RevenueSplitRepository{db: db}
+}
+
+// revenueSplitRow is the database representation of a revenue split.
+type revenueSplitRow struct {
+	OpportunityID uuid.UUID `db:"opportunity_id"`
+	UserID        uuid.UUID `db:"user_id"`
+	UserName      string    `db:"user_name"`
+	Percent       int       `db:"percent"`
+}
+
+// revenueSplitChangeRow is the database representation of a revenue split
+// change.
+type revenueSplitChangeRow struct {
+	ID             uuid.UUID    `db:"id"`
+	TenantID       uuid.UUID    `db:"tenant_id"`
+	OpportunityID  uuid.UUID    `db:"opportunity_id"`
+	Splits         NullableJSON `db:"splits"`
+	PreviousSplits NullableJSON `db:"previous_splits"`
+	Reason         string       `db:"reason"`
+	ChangedBy      uuid.UUID    `db:"changed_by"`
+	ChangedAt      time.Time    `db:"changed_at"`
+}
+
+// GetByOpportunity retrieves the splits of an opportunity in the order they
+// were set.
+func (r *RevenueSplitRepository) GetByOpportunity(ctx context.Context, tenantID, opportunityID uuid.UUID) (domain.RevenueSplits, error) {
+	splits, err := r.GetByOpportunities(ctx, tenantID, []uuid.UUID{opportunityID})
+	if err != nil {
+		return nil, err
+	}
+	if s, ok := splits[opportunityID]; ok {
+		return s, nil
+	}
+	return domain.RevenueSplits{}, nil
+}
+
+// GetByOpportunities retrieves the splits of the opportunities that have any.
+func (r *RevenueSplitRepository) GetByOpportunities(ctx context.Context, tenantID uuid.UUID, opportunityIDs []uuid.UUID) (map[uuid.UUID]domain.RevenueSplits, error) {
+	splits := make(map[uuid.UUID]domain.RevenueSplits)
+	if len(opportunityIDs) == 0 {
+		return splits, nil
+	}
+
+	exec := getExecutor(ctx, r.db)
+
+	query := `
+		SELECT opportunity_id, user_id, user_name, percent
+		FROM sales.opportunity_revenue_splits
+		WHERE tenant_id = $1 AND opportunity_id = ANY($2)
+		ORDER BY opportunity_id, position`
+
+	var rows []revenueSplitRow
+	if err := sqlx.SelectContext(ctx, exec, &rows, query, tenantID, pq.Array(opportunityIDs)); err != nil {
+		return nil, fmt.Errorf("failed to get revenue splits: %w", err)
+	}
+
+	for _, row := range rows {
+		splits[row.OpportunityID] = append(splits[row.OpportunityID], domain.RevenueSplit{
+			UserID:   row.UserID,
+			UserName: row.UserName,
+			Percent:  row.Percent,
+		})
+	}
+	return splits, nil
+}
+
+// Apply replaces the splits of an opportunity and records the change in one
+// transaction.
+func (r *RevenueSplitRepository) Apply(ctx context.Context, change *domain.RevenueSplitChange) error {
+	splitsJSON, err := ToJSON(change.Splits)
+	if err != nil {
+		return fmt.Errorf("failed to marshal revenue splits: %w", err)
+	}
+	previousJSON, err := ToJSON(change.PreviousSplits)
+	if err != nil {
+		return fmt.Errorf("failed to marshal previous revenue splits: %w", err)
+	}
+
+	return NewTransactionManager(r.db).WithTransaction(ctx, func(txCtx context.Context) error {
+		exec := getExecutor(txCtx, r.db)
+
+		if _, err := exec.ExecContext(txCtx,
+			`DELETE FROM sales.opportunity_revenue_splits WHERE tenant_id = $1 AND opportunity_id = $2`,
+			change.TenantID, change.OpportunityID); err != nil {
+			return fmt.Errorf("failed to clear revenue splits: %w", err)
+		}
+
+		for i, split := range change.Splits {
+			_, err := exec.ExecContext(txCtx, `
+				INSERT INTO sales.opportunity_revenue_splits (
+					opportunity_id, user_id, tenant_id, user_name, percent, position, updated_at
+				) VALUES ($1, $2, $3, $4, $5, $6, $7)`,
+				change.OpportunityID, split.UserID, change.TenantID, split.UserName, split.Percent, i, change.ChangedAt,
+			)
+			if err != nil {
+				return fmt.Errorf("failed to insert revenue split: %w", err)
+			}
+		}
+
+		_, err := exec.ExecContext(txCtx, `
+			INSERT INTO sales.opportunity_revenue_split_changes (
+				id, tenant_id, opportunity_id, splits, previous_splits, reason, changed_by, changed_at
+			) VALUES ($1, $2, $3, $4, $5, $6, $7, $8)`,
+			change.ID, change.TenantID, change.OpportunityID, splitsJSON, previousJSON,
+			change.Reason, change.ChangedBy, change.ChangedAt,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to record revenue split change: %w", err)
+		}
+		return nil
+	})
+}
+
+// ListChanges lists the changes of an opportunity's splits, newest first.
+func (r *RevenueSplitRepository) ListChanges(ctx context.Context, tenantID, opportunityID uuid.UUID, opts domain.ListOptions) ([]*domain.RevenueSplitChange, int64, error) {
+	exec := getExecutor(ctx, r.db)
+
+	where := `WHERE tenant_id = $1 AND opportunity_id = $2`
+
+	var total int64
+	if err := sqlx.GetContext(ctx, exec, &total, `SELECT COUNT(*) FROM sales.opportunity_revenue_split_changes `+where, tenantID, opportunityID); err != nil {
+		return nil, 0, fmt.Errorf("failed to count revenue split changes: %w", err)
+	}
+
+	query := `
+		SELECT id, tenant_id, opportunity_id, splits, previous_splits, reason, changed_by, changed_at
+		FROM sales.opportunity_revenue_split_changes ` + where + `
+		ORDER BY changed_at DESC, id DESC
+		LIMIT $3 OFFSET $4`
+
+	var rows []revenueSplitChangeRow
+	if err := sqlx.SelectContext(ctx, exec, &rows, query, tenantID, opportunityID, opts.Limit(), opts.Offset()); err != nil {
+		return nil, 0, fmt.Errorf("failed to list revenue split changes: %w", err)
+	}
+
+	changes := make([]*domain.RevenueSplitChange, len(rows))
+	for i := range rows {
+		change, err := rows[i].toDomain()
+		if err != nil {
+			return nil, 0, err
+		}
+		changes[i] = change
+	}
+	return changes, total, nil
+}
+
+func (row *revenueSplitChangeRow) toDomain() (*domain.RevenueSplitChange, error) {
+	change := &domain.RevenueSplitChange{
+		ID:             row.ID,
+		TenantID:       row.TenantID,
+		OpportunityID:  row.OpportunityID,
+		Splits:         domain.RevenueSplits{},
+		PreviousSplits: domain.RevenueSplits{},
+		Reason:         row.Reason,
+		ChangedBy:      row.ChangedBy,
+		ChangedAt:      row.ChangedAt,
+	}
+	if err := row.Splits.MarshalTo(&change.Splits); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal revenue splits: %w", err)
+	}
+	if err := row.PreviousSplits.MarshalTo(&change.PreviousSplits); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal previous revenue splits: %w", err)
+	}
+	return change, nil
+}
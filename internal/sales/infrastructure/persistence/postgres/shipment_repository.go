@@ -0,0 +1,239 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+
+	"github.com/kilang-desa-murni/crm/internal/sales/domain"
+)
+
+// ShipmentRepository implements domain.ShipmentRepository for PostgreSQL.
+type ShipmentRepository struct {
+	db *sqlx.DB
+}
+
+// NewShipmentRepository creates a new ShipmentRepository.
+func NewShipmentRepository(db *sqlx.DB) *ShipmentRepository {
+	return &ShipmentRepository{db: db}
+}
+
+// shipmentRow is the database representation of a shipment.
+type shipmentRow struct {
+	ID                uuid.UUID    `db:"id"`
+	TenantID          uuid.UUID    `db:"tenant_id"`
+	DealID            uuid.UUID    `db:"deal_id"`
+	DealCode          string       `db:"deal_code"`
+	CustomerID        uuid.UUID    `db:"customer_id"`
+	CustomerName      string       `db:"customer_name"`
+	Carrier           string       `db:"carrier"`
+	TrackingNumber    string       `db:"tracking_number"`
+	TrackingURL       string       `db:"tracking_url"`
+	Status            string       `db:"status"`
+	RecipientName     string       `db:"recipient_name"`
+	RecipientEmail    string       `db:"recipient_email"`
+	EstimatedDelivery *time.Time   `db:"estimated_delivery"`
+	TrackingEvents    NullableJSON `db:"tracking_events"`
+	ShippedAt         *time.Time   `db:"shipped_at"`
+	DeliveredAt       *time.Time   `db:"delivered_at"`
+	LastCheckedAt     *time.Time   `db:"last_checked_at"`
+	CreatedBy         uuid.UUID    `db:"created_by"`
+	CreatedAt         time.Time    `db:"created_at"`
+	UpdatedAt         time.Time    `db:"updated_at"`
+	Version           int          `db:"version"`
+}
+
+const shipmentColumns = `
+	id, tenant_id, deal_id, deal_code, customer_id, customer_name, carrier,
+	tracking_number, tracking_url, status, recipient_name, recipient_email,
+	estimated_delivery, tracking_events, shipped_at, delivered_at, last_checked_at,
+	created_by, created_at, updated_at, version`
+
+// Create creates a new shipment.
+func (r *ShipmentRepository) Create(ctx context.Context, shipment *domain.Shipment) error {
+	exec := getExecutor(ctx, r.db)
+
+	eventsJSON, err := ToJSON(shipment.TrackingEvents)
+	if err != nil {
+		return fmt.Errorf("failed to marshal shipment tracking events: %w", err)
+	}
+
+	query := `
+		INSERT INTO sales.shipments (` + shipmentColumns + `)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, $21)`
+
+	_, err = exec.ExecContext(ctx, query,
+		shipment.ID, shipment.TenantID, shipment.DealID, shipment.DealCode, shipment.CustomerID,
+		shipment.CustomerName, shipment.Carrier, shipment.TrackingNumber, shipment.TrackingURL,
+		string(shipment.Status), shipment.RecipientName, shipment.RecipientEmail,
+		shipment.EstimatedDelivery, eventsJSON, shipment.ShippedAt, shipment.DeliveredAt,
+		shipment.LastCheckedAt, shipment.CreatedBy, shipment.CreatedAt, shipment.UpdatedAt,
+		shipment.Version,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create shipment: %w", err)
+	}
+
+	return nil
+}
+
+// GetByID retrieves a shipment by ID.
+func (r *ShipmentRepository) GetByID(ctx context.Context, tenantID, shipmentID uuid.UUID) (*domain.Shipment, error) {
+	exec := getExecutor(ctx, r.db)
+
+	query := `SELECT ` + shipmentColumns + `
+		FROM sales.shipments
+		WHERE tenant_id = $1 AND id = $2`
+
+	var row shipmentRow
+	if err := sqlx.GetContext(ctx, exec, &row, query, tenantID, shipmentID); err != nil {
+		if IsNotFoundError(err) {
+			return nil, domain.ErrShipmentNotFound
+		}
+		return nil, fmt.Errorf("failed to get shipment: %w", err)
+	}
+
+	return row.toDomain()
+}
+
+// Update updates a shipment, checking the version it was loaded at.
+func (r *ShipmentRepository) Update(ctx context.Context, shipment *domain.Shipment) error {
+	exec := getExecutor(ctx, r.db)
+
+	eventsJSON, err := ToJSON(shipment.TrackingEvents)
+	if err != nil {
+		return fmt.Errorf("failed to marshal shipment tracking events: %w", err)
+	}
+
+	query := `
+		UPDATE sales.shipments SET
+			carrier = $3, tracking_number = $4, tracking_url = $5, status = $6,
+			recipient_name = $7, recipient_email = $8, estimated_delivery = $9,
+			tracking_events = $10, shipped_at = $11, delivered_at = $12,
+			last_checked_at = $13, updated_at = $14, version = $15
+		WHERE tenant_id = $1 AND id = $2 AND version = $15 - 1`
+
+	result, err := exec.ExecContext(ctx, query,
+		shipment.TenantID, shipment.ID, shipment.Carrier, shipment.TrackingNumber,
+		shipment.TrackingURL, string(shipment.Status), shipment.RecipientName,
+		shipment.RecipientEmail, shipment.EstimatedDelivery, eventsJSON, shipment.ShippedAt,
+		shipment.DeliveredAt, shipment.LastCheckedAt, shipment.UpdatedAt, shipment.Version,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update shipment: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return domain.ErrShipmentNotFound
+	}
+
+	return nil
+}
+
+// ListByDeal lists a deal's shipments, newest first.
+func (r *ShipmentRepository) ListByDeal(ctx context.Context, tenantID, dealID uuid.UUID) ([]*domain.Shipment, error) {
+	exec := getExecutor(ctx, r.db)
+
+	query := `SELECT ` + shipmentColumns + `
+		FROM sales.shipments
+		WHERE tenant_id = $1 AND deal_id = $2
+		ORDER BY created_at DESC, id DESC`
+
+	var rows []shipmentRow
+	if err := sqlx.SelectContext(ctx, exec, &rows, query, tenantID, dealID); err != nil {
+		return nil, fmt.Errorf("failed to list deal shipments: %w", err)
+	}
+
+	return shipmentRowsToDomain(rows)
+}
+
+// GetByTracking retrieves the shipments of all tenants with a carrier's tracking number.
+func (r *ShipmentRepository) GetByTracking(ctx context.Context, carrier, trackingNumber string) ([]*domain.Shipment, error) {
+	exec := getExecutor(ctx, r.db)
+
+	query := `SELECT ` + shipmentColumns + `
+		FROM sales.shipments
+		WHERE carrier = $1 AND tracking_number = $2`
+
+	var rows []shipmentRow
+	if err := sqlx.SelectContext(ctx, exec, &rows, query, carrier, trackingNumber); err != nil {
+		return nil, fmt.Errorf("failed to get shipments by tracking number: %w", err)
+	}
+
+	return shipmentRowsToDomain(rows)
+}
+
+// GetDueForTracking retrieves shipments still on their way that were last
+// checked before the time, least recently checked first.
+func (r *ShipmentRepository) GetDueForTracking(ctx context.Context, carriers []string, checkedBefore time.Time, limit int) ([]*domain.Shipment, error) {
+	exec := getExecutor(ctx, r.db)
+
+	query := `SELECT ` + shipmentColumns + `
+		FROM sales.shipments
+		WHERE carrier = ANY($1)
+		  AND status NOT IN ('delivered', 'returned')
+		  AND (last_checked_at IS NULL OR last_checked_at < $2)
+		ORDER BY last_checked_at NULLS FIRST, id
+		LIMIT $3`
+
+	var rows []shipmentRow
+	if err := sqlx.SelectContext(ctx, exec, &rows, query, pq.Array(carriers), checkedBefore, limit); err != nil {
+		return nil, fmt.Errorf("failed to get shipments due for tracking: %w", err)
+	}
+
+	return shipmentRowsToDomain(rows)
+}
+
+func shipmentRowsToDomain(rows []shipmentRow) ([]*domain.Shipment, error) {
+	shipments := make([]*domain.Shipment, len(rows))
+	for i := range rows {
+		shipment, err := rows[i].toDomain()
+		if err != nil {
+			return nil, err
+		}
+		shipments[i] = shipment
+	}
+	return shipments, nil
+}
+
+func (row *shipmentRow) toDomain() (*domain.Shipment, error) {
+	shipment := &domain.Shipment{
+		ID:                row.ID,
+		TenantID:          row.TenantID,
+		DealID:            row.DealID,
+		DealCode:          row.DealCode,
+		CustomerID:        row.CustomerID,
+		CustomerName:      row.CustomerName,
+		Carrier:           row.Carrier,
+		TrackingNumber:    row.TrackingNumber,
+		TrackingURL:       row.TrackingURL,
+		Status:            domain.ShipmentStatus(row.Status),
+		RecipientName:     row.RecipientName,
+		RecipientEmail:    row.RecipientEmail,
+		EstimatedDelivery: row.EstimatedDelivery,
+		TrackingEvents:    []domain.ShipmentTrackingEvent{},
+		ShippedAt:         row.ShippedAt,
+		DeliveredAt:       row.DeliveredAt,
+		LastCheckedAt:     row.LastCheckedAt,
+		CreatedBy:         row.CreatedBy,
+		CreatedAt:         row.CreatedAt,
+		UpdatedAt:         row.UpdatedAt,
+		Version:           row.Version,
+	}
+	if err := row.TrackingEvents.MarshalTo(&shipment.TrackingEvents); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal shipment tracking events: %w", err)
+	}
+	return shipment, nil
+}
+
+// Ensure ShipmentRepository implements domain.ShipmentRepository
+var _ domain.ShipmentRepository = (*ShipmentRepository)(nil)
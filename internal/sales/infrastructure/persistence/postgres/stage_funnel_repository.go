@@ -0,0 +1,167 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+
+	"github.com/kilang-desa-murni/crm/internal/sales/domain"
+)
+
+// StageFunnelRepository implements domain.StageFunnelRepository for PostgreSQL.
+type StageFunnelRepository struct {
+	db *sqlx.DB
+}
+
+// NewStageFunnelRepository creates a new StageFunnelRepository.
+func NewStageFunnelRepository(db *sqlx.DB) *StageFunnelRepository {
+	return &StageFunnelRepository{db: db}
+}
+
+const insertStageFunnelEntryQuery = `
+	INSERT INTO sales.opportunity_stage_funnel (
+		opportunity_id, visit, tenant_id, pipeline_id, stage_id, stage_type, stage_order,
+		entered_at, exited_at, duration_seconds, owner_id, source, product_ids,
+		opportunity_created_at, updated_at
+	) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15)
+	ON CONFLICT (opportunity_id, visit) DO UPDATE SET
+		tenant_id = EXCLUDED.tenant_id,
+		pipeline_id = EXCLUDED.pipeline_id,
+		stage_id = EXCLUDED.stage_id,
+		stage_type = EXCLUDED.stage_type,
+		stage_order = EXCLUDED.stage_order,
+		entered_at = EXCLUDED.entered_at,
+		exited_at = EXCLUDED.exited_at,
+		duration_seconds = EXCLUDED.duration_seconds,
+		owner_id = EXCLUDED.owner_id,
+		source = EXCLUDED.source,
+		product_ids = EXCLUDED.product_ids,
+		opportunity_created_at = EXCLUDED.opportunity_created_at,
+		updated_at = EXCLUDED.updated_at`
+
+// ReplaceOpportunityEntries replaces every funnel entry of an opportunity.
+func (r *StageFunnelRepository) ReplaceOpportunityEntries(ctx context.Context, tenantID, opportunityID uuid.UUID, entries []*domain.StageFunnelEntry) error {
+	return NewTransactionManager(r.db).WithTransaction(ctx, func(txCtx context.Context) error {
+		exec := getExecutor(txCtx, r.db)
+
+		if _, err := exec.ExecContext(txCtx,
+			`DELETE FROM sales.opportunity_stage_funnel WHERE tenant_id = $1 AND opportunity_id = $2`,
+			tenantID, opportunityID); err != nil {
+			return fmt.Errorf("failed to clear stage funnel entries: %w", err)
+		}
+
+		return r.insertEntries(txCtx, exec, entries)
+	})
+}
+
+// ReplacePipelineEntries replaces every funnel entry of a pipeline in one transaction.
+func (r *StageFunnelRepository) ReplacePipelineEntries(ctx context.Context, tenantID, pipelineID uuid.UUID, entries []*domain.StageFunnelEntry) error {
+	return NewTransactionManager(r.db).WithTransaction(ctx, func(txCtx context.Context) error {
+		exec := getExecutor(txCtx, r.db)
+
+		if _, err := exec.ExecContext(txCtx,
+			`DELETE FROM sales.opportunity_stage_funnel WHERE tenant_id = $1 AND pipeline_id = $2`,
+			tenantID, pipelineID); err != nil {
+			return fmt.Errorf("failed to clear stage funnel entries: %w", err)
+		}
+
+		// Entries still filed under another pipeline are moved here
+		return r.insertEntries(txCtx, exec, entries)
+	})
+}
+
+func (r *StageFunnelRepository) insertEntries(ctx context.Context, exec sqlx.ExtContext, entries []*domain.StageFunnelEntry) error {
+	now := time.Now().UTC()
+	for _, e := range entries {
+		_, err := exec.ExecContext(ctx, insertStageFunnelEntryQuery,
+			e.OpportunityID, e.Visit, e.TenantID, e.PipelineID, e.StageID, string(e.StageType), e.StageOrder,
+			e.EnteredAt, NewNullTime(e.ExitedAt).NullTime, e.DurationSeconds, e.OwnerID, e.Source, pq.Array(e.ProductIDs),
+			e.OpportunityCreatedAt, now,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to insert stage funnel entry: %w", err)
+		}
+	}
+	return nil
+}
+
+// GetStageDurations returns, per stage, how many of the cohort's
+// opportunities entered it and how long completed visits lasted.
+func (r *StageFunnelRepository) GetStageDurations(ctx context.Context, tenantID, pipelineID uuid.UUID, filter domain.StageFunnelFilter) ([]*domain.StageDurationStatistics, error) {
+	exec := getExecutor(ctx, r.db)
+
+	where, args := stageFunnelWhere(tenantID, pipelineID, filter)
+	query := `
+		SELECT stage_id,
+			COUNT(DISTINCT opportunity_id) AS opportunities,
+			COUNT(duration_seconds) AS completed_visits,
+			COALESCE(AVG(duration_seconds), 0)::float8 / 3600 AS average_hours,
+			COALESCE(percentile_cont(0.5) WITHIN GROUP (ORDER BY duration_seconds), 0)::float8 / 3600 AS median_hours
+		FROM sales.opportunity_stage_funnel
+		WHERE ` + where + `
+		GROUP BY stage_id`
+
+	var stats []*domain.StageDurationStatistics
+	if err := sqlx.SelectContext(ctx, exec, &stats, query, args...); err != nil {
+		return nil, fmt.Errorf("failed to get stage durations: %w", err)
+	}
+
+	return stats, nil
+}
+
+// GetFurthestStages counts the cohort's opportunities by the furthest stage
+// they entered, ignoring lost stages.
+func (r *StageFunnelRepository) GetFurthestStages(ctx context.Context, tenantID, pipelineID uuid.UUID, filter domain.StageFunnelFilter) ([]*domain.StageReachCount, error) {
+	exec := getExecutor(ctx, r.db)
+
+	where, args := stageFunnelWhere(tenantID, pipelineID, filter)
+	query := `
+		SELECT stage_id, COUNT(*) AS opportunities
+		FROM (
+			SELECT DISTINCT ON (opportunity_id) opportunity_id, stage_id
+			FROM sales.opportunity_stage_funnel
+			WHERE ` + where + ` AND stage_type <> 'lost'
+			ORDER BY opportunity_id, stage_order DESC, visit DESC
+		) furthest
+		GROUP BY stage_id`
+
+	var counts []*domain.StageReachCount
+	if err := sqlx.SelectContext(ctx, exec, &counts, query, args...); err != nil {
+		return nil, fmt.Errorf("failed to get furthest stages: %w", err)
+	}
+
+	return counts, nil
+}
+
+// stageFunnelWhere builds the conditions selecting a pipeline's cohort.
+func stageFunnelWhere(tenantID, pipelineID uuid.UUID, filter domain.StageFunnelFilter) (string, []interface{}) {
+	where := `tenant_id = $1 AND pipeline_id = $2`
+	args := []interface{}{tenantID, pipelineID}
+
+	if filter.From != nil {
+		args = append(args, *filter.From)
+		where += fmt.Sprintf(` AND opportunity_created_at >= $%d`, len(args))
+	}
+	if filter.To != nil {
+		args = append(args, *filter.To)
+		where += fmt.Sprintf(` AND opportunity_created_at < $%d`, len(args))
+	}
+	if filter.OwnerID != nil {
+		args = append(args, *filter.OwnerID)
+		where += fmt.Sprintf(` AND owner_id = $%d`, len(args))
+	}
+	if filter.Source != "" {
+		args = append(args, filter.Source)
+		where += fmt.Sprintf(` AND source = $%d`, len(args))
+	}
+	if filter.ProductID != nil {
+		args = append(args, *filter.ProductID)
+		where += fmt.Sprintf(` AND $%d = ANY(product_ids)`, len(args))
+	}
+
+	return where, args
+}
@@ -0,0 +1,103 @@
+// Package postgres contains PostgreSQL repository implementations for the sales service.
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+
+	"github.com/kilang-desa-murni/crm/internal/sales/domain"
+)
+
+// TaxSettingsRepository implements domain.TaxSettingsRepository for PostgreSQL.
+type TaxSettingsRepository struct {
+	db *sqlx.DB
+}
+
+// NewTaxSettingsRepository creates a new TaxSettingsRepository.
+func NewTaxSettingsRepository(db *sqlx.DB) *TaxSettingsRepository {
+	return &TaxSettingsRepository{db: db}
+}
+
+// taxSettingsRow is the database representation of tax settings.
+type taxSettingsRow struct {
+	TenantID           uuid.UUID      `db:"tenant_id"`
+	Enabled            bool           `db:"enabled"`
+	RegistrationNumber sql.NullString `db:"registration_number"`
+	PricesIncludeTax   bool           `db:"prices_include_tax"`
+	Rates              NullableJSON   `db:"rates"`
+	UpdatedAt          time.Time      `db:"updated_at"`
+}
+
+// Get retrieves a tenant's tax settings, returning nil if none are configured.
+func (r *TaxSettingsRepository) Get(ctx context.Context, tenantID uuid.UUID) (*domain.TaxSettings, error) {
+	exec := getExecutor(ctx, r.db)
+
+	query := `
+		SELECT tenant_id, enabled, registration_number, prices_include_tax, rates, updated_at
+		FROM sales.tax_settings
+		WHERE tenant_id = $1`
+
+	var row taxSettingsRow
+	if err := sqlx.GetContext(ctx, exec, &row, query, tenantID); err != nil {
+		if IsNotFoundError(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get tax settings: %w", err)
+	}
+
+	settings := &domain.TaxSettings{
+		TenantID:           row.TenantID,
+		Enabled:            row.Enabled,
+		RegistrationNumber: row.RegistrationNumber.String,
+		PricesIncludeTax:   row.PricesIncludeTax,
+		Rates:              []domain.TaxRate{},
+		UpdatedAt:          row.UpdatedAt,
+	}
+	if err := row.Rates.MarshalTo(&settings.Rates); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal tax rates: %w", err)
+	}
+
+	return settings, nil
+}
+
+// Upsert creates or updates a tenant's tax settings.
+func (r *TaxSettingsRepository) Upsert(ctx context.Context, settings *domain.TaxSettings) error {
+	exec := getExecutor(ctx, r.db)
+
+	rates := settings.Rates
+	if rates == nil {
+		rates = []domain.TaxRate{}
+	}
+	ratesJSON, err := ToJSON(rates)
+	if err != nil {
+		return fmt.Errorf("failed to marshal tax rates: %w", err)
+	}
+
+	query := `
+		INSERT INTO sales.tax_settings (tenant_id, enabled, registration_number, prices_include_tax, rates, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (tenant_id) DO UPDATE SET
+			enabled = EXCLUDED.enabled,
+			registration_number = EXCLUDED.registration_number,
+			prices_include_tax = EXCLUDED.prices_include_tax,
+			rates = EXCLUDED.rates,
+			updated_at = EXCLUDED.updated_at`
+
+	_, err = exec.ExecContext(ctx, query,
+		settings.TenantID, settings.Enabled, nullString(settings.RegistrationNumber),
+		settings.PricesIncludeTax, ratesJSON, settings.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to upsert tax settings: %w", err)
+	}
+
+	return nil
+}
+
+// Ensure TaxSettingsRepository implements domain.TaxSettingsRepository
+var _ domain.TaxSettingsRepository = (*TaxSettingsRepository)(nil)
@@ -0,0 +1,72 @@
+// Package postgres provides PostgreSQL implementations for sales domain repositories.
+package postgres
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+
+	"github.com/kilang-desa-murni/crm/internal/sales/domain"
+)
+
+// tenantExportTables maps each tenant export data set to its table.
+var tenantExportTables = map[string]string{
+	domain.TenantExportLeads:         "sales.leads",
+	domain.TenantExportOpportunities: "sales.opportunities",
+}
+
+// TenantExportRepository implements domain.TenantDataExporter.
+type TenantExportRepository struct {
+	db *sqlx.DB
+}
+
+// NewTenantExportRepository creates a new TenantExportRepository.
+func NewTenantExportRepository(db *sqlx.DB) *TenantExportRepository {
+	return &TenantExportRepository{db: db}
+}
+
+// Ensure TenantExportRepository implements domain.TenantDataExporter.
+var _ domain.TenantDataExporter = (*TenantExportRepository)(nil)
+
+// ExportTenantData streams the rows of the data set's table as JSON, so
+// every column is exported as stored without loading the tenant's records
+// into memory.
+func (r *TenantExportRepository) ExportTenantData(ctx context.Context, tenantID uuid.UUID, dataset string, w io.Writer) (int64, error) {
+	table, ok := tenantExportTables[dataset]
+	if !ok {
+		return 0, fmt.Errorf("unknown export data set %q", dataset)
+	}
+
+	query := fmt.Sprintf(`
+		SELECT row_to_json(t)::text FROM %s t
+		WHERE t.tenant_id = $1 AND t.deleted_at IS NULL
+		ORDER BY t.created_at, t.id`, table)
+
+	rows, err := r.db.QueryContext(ctx, query, tenantID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to query %s: %w", dataset, err)
+	}
+	defer rows.Close()
+
+	out := bufio.NewWriter(w)
+	var count int64
+	for rows.Next() {
+		var record string
+		if err := rows.Scan(&record); err != nil {
+			return count, fmt.Errorf("failed to scan %s: %w", dataset, err)
+		}
+		if _, err := out.WriteString(record + "\n"); err != nil {
+			return count, err
+		}
+		count++
+	}
+	if err := rows.Err(); err != nil {
+		return count, fmt.Errorf("failed to read %s: %w", dataset, err)
+	}
+
+	return count, out.Flush()
+}
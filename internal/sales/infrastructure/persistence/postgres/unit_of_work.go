@@ -275,6 +275,8 @@ func NewEventStore(db *sqlx.DB) *EventStore {
 
 // eventRow represents the database row structure for domain events.
 type eventRow struct {
+	Position      int64     `db:"position"`
+	Sequence      int64     `db:"sequence"`
 	ID            string    `db:"id"`
 	TenantID      string    `db:"tenant_id"`
 	AggregateID   string    `db:"aggregate_id"`
@@ -287,7 +289,9 @@ type eventRow struct {
 	CreatedAt     time.Time `db:"created_at"`
 }
 
-// Save persists domain events to the database.
+// Save appends domain events to the store. Each event gets the next sequence
+// number of its aggregate; bumping the aggregate's stream row in the same
+// statement serialises concurrent appends, so sequences have no gaps.
 func (es *EventStore) Save(ctx context.Context, events ...domain.DomainEvent) error {
 	if len(events) == 0 {
 		return nil
@@ -296,12 +300,20 @@ func (es *EventStore) Save(ctx context.Context, events ...domain.DomainEvent) er
 	executor := getExecutor(ctx, es.db)
 
 	query := `
+		WITH stream AS (
+			INSERT INTO sales_event_streams (aggregate_id, tenant_id, aggregate_type, last_sequence, updated_at)
+			VALUES ($3, $2, $4, 1, $10)
+			ON CONFLICT (aggregate_id) DO UPDATE SET
+				last_sequence = sales_event_streams.last_sequence + 1,
+				updated_at = EXCLUDED.updated_at
+			RETURNING last_sequence
+		)
 		INSERT INTO sales_domain_events (
 			id, tenant_id, aggregate_id, aggregate_type, event_type,
-			event_data, metadata, version, occurred_at, created_at
-		) VALUES (
-			$1, $2, $3, $4, $5, $6, $7, $8, $9, $10
-		)`
+			event_data, metadata, version, sequence, occurred_at, created_at
+		)
+		SELECT $1, $2, $3, $4, $5, $6, $7, $8, stream.last_sequence, $9, $10
+		FROM stream`
 
 	for _, event := range events {
 		// Serialize the entire event as event data
@@ -310,8 +322,12 @@ func (es *EventStore) Save(ctx context.Context, events ...domain.DomainEvent) er
 			return fmt.Errorf("failed to marshal event data: %w", err)
 		}
 
-		// Empty metadata - could be extended if needed
 		metadata := []byte("{}")
+		if withMetadata, ok := event.(interface{ Metadata() map[string]string }); ok && len(withMetadata.Metadata()) > 0 {
+			if metadata, err = json.Marshal(withMetadata.Metadata()); err != nil {
+				return fmt.Errorf("failed to marshal event metadata: %w", err)
+			}
+		}
 
 		_, err = executor.ExecContext(ctx, query,
 			event.EventID().String(),
@@ -436,6 +452,77 @@ func (es *EventStore) GetEventsInRange(ctx context.Context, tenantID uuid.UUID,
 	return es.toDomainEvents(rows)
 }
 
+// ReadEvents reads recorded events in store order, oldest first.
+func (es *EventStore) ReadEvents(ctx context.Context, filter domain.EventRecordFilter) ([]*domain.EventRecord, error) {
+	executor := getExecutor(ctx, es.db)
+
+	qb := NewQueryBuilder(`
+		SELECT position, sequence, id, tenant_id, aggregate_id, aggregate_type, event_type,
+			event_data, metadata, version, occurred_at, created_at
+		FROM sales_domain_events
+		WHERE position > $1`)
+	qb.args = append(qb.args, filter.AfterPosition)
+
+	if filter.TenantID != nil {
+		qb.Where(fmt.Sprintf("tenant_id = $%d", qb.NextParam()), *filter.TenantID)
+	}
+	if filter.AggregateID != nil {
+		qb.Where(fmt.Sprintf("aggregate_id = $%d", qb.NextParam()), *filter.AggregateID)
+	}
+	if filter.AggregateType != nil {
+		qb.Where(fmt.Sprintf("aggregate_type = $%d", qb.NextParam()), *filter.AggregateType)
+	}
+	if len(filter.EventTypes) > 0 {
+		qb.WhereInStrings("event_type", filter.EventTypes)
+	}
+	if filter.OccurredFrom != nil {
+		qb.Where(fmt.Sprintf("occurred_at >= $%d", qb.NextParam()), *filter.OccurredFrom)
+	}
+	if filter.OccurredTo != nil {
+		qb.Where(fmt.Sprintf("occurred_at < $%d", qb.NextParam()), *filter.OccurredTo)
+	}
+
+	limit := filter.Limit
+	if limit <= 0 {
+		limit = 100
+	}
+	qb.OrderBy("position", "asc")
+	qb.Limit(limit)
+
+	query, args := qb.Build()
+
+	var rows []eventRow
+	if err := sqlx.SelectContext(ctx, executor, &rows, query, args...); err != nil {
+		return nil, fmt.Errorf("failed to read events: %w", err)
+	}
+
+	records := make([]*domain.EventRecord, len(rows))
+	for i, row := range rows {
+		record := &domain.EventRecord{
+			Position:      row.Position,
+			Sequence:      row.Sequence,
+			AggregateType: row.AggregateType,
+			EventType:     row.EventType,
+			Data:          json.RawMessage(row.EventData),
+			Version:       row.Version,
+			OccurredAt:    row.OccurredAt,
+			RecordedAt:    row.CreatedAt,
+		}
+		record.ID, _ = uuid.Parse(row.ID)
+		record.TenantID, _ = uuid.Parse(row.TenantID)
+		record.AggregateID, _ = uuid.Parse(row.AggregateID)
+
+		if len(row.Metadata) > 0 && string(row.Metadata) != "null" {
+			if err := json.Unmarshal(row.Metadata, &record.Metadata); err != nil {
+				return nil, fmt.Errorf("failed to unmarshal event metadata: %w", err)
+			}
+		}
+		records[i] = record
+	}
+
+	return records, nil
+}
+
 // toDomainEvents converts database rows to domain events.
 func (es *EventStore) toDomainEvents(rows []eventRow) ([]domain.DomainEvent, error) {
 	events := make([]domain.DomainEvent, len(rows))
@@ -0,0 +1,60 @@
+// Package projection keeps the sales read models up to date from domain events.
+package projection
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+
+	"github.com/kilang-desa-murni/crm/internal/sales/application/usecase"
+	"github.com/kilang-desa-murni/crm/internal/sales/infrastructure/messaging"
+	"github.com/kilang-desa-murni/crm/pkg/logger"
+)
+
+// ============================================================================
+// Opportunity Board Projector
+// ============================================================================
+
+// BoardProjector updates the opportunity board read model from opportunity
+// events. Each event re-projects the whole card, so the card converges on the
+// opportunity even when events are missed or arrive out of order.
+type BoardProjector struct {
+	boardUseCase usecase.BoardUseCase
+	log          *logger.Logger
+}
+
+// NewBoardProjector creates a new board projector.
+func NewBoardProjector(boardUseCase usecase.BoardUseCase, log *logger.Logger) *BoardProjector {
+	return &BoardProjector{boardUseCase: boardUseCase, log: log}
+}
+
+// Bindings returns the queue bindings for the events the projector handles.
+func (p *BoardProjector) Bindings() []messaging.ConsumerBinding {
+	return []messaging.ConsumerBinding{
+		{Exchange: messaging.SalesEventsExchange, RoutingKey: "sales.opportunity.*"},
+	}
+}
+
+// Handle re-projects the card of the opportunity an event belongs to. Events
+// without a tenant or opportunity ID are ignored.
+func (p *BoardProjector) Handle(ctx context.Context, event messaging.ConsumedEvent) error {
+	tenantID, err := uuid.Parse(event.TenantID)
+	if err != nil {
+		return nil
+	}
+	opportunityID, err := uuid.Parse(event.AggregateID)
+	if err != nil {
+		return nil
+	}
+
+	if err := p.boardUseCase.ProjectOpportunity(ctx, tenantID, opportunityID); err != nil {
+		return fmt.Errorf("failed to project opportunity %s: %w", opportunityID, err)
+	}
+
+	p.log.Debug().
+		Str("event_type", event.Type).
+		Str("opportunity_id", opportunityID.String()).
+		Msg("Board card projected")
+	return nil
+}
@@ -0,0 +1,60 @@
+package projection
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+
+	"github.com/kilang-desa-murni/crm/internal/sales/application/usecase"
+	"github.com/kilang-desa-murni/crm/internal/sales/infrastructure/messaging"
+	"github.com/kilang-desa-murni/crm/pkg/logger"
+)
+
+// ============================================================================
+// Stage Funnel Projector
+// ============================================================================
+
+// FunnelProjector updates the stage funnel read model from opportunity
+// events. Besides stage moves, edits to the owner, source or products change
+// the cohorts an opportunity falls in, so every opportunity event re-projects
+// the opportunity's entries.
+type FunnelProjector struct {
+	funnelUseCase usecase.FunnelUseCase
+	log           *logger.Logger
+}
+
+// NewFunnelProjector creates a new funnel projector.
+func NewFunnelProjector(funnelUseCase usecase.FunnelUseCase, log *logger.Logger) *FunnelProjector {
+	return &FunnelProjector{funnelUseCase: funnelUseCase, log: log}
+}
+
+// Bindings returns the queue bindings for the events the projector handles.
+func (p *FunnelProjector) Bindings() []messaging.ConsumerBinding {
+	return []messaging.ConsumerBinding{
+		{Exchange: messaging.SalesEventsExchange, RoutingKey: "sales.opportunity.*"},
+	}
+}
+
+// Handle re-projects the funnel entries of the opportunity an event belongs
+// to. Events without a tenant or opportunity ID are ignored.
+func (p *FunnelProjector) Handle(ctx context.Context, event messaging.ConsumedEvent) error {
+	tenantID, err := uuid.Parse(event.TenantID)
+	if err != nil {
+		return nil
+	}
+	opportunityID, err := uuid.Parse(event.AggregateID)
+	if err != nil {
+		return nil
+	}
+
+	if err := p.funnelUseCase.ProjectOpportunity(ctx, tenantID, opportunityID); err != nil {
+		return fmt.Errorf("failed to project opportunity %s: %w", opportunityID, err)
+	}
+
+	p.log.Debug().
+		Str("event_type", event.Type).
+		Str("opportunity_id", opportunityID.String()).
+		Msg("Funnel entries projected")
+	return nil
+}
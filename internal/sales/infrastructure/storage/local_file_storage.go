@@ -0,0 +1,164 @@
+// Package storage contains file storage adapters for the Sales Pipeline service.
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/kilang-desa-murni/crm/internal/sales/application/ports"
+)
+
+// ErrFileNotFound is returned when a stored file does not exist.
+var ErrFileNotFound = ports.ErrFileNotFound
+
+// LocalFileStorage implements ports.FileStorageService on the local filesystem.
+// Files are stored as <baseDir>/<tenant>/<entityType>/<entityID>/<name>, and the
+// file ID is the path relative to the tenant directory.
+type LocalFileStorage struct {
+	baseDir string
+}
+
+// NewLocalFileStorage creates a new local file storage rooted at baseDir.
+func NewLocalFileStorage(baseDir string) (*LocalFileStorage, error) {
+	if err := os.MkdirAll(baseDir, 0o750); err != nil {
+		return nil, fmt.Errorf("failed to create storage directory: %w", err)
+	}
+	return &LocalFileStorage{baseDir: baseDir}, nil
+}
+
+// Upload stores a file and returns its information.
+func (s *LocalFileStorage) Upload(ctx context.Context, req ports.FileUploadRequest) (*ports.FileInfo, error) {
+	name := uuid.New().String() + "-" + filepath.Base(req.Filename)
+	fileID := filepath.ToSlash(filepath.Join(req.EntityType, req.EntityID.String(), name))
+
+	path, err := s.path(req.TenantID, fileID)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+		return nil, fmt.Errorf("failed to create file directory: %w", err)
+	}
+	if err := os.WriteFile(path, req.Content, 0o640); err != nil {
+		return nil, fmt.Errorf("failed to write file: %w", err)
+	}
+
+	return &ports.FileInfo{
+		ID:          fileID,
+		TenantID:    req.TenantID,
+		EntityType:  req.EntityType,
+		EntityID:    req.EntityID,
+		Filename:    req.Filename,
+		ContentType: req.ContentType,
+		Size:        int64(len(req.Content)),
+		Metadata:    req.Metadata,
+		UploadedAt:  time.Now().UTC(),
+	}, nil
+}
+
+// Store writes a file under the given file ID, replacing any existing file.
+func (s *LocalFileStorage) Store(ctx context.Context, tenantID uuid.UUID, fileID string, content []byte) error {
+	path, err := s.path(tenantID, fileID)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+		return fmt.Errorf("failed to create file directory: %w", err)
+	}
+	if err := os.WriteFile(path, content, 0o640); err != nil {
+		return fmt.Errorf("failed to write file: %w", err)
+	}
+	return nil
+}
+
+// Download reads a stored file.
+func (s *LocalFileStorage) Download(ctx context.Context, tenantID uuid.UUID, fileID string) ([]byte, error) {
+	path, err := s.path(tenantID, fileID)
+	if err != nil {
+		return nil, err
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, ErrFileNotFound
+		}
+		return nil, fmt.Errorf("failed to read file: %w", err)
+	}
+	return content, nil
+}
+
+// Delete removes a stored file.
+func (s *LocalFileStorage) Delete(ctx context.Context, tenantID uuid.UUID, fileID string) error {
+	path, err := s.path(tenantID, fileID)
+	if err != nil {
+		return err
+	}
+
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to delete file: %w", err)
+	}
+	return nil
+}
+
+// GetURL is not supported by local storage; files are served through the API.
+func (s *LocalFileStorage) GetURL(ctx context.Context, tenantID uuid.UUID, fileID string, expiresIn time.Duration) (string, error) {
+	return "", errors.New("presigned URLs are not supported by local file storage")
+}
+
+// ListFiles lists the files stored for an entity.
+func (s *LocalFileStorage) ListFiles(ctx context.Context, tenantID uuid.UUID, entityType string, entityID uuid.UUID) ([]*ports.FileInfo, error) {
+	dirID := filepath.ToSlash(filepath.Join(entityType, entityID.String()))
+	dir, err := s.path(tenantID, dirID)
+	if err != nil {
+		return nil, err
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to list files: %w", err)
+	}
+
+	files := make([]*ports.FileInfo, 0, len(entries))
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+		files = append(files, &ports.FileInfo{
+			ID:         dirID + "/" + entry.Name(),
+			TenantID:   tenantID,
+			EntityType: entityType,
+			EntityID:   entityID,
+			Filename:   entry.Name(),
+			Size:       info.Size(),
+			UploadedAt: info.ModTime().UTC(),
+		})
+	}
+	return files, nil
+}
+
+// path resolves a file ID to a path inside the tenant directory.
+func (s *LocalFileStorage) path(tenantID uuid.UUID, fileID string) (string, error) {
+	tenantDir := filepath.Join(s.baseDir, tenantID.String())
+	path := filepath.Join(tenantDir, filepath.FromSlash(fileID))
+	if !strings.HasPrefix(path, tenantDir+string(filepath.Separator)) {
+		return "", fmt.Errorf("invalid file id %q", fileID)
+	}
+	return path, nil
+}
+
+// Ensure LocalFileStorage implements ports.FileStorageService
+var _ ports.FileStorageService = (*LocalFileStorage)(nil)
@@ -0,0 +1,144 @@
+// Package webhook delivers outbound webhooks for the sales service.
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/kilang-desa-murni/crm/internal/sales/application/ports"
+	"github.com/kilang-desa-murni/crm/pkg/logger"
+)
+
+const (
+	// SignatureHeader carries the HMAC-SHA256 of the timestamp and body, as
+	// "sha256=<hex>", for requests with a secret.
+	SignatureHeader = "X-Webhook-Signature"
+	// TimestampHeader carries the Unix time the request was signed at.
+	// Receivers should reject old timestamps to stop replays.
+	TimestampHeader = "X-Webhook-Timestamp"
+)
+
+// Config holds configuration for the webhook sender.
+type Config struct {
+	Timeout time.Duration
+	// RetryBackoff is the wait before the first retry; it doubles on each retry.
+	RetryBackoff time.Duration
+}
+
+// DefaultConfig returns default webhook sender configuration.
+func DefaultConfig() Config {
+	return Config{
+		Timeout:      10 * time.Second,
+		RetryBackoff: time.Second,
+	}
+}
+
+// HTTPSender implements ports.WebhookService over HTTP.
+type HTTPSender struct {
+	config     Config
+	httpClient *http.Client
+	log        *logger.Logger
+}
+
+// NewHTTPSender creates a new webhook sender. Redirects are not followed, so
+// a webhook cannot send the payload on to another host.
+func NewHTTPSender(config Config, log *logger.Logger) *HTTPSender {
+	return &HTTPSender{
+		config: config,
+		httpClient: &http.Client{
+			Timeout:       config.Timeout,
+			CheckRedirect: func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse },
+		},
+		log: log,
+	}
+}
+
+// Send delivers the webhook, retrying up to req.RetryCount times on network
+// errors and 5xx or 429 responses.
+func (s *HTTPSender) Send(ctx context.Context, req ports.WebhookRequest) error {
+	body, err := json.Marshal(req.Payload)
+	if err != nil {
+		return fmt.Errorf("failed to marshal webhook payload: %w", err)
+	}
+
+	backoff := s.config.RetryBackoff
+	for attempt := 0; ; attempt++ {
+		retry, err := s.post(ctx, req, body)
+		if err == nil || !retry || attempt >= req.RetryCount {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+	}
+}
+
+// SendAsync delivers the webhook in the background. Failures are logged.
+func (s *HTTPSender) SendAsync(ctx context.Context, req ports.WebhookRequest) error {
+	go func() {
+		if err := s.Send(context.WithoutCancel(ctx), req); err != nil && s.log != nil {
+			s.log.Warn().Err(err).Str("tenant_id", req.TenantID.String()).Msg("Webhook delivery failed")
+		}
+	}()
+	return nil
+}
+
+// post makes one delivery attempt, reporting whether a failure is worth retrying.
+func (s *HTTPSender) post(ctx context.Context, req ports.WebhookRequest, body []byte) (bool, error) {
+	method := req.Method
+	if method == "" {
+		method = http.MethodPost
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, method, req.URL, bytes.NewReader(body))
+	if err != nil {
+		return false, fmt.Errorf("failed to create webhook request: %w", err)
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	for key, value := range req.Headers {
+		httpReq.Header.Set(key, value)
+	}
+	if req.Secret != "" {
+		timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+		httpReq.Header.Set(TimestampHeader, timestamp)
+		httpReq.Header.Set(SignatureHeader, "sha256="+Sign(req.Secret, timestamp, body))
+	}
+
+	resp, err := s.httpClient.Do(httpReq)
+	if err != nil {
+		return true, fmt.Errorf("webhook request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 4096))
+
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return false, nil
+	}
+	retry := resp.StatusCode >= 500 || resp.StatusCode == http.StatusTooManyRequests
+	return retry, fmt.Errorf("webhook returned status %d", resp.StatusCode)
+}
+
+// Sign returns the hex HMAC-SHA256 of "<timestamp>.<body>" with secret.
+func Sign(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// Ensure HTTPSender implements ports.WebhookService
+var _ ports.WebhookService = (*HTTPSender)(nil)
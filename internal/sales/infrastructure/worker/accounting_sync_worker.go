@@ -0,0 +1,107 @@
+package worker
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/kilang-desa-murni/crm/internal/sales/application/usecase"
+	"github.com/kilang-desa-murni/crm/pkg/logger"
+)
+
+// AccountingSyncConfig holds configuration for the accounting sync worker.
+type AccountingSyncConfig struct {
+	// Interval is how often unsynced records are queued and due pushes run.
+	Interval time.Duration
+	// RunTimeout bounds a single run over all tenants' records.
+	RunTimeout time.Duration
+}
+
+// DefaultAccountingSyncConfig returns the default worker configuration.
+func DefaultAccountingSyncConfig() AccountingSyncConfig {
+	return AccountingSyncConfig{
+		Interval:   5 * time.Minute,
+		RunTimeout: 10 * time.Minute,
+	}
+}
+
+// AccountingSyncWorker periodically pushes issued invoices and payments to
+// tenants' accounting systems, retrying failed pushes.
+type AccountingSyncWorker struct {
+	accountingUseCase usecase.AccountingUseCase
+	config            AccountingSyncConfig
+	log               *logger.Logger
+
+	stopCh chan struct{}
+	wg     sync.WaitGroup
+	once   sync.Once
+}
+
+// NewAccountingSyncWorker creates a new accounting sync worker.
+func NewAccountingSyncWorker(accountingUseCase usecase.AccountingUseCase, config AccountingSyncConfig, log *logger.Logger) *AccountingSyncWorker {
+	defaults := DefaultAccountingSyncConfig()
+	if config.Interval <= 0 {
+		config.Interval = defaults.Interval
+	}
+	if config.RunTimeout <= 0 {
+		config.RunTimeout = defaults.RunTimeout
+	}
+
+	return &AccountingSyncWorker{
+		accountingUseCase: accountingUseCase,
+		config:            config,
+		log:               log,
+		stopCh:            make(chan struct{}),
+	}
+}
+
+// Start runs the worker in the background until Stop is called or ctx is cancelled.
+func (w *AccountingSyncWorker) Start(ctx context.Context) {
+	w.wg.Add(1)
+	go func() {
+		defer w.wg.Done()
+
+		ticker := time.NewTicker(w.config.Interval)
+		defer ticker.Stop()
+
+		w.process(ctx)
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-w.stopCh:
+				return
+			case <-ticker.C:
+				w.process(ctx)
+			}
+		}
+	}()
+}
+
+// Stop signals the worker to stop and waits for the current run to finish.
+func (w *AccountingSyncWorker) Stop() {
+	w.once.Do(func() {
+		close(w.stopCh)
+	})
+	w.wg.Wait()
+}
+
+// process pushes the records that are due across all tenants.
+func (w *AccountingSyncWorker) process(ctx context.Context) {
+	runCtx, cancel := context.WithTimeout(ctx, w.config.RunTimeout)
+	defer cancel()
+
+	started := time.Now()
+	synced, err := w.accountingUseCase.ProcessDue(runCtx, started.UTC())
+	if err != nil {
+		w.log.Error().Err(err).Int("records_synced", synced).Msg("Accounting sync failed")
+		return
+	}
+
+	if synced > 0 {
+		w.log.Info().
+			Int("records_synced", synced).
+			Dur("duration", time.Since(started)).
+			Msg("Records synced to accounting")
+	}
+}
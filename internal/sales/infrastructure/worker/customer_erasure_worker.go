@@ -0,0 +1,70 @@
+package worker
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/google/uuid"
+
+	"github.com/kilang-desa-murni/crm/internal/sales/domain"
+	"github.com/kilang-desa-murni/crm/internal/sales/infrastructure/messaging"
+	"github.com/kilang-desa-murni/crm/pkg/logger"
+)
+
+// CustomerErasureWorker erases a customer's personal data from the sales
+// records when the customer service erases the customer, and confirms it
+// with a customer.erased event of its own.
+type CustomerErasureWorker struct {
+	eraser    domain.CustomerDataEraser
+	publisher messaging.EventPublisher
+	log       *logger.Logger
+}
+
+// NewCustomerErasureWorker creates a new customer erasure worker.
+func NewCustomerErasureWorker(eraser domain.CustomerDataEraser, publisher messaging.EventPublisher, log *logger.Logger) *CustomerErasureWorker {
+	return &CustomerErasureWorker{eraser: eraser, publisher: publisher, log: log}
+}
+
+// Bindings returns the queue bindings for the events the worker handles.
+func (w *CustomerErasureWorker) Bindings() []messaging.ConsumerBinding {
+	return []messaging.ConsumerBinding{
+		{Exchange: messaging.CustomerEventsExchange, RoutingKey: "customer.erased"},
+	}
+}
+
+// Handle erases the customer of a customer.erased event and confirms the
+// erasure. Erasing is idempotent, so a redelivered event is erased and
+// confirmed again; the customer service ignores the repeated confirmation.
+// Events without a tenant, customer or erasure ID are ignored.
+func (w *CustomerErasureWorker) Handle(ctx context.Context, event messaging.ConsumedEvent) error {
+	tenantID, err := uuid.Parse(event.TenantID)
+	if err != nil {
+		return nil
+	}
+
+	var body struct {
+		CustomerID       uuid.UUID `json:"customer_id"`
+		ErasureID        uuid.UUID `json:"erasure_id"`
+		IdentifierHashes []string  `json:"identifier_hashes"`
+	}
+	if err := json.Unmarshal(event.Body, &body); err != nil || body.CustomerID == uuid.Nil || body.ErasureID == uuid.Nil {
+		return nil
+	}
+
+	records, err := w.eraser.EraseCustomer(ctx, tenantID, body.CustomerID, body.IdentifierHashes)
+	if err != nil {
+		return fmt.Errorf("failed to erase customer %s: %w", body.CustomerID, err)
+	}
+
+	confirmation := domain.NewCustomerDataErasedEvent(tenantID, body.CustomerID, body.ErasureID, records)
+	if err := w.publisher.Publish(ctx, confirmation); err != nil {
+		return fmt.Errorf("failed to confirm erasure %s: %w", body.ErasureID, err)
+	}
+
+	w.log.Info().
+		Str("erasure_id", body.ErasureID.String()).
+		Int64("records", records).
+		Msg("Customer data erased")
+	return nil
+}
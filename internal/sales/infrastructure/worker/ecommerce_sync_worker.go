@@ -0,0 +1,107 @@
+package worker
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/kilang-desa-murni/crm/internal/sales/application/usecase"
+	"github.com/kilang-desa-murni/crm/pkg/logger"
+)
+
+// EcommerceSyncConfig holds configuration for the e-commerce sync worker.
+type EcommerceSyncConfig struct {
+	// Interval is how often the connected stores are polled.
+	Interval time.Duration
+	// RunTimeout bounds a single run over all stores.
+	RunTimeout time.Duration
+}
+
+// DefaultEcommerceSyncConfig returns the default worker configuration.
+func DefaultEcommerceSyncConfig() EcommerceSyncConfig {
+	return EcommerceSyncConfig{
+		Interval:   15 * time.Minute,
+		RunTimeout: 10 * time.Minute,
+	}
+}
+
+// EcommerceSyncWorker periodically polls connected online stores, importing
+// the orders and abandoned carts their webhooks missed.
+type EcommerceSyncWorker struct {
+	ecommerceUseCase usecase.EcommerceUseCase
+	config           EcommerceSyncConfig
+	log              *logger.Logger
+
+	stopCh chan struct{}
+	wg     sync.WaitGroup
+	once   sync.Once
+}
+
+// NewEcommerceSyncWorker creates a new e-commerce sync worker.
+func NewEcommerceSyncWorker(ecommerceUseCase usecase.EcommerceUseCase, config EcommerceSyncConfig, log *logger.Logger) *EcommerceSyncWorker {
+	defaults := DefaultEcommerceSyncConfig()
+	if config.Interval <= 0 {
+		config.Interval = defaults.Interval
+	}
+	if config.RunTimeout <= 0 {
+		config.RunTimeout = defaults.RunTimeout
+	}
+
+	return &EcommerceSyncWorker{
+		ecommerceUseCase: ecommerceUseCase,
+		config:           config,
+		log:              log,
+		stopCh:           make(chan struct{}),
+	}
+}
+
+// Start runs the worker in the background until Stop is called or ctx is cancelled.
+func (w *EcommerceSyncWorker) Start(ctx context.Context) {
+	w.wg.Add(1)
+	go func() {
+		defer w.wg.Done()
+
+		ticker := time.NewTicker(w.config.Interval)
+		defer ticker.Stop()
+
+		w.process(ctx)
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-w.stopCh:
+				return
+			case <-ticker.C:
+				w.process(ctx)
+			}
+		}
+	}()
+}
+
+// Stop signals the worker to stop and waits for the current run to finish.
+func (w *EcommerceSyncWorker) Stop() {
+	w.once.Do(func() {
+		close(w.stopCh)
+	})
+	w.wg.Wait()
+}
+
+// process polls every enabled store.
+func (w *EcommerceSyncWorker) process(ctx context.Context) {
+	runCtx, cancel := context.WithTimeout(ctx, w.config.RunTimeout)
+	defer cancel()
+
+	started := time.Now()
+	imported, err := w.ecommerceUseCase.ProcessDue(runCtx, started.UTC())
+	if err != nil {
+		w.log.Error().Err(err).Int("records_imported", imported).Msg("E-commerce sync failed")
+		return
+	}
+
+	if imported > 0 {
+		w.log.Info().
+			Int("records_imported", imported).
+			Dur("duration", time.Since(started)).
+			Msg("Store orders and carts imported")
+	}
+}
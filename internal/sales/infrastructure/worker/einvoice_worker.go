@@ -0,0 +1,108 @@
+package worker
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/kilang-desa-murni/crm/internal/sales/application/usecase"
+	"github.com/kilang-desa-murni/crm/pkg/logger"
+)
+
+// EInvoiceConfig holds configuration for the e-invoice worker.
+type EInvoiceConfig struct {
+	// Interval is how often due submissions and validation checks are run.
+	// MyInvois usually validates a document within seconds.
+	Interval time.Duration
+	// RunTimeout bounds a single run over all tenants' e-invoices.
+	RunTimeout time.Duration
+}
+
+// DefaultEInvoiceConfig returns the default worker configuration.
+func DefaultEInvoiceConfig() EInvoiceConfig {
+	return EInvoiceConfig{
+		Interval:   time.Minute,
+		RunTimeout: 5 * time.Minute,
+	}
+}
+
+// EInvoiceWorker periodically retries failed MyInvois submissions and
+// collects the validation results of submitted e-invoices.
+type EInvoiceWorker struct {
+	einvoiceUseCase usecase.EInvoiceUseCase
+	config          EInvoiceConfig
+	log             *logger.Logger
+
+	stopCh chan struct{}
+	wg     sync.WaitGroup
+	once   sync.Once
+}
+
+// NewEInvoiceWorker creates a new e-invoice worker.
+func NewEInvoiceWorker(einvoiceUseCase usecase.EInvoiceUseCase, config EInvoiceConfig, log *logger.Logger) *EInvoiceWorker {
+	defaults := DefaultEInvoiceConfig()
+	if config.Interval <= 0 {
+		config.Interval = defaults.Interval
+	}
+	if config.RunTimeout <= 0 {
+		config.RunTimeout = defaults.RunTimeout
+	}
+
+	return &EInvoiceWorker{
+		einvoiceUseCase: einvoiceUseCase,
+		config:          config,
+		log:             log,
+		stopCh:          make(chan struct{}),
+	}
+}
+
+// Start runs the worker in the background until Stop is called or ctx is cancelled.
+func (w *EInvoiceWorker) Start(ctx context.Context) {
+	w.wg.Add(1)
+	go func() {
+		defer w.wg.Done()
+
+		ticker := time.NewTicker(w.config.Interval)
+		defer ticker.Stop()
+
+		w.process(ctx)
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-w.stopCh:
+				return
+			case <-ticker.C:
+				w.process(ctx)
+			}
+		}
+	}()
+}
+
+// Stop signals the worker to stop and waits for the current run to finish.
+func (w *EInvoiceWorker) Stop() {
+	w.once.Do(func() {
+		close(w.stopCh)
+	})
+	w.wg.Wait()
+}
+
+// process submits and checks the e-invoices that are due across all tenants.
+func (w *EInvoiceWorker) process(ctx context.Context) {
+	runCtx, cancel := context.WithTimeout(ctx, w.config.RunTimeout)
+	defer cancel()
+
+	started := time.Now()
+	updated, err := w.einvoiceUseCase.ProcessDue(runCtx, started.UTC())
+	if err != nil {
+		w.log.Error().Err(err).Int("einvoices_updated", updated).Msg("E-invoice processing failed")
+		return
+	}
+
+	if updated > 0 {
+		w.log.Info().
+			Int("einvoices_updated", updated).
+			Dur("duration", time.Since(started)).
+			Msg("E-invoices updated")
+	}
+}
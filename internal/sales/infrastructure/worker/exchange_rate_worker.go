@@ -0,0 +1,108 @@
+package worker
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/kilang-desa-murni/crm/internal/sales/application/usecase"
+	"github.com/kilang-desa-murni/crm/pkg/logger"
+)
+
+// ExchangeRateRefreshConfig holds configuration for the exchange rate refresh worker.
+type ExchangeRateRefreshConfig struct {
+	// Interval is how often provider rates are fetched. ECB and BNM publish
+	// once per business day, so a few hours is enough to pick up new rates.
+	Interval time.Duration
+	// RunTimeout bounds a single refresh of all tenants.
+	RunTimeout time.Duration
+}
+
+// DefaultExchangeRateRefreshConfig returns the default worker configuration.
+func DefaultExchangeRateRefreshConfig() ExchangeRateRefreshConfig {
+	return ExchangeRateRefreshConfig{
+		Interval:   6 * time.Hour,
+		RunTimeout: 2 * time.Minute,
+	}
+}
+
+// ExchangeRateRefreshWorker periodically fetches exchange rates for tenants
+// that have an automatic rate provider configured.
+type ExchangeRateRefreshWorker struct {
+	exchangeRateUseCase usecase.ExchangeRateUseCase
+	config              ExchangeRateRefreshConfig
+	log                 *logger.Logger
+
+	stopCh chan struct{}
+	wg     sync.WaitGroup
+	once   sync.Once
+}
+
+// NewExchangeRateRefreshWorker creates a new exchange rate refresh worker.
+func NewExchangeRateRefreshWorker(exchangeRateUseCase usecase.ExchangeRateUseCase, config ExchangeRateRefreshConfig, log *logger.Logger) *ExchangeRateRefreshWorker {
+	defaults := DefaultExchangeRateRefreshConfig()
+	if config.Interval <= 0 {
+		config.Interval = defaults.Interval
+	}
+	if config.RunTimeout <= 0 {
+		config.RunTimeout = defaults.RunTimeout
+	}
+
+	return &ExchangeRateRefreshWorker{
+		exchangeRateUseCase: exchangeRateUseCase,
+		config:              config,
+		log:                 log,
+		stopCh:              make(chan struct{}),
+	}
+}
+
+// Start runs the worker in the background until Stop is called or ctx is cancelled.
+func (w *ExchangeRateRefreshWorker) Start(ctx context.Context) {
+	w.wg.Add(1)
+	go func() {
+		defer w.wg.Done()
+
+		ticker := time.NewTicker(w.config.Interval)
+		defer ticker.Stop()
+
+		w.refresh(ctx)
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-w.stopCh:
+				return
+			case <-ticker.C:
+				w.refresh(ctx)
+			}
+		}
+	}()
+}
+
+// Stop signals the worker to stop and waits for the current refresh to finish.
+func (w *ExchangeRateRefreshWorker) Stop() {
+	w.once.Do(func() {
+		close(w.stopCh)
+	})
+	w.wg.Wait()
+}
+
+// refresh fetches provider rates for all tenants.
+func (w *ExchangeRateRefreshWorker) refresh(ctx context.Context) {
+	runCtx, cancel := context.WithTimeout(ctx, w.config.RunTimeout)
+	defer cancel()
+
+	started := time.Now()
+	refreshed, err := w.exchangeRateUseCase.RefreshAllTenants(runCtx)
+	if err != nil {
+		w.log.Error().Err(err).Int("tenants_refreshed", refreshed).Msg("Exchange rate refresh failed")
+		return
+	}
+
+	if refreshed > 0 {
+		w.log.Info().
+			Int("tenants_refreshed", refreshed).
+			Dur("duration", time.Since(started)).
+			Msg("Exchange rates refreshed")
+	}
+}
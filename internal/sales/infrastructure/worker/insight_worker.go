@@ -0,0 +1,142 @@
+package worker
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/kilang-desa-murni/crm/internal/sales/application/usecase"
+	"github.com/kilang-desa-murni/crm/internal/sales/infrastructure/messaging"
+	"github.com/kilang-desa-murni/crm/pkg/logger"
+)
+
+// InsightConfig holds configuration for the insight worker.
+type InsightConfig struct {
+	// Interval is how often pipeline coverage and lead volume are checked.
+	// Each insight is raised once per period, so checks may run often.
+	Interval time.Duration
+	// RunTimeout bounds a single check of all active tenants.
+	RunTimeout time.Duration
+}
+
+// DefaultInsightConfig returns the default worker configuration.
+func DefaultInsightConfig() InsightConfig {
+	return InsightConfig{
+		Interval:   time.Hour,
+		RunTimeout: 10 * time.Minute,
+	}
+}
+
+// InsightWorker runs the insights engine: it checks won opportunities as
+// their events arrive, and periodically checks every active tenant's
+// pipeline coverage and lead volume.
+type InsightWorker struct {
+	insightUseCase usecase.InsightUseCase
+	config         InsightConfig
+	log            *logger.Logger
+
+	stopCh chan struct{}
+	wg     sync.WaitGroup
+	once   sync.Once
+}
+
+// NewInsightWorker creates a new insight worker.
+func NewInsightWorker(insightUseCase usecase.InsightUseCase, config InsightConfig, log *logger.Logger) *InsightWorker {
+	defaults := DefaultInsightConfig()
+	if config.Interval <= 0 {
+		config.Interval = defaults.Interval
+	}
+	if config.RunTimeout <= 0 {
+		config.RunTimeout = defaults.RunTimeout
+	}
+
+	return &InsightWorker{
+		insightUseCase: insightUseCase,
+		config:         config,
+		log:            log,
+		stopCh:         make(chan struct{}),
+	}
+}
+
+// Bindings returns the queue bindings for the events the worker handles.
+func (w *InsightWorker) Bindings() []messaging.ConsumerBinding {
+	return []messaging.ConsumerBinding{
+		{Exchange: messaging.SalesEventsExchange, RoutingKey: "sales.opportunity.won"},
+	}
+}
+
+// Handle checks whether a won opportunity is the biggest deal of its quarter.
+// Events without a tenant or opportunity ID are ignored.
+func (w *InsightWorker) Handle(ctx context.Context, event messaging.ConsumedEvent) error {
+	tenantID, err := uuid.Parse(event.TenantID)
+	if err != nil {
+		return nil
+	}
+	opportunityID, err := uuid.Parse(event.AggregateID)
+	if err != nil {
+		return nil
+	}
+
+	if err := w.insightUseCase.EvaluateWonOpportunity(ctx, tenantID, opportunityID); err != nil {
+		return fmt.Errorf("failed to evaluate won opportunity %s: %w", opportunityID, err)
+	}
+	return nil
+}
+
+// Start runs the scheduled checks in the background until Stop is called or
+// ctx is cancelled.
+func (w *InsightWorker) Start(ctx context.Context) {
+	w.wg.Add(1)
+	go func() {
+		defer w.wg.Done()
+
+		ticker := time.NewTicker(w.config.Interval)
+		defer ticker.Stop()
+
+		w.check(ctx)
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-w.stopCh:
+				return
+			case <-ticker.C:
+				w.check(ctx)
+			}
+		}
+	}()
+}
+
+// Stop signals the worker to stop and waits for the current run to finish.
+func (w *InsightWorker) Stop() {
+	w.once.Do(func() {
+		close(w.stopCh)
+	})
+	w.wg.Wait()
+}
+
+// check runs the scheduled insight checks for all active tenants.
+func (w *InsightWorker) check(ctx context.Context) {
+	runCtx, cancel := context.WithTimeout(ctx, w.config.RunTimeout)
+	defer cancel()
+
+	started := time.Now()
+	result, err := w.insightUseCase.RunScheduledChecks(runCtx, started.UTC())
+	if err != nil {
+		w.log.Error().Err(err).Msg("Insight check failed")
+		if result == nil {
+			return
+		}
+	}
+
+	if result.Detected > 0 {
+		w.log.Info().
+			Int("tenants", result.Tenants).
+			Int("insights_detected", result.Detected).
+			Dur("duration", time.Since(started)).
+			Msg("Insights detected")
+	}
+}
@@ -0,0 +1,108 @@
+package worker
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/kilang-desa-murni/crm/internal/sales/application/usecase"
+	"github.com/kilang-desa-murni/crm/pkg/logger"
+)
+
+// InvoiceOverdueConfig holds configuration for the overdue invoice worker.
+type InvoiceOverdueConfig struct {
+	// Interval is how often sent invoices are checked against their due
+	// date.
+	Interval time.Duration
+	// RunTimeout bounds a single check of all tenants' invoices.
+	RunTimeout time.Duration
+}
+
+// DefaultInvoiceOverdueConfig returns the default worker configuration.
+func DefaultInvoiceOverdueConfig() InvoiceOverdueConfig {
+	return InvoiceOverdueConfig{
+		Interval:   time.Hour,
+		RunTimeout: 10 * time.Minute,
+	}
+}
+
+// InvoiceOverdueWorker periodically marks sent invoices past their due date
+// as overdue, which publishes the overdue alerts.
+type InvoiceOverdueWorker struct {
+	dealUseCase usecase.DealUseCase
+	config      InvoiceOverdueConfig
+	log         *logger.Logger
+
+	stopCh chan struct{}
+	wg     sync.WaitGroup
+	once   sync.Once
+}
+
+// NewInvoiceOverdueWorker creates a new overdue invoice worker.
+func NewInvoiceOverdueWorker(dealUseCase usecase.DealUseCase, config InvoiceOverdueConfig, log *logger.Logger) *InvoiceOverdueWorker {
+	defaults := DefaultInvoiceOverdueConfig()
+	if config.Interval <= 0 {
+		config.Interval = defaults.Interval
+	}
+	if config.RunTimeout <= 0 {
+		config.RunTimeout = defaults.RunTimeout
+	}
+
+	return &InvoiceOverdueWorker{
+		dealUseCase: dealUseCase,
+		config:      config,
+		log:         log,
+		stopCh:      make(chan struct{}),
+	}
+}
+
+// Start runs the worker in the background until Stop is called or ctx is cancelled.
+func (w *InvoiceOverdueWorker) Start(ctx context.Context) {
+	w.wg.Add(1)
+	go func() {
+		defer w.wg.Done()
+
+		ticker := time.NewTicker(w.config.Interval)
+		defer ticker.Stop()
+
+		w.check(ctx)
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-w.stopCh:
+				return
+			case <-ticker.C:
+				w.check(ctx)
+			}
+		}
+	}()
+}
+
+// Stop signals the worker to stop and waits for the current run to finish.
+func (w *InvoiceOverdueWorker) Stop() {
+	w.once.Do(func() {
+		close(w.stopCh)
+	})
+	w.wg.Wait()
+}
+
+// check marks the lapsed invoices of all tenants overdue.
+func (w *InvoiceOverdueWorker) check(ctx context.Context) {
+	runCtx, cancel := context.WithTimeout(ctx, w.config.RunTimeout)
+	defer cancel()
+
+	started := time.Now()
+	marked, err := w.dealUseCase.MarkOverdueInvoices(runCtx, started.UTC())
+	if err != nil {
+		w.log.Error().Err(err).Int("invoices_marked", marked).Msg("Overdue invoice check failed")
+		return
+	}
+
+	if marked > 0 {
+		w.log.Info().
+			Int("invoices_marked", marked).
+			Dur("duration", time.Since(started)).
+			Msg("Invoices marked overdue")
+	}
+}
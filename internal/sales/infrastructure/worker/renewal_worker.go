@@ -0,0 +1,107 @@
+package worker
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/kilang-desa-murni/crm/internal/sales/application/usecase"
+	"github.com/kilang-desa-murni/crm/pkg/logger"
+)
+
+// RenewalConfig holds configuration for the renewal worker.
+type RenewalConfig struct {
+	// Interval is how often recurring deals are checked for renewals due.
+	Interval time.Duration
+	// RunTimeout bounds a single check of all tenants' recurring deals.
+	RunTimeout time.Duration
+}
+
+// DefaultRenewalConfig returns the default worker configuration.
+func DefaultRenewalConfig() RenewalConfig {
+	return RenewalConfig{
+		Interval:   time.Hour,
+		RunTimeout: 10 * time.Minute,
+	}
+}
+
+// RenewalWorker periodically opens renewal opportunities for recurring deals
+// whose renewal notice has started.
+type RenewalWorker struct {
+	opportunityUseCase usecase.OpportunityUseCase
+	config             RenewalConfig
+	log                *logger.Logger
+
+	stopCh chan struct{}
+	wg     sync.WaitGroup
+	once   sync.Once
+}
+
+// NewRenewalWorker creates a new renewal worker.
+func NewRenewalWorker(opportunityUseCase usecase.OpportunityUseCase, config RenewalConfig, log *logger.Logger) *RenewalWorker {
+	defaults := DefaultRenewalConfig()
+	if config.Interval <= 0 {
+		config.Interval = defaults.Interval
+	}
+	if config.RunTimeout <= 0 {
+		config.RunTimeout = defaults.RunTimeout
+	}
+
+	return &RenewalWorker{
+		opportunityUseCase: opportunityUseCase,
+		config:             config,
+		log:                log,
+		stopCh:             make(chan struct{}),
+	}
+}
+
+// Start runs the worker in the background until Stop is called or ctx is cancelled.
+func (w *RenewalWorker) Start(ctx context.Context) {
+	w.wg.Add(1)
+	go func() {
+		defer w.wg.Done()
+
+		ticker := time.NewTicker(w.config.Interval)
+		defer ticker.Stop()
+
+		w.check(ctx)
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-w.stopCh:
+				return
+			case <-ticker.C:
+				w.check(ctx)
+			}
+		}
+	}()
+}
+
+// Stop signals the worker to stop and waits for the current run to finish.
+func (w *RenewalWorker) Stop() {
+	w.once.Do(func() {
+		close(w.stopCh)
+	})
+	w.wg.Wait()
+}
+
+// check creates the renewal opportunities due across all tenants.
+func (w *RenewalWorker) check(ctx context.Context) {
+	runCtx, cancel := context.WithTimeout(ctx, w.config.RunTimeout)
+	defer cancel()
+
+	started := time.Now()
+	created, err := w.opportunityUseCase.CreateRenewalOpportunities(runCtx, started.UTC())
+	if err != nil {
+		w.log.Error().Err(err).Int("renewals_created", created).Msg("Renewal check failed")
+		return
+	}
+
+	if created > 0 {
+		w.log.Info().
+			Int("renewals_created", created).
+			Dur("duration", time.Since(started)).
+			Msg("Renewal opportunities created")
+	}
+}
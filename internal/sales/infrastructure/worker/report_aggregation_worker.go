@@ -0,0 +1,161 @@
+// Package worker contains background workers for the Sales Pipeline service.
+package worker
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/kilang-desa-murni/crm/internal/sales/application/usecase"
+	"github.com/kilang-desa-murni/crm/internal/sales/domain"
+	"github.com/kilang-desa-murni/crm/pkg/logger"
+)
+
+// ReportAggregationConfig holds configuration for the report aggregation worker.
+type ReportAggregationConfig struct {
+	// RunHour is the UTC hour after which the previous day is aggregated.
+	RunHour int
+	// CheckInterval is how often the worker checks whether a run is due.
+	CheckInterval time.Duration
+	// MaxCatchUpDays limits how many missed days are aggregated on a single check.
+	MaxCatchUpDays int
+	// RunTimeout bounds a single day's aggregation.
+	RunTimeout time.Duration
+}
+
+// DefaultReportAggregationConfig returns the default worker configuration.
+func DefaultReportAggregationConfig() ReportAggregationConfig {
+	return ReportAggregationConfig{
+		RunHour:        1,
+		CheckInterval:  15 * time.Minute,
+		MaxCatchUpDays: 31,
+		RunTimeout:     10 * time.Minute,
+	}
+}
+
+// ReportAggregationWorker refreshes the daily sales aggregates every night,
+// catching up on any days missed while the service was down.
+type ReportAggregationWorker struct {
+	reportUseCase usecase.ReportUseCase
+	config        ReportAggregationConfig
+	log           *logger.Logger
+
+	now    func() time.Time
+	stopCh chan struct{}
+	wg     sync.WaitGroup
+	once   sync.Once
+}
+
+// NewReportAggregationWorker creates a new report aggregation worker.
+func NewReportAggregationWorker(reportUseCase usecase.ReportUseCase, config ReportAggregationConfig, log *logger.Logger) *ReportAggregationWorker {
+	defaults := DefaultReportAggregationConfig()
+	if config.CheckInterval <= 0 {
+		config.CheckInterval = defaults.CheckInterval
+	}
+	if config.MaxCatchUpDays <= 0 {
+		config.MaxCatchUpDays = defaults.MaxCatchUpDays
+	}
+	if config.RunTimeout <= 0 {
+		config.RunTimeout = defaults.RunTimeout
+	}
+	if config.RunHour < 0 || config.RunHour > 23 {
+		config.RunHour = defaults.RunHour
+	}
+
+	return &ReportAggregationWorker{
+		reportUseCase: reportUseCase,
+		config:        config,
+		log:           log,
+		now:           time.Now,
+		stopCh:        make(chan struct{}),
+	}
+}
+
+// Start runs the worker in the background until Stop is called or ctx is cancelled.
+func (w *ReportAggregationWorker) Start(ctx context.Context) {
+	w.wg.Add(1)
+	go func() {
+		defer w.wg.Done()
+
+		ticker := time.NewTicker(w.config.CheckInterval)
+		defer ticker.Stop()
+
+		w.runDue(ctx)
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-w.stopCh:
+				return
+			case <-ticker.C:
+				w.runDue(ctx)
+			}
+		}
+	}()
+}
+
+// Stop signals the worker to stop and waits for the current run to finish.
+func (w *ReportAggregationWorker) Stop() {
+	w.once.Do(func() {
+		close(w.stopCh)
+	})
+	w.wg.Wait()
+}
+
+// runDue aggregates every completed day that has not been aggregated yet.
+func (w *ReportAggregationWorker) runDue(ctx context.Context) {
+	now := w.now().UTC()
+	if now.Hour() < w.config.RunHour {
+		// Yesterday is only aggregated once late-arriving updates have settled.
+		now = now.AddDate(0, 0, -1)
+	}
+	latest := domain.TruncateToDay(now).AddDate(0, 0, -1)
+
+	lastDay, err := w.reportUseCase.GetLastAggregatedDay(ctx)
+	if err != nil {
+		w.log.Error().Err(err).Msg("Failed to get last aggregated day")
+		return
+	}
+
+	for _, day := range pendingAggregationDays(lastDay, latest, w.config.MaxCatchUpDays) {
+		select {
+		case <-ctx.Done():
+			return
+		case <-w.stopCh:
+			return
+		default:
+		}
+
+		runCtx, cancel := context.WithTimeout(ctx, w.config.RunTimeout)
+		result, err := w.reportUseCase.RunDailyAggregation(runCtx, day)
+		cancel()
+		if err != nil {
+			w.log.Error().Err(err).Str("day", day.Format("2006-01-02")).Msg("Daily sales aggregation failed")
+			return
+		}
+
+		w.log.Info().
+			Str("day", day.Format("2006-01-02")).
+			Int64("rows_written", result.RowsWritten).
+			Dur("duration", result.CompletedAt.Sub(result.StartedAt)).
+			Msg("Daily sales aggregation completed")
+	}
+}
+
+// pendingAggregationDays returns the days after lastDay up to and including latest,
+// oldest first and limited to the most recent maxDays.
+func pendingAggregationDays(lastDay *time.Time, latest time.Time, maxDays int) []time.Time {
+	start := latest.AddDate(0, 0, -(maxDays - 1))
+	if lastDay != nil {
+		next := domain.TruncateToDay(*lastDay).AddDate(0, 0, 1)
+		if next.After(start) {
+			start = next
+		}
+	}
+
+	var days []time.Time
+	for day := start; !day.After(latest); day = day.AddDate(0, 0, 1) {
+		days = append(days, day)
+	}
+	return days
+}
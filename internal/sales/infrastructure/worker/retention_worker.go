@@ -0,0 +1,114 @@
+package worker
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/kilang-desa-murni/crm/internal/sales/application/usecase"
+	"github.com/kilang-desa-murni/crm/pkg/logger"
+)
+
+// RetentionConfig holds configuration for the data retention worker.
+type RetentionConfig struct {
+	// Interval is how often retention policies are applied. Policies count
+	// in days, so once a day is enough.
+	Interval time.Duration
+	// RunTimeout bounds a single run over all tenants' policies.
+	RunTimeout time.Duration
+}
+
+// DefaultRetentionConfig returns the default worker configuration.
+func DefaultRetentionConfig() RetentionConfig {
+	return RetentionConfig{
+		Interval:   24 * time.Hour,
+		RunTimeout: 30 * time.Minute,
+	}
+}
+
+// RetentionWorker periodically archives or purges the records that tenants'
+// retention policies have expired.
+type RetentionWorker struct {
+	retentionUseCase usecase.RetentionUseCase
+	config           RetentionConfig
+	log              *logger.Logger
+
+	stopCh chan struct{}
+	wg     sync.WaitGroup
+	once   sync.Once
+}
+
+// NewRetentionWorker creates a new data retention worker.
+func NewRetentionWorker(retentionUseCase usecase.RetentionUseCase, config RetentionConfig, log *logger.Logger) *RetentionWorker {
+	defaults := DefaultRetentionConfig()
+	if config.Interval <= 0 {
+		config.Interval = defaults.Interval
+	}
+	if config.RunTimeout <= 0 {
+		config.RunTimeout = defaults.RunTimeout
+	}
+
+	return &RetentionWorker{
+		retentionUseCase: retentionUseCase,
+		config:           config,
+		log:              log,
+		stopCh:           make(chan struct{}),
+	}
+}
+
+// Start runs the worker in the background until Stop is called or ctx is cancelled.
+func (w *RetentionWorker) Start(ctx context.Context) {
+	w.wg.Add(1)
+	go func() {
+		defer w.wg.Done()
+
+		ticker := time.NewTicker(w.config.Interval)
+		defer ticker.Stop()
+
+		w.apply(ctx)
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-w.stopCh:
+				return
+			case <-ticker.C:
+				w.apply(ctx)
+			}
+		}
+	}()
+}
+
+// Stop signals the worker to stop and waits for the current run to finish.
+func (w *RetentionWorker) Stop() {
+	w.once.Do(func() {
+		close(w.stopCh)
+	})
+	w.wg.Wait()
+}
+
+// apply applies the retention policies of all tenants.
+func (w *RetentionWorker) apply(ctx context.Context) {
+	runCtx, cancel := context.WithTimeout(ctx, w.config.RunTimeout)
+	defer cancel()
+
+	started := time.Now()
+	result, err := w.retentionUseCase.ApplyPolicies(runCtx)
+	if err != nil {
+		event := w.log.Error().Err(err)
+		if result != nil {
+			event = event.Int64("archived", result.Archived).Int64("purged", result.Purged)
+		}
+		event.Msg("Data retention run failed")
+		return
+	}
+
+	if result.Archived > 0 || result.Purged > 0 {
+		w.log.Info().
+			Int("policies_applied", result.PoliciesApplied).
+			Int64("archived", result.Archived).
+			Int64("purged", result.Purged).
+			Dur("duration", time.Since(started)).
+			Msg("Data retention policies applied")
+	}
+}
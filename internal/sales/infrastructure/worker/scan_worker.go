@@ -0,0 +1,67 @@
+package worker
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/google/uuid"
+
+	"github.com/kilang-desa-murni/crm/internal/sales/application"
+	"github.com/kilang-desa-murni/crm/internal/sales/application/usecase"
+	"github.com/kilang-desa-murni/crm/internal/sales/infrastructure/messaging"
+	"github.com/kilang-desa-murni/crm/pkg/logger"
+)
+
+// ScanWorker scans uploaded attachments for viruses in the background, so
+// files are released from quarantine without waiting for their first
+// download.
+type ScanWorker struct {
+	attachmentUseCase usecase.AttachmentUseCase
+	log               *logger.Logger
+}
+
+// NewScanWorker creates a new scan worker.
+func NewScanWorker(attachmentUseCase usecase.AttachmentUseCase, log *logger.Logger) *ScanWorker {
+	return &ScanWorker{attachmentUseCase: attachmentUseCase, log: log}
+}
+
+// Bindings returns the queue bindings for the events the worker handles.
+func (w *ScanWorker) Bindings() []messaging.ConsumerBinding {
+	return []messaging.ConsumerBinding{
+		{Exchange: messaging.SalesEventsExchange, RoutingKey: "sales.file.uploaded"},
+	}
+}
+
+// Handle scans an uploaded file. Events without a tenant or file ID, and files
+// deleted since, are ignored. A failed scan is retried; a file whose scan is
+// missed is scanned when it is first downloaded.
+func (w *ScanWorker) Handle(ctx context.Context, event messaging.ConsumedEvent) error {
+	tenantID, err := uuid.Parse(event.TenantID)
+	if err != nil {
+		return nil
+	}
+
+	var body struct {
+		Payload struct {
+			FileID string `json:"file_id"`
+		} `json:"payload"`
+	}
+	if err := json.Unmarshal(event.Body, &body); err != nil || body.Payload.FileID == "" {
+		return nil
+	}
+	fileID := body.Payload.FileID
+
+	if err := w.attachmentUseCase.ScanAttachment(ctx, tenantID, fileID); err != nil {
+		if application.IsNotFoundError(err) {
+			w.log.Warn().Err(err).Str("file_id", fileID).Msg("Skipping attachment scan")
+			return nil
+		}
+		return fmt.Errorf("failed to scan attachment %s: %w", fileID, err)
+	}
+
+	w.log.Debug().
+		Str("file_id", fileID).
+		Msg("Attachment scanned")
+	return nil
+}
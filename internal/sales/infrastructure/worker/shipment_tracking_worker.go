@@ -0,0 +1,108 @@
+package worker
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/kilang-desa-murni/crm/internal/sales/application/usecase"
+	"github.com/kilang-desa-murni/crm/pkg/logger"
+)
+
+// ShipmentTrackingConfig holds configuration for the renewal worker.
+type ShipmentTrackingConfig struct {
+	// Interval is how often shipments are checked for carrier updates. Each
+	// shipment is asked about at most every two hours.
+	Interval time.Duration
+	// RunTimeout bounds a single poll of all tenants' shipments.
+	RunTimeout time.Duration
+}
+
+// DefaultShipmentTrackingConfig returns the default worker configuration.
+func DefaultShipmentTrackingConfig() ShipmentTrackingConfig {
+	return ShipmentTrackingConfig{
+		Interval:   15 * time.Minute,
+		RunTimeout: 10 * time.Minute,
+	}
+}
+
+// ShipmentTrackingWorker periodically polls carriers for the progress of
+// shipments on their way.
+type ShipmentTrackingWorker struct {
+	shipmentUseCase usecase.ShipmentUseCase
+	config          ShipmentTrackingConfig
+	log             *logger.Logger
+
+	stopCh chan struct{}
+	wg     sync.WaitGroup
+	once   sync.Once
+}
+
+// NewShipmentTrackingWorker creates a new shipment tracking worker.
+func NewShipmentTrackingWorker(shipmentUseCase usecase.ShipmentUseCase, config ShipmentTrackingConfig, log *logger.Logger) *ShipmentTrackingWorker {
+	defaults := DefaultShipmentTrackingConfig()
+	if config.Interval <= 0 {
+		config.Interval = defaults.Interval
+	}
+	if config.RunTimeout <= 0 {
+		config.RunTimeout = defaults.RunTimeout
+	}
+
+	return &ShipmentTrackingWorker{
+		shipmentUseCase: shipmentUseCase,
+		config:          config,
+		log:             log,
+		stopCh:          make(chan struct{}),
+	}
+}
+
+// Start runs the worker in the background until Stop is called or ctx is cancelled.
+func (w *ShipmentTrackingWorker) Start(ctx context.Context) {
+	w.wg.Add(1)
+	go func() {
+		defer w.wg.Done()
+
+		ticker := time.NewTicker(w.config.Interval)
+		defer ticker.Stop()
+
+		w.check(ctx)
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-w.stopCh:
+				return
+			case <-ticker.C:
+				w.check(ctx)
+			}
+		}
+	}()
+}
+
+// Stop signals the worker to stop and waits for the current run to finish.
+func (w *ShipmentTrackingWorker) Stop() {
+	w.once.Do(func() {
+		close(w.stopCh)
+	})
+	w.wg.Wait()
+}
+
+// check polls the carriers for the shipments due a check across all tenants.
+func (w *ShipmentTrackingWorker) check(ctx context.Context) {
+	runCtx, cancel := context.WithTimeout(ctx, w.config.RunTimeout)
+	defer cancel()
+
+	started := time.Now()
+	updated, err := w.shipmentUseCase.PollCarriers(runCtx, started.UTC())
+	if err != nil {
+		w.log.Error().Err(err).Int("shipments_updated", updated).Msg("Shipment tracking poll failed")
+		return
+	}
+
+	if updated > 0 {
+		w.log.Info().
+			Int("shipments_updated", updated).
+			Dur("duration", time.Since(started)).
+			Msg("Shipment tracking updated")
+	}
+}
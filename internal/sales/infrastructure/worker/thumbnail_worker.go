@@ -0,0 +1,73 @@
+package worker
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/google/uuid"
+
+	"github.com/kilang-desa-murni/crm/internal/sales/application"
+	"github.com/kilang-desa-murni/crm/internal/sales/application/usecase"
+	"github.com/kilang-desa-murni/crm/internal/sales/infrastructure/messaging"
+	"github.com/kilang-desa-murni/crm/pkg/logger"
+)
+
+// ThumbnailWorker generates the image variants of uploaded attachments in the
+// background, so downloads of thumbnails rarely have to wait for a resize.
+type ThumbnailWorker struct {
+	attachmentUseCase usecase.AttachmentUseCase
+	log               *logger.Logger
+}
+
+// NewThumbnailWorker creates a new thumbnail worker.
+func NewThumbnailWorker(attachmentUseCase usecase.AttachmentUseCase, log *logger.Logger) *ThumbnailWorker {
+	return &ThumbnailWorker{attachmentUseCase: attachmentUseCase, log: log}
+}
+
+// Bindings returns the queue bindings for the events the worker handles.
+func (w *ThumbnailWorker) Bindings() []messaging.ConsumerBinding {
+	return []messaging.ConsumerBinding{
+		{Exchange: messaging.SalesEventsExchange, RoutingKey: "sales.file.uploaded"},
+		// Quarantined files are resized once released by their scan
+		{Exchange: messaging.SalesEventsExchange, RoutingKey: "sales.file.scanned"},
+	}
+}
+
+// Handle generates the image variants of an uploaded file. Events without a
+// tenant or file ID, and files that cannot be decoded as images, are ignored;
+// a missing variant is still generated when it is first downloaded.
+func (w *ThumbnailWorker) Handle(ctx context.Context, event messaging.ConsumedEvent) error {
+	tenantID, err := uuid.Parse(event.TenantID)
+	if err != nil {
+		return nil
+	}
+
+	var body struct {
+		Payload struct {
+			FileID   string `json:"file_id"`
+			Released bool   `json:"released"`
+		} `json:"payload"`
+	}
+	if err := json.Unmarshal(event.Body, &body); err != nil || body.Payload.FileID == "" {
+		return nil
+	}
+	// Files that were not quarantined were resized when uploaded
+	if event.Type == "sales.file.scanned" && !body.Payload.Released {
+		return nil
+	}
+	fileID := body.Payload.FileID
+
+	if err := w.attachmentUseCase.GenerateVariants(ctx, tenantID, fileID); err != nil {
+		if application.IsValidationError(err) || application.IsNotFoundError(err) {
+			w.log.Warn().Err(err).Str("file_id", fileID).Msg("Skipping image variants")
+			return nil
+		}
+		return fmt.Errorf("failed to generate image variants for %s: %w", fileID, err)
+	}
+
+	w.log.Debug().
+		Str("file_id", fileID).
+		Msg("Image variants generated")
+	return nil
+}
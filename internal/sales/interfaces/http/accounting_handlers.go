@@ -0,0 +1,222 @@
+package http
+
+import (
+	"io"
+	"net/http"
+
+	"github.com/kilang-desa-murni/crm/internal/sales/application/dto"
+)
+
+// maxAccountingWebhookBytes bounds the body of an accounting webhook.
+const maxAccountingWebhookBytes = 1 << 20
+
+// ============================================================================
+// Accounting Connection Handler Methods
+// ============================================================================
+
+// GetAccountingConnection handles GET /accounting/connection
+func (h *Handler) GetAccountingConnection(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	tenantID, err := h.getTenantID(ctx)
+	if err != nil {
+		h.respondError(w, ErrUnauthorized("tenant identification required"))
+		return
+	}
+
+	connection, err := h.accountingUseCase.GetConnection(ctx, tenantID)
+	if err != nil {
+		h.respondError(w, toHTTPError(err))
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, connection)
+}
+
+// UpdateAccountingConnection handles PUT /accounting/connection
+func (h *Handler) UpdateAccountingConnection(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	tenantID, err := h.getTenantID(ctx)
+	if err != nil {
+		h.respondError(w, ErrUnauthorized("tenant identification required"))
+		return
+	}
+
+	var req dto.UpdateAccountingConnectionRequest
+	if err := h.decodeJSON(r, &req); err != nil {
+		h.respondError(w, ErrInvalidJSON(err.Error()))
+		return
+	}
+
+	connection, err := h.accountingUseCase.UpdateConnection(ctx, tenantID, &req)
+	if err != nil {
+		h.respondError(w, toHTTPError(err))
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, connection)
+}
+
+// DeleteAccountingConnection handles DELETE /accounting/connection
+func (h *Handler) DeleteAccountingConnection(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	tenantID, err := h.getTenantID(ctx)
+	if err != nil {
+		h.respondError(w, ErrUnauthorized("tenant identification required"))
+		return
+	}
+
+	if err := h.accountingUseCase.DeleteConnection(ctx, tenantID); err != nil {
+		h.respondError(w, toHTTPError(err))
+		return
+	}
+
+	h.respondNoContent(w)
+}
+
+// ============================================================================
+// Accounting Sync Handler Methods
+// ============================================================================
+
+// ListAccountingSyncs handles GET /accounting/syncs
+func (h *Handler) ListAccountingSyncs(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	tenantID, err := h.getTenantID(ctx)
+	if err != nil {
+		h.respondError(w, ErrUnauthorized("tenant identification required"))
+		return
+	}
+
+	req := dto.ListAccountingSyncsRequest{
+		Statuses: h.getQueryStringSlice(r, "status"),
+		Limit:    h.getQueryInt(r, "limit", 100),
+	}
+
+	syncs, err := h.accountingUseCase.ListSyncs(ctx, tenantID, &req)
+	if err != nil {
+		h.respondError(w, toHTTPError(err))
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, syncs)
+}
+
+// GetAccountingReconciliation handles GET /accounting/reconciliation
+func (h *Handler) GetAccountingReconciliation(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	tenantID, err := h.getTenantID(ctx)
+	if err != nil {
+		h.respondError(w, ErrUnauthorized("tenant identification required"))
+		return
+	}
+
+	report, err := h.accountingUseCase.GetReconciliation(ctx, tenantID)
+	if err != nil {
+		h.respondError(w, toHTTPError(err))
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, report)
+}
+
+// ResyncAccountingRecord handles POST /accounting/syncs/{syncID}/resync
+func (h *Handler) ResyncAccountingRecord(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	tenantID, err := h.getTenantID(ctx)
+	if err != nil {
+		h.respondError(w, ErrUnauthorized("tenant identification required"))
+		return
+	}
+
+	syncID, err := h.getUUIDParam(r, "syncID")
+	if err != nil {
+		h.respondError(w, err)
+		return
+	}
+
+	sync, err := h.accountingUseCase.Resync(ctx, tenantID, syncID)
+	if err != nil {
+		h.respondError(w, toHTTPError(err))
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, sync)
+}
+
+// ResyncAllAccountingRecords handles POST /accounting/resync
+func (h *Handler) ResyncAllAccountingRecords(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	tenantID, err := h.getTenantID(ctx)
+	if err != nil {
+		h.respondError(w, ErrUnauthorized("tenant identification required"))
+		return
+	}
+
+	result, err := h.accountingUseCase.ResyncAll(ctx, tenantID)
+	if err != nil {
+		h.respondError(w, toHTTPError(err))
+		return
+	}
+
+	h.respondJSON(w, http.StatusAccepted, result)
+}
+
+// SyncInvoiceToAccounting handles POST /deals/{dealID}/invoices/{invoiceID}/accounting-sync
+func (h *Handler) SyncInvoiceToAccounting(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	tenantID, err := h.getTenantID(ctx)
+	if err != nil {
+		h.respondError(w, ErrUnauthorized("tenant identification required"))
+		return
+	}
+
+	dealID, err := h.getUUIDParam(r, "dealID")
+	if err != nil {
+		h.respondError(w, err)
+		return
+	}
+
+	invoiceID, err := h.getUUIDParam(r, "invoiceID")
+	if err != nil {
+		h.respondError(w, err)
+		return
+	}
+
+	sync, err := h.accountingUseCase.ResyncInvoice(ctx, tenantID, dealID, invoiceID)
+	if err != nil {
+		h.respondError(w, toHTTPError(err))
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, sync)
+}
+
+// ReceiveAccountingWebhook handles POST /accounting/webhooks/{tenantID}. The
+// request is verified with the tenant's connection secret.
+func (h *Handler) ReceiveAccountingWebhook(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	tenantID, err := h.getUUIDParam(r, "tenantID")
+	if err != nil {
+		h.respondError(w, err)
+		return
+	}
+
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxAccountingWebhookBytes))
+	if err != nil {
+		h.respondError(w, ErrBadRequest("failed to read webhook"))
+		return
+	}
+
+	headers := make(map[string]string, len(r.Header))
+	for key := range r.Header {
+		headers[key] = r.Header.Get(key)
+	}
+
+	result, err := h.accountingUseCase.ReceiveWebhook(ctx, tenantID, headers, body)
+	if err != nil {
+		h.respondError(w, toHTTPError(err))
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, result)
+}
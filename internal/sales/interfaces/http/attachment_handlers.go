@@ -0,0 +1,138 @@
+package http
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/kilang-desa-murni/crm/internal/sales/application/dto"
+	"github.com/kilang-desa-murni/crm/internal/sales/domain"
+)
+
+// maxAttachmentUploadBytes bounds an upload request, leaving room for the
+// multipart framing around the 10 MiB file limit.
+const maxAttachmentUploadBytes = 11 << 20
+
+// ============================================================================
+// Attachment Handlers
+// ============================================================================
+
+// UploadOpportunityAttachment handles POST /opportunities/{opportunityID}/attachments
+// The file is sent as multipart/form-data in the "file" field.
+func (h *Handler) UploadOpportunityAttachment(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	tenantID, err := h.getTenantID(ctx)
+	if err != nil {
+		h.respondError(w, ErrUnauthorized("tenant context required"))
+		return
+	}
+
+	userID, _ := h.getUserID(ctx)
+
+	opportunityID, err := h.getUUIDParam(r, "opportunityID")
+	if err != nil {
+		h.respondError(w, err)
+		return
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, maxAttachmentUploadBytes)
+	file, header, err := r.FormFile("file")
+	if err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			h.respondError(w, ErrBadRequest("file is too large"))
+			return
+		}
+		h.respondError(w, ErrMissingParameter("file"))
+		return
+	}
+	defer file.Close()
+
+	content, err := io.ReadAll(file)
+	if err != nil {
+		h.respondError(w, ErrBadRequest("failed to read file"))
+		return
+	}
+
+	req := dto.UploadAttachmentRequest{
+		Filename:    header.Filename,
+		ContentType: header.Header.Get("Content-Type"),
+		Content:     content,
+	}
+
+	attachment, err := h.attachmentUseCase.Upload(ctx, tenantID, opportunityID, ptrToUUID(userID), &req)
+	if err != nil {
+		h.respondError(w, h.toError(err))
+		return
+	}
+
+	h.respondSuccess(w, http.StatusCreated, attachment)
+}
+
+// ListOpportunityAttachments handles GET /opportunities/{opportunityID}/attachments
+func (h *Handler) ListOpportunityAttachments(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	tenantID, err := h.getTenantID(ctx)
+	if err != nil {
+		h.respondError(w, ErrUnauthorized("tenant context required"))
+		return
+	}
+
+	opportunityID, err := h.getUUIDParam(r, "opportunityID")
+	if err != nil {
+		h.respondError(w, err)
+		return
+	}
+
+	attachments, err := h.attachmentUseCase.List(ctx, tenantID, opportunityID)
+	if err != nil {
+		h.respondError(w, h.toError(err))
+		return
+	}
+
+	h.respondSuccess(w, http.StatusOK, attachments)
+}
+
+// DownloadOpportunityAttachment handles GET /opportunities/{opportunityID}/attachments/{name}
+// Images can be downloaded as ?size=thumb or ?size=medium.
+func (h *Handler) DownloadOpportunityAttachment(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	tenantID, err := h.getTenantID(ctx)
+	if err != nil {
+		h.respondError(w, ErrUnauthorized("tenant context required"))
+		return
+	}
+
+	opportunityID, err := h.getUUIDParam(r, "opportunityID")
+	if err != nil {
+		h.respondError(w, err)
+		return
+	}
+
+	name := chi.URLParam(r, "name")
+	if name == "" {
+		h.respondError(w, ErrMissingParameter("name"))
+		return
+	}
+
+	file, err := h.attachmentUseCase.Download(ctx, tenantID, opportunityID, name, h.getQueryString(r, "size"))
+	if err != nil {
+		h.respondError(w, h.toError(err))
+		return
+	}
+
+	// Photos are shown in the browser; everything else is saved
+	disposition := "attachment"
+	if domain.IsThumbnailable(file.ContentType) {
+		disposition = "inline"
+	}
+
+	w.Header().Set("Content-Type", file.ContentType)
+	w.Header().Set("Content-Disposition", fmt.Sprintf("%s; filename=%q", disposition, file.FileName))
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(http.StatusOK)
+	w.Write(file.Content)
+}
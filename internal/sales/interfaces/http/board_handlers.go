@@ -0,0 +1,70 @@
+package http
+
+import (
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+
+	"github.com/kilang-desa-murni/crm/internal/sales/application/dto"
+)
+
+// ============================================================================
+// Board Handler Methods
+// ============================================================================
+
+// GetPipelineBoard handles GET /pipelines/{pipelineID}/board
+func (h *Handler) GetPipelineBoard(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	tenantID, err := h.getTenantID(ctx)
+	if err != nil {
+		h.respondError(w, ErrUnauthorized("tenant identification required"))
+		return
+	}
+
+	pipelineIDStr := chi.URLParam(r, "pipelineID")
+	pipelineID, err := uuid.Parse(pipelineIDStr)
+	if err != nil {
+		h.respondError(w, ErrInvalidParameter("pipelineID", "invalid UUID format"))
+		return
+	}
+
+	req := dto.GetBoardRequest{
+		StageID: h.getQueryString(r, "stage_id"),
+		Cursor:  h.getQueryString(r, "cursor"),
+		Limit:   h.getQueryInt(r, "limit", 20),
+	}
+
+	board, err := h.boardUseCase.GetBoard(ctx, tenantID, pipelineID, &req)
+	if err != nil {
+		h.respondError(w, toHTTPError(err))
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, board)
+}
+
+// RebuildPipelineBoard handles POST /pipelines/{pipelineID}/board/rebuild
+func (h *Handler) RebuildPipelineBoard(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	tenantID, err := h.getTenantID(ctx)
+	if err != nil {
+		h.respondError(w, ErrUnauthorized("tenant identification required"))
+		return
+	}
+
+	pipelineIDStr := chi.URLParam(r, "pipelineID")
+	pipelineID, err := uuid.Parse(pipelineIDStr)
+	if err != nil {
+		h.respondError(w, ErrInvalidParameter("pipelineID", "invalid UUID format"))
+		return
+	}
+
+	result, err := h.boardUseCase.RebuildBoard(ctx, tenantID, pipelineID)
+	if err != nil {
+		h.respondError(w, toHTTPError(err))
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, result)
+}
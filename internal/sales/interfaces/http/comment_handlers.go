@@ -0,0 +1,269 @@
+package http
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+
+	"github.com/kilang-desa-murni/crm/internal/sales/application/dto"
+)
+
+// commentEntityTypes maps the collection in a comment route's path to the
+// entity type its records are commented on as.
+var commentEntityTypes = map[string]string{
+	"leads":         "lead",
+	"opportunities": "opportunity",
+	"customers":     "customer",
+}
+
+// commentEntityType returns the entity type of a comment route, from the
+// collection its path starts with.
+func commentEntityType(r *http.Request) string {
+	collection, _, _ := strings.Cut(strings.TrimPrefix(r.URL.Path, "/api/v1/"), "/")
+	return commentEntityTypes[collection]
+}
+
+// ============================================================================
+// Comment Handler Methods
+// ============================================================================
+
+// ListComments handles GET /{entities}/{entityID}/comments
+func (h *Handler) ListComments(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	tenantID, err := h.getTenantID(ctx)
+	if err != nil {
+		h.respondError(w, err)
+		return
+	}
+
+	entityID, err := h.getUUIDParam(r, "entityID")
+	if err != nil {
+		h.respondError(w, err)
+		return
+	}
+
+	req := dto.ListCommentsRequest{
+		Page:     h.getQueryInt(r, "page", 1),
+		PageSize: h.getQueryInt(r, "page_size", 20),
+	}
+
+	comments, err := h.commentUseCase.List(ctx, tenantID, commentEntityType(r), entityID, &req)
+	if err != nil {
+		h.respondError(w, toHTTPError(err))
+		return
+	}
+
+	h.respondList(w, comments.Comments, comments.Pagination)
+}
+
+// CreateComment handles POST /{entities}/{entityID}/comments
+func (h *Handler) CreateComment(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	tenantID, err := h.getTenantID(ctx)
+	if err != nil {
+		h.respondError(w, err)
+		return
+	}
+	userID := h.getUserIDDirect(ctx)
+	if userID == uuid.Nil {
+		h.respondError(w, ErrUnauthorized("user identification required"))
+		return
+	}
+
+	entityID, err := h.getUUIDParam(r, "entityID")
+	if err != nil {
+		h.respondError(w, err)
+		return
+	}
+
+	var req dto.CreateCommentRequest
+	if err := h.decodeJSON(r, &req); err != nil {
+		h.respondError(w, err)
+		return
+	}
+
+	comment, err := h.commentUseCase.Create(ctx, tenantID, userID, commentEntityType(r), entityID, &req)
+	if err != nil {
+		h.respondError(w, toHTTPError(err))
+		return
+	}
+
+	h.respondSuccess(w, http.StatusCreated, comment)
+}
+
+// ListCommentReplies handles GET /{entities}/{entityID}/comments/{commentID}/replies
+func (h *Handler) ListCommentReplies(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	tenantID, err := h.getTenantID(ctx)
+	if err != nil {
+		h.respondError(w, err)
+		return
+	}
+
+	entityID, commentID, err := h.getCommentParams(r)
+	if err != nil {
+		h.respondError(w, err)
+		return
+	}
+
+	req := dto.ListCommentsRequest{
+		Page:     h.getQueryInt(r, "page", 1),
+		PageSize: h.getQueryInt(r, "page_size", 20),
+	}
+
+	replies, err := h.commentUseCase.ListReplies(ctx, tenantID, commentEntityType(r), entityID, commentID, &req)
+	if err != nil {
+		h.respondError(w, toHTTPError(err))
+		return
+	}
+
+	h.respondList(w, replies.Comments, replies.Pagination)
+}
+
+// UpdateComment handles PUT /{entities}/{entityID}/comments/{commentID}
+func (h *Handler) UpdateComment(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	tenantID, err := h.getTenantID(ctx)
+	if err != nil {
+		h.respondError(w, err)
+		return
+	}
+
+	entityID, commentID, err := h.getCommentParams(r)
+	if err != nil {
+		h.respondError(w, err)
+		return
+	}
+
+	var req dto.UpdateCommentRequest
+	if err := h.decodeJSON(r, &req); err != nil {
+		h.respondError(w, err)
+		return
+	}
+
+	comment, err := h.commentUseCase.Update(ctx, tenantID, h.getUserIDDirect(ctx), commentEntityType(r), entityID, commentID, &req)
+	if err != nil {
+		h.respondError(w, toHTTPError(err))
+		return
+	}
+
+	h.respondSuccess(w, http.StatusOK, comment)
+}
+
+// DeleteComment handles DELETE /{entities}/{entityID}/comments/{commentID}.
+// Admins moderate comments, deleting those of other users.
+func (h *Handler) DeleteComment(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	tenantID, err := h.getTenantID(ctx)
+	if err != nil {
+		h.respondError(w, err)
+		return
+	}
+
+	entityID, commentID, err := h.getCommentParams(r)
+	if err != nil {
+		h.respondError(w, err)
+		return
+	}
+
+	moderator := hasAnyRole(ctx, "admin")
+	if err := h.commentUseCase.Delete(ctx, tenantID, h.getUserIDDirect(ctx), commentEntityType(r), entityID, commentID, moderator); err != nil {
+		h.respondError(w, toHTTPError(err))
+		return
+	}
+
+	h.respondNoContent(w)
+}
+
+// GetCommentHistory handles GET /{entities}/{entityID}/comments/{commentID}/history
+func (h *Handler) GetCommentHistory(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	tenantID, err := h.getTenantID(ctx)
+	if err != nil {
+		h.respondError(w, err)
+		return
+	}
+
+	entityID, commentID, err := h.getCommentParams(r)
+	if err != nil {
+		h.respondError(w, err)
+		return
+	}
+
+	history, err := h.commentUseCase.GetHistory(ctx, tenantID, commentEntityType(r), entityID, commentID)
+	if err != nil {
+		h.respondError(w, toHTTPError(err))
+		return
+	}
+
+	h.respondSuccess(w, http.StatusOK, history)
+}
+
+// AddCommentReaction handles POST /{entities}/{entityID}/comments/{commentID}/reactions
+func (h *Handler) AddCommentReaction(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	tenantID, err := h.getTenantID(ctx)
+	if err != nil {
+		h.respondError(w, err)
+		return
+	}
+
+	entityID, commentID, err := h.getCommentParams(r)
+	if err != nil {
+		h.respondError(w, err)
+		return
+	}
+
+	var req dto.CommentReactionRequest
+	if err := h.decodeJSON(r, &req); err != nil {
+		h.respondError(w, err)
+		return
+	}
+
+	comment, err := h.commentUseCase.AddReaction(ctx, tenantID, h.getUserIDDirect(ctx), commentEntityType(r), entityID, commentID, &req)
+	if err != nil {
+		h.respondError(w, toHTTPError(err))
+		return
+	}
+
+	h.respondSuccess(w, http.StatusOK, comment)
+}
+
+// RemoveCommentReaction handles DELETE /{entities}/{entityID}/comments/{commentID}/reactions/{reaction}
+func (h *Handler) RemoveCommentReaction(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	tenantID, err := h.getTenantID(ctx)
+	if err != nil {
+		h.respondError(w, err)
+		return
+	}
+
+	entityID, commentID, err := h.getCommentParams(r)
+	if err != nil {
+		h.respondError(w, err)
+		return
+	}
+
+	comment, err := h.commentUseCase.RemoveReaction(ctx, tenantID, h.getUserIDDirect(ctx), commentEntityType(r), entityID, commentID, chi.URLParam(r, "reaction"))
+	if err != nil {
+		h.respondError(w, toHTTPError(err))
+		return
+	}
+
+	h.respondSuccess(w, http.StatusOK, comment)
+}
+
+// getCommentParams returns the record and comment IDs of a comment route.
+func (h *Handler) getCommentParams(r *http.Request) (uuid.UUID, uuid.UUID, error) {
+	entityID, err := h.getUUIDParam(r, "entityID")
+	if err != nil {
+		return uuid.Nil, uuid.Nil, err
+	}
+	commentID, err := h.getUUIDParam(r, "commentID")
+	if err != nil {
+		return uuid.Nil, uuid.Nil, err
+	}
+	return entityID, commentID, nil
+}
@@ -658,6 +658,48 @@ func (h *Handler) ResumeDeal(w http.ResponseWriter, r *http.Request) {
 	h.respondJSON(w, http.StatusOK, deal)
 }
 
+// ChurnDeal handles POST /deals/{dealID}/churn
+func (h *Handler) ChurnDeal(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	tenantID, err := h.getTenantID(ctx)
+	if err != nil {
+		h.respondError(w, ErrUnauthorized("tenant identification required"))
+		return
+	}
+
+	userIDPtr, _ := h.getUserID(ctx)
+	if userIDPtr == nil {
+		h.respondError(w, ErrUnauthorized("user identification required"))
+		return
+	}
+	userID := *userIDPtr
+
+	dealIDStr := chi.URLParam(r, "dealID")
+	dealID, err := uuid.Parse(dealIDStr)
+	if err != nil {
+		h.respondError(w, ErrInvalidParameter("dealID", "invalid UUID format"))
+		return
+	}
+
+	var req dto.ChurnDealRequest
+	if err := h.decodeJSON(r, &req); err != nil {
+		h.respondError(w, ErrInvalidJSON(err.Error()))
+		return
+	}
+	if req.Reason == "" {
+		h.respondError(w, ErrMissingParameter("reason"))
+		return
+	}
+
+	deal, err := h.dealUseCase.Churn(ctx, tenantID, dealID, userID, &req)
+	if err != nil {
+		h.respondError(w, toHTTPError(err))
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, deal)
+}
+
 // ============================================================================
 // Statistics
 // ============================================================================
@@ -680,6 +722,56 @@ func (h *Handler) GetDealStatistics(w http.ResponseWriter, r *http.Request) {
 	h.respondJSON(w, http.StatusOK, stats)
 }
 
+// GetRecurringRevenue handles GET /deals/recurring-revenue
+func (h *Handler) GetRecurringRevenue(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	tenantID, err := h.getTenantID(ctx)
+	if err != nil {
+		h.respondError(w, ErrUnauthorized("tenant identification required"))
+		return
+	}
+
+	req := dto.RecurringRevenueRequest{
+		From:     h.getQueryString(r, "from"),
+		To:       h.getQueryString(r, "to"),
+		Currency: h.getQueryString(r, "currency"),
+	}
+	if req.From == "" {
+		h.respondError(w, ErrMissingParameter("from"))
+		return
+	}
+	if req.To == "" {
+		h.respondError(w, ErrMissingParameter("to"))
+		return
+	}
+
+	report, err := h.dealUseCase.GetRecurringRevenue(ctx, tenantID, &req)
+	if err != nil {
+		h.respondError(w, toHTTPError(err))
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, report)
+}
+
+// GetUpcomingRenewals handles GET /deals/renewals
+func (h *Handler) GetUpcomingRenewals(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	tenantID, err := h.getTenantID(ctx)
+	if err != nil {
+		h.respondError(w, ErrUnauthorized("tenant identification required"))
+		return
+	}
+
+	renewals, err := h.dealUseCase.GetUpcomingRenewals(ctx, tenantID, h.getQueryInt(r, "days", 0), h.getQueryString(r, "currency"))
+	if err != nil {
+		h.respondError(w, toHTTPError(err))
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, renewals)
+}
+
 // GetDealsByOwner handles GET /deals/by-owner/{ownerID}
 func (h *Handler) GetDealsByOwner(w http.ResponseWriter, r *http.Request) {
 	h.respondError(w, ErrUnprocessableEntity("deals by owner endpoint not yet implemented"))
@@ -833,11 +925,21 @@ func (h *Handler) parseDealFilter(r *http.Request) *dto.DealFilterRequest {
 			filter.PageSize = ps
 		}
 	}
+	if limit := q.Get("limit"); limit != "" {
+		if l, err := parseInt(limit); err == nil && l > 0 {
+			filter.PageSize = l
+		}
+	}
+	filter.Cursor = q.Get("cursor")
 
 	// Parse sorting
 	filter.SortBy = q.Get("sort_by")
 	filter.SortOrder = q.Get("sort_order")
 
+	// Parse filter and sort expressions
+	filter.Filter = q.Get("filter")
+	filter.Sort = q.Get("sort")
+
 	return filter
 }
 
@@ -0,0 +1,54 @@
+package http
+
+import (
+	"net/http"
+
+	"github.com/google/uuid"
+)
+
+// ============================================================================
+// Demo Data Handler Methods
+// ============================================================================
+
+// SeedDemoData handles POST /internal/tenants/{tenantID}/demo-data, seeding
+// the tenant's demo pipeline, leads and opportunities for the IAM service.
+// The seeded_by query parameter names the user recorded as their creator.
+func (h *Handler) SeedDemoData(w http.ResponseWriter, r *http.Request) {
+	tenantID, err := h.getUUIDParam(r, "tenantID")
+	if err != nil {
+		h.respondError(w, err)
+		return
+	}
+
+	seededBy, err := uuid.Parse(r.URL.Query().Get("seeded_by"))
+	if err != nil {
+		h.respondError(w, ErrInvalidParameter("seeded_by", "must be a valid UUID"))
+		return
+	}
+
+	result, err := h.demoDataUseCase.Seed(r.Context(), tenantID, seededBy)
+	if err != nil {
+		h.respondError(w, toHTTPError(err))
+		return
+	}
+
+	h.respondSuccess(w, http.StatusOK, result)
+}
+
+// PurgeDemoData handles DELETE /internal/tenants/{tenantID}/demo-data,
+// deleting the tenant's seeded demo records for the IAM service.
+func (h *Handler) PurgeDemoData(w http.ResponseWriter, r *http.Request) {
+	tenantID, err := h.getUUIDParam(r, "tenantID")
+	if err != nil {
+		h.respondError(w, err)
+		return
+	}
+
+	result, err := h.demoDataUseCase.Purge(r.Context(), tenantID)
+	if err != nil {
+		h.respondError(w, toHTTPError(err))
+		return
+	}
+
+	h.respondSuccess(w, http.StatusOK, result)
+}
@@ -0,0 +1,300 @@
+package http
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/google/uuid"
+
+	"github.com/kilang-desa-murni/crm/internal/sales/application/dto"
+)
+
+// ============================================================================
+// Discount Approval Rule Handler Methods
+// ============================================================================
+
+// ListDiscountApprovalRules handles GET /discount-approval-rules
+func (h *Handler) ListDiscountApprovalRules(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	tenantID, err := h.getTenantID(ctx)
+	if err != nil {
+		h.respondError(w, ErrUnauthorized("tenant identification required"))
+		return
+	}
+
+	rules, err := h.discountApprovalUseCase.ListRules(ctx, tenantID)
+	if err != nil {
+		h.respondError(w, toHTTPError(err))
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, rules)
+}
+
+// CreateDiscountApprovalRule handles POST /discount-approval-rules
+func (h *Handler) CreateDiscountApprovalRule(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	tenantID, err := h.getTenantID(ctx)
+	if err != nil {
+		h.respondError(w, ErrUnauthorized("tenant identification required"))
+		return
+	}
+
+	var req dto.CreateDiscountApprovalRuleRequest
+	if err := h.decodeJSON(r, &req); err != nil {
+		h.respondError(w, ErrInvalidJSON(err.Error()))
+		return
+	}
+
+	rule, err := h.discountApprovalUseCase.CreateRule(ctx, tenantID, &req)
+	if err != nil {
+		h.respondError(w, toHTTPError(err))
+		return
+	}
+
+	h.respondJSON(w, http.StatusCreated, rule)
+}
+
+// GetDiscountApprovalRule handles GET /discount-approval-rules/{ruleID}
+func (h *Handler) GetDiscountApprovalRule(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	tenantID, err := h.getTenantID(ctx)
+	if err != nil {
+		h.respondError(w, ErrUnauthorized("tenant identification required"))
+		return
+	}
+
+	ruleID, err := h.getUUIDParam(r, "ruleID")
+	if err != nil {
+		h.respondError(w, err)
+		return
+	}
+
+	rule, err := h.discountApprovalUseCase.GetRule(ctx, tenantID, ruleID)
+	if err != nil {
+		h.respondError(w, toHTTPError(err))
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, rule)
+}
+
+// UpdateDiscountApprovalRule handles PUT /discount-approval-rules/{ruleID}
+func (h *Handler) UpdateDiscountApprovalRule(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	tenantID, err := h.getTenantID(ctx)
+	if err != nil {
+		h.respondError(w, ErrUnauthorized("tenant identification required"))
+		return
+	}
+
+	ruleID, err := h.getUUIDParam(r, "ruleID")
+	if err != nil {
+		h.respondError(w, err)
+		return
+	}
+
+	var req dto.UpdateDiscountApprovalRuleRequest
+	if err := h.decodeJSON(r, &req); err != nil {
+		h.respondError(w, ErrInvalidJSON(err.Error()))
+		return
+	}
+
+	rule, err := h.discountApprovalUseCase.UpdateRule(ctx, tenantID, ruleID, &req)
+	if err != nil {
+		h.respondError(w, toHTTPError(err))
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, rule)
+}
+
+// DeleteDiscountApprovalRule handles DELETE /discount-approval-rules/{ruleID}
+func (h *Handler) DeleteDiscountApprovalRule(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	tenantID, err := h.getTenantID(ctx)
+	if err != nil {
+		h.respondError(w, ErrUnauthorized("tenant identification required"))
+		return
+	}
+
+	ruleID, err := h.getUUIDParam(r, "ruleID")
+	if err != nil {
+		h.respondError(w, err)
+		return
+	}
+
+	if err := h.discountApprovalUseCase.DeleteRule(ctx, tenantID, ruleID); err != nil {
+		h.respondError(w, toHTTPError(err))
+		return
+	}
+
+	h.respondNoContent(w)
+}
+
+// ============================================================================
+// Discount Approval Handler Methods
+// ============================================================================
+
+// SubmitDiscountApproval handles POST /opportunities/{opportunityID}/discount-approvals
+func (h *Handler) SubmitDiscountApproval(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	tenantID, err := h.getTenantID(ctx)
+	if err != nil {
+		h.respondError(w, ErrUnauthorized("tenant identification required"))
+		return
+	}
+
+	userID, _ := h.getUserID(ctx)
+	if userID == nil {
+		h.respondError(w, ErrUnauthorized("user identification required"))
+		return
+	}
+
+	opportunityID, err := h.getUUIDParam(r, "opportunityID")
+	if err != nil {
+		h.respondError(w, err)
+		return
+	}
+
+	var req dto.SubmitDiscountApprovalRequest
+	if err := h.decodeJSON(r, &req); err != nil {
+		h.respondError(w, ErrInvalidJSON(err.Error()))
+		return
+	}
+
+	approval, err := h.discountApprovalUseCase.Submit(ctx, tenantID, opportunityID, *userID, &req)
+	if err != nil {
+		h.respondError(w, toHTTPError(err))
+		return
+	}
+
+	h.respondJSON(w, http.StatusCreated, approval)
+}
+
+// ListOpportunityDiscountApprovals handles GET /opportunities/{opportunityID}/discount-approvals
+func (h *Handler) ListOpportunityDiscountApprovals(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	tenantID, err := h.getTenantID(ctx)
+	if err != nil {
+		h.respondError(w, ErrUnauthorized("tenant identification required"))
+		return
+	}
+
+	opportunityID, err := h.getUUIDParam(r, "opportunityID")
+	if err != nil {
+		h.respondError(w, err)
+		return
+	}
+
+	approvals, err := h.discountApprovalUseCase.ListByOpportunity(ctx, tenantID, opportunityID)
+	if err != nil {
+		h.respondError(w, toHTTPError(err))
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, approvals)
+}
+
+// ListPendingDiscountApprovals handles GET /discount-approvals, the current
+// user's approvals to decide.
+func (h *Handler) ListPendingDiscountApprovals(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	tenantID, err := h.getTenantID(ctx)
+	if err != nil {
+		h.respondError(w, ErrUnauthorized("tenant identification required"))
+		return
+	}
+
+	userID, _ := h.getUserID(ctx)
+	if userID == nil {
+		h.respondError(w, ErrUnauthorized("user identification required"))
+		return
+	}
+
+	req := dto.ListDiscountApprovalsRequest{
+		Page:     h.getQueryInt(r, "page", 1),
+		PageSize: h.getQueryInt(r, "page_size", 20),
+	}
+
+	approvals, err := h.discountApprovalUseCase.ListPending(ctx, tenantID, *userID, &req)
+	if err != nil {
+		h.respondError(w, toHTTPError(err))
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, approvals)
+}
+
+// GetDiscountApproval handles GET /discount-approvals/{approvalID}
+func (h *Handler) GetDiscountApproval(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	tenantID, err := h.getTenantID(ctx)
+	if err != nil {
+		h.respondError(w, ErrUnauthorized("tenant identification required"))
+		return
+	}
+
+	approvalID, err := h.getUUIDParam(r, "approvalID")
+	if err != nil {
+		h.respondError(w, err)
+		return
+	}
+
+	approval, err := h.discountApprovalUseCase.GetByID(ctx, tenantID, approvalID)
+	if err != nil {
+		h.respondError(w, toHTTPError(err))
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, approval)
+}
+
+// ApproveDiscount handles POST /discount-approvals/{approvalID}/approve
+func (h *Handler) ApproveDiscount(w http.ResponseWriter, r *http.Request) {
+	h.decideDiscount(w, r, h.discountApprovalUseCase.Approve)
+}
+
+// RejectDiscount handles POST /discount-approvals/{approvalID}/reject
+func (h *Handler) RejectDiscount(w http.ResponseWriter, r *http.Request) {
+	h.decideDiscount(w, r, h.discountApprovalUseCase.Reject)
+}
+
+func (h *Handler) decideDiscount(
+	w http.ResponseWriter,
+	r *http.Request,
+	decide func(ctx context.Context, tenantID, approvalID, userID uuid.UUID, req *dto.DecideDiscountApprovalRequest) (*dto.DiscountApprovalResponse, error),
+) {
+	ctx := r.Context()
+	tenantID, err := h.getTenantID(ctx)
+	if err != nil {
+		h.respondError(w, ErrUnauthorized("tenant identification required"))
+		return
+	}
+
+	userID, _ := h.getUserID(ctx)
+	if userID == nil {
+		h.respondError(w, ErrUnauthorized("user identification required"))
+		return
+	}
+
+	approvalID, err := h.getUUIDParam(r, "approvalID")
+	if err != nil {
+		h.respondError(w, err)
+		return
+	}
+
+	var req dto.DecideDiscountApprovalRequest
+	if err := h.decodeJSON(r, &req); err != nil {
+		h.respondError(w, ErrInvalidJSON(err.Error()))
+		return
+	}
+
+	approval, err := decide(ctx, tenantID, approvalID, *userID, &req)
+	if err != nil {
+		h.respondError(w, toHTTPError(err))
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, approval)
+}
@@ -0,0 +1,252 @@
+package http
+
+import (
+	"io"
+	"net/http"
+
+	"github.com/kilang-desa-murni/crm/internal/sales/application/dto"
+)
+
+// maxEcommerceWebhookBytes bounds the body of a store webhook.
+const maxEcommerceWebhookBytes = 2 << 20
+
+// ============================================================================
+// E-Commerce Store Handler Methods
+// ============================================================================
+
+// ListEcommerceStores handles GET /ecommerce/stores
+func (h *Handler) ListEcommerceStores(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	tenantID, err := h.getTenantID(ctx)
+	if err != nil {
+		h.respondError(w, ErrUnauthorized("tenant identification required"))
+		return
+	}
+
+	stores, err := h.ecommerceUseCase.ListStores(ctx, tenantID)
+	if err != nil {
+		h.respondError(w, toHTTPError(err))
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, stores)
+}
+
+// CreateEcommerceStore handles POST /ecommerce/stores
+func (h *Handler) CreateEcommerceStore(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	tenantID, err := h.getTenantID(ctx)
+	if err != nil {
+		h.respondError(w, ErrUnauthorized("tenant identification required"))
+		return
+	}
+
+	userID, _ := h.getUserID(ctx)
+	if userID == nil {
+		h.respondError(w, ErrUnauthorized("user identification required"))
+		return
+	}
+
+	var req dto.CreateEcommerceStoreRequest
+	if err := h.decodeJSON(r, &req); err != nil {
+		h.respondError(w, ErrInvalidJSON(err.Error()))
+		return
+	}
+
+	store, err := h.ecommerceUseCase.CreateStore(ctx, tenantID, *userID, &req)
+	if err != nil {
+		h.respondError(w, toHTTPError(err))
+		return
+	}
+
+	h.respondJSON(w, http.StatusCreated, store)
+}
+
+// GetEcommerceStore handles GET /ecommerce/stores/{storeID}
+func (h *Handler) GetEcommerceStore(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	tenantID, err := h.getTenantID(ctx)
+	if err != nil {
+		h.respondError(w, ErrUnauthorized("tenant identification required"))
+		return
+	}
+
+	storeID, err := h.getUUIDParam(r, "storeID")
+	if err != nil {
+		h.respondError(w, err)
+		return
+	}
+
+	store, err := h.ecommerceUseCase.GetStore(ctx, tenantID, storeID)
+	if err != nil {
+		h.respondError(w, toHTTPError(err))
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, store)
+}
+
+// UpdateEcommerceStore handles PUT /ecommerce/stores/{storeID}
+func (h *Handler) UpdateEcommerceStore(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	tenantID, err := h.getTenantID(ctx)
+	if err != nil {
+		h.respondError(w, ErrUnauthorized("tenant identification required"))
+		return
+	}
+
+	storeID, err := h.getUUIDParam(r, "storeID")
+	if err != nil {
+		h.respondError(w, err)
+		return
+	}
+
+	var req dto.UpdateEcommerceStoreRequest
+	if err := h.decodeJSON(r, &req); err != nil {
+		h.respondError(w, ErrInvalidJSON(err.Error()))
+		return
+	}
+
+	store, err := h.ecommerceUseCase.UpdateStore(ctx, tenantID, storeID, &req)
+	if err != nil {
+		h.respondError(w, toHTTPError(err))
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, store)
+}
+
+// DeleteEcommerceStore handles DELETE /ecommerce/stores/{storeID}
+func (h *Handler) DeleteEcommerceStore(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	tenantID, err := h.getTenantID(ctx)
+	if err != nil {
+		h.respondError(w, ErrUnauthorized("tenant identification required"))
+		return
+	}
+
+	storeID, err := h.getUUIDParam(r, "storeID")
+	if err != nil {
+		h.respondError(w, err)
+		return
+	}
+
+	if err := h.ecommerceUseCase.DeleteStore(ctx, tenantID, storeID); err != nil {
+		h.respondError(w, toHTTPError(err))
+		return
+	}
+
+	h.respondNoContent(w)
+}
+
+// ============================================================================
+// E-Commerce Sync Handler Methods
+// ============================================================================
+
+// GetEcommerceSyncStatus handles GET /ecommerce/stores/{storeID}/status
+func (h *Handler) GetEcommerceSyncStatus(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	tenantID, err := h.getTenantID(ctx)
+	if err != nil {
+		h.respondError(w, ErrUnauthorized("tenant identification required"))
+		return
+	}
+
+	storeID, err := h.getUUIDParam(r, "storeID")
+	if err != nil {
+		h.respondError(w, err)
+		return
+	}
+
+	status, err := h.ecommerceUseCase.GetSyncStatus(ctx, tenantID, storeID)
+	if err != nil {
+		h.respondError(w, toHTTPError(err))
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, status)
+}
+
+// ListEcommerceImports handles GET /ecommerce/stores/{storeID}/imports
+func (h *Handler) ListEcommerceImports(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	tenantID, err := h.getTenantID(ctx)
+	if err != nil {
+		h.respondError(w, ErrUnauthorized("tenant identification required"))
+		return
+	}
+
+	storeID, err := h.getUUIDParam(r, "storeID")
+	if err != nil {
+		h.respondError(w, err)
+		return
+	}
+
+	req := dto.ListEcommerceImportsRequest{
+		Statuses: h.getQueryStringSlice(r, "status"),
+		Limit:    h.getQueryInt(r, "limit", 100),
+	}
+
+	imports, err := h.ecommerceUseCase.ListImports(ctx, tenantID, storeID, &req)
+	if err != nil {
+		h.respondError(w, toHTTPError(err))
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, imports)
+}
+
+// SyncEcommerceStore handles POST /ecommerce/stores/{storeID}/sync
+func (h *Handler) SyncEcommerceStore(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	tenantID, err := h.getTenantID(ctx)
+	if err != nil {
+		h.respondError(w, ErrUnauthorized("tenant identification required"))
+		return
+	}
+
+	storeID, err := h.getUUIDParam(r, "storeID")
+	if err != nil {
+		h.respondError(w, err)
+		return
+	}
+
+	result, err := h.ecommerceUseCase.SyncStore(ctx, tenantID, storeID)
+	if err != nil {
+		h.respondError(w, toHTTPError(err))
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, result)
+}
+
+// ReceiveEcommerceWebhook handles POST /ecommerce/webhooks/{storeID}. The
+// request is verified with the store's webhook secret.
+func (h *Handler) ReceiveEcommerceWebhook(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	storeID, err := h.getUUIDParam(r, "storeID")
+	if err != nil {
+		h.respondError(w, err)
+		return
+	}
+
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxEcommerceWebhookBytes))
+	if err != nil {
+		h.respondError(w, ErrBadRequest("failed to read webhook"))
+		return
+	}
+
+	headers := make(map[string]string, len(r.Header))
+	for key := range r.Header {
+		headers[key] = r.Header.Get(key)
+	}
+
+	result, err := h.ecommerceUseCase.ReceiveWebhook(ctx, storeID, headers, body)
+	if err != nil {
+		h.respondError(w, toHTTPError(err))
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, result)
+}
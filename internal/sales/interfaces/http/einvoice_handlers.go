@@ -0,0 +1,201 @@
+package http
+
+import (
+	"net/http"
+
+	"github.com/kilang-desa-murni/crm/internal/sales/application/dto"
+)
+
+// ============================================================================
+// E-Invoice Settings Handler Methods
+// ============================================================================
+
+// GetEInvoiceSettings handles GET /einvoice/settings
+func (h *Handler) GetEInvoiceSettings(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	tenantID, err := h.getTenantID(ctx)
+	if err != nil {
+		h.respondError(w, ErrUnauthorized("tenant identification required"))
+		return
+	}
+
+	settings, err := h.einvoiceUseCase.GetSettings(ctx, tenantID)
+	if err != nil {
+		h.respondError(w, toHTTPError(err))
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, settings)
+}
+
+// UpdateEInvoiceSettings handles PUT /einvoice/settings
+func (h *Handler) UpdateEInvoiceSettings(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	tenantID, err := h.getTenantID(ctx)
+	if err != nil {
+		h.respondError(w, ErrUnauthorized("tenant identification required"))
+		return
+	}
+
+	var req dto.UpdateEInvoiceSettingsRequest
+	if err := h.decodeJSON(r, &req); err != nil {
+		h.respondError(w, ErrInvalidJSON(err.Error()))
+		return
+	}
+
+	settings, err := h.einvoiceUseCase.UpdateSettings(ctx, tenantID, &req)
+	if err != nil {
+		h.respondError(w, toHTTPError(err))
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, settings)
+}
+
+// ============================================================================
+// Invoice E-Invoice Handler Methods
+// ============================================================================
+
+// SubmitEInvoice handles POST /deals/{dealID}/invoices/{invoiceID}/einvoice
+func (h *Handler) SubmitEInvoice(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	tenantID, err := h.getTenantID(ctx)
+	if err != nil {
+		h.respondError(w, ErrUnauthorized("tenant identification required"))
+		return
+	}
+
+	userID, _ := h.getUserID(ctx)
+	if userID == nil {
+		h.respondError(w, ErrUnauthorized("user identification required"))
+		return
+	}
+
+	dealID, err := h.getUUIDParam(r, "dealID")
+	if err != nil {
+		h.respondError(w, err)
+		return
+	}
+
+	invoiceID, err := h.getUUIDParam(r, "invoiceID")
+	if err != nil {
+		h.respondError(w, err)
+		return
+	}
+
+	var req dto.SubmitEInvoiceRequest
+	if err := h.decodeJSON(r, &req); err != nil {
+		h.respondError(w, ErrInvalidJSON(err.Error()))
+		return
+	}
+
+	einvoice, err := h.einvoiceUseCase.Submit(ctx, tenantID, dealID, invoiceID, *userID, &req)
+	if err != nil {
+		h.respondError(w, toHTTPError(err))
+		return
+	}
+
+	h.respondJSON(w, http.StatusCreated, einvoice)
+}
+
+// GetEInvoice handles GET /deals/{dealID}/invoices/{invoiceID}/einvoice
+func (h *Handler) GetEInvoice(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	tenantID, err := h.getTenantID(ctx)
+	if err != nil {
+		h.respondError(w, ErrUnauthorized("tenant identification required"))
+		return
+	}
+
+	dealID, err := h.getUUIDParam(r, "dealID")
+	if err != nil {
+		h.respondError(w, err)
+		return
+	}
+
+	invoiceID, err := h.getUUIDParam(r, "invoiceID")
+	if err != nil {
+		h.respondError(w, err)
+		return
+	}
+
+	einvoice, err := h.einvoiceUseCase.Get(ctx, tenantID, dealID, invoiceID)
+	if err != nil {
+		h.respondError(w, toHTTPError(err))
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, einvoice)
+}
+
+// ResubmitEInvoice handles POST /deals/{dealID}/invoices/{invoiceID}/einvoice/resubmit
+func (h *Handler) ResubmitEInvoice(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	tenantID, err := h.getTenantID(ctx)
+	if err != nil {
+		h.respondError(w, ErrUnauthorized("tenant identification required"))
+		return
+	}
+
+	dealID, err := h.getUUIDParam(r, "dealID")
+	if err != nil {
+		h.respondError(w, err)
+		return
+	}
+
+	invoiceID, err := h.getUUIDParam(r, "invoiceID")
+	if err != nil {
+		h.respondError(w, err)
+		return
+	}
+
+	var req dto.ResubmitEInvoiceRequest
+	if err := h.decodeJSON(r, &req); err != nil {
+		h.respondError(w, ErrInvalidJSON(err.Error()))
+		return
+	}
+
+	einvoice, err := h.einvoiceUseCase.Resubmit(ctx, tenantID, dealID, invoiceID, &req)
+	if err != nil {
+		h.respondError(w, toHTTPError(err))
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, einvoice)
+}
+
+// CancelEInvoice handles POST /deals/{dealID}/invoices/{invoiceID}/einvoice/cancel
+func (h *Handler) CancelEInvoice(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	tenantID, err := h.getTenantID(ctx)
+	if err != nil {
+		h.respondError(w, ErrUnauthorized("tenant identification required"))
+		return
+	}
+
+	dealID, err := h.getUUIDParam(r, "dealID")
+	if err != nil {
+		h.respondError(w, err)
+		return
+	}
+
+	invoiceID, err := h.getUUIDParam(r, "invoiceID")
+	if err != nil {
+		h.respondError(w, err)
+		return
+	}
+
+	var req dto.CancelEInvoiceRequest
+	if err := h.decodeJSON(r, &req); err != nil {
+		h.respondError(w, ErrInvalidJSON(err.Error()))
+		return
+	}
+
+	einvoice, err := h.einvoiceUseCase.Cancel(ctx, tenantID, dealID, invoiceID, &req)
+	if err != nil {
+		h.respondError(w, toHTTPError(err))
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, einvoice)
+}
@@ -313,6 +313,74 @@ func toHTTPError(err error) *ErrorResponse {
 		return ErrBadRequest("amount cannot be negative")
 	}
 
+	// Check for domain errors - Reports
+	if errors.Is(err, domain.ErrInvalidReportGroupBy) {
+		return ErrBadRequest("invalid report group by")
+	}
+	if errors.Is(err, domain.ErrInvalidReportPeriod) {
+		return ErrBadRequest("invalid report period")
+	}
+	if errors.Is(err, domain.ErrReportPeriodTooLong) {
+		return ErrBadRequest("report period exceeds maximum range")
+	}
+	if errors.Is(err, domain.ErrReportExportNotFound) {
+		return ErrNotFound("report export")
+	}
+	if errors.Is(err, domain.ErrInvalidReportExportFormat) {
+		return ErrBadRequest("invalid report export format")
+	}
+
+	// Check for domain errors - Presence
+	if errors.Is(err, domain.ErrInvalidPresenceRecordType) {
+		return ErrNotFound("presence record type")
+	}
+	if errors.Is(err, domain.ErrInvalidPresenceMode) {
+		return ErrBadRequest("presence mode must be viewing or editing")
+	}
+	if errors.Is(err, domain.ErrPresenceNotFound) {
+		return ErrNotFound("presence connection")
+	}
+	if errors.Is(err, domain.ErrPresenceRoomFull) {
+		return ErrConflict("too many collaborators on the record")
+	}
+
+	// Check for domain errors - Exchange rates
+	if errors.Is(err, domain.ErrExchangeRateNotFound) {
+		return ErrNotFound("exchange rate")
+	}
+	if errors.Is(err, domain.ErrInvalidExchangeRate) {
+		return ErrBadRequest("exchange rate must be a positive number")
+	}
+	if errors.Is(err, domain.ErrSameCurrencyExchangeRate) {
+		return ErrBadRequest("exchange rate currencies must differ")
+	}
+	if errors.Is(err, domain.ErrInvalidExchangeRateSource) {
+		return ErrBadRequest("invalid exchange rate source")
+	}
+
+	// Check for domain errors - Tax
+	if errors.Is(err, domain.ErrTaxRateNotFound) {
+		return ErrUnprocessableEntity("tax code is not configured")
+	}
+	if errors.Is(err, domain.ErrInvalidTaxRate) {
+		return ErrBadRequest("tax rate must be between 0 and 100 percent")
+	}
+	if errors.Is(err, domain.ErrInvalidTaxCode) {
+		return ErrBadRequest("tax code is required")
+	}
+	if errors.Is(err, domain.ErrInvalidTaxType) {
+		return ErrBadRequest("invalid tax type")
+	}
+	if errors.Is(err, domain.ErrDuplicateTaxCode) {
+		return ErrBadRequest("duplicate tax code")
+	}
+	if errors.Is(err, domain.ErrMultipleDefaultTaxRates) {
+		return ErrBadRequest("only one tax rate can be the default")
+	}
+	if errors.Is(err, domain.ErrTaxRegistrationRequired) {
+		return ErrBadRequest("tax registration number is required when tax is enabled")
+	}
+
 	// Default to internal server error
 	return ErrInternalServer("an unexpected error occurred")
 }
@@ -337,6 +405,7 @@ func mapAppError(err *application.AppError) *ErrorResponse {
 		application.ErrCodeDealNotFound,
 		application.ErrCodePipelineNotFound,
 		application.ErrCodePipelineStageNotFound,
+		application.ErrCodePipelineVersionNotFound,
 		application.ErrCodeProductNotFound,
 		application.ErrCodeContactNotFound,
 		application.ErrCodeCustomerNotFound,
@@ -345,7 +414,24 @@ func mapAppError(err *application.AppError) *ErrorResponse {
 		application.ErrCodeDealLineItemNotFound,
 		application.ErrCodeOpportunityProductNotFound,
 		application.ErrCodeOpportunityContactNotFound,
-		application.ErrCodeUserNotFound:
+		application.ErrCodeUserNotFound,
+		application.ErrCodeExchangeRateNotFound,
+		application.ErrCodeOpportunityReasonNotFound,
+		application.ErrCodeAttachmentNotFound,
+		application.ErrCodeInboundMailboxNotFound,
+		application.ErrCodeRetentionPolicyNotFound,
+		application.ErrCodeArchivedRecordNotFound,
+		application.ErrCodeDiscountApprovalRuleNotFound,
+		application.ErrCodeDiscountApprovalNotFound,
+		application.ErrCodeCommentNotFound,
+		application.ErrCodeOrderNotFound,
+		application.ErrCodeOrderWebhookNotFound,
+		application.ErrCodeShipmentNotFound,
+		application.ErrCodeCarrierNotSupported,
+		application.ErrCodeEInvoiceNotFound,
+		application.ErrCodeAccountingNotConnected,
+		application.ErrCodeAccountingSyncNotFound,
+		application.ErrCodeEcommerceStoreNotFound:
 		return ErrNotFound(err.Message)
 
 	// Conflict/Already exists errors
@@ -366,21 +452,44 @@ func mapAppError(err *application.AppError) *ErrorResponse {
 		application.ErrCodePipelineStageDuplicate,
 		application.ErrCodeOpportunityContactDuplicate,
 		application.ErrCodeOpportunityProductDuplicate,
+		application.ErrCodeOpportunityReasonAlreadyExists,
+		application.ErrCodeArchivedRecordRestored,
+		application.ErrCodeDiscountApprovalDecided,
+		application.ErrCodeCommentDeleted,
+		application.ErrCodeAttachmentQuarantined,
+		application.ErrCodeOrderAlreadyExists,
+		application.ErrCodeEInvoiceAlreadyExists,
 		application.ErrCodeVersionMismatch,
 		application.ErrCodeConcurrentModification:
 		return ErrConflict(err.Message)
 
+	// Potential duplicates are answered with the matches, see CreateLead
+	case application.ErrCodeLeadPotentialDuplicate:
+		return &ErrorResponse{
+			StatusCode: http.StatusConflict,
+			Code:       string(err.Code),
+			Message:    err.Message,
+		}
+
 	// Business rule violations
 	case application.ErrCodeLeadNotQualified,
 		application.ErrCodeLeadInvalidStatus,
 		application.ErrCodeLeadInvalidTransition,
 		application.ErrCodeOpportunityInvalidStatus,
 		application.ErrCodeOpportunityInvalidTransition,
+		application.ErrCodeOpportunityReasonInvalid,
+		application.ErrCodeOpportunityReasonRequired,
 		application.ErrCodeDealInvalidStatus,
 		application.ErrCodeDealInvalidTransition,
 		application.ErrCodeDealPaymentExceedsBalance,
 		application.ErrCodeDealFulfillmentExceeds,
 		application.ErrCodeDealCannotCancel,
+		application.ErrCodeDealNotRecurring,
+		application.ErrCodeDiscountApprovalNotRequired,
+		application.ErrCodeOrderInvalidTransition,
+		application.ErrCodeShipmentInvalidTransition,
+		application.ErrCodeEInvoiceInvalidTransition,
+		application.ErrCodeEInvoiceNotEnabled,
 		application.ErrCodePipelineInactive,
 		application.ErrCodePipelineStageInactive,
 		application.ErrCodePipelineHasOpportunities,
@@ -393,9 +502,37 @@ func mapAppError(err *application.AppError) *ErrorResponse {
 		application.ErrCodePipelineStageCannotDelete,
 		application.ErrCodePipelineInvalidStageOrder,
 		application.ErrCodeCurrencyMismatch,
-		application.ErrCodeCurrencyInvalid:
+		application.ErrCodeCurrencyInvalid,
+		application.ErrCodeTaxRateNotFound:
 		return ErrUnprocessableEntity(err.Message)
 
+	// Clients offer managers an override when a move breaks a stage's rules,
+	// so stage rule violations keep their code
+	case application.ErrCodePipelineStageWIPLimitReached,
+		application.ErrCodePipelineStageEntryCriteriaNotMet:
+		resp := &ErrorResponse{
+			StatusCode: http.StatusUnprocessableEntity,
+			Code:       string(err.Code),
+			Message:    err.Message,
+		}
+		if stageID, ok := err.Details["stage_id"].(string); ok {
+			resp.Details = map[string]string{"stage_id": stageID}
+		}
+		return resp
+
+	// Clients tell a discount waiting for approval from other rule violations
+	// by its code, and show the status of the latest approval
+	case application.ErrCodeDiscountApprovalRequired:
+		resp := &ErrorResponse{
+			StatusCode: http.StatusUnprocessableEntity,
+			Code:       string(err.Code),
+			Message:    err.Message,
+		}
+		if status, ok := err.Details["approval_status"].(string); ok {
+			resp.Details = map[string]string{"approval_status": status}
+		}
+		return resp
+
 	// Authorization errors
 	case application.ErrCodeUnauthorized:
 		return ErrUnauthorized(err.Message)
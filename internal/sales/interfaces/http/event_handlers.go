@@ -0,0 +1,41 @@
+package http
+
+import (
+	"net/http"
+
+	"github.com/kilang-desa-murni/crm/internal/sales/application/dto"
+)
+
+// ============================================================================
+// Event Store Handler Methods
+// ============================================================================
+
+// ListEvents handles GET /events
+func (h *Handler) ListEvents(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	tenantID, err := h.getTenantID(ctx)
+	if err != nil {
+		h.respondError(w, ErrUnauthorized("tenant identification required"))
+		return
+	}
+
+	req := dto.ListEventsRequest{
+		AggregateID:   h.getQueryString(r, "aggregate_id"),
+		AggregateType: h.getQueryString(r, "aggregate_type"),
+		EventType:     h.getQueryString(r, "event_type"),
+		From:          h.getQueryString(r, "from"),
+		To:            h.getQueryString(r, "to"),
+		Limit:         h.getQueryInt(r, "limit", 100),
+	}
+	if after := h.getQueryInt64(r, "after"); after != nil {
+		req.After = *after
+	}
+
+	events, err := h.eventStoreUseCase.ListEvents(ctx, tenantID, &req)
+	if err != nil {
+		h.respondError(w, toHTTPError(err))
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, events)
+}
@@ -0,0 +1,188 @@
+package http
+
+import (
+	"net/http"
+
+	"github.com/kilang-desa-murni/crm/internal/sales/application/dto"
+)
+
+// ============================================================================
+// Currency Settings Handler Methods
+// ============================================================================
+
+// GetCurrencySettings handles GET /currency/settings
+func (h *Handler) GetCurrencySettings(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	tenantID, err := h.getTenantID(ctx)
+	if err != nil {
+		h.respondError(w, ErrUnauthorized("tenant identification required"))
+		return
+	}
+
+	settings, err := h.exchangeRateUseCase.GetSettings(ctx, tenantID)
+	if err != nil {
+		h.respondError(w, toHTTPError(err))
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, settings)
+}
+
+// UpdateCurrencySettings handles PUT /currency/settings
+func (h *Handler) UpdateCurrencySettings(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	tenantID, err := h.getTenantID(ctx)
+	if err != nil {
+		h.respondError(w, ErrUnauthorized("tenant identification required"))
+		return
+	}
+
+	var req dto.UpdateCurrencySettingsRequest
+	if err := h.decodeJSON(r, &req); err != nil {
+		h.respondError(w, ErrInvalidJSON(err.Error()))
+		return
+	}
+
+	settings, err := h.exchangeRateUseCase.UpdateSettings(ctx, tenantID, &req)
+	if err != nil {
+		h.respondError(w, toHTTPError(err))
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, settings)
+}
+
+// ConvertCurrency handles GET /currency/convert
+func (h *Handler) ConvertCurrency(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	tenantID, err := h.getTenantID(ctx)
+	if err != nil {
+		h.respondError(w, ErrUnauthorized("tenant identification required"))
+		return
+	}
+
+	amount := h.getQueryInt64(r, "amount")
+	if amount == nil {
+		h.respondError(w, ErrMissingParameter("amount"))
+		return
+	}
+	req := dto.ConvertCurrencyRequest{
+		Amount: *amount,
+		From:   h.getQueryString(r, "from"),
+		To:     h.getQueryString(r, "to"),
+		Date:   h.getQueryString(r, "date"),
+	}
+	if req.From == "" {
+		h.respondError(w, ErrMissingParameter("from"))
+		return
+	}
+
+	result, err := h.exchangeRateUseCase.Convert(ctx, tenantID, &req)
+	if err != nil {
+		h.respondError(w, toHTTPError(err))
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, result)
+}
+
+// ============================================================================
+// Exchange Rate Handler Methods
+// ============================================================================
+
+// ListExchangeRates handles GET /currency/rates
+func (h *Handler) ListExchangeRates(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	tenantID, err := h.getTenantID(ctx)
+	if err != nil {
+		h.respondError(w, ErrUnauthorized("tenant identification required"))
+		return
+	}
+
+	req := dto.ListExchangeRatesRequest{
+		FromCurrency: h.getQueryString(r, "from_currency"),
+		ToCurrency:   h.getQueryString(r, "to_currency"),
+		Source:       h.getQueryString(r, "source"),
+		Page:         h.getQueryInt(r, "page", 1),
+		PageSize:     h.getQueryInt(r, "page_size", 20),
+	}
+
+	rates, err := h.exchangeRateUseCase.ListRates(ctx, tenantID, &req)
+	if err != nil {
+		h.respondError(w, toHTTPError(err))
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, rates)
+}
+
+// SetExchangeRate handles POST /currency/rates
+func (h *Handler) SetExchangeRate(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	tenantID, err := h.getTenantID(ctx)
+	if err != nil {
+		h.respondError(w, ErrUnauthorized("tenant identification required"))
+		return
+	}
+
+	userIDPtr, _ := h.getUserID(ctx)
+	if userIDPtr == nil {
+		h.respondError(w, ErrUnauthorized("user identification required"))
+		return
+	}
+
+	var req dto.SetExchangeRateRequest
+	if err := h.decodeJSON(r, &req); err != nil {
+		h.respondError(w, ErrInvalidJSON(err.Error()))
+		return
+	}
+
+	rate, err := h.exchangeRateUseCase.SetRate(ctx, tenantID, *userIDPtr, &req)
+	if err != nil {
+		h.respondError(w, toHTTPError(err))
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, rate)
+}
+
+// DeleteExchangeRate handles DELETE /currency/rates/{rateID}
+func (h *Handler) DeleteExchangeRate(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	tenantID, err := h.getTenantID(ctx)
+	if err != nil {
+		h.respondError(w, ErrUnauthorized("tenant identification required"))
+		return
+	}
+
+	rateID, err := h.getUUIDParam(r, "rateID")
+	if err != nil {
+		h.respondError(w, err)
+		return
+	}
+
+	if err := h.exchangeRateUseCase.DeleteRate(ctx, tenantID, rateID); err != nil {
+		h.respondError(w, toHTTPError(err))
+		return
+	}
+
+	h.respondNoContent(w)
+}
+
+// RefreshExchangeRates handles POST /currency/rates/refresh
+func (h *Handler) RefreshExchangeRates(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	tenantID, err := h.getTenantID(ctx)
+	if err != nil {
+		h.respondError(w, ErrUnauthorized("tenant identification required"))
+		return
+	}
+
+	result, err := h.exchangeRateUseCase.RefreshRates(ctx, tenantID)
+	if err != nil {
+		h.respondError(w, toHTTPError(err))
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, result)
+}
@@ -0,0 +1,72 @@
+package http
+
+import (
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+
+	"github.com/kilang-desa-murni/crm/internal/sales/application/dto"
+)
+
+// ============================================================================
+// Funnel Handler Methods
+// ============================================================================
+
+// GetPipelineFunnel handles GET /pipelines/{pipelineID}/funnel
+func (h *Handler) GetPipelineFunnel(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	tenantID, err := h.getTenantID(ctx)
+	if err != nil {
+		h.respondError(w, ErrUnauthorized("tenant identification required"))
+		return
+	}
+
+	pipelineIDStr := chi.URLParam(r, "pipelineID")
+	pipelineID, err := uuid.Parse(pipelineIDStr)
+	if err != nil {
+		h.respondError(w, ErrInvalidParameter("pipelineID", "invalid UUID format"))
+		return
+	}
+
+	req := dto.GetFunnelRequest{
+		From:      h.getQueryString(r, "from"),
+		To:        h.getQueryString(r, "to"),
+		OwnerID:   h.getQueryString(r, "owner_id"),
+		Source:    h.getQueryString(r, "source"),
+		ProductID: h.getQueryString(r, "product_id"),
+	}
+
+	funnel, err := h.funnelUseCase.GetFunnel(ctx, tenantID, pipelineID, &req)
+	if err != nil {
+		h.respondError(w, toHTTPError(err))
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, funnel)
+}
+
+// RebuildPipelineFunnel handles POST /pipelines/{pipelineID}/funnel/rebuild
+func (h *Handler) RebuildPipelineFunnel(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	tenantID, err := h.getTenantID(ctx)
+	if err != nil {
+		h.respondError(w, ErrUnauthorized("tenant identification required"))
+		return
+	}
+
+	pipelineIDStr := chi.URLParam(r, "pipelineID")
+	pipelineID, err := uuid.Parse(pipelineIDStr)
+	if err != nil {
+		h.respondError(w, ErrInvalidParameter("pipelineID", "invalid UUID format"))
+		return
+	}
+
+	result, err := h.funnelUseCase.RebuildFunnel(ctx, tenantID, pipelineID)
+	if err != nil {
+		h.respondError(w, toHTTPError(err))
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, result)
+}
@@ -13,8 +13,11 @@ import (
 	"github.com/google/uuid"
 
 	"github.com/kilang-desa-murni/crm/internal/sales/application"
+	"github.com/kilang-desa-murni/crm/internal/sales/application/dto"
 	"github.com/kilang-desa-murni/crm/internal/sales/application/usecase"
 	"github.com/kilang-desa-murni/crm/internal/sales/domain"
+	"github.com/kilang-desa-murni/crm/pkg/response"
+	"github.com/kilang-desa-murni/crm/pkg/serviceauth"
 )
 
 // ============================================================================
@@ -35,17 +38,117 @@ type Handler struct {
 	// Pipeline use cases
 	pipelineUseCase usecase.PipelineUseCase
 
+	// Report use cases
+	reportUseCase usecase.ReportUseCase
+
+	// Exchange rate use cases
+	exchangeRateUseCase usecase.ExchangeRateUseCase
+
+	// Tax use cases
+	taxUseCase usecase.TaxUseCase
+
+	// Pricing use cases
+	pricingUseCase usecase.PricingUseCase
+
+	// Event store use cases
+	eventStoreUseCase usecase.EventStoreUseCase
+
+	// Board use cases
+	boardUseCase usecase.BoardUseCase
+
+	// Funnel use cases
+	funnelUseCase usecase.FunnelUseCase
+
+	// Insight use cases
+	insightUseCase usecase.InsightUseCase
+
+	// Opportunity reason use cases
+	reasonUseCase usecase.OpportunityReasonUseCase
+
+	// Discount approval use cases
+	discountApprovalUseCase usecase.DiscountApprovalUseCase
+
+	// Order use cases
+	orderUseCase usecase.OrderUseCase
+
+	// Shipment use cases
+	shipmentUseCase usecase.ShipmentUseCase
+
+	// E-invoice use cases
+	einvoiceUseCase usecase.EInvoiceUseCase
+
+	// Accounting use cases
+	accountingUseCase usecase.AccountingUseCase
+
+	// E-commerce use cases
+	ecommerceUseCase usecase.EcommerceUseCase
+
+	// Attachment use cases
+	attachmentUseCase usecase.AttachmentUseCase
+
+	// Inbound email use cases
+	inboundEmailUseCase usecase.InboundEmailUseCase
+
+	// Data retention use cases
+	retentionUseCase usecase.RetentionUseCase
+
+	// Tenant data export
+	tenantExporter domain.TenantDataExporter
+
+	// Demo data use cases
+	demoDataUseCase usecase.DemoDataUseCase
+
+	// Owner reassignment use cases
+	reassignmentUseCase usecase.OwnerReassignmentUseCase
+
+	// Presence use cases
+	presenceUseCase usecase.PresenceUseCase
+
+	// Comment use cases
+	commentUseCase usecase.CommentUseCase
+
+	// Revenue split use cases
+	revenueSplitUseCase usecase.RevenueSplitUseCase
+
+	// Service token verification of internal routes
+	serviceAuth *serviceauth.Verifier
+
 	// Middleware configuration
 	middlewareConfig MiddlewareConfig
 }
 
 // HandlerDependencies contains all dependencies needed to create handlers.
 type HandlerDependencies struct {
-	LeadUseCase        usecase.LeadUseCase
-	OpportunityUseCase usecase.OpportunityUseCase
-	DealUseCase        usecase.DealUseCase
-	PipelineUseCase    usecase.PipelineUseCase
-	MiddlewareConfig   MiddlewareConfig
+	LeadUseCase             usecase.LeadUseCase
+	OpportunityUseCase      usecase.OpportunityUseCase
+	DealUseCase             usecase.DealUseCase
+	PipelineUseCase         usecase.PipelineUseCase
+	ReportUseCase           usecase.ReportUseCase
+	ExchangeRateUseCase     usecase.ExchangeRateUseCase
+	TaxUseCase              usecase.TaxUseCase
+	PricingUseCase          usecase.PricingUseCase
+	EventStoreUseCase       usecase.EventStoreUseCase
+	BoardUseCase            usecase.BoardUseCase
+	FunnelUseCase           usecase.FunnelUseCase
+	InsightUseCase          usecase.InsightUseCase
+	ReasonUseCase           usecase.OpportunityReasonUseCase
+	DiscountApprovalUseCase usecase.DiscountApprovalUseCase
+	OrderUseCase            usecase.OrderUseCase
+	ShipmentUseCase         usecase.ShipmentUseCase
+	EInvoiceUseCase         usecase.EInvoiceUseCase
+	AccountingUseCase       usecase.AccountingUseCase
+	EcommerceUseCase        usecase.EcommerceUseCase
+	AttachmentUseCase       usecase.AttachmentUseCase
+	InboundEmailUseCase     usecase.InboundEmailUseCase
+	RetentionUseCase        usecase.RetentionUseCase
+	TenantExporter          domain.TenantDataExporter
+	DemoDataUseCase         usecase.DemoDataUseCase
+	ReassignmentUseCase     usecase.OwnerReassignmentUseCase
+	PresenceUseCase         usecase.PresenceUseCase
+	CommentUseCase          usecase.CommentUseCase
+	RevenueSplitUseCase     usecase.RevenueSplitUseCase
+	ServiceAuth             *serviceauth.Verifier
+	MiddlewareConfig        MiddlewareConfig
 }
 
 // NewHandler creates a new handler with all dependencies.
@@ -56,11 +159,36 @@ func NewHandler(deps HandlerDependencies) *Handler {
 	}
 
 	return &Handler{
-		leadUseCase:        deps.LeadUseCase,
-		opportunityUseCase: deps.OpportunityUseCase,
-		dealUseCase:        deps.DealUseCase,
-		pipelineUseCase:    deps.PipelineUseCase,
-		middlewareConfig:   config,
+		leadUseCase:             deps.LeadUseCase,
+		opportunityUseCase:      deps.OpportunityUseCase,
+		dealUseCase:             deps.DealUseCase,
+		pipelineUseCase:         deps.PipelineUseCase,
+		reportUseCase:           deps.ReportUseCase,
+		exchangeRateUseCase:     deps.ExchangeRateUseCase,
+		taxUseCase:              deps.TaxUseCase,
+		pricingUseCase:          deps.PricingUseCase,
+		eventStoreUseCase:       deps.EventStoreUseCase,
+		boardUseCase:            deps.BoardUseCase,
+		funnelUseCase:           deps.FunnelUseCase,
+		insightUseCase:          deps.InsightUseCase,
+		reasonUseCase:           deps.ReasonUseCase,
+		discountApprovalUseCase: deps.DiscountApprovalUseCase,
+		orderUseCase:            deps.OrderUseCase,
+		shipmentUseCase:         deps.ShipmentUseCase,
+		einvoiceUseCase:         deps.EInvoiceUseCase,
+		accountingUseCase:       deps.AccountingUseCase,
+		ecommerceUseCase:        deps.EcommerceUseCase,
+		attachmentUseCase:       deps.AttachmentUseCase,
+		inboundEmailUseCase:     deps.InboundEmailUseCase,
+		retentionUseCase:        deps.RetentionUseCase,
+		tenantExporter:          deps.TenantExporter,
+		demoDataUseCase:         deps.DemoDataUseCase,
+		reassignmentUseCase:     deps.ReassignmentUseCase,
+		presenceUseCase:         deps.PresenceUseCase,
+		commentUseCase:          deps.CommentUseCase,
+		revenueSplitUseCase:     deps.RevenueSplitUseCase,
+		serviceAuth:             deps.ServiceAuth,
+		middlewareConfig:        config,
 	}
 }
 
@@ -140,6 +268,12 @@ func (h *Handler) getQueryInt(r *http.Request, name string, defaultValue int) in
 	return parsed
 }
 
+// getPageSize extracts the page size from ?limit=, falling back to the older
+// ?page_size= parameter.
+func (h *Handler) getPageSize(r *http.Request) int {
+	return h.getQueryInt(r, "limit", h.getQueryInt(r, "page_size", 20))
+}
+
 // getQueryInt64 extracts an int64 query parameter.
 func (h *Handler) getQueryInt64(r *http.Request, name string) *int64 {
 	value := r.URL.Query().Get(name)
@@ -184,6 +318,23 @@ func (h *Handler) getQueryStringSlice(r *http.Request, name string) []string {
 	return result
 }
 
+// Fields selectable with ?fields= on lead and opportunity responses.
+var (
+	leadFields             = response.FieldsOf(dto.LeadResponse{})
+	leadBriefFields        = response.FieldsOf(dto.LeadBriefResponse{})
+	opportunityFields      = response.FieldsOf(dto.OpportunityResponse{})
+	opportunityBriefFields = response.FieldsOf(dto.OpportunityBriefResponse{})
+)
+
+// getQueryFields parses the ?fields= sparse fieldset against the allowed fields.
+func (h *Handler) getQueryFields(r *http.Request, allowed response.Fields) (response.Fields, error) {
+	fields, err := response.ParseFields(r.URL.Query().Get("fields"), allowed)
+	if err != nil {
+		return nil, ErrInvalidParameter("fields", err.Error())
+	}
+	return fields, nil
+}
+
 // getQueryUUID extracts a UUID query parameter.
 func (h *Handler) getQueryUUID(r *http.Request, name string) *uuid.UUID {
 	value := r.URL.Query().Get(name)
@@ -311,11 +462,12 @@ type APIResponse struct {
 
 // MetaResponse contains pagination metadata.
 type MetaResponse struct {
-	Total      int64 `json:"total"`
-	Page       int   `json:"page"`
-	PageSize   int   `json:"page_size"`
-	TotalPages int64 `json:"total_pages"`
-	HasMore    bool  `json:"has_more"`
+	Total      int64  `json:"total"`
+	Page       int    `json:"page"`
+	PageSize   int    `json:"page_size"`
+	TotalPages int64  `json:"total_pages"`
+	HasMore    bool   `json:"has_more"`
+	NextCursor string `json:"next_cursor,omitempty"`
 }
 
 // respondJSON writes a JSON response.
@@ -347,21 +499,17 @@ func (h *Handler) respondCreated(w http.ResponseWriter, data interface{}) {
 }
 
 // respondList writes a paginated list response.
-func (h *Handler) respondList(w http.ResponseWriter, data interface{}, total int64, page, pageSize int) {
-	totalPages := total / int64(pageSize)
-	if total%int64(pageSize) > 0 {
-		totalPages++
-	}
-
+func (h *Handler) respondList(w http.ResponseWriter, data interface{}, pagination dto.PaginationResponse) {
 	h.respondJSON(w, http.StatusOK, APIResponse{
 		Success: true,
 		Data:    data,
 		Meta: &MetaResponse{
-			Total:      total,
-			Page:       page,
-			PageSize:   pageSize,
-			TotalPages: totalPages,
-			HasMore:    int64(page) < totalPages,
+			Total:      pagination.TotalItems,
+			Page:       pagination.Page,
+			PageSize:   pagination.PageSize,
+			TotalPages: int64(pagination.TotalPages),
+			HasMore:    pagination.HasNext,
+			NextCursor: pagination.NextCursor,
 		},
 	})
 }
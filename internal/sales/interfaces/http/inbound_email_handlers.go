@@ -0,0 +1,126 @@
+package http
+
+import (
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/kilang-desa-murni/crm/internal/sales/application/dto"
+)
+
+// maxInboundEmailBytes bounds a raw email, leaving room for the base64
+// encoding of the attachments kept from it.
+const maxInboundEmailBytes = 30 << 20
+
+// ============================================================================
+// Inbound Email Handler Methods
+// ============================================================================
+
+// GetInboundMailbox handles GET /inbound-email/mailbox
+func (h *Handler) GetInboundMailbox(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	tenantID, err := h.getTenantID(ctx)
+	if err != nil {
+		h.respondError(w, ErrUnauthorized("tenant identification required"))
+		return
+	}
+
+	mailbox, err := h.inboundEmailUseCase.GetMailbox(ctx, tenantID)
+	if err != nil {
+		h.respondError(w, toHTTPError(err))
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, mailbox)
+}
+
+// UpdateInboundMailbox handles PUT /inbound-email/mailbox
+func (h *Handler) UpdateInboundMailbox(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	tenantID, err := h.getTenantID(ctx)
+	if err != nil {
+		h.respondError(w, ErrUnauthorized("tenant identification required"))
+		return
+	}
+
+	var req dto.UpdateInboundMailboxRequest
+	if err := h.decodeJSON(r, &req); err != nil {
+		h.respondError(w, ErrInvalidJSON(err.Error()))
+		return
+	}
+
+	mailbox, err := h.inboundEmailUseCase.UpdateMailbox(ctx, tenantID, &req)
+	if err != nil {
+		h.respondError(w, toHTTPError(err))
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, mailbox)
+}
+
+// ReceiveInboundEmail handles POST /inbound-email/messages
+// The mail relay posts the raw RFC 5322 message as the request body and the
+// envelope recipient in the "recipient" query parameter or X-Original-To header.
+func (h *Handler) ReceiveInboundEmail(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	raw, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxInboundEmailBytes))
+	if err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			h.respondError(w, ErrBadRequest("email is too large"))
+			return
+		}
+		h.respondError(w, ErrBadRequest("failed to read email"))
+		return
+	}
+
+	req := dto.ReceiveInboundEmailRequest{
+		Recipient: h.getQueryString(r, "recipient"),
+		Raw:       raw,
+	}
+	if req.Recipient == "" {
+		req.Recipient = r.Header.Get("X-Original-To")
+	}
+
+	result, err := h.inboundEmailUseCase.Receive(ctx, &req)
+	if err != nil {
+		h.respondError(w, toHTTPError(err))
+		return
+	}
+
+	status := http.StatusOK
+	if result.Email != nil {
+		status = http.StatusCreated
+	}
+	h.respondJSON(w, status, result)
+}
+
+// ListLeadEmails handles GET /leads/{leadID}/emails
+func (h *Handler) ListLeadEmails(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	tenantID, err := h.getTenantID(ctx)
+	if err != nil {
+		h.respondError(w, ErrUnauthorized("tenant identification required"))
+		return
+	}
+
+	leadID, err := h.getUUIDParam(r, "leadID")
+	if err != nil {
+		h.respondError(w, err)
+		return
+	}
+
+	req := dto.ListInboundEmailsRequest{
+		Page:     h.getQueryInt(r, "page", 1),
+		PageSize: h.getQueryInt(r, "page_size", 20),
+	}
+
+	emails, err := h.inboundEmailUseCase.ListLeadEmails(ctx, tenantID, leadID, &req)
+	if err != nil {
+		h.respondError(w, toHTTPError(err))
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, emails)
+}
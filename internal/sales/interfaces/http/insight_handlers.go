@@ -0,0 +1,82 @@
+package http
+
+import (
+	"net/http"
+
+	"github.com/kilang-desa-murni/crm/internal/sales/application/dto"
+)
+
+// ============================================================================
+// Insight Handler Methods
+// ============================================================================
+
+// ListInsights handles GET /insights
+func (h *Handler) ListInsights(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	tenantID, err := h.getTenantID(ctx)
+	if err != nil {
+		h.respondError(w, ErrUnauthorized("tenant identification required"))
+		return
+	}
+
+	req := dto.ListInsightsRequest{
+		Type:  h.getQueryString(r, "type"),
+		Limit: h.getQueryInt(r, "limit", 0),
+	}
+
+	insights, err := h.insightUseCase.ListInsights(ctx, tenantID, &req)
+	if err != nil {
+		h.respondError(w, toHTTPError(err))
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, insights)
+}
+
+// GetInsightSettings handles GET /insights/settings
+func (h *Handler) GetInsightSettings(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	tenantID, err := h.getTenantID(ctx)
+	if err != nil {
+		h.respondError(w, ErrUnauthorized("tenant identification required"))
+		return
+	}
+
+	settings, err := h.insightUseCase.GetSettings(ctx, tenantID)
+	if err != nil {
+		h.respondError(w, toHTTPError(err))
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, settings)
+}
+
+// UpdateInsightSettings handles PUT /insights/settings
+func (h *Handler) UpdateInsightSettings(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	tenantID, err := h.getTenantID(ctx)
+	if err != nil {
+		h.respondError(w, ErrUnauthorized("tenant identification required"))
+		return
+	}
+
+	userIDPtr, _ := h.getUserID(ctx)
+	if userIDPtr == nil {
+		h.respondError(w, ErrUnauthorized("user identification required"))
+		return
+	}
+
+	var req dto.UpdateInsightSettingsRequest
+	if err := h.decodeJSON(r, &req); err != nil {
+		h.respondError(w, ErrInvalidJSON(err.Error()))
+		return
+	}
+
+	settings, err := h.insightUseCase.UpdateSettings(ctx, tenantID, *userIDPtr, &req)
+	if err != nil {
+		h.respondError(w, toHTTPError(err))
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, settings)
+}
@@ -6,6 +6,7 @@ import (
 
 	"github.com/google/uuid"
 
+	"github.com/kilang-desa-murni/crm/internal/sales/application"
 	"github.com/kilang-desa-murni/crm/internal/sales/application/dto"
 )
 
@@ -29,9 +30,23 @@ func (h *Handler) CreateLead(w http.ResponseWriter, r *http.Request) {
 		h.respondError(w, err)
 		return
 	}
+	if force := h.getQueryBool(r, "force"); force != nil && *force {
+		req.Force = true
+	}
 
 	lead, err := h.leadUseCase.Create(ctx, tenantID, ptrToUUID(userID), &req)
 	if err != nil {
+		// Potential duplicates come back with the leads and customers they
+		// match, for the caller to merge or force the lead through
+		if appErr := application.GetAppError(err); appErr != nil && appErr.Code == application.ErrCodeLeadPotentialDuplicate {
+			matches, _ := appErr.Details["matches"].([]*dto.LeadMatchResponse)
+			h.respondJSON(w, http.StatusConflict, APIResponse{
+				Success: false,
+				Data:    dto.LeadMatchesResponse{Matches: matches},
+				Error:   h.toError(err),
+			})
+			return
+		}
 		h.respondError(w, h.toError(err))
 		return
 	}
@@ -54,13 +69,25 @@ func (h *Handler) GetLead(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	fields, err := h.getQueryFields(r, leadFields)
+	if err != nil {
+		h.respondError(w, err)
+		return
+	}
+
 	lead, err := h.leadUseCase.GetByID(ctx, tenantID, leadID)
 	if err != nil {
 		h.respondError(w, h.toError(err))
 		return
 	}
 
-	h.respondSuccess(w, http.StatusOK, lead)
+	data, err := fields.Apply(lead)
+	if err != nil {
+		h.respondError(w, h.toError(err))
+		return
+	}
+
+	h.respondSuccess(w, http.StatusOK, data)
 }
 
 // UpdateLead handles PUT /leads/{leadID}
@@ -129,6 +156,12 @@ func (h *Handler) ListLeads(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	fields, err := h.getQueryFields(r, leadBriefFields)
+	if err != nil {
+		h.respondError(w, err)
+		return
+	}
+
 	// Build filter request from query parameters
 	req := h.buildLeadFilterRequest(r)
 
@@ -138,16 +171,25 @@ func (h *Handler) ListLeads(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	h.respondList(w, result.Leads, result.Pagination.TotalItems, req.Page, req.PageSize)
+	data, err := fields.Apply(result.Leads)
+	if err != nil {
+		h.respondError(w, h.toError(err))
+		return
+	}
+
+	h.respondList(w, data, result.Pagination)
 }
 
 // buildLeadFilterRequest builds a LeadFilterRequest from query parameters.
 func (h *Handler) buildLeadFilterRequest(r *http.Request) *dto.LeadFilterRequest {
 	req := &dto.LeadFilterRequest{
 		Page:      h.getQueryInt(r, "page", 1),
-		PageSize:  h.getQueryInt(r, "page_size", 20),
+		PageSize:  h.getPageSize(r),
+		Cursor:    h.getQueryString(r, "cursor"),
 		SortBy:    h.getQueryString(r, "sort_by"),
 		SortOrder: h.getQueryString(r, "sort_order"),
+		Filter:    h.getQueryString(r, "filter"),
+		Sort:      h.getQueryString(r, "sort"),
 	}
 
 	// Status filters
@@ -415,6 +457,32 @@ func (h *Handler) BulkAssignLeads(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
+// MergeLeads handles POST /leads/merge
+func (h *Handler) MergeLeads(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	tenantID, err := h.getTenantID(ctx)
+	if err != nil {
+		h.respondError(w, ErrUnauthorized("tenant context required"))
+		return
+	}
+
+	userID, _ := h.getUserID(ctx)
+
+	var req dto.MergeLeadsRequest
+	if err := h.decodeJSON(r, &req); err != nil {
+		h.respondError(w, err)
+		return
+	}
+
+	lead, err := h.leadUseCase.MergeLeads(ctx, tenantID, ptrToUUID(userID), &req)
+	if err != nil {
+		h.respondError(w, h.toError(err))
+		return
+	}
+
+	h.respondSuccess(w, http.StatusOK, lead)
+}
+
 // BulkUpdateLeadStatus handles POST /leads/bulk/status
 // Note: This endpoint is not yet implemented - bulk status updates should be done individually
 func (h *Handler) BulkUpdateLeadStatus(w http.ResponseWriter, r *http.Request) {
@@ -498,7 +566,7 @@ func (h *Handler) GetLeadsByOwner(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	h.respondList(w, result.Leads, result.Pagination.TotalItems, req.Page, req.PageSize)
+	h.respondList(w, result.Leads, result.Pagination)
 }
 
 // GetHighScoreLeads handles GET /leads/high-score
@@ -520,7 +588,7 @@ func (h *Handler) GetHighScoreLeads(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	h.respondList(w, result.Leads, result.Pagination.TotalItems, req.Page, req.PageSize)
+	h.respondList(w, result.Leads, result.Pagination)
 }
 
 // GetUnassignedLeads handles GET /leads/unassigned
@@ -542,7 +610,7 @@ func (h *Handler) GetUnassignedLeads(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	h.respondList(w, result.Leads, result.Pagination.TotalItems, req.Page, req.PageSize)
+	h.respondList(w, result.Leads, result.Pagination)
 }
 
 // GetStaleLeads handles GET /leads/stale
@@ -568,7 +636,7 @@ func (h *Handler) GetStaleLeads(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	h.respondList(w, result.Leads, result.Pagination.TotalItems, req.Page, req.PageSize)
+	h.respondList(w, result.Leads, result.Pagination)
 }
 
 // ============================================================================
@@ -2,12 +2,17 @@ package http
 
 import (
 	"context"
+	"crypto/subtle"
 	"net/http"
 	"strings"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
 	"github.com/google/uuid"
+
+	"github.com/kilang-desa-murni/crm/pkg/config"
+	"github.com/kilang-desa-murni/crm/pkg/identity"
+	"github.com/kilang-desa-murni/crm/pkg/logger"
 )
 
 // ============================================================================
@@ -56,6 +61,19 @@ type MiddlewareConfig struct {
 	AllowedOrigins     []string
 	RateLimitRequests  int
 	RateLimitWindow    time.Duration
+
+	// InboundEmailSecret authenticates the mail relay delivering inbound email.
+	// Inbound email is refused while it is empty.
+	InboundEmailSecret string
+
+	// JWTPreviousSecrets are rotated-out JWT keys still accepted for tokens
+	// issued before the rotation.
+	JWTPreviousSecrets []string
+
+	// IdentitySecret verifies the identity headers the gateway signs, which
+	// authenticate requests in place of the access token, as they must with
+	// opaque tokens. Identity headers are ignored while it is empty.
+	IdentitySecret string
 }
 
 // DefaultMiddlewareConfig returns default middleware configuration
@@ -89,35 +107,52 @@ func (h *Handler) AuthMiddleware(next http.Handler) http.Handler {
 			return
 		}
 
-		// Extract token from Authorization header
-		authHeader := r.Header.Get("Authorization")
-		if authHeader == "" {
-			h.respondError(w, ErrUnauthorized("missing authorization header"))
-			return
-		}
+		var claims *JWTClaims
+		if h.middlewareConfig.IdentitySecret != "" && identity.Present(r.Header) {
+			verified, err := h.identityClaims(r.Header)
+			if err != nil {
+				h.respondError(w, err)
+				return
+			}
+			claims = verified
+		} else {
+			// Extract token from Authorization header
+			authHeader := r.Header.Get("Authorization")
+			if authHeader == "" {
+				h.respondError(w, ErrUnauthorized("missing authorization header"))
+				return
+			}
 
-		// Check Bearer prefix
-		parts := strings.SplitN(authHeader, " ", 2)
-		if len(parts) != 2 || !strings.EqualFold(parts[0], "Bearer") {
-			h.respondError(w, ErrUnauthorized("invalid authorization header format"))
-			return
-		}
+			// Check Bearer prefix
+			parts := strings.SplitN(authHeader, " ", 2)
+			if len(parts) != 2 || !strings.EqualFold(parts[0], "Bearer") {
+				h.respondError(w, ErrUnauthorized("invalid authorization header format"))
+				return
+			}
 
-		tokenString := parts[1]
+			tokenString := parts[1]
 
-		// Parse and validate token
-		claims := &JWTClaims{}
-		token, err := jwt.ParseWithClaims(tokenString, claims, func(token *jwt.Token) (interface{}, error) {
-			// Validate signing method
-			if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-				return nil, jwt.ErrSignatureInvalid
-			}
-			return []byte(h.middlewareConfig.JWTSecret), nil
-		})
+			// Parse and validate token
+			claims = &JWTClaims{}
+			token, err := jwt.ParseWithClaims(tokenString, claims, func(token *jwt.Token) (interface{}, error) {
+				// Validate signing method
+				if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+					return nil, jwt.ErrSignatureInvalid
+				}
+				if len(h.middlewareConfig.JWTPreviousSecrets) == 0 {
+					return []byte(h.middlewareConfig.JWTSecret), nil
+				}
+				keys := jwt.VerificationKeySet{Keys: []jwt.VerificationKey{[]byte(h.middlewareConfig.JWTSecret)}}
+				for _, secret := range h.middlewareConfig.JWTPreviousSecrets {
+					keys.Keys = append(keys.Keys, []byte(secret))
+				}
+				return keys, nil
+			})
 
-		if err != nil || !token.Valid {
-			h.respondError(w, ErrUnauthorized("invalid or expired token"))
-			return
+			if err != nil || !token.Valid {
+				h.respondError(w, ErrUnauthorized("invalid or expired token"))
+				return
+			}
 		}
 
 		// Validate claims
@@ -138,10 +173,34 @@ func (h *Handler) AuthMiddleware(next http.Handler) http.Handler {
 		ctx = context.WithValue(ctx, UserRolesKey, claims.Roles)
 		ctx = context.WithValue(ctx, UserPermissionsKey, claims.Permissions)
 
+		// Attach the tenant and user to the request's log lines
+		if c := logger.CorrelationFromContext(ctx); c != nil {
+			c.SetUser(claims.TenantID.String(), claims.UserID.String())
+		}
+
 		next.ServeHTTP(w, r.WithContext(ctx))
 	})
 }
 
+// identityClaims returns the claims of the signed identity headers of h.
+func (h *Handler) identityClaims(header http.Header) (*JWTClaims, error) {
+	id, err := identity.Verify(header, []byte(h.middlewareConfig.IdentitySecret), time.Now())
+	if err != nil {
+		return nil, ErrUnauthorized(err.Error())
+	}
+
+	// Malformed IDs are left as uuid.Nil, which the claims checks refuse
+	tenantID, _ := uuid.Parse(id.TenantID)
+	userID, _ := uuid.Parse(id.UserID)
+	return &JWTClaims{
+		TenantID:    tenantID,
+		UserID:      userID,
+		Email:       id.Email,
+		Roles:       id.Roles,
+		Permissions: id.Permissions,
+	}, nil
+}
+
 // ============================================================================
 // Tenant Middleware
 // ============================================================================
@@ -174,6 +233,31 @@ func (h *Handler) TenantMiddleware(next http.Handler) http.Handler {
 	})
 }
 
+// ============================================================================
+// Inbound Email Middleware
+// ============================================================================
+
+// InboundEmailAuth authenticates the mail relay by the shared secret in the
+// X-Inbound-Email-Secret header. The relay has no user token, and the tenant
+// is resolved later from the address the email was delivered to.
+func (h *Handler) InboundEmailAuth(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		secret := h.middlewareConfig.InboundEmailSecret
+		if secret == "" {
+			h.respondError(w, ErrServiceUnavailable("inbound email is not configured"))
+			return
+		}
+
+		provided := r.Header.Get("X-Inbound-Email-Secret")
+		if subtle.ConstantTimeCompare([]byte(provided), []byte(secret)) != 1 {
+			h.respondError(w, ErrUnauthorized("invalid inbound email secret"))
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
 // ============================================================================
 // Permission Middleware
 // ============================================================================
@@ -294,19 +378,42 @@ func (h *Handler) RequireAnyRole(roles ...string) func(http.Handler) http.Handle
 	}
 }
 
+// hasAnyRole reports whether the user of the request holds any of the roles,
+// for handlers that allow more to some roles than to others. super_admin holds
+// every role.
+func hasAnyRole(ctx context.Context, roles ...string) bool {
+	userRoles, _ := ctx.Value(UserRolesKey).([]string)
+	for _, userRole := range userRoles {
+		if userRole == "super_admin" {
+			return true
+		}
+		for _, role := range roles {
+			if userRole == role {
+				return true
+			}
+		}
+	}
+	return false
+}
+
 // ============================================================================
 // CORS Middleware
 // ============================================================================
 
-// CORSMiddleware handles CORS headers
+// CORSMiddleware handles CORS headers. Listed origins, which may use
+// subdomain wildcards, are echoed back with credentials; "*" allows any
+// origin without them.
 func (h *Handler) CORSMiddleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		origin := r.Header.Get("Origin")
+		w.Header().Add("Vary", "Origin")
 
 		// Check if origin is allowed
-		allowed := false
+		allowed, anyOrigin := false, false
 		for _, o := range h.middlewareConfig.AllowedOrigins {
-			if o == "*" || o == origin {
+			if o == "*" {
+				anyOrigin = true
+			} else if config.MatchOrigin([]string{o}, origin) {
 				allowed = true
 				break
 			}
@@ -314,12 +421,14 @@ func (h *Handler) CORSMiddleware(next http.Handler) http.Handler {
 
 		if allowed {
 			w.Header().Set("Access-Control-Allow-Origin", origin)
+			w.Header().Set("Access-Control-Allow-Credentials", "true")
+		} else if anyOrigin && origin != "" {
+			w.Header().Set("Access-Control-Allow-Origin", "*")
 		}
 
 		w.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, PATCH, DELETE, OPTIONS")
-		w.Header().Set("Access-Control-Allow-Headers", "Accept, Authorization, Content-Type, X-Tenant-ID, X-Request-ID")
+		w.Header().Set("Access-Control-Allow-Headers", "Accept, Authorization, Content-Type, X-Tenant-ID, X-Request-ID, X-CSRF-Token")
 		w.Header().Set("Access-Control-Expose-Headers", "X-Request-ID, X-Total-Count")
-		w.Header().Set("Access-Control-Allow-Credentials", "true")
 		w.Header().Set("Access-Control-Max-Age", "86400")
 
 		// Handle preflight
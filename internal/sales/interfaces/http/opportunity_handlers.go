@@ -52,13 +52,49 @@ func (h *Handler) GetOpportunity(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	fields, err := h.getQueryFields(r, opportunityFields)
+	if err != nil {
+		h.respondError(w, err)
+		return
+	}
+
 	opportunity, err := h.opportunityUseCase.GetByID(ctx, tenantID, opportunityID)
 	if err != nil {
 		h.respondError(w, h.toError(err))
 		return
 	}
 
-	h.respondSuccess(w, http.StatusOK, opportunity)
+	data, err := fields.Apply(opportunity)
+	if err != nil {
+		h.respondError(w, h.toError(err))
+		return
+	}
+
+	h.respondSuccess(w, http.StatusOK, data)
+}
+
+// BatchGetOpportunities handles POST /opportunities/batch-get
+func (h *Handler) BatchGetOpportunities(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	tenantID, err := h.getTenantID(ctx)
+	if err != nil {
+		h.respondError(w, ErrUnauthorized("tenant context required"))
+		return
+	}
+
+	var req dto.BatchGetOpportunitiesRequest
+	if err := h.decodeJSON(r, &req); err != nil {
+		h.respondError(w, err)
+		return
+	}
+
+	result, err := h.opportunityUseCase.BatchGet(ctx, tenantID, &req)
+	if err != nil {
+		h.respondError(w, h.toError(err))
+		return
+	}
+
+	h.respondSuccess(w, http.StatusOK, result)
 }
 
 // UpdateOpportunity handles PUT /opportunities/{opportunityId}
@@ -72,7 +108,7 @@ func (h *Handler) UpdateOpportunity(w http.ResponseWriter, r *http.Request) {
 
 	userID, _ := h.getUserID(ctx)
 
-	opportunityID, err := h.getUUIDParam(r, "opportunityId")
+	opportunityID, err := h.getUUIDParam(r, "opportunityID")
 	if err != nil {
 		h.respondError(w, err)
 		return
@@ -127,6 +163,12 @@ func (h *Handler) ListOpportunities(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	fields, err := h.getQueryFields(r, opportunityBriefFields)
+	if err != nil {
+		h.respondError(w, err)
+		return
+	}
+
 	req := h.buildOpportunityFilterRequest(r)
 
 	result, err := h.opportunityUseCase.List(ctx, tenantID, req)
@@ -135,16 +177,25 @@ func (h *Handler) ListOpportunities(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	h.respondList(w, result.Opportunities, result.Pagination.TotalItems, req.Page, req.PageSize)
+	data, err := fields.Apply(result.Opportunities)
+	if err != nil {
+		h.respondError(w, h.toError(err))
+		return
+	}
+
+	h.respondList(w, data, result.Pagination)
 }
 
 // buildOpportunityFilterRequest builds an OpportunityFilterRequest from query parameters.
 func (h *Handler) buildOpportunityFilterRequest(r *http.Request) *dto.OpportunityFilterRequest {
 	req := &dto.OpportunityFilterRequest{
 		Page:      h.getQueryInt(r, "page", 1),
-		PageSize:  h.getQueryInt(r, "page_size", 20),
+		PageSize:  h.getPageSize(r),
+		Cursor:    h.getQueryString(r, "cursor"),
 		SortBy:    h.getQueryString(r, "sort_by"),
 		SortOrder: h.getQueryString(r, "sort_order"),
+		Filter:    h.getQueryString(r, "filter"),
+		Sort:      h.getQueryString(r, "sort"),
 	}
 
 	// Status filters
@@ -178,6 +229,10 @@ func (h *Handler) buildOpportunityFilterRequest(r *http.Request) *dto.Opportunit
 // Stage Operations
 // ============================================================================
 
+// stageRuleOverrideRoles may move opportunities past a stage's WIP limit and
+// entry criteria.
+var stageRuleOverrideRoles = []string{"manager", "admin"}
+
 // MoveOpportunityStage handles POST /opportunities/{opportunityId}/stage
 func (h *Handler) MoveOpportunityStage(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
@@ -200,6 +255,10 @@ func (h *Handler) MoveOpportunityStage(w http.ResponseWriter, r *http.Request) {
 		h.respondError(w, err)
 		return
 	}
+	if req.OverrideRules && !hasAnyRole(ctx, stageRuleOverrideRoles...) {
+		h.respondError(w, ErrForbidden("only managers can override stage rules"))
+		return
+	}
 
 	opportunity, err := h.opportunityUseCase.MoveStage(ctx, tenantID, opportunityID, ptrToUUID(userID), &req)
 	if err != nil {
@@ -210,6 +269,42 @@ func (h *Handler) MoveOpportunityStage(w http.ResponseWriter, r *http.Request) {
 	h.respondSuccess(w, http.StatusOK, opportunity)
 }
 
+// RepositionOpportunity handles PATCH /opportunities/{opportunityID}/position
+func (h *Handler) RepositionOpportunity(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	tenantID, err := h.getTenantID(ctx)
+	if err != nil {
+		h.respondError(w, ErrUnauthorized("tenant context required"))
+		return
+	}
+
+	userID, _ := h.getUserID(ctx)
+
+	opportunityID, err := h.getUUIDParam(r, "opportunityID")
+	if err != nil {
+		h.respondError(w, err)
+		return
+	}
+
+	var req dto.RepositionOpportunityRequest
+	if err := h.decodeJSON(r, &req); err != nil {
+		h.respondError(w, err)
+		return
+	}
+	if req.OverrideRules && !hasAnyRole(ctx, stageRuleOverrideRoles...) {
+		h.respondError(w, ErrForbidden("only managers can override stage rules"))
+		return
+	}
+
+	opportunity, err := h.opportunityUseCase.Reposition(ctx, tenantID, opportunityID, ptrToUUID(userID), &req)
+	if err != nil {
+		h.respondError(w, h.toError(err))
+		return
+	}
+
+	h.respondSuccess(w, http.StatusOK, opportunity)
+}
+
 // WinOpportunity handles POST /opportunities/{opportunityId}/win
 func (h *Handler) WinOpportunity(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
@@ -310,7 +405,7 @@ func (h *Handler) ReopenOpportunity(w http.ResponseWriter, r *http.Request) {
 // Product Operations
 // ============================================================================
 
-// AddOpportunityProduct handles POST /opportunities/{opportunityId}/products
+// AddOpportunityProduct handles POST /opportunities/{opportunityID}/products
 func (h *Handler) AddOpportunityProduct(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 	tenantID, err := h.getTenantID(ctx)
@@ -321,7 +416,7 @@ func (h *Handler) AddOpportunityProduct(w http.ResponseWriter, r *http.Request)
 
 	userID, _ := h.getUserID(ctx)
 
-	opportunityID, err := h.getUUIDParam(r, "opportunityId")
+	opportunityID, err := h.getUUIDParam(r, "opportunityID")
 	if err != nil {
 		h.respondError(w, err)
 		return
@@ -342,7 +437,39 @@ func (h *Handler) AddOpportunityProduct(w http.ResponseWriter, r *http.Request)
 	h.respondSuccess(w, http.StatusOK, opportunity)
 }
 
-// UpdateOpportunityProduct handles PUT /opportunities/{opportunityId}/products/{productId}
+// ReplaceOpportunityProducts handles PUT /opportunities/{opportunityID}/products
+func (h *Handler) ReplaceOpportunityProducts(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	tenantID, err := h.getTenantID(ctx)
+	if err != nil {
+		h.respondError(w, ErrUnauthorized("tenant context required"))
+		return
+	}
+
+	userID, _ := h.getUserID(ctx)
+
+	opportunityID, err := h.getUUIDParam(r, "opportunityID")
+	if err != nil {
+		h.respondError(w, err)
+		return
+	}
+
+	var req dto.ReplaceProductsRequest
+	if err := h.decodeJSON(r, &req); err != nil {
+		h.respondError(w, err)
+		return
+	}
+
+	opportunity, err := h.opportunityUseCase.ReplaceProducts(ctx, tenantID, opportunityID, ptrToUUID(userID), &req)
+	if err != nil {
+		h.respondError(w, h.toError(err))
+		return
+	}
+
+	h.respondSuccess(w, http.StatusOK, opportunity)
+}
+
+// UpdateOpportunityProduct handles PUT /opportunities/{opportunityID}/products/{productID}
 func (h *Handler) UpdateOpportunityProduct(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 	tenantID, err := h.getTenantID(ctx)
@@ -353,13 +480,13 @@ func (h *Handler) UpdateOpportunityProduct(w http.ResponseWriter, r *http.Reques
 
 	userID, _ := h.getUserID(ctx)
 
-	opportunityID, err := h.getUUIDParam(r, "opportunityId")
+	opportunityID, err := h.getUUIDParam(r, "opportunityID")
 	if err != nil {
 		h.respondError(w, err)
 		return
 	}
 
-	productID, err := h.getUUIDParam(r, "productId")
+	productID, err := h.getUUIDParam(r, "productID")
 	if err != nil {
 		h.respondError(w, err)
 		return
@@ -380,7 +507,7 @@ func (h *Handler) UpdateOpportunityProduct(w http.ResponseWriter, r *http.Reques
 	h.respondSuccess(w, http.StatusOK, opportunity)
 }
 
-// RemoveOpportunityProduct handles DELETE /opportunities/{opportunityId}/products/{productId}
+// RemoveOpportunityProduct handles DELETE /opportunities/{opportunityID}/products/{productID}
 func (h *Handler) RemoveOpportunityProduct(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 	tenantID, err := h.getTenantID(ctx)
@@ -391,13 +518,13 @@ func (h *Handler) RemoveOpportunityProduct(w http.ResponseWriter, r *http.Reques
 
 	userID, _ := h.getUserID(ctx)
 
-	opportunityID, err := h.getUUIDParam(r, "opportunityId")
+	opportunityID, err := h.getUUIDParam(r, "opportunityID")
 	if err != nil {
 		h.respondError(w, err)
 		return
 	}
 
-	productID, err := h.getUUIDParam(r, "productId")
+	productID, err := h.getUUIDParam(r, "productID")
 	if err != nil {
 		h.respondError(w, err)
 		return
@@ -427,7 +554,7 @@ func (h *Handler) AddOpportunityContact(w http.ResponseWriter, r *http.Request)
 
 	userID, _ := h.getUserID(ctx)
 
-	opportunityID, err := h.getUUIDParam(r, "opportunityId")
+	opportunityID, err := h.getUUIDParam(r, "opportunityID")
 	if err != nil {
 		h.respondError(w, err)
 		return
@@ -459,13 +586,13 @@ func (h *Handler) UpdateOpportunityContact(w http.ResponseWriter, r *http.Reques
 
 	userID, _ := h.getUserID(ctx)
 
-	opportunityID, err := h.getUUIDParam(r, "opportunityId")
+	opportunityID, err := h.getUUIDParam(r, "opportunityID")
 	if err != nil {
 		h.respondError(w, err)
 		return
 	}
 
-	contactID, err := h.getUUIDParam(r, "contactId")
+	contactID, err := h.getUUIDParam(r, "contactID")
 	if err != nil {
 		h.respondError(w, err)
 		return
@@ -497,13 +624,13 @@ func (h *Handler) RemoveOpportunityContact(w http.ResponseWriter, r *http.Reques
 
 	userID, _ := h.getUserID(ctx)
 
-	opportunityID, err := h.getUUIDParam(r, "opportunityId")
+	opportunityID, err := h.getUUIDParam(r, "opportunityID")
 	if err != nil {
 		h.respondError(w, err)
 		return
 	}
 
-	contactID, err := h.getUUIDParam(r, "contactId")
+	contactID, err := h.getUUIDParam(r, "contactID")
 	if err != nil {
 		h.respondError(w, err)
 		return
@@ -672,5 +799,32 @@ func (h *Handler) GetOpportunityStageHistory(w http.ResponseWriter, r *http.Requ
 
 // SetOpportunityPrimaryContact handles POST /opportunities/{opportunityId}/contacts/{contactId}/set-primary
 func (h *Handler) SetOpportunityPrimaryContact(w http.ResponseWriter, r *http.Request) {
-	h.respondError(w, ErrUnprocessableEntity("set primary contact endpoint not yet implemented"))
+	ctx := r.Context()
+	tenantID, err := h.getTenantID(ctx)
+	if err != nil {
+		h.respondError(w, ErrUnauthorized("tenant context required"))
+		return
+	}
+
+	userID, _ := h.getUserID(ctx)
+
+	opportunityID, err := h.getUUIDParam(r, "opportunityID")
+	if err != nil {
+		h.respondError(w, err)
+		return
+	}
+
+	contactID, err := h.getUUIDParam(r, "contactID")
+	if err != nil {
+		h.respondError(w, err)
+		return
+	}
+
+	opportunity, err := h.opportunityUseCase.SetPrimaryContact(ctx, tenantID, opportunityID, contactID, ptrToUUID(userID))
+	if err != nil {
+		h.respondError(w, h.toError(err))
+		return
+	}
+
+	h.respondSuccess(w, http.StatusOK, opportunity)
 }
@@ -0,0 +1,137 @@
+package http
+
+import (
+	"net/http"
+
+	"github.com/kilang-desa-murni/crm/internal/sales/application/dto"
+)
+
+// ============================================================================
+// Opportunity Reason Handler Methods
+// ============================================================================
+
+// ListOpportunityReasons handles GET /opportunity-reasons
+func (h *Handler) ListOpportunityReasons(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	tenantID, err := h.getTenantID(ctx)
+	if err != nil {
+		h.respondError(w, ErrUnauthorized("tenant identification required"))
+		return
+	}
+
+	req := dto.ListOpportunityReasonsRequest{
+		Type: h.getQueryString(r, "type"),
+	}
+	if activeOnly := h.getQueryBool(r, "active_only"); activeOnly != nil {
+		req.ActiveOnly = *activeOnly
+	}
+
+	reasons, err := h.reasonUseCase.List(ctx, tenantID, &req)
+	if err != nil {
+		h.respondError(w, toHTTPError(err))
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, reasons)
+}
+
+// CreateOpportunityReason handles POST /opportunity-reasons
+func (h *Handler) CreateOpportunityReason(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	tenantID, err := h.getTenantID(ctx)
+	if err != nil {
+		h.respondError(w, ErrUnauthorized("tenant identification required"))
+		return
+	}
+
+	var req dto.CreateOpportunityReasonRequest
+	if err := h.decodeJSON(r, &req); err != nil {
+		h.respondError(w, ErrInvalidJSON(err.Error()))
+		return
+	}
+
+	reason, err := h.reasonUseCase.Create(ctx, tenantID, &req)
+	if err != nil {
+		h.respondError(w, toHTTPError(err))
+		return
+	}
+
+	h.respondJSON(w, http.StatusCreated, reason)
+}
+
+// GetOpportunityReason handles GET /opportunity-reasons/{reasonID}
+func (h *Handler) GetOpportunityReason(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	tenantID, err := h.getTenantID(ctx)
+	if err != nil {
+		h.respondError(w, ErrUnauthorized("tenant identification required"))
+		return
+	}
+
+	reasonID, err := h.getUUIDParam(r, "reasonID")
+	if err != nil {
+		h.respondError(w, err)
+		return
+	}
+
+	reason, err := h.reasonUseCase.GetByID(ctx, tenantID, reasonID)
+	if err != nil {
+		h.respondError(w, toHTTPError(err))
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, reason)
+}
+
+// UpdateOpportunityReason handles PUT /opportunity-reasons/{reasonID}
+func (h *Handler) UpdateOpportunityReason(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	tenantID, err := h.getTenantID(ctx)
+	if err != nil {
+		h.respondError(w, ErrUnauthorized("tenant identification required"))
+		return
+	}
+
+	reasonID, err := h.getUUIDParam(r, "reasonID")
+	if err != nil {
+		h.respondError(w, err)
+		return
+	}
+
+	var req dto.UpdateOpportunityReasonRequest
+	if err := h.decodeJSON(r, &req); err != nil {
+		h.respondError(w, ErrInvalidJSON(err.Error()))
+		return
+	}
+
+	reason, err := h.reasonUseCase.Update(ctx, tenantID, reasonID, &req)
+	if err != nil {
+		h.respondError(w, toHTTPError(err))
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, reason)
+}
+
+// DeleteOpportunityReason handles DELETE /opportunity-reasons/{reasonID}
+func (h *Handler) DeleteOpportunityReason(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	tenantID, err := h.getTenantID(ctx)
+	if err != nil {
+		h.respondError(w, ErrUnauthorized("tenant identification required"))
+		return
+	}
+
+	reasonID, err := h.getUUIDParam(r, "reasonID")
+	if err != nil {
+		h.respondError(w, err)
+		return
+	}
+
+	if err := h.reasonUseCase.Delete(ctx, tenantID, reasonID); err != nil {
+		h.respondError(w, toHTTPError(err))
+		return
+	}
+
+	h.respondNoContent(w)
+}
@@ -0,0 +1,226 @@
+package http
+
+import (
+	"net/http"
+
+	"github.com/kilang-desa-murni/crm/internal/sales/application/dto"
+)
+
+// ============================================================================
+// Order Handler Methods
+// ============================================================================
+
+// CreateOrderFromDeal handles POST /deals/{dealID}/orders
+func (h *Handler) CreateOrderFromDeal(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	tenantID, err := h.getTenantID(ctx)
+	if err != nil {
+		h.respondError(w, ErrUnauthorized("tenant identification required"))
+		return
+	}
+
+	userID, _ := h.getUserID(ctx)
+	if userID == nil {
+		h.respondError(w, ErrUnauthorized("user identification required"))
+		return
+	}
+
+	dealID, err := h.getUUIDParam(r, "dealID")
+	if err != nil {
+		h.respondError(w, err)
+		return
+	}
+
+	var req dto.CreateOrderRequest
+	if err := h.decodeJSON(r, &req); err != nil {
+		h.respondError(w, ErrInvalidJSON(err.Error()))
+		return
+	}
+
+	order, err := h.orderUseCase.CreateFromDeal(ctx, tenantID, dealID, *userID, &req)
+	if err != nil {
+		h.respondError(w, toHTTPError(err))
+		return
+	}
+
+	h.respondJSON(w, http.StatusCreated, order)
+}
+
+// ListOrders handles GET /orders
+func (h *Handler) ListOrders(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	tenantID, err := h.getTenantID(ctx)
+	if err != nil {
+		h.respondError(w, ErrUnauthorized("tenant identification required"))
+		return
+	}
+
+	req := dto.ListOrdersRequest{
+		Statuses:   h.getQueryStringSlice(r, "status"),
+		DealID:     h.getQueryStringPtr(r, "deal_id"),
+		CustomerID: h.getQueryStringPtr(r, "customer_id"),
+		Page:       h.getQueryInt(r, "page", 1),
+		PageSize:   h.getQueryInt(r, "page_size", 20),
+	}
+
+	orders, err := h.orderUseCase.List(ctx, tenantID, &req)
+	if err != nil {
+		h.respondError(w, toHTTPError(err))
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, orders)
+}
+
+// GetOrder handles GET /orders/{orderID}
+func (h *Handler) GetOrder(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	tenantID, err := h.getTenantID(ctx)
+	if err != nil {
+		h.respondError(w, ErrUnauthorized("tenant identification required"))
+		return
+	}
+
+	orderID, err := h.getUUIDParam(r, "orderID")
+	if err != nil {
+		h.respondError(w, err)
+		return
+	}
+
+	order, err := h.orderUseCase.GetByID(ctx, tenantID, orderID)
+	if err != nil {
+		h.respondError(w, toHTTPError(err))
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, order)
+}
+
+// UpdateOrder handles PUT /orders/{orderID}
+func (h *Handler) UpdateOrder(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	tenantID, err := h.getTenantID(ctx)
+	if err != nil {
+		h.respondError(w, ErrUnauthorized("tenant identification required"))
+		return
+	}
+
+	orderID, err := h.getUUIDParam(r, "orderID")
+	if err != nil {
+		h.respondError(w, err)
+		return
+	}
+
+	var req dto.UpdateOrderRequest
+	if err := h.decodeJSON(r, &req); err != nil {
+		h.respondError(w, ErrInvalidJSON(err.Error()))
+		return
+	}
+
+	order, err := h.orderUseCase.Update(ctx, tenantID, orderID, &req)
+	if err != nil {
+		h.respondError(w, toHTTPError(err))
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, order)
+}
+
+// UpdateOrderStatus handles POST /orders/{orderID}/status
+func (h *Handler) UpdateOrderStatus(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	tenantID, err := h.getTenantID(ctx)
+	if err != nil {
+		h.respondError(w, ErrUnauthorized("tenant identification required"))
+		return
+	}
+
+	userID, _ := h.getUserID(ctx)
+	if userID == nil {
+		h.respondError(w, ErrUnauthorized("user identification required"))
+		return
+	}
+
+	orderID, err := h.getUUIDParam(r, "orderID")
+	if err != nil {
+		h.respondError(w, err)
+		return
+	}
+
+	var req dto.UpdateOrderStatusRequest
+	if err := h.decodeJSON(r, &req); err != nil {
+		h.respondError(w, ErrInvalidJSON(err.Error()))
+		return
+	}
+
+	order, err := h.orderUseCase.UpdateStatus(ctx, tenantID, orderID, *userID, &req)
+	if err != nil {
+		h.respondError(w, toHTTPError(err))
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, order)
+}
+
+// ============================================================================
+// Order Webhook Handler Methods
+// ============================================================================
+
+// GetOrderWebhook handles GET /orders/webhook
+func (h *Handler) GetOrderWebhook(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	tenantID, err := h.getTenantID(ctx)
+	if err != nil {
+		h.respondError(w, ErrUnauthorized("tenant identification required"))
+		return
+	}
+
+	webhook, err := h.orderUseCase.GetWebhook(ctx, tenantID)
+	if err != nil {
+		h.respondError(w, toHTTPError(err))
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, webhook)
+}
+
+// UpdateOrderWebhook handles PUT /orders/webhook
+func (h *Handler) UpdateOrderWebhook(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	tenantID, err := h.getTenantID(ctx)
+	if err != nil {
+		h.respondError(w, ErrUnauthorized("tenant identification required"))
+		return
+	}
+
+	var req dto.UpdateOrderWebhookRequest
+	if err := h.decodeJSON(r, &req); err != nil {
+		h.respondError(w, ErrInvalidJSON(err.Error()))
+		return
+	}
+
+	webhook, err := h.orderUseCase.UpdateWebhook(ctx, tenantID, &req)
+	if err != nil {
+		h.respondError(w, toHTTPError(err))
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, webhook)
+}
+
+// DeleteOrderWebhook handles DELETE /orders/webhook
+func (h *Handler) DeleteOrderWebhook(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	tenantID, err := h.getTenantID(ctx)
+	if err != nil {
+		h.respondError(w, ErrUnauthorized("tenant identification required"))
+		return
+	}
+
+	if err := h.orderUseCase.DeleteWebhook(ctx, tenantID); err != nil {
+		h.respondError(w, toHTTPError(err))
+		return
+	}
+
+	h.respondNoContent(w)
+}
@@ -2,6 +2,7 @@ package http
 
 import (
 	"net/http"
+	"strconv"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/google/uuid"
@@ -476,6 +477,104 @@ func (h *Handler) ReorderStages(w http.ResponseWriter, r *http.Request) {
 	h.respondJSON(w, http.StatusOK, pipeline)
 }
 
+// MigrateStages handles POST /pipelines/{pipelineID}/stages/migrate
+func (h *Handler) MigrateStages(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	tenantID, err := h.getTenantID(ctx)
+	if err != nil {
+		h.respondError(w, ErrUnauthorized("tenant identification required"))
+		return
+	}
+
+	userIDPtr, _ := h.getUserID(ctx)
+	if userIDPtr == nil {
+		h.respondError(w, ErrUnauthorized("user identification required"))
+		return
+	}
+	userID := *userIDPtr
+
+	pipelineIDStr := chi.URLParam(r, "pipelineID")
+	pipelineID, err := uuid.Parse(pipelineIDStr)
+	if err != nil {
+		h.respondError(w, ErrInvalidParameter("pipelineID", "invalid UUID format"))
+		return
+	}
+
+	var req dto.MigrateStagesRequest
+	if err := h.decodeJSON(r, &req); err != nil {
+		h.respondError(w, ErrInvalidJSON(err.Error()))
+		return
+	}
+
+	result, err := h.pipelineUseCase.MigrateStages(ctx, tenantID, pipelineID, userID, &req)
+	if err != nil {
+		h.respondError(w, toHTTPError(err))
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, result)
+}
+
+// ============================================================================
+// Version History
+// ============================================================================
+
+// ListPipelineVersions handles GET /pipelines/{pipelineID}/versions
+func (h *Handler) ListPipelineVersions(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	tenantID, err := h.getTenantID(ctx)
+	if err != nil {
+		h.respondError(w, ErrUnauthorized("tenant identification required"))
+		return
+	}
+
+	pipelineIDStr := chi.URLParam(r, "pipelineID")
+	pipelineID, err := uuid.Parse(pipelineIDStr)
+	if err != nil {
+		h.respondError(w, ErrInvalidParameter("pipelineID", "invalid UUID format"))
+		return
+	}
+
+	versions, err := h.pipelineUseCase.ListVersions(ctx, tenantID, pipelineID)
+	if err != nil {
+		h.respondError(w, toHTTPError(err))
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, versions)
+}
+
+// GetPipelineVersion handles GET /pipelines/{pipelineID}/versions/{version}
+func (h *Handler) GetPipelineVersion(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	tenantID, err := h.getTenantID(ctx)
+	if err != nil {
+		h.respondError(w, ErrUnauthorized("tenant identification required"))
+		return
+	}
+
+	pipelineIDStr := chi.URLParam(r, "pipelineID")
+	pipelineID, err := uuid.Parse(pipelineIDStr)
+	if err != nil {
+		h.respondError(w, ErrInvalidParameter("pipelineID", "invalid UUID format"))
+		return
+	}
+
+	version, err := strconv.Atoi(chi.URLParam(r, "version"))
+	if err != nil || version < 1 {
+		h.respondError(w, ErrInvalidParameter("version", "must be a positive integer"))
+		return
+	}
+
+	pipelineVersion, err := h.pipelineUseCase.GetVersion(ctx, tenantID, pipelineID, version)
+	if err != nil {
+		h.respondError(w, toHTTPError(err))
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, pipelineVersion)
+}
+
 // ============================================================================
 // Analytics & Statistics
 // ============================================================================
@@ -0,0 +1,153 @@
+package http
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+
+	"github.com/kilang-desa-murni/crm/internal/sales/application/dto"
+)
+
+// presenceKeepAliveInterval is how often an idle presence stream is sent a
+// comment, so proxies do not close it.
+const presenceKeepAliveInterval = 25 * time.Second
+
+// ============================================================================
+// Presence Handler Methods
+// ============================================================================
+
+// GetPresence handles GET /presence/{recordType}/{recordID}
+func (h *Handler) GetPresence(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	tenantID, err := h.getTenantID(ctx)
+	if err != nil {
+		h.respondError(w, err)
+		return
+	}
+
+	recordID, err := h.getUUIDParam(r, "recordID")
+	if err != nil {
+		h.respondError(w, err)
+		return
+	}
+
+	presence, err := h.presenceUseCase.GetPresence(ctx, tenantID, chi.URLParam(r, "recordType"), recordID)
+	if err != nil {
+		h.respondError(w, toHTTPError(err))
+		return
+	}
+
+	h.respondSuccess(w, http.StatusOK, presence)
+}
+
+// StreamPresence handles GET /presence/{recordType}/{recordID}/stream,
+// joining the record and streaming its presence events as Server-Sent
+// Events until the client disconnects, which leaves the record and releases
+// any soft lock the connection held. The first event is the connection's
+// own joined event, carrying the connection ID used to switch its mode. The
+// write deadline is lifted as the stream outlasts it.
+func (h *Handler) StreamPresence(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	tenantID, err := h.getTenantID(ctx)
+	if err != nil {
+		h.respondError(w, err)
+		return
+	}
+	userID := h.getUserIDDirect(ctx)
+	if userID == uuid.Nil {
+		h.respondError(w, ErrUnauthorized("user identification required"))
+		return
+	}
+
+	recordID, err := h.getUUIDParam(r, "recordID")
+	if err != nil {
+		h.respondError(w, err)
+		return
+	}
+
+	sub, err := h.presenceUseCase.Join(ctx, tenantID, userID, chi.URLParam(r, "recordType"), recordID)
+	if err != nil {
+		h.respondError(w, toHTTPError(err))
+		return
+	}
+	defer sub.Close()
+
+	rc := http.NewResponseController(w)
+	_ = rc.SetWriteDeadline(time.Time{})
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+
+	keepAlive := time.NewTicker(presenceKeepAliveInterval)
+	defer keepAlive.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case event, ok := <-sub.Events:
+			if !ok {
+				return
+			}
+			if err := writePresenceEvent(w, event); err != nil {
+				return
+			}
+		case <-keepAlive.C:
+			if _, err := fmt.Fprint(w, ": keep-alive\n\n"); err != nil {
+				return
+			}
+		}
+		if err := rc.Flush(); err != nil {
+			return
+		}
+	}
+}
+
+// writePresenceEvent writes a presence event as a Server-Sent Event named
+// after its type.
+func writePresenceEvent(w http.ResponseWriter, event *dto.PresenceEvent) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event.Type, data)
+	return err
+}
+
+// SetPresenceMode handles PUT /presence/{recordType}/{recordID}/connections/{connectionID},
+// switching the caller's connection between viewing and editing the record.
+func (h *Handler) SetPresenceMode(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	tenantID, err := h.getTenantID(ctx)
+	if err != nil {
+		h.respondError(w, err)
+		return
+	}
+
+	recordID, err := h.getUUIDParam(r, "recordID")
+	if err != nil {
+		h.respondError(w, err)
+		return
+	}
+
+	var req dto.SetPresenceModeRequest
+	if err := h.decodeJSON(r, &req); err != nil {
+		h.respondError(w, err)
+		return
+	}
+
+	presence, err := h.presenceUseCase.SetMode(ctx, tenantID, h.getUserIDDirect(ctx), chi.URLParam(r, "recordType"), recordID, chi.URLParam(r, "connectionID"), &req)
+	if err != nil {
+		h.respondError(w, toHTTPError(err))
+		return
+	}
+
+	h.respondSuccess(w, http.StatusOK, presence)
+}
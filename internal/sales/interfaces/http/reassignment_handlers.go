@@ -0,0 +1,49 @@
+package http
+
+import (
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/kilang-desa-murni/crm/internal/sales/application/dto"
+)
+
+// ============================================================================
+// Owner Reassignment Handler Methods
+// ============================================================================
+
+// ReassignRecords handles POST /internal/tenants/{tenantID}/reassign/{dataset},
+// transferring a user's open leads or opportunities to other users for the
+// IAM service's user reassignments.
+func (h *Handler) ReassignRecords(w http.ResponseWriter, r *http.Request) {
+	tenantID, err := h.getUUIDParam(r, "tenantID")
+	if err != nil {
+		h.respondError(w, err)
+		return
+	}
+
+	dataset := chi.URLParam(r, "dataset")
+	if dataset != "leads" && dataset != "opportunities" {
+		h.respondError(w, ErrNotFound("reassignment data set"))
+		return
+	}
+
+	var req dto.ReassignRecordsRequest
+	if err := h.decodeJSON(r, &req); err != nil {
+		h.respondError(w, ErrInvalidJSON(err.Error()))
+		return
+	}
+
+	var result *dto.ReassignRecordsResponse
+	if dataset == "leads" {
+		result, err = h.reassignmentUseCase.ReassignLeads(r.Context(), tenantID, &req)
+	} else {
+		result, err = h.reassignmentUseCase.ReassignOpportunities(r.Context(), tenantID, &req)
+	}
+	if err != nil {
+		h.respondError(w, toHTTPError(err))
+		return
+	}
+
+	h.respondSuccess(w, http.StatusOK, result)
+}
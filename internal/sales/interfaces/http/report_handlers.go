@@ -0,0 +1,350 @@
+package http
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/kilang-desa-murni/crm/internal/sales/application/dto"
+	"github.com/kilang-desa-murni/crm/internal/sales/domain"
+)
+
+// ============================================================================
+// Report Handler Methods
+// ============================================================================
+
+// GetSalesPerformanceReport handles GET /reports/sales-performance
+func (h *Handler) GetSalesPerformanceReport(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	tenantID, err := h.getTenantID(ctx)
+	if err != nil {
+		h.respondError(w, ErrUnauthorized("tenant identification required"))
+		return
+	}
+
+	req := dto.SalesPerformanceRequest{
+		From:     h.getQueryString(r, "from"),
+		To:       h.getQueryString(r, "to"),
+		GroupBy:  h.getQueryString(r, "group_by"),
+		Currency: h.getQueryString(r, "currency"),
+	}
+	if req.From == "" {
+		h.respondError(w, ErrMissingParameter("from"))
+		return
+	}
+	if req.To == "" {
+		h.respondError(w, ErrMissingParameter("to"))
+		return
+	}
+
+	report, err := h.reportUseCase.GetSalesPerformance(ctx, tenantID, &req)
+	if err != nil {
+		h.respondError(w, toHTTPError(err))
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, report)
+}
+
+// GetAttributionReport handles GET /reports/attribution
+func (h *Handler) GetAttributionReport(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	tenantID, err := h.getTenantID(ctx)
+	if err != nil {
+		h.respondError(w, ErrUnauthorized("tenant identification required"))
+		return
+	}
+
+	req := dto.SalesPerformanceRequest{
+		From:     h.getQueryString(r, "from"),
+		To:       h.getQueryString(r, "to"),
+		Currency: h.getQueryString(r, "currency"),
+	}
+	if req.From == "" {
+		h.respondError(w, ErrMissingParameter("from"))
+		return
+	}
+	if req.To == "" {
+		h.respondError(w, ErrMissingParameter("to"))
+		return
+	}
+
+	report, err := h.reportUseCase.GetAttributionReport(ctx, tenantID, &req)
+	if err != nil {
+		h.respondError(w, toHTTPError(err))
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, report)
+}
+
+// GetWinLossReport handles GET /reports/win-loss
+func (h *Handler) GetWinLossReport(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	tenantID, err := h.getTenantID(ctx)
+	if err != nil {
+		h.respondError(w, ErrUnauthorized("tenant identification required"))
+		return
+	}
+
+	req := dto.WinLossReportRequest{
+		From:     h.getQueryString(r, "from"),
+		To:       h.getQueryString(r, "to"),
+		GroupBy:  h.getQueryString(r, "group_by"),
+		Currency: h.getQueryString(r, "currency"),
+	}
+	if req.From == "" {
+		h.respondError(w, ErrMissingParameter("from"))
+		return
+	}
+	if req.To == "" {
+		h.respondError(w, ErrMissingParameter("to"))
+		return
+	}
+
+	report, err := h.reportUseCase.GetWinLossReport(ctx, tenantID, &req)
+	if err != nil {
+		h.respondError(w, toHTTPError(err))
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, report)
+}
+
+// RebuildReportAggregates handles POST /reports/aggregations/rebuild
+func (h *Handler) RebuildReportAggregates(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var req dto.RunAggregationRequest
+	if err := h.decodeJSON(r, &req); err != nil {
+		h.respondError(w, ErrInvalidJSON(err.Error()))
+		return
+	}
+
+	result, err := h.reportUseCase.RebuildAggregates(ctx, &req)
+	if err != nil {
+		h.respondError(w, toHTTPError(err))
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, result)
+}
+
+// ============================================================================
+// Report Exports
+// ============================================================================
+
+// ExportSalesPerformanceReport handles GET /reports/sales-performance/export
+// The file is streamed to the client as it is rendered.
+func (h *Handler) ExportSalesPerformanceReport(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	tenantID, err := h.getTenantID(ctx)
+	if err != nil {
+		h.respondError(w, ErrUnauthorized("tenant identification required"))
+		return
+	}
+
+	format := h.getQueryString(r, "format")
+	if format == "" {
+		format = string(domain.ReportExportFormatXLSX)
+	}
+
+	req := dto.SalesPerformanceRequest{
+		From:     h.getQueryString(r, "from"),
+		To:       h.getQueryString(r, "to"),
+		GroupBy:  h.getQueryString(r, "group_by"),
+		Currency: h.getQueryString(r, "currency"),
+	}
+	if req.From == "" {
+		h.respondError(w, ErrMissingParameter("from"))
+		return
+	}
+	if req.To == "" {
+		h.respondError(w, ErrMissingParameter("to"))
+		return
+	}
+
+	exportFormat := domain.ReportExportFormat(format)
+	aw := &attachmentWriter{
+		ResponseWriter: w,
+		contentType:    exportFormat.ContentType(),
+		fileName:       exportFormat.FileName(domain.ReportTypeSalesPerformance, time.Now()),
+	}
+
+	if err := h.reportUseCase.ExportSalesPerformance(ctx, tenantID, &req, format, aw); err != nil {
+		if !aw.started {
+			h.respondError(w, toHTTPError(err))
+		}
+		return
+	}
+}
+
+// CreateReportExport handles POST /reports/exports
+func (h *Handler) CreateReportExport(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	tenantID, err := h.getTenantID(ctx)
+	if err != nil {
+		h.respondError(w, ErrUnauthorized("tenant identification required"))
+		return
+	}
+
+	userIDPtr, _ := h.getUserID(ctx)
+	if userIDPtr == nil {
+		h.respondError(w, ErrUnauthorized("user identification required"))
+		return
+	}
+
+	var req dto.CreateReportExportRequest
+	if err := h.decodeJSON(r, &req); err != nil {
+		h.respondError(w, ErrInvalidJSON(err.Error()))
+		return
+	}
+
+	export, err := h.reportUseCase.RequestExport(ctx, tenantID, *userIDPtr, &req)
+	if err != nil {
+		h.respondError(w, toHTTPError(err))
+		return
+	}
+
+	h.respondJSON(w, http.StatusAccepted, export)
+}
+
+// ListReportExports handles GET /reports/exports
+func (h *Handler) ListReportExports(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	tenantID, err := h.getTenantID(ctx)
+	if err != nil {
+		h.respondError(w, ErrUnauthorized("tenant identification required"))
+		return
+	}
+
+	userIDPtr, _ := h.getUserID(ctx)
+	if userIDPtr == nil {
+		h.respondError(w, ErrUnauthorized("user identification required"))
+		return
+	}
+
+	exports, err := h.reportUseCase.ListExports(ctx, tenantID, *userIDPtr)
+	if err != nil {
+		h.respondError(w, toHTTPError(err))
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, exports)
+}
+
+// GetReportExport handles GET /reports/exports/{exportID}
+func (h *Handler) GetReportExport(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	tenantID, err := h.getTenantID(ctx)
+	if err != nil {
+		h.respondError(w, ErrUnauthorized("tenant identification required"))
+		return
+	}
+
+	exportID, err := h.getUUIDParam(r, "exportID")
+	if err != nil {
+		h.respondError(w, err)
+		return
+	}
+
+	export, err := h.reportUseCase.GetExport(ctx, tenantID, exportID)
+	if err != nil {
+		h.respondError(w, toHTTPError(err))
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, export)
+}
+
+// DownloadReportExport handles GET /reports/exports/{exportID}/download
+func (h *Handler) DownloadReportExport(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	tenantID, err := h.getTenantID(ctx)
+	if err != nil {
+		h.respondError(w, ErrUnauthorized("tenant identification required"))
+		return
+	}
+
+	exportID, err := h.getUUIDParam(r, "exportID")
+	if err != nil {
+		h.respondError(w, err)
+		return
+	}
+
+	file, err := h.reportUseCase.DownloadExport(ctx, tenantID, exportID)
+	if err != nil {
+		h.respondError(w, toHTTPError(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", file.ContentType)
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", file.FileName))
+	w.WriteHeader(http.StatusOK)
+	w.Write(file.Content)
+}
+
+// ============================================================================
+// Report Branding
+// ============================================================================
+
+// GetReportBranding handles GET /reports/branding
+func (h *Handler) GetReportBranding(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	tenantID, err := h.getTenantID(ctx)
+	if err != nil {
+		h.respondError(w, ErrUnauthorized("tenant identification required"))
+		return
+	}
+
+	branding, err := h.reportUseCase.GetBranding(ctx, tenantID)
+	if err != nil {
+		h.respondError(w, toHTTPError(err))
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, branding)
+}
+
+// UpdateReportBranding handles PUT /reports/branding
+func (h *Handler) UpdateReportBranding(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	tenantID, err := h.getTenantID(ctx)
+	if err != nil {
+		h.respondError(w, ErrUnauthorized("tenant identification required"))
+		return
+	}
+
+	var req dto.UpdateReportBrandingRequest
+	if err := h.decodeJSON(r, &req); err != nil {
+		h.respondError(w, ErrInvalidJSON(err.Error()))
+		return
+	}
+
+	branding, err := h.reportUseCase.UpdateBranding(ctx, tenantID, &req)
+	if err != nil {
+		h.respondError(w, toHTTPError(err))
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, branding)
+}
+
+// attachmentWriter sets file download headers on the first write, so that an
+// error response can still be sent if rendering fails before any output.
+type attachmentWriter struct {
+	http.ResponseWriter
+	contentType string
+	fileName    string
+	started     bool
+}
+
+func (w *attachmentWriter) Write(b []byte) (int, error) {
+	if !w.started {
+		w.started = true
+		w.Header().Set("Content-Type", w.contentType)
+		w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", w.fileName))
+		w.WriteHeader(http.StatusOK)
+	}
+	return w.ResponseWriter.Write(b)
+}
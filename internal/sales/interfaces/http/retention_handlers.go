@@ -0,0 +1,134 @@
+package http
+
+import (
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/kilang-desa-murni/crm/internal/sales/application/dto"
+)
+
+// ============================================================================
+// Data Retention Handler Methods
+// ============================================================================
+
+// ListRetentionPolicies handles GET /retention/policies
+func (h *Handler) ListRetentionPolicies(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	tenantID, err := h.getTenantID(ctx)
+	if err != nil {
+		h.respondError(w, ErrUnauthorized("tenant identification required"))
+		return
+	}
+
+	policies, err := h.retentionUseCase.ListPolicies(ctx, tenantID)
+	if err != nil {
+		h.respondError(w, toHTTPError(err))
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, policies)
+}
+
+// SetRetentionPolicy handles PUT /retention/policies/{entityType}
+func (h *Handler) SetRetentionPolicy(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	tenantID, err := h.getTenantID(ctx)
+	if err != nil {
+		h.respondError(w, ErrUnauthorized("tenant identification required"))
+		return
+	}
+
+	userIDPtr, _ := h.getUserID(ctx)
+	if userIDPtr == nil {
+		h.respondError(w, ErrUnauthorized("user identification required"))
+		return
+	}
+
+	var req dto.SetRetentionPolicyRequest
+	if err := h.decodeJSON(r, &req); err != nil {
+		h.respondError(w, ErrInvalidJSON(err.Error()))
+		return
+	}
+
+	policy, err := h.retentionUseCase.SetPolicy(ctx, tenantID, *userIDPtr, chi.URLParam(r, "entityType"), &req)
+	if err != nil {
+		h.respondError(w, toHTTPError(err))
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, policy)
+}
+
+// DeleteRetentionPolicy handles DELETE /retention/policies/{entityType}
+func (h *Handler) DeleteRetentionPolicy(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	tenantID, err := h.getTenantID(ctx)
+	if err != nil {
+		h.respondError(w, ErrUnauthorized("tenant identification required"))
+		return
+	}
+
+	if err := h.retentionUseCase.DeletePolicy(ctx, tenantID, chi.URLParam(r, "entityType")); err != nil {
+		h.respondError(w, toHTTPError(err))
+		return
+	}
+
+	h.respondNoContent(w)
+}
+
+// ListArchivedRecords handles GET /retention/archive
+func (h *Handler) ListArchivedRecords(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	tenantID, err := h.getTenantID(ctx)
+	if err != nil {
+		h.respondError(w, ErrUnauthorized("tenant identification required"))
+		return
+	}
+
+	req := dto.ListArchivedRecordsRequest{
+		EntityType:      h.getQueryString(r, "entity_type"),
+		EntityID:        h.getQueryString(r, "entity_id"),
+		IncludeRestored: h.getQueryString(r, "include_restored") == "true",
+		Page:            h.getQueryInt(r, "page", 1),
+		PageSize:        h.getQueryInt(r, "page_size", 20),
+	}
+
+	records, err := h.retentionUseCase.ListArchived(ctx, tenantID, &req)
+	if err != nil {
+		h.respondError(w, toHTTPError(err))
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, records)
+}
+
+// RestoreArchivedRecord handles POST /retention/archive/{recordID}/restore
+func (h *Handler) RestoreArchivedRecord(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	tenantID, err := h.getTenantID(ctx)
+	if err != nil {
+		h.respondError(w, ErrUnauthorized("tenant identification required"))
+		return
+	}
+
+	userIDPtr, _ := h.getUserID(ctx)
+	if userIDPtr == nil {
+		h.respondError(w, ErrUnauthorized("user identification required"))
+		return
+	}
+
+	recordID, err := h.getUUIDParam(r, "recordID")
+	if err != nil {
+		h.respondError(w, err)
+		return
+	}
+
+	record, err := h.retentionUseCase.Restore(ctx, tenantID, *userIDPtr, recordID)
+	if err != nil {
+		h.respondError(w, toHTTPError(err))
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, record)
+}
@@ -0,0 +1,136 @@
+package http
+
+import (
+	"net/http"
+
+	"github.com/google/uuid"
+
+	"github.com/kilang-desa-murni/crm/internal/sales/application/dto"
+)
+
+// revenueSplitManagerRoles may change the revenue splits of opportunities
+// they do not own.
+var revenueSplitManagerRoles = []string{"manager", "admin"}
+
+// ============================================================================
+// Revenue Split Handler Methods
+// ============================================================================
+
+// GetOpportunityRevenueSplits handles GET /opportunities/{opportunityID}/revenue-splits
+func (h *Handler) GetOpportunityRevenueSplits(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	tenantID, err := h.getTenantID(ctx)
+	if err != nil {
+		h.respondError(w, err)
+		return
+	}
+
+	opportunityID, err := h.getUUIDParam(r, "opportunityID")
+	if err != nil {
+		h.respondError(w, err)
+		return
+	}
+
+	splits, err := h.revenueSplitUseCase.Get(ctx, tenantID, opportunityID)
+	if err != nil {
+		h.respondError(w, toHTTPError(err))
+		return
+	}
+
+	h.respondSuccess(w, http.StatusOK, splits)
+}
+
+// SetOpportunityRevenueSplits handles PUT /opportunities/{opportunityID}/revenue-splits
+func (h *Handler) SetOpportunityRevenueSplits(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	tenantID, err := h.getTenantID(ctx)
+	if err != nil {
+		h.respondError(w, err)
+		return
+	}
+	userID := h.getUserIDDirect(ctx)
+	if userID == uuid.Nil {
+		h.respondError(w, ErrUnauthorized("user identification required"))
+		return
+	}
+
+	opportunityID, err := h.getUUIDParam(r, "opportunityID")
+	if err != nil {
+		h.respondError(w, err)
+		return
+	}
+
+	var req dto.SetRevenueSplitsRequest
+	if err := h.decodeJSON(r, &req); err != nil {
+		h.respondError(w, err)
+		return
+	}
+
+	manager := hasAnyRole(ctx, revenueSplitManagerRoles...)
+	splits, err := h.revenueSplitUseCase.Set(ctx, tenantID, userID, opportunityID, manager, &req)
+	if err != nil {
+		h.respondError(w, toHTTPError(err))
+		return
+	}
+
+	h.respondSuccess(w, http.StatusOK, splits)
+}
+
+// ClearOpportunityRevenueSplits handles DELETE /opportunities/{opportunityID}/revenue-splits.
+// The owner is credited in full again.
+func (h *Handler) ClearOpportunityRevenueSplits(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	tenantID, err := h.getTenantID(ctx)
+	if err != nil {
+		h.respondError(w, err)
+		return
+	}
+	userID := h.getUserIDDirect(ctx)
+	if userID == uuid.Nil {
+		h.respondError(w, ErrUnauthorized("user identification required"))
+		return
+	}
+
+	opportunityID, err := h.getUUIDParam(r, "opportunityID")
+	if err != nil {
+		h.respondError(w, err)
+		return
+	}
+
+	manager := hasAnyRole(ctx, revenueSplitManagerRoles...)
+	if _, err := h.revenueSplitUseCase.Clear(ctx, tenantID, userID, opportunityID, manager); err != nil {
+		h.respondError(w, toHTTPError(err))
+		return
+	}
+
+	h.respondNoContent(w)
+}
+
+// ListOpportunityRevenueSplitHistory handles GET /opportunities/{opportunityID}/revenue-splits/history
+func (h *Handler) ListOpportunityRevenueSplitHistory(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	tenantID, err := h.getTenantID(ctx)
+	if err != nil {
+		h.respondError(w, err)
+		return
+	}
+
+	opportunityID, err := h.getUUIDParam(r, "opportunityID")
+	if err != nil {
+		h.respondError(w, err)
+		return
+	}
+
+	req := dto.ListRevenueSplitHistoryRequest{
+		Page:     h.getQueryInt(r, "page", 1),
+		PageSize: h.getQueryInt(r, "page_size", 20),
+	}
+
+	history, err := h.revenueSplitUseCase.ListHistory(ctx, tenantID, opportunityID, &req)
+	if err != nil {
+		h.respondError(w, toHTTPError(err))
+		return
+	}
+
+	h.respondList(w, history.Changes, history.Pagination)
+}
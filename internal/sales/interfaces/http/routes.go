@@ -1,8 +1,13 @@
 package http
 
 import (
+	"net/http"
+
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
+
+	"github.com/kilang-desa-murni/crm/pkg/etag"
+	"github.com/kilang-desa-murni/crm/pkg/serviceauth"
 )
 
 // RegisterRoutes registers all sales API routes
@@ -37,6 +42,9 @@ func (h *Handler) RegisterRoutes(r chi.Router) {
 			r.Post("/bulk/status", h.BulkUpdateLeadStatus)
 			r.Delete("/bulk", h.BulkDeleteLeads)
 
+			// Duplicates
+			r.Post("/merge", h.MergeLeads)
+
 			// Single lead operations
 			r.Route("/{leadID}", func(r chi.Router) {
 				r.Get("/", h.GetLead)
@@ -52,6 +60,9 @@ func (h *Handler) RegisterRoutes(r chi.Router) {
 				// Assignment
 				r.Post("/assign", h.AssignLead)
 				r.Delete("/assign", h.UnassignLead)
+
+				// Inbound email
+				r.Get("/emails", h.ListLeadEmails)
 			})
 		})
 
@@ -67,25 +78,46 @@ func (h *Handler) RegisterRoutes(r chi.Router) {
 			r.Get("/overdue", h.GetOverdueOpportunities)
 
 			// Bulk operations
+			r.Post("/batch-get", h.BatchGetOpportunities)
 			r.Post("/bulk/assign", h.BulkAssignOpportunities)
 			r.Post("/bulk/move-stage", h.BulkMoveStage)
 
 			// Single opportunity operations
 			r.Route("/{opportunityID}", func(r chi.Router) {
-				r.Get("/", h.GetOpportunity)
-				r.Put("/", h.UpdateOpportunity)
-				r.Delete("/", h.DeleteOpportunity)
+				// Reads are tagged with an ETag; writes require If-Match
+				conditional := r.With(etag.Resource(http.HandlerFunc(h.GetOpportunity)))
+				conditional.Get("/", h.GetOpportunity)
+				conditional.Put("/", h.UpdateOpportunity)
+				conditional.Patch("/", h.UpdateOpportunity)
+				conditional.Delete("/", h.DeleteOpportunity)
 
 				// Stage transitions
 				r.Post("/move-stage", h.MoveOpportunityToStage)
+				r.Patch("/position", h.RepositionOpportunity)
 				r.Post("/win", h.WinOpportunity)
 				r.Post("/lose", h.LoseOpportunity)
 				r.Post("/reopen", h.ReopenOpportunity)
 				r.Get("/stage-history", h.GetOpportunityStageHistory)
 
+				// Quotation
+				r.Get("/quote", h.GetOpportunityQuote)
+				r.Get("/quote/pdf", h.DownloadOpportunityQuote)
+
+				// Discount approvals
+				r.Post("/discount-approvals", h.SubmitDiscountApproval)
+				r.Get("/discount-approvals", h.ListOpportunityDiscountApprovals)
+
+				// Attachments
+				r.Route("/attachments", func(r chi.Router) {
+					r.Post("/", h.UploadOpportunityAttachment)
+					r.Get("/", h.ListOpportunityAttachments)
+					r.Get("/{name}", h.DownloadOpportunityAttachment)
+				})
+
 				// Products
 				r.Route("/products", func(r chi.Router) {
 					r.Post("/", h.AddOpportunityProduct)
+					r.Put("/", h.ReplaceOpportunityProducts)
 					r.Put("/{productID}", h.UpdateOpportunityProduct)
 					r.Delete("/{productID}", h.RemoveOpportunityProduct)
 				})
@@ -97,6 +129,14 @@ func (h *Handler) RegisterRoutes(r chi.Router) {
 					r.Delete("/{contactID}", h.RemoveOpportunityContact)
 					r.Post("/{contactID}/set-primary", h.SetOpportunityPrimaryContact)
 				})
+
+				// Revenue splits among the reps who worked the opportunity
+				r.Route("/revenue-splits", func(r chi.Router) {
+					r.Get("/", h.GetOpportunityRevenueSplits)
+					r.Put("/", h.SetOpportunityRevenueSplits)
+					r.Delete("/", h.ClearOpportunityRevenueSplits)
+					r.Get("/history", h.ListOpportunityRevenueSplitHistory)
+				})
 			})
 		})
 
@@ -112,6 +152,8 @@ func (h *Handler) RegisterRoutes(r chi.Router) {
 			r.Get("/overdue-invoices", h.GetOverdueInvoices)
 			r.Get("/pending-payments", h.GetPendingPayments)
 			r.Get("/revenue", h.GetRevenueByPeriod)
+			r.Get("/recurring-revenue", h.GetRecurringRevenue)
+			r.Get("/renewals", h.GetUpcomingRenewals)
 
 			// Bulk operations
 			r.Post("/bulk/assign", h.BulkAssignDeals)
@@ -132,6 +174,14 @@ func (h *Handler) RegisterRoutes(r chi.Router) {
 				r.Post("/lose", h.LoseDeal)
 				r.Post("/cancel", h.CancelDeal)
 				r.Post("/reopen", h.ReopenDeal)
+				r.Post("/churn", h.ChurnDeal)
+
+				// Production order
+				r.Post("/orders", h.CreateOrderFromDeal)
+
+				// Shipments
+				r.Get("/shipments", h.ListDealShipments)
+				r.Post("/shipments", h.CreateShipment)
 
 				// Line items
 				r.Route("/line-items", func(r chi.Router) {
@@ -146,6 +196,14 @@ func (h *Handler) RegisterRoutes(r chi.Router) {
 					r.Put("/{invoiceID}", h.UpdateDealInvoice)
 					r.Post("/{invoiceID}/issue", h.IssueInvoice)
 					r.Post("/{invoiceID}/cancel", h.CancelInvoice)
+					r.Get("/{invoiceID}/pdf", h.DownloadInvoicePDF)
+
+					// MyInvois e-invoice
+					r.Get("/{invoiceID}/einvoice", h.GetEInvoice)
+					r.Post("/{invoiceID}/einvoice", h.SubmitEInvoice)
+					r.Post("/{invoiceID}/einvoice/resubmit", h.ResubmitEInvoice)
+					r.Post("/{invoiceID}/einvoice/cancel", h.CancelEInvoice)
+					r.Post("/{invoiceID}/accounting-sync", h.SyncInvoiceToAccounting)
 				})
 
 				// Payments
@@ -181,11 +239,22 @@ func (h *Handler) RegisterRoutes(r chi.Router) {
 				r.Get("/velocity", h.GetPipelineVelocity)
 				r.Get("/conversion-rates", h.GetStageConversionRates)
 				r.Get("/forecast", h.GetForecast)
+				r.Get("/funnel", h.GetPipelineFunnel)
+				r.With(h.RequireAnyRole("admin")).Post("/funnel/rebuild", h.RebuildPipelineFunnel)
+
+				// Version history
+				r.Get("/versions", h.ListPipelineVersions)
+				r.Get("/versions/{version}", h.GetPipelineVersion)
+
+				// Opportunity board
+				r.Get("/board", h.GetPipelineBoard)
+				r.With(h.RequireAnyRole("admin")).Post("/board/rebuild", h.RebuildPipelineBoard)
 
 				// Stage operations
 				r.Route("/stages", func(r chi.Router) {
 					r.Post("/", h.AddPipelineStage)
 					r.Put("/reorder", h.ReorderPipelineStages)
+					r.Post("/migrate", h.MigrateStages)
 
 					r.Route("/{stageID}", func(r chi.Router) {
 						r.Put("/", h.UpdatePipelineStage)
@@ -196,7 +265,279 @@ func (h *Handler) RegisterRoutes(r chi.Router) {
 				})
 			})
 		})
+
+		// Presence of collaborators on leads, opportunities and deals
+		r.Route("/presence/{recordType}/{recordID}", func(r chi.Router) {
+			r.Get("/", h.GetPresence)
+			r.Get("/stream", h.StreamPresence)
+			r.Put("/connections/{connectionID}", h.SetPresenceMode)
+		})
+	})
+
+	// Comment threads on leads, opportunities and customers; the history of
+	// edits and deletions is for admins
+	for _, entities := range []string{"leads", "opportunities", "customers"} {
+		r.Route("/api/v1/"+entities+"/{entityID}/comments", func(r chi.Router) {
+			r.Use(h.AuthMiddleware)
+			r.Use(h.TenantMiddleware)
+
+			r.Get("/", h.ListComments)
+			r.Post("/", h.CreateComment)
+
+			r.Route("/{commentID}", func(r chi.Router) {
+				r.Put("/", h.UpdateComment)
+				r.Delete("/", h.DeleteComment)
+				r.Get("/replies", h.ListCommentReplies)
+				r.With(h.RequireAnyRole("admin")).Get("/history", h.GetCommentHistory)
+				r.Post("/reactions", h.AddCommentReaction)
+				r.Delete("/reactions/{reaction}", h.RemoveCommentReaction)
+			})
+		})
+	}
+
+	// Reporting routes
+	r.Route("/api/v1/reports", func(r chi.Router) {
+		r.Use(h.AuthMiddleware)
+		r.Use(h.TenantMiddleware)
+
+		r.Get("/sales-performance", h.GetSalesPerformanceReport)
+		r.Get("/sales-performance/export", h.ExportSalesPerformanceReport)
+		r.Get("/attribution", h.GetAttributionReport)
+		r.Get("/win-loss", h.GetWinLossReport)
+
+		// Asynchronous exports
+		r.Route("/exports", func(r chi.Router) {
+			r.Post("/", h.CreateReportExport)
+			r.Get("/", h.ListReportExports)
+			r.Get("/{exportID}", h.GetReportExport)
+			r.Get("/{exportID}/download", h.DownloadReportExport)
+		})
+
+		// Branding
+		r.Get("/branding", h.GetReportBranding)
+		r.With(h.RequireAnyRole("admin")).Put("/branding", h.UpdateReportBranding)
+
+		// Aggregation maintenance
+		r.With(h.RequireAnyRole("admin")).Post("/aggregations/rebuild", h.RebuildReportAggregates)
+	})
+
+	// Currency routes
+	r.Route("/api/v1/currency", func(r chi.Router) {
+		r.Use(h.AuthMiddleware)
+		r.Use(h.TenantMiddleware)
+
+		r.Get("/settings", h.GetCurrencySettings)
+		r.With(h.RequireAnyRole("admin")).Put("/settings", h.UpdateCurrencySettings)
+		r.Get("/convert", h.ConvertCurrency)
+
+		// Exchange rates
+		r.Route("/rates", func(r chi.Router) {
+			r.Get("/", h.ListExchangeRates)
+			r.With(h.RequireAnyRole("admin")).Post("/", h.SetExchangeRate)
+			r.With(h.RequireAnyRole("admin")).Post("/refresh", h.RefreshExchangeRates)
+			r.With(h.RequireAnyRole("admin")).Delete("/{rateID}", h.DeleteExchangeRate)
+		})
+	})
+
+	// Opportunity reason catalog routes
+	r.Route("/api/v1/opportunity-reasons", func(r chi.Router) {
+		r.Use(h.AuthMiddleware)
+		r.Use(h.TenantMiddleware)
+
+		r.Get("/", h.ListOpportunityReasons)
+		r.With(h.RequireAnyRole("admin")).Post("/", h.CreateOpportunityReason)
+
+		r.Route("/{reasonID}", func(r chi.Router) {
+			r.Get("/", h.GetOpportunityReason)
+			r.With(h.RequireAnyRole("admin")).Put("/", h.UpdateOpportunityReason)
+			r.With(h.RequireAnyRole("admin")).Delete("/", h.DeleteOpportunityReason)
+		})
 	})
+
+	// Discount approval rule routes
+	r.Route("/api/v1/discount-approval-rules", func(r chi.Router) {
+		r.Use(h.AuthMiddleware)
+		r.Use(h.TenantMiddleware)
+
+		r.Get("/", h.ListDiscountApprovalRules)
+		r.With(h.RequireAnyRole("admin")).Post("/", h.CreateDiscountApprovalRule)
+
+		r.Route("/{ruleID}", func(r chi.Router) {
+			r.Get("/", h.GetDiscountApprovalRule)
+			r.With(h.RequireAnyRole("admin")).Put("/", h.UpdateDiscountApprovalRule)
+			r.With(h.RequireAnyRole("admin")).Delete("/", h.DeleteDiscountApprovalRule)
+		})
+	})
+
+	// Discount approval routes; the list holds the current user's approvals to decide
+	r.Route("/api/v1/discount-approvals", func(r chi.Router) {
+		r.Use(h.AuthMiddleware)
+		r.Use(h.TenantMiddleware)
+
+		r.Get("/", h.ListPendingDiscountApprovals)
+
+		r.Route("/{approvalID}", func(r chi.Router) {
+			r.Get("/", h.GetDiscountApproval)
+			r.Post("/approve", h.ApproveDiscount)
+			r.Post("/reject", h.RejectDiscount)
+		})
+	})
+
+	// Production order routes; factory systems update orders through them
+	r.Route("/api/v1/orders", func(r chi.Router) {
+		r.Use(h.AuthMiddleware)
+		r.Use(h.TenantMiddleware)
+
+		r.Get("/", h.ListOrders)
+		r.Get("/webhook", h.GetOrderWebhook)
+		r.With(h.RequireAnyRole("admin")).Put("/webhook", h.UpdateOrderWebhook)
+		r.With(h.RequireAnyRole("admin")).Delete("/webhook", h.DeleteOrderWebhook)
+
+		r.Route("/{orderID}", func(r chi.Router) {
+			r.Get("/", h.GetOrder)
+			r.Put("/", h.UpdateOrder)
+			r.Post("/status", h.UpdateOrderStatus)
+		})
+	})
+
+	// Shipment routes
+	r.Route("/api/v1/shipments", func(r chi.Router) {
+		// Posted by carriers, which are verified by the carrier's webhook parser
+		r.Post("/webhooks/{carrier}", h.ReceiveCarrierWebhook)
+
+		r.Group(func(r chi.Router) {
+			r.Use(h.AuthMiddleware)
+			r.Use(h.TenantMiddleware)
+
+			r.Get("/{shipmentID}", h.GetShipment)
+			r.Put("/{shipmentID}", h.UpdateShipment)
+			r.Post("/{shipmentID}/tracking", h.RecordShipmentTracking)
+		})
+	})
+
+	// E-invoice routes
+	r.Route("/api/v1/einvoice", func(r chi.Router) {
+		r.Use(h.AuthMiddleware)
+		r.Use(h.TenantMiddleware)
+
+		r.Get("/settings", h.GetEInvoiceSettings)
+		r.With(h.RequireAnyRole("admin")).Put("/settings", h.UpdateEInvoiceSettings)
+	})
+
+	// Accounting routes
+	r.Route("/api/v1/accounting", func(r chi.Router) {
+		// Posted by the tenant's accounting connector, which signs with the
+		// connection secret
+		r.Post("/webhooks/{tenantID}", h.ReceiveAccountingWebhook)
+
+		r.Group(func(r chi.Router) {
+			r.Use(h.AuthMiddleware)
+			r.Use(h.TenantMiddleware)
+
+			r.Get("/connection", h.GetAccountingConnection)
+			r.With(h.RequireAnyRole("admin")).Put("/connection", h.UpdateAccountingConnection)
+			r.With(h.RequireAnyRole("admin")).Delete("/connection", h.DeleteAccountingConnection)
+			r.Get("/reconciliation", h.GetAccountingReconciliation)
+			r.Get("/syncs", h.ListAccountingSyncs)
+			r.Post("/syncs/{syncID}/resync", h.ResyncAccountingRecord)
+			r.Post("/resync", h.ResyncAllAccountingRecords)
+		})
+	})
+
+	// E-commerce routes
+	r.Route("/api/v1/ecommerce", func(r chi.Router) {
+		// Pushed by the store, which signs with the store's webhook secret
+		r.Post("/webhooks/{storeID}", h.ReceiveEcommerceWebhook)
+
+		r.Group(func(r chi.Router) {
+			r.Use(h.AuthMiddleware)
+			r.Use(h.TenantMiddleware)
+
+			r.Get("/stores", h.ListEcommerceStores)
+			r.With(h.RequireAnyRole("admin")).Post("/stores", h.CreateEcommerceStore)
+			r.Route("/stores/{storeID}", func(r chi.Router) {
+				r.Get("/", h.GetEcommerceStore)
+				r.With(h.RequireAnyRole("admin")).Put("/", h.UpdateEcommerceStore)
+				r.With(h.RequireAnyRole("admin")).Delete("/", h.DeleteEcommerceStore)
+				r.Get("/status", h.GetEcommerceSyncStatus)
+				r.Get("/imports", h.ListEcommerceImports)
+				r.Post("/sync", h.SyncEcommerceStore)
+			})
+		})
+	})
+
+	// Tax routes
+	r.Route("/api/v1/tax", func(r chi.Router) {
+		r.Use(h.AuthMiddleware)
+		r.Use(h.TenantMiddleware)
+
+		r.Get("/settings", h.GetTaxSettings)
+		r.With(h.RequireAnyRole("admin")).Put("/settings", h.UpdateTaxSettings)
+	})
+
+	// Insight routes
+	r.Route("/api/v1/insights", func(r chi.Router) {
+		r.Use(h.AuthMiddleware)
+		r.Use(h.TenantMiddleware)
+
+		r.Get("/", h.ListInsights)
+		r.Get("/settings", h.GetInsightSettings)
+		r.With(h.RequireAnyRole("admin")).Put("/settings", h.UpdateInsightSettings)
+	})
+
+	// Pricing routes
+	r.Route("/api/v1/pricing", func(r chi.Router) {
+		r.Use(h.AuthMiddleware)
+		r.Use(h.TenantMiddleware)
+
+		r.Get("/price-lists", h.GetPriceLists)
+		r.With(h.RequireAnyRole("admin")).Put("/price-lists", h.UpdatePriceLists)
+	})
+
+	// Inbound email routes
+	r.Route("/api/v1/inbound-email", func(r chi.Router) {
+		// Delivered by the mail relay, which authenticates with a shared secret
+		r.With(h.InboundEmailAuth).Post("/messages", h.ReceiveInboundEmail)
+
+		r.Group(func(r chi.Router) {
+			r.Use(h.AuthMiddleware)
+			r.Use(h.TenantMiddleware)
+
+			r.Get("/mailbox", h.GetInboundMailbox)
+			r.With(h.RequireAnyRole("admin")).Put("/mailbox", h.UpdateInboundMailbox)
+		})
+	})
+
+	// Data retention routes
+	r.Route("/api/v1/retention", func(r chi.Router) {
+		r.Use(h.AuthMiddleware)
+		r.Use(h.TenantMiddleware)
+		r.Use(h.RequireAnyRole("admin"))
+
+		r.Get("/policies", h.ListRetentionPolicies)
+		r.Put("/policies/{entityType}", h.SetRetentionPolicy)
+		r.Delete("/policies/{entityType}", h.DeleteRetentionPolicy)
+
+		r.Get("/archive", h.ListArchivedRecords)
+		r.Post("/archive/{recordID}/restore", h.RestoreArchivedRecord)
+	})
+
+	// Event store routes
+	r.Route("/api/v1/events", func(r chi.Router) {
+		r.Use(h.AuthMiddleware)
+		r.Use(h.TenantMiddleware)
+		r.Use(h.RequireAnyRole("admin"))
+
+		r.Get("/", h.ListEvents)
+	})
+
+	// Internal routes, reachable inside the cluster only as the API gateway
+	// does not route them, and callable by services holding a service token
+	// with the route's scope
+	r.With(h.serviceAuth.Require(serviceauth.ScopeTenantsExport)).Get("/internal/tenants/{tenantID}/export/{dataset}", h.ExportTenantData)
+	r.With(h.serviceAuth.Require(serviceauth.ScopeTenantsDemoData)).Post("/internal/tenants/{tenantID}/demo-data", h.SeedDemoData)
+	r.With(h.serviceAuth.Require(serviceauth.ScopeTenantsDemoData)).Delete("/internal/tenants/{tenantID}/demo-data", h.PurgeDemoData)
+	r.With(h.serviceAuth.Require(serviceauth.ScopeTenantsReassign)).Post("/internal/tenants/{tenantID}/reassign/{dataset}", h.ReassignRecords)
 }
 
 // NewRouter creates a new chi router with all sales routes registered
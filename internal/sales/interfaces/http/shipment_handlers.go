@@ -0,0 +1,186 @@
+package http
+
+import (
+	"io"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/kilang-desa-murni/crm/internal/sales/application/dto"
+)
+
+// maxCarrierWebhookBytes bounds the body of a carrier webhook.
+const maxCarrierWebhookBytes = 1 << 20
+
+// ============================================================================
+// Shipment Handler Methods
+// ============================================================================
+
+// CreateShipment handles POST /deals/{dealID}/shipments
+func (h *Handler) CreateShipment(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	tenantID, err := h.getTenantID(ctx)
+	if err != nil {
+		h.respondError(w, ErrUnauthorized("tenant identification required"))
+		return
+	}
+
+	userID, _ := h.getUserID(ctx)
+	if userID == nil {
+		h.respondError(w, ErrUnauthorized("user identification required"))
+		return
+	}
+
+	dealID, err := h.getUUIDParam(r, "dealID")
+	if err != nil {
+		h.respondError(w, err)
+		return
+	}
+
+	var req dto.CreateShipmentRequest
+	if err := h.decodeJSON(r, &req); err != nil {
+		h.respondError(w, ErrInvalidJSON(err.Error()))
+		return
+	}
+
+	shipment, err := h.shipmentUseCase.Create(ctx, tenantID, dealID, *userID, &req)
+	if err != nil {
+		h.respondError(w, toHTTPError(err))
+		return
+	}
+
+	h.respondJSON(w, http.StatusCreated, shipment)
+}
+
+// ListDealShipments handles GET /deals/{dealID}/shipments
+func (h *Handler) ListDealShipments(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	tenantID, err := h.getTenantID(ctx)
+	if err != nil {
+		h.respondError(w, ErrUnauthorized("tenant identification required"))
+		return
+	}
+
+	dealID, err := h.getUUIDParam(r, "dealID")
+	if err != nil {
+		h.respondError(w, err)
+		return
+	}
+
+	shipments, err := h.shipmentUseCase.ListByDeal(ctx, tenantID, dealID)
+	if err != nil {
+		h.respondError(w, toHTTPError(err))
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, shipments)
+}
+
+// GetShipment handles GET /shipments/{shipmentID}
+func (h *Handler) GetShipment(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	tenantID, err := h.getTenantID(ctx)
+	if err != nil {
+		h.respondError(w, ErrUnauthorized("tenant identification required"))
+		return
+	}
+
+	shipmentID, err := h.getUUIDParam(r, "shipmentID")
+	if err != nil {
+		h.respondError(w, err)
+		return
+	}
+
+	shipment, err := h.shipmentUseCase.GetByID(ctx, tenantID, shipmentID)
+	if err != nil {
+		h.respondError(w, toHTTPError(err))
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, shipment)
+}
+
+// UpdateShipment handles PUT /shipments/{shipmentID}
+func (h *Handler) UpdateShipment(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	tenantID, err := h.getTenantID(ctx)
+	if err != nil {
+		h.respondError(w, ErrUnauthorized("tenant identification required"))
+		return
+	}
+
+	shipmentID, err := h.getUUIDParam(r, "shipmentID")
+	if err != nil {
+		h.respondError(w, err)
+		return
+	}
+
+	var req dto.UpdateShipmentRequest
+	if err := h.decodeJSON(r, &req); err != nil {
+		h.respondError(w, ErrInvalidJSON(err.Error()))
+		return
+	}
+
+	shipment, err := h.shipmentUseCase.Update(ctx, tenantID, shipmentID, &req)
+	if err != nil {
+		h.respondError(w, toHTTPError(err))
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, shipment)
+}
+
+// RecordShipmentTracking handles POST /shipments/{shipmentID}/tracking
+func (h *Handler) RecordShipmentTracking(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	tenantID, err := h.getTenantID(ctx)
+	if err != nil {
+		h.respondError(w, ErrUnauthorized("tenant identification required"))
+		return
+	}
+
+	shipmentID, err := h.getUUIDParam(r, "shipmentID")
+	if err != nil {
+		h.respondError(w, err)
+		return
+	}
+
+	var req dto.RecordShipmentTrackingRequest
+	if err := h.decodeJSON(r, &req); err != nil {
+		h.respondError(w, ErrInvalidJSON(err.Error()))
+		return
+	}
+
+	shipment, err := h.shipmentUseCase.RecordTracking(ctx, tenantID, shipmentID, &req)
+	if err != nil {
+		h.respondError(w, toHTTPError(err))
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, shipment)
+}
+
+// ReceiveCarrierWebhook handles POST /shipments/webhooks/{carrier}
+// The carrier has no user token; its webhook parser verifies the request.
+func (h *Handler) ReceiveCarrierWebhook(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxCarrierWebhookBytes))
+	if err != nil {
+		h.respondError(w, ErrBadRequest("failed to read webhook"))
+		return
+	}
+
+	headers := make(map[string]string, len(r.Header))
+	for key := range r.Header {
+		headers[key] = r.Header.Get(key)
+	}
+
+	result, err := h.shipmentUseCase.ReceiveCarrierWebhook(ctx, chi.URLParam(r, "carrier"), headers, body)
+	if err != nil {
+		h.respondError(w, toHTTPError(err))
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, result)
+}
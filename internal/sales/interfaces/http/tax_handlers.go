@@ -0,0 +1,145 @@
+package http
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/kilang-desa-murni/crm/internal/sales/application/dto"
+)
+
+// ============================================================================
+// Tax Settings Handler Methods
+// ============================================================================
+
+// GetTaxSettings handles GET /tax/settings
+func (h *Handler) GetTaxSettings(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	tenantID, err := h.getTenantID(ctx)
+	if err != nil {
+		h.respondError(w, ErrUnauthorized("tenant identification required"))
+		return
+	}
+
+	settings, err := h.taxUseCase.GetSettings(ctx, tenantID)
+	if err != nil {
+		h.respondError(w, toHTTPError(err))
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, settings)
+}
+
+// UpdateTaxSettings handles PUT /tax/settings
+func (h *Handler) UpdateTaxSettings(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	tenantID, err := h.getTenantID(ctx)
+	if err != nil {
+		h.respondError(w, ErrUnauthorized("tenant identification required"))
+		return
+	}
+
+	var req dto.UpdateTaxSettingsRequest
+	if err := h.decodeJSON(r, &req); err != nil {
+		h.respondError(w, ErrInvalidJSON(err.Error()))
+		return
+	}
+
+	settings, err := h.taxUseCase.UpdateSettings(ctx, tenantID, &req)
+	if err != nil {
+		h.respondError(w, toHTTPError(err))
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, settings)
+}
+
+// ============================================================================
+// Tax Document Handler Methods
+// ============================================================================
+
+// GetOpportunityQuote handles GET /opportunities/{opportunityID}/quote
+func (h *Handler) GetOpportunityQuote(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	tenantID, err := h.getTenantID(ctx)
+	if err != nil {
+		h.respondError(w, ErrUnauthorized("tenant identification required"))
+		return
+	}
+
+	opportunityID, err := h.getUUIDParam(r, "opportunityID")
+	if err != nil {
+		h.respondError(w, err)
+		return
+	}
+
+	quote, err := h.taxUseCase.GetQuote(ctx, tenantID, opportunityID)
+	if err != nil {
+		h.respondError(w, toHTTPError(err))
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, quote)
+}
+
+// DownloadOpportunityQuote handles GET /opportunities/{opportunityID}/quote/pdf
+func (h *Handler) DownloadOpportunityQuote(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	tenantID, err := h.getTenantID(ctx)
+	if err != nil {
+		h.respondError(w, ErrUnauthorized("tenant identification required"))
+		return
+	}
+
+	opportunityID, err := h.getUUIDParam(r, "opportunityID")
+	if err != nil {
+		h.respondError(w, err)
+		return
+	}
+
+	aw := &attachmentWriter{
+		ResponseWriter: w,
+		contentType:    "application/pdf",
+		fileName:       fmt.Sprintf("quotation-%s.pdf", opportunityID),
+	}
+
+	if err := h.taxUseCase.RenderQuote(ctx, tenantID, opportunityID, aw); err != nil {
+		if !aw.started {
+			h.respondError(w, toHTTPError(err))
+		}
+		return
+	}
+}
+
+// DownloadInvoicePDF handles GET /deals/{dealID}/invoices/{invoiceID}/pdf
+func (h *Handler) DownloadInvoicePDF(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	tenantID, err := h.getTenantID(ctx)
+	if err != nil {
+		h.respondError(w, ErrUnauthorized("tenant identification required"))
+		return
+	}
+
+	dealID, err := h.getUUIDParam(r, "dealID")
+	if err != nil {
+		h.respondError(w, err)
+		return
+	}
+	invoiceID, err := h.getUUIDParam(r, "invoiceID")
+	if err != nil {
+		h.respondError(w, err)
+		return
+	}
+
+	aw := &attachmentWriter{
+		ResponseWriter: w,
+		contentType:    "application/pdf",
+		fileName:       fmt.Sprintf("invoice-%s.pdf", invoiceID),
+	}
+
+	if err := h.taxUseCase.RenderInvoice(ctx, tenantID, dealID, invoiceID, aw); err != nil {
+		if !aw.started {
+			h.respondError(w, toHTTPError(err))
+		}
+		return
+	}
+}
@@ -0,0 +1,62 @@
+package http
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/kilang-desa-murni/crm/internal/sales/domain"
+)
+
+// ============================================================================
+// Tenant Data Export Handler Methods
+// ============================================================================
+
+// ExportTenantData handles GET /internal/tenants/{tenantID}/export/{dataset},
+// streaming the tenant's records of the data set as one JSON object per line
+// for the IAM service's tenant exports. The write deadline is lifted as an
+// export may outlast it.
+func (h *Handler) ExportTenantData(w http.ResponseWriter, r *http.Request) {
+	tenantID, err := h.getUUIDParam(r, "tenantID")
+	if err != nil {
+		h.respondError(w, err)
+		return
+	}
+
+	dataset := chi.URLParam(r, "dataset")
+	if dataset != domain.TenantExportLeads && dataset != domain.TenantExportOpportunities {
+		h.respondError(w, ErrNotFound("export data set"))
+		return
+	}
+
+	_ = http.NewResponseController(w).SetWriteDeadline(time.Time{})
+
+	out := &exportWriter{w: w}
+	if _, err := h.tenantExporter.ExportTenantData(r.Context(), tenantID, dataset, out); err != nil {
+		if !out.started {
+			h.respondError(w, toHTTPError(err))
+			return
+		}
+		// The status has been sent; abort the connection so the caller sees
+		// a truncated export rather than a complete one
+		panic(http.ErrAbortHandler)
+	}
+}
+
+// exportWriter sends the response headers on the first write, so an export
+// that fails before writing anything still gets an error response.
+type exportWriter struct {
+	w       http.ResponseWriter
+	started bool
+}
+
+// Write writes p to the response.
+func (e *exportWriter) Write(p []byte) (int, error) {
+	if !e.started {
+		e.started = true
+		e.w.Header().Set("Content-Type", "application/x-ndjson")
+		e.w.WriteHeader(http.StatusOK)
+	}
+	return e.w.Write(p)
+}
@@ -10,8 +10,10 @@ import (
 	amqp "github.com/rabbitmq/amqp091-go"
 	"github.com/redis/go-redis/v9"
 
+	"github.com/kilang-desa-murni/crm/internal/sales/application/ports"
 	"github.com/kilang-desa-murni/crm/internal/sales/application/usecase"
 	"github.com/kilang-desa-murni/crm/internal/sales/domain"
+	"github.com/kilang-desa-murni/crm/internal/sales/infrastructure/cache"
 	"github.com/kilang-desa-murni/crm/internal/sales/infrastructure/messaging"
 	"github.com/kilang-desa-murni/crm/internal/sales/infrastructure/persistence/postgres"
 	saleshttp "github.com/kilang-desa-murni/crm/internal/sales/interfaces/http"
@@ -35,7 +37,58 @@ var RepositorySet = wire.NewSet(
 	postgres.NewPipelineRepository,
 	wire.Bind(new(domain.PipelineRepository), new(*postgres.PipelineRepository)),
 
+	postgres.NewReportRepository,
+	wire.Bind(new(domain.ReportRepository), new(*postgres.ReportRepository)),
+
+	postgres.NewReportExportRepository,
+	wire.Bind(new(domain.ReportExportRepository), new(*postgres.ReportExportRepository)),
+
+	postgres.NewReportBrandingRepository,
+	wire.Bind(new(domain.ReportBrandingRepository), new(*postgres.ReportBrandingRepository)),
+
+	postgres.NewExchangeRateRepository,
+	wire.Bind(new(domain.ExchangeRateRepository), new(*postgres.ExchangeRateRepository)),
+
+	postgres.NewTaxSettingsRepository,
+	wire.Bind(new(domain.TaxSettingsRepository), new(*postgres.TaxSettingsRepository)),
+
 	postgres.NewOutboxRepository,
+
+	postgres.NewEventStore,
+	wire.Bind(new(domain.EventStore), new(*postgres.EventStore)),
+
+	postgres.NewOpportunityBoardRepository,
+	wire.Bind(new(domain.OpportunityBoardRepository), new(*postgres.OpportunityBoardRepository)),
+
+	postgres.NewStageFunnelRepository,
+	wire.Bind(new(domain.StageFunnelRepository), new(*postgres.StageFunnelRepository)),
+
+	postgres.NewOpportunityReasonRepository,
+	wire.Bind(new(domain.OpportunityReasonRepository), new(*postgres.OpportunityReasonRepository)),
+
+	postgres.NewInboundMailboxRepository,
+	wire.Bind(new(domain.InboundMailboxRepository), new(*postgres.InboundMailboxRepository)),
+
+	postgres.NewInboundEmailRepository,
+	wire.Bind(new(domain.InboundEmailRepository), new(*postgres.InboundEmailRepository)),
+
+	postgres.NewRetentionPolicyRepository,
+	wire.Bind(new(domain.RetentionPolicyRepository), new(*postgres.RetentionPolicyRepository)),
+
+	postgres.NewArchivedRecordRepository,
+	wire.Bind(new(domain.ArchivedRecordRepository), new(*postgres.ArchivedRecordRepository)),
+
+	postgres.NewRetentionStore,
+	wire.Bind(new(domain.RetentionStore), new(*postgres.RetentionStore)),
+
+	postgres.NewDemoDataRepository,
+	wire.Bind(new(domain.DemoDataRepository), new(*postgres.DemoDataRepository)),
+
+	postgres.NewCommentRepository,
+	wire.Bind(new(domain.CommentRepository), new(*postgres.CommentRepository)),
+
+	postgres.NewRevenueSplitRepository,
+	wire.Bind(new(domain.RevenueSplitRepository), new(*postgres.RevenueSplitRepository)),
 )
 
 // UseCaseSet provides all use case implementations
@@ -51,6 +104,45 @@ var UseCaseSet = wire.NewSet(
 
 	usecase.NewPipelineUseCase,
 	wire.Bind(new(usecase.PipelineUseCase), new(*usecase.PipelineUseCaseImpl)),
+
+	usecase.NewReportUseCase,
+
+	usecase.NewExchangeRateUseCase,
+	wire.Bind(new(ports.CurrencyConverter), new(usecase.ExchangeRateUseCase)),
+
+	usecase.NewTaxUseCase,
+	wire.Bind(new(ports.TaxRateResolver), new(usecase.TaxUseCase)),
+
+	usecase.NewEventStoreUseCase,
+
+	usecase.NewBoardUseCase,
+	usecase.NewFunnelUseCase,
+
+	usecase.NewOpportunityReasonUseCase,
+
+	usecase.NewAttachmentUseCase,
+
+	usecase.NewInboundEmailUseCase,
+
+	usecase.NewRetentionUseCase,
+
+	usecase.NewDemoDataUseCase,
+
+	usecase.NewOwnerReassignmentUseCase,
+
+	usecase.NewPresenceUseCase,
+
+	usecase.NewCommentUseCase,
+
+	usecase.NewRevenueSplitUseCase,
+)
+
+// CacheSet provides the Redis cache implementation
+var CacheSet = wire.NewSet(
+	cache.DefaultRedisCacheConfig,
+	cache.NewRedisCacheService,
+	wire.Bind(new(ports.CacheService), new(*cache.RedisCacheService)),
+	cache.NewCacheInvalidator,
 )
 
 // MessagingSet provides messaging implementations
@@ -101,6 +193,7 @@ func InitializeSalesService(
 	wire.Build(
 		// Infrastructure
 		RepositorySet,
+		CacheSet,
 		MessagingSet,
 
 		// Application
@@ -0,0 +1,9 @@
+-- ============================================================================
+-- Feature Flags Migration (Rollback)
+-- Version: 000002
+-- Description: Drops the feature flags
+-- ============================================================================
+
+SET search_path TO iam, public;
+
+DROP TABLE IF EXISTS feature_flags;
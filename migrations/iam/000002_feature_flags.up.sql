@@ -0,0 +1,17 @@
+-- ============================================================================
+-- Feature Flags Migration
+-- Version: 000002
+-- Description: Adds the feature flags of the postgres store in
+--              pkg/featureflag, shared by every service
+-- ============================================================================
+
+SET search_path TO iam, public;
+
+-- The definition holds the whole flag (rollout, targeted and excluded
+-- tenants and users) as written by the admin API; services cache the table
+-- and evaluate flags in memory, so it is only ever read in full.
+CREATE TABLE IF NOT EXISTS feature_flags (
+    key VARCHAR(100) PRIMARY KEY,
+    definition JSONB NOT NULL,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
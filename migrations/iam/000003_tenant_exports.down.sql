@@ -0,0 +1,13 @@
+-- ============================================================================
+-- Tenant Exports Migration (Rollback)
+-- Version: 000003
+-- Description: Drops the tenant exports
+-- ============================================================================
+
+SET search_path TO iam, public;
+
+UPDATE roles
+SET permissions = permissions - 'tenants:export'
+WHERE name = 'admin' AND is_system = true;
+
+DROP TABLE IF EXISTS tenant_exports;
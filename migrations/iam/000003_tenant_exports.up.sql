@@ -0,0 +1,42 @@
+-- ============================================================================
+-- Tenant Exports Migration
+-- Version: 000003
+-- Description: Adds full tenant data exports, written as one file per data
+--              set to object storage
+-- ============================================================================
+
+SET search_path TO iam, public;
+
+-- Files holds one entry per data set with its storage key, record count and
+-- size once it has been written, so the progress of a running export can be
+-- read from the row.
+CREATE TABLE IF NOT EXISTS tenant_exports (
+    id UUID PRIMARY KEY,
+    tenant_id UUID NOT NULL REFERENCES tenants(id) ON DELETE CASCADE,
+    requested_by UUID NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending'
+        CHECK (status IN ('pending', 'running', 'completed', 'failed', 'expired')),
+    files JSONB NOT NULL DEFAULT '[]',
+    error TEXT,
+    started_at TIMESTAMP WITH TIME ZONE,
+    completed_at TIMESTAMP WITH TIME ZONE,
+    expires_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_tenant_exports_tenant ON tenant_exports(tenant_id, created_at DESC);
+CREATE INDEX idx_tenant_exports_pending ON tenant_exports(created_at) WHERE status = 'pending';
+CREATE INDEX idx_tenant_exports_expires ON tenant_exports(expires_at) WHERE status = 'completed';
+
+-- A tenant has at most one export in progress
+CREATE UNIQUE INDEX idx_tenant_exports_active ON tenant_exports(tenant_id)
+    WHERE status IN ('pending', 'running');
+
+CREATE TRIGGER update_tenant_exports_updated_at BEFORE UPDATE ON tenant_exports
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+
+-- Tenant administrators may export their tenant's data
+UPDATE roles
+SET permissions = permissions || '["tenants:export"]'::jsonb
+WHERE name = 'admin' AND is_system = true AND NOT permissions ? 'tenants:export';
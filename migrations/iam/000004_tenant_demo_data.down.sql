@@ -0,0 +1,11 @@
+-- ============================================================================
+-- Tenant Demo Data Migration (Rollback)
+-- Version: 000004
+-- Description: Removes the demo data permission
+-- ============================================================================
+
+SET search_path TO iam, public;
+
+UPDATE roles
+SET permissions = permissions - 'tenants:seed'
+WHERE name = 'admin' AND is_system = true;
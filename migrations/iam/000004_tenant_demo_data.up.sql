@@ -0,0 +1,11 @@
+-- ============================================================================
+-- Tenant Demo Data Migration
+-- Version: 000004
+-- Description: Lets tenant administrators seed and purge demo data
+-- ============================================================================
+
+SET search_path TO iam, public;
+
+UPDATE roles
+SET permissions = permissions || '["tenants:seed"]'::jsonb
+WHERE name = 'admin' AND is_system = true AND NOT permissions ? 'tenants:seed';
@@ -0,0 +1,9 @@
+-- ============================================================================
+-- Tenant Custom Domains Migration (Rollback)
+-- Version: 000005
+-- Description: Removes the tenant custom domain index
+-- ============================================================================
+
+SET search_path TO iam, public;
+
+DROP INDEX IF EXISTS idx_tenants_custom_domain;
@@ -0,0 +1,11 @@
+-- ============================================================================
+-- Tenant Custom Domains Migration
+-- Version: 000005
+-- Description: Keeps tenant custom domains unique and quick to look up
+-- ============================================================================
+
+SET search_path TO iam, public;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_tenants_custom_domain
+    ON tenants (LOWER(settings->>'custom_domain'))
+    WHERE deleted_at IS NULL AND settings->>'custom_domain' IS NOT NULL;
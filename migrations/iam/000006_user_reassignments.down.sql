@@ -0,0 +1,9 @@
+-- ============================================================================
+-- User Reassignments Migration (Rollback)
+-- Version: 000006
+-- Description: Drops the user reassignments
+-- ============================================================================
+
+SET search_path TO iam, public;
+
+DROP TABLE IF EXISTS user_reassignments;
@@ -0,0 +1,41 @@
+-- ============================================================================
+-- User Reassignments Migration
+-- Version: 000006
+-- Description: Adds reassignments of the leads, opportunities and customers
+--              a user owns to other users of the tenant
+-- ============================================================================
+
+SET search_path TO iam, public;
+
+-- Recipients holds the users receiving the records with the number each one
+-- received per data set, and datasets the outcome of each data set once it
+-- has been reassigned, so the progress of a running reassignment can be read
+-- from the row.
+CREATE TABLE IF NOT EXISTS user_reassignments (
+    id UUID PRIMARY KEY,
+    tenant_id UUID NOT NULL REFERENCES tenants(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    user_name VARCHAR(255) NOT NULL DEFAULT '',
+    requested_by UUID NOT NULL,
+    strategy VARCHAR(20) NOT NULL
+        CHECK (strategy IN ('round_robin', 'mapping')),
+    recipients JSONB NOT NULL DEFAULT '[]',
+    datasets JSONB NOT NULL DEFAULT '[]',
+    status VARCHAR(20) NOT NULL DEFAULT 'pending'
+        CHECK (status IN ('pending', 'running', 'completed', 'failed')),
+    error TEXT,
+    started_at TIMESTAMP WITH TIME ZONE,
+    completed_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_user_reassignments_user ON user_reassignments(tenant_id, user_id, created_at DESC);
+CREATE INDEX idx_user_reassignments_pending ON user_reassignments(created_at) WHERE status = 'pending';
+
+-- A user has at most one reassignment in progress
+CREATE UNIQUE INDEX idx_user_reassignments_active ON user_reassignments(tenant_id, user_id)
+    WHERE status IN ('pending', 'running');
+
+CREATE TRIGGER update_user_reassignments_updated_at BEFORE UPDATE ON user_reassignments
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
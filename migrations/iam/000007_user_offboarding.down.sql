@@ -0,0 +1,9 @@
+-- ============================================================================
+-- User Offboarding Migration (Rollback)
+-- Version: 000007
+-- Description: Removes the index of users due for anonymization
+-- ============================================================================
+
+SET search_path TO iam, public;
+
+DROP INDEX IF EXISTS idx_users_pending_anonymization;
@@ -0,0 +1,15 @@
+-- ============================================================================
+-- User Offboarding Migration
+-- Version: 000007
+-- Description: Finds offboarded users due for anonymization quickly
+-- ============================================================================
+
+SET search_path TO iam, public;
+
+-- Offboarding records deactivated_at in the user's metadata; the user is
+-- anonymized once the retention period after it has passed, which adds
+-- anonymized_at
+CREATE INDEX IF NOT EXISTS idx_users_pending_anonymization
+    ON users ((metadata->>'deactivated_at'))
+    WHERE status = 'inactive' AND deleted_at IS NULL
+        AND metadata->>'deactivated_at' IS NOT NULL AND metadata->>'anonymized_at' IS NULL;
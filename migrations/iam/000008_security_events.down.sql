@@ -0,0 +1,9 @@
+-- ============================================================================
+-- Security Events Migration (Rollback)
+-- Version: 000008
+-- Description: Drops the security events
+-- ============================================================================
+
+SET search_path TO iam, public;
+
+DROP TABLE IF EXISTS security_events;
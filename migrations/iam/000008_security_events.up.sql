@@ -0,0 +1,30 @@
+-- ============================================================================
+-- Security Events Migration
+-- Version: 000008
+-- Description: Adds the security events of users, such as sign-ins and
+--              password and permission changes
+-- ============================================================================
+
+SET search_path TO iam, public;
+
+-- Suspicious holds why the event was flagged by the security rules, empty
+-- when it was not. Events are only ever inserted.
+CREATE TABLE IF NOT EXISTS security_events (
+    id UUID PRIMARY KEY,
+    tenant_id UUID NOT NULL REFERENCES tenants(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    type VARCHAR(50) NOT NULL
+        CHECK (type IN ('login_succeeded', 'login_failed', 'password_changed', 'permissions_changed', 'impersonation_started')),
+    ip_address VARCHAR(50),
+    user_agent TEXT,
+    device_id VARCHAR(255),
+    country VARCHAR(2),
+    actor_id UUID,
+    details JSONB NOT NULL DEFAULT '{}',
+    suspicious JSONB NOT NULL DEFAULT '[]',
+    reauth_required BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_security_events_user ON security_events(user_id, created_at DESC);
+CREATE INDEX idx_security_events_tenant_user ON security_events(tenant_id, user_id, created_at DESC);
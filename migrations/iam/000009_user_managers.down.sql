@@ -0,0 +1,11 @@
+-- ============================================================================
+-- User Managers Migration (Rollback)
+-- Version: 000009
+-- Description: Removes the managers of users
+-- ============================================================================
+
+SET search_path TO iam, public;
+
+DROP INDEX IF EXISTS idx_users_manager_id;
+
+ALTER TABLE users DROP COLUMN IF EXISTS manager_id;
@@ -0,0 +1,17 @@
+-- ============================================================================
+-- User Managers Migration
+-- Version: 000009
+-- Description: Records the manager each user reports to
+-- ============================================================================
+
+SET search_path TO iam, public;
+
+-- The manager a user reports to, which makes up the organization chart used
+-- to route approvals. Cycles are refused by the service before saving.
+ALTER TABLE users
+    ADD COLUMN IF NOT EXISTS manager_id UUID REFERENCES users(id) ON DELETE SET NULL;
+
+-- Finds a manager's direct reports
+CREATE INDEX IF NOT EXISTS idx_users_manager_id
+    ON users (manager_id)
+    WHERE manager_id IS NOT NULL AND deleted_at IS NULL;
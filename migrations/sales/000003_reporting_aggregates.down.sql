@@ -0,0 +1,10 @@
+-- ============================================================================
+-- Reporting Aggregates Migration (Rollback)
+-- Version: 000003
+-- Description: Drops reporting aggregate tables
+-- ============================================================================
+
+DROP POLICY IF EXISTS tenant_isolation_daily_sales_aggregates ON daily_sales_aggregates;
+
+DROP TABLE IF EXISTS report_aggregation_runs;
+DROP TABLE IF EXISTS daily_sales_aggregates;
@@ -0,0 +1,60 @@
+-- ============================================================================
+-- Reporting Aggregates Migration
+-- Version: 000003
+-- Description: Creates pre-aggregated daily tables for sales reporting
+-- ============================================================================
+
+-- ============================================================================
+-- Daily Sales Aggregates Table
+-- ============================================================================
+
+CREATE TABLE IF NOT EXISTS daily_sales_aggregates (
+    tenant_id UUID NOT NULL,
+    day DATE NOT NULL,
+
+    -- Dimension the row is keyed by (owner, product, region)
+    dimension VARCHAR(20) NOT NULL
+        CHECK (dimension IN ('owner', 'product', 'region')),
+    dimension_key VARCHAR(255) NOT NULL,
+    dimension_label VARCHAR(255) NOT NULL DEFAULT '',
+    -- Empty for lead-only rows, which carry no monetary value
+    currency VARCHAR(3) NOT NULL DEFAULT '',
+
+    -- Lead counters
+    new_leads BIGINT NOT NULL DEFAULT 0,
+    converted_leads BIGINT NOT NULL DEFAULT 0,
+
+    -- Opportunity counters
+    opportunities_created BIGINT NOT NULL DEFAULT 0,
+    opportunities_won BIGINT NOT NULL DEFAULT 0,
+    opportunities_lost BIGINT NOT NULL DEFAULT 0,
+
+    -- Revenue (smallest currency unit)
+    won_revenue BIGINT NOT NULL DEFAULT 0,
+
+    aggregated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+
+    PRIMARY KEY (tenant_id, day, dimension, dimension_key, currency)
+);
+
+CREATE INDEX idx_daily_sales_aggregates_lookup ON daily_sales_aggregates(tenant_id, dimension, day);
+CREATE INDEX idx_daily_sales_aggregates_day ON daily_sales_aggregates(day);
+
+ALTER TABLE daily_sales_aggregates ENABLE ROW LEVEL SECURITY;
+
+CREATE POLICY tenant_isolation_daily_sales_aggregates ON daily_sales_aggregates
+    USING (tenant_id = current_setting('app.tenant_id', true)::uuid);
+
+-- ============================================================================
+-- Aggregation Runs Table
+-- ============================================================================
+
+CREATE TABLE IF NOT EXISTS report_aggregation_runs (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    day DATE NOT NULL,
+    rows_written BIGINT NOT NULL DEFAULT 0,
+    started_at TIMESTAMPTZ NOT NULL,
+    completed_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_report_aggregation_runs_completed_at ON report_aggregation_runs(completed_at DESC);
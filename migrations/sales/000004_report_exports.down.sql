@@ -0,0 +1,11 @@
+-- ============================================================================
+-- Report Exports Migration (Rollback)
+-- Version: 000004
+-- Description: Drops report export and branding tables
+-- ============================================================================
+
+DROP POLICY IF EXISTS tenant_isolation_report_branding ON report_branding;
+DROP POLICY IF EXISTS tenant_isolation_report_exports ON report_exports;
+
+DROP TABLE IF EXISTS report_branding;
+DROP TABLE IF EXISTS report_exports;
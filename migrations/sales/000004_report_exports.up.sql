@@ -0,0 +1,61 @@
+-- ============================================================================
+-- Report Exports Migration
+-- Version: 000004
+-- Description: Creates tables for asynchronous report exports and report branding
+-- ============================================================================
+
+-- ============================================================================
+-- Report Exports Table
+-- ============================================================================
+
+CREATE TABLE IF NOT EXISTS report_exports (
+    id UUID PRIMARY KEY,
+    tenant_id UUID NOT NULL,
+    requested_by UUID NOT NULL,
+
+    report_type VARCHAR(50) NOT NULL,
+    format VARCHAR(10) NOT NULL
+        CHECK (format IN ('xlsx', 'pdf')),
+    parameters JSONB NOT NULL DEFAULT '{}',
+
+    status VARCHAR(20) NOT NULL DEFAULT 'pending'
+        CHECK (status IN ('pending', 'processing', 'completed', 'failed')),
+
+    -- Generated file
+    file_id VARCHAR(255),
+    file_name VARCHAR(255),
+    file_size BIGINT NOT NULL DEFAULT 0,
+    error TEXT,
+
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    completed_at TIMESTAMPTZ,
+    expires_at TIMESTAMPTZ
+);
+
+CREATE INDEX idx_report_exports_requested_by ON report_exports(tenant_id, requested_by, created_at DESC);
+CREATE INDEX idx_report_exports_expires_at ON report_exports(expires_at) WHERE expires_at IS NOT NULL;
+
+ALTER TABLE report_exports ENABLE ROW LEVEL SECURITY;
+
+CREATE POLICY tenant_isolation_report_exports ON report_exports
+    USING (tenant_id = current_setting('app.tenant_id', true)::uuid);
+
+-- ============================================================================
+-- Report Branding Table
+-- ============================================================================
+
+CREATE TABLE IF NOT EXISTS report_branding (
+    tenant_id UUID PRIMARY KEY,
+    company_name VARCHAR(255) NOT NULL,
+    logo_file_id VARCHAR(255),
+    locale VARCHAR(10) NOT NULL DEFAULT 'en-MY'
+        CHECK (locale IN ('en-MY', 'ms-MY')),
+    currency_display VARCHAR(10) NOT NULL DEFAULT 'symbol'
+        CHECK (currency_display IN ('symbol', 'code')),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+ALTER TABLE report_branding ENABLE ROW LEVEL SECURITY;
+
+CREATE POLICY tenant_isolation_report_branding ON report_branding
+    USING (tenant_id = current_setting('app.tenant_id', true)::uuid);
@@ -0,0 +1,18 @@
+-- ============================================================================
+-- Exchange Rates Migration (Rollback)
+-- Version: 000005
+-- Description: Drops exchange rate tables and opportunity base currency values
+-- ============================================================================
+
+DROP INDEX IF EXISTS idx_opportunities_base_currency;
+
+ALTER TABLE opportunities
+    DROP COLUMN IF EXISTS exchange_rate,
+    DROP COLUMN IF EXISTS base_currency,
+    DROP COLUMN IF EXISTS base_weighted_amount,
+    DROP COLUMN IF EXISTS base_amount;
+
+DROP POLICY IF EXISTS tenant_isolation_exchange_rates ON exchange_rates;
+
+DROP TABLE IF EXISTS currency_settings;
+DROP TABLE IF EXISTS exchange_rates;
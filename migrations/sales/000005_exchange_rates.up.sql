@@ -0,0 +1,67 @@
+-- ============================================================================
+-- Exchange Rates Migration
+-- Version: 000005
+-- Description: Adds per-tenant exchange rates, currency settings and base
+--              currency values on opportunities
+-- ============================================================================
+
+-- ============================================================================
+-- Exchange Rates Table
+-- ============================================================================
+
+CREATE TABLE IF NOT EXISTS exchange_rates (
+    id UUID PRIMARY KEY,
+    tenant_id UUID NOT NULL,
+
+    from_currency VARCHAR(3) NOT NULL,
+    to_currency VARCHAR(3) NOT NULL,
+    rate NUMERIC(24, 12) NOT NULL CHECK (rate > 0),
+    effective_date DATE NOT NULL,
+    source VARCHAR(20) NOT NULL DEFAULT 'manual'
+        CHECK (source IN ('manual', 'ecb', 'bnm')),
+
+    created_by UUID,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+
+    CONSTRAINT chk_exchange_rates_pair CHECK (from_currency <> to_currency),
+    CONSTRAINT uq_exchange_rates_pair_date_source
+        UNIQUE (tenant_id, from_currency, to_currency, effective_date, source)
+);
+
+CREATE INDEX idx_exchange_rates_lookup
+    ON exchange_rates(tenant_id, from_currency, to_currency, effective_date DESC);
+
+ALTER TABLE exchange_rates ENABLE ROW LEVEL SECURITY;
+
+CREATE POLICY tenant_isolation_exchange_rates ON exchange_rates
+    USING (tenant_id = current_setting('app.tenant_id', true)::uuid);
+
+-- ============================================================================
+-- Currency Settings Table
+-- ============================================================================
+
+CREATE TABLE IF NOT EXISTS currency_settings (
+    tenant_id UUID PRIMARY KEY,
+    base_currency VARCHAR(3) NOT NULL DEFAULT 'MYR',
+    rate_provider VARCHAR(20)
+        CHECK (rate_provider IN ('ecb', 'bnm')),
+    last_fetched_at TIMESTAMPTZ,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- No row level security: the rate refresh worker lists providers across tenants.
+
+-- ============================================================================
+-- Opportunity Base Currency Values
+-- ============================================================================
+
+ALTER TABLE opportunities
+    ADD COLUMN IF NOT EXISTS base_amount BIGINT,
+    ADD COLUMN IF NOT EXISTS base_weighted_amount BIGINT,
+    ADD COLUMN IF NOT EXISTS base_currency VARCHAR(3),
+    ADD COLUMN IF NOT EXISTS exchange_rate NUMERIC(24, 12);
+
+CREATE INDEX idx_opportunities_base_currency
+    ON opportunities(tenant_id, currency, base_currency)
+    WHERE status = 'open' AND deleted_at IS NULL;
@@ -0,0 +1,26 @@
+-- ============================================================================
+-- Tax Settings Migration (Rollback)
+-- Version: 000006
+-- Description: Drops tax settings and document tax fields
+-- ============================================================================
+
+ALTER TABLE IF EXISTS deal_invoices
+    DROP COLUMN IF EXISTS tax_lines,
+    DROP COLUMN IF EXISTS tax_amount,
+    DROP COLUMN IF EXISTS subtotal;
+
+ALTER TABLE deal_line_items
+    DROP COLUMN IF EXISTS tax_inclusive,
+    DROP COLUMN IF EXISTS tax_name,
+    DROP COLUMN IF EXISTS tax_code;
+
+ALTER TABLE opportunity_products
+    DROP COLUMN IF EXISTS tax_amount,
+    DROP COLUMN IF EXISTS tax_inclusive,
+    DROP COLUMN IF EXISTS tax_name,
+    DROP COLUMN IF EXISTS tax_code,
+    DROP COLUMN IF EXISTS tax;
+
+DROP POLICY IF EXISTS tenant_isolation_tax_settings ON tax_settings;
+
+DROP TABLE IF EXISTS tax_settings;
@@ -0,0 +1,56 @@
+-- ============================================================================
+-- Tax Settings Migration
+-- Version: 000006
+-- Description: Adds per-tenant SST/GST configuration and tax fields on
+--              opportunity products, deal line items and invoices
+-- ============================================================================
+
+-- ============================================================================
+-- Tax Settings Table
+-- ============================================================================
+
+CREATE TABLE IF NOT EXISTS tax_settings (
+    tenant_id UUID PRIMARY KEY,
+    enabled BOOLEAN NOT NULL DEFAULT FALSE,
+    registration_number VARCHAR(50),
+    prices_include_tax BOOLEAN NOT NULL DEFAULT FALSE,
+    rates JSONB NOT NULL DEFAULT '[]',
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+
+    CONSTRAINT chk_tax_settings_registration
+        CHECK (NOT enabled OR registration_number IS NOT NULL)
+);
+
+ALTER TABLE tax_settings ENABLE ROW LEVEL SECURITY;
+
+CREATE POLICY tenant_isolation_tax_settings ON tax_settings
+    USING (tenant_id = current_setting('app.tenant_id', true)::uuid);
+
+-- ============================================================================
+-- Opportunity Product Tax
+-- ============================================================================
+
+ALTER TABLE opportunity_products
+    ADD COLUMN IF NOT EXISTS tax NUMERIC(5, 2) NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS tax_code VARCHAR(20),
+    ADD COLUMN IF NOT EXISTS tax_name VARCHAR(100),
+    ADD COLUMN IF NOT EXISTS tax_inclusive BOOLEAN NOT NULL DEFAULT FALSE,
+    ADD COLUMN IF NOT EXISTS tax_amount BIGINT NOT NULL DEFAULT 0;
+
+-- ============================================================================
+-- Deal Line Item Tax
+-- ============================================================================
+
+ALTER TABLE deal_line_items
+    ADD COLUMN IF NOT EXISTS tax_code VARCHAR(20),
+    ADD COLUMN IF NOT EXISTS tax_name VARCHAR(100),
+    ADD COLUMN IF NOT EXISTS tax_inclusive BOOLEAN NOT NULL DEFAULT FALSE;
+
+-- ============================================================================
+-- Invoice Tax Breakdown
+-- ============================================================================
+
+ALTER TABLE IF EXISTS deal_invoices
+    ADD COLUMN IF NOT EXISTS subtotal BIGINT,
+    ADD COLUMN IF NOT EXISTS tax_amount BIGINT NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS tax_lines JSONB NOT NULL DEFAULT '[]';
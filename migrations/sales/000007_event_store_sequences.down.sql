@@ -0,0 +1,21 @@
+-- ============================================================================
+-- Event Store Sequences Migration (Rollback)
+-- Version: 000007
+-- Description: Drops event positions, sequences and the append-only guard
+-- ============================================================================
+
+DROP TRIGGER IF EXISTS sales_domain_events_append_only ON sales_domain_events;
+
+DROP FUNCTION IF EXISTS reject_sales_domain_event_change();
+
+DROP INDEX IF EXISTS idx_sales_events_tenant_position;
+
+ALTER TABLE sales_domain_events
+    DROP CONSTRAINT IF EXISTS uq_sales_domain_events_aggregate_sequence,
+    DROP CONSTRAINT IF EXISTS uq_sales_domain_events_position,
+    DROP COLUMN IF EXISTS sequence,
+    DROP COLUMN IF EXISTS position;
+
+DROP POLICY IF EXISTS tenant_isolation_sales_event_streams ON sales_event_streams;
+
+DROP TABLE IF EXISTS sales_event_streams;
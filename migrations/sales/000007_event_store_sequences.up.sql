@@ -0,0 +1,74 @@
+-- ============================================================================
+-- Event Store Sequences Migration
+-- Version: 000007
+-- Description: Makes sales_domain_events an append-only audit stream with a
+--              global position and a per-aggregate sequence number, so events
+--              can be queried in order and replayed from a point in time
+-- ============================================================================
+
+-- ============================================================================
+-- Event Streams Table
+-- ============================================================================
+
+-- One row per aggregate holding the last sequence number handed out. Bumping
+-- the row locks it, which serialises concurrent appends to the same aggregate.
+CREATE TABLE IF NOT EXISTS sales_event_streams (
+    aggregate_id UUID PRIMARY KEY,
+    tenant_id UUID NOT NULL,
+    aggregate_type VARCHAR(100) NOT NULL,
+    last_sequence BIGINT NOT NULL,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_sales_event_streams_tenant_id ON sales_event_streams(tenant_id);
+
+ALTER TABLE sales_event_streams ENABLE ROW LEVEL SECURITY;
+
+CREATE POLICY tenant_isolation_sales_event_streams ON sales_event_streams
+    USING (tenant_id = current_setting('app.tenant_id', true)::uuid);
+
+-- ============================================================================
+-- Event Positions and Sequences
+-- ============================================================================
+
+-- Existing rows are numbered in insertion order
+ALTER TABLE sales_domain_events
+    ADD COLUMN IF NOT EXISTS position BIGSERIAL,
+    ADD COLUMN IF NOT EXISTS sequence BIGINT;
+
+UPDATE sales_domain_events e
+SET sequence = numbered.sequence
+FROM (
+    SELECT id, ROW_NUMBER() OVER (
+        PARTITION BY aggregate_id ORDER BY occurred_at, created_at, position
+    ) AS sequence
+    FROM sales_domain_events
+) numbered
+WHERE e.id = numbered.id;
+
+ALTER TABLE sales_domain_events
+    ALTER COLUMN sequence SET NOT NULL,
+    ADD CONSTRAINT uq_sales_domain_events_position UNIQUE (position),
+    ADD CONSTRAINT uq_sales_domain_events_aggregate_sequence UNIQUE (aggregate_id, sequence);
+
+CREATE INDEX idx_sales_events_tenant_position ON sales_domain_events(tenant_id, position);
+
+INSERT INTO sales_event_streams (aggregate_id, tenant_id, aggregate_type, last_sequence)
+SELECT aggregate_id, MIN(tenant_id::text)::uuid, MIN(aggregate_type), MAX(sequence)
+FROM sales_domain_events
+GROUP BY aggregate_id
+ON CONFLICT (aggregate_id) DO NOTHING;
+
+-- ============================================================================
+-- Append-Only Guard
+-- ============================================================================
+
+CREATE OR REPLACE FUNCTION reject_sales_domain_event_change()
+RETURNS TRIGGER AS $$
+BEGIN
+    RAISE EXCEPTION 'sales_domain_events is append-only: % is not allowed', TG_OP;
+END;
+$$ language 'plpgsql';
+
+CREATE TRIGGER sales_domain_events_append_only BEFORE UPDATE OR DELETE ON sales_domain_events
+    FOR EACH ROW EXECUTE FUNCTION reject_sales_domain_event_change();
@@ -0,0 +1,9 @@
+-- ============================================================================
+-- Opportunity Board Migration (Rollback)
+-- Version: 000008
+-- Description: Drops the opportunity board read model
+-- ============================================================================
+
+DROP POLICY IF EXISTS tenant_isolation_opportunity_board_cards ON opportunity_board_cards;
+
+DROP TABLE IF EXISTS opportunity_board_cards;
@@ -0,0 +1,41 @@
+-- ============================================================================
+-- Opportunity Board Migration
+-- Version: 000008
+-- Description: Adds the denormalized opportunity board read model that backs
+--              the pipeline Kanban view, projected from opportunity events
+-- ============================================================================
+
+-- ============================================================================
+-- Opportunity Board Cards Table
+-- ============================================================================
+
+CREATE TABLE IF NOT EXISTS opportunity_board_cards (
+    opportunity_id UUID PRIMARY KEY,
+    tenant_id UUID NOT NULL,
+    pipeline_id UUID NOT NULL,
+    stage_id UUID NOT NULL,
+    code VARCHAR(50) NOT NULL,
+    name VARCHAR(255) NOT NULL,
+    status VARCHAR(20) NOT NULL,
+    priority VARCHAR(20) NOT NULL,
+    customer_name VARCHAR(255) NOT NULL DEFAULT '',
+    owner_id UUID NOT NULL,
+    owner_name VARCHAR(255) NOT NULL DEFAULT '',
+    amount BIGINT NOT NULL DEFAULT 0,
+    weighted_amount BIGINT NOT NULL DEFAULT 0,
+    currency VARCHAR(3) NOT NULL,
+    probability INTEGER NOT NULL DEFAULT 0,
+    expected_close_date TIMESTAMPTZ,
+    stage_entered_at TIMESTAMPTZ NOT NULL,
+    version INTEGER NOT NULL,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- Serves column pages in board order and the per-column totals
+CREATE INDEX idx_opportunity_board_cards_column
+    ON opportunity_board_cards(tenant_id, pipeline_id, stage_id, stage_entered_at DESC, opportunity_id DESC);
+
+ALTER TABLE opportunity_board_cards ENABLE ROW LEVEL SECURITY;
+
+CREATE POLICY tenant_isolation_opportunity_board_cards ON opportunity_board_cards
+    USING (tenant_id = current_setting('app.tenant_id', true)::uuid);
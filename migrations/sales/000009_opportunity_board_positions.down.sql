@@ -0,0 +1,18 @@
+-- ============================================================================
+-- Opportunity Board Positions Migration (Rollback)
+-- Version: 000009
+-- Description: Drops board position keys and restores time-ordered board columns
+-- ============================================================================
+
+DROP INDEX IF EXISTS idx_opportunity_board_cards_column;
+
+ALTER TABLE opportunity_board_cards
+    DROP COLUMN IF EXISTS board_position;
+
+CREATE INDEX idx_opportunity_board_cards_column
+    ON opportunity_board_cards(tenant_id, pipeline_id, stage_id, stage_entered_at DESC, opportunity_id DESC);
+
+DROP INDEX IF EXISTS idx_opportunities_board_position;
+
+ALTER TABLE opportunities
+    DROP COLUMN IF EXISTS board_position;
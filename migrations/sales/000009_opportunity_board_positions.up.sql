@@ -0,0 +1,52 @@
+-- ============================================================================
+-- Opportunity Board Positions Migration
+-- Version: 000009
+-- Description: Adds fractional board position keys to opportunities so cards
+--              can be reordered within a stage without renumbering the column
+-- ============================================================================
+
+-- ============================================================================
+-- Opportunity Positions
+-- ============================================================================
+
+-- Keys compare byte by byte, so the column must not use a linguistic collation
+ALTER TABLE opportunities
+    ADD COLUMN IF NOT EXISTS board_position TEXT COLLATE "C";
+
+-- Default keys order cards by the time they entered the stage, newest first
+UPDATE opportunities
+SET board_position = LPAD(
+        (9223372036854775807 - FLOOR(EXTRACT(EPOCH FROM stage_entered_at) * 1000000000)::BIGINT)::TEXT,
+        19, '0') || 'V'
+WHERE board_position IS NULL;
+
+ALTER TABLE opportunities
+    ALTER COLUMN board_position SET NOT NULL;
+
+CREATE INDEX IF NOT EXISTS idx_opportunities_board_position
+    ON opportunities(tenant_id, stage_id, board_position, id)
+    WHERE deleted_at IS NULL;
+
+-- ============================================================================
+-- Board Card Positions
+-- ============================================================================
+
+ALTER TABLE opportunity_board_cards
+    ADD COLUMN IF NOT EXISTS board_position TEXT COLLATE "C";
+
+UPDATE opportunity_board_cards c
+SET board_position = o.board_position
+FROM opportunities o
+WHERE o.id = c.opportunity_id;
+
+-- Cards without an opportunity are stale and are dropped
+DELETE FROM opportunity_board_cards WHERE board_position IS NULL;
+
+ALTER TABLE opportunity_board_cards
+    ALTER COLUMN board_position SET NOT NULL;
+
+DROP INDEX IF EXISTS idx_opportunity_board_cards_column;
+
+-- Serves column pages in board order and the per-column totals
+CREATE INDEX idx_opportunity_board_cards_column
+    ON opportunity_board_cards(tenant_id, pipeline_id, stage_id, board_position, opportunity_id);
@@ -0,0 +1,10 @@
+-- ============================================================================
+-- Pipeline Versions Migration (Rollback)
+-- Version: 000010
+-- Description: Drops pipeline version history
+-- ============================================================================
+
+ALTER TABLE opportunity_stage_history
+    DROP COLUMN IF EXISTS pipeline_version;
+
+DROP TABLE IF EXISTS pipeline_versions;
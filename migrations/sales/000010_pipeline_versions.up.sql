@@ -0,0 +1,69 @@
+-- ============================================================================
+-- Pipeline Versions Migration
+-- Version: 000010
+-- Description: Records every saved version of a pipeline's stage layout and
+--              tags stage history with the pipeline version it happened under
+-- ============================================================================
+
+-- ============================================================================
+-- Pipeline Versions Table
+-- ============================================================================
+
+CREATE TABLE IF NOT EXISTS pipeline_versions (
+    pipeline_id UUID NOT NULL REFERENCES pipelines(id) ON DELETE CASCADE,
+    tenant_id UUID NOT NULL,
+    version INTEGER NOT NULL,
+    name VARCHAR(255) NOT NULL,
+    currency VARCHAR(3) NOT NULL,
+    stages JSONB NOT NULL DEFAULT '[]',
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (pipeline_id, version)
+);
+
+CREATE INDEX idx_pipeline_versions_tenant_id ON pipeline_versions(tenant_id);
+
+ALTER TABLE pipeline_versions ENABLE ROW LEVEL SECURITY;
+
+CREATE POLICY tenant_isolation_pipeline_versions ON pipeline_versions
+    USING (tenant_id = current_setting('app.tenant_id', true)::uuid);
+
+-- Existing pipelines start their history at their current version
+INSERT INTO pipeline_versions (pipeline_id, tenant_id, version, name, currency, stages, created_at)
+SELECT p.id, p.tenant_id, p.version, p.name, p.currency,
+    COALESCE((
+        SELECT jsonb_agg(jsonb_build_object(
+                'id', s.id,
+                'pipeline_id', s.pipeline_id,
+                'name', s.name,
+                'description', COALESCE(s.description, ''),
+                'type', s.type,
+                'order', s.stage_order,
+                'probability', s.probability,
+                'color', COALESCE(s.color, ''),
+                'is_active', s.is_active,
+                'rotten_days', s.rotten_days,
+                'auto_actions', s.auto_actions,
+                'created_at', s.created_at,
+                'updated_at', s.updated_at
+            ) ORDER BY s.stage_order)
+        FROM pipeline_stages s
+        WHERE s.pipeline_id = p.id
+    ), '[]'::jsonb),
+    p.updated_at
+FROM pipelines p
+WHERE p.deleted_at IS NULL
+ON CONFLICT (pipeline_id, version) DO NOTHING;
+
+-- ============================================================================
+-- Stage History Versions
+-- ============================================================================
+
+ALTER TABLE opportunity_stage_history
+    ADD COLUMN IF NOT EXISTS pipeline_version INTEGER;
+
+-- History recorded before versioning is attributed to the current version
+UPDATE opportunity_stage_history h
+SET pipeline_version = p.version
+FROM opportunities o
+JOIN pipelines p ON p.id = o.pipeline_id
+WHERE o.id = h.opportunity_id AND h.pipeline_version IS NULL;
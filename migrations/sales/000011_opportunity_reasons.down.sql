@@ -0,0 +1,14 @@
+-- ============================================================================
+-- Opportunity Reasons Migration (Rollback)
+-- Version: 000011
+-- Description: Drops win and loss reason catalogs
+-- ============================================================================
+
+DROP INDEX IF EXISTS idx_opportunities_close_reason;
+
+ALTER TABLE opportunities
+    DROP COLUMN IF EXISTS close_reason_id;
+
+DROP POLICY IF EXISTS tenant_isolation_opportunity_reasons ON opportunity_reasons;
+
+DROP TABLE IF EXISTS opportunity_reasons;
@@ -0,0 +1,48 @@
+-- ============================================================================
+-- Opportunity Reasons Migration
+-- Version: 000011
+-- Description: Adds per-tenant win and loss reason catalogs and links closed
+--              opportunities to the reason selected when closing
+-- ============================================================================
+
+-- ============================================================================
+-- Opportunity Reasons Table
+-- ============================================================================
+
+CREATE TABLE IF NOT EXISTS opportunity_reasons (
+    id UUID PRIMARY KEY,
+    tenant_id UUID NOT NULL,
+
+    type VARCHAR(10) NOT NULL CHECK (type IN ('won', 'lost')),
+    name VARCHAR(200) NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    is_active BOOLEAN NOT NULL DEFAULT TRUE,
+    sort_order INTEGER NOT NULL DEFAULT 0,
+
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+
+    CONSTRAINT uq_opportunity_reasons_name UNIQUE (tenant_id, type, name)
+);
+
+CREATE INDEX idx_opportunity_reasons_tenant_type
+    ON opportunity_reasons(tenant_id, type, sort_order);
+
+ALTER TABLE opportunity_reasons ENABLE ROW LEVEL SECURITY;
+
+CREATE POLICY tenant_isolation_opportunity_reasons ON opportunity_reasons
+    USING (tenant_id = current_setting('app.tenant_id', true)::uuid);
+
+CREATE TRIGGER update_opportunity_reasons_updated_at BEFORE UPDATE ON opportunity_reasons
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+
+-- ============================================================================
+-- Opportunity Close Reasons
+-- ============================================================================
+
+ALTER TABLE opportunities
+    ADD COLUMN IF NOT EXISTS close_reason_id UUID REFERENCES opportunity_reasons(id);
+
+CREATE INDEX idx_opportunities_close_reason
+    ON opportunities(tenant_id, close_reason_id)
+    WHERE close_reason_id IS NOT NULL;
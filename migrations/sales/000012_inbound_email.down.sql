@@ -0,0 +1,11 @@
+-- ============================================================================
+-- Inbound Email Migration (Rollback)
+-- Version: 000012
+-- Description: Drops inbound mailboxes and the inbound email log
+-- ============================================================================
+
+DROP POLICY IF EXISTS tenant_isolation_inbound_emails ON inbound_emails;
+
+DROP TABLE IF EXISTS inbound_emails;
+
+DROP TABLE IF EXISTS inbound_mailboxes;
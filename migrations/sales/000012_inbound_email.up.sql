@@ -0,0 +1,65 @@
+-- ============================================================================
+-- Inbound Email Migration
+-- Version: 000012
+-- Description: Adds per-tenant forwarding addresses for emailed enquiries and
+--              the log of emails ingested from them
+-- ============================================================================
+
+-- ============================================================================
+-- Inbound Mailboxes Table
+-- ============================================================================
+
+-- No row level security: the mail relay delivers to an address, and the
+-- mailbox must be found by its token before the tenant is known.
+CREATE TABLE IF NOT EXISTS inbound_mailboxes (
+    tenant_id UUID PRIMARY KEY,
+    token VARCHAR(64) NOT NULL,
+    enabled BOOLEAN NOT NULL DEFAULT FALSE,
+    default_owner_id UUID,
+
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+
+    CONSTRAINT uq_inbound_mailboxes_token UNIQUE (token)
+);
+
+CREATE TRIGGER update_inbound_mailboxes_updated_at BEFORE UPDATE ON inbound_mailboxes
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+
+-- ============================================================================
+-- Inbound Emails Table
+-- ============================================================================
+
+CREATE TABLE IF NOT EXISTS inbound_emails (
+    id UUID PRIMARY KEY,
+    tenant_id UUID NOT NULL,
+
+    message_id VARCHAR(998) NOT NULL,
+    from_address VARCHAR(320) NOT NULL,
+    from_name VARCHAR(255),
+    subject TEXT NOT NULL DEFAULT '',
+    body TEXT NOT NULL DEFAULT '',
+
+    lead_id UUID REFERENCES leads(id) ON DELETE SET NULL,
+    opportunity_id UUID REFERENCES opportunities(id) ON DELETE SET NULL,
+    lead_created BOOLEAN NOT NULL DEFAULT FALSE,
+    attachment_ids JSONB NOT NULL DEFAULT '[]',
+
+    received_at TIMESTAMPTZ NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+
+    CONSTRAINT uq_inbound_emails_message UNIQUE (tenant_id, message_id)
+);
+
+CREATE INDEX idx_inbound_emails_lead
+    ON inbound_emails(tenant_id, lead_id, received_at DESC)
+    WHERE lead_id IS NOT NULL;
+
+-- Supports the per-sender rate limit
+CREATE INDEX idx_inbound_emails_sender
+    ON inbound_emails(tenant_id, from_address, created_at DESC);
+
+ALTER TABLE inbound_emails ENABLE ROW LEVEL SECURITY;
+
+CREATE POLICY tenant_isolation_inbound_emails ON inbound_emails
+    USING (tenant_id = current_setting('app.tenant_id', true)::uuid);
@@ -0,0 +1,11 @@
+-- ============================================================================
+-- Keyset Pagination Migration (Rollback)
+-- Version: 000013
+-- Description: Drops the keyset pagination indexes
+-- ============================================================================
+
+DROP INDEX IF EXISTS idx_deals_keyset_created_at;
+
+DROP INDEX IF EXISTS idx_opportunities_keyset_created_at;
+
+DROP INDEX IF EXISTS idx_leads_keyset_created_at;
@@ -0,0 +1,15 @@
+-- ============================================================================
+-- Keyset Pagination Migration
+-- Version: 000013
+-- Description: Adds indexes matching the default list order (newest first,
+--              ID as tie-breaker) so cursor pages are index range scans
+-- ============================================================================
+
+CREATE INDEX IF NOT EXISTS idx_leads_keyset_created_at
+    ON leads(tenant_id, created_at DESC, id DESC) WHERE deleted_at IS NULL;
+
+CREATE INDEX IF NOT EXISTS idx_opportunities_keyset_created_at
+    ON opportunities(tenant_id, created_at DESC, id DESC) WHERE deleted_at IS NULL;
+
+CREATE INDEX IF NOT EXISTS idx_deals_keyset_created_at
+    ON deals(tenant_id, created_at DESC, id DESC) WHERE deleted_at IS NULL;
@@ -0,0 +1,7 @@
+-- ============================================================================
+-- Workflow Sagas Migration (Rollback)
+-- Version: 000014
+-- Description: Drops the workflow saga state
+-- ============================================================================
+
+DROP TABLE IF EXISTS workflow_sagas;
@@ -0,0 +1,40 @@
+-- ============================================================================
+-- Workflow Sagas Migration
+-- Version: 000014
+-- Description: Adds the state of cross-service workflows run by the saga
+--              manager in pkg/events, persisted after every step
+-- ============================================================================
+
+-- ============================================================================
+-- Workflow Sagas Table
+-- ============================================================================
+
+-- No row level security: the admin endpoint lists stuck sagas of every
+-- tenant, and the manager resumes them without a tenant in context.
+CREATE TABLE IF NOT EXISTS workflow_sagas (
+    id UUID PRIMARY KEY,
+    tenant_id VARCHAR(64) NOT NULL DEFAULT '',
+    saga_type VARCHAR(100) NOT NULL,
+    status VARCHAR(20) NOT NULL,
+    current_step INTEGER NOT NULL DEFAULT 0,
+    steps JSONB NOT NULL DEFAULT '[]',
+    data JSONB NOT NULL DEFAULT '{}',
+    error TEXT,
+    abort_reason TEXT,
+
+    deadline TIMESTAMPTZ,
+    started_at TIMESTAMPTZ NOT NULL,
+    updated_at TIMESTAMPTZ NOT NULL,
+    completed_at TIMESTAMPTZ,
+    version INTEGER NOT NULL DEFAULT 1,
+
+    CONSTRAINT chk_workflow_sagas_status CHECK (status IN (
+        'running', 'compensating', 'completed', 'compensated', 'aborted', 'failed'
+    ))
+);
+
+-- Stuck saga lookups only scan unfinished sagas
+CREATE INDEX idx_workflow_sagas_unfinished ON workflow_sagas(updated_at)
+    WHERE status IN ('running', 'compensating', 'failed');
+
+CREATE INDEX idx_workflow_sagas_tenant_type ON workflow_sagas(tenant_id, saga_type);
@@ -0,0 +1,15 @@
+-- ============================================================================
+-- Opportunity Contact Roles Migration (Rollback)
+-- Version: 000015
+-- Description: Drops the opportunity contact details and role constraint
+-- ============================================================================
+
+DROP INDEX IF EXISTS uq_opportunity_contacts_contact;
+
+ALTER TABLE opportunity_contacts DROP CONSTRAINT IF EXISTS chk_opportunity_contacts_role;
+
+ALTER TABLE opportunity_contacts
+    DROP COLUMN IF EXISTS notes,
+    DROP COLUMN IF EXISTS phone,
+    DROP COLUMN IF EXISTS email,
+    DROP COLUMN IF EXISTS name;
@@ -0,0 +1,36 @@
+-- ============================================================================
+-- Opportunity Contact Roles Migration
+-- Version: 000015
+-- Description: Stores the contact details, role notes and attach time of
+--              opportunity contacts, and allows each contact once per
+--              opportunity
+-- ============================================================================
+
+ALTER TABLE opportunity_contacts
+    ADD COLUMN IF NOT EXISTS name VARCHAR(255) NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS email VARCHAR(320) NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS phone VARCHAR(50),
+    ADD COLUMN IF NOT EXISTS notes TEXT;
+
+-- Roles written before roles were validated
+UPDATE opportunity_contacts SET role = 'other'
+WHERE role NOT IN (
+    'decision_maker', 'influencer', 'finance', 'evaluator', 'champion',
+    'blocker', 'user', 'technical_contact', 'billing_contact', 'other'
+);
+
+ALTER TABLE opportunity_contacts
+    ADD CONSTRAINT chk_opportunity_contacts_role CHECK (role IN (
+        'decision_maker', 'influencer', 'finance', 'evaluator', 'champion',
+        'blocker', 'user', 'technical_contact', 'billing_contact', 'other'
+    ));
+
+-- Keep the earliest row of contacts attached more than once
+DELETE FROM opportunity_contacts c
+USING opportunity_contacts earlier
+WHERE c.opportunity_id = earlier.opportunity_id
+  AND c.contact_id = earlier.contact_id
+  AND (c.created_at, c.id) > (earlier.created_at, earlier.id);
+
+CREATE UNIQUE INDEX IF NOT EXISTS uq_opportunity_contacts_contact
+    ON opportunity_contacts(opportunity_id, contact_id);
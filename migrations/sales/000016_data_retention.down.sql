@@ -0,0 +1,13 @@
+-- ============================================================================
+-- Data Retention Migration (Rollback)
+-- Version: 000016
+-- Description: Drops retention policies and the archived record index
+-- ============================================================================
+
+DROP INDEX IF EXISTS idx_leads_closed;
+
+DROP POLICY IF EXISTS tenant_isolation_archived_records ON archived_records;
+
+DROP TABLE IF EXISTS archived_records;
+
+DROP TABLE IF EXISTS retention_policies;
@@ -0,0 +1,68 @@
+-- ============================================================================
+-- Data Retention Migration
+-- Version: 000016
+-- Description: Adds per-tenant retention policies and the index of records
+--              moved to cold storage by the retention worker
+-- ============================================================================
+
+-- ============================================================================
+-- Retention Policies Table
+-- ============================================================================
+
+-- No row level security: the retention worker applies the policies of all
+-- tenants.
+CREATE TABLE IF NOT EXISTS retention_policies (
+    tenant_id UUID NOT NULL,
+    entity_type VARCHAR(50) NOT NULL,
+    action VARCHAR(20) NOT NULL,
+    after_days INTEGER NOT NULL,
+    enabled BOOLEAN NOT NULL DEFAULT TRUE,
+    last_run_at TIMESTAMPTZ,
+
+    updated_by UUID,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+
+    PRIMARY KEY (tenant_id, entity_type),
+    CONSTRAINT chk_retention_policies_action CHECK (action IN ('archive', 'purge')),
+    CONSTRAINT chk_retention_policies_after_days CHECK (after_days >= 30)
+);
+
+CREATE TRIGGER update_retention_policies_updated_at BEFORE UPDATE ON retention_policies
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+
+CREATE INDEX idx_retention_policies_enabled
+    ON retention_policies(last_run_at NULLS FIRST)
+    WHERE enabled;
+
+-- ============================================================================
+-- Archived Records Table
+-- ============================================================================
+
+CREATE TABLE IF NOT EXISTS archived_records (
+    id UUID PRIMARY KEY,
+    tenant_id UUID NOT NULL,
+    entity_type VARCHAR(50) NOT NULL,
+    entity_id UUID NOT NULL,
+    storage_key TEXT NOT NULL,
+
+    archived_at TIMESTAMPTZ NOT NULL,
+    restored_at TIMESTAMPTZ,
+    restored_by UUID
+);
+
+CREATE INDEX idx_archived_records_entity
+    ON archived_records(tenant_id, entity_type, entity_id);
+
+CREATE INDEX idx_archived_records_archived
+    ON archived_records(tenant_id, entity_type, archived_at DESC);
+
+ALTER TABLE archived_records ENABLE ROW LEVEL SECURITY;
+
+CREATE POLICY tenant_isolation_archived_records ON archived_records
+    USING (tenant_id = current_setting('app.tenant_id', true)::uuid);
+
+-- Finds the closed leads a retention policy applies to
+CREATE INDEX IF NOT EXISTS idx_leads_closed
+    ON leads(tenant_id, updated_at)
+    WHERE status IN ('converted', 'unqualified');
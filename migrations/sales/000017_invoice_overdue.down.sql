@@ -0,0 +1,7 @@
+-- ============================================================================
+-- Overdue Invoices Migration (Rollback)
+-- Version: 000017
+-- Description: Drops the index of sent invoices by due date
+-- ============================================================================
+
+DROP INDEX IF EXISTS idx_deal_invoices_sent_due;
@@ -0,0 +1,10 @@
+-- ============================================================================
+-- Overdue Invoices Migration
+-- Version: 000017
+-- Description: Indexes sent invoices by due date for the worker marking
+--              them overdue
+-- ============================================================================
+
+CREATE INDEX IF NOT EXISTS idx_deal_invoices_sent_due
+    ON deal_invoices (due_date)
+    WHERE status = 'sent';
@@ -0,0 +1,12 @@
+-- ============================================================================
+-- Lead Duplicates Migration (Rollback)
+-- Version: 000018
+-- Description: Drops the lead matching indexes and the merged lead column
+-- ============================================================================
+
+DROP INDEX IF EXISTS idx_leads_match_company;
+DROP INDEX IF EXISTS idx_leads_match_phone;
+DROP INDEX IF EXISTS idx_leads_match_email;
+DROP INDEX IF EXISTS idx_leads_merged_into;
+
+ALTER TABLE leads DROP COLUMN IF EXISTS merged_into_id;
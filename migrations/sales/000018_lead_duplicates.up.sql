@@ -0,0 +1,27 @@
+-- ============================================================================
+-- Lead Duplicates Migration
+-- Version: 000018
+-- Description: Records the lead duplicate leads were merged into and indexes
+--              the email, phone and company forms new leads are matched on
+-- ============================================================================
+
+ALTER TABLE leads ADD COLUMN IF NOT EXISTS merged_into_id UUID REFERENCES leads(id);
+
+CREATE INDEX IF NOT EXISTS idx_leads_merged_into
+    ON leads (tenant_id, merged_into_id)
+    WHERE merged_into_id IS NOT NULL;
+
+-- Matching only looks at open leads
+CREATE INDEX IF NOT EXISTS idx_leads_match_email
+    ON leads (tenant_id, LOWER(email))
+    WHERE deleted_at IS NULL;
+
+CREATE INDEX IF NOT EXISTS idx_leads_match_phone
+    ON leads (tenant_id, RIGHT(regexp_replace(phone, '[^0-9]', '', 'g'), 8))
+    WHERE deleted_at IS NULL AND phone IS NOT NULL;
+
+-- text_pattern_ops serves the prefix search of names followed by a legal
+-- form suffix
+CREATE INDEX IF NOT EXISTS idx_leads_match_company
+    ON leads (tenant_id, regexp_replace(LOWER(company_name), '[^a-z0-9]', '', 'g') text_pattern_ops)
+    WHERE deleted_at IS NULL AND company_name IS NOT NULL;
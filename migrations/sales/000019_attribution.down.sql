@@ -0,0 +1,16 @@
+-- ============================================================================
+-- Attribution Migration (Rollback)
+-- Version: 000019
+-- Description: Drops the campaign and channel aggregates and the attribution
+--              columns
+-- ============================================================================
+
+DELETE FROM daily_sales_aggregates WHERE dimension IN ('campaign', 'channel');
+
+ALTER TABLE daily_sales_aggregates DROP CONSTRAINT IF EXISTS daily_sales_aggregates_dimension_check;
+ALTER TABLE daily_sales_aggregates ADD CONSTRAINT daily_sales_aggregates_dimension_check
+    CHECK (dimension IN ('owner', 'product', 'region'));
+
+ALTER TABLE deals DROP COLUMN IF EXISTS attribution;
+ALTER TABLE opportunities DROP COLUMN IF EXISTS attribution;
+ALTER TABLE leads DROP COLUMN IF EXISTS attribution;
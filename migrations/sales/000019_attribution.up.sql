@@ -0,0 +1,17 @@
+-- ============================================================================
+-- Attribution Migration
+-- Version: 000019
+-- Description: Records the UTM parameters, referrer and landing page of leads,
+--              carried over to their opportunities and deals, and aggregates
+--              sales by campaign and channel
+-- ============================================================================
+
+-- utm_source, utm_medium, utm_campaign, utm_term, utm_content, referrer and
+-- landing_page; NULL when nothing was captured
+ALTER TABLE leads ADD COLUMN IF NOT EXISTS attribution JSONB;
+ALTER TABLE opportunities ADD COLUMN IF NOT EXISTS attribution JSONB;
+ALTER TABLE deals ADD COLUMN IF NOT EXISTS attribution JSONB;
+
+ALTER TABLE daily_sales_aggregates DROP CONSTRAINT IF EXISTS daily_sales_aggregates_dimension_check;
+ALTER TABLE daily_sales_aggregates ADD CONSTRAINT daily_sales_aggregates_dimension_check
+    CHECK (dimension IN ('owner', 'product', 'region', 'campaign', 'channel'));
@@ -0,0 +1,12 @@
+-- ============================================================================
+-- Recurring Deals Migration (Rollback)
+-- Version: 000020
+-- Description: Drops the deal type and subscription contract of deals
+-- ============================================================================
+
+DROP INDEX IF EXISTS idx_deals_renewal_opportunity;
+DROP INDEX IF EXISTS idx_deals_renewal_upcoming;
+DROP INDEX IF EXISTS idx_deals_recurring;
+
+ALTER TABLE deals DROP COLUMN IF EXISTS subscription;
+ALTER TABLE deals DROP COLUMN IF EXISTS deal_type;
@@ -0,0 +1,26 @@
+-- ============================================================================
+-- Recurring Deals Migration
+-- Version: 000020
+-- Description: Adds the deal type and the subscription contract of recurring
+--              deals, with the indexes for renewals and recurring revenue
+-- ============================================================================
+
+ALTER TABLE deals ADD COLUMN IF NOT EXISTS deal_type VARCHAR(20) NOT NULL DEFAULT 'one_time'
+    CHECK (deal_type IN ('one_time', 'recurring'));
+
+-- Billing frequency, recurring amount, contract period and renewal progress;
+-- the contract period is also kept in start_date and end_date
+ALTER TABLE deals ADD COLUMN IF NOT EXISTS subscription JSONB;
+
+CREATE INDEX IF NOT EXISTS idx_deals_recurring
+    ON deals (tenant_id, end_date)
+    WHERE deal_type = 'recurring' AND deleted_at IS NULL;
+
+CREATE INDEX IF NOT EXISTS idx_deals_renewal_upcoming
+    ON deals (end_date)
+    WHERE deal_type = 'recurring' AND deleted_at IS NULL
+        AND subscription->>'renewal_status' = 'upcoming';
+
+CREATE INDEX IF NOT EXISTS idx_deals_renewal_opportunity
+    ON deals (tenant_id, (subscription->>'renewal_opportunity_id'))
+    WHERE deal_type = 'recurring';
@@ -0,0 +1,13 @@
+-- ============================================================================
+-- Discount Approvals Migration (Rollback)
+-- Version: 000021
+-- Description: Drops discount approval rules and approvals
+-- ============================================================================
+
+DROP POLICY IF EXISTS tenant_isolation_discount_approvals ON discount_approvals;
+
+DROP TABLE IF EXISTS discount_approvals;
+
+DROP POLICY IF EXISTS tenant_isolation_discount_approval_rules ON discount_approval_rules;
+
+DROP TABLE IF EXISTS discount_approval_rules;
@@ -0,0 +1,80 @@
+-- ============================================================================
+-- Discount Approvals Migration
+-- Version: 000021
+-- Description: Adds per-tenant discount approval rules and the approvals
+--              requested for opportunity discounts above them
+-- ============================================================================
+
+-- ============================================================================
+-- Discount Approval Rules Table
+-- ============================================================================
+
+CREATE TABLE IF NOT EXISTS discount_approval_rules (
+    id UUID PRIMARY KEY,
+    tenant_id UUID NOT NULL,
+
+    name VARCHAR(200) NOT NULL,
+    threshold_type VARCHAR(20) NOT NULL CHECK (threshold_type IN ('percentage', 'amount')),
+    threshold_percent NUMERIC(5, 2) NOT NULL DEFAULT 0,
+    threshold_amount BIGINT NOT NULL DEFAULT 0,
+    currency VARCHAR(3) NOT NULL DEFAULT '',
+    approver_ids UUID[] NOT NULL,
+    is_active BOOLEAN NOT NULL DEFAULT TRUE,
+
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_discount_approval_rules_tenant
+    ON discount_approval_rules(tenant_id, name);
+
+ALTER TABLE discount_approval_rules ENABLE ROW LEVEL SECURITY;
+
+CREATE POLICY tenant_isolation_discount_approval_rules ON discount_approval_rules
+    USING (tenant_id = current_setting('app.tenant_id', true)::uuid);
+
+CREATE TRIGGER update_discount_approval_rules_updated_at BEFORE UPDATE ON discount_approval_rules
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+
+-- ============================================================================
+-- Discount Approvals Table
+-- ============================================================================
+
+-- rule_ids keeps the rules an approval was requested under without a foreign
+-- key, so deleting a rule leaves past approvals intact
+CREATE TABLE IF NOT EXISTS discount_approvals (
+    id UUID PRIMARY KEY,
+    tenant_id UUID NOT NULL,
+    opportunity_id UUID NOT NULL REFERENCES opportunities(id) ON DELETE CASCADE,
+    opportunity_name VARCHAR(255) NOT NULL DEFAULT '',
+
+    status VARCHAR(20) NOT NULL DEFAULT 'pending'
+        CHECK (status IN ('pending', 'approved', 'rejected', 'cancelled')),
+    discount_amount BIGINT NOT NULL,
+    discount_percent NUMERIC(5, 2) NOT NULL,
+    currency VARCHAR(3) NOT NULL,
+    rule_ids UUID[] NOT NULL,
+    approver_ids UUID[] NOT NULL,
+
+    requested_by UUID NOT NULL,
+    request_comment TEXT NOT NULL DEFAULT '',
+    decided_by UUID,
+    decision_comment TEXT NOT NULL DEFAULT '',
+
+    requested_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    decided_at TIMESTAMPTZ,
+    version INTEGER NOT NULL DEFAULT 1
+);
+
+CREATE INDEX idx_discount_approvals_opportunity
+    ON discount_approvals(tenant_id, opportunity_id, requested_at DESC);
+
+-- Supports each approver's list of pending approvals
+CREATE INDEX idx_discount_approvals_pending_approvers
+    ON discount_approvals USING GIN (approver_ids)
+    WHERE status = 'pending';
+
+ALTER TABLE discount_approvals ENABLE ROW LEVEL SECURITY;
+
+CREATE POLICY tenant_isolation_discount_approvals ON discount_approvals
+    USING (tenant_id = current_setting('app.tenant_id', true)::uuid);
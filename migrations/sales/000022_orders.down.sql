@@ -0,0 +1,13 @@
+-- ============================================================================
+-- Production Orders Migration (Rollback)
+-- Version: 000022
+-- Description: Drops production orders and order webhooks
+-- ============================================================================
+
+DROP POLICY IF EXISTS tenant_isolation_order_webhooks ON order_webhooks;
+
+DROP TABLE IF EXISTS order_webhooks;
+
+DROP POLICY IF EXISTS tenant_isolation_orders ON orders;
+
+DROP TABLE IF EXISTS orders;
@@ -0,0 +1,76 @@
+-- ============================================================================
+-- Production Orders Migration
+-- Version: 000022
+-- Description: Adds production orders made from won deals and the per-tenant
+--              webhook that keeps factory systems in sync with them
+-- ============================================================================
+
+-- ============================================================================
+-- Orders Table
+-- ============================================================================
+
+CREATE TABLE IF NOT EXISTS orders (
+    id UUID PRIMARY KEY,
+    tenant_id UUID NOT NULL,
+    order_number VARCHAR(50) NOT NULL,
+    deal_id UUID NOT NULL REFERENCES deals(id) ON DELETE CASCADE,
+    deal_code VARCHAR(50) NOT NULL DEFAULT '',
+    deal_name VARCHAR(255) NOT NULL DEFAULT '',
+    customer_id UUID NOT NULL,
+    customer_name VARCHAR(255) NOT NULL DEFAULT '',
+
+    status VARCHAR(20) NOT NULL DEFAULT 'pending'
+        CHECK (status IN ('pending', 'in_production', 'shipped', 'delivered', 'cancelled')),
+    items JSONB NOT NULL DEFAULT '[]',
+    delivery_date TIMESTAMPTZ,
+    production_notes TEXT NOT NULL DEFAULT '',
+    external_reference VARCHAR(255) NOT NULL DEFAULT '',
+    tracking_number VARCHAR(255) NOT NULL DEFAULT '',
+    status_history JSONB NOT NULL DEFAULT '[]',
+
+    created_by UUID NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    shipped_at TIMESTAMPTZ,
+    delivered_at TIMESTAMPTZ,
+    cancelled_at TIMESTAMPTZ,
+    version INTEGER NOT NULL DEFAULT 1,
+
+    CONSTRAINT uq_orders_number UNIQUE (tenant_id, order_number)
+);
+
+-- A deal has at most one order that is not cancelled
+CREATE UNIQUE INDEX idx_orders_open_deal
+    ON orders(tenant_id, deal_id)
+    WHERE status <> 'cancelled';
+
+CREATE INDEX idx_orders_tenant_status
+    ON orders(tenant_id, status, created_at DESC);
+
+CREATE INDEX idx_orders_customer
+    ON orders(tenant_id, customer_id);
+
+ALTER TABLE orders ENABLE ROW LEVEL SECURITY;
+
+CREATE POLICY tenant_isolation_orders ON orders
+    USING (tenant_id = current_setting('app.tenant_id', true)::uuid);
+
+CREATE TRIGGER update_orders_updated_at BEFORE UPDATE ON orders
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+
+-- ============================================================================
+-- Order Webhooks Table
+-- ============================================================================
+
+CREATE TABLE IF NOT EXISTS order_webhooks (
+    tenant_id UUID PRIMARY KEY,
+    url TEXT NOT NULL,
+    secret VARCHAR(128) NOT NULL,
+    enabled BOOLEAN NOT NULL DEFAULT TRUE,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+ALTER TABLE order_webhooks ENABLE ROW LEVEL SECURITY;
+
+CREATE POLICY tenant_isolation_order_webhooks ON order_webhooks
+    USING (tenant_id = current_setting('app.tenant_id', true)::uuid);
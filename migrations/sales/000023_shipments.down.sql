@@ -0,0 +1,9 @@
+-- ============================================================================
+-- Shipments Migration (Rollback)
+-- Version: 000023
+-- Description: Drops shipments
+-- ============================================================================
+
+DROP POLICY IF EXISTS tenant_isolation_shipments ON shipments;
+
+DROP TABLE IF EXISTS shipments;
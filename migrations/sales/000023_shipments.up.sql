@@ -0,0 +1,51 @@
+-- ============================================================================
+-- Shipments Migration
+-- Version: 000023
+-- Description: Adds carrier shipments of deals and their tracking history
+-- ============================================================================
+
+CREATE TABLE IF NOT EXISTS shipments (
+    id UUID PRIMARY KEY,
+    tenant_id UUID NOT NULL,
+    deal_id UUID NOT NULL REFERENCES deals(id) ON DELETE CASCADE,
+    deal_code VARCHAR(50) NOT NULL DEFAULT '',
+    customer_id UUID NOT NULL,
+    customer_name VARCHAR(255) NOT NULL DEFAULT '',
+
+    carrier VARCHAR(50) NOT NULL,
+    tracking_number VARCHAR(100) NOT NULL,
+    tracking_url TEXT NOT NULL DEFAULT '',
+    status VARCHAR(20) NOT NULL DEFAULT 'pending'
+        CHECK (status IN ('pending', 'in_transit', 'out_for_delivery', 'delivered', 'exception', 'returned')),
+    recipient_name VARCHAR(255) NOT NULL DEFAULT '',
+    recipient_email VARCHAR(255) NOT NULL DEFAULT '',
+    estimated_delivery TIMESTAMPTZ,
+    tracking_events JSONB NOT NULL DEFAULT '[]',
+
+    shipped_at TIMESTAMPTZ,
+    delivered_at TIMESTAMPTZ,
+    last_checked_at TIMESTAMPTZ,
+    created_by UUID NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    version INTEGER NOT NULL DEFAULT 1
+);
+
+CREATE INDEX idx_shipments_deal
+    ON shipments(tenant_id, deal_id, created_at DESC);
+
+-- Carrier webhooks and polling find shipments by tracking number across tenants
+CREATE INDEX idx_shipments_tracking
+    ON shipments(carrier, tracking_number);
+
+CREATE INDEX idx_shipments_active
+    ON shipments(carrier, last_checked_at NULLS FIRST)
+    WHERE status NOT IN ('delivered', 'returned');
+
+ALTER TABLE shipments ENABLE ROW LEVEL SECURITY;
+
+CREATE POLICY tenant_isolation_shipments ON shipments
+    USING (tenant_id = current_setting('app.tenant_id', true)::uuid);
+
+CREATE TRIGGER update_shipments_updated_at BEFORE UPDATE ON shipments
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
@@ -0,0 +1,13 @@
+-- ============================================================================
+-- E-Invoices Migration (Rollback)
+-- Version: 000024
+-- Description: Drops e-invoices and e-invoice settings
+-- ============================================================================
+
+DROP POLICY IF EXISTS tenant_isolation_einvoices ON einvoices;
+
+DROP TABLE IF EXISTS einvoices;
+
+DROP POLICY IF EXISTS tenant_isolation_einvoice_settings ON einvoice_settings;
+
+DROP TABLE IF EXISTS einvoice_settings;
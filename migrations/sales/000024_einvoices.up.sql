@@ -0,0 +1,79 @@
+-- ============================================================================
+-- E-Invoices Migration
+-- Version: 000024
+-- Description: Adds MyInvois e-invoice supplier settings and invoice submissions
+-- ============================================================================
+
+CREATE TABLE IF NOT EXISTS einvoice_settings (
+    tenant_id UUID PRIMARY KEY,
+    enabled BOOLEAN NOT NULL DEFAULT FALSE,
+    name VARCHAR(300) NOT NULL DEFAULT '',
+    tin VARCHAR(14) NOT NULL DEFAULT '',
+    registration_number VARCHAR(20) NOT NULL DEFAULT '',
+    sst_number VARCHAR(35) NOT NULL DEFAULT '',
+    msic_code VARCHAR(5) NOT NULL DEFAULT '',
+    business_activity VARCHAR(300) NOT NULL DEFAULT '',
+    email VARCHAR(320) NOT NULL DEFAULT '',
+    phone VARCHAR(20) NOT NULL DEFAULT '',
+    address JSONB NOT NULL DEFAULT '{}',
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+
+    CONSTRAINT chk_einvoice_settings_tin
+        CHECK (NOT enabled OR tin <> '')
+);
+
+ALTER TABLE einvoice_settings ENABLE ROW LEVEL SECURITY;
+
+CREATE POLICY tenant_isolation_einvoice_settings ON einvoice_settings
+    USING (tenant_id = current_setting('app.tenant_id', true)::uuid);
+
+CREATE TABLE IF NOT EXISTS einvoices (
+    id UUID PRIMARY KEY,
+    tenant_id UUID NOT NULL,
+    deal_id UUID NOT NULL REFERENCES deals(id) ON DELETE CASCADE,
+    invoice_id UUID NOT NULL,
+    invoice_number VARCHAR(50) NOT NULL,
+    customer_id UUID NOT NULL,
+    buyer JSONB NOT NULL DEFAULT '{}',
+
+    status VARCHAR(20) NOT NULL DEFAULT 'pending'
+        CHECK (status IN ('pending', 'submitted', 'valid', 'invalid', 'failed', 'cancelled')),
+    submission_uid VARCHAR(50) NOT NULL DEFAULT '',
+    document_uuid VARCHAR(50) NOT NULL DEFAULT '',
+    long_id VARCHAR(100) NOT NULL DEFAULT '',
+    validation_url TEXT NOT NULL DEFAULT '',
+    validation_errors JSONB NOT NULL DEFAULT '[]',
+
+    attempts INTEGER NOT NULL DEFAULT 0,
+    next_attempt_at TIMESTAMPTZ,
+    last_error TEXT NOT NULL DEFAULT '',
+    submitted_at TIMESTAMPTZ,
+    validated_at TIMESTAMPTZ,
+    cancelled_at TIMESTAMPTZ,
+    cancel_reason VARCHAR(300) NOT NULL DEFAULT '',
+    created_by UUID NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    version INTEGER NOT NULL DEFAULT 1
+);
+
+-- An invoice has at most one live e-invoice; cancelled ones are kept as history
+CREATE UNIQUE INDEX idx_einvoices_invoice
+    ON einvoices(tenant_id, invoice_id)
+    WHERE status <> 'cancelled';
+
+CREATE INDEX idx_einvoices_deal
+    ON einvoices(tenant_id, deal_id, created_at DESC);
+
+-- The e-invoice worker picks up submissions and validation checks across tenants
+CREATE INDEX idx_einvoices_due
+    ON einvoices(next_attempt_at)
+    WHERE status IN ('pending', 'submitted');
+
+ALTER TABLE einvoices ENABLE ROW LEVEL SECURITY;
+
+CREATE POLICY tenant_isolation_einvoices ON einvoices
+    USING (tenant_id = current_setting('app.tenant_id', true)::uuid);
+
+CREATE TRIGGER update_einvoices_updated_at BEFORE UPDATE ON einvoices
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
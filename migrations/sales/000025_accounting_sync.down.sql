@@ -0,0 +1,13 @@
+-- ============================================================================
+-- Accounting Sync Migration (Rollback)
+-- Version: 000025
+-- Description: Drops accounting syncs and accounting connections
+-- ============================================================================
+
+DROP POLICY IF EXISTS tenant_isolation_accounting_syncs ON accounting_syncs;
+
+DROP TABLE IF EXISTS accounting_syncs;
+
+DROP POLICY IF EXISTS tenant_isolation_accounting_connections ON accounting_connections;
+
+DROP TABLE IF EXISTS accounting_connections;
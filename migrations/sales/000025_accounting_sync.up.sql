@@ -0,0 +1,69 @@
+-- ============================================================================
+-- Accounting Sync Migration
+-- Version: 000025
+-- Description: Adds tenants' accounting system connections and the sync
+--              records of deal invoices and payments
+-- ============================================================================
+
+CREATE TABLE IF NOT EXISTS accounting_connections (
+    tenant_id UUID PRIMARY KEY,
+    provider VARCHAR(20) NOT NULL
+        CHECK (provider IN ('xero', 'sql_account', 'autocount')),
+    url TEXT NOT NULL,
+    secret VARCHAR(64) NOT NULL,
+    enabled BOOLEAN NOT NULL DEFAULT TRUE,
+    mapping JSONB NOT NULL DEFAULT '{}',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+ALTER TABLE accounting_connections ENABLE ROW LEVEL SECURITY;
+
+CREATE POLICY tenant_isolation_accounting_connections ON accounting_connections
+    USING (tenant_id = current_setting('app.tenant_id', true)::uuid);
+
+CREATE TABLE IF NOT EXISTS accounting_syncs (
+    id UUID PRIMARY KEY,
+    tenant_id UUID NOT NULL,
+    deal_id UUID NOT NULL REFERENCES deals(id) ON DELETE CASCADE,
+    record_type VARCHAR(20) NOT NULL
+        CHECK (record_type IN ('invoice', 'payment')),
+    record_id UUID NOT NULL,
+    reference VARCHAR(100) NOT NULL DEFAULT '',
+    amount BIGINT NOT NULL,
+    currency VARCHAR(3) NOT NULL,
+
+    status VARCHAR(20) NOT NULL DEFAULT 'pending'
+        CHECK (status IN ('pending', 'synced', 'failed')),
+    external_id VARCHAR(100) NOT NULL DEFAULT '',
+    attempts INTEGER NOT NULL DEFAULT 0,
+    next_attempt_at TIMESTAMPTZ,
+    last_error TEXT NOT NULL DEFAULT '',
+    synced_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    version INTEGER NOT NULL DEFAULT 1,
+
+    CONSTRAINT uq_accounting_syncs_record UNIQUE (tenant_id, record_type, record_id)
+);
+
+-- Payments posted back by the accounting system are matched by their ID there
+CREATE INDEX idx_accounting_syncs_external
+    ON accounting_syncs(tenant_id, record_type, external_id)
+    WHERE external_id <> '';
+
+CREATE INDEX idx_accounting_syncs_status
+    ON accounting_syncs(tenant_id, status, updated_at DESC);
+
+-- The accounting sync worker picks up pushes across tenants
+CREATE INDEX idx_accounting_syncs_due
+    ON accounting_syncs(next_attempt_at)
+    WHERE status = 'pending';
+
+ALTER TABLE accounting_syncs ENABLE ROW LEVEL SECURITY;
+
+CREATE POLICY tenant_isolation_accounting_syncs ON accounting_syncs
+    USING (tenant_id = current_setting('app.tenant_id', true)::uuid);
+
+CREATE TRIGGER update_accounting_syncs_updated_at BEFORE UPDATE ON accounting_syncs
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
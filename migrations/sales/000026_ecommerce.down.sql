@@ -0,0 +1,13 @@
+-- ============================================================================
+-- E-Commerce Migration (Rollback)
+-- Version: 000026
+-- Description: Drops e-commerce imports and online store connectors
+-- ============================================================================
+
+DROP POLICY IF EXISTS tenant_isolation_ecommerce_imports ON ecommerce_imports;
+
+DROP TABLE IF EXISTS ecommerce_imports;
+
+DROP POLICY IF EXISTS tenant_isolation_ecommerce_stores ON ecommerce_stores;
+
+DROP TABLE IF EXISTS ecommerce_stores;
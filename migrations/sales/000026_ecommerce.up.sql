@@ -0,0 +1,84 @@
+-- ============================================================================
+-- E-Commerce Migration
+-- Version: 000026
+-- Description: Adds online store connectors and the import records of the
+--              orders and abandoned carts imported from them
+-- ============================================================================
+
+CREATE TABLE IF NOT EXISTS ecommerce_stores (
+    id UUID PRIMARY KEY,
+    tenant_id UUID NOT NULL,
+    platform VARCHAR(20) NOT NULL
+        CHECK (platform IN ('shopify', 'woocommerce')),
+    name VARCHAR(100) NOT NULL,
+    store_url TEXT NOT NULL,
+    api_key TEXT NOT NULL DEFAULT '',
+    api_secret TEXT NOT NULL DEFAULT '',
+    webhook_secret TEXT NOT NULL DEFAULT '',
+
+    enabled BOOLEAN NOT NULL DEFAULT TRUE,
+    import_abandoned_carts BOOLEAN NOT NULL DEFAULT FALSE,
+    pipeline_id UUID REFERENCES pipelines(id) ON DELETE SET NULL,
+    owner_id UUID NOT NULL,
+
+    synced_through TIMESTAMPTZ,
+    last_sync_at TIMESTAMPTZ,
+    last_sync_error TEXT NOT NULL DEFAULT '',
+    last_webhook_at TIMESTAMPTZ,
+
+    created_by UUID NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    version INTEGER NOT NULL DEFAULT 1
+);
+
+CREATE INDEX idx_ecommerce_stores_tenant ON ecommerce_stores(tenant_id, name);
+
+-- The e-commerce sync worker polls enabled stores across tenants
+CREATE INDEX idx_ecommerce_stores_enabled ON ecommerce_stores(id) WHERE enabled;
+
+ALTER TABLE ecommerce_stores ENABLE ROW LEVEL SECURITY;
+
+CREATE POLICY tenant_isolation_ecommerce_stores ON ecommerce_stores
+    USING (tenant_id = current_setting('app.tenant_id', true)::uuid);
+
+CREATE TRIGGER update_ecommerce_stores_updated_at BEFORE UPDATE ON ecommerce_stores
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+
+CREATE TABLE IF NOT EXISTS ecommerce_imports (
+    id UUID PRIMARY KEY,
+    tenant_id UUID NOT NULL,
+    store_id UUID NOT NULL REFERENCES ecommerce_stores(id) ON DELETE CASCADE,
+    kind VARCHAR(20) NOT NULL
+        CHECK (kind IN ('order', 'abandoned_cart')),
+    external_id VARCHAR(100) NOT NULL,
+    reference VARCHAR(100) NOT NULL DEFAULT '',
+    email VARCHAR(255) NOT NULL DEFAULT '',
+    amount BIGINT NOT NULL DEFAULT 0,
+    currency VARCHAR(3) NOT NULL DEFAULT '',
+
+    status VARCHAR(20) NOT NULL DEFAULT 'pending'
+        CHECK (status IN ('pending', 'imported', 'skipped', 'failed')),
+    customer_id UUID,
+    deal_id UUID,
+    lead_id UUID,
+    reason TEXT NOT NULL DEFAULT '',
+    attempts INTEGER NOT NULL DEFAULT 1,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    version INTEGER NOT NULL DEFAULT 1,
+
+    -- Each order and cart is imported once however often the store sends it
+    CONSTRAINT uq_ecommerce_imports_record UNIQUE (tenant_id, store_id, kind, external_id)
+);
+
+CREATE INDEX idx_ecommerce_imports_status
+    ON ecommerce_imports(tenant_id, store_id, status, updated_at DESC);
+
+ALTER TABLE ecommerce_imports ENABLE ROW LEVEL SECURITY;
+
+CREATE POLICY tenant_isolation_ecommerce_imports ON ecommerce_imports
+    USING (tenant_id = current_setting('app.tenant_id', true)::uuid);
+
+CREATE TRIGGER update_ecommerce_imports_updated_at BEFORE UPDATE ON ecommerce_imports
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
@@ -0,0 +1,9 @@
+-- ============================================================================
+-- Price Lists Migration (Rollback)
+-- Version: 000027
+-- Description: Drops per-tenant price lists
+-- ============================================================================
+
+DROP POLICY IF EXISTS tenant_isolation_pricing_settings ON pricing_settings;
+
+DROP TABLE IF EXISTS pricing_settings;
@@ -0,0 +1,17 @@
+-- ============================================================================
+-- Price Lists Migration
+-- Version: 000027
+-- Description: Adds per-tenant price lists, which price opportunity products
+--              by the customer's tier
+-- ============================================================================
+
+CREATE TABLE IF NOT EXISTS pricing_settings (
+    tenant_id UUID PRIMARY KEY,
+    price_lists JSONB NOT NULL DEFAULT '[]',
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+ALTER TABLE pricing_settings ENABLE ROW LEVEL SECURITY;
+
+CREATE POLICY tenant_isolation_pricing_settings ON pricing_settings
+    USING (tenant_id = current_setting('app.tenant_id', true)::uuid);
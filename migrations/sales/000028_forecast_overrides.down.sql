@@ -0,0 +1,10 @@
+-- ============================================================================
+-- Forecast Overrides Migration (Rollback)
+-- Version: 000028
+-- Description: Drops probability overrides and forecast categories
+-- ============================================================================
+
+DROP INDEX IF EXISTS idx_opportunities_forecast_category;
+
+ALTER TABLE opportunities DROP COLUMN IF EXISTS forecast_category;
+ALTER TABLE opportunities DROP COLUMN IF EXISTS probability_override;
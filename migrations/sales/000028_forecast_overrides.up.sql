@@ -0,0 +1,18 @@
+-- ============================================================================
+-- Forecast Overrides Migration
+-- Version: 000028
+-- Description: Adds per-opportunity probability overrides and forecast
+--              categories
+-- ============================================================================
+
+-- Replaces the stage probability across stage moves while set
+ALTER TABLE opportunities ADD COLUMN IF NOT EXISTS probability_override INTEGER
+    CHECK (probability_override BETWEEN 0 AND 100);
+
+-- NULL derives the category from the probability
+ALTER TABLE opportunities ADD COLUMN IF NOT EXISTS forecast_category VARCHAR(20)
+    CHECK (forecast_category IN ('commit', 'best_case', 'pipeline', 'omitted'));
+
+CREATE INDEX IF NOT EXISTS idx_opportunities_forecast_category
+    ON opportunities(tenant_id, forecast_category)
+    WHERE forecast_category IS NOT NULL AND deleted_at IS NULL;
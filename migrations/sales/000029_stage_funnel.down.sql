@@ -0,0 +1,10 @@
+-- ============================================================================
+-- Stage Funnel Migration (Rollback)
+-- Version: 000029
+-- Description: Drops the stage funnel read model. Removed duplicate stage
+--              history entries are not restored.
+-- ============================================================================
+
+DROP POLICY IF EXISTS tenant_isolation_opportunity_stage_funnel ON opportunity_stage_funnel;
+
+DROP TABLE IF EXISTS opportunity_stage_funnel;
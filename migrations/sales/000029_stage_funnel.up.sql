@@ -0,0 +1,61 @@
+-- ============================================================================
+-- Stage Funnel Migration
+-- Version: 000029
+-- Description: Adds the stage funnel read model that backs stage duration
+--              analytics and the pipeline conversion funnel, projected from
+--              opportunity events
+-- ============================================================================
+
+-- ============================================================================
+-- Stage History Cleanup
+-- ============================================================================
+
+-- Opportunity updates used to re-insert the whole stage history, leaving a
+-- copy of every entry per update. Keep one row per entry, preferring the one
+-- that records the exit.
+DELETE FROM opportunity_stage_history h
+USING opportunity_stage_history d
+WHERE h.opportunity_id = d.opportunity_id
+    AND h.entered_at = d.entered_at
+    AND h.id <> d.id
+    AND ((h.exited_at IS NULL AND d.exited_at IS NOT NULL)
+        OR (h.exited_at IS NOT DISTINCT FROM d.exited_at AND h.id > d.id));
+
+-- ============================================================================
+-- Stage Funnel Table
+-- ============================================================================
+
+-- One row per stage visit, numbered in history order
+CREATE TABLE IF NOT EXISTS opportunity_stage_funnel (
+    opportunity_id UUID NOT NULL,
+    visit INTEGER NOT NULL,
+    tenant_id UUID NOT NULL,
+    pipeline_id UUID NOT NULL,
+    stage_id UUID NOT NULL,
+    stage_type VARCHAR(20) NOT NULL,
+    stage_order INTEGER NOT NULL,
+    entered_at TIMESTAMPTZ NOT NULL,
+    exited_at TIMESTAMPTZ,
+    duration_seconds BIGINT,
+    owner_id UUID NOT NULL,
+    source VARCHAR(100) NOT NULL DEFAULT '',
+    product_ids UUID[] NOT NULL DEFAULT '{}',
+    opportunity_created_at TIMESTAMPTZ NOT NULL,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (opportunity_id, visit)
+);
+
+-- Serves the funnel of a pipeline for a creation-date cohort
+CREATE INDEX idx_opportunity_stage_funnel_cohort
+    ON opportunity_stage_funnel(tenant_id, pipeline_id, opportunity_created_at);
+
+CREATE INDEX idx_opportunity_stage_funnel_owner
+    ON opportunity_stage_funnel(tenant_id, pipeline_id, owner_id);
+
+CREATE INDEX idx_opportunity_stage_funnel_products
+    ON opportunity_stage_funnel USING GIN (product_ids);
+
+ALTER TABLE opportunity_stage_funnel ENABLE ROW LEVEL SECURITY;
+
+CREATE POLICY tenant_isolation_opportunity_stage_funnel ON opportunity_stage_funnel
+    USING (tenant_id = current_setting('app.tenant_id', true)::uuid);
@@ -0,0 +1,15 @@
+-- ============================================================================
+-- Win/Loss Cohorts Migration (Rollback)
+-- Version: 000030
+-- Description: Drops the product category and customer segment aggregates and
+--              columns
+-- ============================================================================
+
+DELETE FROM daily_sales_aggregates WHERE dimension IN ('product_category', 'customer_segment');
+
+ALTER TABLE daily_sales_aggregates DROP CONSTRAINT IF EXISTS daily_sales_aggregates_dimension_check;
+ALTER TABLE daily_sales_aggregates ADD CONSTRAINT daily_sales_aggregates_dimension_check
+    CHECK (dimension IN ('owner', 'product', 'region', 'campaign', 'channel'));
+
+ALTER TABLE opportunities DROP COLUMN IF EXISTS customer_segment;
+ALTER TABLE opportunity_products DROP COLUMN IF EXISTS category;
@@ -0,0 +1,16 @@
+-- ============================================================================
+-- Win/Loss Cohorts Migration
+-- Version: 000030
+-- Description: Records the product category of opportunity lines and the
+--              customer segment of opportunities, and aggregates sales by both
+--              for win/loss cohort analysis
+-- ============================================================================
+
+-- Copied from the catalogue and the customer when the line or the customer
+-- was set; NULL for rows written before this migration
+ALTER TABLE opportunity_products ADD COLUMN IF NOT EXISTS category VARCHAR(100);
+ALTER TABLE opportunities ADD COLUMN IF NOT EXISTS customer_segment VARCHAR(50);
+
+ALTER TABLE daily_sales_aggregates DROP CONSTRAINT IF EXISTS daily_sales_aggregates_dimension_check;
+ALTER TABLE daily_sales_aggregates ADD CONSTRAINT daily_sales_aggregates_dimension_check
+    CHECK (dimension IN ('owner', 'product', 'region', 'campaign', 'channel', 'product_category', 'customer_segment'));
@@ -0,0 +1,11 @@
+-- ============================================================================
+-- Insights Migration (Rollback)
+-- Version: 000031
+-- Description: Drops insight settings and detected insights
+-- ============================================================================
+
+DROP POLICY IF EXISTS tenant_isolation_insights ON insights;
+DROP TABLE IF EXISTS insights;
+
+DROP POLICY IF EXISTS tenant_isolation_insight_settings ON insight_settings;
+DROP TABLE IF EXISTS insight_settings;
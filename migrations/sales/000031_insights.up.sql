@@ -0,0 +1,46 @@
+-- ============================================================================
+-- Insights Migration
+-- Version: 000031
+-- Description: Adds per-tenant insight thresholds and the insights detected
+--              from them: biggest deal of the quarter won, low pipeline
+--              coverage and lead volume drops
+-- ============================================================================
+
+CREATE TABLE IF NOT EXISTS insight_settings (
+    tenant_id UUID PRIMARY KEY,
+    biggest_deal_won BOOLEAN NOT NULL DEFAULT TRUE,
+    low_pipeline_coverage BOOLEAN NOT NULL DEFAULT TRUE,
+    lead_volume_drop BOOLEAN NOT NULL DEFAULT TRUE,
+    currency VARCHAR(3) NOT NULL DEFAULT 'MYR',
+    -- Minor units of currency; coverage is not checked while zero
+    quarterly_quota BIGINT NOT NULL DEFAULT 0 CHECK (quarterly_quota >= 0),
+    minimum_coverage NUMERIC(6, 2) NOT NULL DEFAULT 2,
+    lead_drop_percent NUMERIC(5, 2) NOT NULL DEFAULT 30,
+    minimum_weekly_leads BIGINT NOT NULL DEFAULT 10,
+    updated_by UUID,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+ALTER TABLE insight_settings ENABLE ROW LEVEL SECURITY;
+
+CREATE POLICY tenant_isolation_insight_settings ON insight_settings
+    USING (tenant_id = current_setting('app.tenant_id', true)::uuid);
+
+-- One row per insight raised; the period key keeps each from being raised twice
+CREATE TABLE IF NOT EXISTS insights (
+    id UUID PRIMARY KEY,
+    tenant_id UUID NOT NULL,
+    type VARCHAR(50) NOT NULL
+        CHECK (type IN ('biggest_deal_won', 'low_pipeline_coverage', 'lead_volume_drop')),
+    period_key VARCHAR(100) NOT NULL,
+    details JSONB NOT NULL DEFAULT '{}',
+    detected_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    UNIQUE (tenant_id, type, period_key)
+);
+
+CREATE INDEX idx_insights_tenant_detected ON insights(tenant_id, detected_at DESC);
+
+ALTER TABLE insights ENABLE ROW LEVEL SECURITY;
+
+CREATE POLICY tenant_isolation_insights ON insights
+    USING (tenant_id = current_setting('app.tenant_id', true)::uuid);
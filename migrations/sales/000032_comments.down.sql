@@ -0,0 +1,11 @@
+-- ============================================================================
+-- Comments Migration (Rollback)
+-- Version: 000032
+-- Description: Drops comments and their reactions
+-- ============================================================================
+
+DROP POLICY IF EXISTS tenant_isolation_comment_reactions ON comment_reactions;
+DROP TABLE IF EXISTS comment_reactions;
+
+DROP POLICY IF EXISTS tenant_isolation_comments ON comments;
+DROP TABLE IF EXISTS comments;
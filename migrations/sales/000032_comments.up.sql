@@ -0,0 +1,54 @@
+-- ============================================================================
+-- Comments Migration
+-- Version: 000032
+-- Description: Adds comment threads on leads, opportunities and customers,
+--              with @mentions, an audit trail of edits and deletions, and
+--              reactions
+-- ============================================================================
+
+CREATE TABLE IF NOT EXISTS comments (
+    id UUID PRIMARY KEY,
+    tenant_id UUID NOT NULL,
+    entity_type VARCHAR(20) NOT NULL
+        CHECK (entity_type IN ('lead', 'opportunity', 'customer')),
+    entity_id UUID NOT NULL,
+    -- The top-level comment of the thread; NULL for top-level comments
+    parent_id UUID REFERENCES comments(id) ON DELETE CASCADE,
+    author_id UUID NOT NULL,
+    -- Emptied when the comment is deleted; the revisions keep it
+    body TEXT NOT NULL DEFAULT '',
+    mentions UUID[] NOT NULL DEFAULT '{}',
+    revisions JSONB NOT NULL DEFAULT '[]',
+    edited_at TIMESTAMPTZ,
+    deleted_at TIMESTAMPTZ,
+    deleted_by UUID,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    version INTEGER NOT NULL DEFAULT 1
+);
+
+CREATE INDEX idx_comments_entity_threads ON comments(tenant_id, entity_type, entity_id, created_at DESC)
+    WHERE parent_id IS NULL;
+CREATE INDEX idx_comments_parent ON comments(parent_id, created_at)
+    WHERE parent_id IS NOT NULL;
+
+ALTER TABLE comments ENABLE ROW LEVEL SECURITY;
+
+CREATE POLICY tenant_isolation_comments ON comments
+    USING (tenant_id = current_setting('app.tenant_id', true)::uuid);
+
+-- One row per user and reaction, so adding a reaction twice has no effect
+CREATE TABLE IF NOT EXISTS comment_reactions (
+    comment_id UUID NOT NULL REFERENCES comments(id) ON DELETE CASCADE,
+    tenant_id UUID NOT NULL,
+    user_id UUID NOT NULL,
+    reaction VARCHAR(20) NOT NULL
+        CHECK (reaction IN ('thumbs_up', 'thumbs_down', 'heart', 'laugh', 'celebrate', 'eyes')),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (comment_id, user_id, reaction)
+);
+
+ALTER TABLE comment_reactions ENABLE ROW LEVEL SECURITY;
+
+CREATE POLICY tenant_isolation_comment_reactions ON comment_reactions
+    USING (tenant_id = current_setting('app.tenant_id', true)::uuid);
@@ -0,0 +1,8 @@
+-- ============================================================================
+-- Stage Rules Migration (Rollback)
+-- Version: 000033
+-- Description: Drops the WIP limits and entry criteria of pipeline stages
+-- ============================================================================
+
+ALTER TABLE pipeline_stages DROP COLUMN IF EXISTS entry_criteria;
+ALTER TABLE pipeline_stages DROP COLUMN IF EXISTS wip_limit;
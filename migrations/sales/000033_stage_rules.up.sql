@@ -0,0 +1,12 @@
+-- ============================================================================
+-- Stage Rules Migration
+-- Version: 000033
+-- Description: Adds WIP limits and entry criteria to pipeline stages, checked
+--              when opportunities move into a stage
+-- ============================================================================
+
+-- 0 leaves the stage unlimited
+ALTER TABLE pipeline_stages ADD COLUMN IF NOT EXISTS wip_limit INTEGER NOT NULL DEFAULT 0
+    CHECK (wip_limit >= 0);
+-- Required fields and the minimum number of logged activities
+ALTER TABLE pipeline_stages ADD COLUMN IF NOT EXISTS entry_criteria JSONB NOT NULL DEFAULT '{}';
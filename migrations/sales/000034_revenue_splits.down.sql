@@ -0,0 +1,11 @@
+-- ============================================================================
+-- Revenue Splits Migration (Rollback)
+-- Version: 000034
+-- Description: Drops opportunity revenue splits and their history
+-- ============================================================================
+
+DROP POLICY IF EXISTS tenant_isolation_opportunity_revenue_split_changes ON opportunity_revenue_split_changes;
+DROP TABLE IF EXISTS opportunity_revenue_split_changes;
+
+DROP POLICY IF EXISTS tenant_isolation_opportunity_revenue_splits ON opportunity_revenue_splits;
+DROP TABLE IF EXISTS opportunity_revenue_splits;
@@ -0,0 +1,48 @@
+-- ============================================================================
+-- Revenue Splits Migration
+-- Version: 000034
+-- Description: Adds percentage splits of an opportunity's revenue among
+--              users, credited in rep reports and forecasts, and the history
+--              of their changes
+-- ============================================================================
+
+-- Opportunities without rows credit their owner in full
+CREATE TABLE IF NOT EXISTS opportunity_revenue_splits (
+    opportunity_id UUID NOT NULL REFERENCES opportunities(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL,
+    tenant_id UUID NOT NULL,
+    user_name VARCHAR(255) NOT NULL DEFAULT '',
+    percent INTEGER NOT NULL CHECK (percent BETWEEN 1 AND 100),
+    -- The order the splits were set in, which decides who gets the rounding
+    -- remainder of a credit
+    position INTEGER NOT NULL,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (opportunity_id, user_id)
+);
+
+CREATE INDEX idx_opportunity_revenue_splits_tenant_user ON opportunity_revenue_splits(tenant_id, user_id);
+
+ALTER TABLE opportunity_revenue_splits ENABLE ROW LEVEL SECURITY;
+
+CREATE POLICY tenant_isolation_opportunity_revenue_splits ON opportunity_revenue_splits
+    USING (tenant_id = current_setting('app.tenant_id', true)::uuid);
+
+CREATE TABLE IF NOT EXISTS opportunity_revenue_split_changes (
+    id UUID PRIMARY KEY,
+    tenant_id UUID NOT NULL,
+    opportunity_id UUID NOT NULL REFERENCES opportunities(id) ON DELETE CASCADE,
+    -- Empty when the splits were cleared
+    splits JSONB NOT NULL DEFAULT '[]',
+    previous_splits JSONB NOT NULL DEFAULT '[]',
+    reason TEXT NOT NULL DEFAULT '',
+    changed_by UUID NOT NULL,
+    changed_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_opportunity_revenue_split_changes_opportunity
+    ON opportunity_revenue_split_changes(tenant_id, opportunity_id, changed_at DESC);
+
+ALTER TABLE opportunity_revenue_split_changes ENABLE ROW LEVEL SECURITY;
+
+CREATE POLICY tenant_isolation_opportunity_revenue_split_changes ON opportunity_revenue_split_changes
+    USING (tenant_id = current_setting('app.tenant_id', true)::uuid);
@@ -0,0 +1,73 @@
+package auth
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+
+	apperrors "github.com/kilang-desa-murni/crm/pkg/errors"
+	"github.com/kilang-desa-murni/crm/pkg/identity"
+)
+
+// SignIdentity sets the identity headers of claims on h, signed with the
+// identity secret, for the gateway to forward to backend services. Without
+// an identity secret or claims it only removes the identity headers h
+// already has.
+func (m *JWTManager) SignIdentity(h http.Header, claims *Claims) {
+	secret := m.identityKey()
+	if secret == nil || claims == nil {
+		identity.Clear(h)
+		return
+	}
+
+	identity.Set(h, identity.Identity{
+		UserID:      claims.UserID,
+		TenantID:    claims.TenantID,
+		Email:       claims.Email,
+		Roles:       claims.Roles,
+		Permissions: claims.Permissions,
+	}, secret, time.Now())
+}
+
+// VerifyIdentity returns the claims of the signed identity headers of h. It
+// returns no claims when h carries none or no identity secret is configured,
+// leaving the request to be authenticated by its access token.
+func (m *JWTManager) VerifyIdentity(h http.Header) (*Claims, error) {
+	secret := m.identityKey()
+	if secret == nil || !identity.Present(h) {
+		return nil, nil
+	}
+
+	id, err := identity.Verify(h, secret, time.Now())
+	if err != nil {
+		if errors.Is(err, identity.ErrExpired) {
+			return nil, apperrors.New(apperrors.ErrCodeTokenExpired, err.Error())
+		}
+		return nil, apperrors.New(apperrors.ErrCodeTokenInvalid, err.Error())
+	}
+
+	return &Claims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			Subject:  id.UserID,
+			IssuedAt: jwt.NewNumericDate(id.IssuedAt),
+		},
+		UserID:      id.UserID,
+		TenantID:    id.TenantID,
+		Email:       id.Email,
+		Roles:       id.Roles,
+		Permissions: id.Permissions,
+		TokenType:   TokenTypeAccess,
+	}, nil
+}
+
+// identityKey returns the identity secret, or nil when none is configured.
+func (m *JWTManager) identityKey() []byte {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	if m.identitySecret == "" {
+		return nil
+	}
+	return []byte(m.identitySecret)
+}
@@ -0,0 +1,119 @@
+package auth
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	apperrors "github.com/kilang-desa-murni/crm/pkg/errors"
+)
+
+const introspectionKeyPrefix = "auth:introspection:"
+
+// Introspection is the result of introspecting an access token: its claims
+// when it is active, or the error code of why it is not.
+type Introspection struct {
+	Active bool    `json:"active"`
+	Claims *Claims `json:"claims,omitempty"`
+	Code   string  `json:"code,omitempty"`
+}
+
+// IntrospectionRequest is the body of a token introspection request.
+type IntrospectionRequest struct {
+	Token string `json:"token"`
+}
+
+// Introspector validates access tokens by exchanging them for their claims
+// at the IAM service's introspection endpoint. Results are cached, keyed by
+// a hash of the token, for at most the cache TTL and never beyond the
+// token's expiry.
+type Introspector struct {
+	url      string
+	client   *http.Client
+	cache    TokenStore
+	cacheTTL time.Duration
+}
+
+// NewIntrospector creates a new introspector calling the introspection
+// endpoint at url through transport, http.DefaultTransport when nil. A nil
+// cache introspects every request.
+func NewIntrospector(url string, transport http.RoundTripper, cache TokenStore, cacheTTL time.Duration) *Introspector {
+	return &Introspector{
+		url:      url,
+		client:   &http.Client{Timeout: 5 * time.Second, Transport: transport},
+		cache:    cache,
+		cacheTTL: cacheTTL,
+	}
+}
+
+// ValidateAccessToken returns the claims of an active access token.
+func (i *Introspector) ValidateAccessToken(ctx context.Context, token string) (*Claims, error) {
+	key := introspectionKeyPrefix + hashToken(token)
+	if i.cache != nil {
+		var claims Claims
+		if err := i.cache.Get(ctx, key, &claims); err == nil && (claims.ExpiresAt == nil || claims.ExpiresAt.After(time.Now())) {
+			return &claims, nil
+		}
+	}
+
+	result, err := i.introspect(ctx, token)
+	if err != nil {
+		return nil, apperrors.Wrap(err, apperrors.ErrCodeServiceUnavailable, "token introspection failed")
+	}
+	if !result.Active || result.Claims == nil {
+		if apperrors.ErrorCode(result.Code) == apperrors.ErrCodeTokenExpired {
+			return nil, apperrors.New(apperrors.ErrCodeTokenExpired, "token has expired")
+		}
+		return nil, apperrors.New(apperrors.ErrCodeTokenInvalid, "invalid token")
+	}
+	if result.Claims.TokenType != TokenTypeAccess {
+		return nil, apperrors.New(apperrors.ErrCodeTokenInvalid, "not an access token")
+	}
+
+	ttl := i.cacheTTL
+	if exp := result.Claims.ExpiresAt; exp != nil && time.Until(exp.Time) < ttl {
+		ttl = time.Until(exp.Time)
+	}
+	if i.cache != nil && ttl > 0 {
+		// Failing to cache only costs the next request an introspection
+		_ = i.cache.Set(ctx, key, result.Claims, ttl)
+	}
+
+	return result.Claims, nil
+}
+
+// introspect calls the introspection endpoint.
+func (i *Introspector) introspect(ctx context.Context, token string) (*Introspection, error) {
+	body, err := json.Marshal(IntrospectionRequest{Token: token})
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, i.url, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := i.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("introspection endpoint returned status %d", resp.StatusCode)
+	}
+
+	var envelope struct {
+		Data Introspection `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&envelope); err != nil {
+		return nil, fmt.Errorf("failed to decode introspection: %w", err)
+	}
+	return &envelope.Data, nil
+}
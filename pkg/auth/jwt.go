@@ -6,6 +6,8 @@ import (
 	"crypto/rand"
 	"encoding/base64"
 	"fmt"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
@@ -46,13 +48,75 @@ type TokenPair struct {
 // JWTManager handles JWT token operations.
 type JWTManager struct {
 	config *config.JWTConfig
+
+	// mu guards the signing and verification keys, which UpdateKeys replaces
+	// when they are rotated.
+	mu             sync.RWMutex
+	secret         string
+	keyID          string
+	previousKeys   []config.JWTKeyConfig
+	identitySecret string
 }
 
 // NewJWTManager creates a new JWT manager.
 func NewJWTManager(cfg *config.JWTConfig) *JWTManager {
-	return &JWTManager{
+	m := &JWTManager{
 		config: cfg,
 	}
+	m.UpdateKeys(*cfg)
+	return m
+}
+
+// UpdateKeys replaces the signing key, the previous keys still accepted for
+// validation and the identity secret with those of cfg, such as after the
+// secrets were refreshed.
+func (m *JWTManager) UpdateKeys(cfg config.JWTConfig) {
+	previous := make([]config.JWTKeyConfig, 0, len(cfg.PreviousKeys))
+	for _, key := range cfg.PreviousKeys {
+		if key.Secret != "" {
+			previous = append(previous, key)
+		}
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.secret = cfg.Secret
+	m.keyID = cfg.KeyID
+	m.previousKeys = previous
+	m.identitySecret = cfg.IdentitySecret
+}
+
+// signingKey returns the current signing key and its key ID.
+func (m *JWTManager) signingKey() (string, string) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.secret, m.keyID
+}
+
+// verificationKey returns the keys a token may be signed with. A token with a
+// key ID is checked against that key only; tokens without one, issued before
+// key IDs were configured, are checked against every accepted key.
+func (m *JWTManager) verificationKey(token *jwt.Token) (interface{}, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	if kid, ok := token.Header["kid"].(string); ok && kid != "" {
+		if kid == m.keyID {
+			return []byte(m.secret), nil
+		}
+		for _, key := range m.previousKeys {
+			if key.ID == kid {
+				return []byte(key.Secret), nil
+			}
+		}
+		return nil, fmt.Errorf("unknown signing key: %s", kid)
+	}
+
+	keys := jwt.VerificationKeySet{Keys: []jwt.VerificationKey{[]byte(m.secret)}}
+	for _, key := range m.previousKeys {
+		keys.Keys = append(keys.Keys, []byte(key.Secret))
+	}
+	return keys, nil
 }
 
 // GenerateTokenPair generates an access and refresh token pair.
@@ -94,6 +158,38 @@ func (m *JWTManager) GenerateRefreshToken(userID, tenantID, email string, roles
 
 // generateToken generates a JWT token with the specified parameters.
 func (m *JWTManager) generateToken(userID, tenantID, email string, roles []string, tokenType TokenType, now time.Time) (string, time.Time, error) {
+	claims, err := m.newClaims(userID, tenantID, email, roles, tokenType, now)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+
+	var signingMethod jwt.SigningMethod
+	switch m.config.SigningAlgorithm {
+	case "HS256":
+		signingMethod = jwt.SigningMethodHS256
+	case "HS384":
+		signingMethod = jwt.SigningMethodHS384
+	case "HS512":
+		signingMethod = jwt.SigningMethodHS512
+	default:
+		signingMethod = jwt.SigningMethodHS256
+	}
+
+	secret, keyID := m.signingKey()
+	token := jwt.NewWithClaims(signingMethod, claims)
+	if keyID != "" {
+		token.Header["kid"] = keyID
+	}
+	signedToken, err := token.SignedString([]byte(secret))
+	if err != nil {
+		return "", time.Time{}, fmt.Errorf("failed to sign token: %w", err)
+	}
+
+	return signedToken, claims.ExpiresAt.Time, nil
+}
+
+// newClaims builds the claims of a new token, with a unique token ID.
+func (m *JWTManager) newClaims(userID, tenantID, email string, roles []string, tokenType TokenType, now time.Time) (*Claims, error) {
 	var expiry time.Duration
 	if tokenType == TokenTypeAccess {
 		expiry = m.config.AccessExpiry
@@ -106,10 +202,10 @@ func (m *JWTManager) generateToken(userID, tenantID, email string, roles []strin
 	// Generate a unique token ID
 	jti, err := generateTokenID()
 	if err != nil {
-		return "", time.Time{}, fmt.Errorf("failed to generate token ID: %w", err)
+		return nil, fmt.Errorf("failed to generate token ID: %w", err)
 	}
 
-	claims := &Claims{
+	return &Claims{
 		RegisteredClaims: jwt.RegisteredClaims{
 			ID:        jti,
 			Issuer:    m.config.Issuer,
@@ -124,27 +220,7 @@ func (m *JWTManager) generateToken(userID, tenantID, email string, roles []strin
 		Email:     email,
 		Roles:     roles,
 		TokenType: tokenType,
-	}
-
-	var signingMethod jwt.SigningMethod
-	switch m.config.SigningAlgorithm {
-	case "HS256":
-		signingMethod = jwt.SigningMethodHS256
-	case "HS384":
-		signingMethod = jwt.SigningMethodHS384
-	case "HS512":
-		signingMethod = jwt.SigningMethodHS512
-	default:
-		signingMethod = jwt.SigningMethodHS256
-	}
-
-	token := jwt.NewWithClaims(signingMethod, claims)
-	signedToken, err := token.SignedString([]byte(m.config.Secret))
-	if err != nil {
-		return "", time.Time{}, fmt.Errorf("failed to sign token: %w", err)
-	}
-
-	return signedToken, expiresAt, nil
+	}, nil
 }
 
 // ValidateToken validates a JWT token and returns the claims.
@@ -165,7 +241,7 @@ func (m *JWTManager) ValidateToken(tokenString string) (*Claims, error) {
 				return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
 			}
 		}
-		return []byte(m.config.Secret), nil
+		return m.verificationKey(token)
 	})
 
 	if err != nil {
@@ -321,3 +397,28 @@ func HasAllRoles(ctx context.Context, roles ...string) bool {
 	}
 	return true
 }
+
+// PermissionsFromContext extracts permissions from context.
+func PermissionsFromContext(ctx context.Context) ([]string, bool) {
+	claims, ok := ClaimsFromContext(ctx)
+	if !ok {
+		return nil, false
+	}
+	return claims.Permissions, true
+}
+
+// HasPermission checks if the user has a permission, given as
+// "resource:action". A granted "resource:*" or "*" covers every action.
+func HasPermission(ctx context.Context, permission string) bool {
+	permissions, ok := PermissionsFromContext(ctx)
+	if !ok {
+		return false
+	}
+	resource, _, _ := strings.Cut(permission, ":")
+	for _, p := range permissions {
+		if p == permission || p == "*" || p == resource+":*" {
+			return true
+		}
+	}
+	return false
+}
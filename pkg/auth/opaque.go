@@ -0,0 +1,129 @@
+package auth
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/kilang-desa-murni/crm/pkg/database"
+	apperrors "github.com/kilang-desa-murni/crm/pkg/errors"
+)
+
+// OpaqueTokenPrefix marks opaque access tokens, telling them apart from JWTs.
+const OpaqueTokenPrefix = "crm_at_"
+
+const opaqueTokenKeyPrefix = "auth:opaque:"
+
+// TokenStore keeps the claims of tokens, such as opaque access tokens and
+// cached introspection results. database.RedisClient is a TokenStore.
+type TokenStore interface {
+	Set(ctx context.Context, key string, value interface{}, expiration time.Duration) error
+	Get(ctx context.Context, key string, target interface{}) error
+	Delete(ctx context.Context, keys ...string) error
+}
+
+// OpaqueTokenManager issues opaque access tokens, random references to
+// claims kept in a token store, so tokens carry no user data and can be
+// revoked at once. Refresh tokens stay JWTs, as only the IAM service reads
+// them.
+type OpaqueTokenManager struct {
+	jwt   *JWTManager
+	store TokenStore
+}
+
+// NewOpaqueTokenManager creates a new opaque token manager, signing refresh
+// tokens with jwtManager.
+func NewOpaqueTokenManager(jwtManager *JWTManager, store TokenStore) *OpaqueTokenManager {
+	return &OpaqueTokenManager{
+		jwt:   jwtManager,
+		store: store,
+	}
+}
+
+// GenerateTokenPair generates an opaque access token and a refresh token.
+func (m *OpaqueTokenManager) GenerateTokenPair(ctx context.Context, userID, tenantID, email string, roles []string) (*TokenPair, error) {
+	now := time.Now()
+
+	claims, err := m.jwt.newClaims(userID, tenantID, email, roles, TokenTypeAccess, now)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate access token: %w", err)
+	}
+	accessToken := OpaqueTokenPrefix + claims.ID
+	expiresAt := claims.ExpiresAt.Time
+	if err := m.store.Set(ctx, opaqueTokenKey(accessToken), claims, expiresAt.Sub(now)); err != nil {
+		return nil, fmt.Errorf("failed to store access token: %w", err)
+	}
+
+	refreshToken, _, err := m.jwt.generateToken(userID, tenantID, email, roles, TokenTypeRefresh, now)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate refresh token: %w", err)
+	}
+
+	return &TokenPair{
+		AccessToken:  accessToken,
+		RefreshToken: refreshToken,
+		TokenType:    "Bearer",
+		ExpiresIn:    int64(m.jwt.config.AccessExpiry.Seconds()),
+		ExpiresAt:    expiresAt,
+	}, nil
+}
+
+// RefreshTokenPair refreshes a token pair using a valid refresh token.
+func (m *OpaqueTokenManager) RefreshTokenPair(ctx context.Context, refreshTokenString string) (*TokenPair, error) {
+	claims, err := m.jwt.ValidateRefreshToken(refreshTokenString)
+	if err != nil {
+		return nil, err
+	}
+
+	return m.GenerateTokenPair(ctx, claims.UserID, claims.TenantID, claims.Email, claims.Roles)
+}
+
+// Revoke revokes an opaque access token. Gateways may accept it for as long
+// as they cache its introspection.
+func (m *OpaqueTokenManager) Revoke(ctx context.Context, token string) error {
+	if !IsOpaqueToken(token) {
+		return nil
+	}
+	return m.store.Delete(ctx, opaqueTokenKey(token))
+}
+
+// Introspect returns the claims of an access token. Opaque tokens are looked
+// up in the token store; JWTs, such as those issued before switching to
+// opaque tokens, are validated as usual.
+func (m *OpaqueTokenManager) Introspect(ctx context.Context, token string) (*Claims, error) {
+	if !IsOpaqueToken(token) {
+		return m.jwt.ValidateAccessToken(token)
+	}
+
+	var claims Claims
+	if err := m.store.Get(ctx, opaqueTokenKey(token), &claims); err != nil {
+		if errors.Is(err, database.ErrKeyNotFound) {
+			return nil, apperrors.New(apperrors.ErrCodeTokenInvalid, "invalid token")
+		}
+		return nil, apperrors.Wrap(err, apperrors.ErrCodeServiceUnavailable, "failed to look up token")
+	}
+	if claims.ExpiresAt != nil && !claims.ExpiresAt.After(time.Now()) {
+		return nil, apperrors.New(apperrors.ErrCodeTokenExpired, "token has expired")
+	}
+	return &claims, nil
+}
+
+// IsOpaqueToken reports whether token is an opaque access token.
+func IsOpaqueToken(token string) bool {
+	return strings.HasPrefix(token, OpaqueTokenPrefix)
+}
+
+// opaqueTokenKey returns the store key of an opaque token. Only its hash is
+// stored, so the store holds no usable tokens.
+func opaqueTokenKey(token string) string {
+	return opaqueTokenKeyPrefix + hashToken(token)
+}
+
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,99 @@
+// Package cache provides caching helpers shared by the CRM services and the API gateway.
+package cache
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// ============================================================================
+// Request Coalescing
+// ============================================================================
+
+// Group collapses concurrent calls with the same key into a single call: the
+// first caller runs the function and every caller that arrives while it is in
+// flight waits for and shares its result. The zero value is ready to use.
+//
+// The function runs with a context that is detached from the callers'
+// cancellation, so one caller giving up does not fail the others. Each caller
+// still returns as soon as its own context is done.
+type Group[T any] struct {
+	mu    sync.Mutex
+	calls map[string]*call[T]
+}
+
+// call is an in-flight or completed Do call.
+type call[T any] struct {
+	done    chan struct{}
+	val     T
+	err     error
+	waiters int
+}
+
+// Do runs fn once for all concurrent callers with the same key. shared reports
+// whether the result was given to more than one caller.
+func (g *Group[T]) Do(ctx context.Context, key string, fn func(ctx context.Context) (T, error)) (v T, shared bool, err error) {
+	g.mu.Lock()
+	if g.calls == nil {
+		g.calls = make(map[string]*call[T])
+	}
+	c, inflight := g.calls[key]
+	if inflight {
+		c.waiters++
+	} else {
+		c = &call[T]{done: make(chan struct{})}
+		g.calls[key] = c
+		go g.run(context.WithoutCancel(ctx), key, c, fn)
+	}
+	g.mu.Unlock()
+
+	select {
+	case <-c.done:
+		g.mu.Lock()
+		shared = inflight || c.waiters > 0
+		g.mu.Unlock()
+		return c.val, shared, c.err
+	case <-ctx.Done():
+		var zero T
+		return zero, inflight, ctx.Err()
+	}
+}
+
+// run executes fn and publishes its result to every waiter.
+func (g *Group[T]) run(ctx context.Context, key string, c *call[T], fn func(ctx context.Context) (T, error)) {
+	defer func() {
+		if r := recover(); r != nil {
+			c.err = fmt.Errorf("cache: coalesced call %q panicked: %v", key, r)
+		}
+
+		g.mu.Lock()
+		if g.calls[key] == c {
+			delete(g.calls, key)
+		}
+		g.mu.Unlock()
+		close(c.done)
+	}()
+
+	c.val, c.err = fn(ctx)
+}
+
+// Forget makes the next Do for key start a new call instead of joining the one
+// in flight, e.g. after the underlying data was written.
+func (g *Group[T]) Forget(key string) {
+	g.mu.Lock()
+	delete(g.calls, key)
+	g.mu.Unlock()
+}
+
+// ForgetPrefix forgets every in-flight call whose key starts with prefix.
+func (g *Group[T]) ForgetPrefix(prefix string) {
+	g.mu.Lock()
+	for key := range g.calls {
+		if strings.HasPrefix(key, prefix) {
+			delete(g.calls, key)
+		}
+	}
+	g.mu.Unlock()
+}
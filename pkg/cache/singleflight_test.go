@@ -0,0 +1,121 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestGroup_Do_CoalescesConcurrentCalls(t *testing.T) {
+	var g Group[string]
+	var calls int32
+	release := make(chan struct{})
+
+	fn := func(ctx context.Context) (string, error) {
+		atomic.AddInt32(&calls, 1)
+		<-release
+		return "pipeline", nil
+	}
+
+	const callers = 10
+	var wg sync.WaitGroup
+	results := make(chan string, callers)
+	for i := 0; i < callers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			v, _, err := g.Do(context.Background(), "pipelines:default", fn)
+			if err != nil {
+				t.Errorf("Do() error = %v", err)
+			}
+			results <- v
+		}()
+	}
+
+	// Give every caller time to join the in-flight call
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+	wg.Wait()
+	close(results)
+
+	if n := atomic.LoadInt32(&calls); n != 1 {
+		t.Errorf("fn called %d times, want 1", n)
+	}
+	for v := range results {
+		if v != "pipeline" {
+			t.Errorf("Do() = %q, want pipeline", v)
+		}
+	}
+}
+
+func TestGroup_Do_SequentialCallsRunAgain(t *testing.T) {
+	var g Group[int]
+	var calls int
+
+	for i := 1; i <= 2; i++ {
+		v, shared, err := g.Do(context.Background(), "key", func(ctx context.Context) (int, error) {
+			calls++
+			return calls, nil
+		})
+		if err != nil || v != i || shared {
+			t.Errorf("Do() #%d = (%d, %v, %v), want (%d, false, nil)", i, v, shared, err, i)
+		}
+	}
+}
+
+func TestGroup_Do_CallerCancellation(t *testing.T) {
+	var g Group[int]
+	release := make(chan struct{})
+	fn := func(ctx context.Context) (int, error) {
+		<-release
+		if ctx.Err() != nil {
+			return 0, ctx.Err()
+		}
+		return 42, nil
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	firstErr := make(chan error, 1)
+	go func() {
+		_, _, err := g.Do(ctx, "key", fn)
+		firstErr <- err
+	}()
+	time.Sleep(20 * time.Millisecond)
+
+	second := make(chan int, 1)
+	go func() {
+		v, _, _ := g.Do(context.Background(), "key", fn)
+		second <- v
+	}()
+	time.Sleep(20 * time.Millisecond)
+
+	cancel()
+	if err := <-firstErr; !errors.Is(err, context.Canceled) {
+		t.Errorf("cancelled Do() error = %v, want context.Canceled", err)
+	}
+
+	close(release)
+	if v := <-second; v != 42 {
+		t.Errorf("Do() after another caller cancelled = %d, want 42", v)
+	}
+}
+
+func TestGroup_Do_Panic(t *testing.T) {
+	var g Group[int]
+	_, _, err := g.Do(context.Background(), "key", func(ctx context.Context) (int, error) {
+		panic("boom")
+	})
+	if err == nil {
+		t.Fatal("Do() error = nil, want panic error")
+	}
+
+	v, _, err := g.Do(context.Background(), "key", func(ctx context.Context) (int, error) {
+		return 1, nil
+	})
+	if err != nil || v != 1 {
+		t.Errorf("Do() after panic = (%d, %v), want (1, nil)", v, err)
+	}
+}
@@ -0,0 +1,237 @@
+// Package capture records sanitized request/response pairs at the gateway
+// for debugging. Capturing is opt-in: an administrator starts a capture
+// session for one tenant and time window, the exchanges of the tenant's
+// requests in that window are written to object storage, and the replay
+// tool re-issues them against a staging environment to reproduce a
+// regression.
+package capture
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/kilang-desa-murni/crm/pkg/logger"
+)
+
+// DefaultBodyLimit is the number of bytes of each body kept when no limit
+// is configured.
+const DefaultBodyLimit = 64 * 1024
+
+// recordQueueSize is the number of exchanges waiting to be written before
+// new ones are dropped.
+const recordQueueSize = 1000
+
+// ============================================================================
+// Exchange
+// ============================================================================
+
+// Exchange is a captured request and the response the gateway sent. Header,
+// query and body values that may hold personal data or credentials are
+// redacted, so a replayed request carries "[REDACTED]" in their place.
+type Exchange struct {
+	CaptureID string `json:"capture_id"`
+	RequestID string `json:"request_id,omitempty"`
+	TenantID  string `json:"tenant_id"`
+	UserID    string `json:"user_id,omitempty"`
+
+	Method         string            `json:"method"`
+	Path           string            `json:"path"`
+	Query          string            `json:"query,omitempty"`
+	RequestHeaders map[string]string `json:"request_headers,omitempty"`
+	RequestBody    string            `json:"request_body,omitempty"`
+	// RequestBodyTruncated is set when the body was longer than the limit;
+	// such requests are not replayed.
+	RequestBodyTruncated bool `json:"request_body_truncated,omitempty"`
+
+	Status                int               `json:"status"`
+	ResponseHeaders       map[string]string `json:"response_headers,omitempty"`
+	ResponseBody          string            `json:"response_body,omitempty"`
+	ResponseBodyTruncated bool              `json:"response_body_truncated,omitempty"`
+
+	StartedAt  time.Time `json:"started_at"`
+	DurationMs int64     `json:"duration_ms"`
+}
+
+// ============================================================================
+// Recorder
+// ============================================================================
+
+// Subject returns the tenant and user a request was authenticated as.
+type Subject func(ctx context.Context) (tenantID, userID string)
+
+// activeSessions finds the capture session a request falls in.
+type activeSessions interface {
+	Active(tenantID, path string, now time.Time) *Session
+}
+
+// Recorder is the capture middleware. It records the requests of tenants
+// with an active capture session and writes them to the store in the
+// background, dropping exchanges when the store falls behind rather than
+// slowing requests down.
+type Recorder struct {
+	sessions  activeSessions
+	store     Store
+	subject   Subject
+	redactor  *logger.Redactor
+	bodyLimit int
+	log       *logger.Logger
+
+	queue chan *Exchange
+}
+
+// NewRecorder creates a recorder. Bodies are kept up to bodyLimit bytes.
+func NewRecorder(sessions activeSessions, store Store, subject Subject, bodyLimit int, log *logger.Logger) *Recorder {
+	if bodyLimit <= 0 {
+		bodyLimit = DefaultBodyLimit
+	}
+	return &Recorder{
+		sessions:  sessions,
+		store:     store,
+		subject:   subject,
+		redactor:  logger.NewRedactor(),
+		bodyLimit: bodyLimit,
+		log:       log,
+		queue:     make(chan *Exchange, recordQueueSize),
+	}
+}
+
+// Start writes recorded exchanges to the store until ctx is done.
+func (rec *Recorder) Start(ctx context.Context) {
+	go func() {
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case exchange := <-rec.queue:
+				if err := rec.store.Put(ctx, exchange); err != nil {
+					rec.log.Warn().Err(err).Str("capture_id", exchange.CaptureID).Msg("Failed to store captured exchange")
+				}
+			}
+		}
+	}()
+}
+
+// Middleware records the requests that fall in an active capture session.
+// It must run after authentication, which resolves the tenant.
+func (rec *Recorder) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		tenantID, userID := rec.subject(r.Context())
+		if tenantID == "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+		start := time.Now()
+		session := rec.sessions.Active(tenantID, r.URL.Path, start)
+		if session == nil {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		// Keep the start of the request body and hand the handler all of it
+		var requestBody []byte
+		truncated := false
+		if r.Body != nil && r.Body != http.NoBody {
+			read, _ := io.ReadAll(io.LimitReader(r.Body, int64(rec.bodyLimit)+1))
+			r.Body = readCloser{io.MultiReader(bytes.NewReader(read), r.Body), r.Body}
+
+			requestBody = read
+			if len(read) > rec.bodyLimit {
+				requestBody, truncated = read[:rec.bodyLimit], true
+			}
+		}
+
+		// Headers are sanitized before the handler can change them
+		exchange := &Exchange{
+			CaptureID:            session.ID,
+			RequestID:            r.Header.Get("X-Request-ID"),
+			TenantID:             tenantID,
+			UserID:               userID,
+			Method:               r.Method,
+			Path:                 r.URL.Path,
+			Query:                rec.redactor.Query(r.URL.Query()),
+			RequestHeaders:       rec.redactor.Header(r.Header),
+			RequestBody:          rec.redactor.Body(r.Header.Get("Content-Type"), requestBody),
+			RequestBodyTruncated: truncated,
+			StartedAt:            start.UTC(),
+		}
+
+		cw := &captureWriter{ResponseWriter: w, status: http.StatusOK, limit: rec.bodyLimit}
+		next.ServeHTTP(cw, r)
+
+		exchange.Status = cw.status
+		exchange.ResponseHeaders = rec.redactor.Header(w.Header())
+		exchange.ResponseBody = rec.redactor.Body(w.Header().Get("Content-Type"), cw.body.Bytes())
+		exchange.ResponseBodyTruncated = cw.truncated
+		exchange.DurationMs = time.Since(start).Milliseconds()
+
+		select {
+		case rec.queue <- exchange:
+		default:
+			rec.log.Warn().Str("capture_id", session.ID).Msg("Capture queue full, dropping exchange")
+		}
+	})
+}
+
+// readCloser reads from a replacement reader and closes the original body.
+type readCloser struct {
+	io.Reader
+	io.Closer
+}
+
+// captureWriter keeps the status and the start of the response body.
+type captureWriter struct {
+	http.ResponseWriter
+	status      int
+	wroteHeader bool
+	body        bytes.Buffer
+	limit       int
+	truncated   bool
+}
+
+func (w *captureWriter) WriteHeader(status int) {
+	if !w.wroteHeader {
+		w.status = status
+		w.wroteHeader = true
+	}
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *captureWriter) Write(b []byte) (int, error) {
+	w.wroteHeader = true
+	if room := w.limit - w.body.Len(); room > 0 {
+		if len(b) > room {
+			w.body.Write(b[:room])
+			w.truncated = true
+		} else {
+			w.body.Write(b)
+		}
+	} else if len(b) > 0 {
+		w.truncated = true
+	}
+	return w.ResponseWriter.Write(b)
+}
+
+// Flush lets streamed responses through.
+func (w *captureWriter) Flush() {
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// Unwrap exposes the underlying writer to http.ResponseController.
+func (w *captureWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// hasPrefix reports whether path falls under prefix, on a segment boundary.
+func hasPrefix(path, prefix string) bool {
+	if prefix == "" || prefix == "/" {
+		return true
+	}
+	prefix = strings.TrimSuffix(prefix, "/")
+	return path == prefix || strings.HasPrefix(path, prefix+"/")
+}
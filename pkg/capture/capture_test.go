@@ -0,0 +1,244 @@
+package capture
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/kilang-desa-murni/crm/pkg/logger"
+)
+
+// fixedSessions is a single capture session.
+type fixedSessions struct {
+	session Session
+}
+
+func (s *fixedSessions) Active(tenantID, path string, now time.Time) *Session {
+	if s.session.covers(tenantID, path, now) {
+		return &s.session
+	}
+	return nil
+}
+
+func newTestSession(tenantID string) *fixedSessions {
+	now := time.Now()
+	return &fixedSessions{session: Session{
+		ID:         uuid.NewString(),
+		TenantID:   tenantID,
+		PathPrefix: "/api/v1/leads",
+		StartsAt:   now.Add(-time.Minute),
+		EndsAt:     now.Add(time.Hour),
+	}}
+}
+
+func TestRecorder_CapturesSanitizedExchanges(t *testing.T) {
+	tenantID := uuid.NewString()
+	sessions := newTestSession(tenantID)
+	store, err := NewDirStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewDirStore() error = %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	subject := func(context.Context) (string, string) { return tenantID, "user-1" }
+	recorder := NewRecorder(sessions, store, subject, 0, logger.New(logger.Config{Level: "error"}))
+	recorder.Start(ctx)
+
+	var received string
+	handler := recorder.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		received = string(body)
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"id":"lead-1","email":"siti@example.com"}`))
+	}))
+
+	body := `{"company":"Boutique Anggun","email":"siti@example.com"}`
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/leads/?source=web&token=abc", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer secret")
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+
+	if received != body {
+		t.Errorf("handler received %q, want the full body", received)
+	}
+
+	// Requests outside the session's path are not captured
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/api/v1/customers/", nil))
+
+	var exchanges []*Exchange
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		if exchanges, err = store.List(ctx, sessions.session.ID); err == nil && len(exchanges) > 0 {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	if len(exchanges) != 1 {
+		t.Fatalf("captured %d exchanges, want 1", len(exchanges))
+	}
+
+	ex := exchanges[0]
+	if ex.TenantID != tenantID || ex.Method != http.MethodPost || ex.Path != "/api/v1/leads/" || ex.Status != http.StatusCreated {
+		t.Errorf("exchange = %+v, want the lead creation", ex)
+	}
+	if ex.RequestHeaders["Authorization"] != logger.RedactedValue {
+		t.Errorf("Authorization = %q, want it redacted", ex.RequestHeaders["Authorization"])
+	}
+	if strings.Contains(ex.RequestBody, "siti@example.com") || !strings.Contains(ex.RequestBody, "Boutique Anggun") {
+		t.Errorf("request body = %q, want the email redacted only", ex.RequestBody)
+	}
+	if strings.Contains(ex.ResponseBody, "siti@example.com") {
+		t.Errorf("response body = %q, want the email redacted", ex.ResponseBody)
+	}
+	if strings.Contains(ex.Query, "abc") || !strings.Contains(ex.Query, "source=web") {
+		t.Errorf("query = %q, want the token redacted only", ex.Query)
+	}
+}
+
+func TestRecorder_TruncatesLongBodies(t *testing.T) {
+	tenantID := uuid.NewString()
+	store, err := NewDirStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewDirStore() error = %v", err)
+	}
+	subject := func(context.Context) (string, string) { return tenantID, "" }
+	recorder := NewRecorder(newTestSession(tenantID), store, subject, 8, logger.New(logger.Config{Level: "error"}))
+
+	var received string
+	handler := recorder.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		received = string(body)
+	}))
+
+	body := "0123456789abcdef"
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/leads/import", strings.NewReader(body))
+	req.Header.Set("Content-Type", "text/plain")
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+
+	if received != body {
+		t.Errorf("handler received %q, want %q", received, body)
+	}
+	ex := <-recorder.queue
+	if !ex.RequestBodyTruncated || ex.RequestBody != "01234567" {
+		t.Errorf("captured body = %q (truncated %v), want the first 8 bytes", ex.RequestBody, ex.RequestBodyTruncated)
+	}
+}
+
+func TestDirStore_ListsInOrder(t *testing.T) {
+	store, err := NewDirStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewDirStore() error = %v", err)
+	}
+	ctx := context.Background()
+	captureID := uuid.NewString()
+
+	start := time.Now()
+	for _, offset := range []time.Duration{2 * time.Second, 0, time.Second} {
+		ex := &Exchange{CaptureID: captureID, Path: "/" + offset.String(), StartedAt: start.Add(offset)}
+		if err := store.Put(ctx, ex); err != nil {
+			t.Fatalf("Put() error = %v", err)
+		}
+	}
+
+	exchanges, err := store.List(ctx, captureID)
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	var paths []string
+	for _, ex := range exchanges {
+		paths = append(paths, ex.Path)
+	}
+	if got := strings.Join(paths, ","); got != "/0s,/1s,/2s" {
+		t.Errorf("List() paths = %s, want them in start order", got)
+	}
+
+	if _, err := store.List(ctx, uuid.NewString()); err != ErrCaptureNotFound {
+		t.Errorf("List() of an unknown capture error = %v, want ErrCaptureNotFound", err)
+	}
+	if _, err := store.List(ctx, "../etc"); err == nil {
+		t.Error("List() should refuse capture ids that are not UUIDs")
+	}
+}
+
+func TestReplayer_Replay(t *testing.T) {
+	var got *http.Request
+	var gotBody string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r
+		body, _ := io.ReadAll(r.Body)
+		gotBody = string(body)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	replayer, err := NewReplayer(ReplayConfig{Target: server.URL, Token: "staging-token"})
+	if err != nil {
+		t.Fatalf("NewReplayer() error = %v", err)
+	}
+	ctx := context.Background()
+
+	captured := &Exchange{
+		CaptureID: uuid.NewString(),
+		Method:    http.MethodGet,
+		Path:      "/api/v1/leads/",
+		Query:     "page=2",
+		RequestHeaders: map[string]string{
+			"Authorization":   logger.RedactedValue,
+			"Accept-Language": "ms-MY",
+			"X-Request-Id":    "original",
+		},
+		Status: http.StatusOK,
+	}
+	result := replayer.Replay(ctx, captured)
+	if !result.Matches() {
+		t.Fatalf("Replay() = %+v, want the captured status", result)
+	}
+	if got.URL.Path != "/api/v1/leads/" || got.URL.RawQuery != "page=2" {
+		t.Errorf("replayed %s?%s, want the captured path and query", got.URL.Path, got.URL.RawQuery)
+	}
+	if got.Header.Get("Authorization") != "Bearer staging-token" {
+		t.Errorf("Authorization = %q, want the replay token", got.Header.Get("Authorization"))
+	}
+	if got.Header.Get("Accept-Language") != "ms-MY" || got.Header.Get("X-Request-Id") != "" {
+		t.Errorf("headers = %v, want the captured headers but the request id", got.Header)
+	}
+	if got.Header.Get(ReplayHeader) != captured.CaptureID {
+		t.Errorf("%s = %q, want the capture id", ReplayHeader, got.Header.Get(ReplayHeader))
+	}
+
+	// Writes are only replayed when allowed
+	write := &Exchange{
+		CaptureID:      captured.CaptureID,
+		Method:         http.MethodPost,
+		Path:           "/api/v1/leads/",
+		RequestHeaders: map[string]string{"Content-Type": "application/json"},
+		RequestBody:    `{"company":"Boutique Anggun"}`,
+		Status:         http.StatusCreated,
+	}
+	if result := replayer.Replay(ctx, write); result.Skipped == "" {
+		t.Error("Replay() should skip writes by default")
+	}
+
+	writer, _ := NewReplayer(ReplayConfig{Target: server.URL, AllowWrites: true})
+	result = writer.Replay(ctx, write)
+	if result.Skipped != "" || gotBody != write.RequestBody {
+		t.Errorf("Replay() = %+v with body %q, want the write replayed", result, gotBody)
+	}
+	if result.Matches() {
+		t.Error("Matches() should report the different status")
+	}
+
+	truncated := *write
+	truncated.RequestBodyTruncated = true
+	if result := writer.Replay(ctx, &truncated); result.Skipped == "" {
+		t.Error("Replay() should skip requests with truncated bodies")
+	}
+}
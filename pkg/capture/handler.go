@@ -0,0 +1,104 @@
+package capture
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	apperrors "github.com/kilang-desa-murni/crm/pkg/errors"
+	"github.com/kilang-desa-murni/crm/pkg/response"
+)
+
+// ============================================================================
+// Admin API
+// ============================================================================
+
+// createRequest is the body of POST /.
+type createRequest struct {
+	TenantID   string `json:"tenant_id"`
+	PathPrefix string `json:"path_prefix"`
+	Reason     string `json:"reason"`
+	// StartsAt schedules the window; empty starts it now.
+	StartsAt *time.Time `json:"starts_at"`
+	// Duration is the length of the window, e.g. "30m".
+	Duration string `json:"duration"`
+}
+
+// NewAdminHandler returns the traffic capture API, to be mounted under a
+// prefix behind admin authentication. userID names the administrator who
+// starts a session.
+//
+//	GET    /                  sessions that have not ended
+//	POST   /                  start a session
+//	DELETE /{id}              stop a session
+//	GET    /{id}/exchanges    the exchanges captured by a session
+func NewAdminHandler(sessions *Sessions, store Store, userID func(ctx context.Context) string) http.Handler {
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("GET /{$}", func(w http.ResponseWriter, r *http.Request) {
+		list, err := sessions.List(r.Context())
+		if err != nil {
+			response.Error(w, apperrors.ErrInternalWrap(err, "Failed to load traffic captures"))
+			return
+		}
+		response.OK(w, list)
+	})
+
+	mux.HandleFunc("POST /{$}", func(w http.ResponseWriter, r *http.Request) {
+		var req createRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			response.Error(w, apperrors.ErrBadRequest("Invalid request body"))
+			return
+		}
+		window, err := time.ParseDuration(req.Duration)
+		if err != nil {
+			response.Error(w, apperrors.ErrValidation("duration must be a duration such as 30m"))
+			return
+		}
+		var startsAt time.Time
+		if req.StartsAt != nil {
+			startsAt = *req.StartsAt
+		}
+
+		session, err := sessions.Create(r.Context(), req.TenantID, req.PathPrefix, req.Reason, userID(r.Context()), startsAt, window)
+		if err != nil {
+			if errors.Is(err, ErrInvalidSession) {
+				response.Error(w, apperrors.ErrValidation(err.Error()))
+				return
+			}
+			response.Error(w, apperrors.ErrInternalWrap(err, "Failed to start traffic capture"))
+			return
+		}
+		response.Created(w, session)
+	})
+
+	mux.HandleFunc("DELETE /{id}", func(w http.ResponseWriter, r *http.Request) {
+		stopped, err := sessions.Stop(r.Context(), r.PathValue("id"))
+		if err != nil {
+			response.Error(w, apperrors.ErrInternalWrap(err, "Failed to stop traffic capture"))
+			return
+		}
+		if !stopped {
+			response.Error(w, apperrors.ErrNotFound("Traffic capture"))
+			return
+		}
+		response.NoContent(w)
+	})
+
+	mux.HandleFunc("GET /{id}/exchanges", func(w http.ResponseWriter, r *http.Request) {
+		exchanges, err := store.List(r.Context(), r.PathValue("id"))
+		if err != nil {
+			if errors.Is(err, ErrCaptureNotFound) {
+				response.Error(w, apperrors.ErrNotFound("Traffic capture"))
+				return
+			}
+			response.Error(w, apperrors.ErrInternalWrap(err, "Failed to load captured exchanges"))
+			return
+		}
+		response.OK(w, exchanges)
+	})
+
+	return mux
+}
@@ -0,0 +1,172 @@
+package capture
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/kilang-desa-murni/crm/pkg/logger"
+)
+
+// ReplayHeader marks replayed requests, carrying the capture ID.
+const ReplayHeader = "X-Capture-Replay"
+
+// skippedHeaders are not copied from a captured request: they belong to the
+// original connection or are set for the replay.
+var skippedHeaders = map[string]bool{
+	"Authorization":     true,
+	"Connection":        true,
+	"Content-Length":    true,
+	"Cookie":            true,
+	"Host":              true,
+	"Keep-Alive":        true,
+	"Te":                true,
+	"Trailer":           true,
+	"Transfer-Encoding": true,
+	"Upgrade":           true,
+	"X-Forwarded-For":   true,
+	"X-Forwarded-Host":  true,
+	"X-Forwarded-Proto": true,
+	"X-Real-Ip":         true,
+	"X-Request-Id":      true,
+}
+
+// ============================================================================
+// Replayer
+// ============================================================================
+
+// ReplayConfig configures a replay.
+type ReplayConfig struct {
+	// Target is the base URL of the environment requests are re-issued
+	// against, e.g. https://staging-api.example.com.
+	Target string
+	// Token authenticates the replayed requests; the captured credentials
+	// are redacted.
+	Token string
+	// AllowWrites replays requests other than GET, HEAD and OPTIONS.
+	AllowWrites bool
+	// Timeout bounds each replayed request.
+	Timeout time.Duration
+}
+
+// ReplayResult is the outcome of replaying one exchange.
+type ReplayResult struct {
+	Exchange   *Exchange `json:"exchange"`
+	Status     int       `json:"status,omitempty"`
+	DurationMs int64     `json:"duration_ms,omitempty"`
+	// Skipped says why the exchange was not replayed.
+	Skipped string `json:"skipped,omitempty"`
+	Error   string `json:"error,omitempty"`
+}
+
+// Matches reports whether the replay got the captured status.
+func (r *ReplayResult) Matches() bool {
+	return r.Skipped == "" && r.Error == "" && r.Status == r.Exchange.Status
+}
+
+// Replayer re-issues captured requests against another environment.
+type Replayer struct {
+	config ReplayConfig
+	target *url.URL
+	client *http.Client
+}
+
+// NewReplayer creates a replayer.
+func NewReplayer(config ReplayConfig) (*Replayer, error) {
+	target, err := url.Parse(config.Target)
+	if err != nil || target.Scheme == "" || target.Host == "" {
+		return nil, fmt.Errorf("invalid replay target %q", config.Target)
+	}
+	if config.Timeout <= 0 {
+		config.Timeout = 30 * time.Second
+	}
+	return &Replayer{
+		config: config,
+		target: target,
+		client: &http.Client{
+			Timeout: config.Timeout,
+			// Redirects are compared, not followed
+			CheckRedirect: func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse },
+		},
+	}, nil
+}
+
+// Replay re-issues a captured request and reports the status it got.
+func (p *Replayer) Replay(ctx context.Context, exchange *Exchange) *ReplayResult {
+	result := &ReplayResult{Exchange: exchange}
+	if reason := p.skipReason(exchange); reason != "" {
+		result.Skipped = reason
+		return result
+	}
+
+	target := p.target.JoinPath(exchange.Path)
+	target.RawQuery = exchange.Query
+
+	var body io.Reader
+	if exchange.RequestBody != "" {
+		body = strings.NewReader(exchange.RequestBody)
+	}
+	req, err := http.NewRequestWithContext(ctx, exchange.Method, target.String(), body)
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+	for name, value := range exchange.RequestHeaders {
+		name = http.CanonicalHeaderKey(name)
+		if skippedHeaders[name] || strings.Contains(value, logger.RedactedValue) {
+			continue
+		}
+		req.Header.Set(name, value)
+	}
+	if p.config.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+p.config.Token)
+	}
+	req.Header.Set(ReplayHeader, exchange.CaptureID)
+
+	start := time.Now()
+	resp, err := p.client.Do(req)
+	result.DurationMs = time.Since(start).Milliseconds()
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+	io.Copy(io.Discard, resp.Body)
+	resp.Body.Close()
+
+	result.Status = resp.StatusCode
+	return result
+}
+
+// skipReason returns why an exchange cannot be replayed, or "".
+func (p *Replayer) skipReason(exchange *Exchange) string {
+	switch exchange.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+	default:
+		if !p.config.AllowWrites {
+			return "writes are not replayed"
+		}
+	}
+	if exchange.RequestBodyTruncated {
+		return "request body was truncated"
+	}
+	if exchange.RequestBody != "" && !textual(exchange.RequestHeaders["Content-Type"]) {
+		return "request body was not captured"
+	}
+	return ""
+}
+
+// textual reports whether bodies of a content type are captured as text
+// rather than summarized.
+func textual(contentType string) bool {
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+	return mediaType == "" ||
+		strings.Contains(mediaType, "json") ||
+		mediaType == "application/x-www-form-urlencoded" ||
+		strings.HasPrefix(mediaType, "text/") ||
+		strings.HasSuffix(mediaType, "xml")
+}
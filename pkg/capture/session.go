@@ -0,0 +1,222 @@
+package capture
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/kilang-desa-murni/crm/pkg/database"
+	"github.com/kilang-desa-murni/crm/pkg/logger"
+)
+
+// Redis keys of the capture sessions. The hash maps each session ID to its
+// Session; the channel announces changes to every gateway.
+const (
+	sessionsKey     = "gateway:traffic_captures"
+	sessionsChannel = "gateway:traffic_captures:changed"
+)
+
+// sessionsRefreshInterval is how often the sessions are reloaded from Redis,
+// in case a change announcement was missed.
+const sessionsRefreshInterval = 30 * time.Second
+
+// MaxWindow is the longest a capture session may run.
+const MaxWindow = 24 * time.Hour
+
+// ErrInvalidSession reports a capture session that cannot be started.
+var ErrInvalidSession = errors.New("invalid capture session")
+
+// ============================================================================
+// Session
+// ============================================================================
+
+// Session captures the requests of one tenant in a time window, optionally
+// only those under a path prefix.
+type Session struct {
+	ID         string    `json:"id"`
+	TenantID   string    `json:"tenant_id"`
+	PathPrefix string    `json:"path_prefix,omitempty"`
+	Reason     string    `json:"reason,omitempty"`
+	StartsAt   time.Time `json:"starts_at"`
+	EndsAt     time.Time `json:"ends_at"`
+	CreatedBy  string    `json:"created_by,omitempty"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// covers reports whether a request of a tenant to path at now is captured.
+func (s *Session) covers(tenantID, path string, now time.Time) bool {
+	return s.TenantID == tenantID &&
+		!now.Before(s.StartsAt) && now.Before(s.EndsAt) &&
+		hasPrefix(path, s.PathPrefix)
+}
+
+// ============================================================================
+// Sessions
+// ============================================================================
+
+// Sessions keeps the capture sessions in Redis, shared by every gateway
+// instance, and a local copy the middleware checks requests against.
+type Sessions struct {
+	redis *database.RedisClient
+	log   *logger.Logger
+
+	mu       sync.RWMutex
+	sessions []Session
+}
+
+// NewSessions creates the capture session registry.
+func NewSessions(redis *database.RedisClient, log *logger.Logger) *Sessions {
+	return &Sessions{redis: redis, log: log}
+}
+
+// Start loads the sessions and keeps them in sync with Redis until ctx is
+// done, reloading when any gateway announces a change and periodically.
+func (s *Sessions) Start(ctx context.Context) {
+	if err := s.reload(ctx); err != nil {
+		s.log.Warn().Err(err).Msg("Failed to load traffic capture sessions")
+	}
+
+	pubsub := s.redis.Subscribe(ctx, sessionsChannel)
+	go func() {
+		defer pubsub.Close()
+		ticker := time.NewTicker(sessionsRefreshInterval)
+		defer ticker.Stop()
+		changes := pubsub.Channel()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-changes:
+			case <-ticker.C:
+			}
+			if err := s.reload(ctx); err != nil {
+				s.log.Warn().Err(err).Msg("Failed to reload traffic capture sessions")
+			}
+		}
+	}()
+}
+
+// Active returns the session covering a request, or nil.
+func (s *Sessions) Active(tenantID, path string, now time.Time) *Session {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for i := range s.sessions {
+		if s.sessions[i].covers(tenantID, path, now) {
+			session := s.sessions[i]
+			return &session
+		}
+	}
+	return nil
+}
+
+// List returns the sessions that have not ended, dropping ended ones from
+// Redis.
+func (s *Sessions) List(ctx context.Context) ([]Session, error) {
+	raw, err := s.redis.HGetAll(ctx, sessionsKey)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	sessions := make([]Session, 0, len(raw))
+	var ended []string
+	for id, value := range raw {
+		var session Session
+		if err := json.Unmarshal([]byte(value), &session); err != nil {
+			s.log.Warn().Err(err).Str("capture_id", id).Msg("Skipping malformed traffic capture session")
+			continue
+		}
+		if !now.Before(session.EndsAt) {
+			ended = append(ended, id)
+			continue
+		}
+		sessions = append(sessions, session)
+	}
+	if len(ended) > 0 {
+		_ = s.redis.HDel(ctx, sessionsKey, ended...)
+	}
+
+	sort.Slice(sessions, func(i, j int) bool { return sessions[i].StartsAt.Before(sessions[j].StartsAt) })
+	return sessions, nil
+}
+
+// Create starts a capture session. A zero startsAt starts it now.
+func (s *Sessions) Create(ctx context.Context, tenantID, pathPrefix, reason, createdBy string, startsAt time.Time, window time.Duration) (*Session, error) {
+	if _, err := uuid.Parse(tenantID); err != nil {
+		return nil, fmt.Errorf("%w: tenant_id must be a UUID", ErrInvalidSession)
+	}
+	if window <= 0 || window > MaxWindow {
+		return nil, fmt.Errorf("%w: the window must be positive and at most %s", ErrInvalidSession, MaxWindow)
+	}
+	if pathPrefix != "" && !strings.HasPrefix(pathPrefix, "/") {
+		return nil, fmt.Errorf("%w: path_prefix must start with /", ErrInvalidSession)
+	}
+
+	now := time.Now().UTC()
+	if startsAt.IsZero() {
+		startsAt = now
+	}
+	session := &Session{
+		ID:         uuid.NewString(),
+		TenantID:   tenantID,
+		PathPrefix: pathPrefix,
+		Reason:     reason,
+		StartsAt:   startsAt.UTC(),
+		EndsAt:     startsAt.UTC().Add(window),
+		CreatedBy:  createdBy,
+		CreatedAt:  now,
+	}
+	if !now.Before(session.EndsAt) {
+		return nil, fmt.Errorf("%w: the window has already ended", ErrInvalidSession)
+	}
+
+	// HSet stores the session as JSON
+	if err := s.redis.HSet(ctx, sessionsKey, session.ID, session); err != nil {
+		return nil, err
+	}
+	s.announce(ctx)
+	return session, nil
+}
+
+// Stop ends a capture session. It reports false when there is no such
+// session. The exchanges already captured are kept.
+func (s *Sessions) Stop(ctx context.Context, id string) (bool, error) {
+	removed, err := s.redis.Client().HDel(ctx, sessionsKey, id).Result()
+	if err != nil {
+		return false, err
+	}
+	if removed > 0 {
+		s.announce(ctx)
+	}
+	return removed > 0, nil
+}
+
+// announce reloads the local sessions and tells the other gateways to.
+func (s *Sessions) announce(ctx context.Context) {
+	if err := s.reload(ctx); err != nil {
+		s.log.Warn().Err(err).Msg("Failed to reload traffic capture sessions")
+	}
+	if err := s.redis.Publish(ctx, sessionsChannel, time.Now().Unix()); err != nil {
+		s.log.Warn().Err(err).Msg("Failed to announce traffic capture change")
+	}
+}
+
+func (s *Sessions) reload(ctx context.Context) error {
+	sessions, err := s.List(ctx)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	s.sessions = sessions
+	s.mu.Unlock()
+	return nil
+}
@@ -0,0 +1,132 @@
+package capture
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// ErrCaptureNotFound is returned when a capture has no stored exchanges.
+var ErrCaptureNotFound = errors.New("capture not found")
+
+// ============================================================================
+// Store
+// ============================================================================
+
+// Store keeps captured exchanges, grouped by capture session.
+type Store interface {
+	// Put stores an exchange.
+	Put(ctx context.Context, exchange *Exchange) error
+
+	// List returns the exchanges of a capture in the order they started, or
+	// ErrCaptureNotFound.
+	List(ctx context.Context, captureID string) ([]*Exchange, error)
+}
+
+// DirStore implements Store on a directory, typically an object storage
+// bucket mounted into the pod. Each exchange is a JSON object under
+// "<capture_id>/", named so that listing the prefix returns them in order.
+type DirStore struct {
+	baseDir string
+}
+
+// NewDirStore creates a store rooted at baseDir.
+func NewDirStore(baseDir string) (*DirStore, error) {
+	if err := os.MkdirAll(baseDir, 0o750); err != nil {
+		return nil, fmt.Errorf("failed to create capture directory: %w", err)
+	}
+	return &DirStore{baseDir: baseDir}, nil
+}
+
+// Put stores an exchange. The object only appears once written in full.
+func (s *DirStore) Put(ctx context.Context, exchange *Exchange) error {
+	dir, err := s.dir(exchange.CaptureID)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return fmt.Errorf("failed to create capture directory: %w", err)
+	}
+
+	data, err := json.Marshal(exchange)
+	if err != nil {
+		return fmt.Errorf("failed to serialize exchange: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(dir, ".upload-*")
+	if err != nil {
+		return fmt.Errorf("failed to create file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	_, err = tmp.Write(data)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return fmt.Errorf("failed to write file: %w", err)
+	}
+
+	name := fmt.Sprintf("%020d-%s.json", exchange.StartedAt.UnixNano(), uuid.NewString())
+	if err := os.Rename(tmp.Name(), filepath.Join(dir, name)); err != nil {
+		return fmt.Errorf("failed to store file: %w", err)
+	}
+	return nil
+}
+
+// List returns the exchanges of a capture in the order they started.
+func (s *DirStore) List(ctx context.Context, captureID string) ([]*Exchange, error) {
+	dir, err := s.dir(captureID)
+	if err != nil {
+		return nil, err
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, ErrCaptureNotFound
+		}
+		return nil, fmt.Errorf("failed to list capture: %w", err)
+	}
+
+	names := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		if !entry.IsDir() && strings.HasSuffix(entry.Name(), ".json") {
+			names = append(names, entry.Name())
+		}
+	}
+	sort.Strings(names)
+
+	exchanges := make([]*Exchange, 0, len(names))
+	for _, name := range names {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		data, err := os.ReadFile(filepath.Join(dir, name))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", name, err)
+		}
+		var exchange Exchange
+		if err := json.Unmarshal(data, &exchange); err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %w", name, err)
+		}
+		exchanges = append(exchanges, &exchange)
+	}
+	return exchanges, nil
+}
+
+// dir returns the directory of a capture, refusing IDs that are not UUIDs so
+// a capture cannot name a path outside the store.
+func (s *DirStore) dir(captureID string) (string, error) {
+	if _, err := uuid.Parse(captureID); err != nil {
+		return "", fmt.Errorf("invalid capture id %q", captureID)
+	}
+	return filepath.Join(s.baseDir, captureID), nil
+}
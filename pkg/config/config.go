@@ -3,12 +3,15 @@
 package config
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"strings"
 	"time"
 
 	"github.com/spf13/viper"
+
+	"github.com/kilang-desa-murni/crm/pkg/logger"
 )
 
 // Config holds the application configuration.
@@ -23,6 +26,28 @@ type Config struct {
 	Logger   LoggerConfig   `mapstructure:"logger"`
 	Tracer   TracerConfig   `mapstructure:"tracer"`
 	SMTP     SMTPConfig     `mapstructure:"smtp"`
+	Secrets  SecretsConfig  `mapstructure:"secrets"`
+	CORS     CORSConfig     `mapstructure:"cors"`
+	Security SecurityConfig `mapstructure:"security"`
+
+	FeatureFlags FeatureFlagsConfig `mapstructure:"feature_flags"`
+	Startup      StartupConfig      `mapstructure:"startup"`
+	ServiceAuth  ServiceAuthConfig  `mapstructure:"service_auth"`
+
+	RateLimit      RateLimitConfig      `mapstructure:"rate_limit"`
+	CircuitBreaker CircuitBreakerConfig `mapstructure:"circuit_breaker"`
+	LoadShed       LoadShedConfig       `mapstructure:"load_shed"`
+
+	Notification NotificationConfig `mapstructure:"notification"`
+
+	Dev DevConfig `mapstructure:"dev"`
+
+	// secrets resolves the secret references of raw, the configuration as
+	// loaded, when the secrets are refreshed.
+	secrets *SecretResolver
+	raw     *Config
+	// file is the config file read, if any, watched for tunable changes.
+	file string
 }
 
 // AppConfig holds application-specific configuration.
@@ -46,12 +71,25 @@ type ServerConfig struct {
 	TLSKeyFile      string        `mapstructure:"tls_key_file"`
 }
 
+// StartupConfig holds how a service waits for its dependencies at boot.
+// Connections are retried with exponential backoff from InitialBackoff up to
+// MaxBackoff.
+type StartupConfig struct {
+	// MaxWait is how long a dependency is retried before the service exits.
+	MaxWait        time.Duration `mapstructure:"max_wait"`
+	InitialBackoff time.Duration `mapstructure:"initial_backoff"`
+	MaxBackoff     time.Duration `mapstructure:"max_backoff"`
+	// Degraded serves /health as not ready while waiting and keeps retrying
+	// past MaxWait instead of exiting.
+	Degraded bool `mapstructure:"degraded"`
+}
+
 // DatabaseConfig holds PostgreSQL database configuration.
 type DatabaseConfig struct {
 	Host            string        `mapstructure:"host"`
 	Port            int           `mapstructure:"port"`
 	User            string        `mapstructure:"user"`
-	Password        string        `mapstructure:"password"`
+	Password        string        `mapstructure:"password" secret:"true"`
 	DBName          string        `mapstructure:"dbname"`
 	SSLMode         string        `mapstructure:"sslmode"`
 	MaxOpenConns    int           `mapstructure:"max_open_conns"`
@@ -70,7 +108,7 @@ func (c *DatabaseConfig) DSN() string {
 
 // MongoDBConfig holds MongoDB configuration.
 type MongoDBConfig struct {
-	URI            string        `mapstructure:"uri"`
+	URI            string        `mapstructure:"uri" secret:"url"`
 	Database       string        `mapstructure:"database"`
 	MaxPoolSize    uint64        `mapstructure:"max_pool_size"`
 	MinPoolSize    uint64        `mapstructure:"min_pool_size"`
@@ -82,7 +120,7 @@ type MongoDBConfig struct {
 type RedisConfig struct {
 	Host         string        `mapstructure:"host"`
 	Port         int           `mapstructure:"port"`
-	Password     string        `mapstructure:"password"`
+	Password     string        `mapstructure:"password" secret:"true"`
 	DB           int           `mapstructure:"db"`
 	PoolSize     int           `mapstructure:"pool_size"`
 	MinIdleConns int           `mapstructure:"min_idle_conns"`
@@ -98,22 +136,90 @@ func (c *RedisConfig) Addr() string {
 
 // RabbitMQConfig holds RabbitMQ configuration.
 type RabbitMQConfig struct {
-	URL               string        `mapstructure:"url"`
+	URL               string        `mapstructure:"url" secret:"url"`
 	Exchange          string        `mapstructure:"exchange"`
 	ExchangeType      string        `mapstructure:"exchange_type"`
 	ReconnectDelay    time.Duration `mapstructure:"reconnect_delay"`
 	MaxReconnectDelay time.Duration `mapstructure:"max_reconnect_delay"`
 	PrefetchCount     int           `mapstructure:"prefetch_count"`
+	// PublishChannels is the number of confirm-mode channels kept open for
+	// concurrent publishes.
+	PublishChannels int `mapstructure:"publish_channels"`
+	// ConfirmTimeout is how long a publish waits for the broker to confirm
+	// it before failing.
+	ConfirmTimeout time.Duration `mapstructure:"confirm_timeout"`
 }
 
 // JWTConfig holds JWT configuration.
 type JWTConfig struct {
-	Secret           string        `mapstructure:"secret"`
+	// Secret is the key new tokens are signed with.
+	Secret string `mapstructure:"secret" secret:"true"`
+	// KeyID identifies Secret in the kid header of the tokens it signs.
+	KeyID string `mapstructure:"key_id"`
+	// PreviousKeys are rotated-out keys still accepted for tokens issued
+	// before the rotation.
+	PreviousKeys []JWTKeyConfig `mapstructure:"previous_keys"`
+
 	Issuer           string        `mapstructure:"issuer"`
 	Audience         string        `mapstructure:"audience"`
 	AccessExpiry     time.Duration `mapstructure:"access_expiry"`
 	RefreshExpiry    time.Duration `mapstructure:"refresh_expiry"`
 	SigningAlgorithm string        `mapstructure:"signing_algorithm"`
+
+	// TokenMode selects the access tokens issued: self-contained JWTs, or
+	// opaque references to claims the gateway looks up by introspection.
+	TokenMode string `mapstructure:"token_mode"`
+	// IntrospectionCacheTTL caps how long the gateway caches the claims of
+	// an opaque token, and so how long a revoked token stays usable.
+	IntrospectionCacheTTL time.Duration `mapstructure:"introspection_cache_ttl"`
+	// IdentitySecret signs the identity headers the gateway forwards to
+	// backend services. It must be shared by the gateway and the services,
+	// and is required for opaque tokens.
+	IdentitySecret string `mapstructure:"identity_secret" secret:"true"`
+}
+
+// Token modes.
+const (
+	TokenModeJWT    = "jwt"
+	TokenModeOpaque = "opaque"
+)
+
+// ServiceAuthConfig holds the configuration of service-to-service
+// authentication: the short-lived tokens services obtain from the IAM
+// service to call each other's internal endpoints.
+type ServiceAuthConfig struct {
+	// Enabled requires service tokens on internal endpoints and sends them
+	// with internal calls.
+	Enabled bool `mapstructure:"enabled"`
+	// ClientID and ClientSecret are the credentials the service obtains its
+	// tokens with. ClientID defaults to the service name.
+	ClientID     string `mapstructure:"client_id"`
+	ClientSecret string `mapstructure:"client_secret" secret:"true"`
+	// TokenURL is the IAM service's token endpoint.
+	TokenURL string `mapstructure:"token_url"`
+	// TokenSecret signs service tokens. It must be shared by the IAM service
+	// and every service checking tokens.
+	TokenSecret string `mapstructure:"token_secret" secret:"true"`
+	// TokenTTL is how long service tokens are valid.
+	TokenTTL time.Duration `mapstructure:"token_ttl"`
+	// Clients are the services allowed to obtain tokens. Only the IAM
+	// service reads them.
+	Clients []ServiceClientConfig `mapstructure:"clients"`
+}
+
+// ServiceClientConfig holds a service allowed to obtain service tokens, the
+// services it may call and the scopes it is granted.
+type ServiceClientConfig struct {
+	ID        string   `mapstructure:"id"`
+	Secret    string   `mapstructure:"secret" secret:"true"`
+	Audiences []string `mapstructure:"audiences"`
+	Scopes    []string `mapstructure:"scopes"`
+}
+
+// JWTKeyConfig holds a JWT verification key.
+type JWTKeyConfig struct {
+	ID     string `mapstructure:"id"`
+	Secret string `mapstructure:"secret" secret:"true"`
 }
 
 // LoggerConfig holds logger configuration.
@@ -122,6 +228,24 @@ type LoggerConfig struct {
 	Format     string `mapstructure:"format"` // json or console
 	TimeFormat string `mapstructure:"time_format"`
 	Caller     bool   `mapstructure:"caller"`
+
+	Request  RequestLogConfig      `mapstructure:"request"`
+	Shipping logger.ShippingConfig `mapstructure:"shipping"`
+}
+
+// RequestLogConfig controls how HTTP requests are logged.
+type RequestLogConfig struct {
+	// SampleRate is the fraction of successful, fast requests logged, 0 to 1.
+	// Errors and slow requests are always logged.
+	SampleRate float64 `mapstructure:"sample_rate"`
+	// SlowThreshold is the duration from which a request is logged as slow.
+	SlowThreshold time.Duration `mapstructure:"slow_threshold"`
+	// BodyLimit is how many bytes of the request and response bodies are
+	// logged with errors and slow requests; 0 logs no bodies.
+	BodyLimit int `mapstructure:"body_limit"`
+	// RedactFields are redacted in bodies, query strings and headers in
+	// addition to logger.DefaultRedactFields.
+	RedactFields []string `mapstructure:"redact_fields"`
 }
 
 // TracerConfig holds distributed tracing configuration.
@@ -137,14 +261,269 @@ type SMTPConfig struct {
 	Host     string `mapstructure:"host"`
 	Port     int    `mapstructure:"port"`
 	Username string `mapstructure:"username"`
-	Password string `mapstructure:"password"`
+	Password string `mapstructure:"password" secret:"true"`
 	From     string `mapstructure:"from"`
 	FromName string `mapstructure:"from_name"`
 	TLS      bool   `mapstructure:"tls"`
 }
 
+// SecurityConfig holds the security headers sent with responses and the
+// limits requests are sanitized against.
+type SecurityConfig struct {
+	// HSTSMaxAge is the Strict-Transport-Security max-age sent over HTTPS;
+	// zero disables HSTS.
+	HSTSMaxAge            time.Duration `mapstructure:"hsts_max_age"`
+	HSTSIncludeSubdomains bool          `mapstructure:"hsts_include_subdomains"`
+	HSTSPreload           bool          `mapstructure:"hsts_preload"`
+	FrameOptions          string        `mapstructure:"frame_options"`
+	ReferrerPolicy        string        `mapstructure:"referrer_policy"`
+	PermissionsPolicy     string        `mapstructure:"permissions_policy"`
+	ContentSecurityPolicy string        `mapstructure:"content_security_policy"`
+	// CSPReportOnly sends the policy as Content-Security-Policy-Report-Only,
+	// for trying out a policy before enforcing it.
+	CSPReportOnly bool `mapstructure:"csp_report_only"`
+
+	// MaxBodyBytes is the largest request body accepted, except by file
+	// endpoints, which accept FileMaxBodyBytes.
+	MaxBodyBytes     int64 `mapstructure:"max_body_bytes"`
+	FileMaxBodyBytes int64 `mapstructure:"file_max_body_bytes"`
+	// FileEndpoints are the path prefixes of endpoints serving or storing
+	// files, checked for path traversal. A "*" segment matches any segment.
+	FileEndpoints []string `mapstructure:"file_endpoints"`
+}
+
+// FeatureFlagsConfig holds where the services read feature flags from.
+type FeatureFlagsConfig struct {
+	// Store is "redis" or "postgres"; every service must use the same one.
+	Store string `mapstructure:"store"`
+	// Table is the PostgreSQL table of the postgres store.
+	Table string `mapstructure:"table"`
+	// RefreshInterval is how often services reload the flags.
+	RefreshInterval time.Duration `mapstructure:"refresh_interval"`
+}
+
+// RateLimitConfig holds the request rate limit of each tenant. Requests and
+// Window can be changed at runtime.
+type RateLimitConfig struct {
+	Requests int           `mapstructure:"requests"`
+	Window   time.Duration `mapstructure:"window"`
+	// Algorithm counts the requests: "fixed_window" resets the count at the
+	// end of each window, "sliding_window" counts the requests of the last
+	// Window and "token_bucket" refills Requests tokens evenly over Window.
+	Algorithm string `mapstructure:"algorithm"`
+	// Burst is the extra requests authenticated tenants may make on top of
+	// Requests: the size of their window, or of their bucket for
+	// token_bucket, whose refill rate stays the same.
+	Burst int `mapstructure:"burst"`
+}
+
+// CircuitBreakerConfig holds the thresholds of the circuit breakers guarding
+// calls to other services. It can be changed at runtime.
+type CircuitBreakerConfig struct {
+	// FailureThreshold is the number of consecutive failures that opens a
+	// circuit.
+	FailureThreshold uint32 `mapstructure:"failure_threshold"`
+	// MaxRequests is the number of trial requests let through a half-open
+	// circuit.
+	MaxRequests uint32 `mapstructure:"max_requests"`
+	// Interval is how often the failure counts of a closed circuit reset.
+	Interval time.Duration `mapstructure:"interval"`
+	// Timeout is how long a circuit stays open before trial requests.
+	Timeout time.Duration `mapstructure:"timeout"`
+}
+
+// LoadShedConfig holds the adaptive concurrency limit of a service's HTTP
+// requests. Requests over the limit wait in a bounded queue and are shed with
+// 503 when it is full or their wait times out.
+type LoadShedConfig struct {
+	Enabled bool `mapstructure:"enabled"`
+	// Algorithm adapts the limit: "gradient" follows the ratio of long-term
+	// to recent latency, "aimd" grows it by one per request faster than
+	// LatencyThreshold and cuts it by a tenth otherwise.
+	Algorithm        string        `mapstructure:"algorithm"`
+	InitialLimit     int           `mapstructure:"initial_limit"`
+	MinLimit         int           `mapstructure:"min_limit"`
+	MaxLimit         int           `mapstructure:"max_limit"`
+	LatencyThreshold time.Duration `mapstructure:"latency_threshold"`
+	QueueSize        int           `mapstructure:"queue_size"`
+	QueueTimeout     time.Duration `mapstructure:"queue_timeout"`
+	// BackgroundShare is the share of the limit background requests, such
+	// as imports and exports, may take. They are shed rather than queued,
+	// so interactive requests are served first.
+	BackgroundShare float64 `mapstructure:"background_share"`
+	// RetryAfter is sent to shed clients in the Retry-After header.
+	RetryAfter time.Duration `mapstructure:"retry_after"`
+	// BypassPaths are path prefixes never shed, such as health checks and
+	// critical endpoints.
+	BypassPaths []string `mapstructure:"bypass_paths"`
+}
+
+// NotificationConfig holds notification service configuration.
+type NotificationConfig struct {
+	// TestRecipients are the email addresses and phone numbers that template
+	// test sends may be delivered to.
+	TestRecipients []string `mapstructure:"test_recipients"`
+
+	// DeliveryConcurrency is the number of workers delivering queued
+	// notifications, shared by all priorities.
+	DeliveryConcurrency int `mapstructure:"delivery_concurrency"`
+	// DeliveryWeights is the share of deliveries each priority (critical,
+	// high, normal, low) gets while several have notifications waiting.
+	DeliveryWeights map[string]int `mapstructure:"delivery_weights"`
+	// DeliveryMaxWait is how long a notification may wait behind higher
+	// priorities before it is delivered first.
+	DeliveryMaxWait time.Duration `mapstructure:"delivery_max_wait"`
+
+	// DispatchWorkers is the number of event dispatch workers per channel.
+	DispatchWorkers map[string]int `mapstructure:"dispatch_workers"`
+	// DispatchQueueSize bounds the events waiting for each channel's workers.
+	DispatchQueueSize int `mapstructure:"dispatch_queue_size"`
+	// DispatchSubmitTimeout is how long an event waits for room in a full
+	// queue before it is handed back to RabbitMQ.
+	DispatchSubmitTimeout time.Duration `mapstructure:"dispatch_submit_timeout"`
+
+	// Retry is the delivery retry policy of every channel.
+	Retry RetryPolicyConfig `mapstructure:"retry"`
+	// RetryChannels overrides the retry policy settings of individual
+	// channels; settings left out keep the value from Retry.
+	RetryChannels map[string]RetryPolicyConfig `mapstructure:"retry_channels"`
+
+	// ArchiveRetentionDays is how long sent emails are archived.
+	ArchiveRetentionDays int `mapstructure:"archive_retention_days"`
+	// ArchiveTenantRetentionDays overrides the archive retention of
+	// individual tenants, keyed by tenant ID.
+	ArchiveTenantRetentionDays map[string]int `mapstructure:"archive_tenant_retention_days"`
+	// ArchiveInlineLimit is the largest archived message, in bytes, stored in
+	// the database; larger messages go to object storage.
+	ArchiveInlineLimit int `mapstructure:"archive_inline_limit"`
+
+	// BodyRetentionDays is how long the bodies of sent notifications are
+	// kept; zero keeps them.
+	BodyRetentionDays int `mapstructure:"body_retention_days"`
+	// BodyTenantRetentionDays overrides the body retention of individual
+	// tenants, keyed by tenant ID.
+	BodyTenantRetentionDays map[string]int `mapstructure:"body_tenant_retention_days"`
+	// BodyPurgeInterval is how often expired bodies are cleared.
+	BodyPurgeInterval time.Duration `mapstructure:"body_purge_interval"`
+
+	// RoutingReloadInterval is how often the routing rules of every tenant
+	// are reloaded, picking up changes made through other instances.
+	RoutingReloadInterval time.Duration `mapstructure:"routing_reload_interval"`
+
+	// EscalationInterval is how often critical alerts nobody acknowledged
+	// in time are escalated to their next level.
+	EscalationInterval time.Duration `mapstructure:"escalation_interval"`
+
+	// WebPush configures push notifications to browsers.
+	WebPush WebPushConfig `mapstructure:"web_push"`
+}
+
+// WebPushConfig holds the VAPID keys (RFC 8292) browser push subscriptions
+// are made and sent with. Web push is disabled without any private key.
+type WebPushConfig struct {
+	// Subject is the contact push services reach the operator at, a
+	// mailto: or https: URL.
+	Subject string `mapstructure:"subject"`
+	// PrivateKey is the base64url-encoded P-256 private key of tenants
+	// without their own.
+	PrivateKey string `mapstructure:"private_key" secret:"true"`
+	// TenantKeys are the keys of tenants with their own.
+	TenantKeys []WebPushTenantKeyConfig `mapstructure:"tenant_keys"`
+	// TTL is how long push services keep undelivered messages.
+	TTL time.Duration `mapstructure:"ttl"`
+	// CleanupInterval is how often expired subscriptions are removed.
+	CleanupInterval time.Duration `mapstructure:"cleanup_interval"`
+}
+
+// WebPushTenantKeyConfig is the VAPID private key of one tenant.
+type WebPushTenantKeyConfig struct {
+	TenantID   string `mapstructure:"tenant_id"`
+	PrivateKey string `mapstructure:"private_key" secret:"true"`
+}
+
+// Enabled reports whether any VAPID key is configured.
+func (c WebPushConfig) Enabled() bool {
+	return c.PrivateKey != "" || len(c.TenantKeys) > 0
+}
+
+// RetryPolicyConfig holds a notification delivery retry policy.
+type RetryPolicyConfig struct {
+	MaxAttempts     int           `mapstructure:"max_attempts"`
+	InitialInterval time.Duration `mapstructure:"initial_interval"`
+	MaxInterval     time.Duration `mapstructure:"max_interval"`
+	Multiplier      float64       `mapstructure:"multiplier"`
+	// Jitter is the fraction of the backoff interval, 0 to 1, taken off at
+	// random so failed notifications do not all retry at once.
+	Jitter float64 `mapstructure:"jitter"`
+	// RetryableErrors, when set, are the only delivery error codes retried.
+	RetryableErrors []string `mapstructure:"retryable_errors"`
+	// NonRetryableErrors are delivery error codes that are never retried.
+	NonRetryableErrors []string `mapstructure:"non_retryable_errors"`
+	// ProviderErrors overrides the classification per provider.
+	ProviderErrors map[string]RetryErrorRulesConfig `mapstructure:"provider_errors"`
+}
+
+// RetryErrorRulesConfig classifies one provider's delivery error codes.
+type RetryErrorRulesConfig struct {
+	Retryable    []string `mapstructure:"retryable"`
+	NonRetryable []string `mapstructure:"non_retryable"`
+}
+
+// DevConfig holds the local development mode, in which a service runs
+// without its message broker and cache: events are delivered in process,
+// the cache is kept in memory and outbound email and SMS are written to the
+// log. PostgreSQL is still required.
+type DevConfig struct {
+	Enabled bool `mapstructure:"enabled"`
+}
+
 // Load loads configuration from file and environment variables.
 func Load(configPath string) (*Config, error) {
+	v, err := newViper(configPath)
+	if err != nil {
+		return nil, err
+	}
+
+	// Unmarshal config
+	var cfg Config
+	if err := v.Unmarshal(&cfg); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal config: %w", err)
+	}
+	cfg.file = v.ConfigFileUsed()
+
+	if cfg.Dev.Enabled && cfg.IsProduction() {
+		return nil, fmt.Errorf("dev mode cannot be enabled in production")
+	}
+
+	// Resolve secret references, keeping the references for refreshes
+	raw := cfg
+	cfg.secrets = NewSecretResolver(cfg.Secrets)
+	if err := resolveSecrets(context.Background(), &cfg, cfg.secrets); err != nil {
+		return nil, fmt.Errorf("failed to resolve secrets: %w", err)
+	}
+	cfg.raw = &raw
+
+	switch cfg.JWT.TokenMode {
+	case TokenModeJWT:
+	case TokenModeOpaque:
+		// Backend services cannot read opaque tokens, only the signed
+		// identity the gateway forwards
+		if cfg.JWT.IdentitySecret == "" {
+			return nil, fmt.Errorf("opaque tokens need a jwt identity secret")
+		}
+	default:
+		return nil, fmt.Errorf("invalid jwt token mode %q, want jwt or opaque", cfg.JWT.TokenMode)
+	}
+
+	if cfg.ServiceAuth.Enabled && len(cfg.ServiceAuth.TokenSecret) < 32 {
+		return nil, fmt.Errorf("service auth needs a token secret of at least 32 bytes")
+	}
+
+	return &cfg, nil
+}
+
+// newViper reads the defaults, the config file and the environment.
+func newViper(configPath string) (*viper.Viper, error) {
 	v := viper.New()
 
 	// Set default values
@@ -178,13 +557,7 @@ func Load(configPath string) (*Config, error) {
 	// Override with environment variables
 	bindEnvVars(v)
 
-	// Unmarshal config
-	var cfg Config
-	if err := v.Unmarshal(&cfg); err != nil {
-		return nil, fmt.Errorf("failed to unmarshal config: %w", err)
-	}
-
-	return &cfg, nil
+	return v, nil
 }
 
 // setDefaults sets default configuration values.
@@ -204,6 +577,12 @@ func setDefaults(v *viper.Viper) {
 	v.SetDefault("server.shutdown_timeout", 30*time.Second)
 	v.SetDefault("server.tls_enabled", false)
 
+	// Startup defaults
+	v.SetDefault("startup.max_wait", 2*time.Minute)
+	v.SetDefault("startup.initial_backoff", time.Second)
+	v.SetDefault("startup.max_backoff", 30*time.Second)
+	v.SetDefault("startup.degraded", false)
+
 	// Database defaults
 	v.SetDefault("database.host", "localhost")
 	v.SetDefault("database.port", 5432)
@@ -242,6 +621,8 @@ func setDefaults(v *viper.Viper) {
 	v.SetDefault("rabbitmq.reconnect_delay", 5*time.Second)
 	v.SetDefault("rabbitmq.max_reconnect_delay", 60*time.Second)
 	v.SetDefault("rabbitmq.prefetch_count", 10)
+	v.SetDefault("rabbitmq.publish_channels", 8)
+	v.SetDefault("rabbitmq.confirm_timeout", 5*time.Second)
 
 	// JWT defaults
 	v.SetDefault("jwt.secret", "change-me-in-production")
@@ -250,12 +631,22 @@ func setDefaults(v *viper.Viper) {
 	v.SetDefault("jwt.access_expiry", 24*time.Hour)
 	v.SetDefault("jwt.refresh_expiry", 7*24*time.Hour)
 	v.SetDefault("jwt.signing_algorithm", "HS256")
+	v.SetDefault("jwt.token_mode", TokenModeJWT)
+	v.SetDefault("jwt.introspection_cache_ttl", 30*time.Second)
+
+	// Service auth defaults
+	v.SetDefault("service_auth.enabled", false)
+	v.SetDefault("service_auth.token_url", "http://localhost:8081/internal/auth/token")
+	v.SetDefault("service_auth.token_ttl", 5*time.Minute)
 
 	// Logger defaults
 	v.SetDefault("logger.level", "info")
 	v.SetDefault("logger.format", "json")
 	v.SetDefault("logger.time_format", time.RFC3339Nano)
 	v.SetDefault("logger.caller", false)
+	v.SetDefault("logger.request.sample_rate", 1.0)
+	v.SetDefault("logger.request.slow_threshold", time.Second)
+	v.SetDefault("logger.request.body_limit", 4096)
 
 	// Tracer defaults
 	v.SetDefault("tracer.enabled", false)
@@ -271,32 +662,203 @@ func setDefaults(v *viper.Viper) {
 	v.SetDefault("smtp.from", "noreply@example.com")
 	v.SetDefault("smtp.from_name", "CRM System")
 	v.SetDefault("smtp.tls", false)
+
+	// CORS defaults
+	v.SetDefault("cors.allowed_origins", []string{})
+	v.SetDefault("cors.environment_origins", map[string][]string{
+		"development": {"http://localhost:3000", "http://localhost:5173"},
+	})
+	v.SetDefault("cors.allowed_methods", []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"})
+	v.SetDefault("cors.allowed_headers", []string{
+		"Accept", "Accept-Language", "Authorization", "Content-Type", "Idempotency-Key", "If-Match",
+		"If-None-Match", "X-CSRF-Token", "X-Request-ID", "X-Session-Mode", "X-Tenant-ID",
+	})
+	v.SetDefault("cors.exposed_headers", []string{"ETag", "X-Request-ID", "X-RateLimit-Limit", "X-RateLimit-Remaining", "X-Total-Count"})
+	v.SetDefault("cors.allow_credentials", true)
+	v.SetDefault("cors.max_age", 10*time.Minute)
+	v.SetDefault("cors.tenant_origins_refresh", time.Minute)
+
+	// Security defaults
+	v.SetDefault("security.hsts_max_age", 365*24*time.Hour)
+	v.SetDefault("security.hsts_include_subdomains", true)
+	v.SetDefault("security.hsts_preload", false)
+	v.SetDefault("security.frame_options", "DENY")
+	v.SetDefault("security.referrer_policy", "strict-origin-when-cross-origin")
+	v.SetDefault("security.permissions_policy", "camera=(), geolocation=(), microphone=()")
+	v.SetDefault("security.content_security_policy", "default-src 'none'; frame-ancestors 'none'; base-uri 'none'")
+	v.SetDefault("security.csp_report_only", false)
+	v.SetDefault("security.max_body_bytes", 10<<20)
+	v.SetDefault("security.file_max_body_bytes", 50<<20)
+	v.SetDefault("security.file_endpoints", []string{
+		"/api/v1/opportunities/*/attachments", "/api/v1/imports", "/api/v1/import-uploads", "/api/v1/customers/export",
+		"/api/v1/reports/exports", "/api/v1/notifications/archive", "/api/v1/inbound-email/messages",
+	})
+
+	// Feature flag defaults
+	v.SetDefault("feature_flags.store", "redis")
+	v.SetDefault("feature_flags.table", "iam.feature_flags")
+	v.SetDefault("feature_flags.refresh_interval", 30*time.Second)
+
+	// Rate limit and circuit breaker defaults
+	v.SetDefault("rate_limit.requests", 100)
+	v.SetDefault("rate_limit.window", time.Minute)
+	v.SetDefault("rate_limit.algorithm", "sliding_window")
+	v.SetDefault("rate_limit.burst", 0)
+	v.SetDefault("circuit_breaker.failure_threshold", 5)
+	v.SetDefault("circuit_breaker.max_requests", 5)
+	v.SetDefault("circuit_breaker.interval", time.Minute)
+	v.SetDefault("circuit_breaker.timeout", 30*time.Second)
+
+	// Load shedding defaults
+	v.SetDefault("load_shed.enabled", true)
+	v.SetDefault("load_shed.algorithm", "gradient")
+	v.SetDefault("load_shed.initial_limit", 50)
+	v.SetDefault("load_shed.min_limit", 10)
+	v.SetDefault("load_shed.max_limit", 500)
+	v.SetDefault("load_shed.latency_threshold", time.Second)
+	v.SetDefault("load_shed.queue_size", 100)
+	v.SetDefault("load_shed.queue_timeout", 500*time.Millisecond)
+	v.SetDefault("load_shed.background_share", 0.5)
+	v.SetDefault("load_shed.retry_after", 2*time.Second)
+	v.SetDefault("load_shed.bypass_paths", []string{"/health", "/metrics"})
+
+	// Secrets defaults
+	v.SetDefault("secrets.cache_ttl", 5*time.Minute)
+	v.SetDefault("secrets.refresh_interval", 5*time.Minute)
+	v.SetDefault("secrets.timeout", 10*time.Second)
+
+	// Notification defaults
+	v.SetDefault("notification.test_recipients", []string{})
+	v.SetDefault("notification.delivery_concurrency", 10)
+	v.SetDefault("notification.delivery_weights", map[string]int{"critical": 8, "high": 4, "normal": 2, "low": 1})
+	v.SetDefault("notification.delivery_max_wait", 2*time.Minute)
+	v.SetDefault("notification.dispatch_workers", map[string]int{"email": 4, "sms": 2, "push": 2, "in_app": 2})
+	v.SetDefault("notification.dispatch_queue_size", 100)
+	v.SetDefault("notification.dispatch_submit_timeout", 5*time.Second)
+	v.SetDefault("notification.retry.max_attempts", 3)
+	v.SetDefault("notification.retry.initial_interval", time.Minute)
+	v.SetDefault("notification.retry.max_interval", time.Hour)
+	v.SetDefault("notification.retry.multiplier", 2.0)
+	v.SetDefault("notification.retry.jitter", 0.2)
+	v.SetDefault("notification.retry.non_retryable_errors", []string{"REJECTED", "UNDELIVERABLE"})
+	v.SetDefault("notification.archive_retention_days", 2555)
+	v.SetDefault("notification.archive_inline_limit", 256*1024)
+	v.SetDefault("notification.body_retention_days", 0)
+	v.SetDefault("notification.body_purge_interval", time.Hour)
+	v.SetDefault("notification.routing_reload_interval", 30*time.Second)
+	v.SetDefault("notification.escalation_interval", 30*time.Second)
+	v.SetDefault("notification.web_push.ttl", 24*time.Hour)
+	v.SetDefault("notification.web_push.cleanup_interval", time.Hour)
+
+	// Dev mode defaults
+	v.SetDefault("dev.enabled", false)
 }
 
 // bindEnvVars binds environment variables to config keys.
 func bindEnvVars(v *viper.Viper) {
 	// Map environment variables to config keys
 	envMappings := map[string]string{
-		"APP_ENV":          "app.environment",
-		"APP_DEBUG":        "app.debug",
-		"APP_PORT":         "server.port",
-		"DB_HOST":          "database.host",
-		"DB_PORT":          "database.port",
-		"DB_USER":          "database.user",
-		"DB_PASSWORD":      "database.password",
-		"DB_NAME":          "database.dbname",
-		"MONGODB_URI":      "mongodb.uri",
-		"REDIS_HOST":       "redis.host",
-		"REDIS_PORT":       "redis.port",
-		"REDIS_PASSWORD":   "redis.password",
-		"RABBITMQ_URL":     "rabbitmq.url",
-		"JWT_SECRET":       "jwt.secret",
-		"JWT_EXPIRY":       "jwt.access_expiry",
-		"JAEGER_ENDPOINT":  "tracer.endpoint",
-		"LOG_LEVEL":        "logger.level",
-		"SMTP_HOST":        "smtp.host",
-		"SMTP_PORT":        "smtp.port",
-		"SMTP_FROM":        "smtp.from",
+		"APP_ENV":         "app.environment",
+		"APP_DEBUG":       "app.debug",
+		"APP_PORT":        "server.port",
+		"DB_HOST":         "database.host",
+		"DB_PORT":         "database.port",
+		"DB_USER":         "database.user",
+		"DB_PASSWORD":     "database.password",
+		"DB_NAME":         "database.dbname",
+		"MONGODB_URI":     "mongodb.uri",
+		"REDIS_HOST":      "redis.host",
+		"REDIS_PORT":      "redis.port",
+		"REDIS_PASSWORD":  "redis.password",
+		"RABBITMQ_URL":    "rabbitmq.url",
+		"JWT_SECRET":      "jwt.secret",
+		"JWT_EXPIRY":      "jwt.access_expiry",
+		"JWT_KEY_ID":      "jwt.key_id",
+		"JAEGER_ENDPOINT": "tracer.endpoint",
+		"LOG_LEVEL":       "logger.level",
+		"SMTP_HOST":       "smtp.host",
+		"SMTP_PORT":       "smtp.port",
+		"SMTP_FROM":       "smtp.from",
+
+		"JWT_TOKEN_MODE":              "jwt.token_mode",
+		"JWT_INTROSPECTION_CACHE_TTL": "jwt.introspection_cache_ttl",
+		"JWT_IDENTITY_SECRET":         "jwt.identity_secret",
+
+		"SERVICE_AUTH_ENABLED":       "service_auth.enabled",
+		"SERVICE_AUTH_CLIENT_ID":     "service_auth.client_id",
+		"SERVICE_AUTH_CLIENT_SECRET": "service_auth.client_secret",
+		"SERVICE_AUTH_TOKEN_URL":     "service_auth.token_url",
+		"SERVICE_AUTH_TOKEN_SECRET":  "service_auth.token_secret",
+		"SERVICE_AUTH_TOKEN_TTL":     "service_auth.token_ttl",
+
+		"LOG_REQUEST_SAMPLE_RATE":    "logger.request.sample_rate",
+		"LOG_REQUEST_SLOW_THRESHOLD": "logger.request.slow_threshold",
+		"LOG_REQUEST_BODY_LIMIT":     "logger.request.body_limit",
+		"LOG_SHIPPING_SINK":          "logger.shipping.sink",
+		"LOG_SHIPPING_URL":           "logger.shipping.url",
+		"LOG_SHIPPING_INDEX":         "logger.shipping.index",
+		"LOG_SHIPPING_USERNAME":      "logger.shipping.username",
+		"LOG_SHIPPING_PASSWORD":      "logger.shipping.password",
+
+		"CORS_ALLOWED_ORIGINS":    "cors.allowed_origins",
+		"CORS_ALLOW_CREDENTIALS":  "cors.allow_credentials",
+		"CORS_MAX_AGE":            "cors.max_age",
+		"CORS_TENANT_ORIGINS_URL": "cors.tenant_origins_url",
+
+		"SECURITY_HSTS_MAX_AGE":            "security.hsts_max_age",
+		"SECURITY_CONTENT_SECURITY_POLICY": "security.content_security_policy",
+		"SECURITY_CSP_REPORT_ONLY":         "security.csp_report_only",
+		"SECURITY_MAX_BODY_BYTES":          "security.max_body_bytes",
+		"SECURITY_FILE_MAX_BODY_BYTES":     "security.file_max_body_bytes",
+
+		"RATE_LIMIT_REQUESTS":               "rate_limit.requests",
+		"RATE_LIMIT_WINDOW":                 "rate_limit.window",
+		"RATE_LIMIT_ALGORITHM":              "rate_limit.algorithm",
+		"RATE_LIMIT_BURST":                  "rate_limit.burst",
+		"CIRCUIT_BREAKER_FAILURE_THRESHOLD": "circuit_breaker.failure_threshold",
+		"CIRCUIT_BREAKER_TIMEOUT":           "circuit_breaker.timeout",
+		"LOAD_SHED_ENABLED":                 "load_shed.enabled",
+		"LOAD_SHED_ALGORITHM":               "load_shed.algorithm",
+		"LOAD_SHED_MAX_LIMIT":               "load_shed.max_limit",
+		"LOAD_SHED_QUEUE_TIMEOUT":           "load_shed.queue_timeout",
+		"LOAD_SHED_BACKGROUND_SHARE":        "load_shed.background_share",
+
+		"RABBITMQ_PUBLISH_CHANNELS": "rabbitmq.publish_channels",
+		"RABBITMQ_CONFIRM_TIMEOUT":  "rabbitmq.confirm_timeout",
+
+		"STARTUP_MAX_WAIT": "startup.max_wait",
+		"STARTUP_DEGRADED": "startup.degraded",
+
+		"FEATURE_FLAGS_STORE":            "feature_flags.store",
+		"FEATURE_FLAGS_REFRESH_INTERVAL": "feature_flags.refresh_interval",
+
+		"VAULT_ADDR":            "secrets.vault.address",
+		"VAULT_TOKEN":           "secrets.vault.token",
+		"VAULT_NAMESPACE":       "secrets.vault.namespace",
+		"AWS_REGION":            "secrets.aws.region",
+		"AWS_ACCESS_KEY_ID":     "secrets.aws.access_key_id",
+		"AWS_SECRET_ACCESS_KEY": "secrets.aws.secret_access_key",
+		"AWS_SESSION_TOKEN":     "secrets.aws.session_token",
+
+		"NOTIFICATION_TEST_RECIPIENTS":          "notification.test_recipients",
+		"NOTIFICATION_DELIVERY_CONCURRENCY":     "notification.delivery_concurrency",
+		"NOTIFICATION_DELIVERY_MAX_WAIT":        "notification.delivery_max_wait",
+		"NOTIFICATION_DISPATCH_QUEUE_SIZE":      "notification.dispatch_queue_size",
+		"NOTIFICATION_DISPATCH_SUBMIT_TIMEOUT":  "notification.dispatch_submit_timeout",
+		"NOTIFICATION_RETRY_MAX_ATTEMPTS":       "notification.retry.max_attempts",
+		"NOTIFICATION_RETRY_JITTER":             "notification.retry.jitter",
+		"NOTIFICATION_ARCHIVE_RETENTION_DAYS":   "notification.archive_retention_days",
+		"NOTIFICATION_ARCHIVE_INLINE_LIMIT":     "notification.archive_inline_limit",
+		"NOTIFICATION_BODY_RETENTION_DAYS":      "notification.body_retention_days",
+		"NOTIFICATION_BODY_PURGE_INTERVAL":      "notification.body_purge_interval",
+		"NOTIFICATION_ROUTING_RELOAD_INTERVAL":  "notification.routing_reload_interval",
+		"NOTIFICATION_ESCALATION_INTERVAL":      "notification.escalation_interval",
+		"NOTIFICATION_WEBPUSH_SUBJECT":          "notification.web_push.subject",
+		"NOTIFICATION_WEBPUSH_PRIVATE_KEY":      "notification.web_push.private_key",
+		"NOTIFICATION_WEBPUSH_TTL":              "notification.web_push.ttl",
+		"NOTIFICATION_WEBPUSH_CLEANUP_INTERVAL": "notification.web_push.cleanup_interval",
+
+		"DEV_MODE": "dev.enabled",
 	}
 
 	for env, key := range envMappings {
@@ -329,3 +891,8 @@ func (c *Config) IsProduction() bool {
 func (c *Config) IsStaging() bool {
 	return c.App.Environment == "staging"
 }
+
+// IsDevMode returns true if the service runs in local development mode.
+func (c *Config) IsDevMode() bool {
+	return c.Dev.Enabled
+}
@@ -0,0 +1,96 @@
+package config
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLoad_DevMode(t *testing.T) {
+	cfg, err := Load("")
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if cfg.IsDevMode() {
+		t.Error("dev mode should be disabled by default")
+	}
+
+	t.Setenv("DEV_MODE", "true")
+	cfg, err = Load("")
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if !cfg.IsDevMode() {
+		t.Error("DEV_MODE=true should enable dev mode")
+	}
+
+	t.Setenv("APP_ENV", "production")
+	if _, err := Load(""); err == nil {
+		t.Error("Load() should refuse dev mode in production")
+	}
+}
+
+func TestLoad_TokenMode(t *testing.T) {
+	cfg, err := Load("")
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if cfg.JWT.TokenMode != TokenModeJWT {
+		t.Errorf("TokenMode = %q, want %q by default", cfg.JWT.TokenMode, TokenModeJWT)
+	}
+
+	t.Setenv("JWT_TOKEN_MODE", TokenModeOpaque)
+	if _, err := Load(""); err == nil {
+		t.Error("Load() should refuse opaque tokens without an identity secret")
+	}
+
+	t.Setenv("JWT_IDENTITY_SECRET", "identity-secret")
+	if _, err := Load(""); err != nil {
+		t.Errorf("Load() with opaque tokens error = %v", err)
+	}
+
+	t.Setenv("JWT_TOKEN_MODE", "paseto")
+	if _, err := Load(""); err == nil {
+		t.Error("Load() should refuse an unknown token mode")
+	}
+}
+
+func TestLoad_RateLimit(t *testing.T) {
+	cfg, err := Load("")
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if cfg.RateLimit.Algorithm != "sliding_window" || cfg.RateLimit.Burst != 0 {
+		t.Errorf("RateLimit = %+v, want a sliding window without burst by default", cfg.RateLimit)
+	}
+
+	t.Setenv("RATE_LIMIT_ALGORITHM", "token_bucket")
+	t.Setenv("RATE_LIMIT_BURST", "50")
+	cfg, err = Load("")
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if cfg.RateLimit.Algorithm != "token_bucket" || cfg.RateLimit.Burst != 50 {
+		t.Errorf("RateLimit = %+v, want a token bucket with a burst of 50", cfg.RateLimit)
+	}
+}
+
+func TestLoad_ServiceAuth(t *testing.T) {
+	cfg, err := Load("")
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if cfg.ServiceAuth.Enabled || cfg.ServiceAuth.TokenTTL != 5*time.Minute {
+		t.Errorf("ServiceAuth = %+v, want disabled with 5m tokens by default", cfg.ServiceAuth)
+	}
+
+	t.Setenv("SERVICE_AUTH_ENABLED", "true")
+	t.Setenv("SERVICE_AUTH_TOKEN_SECRET", "too-short")
+	if _, err := Load(""); err == nil {
+		t.Error("Load() should refuse a short service token secret")
+	}
+
+	t.Setenv("SERVICE_AUTH_TOKEN_SECRET", "service-token-secret-of-32-bytes")
+	if _, err := Load(""); err != nil {
+		t.Errorf("Load() with service auth error = %v", err)
+	}
+}
@@ -0,0 +1,71 @@
+package config
+
+import (
+	"strings"
+	"time"
+)
+
+// CORSConfig holds the cross-origin resource sharing policy.
+//
+// Origins are written as scheme://host[:port]. A host starting with "*."
+// matches every subdomain of the rest of the host, but not the domain
+// itself: https://*.kilangbatik.my matches https://shop.kilangbatik.my. The
+// origin "*" matches any origin, but is answered without credentials, as
+// browsers refuse credentialed responses to it.
+type CORSConfig struct {
+	// AllowedOrigins are allowed in every environment.
+	AllowedOrigins []string `mapstructure:"allowed_origins"`
+	// EnvironmentOrigins are allowed in addition in one environment, keyed
+	// by environment name.
+	EnvironmentOrigins map[string][]string `mapstructure:"environment_origins"`
+
+	AllowedMethods   []string `mapstructure:"allowed_methods"`
+	AllowedHeaders   []string `mapstructure:"allowed_headers"`
+	ExposedHeaders   []string `mapstructure:"exposed_headers"`
+	AllowCredentials bool     `mapstructure:"allow_credentials"`
+	// MaxAge is how long browsers may cache preflight responses.
+	MaxAge time.Duration `mapstructure:"max_age"`
+
+	// TenantOriginsURL is the IAM endpoint listing the origins tenants have
+	// allowed for their own users. Empty disables tenant origins.
+	TenantOriginsURL string `mapstructure:"tenant_origins_url"`
+	// TenantOriginsRefresh is how often the tenant origins are reloaded.
+	TenantOriginsRefresh time.Duration `mapstructure:"tenant_origins_refresh"`
+}
+
+// Origins returns the origins allowed in an environment.
+func (c *CORSConfig) Origins(environment string) []string {
+	origins := make([]string, 0, len(c.AllowedOrigins)+len(c.EnvironmentOrigins[environment]))
+	origins = append(origins, c.AllowedOrigins...)
+	return append(origins, c.EnvironmentOrigins[environment]...)
+}
+
+// MatchOrigin reports whether origin matches one of the origin patterns.
+func MatchOrigin(patterns []string, origin string) bool {
+	if origin == "" {
+		return false
+	}
+	origin = strings.ToLower(origin)
+
+	for _, pattern := range patterns {
+		pattern = strings.ToLower(strings.TrimRight(strings.TrimSpace(pattern), "/"))
+		if pattern == "*" || pattern == origin {
+			return true
+		}
+
+		scheme, host, ok := strings.Cut(pattern, "://*.")
+		if !ok {
+			continue
+		}
+		prefix := scheme + "://"
+		if !strings.HasPrefix(origin, prefix) {
+			continue
+		}
+		// The subdomain may not be empty or contain a port of its own
+		subdomain, found := strings.CutSuffix(strings.TrimPrefix(origin, prefix), "."+host)
+		if found && subdomain != "" && !strings.ContainsAny(subdomain, ":/") {
+			return true
+		}
+	}
+	return false
+}
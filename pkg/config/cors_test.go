@@ -0,0 +1,58 @@
+package config
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestMatchOrigin(t *testing.T) {
+	patterns := []string{"https://app.kilangbatik.my", "https://*.kilangbatik.my", "http://localhost:3000/"}
+
+	tests := []struct {
+		origin string
+		want   bool
+	}{
+		{"https://app.kilangbatik.my", true},
+		{"https://shop.kilangbatik.my", true},
+		{"https://a.b.kilangbatik.my", true},
+		{"HTTPS://Shop.KilangBatik.my", true},
+		{"http://localhost:3000", true},
+		{"https://kilangbatik.my", false},
+		{"http://shop.kilangbatik.my", false},
+		{"https://evilkilangbatik.my", false},
+		{"https://shop.kilangbatik.my.evil.com", false},
+		{"https://shop.kilangbatik.my:8443", false},
+		{"http://localhost:3001", false},
+		{"", false},
+	}
+
+	for _, tt := range tests {
+		if got := MatchOrigin(patterns, tt.origin); got != tt.want {
+			t.Errorf("MatchOrigin(%q) = %v, want %v", tt.origin, got, tt.want)
+		}
+	}
+
+	if !MatchOrigin([]string{"*"}, "https://anything.example") {
+		t.Error("MatchOrigin() should match any origin with *")
+	}
+}
+
+func TestLoad_CORSOrigins(t *testing.T) {
+	t.Setenv("CORS_ALLOWED_ORIGINS", "https://app.kilangbatik.my,https://*.kilangbatik.my")
+
+	cfg, err := Load("")
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	want := []string{"https://app.kilangbatik.my", "https://*.kilangbatik.my", "http://localhost:3000", "http://localhost:5173"}
+	if got := cfg.CORS.Origins("development"); !reflect.DeepEqual(got, want) {
+		t.Errorf("development origins = %v, want %v", got, want)
+	}
+	if got := cfg.CORS.Origins("production"); len(got) != 2 {
+		t.Errorf("production origins = %v, want only the general origins", got)
+	}
+	if !cfg.CORS.AllowCredentials || cfg.CORS.MaxAge <= 0 {
+		t.Errorf("CORS config = %+v, want credentials and preflight caching by default", cfg.CORS)
+	}
+}
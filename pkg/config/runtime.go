@@ -0,0 +1,346 @@
+package config
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// ============================================================================
+// Tunables
+// ============================================================================
+
+// Tunables are the settings a running service applies without a restart.
+type Tunables struct {
+	LogLevel       string
+	RateLimit      RateLimitConfig
+	CircuitBreaker CircuitBreakerConfig
+}
+
+// Tunables returns the tunables of the configuration.
+func (c *Config) Tunables() Tunables {
+	return Tunables{
+		LogLevel:       c.Logger.Level,
+		RateLimit:      c.RateLimit,
+		CircuitBreaker: c.CircuitBreaker,
+	}
+}
+
+// logLevels are the levels the logger accepts.
+var logLevels = map[string]bool{
+	"trace": true, "debug": true, "info": true, "warn": true,
+	"error": true, "fatal": true, "panic": true, "disabled": true,
+}
+
+// Validate checks that the tunables can be applied.
+func (t Tunables) Validate() error {
+	if !logLevels[strings.ToLower(t.LogLevel)] {
+		return fmt.Errorf("log_level must be one of trace, debug, info, warn, error, fatal, panic or disabled")
+	}
+	if t.RateLimit.Requests < 1 || t.RateLimit.Window <= 0 {
+		return fmt.Errorf("rate_limit needs at least one request per positive window")
+	}
+	cb := t.CircuitBreaker
+	if cb.FailureThreshold < 1 || cb.MaxRequests < 1 || cb.Interval <= 0 || cb.Timeout <= 0 {
+		return fmt.Errorf("circuit_breaker thresholds and durations must be positive")
+	}
+	return nil
+}
+
+// tunablesJSON is the JSON form of Tunables, with durations such as "30s".
+type tunablesJSON struct {
+	LogLevel  string `json:"log_level"`
+	RateLimit struct {
+		Requests int    `json:"requests"`
+		Window   string `json:"window"`
+	} `json:"rate_limit"`
+	CircuitBreaker struct {
+		FailureThreshold uint32 `json:"failure_threshold"`
+		MaxRequests      uint32 `json:"max_requests"`
+		Interval         string `json:"interval"`
+		Timeout          string `json:"timeout"`
+	} `json:"circuit_breaker"`
+}
+
+// MarshalJSON encodes the tunables with readable durations.
+func (t Tunables) MarshalJSON() ([]byte, error) {
+	var j tunablesJSON
+	j.LogLevel = t.LogLevel
+	j.RateLimit.Requests = t.RateLimit.Requests
+	j.RateLimit.Window = t.RateLimit.Window.String()
+	j.CircuitBreaker.FailureThreshold = t.CircuitBreaker.FailureThreshold
+	j.CircuitBreaker.MaxRequests = t.CircuitBreaker.MaxRequests
+	j.CircuitBreaker.Interval = t.CircuitBreaker.Interval.String()
+	j.CircuitBreaker.Timeout = t.CircuitBreaker.Timeout.String()
+	return json.Marshal(j)
+}
+
+// TunablesPatch changes some tunables; fields left out are kept. Durations
+// are written like "30s" or "2m".
+type TunablesPatch struct {
+	LogLevel       *string              `json:"log_level"`
+	RateLimit      *RateLimitPatch      `json:"rate_limit"`
+	CircuitBreaker *CircuitBreakerPatch `json:"circuit_breaker"`
+}
+
+// RateLimitPatch changes the rate limit.
+type RateLimitPatch struct {
+	Requests *int    `json:"requests"`
+	Window   *string `json:"window"`
+}
+
+// CircuitBreakerPatch changes the circuit breaker thresholds.
+type CircuitBreakerPatch struct {
+	FailureThreshold *uint32 `json:"failure_threshold"`
+	MaxRequests      *uint32 `json:"max_requests"`
+	Interval         *string `json:"interval"`
+	Timeout          *string `json:"timeout"`
+}
+
+// Apply returns t changed by the patch, or an error if the result is not
+// valid.
+func (p TunablesPatch) Apply(t Tunables) (Tunables, error) {
+	if p.LogLevel != nil {
+		t.LogLevel = *p.LogLevel
+	}
+	if rl := p.RateLimit; rl != nil {
+		if rl.Requests != nil {
+			t.RateLimit.Requests = *rl.Requests
+		}
+		if err := patchDuration(&t.RateLimit.Window, rl.Window, "rate_limit.window"); err != nil {
+			return t, err
+		}
+	}
+	if cb := p.CircuitBreaker; cb != nil {
+		if cb.FailureThreshold != nil {
+			t.CircuitBreaker.FailureThreshold = *cb.FailureThreshold
+		}
+		if cb.MaxRequests != nil {
+			t.CircuitBreaker.MaxRequests = *cb.MaxRequests
+		}
+		if err := patchDuration(&t.CircuitBreaker.Interval, cb.Interval, "circuit_breaker.interval"); err != nil {
+			return t, err
+		}
+		if err := patchDuration(&t.CircuitBreaker.Timeout, cb.Timeout, "circuit_breaker.timeout"); err != nil {
+			return t, err
+		}
+	}
+	return t, t.Validate()
+}
+
+func patchDuration(target *time.Duration, value *string, name string) error {
+	if value == nil {
+		return nil
+	}
+	d, err := time.ParseDuration(*value)
+	if err != nil {
+		return fmt.Errorf("%s must be a duration such as 30s or 2m", name)
+	}
+	*target = d
+	return nil
+}
+
+// ============================================================================
+// Runtime
+// ============================================================================
+
+// Tunables sources reported by Runtime.
+const (
+	TunablesFromStartup = "startup"
+	TunablesFromFile    = "file"
+	TunablesFromAdmin   = "admin"
+)
+
+// Runtime holds the current tunables of a service and notifies subscribers
+// when they change, because the config file changed or an operator patched
+// them. A patch lasts until the tunables in the file change or the service
+// restarts.
+type Runtime struct {
+	path string
+
+	mu          sync.Mutex
+	current     Tunables
+	fromFile    Tunables
+	source      string
+	updatedAt   time.Time
+	subscribers map[chan Tunables]struct{}
+}
+
+// NewRuntime creates a runtime holding the tunables of cfg.
+func NewRuntime(cfg *Config) *Runtime {
+	return &Runtime{
+		path:        cfg.file,
+		current:     cfg.Tunables(),
+		fromFile:    cfg.Tunables(),
+		source:      TunablesFromStartup,
+		updatedAt:   time.Now().UTC(),
+		subscribers: make(map[chan Tunables]struct{}),
+	}
+}
+
+// RuntimeStatus describes the current tunables.
+type RuntimeStatus struct {
+	Tunables  Tunables  `json:"tunables"`
+	Source    string    `json:"source"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// Status returns the current tunables and where they came from.
+func (r *Runtime) Status() RuntimeStatus {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return RuntimeStatus{Tunables: r.current, Source: r.source, UpdatedAt: r.updatedAt}
+}
+
+// Tunables returns the current tunables.
+func (r *Runtime) Tunables() Tunables {
+	return r.Status().Tunables
+}
+
+// Subscribe returns a channel receiving the tunables after every change
+// until ctx is done. A slow subscriber only gets the latest tunables.
+func (r *Runtime) Subscribe(ctx context.Context) <-chan Tunables {
+	ch := make(chan Tunables, 1)
+
+	r.mu.Lock()
+	r.subscribers[ch] = struct{}{}
+	r.mu.Unlock()
+
+	go func() {
+		<-ctx.Done()
+		r.mu.Lock()
+		delete(r.subscribers, ch)
+		close(ch)
+		r.mu.Unlock()
+	}()
+	return ch
+}
+
+// Update applies a patch and notifies the subscribers.
+func (r *Runtime) Update(patch TunablesPatch) (Tunables, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	updated, err := patch.Apply(r.current)
+	if err != nil {
+		return r.current, err
+	}
+	r.set(updated, TunablesFromAdmin)
+	return updated, nil
+}
+
+// Reload reads the tunables from the config file and the environment again.
+// They replace the current tunables only when they differ from those read
+// before, so unrelated edits to the file keep patches in place.
+func (r *Runtime) Reload() (bool, error) {
+	v, err := newViper(r.path)
+	if err != nil {
+		return false, err
+	}
+	var cfg Config
+	if err := v.Unmarshal(&cfg); err != nil {
+		return false, fmt.Errorf("failed to unmarshal config: %w", err)
+	}
+	tunables := cfg.Tunables()
+	if err := tunables.Validate(); err != nil {
+		return false, err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if tunables == r.fromFile {
+		return false, nil
+	}
+	r.fromFile = tunables
+	r.set(tunables, TunablesFromFile)
+	return true, nil
+}
+
+// set replaces the current tunables and notifies the subscribers; r.mu must
+// be held.
+func (r *Runtime) set(t Tunables, source string) {
+	r.current = t
+	r.source = source
+	r.updatedAt = time.Now().UTC()
+
+	for ch := range r.subscribers {
+		// Replace a value the subscriber has not taken yet
+		select {
+		case <-ch:
+		default:
+		}
+		ch <- t
+	}
+}
+
+// Watch reloads the tunables when the config file changes or the process
+// receives SIGHUP, until ctx is done. Failed reloads are passed to onError,
+// which may be nil, and keep the current tunables.
+func (r *Runtime) Watch(ctx context.Context, onError func(error)) {
+	reload := make(chan struct{}, 1)
+	trigger := func() {
+		select {
+		case reload <- struct{}{}:
+		default:
+		}
+	}
+
+	if r.path != "" {
+		watcher, err := fsnotify.NewWatcher()
+		if err == nil {
+			err = watcher.Add(r.path)
+		}
+		if err != nil {
+			if onError != nil {
+				onError(fmt.Errorf("failed to watch config file: %w", err))
+			}
+		} else {
+			go func() {
+				defer watcher.Close()
+				for {
+					select {
+					case <-ctx.Done():
+						return
+					case event := <-watcher.Events:
+						// Editors and config maps replace the file; watch the new one
+						if event.Has(fsnotify.Remove) || event.Has(fsnotify.Rename) {
+							_ = watcher.Add(r.path)
+						}
+						trigger()
+					case err := <-watcher.Errors:
+						if err != nil && onError != nil {
+							onError(err)
+						}
+					}
+				}
+			}()
+		}
+	}
+
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+
+	go func() {
+		defer signal.Stop(hup)
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-hup:
+			case <-reload:
+			}
+			if _, err := r.Reload(); err != nil && onError != nil {
+				onError(err)
+			}
+		}
+	}()
+}
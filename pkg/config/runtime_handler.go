@@ -0,0 +1,55 @@
+package config
+
+import (
+	"encoding/json"
+	"net/http"
+
+	apperrors "github.com/kilang-desa-murni/crm/pkg/errors"
+	"github.com/kilang-desa-murni/crm/pkg/response"
+)
+
+// RuntimeHandler serves the runtime configuration API, to be mounted behind
+// admin authentication:
+//
+//	GET   /admin/config   the configuration, with secrets redacted, and the current tunables
+//	PATCH /admin/config   change tunables, e.g. {"log_level": "debug", "rate_limit": {"requests": 200}}
+type RuntimeHandler struct {
+	cfg     *Config
+	runtime *Runtime
+}
+
+// NewRuntimeHandler creates the runtime configuration API of a service
+// started with cfg.
+func NewRuntimeHandler(cfg *Config, runtime *Runtime) *RuntimeHandler {
+	return &RuntimeHandler{cfg: cfg, runtime: runtime}
+}
+
+// runtimeResponse is the body of both endpoints; Config redacts its secrets
+// when encoded.
+type runtimeResponse struct {
+	Config *Config `json:"config"`
+	RuntimeStatus
+}
+
+// Get returns the configuration and the current tunables.
+func (h *RuntimeHandler) Get(w http.ResponseWriter, r *http.Request) {
+	response.OK(w, h.status())
+}
+
+// Patch changes the tunables and returns the result.
+func (h *RuntimeHandler) Patch(w http.ResponseWriter, r *http.Request) {
+	var patch TunablesPatch
+	if err := json.NewDecoder(r.Body).Decode(&patch); err != nil {
+		response.BadRequest(w, "invalid request body")
+		return
+	}
+	if _, err := h.runtime.Update(patch); err != nil {
+		response.Error(w, apperrors.ErrValidation(err.Error()))
+		return
+	}
+	response.OK(w, h.status())
+}
+
+func (h *RuntimeHandler) status() runtimeResponse {
+	return runtimeResponse{Config: h.cfg, RuntimeStatus: h.runtime.Status()}
+}
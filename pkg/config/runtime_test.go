@@ -0,0 +1,177 @@
+package config
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func writeConfigFile(t *testing.T, path, level string, requests int) {
+	t.Helper()
+	content := fmt.Sprintf("logger:\n  level: %s\nrate_limit:\n  requests: %d\n", level, requests)
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+}
+
+func loadTestConfig(t *testing.T, path string) *Config {
+	t.Helper()
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	return cfg
+}
+
+func TestTunablesPatch_Apply(t *testing.T) {
+	cfg := loadTestConfig(t, "")
+	base := cfg.Tunables()
+
+	level := "debug"
+	window := "30s"
+	requests := 250
+	got, err := TunablesPatch{
+		LogLevel:  &level,
+		RateLimit: &RateLimitPatch{Requests: &requests, Window: &window},
+	}.Apply(base)
+	if err != nil {
+		t.Fatalf("apply: %v", err)
+	}
+	if got.LogLevel != "debug" || got.RateLimit.Requests != 250 || got.RateLimit.Window != 30*time.Second {
+		t.Errorf("unexpected tunables %+v", got)
+	}
+	if got.CircuitBreaker != base.CircuitBreaker {
+		t.Error("expected fields left out of the patch to be kept")
+	}
+
+	invalid := []TunablesPatch{
+		{LogLevel: ptr("verbose")},
+		{RateLimit: &RateLimitPatch{Requests: ptr(0)}},
+		{RateLimit: &RateLimitPatch{Window: ptr("soon")}},
+		{CircuitBreaker: &CircuitBreakerPatch{FailureThreshold: ptr(uint32(0))}},
+	}
+	for _, patch := range invalid {
+		if _, err := patch.Apply(base); err == nil {
+			t.Errorf("expected %+v to be refused", patch)
+		}
+	}
+}
+
+func ptr[T any](v T) *T { return &v }
+
+func TestRuntime_Reload(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	writeConfigFile(t, path, "info", 100)
+	runtime := NewRuntime(loadTestConfig(t, path))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	updates := runtime.Subscribe(ctx)
+
+	if changed, err := runtime.Reload(); err != nil || changed {
+		t.Fatalf("expected an unchanged file to be ignored, got %v %v", changed, err)
+	}
+
+	// A patch survives edits to the file that leave the tunables alone
+	if _, err := runtime.Update(TunablesPatch{LogLevel: ptr("debug")}); err != nil {
+		t.Fatalf("update: %v", err)
+	}
+	if got := <-updates; got.LogLevel != "debug" {
+		t.Errorf("expected the patch to be published, got %q", got.LogLevel)
+	}
+	if changed, _ := runtime.Reload(); changed || runtime.Tunables().LogLevel != "debug" {
+		t.Error("expected the patch to be kept")
+	}
+
+	writeConfigFile(t, path, "warn", 500)
+	if changed, err := runtime.Reload(); err != nil || !changed {
+		t.Fatalf("expected the new tunables to be loaded, got %v %v", changed, err)
+	}
+	got := <-updates
+	if got.LogLevel != "warn" || got.RateLimit.Requests != 500 {
+		t.Errorf("unexpected tunables %+v", got)
+	}
+	if status := runtime.Status(); status.Source != TunablesFromFile {
+		t.Errorf("expected the file as source, got %q", status.Source)
+	}
+
+	// Invalid files keep the current tunables
+	writeConfigFile(t, path, "loud", 500)
+	if _, err := runtime.Reload(); err == nil {
+		t.Error("expected an invalid log level to be refused")
+	}
+	if runtime.Tunables().LogLevel != "warn" {
+		t.Error("expected the current tunables to be kept")
+	}
+}
+
+func TestRuntime_Watch(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	writeConfigFile(t, path, "info", 100)
+	runtime := NewRuntime(loadTestConfig(t, path))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	updates := runtime.Subscribe(ctx)
+	runtime.Watch(ctx, nil)
+
+	writeConfigFile(t, path, "error", 100)
+	select {
+	case got := <-updates:
+		if got.LogLevel != "error" {
+			t.Errorf("expected the new level, got %q", got.LogLevel)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected the file change to be picked up")
+	}
+}
+
+func TestRuntimeHandler(t *testing.T) {
+	t.Setenv("DB_PASSWORD", "hunter2")
+	cfg := loadTestConfig(t, "")
+	handler := NewRuntimeHandler(cfg, NewRuntime(cfg))
+
+	rec := httptest.NewRecorder()
+	handler.Patch(rec, httptest.NewRequest(http.MethodPatch, "/admin/config",
+		strings.NewReader(`{"log_level":"debug","circuit_breaker":{"timeout":"1m"}}`)))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	rec = httptest.NewRecorder()
+	handler.Get(rec, httptest.NewRequest(http.MethodGet, "/admin/config", nil))
+	body := rec.Body.String()
+	if strings.Contains(body, "hunter2") {
+		t.Error("expected secrets to be redacted")
+	}
+	var resp struct {
+		Data struct {
+			Tunables struct {
+				LogLevel       string `json:"log_level"`
+				CircuitBreaker struct {
+					Timeout string `json:"timeout"`
+				} `json:"circuit_breaker"`
+			} `json:"tunables"`
+			Source string `json:"source"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if resp.Data.Tunables.LogLevel != "debug" || resp.Data.Tunables.CircuitBreaker.Timeout != "1m0s" || resp.Data.Source != TunablesFromAdmin {
+		t.Errorf("unexpected response %s", body)
+	}
+
+	rec = httptest.NewRecorder()
+	handler.Patch(rec, httptest.NewRequest(http.MethodPatch, "/admin/config", strings.NewReader(`{"rate_limit":{"requests":-1}}`)))
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("expected 400, got %d", rec.Code)
+	}
+}
@@ -0,0 +1,616 @@
+package config
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Secret reference schemes. A configuration value written as a reference is
+// replaced by the secret it points to when the configuration is loaded:
+//
+//	vault://secret/data/crm/database#password
+//	awssm://crm/production/database#password
+//
+// The part after "#" selects a field of the secret. It may be left out when a
+// Vault secret has a single field, or to use a whole AWS secret string.
+const (
+	SecretSchemeVault = "vault"
+	SecretSchemeAWSSM = "awssm"
+)
+
+// redactedValue replaces secrets in configuration dumps.
+const redactedValue = "[REDACTED]"
+
+// SecretsConfig holds the settings of the secret stores configuration values
+// may refer to.
+type SecretsConfig struct {
+	// CacheTTL is how long a resolved secret is reused before it is fetched
+	// again.
+	CacheTTL time.Duration `mapstructure:"cache_ttl"`
+	// RefreshInterval is how often resolved secrets are fetched again to pick
+	// up rotated values. Zero disables the refresh.
+	RefreshInterval time.Duration `mapstructure:"refresh_interval"`
+	// Timeout bounds each request to a secret store.
+	Timeout time.Duration `mapstructure:"timeout"`
+
+	Vault VaultConfig      `mapstructure:"vault"`
+	AWS   AWSSecretsConfig `mapstructure:"aws"`
+}
+
+// VaultConfig holds HashiCorp Vault configuration.
+type VaultConfig struct {
+	Address   string `mapstructure:"address"`
+	Token     string `mapstructure:"token" secret:"true"`
+	Namespace string `mapstructure:"namespace"`
+}
+
+// AWSSecretsConfig holds AWS Secrets Manager configuration.
+type AWSSecretsConfig struct {
+	Region          string `mapstructure:"region"`
+	AccessKeyID     string `mapstructure:"access_key_id"`
+	SecretAccessKey string `mapstructure:"secret_access_key" secret:"true"`
+	SessionToken    string `mapstructure:"session_token" secret:"true"`
+	// Endpoint overrides the regional Secrets Manager endpoint.
+	Endpoint string `mapstructure:"endpoint"`
+}
+
+// SecretRef is a parsed secret reference.
+type SecretRef struct {
+	Scheme string
+	Path   string
+	Key    string
+}
+
+// String returns the reference as written in the configuration.
+func (r SecretRef) String() string {
+	if r.Key == "" {
+		return r.Scheme + "://" + r.Path
+	}
+	return r.Scheme + "://" + r.Path + "#" + r.Key
+}
+
+// ParseSecretRef parses a secret reference. It reports false for values that
+// are not references to a known secret store.
+func ParseSecretRef(value string) (SecretRef, bool) {
+	scheme, rest, ok := strings.Cut(value, "://")
+	if !ok || (scheme != SecretSchemeVault && scheme != SecretSchemeAWSSM) {
+		return SecretRef{}, false
+	}
+	path, key, _ := strings.Cut(rest, "#")
+	if path == "" {
+		return SecretRef{}, false
+	}
+	return SecretRef{Scheme: scheme, Path: path, Key: key}, true
+}
+
+// IsSecretRef reports whether value is a secret reference.
+func IsSecretRef(value string) bool {
+	_, ok := ParseSecretRef(value)
+	return ok
+}
+
+// SecretProvider fetches secrets from a secret store.
+type SecretProvider interface {
+	// FetchSecret returns the value of the referenced secret.
+	FetchSecret(ctx context.Context, ref SecretRef) (string, error)
+}
+
+// ============================================================================
+// Secret Resolver
+// ============================================================================
+
+type cachedSecret struct {
+	value     string
+	fetchedAt time.Time
+}
+
+// SecretResolver resolves secret references with the provider of their
+// scheme and caches the resolved values.
+type SecretResolver struct {
+	providers map[string]SecretProvider
+	ttl       time.Duration
+
+	mu    sync.RWMutex
+	cache map[string]cachedSecret
+}
+
+// NewSecretResolver creates a resolver using the Vault and AWS Secrets Manager
+// providers configured in cfg. A store without an address or region is not
+// registered and references to it fail to resolve.
+func NewSecretResolver(cfg SecretsConfig) *SecretResolver {
+	r := &SecretResolver{
+		providers: make(map[string]SecretProvider),
+		ttl:       cfg.CacheTTL,
+		cache:     make(map[string]cachedSecret),
+	}
+
+	client := &http.Client{Timeout: cfg.Timeout}
+	if cfg.Vault.Address != "" {
+		r.Register(SecretSchemeVault, NewVaultProvider(cfg.Vault, client))
+	}
+	if cfg.AWS.Region != "" {
+		r.Register(SecretSchemeAWSSM, NewAWSSecretsManagerProvider(cfg.AWS, client))
+	}
+	return r
+}
+
+// Register sets the provider of a reference scheme.
+func (r *SecretResolver) Register(scheme string, provider SecretProvider) {
+	r.providers[scheme] = provider
+}
+
+// Resolve returns the secret a reference points to. Values that are not
+// references are returned unchanged.
+func (r *SecretResolver) Resolve(ctx context.Context, value string) (string, error) {
+	ref, ok := ParseSecretRef(value)
+	if !ok {
+		return value, nil
+	}
+
+	r.mu.RLock()
+	cached, ok := r.cache[ref.String()]
+	r.mu.RUnlock()
+	if ok && (r.ttl <= 0 || time.Since(cached.fetchedAt) < r.ttl) {
+		return cached.value, nil
+	}
+
+	return r.fetch(ctx, ref)
+}
+
+func (r *SecretResolver) fetch(ctx context.Context, ref SecretRef) (string, error) {
+	provider, ok := r.providers[ref.Scheme]
+	if !ok {
+		return "", fmt.Errorf("no secret store configured for %s references", ref.Scheme)
+	}
+
+	secret, err := provider.FetchSecret(ctx, ref)
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve secret %s: %w", ref, err)
+	}
+
+	r.mu.Lock()
+	r.cache[ref.String()] = cachedSecret{value: secret, fetchedAt: time.Now()}
+	r.mu.Unlock()
+	return secret, nil
+}
+
+// Refresh fetches every cached secret again and reports whether any value
+// changed. A secret that cannot be fetched keeps its cached value.
+func (r *SecretResolver) Refresh(ctx context.Context) (bool, error) {
+	r.mu.RLock()
+	refs := make([]string, 0, len(r.cache))
+	previous := make(map[string]string, len(r.cache))
+	for ref, cached := range r.cache {
+		refs = append(refs, ref)
+		previous[ref] = cached.value
+	}
+	r.mu.RUnlock()
+
+	var (
+		changed bool
+		errs    []error
+	)
+	for _, value := range refs {
+		ref, _ := ParseSecretRef(value)
+		secret, err := r.fetch(ctx, ref)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if secret != previous[value] {
+			changed = true
+		}
+	}
+	return changed, errors.Join(errs...)
+}
+
+// ============================================================================
+// Configuration Resolution
+// ============================================================================
+
+// resolveSecrets replaces the secret references in cfg with their values.
+// The secret store settings themselves are not resolved.
+func resolveSecrets(ctx context.Context, cfg *Config, resolver *SecretResolver) error {
+	v := reflect.ValueOf(cfg).Elem()
+	for i := 0; i < v.NumField(); i++ {
+		field := v.Type().Field(i)
+		if !field.IsExported() || field.Name == "Secrets" {
+			continue
+		}
+		if err := resolveValue(ctx, v.Field(i), resolver); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func resolveValue(ctx context.Context, v reflect.Value, resolver *SecretResolver) error {
+	switch v.Kind() {
+	case reflect.String:
+		if !IsSecretRef(v.String()) {
+			return nil
+		}
+		secret, err := resolver.Resolve(ctx, v.String())
+		if err != nil {
+			return err
+		}
+		v.SetString(secret)
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			if v.Type().Field(i).IsExported() {
+				if err := resolveValue(ctx, v.Field(i), resolver); err != nil {
+					return err
+				}
+			}
+		}
+	case reflect.Slice:
+		if v.IsNil() {
+			return nil
+		}
+		// Resolve into a copy so the references the slice shares with the
+		// unresolved configuration are kept for the next refresh
+		resolved := reflect.MakeSlice(v.Type(), v.Len(), v.Len())
+		reflect.Copy(resolved, v)
+		for i := 0; i < resolved.Len(); i++ {
+			if err := resolveValue(ctx, resolved.Index(i), resolver); err != nil {
+				return err
+			}
+		}
+		v.Set(resolved)
+	}
+	return nil
+}
+
+// WatchSecrets refreshes the configuration's secrets every refresh interval
+// until ctx is cancelled. When a secret has been rotated, onChange is called
+// with a copy of the configuration resolved with the new values. Failed
+// refreshes are passed to onError, which may be nil.
+func (c *Config) WatchSecrets(ctx context.Context, onChange func(*Config), onError func(error)) {
+	if c.secrets == nil || c.raw == nil || c.Secrets.RefreshInterval <= 0 {
+		return
+	}
+
+	go func() {
+		ticker := time.NewTicker(c.Secrets.RefreshInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+
+			changed, err := c.secrets.Refresh(ctx)
+			if err != nil && onError != nil {
+				onError(err)
+			}
+			if !changed {
+				continue
+			}
+
+			updated := *c.raw
+			if err := resolveSecrets(ctx, &updated, c.secrets); err != nil {
+				if onError != nil {
+					onError(err)
+				}
+				continue
+			}
+			updated.secrets = c.secrets
+			updated.raw = c.raw
+			onChange(&updated)
+		}
+	}()
+}
+
+// ============================================================================
+// Redaction
+// ============================================================================
+
+// Redacted returns a copy of the configuration with its secrets replaced, for
+// dumps and logs. Fields tagged secret:"true" are replaced entirely and fields
+// tagged secret:"url" keep everything but the password.
+func (c Config) Redacted() Config {
+	v := reflect.ValueOf(&c).Elem()
+	redactValue(v, "")
+	c.secrets = nil
+	c.raw = nil
+	return c
+}
+
+func redactValue(v reflect.Value, tag string) {
+	switch v.Kind() {
+	case reflect.String:
+		if v.String() == "" {
+			return
+		}
+		switch tag {
+		case "true":
+			v.SetString(redactedValue)
+		case "url":
+			if u, err := url.Parse(v.String()); err == nil {
+				v.SetString(u.Redacted())
+			} else {
+				v.SetString(redactedValue)
+			}
+		}
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			field := v.Type().Field(i)
+			if field.IsExported() {
+				redactValue(v.Field(i), field.Tag.Get("secret"))
+			}
+		}
+	case reflect.Slice:
+		if v.IsNil() {
+			return
+		}
+		redacted := reflect.MakeSlice(v.Type(), v.Len(), v.Len())
+		reflect.Copy(redacted, v)
+		for i := 0; i < redacted.Len(); i++ {
+			redactValue(redacted.Index(i), tag)
+		}
+		v.Set(redacted)
+	}
+}
+
+// redactedConfig has Config's fields without its methods, so formatting it
+// does not call String again.
+type redactedConfig Config
+
+// String returns the configuration with its secrets redacted.
+func (c Config) String() string {
+	return fmt.Sprintf("%+v", redactedConfig(c.Redacted()))
+}
+
+// GoString returns the configuration with its secrets redacted.
+func (c Config) GoString() string {
+	return fmt.Sprintf("%#v", redactedConfig(c.Redacted()))
+}
+
+// MarshalJSON encodes the configuration with its secrets redacted.
+func (c Config) MarshalJSON() ([]byte, error) {
+	return json.Marshal(redactedConfig(c.Redacted()))
+}
+
+// ============================================================================
+// Vault Provider
+// ============================================================================
+
+// VaultProvider reads secrets from the HashiCorp Vault HTTP API. Version 1
+// and version 2 KV secrets engines are supported; the reference path is the
+// API path, such as secret/data/crm/database for a version 2 engine.
+type VaultProvider struct {
+	config VaultConfig
+	client *http.Client
+}
+
+// NewVaultProvider creates a new Vault provider.
+func NewVaultProvider(cfg VaultConfig, client *http.Client) *VaultProvider {
+	return &VaultProvider{config: cfg, client: client}
+}
+
+// FetchSecret reads the referenced field of a Vault secret.
+func (p *VaultProvider) FetchSecret(ctx context.Context, ref SecretRef) (string, error) {
+	endpoint := strings.TrimRight(p.config.Address, "/") + "/v1/" + strings.TrimLeft(ref.Path, "/")
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("X-Vault-Token", p.config.Token)
+	if p.config.Namespace != "" {
+		req.Header.Set("X-Vault-Namespace", p.config.Namespace)
+	}
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("vault returned status %d", resp.StatusCode)
+	}
+
+	var body struct {
+		Data map[string]interface{} `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return "", fmt.Errorf("invalid vault response: %w", err)
+	}
+
+	// Version 2 engines nest the fields under data.data, next to the version
+	// metadata
+	fields := body.Data
+	if nested, ok := fields["data"].(map[string]interface{}); ok {
+		if _, ok := fields["metadata"]; ok {
+			fields = nested
+		}
+	}
+
+	return secretField(fields, ref.Key)
+}
+
+// secretField returns a field of a secret. Without a key, the secret must
+// have exactly one field.
+func secretField(fields map[string]interface{}, key string) (string, error) {
+	if key == "" {
+		if len(fields) != 1 {
+			return "", fmt.Errorf("secret has %d fields, the reference must name one", len(fields))
+		}
+		for _, value := range fields {
+			return fmt.Sprint(value), nil
+		}
+	}
+
+	value, ok := fields[key]
+	if !ok {
+		return "", fmt.Errorf("secret has no field %q", key)
+	}
+	return fmt.Sprint(value), nil
+}
+
+// ============================================================================
+// AWS Secrets Manager Provider
+// ============================================================================
+
+const awsSecretsManagerService = "secretsmanager"
+
+// AWSSecretsManagerProvider reads secrets from AWS Secrets Manager. The
+// reference path is the secret name or ARN.
+type AWSSecretsManagerProvider struct {
+	config AWSSecretsConfig
+	client *http.Client
+	now    func() time.Time
+}
+
+// NewAWSSecretsManagerProvider creates a new AWS Secrets Manager provider.
+func NewAWSSecretsManagerProvider(cfg AWSSecretsConfig, client *http.Client) *AWSSecretsManagerProvider {
+	return &AWSSecretsManagerProvider{config: cfg, client: client, now: time.Now}
+}
+
+// FetchSecret reads the current version of a secret. With a key, the secret
+// string is decoded as a JSON object and the key's field is returned.
+func (p *AWSSecretsManagerProvider) FetchSecret(ctx context.Context, ref SecretRef) (string, error) {
+	payload, err := json.Marshal(map[string]string{"SecretId": ref.Path})
+	if err != nil {
+		return "", err
+	}
+
+	endpoint := p.config.Endpoint
+	if endpoint == "" {
+		endpoint = fmt.Sprintf("https://%s.%s.amazonaws.com/", awsSecretsManagerService, p.config.Region)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(payload))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/x-amz-json-1.1")
+	req.Header.Set("X-Amz-Target", "secretsmanager.GetSecretValue")
+	p.sign(req, payload, p.now().UTC())
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", err
+	}
+	if resp.StatusCode != http.StatusOK {
+		var failure struct {
+			Type    string `json:"__type"`
+			Message string `json:"message"`
+		}
+		_ = json.Unmarshal(body, &failure)
+		return "", fmt.Errorf("secrets manager returned status %d: %s %s", resp.StatusCode, failure.Type, failure.Message)
+	}
+
+	var result struct {
+		SecretString string `json:"SecretString"`
+		SecretBinary string `json:"SecretBinary"`
+	}
+	if err := json.Unmarshal(body, &result); err != nil {
+		return "", fmt.Errorf("invalid secrets manager response: %w", err)
+	}
+
+	secret := result.SecretString
+	if secret == "" && result.SecretBinary != "" {
+		decoded, err := base64.StdEncoding.DecodeString(result.SecretBinary)
+		if err != nil {
+			return "", fmt.Errorf("invalid binary secret: %w", err)
+		}
+		secret = string(decoded)
+	}
+
+	if ref.Key == "" {
+		return secret, nil
+	}
+	var fields map[string]interface{}
+	if err := json.Unmarshal([]byte(secret), &fields); err != nil {
+		return "", fmt.Errorf("secret is not a JSON object: %w", err)
+	}
+	return secretField(fields, ref.Key)
+}
+
+// sign adds an AWS Signature Version 4 authorization to the request.
+func (p *AWSSecretsManagerProvider) sign(req *http.Request, payload []byte, now time.Time) {
+	amzDate := now.Format("20060102T150405Z")
+	date := amzDate[:8]
+
+	req.Header.Set("X-Amz-Date", amzDate)
+	if p.config.SessionToken != "" {
+		req.Header.Set("X-Amz-Security-Token", p.config.SessionToken)
+	}
+
+	headers := map[string]string{"host": req.URL.Host}
+	for name := range req.Header {
+		headers[strings.ToLower(name)] = strings.TrimSpace(req.Header.Get(name))
+	}
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var canonicalHeaders strings.Builder
+	for _, name := range names {
+		canonicalHeaders.WriteString(name + ":" + headers[name] + "\n")
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	path := req.URL.EscapedPath()
+	if path == "" {
+		path = "/"
+	}
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		path,
+		req.URL.RawQuery,
+		canonicalHeaders.String(),
+		signedHeaders,
+		hashHex(payload),
+	}, "\n")
+
+	scope := date + "/" + p.config.Region + "/" + awsSecretsManagerService + "/aws4_request"
+	stringToSign := "AWS4-HMAC-SHA256\n" + amzDate + "\n" + scope + "\n" + hashHex([]byte(canonicalRequest))
+
+	key := hmacSHA256([]byte("AWS4"+p.config.SecretAccessKey), date)
+	key = hmacSHA256(key, p.config.Region)
+	key = hmacSHA256(key, awsSecretsManagerService)
+	key = hmacSHA256(key, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf(
+		"AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		p.config.AccessKeyID, scope, signedHeaders, signature,
+	))
+}
+
+func hashHex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
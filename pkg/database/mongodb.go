@@ -3,9 +3,11 @@ package database
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"time"
 
+	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
 	"go.mongodb.org/mongo-driver/mongo/readpref"
@@ -14,12 +16,25 @@ import (
 	"github.com/kilang-desa-murni/crm/pkg/logger"
 )
 
+// Error labels the server attaches to errors a transaction can be retried
+// after.
+const (
+	// labelTransientTransactionError marks a transaction that failed as a
+	// whole, e.g. on a write conflict or a primary election, and can be run
+	// again from the start.
+	labelTransientTransactionError = "TransientTransactionError"
+	// labelUnknownTransactionCommitResult marks a commit whose outcome is
+	// unknown; committing again is safe.
+	labelUnknownTransactionCommitResult = "UnknownTransactionCommitResult"
+)
+
 // MongoDB wraps the mongo.Client and provides database operations.
 type MongoDB struct {
-	client   *mongo.Client
-	database *mongo.Database
-	config   *config.MongoDBConfig
-	log      *logger.Logger
+	client       *mongo.Client
+	database     *mongo.Database
+	config       *config.MongoDBConfig
+	log          *logger.Logger
+	transactions bool
 }
 
 // NewMongoDB creates a new MongoDB connection.
@@ -42,21 +57,61 @@ func NewMongoDB(cfg *config.MongoDBConfig, log *logger.Logger) (*MongoDB, error)
 
 	// Verify connection
 	if err := client.Ping(ctx, readpref.Primary()); err != nil {
+		client.Disconnect(context.Background())
 		return nil, fmt.Errorf("failed to ping MongoDB: %w", err)
 	}
 
+	transactions, err := SupportsTransactions(ctx, client)
+	if err != nil {
+		log.Warn().Err(err).Msg("Failed to detect MongoDB topology, assuming a standalone server")
+	} else if !transactions {
+		log.Warn().Msg("MongoDB is a standalone server, multi-document writes will not be transactional")
+	}
+
 	log.Info().
 		Str("database", cfg.Database).
+		Bool("transactions", transactions).
 		Msg("Connected to MongoDB")
 
 	return &MongoDB{
-		client:   client,
-		database: client.Database(cfg.Database),
-		config:   cfg,
-		log:      log,
+		client:       client,
+		database:     client.Database(cfg.Database),
+		config:       cfg,
+		log:          log,
+		transactions: transactions,
 	}, nil
 }
 
+// SupportsTransactions reports whether client is connected to a replica set
+// or a sharded cluster. Standalone servers reject transactions.
+func SupportsTransactions(ctx context.Context, client *mongo.Client) (bool, error) {
+	var hello struct {
+		SetName string `bson:"setName"`
+		Msg     string `bson:"msg"`
+	}
+	if err := client.Database("admin").RunCommand(ctx, bson.D{{Key: "hello", Value: 1}}).Decode(&hello); err != nil {
+		return false, fmt.Errorf("failed to query MongoDB topology: %w", err)
+	}
+	return hello.SetName != "" || hello.Msg == "isdbgrid", nil
+}
+
+// IsTransientTransactionError reports whether a transaction failed for a
+// reason that running it again may fix.
+func IsTransientTransactionError(err error) bool {
+	return hasErrorLabel(err, labelTransientTransactionError)
+}
+
+// IsUnknownCommitResultError reports whether the outcome of a commit is
+// unknown, in which case the commit may be retried.
+func IsUnknownCommitResultError(err error) bool {
+	return hasErrorLabel(err, labelUnknownTransactionCommitResult)
+}
+
+func hasErrorLabel(err error, label string) bool {
+	var labeled mongo.LabeledError
+	return errors.As(err, &labeled) && labeled.HasErrorLabel(label)
+}
+
 // Close closes the MongoDB connection.
 func (m *MongoDB) Close(ctx context.Context) error {
 	m.log.Info().Msg("Closing MongoDB connection")
@@ -83,8 +138,20 @@ func (m *MongoDB) Collection(name string) *mongo.Collection {
 	return m.database.Collection(name)
 }
 
-// Transaction executes a function within a MongoDB transaction.
+// SupportsTransactions reports whether the server accepts transactions.
+func (m *MongoDB) SupportsTransactions() bool {
+	return m.transactions
+}
+
+// Transaction executes a function within a MongoDB transaction. The driver
+// runs fn again when the transaction fails with a transient error and
+// retries a commit whose result is unknown. On a standalone server fn runs
+// in a session without a transaction.
 func (m *MongoDB) Transaction(ctx context.Context, fn func(sessCtx mongo.SessionContext) error) error {
+	if !m.transactions {
+		return m.WithSession(ctx, fn)
+	}
+
 	session, err := m.client.StartSession()
 	if err != nil {
 		return fmt.Errorf("failed to start session: %w", err)
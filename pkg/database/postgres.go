@@ -38,6 +38,7 @@ func NewPostgres(cfg *config.DatabaseConfig, log *logger.Logger) (*PostgresDB, e
 	defer cancel()
 
 	if err := db.PingContext(ctx); err != nil {
+		db.Close()
 		return nil, fmt.Errorf("failed to ping database: %w", err)
 	}
 
@@ -38,6 +38,7 @@ func NewRedis(cfg *config.RedisConfig, log *logger.Logger) (*RedisClient, error)
 	defer cancel()
 
 	if err := client.Ping(ctx).Err(); err != nil {
+		client.Close()
 		return nil, fmt.Errorf("failed to ping Redis: %w", err)
 	}
 
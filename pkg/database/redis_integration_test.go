@@ -4,6 +4,7 @@ package database
 import (
 	"context"
 	"encoding/json"
+	"flag"
 	"os"
 	"sync"
 	"testing"
@@ -22,7 +23,8 @@ var (
 
 // TestMain sets up and tears down the Redis container.
 func TestMain(m *testing.M) {
-	// Skip if in short mode
+	// Skip if in short mode. Flags are not parsed before TestMain runs.
+	flag.Parse()
 	if testing.Short() {
 		os.Exit(0)
 	}
@@ -0,0 +1,224 @@
+// Package demodata provides the demo data set seeded into a tenant for
+// onboarding demos. Every service seeds its own part of the same data set:
+// the customer service the customers and their contacts, the sales service
+// the pipeline, leads and opportunities, and the notification service the
+// notifications. Record IDs are derived from the tenant ID, so the services
+// agree on them without talking to each other, seeding again creates only
+// the records that are missing, and a purge removes the seeded records only.
+//
+// Contact details use example.com addresses and unallocated phone numbers,
+// so nothing is ever delivered to a real person.
+package demodata
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Record kinds, used to derive record IDs.
+const (
+	KindCustomer     = "customer"
+	KindContact      = "contact"
+	KindLead         = "lead"
+	KindPipeline     = "pipeline"
+	KindStage        = "stage"
+	KindOpportunity  = "opportunity"
+	KindNotification = "notification"
+)
+
+// Currency is the currency of the demo amounts.
+const Currency = "MYR"
+
+// ID returns the ID of the n-th demo record of a kind in the tenant.
+func ID(tenantID uuid.UUID, kind string, n int) uuid.UUID {
+	return uuid.NewSHA1(tenantID, []byte(fmt.Sprintf("demo/%s/%d", kind, n)))
+}
+
+// Customer is a demo customer company with its primary contact.
+type Customer struct {
+	ID          uuid.UUID
+	Code        string
+	Name        string
+	Email       string
+	Phone       string
+	Website     string
+	Street      string
+	City        string
+	State       string
+	PostalCode  string
+	Industry    string
+	Tags        []string
+	ContactID   uuid.UUID
+	FirstName   string
+	LastName    string
+	JobTitle    string
+	ContactMail string
+}
+
+// Lead is a demo lead that has not become a customer yet.
+type Lead struct {
+	ID        uuid.UUID
+	FirstName string
+	LastName  string
+	Email     string
+	Phone     string
+	Company   string
+	JobTitle  string
+	City      string
+	Source    string
+}
+
+// Pipeline is the demo sales pipeline. Its stages are the default stages of
+// a new pipeline, with IDs derived like the other records from the stage's
+// order.
+type Pipeline struct {
+	ID   uuid.UUID
+	Name string
+}
+
+// Opportunity is a demo opportunity with one of the demo customers.
+type Opportunity struct {
+	ID         uuid.UUID
+	Name       string
+	CustomerID uuid.UUID
+	Customer   string
+	// Amount is in sen.
+	Amount int64
+	// Stage is the order of the pipeline stage the opportunity is in.
+	Stage int
+	// CloseIn is how long from seeding the opportunity is expected to close.
+	CloseIn time.Duration
+}
+
+// Notification is a demo email sent to the contact of a demo customer.
+type Notification struct {
+	ID             uuid.UUID
+	CustomerID     uuid.UUID
+	RecipientName  string
+	RecipientEmail string
+	Subject        string
+	Body           string
+}
+
+// DataSet is the demo data set of a tenant.
+type DataSet struct {
+	TenantID      uuid.UUID
+	Customers     []Customer
+	Leads         []Lead
+	Pipeline      Pipeline
+	Opportunities []Opportunity
+	Notifications []Notification
+}
+
+// customerFixtures are the demo customers, in the order their IDs are
+// derived in.
+var customerFixtures = []Customer{
+	{Code: "DEMO-001", Name: "Butik Seri Kenanga Sdn Bhd", Street: "12 Jalan Tuanku Abdul Rahman", City: "Kuala Lumpur", State: "Kuala Lumpur", PostalCode: "50100", Industry: "retail", Tags: []string{"retail", "wholesale"}, FirstName: "Aminah", LastName: "Yusof", JobTitle: "Purchasing Manager"},
+	{Code: "DEMO-002", Name: "Galeri Warisan Pulau Pinang", Street: "8 Lebuh Armenian", City: "George Town", State: "Pulau Pinang", PostalCode: "10200", Industry: "media", Tags: []string{"gallery"}, FirstName: "Lim", LastName: "Mei Ling", JobTitle: "Curator"},
+	{Code: "DEMO-003", Name: "Hotel Puteri Terengganu", Street: "3 Jalan Sultan Zainal Abidin", City: "Kuala Terengganu", State: "Terengganu", PostalCode: "20000", Industry: "hospitality", Tags: []string{"hospitality", "corporate"}, FirstName: "Faizal", LastName: "Ismail", JobTitle: "Operations Director"},
+	{Code: "DEMO-004", Name: "Kraf Nusantara Trading", Street: "21 Jalan Dato Onn", City: "Johor Bahru", State: "Johor", PostalCode: "80000", Industry: "manufacturing", Tags: []string{"export"}, FirstName: "Rajesh", LastName: "Kumar", JobTitle: "Managing Director"},
+	{Code: "DEMO-005", Name: "Sekolah Seni Kreatif Melaka", Street: "5 Jalan Hang Jebat", City: "Melaka", State: "Melaka", PostalCode: "75200", Industry: "education", Tags: []string{"education"}, FirstName: "Nurul", LastName: "Huda", JobTitle: "Principal"},
+	{Code: "DEMO-006", Name: "Rumah Fesyen Kota Bharu", Street: "17 Jalan Temenggong", City: "Kota Bharu", State: "Kelantan", PostalCode: "15000", Industry: "retail", Tags: []string{"designer", "wholesale"}, FirstName: "Hafiz", LastName: "Abdullah", JobTitle: "Creative Director"},
+}
+
+// leadFixtures are the demo leads.
+var leadFixtures = []Lead{
+	{FirstName: "Siti", LastName: "Rahman", Company: "Boutique Anggun", JobTitle: "Owner", City: "Shah Alam", Source: "website"},
+	{FirstName: "Tan", LastName: "Wei Jie", Company: "Corporate Gifts Asia", JobTitle: "Procurement Lead", City: "Petaling Jaya", Source: "referral"},
+	{FirstName: "Azlan", LastName: "Hassan", Company: "Resort Langkawi Indah", JobTitle: "General Manager", City: "Langkawi", Source: "trade_show"},
+	{FirstName: "Priya", LastName: "Nair", Company: "Wedding Atelier KL", JobTitle: "Stylist", City: "Kuala Lumpur", Source: "social_media"},
+	{FirstName: "Khairul", LastName: "Anuar", Company: "Muzium Tekstil Kuching", JobTitle: "Collections Officer", City: "Kuching", Source: "email"},
+	{FirstName: "Wong", LastName: "Siew Lan", Company: "Airline Uniform Supplies", JobTitle: "Buyer", City: "Sepang", Source: "cold_call"},
+}
+
+// opportunityFixtures are the demo opportunities, with the index of their
+// customer in customerFixtures.
+var opportunityFixtures = []struct {
+	Name     string
+	Customer int
+	Amount   int64
+	Stage    int
+	CloseIn  time.Duration
+}{
+	{Name: "Hari Raya collection restock", Customer: 0, Amount: 4_500_000, Stage: 4, CloseIn: 14 * 24 * time.Hour},
+	{Name: "Heritage exhibition pieces", Customer: 1, Amount: 1_800_000, Stage: 2, CloseIn: 45 * 24 * time.Hour},
+	{Name: "Staff uniforms for 120 employees", Customer: 2, Amount: 9_600_000, Stage: 5, CloseIn: 7 * 24 * time.Hour},
+	{Name: "Export order to Singapore", Customer: 3, Amount: 12_000_000, Stage: 3, CloseIn: 30 * 24 * time.Hour},
+	{Name: "Batik workshop materials", Customer: 4, Amount: 650_000, Stage: 1, CloseIn: 60 * 24 * time.Hour},
+	{Name: "Designer silk batik range", Customer: 5, Amount: 7_200_000, Stage: 4, CloseIn: 21 * 24 * time.Hour},
+	{Name: "Lobby wall hangings", Customer: 2, Amount: 2_400_000, Stage: 2, CloseIn: 40 * 24 * time.Hour},
+}
+
+// notificationFixtures are the demo notifications, with the index of the
+// customer whose contact they were sent to.
+var notificationFixtures = []struct {
+	Customer int
+	Subject  string
+	Body     string
+}{
+	{Customer: 0, Subject: "Your Hari Raya catalogue", Body: "Thank you for your interest. Our Hari Raya catalogue is attached, with wholesale prices for orders of 50 pieces or more."},
+	{Customer: 1, Subject: "Exhibition pieces shortlist", Body: "As discussed, we have shortlisted twelve heritage pieces for your exhibition. We would be glad to arrange a viewing."},
+	{Customer: 2, Subject: "Uniform sizing appointment", Body: "This confirms our sizing appointment for your staff next Tuesday at 10am at the hotel."},
+	{Customer: 3, Subject: "Quotation for your export order", Body: "Please find our quotation for the Singapore order, valid for 30 days."},
+	{Customer: 4, Subject: "Welcome to Kilang Desa Murni Batik", Body: "Welcome aboard. Your account manager will be in touch to plan the workshop materials."},
+	{Customer: 5, Subject: "Silk samples dispatched", Body: "The silk batik samples you requested were dispatched today and should arrive within three working days."},
+}
+
+// For returns the demo data set of the tenant. The same tenant always gets
+// the same data set.
+func For(tenantID uuid.UUID) *DataSet {
+	ds := &DataSet{
+		TenantID: tenantID,
+		Pipeline: Pipeline{ID: ID(tenantID, KindPipeline, 0), Name: "Demo Sales Pipeline"},
+	}
+
+	for i, fixture := range customerFixtures {
+		c := fixture
+		c.ID = ID(tenantID, KindCustomer, i)
+		c.ContactID = ID(tenantID, KindContact, i)
+		host := fmt.Sprintf("customer%d.example.com", i+1)
+		c.Email = "hello@" + host
+		c.Website = "https://" + host
+		c.ContactMail = "contact@" + host
+		c.Phone = fmt.Sprintf("+6035550%04d", 100+i)
+		c.Tags = append([]string{"demo"}, fixture.Tags...)
+		ds.Customers = append(ds.Customers, c)
+	}
+
+	for i, fixture := range leadFixtures {
+		l := fixture
+		l.ID = ID(tenantID, KindLead, i)
+		l.Email = fmt.Sprintf("lead%d@example.com", i+1)
+		l.Phone = fmt.Sprintf("+6035551%04d", 100+i)
+		ds.Leads = append(ds.Leads, l)
+	}
+
+	for i, fixture := range opportunityFixtures {
+		customer := ds.Customers[fixture.Customer]
+		ds.Opportunities = append(ds.Opportunities, Opportunity{
+			ID:         ID(tenantID, KindOpportunity, i),
+			Name:       fixture.Name,
+			CustomerID: customer.ID,
+			Customer:   customer.Name,
+			Amount:     fixture.Amount,
+			Stage:      fixture.Stage,
+			CloseIn:    fixture.CloseIn,
+		})
+	}
+
+	for i, fixture := range notificationFixtures {
+		customer := ds.Customers[fixture.Customer]
+		ds.Notifications = append(ds.Notifications, Notification{
+			ID:             ID(tenantID, KindNotification, i),
+			CustomerID:     customer.ID,
+			RecipientName:  customer.FirstName + " " + customer.LastName,
+			RecipientEmail: customer.ContactMail,
+			Subject:        fixture.Subject,
+			Body:           fixture.Body,
+		})
+	}
+
+	return ds
+}
@@ -0,0 +1,81 @@
+package demodata
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestFor_IsStablePerTenant(t *testing.T) {
+	tenantID := uuid.New()
+
+	first := For(tenantID)
+	second := For(tenantID)
+
+	if first.Pipeline.ID != second.Pipeline.ID {
+		t.Fatalf("pipeline ID changed between calls")
+	}
+	for i := range first.Customers {
+		if first.Customers[i].ID != second.Customers[i].ID {
+			t.Fatalf("customer %d ID changed between calls", i)
+		}
+	}
+
+	other := For(uuid.New())
+	if other.Customers[0].ID == first.Customers[0].ID {
+		t.Fatalf("two tenants got the same customer ID")
+	}
+}
+
+func TestFor_IDsAreUnique(t *testing.T) {
+	ds := For(uuid.New())
+
+	seen := map[uuid.UUID]bool{ds.Pipeline.ID: true}
+	add := func(id uuid.UUID) {
+		if seen[id] {
+			t.Fatalf("duplicate ID %s", id)
+		}
+		seen[id] = true
+	}
+	for _, c := range ds.Customers {
+		add(c.ID)
+		add(c.ContactID)
+	}
+	for _, l := range ds.Leads {
+		add(l.ID)
+	}
+	for _, o := range ds.Opportunities {
+		add(o.ID)
+	}
+	for _, n := range ds.Notifications {
+		add(n.ID)
+	}
+}
+
+func TestFor_ReferencesDemoCustomers(t *testing.T) {
+	ds := For(uuid.New())
+
+	customers := make(map[uuid.UUID]Customer)
+	for _, c := range ds.Customers {
+		customers[c.ID] = c
+	}
+
+	for _, o := range ds.Opportunities {
+		c, ok := customers[o.CustomerID]
+		if !ok {
+			t.Fatalf("opportunity %q references an unknown customer", o.Name)
+		}
+		if o.Customer != c.Name {
+			t.Errorf("opportunity %q has customer name %q, want %q", o.Name, o.Customer, c.Name)
+		}
+	}
+	for _, n := range ds.Notifications {
+		c, ok := customers[n.CustomerID]
+		if !ok {
+			t.Fatalf("notification %q references an unknown customer", n.Subject)
+		}
+		if n.RecipientEmail != c.ContactMail {
+			t.Errorf("notification %q sent to %q, want %q", n.Subject, n.RecipientEmail, c.ContactMail)
+		}
+	}
+}
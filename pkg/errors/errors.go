@@ -29,6 +29,11 @@ const (
 	ErrCodeTooManyRequests  ErrorCode = "TOO_MANY_REQUESTS"
 	ErrCodeServiceUnavailable ErrorCode = "SERVICE_UNAVAILABLE"
 	ErrCodeTimeout          ErrorCode = "TIMEOUT"
+	ErrCodePayloadTooLarge  ErrorCode = "PAYLOAD_TOO_LARGE"
+	ErrCodeIdempotencyKeyReused ErrorCode = "IDEMPOTENCY_KEY_REUSED"
+	ErrCodePreconditionFailed   ErrorCode = "PRECONDITION_FAILED"
+	ErrCodePreconditionRequired ErrorCode = "PRECONDITION_REQUIRED"
+	ErrCodeGone                 ErrorCode = "GONE"
 
 	// Authentication errors
 	ErrCodeInvalidCredentials ErrorCode = "INVALID_CREDENTIALS"
@@ -82,6 +87,11 @@ var httpStatusMap = map[ErrorCode]int{
 	ErrCodeTooManyRequests:    http.StatusTooManyRequests,
 	ErrCodeServiceUnavailable: http.StatusServiceUnavailable,
 	ErrCodeTimeout:            http.StatusGatewayTimeout,
+	ErrCodePayloadTooLarge:    http.StatusRequestEntityTooLarge,
+	ErrCodeIdempotencyKeyReused: http.StatusUnprocessableEntity,
+	ErrCodePreconditionFailed:   http.StatusPreconditionFailed,
+	ErrCodePreconditionRequired: http.StatusPreconditionRequired,
+	ErrCodeGone:                 http.StatusGone,
 	ErrCodeInvalidCredentials: http.StatusUnauthorized,
 	ErrCodeTokenExpired:       http.StatusUnauthorized,
 	ErrCodeTokenInvalid:       http.StatusUnauthorized,
@@ -295,6 +305,35 @@ func ErrTimeout(operation string) *AppError {
 	return Newf(ErrCodeTimeout, "%s timed out", operation)
 }
 
+// ErrPayloadTooLarge creates a request body size limit error.
+func ErrPayloadTooLarge(limit int64) *AppError {
+	return Newf(ErrCodePayloadTooLarge, "request body exceeds the %d byte limit", limit)
+}
+
+// ErrIdempotencyKeyReused creates an error for an idempotency key sent again
+// with a different request.
+func ErrIdempotencyKeyReused() *AppError {
+	return New(ErrCodeIdempotencyKeyReused, "Idempotency-Key was already used with a different request")
+}
+
+// ErrPreconditionFailed creates an error for a conditional request whose
+// precondition does not hold.
+func ErrPreconditionFailed(message string) *AppError {
+	return New(ErrCodePreconditionFailed, message)
+}
+
+// ErrPreconditionRequired creates an error for a request that must be
+// conditional.
+func ErrPreconditionRequired(message string) *AppError {
+	return New(ErrCodePreconditionRequired, message)
+}
+
+// ErrGone creates an error for a resource that is no longer available, such
+// as an API version past its sunset date.
+func ErrGone(message string) *AppError {
+	return New(ErrCodeGone, message)
+}
+
 // IsAppError checks if the error is an AppError.
 func IsAppError(err error) bool {
 	var appErr *AppError
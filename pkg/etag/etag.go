@@ -0,0 +1,186 @@
+// Package etag provides conditional request support for single-resource
+// endpoints. Reads get an ETag derived from the resource's id, version and
+// updated_at, and are answered with 304 Not Modified when If-None-Match
+// matches. Writes must carry an If-Match with the current ETag, so a client
+// cannot overwrite or delete a resource it has not seen the latest state of.
+package etag
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/kilang-desa-murni/crm/pkg/errors"
+	"github.com/kilang-desa-murni/crm/pkg/response"
+)
+
+// Compute returns the ETag of a JSON response body, or "" when the body is
+// not JSON. Bodies in the {"success", "data"} envelope are tagged by their
+// data. A resource with a version or updated_at is tagged by its id, version
+// and updated_at, so derived fields do not change the tag; anything else is
+// tagged by its content.
+func Compute(body []byte) string {
+	var payload interface{}
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return ""
+	}
+
+	object, _ := payload.(map[string]interface{})
+	if _, wrapped := object["success"]; wrapped {
+		payload = object["data"]
+		object, _ = payload.(map[string]interface{})
+	}
+
+	hash := sha256.New()
+	if object != nil && (object["version"] != nil || object["updated_at"] != nil) {
+		fmt.Fprintf(hash, "%v|%v|%v", object["id"], object["version"], object["updated_at"])
+	} else {
+		data, _ := json.Marshal(payload)
+		hash.Write(data)
+	}
+	return `"` + hex.EncodeToString(hash.Sum(nil))[:32] + `"`
+}
+
+// Resource creates middleware for the routes of a single resource. get serves
+// the resource's current representation; it is called to evaluate If-Match on
+// writes.
+//
+// GET and HEAD responses get an ETag, and a request whose If-None-Match
+// matches it is answered with 304. PUT, PATCH and DELETE require If-Match:
+// without it the request fails with 428, and with a stale ETag with 412.
+func Resource(get http.Handler) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch r.Method {
+			case http.MethodGet, http.MethodHead:
+				serveRead(w, r, next)
+			case http.MethodPut, http.MethodPatch, http.MethodDelete:
+				serveWrite(w, r, next, get)
+			default:
+				next.ServeHTTP(w, r)
+			}
+		})
+	}
+}
+
+// serveRead tags a read and answers it with 304 when the client's copy is
+// current.
+func serveRead(w http.ResponseWriter, r *http.Request, next http.Handler) {
+	rec := newRecorder()
+	next.ServeHTTP(rec, r)
+
+	if rec.statusCode == http.StatusOK {
+		if tag := Compute(rec.body.Bytes()); tag != "" {
+			rec.header.Set("ETag", tag)
+			if matchesAny(r.Header.Get("If-None-Match"), tag, true) {
+				copyHeader(w.Header(), rec.header)
+				w.Header().Del("Content-Length")
+				w.Header().Del("Content-Type")
+				w.WriteHeader(http.StatusNotModified)
+				return
+			}
+		}
+	}
+	rec.flush(w)
+}
+
+// serveWrite checks a write's If-Match against the resource's current ETag
+// before passing it on, and tags the response with the new ETag.
+func serveWrite(w http.ResponseWriter, r *http.Request, next, get http.Handler) {
+	ifMatch := r.Header.Get("If-Match")
+	if ifMatch == "" {
+		response.Error(w, errors.ErrPreconditionRequired("If-Match header is required; send the ETag of the resource"))
+		return
+	}
+
+	current := newRecorder()
+	read := r.Clone(r.Context())
+	read.Method = http.MethodGet
+	read.Body = http.NoBody
+	read.ContentLength = 0
+	read.URL.RawQuery = ""
+	get.ServeHTTP(current, read)
+
+	// A missing resource is reported by the write handler itself.
+	if current.statusCode == http.StatusOK {
+		tag := Compute(current.body.Bytes())
+		if tag == "" || !matchesAny(ifMatch, tag, false) {
+			w.Header().Set("ETag", tag)
+			response.Error(w, errors.ErrPreconditionFailed("resource has been modified; fetch it again and retry"))
+			return
+		}
+	}
+
+	rec := newRecorder()
+	next.ServeHTTP(rec, r)
+	if rec.statusCode >= 200 && rec.statusCode < 300 && r.Method != http.MethodDelete {
+		if tag := Compute(rec.body.Bytes()); tag != "" {
+			rec.header.Set("ETag", tag)
+		}
+	}
+	rec.flush(w)
+}
+
+// matchesAny reports whether an If-Match or If-None-Match header value lists
+// tag. If-None-Match uses the weak comparison, which ignores a W/ prefix.
+func matchesAny(header, tag string, weak bool) bool {
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" {
+			return true
+		}
+		if weak {
+			candidate = strings.TrimPrefix(candidate, "W/")
+		}
+		if candidate == tag {
+			return true
+		}
+	}
+	return false
+}
+
+func copyHeader(dst, src http.Header) {
+	for name, values := range src {
+		dst[name] = values
+	}
+}
+
+// recorder buffers a response so it can be tagged before it is written.
+type recorder struct {
+	header      http.Header
+	statusCode  int
+	body        bytes.Buffer
+	wroteHeader bool
+}
+
+func newRecorder() *recorder {
+	return &recorder{header: make(http.Header), statusCode: http.StatusOK}
+}
+
+func (rec *recorder) Header() http.Header {
+	return rec.header
+}
+
+func (rec *recorder) WriteHeader(code int) {
+	if !rec.wroteHeader {
+		rec.wroteHeader = true
+		rec.statusCode = code
+	}
+}
+
+func (rec *recorder) Write(b []byte) (int, error) {
+	rec.WriteHeader(http.StatusOK)
+	return rec.body.Write(b)
+}
+
+// flush writes the buffered response to w.
+func (rec *recorder) flush(w http.ResponseWriter) {
+	copyHeader(w.Header(), rec.header)
+	w.Header().Del("Content-Length")
+	w.WriteHeader(rec.statusCode)
+	_, _ = w.Write(rec.body.Bytes())
+}
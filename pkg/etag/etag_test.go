@@ -0,0 +1,103 @@
+package etag
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestCompute(t *testing.T) {
+	v1 := Compute([]byte(`{"success":true,"data":{"id":"a","version":1,"updated_at":"2024-01-01T00:00:00Z","days_open":3}}`))
+	v1Later := Compute([]byte(`{"success":true,"data":{"id":"a","version":1,"updated_at":"2024-01-01T00:00:00Z","days_open":4}}`))
+	v2 := Compute([]byte(`{"success":true,"data":{"id":"a","version":2,"updated_at":"2024-01-02T00:00:00Z","days_open":4}}`))
+
+	if v1 == "" || v1 != v1Later {
+		t.Errorf("expected derived fields not to change the tag, got %s and %s", v1, v1Later)
+	}
+	if v1 == v2 {
+		t.Error("expected a new version to change the tag")
+	}
+
+	plain := Compute([]byte(`{"success":true,"data":{"name":"Welcome"},"timestamp":"2024-01-01T00:00:00Z"}`))
+	plainLater := Compute([]byte(`{"success":true,"data":{"name":"Welcome"},"timestamp":"2024-01-01T00:00:05Z"}`))
+	if plain == "" || plain != plainLater {
+		t.Errorf("expected content tags to ignore the envelope, got %s and %s", plain, plainLater)
+	}
+
+	if Compute([]byte("not json")) != "" {
+		t.Error("expected no tag for a non-JSON body")
+	}
+}
+
+// resource is a versioned resource served by a test handler.
+type resource struct {
+	version int
+}
+
+func (res *resource) get(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	if res.version == 2 {
+		_, _ = w.Write([]byte(`{"success":true,"data":{"id":"a","version":2}}`))
+		return
+	}
+	_, _ = w.Write([]byte(`{"success":true,"data":{"id":"a","version":1}}`))
+}
+
+func (res *resource) put(w http.ResponseWriter, r *http.Request) {
+	res.version = 2
+	res.get(w, r)
+}
+
+func TestResource_Read(t *testing.T) {
+	res := &resource{}
+	handler := Resource(http.HandlerFunc(res.get))(http.HandlerFunc(res.get))
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/a", nil))
+	tag := w.Header().Get("ETag")
+	if w.Code != http.StatusOK || tag == "" {
+		t.Fatalf("expected a tagged 200, got %d with ETag %q", w.Code, tag)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/a", nil)
+	req.Header.Set("If-None-Match", "W/"+tag)
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+	if w.Code != http.StatusNotModified || w.Body.Len() != 0 {
+		t.Errorf("expected an empty 304, got %d with %q", w.Code, w.Body.String())
+	}
+}
+
+func TestResource_Write(t *testing.T) {
+	res := &resource{}
+	handler := Resource(http.HandlerFunc(res.get))(http.HandlerFunc(res.put))
+	current := Compute([]byte(`{"id":"a","version":1}`))
+
+	tests := []struct {
+		name    string
+		ifMatch string
+		want    int
+	}{
+		{"missing If-Match", "", http.StatusPreconditionRequired},
+		{"stale If-Match", `"stale"`, http.StatusPreconditionFailed},
+		{"current If-Match", current, http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPut, "/a", nil)
+			if tt.ifMatch != "" {
+				req.Header.Set("If-Match", tt.ifMatch)
+			}
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, req)
+			if w.Code != tt.want {
+				t.Errorf("expected %d, got %d", tt.want, w.Code)
+			}
+		})
+	}
+
+	if res.version != 2 {
+		t.Fatal("expected the current If-Match to reach the handler")
+	}
+}
@@ -48,10 +48,27 @@ const (
 	EventTypeOpportunityLost       EventType = "sales.opportunity.lost"
 	EventTypeDealCreated           EventType = "sales.deal.created"
 	EventTypeDealUpdated           EventType = "sales.deal.updated"
+	EventTypeDealInvoiceOverdue    EventType = "sales.deal.invoice_overdue"
+
+	// Sales insight events
+	EventTypeInsightBiggestDealWon      EventType = "sales.insight.biggest_deal_won"
+	EventTypeInsightLowPipelineCoverage EventType = "sales.insight.low_pipeline_coverage"
+	EventTypeInsightLeadVolumeDrop      EventType = "sales.insight.lead_volume_drop"
+
+	// Sales collaboration events
+	EventTypeCommentMentioned EventType = "sales.comment.mentioned"
 
 	// Notification events
-	EventTypeEmailSend EventType = "notification.email.send"
-	EventTypeSMSSend   EventType = "notification.sms.send"
+	EventTypeEmailSend          EventType = "notification.email.send"
+	EventTypeSMSSend            EventType = "notification.sms.send"
+	EventTypeNotificationFailed EventType = "notification.failed"
+
+	// Billing events
+	EventTypePaymentFailed EventType = "billing.payment.failed"
+
+	// Platform events
+	EventTypeSLOBurnRateAlert EventType = "platform.slo.burn_rate_alert"
+	EventTypeSLABreached      EventType = "platform.sla.breached"
 )
 
 // Event represents a domain event.
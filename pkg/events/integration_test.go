@@ -5,6 +5,7 @@ import (
 	"context"
 	"encoding/json"
 	"os"
+	"strings"
 	"sync"
 	"sync/atomic"
 	"testing"
@@ -112,6 +113,48 @@ func TestRabbitMQEventBus_Connect(t *testing.T) {
 		_, err := NewRabbitMQEventBus(cfg, log)
 		helpers.AssertError(t, err)
 	})
+
+	t.Run("publishes concurrently with broker confirmation", func(t *testing.T) {
+		cfg := &config.RabbitMQConfig{
+			URL:               testRabbitMQ.ConnectionURL(),
+			Exchange:          "test.events",
+			ExchangeType:      "topic",
+			PrefetchCount:     10,
+			ReconnectDelay:    1 * time.Second,
+			MaxReconnectDelay: 5 * time.Second,
+			PublishChannels:   2,
+			ConfirmTimeout:    5 * time.Second,
+		}
+
+		log := logger.New(logger.Config{Level: "debug"})
+		bus, err := NewRabbitMQEventBus(cfg, log)
+		helpers.RequireNoError(t, err)
+		defer bus.Close()
+
+		var wg sync.WaitGroup
+		errs := make(chan error, 10)
+		for i := 0; i < 10; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				event := NewEvent("user.created", fixtures.TestIDs.TenantID1.String(), fixtures.TestIDs.UserID1.String(), nil)
+				errs <- bus.Publish(testCtx, event)
+			}()
+		}
+		wg.Wait()
+		close(errs)
+		for err := range errs {
+			helpers.AssertNoError(t, err)
+		}
+
+		helpers.AssertTrue(t, bus.IsConnected())
+		helpers.AssertNoError(t, bus.Health(testCtx))
+
+		var metrics strings.Builder
+		bus.WriteMetrics(&metrics)
+		helpers.AssertContains(t, metrics.String(), `rabbitmq_publish_total{exchange="test.events",result="confirmed"} 10`)
+		helpers.AssertContains(t, metrics.String(), `rabbitmq_publish_channels{exchange="test.events",state="in_use"} 0`)
+	})
 }
 
 func TestRabbitMQEventBus_Publish(t *testing.T) {
@@ -3,26 +3,53 @@ package events
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"io"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	amqp "github.com/rabbitmq/amqp091-go"
 
 	"github.com/kilang-desa-murni/crm/pkg/config"
+	"github.com/kilang-desa-murni/crm/pkg/lifecycle"
 	"github.com/kilang-desa-murni/crm/pkg/logger"
 )
 
-// RabbitMQEventBus implements EventBus using RabbitMQ.
+var (
+	// ErrPublishNacked is returned when the broker refuses a published event.
+	ErrPublishNacked = errors.New("event was not accepted by the broker")
+	// ErrPublishConfirmTimeout is returned when the broker does not confirm a
+	// published event within the confirm timeout.
+	ErrPublishConfirmTimeout = errors.New("timed out waiting for the broker to confirm the event")
+)
+
+// RabbitMQEventBus implements EventBus using RabbitMQ. Events are published
+// on a pool of confirm-mode channels and each publish waits for the broker
+// to confirm it. Consumers use a channel of their own and are subscribed
+// again when the connection is restored.
 type RabbitMQEventBus struct {
 	conn         *amqp.Connection
 	channel      *amqp.Channel
+	publishers   *channelPool
 	config       *config.RabbitMQConfig
 	log          *logger.Logger
 	mu           sync.RWMutex
 	closed       bool
 	reconnecting bool
 	consumers    map[string]*consumer
+	drainer      lifecycle.Drainer
+	stats        busStats
+}
+
+// busStats counts the reconnections and publish outcomes of the bus.
+type busStats struct {
+	reconnects atomic.Int64
+	confirmed  atomic.Int64
+	nacked     atomic.Int64
+	timeouts   atomic.Int64
+	failed     atomic.Int64
 }
 
 type consumer struct {
@@ -88,6 +115,7 @@ func (b *RabbitMQEventBus) connect() error {
 	b.mu.Lock()
 	b.conn = conn
 	b.channel = channel
+	b.publishers = newChannelPool(conn, b.config.PublishChannels)
 	b.mu.Unlock()
 
 	b.log.Info().
@@ -107,6 +135,7 @@ func (b *RabbitMQEventBus) monitorConnection() {
 			return
 		}
 		conn := b.conn
+		channel := b.channel
 		b.mu.RUnlock()
 
 		if conn == nil {
@@ -114,12 +143,25 @@ func (b *RabbitMQEventBus) monitorConnection() {
 			continue
 		}
 
-		// Wait for connection close notification
-		connClose := conn.NotifyClose(make(chan *amqp.Error))
-		err := <-connClose
+		// Wait for the connection or the consuming channel to close. The
+		// notification channels are buffered so the library never blocks
+		// on them while the bus is reconnecting.
+		connClose := conn.NotifyClose(make(chan *amqp.Error, 1))
+		channelClose := channel.NotifyClose(make(chan *amqp.Error, 1))
 
-		if err != nil {
-			b.log.Error().Err(err).Msg("RabbitMQ connection closed")
+		select {
+		case err := <-connClose:
+			if err != nil {
+				b.log.Error().Err(err).Msg("RabbitMQ connection closed")
+			}
+		case err := <-channelClose:
+			// The broker closes a channel on errors such as an unknown
+			// delivery tag; the consumers on it are only restored by
+			// reconnecting
+			if err != nil {
+				b.log.Error().Err(err).Msg("RabbitMQ consumer channel closed")
+				conn.Close()
+			}
 		}
 
 		b.mu.Lock()
@@ -152,8 +194,11 @@ func (b *RabbitMQEventBus) monitorConnection() {
 				continue
 			}
 
-			// Reconnection successful, restore consumers
-			b.restoreConsumers()
+			// Reconnection successful, restore consumers unless draining
+			b.stats.reconnects.Add(1)
+			if !b.drainer.Stopped() {
+				b.restoreConsumers()
+			}
 
 			b.mu.Lock()
 			b.reconnecting = false
@@ -180,17 +225,20 @@ func (b *RabbitMQEventBus) restoreConsumers() {
 	}
 }
 
-// Publish publishes an event to the event bus.
+// Publish publishes an event to the event bus and waits for the broker to
+// confirm it. It returns ErrPublishNacked when the broker refuses the event
+// and ErrPublishConfirmTimeout when the confirmation does not arrive within
+// the confirm timeout; the event may then still have been delivered.
 func (b *RabbitMQEventBus) Publish(ctx context.Context, event *Event) error {
 	b.mu.RLock()
 	if b.closed {
 		b.mu.RUnlock()
 		return fmt.Errorf("event bus is closed")
 	}
-	channel := b.channel
+	publishers := b.publishers
 	b.mu.RUnlock()
 
-	if channel == nil {
+	if publishers == nil {
 		return fmt.Errorf("channel is not available")
 	}
 
@@ -215,17 +263,34 @@ func (b *RabbitMQEventBus) Publish(ctx context.Context, event *Event) error {
 
 	routingKey := string(event.Type)
 
-	if err := channel.PublishWithContext(
+	channel, err := publishers.get(ctx)
+	if err != nil {
+		b.stats.failed.Add(1)
+		return fmt.Errorf("failed to publish event: %w", err)
+	}
+
+	confirmation, err := channel.PublishWithDeferredConfirmWithContext(
 		ctx,
 		b.config.Exchange, // exchange
 		routingKey,        // routing key
 		false,             // mandatory
 		false,             // immediate
 		msg,
-	); err != nil {
+	)
+	if err != nil {
+		publishers.put(channel, false)
+		b.stats.failed.Add(1)
 		return fmt.Errorf("failed to publish event: %w", err)
 	}
 
+	// A channel with a confirmation still outstanding is not reused, so a
+	// late confirm cannot be mistaken for the next publish's
+	if err := b.awaitConfirm(ctx, confirmation); err != nil {
+		publishers.put(channel, false)
+		return err
+	}
+	publishers.put(channel, true)
+
 	b.log.Debug().
 		Str("event_id", event.ID).
 		Str("event_type", string(event.Type)).
@@ -235,6 +300,29 @@ func (b *RabbitMQEventBus) Publish(ctx context.Context, event *Event) error {
 	return nil
 }
 
+// awaitConfirm waits for the broker to confirm a publish within the confirm
+// timeout.
+func (b *RabbitMQEventBus) awaitConfirm(ctx context.Context, confirmation *amqp.DeferredConfirmation) error {
+	timeout := b.config.ConfirmTimeout
+	if timeout <= 0 {
+		timeout = 5 * time.Second
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	acked, err := confirmation.WaitContext(ctx)
+	switch {
+	case err != nil:
+		b.stats.timeouts.Add(1)
+		return fmt.Errorf("%w: %w", ErrPublishConfirmTimeout, err)
+	case !acked:
+		b.stats.nacked.Add(1)
+		return ErrPublishNacked
+	}
+	b.stats.confirmed.Add(1)
+	return nil
+}
+
 // PublishBatch publishes multiple events to the event bus.
 func (b *RabbitMQEventBus) PublishBatch(ctx context.Context, events []*Event) error {
 	for _, event := range events {
@@ -304,9 +392,11 @@ func (b *RabbitMQEventBus) subscribeToQueue(queueName string, handler Handler) e
 		return fmt.Errorf("channel is not available")
 	}
 
+	// Subscriber queues are unique, so the queue name doubles as the
+	// consumer tag Drain cancels
 	delivery, err := channel.Consume(
 		queueName, // queue
-		"",        // consumer
+		queueName, // consumer
 		false,     // auto-ack
 		false,     // exclusive
 		false,     // no-local
@@ -347,33 +437,72 @@ func (b *RabbitMQEventBus) consume(c *consumer) {
 			if !ok {
 				return
 			}
-
-			event, err := Unmarshal(d.Body)
-			if err != nil {
-				b.log.Error().Err(err).Msg("Failed to unmarshal event")
-				d.Nack(false, false) // Don't requeue malformed messages
+			// Deliveries prefetched after draining began go back to the queue
+			if !b.drainer.Begin() {
+				d.Nack(false, true)
 				continue
 			}
+			b.handle(c, d)
+			b.drainer.End()
+		}
+	}
+}
 
-			ctx := context.Background()
-			if err := c.handler(ctx, event); err != nil {
-				b.log.Error().
-					Err(err).
-					Str("event_id", event.ID).
-					Str("event_type", string(event.Type)).
-					Msg("Failed to handle event")
-				d.Nack(false, true) // Requeue for retry
-				continue
-			}
+// handle processes one delivery.
+func (b *RabbitMQEventBus) handle(c *consumer, d amqp.Delivery) {
+	event, err := Unmarshal(d.Body)
+	if err != nil {
+		b.log.Error().Err(err).Msg("Failed to unmarshal event")
+		d.Nack(false, false) // Don't requeue malformed messages
+		return
+	}
+
+	ctx := context.Background()
+	if err := c.handler(ctx, event); err != nil {
+		b.log.Error().
+			Err(err).
+			Str("event_id", event.ID).
+			Str("event_type", string(event.Type)).
+			Msg("Failed to handle event")
+		d.Nack(false, true) // Requeue for retry
+		return
+	}
 
-			d.Ack(false)
+	d.Ack(false)
 
-			b.log.Debug().
-				Str("event_id", event.ID).
-				Str("event_type", string(event.Type)).
-				Msg("Event handled successfully")
+	b.log.Debug().
+		Str("event_id", event.ID).
+		Str("event_type", string(event.Type)).
+		Msg("Event handled successfully")
+}
+
+// Drain stops consuming, requeues the deliveries not yet handled and waits
+// for the events being handled until ctx is done. Publishing keeps working
+// until Close, so handlers still running elsewhere can publish.
+func (b *RabbitMQEventBus) Drain(ctx context.Context) error {
+	b.drainer.Stop()
+
+	b.mu.RLock()
+	channel := b.channel
+	tags := make([]string, 0, len(b.consumers))
+	for queue := range b.consumers {
+		tags = append(tags, queue)
+	}
+	b.mu.RUnlock()
+
+	if channel != nil {
+		for _, tag := range tags {
+			if err := channel.Cancel(tag, false); err != nil {
+				b.log.Warn().Err(err).Str("queue", tag).Msg("Failed to cancel consumer")
+			}
 		}
 	}
+
+	if err := b.drainer.Wait(ctx); err != nil {
+		return err
+	}
+	b.log.Info().Int("consumers", len(tags)).Msg("RabbitMQ event consumers drained")
+	return nil
 }
 
 // Unsubscribe unsubscribes from all events.
@@ -395,6 +524,7 @@ func (b *RabbitMQEventBus) Close() error {
 	defer b.mu.Unlock()
 
 	b.closed = true
+	b.reconnecting = false
 
 	// Close all consumers
 	for _, c := range b.consumers {
@@ -403,6 +533,10 @@ func (b *RabbitMQEventBus) Close() error {
 
 	var errs []error
 
+	if b.publishers != nil {
+		b.publishers.close()
+	}
+
 	if b.channel != nil {
 		if err := b.channel.Close(); err != nil {
 			errs = append(errs, fmt.Errorf("failed to close channel: %w", err))
@@ -423,3 +557,175 @@ func (b *RabbitMQEventBus) Close() error {
 
 	return nil
 }
+
+// IsConnected reports whether the bus is connected and not reconnecting.
+func (b *RabbitMQEventBus) IsConnected() bool {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	return !b.closed && !b.reconnecting && b.conn != nil && !b.conn.IsClosed()
+}
+
+// Health reports why the bus cannot publish or consume, if it cannot.
+func (b *RabbitMQEventBus) Health(ctx context.Context) error {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	switch {
+	case b.closed:
+		return fmt.Errorf("event bus is closed")
+	case b.reconnecting:
+		return fmt.Errorf("reconnecting to RabbitMQ")
+	case b.conn == nil || b.conn.IsClosed():
+		return fmt.Errorf("connection closed")
+	case b.channel == nil || b.channel.IsClosed():
+		return fmt.Errorf("consumer channel closed")
+	}
+	return nil
+}
+
+// WriteMetrics writes the connection state and publish outcomes of the bus
+// in the Prometheus text format.
+func (b *RabbitMQEventBus) WriteMetrics(w io.Writer) {
+	connected := 0.0
+	if b.IsConnected() {
+		connected = 1
+	}
+
+	b.mu.RLock()
+	publishers := b.publishers
+	consumers := len(b.consumers)
+	b.mu.RUnlock()
+
+	var inUse, idle int
+	if publishers != nil {
+		inUse, idle = publishers.stats()
+	}
+
+	metrics := []struct {
+		name  string
+		kind  string
+		help  string
+		value float64
+	}{
+		{"rabbitmq_connected", "gauge", "Whether the event bus is connected to RabbitMQ.", connected},
+		{"rabbitmq_reconnects_total", "counter", "Connections to RabbitMQ restored after being lost.", float64(b.stats.reconnects.Load())},
+		{"rabbitmq_consumers", "gauge", "Queues being consumed.", float64(consumers)},
+	}
+	for _, m := range metrics {
+		fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s %s\n", m.name, m.help, m.name, m.kind)
+		fmt.Fprintf(w, "%s{exchange=%q} %g\n", m.name, b.config.Exchange, m.value)
+	}
+
+	fmt.Fprintf(w, "# HELP rabbitmq_publish_total Events published by confirmation result.\n# TYPE rabbitmq_publish_total counter\n")
+	for _, r := range []struct {
+		result string
+		count  int64
+	}{
+		{"confirmed", b.stats.confirmed.Load()},
+		{"nacked", b.stats.nacked.Load()},
+		{"timeout", b.stats.timeouts.Load()},
+		{"failed", b.stats.failed.Load()},
+	} {
+		fmt.Fprintf(w, "rabbitmq_publish_total{exchange=%q,result=%q} %d\n", b.config.Exchange, r.result, r.count)
+	}
+
+	fmt.Fprintf(w, "# HELP rabbitmq_publish_channels Publish channels by state.\n# TYPE rabbitmq_publish_channels gauge\n")
+	fmt.Fprintf(w, "rabbitmq_publish_channels{exchange=%q,state=\"in_use\"} %d\n", b.config.Exchange, inUse)
+	fmt.Fprintf(w, "rabbitmq_publish_channels{exchange=%q,state=\"idle\"} %d\n", b.config.Exchange, idle)
+}
+
+// ============================================================================
+// Channel pool
+// ============================================================================
+
+// channelPool lends the confirm-mode channels of a connection to publishes,
+// so concurrent publishes neither share a channel nor open one each. At most
+// size channels are lent at once; further publishes wait for one.
+type channelPool struct {
+	conn  *amqp.Connection
+	idle  chan *amqp.Channel
+	slots chan struct{}
+}
+
+func newChannelPool(conn *amqp.Connection, size int) *channelPool {
+	if size <= 0 {
+		size = 1
+	}
+	return &channelPool{
+		conn:  conn,
+		idle:  make(chan *amqp.Channel, size),
+		slots: make(chan struct{}, size),
+	}
+}
+
+// get lends an idle channel, or opens one, once fewer than size are lent.
+func (p *channelPool) get(ctx context.Context) (*amqp.Channel, error) {
+	select {
+	case p.slots <- struct{}{}:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+
+	if channel := p.takeIdle(); channel != nil {
+		return channel, nil
+	}
+
+	channel, err := p.conn.Channel()
+	if err != nil {
+		<-p.slots
+		return nil, fmt.Errorf("failed to open channel: %w", err)
+	}
+	if err := channel.Confirm(false); err != nil {
+		channel.Close()
+		<-p.slots
+		return nil, fmt.Errorf("failed to enable publisher confirms: %w", err)
+	}
+	return channel, nil
+}
+
+// takeIdle returns an idle channel that is still open, or nil.
+func (p *channelPool) takeIdle() *amqp.Channel {
+	for {
+		select {
+		case channel := <-p.idle:
+			if !channel.IsClosed() {
+				return channel
+			}
+		default:
+			return nil
+		}
+	}
+}
+
+// put returns a lent channel. Channels not to be reused are closed.
+func (p *channelPool) put(channel *amqp.Channel, reuse bool) {
+	defer func() { <-p.slots }()
+
+	if reuse && !channel.IsClosed() {
+		select {
+		case p.idle <- channel:
+			return
+		default:
+		}
+	}
+	channel.Close()
+}
+
+// stats returns the number of channels lent and idle.
+func (p *channelPool) stats() (inUse, idle int) {
+	return len(p.slots), len(p.idle)
+}
+
+// close closes the idle channels; channels still lent are closed with the
+// connection.
+func (p *channelPool) close() {
+	for {
+		select {
+		case channel := <-p.idle:
+			channel.Close()
+		default:
+			return
+		}
+	}
+}
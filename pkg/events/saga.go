@@ -0,0 +1,536 @@
+// Package events provides event bus abstractions for the CRM application.
+package events
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ============================================================================
+// Saga Errors
+// ============================================================================
+
+var (
+	ErrSagaNotFound     = errors.New("saga not found")
+	ErrSagaConflict     = errors.New("saga was updated concurrently")
+	ErrSagaFinished     = errors.New("saga has already finished")
+	ErrSagaTimedOut     = errors.New("saga timed out")
+	ErrUnknownSagaType  = errors.New("unknown saga type")
+	ErrInvalidSagaSteps = errors.New("saga definition has no steps")
+)
+
+// Saga lifecycle event types
+const (
+	EventTypeSagaCompleted   EventType = "saga.completed"
+	EventTypeSagaCompensated EventType = "saga.compensated"
+	EventTypeSagaAborted     EventType = "saga.aborted"
+	EventTypeSagaFailed      EventType = "saga.failed"
+)
+
+// ============================================================================
+// Saga Definition
+// ============================================================================
+
+// SagaAction runs or undoes one step of a saga. Actions share state through
+// Saga.Data, which is persisted between steps; values read back after a
+// resume have been through JSON, so numbers are float64. An action may run
+// again when a saga interrupted during it is resumed, so it should be
+// idempotent.
+type SagaAction func(ctx context.Context, saga *Saga) error
+
+// SagaStep is one step of a saga definition.
+type SagaStep struct {
+	// Name identifies the step in the saga's state.
+	Name string
+
+	// Action does the step's work.
+	Action SagaAction
+
+	// Compensate undoes the step's work when a later step fails or the saga
+	// is aborted. Steps without one have nothing to undo.
+	Compensate SagaAction
+
+	// Timeout bounds each attempt of the action and of the compensation.
+	Timeout time.Duration
+
+	// Retries is the number of extra attempts of a failed action.
+	Retries int
+}
+
+// SagaDefinition is a workflow of steps run in order. When a step fails,
+// the steps already done are compensated in reverse order.
+type SagaDefinition struct {
+	// Type names the workflow, e.g. "tenant.provisioning".
+	Type string
+
+	// Steps are run in order.
+	Steps []SagaStep
+
+	// Timeout bounds the whole saga; a saga past it compensates instead of
+	// running its next step.
+	Timeout time.Duration
+}
+
+// ============================================================================
+// Saga State
+// ============================================================================
+
+// SagaStatus represents the state of a saga.
+type SagaStatus string
+
+const (
+	// SagaStatusRunning indicates the saga is running its steps.
+	SagaStatusRunning SagaStatus = "running"
+
+	// SagaStatusCompensating indicates the saga is undoing its steps.
+	SagaStatusCompensating SagaStatus = "compensating"
+
+	// SagaStatusCompleted indicates all steps succeeded.
+	SagaStatusCompleted SagaStatus = "completed"
+
+	// SagaStatusCompensated indicates a step failed and the steps done were undone.
+	SagaStatusCompensated SagaStatus = "compensated"
+
+	// SagaStatusAborted indicates an operator aborted the saga and its steps were undone.
+	SagaStatusAborted SagaStatus = "aborted"
+
+	// SagaStatusFailed indicates a compensation failed; the saga waits for an
+	// operator to resume or abort it.
+	SagaStatusFailed SagaStatus = "failed"
+)
+
+// IsFinished returns true if the saga will not run again.
+func (s SagaStatus) IsFinished() bool {
+	return s == SagaStatusCompleted || s == SagaStatusCompensated || s == SagaStatusAborted
+}
+
+// SagaStepStatus represents the state of a saga step.
+type SagaStepStatus string
+
+const (
+	SagaStepPending     SagaStepStatus = "pending"
+	SagaStepCompleted   SagaStepStatus = "completed"
+	SagaStepFailed      SagaStepStatus = "failed"
+	SagaStepCompensated SagaStepStatus = "compensated"
+)
+
+// SagaStepState is the state of one step of a saga.
+type SagaStepState struct {
+	Name      string         `json:"name"`
+	Status    SagaStepStatus `json:"status"`
+	Attempts  int            `json:"attempts"`
+	Error     string         `json:"error,omitempty"`
+	UpdatedAt *time.Time     `json:"updated_at,omitempty"`
+}
+
+// Saga is the persisted state of one run of a saga definition.
+type Saga struct {
+	ID          string                 `json:"id"`
+	Type        string                 `json:"type"`
+	TenantID    string                 `json:"tenant_id"`
+	Status      SagaStatus             `json:"status"`
+	CurrentStep int                    `json:"current_step"`
+	Steps       []SagaStepState        `json:"steps"`
+	Data        map[string]interface{} `json:"data"`
+	Error       string                 `json:"error,omitempty"`
+	AbortReason string                 `json:"abort_reason,omitempty"`
+	Deadline    *time.Time             `json:"deadline,omitempty"`
+	StartedAt   time.Time              `json:"started_at"`
+	UpdatedAt   time.Time              `json:"updated_at"`
+	CompletedAt *time.Time             `json:"completed_at,omitempty"`
+	Version     int                    `json:"version"`
+}
+
+// newSaga creates the state of a new run of def.
+func newSaga(def *SagaDefinition, tenantID string, data map[string]interface{}, now time.Time) *Saga {
+	if data == nil {
+		data = make(map[string]interface{})
+	}
+	saga := &Saga{
+		ID:        uuid.New().String(),
+		Type:      def.Type,
+		TenantID:  tenantID,
+		Status:    SagaStatusRunning,
+		Steps:     make([]SagaStepState, len(def.Steps)),
+		Data:      data,
+		StartedAt: now,
+		UpdatedAt: now,
+		Version:   1,
+	}
+	for i, step := range def.Steps {
+		saga.Steps[i] = SagaStepState{Name: step.Name, Status: SagaStepPending}
+	}
+	if def.Timeout > 0 {
+		deadline := now.Add(def.Timeout)
+		saga.Deadline = &deadline
+	}
+	return saga
+}
+
+// ============================================================================
+// Saga Store
+// ============================================================================
+
+// SagaStore persists saga state. Update must fail with ErrSagaConflict when
+// the stored version differs from the saga's, and increment the version.
+type SagaStore interface {
+	// Create stores a new saga.
+	Create(ctx context.Context, saga *Saga) error
+
+	// Update stores the saga's state.
+	Update(ctx context.Context, saga *Saga) error
+
+	// Get retrieves a saga by ID.
+	Get(ctx context.Context, id string) (*Saga, error)
+
+	// ListUnfinished retrieves sagas that have not finished and were last
+	// updated before the given time, oldest first.
+	ListUnfinished(ctx context.Context, updatedBefore time.Time, limit int) ([]*Saga, error)
+}
+
+// InMemorySagaStore is a SagaStore for tests and single-instance tools.
+type InMemorySagaStore struct {
+	mu    sync.RWMutex
+	sagas map[string]*Saga
+}
+
+// NewInMemorySagaStore creates an empty in-memory saga store.
+func NewInMemorySagaStore() *InMemorySagaStore {
+	return &InMemorySagaStore{sagas: make(map[string]*Saga)}
+}
+
+// Create stores a new saga.
+func (s *InMemorySagaStore) Create(ctx context.Context, saga *Saga) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.sagas[saga.ID] = cloneSaga(saga)
+	return nil
+}
+
+// Update stores the saga's state.
+func (s *InMemorySagaStore) Update(ctx context.Context, saga *Saga) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	stored, ok := s.sagas[saga.ID]
+	if !ok {
+		return ErrSagaNotFound
+	}
+	if stored.Version != saga.Version {
+		return ErrSagaConflict
+	}
+	saga.Version++
+	s.sagas[saga.ID] = cloneSaga(saga)
+	return nil
+}
+
+// Get retrieves a saga by ID.
+func (s *InMemorySagaStore) Get(ctx context.Context, id string) (*Saga, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	saga, ok := s.sagas[id]
+	if !ok {
+		return nil, ErrSagaNotFound
+	}
+	return cloneSaga(saga), nil
+}
+
+// ListUnfinished retrieves unfinished sagas last updated before the given time.
+func (s *InMemorySagaStore) ListUnfinished(ctx context.Context, updatedBefore time.Time, limit int) ([]*Saga, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	var sagas []*Saga
+	for _, saga := range s.sagas {
+		if !saga.Status.IsFinished() && saga.UpdatedAt.Before(updatedBefore) {
+			sagas = append(sagas, cloneSaga(saga))
+		}
+	}
+	sort.Slice(sagas, func(i, j int) bool { return sagas[i].UpdatedAt.Before(sagas[j].UpdatedAt) })
+	if limit > 0 && len(sagas) > limit {
+		sagas = sagas[:limit]
+	}
+	return sagas, nil
+}
+
+func cloneSaga(saga *Saga) *Saga {
+	c := *saga
+	c.Steps = append([]SagaStepState(nil), saga.Steps...)
+	c.Data = make(map[string]interface{}, len(saga.Data))
+	for k, v := range saga.Data {
+		c.Data[k] = v
+	}
+	return &c
+}
+
+// ============================================================================
+// Saga Manager
+// ============================================================================
+
+// SagaManager runs sagas of registered definitions, persisting their state
+// after every step so that an interrupted saga can be resumed.
+type SagaManager struct {
+	store       SagaStore
+	publisher   Publisher
+	mu          sync.RWMutex
+	definitions map[string]*SagaDefinition
+	now         func() time.Time
+}
+
+// NewSagaManager creates a saga manager. The publisher, if not nil, receives
+// an event when a saga finishes or fails.
+func NewSagaManager(store SagaStore, publisher Publisher) *SagaManager {
+	return &SagaManager{
+		store:       store,
+		publisher:   publisher,
+		definitions: make(map[string]*SagaDefinition),
+		now:         func() time.Time { return time.Now().UTC() },
+	}
+}
+
+// Register adds a saga definition, replacing one of the same type.
+func (m *SagaManager) Register(def SagaDefinition) error {
+	if def.Type == "" {
+		return fmt.Errorf("saga definition requires a type")
+	}
+	if len(def.Steps) == 0 {
+		return fmt.Errorf("%w: %s", ErrInvalidSagaSteps, def.Type)
+	}
+	for i, step := range def.Steps {
+		if step.Name == "" || step.Action == nil {
+			return fmt.Errorf("saga %s step %d requires a name and an action", def.Type, i+1)
+		}
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.definitions[def.Type] = &def
+	return nil
+}
+
+// Start creates a saga of the given type and runs it. The returned error is
+// that of the step that failed; the saga has then been compensated, or has
+// status failed if a compensation failed too.
+func (m *SagaManager) Start(ctx context.Context, sagaType, tenantID string, data map[string]interface{}) (*Saga, error) {
+	def, err := m.definition(sagaType)
+	if err != nil {
+		return nil, err
+	}
+
+	saga := newSaga(def, tenantID, data, m.now())
+	if err := m.store.Create(ctx, saga); err != nil {
+		return nil, fmt.Errorf("failed to create saga: %w", err)
+	}
+	return saga, m.run(ctx, def, saga)
+}
+
+// Get retrieves a saga by ID.
+func (m *SagaManager) Get(ctx context.Context, id string) (*Saga, error) {
+	return m.store.Get(ctx, id)
+}
+
+// Stuck lists the unfinished sagas that have not made progress for
+// olderThan: sagas interrupted by a restart and failed compensations.
+func (m *SagaManager) Stuck(ctx context.Context, olderThan time.Duration, limit int) ([]*Saga, error) {
+	return m.store.ListUnfinished(ctx, m.now().Add(-olderThan), limit)
+}
+
+// Resume continues an unfinished saga from where it stopped: a running saga
+// runs its remaining steps, a compensating or failed one retries its
+// compensations.
+func (m *SagaManager) Resume(ctx context.Context, id string) (*Saga, error) {
+	saga, def, err := m.load(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if saga.Status == SagaStatusFailed {
+		saga.Status = SagaStatusCompensating
+	}
+	return saga, m.run(ctx, def, saga)
+}
+
+// Abort stops an unfinished saga and undoes the steps it has done.
+func (m *SagaManager) Abort(ctx context.Context, id, reason string) (*Saga, error) {
+	saga, def, err := m.load(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if reason == "" {
+		reason = "aborted by operator"
+	}
+	saga.AbortReason = reason
+	saga.Status = SagaStatusCompensating
+	if err := m.save(ctx, saga); err != nil {
+		return nil, err
+	}
+	return saga, m.compensate(ctx, def, saga)
+}
+
+func (m *SagaManager) load(ctx context.Context, id string) (*Saga, *SagaDefinition, error) {
+	saga, err := m.store.Get(ctx, id)
+	if err != nil {
+		return nil, nil, err
+	}
+	if saga.Status.IsFinished() {
+		return nil, nil, fmt.Errorf("%w: %s", ErrSagaFinished, saga.Status)
+	}
+	def, err := m.definition(saga.Type)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(def.Steps) != len(saga.Steps) {
+		return nil, nil, fmt.Errorf("saga %s has %d steps, definition %s has %d", saga.ID, len(saga.Steps), def.Type, len(def.Steps))
+	}
+	return saga, def, nil
+}
+
+func (m *SagaManager) definition(sagaType string) (*SagaDefinition, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	def, ok := m.definitions[sagaType]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownSagaType, sagaType)
+	}
+	return def, nil
+}
+
+// run runs the remaining steps of a running saga, and compensates when one fails.
+func (m *SagaManager) run(ctx context.Context, def *SagaDefinition, saga *Saga) error {
+	if saga.Status == SagaStatusCompensating {
+		return m.compensate(ctx, def, saga)
+	}
+
+	for saga.CurrentStep < len(def.Steps) {
+		step := def.Steps[saga.CurrentStep]
+		state := &saga.Steps[saga.CurrentStep]
+
+		err := ErrSagaTimedOut
+		if saga.Deadline == nil || m.now().Before(*saga.Deadline) {
+			err = m.attempt(ctx, step, saga, state)
+		}
+		now := m.now()
+		state.UpdatedAt = &now
+
+		if err != nil {
+			state.Status = SagaStepFailed
+			state.Error = err.Error()
+			saga.Error = fmt.Sprintf("step %s: %v", step.Name, err)
+			saga.Status = SagaStatusCompensating
+			if saveErr := m.save(ctx, saga); saveErr != nil {
+				return saveErr
+			}
+			if compErr := m.compensate(ctx, def, saga); compErr != nil {
+				return compErr
+			}
+			return err
+		}
+
+		state.Status = SagaStepCompleted
+		state.Error = ""
+		saga.CurrentStep++
+		if err := m.save(ctx, saga); err != nil {
+			return err
+		}
+	}
+
+	return m.finish(ctx, saga, SagaStatusCompleted, EventTypeSagaCompleted)
+}
+
+// attempt runs a step's action, retrying failed attempts.
+func (m *SagaManager) attempt(ctx context.Context, step SagaStep, saga *Saga, state *SagaStepState) error {
+	var err error
+	for i := 0; i <= step.Retries; i++ {
+		state.Attempts++
+		if err = runSagaAction(ctx, step.Action, step.Timeout, saga); err == nil {
+			return nil
+		}
+		if ctx.Err() != nil {
+			return err
+		}
+	}
+	return err
+}
+
+// compensate undoes the completed steps of a saga in reverse order.
+func (m *SagaManager) compensate(ctx context.Context, def *SagaDefinition, saga *Saga) error {
+	for i := saga.CurrentStep - 1; i >= 0; i-- {
+		state := &saga.Steps[i]
+		if state.Status != SagaStepCompleted {
+			continue
+		}
+
+		step := def.Steps[i]
+		if step.Compensate != nil {
+			if err := runSagaAction(ctx, step.Compensate, step.Timeout, saga); err != nil {
+				state.Error = err.Error()
+				saga.Error = fmt.Sprintf("compensating step %s: %v", step.Name, err)
+				saga.Status = SagaStatusFailed
+				if saveErr := m.save(ctx, saga); saveErr != nil {
+					return saveErr
+				}
+				m.publish(ctx, saga, EventTypeSagaFailed)
+				return fmt.Errorf("compensation of step %s failed: %w", step.Name, err)
+			}
+		}
+
+		now := m.now()
+		state.Status = SagaStepCompensated
+		state.UpdatedAt = &now
+		if err := m.save(ctx, saga); err != nil {
+			return err
+		}
+	}
+
+	if saga.AbortReason != "" {
+		return m.finish(ctx, saga, SagaStatusAborted, EventTypeSagaAborted)
+	}
+	return m.finish(ctx, saga, SagaStatusCompensated, EventTypeSagaCompensated)
+}
+
+func (m *SagaManager) finish(ctx context.Context, saga *Saga, status SagaStatus, eventType EventType) error {
+	now := m.now()
+	saga.Status = status
+	saga.CompletedAt = &now
+	if err := m.save(ctx, saga); err != nil {
+		return err
+	}
+	m.publish(ctx, saga, eventType)
+	return nil
+}
+
+func (m *SagaManager) save(ctx context.Context, saga *Saga) error {
+	saga.UpdatedAt = m.now()
+	if err := m.store.Update(ctx, saga); err != nil {
+		return fmt.Errorf("failed to save saga %s: %w", saga.ID, err)
+	}
+	return nil
+}
+
+// publish reports a finished or failed saga; delivery is best effort.
+func (m *SagaManager) publish(ctx context.Context, saga *Saga, eventType EventType) {
+	if m.publisher == nil {
+		return
+	}
+	event := NewEvent(eventType, saga.TenantID, saga.ID, map[string]interface{}{
+		"saga_type": saga.Type,
+		"status":    string(saga.Status),
+		"error":     saga.Error,
+	})
+	_ = m.publisher.Publish(ctx, event)
+}
+
+// runSagaAction runs an action, bounded by timeout when it is set.
+func runSagaAction(ctx context.Context, action SagaAction, timeout time.Duration, saga *Saga) error {
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+	return action(ctx, saga)
+}
@@ -0,0 +1,127 @@
+// Package events provides event bus abstractions for the CRM application.
+package events
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	apperrors "github.com/kilang-desa-murni/crm/pkg/errors"
+	"github.com/kilang-desa-murni/crm/pkg/response"
+)
+
+// ============================================================================
+// Saga Admin API
+// ============================================================================
+
+// DefaultStuckSagaAge is how long a saga must go without progress to be
+// listed as stuck when the request does not say.
+const DefaultStuckSagaAge = 15 * time.Minute
+
+// NewSagaAdminHandler returns the operator API of a saga manager, to be
+// mounted under a prefix behind admin authentication:
+//
+//	GET  /stuck?older_than=15m&limit=50  unfinished sagas without progress
+//	GET  /{id}                           a saga's state
+//	POST /{id}/resume                    continue a saga where it stopped
+//	POST /{id}/abort                     compensate a saga, body {"reason": "..."}
+func NewSagaAdminHandler(manager *SagaManager) http.Handler {
+	h := &sagaAdminHandler{manager: manager}
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /stuck", h.listStuck)
+	mux.HandleFunc("GET /{id}", h.get)
+	mux.HandleFunc("POST /{id}/resume", h.resume)
+	mux.HandleFunc("POST /{id}/abort", h.abort)
+	return mux
+}
+
+type sagaAdminHandler struct {
+	manager *SagaManager
+}
+
+func (h *sagaAdminHandler) listStuck(w http.ResponseWriter, r *http.Request) {
+	olderThan := DefaultStuckSagaAge
+	if raw := r.URL.Query().Get("older_than"); raw != "" {
+		d, err := time.ParseDuration(raw)
+		if err != nil || d < 0 {
+			response.BadRequest(w, "older_than must be a duration such as 15m or 2h")
+			return
+		}
+		olderThan = d
+	}
+
+	limit := 50
+	if raw := r.URL.Query().Get("limit"); raw != "" {
+		n, err := strconv.Atoi(raw)
+		if err != nil || n < 1 || n > 500 {
+			response.BadRequest(w, "limit must be between 1 and 500")
+			return
+		}
+		limit = n
+	}
+
+	sagas, err := h.manager.Stuck(r.Context(), olderThan, limit)
+	if err != nil {
+		response.Error(w, apperrors.ErrInternalWrap(err, "failed to list stuck sagas"))
+		return
+	}
+	if sagas == nil {
+		sagas = []*Saga{}
+	}
+	response.OK(w, map[string]interface{}{
+		"sagas":      sagas,
+		"older_than": olderThan.String(),
+	})
+}
+
+func (h *sagaAdminHandler) get(w http.ResponseWriter, r *http.Request) {
+	saga, err := h.manager.Get(r.Context(), r.PathValue("id"))
+	if err != nil {
+		writeSagaError(w, err)
+		return
+	}
+	response.OK(w, saga)
+}
+
+// resume responds with the saga's state once it has run; a step that fails
+// again shows in the saga's status and error rather than as a failed request.
+func (h *sagaAdminHandler) resume(w http.ResponseWriter, r *http.Request) {
+	saga, err := h.manager.Resume(r.Context(), r.PathValue("id"))
+	if saga == nil {
+		writeSagaError(w, err)
+		return
+	}
+	response.OK(w, saga)
+}
+
+func (h *sagaAdminHandler) abort(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Reason string `json:"reason"`
+	}
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			response.BadRequest(w, "invalid request body")
+			return
+		}
+	}
+
+	saga, err := h.manager.Abort(r.Context(), r.PathValue("id"), req.Reason)
+	if saga == nil {
+		writeSagaError(w, err)
+		return
+	}
+	response.OK(w, saga)
+}
+
+func writeSagaError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, ErrSagaNotFound):
+		response.NotFound(w, "saga")
+	case errors.Is(err, ErrSagaFinished), errors.Is(err, ErrSagaConflict):
+		response.Conflict(w, err.Error())
+	default:
+		response.Error(w, apperrors.ErrInternalWrap(err, "saga operation failed"))
+	}
+}
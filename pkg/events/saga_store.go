@@ -0,0 +1,196 @@
+// Package events provides event bus abstractions for the CRM application.
+package events
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// ============================================================================
+// PostgreSQL Saga Store
+// ============================================================================
+
+const sagaColumns = `id, tenant_id, saga_type, status, current_step, steps, data,
+	error, abort_reason, deadline, started_at, updated_at, completed_at, version`
+
+// sagaRow represents a saga database row.
+type sagaRow struct {
+	ID          string         `db:"id"`
+	TenantID    string         `db:"tenant_id"`
+	SagaType    string         `db:"saga_type"`
+	Status      string         `db:"status"`
+	CurrentStep int            `db:"current_step"`
+	Steps       []byte         `db:"steps"`
+	Data        []byte         `db:"data"`
+	Error       sql.NullString `db:"error"`
+	AbortReason sql.NullString `db:"abort_reason"`
+	Deadline    sql.NullTime   `db:"deadline"`
+	StartedAt   time.Time      `db:"started_at"`
+	UpdatedAt   time.Time      `db:"updated_at"`
+	CompletedAt sql.NullTime   `db:"completed_at"`
+	Version     int            `db:"version"`
+}
+
+// PostgresSagaStore is a SagaStore backed by a PostgreSQL table. Each
+// service keeps its sagas in a table of its own schema; see
+// migrations/sales/000014_workflow_sagas.up.sql for the layout.
+type PostgresSagaStore struct {
+	db    *sqlx.DB
+	table string
+}
+
+// NewPostgresSagaStore creates a saga store on the given table.
+func NewPostgresSagaStore(db *sqlx.DB, table string) *PostgresSagaStore {
+	return &PostgresSagaStore{db: db, table: table}
+}
+
+// Create inserts a new saga.
+func (s *PostgresSagaStore) Create(ctx context.Context, saga *Saga) error {
+	steps, data, err := marshalSagaState(saga)
+	if err != nil {
+		return err
+	}
+
+	query := `INSERT INTO ` + s.table + ` (` + sagaColumns + `)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14)`
+
+	_, err = s.db.ExecContext(ctx, query,
+		saga.ID, saga.TenantID, saga.Type, string(saga.Status), saga.CurrentStep, steps, data,
+		nullSagaString(saga.Error), nullSagaString(saga.AbortReason), nullSagaTime(saga.Deadline),
+		saga.StartedAt, saga.UpdatedAt, nullSagaTime(saga.CompletedAt), saga.Version,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to insert saga: %w", err)
+	}
+	return nil
+}
+
+// Update stores the saga's state if it has not been updated since it was read.
+func (s *PostgresSagaStore) Update(ctx context.Context, saga *Saga) error {
+	steps, data, err := marshalSagaState(saga)
+	if err != nil {
+		return err
+	}
+
+	query := `UPDATE ` + s.table + ` SET
+			status = $3, current_step = $4, steps = $5, data = $6, error = $7,
+			abort_reason = $8, updated_at = $9, completed_at = $10, version = version + 1
+		WHERE id = $1 AND version = $2`
+
+	result, err := s.db.ExecContext(ctx, query,
+		saga.ID, saga.Version, string(saga.Status), saga.CurrentStep, steps, data,
+		nullSagaString(saga.Error), nullSagaString(saga.AbortReason), saga.UpdatedAt,
+		nullSagaTime(saga.CompletedAt),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update saga: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rows == 0 {
+		if _, err := s.Get(ctx, saga.ID); err != nil {
+			return err
+		}
+		return ErrSagaConflict
+	}
+
+	saga.Version++
+	return nil
+}
+
+// Get retrieves a saga by ID.
+func (s *PostgresSagaStore) Get(ctx context.Context, id string) (*Saga, error) {
+	query := `SELECT ` + sagaColumns + ` FROM ` + s.table + ` WHERE id = $1`
+
+	var row sagaRow
+	if err := s.db.GetContext(ctx, &row, query, id); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrSagaNotFound
+		}
+		return nil, fmt.Errorf("failed to get saga: %w", err)
+	}
+	return row.toSaga()
+}
+
+// ListUnfinished retrieves unfinished sagas last updated before the given time, oldest first.
+func (s *PostgresSagaStore) ListUnfinished(ctx context.Context, updatedBefore time.Time, limit int) ([]*Saga, error) {
+	query := `SELECT ` + sagaColumns + ` FROM ` + s.table + `
+		WHERE status IN ('running', 'compensating', 'failed') AND updated_at < $1
+		ORDER BY updated_at ASC
+		LIMIT $2`
+
+	var rows []sagaRow
+	if err := s.db.SelectContext(ctx, &rows, query, updatedBefore, limit); err != nil {
+		return nil, fmt.Errorf("failed to list unfinished sagas: %w", err)
+	}
+
+	sagas := make([]*Saga, 0, len(rows))
+	for i := range rows {
+		saga, err := rows[i].toSaga()
+		if err != nil {
+			return nil, err
+		}
+		sagas = append(sagas, saga)
+	}
+	return sagas, nil
+}
+
+func (r *sagaRow) toSaga() (*Saga, error) {
+	saga := &Saga{
+		ID:          r.ID,
+		Type:        r.SagaType,
+		TenantID:    r.TenantID,
+		Status:      SagaStatus(r.Status),
+		CurrentStep: r.CurrentStep,
+		Error:       r.Error.String,
+		AbortReason: r.AbortReason.String,
+		StartedAt:   r.StartedAt,
+		UpdatedAt:   r.UpdatedAt,
+		Version:     r.Version,
+	}
+	if r.Deadline.Valid {
+		saga.Deadline = &r.Deadline.Time
+	}
+	if r.CompletedAt.Valid {
+		saga.CompletedAt = &r.CompletedAt.Time
+	}
+	if err := json.Unmarshal(r.Steps, &saga.Steps); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal saga steps: %w", err)
+	}
+	if err := json.Unmarshal(r.Data, &saga.Data); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal saga data: %w", err)
+	}
+	return saga, nil
+}
+
+func marshalSagaState(saga *Saga) ([]byte, []byte, error) {
+	steps, err := json.Marshal(saga.Steps)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to marshal saga steps: %w", err)
+	}
+	data, err := json.Marshal(saga.Data)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to marshal saga data: %w", err)
+	}
+	return steps, data, nil
+}
+
+func nullSagaString(s string) sql.NullString {
+	return sql.NullString{String: s, Valid: s != ""}
+}
+
+func nullSagaTime(t *time.Time) sql.NullTime {
+	if t == nil {
+		return sql.NullTime{}
+	}
+	return sql.NullTime{Time: *t, Valid: true}
+}
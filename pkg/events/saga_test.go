@@ -0,0 +1,338 @@
+package events
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+type recordingSagaPublisher struct {
+	mu     sync.Mutex
+	events []*Event
+}
+
+func (p *recordingSagaPublisher) Publish(ctx context.Context, event *Event) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.events = append(p.events, event)
+	return nil
+}
+
+func (p *recordingSagaPublisher) PublishBatch(ctx context.Context, events []*Event) error {
+	for _, event := range events {
+		_ = p.Publish(ctx, event)
+	}
+	return nil
+}
+
+func (p *recordingSagaPublisher) Close() error { return nil }
+
+// recordStep returns a step that appends its name to calls, and
+// "undo <name>" when it is compensated.
+func recordStep(name string, calls *[]string, err error) SagaStep {
+	return SagaStep{
+		Name: name,
+		Action: func(ctx context.Context, saga *Saga) error {
+			*calls = append(*calls, name)
+			return err
+		},
+		Compensate: func(ctx context.Context, saga *Saga) error {
+			*calls = append(*calls, "undo "+name)
+			return nil
+		},
+	}
+}
+
+func TestSagaManager_Start_Completes(t *testing.T) {
+	publisher := &recordingSagaPublisher{}
+	manager := NewSagaManager(NewInMemorySagaStore(), publisher)
+	err := manager.Register(SagaDefinition{
+		Type: "tenant.provisioning",
+		Steps: []SagaStep{
+			{Name: "create_schema", Action: func(ctx context.Context, saga *Saga) error {
+				saga.Data["schema"] = "tenant_abc"
+				return nil
+			}},
+			{Name: "seed_pipeline", Action: func(ctx context.Context, saga *Saga) error {
+				if saga.Data["schema"] != "tenant_abc" {
+					return errors.New("schema missing from saga data")
+				}
+				return nil
+			}},
+		},
+	})
+	if err != nil {
+		t.Fatalf("Register() error = %v", err)
+	}
+
+	saga, err := manager.Start(context.Background(), "tenant.provisioning", "tenant-1", nil)
+	if err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+	if saga.Status != SagaStatusCompleted || saga.CompletedAt == nil {
+		t.Errorf("Status = %s, want completed", saga.Status)
+	}
+
+	stored, err := manager.Get(context.Background(), saga.ID)
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if stored.CurrentStep != 2 || stored.Steps[1].Status != SagaStepCompleted {
+		t.Errorf("stored saga = %+v, want both steps completed", stored)
+	}
+	if len(publisher.events) != 1 || publisher.events[0].Type != EventTypeSagaCompleted {
+		t.Errorf("published %d events, want one %s", len(publisher.events), EventTypeSagaCompleted)
+	}
+}
+
+func TestSagaManager_Start_CompensatesInReverse(t *testing.T) {
+	var calls []string
+	stepErr := errors.New("invoice service unavailable")
+	manager := NewSagaManager(NewInMemorySagaStore(), nil)
+	_ = manager.Register(SagaDefinition{
+		Type: "deal.invoicing",
+		Steps: []SagaStep{
+			recordStep("reserve_stock", &calls, nil),
+			recordStep("mark_deal_won", &calls, nil),
+			recordStep("create_invoice", &calls, stepErr),
+		},
+	})
+
+	saga, err := manager.Start(context.Background(), "deal.invoicing", "tenant-1", nil)
+	if !errors.Is(err, stepErr) {
+		t.Fatalf("Start() error = %v, want %v", err, stepErr)
+	}
+	if saga.Status != SagaStatusCompensated {
+		t.Errorf("Status = %s, want compensated", saga.Status)
+	}
+
+	want := "reserve_stock,mark_deal_won,create_invoice,undo mark_deal_won,undo reserve_stock"
+	if got := strings.Join(calls, ","); got != want {
+		t.Errorf("calls = %s, want %s", got, want)
+	}
+	if saga.Steps[2].Status != SagaStepFailed || saga.Steps[0].Status != SagaStepCompensated {
+		t.Errorf("steps = %+v", saga.Steps)
+	}
+}
+
+func TestSagaManager_Start_RetriesStep(t *testing.T) {
+	attempts := 0
+	manager := NewSagaManager(NewInMemorySagaStore(), nil)
+	_ = manager.Register(SagaDefinition{
+		Type: "flaky",
+		Steps: []SagaStep{{
+			Name:    "call_service",
+			Retries: 2,
+			Action: func(ctx context.Context, saga *Saga) error {
+				attempts++
+				if attempts < 3 {
+					return errors.New("temporary failure")
+				}
+				return nil
+			},
+		}},
+	})
+
+	saga, err := manager.Start(context.Background(), "flaky", "", nil)
+	if err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+	if saga.Status != SagaStatusCompleted || saga.Steps[0].Attempts != 3 {
+		t.Errorf("Status = %s after %d attempts, want completed after 3", saga.Status, saga.Steps[0].Attempts)
+	}
+}
+
+func TestSagaManager_Start_StepTimeout(t *testing.T) {
+	manager := NewSagaManager(NewInMemorySagaStore(), nil)
+	_ = manager.Register(SagaDefinition{
+		Type: "slow",
+		Steps: []SagaStep{{
+			Name:    "wait",
+			Timeout: 10 * time.Millisecond,
+			Action: func(ctx context.Context, saga *Saga) error {
+				<-ctx.Done()
+				return ctx.Err()
+			},
+		}},
+	})
+
+	saga, err := manager.Start(context.Background(), "slow", "", nil)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Start() error = %v, want deadline exceeded", err)
+	}
+	if saga.Status != SagaStatusCompensated {
+		t.Errorf("Status = %s, want compensated", saga.Status)
+	}
+}
+
+func TestSagaManager_SagaDeadline(t *testing.T) {
+	var calls []string
+	manager := NewSagaManager(NewInMemorySagaStore(), nil)
+	now := time.Date(2024, 6, 1, 9, 0, 0, 0, time.UTC)
+	manager.now = func() time.Time { return now }
+	_ = manager.Register(SagaDefinition{
+		Type:    "onboarding",
+		Timeout: time.Hour,
+		Steps: []SagaStep{
+			{Name: "create_account", Action: func(ctx context.Context, saga *Saga) error {
+				calls = append(calls, "create_account")
+				now = now.Add(2 * time.Hour)
+				return nil
+			}, Compensate: func(ctx context.Context, saga *Saga) error {
+				calls = append(calls, "undo create_account")
+				return nil
+			}},
+			recordStep("send_welcome", &calls, nil),
+		},
+	})
+
+	_, err := manager.Start(context.Background(), "onboarding", "", nil)
+	if !errors.Is(err, ErrSagaTimedOut) {
+		t.Fatalf("Start() error = %v, want %v", err, ErrSagaTimedOut)
+	}
+	if got := strings.Join(calls, ","); got != "create_account,undo create_account" {
+		t.Errorf("calls = %s, want the second step skipped", got)
+	}
+}
+
+func TestSagaManager_FailedCompensation_Resume(t *testing.T) {
+	var calls []string
+	refundDown := true
+	publisher := &recordingSagaPublisher{}
+	manager := NewSagaManager(NewInMemorySagaStore(), publisher)
+	_ = manager.Register(SagaDefinition{
+		Type: "order",
+		Steps: []SagaStep{
+			{Name: "charge", Action: func(ctx context.Context, saga *Saga) error {
+				return nil
+			}, Compensate: func(ctx context.Context, saga *Saga) error {
+				if refundDown {
+					return errors.New("refund service unavailable")
+				}
+				calls = append(calls, "refund")
+				return nil
+			}},
+			recordStep("ship", &calls, errors.New("no courier")),
+		},
+	})
+
+	saga, err := manager.Start(context.Background(), "order", "", nil)
+	if err == nil || saga.Status != SagaStatusFailed {
+		t.Fatalf("Start() = %s, %v; want failed compensation", saga.Status, err)
+	}
+	if len(publisher.events) != 1 || publisher.events[0].Type != EventTypeSagaFailed {
+		t.Errorf("want one %s event", EventTypeSagaFailed)
+	}
+
+	stuck, _ := manager.Stuck(context.Background(), -time.Minute, 10)
+	if len(stuck) != 1 || stuck[0].ID != saga.ID {
+		t.Fatalf("Stuck() = %d sagas, want the failed saga", len(stuck))
+	}
+
+	refundDown = false
+	saga, err = manager.Resume(context.Background(), saga.ID)
+	if err != nil {
+		t.Fatalf("Resume() error = %v", err)
+	}
+	if saga.Status != SagaStatusCompensated || strings.Join(calls, ",") != "ship,refund" {
+		t.Errorf("Resume() = %s with calls %v, want compensated after refund", saga.Status, calls)
+	}
+
+	if _, err := manager.Resume(context.Background(), saga.ID); !errors.Is(err, ErrSagaFinished) {
+		t.Errorf("second Resume() error = %v, want %v", err, ErrSagaFinished)
+	}
+}
+
+func TestSagaManager_Abort(t *testing.T) {
+	var calls []string
+	store := NewInMemorySagaStore()
+	manager := NewSagaManager(store, nil)
+	def := SagaDefinition{
+		Type: "provisioning",
+		Steps: []SagaStep{
+			recordStep("create_tenant", &calls, nil),
+			recordStep("create_admin", &calls, nil),
+		},
+	}
+	_ = manager.Register(def)
+
+	// A saga interrupted after its first step, as after a restart
+	saga := newSaga(&def, "tenant-1", nil, time.Now().UTC())
+	saga.Steps[0].Status = SagaStepCompleted
+	saga.CurrentStep = 1
+	_ = store.Create(context.Background(), saga)
+
+	aborted, err := manager.Abort(context.Background(), saga.ID, "customer cancelled")
+	if err != nil {
+		t.Fatalf("Abort() error = %v", err)
+	}
+	if aborted.Status != SagaStatusAborted || aborted.AbortReason != "customer cancelled" {
+		t.Errorf("Abort() = %s (%q), want aborted", aborted.Status, aborted.AbortReason)
+	}
+	if got := strings.Join(calls, ","); got != "undo create_tenant" {
+		t.Errorf("calls = %s, want only the completed step undone", got)
+	}
+}
+
+func TestSagaManager_UnknownType(t *testing.T) {
+	manager := NewSagaManager(NewInMemorySagaStore(), nil)
+	if _, err := manager.Start(context.Background(), "missing", "", nil); !errors.Is(err, ErrUnknownSagaType) {
+		t.Errorf("Start() error = %v, want %v", err, ErrUnknownSagaType)
+	}
+	if err := manager.Register(SagaDefinition{Type: "empty"}); !errors.Is(err, ErrInvalidSagaSteps) {
+		t.Errorf("Register() error = %v, want %v", err, ErrInvalidSagaSteps)
+	}
+}
+
+func TestInMemorySagaStore_UpdateConflict(t *testing.T) {
+	store := NewInMemorySagaStore()
+	saga := newSaga(&SagaDefinition{Type: "t", Steps: []SagaStep{{Name: "a"}}}, "", nil, time.Now())
+	_ = store.Create(context.Background(), saga)
+
+	stale, _ := store.Get(context.Background(), saga.ID)
+	if err := store.Update(context.Background(), saga); err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+	if err := store.Update(context.Background(), stale); !errors.Is(err, ErrSagaConflict) {
+		t.Errorf("Update(stale) error = %v, want %v", err, ErrSagaConflict)
+	}
+}
+
+func TestSagaAdminHandler(t *testing.T) {
+	var calls []string
+	manager := NewSagaManager(NewInMemorySagaStore(), nil)
+	_ = manager.Register(SagaDefinition{
+		Type:  "provisioning",
+		Steps: []SagaStep{recordStep("create_tenant", &calls, errors.New("boom"))},
+	})
+	saga, _ := manager.Start(context.Background(), "provisioning", "", nil)
+	handler := NewSagaAdminHandler(manager)
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		status int
+	}{
+		{"stuck", http.MethodGet, "/stuck?older_than=0s", http.StatusOK},
+		{"invalid age", http.MethodGet, "/stuck?older_than=soon", http.StatusBadRequest},
+		{"get", http.MethodGet, "/" + saga.ID, http.StatusOK},
+		{"not found", http.MethodGet, "/missing", http.StatusNotFound},
+		{"resume finished", http.MethodPost, "/" + saga.ID + "/resume", http.StatusConflict},
+		{"abort finished", http.MethodPost, "/" + saga.ID + "/abort", http.StatusConflict},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, httptest.NewRequest(tt.method, tt.path, nil))
+			if rec.Code != tt.status {
+				t.Errorf("%s %s = %d, want %d", tt.method, tt.path, rec.Code, tt.status)
+			}
+		})
+	}
+}
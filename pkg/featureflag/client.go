@@ -0,0 +1,93 @@
+package featureflag
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/kilang-desa-murni/crm/pkg/logger"
+)
+
+// DefaultRefreshInterval is how often a client reloads the flags when the
+// configuration does not say.
+const DefaultRefreshInterval = 30 * time.Second
+
+// Client evaluates flags from a copy of the store kept in memory, so checks
+// never wait on the store. Until the first successful load every flag is
+// off; failed reloads keep the flags last loaded.
+type Client struct {
+	store Store
+	log   *logger.Logger
+
+	mu    sync.RWMutex
+	flags map[string]*Flag
+}
+
+// NewClient creates a client on a store.
+func NewClient(store Store, log *logger.Logger) *Client {
+	return &Client{store: store, log: log, flags: map[string]*Flag{}}
+}
+
+// Start loads the flags and reloads them every interval until ctx is done.
+func (c *Client) Start(ctx context.Context, interval time.Duration) {
+	if err := c.Reload(ctx); err != nil {
+		c.log.Warn().Err(err).Msg("Failed to load feature flags")
+	}
+	if interval <= 0 {
+		interval = DefaultRefreshInterval
+	}
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if err := c.Reload(ctx); err != nil {
+					c.log.Warn().Err(err).Msg("Failed to reload feature flags")
+				}
+			}
+		}
+	}()
+}
+
+// Reload replaces the cached flags with those in the store.
+func (c *Client) Reload(ctx context.Context) error {
+	list, err := c.store.List(ctx)
+	if err != nil {
+		return err
+	}
+
+	flags := make(map[string]*Flag, len(list))
+	for _, flag := range list {
+		flags[flag.Key] = flag
+	}
+
+	c.mu.Lock()
+	c.flags = flags
+	c.mu.Unlock()
+	return nil
+}
+
+// Enabled reports whether the flag key is on for s. Unknown flags are off.
+func (c *Client) Enabled(key string, s Subject) bool {
+	c.mu.RLock()
+	flag, ok := c.flags[key]
+	c.mu.RUnlock()
+
+	return ok && flag.Evaluate(s)
+}
+
+// Evaluate returns every flag's state for s.
+func (c *Client) Evaluate(s Subject) map[string]bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	states := make(map[string]bool, len(c.flags))
+	for key, flag := range c.flags {
+		states[key] = flag.Evaluate(s)
+	}
+	return states
+}
@@ -0,0 +1,46 @@
+package featureflag
+
+import (
+	"context"
+	"net/http"
+)
+
+// SubjectFunc returns who a request is evaluated for, usually from the
+// tenant and user the authentication middleware put in the context.
+type SubjectFunc func(ctx context.Context) Subject
+
+type contextKey struct{}
+
+// evaluator is what Middleware puts in the request context.
+type evaluator struct {
+	client  *Client
+	subject SubjectFunc
+}
+
+// Middleware makes the client's flags available to handlers through Enabled
+// and All. The subject is resolved when a flag is checked, so the middleware
+// may run before the authentication middleware.
+func Middleware(client *Client, subject SubjectFunc) func(http.Handler) http.Handler {
+	e := &evaluator{client: client, subject: subject}
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), contextKey{}, e)))
+		})
+	}
+}
+
+// Enabled reports whether the flag key is on for the request of ctx. It is
+// false when the request did not pass through Middleware.
+func Enabled(ctx context.Context, key string) bool {
+	e, ok := ctx.Value(contextKey{}).(*evaluator)
+	return ok && e.client.Enabled(key, e.subject(ctx))
+}
+
+// All returns every flag's state for the request of ctx.
+func All(ctx context.Context) map[string]bool {
+	e, ok := ctx.Value(contextKey{}).(*evaluator)
+	if !ok {
+		return map[string]bool{}
+	}
+	return e.client.Evaluate(e.subject(ctx))
+}
@@ -0,0 +1,212 @@
+package featureflag
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/kilang-desa-murni/crm/pkg/logger"
+)
+
+func testClient(t *testing.T, flags ...*Flag) (*Client, *MemoryStore) {
+	t.Helper()
+	store := NewMemoryStore(flags...)
+	client := NewClient(store, logger.New(logger.Config{Level: "error"}))
+	if err := client.Reload(context.Background()); err != nil {
+		t.Fatalf("reload: %v", err)
+	}
+	return client, store
+}
+
+func TestFlag_Evaluate(t *testing.T) {
+	flag := &Flag{
+		Key:             "pipeline_board",
+		Enabled:         true,
+		Tenants:         []string{"tenant-beta"},
+		Users:           []string{"user-qa"},
+		ExcludedTenants: []string{"tenant-legacy"},
+	}
+
+	tests := []struct {
+		name    string
+		subject Subject
+		want    bool
+	}{
+		{"targeted tenant", Subject{TenantID: "tenant-beta"}, true},
+		{"targeted user", Subject{TenantID: "tenant-other", UserID: "user-qa"}, true},
+		{"excluded tenant wins over user targeting", Subject{TenantID: "tenant-legacy", UserID: "user-qa"}, false},
+		{"untargeted tenant at zero rollout", Subject{TenantID: "tenant-other"}, false},
+		{"anonymous", Subject{}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := flag.Evaluate(tt.subject); got != tt.want {
+				t.Errorf("expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+
+	flag.Enabled = false
+	if flag.Evaluate(Subject{TenantID: "tenant-beta"}) {
+		t.Error("expected a disabled flag to be off for targeted tenants")
+	}
+}
+
+func TestFlag_EvaluateRollout(t *testing.T) {
+	flag := &Flag{Key: "pipeline_board", Enabled: true, Rollout: 30}
+
+	on := map[string]bool{}
+	for i := 0; i < 1000; i++ {
+		tenant := fmt.Sprintf("tenant-%d", i)
+		if flag.Evaluate(Subject{TenantID: tenant}) {
+			on[tenant] = true
+		}
+	}
+	if len(on) < 250 || len(on) > 350 {
+		t.Errorf("expected about 30%% of tenants, got %d of 1000", len(on))
+	}
+
+	// Raising the rollout keeps the tenants that already had the feature
+	flag.Rollout = 60
+	for tenant := range on {
+		if !flag.Evaluate(Subject{TenantID: tenant}) {
+			t.Fatalf("expected %s to keep the feature as the rollout grows", tenant)
+		}
+	}
+
+	flag.Rollout = 100
+	if !flag.Evaluate(Subject{}) {
+		t.Error("expected a full rollout to include anonymous requests")
+	}
+}
+
+func TestFlag_Validate(t *testing.T) {
+	for _, flag := range []*Flag{
+		{Key: "Pipeline Board"},
+		{Key: ""},
+		{Key: "pipeline_board", Rollout: 101},
+	} {
+		if err := flag.Validate(); err == nil {
+			t.Errorf("expected %+v to be invalid", flag)
+		}
+	}
+	if err := (&Flag{Key: "sales.pipeline-board_v2", Rollout: 50}).Validate(); err != nil {
+		t.Errorf("expected a valid flag, got %v", err)
+	}
+}
+
+func TestMiddleware(t *testing.T) {
+	client, _ := testClient(t, &Flag{Key: "pipeline_board", Enabled: true, Tenants: []string{"tenant-beta"}})
+
+	type tenantKey struct{}
+	subject := func(ctx context.Context) Subject {
+		tenant, _ := ctx.Value(tenantKey{}).(string)
+		return Subject{TenantID: tenant}
+	}
+
+	var enabled bool
+	var all map[string]bool
+	handler := Middleware(client, subject)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Authentication runs after the flag middleware here
+		ctx := context.WithValue(r.Context(), tenantKey{}, r.Header.Get("X-Tenant-ID"))
+		enabled = Enabled(ctx, "pipeline_board")
+		all = All(ctx)
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/pipelines", nil)
+	req.Header.Set("X-Tenant-ID", "tenant-beta")
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+	if !enabled || !all["pipeline_board"] {
+		t.Errorf("expected the flag on for the targeted tenant, got %v %v", enabled, all)
+	}
+
+	req.Header.Set("X-Tenant-ID", "tenant-other")
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+	if enabled {
+		t.Error("expected the flag off for other tenants")
+	}
+
+	if Enabled(context.Background(), "pipeline_board") {
+		t.Error("expected flags off outside the middleware")
+	}
+}
+
+func TestAdminHandler(t *testing.T) {
+	client, store := testClient(t)
+	handler := NewAdminHandler(store, client, func(ctx context.Context) Subject {
+		return Subject{UserID: "admin-1"}
+	})
+
+	do := func(method, target, body string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(method, target, strings.NewReader(body)))
+		return rec
+	}
+
+	rec := do(http.MethodPut, "/pipeline_board", `{"enabled":true,"rollout":0,"tenants":["tenant-beta"]}`)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if !client.Enabled("pipeline_board", Subject{TenantID: "tenant-beta"}) {
+		t.Error("expected the change to reach the client at once")
+	}
+	saved, _ := store.Get(context.Background(), "pipeline_board")
+	if saved.UpdatedBy != "admin-1" {
+		t.Errorf("expected the editor to be recorded, got %q", saved.UpdatedBy)
+	}
+
+	if rec := do(http.MethodPut, "/pipeline_board", `{"enabled":true,"rollout":150}`); rec.Code != http.StatusBadRequest {
+		t.Errorf("expected an invalid rollout to be refused, got %d", rec.Code)
+	}
+
+	rec = do(http.MethodGet, "/pipeline_board/evaluate?tenant_id=tenant-beta", "")
+	var body struct {
+		Data struct {
+			Enabled bool `json:"enabled"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil || !body.Data.Enabled {
+		t.Errorf("expected the flag on for the tenant, got %s", rec.Body.String())
+	}
+
+	if rec := do(http.MethodGet, "/", ""); rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), "pipeline_board") {
+		t.Errorf("expected the flag listed, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	if rec := do(http.MethodDelete, "/pipeline_board", ""); rec.Code != http.StatusNoContent {
+		t.Errorf("expected 204, got %d", rec.Code)
+	}
+	if rec := do(http.MethodGet, "/pipeline_board", ""); rec.Code != http.StatusNotFound {
+		t.Errorf("expected 404 after deleting, got %d", rec.Code)
+	}
+	if client.Enabled("pipeline_board", Subject{TenantID: "tenant-beta"}) {
+		t.Error("expected a deleted flag to be off")
+	}
+}
+
+func TestEvaluateHandler(t *testing.T) {
+	client, _ := testClient(t,
+		&Flag{Key: "pipeline_board", Enabled: true, Rollout: 100},
+		&Flag{Key: "forecasting", Enabled: false},
+	)
+	handler := NewEvaluateHandler(client, func(ctx context.Context) Subject { return Subject{TenantID: "tenant-1"} })
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/feature-flags", nil))
+
+	var body struct {
+		Data struct {
+			Flags map[string]bool `json:"flags"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if !body.Data.Flags["pipeline_board"] || body.Data.Flags["forecasting"] || len(body.Data.Flags) != 2 {
+		t.Errorf("unexpected flags %v", body.Data.Flags)
+	}
+}
@@ -0,0 +1,170 @@
+package featureflag
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	apperrors "github.com/kilang-desa-murni/crm/pkg/errors"
+	"github.com/kilang-desa-murni/crm/pkg/response"
+)
+
+// ============================================================================
+// Admin API
+// ============================================================================
+
+// NewAdminHandler returns the flag management API, to be mounted under a
+// prefix behind admin authentication:
+//
+//	GET    /                                      list the flags
+//	GET    /{key}                                 a flag
+//	PUT    /{key}                                 create or replace a flag
+//	DELETE /{key}                                 delete a flag
+//	GET    /{key}/evaluate?tenant_id=&user_id=    whether a flag is on for a subject
+//
+// Changes are applied to client at once; other services pick them up on
+// their next reload. The subject of the request is recorded as the flag's
+// last editor.
+func NewAdminHandler(store Store, client *Client, subject SubjectFunc) http.Handler {
+	h := &adminHandler{store: store, client: client, subject: subject}
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /{$}", h.list)
+	mux.HandleFunc("GET /{key}", h.get)
+	mux.HandleFunc("PUT /{key}", h.put)
+	mux.HandleFunc("DELETE /{key}", h.delete)
+	mux.HandleFunc("GET /{key}/evaluate", h.evaluate)
+	return mux
+}
+
+type adminHandler struct {
+	store   Store
+	client  *Client
+	subject SubjectFunc
+}
+
+// flagRequest is the body of PUT /{key}.
+type flagRequest struct {
+	Description     string   `json:"description"`
+	Enabled         bool     `json:"enabled"`
+	Rollout         int      `json:"rollout"`
+	Tenants         []string `json:"tenants"`
+	Users           []string `json:"users"`
+	ExcludedTenants []string `json:"excluded_tenants"`
+}
+
+func (h *adminHandler) list(w http.ResponseWriter, r *http.Request) {
+	flags, err := h.store.List(r.Context())
+	if err != nil {
+		response.Error(w, apperrors.ErrInternalWrap(err, "failed to list feature flags"))
+		return
+	}
+	if flags == nil {
+		flags = []*Flag{}
+	}
+	response.OK(w, flags)
+}
+
+func (h *adminHandler) get(w http.ResponseWriter, r *http.Request) {
+	flag, err := h.store.Get(r.Context(), r.PathValue("key"))
+	if err != nil {
+		writeFlagError(w, err)
+		return
+	}
+	response.OK(w, flag)
+}
+
+func (h *adminHandler) put(w http.ResponseWriter, r *http.Request) {
+	var req flagRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.BadRequest(w, "invalid request body")
+		return
+	}
+
+	flag := &Flag{
+		Key:             r.PathValue("key"),
+		Description:     req.Description,
+		Enabled:         req.Enabled,
+		Rollout:         req.Rollout,
+		Tenants:         req.Tenants,
+		Users:           req.Users,
+		ExcludedTenants: req.ExcludedTenants,
+		UpdatedBy:       h.subject(r.Context()).UserID,
+		UpdatedAt:       time.Now().UTC(),
+	}
+	if err := flag.Validate(); err != nil {
+		writeFlagError(w, err)
+		return
+	}
+
+	if err := h.store.Save(r.Context(), flag); err != nil {
+		writeFlagError(w, err)
+		return
+	}
+	h.reload(r)
+	response.OK(w, flag)
+}
+
+func (h *adminHandler) delete(w http.ResponseWriter, r *http.Request) {
+	if err := h.store.Delete(r.Context(), r.PathValue("key")); err != nil {
+		writeFlagError(w, err)
+		return
+	}
+	h.reload(r)
+	response.NoContent(w)
+}
+
+func (h *adminHandler) evaluate(w http.ResponseWriter, r *http.Request) {
+	flag, err := h.store.Get(r.Context(), r.PathValue("key"))
+	if err != nil {
+		writeFlagError(w, err)
+		return
+	}
+
+	subject := Subject{
+		TenantID: r.URL.Query().Get("tenant_id"),
+		UserID:   r.URL.Query().Get("user_id"),
+	}
+	response.OK(w, map[string]interface{}{
+		"key":     flag.Key,
+		"subject": subject,
+		"enabled": flag.Evaluate(subject),
+	})
+}
+
+// reload applies a change to the local client; a failure only delays it to
+// the next periodic reload.
+func (h *adminHandler) reload(r *http.Request) {
+	if err := h.client.Reload(r.Context()); err != nil {
+		h.client.log.Warn().Err(err).Msg("Failed to reload feature flags")
+	}
+}
+
+func writeFlagError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, ErrFlagNotFound):
+		response.Error(w, apperrors.ErrNotFound("feature flag"))
+	case errors.Is(err, ErrInvalidFlag):
+		response.Error(w, apperrors.ErrValidation(err.Error()))
+	default:
+		response.Error(w, apperrors.ErrInternalWrap(err, "feature flag operation failed"))
+	}
+}
+
+// ============================================================================
+// Evaluation API
+// ============================================================================
+
+// NewEvaluateHandler returns every flag's state for the caller, for clients
+// such as the web app that toggle features themselves:
+//
+//	{"success": true, "data": {"flags": {"pipeline_board": true}}}
+func NewEvaluateHandler(client *Client, subject SubjectFunc) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Rollouts change while the app is open; let it re-check
+		w.Header().Set("Cache-Control", "private, max-age=60")
+		response.OK(w, map[string]interface{}{
+			"flags": client.Evaluate(subject(r.Context())),
+		})
+	})
+}
@@ -0,0 +1,269 @@
+package featureflag
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/kilang-desa-murni/crm/pkg/config"
+	"github.com/kilang-desa-murni/crm/pkg/database"
+)
+
+// Store persists feature flags. The services share one store, selected by
+// the feature_flags.store setting.
+type Store interface {
+	// List returns every flag, ordered by key.
+	List(ctx context.Context) ([]*Flag, error)
+	// Get returns a flag, or ErrFlagNotFound.
+	Get(ctx context.Context, key string) (*Flag, error)
+	// Save creates or replaces a flag.
+	Save(ctx context.Context, flag *Flag) error
+	// Delete removes a flag, or returns ErrFlagNotFound.
+	Delete(ctx context.Context, key string) error
+}
+
+// NewStore returns the store cfg selects. db is only used by the postgres
+// store and may be nil for services without a PostgreSQL connection.
+func NewStore(cfg config.FeatureFlagsConfig, redis *database.RedisClient, db *sql.DB) (Store, error) {
+	switch cfg.Store {
+	case "", "redis":
+		return NewRedisStore(redis), nil
+	case "postgres":
+		if db == nil {
+			return nil, fmt.Errorf("feature flag store postgres needs a PostgreSQL connection")
+		}
+		return NewPostgresStore(db, cfg.Table), nil
+	default:
+		return nil, fmt.Errorf("unknown feature flag store %q", cfg.Store)
+	}
+}
+
+// ============================================================================
+// Memory Store
+// ============================================================================
+
+// MemoryStore keeps flags in memory, for tests and local development.
+type MemoryStore struct {
+	mu    sync.RWMutex
+	flags map[string]Flag
+}
+
+// NewMemoryStore creates a store holding flags.
+func NewMemoryStore(flags ...*Flag) *MemoryStore {
+	s := &MemoryStore{flags: make(map[string]Flag, len(flags))}
+	for _, flag := range flags {
+		s.flags[flag.Key] = *flag
+	}
+	return s
+}
+
+// List returns every flag, ordered by key.
+func (s *MemoryStore) List(ctx context.Context) ([]*Flag, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	flags := make([]*Flag, 0, len(s.flags))
+	for _, flag := range s.flags {
+		flag := flag
+		flags = append(flags, &flag)
+	}
+	sortFlags(flags)
+	return flags, nil
+}
+
+// Get returns a flag.
+func (s *MemoryStore) Get(ctx context.Context, key string) (*Flag, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	flag, ok := s.flags[key]
+	if !ok {
+		return nil, ErrFlagNotFound
+	}
+	return &flag, nil
+}
+
+// Save creates or replaces a flag.
+func (s *MemoryStore) Save(ctx context.Context, flag *Flag) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.flags[flag.Key] = *flag
+	return nil
+}
+
+// Delete removes a flag.
+func (s *MemoryStore) Delete(ctx context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.flags[key]; !ok {
+		return ErrFlagNotFound
+	}
+	delete(s.flags, key)
+	return nil
+}
+
+// ============================================================================
+// Redis Store
+// ============================================================================
+
+// redisFlagsKey is the Redis hash holding the flags as JSON by key.
+const redisFlagsKey = "feature_flags"
+
+// RedisStore keeps flags in a Redis hash.
+type RedisStore struct {
+	redis *database.RedisClient
+}
+
+// NewRedisStore creates a store on a Redis connection.
+func NewRedisStore(redis *database.RedisClient) *RedisStore {
+	return &RedisStore{redis: redis}
+}
+
+// List returns every flag, ordered by key.
+func (s *RedisStore) List(ctx context.Context) ([]*Flag, error) {
+	raw, err := s.redis.HGetAll(ctx, redisFlagsKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list feature flags: %w", err)
+	}
+
+	flags := make([]*Flag, 0, len(raw))
+	for key, value := range raw {
+		var flag Flag
+		if err := json.Unmarshal([]byte(value), &flag); err != nil {
+			return nil, fmt.Errorf("failed to decode feature flag %s: %w", key, err)
+		}
+		flags = append(flags, &flag)
+	}
+	sortFlags(flags)
+	return flags, nil
+}
+
+// Get returns a flag.
+func (s *RedisStore) Get(ctx context.Context, key string) (*Flag, error) {
+	var flag Flag
+	if err := s.redis.HGet(ctx, redisFlagsKey, key, &flag); err != nil {
+		if errors.Is(err, database.ErrKeyNotFound) {
+			return nil, ErrFlagNotFound
+		}
+		return nil, fmt.Errorf("failed to get feature flag: %w", err)
+	}
+	return &flag, nil
+}
+
+// Save creates or replaces a flag.
+func (s *RedisStore) Save(ctx context.Context, flag *Flag) error {
+	if err := s.redis.HSet(ctx, redisFlagsKey, flag.Key, flag); err != nil {
+		return fmt.Errorf("failed to save feature flag: %w", err)
+	}
+	return nil
+}
+
+// Delete removes a flag.
+func (s *RedisStore) Delete(ctx context.Context, key string) error {
+	removed, err := s.redis.Client().HDel(ctx, redisFlagsKey, key).Result()
+	if err != nil {
+		return fmt.Errorf("failed to delete feature flag: %w", err)
+	}
+	if removed == 0 {
+		return ErrFlagNotFound
+	}
+	return nil
+}
+
+// ============================================================================
+// PostgreSQL Store
+// ============================================================================
+
+// PostgresStore keeps flags in a PostgreSQL table; see
+// migrations/iam/000002_feature_flags.up.sql for the layout.
+type PostgresStore struct {
+	db    *sql.DB
+	table string
+}
+
+// NewPostgresStore creates a store on the given table.
+func NewPostgresStore(db *sql.DB, table string) *PostgresStore {
+	return &PostgresStore{db: db, table: table}
+}
+
+// List returns every flag, ordered by key.
+func (s *PostgresStore) List(ctx context.Context) ([]*Flag, error) {
+	rows, err := s.db.QueryContext(ctx, `SELECT key, definition FROM `+s.table+` ORDER BY key`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list feature flags: %w", err)
+	}
+	defer rows.Close()
+
+	var flags []*Flag
+	for rows.Next() {
+		var key string
+		var definition []byte
+		if err := rows.Scan(&key, &definition); err != nil {
+			return nil, fmt.Errorf("failed to scan feature flag: %w", err)
+		}
+		var flag Flag
+		if err := json.Unmarshal(definition, &flag); err != nil {
+			return nil, fmt.Errorf("failed to decode feature flag %s: %w", key, err)
+		}
+		flags = append(flags, &flag)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list feature flags: %w", err)
+	}
+	return flags, nil
+}
+
+// Get returns a flag.
+func (s *PostgresStore) Get(ctx context.Context, key string) (*Flag, error) {
+	var definition []byte
+	err := s.db.QueryRowContext(ctx, `SELECT definition FROM `+s.table+` WHERE key = $1`, key).Scan(&definition)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrFlagNotFound
+		}
+		return nil, fmt.Errorf("failed to get feature flag: %w", err)
+	}
+
+	var flag Flag
+	if err := json.Unmarshal(definition, &flag); err != nil {
+		return nil, fmt.Errorf("failed to decode feature flag %s: %w", key, err)
+	}
+	return &flag, nil
+}
+
+// Save creates or replaces a flag.
+func (s *PostgresStore) Save(ctx context.Context, flag *Flag) error {
+	definition, err := json.Marshal(flag)
+	if err != nil {
+		return err
+	}
+
+	query := `INSERT INTO ` + s.table + ` (key, definition, updated_at) VALUES ($1, $2, $3)
+		ON CONFLICT (key) DO UPDATE SET definition = EXCLUDED.definition, updated_at = EXCLUDED.updated_at`
+	if _, err := s.db.ExecContext(ctx, query, flag.Key, definition, flag.UpdatedAt); err != nil {
+		return fmt.Errorf("failed to save feature flag: %w", err)
+	}
+	return nil
+}
+
+// Delete removes a flag.
+func (s *PostgresStore) Delete(ctx context.Context, key string) error {
+	result, err := s.db.ExecContext(ctx, `DELETE FROM `+s.table+` WHERE key = $1`, key)
+	if err != nil {
+		return fmt.Errorf("failed to delete feature flag: %w", err)
+	}
+	if n, err := result.RowsAffected(); err == nil && n == 0 {
+		return ErrFlagNotFound
+	}
+	return nil
+}
+
+func sortFlags(flags []*Flag) {
+	sort.Slice(flags, func(i, j int) bool { return flags[i].Key < flags[j].Key })
+}
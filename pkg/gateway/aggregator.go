@@ -8,10 +8,14 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"sort"
+	"strings"
 	"sync"
 	"time"
 
 	"github.com/google/uuid"
+
+	"github.com/kilang-desa-murni/crm/pkg/cache"
 )
 
 // ============================================================================
@@ -86,6 +90,7 @@ type RequestAggregator struct {
 	services   map[string]ServiceEndpoint
 	httpClient *http.Client
 	cache      Cache
+	inflight   cache.Group[ServiceResponse]
 	mu         sync.RWMutex
 }
 
@@ -316,6 +321,32 @@ func (a *RequestAggregator) executeRequest(ctx context.Context, req ServiceReque
 		}
 	}
 
+	// Concurrent identical reads share one backend call
+	if req.Method == "GET" {
+		shared, _, err := a.inflight.Do(ctx, a.getCoalesceKey(req), func(ctx context.Context) (ServiceResponse, error) {
+			return a.fetch(ctx, req, startTime), nil
+		})
+		if err != nil {
+			response.StatusCode = http.StatusGatewayTimeout
+			response.Error = fmt.Sprintf("request cancelled: %v", err)
+			response.Duration = time.Since(startTime)
+			return response
+		}
+		shared.ID = req.ID
+		shared.Duration = time.Since(startTime)
+		return shared
+	}
+
+	return a.fetch(ctx, req, startTime)
+}
+
+// fetch sends a service request to its backend.
+func (a *RequestAggregator) fetch(ctx context.Context, req ServiceRequest, startTime time.Time) ServiceResponse {
+	response := ServiceResponse{
+		ID:      req.ID,
+		Service: req.Service,
+	}
+
 	// Get service endpoint
 	service, ok := a.GetService(req.Service)
 	if !ok {
@@ -415,6 +446,25 @@ func (a *RequestAggregator) getCacheKey(req ServiceRequest) string {
 	return fmt.Sprintf("agg:%s:%s:%s", req.Service, req.Method, req.Path)
 }
 
+// getCoalesceKey identifies requests that may share one backend call. Headers
+// are part of the key so callers with different credentials never share a response.
+func (a *RequestAggregator) getCoalesceKey(req ServiceRequest) string {
+	var b strings.Builder
+	b.WriteString(a.getCacheKey(req))
+	for _, params := range []map[string]string{req.QueryParams, req.Headers} {
+		keys := make([]string, 0, len(params))
+		for k := range params {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		b.WriteByte('|')
+		for _, k := range keys {
+			fmt.Fprintf(&b, "%s=%s;", k, params[k])
+		}
+	}
+	return b.String()
+}
+
 // ============================================================================
 // Aggregation Handler
 // ============================================================================
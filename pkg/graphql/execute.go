@@ -0,0 +1,362 @@
+package graphql
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"sync"
+)
+
+// Params are the parameters of a GraphQL request.
+type Params struct {
+	Query         string                 `json:"query"`
+	OperationName string                 `json:"operationName,omitempty"`
+	Variables     map[string]interface{} `json:"variables,omitempty"`
+}
+
+// Result is the response to a GraphQL request. Data is omitted when the
+// request failed before execution started.
+type Result struct {
+	Data   interface{} `json:"data,omitempty"`
+	Errors []*Error    `json:"errors,omitempty"`
+}
+
+// Execute parses, validates and executes a query.
+func (s *Schema) Execute(ctx context.Context, params Params) *Result {
+	doc, err := Parse(params.Query)
+	if err != nil {
+		return &Result{Errors: []*Error{toError(err)}}
+	}
+
+	op, opErr := selectOperation(doc, params.OperationName)
+	if opErr != nil {
+		return &Result{Errors: []*Error{opErr}}
+	}
+	if op.Type != "query" {
+		return &Result{Errors: []*Error{{
+			Message:   fmt.Sprintf("Operation type %q is not supported.", op.Type),
+			Locations: []Location{op.Loc},
+		}}}
+	}
+
+	variables, declared, errs := s.coerceVariables(op, params.Variables)
+	if len(errs) > 0 {
+		return &Result{Errors: errs}
+	}
+
+	v := &validator{schema: s, doc: doc, variables: variables, declared: declared}
+	v.validate(op)
+	if len(v.errors) > 0 {
+		return &Result{Errors: v.errors}
+	}
+
+	e := &executor{schema: s, doc: doc, variables: variables, args: v.args}
+	data := e.execute(ctx, op)
+	return &Result{Data: data, Errors: e.errors}
+}
+
+// ============================================================================
+// Executor
+// ============================================================================
+
+// executor resolves an operation breadth-first. Every field at one depth is
+// resolved before any field below it, and the thunks returned at a depth are
+// forced together, so dataloaders see all of a depth's keys in one batch.
+type executor struct {
+	schema    *Schema
+	doc       *Document
+	variables map[string]interface{}
+	args      map[*Field]map[string]interface{}
+	errors    []*Error
+}
+
+// fieldTask is one field to resolve, together with where its value goes.
+type fieldTask struct {
+	target *resultMap
+	key    string
+	def    *FieldDef
+	fields []*Field
+	source interface{}
+	path   []interface{}
+
+	value interface{}
+	err   error
+}
+
+func (e *executor) execute(ctx context.Context, op *Operation) *resultMap {
+	data := newResultMap()
+	level := e.plan(e.schema.query, op.SelectionSet, nil, data, nil)
+
+	for len(level) > 0 {
+		if err := ctx.Err(); err != nil {
+			for _, task := range level {
+				e.fail(task, err)
+			}
+			break
+		}
+
+		for _, task := range level {
+			e.resolve(ctx, task)
+		}
+
+		var wg sync.WaitGroup
+		for _, task := range level {
+			if thunk, ok := task.value.(Thunk); ok && task.err == nil {
+				wg.Add(1)
+				go func(task *fieldTask, thunk Thunk) {
+					defer wg.Done()
+					task.value, task.err = force(thunk)
+				}(task, thunk)
+			}
+		}
+		wg.Wait()
+
+		var next []*fieldTask
+		for _, task := range level {
+			if task.err != nil {
+				e.fail(task, task.err)
+				continue
+			}
+			task.target.set(task.key, e.complete(task, task.def.Type, task.value, task.path, &next))
+		}
+		level = next
+	}
+
+	return data
+}
+
+// plan collects the fields of a selection set on an object and reserves
+// their keys in the target, in query order.
+func (e *executor) plan(object *Object, selections []Selection, source interface{}, target *resultMap, path []interface{}) []*fieldTask {
+	keys, groups := e.collect(selections, nil, nil, make(map[string]bool))
+
+	var tasks []*fieldTask
+	for _, key := range keys {
+		fields := groups[key]
+		if fields[0].Name == "__typename" {
+			target.set(key, object.Name)
+			continue
+		}
+
+		def, _ := object.Field(fields[0].Name)
+		target.set(key, nil)
+		tasks = append(tasks, &fieldTask{
+			target: target,
+			key:    key,
+			def:    def,
+			fields: fields,
+			source: source,
+			path:   appendPath(path, key),
+		})
+	}
+	return tasks
+}
+
+// collect groups the selected fields by response key, expanding fragments
+// and applying @skip and @include.
+func (e *executor) collect(selections []Selection, keys []string, groups map[string][]*Field, visited map[string]bool) ([]string, map[string][]*Field) {
+	if groups == nil {
+		groups = make(map[string][]*Field)
+	}
+
+	for _, selection := range selections {
+		switch sel := selection.(type) {
+		case *Field:
+			if include, _ := shouldInclude(sel.Directives, e.variables); !include {
+				continue
+			}
+			key := sel.ResponseKey()
+			if _, ok := groups[key]; !ok {
+				keys = append(keys, key)
+			}
+			groups[key] = append(groups[key], sel)
+
+		case *FragmentSpread:
+			if include, _ := shouldInclude(sel.Directives, e.variables); !include || visited[sel.Name] {
+				continue
+			}
+			visited[sel.Name] = true
+			keys, groups = e.collect(e.doc.Fragments[sel.Name].SelectionSet, keys, groups, visited)
+
+		case *InlineFragment:
+			if include, _ := shouldInclude(sel.Directives, e.variables); !include {
+				continue
+			}
+			keys, groups = e.collect(sel.SelectionSet, keys, groups, visited)
+		}
+	}
+	return keys, groups
+}
+
+func (e *executor) resolve(ctx context.Context, task *fieldTask) {
+	defer func() {
+		if r := recover(); r != nil {
+			task.value, task.err = nil, fmt.Errorf("internal error resolving %q", task.fields[0].Name)
+		}
+	}()
+
+	args := e.args[task.fields[0]]
+	if args == nil {
+		args = map[string]interface{}{}
+	}
+
+	resolve := task.def.Resolve
+	if resolve == nil {
+		resolve = defaultResolve
+	}
+	task.value, task.err = resolve(ResolveParams{
+		Context:   ctx,
+		Source:    task.source,
+		Args:      args,
+		FieldName: task.fields[0].Name,
+		Path:      task.path,
+	})
+}
+
+func force(thunk Thunk) (value interface{}, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			value, err = nil, errors.New("internal error resolving deferred value")
+		}
+	}()
+	return thunk()
+}
+
+func defaultResolve(p ResolveParams) (interface{}, error) {
+	if source, ok := p.Source.(map[string]interface{}); ok {
+		return source[p.FieldName], nil
+	}
+	return nil, nil
+}
+
+// complete converts a resolved value to its response form. Objects are
+// returned empty and their fields queued on next.
+func (e *executor) complete(task *fieldTask, t Type, value interface{}, path []interface{}, next *[]*fieldTask) interface{} {
+	if isNil(value) {
+		return nil
+	}
+
+	switch t := t.(type) {
+	case *Scalar:
+		serialized, err := t.Serialize(value)
+		if err != nil {
+			e.addError(task, path, err)
+			return nil
+		}
+		return serialized
+
+	case *List:
+		rv := reflect.ValueOf(value)
+		if rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array {
+			e.addError(task, path, fmt.Errorf("expected a list for field %q, got %T", task.fields[0].Name, value))
+			return nil
+		}
+		items := make([]interface{}, rv.Len())
+		for i := range items {
+			items[i] = e.complete(task, t.OfType, rv.Index(i).Interface(), appendPath(path, i), next)
+		}
+		return items
+
+	case *Object:
+		var selections []Selection
+		for _, field := range task.fields {
+			selections = append(selections, field.SelectionSet...)
+		}
+		object := newResultMap()
+		*next = append(*next, e.plan(t, selections, value, object, path)...)
+		return object
+	}
+
+	e.addError(task, path, fmt.Errorf("unsupported output type %s", t))
+	return nil
+}
+
+func (e *executor) fail(task *fieldTask, err error) {
+	task.target.set(task.key, nil)
+	e.addError(task, task.path, err)
+}
+
+func (e *executor) addError(task *fieldTask, path []interface{}, err error) {
+	gqlErr := toError(err)
+	gqlErr.Path = path
+	if gqlErr.Locations == nil {
+		gqlErr.Locations = []Location{task.fields[0].Loc}
+	}
+	e.errors = append(e.errors, gqlErr)
+}
+
+// toError converts an error to a GraphQL error, keeping the extensions of
+// errors created with NewError.
+func toError(err error) *Error {
+	var gqlErr *Error
+	if errors.As(err, &gqlErr) {
+		copied := *gqlErr
+		return &copied
+	}
+	return &Error{Message: err.Error()}
+}
+
+func appendPath(path []interface{}, segment interface{}) []interface{} {
+	out := make([]interface{}, len(path), len(path)+1)
+	copy(out, path)
+	return append(out, segment)
+}
+
+func isNil(value interface{}) bool {
+	if value == nil {
+		return true
+	}
+	switch rv := reflect.ValueOf(value); rv.Kind() {
+	case reflect.Ptr, reflect.Map, reflect.Slice, reflect.Interface, reflect.Func:
+		return rv.IsNil()
+	}
+	return false
+}
+
+// ============================================================================
+// Ordered Result Map
+// ============================================================================
+
+// resultMap is a response object that marshals its keys in query order.
+type resultMap struct {
+	keys   []string
+	values map[string]interface{}
+}
+
+func newResultMap() *resultMap {
+	return &resultMap{values: make(map[string]interface{})}
+}
+
+func (m *resultMap) set(key string, value interface{}) {
+	if _, ok := m.values[key]; !ok {
+		m.keys = append(m.keys, key)
+	}
+	m.values[key] = value
+}
+
+// MarshalJSON implements json.Marshaler.
+func (m *resultMap) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, key := range m.keys {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		k, err := json.Marshal(key)
+		if err != nil {
+			return nil, err
+		}
+		v, err := json.Marshal(m.values[key])
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(k)
+		buf.WriteByte(':')
+		buf.Write(v)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,367 @@
+package graphql
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+// testSchema builds a small schema of customers and their owners. Owners are
+// loaded through a loader so batching can be observed.
+func testSchema(t *testing.T, config SchemaConfig) *Schema {
+	t.Helper()
+
+	customers := []interface{}{
+		map[string]interface{}{"id": "c1", "name": "Butik Seri", "ownerId": "u1", "tags": []string{"vip"}},
+		map[string]interface{}{"id": "c2", "name": "Kedai Mawar", "ownerId": "u2"},
+		map[string]interface{}{"id": "c3", "name": "Galeri Pantai", "ownerId": "u1"},
+	}
+
+	user := NewObject("User", "A user.")
+	user.AddField("id", &FieldDef{Type: ID})
+	user.AddField("name", &FieldDef{Type: String})
+
+	customer := NewObject("Customer", "A customer.")
+	customer.AddField("id", &FieldDef{Type: ID})
+	customer.AddField("name", &FieldDef{Type: String})
+	customer.AddField("tags", &FieldDef{Type: NewList(String)})
+	customer.AddField("owner", &FieldDef{
+		Type: user,
+		Resolve: func(p ResolveParams) (interface{}, error) {
+			loader := p.Context.Value(loaderKey{}).(*Loader[string, map[string]interface{}])
+			return loader.Load(p.Context, p.Source.(map[string]interface{})["ownerId"].(string)), nil
+		},
+	})
+	customer.AddField("failing", &FieldDef{
+		Type: String,
+		Resolve: func(p ResolveParams) (interface{}, error) {
+			return nil, NewError("FORBIDDEN", "not allowed")
+		},
+	})
+
+	query := NewObject("Query", "")
+	query.AddField("customers", &FieldDef{
+		Type: NewList(customer),
+		Args: []*ArgDef{{Name: "limit", Type: Int, DefaultValue: 10}},
+		Resolve: func(p ResolveParams) (interface{}, error) {
+			limit := p.Args["limit"].(int)
+			if limit > len(customers) {
+				limit = len(customers)
+			}
+			return customers[:limit], nil
+		},
+		Complexity: func(args map[string]interface{}, child int) int {
+			return 1 + args["limit"].(int)*child
+		},
+	})
+	query.AddField("customer", &FieldDef{
+		Type: customer,
+		Args: []*ArgDef{{Name: "id", Type: NewNonNull(ID)}},
+		Resolve: func(p ResolveParams) (interface{}, error) {
+			for _, c := range customers {
+				if c.(map[string]interface{})["id"] == p.Args["id"] {
+					return c, nil
+				}
+			}
+			return nil, nil
+		},
+	})
+	query.AddField("greeting", &FieldDef{
+		Type: String,
+		Args: []*ArgDef{{Name: "name", Type: String, DefaultValue: "world"}},
+		Resolve: func(p ResolveParams) (interface{}, error) {
+			return "hello " + p.Args["name"].(string), nil
+		},
+	})
+
+	config.Query = query
+	schema, err := NewSchema(config)
+	if err != nil {
+		t.Fatalf("NewSchema() error = %v", err)
+	}
+	return schema
+}
+
+type loaderKey struct{}
+
+func testContext(batches *int32) context.Context {
+	owners := map[string]map[string]interface{}{
+		"u1": {"id": "u1", "name": "Aminah"},
+		"u2": {"id": "u2", "name": "Farid"},
+	}
+	loader := NewLoader(func(ctx context.Context, keys []string) ([]map[string]interface{}, []error) {
+		atomic.AddInt32(batches, 1)
+		out := make([]map[string]interface{}, len(keys))
+		for i, key := range keys {
+			out[i] = owners[key]
+		}
+		return out, nil
+	}, 0)
+	return context.WithValue(context.Background(), loaderKey{}, loader)
+}
+
+func execute(t *testing.T, schema *Schema, batches *int32, params Params) (string, *Result) {
+	t.Helper()
+	result := schema.Execute(testContext(batches), params)
+	body, err := json.Marshal(result)
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+	return string(body), result
+}
+
+func TestExecute_ResolvesNestedFieldsInQueryOrder(t *testing.T) {
+	var batches int32
+	schema := testSchema(t, SchemaConfig{})
+
+	body, result := execute(t, schema, &batches, Params{Query: `
+		query List($n: Int) {
+			top: customers(limit: $n) { name id owner { name } __typename }
+			greeting
+		}`,
+		Variables: map[string]interface{}{"n": float64(2)},
+	})
+
+	want := `{"data":{"top":[` +
+		`{"name":"Butik Seri","id":"c1","owner":{"name":"Aminah"},"__typename":"Customer"},` +
+		`{"name":"Kedai Mawar","id":"c2","owner":{"name":"Farid"},"__typename":"Customer"}],` +
+		`"greeting":"hello world"}}`
+	if body != want {
+		t.Errorf("Execute() = %s\nwant %s", body, want)
+	}
+	if len(result.Errors) != 0 {
+		t.Errorf("Execute() errors = %v", result.Errors)
+	}
+	if batches != 1 {
+		t.Errorf("owner batches = %d, want 1", batches)
+	}
+}
+
+func TestExecute_FragmentsAndDirectives(t *testing.T) {
+	var batches int32
+	schema := testSchema(t, SchemaConfig{})
+
+	body, _ := execute(t, schema, &batches, Params{Query: `
+		query($withTags: Boolean!) {
+			customer(id: "c1") {
+				...Basics
+				tags @include(if: $withTags)
+				... on Customer { owner @skip(if: true) { name } }
+			}
+		}
+		fragment Basics on Customer { id name }`,
+		Variables: map[string]interface{}{"withTags": false},
+	})
+
+	want := `{"data":{"customer":{"id":"c1","name":"Butik Seri"}}}`
+	if body != want {
+		t.Errorf("Execute() = %s\nwant %s", body, want)
+	}
+}
+
+func TestExecute_ResolverErrorNullsOnlyItsField(t *testing.T) {
+	var batches int32
+	schema := testSchema(t, SchemaConfig{})
+
+	body, result := execute(t, schema, &batches, Params{Query: `{ customer(id: "c2") { name failing } }`})
+
+	if !strings.Contains(body, `"customer":{"name":"Kedai Mawar","failing":null}`) {
+		t.Errorf("Execute() = %s, want the failing field nulled", body)
+	}
+	if len(result.Errors) != 1 {
+		t.Fatalf("Execute() errors = %v, want 1", result.Errors)
+	}
+	err := result.Errors[0]
+	if err.Extensions["code"] != "FORBIDDEN" || fmt.Sprint(err.Path) != "[customer failing]" || len(err.Locations) != 1 {
+		t.Errorf("error = %+v, want FORBIDDEN at customer.failing", err)
+	}
+}
+
+func TestExecute_ValidationErrors(t *testing.T) {
+	var batches int32
+	schema := testSchema(t, SchemaConfig{})
+
+	tests := []struct {
+		name      string
+		query     string
+		variables map[string]interface{}
+		want      string
+	}{
+		{"syntax", `{ customers { name }`, nil, "Syntax Error"},
+		{"unknown field", `{ customers { email } }`, nil, `Cannot query field "email" on type "Customer"`},
+		{"unknown argument", `{ customers(first: 1) { id } }`, nil, `Unknown argument "first"`},
+		{"missing required argument", `{ customer { id } }`, nil, `argument "id" of type "ID!" is required`},
+		{"missing selection", `{ customers }`, nil, "must have a selection of subfields"},
+		{"selection on leaf", `{ greeting { x } }`, nil, "must not have a selection"},
+		{"invalid literal", `{ customers(limit: "two") { id } }`, nil, `Argument "limit" has invalid value`},
+		{"undefined variable", `{ customers(limit: $n) { id } }`, nil, `Variable "$n" is not defined`},
+		{"invalid variable", `query($n: Int) { customers(limit: $n) { id } }`, map[string]interface{}{"n": 1.5}, `Variable "$n" got invalid value`},
+		{"fragment cycle", `{ customers { ...A } } fragment A on Customer { ...A }`, nil, `Cannot spread fragment "A" within itself`},
+		{"mutation", `mutation { greeting }`, nil, `Operation type "mutation" is not supported`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, result := execute(t, schema, &batches, Params{Query: tt.query, Variables: tt.variables})
+			if result.Data != nil {
+				t.Errorf("Execute() data = %v, want none", result.Data)
+			}
+			if len(result.Errors) == 0 || !strings.Contains(result.Errors[0].Message, tt.want) {
+				t.Errorf("Execute() errors = %v, want %q", result.Errors, tt.want)
+			}
+		})
+	}
+}
+
+func TestExecute_Limits(t *testing.T) {
+	var batches int32
+
+	deep := testSchema(t, SchemaConfig{MaxDepth: 2})
+	_, result := execute(t, deep, &batches, Params{Query: `{ customers { owner { name } } }`})
+	if len(result.Errors) != 1 || !strings.Contains(result.Errors[0].Message, "depth 3 exceeds the maximum of 2") {
+		t.Errorf("Execute() errors = %v, want depth error", result.Errors)
+	}
+
+	// customers(limit: 5) costs 1 + 5 * (id + owner { name }) = 1 + 5*3 = 16
+	complex := testSchema(t, SchemaConfig{MaxComplexity: 15})
+	_, result = execute(t, complex, &batches, Params{Query: `{ customers(limit: 5) { id owner { name } } }`})
+	if len(result.Errors) != 1 || !strings.Contains(result.Errors[0].Message, "complexity 16 exceeds the maximum of 15") {
+		t.Errorf("Execute() errors = %v, want complexity error", result.Errors)
+	}
+
+	_, result = execute(t, complex, &batches, Params{Query: `{ customers(limit: 4) { id owner { name } } }`})
+	if len(result.Errors) != 0 {
+		t.Errorf("Execute() errors = %v, want none within the limit", result.Errors)
+	}
+}
+
+func TestLoader_BatchesAndCaches(t *testing.T) {
+	var calls int32
+	var sizes []int
+	loader := NewLoader(func(ctx context.Context, keys []int) ([]string, []error) {
+		atomic.AddInt32(&calls, 1)
+		sizes = append(sizes, len(keys))
+		out := make([]string, len(keys))
+		for i, key := range keys {
+			out[i] = fmt.Sprint("v", key)
+		}
+		return out, nil
+	}, 2)
+
+	ctx := context.Background()
+	thunks := []Thunk{loader.Load(ctx, 1), loader.Load(ctx, 2), loader.Load(ctx, 1), loader.Load(ctx, 3)}
+	for i, want := range []string{"v1", "v2", "v1", "v3"} {
+		value, err := thunks[i]()
+		if err != nil || value != want {
+			t.Errorf("thunk %d = %v, %v, want %s", i, value, err, want)
+		}
+	}
+	if calls != 2 || fmt.Sprint(sizes) != "[2 1]" {
+		t.Errorf("batches = %d of sizes %v, want 2 of [2 1]", calls, sizes)
+	}
+
+	if value, _ := loader.LoadValue(ctx, 2); value != "v2" || calls != 2 {
+		t.Errorf("LoadValue() = %s after %d calls, want cached v2", value, calls)
+	}
+}
+
+func TestLoader_Errors(t *testing.T) {
+	ctx := context.Background()
+
+	failing := NewLoader(func(ctx context.Context, keys []string) ([]string, []error) {
+		return nil, []error{errors.New("backend down")}
+	}, 0)
+	a, b := failing.Load(ctx, "a"), failing.Load(ctx, "b")
+	for _, thunk := range []Thunk{a, b} {
+		if _, err := thunk(); err == nil || err.Error() != "backend down" {
+			t.Errorf("thunk error = %v, want backend down", err)
+		}
+	}
+
+	partial := NewLoader(func(ctx context.Context, keys []string) ([]string, []error) {
+		return []string{"ok", ""}, []error{nil, errors.New("forbidden")}
+	}, 0)
+	a, b = partial.Load(ctx, "a"), partial.Load(ctx, "b")
+	if value, err := a(); err != nil || value != "ok" {
+		t.Errorf("thunk a = %v, %v, want ok", value, err)
+	}
+	if _, err := b(); err == nil || err.Error() != "forbidden" {
+		t.Errorf("thunk b error = %v, want forbidden", err)
+	}
+}
+
+func TestSchema_SDL(t *testing.T) {
+	schema := testSchema(t, SchemaConfig{})
+
+	sdl := schema.SDL()
+	for _, want := range []string{
+		"type Query {",
+		"  customers(limit: Int = 10): [Customer]",
+		"  customer(id: ID!): Customer",
+		"\"A customer.\"\ntype Customer {",
+		"  owner: User",
+	} {
+		if !strings.Contains(sdl, want) {
+			t.Errorf("SDL() missing %q:\n%s", want, sdl)
+		}
+	}
+}
+
+func TestHandler(t *testing.T) {
+	var batches int32
+	handler := NewHandler(testSchema(t, SchemaConfig{}))
+	handler.Prepare = func(r *http.Request) *http.Request {
+		return r.WithContext(testContext(&batches))
+	}
+
+	tests := []struct {
+		name       string
+		req        *http.Request
+		wantStatus int
+		wantBody   string
+	}{
+		{
+			name:       "post json",
+			req:        httptest.NewRequest(http.MethodPost, "/graphql", strings.NewReader(`{"query":"query($n: String) { greeting(name: $n) }","variables":{"n":"Siti"}}`)),
+			wantStatus: http.StatusOK,
+			wantBody:   `{"data":{"greeting":"hello Siti"}}`,
+		},
+		{
+			name:       "get",
+			req:        httptest.NewRequest(http.MethodGet, "/graphql?query=%7Bgreeting%7D", nil),
+			wantStatus: http.StatusOK,
+			wantBody:   `{"data":{"greeting":"hello world"}}`,
+		},
+		{
+			name:       "missing query",
+			req:        httptest.NewRequest(http.MethodPost, "/graphql", strings.NewReader(`{}`)),
+			wantStatus: http.StatusBadRequest,
+			wantBody:   `{"errors":[{"message":"query is required"}]}`,
+		},
+		{
+			name:       "method",
+			req:        httptest.NewRequest(http.MethodPut, "/graphql", nil),
+			wantStatus: http.StatusMethodNotAllowed,
+			wantBody:   `{"errors":[{"message":"only GET and POST are supported"}]}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, tt.req)
+			if rec.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d", rec.Code, tt.wantStatus)
+			}
+			if got := strings.TrimSpace(rec.Body.String()); got != tt.wantBody {
+				t.Errorf("body = %s, want %s", got, tt.wantBody)
+			}
+		})
+	}
+}
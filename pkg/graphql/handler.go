@@ -0,0 +1,88 @@
+package graphql
+
+import (
+	"encoding/json"
+	"io"
+	"mime"
+	"net/http"
+)
+
+// maxRequestBytes bounds the size of a GraphQL request body.
+const maxRequestBytes = 1 << 20
+
+// Handler serves GraphQL requests over HTTP. It accepts POST requests with a
+// JSON body or an application/graphql query, and GET requests with query
+// parameters.
+type Handler struct {
+	schema *Schema
+	// Prepare, when set, derives the execution context from the request, e.g.
+	// to attach per-request dataloaders.
+	Prepare func(r *http.Request) *http.Request
+}
+
+// NewHandler creates a new GraphQL HTTP handler.
+func NewHandler(schema *Schema) *Handler {
+	return &Handler{schema: schema}
+}
+
+// ServeHTTP implements http.Handler.
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	params, status, message := h.readParams(w, r)
+	if status != 0 {
+		if status == http.StatusMethodNotAllowed {
+			w.Header().Set("Allow", "GET, POST")
+		}
+		writeResult(w, status, &Result{Errors: []*Error{{Message: message}}})
+		return
+	}
+
+	if h.Prepare != nil {
+		r = h.Prepare(r)
+	}
+
+	writeResult(w, http.StatusOK, h.schema.Execute(r.Context(), params))
+}
+
+func (h *Handler) readParams(w http.ResponseWriter, r *http.Request) (Params, int, string) {
+	var params Params
+
+	switch r.Method {
+	case http.MethodGet:
+		query := r.URL.Query()
+		params.Query = query.Get("query")
+		params.OperationName = query.Get("operationName")
+		if variables := query.Get("variables"); variables != "" {
+			if err := json.Unmarshal([]byte(variables), &params.Variables); err != nil {
+				return params, http.StatusBadRequest, "variables must be a JSON object"
+			}
+		}
+
+	case http.MethodPost:
+		body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxRequestBytes))
+		if err != nil {
+			return params, http.StatusRequestEntityTooLarge, "request body is too large"
+		}
+		mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+		if mediaType == "application/graphql" {
+			params.Query = string(body)
+			break
+		}
+		if err := json.Unmarshal(body, &params); err != nil {
+			return params, http.StatusBadRequest, "request body must be a JSON object with a query"
+		}
+
+	default:
+		return params, http.StatusMethodNotAllowed, "only GET and POST are supported"
+	}
+
+	if params.Query == "" {
+		return params, http.StatusBadRequest, "query is required"
+	}
+	return params, 0, ""
+}
+
+func writeResult(w http.ResponseWriter, status int, result *Result) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(result)
+}
@@ -0,0 +1,132 @@
+package graphql
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+// BatchFunc fetches the values for a batch of keys. Values and errors are
+// positional: values[i] and errs[i] belong to keys[i]. A single error fails
+// every key in the batch, and nil errs means every key succeeded.
+type BatchFunc[K comparable, V any] func(ctx context.Context, keys []K) ([]V, []error)
+
+// Loader batches and caches lookups by key. Load queues a key and returns a
+// Thunk; the queued keys are fetched in one call when the first of their
+// thunks is forced. Since the executor forces the thunks of a whole depth
+// together, a list of N objects resolves a field through one batch instead
+// of N calls.
+//
+// A Loader caches every result, errors included, for its lifetime, so it
+// should be created per request.
+type Loader[K comparable, V any] struct {
+	fetch    BatchFunc[K, V]
+	maxBatch int
+
+	mu      sync.Mutex
+	cache   map[K]*loaderResult[V]
+	pending *loaderBatch[K, V]
+}
+
+type loaderResult[V any] struct {
+	batch interface{ dispatch() }
+	done  chan struct{}
+	value V
+	err   error
+}
+
+type loaderBatch[K comparable, V any] struct {
+	loader  *Loader[K, V]
+	ctx     context.Context
+	keys    []K
+	results map[K]*loaderResult[V]
+	once    sync.Once
+}
+
+// NewLoader creates a loader. maxBatch bounds the keys passed to one fetch
+// call; 0 means unbounded.
+func NewLoader[K comparable, V any](fetch BatchFunc[K, V], maxBatch int) *Loader[K, V] {
+	return &Loader[K, V]{
+		fetch:    fetch,
+		maxBatch: maxBatch,
+		cache:    make(map[K]*loaderResult[V]),
+	}
+}
+
+// Load queues a key and returns a thunk for its value.
+func (l *Loader[K, V]) Load(ctx context.Context, key K) Thunk {
+	result := l.enqueue(ctx, key)
+	return func() (interface{}, error) {
+		result.batch.dispatch()
+		<-result.done
+		return result.value, result.err
+	}
+}
+
+// LoadValue loads a key and waits for its value.
+func (l *Loader[K, V]) LoadValue(ctx context.Context, key K) (V, error) {
+	result := l.enqueue(ctx, key)
+	result.batch.dispatch()
+	<-result.done
+	return result.value, result.err
+}
+
+func (l *Loader[K, V]) enqueue(ctx context.Context, key K) *loaderResult[V] {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if result, ok := l.cache[key]; ok {
+		return result
+	}
+
+	if l.pending == nil {
+		l.pending = &loaderBatch[K, V]{loader: l, ctx: ctx, results: make(map[K]*loaderResult[V])}
+	}
+	batch := l.pending
+
+	result := &loaderResult[V]{batch: batch, done: make(chan struct{})}
+	batch.keys = append(batch.keys, key)
+	batch.results[key] = result
+	l.cache[key] = result
+
+	if l.maxBatch > 0 && len(batch.keys) >= l.maxBatch {
+		l.pending = nil
+	}
+	return result
+}
+
+// dispatch fetches the batch once; later calls are no-ops.
+func (b *loaderBatch[K, V]) dispatch() {
+	b.once.Do(func() {
+		b.loader.mu.Lock()
+		if b.loader.pending == b {
+			b.loader.pending = nil
+		}
+		b.loader.mu.Unlock()
+
+		values, errs := b.fetch()
+		for i, key := range b.keys {
+			result := b.results[key]
+			switch {
+			case i < len(errs) && errs[i] != nil:
+				result.err = errs[i]
+			case len(errs) == 1 && errs[0] != nil:
+				result.err = errs[0]
+			case i < len(values):
+				result.value = values[i]
+			default:
+				result.err = fmt.Errorf("batch returned no value for key %v", key)
+			}
+			close(result.done)
+		}
+	})
+}
+
+func (b *loaderBatch[K, V]) fetch() (values []V, errs []error) {
+	defer func() {
+		if r := recover(); r != nil {
+			values, errs = nil, []error{NewError("INTERNAL", "internal error loading batch")}
+		}
+	}()
+	return b.loader.fetch(b.ctx, b.keys)
+}
@@ -0,0 +1,468 @@
+// Package graphql provides a small GraphQL query engine: a parser, a schema
+// built from Go resolvers, a breadth-first executor that lets resolvers batch
+// through dataloaders, depth and complexity limits, and an HTTP handler.
+//
+// Only query operations over object, list and scalar types are supported.
+// Clients discover the schema through its SDL rather than introspection.
+package graphql
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// ============================================================================
+// Errors
+// ============================================================================
+
+// Error is a GraphQL error as returned to clients.
+type Error struct {
+	Message    string                 `json:"message"`
+	Locations  []Location             `json:"locations,omitempty"`
+	Path       []interface{}          `json:"path,omitempty"`
+	Extensions map[string]interface{} `json:"extensions,omitempty"`
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+// NewError creates an error with an extensions code, for resolvers that
+// want clients to be able to tell failures apart.
+func NewError(code, message string) *Error {
+	return &Error{Message: message, Extensions: map[string]interface{}{"code": code}}
+}
+
+// ============================================================================
+// Types
+// ============================================================================
+
+// Type is a GraphQL type: *Scalar, *Object, *List or *NonNull.
+type Type interface {
+	String() string
+}
+
+// Scalar is a leaf type.
+type Scalar struct {
+	Name        string
+	Description string
+	// Serialize converts a resolved value to its JSON representation.
+	Serialize func(value interface{}) (interface{}, error)
+	// ParseValue converts a JSON variable or literal value to a Go value.
+	ParseValue func(value interface{}) (interface{}, error)
+}
+
+func (s *Scalar) String() string { return s.Name }
+
+// List is a list of another type.
+type List struct {
+	OfType Type
+}
+
+func (l *List) String() string { return "[" + l.OfType.String() + "]" }
+
+// NonNull marks an argument type as required. Output fields are always
+// nullable so that a failing resolver only nulls its own field.
+type NonNull struct {
+	OfType Type
+}
+
+func (n *NonNull) String() string { return n.OfType.String() + "!" }
+
+// NewList wraps a type in a list.
+func NewList(of Type) *List { return &List{OfType: of} }
+
+// NewNonNull wraps an argument type as required.
+func NewNonNull(of Type) *NonNull { return &NonNull{OfType: of} }
+
+// Object is an object type with an ordered set of fields.
+type Object struct {
+	Name        string
+	Description string
+	fields      map[string]*FieldDef
+	order       []string
+}
+
+// NewObject creates an object type.
+func NewObject(name, description string) *Object {
+	return &Object{Name: name, Description: description, fields: make(map[string]*FieldDef)}
+}
+
+func (o *Object) String() string { return o.Name }
+
+// AddField adds a field to the object, replacing any field of the same name.
+// It returns the object so fields can be chained.
+func (o *Object) AddField(name string, field *FieldDef) *Object {
+	if _, ok := o.fields[name]; !ok {
+		o.order = append(o.order, name)
+	}
+	o.fields[name] = field
+	return o
+}
+
+// Field returns a field definition by name.
+func (o *Object) Field(name string) (*FieldDef, bool) {
+	field, ok := o.fields[name]
+	return field, ok
+}
+
+// FieldDef defines a field of an object type.
+type FieldDef struct {
+	Description string
+	Type        Type
+	Args        []*ArgDef
+	// Resolve computes the field value. When nil the value is looked up by
+	// field name in a map[string]interface{} source.
+	Resolve ResolveFunc
+	// Complexity returns the cost of the field given its arguments and the
+	// summed cost of its selections. When nil the cost is 1 plus the
+	// selections' cost.
+	Complexity func(args map[string]interface{}, childComplexity int) int
+}
+
+// ArgDef defines a field argument.
+type ArgDef struct {
+	Name         string
+	Description  string
+	Type         Type
+	DefaultValue interface{}
+}
+
+// ResolveFunc resolves a field. It may return a Thunk to defer the work until
+// all fields at the same depth have been resolved, which is what allows a
+// dataloader to batch their lookups.
+type ResolveFunc func(p ResolveParams) (interface{}, error)
+
+// Thunk is a deferred field value.
+type Thunk func() (interface{}, error)
+
+// ResolveParams are passed to a ResolveFunc.
+type ResolveParams struct {
+	Context context.Context
+	// Source is the resolved value of the parent object; nil for root fields.
+	Source interface{}
+	// Args are the coerced field arguments, with defaults applied.
+	Args map[string]interface{}
+	// FieldName is the schema name of the field being resolved.
+	FieldName string
+	// Path is the response path of the field.
+	Path []interface{}
+}
+
+// ============================================================================
+// Schema
+// ============================================================================
+
+// SchemaConfig configures a schema.
+type SchemaConfig struct {
+	Query *Object
+	// MaxDepth bounds how deeply fields may be nested; 0 means unlimited.
+	MaxDepth int
+	// MaxComplexity bounds the summed field complexity; 0 means unlimited.
+	MaxComplexity int
+}
+
+// Schema is an executable schema.
+type Schema struct {
+	query         *Object
+	maxDepth      int
+	maxComplexity int
+	types         []Type
+	scalars       map[string]*Scalar
+}
+
+// NewSchema validates the type graph reachable from the query type and
+// builds a schema.
+func NewSchema(config SchemaConfig) (*Schema, error) {
+	if config.Query == nil {
+		return nil, fmt.Errorf("graphql: schema must have a query type")
+	}
+
+	s := &Schema{
+		query:         config.Query,
+		maxDepth:      config.MaxDepth,
+		maxComplexity: config.MaxComplexity,
+		scalars:       make(map[string]*Scalar),
+	}
+
+	seen := make(map[string]Type)
+	var visit func(t Type) error
+	visit = func(t Type) error {
+		switch t := t.(type) {
+		case *List:
+			return visit(t.OfType)
+		case *NonNull:
+			return fmt.Errorf("graphql: non-null %s is only supported on arguments", t)
+		case *Scalar:
+			if prev, ok := seen[t.Name]; ok && prev != Type(t) {
+				return fmt.Errorf("graphql: duplicate type %s", t.Name)
+			}
+			seen[t.Name] = t
+			s.scalars[t.Name] = t
+			return nil
+		case *Object:
+			if prev, ok := seen[t.Name]; ok {
+				if prev != Type(t) {
+					return fmt.Errorf("graphql: duplicate type %s", t.Name)
+				}
+				return nil
+			}
+			seen[t.Name] = t
+			s.types = append(s.types, t)
+			for _, name := range t.order {
+				field := t.fields[name]
+				if field.Type == nil {
+					return fmt.Errorf("graphql: field %s.%s has no type", t.Name, name)
+				}
+				for _, arg := range field.Args {
+					if !isInputType(arg.Type) {
+						return fmt.Errorf("graphql: argument %s.%s(%s) must be a scalar or list of scalars", t.Name, name, arg.Name)
+					}
+					if scalar, ok := namedType(arg.Type).(*Scalar); ok {
+						s.scalars[scalar.Name] = scalar
+					}
+				}
+				if err := visit(field.Type); err != nil {
+					return err
+				}
+			}
+			return nil
+		case nil:
+			return fmt.Errorf("graphql: nil type")
+		}
+		return fmt.Errorf("graphql: unsupported type %T", t)
+	}
+	if err := visit(config.Query); err != nil {
+		return nil, err
+	}
+
+	return s, nil
+}
+
+// QueryType returns the root query type.
+func (s *Schema) QueryType() *Object {
+	return s.query
+}
+
+func isInputType(t Type) bool {
+	switch t := t.(type) {
+	case *Scalar:
+		return true
+	case *List:
+		return isInputType(t.OfType)
+	case *NonNull:
+		return isInputType(t.OfType)
+	}
+	return false
+}
+
+// namedType strips list and non-null wrappers.
+func namedType(t Type) Type {
+	for {
+		switch wrapped := t.(type) {
+		case *List:
+			t = wrapped.OfType
+		case *NonNull:
+			t = wrapped.OfType
+		default:
+			return t
+		}
+	}
+}
+
+// SDL renders the schema in the GraphQL schema definition language.
+func (s *Schema) SDL() string {
+	var b strings.Builder
+	for i, t := range s.types {
+		if i > 0 {
+			b.WriteString("\n")
+		}
+		object := t.(*Object)
+		writeDescription(&b, object.Description, "")
+		fmt.Fprintf(&b, "type %s {\n", object.Name)
+		for _, name := range object.order {
+			field := object.fields[name]
+			writeDescription(&b, field.Description, "  ")
+			b.WriteString("  " + name)
+			if len(field.Args) > 0 {
+				args := make([]string, len(field.Args))
+				for j, arg := range field.Args {
+					args[j] = arg.Name + ": " + arg.Type.String()
+					if arg.DefaultValue != nil {
+						args[j] += " = " + formatDefault(arg.DefaultValue)
+					}
+				}
+				b.WriteString("(" + strings.Join(args, ", ") + ")")
+			}
+			b.WriteString(": " + field.Type.String() + "\n")
+		}
+		b.WriteString("}\n")
+	}
+	if s.query.Name != "Query" {
+		fmt.Fprintf(&b, "\nschema {\n  query: %s\n}\n", s.query.Name)
+	}
+	return b.String()
+}
+
+func writeDescription(b *strings.Builder, description, indent string) {
+	if description == "" {
+		return
+	}
+	if strings.Contains(description, "\n") {
+		fmt.Fprintf(b, "%s\"\"\"\n%s%s\n%s\"\"\"\n", indent, indent, strings.ReplaceAll(description, "\n", "\n"+indent), indent)
+		return
+	}
+	fmt.Fprintf(b, "%s%s\n", indent, strconv.Quote(description))
+}
+
+func formatDefault(value interface{}) string {
+	switch v := value.(type) {
+	case string:
+		return strconv.Quote(v)
+	case []interface{}:
+		items := make([]string, len(v))
+		for i, item := range v {
+			items[i] = formatDefault(item)
+		}
+		return "[" + strings.Join(items, ", ") + "]"
+	}
+	return fmt.Sprint(value)
+}
+
+// ============================================================================
+// Built-in Scalars
+// ============================================================================
+
+var (
+	// Int is a signed 32-bit integer.
+	Int = &Scalar{
+		Name:        "Int",
+		Description: "A signed 32-bit integer.",
+		Serialize:   coerceInt,
+		ParseValue:  coerceInt,
+	}
+
+	// Float is a double-precision floating point value.
+	Float = &Scalar{
+		Name:        "Float",
+		Description: "A double-precision floating point value.",
+		Serialize:   coerceFloat,
+		ParseValue:  coerceFloat,
+	}
+
+	// String is a UTF-8 character sequence.
+	String = &Scalar{
+		Name:        "String",
+		Description: "A UTF-8 character sequence.",
+		Serialize: func(value interface{}) (interface{}, error) {
+			switch v := value.(type) {
+			case string:
+				return v, nil
+			case fmt.Stringer:
+				return v.String(), nil
+			case bool, int, int32, int64, float32, float64:
+				return fmt.Sprint(v), nil
+			}
+			return nil, fmt.Errorf("String cannot represent value: %v", value)
+		},
+		ParseValue: func(value interface{}) (interface{}, error) {
+			if v, ok := value.(string); ok {
+				return v, nil
+			}
+			return nil, fmt.Errorf("String cannot represent a non string value: %v", value)
+		},
+	}
+
+	// Boolean is true or false.
+	Boolean = &Scalar{
+		Name:        "Boolean",
+		Description: "true or false.",
+		Serialize: func(value interface{}) (interface{}, error) {
+			if v, ok := value.(bool); ok {
+				return v, nil
+			}
+			return nil, fmt.Errorf("Boolean cannot represent a non boolean value: %v", value)
+		},
+		ParseValue: func(value interface{}) (interface{}, error) {
+			if v, ok := value.(bool); ok {
+				return v, nil
+			}
+			return nil, fmt.Errorf("Boolean cannot represent a non boolean value: %v", value)
+		},
+	}
+
+	// ID is an opaque identifier, serialized as a string.
+	ID = &Scalar{
+		Name:        "ID",
+		Description: "An opaque unique identifier.",
+		Serialize:   coerceID,
+		ParseValue:  coerceID,
+	}
+)
+
+func coerceInt(value interface{}) (interface{}, error) {
+	var f float64
+	switch v := value.(type) {
+	case int:
+		f = float64(v)
+	case int32:
+		return int(v), nil
+	case int64:
+		f = float64(v)
+	case float64:
+		f = v
+	case float32:
+		f = float64(v)
+	default:
+		return nil, fmt.Errorf("Int cannot represent non-integer value: %v", value)
+	}
+	if f != math.Trunc(f) || f > math.MaxInt32 || f < math.MinInt32 {
+		return nil, fmt.Errorf("Int cannot represent non 32-bit signed integer value: %v", value)
+	}
+	return int(f), nil
+}
+
+func coerceFloat(value interface{}) (interface{}, error) {
+	switch v := value.(type) {
+	case float64:
+		return v, nil
+	case float32:
+		return float64(v), nil
+	case int:
+		return float64(v), nil
+	case int32:
+		return float64(v), nil
+	case int64:
+		return float64(v), nil
+	}
+	return nil, fmt.Errorf("Float cannot represent non numeric value: %v", value)
+}
+
+func coerceID(value interface{}) (interface{}, error) {
+	switch v := value.(type) {
+	case string:
+		return v, nil
+	case fmt.Stringer:
+		return v.String(), nil
+	case int, int32, int64:
+		return fmt.Sprint(v), nil
+	case float64:
+		if v == math.Trunc(v) {
+			return strconv.FormatFloat(v, 'f', -1, 64), nil
+		}
+	}
+	return nil, fmt.Errorf("ID cannot represent value: %v", value)
+}
+
+// builtinScalars are the scalars a variable definition may always name, even
+// when the schema does not use them.
+var builtinScalars = map[string]*Scalar{
+	"Int":     Int,
+	"Float":   Float,
+	"String":  String,
+	"Boolean": Boolean,
+	"ID":      ID,
+}
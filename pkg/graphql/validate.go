@@ -0,0 +1,438 @@
+package graphql
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// ============================================================================
+// Operation Selection and Variables
+// ============================================================================
+
+func selectOperation(doc *Document, name string) (*Operation, *Error) {
+	if name == "" {
+		if len(doc.Operations) > 1 {
+			return nil, &Error{Message: "Must provide operation name if query contains multiple operations."}
+		}
+		return doc.Operations[0], nil
+	}
+	for _, op := range doc.Operations {
+		if op.Name == name {
+			return op, nil
+		}
+	}
+	return nil, &Error{Message: fmt.Sprintf("Unknown operation named %q.", name)}
+}
+
+// coerceVariables validates the provided variable values against the
+// operation's variable definitions and applies defaults.
+func (s *Schema) coerceVariables(op *Operation, provided map[string]interface{}) (map[string]interface{}, map[string]bool, []*Error) {
+	values := make(map[string]interface{})
+	declared := make(map[string]bool)
+	var errs []*Error
+
+	for _, def := range op.Variables {
+		declared[def.Name] = true
+
+		t, err := s.typeFromRef(def.Type)
+		if err != nil {
+			errs = append(errs, &Error{Message: err.Error(), Locations: []Location{def.Loc}})
+			continue
+		}
+
+		value, ok := provided[def.Name]
+		if !ok {
+			if def.Default != nil {
+				value, err := coerceLiteral(def.Default, t, nil)
+				if err != nil {
+					errs = append(errs, &Error{Message: fmt.Sprintf("Variable \"$%s\" has invalid default value: %s", def.Name, err), Locations: []Location{def.Loc}})
+					continue
+				}
+				values[def.Name] = value
+			} else if def.Type.NonNull {
+				errs = append(errs, &Error{Message: fmt.Sprintf("Variable \"$%s\" of required type \"%s\" was not provided.", def.Name, def.Type), Locations: []Location{def.Loc}})
+			}
+			continue
+		}
+
+		coerced, err := coerceInput(value, t)
+		if err != nil {
+			errs = append(errs, &Error{Message: fmt.Sprintf("Variable \"$%s\" got invalid value: %s", def.Name, err), Locations: []Location{def.Loc}})
+			continue
+		}
+		values[def.Name] = coerced
+	}
+
+	return values, declared, errs
+}
+
+func (s *Schema) typeFromRef(ref *TypeRef) (Type, error) {
+	var t Type
+	if ref.Elem != nil {
+		elem, err := s.typeFromRef(ref.Elem)
+		if err != nil {
+			return nil, err
+		}
+		t = NewList(elem)
+	} else {
+		scalar, ok := s.scalars[ref.Name]
+		if !ok {
+			scalar, ok = builtinScalars[ref.Name]
+		}
+		if !ok {
+			return nil, fmt.Errorf("Unknown type %q.", ref.Name)
+		}
+		t = scalar
+	}
+	if ref.NonNull {
+		t = NewNonNull(t)
+	}
+	return t, nil
+}
+
+// coerceInput coerces a JSON variable value to an input type.
+func coerceInput(value interface{}, t Type) (interface{}, error) {
+	if nonNull, ok := t.(*NonNull); ok {
+		if value == nil {
+			return nil, fmt.Errorf("expected non-null value of type %s", t)
+		}
+		return coerceInput(value, nonNull.OfType)
+	}
+	if value == nil {
+		return nil, nil
+	}
+
+	switch t := t.(type) {
+	case *List:
+		items, ok := value.([]interface{})
+		if !ok {
+			item, err := coerceInput(value, t.OfType)
+			if err != nil {
+				return nil, err
+			}
+			return []interface{}{item}, nil
+		}
+		out := make([]interface{}, len(items))
+		for i, item := range items {
+			coerced, err := coerceInput(item, t.OfType)
+			if err != nil {
+				return nil, err
+			}
+			out[i] = coerced
+		}
+		return out, nil
+	case *Scalar:
+		return t.ParseValue(value)
+	}
+	return nil, fmt.Errorf("unsupported input type %s", t)
+}
+
+// coerceLiteral coerces a literal from the query text to an input type.
+func coerceLiteral(value *Value, t Type, variables map[string]interface{}) (interface{}, error) {
+	if value.Kind == VariableValue {
+		return coerceInput(variables[value.Raw], t)
+	}
+
+	if nonNull, ok := t.(*NonNull); ok {
+		if value.Kind == NullValue {
+			return nil, fmt.Errorf("expected non-null value of type %s", t)
+		}
+		return coerceLiteral(value, nonNull.OfType, variables)
+	}
+	if value.Kind == NullValue {
+		return nil, nil
+	}
+
+	switch t := t.(type) {
+	case *List:
+		if value.Kind != ListValue {
+			item, err := coerceLiteral(value, t.OfType, variables)
+			if err != nil {
+				return nil, err
+			}
+			return []interface{}{item}, nil
+		}
+		out := make([]interface{}, len(value.List))
+		for i, item := range value.List {
+			coerced, err := coerceLiteral(item, t.OfType, variables)
+			if err != nil {
+				return nil, err
+			}
+			out[i] = coerced
+		}
+		return out, nil
+	case *Scalar:
+		var raw interface{}
+		switch value.Kind {
+		case IntValue:
+			n, err := strconv.ParseInt(value.Raw, 10, 64)
+			if err != nil {
+				return nil, fmt.Errorf("%s cannot represent value: %s", t.Name, value.Raw)
+			}
+			raw = int(n)
+		case FloatValue:
+			f, err := strconv.ParseFloat(value.Raw, 64)
+			if err != nil {
+				return nil, fmt.Errorf("%s cannot represent value: %s", t.Name, value.Raw)
+			}
+			raw = f
+		case StringValue:
+			raw = value.Raw
+		case BooleanValue:
+			raw = value.Raw == "true"
+		default:
+			return nil, fmt.Errorf("%s cannot represent value: %s", t.Name, value.Raw)
+		}
+		return t.ParseValue(raw)
+	}
+	return nil, fmt.Errorf("unsupported input type %s", t)
+}
+
+// ============================================================================
+// Validation
+// ============================================================================
+
+// validator checks an operation against the schema before anything is
+// resolved, coercing every field's arguments along the way, and measures the
+// operation's depth and complexity.
+type validator struct {
+	schema    *Schema
+	doc       *Document
+	variables map[string]interface{}
+	declared  map[string]bool
+	args      map[*Field]map[string]interface{}
+	spreading map[string]bool
+	errors    []*Error
+}
+
+func (v *validator) errorf(loc Location, format string, args ...interface{}) {
+	v.errors = append(v.errors, &Error{Message: fmt.Sprintf(format, args...), Locations: []Location{loc}})
+}
+
+func (v *validator) validate(op *Operation) {
+	v.args = make(map[*Field]map[string]interface{})
+	v.spreading = make(map[string]bool)
+
+	depth, complexity := v.selections(v.schema.query, op.SelectionSet)
+	if len(v.errors) > 0 {
+		return
+	}
+	if v.schema.maxDepth > 0 && depth > v.schema.maxDepth {
+		v.errorf(op.Loc, "Query depth %d exceeds the maximum of %d.", depth, v.schema.maxDepth)
+	}
+	if v.schema.maxComplexity > 0 && complexity > v.schema.maxComplexity {
+		v.errorf(op.Loc, "Query complexity %d exceeds the maximum of %d.", complexity, v.schema.maxComplexity)
+	}
+}
+
+// selections validates a selection set and returns its depth and the
+// complexity of the fields it would resolve.
+func (v *validator) selections(parent *Object, selections []Selection) (depth, complexity int) {
+	for _, selection := range selections {
+		switch sel := selection.(type) {
+		case *Field:
+			d, c := v.field(parent, sel)
+			if v.include(sel.Directives) {
+				depth = max(depth, d)
+				complexity += c
+			}
+
+		case *FragmentSpread:
+			include := v.include(sel.Directives)
+			fragment, ok := v.doc.Fragments[sel.Name]
+			if !ok {
+				v.errorf(sel.Loc, "Unknown fragment %q.", sel.Name)
+				continue
+			}
+			if v.spreading[sel.Name] {
+				v.errorf(sel.Loc, "Cannot spread fragment %q within itself.", sel.Name)
+				continue
+			}
+			if !v.typeCondition(parent, fragment.TypeCondition, sel.Loc) {
+				continue
+			}
+			v.spreading[sel.Name] = true
+			d, c := v.selections(parent, fragment.SelectionSet)
+			delete(v.spreading, sel.Name)
+			if include {
+				depth = max(depth, d)
+				complexity += c
+			}
+
+		case *InlineFragment:
+			include := v.include(sel.Directives)
+			if !v.typeCondition(parent, sel.TypeCondition, sel.Loc) {
+				continue
+			}
+			d, c := v.selections(parent, sel.SelectionSet)
+			if include {
+				depth = max(depth, d)
+				complexity += c
+			}
+		}
+	}
+	return depth, complexity
+}
+
+func (v *validator) field(parent *Object, field *Field) (depth, complexity int) {
+	if field.Name == "__typename" {
+		if len(field.Arguments) > 0 || len(field.SelectionSet) > 0 {
+			v.errorf(field.Loc, "Field \"__typename\" takes no arguments or selections.")
+		}
+		return 1, 0
+	}
+
+	def, ok := parent.Field(field.Name)
+	if !ok {
+		v.errorf(field.Loc, "Cannot query field %q on type %q.", field.Name, parent.Name)
+		return 0, 0
+	}
+
+	args := v.arguments(parent, field, def)
+
+	var childDepth, childComplexity int
+	if object, ok := namedType(def.Type).(*Object); ok {
+		if len(field.SelectionSet) == 0 {
+			v.errorf(field.Loc, "Field %q of type %q must have a selection of subfields.", field.Name, def.Type)
+			return 0, 0
+		}
+		childDepth, childComplexity = v.selections(object, field.SelectionSet)
+	} else if len(field.SelectionSet) > 0 {
+		v.errorf(field.Loc, "Field %q must not have a selection since type %q has no subfields.", field.Name, def.Type)
+		return 0, 0
+	}
+
+	complexity = 1 + childComplexity
+	if def.Complexity != nil && args != nil {
+		complexity = def.Complexity(args, childComplexity)
+	}
+	return 1 + childDepth, complexity
+}
+
+// arguments validates and coerces a field's arguments. It returns nil if any
+// argument is invalid.
+func (v *validator) arguments(parent *Object, field *Field, def *FieldDef) map[string]interface{} {
+	before := len(v.errors)
+
+	given := make(map[string]*Argument, len(field.Arguments))
+	for _, arg := range field.Arguments {
+		if _, dup := given[arg.Name]; dup {
+			v.errorf(arg.Loc, "There can be only one argument named %q.", arg.Name)
+			continue
+		}
+		given[arg.Name] = arg
+		known := false
+		for _, argDef := range def.Args {
+			if argDef.Name == arg.Name {
+				known = true
+				break
+			}
+		}
+		if !known {
+			v.errorf(arg.Loc, "Unknown argument %q on field \"%s.%s\".", arg.Name, parent.Name, field.Name)
+		}
+		v.checkVariables(arg.Value)
+	}
+
+	args := make(map[string]interface{}, len(def.Args))
+	for _, argDef := range def.Args {
+		arg, ok := given[argDef.Name]
+		present := ok
+		if ok && arg.Value.Kind == VariableValue {
+			_, present = v.variables[arg.Value.Raw]
+		}
+
+		var value interface{}
+		if present {
+			coerced, err := coerceLiteral(arg.Value, argDef.Type, v.variables)
+			if err != nil {
+				v.errorf(arg.Loc, "Argument %q has invalid value: %s", argDef.Name, err)
+				continue
+			}
+			value = coerced
+		} else if argDef.DefaultValue != nil {
+			value, present = argDef.DefaultValue, true
+		}
+
+		if value == nil {
+			if _, required := argDef.Type.(*NonNull); required {
+				v.errorf(field.Loc, "Field %q argument %q of type %q is required, but it was not provided.", field.Name, argDef.Name, argDef.Type)
+				continue
+			}
+		}
+		if present {
+			args[argDef.Name] = value
+		}
+	}
+
+	if len(v.errors) > before {
+		return nil
+	}
+	v.args[field] = args
+	return args
+}
+
+// checkVariables reports variables used in a value but not declared by the
+// operation.
+func (v *validator) checkVariables(value *Value) {
+	switch value.Kind {
+	case VariableValue:
+		if !v.declared[value.Raw] {
+			v.errorf(value.Loc, "Variable \"$%s\" is not defined.", value.Raw)
+		}
+	case ListValue:
+		for _, item := range value.List {
+			v.checkVariables(item)
+		}
+	case ObjectValue:
+		for _, field := range value.Fields {
+			v.checkVariables(field.Value)
+		}
+	}
+}
+
+func (v *validator) typeCondition(parent *Object, condition string, loc Location) bool {
+	if condition == "" || condition == parent.Name {
+		return true
+	}
+	v.errorf(loc, "Fragment cannot be spread here as objects of type %q can never be of type %q.", parent.Name, condition)
+	return false
+}
+
+// include evaluates the @skip and @include directives.
+func (v *validator) include(directives []*Directive) bool {
+	include, err := shouldInclude(directives, v.variables)
+	if err != nil {
+		loc := Location{}
+		if len(directives) > 0 {
+			loc = directives[0].Loc
+		}
+		v.errorf(loc, "%s", err)
+		return false
+	}
+	for _, directive := range directives {
+		for _, arg := range directive.Arguments {
+			v.checkVariables(arg.Value)
+		}
+	}
+	return include
+}
+
+func shouldInclude(directives []*Directive, variables map[string]interface{}) (bool, error) {
+	for _, directive := range directives {
+		if directive.Name != "skip" && directive.Name != "include" {
+			return false, fmt.Errorf("Unknown directive \"@%s\".", directive.Name)
+		}
+		if len(directive.Arguments) != 1 || directive.Arguments[0].Name != "if" {
+			return false, fmt.Errorf("Directive \"@%s\" requires a single \"if\" argument.", directive.Name)
+		}
+		value, err := coerceLiteral(directive.Arguments[0].Value, NewNonNull(Boolean), variables)
+		if err != nil {
+			return false, fmt.Errorf("Directive \"@%s\" argument \"if\" has invalid value: %s", directive.Name, err)
+		}
+		if value.(bool) == (directive.Name == "skip") {
+			return false, nil
+		}
+	}
+	return true, nil
+}
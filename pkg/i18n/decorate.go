@@ -0,0 +1,179 @@
+package i18n
+
+import (
+	"bytes"
+	"encoding/json"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// LabelFields maps the JSON fields that hold labelled values to their kind.
+// A field named "parent.field" only matches field inside an object held by
+// parent, or by each item of a list held by parent, e.g. "stage.type".
+type LabelFields map[string]string
+
+// DefaultLabelFields are the labelled fields of the API's responses.
+var DefaultLabelFields = LabelFields{
+	"status":         KindStatus,
+	"renewal_status": KindStatus,
+	"priority":       KindPriority,
+	"stage_type":     KindStageType,
+	"stage.type":     KindStageType,
+	"stages.type":    KindStageType,
+}
+
+// maxDecoratedBodySize is the largest response body labels are added to.
+// Larger bodies are passed through unchanged.
+const maxDecoratedBodySize = 4 << 20
+
+// Decorate adds a "<field>_label" with the value's label in locale next to
+// every labelled field of a JSON body, at any depth. Fields that already have
+// a label, and values without one, are left as they are. Bodies that are not
+// JSON are returned unchanged.
+func (f LabelFields) Decorate(body []byte, locale string) []byte {
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+
+	var payload interface{}
+	if err := decoder.Decode(&payload); err != nil {
+		return body
+	}
+	if !f.decorate(payload, "", locale) {
+		return body
+	}
+
+	decorated, err := json.Marshal(payload)
+	if err != nil {
+		return body
+	}
+	return decorated
+}
+
+// decorate labels value in place, reporting whether it added any label.
+func (f LabelFields) decorate(value interface{}, parent, locale string) bool {
+	changed := false
+	switch v := value.(type) {
+	case map[string]interface{}:
+		added := make(map[string]string)
+		for key, field := range v {
+			if s, ok := field.(string); ok {
+				kind, ok := f[key]
+				if !ok {
+					kind, ok = f[parent+"."+key]
+				}
+				if !ok {
+					continue
+				}
+				if _, exists := v[key+"_label"]; exists {
+					continue
+				}
+				if l := Label(locale, kind, s); l != "" {
+					added[key+"_label"] = l
+				}
+				continue
+			}
+			if f.decorate(field, key, locale) {
+				changed = true
+			}
+		}
+		for key, l := range added {
+			v[key] = l
+			changed = true
+		}
+	case []interface{}:
+		for _, item := range v {
+			if f.decorate(item, parent, locale) {
+				changed = true
+			}
+		}
+	}
+	return changed
+}
+
+// Middleware adds labels to the JSON responses of requests that send an
+// Accept-Language header, in the supported locale that best matches it.
+// Requests without one, and responses that are not JSON, pass through
+// unchanged, so streamed responses are not held up.
+func (f LabelFields) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Accept-Language") == "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		lw := &labelWriter{ResponseWriter: w, statusCode: http.StatusOK}
+		next.ServeHTTP(lw, r)
+		if !lw.buffering {
+			return
+		}
+
+		body := lw.body.Bytes()
+		locale := Negotiate(r.Header.Get("Accept-Language"))
+		header := w.Header()
+		header.Add("Vary", "Accept-Language")
+		if lw.statusCode >= 200 && lw.statusCode < 300 && len(body) <= maxDecoratedBodySize {
+			body = f.Decorate(body, locale)
+			header.Set("Content-Language", locale)
+		}
+		header.Set("Content-Length", strconv.Itoa(len(body)))
+		w.WriteHeader(lw.statusCode)
+		w.Write(body)
+	})
+}
+
+// labelWriter buffers uncompressed JSON responses so they can be labelled,
+// and writes anything else straight through.
+type labelWriter struct {
+	http.ResponseWriter
+	statusCode  int
+	wroteHeader bool
+	buffering   bool
+	body        bytes.Buffer
+}
+
+func (w *labelWriter) WriteHeader(statusCode int) {
+	if w.wroteHeader {
+		return
+	}
+	w.wroteHeader = true
+	w.statusCode = statusCode
+
+	header := w.ResponseWriter.Header()
+	if isJSON(header.Get("Content-Type")) && header.Get("Content-Encoding") == "" {
+		w.buffering = true
+		return
+	}
+	w.ResponseWriter.WriteHeader(statusCode)
+}
+
+func (w *labelWriter) Write(b []byte) (int, error) {
+	if !w.wroteHeader {
+		w.WriteHeader(http.StatusOK)
+	}
+	if w.buffering {
+		return w.body.Write(b)
+	}
+	return w.ResponseWriter.Write(b)
+}
+
+// Flush lets streamed responses through.
+func (w *labelWriter) Flush() {
+	if w.buffering {
+		return
+	}
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// Unwrap exposes the underlying writer to http.ResponseController.
+func (w *labelWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+func isJSON(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	return err == nil && (mediaType == "application/json" || strings.HasSuffix(mediaType, "+json"))
+}
@@ -0,0 +1,73 @@
+// Package i18n provides the display labels of the API's enum values, such as
+// statuses, priorities and pipeline stage types, in English and Malay, and
+// decorates JSON responses with them for the language a client accepts.
+package i18n
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Supported locales.
+const (
+	English = "en"
+	Malay   = "ms-MY"
+)
+
+// Locales lists the supported locales, the default first.
+var Locales = []string{English, Malay}
+
+// Negotiate returns the supported locale that best matches an
+// Accept-Language header, or English when none does. Malay is matched by any
+// "ms" language tag.
+func Negotiate(acceptLanguage string) string {
+	type preference struct {
+		tag     string
+		quality float64
+	}
+
+	var preferences []preference
+	for _, part := range strings.Split(acceptLanguage, ",") {
+		tag, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		if tag == "" {
+			continue
+		}
+		quality := 1.0
+		if q, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			parsed, err := strconv.ParseFloat(q, 64)
+			if err != nil {
+				continue
+			}
+			quality = parsed
+		}
+		if quality <= 0 {
+			continue
+		}
+		preferences = append(preferences, preference{tag: strings.ToLower(strings.TrimSpace(tag)), quality: quality})
+	}
+	sort.SliceStable(preferences, func(i, j int) bool {
+		return preferences[i].quality > preferences[j].quality
+	})
+
+	for _, p := range preferences {
+		if locale := Match(p.tag); locale != "" {
+			return locale
+		}
+	}
+	return English
+}
+
+// Match returns the supported locale of a language tag such as "ms",
+// "ms_MY" or "en-GB", or "" when the language is not supported. The
+// wildcard "*" matches English.
+func Match(tag string) string {
+	language, _, _ := strings.Cut(strings.ReplaceAll(strings.ToLower(strings.TrimSpace(tag)), "_", "-"), "-")
+	switch language {
+	case "ms":
+		return Malay
+	case "en", "*":
+		return English
+	}
+	return ""
+}
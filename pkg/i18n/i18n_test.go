@@ -0,0 +1,129 @@
+package i18n
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestNegotiate(t *testing.T) {
+	tests := []struct {
+		header string
+		want   string
+	}{
+		{"", English},
+		{"ms-MY", Malay},
+		{"ms", Malay},
+		{"en-GB,en;q=0.9", English},
+		{"fr-FR, ms;q=0.8, en;q=0.5", Malay},
+		{"en;q=0.4, ms-MY;q=0.9", Malay},
+		{"ms;q=0, en", English},
+		{"zh-CN", English},
+		{"*", English},
+	}
+
+	for _, tt := range tests {
+		if got := Negotiate(tt.header); got != tt.want {
+			t.Errorf("Negotiate(%q) = %q, want %q", tt.header, got, tt.want)
+		}
+	}
+}
+
+func TestLabel(t *testing.T) {
+	if got := Label(Malay, KindStatus, "won"); got != "Dimenangi" {
+		t.Errorf("Label(ms-MY, status, won) = %q, want Dimenangi", got)
+	}
+	if got := Label("fr", KindPriority, "high"); got != "High" {
+		t.Errorf("Label(fr, priority, high) = %q, want the English label", got)
+	}
+	// The same value is labelled by its kind
+	if got := Label(Malay, KindStageType, "open"); got != "Terbuka" {
+		t.Errorf("Label(ms-MY, stage_type, open) = %q, want Terbuka", got)
+	}
+	if got := Label(Malay, KindStatus, "unknown"); got != "" {
+		t.Errorf("Label() for an unknown value = %q, want none", got)
+	}
+
+	// Every value has a label in every locale
+	for _, locale := range Locales {
+		for kind, values := range Labels(locale) {
+			for value, l := range values {
+				if l == "" {
+					t.Errorf("%s %s %q has no label", locale, kind, value)
+				}
+			}
+		}
+	}
+}
+
+func TestLabelFields_Decorate(t *testing.T) {
+	body := []byte(`{"success":true,"data":[{"status":"open","priority":"high","amount":1234567890123,"stage":{"type":"negotiating","status":"x"},"type":"open"}]}`)
+
+	var got struct {
+		Data []map[string]interface{} `json:"data"`
+	}
+	if err := json.Unmarshal(DefaultLabelFields.Decorate(body, Malay), &got); err != nil {
+		t.Fatalf("Decorate() returned invalid JSON: %v", err)
+	}
+	item := got.Data[0]
+	if item["status_label"] != "Dibuka" || item["priority_label"] != "Tinggi" {
+		t.Errorf("item = %v, want the status and priority labelled", item)
+	}
+	if stage := item["stage"].(map[string]interface{}); stage["type_label"] != "Rundingan" || stage["status_label"] != nil {
+		t.Errorf("stage = %v, want its type labelled and the unknown status left alone", stage)
+	}
+	if _, ok := item["type_label"]; ok {
+		t.Error("type outside a stage was labelled")
+	}
+	if item["amount"] != float64(1234567890123) {
+		t.Errorf("amount = %v, want numbers kept", item["amount"])
+	}
+
+	// Bodies without labelled fields, and other content, are unchanged
+	for _, unchanged := range []string{`{"id":"1"}`, `not json`} {
+		if got := string(DefaultLabelFields.Decorate([]byte(unchanged), Malay)); got != unchanged {
+			t.Errorf("Decorate(%q) = %q, want it unchanged", unchanged, got)
+		}
+	}
+}
+
+func TestLabelFields_Middleware(t *testing.T) {
+	handler := DefaultLabelFields.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/events" {
+			w.Header().Set("Content-Type", "text/event-stream")
+			w.Write([]byte("data: {\"status\":\"open\"}\n\n"))
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"data":{"status":"won"}}`))
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/leads", nil)
+	req.Header.Set("Accept-Language", "ms-MY,ms;q=0.9,en;q=0.8")
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	if rec.Code != http.StatusCreated || rec.Body.String() != `{"data":{"status":"won","status_label":"Dimenangi"}}` {
+		t.Errorf("response = %d %s, want the Malay label", rec.Code, rec.Body.String())
+	}
+	if rec.Header().Get("Content-Language") != Malay || rec.Header().Get("Vary") != "Accept-Language" {
+		t.Errorf("headers = %v, want Content-Language and Vary", rec.Header())
+	}
+
+	// Without Accept-Language the response is unchanged
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/leads", nil))
+	if rec.Body.String() != `{"data":{"status":"won"}}` {
+		t.Errorf("response = %s, want it unchanged", rec.Body.String())
+	}
+
+	// Streams are written through
+	req = httptest.NewRequest(http.MethodGet, "/events", nil)
+	req.Header.Set("Accept-Language", "ms")
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	if rec.Body.String() != "data: {\"status\":\"open\"}\n\n" {
+		t.Errorf("stream = %q, want it unchanged", rec.Body.String())
+	}
+}
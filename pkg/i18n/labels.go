@@ -0,0 +1,135 @@
+package i18n
+
+// Kinds of labelled values. A value such as "open" is labelled differently as
+// a status and as a pipeline stage type.
+const (
+	KindStatus    = "status"
+	KindPriority  = "priority"
+	KindStageType = "stage_type"
+)
+
+// label is a value's display label in each supported locale.
+type label struct {
+	en string
+	ms string
+}
+
+// labels holds the labels of each kind of value. Statuses share one table
+// across resources, since the same status means the same thing wherever it
+// is used.
+var labels = map[string]map[string]label{
+	KindStatus: {
+		// Leads
+		"new":         {"New", "Baharu"},
+		"contacted":   {"Contacted", "Telah Dihubungi"},
+		"qualified":   {"Qualified", "Layak"},
+		"unqualified": {"Unqualified", "Tidak Layak"},
+		"converted":   {"Converted", "Telah Ditukar"},
+		"nurturing":   {"Nurturing", "Dalam Pemupukan"},
+
+		// Opportunities
+		"open": {"Open", "Dibuka"},
+		"won":  {"Won", "Dimenangi"},
+		"lost": {"Lost", "Kalah"},
+
+		// Deals, orders and shipments
+		"draft":            {"Draft", "Draf"},
+		"pending":          {"Pending", "Belum Selesai"},
+		"active":           {"Active", "Aktif"},
+		"on_hold":          {"On Hold", "Ditangguhkan"},
+		"fulfilled":        {"Fulfilled", "Dipenuhi"},
+		"cancelled":        {"Cancelled", "Dibatalkan"},
+		"in_production":    {"In Production", "Dalam Pengeluaran"},
+		"shipped":          {"Shipped", "Telah Dikirim"},
+		"in_transit":       {"In Transit", "Dalam Transit"},
+		"out_for_delivery": {"Out for Delivery", "Dalam Penghantaran"},
+		"delivered":        {"Delivered", "Telah Diterima"},
+		"exception":        {"Exception", "Masalah Penghantaran"},
+		"returned":         {"Returned", "Dipulangkan"},
+
+		// Invoices and e-invoices
+		"sent":      {"Sent", "Dihantar"},
+		"paid":      {"Paid", "Dibayar"},
+		"overdue":   {"Overdue", "Tertunggak"},
+		"submitted": {"Submitted", "Diserahkan"},
+		"valid":     {"Valid", "Sah"},
+		"invalid":   {"Invalid", "Tidak Sah"},
+
+		// Renewals
+		"upcoming":    {"Upcoming", "Akan Datang"},
+		"in_progress": {"In Progress", "Sedang Berjalan"},
+		"renewed":     {"Renewed", "Diperbaharui"},
+		"churned":     {"Churned", "Berhenti Langganan"},
+
+		// Approvals
+		"approved": {"Approved", "Diluluskan"},
+		"rejected": {"Rejected", "Ditolak"},
+
+		// Customers, contacts, users and tenants
+		"lead":      {"Lead", "Bakal Pelanggan"},
+		"prospect":  {"Prospect", "Prospek"},
+		"inactive":  {"Inactive", "Tidak Aktif"},
+		"blocked":   {"Blocked", "Disekat"},
+		"suspended": {"Suspended", "Digantung"},
+		"trial":     {"Trial", "Percubaan"},
+
+		// Notifications
+		"queued":     {"Queued", "Dalam Giliran"},
+		"sending":    {"Sending", "Sedang Dihantar"},
+		"read":       {"Read", "Dibaca"},
+		"scheduled":  {"Scheduled", "Dijadualkan"},
+		"retrying":   {"Retrying", "Mencuba Semula"},
+		"bounced":    {"Bounced", "Melantun"},
+		"complained": {"Complained", "Diadukan"},
+
+		// Jobs and syncs
+		"processing": {"Processing", "Sedang Diproses"},
+		"completed":  {"Completed", "Selesai"},
+		"failed":     {"Failed", "Gagal"},
+		"synced":     {"Synced", "Disegerakkan"},
+		"imported":   {"Imported", "Diimport"},
+		"skipped":    {"Skipped", "Dilangkau"},
+	},
+	KindPriority: {
+		"low":      {"Low", "Rendah"},
+		"normal":   {"Normal", "Biasa"},
+		"medium":   {"Medium", "Sederhana"},
+		"high":     {"High", "Tinggi"},
+		"urgent":   {"Urgent", "Segera"},
+		"critical": {"Critical", "Kritikal"},
+	},
+	KindStageType: {
+		"open":        {"Open", "Terbuka"},
+		"qualifying":  {"Qualifying", "Penilaian Kelayakan"},
+		"negotiating": {"Negotiating", "Rundingan"},
+		"won":         {"Won", "Dimenangi"},
+		"lost":        {"Lost", "Kalah"},
+	},
+}
+
+// Label returns the display label of a value of the given kind in locale, or
+// "" when the value has none. Unsupported locales get the English label.
+func Label(locale, kind, value string) string {
+	l, ok := labels[kind][value]
+	if !ok {
+		return ""
+	}
+	if locale == Malay {
+		return l.ms
+	}
+	return l.en
+}
+
+// Labels returns the display labels of every value of each kind in locale,
+// for clients that label values themselves.
+func Labels(locale string) map[string]map[string]string {
+	result := make(map[string]map[string]string, len(labels))
+	for kind, values := range labels {
+		localized := make(map[string]string, len(values))
+		for value := range values {
+			localized[value] = Label(locale, kind, value)
+		}
+		result[kind] = localized
+	}
+	return result
+}
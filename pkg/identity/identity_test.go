@@ -0,0 +1,56 @@
+package identity
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestSetVerify(t *testing.T) {
+	secret := []byte("identity-secret")
+	now := time.Unix(1700000000, 0)
+	id := Identity{
+		UserID:      "user-1",
+		TenantID:    "tenant-1",
+		Email:       "user@example.com",
+		Roles:       []string{"admin", "sales"},
+		Permissions: []string{"leads:read"},
+	}
+
+	h := http.Header{}
+	h.Set(UserIDHeader, "someone-else")
+	Set(h, id, secret, now)
+
+	got, err := Verify(h, secret, now.Add(time.Minute))
+	if err != nil {
+		t.Fatalf("Verify() error = %v", err)
+	}
+	if got.UserID != "user-1" || got.TenantID != "tenant-1" || len(got.Roles) != 2 || got.Permissions[0] != "leads:read" || !got.IssuedAt.Equal(now) {
+		t.Errorf("Verify() = %+v, want %+v", got, id)
+	}
+
+	// Copied headers verify for calls made on the caller's behalf
+	copied := http.Header{}
+	Copy(copied, h)
+	if _, err := Verify(copied, secret, now); err != nil {
+		t.Errorf("Verify() of copied headers error = %v", err)
+	}
+
+	if _, err := Verify(h, []byte("other-secret"), now); !errors.Is(err, ErrInvalidSignature) {
+		t.Errorf("Verify() with another secret error = %v, want %v", err, ErrInvalidSignature)
+	}
+	if _, err := Verify(h, secret, now.Add(MaxAge+time.Second)); !errors.Is(err, ErrExpired) {
+		t.Errorf("Verify() after MaxAge error = %v, want %v", err, ErrExpired)
+	}
+
+	h.Set(RolesHeader, "admin,sales,owner")
+	if _, err := Verify(h, secret, now); !errors.Is(err, ErrInvalidSignature) {
+		t.Errorf("Verify() of changed headers error = %v, want %v", err, ErrInvalidSignature)
+	}
+
+	Clear(h)
+	if Present(h) || h.Get(UserIDHeader) != "" {
+		t.Errorf("Clear() left identity headers: %v", h)
+	}
+}
@@ -0,0 +1,216 @@
+// Package lifecycle coordinates the startup and graceful shutdown of a
+// service: dependencies are waited for with backoff at boot, and the HTTP
+// server, event consumers, background workers and the connections they use
+// are stopped in dependency order within one deadline.
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/kilang-desa-murni/crm/pkg/logger"
+)
+
+// ============================================================================
+// Manager
+// ============================================================================
+
+// Manager stops the components of a service in the reverse order they were
+// registered. Registering each component once the resources it uses are
+// registered makes shutdown stop the HTTP server first, then the consumers
+// and workers feeding on it, and close the database and broker connections
+// last.
+type Manager struct {
+	log    *logger.Logger
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	mu         sync.Mutex
+	components []component
+}
+
+type component struct {
+	name string
+	stop func(ctx context.Context) error
+}
+
+// New creates a lifecycle manager.
+func New(log *logger.Logger) *Manager {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Manager{log: log, ctx: ctx, cancel: cancel}
+}
+
+// Context is cancelled as soon as shutdown begins, so loops started with it
+// stop taking new work.
+func (m *Manager) Context() context.Context {
+	return m.ctx
+}
+
+// OnShutdown registers the function that stops a component. It should
+// return once the component has finished its work or ctx is done.
+func (m *Manager) OnShutdown(name string, stop func(ctx context.Context) error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.components = append(m.components, component{name: name, stop: stop})
+}
+
+// OnClose registers a resource that is closed at shutdown.
+func (m *Manager) OnClose(name string, closer interface{ Close() error }) {
+	m.OnShutdown(name, func(context.Context) error {
+		return closer.Close()
+	})
+}
+
+// Go runs a background worker until Context is cancelled; shutdown waits
+// for it to return.
+func (m *Manager) Go(name string, run func(ctx context.Context)) {
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		run(m.ctx)
+	}()
+
+	m.OnShutdown(name, func(ctx context.Context) error {
+		select {
+		case <-done:
+			return nil
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	})
+}
+
+// Wait blocks until the process is asked to stop with SIGINT or SIGTERM.
+func (m *Manager) Wait() os.Signal {
+	quit := make(chan os.Signal, 1)
+	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
+	defer signal.Stop(quit)
+	return <-quit
+}
+
+// Shutdown cancels Context and stops the components, last registered
+// first, within timeout. A component that has not stopped when the deadline
+// passes is abandoned and the remaining ones are still stopped, so every
+// connection is closed. The errors of the components are joined.
+func (m *Manager) Shutdown(timeout time.Duration) error {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	m.cancel()
+
+	m.mu.Lock()
+	components := make([]component, len(m.components))
+	copy(components, m.components)
+	m.mu.Unlock()
+
+	var errs []error
+	for i := len(components) - 1; i >= 0; i-- {
+		c := components[i]
+		start := time.Now()
+		if err := m.stop(ctx, c); err != nil {
+			m.log.Error().Err(err).Str("component", c.name).Dur("duration", time.Since(start)).Msg("Component did not stop cleanly")
+			errs = append(errs, fmt.Errorf("%s: %w", c.name, err))
+			continue
+		}
+		m.log.Debug().Str("component", c.name).Dur("duration", time.Since(start)).Msg("Component stopped")
+	}
+	return errors.Join(errs...)
+}
+
+// stop runs the stop function of c, giving up on it when ctx is done.
+func (m *Manager) stop(ctx context.Context, c component) error {
+	done := make(chan error, 1)
+	go func() {
+		defer func() {
+			if r := recover(); r != nil {
+				done <- fmt.Errorf("panic: %v", r)
+			}
+		}()
+		done <- c.stop(ctx)
+	}()
+
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+	}
+
+	// Closing a connection must not be skipped because an earlier component
+	// used up the deadline, so each still gets a moment after it
+	select {
+	case err := <-done:
+		return err
+	case <-time.After(abandonGrace):
+		return fmt.Errorf("did not stop before the shutdown deadline: %w", ctx.Err())
+	}
+}
+
+// abandonGrace is how long a component still gets once the shutdown
+// deadline has passed, enough to close a connection.
+var abandonGrace = 100 * time.Millisecond
+
+// ============================================================================
+// Drainer
+// ============================================================================
+
+// Drainer lets a message consumer finish the deliveries it is handling
+// before it closes. The zero value is ready to use.
+type Drainer struct {
+	mu       sync.Mutex
+	stopped  bool
+	inFlight sync.WaitGroup
+}
+
+// Begin reports whether a delivery may be handled. Once Stop is called it
+// returns false and the consumer should requeue the delivery; every true
+// must be followed by End.
+func (d *Drainer) Begin() bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.stopped {
+		return false
+	}
+	d.inFlight.Add(1)
+	return true
+}
+
+// End marks a delivery begun with Begin as handled.
+func (d *Drainer) End() {
+	d.inFlight.Done()
+}
+
+// Stop refuses further deliveries.
+func (d *Drainer) Stop() {
+	d.mu.Lock()
+	d.stopped = true
+	d.mu.Unlock()
+}
+
+// Stopped reports whether Stop was called, for consumers to not reconnect.
+func (d *Drainer) Stopped() bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.stopped
+}
+
+// Wait waits for the deliveries being handled until ctx is done.
+func (d *Drainer) Wait(ctx context.Context) error {
+	done := make(chan struct{})
+	go func() {
+		d.inFlight.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("deliveries still being handled: %w", ctx.Err())
+	}
+}
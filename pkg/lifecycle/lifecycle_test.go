@@ -0,0 +1,155 @@
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/kilang-desa-murni/crm/pkg/config"
+	"github.com/kilang-desa-murni/crm/pkg/logger"
+)
+
+func TestManager_StopsInReverseOrder(t *testing.T) {
+	m := New(logger.New(logger.Config{Level: "error"}))
+
+	var mu sync.Mutex
+	var order []string
+	record := func(name string) func(context.Context) error {
+		return func(context.Context) error {
+			mu.Lock()
+			defer mu.Unlock()
+			order = append(order, name)
+			return nil
+		}
+	}
+	m.OnShutdown("database", record("database"))
+	m.OnShutdown("consumer", record("consumer"))
+	m.OnShutdown("http", record("http"))
+
+	workerStopped := false
+	m.Go("worker", func(ctx context.Context) {
+		<-ctx.Done()
+		mu.Lock()
+		workerStopped = true
+		mu.Unlock()
+	})
+
+	if err := m.Shutdown(time.Second); err != nil {
+		t.Fatalf("shutdown: %v", err)
+	}
+	if got := strings.Join(order, ","); got != "http,consumer,database" {
+		t.Errorf("expected http,consumer,database, got %s", got)
+	}
+	if !workerStopped {
+		t.Error("expected the worker to have returned")
+	}
+	if m.Context().Err() == nil {
+		t.Error("expected the context to be cancelled")
+	}
+}
+
+func TestManager_DeadlineDoesNotSkipClosing(t *testing.T) {
+	m := New(logger.New(logger.Config{Level: "error"}))
+
+	closed := false
+	m.OnShutdown("database", func(context.Context) error {
+		closed = true
+		return nil
+	})
+	m.OnShutdown("stuck", func(context.Context) error {
+		select {}
+	})
+	m.OnShutdown("failing", func(context.Context) error {
+		return errors.New("boom")
+	})
+
+	err := m.Shutdown(20 * time.Millisecond)
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+	if !strings.Contains(err.Error(), "failing: boom") || !strings.Contains(err.Error(), "stuck: did not stop") {
+		t.Errorf("unexpected error %v", err)
+	}
+	if !closed {
+		t.Error("expected the database closed after the deadline")
+	}
+}
+
+func TestDrainer(t *testing.T) {
+	var d Drainer
+
+	if !d.Begin() {
+		t.Fatal("expected a delivery to be accepted")
+	}
+	d.Stop()
+	if d.Begin() {
+		t.Fatal("expected deliveries refused after Stop")
+	}
+	if !d.Stopped() {
+		t.Error("expected Stopped")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := d.Wait(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected the wait to time out, got %v", err)
+	}
+
+	d.End()
+	if err := d.Wait(context.Background()); err != nil {
+		t.Fatalf("wait: %v", err)
+	}
+}
+
+func TestAwait_RetriesUntilConnected(t *testing.T) {
+	s := NewStartup(config.StartupConfig{
+		MaxWait:        time.Second,
+		InitialBackoff: time.Millisecond,
+		MaxBackoff:     5 * time.Millisecond,
+	}, logger.New(logger.Config{Level: "error"}))
+	defer s.Ready()
+
+	attempts := 0
+	value, err := Await(s, "postgres", func() (string, error) {
+		attempts++
+		if attempts < 3 {
+			if got := s.Pending(); len(got) != 1 || got[0] != "postgres" {
+				t.Errorf("expected postgres pending, got %v", got)
+			}
+			return "", errors.New("connection refused")
+		}
+		return "db", nil
+	})
+	if err != nil || value != "db" {
+		t.Fatalf("expected db, got %q, %v", value, err)
+	}
+	if attempts != 3 {
+		t.Errorf("expected 3 attempts, got %d", attempts)
+	}
+	if got := s.Pending(); len(got) != 0 {
+		t.Errorf("expected nothing pending, got %v", got)
+	}
+}
+
+func TestAwait_GivesUpAfterMaxWait(t *testing.T) {
+	s := NewStartup(config.StartupConfig{
+		MaxWait:        20 * time.Millisecond,
+		InitialBackoff: 5 * time.Millisecond,
+		MaxBackoff:     5 * time.Millisecond,
+	}, logger.New(logger.Config{Level: "error"}))
+	defer s.Ready()
+
+	refused := errors.New("connection refused")
+	_, err := Await(s, "rabbitmq", func() (int, error) { return 0, refused })
+	if !errors.Is(err, refused) {
+		t.Fatalf("expected the last error, got %v", err)
+	}
+
+	checks := s.checks()
+	if checks["rabbitmq"].Message != "connection refused" {
+		t.Errorf("expected rabbitmq reported unhealthy, got %+v", checks)
+	}
+}
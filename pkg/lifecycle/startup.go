@@ -0,0 +1,204 @@
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"net/http"
+	"os/signal"
+	"sort"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/kilang-desa-murni/crm/pkg/config"
+	"github.com/kilang-desa-murni/crm/pkg/logger"
+	"github.com/kilang-desa-murni/crm/pkg/response"
+)
+
+// ============================================================================
+// Startup
+// ============================================================================
+
+// errNotConnected is reported for a dependency before its first attempt
+// completes.
+var errNotConnected = errors.New("not connected yet")
+
+// Startup waits for the dependencies of a service at boot, so a service
+// started before its database or broker retries the connection instead of
+// crash-looping. In degraded mode it also answers health checks as not
+// ready until Ready is called.
+type Startup struct {
+	cfg     config.StartupConfig
+	log     *logger.Logger
+	started time.Time
+
+	// ctx ends when the process is asked to stop while waiting.
+	ctx  context.Context
+	stop context.CancelFunc
+
+	mu      sync.Mutex
+	pending map[string]error
+	server  *http.Server
+}
+
+// NewStartup creates a startup waiter.
+func NewStartup(cfg config.StartupConfig, log *logger.Logger) *Startup {
+	if cfg.InitialBackoff <= 0 {
+		cfg.InitialBackoff = time.Second
+	}
+	if cfg.MaxBackoff < cfg.InitialBackoff {
+		cfg.MaxBackoff = max(30*time.Second, cfg.InitialBackoff)
+	}
+	if cfg.MaxWait <= 0 {
+		cfg.MaxWait = 2 * time.Minute
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	return &Startup{
+		cfg:     cfg,
+		log:     log,
+		started: time.Now(),
+		ctx:     ctx,
+		stop:    stop,
+		pending: make(map[string]error),
+	}
+}
+
+// ServeHealth answers /health, /health/live and /health/ready on addr until
+// Ready is called, when running in degraded mode. Liveness succeeds while
+// the others report the dependencies still being waited for.
+func (s *Startup) ServeHealth(addr, version string) {
+	if !s.cfg.Degraded {
+		return
+	}
+
+	mux := http.NewServeMux()
+	notReady := func(w http.ResponseWriter, r *http.Request) {
+		response.Health(w, "starting", version, time.Since(s.started), s.checks())
+	}
+	mux.HandleFunc("/health", notReady)
+	mux.HandleFunc("/health/ready", notReady)
+	mux.HandleFunc("/health/live", func(w http.ResponseWriter, r *http.Request) {
+		response.Health(w, "healthy", version, time.Since(s.started), nil)
+	})
+
+	server := &http.Server{Addr: addr, Handler: mux, ReadHeaderTimeout: 5 * time.Second}
+	s.mu.Lock()
+	s.server = server
+	s.mu.Unlock()
+
+	go func() {
+		if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			s.log.Warn().Err(err).Str("addr", addr).Msg("Failed to serve health checks while starting")
+		}
+	}()
+}
+
+// checks reports the dependencies still being waited for.
+func (s *Startup) checks() map[string]response.HealthCheck {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	checks := make(map[string]response.HealthCheck, len(s.pending))
+	for name, err := range s.pending {
+		checks[name] = response.HealthCheck{Status: "unhealthy", Message: err.Error()}
+	}
+	return checks
+}
+
+// Pending returns the names of the dependencies still being waited for.
+func (s *Startup) Pending() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	names := make([]string, 0, len(s.pending))
+	for name := range s.pending {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Ready ends the startup: the degraded health endpoint is closed so the
+// service's own server can take the address.
+func (s *Startup) Ready() {
+	s.stop()
+
+	s.mu.Lock()
+	server := s.server
+	s.server = nil
+	s.mu.Unlock()
+
+	if server != nil {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		server.Shutdown(ctx)
+	}
+	s.log.Info().Dur("duration", time.Since(s.started)).Msg("Dependencies ready")
+}
+
+// Await connects to a dependency, retrying with exponential backoff until it
+// succeeds. Outside degraded mode it gives up after MaxWait and returns the
+// last error; it also gives up when the process is asked to stop.
+func Await[T any](s *Startup, name string, connect func() (T, error)) (T, error) {
+	var zero T
+
+	s.setPending(name, errNotConnected)
+	deadline := time.Now().Add(s.cfg.MaxWait)
+	delay := s.cfg.InitialBackoff
+
+	for attempt := 1; ; attempt++ {
+		value, err := connect()
+		if err == nil {
+			s.setPending(name, nil)
+			if attempt > 1 {
+				s.log.Info().Str("dependency", name).Int("attempts", attempt).Msg("Dependency available")
+			}
+			return value, nil
+		}
+		s.setPending(name, err)
+
+		wait := jitter(delay)
+		if !s.cfg.Degraded && time.Now().Add(wait).After(deadline) {
+			return zero, fmt.Errorf("%s not available after %d attempts: %w", name, attempt, err)
+		}
+
+		s.log.Warn().
+			Err(err).
+			Str("dependency", name).
+			Int("attempt", attempt).
+			Dur("retry_in", wait).
+			Msg("Dependency not available, retrying")
+
+		select {
+		case <-s.ctx.Done():
+			return zero, fmt.Errorf("%s: startup interrupted: %w", name, err)
+		case <-time.After(wait):
+		}
+		delay = min(delay*2, s.cfg.MaxBackoff)
+	}
+}
+
+// setPending records the last error of a dependency, or that it is
+// connected when err is nil.
+func (s *Startup) setPending(name string, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err == nil {
+		delete(s.pending, name)
+		return
+	}
+	s.pending[name] = err
+}
+
+// jitter spreads a delay by up to a fifth either way, so instances started
+// together do not retry in step.
+func jitter(d time.Duration) time.Duration {
+	spread := int64(d) / 5
+	if spread <= 0 {
+		return d
+	}
+	return d + time.Duration(rand.Int63n(2*spread)-spread)
+}
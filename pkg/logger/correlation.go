@@ -0,0 +1,81 @@
+package logger
+
+import (
+	"context"
+	"sync"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+// ============================================================================
+// Correlation
+// ============================================================================
+
+// Correlation holds the identifiers attached to every line logged for a
+// request, so the logs of a request, trace or tenant can be queried
+// together. The request logging middleware creates it and the authentication
+// middleware fills in the tenant and user once they are known.
+type Correlation struct {
+	mu        sync.RWMutex
+	requestID string
+	tenantID  string
+	userID    string
+}
+
+type correlationKey struct{}
+
+// WithCorrelation returns a context carrying a correlation for requestID,
+// or the correlation ctx already carries.
+func WithCorrelation(ctx context.Context, requestID string) (context.Context, *Correlation) {
+	if c := CorrelationFromContext(ctx); c != nil {
+		return ctx, c
+	}
+	c := &Correlation{requestID: requestID}
+	return context.WithValue(ctx, correlationKey{}, c), c
+}
+
+// CorrelationFromContext returns the correlation of ctx, or nil.
+func CorrelationFromContext(ctx context.Context) *Correlation {
+	c, _ := ctx.Value(correlationKey{}).(*Correlation)
+	return c
+}
+
+// SetUser records the authenticated tenant and user.
+func (c *Correlation) SetUser(tenantID, userID string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.tenantID = tenantID
+	c.userID = userID
+}
+
+// Correlated returns the logger with the correlation fields of ctx:
+// request_id, tenant_id and user_id from its Correlation and trace_id and
+// span_id from its span. Fields that are not known are left out.
+func (l *Logger) Correlated(ctx context.Context) *Logger {
+	span := trace.SpanContextFromContext(ctx)
+	c := CorrelationFromContext(ctx)
+	if c == nil && !span.IsValid() {
+		return l
+	}
+
+	lc := l.With()
+	if c != nil {
+		c.mu.RLock()
+		requestID, tenantID, userID := c.requestID, c.tenantID, c.userID
+		c.mu.RUnlock()
+
+		if requestID != "" {
+			lc = lc.RequestID(requestID)
+		}
+		if tenantID != "" {
+			lc = lc.TenantID(tenantID)
+		}
+		if userID != "" {
+			lc = lc.UserID(userID)
+		}
+	}
+	if span.IsValid() {
+		lc = lc.TraceID(span.TraceID().String()).SpanID(span.SpanID().String())
+	}
+	return lc.Logger()
+}
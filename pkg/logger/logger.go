@@ -5,8 +5,10 @@ package logger
 
 import (
 	"context"
+	"fmt"
 	"io"
 	"os"
+	"sync/atomic"
 	"time"
 
 	"github.com/rs/zerolog"
@@ -15,6 +17,11 @@ import (
 // Logger wraps zerolog.Logger to provide additional functionality.
 type Logger struct {
 	zl zerolog.Logger
+	// level is shared with the loggers derived with With, so that SetLevel
+	// changes them all.
+	level *atomic.Int32
+	// shipper ships the logs of the logger and its derived loggers, if any.
+	shipper *Shipper
 }
 
 // Config holds the logger configuration.
@@ -24,6 +31,8 @@ type Config struct {
 	TimeFormat string `yaml:"time_format" env:"LOG_TIME_FORMAT" env-default:"2006-01-02T15:04:05.000Z07:00"`
 	Caller     bool   `yaml:"caller" env:"LOG_CALLER" env-default:"false"`
 	Output     io.Writer
+	// Shipping ships the logs to Loki or Elasticsearch besides Output.
+	Shipping ShippingConfig
 }
 
 // contextKey is a type for context keys to avoid collisions.
@@ -73,9 +82,22 @@ func New(cfg Config) *Logger {
 		}
 	}
 
-	// Create base logger
+	// Ship the JSON lines to the log store; the console format is for the
+	// output only
+	var shipper *Shipper
+	if cfg.Shipping.Sink != "" {
+		sink, err := NewSink(cfg.Shipping)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "log shipping disabled: %v\n", err)
+		} else {
+			shipper = NewShipper(sink, cfg.Shipping)
+			output = zerolog.MultiLevelWriter(output, shipper)
+		}
+	}
+
+	// Create base logger; levels are filtered by Logger so they can change
 	zl := zerolog.New(output).
-		Level(level).
+		Level(zerolog.TraceLevel).
 		With().
 		Timestamp().
 		Logger()
@@ -85,7 +107,52 @@ func New(cfg Config) *Logger {
 		zl = zl.With().Caller().Logger()
 	}
 
-	return &Logger{zl: zl}
+	l := &Logger{zl: zl, level: new(atomic.Int32), shipper: shipper}
+	l.level.Store(int32(level))
+	return l
+}
+
+// SetLevel changes the level of the logger and every logger derived from
+// it, such as "debug" or "warn".
+func (l *Logger) SetLevel(level string) error {
+	parsed, err := zerolog.ParseLevel(level)
+	if err != nil {
+		return err
+	}
+	l.level.Store(int32(parsed))
+	return nil
+}
+
+// GetLevel returns the logger's level.
+func (l *Logger) GetLevel() string {
+	return zerolog.Level(l.level.Load()).String()
+}
+
+// event starts an event at level, or a disabled event below the logger's
+// level.
+func (l *Logger) event(level zerolog.Level, start func() *zerolog.Event) *Event {
+	if level < zerolog.Level(l.level.Load()) {
+		return &Event{}
+	}
+	return &Event{ze: start()}
+}
+
+// Close ships the logs still buffered, waiting until ctx is done at most.
+// The logger keeps writing to its output afterwards.
+func (l *Logger) Close(ctx context.Context) error {
+	if l.shipper == nil {
+		return nil
+	}
+	return l.shipper.Close(ctx)
+}
+
+// ShippingStats returns the counts of the logger's shipper, or zeros when
+// logs are not shipped.
+func (l *Logger) ShippingStats() ShippingStats {
+	if l.shipper == nil {
+		return ShippingStats{}
+	}
+	return l.shipper.Stats()
 }
 
 // SetGlobal sets the global logger instance.
@@ -103,22 +170,26 @@ func (l *Logger) WithContext(ctx context.Context) context.Context {
 	return context.WithValue(ctx, loggerKey, l)
 }
 
-// FromContext returns the logger from the context, or the global logger if not found.
+// FromContext returns the logger from the context, or the global logger if
+// not found, with the correlation fields of the context.
 func FromContext(ctx context.Context) *Logger {
-	if l, ok := ctx.Value(loggerKey).(*Logger); ok {
-		return l
+	l, ok := ctx.Value(loggerKey).(*Logger)
+	if !ok {
+		l = globalLogger
 	}
-	return globalLogger
+	return l.Correlated(ctx)
 }
 
 // With returns a new logger with the given fields.
 func (l *Logger) With() *LoggerContext {
-	return &LoggerContext{zc: l.zl.With()}
+	return &LoggerContext{zc: l.zl.With(), level: l.level, shipper: l.shipper}
 }
 
 // LoggerContext is a builder for adding fields to a logger.
 type LoggerContext struct {
-	zc zerolog.Context
+	zc      zerolog.Context
+	level   *atomic.Int32
+	shipper *Shipper
 }
 
 // Str adds a string field.
@@ -219,29 +290,29 @@ func (lc *LoggerContext) Service(service string) *LoggerContext {
 
 // Logger returns the configured logger.
 func (lc *LoggerContext) Logger() *Logger {
-	return &Logger{zl: lc.zc.Logger()}
+	return &Logger{zl: lc.zc.Logger(), level: lc.level, shipper: lc.shipper}
 }
 
 // Log level methods
 
 // Debug logs a debug message.
 func (l *Logger) Debug() *Event {
-	return &Event{ze: l.zl.Debug()}
+	return l.event(zerolog.DebugLevel, l.zl.Debug)
 }
 
 // Info logs an info message.
 func (l *Logger) Info() *Event {
-	return &Event{ze: l.zl.Info()}
+	return l.event(zerolog.InfoLevel, l.zl.Info)
 }
 
 // Warn logs a warning message.
 func (l *Logger) Warn() *Event {
-	return &Event{ze: l.zl.Warn()}
+	return l.event(zerolog.WarnLevel, l.zl.Warn)
 }
 
 // Error logs an error message.
 func (l *Logger) Error() *Event {
-	return &Event{ze: l.zl.Error()}
+	return l.event(zerolog.ErrorLevel, l.zl.Error)
 }
 
 // Fatal logs a fatal message and exits.
@@ -0,0 +1,180 @@
+package logger
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"mime"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// ============================================================================
+// PII Redaction
+// ============================================================================
+
+// RedactedValue replaces values kept out of the logs.
+const RedactedValue = "[REDACTED]"
+
+// DefaultRedactFields are kept out of logged bodies, query strings and
+// headers. A field is redacted when its name contains one of them, ignoring
+// case, "-" and "_", so "primary_email" and "X-CSRF-Token" are covered.
+var DefaultRedactFields = []string{
+	"email", "phone", "mobile", "password", "passcode", "otp", "token",
+	"secret", "authorization", "cookie", "apikey", "icnumber", "nric",
+}
+
+var (
+	emailPattern = regexp.MustCompile(`[A-Za-z0-9._%+\-]+@[A-Za-z0-9.\-]+\.[A-Za-z]{2,}`)
+	// Malaysian mobile numbers and international numbers written with "+"
+	phonePattern  = regexp.MustCompile(`(?:\+?60|\b0)1\d[\s\-]?\d{3,4}[\s\-]?\d{4}\b|\+\d{1,3}[\s\-]?\d{2,4}[\s\-]?\d{3,4}[\s\-]?\d{3,4}\b`)
+	jwtPattern    = regexp.MustCompile(`eyJ[A-Za-z0-9_\-]+\.[A-Za-z0-9_\-]+\.[A-Za-z0-9_\-]*`)
+	bearerPattern = regexp.MustCompile(`(?i)\b(bearer|basic)\s+[A-Za-z0-9._~+/\-]+=*`)
+	// jsonPairPattern finds "key": value pairs in JSON cut off at the
+	// capture limit, which cannot be decoded
+	jsonPairPattern = regexp.MustCompile(`"([^"\\]+)"\s*:\s*("(?:[^"\\]|\\.)*"?|[^,}\]\s]*)`)
+)
+
+// Redactor removes personal data and credentials from what is logged: the
+// values of sensitive fields, and emails, phone numbers and tokens wherever
+// they appear.
+type Redactor struct {
+	fields []string
+}
+
+// NewRedactor creates a redactor for DefaultRedactFields and the given
+// extra fields.
+func NewRedactor(fields ...string) *Redactor {
+	r := &Redactor{}
+	for _, field := range append(append([]string{}, DefaultRedactFields...), fields...) {
+		if field = normalizeField(field); field != "" {
+			r.fields = append(r.fields, field)
+		}
+	}
+	return r
+}
+
+func normalizeField(name string) string {
+	return strings.NewReplacer("-", "", "_", "").Replace(strings.ToLower(name))
+}
+
+// Field reports whether the value of a field, parameter or header is
+// redacted entirely.
+func (r *Redactor) Field(name string) bool {
+	name = normalizeField(name)
+	for _, field := range r.fields {
+		if strings.Contains(name, field) {
+			return true
+		}
+	}
+	return false
+}
+
+// String redacts emails, phone numbers and tokens in free text.
+func (r *Redactor) String(s string) string {
+	s = jwtPattern.ReplaceAllString(s, RedactedValue)
+	s = bearerPattern.ReplaceAllString(s, "$1 "+RedactedValue)
+	s = emailPattern.ReplaceAllString(s, RedactedValue)
+	return phonePattern.ReplaceAllString(s, RedactedValue)
+}
+
+// Header returns the headers as a map for logging, with sensitive values
+// redacted.
+func (r *Redactor) Header(h http.Header) map[string]string {
+	out := make(map[string]string, len(h))
+	for name, values := range h {
+		if r.Field(name) {
+			out[name] = RedactedValue
+			continue
+		}
+		out[name] = r.String(strings.Join(values, ", "))
+	}
+	return out
+}
+
+// Query returns an encoded query string with sensitive values redacted.
+func (r *Redactor) Query(q url.Values) string {
+	redacted := make(url.Values, len(q))
+	for name, values := range q {
+		for _, value := range values {
+			if r.Field(name) {
+				value = RedactedValue
+			} else {
+				value = r.String(value)
+			}
+			redacted.Add(name, value)
+		}
+	}
+	return redacted.Encode()
+}
+
+// Body returns a request or response body for logging with sensitive
+// values redacted. JSON cut off at a capture limit is redacted pair by pair;
+// bodies that are not text are summarized.
+func (r *Redactor) Body(contentType string, body []byte) string {
+	if len(body) == 0 {
+		return ""
+	}
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+
+	switch {
+	case strings.Contains(mediaType, "json"):
+		var v interface{}
+		dec := json.NewDecoder(bytes.NewReader(body))
+		dec.UseNumber()
+		if err := dec.Decode(&v); err == nil {
+			if out, err := json.Marshal(r.value("", v)); err == nil {
+				return string(out)
+			}
+		}
+		return r.String(r.jsonPairs(string(body)))
+	case mediaType == "application/x-www-form-urlencoded":
+		if q, err := url.ParseQuery(string(body)); err == nil {
+			return r.Query(q)
+		}
+		return r.String(string(body))
+	case strings.HasPrefix(mediaType, "text/"), strings.HasSuffix(mediaType, "xml"), mediaType == "":
+		return r.String(string(body))
+	default:
+		return fmt.Sprintf("[%d bytes of %s]", len(body), mediaType)
+	}
+}
+
+// value redacts a decoded JSON value found under key.
+func (r *Redactor) value(key string, v interface{}) interface{} {
+	switch t := v.(type) {
+	case map[string]interface{}:
+		for k, child := range t {
+			t[k] = r.value(k, child)
+		}
+		return t
+	case []interface{}:
+		for i, child := range t {
+			t[i] = r.value(key, child)
+		}
+		return t
+	case nil:
+		return nil
+	}
+	if key != "" && r.Field(key) {
+		return RedactedValue
+	}
+	if s, ok := v.(string); ok {
+		return r.String(s)
+	}
+	return v
+}
+
+// jsonPairs redacts the values of sensitive keys in JSON that cannot be
+// decoded.
+func (r *Redactor) jsonPairs(s string) string {
+	return jsonPairPattern.ReplaceAllStringFunc(s, func(pair string) string {
+		m := jsonPairPattern.FindStringSubmatch(pair)
+		if !r.Field(m[1]) {
+			return pair
+		}
+		return fmt.Sprintf("%q: %q", m[1], RedactedValue)
+	})
+}
@@ -0,0 +1,365 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// ============================================================================
+// Shipping Configuration
+// ============================================================================
+
+// Log sinks.
+const (
+	SinkLoki          = "loki"
+	SinkElasticsearch = "elasticsearch"
+)
+
+// ShippingConfig configures shipping logs to a log store alongside the
+// output. Shipping is off when Sink is empty.
+type ShippingConfig struct {
+	// Sink is "loki" or "elasticsearch".
+	Sink string `mapstructure:"sink"`
+	// URL is the Loki or Elasticsearch base URL, e.g. http://loki:3100.
+	URL string `mapstructure:"url"`
+	// Index is the prefix of the daily Elasticsearch indices.
+	Index string `mapstructure:"index"`
+	// Labels are added to the Loki streams besides service and level.
+	Labels map[string]string `mapstructure:"labels"`
+	// Username and Password authenticate with basic auth.
+	Username string `mapstructure:"username"`
+	Password string `mapstructure:"password" secret:"true"`
+	// BufferSize is how many lines wait to be shipped; further lines are
+	// dropped, so a slow log store never slows the service.
+	BufferSize int `mapstructure:"buffer_size"`
+	// BatchSize and FlushInterval bound how long lines wait: a batch is sent
+	// when it is full or the interval has passed.
+	BatchSize     int           `mapstructure:"batch_size"`
+	FlushInterval time.Duration `mapstructure:"flush_interval"`
+	// Timeout bounds each request to the log store.
+	Timeout time.Duration `mapstructure:"timeout"`
+}
+
+func (c ShippingConfig) withDefaults() ShippingConfig {
+	if c.Index == "" {
+		c.Index = "crm-logs"
+	}
+	if c.BufferSize <= 0 {
+		c.BufferSize = 10000
+	}
+	if c.BatchSize <= 0 {
+		c.BatchSize = 500
+	}
+	if c.FlushInterval <= 0 {
+		c.FlushInterval = 2 * time.Second
+	}
+	if c.Timeout <= 0 {
+		c.Timeout = 10 * time.Second
+	}
+	return c
+}
+
+// ============================================================================
+// Sinks
+// ============================================================================
+
+// Sink stores batches of log lines, each a JSON object.
+type Sink interface {
+	Send(ctx context.Context, lines [][]byte) error
+}
+
+// NewSink creates the sink of the configuration.
+func NewSink(cfg ShippingConfig) (Sink, error) {
+	cfg = cfg.withDefaults()
+	if cfg.URL == "" {
+		return nil, fmt.Errorf("log shipping to %s needs a URL", cfg.Sink)
+	}
+	client := &http.Client{Timeout: cfg.Timeout}
+	base := strings.TrimSuffix(cfg.URL, "/")
+
+	switch cfg.Sink {
+	case SinkLoki:
+		return &LokiSink{url: base + "/loki/api/v1/push", labels: cfg.Labels, username: cfg.Username, password: cfg.Password, client: client}, nil
+	case SinkElasticsearch:
+		return &ElasticsearchSink{url: base + "/_bulk", index: cfg.Index, username: cfg.Username, password: cfg.Password, client: client}, nil
+	default:
+		return nil, fmt.Errorf("unknown log sink %q", cfg.Sink)
+	}
+}
+
+// lineMeta is what sinks read from a log line.
+type lineMeta struct {
+	Level   string `json:"level"`
+	Service string `json:"service"`
+	Time    string `json:"time"`
+}
+
+func parseLine(line []byte) (lineMeta, time.Time) {
+	var meta lineMeta
+	_ = json.Unmarshal(line, &meta)
+	ts, err := time.Parse(time.RFC3339Nano, meta.Time)
+	if err != nil {
+		ts = time.Now()
+	}
+	return meta, ts
+}
+
+// LokiSink pushes lines to Loki in one stream per service and level. Other
+// fields such as tenant_id stay in the line, to be filtered with "| json".
+type LokiSink struct {
+	url      string
+	labels   map[string]string
+	username string
+	password string
+	client   *http.Client
+}
+
+// Send implements Sink.
+func (s *LokiSink) Send(ctx context.Context, lines [][]byte) error {
+	type stream struct {
+		Stream map[string]string `json:"stream"`
+		Values [][2]string       `json:"values"`
+	}
+	streams := map[string]*stream{}
+	var order []string
+
+	for _, line := range lines {
+		meta, ts := parseLine(line)
+		key := meta.Service + "\x00" + meta.Level
+		st, ok := streams[key]
+		if !ok {
+			labels := map[string]string{}
+			for k, v := range s.labels {
+				labels[k] = v
+			}
+			if meta.Service != "" {
+				labels["service"] = meta.Service
+			}
+			if meta.Level != "" {
+				labels["level"] = meta.Level
+			}
+			st = &stream{Stream: labels}
+			streams[key] = st
+			order = append(order, key)
+		}
+		st.Values = append(st.Values, [2]string{fmt.Sprintf("%d", ts.UnixNano()), string(line)})
+	}
+
+	push := struct {
+		Streams []*stream `json:"streams"`
+	}{}
+	for _, key := range order {
+		push.Streams = append(push.Streams, streams[key])
+	}
+	body, err := json.Marshal(push)
+	if err != nil {
+		return err
+	}
+	return postLogs(ctx, s.client, s.url, "application/json", body, s.username, s.password, nil)
+}
+
+// ElasticsearchSink indexes lines into daily indices named
+// <index>-YYYY.MM.DD with the bulk API.
+type ElasticsearchSink struct {
+	url      string
+	index    string
+	username string
+	password string
+	client   *http.Client
+}
+
+// Send implements Sink.
+func (s *ElasticsearchSink) Send(ctx context.Context, lines [][]byte) error {
+	var body bytes.Buffer
+	for _, line := range lines {
+		_, ts := parseLine(line)
+		fmt.Fprintf(&body, `{"create":{"_index":%q}}`+"\n", s.index+"-"+ts.UTC().Format("2006.01.02"))
+		body.Write(bytes.TrimRight(line, "\n"))
+		body.WriteByte('\n')
+	}
+
+	return postLogs(ctx, s.client, s.url, "application/x-ndjson", body.Bytes(), s.username, s.password, func(resp []byte) error {
+		var result struct {
+			Errors bool `json:"errors"`
+		}
+		if err := json.Unmarshal(resp, &result); err == nil && result.Errors {
+			return fmt.Errorf("elasticsearch rejected some log lines")
+		}
+		return nil
+	})
+}
+
+func postLogs(ctx context.Context, client *http.Client, url, contentType string, body []byte, username, password string, check func([]byte) error) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", contentType)
+	if username != "" {
+		req.SetBasicAuth(username, password)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("log store returned %d: %s", resp.StatusCode, bytes.TrimSpace(respBody))
+	}
+	if check != nil {
+		return check(respBody)
+	}
+	return nil
+}
+
+// ============================================================================
+// Shipper
+// ============================================================================
+
+// ShippingStats counts the lines a shipper handled.
+type ShippingStats struct {
+	Shipped uint64 `json:"shipped"`
+	Dropped uint64 `json:"dropped"`
+	Failed  uint64 `json:"failed"`
+}
+
+// Shipper is an io.Writer that ships log lines to a sink in batches from a
+// goroutine. Writes never block: when the buffer is full because the sink
+// is slow or down, lines are dropped and counted, and the count is reported
+// on the error output.
+type Shipper struct {
+	sink   Sink
+	cfg    ShippingConfig
+	errOut io.Writer
+
+	lines chan []byte
+	done  chan struct{}
+
+	mu     sync.RWMutex
+	closed bool
+
+	shipped atomic.Uint64
+	dropped atomic.Uint64
+	failed  atomic.Uint64
+}
+
+// NewShipper starts shipping to sink.
+func NewShipper(sink Sink, cfg ShippingConfig) *Shipper {
+	cfg = cfg.withDefaults()
+	s := &Shipper{
+		sink:   sink,
+		cfg:    cfg,
+		errOut: os.Stderr,
+		lines:  make(chan []byte, cfg.BufferSize),
+		done:   make(chan struct{}),
+	}
+	go s.run()
+	return s
+}
+
+// Write implements io.Writer; p is one log line.
+func (s *Shipper) Write(p []byte) (int, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.closed {
+		return len(p), nil
+	}
+
+	line := make([]byte, len(p))
+	copy(line, p)
+	select {
+	case s.lines <- line:
+	default:
+		s.dropped.Add(1)
+	}
+	return len(p), nil
+}
+
+// Stats returns the lines shipped, dropped because the buffer was full and
+// lost because the sink failed.
+func (s *Shipper) Stats() ShippingStats {
+	return ShippingStats{Shipped: s.shipped.Load(), Dropped: s.dropped.Load(), Failed: s.failed.Load()}
+}
+
+// Close ships the buffered lines, waiting until ctx is done at most.
+func (s *Shipper) Close(ctx context.Context) error {
+	s.mu.Lock()
+	if !s.closed {
+		s.closed = true
+		close(s.lines)
+	}
+	s.mu.Unlock()
+
+	select {
+	case <-s.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (s *Shipper) run() {
+	defer close(s.done)
+
+	ticker := time.NewTicker(s.cfg.FlushInterval)
+	defer ticker.Stop()
+
+	batch := make([][]byte, 0, s.cfg.BatchSize)
+	var reported uint64
+	for {
+		select {
+		case line, ok := <-s.lines:
+			if !ok {
+				s.flush(batch)
+				return
+			}
+			batch = append(batch, line)
+			if len(batch) < s.cfg.BatchSize {
+				continue
+			}
+		case <-ticker.C:
+			if dropped := s.dropped.Load(); dropped > reported {
+				fmt.Fprintf(s.errOut, "log shipping: dropped %d lines, the %s buffer is full\n", dropped-reported, s.cfg.Sink)
+				reported = dropped
+			}
+		}
+		batch = s.flush(batch)
+	}
+}
+
+// flush sends a batch, retrying twice, and returns the emptied batch.
+func (s *Shipper) flush(batch [][]byte) [][]byte {
+	if len(batch) == 0 {
+		return batch
+	}
+
+	var err error
+	for attempt := 0; attempt < 3; attempt++ {
+		if attempt > 0 {
+			time.Sleep(time.Duration(attempt) * 500 * time.Millisecond)
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), s.cfg.Timeout)
+		err = s.sink.Send(ctx, batch)
+		cancel()
+		if err == nil {
+			s.shipped.Add(uint64(len(batch)))
+			return batch[:0]
+		}
+	}
+
+	s.failed.Add(uint64(len(batch)))
+	fmt.Fprintf(s.errOut, "log shipping: lost %d lines: %v\n", len(batch), err)
+	return batch[:0]
+}
@@ -0,0 +1,158 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+type recordingServer struct {
+	*httptest.Server
+	mu     sync.Mutex
+	bodies []string
+	status int
+}
+
+func newRecordingServer(t *testing.T) *recordingServer {
+	t.Helper()
+	s := &recordingServer{status: http.StatusOK}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		s.mu.Lock()
+		s.bodies = append(s.bodies, r.URL.Path+" "+string(body))
+		status := s.status
+		s.mu.Unlock()
+		w.WriteHeader(status)
+		if r.URL.Path == "/_bulk" {
+			io.WriteString(w, `{"errors":false}`)
+		}
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+func (s *recordingServer) received() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return strings.Join(s.bodies, "\n")
+}
+
+func TestLogger_ShipsToLoki(t *testing.T) {
+	server := newRecordingServer(t)
+	log := New(Config{
+		Level:      "info",
+		TimeFormat: time.RFC3339Nano,
+		Output:     io.Discard,
+		Shipping:   ShippingConfig{Sink: SinkLoki, URL: server.URL, Labels: map[string]string{"env": "test"}},
+	}).With().Service("sales-service").Logger()
+
+	ctx, c := WithCorrelation(context.Background(), "req-1")
+	c.SetUser("tenant-1", "user-1")
+	FromContext(log.WithContext(ctx)).Warn().Msg("Deal stage changed")
+	log.Debug().Msg("below the level")
+
+	if err := log.Close(context.Background()); err != nil {
+		t.Fatalf("close: %v", err)
+	}
+
+	got := server.received()
+	if !strings.HasPrefix(got, "/loki/api/v1/push ") {
+		t.Fatalf("expected a push to Loki, got %s", got)
+	}
+	var push struct {
+		Streams []struct {
+			Stream map[string]string `json:"stream"`
+			Values [][2]string       `json:"values"`
+		} `json:"streams"`
+	}
+	if err := json.Unmarshal([]byte(strings.TrimPrefix(got, "/loki/api/v1/push ")), &push); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if len(push.Streams) != 1 || len(push.Streams[0].Values) != 1 {
+		t.Fatalf("expected one line in one stream, got %+v", push)
+	}
+	labels := push.Streams[0].Stream
+	if labels["service"] != "sales-service" || labels["level"] != "warn" || labels["env"] != "test" {
+		t.Errorf("unexpected labels %v", labels)
+	}
+	line := push.Streams[0].Values[0][1]
+	for _, field := range []string{`"request_id":"req-1"`, `"tenant_id":"tenant-1"`, `"user_id":"user-1"`} {
+		if !strings.Contains(line, field) {
+			t.Errorf("expected %s in %s", field, line)
+		}
+	}
+	if stats := log.ShippingStats(); stats.Shipped != 1 || stats.Dropped != 0 {
+		t.Errorf("unexpected stats %+v", stats)
+	}
+}
+
+func TestElasticsearchSink(t *testing.T) {
+	server := newRecordingServer(t)
+	sink, err := NewSink(ShippingConfig{Sink: SinkElasticsearch, URL: server.URL + "/", Index: "crm-logs"})
+	if err != nil {
+		t.Fatalf("sink: %v", err)
+	}
+
+	lines := [][]byte{
+		[]byte(`{"level":"info","time":"2026-10-17T08:00:00Z","message":"a"}` + "\n"),
+		[]byte(`{"level":"error","time":"2026-10-18T01:00:00Z","message":"b"}` + "\n"),
+	}
+	if err := sink.Send(context.Background(), lines); err != nil {
+		t.Fatalf("send: %v", err)
+	}
+
+	got := server.received()
+	want := "/_bulk " +
+		`{"create":{"_index":"crm-logs-2026.10.17"}}` + "\n" + `{"level":"info","time":"2026-10-17T08:00:00Z","message":"a"}` + "\n" +
+		`{"create":{"_index":"crm-logs-2026.10.18"}}` + "\n" + `{"level":"error","time":"2026-10-18T01:00:00Z","message":"b"}` + "\n"
+	if got != want {
+		t.Errorf("unexpected bulk request:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+// blockingSink holds every batch until released.
+type blockingSink struct {
+	release chan struct{}
+	sent    chan int
+}
+
+func (s *blockingSink) Send(ctx context.Context, lines [][]byte) error {
+	<-s.release
+	s.sent <- len(lines)
+	return nil
+}
+
+func TestShipper_DropsWhenFull(t *testing.T) {
+	sink := &blockingSink{release: make(chan struct{}), sent: make(chan int, 10)}
+	shipper := NewShipper(sink, ShippingConfig{Sink: "test", BufferSize: 2, BatchSize: 1, FlushInterval: time.Hour})
+	var errOut bytes.Buffer
+	shipper.errOut = &errOut
+
+	start := time.Now()
+	for i := 0; i < 10; i++ {
+		if _, err := shipper.Write([]byte(`{"message":"x"}`)); err != nil {
+			t.Fatalf("write: %v", err)
+		}
+	}
+	if time.Since(start) > time.Second {
+		t.Error("expected writes not to wait for the sink")
+	}
+
+	close(sink.release)
+	if err := shipper.Close(context.Background()); err != nil {
+		t.Fatalf("close: %v", err)
+	}
+
+	// One line is with the sink and two are buffered
+	stats := shipper.Stats()
+	if stats.Shipped+stats.Dropped != 10 || stats.Dropped < 7 {
+		t.Errorf("unexpected stats %+v", stats)
+	}
+}
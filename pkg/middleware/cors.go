@@ -0,0 +1,177 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/kilang-desa-murni/crm/pkg/config"
+	"github.com/kilang-desa-murni/crm/pkg/errors"
+	"github.com/kilang-desa-murni/crm/pkg/response"
+)
+
+// originTenantsKey is the context key for the tenants whose origin
+// allow-lists admitted a request's origin.
+const originTenantsKey contextKey = "origin_tenants"
+
+// TenantOriginSource looks up the tenants that allowed an origin for their
+// users.
+type TenantOriginSource interface {
+	TenantsForOrigin(origin string) []string
+}
+
+// CORSPolicy is a cross-origin resource sharing policy.
+type CORSPolicy struct {
+	origins     []string
+	methods     string
+	headers     []string
+	exposed     string
+	credentials bool
+	maxAge      string
+	tenants     TenantOriginSource
+}
+
+// NewCORSPolicy creates the CORS policy of an environment. Origins allowed
+// only by tenants, looked up in tenants when it is not nil, are admitted for
+// those tenants' users; see RequireOriginTenant.
+func NewCORSPolicy(cfg config.CORSConfig, environment string, tenants TenantOriginSource) *CORSPolicy {
+	policy := &CORSPolicy{
+		origins:     cfg.Origins(environment),
+		methods:     strings.Join(cfg.AllowedMethods, ", "),
+		headers:     cfg.AllowedHeaders,
+		exposed:     strings.Join(cfg.ExposedHeaders, ", "),
+		credentials: cfg.AllowCredentials,
+		tenants:     tenants,
+	}
+	if cfg.MaxAge > 0 {
+		policy.maxAge = strconv.Itoa(int(cfg.MaxAge.Seconds()))
+	}
+	return policy
+}
+
+// allow reports whether origin is allowed and, when it is allowed only by
+// tenants, which tenants allowed it.
+func (p *CORSPolicy) allow(origin string) (bool, []string) {
+	for _, pattern := range p.origins {
+		if pattern != "*" && config.MatchOrigin([]string{pattern}, origin) {
+			return true, nil
+		}
+	}
+	if p.tenants != nil {
+		if tenants := p.tenants.TenantsForOrigin(origin); len(tenants) > 0 {
+			return true, tenants
+		}
+	}
+	return false, nil
+}
+
+// anyOrigin reports whether the policy allows every origin.
+func (p *CORSPolicy) anyOrigin() bool {
+	for _, pattern := range p.origins {
+		if pattern == "*" {
+			return true
+		}
+	}
+	return false
+}
+
+// allowedHeaders returns the headers a preflight request may ask for. "*"
+// allows the requested headers, as browsers do not treat it as a wildcard
+// for credentialed requests.
+func (p *CORSPolicy) allowedHeaders(r *http.Request) string {
+	for _, header := range p.headers {
+		if header == "*" {
+			return r.Header.Get("Access-Control-Request-Headers")
+		}
+	}
+	return strings.Join(p.headers, ", ")
+}
+
+// CORSWithPolicy handles Cross-Origin Resource Sharing with a policy. An
+// allowed origin is echoed back, with credentials when the policy allows
+// them; other origins get no CORS headers, so browsers block the response.
+// Preflight requests are answered here.
+func CORSWithPolicy(policy *CORSPolicy) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			origin := r.Header.Get("Origin")
+			preflight := r.Method == http.MethodOptions && r.Header.Get("Access-Control-Request-Method") != ""
+
+			// Responses differ by origin, so shared caches must key on it
+			w.Header().Add("Vary", "Origin")
+			if preflight {
+				w.Header().Add("Vary", "Access-Control-Request-Method")
+				w.Header().Add("Vary", "Access-Control-Request-Headers")
+			}
+
+			allowed, tenants := policy.allow(origin)
+			switch {
+			case allowed:
+				w.Header().Set("Access-Control-Allow-Origin", origin)
+				if policy.credentials {
+					w.Header().Set("Access-Control-Allow-Credentials", "true")
+				}
+			case origin != "" && policy.anyOrigin():
+				w.Header().Set("Access-Control-Allow-Origin", "*")
+			}
+
+			if origin != "" && (allowed || policy.anyOrigin()) {
+				if preflight {
+					w.Header().Set("Access-Control-Allow-Methods", policy.methods)
+					w.Header().Set("Access-Control-Allow-Headers", policy.allowedHeaders(r))
+					if policy.maxAge != "" {
+						w.Header().Set("Access-Control-Max-Age", policy.maxAge)
+					}
+				} else if policy.exposed != "" {
+					w.Header().Set("Access-Control-Expose-Headers", policy.exposed)
+				}
+			}
+
+			if preflight {
+				w.WriteHeader(http.StatusNoContent)
+				return
+			}
+
+			if tenants != nil {
+				r = r.WithContext(context.WithValue(r.Context(), originTenantsKey, tenants))
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// CORS handles Cross-Origin Resource Sharing for a fixed list of origins.
+// Credentials are allowed for the listed origins but not for "*".
+func CORS(allowedOrigins []string, allowedMethods []string, allowedHeaders []string) func(http.Handler) http.Handler {
+	return CORSWithPolicy(NewCORSPolicy(config.CORSConfig{
+		AllowedOrigins:   allowedOrigins,
+		AllowedMethods:   allowedMethods,
+		AllowedHeaders:   allowedHeaders,
+		AllowCredentials: true,
+		MaxAge:           24 * time.Hour,
+	}, "", nil))
+}
+
+// RequireOriginTenant refuses requests whose origin was allowed only by
+// tenant allow-lists when the authenticated user belongs to none of those
+// tenants. It must run after authentication.
+func RequireOriginTenant(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		tenants, ok := r.Context().Value(originTenantsKey).([]string)
+		if !ok {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		tenantID := TenantIDFromContext(r.Context())
+		for _, tenant := range tenants {
+			if tenant == tenantID {
+				next.ServeHTTP(w, r)
+				return
+			}
+		}
+		response.Error(w, errors.ErrForbidden("Origin not allowed for this tenant"))
+	})
+}
@@ -0,0 +1,246 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	stderrors "errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/kilang-desa-murni/crm/pkg/database"
+	"github.com/kilang-desa-murni/crm/pkg/errors"
+	"github.com/kilang-desa-murni/crm/pkg/response"
+)
+
+// IdempotencyKeyHeader is the header a client sets to make a request safe to
+// retry.
+const IdempotencyKeyHeader = "Idempotency-Key"
+
+// maxIdempotencyKeyLength bounds the length of an idempotency key.
+const maxIdempotencyKeyLength = 255
+
+// IdempotencyRecord is the stored state of an idempotent request. A record
+// that is not completed marks a request that is still being processed.
+type IdempotencyRecord struct {
+	RequestHash string      `json:"request_hash"`
+	Completed   bool        `json:"completed"`
+	StatusCode  int         `json:"status_code,omitempty"`
+	Header      http.Header `json:"header,omitempty"`
+	Body        []byte      `json:"body,omitempty"`
+}
+
+// IdempotencyStore defines the interface for storing idempotent requests.
+type IdempotencyStore interface {
+	// Reserve stores record under key unless the key is already taken, in
+	// which case it returns the stored record. It returns nil when the key
+	// was reserved.
+	Reserve(ctx context.Context, key string, record *IdempotencyRecord, ttl time.Duration) (*IdempotencyRecord, error)
+
+	// Save replaces the record stored under key.
+	Save(ctx context.Context, key string, record *IdempotencyRecord, ttl time.Duration) error
+
+	// Release deletes the record stored under key.
+	Release(ctx context.Context, key string) error
+}
+
+// IdempotencyConfig holds idempotency middleware configuration.
+type IdempotencyConfig struct {
+	TTL         time.Duration // How long responses are kept for replay
+	Methods     []string      // Methods that honor the header
+	KeyFunc     func(*http.Request) string
+	MaxBodySize int64 // Largest body read to hash a request
+}
+
+// DefaultIdempotencyConfig returns the default idempotency configuration:
+// POST responses are kept for 24 hours, per user, for bodies up to 10 MB.
+func DefaultIdempotencyConfig() IdempotencyConfig {
+	return IdempotencyConfig{
+		TTL:         24 * time.Hour,
+		Methods:     []string{http.MethodPost},
+		KeyFunc:     UserKeyFunc,
+		MaxBodySize: 10 << 20,
+	}
+}
+
+// RedisIdempotencyStore implements IdempotencyStore using Redis.
+type RedisIdempotencyStore struct {
+	redis *database.RedisClient
+}
+
+// NewRedisIdempotencyStore creates a new Redis-backed idempotency store.
+func NewRedisIdempotencyStore(redis *database.RedisClient) *RedisIdempotencyStore {
+	return &RedisIdempotencyStore{redis: redis}
+}
+
+// Reserve stores record under key unless the key is already taken.
+func (s *RedisIdempotencyStore) Reserve(ctx context.Context, key string, record *IdempotencyRecord, ttl time.Duration) (*IdempotencyRecord, error) {
+	redisKey := fmt.Sprintf("idempotency:%s", key)
+
+	reserved, err := s.redis.SetNX(ctx, redisKey, record, ttl)
+	if err != nil {
+		return nil, fmt.Errorf("failed to reserve idempotency key: %w", err)
+	}
+	if reserved {
+		return nil, nil
+	}
+
+	var existing IdempotencyRecord
+	if err := s.redis.Get(ctx, redisKey, &existing); err != nil {
+		return nil, fmt.Errorf("failed to get idempotency record: %w", err)
+	}
+	return &existing, nil
+}
+
+// Save replaces the record stored under key.
+func (s *RedisIdempotencyStore) Save(ctx context.Context, key string, record *IdempotencyRecord, ttl time.Duration) error {
+	return s.redis.Set(ctx, fmt.Sprintf("idempotency:%s", key), record, ttl)
+}
+
+// Release deletes the record stored under key.
+func (s *RedisIdempotencyStore) Release(ctx context.Context, key string) error {
+	return s.redis.Delete(ctx, fmt.Sprintf("idempotency:%s", key))
+}
+
+// Idempotency creates middleware that honors the Idempotency-Key header. The
+// first request with a key is processed and its response stored; a retry with
+// the same key and payload replays the stored response, and a retry with a
+// different payload is rejected. Server errors are not stored, so the request
+// can be retried.
+func Idempotency(store IdempotencyStore, config IdempotencyConfig) func(http.Handler) http.Handler {
+	defaults := DefaultIdempotencyConfig()
+	if config.TTL <= 0 {
+		config.TTL = defaults.TTL
+	}
+	if len(config.Methods) == 0 {
+		config.Methods = defaults.Methods
+	}
+	if config.KeyFunc == nil {
+		config.KeyFunc = defaults.KeyFunc
+	}
+	if config.MaxBodySize <= 0 {
+		config.MaxBodySize = defaults.MaxBodySize
+	}
+
+	methods := make(map[string]bool, len(config.Methods))
+	for _, method := range config.Methods {
+		methods[method] = true
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			idempotencyKey := r.Header.Get(IdempotencyKeyHeader)
+			if idempotencyKey == "" || !methods[r.Method] {
+				next.ServeHTTP(w, r)
+				return
+			}
+			if len(idempotencyKey) > maxIdempotencyKeyLength {
+				response.Error(w, errors.ErrBadRequest(fmt.Sprintf("Idempotency-Key must be at most %d characters", maxIdempotencyKeyLength)))
+				return
+			}
+
+			body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, config.MaxBodySize))
+			if err != nil {
+				var maxBytesErr *http.MaxBytesError
+				if stderrors.As(err, &maxBytesErr) {
+					response.Error(w, errors.ErrPayloadTooLarge(maxBytesErr.Limit))
+					return
+				}
+				response.Error(w, errors.ErrBadRequest("Failed to read request body"))
+				return
+			}
+			r.Body = io.NopCloser(bytes.NewReader(body))
+
+			hash := sha256.New()
+			hash.Write([]byte(r.Method + " " + r.URL.Path + "\n"))
+			hash.Write(body)
+			requestHash := hex.EncodeToString(hash.Sum(nil))
+
+			// The response is stored even if the client goes away, since a
+			// retry is what the key is for.
+			ctx := context.WithoutCancel(r.Context())
+			key := config.KeyFunc(r) + ":" + idempotencyKey
+
+			existing, err := store.Reserve(ctx, key, &IdempotencyRecord{RequestHash: requestHash}, config.TTL)
+			if err != nil {
+				response.Error(w, errors.ErrInternal("Idempotency check failed"))
+				return
+			}
+			if existing != nil {
+				switch {
+				case existing.RequestHash != requestHash:
+					response.Error(w, errors.ErrIdempotencyKeyReused())
+				case !existing.Completed:
+					response.Error(w, errors.ErrConflict("A request with this Idempotency-Key is still being processed"))
+				default:
+					replayIdempotentResponse(w, existing)
+				}
+				return
+			}
+
+			rec := &idempotencyRecorder{ResponseWriter: w, statusCode: http.StatusOK}
+			defer func() {
+				if p := recover(); p != nil {
+					_ = store.Release(ctx, key)
+					panic(p)
+				}
+			}()
+
+			next.ServeHTTP(rec, r)
+
+			if rec.statusCode >= http.StatusInternalServerError {
+				_ = store.Release(ctx, key)
+				return
+			}
+			_ = store.Save(ctx, key, &IdempotencyRecord{
+				RequestHash: requestHash,
+				Completed:   true,
+				StatusCode:  rec.statusCode,
+				Header:      rec.header,
+				Body:        rec.body.Bytes(),
+			}, config.TTL)
+		})
+	}
+}
+
+// replayIdempotentResponse writes a stored response. Headers already set by
+// outer middleware, such as the request ID, are kept.
+func replayIdempotentResponse(w http.ResponseWriter, record *IdempotencyRecord) {
+	for name, values := range record.Header {
+		if w.Header().Get(name) == "" {
+			w.Header()[name] = values
+		}
+	}
+	w.Header().Set("Idempotent-Replayed", "true")
+	w.WriteHeader(record.StatusCode)
+	_, _ = w.Write(record.Body)
+}
+
+// idempotencyRecorder wraps http.ResponseWriter to capture the response.
+type idempotencyRecorder struct {
+	http.ResponseWriter
+	statusCode  int
+	header      http.Header
+	body        bytes.Buffer
+	wroteHeader bool
+}
+
+func (w *idempotencyRecorder) WriteHeader(code int) {
+	if !w.wroteHeader {
+		w.wroteHeader = true
+		w.statusCode = code
+		w.header = w.ResponseWriter.Header().Clone()
+	}
+	w.ResponseWriter.WriteHeader(code)
+}
+
+func (w *idempotencyRecorder) Write(b []byte) (int, error) {
+	if !w.wroteHeader {
+		w.WriteHeader(http.StatusOK)
+	}
+	w.body.Write(b)
+	return w.ResponseWriter.Write(b)
+}
@@ -0,0 +1,206 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// memoryIdempotencyStore is an in-memory IdempotencyStore.
+type memoryIdempotencyStore struct {
+	mu      sync.Mutex
+	records map[string]*IdempotencyRecord
+}
+
+func newMemoryIdempotencyStore() *memoryIdempotencyStore {
+	return &memoryIdempotencyStore{records: make(map[string]*IdempotencyRecord)}
+}
+
+func (s *memoryIdempotencyStore) Reserve(ctx context.Context, key string, record *IdempotencyRecord, ttl time.Duration) (*IdempotencyRecord, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if existing, ok := s.records[key]; ok {
+		copied := *existing
+		return &copied, nil
+	}
+	s.records[key] = record
+	return nil, nil
+}
+
+func (s *memoryIdempotencyStore) Save(ctx context.Context, key string, record *IdempotencyRecord, ttl time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.records[key] = record
+	return nil
+}
+
+func (s *memoryIdempotencyStore) Release(ctx context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.records, key)
+	return nil
+}
+
+func (s *memoryIdempotencyStore) has(key string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, ok := s.records[key]
+	return ok
+}
+
+func idempotencyTestConfig() IdempotencyConfig {
+	config := DefaultIdempotencyConfig()
+	config.KeyFunc = func(*http.Request) string { return "user:1" }
+	return config
+}
+
+func idempotentRequest(key, body string) *http.Request {
+	r := httptest.NewRequest(http.MethodPost, "/api/v1/leads", strings.NewReader(body))
+	r.Header.Set(IdempotencyKeyHeader, key)
+	return r
+}
+
+func TestIdempotency_ReplaysCompletedResponse(t *testing.T) {
+	store := newMemoryIdempotencyStore()
+	calls := 0
+	handler := Idempotency(store, idempotencyTestConfig())(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.Header().Set("Location", "/api/v1/leads/1")
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte(`{"id":"1"}`))
+	}))
+
+	first := httptest.NewRecorder()
+	handler.ServeHTTP(first, idempotentRequest("k1", `{"name":"Aminah"}`))
+	second := httptest.NewRecorder()
+	handler.ServeHTTP(second, idempotentRequest("k1", `{"name":"Aminah"}`))
+
+	if calls != 1 {
+		t.Errorf("expected the handler to run once, ran %d times", calls)
+	}
+	if second.Code != http.StatusCreated || second.Body.String() != `{"id":"1"}` {
+		t.Errorf("expected the stored 201 to be replayed, got %d %s", second.Code, second.Body.String())
+	}
+	if second.Header().Get("Idempotent-Replayed") != "true" || second.Header().Get("Location") != "/api/v1/leads/1" {
+		t.Errorf("expected replayed headers, got %v", second.Header())
+	}
+	if first.Header().Get("Idempotent-Replayed") != "" {
+		t.Error("expected the first response not to be marked as replayed")
+	}
+}
+
+func TestIdempotency_Rejections(t *testing.T) {
+	tests := []struct {
+		name       string
+		stored     *IdempotencyRecord
+		body       string
+		wantStatus int
+	}{
+		{
+			name:       "key reused with a different payload",
+			stored:     &IdempotencyRecord{Completed: true, StatusCode: http.StatusCreated},
+			body:       `{"name":"Siti"}`,
+			wantStatus: http.StatusUnprocessableEntity,
+		},
+		{
+			name:       "first request still running",
+			body:       `{"name":"Aminah"}`,
+			wantStatus: http.StatusConflict,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := newMemoryIdempotencyStore()
+			started, finish := make(chan struct{}), make(chan struct{})
+			handler := Idempotency(store, idempotencyTestConfig())(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				close(started)
+				<-finish
+				w.WriteHeader(http.StatusCreated)
+			}))
+
+			done := make(chan struct{})
+			go func() {
+				defer close(done)
+				handler.ServeHTTP(httptest.NewRecorder(), idempotentRequest("k1", `{"name":"Aminah"}`))
+			}()
+			<-started
+			if tt.stored != nil {
+				// Let the first request complete before the retry
+				close(finish)
+				<-done
+			}
+
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, idempotentRequest("k1", tt.body))
+			if w.Code != tt.wantStatus {
+				t.Errorf("expected %d, got %d: %s", tt.wantStatus, w.Code, w.Body.String())
+			}
+
+			if tt.stored == nil {
+				close(finish)
+				<-done
+			}
+		})
+	}
+}
+
+func TestIdempotency_ReleasesKeyOnFailure(t *testing.T) {
+	tests := []struct {
+		name    string
+		handler http.HandlerFunc
+	}{
+		{
+			name: "server error",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusBadGateway)
+			},
+		},
+		{
+			name: "panic",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				panic("backend exploded")
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := newMemoryIdempotencyStore()
+			handler := Idempotency(store, idempotencyTestConfig())(tt.handler)
+
+			func() {
+				defer func() { _ = recover() }()
+				handler.ServeHTTP(httptest.NewRecorder(), idempotentRequest("k1", `{}`))
+			}()
+
+			if store.has("user:1:k1") {
+				t.Error("expected the key to be released so the request can be retried")
+			}
+		})
+	}
+}
+
+func TestIdempotency_BodyLimit(t *testing.T) {
+	store := newMemoryIdempotencyStore()
+	config := idempotencyTestConfig()
+	config.MaxBodySize = 16
+	called := false
+	handler := Idempotency(store, config)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		called = true
+	}))
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, idempotentRequest("k1", strings.Repeat("x", 17)))
+
+	if w.Code != http.StatusRequestEntityTooLarge {
+		t.Errorf("expected 413, got %d", w.Code)
+	}
+	if called || store.has("user:1:k1") {
+		t.Error("expected an oversized request to be refused before it is reserved")
+	}
+}
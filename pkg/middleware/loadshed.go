@@ -0,0 +1,158 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/kilang-desa-murni/crm/pkg/config"
+	apperrors "github.com/kilang-desa-murni/crm/pkg/errors"
+	"github.com/kilang-desa-murni/crm/pkg/logger"
+	"github.com/kilang-desa-murni/crm/pkg/resilience"
+	"github.com/kilang-desa-murni/crm/pkg/response"
+)
+
+// PriorityHeader marks background requests, such as imports, exports and
+// bulk operations, with PriorityBackground. The API gateway sets it and
+// replaces any value sent by clients.
+const PriorityHeader = "X-Request-Priority"
+
+// PriorityBackground is the PriorityHeader value of background requests.
+const PriorityBackground = "background"
+
+// LoadShedder limits the concurrent requests of a service to an adaptive
+// limit, so a spike is queued briefly or shed with 503 instead of slowing
+// every request down.
+type LoadShedder struct {
+	service string
+	cfg     config.LoadShedConfig
+	limiter *resilience.AdaptiveLimiter
+	log     *logger.Logger
+}
+
+// NewLoadShedder creates the load shedder of a service.
+func NewLoadShedder(service string, cfg config.LoadShedConfig, log *logger.Logger) (*LoadShedder, error) {
+	switch cfg.Algorithm {
+	case resilience.LimitAlgorithmAIMD, resilience.LimitAlgorithmGradient:
+	default:
+		return nil, fmt.Errorf("unknown load shedding algorithm %q", cfg.Algorithm)
+	}
+
+	return &LoadShedder{
+		service: service,
+		cfg:     cfg,
+		limiter: resilience.NewAdaptiveLimiter(resilience.LimiterConfig{
+			Name:             service,
+			Algorithm:        cfg.Algorithm,
+			InitialLimit:     cfg.InitialLimit,
+			MinLimit:         cfg.MinLimit,
+			MaxLimit:         cfg.MaxLimit,
+			LatencyThreshold: cfg.LatencyThreshold,
+			QueueSize:        cfg.QueueSize,
+			QueueTimeout:     cfg.QueueTimeout,
+			BackgroundShare:  cfg.BackgroundShare,
+		}),
+		log: log,
+	}, nil
+}
+
+// Middleware admits requests within the limit. Requests to the bypass paths
+// are always admitted, and background requests only within their share of
+// the limit, without waiting. Responses with 503 or 504 and requests whose context
+// ended count as overload and lower the limit.
+func (s *LoadShedder) Middleware(next http.Handler) http.Handler {
+	if !s.cfg.Enabled {
+		return next
+	}
+	retryAfter := strconv.Itoa(max(int(s.cfg.RetryAfter.Seconds()), 1))
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var release resilience.LimiterRelease
+		if s.bypassed(r.URL.Path) {
+			release = s.limiter.Admit()
+		} else {
+			var err error
+			if r.Header.Get(PriorityHeader) == PriorityBackground {
+				release, err = s.limiter.AcquireBackground()
+			} else {
+				release, err = s.limiter.Acquire(r.Context())
+			}
+			if err != nil {
+				if errors.Is(err, context.Canceled) {
+					return
+				}
+				s.log.Warn().
+					Err(err).
+					Str("method", r.Method).
+					Str("path", r.URL.Path).
+					Msg("Request shed")
+				w.Header().Set("Retry-After", retryAfter)
+				response.Error(w, apperrors.ErrServiceUnavailable(s.service))
+				return
+			}
+		}
+
+		sw := &statusCapture{ResponseWriter: w, status: http.StatusOK}
+		defer func() {
+			overloaded := sw.status == http.StatusServiceUnavailable || sw.status == http.StatusGatewayTimeout ||
+				errors.Is(r.Context().Err(), context.DeadlineExceeded)
+			release(overloaded)
+		}()
+		next.ServeHTTP(sw, r)
+	})
+}
+
+func (s *LoadShedder) bypassed(path string) bool {
+	for _, prefix := range s.cfg.BypassPaths {
+		if strings.HasPrefix(path, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// WriteMetrics writes the limiter state in the Prometheus text format.
+func (s *LoadShedder) WriteMetrics(w io.Writer) {
+	stats := s.limiter.Stats()
+	metrics := []struct {
+		name  string
+		kind  string
+		help  string
+		value float64
+	}{
+		{"http_concurrency_limit", "gauge", "Adaptive limit of concurrent requests.", float64(stats.Limit)},
+		{"http_concurrency_in_flight", "gauge", "Requests being served.", float64(stats.InFlight)},
+		{"http_concurrency_queued", "gauge", "Requests waiting for the limit.", float64(stats.Queued)},
+		{"http_concurrency_admitted_total", "counter", "Requests admitted within the limit.", float64(stats.Admitted)},
+		{"http_concurrency_bypassed_total", "counter", "Requests admitted regardless of the limit.", float64(stats.Bypassed)},
+	}
+	for _, m := range metrics {
+		fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s %s\n", m.name, m.help, m.name, m.kind)
+		fmt.Fprintf(w, "%s{service=%q} %g\n", m.name, s.service, m.value)
+	}
+
+	fmt.Fprintf(w, "# HELP http_requests_shed_total Requests refused with 503 by the concurrency limit.\n# TYPE http_requests_shed_total counter\n")
+	fmt.Fprintf(w, "http_requests_shed_total{service=%q,reason=\"queue_full\"} %d\n", s.service, stats.ShedQueueFull)
+	fmt.Fprintf(w, "http_requests_shed_total{service=%q,reason=\"queue_timeout\"} %d\n", s.service, stats.ShedTimeout)
+	fmt.Fprintf(w, "http_requests_shed_total{service=%q,reason=\"background\"} %d\n", s.service, stats.ShedBackground)
+}
+
+// statusCapture records the status code of a response.
+type statusCapture struct {
+	http.ResponseWriter
+	status int
+}
+
+func (w *statusCapture) WriteHeader(code int) {
+	w.status = code
+	w.ResponseWriter.WriteHeader(code)
+}
+
+// Unwrap lets http.ResponseController flush streamed responses.
+func (w *statusCapture) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
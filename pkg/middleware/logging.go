@@ -0,0 +1,162 @@
+package middleware
+
+import (
+	"context"
+	"io"
+	"math/rand"
+	"net/http"
+	"time"
+
+	"github.com/kilang-desa-murni/crm/pkg/config"
+	"github.com/kilang-desa-murni/crm/pkg/logger"
+)
+
+// RequestLogger logs requests. Successful requests faster than
+// cfg.SlowThreshold are sampled at cfg.SampleRate; errors and slow requests
+// are always logged in full, with their query, headers and the first
+// cfg.BodyLimit bytes of both bodies. Emails, phone numbers, tokens and the
+// configured fields are redacted.
+func RequestLogger(log *logger.Logger, cfg config.RequestLogConfig) func(http.Handler) http.Handler {
+	redactor := logger.NewRedactor(cfg.RedactFields...)
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+
+			// Wrap response writer to capture status code and body
+			wrapped := &responseWriter{ResponseWriter: w, statusCode: http.StatusOK, bodyLimit: cfg.BodyLimit}
+
+			// Keep the start of the request body as the handler reads it
+			var body *bodyCapture
+			if cfg.BodyLimit > 0 && r.Body != nil && r.Body != http.NoBody {
+				body = &bodyCapture{ReadCloser: r.Body, limit: cfg.BodyLimit}
+				r.Body = body
+			}
+
+			// Add start time to context
+			ctx := context.WithValue(r.Context(), StartTimeKey, start)
+
+			// Services behind the gateway get the request ID in the header
+			requestID := RequestIDFromContext(r.Context())
+			if requestID == "" {
+				requestID = r.Header.Get("X-Request-ID")
+			}
+			reqLog := log.With().
+				Str("method", r.Method).
+				Str("path", r.URL.Path).
+				Str("remote_addr", r.RemoteAddr).
+				Logger()
+
+			// Add logger to context; logger.FromContext adds the request ID,
+			// the trace and the tenant once authentication has found it
+			ctx, _ = logger.WithCorrelation(ctx, requestID)
+			ctx = reqLog.WithContext(ctx)
+
+			// Process request
+			next.ServeHTTP(wrapped, r.WithContext(ctx))
+
+			// Log request completion
+			duration := time.Since(start)
+			slow := cfg.SlowThreshold > 0 && duration >= cfg.SlowThreshold
+			full := wrapped.statusCode >= 400 || slow
+
+			// Authentication has recorded the tenant and user by now
+			reqLog = reqLog.Correlated(ctx)
+
+			var event *logger.Event
+			switch {
+			case wrapped.statusCode >= 500:
+				event = reqLog.Error()
+			case full:
+				event = reqLog.Warn()
+			case rand.Float64() < cfg.SampleRate:
+				event = reqLog.Info()
+			default:
+				return
+			}
+
+			event.
+				Int("status", wrapped.statusCode).
+				Dur("duration", duration).
+				Int("bytes", wrapped.bytesWritten).
+				Str("user_agent", r.UserAgent())
+
+			if !full {
+				if cfg.SampleRate < 1 {
+					event.Float64("sample_rate", cfg.SampleRate)
+				}
+				event.Msg("Request completed")
+				return
+			}
+
+			if slow {
+				event.Bool("slow", true)
+			}
+			if r.URL.RawQuery != "" {
+				event.Str("query", redactor.Query(r.URL.Query()))
+			}
+			event.Interface("request_headers", redactor.Header(r.Header))
+			if body != nil && len(body.buf) > 0 {
+				event.Str("request_body", redactor.Body(r.Header.Get("Content-Type"), body.buf))
+				if body.truncated {
+					event.Bool("request_body_truncated", true)
+				}
+			}
+			if len(wrapped.body) > 0 {
+				event.Str("response_body", redactor.Body(wrapped.Header().Get("Content-Type"), wrapped.body))
+				if wrapped.bytesWritten > len(wrapped.body) {
+					event.Bool("response_body_truncated", true)
+				}
+			}
+			event.Msg("Request completed")
+		})
+	}
+}
+
+// bodyCapture keeps the first limit bytes read from a request body.
+type bodyCapture struct {
+	io.ReadCloser
+	limit     int
+	buf       []byte
+	truncated bool
+}
+
+func (b *bodyCapture) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	if room := b.limit - len(b.buf); room > 0 {
+		b.buf = append(b.buf, p[:min(n, room)]...)
+		b.truncated = n > room
+	} else if n > 0 {
+		b.truncated = true
+	}
+	return n, err
+}
+
+// responseWriter wraps http.ResponseWriter to capture the status code, bytes
+// written and the first bodyLimit bytes of the body.
+type responseWriter struct {
+	http.ResponseWriter
+	statusCode   int
+	bytesWritten int
+	bodyLimit    int
+	body         []byte
+}
+
+func (w *responseWriter) WriteHeader(code int) {
+	w.statusCode = code
+	w.ResponseWriter.WriteHeader(code)
+}
+
+func (w *responseWriter) Write(b []byte) (int, error) {
+	n, err := w.ResponseWriter.Write(b)
+	if room := w.bodyLimit - len(w.body); room > 0 {
+		w.body = append(w.body, b[:min(n, room)]...)
+	}
+	w.bytesWritten += n
+	return n, err
+}
+
+// Unwrap lets http.ResponseController flush streamed responses.
+func (w *responseWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
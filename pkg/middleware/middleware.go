@@ -10,6 +10,7 @@ import (
 	"github.com/google/uuid"
 
 	"github.com/kilang-desa-murni/crm/pkg/auth"
+	"github.com/kilang-desa-murni/crm/pkg/config"
 	"github.com/kilang-desa-murni/crm/pkg/errors"
 	"github.com/kilang-desa-murni/crm/pkg/logger"
 	"github.com/kilang-desa-murni/crm/pkg/response"
@@ -51,77 +52,23 @@ func RequestIDFromContext(ctx context.Context) string {
 	return ""
 }
 
-// Logger logs each request with relevant information.
+// Logger logs every request with relevant information. Services configure
+// sampling, body capture and redaction with RequestLogger.
 func Logger(log *logger.Logger) func(http.Handler) http.Handler {
-	return func(next http.Handler) http.Handler {
-		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-			start := time.Now()
-
-			// Wrap response writer to capture status code
-			wrapped := &responseWriter{ResponseWriter: w, statusCode: http.StatusOK}
-
-			// Add start time to context
-			ctx := context.WithValue(r.Context(), StartTimeKey, start)
-
-			// Get logger with context fields
-			requestID := RequestIDFromContext(r.Context())
-			reqLog := log.With().
-				Str("method", r.Method).
-				Str("path", r.URL.Path).
-				Str("remote_addr", r.RemoteAddr).
-				RequestID(requestID).
-				Logger()
-
-			// Add logger to context
-			ctx = reqLog.WithContext(ctx)
-
-			// Process request
-			next.ServeHTTP(wrapped, r.WithContext(ctx))
-
-			// Log request completion
-			duration := time.Since(start)
-			event := reqLog.Info()
-			if wrapped.statusCode >= 400 {
-				event = reqLog.Warn()
-			}
-			if wrapped.statusCode >= 500 {
-				event = reqLog.Error()
-			}
-
-			event.
-				Int("status", wrapped.statusCode).
-				Dur("duration", duration).
-				Int("bytes", wrapped.bytesWritten).
-				Str("user_agent", r.UserAgent()).
-				Msg("Request completed")
-		})
-	}
+	return RequestLogger(log, config.RequestLogConfig{SampleRate: 1, SlowThreshold: time.Second})
 }
 
-// responseWriter wraps http.ResponseWriter to capture status code and bytes written.
-type responseWriter struct {
-	http.ResponseWriter
-	statusCode   int
-	bytesWritten int
-}
-
-func (w *responseWriter) WriteHeader(code int) {
-	w.statusCode = code
-	w.ResponseWriter.WriteHeader(code)
-}
-
-func (w *responseWriter) Write(b []byte) (int, error) {
-	n, err := w.ResponseWriter.Write(b)
-	w.bytesWritten += n
-	return n, err
-}
-
-// Recover recovers from panics and returns a 500 error.
+// Recover recovers from panics and returns a 500 error. http.ErrAbortHandler
+// is passed on, so a handler can still abort a response it has started.
 func Recover(log *logger.Logger) func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			defer func() {
 				if err := recover(); err != nil {
+					if err == http.ErrAbortHandler {
+						panic(err)
+					}
+
 					log.Error().
 						Interface("panic", err).
 						Str("path", r.URL.Path).
@@ -139,65 +86,63 @@ func Recover(log *logger.Logger) func(http.Handler) http.Handler {
 	}
 }
 
-// CORS handles Cross-Origin Resource Sharing.
-func CORS(allowedOrigins []string, allowedMethods []string, allowedHeaders []string) func(http.Handler) http.Handler {
-	return func(next http.Handler) http.Handler {
-		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-			origin := r.Header.Get("Origin")
-
-			// Check if origin is allowed
-			allowed := false
-			for _, allowedOrigin := range allowedOrigins {
-				if allowedOrigin == "*" || allowedOrigin == origin {
-					allowed = true
-					break
-				}
-			}
-
-			if allowed {
-				w.Header().Set("Access-Control-Allow-Origin", origin)
-				w.Header().Set("Access-Control-Allow-Methods", strings.Join(allowedMethods, ", "))
-				w.Header().Set("Access-Control-Allow-Headers", strings.Join(allowedHeaders, ", "))
-				w.Header().Set("Access-Control-Allow-Credentials", "true")
-				w.Header().Set("Access-Control-Max-Age", "86400")
-			}
-
-			// Handle preflight requests
-			if r.Method == http.MethodOptions {
-				w.WriteHeader(http.StatusNoContent)
-				return
-			}
+// TokenValidator validates an access token and returns its claims.
+type TokenValidator func(ctx context.Context, token string) (*auth.Claims, error)
 
-			next.ServeHTTP(w, r)
-		})
+// JWTValidator validates access tokens as JWTs signed by jwtManager's keys.
+func JWTValidator(jwtManager *auth.JWTManager) TokenValidator {
+	return func(ctx context.Context, token string) (*auth.Claims, error) {
+		return jwtManager.ValidateAccessToken(token)
 	}
 }
 
-// Auth authenticates requests using JWT tokens.
+// Auth authenticates requests using JWT tokens. Requests the gateway
+// forwards with signed identity headers, as it does for opaque tokens, are
+// authenticated by those headers instead once an identity secret is
+// configured.
 func Auth(jwtManager *auth.JWTManager) func(http.Handler) http.Handler {
+	return authenticate(JWTValidator(jwtManager), jwtManager)
+}
+
+// authenticate authenticates requests by their bearer token, or by their
+// signed identity headers when identity is not nil.
+func authenticate(validate TokenValidator, identity *auth.JWTManager) func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-			// Extract token from Authorization header
-			authHeader := r.Header.Get("Authorization")
-			if authHeader == "" {
-				response.Error(w, errors.ErrUnauthorized("Missing authorization header"))
-				return
+			var claims *auth.Claims
+			if identity != nil {
+				verified, err := identity.VerifyIdentity(r.Header)
+				if err != nil {
+					response.Error(w, err)
+					return
+				}
+				claims = verified
 			}
 
-			// Check for Bearer token
-			parts := strings.SplitN(authHeader, " ", 2)
-			if len(parts) != 2 || strings.ToLower(parts[0]) != "bearer" {
-				response.Error(w, errors.ErrUnauthorized("Invalid authorization header format"))
-				return
-			}
+			if claims == nil {
+				// Extract token from Authorization header
+				authHeader := r.Header.Get("Authorization")
+				if authHeader == "" {
+					response.Error(w, errors.ErrUnauthorized("Missing authorization header"))
+					return
+				}
 
-			tokenString := parts[1]
+				// Check for Bearer token
+				parts := strings.SplitN(authHeader, " ", 2)
+				if len(parts) != 2 || strings.ToLower(parts[0]) != "bearer" {
+					response.Error(w, errors.ErrUnauthorized("Invalid authorization header format"))
+					return
+				}
 
-			// Validate token
-			claims, err := jwtManager.ValidateAccessToken(tokenString)
-			if err != nil {
-				response.Error(w, err)
-				return
+				tokenString := parts[1]
+
+				// Validate token
+				validated, err := validate(r.Context(), tokenString)
+				if err != nil {
+					response.Error(w, err)
+					return
+				}
+				claims = validated
 			}
 
 			// Add claims to context
@@ -209,11 +154,30 @@ func Auth(jwtManager *auth.JWTManager) func(http.Handler) http.Handler {
 			// Add user ID to context
 			ctx = context.WithValue(ctx, UserIDKey, claims.UserID)
 
+			// Attach the tenant and user to the request's log lines
+			if c := logger.CorrelationFromContext(ctx); c != nil {
+				c.SetUser(claims.TenantID, claims.UserID)
+			}
+
 			next.ServeHTTP(w, r.WithContext(ctx))
 		})
 	}
 }
 
+// ForwardIdentity replaces any identity headers a client sent with the
+// signed identity of the authenticated caller, for the gateway to forward to
+// backend services. Unauthenticated requests are forwarded without identity
+// headers, as are all requests when no identity secret is configured.
+func ForwardIdentity(jwtManager *auth.JWTManager) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			claims, _ := auth.ClaimsFromContext(r.Context())
+			jwtManager.SignIdentity(r.Header, claims)
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
 // TenantIDFromContext extracts the tenant ID from context.
 func TenantIDFromContext(ctx context.Context) string {
 	if id, ok := ctx.Value(TenantIDKey).(string); ok {
@@ -256,6 +220,19 @@ func RequireAllRoles(roles ...string) func(http.Handler) http.Handler {
 	}
 }
 
+// RequirePermission ensures the user has the specified permission.
+func RequirePermission(permission string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if !auth.HasPermission(r.Context(), permission) {
+				response.Error(w, errors.ErrForbidden("Insufficient permissions"))
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
 // Timeout adds a timeout to the request context.
 func Timeout(timeout time.Duration) func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
@@ -4,26 +4,67 @@ package middleware
 import (
 	"context"
 	"fmt"
+	"math"
 	"net/http"
 	"strconv"
 	"sync"
 	"time"
 
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+
 	"github.com/kilang-desa-murni/crm/pkg/database"
 	"github.com/kilang-desa-murni/crm/pkg/errors"
 	"github.com/kilang-desa-murni/crm/pkg/response"
 )
 
+// Rate limit algorithms.
+const (
+	// RateLimitFixedWindow counts requests in consecutive windows. Clients
+	// can make twice the limit around the end of a window.
+	RateLimitFixedWindow = "fixed_window"
+	// RateLimitSlidingWindow counts the requests made in the last window,
+	// logging the time of each.
+	RateLimitSlidingWindow = "sliding_window"
+	// RateLimitTokenBucket refills the limit evenly over the window, so
+	// clients spend what they saved in a burst and then at the refill rate.
+	RateLimitTokenBucket = "token_bucket"
+)
+
 // RateLimiter defines the interface for rate limiting.
 type RateLimiter interface {
-	Allow(ctx context.Context, key string) (bool, int, int, time.Time, error)
+	// Allow counts a request of key, which may make burst requests on top of
+	// the limit. It returns whether the request is allowed, the requests
+	// remaining, the limit and when more requests become available.
+	Allow(ctx context.Context, key string, burst int) (bool, int, int, time.Time, error)
 }
 
 // RateLimitConfig holds rate limiter configuration.
 type RateLimitConfig struct {
-	Requests int           // Number of requests allowed
-	Window   time.Duration // Time window
-	KeyFunc  func(*http.Request) string
+	Requests  int           // Number of requests allowed
+	Window    time.Duration // Time window
+	Algorithm string        // Counting algorithm, RateLimitSlidingWindow when empty
+	Burst     int           // Extra requests allowed on top of Requests
+	KeyFunc   func(*http.Request) string
+}
+
+// rateLimitAlgorithm returns the algorithm to count requests with, sliding
+// window unless another is known.
+func rateLimitAlgorithm(algorithm string) string {
+	switch algorithm {
+	case RateLimitFixedWindow, RateLimitTokenBucket:
+		return algorithm
+	default:
+		return RateLimitSlidingWindow
+	}
+}
+
+// tokenInterval returns how often the token bucket gains a token.
+func tokenInterval(requests int, window time.Duration) time.Duration {
+	if requests < 1 {
+		requests = 1
+	}
+	return window / time.Duration(requests)
 }
 
 // DefaultKeyFunc returns the default key function (uses IP address).
@@ -54,11 +95,17 @@ type InMemoryRateLimiter struct {
 	config RateLimitConfig
 	mu     sync.RWMutex
 	store  map[string]*bucket
+	now    func() time.Time
 }
 
+// bucket is the state of a key: the requests left in its fixed window, the
+// times of its requests in the sliding window, or the tokens of its bucket.
 type bucket struct {
 	tokens    int
 	lastReset time.Time
+	requests  []time.Time
+	level     float64
+	expiresAt time.Time
 }
 
 // NewInMemoryRateLimiter creates a new in-memory rate limiter.
@@ -70,6 +117,7 @@ func NewInMemoryRateLimiter(config RateLimitConfig) *InMemoryRateLimiter {
 	limiter := &InMemoryRateLimiter{
 		config: config,
 		store:  make(map[string]*bucket),
+		now:    time.Now,
 	}
 
 	// Start cleanup goroutine
@@ -79,29 +127,104 @@ func NewInMemoryRateLimiter(config RateLimitConfig) *InMemoryRateLimiter {
 }
 
 // Allow checks if a request is allowed.
-func (l *InMemoryRateLimiter) Allow(ctx context.Context, key string) (bool, int, int, time.Time, error) {
+func (l *InMemoryRateLimiter) Allow(ctx context.Context, key string, burst int) (bool, int, int, time.Time, error) {
 	l.mu.Lock()
 	defer l.mu.Unlock()
 
-	now := time.Now()
-	resetAt := now.Add(l.config.Window)
+	now := l.now()
+	limit := l.config.Requests + burst
+
+	var allowed bool
+	var remaining int
+	var resetAt time.Time
+	switch rateLimitAlgorithm(l.config.Algorithm) {
+	case RateLimitFixedWindow:
+		allowed, remaining, resetAt = l.fixedWindow(key, limit, now)
+	case RateLimitTokenBucket:
+		allowed, remaining, resetAt = l.tokenBucket(key, limit, now)
+	default:
+		allowed, remaining, resetAt = l.slidingWindow(key, limit, now)
+	}
+	return allowed, remaining, limit, resetAt, nil
+}
 
+func (l *InMemoryRateLimiter) fixedWindow(key string, limit int, now time.Time) (bool, int, time.Time) {
 	b, exists := l.store[key]
 	if !exists || now.Sub(b.lastReset) >= l.config.Window {
 		// Create new bucket or reset existing one
-		l.store[key] = &bucket{
-			tokens:    l.config.Requests - 1,
-			lastReset: now,
-		}
-		return true, l.config.Requests - 1, l.config.Requests, resetAt, nil
+		b = &bucket{tokens: limit, lastReset: now}
+		l.store[key] = b
 	}
+	b.expiresAt = b.lastReset.Add(l.config.Window)
 
 	if b.tokens <= 0 {
-		return false, 0, l.config.Requests, b.lastReset.Add(l.config.Window), nil
+		return false, 0, b.expiresAt
 	}
-
 	b.tokens--
-	return true, b.tokens, l.config.Requests, b.lastReset.Add(l.config.Window), nil
+	return true, b.tokens, b.expiresAt
+}
+
+func (l *InMemoryRateLimiter) slidingWindow(key string, limit int, now time.Time) (bool, int, time.Time) {
+	b, exists := l.store[key]
+	if !exists {
+		b = &bucket{}
+		l.store[key] = b
+	}
+
+	// Forget the requests that left the window
+	start := now.Add(-l.config.Window)
+	kept := b.requests[:0]
+	for _, t := range b.requests {
+		if t.After(start) {
+			kept = append(kept, t)
+		}
+	}
+	b.requests = kept
+
+	allowed := len(b.requests) < limit
+	if allowed {
+		b.requests = append(b.requests, now)
+	}
+	b.expiresAt = now.Add(l.config.Window)
+
+	// A request is available again once the oldest leaves the window
+	resetAt := b.expiresAt
+	if len(b.requests) > 0 {
+		resetAt = b.requests[0].Add(l.config.Window)
+	}
+	return allowed, max(limit-len(b.requests), 0), resetAt
+}
+
+func (l *InMemoryRateLimiter) tokenBucket(key string, limit int, now time.Time) (bool, int, time.Time) {
+	interval := tokenInterval(l.config.Requests, l.config.Window)
+
+	b, exists := l.store[key]
+	if !exists {
+		b = &bucket{level: float64(limit), lastReset: now}
+		l.store[key] = b
+	}
+
+	b.level = math.Min(float64(limit), b.level+float64(now.Sub(b.lastReset))/float64(interval))
+	b.lastReset = now
+
+	allowed := b.level >= 1
+	if allowed {
+		b.level--
+	}
+	// The bucket is full again, and the same as a new one, once expired
+	b.expiresAt = now.Add(time.Duration((float64(limit) - b.level) * float64(interval)))
+
+	resetAt := now.Add(time.Duration((1 - math.Mod(b.level, 1)) * float64(interval)))
+	return allowed, int(b.level), resetAt
+}
+
+// SetLimit changes the number of requests allowed per window. Buckets
+// already started keep their tokens until their window ends.
+func (l *InMemoryRateLimiter) SetLimit(requests int, window time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.config.Requests = requests
+	l.config.Window = window
 }
 
 // cleanup removes expired buckets periodically.
@@ -111,9 +234,9 @@ func (l *InMemoryRateLimiter) cleanup() {
 
 	for range ticker.C {
 		l.mu.Lock()
-		now := time.Now()
+		now := l.now()
 		for key, b := range l.store {
-			if now.Sub(b.lastReset) >= l.config.Window*2 {
+			if now.After(b.expiresAt) {
 				delete(l.store, key)
 			}
 		}
@@ -121,9 +244,63 @@ func (l *InMemoryRateLimiter) cleanup() {
 	}
 }
 
+// slidingWindowScript logs a request in the sorted set of the key's requests
+// of the last window when fewer than the limit were made, and returns
+// whether it was allowed, the requests in the window and when the oldest
+// leaves it, in milliseconds.
+var slidingWindowScript = redis.NewScript(`
+local now = tonumber(ARGV[1])
+local window = tonumber(ARGV[2])
+local limit = tonumber(ARGV[3])
+
+redis.call('ZREMRANGEBYSCORE', KEYS[1], '-inf', now - window)
+local count = redis.call('ZCARD', KEYS[1])
+local allowed = 0
+if count < limit then
+	redis.call('ZADD', KEYS[1], now, ARGV[4])
+	count = count + 1
+	allowed = 1
+end
+redis.call('PEXPIRE', KEYS[1], window)
+
+local reset = now + window
+local oldest = redis.call('ZRANGE', KEYS[1], 0, 0, 'WITHSCORES')
+if oldest[2] then
+	reset = tonumber(oldest[2]) + window
+end
+return {allowed, count, reset}
+`)
+
+// tokenBucketScript refills the key's bucket for the time since its last
+// request and takes a token when one is left. It returns whether it was
+// allowed, the whole tokens left and the milliseconds until the next one.
+var tokenBucketScript = redis.NewScript(`
+local now = tonumber(ARGV[1])
+local interval = tonumber(ARGV[2])
+local limit = tonumber(ARGV[3])
+
+local state = redis.call('HMGET', KEYS[1], 'level', 'ts')
+local level = tonumber(state[1]) or limit
+local ts = tonumber(state[2]) or now
+level = math.min(limit, level + math.max(0, now - ts) / interval)
+
+local allowed = 0
+if level >= 1 then
+	level = level - 1
+	allowed = 1
+end
+redis.call('HSET', KEYS[1], 'level', tostring(level), 'ts', now)
+redis.call('PEXPIRE', KEYS[1], math.ceil((limit - level) * interval) + 1000)
+
+return {allowed, math.floor(level), math.ceil((1 - level % 1) * interval)}
+`)
+
 // RedisRateLimiter implements rate limiting using Redis.
 type RedisRateLimiter struct {
-	redis  *database.RedisClient
+	redis *database.RedisClient
+	now   func() time.Time
+
+	mu     sync.RWMutex
 	config RateLimitConfig
 }
 
@@ -135,15 +312,50 @@ func NewRedisRateLimiter(redis *database.RedisClient, config RateLimitConfig) *R
 
 	return &RedisRateLimiter{
 		redis:  redis,
+		now:    time.Now,
 		config: config,
 	}
 }
 
 // Allow checks if a request is allowed using Redis.
-func (l *RedisRateLimiter) Allow(ctx context.Context, key string) (bool, int, int, time.Time, error) {
-	redisKey := fmt.Sprintf("ratelimit:%s", key)
-	now := time.Now()
-	resetAt := now.Add(l.config.Window)
+func (l *RedisRateLimiter) Allow(ctx context.Context, key string, burst int) (bool, int, int, time.Time, error) {
+	l.mu.RLock()
+	requests, window, algorithm := l.config.Requests, l.config.Window, rateLimitAlgorithm(l.config.Algorithm)
+	l.mu.RUnlock()
+
+	limit := requests + burst
+	redisKey := fmt.Sprintf("ratelimit:%s:%s", algorithm, key)
+	now := l.now()
+
+	switch algorithm {
+	case RateLimitFixedWindow:
+		return l.fixedWindow(ctx, redisKey, limit, window, now)
+
+	case RateLimitTokenBucket:
+		interval := tokenInterval(requests, window)
+		result, err := tokenBucketScript.Run(ctx, l.redis.Client(), []string{redisKey},
+			now.UnixMilli(), float64(interval)/float64(time.Millisecond), limit).Int64Slice()
+		if err != nil || len(result) != 3 {
+			return false, 0, limit, now.Add(interval), fmt.Errorf("failed to execute rate limit check: %w", err)
+		}
+		return result[0] == 1, int(result[1]), limit, now.Add(time.Duration(result[2]) * time.Millisecond), nil
+
+	default:
+		result, err := slidingWindowScript.Run(ctx, l.redis.Client(), []string{redisKey},
+			now.UnixMilli(), window.Milliseconds(), limit, uuid.New().String()).Int64Slice()
+		if err != nil || len(result) != 3 {
+			return false, 0, limit, now.Add(window), fmt.Errorf("failed to execute rate limit check: %w", err)
+		}
+		return result[0] == 1, max(limit-int(result[1]), 0), limit, time.UnixMilli(result[2]), nil
+	}
+}
+
+// fixedWindow counts a request in the key's counter of the current window.
+// Windows are aligned to the clock, so every instance counts in the same one.
+func (l *RedisRateLimiter) fixedWindow(ctx context.Context, redisKey string, limit int, window time.Duration, now time.Time) (bool, int, int, time.Time, error) {
+	start := now.Truncate(window)
+	resetAt := start.Add(window)
+	redisKey = fmt.Sprintf("%s:%d", redisKey, start.Unix())
 
 	// Use Redis pipeline for atomic operations
 	pipe := l.redis.Pipeline()
@@ -151,42 +363,71 @@ func (l *RedisRateLimiter) Allow(ctx context.Context, key string) (bool, int, in
 	// Increment counter
 	incr := pipe.Incr(ctx, redisKey)
 
-	// Set expiration if key is new
-	pipe.Expire(ctx, redisKey, l.config.Window)
-
-	// Get TTL
-	ttl := pipe.TTL(ctx, redisKey)
+	// Expire the counter with its window
+	pipe.ExpireAt(ctx, redisKey, resetAt)
 
 	_, err := pipe.Exec(ctx)
 	if err != nil {
-		return false, 0, l.config.Requests, resetAt, fmt.Errorf("failed to execute rate limit check: %w", err)
+		return false, 0, limit, resetAt, fmt.Errorf("failed to execute rate limit check: %w", err)
 	}
 
 	count := int(incr.Val())
-	remaining := l.config.Requests - count
+	remaining := limit - count
 	if remaining < 0 {
 		remaining = 0
 	}
 
-	// Calculate reset time from TTL
-	if ttlDuration := ttl.Val(); ttlDuration > 0 {
-		resetAt = now.Add(ttlDuration)
-	}
+	return count <= limit, remaining, limit, resetAt, nil
+}
 
-	return count <= l.config.Requests, remaining, l.config.Requests, resetAt, nil
+// SetLimit changes the number of requests allowed per window. Keys already
+// started keep their expiry.
+func (l *RedisRateLimiter) SetLimit(requests int, window time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.config.Requests = requests
+	l.config.Window = window
 }
 
-// RateLimit creates rate limiting middleware.
+// RateLimit creates rate limiting middleware keyed by config.KeyFunc, the
+// client address by default. Every key may make config.Burst requests on top
+// of the limit, so ahead of authentication it should be at least the burst
+// TenantRateLimit allows.
 func RateLimit(limiter RateLimiter, config RateLimitConfig) func(http.Handler) http.Handler {
 	if config.KeyFunc == nil {
 		config.KeyFunc = DefaultKeyFunc
 	}
 
+	return rateLimit(limiter, func(r *http.Request) (string, int, bool) {
+		return config.KeyFunc(r), config.Burst, true
+	})
+}
+
+// TenantRateLimit creates rate limiting middleware keyed by the tenant, whose
+// requests may make config.Burst requests on top of the limit. It runs after
+// authentication; requests without a tenant are left to RateLimit.
+func TenantRateLimit(limiter RateLimiter, config RateLimitConfig) func(http.Handler) http.Handler {
+	return rateLimit(limiter, func(r *http.Request) (string, int, bool) {
+		tenantID := TenantIDFromContext(r.Context())
+		if tenantID == "" {
+			return "", 0, false
+		}
+		return fmt.Sprintf("tenant:%s", tenantID), config.Burst, true
+	})
+}
+
+// rateLimit counts requests under the key and burst returned by keyOf,
+// passing on those it returns no key for.
+func rateLimit(limiter RateLimiter, keyOf func(*http.Request) (string, int, bool)) func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-			key := config.KeyFunc(r)
+			key, burst, ok := keyOf(r)
+			if !ok {
+				next.ServeHTTP(w, r)
+				return
+			}
 
-			allowed, remaining, limit, resetAt, err := limiter.Allow(r.Context(), key)
+			allowed, remaining, limit, resetAt, err := limiter.Allow(r.Context(), key, burst)
 			if err != nil {
 				// Log error but allow request to proceed
 				response.Error(w, errors.ErrInternal("Rate limit check failed"))
@@ -199,7 +440,9 @@ func RateLimit(limiter RateLimiter, config RateLimitConfig) func(http.Handler) h
 			w.Header().Set("X-RateLimit-Reset", strconv.FormatInt(resetAt.Unix(), 10))
 
 			if !allowed {
-				w.Header().Set("Retry-After", strconv.FormatInt(int64(time.Until(resetAt).Seconds()), 10))
+				// Round up, as retrying before the reset is refused again
+				retryAfter := int64(math.Ceil(time.Until(resetAt).Seconds()))
+				w.Header().Set("Retry-After", strconv.FormatInt(max(retryAfter, 1), 10))
 				response.Error(w, errors.ErrTooManyRequests("Rate limit exceeded"))
 				return
 			}
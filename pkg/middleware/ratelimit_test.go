@@ -0,0 +1,276 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/kilang-desa-murni/crm/pkg/config"
+	"github.com/kilang-desa-murni/crm/pkg/database"
+	"github.com/kilang-desa-murni/crm/pkg/logger"
+	"github.com/kilang-desa-murni/crm/pkg/testing/containers"
+	"github.com/kilang-desa-murni/crm/pkg/testing/helpers"
+)
+
+// rateLimitStep is a request made at offset from the start of a test,
+// and what the limiter should answer.
+type rateLimitStep struct {
+	offset        time.Duration
+	burst         int
+	wantAllowed   bool
+	wantRemaining int
+	wantReset     time.Duration // from the start of the test
+}
+
+// rateLimitCase is a sequence of requests of one key.
+type rateLimitCase struct {
+	name      string
+	algorithm string
+	steps     []rateLimitStep
+}
+
+// rateLimitCases allow 2 requests per 10 seconds, so the token bucket gains
+// a token every 5 seconds.
+var rateLimitCases = []rateLimitCase{
+	{
+		name:      "fixed window resets at the end of the window",
+		algorithm: RateLimitFixedWindow,
+		steps: []rateLimitStep{
+			{offset: 0, wantAllowed: true, wantRemaining: 1, wantReset: 10 * time.Second},
+			{offset: 4 * time.Second, wantAllowed: true, wantRemaining: 0, wantReset: 10 * time.Second},
+			{offset: 9 * time.Second, wantAllowed: false, wantRemaining: 0, wantReset: 10 * time.Second},
+			{offset: 10 * time.Second, wantAllowed: true, wantRemaining: 1, wantReset: 20 * time.Second},
+		},
+	},
+	{
+		name:      "sliding window frees a request as the oldest leaves",
+		algorithm: RateLimitSlidingWindow,
+		steps: []rateLimitStep{
+			{offset: 0, wantAllowed: true, wantRemaining: 1, wantReset: 10 * time.Second},
+			{offset: 4 * time.Second, wantAllowed: true, wantRemaining: 0, wantReset: 10 * time.Second},
+			{offset: 10*time.Second - time.Millisecond, wantAllowed: false, wantRemaining: 0, wantReset: 10 * time.Second},
+			// The first request leaves the window exactly a window later
+			{offset: 10 * time.Second, wantAllowed: true, wantRemaining: 0, wantReset: 14 * time.Second},
+			{offset: 11 * time.Second, wantAllowed: false, wantRemaining: 0, wantReset: 14 * time.Second},
+			{offset: 14 * time.Second, wantAllowed: true, wantRemaining: 0, wantReset: 20 * time.Second},
+		},
+	},
+	{
+		name:      "sliding window with burst",
+		algorithm: RateLimitSlidingWindow,
+		steps: []rateLimitStep{
+			{offset: 0, burst: 1, wantAllowed: true, wantRemaining: 2, wantReset: 10 * time.Second},
+			{offset: time.Second, burst: 1, wantAllowed: true, wantRemaining: 1, wantReset: 10 * time.Second},
+			{offset: 2 * time.Second, burst: 1, wantAllowed: true, wantRemaining: 0, wantReset: 10 * time.Second},
+			{offset: 3 * time.Second, burst: 1, wantAllowed: false, wantRemaining: 0, wantReset: 10 * time.Second},
+		},
+	},
+	{
+		name:      "token bucket refills a token per interval",
+		algorithm: RateLimitTokenBucket,
+		steps: []rateLimitStep{
+			{offset: 0, burst: 1, wantAllowed: true, wantRemaining: 2, wantReset: 5 * time.Second},
+			{offset: 0, burst: 1, wantAllowed: true, wantRemaining: 1, wantReset: 5 * time.Second},
+			{offset: 0, burst: 1, wantAllowed: true, wantRemaining: 0, wantReset: 5 * time.Second},
+			{offset: 0, burst: 1, wantAllowed: false, wantRemaining: 0, wantReset: 5 * time.Second},
+			// Half a token is not enough
+			{offset: 2500 * time.Millisecond, burst: 1, wantAllowed: false, wantRemaining: 0, wantReset: 5 * time.Second},
+			{offset: 5 * time.Second, burst: 1, wantAllowed: true, wantRemaining: 0, wantReset: 10 * time.Second},
+			// The bucket holds the limit at most however long it is idle
+			{offset: time.Hour, burst: 1, wantAllowed: true, wantRemaining: 2, wantReset: time.Hour + 5*time.Second},
+		},
+	},
+}
+
+// runRateLimitCase makes the requests of tc at their offsets from start.
+func runRateLimitCase(t *testing.T, limiter RateLimiter, setNow func(time.Time), start time.Time, key string, tc rateLimitCase) {
+	t.Helper()
+	for i, step := range tc.steps {
+		setNow(start.Add(step.offset))
+		allowed, remaining, limit, resetAt, err := limiter.Allow(context.Background(), key, step.burst)
+		if err != nil {
+			t.Fatalf("step %d: Allow() error = %v", i, err)
+		}
+		if allowed != step.wantAllowed {
+			t.Errorf("step %d: allowed = %v, want %v", i, allowed, step.wantAllowed)
+		}
+		if remaining != step.wantRemaining {
+			t.Errorf("step %d: remaining = %d, want %d", i, remaining, step.wantRemaining)
+		}
+		if limit != 2+step.burst {
+			t.Errorf("step %d: limit = %d, want %d", i, limit, 2+step.burst)
+		}
+		if want := start.Add(step.wantReset); !resetAt.Equal(want) {
+			t.Errorf("step %d: resetAt = +%v, want +%v", i, resetAt.Sub(start), step.wantReset)
+		}
+	}
+}
+
+func TestInMemoryRateLimiter_Allow(t *testing.T) {
+	// Aligned to the window, as the fixed window of the Redis limiter is
+	start := time.Date(2026, 10, 17, 9, 0, 0, 0, time.UTC)
+
+	for _, tt := range rateLimitCases {
+		t.Run(tt.name, func(t *testing.T) {
+			limiter := NewInMemoryRateLimiter(RateLimitConfig{Requests: 2, Window: 10 * time.Second, Algorithm: tt.algorithm})
+			var now time.Time
+			limiter.now = func() time.Time { return now }
+
+			runRateLimitCase(t, limiter, func(at time.Time) { now = at }, start, "client", tt)
+		})
+	}
+}
+
+func TestRedisRateLimiter_Allow(t *testing.T) {
+	helpers.SkipIfShort(t)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	container, err := containers.NewRedisContainer(ctx, containers.DefaultRedisConfig())
+	if err != nil {
+		t.Skipf("Redis not available: %v", err)
+	}
+	container.Close()
+	port, err := strconv.Atoi(container.Port)
+	if err != nil {
+		t.Fatalf("invalid Redis port %q", container.Port)
+	}
+	redis, err := database.NewRedis(&config.RedisConfig{
+		Host:     container.Host,
+		Port:     port,
+		Password: container.Password,
+		DB:       container.DB,
+		PoolSize: 2,
+	}, logger.New(logger.Config{Level: "error"}))
+	if err != nil {
+		t.Skipf("Redis not available: %v", err)
+	}
+	t.Cleanup(func() { redis.Close() })
+	if err := redis.Client().FlushDB(ctx).Err(); err != nil {
+		t.Fatalf("failed to flush Redis: %v", err)
+	}
+
+	// Keys expire in real time, so the test runs from the current window
+	start := time.Now().Truncate(10 * time.Second).Add(10 * time.Second)
+
+	for _, tt := range rateLimitCases {
+		t.Run(tt.name, func(t *testing.T) {
+			limiter := NewRedisRateLimiter(redis, RateLimitConfig{Requests: 2, Window: 10 * time.Second, Algorithm: tt.algorithm})
+			var now time.Time
+			limiter.now = func() time.Time { return now }
+
+			runRateLimitCase(t, limiter, func(at time.Time) { now = at }, start, tt.name, tt)
+		})
+	}
+}
+
+// stubRateLimiter answers every request the same, recording the last key.
+type stubRateLimiter struct {
+	allowed bool
+	resetIn time.Duration
+	err     error
+
+	calls int
+	key   string
+	burst int
+}
+
+func (l *stubRateLimiter) Allow(ctx context.Context, key string, burst int) (bool, int, int, time.Time, error) {
+	l.calls++
+	l.key = key
+	l.burst = burst
+	return l.allowed, 0, 10 + burst, time.Now().Add(l.resetIn), l.err
+}
+
+func TestRateLimit_RetryAfter(t *testing.T) {
+	tests := []struct {
+		name           string
+		allowed        bool
+		resetIn        time.Duration
+		err            error
+		wantStatus     int
+		wantRetryAfter string
+	}{
+		{name: "allowed", allowed: true, resetIn: 5 * time.Second, wantStatus: http.StatusOK},
+		{name: "rounds a fraction up", resetIn: 1500 * time.Millisecond, wantStatus: http.StatusTooManyRequests, wantRetryAfter: "2"},
+		{name: "whole seconds", resetIn: 3 * time.Second, wantStatus: http.StatusTooManyRequests, wantRetryAfter: "3"},
+		{name: "under a second", resetIn: 100 * time.Millisecond, wantStatus: http.StatusTooManyRequests, wantRetryAfter: "1"},
+		{name: "reset passed", resetIn: -time.Second, wantStatus: http.StatusTooManyRequests, wantRetryAfter: "1"},
+		{name: "limiter failed", err: errors.New("connection refused"), wantStatus: http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			limiter := &stubRateLimiter{allowed: tt.allowed, resetIn: tt.resetIn, err: tt.err}
+			handler := RateLimit(limiter, RateLimitConfig{})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusOK)
+			}))
+
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/leads", nil))
+
+			if rec.Code != tt.wantStatus {
+				t.Errorf("expected status %d, got %d", tt.wantStatus, rec.Code)
+			}
+			if got := rec.Header().Get("Retry-After"); got != tt.wantRetryAfter {
+				t.Errorf("Retry-After = %q, want %q", got, tt.wantRetryAfter)
+			}
+			if tt.err == nil && rec.Header().Get("X-RateLimit-Limit") != "10" {
+				t.Errorf("expected X-RateLimit-Limit 10, got %q", rec.Header().Get("X-RateLimit-Limit"))
+			}
+		})
+	}
+}
+
+func TestRateLimit_Keys(t *testing.T) {
+	limitConfig := RateLimitConfig{Burst: 5}
+
+	tests := []struct {
+		name      string
+		tenantID  string
+		tenant    bool
+		wantCalls int
+		wantKey   string
+		wantBurst int
+	}{
+		{name: "address before authentication", wantCalls: 1, wantKey: "192.0.2.1:1234", wantBurst: 5},
+		{name: "address of a tenant", tenantID: "t-1", wantCalls: 1, wantKey: "192.0.2.1:1234", wantBurst: 5},
+		{name: "tenant", tenantID: "t-1", tenant: true, wantCalls: 1, wantKey: "tenant:t-1", wantBurst: 5},
+		{name: "tenant limit without a tenant", tenant: true, wantCalls: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			limiter := &stubRateLimiter{allowed: true}
+			limit := RateLimit(limiter, limitConfig)
+			if tt.tenant {
+				limit = TenantRateLimit(limiter, limitConfig)
+			}
+			handler := limit(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusOK)
+			}))
+
+			r := httptest.NewRequest(http.MethodGet, "/api/v1/leads", nil)
+			r.RemoteAddr = "192.0.2.1:1234"
+			if tt.tenantID != "" {
+				r = r.WithContext(context.WithValue(r.Context(), TenantIDKey, tt.tenantID))
+			}
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, r)
+
+			if rec.Code != http.StatusOK {
+				t.Errorf("expected status 200, got %d", rec.Code)
+			}
+			if limiter.calls != tt.wantCalls {
+				t.Fatalf("limiter called %d times, want %d", limiter.calls, tt.wantCalls)
+			}
+			if tt.wantCalls > 0 && (limiter.key != tt.wantKey || limiter.burst != tt.wantBurst) {
+				t.Errorf("counted under %q with burst %d, want %q with burst %d", limiter.key, limiter.burst, tt.wantKey, tt.wantBurst)
+			}
+		})
+	}
+}
@@ -0,0 +1,204 @@
+package middleware
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/kilang-desa-murni/crm/pkg/errors"
+	"github.com/kilang-desa-murni/crm/pkg/response"
+)
+
+// SessionMode selects how clients present their access token.
+type SessionMode string
+
+const (
+	// SessionModeBearer accepts tokens in the Authorization header only.
+	SessionModeBearer SessionMode = "bearer"
+	// SessionModeCookie accepts tokens in httpOnly session cookies only.
+	SessionModeCookie SessionMode = "cookie"
+	// SessionModeBoth accepts either, for migrating clients to cookies.
+	SessionModeBoth SessionMode = "both"
+)
+
+// AllowsBearer reports whether the mode accepts Authorization headers.
+func (m SessionMode) AllowsBearer() bool {
+	return m != SessionModeCookie
+}
+
+// AllowsCookie reports whether the mode accepts session cookies.
+func (m SessionMode) AllowsCookie() bool {
+	return m == SessionModeCookie || m == SessionModeBoth
+}
+
+// SessionConfig holds cookie session configuration.
+type SessionConfig struct {
+	Mode SessionMode
+
+	AccessCookie  string // httpOnly cookie carrying the access token
+	RefreshCookie string // httpOnly cookie carrying the refresh token
+	CSRFCookie    string // Cookie carrying the CSRF token, readable by scripts
+	CSRFHeader    string // Header state-changing requests echo the CSRF token in
+
+	Domain   string
+	Secure   bool
+	SameSite http.SameSite
+
+	// RefreshMaxAge is the lifetime of the refresh and CSRF cookies.
+	RefreshMaxAge time.Duration
+
+	// CSRFSecret signs CSRF tokens. It must be shared by every gateway
+	// instance.
+	CSRFSecret []byte
+}
+
+// DefaultSessionConfig returns the default session configuration: bearer
+// tokens only, with secure, same-site cookies once cookies are enabled.
+func DefaultSessionConfig() SessionConfig {
+	return SessionConfig{
+		Mode:          SessionModeBearer,
+		AccessCookie:  "crm_access",
+		RefreshCookie: "crm_refresh",
+		CSRFCookie:    "crm_csrf",
+		CSRFHeader:    "X-CSRF-Token",
+		Secure:        true,
+		SameSite:      http.SameSiteLaxMode,
+		RefreshMaxAge: 7 * 24 * time.Hour,
+	}
+}
+
+// SetSessionCookies stores a token pair in the session cookies and issues a
+// new CSRF token bound to the refresh token, which it returns.
+func SetSessionCookies(w http.ResponseWriter, config SessionConfig, accessToken, refreshToken string, accessMaxAge time.Duration) (string, error) {
+	csrfToken, err := NewCSRFToken(config.CSRFSecret, refreshToken)
+	if err != nil {
+		return "", err
+	}
+
+	http.SetCookie(w, config.cookie(config.AccessCookie, accessToken, accessMaxAge, true))
+	http.SetCookie(w, config.cookie(config.RefreshCookie, refreshToken, config.RefreshMaxAge, true))
+	// Scripts read the CSRF token to echo it in the CSRF header
+	http.SetCookie(w, config.cookie(config.CSRFCookie, csrfToken, config.RefreshMaxAge, false))
+	return csrfToken, nil
+}
+
+// ClearSessionCookies removes the session cookies.
+func ClearSessionCookies(w http.ResponseWriter, config SessionConfig) {
+	for _, name := range []string{config.AccessCookie, config.RefreshCookie, config.CSRFCookie} {
+		cookie := config.cookie(name, "", 0, name != config.CSRFCookie)
+		cookie.MaxAge = -1
+		http.SetCookie(w, cookie)
+	}
+}
+
+func (c SessionConfig) cookie(name, value string, maxAge time.Duration, httpOnly bool) *http.Cookie {
+	return &http.Cookie{
+		Name:     name,
+		Value:    value,
+		Path:     "/",
+		Domain:   c.Domain,
+		MaxAge:   int(maxAge.Seconds()),
+		Secure:   c.Secure,
+		HttpOnly: httpOnly,
+		SameSite: c.SameSite,
+	}
+}
+
+// NewCSRFToken issues a CSRF token for the session of a refresh token. The
+// token is a random nonce signed together with a hash of the refresh token,
+// so a token issued for one session is refused for any other.
+func NewCSRFToken(secret []byte, refreshToken string) (string, error) {
+	nonce := make([]byte, 16)
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+	encoded := base64.RawURLEncoding.EncodeToString(nonce)
+	return encoded + "." + signCSRFNonce(secret, encoded, refreshToken), nil
+}
+
+// VerifyCSRFToken reports whether token was issued for the session of a
+// refresh token.
+func VerifyCSRFToken(secret []byte, token, refreshToken string) bool {
+	nonce, signature, ok := strings.Cut(token, ".")
+	if !ok || nonce == "" || refreshToken == "" {
+		return false
+	}
+	expected := signCSRFNonce(secret, nonce, refreshToken)
+	return subtle.ConstantTimeCompare([]byte(signature), []byte(expected)) == 1
+}
+
+func signCSRFNonce(secret []byte, nonce, refreshToken string) string {
+	session := sha256.Sum256([]byte(refreshToken))
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(nonce))
+	mac.Write(session[:])
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// VerifyCSRF checks the CSRF token of a cookie-authenticated request. Safe
+// methods need none; other requests must echo the CSRF cookie in the CSRF
+// header, and the token must belong to the request's session.
+func VerifyCSRF(r *http.Request, config SessionConfig) error {
+	switch r.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return nil
+	}
+
+	header := r.Header.Get(config.CSRFHeader)
+	cookie, err := r.Cookie(config.CSRFCookie)
+	if header == "" || err != nil || subtle.ConstantTimeCompare([]byte(header), []byte(cookie.Value)) != 1 {
+		return errors.ErrForbidden("Missing or invalid CSRF token")
+	}
+
+	refresh, err := r.Cookie(config.RefreshCookie)
+	if err != nil || !VerifyCSRFToken(config.CSRFSecret, header, refresh.Value) {
+		return errors.ErrForbidden("Missing or invalid CSRF token")
+	}
+	return nil
+}
+
+// SessionAuth authenticates requests by their access token, checked by
+// validate, also accepting the token from the session cookie when the mode
+// allows cookies. A request with an Authorization header is authenticated by
+// the header. Cookie-authenticated requests must pass the CSRF check and are
+// forwarded with the token in the Authorization header, so backend services
+// see a bearer request. Identity headers are never accepted, as the gateway
+// is where they are signed.
+func SessionAuth(validate TokenValidator, config SessionConfig) func(http.Handler) http.Handler {
+	bearer := authenticate(validate, nil)
+
+	return func(next http.Handler) http.Handler {
+		bearerNext := bearer(next)
+
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Header.Get("Authorization") != "" || !config.Mode.AllowsCookie() {
+				if !config.Mode.AllowsBearer() {
+					response.Error(w, errors.ErrUnauthorized("Authorization header not accepted, use the session cookie"))
+					return
+				}
+				bearerNext.ServeHTTP(w, r)
+				return
+			}
+
+			cookie, err := r.Cookie(config.AccessCookie)
+			if err != nil || cookie.Value == "" {
+				response.Error(w, errors.ErrUnauthorized("Missing session"))
+				return
+			}
+
+			if err := VerifyCSRF(r, config); err != nil {
+				response.Error(w, err)
+				return
+			}
+
+			r = r.Clone(r.Context())
+			r.Header.Set("Authorization", "Bearer "+cookie.Value)
+			bearerNext.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,147 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/kilang-desa-murni/crm/pkg/auth"
+	"github.com/kilang-desa-murni/crm/pkg/errors"
+)
+
+const (
+	testAccessToken  = "access-token"
+	testRefreshToken = "refresh-token"
+)
+
+func testSessionConfig(mode SessionMode) SessionConfig {
+	config := DefaultSessionConfig()
+	config.Mode = mode
+	config.CSRFSecret = []byte("0123456789abcdef0123456789abcdef")
+	return config
+}
+
+// testTokenValidator accepts testAccessToken only.
+func testTokenValidator(ctx context.Context, token string) (*auth.Claims, error) {
+	if token != testAccessToken {
+		return nil, errors.ErrUnauthorized("invalid token")
+	}
+	return &auth.Claims{}, nil
+}
+
+// sessionRequest builds a request carrying the session cookies, the CSRF
+// header and the Authorization header when they are set.
+func sessionRequest(method, bearer, accessCookie, csrfCookie, csrfHeader string, config SessionConfig) *http.Request {
+	r := httptest.NewRequest(method, "/api/v1/leads", nil)
+	if bearer != "" {
+		r.Header.Set("Authorization", "Bearer "+bearer)
+	}
+	if accessCookie != "" {
+		r.AddCookie(&http.Cookie{Name: config.AccessCookie, Value: accessCookie})
+		r.AddCookie(&http.Cookie{Name: config.RefreshCookie, Value: testRefreshToken})
+	}
+	if csrfCookie != "" {
+		r.AddCookie(&http.Cookie{Name: config.CSRFCookie, Value: csrfCookie})
+	}
+	if csrfHeader != "" {
+		r.Header.Set(config.CSRFHeader, csrfHeader)
+	}
+	return r
+}
+
+func TestSessionAuth(t *testing.T) {
+	csrfToken, err := NewCSRFToken(testSessionConfig(SessionModeCookie).CSRFSecret, testRefreshToken)
+	if err != nil {
+		t.Fatalf("NewCSRFToken() error = %v", err)
+	}
+
+	tests := []struct {
+		name         string
+		mode         SessionMode
+		method       string
+		bearer       string
+		accessCookie string
+		csrfCookie   string
+		csrfHeader   string
+		wantStatus   int
+		wantBearer   string
+	}{
+		{"bearer mode accepts the header", SessionModeBearer, http.MethodGet, testAccessToken, "", "", "", http.StatusOK, testAccessToken},
+		{"bearer mode refuses cookies", SessionModeBearer, http.MethodGet, "", testAccessToken, "", "", http.StatusUnauthorized, ""},
+		{"cookie mode refuses the header", SessionModeCookie, http.MethodGet, testAccessToken, "", "", "", http.StatusUnauthorized, ""},
+		{"cookie mode accepts a safe request without CSRF", SessionModeCookie, http.MethodGet, "", testAccessToken, "", "", http.StatusOK, testAccessToken},
+		{"cookie mode without a session", SessionModeCookie, http.MethodGet, "", "", "", "", http.StatusUnauthorized, ""},
+		{"post with the CSRF header", SessionModeCookie, http.MethodPost, "", testAccessToken, csrfToken, csrfToken, http.StatusOK, testAccessToken},
+		{"post without the CSRF header", SessionModeCookie, http.MethodPost, "", testAccessToken, csrfToken, "", http.StatusForbidden, ""},
+		{"post with a header not matching the cookie", SessionModeCookie, http.MethodPost, "", testAccessToken, csrfToken, "forged.token", http.StatusForbidden, ""},
+		{"both modes accept the header", SessionModeBoth, http.MethodPost, testAccessToken, "", "", "", http.StatusOK, testAccessToken},
+		{"both modes accept cookies", SessionModeBoth, http.MethodPost, "", testAccessToken, csrfToken, csrfToken, http.StatusOK, testAccessToken},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := testSessionConfig(tt.mode)
+			var gotBearer string
+			handler := SessionAuth(testTokenValidator, config)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				gotBearer = strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+			}))
+
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, sessionRequest(tt.method, tt.bearer, tt.accessCookie, tt.csrfCookie, tt.csrfHeader, config))
+
+			if w.Code != tt.wantStatus {
+				t.Errorf("expected %d, got %d: %s", tt.wantStatus, w.Code, w.Body.String())
+			}
+			if gotBearer != tt.wantBearer {
+				t.Errorf("expected the backend to see bearer %q, got %q", tt.wantBearer, gotBearer)
+			}
+		})
+	}
+}
+
+func TestVerifyCSRFToken(t *testing.T) {
+	secret := testSessionConfig(SessionModeCookie).CSRFSecret
+	token, err := NewCSRFToken(secret, testRefreshToken)
+	if err != nil {
+		t.Fatalf("NewCSRFToken() error = %v", err)
+	}
+
+	tests := []struct {
+		name         string
+		secret       []byte
+		token        string
+		refreshToken string
+		want         bool
+	}{
+		{"own session", secret, token, testRefreshToken, true},
+		{"another session", secret, token, "other-refresh-token", false},
+		{"another secret", []byte("fedcba9876543210fedcba9876543210"), token, testRefreshToken, false},
+		{"no session", secret, token, "", false},
+		{"unsigned token", secret, strings.Split(token, ".")[0], testRefreshToken, false},
+		{"empty token", secret, "", testRefreshToken, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := VerifyCSRFToken(tt.secret, tt.token, tt.refreshToken); got != tt.want {
+				t.Errorf("VerifyCSRFToken() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestVerifyCSRF_TokenOfAnotherSession(t *testing.T) {
+	config := testSessionConfig(SessionModeCookie)
+	// A token issued to another session, echoed in both cookie and header
+	token, err := NewCSRFToken(config.CSRFSecret, "other-refresh-token")
+	if err != nil {
+		t.Fatalf("NewCSRFToken() error = %v", err)
+	}
+
+	r := sessionRequest(http.MethodPost, "", testAccessToken, token, token, config)
+	if err := VerifyCSRF(r, config); err == nil {
+		t.Error("expected a CSRF token of another session to be refused")
+	}
+}
@@ -0,0 +1,35 @@
+package query
+
+import (
+	"fmt"
+	"regexp"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// Mongo renders the filter as MongoDB conditions, one per filter condition,
+// to be combined with $and. Fields are mapped to document paths through
+// paths.
+func (f Filter) Mongo(paths map[string]string) ([]bson.M, error) {
+	conditions := make([]bson.M, 0, len(f))
+
+	for _, c := range f {
+		path, ok := paths[c.Field]
+		if !ok {
+			return nil, fmt.Errorf("no document path for filter field %q", c.Field)
+		}
+
+		var expr bson.M
+		switch c.Op {
+		case In, Nin:
+			expr = bson.M{"$" + string(c.Op): c.Values}
+		case Contains:
+			expr = bson.M{"$regex": regexp.QuoteMeta(c.Value().(string)), "$options": "i"}
+		default:
+			expr = bson.M{"$" + string(c.Op): c.Value()}
+		}
+		conditions = append(conditions, bson.M{path: expr})
+	}
+
+	return conditions, nil
+}
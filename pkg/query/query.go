@@ -0,0 +1,290 @@
+// Package query parses the filter and sort expressions accepted by list
+// endpoints and translates them into SQL predicates and MongoDB filters.
+//
+// A filter is a comma-separated list of conditions of the form
+// field:operator:value, for example
+//
+//	filter=status:eq:open,amount:gte:1000
+//
+// The in and nin operators take several values separated by "|". A literal
+// ",", "|" or "\" inside a value is escaped with a backslash.
+//
+// A sort is a comma-separated list of fields, each optionally prefixed with
+// "-" for descending order, for example
+//
+//	sort=-created_at,name
+//
+// Only fields listed in the endpoint's allow-list can be filtered or sorted
+// on, and every value is parsed according to the field's type before it
+// reaches a query.
+package query
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// MaxConditions is the maximum number of conditions in a filter expression.
+const MaxConditions = 20
+
+// Type is the type of a filterable field. Values are parsed according to it.
+type Type int
+
+const (
+	// String fields compare as text.
+	String Type = iota
+	// Integer fields take whole numbers.
+	Integer
+	// Number fields take decimal numbers.
+	Number
+	// Bool fields take true or false.
+	Bool
+	// Time fields take RFC 3339 timestamps or 2006-01-02 dates.
+	Time
+	// UUID fields take UUIDs.
+	UUID
+)
+
+// Operator is a comparison in a filter condition.
+type Operator string
+
+const (
+	Eq       Operator = "eq"
+	Ne       Operator = "ne"
+	Gt       Operator = "gt"
+	Gte      Operator = "gte"
+	Lt       Operator = "lt"
+	Lte      Operator = "lte"
+	In       Operator = "in"
+	Nin      Operator = "nin"
+	Contains Operator = "contains"
+)
+
+// operators lists the operators each field type supports.
+var operators = map[Type][]Operator{
+	String:  {Eq, Ne, Gt, Gte, Lt, Lte, In, Nin, Contains},
+	Integer: {Eq, Ne, Gt, Gte, Lt, Lte, In, Nin},
+	Number:  {Eq, Ne, Gt, Gte, Lt, Lte, In, Nin},
+	Bool:    {Eq, Ne},
+	Time:    {Eq, Ne, Gt, Gte, Lt, Lte},
+	UUID:    {Eq, Ne, In, Nin},
+}
+
+// Fields is the allow-list of an endpoint: the fields that can be filtered on,
+// by name, with their types.
+type Fields map[string]Type
+
+// Error describes an invalid filter or sort expression.
+type Error struct {
+	Expr   string
+	Reason string
+}
+
+func (e *Error) Error() string {
+	if e.Expr == "" {
+		return e.Reason
+	}
+	return fmt.Sprintf("%s: %s", e.Expr, e.Reason)
+}
+
+// Condition is a single field:operator:value term of a filter. Values holds
+// the parsed value, or several values for In and Nin.
+type Condition struct {
+	Field  string
+	Op     Operator
+	Values []interface{}
+}
+
+// Value returns the value of a single-valued condition.
+func (c Condition) Value() interface{} {
+	return c.Values[0]
+}
+
+// Filter is a parsed filter expression. Its conditions are combined with AND.
+type Filter []Condition
+
+// ParseFilter parses a filter expression against the allow-list. An empty
+// expression yields an empty filter.
+func ParseFilter(raw string, fields Fields) (Filter, error) {
+	if strings.TrimSpace(raw) == "" {
+		return nil, nil
+	}
+
+	terms := split(raw, ',')
+	if len(terms) > MaxConditions {
+		return nil, &Error{Reason: fmt.Sprintf("at most %d conditions are allowed", MaxConditions)}
+	}
+
+	filter := make(Filter, 0, len(terms))
+	for _, term := range terms {
+		cond, err := parseCondition(term, fields)
+		if err != nil {
+			return nil, err
+		}
+		filter = append(filter, cond)
+	}
+	return filter, nil
+}
+
+func parseCondition(term string, fields Fields) (Condition, error) {
+	parts := strings.SplitN(term, ":", 3)
+	if len(parts) != 3 {
+		return Condition{}, &Error{Expr: term, Reason: "expected field:operator:value"}
+	}
+
+	field := strings.TrimSpace(parts[0])
+	typ, ok := fields[field]
+	if !ok {
+		return Condition{}, &Error{Expr: term, Reason: fmt.Sprintf("cannot filter on %q", field)}
+	}
+
+	op := Operator(strings.ToLower(strings.TrimSpace(parts[1])))
+	if !supports(typ, op) {
+		return Condition{}, &Error{Expr: term, Reason: fmt.Sprintf("operator %q is not supported for %s", op, field)}
+	}
+
+	raw := []string{parts[2]}
+	if op == In || op == Nin {
+		raw = split(parts[2], '|')
+	}
+
+	cond := Condition{Field: field, Op: op, Values: make([]interface{}, 0, len(raw))}
+	for _, r := range raw {
+		v, err := parseValue(typ, unescape(r))
+		if err != nil {
+			return Condition{}, &Error{Expr: term, Reason: err.Error()}
+		}
+		cond.Values = append(cond.Values, v)
+	}
+	return cond, nil
+}
+
+func supports(typ Type, op Operator) bool {
+	for _, o := range operators[typ] {
+		if o == op {
+			return true
+		}
+	}
+	return false
+}
+
+func parseValue(typ Type, s string) (interface{}, error) {
+	switch typ {
+	case Integer:
+		v, err := strconv.ParseInt(s, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("%q is not a whole number", s)
+		}
+		return v, nil
+	case Number:
+		v, err := strconv.ParseFloat(s, 64)
+		if err != nil {
+			return nil, fmt.Errorf("%q is not a number", s)
+		}
+		return v, nil
+	case Bool:
+		v, err := strconv.ParseBool(s)
+		if err != nil {
+			return nil, fmt.Errorf("%q is not true or false", s)
+		}
+		return v, nil
+	case Time:
+		if v, err := time.Parse(time.RFC3339, s); err == nil {
+			return v.UTC(), nil
+		}
+		v, err := time.Parse("2006-01-02", s)
+		if err != nil {
+			return nil, fmt.Errorf("%q is not an RFC 3339 timestamp or date", s)
+		}
+		return v, nil
+	case UUID:
+		v, err := uuid.Parse(s)
+		if err != nil {
+			return nil, fmt.Errorf("%q is not a UUID", s)
+		}
+		return v, nil
+	default:
+		return s, nil
+	}
+}
+
+// split splits s on sep, skipping separators escaped with a backslash. The
+// escapes are kept so that a part can be split again.
+func split(s string, sep byte) []string {
+	var parts []string
+	start := 0
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			i++
+		case sep:
+			parts = append(parts, s[start:i])
+			start = i + 1
+		}
+	}
+	return append(parts, s[start:])
+}
+
+// unescape removes the backslash escapes from a value.
+func unescape(s string) string {
+	if !strings.Contains(s, `\`) {
+		return s
+	}
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] == '\\' && i+1 < len(s) {
+			i++
+		}
+		b.WriteByte(s[i])
+	}
+	return b.String()
+}
+
+// SortField is a single field of a sort expression.
+type SortField struct {
+	Field string
+	Desc  bool
+}
+
+// Order returns the sort direction as "asc" or "desc".
+func (s SortField) Order() string {
+	if s.Desc {
+		return "desc"
+	}
+	return "asc"
+}
+
+// Sort is a parsed sort expression, most significant field first.
+type Sort []SortField
+
+// ParseSort parses a sort expression, accepting only the allowed fields. An
+// empty expression yields an empty sort.
+func ParseSort(raw string, allowed ...string) (Sort, error) {
+	if strings.TrimSpace(raw) == "" {
+		return nil, nil
+	}
+
+	var sort Sort
+	for _, term := range strings.Split(raw, ",") {
+		term = strings.TrimSpace(term)
+		field := SortField{Field: strings.TrimLeft(term, "+-"), Desc: strings.HasPrefix(term, "-")}
+		if !contains(allowed, field.Field) {
+			return nil, &Error{Expr: term, Reason: fmt.Sprintf("cannot sort by %q", field.Field)}
+		}
+		sort = append(sort, field)
+	}
+	return sort, nil
+}
+
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,166 @@
+package query
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+var testFields = Fields{
+	"status":     String,
+	"name":       String,
+	"score":      Integer,
+	"amount":     Number,
+	"active":     Bool,
+	"created_at": Time,
+	"owner_id":   UUID,
+}
+
+func TestParseFilter(t *testing.T) {
+	ownerID := uuid.New()
+
+	filter, err := ParseFilter("status:in:open|won,amount:gte:1000.5,created_at:lt:2024-05-01,owner_id:eq:"+ownerID.String()+",name:contains:Batik\\, Sdn", testFields)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := Filter{
+		{Field: "status", Op: In, Values: []interface{}{"open", "won"}},
+		{Field: "amount", Op: Gte, Values: []interface{}{1000.5}},
+		{Field: "created_at", Op: Lt, Values: []interface{}{time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)}},
+		{Field: "owner_id", Op: Eq, Values: []interface{}{ownerID}},
+		{Field: "name", Op: Contains, Values: []interface{}{"Batik, Sdn"}},
+	}
+	if !reflect.DeepEqual(filter, want) {
+		t.Errorf("expected %+v, got %+v", want, filter)
+	}
+}
+
+func TestParseFilter_TimestampValue(t *testing.T) {
+	filter, err := ParseFilter("created_at:gte:2024-05-01T08:30:00+08:00", testFields)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := filter[0].Value(); got != time.Date(2024, 5, 1, 0, 30, 0, 0, time.UTC) {
+		t.Errorf("unexpected value %v", got)
+	}
+}
+
+func TestParseFilter_Empty(t *testing.T) {
+	filter, err := ParseFilter("  ", testFields)
+	if err != nil || filter != nil {
+		t.Errorf("expected an empty filter, got %v, %v", filter, err)
+	}
+}
+
+func TestParseFilter_Invalid(t *testing.T) {
+	tests := []struct {
+		name string
+		raw  string
+	}{
+		{"missing value", "status:eq"},
+		{"unknown field", "password:eq:secret"},
+		{"unknown operator", "status:like:open"},
+		{"operator not supported for type", "active:gt:true"},
+		{"bad integer", "score:gte:high"},
+		{"bad number", "amount:lt:lots"},
+		{"bad bool", "active:eq:maybe"},
+		{"bad time", "created_at:gt:yesterday"},
+		{"bad uuid", "owner_id:eq:42"},
+		{"bad value in list", "score:in:1|two"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseFilter(tt.raw, testFields)
+			var qe *Error
+			if !errors.As(err, &qe) {
+				t.Errorf("expected a query error, got %v", err)
+			}
+		})
+	}
+}
+
+func TestParseFilter_TooManyConditions(t *testing.T) {
+	raw := "score:gt:0"
+	for i := 0; i < MaxConditions; i++ {
+		raw += ",score:gt:0"
+	}
+	if _, err := ParseFilter(raw, testFields); err == nil {
+		t.Error("expected an error")
+	}
+}
+
+func TestFilter_SQL(t *testing.T) {
+	filter, err := ParseFilter("status:nin:lost|won,score:ne:5,name:contains:50%_off,amount:gte:10", testFields)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	columns := map[string]string{"status": "o.status", "score": "o.score", "name": "o.name", "amount": "o.amount"}
+
+	predicate, args, err := filter.SQL(columns, 3)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	wantPredicate := "(o.status IS NULL OR o.status NOT IN ($3, $4)) AND o.score IS DISTINCT FROM $5 AND o.name ILIKE $6 AND o.amount >= $7"
+	if predicate != wantPredicate {
+		t.Errorf("expected predicate %q, got %q", wantPredicate, predicate)
+	}
+	wantArgs := []interface{}{"lost", "won", int64(5), `%50\%\_off%`, 10.0}
+	if !reflect.DeepEqual(args, wantArgs) {
+		t.Errorf("expected args %v, got %v", wantArgs, args)
+	}
+}
+
+func TestFilter_SQL_UnmappedField(t *testing.T) {
+	filter := Filter{{Field: "status", Op: Eq, Values: []interface{}{"open"}}}
+	if _, _, err := filter.SQL(map[string]string{}, 1); err == nil {
+		t.Error("expected an error")
+	}
+}
+
+func TestFilter_Mongo(t *testing.T) {
+	filter, err := ParseFilter("status:in:active|prospect,name:contains:a.b,score:lt:10", testFields)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	paths := map[string]string{"status": "status", "name": "name", "score": "stats.score"}
+
+	conditions, err := filter.Mongo(paths)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := []bson.M{
+		{"status": bson.M{"$in": []interface{}{"active", "prospect"}}},
+		{"name": bson.M{"$regex": `a\.b`, "$options": "i"}},
+		{"stats.score": bson.M{"$lt": int64(10)}},
+	}
+	if !reflect.DeepEqual(conditions, want) {
+		t.Errorf("expected %v, got %v", want, conditions)
+	}
+}
+
+func TestParseSort(t *testing.T) {
+	sort, err := ParseSort("-created_at, name", "created_at", "name")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := Sort{{Field: "created_at", Desc: true}, {Field: "name"}}
+	if !reflect.DeepEqual(sort, want) {
+		t.Errorf("expected %+v, got %+v", want, sort)
+	}
+	if sort[0].Order() != "desc" || sort[1].Order() != "asc" {
+		t.Errorf("unexpected orders %q, %q", sort[0].Order(), sort[1].Order())
+	}
+
+	if _, err := ParseSort("-password", "created_at", "name"); err == nil {
+		t.Error("expected an error for a field that is not allowed")
+	}
+}
@@ -0,0 +1,65 @@
+package query
+
+import (
+	"fmt"
+	"strings"
+)
+
+// sqlOperators maps the comparison operators to SQL. Ne uses IS DISTINCT FROM
+// so that, as in MongoDB, rows where the column is NULL match.
+var sqlOperators = map[Operator]string{
+	Eq:  "=",
+	Ne:  "IS DISTINCT FROM",
+	Gt:  ">",
+	Gte: ">=",
+	Lt:  "<",
+	Lte: "<=",
+}
+
+// SQL renders the filter as a predicate for a WHERE clause. Fields are mapped
+// to columns through columns, and placeholders are numbered from param. An
+// empty filter renders an empty predicate.
+func (f Filter) SQL(columns map[string]string, param int) (string, []interface{}, error) {
+	var (
+		clauses []string
+		args    []interface{}
+	)
+
+	for _, c := range f {
+		column, ok := columns[c.Field]
+		if !ok {
+			return "", nil, fmt.Errorf("no column for filter field %q", c.Field)
+		}
+
+		switch c.Op {
+		case In, Nin:
+			placeholders := make([]string, len(c.Values))
+			for i, v := range c.Values {
+				placeholders[i] = fmt.Sprintf("$%d", param)
+				args = append(args, v)
+				param++
+			}
+			list := strings.Join(placeholders, ", ")
+			if c.Op == In {
+				clauses = append(clauses, fmt.Sprintf("%s IN (%s)", column, list))
+			} else {
+				clauses = append(clauses, fmt.Sprintf("(%s IS NULL OR %s NOT IN (%s))", column, column, list))
+			}
+		case Contains:
+			clauses = append(clauses, fmt.Sprintf("%s ILIKE $%d", column, param))
+			args = append(args, "%"+escapeLike(c.Value().(string))+"%")
+			param++
+		default:
+			clauses = append(clauses, fmt.Sprintf("%s %s $%d", column, sqlOperators[c.Op], param))
+			args = append(args, c.Value())
+			param++
+		}
+	}
+
+	return strings.Join(clauses, " AND "), args, nil
+}
+
+// escapeLike escapes the LIKE wildcards in s.
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
+}
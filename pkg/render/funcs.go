@@ -0,0 +1,296 @@
+package render
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Date layouts that formatDate accepts by name.
+var dateLayouts = map[string]string{
+	"date":     "02/01/2006",
+	"datetime": "02/01/2006 15:04",
+	"time":     "15:04",
+	"long":     "2 January 2006",
+	"iso":      "2006-01-02",
+}
+
+// currencySymbols are the symbols amounts are prefixed with. Other currencies
+// are prefixed with their code.
+var currencySymbols = map[string]string{
+	"MYR": "RM",
+	"SGD": "S$",
+	"USD": "US$",
+	"EUR": "€",
+	"GBP": "£",
+}
+
+// Funcs returns the helper functions available to templates. Helpers take the
+// value last, so they can be used in pipelines:
+//
+//	{{.Total | currency}}                 RM1,234.50
+//	{{.Total | currencyIn "USD"}}         US$1,234.50
+//	{{.Quantity | number 0}}              1,234
+//	{{.DueDate | date}}                   31/12/2024
+//	{{.SentAt | formatDate "datetime"}}   31/12/2024 17:30
+//	{{.Name | default "Customer"}}
+//	{{len .Items | pluralize "item" "items"}}
+//
+// and upper, lower, title, trim, truncate, join, add, sub and mul.
+func Funcs(opts Options) template.FuncMap {
+	loc := opts.Location
+	if loc == nil {
+		loc = DefaultOptions().Location
+	}
+	currency := opts.Currency
+	if currency == "" {
+		currency = DefaultOptions().Currency
+	}
+
+	return template.FuncMap{
+		"currency": func(amount interface{}) (string, error) {
+			return formatCurrency(currency, amount)
+		},
+		"currencyIn": formatCurrency,
+		"number":     formatNumber,
+		"date": func(value interface{}) (string, error) {
+			return formatDate(dateLayouts["date"], value, loc)
+		},
+		"formatDate": func(layout string, value interface{}) (string, error) {
+			return formatDate(layout, value, loc)
+		},
+		"default":   defaultValue,
+		"pluralize": pluralize,
+		"upper":     strings.ToUpper,
+		"lower":     strings.ToLower,
+		"title":     title,
+		"trim":      strings.TrimSpace,
+		"truncate":  truncate,
+		"join":      join,
+		"add":       arithmetic(func(a, b float64) float64 { return a + b }),
+		"sub":       arithmetic(func(a, b float64) float64 { return a - b }),
+		"mul":       arithmetic(func(a, b float64) float64 { return a * b }),
+		// call would invoke functions found in the data
+		"call": func(...interface{}) (string, error) { return "", ErrFunctionNotAllowed },
+	}
+}
+
+// formatCurrency formats amount with two decimals and thousands separators,
+// prefixed with the currency's symbol: formatCurrency("MYR", 1234.5) is
+// "RM1,234.50".
+func formatCurrency(code string, amount interface{}) (string, error) {
+	f, err := toFloat(amount)
+	if err != nil {
+		return "", fmt.Errorf("currency: %w", err)
+	}
+
+	code = strings.ToUpper(code)
+	symbol, ok := currencySymbols[code]
+	if !ok {
+		symbol = code + " "
+	}
+
+	sign := ""
+	if f < 0 {
+		sign = "-"
+		f = -f
+	}
+	return sign + symbol + groupThousands(strconv.FormatFloat(f, 'f', 2, 64)), nil
+}
+
+// formatNumber formats value with decimals decimal places and thousands
+// separators.
+func formatNumber(decimals int, value interface{}) (string, error) {
+	f, err := toFloat(value)
+	if err != nil {
+		return "", fmt.Errorf("number: %w", err)
+	}
+	if decimals < 0 {
+		decimals = 0
+	}
+
+	s := strconv.FormatFloat(math.Abs(f), 'f', decimals, 64)
+	if f < 0 && strings.Trim(s, "0.") != "" {
+		return "-" + groupThousands(s), nil
+	}
+	return groupThousands(s), nil
+}
+
+// groupThousands inserts commas into the integer part of a formatted,
+// unsigned number.
+func groupThousands(s string) string {
+	intPart, frac := s, ""
+	if i := strings.IndexByte(s, '.'); i >= 0 {
+		intPart, frac = s[:i], s[i:]
+	}
+
+	var b strings.Builder
+	for i, r := range intPart {
+		if i > 0 && (len(intPart)-i)%3 == 0 {
+			b.WriteByte(',')
+		}
+		b.WriteRune(r)
+	}
+	return b.String() + frac
+}
+
+// formatDate formats value in loc with a layout name from dateLayouts or a Go
+// layout. value may be a time.Time or an RFC 3339 or YYYY-MM-DD string; a
+// missing value formats as "".
+func formatDate(layout string, value interface{}, loc *time.Location) (string, error) {
+	if named, ok := dateLayouts[layout]; ok {
+		layout = named
+	}
+
+	var t time.Time
+	switch v := value.(type) {
+	case nil:
+		return "", nil
+	case time.Time:
+		t = v.In(loc)
+	case *time.Time:
+		if v == nil {
+			return "", nil
+		}
+		t = v.In(loc)
+	case string:
+		if v == "" {
+			return "", nil
+		}
+		parsed, err := time.Parse(time.RFC3339, v)
+		if err == nil {
+			t = parsed.In(loc)
+			break
+		}
+		// A date without a time is a calendar date, not an instant.
+		t, err = time.ParseInLocation("2006-01-02", v, loc)
+		if err != nil {
+			return "", fmt.Errorf("date: cannot parse %q as a date", v)
+		}
+	default:
+		return "", fmt.Errorf("date: unsupported value of type %T", value)
+	}
+	return t.Format(layout), nil
+}
+
+// defaultValue returns value, or fallback when value is empty.
+func defaultValue(fallback, value interface{}) interface{} {
+	switch v := value.(type) {
+	case nil:
+		return fallback
+	case string:
+		if strings.TrimSpace(v) == "" {
+			return fallback
+		}
+	}
+	return value
+}
+
+// pluralize returns singular when count is 1 and plural otherwise.
+func pluralize(singular, plural string, count interface{}) (string, error) {
+	n, err := toFloat(count)
+	if err != nil {
+		return "", fmt.Errorf("pluralize: %w", err)
+	}
+	if n == 1 {
+		return singular, nil
+	}
+	return plural, nil
+}
+
+// title capitalizes the first letter of each word.
+func title(s string) string {
+	prev := ' '
+	return strings.Map(func(r rune) rune {
+		defer func() { prev = r }()
+		if unicode.IsSpace(prev) {
+			return unicode.ToUpper(r)
+		}
+		return r
+	}, s)
+}
+
+// truncate shortens s to at most length characters, ending in "..." when it
+// was cut.
+func truncate(length int, s string) string {
+	if utf8.RuneCountInString(s) <= length {
+		return s
+	}
+	if length <= 3 {
+		return string([]rune(s)[:max(length, 0)])
+	}
+	return string([]rune(s)[:length-3]) + "..."
+}
+
+// join joins the elements of list with sep.
+func join(sep string, list interface{}) (string, error) {
+	switch l := list.(type) {
+	case nil:
+		return "", nil
+	case []string:
+		return strings.Join(l, sep), nil
+	case []interface{}:
+		parts := make([]string, len(l))
+		for i, v := range l {
+			parts[i] = fmt.Sprint(v)
+		}
+		return strings.Join(parts, sep), nil
+	}
+	return "", fmt.Errorf("join: unsupported value of type %T", list)
+}
+
+func arithmetic(op func(a, b float64) float64) func(a, b interface{}) (float64, error) {
+	return func(a, b interface{}) (float64, error) {
+		x, err := toFloat(a)
+		if err != nil {
+			return 0, err
+		}
+		y, err := toFloat(b)
+		if err != nil {
+			return 0, err
+		}
+		return op(x, y), nil
+	}
+}
+
+// toFloat converts a numeric value, or a string holding one, to float64.
+func toFloat(value interface{}) (float64, error) {
+	switch v := value.(type) {
+	case float64:
+		return v, nil
+	case float32:
+		return float64(v), nil
+	case int:
+		return float64(v), nil
+	case int8:
+		return float64(v), nil
+	case int16:
+		return float64(v), nil
+	case int32:
+		return float64(v), nil
+	case int64:
+		return float64(v), nil
+	case uint:
+		return float64(v), nil
+	case uint8:
+		return float64(v), nil
+	case uint16:
+		return float64(v), nil
+	case uint32:
+		return float64(v), nil
+	case uint64:
+		return float64(v), nil
+	case string:
+		f, err := strconv.ParseFloat(strings.TrimSpace(v), 64)
+		if err != nil {
+			return 0, fmt.Errorf("%q is not a number", v)
+		}
+		return f, nil
+	}
+	return 0, fmt.Errorf("value of type %T is not a number", value)
+}
@@ -0,0 +1,94 @@
+package render
+
+import (
+	"testing"
+	"time"
+)
+
+func TestFormatCurrency(t *testing.T) {
+	tests := []struct {
+		code   string
+		amount interface{}
+		want   string
+	}{
+		{"MYR", 1234.5, "RM1,234.50"},
+		{"myr", 0, "RM0.00"},
+		{"MYR", -1999999, "-RM1,999,999.00"},
+		{"MYR", "88.888", "RM88.89"},
+		{"USD", 12, "US$12.00"},
+		{"IDR", 15000, "IDR 15,000.00"},
+	}
+
+	for _, tt := range tests {
+		got, err := formatCurrency(tt.code, tt.amount)
+		if err != nil {
+			t.Fatalf("formatCurrency(%q, %v) error: %v", tt.code, tt.amount, err)
+		}
+		if got != tt.want {
+			t.Errorf("formatCurrency(%q, %v) = %q, want %q", tt.code, tt.amount, got, tt.want)
+		}
+	}
+
+	if _, err := formatCurrency("MYR", "ten"); err == nil {
+		t.Error("expected an error for a non-numeric amount")
+	}
+}
+
+func TestFormatNumber(t *testing.T) {
+	if got, _ := formatNumber(0, 1234567.4); got != "1,234,567" {
+		t.Errorf("expected 1,234,567, got %s", got)
+	}
+	if got, _ := formatNumber(2, -0.001); got != "0.00" {
+		t.Errorf("expected 0.00, got %s", got)
+	}
+}
+
+func TestFormatDate(t *testing.T) {
+	loc := DefaultOptions().Location
+	instant := time.Date(2024, 12, 31, 20, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		layout string
+		value  interface{}
+		want   string
+	}{
+		{"date", instant, "01/01/2025"}, // 4am the next day in Malaysia
+		{"datetime", "2024-12-31T09:30:00Z", "31/12/2024 17:30"},
+		{"date", "2024-06-01", "01/06/2024"},
+		{"long", "2024-06-01", "1 June 2024"},
+		{"Jan 2006", &instant, "Jan 2025"},
+		{"date", nil, ""},
+	}
+
+	for _, tt := range tests {
+		got, err := formatDate(tt.layout, tt.value, loc)
+		if err != nil {
+			t.Fatalf("formatDate(%q, %v) error: %v", tt.layout, tt.value, err)
+		}
+		if got != tt.want {
+			t.Errorf("formatDate(%q, %v) = %q, want %q", tt.layout, tt.value, got, tt.want)
+		}
+	}
+
+	if _, err := formatDate("date", "31/12/2024", loc); err == nil {
+		t.Error("expected an error for an unparseable date")
+	}
+}
+
+func TestStringHelpers(t *testing.T) {
+	if got := title("siti nur aisyah"); got != "Siti Nur Aisyah" {
+		t.Errorf("title() = %q", got)
+	}
+	if got := truncate(10, "Kilang Desa Murni Batik"); got != "Kilang ..." {
+		t.Errorf("truncate() = %q", got)
+	}
+	if got := truncate(30, "Kilang"); got != "Kilang" {
+		t.Errorf("truncate() = %q", got)
+	}
+	if got := defaultValue("Customer", " "); got != "Customer" {
+		t.Errorf("defaultValue() = %v", got)
+	}
+	if got, _ := join(", ", []interface{}{"a", 1}); got != "a, 1" {
+		t.Errorf("join() = %q", got)
+	}
+}
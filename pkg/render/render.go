@@ -0,0 +1,275 @@
+// Package render renders notification templates written in Go template
+// syntax. Templates can use conditionals ({{if}}), loops ({{range .Items}})
+// and the helper functions in Funcs, such as currency and formatDate.
+//
+// Rendering is sandboxed: templates can only call the helpers, not functions
+// or methods reached through the data, and a render is stopped when it runs
+// longer than the timeout or writes more than the output limit.
+package render
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	htmltemplate "html/template"
+	"io"
+	"reflect"
+	"text/template"
+	"time"
+)
+
+var (
+	// ErrTimeout is returned when a render runs longer than the timeout.
+	ErrTimeout = errors.New("render: template execution timed out")
+	// ErrOutputTooLarge is returned when a render exceeds the output limit.
+	ErrOutputTooLarge = errors.New("render: template output too large")
+	// ErrFunctionNotAllowed is returned when a template calls a function
+	// value from its data.
+	ErrFunctionNotAllowed = errors.New("render: calling functions is not allowed")
+)
+
+// Options configures an Engine.
+type Options struct {
+	Timeout       time.Duration  // Maximum execution time of a render
+	MaxOutputSize int            // Maximum size of a rendered result, in bytes
+	Location      *time.Location // Time zone dates are formatted in
+	Currency      string         // Currency used when a template does not name one
+}
+
+// DefaultOptions returns the default options: renders time out after one
+// second, produce at most 1 MB, format dates in Malaysia time and amounts in
+// ringgit.
+func DefaultOptions() Options {
+	return Options{
+		Timeout:       time.Second,
+		MaxOutputSize: 1 << 20,
+		Location:      time.FixedZone("MYT", 8*60*60),
+		Currency:      "MYR",
+	}
+}
+
+// Engine renders templates with a fixed set of helper functions.
+type Engine struct {
+	opts  Options
+	funcs template.FuncMap
+}
+
+// New creates an Engine. Zero options take their default value.
+func New(opts Options) *Engine {
+	defaults := DefaultOptions()
+	if opts.Timeout <= 0 {
+		opts.Timeout = defaults.Timeout
+	}
+	if opts.MaxOutputSize <= 0 {
+		opts.MaxOutputSize = defaults.MaxOutputSize
+	}
+	if opts.Location == nil {
+		opts.Location = defaults.Location
+	}
+	if opts.Currency == "" {
+		opts.Currency = defaults.Currency
+	}
+
+	e := &Engine{opts: opts}
+	e.funcs = Funcs(opts)
+	return e
+}
+
+var defaultEngine = New(DefaultOptions())
+
+// Text renders src as plain text with the default engine.
+func Text(src string, data map[string]interface{}) (string, error) {
+	return defaultEngine.Text(src, data)
+}
+
+// HTML renders src as HTML with the default engine.
+func HTML(src string, data map[string]interface{}) (string, error) {
+	return defaultEngine.HTML(src, data)
+}
+
+// Validate checks the syntax of src with the default engine.
+func Validate(src string) error {
+	return defaultEngine.Validate(src)
+}
+
+// Text renders src as plain text, for subjects, SMS and push bodies.
+func (e *Engine) Text(src string, data map[string]interface{}) (string, error) {
+	if src == "" {
+		return "", nil
+	}
+
+	tmpl, err := template.New("notification").Funcs(e.funcs).Parse(src)
+	if err != nil {
+		return "", err
+	}
+	return e.execute(tmpl, data)
+}
+
+// HTML renders src as HTML. Values are escaped for the context they appear
+// in, so data cannot inject markup or scripts.
+func (e *Engine) HTML(src string, data map[string]interface{}) (string, error) {
+	if src == "" {
+		return "", nil
+	}
+
+	tmpl, err := htmltemplate.New("notification").Funcs(htmltemplate.FuncMap(e.funcs)).Parse(src)
+	if err != nil {
+		return "", err
+	}
+	return e.execute(tmpl, data)
+}
+
+// Validate checks the syntax of src, including that it only calls known
+// helpers.
+func (e *Engine) Validate(src string) error {
+	_, err := template.New("notification").Funcs(e.funcs).Parse(src)
+	return err
+}
+
+// executor is implemented by text and HTML templates.
+type executor interface {
+	Execute(w io.Writer, data interface{}) error
+}
+
+// execute runs tmpl on a sandboxed copy of data within the engine's limits.
+func (e *Engine) execute(tmpl executor, data map[string]interface{}) (string, error) {
+	safe, err := sandbox(data)
+	if err != nil {
+		return "", err
+	}
+
+	out := &limitedWriter{
+		max:      e.opts.MaxOutputSize,
+		deadline: time.Now().Add(e.opts.Timeout),
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		done <- tmpl.Execute(out, safe)
+	}()
+
+	select {
+	case err := <-done:
+		// The limit errors come back wrapped in the template's position.
+		switch {
+		case err == nil:
+			return out.buf.String(), nil
+		case errors.Is(err, ErrTimeout):
+			return "", ErrTimeout
+		case errors.Is(err, ErrOutputTooLarge):
+			return "", ErrOutputTooLarge
+		}
+		return "", err
+	case <-time.After(e.opts.Timeout):
+		// The template stops at its next write, which fails past the deadline.
+		return "", ErrTimeout
+	}
+}
+
+// limitedWriter fails writes past its size limit or deadline, which stops the
+// template executing.
+type limitedWriter struct {
+	buf      bytes.Buffer
+	max      int
+	deadline time.Time
+}
+
+func (w *limitedWriter) Write(p []byte) (int, error) {
+	if time.Now().After(w.deadline) {
+		return 0, ErrTimeout
+	}
+	if w.buf.Len()+len(p) > w.max {
+		return 0, ErrOutputTooLarge
+	}
+	return w.buf.Write(p)
+}
+
+// sandbox returns a copy of data holding only maps, slices, strings, numbers,
+// booleans and times, so a template cannot reach functions or methods through
+// it. Other values are converted through their JSON form.
+func sandbox(data map[string]interface{}) (map[string]interface{}, error) {
+	safe := make(map[string]interface{}, len(data))
+	for k, v := range data {
+		sv, err := sandboxValue(k, v)
+		if err != nil {
+			return nil, err
+		}
+		safe[k] = sv
+	}
+	return safe, nil
+}
+
+func sandboxValue(path string, v interface{}) (interface{}, error) {
+	if v == nil {
+		return nil, nil
+	}
+	if t, ok := v.(time.Time); ok {
+		return t, nil
+	}
+
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Bool, reflect.String,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		// Named types are reduced to their basic kind, dropping their methods.
+		return rv.Convert(basicTypes[rv.Kind()]).Interface(), nil
+	case reflect.Func, reflect.Chan, reflect.UnsafePointer:
+		return nil, fmt.Errorf("%w: variable %s", ErrFunctionNotAllowed, path)
+	case reflect.Map:
+		if rv.Type().Key().Kind() != reflect.String {
+			break
+		}
+		m := make(map[string]interface{}, rv.Len())
+		iter := rv.MapRange()
+		for iter.Next() {
+			key := iter.Key().String()
+			sv, err := sandboxValue(path+"."+key, iter.Value().Interface())
+			if err != nil {
+				return nil, err
+			}
+			m[key] = sv
+		}
+		return m, nil
+	case reflect.Slice, reflect.Array:
+		s := make([]interface{}, rv.Len())
+		for i := range s {
+			sv, err := sandboxValue(fmt.Sprintf("%s[%d]", path, i), rv.Index(i).Interface())
+			if err != nil {
+				return nil, err
+			}
+			s[i] = sv
+		}
+		return s, nil
+	}
+
+	// Structs, pointers and anything else are reduced to their JSON form.
+	encoded, err := json.Marshal(v)
+	if err != nil {
+		return nil, fmt.Errorf("render: variable %s: %w", path, err)
+	}
+	var plain interface{}
+	if err := json.Unmarshal(encoded, &plain); err != nil {
+		return nil, fmt.Errorf("render: variable %s: %w", path, err)
+	}
+	return plain, nil
+}
+
+var basicTypes = map[reflect.Kind]reflect.Type{
+	reflect.Bool:    reflect.TypeOf(false),
+	reflect.String:  reflect.TypeOf(""),
+	reflect.Int:     reflect.TypeOf(int(0)),
+	reflect.Int8:    reflect.TypeOf(int8(0)),
+	reflect.Int16:   reflect.TypeOf(int16(0)),
+	reflect.Int32:   reflect.TypeOf(int32(0)),
+	reflect.Int64:   reflect.TypeOf(int64(0)),
+	reflect.Uint:    reflect.TypeOf(uint(0)),
+	reflect.Uint8:   reflect.TypeOf(uint8(0)),
+	reflect.Uint16:  reflect.TypeOf(uint16(0)),
+	reflect.Uint32:  reflect.TypeOf(uint32(0)),
+	reflect.Uint64:  reflect.TypeOf(uint64(0)),
+	reflect.Float32: reflect.TypeOf(float32(0)),
+	reflect.Float64: reflect.TypeOf(float64(0)),
+}
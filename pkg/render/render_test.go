@@ -0,0 +1,90 @@
+package render
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestText_ConditionalsAndLoops(t *testing.T) {
+	src := `Order {{.Number}}{{if .Paid}} (paid){{end}}
+{{range .Items}}- {{.Name}} x{{.Qty}}: {{mul .Qty .Price | currency}}
+{{end}}Total: {{.Total | currency}}, {{len .Items}} {{len .Items | pluralize "item" "items"}}, due {{.DueDate | date}}`
+
+	got, err := Text(src, map[string]interface{}{
+		"Number": "SO-1001",
+		"Paid":   true,
+		"Items": []map[string]interface{}{
+			{"Name": "Batik Sarong", "Qty": 2, "Price": 150.0},
+			{"Name": "Silk Scarf", "Qty": 1, "Price": 1234.5},
+		},
+		"Total":   1534.5,
+		"DueDate": "2024-12-31",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := `Order SO-1001 (paid)
+- Batik Sarong x2: RM300.00
+- Silk Scarf x1: RM1,234.50
+Total: RM1,534.50, 2 items, due 31/12/2024`
+	if got != want {
+		t.Errorf("expected\n%s\ngot\n%s", want, got)
+	}
+}
+
+func TestHTML_EscapesData(t *testing.T) {
+	got, err := HTML(`<p>Hello {{.Name}}</p>`, map[string]interface{}{"Name": `<script>alert(1)</script>`})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if strings.Contains(got, "<script>") {
+		t.Errorf("expected the name to be escaped, got %s", got)
+	}
+}
+
+type account struct {
+	Name string `json:"name"`
+}
+
+func (account) Delete() string { return "deleted" }
+
+func TestText_Sandbox(t *testing.T) {
+	// Functions in the data cannot be called
+	_, err := Text(`{{call .Fn}}`, map[string]interface{}{"Fn": func() string { return "ran" }})
+	if !errors.Is(err, ErrFunctionNotAllowed) {
+		t.Errorf("expected %v, got %v", ErrFunctionNotAllowed, err)
+	}
+
+	// Structs are reduced to their data, so their methods are not reachable
+	got, err := Text(`{{.Account.Delete}}`, map[string]interface{}{"Account": account{Name: "Aisyah"}})
+	if err != nil || got == "deleted" {
+		t.Errorf("expected the method not to be called, got %q (%v)", got, err)
+	}
+	got, err = Text(`{{.Account.name}}`, map[string]interface{}{"Account": &account{Name: "Aisyah"}})
+	if err != nil || got != "Aisyah" {
+		t.Errorf("expected the struct's data to render, got %q (%v)", got, err)
+	}
+
+	// Unknown functions are rejected at parse time
+	if err := Validate(`{{exec "rm"}}`); err == nil {
+		t.Error("expected an unknown function to fail validation")
+	}
+}
+
+func TestEngine_Limits(t *testing.T) {
+	engine := New(Options{MaxOutputSize: 10})
+	_, err := engine.Text(`{{range .Items}}0123456789{{end}}`, map[string]interface{}{"Items": []int{1, 2}})
+	if err != ErrOutputTooLarge {
+		t.Errorf("expected %v, got %v", ErrOutputTooLarge, err)
+	}
+
+	engine = New(Options{Timeout: 20 * time.Millisecond})
+	items := make([]int, 2000)
+	_, err = engine.Text(`{{range .Items}}{{range $.Items}}{{range $.Items}}.{{end}}{{end}}{{end}}`, map[string]interface{}{"Items": items})
+	if err != ErrTimeout {
+		t.Errorf("expected %v, got %v", ErrTimeout, err)
+	}
+}
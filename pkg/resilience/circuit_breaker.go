@@ -375,6 +375,29 @@ func (cb *CircuitBreaker) Reset() {
 	cb.setState(StateClosed, time.Now())
 }
 
+// Reconfigure changes the thresholds of the circuit breaker while keeping its
+// state and counts. Zero values and nil functions leave the current setting.
+func (cb *CircuitBreaker) Reconfigure(config CircuitBreakerConfig) {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	if config.MaxRequests > 0 {
+		cb.maxRequests = config.MaxRequests
+	}
+	if config.Interval > 0 {
+		cb.interval = config.Interval
+	}
+	if config.Timeout > 0 {
+		cb.timeout = config.Timeout
+	}
+	if config.ReadyToTrip != nil {
+		cb.readyToTrip = config.ReadyToTrip
+	}
+	if config.IsSuccessful != nil {
+		cb.isSuccessful = config.IsSuccessful
+	}
+}
+
 // ============================================================================
 // Circuit Breaker Registry
 // ============================================================================
@@ -442,6 +465,18 @@ func (r *CircuitBreakerRegistry) List() map[string]*CircuitBreaker {
 	return result
 }
 
+// Reconfigure changes the configuration of new circuit breakers and the
+// thresholds of existing ones.
+func (r *CircuitBreakerRegistry) Reconfigure(config CircuitBreakerConfig) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.config = config
+	for _, cb := range r.breakers {
+		cb.Reconfigure(config)
+	}
+}
+
 // ResetAll resets all circuit breakers.
 func (r *CircuitBreakerRegistry) ResetAll() {
 	r.mu.RLock()
@@ -0,0 +1,350 @@
+package resilience
+
+import (
+	"context"
+	"errors"
+	"math"
+	"sync"
+	"time"
+)
+
+// ============================================================================
+// Limiter Errors
+// ============================================================================
+
+var (
+	// ErrLimitExceeded is returned when the limit is reached and the queue
+	// is full.
+	ErrLimitExceeded = errors.New("concurrency limit exceeded")
+
+	// ErrLimitQueueTimeout is returned when a queued call is not admitted in
+	// time.
+	ErrLimitQueueTimeout = errors.New("concurrency limit queue timeout")
+)
+
+// ============================================================================
+// Limiter Configuration
+// ============================================================================
+
+// Limit algorithms.
+const (
+	// LimitAlgorithmAIMD grows the limit by one per fast call and cuts it
+	// by BackoffRatio when a call is slow or dropped.
+	LimitAlgorithmAIMD = "aimd"
+	// LimitAlgorithmGradient moves the limit by the ratio of the long-term
+	// to the recent latency, shrinking it as soon as queueing shows up in
+	// the latency, without a fixed latency threshold.
+	LimitAlgorithmGradient = "gradient"
+)
+
+// LimiterConfig configures the adaptive limiter.
+type LimiterConfig struct {
+	// Name of the limiter for identification.
+	Name string
+
+	// Algorithm is LimitAlgorithmAIMD or LimitAlgorithmGradient.
+	Algorithm string
+
+	// InitialLimit, MinLimit and MaxLimit bound the concurrent calls.
+	InitialLimit int
+	MinLimit     int
+	MaxLimit     int
+
+	// LatencyThreshold is the slowest call AIMD counts as healthy.
+	LatencyThreshold time.Duration
+
+	// BackoffRatio is what AIMD multiplies the limit by on overload.
+	BackoffRatio float64
+
+	// QueueSize is how many calls may wait for a slot once the limit is
+	// reached, and QueueTimeout how long each may wait.
+	QueueSize    int
+	QueueTimeout time.Duration
+
+	// BackgroundShare is the share of the limit background calls, such as
+	// imports and exports, may take, leaving the rest to interactive calls.
+	BackgroundShare float64
+}
+
+// DefaultLimiterConfig returns default limiter configuration.
+func DefaultLimiterConfig(name string) LimiterConfig {
+	return LimiterConfig{
+		Name:             name,
+		Algorithm:        LimitAlgorithmGradient,
+		InitialLimit:     50,
+		MinLimit:         10,
+		MaxLimit:         500,
+		LatencyThreshold: time.Second,
+		BackoffRatio:     0.9,
+		QueueSize:        100,
+		QueueTimeout:     500 * time.Millisecond,
+		BackgroundShare:  0.5,
+	}
+}
+
+// ============================================================================
+// Adaptive Limiter
+// ============================================================================
+
+// AdaptiveLimiter limits concurrent calls to a limit that follows the
+// latency of the calls: it grows while latency stays flat and shrinks when
+// latency rises or calls are dropped, so excess load waits briefly in a
+// bounded queue or is shed instead of slowing every call down.
+type AdaptiveLimiter struct {
+	config LimiterConfig
+
+	mu       sync.Mutex
+	limit    float64
+	inFlight int
+	waiters  []*limitWaiter
+
+	// Latency averages of the gradient algorithm, in seconds.
+	longRTT  float64
+	shortRTT float64
+
+	admitted       uint64
+	bypassed       uint64
+	shedQueueFull  uint64
+	shedTimeout    uint64
+	shedBackground uint64
+}
+
+type limitWaiter struct {
+	ready   chan struct{}
+	granted bool
+}
+
+// LimiterRelease ends a call admitted by the limiter. Dropped reports that
+// the call failed from overload, such as a timeout; it must be called once.
+type LimiterRelease func(dropped bool)
+
+// NewAdaptiveLimiter creates a new adaptive limiter.
+func NewAdaptiveLimiter(config LimiterConfig) *AdaptiveLimiter {
+	defaults := DefaultLimiterConfig(config.Name)
+	if config.Algorithm != LimitAlgorithmAIMD {
+		config.Algorithm = LimitAlgorithmGradient
+	}
+	if config.MinLimit <= 0 {
+		config.MinLimit = defaults.MinLimit
+	}
+	if config.MaxLimit < config.MinLimit {
+		config.MaxLimit = max(defaults.MaxLimit, config.MinLimit)
+	}
+	if config.InitialLimit <= 0 {
+		config.InitialLimit = defaults.InitialLimit
+	}
+	config.InitialLimit = min(max(config.InitialLimit, config.MinLimit), config.MaxLimit)
+	if config.LatencyThreshold <= 0 {
+		config.LatencyThreshold = defaults.LatencyThreshold
+	}
+	if config.BackoffRatio <= 0 || config.BackoffRatio >= 1 {
+		config.BackoffRatio = defaults.BackoffRatio
+	}
+	if config.QueueSize < 0 {
+		config.QueueSize = 0
+	}
+	if config.BackgroundShare <= 0 || config.BackgroundShare > 1 {
+		config.BackgroundShare = defaults.BackgroundShare
+	}
+
+	return &AdaptiveLimiter{
+		config: config,
+		limit:  float64(config.InitialLimit),
+	}
+}
+
+// Acquire admits a call, waiting in the queue for up to QueueTimeout when
+// the limit is reached. It returns ErrLimitExceeded when the queue is full
+// and ErrLimitQueueTimeout or the context's error when the wait ends first.
+func (l *AdaptiveLimiter) Acquire(ctx context.Context) (LimiterRelease, error) {
+	l.mu.Lock()
+	if l.inFlight < l.currentLimit() && len(l.waiters) == 0 {
+		l.inFlight++
+		l.admitted++
+		l.mu.Unlock()
+		return l.release(time.Now(), true), nil
+	}
+	if len(l.waiters) >= l.config.QueueSize {
+		l.shedQueueFull++
+		l.mu.Unlock()
+		return nil, ErrLimitExceeded
+	}
+	w := &limitWaiter{ready: make(chan struct{})}
+	l.waiters = append(l.waiters, w)
+	l.mu.Unlock()
+
+	timer := time.NewTimer(l.config.QueueTimeout)
+	defer timer.Stop()
+
+	var err error
+	select {
+	case <-w.ready:
+		return l.release(time.Now(), true), nil
+	case <-timer.C:
+		err = ErrLimitQueueTimeout
+	case <-ctx.Done():
+		err = ctx.Err()
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if w.granted {
+		// Admitted while giving up; take the slot after all
+		return l.release(time.Now(), true), nil
+	}
+	for i, queued := range l.waiters {
+		if queued == w {
+			l.waiters = append(l.waiters[:i], l.waiters[i+1:]...)
+			break
+		}
+	}
+	l.shedTimeout++
+	return nil, err
+}
+
+// AcquireBackground admits a background call while calls take less than
+// BackgroundShare of the limit and none are queued. Background calls never
+// wait, so interactive calls are always served first; it returns
+// ErrLimitExceeded instead.
+func (l *AdaptiveLimiter) AcquireBackground() (LimiterRelease, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if len(l.waiters) > 0 || float64(l.inFlight) >= l.limit*l.config.BackgroundShare {
+		l.shedBackground++
+		return nil, ErrLimitExceeded
+	}
+	l.inFlight++
+	l.admitted++
+	return l.release(time.Now(), true), nil
+}
+
+// Admit admits a call regardless of the limit, for calls that must never be
+// shed such as health checks. The call takes a slot but does not adjust
+// the limit.
+func (l *AdaptiveLimiter) Admit() LimiterRelease {
+	l.mu.Lock()
+	l.inFlight++
+	l.bypassed++
+	l.mu.Unlock()
+	return l.release(time.Now(), false)
+}
+
+// release returns the function ending a call started at start.
+func (l *AdaptiveLimiter) release(start time.Time, sample bool) LimiterRelease {
+	return func(dropped bool) {
+		rtt := time.Since(start)
+
+		l.mu.Lock()
+		defer l.mu.Unlock()
+		if sample {
+			l.update(rtt, dropped)
+		}
+		l.inFlight--
+		l.grant()
+	}
+}
+
+// currentLimit returns the limit as a whole number of calls; l.mu must be
+// held.
+func (l *AdaptiveLimiter) currentLimit() int {
+	return int(l.limit)
+}
+
+// grant admits queued calls while there is room; l.mu must be held.
+func (l *AdaptiveLimiter) grant() {
+	for len(l.waiters) > 0 && l.inFlight < l.currentLimit() {
+		w := l.waiters[0]
+		l.waiters = l.waiters[1:]
+		w.granted = true
+		l.inFlight++
+		l.admitted++
+		close(w.ready)
+	}
+}
+
+// update adjusts the limit with the outcome of a call; l.mu must be held.
+func (l *AdaptiveLimiter) update(rtt time.Duration, dropped bool) {
+	// The limit only grows while it is being used, so a quiet period does
+	// not leave it far above what the service can take
+	used := l.inFlight*2 >= l.currentLimit()
+
+	limit := l.limit
+	switch l.config.Algorithm {
+	case LimitAlgorithmAIMD:
+		switch {
+		case dropped || rtt > l.config.LatencyThreshold:
+			limit *= l.config.BackoffRatio
+		case used:
+			limit++
+		}
+	default:
+		limit = l.gradient(rtt.Seconds(), dropped, used)
+	}
+
+	l.limit = math.Min(math.Max(limit, float64(l.config.MinLimit)), float64(l.config.MaxLimit))
+}
+
+// gradient is the next limit of the gradient algorithm; l.mu must be held.
+func (l *AdaptiveLimiter) gradient(rtt float64, dropped, used bool) float64 {
+	if l.longRTT == 0 {
+		l.longRTT, l.shortRTT = rtt, rtt
+	}
+	l.shortRTT = 0.9*l.shortRTT + 0.1*rtt
+	l.longRTT = l.longRTT + (rtt-l.longRTT)/600
+	// After a slow spell the long average lags; pull it down so the limit
+	// can grow again
+	if l.longRTT > 2*l.shortRTT {
+		l.longRTT *= 0.95
+	}
+
+	if dropped {
+		return l.limit * l.config.BackoffRatio
+	}
+	if l.shortRTT <= 0 {
+		return l.limit
+	}
+
+	// Latency up to 1.5 times the long-term average is tolerated before the
+	// limit shrinks; the square root headroom lets it probe upwards
+	gradient := math.Min(math.Max(1.5*l.longRTT/l.shortRTT, 0.5), 1)
+	next := l.limit*gradient + math.Sqrt(l.limit)
+	if next > l.limit && !used {
+		return l.limit
+	}
+	return 0.8*l.limit + 0.2*next
+}
+
+// ============================================================================
+// Limiter Stats
+// ============================================================================
+
+// LimiterStats is the state of an adaptive limiter.
+type LimiterStats struct {
+	Name           string
+	Limit          int
+	InFlight       int
+	Queued         int
+	Admitted       uint64
+	Bypassed       uint64
+	ShedQueueFull  uint64
+	ShedTimeout    uint64
+	ShedBackground uint64
+}
+
+// Stats returns the state of the limiter.
+func (l *AdaptiveLimiter) Stats() LimiterStats {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return LimiterStats{
+		Name:           l.config.Name,
+		Limit:          l.currentLimit(),
+		InFlight:       l.inFlight,
+		Queued:         len(l.waiters),
+		Admitted:       l.admitted,
+		Bypassed:       l.bypassed,
+		ShedQueueFull:  l.shedQueueFull,
+		ShedTimeout:    l.shedTimeout,
+		ShedBackground: l.shedBackground,
+	}
+}
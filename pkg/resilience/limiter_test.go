@@ -0,0 +1,197 @@
+package resilience
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestAdaptiveLimiter_QueuesThenSheds(t *testing.T) {
+	l := NewAdaptiveLimiter(LimiterConfig{
+		Algorithm:    LimitAlgorithmAIMD,
+		InitialLimit: 2,
+		MinLimit:     2,
+		MaxLimit:     2,
+		QueueSize:    1,
+		QueueTimeout: 50 * time.Millisecond,
+	})
+	ctx := context.Background()
+
+	first, err := l.Acquire(ctx)
+	if err != nil {
+		t.Fatalf("acquire: %v", err)
+	}
+	if _, err := l.Acquire(ctx); err != nil {
+		t.Fatalf("acquire: %v", err)
+	}
+
+	// The third call waits and is admitted when a slot frees up
+	admitted := make(chan error, 1)
+	go func() {
+		release, err := l.Acquire(ctx)
+		if err == nil {
+			release(false)
+		}
+		admitted <- err
+	}()
+	waitFor(t, func() bool { return l.Stats().Queued == 1 })
+
+	// The queue is full
+	if _, err := l.Acquire(ctx); !errors.Is(err, ErrLimitExceeded) {
+		t.Fatalf("expected ErrLimitExceeded, got %v", err)
+	}
+
+	first(false)
+	if err := <-admitted; err != nil {
+		t.Fatalf("expected the queued call admitted, got %v", err)
+	}
+
+	// Nothing frees up in time
+	if _, err := l.Acquire(ctx); err != nil {
+		t.Fatalf("acquire: %v", err)
+	}
+	if _, err := l.Acquire(ctx); !errors.Is(err, ErrLimitQueueTimeout) {
+		t.Fatalf("expected ErrLimitQueueTimeout, got %v", err)
+	}
+
+	// Bypassed calls are admitted over the limit
+	l.Admit()(false)
+
+	stats := l.Stats()
+	if stats.Admitted != 4 || stats.ShedQueueFull != 1 || stats.ShedTimeout != 1 || stats.Bypassed != 1 || stats.Queued != 0 {
+		t.Errorf("unexpected stats %+v", stats)
+	}
+}
+
+func TestAdaptiveLimiter_BackgroundCallsLeaveRoom(t *testing.T) {
+	l := NewAdaptiveLimiter(LimiterConfig{
+		Algorithm:       LimitAlgorithmAIMD,
+		InitialLimit:    4,
+		MinLimit:        4,
+		MaxLimit:        4,
+		QueueSize:       1,
+		QueueTimeout:    time.Second,
+		BackgroundShare: 0.5,
+	})
+
+	// Background calls take up to half of the limit
+	background := make([]LimiterRelease, 0, 2)
+	for i := 0; i < 2; i++ {
+		release, err := l.AcquireBackground()
+		if err != nil {
+			t.Fatalf("acquire background: %v", err)
+		}
+		background = append(background, release)
+	}
+	if _, err := l.AcquireBackground(); !errors.Is(err, ErrLimitExceeded) {
+		t.Fatalf("expected ErrLimitExceeded over the background share, got %v", err)
+	}
+
+	// Interactive calls take the rest
+	interactive := acquireN(t, l, 2)
+
+	// and a freed slot goes to a queued interactive call first
+	queued := make(chan error, 1)
+	go func() {
+		release, err := l.Acquire(context.Background())
+		if err == nil {
+			release(false)
+		}
+		queued <- err
+	}()
+	waitFor(t, func() bool { return l.Stats().Queued == 1 })
+	background[0](false)
+	if _, err := l.AcquireBackground(); !errors.Is(err, ErrLimitExceeded) {
+		t.Fatalf("expected ErrLimitExceeded after the queued call, got %v", err)
+	}
+	if err := <-queued; err != nil {
+		t.Fatalf("expected the queued call admitted, got %v", err)
+	}
+
+	background[1](false)
+	for _, release := range interactive {
+		release(false)
+	}
+	if stats := l.Stats(); stats.ShedBackground != 2 || stats.InFlight != 0 {
+		t.Errorf("unexpected stats %+v", stats)
+	}
+}
+
+func TestAdaptiveLimiter_AIMD(t *testing.T) {
+	l := NewAdaptiveLimiter(LimiterConfig{
+		Algorithm:        LimitAlgorithmAIMD,
+		InitialLimit:     10,
+		MinLimit:         5,
+		MaxLimit:         20,
+		LatencyThreshold: time.Second,
+		BackoffRatio:     0.5,
+	})
+
+	// Fast calls grow it by one each while at least half of it is in use
+	releases := acquireN(t, l, 10)
+	for _, release := range releases[:5] {
+		release(false)
+	}
+	if got := l.Stats().Limit; got != 14 {
+		t.Fatalf("expected the limit to grow to 14, got %d", got)
+	}
+
+	// A dropped call halves it, down to the minimum
+	releases[5](true)
+	if got := l.Stats().Limit; got != 7 {
+		t.Fatalf("expected the limit halved to 7, got %d", got)
+	}
+	releases[6](true)
+	if got := l.Stats().Limit; got != 5 {
+		t.Fatalf("expected the limit at the minimum of 5, got %d", got)
+	}
+}
+
+func TestAdaptiveLimiter_GradientShrinksOnLatency(t *testing.T) {
+	l := NewAdaptiveLimiter(LimiterConfig{InitialLimit: 100, MinLimit: 10, MaxLimit: 200})
+
+	l.mu.Lock()
+	for i := 0; i < 200; i++ {
+		l.inFlight = 100
+		l.update(10*time.Millisecond, false)
+	}
+	steady := l.currentLimit()
+	for i := 0; i < 50; i++ {
+		l.inFlight = 100
+		l.update(200*time.Millisecond, false)
+	}
+	slow := l.currentLimit()
+	l.mu.Unlock()
+
+	if steady < 100 {
+		t.Errorf("expected the limit to hold or grow at steady latency, got %d", steady)
+	}
+	if slow > steady/2 {
+		t.Errorf("expected the limit to shrink when latency rose, got %d from %d", slow, steady)
+	}
+}
+
+func acquireN(t *testing.T, l *AdaptiveLimiter, n int) []LimiterRelease {
+	t.Helper()
+	releases := make([]LimiterRelease, n)
+	for i := range releases {
+		release, err := l.Acquire(context.Background())
+		if err != nil {
+			t.Fatalf("acquire %d: %v", i, err)
+		}
+		releases[i] = release
+	}
+	return releases
+}
+
+func waitFor(t *testing.T, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatal("condition not met")
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
@@ -0,0 +1,48 @@
+package response
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+)
+
+// ErrInvalidCursor is returned when a pagination cursor cannot be decoded or
+// was issued for a different sort order.
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// Cursor is a keyset pagination position: the sort key of the last item of a
+// page, with the item's ID as a tie-breaker. The next page continues after
+// it, so rows inserted or deleted meanwhile do not shift the page boundary
+// the way they do with offsets.
+//
+// Clients receive cursors as opaque strings and send them back unchanged.
+type Cursor struct {
+	SortBy    string `json:"s"`
+	SortOrder string `json:"o"`
+	Value     string `json:"v"`
+	ID        string `json:"i"`
+}
+
+// Encode returns the opaque form of the cursor.
+func (c Cursor) Encode() string {
+	data, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// DecodeCursor decodes an opaque cursor and checks that it was issued for the
+// given sort.
+func DecodeCursor(raw, sortBy, sortOrder string) (Cursor, error) {
+	var c Cursor
+
+	data, err := base64.RawURLEncoding.DecodeString(raw)
+	if err != nil {
+		return c, ErrInvalidCursor
+	}
+	if err := json.Unmarshal(data, &c); err != nil || c.ID == "" {
+		return Cursor{}, ErrInvalidCursor
+	}
+	if c.SortBy != sortBy || c.SortOrder != sortOrder {
+		return Cursor{}, ErrInvalidCursor
+	}
+	return c, nil
+}
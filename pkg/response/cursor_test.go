@@ -0,0 +1,44 @@
+package response
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestCursor_RoundTrip(t *testing.T) {
+	cursor := Cursor{SortBy: "created_at", SortOrder: "desc", Value: "2024-05-01T08:30:00.123456Z", ID: "c1"}
+
+	encoded := cursor.Encode()
+	decoded, err := DecodeCursor(encoded, "created_at", "desc")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if decoded != cursor {
+		t.Errorf("expected %+v, got %+v", cursor, decoded)
+	}
+}
+
+func TestDecodeCursor_Invalid(t *testing.T) {
+	valid := Cursor{SortBy: "name", SortOrder: "asc", Value: "Batik", ID: "c1"}.Encode()
+
+	tests := []struct {
+		name      string
+		raw       string
+		sortBy    string
+		sortOrder string
+	}{
+		{"not base64", "!!!", "name", "asc"},
+		{"not json", "bm90IGpzb24", "name", "asc"},
+		{"missing id", Cursor{SortBy: "name", SortOrder: "asc"}.Encode(), "name", "asc"},
+		{"different sort field", valid, "created_at", "asc"},
+		{"different sort order", valid, "name", "desc"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := DecodeCursor(tt.raw, tt.sortBy, tt.sortOrder); !errors.Is(err, ErrInvalidCursor) {
+				t.Errorf("expected ErrInvalidCursor, got %v", err)
+			}
+		})
+	}
+}
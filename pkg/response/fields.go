@@ -0,0 +1,195 @@
+package response
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// Fields is a sparse fieldset: the fields selected from an object, each
+// mapped to the selection of its own fields. A nil selection means the whole
+// value of the field.
+//
+// A fieldset is parsed from a ?fields= query parameter of comma-separated,
+// dot-separated paths, e.g. "id,name,owner.name".
+type Fields map[string]Fields
+
+// FieldError reports a ?fields= path that the resource does not allow.
+type FieldError struct {
+	Path   string
+	Reason string
+}
+
+// Error implements the error interface.
+func (e *FieldError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Path, e.Reason)
+}
+
+// FieldsOf returns the fields that may be selected from a response type,
+// derived from its JSON tags. Nested structs, pointers to structs and slices
+// of structs are selectable by path; everything else is a leaf.
+func FieldsOf(v interface{}) Fields {
+	return fieldsOf(reflect.TypeOf(v), make(map[reflect.Type]bool))
+}
+
+var (
+	jsonMarshalerType = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+	textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+)
+
+func fieldsOf(t reflect.Type, visiting map[reflect.Type]bool) Fields {
+	for t != nil && (t.Kind() == reflect.Ptr || t.Kind() == reflect.Slice || t.Kind() == reflect.Array) {
+		if t.Kind() == reflect.Array && isLeaf(t) {
+			return nil
+		}
+		t = t.Elem()
+	}
+	if t == nil || t.Kind() != reflect.Struct || isLeaf(t) || visiting[t] {
+		return nil
+	}
+	visiting[t] = true
+	defer delete(visiting, t)
+
+	fields := make(Fields)
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag := field.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, _, _ := strings.Cut(tag, ",")
+
+		if field.Anonymous && name == "" {
+			for embedded, sub := range fieldsOf(field.Type, visiting) {
+				fields[embedded] = sub
+			}
+			continue
+		}
+		if !field.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		fields[name] = fieldsOf(field.Type, visiting)
+	}
+	return fields
+}
+
+// isLeaf reports whether a type marshals itself, e.g. time.Time or uuid.UUID.
+func isLeaf(t reflect.Type) bool {
+	return t.Implements(jsonMarshalerType) || t.Implements(textMarshalerType) ||
+		reflect.PointerTo(t).Implements(jsonMarshalerType) || reflect.PointerTo(t).Implements(textMarshalerType)
+}
+
+// ParseFields parses a ?fields= value and validates it against the fields the
+// resource allows. An empty value returns a nil fieldset, which selects
+// everything.
+func ParseFields(raw string, allowed Fields) (Fields, error) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return nil, nil
+	}
+
+	selected := make(Fields)
+	for _, path := range strings.Split(raw, ",") {
+		path = strings.TrimSpace(path)
+		if path == "" {
+			continue
+		}
+		if err := selected.add(path, allowed); err != nil {
+			return nil, err
+		}
+	}
+	if len(selected) == 0 {
+		return nil, nil
+	}
+	return selected, nil
+}
+
+func (f Fields) add(path string, allowed Fields) error {
+	segments := strings.Split(path, ".")
+	node, options := f, allowed
+	for i, segment := range segments {
+		if segment == "" {
+			return &FieldError{Path: path, Reason: "empty field name"}
+		}
+		sub, ok := options[segment]
+		if !ok {
+			return &FieldError{Path: path, Reason: fmt.Sprintf("unknown field %q", strings.Join(segments[:i+1], "."))}
+		}
+
+		last := i == len(segments)-1
+		if !last && sub == nil {
+			return &FieldError{Path: path, Reason: fmt.Sprintf("field %q has no subfields", strings.Join(segments[:i+1], "."))}
+		}
+
+		existing, seen := node[segment]
+		switch {
+		case last:
+			// Selecting a field whole overrides any earlier subselection.
+			node[segment] = nil
+			return nil
+		case seen && existing == nil:
+			// The field is already selected whole.
+			return nil
+		case !seen:
+			existing = make(Fields)
+			node[segment] = existing
+		}
+		node, options = existing, sub
+	}
+	return nil
+}
+
+// Apply reduces a response value to the selected fields. Objects keep only
+// the selected keys and lists are reduced element by element. A nil fieldset
+// returns the value unchanged.
+func (f Fields) Apply(v interface{}) (interface{}, error) {
+	if f == nil {
+		return v, nil
+	}
+
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+
+	var generic interface{}
+	if err := decoder.Decode(&generic); err != nil {
+		return nil, err
+	}
+	return f.project(generic), nil
+}
+
+func (f Fields) project(v interface{}) interface{} {
+	switch value := v.(type) {
+	case map[string]interface{}:
+		out := make(map[string]interface{}, len(f))
+		for name, sub := range f {
+			field, ok := value[name]
+			if !ok {
+				continue
+			}
+			if sub == nil {
+				out[name] = field
+			} else {
+				out[name] = sub.project(field)
+			}
+		}
+		return out
+	case []interface{}:
+		out := make([]interface{}, len(value))
+		for i, item := range value {
+			out[i] = f.project(item)
+		}
+		return out
+	default:
+		return v
+	}
+}
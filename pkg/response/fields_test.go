@@ -0,0 +1,161 @@
+package response
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+)
+
+type testOwner struct {
+	ID    string `json:"id"`
+	Name  string `json:"name"`
+	Email string `json:"email"`
+}
+
+type testAudit struct {
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type testResource struct {
+	testAudit
+	ID       string                 `json:"id"`
+	Name     string                 `json:"name"`
+	Owner    *testOwner             `json:"owner,omitempty"`
+	Contacts []testOwner            `json:"contacts"`
+	Custom   map[string]interface{} `json:"custom,omitempty"`
+	Secret   string                 `json:"-"`
+	internal string
+}
+
+func TestFieldsOf(t *testing.T) {
+	fields := FieldsOf(testResource{})
+
+	for _, name := range []string{"id", "name", "owner", "contacts", "custom", "created_at"} {
+		if _, ok := fields[name]; !ok {
+			t.Errorf("expected field %q to be selectable", name)
+		}
+	}
+	for _, name := range []string{"Secret", "-", "internal", "testAudit"} {
+		if _, ok := fields[name]; ok {
+			t.Errorf("expected field %q not to be selectable", name)
+		}
+	}
+	if fields["owner"]["name"] != nil || len(fields["owner"]) != 3 {
+		t.Errorf("expected owner subfields, got %v", fields["owner"])
+	}
+	if len(fields["contacts"]) != 3 {
+		t.Errorf("expected contacts subfields, got %v", fields["contacts"])
+	}
+	if fields["created_at"] != nil || fields["custom"] != nil {
+		t.Error("expected time and map fields to be leaves")
+	}
+}
+
+func TestParseFields(t *testing.T) {
+	allowed := FieldsOf(testResource{})
+
+	t.Run("empty selects everything", func(t *testing.T) {
+		for _, raw := range []string{"", "  ", ",,"} {
+			fields, err := ParseFields(raw, allowed)
+			if err != nil || fields != nil {
+				t.Errorf("ParseFields(%q) = %v, %v; want nil, nil", raw, fields, err)
+			}
+		}
+	})
+
+	t.Run("nested paths", func(t *testing.T) {
+		fields, err := ParseFields("id, name,owner.name,owner.email", allowed)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if _, ok := fields["id"]; !ok {
+			t.Error("expected id to be selected")
+		}
+		if len(fields["owner"]) != 2 {
+			t.Errorf("expected two owner fields, got %v", fields["owner"])
+		}
+	})
+
+	t.Run("whole field wins over subselection", func(t *testing.T) {
+		for _, raw := range []string{"owner.name,owner", "owner,owner.name"} {
+			fields, err := ParseFields(raw, allowed)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if sub, ok := fields["owner"]; !ok || sub != nil {
+				t.Errorf("ParseFields(%q): expected owner to be selected whole, got %v", raw, sub)
+			}
+		}
+	})
+
+	invalid := []string{"unknown", "owner.unknown", "name.first", "owner..name", "Secret", "internal"}
+	for _, raw := range invalid {
+		t.Run("rejects "+raw, func(t *testing.T) {
+			_, err := ParseFields(raw, allowed)
+			var fieldErr *FieldError
+			if !errors.As(err, &fieldErr) {
+				t.Fatalf("expected a FieldError, got %v", err)
+			}
+			if fieldErr.Path != raw {
+				t.Errorf("expected path %q, got %q", raw, fieldErr.Path)
+			}
+		})
+	}
+}
+
+func TestFields_Apply(t *testing.T) {
+	allowed := FieldsOf(testResource{})
+	resources := []*testResource{
+		{
+			ID:       "c1",
+			Name:     "Batik Sdn Bhd",
+			Owner:    &testOwner{ID: "u1", Name: "Aminah", Email: "aminah@example.com"},
+			Contacts: []testOwner{{ID: "p1", Name: "Siti", Email: "siti@example.com"}},
+			Custom:   map[string]interface{}{"region": "Kelantan"},
+		},
+		{ID: "c2", Name: "Songket House"},
+	}
+
+	fields, err := ParseFields("id,owner.name,contacts.email,custom", allowed)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	projected, err := fields.Apply(resources)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	got, _ := json.Marshal(projected)
+	want := `[{"contacts":[{"email":"siti@example.com"}],"custom":{"region":"Kelantan"},"id":"c1","owner":{"name":"Aminah"}},{"contacts":null,"id":"c2"}]`
+	if string(got) != want {
+		t.Errorf("unexpected projection:\n got %s\nwant %s", got, want)
+	}
+}
+
+func TestFields_Apply_NilReturnsValue(t *testing.T) {
+	resource := &testResource{ID: "c1"}
+
+	var fields Fields
+	projected, err := fields.Apply(resource)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if projected != resource {
+		t.Error("expected nil fieldset to return the value unchanged")
+	}
+}
+
+func TestFields_Apply_PreservesNumbers(t *testing.T) {
+	fields := Fields{"amount": nil}
+
+	projected, err := fields.Apply(map[string]interface{}{"amount": int64(9007199254740993), "other": 1})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	got, _ := json.Marshal(projected)
+	if string(got) != `{"amount":9007199254740993}` {
+		t.Errorf("unexpected projection: %s", got)
+	}
+}
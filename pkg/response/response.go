@@ -28,10 +28,11 @@ type ErrorBody struct {
 
 // Meta holds metadata for paginated responses.
 type Meta struct {
-	Page       int   `json:"page,omitempty"`
-	PerPage    int   `json:"per_page,omitempty"`
-	Total      int64 `json:"total,omitempty"`
-	TotalPages int   `json:"total_pages,omitempty"`
+	Page       int    `json:"page,omitempty"`
+	PerPage    int    `json:"per_page,omitempty"`
+	Total      int64  `json:"total,omitempty"`
+	TotalPages int    `json:"total_pages,omitempty"`
+	NextCursor string `json:"next_cursor,omitempty"`
 }
 
 // PaginatedData represents paginated data with items.
@@ -100,6 +101,27 @@ func Paginated(w http.ResponseWriter, items interface{}, page, perPage int, tota
 	json.NewEncoder(w).Encode(response)
 }
 
+// CursorPaginated writes a keyset-paginated response. nextCursor is empty on
+// the last page.
+func CursorPaginated(w http.ResponseWriter, items interface{}, limit int, nextCursor string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+
+	response := Response{
+		Success: true,
+		Data: PaginatedData{
+			Items: items,
+		},
+		Meta: &Meta{
+			PerPage:    limit,
+			NextCursor: nextCursor,
+		},
+		Timestamp: time.Now().UTC(),
+	}
+
+	json.NewEncoder(w).Encode(response)
+}
+
 // Error writes an error response.
 func Error(w http.ResponseWriter, err error) {
 	var statusCode int
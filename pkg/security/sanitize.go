@@ -0,0 +1,161 @@
+package security
+
+import (
+	"bytes"
+	stderrors "errors"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+
+	"github.com/kilang-desa-murni/crm/pkg/config"
+	"github.com/kilang-desa-murni/crm/pkg/errors"
+	"github.com/kilang-desa-murni/crm/pkg/response"
+)
+
+// Sanitize refuses suspicious requests before they reach a handler:
+//
+//   - bodies larger than cfg.MaxBodyBytes, or cfg.FileMaxBodyBytes on file
+//     endpoints, with 413; bodies without a Content-Length are cut off at the
+//     limit
+//   - null bytes in the path, the query or a JSON, form or text body, with 400
+//   - ".." path segments, with 400, also when percent-encoded; on file
+//     endpoints also encoded path separators in a segment and ".." in query
+//     values
+func Sanitize(cfg config.SecurityConfig) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			fileEndpoint := matchesEndpoint(cfg.FileEndpoints, r.URL.Path)
+
+			if err := checkURL(r.URL, fileEndpoint); err != nil {
+				response.Error(w, err)
+				return
+			}
+
+			limit := cfg.MaxBodyBytes
+			if fileEndpoint {
+				limit = cfg.FileMaxBodyBytes
+			}
+			if limit > 0 && r.Body != nil {
+				if r.ContentLength > limit {
+					response.Error(w, errors.ErrPayloadTooLarge(limit))
+					return
+				}
+				r.Body = http.MaxBytesReader(w, r.Body, limit)
+			}
+
+			if hasTextBody(r) {
+				body, err := io.ReadAll(r.Body)
+				if err != nil {
+					var maxBytesErr *http.MaxBytesError
+					if stderrors.As(err, &maxBytesErr) {
+						response.Error(w, errors.ErrPayloadTooLarge(limit))
+						return
+					}
+					response.Error(w, errors.ErrBadRequest("Failed to read request body"))
+					return
+				}
+				if containsNullByte(body) {
+					response.Error(w, errors.ErrBadRequest("Request body contains null bytes"))
+					return
+				}
+				r.Body = io.NopCloser(bytes.NewReader(body))
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// checkURL checks the path and query of a request.
+func checkURL(u *url.URL, fileEndpoint bool) error {
+	for _, segment := range strings.Split(u.EscapedPath(), "/") {
+		decoded, err := url.PathUnescape(segment)
+		if err != nil {
+			return errors.ErrBadRequest("Malformed request path")
+		}
+		if strings.ContainsRune(decoded, 0) {
+			return errors.ErrBadRequest("Request path contains null bytes")
+		}
+		if hasDotDot(decoded) {
+			return errors.ErrBadRequest("Request path contains a path traversal sequence")
+		}
+		if fileEndpoint && strings.ContainsAny(decoded, `/\`) {
+			return errors.ErrBadRequest("Request path contains an encoded path separator")
+		}
+	}
+
+	// Pairs that fail to parse are dropped by handlers too, so only the
+	// parsed ones are checked
+	query, _ := url.ParseQuery(u.RawQuery)
+	for key, values := range query {
+		for _, value := range append(values, key) {
+			if strings.ContainsRune(value, 0) {
+				return errors.ErrBadRequest("Query contains null bytes")
+			}
+			if fileEndpoint && hasDotDot(value) {
+				return errors.ErrBadRequest("Query contains a path traversal sequence")
+			}
+		}
+	}
+	return nil
+}
+
+// hasDotDot reports whether a decoded value has a ".." path element.
+func hasDotDot(value string) bool {
+	if !strings.Contains(value, "..") {
+		return false
+	}
+	for _, element := range strings.FieldsFunc(value, func(r rune) bool { return r == '/' || r == '\\' }) {
+		if element == ".." {
+			return true
+		}
+	}
+	return false
+}
+
+// matchesEndpoint reports whether urlPath is below one of the endpoint
+// prefixes, where a "*" segment matches any one segment.
+func matchesEndpoint(endpoints []string, urlPath string) bool {
+	segments := strings.Split(strings.Trim(urlPath, "/"), "/")
+	for _, endpoint := range endpoints {
+		patterns := strings.Split(strings.Trim(endpoint, "/"), "/")
+		if len(patterns) > len(segments) {
+			continue
+		}
+		matched := true
+		for i, pattern := range patterns {
+			if ok, _ := path.Match(pattern, segments[i]); !ok {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			return true
+		}
+	}
+	return false
+}
+
+// hasTextBody reports whether a request has a JSON, form or text body,
+// which never legitimately holds null bytes. Binary and multipart uploads
+// are left alone.
+func hasTextBody(r *http.Request) bool {
+	if r.Body == nil || r.Body == http.NoBody {
+		return false
+	}
+	mediaType, _, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if err != nil {
+		return false
+	}
+	return mediaType == "application/json" || strings.HasSuffix(mediaType, "+json") ||
+		mediaType == "application/x-www-form-urlencoded" || strings.HasPrefix(mediaType, "text/")
+}
+
+// containsNullByte reports whether body holds a null byte, raw or as a JSON
+// \u0000 escape.
+func containsNullByte(body []byte) bool {
+	return bytes.IndexByte(body, 0) >= 0 || bytes.Contains(bytes.ToLower(body), []byte(`\u0000`))
+}
@@ -0,0 +1,106 @@
+package security
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kilang-desa-murni/crm/pkg/config"
+)
+
+func testConfig() config.SecurityConfig {
+	return config.SecurityConfig{
+		HSTSMaxAge:            365 * 24 * time.Hour,
+		HSTSIncludeSubdomains: true,
+		FrameOptions:          "DENY",
+		ReferrerPolicy:        "strict-origin-when-cross-origin",
+		ContentSecurityPolicy: "default-src 'none'",
+		MaxBodyBytes:          64,
+		FileMaxBodyBytes:      1024,
+		FileEndpoints:         []string{"/api/v1/opportunities/*/attachments"},
+	}
+}
+
+func TestHeaders(t *testing.T) {
+	handler := Headers(testConfig())(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/page" {
+			w.Header().Set("Content-Security-Policy", "default-src 'self'")
+		}
+	}))
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/leads", nil))
+	if rec.Header().Get("X-Content-Type-Options") != "nosniff" || rec.Header().Get("X-Frame-Options") != "DENY" ||
+		rec.Header().Get("Content-Security-Policy") != "default-src 'none'" {
+		t.Errorf("expected the security headers, got %v", rec.Header())
+	}
+	if rec.Header().Get("Strict-Transport-Security") != "" {
+		t.Error("expected no HSTS over plain HTTP")
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/page", nil)
+	req.Header.Set("X-Forwarded-Proto", "https")
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	if got := rec.Header().Get("Strict-Transport-Security"); got != "max-age=31536000; includeSubDomains" {
+		t.Errorf("expected HSTS behind a TLS proxy, got %q", got)
+	}
+	if got := rec.Header().Get("Content-Security-Policy"); got != "default-src 'self'" {
+		t.Errorf("expected the handler's own policy to win, got %q", got)
+	}
+}
+
+func TestSanitize(t *testing.T) {
+	handler := Sanitize(testConfig())(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			w.WriteHeader(http.StatusRequestEntityTooLarge)
+			return
+		}
+		_, _ = w.Write(body)
+	}))
+
+	tests := []struct {
+		name        string
+		method      string
+		target      string
+		contentType string
+		body        string
+		want        int
+	}{
+		{"plain request", http.MethodGet, "/api/v1/leads?search=batik", "", "", http.StatusOK},
+		{"json body", http.MethodPost, "/api/v1/leads", "application/json", `{"name":"Batik"}`, http.StatusOK},
+		{"oversized body", http.MethodPost, "/api/v1/leads", "application/json", strings.Repeat("a", 65), http.StatusRequestEntityTooLarge},
+		{"large upload", http.MethodPost, "/api/v1/opportunities/1/attachments", "application/octet-stream", strings.Repeat("a", 512), http.StatusOK},
+		{"null byte in body", http.MethodPost, "/api/v1/leads", "application/json", "{\"name\":\"a\x00\"}", http.StatusBadRequest},
+		{"escaped null byte in body", http.MethodPost, "/api/v1/leads", "application/json", `{"name":"a\u0000"}`, http.StatusBadRequest},
+		{"null byte in path", http.MethodGet, "/api/v1/leads/a%00", "", "", http.StatusBadRequest},
+		{"null byte in query", http.MethodGet, "/api/v1/leads?search=a%00", "", "", http.StatusBadRequest},
+		{"traversal", http.MethodGet, "/api/v1/leads/%2e%2e/admin", "", "", http.StatusBadRequest},
+		{"encoded traversal", http.MethodGet, "/api/v1/opportunities/1/attachments/..%2f..%2fetc%2fpasswd", "", "", http.StatusBadRequest},
+		{"encoded separator in file endpoint", http.MethodGet, "/api/v1/opportunities/1/attachments/a%2fb", "", "", http.StatusBadRequest},
+		{"traversal in file query", http.MethodGet, "/api/v1/opportunities/1/attachments?name=..%5Cboot.ini", "", "", http.StatusBadRequest},
+		{"dots in file name", http.MethodGet, "/api/v1/opportunities/1/attachments/quote..v2.pdf", "", "", http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, tt.target, strings.NewReader(tt.body))
+			if tt.contentType != "" {
+				req.Header.Set("Content-Type", tt.contentType)
+			}
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			if rec.Code != tt.want {
+				t.Errorf("expected %d, got %d: %s", tt.want, rec.Code, rec.Body.String())
+			}
+			if tt.want == http.StatusOK && rec.Body.String() != tt.body {
+				t.Errorf("expected the handler to read the body %q, got %q", tt.body, rec.Body.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,152 @@
+package serviceauth
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/kilang-desa-murni/crm/pkg/config"
+)
+
+// refreshBefore is how long before it expires a cached token is replaced,
+// so a token is never sent just as it expires.
+const refreshBefore = 30 * time.Second
+
+// Client obtains service tokens from the IAM token endpoint with the
+// service's client credentials. It caches one token per audience and
+// fetches a new one shortly before it expires.
+type Client struct {
+	config config.ServiceAuthConfig
+	http   *http.Client
+
+	mu     sync.Mutex
+	tokens map[string]cachedToken
+}
+
+type cachedToken struct {
+	value     string
+	expiresAt time.Time
+}
+
+// NewClient creates a new client for service, whose name is the client ID
+// unless the configuration sets one.
+func NewClient(cfg config.ServiceAuthConfig, service string) *Client {
+	if cfg.ClientID == "" {
+		cfg.ClientID = service
+	}
+	return &Client{
+		config: cfg,
+		http:   &http.Client{Timeout: 10 * time.Second},
+		tokens: make(map[string]cachedToken),
+	}
+}
+
+// Token returns a token to call audience, fetching one if none is cached or
+// the cached one is about to expire.
+func (c *Client) Token(ctx context.Context, audience string) (string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if cached, ok := c.tokens[audience]; ok && time.Until(cached.expiresAt) > refreshBefore {
+		return cached.value, nil
+	}
+
+	token, err := c.fetch(ctx, audience)
+	if err != nil {
+		return "", err
+	}
+	c.tokens[audience] = cachedToken{
+		value:     token.AccessToken,
+		expiresAt: time.Now().Add(time.Duration(token.ExpiresIn) * time.Second),
+	}
+	return token.AccessToken, nil
+}
+
+// invalidate drops the cached token of audience, such as after the service
+// refused it.
+func (c *Client) invalidate(audience, token string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if cached, ok := c.tokens[audience]; ok && cached.value == token {
+		delete(c.tokens, audience)
+	}
+}
+
+// fetch requests a token from the token endpoint.
+func (c *Client) fetch(ctx context.Context, audience string) (*Token, error) {
+	form := url.Values{
+		"grant_type": {"client_credentials"},
+		"audience":   {audience},
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.config.TokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	req.SetBasicAuth(c.config.ClientID, c.config.ClientSecret)
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to request service token: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		var body struct {
+			Error string `json:"error"`
+		}
+		_ = json.NewDecoder(resp.Body).Decode(&body)
+		return nil, fmt.Errorf("token endpoint returned status %d: %s", resp.StatusCode, body.Error)
+	}
+
+	var token Token
+	if err := json.NewDecoder(resp.Body).Decode(&token); err != nil {
+		return nil, fmt.Errorf("failed to decode service token: %w", err)
+	}
+	return &token, nil
+}
+
+// Transport returns a round tripper sending a token to call audience with
+// every request made through base, http.DefaultTransport when nil. While
+// service auth is disabled it returns base.
+func (c *Client) Transport(audience string, base http.RoundTripper) http.RoundTripper {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	if c == nil || !c.config.Enabled {
+		return base
+	}
+	return &transport{client: c, audience: audience, base: base}
+}
+
+type transport struct {
+	client   *Client
+	audience string
+	base     http.RoundTripper
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	token, err := t.client.Token(req.Context(), t.audience)
+	if err != nil {
+		return nil, err
+	}
+
+	// A round tripper must not change the caller's request
+	req = req.Clone(req.Context())
+	req.Header.Set(Header, token)
+
+	resp, err := t.base.RoundTrip(req)
+	if err == nil && resp.StatusCode == http.StatusUnauthorized {
+		// The token may be signed with a rotated secret; the next request
+		// fetches a new one
+		t.client.invalidate(t.audience, token)
+	}
+	return resp, err
+}
@@ -0,0 +1,156 @@
+package serviceauth
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+
+	"github.com/kilang-desa-murni/crm/pkg/config"
+)
+
+// issuer is the iss claim of service tokens.
+const issuer = "crm-iam"
+
+var (
+	// ErrInvalidClient is returned for unknown clients and wrong secrets.
+	ErrInvalidClient = errors.New("invalid client credentials")
+	// ErrInvalidAudience is returned when a client asks for a token to call
+	// a service it may not call.
+	ErrInvalidAudience = errors.New("audience not allowed for client")
+	// ErrInvalidScope is returned when a client asks for a scope it is not
+	// granted.
+	ErrInvalidScope = errors.New("scope not granted to client")
+)
+
+// Issuer issues service tokens to the clients of the configuration, the
+// client-credentials grant of the IAM service.
+type Issuer struct {
+	config config.ServiceAuthConfig
+}
+
+// NewIssuer creates a new issuer.
+func NewIssuer(cfg config.ServiceAuthConfig) *Issuer {
+	return &Issuer{config: cfg}
+}
+
+// Issue issues a token for a client authenticated by its secret, to call
+// audience. The requested scopes must be granted to the client; requesting
+// none asks for all of them.
+func (i *Issuer) Issue(clientID, clientSecret, audience string, scopes []string, now time.Time) (*Token, error) {
+	client, ok := i.client(clientID)
+	if !ok || client.Secret == "" || subtle.ConstantTimeCompare([]byte(client.Secret), []byte(clientSecret)) != 1 {
+		return nil, ErrInvalidClient
+	}
+	if !contains(client.Audiences, audience) {
+		return nil, ErrInvalidAudience
+	}
+	if len(scopes) == 0 {
+		scopes = client.Scopes
+	}
+	for _, scope := range scopes {
+		if !contains(client.Scopes, scope) {
+			return nil, ErrInvalidScope
+		}
+	}
+
+	claims := &Claims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        uuid.New().String(),
+			Issuer:    issuer,
+			Subject:   client.ID,
+			Audience:  jwt.ClaimStrings{audience},
+			IssuedAt:  jwt.NewNumericDate(now),
+			NotBefore: jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(now.Add(i.config.TokenTTL)),
+		},
+		Scopes:    scopes,
+		TokenType: tokenType,
+	}
+	signed, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(i.config.TokenSecret))
+	if err != nil {
+		return nil, err
+	}
+
+	return &Token{
+		AccessToken: signed,
+		TokenType:   "Bearer",
+		ExpiresIn:   int64(i.config.TokenTTL.Seconds()),
+		Scope:       strings.Join(scopes, " "),
+	}, nil
+}
+
+func (i *Issuer) client(id string) (config.ServiceClientConfig, bool) {
+	for _, client := range i.config.Clients {
+		if client.ID == id {
+			return client, true
+		}
+	}
+	return config.ServiceClientConfig{}, false
+}
+
+// ServeHTTP serves the token endpoint: a client-credentials grant with the
+// client ID and secret in HTTP basic auth, and the audience and the
+// space-separated scopes in the form. Responses and errors follow OAuth 2.0
+// so standard clients can use it.
+func (i *Issuer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		writeTokenError(w, http.StatusMethodNotAllowed, "invalid_request")
+		return
+	}
+	if err := r.ParseForm(); err != nil {
+		writeTokenError(w, http.StatusBadRequest, "invalid_request")
+		return
+	}
+	if r.PostForm.Get("grant_type") != "client_credentials" {
+		writeTokenError(w, http.StatusBadRequest, "unsupported_grant_type")
+		return
+	}
+
+	clientID, clientSecret, ok := r.BasicAuth()
+	if !ok {
+		clientID, clientSecret = r.PostForm.Get("client_id"), r.PostForm.Get("client_secret")
+	}
+
+	token, err := i.Issue(clientID, clientSecret, r.PostForm.Get("audience"), strings.Fields(r.PostForm.Get("scope")), time.Now())
+	switch {
+	case errors.Is(err, ErrInvalidClient):
+		writeTokenError(w, http.StatusUnauthorized, "invalid_client")
+		return
+	case errors.Is(err, ErrInvalidAudience):
+		writeTokenError(w, http.StatusBadRequest, "invalid_target")
+		return
+	case errors.Is(err, ErrInvalidScope):
+		writeTokenError(w, http.StatusBadRequest, "invalid_scope")
+		return
+	case err != nil:
+		writeTokenError(w, http.StatusInternalServerError, "server_error")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	_ = json.NewEncoder(w).Encode(token)
+}
+
+func writeTokenError(w http.ResponseWriter, status int, code string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(map[string]string{"error": code})
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,254 @@
+package serviceauth
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/kilang-desa-murni/crm/pkg/config"
+)
+
+func testConfig() config.ServiceAuthConfig {
+	return config.ServiceAuthConfig{
+		Enabled:     true,
+		ClientID:    "sales-service",
+		TokenSecret: "service-token-secret-of-32-bytes",
+		TokenTTL:    5 * time.Minute,
+		Clients: []config.ServiceClientConfig{{
+			ID:        "sales-service",
+			Secret:    "sales-secret",
+			Audiences: []string{"customer-service"},
+			Scopes:    []string{ScopeCustomersMatch, ScopeTenantsExport},
+		}},
+	}
+}
+
+func TestIssuer_Issue(t *testing.T) {
+	issuer := NewIssuer(testConfig())
+	now := time.Now()
+
+	tests := []struct {
+		name     string
+		secret   string
+		audience string
+		scopes   []string
+		wantErr  error
+	}{
+		{"all scopes", "sales-secret", "customer-service", nil, nil},
+		{"requested scope", "sales-secret", "customer-service", []string{ScopeCustomersMatch}, nil},
+		{"wrong secret", "other-secret", "customer-service", nil, ErrInvalidClient},
+		{"audience not allowed", "sales-secret", "iam-service", nil, ErrInvalidAudience},
+		{"scope not granted", "sales-secret", "customer-service", []string{ScopeTenantsReassign}, ErrInvalidScope},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			token, err := issuer.Issue("sales-service", tt.secret, tt.audience, tt.scopes, now)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Issue() error = %v, want %v", err, tt.wantErr)
+			}
+			if err == nil && token.ExpiresIn != 300 {
+				t.Errorf("ExpiresIn = %d, want 300", token.ExpiresIn)
+			}
+		})
+	}
+
+	if _, err := issuer.Issue("unknown-service", "", "customer-service", nil, now); !errors.Is(err, ErrInvalidClient) {
+		t.Errorf("Issue() for an unknown client error = %v, want %v", err, ErrInvalidClient)
+	}
+}
+
+func TestVerifier_Verify(t *testing.T) {
+	cfg := testConfig()
+	token, err := NewIssuer(cfg).Issue("sales-service", "sales-secret", "customer-service", []string{ScopeCustomersMatch}, time.Now())
+	if err != nil {
+		t.Fatalf("Issue() error = %v", err)
+	}
+
+	claims, err := NewVerifier(cfg, "customer-service").Verify(token.AccessToken)
+	if err != nil {
+		t.Fatalf("Verify() error = %v", err)
+	}
+	if claims.Subject != "sales-service" || !claims.HasScope(ScopeCustomersMatch) || claims.HasScope(ScopeTenantsExport) {
+		t.Errorf("Verify() = %+v", claims)
+	}
+
+	if _, err := NewVerifier(cfg, "notification-service").Verify(token.AccessToken); err == nil {
+		t.Error("Verify() should refuse a token issued for another service")
+	}
+
+	other := cfg
+	other.TokenSecret = "another-token-secret-of-32-bytes"
+	if _, err := NewVerifier(other, "customer-service").Verify(token.AccessToken); err == nil {
+		t.Error("Verify() should refuse a token signed with another secret")
+	}
+
+	expired, err := NewIssuer(cfg).Issue("sales-service", "sales-secret", "customer-service", nil, time.Now().Add(-time.Hour))
+	if err != nil {
+		t.Fatalf("Issue() error = %v", err)
+	}
+	if _, err := NewVerifier(cfg, "customer-service").Verify(expired.AccessToken); err == nil {
+		t.Error("Verify() should refuse an expired token")
+	}
+}
+
+func TestVerifier_Require(t *testing.T) {
+	cfg := testConfig()
+	token, err := NewIssuer(cfg).Issue("sales-service", "sales-secret", "customer-service", []string{ScopeCustomersMatch}, time.Now())
+	if err != nil {
+		t.Fatalf("Issue() error = %v", err)
+	}
+
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if claims, ok := ClaimsFromContext(r.Context()); !ok || claims.Subject != "sales-service" {
+			t.Errorf("ClaimsFromContext() = %v, %v", claims, ok)
+		}
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	tests := []struct {
+		name  string
+		scope string
+		token string
+		want  int
+	}{
+		{"granted scope", ScopeCustomersMatch, token.AccessToken, http.StatusNoContent},
+		{"missing scope", ScopeTenantsExport, token.AccessToken, http.StatusForbidden},
+		{"missing token", ScopeCustomersMatch, "", http.StatusUnauthorized},
+		{"invalid token", ScopeCustomersMatch, "not-a-token", http.StatusUnauthorized},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/internal/tenants/t1/customers/matches", nil)
+			if tt.token != "" {
+				req.Header.Set(Header, tt.token)
+			}
+			rec := httptest.NewRecorder()
+			NewVerifier(cfg, "customer-service").Require(tt.scope)(next).ServeHTTP(rec, req)
+			if rec.Code != tt.want {
+				t.Errorf("status = %d, want %d", rec.Code, tt.want)
+			}
+		})
+	}
+
+	disabled := cfg
+	disabled.Enabled = false
+	rec := httptest.NewRecorder()
+	NewVerifier(disabled, "customer-service").Require(ScopeTenantsExport)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	})).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/internal/tenants/t1/export/customers", nil))
+	if rec.Code != http.StatusNoContent {
+		t.Errorf("status with service auth disabled = %d, want %d", rec.Code, http.StatusNoContent)
+	}
+}
+
+func TestIssuer_ServeHTTP(t *testing.T) {
+	issuer := NewIssuer(testConfig())
+
+	form := url.Values{"grant_type": {"client_credentials"}, "audience": {"customer-service"}}
+	req := httptest.NewRequest(http.MethodPost, "/internal/auth/token", strings.NewReader(form.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.SetBasicAuth("sales-service", "sales-secret")
+	rec := httptest.NewRecorder()
+	issuer.ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), "access_token") {
+		t.Errorf("ServeHTTP() = %d %s, want a token", rec.Code, rec.Body.String())
+	}
+
+	req = httptest.NewRequest(http.MethodPost, "/internal/auth/token", strings.NewReader(form.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.SetBasicAuth("sales-service", "wrong-secret")
+	rec = httptest.NewRecorder()
+	issuer.ServeHTTP(rec, req)
+	if rec.Code != http.StatusUnauthorized || !strings.Contains(rec.Body.String(), "invalid_client") {
+		t.Errorf("ServeHTTP() with a wrong secret = %d %s, want invalid_client", rec.Code, rec.Body.String())
+	}
+}
+
+func TestClient_Transport(t *testing.T) {
+	cfg := testConfig()
+	cfg.ClientID = ""
+	cfg.ClientSecret = "sales-secret"
+
+	var issued atomic.Int32
+	issuer := NewIssuer(cfg)
+	tokenServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		issued.Add(1)
+		issuer.ServeHTTP(w, r)
+	}))
+	defer tokenServer.Close()
+	cfg.TokenURL = tokenServer.URL
+
+	verifier := NewVerifier(cfg, "customer-service")
+	var reject atomic.Bool
+	service := httptest.NewServer(verifier.Require(ScopeCustomersMatch)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if reject.Load() {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	})))
+	defer service.Close()
+
+	client := &http.Client{Transport: NewClient(cfg, "sales-service").Transport("customer-service", nil)}
+	get := func() int {
+		resp, err := client.Get(service.URL)
+		if err != nil {
+			t.Fatalf("Get() error = %v", err)
+		}
+		resp.Body.Close()
+		return resp.StatusCode
+	}
+
+	for i := 0; i < 2; i++ {
+		if status := get(); status != http.StatusNoContent {
+			t.Fatalf("status = %d, want %d", status, http.StatusNoContent)
+		}
+	}
+	if n := issued.Load(); n != 1 {
+		t.Errorf("tokens issued = %d, want 1 reused token", n)
+	}
+
+	// A refused token is replaced on the next request
+	reject.Store(true)
+	get()
+	reject.Store(false)
+	get()
+	if n := issued.Load(); n != 2 {
+		t.Errorf("tokens issued = %d, want 2 after a refused token", n)
+	}
+}
+
+func TestClient_Token_Refresh(t *testing.T) {
+	cfg := testConfig()
+	cfg.ClientSecret = "sales-secret"
+	cfg.TokenTTL = 20 * time.Second // within refreshBefore, so never reused
+
+	var issued atomic.Int32
+	issuer := NewIssuer(cfg)
+	tokenServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		issued.Add(1)
+		issuer.ServeHTTP(w, r)
+	}))
+	defer tokenServer.Close()
+	cfg.TokenURL = tokenServer.URL
+
+	client := NewClient(cfg, "sales-service")
+	for i := 0; i < 2; i++ {
+		if _, err := client.Token(context.Background(), "customer-service"); err != nil {
+			t.Fatalf("Token() error = %v", err)
+		}
+	}
+	if n := issued.Load(); n != 2 {
+		t.Errorf("tokens issued = %d, want 2 as tokens about to expire are refreshed", n)
+	}
+
+	if _, err := client.Token(context.Background(), "iam-service"); err == nil {
+		t.Error("Token() should fail for an audience the client may not call")
+	}
+}
@@ -0,0 +1,221 @@
+// Package slo tracks service level objectives of route groups: it counts
+// the requests that meet and miss each objective, computes how fast the
+// error budget burns, alerts when it burns too fast and generates the
+// matching Prometheus alert rules.
+package slo
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+// Objective types.
+const (
+	// TypeAvailability counts requests answered without a server error.
+	TypeAvailability = "availability"
+	// TypeLatency counts requests answered within the threshold.
+	TypeLatency = "latency"
+)
+
+// ============================================================================
+// Configuration
+// ============================================================================
+
+// Config is the YAML-driven SLO policy.
+type Config struct {
+	// Window is the period the error budget is spent over.
+	Window time.Duration `mapstructure:"window"`
+	// EvaluationInterval is how often burn rates are checked for alerts.
+	EvaluationInterval time.Duration `mapstructure:"evaluation_interval"`
+	// MinRequests is how many requests the long window of an alert rule
+	// needs before it fires, so a single failure at night does not page.
+	MinRequests uint64 `mapstructure:"min_requests"`
+	// Recipients get an email when an alert fires or resolves.
+	Recipients []string `mapstructure:"recipients"`
+	// Alerts are the multiwindow burn rate rules applied to every objective.
+	Alerts []AlertRule `mapstructure:"alerts"`
+	// Objectives are matched by path prefix; a request counts towards every
+	// objective it matches.
+	Objectives []Objective `mapstructure:"objectives"`
+}
+
+// AlertRule fires when the error budget burns at BurnRate times the
+// sustainable rate or faster over both windows: the long window makes sure
+// enough budget is lost to matter and the short one that it is still being
+// lost, so the alert resolves soon after the problem does.
+type AlertRule struct {
+	Severity    string        `mapstructure:"severity"`
+	LongWindow  time.Duration `mapstructure:"long_window"`
+	ShortWindow time.Duration `mapstructure:"short_window"`
+	BurnRate    float64       `mapstructure:"burn_rate"`
+}
+
+// Objective is the share of requests of a route group that must be good.
+type Objective struct {
+	Name        string `mapstructure:"name"`
+	Description string `mapstructure:"description"`
+	// Paths are the path prefixes of the route group.
+	Paths []string `mapstructure:"paths"`
+	// Methods limit the objective to some methods; empty means all.
+	Methods []string `mapstructure:"methods"`
+	// Type is "availability" or "latency".
+	Type string `mapstructure:"type"`
+	// Threshold is the slowest good response of a latency objective.
+	Threshold time.Duration `mapstructure:"threshold"`
+	// Target is the percentage of good requests, e.g. 99.9.
+	Target float64 `mapstructure:"target"`
+}
+
+// DefaultAlertRules page when 2% of a 30 day budget is spent in an hour and
+// open a ticket when 5% is spent in six hours.
+func DefaultAlertRules() []AlertRule {
+	return []AlertRule{
+		{Severity: "critical", LongWindow: time.Hour, ShortWindow: 5 * time.Minute, BurnRate: 14.4},
+		{Severity: "warning", LongWindow: 6 * time.Hour, ShortWindow: 30 * time.Minute, BurnRate: 6},
+	}
+}
+
+func (c *Config) setDefaults() {
+	if c.Window <= 0 {
+		c.Window = 30 * 24 * time.Hour
+	}
+	if c.EvaluationInterval <= 0 {
+		c.EvaluationInterval = time.Minute
+	}
+	if len(c.Alerts) == 0 {
+		c.Alerts = DefaultAlertRules()
+	}
+	for i := range c.Objectives {
+		if c.Objectives[i].Type == "" {
+			c.Objectives[i].Type = TypeAvailability
+		}
+		for j, m := range c.Objectives[i].Methods {
+			c.Objectives[i].Methods[j] = strings.ToUpper(m)
+		}
+	}
+}
+
+// Validate checks the policy.
+func (c *Config) Validate() error {
+	if c.Window < time.Hour {
+		return fmt.Errorf("slo window %s is shorter than an hour", c.Window)
+	}
+	for _, a := range c.Alerts {
+		if a.Severity == "" {
+			return errors.New("slo alert rule without severity")
+		}
+		if a.ShortWindow < time.Minute || a.LongWindow <= a.ShortWindow || a.LongWindow > c.Window {
+			return fmt.Errorf("slo alert rule %s: windows must satisfy 1m <= short < long <= window", a.Severity)
+		}
+		if a.BurnRate <= 0 {
+			return fmt.Errorf("slo alert rule %s: burn_rate must be positive", a.Severity)
+		}
+	}
+
+	names := map[string]bool{}
+	for _, o := range c.Objectives {
+		if o.Name == "" {
+			return errors.New("slo objective without name")
+		}
+		if names[o.Name] {
+			return fmt.Errorf("duplicate slo objective %q", o.Name)
+		}
+		names[o.Name] = true
+		if len(o.Paths) == 0 {
+			return fmt.Errorf("slo objective %q has no paths", o.Name)
+		}
+		switch o.Type {
+		case TypeAvailability:
+		case TypeLatency:
+			if o.Threshold <= 0 {
+				return fmt.Errorf("slo objective %q needs a latency threshold", o.Name)
+			}
+		default:
+			return fmt.Errorf("slo objective %q has unknown type %q", o.Name, o.Type)
+		}
+		if o.Target <= 0 || o.Target >= 100 {
+			return fmt.Errorf("slo objective %q: target must be between 0 and 100 percent", o.Name)
+		}
+	}
+	return nil
+}
+
+// LoadConfig reads the policy from a YAML file. A missing file is a policy
+// without objectives.
+func LoadConfig(path string) (*Config, error) {
+	cfg := &Config{}
+	if _, err := os.Stat(path); errors.Is(err, os.ErrNotExist) {
+		cfg.setDefaults()
+		return cfg, nil
+	}
+
+	v := viper.New()
+	v.SetConfigFile(path)
+	v.SetConfigType("yaml")
+	if err := v.ReadInConfig(); err != nil {
+		return nil, fmt.Errorf("error reading slo config: %w", err)
+	}
+	if err := v.Unmarshal(cfg); err != nil {
+		return nil, fmt.Errorf("error parsing slo config: %w", err)
+	}
+	cfg.setDefaults()
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// matches reports whether a request belongs to the route group.
+func (o *Objective) matches(method, path string) bool {
+	if len(o.Methods) > 0 {
+		found := false
+		for _, m := range o.Methods {
+			if m == method {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	for _, prefix := range o.Paths {
+		if strings.HasPrefix(path, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// good reports whether a request met the objective.
+func (o *Objective) good(status int, duration time.Duration) bool {
+	if o.Type == TypeLatency {
+		return duration <= o.Threshold
+	}
+	return status < 500
+}
+
+// budget is the share of requests allowed to miss the objective.
+func (o *Objective) budget() float64 {
+	return (100 - o.Target) / 100
+}
+
+// formatWindow formats a window the way Prometheus writes ranges, e.g. 5m,
+// 1h or 30d.
+func formatWindow(d time.Duration) string {
+	switch {
+	case d%(24*time.Hour) == 0:
+		return fmt.Sprintf("%dd", d/(24*time.Hour))
+	case d%time.Hour == 0:
+		return fmt.Sprintf("%dh", d/time.Hour)
+	case d%time.Minute == 0:
+		return fmt.Sprintf("%dm", d/time.Minute)
+	default:
+		return fmt.Sprintf("%ds", d/time.Second)
+	}
+}
@@ -0,0 +1,31 @@
+package slo
+
+import (
+	"net/http"
+
+	"github.com/kilang-desa-murni/crm/pkg/response"
+)
+
+// ============================================================================
+// Admin API
+// ============================================================================
+
+// NewAdminHandler returns the SLO API, to be mounted under a prefix behind
+// admin authentication:
+//
+//	GET /         state of every objective
+//	GET /rules    the Prometheus alert rules of the policy
+func NewAdminHandler(cfg *Config, tracker *Tracker) http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /{$}", func(w http.ResponseWriter, r *http.Request) {
+		response.OK(w, map[string]interface{}{
+			"window":     formatWindow(cfg.Window),
+			"objectives": tracker.Status(),
+		})
+	})
+	mux.HandleFunc("GET /rules", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/yaml")
+		w.Write([]byte(PrometheusRules(cfg)))
+	})
+	return mux
+}
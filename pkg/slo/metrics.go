@@ -0,0 +1,121 @@
+package slo
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// ============================================================================
+// Metrics
+// ============================================================================
+
+// WriteMetrics writes the SLO series in the Prometheus text format:
+// counters of requests and errors per objective, which the generated alert
+// rules read, and the burn rates, remaining budget and firing alerts this
+// instance computed.
+func (t *Tracker) WriteMetrics(w io.Writer) {
+	if len(t.objectives) == 0 {
+		return
+	}
+	statuses := t.Status()
+
+	fmt.Fprintf(w, "# HELP slo_objective_target Share of requests that must meet the objective.\n# TYPE slo_objective_target gauge\n")
+	for _, s := range statuses {
+		fmt.Fprintf(w, "slo_objective_target{objective=%q,type=%q} %g\n", s.Name, s.Type, s.Target/100)
+	}
+
+	fmt.Fprintf(w, "# HELP slo_requests_total Requests counted towards the objective.\n# TYPE slo_requests_total counter\n")
+	for _, o := range t.objectives {
+		o.mu.Lock()
+		fmt.Fprintf(w, "slo_requests_total{objective=%q} %d\n", o.Name, o.total)
+		o.mu.Unlock()
+	}
+
+	fmt.Fprintf(w, "# HELP slo_errors_total Requests that missed the objective.\n# TYPE slo_errors_total counter\n")
+	for _, o := range t.objectives {
+		o.mu.Lock()
+		fmt.Fprintf(w, "slo_errors_total{objective=%q} %d\n", o.Name, o.bad)
+		o.mu.Unlock()
+	}
+
+	fmt.Fprintf(w, "# HELP slo_burn_rate Error budget burn rate over the window; 1 spends the budget exactly over the SLO window.\n# TYPE slo_burn_rate gauge\n")
+	for _, s := range statuses {
+		for _, window := range t.windows() {
+			label := formatWindow(window)
+			fmt.Fprintf(w, "slo_burn_rate{objective=%q,window=%q} %g\n", s.Name, label, s.BurnRates[label])
+		}
+	}
+
+	fmt.Fprintf(w, "# HELP slo_error_budget_remaining Share of the error budget left in the SLO window.\n# TYPE slo_error_budget_remaining gauge\n")
+	for _, s := range statuses {
+		fmt.Fprintf(w, "slo_error_budget_remaining{objective=%q} %g\n", s.Name, s.ErrorBudgetRemaining)
+	}
+
+	fmt.Fprintf(w, "# HELP slo_alert_firing Whether the burn rate alert of the severity is firing.\n# TYPE slo_alert_firing gauge\n")
+	for _, s := range statuses {
+		firing := map[string]bool{}
+		for _, severity := range s.Firing {
+			firing[severity] = true
+		}
+		for _, rule := range t.cfg.Alerts {
+			value := 0
+			if firing[rule.Severity] {
+				value = 1
+			}
+			fmt.Fprintf(w, "slo_alert_firing{objective=%q,severity=%q} %d\n", s.Name, rule.Severity, value)
+		}
+	}
+}
+
+// ============================================================================
+// Alert Rules
+// ============================================================================
+
+// PrometheusRules generates the Prometheus rule group alerting on the
+// policy's burn rates from the slo_requests_total and slo_errors_total
+// series of all gateway instances.
+func PrometheusRules(cfg *Config) string {
+	var b strings.Builder
+	b.WriteString("groups:\n")
+	b.WriteString("  - name: slo-burn-rate\n")
+	b.WriteString("    rules:\n")
+	if len(cfg.Objectives) == 0 {
+		b.WriteString("      []\n")
+		return b.String()
+	}
+
+	for _, o := range cfg.Objectives {
+		for _, rule := range cfg.Alerts {
+			threshold := rule.BurnRate * o.budget()
+			long, short := formatWindow(rule.LongWindow), formatWindow(rule.ShortWindow)
+
+			fmt.Fprintf(&b, "      - alert: SLOErrorBudgetBurn\n")
+			fmt.Fprintf(&b, "        expr: |\n")
+			fmt.Fprintf(&b, "          %s >= %.6g\n", errorRatio(o.Name, long), threshold)
+			fmt.Fprintf(&b, "          and\n")
+			fmt.Fprintf(&b, "          %s >= %.6g\n", errorRatio(o.Name, short), threshold)
+			if cfg.MinRequests > 0 {
+				fmt.Fprintf(&b, "          and\n")
+				fmt.Fprintf(&b, "          sum(increase(slo_requests_total{objective=%q}[%s])) >= %d\n", o.Name, long, cfg.MinRequests)
+			}
+			fmt.Fprintf(&b, "        labels:\n")
+			fmt.Fprintf(&b, "          severity: %s\n", rule.Severity)
+			fmt.Fprintf(&b, "          category: slo\n")
+			fmt.Fprintf(&b, "          objective: %s\n", o.Name)
+			fmt.Fprintf(&b, "        annotations:\n")
+			fmt.Fprintf(&b, "          summary: %q\n", fmt.Sprintf("Error budget of %s is burning too fast", o.Name))
+			fmt.Fprintf(&b, "          description: %q\n", fmt.Sprintf(
+				"%s (%g%% %s) is burning its error budget at %gx or more over the last %s and %s.",
+				o.Name, o.Target, o.Type, rule.BurnRate, long, short))
+		}
+	}
+	return b.String()
+}
+
+// errorRatio is the PromQL share of requests that missed the objective over
+// the window.
+func errorRatio(objective, window string) string {
+	return fmt.Sprintf("sum(rate(slo_errors_total{objective=%q}[%s])) / sum(rate(slo_requests_total{objective=%q}[%s]))",
+		objective, window, objective, window)
+}
@@ -0,0 +1,183 @@
+package slo
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func testConfig() *Config {
+	cfg := &Config{
+		MinRequests: 10,
+		Objectives: []Objective{
+			{Name: "api-availability", Paths: []string{"/api/"}, Target: 99},
+			{Name: "leads-latency", Paths: []string{"/api/v1/leads"}, Methods: []string{"get"}, Type: TypeLatency, Threshold: 300 * time.Millisecond, Target: 90},
+		},
+	}
+	cfg.setDefaults()
+	return cfg
+}
+
+// newTestTracker returns a tracker whose clock is advanced by the returned
+// function.
+func newTestTracker(t *testing.T, cfg *Config) (*Tracker, func(time.Duration)) {
+	t.Helper()
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("validate: %v", err)
+	}
+	now := time.Date(2026, 10, 17, 8, 0, 0, 0, time.UTC)
+	tracker := NewTracker(cfg)
+	tracker.now = func() time.Time { return now }
+	return tracker, func(d time.Duration) { now = now.Add(d) }
+}
+
+func TestConfig_Validate(t *testing.T) {
+	tests := []struct {
+		name   string
+		mutate func(*Config)
+	}{
+		{"latency without threshold", func(c *Config) { c.Objectives[1].Threshold = 0 }},
+		{"target of 100", func(c *Config) { c.Objectives[0].Target = 100 }},
+		{"duplicate name", func(c *Config) { c.Objectives[1].Name = c.Objectives[0].Name }},
+		{"no paths", func(c *Config) { c.Objectives[0].Paths = nil }},
+		{"short window longer than long", func(c *Config) { c.Alerts[0].ShortWindow = 2 * time.Hour }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := testConfig()
+			tt.mutate(cfg)
+			if err := cfg.Validate(); err == nil {
+				t.Error("expected an error")
+			}
+		})
+	}
+}
+
+func TestTracker_BurnRatesAndBudget(t *testing.T) {
+	tracker, advance := newTestTracker(t, testConfig())
+
+	// 2% errors spend the 1% budget twice as fast as sustainable
+	for i := 0; i < 100; i++ {
+		status := http.StatusOK
+		if i < 2 {
+			status = http.StatusBadGateway
+		}
+		tracker.Record(http.MethodPost, "/api/v1/customers", status, time.Millisecond)
+	}
+	// Slow lead reads miss the latency objective; writes are not counted
+	tracker.Record(http.MethodGet, "/api/v1/leads/1", http.StatusOK, time.Second)
+	tracker.Record(http.MethodPost, "/api/v1/leads", http.StatusOK, time.Second)
+	tracker.Record(http.MethodGet, "/health", http.StatusInternalServerError, 0)
+
+	advance(10 * time.Minute)
+	statuses := tracker.Status()
+
+	api := statuses[0]
+	if api.Requests != 102 || api.Errors != 2 {
+		t.Fatalf("unexpected counts %+v", api)
+	}
+	if got := api.BurnRates["1h"]; got < 1.9 || got > 2 {
+		t.Errorf("expected a burn rate of about 2 over 1h, got %g", got)
+	}
+	if got := api.BurnRates["5m"]; got != 0 {
+		t.Errorf("expected no requests in the last 5m, got burn rate %g", got)
+	}
+	if got := api.ErrorBudgetRemaining; got > -0.9 || got < -1 {
+		t.Errorf("expected the budget overspent, got %g", got)
+	}
+
+	leads := statuses[1]
+	if leads.Requests != 1 || leads.Errors != 1 || leads.Threshold != "300ms" {
+		t.Errorf("unexpected latency objective %+v", leads)
+	}
+}
+
+func TestTracker_EvaluateFiresAndResolves(t *testing.T) {
+	tracker, advance := newTestTracker(t, testConfig())
+
+	record := func(n, failures int) {
+		for i := 0; i < n; i++ {
+			status := http.StatusOK
+			if i < failures {
+				status = http.StatusServiceUnavailable
+			}
+			tracker.Record(http.MethodGet, "/api/v1/deals", status, time.Millisecond)
+		}
+	}
+
+	// Below the minimum requests nothing fires
+	record(5, 5)
+	if alerts := tracker.Evaluate(); len(alerts) != 0 {
+		t.Fatalf("expected no alerts below min requests, got %+v", alerts)
+	}
+
+	// 20% errors burn at 20x: both rules fire
+	record(95, 15)
+	alerts := tracker.Evaluate()
+	if len(alerts) != 2 || alerts[0].Status != AlertFiring || alerts[0].Severity != "critical" || alerts[1].Severity != "warning" {
+		t.Fatalf("expected critical and warning alerts, got %+v", alerts)
+	}
+	if alerts[0].LongWindow != "1h" || alerts[0].ShortWindow != "5m" || alerts[0].BurnRate < 19.99 || alerts[0].BurnRate > 20.01 {
+		t.Errorf("unexpected alert %+v", alerts[0])
+	}
+	if alerts := tracker.Evaluate(); len(alerts) != 0 {
+		t.Fatalf("expected no change, got %+v", alerts)
+	}
+
+	// Once the errors stop the short windows recover and the alerts resolve,
+	// although the long windows still burn fast
+	advance(31 * time.Minute)
+	record(10, 0)
+	alerts = tracker.Evaluate()
+	if len(alerts) != 2 || alerts[0].Status != AlertResolved || alerts[1].Status != AlertResolved {
+		t.Fatalf("expected both alerts resolved, got %+v", alerts)
+	}
+}
+
+func TestTracker_Middleware(t *testing.T) {
+	tracker, _ := newTestTracker(t, testConfig())
+	handler := tracker.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/api/v1/customers", nil))
+
+	if s := tracker.Status()[0]; s.Requests != 1 || s.Errors != 1 {
+		t.Errorf("expected the server error recorded, got %+v", s)
+	}
+}
+
+func TestWriteMetricsAndRules(t *testing.T) {
+	cfg := testConfig()
+	tracker, _ := newTestTracker(t, cfg)
+	tracker.Record(http.MethodGet, "/api/v1/customers", http.StatusInternalServerError, 0)
+
+	var buf bytes.Buffer
+	tracker.WriteMetrics(&buf)
+	metrics := buf.String()
+	for _, line := range []string{
+		`slo_requests_total{objective="api-availability"} 1`,
+		`slo_errors_total{objective="api-availability"} 1`,
+		`slo_burn_rate{objective="api-availability",window="1h"} 100`,
+		`slo_objective_target{objective="leads-latency",type="latency"} 0.9`,
+		`slo_alert_firing{objective="api-availability",severity="critical"} 0`,
+	} {
+		if !strings.Contains(metrics, line+"\n") {
+			t.Errorf("expected %s in:\n%s", line, metrics)
+		}
+	}
+
+	rules := PrometheusRules(cfg)
+	for _, s := range []string{
+		`sum(rate(slo_errors_total{objective="api-availability"}[1h])) / sum(rate(slo_requests_total{objective="api-availability"}[1h])) >= 0.144`,
+		`sum(increase(slo_requests_total{objective="leads-latency"}[6h])) >= 10`,
+		"severity: warning",
+	} {
+		if !strings.Contains(rules, s) {
+			t.Errorf("expected %s in:\n%s", s, rules)
+		}
+	}
+}
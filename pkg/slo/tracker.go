@@ -0,0 +1,349 @@
+package slo
+
+import (
+	"context"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+)
+
+// ============================================================================
+// Request Counts
+// ============================================================================
+
+// bucket counts the requests of one slot of a series.
+type bucket struct {
+	slot  int64
+	total uint64
+	bad   uint64
+}
+
+// series is a ring of fixed width buckets covering a span of time.
+type series struct {
+	width   time.Duration
+	buckets []bucket
+}
+
+func newSeries(width, span time.Duration) series {
+	n := int((span + width - 1) / width)
+	return series{width: width, buckets: make([]bucket, n+1)}
+}
+
+func (s *series) slot(t time.Time) int64 {
+	return t.UnixNano() / int64(s.width)
+}
+
+func (s *series) add(t time.Time, bad bool) {
+	slot := s.slot(t)
+	b := &s.buckets[slot%int64(len(s.buckets))]
+	if b.slot != slot {
+		*b = bucket{slot: slot}
+	}
+	b.total++
+	if bad {
+		b.bad++
+	}
+}
+
+// sum counts the requests of the window ending at now, including the
+// current partial bucket.
+func (s *series) sum(now time.Time, window time.Duration) (total, bad uint64) {
+	last := s.slot(now)
+	first := last - int64(window/s.width) + 1
+	for _, b := range s.buckets {
+		if b.slot >= first && b.slot <= last {
+			total += b.total
+			bad += b.bad
+		}
+	}
+	return total, bad
+}
+
+// ============================================================================
+// Tracker
+// ============================================================================
+
+// Tracker counts requests per objective in minute buckets, for the alert
+// windows, and hour buckets, for the error budget window. Counts live in
+// memory, so each gateway instance reports its own share and the budget
+// starts afresh on restart; the Prometheus rules sum the instances.
+type Tracker struct {
+	cfg        Config
+	objectives []*tracked
+	now        func() time.Time
+}
+
+type tracked struct {
+	Objective
+
+	mu      sync.Mutex
+	total   uint64
+	bad     uint64
+	minutes series
+	hours   series
+	firing  map[string]bool
+}
+
+// NewTracker creates a tracker for a validated policy.
+func NewTracker(cfg *Config) *Tracker {
+	span := time.Duration(0)
+	for _, a := range cfg.Alerts {
+		span = max(span, a.LongWindow)
+	}
+
+	t := &Tracker{cfg: *cfg, now: time.Now}
+	for _, o := range cfg.Objectives {
+		t.objectives = append(t.objectives, &tracked{
+			Objective: o,
+			minutes:   newSeries(time.Minute, span),
+			hours:     newSeries(time.Hour, cfg.Window),
+			firing:    map[string]bool{},
+		})
+	}
+	return t
+}
+
+// Record counts a request towards the objectives it matches.
+func (t *Tracker) Record(method, path string, status int, duration time.Duration) {
+	now := t.now()
+	for _, o := range t.objectives {
+		if !o.matches(method, path) {
+			continue
+		}
+		bad := !o.good(status, duration)
+
+		o.mu.Lock()
+		o.total++
+		if bad {
+			o.bad++
+		}
+		o.minutes.add(now, bad)
+		o.hours.add(now, bad)
+		o.mu.Unlock()
+	}
+}
+
+// Middleware records the outcome of every request.
+func (t *Tracker) Middleware(next http.Handler) http.Handler {
+	if len(t.objectives) == 0 {
+		return next
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		sw := &statusWriter{ResponseWriter: w, status: http.StatusOK}
+		next.ServeHTTP(sw, r)
+		t.Record(r.Method, r.URL.Path, sw.status, time.Since(start))
+	})
+}
+
+// statusWriter captures the status code of a response.
+type statusWriter struct {
+	http.ResponseWriter
+	status int
+}
+
+func (w *statusWriter) WriteHeader(code int) {
+	w.status = code
+	w.ResponseWriter.WriteHeader(code)
+}
+
+// Unwrap lets http.ResponseController flush streamed responses.
+func (w *statusWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// count returns the requests and errors of the window; o.mu must be held.
+func (o *tracked) count(now time.Time, window time.Duration) (total, bad uint64) {
+	if window <= time.Duration(len(o.minutes.buckets)-1)*o.minutes.width {
+		return o.minutes.sum(now, window)
+	}
+	return o.hours.sum(now, window)
+}
+
+// burnRate is how many times faster than sustainable the budget burned over
+// the window; o.mu must be held.
+func (o *tracked) burnRate(now time.Time, window time.Duration) (float64, uint64) {
+	total, bad := o.count(now, window)
+	if total == 0 {
+		return 0, 0
+	}
+	return float64(bad) / float64(total) / o.budget(), total
+}
+
+// budgetRemaining is the share of the error budget left after bad of total
+// requests missed the objective.
+func (o *tracked) budgetRemaining(total, bad uint64) float64 {
+	if total == 0 {
+		return 1
+	}
+	return 1 - float64(bad)/float64(total)/o.budget()
+}
+
+// ============================================================================
+// Status
+// ============================================================================
+
+// ObjectiveStatus is the state of an objective.
+type ObjectiveStatus struct {
+	Name        string  `json:"name"`
+	Description string  `json:"description,omitempty"`
+	Type        string  `json:"type"`
+	Threshold   string  `json:"threshold,omitempty"`
+	Target      float64 `json:"target"`
+	// Requests and Errors are counted over the budget window.
+	Requests uint64 `json:"requests"`
+	Errors   uint64 `json:"errors"`
+	// ErrorBudgetRemaining is the share of the budget left; it goes below
+	// zero once the objective is missed.
+	ErrorBudgetRemaining float64 `json:"error_budget_remaining"`
+	// BurnRates are keyed by the alert windows, e.g. "5m" or "1h".
+	BurnRates map[string]float64 `json:"burn_rates"`
+	// Firing are the severities of the alerts firing.
+	Firing []string `json:"firing"`
+}
+
+// Status returns the state of every objective.
+func (t *Tracker) Status() []ObjectiveStatus {
+	now := t.now()
+	statuses := make([]ObjectiveStatus, 0, len(t.objectives))
+	for _, o := range t.objectives {
+		o.mu.Lock()
+		s := ObjectiveStatus{
+			Name:        o.Name,
+			Description: o.Description,
+			Type:        o.Type,
+			Target:      o.Target,
+			BurnRates:   map[string]float64{},
+			Firing:      []string{},
+		}
+		if o.Type == TypeLatency {
+			s.Threshold = o.Threshold.String()
+		}
+		s.Requests, s.Errors = o.hours.sum(now, t.cfg.Window)
+		s.ErrorBudgetRemaining = o.budgetRemaining(s.Requests, s.Errors)
+		for _, w := range t.windows() {
+			s.BurnRates[formatWindow(w)], _ = o.burnRate(now, w)
+		}
+		for severity, firing := range o.firing {
+			if firing {
+				s.Firing = append(s.Firing, severity)
+			}
+		}
+		sort.Strings(s.Firing)
+		o.mu.Unlock()
+		statuses = append(statuses, s)
+	}
+	return statuses
+}
+
+// windows returns the distinct alert windows, shortest first.
+func (t *Tracker) windows() []time.Duration {
+	seen := map[time.Duration]bool{}
+	var windows []time.Duration
+	for _, a := range t.cfg.Alerts {
+		for _, w := range []time.Duration{a.ShortWindow, a.LongWindow} {
+			if !seen[w] {
+				seen[w] = true
+				windows = append(windows, w)
+			}
+		}
+	}
+	sort.Slice(windows, func(i, j int) bool { return windows[i] < windows[j] })
+	return windows
+}
+
+// ============================================================================
+// Alerts
+// ============================================================================
+
+// Alert statuses.
+const (
+	AlertFiring   = "firing"
+	AlertResolved = "resolved"
+)
+
+// Alert reports that an alert rule started or stopped firing for an
+// objective.
+type Alert struct {
+	Objective            string    `json:"objective"`
+	Description          string    `json:"description,omitempty"`
+	Severity             string    `json:"severity"`
+	Status               string    `json:"status"`
+	Target               float64   `json:"target"`
+	BurnRate             float64   `json:"burn_rate"`
+	ShortBurnRate        float64   `json:"short_burn_rate"`
+	Threshold            float64   `json:"threshold"`
+	LongWindow           string    `json:"long_window"`
+	ShortWindow          string    `json:"short_window"`
+	ErrorBudgetRemaining float64   `json:"error_budget_remaining"`
+	At                   time.Time `json:"at"`
+}
+
+// Notifier delivers alerts.
+type Notifier func(ctx context.Context, alert Alert) error
+
+// Evaluate checks every alert rule of every objective and returns the
+// alerts that started or stopped firing since the last evaluation.
+func (t *Tracker) Evaluate() []Alert {
+	now := t.now()
+	var alerts []Alert
+	for _, o := range t.objectives {
+		o.mu.Lock()
+		for _, rule := range t.cfg.Alerts {
+			long, requests := o.burnRate(now, rule.LongWindow)
+			short, _ := o.burnRate(now, rule.ShortWindow)
+			firing := requests >= t.cfg.MinRequests && long >= rule.BurnRate && short >= rule.BurnRate
+			if firing == o.firing[rule.Severity] {
+				continue
+			}
+			o.firing[rule.Severity] = firing
+
+			alert := Alert{
+				Objective:     o.Name,
+				Description:   o.Description,
+				Severity:      rule.Severity,
+				Status:        AlertResolved,
+				Target:        o.Target,
+				BurnRate:      long,
+				ShortBurnRate: short,
+				Threshold:     rule.BurnRate,
+				LongWindow:    formatWindow(rule.LongWindow),
+				ShortWindow:   formatWindow(rule.ShortWindow),
+				At:            now.UTC(),
+			}
+			if firing {
+				alert.Status = AlertFiring
+			}
+			alert.ErrorBudgetRemaining = o.budgetRemaining(o.hours.sum(now, t.cfg.Window))
+			alerts = append(alerts, alert)
+		}
+		o.mu.Unlock()
+	}
+	return alerts
+}
+
+// Start evaluates the alert rules every evaluation interval until ctx is
+// done, passing the alerts that change to notify. Alerts that could not be
+// delivered are reported to onError and not retried.
+func (t *Tracker) Start(ctx context.Context, notify Notifier, onError func(error)) {
+	if len(t.objectives) == 0 {
+		return
+	}
+	go func() {
+		ticker := time.NewTicker(t.cfg.EvaluationInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				for _, alert := range t.Evaluate() {
+					if err := notify(ctx, alert); err != nil && onError != nil {
+						onError(err)
+					}
+				}
+			}
+		}
+	}()
+}
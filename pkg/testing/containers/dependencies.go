@@ -0,0 +1,206 @@
+// Package containers provides test container implementations for integration testing.
+package containers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+)
+
+// Credentials of the dependencies started by StartDependencies. They match
+// docker-compose.yml, so tests run the same against either.
+const (
+	dependencyUser          = "crm_admin"
+	postgresPassword        = "crm_secret_password"
+	mongoDBPassword         = "crm_secret_password"
+	redisPassword           = "crm_redis_password"
+	rabbitMQPassword        = "crm_rabbit_password"
+	rabbitMQVHost           = "crm_vhost"
+	externalDependenciesEnv = "TEST_EXTERNAL_DEPENDENCIES"
+)
+
+// Dependencies are the PostgreSQL, MongoDB, Redis and RabbitMQ servers an
+// integration test run works against.
+type Dependencies struct {
+	containers []*DockerContainer
+}
+
+// DependenciesConfig holds configuration for the dependencies.
+type DependenciesConfig struct {
+	PostgresImage string
+	MongoDBImage  string
+	RedisImage    string
+	RabbitMQImage string
+
+	// PostgresInitScript is run by PostgreSQL on its first start, typically
+	// scripts/init-postgres.sql creating the databases and schemas of the
+	// services. It must be an absolute path.
+	PostgresInitScript string
+}
+
+// DefaultDependenciesConfig returns the images docker-compose.yml runs.
+func DefaultDependenciesConfig() DependenciesConfig {
+	return DependenciesConfig{
+		PostgresImage: "postgres:16-alpine",
+		MongoDBImage:  "mongo:7.0",
+		RedisImage:    "redis:7-alpine",
+		RabbitMQImage: "rabbitmq:3.13-alpine",
+	}
+}
+
+// StartDependencies starts the dependencies in docker and points the TEST_*
+// environment variables read by NewPostgresContainer, NewMongoDBContainer,
+// NewRedisContainer and NewRabbitMQContainer at them.
+//
+// When TEST_EXTERNAL_DEPENDENCIES is set nothing is started and the
+// variables are left as they are, to run against docker compose or the
+// service containers of a CI job instead.
+func StartDependencies(ctx context.Context, cfg DependenciesConfig) (*Dependencies, error) {
+	deps := &Dependencies{}
+	if os.Getenv(externalDependenciesEnv) != "" {
+		return deps, nil
+	}
+	if !DockerAvailable(ctx) {
+		return nil, fmt.Errorf("docker is not available; set %s=1 to use running dependencies", externalDependenciesEnv)
+	}
+
+	defaults := DefaultDependenciesConfig()
+	if cfg.PostgresImage == "" {
+		cfg.PostgresImage = defaults.PostgresImage
+	}
+	if cfg.MongoDBImage == "" {
+		cfg.MongoDBImage = defaults.MongoDBImage
+	}
+	if cfg.RedisImage == "" {
+		cfg.RedisImage = defaults.RedisImage
+	}
+	if cfg.RabbitMQImage == "" {
+		cfg.RabbitMQImage = defaults.RabbitMQImage
+	}
+
+	postgres := DockerRunConfig{
+		Image: cfg.PostgresImage,
+		Env: map[string]string{
+			"POSTGRES_USER":     dependencyUser,
+			"POSTGRES_PASSWORD": postgresPassword,
+			"POSTGRES_DB":       "crm_main",
+		},
+		Ports: []string{"5432/tcp"},
+		// Postgres only listens on TCP once its init scripts are done
+		HealthCmd: "pg_isready -h 127.0.0.1 -U " + dependencyUser + " -d crm_main",
+	}
+	if cfg.PostgresInitScript != "" {
+		postgres.Volumes = []string{cfg.PostgresInitScript + ":/docker-entrypoint-initdb.d/init.sql:ro"}
+	}
+
+	specs := []struct {
+		run     DockerRunConfig
+		port    string
+		envHost string
+		envPort string
+		env     map[string]string
+	}{
+		{
+			run:     postgres,
+			port:    "5432/tcp",
+			envHost: "TEST_POSTGRES_HOST",
+			envPort: "TEST_POSTGRES_PORT",
+			env: map[string]string{
+				"TEST_POSTGRES_USER":     dependencyUser,
+				"TEST_POSTGRES_PASSWORD": postgresPassword,
+			},
+		},
+		{
+			run: DockerRunConfig{
+				Image: cfg.MongoDBImage,
+				Env: map[string]string{
+					"MONGO_INITDB_ROOT_USERNAME": dependencyUser,
+					"MONGO_INITDB_ROOT_PASSWORD": mongoDBPassword,
+				},
+				Ports: []string{"27017/tcp"},
+				// MongoDB binds to localhost only while it initializes
+				HealthCmd: `mongosh --quiet --host "$(hostname -i)" --eval "db.adminCommand('ping')"`,
+			},
+			port:    "27017/tcp",
+			envHost: "TEST_MONGODB_HOST",
+			envPort: "TEST_MONGODB_PORT",
+			env: map[string]string{
+				"TEST_MONGODB_USER":     dependencyUser,
+				"TEST_MONGODB_PASSWORD": mongoDBPassword,
+			},
+		},
+		{
+			run: DockerRunConfig{
+				Image:     cfg.RedisImage,
+				Ports:     []string{"6379/tcp"},
+				HealthCmd: "redis-cli -a " + redisPassword + " ping | grep PONG",
+				Cmd:       []string{"redis-server", "--requirepass", redisPassword},
+			},
+			port:    "6379/tcp",
+			envHost: "TEST_REDIS_HOST",
+			envPort: "TEST_REDIS_PORT",
+			env: map[string]string{
+				"TEST_REDIS_PASSWORD": redisPassword,
+			},
+		},
+		{
+			run: DockerRunConfig{
+				Image: cfg.RabbitMQImage,
+				Env: map[string]string{
+					"RABBITMQ_DEFAULT_USER":  dependencyUser,
+					"RABBITMQ_DEFAULT_PASS":  rabbitMQPassword,
+					"RABBITMQ_DEFAULT_VHOST": rabbitMQVHost,
+				},
+				Ports:     []string{"5672/tcp"},
+				HealthCmd: "rabbitmq-diagnostics -q check_port_connectivity",
+			},
+			port:    "5672/tcp",
+			envHost: "TEST_RABBITMQ_HOST",
+			envPort: "TEST_RABBITMQ_PORT",
+			env: map[string]string{
+				"TEST_RABBITMQ_USER":     dependencyUser,
+				"TEST_RABBITMQ_PASSWORD": rabbitMQPassword,
+				"TEST_RABBITMQ_VHOST":    rabbitMQVHost,
+			},
+		},
+	}
+
+	for _, spec := range specs {
+		container, err := RunDockerContainer(ctx, spec.run)
+		if err != nil {
+			_ = deps.Terminate(context.Background())
+			return nil, err
+		}
+		deps.containers = append(deps.containers, container)
+
+		host, port, err := container.Endpoint(ctx, spec.port)
+		if err != nil {
+			_ = deps.Terminate(context.Background())
+			return nil, err
+		}
+
+		spec.env[spec.envHost] = host
+		spec.env[spec.envPort] = port
+		for key, value := range spec.env {
+			if err := os.Setenv(key, value); err != nil {
+				_ = deps.Terminate(context.Background())
+				return nil, fmt.Errorf("failed to set %s: %w", key, err)
+			}
+		}
+	}
+
+	return deps, nil
+}
+
+// Terminate removes the containers started by StartDependencies.
+func (d *Dependencies) Terminate(ctx context.Context) error {
+	var errs []error
+	for _, container := range d.containers {
+		if err := container.Terminate(ctx); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	d.containers = nil
+	return errors.Join(errs...)
+}
@@ -0,0 +1,155 @@
+// Package containers provides test container implementations for integration testing.
+package containers
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+// DockerContainer is a container started through the docker CLI for the
+// duration of a test run.
+type DockerContainer struct {
+	ID    string
+	Name  string
+	Image string
+}
+
+// DockerRunConfig describes the container to start.
+type DockerRunConfig struct {
+	// Name of the container; docker picks one when empty.
+	Name  string
+	Image string
+	Env   map[string]string
+	// Ports are the container ports to publish on a random port of the
+	// loopback interface, e.g. "5432/tcp".
+	Ports []string
+	// Volumes are bind mounts in docker's host:container[:options] form.
+	Volumes []string
+	// HealthCmd is run inside the container to tell when it is ready.
+	HealthCmd string
+	// Cmd overrides the image's command.
+	Cmd []string
+}
+
+// DockerAvailable reports whether the docker CLI can reach a daemon.
+func DockerAvailable(ctx context.Context) bool {
+	_, err := docker(ctx, "version", "--format", "{{.Server.Version}}")
+	return err == nil
+}
+
+// RunDockerContainer starts a container and returns once its health command
+// succeeds, or right away when it has none. The container is removed on
+// Terminate and when it stops.
+func RunDockerContainer(ctx context.Context, cfg DockerRunConfig) (*DockerContainer, error) {
+	args := []string{"run", "--detach", "--rm", "--label", "crm.testing=integration"}
+	if cfg.Name != "" {
+		args = append(args, "--name", cfg.Name)
+	}
+	for key, value := range cfg.Env {
+		args = append(args, "--env", key+"="+value)
+	}
+	for _, port := range cfg.Ports {
+		args = append(args, "--publish", "127.0.0.1::"+port)
+	}
+	for _, volume := range cfg.Volumes {
+		args = append(args, "--volume", volume)
+	}
+	if cfg.HealthCmd != "" {
+		args = append(args,
+			"--health-cmd", cfg.HealthCmd,
+			"--health-interval", "1s",
+			"--health-timeout", "5s",
+			"--health-retries", "120",
+		)
+	}
+	args = append(args, cfg.Image)
+	args = append(args, cfg.Cmd...)
+
+	id, err := docker(ctx, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to start %s: %w", cfg.Image, err)
+	}
+
+	container := &DockerContainer{ID: id, Name: cfg.Name, Image: cfg.Image}
+	if cfg.HealthCmd != "" {
+		if err := container.waitHealthy(ctx); err != nil {
+			_ = container.Terminate(context.Background())
+			return nil, err
+		}
+	}
+
+	return container, nil
+}
+
+// waitHealthy waits for the container's health command to succeed.
+func (c *DockerContainer) waitHealthy(ctx context.Context) error {
+	ticker := time.NewTicker(500 * time.Millisecond)
+	defer ticker.Stop()
+
+	for {
+		status, err := docker(ctx, "inspect", "--format", "{{.State.Health.Status}}", c.ID)
+		if err != nil {
+			return fmt.Errorf("failed to inspect %s: %w", c.Image, err)
+		}
+		switch status {
+		case "healthy":
+			return nil
+		case "unhealthy":
+			return fmt.Errorf("%s is unhealthy:\n%s", c.Image, c.Logs(context.Background()))
+		}
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("timeout waiting for %s to be ready: %w", c.Image, ctx.Err())
+		case <-ticker.C:
+		}
+	}
+}
+
+// Endpoint returns the host and port the container port is published on.
+func (c *DockerContainer) Endpoint(ctx context.Context, port string) (string, string, error) {
+	out, err := docker(ctx, "port", c.ID, port)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to find the published port of %s: %w", port, err)
+	}
+
+	// docker lists one binding per line, IPv4 first
+	host, hostPort, err := net.SplitHostPort(strings.SplitN(out, "\n", 2)[0])
+	if err != nil {
+		return "", "", fmt.Errorf("unexpected port binding %q: %w", out, err)
+	}
+	return host, hostPort, nil
+}
+
+// Logs returns the container's output, for reporting why it failed.
+func (c *DockerContainer) Logs(ctx context.Context) string {
+	// Most images log to stderr
+	out, _ := exec.CommandContext(ctx, "docker", "logs", "--tail", "50", c.ID).CombinedOutput()
+	return strings.TrimSpace(string(out))
+}
+
+// Terminate stops and removes the container.
+func (c *DockerContainer) Terminate(ctx context.Context) error {
+	if _, err := docker(ctx, "rm", "--force", "--volumes", c.ID); err != nil {
+		return fmt.Errorf("failed to remove %s: %w", c.Image, err)
+	}
+	return nil
+}
+
+// docker runs the docker CLI and returns its trimmed output.
+func docker(ctx context.Context, args ...string) (string, error) {
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, "docker", args...)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("docker %s: %w: %s", args[0], err, strings.TrimSpace(stderr.String()))
+	}
+	return strings.TrimSpace(stdout.String()), nil
+}
@@ -4,7 +4,8 @@ package containers
 import (
 	"context"
 	"fmt"
-	"os"
+	"net/url"
+	"strings"
 	"sync"
 	"time"
 
@@ -118,8 +119,8 @@ func NewRabbitMQContainer(ctx context.Context, cfg RabbitMQContainerConfig) (*Ra
 
 // ConnectionURL returns the RabbitMQ connection URL.
 func (c *RabbitMQContainer) ConnectionURL() string {
-	return fmt.Sprintf("amqp://%s:%s@%s:%s%s",
-		c.User, c.Password, c.Host, c.Port, c.VHost)
+	return fmt.Sprintf("amqp://%s:%s@%s:%s/%s",
+		c.User, c.Password, c.Host, c.Port, url.PathEscape(strings.TrimPrefix(c.VHost, "/")))
 }
 
 // GetConnection returns the RabbitMQ connection.
@@ -0,0 +1,42 @@
+//go:build integration
+
+package integration
+
+import (
+	"context"
+	"testing"
+)
+
+func TestDependencies_Reachable(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("postgres", func(t *testing.T) {
+		var searchPath string
+		if err := postgresContainer.DB.GetContext(ctx, &searchPath, "SHOW search_path"); err != nil {
+			t.Fatalf("failed to query PostgreSQL: %v", err)
+		}
+		if searchPath != "sales, public" {
+			t.Errorf("search_path = %q, want the sales schema first", searchPath)
+		}
+	})
+
+	t.Run("mongodb", func(t *testing.T) {
+		if err := mongoContainer.Client.Ping(ctx, nil); err != nil {
+			t.Errorf("failed to ping MongoDB: %v", err)
+		}
+	})
+
+	t.Run("redis", func(t *testing.T) {
+		if err := redisContainer.Client.Ping(ctx).Err(); err != nil {
+			t.Errorf("failed to ping Redis: %v", err)
+		}
+	})
+
+	t.Run("rabbitmq", func(t *testing.T) {
+		ch, err := rabbitContainer.Conn.Channel()
+		if err != nil {
+			t.Fatalf("failed to open a RabbitMQ channel: %v", err)
+		}
+		ch.Close()
+	})
+}
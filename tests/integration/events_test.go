@@ -0,0 +1,94 @@
+//go:build integration
+
+package integration
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	amqp "github.com/rabbitmq/amqp091-go"
+
+	salescache "github.com/kilang-desa-murni/crm/internal/sales/infrastructure/cache"
+	"github.com/kilang-desa-murni/crm/internal/sales/infrastructure/messaging"
+	"github.com/kilang-desa-murni/crm/pkg/testing/helpers"
+)
+
+// subscribe binds a private queue to the sales events matching routingKey.
+func subscribe(t *testing.T, routingKey string) <-chan amqp.Delivery {
+	t.Helper()
+
+	ch, err := rabbitContainer.Conn.Channel()
+	if err != nil {
+		t.Fatalf("failed to open channel: %v", err)
+	}
+	t.Cleanup(func() { _ = ch.Close() })
+
+	queue, err := ch.QueueDeclare("", false, true, true, false, nil)
+	if err != nil {
+		t.Fatalf("failed to declare queue: %v", err)
+	}
+	if err := ch.QueueBind(queue.Name, routingKey, messaging.SalesEventsExchange, false, nil); err != nil {
+		t.Fatalf("failed to bind queue: %v", err)
+	}
+
+	deliveries, err := ch.Consume(queue.Name, "", true, true, false, false, nil)
+	if err != nil {
+		t.Fatalf("failed to consume: %v", err)
+	}
+	return deliveries
+}
+
+func TestEvents_LeadCreatedIsPublished(t *testing.T) {
+	deliveries := subscribe(t, "sales.lead.*")
+	user := newTestUser(t)
+
+	lead := createLead(t, user, "event@kilang-batik.test")
+
+	timeout := time.After(10 * time.Second)
+	for {
+		select {
+		case d := <-deliveries:
+			if d.Headers["aggregate_id"] != lead.ID {
+				continue
+			}
+			if d.RoutingKey != "sales.lead.created" {
+				t.Errorf("routing key = %q, want sales.lead.created", d.RoutingKey)
+			}
+			if d.Headers["tenant_id"] != user.TenantID.String() {
+				t.Errorf("tenant_id header = %v, want %s", d.Headers["tenant_id"], user.TenantID)
+			}
+			return
+		case <-timeout:
+			t.Fatal("the lead.created event was not published")
+		}
+	}
+}
+
+func TestEvents_LeadChangesInvalidateCache(t *testing.T) {
+	ctx := context.Background()
+	user := newTestUser(t)
+
+	// A cached lead list of the tenant and of another tenant
+	ownKey := salescache.TenantKey("lead", user.TenantID, "list")
+	otherKey := salescache.TenantKey("lead", uuid.New(), "list")
+	for _, key := range []string{ownKey, otherKey} {
+		if err := salesCache.Set(ctx, key, []byte(`[]`), time.Hour); err != nil {
+			t.Fatalf("failed to cache %s: %v", key, err)
+		}
+	}
+
+	// The event travels through RabbitMQ to the cache invalidation consumer
+	createLead(t, user, "cache@kilang-batik.test")
+
+	helpers.Eventually(t, func() bool {
+		exists, err := salesCache.Exists(ctx, ownKey)
+		return err == nil && !exists
+	}, 10*time.Second, 100*time.Millisecond, "the tenant's cached leads should be invalidated")
+
+	exists, err := salesCache.Exists(ctx, otherKey)
+	if err != nil || !exists {
+		t.Errorf("the cached leads of another tenant were invalidated (err = %v)", err)
+	}
+}
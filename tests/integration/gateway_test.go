@@ -0,0 +1,83 @@
+//go:build integration
+
+package integration
+
+import (
+	"net/http"
+	"os"
+	"strings"
+	"testing"
+)
+
+// gatewayURL returns the URL of the API gateway of a running stack, as
+// started by `make test-integration-stack`. Gateway tests are skipped
+// without one.
+func gatewayURL(t *testing.T) string {
+	t.Helper()
+
+	url := os.Getenv("TEST_GATEWAY_URL")
+	if url == "" {
+		t.Skip("TEST_GATEWAY_URL is not set")
+	}
+	return strings.TrimSuffix(url, "/")
+}
+
+func TestGateway_Health(t *testing.T) {
+	gateway := gatewayURL(t)
+
+	resp, err := httpClient.Get(gateway + "/health")
+	if err != nil {
+		t.Fatalf("GET /health failed: %v", err)
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("gateway health status = %d, want %d", resp.StatusCode, http.StatusOK)
+	}
+}
+
+func TestGateway_RequiresAuthentication(t *testing.T) {
+	gateway := gatewayURL(t)
+
+	for _, path := range []string{"/api/v1/leads/", "/api/v1/customers/", "/api/v1/notifications/"} {
+		if status := doRequest(t, http.MethodGet, gateway+path, "", nil, nil); status != http.StatusUnauthorized {
+			t.Errorf("GET %s without token status = %d, want %d", path, status, http.StatusUnauthorized)
+		}
+	}
+}
+
+func TestGateway_DoesNotRouteInternalEndpoints(t *testing.T) {
+	gateway := gatewayURL(t)
+	user := newTestUser(t)
+
+	path := "/internal/tenants/" + user.TenantID.String() + "/demo-data?seeded_by=" + user.UserID.String()
+	status := doRequest(t, http.MethodPost, gateway+path, "", nil, nil)
+	if status < http.StatusBadRequest {
+		t.Errorf("POST %s through the gateway status = %d, want it refused", path, status)
+	}
+	if got := countRows(t, "sales.leads", user); got != 0 {
+		t.Errorf("the gateway seeded %d demo leads", got)
+	}
+}
+
+func TestGateway_RoutesToServices(t *testing.T) {
+	gateway := gatewayURL(t)
+	token := os.Getenv("TEST_GATEWAY_TOKEN")
+	if token == "" {
+		t.Skip("TEST_GATEWAY_TOKEN is not set")
+	}
+
+	// One list endpoint per backend service
+	paths := []string{
+		"/api/v1/users/",
+		"/api/v1/customers/",
+		"/api/v1/leads/",
+		"/api/v1/pipelines/",
+		"/api/v1/notifications/",
+	}
+	for _, path := range paths {
+		if status := doRequest(t, http.MethodGet, gateway+path, token, nil, nil); status != http.StatusOK {
+			t.Errorf("GET %s through the gateway status = %d, want %d", path, status, http.StatusOK)
+		}
+	}
+}
@@ -0,0 +1,330 @@
+//go:build integration
+
+// Package integration runs the services against real PostgreSQL, MongoDB,
+// Redis and RabbitMQ servers started in docker. Run it with
+// `make test-integration`.
+package integration
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+
+	"github.com/kilang-desa-murni/crm/internal/sales/application/usecase"
+	salescache "github.com/kilang-desa-murni/crm/internal/sales/infrastructure/cache"
+	"github.com/kilang-desa-murni/crm/internal/sales/infrastructure/messaging"
+	"github.com/kilang-desa-murni/crm/internal/sales/infrastructure/persistence/postgres"
+	saleshttp "github.com/kilang-desa-murni/crm/internal/sales/interfaces/http"
+	"github.com/kilang-desa-murni/crm/pkg/logger"
+	"github.com/kilang-desa-murni/crm/pkg/testing/containers"
+)
+
+// jwtSecret signs the tokens of the in-process services.
+const jwtSecret = "integration-test-secret"
+
+var (
+	// Dependencies
+	dependencies      *containers.Dependencies
+	postgresContainer *containers.PostgresContainer
+	mongoContainer    *containers.MongoDBContainer
+	redisContainer    *containers.RedisContainer
+	rabbitContainer   *containers.RabbitMQContainer
+
+	// Services
+	salesServer          *httptest.Server
+	salesPublisher       *messaging.RabbitMQPublisher
+	salesCache           *salescache.RedisCacheService
+	invalidationConsumer *messaging.RabbitMQConsumer
+
+	httpClient = &http.Client{Timeout: 30 * time.Second}
+)
+
+// TestMain starts the dependencies and the services under test.
+func TestMain(m *testing.M) {
+	flag.Parse()
+	if testing.Short() {
+		os.Exit(0)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Minute)
+	err := setup(ctx)
+	cancel()
+	if err != nil {
+		fmt.Printf("Failed to set up integration test infrastructure: %v\n", err)
+		teardown()
+		os.Exit(1)
+	}
+
+	code := m.Run()
+	teardown()
+	os.Exit(code)
+}
+
+func setup(ctx context.Context) error {
+	initScript, err := filepath.Abs(filepath.Join("..", "..", "scripts", "init-postgres.sql"))
+	if err != nil {
+		return err
+	}
+
+	dependencies, err = containers.StartDependencies(ctx, containers.DependenciesConfig{
+		PostgresInitScript: initScript,
+	})
+	if err != nil {
+		return err
+	}
+
+	// The init script creates a database per service, with its schema on the
+	// search path
+	postgresContainer, err = containers.NewPostgresContainer(ctx, containers.PostgresContainerConfig{
+		Database: "crm_sales",
+		User:     "crm_admin",
+		Password: "crm_secret_password",
+	})
+	if err != nil {
+		return err
+	}
+	if err := postgresContainer.RunMigrations(ctx, filepath.Join("..", "..", "migrations", "sales")); err != nil {
+		return err
+	}
+
+	mongoContainer, err = containers.NewMongoDBContainer(ctx, containers.DefaultMongoDBConfig())
+	if err != nil {
+		return err
+	}
+
+	redisContainer, err = containers.NewRedisContainer(ctx, containers.DefaultRedisConfig())
+	if err != nil {
+		return err
+	}
+
+	rabbitContainer, err = containers.NewRabbitMQContainer(ctx, containers.DefaultRabbitMQConfig())
+	if err != nil {
+		return err
+	}
+
+	return setupSalesService()
+}
+
+// setupSalesService runs the sales service's router on its real repositories,
+// publisher, cache and cache invalidation consumer.
+func setupSalesService() error {
+	log := logger.New(logger.Config{Level: "error"})
+	db := postgresContainer.DB
+
+	rabbitConfig := messaging.DefaultRabbitMQConfig()
+	rabbitConfig.URL = rabbitContainer.ConnectionURL()
+
+	var err error
+	salesPublisher, err = messaging.NewRabbitMQPublisher(rabbitConfig)
+	if err != nil {
+		return err
+	}
+
+	salesCache = salescache.NewRedisCacheService(redisContainer.Client, salescache.DefaultRedisCacheConfig())
+	invalidator := salescache.NewCacheInvalidator(salesCache, log)
+
+	invalidationConsumer, err = messaging.NewRabbitMQConsumer(messaging.RabbitMQConsumerConfig{
+		URL:            rabbitConfig.URL,
+		Queue:          messaging.CacheInvalidationQueue,
+		Bindings:       invalidator.Bindings(),
+		PrefetchCount:  rabbitConfig.PrefetchCount,
+		ReconnectDelay: time.Second,
+	})
+	if err != nil {
+		return err
+	}
+	if err := invalidationConsumer.Consume(context.Background(), invalidator.Handle); err != nil {
+		return err
+	}
+
+	publisher := messaging.NewApplicationPublisher(salesPublisher)
+	leadRepo := postgres.NewLeadRepository(db)
+	opportunityRepo := postgres.NewOpportunityRepository(db)
+	pipelineRepo := postgres.NewPipelineRepository(db)
+
+	leadConversion := usecase.NewLeadConversionOrchestrator(
+		postgres.NewUnitOfWork(db),
+		leadRepo,
+		opportunityRepo,
+		pipelineRepo,
+		publisher,
+		nil, // customerService
+	)
+
+	handler := saleshttp.NewHandler(saleshttp.HandlerDependencies{
+		LeadUseCase: usecase.NewLeadUseCase(
+			leadRepo,
+			opportunityRepo,
+			pipelineRepo,
+			publisher,
+			nil, // customerService
+			nil, // userService
+			salesCache,
+			nil, // searchService
+			nil, // idGenerator
+			leadConversion,
+			nil, // customerMatcher
+		),
+		PipelineUseCase: usecase.NewPipelineUseCase(
+			pipelineRepo,
+			opportunityRepo,
+			publisher,
+			salesCache,
+			nil, // idGenerator
+			nil, // currencyConverter
+			nil, // revenueSplitRepo
+		),
+		DemoDataUseCase: usecase.NewDemoDataUseCase(
+			postgres.NewDemoDataRepository(db),
+			pipelineRepo,
+			leadRepo,
+			opportunityRepo,
+		),
+		MiddlewareConfig: saleshttp.MiddlewareConfig{
+			JWTSecret:         jwtSecret,
+			AllowedOrigins:    []string{"*"},
+			RateLimitRequests: 1000,
+			RateLimitWindow:   time.Minute,
+		},
+	})
+
+	salesServer = httptest.NewServer(saleshttp.NewRouter(handler))
+	return nil
+}
+
+func teardown() {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	if salesServer != nil {
+		salesServer.Close()
+	}
+	if invalidationConsumer != nil {
+		_ = invalidationConsumer.Shutdown(ctx)
+	}
+	if salesPublisher != nil {
+		_ = salesPublisher.Close()
+	}
+	if postgresContainer != nil {
+		_ = postgresContainer.Close()
+	}
+	if mongoContainer != nil {
+		_ = mongoContainer.Close(ctx)
+	}
+	if redisContainer != nil {
+		_ = redisContainer.Close()
+	}
+	if rabbitContainer != nil {
+		_ = rabbitContainer.Close()
+	}
+	if dependencies != nil {
+		if err := dependencies.Terminate(ctx); err != nil {
+			fmt.Printf("Failed to remove test containers: %v\n", err)
+		}
+	}
+}
+
+// ============================================================================
+// Helpers
+// ============================================================================
+
+// testUser is a user of a fresh tenant.
+type testUser struct {
+	TenantID uuid.UUID
+	UserID   uuid.UUID
+	Token    string
+}
+
+// newTestUser returns a sales user of a new tenant, with a token the
+// in-process services accept.
+func newTestUser(t *testing.T) testUser {
+	t.Helper()
+
+	user := testUser{TenantID: uuid.New(), UserID: uuid.New()}
+	claims := saleshttp.JWTClaims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			Subject:   user.UserID.String(),
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour)),
+			IssuedAt:  jwt.NewNumericDate(time.Now()),
+		},
+		TenantID: user.TenantID,
+		UserID:   user.UserID,
+		Email:    "sales@kilang-batik.test",
+		Roles:    []string{"admin"},
+	}
+
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(jwtSecret))
+	if err != nil {
+		t.Fatalf("failed to sign token: %v", err)
+	}
+	user.Token = token
+	return user
+}
+
+// apiResponse is the envelope of the services' responses.
+type apiResponse struct {
+	Success bool            `json:"success"`
+	Data    json.RawMessage `json:"data"`
+	Error   json.RawMessage `json:"error"`
+}
+
+// doRequest sends a request and decodes the response envelope into data,
+// when given.
+func doRequest(t *testing.T, method, url, token string, body, data interface{}) int {
+	t.Helper()
+
+	var reader io.Reader
+	if body != nil {
+		payload, err := json.Marshal(body)
+		if err != nil {
+			t.Fatalf("failed to encode request: %v", err)
+		}
+		reader = bytes.NewReader(payload)
+	}
+
+	req, err := http.NewRequest(method, url, reader)
+	if err != nil {
+		t.Fatalf("failed to create request: %v", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		t.Fatalf("%s %s failed: %v", method, url, err)
+	}
+	defer resp.Body.Close()
+
+	raw, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatalf("failed to read response: %v", err)
+	}
+
+	if data != nil && len(raw) > 0 {
+		var envelope apiResponse
+		if err := json.Unmarshal(raw, &envelope); err != nil {
+			t.Fatalf("failed to decode response %s: %v", raw, err)
+		}
+		if len(envelope.Data) > 0 {
+			if err := json.Unmarshal(envelope.Data, data); err != nil {
+				t.Fatalf("failed to decode response data %s: %v", envelope.Data, err)
+			}
+		}
+	}
+
+	return resp.StatusCode
+}
@@ -0,0 +1,115 @@
+//go:build integration
+
+package integration
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/kilang-desa-murni/crm/internal/sales/application/dto"
+	"github.com/kilang-desa-murni/crm/pkg/demodata"
+)
+
+// createLead creates a lead through the sales API and returns it.
+func createLead(t *testing.T, user testUser, email string) dto.LeadResponse {
+	t.Helper()
+
+	request := map[string]interface{}{
+		"first_name": "Siti",
+		"last_name":  "Rahman",
+		"email":      email,
+		"company":    "Boutique Anggun",
+		"source":     "website",
+	}
+
+	var lead dto.LeadResponse
+	status := doRequest(t, http.MethodPost, salesServer.URL+"/api/v1/sales/leads", user.Token, request, &lead)
+	if status != http.StatusCreated {
+		t.Fatalf("create lead status = %d, want %d", status, http.StatusCreated)
+	}
+	if lead.ID == "" {
+		t.Fatal("created lead has no ID")
+	}
+	return lead
+}
+
+func TestSales_LeadLifecycle(t *testing.T) {
+	user := newTestUser(t)
+	lead := createLead(t, user, "siti@boutique-anggun.test")
+
+	var found dto.LeadResponse
+	status := doRequest(t, http.MethodGet, salesServer.URL+"/api/v1/sales/leads/"+lead.ID, user.Token, nil, &found)
+	if status != http.StatusOK {
+		t.Fatalf("get lead status = %d, want %d", status, http.StatusOK)
+	}
+	if found.Email != "siti@boutique-anggun.test" || found.TenantID != user.TenantID.String() {
+		t.Errorf("get lead = %+v, want the created lead", found)
+	}
+
+	t.Run("other tenants do not see the lead", func(t *testing.T) {
+		other := newTestUser(t)
+		status := doRequest(t, http.MethodGet, salesServer.URL+"/api/v1/sales/leads/"+lead.ID, other.Token, nil, nil)
+		if status != http.StatusNotFound {
+			t.Errorf("get lead of another tenant status = %d, want %d", status, http.StatusNotFound)
+		}
+	})
+
+	t.Run("requests without a token are refused", func(t *testing.T) {
+		status := doRequest(t, http.MethodGet, salesServer.URL+"/api/v1/sales/leads/"+lead.ID, "", nil, nil)
+		if status != http.StatusUnauthorized {
+			t.Errorf("get lead without token status = %d, want %d", status, http.StatusUnauthorized)
+		}
+	})
+}
+
+func TestSales_DemoData(t *testing.T) {
+	user := newTestUser(t)
+	endpoint := salesServer.URL + "/internal/tenants/" + user.TenantID.String() + "/demo-data"
+
+	var seeded dto.DemoDataResponse
+	if status := doRequest(t, http.MethodPost, endpoint+"?seeded_by="+user.UserID.String(), "", nil, &seeded); status != http.StatusOK {
+		t.Fatalf("seed status = %d, want %d", status, http.StatusOK)
+	}
+	if seeded.Created == 0 {
+		t.Fatal("seeding created no records")
+	}
+	if got := countRows(t, "sales.leads", user); got != int64(len(demodata.For(user.TenantID).Leads)) {
+		t.Errorf("tenant has %d leads after seeding, want %d", got, len(demodata.For(user.TenantID).Leads))
+	}
+
+	var reseeded dto.DemoDataResponse
+	if status := doRequest(t, http.MethodPost, endpoint+"?seeded_by="+user.UserID.String(), "", nil, &reseeded); status != http.StatusOK {
+		t.Fatalf("second seed status = %d, want %d", status, http.StatusOK)
+	}
+	if reseeded.Created != 0 || reseeded.Existing != seeded.Created {
+		t.Errorf("second seed = %+v, want every record to exist", reseeded)
+	}
+
+	// The tenant's own records survive the purge
+	createLead(t, user, "own@kilang-batik.test")
+
+	var purged dto.DemoDataResponse
+	if status := doRequest(t, http.MethodDelete, endpoint, "", nil, &purged); status != http.StatusOK {
+		t.Fatalf("purge status = %d, want %d", status, http.StatusOK)
+	}
+	if purged.Deleted != seeded.Created {
+		t.Errorf("purge deleted %d records, want %d", purged.Deleted, seeded.Created)
+	}
+	if got := countRows(t, "sales.leads", user); got != 1 {
+		t.Errorf("tenant has %d leads after purging, want its own lead only", got)
+	}
+}
+
+// countRows counts the rows of the user's tenant in a table.
+func countRows(t *testing.T, table string, user testUser) int64 {
+	t.Helper()
+
+	var count int64
+	err := postgresContainer.DB.GetContext(context.Background(), &count,
+		"SELECT COUNT(*) FROM "+table+" WHERE tenant_id = $1 AND deleted_at IS NULL", user.TenantID)
+	if err != nil {
+		t.Fatalf("failed to count %s: %v", table, err)
+	}
+	return count
+}